@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -10,13 +11,28 @@ import (
 	"time"
 
 	"github.com/assimoes/beautix/configs"
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
 	"github.com/assimoes/beautix/internal/infrastructure/auth"
 	"github.com/assimoes/beautix/internal/infrastructure/database"
+	"github.com/assimoes/beautix/internal/infrastructure/events"
+	"github.com/assimoes/beautix/internal/infrastructure/health"
+	"github.com/assimoes/beautix/internal/infrastructure/jobs"
+	"github.com/assimoes/beautix/internal/infrastructure/lifecycle"
+	"github.com/assimoes/beautix/internal/infrastructure/notification"
+	"github.com/assimoes/beautix/internal/infrastructure/outbound"
+	"github.com/assimoes/beautix/internal/infrastructure/residency"
+	"github.com/assimoes/beautix/internal/infrastructure/resilience"
+	"github.com/assimoes/beautix/internal/infrastructure/storage"
+	"github.com/assimoes/beautix/internal/infrastructure/stripe"
+	"github.com/assimoes/beautix/internal/infrastructure/telemetry"
+	"github.com/assimoes/beautix/internal/infrastructure/webhook"
 	"github.com/assimoes/beautix/internal/repository"
 	"github.com/assimoes/beautix/internal/service"
+	"github.com/assimoes/beautix/migrations"
 	"github.com/assimoes/beautix/pkg/graph"
+	"github.com/assimoes/beautix/pkg/graph/dataloader"
 	"github.com/go-playground/validator/v10"
-	"github.com/joho/godotenv"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
@@ -28,19 +44,29 @@ var (
 	CommitHash = "unknown"
 )
 
+// publicRequestsPerMinute is how many requests an IP address can make to the public booking
+// API each minute
+const publicRequestsPerMinute = 60
+
 func main() {
 	// Configure logging
 	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
 	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
 
-	// Load environment variables
-	if err := godotenv.Load(); err != nil {
-		log.Warn().Err(err).Msg("Error loading .env file, using environment variables")
+	// Load environment variables, with those of the environment's .env file taking precedence
+	if err := configs.LoadEnvFiles(); err != nil {
+		log.Fatal().Err(err).Msg("Failed to load .env files")
 	}
 
-	// Load configuration
+	// Load configuration, listing every invalid setting before giving up
 	config, err := configs.LoadConfig()
 	if err != nil {
+		var invalid *configs.ValidationError
+		if errors.As(err, &invalid) {
+			for _, problem := range invalid.Problems {
+				log.Error().Str("key", problem.Key).Msg(problem.Message)
+			}
+		}
 		log.Fatal().Err(err).Msg("Failed to load configuration")
 	}
 
@@ -58,6 +84,12 @@ func main() {
 		Str("environment", config.Environment).
 		Msg("Starting BeautyBiz API")
 
+	// Initialize tracing; spans are only exported when an OTLP collector is configured
+	shutdownTelemetry, err := telemetry.Setup(context.Background(), config.Telemetry, Version)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize telemetry")
+	}
+
 	// Initialize database
 	if err := database.InitDB(config); err != nil {
 		log.Fatal().Err(err).Msg("Failed to initialize database")
@@ -69,45 +101,439 @@ func main() {
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to get database instance")
 	}
+	if err := db.DB.Use(telemetry.NewGORMPlugin()); err != nil {
+		log.Fatal().Err(err).Msg("Failed to instrument database")
+	}
+	// Export the statistics of the connection pool, whose waits show it is too small for the load
+	if sqlDB, err := db.DB.DB(); err != nil {
+		log.Fatal().Err(err).Msg("Failed to get SQL DB")
+	} else if err := telemetry.RegisterDBStats(sqlDB, config.Database.DBName); err != nil {
+		log.Fatal().Err(err).Msg("Failed to export database pool statistics")
+	}
+	// New records get time-ordered UUIDv7 IDs, keeping the primary key indexes of the tables
+	// appended to the most compact
+	if err := db.DB.Use(repository.NewIDGenerator()); err != nil {
+		log.Fatal().Err(err).Msg("Failed to register the ID generator")
+	}
+	// Monetary amounts move to integer minor units: once backfilled and verified, reads switch
+	// to the minor units while both columns are still written
+	switch phase := domain.MoneyMigrationPhase(config.Database.MoneyMigrationPhase); {
+	case !phase.IsValid():
+		log.Fatal().Str("phase", string(phase)).Msg("Unknown money migration phase")
+	case phase == domain.MoneyPhaseDualRead:
+		if err := db.DB.Use(repository.NewMoneyReadShim()); err != nil {
+			log.Fatal().Err(err).Msg("Failed to read money from minor units")
+		}
+	}
 
 	// Initialize repositories
 	userRepo := repository.NewUserRepository(db.DB)
 	businessRepo := repository.NewBusinessRepository(db.DB)
 	staffRepo := repository.NewStaffRepository(db.DB)
+	serviceRepo := repository.NewBaseRepository[domain.Service](db.DB, repository.WithTenantScope())
+	appointmentRepo := repository.NewAppointmentRepository(db.DB)
+	serviceCompletionRepo := repository.NewServiceCompletionRepository(db.DB)
+	serviceRecordRepo := repository.NewServiceRecordRepository(db.DB)
+	serviceRecordTemplateRepo := repository.NewServiceRecordTemplateRepository(db.DB)
+	commissionPlanRepo := repository.NewStaffCommissionPlanRepository(db.DB)
+	integrationUsageRepo := repository.NewIntegrationUsageRepository(db.DB)
+	domainEventRepo := repository.NewDomainEventRepository(db.DB)
+	jobRepo := repository.NewJobRepository(db.DB)
+	unitOfWork := repository.NewUnitOfWork(db.DB)
+	auditLogRepo := repository.NewAuditLogRepository(db.DB)
+	calendarRepo := repository.NewCalendarRepository(db.DB)
+	clientPortalRepo := repository.NewClientPortalRepository(db.DB)
+	invoiceRepo := repository.NewInvoiceRepository(db.DB)
+	clientRepo := repository.NewBaseRepository[domain.Client](db.DB, repository.WithTenantScope())
+	messageTemplateRepo := repository.NewMessageTemplateRepository(db.DB)
+	confirmationRepo := repository.NewAppointmentConfirmationRepository(db.DB)
+	frontDeskTaskRepo := repository.NewFrontDeskTaskRepository(db.DB)
+	blocklistRepo := repository.NewBlocklistRepository(db.DB)
+	bookingAttemptRepo := repository.NewBookingAttemptRepository(db.DB)
+	businessSettingsRepo := repository.NewBusinessSettingsRepository(db.DB)
+	staffShiftRepo := repository.NewStaffShiftRepository(db.DB)
+	timeEntryRepo := repository.NewTimeEntryRepository(db.DB)
+	staffPerformanceRepo := repository.NewStaffPerformanceRepository(db.DB)
+	reportExportRepo := repository.NewReportExportRepository(db.DB)
+	imageUploadRepo := repository.NewImageUploadRepository(db.DB)
+	clientAttachmentRepo := repository.NewClientAttachmentRepository(db.DB)
+	capacityAlertRepo := repository.NewCapacityAlertRepository(db.DB)
+	priceChangeRepo := repository.NewPriceChangeRepository(db.DB)
+	serviceBundleRepo := repository.NewServiceBundleRepository(db.DB)
+	clientGuardianRepo := repository.NewClientGuardianRepository(db.DB)
+	broadcastRepo := repository.NewBroadcastRepository(db.DB)
+	paymentRepo := repository.NewPaymentRepository(db.DB)
+	giftCardRepo := repository.NewGiftCardRepository(db.DB)
+	loyaltyRepo := repository.NewLoyaltyRepository(db.DB)
+	staffPageRepo := repository.NewStaffBookingPageRepository(db.DB)
+	campaignRepo := repository.NewBaseRepository[domain.Campaign](db.DB, repository.WithTenantScope())
+	campaignClientRepo := repository.NewCampaignClientRepository(db.DB)
+	membershipPlanRepo := repository.NewBaseRepository[domain.MembershipPlan](db.DB, repository.WithTenantScope())
+	membershipRepo := repository.NewMembershipRepository(db.DB)
+	announcementRepo := repository.NewAnnouncementRepository(db.DB)
+	webhookRepo := repository.NewWebhookRepository(db.DB)
+	demoDataRepo := repository.NewDemoDataRepository(db.DB)
+	savedViewRepo := repository.NewSavedViewRepository(db.DB)
+	businessImportRepo := repository.NewBusinessImportRepository(db.DB)
+	watchdogRepo := repository.NewWatchdogRepository(db.DB)
+	notificationQueueRepo := repository.NewNotificationQueueRepository(db.DB)
+	clientImportRepo := repository.NewClientImportRepository(db.DB)
+	clientDataRepo := repository.NewClientDataRepository(db.DB)
+	clientMergeRepo := repository.NewClientMergeRepository(db.DB)
+	subscriptionRepo := repository.NewSubscriptionRepository(db.DB)
+	trialRepo := repository.NewTrialRepository(db.DB)
+	businessHoursRepo := repository.NewBusinessHoursRepository(db.DB)
+	businessLocationRepo := repository.NewBusinessLocationRepository(db.DB)
+	resourceRepo := repository.NewResourceRepository(db.DB)
+	outboundRequestRepo := repository.NewOutboundRequestRepository(db.DB)
+	idempotencyKeyRepo := repository.NewIdempotencyKeyRepository(db.DB)
+	anonymousFeedbackRepo := repository.NewAnonymousFeedbackRepository(db.DB)
+	userSessionRepo := repository.NewUserSessionRepository(db.DB)
+	businessCloneRepo := repository.NewBusinessCloneRepository(db.DB)
+	searchRepo := repository.NewSearchRepository(db.DB)
+	clientLeaderboardRepo := repository.NewClientLeaderboardRepository(db.DB)
+	appointmentQuoteRepo := repository.NewAppointmentQuoteRepository(db.DB)
+	reviewRepo := repository.NewReviewRepository(db.DB)
+	clientTimelineRepo := repository.NewClientTimelineRepository(db.DB)
+
+	// External providers are called through guards that report their health, and every call
+	// is audited
+	providers := resilience.NewRegistry()
+	outboundRecorder := outbound.NewRecorder(outboundRequestRepo)
+
+	// Destinations outside the database are registered with their region, so that data of EU
+	// businesses is only sent to EU destinations
+	residencyPolicy := residency.NewPolicy()
+	storageBuckets := residency.NewBuckets(residencyPolicy, map[domain.DataRegion]string{
+		domain.DataRegionEU: config.Residency.StorageBucketEU,
+		domain.DataRegionUS: config.Residency.StorageBucketUS,
+	})
+	// Each storage bucket is signed for in the region it lives in
+	bucketRegions := map[string]string{}
+	if config.Residency.StorageBucketEU != "" {
+		bucketRegions[config.Residency.StorageBucketEU] = config.Storage.RegionEU
+	}
+	if config.Residency.StorageBucketUS != "" {
+		bucketRegions[config.Residency.StorageBucketUS] = config.Storage.RegionUS
+	}
+	objectStore, err := storage.NewStore(config.Storage, bucketRegions, providers.Guard(storage.ProviderName, storage.Policy), outboundRecorder)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize object storage")
+	}
+	if config.Residency.WarehouseName != "" {
+		residencyPolicy.Register(domain.DataDestination{
+			Name:   config.Residency.WarehouseName,
+			Kind:   domain.DataDestinationWarehouse,
+			Region: domain.DataRegion(config.Residency.WarehouseRegion),
+		})
+	}
 
 	// Initialize services
 	validator := validator.New()
-	clerkClient := auth.NewClerkClient()
+	dto.RegisterOptionalTypes(validator)
+	clerkClient := auth.NewClerkClient(config.Auth, providers.Guard(auth.ProviderName, auth.Policy))
 	authService := service.NewAuthService(userRepo, clerkClient, db.DB)
 	userService := service.NewUserService(userRepo, businessRepo, staffRepo, validator)
+	serviceRecordService := service.NewServiceRecordService(serviceRecordRepo, serviceRecordTemplateRepo, serviceCompletionRepo, serviceRepo, appointmentRepo,
+		clientRepo, staffRepo, validator)
+	commissionService := service.NewCommissionService(commissionPlanRepo, staffRepo, businessRepo, unitOfWork, validator)
+	integrationUsageService := service.NewIntegrationUsageService(integrationUsageRepo, staffRepo)
+	// Events are recorded in the outbox and published by the relay
+	eventRelay := events.NewRelay(domainEventRepo)
+	eventService := service.NewEventService(domainEventRepo, events.NewOutbox(domainEventRepo), eventRelay, validator)
+	calendarService := service.NewCalendarService(calendarRepo, staffRepo)
+	clientPortalService := service.NewClientPortalService(clientPortalRepo, invoiceRepo)
+	messageTemplateService := service.NewMessageTemplateService(messageTemplateRepo, businessRepo, clientRepo, calendarRepo, validator)
+	notificationRouter, err := notification.NewSender(config.Notification, providers, outboundRecorder)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to configure notification providers")
+	}
+	// Messages are queued while their provider is unavailable, and text messages are counted
+	// against the SMS credits of the business's plan before that
+	entitlementService := service.NewEntitlementService(subscriptionRepo, businessRepo)
+	messageQueue := notification.NewQueueingSender(notificationRouter, notificationQueueRepo)
+	messageSender := notification.NewMeteredSender(messageQueue, entitlementService)
+	confirmationService := service.NewAppointmentConfirmationService(confirmationRepo, frontDeskTaskRepo, appointmentRepo, businessRepo, clientRepo,
+		calendarRepo, messageTemplateRepo, eventService, messageSender, config.App.PublicURL)
+	bookingGuardService := service.NewBookingGuardService(blocklistRepo, bookingAttemptRepo, clientRepo, nil, validator)
+	appointmentService := service.NewAppointmentService(appointmentRepo, businessRepo, staffRepo, clientRepo, serviceBundleRepo, appointmentQuoteRepo,
+		businessSettingsRepo, eventService, validator)
+	capacityService := service.NewCapacityService(calendarRepo, businessSettingsRepo, staffRepo, businessRepo, capacityAlertRepo, messageSender)
+	priceChangeService := service.NewPriceChangeService(priceChangeRepo, validator)
+	serviceBundleService := service.NewServiceBundleService(serviceBundleRepo, validator)
+	clientGuardianService := service.NewClientGuardianService(clientGuardianRepo, clientRepo, validator)
+	broadcastService := service.NewBroadcastService(broadcastRepo, appointmentRepo, businessRepo, calendarRepo, appointmentService,
+		messageSender, validator, config.App.PublicURL)
+	paymentGateway := stripe.NewGateway(config.Stripe, providers.Guard(stripe.ProviderName, stripe.Policy), outboundRecorder)
+	paymentService := service.NewPaymentService(paymentRepo, appointmentRepo, serviceCompletionRepo, businessRepo, giftCardRepo,
+		businessSettingsRepo, clientRepo, staffRepo, unitOfWork, eventService, paymentGateway, validator)
+	subscriptionService := service.NewSubscriptionService(subscriptionRepo, businessRepo, staffRepo, entitlementService,
+		stripe.NewBilling(paymentGateway), map[domain.SubscriptionTier]string{
+			domain.TierPro:     config.Stripe.ProPriceID,
+			domain.TierPremium: config.Stripe.PremiumPriceID,
+		}, config.Stripe.WebhookSecret, validator)
+	trialService := service.NewTrialService(trialRepo, businessRepo, subscriptionRepo, staffRepo, messageSender)
+	clientService := service.NewClientService(clientImportRepo, clientDataRepo, clientMergeRepo, clientRepo, businessRepo, staffRepo, auditLogRepo, eventService, validator)
+	outboundRequestService := service.NewOutboundRequestService(outboundRequestRepo, validator)
+	idempotencyService := service.NewIdempotencyService(idempotencyKeyRepo)
+	dataResidencyService := service.NewDataResidencyService(businessRepo, residencyPolicy, storageBuckets, validator)
+	anonymousFeedbackService := service.NewAnonymousFeedbackService(anonymousFeedbackRepo, appointmentRepo, businessRepo, staffRepo,
+		validator, config.App.PublicURL)
+	availabilityService := service.NewAvailabilityService(businessRepo, businessSettingsRepo, serviceRepo, staffRepo, appointmentRepo, validator)
+	publicBookingService := service.NewPublicBookingService(businessRepo, serviceRepo, staffRepo, userRepo, clientRepo,
+		appointmentRepo, staffPageRepo, availabilityService, bookingGuardService, eventService, validator)
+	scheduleChangeService := service.NewScheduleChangeService(businessRepo, businessSettingsRepo, businessHoursRepo, serviceRepo, staffRepo, userRepo,
+		appointmentRepo, messageSender, validator)
+	businessHoursService := service.NewBusinessHoursService(businessHoursRepo, businessRepo, businessLocationRepo, staffRepo, validator)
+	resourceService := service.NewResourceService(resourceRepo, businessLocationRepo, serviceRepo, staffRepo, validator)
+	adminService := service.NewAdminService(businessRepo, validator)
+	giftCardService := service.NewGiftCardService(giftCardRepo, businessRepo, clientRepo, staffRepo, validator)
+	staffBookingPageService := service.NewStaffBookingPageService(staffPageRepo, staffRepo, userRepo, serviceRepo, validator)
+	businessService := service.NewBusinessService(businessRepo, businessSettingsRepo, staffRepo, businessCloneRepo, unitOfWork, validator)
+	referralService := service.NewReferralService(loyaltyRepo, clientRepo, appointmentRepo, validator)
+	campaignService := service.NewCampaignService(campaignRepo, campaignClientRepo, staffRepo, entitlementService, validator)
+	membershipService := service.NewMembershipService(membershipPlanRepo, membershipRepo, clientRepo, businessRepo, staffRepo,
+		paymentGateway, validator)
+	announcementService := service.NewAnnouncementService(announcementRepo, businessRepo, staffRepo, validator)
+	webhookService := service.NewWebhookService(webhookRepo, staffRepo, validator)
+	webhookDispatcher := webhook.NewDispatcher(webhookRepo, nil)
+	demoDataService := service.NewDemoDataService(demoDataRepo, businessRepo, staffRepo, serviceRepo, validator)
+	savedViewService := service.NewSavedViewService(savedViewRepo, staffRepo, validator)
+	businessImportService := service.NewBusinessImportService(businessImportRepo, staffRepo, entitlementService, validator)
+	// Operator alerts bypass the notification queue, which may be what is stuck
+	watchdogService := service.NewWatchdogService(watchdogRepo, appointmentService, notificationRouter,
+		config.Notification.OperatorEmail, domain.DefaultWatchdogThresholds())
+	runSheetService := service.NewRunSheetService(staffRepo, calendarRepo, clientRepo, serviceRecordRepo)
+	// Background jobs are enqueued by subsystems and run by the handlers registered with the runner
+	jobRunner := jobs.NewRunner(jobRepo)
+	jobQueue := jobs.NewQueue(jobRepo, jobRunner)
+	jobService := service.NewJobService(jobRepo, jobRunner, validator)
+	auditLogService := service.NewAuditLogService(auditLogRepo, staffRepo, validator)
+	permissionService := service.NewPermissionService(staffRepo, validator)
+	trashService := service.NewTrashService(clientRepo, serviceRepo, appointmentRepo, staffRepo, validator)
+	sessionService := service.NewSessionService(userSessionRepo, userRepo, clerkClient, config.Auth.ClerkWebhookSecret, validator)
+	searchService := service.NewSearchService(searchRepo, staffRepo, validator)
+	clientLeaderboardService := service.NewClientLeaderboardService(clientLeaderboardRepo, clientRepo, businessSettingsRepo, staffRepo, validator)
+	appointmentQuoteService := service.NewAppointmentQuoteService(appointmentQuoteRepo, serviceRepo, priceChangeRepo, loyaltyRepo, clientRepo,
+		businessRepo, staffRepo, validator)
+	cancellationPolicyService := service.NewCancellationPolicyService(businessSettingsRepo, clientRepo, staffRepo, validator)
+	staffShiftService := service.NewStaffShiftService(staffShiftRepo, staffRepo, businessRepo, validator)
+	timeClockService := service.NewTimeClockService(timeEntryRepo, staffShiftRepo, staffRepo, businessRepo, userRepo, validator)
+	depositFollowUp := service.NewDepositFollowUp(appointmentRepo, businessRepo, businessSettingsRepo, clientRepo, paymentRepo,
+		appointmentService, jobQueue, messageSender)
+	depositFollowUp.RegisterJobs(jobRunner)
+	staffPerformanceService := service.NewStaffPerformanceService(staffPerformanceRepo, staffRepo, businessRepo, jobQueue, validator)
+	staffPerformanceService.RegisterJobs(jobRunner)
+	reportExportService := service.NewReportExportService(reportExportRepo, staffRepo, userRepo, businessRepo, commissionService,
+		objectStore, storageBuckets, jobQueue, config.Storage.URLExpiry, validator)
+	reportExportService.RegisterJobs(jobRunner)
+	imageUploadService := service.NewImageUploadService(imageUploadRepo, businessRepo, staffRepo, objectStore, storageBuckets,
+		config.Storage.URLExpiry, validator)
+	clientAttachmentService := service.NewClientAttachmentService(clientAttachmentRepo, clientRepo, appointmentRepo, businessRepo, staffRepo,
+		objectStore, storageBuckets, config.Storage.URLExpiry, validator)
+	reviewService := service.NewReviewService(reviewRepo, staffRepo, domain.NewWordFilter(config.App.ReviewBlockedWordList()), jobQueue, validator)
+	clientTimelineService := service.NewClientTimelineService(clientTimelineRepo, clientRepo, staffRepo, validator)
+	reviewFollowUp := service.NewReviewFollowUp(reviewRepo, businessSettingsRepo, jobQueue, messageSender, config.App.PublicURL)
+	reviewFollowUp.RegisterJobs(jobRunner)
+
+	// Register event consumers
+	eventService.RegisterConsumer(service.NewCalendarProjector(calendarRepo))
+	eventService.RegisterConsumer(service.NewLoyaltyEngine(loyaltyRepo, appointmentRepo, serviceCompletionRepo))
+	eventService.RegisterConsumer(webhookDispatcher)
+	eventService.RegisterConsumer(depositFollowUp)
+	eventService.RegisterConsumer(reviewFollowUp)
+
+	// Started as "worker", the process only works the job queue
+	if len(os.Args) > 1 && os.Args[1] == "worker" {
+		runJobWorker(jobRunner, config.Jobs.PollInterval)
+		if err := shutdownTelemetry(context.Background()); err != nil {
+			log.Warn().Err(err).Msg("Failed to flush traces")
+		}
+		return
+	}
+
+	// Started as "money-migrate backfill" or "money-migrate verify", the process converts or
+	// checks the minor units of the monetary columns
+	if len(os.Args) > 2 && os.Args[1] == "money-migrate" {
+		err := runMoneyMigration(service.NewMoneyMigrationService(repository.NewMoneyMigrationRepository(db.DB)), os.Args[2])
+		if err != nil {
+			log.Fatal().Err(err).Msg("Money migration failed")
+		}
+		return
+	}
 
 	// Initialize GraphQL resolver and schema
-	resolver := graph.NewResolver(userService, authService)
+	resolver := graph.NewResolver(userService, authService,
+		graph.WithServiceRecordService(serviceRecordService),
+		graph.WithCommissionService(commissionService),
+		graph.WithIntegrationUsageService(integrationUsageService),
+		graph.WithEventService(eventService),
+		graph.WithCalendarService(calendarService),
+		graph.WithClientPortalService(clientPortalService),
+		graph.WithMessageTemplateService(messageTemplateService),
+		graph.WithAppointmentConfirmationService(confirmationService),
+		graph.WithBookingGuardService(bookingGuardService),
+		graph.WithCapacityService(capacityService),
+		graph.WithAppointmentService(appointmentService),
+		graph.WithPriceChangeService(priceChangeService),
+		graph.WithServiceBundleService(serviceBundleService),
+		graph.WithClientGuardianService(clientGuardianService),
+		graph.WithBroadcastService(broadcastService),
+		graph.WithPaymentService(paymentService),
+		graph.WithClientService(clientService),
+		graph.WithOutboundRequestService(outboundRequestService),
+		graph.WithDataResidencyService(dataResidencyService),
+		graph.WithAnonymousFeedbackService(anonymousFeedbackService),
+		graph.WithAvailabilityService(availabilityService),
+		graph.WithScheduleChangeService(scheduleChangeService),
+		graph.WithGiftCardService(giftCardService),
+		graph.WithStaffBookingPageService(staffBookingPageService),
+		graph.WithBusinessService(businessService),
+		graph.WithReferralService(referralService),
+		graph.WithCampaignService(campaignService),
+		graph.WithMembershipService(membershipService),
+		graph.WithAnnouncementService(announcementService),
+		graph.WithWebhookService(webhookService),
+		graph.WithDemoDataService(demoDataService),
+		graph.WithSavedViewService(savedViewService),
+		graph.WithBusinessImportService(businessImportService),
+		graph.WithWatchdogService(watchdogService),
+		graph.WithRunSheetService(runSheetService),
+		graph.WithJobService(jobService),
+		graph.WithAuditLogService(auditLogService),
+		graph.WithPermissionService(permissionService),
+		graph.WithTrashService(trashService),
+		graph.WithSessionService(sessionService),
+		graph.WithSearchService(searchService),
+		graph.WithClientLeaderboardService(clientLeaderboardService),
+		graph.WithAppointmentQuoteService(appointmentQuoteService),
+		graph.WithCancellationPolicyService(cancellationPolicyService),
+		graph.WithStaffShiftService(staffShiftService),
+		graph.WithTimeClockService(timeClockService),
+		graph.WithStaffPerformanceService(staffPerformanceService),
+		graph.WithReportExportService(reportExportService),
+		graph.WithImageUploadService(imageUploadService),
+		graph.WithClientAttachmentService(clientAttachmentService),
+		graph.WithReviewService(reviewService),
+		graph.WithClientTimelineService(clientTimelineService),
+		graph.WithSubscriptionService(subscriptionService),
+		graph.WithTrialService(trialService),
+		graph.WithBusinessHoursService(businessHoursService),
+		graph.WithResourceService(resourceService),
+		graph.WithAdminService(adminService),
+	)
 	schema, err := graph.CreateSchema(resolver)
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to create GraphQL schema")
 	}
+	publicSchema, err := graph.CreatePublicSchema(graph.NewPublicResolver(publicBookingService))
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to create public booking GraphQL schema")
+	}
 
 	// Setup HTTP server with routes
 	mux := http.NewServeMux()
 
 	// GraphQL endpoint
-	mux.Handle("/graphql", graph.Handler(schema))
+	privateOptions := []graph.HandlerOption{
+		graph.WithLoaders(dataloader.Sources{
+			Users:        userRepo,
+			Clients:      clientRepo,
+			Staff:        staffRepo,
+			Businesses:   businessRepo,
+			Services:     serviceRepo,
+			Appointments: appointmentRepo,
+		}),
+		graph.WithRequestObserver(func(ctx context.Context, operationName string, failed bool) {
+			businessID := service.GetBusinessIDFromContext(ctx)
+			if businessID == nil {
+				return
+			}
+			if err := integrationUsageService.RecordAPIRequest(ctx, *businessID, "", failed); err != nil {
+				telemetry.Logger(ctx).Warn().Err(err).Str("operation", operationName).Msg("Failed to record integration usage")
+			}
+		}),
+		graph.WithAuthentication(service.NewAuthenticator(clerkClient, userRepo, staffRepo, config.Auth.PlatformAdminIDs())),
+		graph.WithSessionCheck(sessionService),
+		graph.WithIdempotency(idempotencyService),
+		graph.WithSuspensionCheck(adminService),
+		graph.WithLocalePreferences(userService),
+		graph.WithTelemetry(),
+		graph.WithRequestLogging(),
+	}
+	publicOptions := []graph.HandlerOption{
+		graph.WithRateLimit(graph.NewRateLimiter(publicRequestsPerMinute, time.Minute)),
+		graph.WithIdempotency(idempotencyService),
+		graph.WithTelemetry(),
+		graph.WithRequestLogging(),
+	}
+	if !config.GraphQL.Introspection {
+		privateOptions = append(privateOptions, graph.WithoutIntrospection())
+		publicOptions = append(publicOptions, graph.WithoutIntrospection())
+	}
+	mux.Handle("/graphql", graph.Handler(schema, privateOptions...))
 
-	// GraphQL Sandbox (Apollo Studio)
-	mux.Handle("/sandbox", graph.SandboxHandler("http://localhost:8090/graphql"))
+	// Unauthenticated online booking, limited per IP address
+	mux.Handle(graph.PublicGraphQLPath, graph.Handler(publicSchema, publicOptions...))
 
-	// Health check endpoint
-	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(`{"status":"ok","version":"` + Version + `"}`))
-	})
+	// Service menus for booking widgets and crawlers, cacheable by ETag and Last-Modified
+	mux.Handle(graph.PublicServiceMenuPath, graph.ServiceMenuHandler(publicBookingService))
+
+	// Client portal invoice downloads
+	mux.Handle(graph.InvoiceDownloadPath, graph.InvoiceDownloadHandler(clientPortalService))
+
+	// Appointment confirmation links and SMS replies
+	mux.Handle(service.ConfirmationLinkPath, graph.ConfirmationHandler(confirmationService))
+	mux.Handle(graph.SMSReplyPath, graph.SMSReplyHandler(confirmationService))
+
+	// Subscription events posted by Stripe
+	mux.Handle(graph.StripeWebhookPath, graph.StripeWebhookHandler(subscriptionService))
+
+	// User updates posted by Clerk, which sign other devices out on security changes
+	mux.Handle(graph.ClerkWebhookPath, graph.ClerkWebhookHandler(sessionService))
+
+	// Rescheduling links sent by emergency broadcasts
+	mux.Handle(service.RescheduleLinkPath, graph.RescheduleHandler(broadcastService))
+
+	// Anonymous feedback links
+	mux.Handle(service.FeedbackLinkPath, graph.FeedbackHandler(anonymousFeedbackService))
+
+	// Review links sent after completed appointments
+	mux.Handle(service.ReviewLinkPath, graph.ReviewHandler(reviewService))
+
+	// GraphQL Sandbox (Apollo Studio), which needs introspection
+	if config.GraphQL.Introspection {
+		mux.Handle("/sandbox", graph.SandboxHandler("http://localhost:8090/graphql"))
+	}
+
+	// Liveness endpoint; /health is kept for existing monitors
+	mux.Handle("/healthz", graph.LivenessHandler(Version))
+	mux.Handle("/health", graph.LivenessHandler(Version))
+
+	// Readiness endpoint with a check of every dependency and the health of every external provider
+	latestMigration, err := migrations.Latest()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to read the embedded migrations")
+	}
+	readinessChecks := []health.Check{
+		health.Database(db.PingContext),
+		health.Migrations(db.MigrationVersion, latestMigration),
+		health.JobQueue(jobRepo.OldestDue, config.Jobs.MaxLag),
+	}
+	if config.Auth.ClerkSecretKey != "" {
+		readinessChecks = append(readinessChecks, health.Provider(auth.ProviderName, clerkClient.Ping))
+	}
+	mux.Handle("/readyz", graph.ReadinessHandler(readinessChecks, providers))
+
+	// Prometheus metrics of request latency, resolvers and database queries
+	if config.Telemetry.MetricsEnabled {
+		mux.Handle("/metrics", telemetry.MetricsHandler())
+	}
 
 	// Create HTTP server
 	server := &http.Server{
 		Addr:         fmt.Sprintf(":%s", config.App.Port),
-		Handler:      mux,
+		Handler:      telemetry.RequestIDMiddleware(telemetry.Middleware(mux)),
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
@@ -118,8 +544,11 @@ func main() {
 		log.Info().
 			Str("port", config.App.Port).
 			Str("graphql_endpoint", "/graphql").
+			Str("public_graphql_endpoint", graph.PublicGraphQLPath).
 			Str("sandbox_endpoint", "/sandbox").
-			Str("health_endpoint", "/health").
+			Bool("graphql_introspection", config.GraphQL.Introspection).
+			Str("liveness_endpoint", "/healthz").
+			Str("readiness_endpoint", "/readyz").
 			Msg("Starting HTTP server")
 
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -127,6 +556,135 @@ func main() {
 		}
 	}()
 
+	// Background components are stopped in the reverse of this order on shutdown, each given its
+	// drain timeout to finish its work in flight, so what they produce is still consumed
+	background := lifecycle.NewManager()
+	// Deliver webhooks as events are published, retrying failed deliveries when they are due
+	background.Go("webhook_dispatcher", dispatcherDrainTimeout, func(ctx context.Context) {
+		webhookDispatcher.Start(ctx, 15*time.Second)
+	})
+	// Publish the events recorded in the outbox as soon as they are committed
+	background.Go("event_relay", relayDrainTimeout, func(ctx context.Context) { eventRelay.Start(ctx, time.Second) })
+	// Run background jobs, unless dedicated workers do
+	if config.Jobs.RunInAPI {
+		background.Go("job_runner", jobRunnerDrainTimeout, func(ctx context.Context) {
+			jobRunner.Start(ctx, config.Jobs.PollInterval)
+		})
+	}
+	// Run periodic background work: confirmation requests for appointments entering their
+	// confirmation lead time and escalation of those left unacknowledged, pruning of booking
+	// attempts used for velocity checks, notification of capacity warnings for the coming days,
+	// scheduled price changes, messages queued while their provider was unavailable, membership
+	// billing with its dunning, pruning of the outbound request audit, and the VIP tags of clients
+	background.Go("scheduler", schedulerDrainTimeout, func(ctx context.Context) {
+		work := lifecycle.Work(ctx)
+		ticker := time.NewTicker(5 * time.Minute)
+		defer ticker.Stop()
+		for {
+			run, err := confirmationService.SendDueRequests(work, time.Now())
+			if err != nil {
+				log.Error().Err(err).Msg("Failed to send confirmation requests")
+			} else if run.Due > 0 {
+				log.Info().Int("sent", run.Sent).Int("failed", run.Failed).Msg("Sent confirmation requests")
+			}
+			if run, err := confirmationService.EscalateUnacknowledged(work, time.Now()); err != nil {
+				log.Error().Err(err).Msg("Failed to escalate confirmation requests")
+			} else if run.Due > 0 {
+				log.Info().Int("resent", run.Resent).Int("tasks", run.Tasks).Int("failed", run.Failed).Msg("Escalated confirmation requests")
+			}
+			if _, err := bookingGuardService.PruneAttempts(work, time.Now()); err != nil {
+				log.Error().Err(err).Msg("Failed to prune booking attempts")
+			}
+			if run, err := capacityService.NotifyUpcomingWarnings(work, time.Now()); err != nil {
+				log.Error().Err(err).Msg("Failed to notify capacity warnings")
+			} else if run.Warnings > 0 {
+				log.Info().Int("businesses", run.Businesses).Int("warnings", run.Warnings).Msg("Notified capacity warnings")
+			}
+			if run, err := priceChangeService.ApplyDueChanges(work, time.Now()); err != nil {
+				log.Error().Err(err).Msg("Failed to apply price changes")
+			} else if run.Due > 0 {
+				log.Info().Int("applied", run.Applied).Int("failed", run.Failed).Int("services", run.Services).Msg("Applied price changes")
+			}
+			if run, err := messageQueue.DeliverQueued(work, time.Now()); err != nil {
+				log.Error().Err(err).Msg("Failed to deliver queued notifications")
+			} else if run.Due > 0 {
+				log.Info().Int("sent", run.Sent).Int("failed", run.Failed).Msg("Delivered queued notifications")
+			}
+			if run, err := membershipService.RunBilling(work, time.Now()); err != nil {
+				log.Error().Err(err).Msg("Failed to run membership billing")
+			} else if run.Due > 0 {
+				log.Info().Int("paid", run.Paid).Int("failed", run.Failed).Int("suspended", run.Suspended).Int("errors", run.Errors).
+					Msg("Ran membership billing")
+			}
+			if _, err := outboundRequestService.PruneOutboundRequests(work, time.Now()); err != nil {
+				log.Error().Err(err).Msg("Failed to prune outbound requests")
+			}
+			if _, err := idempotencyService.PruneIdempotencyKeys(work, time.Now()); err != nil {
+				log.Error().Err(err).Msg("Failed to prune idempotency keys")
+			}
+			if report, err := watchdogService.Run(work, time.Now()); err != nil {
+				log.Error().Err(err).Msg("Failed to run the watchdog")
+			} else {
+				for _, finding := range report.Findings {
+					if finding.Count > 0 || finding.Error != nil {
+						log.Warn().Str("check", string(finding.Check)).Int64("count", finding.Count).Int64("remediated", finding.Remediated).
+							Str("action", string(finding.Action)).Msg("Watchdog found stuck work")
+					}
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	})
+	// VIPs change as clients cross thresholds and older visits leave the rules' period, and
+	// staff performance as appointments are completed and rated, neither of which calls for
+	// more than an hourly refresh; replaced images and deleted attachments only cost storage until removed,
+	// and trial notices and expiry are due by the day
+	background.Go("maintenance", schedulerDrainTimeout, func(ctx context.Context) {
+		work := lifecycle.Work(ctx)
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for {
+			if run, err := clientLeaderboardService.RefreshAllVIPClients(work, time.Now()); err != nil {
+				log.Error().Err(err).Msg("Failed to refresh VIP clients")
+			} else if run.Tagged > 0 || run.Untagged > 0 || run.Failed > 0 {
+				log.Info().Int("businesses", run.Businesses).Int64("tagged", run.Tagged).Int64("untagged", run.Untagged).
+					Int("failed", run.Failed).Msg("Refreshed VIP clients")
+			}
+			if run, err := staffPerformanceService.AggregateRecent(work, time.Now()); err != nil {
+				log.Error().Err(err).Msg("Failed to aggregate staff performance")
+			} else if run.Businesses > 0 || run.Failed > 0 {
+				log.Info().Int("businesses", run.Businesses).Int("records", run.Records).Int("failed", run.Failed).
+					Msg("Aggregated staff performance")
+			}
+			if run, err := imageUploadService.CleanupImageUploads(work, time.Now()); err != nil {
+				log.Error().Err(err).Msg("Failed to clean up image uploads")
+			} else if run.Removed > 0 || run.Failed > 0 {
+				log.Info().Int("removed", run.Removed).Int("failed", run.Failed).Msg("Cleaned up image uploads")
+			}
+			if run, err := clientAttachmentService.CleanupClientAttachments(work, time.Now()); err != nil {
+				log.Error().Err(err).Msg("Failed to clean up client attachments")
+			} else if run.Removed > 0 || run.Failed > 0 {
+				log.Info().Int("removed", run.Removed).Int("failed", run.Failed).Msg("Cleaned up client attachments")
+			}
+			if run, err := trialService.ProcessTrials(work, time.Now()); err != nil {
+				log.Error().Err(err).Msg("Failed to process trials")
+			} else if run.Notified > 0 || run.Restricted > 0 || run.Failed > 0 {
+				log.Info().Int("notified", run.Notified).Int("restricted", run.Restricted).Int("failed", run.Failed).Msg("Processed trials")
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	})
+
 	// Wait for interrupt signal to gracefully shutdown the server
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -134,12 +692,22 @@ func main() {
 
 	log.Info().Msg("Shutting down server...")
 
-	// Gracefully shutdown the server with a 30 second timeout
+	// Stop taking requests first, since they enqueue jobs and record events, then drain the
+	// background components, and only then flush traces and close the database
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
 	if err := server.Shutdown(ctx); err != nil {
-		log.Fatal().Err(err).Msg("Server forced to shutdown")
+		log.Error().Err(err).Msg("Server forced to shutdown")
+	}
+	if err := background.Shutdown(ctx); err != nil {
+		log.Warn().Err(err).Msg("Background work was cut short")
+	}
+	if err := shutdownTelemetry(ctx); err != nil {
+		log.Warn().Err(err).Msg("Failed to flush traces")
+	}
+	if err := database.CloseDB(); err != nil {
+		log.Warn().Err(err).Msg("Failed to close the database")
 	}
 
 	log.Info().Msg("Server exited")
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+// moneyBackfillBatchSize is how many rows each backfill statement converts
+const moneyBackfillBatchSize = 1000
+
+// runMoneyMigration backfills or verifies the minor units of the monetary columns, as in
+// "api money-migrate backfill" and "api money-migrate verify", and prints the verification
+// report. It fails when a column does not verify.
+func runMoneyMigration(migrationService service.MoneyMigrationService, command string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var report *domain.MoneyMigrationReport
+	var err error
+	switch command {
+	case "backfill":
+		report, err = migrationService.Backfill(ctx, moneyBackfillBatchSize)
+	case "verify":
+		report, err = migrationService.Verify(ctx)
+	default:
+		return fmt.Errorf("unknown money-migrate command %q, expected backfill or verify", command)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%-45s %10s %10s %10s %10s %15s %15s\n", "COLUMN", "ROWS", "MISSING", "MISMATCHED", "FRACTIONAL", "SUM", "SUM MINOR")
+	for _, column := range report.Columns {
+		fmt.Printf("%-45s %10d %10d %10d %10d %15s %15d\n", column.Column.String(), column.Rows, column.Missing,
+			column.Mismatched, column.Fractional, column.Sum.StringFixed(2), column.SumMinor)
+	}
+	if !report.Verified() {
+		return errors.New("minor units do not match the amounts of every column")
+	}
+	log.Info().Int("columns", len(report.Columns)).Msg("Minor units verified")
+	return nil
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/assimoes/beautix/internal/infrastructure/jobs"
+	"github.com/assimoes/beautix/internal/infrastructure/lifecycle"
+	"github.com/rs/zerolog/log"
+)
+
+// Drain timeouts of the background components: how long each may take to finish its work in
+// flight once the process is asked to stop
+const (
+	jobRunnerDrainTimeout  = 15 * time.Second
+	relayDrainTimeout      = 5 * time.Second
+	dispatcherDrainTimeout = 10 * time.Second // Webhook deliveries wait on endpoints outside our control
+	schedulerDrainTimeout  = 10 * time.Second
+)
+
+// runJobWorker works the job queue until the process is interrupted, letting the jobs in flight
+// finish. Jobs cut short run again once their lease runs out. Any number of workers can run
+// beside the API servers.
+func runJobWorker(runner *jobs.Runner, interval time.Duration) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	log.Info().
+		Strs("kinds", runner.Kinds()).
+		Dur("poll_interval", interval).
+		Msg("Starting job worker")
+	background := lifecycle.NewManager()
+	background.Go("job_runner", jobRunnerDrainTimeout, func(ctx context.Context) { runner.Start(ctx, interval) })
+	<-ctx.Done()
+
+	if err := background.Shutdown(context.Background()); err != nil {
+		log.Warn().Err(err).Msg("Job worker stopped before its jobs finished")
+	}
+	log.Info().Msg("Job worker exited")
+}
@@ -0,0 +1,133 @@
+// Command prober runs synthetic probes of the booking funnel against a running API: login,
+// availability, booking in a sandbox business and a deposit paid with a Stripe test card.
+//
+// As a sidecar it probes every interval and serves the outcome and latency of each flow on
+// /metrics for Prometheus to scrape:
+//
+//	prober -api http://beautix:8090 -business $SANDBOX_BUSINESS_ID -interval 1m -listen :9102
+//
+// As a scheduled job (e.g. a Kubernetes CronJob) it probes once, pushes the metrics to a
+// Pushgateway when given one and exits with status 1 when a flow failed:
+//
+//	prober -api http://beautix:8090 -business $SANDBOX_BUSINESS_ID -once -pushgateway http://pushgateway:9091
+//
+// The probe user's session token is read from PROBE_TOKEN rather than a flag, so it does not
+// show in the process list.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/push"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	"github.com/assimoes/beautix/internal/infrastructure/probe"
+	"github.com/assimoes/beautix/pkg/graph"
+)
+
+// pushJob is the job name the metrics are pushed to the Pushgateway under
+const pushJob = "beautix_prober"
+
+func main() {
+	api := flag.String("api", envOr("PROBE_API_URL", "http://localhost:8090"), "the base URL of the API")
+	businessID := flag.String("business", os.Getenv("PROBE_BUSINESS_ID"), "the ID of the sandbox business to book in")
+	serviceID := flag.String("service", os.Getenv("PROBE_SERVICE_ID"), "the ID of the service to book; the first bookable one when empty")
+	paymentMethod := flag.String("payment-method", envOr("PROBE_PAYMENT_METHOD_ID", probe.DefaultPaymentMethodID), "the Stripe test PaymentMethod the deposit is charged to")
+	deposit := flag.String("deposit", probe.DefaultDepositAmount, "the deposit charged, and refunded")
+	interval := flag.Duration("interval", time.Minute, "how often to probe as a sidecar")
+	timeout := flag.Duration("timeout", probe.DefaultTimeout, "bounds a run of every flow")
+	listen := flag.String("listen", ":9102", "the address metrics are served on as a sidecar")
+	once := flag.Bool("once", false, "probe once and exit, as a scheduled job")
+	pushgateway := flag.String("pushgateway", os.Getenv("PROBE_PUSHGATEWAY_URL"), "the Pushgateway to push the metrics of a single run to")
+	flag.Parse()
+
+	zerolog.TimeFieldFormat = time.RFC3339
+	base := strings.TrimRight(*api, "/")
+	prober, err := probe.New(probe.Config{
+		PrivateURL:      base + "/graphql",
+		PublicURL:       base + graph.PublicGraphQLPath,
+		Token:           os.Getenv("PROBE_TOKEN"),
+		BusinessID:      *businessID,
+		ServiceID:       *serviceID,
+		PaymentMethodID: *paymentMethod,
+		DepositAmount:   *deposit,
+		Timeout:         *timeout,
+	}, &http.Client{Timeout: *timeout})
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid prober configuration")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *once {
+		failed := runOnce(ctx, prober)
+		if *pushgateway != "" {
+			if err := push.New(*pushgateway, pushJob).Gatherer(probe.Gatherer()).PushContext(ctx); err != nil {
+				log.Error().Err(err).Str("pushgateway", *pushgateway).Msg("Failed to push probe metrics")
+				failed = true
+			}
+		}
+		if failed {
+			os.Exit(1)
+		}
+		return
+	}
+
+	server := &http.Server{Addr: *listen, Handler: probe.MetricsHandler(), ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal().Err(err).Msg("Failed to serve probe metrics")
+		}
+	}()
+	log.Info().Str("listen", *listen).Dur("interval", *interval).Str("api", base).Msg("Starting prober")
+
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for {
+		runOnce(ctx, prober)
+		select {
+		case <-ctx.Done():
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_ = server.Shutdown(shutdownCtx)
+			log.Info().Msg("Prober exited")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runOnce probes every flow, logging the outcome of each, and reports whether any failed
+func runOnce(ctx context.Context, prober *probe.Prober) bool {
+	failed := false
+	for _, result := range prober.Run(ctx) {
+		event := log.Info()
+		switch {
+		case result.Failed():
+			failed = true
+			event = log.Error().Err(result.Err)
+		case result.Err != nil:
+			event = log.Warn().Err(result.Err)
+		}
+		event.Str("flow", result.Flow).Dur("duration", result.Duration).Msg("Probed flow")
+	}
+	return failed
+}
+
+// envOr returns the environment variable, or the fallback when it is unset
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,143 @@
+// Command schemadoc writes browsable documentation of the GraphQL API for frontend developers.
+// By default it builds the schemas the server registers and introspects them in-process, so no
+// database or running server is needed; with -url it introspects a running server instead.
+// The sdl format prints the built-in schema in the schema definition language for the code
+// generators of frontends, and the subgraph format prints the private schema as the federation
+// subgraph it is, for routers composing from a file when introspection is turned off.
+//
+//	go run ./cmd/schemadoc -format html -out docs/graphql.html
+//	go run ./cmd/schemadoc -format sdl -out docs/schema.graphql
+//	go run ./cmd/schemadoc -schema public -format markdown -out docs/public-graphql.md
+//	go run ./cmd/schemadoc -url http://localhost:8090/graphql -token $TOKEN
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/pkg/graph"
+	"github.com/assimoes/beautix/pkg/graph/schemadoc"
+)
+
+func main() {
+	schemaName := flag.String("schema", "private", "the schema to document: private (/graphql) or public (/public/graphql)")
+	format := flag.String("format", string(schemadoc.FormatMarkdown), "the output format: markdown, html, sdl or subgraph")
+	out := flag.String("out", "", "the file to write, standard output when empty")
+	url := flag.String("url", "", "the endpoint of a running server to introspect instead of the built-in schema")
+	token := flag.String("token", os.Getenv("GRAPHQL_TOKEN"), "the bearer token authenticating with -url")
+	title := flag.String("title", "", "the title of the documentation")
+	flag.Parse()
+
+	if err := run(*schemaName, schemadoc.Format(*format), *out, *url, *token, *title); err != nil {
+		fmt.Fprintln(os.Stderr, "schemadoc:", err)
+		os.Exit(1)
+	}
+}
+
+// The formats printing the built-in schema itself rather than documenting it
+const (
+	formatSDL      schemadoc.Format = "sdl"
+	formatSubgraph schemadoc.Format = "subgraph"
+)
+
+func run(schemaName string, format schemadoc.Format, out, url, token, title string) error {
+	if format == formatSDL || format == formatSubgraph {
+		return printSDL(schemaName, format, out, url)
+	}
+
+	var schema *schemadoc.Schema
+	var err error
+	if url != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		schema, err = schemadoc.Fetch(ctx, http.DefaultClient, url, token)
+	} else {
+		schema, err = introspectBuiltIn(schemaName)
+	}
+	if err != nil {
+		return err
+	}
+
+	if title == "" {
+		title = "Beautix GraphQL API"
+		if schemaName == "public" {
+			title = "Beautix public booking GraphQL API"
+		}
+	}
+	return writeTo(out, func(w io.Writer) error {
+		return schemadoc.Write(w, schema, format, title)
+	})
+}
+
+// printSDL writes a built-in schema in the schema definition language
+func printSDL(schemaName string, format schemadoc.Format, out, url string) error {
+	if url != "" {
+		return fmt.Errorf("-format %s prints the built-in schema and cannot be used with -url", format)
+	}
+	if format == formatSubgraph && schemaName != "private" {
+		return fmt.Errorf("only the private schema is a federation subgraph")
+	}
+	schema, err := buildSchema(schemaName)
+	if err != nil {
+		return err
+	}
+	sdl := graph.PrintSDL(schema)
+	if format == formatSubgraph {
+		sdl = graph.PrintSubgraphSDL(schema)
+	}
+	return writeTo(out, func(w io.Writer) error {
+		_, err := io.WriteString(w, sdl)
+		return err
+	})
+}
+
+// writeTo writes to a file, or to standard output when none is given
+func writeTo(out string, write func(w io.Writer) error) error {
+	if out == "" {
+		return write(os.Stdout)
+	}
+	file, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	if err := write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// introspectBuiltIn builds a schema the way the server does and introspects it
+func introspectBuiltIn(schemaName string) (*schemadoc.Schema, error) {
+	schema, err := buildSchema(schemaName)
+	if err != nil {
+		return nil, err
+	}
+	return schemadoc.Introspect(schema)
+}
+
+// buildSchema builds a schema the way the server does, without services: neither introspection
+// nor printing resolves a field, so none are needed
+func buildSchema(schemaName string) (graphql.Schema, error) {
+	var schema graphql.Schema
+	var err error
+	switch schemaName {
+	case "private":
+		schema, err = graph.CreateSchema(graph.NewResolver(nil, nil))
+	case "public":
+		schema, err = graph.CreatePublicSchema(graph.NewPublicResolver(nil))
+	default:
+		return graphql.Schema{}, fmt.Errorf("unknown schema %q, expected private or public", schemaName)
+	}
+	if err != nil {
+		return graphql.Schema{}, fmt.Errorf("failed to build the %s schema: %w", schemaName, err)
+	}
+	return schema, nil
+}
@@ -2,89 +2,225 @@ package configs
 
 import (
 	"fmt"
+	"reflect"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
 )
 
-// Config stores all configuration of the application
+// Config stores all configuration of the application. Each setting is read from the environment
+// variable in its env tag and checked against its validate tag; secrets can be read from the
+// file named by the variable with a _FILE suffix instead.
 type Config struct {
-	App         AppConfig
-	Database    DatabaseConfig
-	Auth        AuthConfig
-	Environment string
+	App          AppConfig
+	Database     DatabaseConfig
+	Auth         AuthConfig
+	Notification NotificationConfig
+	Stripe       StripeConfig
+	Residency    ResidencyConfig
+	Storage      StorageConfig
+	Telemetry    TelemetryConfig
+	Jobs         JobsConfig
+	GraphQL      GraphQLConfig
+	Environment  string `env:"APP_ENV" validate:"oneof=development testing staging production"`
 }
 
 // AppConfig stores application configuration
 type AppConfig struct {
-	Host string
-	Port string
+	Host               string `env:"APP_HOST" validate:"required"`
+	Port               string `env:"APP_PORT" validate:"port"`
+	PublicURL          string `env:"APP_PUBLIC_URL" validate:"required,url"` // Externally reachable base URL, used in links sent to clients
+	ReviewBlockedWords string `env:"REVIEW_BLOCKED_WORDS"`                   // Comma-separated words that hold reviews and replies back for the business to look at
 }
 
 // DatabaseConfig stores database configuration
 type DatabaseConfig struct {
-	Host     string
-	Port     string
-	User     string
-	Password string
-	DBName   string
-	SSLMode  string
-	URL      string
+	Host                string        `env:"DB_HOST" validate:"required"`
+	Port                string        `env:"DB_PORT" validate:"port"`
+	User                string        `env:"DB_USER" validate:"required"`
+	Password            string        `env:"DB_PASSWORD" secret:"true"`
+	DBName              string        `env:"DB_NAME" validate:"required"`
+	SSLMode             string        `env:"DB_SSLMODE" validate:"oneof=disable allow prefer require verify-ca verify-full"`
+	URL                 string        // Built from the settings above
+	MoneyMigrationPhase string        `env:"DB_MONEY_MIGRATION_PHASE" validate:"oneof=dual_write dual_read"` // dual_write reads amounts from the decimal columns, dual_read from their minor units
+	MaxOpenConns        int           `env:"DB_MAX_OPEN_CONNS" validate:"gt=0"`                              // Connections an instance opens at most; keep their sum over every instance under Postgres' max_connections
+	MaxIdleConns        int           `env:"DB_MAX_IDLE_CONNS" validate:"gte=0"`                             // Connections kept open while idle, at most DB_MAX_OPEN_CONNS
+	ConnMaxLifetime     time.Duration `env:"DB_CONN_MAX_LIFETIME" validate:"gte=0"`                          // Connections are replaced once this old, e.g. to spread over a failed-over primary; never when 0
+	ConnMaxIdleTime     time.Duration `env:"DB_CONN_MAX_IDLE_TIME" validate:"gte=0"`                         // Idle connections are closed once idle this long; never when 0
+	StatementTimeout    time.Duration `env:"DB_STATEMENT_TIMEOUT" validate:"gte=0"`                          // Postgres cancels statements running longer, freeing their connection; never when 0
 }
 
 // AuthConfig stores authentication configuration
 type AuthConfig struct {
-	Secret     string
-	Expiration time.Duration
-	ClerkSecretKey string
-	ClerkPublishableKey string
+	Secret              string        `env:"JWT_SECRET" secret:"true" validate:"required,min=32"`
+	Expiration          time.Duration `env:"JWT_EXPIRATION" validate:"gt=0"`
+	ClerkSecretKey      string        `env:"CLERK_SECRET_KEY" secret:"true"`
+	ClerkPublishableKey string        `env:"CLERK_PUBLISHABLE_KEY"`
+	ClerkAPIURL         string        `env:"CLERK_API_URL" validate:"required,url"` // Base URL of the Clerk Backend API
+	PlatformAdmins      string        `env:"PLATFORM_ADMINS"`                       // Comma-separated Clerk user IDs of the platform operators
+	ClerkWebhookSecret  string        `env:"CLERK_WEBHOOK_SECRET" secret:"true"`    // Signs the events Clerk posts to the webhook endpoint
 }
 
-// LoadConfig reads configuration from environment variables or .env file
+// NotificationConfig stores the providers messages to clients are delivered through
+type NotificationConfig struct {
+	EmailProvider string `env:"NOTIFICATION_EMAIL_PROVIDER" validate:"omitempty,oneof=smtp log"`          // "smtp" or "log"
+	SMSProvider   string `env:"NOTIFICATION_SMS_PROVIDER" validate:"omitempty,oneof=twilio whatsapp log"` // "twilio", "whatsapp" or "log"
+	SMTP          SMTPConfig
+	Twilio        TwilioConfig
+	WhatsApp      WhatsAppConfig
+	OperatorEmail string `env:"OPERATOR_ALERT_EMAIL" validate:"omitempty,email"` // Where alerts for platform operators are sent; logged when empty
+}
+
+// SMTPConfig stores the SMTP server email is sent through
+type SMTPConfig struct {
+	Host     string `env:"SMTP_HOST"`
+	Port     string `env:"SMTP_PORT" validate:"port"`
+	Username string `env:"SMTP_USERNAME"`
+	Password string `env:"SMTP_PASSWORD" secret:"true"`
+	From     string `env:"SMTP_FROM"`
+}
+
+// TwilioConfig stores the Twilio account SMS is sent from
+type TwilioConfig struct {
+	AccountSID string `env:"TWILIO_ACCOUNT_SID"`
+	AuthToken  string `env:"TWILIO_AUTH_TOKEN" secret:"true"`
+	FromNumber string `env:"TWILIO_FROM_NUMBER"`
+}
+
+// WhatsAppConfig stores the WhatsApp Business Cloud API number text messages are sent from
+type WhatsAppConfig struct {
+	APIURL        string `env:"WHATSAPP_API_URL" validate:"required,url"`
+	PhoneNumberID string `env:"WHATSAPP_PHONE_NUMBER_ID"`
+	AccessToken   string `env:"WHATSAPP_ACCESS_TOKEN" secret:"true"`
+}
+
+// StripeConfig stores the Stripe account client payments are taken through, which also bills
+// the subscriptions of businesses
+type StripeConfig struct {
+	SecretKey      string `env:"STRIPE_SECRET_KEY" secret:"true"`
+	APIURL         string `env:"STRIPE_API_URL" validate:"required,url"`
+	WebhookSecret  string `env:"STRIPE_WEBHOOK_SECRET" secret:"true"` // Signs the events Stripe posts to the webhook endpoint
+	ProPriceID     string `env:"STRIPE_PRICE_PRO"`                    // The Stripe price of the pro tier; the tier is not sold when empty
+	PremiumPriceID string `env:"STRIPE_PRICE_PREMIUM"`
+}
+
+// ResidencyConfig stores the destinations outside the database business data is sent to, with
+// the region each is in
+type ResidencyConfig struct {
+	StorageBucketEU string `env:"STORAGE_BUCKET_EU"` // Bucket for files of businesses whose data stays in the EU
+	StorageBucketUS string `env:"STORAGE_BUCKET_US"`
+	WarehouseName   string `env:"WAREHOUSE_NAME"`                                    // Analytics warehouse business data is exported to, if any
+	WarehouseRegion string `env:"WAREHOUSE_REGION" validate:"omitempty,oneof=eu us"` // "eu" or "us"; exports to a warehouse of unknown region leave out EU businesses
+}
+
+// StorageConfig stores the S3-compatible object storage the residency buckets are in
+type StorageConfig struct {
+	Endpoint        string        `env:"STORAGE_ENDPOINT" validate:"omitempty,url"` // Base URL of an S3-compatible service, addressed path-style; AWS S3 in each bucket's region when empty
+	AccessKeyID     string        `env:"STORAGE_ACCESS_KEY_ID"`
+	SecretAccessKey string        `env:"STORAGE_SECRET_ACCESS_KEY" secret:"true"`
+	RegionEU        string        `env:"STORAGE_REGION_EU" validate:"required"` // Region the EU bucket is in, which requests to it are signed for
+	RegionUS        string        `env:"STORAGE_REGION_US" validate:"required"`
+	URLExpiry       time.Duration `env:"STORAGE_URL_EXPIRY" validate:"gt=0,lte=168h"` // How long signed download URLs stay valid; S3 accepts at most a week
+}
+
+// TelemetryConfig stores where traces are exported to and whether metrics are served
+type TelemetryConfig struct {
+	ServiceName    string  `env:"OTEL_SERVICE_NAME" validate:"required"`
+	OTLPEnabled    bool    `env:"OTEL_EXPORTER_ENABLED"`                                // Export traces over OTLP/HTTP; spans are dropped otherwise
+	OTLPEndpoint   string  `env:"OTEL_EXPORTER_OTLP_ENDPOINT" validate:"omitempty,url"` // Collector URL, e.g. http://localhost:4318; the OTEL_EXPORTER_OTLP_* variables apply when empty
+	SampleRatio    float64 `env:"OTEL_TRACES_SAMPLE_RATIO" validate:"gte=0,lte=1"`      // Share of the traces started by the API that are kept
+	MetricsEnabled bool    `env:"METRICS_ENABLED"`                                      // Serve Prometheus metrics on /metrics
+}
+
+// JobsConfig stores how the background job queue is worked
+type JobsConfig struct {
+	RunInAPI     bool          `env:"JOBS_RUN_IN_API"`                    // Work the queue within the API server; turn off when running dedicated workers
+	PollInterval time.Duration `env:"JOBS_POLL_INTERVAL" validate:"gt=0"` // How often workers look for due jobs
+	MaxLag       time.Duration `env:"JOBS_MAX_LAG" validate:"gt=0"`       // How long a due job may wait before readiness reports the queue as lagging
+}
+
+// GraphQLConfig stores how the GraphQL endpoints describe themselves
+type GraphQLConfig struct {
+	Introspection bool `env:"GRAPHQL_INTROSPECTION"` // Answer queries for the schema and serve the sandbox; off in production by default
+}
+
+// defaults are the settings used when their variable is not set
+var defaults = map[string]any{
+	"APP_ENV":                     "development",
+	"APP_HOST":                    "0.0.0.0",
+	"APP_PORT":                    "8090",
+	"APP_PUBLIC_URL":              "http://localhost:8090",
+	"DB_HOST":                     "localhost",
+	"DB_PORT":                     "5432",
+	"DB_USER":                     "postgres",
+	"DB_PASSWORD":                 "postgres",
+	"DB_NAME":                     "beautix",
+	"DB_SSLMODE":                  "disable",
+	"DB_MONEY_MIGRATION_PHASE":    "dual_write",
+	"DB_MAX_OPEN_CONNS":           25,
+	"DB_MAX_IDLE_CONNS":           25,
+	"DB_CONN_MAX_LIFETIME":        "30m",
+	"DB_CONN_MAX_IDLE_TIME":       "5m",
+	"DB_STATEMENT_TIMEOUT":        "30s",
+	"JWT_SECRET":                  insecureJWTSecret,
+	"JWT_EXPIRATION":              "24h",
+	"CLERK_API_URL":               "https://api.clerk.com/v1",
+	"NOTIFICATION_EMAIL_PROVIDER": "log",
+	"NOTIFICATION_SMS_PROVIDER":   "log",
+	"SMTP_PORT":                   "587",
+	"WHATSAPP_API_URL":            "https://graph.facebook.com/v19.0",
+	"STRIPE_API_URL":              "https://api.stripe.com/v1",
+	"STORAGE_REGION_EU":           "eu-west-1",
+	"STORAGE_REGION_US":           "us-east-1",
+	"STORAGE_URL_EXPIRY":          "15m",
+	"OTEL_SERVICE_NAME":           "beautix-api",
+	"OTEL_EXPORTER_ENABLED":       false,
+	"OTEL_TRACES_SAMPLE_RATIO":    1.0,
+	"METRICS_ENABLED":             true,
+	"JOBS_RUN_IN_API":             true,
+	"JOBS_POLL_INTERVAL":          "5s",
+	"JOBS_MAX_LAG":                "5m",
+	"GRAPHQL_INTROSPECTION":       true,
+}
+
+// environmentDefaults override the defaults in an environment. Variables that are set still win.
+var environmentDefaults = map[string]map[string]any{
+	"testing": {
+		"DB_NAME": "beautix_test",
+	},
+	"staging": {
+		"DB_SSLMODE": "require",
+	},
+	"production": {
+		// Production databases are reached over TLS, its secrets must be set, and the schema is
+		// not given away unless asked for
+		"DB_SSLMODE":            "require",
+		"DB_PASSWORD":           "",
+		"JWT_SECRET":            "",
+		"GRAPHQL_INTROSPECTION": false,
+	},
+}
+
+// insecureJWTSecret is the JWT secret of development, which production refuses
+const insecureJWTSecret = "change_this_to_a_secure_secret_in_production"
+
+// LoadConfig reads configuration from environment variables, which the .env files of the
+// environment may set (see LoadEnvFiles). Every setting is checked, and a *ValidationError lists
+// all of those that are missing or invalid at once.
 func LoadConfig() (*Config, error) {
-	// Set default values
-	viper.SetDefault("APP_ENV", "development")
-	viper.SetDefault("APP_HOST", "0.0.0.0")
-	viper.SetDefault("APP_PORT", "8090")
-	viper.SetDefault("DB_HOST", "localhost")
-	viper.SetDefault("DB_PORT", "5432")
-	viper.SetDefault("DB_USER", "postgres")
-	viper.SetDefault("DB_PASSWORD", "postgres")
-	viper.SetDefault("DB_NAME", "beautix")
-	viper.SetDefault("DB_SSLMODE", "disable")
-	viper.SetDefault("JWT_SECRET", "change_this_to_a_secure_secret_in_production")
-	viper.SetDefault("JWT_EXPIRATION", "24h")
-	viper.SetDefault("CLERK_SECRET_KEY", "")
-	viper.SetDefault("CLERK_PUBLISHABLE_KEY", "")
-
-	// Set environment variable prefix
-	viper.SetEnvPrefix("")
-
-	// Read from environment variables
-	viper.AutomaticEnv()
-
-	// Create config
-	config := &Config{
-		Environment: viper.GetString("APP_ENV"),
-		App: AppConfig{
-			Host: viper.GetString("APP_HOST"),
-			Port: viper.GetString("APP_PORT"),
-		},
-		Database: DatabaseConfig{
-			Host:     viper.GetString("DB_HOST"),
-			Port:     viper.GetString("DB_PORT"),
-			User:     viper.GetString("DB_USER"),
-			Password: viper.GetString("DB_PASSWORD"),
-			DBName:   viper.GetString("DB_NAME"),
-			SSLMode:  viper.GetString("DB_SSLMODE"),
-		},
-		Auth: AuthConfig{
-			Secret: viper.GetString("JWT_SECRET"),
-			ClerkSecretKey: viper.GetString("CLERK_SECRET_KEY"),
-			ClerkPublishableKey: viper.GetString("CLERK_PUBLISHABLE_KEY"),
-		},
+	v := viper.New()
+	for key, value := range defaults {
+		v.SetDefault(key, value)
+	}
+	v.AutomaticEnv()
+	for key, value := range environmentDefaults[v.GetString("APP_ENV")] {
+		v.SetDefault(key, value)
 	}
 
+	config := &Config{}
+	problems := decode(v, reflect.ValueOf(config).Elem())
+
 	// Set database URL
 	config.Database.URL = fmt.Sprintf(
 		"postgres://%s:%s@%s:%s/%s?sslmode=%s",
@@ -96,13 +232,10 @@ func LoadConfig() (*Config, error) {
 		config.Database.SSLMode,
 	)
 
-	// Parse JWT expiration duration
-	expiration, err := time.ParseDuration(viper.GetString("JWT_EXPIRATION"))
-	if err != nil {
-		return nil, fmt.Errorf("invalid JWT_EXPIRATION: %w", err)
+	problems = append(problems, config.validate(problems)...)
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
 	}
-	config.Auth.Expiration = expiration
-
 	return config, nil
 }
 
@@ -120,3 +253,25 @@ func (c *Config) IsProduction() bool {
 func (c *Config) IsTesting() bool {
 	return c.Environment == "testing"
 }
+
+// ReviewBlockedWordList returns the words that hold reviews and replies back
+func (c AppConfig) ReviewBlockedWordList() []string {
+	var words []string
+	for _, word := range strings.Split(c.ReviewBlockedWords, ",") {
+		if word = strings.TrimSpace(word); word != "" {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// PlatformAdminIDs returns the Clerk user IDs of the platform operators
+func (c AuthConfig) PlatformAdminIDs() []string {
+	var ids []string
+	for _, id := range strings.Split(c.PlatformAdmins, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
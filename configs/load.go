@@ -0,0 +1,128 @@
+package configs
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/spf13/viper"
+)
+
+// secretFileSuffix is appended to the variable of a secret to name the file it is read from
+// instead, as Docker and Kubernetes mount secrets
+const secretFileSuffix = "_FILE"
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// LoadEnvFiles sets the variables of the .env file of the environment, .env.<APP_ENV>, and then
+// those of the .env file, leaving variables already set alone. The environment is taken from
+// APP_ENV when set, or else from the .env file. Missing files are skipped.
+func LoadEnvFiles() error {
+	environment := os.Getenv("APP_ENV")
+	if environment == "" {
+		if values, err := godotenv.Read(".env"); err == nil {
+			environment = values["APP_ENV"]
+		}
+	}
+	if environment == "" {
+		environment = defaults["APP_ENV"].(string)
+	}
+	for _, file := range []string{".env." + environment, ".env"} {
+		if err := godotenv.Load(file); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to load %s: %w", file, err)
+		}
+	}
+	return nil
+}
+
+// decode sets the fields of a config struct from the variables in their env tags, recursing
+// into nested structs, and reports the values that cannot be parsed
+func decode(v *viper.Viper, config reflect.Value) []Problem {
+	var problems []Problem
+	for i := 0; i < config.NumField(); i++ {
+		field, value := config.Type().Field(i), config.Field(i)
+		key := field.Tag.Get("env")
+		if key == "" {
+			if field.Type.Kind() == reflect.Struct {
+				problems = append(problems, decode(v, value)...)
+			}
+			continue
+		}
+
+		raw := v.GetString(key)
+		if field.Tag.Get("secret") == "true" {
+			secret, problem := readSecret(key, raw)
+			if problem != nil {
+				problems = append(problems, *problem)
+				continue
+			}
+			raw = secret
+		}
+		if problem := set(value, key, raw); problem != nil {
+			problems = append(problems, *problem)
+		}
+	}
+	return problems
+}
+
+// readSecret returns a secret, reading it from the file named by its _FILE variable when set.
+// The line break files usually end with is dropped.
+func readSecret(key, value string) (string, *Problem) {
+	path := os.Getenv(key + secretFileSuffix)
+	if path == "" {
+		return value, nil
+	}
+	if _, set := os.LookupEnv(key); set {
+		return "", &Problem{Key: key, Message: fmt.Sprintf("set either %s or %s, not both", key, key+secretFileSuffix)}
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", &Problem{Key: key + secretFileSuffix, Message: fmt.Sprintf("cannot read the secret file: %v", err)}
+	}
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
+// set parses a variable into a field of its type
+func set(field reflect.Value, key, raw string) *Problem {
+	raw = strings.TrimSpace(raw)
+	switch {
+	case field.Type() == durationType:
+		duration, err := time.ParseDuration(raw)
+		if err != nil {
+			return &Problem{Key: key, Message: fmt.Sprintf("must be a duration such as 30s or 24h, not %q", raw)}
+		}
+		field.SetInt(int64(duration))
+	case field.Kind() == reflect.String:
+		field.SetString(raw)
+	case field.Kind() == reflect.Bool:
+		if raw == "" {
+			return nil
+		}
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return &Problem{Key: key, Message: fmt.Sprintf("must be true or false, not %q", raw)}
+		}
+		field.SetBool(value)
+	case field.Kind() == reflect.Int:
+		value, err := strconv.Atoi(raw)
+		if err != nil {
+			return &Problem{Key: key, Message: fmt.Sprintf("must be a whole number, not %q", raw)}
+		}
+		field.SetInt(int64(value))
+	case field.Kind() == reflect.Float64:
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return &Problem{Key: key, Message: fmt.Sprintf("must be a number, not %q", raw)}
+		}
+		field.SetFloat(value)
+	default:
+		panic(fmt.Sprintf("configs: %s has an unsupported type %s", key, field.Type()))
+	}
+	return nil
+}
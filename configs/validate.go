@@ -0,0 +1,153 @@
+package configs
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Problem is a setting that is missing or invalid
+type Problem struct {
+	Key     string // The environment variable of the setting
+	Message string
+}
+
+// ValidationError lists every setting that is missing or invalid, so that all of them can be
+// fixed before starting again
+type ValidationError struct {
+	Problems []Problem
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, len(e.Problems))
+	for i, problem := range e.Problems {
+		lines[i] = problem.Key + ": " + problem.Message
+	}
+	return "invalid configuration: " + strings.Join(lines, "; ")
+}
+
+// validate checks the settings against the rules of their validate tags, then the settings
+// that are only required together with others. Settings that could not be parsed are not
+// checked again.
+func (c *Config) validate(parsed []Problem) []Problem {
+	reported := map[string]bool{}
+	for _, problem := range parsed {
+		reported[problem.Key] = true
+	}
+	var problems []Problem
+	report := func(key, message string) {
+		if !reported[key] {
+			reported[key] = true
+			problems = append(problems, Problem{Key: key, Message: message})
+		}
+	}
+
+	if err := newValidator().Struct(c); err != nil {
+		fieldErrors, ok := err.(validator.ValidationErrors)
+		if !ok {
+			return []Problem{{Key: "config", Message: err.Error()}}
+		}
+		for _, fieldError := range fieldErrors {
+			report(fieldError.Field(), describe(fieldError))
+		}
+	}
+
+	if c.Database.MaxIdleConns > c.Database.MaxOpenConns && !reported["DB_MAX_OPEN_CONNS"] {
+		report("DB_MAX_IDLE_CONNS", "must be at most DB_MAX_OPEN_CONNS")
+	}
+
+	notification := c.Notification
+	if notification.EmailProvider == "smtp" {
+		requireSettings(report, "for the smtp email provider", map[string]string{
+			"SMTP_HOST": notification.SMTP.Host,
+			"SMTP_FROM": notification.SMTP.From,
+		})
+	}
+	switch notification.SMSProvider {
+	case "twilio":
+		requireSettings(report, "for the twilio SMS provider", map[string]string{
+			"TWILIO_ACCOUNT_SID": notification.Twilio.AccountSID,
+			"TWILIO_AUTH_TOKEN":  notification.Twilio.AuthToken,
+			"TWILIO_FROM_NUMBER": notification.Twilio.FromNumber,
+		})
+	case "whatsapp":
+		requireSettings(report, "for the whatsapp SMS provider", map[string]string{
+			"WHATSAPP_PHONE_NUMBER_ID": notification.WhatsApp.PhoneNumberID,
+			"WHATSAPP_ACCESS_TOKEN":    notification.WhatsApp.AccessToken,
+		})
+	}
+	if c.Stripe.ProPriceID != "" || c.Stripe.PremiumPriceID != "" {
+		requireSettings(report, "to sell the paid tiers", map[string]string{
+			"STRIPE_SECRET_KEY":     c.Stripe.SecretKey,
+			"STRIPE_WEBHOOK_SECRET": c.Stripe.WebhookSecret,
+		})
+	}
+	if (c.Storage.AccessKeyID == "") != (c.Storage.SecretAccessKey == "") {
+		report("STORAGE_SECRET_ACCESS_KEY", "must be set together with STORAGE_ACCESS_KEY_ID")
+	}
+	if c.IsProduction() {
+		if c.Auth.Secret == insecureJWTSecret {
+			report("JWT_SECRET", "must be changed from the development secret in production")
+		}
+		requireSettings(report, "in production", map[string]string{"DB_PASSWORD": c.Database.Password})
+	}
+
+	slices.SortStableFunc(problems, func(a, b Problem) int { return strings.Compare(a.Key, b.Key) })
+	return problems
+}
+
+// requireSettings reports the settings of a map that are empty as required for a reason
+func requireSettings(report func(key, message string), reason string, settings map[string]string) {
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		if settings[key] == "" {
+			report(key, "is required "+reason)
+		}
+	}
+}
+
+// newValidator returns a validator naming fields by their environment variable
+func newValidator() *validator.Validate {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		return field.Tag.Get("env")
+	})
+	validate.RegisterValidation("port", func(fl validator.FieldLevel) bool {
+		port, err := strconv.Atoi(fl.Field().String())
+		return err == nil && port > 0 && port <= 65535
+	})
+	return validate
+}
+
+// describe explains a failed rule of a setting
+func describe(err validator.FieldError) string {
+	switch err.Tag() {
+	case "required":
+		return "is required"
+	case "oneof":
+		return fmt.Sprintf("must be one of %s, not %q", strings.Join(strings.Fields(err.Param()), ", "), err.Value())
+	case "url":
+		return fmt.Sprintf("must be an absolute URL, not %q", err.Value())
+	case "email":
+		return fmt.Sprintf("must be an email address, not %q", err.Value())
+	case "port":
+		return fmt.Sprintf("must be a port number from 1 to 65535, not %q", err.Value())
+	case "min":
+		return fmt.Sprintf("must be at least %s characters long", err.Param())
+	case "gt":
+		return "must be greater than " + err.Param()
+	case "gte":
+		return "must be at least " + err.Param()
+	case "lte":
+		return "must be at most " + err.Param()
+	}
+	return "fails the " + err.Tag() + " rule"
+}
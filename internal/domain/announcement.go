@@ -0,0 +1,139 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// MaxAnnouncementTitleLength bounds the length of an announcement title
+const MaxAnnouncementTitleLength = 200
+
+// Announcement is a product update the platform posts to the businesses using it, shown in
+// their in-app feed instead of being emailed. An announcement targets the businesses on the
+// given subscription plans whose users read the app in the given locales; an empty list
+// targets everyone.
+type Announcement struct {
+	BaseModel
+	Title     string     `gorm:"not null;size:200" json:"title"`
+	Body      string     `gorm:"not null;type:text" json:"body"`                   // Markdown
+	Link      *string    `gorm:"size:500" json:"link,omitempty"`                   // Where to read more
+	Plans     *string    `gorm:"type:jsonb;default:'[]'" json:"plans,omitempty"`   // JSON list of subscription tiers
+	Locales   *string    `gorm:"type:jsonb;default:'[]'" json:"locales,omitempty"` // JSON list of locales, e.g. ["pt", "en-GB"]
+	PublishAt time.Time  `gorm:"not null;index" json:"publish_at"`                 // Hidden from the feed until then
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`                             // Hidden from the feed from then on
+}
+
+// TableName returns the table name for Announcement
+func (Announcement) TableName() string { return "announcements" }
+
+// Validate validates the announcement model
+func (a *Announcement) Validate() error {
+	if strings.TrimSpace(a.Title) == "" {
+		return fmt.Errorf("%w: title is required", ErrValidation)
+	}
+	if len(a.Title) > MaxAnnouncementTitleLength {
+		return fmt.Errorf("%w: title must be at most %d characters", ErrValidation, MaxAnnouncementTitleLength)
+	}
+	if strings.TrimSpace(a.Body) == "" {
+		return fmt.Errorf("%w: body is required", ErrValidation)
+	}
+	if a.PublishAt.IsZero() {
+		return fmt.Errorf("%w: publish time is required", ErrValidation)
+	}
+	if a.ExpiresAt != nil && !a.ExpiresAt.After(a.PublishAt) {
+		return fmt.Errorf("%w: an announcement must expire after it is published", ErrValidation)
+	}
+	return nil
+}
+
+// GetPlans decodes the subscription tiers the announcement targets
+func (a *Announcement) GetPlans() ([]string, error) {
+	return decodeIDs(a.Plans)
+}
+
+// SetPlans encodes the subscription tiers the announcement targets
+func (a *Announcement) SetPlans(plans []string) error {
+	encoded, err := encodeIDs(normalizeTargets(plans))
+	if err != nil {
+		return err
+	}
+	a.Plans = encoded
+	return nil
+}
+
+// GetLocales decodes the locales the announcement targets
+func (a *Announcement) GetLocales() ([]string, error) {
+	return decodeIDs(a.Locales)
+}
+
+// SetLocales encodes the locales the announcement targets
+func (a *Announcement) SetLocales(locales []string) error {
+	encoded, err := encodeIDs(normalizeTargets(locales))
+	if err != nil {
+		return err
+	}
+	a.Locales = encoded
+	return nil
+}
+
+// IsLive reports whether the announcement is shown in the feed at a time
+func (a *Announcement) IsLive(now time.Time) bool {
+	return !now.Before(a.PublishAt) && (a.ExpiresAt == nil || now.Before(*a.ExpiresAt))
+}
+
+// Targets reports whether the announcement is meant for a business on a subscription plan
+// whose user reads the app in a locale. A targeted language matches every regional variant,
+// so "pt" matches "pt-BR" while "pt-BR" does not match "pt-PT".
+func (a *Announcement) Targets(plan, locale string) bool {
+	plans, _ := a.GetPlans()
+	if len(plans) > 0 && !slices.Contains(plans, strings.ToLower(plan)) {
+		return false
+	}
+
+	locales, _ := a.GetLocales()
+	if len(locales) == 0 {
+		return true
+	}
+	locale = strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+	language, _, _ := strings.Cut(locale, "-")
+	return slices.Contains(locales, locale) || slices.Contains(locales, language)
+}
+
+// normalizeTargets lower-cases the targeted plans or locales, dropping blanks and duplicates
+func normalizeTargets(values []string) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		value = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(value), "_", "-"))
+		if value != "" && !slices.Contains(result, value) {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// AnnouncementRead records that a user read an announcement
+type AnnouncementRead struct {
+	AnnouncementID string    `gorm:"primaryKey;type:uuid" json:"announcement_id"`
+	UserID         string    `gorm:"primaryKey;type:uuid" json:"user_id"`
+	ReadAt         time.Time `gorm:"not null" json:"read_at"`
+}
+
+// TableName returns the table name for AnnouncementRead
+func (AnnouncementRead) TableName() string { return "announcement_reads" }
+
+// AnnouncementRepository defines the repository interface for announcements and the reads
+// tracked for each user
+type AnnouncementRepository interface {
+	BaseRepository[Announcement]
+	// FindAll finds every announcement, including scheduled and expired ones, latest first
+	FindAll(ctx context.Context, page, pageSize int) ([]*Announcement, int64, error)
+	// FindLive finds the announcements live at a time, most recently published first
+	FindLive(ctx context.Context, now time.Time) ([]*Announcement, error)
+	// FindReads finds when a user read the given announcements, by announcement ID
+	FindReads(ctx context.Context, userID string, announcementIDs []string) (map[string]time.Time, error)
+	// MarkRead records that a user read announcements, returning how many were unread
+	MarkRead(ctx context.Context, userID string, announcementIDs []string, at time.Time) (int64, error)
+}
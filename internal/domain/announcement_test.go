@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnnouncement_Validate(t *testing.T) {
+	publishAt := time.Date(2026, time.October, 17, 9, 0, 0, 0, time.UTC)
+	announcement := &Announcement{Title: "Deposits are here", Body: "Ask for a deposit when clients book online.", PublishAt: publishAt}
+	assert.NoError(t, announcement.Validate())
+
+	untitled := *announcement
+	untitled.Title = " "
+	assert.ErrorIs(t, untitled.Validate(), ErrValidation)
+
+	expired := *announcement
+	expired.ExpiresAt = &publishAt
+	assert.ErrorIs(t, expired.Validate(), ErrValidation)
+}
+
+func TestAnnouncement_IsLive(t *testing.T) {
+	publishAt := time.Date(2026, time.October, 17, 9, 0, 0, 0, time.UTC)
+	expiresAt := publishAt.Add(7 * 24 * time.Hour)
+	announcement := &Announcement{PublishAt: publishAt, ExpiresAt: &expiresAt}
+
+	assert.False(t, announcement.IsLive(publishAt.Add(-time.Minute)), "scheduled")
+	assert.True(t, announcement.IsLive(publishAt))
+	assert.False(t, announcement.IsLive(expiresAt), "expired")
+
+	announcement.ExpiresAt = nil
+	assert.True(t, announcement.IsLive(publishAt.Add(365*24*time.Hour)))
+}
+
+func TestAnnouncement_Targets(t *testing.T) {
+	announcement := &Announcement{}
+	require.NoError(t, announcement.SetPlans(nil))
+	require.NoError(t, announcement.SetLocales(nil))
+	assert.True(t, announcement.Targets("free", "en"), "empty lists target everyone")
+
+	require.NoError(t, announcement.SetPlans([]string{" Pro ", "premium", "pro"}))
+	plans, err := announcement.GetPlans()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pro", "premium"}, plans)
+	assert.True(t, announcement.Targets("pro", "en"))
+	assert.False(t, announcement.Targets("free", "en"))
+
+	require.NoError(t, announcement.SetLocales([]string{"pt", "en_GB"}))
+	assert.True(t, announcement.Targets("pro", "pt-BR"), "a language targets all its regions")
+	assert.True(t, announcement.Targets("pro", "en-GB"))
+	assert.False(t, announcement.Targets("pro", "en-US"))
+	assert.False(t, announcement.Targets("pro", "en"))
+}
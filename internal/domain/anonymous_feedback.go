@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// FeedbackTokenValidity is how long after it is issued a feedback link can be used
+	FeedbackTokenValidity = 30 * 24 * time.Hour
+	// MaxFeedbackMessageLength bounds the length of an anonymous feedback message in characters
+	MaxFeedbackMessageLength = 2000
+)
+
+var (
+	// ErrFeedbackTokenUsed is returned when feedback was already sent with a token
+	ErrFeedbackTokenUsed = errors.New("feedback has already been sent for this appointment")
+	// ErrFeedbackTokenExpired is returned when a feedback token is past its validity
+	ErrFeedbackTokenExpired = errors.New("this feedback link has expired")
+)
+
+// FeedbackToken lets the client of a completed appointment send anonymous feedback once. The
+// token records whether it was used but not when, so it cannot be matched to the feedback.
+type FeedbackToken struct {
+	BaseModel
+	BusinessID    string    `gorm:"not null;type:uuid;index" json:"business_id"`
+	AppointmentID string    `gorm:"not null;type:uuid;uniqueIndex" json:"appointment_id"`
+	Token         string    `gorm:"not null;size:64;uniqueIndex" json:"-"`
+	ExpiresAt     time.Time `gorm:"not null" json:"expires_at"`
+	Used          bool      `gorm:"not null;default:false" json:"used"`
+}
+
+// TableName returns the table name for FeedbackToken
+func (FeedbackToken) TableName() string { return "feedback_tokens" }
+
+// IsExpired reports whether the token can no longer be used
+func (t *FeedbackToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
+
+// CheckUsable returns why feedback cannot be sent with the token, if it cannot
+func (t *FeedbackToken) CheckUsable(now time.Time) error {
+	if t.Used {
+		return ErrFeedbackTokenUsed
+	}
+	if t.IsExpired(now) {
+		return ErrFeedbackTokenExpired
+	}
+	return nil
+}
+
+// AnonymousFeedback is feedback a client sent to a business without identifying themselves.
+// It is linked to the business only: it has no client, appointment or token, and records the
+// day it was sent rather than the time, so it cannot be traced back to a visit.
+type AnonymousFeedback struct {
+	ID          string         `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	BusinessID  string         `gorm:"not null;type:uuid;index" json:"business_id"`
+	Rating      *int           `json:"rating,omitempty"` // 1 to 5 stars, when given
+	Message     string         `gorm:"not null;type:text" json:"message"`
+	SubmittedOn time.Time      `gorm:"not null;type:date" json:"submitted_on"`
+	ReadAt      *time.Time     `json:"read_at,omitempty"`
+	UpdatedAt   *time.Time     `json:"updated_at,omitempty"`
+	UpdatedBy   *string        `gorm:"type:uuid" json:"updated_by,omitempty"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+	DeletedBy   *string        `gorm:"type:uuid" json:"deleted_by,omitempty"`
+}
+
+// TableName returns the table name for AnonymousFeedback
+func (AnonymousFeedback) TableName() string { return "anonymous_feedback" }
+
+// NewAnonymousFeedback validates feedback sent to a business on a day
+func NewAnonymousFeedback(businessID string, rating *int, message string, now time.Time) (*AnonymousFeedback, error) {
+	message = strings.TrimSpace(message)
+	if message == "" {
+		return nil, fmt.Errorf("%w: message is required", ErrValidation)
+	}
+	if utf8.RuneCountInString(message) > MaxFeedbackMessageLength {
+		return nil, fmt.Errorf("%w: message must be at most %d characters", ErrValidation, MaxFeedbackMessageLength)
+	}
+	if rating != nil && (*rating < 1 || *rating > 5) {
+		return nil, fmt.Errorf("%w: rating must be between 1 and 5", ErrValidation)
+	}
+
+	year, month, day := now.UTC().Date()
+	return &AnonymousFeedback{
+		BusinessID:  businessID,
+		Rating:      rating,
+		Message:     message,
+		SubmittedOn: time.Date(year, month, day, 0, 0, 0, 0, time.UTC),
+	}, nil
+}
+
+// AnonymousFeedbackRepository defines the repository interface for anonymous feedback and the
+// tokens it is sent with
+type AnonymousFeedbackRepository interface {
+	FindTokenByAppointment(ctx context.Context, appointmentID string) (*FeedbackToken, error)
+	FindTokenByToken(ctx context.Context, token string) (*FeedbackToken, error)
+	// SaveToken creates a token or replaces an expired one
+	SaveToken(ctx context.Context, token *FeedbackToken) error
+	// Submit uses the token and stores the feedback in a single transaction, returning
+	// ErrFeedbackTokenUsed when the token was used concurrently
+	Submit(ctx context.Context, tokenID string, feedback *AnonymousFeedback) error
+	FindByBusiness(ctx context.Context, businessID string, unreadOnly bool, page, pageSize int) ([]*AnonymousFeedback, int64, error)
+	MarkRead(ctx context.Context, businessID string, ids []string, at time.Time, by *string) (int64, error)
+}
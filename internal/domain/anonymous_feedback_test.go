@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAnonymousFeedback(t *testing.T) {
+	now := time.Date(2026, time.October, 17, 18, 42, 7, 0, time.UTC)
+	rating := 4
+
+	feedback, err := NewAnonymousFeedback("business-1", &rating, "  The music was too loud  ", now)
+	require.NoError(t, err)
+	assert.Equal(t, "The music was too loud", feedback.Message)
+	assert.Equal(t, time.Date(2026, time.October, 17, 0, 0, 0, 0, time.UTC), feedback.SubmittedOn,
+		"only the day is kept so the feedback cannot be matched to a visit")
+
+	_, err = NewAnonymousFeedback("business-1", nil, "   ", now)
+	assert.ErrorIs(t, err, ErrValidation)
+
+	_, err = NewAnonymousFeedback("business-1", nil, strings.Repeat("a", MaxFeedbackMessageLength+1), now)
+	assert.ErrorIs(t, err, ErrValidation)
+
+	rating = 6
+	_, err = NewAnonymousFeedback("business-1", &rating, "Great", now)
+	assert.ErrorIs(t, err, ErrValidation)
+}
+
+func TestFeedbackToken_CheckUsable(t *testing.T) {
+	now := time.Date(2026, time.October, 17, 9, 0, 0, 0, time.UTC)
+	token := &FeedbackToken{ExpiresAt: now.Add(time.Hour)}
+	assert.NoError(t, token.CheckUsable(now))
+	assert.ErrorIs(t, token.CheckUsable(now.Add(time.Hour)), ErrFeedbackTokenExpired)
+
+	token.Used = true
+	assert.ErrorIs(t, token.CheckUsable(now), ErrFeedbackTokenUsed)
+}
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"fmt"
 	"time"
 	"github.com/shopspring/decimal"
 )
@@ -10,6 +11,7 @@ import (
 type AppointmentStatus string
 
 const (
+	AppointmentStatusPending   AppointmentStatus = "pending" // Requested online, awaiting acceptance by the business
 	AppointmentStatusScheduled AppointmentStatus = "scheduled"
 	AppointmentStatusConfirmed AppointmentStatus = "confirmed"
 	AppointmentStatusInProgress AppointmentStatus = "in_progress"
@@ -27,14 +29,19 @@ type Appointment struct {
 	StaffID         string            `gorm:"not null;type:uuid;index" json:"staff_id"`
 	StartTime       time.Time         `gorm:"not null;index" json:"start_time"`
 	EndTime         time.Time         `gorm:"not null;index" json:"end_time"`
-	Status          AppointmentStatus `gorm:"not null;size:20;default:'scheduled';check:status IN ('scheduled','confirmed','in_progress','completed','cancelled','no_show','rescheduled')" json:"status"`
+	Status          AppointmentStatus `gorm:"not null;size:20;default:'scheduled';check:status IN ('pending','scheduled','confirmed','in_progress','completed','cancelled','no_show','rescheduled')" json:"status"`
 	Title           *string           `gorm:"size:200" json:"title,omitempty"`
 	Notes           *string           `gorm:"type:text" json:"notes,omitempty"`
 	InternalNotes   *string           `gorm:"type:text" json:"internal_notes,omitempty"`
 	CancellationReason *string        `gorm:"type:text" json:"cancellation_reason,omitempty"`
+	BundleID        *string           `gorm:"type:uuid" json:"bundle_id,omitempty"` // The service bundle the appointment was booked from
+	EstimatedPrice  *decimal.Decimal  `gorm:"type:decimal(10,2)" json:"estimated_price,omitempty"`
+	BroadcastID     *string           `gorm:"type:uuid" json:"broadcast_id,omitempty"` // The emergency broadcast about a disruption affecting the appointment
 	TotalPrice      decimal.Decimal   `gorm:"type:decimal(10,2);not null;default:0" json:"total_price"`
 	DepositPaid     decimal.Decimal   `gorm:"type:decimal(10,2);not null;default:0" json:"deposit_paid"`
+	NoShowFee       *decimal.Decimal  `gorm:"type:decimal(10,2)" json:"no_show_fee,omitempty"` // Charged for a no-show or late cancellation
 	ReminderSent    bool              `gorm:"not null;default:false" json:"reminder_sent"`
+	ClientConfirmed bool              `gorm:"default:false" json:"client_confirmed"`
 	ConfirmedAt     *time.Time        `gorm:"" json:"confirmed_at,omitempty"`
 	CompletedAt     *time.Time        `gorm:"" json:"completed_at,omitempty"`
 	CancelledAt     *time.Time        `gorm:"" json:"cancelled_at,omitempty"`
@@ -94,7 +101,21 @@ func (a *Appointment) IsPast() bool {
 
 // CanBeCancelled returns true if the appointment can be cancelled
 func (a *Appointment) CanBeCancelled() bool {
-	return a.Status == AppointmentStatusScheduled || a.Status == AppointmentStatusConfirmed
+	return a.Status == AppointmentStatusPending || a.Status == AppointmentStatusScheduled || a.Status == AppointmentStatusConfirmed
+}
+
+// IsPending returns true if the appointment was requested online and has not been accepted yet
+func (a *Appointment) IsPending() bool {
+	return a.Status == AppointmentStatusPending
+}
+
+// Accept accepts an appointment requested online, booking it like any other
+func (a *Appointment) Accept() error {
+	if !a.IsPending() {
+		return fmt.Errorf("%w: only pending appointments can be accepted", ErrValidation)
+	}
+	a.Status = AppointmentStatusScheduled
+	return nil
 }
 
 // CanBeCompleted returns true if the appointment can be marked as completed
@@ -129,6 +150,11 @@ type AppointmentRepository interface {
 	GetDashboardData(ctx context.Context, businessID string, date time.Time) (*DashboardData, error)
 	GetCalendarView(ctx context.Context, businessID string, start, end time.Time) ([]*CalendarAppointment, error)
 	GetByStatus(ctx context.Context, businessID string, status AppointmentStatus) ([]*Appointment, error)
+	CheckAvailability(ctx context.Context, request AvailabilityRequest) ([]*AvailabilityConflict, error)
+	// FindSchedules finds what occupies the time of each of the staff members between start and end
+	FindSchedules(ctx context.Context, staffIDs []string, start, end time.Time) ([]*StaffSchedule, error)
+	// CreateWithLines creates an appointment together with the services performed during it
+	CreateWithLines(ctx context.Context, appointment *Appointment, lines []*AppointmentLine) error
 }
 
 // Helper types for repository methods
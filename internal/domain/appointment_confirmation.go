@@ -0,0 +1,208 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ConfirmationStatus represents the state of an appointment confirmation request
+type ConfirmationStatus string
+
+const (
+	ConfirmationStatusPending   ConfirmationStatus = "pending"
+	ConfirmationStatusConfirmed ConfirmationStatus = "confirmed"
+	ConfirmationStatusFailed    ConfirmationStatus = "failed" // The request could not be delivered
+)
+
+// ConfirmationSource represents how a client confirmed an appointment
+type ConfirmationSource string
+
+const (
+	ConfirmationSourceLink  ConfirmationSource = "link"  // The client opened the link in the request
+	ConfirmationSourceSMS   ConfirmationSource = "sms"   // The client replied to the SMS with a confirmation keyword
+	ConfirmationSourceStaff ConfirmationSource = "staff" // Staff confirmed on the client's behalf, e.g. after calling them
+)
+
+// ConfirmationPurpose is the message template purpose used for confirmation requests
+const ConfirmationPurpose = "confirmation"
+
+// DefaultConfirmationLeadHours is how long before an appointment the confirmation request
+// is sent when the business has no settings
+const DefaultConfirmationLeadHours = 24
+
+// DefaultConfirmationCutoffHours is how long a client has to acknowledge a confirmation request
+// before it is escalated when the business has no settings, and MaxConfirmationCutoffHours
+// bounds the setting
+const (
+	DefaultConfirmationCutoffHours = 4
+	MaxConfirmationCutoffHours     = 72
+)
+
+// DefaultConfirmationSMS and DefaultConfirmationEmail are used when the business has no
+// active confirmation template for the channel
+const (
+	DefaultConfirmationSMS          = "Hi {{client.first_name}}, please confirm your appointment at {{business.name}} on {{appointment.date}} at {{appointment.time}} by replying YES or opening {{confirmation.link}}"
+	DefaultConfirmationEmailSubject = "Please confirm your appointment at {{business.name}}"
+	DefaultConfirmationEmail        = "Hi {{client.first_name}},\n\nYou have an appointment at {{business.name}} on {{appointment.date}} at {{appointment.time}} for {{appointment.services}}.\n\nPlease confirm you are coming: {{confirmation.link}}\n\nSee you soon!"
+)
+
+// confirmationKeywords are the SMS replies accepted as a confirmation
+var confirmationKeywords = map[string]bool{
+	"YES": true, "Y": true, "SIM": true, "S": true, "CONFIRM": true, "CONFIRMO": true, "OK": true,
+}
+
+// AppointmentConfirmation is a request sent to a client to confirm an upcoming appointment. A
+// request the client does not acknowledge by the business's cutoff is escalated to the next
+// attempt of the appointment's chain, through a channel not tried yet; once every channel was
+// tried the front desk is given a task to call the client.
+type AppointmentConfirmation struct {
+	BaseModel
+	AppointmentID  string              `gorm:"not null;type:uuid;uniqueIndex:idx_appointment_confirmations_attempt" json:"appointment_id"`
+	Attempt        int                 `gorm:"not null;default:1;uniqueIndex:idx_appointment_confirmations_attempt" json:"attempt"` // 1 for the first request
+	BusinessID     string              `gorm:"not null;type:uuid" json:"business_id"`
+	ClientID       string              `gorm:"not null;type:uuid" json:"client_id"`
+	Channel        MessageChannel      `gorm:"not null;size:20" json:"channel"`
+	Recipient      string              `gorm:"not null;size:255" json:"recipient"`
+	Token          string              `gorm:"not null;size:64;uniqueIndex" json:"-"`
+	Status         ConfirmationStatus  `gorm:"not null;size:20;default:'pending'" json:"status"`
+	SentAt         time.Time           `gorm:"not null" json:"sent_at"`
+	RespondedAt    *time.Time          `json:"responded_at,omitempty"`
+	ResponseSource *ConfirmationSource `gorm:"size:20" json:"response_source,omitempty"`
+	DeliveredAt    *time.Time          `json:"delivered_at,omitempty"` // When the provider accepted the message
+	DeliveryError  *string             `gorm:"type:text" json:"delivery_error,omitempty"`
+	EscalatedAt    *time.Time          `json:"escalated_at,omitempty"` // When the next step of the chain was taken
+}
+
+// TableName returns the table name for AppointmentConfirmation
+func (AppointmentConfirmation) TableName() string { return "appointment_confirmations" }
+
+// RecordDelivery records the outcome of handing the request to its provider
+func (c *AppointmentConfirmation) RecordDelivery(err error, at time.Time) {
+	if err != nil {
+		message := err.Error()
+		c.Status = ConfirmationStatusFailed
+		c.DeliveryError = &message
+		return
+	}
+	c.DeliveredAt = &at
+}
+
+// IsDueForEscalation reports whether the request has gone unacknowledged past the cutoff, or
+// could not be delivered, and no further step has been taken yet
+func (c *AppointmentConfirmation) IsDueForEscalation(now time.Time, cutoffHours int) bool {
+	if c.EscalatedAt != nil || c.Status == ConfirmationStatusConfirmed || cutoffHours <= 0 {
+		return false
+	}
+	return c.Status == ConfirmationStatusFailed || !now.Before(c.SentAt.Add(time.Duration(cutoffHours)*time.Hour))
+}
+
+// NewConfirmationToken generates the unguessable token used in confirmation links
+func NewConfirmationToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate confirmation token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// IsConfirmationReply checks whether an SMS reply confirms the appointment. The reply is
+// matched on its first word, ignoring case and punctuation, so "Yes!" and "sim, obrigada" count.
+func IsConfirmationReply(text string) bool {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	if len(words) == 0 {
+		return false
+	}
+	return confirmationKeywords[strings.ToUpper(words[0])]
+}
+
+// NormalizePhone strips everything but digits from a phone number
+func NormalizePhone(phone string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, phone)
+}
+
+// ConfirmationCandidate is an upcoming appointment that is due a confirmation request
+type ConfirmationCandidate struct {
+	AppointmentID string    `json:"appointment_id"`
+	BusinessID    string    `json:"business_id"`
+	ClientID      string    `json:"client_id"`
+	StartTime     time.Time `json:"start_time"`
+	EndTime       time.Time `json:"end_time"`
+	ClientPhone   *string   `json:"client_phone,omitempty"`
+	ClientEmail   *string   `json:"client_email,omitempty"`
+}
+
+// Channel picks how to reach the client, preferring SMS so the client can reply YES.
+// It returns false when the client has no contact details.
+func (c ConfirmationCandidate) Channel() (MessageChannel, string, bool) {
+	return c.NextChannel(nil)
+}
+
+// NextChannel picks how to reach the client through a channel not tried yet, SMS first. It
+// returns false once the client cannot be reached through any other channel.
+func (c ConfirmationCandidate) NextChannel(tried []MessageChannel) (MessageChannel, string, bool) {
+	if c.ClientPhone != nil && len(NormalizePhone(*c.ClientPhone)) >= 6 && !slices.Contains(tried, MessageChannelSMS) {
+		return MessageChannelSMS, *c.ClientPhone, true
+	}
+	if c.ClientEmail != nil && strings.TrimSpace(*c.ClientEmail) != "" && !slices.Contains(tried, MessageChannelEmail) {
+		return MessageChannelEmail, strings.TrimSpace(*c.ClientEmail), true
+	}
+	return "", "", false
+}
+
+// EscalationCandidate is an upcoming, unconfirmed appointment whose latest confirmation request
+// is due to be escalated
+type EscalationCandidate struct {
+	ConfirmationCandidate
+	ConfirmationID string `json:"confirmation_id"` // The latest request of the chain
+	Attempt        int    `json:"attempt"`
+}
+
+// UnconfirmedAppointment is an upcoming appointment the client has not confirmed yet
+type UnconfirmedAppointment struct {
+	AppointmentID  string              `json:"appointment_id"`
+	StaffID        string              `json:"staff_id"`
+	ClientID       string              `json:"client_id"`
+	StartTime      time.Time           `json:"start_time"`
+	EndTime        time.Time           `json:"end_time"`
+	Status         AppointmentStatus   `json:"status"`
+	ClientName     string              `json:"client_name"`
+	ClientPhone    *string             `json:"client_phone,omitempty"`
+	ClientEmail    *string             `json:"client_email,omitempty"`
+	StaffName      string              `json:"staff_name"`
+	RequestStatus  *ConfirmationStatus `json:"request_status,omitempty"` // Nil when no request has been sent yet
+	RequestChannel *MessageChannel     `json:"request_channel,omitempty"`
+	RequestSentAt  *time.Time          `json:"request_sent_at,omitempty"`
+}
+
+// AppointmentConfirmationRepository defines the repository interface for AppointmentConfirmation
+type AppointmentConfirmationRepository interface {
+	BaseRepository[AppointmentConfirmation]
+	FindByToken(ctx context.Context, token string) (*AppointmentConfirmation, error)
+	FindPendingByPhone(ctx context.Context, phone string) (*AppointmentConfirmation, error)
+	FindDueForRequest(ctx context.Context, now time.Time, limit int) ([]*ConfirmationCandidate, error)
+	FindUnconfirmed(ctx context.Context, businessID string, start, end time.Time) ([]*UnconfirmedAppointment, error)
+	// ConfirmAppointment flags the appointment as confirmed, closes its pending requests and
+	// completes the open tasks to call the client about it
+	ConfirmAppointment(ctx context.Context, appointmentID string, source ConfirmationSource, at time.Time) error
+	// FindByAppointment finds the requests of an appointment's chain, first attempt first
+	FindByAppointment(ctx context.Context, appointmentID string) ([]*AppointmentConfirmation, error)
+	// FindDueForEscalation finds the upcoming, unconfirmed appointments whose latest request went
+	// unacknowledged past their business's cutoff or could not be delivered
+	FindDueForEscalation(ctx context.Context, now time.Time, limit int) ([]*EscalationCandidate, error)
+	// MarkEscalated records that the next step was taken for a request, returning
+	// gorm.ErrRecordNotFound when it had been escalated already
+	MarkEscalated(ctx context.Context, confirmationID string, at time.Time) error
+}
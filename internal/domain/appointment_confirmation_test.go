@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsConfirmationReply(t *testing.T) {
+	tests := []struct {
+		reply    string
+		expected bool
+	}{
+		{"YES", true},
+		{"yes", true},
+		{"  Yes!", true},
+		{"Sim, obrigada", true},
+		{"y", true},
+		{"OK see you then", true},
+		{"No", false},
+		{"Can we move it to 5pm?", false},
+		{"yesterday was great", false},
+		{"", false},
+		{"👍", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.reply, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsConfirmationReply(tt.reply))
+		})
+	}
+}
+
+func TestNormalizePhone(t *testing.T) {
+	assert.Equal(t, "351912345678", NormalizePhone("+351 912 345 678"))
+	assert.Equal(t, "912345678", NormalizePhone("(912) 345-678"))
+	assert.Equal(t, "", NormalizePhone("n/a"))
+}
+
+func TestConfirmationCandidateChannel(t *testing.T) {
+	phone := "+351 912 345 678"
+	shortPhone := "123"
+	email := " maria@example.com "
+
+	channel, recipient, ok := ConfirmationCandidate{ClientPhone: &phone, ClientEmail: &email}.Channel()
+	assert.True(t, ok)
+	assert.Equal(t, MessageChannelSMS, channel)
+	assert.Equal(t, phone, recipient)
+
+	channel, recipient, ok = ConfirmationCandidate{ClientPhone: &shortPhone, ClientEmail: &email}.Channel()
+	assert.True(t, ok)
+	assert.Equal(t, MessageChannelEmail, channel)
+	assert.Equal(t, "maria@example.com", recipient)
+
+	_, _, ok = ConfirmationCandidate{ClientPhone: &shortPhone}.Channel()
+	assert.False(t, ok)
+}
+
+func TestConfirmationCandidateNextChannel(t *testing.T) {
+	phone := "+351 912 345 678"
+	email := "maria@example.com"
+	candidate := ConfirmationCandidate{ClientPhone: &phone, ClientEmail: &email}
+
+	channel, recipient, ok := candidate.NextChannel([]MessageChannel{MessageChannelSMS})
+	assert.True(t, ok)
+	assert.Equal(t, MessageChannelEmail, channel)
+	assert.Equal(t, email, recipient)
+
+	_, _, ok = candidate.NextChannel([]MessageChannel{MessageChannelSMS, MessageChannelEmail})
+	assert.False(t, ok, "every channel was tried")
+
+	_, _, ok = ConfirmationCandidate{ClientPhone: &phone}.NextChannel([]MessageChannel{MessageChannelSMS})
+	assert.False(t, ok, "the client has no email address")
+}
+
+func TestAppointmentConfirmationEscalation(t *testing.T) {
+	sentAt := time.Date(2026, time.October, 17, 9, 0, 0, 0, time.UTC)
+	newRequest := func() *AppointmentConfirmation {
+		return &AppointmentConfirmation{Attempt: 1, Status: ConfirmationStatusPending, SentAt: sentAt}
+	}
+
+	t.Run("delivered requests escalate after the cutoff", func(t *testing.T) {
+		request := newRequest()
+		request.RecordDelivery(nil, sentAt)
+		assert.Equal(t, &sentAt, request.DeliveredAt)
+		assert.False(t, request.IsDueForEscalation(sentAt.Add(3*time.Hour), 4))
+		assert.True(t, request.IsDueForEscalation(sentAt.Add(4*time.Hour), 4))
+		assert.False(t, request.IsDueForEscalation(sentAt.Add(24*time.Hour), 0), "a cutoff of 0 disables escalation")
+	})
+
+	t.Run("undelivered requests escalate at once", func(t *testing.T) {
+		request := newRequest()
+		request.RecordDelivery(errors.New("invalid number"), sentAt)
+		assert.Equal(t, ConfirmationStatusFailed, request.Status)
+		assert.Nil(t, request.DeliveredAt)
+		assert.Equal(t, "invalid number", *request.DeliveryError)
+		assert.True(t, request.IsDueForEscalation(sentAt, 4))
+	})
+
+	t.Run("acknowledged or escalated requests do not escalate", func(t *testing.T) {
+		request := newRequest()
+		request.Status = ConfirmationStatusConfirmed
+		assert.False(t, request.IsDueForEscalation(sentAt.Add(24*time.Hour), 4))
+
+		request = newRequest()
+		request.EscalatedAt = &sentAt
+		assert.False(t, request.IsDueForEscalation(sentAt.Add(24*time.Hour), 4))
+	})
+}
@@ -0,0 +1,383 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+const (
+	// QuoteValidity is how long a quoted price is honored at booking
+	QuoteValidity = 30 * time.Minute
+	// MaxQuoteServices bounds the services and add-ons of a quote together
+	MaxQuoteServices = 10
+	// OfferTypeDiscount is the campaign offer type taking money off bookings
+	OfferTypeDiscount = "discount"
+	// RewardTypeDiscount is the loyalty reward type taking money off a booking for points
+	RewardTypeDiscount = "discount"
+)
+
+var (
+	// ErrQuoteUsed is returned when an appointment was booked with the quote already
+	ErrQuoteUsed = errors.New("the quote has already been used")
+	// ErrQuoteExpired is returned when a quote is past its validity
+	ErrQuoteExpired = errors.New("the quote has expired; request a new one")
+	// ErrQuoteMismatch is returned when a booking differs from what was quoted
+	ErrQuoteMismatch = errors.New("the booking does not match the quote")
+)
+
+// PromotionOffer is what a discount campaign takes off, decoded from its offer details. Either
+// a percentage or a fixed amount is taken off the services it covers, all of them when none
+// are listed.
+type PromotionOffer struct {
+	Percentage *decimal.Decimal `json:"percentage,omitempty"`
+	Amount     *decimal.Decimal `json:"amount,omitempty"`
+	ServiceIDs []string         `json:"service_ids,omitempty"`
+}
+
+// GetPromotionOffer decodes the offer of a discount campaign
+func (c *Campaign) GetPromotionOffer() (PromotionOffer, error) {
+	var offer PromotionOffer
+	if c.OfferType != OfferTypeDiscount {
+		return offer, fmt.Errorf("%w: campaign %q does not offer a discount", ErrValidation, c.Name)
+	}
+	if err := json.Unmarshal([]byte(c.OfferDetails), &offer); err != nil {
+		return offer, fmt.Errorf("%w: invalid offer details", ErrValidation)
+	}
+	if (offer.Percentage == nil) == (offer.Amount == nil) {
+		return offer, fmt.Errorf("%w: a discount needs either a percentage or an amount", ErrValidation)
+	}
+	if (offer.Percentage != nil && (!offer.Percentage.IsPositive() || offer.Percentage.GreaterThan(decimal.NewFromInt(100)))) ||
+		(offer.Amount != nil && !offer.Amount.IsPositive()) {
+		return offer, fmt.Errorf("%w: the discount is out of range", ErrValidation)
+	}
+	return offer, nil
+}
+
+// LoyaltyReward is what a discount reward takes off a booking for a number of points, decoded
+// from a loyalty program's reward value. Either a percentage or a fixed amount is taken off.
+type LoyaltyReward struct {
+	Points     int              `json:"points"`
+	Percentage *decimal.Decimal `json:"percentage,omitempty"`
+	Amount     *decimal.Decimal `json:"amount,omitempty"`
+}
+
+// GetReward decodes the discount reward of a loyalty program
+func (p *LoyaltyProgram) GetReward() (LoyaltyReward, error) {
+	var reward LoyaltyReward
+	if p.RewardType != RewardTypeDiscount {
+		return reward, fmt.Errorf("%w: program %q does not reward discounts", ErrValidation, p.Name)
+	}
+	if err := json.Unmarshal([]byte(p.RewardValue), &reward); err != nil {
+		return reward, fmt.Errorf("%w: invalid reward value", ErrValidation)
+	}
+	if reward.Points < 1 || (reward.Percentage == nil) == (reward.Amount == nil) {
+		return reward, fmt.Errorf("%w: a discount reward needs points and either a percentage or an amount", ErrValidation)
+	}
+	return reward, nil
+}
+
+// discountOff returns a percentage or fixed amount off a price, never more than the price
+func discountOff(price decimal.Decimal, percentage, amount *decimal.Decimal) decimal.Decimal {
+	var discount decimal.Decimal
+	if percentage != nil {
+		discount = price.Mul(*percentage).Div(decimal.NewFromInt(100)).Round(2)
+	} else if amount != nil {
+		discount = *amount
+	}
+	return decimal.Min(discount, price)
+}
+
+// PendingPriceChange is a scheduled price change that has not been applied yet, with the
+// services it targets
+type PendingPriceChange struct {
+	Change     *PriceChange
+	ServiceIDs []string
+}
+
+// QuoteAdjustment is the effect of a scheduled price change on a quoted service, which takes
+// effect by the time of the appointment
+type QuoteAdjustment struct {
+	PriceChangeID  string              `json:"price_change_id"`
+	AdjustmentType PriceAdjustmentType `json:"adjustment_type"`
+	Amount         decimal.Decimal     `json:"amount"`
+	Effect         decimal.Decimal     `json:"effect"` // Added to the price; negative for a reduction
+}
+
+// QuoteLine is a quoted service or add-on
+type QuoteLine struct {
+	ServiceID     string            `json:"service_id"`
+	Name          string            `json:"name"`
+	AddOn         bool              `json:"add_on"`
+	Duration      int               `json:"duration"`   // In minutes, preparation and cleanup included
+	BasePrice     decimal.Decimal   `json:"base_price"` // The service's current price
+	Adjustments   []QuoteAdjustment `json:"adjustments"`
+	Price         decimal.Decimal   `json:"price"` // After the adjustments
+	DepositAmount decimal.Decimal   `json:"deposit_amount"`
+}
+
+// QuotePromotion is a discount campaign applied to a quote
+type QuotePromotion struct {
+	CampaignID string          `json:"campaign_id"`
+	Name       string          `json:"name"`
+	Discount   decimal.Decimal `json:"discount"`
+}
+
+// QuoteRedemption is a loyalty reward the client could redeem on the booking. Redemptions are
+// offered, not applied: the quoted total leaves them out.
+type QuoteRedemption struct {
+	ProgramID       string          `json:"program_id"`
+	ProgramName     string          `json:"program_name"`
+	PointsRequired  int             `json:"points_required"`
+	PointsAvailable int             `json:"points_available"`
+	Discount        decimal.Decimal `json:"discount"`
+	Eligible        bool            `json:"eligible"` // The client has enough points
+}
+
+// QuoteBreakdown is how the price of an appointment adds up
+type QuoteBreakdown struct {
+	Lines       []QuoteLine       `json:"lines"`
+	Duration    int               `json:"duration"`
+	BasePrice   decimal.Decimal   `json:"base_price"`  // The services' current prices
+	Adjustments decimal.Decimal   `json:"adjustments"` // Of scheduled price changes
+	Subtotal    decimal.Decimal   `json:"subtotal"`
+	Promotion   *QuotePromotion   `json:"promotion,omitempty"`
+	Total       decimal.Decimal   `json:"total"`
+	DepositDue  decimal.Decimal   `json:"deposit_due"`
+	Redemptions []QuoteRedemption `json:"redemptions,omitempty"`
+}
+
+// QuoteRequest is what a quote is priced from
+type QuoteRequest struct {
+	Services     []*Service // Ordered as booked
+	AddOns       []*Service
+	StartTime    time.Time
+	PriceChanges []PendingPriceChange
+	Promotions   []*Campaign // Discount campaigns the client was targeted by
+	Programs     []*LoyaltyProgram
+	Memberships  map[string]*ClientLoyaltyMembership // By program ID
+}
+
+// PriceQuote works out the price of an appointment. Price changes scheduled by the start of the
+// appointment are applied to the services they target, in the order they take effect. The
+// promotion taking the most off is applied, as promotions do not stack. The deposit due is the
+// deposit of every service requiring one, no more than the total. Discount rewards of the
+// client's loyalty programs are listed as redemption options.
+func PriceQuote(request QuoteRequest) (*QuoteBreakdown, error) {
+	if len(request.Services) == 0 {
+		return nil, fmt.Errorf("%w: a quote needs at least one service", ErrValidation)
+	}
+	if len(request.Services)+len(request.AddOns) > MaxQuoteServices {
+		return nil, fmt.Errorf("%w: a quote covers at most %d services and add-ons", ErrValidation, MaxQuoteServices)
+	}
+
+	changes := slices.Clone(request.PriceChanges)
+	slices.SortStableFunc(changes, func(a, b PendingPriceChange) int {
+		return a.Change.EffectiveAt.Compare(b.Change.EffectiveAt)
+	})
+	breakdown := &QuoteBreakdown{Lines: make([]QuoteLine, 0, len(request.Services)+len(request.AddOns))}
+	lines := slices.Concat(request.Services, request.AddOns)
+	for i, service := range lines {
+		line := QuoteLine{
+			ServiceID:   service.ID,
+			Name:        service.Name,
+			AddOn:       i >= len(request.Services),
+			Duration:    service.GetTotalDuration(),
+			BasePrice:   service.Price,
+			Adjustments: []QuoteAdjustment{},
+			Price:       service.Price,
+		}
+		for _, pending := range changes {
+			change := pending.Change
+			if change.Status != PriceChangeScheduled || change.EffectiveAt.After(request.StartTime) || !slices.Contains(pending.ServiceIDs, service.ID) {
+				continue
+			}
+			price, err := change.Adjustment().Apply(line.Price)
+			if err != nil {
+				return nil, err
+			}
+			line.Adjustments = append(line.Adjustments, QuoteAdjustment{
+				PriceChangeID:  change.ID,
+				AdjustmentType: change.AdjustmentType,
+				Amount:         change.Amount,
+				Effect:         price.Sub(line.Price),
+			})
+			line.Price = price
+		}
+		if service.RequiresDeposit && service.DepositAmount != nil {
+			line.DepositAmount = *service.DepositAmount
+		}
+		breakdown.Lines = append(breakdown.Lines, line)
+		breakdown.Duration += line.Duration
+		breakdown.BasePrice = breakdown.BasePrice.Add(line.BasePrice)
+		breakdown.Subtotal = breakdown.Subtotal.Add(line.Price)
+		breakdown.DepositDue = breakdown.DepositDue.Add(line.DepositAmount)
+	}
+	breakdown.Adjustments = breakdown.Subtotal.Sub(breakdown.BasePrice)
+
+	for _, campaign := range request.Promotions {
+		if !campaign.IsActive || request.StartTime.Before(campaign.StartDate) || !request.StartTime.Before(campaign.EndDate) {
+			continue
+		}
+		offer, err := campaign.GetPromotionOffer()
+		if err != nil {
+			continue // Campaigns with offers the quote cannot price are left out
+		}
+		covered := decimal.Zero
+		for _, line := range breakdown.Lines {
+			if len(offer.ServiceIDs) == 0 || slices.Contains(offer.ServiceIDs, line.ServiceID) {
+				covered = covered.Add(line.Price)
+			}
+		}
+		discount := discountOff(covered, offer.Percentage, offer.Amount)
+		if discount.IsPositive() && (breakdown.Promotion == nil || discount.GreaterThan(breakdown.Promotion.Discount)) {
+			breakdown.Promotion = &QuotePromotion{CampaignID: campaign.ID, Name: campaign.Name, Discount: discount}
+		}
+	}
+	breakdown.Total = breakdown.Subtotal
+	if breakdown.Promotion != nil {
+		breakdown.Total = breakdown.Total.Sub(breakdown.Promotion.Discount)
+	}
+	breakdown.DepositDue = decimal.Min(breakdown.DepositDue, breakdown.Total)
+
+	for _, program := range request.Programs {
+		membership := request.Memberships[program.ID]
+		if membership == nil || !membership.IsActiveAt(request.StartTime) {
+			continue
+		}
+		reward, err := program.GetReward()
+		if err != nil {
+			continue // Only discount rewards apply to bookings
+		}
+		breakdown.Redemptions = append(breakdown.Redemptions, QuoteRedemption{
+			ProgramID:       program.ID,
+			ProgramName:     program.Name,
+			PointsRequired:  reward.Points,
+			PointsAvailable: membership.CurrentPoints,
+			Discount:        discountOff(breakdown.Total, reward.Percentage, reward.Amount),
+			Eligible:        membership.CurrentPoints >= reward.Points,
+		})
+	}
+	return breakdown, nil
+}
+
+// AppointmentQuote is a priced appointment that has not been booked. Booking with its token
+// within QuoteValidity honors the quoted total, whatever prices do in between.
+type AppointmentQuote struct {
+	BaseModel
+	BusinessID    string          `gorm:"not null;type:uuid;index" json:"business_id"`
+	ClientID      string          `gorm:"not null;type:uuid;index" json:"client_id"`
+	StaffID       string          `gorm:"not null;type:uuid" json:"staff_id"`
+	StartTime     time.Time       `gorm:"not null" json:"start_time"`
+	EndTime       time.Time       `gorm:"not null" json:"end_time"`
+	Token         string          `gorm:"not null;size:64;uniqueIndex" json:"-"`
+	ExpiresAt     time.Time       `gorm:"not null" json:"expires_at"`
+	Total         decimal.Decimal `gorm:"type:decimal(10,2);not null" json:"total"`
+	DepositDue    decimal.Decimal `gorm:"type:decimal(10,2);not null" json:"deposit_due"`
+	Breakdown     string          `gorm:"type:jsonb;not null" json:"breakdown"`      // JSON QuoteBreakdown
+	AppointmentID *string         `gorm:"type:uuid" json:"appointment_id,omitempty"` // Booked with the quote
+}
+
+// TableName returns the table name for AppointmentQuote
+func (AppointmentQuote) TableName() string { return "appointment_quotes" }
+
+// NewAppointmentQuote issues a quote for a priced appointment, valid for QuoteValidity
+func NewAppointmentQuote(businessID, clientID, staffID string, startTime time.Time, breakdown *QuoteBreakdown, now time.Time) (*AppointmentQuote, error) {
+	token, err := NewQuoteToken()
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(breakdown)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode quote breakdown: %w", err)
+	}
+	return &AppointmentQuote{
+		BusinessID: businessID,
+		ClientID:   clientID,
+		StaffID:    staffID,
+		StartTime:  startTime,
+		EndTime:    startTime.Add(time.Duration(breakdown.Duration) * time.Minute),
+		Token:      token,
+		ExpiresAt:  now.Add(QuoteValidity),
+		Total:      breakdown.Total,
+		DepositDue: breakdown.DepositDue,
+		Breakdown:  string(data),
+	}, nil
+}
+
+// NewQuoteToken generates the unguessable token a quote is booked with
+func NewQuoteToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate quote token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// GetBreakdown decodes how the quoted price adds up
+func (q *AppointmentQuote) GetBreakdown() (*QuoteBreakdown, error) {
+	var breakdown QuoteBreakdown
+	if err := json.Unmarshal([]byte(q.Breakdown), &breakdown); err != nil {
+		return nil, fmt.Errorf("%w: invalid quote breakdown", ErrValidation)
+	}
+	return &breakdown, nil
+}
+
+// CheckBooking returns why an appointment cannot be booked with the quote, if it cannot. The
+// booking must be for the quoted business, client, staff member and times.
+func (q *AppointmentQuote) CheckBooking(appointment *Appointment, now time.Time) error {
+	if q.AppointmentID != nil {
+		return ErrQuoteUsed
+	}
+	if !now.Before(q.ExpiresAt) {
+		return ErrQuoteExpired
+	}
+	if appointment.BusinessID != q.BusinessID || appointment.ClientID != q.ClientID || appointment.StaffID != q.StaffID ||
+		!appointment.StartTime.Equal(q.StartTime) || !appointment.EndTime.Equal(q.EndTime) {
+		return ErrQuoteMismatch
+	}
+	return nil
+}
+
+// AppointmentLines returns the services of the appointment booked with the quote, at their
+// quoted prices. A promotion is shared between the lines in proportion to their prices.
+func (q *AppointmentQuote) AppointmentLines() ([]*AppointmentLine, error) {
+	breakdown, err := q.GetBreakdown()
+	if err != nil {
+		return nil, err
+	}
+	lines := make([]*AppointmentLine, len(breakdown.Lines))
+	remaining := breakdown.Total
+	for i, line := range breakdown.Lines {
+		price := line.Price
+		if i == len(breakdown.Lines)-1 {
+			price = remaining
+		} else if breakdown.Subtotal.IsPositive() {
+			price = breakdown.Total.Mul(line.Price).Div(breakdown.Subtotal).Round(2)
+		}
+		remaining = remaining.Sub(price)
+		lines[i] = &AppointmentLine{ServiceID: line.ServiceID, StaffID: q.StaffID, Duration: line.Duration, Price: price}
+	}
+	return lines, nil
+}
+
+// AppointmentQuoteRepository defines the repository interface for appointment quotes and what
+// they are priced from
+type AppointmentQuoteRepository interface {
+	Create(ctx context.Context, quote *AppointmentQuote) error
+	FindByToken(ctx context.Context, token string) (*AppointmentQuote, error)
+	// FindPromotions finds the active discount campaigns of the business the client was
+	// targeted by and did not unsubscribe from, running at the given time
+	FindPromotions(ctx context.Context, businessID, clientID string, at time.Time) ([]*Campaign, error)
+	// MarkUsed records the appointment booked with the quote within the booking's transaction,
+	// returning ErrQuoteUsed when another booking used it concurrently
+	MarkUsed(ctx context.Context, tx *gorm.DB, quoteID, appointmentID string) error
+}
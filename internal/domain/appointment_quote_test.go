@@ -0,0 +1,180 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func quoteFixture() QuoteRequest {
+	start := time.Date(2026, 6, 1, 10, 0, 0, 0, time.UTC)
+	deposit := decimal.NewFromInt(20)
+	return QuoteRequest{
+		Services: []*Service{
+			{BaseModel: BaseModel{ID: "cut"}, Name: "Cut", Duration: 45, Price: decimal.NewFromInt(40), RequiresDeposit: true, DepositAmount: &deposit},
+		},
+		AddOns: []*Service{
+			{BaseModel: BaseModel{ID: "mask"}, Name: "Mask", Duration: 15, Price: decimal.NewFromInt(10)},
+		},
+		StartTime: start,
+	}
+}
+
+func TestPriceQuote_Lines(t *testing.T) {
+	breakdown, err := PriceQuote(quoteFixture())
+	require.NoError(t, err)
+
+	require.Len(t, breakdown.Lines, 2)
+	assert.False(t, breakdown.Lines[0].AddOn)
+	assert.True(t, breakdown.Lines[1].AddOn)
+	assert.Equal(t, 60, breakdown.Duration)
+	assert.Equal(t, "50.00", breakdown.Subtotal.StringFixed(2))
+	assert.Equal(t, "50.00", breakdown.Total.StringFixed(2))
+	assert.Equal(t, "20.00", breakdown.DepositDue.StringFixed(2))
+	assert.Nil(t, breakdown.Promotion)
+}
+
+func TestPriceQuote_PendingPriceChanges(t *testing.T) {
+	request := quoteFixture()
+	request.PriceChanges = []PendingPriceChange{
+		{Change: &PriceChange{BaseModel: BaseModel{ID: "later"}, AdjustmentType: PriceAdjustmentFixed, Amount: decimal.NewFromInt(5),
+			EffectiveAt: request.StartTime.Add(-time.Hour), Status: PriceChangeScheduled}, ServiceIDs: []string{"cut"}},
+		{Change: &PriceChange{BaseModel: BaseModel{ID: "earlier"}, AdjustmentType: PriceAdjustmentPercentage, Amount: decimal.NewFromInt(10),
+			EffectiveAt: request.StartTime.Add(-48 * time.Hour), Status: PriceChangeScheduled}, ServiceIDs: []string{"cut", "mask"}},
+		{Change: &PriceChange{BaseModel: BaseModel{ID: "after"}, AdjustmentType: PriceAdjustmentFixed, Amount: decimal.NewFromInt(100),
+			EffectiveAt: request.StartTime.Add(time.Hour), Status: PriceChangeScheduled}, ServiceIDs: []string{"cut"}},
+	}
+
+	breakdown, err := PriceQuote(request)
+	require.NoError(t, err)
+
+	cut := breakdown.Lines[0]
+	require.Len(t, cut.Adjustments, 2)
+	assert.Equal(t, "earlier", cut.Adjustments[0].PriceChangeID)
+	assert.Equal(t, "4.00", cut.Adjustments[0].Effect.StringFixed(2))
+	assert.Equal(t, "later", cut.Adjustments[1].PriceChangeID)
+	assert.Equal(t, "49.00", cut.Price.StringFixed(2))
+	assert.Equal(t, "11.00", breakdown.Lines[1].Price.StringFixed(2))
+	assert.Equal(t, "10.00", breakdown.Adjustments.StringFixed(2))
+	assert.Equal(t, "60.00", breakdown.Total.StringFixed(2))
+}
+
+func TestPriceQuote_Promotions(t *testing.T) {
+	request := quoteFixture()
+	running := func(id, details string) *Campaign {
+		return &Campaign{BaseModel: BaseModel{ID: id}, Name: id, OfferType: OfferTypeDiscount, OfferDetails: details, IsActive: true,
+			StartDate: request.StartTime.AddDate(0, 0, -7), EndDate: request.StartTime.AddDate(0, 0, 7)}
+	}
+	ended := running("ended", `{"percentage": 50}`)
+	ended.EndDate = request.StartTime
+	request.Promotions = []*Campaign{
+		running("ten-percent", `{"percentage": 10}`),
+		running("mask-free", `{"amount": 15, "service_ids": ["mask"]}`),
+		running("eight-off", `{"amount": 8}`),
+		running("broken", `{"percentage": 10, "amount": 5}`),
+		ended,
+	}
+
+	breakdown, err := PriceQuote(request)
+	require.NoError(t, err)
+
+	require.NotNil(t, breakdown.Promotion)
+	assert.Equal(t, "mask-free", breakdown.Promotion.CampaignID)
+	assert.Equal(t, "10.00", breakdown.Promotion.Discount.StringFixed(2), "a discount is capped at the services it covers")
+	assert.Equal(t, "40.00", breakdown.Total.StringFixed(2))
+}
+
+func TestPriceQuote_DepositCappedAtTotal(t *testing.T) {
+	request := quoteFixture()
+	request.Promotions = []*Campaign{{OfferType: OfferTypeDiscount, OfferDetails: `{"amount": 45}`, IsActive: true,
+		StartDate: request.StartTime.Add(-time.Hour), EndDate: request.StartTime.Add(time.Hour)}}
+
+	breakdown, err := PriceQuote(request)
+	require.NoError(t, err)
+	assert.Equal(t, "5.00", breakdown.Total.StringFixed(2))
+	assert.Equal(t, "5.00", breakdown.DepositDue.StringFixed(2))
+}
+
+func TestPriceQuote_Redemptions(t *testing.T) {
+	request := quoteFixture()
+	request.Programs = []*LoyaltyProgram{
+		{BaseModel: BaseModel{ID: "points"}, Name: "Points", RewardType: RewardTypeDiscount, RewardValue: `{"points": 100, "amount": 5}`},
+		{BaseModel: BaseModel{ID: "vip"}, Name: "VIP", RewardType: RewardTypeDiscount, RewardValue: `{"points": 500, "percentage": 20}`},
+		{BaseModel: BaseModel{ID: "gift"}, Name: "Gift", RewardType: "free_service", RewardValue: `{}`},
+		{BaseModel: BaseModel{ID: "stranger"}, Name: "Stranger", RewardType: RewardTypeDiscount, RewardValue: `{"points": 1, "amount": 1}`},
+	}
+	request.Memberships = map[string]*ClientLoyaltyMembership{
+		"points": {CurrentPoints: 150, IsActive: true},
+		"vip":    {CurrentPoints: 150, IsActive: true},
+		"gift":   {CurrentPoints: 150, IsActive: true},
+	}
+
+	breakdown, err := PriceQuote(request)
+	require.NoError(t, err)
+
+	require.Len(t, breakdown.Redemptions, 2)
+	assert.Equal(t, "points", breakdown.Redemptions[0].ProgramID)
+	assert.True(t, breakdown.Redemptions[0].Eligible)
+	assert.Equal(t, "5.00", breakdown.Redemptions[0].Discount.StringFixed(2))
+	assert.Equal(t, "vip", breakdown.Redemptions[1].ProgramID)
+	assert.False(t, breakdown.Redemptions[1].Eligible)
+	assert.Equal(t, "10.00", breakdown.Redemptions[1].Discount.StringFixed(2))
+	assert.Equal(t, "50.00", breakdown.Total.StringFixed(2), "redemptions are offered, not applied")
+}
+
+func TestPriceQuote_Validation(t *testing.T) {
+	_, err := PriceQuote(QuoteRequest{})
+	assert.True(t, errors.Is(err, ErrValidation))
+
+	request := quoteFixture()
+	for range MaxQuoteServices {
+		request.AddOns = append(request.AddOns, request.AddOns[0])
+	}
+	_, err = PriceQuote(request)
+	assert.True(t, errors.Is(err, ErrValidation))
+}
+
+func TestAppointmentQuote_CheckBooking(t *testing.T) {
+	now := time.Date(2026, 5, 20, 12, 0, 0, 0, time.UTC)
+	request := quoteFixture()
+	breakdown, err := PriceQuote(request)
+	require.NoError(t, err)
+	quote, err := NewAppointmentQuote("business-1", "client-1", "staff-1", request.StartTime, breakdown, now)
+	require.NoError(t, err)
+	assert.Len(t, quote.Token, 32)
+	assert.Equal(t, request.StartTime.Add(time.Hour), quote.EndTime)
+
+	appointment := &Appointment{BusinessID: "business-1", ClientID: "client-1", StaffID: "staff-1", StartTime: quote.StartTime, EndTime: quote.EndTime}
+	assert.NoError(t, quote.CheckBooking(appointment, now.Add(QuoteValidity-time.Second)))
+	assert.ErrorIs(t, quote.CheckBooking(appointment, now.Add(QuoteValidity)), ErrQuoteExpired)
+
+	other := *appointment
+	other.StaffID = "staff-2"
+	assert.ErrorIs(t, quote.CheckBooking(&other, now), ErrQuoteMismatch)
+
+	appointmentID := "appointment-1"
+	quote.AppointmentID = &appointmentID
+	assert.ErrorIs(t, quote.CheckBooking(appointment, now), ErrQuoteUsed)
+}
+
+func TestAppointmentQuote_AppointmentLines(t *testing.T) {
+	request := quoteFixture()
+	request.Promotions = []*Campaign{{OfferType: OfferTypeDiscount, OfferDetails: `{"amount": 10}`, IsActive: true,
+		StartDate: request.StartTime.Add(-time.Hour), EndDate: request.StartTime.Add(time.Hour)}}
+	breakdown, err := PriceQuote(request)
+	require.NoError(t, err)
+	quote, err := NewAppointmentQuote("business-1", "client-1", "staff-1", request.StartTime, breakdown, time.Now())
+	require.NoError(t, err)
+
+	lines, err := quote.AppointmentLines()
+	require.NoError(t, err)
+	require.Len(t, lines, 2)
+	assert.Equal(t, "cut", lines[0].ServiceID)
+	assert.Equal(t, "32.00", lines[0].Price.StringFixed(2))
+	assert.Equal(t, "8.00", lines[1].Price.StringFixed(2))
+	assert.Equal(t, "staff-1", lines[1].StaffID)
+}
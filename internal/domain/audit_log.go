@@ -0,0 +1,124 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// AuditAction represents the kind of change an audit log entry records
+type AuditAction string
+
+const (
+	AuditActionCreate  AuditAction = "create"
+	AuditActionUpdate  AuditAction = "update"
+	AuditActionDelete  AuditAction = "delete"
+	AuditActionRestore AuditAction = "restore"
+	AuditActionExport  AuditAction = "export" // The entity's data was exported; nothing changed
+)
+
+// IsValid checks if the audit action is valid
+func (a AuditAction) IsValid() bool {
+	switch a {
+	case AuditActionCreate, AuditActionUpdate, AuditActionDelete, AuditActionRestore, AuditActionExport:
+		return true
+	default:
+		return false
+	}
+}
+
+// AuditRedacted stands in for the values of secret fields, such as password hashes and tokens,
+// so that the audit log tells they changed without keeping them
+const AuditRedacted = `"[redacted]"`
+
+// AuditChange is the value of a field before and after a change, encoded as JSON. New entities
+// have no value before.
+type AuditChange struct {
+	From json.RawMessage `json:"from"`
+	To   json.RawMessage `json:"to"`
+}
+
+// AuditLog is an entry in the append-only record of who changed what. Every write made through
+// the repositories is recorded in the transaction of the write, with the fields it changed.
+type AuditLog struct {
+	BaseModel
+	BusinessID *string     `gorm:"type:uuid;index" json:"business_id,omitempty"` // Nil for changes outside any business, e.g. to users
+	EntityType string      `gorm:"not null;size:100" json:"entity_type"`         // The table changed, e.g. "appointments"
+	EntityID   string      `gorm:"not null;size:100" json:"entity_id"`
+	Action     AuditAction `gorm:"not null;size:20" json:"action"`
+	Changes    *string     `gorm:"type:jsonb" json:"changes,omitempty"`       // Field name to AuditChange; nil for deletions and restorations
+	ActorID    *string     `gorm:"type:uuid;index" json:"actor_id,omitempty"` // The user who made the change; nil for the system
+	OccurredAt time.Time   `gorm:"not null" json:"occurred_at"`
+}
+
+// TableName returns the table name for AuditLog
+func (AuditLog) TableName() string { return "audit_logs" }
+
+// NewAuditLog creates an audit log entry for a change to an entity, made by the actor carried
+// in the context
+func NewAuditLog(ctx context.Context, action AuditAction, entityType, entityID string, businessID *string, changes map[string]AuditChange, now time.Time) (*AuditLog, error) {
+	log := &AuditLog{
+		BusinessID: businessID,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     action,
+		OccurredAt: now,
+	}
+	if actorID, ok := ActorFromContext(ctx); ok {
+		log.ActorID = &actorID
+	}
+	if len(changes) > 0 {
+		data, err := json.Marshal(changes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode audited changes: %w", err)
+		}
+		encoded := string(data)
+		log.Changes = &encoded
+	}
+	return log, nil
+}
+
+// DecodeChanges decodes the fields the entry records as changed
+func (l *AuditLog) DecodeChanges() (map[string]AuditChange, error) {
+	changes := map[string]AuditChange{}
+	if l.Changes == nil || *l.Changes == "" {
+		return changes, nil
+	}
+	if err := json.Unmarshal([]byte(*l.Changes), &changes); err != nil {
+		return nil, fmt.Errorf("invalid changes in audit log entry %s: %w", l.ID, err)
+	}
+	return changes, nil
+}
+
+type actorContextKey struct{}
+
+// WithActor returns a context acting on behalf of a user, whom the audit log records as the
+// author of the changes made with it
+func WithActor(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, userID)
+}
+
+// ActorFromContext returns the user a context acts on behalf of
+func ActorFromContext(ctx context.Context) (string, bool) {
+	userID, ok := ctx.Value(actorContextKey{}).(string)
+	return userID, ok && userID != ""
+}
+
+// AuditLogFilter represents the criteria for browsing the audit log of a business
+type AuditLogFilter struct {
+	BusinessID string
+	EntityType *string
+	EntityID   *string
+	ActorID    *string
+	Action     *AuditAction
+	StartDate  *time.Time
+	EndDate    *time.Time
+}
+
+// AuditLogRepository defines the repository interface for AuditLog
+type AuditLogRepository interface {
+	BaseRepository[AuditLog]
+	// Search finds the entries matching the filter, most recent first
+	Search(ctx context.Context, filter AuditLogFilter, page, pageSize int) ([]*AuditLog, int64, error)
+}
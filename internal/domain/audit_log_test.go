@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAuditLog(t *testing.T) {
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	businessID := "business-1"
+	changes := map[string]AuditChange{"name": {From: json.RawMessage(`"Haircut"`), To: json.RawMessage(`"Signature haircut"`)}}
+
+	t.Run("records the actor and the changes", func(t *testing.T) {
+		ctx := WithActor(context.Background(), "user-1")
+		entry, err := NewAuditLog(ctx, AuditActionUpdate, "services", "service-1", &businessID, changes, now)
+		require.NoError(t, err)
+		require.NotNil(t, entry.ActorID)
+		assert.Equal(t, "user-1", *entry.ActorID)
+		assert.Equal(t, now, entry.OccurredAt)
+
+		decoded, err := entry.DecodeChanges()
+		require.NoError(t, err)
+		assert.Equal(t, changes, decoded)
+	})
+
+	t.Run("changes made by the system have no actor", func(t *testing.T) {
+		entry, err := NewAuditLog(context.Background(), AuditActionDelete, "services", "service-1", &businessID, nil, now)
+		require.NoError(t, err)
+		assert.Nil(t, entry.ActorID)
+		assert.Nil(t, entry.Changes)
+
+		decoded, err := entry.DecodeChanges()
+		require.NoError(t, err)
+		assert.Empty(t, decoded)
+	})
+}
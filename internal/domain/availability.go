@@ -0,0 +1,374 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrAppointmentConflict is matched by errors.Is on AppointmentConflictError
+var ErrAppointmentConflict = errors.New("appointment conflicts with the schedule")
+
+// ConflictReason represents why a staff member cannot take an appointment at a given time
+type ConflictReason string
+
+const (
+	ConflictOverlap          ConflictReason = "overlap"           // Another appointment, including its buffer, overlaps
+	ConflictOutsideHours     ConflictReason = "outside_hours"     // The business is closed
+	ConflictStaffUnavailable ConflictReason = "staff_unavailable" // The staff member has time off
+)
+
+// AvailabilityConflict describes one reason a time slot is not available
+type AvailabilityConflict struct {
+	Reason        ConflictReason `json:"reason"`
+	Message       string         `json:"message"`
+	AppointmentID *string        `json:"appointment_id,omitempty"` // The overlapping appointment
+	ExceptionID   *string        `json:"exception_id,omitempty"`   // The staff member's availability exception
+	ResourceID    *string        `json:"resource_id,omitempty"`    // The resource in use
+	Start         *time.Time     `json:"start,omitempty"`
+	End           *time.Time     `json:"end,omitempty"`
+}
+
+// AppointmentConflictError is returned when an appointment cannot be booked at its time
+type AppointmentConflictError struct {
+	Conflicts []*AvailabilityConflict
+}
+
+// Error implements the error interface
+func (e *AppointmentConflictError) Error() string {
+	messages := make([]string, len(e.Conflicts))
+	for i, conflict := range e.Conflicts {
+		messages[i] = conflict.Message
+	}
+	return ErrAppointmentConflict.Error() + ": " + strings.Join(messages, "; ")
+}
+
+// Is makes the error match ErrAppointmentConflict
+func (e *AppointmentConflictError) Is(target error) bool {
+	return target == ErrAppointmentConflict
+}
+
+// AvailabilityRequest asks whether a staff member can take an appointment at a time
+type AvailabilityRequest struct {
+	BusinessID           string
+	StaffID              string
+	Start                time.Time
+	End                  time.Time
+	ExcludeAppointmentID *string  // The appointment being rescheduled, which must not conflict with itself
+	ServiceIDs           []string // The services performed, whose resources the appointment occupies
+}
+
+// DayHours are the opening hours of a business on one day of the week
+type DayHours struct {
+	IsOpen    bool   `json:"is_open"`
+	OpenTime  string `json:"open_time,omitempty"`  // HH:MM
+	CloseTime string `json:"close_time,omitempty"` // HH:MM
+}
+
+// WeeklyHours are the opening hours of a business, keyed by lower-case English weekday name.
+// A missing day is closed.
+type WeeklyHours map[string]*DayHours
+
+// ParseWeeklyHours decodes the business_hours JSON of a business. It returns nil when the
+// business has not configured its hours.
+func ParseWeeklyHours(raw *string) (WeeklyHours, error) {
+	if raw == nil || strings.TrimSpace(*raw) == "" || strings.TrimSpace(*raw) == "{}" {
+		return nil, nil
+	}
+	var hours WeeklyHours
+	if err := json.Unmarshal([]byte(*raw), &hours); err != nil {
+		return nil, fmt.Errorf("%w: invalid business hours", ErrValidation)
+	}
+	return hours, nil
+}
+
+// DailyHours returns the same opening hours for every day of the week
+func DailyHours(startHour, endHour int) WeeklyHours {
+	hours := WeeklyHours{}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		hours[strings.ToLower(day.String())] = &DayHours{
+			IsOpen:    true,
+			OpenTime:  fmt.Sprintf("%02d:00", startHour),
+			CloseTime: fmt.Sprintf("%02d:00", endHour),
+		}
+	}
+	return hours
+}
+
+// Contains checks whether the interval falls within the opening hours of a single local day
+func (h WeeklyHours) Contains(start, end time.Time, location *time.Location) bool {
+	localStart, localEnd := start.In(location), end.In(location)
+	day := h[strings.ToLower(localStart.Weekday().String())]
+	if day == nil || !day.IsOpen {
+		return false
+	}
+
+	opens, err := clockOn(localStart, day.OpenTime)
+	if err != nil {
+		return false
+	}
+	closes, err := clockOn(localStart, day.CloseTime)
+	if err != nil {
+		return false
+	}
+	if !closes.After(opens) {
+		// Closes at midnight
+		closes = closes.AddDate(0, 0, 1)
+	}
+	return !localStart.Before(opens) && !localEnd.After(closes)
+}
+
+// clockOn returns the time of day HH:MM on the date of t, in t's location
+func clockOn(t time.Time, clock string) (time.Time, error) {
+	if clock == "24:00" {
+		clock = "00:00"
+	}
+	parsed, err := time.Parse("15:04", clock)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), parsed.Hour(), parsed.Minute(), 0, 0, t.Location()), nil
+}
+
+// AvailabilityExceptionType represents the kind of an exception to a staff member's availability
+type AvailabilityExceptionType string
+
+const (
+	ExceptionTimeOff     AvailabilityExceptionType = "time_off"
+	ExceptionHoliday     AvailabilityExceptionType = "holiday"
+	ExceptionCustomHours AvailabilityExceptionType = "custom_hours" // Working at unusual hours; does not block bookings
+)
+
+// AvailabilityException is a period in which a staff member's availability differs from usual
+type AvailabilityException struct {
+	BaseModel
+	BusinessID     string                    `gorm:"not null;type:uuid;index" json:"business_id"`
+	StaffID        string                    `gorm:"not null;type:uuid;index" json:"staff_id"`
+	ExceptionType  AvailabilityExceptionType `gorm:"not null;size:50" json:"exception_type"`
+	StartTime      time.Time                 `gorm:"not null" json:"start_time"`
+	EndTime        time.Time                 `gorm:"not null" json:"end_time"`
+	IsFullDay      bool                      `gorm:"not null;default:false" json:"is_full_day"`
+	IsRecurring    bool                      `gorm:"not null;default:false" json:"is_recurring"`
+	RecurrenceRule *string                   `gorm:"type:text" json:"recurrence_rule,omitempty"` // iCalendar RRULE
+	Notes          *string                   `gorm:"type:text" json:"notes,omitempty"`
+}
+
+// TableName returns the table name for AvailabilityException
+func (AvailabilityException) TableName() string { return "availability_exception" }
+
+// Blocks checks whether the exception makes the staff member unavailable for any part of the
+// interval. Recurring exceptions repeat with the FREQ of their rule (DAILY, WEEKLY or YEARLY);
+// other rules are treated as a single occurrence.
+func (e *AvailabilityException) Blocks(start, end time.Time) bool {
+	if e.ExceptionType == ExceptionCustomHours {
+		return false
+	}
+
+	length := e.EndTime.Sub(e.StartTime)
+	occurrence := e.StartTime
+	step := e.recurrenceStep()
+	if step != nil {
+		// Advance to the occurrence nearest the interval, without passing its end
+		for occurrence.Add(length).Before(start) || occurrence.Add(length).Equal(start) {
+			next := step(occurrence)
+			if !next.Before(end) {
+				break
+			}
+			occurrence = next
+		}
+	}
+	return occurrence.Before(end) && occurrence.Add(length).After(start)
+}
+
+// recurrenceStep returns the function advancing a recurring exception to its next occurrence
+func (e *AvailabilityException) recurrenceStep() func(time.Time) time.Time {
+	if !e.IsRecurring || e.RecurrenceRule == nil {
+		return nil
+	}
+	rule := strings.ToUpper(*e.RecurrenceRule)
+	switch {
+	case strings.Contains(rule, "FREQ=DAILY"):
+		return func(t time.Time) time.Time { return t.AddDate(0, 0, 1) }
+	case strings.Contains(rule, "FREQ=WEEKLY"):
+		return func(t time.Time) time.Time { return t.AddDate(0, 0, 7) }
+	case strings.Contains(rule, "FREQ=YEARLY"):
+		return func(t time.Time) time.Time { return t.AddDate(1, 0, 0) }
+	}
+	return nil
+}
+
+// BusyInterval is a staff member's existing appointment, as seen by availability checks
+type BusyInterval struct {
+	AppointmentID string    `json:"appointment_id"`
+	StartTime     time.Time `json:"start_time"`
+	EndTime       time.Time `json:"end_time"`
+}
+
+// AvailabilityRules are the business rules a booking is checked against
+type AvailabilityRules struct {
+	Location *time.Location
+	Hours    WeeklyHours
+	Buffer   time.Duration // Minimum gap between a staff member's appointments
+}
+
+// NewAvailabilityRules builds the rules of a business. Businesses without configured opening
+// hours are open every day during their calendar hours.
+func NewAvailabilityRules(business *Business, settings *BusinessSettings) AvailabilityRules {
+	rules := AvailabilityRules{Location: time.UTC}
+	if location, err := time.LoadLocation(business.TimeZone); err == nil {
+		rules.Location = location
+	}
+	if hours, err := ParseWeeklyHours(business.BusinessHours); err == nil && hours != nil {
+		rules.Hours = hours
+	}
+
+	startHour, endHour := 9, 18
+	if settings != nil {
+		startHour, endHour = settings.CalendarStartHour, settings.CalendarEndHour
+		rules.Buffer = time.Duration(settings.AppointmentBufferMinutes) * time.Minute
+	}
+	if rules.Hours == nil {
+		rules.Hours = DailyHours(startHour, endHour)
+	}
+	return rules
+}
+
+// Check returns the conflicts of a requested time slot with the business hours, the staff
+// member's existing appointments and their availability exceptions
+func (r AvailabilityRules) Check(request AvailabilityRequest, busy []*BusyInterval, exceptions []*AvailabilityException) []*AvailabilityConflict {
+	var conflicts []*AvailabilityConflict
+
+	if !r.Hours.Contains(request.Start, request.End, r.Location) {
+		conflicts = append(conflicts, &AvailabilityConflict{
+			Reason:  ConflictOutsideHours,
+			Message: "the business is closed at the requested time",
+		})
+	}
+
+	for _, interval := range busy {
+		if request.ExcludeAppointmentID != nil && interval.AppointmentID == *request.ExcludeAppointmentID {
+			continue
+		}
+		// The buffer is kept free after every appointment, whichever is booked first
+		if request.Start.Before(interval.EndTime.Add(r.Buffer)) && interval.StartTime.Before(request.End.Add(r.Buffer)) {
+			appointmentID, start, end := interval.AppointmentID, interval.StartTime, interval.EndTime
+			conflicts = append(conflicts, &AvailabilityConflict{
+				Reason:        ConflictOverlap,
+				Message:       "the staff member has another appointment from " + start.In(r.Location).Format("15:04") + " to " + end.In(r.Location).Format("15:04"),
+				AppointmentID: &appointmentID,
+				Start:         &start,
+				End:           &end,
+			})
+		}
+	}
+
+	for _, exception := range exceptions {
+		if exception.Blocks(request.Start, request.End) {
+			exceptionID := exception.ID
+			conflicts = append(conflicts, &AvailabilityConflict{
+				Reason:      ConflictStaffUnavailable,
+				Message:     "the staff member is unavailable (" + strings.ReplaceAll(string(exception.ExceptionType), "_", " ") + ")",
+				ExceptionID: &exceptionID,
+			})
+		}
+	}
+	return conflicts
+}
+
+// SlotInterval is the spacing of the start times offered by searches for free slots
+const SlotInterval = 15 * time.Minute
+
+// MaxSlotSearchDays is the longest period, in days, a search for free slots may cover
+const MaxSlotSearchDays = 31
+
+// StaffSchedule is what occupies a staff member's time during a period, as seen by searches for
+// free slots
+type StaffSchedule struct {
+	StaffID    string
+	Busy       []*BusyInterval
+	Exceptions []*AvailabilityException
+	Shifts     []*StaffShift // None when the staff member is not on a rota
+}
+
+// SlotSearch asks for the times at which an appointment can start
+type SlotSearch struct {
+	Start     time.Time     // Start of the period searched
+	End       time.Time     // End of the period searched; appointments must end by then
+	Duration  time.Duration // Length of the appointment
+	Interval  time.Duration // Spacing of the start times offered
+	NotBefore time.Time     // No appointment may start earlier, typically now
+	NotAfter  time.Time     // No appointment may start later; no limit when zero
+}
+
+// FreeSlots returns the start times in the search period, in order, at which the staff member
+// can take an appointment: within the opening hours and their shifts, clear of their
+// appointments and the buffer around them, and outside their time off
+func (r AvailabilityRules) FreeSlots(schedule *StaffSchedule, search SlotSearch) []time.Time {
+	var slots []time.Time
+	for day := search.Start.In(r.Location); day.Before(search.End); day = nextLocalDay(day, r.Location) {
+		for _, start := range r.CandidateSlots(day, search.Duration, search.Interval, search.NotBefore) {
+			end := start.Add(search.Duration)
+			if start.Before(search.Start) || end.After(search.End) {
+				continue
+			}
+			if !search.NotAfter.IsZero() && start.After(search.NotAfter) {
+				continue
+			}
+			request := AvailabilityRequest{StaffID: schedule.StaffID, Start: start, End: end}
+			if len(r.Check(request, schedule.Busy, schedule.Exceptions)) == 0 && r.CheckShifts(request, schedule.Shifts) == nil {
+				slots = append(slots, start)
+			}
+		}
+	}
+	return slots
+}
+
+// ParseBookingDate parses a YYYY-MM-DD date as the start of that day in the location
+func ParseBookingDate(date string, location *time.Location) (time.Time, error) {
+	day, err := time.ParseInLocation(time.DateOnly, strings.TrimSpace(date), location)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: date must be formatted as YYYY-MM-DD", ErrValidation)
+	}
+	return day, nil
+}
+
+// CandidateSlots returns the start times on a local day, spaced by interval, at which an
+// appointment lasting duration fits in the opening hours and starts after notBefore. The
+// staff members' schedules still have to be checked for each candidate.
+func (r AvailabilityRules) CandidateSlots(day time.Time, duration, interval time.Duration, notBefore time.Time) []time.Time {
+	var starts []time.Time
+	if duration <= 0 || interval <= 0 {
+		return starts
+	}
+
+	local := day.In(r.Location)
+	dayStart := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, r.Location)
+	dayEnd := dayStart.AddDate(0, 0, 1)
+	for start := dayStart; start.Before(dayEnd); start = start.Add(interval) {
+		if start.Before(notBefore) {
+			continue
+		}
+		if r.Hours.Contains(start, start.Add(duration), r.Location) {
+			starts = append(starts, start)
+		}
+	}
+	return starts
+}
+
+// nextLocalDay returns the start of the local day following t
+func nextLocalDay(t time.Time, location *time.Location) time.Time {
+	local := t.In(location)
+	return time.Date(local.Year(), local.Month(), local.Day()+1, 0, 0, 0, 0, location)
+}
+
+// blocksAvailability lists the appointment statuses that occupy a staff member's time
+var blocksAvailability = []AppointmentStatus{
+	AppointmentStatusPending, AppointmentStatusScheduled, AppointmentStatusConfirmed, AppointmentStatusInProgress, AppointmentStatusCompleted,
+}
+
+// BlockingAppointmentStatuses returns the appointment statuses that occupy a staff member's time
+func BlockingAppointmentStatuses() []AppointmentStatus {
+	return append([]AppointmentStatus(nil), blocksAvailability...)
+}
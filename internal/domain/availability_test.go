@@ -0,0 +1,203 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWeeklyHours(t *testing.T) {
+	hours, err := ParseWeeklyHours(nil)
+	require.NoError(t, err)
+	assert.Nil(t, hours)
+
+	raw := `{"monday": {"is_open": true, "open_time": "09:00", "close_time": "19:00"}, "sunday": {"is_open": false}}`
+	hours, err = ParseWeeklyHours(&raw)
+	require.NoError(t, err)
+	assert.True(t, hours["monday"].IsOpen)
+	assert.Equal(t, "19:00", hours["monday"].CloseTime)
+
+	invalid := `{"monday": true}`
+	_, err = ParseWeeklyHours(&invalid)
+	assert.True(t, errors.Is(err, ErrValidation))
+}
+
+func TestWeeklyHoursContains(t *testing.T) {
+	lisbon, err := time.LoadLocation("Europe/Lisbon")
+	require.NoError(t, err)
+	hours := WeeklyHours{
+		"monday":   {IsOpen: true, OpenTime: "09:00", CloseTime: "18:00"},
+		"saturday": {IsOpen: true, OpenTime: "20:00", CloseTime: "24:00"},
+		"sunday":   {IsOpen: false},
+	}
+	at := func(day int, clock string) time.Time {
+		parsed, _ := time.Parse("15:04", clock)
+		return time.Date(2024, 6, day, parsed.Hour(), parsed.Minute(), 0, 0, lisbon)
+	}
+
+	// 2024-06-03 is a Monday
+	assert.True(t, hours.Contains(at(3, "09:00"), at(3, "10:00"), lisbon))
+	assert.True(t, hours.Contains(at(3, "17:00"), at(3, "18:00"), lisbon))
+	assert.False(t, hours.Contains(at(3, "08:30"), at(3, "09:30"), lisbon))
+	assert.False(t, hours.Contains(at(3, "17:30"), at(3, "18:30"), lisbon))
+	assert.False(t, hours.Contains(at(4, "10:00"), at(4, "11:00"), lisbon), "tuesday is not configured")
+	assert.False(t, hours.Contains(at(2, "10:00"), at(2, "11:00"), lisbon), "closed on sunday")
+	assert.True(t, hours.Contains(at(1, "22:00"), at(2, "00:00"), lisbon), "open until midnight")
+
+	// The interval is compared in the business's time zone: 08:30 UTC is 09:30 in Lisbon in summer
+	start := time.Date(2024, 6, 3, 8, 30, 0, 0, time.UTC)
+	assert.True(t, hours.Contains(start, start.Add(time.Hour), lisbon))
+}
+
+func TestAvailabilityExceptionBlocks(t *testing.T) {
+	start := time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC)
+	exception := &AvailabilityException{
+		ExceptionType: ExceptionTimeOff,
+		StartTime:     start,
+		EndTime:       start.Add(time.Hour),
+	}
+
+	assert.True(t, exception.Blocks(start.Add(30*time.Minute), start.Add(90*time.Minute)))
+	assert.False(t, exception.Blocks(start.Add(time.Hour), start.Add(2*time.Hour)), "touching intervals do not overlap")
+	assert.False(t, exception.Blocks(start.AddDate(0, 0, 7), start.AddDate(0, 0, 7).Add(time.Hour)))
+
+	weekly := "FREQ=WEEKLY"
+	exception.IsRecurring = true
+	exception.RecurrenceRule = &weekly
+	assert.True(t, exception.Blocks(start.AddDate(0, 0, 14), start.AddDate(0, 0, 14).Add(time.Hour)))
+	assert.False(t, exception.Blocks(start.AddDate(0, 0, 15), start.AddDate(0, 0, 15).Add(time.Hour)))
+	assert.False(t, exception.Blocks(start.AddDate(0, 0, -7), start.AddDate(0, 0, -7).Add(time.Hour)), "no occurrences before the first")
+
+	exception.ExceptionType = ExceptionCustomHours
+	assert.False(t, exception.Blocks(start, start.Add(time.Hour)))
+}
+
+func TestAvailabilityRulesCheck(t *testing.T) {
+	business := &Business{TimeZone: "UTC"}
+	settings := &BusinessSettings{CalendarStartHour: 9, CalendarEndHour: 18, AppointmentBufferMinutes: 15}
+	rules := NewAvailabilityRules(business, settings)
+	day := time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC)
+
+	busy := []*BusyInterval{
+		{AppointmentID: "existing", StartTime: day.Add(10 * time.Hour), EndTime: day.Add(11 * time.Hour)},
+	}
+	request := func(from, to time.Duration) AvailabilityRequest {
+		return AvailabilityRequest{StaffID: "staff", Start: day.Add(from), End: day.Add(to)}
+	}
+
+	assert.Empty(t, rules.Check(request(12*time.Hour, 13*time.Hour), busy, nil))
+
+	// Ending 10 minutes before the existing appointment leaves less than the buffer
+	conflicts := rules.Check(request(9*time.Hour, 9*time.Hour+50*time.Minute), busy, nil)
+	require.Len(t, conflicts, 1)
+	assert.Equal(t, ConflictOverlap, conflicts[0].Reason)
+	assert.Equal(t, "existing", *conflicts[0].AppointmentID)
+
+	// Starting right after the existing appointment ends also violates the buffer
+	require.Len(t, rules.Check(request(11*time.Hour, 12*time.Hour), busy, nil), 1)
+	assert.Empty(t, rules.Check(request(11*time.Hour+15*time.Minute, 12*time.Hour), busy, nil))
+
+	// Rescheduling an appointment does not conflict with itself
+	moved := request(10*time.Hour+30*time.Minute, 11*time.Hour+30*time.Minute)
+	existing := "existing"
+	moved.ExcludeAppointmentID = &existing
+	assert.Empty(t, rules.Check(moved, busy, nil))
+
+	// Outside the calendar hours and during time off
+	exception := &AvailabilityException{
+		BaseModel:     BaseModel{ID: "time-off"},
+		ExceptionType: ExceptionHoliday,
+		StartTime:     day.Add(17 * time.Hour),
+		EndTime:       day.Add(24 * time.Hour),
+	}
+	conflicts = rules.Check(request(17*time.Hour+30*time.Minute, 18*time.Hour+30*time.Minute), busy, []*AvailabilityException{exception})
+	require.Len(t, conflicts, 2)
+	assert.Equal(t, ConflictOutsideHours, conflicts[0].Reason)
+	assert.Equal(t, ConflictStaffUnavailable, conflicts[1].Reason)
+	assert.Equal(t, "time-off", *conflicts[1].ExceptionID)
+}
+
+func TestParseBookingDate(t *testing.T) {
+	lisbon, err := time.LoadLocation("Europe/Lisbon")
+	require.NoError(t, err)
+
+	day, err := ParseBookingDate("2024-06-03", lisbon)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 6, 3, 0, 0, 0, 0, lisbon), day)
+
+	_, err = ParseBookingDate("03/06/2024", lisbon)
+	assert.True(t, errors.Is(err, ErrValidation))
+}
+
+func TestAvailabilityRulesCandidateSlots(t *testing.T) {
+	lisbon, err := time.LoadLocation("Europe/Lisbon")
+	require.NoError(t, err)
+	rules := AvailabilityRules{
+		Location: lisbon,
+		Hours: WeeklyHours{
+			"monday": {IsOpen: true, OpenTime: "09:00", CloseTime: "10:30"},
+		},
+	}
+	monday := time.Date(2024, 6, 3, 0, 0, 0, 0, lisbon)
+	at := func(hour, minute int) time.Time { return time.Date(2024, 6, 3, hour, minute, 0, 0, lisbon) }
+
+	slots := rules.CandidateSlots(monday, time.Hour, 15*time.Minute, time.Time{})
+	assert.Equal(t, []time.Time{at(9, 0), at(9, 15), at(9, 30)}, slots)
+
+	slots = rules.CandidateSlots(monday, time.Hour, 15*time.Minute, at(9, 10))
+	assert.Equal(t, []time.Time{at(9, 15), at(9, 30)}, slots, "slots already started are not offered")
+
+	assert.Empty(t, rules.CandidateSlots(monday, 2*time.Hour, 15*time.Minute, time.Time{}), "too long for the opening hours")
+	assert.Empty(t, rules.CandidateSlots(monday.AddDate(0, 0, 1), time.Hour, 15*time.Minute, time.Time{}), "closed on tuesday")
+}
+
+func TestAvailabilityRulesFreeSlots(t *testing.T) {
+	business := &Business{TimeZone: "UTC"}
+	settings := &BusinessSettings{CalendarStartHour: 9, CalendarEndHour: 13, AppointmentBufferMinutes: 15}
+	rules := NewAvailabilityRules(business, settings)
+	day := time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) time.Time { return day.Add(d) }
+
+	schedule := &StaffSchedule{
+		StaffID: "staff",
+		Busy: []*BusyInterval{
+			{AppointmentID: "existing", StartTime: at(10 * time.Hour), EndTime: at(11 * time.Hour)},
+		},
+		Exceptions: []*AvailabilityException{{
+			ExceptionType: ExceptionTimeOff,
+			StartTime:     day.AddDate(0, 0, 1),
+			EndTime:       day.AddDate(0, 0, 2),
+		}},
+	}
+	search := SlotSearch{
+		Start:    day,
+		End:      day.AddDate(0, 0, 2),
+		Duration: time.Hour,
+		Interval: 30 * time.Minute,
+	}
+
+	// 9:00 leaves no buffer before the 10:00 appointment, 11:00 none after it, and 12:30 would
+	// end after closing. The next day is taken by time off.
+	assert.Equal(t, []time.Time{at(11*time.Hour + 30*time.Minute), at(12 * time.Hour)}, rules.FreeSlots(schedule, search))
+
+	search.Duration = 30 * time.Minute
+	assert.Equal(t, []time.Time{at(9 * time.Hour), at(11*time.Hour + 30*time.Minute), at(12 * time.Hour), at(12*time.Hour + 30*time.Minute)},
+		rules.FreeSlots(schedule, search))
+
+	search.NotBefore = at(12 * time.Hour)
+	assert.Equal(t, []time.Time{at(12 * time.Hour), at(12*time.Hour + 30*time.Minute)}, rules.FreeSlots(schedule, search))
+
+	search.NotAfter = at(12 * time.Hour)
+	assert.Equal(t, []time.Time{at(12 * time.Hour)}, rules.FreeSlots(schedule, search))
+}
+
+func TestAppointmentConflictError(t *testing.T) {
+	err := error(&AppointmentConflictError{Conflicts: []*AvailabilityConflict{
+		{Reason: ConflictOutsideHours, Message: "the business is closed at the requested time"},
+	}})
+	assert.True(t, errors.Is(err, ErrAppointmentConflict))
+	assert.Contains(t, err.Error(), "the business is closed")
+}
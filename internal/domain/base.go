@@ -16,6 +16,7 @@ type BaseModel struct {
 	UpdatedBy *string        `gorm:"type:uuid" json:"updated_by,omitempty"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
 	DeletedBy *string        `gorm:"type:uuid" json:"deleted_by,omitempty"`
+	Version   int            `gorm:"not null;default:1" json:"version"` // Incremented by every update, so concurrent updates can be told apart
 }
 
 // Entity interface that all domain models should implement
@@ -64,24 +65,77 @@ func (b *BaseModel) SetDeletedFields(userID *string) {
 	b.DeletedBy = userID
 }
 
+// DefaultBatchSize is how many rows a bulk insert writes per statement unless told otherwise,
+// keeping statements of wide tables within the 65535 parameters Postgres accepts
+const DefaultBatchSize = 500
+
+// ConflictAction is what a bulk insert does with the rows conflicting with stored ones
+type ConflictAction int
+
+const (
+	ConflictFail   ConflictAction = iota // The insert fails and writes nothing
+	ConflictSkip                         // Conflicting rows are left as they are stored
+	ConflictUpdate                       // Conflicting rows are overwritten with the new values
+)
+
+// BatchOptions configures a bulk insert
+type BatchOptions struct {
+	BatchSize       int            // Rows per statement; DefaultBatchSize when 0
+	Columns         []string       // Fields written, by name; every field when empty
+	OnConflict      ConflictAction // ConflictFail when unset
+	ConflictColumns []string       // Columns of the unique index conflicts are found on; the primary key when empty
+	UpdateColumns   []string       // Columns ConflictUpdate overwrites; every column when empty
+}
+
 // BaseRepository defines common repository operations
 type BaseRepository[T any] interface {
 	// Basic CRUD operations
 	Create(ctx context.Context, entity *T) error
 	GetByID(ctx context.Context, id string) (*T, error)
+	GetByIDs(ctx context.Context, ids []string) ([]*T, error)
 	Update(ctx context.Context, entity *T) error
+	// UpdateFields writes only the columns the changes touch, zero values included, and returns
+	// the updated entity. It fails with gorm.ErrRecordNotFound when no entity has the ID, and
+	// with a ConflictError when the entity was changed since the changes' version.
+	UpdateFields(ctx context.Context, id string, changes ChangeSet) (*T, error)
 	Delete(ctx context.Context, id string) error
 	
 	// List and search operations
 	List(ctx context.Context, page, pageSize int) ([]*T, int64, error)
+	ListAfter(ctx context.Context, cursor *Cursor, limit int) ([]*T, bool, error)
 	FindBy(ctx context.Context, criteria map[string]any) ([]*T, error)
 	ExistsByID(ctx context.Context, id string) (bool, error)
 	
+	// Bulk operations
+	// CreateBatch inserts the entities in statements of at most BatchSize rows, within one
+	// transaction so that a failure leaves none of them behind, and returns how many rows were
+	// inserted or overwritten. Entities recorded in the audit log only accept ConflictFail.
+	CreateBatch(ctx context.Context, entities []*T, opts BatchOptions) (int64, error)
+	// UpdateBatch updates the entities within one transaction, checking each like Update: a
+	// ConflictError on any of them leaves all of them unchanged
+	UpdateBatch(ctx context.Context, entities []*T) error
+	
+	// Trash operations
+	// ListDeleted lists the soft-deleted entities matching the criteria that were deleted at or
+	// after since, most recently deleted first
+	ListDeleted(ctx context.Context, criteria map[string]any, since time.Time, page, pageSize int) ([]*T, int64, error)
+	// Restore undoes the soft deletion of an entity, returning gorm.ErrRecordNotFound when no
+	// deleted entity has the ID
+	Restore(ctx context.Context, id string) error
+	
 	// Transaction operations
 	WithTx(tx *gorm.DB) BaseRepository[T]
 	GetDB() *gorm.DB
 }
 
+// UnitOfWork runs several repository calls atomically. Do runs fn in a transaction that is
+// committed when fn returns nil and rolled back when it returns an error or panics; the calls
+// fn makes with the context it is given join the transaction. A unit of work started within
+// another joins the outer one, rolling back only its own changes when it fails.
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
 // BaseService defines common service operations
 type BaseService[CreateDTO, UpdateDTO, ResponseDTO any] interface {
 	Create(ctx context.Context, dto CreateDTO) (*ResponseDTO, error)
@@ -89,4 +143,5 @@ type BaseService[CreateDTO, UpdateDTO, ResponseDTO any] interface {
 	Update(ctx context.Context, id string, dto UpdateDTO) (*ResponseDTO, error)
 	Delete(ctx context.Context, id string) error
 	List(ctx context.Context, page, pageSize int) ([]*ResponseDTO, int64, error)
+	ListAfter(ctx context.Context, after string, first int) (*Connection[ResponseDTO], error)
 }
\ No newline at end of file
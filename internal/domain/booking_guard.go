@@ -0,0 +1,178 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// BlocklistEntryType represents what a blocklist entry matches
+type BlocklistEntryType string
+
+const (
+	BlocklistPhone  BlocklistEntryType = "phone"
+	BlocklistEmail  BlocklistEntryType = "email"
+	BlocklistClient BlocklistEntryType = "client"
+)
+
+// IsValid checks if the entry type is valid
+func (t BlocklistEntryType) IsValid() bool {
+	return t == BlocklistPhone || t == BlocklistEmail || t == BlocklistClient
+}
+
+// BlocklistEntry prevents a phone number, email address or client from booking online with a business
+type BlocklistEntry struct {
+	BaseModel
+	BusinessID string             `gorm:"not null;type:uuid;index" json:"business_id"`
+	EntryType  BlocklistEntryType `gorm:"not null;size:20" json:"entry_type"`
+	Value      string             `gorm:"not null;size:255" json:"value"`
+	Reason     *string            `gorm:"type:text" json:"reason,omitempty"`
+	ExpiresAt  *time.Time         `json:"expires_at,omitempty"` // Nil blocks indefinitely
+}
+
+// TableName returns the table name for BlocklistEntry
+func (BlocklistEntry) TableName() string { return "booking_blocklist_entries" }
+
+// NormalizeBlocklistValue normalizes a value so that formatting differences do not
+// defeat the blocklist: phones are reduced to digits and emails are lower-cased
+func NormalizeBlocklistValue(entryType BlocklistEntryType, value string) string {
+	value = strings.TrimSpace(value)
+	switch entryType {
+	case BlocklistPhone:
+		return NormalizePhone(value)
+	case BlocklistEmail:
+		return strings.ToLower(value)
+	}
+	return value
+}
+
+// Validate normalizes and validates the blocklist entry
+func (e *BlocklistEntry) Validate() error {
+	if e.BusinessID == "" {
+		return fmt.Errorf("%w: business_id is required", ErrValidation)
+	}
+	if !e.EntryType.IsValid() {
+		return fmt.Errorf("%w: invalid entry type %q", ErrValidation, e.EntryType)
+	}
+
+	e.Value = NormalizeBlocklistValue(e.EntryType, e.Value)
+	switch e.EntryType {
+	case BlocklistPhone:
+		if len(e.Value) < 6 {
+			return fmt.Errorf("%w: phone number must have at least 6 digits", ErrValidation)
+		}
+	case BlocklistEmail:
+		if _, err := mail.ParseAddress(e.Value); err != nil {
+			return fmt.Errorf("%w: invalid email address", ErrValidation)
+		}
+	case BlocklistClient:
+		if e.Value == "" {
+			return fmt.Errorf("%w: client ID is required", ErrValidation)
+		}
+	}
+	return nil
+}
+
+// IsActive checks whether the entry still blocks bookings at the given time
+func (e *BlocklistEntry) IsActive(at time.Time) bool {
+	return e.ExpiresAt == nil || e.ExpiresAt.After(at)
+}
+
+// BlocklistKey identifies a value to look up in a business's blocklist
+type BlocklistKey struct {
+	EntryType BlocklistEntryType
+	Value     string
+}
+
+// BookingAttempt describes who is trying to book online
+type BookingAttempt struct {
+	BusinessID string
+	ClientID   *string // Set when the booker is a known client
+	Phone      *string
+	Email      *string
+	IPAddress  string
+}
+
+// BlocklistKeys returns the normalized blocklist values the attempt must be checked against
+func (a BookingAttempt) BlocklistKeys() []BlocklistKey {
+	var keys []BlocklistKey
+	add := func(entryType BlocklistEntryType, value *string) {
+		if value == nil {
+			return
+		}
+		if normalized := NormalizeBlocklistValue(entryType, *value); normalized != "" {
+			keys = append(keys, BlocklistKey{EntryType: entryType, Value: normalized})
+		}
+	}
+	add(BlocklistClient, a.ClientID)
+	add(BlocklistPhone, a.Phone)
+	add(BlocklistEmail, a.Email)
+	return keys
+}
+
+// BookingOutcome is the result of checking a booking attempt
+type BookingOutcome string
+
+const (
+	BookingAllowed         BookingOutcome = "allowed"
+	BookingBlocklisted     BookingOutcome = "blocklisted"
+	BookingCaptchaRequired BookingOutcome = "captcha_required"
+	BookingRateLimited     BookingOutcome = "rate_limited"
+)
+
+// BookingAttemptRecord is a public booking attempt, kept for velocity checks
+type BookingAttemptRecord struct {
+	ID         string         `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	IPAddress  string         `gorm:"not null;size:45" json:"ip_address"`
+	BusinessID string         `gorm:"not null;type:uuid" json:"business_id"`
+	Outcome    BookingOutcome `gorm:"not null;size:20" json:"outcome"`
+	CreatedAt  time.Time      `gorm:"not null;default:now()" json:"created_at"`
+}
+
+// TableName returns the table name for BookingAttemptRecord
+func (BookingAttemptRecord) TableName() string { return "booking_attempts" }
+
+// VelocityPolicy limits how many booking attempts a single IP address can make across all
+// businesses. Above CaptchaThreshold attempts within Window the booker must solve a CAPTCHA;
+// above BlockThreshold attempts are rejected outright.
+type VelocityPolicy struct {
+	Window           time.Duration
+	CaptchaThreshold int
+	BlockThreshold   int
+}
+
+// DefaultVelocityPolicy is the velocity policy of the public booking API
+var DefaultVelocityPolicy = VelocityPolicy{
+	Window:           time.Hour,
+	CaptchaThreshold: 5,
+	BlockThreshold:   20,
+}
+
+// Evaluate returns the outcome for an IP address that has already made the given number of
+// attempts within the window
+func (p VelocityPolicy) Evaluate(previousAttempts int) BookingOutcome {
+	switch {
+	case previousAttempts >= p.BlockThreshold:
+		return BookingRateLimited
+	case previousAttempts >= p.CaptchaThreshold:
+		return BookingCaptchaRequired
+	default:
+		return BookingAllowed
+	}
+}
+
+// BlocklistRepository defines the repository interface for BlocklistEntry
+type BlocklistRepository interface {
+	BaseRepository[BlocklistEntry]
+	FindByBusinessID(ctx context.Context, businessID string) ([]*BlocklistEntry, error)
+	FindMatch(ctx context.Context, businessID string, keys []BlocklistKey, at time.Time) (*BlocklistEntry, error)
+}
+
+// BookingAttemptRepository defines the repository interface for BookingAttemptRecord
+type BookingAttemptRepository interface {
+	Record(ctx context.Context, attempt *BookingAttemptRecord) error
+	CountByIP(ctx context.Context, ipAddress string, since time.Time) (int64, error)
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
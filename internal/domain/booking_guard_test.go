@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlocklistEntryValidate(t *testing.T) {
+	entry := &BlocklistEntry{BusinessID: "b1", EntryType: BlocklistPhone, Value: "+351 912-345-678"}
+	assert.NoError(t, entry.Validate())
+	assert.Equal(t, "351912345678", entry.Value)
+
+	entry = &BlocklistEntry{BusinessID: "b1", EntryType: BlocklistEmail, Value: " Maria@Example.COM "}
+	assert.NoError(t, entry.Validate())
+	assert.Equal(t, "maria@example.com", entry.Value)
+
+	invalid := []*BlocklistEntry{
+		{EntryType: BlocklistPhone, Value: "912345678"},
+		{BusinessID: "b1", EntryType: "ip", Value: "10.0.0.1"},
+		{BusinessID: "b1", EntryType: BlocklistPhone, Value: "123"},
+		{BusinessID: "b1", EntryType: BlocklistEmail, Value: "not-an-email"},
+		{BusinessID: "b1", EntryType: BlocklistClient, Value: " "},
+	}
+	for _, entry := range invalid {
+		assert.True(t, errors.Is(entry.Validate(), ErrValidation), "%+v", entry)
+	}
+}
+
+func TestBlocklistEntryIsActive(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	assert.True(t, (&BlocklistEntry{}).IsActive(now))
+	assert.True(t, (&BlocklistEntry{ExpiresAt: &future}).IsActive(now))
+	assert.False(t, (&BlocklistEntry{ExpiresAt: &past}).IsActive(now))
+}
+
+func TestBookingAttemptBlocklistKeys(t *testing.T) {
+	clientID := "c1"
+	phone := "(912) 345-678"
+	email := "Maria@Example.com"
+	blank := "  "
+
+	keys := BookingAttempt{ClientID: &clientID, Phone: &phone, Email: &email}.BlocklistKeys()
+	assert.Equal(t, []BlocklistKey{
+		{EntryType: BlocklistClient, Value: "c1"},
+		{EntryType: BlocklistPhone, Value: "912345678"},
+		{EntryType: BlocklistEmail, Value: "maria@example.com"},
+	}, keys)
+
+	assert.Empty(t, BookingAttempt{Email: &blank}.BlocklistKeys())
+}
+
+func TestVelocityPolicyEvaluate(t *testing.T) {
+	policy := VelocityPolicy{Window: time.Hour, CaptchaThreshold: 5, BlockThreshold: 20}
+
+	assert.Equal(t, BookingAllowed, policy.Evaluate(0))
+	assert.Equal(t, BookingAllowed, policy.Evaluate(4))
+	assert.Equal(t, BookingCaptchaRequired, policy.Evaluate(5))
+	assert.Equal(t, BookingCaptchaRequired, policy.Evaluate(19))
+	assert.Equal(t, BookingRateLimited, policy.Evaluate(20))
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	MaxBookingLeadHours   = 168 // Longest notice a business may require, a week
+	MaxBookingHorizonDays = 730 // Furthest ahead a business may limit bookings to, two years
+)
+
+// BookingWindow is how far ahead clients may book a service
+type BookingWindow struct {
+	MinLeadHours   int // Shortest notice a booking needs; 0 allows booking until the appointment starts
+	MaxHorizonDays int // Furthest ahead a booking may start; 0 for no limit
+}
+
+// NewBookingWindow returns the booking window of a service: the limits the service sets
+// itself, and the business's for the others
+func NewBookingWindow(settings *BusinessSettings, service *Service) BookingWindow {
+	var window BookingWindow
+	if settings != nil {
+		window = BookingWindow{MinLeadHours: settings.MinBookingLeadHours, MaxHorizonDays: settings.MaxBookingHorizonDays}
+	}
+	if service != nil && service.MinAdvanceBooking != nil {
+		window.MinLeadHours = *service.MinAdvanceBooking
+	}
+	if service != nil && service.MaxAdvanceBooking != nil {
+		window.MaxHorizonDays = *service.MaxAdvanceBooking
+	}
+	return window
+}
+
+// Earliest returns the earliest a booking made now may start
+func (w BookingWindow) Earliest(now time.Time) time.Time {
+	return now.Add(time.Duration(w.MinLeadHours) * time.Hour)
+}
+
+// Latest returns the latest a booking made now may start, or the zero time when there is no
+// limit
+func (w BookingWindow) Latest(now time.Time) time.Time {
+	if w.MaxHorizonDays == 0 {
+		return time.Time{}
+	}
+	return now.AddDate(0, 0, w.MaxHorizonDays)
+}
+
+// Check checks that a booking made now may start at a time, explaining to the client why not
+func (w BookingWindow) Check(start, now time.Time) error {
+	if !start.After(now) {
+		return fmt.Errorf("%w: the selected time has already passed", ErrValidation)
+	}
+	if start.Before(w.Earliest(now)) {
+		return fmt.Errorf("%w: this service must be booked at least %s ahead", ErrValidation, countOf(w.MinLeadHours, "hour"))
+	}
+	if latest := w.Latest(now); !latest.IsZero() && start.After(latest) {
+		return fmt.Errorf("%w: this service can be booked at most %s ahead", ErrValidation, countOf(w.MaxHorizonDays, "day"))
+	}
+	return nil
+}
+
+// countOf formats a count of a unit, e.g. 1 hour or 2 hours
+func countOf(n int, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, unit)
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewBookingWindow(t *testing.T) {
+	settings := &BusinessSettings{MinBookingLeadHours: 2, MaxBookingHorizonDays: 60}
+	assert.Equal(t, BookingWindow{MinLeadHours: 2, MaxHorizonDays: 60}, NewBookingWindow(settings, &Service{}))
+
+	noLead, shortHorizon := 0, 14
+	service := &Service{MinAdvanceBooking: &noLead, MaxAdvanceBooking: &shortHorizon}
+	assert.Equal(t, BookingWindow{MinLeadHours: 0, MaxHorizonDays: 14}, NewBookingWindow(settings, service))
+
+	assert.Equal(t, BookingWindow{}, NewBookingWindow(nil, nil))
+}
+
+func TestBookingWindow_Check(t *testing.T) {
+	now := time.Date(2024, 6, 3, 10, 0, 0, 0, time.UTC)
+	window := BookingWindow{MinLeadHours: 2, MaxHorizonDays: 60}
+
+	assert.NoError(t, window.Check(now.Add(2*time.Hour), now))
+	assert.NoError(t, window.Check(now.AddDate(0, 0, 60), now))
+
+	err := window.Check(now.Add(time.Hour), now)
+	assert.ErrorIs(t, err, ErrValidation)
+	assert.Contains(t, err.Error(), "at least 2 hours ahead")
+
+	err = window.Check(now.AddDate(0, 0, 61), now)
+	assert.ErrorIs(t, err, ErrValidation)
+	assert.Contains(t, err.Error(), "at most 60 days ahead")
+
+	err = BookingWindow{}.Check(now, now)
+	assert.ErrorIs(t, err, ErrValidation)
+	assert.Contains(t, err.Error(), "already passed")
+
+	assert.NoError(t, BookingWindow{}.Check(now.AddDate(5, 0, 0), now), "no horizon without a limit")
+}
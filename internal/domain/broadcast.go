@@ -0,0 +1,170 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// BroadcastRecipientStatus represents whether a client was reached by a broadcast
+type BroadcastRecipientStatus string
+
+const (
+	BroadcastRecipientPending     BroadcastRecipientStatus = "pending"
+	BroadcastRecipientNotified    BroadcastRecipientStatus = "notified"    // Delivered on at least one channel
+	BroadcastRecipientFailed      BroadcastRecipientStatus = "failed"      // Every delivery attempt failed
+	BroadcastRecipientUnreachable BroadcastRecipientStatus = "unreachable" // The client has no phone number or email address
+)
+
+// MaxBroadcastWindow is the longest time window a broadcast may cover
+const MaxBroadcastWindow = 14 * 24 * time.Hour
+
+// RescheduleLinkVariable is the placeholder replaced by a client's rescheduling link
+const RescheduleLinkVariable = "reschedule.link"
+
+// Broadcast is an urgent notice sent to every client with an appointment in a time window,
+// e.g. when the salon has to close because of a power outage
+type Broadcast struct {
+	BaseModel
+	BusinessID       string     `gorm:"not null;type:uuid;index" json:"business_id"`
+	Subject          *string    `gorm:"size:200" json:"subject,omitempty"` // Email only
+	Message          string     `gorm:"not null;type:text" json:"message"`
+	WindowStart      time.Time  `gorm:"not null" json:"window_start"`
+	WindowEnd        time.Time  `gorm:"not null" json:"window_end"`
+	OfferReschedule  bool       `gorm:"not null;default:true" json:"offer_reschedule"` // Include a link to pick a new time
+	AppointmentCount int        `gorm:"not null;default:0" json:"appointment_count"`
+	NotifiedCount    int        `gorm:"not null;default:0" json:"notified_count"`
+	FailedCount      int        `gorm:"not null;default:0" json:"failed_count"` // Failed or unreachable
+	SentAt           *time.Time `json:"sent_at,omitempty"`
+}
+
+// TableName returns the table name for Broadcast
+func (Broadcast) TableName() string { return "broadcasts" }
+
+// Validate validates the broadcast model
+func (b *Broadcast) Validate() error {
+	if b.BusinessID == "" {
+		return ErrValidation
+	}
+	if strings.TrimSpace(b.Message) == "" {
+		return fmt.Errorf("%w: the message is empty", ErrValidation)
+	}
+	if !b.WindowEnd.After(b.WindowStart) {
+		return fmt.Errorf("%w: the window must end after it starts", ErrValidation)
+	}
+	if b.WindowEnd.Sub(b.WindowStart) > MaxBroadcastWindow {
+		return fmt.Errorf("%w: the window cannot be longer than %d days", ErrValidation, int(MaxBroadcastWindow.Hours()/24))
+	}
+	return nil
+}
+
+// Render renders the broadcast message for one recipient. When rescheduling is offered and the
+// message has no {{reschedule.link}} placeholder, the link is appended.
+func (b *Broadcast) Render(values map[string]string, rescheduleLink string) string {
+	text := b.Message
+	if b.OfferReschedule && rescheduleLink != "" {
+		values[RescheduleLinkVariable] = rescheduleLink
+		if !usesTemplateVariable(text, RescheduleLinkVariable) {
+			text = strings.TrimRight(text, "\n ") + "\n\nPick a new time: {{" + RescheduleLinkVariable + "}}"
+		}
+	}
+	return RenderTemplateText(text, values)
+}
+
+// usesTemplateVariable reports whether a text has a placeholder for the variable
+func usesTemplateVariable(text, name string) bool {
+	for _, match := range templatePlaceholder.FindAllStringSubmatch(text, -1) {
+		if match[1] == name {
+			return true
+		}
+	}
+	return false
+}
+
+// BroadcastRecipient is an appointment affected by a broadcast and whether its client was reached
+type BroadcastRecipient struct {
+	BaseModel
+	BroadcastID     string                   `gorm:"not null;type:uuid;index" json:"broadcast_id"`
+	AppointmentID   string                   `gorm:"not null;type:uuid" json:"appointment_id"`
+	ClientID        string                   `gorm:"not null;type:uuid" json:"client_id"`
+	Status          BroadcastRecipientStatus `gorm:"not null;size:20;default:'pending'" json:"status"`
+	Channels        *string                  `gorm:"type:jsonb;default:'[]'" json:"channels,omitempty"` // JSON array of MessageChannel
+	Error           *string                  `gorm:"type:text" json:"error,omitempty"`
+	RescheduleToken *string                  `gorm:"size:64" json:"-"`
+	RescheduledAt   *time.Time               `json:"rescheduled_at,omitempty"`
+}
+
+// TableName returns the table name for BroadcastRecipient
+func (BroadcastRecipient) TableName() string { return "broadcast_recipients" }
+
+// GetChannels decodes the channels the notice was delivered on
+func (r *BroadcastRecipient) GetChannels() []MessageChannel {
+	channels := []MessageChannel{}
+	if r.Channels != nil && *r.Channels != "" {
+		_ = json.Unmarshal([]byte(*r.Channels), &channels)
+	}
+	return channels
+}
+
+// RecordDelivery records the outcome of delivering the notice on each attempted channel
+func (r *BroadcastRecipient) RecordDelivery(delivered []MessageChannel, failures []string) {
+	if delivered == nil {
+		delivered = []MessageChannel{}
+	}
+	data, _ := json.Marshal(delivered)
+	encoded := string(data)
+	r.Channels = &encoded
+
+	switch {
+	case len(delivered) > 0:
+		r.Status = BroadcastRecipientNotified
+	case len(failures) > 0:
+		r.Status = BroadcastRecipientFailed
+	default:
+		r.Status = BroadcastRecipientUnreachable
+	}
+	if len(failures) > 0 {
+		message := strings.Join(failures, "; ")
+		r.Error = &message
+	}
+}
+
+// BroadcastTarget is an appointment in a broadcast window with the contact details of its
+// client, or of their guardian
+type BroadcastTarget struct {
+	AppointmentID   string    `json:"appointment_id"`
+	ClientID        string    `json:"client_id"`
+	StartTime       time.Time `json:"start_time"`
+	EndTime         time.Time `json:"end_time"`
+	ClientFirstName string    `json:"client_first_name"`
+	ClientLastName  string    `json:"client_last_name"`
+	ClientPhone     *string   `json:"client_phone,omitempty"`
+	ClientEmail     *string   `json:"client_email,omitempty"`
+}
+
+// Channels returns every channel the client can be reached on; urgent notices go out on all of them
+func (t BroadcastTarget) Channels() map[MessageChannel]string {
+	channels := map[MessageChannel]string{}
+	if t.ClientPhone != nil && len(NormalizePhone(*t.ClientPhone)) >= 6 {
+		channels[MessageChannelSMS] = *t.ClientPhone
+	}
+	if t.ClientEmail != nil && strings.TrimSpace(*t.ClientEmail) != "" {
+		channels[MessageChannelEmail] = strings.TrimSpace(*t.ClientEmail)
+	}
+	return channels
+}
+
+// BroadcastRepository defines the repository interface for Broadcast
+type BroadcastRepository interface {
+	BaseRepository[Broadcast]
+	FindByBusinessID(ctx context.Context, businessID string) ([]*Broadcast, error)
+	// FindTargets finds the business's scheduled and confirmed appointments overlapping a window
+	FindTargets(ctx context.Context, businessID string, start, end time.Time) ([]*BroadcastTarget, error)
+	// AddRecipients records the recipients of a broadcast and marks their appointments as affected
+	AddRecipients(ctx context.Context, broadcast *Broadcast, recipients []*BroadcastRecipient) error
+	UpdateRecipient(ctx context.Context, recipient *BroadcastRecipient) error
+	FindRecipients(ctx context.Context, broadcastID string) ([]*BroadcastRecipient, error)
+	FindRecipientByToken(ctx context.Context, token string) (*BroadcastRecipient, error)
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBroadcast_Validate(t *testing.T) {
+	start := time.Date(2026, time.October, 17, 9, 0, 0, 0, time.UTC)
+	broadcast := &Broadcast{BusinessID: "business-1", Message: "We are closed today", WindowStart: start, WindowEnd: start.Add(10 * time.Hour)}
+	assert.NoError(t, broadcast.Validate())
+
+	empty := *broadcast
+	empty.Message = "  "
+	assert.ErrorIs(t, empty.Validate(), ErrValidation)
+
+	backwards := *broadcast
+	backwards.WindowEnd = start
+	assert.ErrorIs(t, backwards.Validate(), ErrValidation)
+
+	tooLong := *broadcast
+	tooLong.WindowEnd = start.Add(MaxBroadcastWindow + time.Hour)
+	assert.ErrorIs(t, tooLong.Validate(), ErrValidation)
+}
+
+func TestBroadcast_Render(t *testing.T) {
+	link := "https://beautix.example/reschedule/abc"
+
+	broadcast := &Broadcast{Message: "Hi {{client.first_name}}, we are closed due to a power cut.", OfferReschedule: true}
+	body := broadcast.Render(map[string]string{"client.first_name": "Ana"}, link)
+	assert.Equal(t, "Hi Ana, we are closed due to a power cut.\n\nPick a new time: "+link, body, "the link is appended when not used")
+
+	broadcast.Message = "Closed today. Rebook at {{ reschedule.link }} - sorry!"
+	body = broadcast.Render(map[string]string{}, link)
+	assert.Equal(t, "Closed today. Rebook at "+link+" - sorry!", body, "the link is not repeated")
+
+	broadcast.OfferReschedule = false
+	broadcast.Message = "Closed today."
+	assert.Equal(t, "Closed today.", broadcast.Render(map[string]string{}, link))
+}
+
+func TestBroadcastRecipient_RecordDelivery(t *testing.T) {
+	recipient := &BroadcastRecipient{}
+	recipient.RecordDelivery([]MessageChannel{MessageChannelEmail}, []string{"sms: provider down"})
+	assert.Equal(t, BroadcastRecipientNotified, recipient.Status)
+	assert.Equal(t, []MessageChannel{MessageChannelEmail}, recipient.GetChannels())
+	assert.Equal(t, "sms: provider down", *recipient.Error)
+
+	recipient = &BroadcastRecipient{}
+	recipient.RecordDelivery(nil, []string{"sms: provider down"})
+	assert.Equal(t, BroadcastRecipientFailed, recipient.Status)
+	assert.Empty(t, recipient.GetChannels())
+
+	recipient = &BroadcastRecipient{}
+	recipient.RecordDelivery(nil, nil)
+	assert.Equal(t, BroadcastRecipientUnreachable, recipient.Status)
+	assert.Nil(t, recipient.Error)
+}
+
+func TestBroadcastTarget_Channels(t *testing.T) {
+	phone, shortPhone, email := "+351 910 000 000", "123", " ana@example.com "
+
+	assert.Equal(t, map[MessageChannel]string{MessageChannelSMS: phone, MessageChannelEmail: "ana@example.com"},
+		BroadcastTarget{ClientPhone: &phone, ClientEmail: &email}.Channels())
+	assert.Equal(t, map[MessageChannel]string{}, BroadcastTarget{ClientPhone: &shortPhone}.Channels())
+}
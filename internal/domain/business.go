@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
 )
 
@@ -28,6 +29,16 @@ type Business struct {
 	IsActive            bool    `gorm:"not null;default:true" json:"is_active"`
 	SubscriptionTier    string  `gorm:"size:50;default:'free'" json:"subscription_tier"`
 	TrialEndsAt         *time.Time `gorm:"" json:"trial_ends_at,omitempty"`
+	TrialExpiredAt      *time.Time `gorm:"" json:"trial_expired_at,omitempty"` // When the business was restricted after its trial
+	SuspendedAt         *time.Time `gorm:"" json:"suspended_at,omitempty"`      // When platform operators suspended the business
+	SuspensionReason    *string    `gorm:"size:500" json:"suspension_reason,omitempty"`
+	DataRegion          DataRegion `gorm:"size:10;not null;default:'eu'" json:"data_region"` // Where the business's data must stay
+	Mode                BusinessMode `gorm:"size:10;not null;default:'team'" json:"mode"` // Decides the capabilities the business has
+	IsSandbox           bool       `gorm:"not null;default:false" json:"is_sandbox"` // A demo tenant whose data can be reset
+	IsTemplate          bool       `gorm:"not null;default:false" json:"is_template"` // Kept to clone new businesses from; cannot be booked
+	AttachmentQuotaMB   *int       `gorm:"" json:"attachment_quota_mb,omitempty"` // Storage for client attachments; DefaultAttachmentQuotaMB when nil
+	AverageRating       *decimal.Decimal `gorm:"type:decimal(3,2)" json:"average_rating,omitempty"` // Of its published reviews; nil without any
+	ReviewCount         int        `gorm:"not null;default:0" json:"review_count"`                 // Published reviews
 
 	// Relationships
 	User             User               `gorm:"foreignKey:UserID" json:"user"`
@@ -73,6 +84,19 @@ type BusinessSettings struct {
 	Currency                     string  `gorm:"not null;size:3;default:'EUR'" json:"currency"`
 	DateFormat                   string  `gorm:"not null;size:20;default:'DD-MM-YYYY'" json:"date_format"`
 	TimeFormat                   string  `gorm:"not null;size:10;default:'24h'" json:"time_format"`
+	ConfirmationLeadHours        int     `gorm:"not null;default:24" json:"confirmation_lead_hours"` // 0 disables confirmation requests
+	ConfirmationCutoffHours      int     `gorm:"not null;default:4" json:"confirmation_cutoff_hours"` // Unacknowledged requests escalate after this; 0 disables escalation
+	CapacityWarningPercent       int     `gorm:"not null;default:90" json:"capacity_warning_percent"`     // 0 disables day capacity warnings
+	MaxContinuousWorkMinutes     int     `gorm:"not null;default:240" json:"max_continuous_work_minutes"` // 0 disables break warnings
+	MinBreakMinutes              int     `gorm:"not null;default:15" json:"min_break_minutes"`
+	MinBookingLeadHours          int     `gorm:"not null;default:0" json:"min_booking_lead_hours"`   // 0 allows booking until the appointment starts
+	MaxBookingHorizonDays        int     `gorm:"not null;default:0" json:"max_booking_horizon_days"` // 0 allows booking any time ahead
+	DepositWindowHours           int     `gorm:"not null;default:24" json:"deposit_window_hours"`    // Unpaid deposits release the slot after this; 0 keeps it booked
+	VIPRules                     *string `gorm:"type:jsonb" json:"vip_rules,omitempty"`              // JSON VIPRules; disabled when nil
+	CancellationNoticeHours      int     `gorm:"not null;default:0" json:"cancellation_notice_hours"` // Later cancellations count as no-shows; 0 allows cancelling until the start
+	NoShowFee                    *decimal.Decimal `gorm:"type:decimal(10,2)" json:"no_show_fee,omitempty"` // Charged for no-shows and late cancellations; none when nil
+	MaxNoShows                   int     `gorm:"not null;default:0" json:"max_no_shows"` // No-shows flagging a client as needing a deposit; 0 never flags
+	ReviewRequestDelayHours      int     `gorm:"not null;default:2" json:"review_request_delay_hours"` // Clients are asked for a review this long after their visit; 0 disables review requests
 
 	// Relationships
 	Business Business `gorm:"foreignKey:BusinessID;constraint:OnDelete:CASCADE" json:"business"`
@@ -133,6 +157,9 @@ func (b *Business) Validate() error {
 	if b.TimeZone == "" {
 		return ErrValidation
 	}
+	if b.Mode != "" && !b.Mode.IsValid() {
+		return ErrValidation
+	}
 	return nil
 }
 
@@ -147,6 +174,9 @@ func (b *Business) BeforeCreate(tx *gorm.DB) error {
 	if b.SubscriptionTier == "" {
 		b.SubscriptionTier = "free"
 	}
+	if b.Mode == "" {
+		b.Mode = BusinessModeTeam
+	}
 	return nil
 }
 
@@ -164,6 +194,15 @@ func (bl *BusinessLocation) Validate() error {
 	return nil
 }
 
+// NewBusinessSettings returns the settings a new business starts with, in the business's
+// currency. The other settings are left zero for the column defaults to apply on creation.
+func NewBusinessSettings(business *Business) *BusinessSettings {
+	return &BusinessSettings{
+		BusinessID: business.ID,
+		Currency:   business.Currency,
+	}
+}
+
 // Validate validates the business settings model
 func (bs *BusinessSettings) Validate() error {
 	if bs.BusinessID == "" {
@@ -178,6 +217,36 @@ func (bs *BusinessSettings) Validate() error {
 	if bs.CalendarStartHour >= bs.CalendarEndHour {
 		return ErrValidation
 	}
+	if bs.ConfirmationLeadHours < 0 || bs.ConfirmationLeadHours > 168 {
+		return ErrValidation
+	}
+	if bs.ConfirmationCutoffHours < 0 || bs.ConfirmationCutoffHours > MaxConfirmationCutoffHours {
+		return ErrValidation
+	}
+	if bs.CapacityWarningPercent < 0 || bs.CapacityWarningPercent > 100 {
+		return ErrValidation
+	}
+	if bs.MaxContinuousWorkMinutes < 0 || bs.MaxContinuousWorkMinutes > 720 {
+		return ErrValidation
+	}
+	if bs.MinBreakMinutes < 5 || bs.MinBreakMinutes > 120 {
+		return ErrValidation
+	}
+	if bs.MinBookingLeadHours < 0 || bs.MinBookingLeadHours > MaxBookingLeadHours {
+		return ErrValidation
+	}
+	if bs.MaxBookingHorizonDays < 0 || bs.MaxBookingHorizonDays > MaxBookingHorizonDays {
+		return ErrValidation
+	}
+	if bs.DepositWindowHours < 0 || bs.DepositWindowHours > MaxDepositWindowHours {
+		return ErrValidation
+	}
+	if bs.ReviewRequestDelayHours < 0 || bs.ReviewRequestDelayHours > MaxReviewRequestDelayHours {
+		return ErrValidation
+	}
+	if err := bs.GetCancellationPolicy().Validate(); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -188,10 +257,13 @@ type BusinessRepository interface {
 	FindByName(ctx context.Context, name string) ([]*Business, error)
 	ExistsByName(ctx context.Context, name string) (bool, error)
 	FindActiveBusinesses(ctx context.Context, page, pageSize int) ([]*Business, int64, error)
+	// Search finds the businesses of every tenant passing a filter, most recently created first
+	Search(ctx context.Context, search BusinessSearch, page, pageSize int) ([]*Business, int64, error)
 	SearchByLocation(ctx context.Context, city, country string) ([]*Business, error)
 	SearchByService(ctx context.Context, serviceName string) ([]*Business, error)
 	GetBusinessWithDetails(ctx context.Context, businessID string) (*Business, error)
 	GetWithLocations(ctx context.Context, businessID string) (*Business, error)
+	SetDataRegion(ctx context.Context, businessID string, region DataRegion, updatedBy *string) error
 }
 
 // BusinessLocationRepository defines the repository interface for BusinessLocation
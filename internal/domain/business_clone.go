@@ -0,0 +1,218 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// BusinessTemplate is the configuration of a business that a new business can be cloned from.
+// Clients, appointments and everything else that happened at the business are not part of it.
+type BusinessTemplate struct {
+	Business         *Business
+	Settings         *BusinessSettings // Nil when the business kept the defaults
+	Categories       []*ServiceCategory
+	Services         []*Service
+	Bundles          []*ServiceBundle
+	MessageTemplates []*MessageTemplate
+	LoyaltyPrograms  []*LoyaltyProgram
+	Staff            []*Staff // Active staff, whose roles and permissions can be copied
+}
+
+// BusinessCloneOptions are what a cloned business does not take from its template
+type BusinessCloneOptions struct {
+	Name           string
+	DisplayName    *string
+	Email          string // The template's when empty
+	TimeZone       string // The template's when empty
+	CopyStaffRoles bool   // Give the template's staff the same roles and permissions in the clone
+}
+
+// BusinessClone is a new business and the configuration it copies from a template, ready to be
+// stored in one go. Every copy has a new ID, and references between copies follow them.
+type BusinessClone struct {
+	Business         *Business
+	Settings         *BusinessSettings
+	Categories       []*ServiceCategory
+	Services         []*Service
+	Bundles          []*ServiceBundle
+	MessageTemplates []*MessageTemplate
+	LoyaltyPrograms  []*LoyaltyProgram
+	Staff            []*Staff // The owner first
+	Hours            []*BusinessHours
+}
+
+// NewBusinessClone copies a template into a new business owned by the given user, who becomes
+// its owner whatever their role at the template. The clone is never a template or a sandbox
+// itself, and starts on the free tier. Staff roles are copied only when asked for, and the
+// template's owner is not copied.
+func NewBusinessClone(template *BusinessTemplate, options BusinessCloneOptions, ownerID string, now time.Time, by *string) (*BusinessClone, error) {
+	source := template.Business
+	business := &Business{
+		BaseModel:     newCloneModel(by, now),
+		UserID:        ownerID,
+		Name:          strings.TrimSpace(options.Name),
+		DisplayName:   options.DisplayName,
+		BusinessType:  source.BusinessType,
+		Email:         strings.ToLower(options.Email),
+		Website:       source.Website,
+		LogoURL:       source.LogoURL,
+		CoverPhotoURL: source.CoverPhotoURL,
+		SocialLinks:   source.SocialLinks,
+		Settings:      source.Settings,
+		Currency:      source.Currency,
+		TimeZone:      options.TimeZone,
+		BusinessHours: source.BusinessHours,
+		IsActive:      true,
+		DataRegion:    source.DataRegion,
+		Mode:          source.GetMode(),
+	}
+	if business.Email == "" {
+		business.Email = source.Email
+	}
+	if business.TimeZone == "" {
+		business.TimeZone = source.TimeZone
+	}
+	if err := business.Validate(); err != nil {
+		return nil, err
+	}
+	clone := &BusinessClone{Business: business}
+	if hours, err := ParseWeeklyHours(business.BusinessHours); err == nil {
+		for _, row := range hours.Rows(business.ID, nil) {
+			row.BaseModel = newCloneModel(by, now)
+			clone.Hours = append(clone.Hours, row)
+		}
+	}
+
+	if template.Settings != nil {
+		settings := *template.Settings
+		settings.BaseModel = newCloneModel(by, now)
+		settings.BusinessID = business.ID
+		settings.Business = Business{}
+		clone.Settings = &settings
+	}
+
+	categoryIDs := make(map[string]string, len(template.Categories))
+	for _, source := range template.Categories {
+		category := *source
+		category.BaseModel = newCloneModel(by, now)
+		category.BusinessID = business.ID
+		category.Business = Business{}
+		category.Services = nil
+		categoryIDs[source.ID] = category.ID
+		clone.Categories = append(clone.Categories, &category)
+	}
+
+	serviceIDs := make(map[string]string, len(template.Services))
+	for _, source := range template.Services {
+		service := *source
+		service.BaseModel = newCloneModel(by, now)
+		service.BusinessID = business.ID
+		service.Business = Business{}
+		service.Category = nil
+		if source.CategoryID != nil {
+			// Services of a category the template no longer has are left uncategorised
+			if categoryID, ok := categoryIDs[*source.CategoryID]; ok {
+				service.CategoryID = &categoryID
+			} else {
+				service.CategoryID = nil
+			}
+		}
+		serviceIDs[source.ID] = service.ID
+		clone.Services = append(clone.Services, &service)
+	}
+
+	for _, source := range template.Bundles {
+		bundle := *source
+		bundle.BaseModel = newCloneModel(by, now)
+		bundle.BusinessID = business.ID
+		items, err := cloneBundleItems(source, serviceIDs)
+		if err != nil {
+			return nil, err
+		}
+		bundle.Items = items
+		clone.Bundles = append(clone.Bundles, &bundle)
+	}
+
+	for _, source := range template.MessageTemplates {
+		messageTemplate := *source
+		messageTemplate.BaseModel = newCloneModel(by, now)
+		messageTemplate.BusinessID = business.ID
+		messageTemplate.Business = Business{}
+		clone.MessageTemplates = append(clone.MessageTemplates, &messageTemplate)
+	}
+
+	for _, source := range template.LoyaltyPrograms {
+		program := *source
+		program.BaseModel = newCloneModel(by, now)
+		program.BusinessID = business.ID
+		clone.LoyaltyPrograms = append(clone.LoyaltyPrograms, &program)
+	}
+
+	clone.Staff = append(clone.Staff, &Staff{
+		BaseModel:  newCloneModel(by, now),
+		BusinessID: business.ID,
+		UserID:     ownerID,
+		Role:       BusinessRoleOwner,
+		IsActive:   true,
+		StartDate:  &now,
+	})
+	if options.CopyStaffRoles {
+		for _, source := range template.Staff {
+			if source.UserID == ownerID || source.IsOwner() || !source.IsActive {
+				continue
+			}
+			clone.Staff = append(clone.Staff, &Staff{
+				BaseModel:   newCloneModel(by, now),
+				BusinessID:  business.ID,
+				UserID:      source.UserID,
+				Role:        source.Role,
+				IsActive:    true,
+				Permissions: source.Permissions,
+				StartDate:   &now,
+			})
+		}
+	}
+	return clone, nil
+}
+
+// cloneBundleItems copies the items of a bundle, pointing them at the copies of their services
+func cloneBundleItems(bundle *ServiceBundle, serviceIDs map[string]string) (*string, error) {
+	if bundle.Items == nil {
+		return nil, nil
+	}
+	var items []BundleItem
+	if err := json.Unmarshal([]byte(*bundle.Items), &items); err != nil {
+		return nil, fmt.Errorf("%w: bundle %q has invalid items: %v", ErrValidation, bundle.Name, err)
+	}
+	for i, item := range items {
+		serviceID, ok := serviceIDs[item.ServiceID]
+		if !ok {
+			return nil, fmt.Errorf("%w: bundle %q includes a service the template no longer has", ErrValidation, bundle.Name)
+		}
+		items[i].ServiceID = serviceID
+	}
+	data, err := json.Marshal(items)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode bundle items: %w", err)
+	}
+	encoded := string(data)
+	return &encoded, nil
+}
+
+// newCloneModel returns the base model of a copy. IDs are assigned up front so that copies can
+// reference each other before they are stored, and every field is set as copies are stored
+// whole, zero values included.
+func newCloneModel(by *string, now time.Time) BaseModel {
+	return BaseModel{ID: NewID(), CreatedAt: now, CreatedBy: by, UpdatedAt: now, UpdatedBy: by, Version: 1}
+}
+
+// BusinessCloneRepository defines the interface for cloning businesses from templates
+type BusinessCloneRepository interface {
+	// LoadTemplate loads the configuration of a business that clones copy
+	LoadTemplate(ctx context.Context, businessID string) (*BusinessTemplate, error)
+	// Create stores a clone in a single transaction, so that a business is never left half copied
+	Create(ctx context.Context, clone *BusinessClone) error
+}
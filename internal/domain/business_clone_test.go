@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func cloneTemplate() *BusinessTemplate {
+	categoryID := "category"
+	items := `[{"service_id":"cut"},{"service_id":"colour","processing_minutes":30}]`
+	return &BusinessTemplate{
+		Business: &Business{
+			BaseModel:  BaseModel{ID: "template"},
+			UserID:     "owner",
+			Name:       "Template",
+			Email:      "template@example.com",
+			Currency:   "EUR",
+			TimeZone:   "Europe/Lisbon",
+			IsTemplate: true,
+		},
+		Settings:   &BusinessSettings{BaseModel: BaseModel{ID: "settings"}, BusinessID: "template"},
+		Categories: []*ServiceCategory{{BaseModel: BaseModel{ID: categoryID}, BusinessID: "template", Name: "Hair"}},
+		Services: []*Service{
+			{BaseModel: BaseModel{ID: "cut"}, BusinessID: "template", CategoryID: &categoryID, Name: "Cut", IsActive: true},
+			{BaseModel: BaseModel{ID: "colour"}, BusinessID: "template", Name: "Colour", IsActive: false},
+		},
+		Bundles: []*ServiceBundle{{BaseModel: BaseModel{ID: "bundle"}, BusinessID: "template", Name: "Cut and colour", Items: &items}},
+		Staff: []*Staff{
+			{UserID: "owner", Role: BusinessRoleOwner, IsActive: true},
+			{UserID: "manager", Role: BusinessRoleManager, IsActive: true},
+			{UserID: "former", Role: BusinessRoleEmployee, IsActive: false},
+		},
+	}
+}
+
+func TestNewBusinessClone(t *testing.T) {
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	template := cloneTemplate()
+
+	clone, err := NewBusinessClone(template, BusinessCloneOptions{Name: " New salon ", Email: "New@Example.com"}, "buyer", now, nil)
+	require.NoError(t, err)
+
+	business := clone.Business
+	assert.NotEqual(t, "template", business.ID)
+	assert.Equal(t, "buyer", business.UserID)
+	assert.Equal(t, "New salon", business.Name)
+	assert.Equal(t, "new@example.com", business.Email)
+	assert.Equal(t, "Europe/Lisbon", business.TimeZone)
+	assert.False(t, business.IsTemplate)
+
+	require.NotNil(t, clone.Settings)
+	assert.NotEqual(t, "settings", clone.Settings.ID)
+	assert.Equal(t, business.ID, clone.Settings.BusinessID)
+
+	require.Len(t, clone.Categories, 1)
+	require.Len(t, clone.Services, 2)
+	cut, colour := clone.Services[0], clone.Services[1]
+	assert.Equal(t, business.ID, cut.BusinessID)
+	require.NotNil(t, cut.CategoryID)
+	assert.Equal(t, clone.Categories[0].ID, *cut.CategoryID)
+	assert.False(t, colour.IsActive, "inactive services are copied as they are")
+
+	require.Len(t, clone.Bundles, 1)
+	var items []BundleItem
+	require.NoError(t, json.Unmarshal([]byte(*clone.Bundles[0].Items), &items))
+	require.Len(t, items, 2)
+	assert.Equal(t, cut.ID, items[0].ServiceID)
+	assert.Equal(t, colour.ID, items[1].ServiceID)
+	assert.Equal(t, 30, items[1].ProcessingMinutes)
+
+	require.Len(t, clone.Staff, 1, "staff roles are copied only when asked for")
+	assert.Equal(t, "buyer", clone.Staff[0].UserID)
+	assert.Equal(t, BusinessRoleOwner, clone.Staff[0].Role)
+	assert.Equal(t, "template", template.Business.ID, "the template is left untouched")
+}
+
+func TestNewBusinessClone_CopyStaffRoles(t *testing.T) {
+	clone, err := NewBusinessClone(cloneTemplate(), BusinessCloneOptions{Name: "New salon", CopyStaffRoles: true}, "manager", time.Now(), nil)
+	require.NoError(t, err)
+
+	require.Len(t, clone.Staff, 1, "the caller becomes the owner, and the template's owner and former staff are not copied")
+	assert.Equal(t, "manager", clone.Staff[0].UserID)
+	assert.Equal(t, BusinessRoleOwner, clone.Staff[0].Role)
+
+	clone, err = NewBusinessClone(cloneTemplate(), BusinessCloneOptions{Name: "New salon", CopyStaffRoles: true}, "buyer", time.Now(), nil)
+	require.NoError(t, err)
+	require.Len(t, clone.Staff, 2)
+	assert.Equal(t, "manager", clone.Staff[1].UserID)
+	assert.Equal(t, BusinessRoleManager, clone.Staff[1].Role)
+}
+
+func TestNewBusinessClone_Invalid(t *testing.T) {
+	_, err := NewBusinessClone(cloneTemplate(), BusinessCloneOptions{Name: " "}, "buyer", time.Now(), nil)
+	assert.ErrorIs(t, err, ErrValidation)
+
+	template := cloneTemplate()
+	template.Services = template.Services[:1]
+	_, err = NewBusinessClone(template, BusinessCloneOptions{Name: "New salon"}, "buyer", time.Now(), nil)
+	assert.ErrorIs(t, err, ErrValidation, "bundles must only include services the template has")
+}
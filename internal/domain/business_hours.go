@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// BusinessHours are the opening hours of a business, or of one of its locations, on one day of
+// the week. A day without a row is closed.
+type BusinessHours struct {
+	BaseModel
+	BusinessID string       `gorm:"not null;type:uuid;index" json:"business_id"`
+	LocationID *string      `gorm:"type:uuid;index" json:"location_id,omitempty"` // The whole business when nil
+	Weekday    time.Weekday `gorm:"not null" json:"weekday"`
+	IsOpen     bool         `gorm:"not null;default:false" json:"is_open"`
+	OpenTime   string       `gorm:"size:5" json:"open_time,omitempty"`  // HH:MM
+	CloseTime  string       `gorm:"size:5" json:"close_time,omitempty"` // HH:MM, or 24:00 for midnight
+
+	// Relationships
+	Business Business `gorm:"foreignKey:BusinessID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// TableName returns the table name for BusinessHours
+func (BusinessHours) TableName() string { return "business_hours" }
+
+// Validate checks the opening hours of every day of the week, of which there is at most one per day
+func (h WeeklyHours) Validate() error {
+	for name, day := range h {
+		if _, ok := weekdayNamed(name); !ok {
+			return fmt.Errorf("%w: %q is not a day of the week", ErrValidation, name)
+		}
+		if day == nil || !day.IsOpen {
+			continue
+		}
+		opens, err := time.Parse("15:04", day.OpenTime)
+		if err != nil {
+			return fmt.Errorf("%w: the opening time on %s must be HH:MM", ErrValidation, name)
+		}
+		closes, err := clockOn(opens, day.CloseTime)
+		if err != nil {
+			return fmt.Errorf("%w: the closing time on %s must be HH:MM", ErrValidation, name)
+		}
+		// Closing at midnight is the only way to close on the clock before opening
+		if !closes.After(opens) && (closes.Hour() != 0 || closes.Minute() != 0) {
+			return fmt.Errorf("%w: the closing time on %s must be after the opening time", ErrValidation, name)
+		}
+	}
+	return nil
+}
+
+// Rows returns the opening hours as one row per day of the week, from Sunday
+func (h WeeklyHours) Rows(businessID string, locationID *string) []*BusinessHours {
+	rows := make([]*BusinessHours, 0, len(h))
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		hours := h[strings.ToLower(day.String())]
+		if hours == nil {
+			continue
+		}
+		row := &BusinessHours{BusinessID: businessID, LocationID: locationID, Weekday: day, IsOpen: hours.IsOpen}
+		if hours.IsOpen {
+			row.OpenTime, row.CloseTime = hours.OpenTime, hours.CloseTime
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// Encode returns the opening hours in the JSON of Business.BusinessHours, or nil without any
+func (h WeeklyHours) Encode() *string {
+	if len(h) == 0 {
+		return nil
+	}
+	encoded, _ := json.Marshal(h)
+	raw := string(encoded)
+	return &raw
+}
+
+// WeeklyHoursOf returns the opening hours of rows of the same business or location, or nil
+// without any
+func WeeklyHoursOf(rows []*BusinessHours) WeeklyHours {
+	if len(rows) == 0 {
+		return nil
+	}
+	hours := make(WeeklyHours, len(rows))
+	for _, row := range rows {
+		hours[strings.ToLower(row.Weekday.String())] = &DayHours{IsOpen: row.IsOpen, OpenTime: row.OpenTime, CloseTime: row.CloseTime}
+	}
+	return hours
+}
+
+// HoursAt returns the opening hours of a location: its own when it has any, otherwise those of
+// the whole business. The rows are those of the business and all its locations.
+func HoursAt(rows []*BusinessHours, locationID *string) WeeklyHours {
+	if locationID != nil {
+		own := slices.DeleteFunc(slices.Clone(rows), func(row *BusinessHours) bool {
+			return row.LocationID == nil || *row.LocationID != *locationID
+		})
+		if len(own) > 0 {
+			return WeeklyHoursOf(own)
+		}
+	}
+	return WeeklyHoursOf(slices.DeleteFunc(slices.Clone(rows), func(row *BusinessHours) bool { return row.LocationID != nil }))
+}
+
+// weekdayNamed returns the day of the week of a lower-case English name
+func weekdayNamed(name string) (time.Weekday, bool) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.ToLower(day.String()) == name {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+// BusinessHoursRepository defines the repository interface for opening hours
+type BusinessHoursRepository interface {
+	// FindByBusiness finds the hours of a business and of all its locations, the business's first,
+	// then by location and weekday
+	FindByBusiness(ctx context.Context, businessID string) ([]*BusinessHours, error)
+	// Replace replaces the hours of a business, or of one of its locations, with the rows, which
+	// may be none. The hours of the whole business are also written to Business.BusinessHours,
+	// which the availability rules check bookings against.
+	Replace(ctx context.Context, businessID string, locationID *string, rows []*BusinessHours) error
+}
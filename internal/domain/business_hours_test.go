@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWeeklyHoursValidate(t *testing.T) {
+	valid := WeeklyHours{
+		"monday":   {IsOpen: true, OpenTime: "09:00", CloseTime: "18:00"},
+		"saturday": {IsOpen: true, OpenTime: "20:00", CloseTime: "24:00"},
+		"sunday":   {IsOpen: false},
+	}
+	assert.NoError(t, valid.Validate())
+
+	invalid := []WeeklyHours{
+		{"mondays": {IsOpen: true, OpenTime: "09:00", CloseTime: "18:00"}},
+		{"monday": {IsOpen: true, OpenTime: "9am", CloseTime: "18:00"}},
+		{"monday": {IsOpen: true, OpenTime: "09:00"}},
+		{"monday": {IsOpen: true, OpenTime: "18:00", CloseTime: "09:00"}},
+	}
+	for _, hours := range invalid {
+		assert.True(t, errors.Is(hours.Validate(), ErrValidation), "%v", hours["monday"])
+	}
+}
+
+func TestWeeklyHoursRows(t *testing.T) {
+	hours := WeeklyHours{
+		"monday": {IsOpen: true, OpenTime: "09:00", CloseTime: "18:00"},
+		"sunday": {IsOpen: false, OpenTime: "10:00", CloseTime: "12:00"},
+	}
+	rows := hours.Rows("business-1", nil)
+	require.Len(t, rows, 2)
+	assert.Equal(t, time.Sunday, rows[0].Weekday)
+	assert.Empty(t, rows[0].OpenTime, "closed days have no times")
+	assert.Equal(t, time.Monday, rows[1].Weekday)
+	assert.Equal(t, "18:00", rows[1].CloseTime)
+
+	roundTrip := WeeklyHoursOf(rows)
+	assert.Equal(t, hours["monday"], roundTrip["monday"])
+	assert.False(t, roundTrip["sunday"].IsOpen)
+
+	decoded, err := ParseWeeklyHours(roundTrip.Encode())
+	require.NoError(t, err)
+	assert.Equal(t, roundTrip, decoded)
+	assert.Nil(t, WeeklyHours(nil).Encode())
+}
+
+func TestHoursAt(t *testing.T) {
+	downtown := "location-1"
+	rows := append(
+		WeeklyHours{"monday": {IsOpen: true, OpenTime: "09:00", CloseTime: "18:00"}}.Rows("business-1", nil),
+		WeeklyHours{"monday": {IsOpen: true, OpenTime: "10:00", CloseTime: "20:00"}}.Rows("business-1", &downtown)...,
+	)
+
+	assert.Equal(t, "18:00", HoursAt(rows, nil)["monday"].CloseTime)
+	assert.Equal(t, "20:00", HoursAt(rows, &downtown)["monday"].CloseTime)
+	other := "location-2"
+	assert.Equal(t, "18:00", HoursAt(rows, &other)["monday"].CloseTime, "locations without hours of their own keep the business's")
+	assert.Nil(t, HoursAt(nil, nil))
+}
@@ -0,0 +1,551 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// MaxBusinessImportRows bounds the rows of a single service or staff import
+const MaxBusinessImportRows = 1000
+
+// ErrImportBatchInUse is returned when rolling back an import whose records are in use
+var ErrImportBatchInUse = errors.New("the imported records are already in use")
+
+// ImportKind is what an import batch created
+type ImportKind string
+
+const (
+	ImportKindServices ImportKind = "services" // Service categories and services
+	ImportKindStaff    ImportKind = "staff"
+)
+
+// ImportRowStatus is the outcome of a row of a service or staff import
+type ImportRowStatus string
+
+const (
+	ImportRowReady     ImportRowStatus = "ready"     // Valid, but not imported because of a dry run or an invalid row
+	ImportRowImported  ImportRowStatus = "imported"  // Created
+	ImportRowDuplicate ImportRowStatus = "duplicate" // Skipped because it already exists
+	ImportRowInvalid   ImportRowStatus = "invalid"   // Rejected; see the errors
+)
+
+// serviceImportColumns maps the accepted header names onto service fields. Headers are matched
+// case-insensitively, ignoring spaces, dashes and underscores.
+var serviceImportColumns = map[string]string{
+	"category":        "category",
+	"servicecategory": "category",
+	"name":            "name",
+	"service":         "name",
+	"servicename":     "name",
+	"description":     "description",
+	"duration":        "duration",
+	"durationminutes": "duration",
+	"minutes":         "duration",
+	"price":           "price",
+	"preparationtime": "preparation_time",
+	"preparation":     "preparation_time",
+	"cleanuptime":     "cleanup_time",
+	"cleanup":         "cleanup_time",
+}
+
+// staffImportColumns maps the accepted header names onto staff fields
+var staffImportColumns = map[string]string{
+	"firstname":    "first_name",
+	"givenname":    "first_name",
+	"lastname":     "last_name",
+	"surname":      "last_name",
+	"familyname":   "last_name",
+	"name":         "name",
+	"fullname":     "name",
+	"email":        "email",
+	"emailaddress": "email",
+	"phone":        "phone",
+	"phonenumber":  "phone",
+	"mobile":       "phone",
+	"role":         "role",
+	"startdate":    "start_date",
+	"started":      "start_date",
+}
+
+// ServiceImportRow is a row of a service import with the service it describes
+type ServiceImportRow struct {
+	Line            int // Line of the file, counting the header as line 1
+	Category        string
+	Service         Service
+	Status          ImportRowStatus
+	Errors          []string
+	DuplicateOfID   *string // The existing service the row duplicates
+	DuplicateOfLine *int    // The earlier row of the file the row duplicates
+}
+
+// StaffImportRow is a row of a staff import with the staff member it describes
+type StaffImportRow struct {
+	Line            int
+	User            User // The account of the staff member, matched to an existing one by email
+	Staff           Staff
+	Status          ImportRowStatus
+	Errors          []string
+	DuplicateOfID   *string // The existing staff member the row duplicates
+	DuplicateOfLine *int
+}
+
+// ImportedName identifies an existing record of a business by a name or email address, used to
+// detect duplicates
+type ImportedName struct {
+	ID   string
+	Name string
+}
+
+// ParseServiceCSV reads services from a CSV file with a header row, in the separators accepted
+// for client imports. Durations are minutes or hours and minutes ("90", "1h30", "1:30"); prices
+// take a decimal comma or point and may carry a currency symbol. Rows that fail validation are
+// returned with status invalid; an error is only returned when the file as a whole cannot be
+// imported.
+func ParseServiceCSV(r io.Reader, businessID string) ([]*ServiceImportRow, error) {
+	records, err := readImportCSV(r, serviceImportColumns, requireServiceImportColumns, MaxBusinessImportRows, "services")
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]*ServiceImportRow, len(records))
+	for i, record := range records {
+		rows[i] = parseServiceImportRow(record.line, businessID, record.values)
+		rows[i].Service.DisplayOrder = i
+	}
+	return rows, nil
+}
+
+// ParseStaffCSV reads staff members from a CSV file with a header row. Roles default to
+// employee; owners cannot be imported.
+func ParseStaffCSV(r io.Reader, businessID string) ([]*StaffImportRow, error) {
+	records, err := readImportCSV(r, staffImportColumns, requireClientImportColumns, MaxBusinessImportRows, "staff members")
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]*StaffImportRow, len(records))
+	for i, record := range records {
+		rows[i] = parseStaffImportRow(record.line, businessID, record.values)
+	}
+	return rows, nil
+}
+
+// MarkServiceImportDuplicates marks the valid rows whose name, ignoring case, belongs to an
+// existing service or to an earlier row of the file
+func MarkServiceImportDuplicates(rows []*ServiceImportRow, existing []ImportedName) {
+	existingIDs := importedNameIDs(existing)
+	seenLines := map[string]int{}
+	for _, row := range rows {
+		if row.Status == ImportRowInvalid {
+			continue
+		}
+		key := strings.ToLower(row.Service.Name)
+		if id, ok := existingIDs[key]; ok {
+			row.Status = ImportRowDuplicate
+			row.DuplicateOfID = &id
+		} else if line, ok := seenLines[key]; ok {
+			row.Status = ImportRowDuplicate
+			row.DuplicateOfLine = &line
+		} else {
+			seenLines[key] = row.Line
+		}
+	}
+}
+
+// MarkStaffImportDuplicates marks the valid rows whose email belongs to an existing staff
+// member or to an earlier row of the file
+func MarkStaffImportDuplicates(rows []*StaffImportRow, existing []ImportedName) {
+	existingIDs := importedNameIDs(existing)
+	seenLines := map[string]int{}
+	for _, row := range rows {
+		if row.Status == ImportRowInvalid {
+			continue
+		}
+		key := row.User.Email
+		if id, ok := existingIDs[key]; ok {
+			row.Status = ImportRowDuplicate
+			row.DuplicateOfID = &id
+		} else if line, ok := seenLines[key]; ok {
+			row.Status = ImportRowDuplicate
+			row.DuplicateOfLine = &line
+		} else {
+			seenLines[key] = row.Line
+		}
+	}
+}
+
+// importedNameIDs indexes existing records by their lower-cased name
+func importedNameIDs(existing []ImportedName) map[string]string {
+	ids := make(map[string]string, len(existing))
+	for _, record := range existing {
+		ids[strings.ToLower(strings.TrimSpace(record.Name))] = record.ID
+	}
+	return ids
+}
+
+// requireServiceImportColumns checks that the name, duration and price of services can be read
+func requireServiceImportColumns(found map[string]bool) error {
+	for _, field := range []string{"name", "duration", "price"} {
+		if !found[field] {
+			return fmt.Errorf("%w: the file needs a %s column", ErrValidation, field)
+		}
+	}
+	return nil
+}
+
+// parseServiceImportRow builds and validates the service of a row
+func parseServiceImportRow(line int, businessID string, values map[string]string) *ServiceImportRow {
+	row := &ServiceImportRow{Line: line, Status: ImportRowReady, Category: strings.Join(strings.Fields(values["category"]), " ")}
+	service := &row.Service
+	service.BusinessID = businessID
+	service.IsActive = true
+
+	if len(row.Category) > 100 {
+		row.addError("category must be at most 100 characters")
+	}
+	service.Name = strings.Join(strings.Fields(values["name"]), " ")
+	if service.Name == "" {
+		row.addError("name is required")
+	} else if len(service.Name) > 200 {
+		row.addError("name must be at most 200 characters")
+	}
+	service.Description = optionalValue(values["description"])
+
+	if value := values["duration"]; value == "" {
+		row.addError("duration is required")
+	} else if minutes, ok := parseImportDuration(value); !ok || minutes <= 0 || minutes > 24*60 {
+		row.addError(fmt.Sprintf("%q is not a valid duration; use minutes or hours and minutes, e.g. 90 or 1h30", value))
+	} else {
+		service.Duration = minutes
+	}
+
+	if value := values["price"]; value == "" {
+		row.addError("price is required")
+	} else if price, ok := parseImportPrice(value); !ok || price.IsNegative() {
+		row.addError(fmt.Sprintf("%q is not a valid price", value))
+	} else {
+		service.Price = price
+	}
+
+	buffers := []struct {
+		field  string
+		target **int
+	}{{"preparation_time", &service.PreparationTime}, {"cleanup_time", &service.CleanupTime}}
+	for _, buffer := range buffers {
+		field, value := buffer.field, values[buffer.field]
+		if value == "" {
+			continue
+		}
+		minutes, ok := parseImportDuration(value)
+		if !ok || minutes < 0 || minutes > 240 {
+			row.addError(fmt.Sprintf("%q is not a valid %s", value, strings.ReplaceAll(field, "_", " ")))
+			continue
+		}
+		*buffer.target = &minutes
+	}
+	return row
+}
+
+// parseStaffImportRow builds and validates the staff member of a row
+func parseStaffImportRow(line int, businessID string, values map[string]string) *StaffImportRow {
+	row := &StaffImportRow{Line: line, Status: ImportRowReady}
+	user := &row.User
+	user.IsActive = true
+	row.Staff = Staff{BusinessID: businessID, Role: BusinessRoleEmployee, IsActive: true}
+
+	user.FirstName, user.LastName = values["first_name"], values["last_name"]
+	if user.FirstName == "" && user.LastName == "" && values["name"] != "" {
+		user.FirstName, user.LastName, _ = strings.Cut(strings.Join(strings.Fields(values["name"]), " "), " ")
+	}
+	if user.FirstName == "" {
+		row.addError("first name is required")
+	} else if len(user.FirstName) > 100 {
+		row.addError("first name must be at most 100 characters")
+	}
+	if user.LastName == "" {
+		row.addError("last name is required")
+	} else if len(user.LastName) > 100 {
+		row.addError("last name must be at most 100 characters")
+	}
+
+	user.Email = strings.ToLower(values["email"])
+	if user.Email == "" {
+		row.addError("email is required")
+	} else if !isImportableEmail(user.Email) {
+		row.addError(fmt.Sprintf("%q is not a valid email address", values["email"]))
+	}
+
+	if phone := values["phone"]; phone != "" {
+		if len(NormalizePhone(phone)) < 6 || len(phone) > 50 {
+			row.addError(fmt.Sprintf("%q is not a valid phone number", phone))
+		}
+		user.Phone = &phone
+	}
+
+	if value := strings.ToLower(values["role"]); value != "" {
+		switch role := BusinessRole(value); role {
+		case BusinessRoleManager, BusinessRoleEmployee, BusinessRoleAssistant:
+			row.Staff.Role = role
+		case BusinessRoleOwner:
+			row.addError("owners cannot be imported")
+		default:
+			row.addError(fmt.Sprintf("%q is not a valid role; use manager, employee or assistant", values["role"]))
+		}
+	}
+
+	if value := values["start_date"]; value != "" {
+		startDate, ok := parseClientImportDate(value)
+		if !ok {
+			row.addError(fmt.Sprintf("%q is not a valid start date; use YYYY-MM-DD or DD/MM/YYYY", value))
+		} else {
+			row.Staff.StartDate = &startDate
+		}
+	}
+	return row
+}
+
+// addError records a validation error, making the row invalid
+func (r *ServiceImportRow) addError(message string) {
+	r.Status = ImportRowInvalid
+	r.Errors = append(r.Errors, message)
+}
+
+// addError records a validation error, making the row invalid
+func (r *StaffImportRow) addError(message string) {
+	r.Status = ImportRowInvalid
+	r.Errors = append(r.Errors, message)
+}
+
+// parseImportDuration parses a duration in minutes ("90", "90 min"), hours and minutes
+// ("1h30", "1h 30m", "1:30") or hours ("2h")
+func parseImportDuration(value string) (int, bool) {
+	value = strings.ToLower(strings.ReplaceAll(value, " ", ""))
+	for _, suffix := range []string{"minutes", "minute", "mins", "min", "m"} {
+		if trimmed, ok := strings.CutSuffix(value, suffix); ok {
+			value = trimmed
+			break
+		}
+	}
+	hours, minutes, ok := strings.Cut(value, "h")
+	if !ok {
+		hours, minutes, ok = strings.Cut(value, ":")
+	}
+	if !ok {
+		total, err := strconv.Atoi(value)
+		return total, err == nil
+	}
+
+	h, err := strconv.Atoi(hours)
+	if err != nil || h < 0 {
+		return 0, false
+	}
+	m := 0
+	if minutes != "" {
+		if m, err = strconv.Atoi(minutes); err != nil || m < 0 || m >= 60 {
+			return 0, false
+		}
+	}
+	return h*60 + m, true
+}
+
+// parseImportPrice parses a price with a decimal point or comma, ignoring currency symbols and
+// thousands separators: "25", "25,50", "€1.250,00" and "$1,250" are all accepted. A separator
+// followed by three digits separates thousands, since prices have at most two decimals.
+func parseImportPrice(value string) (decimal.Decimal, bool) {
+	value = strings.Map(func(r rune) rune {
+		if (r >= '0' && r <= '9') || r == '.' || r == ',' || r == '-' {
+			return r
+		}
+		return -1
+	}, value)
+	whole, fraction := value, ""
+	if i := strings.LastIndexAny(value, ".,"); i >= 0 && len(value)-i-1 != 3 {
+		whole, fraction = value[:i], "."+value[i+1:]
+	}
+	groups := strings.FieldsFunc(whole, func(r rune) bool { return r == '.' || r == ',' })
+	for _, group := range groups[min(1, len(groups)):] {
+		if len(group) != 3 {
+			return decimal.Zero, false
+		}
+	}
+	price, err := decimal.NewFromString(strings.Join(groups, "") + fraction)
+	if err != nil || price.Exponent() < -2 {
+		return decimal.Zero, false
+	}
+	return price, true
+}
+
+// PlanServiceImport prepares the records importing the ready rows creates: the services, with
+// IDs so that the batch can list them, and a category for every category name not matching an
+// existing one, ignoring case. The rows' services are linked to their categories.
+func PlanServiceImport(rows []*ServiceImportRow, existing []*ServiceCategory, by *string) ([]*ServiceCategory, []*Service, ImportRecords) {
+	categoryIDs := map[string]string{}
+	order := 0
+	for _, category := range existing {
+		categoryIDs[strings.ToLower(category.Name)] = category.ID
+		order = max(order, category.DisplayOrder+1)
+	}
+
+	var categories []*ServiceCategory
+	var services []*Service
+	var records ImportRecords
+	for _, row := range rows {
+		if row.Status != ImportRowReady {
+			continue
+		}
+		if row.Category != "" {
+			key := strings.ToLower(row.Category)
+			id, ok := categoryIDs[key]
+			if !ok {
+				category := &ServiceCategory{
+					BaseModel:    newImportModel(by),
+					BusinessID:   row.Service.BusinessID,
+					Name:         row.Category,
+					DisplayOrder: order,
+					IsActive:     true,
+				}
+				order++
+				id = category.ID
+				categoryIDs[key] = id
+				categories = append(categories, category)
+				records.CategoryIDs = append(records.CategoryIDs, id)
+			}
+			row.Service.CategoryID = &id
+		}
+		row.Service.BaseModel = newImportModel(by)
+		services = append(services, &row.Service)
+		records.ServiceIDs = append(records.ServiceIDs, row.Service.ID)
+	}
+	return categories, services, records
+}
+
+// PlanStaffImport prepares the records importing the ready rows creates: the staff members,
+// linked to the existing user account with their email or to a new one
+func PlanStaffImport(rows []*StaffImportRow, existing []*User, by *string) ([]*User, []*Staff, ImportRecords) {
+	userIDs := map[string]string{}
+	for _, user := range existing {
+		userIDs[strings.ToLower(user.Email)] = user.ID
+	}
+
+	var users []*User
+	var staff []*Staff
+	var records ImportRecords
+	for _, row := range rows {
+		if row.Status != ImportRowReady {
+			continue
+		}
+		id, ok := userIDs[row.User.Email]
+		if !ok {
+			row.User.BaseModel = newImportModel(by)
+			id = row.User.ID
+			userIDs[row.User.Email] = id
+			users = append(users, &row.User)
+			records.UserIDs = append(records.UserIDs, id)
+		}
+		row.Staff.BaseModel = newImportModel(by)
+		row.Staff.UserID = id
+		staff = append(staff, &row.Staff)
+		records.StaffIDs = append(records.StaffIDs, row.Staff.ID)
+	}
+	return users, staff, records
+}
+
+// newImportModel returns the base of a record created by an import
+func newImportModel(by *string) BaseModel {
+	return BaseModel{ID: NewID(), CreatedBy: by, UpdatedBy: by}
+}
+
+// ImportBatchStatus is the state of an import batch
+type ImportBatchStatus string
+
+const (
+	ImportBatchImported   ImportBatchStatus = "imported"
+	ImportBatchRolledBack ImportBatchStatus = "rolled_back"
+)
+
+// ImportRecords lists the records an import batch created, so that it can be rolled back
+type ImportRecords struct {
+	CategoryIDs []string `json:"category_ids,omitempty"`
+	ServiceIDs  []string `json:"service_ids,omitempty"`
+	StaffIDs    []string `json:"staff_ids,omitempty"`
+	UserIDs     []string `json:"user_ids,omitempty"` // Accounts created for staff members who had none
+}
+
+// ImportBatch records an import of services or staff into a business. Rolling a batch back
+// removes everything it created, as long as none of it has been used since.
+type ImportBatch struct {
+	BaseModel
+	BusinessID   string            `gorm:"not null;type:uuid;index" json:"business_id"`
+	Kind         ImportKind        `gorm:"not null;size:20" json:"kind"`
+	Status       ImportBatchStatus `gorm:"not null;size:20;default:'imported'" json:"status"`
+	TotalRows    int               `gorm:"not null" json:"total_rows"`
+	Imported     int               `gorm:"not null" json:"imported"`
+	Records      *string           `gorm:"type:jsonb;default:'{}'" json:"records,omitempty"` // JSON ImportRecords
+	RolledBackAt *time.Time        `json:"rolled_back_at,omitempty"`
+	RolledBackBy *string           `gorm:"type:uuid" json:"rolled_back_by,omitempty"`
+}
+
+// TableName returns the table name for ImportBatch
+func (ImportBatch) TableName() string { return "import_batches" }
+
+// GetRecords decodes the records the batch created
+func (b *ImportBatch) GetRecords() (ImportRecords, error) {
+	var records ImportRecords
+	if b.Records == nil || *b.Records == "" {
+		return records, nil
+	}
+	if err := json.Unmarshal([]byte(*b.Records), &records); err != nil {
+		return records, fmt.Errorf("%w: invalid import records", ErrValidation)
+	}
+	return records, nil
+}
+
+// SetRecords encodes the records the batch created
+func (b *ImportBatch) SetRecords(records ImportRecords) error {
+	data, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+	encoded := string(data)
+	b.Records = &encoded
+	return nil
+}
+
+// CanRollBack reports whether the batch can still be rolled back
+func (b *ImportBatch) CanRollBack() bool {
+	return b.Status == ImportBatchImported
+}
+
+// MarkRolledBack records that the batch was rolled back
+func (b *ImportBatch) MarkRolledBack(by *string, at time.Time) {
+	b.Status = ImportBatchRolledBack
+	b.RolledBackAt = &at
+	b.RolledBackBy = by
+}
+
+// BusinessImportRepository defines the interface for importing services and staff in bulk
+type BusinessImportRepository interface {
+	GetBatch(ctx context.Context, id string) (*ImportBatch, error)
+	// FindBatches finds the import batches of a business, latest first
+	FindBatches(ctx context.Context, businessID string, limit int) ([]*ImportBatch, error)
+	// FindServiceNames finds the name of every service of a business
+	FindServiceNames(ctx context.Context, businessID string) ([]ImportedName, error)
+	FindCategories(ctx context.Context, businessID string) ([]*ServiceCategory, error)
+	// FindStaffEmails finds the email of every staff member of a business, by staff ID
+	FindStaffEmails(ctx context.Context, businessID string) ([]ImportedName, error)
+	FindUsersByEmail(ctx context.Context, emails []string) ([]*User, error)
+	// ImportServices creates the categories, services and batch in a single transaction
+	ImportServices(ctx context.Context, batch *ImportBatch, categories []*ServiceCategory, services []*Service) error
+	// ImportStaff creates the user accounts, staff members and batch in a single transaction
+	ImportStaff(ctx context.Context, batch *ImportBatch, users []*User, staff []*Staff) error
+	// RollBack removes the records a batch created and saves the batch, marked rolled back, in a
+	// single transaction. It fails with ErrImportBatchInUse when an appointment uses any of them, and
+	// keeps the user accounts that have since signed in or joined another business.
+	RollBack(ctx context.Context, batch *ImportBatch) error
+}
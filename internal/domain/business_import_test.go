@@ -0,0 +1,165 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseServiceCSV(t *testing.T) {
+	file := "Category;Service Name;Duration;Price;Cleanup\n" +
+		"Hair;Women's Cut;1h;€35,00;10\n" +
+		";Manicure;45 min;20;\n" +
+		"Hair;;0;free;5h\n"
+
+	rows, err := ParseServiceCSV(strings.NewReader(file), "business-1")
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+
+	cut := rows[0]
+	assert.Equal(t, ImportRowReady, cut.Status)
+	assert.Equal(t, "Hair", cut.Category)
+	assert.Equal(t, "business-1", cut.Service.BusinessID)
+	assert.Equal(t, 60, cut.Service.Duration)
+	assert.True(t, decimal.NewFromInt(35).Equal(cut.Service.Price))
+	require.NotNil(t, cut.Service.CleanupTime)
+	assert.Equal(t, 10, *cut.Service.CleanupTime)
+
+	manicure := rows[1]
+	assert.Equal(t, ImportRowReady, manicure.Status)
+	assert.Empty(t, manicure.Category)
+	assert.Equal(t, 45, manicure.Service.Duration)
+	assert.Equal(t, 1, manicure.Service.DisplayOrder)
+
+	invalid := rows[2]
+	assert.Equal(t, ImportRowInvalid, invalid.Status)
+	assert.Len(t, invalid.Errors, 4)
+}
+
+func TestParseServiceCSV_InvalidFile(t *testing.T) {
+	_, err := ParseServiceCSV(strings.NewReader("name,price\nCut,20\n"), "business-1")
+	assert.ErrorContains(t, err, "duration column")
+}
+
+func TestParseStaffCSV(t *testing.T) {
+	file := "name,email,role,start date\n" +
+		"Ana Silva,Ana@Example.com,,2024-01-15\n" +
+		"Rui Costa,rui@example.com,Manager,\n" +
+		"Joana,joana@example.com,owner,soon\n"
+
+	rows, err := ParseStaffCSV(strings.NewReader(file), "business-1")
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+
+	ana := rows[0]
+	assert.Equal(t, ImportRowReady, ana.Status)
+	assert.Equal(t, "Ana", ana.User.FirstName)
+	assert.Equal(t, "ana@example.com", ana.User.Email)
+	assert.Equal(t, BusinessRoleEmployee, ana.Staff.Role)
+	require.NotNil(t, ana.Staff.StartDate)
+
+	assert.Equal(t, BusinessRoleManager, rows[1].Staff.Role)
+
+	joana := rows[2]
+	assert.Equal(t, ImportRowInvalid, joana.Status)
+	assert.Contains(t, joana.Errors, "owners cannot be imported")
+	assert.Len(t, joana.Errors, 3, "missing last name, owner role and invalid start date")
+}
+
+func TestMarkServiceImportDuplicates(t *testing.T) {
+	file := "name,duration,price\nCut,30,20\nColour,90,60\ncut,45,25\n"
+	rows, err := ParseServiceCSV(strings.NewReader(file), "business-1")
+	require.NoError(t, err)
+
+	MarkServiceImportDuplicates(rows, []ImportedName{{ID: "service-1", Name: "COLOUR"}})
+
+	assert.Equal(t, ImportRowReady, rows[0].Status)
+	assert.Equal(t, ImportRowDuplicate, rows[1].Status)
+	assert.Equal(t, "service-1", *rows[1].DuplicateOfID)
+	assert.Equal(t, ImportRowDuplicate, rows[2].Status)
+	assert.Equal(t, 2, *rows[2].DuplicateOfLine)
+}
+
+func TestPlanServiceImport(t *testing.T) {
+	file := "category,name,duration,price\nHair,Cut,30,20\nnails,Manicure,45,20\nNails,Pedicure,45,25\n,Brows,15,10\n"
+	rows, err := ParseServiceCSV(strings.NewReader(file), "business-1")
+	require.NoError(t, err)
+
+	existing := []*ServiceCategory{{BaseModel: BaseModel{ID: "hair"}, Name: "hair", DisplayOrder: 2}}
+	categories, services, records := PlanServiceImport(rows, existing, nil)
+
+	require.Len(t, categories, 1, "categories are matched ignoring case")
+	assert.Equal(t, "nails", categories[0].Name)
+	assert.Equal(t, 3, categories[0].DisplayOrder)
+	require.Len(t, services, 4)
+	assert.Equal(t, "hair", *services[0].CategoryID)
+	assert.Equal(t, categories[0].ID, *services[1].CategoryID)
+	assert.Equal(t, categories[0].ID, *services[2].CategoryID)
+	assert.Nil(t, services[3].CategoryID)
+	assert.Equal(t, []string{categories[0].ID}, records.CategoryIDs)
+	assert.Len(t, records.ServiceIDs, 4)
+	assert.NotEmpty(t, services[0].ID)
+}
+
+func TestPlanStaffImport(t *testing.T) {
+	file := "first_name,last_name,email\nAna,Silva,ana@example.com\nRui,Costa,rui@example.com\n"
+	rows, err := ParseStaffCSV(strings.NewReader(file), "business-1")
+	require.NoError(t, err)
+
+	existing := []*User{{BaseModel: BaseModel{ID: "user-1"}, Email: "Ana@example.com"}}
+	users, staff, records := PlanStaffImport(rows, existing, nil)
+
+	require.Len(t, users, 1)
+	assert.Equal(t, "rui@example.com", users[0].Email)
+	require.Len(t, staff, 2)
+	assert.Equal(t, "user-1", staff[0].UserID)
+	assert.Equal(t, users[0].ID, staff[1].UserID)
+	assert.Equal(t, []string{users[0].ID}, records.UserIDs)
+	assert.Equal(t, []string{staff[0].ID, staff[1].ID}, records.StaffIDs)
+}
+
+func TestParseImportDuration(t *testing.T) {
+	tests := map[string]int{"90": 90, "90 min": 90, "1h30": 90, "1h 30m": 90, "1:30": 90, "2h": 120}
+	for value, expected := range tests {
+		minutes, ok := parseImportDuration(value)
+		assert.True(t, ok, value)
+		assert.Equal(t, expected, minutes, value)
+	}
+	for _, value := range []string{"", "an hour", "1:75", "1h-5"} {
+		_, ok := parseImportDuration(value)
+		assert.False(t, ok, value)
+	}
+}
+
+func TestParseImportPrice(t *testing.T) {
+	tests := map[string]string{"25": "25", "25,50": "25.5", "€1.250,00": "1250", "$1,250": "1250", "12.5": "12.5"}
+	for value, expected := range tests {
+		price, ok := parseImportPrice(value)
+		assert.True(t, ok, value)
+		assert.Equal(t, expected, price.String(), value)
+	}
+	for _, value := range []string{"free", "1.2.3.4", "1.2.3,50", "1,2345"} {
+		_, ok := parseImportPrice(value)
+		assert.False(t, ok, value)
+	}
+}
+
+func TestImportBatch_RollBack(t *testing.T) {
+	batch := &ImportBatch{Status: ImportBatchImported}
+	require.NoError(t, batch.SetRecords(ImportRecords{ServiceIDs: []string{"service-1"}}))
+
+	records, err := batch.GetRecords()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"service-1"}, records.ServiceIDs)
+
+	assert.True(t, batch.CanRollBack())
+	by := "user-1"
+	batch.MarkRolledBack(&by, time.Now())
+	assert.False(t, batch.CanRollBack())
+	assert.Equal(t, ImportBatchRolledBack, batch.Status)
+	assert.Equal(t, &by, batch.RolledBackBy)
+}
@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+)
+
+// BusinessMode is the way a business is run, which decides the capabilities it has
+type BusinessMode string
+
+const (
+	BusinessModeTeam BusinessMode = "team" // A salon with a team of staff members
+	BusinessModeSolo BusinessMode = "solo" // A professional working on their own
+)
+
+// IsValid checks if the business mode is valid
+func (m BusinessMode) IsValid() bool {
+	return m == BusinessModeTeam || m == BusinessModeSolo
+}
+
+// Capability is a part of the app that only some businesses use. Clients hide the screens of
+// the capabilities a business lacks, and services refuse their operations.
+type Capability string
+
+const (
+	CapabilityStaffManagement Capability = "staff_management" // Managing other staff members, their roles and their pay
+	CapabilityStaffCalendars  Capability = "staff_calendars"  // A calendar per staff member, with each booking assigned to one
+)
+
+// modeCapabilities lists the capabilities of each business mode
+var modeCapabilities = map[BusinessMode][]Capability{
+	BusinessModeTeam: {CapabilityStaffManagement, CapabilityStaffCalendars},
+	BusinessModeSolo: {},
+}
+
+// GetMode returns the mode of the business; businesses without one are run by a team
+func (b Business) GetMode() BusinessMode {
+	if b.Mode == "" {
+		return BusinessModeTeam
+	}
+	return b.Mode
+}
+
+// Capabilities returns the capabilities of the business
+func (b Business) Capabilities() []Capability {
+	return modeCapabilities[b.GetMode()]
+}
+
+// Can reports whether the business has a capability
+func (b Business) Can(capability Capability) bool {
+	return slices.Contains(b.Capabilities(), capability)
+}
+
+// CheckModeChange checks that the business can switch to a mode given its active staff. A
+// solo business is run by its owner alone.
+func (b Business) CheckModeChange(mode BusinessMode, activeStaff []*Staff) error {
+	if !mode.IsValid() {
+		return fmt.Errorf("%w: invalid business mode %q", ErrValidation, mode)
+	}
+	if mode != BusinessModeSolo {
+		return nil
+	}
+	for _, staff := range activeStaff {
+		if staff.UserID != b.UserID {
+			return fmt.Errorf("%w: a solo business cannot have other active staff members", ErrValidation)
+		}
+	}
+	return nil
+}
+
+// SoleStaff returns the staff member who takes every booking of a business without staff
+// calendars
+func SoleStaff(activeStaff []*Staff) (*Staff, error) {
+	if len(activeStaff) != 1 {
+		return nil, fmt.Errorf("%w: the business has %d active staff members instead of one", ErrValidation, len(activeStaff))
+	}
+	return activeStaff[0], nil
+}
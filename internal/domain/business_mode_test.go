@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBusiness_Capabilities(t *testing.T) {
+	team := Business{Mode: BusinessModeTeam}
+	assert.True(t, team.Can(CapabilityStaffManagement))
+	assert.True(t, team.Can(CapabilityStaffCalendars))
+
+	solo := Business{Mode: BusinessModeSolo}
+	assert.False(t, solo.Can(CapabilityStaffManagement))
+	assert.False(t, solo.Can(CapabilityStaffCalendars))
+
+	legacy := Business{}
+	assert.Equal(t, BusinessModeTeam, legacy.GetMode())
+	assert.True(t, legacy.Can(CapabilityStaffCalendars))
+}
+
+func TestBusiness_CheckModeChange(t *testing.T) {
+	business := Business{UserID: "owner", Mode: BusinessModeTeam}
+	owner := &Staff{UserID: "owner", Role: BusinessRoleOwner, IsActive: true}
+	employee := &Staff{UserID: "employee", Role: BusinessRoleEmployee, IsActive: true}
+
+	assert.NoError(t, business.CheckModeChange(BusinessModeSolo, []*Staff{owner}))
+	assert.ErrorIs(t, business.CheckModeChange(BusinessModeSolo, []*Staff{owner, employee}), ErrValidation)
+	assert.NoError(t, business.CheckModeChange(BusinessModeTeam, []*Staff{owner, employee}))
+	assert.ErrorIs(t, business.CheckModeChange("duo", nil), ErrValidation)
+}
+
+func TestSoleStaff(t *testing.T) {
+	owner := &Staff{UserID: "owner"}
+	staff, err := SoleStaff([]*Staff{owner})
+	require.NoError(t, err)
+	assert.Same(t, owner, staff)
+
+	_, err = SoleStaff(nil)
+	assert.ErrorIs(t, err, ErrValidation)
+	_, err = SoleStaff([]*Staff{owner, {UserID: "other"}})
+	assert.ErrorIs(t, err, ErrValidation)
+}
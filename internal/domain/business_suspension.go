@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrBusinessSuspended is returned for requests made for a business platform operators suspended
+var ErrBusinessSuspended = errors.New("business suspended")
+
+// MaxSuspensionReasonLength is the longest reason a business can be suspended with
+const MaxSuspensionReasonLength = 500
+
+// BusinessStatus is whether a business can be used, as platform operators filter businesses by
+type BusinessStatus string
+
+const (
+	BusinessStatusActive    BusinessStatus = "active"    // Can be used and booked
+	BusinessStatusSuspended BusinessStatus = "suspended" // Suspended by platform operators
+	BusinessStatusInactive  BusinessStatus = "inactive"  // Deactivated without being suspended
+)
+
+// IsValid checks if the business status is a known one
+func (s BusinessStatus) IsValid() bool {
+	switch s {
+	case BusinessStatusActive, BusinessStatusSuspended, BusinessStatusInactive:
+		return true
+	}
+	return false
+}
+
+// Status returns whether the business can be used
+func (b Business) Status() BusinessStatus {
+	switch {
+	case b.SuspendedAt != nil:
+		return BusinessStatusSuspended
+	case !b.IsActive:
+		return BusinessStatusInactive
+	}
+	return BusinessStatusActive
+}
+
+// IsSuspended checks if platform operators suspended the business
+func (b Business) IsSuspended() bool {
+	return b.SuspendedAt != nil
+}
+
+// Suspend stops the business from being used or booked until it is reactivated
+func (b *Business) Suspend(reason string, now time.Time) error {
+	if b.SuspendedAt != nil {
+		return fmt.Errorf("%w: the business is already suspended", ErrValidation)
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" || len(reason) > MaxSuspensionReasonLength {
+		return fmt.Errorf("%w: a suspension needs a reason of at most %d characters", ErrValidation, MaxSuspensionReasonLength)
+	}
+	b.SuspendedAt = &now
+	b.SuspensionReason = &reason
+	b.IsActive = false
+	return nil
+}
+
+// Reactivate lifts the suspension of the business
+func (b *Business) Reactivate() error {
+	if b.SuspendedAt == nil {
+		return fmt.Errorf("%w: the business is not suspended", ErrValidation)
+	}
+	b.SuspendedAt = nil
+	b.SuspensionReason = nil
+	b.IsActive = true
+	return nil
+}
+
+// BusinessSearch filters the businesses of every tenant, for platform operators
+type BusinessSearch struct {
+	Query  string            // Matched against the name, display name and email; any when empty
+	Status *BusinessStatus   // Any when nil
+	Tier   *SubscriptionTier // Any when nil
+}
+
+// Matches checks if a business passes the filter
+func (s BusinessSearch) Matches(b *Business) bool {
+	if s.Status != nil && b.Status() != *s.Status {
+		return false
+	}
+	if s.Tier != nil && b.Tier() != *s.Tier {
+		return false
+	}
+	if query := strings.ToLower(strings.TrimSpace(s.Query)); query != "" {
+		return strings.Contains(strings.ToLower(b.Name), query) ||
+			strings.Contains(strings.ToLower(b.Email), query) ||
+			(b.DisplayName != nil && strings.Contains(strings.ToLower(*b.DisplayName), query))
+	}
+	return true
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBusiness_Suspend(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	business := Business{Name: "Salão Rosa", IsActive: true}
+	assert.Equal(t, BusinessStatusActive, business.Status())
+
+	assert.True(t, errors.Is(business.Suspend("  ", now), ErrValidation), "a reason is required")
+	assert.True(t, errors.Is(business.Suspend(strings.Repeat("x", MaxSuspensionReasonLength+1), now), ErrValidation))
+	assert.True(t, errors.Is(business.Reactivate(), ErrValidation), "only suspended businesses are reactivated")
+
+	require.NoError(t, business.Suspend(" Chargeback fraud ", now))
+	assert.True(t, business.IsSuspended())
+	assert.False(t, business.IsActive, "suspended businesses cannot be booked")
+	assert.Equal(t, BusinessStatusSuspended, business.Status())
+	assert.Equal(t, "Chargeback fraud", *business.SuspensionReason)
+	assert.True(t, errors.Is(business.Suspend("again", now), ErrValidation))
+
+	require.NoError(t, business.Reactivate())
+	assert.False(t, business.IsSuspended())
+	assert.True(t, business.IsActive)
+	assert.Nil(t, business.SuspensionReason)
+	assert.Equal(t, BusinessStatusActive, business.Status())
+
+	business.IsActive = false
+	assert.Equal(t, BusinessStatusInactive, business.Status())
+}
+
+func TestBusinessSearch_Matches(t *testing.T) {
+	displayName := "Rosa Beauty"
+	business := &Business{Name: "Salão Rosa", DisplayName: &displayName, Email: "ola@rosa.pt", IsActive: true, SubscriptionTier: "pro"}
+	suspended, pro, free := BusinessStatusSuspended, TierPro, TierFree
+
+	assert.True(t, BusinessSearch{}.Matches(business))
+	assert.True(t, BusinessSearch{Query: "ROSA.PT"}.Matches(business), "the email is searched")
+	assert.True(t, BusinessSearch{Query: "beauty", Tier: &pro}.Matches(business), "the display name is searched")
+	assert.False(t, BusinessSearch{Query: "lisboa"}.Matches(business))
+	assert.False(t, BusinessSearch{Tier: &free}.Matches(business))
+	assert.False(t, BusinessSearch{Status: &suspended}.Matches(business))
+}
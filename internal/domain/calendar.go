@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// CalendarEntry is the denormalized calendar read model of an appointment. Entries are
+// derived from appointments, clients, staff and services and are never edited directly.
+type CalendarEntry struct {
+	AppointmentID string            `gorm:"primaryKey;type:uuid" json:"appointment_id"`
+	BusinessID    string            `gorm:"not null;type:uuid" json:"business_id"`
+	StaffID       string            `gorm:"not null;type:uuid" json:"staff_id"`
+	ClientID      string            `gorm:"not null;type:uuid" json:"client_id"`
+	CalendarDate  time.Time         `gorm:"not null;type:date" json:"calendar_date"` // Local date in the business time zone
+	StartTime     time.Time         `gorm:"not null" json:"start_time"`
+	EndTime       time.Time         `gorm:"not null" json:"end_time"`
+	Status        AppointmentStatus `gorm:"not null;size:20" json:"status"`
+	ClientName    string            `gorm:"not null;size:201" json:"client_name"`
+	ClientPhone   *string           `gorm:"size:50" json:"client_phone,omitempty"`
+	StaffName     string            `gorm:"not null;size:201" json:"staff_name"`
+	ServiceNames  *string           `gorm:"type:jsonb;default:'[]'" json:"service_names,omitempty"` // JSON array of service names
+	TotalDuration int               `gorm:"not null;default:0" json:"total_duration"`               // in minutes
+	Price         *decimal.Decimal  `gorm:"type:decimal(10,2)" json:"price,omitempty"`
+	Notes         *string           `gorm:"type:text" json:"notes,omitempty"`
+	ProjectedAt   time.Time         `gorm:"not null" json:"projected_at"`
+}
+
+// TableName returns the table name for CalendarEntry
+func (CalendarEntry) TableName() string { return "calendar_entries" }
+
+// GetServiceNames decodes the names of the appointment's services
+func (e *CalendarEntry) GetServiceNames() []string {
+	names := []string{}
+	if e.ServiceNames == nil || *e.ServiceNames == "" {
+		return names
+	}
+	_ = json.Unmarshal([]byte(*e.ServiceNames), &names)
+	return names
+}
+
+// CalendarRepository defines the repository interface for the calendar read model
+type CalendarRepository interface {
+	FindByDateRange(ctx context.Context, businessID string, startDate, endDate time.Time, staffID *string) ([]*CalendarEntry, error)
+	FindByAppointmentID(ctx context.Context, appointmentID string) (*CalendarEntry, error)
+	RefreshAppointment(ctx context.Context, appointmentID string) error
+	RefreshClient(ctx context.Context, clientID string) error
+	RefreshStaff(ctx context.Context, staffID string) error
+	RebuildBusiness(ctx context.Context, businessID string) error
+}
@@ -0,0 +1,331 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// CampaignType describes what a marketing campaign is for
+type CampaignType string
+
+const (
+	CampaignPromotion    CampaignType = "promotion"
+	CampaignSeasonal     CampaignType = "seasonal"
+	CampaignReactivation CampaignType = "reactivation" // Brings back clients who stopped visiting
+	CampaignBirthday     CampaignType = "birthday"
+)
+
+// CampaignClientStatus tracks how a targeted client responded to a campaign
+type CampaignClientStatus string
+
+const (
+	CampaignClientPending      CampaignClientStatus = "pending"
+	CampaignClientSent         CampaignClientStatus = "sent"
+	CampaignClientOpened       CampaignClientStatus = "opened"
+	CampaignClientClicked      CampaignClientStatus = "clicked"
+	CampaignClientConverted    CampaignClientStatus = "converted"
+	CampaignClientUnsubscribed CampaignClientStatus = "unsubscribed"
+)
+
+// MaxClientTags is the most tags a client can have
+const MaxClientTags = 20
+
+// TargetAudience selects the clients of a business a campaign is sent to. Every criterion set
+// must hold; an empty audience targets every active client. Visits and spend count completed
+// appointments only.
+type TargetAudience struct {
+	VisitedWithinDays *int             `json:"visited_within_days,omitempty"`  // Last visit at most this many days ago
+	NotVisitedForDays *int             `json:"not_visited_for_days,omitempty"` // Last visit more than this many days ago; never visited clients are left out
+	MinVisits         *int             `json:"min_visits,omitempty"`
+	MaxVisits         *int             `json:"max_visits,omitempty"`
+	MinSpend          *decimal.Decimal `json:"min_spend,omitempty"`
+	MaxSpend          *decimal.Decimal `json:"max_spend,omitempty"`
+	BirthdayMonth     *int             `json:"birthday_month,omitempty"` // 1 for January
+	Tags              []string         `json:"tags,omitempty"`           // Clients with any of the tags
+	LoyaltyProgramID  *string          `json:"loyalty_program_id,omitempty"`
+	MinLoyaltyPoints  *int             `json:"min_loyalty_points,omitempty"` // Requires LoyaltyProgramID
+	LoyaltyTier       *string          `json:"loyalty_tier,omitempty"`       // Requires LoyaltyProgramID
+	AcceptsMarketing  bool             `json:"accepts_marketing,omitempty"`  // Only clients who accepted marketing messages
+	VIPOnly           bool             `json:"vip_only,omitempty"`           // Only clients tagged VIPTag
+}
+
+// Validate checks that the criteria are consistent
+func (a TargetAudience) Validate() error {
+	for _, days := range []*int{a.VisitedWithinDays, a.NotVisitedForDays, a.MinVisits, a.MaxVisits, a.MinLoyaltyPoints} {
+		if days != nil && *days < 0 {
+			return fmt.Errorf("%w: audience criteria cannot be negative", ErrValidation)
+		}
+	}
+	if (a.MinSpend != nil && a.MinSpend.IsNegative()) || (a.MaxSpend != nil && a.MaxSpend.IsNegative()) {
+		return fmt.Errorf("%w: audience criteria cannot be negative", ErrValidation)
+	}
+	if a.MinVisits != nil && a.MaxVisits != nil && *a.MinVisits > *a.MaxVisits {
+		return fmt.Errorf("%w: min_visits cannot be more than max_visits", ErrValidation)
+	}
+	if a.MinSpend != nil && a.MaxSpend != nil && a.MinSpend.GreaterThan(*a.MaxSpend) {
+		return fmt.Errorf("%w: min_spend cannot be more than max_spend", ErrValidation)
+	}
+	if a.VisitedWithinDays != nil && a.NotVisitedForDays != nil && *a.NotVisitedForDays >= *a.VisitedWithinDays {
+		return fmt.Errorf("%w: no visit can be both within %s and more than %s ago", ErrValidation,
+			countOf(*a.VisitedWithinDays, "day"), countOf(*a.NotVisitedForDays, "day"))
+	}
+	if a.BirthdayMonth != nil && (*a.BirthdayMonth < 1 || *a.BirthdayMonth > 12) {
+		return fmt.Errorf("%w: birthday_month must be between 1 and 12", ErrValidation)
+	}
+	if a.LoyaltyProgramID == nil && (a.MinLoyaltyPoints != nil || a.LoyaltyTier != nil) {
+		return fmt.Errorf("%w: loyalty criteria require a loyalty program", ErrValidation)
+	}
+	return nil
+}
+
+// VisitedSince returns the earliest last visit of clients visited within the days, if set
+func (a TargetAudience) VisitedSince(now time.Time) *time.Time {
+	return daysBefore(now, a.VisitedWithinDays)
+}
+
+// NotVisitedSince returns the time clients' last visit must be before, if set
+func (a TargetAudience) NotVisitedSince(now time.Time) *time.Time {
+	return daysBefore(now, a.NotVisitedForDays)
+}
+
+// daysBefore returns the time the days before now
+func daysBefore(now time.Time, days *int) *time.Time {
+	if days == nil {
+		return nil
+	}
+	at := now.AddDate(0, 0, -*days)
+	return &at
+}
+
+// AudienceFacts is what the audience criteria are checked against for one client
+type AudienceFacts struct {
+	Client      *Client
+	Tags        []string
+	Visits      int
+	LastVisit   *time.Time
+	Spent       decimal.Decimal
+	Memberships []*ClientLoyaltyMembership
+}
+
+// Matches reports whether a client belongs to the audience at the given time. The SQL
+// repositories apply the same criteria in the database.
+func (a TargetAudience) Matches(facts AudienceFacts, now time.Time) bool {
+	client := facts.Client
+	if !client.IsActive || client.AnonymizedAt != nil {
+		return false
+	}
+	if a.AcceptsMarketing && !client.AcceptsMarketing {
+		return false
+	}
+	if since := a.VisitedSince(now); since != nil && (facts.LastVisit == nil || facts.LastVisit.Before(*since)) {
+		return false
+	}
+	if since := a.NotVisitedSince(now); since != nil && (facts.LastVisit == nil || !facts.LastVisit.Before(*since)) {
+		return false
+	}
+	if (a.MinVisits != nil && facts.Visits < *a.MinVisits) || (a.MaxVisits != nil && facts.Visits > *a.MaxVisits) {
+		return false
+	}
+	if (a.MinSpend != nil && facts.Spent.LessThan(*a.MinSpend)) || (a.MaxSpend != nil && facts.Spent.GreaterThan(*a.MaxSpend)) {
+		return false
+	}
+	if a.BirthdayMonth != nil && (client.DateOfBirth == nil || int(client.DateOfBirth.Month()) != *a.BirthdayMonth) {
+		return false
+	}
+	if len(a.Tags) > 0 && !slices.ContainsFunc(a.Tags, func(tag string) bool { return slices.Contains(facts.Tags, tag) }) {
+		return false
+	}
+	if a.VIPOnly && !slices.Contains(facts.Tags, VIPTag) {
+		return false
+	}
+	if a.LoyaltyProgramID != nil {
+		return slices.ContainsFunc(facts.Memberships, func(m *ClientLoyaltyMembership) bool {
+			return m.ProgramID == *a.LoyaltyProgramID && m.IsActive &&
+				(a.MinLoyaltyPoints == nil || m.CurrentPoints >= *a.MinLoyaltyPoints) &&
+				(a.LoyaltyTier == nil || (m.TierLevel != nil && *m.TierLevel == *a.LoyaltyTier))
+		})
+	}
+	return true
+}
+
+// NormalizeClientTags lower-cases and trims tags, dropping empty and repeated ones
+func NormalizeClientTags(tags []string) ([]string, error) {
+	normalized := []string{}
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || slices.Contains(normalized, tag) {
+			continue
+		}
+		if len(tag) > 50 {
+			return nil, fmt.Errorf("%w: tags cannot be longer than 50 characters", ErrValidation)
+		}
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) > MaxClientTags {
+		return nil, fmt.Errorf("%w: a client cannot have more than %d tags", ErrValidation, MaxClientTags)
+	}
+	return normalized, nil
+}
+
+// GetTags decodes the client's tags
+func (c *Client) GetTags() ([]string, error) {
+	return decodeIDs(c.Tags)
+}
+
+// SetTags encodes the client's tags
+func (c *Client) SetTags(tags []string) error {
+	encoded, err := encodeIDs(tags)
+	if err != nil {
+		return err
+	}
+	c.Tags = encoded
+	return nil
+}
+
+// Campaign is a marketing campaign of a business, sent to the clients of its target audience
+type Campaign struct {
+	BaseModel
+	BusinessID      string       `gorm:"not null;type:uuid;index" json:"business_id"`
+	Name            string       `gorm:"not null;size:100" json:"name"`
+	Description     *string      `gorm:"type:text" json:"description,omitempty"`
+	CampaignType    CampaignType `gorm:"not null;size:50" json:"campaign_type"`
+	TargetAudience  *string      `gorm:"type:jsonb" json:"target_audience,omitempty"` // JSON TargetAudience
+	OfferType       string       `gorm:"not null;size:50" json:"offer_type"`
+	OfferDetails    string       `gorm:"type:jsonb;not null" json:"offer_details"`
+	StartDate       time.Time    `gorm:"not null" json:"start_date"`
+	EndDate         time.Time    `gorm:"not null" json:"end_date"`
+	IsActive        bool         `gorm:"not null;default:true" json:"is_active"`
+	MessageTemplate *string      `gorm:"type:text" json:"message_template,omitempty"`
+}
+
+// TableName returns the table name for Campaign
+func (Campaign) TableName() string { return "campaigns" }
+
+// GetTargetAudience decodes the campaign's audience criteria
+func (c *Campaign) GetTargetAudience() (TargetAudience, error) {
+	var audience TargetAudience
+	if c.TargetAudience == nil || *c.TargetAudience == "" {
+		return audience, nil
+	}
+	if err := json.Unmarshal([]byte(*c.TargetAudience), &audience); err != nil {
+		return audience, fmt.Errorf("%w: invalid target audience", ErrValidation)
+	}
+	return audience, nil
+}
+
+// SetTargetAudience encodes the campaign's audience criteria
+func (c *Campaign) SetTargetAudience(audience TargetAudience) error {
+	data, err := json.Marshal(audience)
+	if err != nil {
+		return err
+	}
+	encoded := string(data)
+	c.TargetAudience = &encoded
+	return nil
+}
+
+// Validate validates the campaign model
+func (c *Campaign) Validate() error {
+	if c.BusinessID == "" || strings.TrimSpace(c.Name) == "" || c.OfferType == "" {
+		return ErrValidation
+	}
+	switch c.CampaignType {
+	case CampaignPromotion, CampaignSeasonal, CampaignReactivation, CampaignBirthday:
+	default:
+		return fmt.Errorf("%w: unknown campaign type %q", ErrValidation, c.CampaignType)
+	}
+	if !c.EndDate.After(c.StartDate) {
+		return fmt.Errorf("%w: end_date must be after start_date", ErrValidation)
+	}
+	audience, err := c.GetTargetAudience()
+	if err != nil {
+		return err
+	}
+	return audience.Validate()
+}
+
+// CampaignClient is a client targeted by a campaign, with how they responded to it
+type CampaignClient struct {
+	BaseModel
+	CampaignID      string               `gorm:"not null;type:uuid;index" json:"campaign_id"`
+	ClientID        string               `gorm:"not null;type:uuid;index" json:"client_id"`
+	Status          CampaignClientStatus `gorm:"not null;size:20;default:'pending'" json:"status"`
+	SentAt          *time.Time           `gorm:"" json:"sent_at,omitempty"`
+	OpenedAt        *time.Time           `gorm:"" json:"opened_at,omitempty"`
+	ClickedAt       *time.Time           `gorm:"" json:"clicked_at,omitempty"`
+	ConvertedAt     *time.Time           `gorm:"" json:"converted_at,omitempty"`
+	ConversionValue *decimal.Decimal     `gorm:"type:decimal(10,2)" json:"conversion_value,omitempty"`
+}
+
+// TableName returns the table name for CampaignClient
+func (CampaignClient) TableName() string { return "campaign_clients" }
+
+// CampaignAnalytics aggregates how the clients targeted by a campaign responded to it within a
+// time range. Each stage counts the clients who reached it within the range.
+type CampaignAnalytics struct {
+	CampaignID   string
+	From         time.Time
+	To           time.Time
+	Targeted     int64
+	Sent         int64
+	Opened       int64
+	Clicked      int64
+	Converted    int64
+	Unsubscribed int64           // Counted whenever it happened, as unsubscribing is not timestamped
+	Visits       int64           // Visits converted clients completed on or after converting
+	Revenue      decimal.Decimal // Charged for those visits
+}
+
+// OpenRate returns the share of sent messages that were opened, between 0 and 1
+func (a *CampaignAnalytics) OpenRate() float64 { return share(a.Opened, a.Sent) }
+
+// ClickRate returns the share of sent messages that were clicked, between 0 and 1
+func (a *CampaignAnalytics) ClickRate() float64 { return share(a.Clicked, a.Sent) }
+
+// ConversionRate returns the share of sent messages that converted, between 0 and 1
+func (a *CampaignAnalytics) ConversionRate() float64 { return share(a.Converted, a.Sent) }
+
+// share returns part as a share of total, or 0 when the total is 0
+func share(part, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total)
+}
+
+// AnalyticsRange returns the range a campaign's analytics cover: from start, or since the
+// campaign was created, to end, or now
+func (c *Campaign) AnalyticsRange(start, end *time.Time, now time.Time) (time.Time, time.Time, error) {
+	from, to := c.CreatedAt, now
+	if start != nil {
+		from = *start
+	}
+	if end != nil {
+		to = *end
+	}
+	if !from.Before(to) {
+		return from, to, fmt.Errorf("%w: the start must be before the end", ErrValidation)
+	}
+	return from, to, nil
+}
+
+// CampaignClientRepository defines the repository interface for the clients targeted by campaigns
+type CampaignClientRepository interface {
+	BaseRepository[CampaignClient]
+	// CountAudience counts the business's clients in the audience at the given time
+	CountAudience(ctx context.Context, businessID string, audience TargetAudience, now time.Time) (int64, error)
+	// TargetClients adds the clients in the campaign's audience at the given time to the
+	// campaign, skipping clients targeted already, and returns how many were added
+	TargetClients(ctx context.Context, campaign *Campaign, audience TargetAudience, now time.Time, userID *string) (int64, error)
+	// FindByCampaignID finds the clients targeted by a campaign, oldest first
+	FindByCampaignID(ctx context.Context, campaignID string) ([]*CampaignClient, error)
+	// GetCampaignAnalytics aggregates the responses to a campaign between from and to, with
+	// the revenue of the visits its converted clients completed
+	GetCampaignAnalytics(ctx context.Context, campaign *Campaign, from, to time.Time) (*CampaignAnalytics, error)
+}
@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTargetAudience_Validate(t *testing.T) {
+	days := func(n int) *int { return &n }
+	assert.NoError(t, TargetAudience{}.Validate())
+	assert.NoError(t, TargetAudience{VisitedWithinDays: days(365), NotVisitedForDays: days(90)}.Validate())
+
+	assert.ErrorIs(t, TargetAudience{MinVisits: days(-1)}.Validate(), ErrValidation)
+	assert.ErrorIs(t, TargetAudience{MinVisits: days(5), MaxVisits: days(2)}.Validate(), ErrValidation)
+	assert.ErrorIs(t, TargetAudience{VisitedWithinDays: days(30), NotVisitedForDays: days(60)}.Validate(), ErrValidation,
+		"no last visit is both recent and lapsed")
+	assert.ErrorIs(t, TargetAudience{BirthdayMonth: days(13)}.Validate(), ErrValidation)
+	assert.ErrorIs(t, TargetAudience{MinLoyaltyPoints: days(100)}.Validate(), ErrValidation, "loyalty criteria need a program")
+}
+
+func TestTargetAudience_Matches(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	days := func(n int) *int { return &n }
+	birthday := time.Date(1990, 6, 3, 0, 0, 0, 0, time.UTC)
+	lastVisit := now.AddDate(0, 0, -120)
+	program := "program-1"
+	gold := "gold"
+	facts := AudienceFacts{
+		Client:      &Client{IsActive: true, DateOfBirth: &birthday},
+		Tags:        []string{"vip", "bridal"},
+		Visits:      6,
+		LastVisit:   &lastVisit,
+		Spent:       decimal.NewFromInt(480),
+		Memberships: []*ClientLoyaltyMembership{{ProgramID: program, IsActive: true, CurrentPoints: 300, TierLevel: &gold}},
+	}
+
+	minSpend := decimal.NewFromInt(400)
+	assert.True(t, TargetAudience{}.Matches(facts, now), "an empty audience targets every active client")
+	assert.True(t, TargetAudience{NotVisitedForDays: days(90), MinVisits: days(5), MinSpend: &minSpend}.Matches(facts, now),
+		"a lapsed regular")
+	assert.False(t, TargetAudience{VisitedWithinDays: days(90)}.Matches(facts, now))
+	assert.True(t, TargetAudience{BirthdayMonth: days(6)}.Matches(facts, now))
+	assert.False(t, TargetAudience{BirthdayMonth: days(7)}.Matches(facts, now))
+	assert.True(t, TargetAudience{Tags: []string{"colour", "vip"}}.Matches(facts, now), "any of the tags")
+	assert.False(t, TargetAudience{Tags: []string{"colour"}}.Matches(facts, now))
+	assert.True(t, TargetAudience{LoyaltyProgramID: &program, MinLoyaltyPoints: days(250), LoyaltyTier: &gold}.Matches(facts, now))
+	assert.False(t, TargetAudience{LoyaltyProgramID: &program, MinLoyaltyPoints: days(500)}.Matches(facts, now))
+	assert.False(t, TargetAudience{AcceptsMarketing: true}.Matches(facts, now), "the client did not accept marketing")
+	assert.True(t, TargetAudience{VIPOnly: true}.Matches(facts, now))
+	regular := facts
+	regular.Tags = []string{"bridal"}
+	assert.False(t, TargetAudience{VIPOnly: true}.Matches(regular, now))
+
+	newcomer := AudienceFacts{Client: &Client{IsActive: true}}
+	assert.False(t, TargetAudience{NotVisitedForDays: days(90)}.Matches(newcomer, now), "clients who never visited have not lapsed")
+	assert.True(t, TargetAudience{MaxVisits: days(0)}.Matches(newcomer, now))
+
+	anonymized := AudienceFacts{Client: &Client{IsActive: true, AnonymizedAt: &now}}
+	assert.False(t, TargetAudience{}.Matches(anonymized, now))
+}
+
+func TestNormalizeClientTags(t *testing.T) {
+	tags, err := NormalizeClientTags([]string{" VIP ", "vip", "", "Bridal"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"vip", "bridal"}, tags)
+
+	many := make([]string, MaxClientTags+1)
+	for i := range many {
+		many[i] = string(rune('a' + i))
+	}
+	_, err = NormalizeClientTags(many)
+	assert.ErrorIs(t, err, ErrValidation)
+}
+
+func TestCampaign_Validate(t *testing.T) {
+	start := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	campaign := &Campaign{BusinessID: "business-1", Name: "Summer", CampaignType: CampaignSeasonal, OfferType: "discount",
+		StartDate: start, EndDate: start.AddDate(0, 1, 0)}
+	require.NoError(t, campaign.Validate())
+
+	visits := 5
+	require.NoError(t, campaign.SetTargetAudience(TargetAudience{MinVisits: &visits}))
+	audience, err := campaign.GetTargetAudience()
+	require.NoError(t, err)
+	assert.Equal(t, 5, *audience.MinVisits)
+
+	campaign.CampaignType = "newsletter"
+	assert.ErrorIs(t, campaign.Validate(), ErrValidation)
+	campaign.CampaignType = CampaignSeasonal
+	campaign.EndDate = start
+	assert.ErrorIs(t, campaign.Validate(), ErrValidation)
+}
+
+func TestCampaignAnalytics_Rates(t *testing.T) {
+	analytics := &CampaignAnalytics{Sent: 200, Opened: 90, Clicked: 30, Converted: 12}
+	assert.InDelta(t, 0.45, analytics.OpenRate(), 1e-9)
+	assert.InDelta(t, 0.15, analytics.ClickRate(), 1e-9)
+	assert.InDelta(t, 0.06, analytics.ConversionRate(), 1e-9)
+	assert.Zero(t, (&CampaignAnalytics{Opened: 3}).OpenRate(), "nothing sent yet")
+}
+
+func TestCampaign_AnalyticsRange(t *testing.T) {
+	created := time.Date(2024, 5, 20, 10, 0, 0, 0, time.UTC)
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	campaign := &Campaign{}
+	campaign.CreatedAt = created
+
+	from, to, err := campaign.AnalyticsRange(nil, nil, now)
+	require.NoError(t, err)
+	assert.Equal(t, created, from, "defaults to since the campaign was created")
+	assert.Equal(t, now, to)
+
+	start := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	from, to, err = campaign.AnalyticsRange(&start, nil, now)
+	require.NoError(t, err)
+	assert.Equal(t, start, from)
+	assert.Equal(t, now, to)
+
+	_, _, err = campaign.AnalyticsRange(&now, &start, now)
+	assert.ErrorIs(t, err, ErrValidation)
+}
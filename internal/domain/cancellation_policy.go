@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// MaxCancellationNoticeHours is the longest notice a business may ask for, a week
+	MaxCancellationNoticeHours = 168
+	// MaxNoShowLimit bounds the no-shows a business may let clients reach before flagging them
+	MaxNoShowLimit = 20
+)
+
+// CancellationPolicy is how a business treats late cancellations and no-shows, taken from its
+// settings. A cancellation with less than NoticeHours notice counts as a no-show: the client is
+// charged NoShowFee and their no-show counter goes up. Clients reaching MaxNoShows are flagged
+// and must pay a deposit to book.
+type CancellationPolicy struct {
+	NoticeHours int              // 0 allows cancelling until the appointment starts
+	NoShowFee   *decimal.Decimal // Nil charges no fee
+	MaxNoShows  int              // 0 never flags clients
+}
+
+// GetCancellationPolicy returns the business's cancellation policy. Businesses without settings
+// have none: cancelling is free until the appointment starts and no client is flagged.
+func (s *BusinessSettings) GetCancellationPolicy() CancellationPolicy {
+	if s == nil {
+		return CancellationPolicy{}
+	}
+	return CancellationPolicy{NoticeHours: s.CancellationNoticeHours, NoShowFee: s.NoShowFee, MaxNoShows: s.MaxNoShows}
+}
+
+// SetCancellationPolicy validates and stores the business's cancellation policy
+func (s *BusinessSettings) SetCancellationPolicy(policy CancellationPolicy) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+	s.CancellationNoticeHours = policy.NoticeHours
+	s.NoShowFee = policy.NoShowFee
+	s.MaxNoShows = policy.MaxNoShows
+	return nil
+}
+
+// Validate checks that the policy's notice, fee and no-show limit are in range
+func (p CancellationPolicy) Validate() error {
+	if p.NoticeHours < 0 || p.NoticeHours > MaxCancellationNoticeHours {
+		return fmt.Errorf("%w: notice_hours must be between 0 and %d", ErrValidation, MaxCancellationNoticeHours)
+	}
+	if p.NoShowFee != nil && !p.NoShowFee.IsPositive() {
+		return fmt.Errorf("%w: no_show_fee must be positive", ErrValidation)
+	}
+	if p.MaxNoShows < 0 || p.MaxNoShows > MaxNoShowLimit {
+		return fmt.Errorf("%w: max_no_shows must be between 0 and %d", ErrValidation, MaxNoShowLimit)
+	}
+	return nil
+}
+
+// IsLateCancellation reports whether cancelling the appointment at the given time gives less
+// notice than the policy asks for
+func (p CancellationPolicy) IsLateCancellation(appointment *Appointment, now time.Time) bool {
+	return p.NoticeHours > 0 && now.After(appointment.StartTime.Add(-time.Duration(p.NoticeHours)*time.Hour))
+}
+
+// DepositDue returns the deposit a booking needs: the deposits of its services, or for a client
+// flagged for no-shows, at least the no-show fee
+func (p CancellationPolicy) DepositDue(serviceDeposits decimal.Decimal, client *Client) decimal.Decimal {
+	if client != nil && client.DepositRequired && p.NoShowFee != nil {
+		return decimal.Max(serviceDeposits, *p.NoShowFee)
+	}
+	return serviceDeposits
+}
+
+// RecordNoShow counts a no-show or late cancellation against the client, flagging them as
+// needing a deposit to book once they reach the policy's limit. It reports whether the client
+// was newly flagged.
+func (c *Client) RecordNoShow(policy CancellationPolicy) bool {
+	c.NoShowCount++
+	if policy.MaxNoShows == 0 || c.DepositRequired || c.NoShowCount < policy.MaxNoShows {
+		return false
+	}
+	c.DepositRequired = true
+	return true
+}
+
+// ClearNoShows forgives the client's no-shows, lifting the deposit requirement
+func (c *Client) ClearNoShows() {
+	c.NoShowCount = 0
+	c.DepositRequired = false
+}
+
+// CanBeMarkedNoShow reports whether the client can be recorded as not turning up: the
+// appointment was booked and has started
+func (a *Appointment) CanBeMarkedNoShow(now time.Time) bool {
+	return (a.Status == AppointmentStatusScheduled || a.Status == AppointmentStatusConfirmed) && !now.Before(a.StartTime)
+}
+
+// MarkNoShow records that the client did not turn up, charging the policy's fee
+func (a *Appointment) MarkNoShow(policy CancellationPolicy, now time.Time) error {
+	if !a.CanBeMarkedNoShow(now) {
+		return fmt.Errorf("%w: only scheduled or confirmed appointments that have started can be marked as no-shows", ErrValidation)
+	}
+	a.Status = AppointmentStatusNoShow
+	a.NoShowFee = policy.NoShowFee
+	return nil
+}
+
+// MarkLateCancellation cancels the appointment with less notice than the policy asks for,
+// charging the policy's fee
+func (a *Appointment) MarkLateCancellation(reason string, policy CancellationPolicy) {
+	a.MarkCancelled(reason)
+	a.NoShowFee = policy.NoShowFee
+}
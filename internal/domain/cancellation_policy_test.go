@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCancellationPolicy_Validate(t *testing.T) {
+	fee := decimal.NewFromInt(25)
+	zero := decimal.Zero
+	assert.NoError(t, CancellationPolicy{}.Validate())
+	assert.NoError(t, CancellationPolicy{NoticeHours: 24, NoShowFee: &fee, MaxNoShows: 3}.Validate())
+
+	for _, policy := range []CancellationPolicy{
+		{NoticeHours: -1},
+		{NoticeHours: MaxCancellationNoticeHours + 1},
+		{NoShowFee: &zero},
+		{MaxNoShows: MaxNoShowLimit + 1},
+	} {
+		assert.True(t, errors.Is(policy.Validate(), ErrValidation), "%+v", policy)
+	}
+}
+
+func TestBusinessSettings_CancellationPolicy(t *testing.T) {
+	var none *BusinessSettings
+	assert.Equal(t, CancellationPolicy{}, none.GetCancellationPolicy())
+
+	fee := decimal.NewFromInt(25)
+	settings := &BusinessSettings{}
+	require.NoError(t, settings.SetCancellationPolicy(CancellationPolicy{NoticeHours: 24, NoShowFee: &fee, MaxNoShows: 3}))
+	assert.Equal(t, 24, settings.CancellationNoticeHours)
+	assert.Equal(t, 3, settings.GetCancellationPolicy().MaxNoShows)
+	assert.Error(t, settings.SetCancellationPolicy(CancellationPolicy{NoticeHours: -1}))
+	assert.Equal(t, 24, settings.CancellationNoticeHours, "an invalid policy leaves the settings as they are")
+}
+
+func TestCancellationPolicy_IsLateCancellation(t *testing.T) {
+	start := time.Date(2026, 6, 1, 10, 0, 0, 0, time.UTC)
+	appointment := &Appointment{StartTime: start}
+	policy := CancellationPolicy{NoticeHours: 24}
+
+	assert.False(t, policy.IsLateCancellation(appointment, start.Add(-24*time.Hour)))
+	assert.True(t, policy.IsLateCancellation(appointment, start.Add(-23*time.Hour)))
+	assert.False(t, CancellationPolicy{}.IsLateCancellation(appointment, start.Add(-time.Minute)), "no notice asked for")
+}
+
+func TestCancellationPolicy_DepositDue(t *testing.T) {
+	fee := decimal.NewFromInt(25)
+	policy := CancellationPolicy{NoShowFee: &fee}
+	flagged := &Client{DepositRequired: true}
+
+	assert.Equal(t, "0", policy.DepositDue(decimal.Zero, &Client{}).String())
+	assert.Equal(t, "25", policy.DepositDue(decimal.Zero, flagged).String())
+	assert.Equal(t, "40", policy.DepositDue(decimal.NewFromInt(40), flagged).String())
+	assert.Equal(t, "0", CancellationPolicy{}.DepositDue(decimal.Zero, flagged).String(), "no fee, no deposit")
+}
+
+func TestClient_RecordNoShow(t *testing.T) {
+	policy := CancellationPolicy{MaxNoShows: 2}
+	client := &Client{}
+
+	assert.False(t, client.RecordNoShow(policy))
+	assert.False(t, client.DepositRequired)
+	assert.True(t, client.RecordNoShow(policy))
+	assert.True(t, client.DepositRequired)
+	assert.False(t, client.RecordNoShow(policy), "already flagged")
+	assert.Equal(t, 3, client.NoShowCount)
+
+	client.ClearNoShows()
+	assert.Zero(t, client.NoShowCount)
+	assert.False(t, client.DepositRequired)
+
+	assert.False(t, client.RecordNoShow(CancellationPolicy{}), "no limit never flags")
+	assert.False(t, client.DepositRequired)
+}
+
+func TestAppointment_MarkNoShow(t *testing.T) {
+	start := time.Date(2026, 6, 1, 10, 0, 0, 0, time.UTC)
+	fee := decimal.NewFromInt(25)
+	policy := CancellationPolicy{NoShowFee: &fee}
+
+	appointment := &Appointment{Status: AppointmentStatusConfirmed, StartTime: start}
+	assert.True(t, errors.Is(appointment.MarkNoShow(policy, start.Add(-time.Minute)), ErrValidation), "not started yet")
+
+	require.NoError(t, appointment.MarkNoShow(policy, start))
+	assert.Equal(t, AppointmentStatusNoShow, appointment.Status)
+	assert.Equal(t, "25", appointment.NoShowFee.String())
+	assert.True(t, errors.Is(appointment.MarkNoShow(policy, start), ErrValidation), "already a no-show")
+
+	late := &Appointment{Status: AppointmentStatusScheduled, StartTime: start}
+	late.MarkLateCancellation("running late", policy)
+	assert.Equal(t, AppointmentStatusCancelled, late.Status)
+	assert.Equal(t, "25", late.NoShowFee.String())
+}
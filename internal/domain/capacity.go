@@ -0,0 +1,192 @@
+package domain
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// CapacityWarningKind represents why a schedule is considered too dense
+type CapacityWarningKind string
+
+const (
+	CapacityWarningDayBooked CapacityWarningKind = "day_booked" // The day is booked above the business's threshold
+	CapacityWarningNoBreak   CapacityWarningKind = "no_break"   // A staff member works too long without a break
+)
+
+// Defaults used when the business has no settings
+const (
+	DefaultCapacityWarningPercent   = 90
+	DefaultMaxContinuousWorkMinutes = 240
+	DefaultMinBreakMinutes          = 15
+)
+
+// CapacityPolicy holds the thresholds above which a schedule is flagged. Warnings are soft:
+// they never prevent a booking.
+type CapacityPolicy struct {
+	DayThresholdPercent  int // 0 disables day warnings
+	MaxContinuousMinutes int // 0 disables break warnings
+	MinBreakMinutes      int // Shorter gaps between appointments do not count as a break
+}
+
+// CapacityPolicyFor returns the capacity policy configured in the business settings
+func CapacityPolicyFor(settings *BusinessSettings) CapacityPolicy {
+	if settings == nil {
+		return CapacityPolicy{
+			DayThresholdPercent:  DefaultCapacityWarningPercent,
+			MaxContinuousMinutes: DefaultMaxContinuousWorkMinutes,
+			MinBreakMinutes:      DefaultMinBreakMinutes,
+		}
+	}
+	return CapacityPolicy{
+		DayThresholdPercent:  settings.CapacityWarningPercent,
+		MaxContinuousMinutes: settings.MaxContinuousWorkMinutes,
+		MinBreakMinutes:      settings.MinBreakMinutes,
+	}
+}
+
+// BreakSuggestion proposes where to block time for a break in a staff member's day
+type BreakSuggestion struct {
+	StaffID string    `json:"staff_id"`
+	Start   time.Time `json:"start"`
+	Minutes int       `json:"minutes"`
+}
+
+// CapacityWarning flags a day, or a staff member's stretch of work, as too dense
+type CapacityWarning struct {
+	Kind            CapacityWarningKind `json:"kind"`
+	Date            time.Time           `json:"date"` // Local calendar date
+	BookedMinutes   int                 `json:"booked_minutes"`
+	CapacityMinutes int                 `json:"capacity_minutes,omitempty"` // Day warnings only
+	BookedPercent   int                 `json:"booked_percent,omitempty"`   // Day warnings only
+	StaffID         *string             `json:"staff_id,omitempty"`         // Break warnings only
+	StaffName       *string             `json:"staff_name,omitempty"`
+	Start           *time.Time          `json:"start,omitempty"` // The stretch without a break
+	End             *time.Time          `json:"end,omitempty"`
+	SuggestedBreak  *BreakSuggestion    `json:"suggested_break,omitempty"`
+}
+
+// countsTowardsCapacity checks whether an appointment occupies its staff member's time
+func countsTowardsCapacity(status AppointmentStatus) bool {
+	return status != AppointmentStatusCancelled && status != AppointmentStatusNoShow &&
+		status != AppointmentStatusRescheduled
+}
+
+// EvaluateDay returns the warnings for one local date. Entries must all fall on that date;
+// capacityMinutes is the bookable time of all staff working that day.
+func (p CapacityPolicy) EvaluateDay(date time.Time, entries []*CalendarEntry, capacityMinutes int) []*CapacityWarning {
+	var warnings []*CapacityWarning
+
+	byStaff := map[string][]*CalendarEntry{}
+	bookedMinutes := 0
+	for _, entry := range entries {
+		if !countsTowardsCapacity(entry.Status) {
+			continue
+		}
+		bookedMinutes += int(entry.EndTime.Sub(entry.StartTime).Minutes())
+		byStaff[entry.StaffID] = append(byStaff[entry.StaffID], entry)
+	}
+
+	if p.DayThresholdPercent > 0 && capacityMinutes > 0 {
+		percent := bookedMinutes * 100 / capacityMinutes
+		if percent >= p.DayThresholdPercent {
+			warnings = append(warnings, &CapacityWarning{
+				Kind:            CapacityWarningDayBooked,
+				Date:            date,
+				BookedMinutes:   bookedMinutes,
+				CapacityMinutes: capacityMinutes,
+				BookedPercent:   percent,
+			})
+		}
+	}
+
+	if p.MaxContinuousMinutes > 0 {
+		staffIDs := make([]string, 0, len(byStaff))
+		for staffID := range byStaff {
+			staffIDs = append(staffIDs, staffID)
+		}
+		sort.Strings(staffIDs)
+		for _, staffID := range staffIDs {
+			warnings = append(warnings, p.evaluateBreaks(date, byStaff[staffID])...)
+		}
+	}
+	return warnings
+}
+
+// evaluateBreaks flags the stretches of back-to-back appointments of one staff member that
+// run longer than the policy allows
+func (p CapacityPolicy) evaluateBreaks(date time.Time, entries []*CalendarEntry) []*CapacityWarning {
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].StartTime.Before(entries[j].StartTime)
+	})
+
+	var warnings []*CapacityWarning
+	minBreak := time.Duration(p.MinBreakMinutes) * time.Minute
+	for i := 0; i < len(entries); {
+		// Extend the stretch while the next appointment starts before a break could fit
+		stretch := []*CalendarEntry{entries[i]}
+		end := entries[i].EndTime
+		j := i + 1
+		for ; j < len(entries) && entries[j].StartTime.Sub(end) < minBreak; j++ {
+			stretch = append(stretch, entries[j])
+			if entries[j].EndTime.After(end) {
+				end = entries[j].EndTime
+			}
+		}
+
+		start := entries[i].StartTime
+		if minutes := int(end.Sub(start).Minutes()); minutes > p.MaxContinuousMinutes {
+			staffID, staffName := entries[i].StaffID, entries[i].StaffName
+			stretchStart, stretchEnd := start, end
+			warnings = append(warnings, &CapacityWarning{
+				Kind:           CapacityWarningNoBreak,
+				Date:           date,
+				BookedMinutes:  minutes,
+				StaffID:        &staffID,
+				StaffName:      &staffName,
+				Start:          &stretchStart,
+				End:            &stretchEnd,
+				SuggestedBreak: p.suggestBreak(staffID, stretch),
+			})
+		}
+		i = j
+	}
+	return warnings
+}
+
+// suggestBreak picks the latest appointment boundary in a stretch that keeps the work before
+// it within the limit. When even the first appointment runs over, the break goes after it.
+func (p CapacityPolicy) suggestBreak(staffID string, stretch []*CalendarEntry) *BreakSuggestion {
+	limit := stretch[0].StartTime.Add(time.Duration(p.MaxContinuousMinutes) * time.Minute)
+	breakAt := stretch[0].EndTime
+	for _, entry := range stretch[:len(stretch)-1] {
+		if entry.EndTime.After(limit) {
+			break
+		}
+		if entry.EndTime.After(breakAt) {
+			breakAt = entry.EndTime
+		}
+	}
+	return &BreakSuggestion{StaffID: staffID, Start: breakAt, Minutes: p.MinBreakMinutes}
+}
+
+// CapacityAlert records that a capacity warning was notified, so it is notified only once
+type CapacityAlert struct {
+	ID         string              `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	BusinessID string              `gorm:"not null;type:uuid" json:"business_id"`
+	AlertDate  time.Time           `gorm:"not null;type:date" json:"alert_date"`
+	Kind       CapacityWarningKind `gorm:"not null;size:20" json:"kind"`
+	StaffID    *string             `gorm:"type:uuid" json:"staff_id,omitempty"`
+	NotifiedAt time.Time           `gorm:"not null" json:"notified_at"`
+}
+
+// TableName returns the table name for CapacityAlert
+func (CapacityAlert) TableName() string { return "capacity_alerts" }
+
+// CapacityAlertRepository defines the repository interface for capacity alerts
+type CapacityAlertRepository interface {
+	// MarkNotified records the alert and reports whether it had not been recorded before
+	MarkNotified(ctx context.Context, alert *CapacityAlert) (bool, error)
+	// FindBusinessesWithAppointments lists the businesses with appointments between two local dates
+	FindBusinessesWithAppointments(ctx context.Context, startDate, endDate time.Time) ([]string, error)
+}
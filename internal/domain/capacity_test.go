@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func capacityEntry(staffID string, start, end string, status AppointmentStatus) *CalendarEntry {
+	day := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+	parse := func(clock string) time.Time {
+		t, _ := time.Parse("15:04", clock)
+		return day.Add(time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute)
+	}
+	return &CalendarEntry{
+		StaffID:      staffID,
+		StaffName:    "Staff " + staffID,
+		CalendarDate: day,
+		StartTime:    parse(start),
+		EndTime:      parse(end),
+		Status:       status,
+	}
+}
+
+func TestCapacityPolicyFor(t *testing.T) {
+	policy := CapacityPolicyFor(nil)
+	assert.Equal(t, DefaultCapacityWarningPercent, policy.DayThresholdPercent)
+	assert.Equal(t, DefaultMaxContinuousWorkMinutes, policy.MaxContinuousMinutes)
+	assert.Equal(t, DefaultMinBreakMinutes, policy.MinBreakMinutes)
+
+	policy = CapacityPolicyFor(&BusinessSettings{CapacityWarningPercent: 75, MaxContinuousWorkMinutes: 0, MinBreakMinutes: 30})
+	assert.Equal(t, 75, policy.DayThresholdPercent)
+	assert.Zero(t, policy.MaxContinuousMinutes)
+	assert.Equal(t, 30, policy.MinBreakMinutes)
+}
+
+func TestEvaluateDayBooked(t *testing.T) {
+	policy := CapacityPolicy{DayThresholdPercent: 80, MinBreakMinutes: 15}
+	date := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+	entries := []*CalendarEntry{
+		capacityEntry("a", "09:00", "12:00", AppointmentStatusScheduled),
+		capacityEntry("a", "13:00", "17:00", AppointmentStatusConfirmed),
+		capacityEntry("a", "17:00", "18:00", AppointmentStatusCancelled),
+	}
+
+	// 7 of 8 hours booked; the cancelled appointment does not count
+	warnings := policy.EvaluateDay(date, entries, 8*60)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, CapacityWarningDayBooked, warnings[0].Kind)
+	assert.Equal(t, 420, warnings[0].BookedMinutes)
+	assert.Equal(t, 87, warnings[0].BookedPercent)
+
+	assert.Empty(t, policy.EvaluateDay(date, entries, 10*60))
+
+	policy.DayThresholdPercent = 0
+	assert.Empty(t, policy.EvaluateDay(date, entries, 8*60))
+}
+
+func TestEvaluateDayNoBreak(t *testing.T) {
+	policy := CapacityPolicy{MaxContinuousMinutes: 240, MinBreakMinutes: 15}
+	date := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+	entries := []*CalendarEntry{
+		capacityEntry("a", "11:00", "12:00", AppointmentStatusScheduled),
+		capacityEntry("a", "09:00", "10:00", AppointmentStatusScheduled),
+		capacityEntry("a", "10:05", "11:00", AppointmentStatusScheduled), // 5-minute gap is not a break
+		capacityEntry("a", "12:10", "14:00", AppointmentStatusScheduled),
+		capacityEntry("a", "15:00", "16:00", AppointmentStatusScheduled), // After a real break
+		capacityEntry("b", "09:00", "12:00", AppointmentStatusScheduled),
+	}
+
+	warnings := policy.EvaluateDay(date, entries, 0)
+	require.Len(t, warnings, 1)
+
+	warning := warnings[0]
+	assert.Equal(t, CapacityWarningNoBreak, warning.Kind)
+	assert.Equal(t, "a", *warning.StaffID)
+	assert.Equal(t, 300, warning.BookedMinutes)
+	assert.Equal(t, "09:00", warning.Start.Format("15:04"))
+	assert.Equal(t, "14:00", warning.End.Format("15:04"))
+
+	// The break goes at the latest boundary within four hours of 09:00
+	require.NotNil(t, warning.SuggestedBreak)
+	assert.Equal(t, "12:00", warning.SuggestedBreak.Start.Format("15:04"))
+	assert.Equal(t, 15, warning.SuggestedBreak.Minutes)
+}
+
+func TestSuggestBreakAfterLongFirstAppointment(t *testing.T) {
+	policy := CapacityPolicy{MaxContinuousMinutes: 120, MinBreakMinutes: 15}
+	date := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+	entries := []*CalendarEntry{
+		capacityEntry("a", "09:00", "12:00", AppointmentStatusScheduled),
+		capacityEntry("a", "12:00", "13:00", AppointmentStatusScheduled),
+	}
+
+	warnings := policy.EvaluateDay(date, entries, 0)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, "12:00", warnings[0].SuggestedBreak.Start.Format("15:04"))
+}
@@ -0,0 +1,175 @@
+package domain
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"gorm.io/gorm/schema"
+)
+
+// Changer is a field of a partial update that may or may not touch its column, such as
+// dto.Optional. Set reports whether it does; a nil value clears the column.
+type Changer interface {
+	Change() (value any, set bool)
+}
+
+// ChangeSet is a partial update of an entity: the columns it touches and the values it writes
+// to them. Unlike an update of the whole entity, it writes zero values, so a field can be
+// cleared or set to false on purpose, and leaves the columns it does not touch alone.
+type ChangeSet struct {
+	values map[string]any
+	// Version is the version of the entity the changes were made to. The update fails with a
+	// ConflictError when the entity was changed since; zero writes whatever the version.
+	Version int
+}
+
+// NewChangeSet builds the changes of a partial update DTO. Non-nil pointers, maps and slices
+// touch their column with the value they hold, as do Changer fields that are set. Columns are
+// named by the `column` tag, or else by the json tag; fields tagged `column:"-"` and fields of
+// other kinds are left out.
+func NewChangeSet(update any) (ChangeSet, error) {
+	changes := ChangeSet{values: map[string]any{}}
+	v := reflect.Indirect(reflect.ValueOf(update))
+	if v.Kind() != reflect.Struct {
+		return changes, fmt.Errorf("%w: changes must be read from a struct, not %s", ErrValidation, v.Kind())
+	}
+	changes.collect(v)
+	return changes, nil
+}
+
+// collect adds the touched fields of a struct, descending into embedded structs
+func (c *ChangeSet) collect(v reflect.Value) {
+	for i := range v.NumField() {
+		field, value := v.Type().Field(i), v.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			c.collect(value)
+			continue
+		}
+		column := changeColumn(field)
+		if column == "" {
+			continue
+		}
+
+		if changer, ok := value.Interface().(Changer); ok {
+			if change, set := changer.Change(); set {
+				c.Set(column, change)
+			}
+			continue
+		}
+		switch value.Kind() {
+		case reflect.Pointer:
+			if !value.IsNil() {
+				c.Set(column, value.Elem().Interface())
+			}
+		case reflect.Map, reflect.Slice:
+			if !value.IsNil() {
+				c.Set(column, value.Interface())
+			}
+		}
+	}
+}
+
+// changeColumn returns the column a field of a partial update touches, or "" for none
+func changeColumn(field reflect.StructField) string {
+	if column, ok := field.Tag.Lookup("column"); ok {
+		if column == "-" {
+			return ""
+		}
+		return column
+	}
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return schema.NamingStrategy{}.ColumnName("", field.Name)
+	}
+	return name
+}
+
+// Set makes the changes write a value to a column, nil to clear it
+func (c *ChangeSet) Set(column string, value any) *ChangeSet {
+	if c.values == nil {
+		c.values = map[string]any{}
+	}
+	c.values[column] = value
+	return c
+}
+
+// Has reports whether the changes touch a column
+func (c ChangeSet) Has(column string) bool {
+	_, ok := c.values[column]
+	return ok
+}
+
+// IsEmpty reports whether the changes touch no column
+func (c ChangeSet) IsEmpty() bool {
+	return len(c.values) == 0
+}
+
+// Columns returns the columns the changes touch, in alphabetical order
+func (c ChangeSet) Columns() []string {
+	columns := make([]string, 0, len(c.values))
+	for column := range c.values {
+		columns = append(columns, column)
+	}
+	slices.Sort(columns)
+	return columns
+}
+
+// Values returns the values the changes write, by column
+func (c ChangeSet) Values() map[string]any {
+	values := make(map[string]any, len(c.values))
+	for column, value := range c.values {
+		values[column] = value
+	}
+	return values
+}
+
+// Capture replaces the values of the touched columns with those of an entity the update was
+// applied to, so that what is written is what the entity validated rather than the raw input.
+// The changes take the entity's version too.
+func (c *ChangeSet) Capture(entity any) error {
+	v := reflect.Indirect(reflect.ValueOf(entity))
+	if v.Kind() != reflect.Struct {
+		return fmt.Errorf("%w: changes must be captured from a struct, not %s", ErrValidation, v.Kind())
+	}
+	fields := entityColumns(v.Type(), nil, map[string][]int{})
+	for column := range c.values {
+		index, ok := fields[column]
+		if !ok {
+			return fmt.Errorf("%w: %s has no column %q", ErrValidation, v.Type().Name(), column)
+		}
+		c.values[column] = v.FieldByIndex(index).Interface()
+	}
+	if version := v.FieldByName("Version"); version.IsValid() && version.Kind() == reflect.Int {
+		c.Version = int(version.Int())
+	}
+	return nil
+}
+
+// entityColumns maps the columns of an entity type to its fields, the way GORM names them
+func entityColumns(typ reflect.Type, parent []int, columns map[string][]int) map[string][]int {
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		index := append(slices.Clone(parent), i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			entityColumns(field.Type, index, columns)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		column := schema.ParseTagSetting(field.Tag.Get("gorm"), ";")["COLUMN"]
+		if column == "" {
+			column = schema.NamingStrategy{}.ColumnName("", field.Name)
+		}
+		columns[column] = index
+	}
+	return columns
+}
@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// optionalNotes stands in for dto.Optional
+type optionalNotes struct {
+	set   bool
+	value *string
+}
+
+func (o optionalNotes) Change() (any, bool) {
+	if o.value == nil {
+		return nil, o.set
+	}
+	return *o.value, o.set
+}
+
+func TestNewChangeSet(t *testing.T) {
+	name, active := "", false
+	update := struct {
+		FirstName   *string        `json:"first_name,omitempty"`
+		LastName    *string        `json:"last_name,omitempty"`
+		IsActive    *bool          `json:"is_active,omitempty"`
+		Notes       optionalNotes  `json:"notes"`
+		Phone       optionalNotes  `json:"phone"`
+		FieldValues map[string]any `json:"field_values,omitempty"`
+		Expected    *int           `json:"expected_version" column:"-"`
+		DryRun      bool           `json:"dry_run"`
+	}{FirstName: &name, IsActive: &active, Notes: optionalNotes{set: true}, Expected: new(int)}
+
+	changes, err := NewChangeSet(update)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first_name", "is_active", "notes"}, changes.Columns())
+	assert.Equal(t, map[string]any{"first_name": "", "is_active": false, "notes": nil}, changes.Values(),
+		"zero values and clearing are kept as changes")
+
+	_, err = NewChangeSet("not a struct")
+	assert.ErrorIs(t, err, ErrValidation)
+}
+
+func TestChangeSetCapture(t *testing.T) {
+	notes := "  Prefers mornings  "
+	changes, err := NewChangeSet(struct {
+		Notes *string `json:"notes"`
+	}{Notes: &notes})
+	require.NoError(t, err)
+
+	client := &Client{Notes: nil}
+	client.Version = 4
+	require.NoError(t, changes.Capture(client))
+	assert.Equal(t, map[string]any{"notes": (*string)(nil)}, changes.Values(), "the entity's values are written")
+	assert.Equal(t, 4, changes.Version)
+
+	changes.Set("no_such_column", 1)
+	assert.ErrorIs(t, changes.Capture(client), ErrValidation)
+}
@@ -2,6 +2,8 @@ package domain
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"time"
 	"github.com/shopspring/decimal"
 )
@@ -25,6 +27,15 @@ type Client struct {
 	LastVisit    *time.Time `gorm:"" json:"last_visit,omitempty"`
 	TotalVisits  int        `gorm:"not null;default:0" json:"total_visits"`
 	TotalSpent   decimal.Decimal `gorm:"type:decimal(10,2);not null;default:0" json:"total_spent"`
+	GuardianClientID     *string    `gorm:"type:uuid;index" json:"guardian_client_id,omitempty"` // Guardian of a minor client
+	GuardianRelationship *string    `gorm:"size:50" json:"guardian_relationship,omitempty"`
+	GuardianConsentAt    *time.Time `gorm:"" json:"guardian_consent_at,omitempty"`
+	AnonymizedAt         *time.Time `gorm:"" json:"anonymized_at,omitempty"` // Set once the client's personal data has been removed
+	AcceptsMarketing     bool       `gorm:"not null;default:false" json:"accepts_marketing"`
+	Tags                 *string    `gorm:"type:jsonb;default:'[]'" json:"tags,omitempty"` // JSON list of lower-case tags
+	NoShowCount          int        `gorm:"not null;default:0" json:"no_show_count"`        // No-shows and late cancellations
+	DepositRequired      bool       `gorm:"not null;default:false" json:"deposit_required"` // Flagged for no-shows; bookings need a deposit
+	MergedIntoID         *string    `gorm:"type:uuid" json:"merged_into_id,omitempty"`      // Set on a duplicate deleted by merging it into another client
 
 	// Relationships
 	Business     Business     `gorm:"foreignKey:BusinessID;constraint:OnDelete:CASCADE" json:"business"`
@@ -57,6 +68,18 @@ func (c *Client) GetFullName() string {
 	return c.FirstName + " " + c.LastName
 }
 
+// GetPreferences decodes the client's preferences
+func (c *Client) GetPreferences() (map[string]any, error) {
+	preferences := map[string]any{}
+	if c.Preferences == nil || *c.Preferences == "" {
+		return preferences, nil
+	}
+	if err := json.Unmarshal([]byte(*c.Preferences), &preferences); err != nil {
+		return nil, fmt.Errorf("%w: invalid client preferences", ErrValidation)
+	}
+	return preferences, nil
+}
+
 // UpdateVisitStats updates the last visit and total visits
 func (c *Client) UpdateVisitStats(visitTime time.Time, amount decimal.Decimal) {
 	c.LastVisit = &visitTime
@@ -0,0 +1,228 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ClientAttachmentKind represents what a file attached to a client is
+type ClientAttachmentKind string
+
+const (
+	ClientAttachmentBeforePhoto ClientAttachmentKind = "before_photo" // Taken before a treatment
+	ClientAttachmentAfterPhoto  ClientAttachmentKind = "after_photo"  // Taken after a treatment
+	ClientAttachmentPhoto       ClientAttachmentKind = "photo"        // Any other photo of the client
+	ClientAttachmentDocument    ClientAttachmentKind = "document"     // A signed form, a prescription or the like
+)
+
+// IsValid reports whether the kind is known
+func (k ClientAttachmentKind) IsValid() bool {
+	switch k {
+	case ClientAttachmentBeforePhoto, ClientAttachmentAfterPhoto, ClientAttachmentPhoto, ClientAttachmentDocument:
+		return true
+	}
+	return false
+}
+
+// IsPhoto reports whether attachments of the kind are photos, which alone may be used in marketing
+func (k ClientAttachmentKind) IsPhoto() bool {
+	return k != ClientAttachmentDocument
+}
+
+// Accepts reports whether files of a media type can be attached as the kind: photos are images,
+// and documents may also be PDFs
+func (k ClientAttachmentKind) Accepts(contentType string) bool {
+	switch contentType {
+	case "image/jpeg", "image/png", "image/gif":
+		return true
+	case "application/pdf":
+		return k == ClientAttachmentDocument
+	}
+	return false
+}
+
+// ClientAttachmentStatus represents the state of a client attachment
+type ClientAttachmentStatus string
+
+const (
+	ClientAttachmentPending  ClientAttachmentStatus = "pending"  // The upload URL was handed out; waiting for the file
+	ClientAttachmentStored   ClientAttachmentStatus = "stored"   // Checked and kept
+	ClientAttachmentRejected ClientAttachmentStatus = "rejected" // The file was not what it was declared as; see the error
+	ClientAttachmentDeleted  ClientAttachmentStatus = "deleted"  // Deleted, or its client anonymized; its files are yet to be removed
+	ClientAttachmentRemoved  ClientAttachmentStatus = "removed"  // Its files were removed from storage
+)
+
+const (
+	// MaxClientAttachmentBytes bounds the size of a file attached to a client
+	MaxClientAttachmentBytes = 20 << 20
+	// ClientPhotoMaxDimension is the most pixels attached images are kept at on either side
+	ClientPhotoMaxDimension = 2048
+	// DefaultAttachmentQuotaMB is the storage a business may use for client attachments unless
+	// given another quota
+	DefaultAttachmentQuotaMB = 1024
+	// ClientAttachmentPrefix is the prefix of the storage keys of kept attachments, which are only
+	// served through signed URLs
+	ClientAttachmentPrefix = "client-files/"
+)
+
+// ClientAttachment is a photo or document attached to a client, and optionally to one of their
+// appointments. Like images, files are uploaded straight to object storage through a presigned
+// URL and checked when the upload is completed; images are stripped of their metadata. They
+// count against the business's storage quota until deleted. Photos may only be used in marketing
+// once the client's consent to it is recorded.
+type ClientAttachment struct {
+	BaseModel
+	BusinessID         string                 `gorm:"not null;type:uuid;index" json:"business_id"`
+	ClientID           string                 `gorm:"not null;type:uuid;index" json:"client_id"`
+	AppointmentID      *string                `gorm:"type:uuid;index" json:"appointment_id,omitempty"`
+	Kind               ClientAttachmentKind   `gorm:"not null;size:20" json:"kind"`
+	FileName           string                 `gorm:"not null;size:255" json:"file_name"`
+	Caption            *string                `gorm:"size:500" json:"caption,omitempty"`
+	ContentType        string                 `gorm:"not null;size:50" json:"content_type"`
+	SizeBytes          int64                  `gorm:"not null" json:"size_bytes"` // As declared until stored, then as kept
+	Status             ClientAttachmentStatus `gorm:"not null;size:20;default:'pending'" json:"status"`
+	Bucket             string                 `gorm:"not null;size:255" json:"bucket"`
+	UploadKey          string                 `gorm:"not null;size:500" json:"upload_key"`
+	UploadExpiresAt    time.Time              `gorm:"not null" json:"upload_expires_at"` // When the upload URL stops working
+	ObjectKey          *string                `gorm:"size:500" json:"object_key,omitempty"`
+	Width              *int                   `gorm:"" json:"width,omitempty"`
+	Height             *int                   `gorm:"" json:"height,omitempty"`
+	Error              *string                `gorm:"type:text" json:"error,omitempty"`
+	StoredAt           *time.Time             `gorm:"" json:"stored_at,omitempty"`
+	MarketingConsentAt *time.Time             `gorm:"" json:"marketing_consent_at,omitempty"`          // When the client agreed to the photo's use in marketing
+	MarketingConsentBy *string                `gorm:"type:uuid" json:"marketing_consent_by,omitempty"` // The user who recorded the consent
+	RemovedAt          *time.Time             `gorm:"" json:"removed_at,omitempty"`
+}
+
+// TableName returns the table name for ClientAttachment
+func (ClientAttachment) TableName() string { return "client_attachments" }
+
+// Validate validates the client attachment model
+func (a *ClientAttachment) Validate() error {
+	if a.BusinessID == "" {
+		return fmt.Errorf("%w: business_id is required", ErrValidation)
+	}
+	if a.ClientID == "" {
+		return fmt.Errorf("%w: client_id is required", ErrValidation)
+	}
+	if !a.Kind.IsValid() {
+		return fmt.Errorf("%w: invalid kind %q", ErrValidation, a.Kind)
+	}
+	if strings.TrimSpace(a.FileName) == "" {
+		return fmt.Errorf("%w: file_name is required", ErrValidation)
+	}
+	if !a.Kind.Accepts(a.ContentType) {
+		return fmt.Errorf("%w: %s files cannot be attached as a %s", ErrValidation, a.ContentType, strings.ReplaceAll(string(a.Kind), "_", " "))
+	}
+	if a.SizeBytes <= 0 || a.SizeBytes > MaxClientAttachmentBytes {
+		return fmt.Errorf("%w: attachments must be at most %d MB", ErrValidation, MaxClientAttachmentBytes>>20)
+	}
+	return nil
+}
+
+// UploadKeyFor returns the storage key the file is uploaded to
+func (a *ClientAttachment) UploadKeyFor() string {
+	return ImageUploadPrefix + a.BusinessID + "/" + a.ID
+}
+
+// ObjectKeyFor returns the storage key the checked file is kept at, with the extension of its
+// format
+func (a *ClientAttachment) ObjectKeyFor(extension string) string {
+	return ClientAttachmentPrefix + a.BusinessID + "/" + a.ClientID + "/" + a.ID + "." + extension
+}
+
+// CanComplete reports whether the upload can still be completed at a time
+func (a *ClientAttachment) CanComplete(now time.Time) bool {
+	return a.Status == ClientAttachmentPending && now.Before(a.UploadExpiresAt.Add(ImageUploadGracePeriod))
+}
+
+// Store records the checked file as kept, with the type and size it is kept at
+func (a *ClientAttachment) Store(key, contentType string, size int64, width, height *int, now time.Time) {
+	a.Status = ClientAttachmentStored
+	a.ObjectKey = &key
+	a.ContentType = contentType
+	a.SizeBytes = size
+	a.Width = width
+	a.Height = height
+	a.Error = nil
+	a.StoredAt = &now
+}
+
+// Reject records why the uploaded file was refused
+func (a *ClientAttachment) Reject(message string) {
+	a.Status = ClientAttachmentRejected
+	a.Error = &message
+}
+
+// Delete records the attachment as deleted, leaving its files to the cleanup
+func (a *ClientAttachment) Delete() error {
+	if a.Status == ClientAttachmentDeleted || a.Status == ClientAttachmentRemoved {
+		return fmt.Errorf("%w: the attachment has already been deleted", ErrValidation)
+	}
+	a.Status = ClientAttachmentDeleted
+	a.MarketingConsentAt = nil
+	a.MarketingConsentBy = nil
+	return nil
+}
+
+// MarkRemoved records the attachment's files as removed from storage
+func (a *ClientAttachment) MarkRemoved(now time.Time) {
+	a.Status = ClientAttachmentRemoved
+	a.RemovedAt = &now
+}
+
+// SetMarketingConsent records the client agreeing to the photo's use in marketing, or withdrawing
+// their agreement
+func (a *ClientAttachment) SetMarketingConsent(consented bool, by *string, now time.Time) error {
+	if !a.Kind.IsPhoto() {
+		return fmt.Errorf("%w: only photos can be used in marketing", ErrValidation)
+	}
+	if a.Status != ClientAttachmentStored {
+		return fmt.Errorf("%w: only stored photos can be used in marketing", ErrValidation)
+	}
+	if !consented {
+		a.MarketingConsentAt = nil
+		a.MarketingConsentBy = nil
+		return nil
+	}
+	if a.MarketingConsentAt == nil {
+		a.MarketingConsentAt = &now
+		a.MarketingConsentBy = by
+	}
+	return nil
+}
+
+// UsableInMarketing reports whether the attachment is a kept photo the client agreed to the use
+// of in marketing
+func (a *ClientAttachment) UsableInMarketing() bool {
+	return a.Kind.IsPhoto() && a.Status == ClientAttachmentStored && a.MarketingConsentAt != nil
+}
+
+// AttachmentQuotaBytes returns the storage the business may use for client attachments
+func (b Business) AttachmentQuotaBytes() int64 {
+	quota := DefaultAttachmentQuotaMB
+	if b.AttachmentQuotaMB != nil {
+		quota = *b.AttachmentQuotaMB
+	}
+	return int64(quota) << 20
+}
+
+// ClientAttachmentRepository defines the interface for client attachment repository operations
+type ClientAttachmentRepository interface {
+	BaseRepository[ClientAttachment]
+	// FindByClient finds the stored attachments of a client, newest first, only those of an
+	// appointment when one is given
+	FindByClient(ctx context.Context, clientID string, appointmentID *string) ([]*ClientAttachment, error)
+	// FindUsableInMarketing finds the stored photos of a business whose clients agreed to their
+	// use in marketing, newest first
+	FindUsableInMarketing(ctx context.Context, businessID string) ([]*ClientAttachment, error)
+	// SumUsage totals the bytes of a business's attachments counting against its quota: those
+	// stored, and those pending as declared
+	SumUsage(ctx context.Context, businessID string) (int64, error)
+	// FindRemovable finds, across businesses and oldest first, the attachments whose files can be
+	// removed: pending ones whose upload URL expired before the cutoff, rejected ones and deleted
+	// ones
+	FindRemovable(ctx context.Context, abandonedBefore time.Time, limit int) ([]*ClientAttachment, error)
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientAttachmentValidate(t *testing.T) {
+	attachment := func(kind ClientAttachmentKind, contentType string, size int64) *ClientAttachment {
+		return &ClientAttachment{BusinessID: "business-1", ClientID: "client-1", Kind: kind, FileName: "scan.pdf",
+			ContentType: contentType, SizeBytes: size}
+	}
+	assert.NoError(t, attachment(ClientAttachmentDocument, "application/pdf", 1024).Validate())
+	assert.NoError(t, attachment(ClientAttachmentDocument, "image/jpeg", 1024).Validate())
+	assert.NoError(t, attachment(ClientAttachmentBeforePhoto, "image/png", MaxClientAttachmentBytes).Validate())
+	assert.ErrorIs(t, attachment(ClientAttachmentAfterPhoto, "application/pdf", 1024).Validate(), ErrValidation, "photos are images")
+	assert.ErrorIs(t, attachment(ClientAttachmentPhoto, "image/heic", 1024).Validate(), ErrValidation)
+	assert.ErrorIs(t, attachment(ClientAttachmentPhoto, "image/png", MaxClientAttachmentBytes+1).Validate(), ErrValidation)
+	assert.ErrorIs(t, attachment("selfie", "image/png", 1024).Validate(), ErrValidation)
+}
+
+func TestClientAttachmentMarketingConsent(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	user := "user-1"
+	photo := &ClientAttachment{BaseModel: BaseModel{ID: "attachment-1"}, BusinessID: "business-1", ClientID: "client-1",
+		Kind: ClientAttachmentAfterPhoto, Status: ClientAttachmentPending, UploadExpiresAt: now}
+	assert.ErrorIs(t, photo.SetMarketingConsent(true, &user, now), ErrValidation, "photos must be stored first")
+
+	width, height := 800, 600
+	photo.Store(photo.ObjectKeyFor("jpg"), "image/jpeg", 2048, &width, &height, now)
+	assert.Equal(t, "client-files/business-1/client-1/attachment-1.jpg", *photo.ObjectKey)
+	assert.False(t, photo.UsableInMarketing())
+
+	require.NoError(t, photo.SetMarketingConsent(true, &user, now))
+	assert.True(t, photo.UsableInMarketing())
+	require.NoError(t, photo.SetMarketingConsent(true, nil, now.Add(time.Hour)))
+	assert.Equal(t, now, *photo.MarketingConsentAt, "repeating the consent keeps when it was first given")
+	assert.Equal(t, &user, photo.MarketingConsentBy)
+
+	require.NoError(t, photo.SetMarketingConsent(false, &user, now))
+	assert.False(t, photo.UsableInMarketing())
+	assert.Nil(t, photo.MarketingConsentBy)
+
+	require.NoError(t, photo.SetMarketingConsent(true, &user, now))
+	require.NoError(t, photo.Delete())
+	assert.False(t, photo.UsableInMarketing(), "deleting a photo takes it out of marketing")
+	assert.ErrorIs(t, photo.Delete(), ErrValidation)
+
+	document := &ClientAttachment{Kind: ClientAttachmentDocument, Status: ClientAttachmentStored}
+	assert.ErrorIs(t, document.SetMarketingConsent(true, &user, now), ErrValidation)
+}
+
+func TestBusinessAttachmentQuotaBytes(t *testing.T) {
+	assert.Equal(t, int64(DefaultAttachmentQuotaMB)<<20, Business{}.AttachmentQuotaBytes())
+	quota := 0
+	assert.Zero(t, Business{AttachmentQuotaMB: &quota}.AttachmentQuotaBytes())
+}
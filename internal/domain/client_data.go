@@ -0,0 +1,154 @@
+package domain
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// The names an anonymized client is left with
+const (
+	AnonymizedFirstName = "Anonymized"
+	AnonymizedLastName  = "Client"
+)
+
+// ClientDataExportFormat is the file format of a client data export
+type ClientDataExportFormat string
+
+const (
+	ClientDataExportJSON ClientDataExportFormat = "json" // A single JSON document
+	ClientDataExportZIP  ClientDataExportFormat = "zip"  // A ZIP archive with a JSON file per kind of data
+)
+
+// IsValid reports whether the format is supported
+func (f ClientDataExportFormat) IsValid() bool {
+	return f == ClientDataExportJSON || f == ClientDataExportZIP
+}
+
+// ClientProfileExport is the personal data a business holds about a client
+type ClientProfileExport struct {
+	ID               string     `json:"id"`
+	BusinessID       string     `json:"business_id"`
+	FirstName        string     `json:"first_name"`
+	LastName         string     `json:"last_name"`
+	Email            *string    `json:"email"`
+	Phone            *string    `json:"phone"`
+	DateOfBirth      *time.Time `json:"date_of_birth"`
+	AddressLine1     *string    `json:"address_line1"`
+	City             *string    `json:"city"`
+	PostalCode       *string    `json:"postal_code"`
+	Country          *string    `json:"country"`
+	Notes            *string    `json:"notes"`
+	Allergies        *string    `json:"allergies"`
+	HealthConditions *string    `json:"health_conditions"`
+	ReferralSource   *string    `json:"referral_source"`
+	AcceptsMarketing bool       `json:"accepts_marketing"`
+	IsActive         bool       `json:"is_active"`
+	CreatedAt        time.Time  `json:"created_at"`
+	AnonymizedAt     *time.Time `json:"anonymized_at,omitempty"`
+}
+
+// ClientAppointmentExport is an appointment of a client
+type ClientAppointmentExport struct {
+	ID                 string           `json:"id"`
+	StartTime          time.Time        `json:"start_time"`
+	EndTime            time.Time        `json:"end_time"`
+	Status             string           `json:"status"`
+	Services           string           `json:"services"` // Names of the services, comma-separated
+	Notes              *string          `json:"notes"`
+	EstimatedPrice     *decimal.Decimal `json:"estimated_price"`
+	ActualPrice        *decimal.Decimal `json:"actual_price"`
+	PaymentStatus      *string          `json:"payment_status"`
+	CancellationReason *string          `json:"cancellation_reason"`
+}
+
+// ClientCompletionExport is a completed service of a client
+type ClientCompletionExport struct {
+	AppointmentID  string          `json:"appointment_id"`
+	PriceCharged   decimal.Decimal `json:"price_charged"`
+	PaymentMethod  string          `json:"payment_method"`
+	CompletionDate *time.Time      `json:"completion_date"`
+	ActualDuration *int            `json:"actual_duration"` // in minutes
+}
+
+// ClientLoyaltyMembershipExport is a client's membership of a loyalty program
+type ClientLoyaltyMembershipExport struct {
+	ProgramName   string          `json:"program_name"`
+	CurrentPoints int             `json:"current_points"`
+	VisitsCount   int             `json:"visits_count"`
+	TotalSpent    decimal.Decimal `json:"total_spent"`
+	TierLevel     *string         `json:"tier_level"`
+	JoinDate      time.Time       `json:"join_date"`
+	ExpiryDate    *time.Time      `json:"expiry_date"`
+	IsActive      bool            `json:"is_active"`
+}
+
+// ClientCampaignMessageExport is a marketing message sent to a client
+type ClientCampaignMessageExport struct {
+	CampaignName   string     `json:"campaign_name"`
+	MessageType    string     `json:"message_type"`
+	MessageContent string     `json:"message_content"`
+	ScheduledTime  time.Time  `json:"scheduled_time"`
+	SentTime       *time.Time `json:"sent_time"`
+	Status         string     `json:"status"`
+}
+
+// ClientDataExport is everything a business holds about a client, as handed over on a data
+// subject access request
+type ClientDataExport struct {
+	ExportedAt         time.Time                       `json:"exported_at"`
+	Profile            ClientProfileExport             `json:"profile"`
+	Appointments       []ClientAppointmentExport       `json:"appointments"`
+	Completions        []ClientCompletionExport        `json:"completions"`
+	LoyaltyMemberships []ClientLoyaltyMembershipExport `json:"loyalty_memberships"`
+	CampaignMessages   []ClientCampaignMessageExport   `json:"campaign_messages"`
+}
+
+// WriteJSON writes the export as a single indented JSON document
+func (e *ClientDataExport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(e)
+}
+
+// WriteZip writes the export as a ZIP archive with a JSON file per kind of data
+func (e *ClientDataExport) WriteZip(w io.Writer) error {
+	archive := zip.NewWriter(w)
+	files := []struct {
+		name string
+		data any
+	}{
+		{"profile.json", e.Profile},
+		{"appointments.json", e.Appointments},
+		{"completions.json", e.Completions},
+		{"loyalty_memberships.json", e.LoyaltyMemberships},
+		{"campaign_messages.json", e.CampaignMessages},
+	}
+	for _, file := range files {
+		writer, err := archive.CreateHeader(&zip.FileHeader{Name: file.name, Method: zip.Deflate, Modified: e.ExportedAt})
+		if err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(file.data); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+// ClientDataRepository defines the interface for exporting and anonymizing the personal data
+// held about a client
+type ClientDataRepository interface {
+	// FindExport collects everything held about a client
+	FindExport(ctx context.Context, clientID string) (*ClientDataExport, error)
+	// Anonymize removes the personal data of a client in a single transaction, keeping their
+	// appointments, completions and loyalty balances for the business's statistics. Their
+	// attachments are deleted, leaving the files to the attachment cleanup.
+	Anonymize(ctx context.Context, clientID string, at time.Time, by *string) error
+}
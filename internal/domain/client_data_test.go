@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClientDataExport() *ClientDataExport {
+	email := "ana@example.com"
+	return &ClientDataExport{
+		ExportedAt: time.Date(2026, time.October, 17, 9, 0, 0, 0, time.UTC),
+		Profile:    ClientProfileExport{ID: "client-1", FirstName: "Ana", LastName: "Silva", Email: &email},
+		Appointments: []ClientAppointmentExport{
+			{ID: "appointment-1", Status: "completed", Services: "Manicure, Pedicure"},
+		},
+		Completions: []ClientCompletionExport{
+			{AppointmentID: "appointment-1", PriceCharged: decimal.RequireFromString("45.00"), PaymentMethod: "card"},
+		},
+		LoyaltyMemberships: []ClientLoyaltyMembershipExport{},
+		CampaignMessages:   []ClientCampaignMessageExport{},
+	}
+}
+
+func TestClientDataExport_WriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, newTestClientDataExport().WriteJSON(&buf))
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, "ana@example.com", decoded["profile"].(map[string]any)["email"])
+	assert.Len(t, decoded["appointments"], 1)
+	assert.Equal(t, []any{}, decoded["campaign_messages"], "empty sections are exported as empty lists")
+}
+
+func TestClientDataExport_WriteZip(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, newTestClientDataExport().WriteZip(&buf))
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	names := make([]string, len(archive.File))
+	for i, file := range archive.File {
+		names[i] = file.Name
+	}
+	assert.Equal(t, []string{
+		"profile.json", "appointments.json", "completions.json", "loyalty_memberships.json", "campaign_messages.json",
+	}, names)
+
+	file, err := archive.File[2].Open()
+	require.NoError(t, err)
+	defer file.Close()
+	content, err := io.ReadAll(file)
+	require.NoError(t, err)
+	var completions []ClientCompletionExport
+	require.NoError(t, json.Unmarshal(content, &completions))
+	require.Len(t, completions, 1)
+	assert.True(t, decimal.RequireFromString("45").Equal(completions[0].PriceCharged))
+}
+
+func TestClientDataExportFormat_IsValid(t *testing.T) {
+	assert.True(t, ClientDataExportJSON.IsValid())
+	assert.True(t, ClientDataExportZIP.IsValid())
+	assert.False(t, ClientDataExportFormat("csv").IsValid())
+}
@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// AdultAge is the age from which a client can book and be contacted without a guardian
+const AdultAge = 18
+
+// AgeOn returns the age in whole years of someone born on dateOfBirth, on the given day
+func AgeOn(dateOfBirth, on time.Time) int {
+	on = on.In(dateOfBirth.Location())
+	age := on.Year() - dateOfBirth.Year()
+	if on.Month() < dateOfBirth.Month() || (on.Month() == dateOfBirth.Month() && on.Day() < dateOfBirth.Day()) {
+		age--
+	}
+	return age
+}
+
+// AgeAt returns the client's age at a time, and false when the date of birth is unknown
+func (c *Client) AgeAt(at time.Time) (int, bool) {
+	if c.DateOfBirth == nil {
+		return 0, false
+	}
+	return AgeOn(*c.DateOfBirth, at), true
+}
+
+// IsMinorAt reports whether the client is known to be under AdultAge at a time
+func (c *Client) IsMinorAt(at time.Time) bool {
+	age, known := c.AgeAt(at)
+	return known && age < AdultAge
+}
+
+// HasGuardian reports whether the client is linked to a guardian
+func (c *Client) HasGuardian() bool {
+	return c.GuardianClientID != nil && *c.GuardianClientID != ""
+}
+
+// ValidateGuardian checks that guardian can act as the guardian of the client at a time
+func (c *Client) ValidateGuardian(guardian *Client, at time.Time) error {
+	if guardian.ID == c.ID {
+		return fmt.Errorf("%w: a client cannot be their own guardian", ErrValidation)
+	}
+	if guardian.BusinessID != c.BusinessID {
+		return fmt.Errorf("%w: the guardian must be a client of the same business", ErrValidation)
+	}
+	if guardian.HasGuardian() {
+		return fmt.Errorf("%w: the guardian has a guardian of their own", ErrValidation)
+	}
+	if guardian.IsMinorAt(at) {
+		return fmt.Errorf("%w: the guardian must be at least %d years old", ErrValidation, AdultAge)
+	}
+	if !c.IsMinorAt(at) {
+		return fmt.Errorf("%w: only clients under %d with a known date of birth can have a guardian", ErrValidation, AdultAge)
+	}
+	return nil
+}
+
+// CheckBookingAge checks that the client may be booked for the services at a time: a minor
+// needs a guardian who has given consent, and age-restricted services need a client old enough
+// on the day of the appointment
+func (c *Client) CheckBookingAge(services []*Service, at time.Time) error {
+	if c.IsMinorAt(at) {
+		if !c.HasGuardian() {
+			return fmt.Errorf("%w: clients under %d must be linked to a guardian before booking", ErrValidation, AdultAge)
+		}
+		if c.GuardianConsentAt == nil {
+			return fmt.Errorf("%w: the guardian has not given consent for this client", ErrValidation)
+		}
+	}
+
+	for _, service := range services {
+		if service.MinAge == nil {
+			continue
+		}
+		age, known := c.AgeAt(at)
+		if !known {
+			return fmt.Errorf("%w: %s is age-restricted and the client has no date of birth", ErrValidation, service.Name)
+		}
+		if age < *service.MinAge {
+			return fmt.Errorf("%w: %s is only available to clients aged %d or older", ErrValidation, service.Name, *service.MinAge)
+		}
+	}
+	return nil
+}
+
+// ClientGuardianRepository defines the repository interface for guardian links and age restrictions
+type ClientGuardianRepository interface {
+	// SetGuardian links the client to a guardian, or unlinks it when guardianID is nil
+	SetGuardian(ctx context.Context, clientID string, guardianID, relationship *string, consentAt *time.Time) error
+	SetConsent(ctx context.Context, clientID string, consentAt time.Time) error
+	FindDependents(ctx context.Context, guardianID string) ([]*Client, error)
+	FindService(ctx context.Context, serviceID string) (*Service, error)
+	SetServiceMinAge(ctx context.Context, serviceID string, minAge *int) error
+}
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func clientBornOn(id string, dateOfBirth time.Time) *Client {
+	return &Client{BaseModel: BaseModel{ID: id}, BusinessID: "business-1", FirstName: "Ana", LastName: "Silva", DateOfBirth: &dateOfBirth}
+}
+
+func TestAgeOn(t *testing.T) {
+	born := date(2010, time.June, 15)
+
+	assert.Equal(t, 15, AgeOn(born, date(2026, time.June, 14)))
+	assert.Equal(t, 16, AgeOn(born, date(2026, time.June, 15)))
+	assert.Equal(t, 16, AgeOn(born, date(2026, time.December, 1)))
+	assert.Equal(t, 3, AgeOn(date(2020, time.February, 29), date(2024, time.February, 28)))
+}
+
+func TestClient_IsMinorAt(t *testing.T) {
+	now := date(2026, time.October, 17)
+
+	assert.True(t, clientBornOn("minor", date(2010, time.January, 1)).IsMinorAt(now))
+	assert.False(t, clientBornOn("adult", date(2008, time.October, 17)).IsMinorAt(now))
+	assert.False(t, (&Client{}).IsMinorAt(now), "an unknown date of birth is not treated as a minor")
+}
+
+func TestClient_ValidateGuardian(t *testing.T) {
+	now := date(2026, time.October, 17)
+	minor := clientBornOn("minor", date(2012, time.March, 3))
+	parent := clientBornOn("parent", date(1985, time.May, 5))
+
+	assert.NoError(t, minor.ValidateGuardian(parent, now))
+
+	tests := []struct {
+		name     string
+		client   *Client
+		guardian *Client
+	}{
+		{"self", minor, minor},
+		{"other business", minor, &Client{BaseModel: BaseModel{ID: "other"}, BusinessID: "business-2"}},
+		{"minor guardian", minor, clientBornOn("sibling", date(2011, time.July, 1))},
+		{"adult client", clientBornOn("adult", date(1990, time.January, 1)), parent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.True(t, errors.Is(tt.client.ValidateGuardian(tt.guardian, now), ErrValidation))
+		})
+	}
+
+	guardianID := "grandparent"
+	linked := clientBornOn("linked", date(1990, time.January, 1))
+	linked.GuardianClientID = &guardianID
+	assert.True(t, errors.Is(minor.ValidateGuardian(linked, now), ErrValidation), "guardians cannot be chained")
+}
+
+func TestClient_CheckBookingAge(t *testing.T) {
+	at := date(2026, time.October, 17)
+	sixteen := 16
+	tanning := &Service{Name: "Spray tan", MinAge: &sixteen}
+	haircut := &Service{Name: "Haircut"}
+
+	minor := clientBornOn("minor", date(2012, time.March, 3))
+	assert.ErrorIs(t, minor.CheckBookingAge(nil, at), ErrValidation, "a minor needs a guardian")
+
+	guardianID := "parent"
+	minor.GuardianClientID = &guardianID
+	assert.ErrorIs(t, minor.CheckBookingAge(nil, at), ErrValidation, "a minor needs the guardian's consent")
+
+	consentAt := at.AddDate(0, -1, 0)
+	minor.GuardianConsentAt = &consentAt
+	assert.NoError(t, minor.CheckBookingAge([]*Service{haircut}, at))
+	assert.ErrorIs(t, minor.CheckBookingAge([]*Service{haircut, tanning}, at), ErrValidation)
+
+	adult := clientBornOn("adult", date(1990, time.January, 1))
+	assert.NoError(t, adult.CheckBookingAge([]*Service{tanning}, at))
+
+	unknown := &Client{FirstName: "Rui"}
+	assert.NoError(t, unknown.CheckBookingAge([]*Service{haircut}, at))
+	assert.ErrorIs(t, unknown.CheckBookingAge([]*Service{tanning}, at), ErrValidation, "age-restricted services need a date of birth")
+}
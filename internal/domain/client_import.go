@@ -0,0 +1,357 @@
+package domain
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// MaxClientImportRows bounds the rows of a single client import
+const MaxClientImportRows = 5000
+
+// ClientImportStatus is the outcome of a row of a client import
+type ClientImportStatus string
+
+const (
+	ClientImportReady     ClientImportStatus = "ready"     // Valid, but not imported because of a dry run or an invalid row
+	ClientImportImported  ClientImportStatus = "imported"  // Created as a new client
+	ClientImportDuplicate ClientImportStatus = "duplicate" // Skipped because the client already exists
+	ClientImportInvalid   ClientImportStatus = "invalid"   // Rejected; see the errors
+)
+
+// clientImportColumns maps the accepted header names onto client fields. Headers are matched
+// case-insensitively, ignoring spaces, dashes and underscores.
+var clientImportColumns = map[string]string{
+	"firstname":      "first_name",
+	"givenname":      "first_name",
+	"lastname":       "last_name",
+	"surname":        "last_name",
+	"familyname":     "last_name",
+	"name":           "name",
+	"fullname":       "name",
+	"email":          "email",
+	"emailaddress":   "email",
+	"phone":          "phone",
+	"phonenumber":    "phone",
+	"mobile":         "phone",
+	"telephone":      "phone",
+	"dateofbirth":    "date_of_birth",
+	"birthdate":      "date_of_birth",
+	"birthday":       "date_of_birth",
+	"dob":            "date_of_birth",
+	"notes":          "notes",
+	"allergies":      "allergies",
+	"referralsource": "referral_source",
+	"source":         "referral_source",
+}
+
+// clientImportDateLayouts are the accepted date of birth formats, day before month as exported
+// by European tools
+var clientImportDateLayouts = []string{"2006-01-02", "02/01/2006", "02-01-2006", "02.01.2006"}
+
+// ClientImportRow is a row of a client import with the client it describes
+type ClientImportRow struct {
+	Line            int // Line of the file, counting the header as line 1
+	Client          Client
+	Status          ClientImportStatus
+	Errors          []string
+	DuplicateOfID   *string // The existing client the row duplicates
+	DuplicateOfLine *int    // The earlier row of the file the row duplicates
+}
+
+// ClientContact is the email and phone of an existing client, used to detect duplicates
+type ClientContact struct {
+	ID    string
+	Email *string
+	Phone *string
+}
+
+// ParseClientCSV reads clients from a CSV file with a header row. Commas, semicolons and tabs
+// are accepted as separators, so spreadsheets exported from Excel in any locale can be imported.
+// Rows that fail validation are returned with status invalid; an error is only returned when
+// the file as a whole cannot be imported.
+func ParseClientCSV(r io.Reader, businessID string) ([]*ClientImportRow, error) {
+	records, err := readImportCSV(r, clientImportColumns, requireClientImportColumns, MaxClientImportRows, "clients")
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]*ClientImportRow, len(records))
+	for i, record := range records {
+		rows[i] = parseClientImportRow(record.line, businessID, record.values)
+	}
+	return rows, nil
+}
+
+// importRecord is a non-blank row of an imported CSV file, its values keyed by field
+type importRecord struct {
+	line   int
+	values map[string]string
+}
+
+// readImportCSV reads the rows of a CSV file with a header row, keeping the values of the
+// columns whose header maps onto a field. Commas, semicolons and tabs are accepted as
+// separators. requireColumns checks the fields found in the header.
+func readImportCSV(r io.Reader, columns map[string]string, requireColumns func(found map[string]bool) error, maxRows int, noun string) ([]importRecord, error) {
+	buffered := bufio.NewReader(r)
+	firstLine, err := buffered.Peek(4096)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, err
+	}
+
+	reader := csv.NewReader(buffered)
+	reader.Comma = detectCSVSeparator(string(firstLine))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: the file is empty", ErrValidation)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: the header cannot be read: %v", ErrValidation, err)
+	}
+	fields, found := mapImportHeader(header, columns)
+	if err := requireColumns(found); err != nil {
+		return nil, err
+	}
+
+	var records []importRecord
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: the file cannot be read: %v", ErrValidation, err)
+		}
+		line, _ := reader.FieldPos(0)
+		if isBlankRecord(record) {
+			continue
+		}
+		if len(records) == maxRows {
+			return nil, fmt.Errorf("%w: a single import is limited to %d %s", ErrValidation, maxRows, noun)
+		}
+
+		values := map[string]string{}
+		for i, value := range record {
+			if field, ok := fields[i]; ok {
+				values[field] = strings.TrimSpace(value)
+			}
+		}
+		records = append(records, importRecord{line: line, values: values})
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%w: the file has no %s", ErrValidation, noun)
+	}
+	return records, nil
+}
+
+// MarkClientImportDuplicates marks the valid rows whose email or phone belongs to an existing
+// client or to an earlier row of the file
+func MarkClientImportDuplicates(rows []*ClientImportRow, existing []ClientContact) {
+	existingIDs := map[string]string{}
+	for _, contact := range existing {
+		for _, key := range contactKeys(contact.Email, contact.Phone) {
+			existingIDs[key] = contact.ID
+		}
+	}
+
+	seenLines := map[string]int{}
+	for _, row := range rows {
+		if row.Status == ClientImportInvalid {
+			continue
+		}
+		keys := contactKeys(&row.Client.Email, row.Client.Phone)
+		for _, key := range keys {
+			if id, ok := existingIDs[key]; ok {
+				row.Status = ClientImportDuplicate
+				row.DuplicateOfID = &id
+				break
+			}
+			if line, ok := seenLines[key]; ok {
+				row.Status = ClientImportDuplicate
+				row.DuplicateOfLine = &line
+				break
+			}
+		}
+		if row.Status == ClientImportDuplicate {
+			continue
+		}
+		for _, key := range keys {
+			seenLines[key] = row.Line
+		}
+	}
+}
+
+// detectCSVSeparator picks the separator that occurs most often in the header line
+func detectCSVSeparator(sample string) rune {
+	if i := strings.IndexAny(sample, "\r\n"); i >= 0 {
+		sample = sample[:i]
+	}
+	separator, best := ',', strings.Count(sample, ",")
+	for _, candidate := range []rune{';', '\t'} {
+		if count := strings.Count(sample, string(candidate)); count > best {
+			separator, best = candidate, count
+		}
+	}
+	return separator
+}
+
+// mapImportHeader maps column positions onto the fields their header names, returning the
+// fields found. Headers are matched case-insensitively, ignoring spaces, dashes and
+// underscores; only the first column of a field is kept.
+func mapImportHeader(header []string, columns map[string]string) (map[int]string, map[string]bool) {
+	fields := map[int]string{}
+	found := map[string]bool{}
+	for i, name := range header {
+		key := strings.ToLower(strings.TrimPrefix(name, "\ufeff"))
+		key = strings.NewReplacer(" ", "", "_", "", "-", "").Replace(strings.TrimSpace(key))
+		field, ok := columns[key]
+		if !ok || found[field] {
+			continue
+		}
+		fields[i] = field
+		found[field] = true
+	}
+	return fields, found
+}
+
+// requireClientImportColumns checks that the names and an email of clients can be read
+func requireClientImportColumns(found map[string]bool) error {
+	if !found["email"] {
+		return fmt.Errorf("%w: the file needs an email column", ErrValidation)
+	}
+	if !found["name"] && (!found["first_name"] || !found["last_name"]) {
+		return fmt.Errorf("%w: the file needs first and last name columns, or a full name column", ErrValidation)
+	}
+	return nil
+}
+
+// parseClientImportRow builds and validates the client of a row
+func parseClientImportRow(line int, businessID string, values map[string]string) *ClientImportRow {
+	row := &ClientImportRow{Line: line, Status: ClientImportReady}
+	client := &row.Client
+	client.BusinessID = businessID
+	client.IsActive = true
+
+	client.FirstName, client.LastName = values["first_name"], values["last_name"]
+	if client.FirstName == "" && client.LastName == "" && values["name"] != "" {
+		client.FirstName, client.LastName, _ = strings.Cut(strings.Join(strings.Fields(values["name"]), " "), " ")
+	}
+	if client.FirstName == "" {
+		row.addError("first name is required")
+	} else if len(client.FirstName) > 100 {
+		row.addError("first name must be at most 100 characters")
+	}
+	if client.LastName == "" {
+		row.addError("last name is required")
+	} else if len(client.LastName) > 100 {
+		row.addError("last name must be at most 100 characters")
+	}
+
+	client.Email = strings.ToLower(values["email"])
+	if client.Email == "" {
+		row.addError("email is required")
+	} else if !isImportableEmail(client.Email) {
+		row.addError(fmt.Sprintf("%q is not a valid email address", values["email"]))
+	}
+
+	if phone := values["phone"]; phone != "" {
+		if len(NormalizePhone(phone)) < 6 || len(phone) > 50 {
+			row.addError(fmt.Sprintf("%q is not a valid phone number", phone))
+		}
+		client.Phone = &phone
+	}
+
+	if value := values["date_of_birth"]; value != "" {
+		dateOfBirth, ok := parseClientImportDate(value)
+		if !ok {
+			row.addError(fmt.Sprintf("%q is not a valid date of birth; use YYYY-MM-DD or DD/MM/YYYY", value))
+		} else if dateOfBirth.After(time.Now()) {
+			row.addError("date of birth is in the future")
+		} else {
+			client.DateOfBirth = &dateOfBirth
+		}
+	}
+
+	client.Notes = optionalValue(values["notes"])
+	client.Allergies = optionalValue(values["allergies"])
+	client.ReferralSource = optionalValue(values["referral_source"])
+	if client.ReferralSource != nil && len(*client.ReferralSource) > 100 {
+		row.addError("referral source must be at most 100 characters")
+	}
+	return row
+}
+
+// addError records a validation error, making the row invalid
+func (r *ClientImportRow) addError(message string) {
+	r.Status = ClientImportInvalid
+	r.Errors = append(r.Errors, message)
+}
+
+// isImportableEmail reports whether an email is a bare address with a domain name that could
+// receive mail
+func isImportableEmail(email string) bool {
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email || len(email) > 255 {
+		return false
+	}
+	_, domain, _ := strings.Cut(email, "@")
+	return strings.Contains(strings.Trim(domain, "."), ".")
+}
+
+// parseClientImportDate parses a date in any of the accepted layouts
+func parseClientImportDate(value string) (time.Time, bool) {
+	for _, layout := range clientImportDateLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// contactKeys returns the normalized email and phone a client is recognized by
+func contactKeys(email, phone *string) []string {
+	var keys []string
+	if email != nil && strings.TrimSpace(*email) != "" {
+		keys = append(keys, "email:"+strings.ToLower(strings.TrimSpace(*email)))
+	}
+	if phone != nil {
+		if digits := NormalizePhone(*phone); len(digits) >= 6 {
+			keys = append(keys, "phone:"+digits)
+		}
+	}
+	return keys
+}
+
+// isBlankRecord reports whether every value of a record is empty
+func isBlankRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// optionalValue returns nil for an empty value
+func optionalValue(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// ClientImportRepository defines the interface for importing clients in bulk
+type ClientImportRepository interface {
+	// FindContacts finds the email and phone of every client of a business
+	FindContacts(ctx context.Context, businessID string) ([]ClientContact, error)
+	// CreateClients creates the clients in a single transaction
+	CreateClients(ctx context.Context, clients []*Client) error
+}
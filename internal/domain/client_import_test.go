@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseClientCSV(t *testing.T) {
+	file := "\ufeffFirst Name;Last Name;E-mail;Mobile;Birthday;Notes\n" +
+		"Ana;Silva;Ana@Example.com;+351 912 345 678;15/03/1990;Prefers mornings\n" +
+		"\n" +
+		"Rui;;rui@example;12;1990-13-01;\n"
+
+	rows, err := ParseClientCSV(strings.NewReader(file), "business-1")
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+
+	ana := rows[0]
+	assert.Equal(t, 2, ana.Line)
+	assert.Equal(t, ClientImportReady, ana.Status)
+	assert.Equal(t, "business-1", ana.Client.BusinessID)
+	assert.Equal(t, "ana@example.com", ana.Client.Email)
+	require.NotNil(t, ana.Client.DateOfBirth)
+	assert.Equal(t, "1990-03-15", ana.Client.DateOfBirth.Format("2006-01-02"))
+	require.NotNil(t, ana.Client.Notes)
+
+	rui := rows[1]
+	assert.Equal(t, 4, rui.Line, "blank lines are skipped but counted")
+	assert.Equal(t, ClientImportInvalid, rui.Status)
+	assert.Len(t, rui.Errors, 4)
+}
+
+func TestParseClientCSV_FullName(t *testing.T) {
+	rows, err := ParseClientCSV(strings.NewReader("name,email\nAna Maria Silva,ana@example.com\n"), "business-1")
+	require.NoError(t, err)
+	assert.Equal(t, "Ana", rows[0].Client.FirstName)
+	assert.Equal(t, "Maria Silva", rows[0].Client.LastName)
+}
+
+func TestParseClientCSV_InvalidFile(t *testing.T) {
+	_, err := ParseClientCSV(strings.NewReader(""), "business-1")
+	assert.ErrorIs(t, err, ErrValidation)
+
+	_, err = ParseClientCSV(strings.NewReader("first_name,last_name,phone\nAna,Silva,912345678\n"), "business-1")
+	assert.ErrorContains(t, err, "email column")
+
+	_, err = ParseClientCSV(strings.NewReader("first_name,last_name,email\n"), "business-1")
+	assert.ErrorContains(t, err, "no clients")
+}
+
+func TestMarkClientImportDuplicates(t *testing.T) {
+	file := "first_name,last_name,email,phone\n" +
+		"Ana,Silva,ana@example.com,\n" +
+		"Rita,Costa,rita@example.com,912 345 678\n" +
+		"Rita,Costa,rita.costa@example.com,+351912345678\n" +
+		"Joana,Sousa,joana@example.com,\n"
+	rows, err := ParseClientCSV(strings.NewReader(file), "business-1")
+	require.NoError(t, err)
+
+	existingEmail := "ANA@example.com"
+	existingPhone := "+351 912345678"
+	MarkClientImportDuplicates(rows, []ClientContact{
+		{ID: "client-1", Email: &existingEmail},
+		{ID: "client-2", Phone: &existingPhone},
+	})
+
+	assert.Equal(t, ClientImportDuplicate, rows[0].Status)
+	assert.Equal(t, "client-1", *rows[0].DuplicateOfID)
+	assert.Equal(t, ClientImportReady, rows[1].Status, "phones are compared digit by digit, with the country code")
+	assert.Equal(t, ClientImportDuplicate, rows[2].Status)
+	assert.Equal(t, "client-2", *rows[2].DuplicateOfID)
+	assert.Equal(t, ClientImportReady, rows[3].Status)
+
+	rows, err = ParseClientCSV(strings.NewReader(file), "business-1")
+	require.NoError(t, err)
+	rows[2].Client.Phone = rows[1].Client.Phone
+	MarkClientImportDuplicates(rows, nil)
+	require.Equal(t, ClientImportDuplicate, rows[2].Status)
+	assert.Equal(t, 3, *rows[2].DuplicateOfLine)
+}
@@ -0,0 +1,182 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// LeaderboardMetric is what clients are ranked by
+type LeaderboardMetric string
+
+const (
+	LeaderboardSpend  LeaderboardMetric = "spend"  // Spent on completed appointments
+	LeaderboardVisits LeaderboardMetric = "visits" // Completed appointments
+	LeaderboardPoints LeaderboardMetric = "points" // Loyalty points earned, across programs
+)
+
+// IsValid checks if the leaderboard metric is valid
+func (m LeaderboardMetric) IsValid() bool {
+	return m == LeaderboardSpend || m == LeaderboardVisits || m == LeaderboardPoints
+}
+
+const (
+	DefaultLeaderboardLimit = 20
+	MaxLeaderboardLimit     = 100
+	// MaxLeaderboardDays is the longest period a leaderboard covers, two years
+	MaxLeaderboardDays = 731
+)
+
+// VIPTag is the client tag marking VIP clients. While a business's VIP rules are enabled the
+// tag follows them: it is given to clients meeting a threshold and taken from the others.
+const VIPTag = "vip"
+
+// ClientStanding is what a client did at a business over a period. Visits and spend count
+// completed appointments, and points the loyalty points earned, referral bonuses included.
+type ClientStanding struct {
+	ClientID  string
+	Visits    int
+	Spent     decimal.Decimal
+	Points    int
+	LastVisit *time.Time
+	Rank      int // 1 for the top client; clients tied share a rank
+}
+
+// value returns the standing's measure of a metric, for comparing standings
+func (s *ClientStanding) value(metric LeaderboardMetric) decimal.Decimal {
+	switch metric {
+	case LeaderboardVisits:
+		return decimal.NewFromInt(int64(s.Visits))
+	case LeaderboardPoints:
+		return decimal.NewFromInt(int64(s.Points))
+	default:
+		return s.Spent
+	}
+}
+
+// RankStandings orders standings by a metric, highest first, and numbers their ranks. Clients
+// tied on the metric share a rank, the next rank skipping as many places, and are listed by
+// their latest visit, then ID, so the order is stable. Clients with nothing on the metric are
+// left out.
+func RankStandings(standings []*ClientStanding, metric LeaderboardMetric) []*ClientStanding {
+	ranked := slices.DeleteFunc(slices.Clone(standings), func(s *ClientStanding) bool {
+		return !s.value(metric).IsPositive()
+	})
+	slices.SortStableFunc(ranked, func(a, b *ClientStanding) int {
+		if c := b.value(metric).Cmp(a.value(metric)); c != 0 {
+			return c
+		}
+		switch {
+		case a.LastVisit != nil && b.LastVisit != nil && !a.LastVisit.Equal(*b.LastVisit):
+			return b.LastVisit.Compare(*a.LastVisit)
+		case a.LastVisit != nil && b.LastVisit == nil:
+			return -1
+		case a.LastVisit == nil && b.LastVisit != nil:
+			return 1
+		}
+		return strings.Compare(a.ClientID, b.ClientID)
+	})
+	for i, standing := range ranked {
+		standing.Rank = i + 1
+		if i > 0 && standing.value(metric).Equal(ranked[i-1].value(metric)) {
+			standing.Rank = ranked[i-1].Rank
+		}
+	}
+	return ranked
+}
+
+// LeaderboardPeriod returns the period a leaderboard covers, checking that it is no longer than
+// MaxLeaderboardDays
+func LeaderboardPeriod(from, to time.Time) (*DateRange, error) {
+	if !to.After(from) {
+		return nil, fmt.Errorf("%w: the period must end after it starts", ErrValidation)
+	}
+	if to.Sub(from) > MaxLeaderboardDays*24*time.Hour {
+		return nil, fmt.Errorf("%w: the period cannot be longer than %d days", ErrValidation, MaxLeaderboardDays)
+	}
+	return &DateRange{Start: from, End: to}, nil
+}
+
+// VIPRules are the thresholds making clients VIPs, stored with a business's settings. A client
+// meeting any threshold over the trailing period is a VIP.
+type VIPRules struct {
+	Enabled    bool             `json:"enabled"`
+	PeriodDays int              `json:"period_days"`
+	MinSpend   *decimal.Decimal `json:"min_spend,omitempty"`
+	MinVisits  *int             `json:"min_visits,omitempty"`
+	MinPoints  *int             `json:"min_points,omitempty"`
+}
+
+// DefaultVIPPeriodDays is the period VIP thresholds apply to unless a business chooses another
+const DefaultVIPPeriodDays = 365
+
+// Validate checks that the period is in range and that enabled rules have a threshold
+func (r VIPRules) Validate() error {
+	if r.PeriodDays < 1 || r.PeriodDays > MaxLeaderboardDays {
+		return fmt.Errorf("%w: period_days must be between 1 and %d", ErrValidation, MaxLeaderboardDays)
+	}
+	if (r.MinSpend != nil && !r.MinSpend.IsPositive()) || (r.MinVisits != nil && *r.MinVisits < 1) || (r.MinPoints != nil && *r.MinPoints < 1) {
+		return fmt.Errorf("%w: VIP thresholds must be positive", ErrValidation)
+	}
+	if r.Enabled && r.MinSpend == nil && r.MinVisits == nil && r.MinPoints == nil {
+		return fmt.Errorf("%w: VIP rules need a threshold on spend, visits or points", ErrValidation)
+	}
+	return nil
+}
+
+// Period returns the trailing period the thresholds apply to at the given time
+func (r VIPRules) Period(now time.Time) DateRange {
+	return DateRange{Start: now.AddDate(0, 0, -r.PeriodDays), End: now}
+}
+
+// Qualifies reports whether a client's standing over the period meets a threshold
+func (r VIPRules) Qualifies(standing *ClientStanding) bool {
+	return (r.MinSpend != nil && standing.Spent.GreaterThanOrEqual(*r.MinSpend)) ||
+		(r.MinVisits != nil && standing.Visits >= *r.MinVisits) ||
+		(r.MinPoints != nil && standing.Points >= *r.MinPoints)
+}
+
+// GetVIPRules decodes the business's VIP rules. Businesses that never set any have them
+// disabled, over DefaultVIPPeriodDays.
+func (s *BusinessSettings) GetVIPRules() (VIPRules, error) {
+	rules := VIPRules{PeriodDays: DefaultVIPPeriodDays}
+	if s == nil || s.VIPRules == nil || *s.VIPRules == "" {
+		return rules, nil
+	}
+	if err := json.Unmarshal([]byte(*s.VIPRules), &rules); err != nil {
+		return VIPRules{}, fmt.Errorf("%w: invalid VIP rules", ErrValidation)
+	}
+	return rules, nil
+}
+
+// SetVIPRules validates and encodes the business's VIP rules
+func (s *BusinessSettings) SetVIPRules(rules VIPRules) error {
+	if err := rules.Validate(); err != nil {
+		return err
+	}
+	data, err := json.Marshal(rules)
+	if err != nil {
+		return fmt.Errorf("failed to encode VIP rules: %w", err)
+	}
+	encoded := string(data)
+	s.VIPRules = &encoded
+	return nil
+}
+
+// ClientLeaderboardRepository defines the repository interface for ranking the clients of a
+// business and tagging its VIPs
+type ClientLeaderboardRepository interface {
+	// FindStandings sums up what each active client of the business did in the period, leaving
+	// out clients who did nothing
+	FindStandings(ctx context.Context, businessID string, period DateRange) ([]*ClientStanding, error)
+	// FindBusinessesWithVIPRules finds the businesses whose VIP rules are enabled
+	FindBusinessesWithVIPRules(ctx context.Context) ([]string, error)
+	// SetVIPClients gives VIPTag to exactly the given clients of the business, taking it from
+	// the others, and returns how many clients gained and lost it
+	SetVIPClients(ctx context.Context, businessID string, clientIDs []string) (tagged, untagged int64, err error)
+}
@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRankStandings(t *testing.T) {
+	earlier := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	later := earlier.AddDate(0, 1, 0)
+	standings := []*ClientStanding{
+		{ClientID: "a", Visits: 2, Spent: decimal.NewFromInt(80), LastVisit: &earlier},
+		{ClientID: "b", Visits: 5, Spent: decimal.NewFromInt(120), LastVisit: &earlier},
+		{ClientID: "c", Visits: 2, Spent: decimal.NewFromInt(200), LastVisit: &later},
+		{ClientID: "d", Points: 40},
+	}
+
+	bySpend := RankStandings(standings, LeaderboardSpend)
+	require.Len(t, bySpend, 3, "clients with nothing spent are left out")
+	assert.Equal(t, []string{"c", "b", "a"}, clientIDs(bySpend))
+	assert.Equal(t, []int{1, 2, 3}, ranks(bySpend))
+
+	byVisits := RankStandings(standings, LeaderboardVisits)
+	assert.Equal(t, []string{"b", "c", "a"}, clientIDs(byVisits), "ties are listed by latest visit")
+	assert.Equal(t, []int{1, 2, 2}, ranks(byVisits), "ties share a rank")
+
+	byPoints := RankStandings(standings, LeaderboardPoints)
+	assert.Equal(t, []string{"d"}, clientIDs(byPoints))
+}
+
+func clientIDs(standings []*ClientStanding) []string {
+	ids := make([]string, len(standings))
+	for i, standing := range standings {
+		ids[i] = standing.ClientID
+	}
+	return ids
+}
+
+func ranks(standings []*ClientStanding) []int {
+	result := make([]int, len(standings))
+	for i, standing := range standings {
+		result[i] = standing.Rank
+	}
+	return result
+}
+
+func TestLeaderboardPeriod(t *testing.T) {
+	now := time.Now()
+	period, err := LeaderboardPeriod(now.AddDate(0, -6, 0), now)
+	require.NoError(t, err)
+	assert.Equal(t, now, period.End)
+
+	_, err = LeaderboardPeriod(now, now)
+	assert.ErrorIs(t, err, ErrValidation)
+	_, err = LeaderboardPeriod(now.AddDate(-3, 0, 0), now)
+	assert.ErrorIs(t, err, ErrValidation)
+}
+
+func TestVIPRules(t *testing.T) {
+	spend := decimal.NewFromInt(500)
+	visits := 10
+	rules := VIPRules{Enabled: true, PeriodDays: 365, MinSpend: &spend, MinVisits: &visits}
+	require.NoError(t, rules.Validate())
+
+	assert.True(t, rules.Qualifies(&ClientStanding{Spent: decimal.NewFromInt(500)}))
+	assert.True(t, rules.Qualifies(&ClientStanding{Visits: 12}), "meeting any threshold is enough")
+	assert.False(t, rules.Qualifies(&ClientStanding{Spent: decimal.NewFromInt(499), Visits: 9, Points: 1000}))
+
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, now.AddDate(-1, 0, 0), rules.Period(now).Start)
+
+	assert.ErrorIs(t, VIPRules{Enabled: true, PeriodDays: 365}.Validate(), ErrValidation, "enabled rules need a threshold")
+	assert.NoError(t, VIPRules{PeriodDays: 365}.Validate())
+	assert.ErrorIs(t, VIPRules{PeriodDays: 0}.Validate(), ErrValidation)
+	zero := 0
+	assert.ErrorIs(t, VIPRules{PeriodDays: 30, MinVisits: &zero}.Validate(), ErrValidation)
+}
+
+func TestBusinessSettings_VIPRules(t *testing.T) {
+	var none *BusinessSettings
+	rules, err := none.GetVIPRules()
+	require.NoError(t, err)
+	assert.False(t, rules.Enabled)
+	assert.Equal(t, DefaultVIPPeriodDays, rules.PeriodDays)
+
+	points := 300
+	settings := &BusinessSettings{}
+	require.NoError(t, settings.SetVIPRules(VIPRules{Enabled: true, PeriodDays: 90, MinPoints: &points}))
+	rules, err = settings.GetVIPRules()
+	require.NoError(t, err)
+	assert.True(t, rules.Enabled)
+	assert.Equal(t, 90, rules.PeriodDays)
+	assert.Equal(t, 300, *rules.MinPoints)
+
+	assert.ErrorIs(t, settings.SetVIPRules(VIPRules{Enabled: true, PeriodDays: 90}), ErrValidation)
+}
@@ -0,0 +1,239 @@
+package domain
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DuplicateReason tells why two clients are probably the same person
+type DuplicateReason string
+
+const (
+	DuplicateEmail DuplicateReason = "email" // The same email address, ignoring case
+	DuplicatePhone DuplicateReason = "phone" // The same phone number, ignoring punctuation
+	DuplicateName  DuplicateReason = "name"  // The same name, ignoring case, accents, punctuation and word order
+)
+
+// MaxDuplicateGroupSize is the most clients sharing an email, phone or name that are reported as
+// duplicates of each other. Larger groups are placeholders, such as the salon's own phone
+// number entered for walk-ins, rather than the same person.
+const MaxDuplicateGroupSize = 5
+
+// ErrClientMembershipConflict is returned when the clients being merged both hold a current
+// membership of the same plan
+var ErrClientMembershipConflict = errors.New("both clients hold a current membership of the same plan; cancel one first")
+
+// ClientDuplicate is a pair of clients of a business that are probably the same person. Client
+// is the older of the two, which is usually the one to keep.
+type ClientDuplicate struct {
+	Client    *Client
+	Duplicate *Client
+	Reasons   []DuplicateReason
+}
+
+// FindDuplicateClients finds the pairs of clients sharing an email, phone or name, strongest
+// matches first. Anonymized clients, and clients linked as guardian and minor or as minors of
+// the same guardian, who often share their contact details, are never reported.
+func FindDuplicateClients(clients []*Client) []*ClientDuplicate {
+	groups := map[string][]*Client{}
+	for _, client := range clients {
+		if client.AnonymizedAt != nil {
+			continue
+		}
+		for _, key := range duplicateKeys(client) {
+			groups[key] = append(groups[key], client)
+		}
+	}
+
+	pairs := map[[2]string]*ClientDuplicate{}
+	for key, group := range groups {
+		if len(group) < 2 || len(group) > MaxDuplicateGroupSize {
+			continue
+		}
+		reason, _, _ := strings.Cut(key, ":")
+		slices.SortFunc(group, compareClientsByAge)
+		for i, client := range group {
+			for _, duplicate := range group[i+1:] {
+				if client.ID == duplicate.ID || relatedByGuardian(client, duplicate) {
+					continue
+				}
+				pairKey := [2]string{client.ID, duplicate.ID}
+				if pairs[pairKey] == nil {
+					pairs[pairKey] = &ClientDuplicate{Client: client, Duplicate: duplicate}
+				}
+				pairs[pairKey].Reasons = append(pairs[pairKey].Reasons, DuplicateReason(reason))
+			}
+		}
+	}
+
+	duplicates := make([]*ClientDuplicate, 0, len(pairs))
+	for _, pair := range pairs {
+		slices.Sort(pair.Reasons)
+		duplicates = append(duplicates, pair)
+	}
+	slices.SortFunc(duplicates, func(a, b *ClientDuplicate) int {
+		return cmp.Or(
+			cmp.Compare(len(b.Reasons), len(a.Reasons)),
+			cmp.Compare(duplicateStrength(a), duplicateStrength(b)),
+			compareClientsByAge(a.Client, b.Client),
+			compareClientsByAge(a.Duplicate, b.Duplicate),
+		)
+	})
+	return duplicates
+}
+
+// duplicateKeys returns the keys a client is grouped with its probable duplicates by
+func duplicateKeys(client *Client) []string {
+	email := client.Email
+	keys := contactKeys(&email, client.Phone)
+	if terms := SearchTerms(client.FirstName + " " + client.LastName); len(terms) >= 2 {
+		slices.Sort(terms)
+		keys = append(keys, string(DuplicateName)+":"+strings.Join(terms, " "))
+	}
+	return keys
+}
+
+// duplicateStrength orders pairs matched by contact details before those matched by name only
+func duplicateStrength(pair *ClientDuplicate) int {
+	if slices.Contains(pair.Reasons, DuplicateEmail) || slices.Contains(pair.Reasons, DuplicatePhone) {
+		return 0
+	}
+	return 1
+}
+
+// compareClientsByAge orders clients by when they were created, then ID
+func compareClientsByAge(a, b *Client) int {
+	return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+}
+
+// relatedByGuardian reports whether one client is the other's guardian, or both are minors of
+// the same guardian
+func relatedByGuardian(a, b *Client) bool {
+	return (a.GuardianClientID != nil && *a.GuardianClientID == b.ID) ||
+		(b.GuardianClientID != nil && *b.GuardianClientID == a.ID) ||
+		(a.GuardianClientID != nil && b.GuardianClientID != nil && *a.GuardianClientID == *b.GuardianClientID)
+}
+
+// CheckMerge checks that a duplicate can be merged into the client
+func (c *Client) CheckMerge(duplicate *Client) error {
+	switch {
+	case c.ID == duplicate.ID:
+		return fmt.Errorf("%w: a client cannot be merged into itself", ErrValidation)
+	case c.BusinessID != duplicate.BusinessID:
+		return fmt.Errorf("%w: only clients of the same business can be merged", ErrValidation)
+	case c.AnonymizedAt != nil || duplicate.AnonymizedAt != nil:
+		return fmt.Errorf("%w: anonymized clients cannot be merged", ErrValidation)
+	case (c.GuardianClientID != nil && *c.GuardianClientID == duplicate.ID) ||
+		(duplicate.GuardianClientID != nil && *duplicate.GuardianClientID == c.ID):
+		return fmt.Errorf("%w: a client cannot be merged with their guardian", ErrValidation)
+	}
+	return nil
+}
+
+// Absorb folds what is known about a duplicate into the client, which is kept. The client's own
+// details win; the duplicate's fill the gaps. Notes and allergies are kept from both, visit
+// statistics are added up and tags are combined. Marketing consent is never taken from the
+// duplicate, since it was given for the other record.
+func (c *Client) Absorb(duplicate *Client) {
+	if c.Phone == nil {
+		c.Phone = duplicate.Phone
+	}
+	if c.DateOfBirth == nil {
+		c.DateOfBirth = duplicate.DateOfBirth
+	}
+	if c.Gender == nil {
+		c.Gender = duplicate.Gender
+	}
+	if c.ReferralSource == nil {
+		c.ReferralSource = duplicate.ReferralSource
+	}
+	if c.UserID == nil {
+		c.UserID = duplicate.UserID
+	}
+	if c.GuardianClientID == nil && duplicate.GuardianClientID != nil {
+		c.GuardianClientID = duplicate.GuardianClientID
+		c.GuardianRelationship = duplicate.GuardianRelationship
+		c.GuardianConsentAt = duplicate.GuardianConsentAt
+	}
+	c.Notes = joinText(c.Notes, duplicate.Notes)
+	c.Allergies = joinText(c.Allergies, duplicate.Allergies)
+	c.Preferences = mergePreferences(c.Preferences, duplicate.Preferences)
+
+	tags, _ := c.GetTags()
+	duplicateTags, _ := duplicate.GetTags()
+	for _, tag := range duplicateTags {
+		if len(tags) < MaxClientTags && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) > 0 {
+		_ = c.SetTags(tags)
+	}
+
+	if duplicate.LastVisit != nil && (c.LastVisit == nil || duplicate.LastVisit.After(*c.LastVisit)) {
+		c.LastVisit = duplicate.LastVisit
+	}
+	c.TotalVisits += duplicate.TotalVisits
+	c.TotalSpent = c.TotalSpent.Add(duplicate.TotalSpent)
+	c.NoShowCount += duplicate.NoShowCount
+	c.DepositRequired = c.DepositRequired || duplicate.DepositRequired
+	c.IsActive = c.IsActive || duplicate.IsActive
+}
+
+// joinText keeps both of two optional texts, once when they are the same
+func joinText(kept, other *string) *string {
+	switch {
+	case other == nil || strings.TrimSpace(*other) == "":
+		return kept
+	case kept == nil || strings.TrimSpace(*kept) == "":
+		return other
+	case strings.TrimSpace(*kept) == strings.TrimSpace(*other):
+		return kept
+	}
+	joined := strings.TrimRight(*kept, "\n ") + "\n\n" + strings.TrimSpace(*other)
+	return &joined
+}
+
+// mergePreferences adds the other client's preferences to the kept ones, which win. Preferences
+// that cannot be decoded are left as they are.
+func mergePreferences(kept, other *string) *string {
+	merged := map[string]any{}
+	if other == nil || *other == "" || json.Unmarshal([]byte(*other), &merged) != nil || len(merged) == 0 {
+		return kept
+	}
+	if kept != nil && *kept != "" {
+		keptPreferences := map[string]any{}
+		if json.Unmarshal([]byte(*kept), &keptPreferences) != nil {
+			return kept
+		}
+		for key, value := range keptPreferences {
+			merged[key] = value
+		}
+	}
+	encoded, err := json.Marshal(merged)
+	if err != nil {
+		return kept
+	}
+	preferences := string(encoded)
+	return &preferences
+}
+
+// ClientMergeRepository defines the interface for finding and merging duplicate clients
+type ClientMergeRepository interface {
+	// FindCandidates finds the clients of a business that are not anonymized
+	FindCandidates(ctx context.Context, businessID string) ([]*Client, error)
+	// Merge stores the client, which has absorbed the duplicate, and moves everything held about
+	// the duplicate to it in a single transaction: appointments and through them their
+	// completions and payments, loyalty memberships and their points, campaign records, service
+	// records, reviews, invoices, memberships, referrals, attachments and guardian links. The
+	// duplicate is then deleted, pointing at the client it was merged into. It returns how many
+	// rows were moved, and fails with ErrClientMembershipConflict when both clients hold a
+	// current membership of the same plan.
+	Merge(ctx context.Context, client *Client, duplicateID string, at time.Time, by *string) (int64, error)
+}
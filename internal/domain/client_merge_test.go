@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindDuplicateClients(t *testing.T) {
+	day := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	client := func(id string, created int, first, last, email string, phone *string) *Client {
+		return &Client{
+			BaseModel: BaseModel{ID: id, CreatedAt: day.Add(time.Duration(created) * time.Hour)},
+			FirstName: first, LastName: last, Email: email, Phone: phone,
+		}
+	}
+	phone := "+351 912 345 678"
+	samePhone := "+351 912-345-678"
+	rita := client("a", 0, "Rita", "Sousa", "rita@example.com", &phone)
+	ritaAgain := client("b", 2, "Rita", "Sousa", "RITA@example.com ", &samePhone)
+	ritaByName := client("c", 1, "Sousa", "Ríta", "other@example.com", nil)
+	joana := client("d", 3, "Joana", "Lima", "joana@example.com", nil)
+	guardian := "d"
+	minor := client("e", 4, "Maria", "Lima", "joana@example.com", nil)
+	minor.GuardianClientID = &guardian
+	anonymized := client("f", 5, "Rita", "Sousa", "rita@example.com", nil)
+	anonymized.AnonymizedAt = &day
+
+	duplicates := FindDuplicateClients([]*Client{ritaAgain, joana, ritaByName, rita, minor, anonymized})
+
+	require.Len(t, duplicates, 3)
+	assert.Equal(t, "a", duplicates[0].Client.ID, "the older client is the one to keep")
+	assert.Equal(t, "b", duplicates[0].Duplicate.ID)
+	assert.Equal(t, []DuplicateReason{DuplicateEmail, DuplicateName, DuplicatePhone}, duplicates[0].Reasons)
+	assert.Equal(t, [2]string{"a", "c"}, [2]string{duplicates[1].Client.ID, duplicates[1].Duplicate.ID})
+	assert.Equal(t, [2]string{"c", "b"}, [2]string{duplicates[2].Client.ID, duplicates[2].Duplicate.ID})
+	assert.Equal(t, []DuplicateReason{DuplicateName}, duplicates[2].Reasons)
+}
+
+func TestFindDuplicateClientsIgnoresPlaceholders(t *testing.T) {
+	phone := "210000000"
+	var clients []*Client
+	for _, id := range []string{"a", "b", "c", "d", "e", "f"} {
+		clients = append(clients, &Client{BaseModel: BaseModel{ID: id}, FirstName: id, Email: id + "@example.com", Phone: &phone})
+	}
+
+	assert.Empty(t, FindDuplicateClients(clients), "a phone shared by more clients than a group holds is a placeholder")
+	assert.Len(t, FindDuplicateClients(clients[:MaxDuplicateGroupSize]), 10)
+}
+
+func TestClientCheckMerge(t *testing.T) {
+	client := &Client{BaseModel: BaseModel{ID: "a"}, BusinessID: "salon"}
+	now := time.Now()
+	guardian := "a"
+
+	tests := []struct {
+		name      string
+		duplicate *Client
+		wantErr   bool
+	}{
+		{"a duplicate of the same business", &Client{BaseModel: BaseModel{ID: "b"}, BusinessID: "salon"}, false},
+		{"the client itself", &Client{BaseModel: BaseModel{ID: "a"}, BusinessID: "salon"}, true},
+		{"another business", &Client{BaseModel: BaseModel{ID: "b"}, BusinessID: "spa"}, true},
+		{"an anonymized client", &Client{BaseModel: BaseModel{ID: "b"}, BusinessID: "salon", AnonymizedAt: &now}, true},
+		{"a minor of the client", &Client{BaseModel: BaseModel{ID: "b"}, BusinessID: "salon", GuardianClientID: &guardian}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := client.CheckMerge(tt.duplicate)
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, ErrValidation))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestClientAbsorb(t *testing.T) {
+	earlier := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	later := earlier.AddDate(0, 1, 0)
+	phone := "912345678"
+	keptNotes, otherNotes := "Prefers mornings", "Sensitive scalp"
+	allergies := "Latex"
+	keptPreferences, otherPreferences := `{"drink":"tea"}`, `{"drink":"coffee","music":"jazz"}`
+	client := &Client{
+		FirstName: "Rita", Notes: &keptNotes, Preferences: &keptPreferences, LastVisit: &earlier,
+		TotalVisits: 3, TotalSpent: decimal.NewFromInt(90), NoShowCount: 1,
+	}
+	require.NoError(t, client.SetTags([]string{"vip"}))
+	duplicate := &Client{
+		FirstName: "Rita", Phone: &phone, Notes: &otherNotes, Allergies: &allergies, Preferences: &otherPreferences,
+		LastVisit: &later, TotalVisits: 2, TotalSpent: decimal.NewFromInt(40), DepositRequired: true,
+		AcceptsMarketing: true, IsActive: true,
+	}
+	require.NoError(t, duplicate.SetTags([]string{"colour", "vip"}))
+
+	client.Absorb(duplicate)
+
+	assert.Equal(t, &phone, client.Phone, "gaps are filled from the duplicate")
+	assert.Equal(t, "Prefers mornings\n\nSensitive scalp", *client.Notes)
+	assert.Equal(t, "Latex", *client.Allergies)
+	assert.JSONEq(t, `{"drink":"tea","music":"jazz"}`, *client.Preferences, "the client's own preferences win")
+	tags, err := client.GetTags()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"vip", "colour"}, tags)
+	assert.Equal(t, later, *client.LastVisit)
+	assert.Equal(t, 5, client.TotalVisits)
+	assert.True(t, decimal.NewFromInt(130).Equal(client.TotalSpent))
+	assert.Equal(t, 1, client.NoShowCount)
+	assert.True(t, client.DepositRequired)
+	assert.True(t, client.IsActive)
+	assert.False(t, client.AcceptsMarketing, "marketing consent is never taken from the duplicate")
+}
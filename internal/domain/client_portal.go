@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// PortalVisitService is a service delivered during a client's visit
+type PortalVisitService struct {
+	AppointmentID string          `json:"appointment_id"`
+	Name          string          `json:"name"`
+	Duration      int             `json:"duration"` // in minutes
+	Price         decimal.Decimal `json:"price"`
+}
+
+// PortalVisit is a past appointment as shown to the client in the client portal
+type PortalVisit struct {
+	AppointmentID string               `json:"appointment_id"`
+	BusinessID    string               `json:"business_id"`
+	BusinessName  string               `json:"business_name"`
+	BusinessTaxID *string              `json:"business_tax_id,omitempty"`
+	Currency      string               `json:"currency"`
+	ClientID      string               `json:"client_id"`
+	ClientName    string               `json:"client_name"`
+	StaffName     string               `json:"staff_name"`
+	StartTime     time.Time            `json:"start_time"`
+	EndTime       time.Time            `json:"end_time"`
+	Status        AppointmentStatus    `json:"status"`
+	AmountPaid    *decimal.Decimal     `json:"amount_paid,omitempty"` // Nil until the service is completed
+	PaymentMethod *string              `json:"payment_method,omitempty"`
+	LoyaltyPoints int                  `json:"loyalty_points"`
+	InvoiceID     *string              `json:"invoice_id,omitempty"`
+	Services      []PortalVisitService `json:"services" gorm:"-"`
+}
+
+// CanBeInvoiced returns true if an invoice can be issued for the visit
+func (v *PortalVisit) CanBeInvoiced() bool {
+	return v.Status == AppointmentStatusCompleted && v.AmountPaid != nil
+}
+
+// InvoiceLines returns the billed items of the visit. When the amount charged differs from
+// the listed service prices, e.g. after a discount, the difference is billed as an adjustment.
+func (v *PortalVisit) InvoiceLines() []InvoiceLine {
+	lines := make([]InvoiceLine, 0, len(v.Services)+1)
+	listed := decimal.Zero
+	for _, service := range v.Services {
+		lines = append(lines, InvoiceLine{Description: service.Name, Amount: service.Price})
+		listed = listed.Add(service.Price)
+	}
+
+	if v.AmountPaid == nil {
+		return lines
+	}
+	if len(lines) == 0 {
+		return []InvoiceLine{{Description: "Appointment", Amount: *v.AmountPaid}}
+	}
+	if adjustment := v.AmountPaid.Sub(listed); !adjustment.IsZero() {
+		lines = append(lines, InvoiceLine{Description: "Adjustment", Amount: adjustment})
+	}
+	return lines
+}
+
+// ClientPortalRepository defines the read-only queries behind the client portal. Every
+// query is restricted to the given client IDs, which callers resolve from the signed-in user.
+type ClientPortalRepository interface {
+	FindClientIDsByUserID(ctx context.Context, userID string) ([]string, error)
+	FindPastVisits(ctx context.Context, clientIDs []string, page, pageSize int) ([]*PortalVisit, int64, error)
+	FindVisit(ctx context.Context, clientIDs []string, appointmentID string) (*PortalVisit, error)
+}
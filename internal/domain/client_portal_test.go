@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPortalVisitInvoiceLines(t *testing.T) {
+	services := []PortalVisitService{
+		{Name: "Cut", Price: decimal.NewFromInt(30)},
+		{Name: "Colour", Price: decimal.NewFromInt(45)},
+	}
+
+	t.Run("lists services when the charge matches", func(t *testing.T) {
+		paid := decimal.NewFromInt(75)
+		visit := &PortalVisit{Services: services, AmountPaid: &paid}
+
+		lines := visit.InvoiceLines()
+		assert.Len(t, lines, 2)
+		assert.Equal(t, "Cut", lines[0].Description)
+	})
+
+	t.Run("adds an adjustment for discounts", func(t *testing.T) {
+		paid := decimal.NewFromInt(70)
+		visit := &PortalVisit{Services: services, AmountPaid: &paid}
+
+		lines := visit.InvoiceLines()
+		assert.Len(t, lines, 3)
+		assert.Equal(t, "Adjustment", lines[2].Description)
+		assert.True(t, lines[2].Amount.Equal(decimal.NewFromInt(-5)))
+
+		invoice := &Invoice{}
+		assert.NoError(t, invoice.SetLineItems(lines))
+		assert.True(t, invoice.Total.Equal(paid))
+	})
+
+	t.Run("bills the charge when no services are listed", func(t *testing.T) {
+		paid := decimal.NewFromInt(20)
+		visit := &PortalVisit{AmountPaid: &paid}
+
+		lines := visit.InvoiceLines()
+		assert.Len(t, lines, 1)
+		assert.True(t, lines[0].Amount.Equal(paid))
+	})
+}
+
+func TestPortalVisitCanBeInvoiced(t *testing.T) {
+	paid := decimal.NewFromInt(20)
+
+	assert.True(t, (&PortalVisit{Status: AppointmentStatusCompleted, AmountPaid: &paid}).CanBeInvoiced())
+	assert.False(t, (&PortalVisit{Status: AppointmentStatusCompleted}).CanBeInvoiced())
+	assert.False(t, (&PortalVisit{Status: AppointmentStatusCancelled, AmountPaid: &paid}).CanBeInvoiced())
+}
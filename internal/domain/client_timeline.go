@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// TimelineEntryKind tells what happened in a client's timeline
+type TimelineEntryKind string
+
+const (
+	TimelineAppointment TimelineEntryKind = "appointment" // An appointment was booked
+	TimelineCompletion  TimelineEntryKind = "completion"  // An appointment was completed
+	TimelinePayment     TimelineEntryKind = "payment"     // A card payment was taken
+	TimelineLoyalty     TimelineEntryKind = "loyalty"     // Loyalty points were earned, redeemed, adjusted or expired
+	TimelineCampaign    TimelineEntryKind = "campaign"    // A campaign message was sent
+	TimelineNote        TimelineEntryKind = "note"        // A service record was written up
+	TimelineReview      TimelineEntryKind = "review"      // A review was left
+)
+
+// MaxTimelinePageSize is the most entries a page of a client's timeline holds
+const MaxTimelinePageSize = 100
+
+// TimelineEntry is one thing that happened to a client, taken from the record it happened in.
+// Only the fields that apply to its kind are set.
+type TimelineEntry struct {
+	ID            string            `json:"id"` // The ID of the record the entry is taken from
+	Kind          TimelineEntryKind `json:"kind"`
+	OccurredAt    time.Time         `json:"occurred_at"`
+	AppointmentID *string           `json:"appointment_id,omitempty"`
+	Status        *string           `json:"status,omitempty"`      // The appointment, payment, campaign or review status, the loyalty transaction type or the completion's payment method
+	Title         *string           `json:"title,omitempty"`       // The campaign or loyalty program name, or the payment kind
+	Description   *string           `json:"description,omitempty"` // The appointment or service record notes, loyalty description, campaign message or review comment
+	Channel       *string           `json:"channel,omitempty"`     // The channel a campaign message was sent through
+	StartTime     *time.Time        `json:"start_time,omitempty"`  // When the booked appointment starts
+	Amount        *decimal.Decimal  `json:"amount,omitempty"`      // The price charged or the payment amount
+	Currency      *string           `json:"currency,omitempty"`
+	Points        *int              `json:"points,omitempty"`
+	Rating        *int              `json:"rating,omitempty"`
+}
+
+// Cursor returns the cursor pointing at the entry. The timeline is ordered newest first, by
+// when entries occurred and then ID.
+func (e *TimelineEntry) Cursor() Cursor {
+	return Cursor{CreatedAt: e.OccurredAt, ID: e.ID}
+}
+
+// Before reports whether the entry comes after the cursor in the timeline, being older than it
+func (e *TimelineEntry) Before(cursor Cursor) bool {
+	if !e.OccurredAt.Equal(cursor.CreatedAt) {
+		return e.OccurredAt.Before(cursor.CreatedAt)
+	}
+	return e.ID < cursor.ID
+}
+
+// ClientTimelineRepository assembles the timeline of a client from the records of what happened
+// to them
+type ClientTimelineRepository interface {
+	// FindEntries finds up to limit entries of a client's timeline following the cursor, newest
+	// first. The boolean reports whether more entries follow.
+	FindEntries(ctx context.Context, clientID string, after *Cursor, limit int) ([]*TimelineEntry, bool, error)
+}
@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimelineEntryBefore(t *testing.T) {
+	at := time.Date(2024, 3, 15, 9, 30, 0, 0, time.UTC)
+	cursor := (&TimelineEntry{ID: "b", OccurredAt: at}).Cursor()
+
+	assert.True(t, (&TimelineEntry{ID: "z", OccurredAt: at.Add(-time.Second)}).Before(cursor), "older entries follow")
+	assert.True(t, (&TimelineEntry{ID: "a", OccurredAt: at}).Before(cursor), "ties are broken by ID, descending")
+	assert.False(t, (&TimelineEntry{ID: "b", OccurredAt: at}).Before(cursor), "the entry at the cursor is not repeated")
+	assert.False(t, (&TimelineEntry{ID: "a", OccurredAt: at.Add(time.Second)}).Before(cursor))
+}
@@ -0,0 +1,283 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// CommissionTier applies a commission rate to service revenue above a threshold.
+// Tiers are marginal: each rate only applies to the revenue between its threshold and the next.
+type CommissionTier struct {
+	Threshold decimal.Decimal `json:"threshold"`
+	Rate      decimal.Decimal `json:"rate"` // Percentage, e.g. 40 for 40%
+}
+
+// ServiceCommissionOverride replaces the tiered rate for a specific service
+type ServiceCommissionOverride struct {
+	ServiceID string          `json:"service_id"`
+	Rate      decimal.Decimal `json:"rate"` // Percentage
+}
+
+// StaffCommissionPlan represents a staff member's commission configuration over a period
+type StaffCommissionPlan struct {
+	BaseModel
+	BusinessID       string          `gorm:"not null;type:uuid;index" json:"business_id"`
+	StaffID          string          `gorm:"not null;type:uuid;index" json:"staff_id"`
+	EffectiveFrom    time.Time       `gorm:"not null" json:"effective_from"`
+	EffectiveTo      *time.Time      `gorm:"" json:"effective_to,omitempty"`                             // Nil for the current plan
+	ServiceTiers     *string         `gorm:"type:jsonb;default:'[]'" json:"service_tiers,omitempty"`     // JSON array of CommissionTier
+	ProductRate      decimal.Decimal `gorm:"type:decimal(5,2);not null;default:0" json:"product_rate"`   // Percentage of retail sales
+	ServiceOverrides *string         `gorm:"type:jsonb;default:'[]'" json:"service_overrides,omitempty"` // JSON array of ServiceCommissionOverride
+	Notes            *string         `gorm:"type:text" json:"notes,omitempty"`
+
+	// Relationships
+	Business Business `gorm:"foreignKey:BusinessID;constraint:OnDelete:CASCADE" json:"business"`
+	Staff    Staff    `gorm:"foreignKey:StaffID;constraint:OnDelete:CASCADE" json:"staff"`
+}
+
+// TableName returns the table name for StaffCommissionPlan
+func (StaffCommissionPlan) TableName() string { return "staff_commission_plans" }
+
+// GetServiceTiers decodes the service commission tiers
+func (p *StaffCommissionPlan) GetServiceTiers() ([]CommissionTier, error) {
+	tiers := []CommissionTier{}
+	if p.ServiceTiers == nil || *p.ServiceTiers == "" {
+		return tiers, nil
+	}
+	if err := json.Unmarshal([]byte(*p.ServiceTiers), &tiers); err != nil {
+		return nil, fmt.Errorf("%w: invalid service tiers", ErrValidation)
+	}
+	return tiers, nil
+}
+
+// SetServiceTiers encodes the service commission tiers
+func (p *StaffCommissionPlan) SetServiceTiers(tiers []CommissionTier) error {
+	if tiers == nil {
+		tiers = []CommissionTier{}
+	}
+	data, err := json.Marshal(tiers)
+	if err != nil {
+		return err
+	}
+	encoded := string(data)
+	p.ServiceTiers = &encoded
+	return nil
+}
+
+// GetServiceOverrides decodes the service-specific commission overrides
+func (p *StaffCommissionPlan) GetServiceOverrides() ([]ServiceCommissionOverride, error) {
+	overrides := []ServiceCommissionOverride{}
+	if p.ServiceOverrides == nil || *p.ServiceOverrides == "" {
+		return overrides, nil
+	}
+	if err := json.Unmarshal([]byte(*p.ServiceOverrides), &overrides); err != nil {
+		return nil, fmt.Errorf("%w: invalid service overrides", ErrValidation)
+	}
+	return overrides, nil
+}
+
+// SetServiceOverrides encodes the service-specific commission overrides
+func (p *StaffCommissionPlan) SetServiceOverrides(overrides []ServiceCommissionOverride) error {
+	if overrides == nil {
+		overrides = []ServiceCommissionOverride{}
+	}
+	data, err := json.Marshal(overrides)
+	if err != nil {
+		return err
+	}
+	encoded := string(data)
+	p.ServiceOverrides = &encoded
+	return nil
+}
+
+// IsEffectiveAt returns true if the plan applies at the given time
+func (p *StaffCommissionPlan) IsEffectiveAt(at time.Time) bool {
+	if at.Before(p.EffectiveFrom) {
+		return false
+	}
+	return p.EffectiveTo == nil || at.Before(*p.EffectiveTo)
+}
+
+// Validate validates the staff commission plan model
+func (p *StaffCommissionPlan) Validate() error {
+	if p.BusinessID == "" {
+		return ErrValidation
+	}
+	if p.StaffID == "" {
+		return ErrValidation
+	}
+	if p.EffectiveFrom.IsZero() {
+		return ErrValidation
+	}
+	if p.EffectiveTo != nil && !p.EffectiveTo.After(p.EffectiveFrom) {
+		return fmt.Errorf("%w: effective_to must be after effective_from", ErrValidation)
+	}
+	if !isValidRate(p.ProductRate) {
+		return fmt.Errorf("%w: product rate must be between 0 and 100", ErrValidation)
+	}
+
+	tiers, err := p.GetServiceTiers()
+	if err != nil {
+		return err
+	}
+	for i, tier := range tiers {
+		if !isValidRate(tier.Rate) {
+			return fmt.Errorf("%w: tier rates must be between 0 and 100", ErrValidation)
+		}
+		if i == 0 && !tier.Threshold.IsZero() {
+			return fmt.Errorf("%w: the first tier must start at 0", ErrValidation)
+		}
+		if i > 0 && !tier.Threshold.GreaterThan(tiers[i-1].Threshold) {
+			return fmt.Errorf("%w: tier thresholds must be increasing", ErrValidation)
+		}
+	}
+
+	overrides, err := p.GetServiceOverrides()
+	if err != nil {
+		return err
+	}
+	seen := make(map[string]bool, len(overrides))
+	for _, override := range overrides {
+		if override.ServiceID == "" || seen[override.ServiceID] {
+			return fmt.Errorf("%w: service overrides require a unique service", ErrValidation)
+		}
+		seen[override.ServiceID] = true
+		if !isValidRate(override.Rate) {
+			return fmt.Errorf("%w: override rates must be between 0 and 100", ErrValidation)
+		}
+	}
+	return nil
+}
+
+func isValidRate(rate decimal.Decimal) bool {
+	return !rate.IsNegative() && rate.LessThanOrEqual(decimal.NewFromInt(100))
+}
+
+// CommissionLineKind represents the source of commissionable revenue
+type CommissionLineKind string
+
+const (
+	CommissionLineService CommissionLineKind = "service"
+	CommissionLineProduct CommissionLineKind = "product"
+)
+
+// CommissionRevenueLine is a single commissionable sale attributed to a staff member
+type CommissionRevenueLine struct {
+	Kind          CommissionLineKind `json:"kind"`
+	ServiceID     *string            `json:"service_id,omitempty"`
+	ProductID     *string            `json:"product_id,omitempty"`
+	AppointmentID *string            `json:"appointment_id,omitempty"`
+	Amount        decimal.Decimal    `json:"amount"`
+	OccurredAt    time.Time          `json:"occurred_at"`
+}
+
+// TierCommission is the commission earned within a single tier
+type TierCommission struct {
+	Threshold  decimal.Decimal `json:"threshold"`
+	Rate       decimal.Decimal `json:"rate"`
+	Revenue    decimal.Decimal `json:"revenue"`
+	Commission decimal.Decimal `json:"commission"`
+}
+
+// CommissionBreakdown is the result of applying a commission plan to revenue
+type CommissionBreakdown struct {
+	ServiceRevenue     decimal.Decimal  `json:"service_revenue"`
+	TieredRevenue      decimal.Decimal  `json:"tiered_revenue"`
+	OverrideRevenue    decimal.Decimal  `json:"override_revenue"`
+	ProductRevenue     decimal.Decimal  `json:"product_revenue"`
+	Tiers              []TierCommission `json:"tiers"`
+	TieredCommission   decimal.Decimal  `json:"tiered_commission"`
+	OverrideCommission decimal.Decimal  `json:"override_commission"`
+	ProductCommission  decimal.Decimal  `json:"product_commission"`
+	Total              decimal.Decimal  `json:"total"`
+}
+
+// Add accumulates another breakdown, e.g. when a period spans several plans
+func (b *CommissionBreakdown) Add(other *CommissionBreakdown) {
+	b.ServiceRevenue = b.ServiceRevenue.Add(other.ServiceRevenue)
+	b.TieredRevenue = b.TieredRevenue.Add(other.TieredRevenue)
+	b.OverrideRevenue = b.OverrideRevenue.Add(other.OverrideRevenue)
+	b.ProductRevenue = b.ProductRevenue.Add(other.ProductRevenue)
+	b.Tiers = append(b.Tiers, other.Tiers...)
+	b.TieredCommission = b.TieredCommission.Add(other.TieredCommission)
+	b.OverrideCommission = b.OverrideCommission.Add(other.OverrideCommission)
+	b.ProductCommission = b.ProductCommission.Add(other.ProductCommission)
+	b.Total = b.Total.Add(other.Total)
+}
+
+// Calculate applies the plan to the given revenue lines. Services with an override are paid
+// at the override rate and do not count towards tier thresholds; all other service revenue
+// is paid marginally across the tiers. Amounts are rounded to cents.
+func (p *StaffCommissionPlan) Calculate(lines []CommissionRevenueLine) (*CommissionBreakdown, error) {
+	tiers, err := p.GetServiceTiers()
+	if err != nil {
+		return nil, err
+	}
+	overrides, err := p.GetServiceOverrides()
+	if err != nil {
+		return nil, err
+	}
+
+	overrideRates := make(map[string]decimal.Decimal, len(overrides))
+	for _, override := range overrides {
+		overrideRates[override.ServiceID] = override.Rate
+	}
+
+	hundred := decimal.NewFromInt(100)
+	breakdown := &CommissionBreakdown{Tiers: []TierCommission{}}
+
+	for _, line := range lines {
+		switch line.Kind {
+		case CommissionLineProduct:
+			breakdown.ProductRevenue = breakdown.ProductRevenue.Add(line.Amount)
+		case CommissionLineService:
+			breakdown.ServiceRevenue = breakdown.ServiceRevenue.Add(line.Amount)
+			if line.ServiceID != nil {
+				if rate, ok := overrideRates[*line.ServiceID]; ok {
+					breakdown.OverrideRevenue = breakdown.OverrideRevenue.Add(line.Amount)
+					breakdown.OverrideCommission = breakdown.OverrideCommission.Add(line.Amount.Mul(rate).Div(hundred))
+					continue
+				}
+			}
+			breakdown.TieredRevenue = breakdown.TieredRevenue.Add(line.Amount)
+		}
+	}
+
+	for i, tier := range tiers {
+		if breakdown.TieredRevenue.LessThanOrEqual(tier.Threshold) {
+			break
+		}
+		upper := breakdown.TieredRevenue
+		if i+1 < len(tiers) && tiers[i+1].Threshold.LessThan(upper) {
+			upper = tiers[i+1].Threshold
+		}
+		revenue := upper.Sub(tier.Threshold)
+		commission := revenue.Mul(tier.Rate).Div(hundred).Round(2)
+		breakdown.Tiers = append(breakdown.Tiers, TierCommission{
+			Threshold:  tier.Threshold,
+			Rate:       tier.Rate,
+			Revenue:    revenue,
+			Commission: commission,
+		})
+		breakdown.TieredCommission = breakdown.TieredCommission.Add(commission)
+	}
+
+	breakdown.OverrideCommission = breakdown.OverrideCommission.Round(2)
+	breakdown.ProductCommission = breakdown.ProductRevenue.Mul(p.ProductRate).Div(hundred).Round(2)
+	breakdown.Total = breakdown.TieredCommission.Add(breakdown.OverrideCommission).Add(breakdown.ProductCommission)
+
+	return breakdown, nil
+}
+
+// StaffCommissionPlanRepository defines the repository interface for StaffCommissionPlan
+type StaffCommissionPlanRepository interface {
+	BaseRepository[StaffCommissionPlan]
+	FindByStaffID(ctx context.Context, staffID string) ([]*StaffCommissionPlan, error)
+	FindEffective(ctx context.Context, staffID string, at time.Time) (*StaffCommissionPlan, error)
+	FindInPeriod(ctx context.Context, staffID string, start, end time.Time) ([]*StaffCommissionPlan, error)
+	FindRevenueLines(ctx context.Context, staffID string, start, end time.Time) ([]CommissionRevenueLine, error)
+}
@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCommissionPlan(t *testing.T) *StaffCommissionPlan {
+	t.Helper()
+
+	plan := &StaffCommissionPlan{
+		BusinessID:    "business-1",
+		StaffID:       "staff-1",
+		EffectiveFrom: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		ProductRate:   decimal.NewFromInt(10),
+	}
+	require.NoError(t, plan.SetServiceTiers([]CommissionTier{
+		{Threshold: decimal.Zero, Rate: decimal.NewFromInt(30)},
+		{Threshold: decimal.NewFromInt(1000), Rate: decimal.NewFromInt(40)},
+		{Threshold: decimal.NewFromInt(2000), Rate: decimal.NewFromInt(50)},
+	}))
+	require.NoError(t, plan.SetServiceOverrides([]ServiceCommissionOverride{
+		{ServiceID: "bridal", Rate: decimal.NewFromInt(20)},
+	}))
+	require.NoError(t, plan.Validate())
+	return plan
+}
+
+func serviceLine(serviceID string, amount int64) CommissionRevenueLine {
+	return CommissionRevenueLine{Kind: CommissionLineService, ServiceID: &serviceID, Amount: decimal.NewFromInt(amount)}
+}
+
+func TestStaffCommissionPlan_Calculate(t *testing.T) {
+	plan := newCommissionPlan(t)
+
+	breakdown, err := plan.Calculate([]CommissionRevenueLine{
+		serviceLine("cut", 900),
+		serviceLine("colour", 600),
+		serviceLine("bridal", 500),
+		{Kind: CommissionLineProduct, Amount: decimal.NewFromInt(250)},
+	})
+	require.NoError(t, err)
+
+	// 1000 at 30% + 500 at 40%; bridal is paid at its override and excluded from tiers
+	assert.True(t, decimal.NewFromInt(1500).Equal(breakdown.TieredRevenue))
+	require.Len(t, breakdown.Tiers, 2)
+	assert.True(t, decimal.NewFromInt(300).Equal(breakdown.Tiers[0].Commission))
+	assert.True(t, decimal.NewFromInt(200).Equal(breakdown.Tiers[1].Commission))
+	assert.True(t, decimal.NewFromInt(100).Equal(breakdown.OverrideCommission))
+	assert.True(t, decimal.NewFromInt(25).Equal(breakdown.ProductCommission))
+	assert.True(t, decimal.NewFromInt(625).Equal(breakdown.Total))
+}
+
+func TestStaffCommissionPlan_Validate(t *testing.T) {
+	plan := newCommissionPlan(t)
+
+	require.NoError(t, plan.SetServiceTiers([]CommissionTier{
+		{Threshold: decimal.NewFromInt(100), Rate: decimal.NewFromInt(30)},
+	}))
+	assert.True(t, errors.Is(plan.Validate(), ErrValidation), "first tier must start at 0")
+
+	require.NoError(t, plan.SetServiceTiers([]CommissionTier{
+		{Threshold: decimal.Zero, Rate: decimal.NewFromInt(30)},
+		{Threshold: decimal.Zero, Rate: decimal.NewFromInt(40)},
+	}))
+	assert.True(t, errors.Is(plan.Validate(), ErrValidation), "thresholds must increase")
+
+	require.NoError(t, plan.SetServiceTiers([]CommissionTier{
+		{Threshold: decimal.Zero, Rate: decimal.NewFromInt(130)},
+	}))
+	assert.True(t, errors.Is(plan.Validate(), ErrValidation), "rates are percentages")
+}
+
+func TestStaffCommissionPlan_IsEffectiveAt(t *testing.T) {
+	plan := newCommissionPlan(t)
+	end := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	plan.EffectiveTo = &end
+
+	assert.False(t, plan.IsEffectiveAt(time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC)))
+	assert.True(t, plan.IsEffectiveAt(plan.EffectiveFrom))
+	assert.True(t, plan.IsEffectiveAt(time.Date(2024, 5, 31, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, plan.IsEffectiveAt(end))
+}
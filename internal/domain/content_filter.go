@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"strings"
+	"unicode"
+)
+
+// ContentFilter tells whether text written for the public, such as a review or the business's
+// reply to it, has to be looked at by the business before it is shown
+type ContentFilter interface {
+	Flags(text string) bool
+}
+
+// wordFilter flags text containing any of a list of words
+type wordFilter struct {
+	words map[string]bool
+}
+
+// NewWordFilter creates a content filter flagging text that contains any of the words, matched
+// whole and regardless of case
+func NewWordFilter(words []string) ContentFilter {
+	filter := &wordFilter{words: make(map[string]bool, len(words))}
+	for _, word := range words {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			filter.words[word] = true
+		}
+	}
+	return filter
+}
+
+// Flags reports whether the text contains any of the filter's words
+func (f *wordFilter) Flags(text string) bool {
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	}) {
+		if f.words[word] {
+			return true
+		}
+	}
+	return false
+}
+
+// flags reports whether a filter flags the text, with no filter flagging nothing
+func flags(filter ContentFilter, text string) bool {
+	return filter != nil && filter.Flags(text)
+}
@@ -0,0 +1,17 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWordFilter(t *testing.T) {
+	filter := NewWordFilter([]string{" Scam ", "", "idiot"})
+
+	assert.True(t, filter.Flags("Total scam."))
+	assert.True(t, filter.Flags("The stylist was an IDIOT"))
+	assert.False(t, filter.Flags("Scampi for lunch after my haircut"), "words are matched whole")
+	assert.False(t, filter.Flags("Lovely visit"))
+	assert.False(t, NewWordFilter(nil).Flags("scam"))
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Cursor marks a position in a keyset-paginated list. Lists are ordered by creation time and
+// then ID, so a cursor stays valid when rows are inserted or deleted before it.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// CursorFor returns the cursor pointing at the given entity
+func CursorFor(entity Entity) Cursor {
+	return Cursor{CreatedAt: entity.GetCreatedAt(), ID: entity.GetID()}
+}
+
+// Encode returns the opaque string handed to clients
+func (c Cursor) Encode() string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses a cursor previously returned by Encode
+func DecodeCursor(encoded string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid cursor", ErrValidation)
+	}
+
+	createdAt, id, found := strings.Cut(string(raw), "|")
+	if !found || id == "" {
+		return nil, fmt.Errorf("%w: invalid cursor", ErrValidation)
+	}
+	at, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid cursor", ErrValidation)
+	}
+	return &Cursor{CreatedAt: at, ID: id}, nil
+}
+
+// Edge is an item of a cursor-paginated list together with its cursor
+type Edge[T any] struct {
+	Cursor string `json:"cursor"`
+	Node   *T     `json:"node"`
+}
+
+// PageInfo describes where a page sits in a cursor-paginated list
+type PageInfo struct {
+	HasNextPage     bool    `json:"has_next_page"`
+	HasPreviousPage bool    `json:"has_previous_page"`
+	StartCursor     *string `json:"start_cursor,omitempty"`
+	EndCursor       *string `json:"end_cursor,omitempty"` // Pass as the next page's cursor
+}
+
+// Connection is a page of a cursor-paginated list, shaped after Relay connections
+type Connection[T any] struct {
+	Edges    []*Edge[T] `json:"edges"`
+	PageInfo PageInfo   `json:"page_info"`
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	cursor := Cursor{
+		CreatedAt: time.Date(2024, 3, 15, 9, 30, 12, 123456000, time.FixedZone("WET", 0)),
+		ID:        "7c9e6679-7425-40de-944b-e07fc1f90ae7",
+	}
+
+	decoded, err := DecodeCursor(cursor.Encode())
+	require.NoError(t, err)
+	assert.True(t, cursor.CreatedAt.Equal(decoded.CreatedAt))
+	assert.Equal(t, cursor.ID, decoded.ID)
+}
+
+func TestCursorFor(t *testing.T) {
+	createdAt := time.Date(2024, 3, 15, 9, 30, 0, 0, time.UTC)
+	user := &User{BaseModel: BaseModel{ID: "user-1", CreatedAt: createdAt}}
+
+	cursor := CursorFor(user)
+	assert.Equal(t, "user-1", cursor.ID)
+	assert.Equal(t, createdAt, cursor.CreatedAt)
+}
+
+func TestDecodeCursorRejectsInvalidInput(t *testing.T) {
+	for _, encoded := range []string{
+		"",
+		"not base64!",
+		Cursor{ID: "x"}.Encode()[:4],
+		"bm8tc2VwYXJhdG9y", // "no-separator"
+	} {
+		_, err := DecodeCursor(encoded)
+		assert.True(t, errors.Is(err, ErrValidation), "cursor %q", encoded)
+	}
+}
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// DataRegion is the region a business's data must stay in
+type DataRegion string
+
+const (
+	DataRegionEU DataRegion = "eu" // Data stays in the European Union
+	DataRegionUS DataRegion = "us" // Data is hosted in the United States and may flow to any region
+)
+
+// DefaultDataRegion is the region of businesses that have not chosen one
+const DefaultDataRegion = DataRegionEU
+
+// ErrDataResidency is returned when data would leave the region its business requires
+var ErrDataResidency = errors.New("data residency violation")
+
+// IsValid reports whether the region is supported
+func (r DataRegion) IsValid() bool {
+	return r == DataRegionEU || r == DataRegionUS
+}
+
+// Permits reports whether data of the region may be sent to a destination in another region.
+// EU data may only go to EU destinations; destinations of unknown region are not trusted with it.
+func (r DataRegion) Permits(destination DataRegion) bool {
+	if r == DataRegionEU {
+		return destination == DataRegionEU
+	}
+	return true
+}
+
+// DataDestinationKind is what a data destination is used for
+type DataDestinationKind string
+
+const (
+	DataDestinationStorage   DataDestinationKind = "storage"   // Object storage for files such as photos and exports
+	DataDestinationWarehouse DataDestinationKind = "warehouse" // Analytics warehouse business data is exported to
+	DataDestinationProvider  DataDestinationKind = "provider"  // Third-party API called with business data
+)
+
+// DataDestination is a place outside the database that business data can be sent to
+type DataDestination struct {
+	Name   string              `json:"name"`
+	Kind   DataDestinationKind `json:"kind"`
+	Region DataRegion          `json:"region"` // Empty when the region is unknown
+}
+
+// CheckTransfer returns an ErrDataResidency error when data of the region may not be sent to
+// the destination
+func (r DataRegion) CheckTransfer(destination DataDestination) error {
+	if r.Permits(destination.Region) {
+		return nil
+	}
+	region := string(destination.Region)
+	if region == "" {
+		region = "an unknown region"
+	}
+	return fmt.Errorf("%w: %s data cannot be sent to %s %q in %s", ErrDataResidency, r, destination.Kind, destination.Name, region)
+}
@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataRegion_Permits(t *testing.T) {
+	assert.True(t, DataRegionEU.Permits(DataRegionEU))
+	assert.False(t, DataRegionEU.Permits(DataRegionUS))
+	assert.False(t, DataRegionEU.Permits(""), "EU data does not go to destinations of unknown region")
+	assert.True(t, DataRegionUS.Permits(DataRegionEU))
+	assert.True(t, DataRegionUS.Permits(""))
+}
+
+func TestDataRegion_CheckTransfer(t *testing.T) {
+	err := DataRegionEU.CheckTransfer(DataDestination{Name: "reports", Kind: DataDestinationStorage})
+	assert.ErrorIs(t, err, ErrDataResidency)
+	assert.Contains(t, err.Error(), `storage "reports" in an unknown region`)
+
+	assert.NoError(t, DataRegionEU.CheckTransfer(DataDestination{Name: "reports", Kind: DataDestinationStorage, Region: DataRegionEU}))
+}
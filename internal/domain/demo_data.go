@@ -0,0 +1,194 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// demoService is a service of the standard demo catalogue
+type demoService struct {
+	name     string
+	duration int // Minutes
+	price    string
+}
+
+// demoServices is the standard demo catalogue. Services of the same name the business already
+// has are reused rather than created again.
+var demoServices = []demoService{
+	{"Haircut & Blow-dry", 45, "35.00"},
+	{"Colour & Gloss", 90, "70.00"},
+	{"Gel Manicure", 45, "28.00"},
+	{"Signature Facial", 60, "55.00"},
+}
+
+// demoClients are the clients of the standard demo dataset
+var demoClients = []struct{ firstName, lastName string }{
+	{"Ana", "Ferreira"},
+	{"Beatriz", "Costa"},
+	{"Carla", "Mendes"},
+	{"Diana", "Rocha"},
+	{"Eva", "Martins"},
+	{"Filipa", "Sousa"},
+}
+
+// demoAppointment is an appointment of the standard demo dataset, placed relative to the day
+// the data is reset on
+type demoAppointment struct {
+	day     int // Days from the reset
+	hour    int
+	minute  int
+	client  int // Index into demoClients
+	service int // Index into demoServices
+	status  AppointmentStatus
+}
+
+// demoAppointments fill the two weeks before the reset with history and the week after with
+// upcoming bookings
+var demoAppointments = []demoAppointment{
+	{-13, 10, 0, 0, 0, AppointmentStatusCompleted},
+	{-12, 14, 30, 1, 1, AppointmentStatusCompleted},
+	{-10, 11, 0, 2, 2, AppointmentStatusCompleted},
+	{-9, 16, 0, 3, 3, AppointmentStatusCompleted},
+	{-7, 10, 30, 4, 0, AppointmentStatusCompleted},
+	{-6, 15, 0, 5, 2, AppointmentStatusNoShow},
+	{-5, 12, 0, 0, 3, AppointmentStatusCompleted},
+	{-3, 9, 30, 1, 2, AppointmentStatusCancelled},
+	{-2, 11, 30, 2, 1, AppointmentStatusCompleted},
+	{-1, 17, 0, 3, 0, AppointmentStatusCompleted},
+	{1, 10, 0, 4, 3, AppointmentStatusConfirmed},
+	{1, 14, 0, 5, 0, AppointmentStatusScheduled},
+	{2, 11, 0, 0, 1, AppointmentStatusScheduled},
+	{3, 15, 30, 1, 0, AppointmentStatusConfirmed},
+	{5, 10, 30, 2, 3, AppointmentStatusScheduled},
+	{6, 13, 0, 3, 2, AppointmentStatusScheduled},
+}
+
+// DemoDataset is the standard data a sandbox business is reset to
+type DemoDataset struct {
+	Services     []*Service // Catalogue services the business did not have yet
+	Clients      []*Client
+	Appointments []*Appointment
+	Lines        []*AppointmentLine
+	Completions  []*ServiceCompletion
+}
+
+// NewDemoDataset builds the standard demo dataset of a sandbox business at a time. Appointments
+// are shared out between the staff members in turn, in the business's time zone, and use the
+// business's services of the catalogue's names where it has them. The data is created by the
+// given user.
+func NewDemoDataset(business *Business, staff []*Staff, services []*Service, now time.Time, by *string) (*DemoDataset, error) {
+	if !business.IsSandbox {
+		return nil, fmt.Errorf("%w: only the data of sandbox businesses can be reset", ErrValidation)
+	}
+	if len(staff) == 0 {
+		return nil, fmt.Errorf("%w: a sandbox business needs an active staff member to seed appointments", ErrValidation)
+	}
+	location := time.UTC
+	if loc, err := time.LoadLocation(business.TimeZone); err == nil {
+		location = loc
+	}
+	dataset := &DemoDataset{}
+
+	existing := make(map[string]*Service, len(services))
+	for _, service := range services {
+		existing[strings.ToLower(service.Name)] = service
+	}
+	catalogue := make([]*Service, len(demoServices))
+	for i, demo := range demoServices {
+		if service, ok := existing[strings.ToLower(demo.name)]; ok {
+			catalogue[i] = service
+			continue
+		}
+		catalogue[i] = &Service{
+			BaseModel:  newDemoModel(by),
+			BusinessID: business.ID,
+			Name:       demo.name,
+			Duration:   demo.duration,
+			Price:      decimal.RequireFromString(demo.price),
+			IsActive:   true,
+		}
+		dataset.Services = append(dataset.Services, catalogue[i])
+	}
+
+	for _, demo := range demoClients {
+		phone := fmt.Sprintf("+351 910 000 %03d", len(dataset.Clients)+1)
+		dataset.Clients = append(dataset.Clients, &Client{
+			BaseModel:        newDemoModel(by),
+			BusinessID:       business.ID,
+			FirstName:        demo.firstName,
+			LastName:         demo.lastName,
+			Email:            strings.ToLower(demo.firstName+"."+demo.lastName) + "@example.com",
+			Phone:            &phone,
+			IsActive:         true,
+			AcceptsMarketing: true,
+		})
+	}
+
+	today := now.In(location)
+	for i, demo := range demoAppointments {
+		service := catalogue[demo.service]
+		staffID := staff[i%len(staff)].ID
+		start := time.Date(today.Year(), today.Month(), today.Day()+demo.day, demo.hour, demo.minute, 0, 0, location)
+		price := service.Price
+		appointment := &Appointment{
+			BaseModel:      newDemoModel(by),
+			BusinessID:     business.ID,
+			ClientID:       dataset.Clients[demo.client].ID,
+			StaffID:        staffID,
+			StartTime:      start,
+			EndTime:        start.Add(time.Duration(service.Duration) * time.Minute),
+			Status:         demo.status,
+			EstimatedPrice: &price,
+			TotalPrice:     price,
+		}
+		switch demo.status {
+		case AppointmentStatusCompleted:
+			completedAt := appointment.EndTime
+			appointment.CompletedAt = &completedAt
+			dataset.Completions = append(dataset.Completions, &ServiceCompletion{
+				BaseModel:         newDemoModel(by),
+				AppointmentID:     appointment.ID,
+				PriceCharged:      price,
+				PaymentMethod:     PaymentMethodCard,
+				ProviderConfirmed: true,
+				CompletionDate:    &completedAt,
+				ActualDuration:    &service.Duration,
+			})
+		case AppointmentStatusCancelled:
+			reason := "Client rescheduled by phone"
+			cancelledAt := start.Add(-24 * time.Hour)
+			appointment.CancellationReason = &reason
+			appointment.CancelledAt = &cancelledAt
+		case AppointmentStatusConfirmed:
+			appointment.ClientConfirmed = true
+		}
+		dataset.Appointments = append(dataset.Appointments, appointment)
+		dataset.Lines = append(dataset.Lines, &AppointmentLine{
+			BaseModel:     newDemoModel(by),
+			AppointmentID: appointment.ID,
+			ServiceID:     service.ID,
+			StaffID:       staffID,
+			Duration:      service.Duration,
+			Price:         price,
+		})
+	}
+	return dataset, nil
+}
+
+// newDemoModel returns the base model of a seeded entity. IDs are assigned up front so that the
+// dataset can reference its own entities before it is stored.
+func newDemoModel(by *string) BaseModel {
+	return BaseModel{ID: NewID(), CreatedBy: by, UpdatedBy: by}
+}
+
+// DemoDataRepository defines the interface for resetting the data of sandbox businesses
+type DemoDataRepository interface {
+	// Reset removes the transactional data of a business for good - its clients, appointments,
+	// payments, invoices, messages and events - and seeds a dataset in its place, in a single
+	// transaction. Configuration such as services, staff and settings is kept.
+	Reset(ctx context.Context, businessID string, dataset *DemoDataset) error
+}
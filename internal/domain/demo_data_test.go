@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDemoDataset(t *testing.T) {
+	now := time.Date(2026, time.October, 17, 9, 0, 0, 0, time.UTC)
+	business := &Business{BaseModel: BaseModel{ID: "business-1"}, TimeZone: "Europe/Lisbon", IsSandbox: true}
+	staff := []*Staff{{BaseModel: BaseModel{ID: "staff-1"}}, {BaseModel: BaseModel{ID: "staff-2"}}}
+	haircut := &Service{BaseModel: BaseModel{ID: "service-1"}, Name: "haircut & blow-dry", Duration: 30, Price: decimal.NewFromInt(30)}
+
+	t.Run("seeds the standard dataset", func(t *testing.T) {
+		dataset, err := NewDemoDataset(business, staff, []*Service{haircut}, now, nil)
+		require.NoError(t, err)
+
+		assert.Len(t, dataset.Services, len(demoServices)-1, "existing catalogue services are reused")
+		assert.Len(t, dataset.Clients, len(demoClients))
+		assert.Len(t, dataset.Appointments, len(demoAppointments))
+		assert.Len(t, dataset.Lines, len(demoAppointments))
+
+		completed := 0
+		for i, appointment := range dataset.Appointments {
+			assert.Equal(t, staff[i%len(staff)].ID, appointment.StaffID)
+			switch appointment.Status {
+			case AppointmentStatusCompleted:
+				assert.True(t, appointment.EndTime.Before(now), "completed appointments are history")
+				completed++
+			case AppointmentStatusScheduled, AppointmentStatusConfirmed:
+				assert.True(t, appointment.StartTime.After(now), "booked appointments are upcoming")
+			}
+		}
+		assert.Len(t, dataset.Completions, completed)
+
+		first := dataset.Appointments[0]
+		assert.Equal(t, "10:00", first.StartTime.Format("15:04"), "appointments are placed in the business's time zone")
+		assert.Equal(t, haircut.ID, dataset.Lines[0].ServiceID)
+		assert.Equal(t, 30*time.Minute, first.EndTime.Sub(first.StartTime))
+	})
+
+	t.Run("only resets sandbox businesses", func(t *testing.T) {
+		_, err := NewDemoDataset(&Business{BaseModel: BaseModel{ID: "business-2"}}, staff, nil, now, nil)
+		assert.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("needs a staff member", func(t *testing.T) {
+		_, err := NewDemoDataset(business, nil, nil, now, nil)
+		assert.ErrorIs(t, err, ErrValidation)
+	})
+}
@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"time"
+)
+
+const (
+	DefaultDepositWindowHours = 24  // How long clients have to pay a deposit when the business has no settings
+	MaxDepositWindowHours     = 168 // Longest a business may hold a slot for an unpaid deposit, a week
+)
+
+// Background job kinds following up unpaid deposits
+const (
+	JobDepositReminder = "deposit.remind"
+	JobDepositExpiry   = "deposit.expire"
+)
+
+// depositReminderShares are how far into the payment window each reminder goes out, the last
+// one being the final notice
+var depositReminderShares = []float64{0.5, 0.85}
+
+// DepositReminder is a reminder to pay a deposit
+type DepositReminder struct {
+	Stage int // From 1; the last stage is the final notice
+	At    time.Time
+}
+
+// DepositDeadline is when an appointment booked with a deposit is released unless the deposit
+// is paid, and when the client is reminded beforehand
+type DepositDeadline struct {
+	ExpiresAt time.Time
+	Reminders []DepositReminder
+}
+
+// DepositJobPayload identifies the appointment a deposit job follows up
+type DepositJobPayload struct {
+	AppointmentID string     `json:"appointment_id"`
+	Stage         int        `json:"stage,omitempty"`      // Reminders only
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"` // Reminders only
+}
+
+// DepositReminderStages returns how many reminders clients get before the deadline
+func DepositReminderStages() int {
+	return len(depositReminderShares)
+}
+
+// DepositWindowHours returns how long the business gives clients to pay a deposit after
+// booking; 0 leaves unpaid appointments booked
+func DepositWindowHours(settings *BusinessSettings) int {
+	if settings == nil {
+		return DefaultDepositWindowHours
+	}
+	return settings.DepositWindowHours
+}
+
+// NewDepositDeadline returns the deadline for paying the deposit of an appointment booked at a
+// time: the end of the payment window, or the start of the appointment if it comes first.
+// Reminders are spread over the window, escalating to a final notice. There is no deadline
+// when the window is 0.
+func NewDepositDeadline(bookedAt, startTime time.Time, windowHours int) (*DepositDeadline, bool) {
+	if windowHours <= 0 {
+		return nil, false
+	}
+	expiresAt := bookedAt.Add(time.Duration(windowHours) * time.Hour)
+	if startTime.Before(expiresAt) {
+		expiresAt = startTime
+	}
+
+	deadline := &DepositDeadline{ExpiresAt: expiresAt}
+	window := expiresAt.Sub(bookedAt)
+	for i, share := range depositReminderShares {
+		if window <= 0 {
+			break
+		}
+		deadline.Reminders = append(deadline.Reminders, DepositReminder{
+			Stage: i + 1,
+			At:    bookedAt.Add(time.Duration(float64(window) * share)),
+		})
+	}
+	return deadline, true
+}
+
+// DepositPaid reports whether a deposit among an appointment's payments was taken
+func DepositPaid(payments []*Payment) bool {
+	for _, payment := range payments {
+		if payment.Kind == PaymentKindDeposit && (payment.IsPaid() || payment.Status == PaymentStatusRefunded) {
+			return true
+		}
+	}
+	return false
+}
+
+// DepositInProgress reports whether a deposit among an appointment's payments may still go through
+func DepositInProgress(payments []*Payment) bool {
+	for _, payment := range payments {
+		if payment.Kind == PaymentKindDeposit && payment.IsOpen() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDepositDeadline(t *testing.T) {
+	bookedAt := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+
+	t.Run("spreads reminders over the window", func(t *testing.T) {
+		deadline, ok := NewDepositDeadline(bookedAt, bookedAt.Add(7*24*time.Hour), 24)
+		require.True(t, ok)
+		assert.Equal(t, bookedAt.Add(24*time.Hour), deadline.ExpiresAt)
+		assert.Equal(t, []DepositReminder{
+			{Stage: 1, At: bookedAt.Add(12 * time.Hour)},
+			{Stage: 2, At: bookedAt.Add(time.Duration(0.85 * float64(24*time.Hour)))},
+		}, deadline.Reminders)
+		assert.Len(t, deadline.Reminders, DepositReminderStages())
+	})
+
+	t.Run("expires at the start of appointments starting sooner", func(t *testing.T) {
+		start := bookedAt.Add(4 * time.Hour)
+		deadline, ok := NewDepositDeadline(bookedAt, start, 24)
+		require.True(t, ok)
+		assert.Equal(t, start, deadline.ExpiresAt)
+		assert.Equal(t, bookedAt.Add(2*time.Hour), deadline.Reminders[0].At)
+	})
+
+	t.Run("leaves out reminders for appointments already started", func(t *testing.T) {
+		deadline, ok := NewDepositDeadline(bookedAt, bookedAt.Add(-time.Hour), 24)
+		require.True(t, ok)
+		assert.Empty(t, deadline.Reminders)
+	})
+
+	t.Run("keeps unpaid appointments booked without a window", func(t *testing.T) {
+		_, ok := NewDepositDeadline(bookedAt, bookedAt.Add(48*time.Hour), 0)
+		assert.False(t, ok)
+	})
+}
+
+func TestDepositWindowHours(t *testing.T) {
+	assert.Equal(t, DefaultDepositWindowHours, DepositWindowHours(nil))
+	assert.Equal(t, 0, DepositWindowHours(&BusinessSettings{DepositWindowHours: 0}))
+	assert.Equal(t, 48, DepositWindowHours(&BusinessSettings{DepositWindowHours: 48}))
+}
+
+func TestDepositPaid(t *testing.T) {
+	deposit := func(status PaymentStatus) *Payment { return &Payment{Kind: PaymentKindDeposit, Status: status} }
+
+	assert.False(t, DepositPaid(nil))
+	assert.False(t, DepositPaid([]*Payment{deposit(PaymentStatusFailed)}))
+	assert.False(t, DepositPaid([]*Payment{{Kind: PaymentKindBalance, Status: PaymentStatusSucceeded}}))
+	assert.True(t, DepositPaid([]*Payment{deposit(PaymentStatusFailed), deposit(PaymentStatusSucceeded)}))
+	assert.True(t, DepositPaid([]*Payment{deposit(PaymentStatusRefunded)}), "a refund is the business's call")
+
+	assert.True(t, DepositInProgress([]*Payment{deposit(PaymentStatusRequiresAction)}))
+	assert.False(t, DepositInProgress([]*Payment{deposit(PaymentStatusFailed)}))
+}
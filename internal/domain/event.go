@@ -0,0 +1,176 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Aggregate types recorded in the event log
+const (
+	AggregateAppointment = "appointment"
+	AggregateClient      = "client"
+	AggregateStaff       = "staff"
+)
+
+// Event types recorded in the event log
+const (
+	EventAppointmentCreated   = "appointment.created"
+	EventAppointmentUpdated   = "appointment.updated"
+	EventAppointmentCancelled = "appointment.cancelled"
+	EventAppointmentDeleted   = "appointment.deleted"
+	EventAppointmentCompleted = "appointment.completed"
+	EventClientCreated        = "client.created"
+	EventClientUpdated        = "client.updated"
+	EventStaffUpdated         = "staff.updated"
+)
+
+// MaxEventPublishAttempts is how many times the outbox relay tries to publish an event before
+// abandoning it, leaving it to be replayed once the failing subscriber is fixed
+const MaxEventPublishAttempts = 10
+
+const (
+	eventPublishRetryBaseDelay = 10 * time.Second
+	eventPublishRetryMaxDelay  = time.Hour
+)
+
+// DomainEvent is an entry in the append-only event log. Events are written to the log in the
+// transaction of the changes they describe, and the log doubles as the outbox the relay
+// publishes them from.
+type DomainEvent struct {
+	BaseModel
+	BusinessID      *string    `gorm:"type:uuid;index" json:"business_id,omitempty"`
+	AggregateType   string     `gorm:"not null;size:50" json:"aggregate_type"`
+	AggregateID     string     `gorm:"not null;type:uuid" json:"aggregate_id"`
+	EventType       string     `gorm:"not null;size:100" json:"event_type"`
+	Payload         *string    `gorm:"type:jsonb;default:'{}'" json:"payload,omitempty"`
+	OccurredAt      time.Time  `gorm:"not null" json:"occurred_at"`
+	ReplayCount     int        `gorm:"not null;default:0" json:"replay_count"`
+	LastReplayedAt  *time.Time `gorm:"" json:"last_replayed_at,omitempty"`
+	PublishedAt     *time.Time `gorm:"" json:"published_at,omitempty"` // When every subscriber took the event
+	PublishAttempts int        `gorm:"not null;default:0" json:"publish_attempts"`
+	NextPublishAt   *time.Time `gorm:"" json:"next_publish_at,omitempty"` // Not published before; also leases the event to a relay
+	PublishError    *string    `gorm:"type:text" json:"publish_error,omitempty"`
+	AbandonedAt     *time.Time `gorm:"" json:"abandoned_at,omitempty"` // When the relay gave up on the event
+}
+
+// TableName returns the table name for DomainEvent
+func (DomainEvent) TableName() string { return "domain_events" }
+
+// NewDomainEvent creates an event for an aggregate with an encoded payload
+func NewDomainEvent(aggregateType, aggregateID, eventType string, businessID *string, payload any) (*DomainEvent, error) {
+	event := &DomainEvent{
+		BusinessID:    businessID,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		EventType:     eventType,
+		OccurredAt:    time.Now(),
+	}
+	if err := event.SetPayload(payload); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// GetPayload decodes the event payload into target
+func (e *DomainEvent) GetPayload(target any) error {
+	if e.Payload == nil || *e.Payload == "" {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(*e.Payload), target); err != nil {
+		return fmt.Errorf("%w: invalid event payload", ErrValidation)
+	}
+	return nil
+}
+
+// SetPayload encodes the event payload
+func (e *DomainEvent) SetPayload(payload any) error {
+	if payload == nil {
+		payload = map[string]any{}
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	encoded := string(data)
+	e.Payload = &encoded
+	return nil
+}
+
+// Validate validates the domain event model
+func (e *DomainEvent) Validate() error {
+	if e.AggregateType == "" || e.AggregateID == "" {
+		return fmt.Errorf("%w: events require an aggregate", ErrValidation)
+	}
+	if e.EventType == "" {
+		return fmt.Errorf("%w: events require a type", ErrValidation)
+	}
+	if e.OccurredAt.IsZero() {
+		return ErrValidation
+	}
+	return nil
+}
+
+// IsUnpublished reports whether the relay still has to publish the event
+func (e *DomainEvent) IsUnpublished() bool {
+	return e.PublishedAt == nil && e.AbandonedAt == nil
+}
+
+// MarkPublished records that every subscriber took the event
+func (e *DomainEvent) MarkPublished(now time.Time) {
+	e.PublishAttempts++
+	e.PublishedAt = &now
+	e.NextPublishAt = nil
+	e.PublishError = nil
+}
+
+// RecordPublishFailure records a failed attempt to publish the event and schedules the next
+// one, abandoning the event once it has used up its attempts
+func (e *DomainEvent) RecordPublishFailure(err error, now time.Time) {
+	e.PublishAttempts++
+	message := err.Error()
+	e.PublishError = &message
+	if e.PublishAttempts >= MaxEventPublishAttempts {
+		e.AbandonedAt = &now
+		e.NextPublishAt = nil
+		return
+	}
+	next := now.Add(EventPublishRetryDelay(e.PublishAttempts))
+	e.NextPublishAt = &next
+}
+
+// EventPublishRetryDelay returns the delay after a number of failed attempts to publish an
+// event, doubling from ten seconds up to an hour
+func EventPublishRetryDelay(attempts int) time.Duration {
+	delay := eventPublishRetryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= eventPublishRetryMaxDelay {
+			return eventPublishRetryMaxDelay
+		}
+	}
+	return delay
+}
+
+// DomainEventFilter represents the criteria for browsing the event log
+type DomainEventFilter struct {
+	BusinessID    *string    `json:"business_id,omitempty"`
+	AggregateType *string    `json:"aggregate_type,omitempty"`
+	AggregateID   *string    `json:"aggregate_id,omitempty"`
+	EventType     *string    `json:"event_type,omitempty"`
+	DateRange     *DateRange `json:"date_range,omitempty"`
+}
+
+// DomainEventRepository defines the repository interface for DomainEvent
+type DomainEventRepository interface {
+	BaseRepository[DomainEvent]
+	Search(ctx context.Context, filter DomainEventFilter, page, pageSize int) ([]*DomainEvent, int64, error)
+	FindByIDs(ctx context.Context, ids []string) ([]*DomainEvent, error)
+	MarkReplayed(ctx context.Context, ids []string, at time.Time) error
+	// ClaimUnpublished leases up to limit unpublished events that are due to the caller, in
+	// the order they occurred, so that concurrent relays do not publish the same events. The
+	// lease ends at now plus lease, when the events are due again unless their outcome was
+	// saved.
+	ClaimUnpublished(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*DomainEvent, error)
+}
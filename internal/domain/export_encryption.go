@@ -0,0 +1,254 @@
+package domain
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+// ExportEncryptionMethod is how an export is encrypted
+type ExportEncryptionMethod string
+
+const (
+	ExportEncryptionNone       ExportEncryptionMethod = "none"
+	ExportEncryptionPublicKey  ExportEncryptionMethod = "public_key" // For the holder of the private key of an RSA key
+	ExportEncryptionPassphrase ExportEncryptionMethod = "passphrase" // For whoever knows the passphrase
+)
+
+const (
+	// MinExportKeyBits is the smallest RSA key exports are encrypted for
+	MinExportKeyBits = 2048
+	// MinExportPassphraseLength is the shortest passphrase exports are encrypted with
+	MinExportPassphraseLength = 12
+	// ExportPassphraseIterations is the PBKDF2-SHA256 work factor deriving keys from passphrases
+	ExportPassphraseIterations = 600_000
+	// EncryptedExportExtension is appended to the file name of encrypted exports
+	EncryptedExportExtension = ".enc"
+
+	// maxExportPassphraseIterations bounds the work decrypting an export may ask for
+	maxExportPassphraseIterations = 10_000_000
+)
+
+// encryptedExportMagic starts every encrypted export, followed by the format version
+var encryptedExportMagic = []byte("BTXE")
+
+const encryptedExportVersion = 1
+
+// Method identifiers in encrypted exports
+const (
+	exportMethodPublicKey  byte = 1
+	exportMethodPassphrase byte = 2
+)
+
+// ErrExportDecryption is returned when an encrypted export cannot be opened with the key or
+// passphrase given, or was tampered with
+var ErrExportDecryption = errors.New("cannot decrypt export")
+
+// ExportEncrypter encrypts exports, so files left in object storage or downloaded by operators
+// are readable only by the business. An encrypted export is:
+//
+//	"BTXE", version 1, method (1 public key, 2 passphrase)
+//	public key: uint16 length and the AES key wrapped with RSA-OAEP-SHA256
+//	passphrase: 16-byte salt and uint32 PBKDF2-SHA256 iterations deriving the AES key
+//	12-byte nonce, then the export sealed with AES-256-GCM, authenticating all of the above
+//
+// Integers are big-endian. DecryptExportWithKey and DecryptExportWithPassphrase open them.
+type ExportEncrypter struct {
+	method     ExportEncryptionMethod
+	publicKey  *rsa.PublicKey
+	passphrase string
+	// Fingerprint identifies the public key exports are encrypted for, as "SHA256:" and the
+	// base64 SHA-256 of its DER encoding. Passphrases have none: a fingerprint would let them
+	// be guessed offline.
+	Fingerprint string
+}
+
+// NewPublicKeyEncrypter returns an encrypter for the holder of an RSA key, given as a PEM
+// "PUBLIC KEY" or "RSA PUBLIC KEY" of at least MinExportKeyBits
+func NewPublicKeyEncrypter(publicKeyPEM string) (*ExportEncrypter, error) {
+	block, _ := pem.Decode([]byte(publicKeyPEM))
+	if block == nil {
+		return nil, fmt.Errorf("%w: public_key must be a PEM encoded public key", ErrValidation)
+	}
+	var parsed any
+	var err error
+	switch block.Type {
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("%w: public_key must be a PEM encoded public key, not %s", ErrValidation, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid public_key: %v", ErrValidation, err)
+	}
+	key, ok := parsed.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: public_key must be an RSA key", ErrValidation)
+	}
+	if key.N.BitLen() < MinExportKeyBits {
+		return nil, fmt.Errorf("%w: public_key must be at least %d bits", ErrValidation, MinExportKeyBits)
+	}
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid public_key: %v", ErrValidation, err)
+	}
+	sum := sha256.Sum256(der)
+	return &ExportEncrypter{
+		method:      ExportEncryptionPublicKey,
+		publicKey:   key,
+		Fingerprint: "SHA256:" + base64.RawStdEncoding.EncodeToString(sum[:]),
+	}, nil
+}
+
+// NewPassphraseEncrypter returns an encrypter for whoever knows a passphrase of at least
+// MinExportPassphraseLength characters
+func NewPassphraseEncrypter(passphrase string) (*ExportEncrypter, error) {
+	if len([]rune(passphrase)) < MinExportPassphraseLength {
+		return nil, fmt.Errorf("%w: passphrase must be at least %d characters", ErrValidation, MinExportPassphraseLength)
+	}
+	return &ExportEncrypter{method: ExportEncryptionPassphrase, passphrase: passphrase}, nil
+}
+
+// Method returns how the encrypter encrypts exports
+func (e *ExportEncrypter) Method() ExportEncryptionMethod {
+	return e.method
+}
+
+// Encrypt encrypts an export with a fresh key
+func (e *ExportEncrypter) Encrypt(plaintext []byte) ([]byte, error) {
+	header := bytes.NewBuffer(append([]byte{}, encryptedExportMagic...))
+	header.WriteByte(encryptedExportVersion)
+
+	var key []byte
+	switch e.method {
+	case ExportEncryptionPublicKey:
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+		wrapped, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, e.publicKey, key, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to wrap export key: %w", err)
+		}
+		header.WriteByte(exportMethodPublicKey)
+		_ = binary.Write(header, binary.BigEndian, uint16(len(wrapped)))
+		header.Write(wrapped)
+	case ExportEncryptionPassphrase:
+		salt := make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, err
+		}
+		var err error
+		if key, err = pbkdf2.Key(sha256.New, e.passphrase, salt, ExportPassphraseIterations, 32); err != nil {
+			return nil, fmt.Errorf("failed to derive export key: %w", err)
+		}
+		header.WriteByte(exportMethodPassphrase)
+		header.Write(salt)
+		_ = binary.Write(header, binary.BigEndian, uint32(ExportPassphraseIterations))
+	default:
+		return nil, fmt.Errorf("unknown export encryption method %q", e.method)
+	}
+
+	gcm, err := newExportCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	additional := header.Bytes()
+	out := append(append([]byte{}, additional...), nonce...)
+	return gcm.Seal(out, nonce, plaintext, additional), nil
+}
+
+// DecryptExportWithKey opens an export encrypted for the public key of an RSA private key
+func DecryptExportWithKey(data []byte, privateKey *rsa.PrivateKey) ([]byte, error) {
+	method, rest, err := readExportHeader(data)
+	if err != nil {
+		return nil, err
+	}
+	if method != exportMethodPublicKey || len(rest) < 2 {
+		return nil, fmt.Errorf("%w: not encrypted for a public key", ErrExportDecryption)
+	}
+	size := int(binary.BigEndian.Uint16(rest))
+	if len(rest) < 2+size {
+		return nil, fmt.Errorf("%w: truncated", ErrExportDecryption)
+	}
+	key, err := rsa.DecryptOAEP(sha256.New(), nil, privateKey, rest[2:2+size], nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: wrong key", ErrExportDecryption)
+	}
+	return openExport(data, len(data)-len(rest)+2+size, key)
+}
+
+// DecryptExportWithPassphrase opens an export encrypted with a passphrase
+func DecryptExportWithPassphrase(data []byte, passphrase string) ([]byte, error) {
+	method, rest, err := readExportHeader(data)
+	if err != nil {
+		return nil, err
+	}
+	if method != exportMethodPassphrase || len(rest) < 20 {
+		return nil, fmt.Errorf("%w: not encrypted with a passphrase", ErrExportDecryption)
+	}
+	iterations := binary.BigEndian.Uint32(rest[16:20])
+	if iterations == 0 || iterations > maxExportPassphraseIterations {
+		return nil, fmt.Errorf("%w: unsupported key derivation", ErrExportDecryption)
+	}
+	key, err := pbkdf2.Key(sha256.New, passphrase, rest[:16], int(iterations), 32)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrExportDecryption, err)
+	}
+	return openExport(data, len(data)-len(rest)+20, key)
+}
+
+// readExportHeader checks the start of an encrypted export, returning its method and the bytes
+// after it
+func readExportHeader(data []byte) (byte, []byte, error) {
+	prefix := len(encryptedExportMagic)
+	if len(data) < prefix+2 || !bytes.Equal(data[:prefix], encryptedExportMagic) {
+		return 0, nil, fmt.Errorf("%w: not an encrypted export", ErrExportDecryption)
+	}
+	if data[prefix] != encryptedExportVersion {
+		return 0, nil, fmt.Errorf("%w: unsupported version %d", ErrExportDecryption, data[prefix])
+	}
+	return data[prefix+1], data[prefix+2:], nil
+}
+
+// openExport decrypts the sealed export following a header of the given length
+func openExport(data []byte, headerLength int, key []byte) ([]byte, error) {
+	gcm, err := newExportCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < headerLength+gcm.NonceSize() {
+		return nil, fmt.Errorf("%w: truncated", ErrExportDecryption)
+	}
+	nonce := data[headerLength : headerLength+gcm.NonceSize()]
+	plaintext, err := gcm.Open(nil, nonce, data[headerLength+gcm.NonceSize():], data[:headerLength])
+	if err != nil {
+		return nil, fmt.Errorf("%w: wrong key or tampered with", ErrExportDecryption)
+	}
+	return plaintext, nil
+}
+
+// newExportCipher returns the AES-256-GCM cipher of an export key
+func newExportCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func exportKeyPEM(t *testing.T, bits int) (*rsa.PrivateKey, string) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, bits)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	return key, string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+func TestExportEncrypter_PublicKey(t *testing.T) {
+	key, publicKey := exportKeyPEM(t, 2048)
+	encrypter, err := NewPublicKeyEncrypter(publicKey)
+	require.NoError(t, err)
+	assert.Equal(t, ExportEncryptionPublicKey, encrypter.Method())
+	assert.True(t, strings.HasPrefix(encrypter.Fingerprint, "SHA256:"))
+
+	pkcs1 := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(&key.PublicKey)}))
+	same, err := NewPublicKeyEncrypter(pkcs1)
+	require.NoError(t, err)
+	assert.Equal(t, encrypter.Fingerprint, same.Fingerprint, "the fingerprint does not depend on the PEM flavour")
+
+	plaintext := []byte(`{"profile": {"notes": "allergic to latex"}}`)
+	sealed, err := encrypter.Encrypt(plaintext)
+	require.NoError(t, err)
+	assert.NotContains(t, string(sealed), "latex")
+
+	opened, err := DecryptExportWithKey(sealed, key)
+	require.NoError(t, err)
+	assert.Equal(t, plaintext, opened)
+
+	other, _ := exportKeyPEM(t, 2048)
+	_, err = DecryptExportWithKey(sealed, other)
+	assert.ErrorIs(t, err, ErrExportDecryption)
+
+	sealed[len(sealed)-1] ^= 1
+	_, err = DecryptExportWithKey(sealed, key)
+	assert.ErrorIs(t, err, ErrExportDecryption, "tampering is detected")
+}
+
+func TestExportEncrypter_Passphrase(t *testing.T) {
+	encrypter, err := NewPassphraseEncrypter("correct horse battery")
+	require.NoError(t, err)
+	assert.Empty(t, encrypter.Fingerprint)
+
+	plaintext := []byte("client data")
+	sealed, err := encrypter.Encrypt(plaintext)
+	require.NoError(t, err)
+
+	opened, err := DecryptExportWithPassphrase(sealed, "correct horse battery")
+	require.NoError(t, err)
+	assert.Equal(t, plaintext, opened)
+
+	_, err = DecryptExportWithPassphrase(sealed, "wrong horse battery")
+	assert.ErrorIs(t, err, ErrExportDecryption)
+	key, _ := exportKeyPEM(t, 2048)
+	_, err = DecryptExportWithKey(sealed, key)
+	assert.ErrorIs(t, err, ErrExportDecryption, "a passphrase export needs the passphrase")
+}
+
+func TestExportEncrypter_Validation(t *testing.T) {
+	_, small := exportKeyPEM(t, 1024)
+	for _, publicKey := range []string{"", "not a key", small} {
+		_, err := NewPublicKeyEncrypter(publicKey)
+		assert.True(t, errors.Is(err, ErrValidation), publicKey)
+	}
+	_, err := NewPassphraseEncrypter("too short")
+	assert.True(t, errors.Is(err, ErrValidation))
+
+	_, err = DecryptExportWithPassphrase([]byte("plain json"), "correct horse battery")
+	assert.ErrorIs(t, err, ErrExportDecryption)
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// FrontDeskTaskKind represents what the front desk is asked to do
+type FrontDeskTaskKind string
+
+const (
+	// FrontDeskTaskCallClient asks the front desk to call a client who did not acknowledge any
+	// of the confirmation requests sent about an appointment
+	FrontDeskTaskCallClient FrontDeskTaskKind = "call_client"
+)
+
+// CallClientTaskConfirmedOutcome is the outcome of the tasks to call a client the app closes once
+// the appointment is confirmed some other way
+const CallClientTaskConfirmedOutcome = "The appointment was confirmed"
+
+// FrontDeskTaskStatus represents the state of a front desk task
+type FrontDeskTaskStatus string
+
+const (
+	FrontDeskTaskOpen FrontDeskTaskStatus = "open"
+	FrontDeskTaskDone FrontDeskTaskStatus = "done"
+)
+
+// FrontDeskTask is a to-do for the front desk of a business, raised by the app
+type FrontDeskTask struct {
+	BaseModel
+	BusinessID    string              `gorm:"not null;type:uuid;index" json:"business_id"`
+	AppointmentID *string             `gorm:"type:uuid;index" json:"appointment_id,omitempty"`
+	ClientID      *string             `gorm:"type:uuid" json:"client_id,omitempty"`
+	Kind          FrontDeskTaskKind   `gorm:"not null;size:30" json:"kind"`
+	Status        FrontDeskTaskStatus `gorm:"not null;size:20;default:'open'" json:"status"`
+	Reason        string              `gorm:"not null;type:text" json:"reason"` // Why the task was raised
+	DueAt         time.Time           `gorm:"not null" json:"due_at"`
+	CompletedAt   *time.Time          `json:"completed_at,omitempty"`
+	CompletedBy   *string             `gorm:"type:uuid" json:"completed_by,omitempty"` // Nil when the app closed the task
+	Outcome       *string             `gorm:"type:text" json:"outcome,omitempty"`
+}
+
+// TableName returns the table name for FrontDeskTask
+func (FrontDeskTask) TableName() string { return "front_desk_tasks" }
+
+// IsOpen reports whether the task is still to be done
+func (t *FrontDeskTask) IsOpen() bool {
+	return t.Status == FrontDeskTaskOpen
+}
+
+// Complete marks the task done by a user, or by the app when by is nil
+func (t *FrontDeskTask) Complete(outcome *string, by *string, at time.Time) {
+	t.Status = FrontDeskTaskDone
+	t.Outcome = outcome
+	t.CompletedBy = by
+	t.CompletedAt = &at
+}
+
+// FrontDeskTaskRepository defines the repository interface for FrontDeskTask
+type FrontDeskTaskRepository interface {
+	BaseRepository[FrontDeskTask]
+	// FindByBusiness finds the tasks of a business with a status, or every status when nil,
+	// soonest due first
+	FindByBusiness(ctx context.Context, businessID string, status *FrontDeskTaskStatus) ([]*FrontDeskTask, error)
+	// FindByAppointment finds the tasks raised about an appointment, oldest first
+	FindByAppointment(ctx context.Context, appointmentID string) ([]*FrontDeskTask, error)
+}
@@ -0,0 +1,168 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// PaymentMethodGiftCard is the payment method of payments and completions paid with a gift card
+const PaymentMethodGiftCard = "gift_card"
+
+// giftCardCodeAlphabet leaves out letters and digits that are easily confused, such as O and 0
+const giftCardCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// giftCardCodeLength is the number of characters of a gift card code, without the dashes
+const giftCardCodeLength = 12
+
+// ErrGiftCardBalanceChanged is returned when a redemption would take a card below zero because
+// it was redeemed concurrently
+var ErrGiftCardBalanceChanged = errors.New("the gift card balance changed, please try again")
+
+// GiftCardTransactionKind tells how a transaction changed a gift card's balance
+type GiftCardTransactionKind string
+
+const (
+	GiftCardTransactionIssue      GiftCardTransactionKind = "issue"      // The card was sold with its initial value
+	GiftCardTransactionRedemption GiftCardTransactionKind = "redemption" // Part of the balance paid for an appointment
+	GiftCardTransactionRefund     GiftCardTransactionKind = "refund"     // A redemption was refunded back onto the card
+)
+
+// GiftCard is a prepaid voucher sold by a business and redeemed, in one go or in parts,
+// against its appointments
+type GiftCard struct {
+	BaseModel
+	BusinessID        string          `gorm:"not null;type:uuid;index" json:"business_id"`
+	Code              string          `gorm:"not null;size:20;uniqueIndex" json:"code"` // Grouped in fours, e.g. ABCD-EFGH-JKLM
+	InitialValue      decimal.Decimal `gorm:"type:decimal(10,2);not null" json:"initial_value"`
+	Balance           decimal.Decimal `gorm:"type:decimal(10,2);not null" json:"balance"`
+	Currency          string          `gorm:"not null;size:3" json:"currency"`
+	ExpiresAt         *time.Time      `gorm:"" json:"expires_at,omitempty"`
+	PurchaserClientID *string         `gorm:"type:uuid" json:"purchaser_client_id,omitempty"`
+	RecipientName     *string         `gorm:"size:200" json:"recipient_name,omitempty"`
+	RecipientEmail    *string         `gorm:"size:255" json:"recipient_email,omitempty"`
+	Message           *string         `gorm:"type:text" json:"message,omitempty"`
+	SalePaymentMethod string          `gorm:"not null;size:20" json:"sale_payment_method"` // How the card itself was paid for, e.g. cash
+}
+
+// TableName returns the table name for GiftCard
+func (GiftCard) TableName() string { return "gift_cards" }
+
+// GiftCardTransaction records a change to a gift card's balance
+type GiftCardTransaction struct {
+	BaseModel
+	BusinessID    string                  `gorm:"not null;type:uuid" json:"business_id"`
+	GiftCardID    string                  `gorm:"not null;type:uuid;index" json:"gift_card_id"`
+	Kind          GiftCardTransactionKind `gorm:"not null;size:20" json:"kind"`
+	Amount        decimal.Decimal         `gorm:"type:decimal(10,2);not null" json:"amount"` // Always positive; the kind gives the direction
+	BalanceAfter  decimal.Decimal         `gorm:"type:decimal(10,2);not null" json:"balance_after"`
+	PaymentID     *string                 `gorm:"type:uuid" json:"payment_id,omitempty"`
+	AppointmentID *string                 `gorm:"type:uuid" json:"appointment_id,omitempty"`
+}
+
+// TableName returns the table name for GiftCardTransaction
+func (GiftCardTransaction) TableName() string { return "gift_card_transactions" }
+
+// NewGiftCardCode generates a random gift card code, grouped in fours for reading out
+func NewGiftCardCode() (string, error) {
+	buf := make([]byte, giftCardCodeLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate gift card code: %w", err)
+	}
+	code := make([]byte, len(buf))
+	for i, b := range buf {
+		code[i] = giftCardCodeAlphabet[int(b)%len(giftCardCodeAlphabet)]
+	}
+	return NormalizeGiftCardCode(string(code)), nil
+}
+
+// NormalizeGiftCardCode brings a code as typed at the till to its stored form, so
+// "abcd efgh jklm" finds ABCD-EFGH-JKLM
+func NormalizeGiftCardCode(code string) string {
+	var compact strings.Builder
+	for _, r := range strings.ToUpper(code) {
+		if r != '-' && r != ' ' {
+			compact.WriteRune(r)
+		}
+	}
+	var grouped strings.Builder
+	for i, r := range compact.String() {
+		if i > 0 && i%4 == 0 {
+			grouped.WriteByte('-')
+		}
+		grouped.WriteRune(r)
+	}
+	return grouped.String()
+}
+
+// Validate validates the gift card model
+func (g *GiftCard) Validate() error {
+	if g.BusinessID == "" || g.Code == "" || g.SalePaymentMethod == "" {
+		return ErrValidation
+	}
+	if !g.InitialValue.IsPositive() {
+		return fmt.Errorf("%w: the value must be positive", ErrValidation)
+	}
+	if g.Balance.IsNegative() || g.Balance.GreaterThan(g.InitialValue) {
+		return fmt.Errorf("%w: the balance must be between zero and the value", ErrValidation)
+	}
+	if len(g.Currency) != 3 {
+		return fmt.Errorf("%w: the currency must be a three-letter code", ErrValidation)
+	}
+	return nil
+}
+
+// IsExpired reports whether the card can no longer be redeemed because of its expiry date
+func (g *GiftCard) IsExpired(now time.Time) bool {
+	return g.ExpiresAt != nil && !now.Before(*g.ExpiresAt)
+}
+
+// IsRedeemable reports whether the card has a balance left and has not expired
+func (g *GiftCard) IsRedeemable(now time.Time) bool {
+	return g.Balance.IsPositive() && !g.IsExpired(now)
+}
+
+// RedemptionAmount works out how much of the card goes towards a bill with the given amount
+// due: the amount requested, or as much as the balance covers
+func (g *GiftCard) RedemptionAmount(due decimal.Decimal, requested *decimal.Decimal, now time.Time) (decimal.Decimal, error) {
+	if g.IsExpired(now) {
+		return decimal.Zero, fmt.Errorf("%w: the gift card expired on %s", ErrValidation, g.ExpiresAt.Format("2006-01-02"))
+	}
+	if !g.Balance.IsPositive() {
+		return decimal.Zero, fmt.Errorf("%w: the gift card has no balance left", ErrValidation)
+	}
+	if requested == nil {
+		return decimal.Min(g.Balance, due), nil
+	}
+	if !requested.IsPositive() {
+		return decimal.Zero, fmt.Errorf("%w: the amount must be positive", ErrValidation)
+	}
+	if requested.GreaterThan(g.Balance) {
+		return decimal.Zero, fmt.Errorf("%w: the gift card balance is %s", ErrValidation, g.Balance.StringFixed(2))
+	}
+	if requested.GreaterThan(due) {
+		return decimal.Zero, fmt.Errorf("%w: only %s is due", ErrValidation, due.StringFixed(2))
+	}
+	return *requested, nil
+}
+
+// GiftCardRepository defines the repository interface for GiftCard
+type GiftCardRepository interface {
+	BaseRepository[GiftCard]
+	FindByCode(ctx context.Context, businessID, code string) (*GiftCard, error)
+	FindTransactions(ctx context.Context, giftCardID string) ([]*GiftCardTransaction, error)
+	// Issue creates a card together with the transaction recording its sale
+	Issue(ctx context.Context, card *GiftCard) error
+	// Redeem takes a succeeded payment's amount off a card and records the payment and the
+	// transaction in a single transaction, returning ErrGiftCardBalanceChanged when the
+	// balance no longer covers it
+	Redeem(ctx context.Context, giftCardID string, payment *Payment) error
+	// Refund puts part of a gift card payment back onto the card and records the refund on the
+	// payment, which must already carry it
+	Refund(ctx context.Context, payment *Payment, amount decimal.Decimal) error
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewGiftCardCode(t *testing.T) {
+	code, err := NewGiftCardCode()
+	require.NoError(t, err)
+	assert.Regexp(t, regexp.MustCompile(`^[A-HJ-NP-Z2-9]{4}-[A-HJ-NP-Z2-9]{4}-[A-HJ-NP-Z2-9]{4}$`), code)
+
+	other, err := NewGiftCardCode()
+	require.NoError(t, err)
+	assert.NotEqual(t, code, other)
+}
+
+func TestNormalizeGiftCardCode(t *testing.T) {
+	assert.Equal(t, "ABCD-EFGH-JKLM", NormalizeGiftCardCode("abcd efgh jklm"))
+	assert.Equal(t, "ABCD-EFGH-JKLM", NormalizeGiftCardCode("ABCDEFGHJKLM"))
+	assert.Equal(t, "ABCD-EFGH-JKLM", NormalizeGiftCardCode("ab-cdEF-GHjk lm"))
+}
+
+func TestGiftCard_IsRedeemable(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	expiry := now.Add(time.Hour)
+	card := &GiftCard{Balance: decimal.NewFromInt(50), ExpiresAt: &expiry}
+
+	assert.True(t, card.IsRedeemable(now))
+	assert.False(t, card.IsRedeemable(expiry), "a card expires at its expiry time")
+
+	spent := &GiftCard{Balance: decimal.Zero}
+	assert.False(t, spent.IsRedeemable(now))
+}
+
+func TestGiftCard_RedemptionAmount(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	card := &GiftCard{Balance: decimal.NewFromInt(50)}
+
+	amount, err := card.RedemptionAmount(decimal.NewFromInt(30), nil, now)
+	require.NoError(t, err)
+	assert.True(t, amount.Equal(decimal.NewFromInt(30)), "the card covers the whole bill")
+
+	amount, err = card.RedemptionAmount(decimal.NewFromInt(80), nil, now)
+	require.NoError(t, err)
+	assert.True(t, amount.Equal(decimal.NewFromInt(50)), "the card covers what its balance allows")
+
+	requested := decimal.NewFromInt(20)
+	amount, err = card.RedemptionAmount(decimal.NewFromInt(30), &requested, now)
+	require.NoError(t, err)
+	assert.True(t, amount.Equal(requested))
+
+	tooMuch := decimal.NewFromInt(60)
+	_, err = card.RedemptionAmount(decimal.NewFromInt(80), &tooMuch, now)
+	assert.ErrorIs(t, err, ErrValidation, "cannot redeem more than the balance")
+
+	_, err = card.RedemptionAmount(decimal.NewFromInt(10), &requested, now)
+	assert.ErrorIs(t, err, ErrValidation, "cannot redeem more than is due")
+
+	expired := now.Add(-time.Hour)
+	card.ExpiresAt = &expired
+	_, err = card.RedemptionAmount(decimal.NewFromInt(30), nil, now)
+	assert.ErrorIs(t, err, ErrValidation)
+}
@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewID(t *testing.T) {
+	before := time.Now().Truncate(time.Millisecond)
+	first, second := NewID(), NewID()
+
+	parsed, err := uuid.Parse(first)
+	require.NoError(t, err)
+	assert.Equal(t, uuid.Version(7), parsed.Version())
+	assert.Less(t, first, second, "IDs sort in the order they were generated")
+
+	created, ok := IDTime(first)
+	require.True(t, ok)
+	assert.False(t, created.Before(before))
+	assert.WithinDuration(t, time.Now(), created, time.Second)
+}
+
+func TestIDTime_LegacyIDs(t *testing.T) {
+	_, ok := IDTime(uuid.NewString())
+	assert.False(t, ok, "UUIDv4 IDs carry no time")
+	_, ok = IDTime("not-a-uuid")
+	assert.False(t, ok)
+}
@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	// IdempotencyKeyRetention is how long the response to a request is replayed to its retries
+	IdempotencyKeyRetention = 24 * time.Hour
+	// IdempotencyKeyLockTimeout is how long a request may hold its key before a retry may run it
+	// again, in case the request was cut short without releasing the key
+	IdempotencyKeyLockTimeout = time.Minute
+	// MaxIdempotencyKeyLength is the length of the longest idempotency key accepted
+	MaxIdempotencyKeyLength = 255
+)
+
+var (
+	// ErrIdempotencyKeyReused is returned when an idempotency key is sent with a request other
+	// than the one it was first sent with
+	ErrIdempotencyKeyReused = errors.New("the idempotency key was used for a different request")
+	// ErrIdempotencyKeyInProgress is returned when a request is retried while the first attempt
+	// is still running
+	ErrIdempotencyKeyInProgress = errors.New("a request with the idempotency key is in progress")
+)
+
+// IdempotencyStatus is how far the request of an idempotency key got
+type IdempotencyStatus string
+
+const (
+	IdempotencyPending   IdempotencyStatus = "pending"   // The request is running
+	IdempotencyCompleted IdempotencyStatus = "completed" // The response was recorded, to be replayed to retries
+)
+
+// IdempotencyKey records the first response to a request sent with an Idempotency-Key header,
+// so that retries of the request get that response rather than running it again. Keys are
+// scoped to the user sending them, or for anonymous callers to their IP address.
+type IdempotencyKey struct {
+	BaseModel
+	UserID      *string           `gorm:"type:uuid" json:"user_id,omitempty"` // Nil for anonymous callers, as on the public booking API
+	Scope       string            `gorm:"not null;size:100" json:"scope"`     // Whose keys the key is unique among; see UserIdempotencyScope and ClientIdempotencyScope
+	Key         string            `gorm:"not null;size:255" json:"key"`
+	RequestHash string            `gorm:"not null;size:64" json:"request_hash"` // SHA-256 of the request, to tell other requests sent with the key apart
+	Status      IdempotencyStatus `gorm:"not null;size:20;default:'pending'" json:"status"`
+	Response    *string           `gorm:"type:text" json:"response,omitempty"` // Set once completed
+	ExpiresAt   time.Time         `gorm:"not null;index" json:"expires_at"`
+}
+
+// TableName returns the table name for IdempotencyKey
+func (IdempotencyKey) TableName() string { return "idempotency_keys" }
+
+// UserIdempotencyScope returns the scope of the keys a signed-in user sends
+func UserIdempotencyScope(userID string) string {
+	return "user:" + userID
+}
+
+// ClientIdempotencyScope returns the scope of the keys anonymous callers send from an IP address
+func ClientIdempotencyScope(ip string) string {
+	return "ip:" + ip
+}
+
+// NewIdempotencyKey creates the pending key of a request sent in a scope, by a user unless nil
+func NewIdempotencyKey(scope string, userID *string, key, requestHash string, now time.Time) *IdempotencyKey {
+	return &IdempotencyKey{
+		UserID:      userID,
+		Scope:       scope,
+		Key:         key,
+		RequestHash: requestHash,
+		Status:      IdempotencyPending,
+		ExpiresAt:   now.Add(IdempotencyKeyRetention),
+	}
+}
+
+// CheckRetry checks that a request sent with an already stored key can be answered with the
+// response recorded for it
+func (k *IdempotencyKey) CheckRetry(requestHash string) error {
+	if k.RequestHash != requestHash {
+		return ErrIdempotencyKeyReused
+	}
+	if k.Status != IdempotencyCompleted || k.Response == nil {
+		return ErrIdempotencyKeyInProgress
+	}
+	return nil
+}
+
+// Replaceable reports whether a new request may take over the key: once it has expired, or when
+// its request has held it pending past the lock timeout
+func (k *IdempotencyKey) Replaceable(now time.Time) bool {
+	if !k.ExpiresAt.After(now) {
+		return true
+	}
+	return k.Status == IdempotencyPending && !k.CreatedAt.After(now.Add(-IdempotencyKeyLockTimeout))
+}
+
+// IdempotencyKeyRepository defines the repository interface for IdempotencyKey
+type IdempotencyKeyRepository interface {
+	BaseRepository[IdempotencyKey]
+	// Claim stores a pending key unless its scope already has one with the same key that is not
+	// Replaceable, in which case it returns that one and false
+	Claim(ctx context.Context, key *IdempotencyKey, now time.Time) (*IdempotencyKey, bool, error)
+	// Complete records the response to the request of a pending key
+	Complete(ctx context.Context, id string, response string) error
+	// Release permanently deletes a pending key, so that its request can be retried
+	Release(ctx context.Context, id string) error
+	// DeleteExpired permanently deletes the keys that expired before a time
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyKey_CheckRetry(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	user := "user-1"
+	key := NewIdempotencyKey(UserIdempotencyScope(user), &user, "key-1", "hash-1", now)
+
+	assert.ErrorIs(t, key.CheckRetry("hash-1"), ErrIdempotencyKeyInProgress)
+	assert.ErrorIs(t, key.CheckRetry("hash-2"), ErrIdempotencyKeyReused)
+
+	response := `{"data":{}}`
+	key.Status = IdempotencyCompleted
+	key.Response = &response
+	assert.NoError(t, key.CheckRetry("hash-1"))
+	assert.ErrorIs(t, key.CheckRetry("hash-2"), ErrIdempotencyKeyReused)
+}
+
+func TestIdempotencyKey_Replaceable(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	key := NewIdempotencyKey(ClientIdempotencyScope("203.0.113.7"), nil, "key-1", "hash-1", now)
+	key.CreatedAt = now
+
+	assert.False(t, key.Replaceable(now.Add(30*time.Second)), "the request may still be running")
+	assert.True(t, key.Replaceable(now.Add(IdempotencyKeyLockTimeout)), "the request was cut short")
+
+	key.Status = IdempotencyCompleted
+	assert.False(t, key.Replaceable(now.Add(time.Hour)))
+	assert.True(t, key.Replaceable(now.Add(IdempotencyKeyRetention)))
+}
@@ -0,0 +1,163 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ImagePurpose represents what an uploaded image is shown as
+type ImagePurpose string
+
+const (
+	ImagePurposeBusinessLogo  ImagePurpose = "business_logo"  // The business's logo
+	ImagePurposeBusinessCover ImagePurpose = "business_cover" // The banner of the business's pages
+	ImagePurposeStaffPhoto    ImagePurpose = "staff_photo"    // A staff member's profile picture
+)
+
+// IsValid reports whether the purpose is known
+func (p ImagePurpose) IsValid() bool {
+	switch p {
+	case ImagePurposeBusinessLogo, ImagePurposeBusinessCover, ImagePurposeStaffPhoto:
+		return true
+	}
+	return false
+}
+
+// MaxDimension returns the most pixels images of the purpose are served at on either side;
+// larger ones are scaled down
+func (p ImagePurpose) MaxDimension() int {
+	switch p {
+	case ImagePurposeBusinessCover:
+		return 1920
+	case ImagePurposeStaffPhoto:
+		return 800
+	default:
+		return 512
+	}
+}
+
+// Column returns the column of the target the image's URL is set on
+func (p ImagePurpose) Column() string {
+	switch p {
+	case ImagePurposeBusinessCover:
+		return "cover_photo_url"
+	case ImagePurposeStaffPhoto:
+		return "profile_image_url"
+	default:
+		return "logo_url"
+	}
+}
+
+// ImageUploadStatus represents the state of an image upload
+type ImageUploadStatus string
+
+const (
+	ImageUploadPending  ImageUploadStatus = "pending"  // The upload URL was handed out; waiting for the file
+	ImageUploadAttached ImageUploadStatus = "attached" // Processed and shown on its target
+	ImageUploadRejected ImageUploadStatus = "rejected" // The file was not a valid image; see the error
+	ImageUploadRemoved  ImageUploadStatus = "removed"  // Its files were removed from storage
+)
+
+const (
+	// MaxImageUploadBytes bounds the size of an uploaded image
+	MaxImageUploadBytes = 10 << 20
+	// ImageUploadGracePeriod is how long after its upload URL expires an upload can still be
+	// completed, before it is considered abandoned and its file removed
+	ImageUploadGracePeriod = time.Hour
+	// ImageUploadPrefix is the prefix of the storage keys of files as uploaded
+	ImageUploadPrefix = "uploads/"
+	// ImagePrefix is the prefix of the storage keys of processed images, which are served publicly
+	ImagePrefix = "images/"
+)
+
+// ImageUpload is an image uploaded straight to object storage through a presigned URL. Once the
+// upload is completed the image is validated, scaled down and stored again to be served from its
+// public URL, which is set on its target: a business's logo or cover photo, or a staff member's
+// profile picture. Files no longer shown anywhere are removed by a periodic cleanup.
+type ImageUpload struct {
+	BaseModel
+	BusinessID      string            `gorm:"not null;type:uuid;index" json:"business_id"`
+	Purpose         ImagePurpose      `gorm:"not null;size:20" json:"purpose"`
+	TargetID        string            `gorm:"not null;type:uuid" json:"target_id"`  // The business or staff member shown the image
+	ContentType     string            `gorm:"not null;size:50" json:"content_type"` // As declared for the upload
+	SizeBytes       int64             `gorm:"not null" json:"size_bytes"`           // As declared for the upload
+	Status          ImageUploadStatus `gorm:"not null;size:20;default:'pending'" json:"status"`
+	Bucket          string            `gorm:"not null;size:255" json:"bucket"`
+	UploadKey       string            `gorm:"not null;size:500" json:"upload_key"`
+	UploadExpiresAt time.Time         `gorm:"not null" json:"upload_expires_at"` // When the upload URL stops working
+	ObjectKey       *string           `gorm:"size:500" json:"object_key,omitempty"`
+	URL             *string           `gorm:"size:255" json:"url,omitempty"` // Where the processed image is served from
+	Width           *int              `gorm:"" json:"width,omitempty"`
+	Height          *int              `gorm:"" json:"height,omitempty"`
+	Error           *string           `gorm:"type:text" json:"error,omitempty"`
+	AttachedAt      *time.Time        `gorm:"" json:"attached_at,omitempty"`
+	RemovedAt       *time.Time        `gorm:"" json:"removed_at,omitempty"`
+}
+
+// TableName returns the table name for ImageUpload
+func (ImageUpload) TableName() string { return "image_uploads" }
+
+// Validate validates the image upload model
+func (u *ImageUpload) Validate() error {
+	if u.BusinessID == "" {
+		return fmt.Errorf("%w: business_id is required", ErrValidation)
+	}
+	if !u.Purpose.IsValid() {
+		return fmt.Errorf("%w: invalid purpose %q", ErrValidation, u.Purpose)
+	}
+	if u.TargetID == "" {
+		return fmt.Errorf("%w: target_id is required", ErrValidation)
+	}
+	if u.SizeBytes <= 0 || u.SizeBytes > MaxImageUploadBytes {
+		return fmt.Errorf("%w: images must be at most %d MB", ErrValidation, MaxImageUploadBytes>>20)
+	}
+	return nil
+}
+
+// UploadKeyFor returns the storage key the file is uploaded to
+func (u *ImageUpload) UploadKeyFor() string {
+	return ImageUploadPrefix + u.BusinessID + "/" + u.ID
+}
+
+// ObjectKeyFor returns the storage key of the processed image, with the extension of its format
+func (u *ImageUpload) ObjectKeyFor(extension string) string {
+	return ImagePrefix + u.BusinessID + "/" + u.ID + "." + extension
+}
+
+// CanComplete reports whether the upload can still be completed at a time
+func (u *ImageUpload) CanComplete(now time.Time) bool {
+	return u.Status == ImageUploadPending && now.Before(u.UploadExpiresAt.Add(ImageUploadGracePeriod))
+}
+
+// Attach records the processed image as stored and shown on its target
+func (u *ImageUpload) Attach(key, url string, width, height int, now time.Time) {
+	u.Status = ImageUploadAttached
+	u.ObjectKey = &key
+	u.URL = &url
+	u.Width = &width
+	u.Height = &height
+	u.Error = nil
+	u.AttachedAt = &now
+}
+
+// Reject records why the uploaded file was refused
+func (u *ImageUpload) Reject(message string) {
+	u.Status = ImageUploadRejected
+	u.Error = &message
+}
+
+// MarkRemoved records the upload's files as removed from storage
+func (u *ImageUpload) MarkRemoved(now time.Time) {
+	u.Status = ImageUploadRemoved
+	u.RemovedAt = &now
+}
+
+// ImageUploadRepository defines the interface for image upload repository operations
+type ImageUploadRepository interface {
+	BaseRepository[ImageUpload]
+	// FindUnreferenced finds, across businesses and oldest first, the uploads whose files can be
+	// removed: pending ones whose upload URL expired before the cutoff, rejected ones, and
+	// attached ones no business or staff member shows any more
+	FindUnreferenced(ctx context.Context, abandonedBefore time.Time, limit int) ([]*ImageUpload, error)
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// IntegrationChannel represents the kind of integration traffic being counted
+type IntegrationChannel string
+
+const (
+	IntegrationChannelAPI     IntegrationChannel = "api"
+	IntegrationChannelWebhook IntegrationChannel = "webhook"
+)
+
+// IntegrationUsageDaily holds a business's request and error counts for one channel and day
+type IntegrationUsageDaily struct {
+	BaseModel
+	BusinessID   string             `gorm:"not null;type:uuid;uniqueIndex:uq_integration_usage_daily" json:"business_id"`
+	UsageDate    time.Time          `gorm:"not null;type:date;uniqueIndex:uq_integration_usage_daily" json:"usage_date"`
+	Channel      IntegrationChannel `gorm:"not null;size:20;uniqueIndex:uq_integration_usage_daily" json:"channel"`
+	SourceID     string             `gorm:"not null;size:100;default:'';uniqueIndex:uq_integration_usage_daily" json:"source_id"` // API key or webhook endpoint
+	RequestCount int                `gorm:"not null;default:0" json:"request_count"`
+	ErrorCount   int                `gorm:"not null;default:0" json:"error_count"`
+}
+
+// TableName returns the table name for IntegrationUsageDaily
+func (IntegrationUsageDaily) TableName() string { return "integration_usage_daily" }
+
+// ErrorRate returns the share of failed requests, between 0 and 1
+func (u *IntegrationUsageDaily) ErrorRate() float64 {
+	if u.RequestCount == 0 {
+		return 0
+	}
+	return float64(u.ErrorCount) / float64(u.RequestCount)
+}
+
+// IntegrationUsageRepository defines the repository interface for IntegrationUsageDaily
+type IntegrationUsageRepository interface {
+	BaseRepository[IntegrationUsageDaily]
+	Increment(ctx context.Context, businessID string, day time.Time, channel IntegrationChannel, sourceID string, failed bool) error
+	FindByBusiness(ctx context.Context, businessID string, start, end time.Time) ([]*IntegrationUsageDaily, error)
+}
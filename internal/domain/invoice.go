@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// InvoiceLine is a single billed item of an invoice
+type InvoiceLine struct {
+	Description string          `json:"description"`
+	Amount      decimal.Decimal `json:"amount"`
+}
+
+// Invoice is an immutable record of a completed appointment's charges. Business and client
+// details are captured at issue time so that later edits don't change issued invoices.
+type Invoice struct {
+	BaseModel
+	BusinessID    string          `gorm:"not null;type:uuid" json:"business_id"`
+	ClientID      string          `gorm:"not null;type:uuid;index" json:"client_id"`
+	AppointmentID string          `gorm:"not null;type:uuid;uniqueIndex" json:"appointment_id"`
+	InvoiceNumber string          `gorm:"not null;size:20" json:"invoice_number"`
+	IssuedAt      time.Time       `gorm:"not null" json:"issued_at"`
+	Currency      string          `gorm:"not null;size:3;default:'EUR'" json:"currency"`
+	BusinessName  string          `gorm:"not null;size:100" json:"business_name"`
+	BusinessTaxID *string         `gorm:"size:50" json:"business_tax_id,omitempty"`
+	ClientName    string          `gorm:"not null;size:201" json:"client_name"`
+	LineItems     *string         `gorm:"type:jsonb;default:'[]'" json:"line_items,omitempty"` // JSON array of InvoiceLine
+	Total         decimal.Decimal `gorm:"type:decimal(10,2);not null" json:"total"`
+	AmountPaid    decimal.Decimal `gorm:"type:decimal(10,2);not null" json:"amount_paid"`
+	PaymentMethod *string         `gorm:"size:20" json:"payment_method,omitempty"`
+}
+
+// TableName returns the table name for Invoice
+func (Invoice) TableName() string { return "invoices" }
+
+// GetLineItems decodes the invoice lines
+func (i *Invoice) GetLineItems() ([]InvoiceLine, error) {
+	lines := []InvoiceLine{}
+	if i.LineItems == nil || *i.LineItems == "" {
+		return lines, nil
+	}
+	if err := json.Unmarshal([]byte(*i.LineItems), &lines); err != nil {
+		return nil, fmt.Errorf("%w: invalid invoice lines", ErrValidation)
+	}
+	return lines, nil
+}
+
+// SetLineItems encodes the invoice lines and updates the total
+func (i *Invoice) SetLineItems(lines []InvoiceLine) error {
+	if lines == nil {
+		lines = []InvoiceLine{}
+	}
+	data, err := json.Marshal(lines)
+	if err != nil {
+		return err
+	}
+	encoded := string(data)
+	i.LineItems = &encoded
+
+	i.Total = decimal.Zero
+	for _, line := range lines {
+		i.Total = i.Total.Add(line.Amount)
+	}
+	return nil
+}
+
+// FormatInvoiceNumber formats a business's sequential invoice number
+func FormatInvoiceNumber(issuedAt time.Time, sequence int) string {
+	return fmt.Sprintf("%d-%06d", issuedAt.Year(), sequence)
+}
+
+// InvoiceRepository defines the repository interface for Invoice
+type InvoiceRepository interface {
+	BaseRepository[Invoice]
+	FindByAppointmentID(ctx context.Context, appointmentID string) (*Invoice, error)
+	// Issue assigns the business's next invoice number and creates the invoice atomically
+	Issue(ctx context.Context, invoice *Invoice) error
+}
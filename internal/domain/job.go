@@ -0,0 +1,174 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// JobStatus represents the state of a background job
+type JobStatus string
+
+const (
+	JobStatusPending   JobStatus = "pending"   // Waiting to run, or to be retried
+	JobStatusSucceeded JobStatus = "succeeded" // Ran to completion
+	JobStatusDead      JobStatus = "dead"      // Failed every attempt, or permanently; left to be retried by an operator
+	JobStatusCancelled JobStatus = "cancelled" // Called off before it ran
+)
+
+// IsValid checks if the job status is valid
+func (s JobStatus) IsValid() bool {
+	switch s {
+	case JobStatusPending, JobStatusSucceeded, JobStatusDead, JobStatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// DefaultJobMaxAttempts is how many times a job runs before it is dead-lettered, unless enqueued
+// with another limit
+const DefaultJobMaxAttempts = 8
+
+const (
+	jobRetryBaseDelay = 30 * time.Second
+	jobRetryMaxDelay  = 6 * time.Hour
+	maxJobKindLength  = 100
+	maxJobKeyLength   = 200
+)
+
+// Job is a unit of background work, run by a worker once it is due. The time it is due also
+// leases it to the worker running it, so that a worker stopping mid-job only delays it.
+type Job struct {
+	BaseModel
+	BusinessID  *string    `gorm:"type:uuid;index" json:"business_id,omitempty"` // The business the job runs for; its handler runs within that tenant
+	Kind        string     `gorm:"not null;size:100;index" json:"kind"`          // Selects the handler, e.g. "deposit.expire"
+	UniqueKey   *string    `gorm:"size:200" json:"unique_key,omitempty"`         // At most one pending job of a kind has a key
+	Payload     *string    `gorm:"type:jsonb;default:'{}'" json:"payload,omitempty"`
+	Status      JobStatus  `gorm:"not null;size:20;default:'pending'" json:"status"`
+	RunAt       time.Time  `gorm:"not null;index" json:"run_at"` // Due; also leases the job to a worker
+	Attempts    int        `gorm:"not null;default:0" json:"attempts"`
+	MaxAttempts int        `gorm:"not null;default:8" json:"max_attempts"`
+	LastError   *string    `gorm:"type:text" json:"last_error,omitempty"`
+	CompletedAt *time.Time `gorm:"" json:"completed_at,omitempty"` // When the job succeeded, died or was cancelled
+}
+
+// TableName returns the table name for Job
+func (Job) TableName() string { return "jobs" }
+
+// NewJob creates a pending job of a kind running at runAt with the payload encoded as JSON
+func NewJob(kind string, payload any, runAt time.Time) (*Job, error) {
+	if kind == "" || len(kind) > maxJobKindLength {
+		return nil, fmt.Errorf("%w: job kind must be between 1 and %d characters", ErrValidation, maxJobKindLength)
+	}
+	job := &Job{Kind: kind, Status: JobStatusPending, RunAt: runAt, MaxAttempts: DefaultJobMaxAttempts}
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("%w: job payload cannot be encoded: %v", ErrValidation, err)
+		}
+		encoded := string(data)
+		job.Payload = &encoded
+	}
+	return job, nil
+}
+
+// Validate validates the job model
+func (j *Job) Validate() error {
+	if j.Kind == "" || len(j.Kind) > maxJobKindLength {
+		return fmt.Errorf("%w: job kind must be between 1 and %d characters", ErrValidation, maxJobKindLength)
+	}
+	if j.UniqueKey != nil && (*j.UniqueKey == "" || len(*j.UniqueKey) > maxJobKeyLength) {
+		return fmt.Errorf("%w: job key must be between 1 and %d characters", ErrValidation, maxJobKeyLength)
+	}
+	if !j.Status.IsValid() {
+		return fmt.Errorf("%w: invalid job status %q", ErrValidation, j.Status)
+	}
+	if j.MaxAttempts < 1 {
+		return fmt.Errorf("%w: a job must be allowed at least one attempt", ErrValidation)
+	}
+	return nil
+}
+
+// DecodePayload decodes the job's payload into v
+func (j *Job) DecodePayload(v any) error {
+	if j.Payload == nil || *j.Payload == "" {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(*j.Payload), v); err != nil {
+		return fmt.Errorf("%w: invalid payload for job %s: %v", ErrValidation, j.Kind, err)
+	}
+	return nil
+}
+
+// MarkSucceeded records that the job ran to completion
+func (j *Job) MarkSucceeded(now time.Time) {
+	j.Attempts++
+	j.Status = JobStatusSucceeded
+	j.LastError = nil
+	j.CompletedAt = &now
+}
+
+// RecordFailure records a failed attempt and schedules the next one, dead-lettering the job once
+// it has used up its attempts or when the failure is permanent
+func (j *Job) RecordFailure(err error, permanent bool, now time.Time) {
+	j.Attempts++
+	message := err.Error()
+	j.LastError = &message
+	if permanent || j.Attempts >= j.MaxAttempts {
+		j.Status = JobStatusDead
+		j.CompletedAt = &now
+		return
+	}
+	j.RunAt = now.Add(JobRetryDelay(j.Attempts))
+}
+
+// Retry puts a dead job back in the queue with a fresh set of attempts
+func (j *Job) Retry(now time.Time) error {
+	if j.Status != JobStatusDead {
+		return fmt.Errorf("%w: only dead jobs can be retried", ErrValidation)
+	}
+	j.Status = JobStatusPending
+	j.Attempts = 0
+	j.RunAt = now
+	j.CompletedAt = nil
+	return nil
+}
+
+// JobRetryDelay returns the delay after a number of failed attempts to run a job, doubling from
+// thirty seconds up to six hours
+func JobRetryDelay(attempts int) time.Duration {
+	delay := jobRetryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= jobRetryMaxDelay {
+			return jobRetryMaxDelay
+		}
+	}
+	return delay
+}
+
+// JobFilter represents the criteria for browsing jobs
+type JobFilter struct {
+	Status     *JobStatus `json:"status,omitempty"`
+	Kind       *string    `json:"kind,omitempty"`
+	BusinessID *string    `json:"business_id,omitempty"`
+}
+
+// JobRepository defines the repository interface for Job
+type JobRepository interface {
+	BaseRepository[Job]
+	// Enqueue creates a job and returns it. A job with a unique key is not created while a pending
+	// job of the same kind has the key; that job is returned instead, with created false.
+	Enqueue(ctx context.Context, job *Job) (stored *Job, created bool, err error)
+	// ClaimDue leases the pending jobs that are due, oldest first, skipping those other workers
+	// are claiming
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*Job, error)
+	// CancelPending cancels the pending job of a kind with a unique key, reporting whether there was one
+	CancelPending(ctx context.Context, kind, uniqueKey string, now time.Time) (bool, error)
+	// OldestDue returns when the oldest pending job that is due was due, or nil when none is
+	OldestDue(ctx context.Context, now time.Time) (*time.Time, error)
+	// Search finds the jobs matching the filter, most recently due first
+	Search(ctx context.Context, filter JobFilter, page, pageSize int) ([]*Job, int64, error)
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewJob(t *testing.T) {
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+
+	job, err := NewJob("deposit.expire", map[string]string{"appointment_id": "appointment-1"}, now)
+	require.NoError(t, err)
+	assert.Equal(t, JobStatusPending, job.Status)
+	assert.Equal(t, DefaultJobMaxAttempts, job.MaxAttempts)
+	require.NoError(t, job.Validate())
+
+	var payload struct {
+		AppointmentID string `json:"appointment_id"`
+	}
+	require.NoError(t, job.DecodePayload(&payload))
+	assert.Equal(t, "appointment-1", payload.AppointmentID)
+
+	_, err = NewJob("", nil, now)
+	assert.ErrorIs(t, err, ErrValidation)
+	_, err = NewJob("report.generate", func() {}, now)
+	assert.ErrorIs(t, err, ErrValidation)
+}
+
+func TestJob_RecordFailure(t *testing.T) {
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	job, err := NewJob("campaign.send", nil, now)
+	require.NoError(t, err)
+	job.MaxAttempts = 2
+
+	job.RecordFailure(errors.New("provider unavailable"), false, now)
+	assert.Equal(t, JobStatusPending, job.Status)
+	assert.Equal(t, now.Add(30*time.Second), job.RunAt)
+	assert.Nil(t, job.CompletedAt)
+
+	job.RecordFailure(errors.New("provider unavailable"), false, now)
+	assert.Equal(t, JobStatusDead, job.Status, "dead-lettered once its attempts are used up")
+	assert.Equal(t, &now, job.CompletedAt)
+
+	require.NoError(t, job.Retry(now))
+	assert.Equal(t, JobStatusPending, job.Status)
+	assert.Zero(t, job.Attempts)
+	assert.Nil(t, job.CompletedAt)
+	assert.ErrorIs(t, job.Retry(now), ErrValidation, "only dead jobs can be retried")
+
+	job.RecordFailure(errors.New("invalid payload"), true, now)
+	assert.Equal(t, JobStatusDead, job.Status, "permanent failures are dead-lettered right away")
+	assert.Equal(t, "invalid payload", *job.LastError)
+
+	require.NoError(t, job.Retry(now))
+	job.MarkSucceeded(now)
+	assert.Equal(t, JobStatusSucceeded, job.Status)
+	assert.Nil(t, job.LastError)
+}
+
+func TestJobRetryDelay(t *testing.T) {
+	assert.Equal(t, 30*time.Second, JobRetryDelay(1))
+	assert.Equal(t, time.Minute, JobRetryDelay(2))
+	assert.Equal(t, 4*time.Minute, JobRetryDelay(4))
+	assert.Equal(t, 6*time.Hour, JobRetryDelay(20))
+}
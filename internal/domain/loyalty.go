@@ -0,0 +1,309 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// LoyaltyProgramType describes what a loyalty program rewards
+type LoyaltyProgramType string
+
+const (
+	LoyaltyProgramVisit   LoyaltyProgramType = "visit"   // Points for every visit
+	LoyaltyProgramSpend   LoyaltyProgramType = "spend"   // Points for every unit of currency spent
+	LoyaltyProgramService LoyaltyProgramType = "service" // Points for visits for particular services
+	LoyaltyProgramTier    LoyaltyProgramType = "tier"    // Members move up tiers as they collect points
+)
+
+// LoyaltyTransactionType tells how a transaction changed a membership's points
+type LoyaltyTransactionType string
+
+const (
+	LoyaltyTransactionEarn   LoyaltyTransactionType = "earn"
+	LoyaltyTransactionRedeem LoyaltyTransactionType = "redeem"
+	LoyaltyTransactionAdjust LoyaltyTransactionType = "adjust"
+	LoyaltyTransactionExpire LoyaltyTransactionType = "expire"
+	// LoyaltyTransactionReferral awards a referrer the program's referral bonus
+	LoyaltyTransactionReferral LoyaltyTransactionType = "referral"
+)
+
+// LoyaltyTier is a level members reach once they have collected enough points
+type LoyaltyTier struct {
+	Name      string `json:"name"`
+	MinPoints int    `json:"min_points"` // Lifetime points needed to reach the tier
+}
+
+// LoyaltyRules configures how a program awards points for a completed visit
+type LoyaltyRules struct {
+	PointsPerVisit        int             `json:"points_per_visit"`
+	PointsPerCurrencyUnit decimal.Decimal `json:"points_per_currency_unit"` // e.g. 1 for a point per euro
+	ServiceIDs            []string        `json:"service_ids,omitempty"`    // Only visits for these services earn; all visits when empty
+	MinimumSpend          decimal.Decimal `json:"minimum_spend"`            // The least a visit must cost to earn
+	Tiers                 []LoyaltyTier   `json:"tiers,omitempty"`          // Ordered by increasing MinPoints
+	ReferralBonus         int             `json:"referral_bonus,omitempty"` // Points for the referrer once a referred client completes their first visit
+}
+
+// LoyaltyVisit is what a client paid for at a completed appointment
+type LoyaltyVisit struct {
+	PriceCharged decimal.Decimal
+	Lines        []*AppointmentLine
+}
+
+// Earned works out the points a visit earns and the spend that counts towards them. Programs
+// limited to some services only count what was paid for those services.
+func (r LoyaltyRules) Earned(visit LoyaltyVisit) (int, decimal.Decimal) {
+	spend := visit.PriceCharged
+	if len(r.ServiceIDs) > 0 {
+		spend = decimal.Zero
+		for _, line := range visit.Lines {
+			if slices.Contains(r.ServiceIDs, line.ServiceID) {
+				spend = spend.Add(line.Price)
+			}
+		}
+		if spend.IsZero() {
+			return 0, decimal.Zero
+		}
+		spend = decimal.Min(spend, visit.PriceCharged)
+	}
+	if spend.LessThan(r.MinimumSpend) {
+		return 0, decimal.Zero
+	}
+
+	points := r.PointsPerVisit + int(spend.Mul(r.PointsPerCurrencyUnit).Floor().IntPart())
+	return points, spend
+}
+
+// TierFor returns the highest tier reached with the given lifetime points, and the tier after it
+func (r LoyaltyRules) TierFor(points int) (*LoyaltyTier, *LoyaltyTier) {
+	var current, next *LoyaltyTier
+	for i := range r.Tiers {
+		if r.Tiers[i].MinPoints <= points {
+			current = &r.Tiers[i]
+		} else if next == nil {
+			next = &r.Tiers[i]
+		}
+	}
+	return current, next
+}
+
+// LoyaltyProgram is a business's scheme for rewarding returning clients
+type LoyaltyProgram struct {
+	BaseModel
+	BusinessID  string             `gorm:"not null;type:uuid;index" json:"business_id"`
+	Name        string             `gorm:"not null;size:100" json:"name"`
+	Description *string            `gorm:"type:text" json:"description,omitempty"`
+	ProgramType LoyaltyProgramType `gorm:"not null;size:50" json:"program_type"`
+	Rules       string             `gorm:"type:jsonb;not null" json:"rules"` // JSON LoyaltyRules
+	RewardType  string             `gorm:"not null;size:50" json:"reward_type"`
+	RewardValue string             `gorm:"type:jsonb;not null" json:"reward_value"`
+	IsActive    bool               `gorm:"not null;default:true" json:"is_active"`
+	StartDate   *time.Time         `gorm:"" json:"start_date,omitempty"`
+	EndDate     *time.Time         `gorm:"" json:"end_date,omitempty"`
+}
+
+// TableName returns the table name for LoyaltyProgram
+func (LoyaltyProgram) TableName() string { return "loyalty_programs" }
+
+// GetRules decodes the program rules
+func (p *LoyaltyProgram) GetRules() (LoyaltyRules, error) {
+	var rules LoyaltyRules
+	if p.Rules == "" {
+		return rules, nil
+	}
+	if err := json.Unmarshal([]byte(p.Rules), &rules); err != nil {
+		return rules, fmt.Errorf("%w: invalid loyalty rules", ErrValidation)
+	}
+	return rules, nil
+}
+
+// SetRules encodes the program rules
+func (p *LoyaltyProgram) SetRules(rules LoyaltyRules) error {
+	data, err := json.Marshal(rules)
+	if err != nil {
+		return err
+	}
+	p.Rules = string(data)
+	return nil
+}
+
+// IsRunningAt reports whether the program awards points at the given time
+func (p *LoyaltyProgram) IsRunningAt(at time.Time) bool {
+	if !p.IsActive {
+		return false
+	}
+	if p.StartDate != nil && at.Before(*p.StartDate) {
+		return false
+	}
+	return p.EndDate == nil || at.Before(*p.EndDate)
+}
+
+// Validate validates the loyalty program model
+func (p *LoyaltyProgram) Validate() error {
+	if p.BusinessID == "" || p.Name == "" || p.RewardType == "" {
+		return ErrValidation
+	}
+	switch p.ProgramType {
+	case LoyaltyProgramVisit, LoyaltyProgramSpend, LoyaltyProgramService, LoyaltyProgramTier:
+	default:
+		return fmt.Errorf("%w: unknown program type %q", ErrValidation, p.ProgramType)
+	}
+	if p.StartDate != nil && p.EndDate != nil && !p.EndDate.After(*p.StartDate) {
+		return fmt.Errorf("%w: end_date must be after start_date", ErrValidation)
+	}
+
+	rules, err := p.GetRules()
+	if err != nil {
+		return err
+	}
+	if rules.PointsPerVisit < 0 || rules.PointsPerCurrencyUnit.IsNegative() || rules.MinimumSpend.IsNegative() || rules.ReferralBonus < 0 {
+		return fmt.Errorf("%w: loyalty rules cannot be negative", ErrValidation)
+	}
+	if rules.PointsPerVisit == 0 && rules.PointsPerCurrencyUnit.IsZero() {
+		return fmt.Errorf("%w: the program must award points per visit or per spend", ErrValidation)
+	}
+	if p.ProgramType == LoyaltyProgramService && len(rules.ServiceIDs) == 0 {
+		return fmt.Errorf("%w: service programs must name their services", ErrValidation)
+	}
+	if p.ProgramType == LoyaltyProgramTier && len(rules.Tiers) == 0 {
+		return fmt.Errorf("%w: tier programs must define their tiers", ErrValidation)
+	}
+	for i, tier := range rules.Tiers {
+		if tier.Name == "" {
+			return fmt.Errorf("%w: tiers must be named", ErrValidation)
+		}
+		if i > 0 && tier.MinPoints <= rules.Tiers[i-1].MinPoints {
+			return fmt.Errorf("%w: tier points must be increasing", ErrValidation)
+		}
+	}
+	return nil
+}
+
+// LoyaltyProgress is what a member has collected towards the program's tiers
+type LoyaltyProgress struct {
+	LifetimePoints   int        `json:"lifetime_points"` // Points earned, ignoring redemptions, which set the tier
+	NextTier         *string    `json:"next_tier,omitempty"`
+	PointsToNextTier int        `json:"points_to_next_tier"`
+	LastEarnedAt     *time.Time `json:"last_earned_at,omitempty"`
+}
+
+// ClientLoyaltyMembership is a client's membership of a loyalty program
+type ClientLoyaltyMembership struct {
+	BaseModel
+	ProgramID     string          `gorm:"not null;type:uuid;index" json:"program_id"`
+	ClientID      string          `gorm:"not null;type:uuid;index" json:"client_id"`
+	CurrentPoints int             `gorm:"not null;default:0" json:"current_points"`
+	VisitsCount   int             `gorm:"not null;default:0" json:"visits_count"`
+	TotalSpent    decimal.Decimal `gorm:"type:decimal(10,2);not null;default:0" json:"total_spent"`
+	TierLevel     *string         `gorm:"size:20" json:"tier_level,omitempty"`
+	Progress      *string         `gorm:"type:jsonb" json:"progress,omitempty"` // JSON LoyaltyProgress
+	JoinDate      time.Time       `gorm:"not null" json:"join_date"`
+	ExpiryDate    *time.Time      `gorm:"" json:"expiry_date,omitempty"`
+	IsActive      bool            `gorm:"not null;default:true" json:"is_active"`
+	ReferralCode  *string         `gorm:"size:20;uniqueIndex" json:"referral_code,omitempty"` // Given to friends the member refers
+}
+
+// TableName returns the table name for ClientLoyaltyMembership
+func (ClientLoyaltyMembership) TableName() string { return "client_loyalty_memberships" }
+
+// GetProgress decodes the membership progress
+func (m *ClientLoyaltyMembership) GetProgress() (LoyaltyProgress, error) {
+	var progress LoyaltyProgress
+	if m.Progress == nil || *m.Progress == "" {
+		return progress, nil
+	}
+	if err := json.Unmarshal([]byte(*m.Progress), &progress); err != nil {
+		return progress, fmt.Errorf("%w: invalid loyalty progress", ErrValidation)
+	}
+	return progress, nil
+}
+
+// SetProgress encodes the membership progress
+func (m *ClientLoyaltyMembership) SetProgress(progress LoyaltyProgress) error {
+	data, err := json.Marshal(progress)
+	if err != nil {
+		return err
+	}
+	encoded := string(data)
+	m.Progress = &encoded
+	return nil
+}
+
+// IsActiveAt reports whether the membership can earn points at the given time
+func (m *ClientLoyaltyMembership) IsActiveAt(at time.Time) bool {
+	return m.IsActive && (m.ExpiryDate == nil || at.Before(*m.ExpiryDate))
+}
+
+// Earn adds the points and spend of a visit to the membership and moves it up the program's
+// tiers
+func (m *ClientLoyaltyMembership) Earn(rules LoyaltyRules, points int, spend decimal.Decimal, at time.Time) error {
+	m.VisitsCount++
+	m.TotalSpent = m.TotalSpent.Add(spend)
+	return m.addPoints(rules, points, at)
+}
+
+// addPoints adds earned points to the membership, moving it up the program's tiers
+func (m *ClientLoyaltyMembership) addPoints(rules LoyaltyRules, points int, at time.Time) error {
+	progress, err := m.GetProgress()
+	if err != nil {
+		return err
+	}
+	m.CurrentPoints += points
+
+	progress.LifetimePoints += points
+	progress.LastEarnedAt = &at
+	progress.NextTier, progress.PointsToNextTier = nil, 0
+	current, next := rules.TierFor(progress.LifetimePoints)
+	if current != nil {
+		m.TierLevel = &current.Name
+	}
+	if next != nil {
+		progress.NextTier = &next.Name
+		progress.PointsToNextTier = next.MinPoints - progress.LifetimePoints
+	}
+	return m.SetProgress(progress)
+}
+
+// LoyaltyTransaction records a change to a membership's points
+type LoyaltyTransaction struct {
+	BaseModel
+	MembershipID    string                 `gorm:"not null;type:uuid;index" json:"membership_id"`
+	AppointmentID   *string                `gorm:"type:uuid;index" json:"appointment_id,omitempty"`
+	TransactionType LoyaltyTransactionType `gorm:"not null;size:20" json:"transaction_type"`
+	Points          int                    `gorm:"not null" json:"points"`
+	Description     *string                `gorm:"type:text" json:"description,omitempty"`
+}
+
+// TableName returns the table name for LoyaltyTransaction
+func (LoyaltyTransaction) TableName() string { return "loyalty_transactions" }
+
+// LoyaltyRepository defines the repository interface for LoyaltyProgram and its memberships
+type LoyaltyRepository interface {
+	BaseRepository[LoyaltyProgram]
+	// FindRunningPrograms finds the business's programs awarding points at the given time
+	FindRunningPrograms(ctx context.Context, businessID string, at time.Time) ([]*LoyaltyProgram, error)
+	FindMembership(ctx context.Context, programID, clientID string) (*ClientLoyaltyMembership, error)
+	// HasEarned reports whether the membership already earned points for the appointment
+	HasEarned(ctx context.Context, membershipID, appointmentID string) (bool, error)
+	// FindAppointmentLines finds the services performed during an appointment
+	FindAppointmentLines(ctx context.Context, appointmentID string) ([]*AppointmentLine, error)
+	// RecordEarning saves the membership, creating it on the client's first earning visit,
+	// together with the transaction recording the points
+	RecordEarning(ctx context.Context, membership *ClientLoyaltyMembership, transaction *LoyaltyTransaction) error
+	// SaveMembership saves a membership, creating it when it is new
+	SaveMembership(ctx context.Context, membership *ClientLoyaltyMembership) error
+	// FindMembershipByReferralCode finds the membership a referral code was given to
+	FindMembershipByReferralCode(ctx context.Context, code string) (*ClientLoyaltyMembership, error)
+	// FindReferral finds how a client was referred to the business
+	FindReferral(ctx context.Context, businessID, referredClientID string) (*Referral, error)
+	CreateReferral(ctx context.Context, referral *Referral) error
+	// RecordReferralBonus saves a converted referral together with the referrer's membership and
+	// the transaction awarding the bonus, when one was awarded
+	RecordReferralBonus(ctx context.Context, referral *Referral, membership *ClientLoyaltyMembership, transaction *LoyaltyTransaction) error
+	// FindReferrals finds the business's referrals made between from and to, oldest first
+	FindReferrals(ctx context.Context, businessID string, from, to time.Time) ([]*Referral, error)
+}
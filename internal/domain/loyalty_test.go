@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func loyaltyVisit(price string, lines ...*AppointmentLine) LoyaltyVisit {
+	return LoyaltyVisit{PriceCharged: decimal.RequireFromString(price), Lines: lines}
+}
+
+func TestLoyaltyRules_Earned(t *testing.T) {
+	rules := LoyaltyRules{PointsPerVisit: 10, PointsPerCurrencyUnit: decimal.NewFromInt(1), MinimumSpend: decimal.NewFromInt(20)}
+
+	points, spend := rules.Earned(loyaltyVisit("45.80"))
+	assert.Equal(t, 55, points, "a point per whole euro on top of the visit")
+	assert.True(t, spend.Equal(decimal.RequireFromString("45.80")))
+
+	points, _ = rules.Earned(loyaltyVisit("15"))
+	assert.Zero(t, points, "visits below the minimum spend earn nothing")
+}
+
+func TestLoyaltyRules_Earned_Services(t *testing.T) {
+	rules := LoyaltyRules{PointsPerCurrencyUnit: decimal.NewFromInt(2), ServiceIDs: []string{"colour"}}
+	colour := &AppointmentLine{ServiceID: "colour", Price: decimal.NewFromInt(40)}
+	cut := &AppointmentLine{ServiceID: "cut", Price: decimal.NewFromInt(25)}
+
+	points, spend := rules.Earned(loyaltyVisit("65", colour, cut))
+	assert.Equal(t, 80, points, "only the colour counts")
+	assert.True(t, spend.Equal(decimal.NewFromInt(40)))
+
+	points, _ = rules.Earned(loyaltyVisit("30", colour))
+	assert.Equal(t, 60, points, "a discounted visit counts what was charged")
+
+	points, _ = rules.Earned(loyaltyVisit("25", cut))
+	assert.Zero(t, points)
+}
+
+func TestClientLoyaltyMembership_Earn(t *testing.T) {
+	rules := LoyaltyRules{PointsPerVisit: 50, Tiers: []LoyaltyTier{{Name: "silver", MinPoints: 100}, {Name: "gold", MinPoints: 250}}}
+	at := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	membership := &ClientLoyaltyMembership{}
+
+	require.NoError(t, membership.Earn(rules, 50, decimal.NewFromInt(30), at))
+	assert.Nil(t, membership.TierLevel)
+	progress, err := membership.GetProgress()
+	require.NoError(t, err)
+	assert.Equal(t, "silver", *progress.NextTier)
+	assert.Equal(t, 50, progress.PointsToNextTier)
+
+	membership.CurrentPoints = 0 // Redeemed points do not move the member down a tier
+	require.NoError(t, membership.Earn(rules, 60, decimal.NewFromInt(30), at))
+	assert.Equal(t, "silver", *membership.TierLevel)
+	assert.Equal(t, 60, membership.CurrentPoints)
+	assert.Equal(t, 2, membership.VisitsCount)
+	assert.True(t, membership.TotalSpent.Equal(decimal.NewFromInt(60)))
+	progress, err = membership.GetProgress()
+	require.NoError(t, err)
+	assert.Equal(t, 110, progress.LifetimePoints)
+	assert.Equal(t, "gold", *progress.NextTier)
+	assert.Equal(t, 140, progress.PointsToNextTier)
+}
+
+func TestLoyaltyProgram_Validate(t *testing.T) {
+	program := &LoyaltyProgram{BusinessID: "business-1", Name: "Regulars", ProgramType: LoyaltyProgramVisit, RewardType: "fixed", IsActive: true}
+	require.NoError(t, program.SetRules(LoyaltyRules{PointsPerVisit: 10}))
+	assert.NoError(t, program.Validate())
+
+	require.NoError(t, program.SetRules(LoyaltyRules{}))
+	assert.ErrorIs(t, program.Validate(), ErrValidation, "the program must award something")
+
+	program.ProgramType = LoyaltyProgramTier
+	require.NoError(t, program.SetRules(LoyaltyRules{PointsPerVisit: 10, Tiers: []LoyaltyTier{{Name: "gold", MinPoints: 200}, {Name: "silver", MinPoints: 100}}}))
+	assert.ErrorIs(t, program.Validate(), ErrValidation, "tiers must be increasing")
+}
@@ -0,0 +1,479 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// BillingInterval is how often a membership is billed
+type BillingInterval string
+
+const (
+	BillingMonthly BillingInterval = "monthly"
+)
+
+// MembershipStatus represents the billing status of a client's membership
+type MembershipStatus string
+
+const (
+	MembershipActive    MembershipStatus = "active"
+	MembershipPastDue   MembershipStatus = "past_due"  // A payment failed and is being retried within the grace period
+	MembershipSuspended MembershipStatus = "suspended" // The grace period ended unpaid; the client has no access
+	MembershipCancelled MembershipStatus = "cancelled" // No longer billed; access lasts to the end of the paid period
+)
+
+// MembershipInvoiceStatus represents the status of a membership invoice
+type MembershipInvoiceStatus string
+
+const (
+	MembershipInvoiceOpen          MembershipInvoiceStatus = "open"
+	MembershipInvoicePaid          MembershipInvoiceStatus = "paid"
+	MembershipInvoiceUncollectible MembershipInvoiceStatus = "uncollectible" // Given up on when the membership was suspended
+)
+
+// RefundPolicy is what a client gets back for the unused part of the paid period when their
+// membership is cancelled
+type RefundPolicy string
+
+const (
+	RefundPolicyNone   RefundPolicy = "none"   // Access lasts to the end of the paid period instead
+	RefundPolicyRefund RefundPolicy = "refund" // Refunded to the card the period was paid with
+	RefundPolicyCredit RefundPolicy = "credit" // Issued as store credit on a gift card
+)
+
+// GivesBack reports whether the policy refunds or credits the unused part of the period. An
+// unset policy is taken as RefundPolicyNone.
+func (p RefundPolicy) GivesBack() bool {
+	return p == RefundPolicyRefund || p == RefundPolicyCredit
+}
+
+// CreditNoteKind tells how a credit note was settled
+type CreditNoteKind string
+
+const (
+	CreditNoteRefund CreditNoteKind = "refund"
+	CreditNoteCredit CreditNoteKind = "credit"
+)
+
+// PaymentMethodCreditNote is the sale payment method of gift cards holding store credit
+const PaymentMethodCreditNote = "credit_note"
+
+// ErrNoSessionsLeft is returned when a session is used from a membership with none left in
+// its period
+var ErrNoSessionsLeft = errors.New("no sessions are left in the membership period")
+
+// MembershipBillingAction is what a billing run does with a membership
+type MembershipBillingAction string
+
+const (
+	MembershipBillingNone    MembershipBillingAction = ""
+	MembershipBillingCharge  MembershipBillingAction = "charge"  // Invoice and charge the next period
+	MembershipBillingRetry   MembershipBillingAction = "retry"   // Retry the open invoice
+	MembershipBillingSuspend MembershipBillingAction = "suspend" // The grace period ended unpaid
+)
+
+// MembershipRetrySchedule is how long dunning waits after each failed payment before retrying.
+// Once the retries are used up the membership waits out its grace period.
+var MembershipRetrySchedule = []time.Duration{24 * time.Hour, 3 * 24 * time.Hour, 5 * 24 * time.Hour}
+
+// MembershipGracePeriod is how long after the first failed payment a membership keeps its
+// access while payment is retried, before it is suspended
+const MembershipGracePeriod = 14 * 24 * time.Hour
+
+// MembershipPlan is a recurring membership a business sells, billed to the client's stored card
+type MembershipPlan struct {
+	BaseModel
+	BusinessID      string          `gorm:"not null;type:uuid;index" json:"business_id"`
+	Name            string          `gorm:"not null;size:100" json:"name"`
+	Description     *string         `gorm:"type:text" json:"description,omitempty"`
+	Price           decimal.Decimal `gorm:"type:decimal(10,2);not null" json:"price"`
+	Currency        string          `gorm:"not null;size:3" json:"currency"`
+	BillingInterval BillingInterval `gorm:"not null;size:20;default:'monthly'" json:"billing_interval"`
+	// SessionsPerPeriod makes the plan a package of sessions renewed each period; unlimited when nil
+	SessionsPerPeriod      *int         `gorm:"" json:"sessions_per_period,omitempty"`
+	RefundPolicy           RefundPolicy `gorm:"not null;size:20;default:'none'" json:"refund_policy"`
+	CancellationFeePercent int          `gorm:"not null;default:0" json:"cancellation_fee_percent"` // Kept from the unused part refunded or credited
+	IsActive               bool         `gorm:"not null;default:true" json:"is_active"`
+}
+
+// TableName returns the table name for MembershipPlan
+func (MembershipPlan) TableName() string { return "membership_plans" }
+
+// ClientMembership is a client's subscription to a membership plan. The price is fixed when
+// the client joins, so later plan changes do not reprice existing members.
+type ClientMembership struct {
+	BaseModel
+	BusinessID         string           `gorm:"not null;type:uuid;index" json:"business_id"`
+	PlanID             string           `gorm:"not null;type:uuid;index" json:"plan_id"`
+	ClientID           string           `gorm:"not null;type:uuid;index" json:"client_id"`
+	Status             MembershipStatus `gorm:"not null;size:20;default:'active'" json:"status"`
+	Price              decimal.Decimal  `gorm:"type:decimal(10,2);not null" json:"price"`
+	Currency           string           `gorm:"not null;size:3" json:"currency"`
+	StripeCustomerID   *string          `gorm:"size:255" json:"stripe_customer_id,omitempty"`
+	PaymentMethodID    string           `gorm:"not null;size:255" json:"payment_method_id"` // The stored card or mandate charged each period
+	BillingDay         int              `gorm:"not null" json:"billing_day"`                // The day of the month periods start on
+	CurrentPeriodStart *time.Time       `gorm:"" json:"current_period_start,omitempty"`     // The last paid period
+	CurrentPeriodEnd   *time.Time       `gorm:"" json:"current_period_end,omitempty"`
+	SessionsPerPeriod  *int             `gorm:"" json:"sessions_per_period,omitempty"` // Fixed when the client joins
+	SessionsRemaining  *int             `gorm:"" json:"sessions_remaining,omitempty"`  // In the current period
+	NextBillingAt      time.Time        `gorm:"not null;index" json:"next_billing_at"`
+	FailedAttempts     int              `gorm:"not null;default:0" json:"failed_attempts"` // Failed payments of the open invoice
+	NextRetryAt        *time.Time       `gorm:"" json:"next_retry_at,omitempty"`
+	GraceUntil         *time.Time       `gorm:"" json:"grace_until,omitempty"`
+	SuspendedAt        *time.Time       `gorm:"" json:"suspended_at,omitempty"`
+	CancelledAt        *time.Time       `gorm:"" json:"cancelled_at,omitempty"`
+}
+
+// TableName returns the table name for ClientMembership
+func (ClientMembership) TableName() string { return "client_memberships" }
+
+// MembershipInvoice bills one period of a membership
+type MembershipInvoice struct {
+	BaseModel
+	BusinessID      string                  `gorm:"not null;type:uuid;index" json:"business_id"`
+	MembershipID    string                  `gorm:"not null;type:uuid;uniqueIndex:idx_membership_invoices_period" json:"membership_id"`
+	ClientID        string                  `gorm:"not null;type:uuid" json:"client_id"`
+	PeriodStart     time.Time               `gorm:"not null;uniqueIndex:idx_membership_invoices_period" json:"period_start"`
+	PeriodEnd       time.Time               `gorm:"not null" json:"period_end"`
+	Amount          decimal.Decimal         `gorm:"type:decimal(10,2);not null" json:"amount"`
+	Currency        string                  `gorm:"not null;size:3" json:"currency"`
+	Status          MembershipInvoiceStatus `gorm:"not null;size:20;default:'open'" json:"status"`
+	Attempts        int                     `gorm:"not null;default:0" json:"attempts"`
+	PaymentIntentID *string                 `gorm:"size:255" json:"payment_intent_id,omitempty"`
+	FailureReason   *string                 `gorm:"type:text" json:"failure_reason,omitempty"`
+	PaidAt          *time.Time              `gorm:"" json:"paid_at,omitempty"`
+}
+
+// TableName returns the table name for MembershipInvoice
+func (MembershipInvoice) TableName() string { return "membership_invoices" }
+
+// MembershipCreditNote records what was given back for the unused part of a paid period when a
+// membership was cancelled
+type MembershipCreditNote struct {
+	BaseModel
+	BusinessID        string          `gorm:"not null;type:uuid;index" json:"business_id"`
+	MembershipID      string          `gorm:"not null;type:uuid;index" json:"membership_id"`
+	InvoiceID         string          `gorm:"not null;type:uuid;uniqueIndex" json:"invoice_id"` // The paid invoice credited
+	ClientID          string          `gorm:"not null;type:uuid" json:"client_id"`
+	Kind              CreditNoteKind  `gorm:"not null;size:20" json:"kind"`
+	UnusedShare       decimal.Decimal `gorm:"type:decimal(5,4);not null" json:"unused_share"` // Of the period, between 0 and 1
+	Fee               decimal.Decimal `gorm:"type:decimal(10,2);not null;default:0" json:"fee"`
+	Amount            decimal.Decimal `gorm:"type:decimal(10,2);not null" json:"amount"` // Refunded or credited, after the fee
+	Currency          string          `gorm:"not null;size:3" json:"currency"`
+	SessionsForfeited int             `gorm:"not null;default:0" json:"sessions_forfeited"`
+	RefundID          *string         `gorm:"size:255" json:"refund_id,omitempty"`
+	GiftCardID        *string         `gorm:"type:uuid" json:"gift_card_id,omitempty"` // Holding the store credit
+}
+
+// TableName returns the table name for MembershipCreditNote
+func (MembershipCreditNote) TableName() string { return "membership_credit_notes" }
+
+// ProRation is the unused part of a membership's paid period at some time, and what the plan's
+// refund policy gives back for it
+type ProRation struct {
+	Policy            RefundPolicy
+	PaidAmount        decimal.Decimal
+	UnusedShare       decimal.Decimal // Of the sessions left when the plan has sessions, of the time left otherwise
+	Unused            decimal.Decimal
+	Fee               decimal.Decimal
+	Amount            decimal.Decimal // Refunded or credited; zero under RefundPolicyNone
+	Currency          string
+	SessionsRemaining int
+}
+
+// Validate validates the membership plan model
+func (p *MembershipPlan) Validate() error {
+	if p.BusinessID == "" || strings.TrimSpace(p.Name) == "" {
+		return ErrValidation
+	}
+	if !p.Price.IsPositive() {
+		return fmt.Errorf("%w: the price must be positive", ErrValidation)
+	}
+	if len(p.Currency) != 3 {
+		return fmt.Errorf("%w: the currency must be a three-letter code", ErrValidation)
+	}
+	if p.BillingInterval != BillingMonthly {
+		return fmt.Errorf("%w: plans are billed monthly", ErrValidation)
+	}
+	if p.SessionsPerPeriod != nil && *p.SessionsPerPeriod < 1 {
+		return fmt.Errorf("%w: a package includes at least one session", ErrValidation)
+	}
+	switch p.RefundPolicy {
+	case "", RefundPolicyNone, RefundPolicyRefund, RefundPolicyCredit:
+	default:
+		return fmt.Errorf("%w: unknown refund policy %q", ErrValidation, p.RefundPolicy)
+	}
+	if p.CancellationFeePercent < 0 || p.CancellationFeePercent > 100 {
+		return fmt.Errorf("%w: the cancellation fee must be between 0 and 100 percent", ErrValidation)
+	}
+	return nil
+}
+
+// NewClientMembership subscribes a client to a plan, with the first period billed from start
+func NewClientMembership(plan *MembershipPlan, clientID, paymentMethodID string, customerID *string, start time.Time) (*ClientMembership, error) {
+	if !plan.IsActive {
+		return nil, fmt.Errorf("%w: the plan is no longer sold", ErrValidation)
+	}
+	membership := &ClientMembership{
+		BusinessID:        plan.BusinessID,
+		PlanID:            plan.ID,
+		ClientID:          clientID,
+		Status:            MembershipActive,
+		Price:             plan.Price,
+		Currency:          plan.Currency,
+		StripeCustomerID:  customerID,
+		PaymentMethodID:   paymentMethodID,
+		BillingDay:        start.Day(),
+		NextBillingAt:     start,
+		SessionsPerPeriod: plan.SessionsPerPeriod,
+	}
+	return membership, membership.Validate()
+}
+
+// Validate validates the client membership model
+func (m *ClientMembership) Validate() error {
+	if m.BusinessID == "" || m.PlanID == "" || m.ClientID == "" || m.PaymentMethodID == "" {
+		return ErrValidation
+	}
+	if !m.Price.IsPositive() {
+		return fmt.Errorf("%w: the price must be positive", ErrValidation)
+	}
+	if m.BillingDay < 1 || m.BillingDay > 31 {
+		return fmt.Errorf("%w: the billing day must be between 1 and 31", ErrValidation)
+	}
+	return nil
+}
+
+// BillingAction tells what a billing run at the given time does with the membership
+func (m *ClientMembership) BillingAction(now time.Time) MembershipBillingAction {
+	switch m.Status {
+	case MembershipActive:
+		if !m.NextBillingAt.After(now) {
+			return MembershipBillingCharge
+		}
+	case MembershipPastDue:
+		if m.GraceUntil != nil && !now.Before(*m.GraceUntil) {
+			return MembershipBillingSuspend
+		}
+		if m.NextRetryAt != nil && !m.NextRetryAt.After(now) {
+			return MembershipBillingRetry
+		}
+	}
+	return MembershipBillingNone
+}
+
+// HasAccess reports whether the client can use the membership: while it is paid up or being
+// retried within its grace period, and after cancelling until the paid period ends
+func (m *ClientMembership) HasAccess(now time.Time) bool {
+	switch m.Status {
+	case MembershipActive, MembershipPastDue:
+		return true
+	case MembershipCancelled:
+		return m.CurrentPeriodEnd != nil && now.Before(*m.CurrentPeriodEnd)
+	default:
+		return false
+	}
+}
+
+// NextInvoice builds the invoice for the period starting at the next billing date
+func (m *ClientMembership) NextInvoice() *MembershipInvoice {
+	return &MembershipInvoice{
+		BusinessID:   m.BusinessID,
+		MembershipID: m.ID,
+		ClientID:     m.ClientID,
+		PeriodStart:  m.NextBillingAt,
+		PeriodEnd:    nextBillingDate(m.NextBillingAt, m.BillingDay),
+		Amount:       m.Price,
+		Currency:     m.Currency,
+		Status:       MembershipInvoiceOpen,
+	}
+}
+
+// RecordPayment marks the invoice paid, making its period the membership's current one and
+// clearing any dunning
+func (m *ClientMembership) RecordPayment(invoice *MembershipInvoice, paymentIntentID string, at time.Time) {
+	invoice.Status = MembershipInvoicePaid
+	invoice.Attempts++
+	invoice.PaymentIntentID = &paymentIntentID
+	invoice.FailureReason = nil
+	invoice.PaidAt = &at
+
+	m.Status = MembershipActive
+	m.CurrentPeriodStart = &invoice.PeriodStart
+	m.CurrentPeriodEnd = &invoice.PeriodEnd
+	m.NextBillingAt = invoice.PeriodEnd
+	if m.SessionsPerPeriod != nil {
+		sessions := *m.SessionsPerPeriod
+		m.SessionsRemaining = &sessions
+	}
+	m.FailedAttempts = 0
+	m.NextRetryAt = nil
+	m.GraceUntil = nil
+	m.SuspendedAt = nil
+}
+
+// RecordFailure records a failed payment of the invoice. The first failure starts the grace
+// period; retries follow MembershipRetrySchedule while they fit in it.
+func (m *ClientMembership) RecordFailure(invoice *MembershipInvoice, reason string, at time.Time) {
+	invoice.Attempts++
+	invoice.FailureReason = &reason
+
+	m.Status = MembershipPastDue
+	m.FailedAttempts++
+	if m.GraceUntil == nil {
+		graceUntil := at.Add(MembershipGracePeriod)
+		m.GraceUntil = &graceUntil
+	}
+	m.NextRetryAt = nil
+	if m.FailedAttempts <= len(MembershipRetrySchedule) {
+		retryAt := at.Add(MembershipRetrySchedule[m.FailedAttempts-1])
+		if retryAt.Before(*m.GraceUntil) {
+			m.NextRetryAt = &retryAt
+		}
+	}
+}
+
+// Suspend suspends the membership once its grace period has ended unpaid, giving up on the
+// open invoice
+func (m *ClientMembership) Suspend(invoice *MembershipInvoice, at time.Time) {
+	if invoice != nil {
+		invoice.Status = MembershipInvoiceUncollectible
+	}
+	m.Status = MembershipSuspended
+	m.SuspendedAt = &at
+	m.NextRetryAt = nil
+	m.GraceUntil = nil
+}
+
+// Reactivate restarts a suspended membership, with a new period billed from the given time
+func (m *ClientMembership) Reactivate(at time.Time) error {
+	if m.Status != MembershipSuspended {
+		return fmt.Errorf("%w: only suspended memberships can be reactivated", ErrValidation)
+	}
+	m.Status = MembershipActive
+	m.BillingDay = at.Day()
+	m.NextBillingAt = at
+	m.FailedAttempts = 0
+	m.SuspendedAt = nil
+	return nil
+}
+
+// Cancel stops billing the membership. The client keeps access until the paid period ends.
+func (m *ClientMembership) Cancel(at time.Time) error {
+	if m.Status == MembershipCancelled {
+		return fmt.Errorf("%w: the membership is already cancelled", ErrValidation)
+	}
+	m.Status = MembershipCancelled
+	m.CancelledAt = &at
+	m.NextRetryAt = nil
+	m.GraceUntil = nil
+	return nil
+}
+
+// ProRate works out the unused part of the period paid by the invoice at the given time, and
+// what the refund policy gives back for it after the cancellation fee. Packages are pro-rated
+// by the sessions left, other plans by the time left. Nothing is unused when the invoice is
+// nil or does not pay for the current period.
+func (m *ClientMembership) ProRate(plan *MembershipPlan, invoice *MembershipInvoice, at time.Time) ProRation {
+	proration := ProRation{Policy: plan.RefundPolicy, Currency: m.Currency, UnusedShare: decimal.Zero}
+	if m.SessionsRemaining != nil {
+		proration.SessionsRemaining = *m.SessionsRemaining
+	}
+	if invoice == nil || invoice.Status != MembershipInvoicePaid || m.CurrentPeriodEnd == nil ||
+		!invoice.PeriodEnd.Equal(*m.CurrentPeriodEnd) || !at.Before(invoice.PeriodEnd) {
+		return proration
+	}
+
+	proration.PaidAmount = invoice.Amount
+	switch {
+	case m.SessionsPerPeriod != nil && *m.SessionsPerPeriod > 0:
+		proration.UnusedShare = decimal.NewFromInt(int64(proration.SessionsRemaining)).
+			Div(decimal.NewFromInt(int64(*m.SessionsPerPeriod)))
+	default:
+		start := invoice.PeriodStart
+		if at.After(start) {
+			start = at
+		}
+		proration.UnusedShare = decimal.NewFromInt(int64(invoice.PeriodEnd.Sub(start))).
+			Div(decimal.NewFromInt(int64(invoice.PeriodEnd.Sub(invoice.PeriodStart))))
+	}
+	proration.UnusedShare = decimal.Min(proration.UnusedShare, decimal.NewFromInt(1)).Round(4)
+	proration.Unused = invoice.Amount.Mul(proration.UnusedShare).Round(2)
+	if !plan.RefundPolicy.GivesBack() {
+		return proration
+	}
+	proration.Fee = proration.Unused.Mul(decimal.NewFromInt(int64(plan.CancellationFeePercent))).Div(decimal.NewFromInt(100)).Round(2)
+	proration.Amount = proration.Unused.Sub(proration.Fee)
+	return proration
+}
+
+// ForfeitPeriod ends the paid period at the given time, once its unused part has been
+// refunded or credited, forfeiting the sessions left in it
+func (m *ClientMembership) ForfeitPeriod(at time.Time) {
+	if m.CurrentPeriodEnd != nil && at.Before(*m.CurrentPeriodEnd) {
+		m.CurrentPeriodEnd = &at
+	}
+	if m.SessionsRemaining != nil {
+		none := 0
+		m.SessionsRemaining = &none
+	}
+}
+
+// CanUseSession checks that a session can be used from the membership at the given time
+func (m *ClientMembership) CanUseSession(now time.Time) error {
+	if !m.HasAccess(now) {
+		return fmt.Errorf("%w: the membership gives no access", ErrValidation)
+	}
+	if m.SessionsRemaining == nil {
+		if m.SessionsPerPeriod != nil {
+			return ErrNoSessionsLeft // Joined, but the first period is not paid yet
+		}
+		return nil
+	}
+	if *m.SessionsRemaining < 1 {
+		return ErrNoSessionsLeft
+	}
+	return nil
+}
+
+// IdempotencyKey returns the key of the invoice's next payment attempt, so a charge repeated
+// after a lost response is not taken twice while each retry is a new charge
+func (i *MembershipInvoice) IdempotencyKey() string {
+	return fmt.Sprintf("membership-invoice-%s-%d", i.ID, i.Attempts+1)
+}
+
+// nextBillingDate returns the start of the period after the one starting at from: the billing
+// day of the following month, or its last day when the month is shorter
+func nextBillingDate(from time.Time, billingDay int) time.Time {
+	year, month, _ := from.Date()
+	firstOfNext := time.Date(year, month+1, 1, from.Hour(), from.Minute(), from.Second(), from.Nanosecond(), from.Location())
+	lastDay := firstOfNext.AddDate(0, 1, -1).Day()
+	return firstOfNext.AddDate(0, 0, min(billingDay, lastDay)-1)
+}
+
+// MembershipRepository defines the repository interface for ClientMembership and its invoices
+type MembershipRepository interface {
+	BaseRepository[ClientMembership]
+	FindByClientID(ctx context.Context, clientID string) ([]*ClientMembership, error)
+	// FindCurrent finds the client's membership of the plan that has not been cancelled
+	FindCurrent(ctx context.Context, clientID, planID string) (*ClientMembership, error)
+	// FindDue finds the memberships a billing run at the given time has to act on
+	FindDue(ctx context.Context, now time.Time) ([]*ClientMembership, error)
+	FindOpenInvoice(ctx context.Context, membershipID string) (*MembershipInvoice, error)
+	FindInvoices(ctx context.Context, membershipID string) ([]*MembershipInvoice, error)
+	// SaveBilling saves a membership together with its invoice, creating the invoice when it is
+	// new, in a single transaction
+	SaveBilling(ctx context.Context, membership *ClientMembership, invoice *MembershipInvoice) error
+	// UseSession takes a session off the membership's current period, returning
+	// ErrNoSessionsLeft when none are left. Memberships without sessions are left untouched.
+	UseSession(ctx context.Context, membershipID string, by *string) error
+	// Cancel saves a cancelled membership together with the credit note for its unused period
+	// and the gift card holding the credit, when there are, in a single transaction
+	Cancel(ctx context.Context, membership *ClientMembership, creditNote *MembershipCreditNote, giftCard *GiftCard) error
+	FindCreditNotes(ctx context.Context, membershipID string) ([]*MembershipCreditNote, error)
+}
@@ -0,0 +1,181 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMembership(t *testing.T, start time.Time) *ClientMembership {
+	t.Helper()
+	plan := &MembershipPlan{BusinessID: "business-1", Name: "Unlimited blow-dry", Price: decimal.NewFromInt(60),
+		Currency: "EUR", BillingInterval: BillingMonthly, IsActive: true}
+	plan.ID = "plan-1"
+	require.NoError(t, plan.Validate())
+	membership, err := NewClientMembership(plan, "client-1", "pm_card_visa", nil, start)
+	require.NoError(t, err)
+	membership.ID = "membership-1"
+	return membership
+}
+
+func TestMembershipPlan_Validate(t *testing.T) {
+	plan := &MembershipPlan{BusinessID: "business-1", Name: "Monthly", Price: decimal.NewFromInt(40), Currency: "EUR",
+		BillingInterval: BillingMonthly}
+	require.NoError(t, plan.Validate())
+
+	plan.BillingInterval = "weekly"
+	assert.ErrorIs(t, plan.Validate(), ErrValidation)
+	plan.BillingInterval = BillingMonthly
+	plan.Price = decimal.Zero
+	assert.ErrorIs(t, plan.Validate(), ErrValidation)
+
+	_, err := NewClientMembership(&MembershipPlan{}, "client-1", "pm_card_visa", nil, time.Now())
+	assert.ErrorIs(t, err, ErrValidation, "plans no longer sold cannot be joined")
+}
+
+func TestNextBillingDate(t *testing.T) {
+	jan31 := time.Date(2024, 1, 31, 9, 0, 0, 0, time.UTC)
+	feb29 := nextBillingDate(jan31, 31)
+	assert.Equal(t, time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC), feb29, "short months bill on their last day")
+	assert.Equal(t, time.Date(2024, 3, 31, 9, 0, 0, 0, time.UTC), nextBillingDate(feb29, 31), "and the billing day comes back")
+	assert.Equal(t, time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC), nextBillingDate(time.Date(2024, 12, 15, 9, 0, 0, 0, time.UTC), 15))
+}
+
+func TestClientMembership_Billing(t *testing.T) {
+	start := time.Date(2024, 3, 10, 9, 0, 0, 0, time.UTC)
+	membership := newTestMembership(t, start)
+	assert.Equal(t, MembershipBillingCharge, membership.BillingAction(start))
+	assert.Equal(t, MembershipBillingNone, membership.BillingAction(start.Add(-time.Minute)))
+
+	invoice := membership.NextInvoice()
+	invoice.ID = "invoice-1"
+	assert.Equal(t, start.AddDate(0, 1, 0), invoice.PeriodEnd)
+	assert.Equal(t, "membership-invoice-invoice-1-1", invoice.IdempotencyKey())
+
+	membership.RecordPayment(invoice, "pi_1", start)
+	assert.Equal(t, MembershipInvoicePaid, invoice.Status)
+	assert.Equal(t, "membership-invoice-invoice-1-2", invoice.IdempotencyKey(), "each attempt is a new charge")
+	assert.Equal(t, invoice.PeriodEnd, membership.NextBillingAt)
+	assert.Equal(t, MembershipBillingNone, membership.BillingAction(start.AddDate(0, 0, 20)))
+	assert.True(t, membership.HasAccess(start.AddDate(0, 0, 20)))
+}
+
+func TestClientMembership_Dunning(t *testing.T) {
+	start := time.Date(2024, 3, 10, 9, 0, 0, 0, time.UTC)
+	membership := newTestMembership(t, start)
+	invoice := membership.NextInvoice()
+
+	at := start
+	for _, delay := range MembershipRetrySchedule {
+		membership.RecordFailure(invoice, "Your card was declined.", at)
+		require.NotNil(t, membership.NextRetryAt)
+		assert.Equal(t, at.Add(delay), *membership.NextRetryAt)
+		assert.Equal(t, MembershipPastDue, membership.Status)
+		assert.True(t, membership.HasAccess(at), "past due memberships keep access during the grace period")
+		assert.Equal(t, MembershipBillingNone, membership.BillingAction(at))
+		at = *membership.NextRetryAt
+		assert.Equal(t, MembershipBillingRetry, membership.BillingAction(at))
+	}
+	assert.Equal(t, start.Add(MembershipGracePeriod), *membership.GraceUntil, "the grace period runs from the first failure")
+
+	membership.RecordFailure(invoice, "Your card was declined.", at)
+	assert.Nil(t, membership.NextRetryAt, "the retries are used up")
+	assert.Equal(t, len(MembershipRetrySchedule)+1, invoice.Attempts)
+	assert.Equal(t, MembershipBillingNone, membership.BillingAction(at))
+	assert.Equal(t, MembershipBillingSuspend, membership.BillingAction(*membership.GraceUntil))
+
+	suspendedAt := *membership.GraceUntil
+	membership.Suspend(invoice, suspendedAt)
+	assert.Equal(t, MembershipSuspended, membership.Status)
+	assert.Equal(t, MembershipInvoiceUncollectible, invoice.Status)
+	assert.False(t, membership.HasAccess(suspendedAt))
+	assert.Equal(t, MembershipBillingNone, membership.BillingAction(suspendedAt.AddDate(1, 0, 0)))
+
+	reactivatedAt := suspendedAt.AddDate(0, 0, 3)
+	require.NoError(t, membership.Reactivate(reactivatedAt))
+	assert.Equal(t, MembershipBillingCharge, membership.BillingAction(reactivatedAt))
+	assert.Equal(t, reactivatedAt.Day(), membership.BillingDay)
+	assert.ErrorIs(t, membership.Reactivate(reactivatedAt), ErrValidation)
+}
+
+func TestClientMembership_Cancel(t *testing.T) {
+	start := time.Date(2024, 3, 10, 9, 0, 0, 0, time.UTC)
+	membership := newTestMembership(t, start)
+	invoice := membership.NextInvoice()
+	membership.RecordPayment(invoice, "pi_1", start)
+
+	require.NoError(t, membership.Cancel(start.AddDate(0, 0, 5)))
+	assert.True(t, membership.HasAccess(start.AddDate(0, 0, 20)), "cancelled memberships last to the end of the paid period")
+	assert.False(t, membership.HasAccess(invoice.PeriodEnd))
+	assert.Equal(t, MembershipBillingNone, membership.BillingAction(invoice.PeriodEnd))
+	assert.ErrorIs(t, membership.Cancel(start.AddDate(0, 0, 6)), ErrValidation)
+}
+
+func TestClientMembership_ProRate(t *testing.T) {
+	start := time.Date(2024, 3, 10, 9, 0, 0, 0, time.UTC)
+	membership := newTestMembership(t, start)
+	invoice := membership.NextInvoice()
+	plan := &MembershipPlan{RefundPolicy: RefundPolicyRefund, CancellationFeePercent: 10}
+
+	proration := membership.ProRate(plan, invoice, start)
+	assert.True(t, proration.Amount.IsZero(), "unpaid periods have nothing to give back")
+
+	membership.RecordPayment(invoice, "pi_1", start)
+	halfway := start.Add(invoice.PeriodEnd.Sub(start) / 2)
+	proration = membership.ProRate(plan, invoice, halfway)
+	assert.Equal(t, "0.5", proration.UnusedShare.String())
+	assert.Equal(t, "30", proration.Unused.String())
+	assert.Equal(t, "3", proration.Fee.String())
+	assert.Equal(t, "27", proration.Amount.String())
+
+	assert.Equal(t, "60", membership.ProRate(plan, invoice, start.Add(-time.Hour)).Unused.String(),
+		"a period not started yet is wholly unused")
+	assert.True(t, membership.ProRate(plan, invoice, invoice.PeriodEnd).Unused.IsZero())
+
+	plan.RefundPolicy = RefundPolicyNone
+	proration = membership.ProRate(plan, invoice, halfway)
+	assert.Equal(t, "30", proration.Unused.String())
+	assert.True(t, proration.Amount.IsZero(), "nothing is given back without a refund policy")
+}
+
+func TestClientMembership_Sessions(t *testing.T) {
+	start := time.Date(2024, 3, 10, 9, 0, 0, 0, time.UTC)
+	sessions := 4
+	plan := &MembershipPlan{BusinessID: "business-1", Name: "Four blow-dries", Price: decimal.NewFromInt(60),
+		Currency: "EUR", BillingInterval: BillingMonthly, SessionsPerPeriod: &sessions, RefundPolicy: RefundPolicyCredit,
+		IsActive: true}
+	plan.ID = "plan-1"
+	require.NoError(t, plan.Validate())
+	membership, err := NewClientMembership(plan, "client-1", "pm_card_visa", nil, start)
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, membership.CanUseSession(start), ErrNoSessionsLeft, "sessions come with the first payment")
+	invoice := membership.NextInvoice()
+	membership.RecordPayment(invoice, "pi_1", start)
+	require.NotNil(t, membership.SessionsRemaining)
+	assert.Equal(t, 4, *membership.SessionsRemaining)
+	require.NoError(t, membership.CanUseSession(start))
+
+	remaining := 1
+	membership.SessionsRemaining = &remaining
+	proration := membership.ProRate(plan, invoice, start.AddDate(0, 0, 1))
+	assert.Equal(t, "0.25", proration.UnusedShare.String(), "packages are pro-rated by the sessions left")
+	assert.Equal(t, "15", proration.Amount.String())
+	assert.Equal(t, 1, proration.SessionsRemaining)
+
+	at := start.AddDate(0, 0, 1)
+	require.NoError(t, membership.Cancel(at))
+	membership.ForfeitPeriod(at)
+	assert.Equal(t, 0, *membership.SessionsRemaining)
+	assert.False(t, membership.HasAccess(at.Add(time.Hour)), "a refunded period ends at once")
+	assert.Error(t, membership.CanUseSession(at))
+
+	plan.CancellationFeePercent = 101
+	assert.ErrorIs(t, plan.Validate(), ErrValidation)
+	plan.CancellationFeePercent = 0
+	plan.RefundPolicy = "partial"
+	assert.ErrorIs(t, plan.Validate(), ErrValidation)
+}
@@ -0,0 +1,293 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// MessageChannel represents how a message is delivered to a client
+type MessageChannel string
+
+const (
+	MessageChannelEmail MessageChannel = "email"
+	MessageChannelSMS   MessageChannel = "sms"
+)
+
+// IsValid checks if the channel is valid
+func (c MessageChannel) IsValid() bool {
+	return c == MessageChannelEmail || c == MessageChannelSMS
+}
+
+// MaxSMSSegments is the longest SMS, in segments, an active template may produce
+const MaxSMSSegments = 3
+
+// MessageTemplate is a business's reusable email or SMS message with {{variable}} placeholders
+type MessageTemplate struct {
+	BaseModel
+	BusinessID string         `gorm:"not null;type:uuid;index" json:"business_id"`
+	Name       string         `gorm:"not null;size:100" json:"name"`
+	Channel    MessageChannel `gorm:"not null;size:20" json:"channel"`
+	Purpose    *string        `gorm:"size:50" json:"purpose,omitempty"`  // e.g. reminder, confirmation, campaign
+	Subject    *string        `gorm:"size:200" json:"subject,omitempty"` // Email only
+	Body       string         `gorm:"not null;type:text" json:"body"`
+	IsActive   bool           `gorm:"not null;default:false" json:"is_active"`
+
+	// Relationships
+	Business Business `gorm:"foreignKey:BusinessID;constraint:OnDelete:CASCADE" json:"business"`
+}
+
+// TableName returns the table name for MessageTemplate
+func (MessageTemplate) TableName() string { return "message_templates" }
+
+// Validate validates the message template model. Content problems are reported by Lint.
+func (t *MessageTemplate) Validate() error {
+	if t.BusinessID == "" {
+		return ErrValidation
+	}
+	if t.Name == "" {
+		return ErrValidation
+	}
+	if !t.Channel.IsValid() {
+		return fmt.Errorf("%w: invalid channel %q", ErrValidation, t.Channel)
+	}
+	return nil
+}
+
+// TemplateVariable describes a placeholder that can be used in message templates
+type TemplateVariable struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Sample      string `json:"sample"`
+}
+
+// TemplateVariables lists the placeholders available to message templates
+var TemplateVariables = []TemplateVariable{
+	{Name: "client.first_name", Description: "The client's first name", Sample: "Maria"},
+	{Name: "client.last_name", Description: "The client's last name", Sample: "Silva"},
+	{Name: "client.full_name", Description: "The client's full name", Sample: "Maria Silva"},
+	{Name: "business.name", Description: "The business's display name", Sample: "Beautix Studio"},
+	{Name: "appointment.date", Description: "The appointment date in the business time zone", Sample: "2025-03-14"},
+	{Name: "appointment.time", Description: "The appointment start time in the business time zone", Sample: "15:30"},
+	{Name: "appointment.end_time", Description: "The appointment end time in the business time zone", Sample: "17:00"},
+	{Name: "appointment.services", Description: "The booked services", Sample: "Cut, Colour"},
+	{Name: "appointment.staff", Description: "The staff member's name", Sample: "Ana Costa"},
+	{Name: "appointment.duration", Description: "The appointment duration in minutes", Sample: "90"},
+	{Name: "confirmation.link", Description: "The link the client opens to confirm the appointment", Sample: "https://beautix.app/confirm/3f9a2c"},
+}
+
+// SampleTemplateValues returns the sample value of every template variable
+func SampleTemplateValues() map[string]string {
+	values := make(map[string]string, len(TemplateVariables))
+	for _, variable := range TemplateVariables {
+		values[variable.Name] = variable.Sample
+	}
+	return values
+}
+
+// TemplateRenderContext holds the records a template is rendered against
+type TemplateRenderContext struct {
+	Business    *Business
+	Client      *Client
+	Appointment *CalendarEntry
+	// ConfirmationLink is only set when rendering a confirmation request
+	ConfirmationLink string
+}
+
+// Values returns the template variable values for the context. Variables without a
+// record, e.g. appointment fields when previewing against a client only, use samples.
+func (c TemplateRenderContext) Values() map[string]string {
+	values := SampleTemplateValues()
+
+	location := time.UTC
+	if c.Business != nil {
+		values["business.name"] = c.Business.GetDisplayName()
+		if loc, err := time.LoadLocation(c.Business.TimeZone); err == nil {
+			location = loc
+		}
+	}
+	if c.Client != nil {
+		values["client.first_name"] = c.Client.FirstName
+		values["client.last_name"] = c.Client.LastName
+		values["client.full_name"] = strings.TrimSpace(c.Client.FirstName + " " + c.Client.LastName)
+	}
+	if c.Appointment != nil {
+		start := c.Appointment.StartTime.In(location)
+		values["appointment.date"] = start.Format("2006-01-02")
+		values["appointment.time"] = start.Format("15:04")
+		values["appointment.end_time"] = c.Appointment.EndTime.In(location).Format("15:04")
+		values["appointment.services"] = strings.Join(c.Appointment.GetServiceNames(), ", ")
+		values["appointment.staff"] = c.Appointment.StaffName
+		values["appointment.duration"] = fmt.Sprintf("%d", c.Appointment.TotalDuration)
+	}
+	if c.ConfirmationLink != "" {
+		values["confirmation.link"] = c.ConfirmationLink
+	}
+	return values
+}
+
+// templatePlaceholder matches {{variable}} placeholders, allowing surrounding spaces
+var templatePlaceholder = regexp.MustCompile(`\{\{\s*([^{}]*?)\s*\}\}`)
+
+// RenderTemplateText replaces the placeholders of a template text. Unknown variables
+// are left untouched so that they stand out in previews.
+func RenderTemplateText(text string, values map[string]string) string {
+	return templatePlaceholder.ReplaceAllStringFunc(text, func(placeholder string) string {
+		name := templatePlaceholder.FindStringSubmatch(placeholder)[1]
+		if value, ok := values[name]; ok {
+			return value
+		}
+		return placeholder
+	})
+}
+
+// SMSEncoding is the character encoding an SMS is sent with
+type SMSEncoding string
+
+const (
+	SMSEncodingGSM7 SMSEncoding = "GSM-7"
+	SMSEncodingUCS2 SMSEncoding = "UCS-2"
+)
+
+// gsm7Basic and gsm7Extended are the GSM 03.38 character sets; extended characters take two septets
+const (
+	gsm7Basic    = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+	gsm7Extended = "^{}\\[~]|€\f"
+)
+
+// SMSInfo describes how a text is split into SMS segments
+type SMSInfo struct {
+	Encoding   SMSEncoding `json:"encoding"`
+	Characters int         `json:"characters"` // In encoding units: septets for GSM-7, UTF-16 code units for UCS-2
+	Segments   int         `json:"segments"`
+}
+
+// AnalyzeSMS calculates the encoding and number of segments of an SMS text
+func AnalyzeSMS(text string) SMSInfo {
+	septets := 0
+	for _, r := range text {
+		switch {
+		case strings.ContainsRune(gsm7Basic, r):
+			septets++
+		case strings.ContainsRune(gsm7Extended, r):
+			septets += 2
+		default:
+			units := len(utf16.Encode([]rune(text)))
+			return SMSInfo{Encoding: SMSEncodingUCS2, Characters: units, Segments: smsSegments(units, 70, 67)}
+		}
+	}
+	return SMSInfo{Encoding: SMSEncodingGSM7, Characters: septets, Segments: smsSegments(septets, 160, 153)}
+}
+
+// smsSegments returns the number of segments for a length, given the single and concatenated segment capacities
+func smsSegments(length, single, concatenated int) int {
+	if length == 0 {
+		return 0
+	}
+	if length <= single {
+		return 1
+	}
+	return int(math.Ceil(float64(length) / float64(concatenated)))
+}
+
+// TemplateLintSeverity represents how serious a lint issue is
+type TemplateLintSeverity string
+
+const (
+	TemplateLintError   TemplateLintSeverity = "error"   // Prevents activating the template
+	TemplateLintWarning TemplateLintSeverity = "warning" // Worth reviewing, but allowed
+)
+
+// TemplateLintIssue is a problem found in a message template
+type TemplateLintIssue struct {
+	Severity TemplateLintSeverity `json:"severity"`
+	Code     string               `json:"code"`
+	Message  string               `json:"message"`
+}
+
+// Lint checks the template for unknown variables, malformed placeholders and channel limits.
+// SMS length is measured on the body rendered with sample values.
+func (t *MessageTemplate) Lint() []TemplateLintIssue {
+	issues := []TemplateLintIssue{}
+	addError := func(code, format string, args ...any) {
+		issues = append(issues, TemplateLintIssue{Severity: TemplateLintError, Code: code, Message: fmt.Sprintf(format, args...)})
+	}
+	addWarning := func(code, format string, args ...any) {
+		issues = append(issues, TemplateLintIssue{Severity: TemplateLintWarning, Code: code, Message: fmt.Sprintf(format, args...)})
+	}
+
+	texts := []string{t.Body}
+	if t.Subject != nil {
+		texts = append(texts, *t.Subject)
+	}
+
+	known := SampleTemplateValues()
+	unknown := map[string]bool{}
+	for _, text := range texts {
+		for _, match := range templatePlaceholder.FindAllStringSubmatch(text, -1) {
+			if _, ok := known[match[1]]; !ok {
+				unknown[match[1]] = true
+			}
+		}
+		stripped := templatePlaceholder.ReplaceAllString(text, "")
+		if strings.Contains(stripped, "{{") || strings.Contains(stripped, "}}") {
+			addError("malformed_placeholder", "placeholders must be written as {{variable}}")
+		}
+	}
+	names := make([]string, 0, len(unknown))
+	for name := range unknown {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		addError("unknown_variable", "unknown variable {{%s}}", name)
+	}
+
+	if strings.TrimSpace(t.Body) == "" {
+		addError("empty_body", "the message body is empty")
+	}
+
+	switch t.Channel {
+	case MessageChannelEmail:
+		if t.Subject == nil || strings.TrimSpace(*t.Subject) == "" {
+			addError("missing_subject", "email templates require a subject")
+		}
+	case MessageChannelSMS:
+		if t.Subject != nil && *t.Subject != "" {
+			addWarning("subject_ignored", "SMS messages have no subject; it will not be sent")
+		}
+		info := AnalyzeSMS(RenderTemplateText(t.Body, known))
+		if info.Segments > MaxSMSSegments {
+			addError("sms_too_long", "the message needs %d SMS segments; the limit is %d", info.Segments, MaxSMSSegments)
+		} else if info.Segments > 1 {
+			addWarning("sms_multiple_segments", "the message is sent as %d SMS segments and billed accordingly", info.Segments)
+		}
+		if info.Encoding == SMSEncodingUCS2 {
+			addWarning("sms_unicode", "the message contains characters outside GSM-7, reducing each segment to 70 characters")
+		}
+	}
+
+	return issues
+}
+
+// HasLintErrors returns true if any issue prevents activating a template
+func HasLintErrors(issues []TemplateLintIssue) bool {
+	for _, issue := range issues {
+		if issue.Severity == TemplateLintError {
+			return true
+		}
+	}
+	return false
+}
+
+// MessageTemplateRepository defines the repository interface for MessageTemplate
+type MessageTemplateRepository interface {
+	BaseRepository[MessageTemplate]
+	FindByBusinessID(ctx context.Context, businessID string) ([]*MessageTemplate, error)
+	FindActiveByPurpose(ctx context.Context, businessID string, channel MessageChannel, purpose string) (*MessageTemplate, error)
+}
@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzeSMS(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		encoding SMSEncoding
+		chars    int
+		segments int
+	}{
+		{"single GSM-7 segment", strings.Repeat("a", 160), SMSEncodingGSM7, 160, 1},
+		{"concatenated GSM-7", strings.Repeat("a", 161), SMSEncodingGSM7, 161, 2},
+		{"extended characters count twice", "€10", SMSEncodingGSM7, 4, 1},
+		{"unicode switches to UCS-2", "Olá 😊", SMSEncodingUCS2, 6, 1},
+		{"concatenated UCS-2", strings.Repeat("ç", 68), SMSEncodingUCS2, 68, 1},
+		{"long UCS-2", strings.Repeat("ã", 71), SMSEncodingUCS2, 71, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := AnalyzeSMS(tt.text)
+			assert.Equal(t, tt.encoding, info.Encoding)
+			assert.Equal(t, tt.chars, info.Characters)
+			assert.Equal(t, tt.segments, info.Segments)
+		})
+	}
+}
+
+func TestRenderTemplateText(t *testing.T) {
+	values := map[string]string{"client.first_name": "Maria"}
+
+	assert.Equal(t, "Hi Maria!", RenderTemplateText("Hi {{ client.first_name }}!", values))
+	assert.Equal(t, "Hi {{client.nickname}}", RenderTemplateText("Hi {{client.nickname}}", values))
+}
+
+func TestMessageTemplateLint(t *testing.T) {
+	subject := "See you soon"
+
+	t.Run("valid email template", func(t *testing.T) {
+		template := &MessageTemplate{Channel: MessageChannelEmail, Subject: &subject, Body: "Hi {{client.first_name}}, see you on {{appointment.date}}."}
+		assert.Empty(t, template.Lint())
+	})
+
+	t.Run("unknown and malformed placeholders", func(t *testing.T) {
+		template := &MessageTemplate{Channel: MessageChannelEmail, Subject: &subject, Body: "Hi {{client.nickname}}, {{appointment.date}"}
+		issues := template.Lint()
+		codes := []string{}
+		for _, issue := range issues {
+			codes = append(codes, issue.Code)
+		}
+		assert.ElementsMatch(t, []string{"unknown_variable", "malformed_placeholder"}, codes)
+		assert.True(t, HasLintErrors(issues))
+	})
+
+	t.Run("email requires a subject", func(t *testing.T) {
+		template := &MessageTemplate{Channel: MessageChannelEmail, Body: "Hello"}
+		issues := template.Lint()
+		assert.Len(t, issues, 1)
+		assert.Equal(t, "missing_subject", issues[0].Code)
+	})
+
+	t.Run("long SMS is a warning, too long is an error", func(t *testing.T) {
+		template := &MessageTemplate{Channel: MessageChannelSMS, Body: strings.Repeat("a", 200)}
+		issues := template.Lint()
+		assert.False(t, HasLintErrors(issues))
+		assert.Equal(t, "sms_multiple_segments", issues[0].Code)
+
+		template.Body = strings.Repeat("a", 153*MaxSMSSegments+1)
+		assert.True(t, HasLintErrors(template.Lint()))
+	})
+}
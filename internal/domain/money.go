@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// storedUnitExponent is the number of decimal places the monetary columns keep, whatever the
+// currency
+const storedUnitExponent = 2
+
+// MoneyMigrationPhase is how far the move of monetary amounts from decimal columns to integer
+// minor units has gone. Both columns are written in every phase: a trigger keeps each
+// <column>_minor in step with its decimal column.
+type MoneyMigrationPhase string
+
+const (
+	MoneyPhaseDualWrite MoneyMigrationPhase = "dual_write" // Amounts are read from the decimal columns
+	MoneyPhaseDualRead  MoneyMigrationPhase = "dual_read"  // Amounts are read from the minor units, falling back to the decimal columns
+)
+
+// IsValid checks if the money migration phase is valid
+func (p MoneyMigrationPhase) IsValid() bool {
+	return p == MoneyPhaseDualWrite || p == MoneyPhaseDualRead
+}
+
+// MoneyColumn is a decimal column holding a monetary amount, which gains an integer twin in
+// minor units
+type MoneyColumn struct {
+	Table  string
+	Column string
+}
+
+// MinorColumn returns the name of the column holding the amount in minor units
+func (c MoneyColumn) MinorColumn() string { return c.Column + "_minor" }
+
+// String returns the column qualified by its table
+func (c MoneyColumn) String() string { return c.Table + "." + c.Column }
+
+// MoneyColumns are the monetary columns being moved to minor units. Rates, shares and the
+// amounts of price adjustments, which may be percentages, are left out.
+var MoneyColumns = []MoneyColumn{
+	{Table: "appointments", Column: "estimated_price"},
+	{Table: "appointments", Column: "total_price"},
+	{Table: "appointments", Column: "deposit_paid"},
+	{Table: "appointments", Column: "no_show_fee"},
+	{Table: "appointment_quotes", Column: "total"},
+	{Table: "appointment_quotes", Column: "deposit_due"},
+	{Table: "appointment_services", Column: "price"},
+	{Table: "business_settings", Column: "no_show_fee"},
+	{Table: "calendar_entries", Column: "price"},
+	{Table: "campaign_clients", Column: "conversion_value"},
+	{Table: "clients", Column: "total_spent"},
+	{Table: "client_loyalty_memberships", Column: "total_spent"},
+	{Table: "client_memberships", Column: "price"},
+	{Table: "gift_cards", Column: "initial_value"},
+	{Table: "gift_cards", Column: "balance"},
+	{Table: "gift_card_transactions", Column: "amount"},
+	{Table: "gift_card_transactions", Column: "balance_after"},
+	{Table: "invoices", Column: "total"},
+	{Table: "invoices", Column: "amount_paid"},
+	{Table: "membership_credit_notes", Column: "fee"},
+	{Table: "membership_credit_notes", Column: "amount"},
+	{Table: "membership_invoices", Column: "amount"},
+	{Table: "membership_plans", Column: "price"},
+	{Table: "payments", Column: "amount"},
+	{Table: "payments", Column: "refunded_amount"},
+	{Table: "service_bundles", Column: "price"},
+	{Table: "service_completions", Column: "price_charged"},
+	{Table: "service_price_history", Column: "old_price"},
+	{Table: "service_price_history", Column: "new_price"},
+	{Table: "services", Column: "price"},
+	{Table: "services", Column: "deposit_amount"},
+}
+
+// MoneyColumnsOf returns the monetary columns of a table
+func MoneyColumnsOf(table string) []MoneyColumn {
+	var columns []MoneyColumn
+	for _, column := range MoneyColumns {
+		if column.Table == table {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+// ToStoredUnits converts an amount to the hundredths the minor units columns keep. Unlike
+// ToMinorUnits it ignores the currency, as the decimal columns do; amounts with a fraction of a
+// hundredth cannot be converted without losing money.
+func ToStoredUnits(amount decimal.Decimal) (int64, error) {
+	units := amount.Shift(storedUnitExponent)
+	if !units.IsInteger() {
+		return 0, fmt.Errorf("%w: %s has a fraction of a cent", ErrValidation, amount.String())
+	}
+	return units.IntPart(), nil
+}
+
+// FromStoredUnits converts the hundredths kept in a minor units column back to an amount
+func FromStoredUnits(units int64) decimal.Decimal {
+	return decimal.New(units, -storedUnitExponent)
+}
+
+// MoneyColumnReport is how far a monetary column is from being read safely from its minor units
+type MoneyColumnReport struct {
+	Column     MoneyColumn
+	Rows       int64
+	Missing    int64           // Rows with an amount but no minor units yet
+	Mismatched int64           // Rows whose minor units differ from their amount
+	Fractional int64           // Rows whose amount has a fraction of a cent
+	Sum        decimal.Decimal // Of the amounts
+	SumMinor   int64           // Of the minor units
+}
+
+// Verified reports whether every amount of the column has the minor units it converts to
+func (r *MoneyColumnReport) Verified() bool {
+	return r.Missing == 0 && r.Mismatched == 0 && r.Fractional == 0 && FromStoredUnits(r.SumMinor).Equal(r.Sum)
+}
+
+// MoneyMigrationReport is the verification of every monetary column, to be clean before reads
+// move to the minor units
+type MoneyMigrationReport struct {
+	Columns   []*MoneyColumnReport
+	CheckedAt time.Time
+}
+
+// Verified reports whether every column was verified
+func (r *MoneyMigrationReport) Verified() bool {
+	for _, column := range r.Columns {
+		if !column.Verified() {
+			return false
+		}
+	}
+	return true
+}
+
+// MoneyMigrationRepository fills in and checks the minor units of the monetary columns
+type MoneyMigrationRepository interface {
+	// HasMinorUnits reports whether the migration gave a column its minor units, which it does
+	// not for columns missing from the database
+	HasMinorUnits(ctx context.Context, column MoneyColumn) (bool, error)
+	// Backfill converts the amounts of up to batchSize rows that have no minor units yet,
+	// returning how many it converted
+	Backfill(ctx context.Context, column MoneyColumn, batchSize int) (int64, error)
+	Verify(ctx context.Context, column MoneyColumn) (*MoneyColumnReport, error)
+}
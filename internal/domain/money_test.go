@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoredUnits(t *testing.T) {
+	units, err := ToStoredUnits(decimal.RequireFromString("19.99"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1999), units)
+
+	// Zero-decimal currencies are kept in hundredths too, as the decimal columns keep them
+	units, err = ToStoredUnits(decimal.RequireFromString("-1500"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(-150000), units)
+
+	_, err = ToStoredUnits(decimal.RequireFromString("0.125"))
+	assert.ErrorIs(t, err, ErrValidation)
+
+	assert.True(t, FromStoredUnits(1999).Equal(decimal.RequireFromString("19.99")))
+}
+
+func TestMoneyColumnsOf(t *testing.T) {
+	columns := MoneyColumnsOf("payments")
+	require.Len(t, columns, 2)
+	assert.Equal(t, "refunded_amount_minor", columns[1].MinorColumn())
+	assert.Equal(t, "payments.amount", columns[0].String())
+	assert.Empty(t, MoneyColumnsOf("service_price_changes"))
+}
+
+func TestMoneyMigrationReport_Verified(t *testing.T) {
+	clean := &MoneyColumnReport{Rows: 3, Sum: decimal.RequireFromString("45.50"), SumMinor: 4550}
+	report := &MoneyMigrationReport{Columns: []*MoneyColumnReport{clean}}
+	assert.True(t, report.Verified())
+
+	report.Columns = append(report.Columns, &MoneyColumnReport{Rows: 2, Missing: 1, Sum: decimal.RequireFromString("10"), SumMinor: 500})
+	assert.False(t, report.Verified())
+
+	drifted := &MoneyColumnReport{Rows: 1, Sum: decimal.RequireFromString("10"), SumMinor: 999}
+	assert.False(t, drifted.Verified())
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// MaxNotificationAttempts is how many times a queued notification is sent before it is abandoned
+const MaxNotificationAttempts = 8
+
+// Bounds of the delay between attempts to send a queued notification
+const (
+	notificationRetryBaseDelay = time.Minute
+	notificationRetryMaxDelay  = 6 * time.Hour
+)
+
+// QueuedNotification is a message whose provider could not be reached, kept to be sent later
+type QueuedNotification struct {
+	BaseModel
+	BusinessID    *string        `gorm:"type:uuid;index" json:"business_id,omitempty"`
+	Channel       MessageChannel `gorm:"not null;size:20" json:"channel"`
+	Recipient     string         `gorm:"not null" json:"recipient"`
+	Subject       *string        `json:"subject,omitempty"`
+	Body          string         `gorm:"type:text;not null" json:"body"`
+	Attempts      int            `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt time.Time      `gorm:"not null" json:"next_attempt_at"`
+	LastError     *string        `gorm:"type:text" json:"last_error,omitempty"`
+	SentAt        *time.Time     `json:"sent_at,omitempty"`
+	AbandonedAt   *time.Time     `json:"abandoned_at,omitempty"` // Set once every attempt has failed
+}
+
+// TableName returns the table name for QueuedNotification
+func (QueuedNotification) TableName() string { return "notification_queue" }
+
+// IsPending reports whether the notification is still to be sent
+func (n *QueuedNotification) IsPending() bool {
+	return n.SentAt == nil && n.AbandonedAt == nil
+}
+
+// MarkSent records the delivery of the notification
+func (n *QueuedNotification) MarkSent(now time.Time) {
+	n.Attempts++
+	n.SentAt = &now
+	n.LastError = nil
+}
+
+// RecordFailure records a failed attempt and schedules the next one, abandoning the
+// notification once it has used up its attempts
+func (n *QueuedNotification) RecordFailure(err error, now time.Time) {
+	n.Attempts++
+	message := err.Error()
+	n.LastError = &message
+	if n.Attempts >= MaxNotificationAttempts {
+		n.AbandonedAt = &now
+		return
+	}
+	n.NextAttemptAt = now.Add(NotificationRetryDelay(n.Attempts))
+}
+
+// Abandon records an attempt the provider rejected outright, which retrying cannot fix
+func (n *QueuedNotification) Abandon(err error, now time.Time) {
+	n.Attempts++
+	message := err.Error()
+	n.LastError = &message
+	n.AbandonedAt = &now
+}
+
+// Postpone schedules the next attempt without using one up, for when the provider is known to
+// be down and was not called
+func (n *QueuedNotification) Postpone(until time.Time) {
+	n.NextAttemptAt = until
+}
+
+// NotificationRetryDelay returns the delay after a number of failed attempts, doubling from a
+// minute up to six hours
+func NotificationRetryDelay(attempts int) time.Duration {
+	delay := notificationRetryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= notificationRetryMaxDelay {
+			return notificationRetryMaxDelay
+		}
+	}
+	return delay
+}
+
+// NotificationQueueRepository defines the interface for queued notification operations
+type NotificationQueueRepository interface {
+	BaseRepository[QueuedNotification]
+	// FindDue finds the pending notifications whose next attempt is due, oldest first
+	FindDue(ctx context.Context, now time.Time, limit int) ([]*QueuedNotification, error)
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationRetryDelay(t *testing.T) {
+	assert.Equal(t, time.Minute, NotificationRetryDelay(1))
+	assert.Equal(t, 2*time.Minute, NotificationRetryDelay(2))
+	assert.Equal(t, 16*time.Minute, NotificationRetryDelay(5))
+	assert.Equal(t, 6*time.Hour, NotificationRetryDelay(20))
+}
+
+func TestQueuedNotification_RecordFailure(t *testing.T) {
+	now := time.Date(2026, time.October, 17, 9, 0, 0, 0, time.UTC)
+	notification := &QueuedNotification{Channel: MessageChannelSMS, Recipient: "+351912345678", Body: "Hello", NextAttemptAt: now}
+
+	notification.RecordFailure(errors.New("twilio request failed"), now)
+	assert.Equal(t, 1, notification.Attempts)
+	assert.Equal(t, now.Add(time.Minute), notification.NextAttemptAt)
+	require.NotNil(t, notification.LastError)
+	assert.True(t, notification.IsPending())
+
+	notification.Attempts = MaxNotificationAttempts - 1
+	notification.RecordFailure(errors.New("twilio request failed"), now)
+	require.NotNil(t, notification.AbandonedAt)
+	assert.False(t, notification.IsPending())
+}
+
+func TestQueuedNotification_MarkSent(t *testing.T) {
+	now := time.Date(2026, time.October, 17, 9, 0, 0, 0, time.UTC)
+	message := "smtp timeout"
+	notification := &QueuedNotification{Attempts: 2, LastError: &message}
+
+	notification.MarkSent(now)
+	assert.Equal(t, 3, notification.Attempts)
+	assert.Nil(t, notification.LastError)
+	assert.False(t, notification.IsPending())
+}
@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// MaxServiceBookingsPerDay is the highest daily booking limit a service may set
+const MaxServiceBookingsPerDay = 100
+
+// OnlineBookingMode is how a service is offered to online bookers
+type OnlineBookingMode string
+
+const (
+	OnlineBookingBookable   OnlineBookingMode = "bookable"     // Listed and bookable online
+	OnlineBookingCallToBook OnlineBookingMode = "call_to_book" // Listed, but booked by calling the business
+	OnlineBookingHidden     OnlineBookingMode = "hidden"       // Not offered online at all
+)
+
+// IsValid checks if the online booking mode is valid
+func (m OnlineBookingMode) IsValid() bool {
+	switch m {
+	case OnlineBookingBookable, OnlineBookingCallToBook, OnlineBookingHidden:
+		return true
+	}
+	return false
+}
+
+// BookingMode returns how the service is offered online, bookable unless set otherwise
+func (s *Service) BookingMode() OnlineBookingMode {
+	if s.OnlineBooking == "" {
+		return OnlineBookingBookable
+	}
+	return s.OnlineBooking
+}
+
+// IsListedOnline reports whether online bookers see the service
+func (s *Service) IsListedOnline() bool {
+	return s.BookingMode() != OnlineBookingHidden
+}
+
+// CheckOnlineBooking checks that clients may book the service online, explaining to them why not
+func (s *Service) CheckOnlineBooking() error {
+	switch s.BookingMode() {
+	case OnlineBookingBookable:
+		return nil
+	case OnlineBookingCallToBook:
+		return fmt.Errorf("%w: %s cannot be booked online, please call the business to book it", ErrValidation, s.Name)
+	}
+	return fmt.Errorf("%w: %s is not available for online booking", ErrValidation, s.Name)
+}
+
+// CheckClientHistory checks that a client with the given appointments may book the service
+// online. Services for returning clients only take clients who have completed an appointment,
+// such as a consultation, with the business; nil appointments stand for a new client.
+func (s *Service) CheckClientHistory(appointments []*Appointment) error {
+	if !s.ReturningClientsOnly {
+		return nil
+	}
+	for _, appointment := range appointments {
+		if appointment.Status == AppointmentStatusCompleted {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: new clients need a consultation before booking %s, please book one or call the business", ErrValidation, s.Name)
+}
+
+// CheckDailyLimit checks that the service can take another booking on a day it is already
+// booked for by the given appointments, explaining to the client why not
+func (s *Service) CheckDailyLimit(appointments []*Appointment) error {
+	if s.MaxPerDay == nil {
+		return nil
+	}
+	if CountDailyBookings(appointments) >= *s.MaxPerDay {
+		return fmt.Errorf("%w: %s is fully booked on this day, please choose another", ErrValidation, s.Name)
+	}
+	return nil
+}
+
+// CountDailyBookings counts the appointments that take up a service's daily bookings; those
+// cancelled or moved to another time give theirs back
+func CountDailyBookings(appointments []*Appointment) int {
+	count := 0
+	for _, appointment := range appointments {
+		if appointment.Status != AppointmentStatusCancelled && appointment.Status != AppointmentStatusRescheduled {
+			count++
+		}
+	}
+	return count
+}
+
+// LocalDay returns the day, in a location, that a time falls on
+func LocalDay(t time.Time, location *time.Location) DateRange {
+	local := t.In(location)
+	start := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, location)
+	return DateRange{Start: start, End: start.AddDate(0, 0, 1)}
+}
+
+// validateOnlineBooking validates the online booking rules of a service
+func (s *Service) validateOnlineBooking() error {
+	if s.OnlineBooking != "" && !s.OnlineBooking.IsValid() {
+		return fmt.Errorf("%w: invalid online booking mode %q", ErrValidation, s.OnlineBooking)
+	}
+	if s.MaxPerDay != nil && (*s.MaxPerDay < 1 || *s.MaxPerDay > MaxServiceBookingsPerDay) {
+		return fmt.Errorf("%w: the daily booking limit must be between 1 and %d", ErrValidation, MaxServiceBookingsPerDay)
+	}
+	return nil
+}
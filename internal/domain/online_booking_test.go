@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceOnlineBooking(t *testing.T) {
+	t.Run("services are bookable unless set otherwise", func(t *testing.T) {
+		service := &Service{Name: "Haircut"}
+		assert.Equal(t, OnlineBookingBookable, service.BookingMode())
+		assert.True(t, service.IsListedOnline())
+		assert.NoError(t, service.CheckOnlineBooking())
+	})
+
+	t.Run("call to book services are listed but not bookable", func(t *testing.T) {
+		service := &Service{Name: "Bridal makeup", OnlineBooking: OnlineBookingCallToBook}
+		assert.True(t, service.IsListedOnline())
+		err := service.CheckOnlineBooking()
+		require.ErrorIs(t, err, ErrValidation)
+		assert.Contains(t, err.Error(), "please call the business")
+	})
+
+	t.Run("hidden services are not offered", func(t *testing.T) {
+		service := &Service{Name: "Staff training", OnlineBooking: OnlineBookingHidden}
+		assert.False(t, service.IsListedOnline())
+		assert.ErrorIs(t, service.CheckOnlineBooking(), ErrValidation)
+	})
+}
+
+func TestServiceCheckClientHistory(t *testing.T) {
+	service := &Service{Name: "Chemical peel", ReturningClientsOnly: true}
+
+	assert.ErrorIs(t, service.CheckClientHistory(nil), ErrValidation, "new clients need a consultation")
+	assert.ErrorIs(t, service.CheckClientHistory([]*Appointment{
+		{Status: AppointmentStatusConfirmed},
+		{Status: AppointmentStatusCancelled},
+	}), ErrValidation, "only completed appointments count")
+	assert.NoError(t, service.CheckClientHistory([]*Appointment{{Status: AppointmentStatusCompleted}}))
+
+	service.ReturningClientsOnly = false
+	assert.NoError(t, service.CheckClientHistory(nil))
+}
+
+func TestServiceCheckDailyLimit(t *testing.T) {
+	limit := 2
+	service := &Service{Name: "Microblading", MaxPerDay: &limit}
+
+	booked := []*Appointment{{Status: AppointmentStatusConfirmed}, {Status: AppointmentStatusCancelled}, {Status: AppointmentStatusRescheduled}}
+	assert.NoError(t, service.CheckDailyLimit(booked), "cancelled and rescheduled appointments give their booking back")
+
+	booked = append(booked, &Appointment{Status: AppointmentStatusPending})
+	assert.ErrorIs(t, service.CheckDailyLimit(booked), ErrValidation)
+
+	service.MaxPerDay = nil
+	assert.NoError(t, service.CheckDailyLimit(booked))
+}
+
+func TestLocalDay(t *testing.T) {
+	lisbon, err := time.LoadLocation("Europe/Lisbon")
+	require.NoError(t, err)
+
+	day := LocalDay(time.Date(2026, 10, 17, 23, 30, 0, 0, time.UTC), lisbon)
+	assert.Equal(t, time.Date(2026, 10, 18, 0, 0, 0, 0, lisbon), day.Start, "23:30 UTC is past midnight in Lisbon summer time")
+	assert.Equal(t, time.Date(2026, 10, 19, 0, 0, 0, 0, lisbon), day.End)
+}
+
+func TestServiceValidateOnlineBooking(t *testing.T) {
+	newService := func() *Service {
+		return &Service{BusinessID: "business-1", Name: "Haircut", Duration: 30, Price: decimal.NewFromInt(25)}
+	}
+	assert.NoError(t, newService().Validate())
+
+	service := newService()
+	service.OnlineBooking = "by_appointment"
+	assert.ErrorIs(t, service.Validate(), ErrValidation)
+
+	for _, limit := range []int{0, MaxServiceBookingsPerDay + 1} {
+		service := newService()
+		service.MaxPerDay = &limit
+		assert.ErrorIs(t, service.Validate(), ErrValidation)
+	}
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// OutboundRequestRetention is how long outbound requests are kept for debugging
+const OutboundRequestRetention = 7 * 24 * time.Hour
+
+// OutboundRequest is a call made to a third-party provider. Bodies are scrubbed of personal
+// data before they are stored.
+type OutboundRequest struct {
+	BaseModel
+	Provider     string  `gorm:"not null;size:50;index" json:"provider"`
+	Method       string  `gorm:"not null;size:10" json:"method"`
+	Endpoint     string  `gorm:"not null;size:500" json:"endpoint"` // Without the query string
+	StatusCode   *int    `json:"status_code,omitempty"`             // Nil when no response was received
+	LatencyMs    int64   `gorm:"not null" json:"latency_ms"`
+	RequestBody  *string `gorm:"type:text" json:"request_body,omitempty"`
+	ResponseBody *string `gorm:"type:text" json:"response_body,omitempty"`
+	Error        *string `gorm:"type:text" json:"error,omitempty"` // Why no response was received
+}
+
+// TableName returns the table name for OutboundRequest
+func (OutboundRequest) TableName() string { return "outbound_requests" }
+
+// Failed reports whether the call failed, either without a response or with an error status
+func (r *OutboundRequest) Failed() bool {
+	return r.Error != nil || r.StatusCode == nil || *r.StatusCode >= 400
+}
+
+// OutboundRequestFilter selects outbound requests
+type OutboundRequestFilter struct {
+	Provider   *string
+	FailedOnly bool
+	StartDate  *time.Time
+	EndDate    *time.Time
+	Limit      int
+}
+
+// OutboundRequestRepository defines the repository interface for OutboundRequest
+type OutboundRequestRepository interface {
+	BaseRepository[OutboundRequest]
+	// FindByFilter finds the outbound requests matching the filter, most recent first
+	FindByFilter(ctx context.Context, filter OutboundRequestFilter) ([]*OutboundRequest, error)
+	// DeleteBefore permanently deletes the outbound requests made before a time
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,211 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// PaymentKind tells what a card payment is for
+type PaymentKind string
+
+const (
+	PaymentKindDeposit PaymentKind = "deposit" // Taken at booking
+	PaymentKindBalance PaymentKind = "balance" // Taken at completion, less the deposits
+)
+
+// PaymentStatus represents the status of a card payment
+type PaymentStatus string
+
+const (
+	PaymentStatusPending           PaymentStatus = "pending"
+	PaymentStatusRequiresAction    PaymentStatus = "requires_action" // The client has to authenticate the card
+	PaymentStatusSucceeded         PaymentStatus = "succeeded"
+	PaymentStatusFailed            PaymentStatus = "failed"
+	PaymentStatusPartiallyRefunded PaymentStatus = "partially_refunded"
+	PaymentStatusRefunded          PaymentStatus = "refunded"
+)
+
+// AppointmentPaymentStatus is the payment state of an appointment as a whole
+type AppointmentPaymentStatus string
+
+const (
+	AppointmentPaymentPending  AppointmentPaymentStatus = "pending"
+	AppointmentPaymentPartial  AppointmentPaymentStatus = "partial"
+	AppointmentPaymentPaid     AppointmentPaymentStatus = "paid"
+	AppointmentPaymentRefunded AppointmentPaymentStatus = "refunded"
+)
+
+// PaymentMethodCard is the service completion payment method of payments taken by card
+const PaymentMethodCard = "card"
+
+// zeroDecimalCurrencies are the currencies the payment provider charges in whole units
+var zeroDecimalCurrencies = map[string]bool{
+	"BIF": true, "CLP": true, "DJF": true, "GNF": true, "JPY": true, "KMF": true, "KRW": true, "MGA": true,
+	"PYG": true, "RWF": true, "UGX": true, "VND": true, "VUV": true, "XAF": true, "XOF": true, "XPF": true,
+}
+
+// Payment is a payment for an appointment, taken by card through the payment provider or
+// redeemed from a gift card
+type Payment struct {
+	BaseModel
+	BusinessID          string          `gorm:"not null;type:uuid;index" json:"business_id"`
+	AppointmentID       string          `gorm:"not null;type:uuid;index" json:"appointment_id"`
+	ServiceCompletionID *string         `gorm:"type:uuid" json:"service_completion_id,omitempty"`
+	Kind                PaymentKind     `gorm:"not null;size:20" json:"kind"`
+	Status              PaymentStatus   `gorm:"not null;size:20;default:'pending'" json:"status"`
+	Amount              decimal.Decimal `gorm:"type:decimal(10,2);not null" json:"amount"`
+	RefundedAmount      decimal.Decimal `gorm:"type:decimal(10,2);not null;default:0" json:"refunded_amount"`
+	Currency            string          `gorm:"not null;size:3" json:"currency"`
+	PaymentIntentID     *string         `gorm:"size:255;uniqueIndex" json:"payment_intent_id,omitempty"`
+	GiftCardID          *string         `gorm:"type:uuid;index" json:"gift_card_id,omitempty"` // Set for payments redeemed from a gift card
+	FailureReason       *string         `gorm:"type:text" json:"failure_reason,omitempty"`
+	PaidAt              *time.Time      `gorm:"" json:"paid_at,omitempty"`
+}
+
+// TableName returns the table name for Payment
+func (Payment) TableName() string { return "payments" }
+
+// Validate validates the payment model
+func (p *Payment) Validate() error {
+	if p.BusinessID == "" || p.AppointmentID == "" {
+		return ErrValidation
+	}
+	if p.Kind != PaymentKindDeposit && p.Kind != PaymentKindBalance {
+		return fmt.Errorf("%w: unknown payment kind %q", ErrValidation, p.Kind)
+	}
+	if !p.Amount.IsPositive() {
+		return fmt.Errorf("%w: the amount must be positive", ErrValidation)
+	}
+	if len(p.Currency) != 3 {
+		return fmt.Errorf("%w: the currency must be a three-letter code", ErrValidation)
+	}
+	return nil
+}
+
+// IsPaid reports whether the money was taken, even if some of it was refunded since
+func (p *Payment) IsPaid() bool {
+	return p.Status == PaymentStatusSucceeded || p.Status == PaymentStatusPartiallyRefunded
+}
+
+// IsOpen reports whether the payment may still go through
+func (p *Payment) IsOpen() bool {
+	return p.Status == PaymentStatusPending || p.Status == PaymentStatusRequiresAction
+}
+
+// NetAmount returns the amount kept after refunds, or zero when nothing was taken
+func (p *Payment) NetAmount() decimal.Decimal {
+	if !p.IsPaid() && p.Status != PaymentStatusRefunded {
+		return decimal.Zero
+	}
+	return p.Amount.Sub(p.RefundedAmount)
+}
+
+// RefundableAmount returns how much of the payment can still be refunded
+func (p *Payment) RefundableAmount() decimal.Decimal {
+	if !p.IsPaid() {
+		return decimal.Zero
+	}
+	return p.Amount.Sub(p.RefundedAmount)
+}
+
+// RecordRefund adds a refund to the payment and updates its status
+func (p *Payment) RecordRefund(amount decimal.Decimal) error {
+	if !p.IsPaid() {
+		return fmt.Errorf("%w: only succeeded payments can be refunded", ErrValidation)
+	}
+	if !amount.IsPositive() {
+		return fmt.Errorf("%w: the refund must be positive", ErrValidation)
+	}
+	if amount.GreaterThan(p.RefundableAmount()) {
+		return fmt.Errorf("%w: at most %s can be refunded", ErrValidation, p.RefundableAmount().StringFixed(2))
+	}
+
+	p.RefundedAmount = p.RefundedAmount.Add(amount)
+	if p.RefundedAmount.Equal(p.Amount) {
+		p.Status = PaymentStatusRefunded
+	} else {
+		p.Status = PaymentStatusPartiallyRefunded
+	}
+	return nil
+}
+
+// MarkFailed records why the payment did not go through
+func (p *Payment) MarkFailed(reason string) {
+	p.Status = PaymentStatusFailed
+	p.FailureReason = &reason
+}
+
+// ToMinorUnits converts an amount to the smallest unit of its currency, as payment providers
+// expect it
+func ToMinorUnits(amount decimal.Decimal, currency string) int64 {
+	if zeroDecimalCurrencies[strings.ToUpper(currency)] {
+		return amount.Round(0).IntPart()
+	}
+	return amount.Shift(2).Round(0).IntPart()
+}
+
+// FromMinorUnits converts an amount in the smallest unit of its currency back to a decimal
+func FromMinorUnits(amount int64, currency string) decimal.Decimal {
+	if zeroDecimalCurrencies[strings.ToUpper(currency)] {
+		return decimal.NewFromInt(amount)
+	}
+	return decimal.New(amount, -2)
+}
+
+// NetPaid returns the total kept from an appointment's payments
+func NetPaid(payments []*Payment) decimal.Decimal {
+	total := decimal.Zero
+	for _, payment := range payments {
+		total = total.Add(payment.NetAmount())
+	}
+	return total
+}
+
+// BalanceDue returns what is left to pay of a price after the appointment's payments
+func BalanceDue(price decimal.Decimal, payments []*Payment) decimal.Decimal {
+	return decimal.Max(price.Sub(NetPaid(payments)), decimal.Zero)
+}
+
+// SummarizePayments works out the payment status of an appointment with the given price. A
+// zero price means the price is not known yet, so any payment counts as partial.
+func SummarizePayments(price decimal.Decimal, payments []*Payment) AppointmentPaymentStatus {
+	net := NetPaid(payments)
+	if !net.IsPositive() {
+		for _, payment := range payments {
+			if payment.RefundedAmount.IsPositive() {
+				return AppointmentPaymentRefunded
+			}
+		}
+		return AppointmentPaymentPending
+	}
+	if price.IsPositive() && net.GreaterThanOrEqual(price) {
+		return AppointmentPaymentPaid
+	}
+	return AppointmentPaymentPartial
+}
+
+// ErrDepositAlreadyCharged is returned when a deposit is charged for an appointment that
+// already has one paid or in progress, as when two charges race
+var ErrDepositAlreadyCharged = errors.New("a deposit has already been charged for the appointment")
+
+// PaymentRepository defines the repository interface for Payment
+type PaymentRepository interface {
+	BaseRepository[Payment]
+	FindByAppointmentID(ctx context.Context, appointmentID string) ([]*Payment, error)
+	// GetByIDForUpdate retrieves a payment and locks it until the unit of work the call runs in
+	// ends, so that concurrent refunds of it take turns
+	GetByIDForUpdate(ctx context.Context, id string) (*Payment, error)
+	// FindDepositDue sums the deposits of the services booked for an appointment
+	FindDepositDue(ctx context.Context, appointmentID string) (decimal.Decimal, error)
+	SetAppointmentPaymentStatus(ctx context.Context, appointmentID string, status AppointmentPaymentStatus) error
+	// SetServiceDeposit sets the deposit charged at booking for a service
+	SetServiceDeposit(ctx context.Context, serviceID string, requiresDeposit bool, amount *decimal.Decimal) error
+	// RecordCompletion creates the completion of an appointment paid by card or gift card and marks the
+	// appointment completed at the charged price
+	RecordCompletion(ctx context.Context, completion *ServiceCompletion) error
+}
@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func paidPayment(amount, refunded string) *Payment {
+	return &Payment{
+		Kind:           PaymentKindDeposit,
+		Status:         PaymentStatusSucceeded,
+		Amount:         decimal.RequireFromString(amount),
+		RefundedAmount: decimal.RequireFromString(refunded),
+		Currency:       "EUR",
+	}
+}
+
+func TestPayment_Validate(t *testing.T) {
+	payment := &Payment{BusinessID: "business-1", AppointmentID: "appointment-1", Kind: PaymentKindDeposit, Amount: decimal.NewFromInt(10), Currency: "EUR"}
+	assert.NoError(t, payment.Validate())
+
+	zero := *payment
+	zero.Amount = decimal.Zero
+	assert.ErrorIs(t, zero.Validate(), ErrValidation)
+
+	unknown := *payment
+	unknown.Kind = "tip"
+	assert.ErrorIs(t, unknown.Validate(), ErrValidation)
+}
+
+func TestPayment_RecordRefund(t *testing.T) {
+	payment := paidPayment("30", "0")
+
+	require.NoError(t, payment.RecordRefund(decimal.NewFromInt(10)))
+	assert.Equal(t, PaymentStatusPartiallyRefunded, payment.Status)
+	assert.True(t, payment.RefundableAmount().Equal(decimal.NewFromInt(20)))
+
+	assert.ErrorIs(t, payment.RecordRefund(decimal.NewFromInt(25)), ErrValidation, "cannot refund more than is left")
+
+	require.NoError(t, payment.RecordRefund(decimal.NewFromInt(20)))
+	assert.Equal(t, PaymentStatusRefunded, payment.Status)
+	assert.True(t, payment.NetAmount().IsZero())
+	assert.ErrorIs(t, payment.RecordRefund(decimal.NewFromInt(1)), ErrValidation)
+
+	failed := &Payment{Status: PaymentStatusFailed, Amount: decimal.NewFromInt(30)}
+	assert.ErrorIs(t, failed.RecordRefund(decimal.NewFromInt(1)), ErrValidation)
+}
+
+func TestMinorUnits(t *testing.T) {
+	assert.Equal(t, int64(1999), ToMinorUnits(decimal.RequireFromString("19.99"), "EUR"))
+	assert.Equal(t, int64(2000), ToMinorUnits(decimal.RequireFromString("1999.6"), "jpy"))
+	assert.True(t, FromMinorUnits(1999, "EUR").Equal(decimal.RequireFromString("19.99")))
+	assert.True(t, FromMinorUnits(2000, "JPY").Equal(decimal.NewFromInt(2000)))
+}
+
+func TestBalanceDueAndSummary(t *testing.T) {
+	price := decimal.NewFromInt(80)
+	failed := &Payment{Status: PaymentStatusFailed, Amount: decimal.NewFromInt(80)}
+
+	assert.Equal(t, AppointmentPaymentPending, SummarizePayments(price, []*Payment{failed}))
+	assert.True(t, BalanceDue(price, []*Payment{failed}).Equal(price), "failed payments do not count")
+
+	deposit := paidPayment("20", "5")
+	assert.True(t, BalanceDue(price, []*Payment{deposit}).Equal(decimal.NewFromInt(65)))
+	assert.Equal(t, AppointmentPaymentPartial, SummarizePayments(price, []*Payment{deposit}))
+	assert.Equal(t, AppointmentPaymentPartial, SummarizePayments(decimal.Zero, []*Payment{deposit}), "the price is not known yet")
+
+	balance := paidPayment("65", "0")
+	assert.True(t, BalanceDue(price, []*Payment{deposit, balance}).IsZero())
+	assert.Equal(t, AppointmentPaymentPaid, SummarizePayments(price, []*Payment{deposit, balance}))
+
+	refunded := paidPayment("20", "0")
+	require.NoError(t, refunded.RecordRefund(decimal.NewFromInt(20)))
+	assert.Equal(t, AppointmentPaymentRefunded, SummarizePayments(price, []*Payment{refunded}))
+}
@@ -0,0 +1,246 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Permission is something a member of staff may do in a business, written as a resource and
+// an action on it, e.g. "appointments:cancel"
+type Permission string
+
+// Resource returns the resource the permission is about
+func (p Permission) Resource() string {
+	resource, _, _ := strings.Cut(string(p), ":")
+	return resource
+}
+
+// Action returns what the permission allows doing to its resource
+func (p Permission) Action() string {
+	_, action, _ := strings.Cut(string(p), ":")
+	return action
+}
+
+// IsValid checks if the permission is in the catalog
+func (p Permission) IsValid() bool {
+	_, ok := permissionIndex[p]
+	return ok
+}
+
+const (
+	PermissionAppointmentsView   Permission = "appointments:view"
+	PermissionAppointmentsCreate Permission = "appointments:create"
+	PermissionAppointmentsEdit   Permission = "appointments:edit"
+	PermissionAppointmentsCancel Permission = "appointments:cancel"
+	PermissionClientsView        Permission = "clients:view"
+	PermissionClientsEdit        Permission = "clients:edit"
+	PermissionClientsExport      Permission = "clients:export"
+	PermissionClientsDelete      Permission = "clients:delete"
+	PermissionServicesView       Permission = "services:view"
+	PermissionServicesEdit       Permission = "services:edit"
+	PermissionStaffView          Permission = "staff:view"
+	PermissionStaffEdit          Permission = "staff:edit"
+	PermissionStaffPermissions   Permission = "staff:permissions"
+	PermissionStaffPay           Permission = "staff:pay"
+	PermissionPaymentsView       Permission = "payments:view"
+	PermissionPaymentsTake       Permission = "payments:take"
+	PermissionPaymentsRefund     Permission = "payments:refund"
+	PermissionReportsView        Permission = "reports:view"
+	PermissionCampaignsView      Permission = "campaigns:view"
+	PermissionCampaignsSend      Permission = "campaigns:send"
+	PermissionReviewsModerate    Permission = "reviews:moderate"
+	PermissionSettingsEdit       Permission = "settings:edit"
+	PermissionAuditLogView       Permission = "audit_log:view"
+)
+
+// PermissionDefinition describes a permission of the catalog
+type PermissionDefinition struct {
+	Permission  Permission
+	Description string
+}
+
+// permissionCatalog lists every permission a member of staff may be given, grouped by resource
+var permissionCatalog = []PermissionDefinition{
+	{PermissionAppointmentsView, "See the appointment book"},
+	{PermissionAppointmentsCreate, "Book appointments"},
+	{PermissionAppointmentsEdit, "Change and reschedule appointments"},
+	{PermissionAppointmentsCancel, "Cancel appointments"},
+	{PermissionClientsView, "See client records"},
+	{PermissionClientsEdit, "Add and change client records"},
+	{PermissionClientsExport, "Export client data"},
+	{PermissionClientsDelete, "Delete client records"},
+	{PermissionServicesView, "See the service menu"},
+	{PermissionServicesEdit, "Change services, prices and online booking rules"},
+	{PermissionStaffView, "See the staff list and schedules"},
+	{PermissionStaffEdit, "Add staff and change their schedules"},
+	{PermissionStaffPermissions, "Change what other staff may do"},
+	{PermissionStaffPay, "See and set the commission plans and earnings of staff"},
+	{PermissionPaymentsView, "See payments"},
+	{PermissionPaymentsTake, "Take payments and deposits"},
+	{PermissionPaymentsRefund, "Refund payments"},
+	{PermissionReportsView, "See sales and performance reports"},
+	{PermissionCampaignsView, "See marketing campaigns"},
+	{PermissionCampaignsSend, "Create and send marketing campaigns"},
+	{PermissionReviewsModerate, "Publish, reject and reply to client reviews"},
+	{PermissionSettingsEdit, "Change the business settings"},
+	{PermissionAuditLogView, "Read the audit log"},
+}
+
+// permissionIndex indexes the catalog by permission
+var permissionIndex = func() map[Permission]PermissionDefinition {
+	index := make(map[Permission]PermissionDefinition, len(permissionCatalog))
+	for _, definition := range permissionCatalog {
+		index[definition.Permission] = definition
+	}
+	return index
+}()
+
+// PermissionCatalog returns every permission a member of staff may be given
+func PermissionCatalog() []PermissionDefinition {
+	return slices.Clone(permissionCatalog)
+}
+
+// rolePresets are the permissions each role comes with. Owners have every permission.
+var rolePresets = map[BusinessRole][]Permission{
+	BusinessRoleManager: {
+		PermissionAppointmentsView, PermissionAppointmentsCreate, PermissionAppointmentsEdit, PermissionAppointmentsCancel,
+		PermissionClientsView, PermissionClientsEdit, PermissionClientsExport,
+		PermissionServicesView, PermissionServicesEdit,
+		PermissionStaffView, PermissionStaffEdit, PermissionStaffPermissions, PermissionStaffPay,
+		PermissionPaymentsView, PermissionPaymentsTake, PermissionPaymentsRefund,
+		PermissionReportsView, PermissionCampaignsView, PermissionCampaignsSend,
+		PermissionReviewsModerate, PermissionSettingsEdit,
+	},
+	BusinessRoleEmployee: {
+		PermissionAppointmentsView, PermissionAppointmentsCreate, PermissionAppointmentsEdit, PermissionAppointmentsCancel,
+		PermissionClientsView, PermissionClientsEdit,
+		PermissionServicesView, PermissionStaffView,
+		PermissionPaymentsView, PermissionPaymentsTake,
+	},
+	BusinessRoleAssistant: {
+		PermissionAppointmentsView, PermissionAppointmentsCreate,
+		PermissionClientsView, PermissionServicesView, PermissionStaffView,
+	},
+}
+
+// RolePermissions returns the permissions a role comes with, in catalog order
+func RolePermissions(role BusinessRole) []Permission {
+	if role == BusinessRoleOwner {
+		permissions := make([]Permission, len(permissionCatalog))
+		for i, definition := range permissionCatalog {
+			permissions[i] = definition.Permission
+		}
+		return permissions
+	}
+	return sortPermissions(slices.Clone(rolePresets[role]))
+}
+
+// StaffPermissions is how a member of staff's permissions differ from the preset of their
+// role, as stored in Staff.Permissions
+type StaffPermissions struct {
+	Grants  []Permission `json:"grants,omitempty"`  // Permissions given beyond the role's
+	Revokes []Permission `json:"revokes,omitempty"` // Permissions of the role taken away
+}
+
+// Validate checks that the permissions are in the catalog and that none is both granted and
+// revoked
+func (p StaffPermissions) Validate() error {
+	for _, permission := range append(slices.Clone(p.Grants), p.Revokes...) {
+		if !permission.IsValid() {
+			return fmt.Errorf("%w: unknown permission %q", ErrValidation, permission)
+		}
+	}
+	for _, permission := range p.Grants {
+		if slices.Contains(p.Revokes, permission) {
+			return fmt.Errorf("%w: permission %q cannot be both granted and revoked", ErrValidation, permission)
+		}
+	}
+	return nil
+}
+
+// Normalize drops duplicates, grants the role already has and revokes it does not have, so
+// that only the actual differences from the role are stored
+func (p StaffPermissions) Normalize(role BusinessRole) StaffPermissions {
+	preset := RolePermissions(role)
+	normalized := StaffPermissions{}
+	for _, permission := range p.Grants {
+		if !slices.Contains(preset, permission) && !slices.Contains(normalized.Grants, permission) {
+			normalized.Grants = append(normalized.Grants, permission)
+		}
+	}
+	for _, permission := range p.Revokes {
+		if slices.Contains(preset, permission) && !slices.Contains(normalized.Revokes, permission) {
+			normalized.Revokes = append(normalized.Revokes, permission)
+		}
+	}
+	sortPermissions(normalized.Grants)
+	sortPermissions(normalized.Revokes)
+	return normalized
+}
+
+// ParseStaffPermissions decodes stored permissions. Nothing stored, or the empty object staff
+// are created with, means the role's preset as is.
+func ParseStaffPermissions(stored *string) (StaffPermissions, error) {
+	var permissions StaffPermissions
+	if stored == nil || strings.TrimSpace(*stored) == "" {
+		return permissions, nil
+	}
+	if err := json.Unmarshal([]byte(*stored), &permissions); err != nil {
+		return StaffPermissions{}, fmt.Errorf("%w: invalid staff permissions: %v", ErrValidation, err)
+	}
+	return permissions, nil
+}
+
+// Encode encodes the permissions for storing in Staff.Permissions
+func (p StaffPermissions) Encode() (*string, error) {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode staff permissions: %w", err)
+	}
+	encoded := string(data)
+	return &encoded, nil
+}
+
+// Effective returns the permissions a member of staff with a role has: the role's preset with
+// the grants added and the revokes taken away. Owners always have every permission.
+func (p StaffPermissions) Effective(role BusinessRole) []Permission {
+	if role == BusinessRoleOwner {
+		return RolePermissions(role)
+	}
+	effective := []Permission{}
+	for _, permission := range append(RolePermissions(role), p.Grants...) {
+		if permission.IsValid() && !slices.Contains(p.Revokes, permission) && !slices.Contains(effective, permission) {
+			effective = append(effective, permission)
+		}
+	}
+	return sortPermissions(effective)
+}
+
+// EffectivePermissions returns what the staff member may do in the business. Permissions that
+// cannot be read fall back to the role's preset.
+func (s Staff) EffectivePermissions() []Permission {
+	permissions, err := ParseStaffPermissions(s.Permissions)
+	if err != nil {
+		return RolePermissions(s.Role)
+	}
+	return permissions.Effective(s.Role)
+}
+
+// Can reports whether the staff member has a permission
+func (s Staff) Can(permission Permission) bool {
+	return s.IsActive && slices.Contains(s.EffectivePermissions(), permission)
+}
+
+// sortPermissions sorts permissions in catalog order, unknown ones last
+func sortPermissions(permissions []Permission) []Permission {
+	position := func(p Permission) int {
+		if i := slices.IndexFunc(permissionCatalog, func(d PermissionDefinition) bool { return d.Permission == p }); i >= 0 {
+			return i
+		}
+		return len(permissionCatalog)
+	}
+	slices.SortStableFunc(permissions, func(a, b Permission) int { return position(a) - position(b) })
+	return permissions
+}
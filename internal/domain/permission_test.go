@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPermissionCatalog(t *testing.T) {
+	for _, definition := range PermissionCatalog() {
+		assert.NotEmpty(t, definition.Permission.Resource(), definition.Permission)
+		assert.NotEmpty(t, definition.Permission.Action(), definition.Permission)
+		assert.NotEmpty(t, definition.Description, definition.Permission)
+	}
+	for role, preset := range rolePresets {
+		for _, permission := range preset {
+			assert.True(t, permission.IsValid(), "%s preset has unknown permission %s", role, permission)
+		}
+	}
+	assert.Len(t, RolePermissions(BusinessRoleOwner), len(PermissionCatalog()))
+	assert.False(t, Permission("appointments:teleport").IsValid())
+}
+
+func TestStaffPermissionsValidate(t *testing.T) {
+	assert.NoError(t, StaffPermissions{Grants: []Permission{PermissionReportsView}, Revokes: []Permission{PermissionAppointmentsCancel}}.Validate())
+	assert.ErrorIs(t, StaffPermissions{Grants: []Permission{"reports:everything"}}.Validate(), ErrValidation)
+	assert.ErrorIs(t, StaffPermissions{Grants: []Permission{PermissionReportsView}, Revokes: []Permission{PermissionReportsView}}.Validate(), ErrValidation)
+}
+
+func TestStaffPermissionsEffective(t *testing.T) {
+	permissions := StaffPermissions{
+		Grants:  []Permission{PermissionReportsView, PermissionAppointmentsView},
+		Revokes: []Permission{PermissionAppointmentsCancel, PermissionSettingsEdit},
+	}.Normalize(BusinessRoleEmployee)
+
+	assert.Equal(t, []Permission{PermissionReportsView}, permissions.Grants, "grants the role already has are dropped")
+	assert.Equal(t, []Permission{PermissionAppointmentsCancel}, permissions.Revokes, "revokes the role does not have are dropped")
+
+	effective := permissions.Effective(BusinessRoleEmployee)
+	assert.Contains(t, effective, PermissionReportsView)
+	assert.Contains(t, effective, PermissionAppointmentsEdit)
+	assert.NotContains(t, effective, PermissionAppointmentsCancel)
+
+	assert.Equal(t, RolePermissions(BusinessRoleOwner), permissions.Effective(BusinessRoleOwner), "owners always have every permission")
+}
+
+func TestStaffCan(t *testing.T) {
+	stored, err := StaffPermissions{Revokes: []Permission{PermissionClientsView}}.Encode()
+	require.NoError(t, err)
+
+	staff := Staff{Role: BusinessRoleAssistant, IsActive: true, Permissions: stored}
+	assert.True(t, staff.Can(PermissionAppointmentsView))
+	assert.False(t, staff.Can(PermissionClientsView))
+
+	legacy := "{}"
+	staff.Permissions = &legacy
+	assert.True(t, staff.Can(PermissionClientsView), "staff created without permissions have their role's")
+
+	staff.IsActive = false
+	assert.False(t, staff.Can(PermissionAppointmentsView), "inactive staff may do nothing")
+}
@@ -0,0 +1,217 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// PriceAdjustmentType represents how a bulk price change modifies prices
+type PriceAdjustmentType string
+
+const (
+	PriceAdjustmentPercentage PriceAdjustmentType = "percentage" // Amount is a percentage of the current price
+	PriceAdjustmentFixed      PriceAdjustmentType = "fixed"      // Amount is added to the current price
+)
+
+// PriceChangeStatus represents the lifecycle of a bulk price change
+type PriceChangeStatus string
+
+const (
+	PriceChangeScheduled PriceChangeStatus = "scheduled"
+	PriceChangeApplied   PriceChangeStatus = "applied"
+	PriceChangeCancelled PriceChangeStatus = "cancelled"
+	PriceChangeFailed    PriceChangeStatus = "failed" // Applying it would have made a price negative
+)
+
+// PriceAdjustment is a percentage or fixed change to a price. Negative amounts lower prices.
+type PriceAdjustment struct {
+	Type   PriceAdjustmentType `json:"type"`
+	Amount decimal.Decimal     `json:"amount"`
+}
+
+// Validate validates the adjustment
+func (a PriceAdjustment) Validate() error {
+	switch a.Type {
+	case PriceAdjustmentPercentage:
+		if a.Amount.LessThanOrEqual(decimal.NewFromInt(-100)) {
+			return fmt.Errorf("%w: a percentage decrease must be less than 100%%", ErrValidation)
+		}
+	case PriceAdjustmentFixed:
+	default:
+		return fmt.Errorf("%w: invalid adjustment type %q", ErrValidation, a.Type)
+	}
+	if a.Amount.IsZero() {
+		return fmt.Errorf("%w: the adjustment amount must not be zero", ErrValidation)
+	}
+	return nil
+}
+
+// Apply returns the adjusted price, rounded to cents
+func (a PriceAdjustment) Apply(price decimal.Decimal) (decimal.Decimal, error) {
+	var adjusted decimal.Decimal
+	switch a.Type {
+	case PriceAdjustmentPercentage:
+		adjusted = price.Add(price.Mul(a.Amount).Div(decimal.NewFromInt(100)))
+	case PriceAdjustmentFixed:
+		adjusted = price.Add(a.Amount)
+	default:
+		return decimal.Zero, fmt.Errorf("%w: invalid adjustment type %q", ErrValidation, a.Type)
+	}
+	adjusted = adjusted.Round(2)
+	if adjusted.IsNegative() {
+		return decimal.Zero, fmt.Errorf("%w: the price %s would become negative", ErrValidation, price.StringFixed(2))
+	}
+	return adjusted, nil
+}
+
+// PriceChange is a bulk price adjustment of selected services and categories, applied on its
+// effective date to the prices current at that time
+type PriceChange struct {
+	BaseModel
+	BusinessID     string              `gorm:"not null;type:uuid;index" json:"business_id"`
+	AdjustmentType PriceAdjustmentType `gorm:"not null;size:20" json:"adjustment_type"`
+	Amount         decimal.Decimal     `gorm:"type:decimal(10,2);not null" json:"amount"`
+	ServiceIDs     *string             `gorm:"type:jsonb;default:'[]'" json:"service_ids,omitempty"`  // JSON array of service IDs
+	CategoryIDs    *string             `gorm:"type:jsonb;default:'[]'" json:"category_ids,omitempty"` // JSON array of category IDs
+	EffectiveAt    time.Time           `gorm:"not null" json:"effective_at"`
+	Status         PriceChangeStatus   `gorm:"not null;size:20;default:'scheduled'" json:"status"`
+	AppliedAt      *time.Time          `json:"applied_at,omitempty"`
+	FailureReason  *string             `gorm:"type:text" json:"failure_reason,omitempty"`
+	Notes          *string             `gorm:"type:text" json:"notes,omitempty"`
+}
+
+// TableName returns the table name for PriceChange
+func (PriceChange) TableName() string { return "service_price_changes" }
+
+// Adjustment returns the price adjustment of the change
+func (c *PriceChange) Adjustment() PriceAdjustment {
+	return PriceAdjustment{Type: c.AdjustmentType, Amount: c.Amount}
+}
+
+// Validate validates the price change model
+func (c *PriceChange) Validate() error {
+	if c.BusinessID == "" {
+		return ErrValidation
+	}
+	if err := c.Adjustment().Validate(); err != nil {
+		return err
+	}
+	serviceIDs, err := c.GetServiceIDs()
+	if err != nil {
+		return err
+	}
+	categoryIDs, err := c.GetCategoryIDs()
+	if err != nil {
+		return err
+	}
+	if len(serviceIDs) == 0 && len(categoryIDs) == 0 {
+		return fmt.Errorf("%w: select at least one service or category", ErrValidation)
+	}
+	return nil
+}
+
+// GetServiceIDs decodes the IDs of the selected services
+func (c *PriceChange) GetServiceIDs() ([]string, error) {
+	return decodeIDs(c.ServiceIDs)
+}
+
+// GetCategoryIDs decodes the IDs of the selected categories
+func (c *PriceChange) GetCategoryIDs() ([]string, error) {
+	return decodeIDs(c.CategoryIDs)
+}
+
+// SetTargets encodes the selected services and categories
+func (c *PriceChange) SetTargets(serviceIDs, categoryIDs []string) error {
+	var err error
+	if c.ServiceIDs, err = encodeIDs(serviceIDs); err != nil {
+		return err
+	}
+	c.CategoryIDs, err = encodeIDs(categoryIDs)
+	return err
+}
+
+// IsDue checks whether a scheduled change should be applied
+func (c *PriceChange) IsDue(now time.Time) bool {
+	return c.Status == PriceChangeScheduled && !c.EffectiveAt.After(now)
+}
+
+func decodeIDs(raw *string) ([]string, error) {
+	ids := []string{}
+	if raw == nil || *raw == "" {
+		return ids, nil
+	}
+	if err := json.Unmarshal([]byte(*raw), &ids); err != nil {
+		return nil, fmt.Errorf("%w: invalid ID list", ErrValidation)
+	}
+	return ids, nil
+}
+
+func encodeIDs(ids []string) (*string, error) {
+	if ids == nil {
+		ids = []string{}
+	}
+	data, err := json.Marshal(ids)
+	if err != nil {
+		return nil, err
+	}
+	encoded := string(data)
+	return &encoded, nil
+}
+
+// PricePreview is a service's price before and after a price change
+type PricePreview struct {
+	ServiceID   string          `json:"service_id"`
+	ServiceName string          `json:"service_name"`
+	OldPrice    decimal.Decimal `json:"old_price"`
+	NewPrice    decimal.Decimal `json:"new_price"`
+}
+
+// PreviewPriceChange computes the new price of each service, failing if any would be negative
+func PreviewPriceChange(services []*Service, adjustment PriceAdjustment) ([]PricePreview, error) {
+	previews := make([]PricePreview, 0, len(services))
+	for _, service := range services {
+		newPrice, err := adjustment.Apply(service.Price)
+		if err != nil {
+			return nil, fmt.Errorf("%w (%s)", err, service.Name)
+		}
+		previews = append(previews, PricePreview{
+			ServiceID:   service.ID,
+			ServiceName: service.Name,
+			OldPrice:    service.Price,
+			NewPrice:    newPrice,
+		})
+	}
+	return previews, nil
+}
+
+// ServicePriceHistory records a change of a service's price
+type ServicePriceHistory struct {
+	ID            string          `gorm:"primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	BusinessID    string          `gorm:"not null;type:uuid" json:"business_id"`
+	ServiceID     string          `gorm:"not null;type:uuid;index" json:"service_id"`
+	PriceChangeID *string         `gorm:"type:uuid" json:"price_change_id,omitempty"` // The bulk change that set the price
+	OldPrice      decimal.Decimal `gorm:"type:decimal(10,2);not null" json:"old_price"`
+	NewPrice      decimal.Decimal `gorm:"type:decimal(10,2);not null" json:"new_price"`
+	ChangedAt     time.Time       `gorm:"not null" json:"changed_at"`
+	ChangedBy     *string         `gorm:"type:uuid" json:"changed_by,omitempty"`
+}
+
+// TableName returns the table name for ServicePriceHistory
+func (ServicePriceHistory) TableName() string { return "service_price_history" }
+
+// PriceChangeRepository defines the repository interface for PriceChange
+type PriceChangeRepository interface {
+	BaseRepository[PriceChange]
+	FindByBusinessID(ctx context.Context, businessID string) ([]*PriceChange, error)
+	// FindTargetServices finds the business's services that are selected directly or through their category
+	FindTargetServices(ctx context.Context, businessID string, serviceIDs, categoryIDs []string) ([]*Service, error)
+	FindDue(ctx context.Context, now time.Time) ([]*PriceChange, error)
+	// Apply adjusts the current prices of the change's services, records their history and marks
+	// the change applied, atomically. Changes that are no longer scheduled are left untouched.
+	Apply(ctx context.Context, change *PriceChange, appliedAt time.Time) ([]*ServicePriceHistory, error)
+	FindHistoryByServiceID(ctx context.Context, serviceID string) ([]*ServicePriceHistory, error)
+}
@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceAdjustment_Apply(t *testing.T) {
+	price := decimal.RequireFromString("45.00")
+
+	tests := []struct {
+		name       string
+		adjustment PriceAdjustment
+		expected   string
+	}{
+		{"percentage increase", PriceAdjustment{Type: PriceAdjustmentPercentage, Amount: decimal.NewFromInt(10)}, "49.50"},
+		{"percentage decrease", PriceAdjustment{Type: PriceAdjustmentPercentage, Amount: decimal.NewFromInt(-15)}, "38.25"},
+		{"rounds to cents", PriceAdjustment{Type: PriceAdjustmentPercentage, Amount: decimal.RequireFromString("3.33")}, "46.50"},
+		{"fixed increase", PriceAdjustment{Type: PriceAdjustmentFixed, Amount: decimal.NewFromInt(5)}, "50.00"},
+		{"fixed decrease to zero", PriceAdjustment{Type: PriceAdjustmentFixed, Amount: decimal.NewFromInt(-45)}, "0.00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adjusted, err := tt.adjustment.Apply(price)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, adjusted.StringFixed(2))
+		})
+	}
+
+	_, err := PriceAdjustment{Type: PriceAdjustmentFixed, Amount: decimal.NewFromInt(-50)}.Apply(price)
+	assert.True(t, errors.Is(err, ErrValidation))
+}
+
+func TestPriceAdjustment_Validate(t *testing.T) {
+	assert.NoError(t, PriceAdjustment{Type: PriceAdjustmentPercentage, Amount: decimal.NewFromInt(-99)}.Validate())
+	assert.NoError(t, PriceAdjustment{Type: PriceAdjustmentFixed, Amount: decimal.NewFromInt(-200)}.Validate())
+
+	assert.Error(t, PriceAdjustment{Type: PriceAdjustmentPercentage, Amount: decimal.NewFromInt(-100)}.Validate())
+	assert.Error(t, PriceAdjustment{Type: PriceAdjustmentFixed, Amount: decimal.Zero}.Validate())
+	assert.Error(t, PriceAdjustment{Type: "double", Amount: decimal.NewFromInt(2)}.Validate())
+}
+
+func TestPriceChange_Validate(t *testing.T) {
+	change := &PriceChange{
+		BusinessID:     "business-1",
+		AdjustmentType: PriceAdjustmentPercentage,
+		Amount:         decimal.NewFromInt(5),
+		Status:         PriceChangeScheduled,
+	}
+	require.NoError(t, change.SetTargets(nil, nil))
+	assert.True(t, errors.Is(change.Validate(), ErrValidation), "a change must select something")
+
+	require.NoError(t, change.SetTargets([]string{"service-1"}, []string{"category-1"}))
+	require.NoError(t, change.Validate())
+
+	serviceIDs, err := change.GetServiceIDs()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"service-1"}, serviceIDs)
+	categoryIDs, err := change.GetCategoryIDs()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"category-1"}, categoryIDs)
+}
+
+func TestPriceChange_IsDue(t *testing.T) {
+	now := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	change := &PriceChange{Status: PriceChangeScheduled, EffectiveAt: now}
+
+	assert.True(t, change.IsDue(now))
+	assert.False(t, change.IsDue(now.Add(-time.Minute)))
+
+	change.Status = PriceChangeCancelled
+	assert.False(t, change.IsDue(now.Add(time.Hour)))
+}
+
+func TestPreviewPriceChange(t *testing.T) {
+	services := []*Service{
+		{BaseModel: BaseModel{ID: "cut"}, Name: "Cut", Price: decimal.NewFromInt(30)},
+		{BaseModel: BaseModel{ID: "colour"}, Name: "Colour", Price: decimal.NewFromInt(80)},
+	}
+
+	previews, err := PreviewPriceChange(services, PriceAdjustment{Type: PriceAdjustmentFixed, Amount: decimal.NewFromInt(-10)})
+	require.NoError(t, err)
+	require.Len(t, previews, 2)
+	assert.Equal(t, "cut", previews[0].ServiceID)
+	assert.Equal(t, "20", previews[0].NewPrice.String())
+	assert.Equal(t, "80", previews[1].OldPrice.String())
+	assert.Equal(t, "70", previews[1].NewPrice.String())
+
+	_, err = PreviewPriceChange(services, PriceAdjustment{Type: PriceAdjustmentFixed, Amount: decimal.NewFromInt(-40)})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Cut")
+}
@@ -0,0 +1,25 @@
+package domain
+
+import "strings"
+
+// OnlineBookingReferralSource is the referral source of clients who registered while booking online
+const OnlineBookingReferralSource = "online_booking"
+
+// NewOnlineBookingClient returns the client record of a person registering while booking online
+func NewOnlineBookingClient(businessID, firstName, lastName, email string, phone *string) *Client {
+	source := OnlineBookingReferralSource
+	return &Client{
+		BusinessID:     businessID,
+		FirstName:      strings.TrimSpace(firstName),
+		LastName:       strings.TrimSpace(lastName),
+		Email:          NormalizeEmail(email),
+		Phone:          phone,
+		IsActive:       true,
+		ReferralSource: &source,
+	}
+}
+
+// NormalizeEmail returns an email address in the form it is stored and compared in
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,17 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewOnlineBookingClient(t *testing.T) {
+	client := NewOnlineBookingClient("business-1", " Ana ", "Silva", " Ana.Silva@Example.com ", nil)
+	assert.Equal(t, "Ana", client.FirstName)
+	assert.Equal(t, "ana.silva@example.com", client.Email)
+	assert.True(t, client.IsActive)
+	require.NotNil(t, client.ReferralSource)
+	assert.Equal(t, OnlineBookingReferralSource, *client.ReferralSource)
+}
@@ -0,0 +1,165 @@
+package domain
+
+import (
+	"cmp"
+	"crypto/rand"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// referralCodeLength is the number of characters of a referral code, without the dash
+const referralCodeLength = 8
+
+// ReferralStatus tells whether a referred client has become a returning one yet
+type ReferralStatus string
+
+const (
+	ReferralPending   ReferralStatus = "pending"   // The referred client has not completed a visit yet
+	ReferralConverted ReferralStatus = "converted" // The referred client completed their first visit
+)
+
+// NewReferralCode generates a random referral code, in the same alphabet and grouping as gift
+// card codes so that it is as easy to read out
+func NewReferralCode() (string, error) {
+	buf := make([]byte, referralCodeLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate referral code: %w", err)
+	}
+	code := make([]byte, len(buf))
+	for i, b := range buf {
+		code[i] = giftCardCodeAlphabet[int(b)%len(giftCardCodeAlphabet)]
+	}
+	return NormalizeReferralCode(string(code)), nil
+}
+
+// NormalizeReferralCode brings a code as typed by a client to its stored form
+func NormalizeReferralCode(code string) string {
+	return NormalizeGiftCardCode(code)
+}
+
+// Referral records that a member of a loyalty program brought a new client to the business
+type Referral struct {
+	BaseModel
+	BusinessID           string         `gorm:"not null;type:uuid;index" json:"business_id"`
+	ProgramID            string         `gorm:"not null;type:uuid;index" json:"program_id"`
+	ReferrerMembershipID string         `gorm:"not null;type:uuid;index" json:"referrer_membership_id"`
+	ReferrerClientID     string         `gorm:"not null;type:uuid" json:"referrer_client_id"`
+	ReferredClientID     string         `gorm:"not null;type:uuid" json:"referred_client_id"` // Referred once per business
+	Code                 string         `gorm:"not null;size:20" json:"code"`
+	Status               ReferralStatus `gorm:"not null;size:20;default:'pending'" json:"status"`
+	AppointmentID        *string        `gorm:"type:uuid" json:"appointment_id,omitempty"` // The referred client's first completed visit
+	ConvertedAt          *time.Time     `gorm:"" json:"converted_at,omitempty"`
+	BonusPoints          int            `gorm:"not null;default:0" json:"bonus_points"` // Awarded to the referrer on conversion
+}
+
+// TableName returns the table name for Referral
+func (Referral) TableName() string { return "referrals" }
+
+// NewReferral records a client referred by a member of the program with the member's code
+func NewReferral(program *LoyaltyProgram, referrer *ClientLoyaltyMembership, referredClientID string) (*Referral, error) {
+	if referrer.ProgramID != program.ID || referrer.ReferralCode == nil {
+		return nil, fmt.Errorf("%w: the referrer has no referral code in this program", ErrValidation)
+	}
+	if referrer.ClientID == referredClientID {
+		return nil, fmt.Errorf("%w: clients cannot refer themselves", ErrValidation)
+	}
+	return &Referral{
+		BusinessID:           program.BusinessID,
+		ProgramID:            program.ID,
+		ReferrerMembershipID: referrer.ID,
+		ReferrerClientID:     referrer.ClientID,
+		ReferredClientID:     referredClientID,
+		Code:                 *referrer.ReferralCode,
+		Status:               ReferralPending,
+	}, nil
+}
+
+// Convert marks the referral converted by the referred client's first completed visit,
+// recording the bonus the referrer was awarded for it
+func (r *Referral) Convert(appointmentID string, bonusPoints int, at time.Time) error {
+	if r.Status == ReferralConverted {
+		return fmt.Errorf("%w: the referral was already converted", ErrValidation)
+	}
+	r.Status = ReferralConverted
+	r.AppointmentID = &appointmentID
+	r.ConvertedAt = &at
+	r.BonusPoints = bonusPoints
+	return nil
+}
+
+// AwardReferralBonus adds the program's referral bonus to the referrer's membership, returning
+// the points awarded. Referral bonuses count towards tiers but are not visits.
+func (m *ClientLoyaltyMembership) AwardReferralBonus(rules LoyaltyRules, at time.Time) (int, error) {
+	if rules.ReferralBonus == 0 || !m.IsActiveAt(at) {
+		return 0, nil
+	}
+	if err := m.addPoints(rules, rules.ReferralBonus, at); err != nil {
+		return 0, err
+	}
+	return rules.ReferralBonus, nil
+}
+
+// ReferrerStats is how many clients a member referred and how many of them converted
+type ReferrerStats struct {
+	MembershipID string
+	ClientID     string
+	Referrals    int
+	Converted    int
+	BonusPoints  int
+}
+
+// ReferralStats sums up how well a business's referrals convert
+type ReferralStats struct {
+	Referrals            int
+	Converted            int
+	Pending              int
+	BonusPoints          int
+	ConversionRate       float64 // The share of referrals that converted, between 0 and 1
+	AverageDaysToConvert float64 // From the referral to the first completed visit
+	TopReferrers         []ReferrerStats
+}
+
+// SummarizeReferrals sums up the referrals, listing the top referrers by conversions
+func SummarizeReferrals(referrals []*Referral, top int) ReferralStats {
+	var stats ReferralStats
+	var daysToConvert float64
+	byReferrer := map[string]*ReferrerStats{}
+	for _, referral := range referrals {
+		referrer, ok := byReferrer[referral.ReferrerMembershipID]
+		if !ok {
+			referrer = &ReferrerStats{MembershipID: referral.ReferrerMembershipID, ClientID: referral.ReferrerClientID}
+			byReferrer[referral.ReferrerMembershipID] = referrer
+		}
+		stats.Referrals++
+		referrer.Referrals++
+		if referral.Status != ReferralConverted {
+			stats.Pending++
+			continue
+		}
+		stats.Converted++
+		stats.BonusPoints += referral.BonusPoints
+		referrer.Converted++
+		referrer.BonusPoints += referral.BonusPoints
+		if referral.ConvertedAt != nil {
+			daysToConvert += referral.ConvertedAt.Sub(referral.CreatedAt).Hours() / 24
+		}
+	}
+	if stats.Referrals > 0 {
+		stats.ConversionRate = float64(stats.Converted) / float64(stats.Referrals)
+	}
+	if stats.Converted > 0 {
+		stats.AverageDaysToConvert = daysToConvert / float64(stats.Converted)
+	}
+
+	for _, referrer := range byReferrer {
+		stats.TopReferrers = append(stats.TopReferrers, *referrer)
+	}
+	slices.SortFunc(stats.TopReferrers, func(a, b ReferrerStats) int {
+		return cmp.Or(cmp.Compare(b.Converted, a.Converted), cmp.Compare(b.Referrals, a.Referrals), cmp.Compare(a.ClientID, b.ClientID))
+	})
+	if len(stats.TopReferrers) > top {
+		stats.TopReferrers = stats.TopReferrers[:top]
+	}
+	return stats
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewReferralCode(t *testing.T) {
+	code, err := NewReferralCode()
+	require.NoError(t, err)
+	assert.Len(t, code, 9)
+	assert.Equal(t, code, NormalizeReferralCode(code))
+	assert.Equal(t, "ABCD-EFGH", NormalizeReferralCode("abcd efgh"))
+}
+
+func TestNewReferral(t *testing.T) {
+	program := &LoyaltyProgram{BaseModel: BaseModel{ID: "program-1"}, BusinessID: "business-1"}
+	code := "ABCD-EFGH"
+	referrer := &ClientLoyaltyMembership{BaseModel: BaseModel{ID: "membership-1"}, ProgramID: "program-1", ClientID: "client-1", ReferralCode: &code}
+
+	referral, err := NewReferral(program, referrer, "client-2")
+	require.NoError(t, err)
+	assert.Equal(t, "business-1", referral.BusinessID)
+	assert.Equal(t, "client-1", referral.ReferrerClientID)
+	assert.Equal(t, ReferralPending, referral.Status)
+
+	_, err = NewReferral(program, referrer, "client-1")
+	assert.ErrorIs(t, err, ErrValidation, "clients cannot refer themselves")
+
+	at := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, referral.Convert("appointment-1", 100, at))
+	assert.Equal(t, ReferralConverted, referral.Status)
+	assert.Equal(t, 100, referral.BonusPoints)
+	assert.ErrorIs(t, referral.Convert("appointment-2", 100, at), ErrValidation, "referrals convert once")
+}
+
+func TestClientLoyaltyMembership_AwardReferralBonus(t *testing.T) {
+	rules := LoyaltyRules{PointsPerVisit: 10, ReferralBonus: 100, Tiers: []LoyaltyTier{{Name: "silver", MinPoints: 100}}}
+	at := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	membership := &ClientLoyaltyMembership{IsActive: true}
+
+	points, err := membership.AwardReferralBonus(rules, at)
+	require.NoError(t, err)
+	assert.Equal(t, 100, points)
+	assert.Equal(t, 100, membership.CurrentPoints)
+	assert.Zero(t, membership.VisitsCount, "a bonus is not a visit")
+	assert.Equal(t, "silver", *membership.TierLevel)
+
+	membership.IsActive = false
+	points, err = membership.AwardReferralBonus(rules, at)
+	require.NoError(t, err)
+	assert.Zero(t, points, "inactive members are not awarded bonuses")
+}
+
+func TestSummarizeReferrals(t *testing.T) {
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	converted := start.Add(72 * time.Hour)
+	referral := func(membershipID string, status ReferralStatus) *Referral {
+		r := &Referral{BaseModel: BaseModel{CreatedAt: start}, ReferrerMembershipID: membershipID, ReferrerClientID: "client-" + membershipID, Status: status}
+		if status == ReferralConverted {
+			r.ConvertedAt, r.BonusPoints = &converted, 50
+		}
+		return r
+	}
+
+	stats := SummarizeReferrals([]*Referral{
+		referral("a", ReferralPending),
+		referral("a", ReferralPending),
+		referral("b", ReferralConverted),
+		referral("c", ReferralConverted),
+		referral("c", ReferralPending),
+	}, 2)
+	assert.Equal(t, 5, stats.Referrals)
+	assert.Equal(t, 2, stats.Converted)
+	assert.Equal(t, 3, stats.Pending)
+	assert.Equal(t, 100, stats.BonusPoints)
+	assert.InDelta(t, 0.4, stats.ConversionRate, 1e-9)
+	assert.InDelta(t, 3, stats.AverageDaysToConvert, 1e-9)
+	require.Len(t, stats.TopReferrers, 2)
+	assert.Equal(t, "c", stats.TopReferrers[0].MembershipID, "most conversions first, then most referrals")
+	assert.Equal(t, "b", stats.TopReferrers[1].MembershipID)
+
+	assert.Zero(t, SummarizeReferrals(nil, 10).ConversionRate)
+}
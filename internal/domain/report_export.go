@@ -0,0 +1,404 @@
+package domain
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ReportKind is a reporting query that can be exported
+type ReportKind string
+
+const (
+	ReportRevenue      ReportKind = "revenue"      // What was charged for each completed service
+	ReportCommissions  ReportKind = "commissions"  // The commission each staff member earned
+	ReportClients      ReportKind = "clients"      // The client list; not limited to a range of dates
+	ReportAppointments ReportKind = "appointments" // The appointments starting in the range
+)
+
+// ReportKinds are the reports that can be exported
+var ReportKinds = []ReportKind{ReportRevenue, ReportCommissions, ReportClients, ReportAppointments}
+
+// IsValid checks if the report kind is valid
+func (k ReportKind) IsValid() bool {
+	return slices.Contains(ReportKinds, k)
+}
+
+// IsDated reports whether the report covers a range of dates
+func (k ReportKind) IsDated() bool {
+	return k != ReportClients
+}
+
+// Permission returns the permission staff need to export the report
+func (k ReportKind) Permission() Permission {
+	if k == ReportClients {
+		return PermissionClientsExport
+	}
+	return PermissionReportsView
+}
+
+// ReportFormat is the file format a report is exported to
+type ReportFormat string
+
+const (
+	ReportFormatCSV ReportFormat = "csv"
+	ReportFormatPDF ReportFormat = "pdf"
+)
+
+// IsValid checks if the report format is valid
+func (f ReportFormat) IsValid() bool {
+	return f == ReportFormatCSV || f == ReportFormatPDF
+}
+
+// ContentType returns the media type of files of the format
+func (f ReportFormat) ContentType() string {
+	if f == ReportFormatPDF {
+		return "application/pdf"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// ReportExportStatus represents the state of a report export
+type ReportExportStatus string
+
+const (
+	ReportExportPending ReportExportStatus = "pending" // Waiting for the export job to render it
+	ReportExportReady   ReportExportStatus = "ready"   // Stored and downloadable until it expires
+	ReportExportFailed  ReportExportStatus = "failed"  // Could not be rendered or stored; see the error
+	ReportExportExpired ReportExportStatus = "expired" // Removed from storage
+)
+
+const (
+	// JobReportExport renders a report export and stores it
+	JobReportExport = "report_export.render"
+	// JobReportExportExpire removes an export's file from storage once it expires
+	JobReportExportExpire = "report_export.expire"
+	// ReportExportRetention is how long an export can be downloaded after it is rendered
+	ReportExportRetention = 7 * 24 * time.Hour
+	// MaxReportRangeDays bounds the range of dates of a report
+	MaxReportRangeDays = 366
+	// ReportExportObjectPrefix is the prefix of the storage keys of report exports
+	ReportExportObjectPrefix = "report-exports/"
+)
+
+// ReportExportPayload is the export a report export job works on
+type ReportExportPayload struct {
+	ExportID string `json:"export_id"`
+}
+
+// ReportExport is a report rendered to a file in the background and kept in object storage, from
+// where it is downloaded through signed URLs until it expires
+type ReportExport struct {
+	BaseModel
+	BusinessID  string             `gorm:"not null;type:uuid;index" json:"business_id"`
+	Report      ReportKind         `gorm:"not null;size:20" json:"report"`
+	Format      ReportFormat       `gorm:"not null;size:10" json:"format"`
+	StartDate   *string            `gorm:"size:10" json:"start_date,omitempty"` // YYYY-MM-DD, in the business's time zone
+	EndDate     *string            `gorm:"size:10" json:"end_date,omitempty"`   // YYYY-MM-DD, inclusive
+	Status      ReportExportStatus `gorm:"not null;size:20;default:'pending'" json:"status"`
+	FileName    string             `gorm:"not null;size:255" json:"file_name"`
+	JobID       *string            `gorm:"type:uuid" json:"job_id,omitempty"`
+	Bucket      *string            `gorm:"size:255" json:"bucket,omitempty"`
+	ObjectKey   *string            `gorm:"size:500" json:"object_key,omitempty"`
+	RowCount    *int               `gorm:"" json:"row_count,omitempty"`
+	SizeBytes   *int64             `gorm:"" json:"size_bytes,omitempty"`
+	Error       *string            `gorm:"type:text" json:"error,omitempty"`
+	CompletedAt *time.Time         `gorm:"" json:"completed_at,omitempty"` // When it was stored or failed
+	ExpiresAt   *time.Time         `gorm:"" json:"expires_at,omitempty"`
+}
+
+// TableName returns the table name for ReportExport
+func (ReportExport) TableName() string { return "report_exports" }
+
+// Validate validates the report export model
+func (e *ReportExport) Validate() error {
+	if e.BusinessID == "" {
+		return fmt.Errorf("%w: business_id is required", ErrValidation)
+	}
+	if !e.Report.IsValid() {
+		return fmt.Errorf("%w: invalid report %q", ErrValidation, e.Report)
+	}
+	if !e.Format.IsValid() {
+		return fmt.Errorf("%w: invalid format %q", ErrValidation, e.Format)
+	}
+	if e.Report.IsDated() && (e.StartDate == nil || e.EndDate == nil) {
+		return fmt.Errorf("%w: the %s report needs a start_date and an end_date", ErrValidation, e.Report)
+	}
+	if e.FileName == "" {
+		return fmt.Errorf("%w: file_name is required", ErrValidation)
+	}
+	return nil
+}
+
+// ReportFileName names the file of a report over an inclusive range of dates, or of an undated
+// report generated on a day
+func ReportFileName(kind ReportKind, format ReportFormat, startDate, endDate *string, generated time.Time) string {
+	if kind.IsDated() && startDate != nil && endDate != nil {
+		return fmt.Sprintf("%s-%s-to-%s.%s", kind, *startDate, *endDate, format)
+	}
+	return fmt.Sprintf("%s-%s.%s", kind, generated.Format(time.DateOnly), format)
+}
+
+// ObjectKeyFor returns the storage key of the export's file
+func (e *ReportExport) ObjectKeyFor() string {
+	return ReportExportObjectPrefix + e.BusinessID + "/" + e.ID + "/" + e.FileName
+}
+
+// MarkReady records the export as stored, downloadable for ReportExportRetention
+func (e *ReportExport) MarkReady(bucket, key string, rows int, size int64, now time.Time) {
+	expires := now.Add(ReportExportRetention)
+	e.Status = ReportExportReady
+	e.Bucket = &bucket
+	e.ObjectKey = &key
+	e.RowCount = &rows
+	e.SizeBytes = &size
+	e.Error = nil
+	e.CompletedAt = &now
+	e.ExpiresAt = &expires
+}
+
+// MarkFailed records why the export could not be rendered or stored
+func (e *ReportExport) MarkFailed(message string, now time.Time) {
+	e.Status = ReportExportFailed
+	e.Error = &message
+	e.CompletedAt = &now
+}
+
+// MarkExpired records the export's file as removed from storage
+func (e *ReportExport) MarkExpired() {
+	e.Status = ReportExportExpired
+}
+
+// IsDownloadable reports whether the export's file can be downloaded at a time
+func (e *ReportExport) IsDownloadable(now time.Time) bool {
+	return e.Status == ReportExportReady && e.ObjectKey != nil && e.ExpiresAt != nil && now.Before(*e.ExpiresAt)
+}
+
+// ReportColumn is a column of a report
+type ReportColumn struct {
+	Name    string // Header of CSV files, in snake case
+	Label   string // Header of PDF files
+	Numeric bool   // Right-aligned in PDF files
+}
+
+// ReportTable is a report ready to be written to a file: a title and rows of formatted values
+type ReportTable struct {
+	Title   string
+	Columns []ReportColumn
+	Rows    [][]string
+}
+
+// WriteCSV writes the report as a CSV file with a header row, for spreadsheets and accounting
+// software
+func (t *ReportTable) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	header := make([]string, len(t.Columns))
+	for i, column := range t.Columns {
+		header[i] = column.Name
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, row := range t.Rows {
+		safe := make([]string, len(row))
+		for i, value := range row {
+			safe[i] = csvSafe(value)
+		}
+		if err := writer.Write(safe); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// Write writes the report in a format
+func (t *ReportTable) Write(w io.Writer, format ReportFormat, generated time.Time) error {
+	if format == ReportFormatPDF {
+		return t.WritePDF(w, generated)
+	}
+	return t.WriteCSV(w)
+}
+
+// RevenueReportRow is a completed service and what was charged for it
+type RevenueReportRow struct {
+	AppointmentID string
+	CompletedAt   time.Time
+	ClientName    string
+	StaffName     string
+	Services      string // The names of the appointment's services, comma separated
+	PaymentMethod string
+	Amount        decimal.Decimal
+}
+
+// AppointmentReportRow is an appointment with the names of those involved
+type AppointmentReportRow struct {
+	AppointmentID string
+	StartTime     time.Time
+	EndTime       time.Time
+	Status        AppointmentStatus
+	ClientName    string
+	StaffName     string
+	Services      string // Comma separated
+	TotalPrice    decimal.Decimal
+}
+
+// CommissionReportRow is the commission a staff member earned over the range of a report
+type CommissionReportRow struct {
+	StaffID        string
+	StaffName      string
+	ServiceRevenue decimal.Decimal
+	ProductRevenue decimal.Decimal
+	Commission     decimal.Decimal
+}
+
+// reportTitle titles a report over an inclusive range of dates
+func reportTitle(name, startDate, endDate string) string {
+	return fmt.Sprintf("%s, %s to %s", name, startDate, endDate)
+}
+
+// NewRevenueReport tabulates completed services with times in the location
+func NewRevenueReport(startDate, endDate string, location *time.Location, rows []*RevenueReportRow) *ReportTable {
+	table := &ReportTable{
+		Title: reportTitle("Revenue", startDate, endDate),
+		Columns: []ReportColumn{
+			{Name: "completed_at", Label: "Completed"},
+			{Name: "client_name", Label: "Client"},
+			{Name: "staff_name", Label: "Staff"},
+			{Name: "services", Label: "Services"},
+			{Name: "payment_method", Label: "Payment"},
+			{Name: "amount", Label: "Amount", Numeric: true},
+			{Name: "appointment_id", Label: "Appointment"},
+		},
+	}
+	for _, row := range rows {
+		table.Rows = append(table.Rows, []string{
+			row.CompletedAt.In(location).Format("2006-01-02 15:04"),
+			row.ClientName,
+			row.StaffName,
+			row.Services,
+			row.PaymentMethod,
+			row.Amount.StringFixed(2),
+			row.AppointmentID,
+		})
+	}
+	return table
+}
+
+// NewCommissionReport tabulates the commission of each staff member with a commission plan
+func NewCommissionReport(startDate, endDate string, rows []*CommissionReportRow) *ReportTable {
+	table := &ReportTable{
+		Title: reportTitle("Commissions", startDate, endDate),
+		Columns: []ReportColumn{
+			{Name: "staff_name", Label: "Staff"},
+			{Name: "service_revenue", Label: "Service revenue", Numeric: true},
+			{Name: "product_revenue", Label: "Product revenue", Numeric: true},
+			{Name: "commission", Label: "Commission", Numeric: true},
+			{Name: "staff_id", Label: "Staff ID"},
+		},
+	}
+	for _, row := range rows {
+		table.Rows = append(table.Rows, []string{
+			row.StaffName,
+			row.ServiceRevenue.StringFixed(2),
+			row.ProductRevenue.StringFixed(2),
+			row.Commission.StringFixed(2),
+			row.StaffID,
+		})
+	}
+	return table
+}
+
+// NewClientReport tabulates the client list with dates in the location. Anonymized clients are
+// listed with the placeholders that replaced their personal data.
+func NewClientReport(generated time.Time, location *time.Location, clients []*Client) *ReportTable {
+	table := &ReportTable{
+		Title: "Clients, " + generated.In(location).Format(time.DateOnly),
+		Columns: []ReportColumn{
+			{Name: "first_name", Label: "First name"},
+			{Name: "last_name", Label: "Last name"},
+			{Name: "email", Label: "Email"},
+			{Name: "phone", Label: "Phone"},
+			{Name: "total_visits", Label: "Visits", Numeric: true},
+			{Name: "total_spent", Label: "Spent", Numeric: true},
+			{Name: "last_visit", Label: "Last visit"},
+			{Name: "accepts_marketing", Label: "Marketing"},
+			{Name: "active", Label: "Active"},
+			{Name: "client_id", Label: "Client ID"},
+		},
+	}
+	for _, client := range clients {
+		phone, lastVisit := "", ""
+		if client.Phone != nil {
+			phone = *client.Phone
+		}
+		if client.LastVisit != nil {
+			lastVisit = client.LastVisit.In(location).Format(time.DateOnly)
+		}
+		table.Rows = append(table.Rows, []string{
+			client.FirstName,
+			client.LastName,
+			client.Email,
+			phone,
+			strconv.Itoa(client.TotalVisits),
+			client.TotalSpent.StringFixed(2),
+			lastVisit,
+			strconv.FormatBool(client.AcceptsMarketing),
+			strconv.FormatBool(client.IsActive),
+			client.ID,
+		})
+	}
+	return table
+}
+
+// NewAppointmentReport tabulates appointments with times in the location
+func NewAppointmentReport(startDate, endDate string, location *time.Location, rows []*AppointmentReportRow) *ReportTable {
+	table := &ReportTable{
+		Title: reportTitle("Appointments", startDate, endDate),
+		Columns: []ReportColumn{
+			{Name: "start_time", Label: "Start"},
+			{Name: "end_time", Label: "End"},
+			{Name: "status", Label: "Status"},
+			{Name: "client_name", Label: "Client"},
+			{Name: "staff_name", Label: "Staff"},
+			{Name: "services", Label: "Services"},
+			{Name: "total_price", Label: "Price", Numeric: true},
+			{Name: "appointment_id", Label: "Appointment"},
+		},
+	}
+	for _, row := range rows {
+		table.Rows = append(table.Rows, []string{
+			row.StartTime.In(location).Format("2006-01-02 15:04"),
+			row.EndTime.In(location).Format("15:04"),
+			string(row.Status),
+			row.ClientName,
+			row.StaffName,
+			row.Services,
+			row.TotalPrice.StringFixed(2),
+			row.AppointmentID,
+		})
+	}
+	return table
+}
+
+// ReportExportRepository defines the repository interface for ReportExport, along with the
+// queries reports are rendered from
+type ReportExportRepository interface {
+	BaseRepository[ReportExport]
+	// FindByBusinessID finds the most recent exports of a business, newest first
+	FindByBusinessID(ctx context.Context, businessID string, limit int) ([]*ReportExport, error)
+	// FindRevenueRows finds the services completed on appointments of a business starting in the
+	// range, ordered by completion
+	FindRevenueRows(ctx context.Context, businessID string, start, end time.Time) ([]*RevenueReportRow, error)
+	// FindAppointmentRows finds the appointments of a business starting in the range, ordered by
+	// start time
+	FindAppointmentRows(ctx context.Context, businessID string, start, end time.Time) ([]*AppointmentReportRow, error)
+	// FindClients finds the clients of a business, ordered by last and first name
+	FindClients(ctx context.Context, businessID string) ([]*Client, error)
+}
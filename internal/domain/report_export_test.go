@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportTableWriteCSV(t *testing.T) {
+	lisbon, err := time.LoadLocation("Europe/Lisbon")
+	require.NoError(t, err)
+	table := NewRevenueReport("2024-06-01", "2024-06-30", lisbon, []*RevenueReportRow{{
+		AppointmentID: "appointment-1",
+		CompletedAt:   time.Date(2024, 6, 3, 9, 30, 0, 0, time.UTC),
+		ClientName:    "=HYPERLINK(\"x\")",
+		StaffName:     "Ana Sousa",
+		Services:      "Cut, Colour",
+		PaymentMethod: "card",
+		Amount:        decimal.NewFromFloat(42.5),
+	}})
+
+	var buf bytes.Buffer
+	require.NoError(t, table.WriteCSV(&buf))
+	assert.Equal(t, "completed_at,client_name,staff_name,services,payment_method,amount,appointment_id\n"+
+		"2024-06-03 10:30,\"'=HYPERLINK(\"\"x\"\")\",Ana Sousa,\"Cut, Colour\",card,42.50,appointment-1\n", buf.String())
+}
+
+func TestReportTableWritePDF(t *testing.T) {
+	table := &ReportTable{
+		Title:   "Clients, 2024-06-01",
+		Columns: []ReportColumn{{Name: "name", Label: "Name"}, {Name: "spent", Label: "Spent", Numeric: true}},
+	}
+	for i := range pdfRowsPerPage + 1 {
+		table.Rows = append(table.Rows, []string{fmt.Sprintf("João (%d)", i), "10.00"})
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, table.WritePDF(&buf, time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)))
+	pdf := buf.Bytes()
+	require.True(t, bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")))
+	require.True(t, bytes.HasSuffix(pdf, []byte("%%EOF\n")))
+	assert.Contains(t, string(pdf), "/Count 2", "rows past a page continue on the next")
+	assert.Contains(t, string(pdf), "(Jo\xe3o \\(0\\)   10.00) Tj", "text is escaped and encoded in Windows-1252")
+	assert.Contains(t, string(pdf), "(Page 2 of 2) Tj")
+
+	// The cross-reference table points at every object
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(pdf)
+	require.NotNil(t, startxref)
+	offset, err := strconv.Atoi(string(startxref[1]))
+	require.NoError(t, err)
+	require.True(t, bytes.HasPrefix(pdf[offset:], []byte("xref\n")))
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(pdf[offset:], -1)
+	require.Len(t, entries, 8, "catalog, pages, two fonts and two objects per page")
+	for i, entry := range entries {
+		objectOffset, err := strconv.Atoi(string(entry[1]))
+		require.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(pdf[objectOffset:], []byte(strconv.Itoa(i+1)+" 0 obj\n")))
+	}
+}
+
+func TestReportTablePDFColumnWidths(t *testing.T) {
+	table := &ReportTable{Columns: make([]ReportColumn, 5)}
+	table.Rows = [][]string{{strings.Repeat("a", 100), strings.Repeat("b", 100), "short", strings.Repeat("c", 100), strings.Repeat("d", 100)}}
+
+	widths := table.pdfColumnWidths()
+	assert.LessOrEqual(t, sumWidths(widths), pdfLineChars)
+	assert.Equal(t, 5, widths[2], "narrow columns keep their width")
+	assert.Less(t, widths[0], pdfMaxColumn, "the widest columns are narrowed to fit")
+
+	line := table.pdfRow(table.Rows[0], widths)
+	assert.True(t, strings.HasPrefix(line, strings.Repeat("a", widths[0]-1)+"…  b"), "long values are truncated")
+}
+
+func TestReportExportLifecycle(t *testing.T) {
+	start, end := "2024-06-01", "2024-06-30"
+	now := time.Date(2024, 7, 1, 8, 0, 0, 0, time.UTC)
+	export := &ReportExport{
+		BaseModel:  BaseModel{ID: "export-1"},
+		BusinessID: "business-1",
+		Report:     ReportRevenue,
+		Format:     ReportFormatPDF,
+		StartDate:  &start,
+		EndDate:    &end,
+		Status:     ReportExportPending,
+		FileName:   ReportFileName(ReportRevenue, ReportFormatPDF, &start, &end, now),
+	}
+	require.NoError(t, export.Validate())
+	assert.Equal(t, "revenue-2024-06-01-to-2024-06-30.pdf", export.FileName)
+	assert.Equal(t, "report-exports/business-1/export-1/revenue-2024-06-01-to-2024-06-30.pdf", export.ObjectKeyFor())
+	assert.False(t, export.IsDownloadable(now))
+
+	export.MarkReady("beautix-eu", export.ObjectKeyFor(), 3, 1024, now)
+	assert.True(t, export.IsDownloadable(now.Add(ReportExportRetention-time.Second)))
+	assert.False(t, export.IsDownloadable(now.Add(ReportExportRetention)))
+
+	undated := &ReportExport{BusinessID: "business-1", Report: ReportCommissions, Format: ReportFormatCSV, FileName: "x.csv"}
+	assert.ErrorIs(t, undated.Validate(), ErrValidation, "dated reports need their dates")
+	assert.Equal(t, "clients-2024-07-01.csv", ReportFileName(ReportClients, ReportFormatCSV, &start, &end, now))
+}
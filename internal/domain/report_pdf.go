@@ -0,0 +1,213 @@
+package domain
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Layout of report PDFs: landscape A4 in points, set in Courier so that columns line up by
+// character count
+const (
+	pdfPageWidth    = 842
+	pdfPageHeight   = 595
+	pdfMargin       = 36
+	pdfFontSize     = 8
+	pdfLineHeight   = 11
+	pdfLineChars    = 160 // Courier characters are 0.6 em wide: (842 - 2*36) / 4.8
+	pdfColumnGap    = 2
+	pdfMaxColumn    = 40
+	pdfMinColumn    = 6
+	pdfPageLines    = (pdfPageHeight - 2*pdfMargin) / pdfLineHeight
+	pdfHeaderLines  = 5 // Title, generation date, a blank line, column labels and a rule
+	pdfRowsPerPage  = pdfPageLines - pdfHeaderLines - 2
+	pdfFirstFontObj = 3
+	pdfFirstPageObj = 5
+)
+
+// WritePDF writes the report as a PDF document of tables in landscape A4, repeating the column
+// labels on every page. Columns too wide to fit are truncated. The document uses the standard
+// Courier fonts, so text is limited to the Windows-1252 characters; others print as "?".
+func (t *ReportTable) WritePDF(w io.Writer, generated time.Time) error {
+	widths := t.pdfColumnWidths()
+	header := []string{
+		t.Title,
+		"Generated " + generated.UTC().Format("2006-01-02 15:04") + " UTC",
+		"",
+	}
+	labels := make([]string, len(t.Columns))
+	for i, column := range t.Columns {
+		labels[i] = column.Label
+	}
+	rule := strings.Repeat("-", min(pdfLineChars, sumWidths(widths)))
+
+	pages := max(1, (len(t.Rows)+pdfRowsPerPage-1)/pdfRowsPerPage)
+	doc := &pdfDocument{}
+	doc.object(fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", 2))
+	kids := make([]string, pages)
+	for i := range kids {
+		kids[i] = fmt.Sprintf("%d 0 R", pdfFirstPageObj+2*i)
+	}
+	doc.object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), pages))
+	doc.object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	doc.object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>")
+
+	for page := range pages {
+		var content bytes.Buffer
+		y := pdfPageHeight - pdfMargin - pdfFontSize
+		line := func(font, text string) {
+			fmt.Fprintf(&content, "BT /%s %d Tf %d %d Td (%s) Tj ET\n", font, pdfFontSize, pdfMargin, y, pdfEscape(text))
+			y -= pdfLineHeight
+		}
+		for i, text := range header {
+			font := "F1"
+			if i == 0 {
+				font = "F2"
+			}
+			line(font, text)
+		}
+		line("F2", t.pdfRow(labels, widths))
+		line("F1", rule)
+
+		first := page * pdfRowsPerPage
+		rows := t.Rows[min(first, len(t.Rows)):min(first+pdfRowsPerPage, len(t.Rows))]
+		if len(t.Rows) == 0 {
+			line("F1", "No rows")
+		}
+		for _, row := range rows {
+			line("F1", t.pdfRow(row, widths))
+		}
+		y = pdfMargin
+		line("F1", fmt.Sprintf("Page %d of %d", page+1, pages))
+
+		doc.object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 %d 0 R /F2 %d 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, pdfFirstFontObj, pdfFirstFontObj+1, pdfFirstPageObj+2*page+1))
+		doc.object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()))
+	}
+
+	_, err := w.Write(doc.bytes())
+	return err
+}
+
+// pdfColumnWidths sizes each column, in characters, to its widest value up to pdfMaxColumn, then
+// narrows the widest columns until the row fits on the page
+func (t *ReportTable) pdfColumnWidths() []int {
+	widths := make([]int, len(t.Columns))
+	for i, column := range t.Columns {
+		widths[i] = min(pdfMaxColumn, max(len([]rune(column.Label)), 1))
+	}
+	for _, row := range t.Rows {
+		for i, value := range row {
+			if i < len(widths) {
+				widths[i] = min(pdfMaxColumn, max(widths[i], len([]rune(value))))
+			}
+		}
+	}
+	for sumWidths(widths) > pdfLineChars {
+		widest := 0
+		for i, width := range widths {
+			if width > widths[widest] {
+				widest = i
+			}
+		}
+		if widths[widest] <= pdfMinColumn {
+			break
+		}
+		widths[widest]--
+	}
+	return widths
+}
+
+// sumWidths returns the characters a row of columns takes, gaps included
+func sumWidths(widths []int) int {
+	total := 0
+	for _, width := range widths {
+		total += width
+	}
+	return total + pdfColumnGap*max(len(widths)-1, 0)
+}
+
+// pdfRow lays out the values of a row in columns of the widths, padding or truncating each
+func (t *ReportTable) pdfRow(values []string, widths []int) string {
+	var line strings.Builder
+	for i, width := range widths {
+		value := ""
+		if i < len(values) {
+			value = values[i]
+		}
+		runes := []rune(value)
+		if len(runes) > width {
+			runes = append(runes[:width-1], '…')
+		}
+		padding := strings.Repeat(" ", width-len(runes))
+		if i > 0 {
+			line.WriteString(strings.Repeat(" ", pdfColumnGap))
+		}
+		if t.Columns[i].Numeric {
+			line.WriteString(padding + string(runes))
+		} else {
+			line.WriteString(string(runes) + padding)
+		}
+	}
+	return strings.TrimRight(line.String(), " ")
+}
+
+// pdfSpecialRunes maps the characters of Windows-1252 outside Latin-1 onto their codes
+var pdfSpecialRunes = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87, 'ˆ': 0x88,
+	'‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E, '‘': 0x91, '’': 0x92, '“': 0x93,
+	'”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B,
+	'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// pdfEscape encodes text as the contents of a PDF string in Windows-1252
+func pdfEscape(text string) string {
+	var escaped strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			escaped.WriteByte('\\')
+			escaped.WriteRune(r)
+		case r >= 0x20 && r < 0x7F:
+			escaped.WriteRune(r)
+		case r >= 0xA0 && r <= 0xFF:
+			escaped.WriteByte(byte(r))
+		default:
+			if code, ok := pdfSpecialRunes[r]; ok {
+				escaped.WriteByte(code)
+			} else {
+				escaped.WriteByte('?')
+			}
+		}
+	}
+	return escaped.String()
+}
+
+// pdfDocument assembles the objects of a PDF file, numbered from 1 in the order added
+type pdfDocument struct {
+	buf     bytes.Buffer
+	offsets []int
+}
+
+// object appends an indirect object
+func (d *pdfDocument) object(body string) {
+	if d.buf.Len() == 0 {
+		// The comment of bytes above 127 marks the file as binary for transfer tools
+		d.buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	}
+	d.offsets = append(d.offsets, d.buf.Len())
+	fmt.Fprintf(&d.buf, "%d 0 obj\n%s\nendobj\n", len(d.offsets), body)
+}
+
+// bytes finishes the file with the cross-reference table and trailer
+func (d *pdfDocument) bytes() []byte {
+	start := d.buf.Len()
+	fmt.Fprintf(&d.buf, "xref\n0 %d\n0000000000 65535 f \n", len(d.offsets)+1)
+	for _, offset := range d.offsets {
+		fmt.Fprintf(&d.buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&d.buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(d.offsets)+1, start)
+	return d.buf.Bytes()
+}
@@ -0,0 +1,139 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ResourceKind is what a bookable resource is
+type ResourceKind string
+
+const (
+	ResourceRoom      ResourceKind = "room"      // A treatment room
+	ResourceChair     ResourceKind = "chair"     // A chair or station
+	ResourceEquipment ResourceKind = "equipment" // A machine, such as a laser
+)
+
+// ConflictResourceBusy is the conflict of a booking with the other appointments using a resource
+// its services need
+const ConflictResourceBusy ConflictReason = "resource_busy"
+
+// Resource is a room, chair or piece of equipment that appointments occupy. Appointments for
+// services that need a resource cannot overlap more of the resource's other appointments than
+// its capacity allows, whoever performs them.
+type Resource struct {
+	BaseModel
+	BusinessID string       `gorm:"not null;type:uuid;index" json:"business_id"`
+	LocationID *string      `gorm:"type:uuid;index" json:"location_id,omitempty"` // The location the resource is at
+	Name       string       `gorm:"not null;size:100" json:"name"`
+	Kind       ResourceKind `gorm:"not null;size:20" json:"kind"`
+	Capacity   int          `gorm:"not null;default:1" json:"capacity"` // Appointments it can hold at once
+	IsActive   bool         `gorm:"not null;default:true" json:"is_active"`
+	Notes      *string      `gorm:"type:text" json:"notes,omitempty"`
+}
+
+// TableName returns the table name for Resource
+func (Resource) TableName() string { return "resources" }
+
+// Validate validates the resource model
+func (r *Resource) Validate() error {
+	if r.BusinessID == "" {
+		return ErrValidation
+	}
+	if strings.TrimSpace(r.Name) == "" {
+		return fmt.Errorf("%w: a resource needs a name", ErrValidation)
+	}
+	switch r.Kind {
+	case ResourceRoom, ResourceChair, ResourceEquipment:
+	default:
+		return fmt.Errorf("%w: unknown resource kind %q", ErrValidation, r.Kind)
+	}
+	if r.Capacity < 1 {
+		return fmt.Errorf("%w: a resource holds at least one appointment at once", ErrValidation)
+	}
+	return nil
+}
+
+// ServiceResource records that every appointment for a service occupies a resource
+type ServiceResource struct {
+	BaseModel
+	BusinessID string `gorm:"not null;type:uuid;index" json:"business_id"`
+	ServiceID  string `gorm:"not null;type:uuid;index" json:"service_id"`
+	ResourceID string `gorm:"not null;type:uuid;index" json:"resource_id"`
+}
+
+// TableName returns the table name for ServiceResource
+func (ServiceResource) TableName() string { return "service_resources" }
+
+// ResourceUsage is a resource an appointment needs, with the other appointments using it around
+// the appointment's time
+type ResourceUsage struct {
+	Resource *Resource
+	Busy     []*BusyInterval
+}
+
+// CheckResources returns a conflict for each resource that is already in use by as many other
+// appointments as it can hold at some point during the requested time
+func CheckResources(request AvailabilityRequest, usages []*ResourceUsage, location *time.Location) []*AvailabilityConflict {
+	var conflicts []*AvailabilityConflict
+	for _, usage := range usages {
+		busy := slices.DeleteFunc(slices.Clone(usage.Busy), func(interval *BusyInterval) bool {
+			excluded := request.ExcludeAppointmentID != nil && interval.AppointmentID == *request.ExcludeAppointmentID
+			return excluded || !interval.StartTime.Before(request.End) || !request.Start.Before(interval.EndTime)
+		})
+		if peakOverlap(busy) < usage.Resource.Capacity {
+			continue
+		}
+		resourceID := usage.Resource.ID
+		message := usage.Resource.Name + " is in use"
+		if len(busy) == 1 {
+			message += " from " + busy[0].StartTime.In(location).Format("15:04") + " to " + busy[0].EndTime.In(location).Format("15:04")
+		}
+		conflicts = append(conflicts, &AvailabilityConflict{
+			Reason:     ConflictResourceBusy,
+			Message:    message,
+			ResourceID: &resourceID,
+		})
+	}
+	return conflicts
+}
+
+// peakOverlap returns the largest number of the intervals that overlap at any one time
+func peakOverlap(intervals []*BusyInterval) int {
+	type edge struct {
+		at    time.Time
+		delta int
+	}
+	edges := make([]edge, 0, 2*len(intervals))
+	for _, interval := range intervals {
+		edges = append(edges, edge{interval.StartTime, 1}, edge{interval.EndTime, -1})
+	}
+	// Intervals ending as another starts do not overlap, so ends come first
+	slices.SortFunc(edges, func(a, b edge) int {
+		if c := a.at.Compare(b.at); c != 0 {
+			return c
+		}
+		return a.delta - b.delta
+	})
+	peak, current := 0, 0
+	for _, e := range edges {
+		current += e.delta
+		peak = max(peak, current)
+	}
+	return peak
+}
+
+// ResourceRepository defines the repository interface for Resource
+type ResourceRepository interface {
+	BaseRepository[Resource]
+	// FindByBusiness finds the resources of a business by name, only those at a location when one
+	// is given
+	FindByBusiness(ctx context.Context, businessID string, locationID *string) ([]*Resource, error)
+	// FindByServices finds the active resources the services need, by name
+	FindByServices(ctx context.Context, serviceIDs []string) ([]*Resource, error)
+	// SetServiceResources replaces the resources a service needs
+	SetServiceResources(ctx context.Context, serviceID string, rows []*ServiceResource) error
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceValidate(t *testing.T) {
+	resource := Resource{BusinessID: "business-1", Name: "Room 1", Kind: ResourceRoom, Capacity: 1}
+	assert.NoError(t, resource.Validate())
+
+	for _, invalid := range []Resource{
+		{BusinessID: "business-1", Name: " ", Kind: ResourceRoom, Capacity: 1},
+		{BusinessID: "business-1", Name: "Room 1", Kind: "sofa", Capacity: 1},
+		{BusinessID: "business-1", Name: "Room 1", Kind: ResourceRoom},
+	} {
+		assert.True(t, errors.Is(invalid.Validate(), ErrValidation), invalid.Name)
+	}
+}
+
+func TestCheckResources(t *testing.T) {
+	start := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	interval := func(id string, from, to time.Duration) *BusyInterval {
+		return &BusyInterval{AppointmentID: id, StartTime: start.Add(from), EndTime: start.Add(to)}
+	}
+	room := &Resource{BaseModel: BaseModel{ID: "room-1"}, Name: "Room 1", Capacity: 1}
+	chairs := &Resource{BaseModel: BaseModel{ID: "chairs"}, Name: "Pedicure chairs", Capacity: 2}
+	request := AvailabilityRequest{Start: start, End: start.Add(time.Hour)}
+
+	conflicts := CheckResources(request, []*ResourceUsage{
+		{Resource: room, Busy: []*BusyInterval{interval("a", -time.Hour, 0)}},
+		{Resource: chairs, Busy: []*BusyInterval{interval("b", 0, 30*time.Minute), interval("c", 30*time.Minute, time.Hour)}},
+	}, time.UTC)
+	assert.Empty(t, conflicts, "back-to-back appointments never share the resource at once")
+
+	conflicts = CheckResources(request, []*ResourceUsage{
+		{Resource: room, Busy: []*BusyInterval{interval("a", 30*time.Minute, 90*time.Minute)}},
+		{Resource: chairs, Busy: []*BusyInterval{interval("b", 0, 45*time.Minute), interval("c", 30*time.Minute, time.Hour)}},
+	}, time.UTC)
+	require.Len(t, conflicts, 2)
+	assert.Equal(t, ConflictResourceBusy, conflicts[0].Reason)
+	assert.Equal(t, "room-1", *conflicts[0].ResourceID)
+	assert.Equal(t, "Room 1 is in use from 10:30 to 11:30", conflicts[0].Message)
+	assert.Equal(t, "chairs", *conflicts[1].ResourceID)
+
+	excluded := "a"
+	request.ExcludeAppointmentID = &excluded
+	conflicts = CheckResources(request, []*ResourceUsage{
+		{Resource: room, Busy: []*BusyInterval{interval("a", 30*time.Minute, 90*time.Minute)}},
+	}, time.UTC)
+	assert.Empty(t, conflicts, "a rescheduled appointment does not conflict with itself")
+}
@@ -0,0 +1,270 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ReviewStatus represents where a review is in moderation
+type ReviewStatus string
+
+const (
+	ReviewPending   ReviewStatus = "pending"   // Has a comment the business is yet to moderate
+	ReviewPublished ReviewStatus = "published" // Shown, and counted in the ratings
+	ReviewRejected  ReviewStatus = "rejected"  // Hidden by the business, and not counted
+	ReviewFlagged   ReviewStatus = "flagged"   // Held back by the content filter until the business publishes or rejects it
+)
+
+// IsValid reports whether the status is known
+func (s ReviewStatus) IsValid() bool {
+	return s == ReviewPending || s == ReviewPublished || s == ReviewRejected || s == ReviewFlagged
+}
+
+const (
+	// ReviewRequestValidity is how long after it is issued a review link can be used
+	ReviewRequestValidity = 30 * 24 * time.Hour
+	// MaxReviewCommentLength bounds the length of a review comment in characters
+	MaxReviewCommentLength = 2000
+	// MaxReviewReplyLength bounds the length of the business's reply to a review in characters
+	MaxReviewReplyLength = 2000
+	// MaxModerationNoteLength bounds the length of the note on why a review was rejected
+	MaxModerationNoteLength = 500
+	// JobReviewRequest is the job kind that asks the client of a completed appointment for a review
+	JobReviewRequest = "review.request"
+	// JobReviewReply is the job kind that lets a client know the business replied to their review
+	JobReviewReply = "review.reply"
+)
+
+var (
+	// ErrReviewRequestUsed is returned when a review was already left for an appointment
+	ErrReviewRequestUsed = errors.New("a review has already been left for this appointment")
+	// ErrReviewRequestExpired is returned when a review link is past its validity
+	ErrReviewRequestExpired = errors.New("this review link has expired")
+)
+
+// Review is a client's rating of a completed appointment, from 1 to 5 stars, with an optional
+// comment the business may reply to. Reviews with a comment wait for the business to publish or
+// reject them; only published reviews are shown and counted in the ratings of the staff member
+// and the business.
+type Review struct {
+	BaseModel
+	BusinessID     string       `gorm:"not null;type:uuid;index" json:"business_id"`
+	AppointmentID  string       `gorm:"not null;type:uuid;uniqueIndex" json:"appointment_id"`
+	ClientID       string       `gorm:"not null;type:uuid;index" json:"client_id"`
+	StaffID        string       `gorm:"not null;type:uuid;index" json:"staff_id"`
+	ServiceID      string       `gorm:"not null;type:uuid;index" json:"service_id"` // The first service of the appointment
+	Rating         int          `gorm:"not null" json:"rating"`                     // 1 to 5 stars
+	Comment        *string      `gorm:"type:text" json:"comment,omitempty"`
+	Status         ReviewStatus `gorm:"not null;size:20;default:'pending'" json:"status"`
+	ModeratedAt    *time.Time   `gorm:"" json:"moderated_at,omitempty"`
+	ModeratedBy    *string      `gorm:"type:uuid" json:"moderated_by,omitempty"`
+	ModerationNote *string      `gorm:"size:500" json:"moderation_note,omitempty"` // Why the review was rejected; never shown to the public
+	Reply          *string      `gorm:"type:text" json:"reply,omitempty"`          // The business's public reply
+	RepliedAt      *time.Time   `gorm:"" json:"replied_at,omitempty"`
+	RepliedBy      *string      `gorm:"type:uuid" json:"replied_by,omitempty"`
+}
+
+// TableName returns the table name for Review
+func (Review) TableName() string { return "reviews" }
+
+// NewReview validates a review of an appointment. Ratings without a comment have nothing to
+// moderate and are published at once; comments the filter flags are held back as flagged.
+func NewReview(appointment *ReviewableAppointment, rating int, comment string, filter ContentFilter) (*Review, error) {
+	review := &Review{
+		BusinessID:    appointment.BusinessID,
+		AppointmentID: appointment.AppointmentID,
+		ClientID:      appointment.ClientID,
+		StaffID:       appointment.StaffID,
+		ServiceID:     appointment.ServiceID,
+		Rating:        rating,
+		Status:        ReviewPublished,
+	}
+	if comment = strings.TrimSpace(comment); comment != "" {
+		review.Comment = &comment
+		review.Status = ReviewPending
+		if flags(filter, comment) {
+			review.Status = ReviewFlagged
+		}
+	}
+	if err := review.Validate(); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// Validate validates the review model
+func (r *Review) Validate() error {
+	if r.BusinessID == "" || r.AppointmentID == "" || r.ClientID == "" || r.StaffID == "" || r.ServiceID == "" {
+		return ErrValidation
+	}
+	if r.Rating < 1 || r.Rating > 5 {
+		return fmt.Errorf("%w: rating must be between 1 and 5", ErrValidation)
+	}
+	if r.Comment != nil && utf8.RuneCountInString(*r.Comment) > MaxReviewCommentLength {
+		return fmt.Errorf("%w: comment must be at most %d characters", ErrValidation, MaxReviewCommentLength)
+	}
+	if !r.Status.IsValid() {
+		return fmt.Errorf("%w: invalid status %q", ErrValidation, r.Status)
+	}
+	return nil
+}
+
+// Moderate publishes or rejects the review, noting why when rejected. A decision can be revised.
+func (r *Review) Moderate(publish bool, note *string, by *string, now time.Time) error {
+	if note != nil {
+		trimmed := strings.TrimSpace(*note)
+		note = &trimmed
+		if trimmed == "" {
+			note = nil
+		} else if utf8.RuneCountInString(trimmed) > MaxModerationNoteLength {
+			return fmt.Errorf("%w: moderation note must be at most %d characters", ErrValidation, MaxModerationNoteLength)
+		}
+	}
+	r.Status = ReviewRejected
+	if publish {
+		r.Status = ReviewPublished
+	}
+	r.ModerationNote = note
+	r.ModeratedAt = &now
+	r.ModeratedBy = by
+	return nil
+}
+
+// SetReply sets the business's public reply to the review, or removes it when empty. Rejected
+// reviews are not shown, so they cannot be replied to. A reply the filter flags holds the review
+// back as flagged until it is published again.
+func (r *Review) SetReply(reply string, by *string, now time.Time, filter ContentFilter) error {
+	reply = strings.TrimSpace(reply)
+	if reply == "" {
+		r.Reply, r.RepliedAt, r.RepliedBy = nil, nil, nil
+		return nil
+	}
+	if r.Status == ReviewRejected {
+		return fmt.Errorf("%w: rejected reviews cannot be replied to", ErrValidation)
+	}
+	if utf8.RuneCountInString(reply) > MaxReviewReplyLength {
+		return fmt.Errorf("%w: reply must be at most %d characters", ErrValidation, MaxReviewReplyLength)
+	}
+	r.Reply = &reply
+	r.RepliedAt = &now
+	r.RepliedBy = by
+	if flags(filter, reply) {
+		r.Status = ReviewFlagged
+	}
+	return nil
+}
+
+// IsReplyShown reports whether the business's reply is shown with the review
+func (r *Review) IsReplyShown() bool {
+	return r.Reply != nil && r.Status == ReviewPublished
+}
+
+// ReviewRequest lets the client of a completed appointment leave a review once, through the link
+// they are sent after their visit
+type ReviewRequest struct {
+	BaseModel
+	BusinessID    string     `gorm:"not null;type:uuid;index" json:"business_id"`
+	AppointmentID string     `gorm:"not null;type:uuid;uniqueIndex" json:"appointment_id"`
+	Token         string     `gorm:"not null;size:64;uniqueIndex" json:"-"`
+	ExpiresAt     time.Time  `gorm:"not null" json:"expires_at"`
+	SentAt        *time.Time `gorm:"" json:"sent_at,omitempty"`
+	ReviewedAt    *time.Time `gorm:"" json:"reviewed_at,omitempty"` // When the link was used
+}
+
+// TableName returns the table name for ReviewRequest
+func (ReviewRequest) TableName() string { return "review_requests" }
+
+// IsExpired reports whether the link can no longer be used
+func (r *ReviewRequest) IsExpired(now time.Time) bool {
+	return !now.Before(r.ExpiresAt)
+}
+
+// CheckUsable returns why a review cannot be left with the request, if it cannot
+func (r *ReviewRequest) CheckUsable(now time.Time) error {
+	if r.ReviewedAt != nil {
+		return ErrReviewRequestUsed
+	}
+	if r.IsExpired(now) {
+		return ErrReviewRequestExpired
+	}
+	return nil
+}
+
+// ReviewableAppointment is what a review of an appointment is about, and who it is asked of
+type ReviewableAppointment struct {
+	AppointmentID    string
+	BusinessID       string
+	BusinessName     string
+	TimeZone         string
+	ClientID         string
+	ClientFirstName  string
+	ClientEmail      string
+	ClientAnonymized bool
+	StaffID          string
+	StaffName        string
+	ServiceID        string // The first service of the appointment; empty when it has none
+	ServiceName      string
+	Status           AppointmentStatus
+	StartTime        time.Time
+	Reviewed         bool // Whether a review was left for it
+}
+
+// CanBeReviewed reports whether the client can be asked for a review of the appointment
+func (a *ReviewableAppointment) CanBeReviewed() bool {
+	return a.Status == AppointmentStatusCompleted && !a.Reviewed && !a.ClientAnonymized && a.ServiceID != ""
+}
+
+// ReviewRequestJobPayload identifies the appointment a review request job asks about
+type ReviewRequestJobPayload struct {
+	AppointmentID string `json:"appointment_id"`
+}
+
+// ReviewReplyJobPayload identifies the review a reply notification job is about
+type ReviewReplyJobPayload struct {
+	ReviewID string `json:"review_id"`
+}
+
+const (
+	DefaultReviewRequestDelayHours = 2   // How long after their visit clients are asked for a review when the business has no settings
+	MaxReviewRequestDelayHours     = 168 // Latest a business may ask for a review, a week after the visit
+)
+
+// ReviewRequestDelay returns how long after an appointment is completed its client is asked for
+// a review, and whether they are asked at all
+func ReviewRequestDelay(settings *BusinessSettings) (time.Duration, bool) {
+	hours := DefaultReviewRequestDelayHours
+	if settings != nil {
+		hours = settings.ReviewRequestDelayHours
+	}
+	return time.Duration(hours) * time.Hour, hours > 0
+}
+
+// ReviewFilter narrows a list of reviews
+type ReviewFilter struct {
+	Status  *ReviewStatus
+	StaffID *string
+}
+
+// ReviewRepository defines the repository interface for reviews and the requests they are left
+// through
+type ReviewRepository interface {
+	BaseRepository[Review]
+	// FindByBusiness finds the reviews of a business, newest first
+	FindByBusiness(ctx context.Context, businessID string, filter ReviewFilter, page, pageSize int) ([]*Review, int64, error)
+	// FindReviewable finds what a review of an appointment is about
+	FindReviewable(ctx context.Context, appointmentID string) (*ReviewableAppointment, error)
+	FindRequestByAppointment(ctx context.Context, appointmentID string) (*ReviewRequest, error)
+	FindRequestByToken(ctx context.Context, token string) (*ReviewRequest, error)
+	// SaveRequest creates a request or updates an existing one
+	SaveRequest(ctx context.Context, request *ReviewRequest) error
+	// Submit uses the request and stores the review in a single transaction, returning
+	// ErrReviewRequestUsed when the request was used concurrently
+	Submit(ctx context.Context, requestID string, review *Review, at time.Time) error
+	// RefreshRatings works out the ratings of a staff member and their business again from
+	// their published reviews
+	RefreshRatings(ctx context.Context, businessID, staffID string) error
+}
@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewReview(t *testing.T) {
+	appointment := &ReviewableAppointment{AppointmentID: "appointment-1", BusinessID: "business-1", ClientID: "client-1",
+		StaffID: "staff-1", ServiceID: "service-1", Status: AppointmentStatusCompleted}
+
+	rating, err := NewReview(appointment, 5, "  ", nil)
+	require.NoError(t, err)
+	assert.Equal(t, ReviewPublished, rating.Status, "ratings without a comment have nothing to moderate")
+	assert.Nil(t, rating.Comment)
+
+	commented, err := NewReview(appointment, 2, " Running late \n", nil)
+	require.NoError(t, err)
+	assert.Equal(t, ReviewPending, commented.Status)
+	assert.Equal(t, "Running late", *commented.Comment)
+
+	flagged, err := NewReview(appointment, 1, "What a SCAM!", NewWordFilter([]string{"scam"}))
+	require.NoError(t, err)
+	assert.Equal(t, ReviewFlagged, flagged.Status, "comments the filter flags are held back")
+
+	_, err = NewReview(appointment, 0, "", nil)
+	assert.ErrorIs(t, err, ErrValidation)
+	_, err = NewReview(appointment, 6, "", nil)
+	assert.ErrorIs(t, err, ErrValidation)
+	_, err = NewReview(appointment, 4, strings.Repeat("a", MaxReviewCommentLength+1), nil)
+	assert.ErrorIs(t, err, ErrValidation)
+	_, err = NewReview(&ReviewableAppointment{AppointmentID: "appointment-2", BusinessID: "business-1", ClientID: "client-1",
+		StaffID: "staff-1"}, 4, "", nil)
+	assert.ErrorIs(t, err, ErrValidation, "reviews are of a service")
+}
+
+func TestReviewModerationAndReply(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	user := "user-1"
+	comment := "Lovely"
+	review := &Review{BusinessID: "business-1", AppointmentID: "appointment-1", ClientID: "client-1", StaffID: "staff-1",
+		ServiceID: "service-1", Rating: 5, Comment: &comment, Status: ReviewPending}
+
+	note := "  Mentions another client  "
+	require.NoError(t, review.Moderate(false, &note, &user, now))
+	assert.Equal(t, ReviewRejected, review.Status)
+	assert.Equal(t, "Mentions another client", *review.ModerationNote)
+	assert.ErrorIs(t, review.SetReply("Thanks", &user, now, nil), ErrValidation, "rejected reviews are not shown")
+
+	blank := " "
+	require.NoError(t, review.Moderate(true, &blank, &user, now.Add(time.Hour)))
+	assert.Equal(t, ReviewPublished, review.Status)
+	assert.Nil(t, review.ModerationNote)
+	assert.Equal(t, now.Add(time.Hour), *review.ModeratedAt)
+
+	require.NoError(t, review.SetReply(" Thank you! ", &user, now, nil))
+	assert.Equal(t, "Thank you!", *review.Reply)
+	assert.Equal(t, now, *review.RepliedAt)
+	require.NoError(t, review.SetReply("", &user, now, nil))
+	assert.Nil(t, review.Reply)
+	assert.Nil(t, review.RepliedAt)
+
+	require.NoError(t, review.SetReply("You are a scammer", &user, now, NewWordFilter([]string{"scammer"})))
+	assert.Equal(t, ReviewFlagged, review.Status, "replies the filter flags hold the review back")
+	assert.False(t, review.IsReplyShown())
+	require.NoError(t, review.Moderate(true, nil, &user, now))
+	assert.True(t, review.IsReplyShown())
+}
+
+func TestReviewRequestCheckUsable(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	request := &ReviewRequest{ExpiresAt: now.Add(ReviewRequestValidity)}
+	assert.NoError(t, request.CheckUsable(now))
+	assert.ErrorIs(t, request.CheckUsable(request.ExpiresAt), ErrReviewRequestExpired)
+
+	request.ReviewedAt = &now
+	assert.ErrorIs(t, request.CheckUsable(now), ErrReviewRequestUsed)
+}
+
+func TestReviewRequestDelay(t *testing.T) {
+	delay, ok := ReviewRequestDelay(nil)
+	assert.True(t, ok)
+	assert.Equal(t, DefaultReviewRequestDelayHours*time.Hour, delay)
+
+	delay, ok = ReviewRequestDelay(&BusinessSettings{ReviewRequestDelayHours: 24})
+	assert.True(t, ok)
+	assert.Equal(t, 24*time.Hour, delay)
+
+	_, ok = ReviewRequestDelay(&BusinessSettings{})
+	assert.False(t, ok, "0 disables review requests")
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// RunSheet is a staff member's appointments of a day, with what they need to know about each
+// client at the station: preferences, allergies, notes and the formula used last time
+type RunSheet struct {
+	StaffID string
+	Date    time.Time // Local date in the business time zone
+	Entries []*RunSheetEntry
+}
+
+// RunSheetEntry is an appointment on a run sheet
+type RunSheetEntry struct {
+	Appointment *CalendarEntry
+	Client      *Client        // Nil when the client no longer exists
+	LastRecord  *ServiceRecord // The client's latest service record, such as their colour formula
+}
+
+// NewRunSheet builds the run sheet of a staff member from their calendar entries of the day,
+// in chronological order. Cancelled and rescheduled appointments are left off; clients and
+// records are matched to the appointments by client.
+func NewRunSheet(staffID string, date time.Time, appointments []*CalendarEntry, clients []*Client, records []*ServiceRecord) *RunSheet {
+	clientsByID := make(map[string]*Client, len(clients))
+	for _, client := range clients {
+		clientsByID[client.ID] = client
+	}
+	recordsByClient := make(map[string]*ServiceRecord, len(records))
+	for _, record := range records {
+		if latest, ok := recordsByClient[record.ClientID]; !ok || record.RecordedAt.After(latest.RecordedAt) {
+			recordsByClient[record.ClientID] = record
+		}
+	}
+
+	sheet := &RunSheet{StaffID: staffID, Date: date, Entries: []*RunSheetEntry{}}
+	for _, appointment := range appointments {
+		if appointment.Status == AppointmentStatusCancelled || appointment.Status == AppointmentStatusRescheduled {
+			continue
+		}
+		sheet.Entries = append(sheet.Entries, &RunSheetEntry{
+			Appointment: appointment,
+			Client:      clientsByID[appointment.ClientID],
+			LastRecord:  recordsByClient[appointment.ClientID],
+		})
+	}
+	slices.SortStableFunc(sheet.Entries, func(a, b *RunSheetEntry) int {
+		return a.Appointment.StartTime.Compare(b.Appointment.StartTime)
+	})
+	return sheet
+}
+
+// RunSheetClientIDs returns the IDs of the clients of calendar entries, once each
+func RunSheetClientIDs(appointments []*CalendarEntry) []string {
+	var ids []string
+	for _, appointment := range appointments {
+		if !slices.Contains(ids, appointment.ClientID) {
+			ids = append(ids, appointment.ClientID)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRunSheet(t *testing.T) {
+	day := time.Date(2026, 5, 4, 0, 0, 0, 0, time.UTC)
+	at := func(hour int) time.Time { return day.Add(time.Duration(hour) * time.Hour) }
+	appointments := []*CalendarEntry{
+		{AppointmentID: "a-2", ClientID: "client-1", StartTime: at(14), Status: AppointmentStatusScheduled},
+		{AppointmentID: "a-1", ClientID: "client-2", StartTime: at(9), Status: AppointmentStatusConfirmed},
+		{AppointmentID: "a-3", ClientID: "client-1", StartTime: at(11), Status: AppointmentStatusCancelled},
+		{AppointmentID: "a-4", ClientID: "client-3", StartTime: at(16), Status: AppointmentStatusPending},
+	}
+	allergies := "Latex"
+	clients := []*Client{
+		{BaseModel: BaseModel{ID: "client-1"}, Allergies: &allergies},
+		{BaseModel: BaseModel{ID: "client-2"}},
+	}
+	records := []*ServiceRecord{
+		{BaseModel: BaseModel{ID: "r-old"}, ClientID: "client-1", RecordedAt: day.AddDate(0, -2, 0)},
+		{BaseModel: BaseModel{ID: "r-new"}, ClientID: "client-1", RecordedAt: day.AddDate(0, -1, 0)},
+	}
+
+	assert.Equal(t, []string{"client-1", "client-2", "client-3"}, RunSheetClientIDs(appointments))
+
+	sheet := NewRunSheet("staff-1", day, appointments, clients, records)
+	require.Len(t, sheet.Entries, 3, "cancelled appointments are left off")
+	assert.Equal(t, "a-1", sheet.Entries[0].Appointment.AppointmentID)
+	assert.Equal(t, "a-2", sheet.Entries[1].Appointment.AppointmentID)
+	assert.Equal(t, "a-4", sheet.Entries[2].Appointment.AppointmentID)
+
+	assert.Equal(t, &allergies, sheet.Entries[1].Client.Allergies)
+	assert.Equal(t, "r-new", sheet.Entries[1].LastRecord.ID)
+	assert.Nil(t, sheet.Entries[0].LastRecord)
+	assert.Nil(t, sheet.Entries[2].Client, "a client that no longer exists")
+}
+
+func TestClient_GetPreferences(t *testing.T) {
+	preferences, err := (&Client{}).GetPreferences()
+	require.NoError(t, err)
+	assert.Empty(t, preferences)
+
+	encoded := `{"drink": "tea", "music": false}`
+	preferences, err = (&Client{Preferences: &encoded}).GetPreferences()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"drink": "tea", "music": false}, preferences)
+
+	invalid := "tea"
+	_, err = (&Client{Preferences: &invalid}).GetPreferences()
+	assert.ErrorIs(t, err, ErrValidation)
+}
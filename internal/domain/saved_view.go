@@ -0,0 +1,252 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// SavedViewEntity is the list a saved view filters
+type SavedViewEntity string
+
+const (
+	SavedViewClients      SavedViewEntity = "clients"
+	SavedViewAppointments SavedViewEntity = "appointments"
+)
+
+// Bounds of saved views
+const (
+	MaxSavedViewNameLength = 100
+	MaxSavedViewsPerUser   = 50 // Per business and list
+	MaxSavedViewDayOffset  = 366
+)
+
+// savedViewSortFields lists the fields each list can be sorted by
+var savedViewSortFields = map[SavedViewEntity][]string{
+	SavedViewClients:      {"name", "created_at", "last_visit", "total_visits", "total_spent"},
+	SavedViewAppointments: {"start_time", "created_at", "status"},
+}
+
+// SavedViewSortFields returns the fields a list can be sorted by
+func SavedViewSortFields(entity SavedViewEntity) []string {
+	return savedViewSortFields[entity]
+}
+
+// SavedViewFilters are the criteria of a saved view; every criterion set must hold. Client
+// views take the audience criteria of campaigns, appointment views the appointment criteria.
+type SavedViewFilters struct {
+	Search *string `json:"search,omitempty"` // Matched against names, email addresses and phone numbers
+
+	// Clients
+	Audience *TargetAudience `json:"audience,omitempty"`
+
+	// Appointments
+	Statuses       []AppointmentStatus `json:"statuses,omitempty"`
+	StaffIDs       []string            `json:"staff_ids,omitempty"`
+	MyAppointments bool                `json:"my_appointments,omitempty"` // Those of the staff member using the view
+	Confirmed      *bool               `json:"confirmed,omitempty"`       // Whether the client confirmed
+	FromDay        *int                `json:"from_day,omitempty"`        // Days from today, e.g. 1 for tomorrow
+	ToDay          *int                `json:"to_day,omitempty"`          // Days from today, inclusive
+}
+
+// Validate checks that the criteria apply to the list and are consistent
+func (f SavedViewFilters) Validate(entity SavedViewEntity) error {
+	if f.Search != nil && len(*f.Search) > 200 {
+		return fmt.Errorf("%w: search must be at most 200 characters", ErrValidation)
+	}
+	appointmentCriteria := len(f.Statuses) > 0 || len(f.StaffIDs) > 0 || f.MyAppointments || f.Confirmed != nil ||
+		f.FromDay != nil || f.ToDay != nil
+
+	switch entity {
+	case SavedViewClients:
+		if appointmentCriteria {
+			return fmt.Errorf("%w: client views cannot filter by appointment criteria", ErrValidation)
+		}
+		if f.Audience != nil {
+			return f.Audience.Validate()
+		}
+	case SavedViewAppointments:
+		if f.Audience != nil {
+			return fmt.Errorf("%w: appointment views cannot filter by client audience", ErrValidation)
+		}
+		for _, status := range f.Statuses {
+			if !isAppointmentStatus(status) {
+				return fmt.Errorf("%w: unknown appointment status %q", ErrValidation, status)
+			}
+		}
+		for _, days := range []*int{f.FromDay, f.ToDay} {
+			if days != nil && (*days < -MaxSavedViewDayOffset || *days > MaxSavedViewDayOffset) {
+				return fmt.Errorf("%w: day offsets must be within %d days of today", ErrValidation, MaxSavedViewDayOffset)
+			}
+		}
+		if f.FromDay != nil && f.ToDay != nil && *f.FromDay > *f.ToDay {
+			return fmt.Errorf("%w: from_day cannot be after to_day", ErrValidation)
+		}
+	default:
+		return fmt.Errorf("%w: unknown saved view list %q", ErrValidation, entity)
+	}
+	return nil
+}
+
+// DateRange returns the period the day offsets select at a time in a location: from the start
+// of the first day to the end of the last, either bound nil when its offset is not set
+func (f SavedViewFilters) DateRange(now time.Time, loc *time.Location) (*time.Time, *time.Time) {
+	local := now.In(loc)
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+
+	var from, to *time.Time
+	if f.FromDay != nil {
+		start := today.AddDate(0, 0, *f.FromDay)
+		from = &start
+	}
+	if f.ToDay != nil {
+		end := today.AddDate(0, 0, *f.ToDay+1)
+		to = &end
+	}
+	return from, to
+}
+
+// isAppointmentStatus reports whether a status is one appointments can have
+func isAppointmentStatus(status AppointmentStatus) bool {
+	switch status {
+	case AppointmentStatusPending, AppointmentStatusScheduled, AppointmentStatusConfirmed, AppointmentStatusInProgress,
+		AppointmentStatusCompleted, AppointmentStatusCancelled, AppointmentStatusNoShow, AppointmentStatusRescheduled:
+		return true
+	}
+	return false
+}
+
+// SavedView is a named filter and sort of the clients or appointments of a business that a user
+// saved, so that it follows them across devices. A view is private to its owner unless shared
+// with the staff of some roles.
+type SavedView struct {
+	BaseModel
+	BusinessID      string          `gorm:"not null;type:uuid;index" json:"business_id"`
+	UserID          string          `gorm:"not null;type:uuid;index" json:"user_id"` // The owner
+	Name            string          `gorm:"not null;size:100" json:"name"`
+	Entity          SavedViewEntity `gorm:"not null;size:20" json:"entity"`
+	Filters         *string         `gorm:"type:jsonb;default:'{}'" json:"filters,omitempty"` // JSON SavedViewFilters
+	SortField       *string         `gorm:"size:50" json:"sort_field,omitempty"`
+	SortDescending  bool            `gorm:"not null;default:false" json:"sort_descending"`
+	SharedWithRoles *string         `gorm:"type:jsonb;default:'[]'" json:"shared_with_roles,omitempty"` // JSON list of business roles
+}
+
+// TableName returns the table name for SavedView
+func (SavedView) TableName() string { return "saved_views" }
+
+// Validate validates the saved view model
+func (v *SavedView) Validate() error {
+	if v.BusinessID == "" || v.UserID == "" {
+		return ErrValidation
+	}
+	if strings.TrimSpace(v.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrValidation)
+	}
+	if len(v.Name) > MaxSavedViewNameLength {
+		return fmt.Errorf("%w: name must be at most %d characters", ErrValidation, MaxSavedViewNameLength)
+	}
+	filters, err := v.GetFilters()
+	if err != nil {
+		return err
+	}
+	if err := filters.Validate(v.Entity); err != nil {
+		return err
+	}
+	if v.SortField != nil && !slices.Contains(SavedViewSortFields(v.Entity), *v.SortField) {
+		return fmt.Errorf("%w: %s cannot be sorted by %q", ErrValidation, v.Entity, *v.SortField)
+	}
+	roles, err := v.GetSharedWithRoles()
+	if err != nil {
+		return err
+	}
+	for _, role := range roles {
+		if !isBusinessRole(role) {
+			return fmt.Errorf("%w: unknown role %q", ErrValidation, role)
+		}
+	}
+	return nil
+}
+
+// GetFilters decodes the criteria of the view
+func (v *SavedView) GetFilters() (SavedViewFilters, error) {
+	var filters SavedViewFilters
+	if v.Filters == nil || *v.Filters == "" {
+		return filters, nil
+	}
+	if err := json.Unmarshal([]byte(*v.Filters), &filters); err != nil {
+		return filters, fmt.Errorf("%w: invalid saved view filters", ErrValidation)
+	}
+	return filters, nil
+}
+
+// SetFilters encodes the criteria of the view
+func (v *SavedView) SetFilters(filters SavedViewFilters) error {
+	data, err := json.Marshal(filters)
+	if err != nil {
+		return err
+	}
+	encoded := string(data)
+	v.Filters = &encoded
+	return nil
+}
+
+// GetSharedWithRoles decodes the roles whose staff the view is shared with
+func (v *SavedView) GetSharedWithRoles() ([]BusinessRole, error) {
+	var roles []BusinessRole
+	if v.SharedWithRoles == nil || *v.SharedWithRoles == "" {
+		return roles, nil
+	}
+	if err := json.Unmarshal([]byte(*v.SharedWithRoles), &roles); err != nil {
+		return nil, fmt.Errorf("%w: invalid shared roles", ErrValidation)
+	}
+	return roles, nil
+}
+
+// SetSharedWithRoles encodes the roles whose staff the view is shared with, dropping duplicates
+func (v *SavedView) SetSharedWithRoles(roles []BusinessRole) error {
+	unique := make([]BusinessRole, 0, len(roles))
+	for _, role := range roles {
+		if !slices.Contains(unique, role) {
+			unique = append(unique, role)
+		}
+	}
+	data, err := json.Marshal(unique)
+	if err != nil {
+		return err
+	}
+	encoded := string(data)
+	v.SharedWithRoles = &encoded
+	return nil
+}
+
+// VisibleTo reports whether a user with a role in the view's business can use the view
+func (v *SavedView) VisibleTo(userID string, role BusinessRole) bool {
+	if v.UserID == userID {
+		return true
+	}
+	roles, _ := v.GetSharedWithRoles()
+	return slices.Contains(roles, role)
+}
+
+// isBusinessRole reports whether a role is one staff can have
+func isBusinessRole(role BusinessRole) bool {
+	switch role {
+	case BusinessRoleOwner, BusinessRoleManager, BusinessRoleEmployee, BusinessRoleAssistant:
+		return true
+	}
+	return false
+}
+
+// SavedViewRepository defines the repository interface for SavedView
+type SavedViewRepository interface {
+	BaseRepository[SavedView]
+	// FindVisible finds the views of a business a user with a role can use, optionally of one
+	// list only, ordered by name
+	FindVisible(ctx context.Context, businessID, userID string, role BusinessRole, entity *SavedViewEntity) ([]*SavedView, error)
+	// FindByOwnerAndName finds a user's view of a list by name, ignoring case
+	FindByOwnerAndName(ctx context.Context, businessID, userID string, entity SavedViewEntity, name string) (*SavedView, error)
+	CountByOwner(ctx context.Context, businessID, userID string, entity SavedViewEntity) (int64, error)
+}
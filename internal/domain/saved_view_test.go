@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func intPtr(value int) *int { return &value }
+
+func TestSavedViewFiltersValidate(t *testing.T) {
+	confirmed := false
+	tests := []struct {
+		name    string
+		entity  SavedViewEntity
+		filters SavedViewFilters
+		valid   bool
+	}{
+		{"empty client view", SavedViewClients, SavedViewFilters{}, true},
+		{"VIP clients", SavedViewClients, SavedViewFilters{Audience: &TargetAudience{Tags: []string{"vip"}}}, true},
+		{"invalid audience", SavedViewClients, SavedViewFilters{Audience: &TargetAudience{MinVisits: intPtr(-1)}}, false},
+		{"client view with appointment criteria", SavedViewClients, SavedViewFilters{Confirmed: &confirmed}, false},
+		{"unconfirmed tomorrow", SavedViewAppointments, SavedViewFilters{Confirmed: &confirmed, FromDay: intPtr(1), ToDay: intPtr(1)}, true},
+		{"appointment view with audience", SavedViewAppointments, SavedViewFilters{Audience: &TargetAudience{}}, false},
+		{"unknown status", SavedViewAppointments, SavedViewFilters{Statuses: []AppointmentStatus{"lost"}}, false},
+		{"inverted days", SavedViewAppointments, SavedViewFilters{FromDay: intPtr(2), ToDay: intPtr(1)}, false},
+		{"days out of range", SavedViewAppointments, SavedViewFilters{ToDay: intPtr(MaxSavedViewDayOffset + 1)}, false},
+		{"unknown list", "invoices", SavedViewFilters{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.filters.Validate(tt.entity)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, errors.Is(err, ErrValidation))
+			}
+		})
+	}
+}
+
+func TestSavedViewFiltersDateRange(t *testing.T) {
+	lisbon, err := time.LoadLocation("Europe/Lisbon")
+	require.NoError(t, err)
+	now := time.Date(2026, time.October, 17, 23, 30, 0, 0, time.UTC) // 00:30 on the 18th in Lisbon
+
+	from, to := SavedViewFilters{FromDay: intPtr(1), ToDay: intPtr(1)}.DateRange(now, lisbon)
+	require.NotNil(t, from)
+	require.NotNil(t, to)
+	assert.Equal(t, time.Date(2026, time.October, 19, 0, 0, 0, 0, lisbon), *from)
+	assert.Equal(t, time.Date(2026, time.October, 20, 0, 0, 0, 0, lisbon), *to)
+
+	from, to = SavedViewFilters{}.DateRange(now, lisbon)
+	assert.Nil(t, from)
+	assert.Nil(t, to)
+}
+
+func TestSavedView(t *testing.T) {
+	view := &SavedView{BusinessID: "business-1", UserID: "user-1", Name: "My VIPs", Entity: SavedViewClients}
+	require.NoError(t, view.SetFilters(SavedViewFilters{Audience: &TargetAudience{Tags: []string{"vip"}}}))
+	require.NoError(t, view.SetSharedWithRoles([]BusinessRole{BusinessRoleManager, BusinessRoleManager}))
+	require.NoError(t, view.Validate())
+
+	roles, err := view.GetSharedWithRoles()
+	require.NoError(t, err)
+	assert.Equal(t, []BusinessRole{BusinessRoleManager}, roles)
+
+	t.Run("visible to its owner and the roles it is shared with", func(t *testing.T) {
+		assert.True(t, view.VisibleTo("user-1", BusinessRoleEmployee))
+		assert.True(t, view.VisibleTo("user-2", BusinessRoleManager))
+		assert.False(t, view.VisibleTo("user-2", BusinessRoleEmployee))
+	})
+
+	t.Run("sorts by the fields of its list", func(t *testing.T) {
+		sortField := "total_spent"
+		view.SortField = &sortField
+		assert.NoError(t, view.Validate())
+
+		sortField = "start_time"
+		assert.True(t, errors.Is(view.Validate(), ErrValidation))
+		view.SortField = nil
+	})
+
+	t.Run("shares with known roles only", func(t *testing.T) {
+		require.NoError(t, view.SetSharedWithRoles([]BusinessRole{"receptionist"}))
+		assert.True(t, errors.Is(view.Validate(), ErrValidation))
+	})
+}
@@ -0,0 +1,119 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// ConflictStaffUnassigned means the appointment's staff member no longer works for the business
+// at its time
+const ConflictStaffUnassigned ConflictReason = "staff_unassigned"
+
+// ScheduleResolution represents a way to resolve an appointment invalidated by a schedule change
+type ScheduleResolution string
+
+const (
+	ResolutionReschedule ScheduleResolution = "reschedule" // Move the appointment to a time that works under the new schedule
+	ResolutionReassign   ScheduleResolution = "reassign"   // Give the appointment to another staff member
+	ResolutionCancel     ScheduleResolution = "cancel"     // Cancel the appointment and let the client know
+)
+
+// ImpactedAppointment is an upcoming appointment that a schedule change invalidates, with the
+// ways it can be resolved
+type ImpactedAppointment struct {
+	AppointmentID string                  `json:"appointment_id"`
+	StaffID       string                  `json:"staff_id"`
+	StartTime     time.Time               `json:"start_time"`
+	EndTime       time.Time               `json:"end_time"`
+	Conflicts     []*AvailabilityConflict `json:"conflicts"`
+	Resolutions   []ScheduleResolution    `json:"resolutions"`
+}
+
+// ScheduleImpact returns the appointments in the schedules starting at or after from that the
+// proposed rules invalidate, in the order of the schedules and then by start time. Only conflicts
+// the change introduces count: an appointment that already clashed under the current rules is
+// reported only if the change adds a new reason.
+func ScheduleImpact(current, proposed AvailabilityRules, schedules []*StaffSchedule, from time.Time) []*ImpactedAppointment {
+	var impacted []*ImpactedAppointment
+	for _, schedule := range schedules {
+		for _, interval := range schedule.Busy {
+			if interval.StartTime.Before(from) {
+				continue
+			}
+			request := AvailabilityRequest{
+				StaffID:              schedule.StaffID,
+				Start:                interval.StartTime,
+				End:                  interval.EndTime,
+				ExcludeAppointmentID: &interval.AppointmentID,
+			}
+			// Time off was already there before the change, so it is not the change's doing
+			before := current.Check(request, schedule.Busy, nil)
+			var introduced []*AvailabilityConflict
+			for _, conflict := range proposed.Check(request, schedule.Busy, nil) {
+				if !slices.ContainsFunc(before, conflict.sameAs) {
+					introduced = append(introduced, conflict)
+				}
+			}
+			if len(introduced) > 0 {
+				impacted = append(impacted, newImpactedAppointment(schedule.StaffID, interval, introduced))
+			}
+		}
+	}
+	return impacted
+}
+
+// UnassignedImpact returns the appointments in a staff member's schedule starting at or after
+// from, which they can no longer take once they stop working for the business
+func UnassignedImpact(schedule *StaffSchedule, from time.Time) []*ImpactedAppointment {
+	var impacted []*ImpactedAppointment
+	for _, interval := range schedule.Busy {
+		if interval.StartTime.Before(from) {
+			continue
+		}
+		conflict := &AvailabilityConflict{
+			Reason:  ConflictStaffUnassigned,
+			Message: "the staff member no longer works for the business at this time",
+		}
+		impacted = append(impacted, newImpactedAppointment(schedule.StaffID, interval, []*AvailabilityConflict{conflict}))
+	}
+	return impacted
+}
+
+// newImpactedAppointment describes an invalidated appointment with the resolutions its
+// conflicts allow
+func newImpactedAppointment(staffID string, interval *BusyInterval, conflicts []*AvailabilityConflict) *ImpactedAppointment {
+	return &ImpactedAppointment{
+		AppointmentID: interval.AppointmentID,
+		StaffID:       staffID,
+		StartTime:     interval.StartTime,
+		EndTime:       interval.EndTime,
+		Conflicts:     conflicts,
+		Resolutions:   resolutionsFor(conflicts),
+	}
+}
+
+// resolutionsFor returns the ways to resolve an appointment with conflicts. The opening hours
+// apply to every staff member, so another staff member only helps with clashes of their own.
+func resolutionsFor(conflicts []*AvailabilityConflict) []ScheduleResolution {
+	switch {
+	case slices.ContainsFunc(conflicts, func(c *AvailabilityConflict) bool { return c.Reason == ConflictOutsideHours }):
+		return []ScheduleResolution{ResolutionReschedule, ResolutionCancel}
+	case slices.ContainsFunc(conflicts, func(c *AvailabilityConflict) bool { return c.Reason == ConflictStaffUnassigned }):
+		return []ScheduleResolution{ResolutionReassign, ResolutionCancel}
+	default:
+		return []ScheduleResolution{ResolutionReschedule, ResolutionReassign, ResolutionCancel}
+	}
+}
+
+// sameAs reports whether two conflicts have the same cause
+func (c *AvailabilityConflict) sameAs(other *AvailabilityConflict) bool {
+	return c.Reason == other.Reason && equalIDs(c.AppointmentID, other.AppointmentID) && equalIDs(c.ExceptionID, other.ExceptionID)
+}
+
+// equalIDs reports whether two optional IDs are both absent or the same
+func equalIDs(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
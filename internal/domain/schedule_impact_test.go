@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleImpact(t *testing.T) {
+	// 2024-06-03 is a Monday
+	at := func(hour, minute int) time.Time { return time.Date(2024, 6, 3, hour, minute, 0, 0, time.UTC) }
+	current := AvailabilityRules{Location: time.UTC, Hours: DailyHours(9, 19)}
+	schedules := []*StaffSchedule{
+		{StaffID: "staff-1", Busy: []*BusyInterval{
+			{AppointmentID: "past", StartTime: at(9, 0), EndTime: at(10, 0)},
+			{AppointmentID: "morning", StartTime: at(10, 0), EndTime: at(11, 0)},
+			{AppointmentID: "back-to-back", StartTime: at(11, 0), EndTime: at(12, 0)},
+			{AppointmentID: "evening", StartTime: at(18, 0), EndTime: at(19, 0)},
+		}},
+	}
+	from := at(9, 30)
+
+	t.Run("shorter hours", func(t *testing.T) {
+		proposed := current
+		proposed.Hours = DailyHours(9, 18)
+
+		impacted := ScheduleImpact(current, proposed, schedules, from)
+		require.Len(t, impacted, 1)
+		assert.Equal(t, "evening", impacted[0].AppointmentID)
+		assert.Equal(t, "staff-1", impacted[0].StaffID)
+		require.Len(t, impacted[0].Conflicts, 1)
+		assert.Equal(t, ConflictOutsideHours, impacted[0].Conflicts[0].Reason)
+		assert.Equal(t, []ScheduleResolution{ResolutionReschedule, ResolutionCancel}, impacted[0].Resolutions)
+	})
+
+	t.Run("longer buffer", func(t *testing.T) {
+		proposed := current
+		proposed.Buffer = 15 * time.Minute
+
+		impacted := ScheduleImpact(current, proposed, schedules, from)
+		ids := make([]string, len(impacted))
+		for i, appointment := range impacted {
+			ids[i] = appointment.AppointmentID
+		}
+		assert.Equal(t, []string{"morning", "back-to-back"}, ids, "appointments before from are left alone")
+		assert.Equal(t, ConflictOverlap, impacted[0].Conflicts[0].Reason)
+		assert.Equal(t, []ScheduleResolution{ResolutionReschedule, ResolutionReassign, ResolutionCancel}, impacted[0].Resolutions)
+	})
+
+	t.Run("existing conflicts are not the change's doing", func(t *testing.T) {
+		buffered := current
+		buffered.Buffer = 15 * time.Minute
+		longer := buffered
+		longer.Buffer = 30 * time.Minute
+
+		assert.Empty(t, ScheduleImpact(buffered, longer, schedules, from))
+		assert.Empty(t, ScheduleImpact(current, current, schedules, from))
+	})
+}
+
+func TestUnassignedImpact(t *testing.T) {
+	start := time.Date(2024, 6, 3, 10, 0, 0, 0, time.UTC)
+	schedule := &StaffSchedule{StaffID: "staff-1", Busy: []*BusyInterval{
+		{AppointmentID: "before", StartTime: start, EndTime: start.Add(time.Hour)},
+		{AppointmentID: "after", StartTime: start.AddDate(0, 0, 7), EndTime: start.AddDate(0, 0, 7).Add(time.Hour)},
+	}}
+
+	impacted := UnassignedImpact(schedule, start.AddDate(0, 0, 1))
+	require.Len(t, impacted, 1)
+	assert.Equal(t, "after", impacted[0].AppointmentID)
+	assert.Equal(t, ConflictStaffUnassigned, impacted[0].Conflicts[0].Reason)
+	assert.Equal(t, []ScheduleResolution{ResolutionReassign, ResolutionCancel}, impacted[0].Resolutions)
+}
@@ -0,0 +1,236 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// SearchResultKind is the kind of record a search result is
+type SearchResultKind string
+
+const (
+	SearchResultClient  SearchResultKind = "client"
+	SearchResultService SearchResultKind = "service"
+)
+
+// SearchResultKinds are the kinds of record a search covers, in the order ties are listed
+var SearchResultKinds = []SearchResultKind{SearchResultClient, SearchResultService}
+
+// IsValid checks if the search result kind is valid
+func (k SearchResultKind) IsValid() bool {
+	return slices.Contains(SearchResultKinds, k)
+}
+
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 50
+	// minPhoneSearchDigits is how many digits a query needs to be matched against phone numbers
+	minPhoneSearchDigits = 3
+)
+
+// Search weights of the fields matched, as Postgres ts_rank weighs the A, B and C labels of the
+// search vectors
+const (
+	searchWeightName  = 1.0 // Client and service names, and phone numbers
+	searchWeightEmail = 0.4
+	searchWeightNotes = 0.2 // Client notes and service descriptions
+)
+
+// SearchQuery is a search of a business's clients and services. Every term must match the
+// start of a word, ignoring case and accents, so "joao sil" finds João Silva.
+type SearchQuery struct {
+	BusinessID string
+	Terms      []string // Normalised
+	Digits     string   // The query's digits when it looks like a phone number, matched anywhere in phone numbers
+	Kinds      []SearchResultKind
+	Limit      int
+}
+
+// NewSearchQuery parses a search of a business. All kinds of record are searched when none is
+// given, and the limit defaults to DefaultSearchLimit.
+func NewSearchQuery(businessID, text string, kinds []SearchResultKind, limit int) (*SearchQuery, error) {
+	terms := SearchTerms(text)
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("%w: the search must include letters or digits", ErrValidation)
+	}
+	for _, kind := range kinds {
+		if !kind.IsValid() {
+			return nil, fmt.Errorf("%w: invalid search result kind %q", ErrValidation, kind)
+		}
+	}
+	if len(kinds) == 0 {
+		kinds = SearchResultKinds
+	}
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+	return &SearchQuery{
+		BusinessID: businessID,
+		Terms:      terms,
+		Digits:     phoneDigits(text),
+		Kinds:      slices.Clone(kinds),
+		Limit:      min(limit, MaxSearchLimit),
+	}, nil
+}
+
+// Includes reports whether the query searches a kind of record
+func (q *SearchQuery) Includes(kind SearchResultKind) bool {
+	return slices.Contains(q.Kinds, kind)
+}
+
+// NormalizeSearchText lower-cases text and drops its accents, as the search_normalize function
+// of the database does, so that Conceição matches conceicao
+func NormalizeSearchText(text string) string {
+	var normalized strings.Builder
+	for _, r := range norm.NFD.String(strings.ToLower(text)) {
+		if !unicode.Is(unicode.Mn, r) {
+			normalized.WriteRune(r)
+		}
+	}
+	return normalized.String()
+}
+
+// SearchTerms splits text into the normalised words a search matches, which are made of letters
+// and digits only
+func SearchTerms(text string) []string {
+	return strings.FieldsFunc(NormalizeSearchText(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// phoneDigits returns the digits of a query made of digits and the punctuation of phone numbers
+// only, e.g. "+351 912-345", and nothing for other queries
+func phoneDigits(text string) string {
+	var digits strings.Builder
+	for _, r := range text {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case strings.ContainsRune("+-(). ", r):
+		default:
+			return ""
+		}
+	}
+	if digits.Len() < minPhoneSearchDigits {
+		return ""
+	}
+	return digits.String()
+}
+
+// SearchResult is a client or service matching a search, ranked by how well it matches
+type SearchResult struct {
+	Kind    SearchResultKind
+	Client  *Client  // Set for client results
+	Service *Service // Set for service results
+	Rank    float64
+}
+
+// RankClient ranks how well a client matches a search, reporting whether it matches at all.
+// Anonymised clients never match. The database ranks with full-text search and also tolerates
+// typos in names; this is the same matching without typos, for stores without it.
+func RankClient(client *Client, query *SearchQuery) (float64, bool) {
+	if client.AnonymizedAt != nil {
+		return 0, false
+	}
+	rank := 0.0
+	if query.Digits != "" && client.Phone != nil && strings.Contains(onlyDigits(*client.Phone), query.Digits) {
+		rank += searchWeightName
+	}
+	fields := []searchField{
+		{client.FirstName + " " + client.LastName, searchWeightName},
+		{client.Email, searchWeightEmail},
+	}
+	if client.Notes != nil {
+		fields = append(fields, searchField{*client.Notes, searchWeightNotes})
+	}
+	if termsRank, ok := rankTerms(fields, query.Terms); ok {
+		rank += termsRank
+	}
+	return rank, rank > 0
+}
+
+// RankService ranks how well a service matches a search, reporting whether it matches at all
+func RankService(service *Service, query *SearchQuery) (float64, bool) {
+	fields := []searchField{{service.Name, searchWeightName}}
+	if service.Description != nil {
+		fields = append(fields, searchField{*service.Description, searchWeightNotes})
+	}
+	return rankTerms(fields, query.Terms)
+}
+
+// searchField is text a search matches and the weight of its matches
+type searchField struct {
+	text   string
+	weight float64
+}
+
+// rankTerms adds up the weight of the best field each term starts a word of, reporting whether
+// every term did
+func rankTerms(fields []searchField, terms []string) (float64, bool) {
+	words := make([][]string, len(fields))
+	for i, field := range fields {
+		words[i] = SearchTerms(field.text)
+	}
+	rank := 0.0
+	for _, term := range terms {
+		best := 0.0
+		for i, field := range fields {
+			if field.weight > best && slices.ContainsFunc(words[i], func(word string) bool { return strings.HasPrefix(word, term) }) {
+				best = field.weight
+			}
+		}
+		if best == 0 {
+			return 0, false
+		}
+		rank += best
+	}
+	return rank / float64(len(terms)), true
+}
+
+// onlyDigits returns the digits of text
+func onlyDigits(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, text)
+}
+
+// SortSearchResults orders search results best match first, then by kind and name
+func SortSearchResults(results []*SearchResult) {
+	slices.SortStableFunc(results, func(a, b *SearchResult) int {
+		if a.Rank != b.Rank {
+			if a.Rank > b.Rank {
+				return -1
+			}
+			return 1
+		}
+		if a.Kind != b.Kind {
+			return slices.Index(SearchResultKinds, a.Kind) - slices.Index(SearchResultKinds, b.Kind)
+		}
+		return strings.Compare(NormalizeSearchText(a.title()), NormalizeSearchText(b.title()))
+	})
+}
+
+// title returns the name a search result is listed by
+func (r *SearchResult) title() string {
+	if r.Client != nil {
+		return r.Client.GetFullName()
+	}
+	if r.Service != nil {
+		return r.Service.Name
+	}
+	return ""
+}
+
+// SearchRepository searches the clients and services of a business
+type SearchRepository interface {
+	// Search returns up to query.Limit clients and services matching a query, best match first
+	Search(ctx context.Context, query *SearchQuery) ([]*SearchResult, error)
+}
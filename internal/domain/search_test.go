@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchTerms(t *testing.T) {
+	assert.Equal(t, []string{"conceicao", "joao"}, SearchTerms("Conceição, João"))
+	assert.Equal(t, []string{"ana", "sofia", "example", "pt"}, SearchTerms("ana.sofia@example.pt"))
+	assert.Empty(t, SearchTerms(" -- "))
+}
+
+func TestNewSearchQuery(t *testing.T) {
+	query, err := NewSearchQuery("business", "Gonçalves", nil, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"goncalves"}, query.Terms)
+	assert.Empty(t, query.Digits)
+	assert.Equal(t, SearchResultKinds, query.Kinds)
+	assert.Equal(t, DefaultSearchLimit, query.Limit)
+
+	query, err = NewSearchQuery("business", "+351 912-345", []SearchResultKind{SearchResultClient}, 500)
+	require.NoError(t, err)
+	assert.Equal(t, "351912345", query.Digits)
+	assert.True(t, query.Includes(SearchResultClient))
+	assert.False(t, query.Includes(SearchResultService))
+	assert.Equal(t, MaxSearchLimit, query.Limit)
+
+	query, err = NewSearchQuery("business", "12", nil, 0)
+	require.NoError(t, err)
+	assert.Empty(t, query.Digits, "too few digits to search phone numbers")
+
+	_, err = NewSearchQuery("business", "?!", nil, 0)
+	assert.ErrorIs(t, err, ErrValidation)
+	_, err = NewSearchQuery("business", "ana", []SearchResultKind{"appointment"}, 0)
+	assert.ErrorIs(t, err, ErrValidation)
+}
+
+func TestRankClient(t *testing.T) {
+	notes := "Prefers Sónia for colour"
+	phone := "+351 912 345 678"
+	client := &Client{FirstName: "João", LastName: "Conceição", Email: "jc@example.pt", Phone: &phone, Notes: &notes}
+	search := func(text string) (float64, bool) {
+		query, err := NewSearchQuery("business", text, nil, 0)
+		require.NoError(t, err)
+		return RankClient(client, query)
+	}
+
+	byName, ok := search("joao conc")
+	assert.True(t, ok, "terms match word prefixes ignoring accents")
+	byNotes, ok := search("sonia")
+	assert.True(t, ok)
+	assert.Greater(t, byName, byNotes, "names weigh more than notes")
+
+	_, ok = search("joao silva")
+	assert.False(t, ok, "every term must match")
+	_, ok = search("ceicao")
+	assert.False(t, ok, "terms match the start of words only")
+
+	_, ok = search("912 345")
+	assert.True(t, ok, "digits match phone numbers anywhere")
+
+	anonymizedAt := time.Now()
+	client.AnonymizedAt = &anonymizedAt
+	_, ok = search("joao")
+	assert.False(t, ok, "anonymised clients are never found")
+}
+
+func TestSortSearchResults(t *testing.T) {
+	results := []*SearchResult{
+		{Kind: SearchResultService, Service: &Service{Name: "Cut"}, Rank: 1},
+		{Kind: SearchResultClient, Client: &Client{FirstName: "Zé", LastName: "Sousa"}, Rank: 1},
+		{Kind: SearchResultClient, Client: &Client{FirstName: "Álvaro", LastName: "Sousa"}, Rank: 1},
+		{Kind: SearchResultService, Service: &Service{Name: "Colour"}, Rank: 2},
+	}
+	SortSearchResults(results)
+
+	assert.Equal(t, "Colour", results[0].Service.Name)
+	assert.Equal(t, "Álvaro", results[1].Client.FirstName)
+	assert.Equal(t, "Zé", results[2].Client.FirstName)
+	assert.Equal(t, "Cut", results[3].Service.Name)
+}
@@ -18,10 +18,14 @@ type Service struct {
 	DisplayOrder int             `gorm:"not null;default:0" json:"display_order"`
 	PreparationTime *int         `gorm:"default:0" json:"preparation_time,omitempty"` // Buffer time before in minutes
 	CleanupTime     *int         `gorm:"default:0" json:"cleanup_time,omitempty"`     // Buffer time after in minutes
-	MaxAdvanceBooking *int       `gorm:"" json:"max_advance_booking,omitempty"`       // Days in advance
-	MinAdvanceBooking *int       `gorm:"default:0" json:"min_advance_booking,omitempty"` // Hours in advance
+	MaxAdvanceBooking *int       `gorm:"" json:"max_advance_booking,omitempty"`       // Days in advance; the business's horizon when nil
+	MinAdvanceBooking *int       `gorm:"" json:"min_advance_booking,omitempty"`       // Hours in advance; the business's lead time when nil
 	RequiresDeposit   bool       `gorm:"not null;default:false" json:"requires_deposit"`
 	DepositAmount     *decimal.Decimal `gorm:"type:decimal(10,2)" json:"deposit_amount,omitempty"`
+	MinAge            *int       `gorm:"" json:"min_age,omitempty"` // Minimum client age in years
+	OnlineBooking     OnlineBookingMode `gorm:"not null;size:20;default:'bookable'" json:"online_booking"`
+	ReturningClientsOnly bool    `gorm:"not null;default:false" json:"returning_clients_only"` // New clients need a consultation first
+	MaxPerDay         *int       `gorm:"" json:"max_per_day,omitempty"` // Online bookings a day across staff; no limit when nil
 
 	// Relationships
 	Business Business        `gorm:"foreignKey:BusinessID;constraint:OnDelete:CASCADE" json:"business"`
@@ -48,7 +52,13 @@ func (s *Service) Validate() error {
 	if s.DepositAmount != nil && s.DepositAmount.IsNegative() {
 		return ErrValidation
 	}
-	return nil
+	if s.MinAdvanceBooking != nil && (*s.MinAdvanceBooking < 0 || *s.MinAdvanceBooking > MaxBookingLeadHours) {
+		return ErrValidation
+	}
+	if s.MaxAdvanceBooking != nil && (*s.MaxAdvanceBooking < 0 || *s.MaxAdvanceBooking > MaxBookingHorizonDays) {
+		return ErrValidation
+	}
+	return s.validateOnlineBooking()
 }
 
 // GetFullName returns the full display name including category
@@ -0,0 +1,214 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// BundleItem is one service of a bundle, in the order it is performed
+type BundleItem struct {
+	ServiceID string `json:"service_id"`
+	// ProcessingMinutes is the trailing part of the service during which the client waits and
+	// the staff member is free, e.g. while colour develops
+	ProcessingMinutes int `json:"processing_minutes,omitempty"`
+	// DuringPreviousProcessing starts the service as soon as the previous one starts processing
+	DuringPreviousProcessing bool `json:"during_previous_processing,omitempty"`
+}
+
+// ServiceBundle is a set of services booked together at a combined price
+type ServiceBundle struct {
+	BaseModel
+	BusinessID  string          `gorm:"not null;type:uuid;index" json:"business_id"`
+	Name        string          `gorm:"not null;size:100" json:"name"`
+	Description *string         `gorm:"type:text" json:"description,omitempty"`
+	Price       decimal.Decimal `gorm:"type:decimal(10,2);not null" json:"price"`
+	IsActive    bool            `gorm:"not null;default:true" json:"is_active"`         // Inactive bundles cannot be booked
+	Items       *string         `gorm:"type:jsonb;default:'[]'" json:"items,omitempty"` // JSON array of BundleItem
+}
+
+// TableName returns the table name for ServiceBundle
+func (ServiceBundle) TableName() string { return "service_bundles" }
+
+// Validate validates the service bundle model
+func (b *ServiceBundle) Validate() error {
+	if b.BusinessID == "" {
+		return ErrValidation
+	}
+	if b.Name == "" {
+		return ErrValidation
+	}
+	if b.Price.IsNegative() {
+		return fmt.Errorf("%w: the bundle price must not be negative", ErrValidation)
+	}
+
+	items, err := b.GetItems()
+	if err != nil {
+		return err
+	}
+	if len(items) < 2 {
+		return fmt.Errorf("%w: a bundle needs at least two services", ErrValidation)
+	}
+	seen := map[string]bool{}
+	for i, item := range items {
+		if item.ServiceID == "" {
+			return fmt.Errorf("%w: bundle item %d has no service", ErrValidation, i+1)
+		}
+		if seen[item.ServiceID] {
+			return fmt.Errorf("%w: a service appears twice in the bundle", ErrValidation)
+		}
+		seen[item.ServiceID] = true
+		if item.ProcessingMinutes < 0 {
+			return fmt.Errorf("%w: processing minutes must not be negative", ErrValidation)
+		}
+		if i == 0 && item.DuringPreviousProcessing {
+			return fmt.Errorf("%w: the first service of a bundle cannot overlap a previous one", ErrValidation)
+		}
+	}
+	return nil
+}
+
+// GetItems decodes the services of the bundle
+func (b *ServiceBundle) GetItems() ([]BundleItem, error) {
+	items := []BundleItem{}
+	if b.Items == nil || *b.Items == "" {
+		return items, nil
+	}
+	if err := json.Unmarshal([]byte(*b.Items), &items); err != nil {
+		return nil, fmt.Errorf("%w: invalid bundle items", ErrValidation)
+	}
+	return items, nil
+}
+
+// SetItems encodes the services of the bundle
+func (b *ServiceBundle) SetItems(items []BundleItem) error {
+	if items == nil {
+		items = []BundleItem{}
+	}
+	data, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+	encoded := string(data)
+	b.Items = &encoded
+	return nil
+}
+
+// ServiceIDs returns the IDs of the bundle's services, in order
+func (b *ServiceBundle) ServiceIDs() []string {
+	items, _ := b.GetItems()
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ServiceID
+	}
+	return ids
+}
+
+// BundleStep is when a service of a bundle is performed, relative to the start of the booking
+type BundleStep struct {
+	ServiceID     string          `json:"service_id"`
+	ServiceName   string          `json:"service_name"`
+	OffsetMinutes int             `json:"offset_minutes"`
+	Duration      int             `json:"duration"` // Minutes
+	Price         decimal.Decimal `json:"price"`    // Share of the bundle price
+}
+
+// BundlePlan is the schedule and pricing of a bundle
+type BundlePlan struct {
+	Steps              []BundleStep    `json:"steps"`
+	Duration           int             `json:"duration"`            // Minutes, with overlapping processing time
+	SequentialDuration int             `json:"sequential_duration"` // Minutes, one service after another
+	RegularPrice       decimal.Decimal `json:"regular_price"`       // Sum of the service prices
+	Price              decimal.Decimal `json:"price"`
+}
+
+// Savings returns how much less the bundle costs than its services booked separately
+func (p *BundlePlan) Savings() decimal.Decimal {
+	return p.RegularPrice.Sub(p.Price)
+}
+
+// Plan schedules the bundle's services and splits its price between them. A service marked
+// DuringPreviousProcessing starts when the previous service starts processing; any other
+// service starts once everything before it has finished. services must contain every service
+// of the bundle.
+func (b *ServiceBundle) Plan(services map[string]*Service) (*BundlePlan, error) {
+	items, err := b.GetItems()
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &BundlePlan{Steps: make([]BundleStep, 0, len(items)), Price: b.Price}
+	end, previousProcessingStart := 0, 0
+	for _, item := range items {
+		service, ok := services[item.ServiceID]
+		if !ok {
+			return nil, fmt.Errorf("%w: bundle service %s not found", ErrValidation, item.ServiceID)
+		}
+		if item.ProcessingMinutes > service.Duration {
+			return nil, fmt.Errorf("%w: %s cannot process for longer than it lasts", ErrValidation, service.Name)
+		}
+
+		offset := end
+		if item.DuringPreviousProcessing {
+			offset = previousProcessingStart
+		}
+		plan.Steps = append(plan.Steps, BundleStep{
+			ServiceID:     service.ID,
+			ServiceName:   service.Name,
+			OffsetMinutes: offset,
+			Duration:      service.Duration,
+		})
+		end = max(end, offset+service.Duration)
+		previousProcessingStart = offset + service.Duration - item.ProcessingMinutes
+
+		plan.SequentialDuration += service.Duration
+		plan.RegularPrice = plan.RegularPrice.Add(service.Price)
+	}
+	plan.Duration = end
+
+	allocatePrice(plan, services)
+	return plan, nil
+}
+
+// allocatePrice splits the bundle price between the steps in proportion to their regular
+// prices, giving any rounding remainder to the last step
+func allocatePrice(plan *BundlePlan, services map[string]*Service) {
+	if len(plan.Steps) == 0 {
+		return
+	}
+	remaining := plan.Price
+	for i := range plan.Steps[:len(plan.Steps)-1] {
+		share := decimal.Zero
+		if plan.RegularPrice.IsPositive() {
+			share = plan.Price.Mul(services[plan.Steps[i].ServiceID].Price).Div(plan.RegularPrice).Round(2)
+		}
+		plan.Steps[i].Price = share
+		remaining = remaining.Sub(share)
+	}
+	plan.Steps[len(plan.Steps)-1].Price = remaining
+}
+
+// AppointmentLine is a service performed during an appointment
+type AppointmentLine struct {
+	BaseModel
+	AppointmentID string          `gorm:"not null;type:uuid;index" json:"appointment_id"`
+	ServiceID     string          `gorm:"not null;type:uuid" json:"service_id"`
+	StaffID       string          `gorm:"not null;type:uuid" json:"staff_id"`
+	Duration      int             `gorm:"not null" json:"duration"` // Minutes
+	Price         decimal.Decimal `gorm:"type:decimal(10,2);not null" json:"price"`
+	Notes         *string         `gorm:"type:text" json:"notes,omitempty"`
+}
+
+// TableName returns the table name for AppointmentLine
+func (AppointmentLine) TableName() string { return "appointment_services" }
+
+// ServiceBundleRepository defines the repository interface for ServiceBundle
+type ServiceBundleRepository interface {
+	BaseRepository[ServiceBundle]
+	FindByBusinessID(ctx context.Context, businessID string, activeOnly bool) ([]*ServiceBundle, error)
+	ExistsByNameAndBusiness(ctx context.Context, name, businessID string, excludeID *string) (bool, error)
+	// FindServices finds the business's services with the given IDs, keyed by ID
+	FindServices(ctx context.Context, businessID string, serviceIDs []string) (map[string]*Service, error)
+}
@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func bundleServices() map[string]*Service {
+	return map[string]*Service{
+		"colour":   {BaseModel: BaseModel{ID: "colour"}, Name: "Colour", Duration: 90, Price: decimal.NewFromInt(60)},
+		"manicure": {BaseModel: BaseModel{ID: "manicure"}, Name: "Manicure", Duration: 45, Price: decimal.NewFromInt(25)},
+		"cut":      {BaseModel: BaseModel{ID: "cut"}, Name: "Cut", Duration: 45, Price: decimal.NewFromInt(30)},
+		"blowdry":  {BaseModel: BaseModel{ID: "blowdry"}, Name: "Blow-dry", Duration: 30, Price: decimal.NewFromInt(20)},
+	}
+}
+
+func newBundle(t *testing.T, price int64, items ...BundleItem) *ServiceBundle {
+	t.Helper()
+
+	bundle := &ServiceBundle{BusinessID: "business-1", Name: "Pamper day", Price: decimal.NewFromInt(price), IsActive: true}
+	require.NoError(t, bundle.SetItems(items))
+	return bundle
+}
+
+func TestServiceBundle_Validate(t *testing.T) {
+	bundle := newBundle(t, 100, BundleItem{ServiceID: "cut"}, BundleItem{ServiceID: "blowdry"})
+	require.NoError(t, bundle.Validate())
+
+	tests := []struct {
+		name  string
+		items []BundleItem
+	}{
+		{"single service", []BundleItem{{ServiceID: "cut"}}},
+		{"duplicate service", []BundleItem{{ServiceID: "cut"}, {ServiceID: "cut"}}},
+		{"negative processing", []BundleItem{{ServiceID: "cut", ProcessingMinutes: -5}, {ServiceID: "blowdry"}}},
+		{"first service overlaps", []BundleItem{{ServiceID: "cut", DuringPreviousProcessing: true}, {ServiceID: "blowdry"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bundle := newBundle(t, 100, tt.items...)
+			assert.True(t, errors.Is(bundle.Validate(), ErrValidation))
+		})
+	}
+}
+
+func TestServiceBundle_Plan(t *testing.T) {
+	// The manicure is done while the colour develops; the cut waits for the colour to be rinsed
+	bundle := newBundle(t, 120,
+		BundleItem{ServiceID: "colour", ProcessingMinutes: 30},
+		BundleItem{ServiceID: "manicure", DuringPreviousProcessing: true},
+		BundleItem{ServiceID: "cut"},
+		BundleItem{ServiceID: "blowdry"},
+	)
+
+	plan, err := bundle.Plan(bundleServices())
+	require.NoError(t, err)
+
+	offsets := make([]int, len(plan.Steps))
+	for i, step := range plan.Steps {
+		offsets[i] = step.OffsetMinutes
+	}
+	assert.Equal(t, []int{0, 60, 105, 150}, offsets)
+	assert.Equal(t, 180, plan.Duration)
+	assert.Equal(t, 210, plan.SequentialDuration)
+
+	assert.Equal(t, "135", plan.RegularPrice.String())
+	assert.Equal(t, "15", plan.Savings().String())
+	total := decimal.Zero
+	for _, step := range plan.Steps {
+		total = total.Add(step.Price)
+	}
+	assert.True(t, total.Equal(bundle.Price), "step prices add up to the bundle price")
+	assert.Equal(t, "53.33", plan.Steps[0].Price.StringFixed(2))
+}
+
+func TestServiceBundle_PlanOverlapShorterThanProcessing(t *testing.T) {
+	// A short service during a long processing time does not extend the booking
+	services := bundleServices()
+	bundle := newBundle(t, 70,
+		BundleItem{ServiceID: "colour", ProcessingMinutes: 60},
+		BundleItem{ServiceID: "blowdry", DuringPreviousProcessing: true},
+	)
+
+	plan, err := bundle.Plan(services)
+	require.NoError(t, err)
+	assert.Equal(t, 30, plan.Steps[1].OffsetMinutes)
+	assert.Equal(t, 90, plan.Duration)
+}
+
+func TestServiceBundle_PlanErrors(t *testing.T) {
+	missing := newBundle(t, 50, BundleItem{ServiceID: "cut"}, BundleItem{ServiceID: "perm"})
+	_, err := missing.Plan(bundleServices())
+	assert.True(t, errors.Is(err, ErrValidation))
+
+	tooLong := newBundle(t, 50, BundleItem{ServiceID: "cut", ProcessingMinutes: 60}, BundleItem{ServiceID: "blowdry"})
+	_, err = tooLong.Plan(bundleServices())
+	assert.True(t, errors.Is(err, ErrValidation))
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ServiceCompletion records the outcome of a completed appointment
+type ServiceCompletion struct {
+	BaseModel
+	AppointmentID     string          `gorm:"not null;type:uuid;index" json:"appointment_id"`
+	PriceCharged      decimal.Decimal `gorm:"type:decimal(10,2);not null" json:"price_charged"`
+	PaymentMethod     string          `gorm:"not null;size:20" json:"payment_method"`
+	ProviderConfirmed bool            `gorm:"not null;default:false" json:"provider_confirmed"`
+	ClientConfirmed   bool            `gorm:"not null;default:false" json:"client_confirmed"`
+	CompletionDate    *time.Time      `gorm:"" json:"completion_date,omitempty"`
+	ActualDuration    *int            `gorm:"" json:"actual_duration,omitempty"` // Duration in minutes
+
+	// Relationships
+	Appointment Appointment `gorm:"foreignKey:AppointmentID;constraint:OnDelete:CASCADE" json:"appointment"`
+}
+
+// TableName returns the table name for ServiceCompletion
+func (ServiceCompletion) TableName() string { return "service_completions" }
+
+// Validate validates the service completion model
+func (sc *ServiceCompletion) Validate() error {
+	if sc.AppointmentID == "" {
+		return ErrValidation
+	}
+	if sc.PaymentMethod == "" {
+		return ErrValidation
+	}
+	if sc.PriceCharged.IsNegative() {
+		return ErrValidation
+	}
+	if sc.ActualDuration != nil && *sc.ActualDuration <= 0 {
+		return ErrValidation
+	}
+	return nil
+}
+
+// ServiceCompletionRepository defines the repository interface for ServiceCompletion
+type ServiceCompletionRepository interface {
+	BaseRepository[ServiceCompletion]
+	FindByAppointmentID(ctx context.Context, appointmentID string) (*ServiceCompletion, error)
+}
@@ -0,0 +1,344 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ServiceRecordFieldType represents the kind of value a template field captures
+type ServiceRecordFieldType string
+
+const (
+	ServiceRecordFieldText     ServiceRecordFieldType = "text"
+	ServiceRecordFieldNumber   ServiceRecordFieldType = "number"
+	ServiceRecordFieldSelect   ServiceRecordFieldType = "select"
+	ServiceRecordFieldDuration ServiceRecordFieldType = "duration" // Duration in minutes
+)
+
+// IsValid checks if the field type is valid
+func (t ServiceRecordFieldType) IsValid() bool {
+	switch t {
+	case ServiceRecordFieldText, ServiceRecordFieldNumber, ServiceRecordFieldSelect, ServiceRecordFieldDuration:
+		return true
+	default:
+		return false
+	}
+}
+
+// ServiceRecordField describes a single field of a service record template
+type ServiceRecordField struct {
+	Key      string                 `json:"key"`
+	Label    string                 `json:"label"`
+	Type     ServiceRecordFieldType `json:"type"`
+	Required bool                   `json:"required"`
+	Unit     *string                `json:"unit,omitempty"`    // e.g. "vol", "g", "ml"
+	Options  []string               `json:"options,omitempty"` // Allowed values for select fields
+}
+
+// ServiceRecordTemplate defines the structured fields recorded for a service category
+type ServiceRecordTemplate struct {
+	BaseModel
+	BusinessID  string  `gorm:"not null;type:uuid;index" json:"business_id"`
+	CategoryID  *string `gorm:"type:uuid;index" json:"category_id,omitempty"`
+	Name        string  `gorm:"not null;size:100" json:"name"`
+	Description *string `gorm:"type:text" json:"description,omitempty"`
+	Fields      *string `gorm:"type:jsonb;default:'[]'" json:"fields,omitempty"` // JSON array of ServiceRecordField
+	IsActive    bool    `gorm:"not null;default:true" json:"is_active"`
+
+	// Relationships
+	Business Business         `gorm:"foreignKey:BusinessID;constraint:OnDelete:CASCADE" json:"business"`
+	Category *ServiceCategory `gorm:"foreignKey:CategoryID;constraint:OnDelete:SET NULL" json:"category,omitempty"`
+}
+
+// TableName returns the table name for ServiceRecordTemplate
+func (ServiceRecordTemplate) TableName() string { return "service_record_templates" }
+
+// GetFields decodes the template field definitions
+func (t *ServiceRecordTemplate) GetFields() ([]ServiceRecordField, error) {
+	var fields []ServiceRecordField
+	if t.Fields == nil || *t.Fields == "" {
+		return fields, nil
+	}
+	if err := json.Unmarshal([]byte(*t.Fields), &fields); err != nil {
+		return nil, fmt.Errorf("%w: invalid template fields", ErrValidation)
+	}
+	return fields, nil
+}
+
+// SetFields encodes the template field definitions
+func (t *ServiceRecordTemplate) SetFields(fields []ServiceRecordField) error {
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	encoded := string(data)
+	t.Fields = &encoded
+	return nil
+}
+
+// Validate validates the service record template model
+func (t *ServiceRecordTemplate) Validate() error {
+	if t.BusinessID == "" {
+		return ErrValidation
+	}
+	if t.Name == "" {
+		return ErrValidation
+	}
+
+	fields, err := t.GetFields()
+	if err != nil {
+		return err
+	}
+	if len(fields) == 0 {
+		return fmt.Errorf("%w: template must define at least one field", ErrValidation)
+	}
+
+	seen := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		if field.Key == "" || field.Label == "" {
+			return fmt.Errorf("%w: template fields require a key and label", ErrValidation)
+		}
+		if seen[field.Key] {
+			return fmt.Errorf("%w: duplicate template field %q", ErrValidation, field.Key)
+		}
+		seen[field.Key] = true
+		if !field.Type.IsValid() {
+			return fmt.Errorf("%w: invalid type %q for field %q", ErrValidation, field.Type, field.Key)
+		}
+		if field.Type == ServiceRecordFieldSelect && len(field.Options) == 0 {
+			return fmt.Errorf("%w: select field %q requires options", ErrValidation, field.Key)
+		}
+	}
+	return nil
+}
+
+// ProductUsage records a product used during a service, such as a colour in a formula
+type ProductUsage struct {
+	ProductID *string  `json:"product_id,omitempty"`
+	Name      string   `json:"name"`
+	Brand     *string  `json:"brand,omitempty"`
+	Shade     *string  `json:"shade,omitempty"`
+	Amount    *float64 `json:"amount,omitempty"`
+	Unit      *string  `json:"unit,omitempty"`
+}
+
+// ServiceRecord captures what was done for a client during a visit
+type ServiceRecord struct {
+	BaseModel
+	BusinessID          string    `gorm:"not null;type:uuid;index" json:"business_id"`
+	ClientID            string    `gorm:"not null;type:uuid;index" json:"client_id"`
+	AppointmentID       *string   `gorm:"type:uuid;index" json:"appointment_id,omitempty"`
+	ServiceCompletionID *string   `gorm:"type:uuid;index" json:"service_completion_id,omitempty"`
+	ServiceID           *string   `gorm:"type:uuid" json:"service_id,omitempty"`
+	StaffID             *string   `gorm:"type:uuid" json:"staff_id,omitempty"`
+	TemplateID          *string   `gorm:"type:uuid" json:"template_id,omitempty"`
+	CopiedFromID        *string   `gorm:"type:uuid" json:"copied_from_id,omitempty"`
+	RecordedAt          time.Time `gorm:"not null;default:now()" json:"recorded_at"`
+	FieldValues         *string   `gorm:"type:jsonb;default:'{}'" json:"field_values,omitempty"`  // JSON object keyed by template field
+	ProductsUsed        *string   `gorm:"type:jsonb;default:'[]'" json:"products_used,omitempty"` // JSON array of ProductUsage
+	ProcessingTime      *int      `gorm:"" json:"processing_time,omitempty"`                      // Processing time in minutes
+	Notes               *string   `gorm:"type:text" json:"notes,omitempty"`
+	IsDraft             bool      `gorm:"not null;default:false" json:"is_draft"`
+
+	// Relationships
+	Client   Client                 `gorm:"foreignKey:ClientID;constraint:OnDelete:CASCADE" json:"client"`
+	Template *ServiceRecordTemplate `gorm:"foreignKey:TemplateID;constraint:OnDelete:SET NULL" json:"template,omitempty"`
+}
+
+// TableName returns the table name for ServiceRecord
+func (ServiceRecord) TableName() string { return "service_records" }
+
+// GetFieldValues decodes the recorded field values
+func (r *ServiceRecord) GetFieldValues() (map[string]any, error) {
+	values := map[string]any{}
+	if r.FieldValues == nil || *r.FieldValues == "" {
+		return values, nil
+	}
+	if err := json.Unmarshal([]byte(*r.FieldValues), &values); err != nil {
+		return nil, fmt.Errorf("%w: invalid field values", ErrValidation)
+	}
+	return values, nil
+}
+
+// SetFieldValues encodes the recorded field values
+func (r *ServiceRecord) SetFieldValues(values map[string]any) error {
+	if values == nil {
+		values = map[string]any{}
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	encoded := string(data)
+	r.FieldValues = &encoded
+	return nil
+}
+
+// GetProductsUsed decodes the products used during the service
+func (r *ServiceRecord) GetProductsUsed() ([]ProductUsage, error) {
+	products := []ProductUsage{}
+	if r.ProductsUsed == nil || *r.ProductsUsed == "" {
+		return products, nil
+	}
+	if err := json.Unmarshal([]byte(*r.ProductsUsed), &products); err != nil {
+		return nil, fmt.Errorf("%w: invalid products used", ErrValidation)
+	}
+	return products, nil
+}
+
+// SetProductsUsed encodes the products used during the service
+func (r *ServiceRecord) SetProductsUsed(products []ProductUsage) error {
+	if products == nil {
+		products = []ProductUsage{}
+	}
+	data, err := json.Marshal(products)
+	if err != nil {
+		return err
+	}
+	encoded := string(data)
+	r.ProductsUsed = &encoded
+	return nil
+}
+
+// Validate validates the service record model
+func (r *ServiceRecord) Validate() error {
+	if r.BusinessID == "" {
+		return ErrValidation
+	}
+	if r.ClientID == "" {
+		return ErrValidation
+	}
+	if r.ProcessingTime != nil && *r.ProcessingTime < 0 {
+		return ErrValidation
+	}
+
+	products, err := r.GetProductsUsed()
+	if err != nil {
+		return err
+	}
+	for _, product := range products {
+		if product.Name == "" {
+			return fmt.Errorf("%w: products used require a name", ErrValidation)
+		}
+		if product.Amount != nil && *product.Amount < 0 {
+			return fmt.Errorf("%w: product amount cannot be negative", ErrValidation)
+		}
+	}
+
+	_, err = r.GetFieldValues()
+	return err
+}
+
+// ValidateAgainstTemplate checks the recorded values against the template field definitions.
+// Drafts may omit required fields so they can be completed during the visit.
+func (r *ServiceRecord) ValidateAgainstTemplate(template *ServiceRecordTemplate) error {
+	fields, err := template.GetFields()
+	if err != nil {
+		return err
+	}
+	values, err := r.GetFieldValues()
+	if err != nil {
+		return err
+	}
+
+	known := make(map[string]ServiceRecordField, len(fields))
+	for _, field := range fields {
+		known[field.Key] = field
+	}
+	for key := range values {
+		if _, ok := known[key]; !ok {
+			return fmt.Errorf("%w: unknown field %q", ErrValidation, key)
+		}
+	}
+
+	for _, field := range fields {
+		value, ok := values[field.Key]
+		if !ok || value == nil || value == "" {
+			if field.Required && !r.IsDraft {
+				return fmt.Errorf("%w: field %q is required", ErrValidation, field.Key)
+			}
+			continue
+		}
+
+		switch field.Type {
+		case ServiceRecordFieldNumber, ServiceRecordFieldDuration:
+			number, ok := value.(float64)
+			if !ok {
+				return fmt.Errorf("%w: field %q must be a number", ErrValidation, field.Key)
+			}
+			if field.Type == ServiceRecordFieldDuration && number < 0 {
+				return fmt.Errorf("%w: field %q cannot be negative", ErrValidation, field.Key)
+			}
+		case ServiceRecordFieldSelect:
+			option, ok := value.(string)
+			if !ok || !containsString(field.Options, option) {
+				return fmt.Errorf("%w: invalid option for field %q", ErrValidation, field.Key)
+			}
+		default:
+			if _, ok := value.(string); !ok {
+				return fmt.Errorf("%w: field %q must be text", ErrValidation, field.Key)
+			}
+		}
+	}
+	return nil
+}
+
+// CopyForward creates a draft record for a follow-up appointment, carrying over the
+// formula, products and processing time so they can be adjusted rather than re-entered
+func (r *ServiceRecord) CopyForward(appointmentID string) *ServiceRecord {
+	copyRecord := &ServiceRecord{
+		BusinessID:     r.BusinessID,
+		ClientID:       r.ClientID,
+		AppointmentID:  &appointmentID,
+		ServiceID:      r.ServiceID,
+		StaffID:        r.StaffID,
+		TemplateID:     r.TemplateID,
+		CopiedFromID:   &r.ID,
+		RecordedAt:     time.Now(),
+		FieldValues:    r.FieldValues,
+		ProductsUsed:   r.ProductsUsed,
+		ProcessingTime: r.ProcessingTime,
+		Notes:          r.Notes,
+		IsDraft:        true,
+	}
+	return copyRecord
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ServiceRecordSearch represents the criteria for searching service record history
+type ServiceRecordSearch struct {
+	ClientID      *string    `json:"client_id,omitempty"`
+	ServiceID     *string    `json:"service_id,omitempty"`
+	StaffID       *string    `json:"staff_id,omitempty"`
+	Query         string     `json:"query,omitempty"` // Matches notes, field values and products used
+	DateRange     *DateRange `json:"date_range,omitempty"`
+	IncludeDrafts bool       `json:"include_drafts"`
+}
+
+// ServiceRecordTemplateRepository defines the repository interface for ServiceRecordTemplate
+type ServiceRecordTemplateRepository interface {
+	BaseRepository[ServiceRecordTemplate]
+	FindByBusinessID(ctx context.Context, businessID string) ([]*ServiceRecordTemplate, error)
+	FindActiveByCategory(ctx context.Context, businessID, categoryID string) (*ServiceRecordTemplate, error)
+}
+
+// ServiceRecordRepository defines the repository interface for ServiceRecord
+type ServiceRecordRepository interface {
+	BaseRepository[ServiceRecord]
+	FindByClientID(ctx context.Context, clientID string, page, pageSize int) ([]*ServiceRecord, int64, error)
+	FindByAppointmentID(ctx context.Context, appointmentID string) ([]*ServiceRecord, error)
+	FindLatestByClient(ctx context.Context, clientID string, serviceID *string) (*ServiceRecord, error)
+	// FindLatestByClients finds the latest record of each of the clients that has one
+	FindLatestByClients(ctx context.Context, clientIDs []string) ([]*ServiceRecord, error)
+	Search(ctx context.Context, businessID string, criteria ServiceRecordSearch, page, pageSize int) ([]*ServiceRecord, int64, error)
+}
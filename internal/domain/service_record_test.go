@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newColorTemplate(t *testing.T) *ServiceRecordTemplate {
+	t.Helper()
+
+	template := &ServiceRecordTemplate{BusinessID: "business-1", Name: "Colour formula"}
+	require.NoError(t, template.SetFields([]ServiceRecordField{
+		{Key: "developer_volume", Label: "Developer", Type: ServiceRecordFieldSelect, Required: true, Options: []string{"10", "20", "30"}},
+		{Key: "ratio", Label: "Mix ratio", Type: ServiceRecordFieldText},
+		{Key: "processing", Label: "Processing time", Type: ServiceRecordFieldDuration},
+	}))
+	require.NoError(t, template.Validate())
+	return template
+}
+
+func TestServiceRecordTemplate_Validate(t *testing.T) {
+	template := &ServiceRecordTemplate{BusinessID: "business-1", Name: "Broken"}
+	require.NoError(t, template.SetFields([]ServiceRecordField{
+		{Key: "tone", Label: "Tone", Type: ServiceRecordFieldSelect},
+	}))
+	assert.True(t, errors.Is(template.Validate(), ErrValidation), "select fields need options")
+
+	require.NoError(t, template.SetFields([]ServiceRecordField{
+		{Key: "a", Label: "A", Type: ServiceRecordFieldText},
+		{Key: "a", Label: "B", Type: ServiceRecordFieldText},
+	}))
+	assert.True(t, errors.Is(template.Validate(), ErrValidation), "keys must be unique")
+}
+
+func TestServiceRecord_ValidateAgainstTemplate(t *testing.T) {
+	template := newColorTemplate(t)
+
+	tests := []struct {
+		name    string
+		values  map[string]any
+		isDraft bool
+		wantErr bool
+	}{
+		{name: "valid formula", values: map[string]any{"developer_volume": "20", "ratio": "1:1", "processing": float64(35)}},
+		{name: "missing required field", values: map[string]any{"ratio": "1:1"}, wantErr: true},
+		{name: "draft may omit required field", values: map[string]any{"ratio": "1:1"}, isDraft: true},
+		{name: "unknown option", values: map[string]any{"developer_volume": "40"}, wantErr: true},
+		{name: "unknown field", values: map[string]any{"developer_volume": "20", "toner": "9V"}, wantErr: true},
+		{name: "negative duration", values: map[string]any{"developer_volume": "20", "processing": float64(-5)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := &ServiceRecord{BusinessID: "business-1", ClientID: "client-1", IsDraft: tt.isDraft}
+			require.NoError(t, record.SetFieldValues(tt.values))
+
+			err := record.ValidateAgainstTemplate(template)
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, ErrValidation))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestServiceRecord_CopyForward(t *testing.T) {
+	processing := 35
+	source := &ServiceRecord{
+		BaseModel:      BaseModel{ID: "record-1"},
+		BusinessID:     "business-1",
+		ClientID:       "client-1",
+		ProcessingTime: &processing,
+	}
+	require.NoError(t, source.SetFieldValues(map[string]any{"developer_volume": "20"}))
+	require.NoError(t, source.SetProductsUsed([]ProductUsage{{Name: "Koleston", Shade: strPtr("7/1")}}))
+
+	copied := source.CopyForward("appointment-2")
+
+	assert.True(t, copied.IsDraft)
+	assert.Equal(t, "appointment-2", *copied.AppointmentID)
+	assert.Equal(t, "record-1", *copied.CopiedFromID)
+	assert.Equal(t, *source.FieldValues, *copied.FieldValues)
+	assert.Equal(t, *source.ProductsUsed, *copied.ProductsUsed)
+	assert.Empty(t, copied.ID)
+}
+
+func strPtr(s string) *string {
+	return &s
+}
@@ -3,6 +3,8 @@ package domain
 import (
 	"context"
 	"time"
+
+	"github.com/shopspring/decimal"
 )
 
 // BusinessRole represents the role a user has within a specific business
@@ -18,13 +20,16 @@ const (
 // Staff represents a user's role and permissions within a specific business
 type Staff struct {
 	BaseModel
-	BusinessID   string       `gorm:"not null;type:uuid;index" json:"business_id"`
-	UserID       string       `gorm:"not null;type:uuid;index" json:"user_id"`
-	Role         BusinessRole `gorm:"not null;size:20;check:role IN ('owner','manager','employee','assistant')" json:"role"`
-	IsActive     bool         `gorm:"not null;default:true" json:"is_active"`
-	Permissions  *string      `gorm:"type:jsonb;default:'{}'" json:"permissions,omitempty"` // JSON object with specific permissions
-	StartDate    *time.Time   `gorm:"" json:"start_date,omitempty"`
-	EndDate      *time.Time   `gorm:"" json:"end_date,omitempty"`
+	BusinessID      string       `gorm:"not null;type:uuid;index" json:"business_id"`
+	UserID          string       `gorm:"not null;type:uuid;index" json:"user_id"`
+	Role            BusinessRole `gorm:"not null;size:20;check:role IN ('owner','manager','employee','assistant')" json:"role"`
+	IsActive        bool         `gorm:"not null;default:true" json:"is_active"`
+	Permissions     *string      `gorm:"type:jsonb;default:'{}'" json:"permissions,omitempty"` // JSON object with specific permissions
+	StartDate       *time.Time   `gorm:"" json:"start_date,omitempty"`
+	EndDate         *time.Time   `gorm:"" json:"end_date,omitempty"`
+	ProfileImageURL *string      `gorm:"type:text" json:"profile_image_url,omitempty"` // Set by uploading a staff photo
+	AverageRating   *decimal.Decimal `gorm:"type:decimal(3,2)" json:"average_rating,omitempty"` // Of their published reviews; nil without any
+	ReviewCount     int          `gorm:"not null;default:0" json:"review_count"`                 // Published reviews
 
 	// Relationships
 	Business Business `gorm:"foreignKey:BusinessID;constraint:OnDelete:CASCADE" json:"business"`
@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxPortfolioImages limits the number of images shown on a staff booking page
+const MaxPortfolioImages = 20
+
+// staffPageSlugPattern matches slugs of lowercase words joined by dashes, e.g. rita-sousa
+var staffPageSlugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// StaffBookingPage is a staff member's personal booking page, which they can share so that
+// clients book with them directly
+type StaffBookingPage struct {
+	BaseModel
+	BusinessID    string  `gorm:"not null;type:uuid;index" json:"business_id"`
+	StaffID       string  `gorm:"not null;type:uuid;uniqueIndex" json:"staff_id"`
+	Slug          string  `gorm:"not null;size:100;uniqueIndex" json:"slug"`
+	Bio           *string `gorm:"type:text" json:"bio,omitempty"`
+	PortfolioURLs *string `gorm:"type:jsonb;default:'[]'" json:"portfolio_urls,omitempty"` // JSON array of image URLs
+	ServiceIDs    *string `gorm:"type:jsonb;default:'[]'" json:"service_ids,omitempty"`    // JSON array; all of the business's services when empty
+	IsPublished   bool    `gorm:"not null;default:false" json:"is_published"`
+}
+
+// TableName returns the table name for StaffBookingPage
+func (StaffBookingPage) TableName() string { return "staff_booking_pages" }
+
+// StaffPageSlug derives a page slug from a staff member's name, e.g. "Rita Sousa" gives
+// rita-sousa. Accents are dropped so that the slug is plain ASCII.
+func StaffPageSlug(firstName, lastName string) string {
+	var slug strings.Builder
+	dash := false
+	for _, r := range norm.NFD.String(strings.ToLower(firstName + " " + lastName)) {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			if dash && slug.Len() > 0 {
+				slug.WriteByte('-')
+			}
+			slug.WriteRune(r)
+			dash = false
+		case unicode.Is(unicode.Mn, r):
+			// Accents decomposed from the letter before
+		default:
+			dash = true
+		}
+	}
+	return slug.String()
+}
+
+// GetPortfolioURLs decodes the portfolio image URLs
+func (p *StaffBookingPage) GetPortfolioURLs() ([]string, error) {
+	return decodeIDs(p.PortfolioURLs)
+}
+
+// SetPortfolioURLs encodes the portfolio image URLs
+func (p *StaffBookingPage) SetPortfolioURLs(urls []string) error {
+	encoded, err := encodeIDs(urls)
+	p.PortfolioURLs = encoded
+	return err
+}
+
+// GetServiceIDs decodes the IDs of the services offered on the page
+func (p *StaffBookingPage) GetServiceIDs() ([]string, error) {
+	return decodeIDs(p.ServiceIDs)
+}
+
+// SetServiceIDs encodes the IDs of the services offered on the page
+func (p *StaffBookingPage) SetServiceIDs(ids []string) error {
+	encoded, err := encodeIDs(ids)
+	p.ServiceIDs = encoded
+	return err
+}
+
+// Offers reports whether the service can be booked from the page
+func (p *StaffBookingPage) Offers(serviceID string) bool {
+	ids, err := p.GetServiceIDs()
+	return err == nil && (len(ids) == 0 || slices.Contains(ids, serviceID))
+}
+
+// Validate validates the staff booking page model
+func (p *StaffBookingPage) Validate() error {
+	if p.BusinessID == "" || p.StaffID == "" {
+		return ErrValidation
+	}
+	if len(p.Slug) < 3 || len(p.Slug) > 100 || !staffPageSlugPattern.MatchString(p.Slug) {
+		return fmt.Errorf("%w: the slug must be 3 to 100 lowercase letters, digits and dashes", ErrValidation)
+	}
+
+	urls, err := p.GetPortfolioURLs()
+	if err != nil {
+		return err
+	}
+	if len(urls) > MaxPortfolioImages {
+		return fmt.Errorf("%w: the portfolio can have at most %d images", ErrValidation, MaxPortfolioImages)
+	}
+	for _, raw := range urls {
+		u, err := url.Parse(raw)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("%w: invalid portfolio image URL %q", ErrValidation, raw)
+		}
+	}
+	_, err = p.GetServiceIDs()
+	return err
+}
+
+// StaffBookingPageRepository defines the repository interface for StaffBookingPage
+type StaffBookingPageRepository interface {
+	BaseRepository[StaffBookingPage]
+	FindBySlug(ctx context.Context, slug string) (*StaffBookingPage, error)
+	FindByStaffID(ctx context.Context, staffID string) (*StaffBookingPage, error)
+	SlugExists(ctx context.Context, slug string, excludeID *string) (bool, error)
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaffPageSlug(t *testing.T) {
+	assert.Equal(t, "rita-sousa", StaffPageSlug("Rita", "Sousa"))
+	assert.Equal(t, "jose-avila", StaffPageSlug("José", "Ávila"))
+	assert.Equal(t, "ana-maria-conceicao", StaffPageSlug(" Ana  Maria", "Conceição!"))
+	assert.Empty(t, StaffPageSlug("", ""))
+}
+
+func TestStaffBookingPage_Offers(t *testing.T) {
+	page := &StaffBookingPage{}
+	assert.True(t, page.Offers("service-1"), "a page without services offers all of them")
+
+	require.NoError(t, page.SetServiceIDs([]string{"service-1"}))
+	assert.True(t, page.Offers("service-1"))
+	assert.False(t, page.Offers("service-2"))
+}
+
+func TestStaffBookingPage_Validate(t *testing.T) {
+	page := &StaffBookingPage{BusinessID: "business-1", StaffID: "staff-1", Slug: "rita-sousa"}
+	require.NoError(t, page.SetPortfolioURLs([]string{"https://example.com/nails.jpg"}))
+	assert.NoError(t, page.Validate())
+
+	for _, slug := range []string{"ri", "Rita-Sousa", "rita--sousa", "-rita", strings.Repeat("a", 101)} {
+		invalid := *page
+		invalid.Slug = slug
+		assert.ErrorIs(t, invalid.Validate(), ErrValidation, slug)
+	}
+
+	require.NoError(t, page.SetPortfolioURLs([]string{"javascript:alert(1)"}))
+	assert.ErrorIs(t, page.Validate(), ErrValidation)
+
+	require.NoError(t, page.SetPortfolioURLs(make([]string, MaxPortfolioImages+1)))
+	assert.ErrorIs(t, page.Validate(), ErrValidation)
+}
@@ -0,0 +1,220 @@
+package domain
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// PerformancePeriod is the length of the periods staff performance is aggregated over
+type PerformancePeriod string
+
+const (
+	PerformanceDaily   PerformancePeriod = "daily"
+	PerformanceWeekly  PerformancePeriod = "weekly" // Monday to Sunday
+	PerformanceMonthly PerformancePeriod = "monthly"
+)
+
+// PerformancePeriods are the periods staff performance is aggregated over, shortest first
+var PerformancePeriods = []PerformancePeriod{PerformanceDaily, PerformanceWeekly, PerformanceMonthly}
+
+// IsValid checks if the performance period is valid
+func (p PerformancePeriod) IsValid() bool {
+	return slices.Contains(PerformancePeriods, p)
+}
+
+// Bounds returns the start of the period containing the local date of day and the start of the
+// next period, in the location
+func (p PerformancePeriod) Bounds(day time.Time, location *time.Location) (time.Time, time.Time) {
+	local := day.In(location)
+	switch p {
+	case PerformanceWeekly:
+		start := RotaWeekStart(day, location)
+		return start, start.AddDate(0, 0, 7)
+	case PerformanceMonthly:
+		start := time.Date(local.Year(), local.Month(), 1, 0, 0, 0, 0, location)
+		return start, start.AddDate(0, 1, 0)
+	default:
+		start := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, location)
+		return start, start.AddDate(0, 0, 1)
+	}
+}
+
+const (
+	// JobPerformanceBackfill aggregates the staff performance of a business over a range of
+	// past dates
+	JobPerformanceBackfill = "staff_performance.backfill"
+	// PerformanceRefreshDays is how many days back the scheduled aggregation recomputes, so
+	// appointments completed and rated after the day they took place are counted
+	PerformanceRefreshDays = 7
+	// MaxPerformanceBackfillDays bounds the range of a backfill
+	MaxPerformanceBackfillDays = 731
+)
+
+// PerformanceBackfillPayload is the range of local dates a backfill job aggregates, inclusive
+type PerformanceBackfillPayload struct {
+	StartDate string `json:"start_date"` // YYYY-MM-DD
+	EndDate   string `json:"end_date"`   // YYYY-MM-DD
+}
+
+// StaffPerformance is what a staff member did during a period: their appointments, the revenue
+// they brought in, their clients' ratings and how many of their clients came back. Records are
+// computed from the appointments by the aggregation job, never entered by hand.
+type StaffPerformance struct {
+	BaseModel
+	BusinessID            string            `gorm:"not null;type:uuid;index" json:"business_id"`
+	StaffID               string            `gorm:"not null;type:uuid;index" json:"staff_id"`
+	Period                PerformancePeriod `gorm:"not null;size:20" json:"period"`
+	StartDate             time.Time         `gorm:"not null" json:"start_date"`
+	EndDate               time.Time         `gorm:"not null" json:"end_date"` // The start of the next period
+	TotalAppointments     int               `gorm:"not null;default:0" json:"total_appointments"`
+	CompletedAppointments int               `gorm:"not null;default:0" json:"completed_appointments"`
+	CanceledAppointments  int               `gorm:"not null;default:0" json:"canceled_appointments"`
+	NoShowAppointments    int               `gorm:"not null;default:0" json:"no_show_appointments"`
+	TotalRevenue          decimal.Decimal   `gorm:"type:decimal(10,2);not null;default:0" json:"total_revenue"` // Charged for the completed appointments
+	AverageRating         *decimal.Decimal  `gorm:"type:decimal(3,2)" json:"average_rating,omitempty"`          // Nil when no appointment was rated
+	ClientRetentionRate   *decimal.Decimal  `gorm:"type:decimal(5,2)" json:"client_retention_rate,omitempty"`   // Percentage of the clients served who had been before
+	NewClients            int               `gorm:"not null;default:0" json:"new_clients"`                      // Clients on their first visit to the business
+	ReturnClients         int               `gorm:"not null;default:0" json:"return_clients"`
+}
+
+// TableName returns the table name for StaffPerformance
+func (StaffPerformance) TableName() string { return "staff_performance" }
+
+// Validate validates the staff performance model
+func (p *StaffPerformance) Validate() error {
+	if p.BusinessID == "" || p.StaffID == "" {
+		return ErrValidation
+	}
+	if !p.Period.IsValid() {
+		return fmt.Errorf("%w: invalid performance period %q", ErrValidation, p.Period)
+	}
+	if !p.StartDate.Before(p.EndDate) {
+		return fmt.Errorf("%w: a performance period must end after it starts", ErrValidation)
+	}
+	return nil
+}
+
+// PerformanceAppointment is an appointment as counted towards the performance of its staff member
+type PerformanceAppointment struct {
+	StaffID    string
+	ClientID   string
+	Status     AppointmentStatus
+	StartTime  time.Time
+	Revenue    decimal.Decimal // Charged on completion
+	Rating     *int            // The client's rating, when given
+	FirstVisit *time.Time      // The start of the client's first completed appointment at the business
+}
+
+// performanceKey identifies the record of a staff member for a period
+type performanceKey struct {
+	staffID string
+	start   time.Time
+}
+
+// performanceTally accumulates the appointments of a record
+type performanceTally struct {
+	record      *StaffPerformance
+	ratings     int
+	ratingTotal int
+	clients     map[string]bool // Whether each client served was new
+}
+
+// AggregatePerformance computes the performance of each staff member during every period of the
+// kind in which they had appointments, ordered by period and staff ID. Periods follow the local
+// dates of the location. Rescheduled appointments were replaced by others and are not counted.
+func AggregatePerformance(businessID string, period PerformancePeriod, location *time.Location, appointments []*PerformanceAppointment) []*StaffPerformance {
+	tallies := map[performanceKey]*performanceTally{}
+	for _, appointment := range appointments {
+		if appointment.Status == AppointmentStatusRescheduled {
+			continue
+		}
+		start, end := period.Bounds(appointment.StartTime, location)
+		key := performanceKey{staffID: appointment.StaffID, start: start}
+		tally, ok := tallies[key]
+		if !ok {
+			tally = &performanceTally{
+				record: &StaffPerformance{
+					BusinessID: businessID, StaffID: appointment.StaffID, Period: period, StartDate: start, EndDate: end,
+				},
+				clients: map[string]bool{},
+			}
+			tallies[key] = tally
+		}
+		tally.add(appointment)
+	}
+
+	records := make([]*StaffPerformance, 0, len(tallies))
+	for _, tally := range tallies {
+		records = append(records, tally.finish())
+	}
+	slices.SortFunc(records, func(a, b *StaffPerformance) int {
+		return cmp.Or(a.StartDate.Compare(b.StartDate), cmp.Compare(a.StaffID, b.StaffID))
+	})
+	return records
+}
+
+// add counts an appointment of the period
+func (t *performanceTally) add(appointment *PerformanceAppointment) {
+	record := t.record
+	record.TotalAppointments++
+	switch appointment.Status {
+	case AppointmentStatusCompleted:
+		record.CompletedAppointments++
+		record.TotalRevenue = record.TotalRevenue.Add(appointment.Revenue)
+		// A client is new in the period of their first visit, however often they come back in it
+		isNew := appointment.FirstVisit == nil ||
+			(!appointment.FirstVisit.Before(record.StartDate) && appointment.FirstVisit.Before(record.EndDate))
+		t.clients[appointment.ClientID] = t.clients[appointment.ClientID] || isNew
+	case AppointmentStatusCancelled:
+		record.CanceledAppointments++
+	case AppointmentStatusNoShow:
+		record.NoShowAppointments++
+	}
+	if appointment.Rating != nil {
+		t.ratings++
+		t.ratingTotal += *appointment.Rating
+	}
+}
+
+// finish works out the averages and rates of the record
+func (t *performanceTally) finish() *StaffPerformance {
+	record := t.record
+	if t.ratings > 0 {
+		average := decimal.NewFromInt(int64(t.ratingTotal)).Div(decimal.NewFromInt(int64(t.ratings))).Round(2)
+		record.AverageRating = &average
+	}
+	for _, isNew := range t.clients {
+		if isNew {
+			record.NewClients++
+		} else {
+			record.ReturnClients++
+		}
+	}
+	if served := len(t.clients); served > 0 {
+		rate := decimal.NewFromInt(int64(record.ReturnClients * 100)).Div(decimal.NewFromInt(int64(served))).Round(2)
+		record.ClientRetentionRate = &rate
+	}
+	return record
+}
+
+// StaffPerformanceRepository defines the repository interface for StaffPerformance
+type StaffPerformanceRepository interface {
+	BaseRepository[StaffPerformance]
+	// FindBusinessesWithAppointments finds the businesses with appointments starting in the range
+	FindBusinessesWithAppointments(ctx context.Context, start, end time.Time) ([]string, error)
+	// FindPerformanceAppointments finds the appointments of a business starting in the range,
+	// with what was charged for them, their rating and the first visit of their client
+	FindPerformanceAppointments(ctx context.Context, businessID string, start, end time.Time) ([]*PerformanceAppointment, error)
+	// ReplacePeriod stores the records of a period of a business, updating those of the same
+	// staff member and period start and removing those of staff members left out, so
+	// aggregating a period again leaves the same records
+	ReplacePeriod(ctx context.Context, businessID string, period PerformancePeriod, start time.Time, records []*StaffPerformance) error
+	// FindInRange finds the records of a business for periods of the kind starting in the
+	// range, of one staff member when staffID is set, ordered by period start and staff ID
+	FindInRange(ctx context.Context, businessID string, period PerformancePeriod, staffID *string, start, end time.Time) ([]*StaffPerformance, error)
+}
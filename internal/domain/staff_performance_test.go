@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPerformancePeriodBounds(t *testing.T) {
+	lisbon, err := time.LoadLocation("Europe/Lisbon")
+	require.NoError(t, err)
+	// 2024-03-31 is a Sunday, when Lisbon moves to summer time
+	day := time.Date(2024, 3, 31, 0, 30, 0, 0, time.UTC)
+
+	start, end := PerformanceDaily.Bounds(day, lisbon)
+	assert.Equal(t, time.Date(2024, 3, 31, 0, 0, 0, 0, lisbon), start)
+	assert.Equal(t, 23*time.Hour, end.Sub(start))
+
+	start, end = PerformanceWeekly.Bounds(day, lisbon)
+	assert.Equal(t, time.Date(2024, 3, 25, 0, 0, 0, 0, lisbon), start)
+	assert.Equal(t, time.Date(2024, 4, 1, 0, 0, 0, 0, lisbon), end)
+
+	start, end = PerformanceMonthly.Bounds(day, lisbon)
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, lisbon), start)
+	assert.Equal(t, time.Date(2024, 4, 1, 0, 0, 0, 0, lisbon), end)
+}
+
+func TestAggregatePerformance(t *testing.T) {
+	at := func(day, hour int) time.Time { return time.Date(2024, 6, day, hour, 0, 0, 0, time.UTC) }
+	rating := func(r int) *int { return &r }
+	visit := func(t time.Time) *time.Time { return &t }
+	appointments := []*PerformanceAppointment{
+		// Monday: a new client, a returning one and a no-show
+		{StaffID: "ana", ClientID: "new", Status: AppointmentStatusCompleted, StartTime: at(3, 9), Revenue: decimal.NewFromInt(40), Rating: rating(5), FirstVisit: visit(at(3, 9))},
+		{StaffID: "ana", ClientID: "regular", Status: AppointmentStatusCompleted, StartTime: at(3, 11), Revenue: decimal.NewFromInt(25), Rating: rating(4), FirstVisit: visit(at(1, 10))},
+		{StaffID: "ana", ClientID: "absent", Status: AppointmentStatusNoShow, StartTime: at(3, 14)},
+		// Tuesday: the new client comes back, and a rescheduled appointment is left out
+		{StaffID: "ana", ClientID: "new", Status: AppointmentStatusCompleted, StartTime: at(4, 9), Revenue: decimal.NewFromInt(30), FirstVisit: visit(at(3, 9))},
+		{StaffID: "ana", ClientID: "regular", Status: AppointmentStatusRescheduled, StartTime: at(4, 15)},
+		{StaffID: "rui", ClientID: "regular", Status: AppointmentStatusCancelled, StartTime: at(4, 10)},
+	}
+
+	daily := AggregatePerformance("business", PerformanceDaily, time.UTC, appointments)
+	require.Len(t, daily, 3)
+	monday := daily[0]
+	assert.Equal(t, "ana", monday.StaffID)
+	assert.Equal(t, at(3, 0), monday.StartDate)
+	assert.Equal(t, at(4, 0), monday.EndDate)
+	assert.Equal(t, 3, monday.TotalAppointments)
+	assert.Equal(t, 2, monday.CompletedAppointments)
+	assert.Equal(t, 1, monday.NoShowAppointments)
+	assert.True(t, decimal.NewFromInt(65).Equal(monday.TotalRevenue))
+	assert.Equal(t, "4.5", monday.AverageRating.String())
+	assert.Equal(t, 1, monday.NewClients)
+	assert.Equal(t, 1, monday.ReturnClients)
+	assert.Equal(t, "50", monday.ClientRetentionRate.String())
+
+	tuesday := daily[1]
+	assert.Equal(t, 1, tuesday.TotalAppointments, "rescheduled appointments are not counted")
+	assert.Nil(t, tuesday.AverageRating)
+	assert.Equal(t, 0, tuesday.NewClients, "clients are new only in the period of their first visit")
+	assert.Equal(t, 1, tuesday.ReturnClients)
+
+	rui := daily[2]
+	assert.Equal(t, "rui", rui.StaffID)
+	assert.Equal(t, 1, rui.CanceledAppointments)
+	assert.Nil(t, rui.ClientRetentionRate, "no clients were served")
+
+	weekly := AggregatePerformance("business", PerformanceWeekly, time.UTC, appointments)
+	require.Len(t, weekly, 2)
+	week := weekly[0]
+	assert.Equal(t, at(3, 0), week.StartDate)
+	assert.Equal(t, 4, week.TotalAppointments)
+	assert.True(t, decimal.NewFromInt(95).Equal(week.TotalRevenue))
+	assert.Equal(t, 1, week.NewClients, "a new client coming back in the period is still new")
+	assert.Equal(t, 1, week.ReturnClients)
+	for _, record := range weekly {
+		assert.NoError(t, record.Validate())
+	}
+}
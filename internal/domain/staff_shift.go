@@ -0,0 +1,246 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// StaffShiftKind is how a shift repeats
+type StaffShiftKind string
+
+const (
+	StaffShiftRecurring StaffShiftKind = "recurring" // Every week on a weekday: the staff member's usual pattern
+	StaffShiftOneOff    StaffShiftKind = "one_off"   // On a single date, on top of the weekly pattern
+)
+
+// ConflictOutsideShift is the conflict of a booking with the shifts of a staff member on a rota
+const ConflictOutsideShift ConflictReason = "outside_shift"
+
+// RotaDays is the number of days of a rota, Monday to Sunday
+const RotaDays = 7
+
+// StaffShift is a period a staff member works. Staff members with shifts are on a rota and can
+// only be booked during them; those without work whenever the business is open. Either way the
+// business must be open, and time off recorded as an availability exception still blocks
+// bookings within a shift.
+type StaffShift struct {
+	BaseModel
+	BusinessID     string         `gorm:"not null;type:uuid;index" json:"business_id"`
+	StaffID        string         `gorm:"not null;type:uuid;index" json:"staff_id"`
+	Kind           StaffShiftKind `gorm:"not null;size:20" json:"kind"`
+	Weekday        *int           `gorm:"type:smallint" json:"weekday,omitempty"`     // Recurring shifts: 0 for Sunday to 6 for Saturday
+	ShiftDate      *time.Time     `gorm:"type:date" json:"shift_date,omitempty"`      // One-off shifts
+	StartTime      string         `gorm:"not null;size:5" json:"start_time"`          // HH:MM, in the business's time zone
+	EndTime        string         `gorm:"not null;size:5" json:"end_time"`            // HH:MM after the start; 24:00 for midnight
+	EffectiveFrom  *time.Time     `gorm:"type:date" json:"effective_from,omitempty"`  // Recurring shifts: the first date; no limit when nil
+	EffectiveUntil *time.Time     `gorm:"type:date" json:"effective_until,omitempty"` // Recurring shifts: the last date; no limit when nil
+	Notes          *string        `gorm:"type:text" json:"notes,omitempty"`
+}
+
+// TableName returns the table name for StaffShift
+func (StaffShift) TableName() string { return "staff_shifts" }
+
+// Validate validates the staff shift model
+func (s *StaffShift) Validate() error {
+	if s.BusinessID == "" || s.StaffID == "" {
+		return ErrValidation
+	}
+	switch s.Kind {
+	case StaffShiftRecurring:
+		if s.Weekday == nil || *s.Weekday < int(time.Sunday) || *s.Weekday > int(time.Saturday) {
+			return fmt.Errorf("%w: a recurring shift needs a weekday from 0 (Sunday) to 6 (Saturday)", ErrValidation)
+		}
+		if s.ShiftDate != nil {
+			return fmt.Errorf("%w: a recurring shift has no date; give its weekday", ErrValidation)
+		}
+		if s.EffectiveFrom != nil && s.EffectiveUntil != nil && s.EffectiveUntil.Before(*s.EffectiveFrom) {
+			return fmt.Errorf("%w: effective_until must not be before effective_from", ErrValidation)
+		}
+	case StaffShiftOneOff:
+		if s.ShiftDate == nil {
+			return fmt.Errorf("%w: a one-off shift needs a date", ErrValidation)
+		}
+		if s.Weekday != nil || s.EffectiveFrom != nil || s.EffectiveUntil != nil {
+			return fmt.Errorf("%w: a one-off shift has no weekday or effective dates", ErrValidation)
+		}
+	default:
+		return fmt.Errorf("%w: unknown shift kind %q", ErrValidation, s.Kind)
+	}
+
+	day := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	start, err := clockOn(day, s.StartTime)
+	if err != nil || s.StartTime == "24:00" {
+		return fmt.Errorf("%w: start_time must be formatted as HH:MM", ErrValidation)
+	}
+	end, err := clockOn(day, s.EndTime)
+	if err != nil {
+		return fmt.Errorf("%w: end_time must be formatted as HH:MM", ErrValidation)
+	}
+	if s.EndTime == "24:00" {
+		end = end.AddDate(0, 0, 1)
+	}
+	if !end.After(start) {
+		return fmt.Errorf("%w: a shift must end after it starts, on the same day", ErrValidation)
+	}
+	return nil
+}
+
+// OccursOn reports whether the shift is worked on the local date of day
+func (s *StaffShift) OccursOn(day time.Time) bool {
+	date := day.Format(time.DateOnly)
+	switch s.Kind {
+	case StaffShiftOneOff:
+		return s.ShiftDate != nil && s.ShiftDate.Format(time.DateOnly) == date
+	case StaffShiftRecurring:
+		if s.Weekday == nil || *s.Weekday != int(day.Weekday()) {
+			return false
+		}
+		if s.EffectiveFrom != nil && date < s.EffectiveFrom.Format(time.DateOnly) {
+			return false
+		}
+		return s.EffectiveUntil == nil || date <= s.EffectiveUntil.Format(time.DateOnly)
+	}
+	return false
+}
+
+// On returns when the shift starts and ends on the local date of day, in day's location
+func (s *StaffShift) On(day time.Time) (time.Time, time.Time, error) {
+	start, err := clockOn(day, s.StartTime)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: invalid shift start %q", ErrValidation, s.StartTime)
+	}
+	end, err := clockOn(day, s.EndTime)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: invalid shift end %q", ErrValidation, s.EndTime)
+	}
+	if !end.After(start) {
+		// Ends at midnight
+		end = end.AddDate(0, 0, 1)
+	}
+	return start, end, nil
+}
+
+// WorkingIntervals returns when the shifts are worked on the local date of day, in order, with
+// overlapping and back-to-back shifts joined
+func WorkingIntervals(shifts []*StaffShift, day time.Time) []DateRange {
+	var intervals []DateRange
+	for _, shift := range shifts {
+		if !shift.OccursOn(day) {
+			continue
+		}
+		if start, end, err := shift.On(day); err == nil {
+			intervals = append(intervals, DateRange{Start: start, End: end})
+		}
+	}
+	slices.SortFunc(intervals, func(a, b DateRange) int { return a.Start.Compare(b.Start) })
+
+	var joined []DateRange
+	for _, interval := range intervals {
+		if last := len(joined) - 1; last >= 0 && !interval.Start.After(joined[last].End) {
+			if interval.End.After(joined[last].End) {
+				joined[last].End = interval.End
+			}
+			continue
+		}
+		joined = append(joined, interval)
+	}
+	return joined
+}
+
+// CheckShifts returns the conflict of a requested time slot with the staff member's shifts,
+// nil when it falls within one or the staff member is not on a rota
+func (r AvailabilityRules) CheckShifts(request AvailabilityRequest, shifts []*StaffShift) *AvailabilityConflict {
+	if len(shifts) == 0 {
+		return nil
+	}
+	for _, interval := range WorkingIntervals(shifts, request.Start.In(r.Location)) {
+		if !request.Start.Before(interval.Start) && !request.End.After(interval.End) {
+			return nil
+		}
+	}
+	return &AvailabilityConflict{
+		Reason:  ConflictOutsideShift,
+		Message: "the staff member is not on shift at the requested time",
+	}
+}
+
+// RotaWeekStart returns the Monday starting the week of the local date of day
+func RotaWeekStart(day time.Time, location *time.Location) time.Time {
+	local := day.In(location)
+	offset := (int(local.Weekday()) + 6) % 7
+	return time.Date(local.Year(), local.Month(), local.Day()-offset, 0, 0, 0, 0, location)
+}
+
+// RotaShift is a shift worked on a day of a rota
+type RotaShift struct {
+	ShiftID string
+	Kind    StaffShiftKind
+	Date    string // YYYY-MM-DD
+	Start   time.Time
+	End     time.Time
+	Notes   *string
+}
+
+// StaffRota is the week of a staff member
+type StaffRota struct {
+	StaffID string
+	OnRota  bool // Whether the staff member has shifts at all; those who do not work whenever the business is open
+	Shifts  []*RotaShift
+	Minutes int // The time on shift during the week
+}
+
+// Rota is the week of shifts of the staff of a business, as rendered by the calendar
+type Rota struct {
+	WeekStart time.Time // Monday, in the business's time zone
+	Staff     []*StaffRota
+}
+
+// NewRota lays out the shifts of each staff member during the week starting on the Monday of
+// weekStart, in the order of the staff IDs. Shifts of staff members not listed are left out.
+func NewRota(weekStart time.Time, location *time.Location, staffIDs []string, shifts []*StaffShift) *Rota {
+	rota := &Rota{WeekStart: RotaWeekStart(weekStart, location)}
+	byStaff := map[string][]*StaffShift{}
+	for _, shift := range shifts {
+		byStaff[shift.StaffID] = append(byStaff[shift.StaffID], shift)
+	}
+
+	for _, staffID := range staffIDs {
+		week := &StaffRota{StaffID: staffID, OnRota: len(byStaff[staffID]) > 0, Shifts: []*RotaShift{}}
+		for i := range RotaDays {
+			day := time.Date(rota.WeekStart.Year(), rota.WeekStart.Month(), rota.WeekStart.Day()+i, 0, 0, 0, 0, location)
+			var worked []*RotaShift
+			for _, shift := range byStaff[staffID] {
+				if !shift.OccursOn(day) {
+					continue
+				}
+				start, end, err := shift.On(day)
+				if err != nil {
+					continue
+				}
+				worked = append(worked, &RotaShift{
+					ShiftID: shift.ID,
+					Kind:    shift.Kind,
+					Date:    day.Format(time.DateOnly),
+					Start:   start,
+					End:     end,
+					Notes:   shift.Notes,
+				})
+			}
+			slices.SortFunc(worked, func(a, b *RotaShift) int { return a.Start.Compare(b.Start) })
+			week.Shifts = append(week.Shifts, worked...)
+			for _, interval := range WorkingIntervals(byStaff[staffID], day) {
+				week.Minutes += int(interval.End.Sub(interval.Start).Minutes())
+			}
+		}
+		rota.Staff = append(rota.Staff, week)
+	}
+	return rota
+}
+
+// StaffShiftRepository defines the repository interface for StaffShift
+type StaffShiftRepository interface {
+	BaseRepository[StaffShift]
+	FindByStaffIDs(ctx context.Context, staffIDs []string) ([]*StaffShift, error)
+}
@@ -0,0 +1,177 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func weeklyShift(id string, weekday time.Weekday, start, end string) *StaffShift {
+	day := int(weekday)
+	return &StaffShift{
+		BaseModel:  BaseModel{ID: id},
+		BusinessID: "business",
+		StaffID:    "staff",
+		Kind:       StaffShiftRecurring,
+		Weekday:    &day,
+		StartTime:  start,
+		EndTime:    end,
+	}
+}
+
+func oneOffShift(id string, date time.Time, start, end string) *StaffShift {
+	return &StaffShift{
+		BaseModel:  BaseModel{ID: id},
+		BusinessID: "business",
+		StaffID:    "staff",
+		Kind:       StaffShiftOneOff,
+		ShiftDate:  &date,
+		StartTime:  start,
+		EndTime:    end,
+	}
+}
+
+func TestStaffShiftValidate(t *testing.T) {
+	monday := time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC)
+	assert.NoError(t, weeklyShift("s", time.Monday, "09:00", "17:00").Validate())
+	assert.NoError(t, weeklyShift("s", time.Saturday, "20:00", "24:00").Validate(), "a shift may end at midnight")
+	assert.NoError(t, oneOffShift("s", monday, "09:00", "13:00").Validate())
+
+	invalid := map[string]*StaffShift{
+		"no weekday":           {BusinessID: "b", StaffID: "s", Kind: StaffShiftRecurring, StartTime: "09:00", EndTime: "17:00"},
+		"no date":              {BusinessID: "b", StaffID: "s", Kind: StaffShiftOneOff, StartTime: "09:00", EndTime: "17:00"},
+		"unknown kind":         {BusinessID: "b", StaffID: "s", Kind: "fortnightly", StartTime: "09:00", EndTime: "17:00"},
+		"ends before starting": weeklyShift("s", time.Monday, "17:00", "09:00"),
+		"starts at midnight":   weeklyShift("s", time.Monday, "24:00", "24:00"),
+		"malformed time":       weeklyShift("s", time.Monday, "9am", "17:00"),
+	}
+	until := monday.AddDate(0, 0, -1)
+	backwards := weeklyShift("s", time.Monday, "09:00", "17:00")
+	backwards.EffectiveFrom, backwards.EffectiveUntil = &monday, &until
+	invalid["effective dates backwards"] = backwards
+	dated := oneOffShift("s", monday, "09:00", "17:00")
+	dated.EffectiveFrom = &monday
+	invalid["one-off with effective dates"] = dated
+
+	for name, shift := range invalid {
+		assert.True(t, errors.Is(shift.Validate(), ErrValidation), name)
+	}
+}
+
+func TestStaffShiftOccursOn(t *testing.T) {
+	lisbon, err := time.LoadLocation("Europe/Lisbon")
+	require.NoError(t, err)
+	// 2024-06-03 is a Monday
+	monday := time.Date(2024, 6, 3, 0, 0, 0, 0, lisbon)
+
+	shift := weeklyShift("s", time.Monday, "09:00", "17:00")
+	assert.True(t, shift.OccursOn(monday))
+	assert.True(t, shift.OccursOn(monday.AddDate(0, 0, 7)))
+	assert.False(t, shift.OccursOn(monday.AddDate(0, 0, 1)))
+
+	// Effective dates are stored as UTC midnight and bound the local dates, inclusive
+	from := time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 6, 17, 0, 0, 0, 0, time.UTC)
+	shift.EffectiveFrom, shift.EffectiveUntil = &from, &until
+	assert.False(t, shift.OccursOn(monday))
+	assert.True(t, shift.OccursOn(monday.AddDate(0, 0, 7)))
+	assert.True(t, shift.OccursOn(monday.AddDate(0, 0, 14)))
+	assert.False(t, shift.OccursOn(monday.AddDate(0, 0, 21)))
+
+	oneOff := oneOffShift("o", time.Date(2024, 6, 4, 0, 0, 0, 0, time.UTC), "10:00", "12:00")
+	assert.True(t, oneOff.OccursOn(monday.AddDate(0, 0, 1)))
+	assert.False(t, oneOff.OccursOn(monday))
+}
+
+func TestWorkingIntervals(t *testing.T) {
+	monday := time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC)
+	at := func(hour int) time.Time { return monday.Add(time.Duration(hour) * time.Hour) }
+	shifts := []*StaffShift{
+		weeklyShift("afternoon", time.Monday, "14:00", "18:00"),
+		weeklyShift("morning", time.Monday, "09:00", "12:00"),
+		oneOffShift("extra", monday, "12:00", "13:00"),
+		weeklyShift("late", time.Monday, "20:00", "24:00"),
+		weeklyShift("tuesday", time.Tuesday, "09:00", "17:00"),
+	}
+
+	assert.Equal(t, []DateRange{
+		{Start: at(9), End: at(13)},
+		{Start: at(14), End: at(18)},
+		{Start: at(20), End: at(24)},
+	}, WorkingIntervals(shifts, monday), "back-to-back shifts are joined")
+	assert.Empty(t, WorkingIntervals(shifts, monday.AddDate(0, 0, 2)))
+}
+
+func TestAvailabilityRulesCheckShifts(t *testing.T) {
+	rules := NewAvailabilityRules(&Business{TimeZone: "UTC"}, nil)
+	monday := time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC)
+	request := func(from, to time.Duration) AvailabilityRequest {
+		return AvailabilityRequest{StaffID: "staff", Start: monday.Add(from), End: monday.Add(to)}
+	}
+	shifts := []*StaffShift{
+		weeklyShift("morning", time.Monday, "09:00", "12:00"),
+		weeklyShift("noon", time.Monday, "12:00", "14:00"),
+	}
+
+	assert.Nil(t, rules.CheckShifts(request(10*time.Hour, 11*time.Hour), nil), "staff not on a rota work any time")
+	assert.Nil(t, rules.CheckShifts(request(11*time.Hour, 13*time.Hour), shifts), "spanning back-to-back shifts")
+
+	conflict := rules.CheckShifts(request(13*time.Hour+30*time.Minute, 14*time.Hour+30*time.Minute), shifts)
+	require.NotNil(t, conflict)
+	assert.Equal(t, ConflictOutsideShift, conflict.Reason)
+	assert.NotNil(t, rules.CheckShifts(request(34*time.Hour, 35*time.Hour), shifts), "no shift on tuesday")
+}
+
+func TestAvailabilityRulesFreeSlotsWithinShifts(t *testing.T) {
+	settings := &BusinessSettings{CalendarStartHour: 9, CalendarEndHour: 18}
+	rules := NewAvailabilityRules(&Business{TimeZone: "UTC"}, settings)
+	monday := time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) time.Time { return monday.Add(d) }
+
+	schedule := &StaffSchedule{
+		StaffID: "staff",
+		Shifts:  []*StaffShift{weeklyShift("late", time.Monday, "15:00", "20:00")},
+	}
+	search := SlotSearch{Start: monday, End: monday.AddDate(0, 0, 2), Duration: time.Hour, Interval: time.Hour}
+
+	// The shift runs past closing, and there is none on tuesday
+	assert.Equal(t, []time.Time{at(15 * time.Hour), at(16 * time.Hour), at(17 * time.Hour)}, rules.FreeSlots(schedule, search))
+}
+
+func TestNewRota(t *testing.T) {
+	lisbon, err := time.LoadLocation("Europe/Lisbon")
+	require.NoError(t, err)
+	wednesday := time.Date(2024, 6, 5, 0, 0, 0, 0, lisbon)
+	notes := "cover for Ana"
+	extra := oneOffShift("extra", time.Date(2024, 6, 8, 0, 0, 0, 0, time.UTC), "10:00", "14:00")
+	extra.Notes = &notes
+	shifts := []*StaffShift{
+		weeklyShift("monday", time.Monday, "09:00", "17:00"),
+		weeklyShift("sunday", time.Sunday, "09:00", "13:00"),
+		weeklyShift("overlap", time.Monday, "16:00", "18:00"),
+		extra,
+		{BaseModel: BaseModel{ID: "other"}, StaffID: "unlisted", Kind: StaffShiftRecurring},
+	}
+
+	rota := NewRota(wednesday, lisbon, []string{"staff", "unrostered"}, shifts)
+	assert.Equal(t, time.Date(2024, 6, 3, 0, 0, 0, 0, lisbon), rota.WeekStart, "weeks start on monday")
+	require.Len(t, rota.Staff, 2)
+
+	week := rota.Staff[0]
+	assert.True(t, week.OnRota)
+	require.Len(t, week.Shifts, 4)
+	assert.Equal(t, []string{"monday", "overlap", "extra", "sunday"},
+		[]string{week.Shifts[0].ShiftID, week.Shifts[1].ShiftID, week.Shifts[2].ShiftID, week.Shifts[3].ShiftID})
+	assert.Equal(t, "2024-06-08", week.Shifts[2].Date)
+	assert.Equal(t, &notes, week.Shifts[2].Notes)
+	assert.Equal(t, time.Date(2024, 6, 9, 9, 0, 0, 0, lisbon), week.Shifts[3].Start)
+	// Monday 09:00-18:00 with the overlap counted once, plus 4 hours on saturday and sunday
+	assert.Equal(t, (9+4+4)*60, week.Minutes)
+
+	assert.False(t, rota.Staff[1].OnRota)
+	assert.Empty(t, rota.Staff[1].Shifts)
+	assert.Zero(t, rota.Staff[1].Minutes)
+}
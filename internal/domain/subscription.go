@@ -0,0 +1,274 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// SubscriptionTier is the plan a business pays for, which decides how much of the app it can use
+type SubscriptionTier string
+
+const (
+	TierFree    SubscriptionTier = "free"
+	TierPro     SubscriptionTier = "pro"
+	TierPremium SubscriptionTier = "premium"
+)
+
+// SubscriptionTiers lists the tiers from the cheapest up
+var SubscriptionTiers = []SubscriptionTier{TierFree, TierPro, TierPremium}
+
+// IsValid checks if the subscription tier is valid
+func (t SubscriptionTier) IsValid() bool {
+	return slices.Contains(SubscriptionTiers, t)
+}
+
+// IsPaid reports whether the tier is billed
+func (t SubscriptionTier) IsPaid() bool {
+	return t.IsValid() && t != TierFree
+}
+
+// Entitlement is something a subscription plan allows a business a limited amount of
+type Entitlement string
+
+const (
+	EntitlementStaff      Entitlement = "staff"       // Active staff members, the owner included
+	EntitlementLocations  Entitlement = "locations"   // Active locations
+	EntitlementCampaigns  Entitlement = "campaigns"   // Active campaigns that have not ended
+	EntitlementSMSCredits Entitlement = "sms_credits" // Text messages sent in a calendar month, in UTC
+)
+
+// Entitlements lists every entitlement, in the order they are reported
+var Entitlements = []Entitlement{EntitlementStaff, EntitlementLocations, EntitlementCampaigns, EntitlementSMSCredits}
+
+// Unlimited is the limit of an entitlement a plan does not limit
+const Unlimited = -1
+
+// SubscriptionPlan is what a subscription tier allows a business
+type SubscriptionPlan struct {
+	Tier   SubscriptionTier
+	Limits map[Entitlement]int
+}
+
+// subscriptionPlans holds the plan of each tier
+var subscriptionPlans = map[SubscriptionTier]SubscriptionPlan{
+	TierFree: {Tier: TierFree, Limits: map[Entitlement]int{
+		EntitlementStaff:      1,
+		EntitlementLocations:  1,
+		EntitlementCampaigns:  0,
+		EntitlementSMSCredits: 25,
+	}},
+	TierPro: {Tier: TierPro, Limits: map[Entitlement]int{
+		EntitlementStaff:      5,
+		EntitlementLocations:  2,
+		EntitlementCampaigns:  5,
+		EntitlementSMSCredits: 500,
+	}},
+	TierPremium: {Tier: TierPremium, Limits: map[Entitlement]int{
+		EntitlementStaff:      Unlimited,
+		EntitlementLocations:  Unlimited,
+		EntitlementCampaigns:  Unlimited,
+		EntitlementSMSCredits: 2000,
+	}},
+}
+
+// PlanFor returns the plan of a tier. Tiers no longer sold, and businesses without a tier, get
+// the free plan.
+func PlanFor(tier SubscriptionTier) SubscriptionPlan {
+	if plan, ok := subscriptionPlans[tier]; ok {
+		return plan
+	}
+	return subscriptionPlans[TierFree]
+}
+
+// Limit returns how much of an entitlement the plan allows, or Unlimited
+func (p SubscriptionPlan) Limit(entitlement Entitlement) int {
+	return p.Limits[entitlement]
+}
+
+// Allows reports whether a business using some of an entitlement can add more under the plan
+func (p SubscriptionPlan) Allows(entitlement Entitlement, used, adding int) bool {
+	limit := p.Limit(entitlement)
+	return limit == Unlimited || used+adding <= limit
+}
+
+// UpgradeFor returns the cheapest tier allowing a business using some of an entitlement to add
+// more, or an empty tier when none does
+func UpgradeFor(entitlement Entitlement, used, adding int) SubscriptionTier {
+	for _, tier := range SubscriptionTiers {
+		if PlanFor(tier).Allows(entitlement, used, adding) {
+			return tier
+		}
+	}
+	return ""
+}
+
+// UpgradeRequiredError is returned when a business has used up an entitlement of its plan.
+// Clients offer the upgrade to RequiredTier.
+type UpgradeRequiredError struct {
+	Entitlement  Entitlement
+	Tier         SubscriptionTier
+	Limit        int
+	RequiredTier SubscriptionTier // The cheapest tier allowing it; empty when no tier does
+}
+
+// Error describes the limit reached
+func (e *UpgradeRequiredError) Error() string {
+	message := fmt.Sprintf("the %s plan allows %d %s", e.Tier, e.Limit, strings.ReplaceAll(string(e.Entitlement), "_", " "))
+	if e.RequiredTier == "" {
+		return message
+	}
+	return fmt.Sprintf("%s; upgrade to %s for more", message, e.RequiredTier)
+}
+
+// Tier returns the subscription tier of the business, which is kept in step with its
+// subscription's status. Unknown tiers are taken as the free tier.
+func (b Business) Tier() SubscriptionTier {
+	return PlanFor(SubscriptionTier(b.SubscriptionTier)).Tier
+}
+
+// CheckEntitlement checks that the business, using some of an entitlement, can add more under
+// its plan, returning an UpgradeRequiredError otherwise
+func (b Business) CheckEntitlement(entitlement Entitlement, used, adding int) error {
+	plan := PlanFor(b.Tier())
+	if plan.Allows(entitlement, used, adding) {
+		return nil
+	}
+	return &UpgradeRequiredError{
+		Entitlement:  entitlement,
+		Tier:         plan.Tier,
+		Limit:        plan.Limit(entitlement),
+		RequiredTier: UpgradeFor(entitlement, used, adding),
+	}
+}
+
+// CheckDowngrade checks that a business using the given amounts of its entitlements fits in
+// the plan of a lower tier. SMS credits are left out, as they start again every month.
+func CheckDowngrade(tier SubscriptionTier, usage map[Entitlement]int) error {
+	plan := PlanFor(tier)
+	var over []string
+	for _, entitlement := range Entitlements {
+		if entitlement == EntitlementSMSCredits {
+			continue
+		}
+		if !plan.Allows(entitlement, usage[entitlement], 0) {
+			over = append(over, fmt.Sprintf("%d %s of the %d allowed", usage[entitlement], entitlement, plan.Limit(entitlement)))
+		}
+	}
+	if len(over) > 0 {
+		return fmt.Errorf("%w: the business uses more than the %s plan allows: %s", ErrValidation, tier, strings.Join(over, ", "))
+	}
+	return nil
+}
+
+// SubscriptionStatus is the billing status of a business's subscription, as Stripe reports it
+type SubscriptionStatus string
+
+const (
+	SubscriptionIncomplete        SubscriptionStatus = "incomplete" // The first payment has not gone through yet
+	SubscriptionIncompleteExpired SubscriptionStatus = "incomplete_expired"
+	SubscriptionTrialing          SubscriptionStatus = "trialing"
+	SubscriptionActive            SubscriptionStatus = "active"
+	SubscriptionPastDue           SubscriptionStatus = "past_due" // A payment failed and Stripe is retrying it
+	SubscriptionUnpaid            SubscriptionStatus = "unpaid"   // Stripe gave up retrying
+	SubscriptionCanceled          SubscriptionStatus = "canceled"
+	SubscriptionPaused            SubscriptionStatus = "paused"
+)
+
+// GrantsTier reports whether a subscription in the status gives the business its tier. Past
+// due subscriptions keep it while Stripe retries the payment.
+func (s SubscriptionStatus) GrantsTier() bool {
+	return s == SubscriptionTrialing || s == SubscriptionActive || s == SubscriptionPastDue
+}
+
+// BusinessSubscription is a business's paid subscription, billed through Stripe. Stripe owns
+// the billing; the subscription mirrors it from Stripe's webhooks, and the business's tier is
+// kept in step with it.
+type BusinessSubscription struct {
+	BaseModel
+	BusinessID           string             `gorm:"not null;type:uuid;uniqueIndex" json:"business_id"`
+	Tier                 SubscriptionTier   `gorm:"not null;size:50" json:"tier"`
+	Status               SubscriptionStatus `gorm:"not null;size:20" json:"status"`
+	StripeCustomerID     string             `gorm:"not null;size:255" json:"stripe_customer_id"`
+	StripeSubscriptionID *string            `gorm:"size:255;uniqueIndex" json:"stripe_subscription_id,omitempty"`
+	CurrentPeriodEnd     *time.Time         `gorm:"" json:"current_period_end,omitempty"`
+	TrialEndsAt          *time.Time         `gorm:"" json:"trial_ends_at,omitempty"`
+	CancelAtPeriodEnd    bool               `gorm:"not null;default:false" json:"cancel_at_period_end"`
+	SyncedAt             *time.Time         `gorm:"" json:"synced_at,omitempty"` // When Stripe reported the state mirrored
+}
+
+// TableName returns the table name for BusinessSubscription
+func (BusinessSubscription) TableName() string { return "business_subscriptions" }
+
+// EffectiveTier returns the tier the subscription gives the business: its own while its status
+// grants it, the free tier otherwise
+func (s *BusinessSubscription) EffectiveTier() SubscriptionTier {
+	if s.Status.GrantsTier() {
+		return s.Tier
+	}
+	return TierFree
+}
+
+// SubscriptionState is the state of a subscription as Stripe reported it at some time
+type SubscriptionState struct {
+	StripeSubscriptionID string
+	Tier                 SubscriptionTier
+	Status               SubscriptionStatus
+	CurrentPeriodEnd     *time.Time
+	TrialEndsAt          *time.Time
+	CancelAtPeriodEnd    bool
+	ReportedAt           time.Time
+}
+
+// Sync mirrors a state Stripe reported, unless a later one was mirrored already: webhooks
+// can arrive out of order. It reports whether the state was taken.
+func (s *BusinessSubscription) Sync(state SubscriptionState) bool {
+	if s.SyncedAt != nil && state.ReportedAt.Before(*s.SyncedAt) {
+		return false
+	}
+	if state.Tier.IsPaid() {
+		s.Tier = state.Tier
+	}
+	s.StripeSubscriptionID = &state.StripeSubscriptionID
+	s.Status = state.Status
+	s.CurrentPeriodEnd = state.CurrentPeriodEnd
+	s.TrialEndsAt = state.TrialEndsAt
+	s.CancelAtPeriodEnd = state.CancelAtPeriodEnd
+	s.SyncedAt = &state.ReportedAt
+	return true
+}
+
+// SMSUsageMonthly counts the text messages a business sent in a calendar month
+type SMSUsageMonthly struct {
+	BaseModel
+	BusinessID string    `gorm:"not null;type:uuid;uniqueIndex:uq_sms_usage_monthly" json:"business_id"`
+	Month      time.Time `gorm:"not null;type:date;uniqueIndex:uq_sms_usage_monthly" json:"month"` // The first day of the month
+	Used       int       `gorm:"not null;default:0" json:"used"`
+}
+
+// TableName returns the table name for SMSUsageMonthly
+func (SMSUsageMonthly) TableName() string { return "sms_usage_monthly" }
+
+// UsageMonth returns the first day of the calendar month SMS credits are counted in at a time
+func UsageMonth(at time.Time) time.Time {
+	year, month, _ := at.UTC().Date()
+	return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// SubscriptionRepository defines the repository interface for BusinessSubscription and the
+// usage of entitlements
+type SubscriptionRepository interface {
+	BaseRepository[BusinessSubscription]
+	FindByBusiness(ctx context.Context, businessID string) (*BusinessSubscription, error)
+	FindByStripeSubscription(ctx context.Context, stripeSubscriptionID string) (*BusinessSubscription, error)
+	// CountUsage counts how much of an entitlement a business uses at a time
+	CountUsage(ctx context.Context, businessID string, entitlement Entitlement, now time.Time) (int, error)
+	// UseSMSCredit takes a credit off the business's SMS credits of the month, reporting false
+	// when the limit has been reached. Unlimited plans are counted too.
+	UseSMSCredit(ctx context.Context, businessID string, now time.Time, limit int) (bool, error)
+	// Sync saves the subscription and gives the business its effective tier and trial end, in
+	// a single transaction
+	Sync(ctx context.Context, subscription *BusinessSubscription) error
+}
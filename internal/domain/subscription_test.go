@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBusiness_CheckEntitlement(t *testing.T) {
+	free := Business{SubscriptionTier: "free"}
+	assert.NoError(t, free.CheckEntitlement(EntitlementStaff, 0, 1))
+
+	err := free.CheckEntitlement(EntitlementStaff, 1, 3)
+	var upgrade *UpgradeRequiredError
+	require.True(t, errors.As(err, &upgrade))
+	assert.Equal(t, TierFree, upgrade.Tier)
+	assert.Equal(t, 1, upgrade.Limit)
+	assert.Equal(t, TierPro, upgrade.RequiredTier, "pro is the cheapest tier allowing four staff")
+	assert.Equal(t, "the free plan allows 1 staff; upgrade to pro for more", upgrade.Error())
+
+	err = free.CheckEntitlement(EntitlementStaff, 1, 9)
+	require.True(t, errors.As(err, &upgrade))
+	assert.Equal(t, TierPremium, upgrade.RequiredTier)
+
+	premium := Business{SubscriptionTier: "premium"}
+	assert.NoError(t, premium.CheckEntitlement(EntitlementCampaigns, 1000, 1))
+	err = premium.CheckEntitlement(EntitlementSMSCredits, 2000, 1)
+	require.True(t, errors.As(err, &upgrade))
+	assert.Empty(t, upgrade.RequiredTier, "no tier allows more text messages")
+	assert.Equal(t, "the premium plan allows 2000 sms credits", upgrade.Error())
+
+	assert.Equal(t, TierFree, Business{SubscriptionTier: "enterprise"}.Tier(), "tiers no longer sold are free")
+}
+
+func TestCheckDowngrade(t *testing.T) {
+	usage := map[Entitlement]int{EntitlementStaff: 3, EntitlementLocations: 1, EntitlementCampaigns: 2, EntitlementSMSCredits: 400}
+	assert.NoError(t, CheckDowngrade(TierPro, usage), "SMS credits start again every month")
+
+	err := CheckDowngrade(TierFree, usage)
+	assert.ErrorIs(t, err, ErrValidation)
+	assert.Contains(t, err.Error(), "3 staff of the 1 allowed")
+	assert.Contains(t, err.Error(), "2 campaigns of the 0 allowed")
+}
+
+func TestBusinessSubscription_Sync(t *testing.T) {
+	reported := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	periodEnd := reported.AddDate(0, 1, 0)
+	subscription := &BusinessSubscription{BusinessID: "business-1", Tier: TierPro, StripeCustomerID: "cus_1"}
+
+	assert.True(t, subscription.Sync(SubscriptionState{StripeSubscriptionID: "sub_1", Tier: TierPremium, Status: SubscriptionActive,
+		CurrentPeriodEnd: &periodEnd, ReportedAt: reported}))
+	assert.Equal(t, TierPremium, subscription.EffectiveTier())
+	assert.Equal(t, "sub_1", *subscription.StripeSubscriptionID)
+
+	assert.False(t, subscription.Sync(SubscriptionState{StripeSubscriptionID: "sub_1", Status: SubscriptionIncomplete,
+		ReportedAt: reported.Add(-time.Minute)}), "states reported earlier arrive late and are ignored")
+	assert.Equal(t, SubscriptionActive, subscription.Status)
+
+	assert.True(t, subscription.Sync(SubscriptionState{StripeSubscriptionID: "sub_1", Status: SubscriptionPastDue, ReportedAt: reported.Add(time.Hour)}))
+	assert.Equal(t, TierPremium, subscription.EffectiveTier(), "past due subscriptions keep their tier, and unknown prices keep it too")
+
+	assert.True(t, subscription.Sync(SubscriptionState{StripeSubscriptionID: "sub_1", Tier: TierPremium, Status: SubscriptionCanceled,
+		ReportedAt: reported.Add(2 * time.Hour)}))
+	assert.Equal(t, TierFree, subscription.EffectiveTier())
+}
+
+func TestUsageMonth(t *testing.T) {
+	lisbon, err := time.LoadLocation("Europe/Lisbon")
+	require.NoError(t, err)
+	at := time.Date(2026, 4, 1, 0, 30, 0, 0, lisbon) // Still March in UTC
+	assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), UsageMonth(at))
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrCrossTenant is returned when a request tries to read or change data of a business other
+// than the one it acts for
+var ErrCrossTenant = errors.New("cross-tenant access denied")
+
+// TenantContext identifies the business a request acts for. Repositories scoped to the tenant
+// only see and change that business's data. Signed-in users acting for no business have a
+// tenant without one, which owns no data, so that scoped repositories fail closed for them.
+type TenantContext struct {
+	BusinessID string
+}
+
+type tenantContextKey struct{}
+
+// WithTenant returns a context acting for the tenant
+func WithTenant(ctx context.Context, tenant TenantContext) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant the context acts for. Contexts without one, such as
+// background jobs and platform admin requests, are not scoped to a business.
+func TenantFromContext(ctx context.Context) (TenantContext, bool) {
+	tenant, ok := ctx.Value(tenantContextKey{}).(TenantContext)
+	return tenant, ok
+}
+
+// Owns reports whether data of the business belongs to the tenant
+func (t TenantContext) Owns(businessID string) bool {
+	return t.BusinessID != "" && t.BusinessID == businessID
+}
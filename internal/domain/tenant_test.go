@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTenantFromContext(t *testing.T) {
+	_, ok := TenantFromContext(context.Background())
+	assert.False(t, ok)
+
+	none, ok := TenantFromContext(WithTenant(context.Background(), TenantContext{}))
+	assert.True(t, ok, "a tenant without a business still scopes the context")
+	assert.False(t, none.Owns(""), "a tenant without a business owns nothing")
+
+	tenant, ok := TenantFromContext(WithTenant(context.Background(), TenantContext{BusinessID: "business-1"}))
+	assert.True(t, ok)
+	assert.True(t, tenant.Owns("business-1"))
+	assert.False(t, tenant.Owns("business-2"))
+}
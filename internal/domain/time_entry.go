@@ -0,0 +1,265 @@
+package domain
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// TimeEntryFlag marks a time entry a manager should look at before it is paid
+type TimeEntryFlag string
+
+const (
+	TimeEntryOutsideShift TimeEntryFlag = "outside_shift" // Clocked in before or out after the staff member's shifts
+	TimeEntryNoShift      TimeEntryFlag = "no_shift"      // Clocked in on a day the staff member on a rota has no shift
+	TimeEntryOverlong     TimeEntryFlag = "overlong"      // Longer than MaxTimeEntryDuration, usually a forgotten clock-out
+)
+
+const (
+	// TimeEntryGrace is how early or late staff can clock in or out around a shift without
+	// the entry being flagged
+	TimeEntryGrace = 10 * time.Minute
+	// MaxTimeEntryDuration is the longest entry not flagged as overlong
+	MaxTimeEntryDuration = 14 * time.Hour
+	// MaxTimesheetDays bounds the pay period of a timesheet
+	MaxTimesheetDays = 31
+)
+
+// TimeEntry is a period a staff member was clocked in. Entries are flagged against the staff
+// member's shifts when they are clocked in, clocked out or corrected by a manager.
+type TimeEntry struct {
+	BaseModel
+	BusinessID string     `gorm:"not null;type:uuid;index" json:"business_id"`
+	StaffID    string     `gorm:"not null;type:uuid;index" json:"staff_id"`
+	ClockIn    time.Time  `gorm:"not null;index" json:"clock_in"`
+	ClockOut   *time.Time `gorm:"" json:"clock_out,omitempty"`                    // Still clocked in when nil
+	Flags      *string    `gorm:"type:jsonb;default:'[]'" json:"flags,omitempty"` // JSON list of TimeEntryFlag
+	Notes      *string    `gorm:"type:text" json:"notes,omitempty"`
+	AdjustedBy *string    `gorm:"type:uuid" json:"adjusted_by,omitempty"` // The manager who last corrected the times
+}
+
+// TableName returns the table name for TimeEntry
+func (TimeEntry) TableName() string { return "time_entries" }
+
+// Validate validates the time entry model
+func (e *TimeEntry) Validate() error {
+	if e.BusinessID == "" || e.StaffID == "" {
+		return ErrValidation
+	}
+	if e.ClockIn.IsZero() {
+		return fmt.Errorf("%w: clock_in is required", ErrValidation)
+	}
+	if e.ClockOut != nil && !e.ClockOut.After(e.ClockIn) {
+		return fmt.Errorf("%w: clock_out must be after clock_in", ErrValidation)
+	}
+	return nil
+}
+
+// IsOpen reports whether the staff member is still clocked in
+func (e *TimeEntry) IsOpen() bool {
+	return e.ClockOut == nil
+}
+
+// Duration returns the time clocked in, zero while the entry is open
+func (e *TimeEntry) Duration() time.Duration {
+	if e.ClockOut == nil {
+		return 0
+	}
+	return e.ClockOut.Sub(e.ClockIn)
+}
+
+// Close clocks the staff member out
+func (e *TimeEntry) Close(at time.Time) error {
+	if !e.IsOpen() {
+		return fmt.Errorf("%w: already clocked out", ErrValidation)
+	}
+	if !at.After(e.ClockIn) {
+		return fmt.Errorf("%w: clock_out must be after clock_in", ErrValidation)
+	}
+	e.ClockOut = &at
+	return nil
+}
+
+// GetFlags decodes the flags of the entry
+func (e *TimeEntry) GetFlags() ([]TimeEntryFlag, error) {
+	flags := []TimeEntryFlag{}
+	if e.Flags == nil || *e.Flags == "" {
+		return flags, nil
+	}
+	if err := json.Unmarshal([]byte(*e.Flags), &flags); err != nil {
+		return nil, fmt.Errorf("invalid time entry flags: %w", err)
+	}
+	return flags, nil
+}
+
+// SetFlags encodes the flags of the entry
+func (e *TimeEntry) SetFlags(flags []TimeEntryFlag) error {
+	if flags == nil {
+		flags = []TimeEntryFlag{}
+	}
+	data, err := json.Marshal(flags)
+	if err != nil {
+		return err
+	}
+	encoded := string(data)
+	e.Flags = &encoded
+	return nil
+}
+
+// IsFlagged reports whether the entry has any flag
+func (e *TimeEntry) IsFlagged() bool {
+	flags, _ := e.GetFlags()
+	return len(flags) > 0
+}
+
+// Review flags the entry against the staff member's shifts, worked in the location. Staff
+// members without shifts are not on a rota, so only overlong entries are flagged for them.
+func (e *TimeEntry) Review(shifts []*StaffShift, location *time.Location) error {
+	var flags []TimeEntryFlag
+	if len(shifts) > 0 {
+		if flag := e.checkShifts(shifts, location); flag != "" {
+			flags = append(flags, flag)
+		}
+	}
+	if e.Duration() > MaxTimeEntryDuration {
+		flags = append(flags, TimeEntryOverlong)
+	}
+	return e.SetFlags(flags)
+}
+
+// checkShifts returns the flag of an entry not worked within the shifts, empty when it was
+func (e *TimeEntry) checkShifts(shifts []*StaffShift, location *time.Location) TimeEntryFlag {
+	clockIn := e.ClockIn.In(location)
+	intervals := WorkingIntervals(shifts, clockIn)
+	// A shift ending at midnight may run into a night shift of the next day
+	if e.ClockOut != nil && e.ClockOut.In(location).Format(time.DateOnly) != clockIn.Format(time.DateOnly) {
+		for _, next := range WorkingIntervals(shifts, e.ClockOut.In(location)) {
+			if last := len(intervals) - 1; last >= 0 && next.Start.Equal(intervals[last].End) {
+				intervals[last].End = next.End
+				continue
+			}
+			intervals = append(intervals, next)
+		}
+	}
+	if len(intervals) == 0 {
+		return TimeEntryNoShift
+	}
+	for _, interval := range intervals {
+		if e.ClockIn.Before(interval.Start.Add(-TimeEntryGrace)) || !e.ClockIn.Before(interval.End) {
+			continue
+		}
+		if e.ClockOut == nil || !e.ClockOut.After(interval.End.Add(TimeEntryGrace)) {
+			return ""
+		}
+	}
+	return TimeEntryOutsideShift
+}
+
+// TimesheetLine is the time a staff member worked during a pay period
+type TimesheetLine struct {
+	StaffID   string
+	StaffName string
+	Minutes   int // Of the closed entries, within the period
+	Entries   int
+	Flagged   int // Entries a manager should look at before paying them
+	Open      int // Entries still clocked in, not counted in Minutes
+}
+
+// Timesheet is the time the staff of a business worked during a pay period, for payroll
+type Timesheet struct {
+	Start time.Time // The first day of the period
+	End   time.Time // The day after the last day of the period
+	Lines []*TimesheetLine
+}
+
+// NewTimesheet totals the entries overlapping the period for each staff member, in the order
+// of the staff IDs. Entries spanning the bounds of the period count only the time within it;
+// entries of staff members not listed are left out.
+func NewTimesheet(start, end time.Time, staffIDs []string, names map[string]string, entries []*TimeEntry) *Timesheet {
+	timesheet := &Timesheet{Start: start, End: end}
+	lines := map[string]*TimesheetLine{}
+	for _, staffID := range staffIDs {
+		line := &TimesheetLine{StaffID: staffID, StaffName: names[staffID]}
+		lines[staffID] = line
+		timesheet.Lines = append(timesheet.Lines, line)
+	}
+
+	for _, entry := range entries {
+		line, ok := lines[entry.StaffID]
+		if !ok || !entry.ClockIn.Before(end) || (entry.ClockOut != nil && !entry.ClockOut.After(start)) {
+			continue
+		}
+		line.Entries++
+		if entry.IsFlagged() {
+			line.Flagged++
+		}
+		if entry.IsOpen() {
+			line.Open++
+			continue
+		}
+		from, to := entry.ClockIn, *entry.ClockOut
+		if from.Before(start) {
+			from = start
+		}
+		if to.After(end) {
+			to = end
+		}
+		line.Minutes += int(to.Sub(from).Minutes())
+	}
+	return timesheet
+}
+
+// timesheetColumns are the header of a timesheet CSV
+var timesheetColumns = []string{"staff_id", "staff_name", "period_start", "period_end", "hours", "entries", "flagged_entries", "open_entries"}
+
+// WriteCSV writes the timesheet as a CSV file with a header row and a row per staff member,
+// for import into payroll software. Hours are decimal, to two places.
+func (t *Timesheet) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(timesheetColumns); err != nil {
+		return err
+	}
+	periodStart := t.Start.Format(time.DateOnly)
+	periodEnd := t.End.AddDate(0, 0, -1).Format(time.DateOnly)
+	for _, line := range t.Lines {
+		err := writer.Write([]string{
+			line.StaffID,
+			csvSafe(line.StaffName),
+			periodStart,
+			periodEnd,
+			strconv.FormatFloat(float64(line.Minutes)/60, 'f', 2, 64),
+			strconv.Itoa(line.Entries),
+			strconv.Itoa(line.Flagged),
+			strconv.Itoa(line.Open),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvSafe keeps a value from being read as a formula by spreadsheet software
+func csvSafe(value string) string {
+	if value != "" && slices.Contains([]byte("=+-@\t\r"), value[0]) {
+		return "'" + value
+	}
+	return value
+}
+
+// TimeEntryRepository defines the repository interface for TimeEntry
+type TimeEntryRepository interface {
+	BaseRepository[TimeEntry]
+	// FindOpenByStaffID finds the entry the staff member is clocked in on, failing with
+	// gorm.ErrRecordNotFound when they are clocked out
+	FindOpenByStaffID(ctx context.Context, staffID string) (*TimeEntry, error)
+	// FindInRange finds the entries of a business overlapping the range, of one staff member
+	// when staffID is set, ordered by clock-in time
+	FindInRange(ctx context.Context, businessID string, staffID *string, start, end time.Time) ([]*TimeEntry, error)
+}
@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeEntryClose(t *testing.T) {
+	clockIn := time.Date(2024, 6, 3, 9, 0, 0, 0, time.UTC)
+	entry := &TimeEntry{BusinessID: "business", StaffID: "staff", ClockIn: clockIn}
+	require.NoError(t, entry.Validate())
+	assert.True(t, entry.IsOpen())
+	assert.Zero(t, entry.Duration())
+
+	assert.True(t, errors.Is(entry.Close(clockIn), ErrValidation), "clock-out must follow clock-in")
+	require.NoError(t, entry.Close(clockIn.Add(8*time.Hour)))
+	assert.False(t, entry.IsOpen())
+	assert.Equal(t, 8*time.Hour, entry.Duration())
+	assert.True(t, errors.Is(entry.Close(clockIn.Add(9*time.Hour)), ErrValidation), "already clocked out")
+}
+
+func TestTimeEntryReview(t *testing.T) {
+	lisbon, err := time.LoadLocation("Europe/Lisbon")
+	require.NoError(t, err)
+	// 2024-06-03 is a Monday
+	at := func(day, hour, minute int) time.Time { return time.Date(2024, 6, day, hour, minute, 0, 0, lisbon) }
+	shifts := []*StaffShift{
+		weeklyShift("day", time.Monday, "09:00", "17:00"),
+		weeklyShift("late", time.Friday, "18:00", "24:00"),
+		weeklyShift("night", time.Saturday, "00:00", "02:00"),
+	}
+	review := func(clockIn time.Time, clockOut *time.Time, shifts []*StaffShift) []TimeEntryFlag {
+		entry := &TimeEntry{BusinessID: "business", StaffID: "staff", ClockIn: clockIn.UTC(), ClockOut: clockOut}
+		require.NoError(t, entry.Review(shifts, lisbon))
+		flags, err := entry.GetFlags()
+		require.NoError(t, err)
+		return flags
+	}
+	ptr := func(t time.Time) *time.Time { return &t }
+
+	assert.Empty(t, review(at(3, 8, 55), ptr(at(3, 17, 5)), shifts), "within the grace around the shift")
+	assert.Empty(t, review(at(3, 8, 55), nil, shifts), "open entries are checked on clock-in")
+	assert.Equal(t, []TimeEntryFlag{TimeEntryOutsideShift}, review(at(3, 8, 30), ptr(at(3, 17, 0)), shifts))
+	assert.Equal(t, []TimeEntryFlag{TimeEntryOutsideShift}, review(at(3, 9, 0), ptr(at(3, 18, 0)), shifts))
+	assert.Equal(t, []TimeEntryFlag{TimeEntryNoShift}, review(at(4, 9, 0), ptr(at(4, 17, 0)), shifts))
+	assert.Empty(t, review(at(7, 18, 0), ptr(at(8, 2, 0)), shifts), "a late shift running into a night shift")
+
+	assert.Empty(t, review(at(4, 9, 0), ptr(at(4, 17, 0)), nil), "staff not on a rota are not checked against shifts")
+	assert.Equal(t, []TimeEntryFlag{TimeEntryOverlong}, review(at(4, 6, 0), ptr(at(4, 22, 0)), nil))
+	assert.Equal(t, []TimeEntryFlag{TimeEntryOutsideShift, TimeEntryOverlong}, review(at(3, 9, 0), ptr(at(4, 9, 0)), shifts))
+}
+
+func TestNewTimesheet(t *testing.T) {
+	start := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 6, 16, 0, 0, 0, 0, time.UTC)
+	entry := func(staffID string, clockIn time.Time, hours int, flags ...TimeEntryFlag) *TimeEntry {
+		e := &TimeEntry{StaffID: staffID, ClockIn: clockIn}
+		if hours > 0 {
+			clockOut := clockIn.Add(time.Duration(hours) * time.Hour)
+			e.ClockOut = &clockOut
+		}
+		require.NoError(t, e.SetFlags(flags))
+		return e
+	}
+	entries := []*TimeEntry{
+		entry("ana", start.Add(-2*time.Hour), 4), // 2 hours before the period
+		entry("ana", start.AddDate(0, 0, 2).Add(9*time.Hour), 8, TimeEntryOutsideShift),
+		entry("ana", end.Add(-time.Hour), 3), // 1 hour within the period
+		entry("rui", start.AddDate(0, 0, 3).Add(9*time.Hour), 0),
+		entry("rui", end.Add(time.Hour), 8), // After the period
+		entry("unlisted", start.Add(9*time.Hour), 8),
+	}
+
+	timesheet := NewTimesheet(start, end, []string{"ana", "rui", "eva"}, map[string]string{"ana": "Ana Silva", "rui": "=Rui"}, entries)
+	require.Len(t, timesheet.Lines, 3)
+	assert.Equal(t, TimesheetLine{StaffID: "ana", StaffName: "Ana Silva", Minutes: (2 + 8 + 1) * 60, Entries: 3, Flagged: 1}, *timesheet.Lines[0])
+	assert.Equal(t, TimesheetLine{StaffID: "rui", StaffName: "=Rui", Entries: 1, Open: 1}, *timesheet.Lines[1])
+	assert.Equal(t, TimesheetLine{StaffID: "eva"}, *timesheet.Lines[2])
+
+	var csv strings.Builder
+	require.NoError(t, timesheet.WriteCSV(&csv))
+	assert.Equal(t, "staff_id,staff_name,period_start,period_end,hours,entries,flagged_entries,open_entries\n"+
+		"ana,Ana Silva,2024-06-01,2024-06-15,11.00,3,1,0\n"+
+		"rui,'=Rui,2024-06-01,2024-06-15,0.00,1,0,1\n"+
+		"eva,,2024-06-01,2024-06-15,0.00,0,0,0\n", csv.String())
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// TrashRetention is how long deleted records stay in the trash, where they can be restored
+const TrashRetention = 30 * 24 * time.Hour
+
+// TrashEntityType is a kind of record that can be restored from the trash
+type TrashEntityType string
+
+const (
+	TrashEntityClient      TrashEntityType = "client"
+	TrashEntityService     TrashEntityType = "service"
+	TrashEntityAppointment TrashEntityType = "appointment"
+)
+
+// IsValid checks if the trash entity type is valid
+func (t TrashEntityType) IsValid() bool {
+	switch t {
+	case TrashEntityClient, TrashEntityService, TrashEntityAppointment:
+		return true
+	}
+	return false
+}
+
+// TrashCutoff returns the earliest deletion time of the records still in the trash
+func TrashCutoff(now time.Time) time.Time {
+	return now.Add(-TrashRetention)
+}
+
+// RestorableUntil returns when a record deleted at a time leaves the trash for good
+func RestorableUntil(deletedAt time.Time) time.Time {
+	return deletedAt.Add(TrashRetention)
+}
+
+// CheckRestore checks that a deleted appointment can be put back in the book given the client
+// it is for, nil when the client is deleted too. An upcoming appointment must not clash with
+// what its staff member has booked since, reported as overlapping.
+func (a *Appointment) CheckRestore(client *Client, overlapping bool, now time.Time) error {
+	if client == nil {
+		return fmt.Errorf("%w: the client of the appointment is deleted, restore the client first", ErrValidation)
+	}
+	if overlapping && a.CanBeCancelled() && a.EndTime.After(now) {
+		return fmt.Errorf("%w: the staff member has been booked at the time of the appointment since it was deleted", ErrValidation)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppointmentCheckRestore(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	client := &Client{FirstName: "Ana"}
+	upcoming := &Appointment{Status: AppointmentStatusConfirmed, StartTime: now.Add(time.Hour), EndTime: now.Add(2 * time.Hour)}
+
+	assert.NoError(t, upcoming.CheckRestore(client, false, now))
+	assert.ErrorIs(t, upcoming.CheckRestore(nil, false, now), ErrValidation, "the client must be restored first")
+	assert.ErrorIs(t, upcoming.CheckRestore(client, true, now), ErrValidation, "the slot was booked since")
+
+	past := &Appointment{Status: AppointmentStatusCompleted, StartTime: now.Add(-2 * time.Hour), EndTime: now.Add(-time.Hour)}
+	assert.NoError(t, past.CheckRestore(client, true, now), "past appointments only go back in the history")
+
+	cancelled := &Appointment{Status: AppointmentStatusCancelled, StartTime: now.Add(time.Hour), EndTime: now.Add(2 * time.Hour)}
+	assert.NoError(t, cancelled.CheckRestore(client, true, now), "cancelled appointments do not take the slot")
+}
+
+func TestTrashRetention(t *testing.T) {
+	deletedAt := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2026, 10, 31, 9, 0, 0, 0, time.UTC), RestorableUntil(deletedAt))
+	assert.True(t, TrashCutoff(RestorableUntil(deletedAt)).Equal(deletedAt))
+}
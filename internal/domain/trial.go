@@ -0,0 +1,119 @@
+package domain
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// TrialGracePeriod is how long a business keeps its trial tier after the trial ends, so that
+// it can subscribe before it is restricted to the free plan
+const TrialGracePeriod = 7 * 24 * time.Hour
+
+// TrialState is where a business is in a free trial of a paid tier
+type TrialState string
+
+const (
+	TrialNone    TrialState = "none"    // Not on a trial, or billed by Stripe after it
+	TrialActive  TrialState = "active"  // The trial has not ended
+	TrialGrace   TrialState = "grace"   // The trial ended; the business keeps its tier for the grace period
+	TrialExpired TrialState = "expired" // The grace period passed; the business is restricted to the free plan
+)
+
+// TrialNoticeKind is an email warning the owner that the trial is ending
+type TrialNoticeKind string
+
+const (
+	TrialNoticeSevenDays TrialNoticeKind = "seven_days" // A week before the trial ends
+	TrialNoticeOneDay    TrialNoticeKind = "one_day"    // The day before
+	TrialNoticeEnded     TrialNoticeKind = "ended"      // When it ends, with the grace period left
+)
+
+// trialNotices lists the notices from the earliest, with how long before the end of the trial
+// each is sent
+var trialNotices = []struct {
+	kind TrialNoticeKind
+	lead time.Duration
+}{
+	{TrialNoticeSevenDays, 7 * 24 * time.Hour},
+	{TrialNoticeOneDay, 24 * time.Hour},
+	{TrialNoticeEnded, 0},
+}
+
+// TrialNoticeHorizon is how long before the end of a trial the first notice is sent
+const TrialNoticeHorizon = 7 * 24 * time.Hour
+
+// TrialState returns where the business is in its trial. Businesses billed by Stripe are not
+// on an in-app trial; Stripe ends their trials by charging them.
+func (b Business) TrialState(now time.Time) TrialState {
+	if b.TrialEndsAt == nil {
+		return TrialNone
+	}
+	if b.TrialExpiredAt != nil {
+		return TrialExpired
+	}
+	if now.Before(*b.TrialEndsAt) {
+		return TrialActive
+	}
+	if now.Before(b.TrialEndsAt.Add(TrialGracePeriod)) {
+		return TrialGrace
+	}
+	return TrialExpired
+}
+
+// GraceEndsAt returns when the business is restricted to the free plan, for businesses on a trial
+func (b Business) GraceEndsAt() *time.Time {
+	if b.TrialEndsAt == nil {
+		return nil
+	}
+	graceEndsAt := b.TrialEndsAt.Add(TrialGracePeriod)
+	return &graceEndsAt
+}
+
+// DueTrialNotice returns the latest notice due at a time, if any. Notices missed while the job
+// was not running are not caught up on, so owners get one email rather than several at once.
+func (b Business) DueTrialNotice(now time.Time) (TrialNoticeKind, bool) {
+	if b.TrialEndsAt == nil || b.TrialExpiredAt != nil || !now.Before(b.TrialEndsAt.Add(TrialGracePeriod)) {
+		return "", false
+	}
+	for i := len(trialNotices) - 1; i >= 0; i-- {
+		if !now.Before(b.TrialEndsAt.Add(-trialNotices[i].lead)) {
+			return trialNotices[i].kind, true
+		}
+	}
+	return "", false
+}
+
+// DaysLeft returns the whole days, rounded up, from a time until another, or zero once passed
+func DaysLeft(now, until time.Time) int {
+	if !now.Before(until) {
+		return 0
+	}
+	return int(math.Ceil(until.Sub(now).Hours() / 24))
+}
+
+// TrialNotice records a notice sent about a trial ending, so that it is sent once per trial
+type TrialNotice struct {
+	BaseModel
+	BusinessID  string          `gorm:"not null;type:uuid;uniqueIndex:uq_trial_notices" json:"business_id"`
+	Kind        TrialNoticeKind `gorm:"not null;size:20;uniqueIndex:uq_trial_notices" json:"kind"`
+	TrialEndsAt time.Time       `gorm:"not null;uniqueIndex:uq_trial_notices" json:"trial_ends_at"` // The trial the notice is about
+	SentAt      time.Time       `gorm:"not null" json:"sent_at"`
+}
+
+// TableName returns the table name for TrialNotice
+func (TrialNotice) TableName() string { return "trial_notices" }
+
+// TrialRepository defines the repository interface for the in-app trials of businesses
+type TrialRepository interface {
+	// FindEnding finds the businesses on an in-app trial of a paid tier, not billed by Stripe,
+	// whose trial ends before a time or has ended without their being restricted yet
+	FindEnding(ctx context.Context, before time.Time) ([]*Business, error)
+	// RecordNotice records a notice unless it was already recorded for the trial, reporting
+	// whether it was
+	RecordNotice(ctx context.Context, notice *TrialNotice) (bool, error)
+	// Restrict moves a business whose trial and grace period are over to the free tier and
+	// marks the trial expired, unless it has since subscribed or its trial was extended. It
+	// reports whether the business was restricted.
+	Restrict(ctx context.Context, businessID string, trialEndsAt, now time.Time) (bool, error)
+}
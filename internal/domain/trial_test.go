@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBusiness_TrialState(t *testing.T) {
+	trialEndsAt := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	business := Business{SubscriptionTier: "pro", TrialEndsAt: &trialEndsAt}
+
+	assert.Equal(t, TrialNone, Business{}.TrialState(trialEndsAt))
+	assert.Equal(t, TrialActive, business.TrialState(trialEndsAt.Add(-time.Minute)))
+	assert.Equal(t, TrialGrace, business.TrialState(trialEndsAt))
+	assert.Equal(t, TrialGrace, business.TrialState(trialEndsAt.Add(TrialGracePeriod-time.Minute)))
+	assert.Equal(t, TrialExpired, business.TrialState(trialEndsAt.Add(TrialGracePeriod)), "due to be restricted")
+
+	expiredAt := trialEndsAt.Add(TrialGracePeriod)
+	business.TrialExpiredAt = &expiredAt
+	assert.Equal(t, TrialExpired, business.TrialState(trialEndsAt))
+	assert.Equal(t, time.Date(2026, 3, 17, 12, 0, 0, 0, time.UTC), *business.GraceEndsAt())
+}
+
+func TestBusiness_DueTrialNotice(t *testing.T) {
+	trialEndsAt := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	business := Business{SubscriptionTier: "pro", TrialEndsAt: &trialEndsAt}
+
+	_, ok := business.DueTrialNotice(trialEndsAt.AddDate(0, 0, -8))
+	assert.False(t, ok)
+
+	kind, ok := business.DueTrialNotice(trialEndsAt.AddDate(0, 0, -7))
+	assert.True(t, ok)
+	assert.Equal(t, TrialNoticeSevenDays, kind)
+
+	kind, _ = business.DueTrialNotice(trialEndsAt.AddDate(0, 0, -3))
+	assert.Equal(t, TrialNoticeSevenDays, kind, "still the latest notice due")
+
+	kind, _ = business.DueTrialNotice(trialEndsAt.Add(-time.Hour))
+	assert.Equal(t, TrialNoticeOneDay, kind)
+
+	kind, _ = business.DueTrialNotice(trialEndsAt.Add(time.Hour))
+	assert.Equal(t, TrialNoticeEnded, kind)
+
+	_, ok = business.DueTrialNotice(trialEndsAt.Add(TrialGracePeriod))
+	assert.False(t, ok, "businesses past their grace period are restricted instead")
+}
+
+func TestDaysLeft(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, 1, DaysLeft(now, now.Add(time.Hour)))
+	assert.Equal(t, 2, DaysLeft(now, now.Add(25*time.Hour)))
+	assert.Equal(t, 0, DaysLeft(now, now.Add(-time.Hour)))
+}
@@ -3,6 +3,7 @@ package domain
 import (
 	"context"
 	"errors"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -21,6 +22,10 @@ type User struct {
 	LastName  string   `gorm:"not null;size:100" json:"last_name"`
 	Phone     *string  `gorm:"size:50" json:"phone,omitempty"`
 	IsActive  bool     `gorm:"not null;default:true" json:"is_active"`
+	SessionsRevokedAt *time.Time `json:"sessions_revoked_at,omitempty"` // Sessions signed in before then are signed out
+	Locale            *string    `gorm:"size:10" json:"locale,omitempty"`      // The locale the user reads the app in; their browser's when nil
+	PasswordChangedAt *time.Time `json:"password_changed_at,omitempty"`       // When Clerk last reported a password change; unknown when nil
+	TwoFactorEnabled  *bool      `json:"two_factor_enabled,omitempty"`        // Whether Clerk last reported two-factor authentication on; unknown when nil
 
 	// Relationships
 	Businesses           []Business           `gorm:"foreignKey:UserID" json:"businesses,omitempty"`
@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrSessionRevoked is returned when a request is made with a session that was signed out
+var ErrSessionRevoked = errors.New("session revoked")
+
+// ErrUnauthenticated is returned when a request is made with credentials that cannot be verified
+var ErrUnauthenticated = errors.New("unauthenticated")
+
+// SessionKind is how a session authenticates its requests
+type SessionKind string
+
+const (
+	SessionKindClerk  SessionKind = "clerk"   // A browser or app signed in through Clerk
+	SessionKindAPIKey SessionKind = "api_key" // An API key issued to the user
+)
+
+// SessionRevocationReason is why a session was signed out
+type SessionRevocationReason string
+
+const (
+	SessionRevokedByUser          SessionRevocationReason = "user"              // Signed out from the list of devices
+	SessionRevokedSignOutAll      SessionRevocationReason = "sign_out_all"      // Every device was signed out at once
+	SessionRevokedPasswordChange  SessionRevocationReason = "password_change"   // The password was changed
+	SessionRevokedTwoFactorChange SessionRevocationReason = "two_factor_change" // Two-factor authentication was set up or removed
+)
+
+// IsValid checks if the revocation reason is valid
+func (r SessionRevocationReason) IsValid() bool {
+	switch r {
+	case SessionRevokedByUser, SessionRevokedSignOutAll, SessionRevokedPasswordChange, SessionRevokedTwoFactorChange:
+		return true
+	}
+	return false
+}
+
+// sessionSeenInterval is how stale the last use of a session may get before it is recorded again,
+// so that not every request writes to the session
+const sessionSeenInterval = time.Minute
+
+// UserSession is a device or API key a user is signed in with. Sessions are recorded when first
+// seen by the API; revoking one puts it on the revocation list checked on every request.
+type UserSession struct {
+	BaseModel
+	UserID        string                   `gorm:"not null;type:uuid;index" json:"user_id"`
+	Kind          SessionKind              `gorm:"not null;size:20" json:"kind"`
+	ExternalID    string                   `gorm:"not null;size:255" json:"external_id"` // The Clerk session ID, or the API key's ID
+	DeviceName    *string                  `gorm:"size:255" json:"device_name,omitempty"`
+	UserAgent     *string                  `gorm:"size:500" json:"user_agent,omitempty"`
+	IPAddress     *string                  `gorm:"size:45" json:"ip_address,omitempty"`
+	IssuedAt      time.Time                `gorm:"not null" json:"issued_at"`
+	LastSeenAt    time.Time                `gorm:"not null" json:"last_seen_at"`
+	RevokedAt     *time.Time               `json:"revoked_at,omitempty"`
+	RevokedReason *SessionRevocationReason `gorm:"size:30" json:"revoked_reason,omitempty"`
+
+	// Relationships
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// TableName returns the table name for UserSession
+func (UserSession) TableName() string { return "user_sessions" }
+
+// Validate validates the user session model
+func (s *UserSession) Validate() error {
+	if s.UserID == "" || s.ExternalID == "" {
+		return ErrValidation
+	}
+	if s.Kind != SessionKindClerk && s.Kind != SessionKindAPIKey {
+		return fmt.Errorf("%w: unknown session kind %q", ErrValidation, s.Kind)
+	}
+	if s.RevokedReason != nil && !s.RevokedReason.IsValid() {
+		return fmt.Errorf("%w: unknown revocation reason %q", ErrValidation, *s.RevokedReason)
+	}
+	return nil
+}
+
+// IsRevoked reports whether the session was signed out, by itself or together with every session
+// its user had signed in before signing all devices out at revokedBefore
+func (s *UserSession) IsRevoked(revokedBefore *time.Time) bool {
+	if s.RevokedAt != nil {
+		return true
+	}
+	return revokedBefore != nil && !s.IssuedAt.After(*revokedBefore)
+}
+
+// CheckActive checks that requests may still be made with the session
+func (s *UserSession) CheckActive(revokedBefore *time.Time) error {
+	if s.IsRevoked(revokedBefore) {
+		return ErrSessionRevoked
+	}
+	return nil
+}
+
+// Revoke signs the session out for a reason
+func (s *UserSession) Revoke(reason SessionRevocationReason, now time.Time) error {
+	if s.RevokedAt != nil {
+		return fmt.Errorf("%w: the session is already signed out", ErrValidation)
+	}
+	if !reason.IsValid() {
+		return fmt.Errorf("%w: unknown revocation reason %q", ErrValidation, reason)
+	}
+	s.RevokedAt = &now
+	s.RevokedReason = &reason
+	return nil
+}
+
+// Seen records that a request was made with the session, reporting whether the change is worth
+// saving
+func (s *UserSession) Seen(now time.Time) bool {
+	if now.Sub(s.LastSeenAt) < sessionSeenInterval {
+		return false
+	}
+	s.LastSeenAt = now
+	return true
+}
+
+// UserSessionRepository defines the repository interface for UserSession
+type UserSessionRepository interface {
+	BaseRepository[UserSession]
+	FindByExternalID(ctx context.Context, kind SessionKind, externalID string) (*UserSession, error)
+	FindByUser(ctx context.Context, userID string, includeRevoked bool) ([]*UserSession, error) // Most recently seen first
+	RevokeAllByUser(ctx context.Context, userID string, reason SessionRevocationReason, at time.Time) (int64, error)
+}
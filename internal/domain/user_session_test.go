@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserSessionRevoke(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	session := &UserSession{UserID: "user-1", Kind: SessionKindClerk, ExternalID: "sess_1", IssuedAt: now.Add(-time.Hour)}
+	require.NoError(t, session.Validate())
+	assert.NoError(t, session.CheckActive(nil))
+
+	assert.ErrorIs(t, session.Revoke("forgot", now), ErrValidation)
+	require.NoError(t, session.Revoke(SessionRevokedByUser, now))
+	assert.ErrorIs(t, session.CheckActive(nil), ErrSessionRevoked)
+	assert.ErrorIs(t, session.Revoke(SessionRevokedByUser, now), ErrValidation, "sessions are signed out once")
+}
+
+func TestUserSessionRevokedBefore(t *testing.T) {
+	signOutAll := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	before := &UserSession{IssuedAt: signOutAll.Add(-time.Minute)}
+	after := &UserSession{IssuedAt: signOutAll.Add(time.Minute)}
+
+	assert.True(t, before.IsRevoked(&signOutAll), "sessions signed in before signing out everywhere are revoked")
+	assert.False(t, after.IsRevoked(&signOutAll), "sessions signed in since are not")
+	assert.False(t, before.IsRevoked(nil))
+}
+
+func TestUserSessionSeen(t *testing.T) {
+	now := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	session := &UserSession{LastSeenAt: now}
+
+	assert.False(t, session.Seen(now.Add(10*time.Second)), "recent use is not recorded again")
+	assert.True(t, session.Seen(now.Add(2*time.Minute)))
+	assert.Equal(t, now.Add(2*time.Minute), session.LastSeenAt)
+}
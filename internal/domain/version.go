@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrConflict is returned when an update was made to an outdated version of an entity
+var ErrConflict = errors.New("version conflict")
+
+// ConflictError reports an update made to an outdated version of an entity: someone else
+// changed the entity since it was read. It matches ErrConflict.
+type ConflictError struct {
+	EntityType string // The table of the entity, e.g. "appointments"
+	ID         string
+	Version    int // The version the update expected
+}
+
+// Error implements the error interface
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s %s was changed by someone else since version %d, reload it and try again", e.EntityType, e.ID, e.Version)
+}
+
+// Is reports whether the target is ErrConflict
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// CheckVersion checks that an entity is still at the version the caller read it at, so that
+// a change based on an outdated read is turned down before it is worked out. A nil version
+// skips the check, leaving it to the repository's update.
+func (b *BaseModel) CheckVersion(entityType string, expected *int) error {
+	if expected == nil {
+		return nil
+	}
+	if *expected != b.Version {
+		return &ConflictError{EntityType: entityType, ID: b.ID, Version: *expected}
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseModelCheckVersion(t *testing.T) {
+	appointment := &Appointment{BaseModel: BaseModel{ID: "appointment-1", Version: 4}}
+
+	assert.NoError(t, appointment.CheckVersion("appointments", nil), "no expected version skips the check")
+	current := 4
+	assert.NoError(t, appointment.CheckVersion("appointments", &current))
+
+	stale := 3
+	err := appointment.CheckVersion("appointments", &stale)
+	require.ErrorIs(t, err, ErrConflict)
+	assert.Contains(t, err.Error(), "since version 3")
+}
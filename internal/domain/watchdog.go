@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// WatchdogCheck is a kind of background work the watchdog looks for stuck items of
+type WatchdogCheck string
+
+const (
+	WatchdogEventLeases         WatchdogCheck = "event_leases"         // Outbox events leased further ahead than a relay ever leases them
+	WatchdogEventBacklog        WatchdogCheck = "event_backlog"        // Outbox events overdue for publishing
+	WatchdogNotificationLeases  WatchdogCheck = "notification_leases"  // Queued notifications scheduled further ahead than any retry
+	WatchdogNotificationBacklog WatchdogCheck = "notification_backlog" // Queued notifications overdue for sending
+	WatchdogWebhookLeases       WatchdogCheck = "webhook_leases"       // Webhook deliveries scheduled further ahead than any retry
+	WatchdogWebhookBacklog      WatchdogCheck = "webhook_backlog"      // Webhook deliveries overdue
+	WatchdogStalledBroadcasts   WatchdogCheck = "stalled_broadcasts"   // Broadcasts that stopped sending part way
+	WatchdogOpenPayments        WatchdogCheck = "open_payments"        // Card payments neither taken nor failed
+	WatchdogUnansweredRequests  WatchdogCheck = "unanswered_requests"  // Online booking requests still holding their slot once it started
+)
+
+// WatchdogAction is what the watchdog did about the stuck items a check found
+type WatchdogAction string
+
+const (
+	WatchdogActionNone     WatchdogAction = "none"     // Nothing was stuck
+	WatchdogActionRequeued WatchdogAction = "requeued" // Made due again, for their worker to pick up
+	WatchdogActionExpired  WatchdogAction = "expired"  // Cancelled, releasing what they held
+	WatchdogActionAlerted  WatchdogAction = "alerted"  // Left for an operator, who was alerted
+)
+
+// WatchdogLeaseMargin is how far past the longest delay its worker sets an item's next attempt
+// must be before the item counts as stuck
+const WatchdogLeaseMargin = 15 * time.Minute
+
+// WatchdogThresholds are how long items may be overdue before the watchdog alerts operators
+type WatchdogThresholds struct {
+	EventBacklog        time.Duration
+	NotificationBacklog time.Duration
+	WebhookBacklog      time.Duration
+	StalledBroadcast    time.Duration // Since the broadcast was created
+	OpenPayment         time.Duration // Since the payment was created
+}
+
+// DefaultWatchdogThresholds returns the thresholds used unless configured otherwise. Workers
+// run every few seconds to five minutes, so these leave room for a slow run or a restart.
+func DefaultWatchdogThresholds() WatchdogThresholds {
+	return WatchdogThresholds{
+		EventBacklog:        15 * time.Minute,
+		NotificationBacklog: 30 * time.Minute,
+		WebhookBacklog:      30 * time.Minute,
+		StalledBroadcast:    30 * time.Minute,
+		OpenPayment:         24 * time.Hour,
+	}
+}
+
+// EventLeaseCutoff returns the time past which an outbox event's lease is stuck: relays lease
+// events for a minute and retry them within the longest publish delay
+func EventLeaseCutoff(now time.Time) time.Time {
+	return now.Add(eventPublishRetryMaxDelay + WatchdogLeaseMargin)
+}
+
+// NotificationLeaseCutoff returns the time past which a queued notification's next attempt is stuck
+func NotificationLeaseCutoff(now time.Time) time.Time {
+	return now.Add(notificationRetryMaxDelay + WatchdogLeaseMargin)
+}
+
+// WebhookLeaseCutoff returns the time past which a webhook delivery's next attempt is stuck
+func WebhookLeaseCutoff(now time.Time) time.Time {
+	return now.Add(webhookRetryMaxDelay + WatchdogLeaseMargin)
+}
+
+// StuckItems counts the stuck items a check found
+type StuckItems struct {
+	Count    int64
+	OldestAt *time.Time // When the oldest of them was due or created
+}
+
+// WatchdogFinding is the outcome of a check
+type WatchdogFinding struct {
+	Check      WatchdogCheck
+	Count      int64 // Stuck items found
+	Remediated int64 // Of which fixed by the watchdog
+	OldestAt   *time.Time
+	Action     WatchdogAction
+	Error      *string // Why the check or its remediation failed
+}
+
+// NeedsOperator reports whether an operator has to look into the finding: stuck items the
+// watchdog cannot fix, or a check that failed
+func (f *WatchdogFinding) NeedsOperator() bool {
+	return f.Error != nil || f.Count > f.Remediated
+}
+
+// WatchdogReport is the outcome of a watchdog run
+type WatchdogReport struct {
+	RanAt    time.Time
+	Findings []*WatchdogFinding
+}
+
+// NeedingOperator returns the findings an operator has to look into
+func (r *WatchdogReport) NeedingOperator() []*WatchdogFinding {
+	var findings []*WatchdogFinding
+	for _, finding := range r.Findings {
+		if finding.NeedsOperator() {
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
+
+// WatchdogRepository defines the interface for finding and requeuing stuck background work
+type WatchdogRepository interface {
+	// RequeueEventLeases makes the unpublished events leased past the cutoff due at now
+	RequeueEventLeases(ctx context.Context, cutoff, now time.Time) (int64, error)
+	// FindEventBacklog counts the unpublished events that were due before a time
+	FindEventBacklog(ctx context.Context, dueBefore time.Time) (StuckItems, error)
+	// RequeueNotifications makes the pending notifications scheduled past the cutoff due at now
+	RequeueNotifications(ctx context.Context, cutoff, now time.Time) (int64, error)
+	FindNotificationBacklog(ctx context.Context, dueBefore time.Time) (StuckItems, error)
+	// RequeueWebhookDeliveries makes the pending deliveries scheduled past the cutoff due at now
+	RequeueWebhookDeliveries(ctx context.Context, cutoff, now time.Time) (int64, error)
+	FindWebhookBacklog(ctx context.Context, dueBefore time.Time) (StuckItems, error)
+	// FindStalledBroadcasts counts the broadcasts created before a time that never finished sending
+	FindStalledBroadcasts(ctx context.Context, createdBefore time.Time) (StuckItems, error)
+	// FindOpenPayments counts the pending payments, or those awaiting authentication, created before a time
+	FindOpenPayments(ctx context.Context, createdBefore time.Time) (StuckItems, error)
+	// FindUnansweredRequests finds the appointments still pending acceptance that started before a
+	// time, oldest first
+	FindUnansweredRequests(ctx context.Context, startedBefore time.Time, limit int) ([]*Appointment, error)
+}
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchdogLeaseCutoffs(t *testing.T) {
+	now := time.Date(2026, 5, 4, 9, 0, 0, 0, time.UTC)
+
+	// The longest retry delay a worker sets must never count as stuck
+	assert.True(t, EventLeaseCutoff(now).After(now.Add(EventPublishRetryDelay(100))))
+	assert.True(t, NotificationLeaseCutoff(now).After(now.Add(NotificationRetryDelay(100))))
+	assert.True(t, WebhookLeaseCutoff(now).After(now.Add(WebhookRetryDelay(100))))
+
+	assert.Equal(t, now.Add(time.Hour+WatchdogLeaseMargin), EventLeaseCutoff(now))
+	assert.Equal(t, now.Add(6*time.Hour+WatchdogLeaseMargin), NotificationLeaseCutoff(now))
+}
+
+func TestWatchdogFinding_NeedsOperator(t *testing.T) {
+	failed := "connection refused"
+	tests := []struct {
+		name    string
+		finding WatchdogFinding
+		want    bool
+	}{
+		{"nothing stuck", WatchdogFinding{}, false},
+		{"all remediated", WatchdogFinding{Count: 3, Remediated: 3}, false},
+		{"some left", WatchdogFinding{Count: 3, Remediated: 2}, true},
+		{"left for operators", WatchdogFinding{Count: 1}, true},
+		{"check failed", WatchdogFinding{Error: &failed}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.finding.NeedsOperator())
+		})
+	}
+}
+
+func TestWatchdogReport_NeedingOperator(t *testing.T) {
+	report := &WatchdogReport{Findings: []*WatchdogFinding{
+		{Check: WatchdogEventLeases, Count: 2, Remediated: 2, Action: WatchdogActionRequeued},
+		{Check: WatchdogEventBacklog, Count: 5, Action: WatchdogActionAlerted},
+		{Check: WatchdogOpenPayments, Action: WatchdogActionNone},
+	}}
+
+	findings := report.NeedingOperator()
+	if assert.Len(t, findings, 1) {
+		assert.Equal(t, WatchdogEventBacklog, findings[0].Check)
+	}
+}
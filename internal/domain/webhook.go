@@ -0,0 +1,285 @@
+package domain
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Event types delivered to webhook subscriptions
+const (
+	WebhookEventAppointmentCreated        = "appointment.created"
+	WebhookEventAppointmentCancelled      = "appointment.cancelled"
+	WebhookEventClientCreated             = "client.created"
+	WebhookEventServiceCompletionRecorded = "servicecompletion.recorded"
+)
+
+// WebhookEventTypes lists the event types a webhook subscription can receive
+var WebhookEventTypes = []string{
+	WebhookEventAppointmentCreated,
+	WebhookEventAppointmentCancelled,
+	WebhookEventClientCreated,
+	WebhookEventServiceCompletionRecorded,
+}
+
+// webhookEventsByDomainEvent maps the domain events published to integrations to the event
+// types webhooks receive them as
+var webhookEventsByDomainEvent = map[string]string{
+	EventAppointmentCreated:   WebhookEventAppointmentCreated,
+	EventAppointmentCancelled: WebhookEventAppointmentCancelled,
+	EventClientCreated:        WebhookEventClientCreated,
+	EventAppointmentCompleted: WebhookEventServiceCompletionRecorded,
+}
+
+// MaxWebhookAttempts is how many times a webhook delivery is attempted before it fails for good
+const MaxWebhookAttempts = 8
+
+// Bounds of the delay between attempts to deliver a webhook
+const (
+	webhookRetryBaseDelay = 30 * time.Second
+	webhookRetryMaxDelay  = 6 * time.Hour
+)
+
+// MaxWebhookSubscriptions bounds the webhook subscriptions of a business
+const MaxWebhookSubscriptions = 10
+
+// Headers sent with every webhook delivery
+const (
+	WebhookSignatureHeader = "X-Beautix-Signature"
+	WebhookEventHeader     = "X-Beautix-Event"
+	WebhookDeliveryHeader  = "X-Beautix-Delivery"
+)
+
+// WebhookEventFor returns the webhook event type a domain event is delivered as, if any
+func WebhookEventFor(event *DomainEvent) (string, bool) {
+	eventType, ok := webhookEventsByDomainEvent[event.EventType]
+	return eventType, ok
+}
+
+// WebhookSubscription is an endpoint of a third-party integration that a business has
+// subscribed to some of its events. Deliveries are signed with the subscription's secret.
+type WebhookSubscription struct {
+	BaseModel
+	BusinessID  string  `gorm:"not null;type:uuid;index" json:"business_id"`
+	URL         string  `gorm:"not null;size:500" json:"url"`
+	Secret      string  `gorm:"not null;size:100" json:"-"`
+	EventTypes  *string `gorm:"type:jsonb;default:'[]'" json:"event_types,omitempty"` // JSON list of webhook event types
+	Description *string `gorm:"size:255" json:"description,omitempty"`
+	IsActive    bool    `gorm:"not null;default:true" json:"is_active"`
+}
+
+// TableName returns the table name for WebhookSubscription
+func (WebhookSubscription) TableName() string { return "webhook_subscriptions" }
+
+// Validate validates the webhook subscription model
+func (s *WebhookSubscription) Validate() error {
+	if s.BusinessID == "" || s.Secret == "" {
+		return ErrValidation
+	}
+	endpoint, err := url.Parse(s.URL)
+	if err != nil || endpoint.Scheme != "https" || endpoint.Host == "" {
+		return fmt.Errorf("%w: the webhook URL must be an absolute https URL", ErrValidation)
+	}
+	eventTypes, err := s.GetEventTypes()
+	if err != nil {
+		return err
+	}
+	if len(eventTypes) == 0 {
+		return fmt.Errorf("%w: subscribe to at least one event type", ErrValidation)
+	}
+	for _, eventType := range eventTypes {
+		if !slices.Contains(WebhookEventTypes, eventType) {
+			return fmt.Errorf("%w: unknown webhook event type %q", ErrValidation, eventType)
+		}
+	}
+	return nil
+}
+
+// GetEventTypes decodes the event types the subscription receives
+func (s *WebhookSubscription) GetEventTypes() ([]string, error) {
+	return decodeIDs(s.EventTypes)
+}
+
+// SetEventTypes encodes the event types the subscription receives, dropping duplicates
+func (s *WebhookSubscription) SetEventTypes(eventTypes []string) error {
+	unique := make([]string, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		if !slices.Contains(unique, eventType) {
+			unique = append(unique, eventType)
+		}
+	}
+	encoded, err := encodeIDs(unique)
+	if err != nil {
+		return err
+	}
+	s.EventTypes = encoded
+	return nil
+}
+
+// Receives reports whether the subscription is delivered events of a type
+func (s *WebhookSubscription) Receives(eventType string) bool {
+	if !s.IsActive {
+		return false
+	}
+	eventTypes, _ := s.GetEventTypes()
+	return slices.Contains(eventTypes, eventType)
+}
+
+// NewWebhookSecret generates the secret a webhook subscription signs its deliveries with
+func NewWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
+
+// SignWebhookPayload returns the signature header of a webhook delivery sent at a time:
+// "t=<unix time>,v1=<hex HMAC-SHA256 of "<unix time>.<body>">". Receivers recompute the HMAC
+// with their secret and reject old timestamps to prevent replays.
+func SignWebhookPayload(secret string, sentAt time.Time, body []byte) string {
+	timestamp := strconv.FormatInt(sentAt.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookPayload is the JSON body of a webhook delivery
+type WebhookPayload struct {
+	ID         string          `json:"id"` // The ID of the event, the same for every retry
+	Type       string          `json:"type"`
+	BusinessID string          `json:"business_id"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// NewWebhookPayload encodes the body delivering a domain event as a webhook event type
+func NewWebhookPayload(event *DomainEvent, eventType string) (string, error) {
+	data := json.RawMessage("{}")
+	if event.Payload != nil && *event.Payload != "" {
+		data = json.RawMessage(*event.Payload)
+	}
+	payload := WebhookPayload{
+		ID:         event.ID,
+		Type:       eventType,
+		OccurredAt: event.OccurredAt,
+		Data:       data,
+	}
+	if event.BusinessID != nil {
+		payload.BusinessID = *event.BusinessID
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+// WebhookDeliveryStatus represents the state of a webhook delivery
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed" // Every attempt failed
+)
+
+// WebhookDelivery is an event to deliver to a webhook subscription, logging the outcome of the
+// last attempt. The body is fixed when the delivery is created, so retries send the same bytes.
+type WebhookDelivery struct {
+	BaseModel
+	BusinessID     string                `gorm:"not null;type:uuid;index" json:"business_id"`
+	SubscriptionID string                `gorm:"not null;type:uuid;uniqueIndex:idx_webhook_deliveries_subscription_event" json:"subscription_id"`
+	EventID        string                `gorm:"not null;type:uuid;uniqueIndex:idx_webhook_deliveries_subscription_event" json:"event_id"`
+	EventType      string                `gorm:"not null;size:100" json:"event_type"`
+	Payload        string                `gorm:"not null;type:text" json:"payload"`
+	Status         WebhookDeliveryStatus `gorm:"not null;size:20;default:'pending'" json:"status"`
+	Attempts       int                   `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt  time.Time             `gorm:"not null" json:"next_attempt_at"`
+	LastAttemptAt  *time.Time            `json:"last_attempt_at,omitempty"`
+	StatusCode     *int                  `json:"status_code,omitempty"` // Of the last response
+	LastError      *string               `gorm:"type:text" json:"last_error,omitempty"`
+	DurationMs     *int64                `json:"duration_ms,omitempty"` // Of the last attempt
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty"`
+}
+
+// TableName returns the table name for WebhookDelivery
+func (WebhookDelivery) TableName() string { return "webhook_deliveries" }
+
+// IsPending reports whether the delivery is still to be attempted
+func (d *WebhookDelivery) IsPending() bool {
+	return d.Status == WebhookDeliveryPending
+}
+
+// MarkDelivered records an attempt the endpoint acknowledged with a 2xx response
+func (d *WebhookDelivery) MarkDelivered(statusCode int, duration time.Duration, now time.Time) {
+	d.recordAttempt(&statusCode, duration, now)
+	d.Status = WebhookDeliveryDelivered
+	d.DeliveredAt = &now
+	d.LastError = nil
+}
+
+// RecordFailure records a failed attempt and schedules the next one, failing the delivery once
+// it has used up its attempts. statusCode is nil when no response was received.
+func (d *WebhookDelivery) RecordFailure(statusCode *int, err error, duration time.Duration, now time.Time) {
+	d.recordAttempt(statusCode, duration, now)
+	message := err.Error()
+	d.LastError = &message
+	if d.Attempts >= MaxWebhookAttempts {
+		d.Status = WebhookDeliveryFailed
+		return
+	}
+	d.NextAttemptAt = now.Add(WebhookRetryDelay(d.Attempts))
+}
+
+// Abandon fails the delivery without attempting it, e.g. once its subscription was deleted
+func (d *WebhookDelivery) Abandon(err error) {
+	message := err.Error()
+	d.LastError = &message
+	d.Status = WebhookDeliveryFailed
+}
+
+func (d *WebhookDelivery) recordAttempt(statusCode *int, duration time.Duration, now time.Time) {
+	d.Attempts++
+	d.LastAttemptAt = &now
+	d.StatusCode = statusCode
+	durationMs := duration.Milliseconds()
+	d.DurationMs = &durationMs
+}
+
+// WebhookRetryDelay returns the delay after a number of failed delivery attempts, doubling
+// from thirty seconds up to six hours
+func WebhookRetryDelay(attempts int) time.Duration {
+	delay := webhookRetryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= webhookRetryMaxDelay {
+			return webhookRetryMaxDelay
+		}
+	}
+	return delay
+}
+
+// WebhookRepository defines the repository interface for webhook subscriptions and their
+// deliveries
+type WebhookRepository interface {
+	BaseRepository[WebhookSubscription]
+	FindByBusiness(ctx context.Context, businessID string) ([]*WebhookSubscription, error)
+	// EnqueueDeliveries creates deliveries, skipping those already created for the same
+	// subscription and event, so that an event published again is not delivered twice
+	EnqueueDeliveries(ctx context.Context, deliveries []*WebhookDelivery) error
+	// FindDueDeliveries finds the pending deliveries whose next attempt is due, oldest first
+	FindDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, delivery *WebhookDelivery) error
+	// FindDeliveries finds the deliveries of a subscription, latest first
+	FindDeliveries(ctx context.Context, subscriptionID string, page, pageSize int) ([]*WebhookDelivery, int64, error)
+}
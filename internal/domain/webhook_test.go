@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newWebhookSubscription(t *testing.T, url string, eventTypes ...string) *WebhookSubscription {
+	t.Helper()
+	subscription := &WebhookSubscription{BusinessID: "business-1", URL: url, Secret: "whsec_test", IsActive: true}
+	require.NoError(t, subscription.SetEventTypes(eventTypes))
+	return subscription
+}
+
+func TestWebhookSubscription_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		url        string
+		eventTypes []string
+		wantErr    bool
+	}{
+		{"valid", "https://example.com/hooks", []string{WebhookEventAppointmentCreated}, false},
+		{"plain http", "http://example.com/hooks", []string{WebhookEventAppointmentCreated}, true},
+		{"relative URL", "/hooks", []string{WebhookEventAppointmentCreated}, true},
+		{"no event types", "https://example.com/hooks", nil, true},
+		{"unknown event type", "https://example.com/hooks", []string{"appointment.rescheduled"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newWebhookSubscription(t, tt.url, tt.eventTypes...).Validate()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrValidation)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWebhookSubscription_Receives(t *testing.T) {
+	subscription := newWebhookSubscription(t, "https://example.com/hooks",
+		WebhookEventClientCreated, WebhookEventClientCreated, WebhookEventAppointmentCancelled)
+
+	eventTypes, err := subscription.GetEventTypes()
+	require.NoError(t, err)
+	assert.Equal(t, []string{WebhookEventClientCreated, WebhookEventAppointmentCancelled}, eventTypes)
+
+	assert.True(t, subscription.Receives(WebhookEventClientCreated))
+	assert.False(t, subscription.Receives(WebhookEventAppointmentCreated))
+
+	subscription.IsActive = false
+	assert.False(t, subscription.Receives(WebhookEventClientCreated), "inactive subscriptions receive nothing")
+}
+
+func TestWebhookEventFor(t *testing.T) {
+	eventType, ok := WebhookEventFor(&DomainEvent{EventType: EventAppointmentCompleted})
+	assert.True(t, ok)
+	assert.Equal(t, WebhookEventServiceCompletionRecorded, eventType)
+
+	_, ok = WebhookEventFor(&DomainEvent{EventType: EventAppointmentUpdated})
+	assert.False(t, ok)
+}
+
+func TestSignWebhookPayload(t *testing.T) {
+	sentAt := time.Unix(1760000000, 0)
+	body := []byte(`{"id":"event-1"}`)
+
+	signature := SignWebhookPayload("whsec_test", sentAt, body)
+	assert.Equal(t, "t=1760000000,v1=9179007244440f1c0f5f76716de73aa26b771aa40b93cd800ddb243da5d0841b", signature)
+	assert.NotEqual(t, signature, SignWebhookPayload("whsec_other", sentAt, body))
+	assert.NotEqual(t, signature, SignWebhookPayload("whsec_test", sentAt.Add(time.Second), body))
+}
+
+func TestWebhookDelivery_RecordFailure(t *testing.T) {
+	now := time.Date(2026, time.October, 17, 9, 0, 0, 0, time.UTC)
+	delivery := &WebhookDelivery{Status: WebhookDeliveryPending, NextAttemptAt: now}
+	statusCode := 503
+
+	delivery.RecordFailure(&statusCode, errors.New("the endpoint responded with status 503"), time.Second, now)
+	assert.True(t, delivery.IsPending())
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Equal(t, now.Add(30*time.Second), delivery.NextAttemptAt)
+	assert.Equal(t, int64(1000), *delivery.DurationMs)
+
+	for delivery.Attempts < MaxWebhookAttempts {
+		delivery.RecordFailure(nil, errors.New("connection refused"), time.Second, now)
+	}
+	assert.Equal(t, WebhookDeliveryFailed, delivery.Status)
+	assert.Nil(t, delivery.StatusCode)
+	assert.Equal(t, "connection refused", *delivery.LastError)
+
+	assert.Equal(t, time.Minute, WebhookRetryDelay(2))
+	assert.Equal(t, 6*time.Hour, WebhookRetryDelay(20))
+}
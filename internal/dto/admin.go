@@ -0,0 +1,70 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// AdminBusinessSearchDTO represents the filter platform operators search the businesses of
+// every tenant with
+type AdminBusinessSearchDTO struct {
+	Query  string                   `json:"query,omitempty" validate:"max=255"`
+	Status *domain.BusinessStatus   `json:"status,omitempty" validate:"omitempty,oneof=active suspended inactive"`
+	Tier   *domain.SubscriptionTier `json:"tier,omitempty" validate:"omitempty,oneof=free pro premium"`
+}
+
+// SuspendBusinessDTO represents the data for suspending a business
+type SuspendBusinessDTO struct {
+	BusinessID string `json:"business_id" validate:"required,uuid"`
+	Reason     string `json:"reason" validate:"required,max=500"`
+}
+
+// AdminBusinessDTO represents a business as platform operators see it
+type AdminBusinessDTO struct {
+	BaseResponse
+	OwnerID          string                  `json:"owner_id"`
+	Name             string                  `json:"name"`
+	DisplayName      *string                 `json:"display_name,omitempty"`
+	Email            string                  `json:"email"`
+	Status           domain.BusinessStatus   `json:"status"`
+	Tier             domain.SubscriptionTier `json:"tier"`
+	TrialState       domain.TrialState       `json:"trial_state"`
+	TrialEndsAt      *time.Time              `json:"trial_ends_at,omitempty"`
+	SuspendedAt      *time.Time              `json:"suspended_at,omitempty"`
+	SuspensionReason *string                 `json:"suspension_reason,omitempty"`
+	DataRegion       domain.DataRegion       `json:"data_region"`
+	Mode             domain.BusinessMode     `json:"mode"`
+	IsSandbox        bool                    `json:"is_sandbox"`
+	IsTemplate       bool                    `json:"is_template"`
+}
+
+// ToAdminBusinessDTO converts a business to the DTO platform operators see at a time
+func ToAdminBusinessDTO(business *domain.Business, now time.Time) *AdminBusinessDTO {
+	adminDTO := &AdminBusinessDTO{
+		BaseResponse: BaseResponse{
+			ID:        business.ID,
+			CreatedAt: business.CreatedAt,
+			UpdatedAt: business.UpdatedAt,
+			Version:   business.Version,
+		},
+		OwnerID:          business.UserID,
+		Name:             business.Name,
+		DisplayName:      business.DisplayName,
+		Email:            business.Email,
+		Status:           business.Status(),
+		Tier:             business.Tier(),
+		TrialState:       business.TrialState(now),
+		TrialEndsAt:      business.TrialEndsAt,
+		SuspendedAt:      business.SuspendedAt,
+		SuspensionReason: business.SuspensionReason,
+		DataRegion:       business.DataRegion,
+		Mode:             business.GetMode(),
+		IsSandbox:        business.IsSandbox,
+		IsTemplate:       business.IsTemplate,
+	}
+	if adminDTO.DataRegion == "" {
+		adminDTO.DataRegion = domain.DefaultDataRegion
+	}
+	return adminDTO
+}
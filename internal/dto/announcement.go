@@ -0,0 +1,89 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// CreateAnnouncementDTO represents a product update the platform posts to businesses
+type CreateAnnouncementDTO struct {
+	Title     string     `json:"title" validate:"required,max=200"`
+	Body      string     `json:"body" validate:"required,max=10000"`
+	Link      *string    `json:"link,omitempty" validate:"omitempty,url,max=500"`
+	Plans     []string   `json:"plans,omitempty" validate:"omitempty,max=20,dive,required,max=50"`
+	Locales   []string   `json:"locales,omitempty" validate:"omitempty,max=50,dive,required,bcp47_language_tag"`
+	PublishAt *time.Time `json:"publish_at,omitempty"` // Defaults to now
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// MarkAnnouncementsReadDTO represents announcements a user has read
+type MarkAnnouncementsReadDTO struct {
+	AnnouncementIDs []string `json:"announcement_ids" validate:"required,min=1,max=100,dive,uuid"`
+}
+
+// AnnouncementResponseDTO represents an announcement as the platform authored it
+type AnnouncementResponseDTO struct {
+	BaseResponse
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	Link      *string    `json:"link,omitempty"`
+	Plans     []string   `json:"plans"`
+	Locales   []string   `json:"locales"`
+	PublishAt time.Time  `json:"publish_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// AnnouncementFeedItemDTO represents an announcement in a user's feed
+type AnnouncementFeedItemDTO struct {
+	ID        string     `json:"id"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	Link      *string    `json:"link,omitempty"`
+	PublishAt time.Time  `json:"publish_at"`
+	ReadAt    *time.Time `json:"read_at,omitempty"` // When the user read it
+}
+
+// AnnouncementFeedDTO represents the announcements shown to a user of a business, most
+// recently published first
+type AnnouncementFeedDTO struct {
+	Announcements []*AnnouncementFeedItemDTO `json:"announcements"`
+	UnreadCount   int                        `json:"unread_count"`
+}
+
+// ToAnnouncementResponseDTO converts an Announcement domain model to AnnouncementResponseDTO
+func ToAnnouncementResponseDTO(announcement *domain.Announcement) *AnnouncementResponseDTO {
+	if announcement == nil {
+		return nil
+	}
+
+	plans, _ := announcement.GetPlans()
+	locales, _ := announcement.GetLocales()
+	return &AnnouncementResponseDTO{
+		BaseResponse: BaseResponse{
+			ID:        announcement.ID,
+			CreatedAt: announcement.CreatedAt,
+			UpdatedAt: announcement.UpdatedAt,
+			Version:   announcement.Version,
+		},
+		Title:     announcement.Title,
+		Body:      announcement.Body,
+		Link:      announcement.Link,
+		Plans:     plans,
+		Locales:   locales,
+		PublishAt: announcement.PublishAt,
+		ExpiresAt: announcement.ExpiresAt,
+	}
+}
+
+// ToAnnouncementFeedItemDTO converts an announcement to a feed item, read at readAt if not nil
+func ToAnnouncementFeedItemDTO(announcement *domain.Announcement, readAt *time.Time) *AnnouncementFeedItemDTO {
+	return &AnnouncementFeedItemDTO{
+		ID:        announcement.ID,
+		Title:     announcement.Title,
+		Body:      announcement.Body,
+		Link:      announcement.Link,
+		PublishAt: announcement.PublishAt,
+		ReadAt:    readAt,
+	}
+}
@@ -0,0 +1,53 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// FeedbackLinkDTO represents the link a client opens to send anonymous feedback about an
+// appointment
+type FeedbackLinkDTO struct {
+	AppointmentID string    `json:"appointment_id"`
+	URL           string    `json:"url"`
+	ExpiresAt     time.Time `json:"expires_at"`
+}
+
+// FeedbackFormDTO represents what the feedback page shows a client
+type FeedbackFormDTO struct {
+	BusinessName string `json:"business_name"`
+}
+
+// SubmitAnonymousFeedbackDTO represents feedback a client sends with a feedback link
+type SubmitAnonymousFeedbackDTO struct {
+	Token   string `json:"token" validate:"required"`
+	Rating  *int   `json:"rating,omitempty" validate:"omitempty,min=1,max=5"`
+	Message string `json:"message" validate:"required"`
+}
+
+// AnonymousFeedbackDTO represents anonymous feedback in a business owner's inbox
+type AnonymousFeedbackDTO struct {
+	ID          string     `json:"id"`
+	Rating      *int       `json:"rating,omitempty"`
+	Message     string     `json:"message"`
+	SubmittedOn time.Time  `json:"submitted_on"`
+	ReadAt      *time.Time `json:"read_at,omitempty"`
+}
+
+// MarkAnonymousFeedbackReadDTO represents feedback an owner has read
+type MarkAnonymousFeedbackReadDTO struct {
+	BusinessID  string   `json:"business_id" validate:"required,uuid"`
+	FeedbackIDs []string `json:"feedback_ids" validate:"required,min=1,max=100,dive,uuid"`
+}
+
+// ToAnonymousFeedbackDTO converts anonymous feedback to a DTO
+func ToAnonymousFeedbackDTO(feedback *domain.AnonymousFeedback) *AnonymousFeedbackDTO {
+	return &AnonymousFeedbackDTO{
+		ID:          feedback.ID,
+		Rating:      feedback.Rating,
+		Message:     feedback.Message,
+		SubmittedOn: feedback.SubmittedOn,
+		ReadAt:      feedback.ReadAt,
+	}
+}
@@ -0,0 +1,136 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// CreateAppointmentDTO represents the data for booking an appointment
+type CreateAppointmentDTO struct {
+	BusinessID string    `json:"business_id" validate:"required,uuid"`
+	ClientID   string    `json:"client_id" validate:"required,uuid"`
+	StaffID    string    `json:"staff_id" validate:"omitempty,uuid"` // Required unless the business is solo
+	StartTime  time.Time `json:"start_time" validate:"required"`
+	EndTime    time.Time `json:"end_time" validate:"required,gtfield=StartTime"`
+	Notes      *string   `json:"notes,omitempty" validate:"omitempty,max=2000"`
+	// QuoteToken books the appointment at the price of a quote for it, see QuoteAppointmentDTO
+	QuoteToken *string `json:"quote_token,omitempty" validate:"omitempty,len=32,hexadecimal"`
+}
+
+// RescheduleAppointmentDTO represents the data for moving an appointment to another time or staff member
+type RescheduleAppointmentDTO struct {
+	StartTime time.Time `json:"start_time" validate:"required"`
+	EndTime   time.Time `json:"end_time" validate:"required,gtfield=StartTime"`
+	StaffID   *string   `json:"staff_id,omitempty" validate:"omitempty,uuid"`
+	// ExpectedVersion is the version of the appointment the change is based on; the change is
+	// turned down when someone else changed the appointment since
+	ExpectedVersion *int `json:"expected_version,omitempty" validate:"omitempty,min=1"`
+}
+
+// CancelAppointmentDTO represents the data for cancelling an appointment
+type CancelAppointmentDTO struct {
+	Reason          string `json:"reason" validate:"max=500"`
+	ExpectedVersion *int   `json:"expected_version,omitempty" validate:"omitempty,min=1"` // See RescheduleAppointmentDTO
+	// WaiveNoShowPolicy cancels without charging a late cancellation as a no-show; owners and
+	// managers only
+	WaiveNoShowPolicy bool `json:"waive_no_show_policy,omitempty"`
+}
+
+// CheckAvailabilityDTO represents a question whether a staff member can take an appointment
+type CheckAvailabilityDTO struct {
+	BusinessID           string    `json:"business_id" validate:"required,uuid"`
+	StaffID              string    `json:"staff_id" validate:"omitempty,uuid"` // Required unless the business is solo
+	StartTime            time.Time `json:"start_time" validate:"required"`
+	EndTime              time.Time `json:"end_time" validate:"required,gtfield=StartTime"`
+	ExcludeAppointmentID *string   `json:"exclude_appointment_id,omitempty" validate:"omitempty,uuid"`
+	ServiceIDs           []string  `json:"service_ids,omitempty" validate:"omitempty,dive,uuid"` // Whose resources the appointment would occupy
+}
+
+// AvailabilityConflictDTO represents one reason a time slot is not available
+type AvailabilityConflictDTO struct {
+	Reason        domain.ConflictReason `json:"reason"`
+	Message       string                `json:"message"`
+	AppointmentID *string               `json:"appointment_id,omitempty"`
+	ExceptionID   *string               `json:"exception_id,omitempty"`
+	ResourceID    *string               `json:"resource_id,omitempty"`
+	Start         *time.Time            `json:"start,omitempty"`
+	End           *time.Time            `json:"end,omitempty"`
+}
+
+// AvailabilityResultDTO represents whether a time slot is available
+type AvailabilityResultDTO struct {
+	Available bool                       `json:"available"`
+	Conflicts []*AvailabilityConflictDTO `json:"conflicts"`
+}
+
+// AppointmentResponseDTO represents the response data for an appointment
+type AppointmentResponseDTO struct {
+	BaseResponse
+	BusinessID      string                   `json:"business_id"`
+	ClientID        string                   `json:"client_id"`
+	StaffID         string                   `json:"staff_id"`
+	StartTime       time.Time                `json:"start_time"`
+	EndTime         time.Time                `json:"end_time"`
+	Status          domain.AppointmentStatus `json:"status"`
+	Notes           *string                  `json:"notes,omitempty"`
+	BundleID        *string                  `json:"bundle_id,omitempty"`
+	EstimatedPrice  *decimal.Decimal         `json:"estimated_price,omitempty"`
+	BroadcastID     *string                  `json:"broadcast_id,omitempty"`
+	ClientConfirmed bool                     `json:"client_confirmed"`
+	NoShowFee       *decimal.Decimal         `json:"no_show_fee,omitempty"`
+}
+
+// ToAppointmentResponseDTO converts an Appointment domain model to AppointmentResponseDTO
+func ToAppointmentResponseDTO(appointment *domain.Appointment) *AppointmentResponseDTO {
+	if appointment == nil {
+		return nil
+	}
+
+	return &AppointmentResponseDTO{
+		BaseResponse: BaseResponse{
+			ID:        appointment.ID,
+			CreatedAt: appointment.CreatedAt,
+			UpdatedAt: appointment.UpdatedAt,
+			Version:   appointment.Version,
+		},
+		BusinessID:      appointment.BusinessID,
+		ClientID:        appointment.ClientID,
+		StaffID:         appointment.StaffID,
+		StartTime:       appointment.StartTime,
+		EndTime:         appointment.EndTime,
+		Status:          appointment.Status,
+		Notes:           appointment.Notes,
+		BundleID:        appointment.BundleID,
+		EstimatedPrice:  appointment.EstimatedPrice,
+		BroadcastID:     appointment.BroadcastID,
+		ClientConfirmed: appointment.ClientConfirmed,
+		NoShowFee:       appointment.NoShowFee,
+	}
+}
+
+// ToAvailabilityResultDTO converts availability conflicts to AvailabilityResultDTO
+func ToAvailabilityResultDTO(conflicts []*domain.AvailabilityConflict) *AvailabilityResultDTO {
+	return &AvailabilityResultDTO{
+		Available: len(conflicts) == 0,
+		Conflicts: ToAvailabilityConflictDTOs(conflicts),
+	}
+}
+
+// ToAvailabilityConflictDTOs converts availability conflicts to AvailabilityConflictDTOs
+func ToAvailabilityConflictDTOs(conflicts []*domain.AvailabilityConflict) []*AvailabilityConflictDTO {
+	result := make([]*AvailabilityConflictDTO, len(conflicts))
+	for i, conflict := range conflicts {
+		result[i] = &AvailabilityConflictDTO{
+			Reason:        conflict.Reason,
+			Message:       conflict.Message,
+			AppointmentID: conflict.AppointmentID,
+			ExceptionID:   conflict.ExceptionID,
+			ResourceID:    conflict.ResourceID,
+			Start:         conflict.Start,
+			End:           conflict.End,
+		}
+	}
+	return result
+}
@@ -0,0 +1,118 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// UnconfirmedAppointmentDTO represents an upcoming appointment staff may need to chase
+type UnconfirmedAppointmentDTO struct {
+	AppointmentID  string                     `json:"appointment_id"`
+	StaffID        string                     `json:"staff_id"`
+	ClientID       string                     `json:"client_id"`
+	StartTime      time.Time                  `json:"start_time"`
+	EndTime        time.Time                  `json:"end_time"`
+	Status         domain.AppointmentStatus   `json:"status"`
+	ClientName     string                     `json:"client_name"`
+	ClientPhone    *string                    `json:"client_phone,omitempty"`
+	ClientEmail    *string                    `json:"client_email,omitempty"`
+	StaffName      string                     `json:"staff_name"`
+	RequestStatus  *domain.ConfirmationStatus `json:"request_status,omitempty"`
+	RequestChannel *domain.MessageChannel     `json:"request_channel,omitempty"`
+	RequestSentAt  *time.Time                 `json:"request_sent_at,omitempty"`
+}
+
+// ConfirmationRunDTO summarizes a run of the confirmation request sender
+type ConfirmationRunDTO struct {
+	Due    int `json:"due"`
+	Sent   int `json:"sent"`
+	Failed int `json:"failed"`
+}
+
+// EscalationRunDTO summarizes a run escalating unacknowledged confirmation requests
+type EscalationRunDTO struct {
+	Due    int `json:"due"`
+	Resent int `json:"resent"` // Sent again through another channel
+	Tasks  int `json:"tasks"`  // Handed to the front desk to call the client
+	Failed int `json:"failed"`
+}
+
+// ConfirmationResultDTO represents the appointment of a confirmation link
+type ConfirmationResultDTO struct {
+	AppointmentID    string    `json:"appointment_id"`
+	BusinessName     string    `json:"business_name"`
+	StartTime        time.Time `json:"start_time"` // In the business time zone
+	AlreadyConfirmed bool      `json:"already_confirmed"`
+}
+
+// ToUnconfirmedAppointmentDTOs converts UnconfirmedAppointment read models to DTOs
+func ToUnconfirmedAppointmentDTOs(appointments []*domain.UnconfirmedAppointment) []*UnconfirmedAppointmentDTO {
+	result := make([]*UnconfirmedAppointmentDTO, len(appointments))
+	for i, appointment := range appointments {
+		result[i] = &UnconfirmedAppointmentDTO{
+			AppointmentID:  appointment.AppointmentID,
+			StaffID:        appointment.StaffID,
+			ClientID:       appointment.ClientID,
+			StartTime:      appointment.StartTime,
+			EndTime:        appointment.EndTime,
+			Status:         appointment.Status,
+			ClientName:     appointment.ClientName,
+			ClientPhone:    appointment.ClientPhone,
+			ClientEmail:    appointment.ClientEmail,
+			StaffName:      appointment.StaffName,
+			RequestStatus:  appointment.RequestStatus,
+			RequestChannel: appointment.RequestChannel,
+			RequestSentAt:  appointment.RequestSentAt,
+		}
+	}
+	return result
+}
+
+// ReminderAttemptDTO represents a confirmation request of an appointment's reminder chain
+type ReminderAttemptDTO struct {
+	Attempt        int                        `json:"attempt"`
+	Channel        domain.MessageChannel      `json:"channel"`
+	Recipient      string                     `json:"recipient"`
+	Status         domain.ConfirmationStatus  `json:"status"`
+	SentAt         time.Time                  `json:"sent_at"`
+	DeliveredAt    *time.Time                 `json:"delivered_at,omitempty"`
+	DeliveryError  *string                    `json:"delivery_error,omitempty"`
+	RespondedAt    *time.Time                 `json:"responded_at,omitempty"`
+	ResponseSource *domain.ConfirmationSource `json:"response_source,omitempty"`
+	EscalatedAt    *time.Time                 `json:"escalated_at,omitempty"`
+}
+
+// ReminderChainDTO represents the confirmation requests sent about an appointment and the
+// front desk tasks they were escalated to
+type ReminderChainDTO struct {
+	AppointmentID string                `json:"appointment_id"`
+	Acknowledged  bool                  `json:"acknowledged"` // Whether the appointment was confirmed
+	Attempts      []*ReminderAttemptDTO `json:"attempts"`
+	Tasks         []*FrontDeskTaskDTO   `json:"tasks"`
+}
+
+// ToReminderChainDTO converts an appointment's confirmation requests and tasks to a DTO
+func ToReminderChainDTO(appointment *domain.Appointment, confirmations []*domain.AppointmentConfirmation, tasks []*domain.FrontDeskTask) *ReminderChainDTO {
+	chain := &ReminderChainDTO{
+		AppointmentID: appointment.ID,
+		Acknowledged:  appointment.ClientConfirmed,
+		Attempts:      make([]*ReminderAttemptDTO, len(confirmations)),
+		Tasks:         ToFrontDeskTaskDTOs(tasks),
+	}
+	for i, confirmation := range confirmations {
+		chain.Attempts[i] = &ReminderAttemptDTO{
+			Attempt:        confirmation.Attempt,
+			Channel:        confirmation.Channel,
+			Recipient:      confirmation.Recipient,
+			Status:         confirmation.Status,
+			SentAt:         confirmation.SentAt,
+			DeliveredAt:    confirmation.DeliveredAt,
+			DeliveryError:  confirmation.DeliveryError,
+			RespondedAt:    confirmation.RespondedAt,
+			ResponseSource: confirmation.ResponseSource,
+			EscalatedAt:    confirmation.EscalatedAt,
+		}
+	}
+	return chain
+}
@@ -0,0 +1,62 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// QuoteAppointmentDTO represents the data for pricing an appointment before booking it
+type QuoteAppointmentDTO struct {
+	BusinessID      string    `json:"business_id" validate:"required,uuid"`
+	ClientID        string    `json:"client_id" validate:"required,uuid"`
+	StaffID         string    `json:"staff_id" validate:"omitempty,uuid"` // Required unless the business is solo
+	StartTime       time.Time `json:"start_time" validate:"required"`
+	ServiceIDs      []string  `json:"service_ids" validate:"required,min=1,max=10,unique,dive,uuid"`
+	AddOnServiceIDs []string  `json:"add_on_service_ids,omitempty" validate:"omitempty,max=9,unique,dive,uuid"`
+}
+
+// AppointmentQuoteDTO represents a priced appointment and the token booking it at that price
+type AppointmentQuoteDTO struct {
+	Token       string                   `json:"token"`
+	ExpiresAt   time.Time                `json:"expires_at"`
+	BusinessID  string                   `json:"business_id"`
+	ClientID    string                   `json:"client_id"`
+	StaffID     string                   `json:"staff_id"`
+	StartTime   time.Time                `json:"start_time"`
+	EndTime     time.Time                `json:"end_time"`
+	Lines       []domain.QuoteLine       `json:"lines"`
+	BasePrice   decimal.Decimal          `json:"base_price"`
+	Adjustments decimal.Decimal          `json:"adjustments"`
+	Subtotal    decimal.Decimal          `json:"subtotal"`
+	Promotion   *domain.QuotePromotion   `json:"promotion,omitempty"`
+	Total       decimal.Decimal          `json:"total"`
+	DepositDue  decimal.Decimal          `json:"deposit_due"`
+	Redemptions []domain.QuoteRedemption `json:"redemptions"`
+}
+
+// ToAppointmentQuoteDTO converts a quote and its breakdown to an AppointmentQuoteDTO
+func ToAppointmentQuoteDTO(quote *domain.AppointmentQuote, breakdown *domain.QuoteBreakdown) *AppointmentQuoteDTO {
+	redemptions := breakdown.Redemptions
+	if redemptions == nil {
+		redemptions = []domain.QuoteRedemption{}
+	}
+	return &AppointmentQuoteDTO{
+		Token:       quote.Token,
+		ExpiresAt:   quote.ExpiresAt,
+		BusinessID:  quote.BusinessID,
+		ClientID:    quote.ClientID,
+		StaffID:     quote.StaffID,
+		StartTime:   quote.StartTime,
+		EndTime:     quote.EndTime,
+		Lines:       breakdown.Lines,
+		BasePrice:   breakdown.BasePrice,
+		Adjustments: breakdown.Adjustments,
+		Subtotal:    breakdown.Subtotal,
+		Promotion:   breakdown.Promotion,
+		Total:       breakdown.Total,
+		DepositDue:  breakdown.DepositDue,
+		Redemptions: redemptions,
+	}
+}
@@ -0,0 +1,80 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// AuditLogFilterDTO represents the criteria for browsing the audit log of a business
+type AuditLogFilterDTO struct {
+	BusinessID string     `json:"business_id" validate:"required,uuid"`
+	EntityType *string    `json:"entity_type,omitempty" validate:"omitempty,max=100"`
+	EntityID   *string    `json:"entity_id,omitempty" validate:"omitempty,max=100"`
+	ActorID    *string    `json:"actor_id,omitempty" validate:"omitempty,uuid"`
+	Action     *string    `json:"action,omitempty" validate:"omitempty,oneof=create update delete restore"`
+	StartDate  *time.Time `json:"start_date,omitempty"`
+	EndDate    *time.Time `json:"end_date,omitempty"`
+}
+
+// AuditLogResponseDTO represents the response data for an audit log entry
+type AuditLogResponseDTO struct {
+	ID         string         `json:"id"`
+	BusinessID *string        `json:"business_id,omitempty"`
+	EntityType string         `json:"entity_type"`
+	EntityID   string         `json:"entity_id"`
+	Action     string         `json:"action"`
+	Changes    map[string]any `json:"changes"` // Field name to its value before and after
+	ActorID    *string        `json:"actor_id,omitempty"`
+	OccurredAt time.Time      `json:"occurred_at"`
+}
+
+// ToAuditLogFilter converts an AuditLogFilterDTO to a domain filter
+func ToAuditLogFilter(filterDTO AuditLogFilterDTO) domain.AuditLogFilter {
+	filter := domain.AuditLogFilter{
+		BusinessID: filterDTO.BusinessID,
+		EntityType: filterDTO.EntityType,
+		EntityID:   filterDTO.EntityID,
+		ActorID:    filterDTO.ActorID,
+		StartDate:  filterDTO.StartDate,
+		EndDate:    filterDTO.EndDate,
+	}
+	if filterDTO.Action != nil {
+		action := domain.AuditAction(*filterDTO.Action)
+		filter.Action = &action
+	}
+	return filter
+}
+
+// ToAuditLogResponseDTO converts an AuditLog domain model to AuditLogResponseDTO
+func ToAuditLogResponseDTO(entry *domain.AuditLog) *AuditLogResponseDTO {
+	if entry == nil {
+		return nil
+	}
+
+	changes := map[string]any{}
+	if entry.Changes != nil {
+		_ = json.Unmarshal([]byte(*entry.Changes), &changes)
+	}
+
+	return &AuditLogResponseDTO{
+		ID:         entry.ID,
+		BusinessID: entry.BusinessID,
+		EntityType: entry.EntityType,
+		EntityID:   entry.EntityID,
+		Action:     string(entry.Action),
+		Changes:    changes,
+		ActorID:    entry.ActorID,
+		OccurredAt: entry.OccurredAt,
+	}
+}
+
+// ToAuditLogResponseDTOs converts a slice of AuditLog domain models to DTOs
+func ToAuditLogResponseDTOs(entries []*domain.AuditLog) []*AuditLogResponseDTO {
+	result := make([]*AuditLogResponseDTO, len(entries))
+	for i, entry := range entries {
+		result[i] = ToAuditLogResponseDTO(entry)
+	}
+	return result
+}
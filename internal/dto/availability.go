@@ -0,0 +1,19 @@
+package dto
+
+import "time"
+
+// AvailableSlotsQueryDTO represents a search for the times a service can be booked
+type AvailableSlotsQueryDTO struct {
+	BusinessID string  `json:"business_id" validate:"required,uuid"`
+	ServiceID  string  `json:"service_id" validate:"required,uuid"`
+	StaffID    *string `json:"staff_id,omitempty" validate:"omitempty,uuid"` // Any active staff member when nil
+	StartDate  string  `json:"start_date" validate:"required"`               // YYYY-MM-DD in the business's time zone
+	EndDate    string  `json:"end_date" validate:"required"`                 // Inclusive, YYYY-MM-DD in the business's time zone
+}
+
+// AvailableSlotDTO represents a time at which a staff member can take the service
+type AvailableSlotDTO struct {
+	StaffID   string    `json:"staff_id"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+}
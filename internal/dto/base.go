@@ -32,6 +32,12 @@ type BaseResponse struct {
 	ID        string    `json:"id"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	Version   int       `json:"version"` // Sent back with updates to detect concurrent changes
+}
+
+// GetBaseResponse returns the common response fields of an embedding DTO
+func (b BaseResponse) GetBaseResponse() BaseResponse {
+	return b
 }
 
 // ListResponse represents a paginated list response
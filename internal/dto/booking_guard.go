@@ -0,0 +1,65 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// CreateBlocklistEntryDTO represents the data for blocklisting a phone, email or client
+type CreateBlocklistEntryDTO struct {
+	BusinessID string                    `json:"business_id" validate:"required,uuid"`
+	EntryType  domain.BlocklistEntryType `json:"entry_type" validate:"required,oneof=phone email client"`
+	Value      string                    `json:"value" validate:"required,max=255"`
+	Reason     *string                   `json:"reason,omitempty" validate:"omitempty,max=1000"`
+	ExpiresAt  *time.Time                `json:"expires_at,omitempty"`
+}
+
+// BlocklistEntryResponseDTO represents the response data for a blocklist entry
+type BlocklistEntryResponseDTO struct {
+	BaseResponse
+	BusinessID string                    `json:"business_id"`
+	EntryType  domain.BlocklistEntryType `json:"entry_type"`
+	Value      string                    `json:"value"`
+	Reason     *string                   `json:"reason,omitempty"`
+	ExpiresAt  *time.Time                `json:"expires_at,omitempty"`
+	IsActive   bool                      `json:"is_active"`
+}
+
+// BookingDecisionDTO represents whether an online booking attempt may proceed
+type BookingDecisionDTO struct {
+	Allowed bool                  `json:"allowed"`
+	Outcome domain.BookingOutcome `json:"outcome"`
+	Message string                `json:"message,omitempty"` // Safe to show to the booker
+}
+
+// ToBlocklistEntryResponseDTO converts a BlocklistEntry domain model to BlocklistEntryResponseDTO
+func ToBlocklistEntryResponseDTO(entry *domain.BlocklistEntry) *BlocklistEntryResponseDTO {
+	if entry == nil {
+		return nil
+	}
+
+	return &BlocklistEntryResponseDTO{
+		BaseResponse: BaseResponse{
+			ID:        entry.ID,
+			CreatedAt: entry.CreatedAt,
+			UpdatedAt: entry.UpdatedAt,
+			Version:   entry.Version,
+		},
+		BusinessID: entry.BusinessID,
+		EntryType:  entry.EntryType,
+		Value:      entry.Value,
+		Reason:     entry.Reason,
+		ExpiresAt:  entry.ExpiresAt,
+		IsActive:   entry.IsActive(time.Now()),
+	}
+}
+
+// ToBlocklistEntryResponseDTOs converts a slice of BlocklistEntry domain models to DTOs
+func ToBlocklistEntryResponseDTOs(entries []*domain.BlocklistEntry) []*BlocklistEntryResponseDTO {
+	result := make([]*BlocklistEntryResponseDTO, len(entries))
+	for i, entry := range entries {
+		result[i] = ToBlocklistEntryResponseDTO(entry)
+	}
+	return result
+}
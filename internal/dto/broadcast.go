@@ -0,0 +1,105 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// SendBroadcastDTO represents an urgent notice to the clients with appointments in a window
+type SendBroadcastDTO struct {
+	BusinessID      string    `json:"business_id" validate:"required,uuid"`
+	Subject         *string   `json:"subject,omitempty" validate:"omitempty,max=200"`
+	Message         string    `json:"message" validate:"required,max=2000"`
+	WindowStart     time.Time `json:"window_start" validate:"required"`
+	WindowEnd       time.Time `json:"window_end" validate:"required,gtfield=WindowStart"`
+	OfferReschedule bool      `json:"offer_reschedule"`
+}
+
+// BroadcastPreviewDTO represents who a broadcast would reach
+type BroadcastPreviewDTO struct {
+	AppointmentCount int `json:"appointment_count"`
+	ReachableCount   int `json:"reachable_count"`
+	UnreachableCount int `json:"unreachable_count"` // Clients without a phone number or email address
+}
+
+// BroadcastRecipientDTO represents an appointment affected by a broadcast
+type BroadcastRecipientDTO struct {
+	AppointmentID string                          `json:"appointment_id"`
+	ClientID      string                          `json:"client_id"`
+	Status        domain.BroadcastRecipientStatus `json:"status"`
+	Channels      []domain.MessageChannel         `json:"channels"`
+	Error         *string                         `json:"error,omitempty"`
+	RescheduledAt *time.Time                      `json:"rescheduled_at,omitempty"`
+}
+
+// BroadcastResponseDTO represents the response data for a broadcast
+type BroadcastResponseDTO struct {
+	BaseResponse
+	BusinessID       string                   `json:"business_id"`
+	Subject          *string                  `json:"subject,omitempty"`
+	Message          string                   `json:"message"`
+	WindowStart      time.Time                `json:"window_start"`
+	WindowEnd        time.Time                `json:"window_end"`
+	OfferReschedule  bool                     `json:"offer_reschedule"`
+	AppointmentCount int                      `json:"appointment_count"`
+	NotifiedCount    int                      `json:"notified_count"`
+	FailedCount      int                      `json:"failed_count"`
+	RescheduledCount int                      `json:"rescheduled_count"`
+	SentAt           *time.Time               `json:"sent_at,omitempty"`
+	Recipients       []*BroadcastRecipientDTO `json:"recipients,omitempty"`
+}
+
+// RescheduleRequestDTO represents the appointment behind a rescheduling link
+type RescheduleRequestDTO struct {
+	AppointmentID string    `json:"appointment_id"`
+	BusinessName  string    `json:"business_name"`
+	TimeZone      string    `json:"time_zone"`
+	StartTime     time.Time `json:"start_time"` // In the business time zone
+	Duration      int       `json:"duration"`   // Minutes
+	Rescheduled   bool      `json:"rescheduled"`
+}
+
+// ToBroadcastResponseDTO converts a Broadcast domain model and its recipients to BroadcastResponseDTO.
+// Recipients are omitted when nil.
+func ToBroadcastResponseDTO(broadcast *domain.Broadcast, recipients []*domain.BroadcastRecipient) *BroadcastResponseDTO {
+	if broadcast == nil {
+		return nil
+	}
+
+	response := &BroadcastResponseDTO{
+		BaseResponse: BaseResponse{
+			ID:        broadcast.ID,
+			CreatedAt: broadcast.CreatedAt,
+			UpdatedAt: broadcast.UpdatedAt,
+			Version:   broadcast.Version,
+		},
+		BusinessID:       broadcast.BusinessID,
+		Subject:          broadcast.Subject,
+		Message:          broadcast.Message,
+		WindowStart:      broadcast.WindowStart,
+		WindowEnd:        broadcast.WindowEnd,
+		OfferReschedule:  broadcast.OfferReschedule,
+		AppointmentCount: broadcast.AppointmentCount,
+		NotifiedCount:    broadcast.NotifiedCount,
+		FailedCount:      broadcast.FailedCount,
+		SentAt:           broadcast.SentAt,
+	}
+	if recipients != nil {
+		response.Recipients = make([]*BroadcastRecipientDTO, len(recipients))
+		for i, recipient := range recipients {
+			if recipient.RescheduledAt != nil {
+				response.RescheduledCount++
+			}
+			response.Recipients[i] = &BroadcastRecipientDTO{
+				AppointmentID: recipient.AppointmentID,
+				ClientID:      recipient.ClientID,
+				Status:        recipient.Status,
+				Channels:      recipient.GetChannels(),
+				Error:         recipient.Error,
+				RescheduledAt: recipient.RescheduledAt,
+			}
+		}
+	}
+	return response
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/assimoes/beautix/internal/domain"
+	"github.com/shopspring/decimal"
 )
 
 // CreateBusinessDTO represents the data for creating a business
@@ -16,21 +17,22 @@ type CreateBusinessDTO struct {
 	Website       *string `json:"website,omitempty" validate:"omitempty,url"`
 	Currency      string  `json:"currency" validate:"required,currency"`
 	TimeZone      string  `json:"time_zone" validate:"required"`
+	Mode          domain.BusinessMode `json:"mode,omitempty" validate:"omitempty,oneof=team solo"` // Team when omitted
 }
 
 // UpdateBusinessDTO represents the data for updating a business
 type UpdateBusinessDTO struct {
-	Name          *string `json:"name,omitempty" validate:"omitempty,min=2,max=100"`
-	DisplayName   *string `json:"display_name,omitempty" validate:"omitempty,min=2,max=100"`
-	BusinessType  *string `json:"business_type,omitempty" validate:"omitempty,max=50"`
-	TaxID         *string `json:"tax_id,omitempty" validate:"omitempty,max=50"`
-	Email         *string `json:"email,omitempty" validate:"omitempty,email"`
-	Website       *string `json:"website,omitempty" validate:"omitempty,url"`
-	LogoURL       *string `json:"logo_url,omitempty" validate:"omitempty,url"`
-	CoverPhotoURL *string `json:"cover_photo_url,omitempty" validate:"omitempty,url"`
-	Currency      *string `json:"currency,omitempty" validate:"omitempty,currency"`
-	TimeZone      *string `json:"time_zone,omitempty"`
-	IsActive      *bool   `json:"is_active,omitempty"`
+	Name          *string          `json:"name,omitempty" validate:"omitempty,min=2,max=100"`
+	DisplayName   Optional[string] `json:"display_name" validate:"omitempty,min=2,max=100"`
+	BusinessType  Optional[string] `json:"business_type" validate:"omitempty,max=50"`
+	TaxID         Optional[string] `json:"tax_id" validate:"omitempty,max=50"`
+	Email         *string          `json:"email,omitempty" validate:"omitempty,email"`
+	Website       Optional[string] `json:"website" validate:"omitempty,url"`
+	LogoURL       Optional[string] `json:"logo_url" validate:"omitempty,url"`
+	CoverPhotoURL Optional[string] `json:"cover_photo_url" validate:"omitempty,url"`
+	Currency      *string          `json:"currency,omitempty" validate:"omitempty,currency"`
+	TimeZone      *string          `json:"time_zone,omitempty"`
+	IsActive      *bool            `json:"is_active,omitempty"`
 }
 
 // BusinessResponseDTO represents the response data for a business
@@ -51,9 +53,22 @@ type BusinessResponseDTO struct {
 	IsActive         bool      `json:"is_active"`
 	SubscriptionTier string    `json:"subscription_tier"`
 	TrialEndsAt      *time.Time `json:"trial_ends_at,omitempty"`
+	DataRegion       domain.DataRegion `json:"data_region"`
+	Mode             domain.BusinessMode `json:"mode"`
+	IsSandbox        bool      `json:"is_sandbox"`
+	IsTemplate       bool      `json:"is_template"`
+	AverageRating    *decimal.Decimal `json:"average_rating,omitempty"`
+	ReviewCount      int       `json:"review_count"`
+	Capabilities     []domain.Capability `json:"capabilities"`
 	DisplayNameValue string    `json:"display_name_value"`
 }
 
+// SetBusinessModeDTO represents the data for changing the way a business is run
+type SetBusinessModeDTO struct {
+	BusinessID string              `json:"business_id" validate:"required,uuid"`
+	Mode       domain.BusinessMode `json:"mode" validate:"required,oneof=team solo"`
+}
+
 // BusinessWithLocationsDTO represents a business with its locations
 type BusinessWithLocationsDTO struct {
 	BusinessResponseDTO
@@ -76,17 +91,17 @@ type CreateLocationDTO struct {
 
 // UpdateLocationDTO represents the data for updating a business location
 type UpdateLocationDTO struct {
-	Name       *string `json:"name,omitempty" validate:"omitempty,min=2,max=100"`
-	Address    *string `json:"address,omitempty" validate:"omitempty,max=255"`
-	City       *string `json:"city,omitempty" validate:"omitempty,max=100"`
-	State      *string `json:"state,omitempty" validate:"omitempty,max=100"`
-	PostalCode *string `json:"postal_code,omitempty" validate:"omitempty,max=20"`
-	Country    *string `json:"country,omitempty" validate:"omitempty,max=50"`
-	Phone      *string `json:"phone,omitempty" validate:"omitempty,min=10,max=20"`
-	Email      *string `json:"email,omitempty" validate:"omitempty,email"`
-	Timezone   *string `json:"timezone,omitempty"`
-	IsActive   *bool   `json:"is_active,omitempty"`
-	IsMain     *bool   `json:"is_main,omitempty"`
+	Name       *string          `json:"name,omitempty" validate:"omitempty,min=2,max=100"`
+	Address    Optional[string] `json:"address" validate:"omitempty,max=255"`
+	City       Optional[string] `json:"city" validate:"omitempty,max=100"`
+	State      Optional[string] `json:"state" validate:"omitempty,max=100"`
+	PostalCode Optional[string] `json:"postal_code" validate:"omitempty,max=20"`
+	Country    *string          `json:"country,omitempty" validate:"omitempty,max=50"`
+	Phone      Optional[string] `json:"phone" validate:"omitempty,min=10,max=20"`
+	Email      Optional[string] `json:"email" validate:"omitempty,email"`
+	Timezone   *string          `json:"timezone,omitempty"`
+	IsActive   *bool            `json:"is_active,omitempty"`
+	IsMain     *bool            `json:"is_main,omitempty"`
 }
 
 // LocationResponseDTO represents the response data for a business location
@@ -199,6 +214,7 @@ func ToBusinessResponseDTO(business *domain.Business) *BusinessResponseDTO {
 			ID:        business.ID,
 			CreatedAt: business.CreatedAt,
 			UpdatedAt: business.UpdatedAt,
+			Version:   business.Version,
 		},
 		UserID:           business.UserID,
 		Name:             business.Name,
@@ -215,6 +231,13 @@ func ToBusinessResponseDTO(business *domain.Business) *BusinessResponseDTO {
 		IsActive:         business.IsActive,
 		SubscriptionTier: business.SubscriptionTier,
 		TrialEndsAt:      business.TrialEndsAt,
+		DataRegion:       business.DataRegion,
+		Mode:             business.GetMode(),
+		IsSandbox:        business.IsSandbox,
+		IsTemplate:       business.IsTemplate,
+		AverageRating:    business.AverageRating,
+		ReviewCount:      business.ReviewCount,
+		Capabilities:     business.Capabilities(),
 		DisplayNameValue: business.GetDisplayName(),
 	}
 }
@@ -239,6 +262,7 @@ func ToLocationResponseDTO(location *domain.BusinessLocation) *LocationResponseD
 			ID:        location.ID,
 			CreatedAt: location.CreatedAt,
 			UpdatedAt: location.UpdatedAt,
+			Version:   location.Version,
 		},
 		BusinessID: location.BusinessID,
 		Name:       location.Name,
@@ -275,6 +299,7 @@ func ToSettingsResponseDTO(settings *domain.BusinessSettings) *SettingsResponseD
 			ID:        settings.ID,
 			CreatedAt: settings.CreatedAt,
 			UpdatedAt: settings.UpdatedAt,
+			Version:   settings.Version,
 		},
 		BusinessID:                   settings.BusinessID,
 		CalendarStartHour:            settings.CalendarStartHour,
@@ -323,20 +348,23 @@ type CreateStaffDTO struct {
 type UpdateStaffDTO struct {
 	Role        *domain.BusinessRole `json:"role,omitempty" validate:"omitempty,business_role"`
 	IsActive    *bool                `json:"is_active,omitempty"`
-	Permissions *string              `json:"permissions,omitempty"`
-	EndDate     *time.Time           `json:"end_date,omitempty"`
+	Permissions Optional[string]     `json:"permissions"`
+	EndDate     Optional[time.Time]  `json:"end_date"`
 }
 
 // StaffResponseDTO represents the response data for a staff member
 type StaffResponseDTO struct {
 	BaseResponse
-	BusinessID  string              `json:"business_id"`
-	UserID      string              `json:"user_id"`
-	Role        domain.BusinessRole `json:"role"`
-	IsActive    bool                `json:"is_active"`
-	Permissions *string             `json:"permissions,omitempty"`
-	StartDate   *time.Time          `json:"start_date,omitempty"`
-	EndDate     *time.Time          `json:"end_date,omitempty"`
+	BusinessID      string              `json:"business_id"`
+	UserID          string              `json:"user_id"`
+	Role            domain.BusinessRole `json:"role"`
+	IsActive        bool                `json:"is_active"`
+	Permissions     *string             `json:"permissions,omitempty"`
+	StartDate       *time.Time          `json:"start_date,omitempty"`
+	EndDate         *time.Time          `json:"end_date,omitempty"`
+	ProfileImageURL *string             `json:"profile_image_url,omitempty"`
+	AverageRating   *decimal.Decimal    `json:"average_rating,omitempty"`
+	ReviewCount     int                 `json:"review_count"`
 }
 
 // StaffWithUserDTO represents a staff member with user details
@@ -356,14 +384,18 @@ func ToStaffResponseDTO(staff *domain.Staff) *StaffResponseDTO {
 			ID:        staff.ID,
 			CreatedAt: staff.CreatedAt,
 			UpdatedAt: staff.UpdatedAt,
+			Version:   staff.Version,
 		},
-		BusinessID:  staff.BusinessID,
-		UserID:      staff.UserID,
-		Role:        staff.Role,
-		IsActive:    staff.IsActive,
-		Permissions: staff.Permissions,
-		StartDate:   staff.StartDate,
-		EndDate:     staff.EndDate,
+		BusinessID:      staff.BusinessID,
+		UserID:          staff.UserID,
+		Role:            staff.Role,
+		IsActive:        staff.IsActive,
+		Permissions:     staff.Permissions,
+		StartDate:       staff.StartDate,
+		EndDate:         staff.EndDate,
+		ProfileImageURL: staff.ProfileImageURL,
+		AverageRating:   staff.AverageRating,
+		ReviewCount:     staff.ReviewCount,
 	}
 }
 
@@ -0,0 +1,47 @@
+package dto
+
+import "github.com/assimoes/beautix/internal/domain"
+
+// CloneBusinessDTO represents the data for creating a business from a template
+type CloneBusinessDTO struct {
+	TemplateBusinessID string  `json:"template_business_id" validate:"required,uuid"`
+	Name               string  `json:"name" validate:"required,min=2,max=100"`
+	DisplayName        *string `json:"display_name,omitempty" validate:"omitempty,min=2,max=100"`
+	Email              *string `json:"email,omitempty" validate:"omitempty,email"`      // The template's when omitted
+	TimeZone           *string `json:"time_zone,omitempty" validate:"omitempty,max=50"` // The template's when omitted
+	CopyStaffRoles     bool    `json:"copy_staff_roles"`                                // Give the template's staff the same roles in the new business
+}
+
+// SetBusinessTemplateDTO represents the data for marking a business as a template
+type SetBusinessTemplateDTO struct {
+	BusinessID string `json:"business_id" validate:"required,uuid"`
+	IsTemplate bool   `json:"is_template"`
+}
+
+// BusinessCloneDTO represents a business created from a template and what it copied
+type BusinessCloneDTO struct {
+	Business           *BusinessResponseDTO `json:"business"`
+	TemplateBusinessID string               `json:"template_business_id"`
+	Categories         int                  `json:"categories"`
+	Services           int                  `json:"services"`
+	Bundles            int                  `json:"bundles"`
+	MessageTemplates   int                  `json:"message_templates"`
+	LoyaltyPrograms    int                  `json:"loyalty_programs"`
+	StaffRoles         int                  `json:"staff_roles"` // Staff given a role besides the owner
+	SettingsCopied     bool                 `json:"settings_copied"`
+}
+
+// ToBusinessCloneDTO summarizes a business cloned from a template
+func ToBusinessCloneDTO(templateBusinessID string, clone *domain.BusinessClone) *BusinessCloneDTO {
+	return &BusinessCloneDTO{
+		Business:           ToBusinessResponseDTO(clone.Business),
+		TemplateBusinessID: templateBusinessID,
+		Categories:         len(clone.Categories),
+		Services:           len(clone.Services),
+		Bundles:            len(clone.Bundles),
+		MessageTemplates:   len(clone.MessageTemplates),
+		LoyaltyPrograms:    len(clone.LoyaltyPrograms),
+		StaffRoles:         len(clone.Staff) - 1,
+		SettingsCopied:     clone.Settings != nil,
+	}
+}
@@ -0,0 +1,57 @@
+package dto
+
+import (
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// OpeningHoursDTO represents the opening hours of a business or of one of its locations
+type OpeningHoursDTO struct {
+	BusinessID   string           `json:"business_id"`
+	LocationID   *string          `json:"location_id,omitempty"`   // The whole business when nil
+	LocationName *string          `json:"location_name,omitempty"` // The name of the location
+	Hours        BusinessHoursDTO `json:"hours"`
+	Inherited    bool             `json:"inherited"` // The location has no hours of its own and keeps the business's
+	Configured   bool             `json:"configured"`
+}
+
+// SetBusinessHoursDTO represents a request to replace the opening hours of a business or of one
+// of its locations. Days left out are closed.
+type SetBusinessHoursDTO struct {
+	BusinessID string           `json:"business_id" validate:"required,uuid"`
+	LocationID *string          `json:"location_id,omitempty" validate:"omitempty,uuid"`
+	Hours      BusinessHoursDTO `json:"hours"`
+}
+
+// WeeklyHours returns the opening hours by lower-case weekday name
+func (d BusinessHoursDTO) WeeklyHours() domain.WeeklyHours {
+	hours := domain.WeeklyHours{}
+	days := map[string]*DayHoursDTO{
+		"monday": d.Monday, "tuesday": d.Tuesday, "wednesday": d.Wednesday, "thursday": d.Thursday,
+		"friday": d.Friday, "saturday": d.Saturday, "sunday": d.Sunday,
+	}
+	for name, day := range days {
+		if day != nil {
+			hours[name] = &domain.DayHours{IsOpen: day.IsOpen, OpenTime: day.OpenTime, CloseTime: day.CloseTime}
+		}
+	}
+	return hours
+}
+
+// ToBusinessHoursDTO converts opening hours to a BusinessHoursDTO
+func ToBusinessHoursDTO(hours domain.WeeklyHours) BusinessHoursDTO {
+	day := func(name string) *DayHoursDTO {
+		if hours[name] == nil {
+			return nil
+		}
+		return &DayHoursDTO{IsOpen: hours[name].IsOpen, OpenTime: hours[name].OpenTime, CloseTime: hours[name].CloseTime}
+	}
+	return BusinessHoursDTO{
+		Monday:    day("monday"),
+		Tuesday:   day("tuesday"),
+		Wednesday: day("wednesday"),
+		Thursday:  day("thursday"),
+		Friday:    day("friday"),
+		Saturday:  day("saturday"),
+		Sunday:    day("sunday"),
+	}
+}
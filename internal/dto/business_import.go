@@ -0,0 +1,153 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// BusinessImportDTO represents the data for importing services or staff from a CSV file
+type BusinessImportDTO struct {
+	BusinessID string `json:"business_id" validate:"required,uuid"`
+	CSV        string `json:"csv" validate:"required"`
+	DryRun     bool   `json:"dry_run"` // Validate and report without importing
+}
+
+// BusinessImportRowDTO represents the outcome of a row of a service or staff import
+type BusinessImportRowDTO struct {
+	Line            int                    `json:"line"`
+	Status          domain.ImportRowStatus `json:"status"`
+	Name            string                 `json:"name"`             // The service's name or the staff member's full name
+	Detail          *string                `json:"detail,omitempty"` // The service's category or the staff member's email
+	Errors          []string               `json:"errors"`
+	RecordID        *string                `json:"record_id,omitempty"` // The service or staff member created
+	DuplicateOfID   *string                `json:"duplicate_of_id,omitempty"`
+	DuplicateOfLine *int                   `json:"duplicate_of_line,omitempty"`
+}
+
+// BusinessImportResultDTO represents the outcome of a service or staff import
+type BusinessImportResultDTO struct {
+	BatchID    *string                 `json:"batch_id,omitempty"` // Set once records were created
+	BusinessID string                  `json:"business_id"`
+	Kind       domain.ImportKind       `json:"kind"`
+	DryRun     bool                    `json:"dry_run"`
+	TotalRows  int                     `json:"total_rows"`
+	Imported   int                     `json:"imported"`
+	Ready      int                     `json:"ready"`
+	Duplicates int                     `json:"duplicates"`
+	Invalid    int                     `json:"invalid"`
+	Rows       []*BusinessImportRowDTO `json:"rows"`
+}
+
+// ImportBatchDTO represents an import of services or staff into a business
+type ImportBatchDTO struct {
+	BaseResponse
+	BusinessID   string                   `json:"business_id"`
+	Kind         domain.ImportKind        `json:"kind"`
+	Status       domain.ImportBatchStatus `json:"status"`
+	TotalRows    int                      `json:"total_rows"`
+	Imported     int                      `json:"imported"`
+	CreatedBy    *string                  `json:"created_by,omitempty"`
+	RolledBackAt *time.Time               `json:"rolled_back_at,omitempty"`
+	RolledBackBy *string                  `json:"rolled_back_by,omitempty"`
+}
+
+// ToServiceImportResultDTO summarizes the rows of a service import
+func ToServiceImportResultDTO(businessID string, dryRun bool, batch *domain.ImportBatch, rows []*domain.ServiceImportRow) *BusinessImportResultDTO {
+	result := newBusinessImportResultDTO(businessID, domain.ImportKindServices, dryRun, batch, len(rows))
+	for i, row := range rows {
+		rowDTO := &BusinessImportRowDTO{
+			Line:            row.Line,
+			Status:          row.Status,
+			Name:            row.Service.Name,
+			Errors:          row.Errors,
+			DuplicateOfID:   row.DuplicateOfID,
+			DuplicateOfLine: row.DuplicateOfLine,
+		}
+		if row.Category != "" {
+			rowDTO.Detail = &row.Category
+		}
+		if row.Status == domain.ImportRowImported {
+			rowDTO.RecordID = &row.Service.ID
+		}
+		result.addRow(i, rowDTO)
+	}
+	return result
+}
+
+// ToStaffImportResultDTO summarizes the rows of a staff import
+func ToStaffImportResultDTO(businessID string, dryRun bool, batch *domain.ImportBatch, rows []*domain.StaffImportRow) *BusinessImportResultDTO {
+	result := newBusinessImportResultDTO(businessID, domain.ImportKindStaff, dryRun, batch, len(rows))
+	for i, row := range rows {
+		rowDTO := &BusinessImportRowDTO{
+			Line:            row.Line,
+			Status:          row.Status,
+			Name:            row.User.FirstName + " " + row.User.LastName,
+			Detail:          &row.User.Email,
+			Errors:          row.Errors,
+			DuplicateOfID:   row.DuplicateOfID,
+			DuplicateOfLine: row.DuplicateOfLine,
+		}
+		if row.Status == domain.ImportRowImported {
+			rowDTO.RecordID = &row.Staff.ID
+		}
+		result.addRow(i, rowDTO)
+	}
+	return result
+}
+
+// newBusinessImportResultDTO creates the summary of an import of rows
+func newBusinessImportResultDTO(businessID string, kind domain.ImportKind, dryRun bool, batch *domain.ImportBatch, rows int) *BusinessImportResultDTO {
+	result := &BusinessImportResultDTO{
+		BusinessID: businessID,
+		Kind:       kind,
+		DryRun:     dryRun,
+		TotalRows:  rows,
+		Rows:       make([]*BusinessImportRowDTO, rows),
+	}
+	if batch != nil {
+		result.BatchID = &batch.ID
+	}
+	return result
+}
+
+// addRow sets a row of the summary and counts it by status
+func (r *BusinessImportResultDTO) addRow(i int, row *BusinessImportRowDTO) {
+	switch row.Status {
+	case domain.ImportRowImported:
+		r.Imported++
+	case domain.ImportRowReady:
+		r.Ready++
+	case domain.ImportRowDuplicate:
+		r.Duplicates++
+	case domain.ImportRowInvalid:
+		r.Invalid++
+	}
+	if row.Errors == nil {
+		row.Errors = []string{}
+	}
+	r.Rows[i] = row
+}
+
+// ToImportBatchDTO converts an ImportBatch domain model to ImportBatchDTO
+func ToImportBatchDTO(batch *domain.ImportBatch) *ImportBatchDTO {
+	if batch == nil {
+		return nil
+	}
+	return &ImportBatchDTO{
+		BaseResponse: BaseResponse{
+			ID:        batch.ID,
+			CreatedAt: batch.CreatedAt,
+			UpdatedAt: batch.UpdatedAt,
+			Version:   batch.Version,
+		},
+		BusinessID:   batch.BusinessID,
+		Kind:         batch.Kind,
+		Status:       batch.Status,
+		TotalRows:    batch.TotalRows,
+		Imported:     batch.Imported,
+		CreatedBy:    batch.CreatedBy,
+		RolledBackAt: batch.RolledBackAt,
+		RolledBackBy: batch.RolledBackBy,
+	}
+}
@@ -0,0 +1,59 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// CalendarEntryDTO represents an appointment as shown on the calendar
+type CalendarEntryDTO struct {
+	AppointmentID string                   `json:"appointment_id"`
+	StaffID       string                   `json:"staff_id"`
+	ClientID      string                   `json:"client_id"`
+	CalendarDate  time.Time                `json:"calendar_date"`
+	StartTime     time.Time                `json:"start_time"`
+	EndTime       time.Time                `json:"end_time"`
+	Status        domain.AppointmentStatus `json:"status"`
+	ClientName    string                   `json:"client_name"`
+	ClientPhone   *string                  `json:"client_phone,omitempty"`
+	StaffName     string                   `json:"staff_name"`
+	ServiceNames  []string                 `json:"service_names"`
+	TotalDuration int                      `json:"total_duration"`
+	Price         *decimal.Decimal         `json:"price,omitempty"`
+	Notes         *string                  `json:"notes,omitempty"`
+}
+
+// ToCalendarEntryDTO converts a CalendarEntry read model to CalendarEntryDTO
+func ToCalendarEntryDTO(entry *domain.CalendarEntry) *CalendarEntryDTO {
+	if entry == nil {
+		return nil
+	}
+
+	return &CalendarEntryDTO{
+		AppointmentID: entry.AppointmentID,
+		StaffID:       entry.StaffID,
+		ClientID:      entry.ClientID,
+		CalendarDate:  entry.CalendarDate,
+		StartTime:     entry.StartTime,
+		EndTime:       entry.EndTime,
+		Status:        entry.Status,
+		ClientName:    entry.ClientName,
+		ClientPhone:   entry.ClientPhone,
+		StaffName:     entry.StaffName,
+		ServiceNames:  entry.GetServiceNames(),
+		TotalDuration: entry.TotalDuration,
+		Price:         entry.Price,
+		Notes:         entry.Notes,
+	}
+}
+
+// ToCalendarEntryDTOs converts a slice of CalendarEntry read models to DTOs
+func ToCalendarEntryDTOs(entries []*domain.CalendarEntry) []*CalendarEntryDTO {
+	result := make([]*CalendarEntryDTO, len(entries))
+	for i, entry := range entries {
+		result[i] = ToCalendarEntryDTO(entry)
+	}
+	return result
+}
@@ -0,0 +1,134 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// CreateCampaignDTO represents the data for creating a marketing campaign
+type CreateCampaignDTO struct {
+	BusinessID      string                `json:"business_id" validate:"required,uuid"`
+	Name            string                `json:"name" validate:"required,max=100"`
+	Description     *string               `json:"description,omitempty" validate:"omitempty,max=1000"`
+	CampaignType    domain.CampaignType   `json:"campaign_type" validate:"required,oneof=promotion seasonal reactivation birthday"`
+	TargetAudience  domain.TargetAudience `json:"target_audience"`
+	OfferType       string                `json:"offer_type" validate:"required,oneof=discount free_service bundle gift"`
+	OfferDetails    map[string]any        `json:"offer_details"`
+	StartDate       time.Time             `json:"start_date" validate:"required"`
+	EndDate         time.Time             `json:"end_date" validate:"required"`
+	MessageTemplate *string               `json:"message_template,omitempty" validate:"omitempty,max=2000"`
+}
+
+// PreviewAudienceDTO represents the data for counting the clients an audience would target
+type PreviewAudienceDTO struct {
+	BusinessID     string                `json:"business_id" validate:"required,uuid"`
+	TargetAudience domain.TargetAudience `json:"target_audience"`
+}
+
+// AudiencePreviewDTO represents how many clients an audience targets
+type AudiencePreviewDTO struct {
+	BusinessID string `json:"business_id"`
+	Clients    int64  `json:"clients"`
+}
+
+// CampaignTargetingDTO represents the outcome of targeting a campaign's audience
+type CampaignTargetingDTO struct {
+	CampaignID string `json:"campaign_id"`
+	Added      int64  `json:"added"` // Clients added by this run
+	Targeted   int    `json:"targeted"`
+}
+
+// CampaignAnalyticsDTO represents how the clients targeted by a campaign responded to it within
+// a time range
+type CampaignAnalyticsDTO struct {
+	CampaignID     string          `json:"campaign_id"`
+	Start          time.Time       `json:"start"`
+	End            time.Time       `json:"end"`
+	Targeted       int64           `json:"targeted"`
+	Sent           int64           `json:"sent"`
+	Opened         int64           `json:"opened"`
+	Clicked        int64           `json:"clicked"`
+	Converted      int64           `json:"converted"`
+	Unsubscribed   int64           `json:"unsubscribed"`
+	OpenRate       float64         `json:"open_rate"`       // Of the messages sent, between 0 and 1
+	ClickRate      float64         `json:"click_rate"`      // Of the messages sent
+	ConversionRate float64         `json:"conversion_rate"` // Of the messages sent
+	Visits         int64           `json:"visits"`          // Completed by converted clients on or after converting
+	Revenue        decimal.Decimal `json:"revenue"`         // Charged for those visits
+}
+
+// CampaignResponseDTO represents the response data for a campaign
+type CampaignResponseDTO struct {
+	BaseResponse
+	BusinessID      string                `json:"business_id"`
+	Name            string                `json:"name"`
+	Description     *string               `json:"description,omitempty"`
+	CampaignType    domain.CampaignType   `json:"campaign_type"`
+	TargetAudience  domain.TargetAudience `json:"target_audience"`
+	OfferType       string                `json:"offer_type"`
+	OfferDetails    map[string]any        `json:"offer_details"`
+	StartDate       time.Time             `json:"start_date"`
+	EndDate         time.Time             `json:"end_date"`
+	IsActive        bool                  `json:"is_active"`
+	MessageTemplate *string               `json:"message_template,omitempty"`
+	Targeted        int                   `json:"targeted"` // Clients targeted so far
+}
+
+// ToCampaignResponseDTO converts a Campaign domain model to CampaignResponseDTO
+func ToCampaignResponseDTO(campaign *domain.Campaign, targeted int) *CampaignResponseDTO {
+	if campaign == nil {
+		return nil
+	}
+
+	audience, _ := campaign.GetTargetAudience()
+	offerDetails := map[string]any{}
+	_ = json.Unmarshal([]byte(campaign.OfferDetails), &offerDetails)
+
+	return &CampaignResponseDTO{
+		BaseResponse: BaseResponse{
+			ID:        campaign.ID,
+			CreatedAt: campaign.CreatedAt,
+			UpdatedAt: campaign.UpdatedAt,
+			Version:   campaign.Version,
+		},
+		BusinessID:      campaign.BusinessID,
+		Name:            campaign.Name,
+		Description:     campaign.Description,
+		CampaignType:    campaign.CampaignType,
+		TargetAudience:  audience,
+		OfferType:       campaign.OfferType,
+		OfferDetails:    offerDetails,
+		StartDate:       campaign.StartDate,
+		EndDate:         campaign.EndDate,
+		IsActive:        campaign.IsActive,
+		MessageTemplate: campaign.MessageTemplate,
+		Targeted:        targeted,
+	}
+}
+
+// ToCampaignAnalyticsDTO converts CampaignAnalytics to CampaignAnalyticsDTO
+func ToCampaignAnalyticsDTO(analytics *domain.CampaignAnalytics) *CampaignAnalyticsDTO {
+	if analytics == nil {
+		return nil
+	}
+
+	return &CampaignAnalyticsDTO{
+		CampaignID:     analytics.CampaignID,
+		Start:          analytics.From,
+		End:            analytics.To,
+		Targeted:       analytics.Targeted,
+		Sent:           analytics.Sent,
+		Opened:         analytics.Opened,
+		Clicked:        analytics.Clicked,
+		Converted:      analytics.Converted,
+		Unsubscribed:   analytics.Unsubscribed,
+		OpenRate:       analytics.OpenRate(),
+		ClickRate:      analytics.ClickRate(),
+		ConversionRate: analytics.ConversionRate(),
+		Visits:         analytics.Visits,
+		Revenue:        analytics.Revenue,
+	}
+}
@@ -0,0 +1,33 @@
+package dto
+
+import (
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// SetCancellationPolicyDTO represents the data for setting how a business treats late
+// cancellations and no-shows; the policy is replaced whole
+type SetCancellationPolicyDTO struct {
+	BusinessID  string           `json:"business_id" validate:"required,uuid"`
+	NoticeHours int              `json:"notice_hours" validate:"min=0,max=168"` // 0 allows cancelling until the appointment starts
+	NoShowFee   *decimal.Decimal `json:"no_show_fee,omitempty"`
+	MaxNoShows  int              `json:"max_no_shows" validate:"min=0,max=20"` // 0 never flags clients
+}
+
+// CancellationPolicyDTO represents how a business treats late cancellations and no-shows
+type CancellationPolicyDTO struct {
+	BusinessID  string           `json:"business_id"`
+	NoticeHours int              `json:"notice_hours"`
+	NoShowFee   *decimal.Decimal `json:"no_show_fee,omitempty"`
+	MaxNoShows  int              `json:"max_no_shows"`
+}
+
+// ToCancellationPolicyDTO converts a business's cancellation policy to a CancellationPolicyDTO
+func ToCancellationPolicyDTO(businessID string, policy domain.CancellationPolicy) *CancellationPolicyDTO {
+	return &CancellationPolicyDTO{
+		BusinessID:  businessID,
+		NoticeHours: policy.NoticeHours,
+		NoShowFee:   policy.NoShowFee,
+		MaxNoShows:  policy.MaxNoShows,
+	}
+}
@@ -0,0 +1,70 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// BreakSuggestionDTO represents a suggested break in a staff member's day
+type BreakSuggestionDTO struct {
+	StaffID string    `json:"staff_id"`
+	Start   time.Time `json:"start"`
+	Minutes int       `json:"minutes"`
+}
+
+// CapacityWarningDTO represents a day or staff schedule that exceeds the business's capacity thresholds
+type CapacityWarningDTO struct {
+	Kind            domain.CapacityWarningKind `json:"kind"`
+	Date            time.Time                  `json:"date"`
+	BookedMinutes   int                        `json:"booked_minutes"`
+	CapacityMinutes int                        `json:"capacity_minutes,omitempty"`
+	BookedPercent   int                        `json:"booked_percent,omitempty"`
+	StaffID         *string                    `json:"staff_id,omitempty"`
+	StaffName       *string                    `json:"staff_name,omitempty"`
+	Start           *time.Time                 `json:"start,omitempty"`
+	End             *time.Time                 `json:"end,omitempty"`
+	SuggestedBreak  *BreakSuggestionDTO        `json:"suggested_break,omitempty"`
+}
+
+// CapacityNotificationRunDTO represents the result of a capacity notification run
+type CapacityNotificationRunDTO struct {
+	Businesses int `json:"businesses"` // Businesses notified
+	Warnings   int `json:"warnings"`   // New warnings notified
+}
+
+// ToCapacityWarningDTO converts a CapacityWarning to CapacityWarningDTO
+func ToCapacityWarningDTO(warning *domain.CapacityWarning) *CapacityWarningDTO {
+	if warning == nil {
+		return nil
+	}
+
+	result := &CapacityWarningDTO{
+		Kind:            warning.Kind,
+		Date:            warning.Date,
+		BookedMinutes:   warning.BookedMinutes,
+		CapacityMinutes: warning.CapacityMinutes,
+		BookedPercent:   warning.BookedPercent,
+		StaffID:         warning.StaffID,
+		StaffName:       warning.StaffName,
+		Start:           warning.Start,
+		End:             warning.End,
+	}
+	if warning.SuggestedBreak != nil {
+		result.SuggestedBreak = &BreakSuggestionDTO{
+			StaffID: warning.SuggestedBreak.StaffID,
+			Start:   warning.SuggestedBreak.Start,
+			Minutes: warning.SuggestedBreak.Minutes,
+		}
+	}
+	return result
+}
+
+// ToCapacityWarningDTOs converts a slice of CapacityWarning to DTOs
+func ToCapacityWarningDTOs(warnings []*domain.CapacityWarning) []*CapacityWarningDTO {
+	result := make([]*CapacityWarningDTO, len(warnings))
+	for i, warning := range warnings {
+		result[i] = ToCapacityWarningDTO(warning)
+	}
+	return result
+}
@@ -0,0 +1,189 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// MaxClientImportSize bounds the size of an imported CSV file in bytes
+const MaxClientImportSize = 5 << 20
+
+// ImportClientsDTO represents the data for importing clients from a CSV file
+type ImportClientsDTO struct {
+	BusinessID string `json:"business_id" validate:"required,uuid"`
+	CSV        string `json:"csv" validate:"required"`
+	DryRun     bool   `json:"dry_run"` // Validate and report without importing
+}
+
+// ClientImportRowDTO represents the outcome of a row of a client import
+type ClientImportRowDTO struct {
+	Line                int                       `json:"line"`
+	Status              domain.ClientImportStatus `json:"status"`
+	FirstName           string                    `json:"first_name"`
+	LastName            string                    `json:"last_name"`
+	Email               string                    `json:"email"`
+	Phone               *string                   `json:"phone,omitempty"`
+	Errors              []string                  `json:"errors"`
+	ClientID            *string                   `json:"client_id,omitempty"` // The created client
+	DuplicateOfClientID *string                   `json:"duplicate_of_client_id,omitempty"`
+	DuplicateOfLine     *int                      `json:"duplicate_of_line,omitempty"`
+}
+
+// ClientImportResultDTO represents the outcome of a client import
+type ClientImportResultDTO struct {
+	BusinessID string                `json:"business_id"`
+	DryRun     bool                  `json:"dry_run"`
+	TotalRows  int                   `json:"total_rows"`
+	Imported   int                   `json:"imported"`
+	Ready      int                   `json:"ready"`
+	Duplicates int                   `json:"duplicates"`
+	Invalid    int                   `json:"invalid"`
+	Rows       []*ClientImportRowDTO `json:"rows"`
+}
+
+// ToClientImportResultDTO summarizes the rows of a client import
+func ToClientImportResultDTO(businessID string, dryRun bool, rows []*domain.ClientImportRow) *ClientImportResultDTO {
+	result := &ClientImportResultDTO{
+		BusinessID: businessID,
+		DryRun:     dryRun,
+		TotalRows:  len(rows),
+		Rows:       make([]*ClientImportRowDTO, len(rows)),
+	}
+	for i, row := range rows {
+		switch row.Status {
+		case domain.ClientImportImported:
+			result.Imported++
+		case domain.ClientImportReady:
+			result.Ready++
+		case domain.ClientImportDuplicate:
+			result.Duplicates++
+		case domain.ClientImportInvalid:
+			result.Invalid++
+		}
+
+		rowDTO := &ClientImportRowDTO{
+			Line:                row.Line,
+			Status:              row.Status,
+			FirstName:           row.Client.FirstName,
+			LastName:            row.Client.LastName,
+			Email:               row.Client.Email,
+			Phone:               row.Client.Phone,
+			Errors:              row.Errors,
+			DuplicateOfClientID: row.DuplicateOfID,
+			DuplicateOfLine:     row.DuplicateOfLine,
+		}
+		if rowDTO.Errors == nil {
+			rowDTO.Errors = []string{}
+		}
+		if row.Status == domain.ClientImportImported {
+			rowDTO.ClientID = &row.Client.ID
+		}
+		result.Rows[i] = rowDTO
+	}
+	return result
+}
+
+// ExportClientDataDTO represents a request for everything held about a client. Given a PEM
+// encoded RSA public key or a passphrase, but not both, the export is encrypted for it.
+type ExportClientDataDTO struct {
+	ClientID   string                        `json:"client_id" validate:"required,uuid"`
+	Format     domain.ClientDataExportFormat `json:"format" validate:"omitempty,oneof=json zip"` // Defaults to JSON
+	PublicKey  string                        `json:"public_key,omitempty" validate:"omitempty,max=8192,excluded_with=Passphrase"`
+	Passphrase string                        `json:"passphrase,omitempty" validate:"omitempty,max=1024"`
+}
+
+// ClientDataExportDTO represents an export of a client's data as a file
+type ClientDataExportDTO struct {
+	ClientID    string                        `json:"client_id"`
+	Format      domain.ClientDataExportFormat `json:"format"`
+	FileName    string                        `json:"file_name"`
+	ContentType string                        `json:"content_type"`
+	Data        string                        `json:"data"` // The file, base64-encoded
+	GeneratedAt time.Time                     `json:"generated_at"`
+	Encryption  domain.ExportEncryptionMethod `json:"encryption"`
+	// KeyFingerprint identifies the public key the export is encrypted for
+	KeyFingerprint *string `json:"key_fingerprint,omitempty"`
+}
+
+// ClientAnonymizationDTO represents the outcome of anonymizing a client
+type ClientAnonymizationDTO struct {
+	ClientID     string    `json:"client_id"`
+	AnonymizedAt time.Time `json:"anonymized_at"`
+}
+
+// SetClientTagsDTO represents the data for replacing the tags of a client
+type SetClientTagsDTO struct {
+	ClientID        string   `json:"client_id" validate:"required,uuid"`
+	Tags            []string `json:"tags" validate:"max=20"`
+	ExpectedVersion *int     `json:"expected_version,omitempty" validate:"omitempty,min=1"` // See RescheduleAppointmentDTO
+}
+
+// MergeClientsDTO represents the data for merging a duplicate client into the client kept
+type MergeClientsDTO struct {
+	ClientID    string `json:"client_id" validate:"required,uuid"`
+	DuplicateID string `json:"duplicate_id" validate:"required,uuid,nefield=ClientID"`
+}
+
+// ClientDuplicateDTO represents a pair of clients that are probably the same person
+type ClientDuplicateDTO struct {
+	Client    *ClientResponseDTO       `json:"client"` // The older of the two, usually the one to keep
+	Duplicate *ClientResponseDTO       `json:"duplicate"`
+	Reasons   []domain.DuplicateReason `json:"reasons"`
+}
+
+// ClientMergeDTO represents the outcome of merging a duplicate client
+type ClientMergeDTO struct {
+	Client         *ClientResponseDTO `json:"client"`
+	MergedClientID string             `json:"merged_client_id"`
+	RecordsMoved   int64              `json:"records_moved"`
+	MergedAt       time.Time          `json:"merged_at"`
+}
+
+// ClientResponseDTO represents the response data for a client
+type ClientResponseDTO struct {
+	BaseResponse
+	BusinessID      string          `json:"business_id"`
+	FirstName       string          `json:"first_name"`
+	LastName        string          `json:"last_name"`
+	Email           string          `json:"email"`
+	Phone           *string         `json:"phone,omitempty"`
+	IsActive        bool            `json:"is_active"`
+	LastVisit       *time.Time      `json:"last_visit,omitempty"`
+	TotalVisits     int             `json:"total_visits"`
+	TotalSpent      decimal.Decimal `json:"total_spent"`
+	Tags            []string        `json:"tags"`
+	NoShowCount     int             `json:"no_show_count"`
+	DepositRequired bool            `json:"deposit_required"` // Set once the client reaches the business's no-show limit
+}
+
+// ToClientResponseDTO converts a Client domain model to ClientResponseDTO
+func ToClientResponseDTO(client *domain.Client) *ClientResponseDTO {
+	if client == nil {
+		return nil
+	}
+
+	tags, _ := client.GetTags()
+
+	return &ClientResponseDTO{
+		BaseResponse: BaseResponse{
+			ID:        client.ID,
+			CreatedAt: client.CreatedAt,
+			UpdatedAt: client.UpdatedAt,
+			Version:   client.Version,
+		},
+		BusinessID:      client.BusinessID,
+		FirstName:       client.FirstName,
+		LastName:        client.LastName,
+		Email:           client.Email,
+		Phone:           client.Phone,
+		IsActive:        client.IsActive,
+		LastVisit:       client.LastVisit,
+		TotalVisits:     client.TotalVisits,
+		TotalSpent:      client.TotalSpent,
+		Tags:            tags,
+		NoShowCount:     client.NoShowCount,
+		DepositRequired: client.DepositRequired,
+	}
+}
@@ -0,0 +1,110 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// CreateClientAttachmentDTO represents a request for a URL to upload a client's photo or document to
+type CreateClientAttachmentDTO struct {
+	BusinessID    string                      `json:"business_id" validate:"required,uuid"`
+	ClientID      string                      `json:"client_id" validate:"required,uuid"`
+	AppointmentID *string                     `json:"appointment_id,omitempty" validate:"omitempty,uuid"`
+	Kind          domain.ClientAttachmentKind `json:"kind" validate:"required,oneof=before_photo after_photo photo document"`
+	FileName      string                      `json:"file_name" validate:"required,max=255"`
+	Caption       *string                     `json:"caption,omitempty" validate:"omitempty,max=500"`
+	ContentType   string                      `json:"content_type" validate:"required,oneof=image/jpeg image/png image/gif application/pdf"`
+	SizeBytes     int64                       `json:"size_bytes" validate:"required,min=1,max=20971520"` // The exact size of the file
+}
+
+// ClientAttachmentResponseDTO represents a client attachment, with a signed URL to view it once
+// stored
+type ClientAttachmentResponseDTO struct {
+	BaseResponse
+	BusinessID         string                        `json:"business_id"`
+	ClientID           string                        `json:"client_id"`
+	AppointmentID      *string                       `json:"appointment_id,omitempty"`
+	Kind               domain.ClientAttachmentKind   `json:"kind"`
+	FileName           string                        `json:"file_name"`
+	Caption            *string                       `json:"caption,omitempty"`
+	ContentType        string                        `json:"content_type"`
+	SizeBytes          int64                         `json:"size_bytes"`
+	Status             domain.ClientAttachmentStatus `json:"status"`
+	Width              *int                          `json:"width,omitempty"`
+	Height             *int                          `json:"height,omitempty"`
+	Error              *string                       `json:"error,omitempty"`
+	StoredAt           *time.Time                    `json:"stored_at,omitempty"`
+	MarketingConsentAt *time.Time                    `json:"marketing_consent_at,omitempty"`
+	UsableInMarketing  bool                          `json:"usable_in_marketing"`
+	URL                *string                       `json:"url,omitempty"`            // Signed; set on stored attachments
+	URLExpiresAt       *time.Time                    `json:"url_expires_at,omitempty"` // When the URL stops working
+}
+
+// ToClientAttachmentResponseDTO converts a ClientAttachment domain model to
+// ClientAttachmentResponseDTO, without a URL
+func ToClientAttachmentResponseDTO(attachment *domain.ClientAttachment) *ClientAttachmentResponseDTO {
+	if attachment == nil {
+		return nil
+	}
+
+	return &ClientAttachmentResponseDTO{
+		BaseResponse: BaseResponse{
+			ID:        attachment.ID,
+			CreatedAt: attachment.CreatedAt,
+			UpdatedAt: attachment.UpdatedAt,
+			Version:   attachment.Version,
+		},
+		BusinessID:         attachment.BusinessID,
+		ClientID:           attachment.ClientID,
+		AppointmentID:      attachment.AppointmentID,
+		Kind:               attachment.Kind,
+		FileName:           attachment.FileName,
+		Caption:            attachment.Caption,
+		ContentType:        attachment.ContentType,
+		SizeBytes:          attachment.SizeBytes,
+		Status:             attachment.Status,
+		Width:              attachment.Width,
+		Height:             attachment.Height,
+		Error:              attachment.Error,
+		StoredAt:           attachment.StoredAt,
+		MarketingConsentAt: attachment.MarketingConsentAt,
+		UsableInMarketing:  attachment.UsableInMarketing(),
+	}
+}
+
+// ClientAttachmentTicketDTO represents a presigned URL the file of a client attachment is sent to
+type ClientAttachmentTicketDTO struct {
+	Attachment *ClientAttachmentResponseDTO `json:"attachment"`
+	UploadURL  string                       `json:"upload_url"`
+	Method     string                       `json:"method"`
+	Headers    []UploadHeaderDTO            `json:"headers"`    // Signed, so they must be sent as given
+	ExpiresAt  time.Time                    `json:"expires_at"` // When the URL stops working
+}
+
+// SetClientAttachmentConsentDTO represents a client agreeing to, or withdrawing from, the use of a
+// photo in marketing
+type SetClientAttachmentConsentDTO struct {
+	AttachmentID string `json:"attachment_id" validate:"required,uuid"`
+	Consented    bool   `json:"consented"`
+}
+
+// SetAttachmentQuotaDTO represents a change of the storage a business may use for attachments
+type SetAttachmentQuotaDTO struct {
+	BusinessID string `json:"business_id" validate:"required,uuid"`
+	QuotaMB    *int   `json:"quota_mb,omitempty" validate:"omitempty,min=0,max=1048576"` // The default quota when nil
+}
+
+// AttachmentUsageDTO represents the storage a business uses for client attachments
+type AttachmentUsageDTO struct {
+	BusinessID string `json:"business_id"`
+	UsedBytes  int64  `json:"used_bytes"`
+	QuotaBytes int64  `json:"quota_bytes"`
+}
+
+// ClientAttachmentCleanupRunDTO represents a removal of the files of deleted, rejected and
+// abandoned client attachments
+type ClientAttachmentCleanupRunDTO struct {
+	Removed int `json:"removed"`
+	Failed  int `json:"failed"`
+}
@@ -0,0 +1,69 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// LinkGuardianDTO represents the data for linking a minor client to a guardian
+type LinkGuardianDTO struct {
+	ClientID         string  `json:"client_id" validate:"required,uuid"`
+	GuardianClientID string  `json:"guardian_client_id" validate:"required,uuid"`
+	Relationship     *string `json:"relationship,omitempty" validate:"omitempty,max=50"`
+	ConsentGiven     bool    `json:"consent_given"`
+}
+
+// MinorClientDTO represents a client with their age and guardian details
+type MinorClientDTO struct {
+	ClientID             string     `json:"client_id"`
+	BusinessID           string     `json:"business_id"`
+	FullName             string     `json:"full_name"`
+	DateOfBirth          *time.Time `json:"date_of_birth,omitempty"`
+	Age                  *int       `json:"age,omitempty"`
+	IsMinor              bool       `json:"is_minor"`
+	GuardianClientID     *string    `json:"guardian_client_id,omitempty"`
+	GuardianRelationship *string    `json:"guardian_relationship,omitempty"`
+	GuardianConsentAt    *time.Time `json:"guardian_consent_at,omitempty"`
+}
+
+// ServiceAgeRestrictionDTO represents the minimum client age of a service
+type ServiceAgeRestrictionDTO struct {
+	ServiceID string `json:"service_id"`
+	Name      string `json:"name"`
+	MinAge    *int   `json:"min_age,omitempty"`
+}
+
+// ToMinorClientDTO converts a Client domain model to MinorClientDTO, with the age at a time
+func ToMinorClientDTO(client *domain.Client, at time.Time) *MinorClientDTO {
+	if client == nil {
+		return nil
+	}
+
+	response := &MinorClientDTO{
+		ClientID:             client.ID,
+		BusinessID:           client.BusinessID,
+		FullName:             client.GetFullName(),
+		DateOfBirth:          client.DateOfBirth,
+		IsMinor:              client.IsMinorAt(at),
+		GuardianClientID:     client.GuardianClientID,
+		GuardianRelationship: client.GuardianRelationship,
+		GuardianConsentAt:    client.GuardianConsentAt,
+	}
+	if age, known := client.AgeAt(at); known {
+		response.Age = &age
+	}
+	return response
+}
+
+// ToServiceAgeRestrictionDTO converts a Service domain model to ServiceAgeRestrictionDTO
+func ToServiceAgeRestrictionDTO(service *domain.Service) *ServiceAgeRestrictionDTO {
+	if service == nil {
+		return nil
+	}
+	return &ServiceAgeRestrictionDTO{
+		ServiceID: service.ID,
+		Name:      service.Name,
+		MinAge:    service.MinAge,
+	}
+}
@@ -0,0 +1,103 @@
+package dto
+
+import (
+	"slices"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// ClientLeaderboardRequestDTO represents the data for ranking the clients of a business
+type ClientLeaderboardRequestDTO struct {
+	BusinessID string                   `json:"business_id" validate:"required,uuid"`
+	Metric     domain.LeaderboardMetric `json:"metric" validate:"required,oneof=spend visits points"`
+	From       *time.Time               `json:"from,omitempty"` // A year before To when omitted
+	To         *time.Time               `json:"to,omitempty"`   // Now when omitted
+	Limit      int                      `json:"limit,omitempty" validate:"omitempty,min=1,max=100"`
+}
+
+// ClientStandingDTO represents a client's place on a leaderboard
+type ClientStandingDTO struct {
+	Rank      int                `json:"rank"`
+	Client    *ClientResponseDTO `json:"client"`
+	Visits    int                `json:"visits"`
+	Spent     decimal.Decimal    `json:"spent"`
+	Points    int                `json:"points"`
+	LastVisit *time.Time         `json:"last_visit,omitempty"`
+	IsVIP     bool               `json:"is_vip"`
+}
+
+// ClientLeaderboardDTO represents the top clients of a business over a period
+type ClientLeaderboardDTO struct {
+	BusinessID string                   `json:"business_id"`
+	Metric     domain.LeaderboardMetric `json:"metric"`
+	From       time.Time                `json:"from"`
+	To         time.Time                `json:"to"`
+	Standings  []*ClientStandingDTO     `json:"standings"`
+}
+
+// ToClientStandingDTO converts a client's standing to a ClientStandingDTO
+func ToClientStandingDTO(standing *domain.ClientStanding, client *domain.Client) *ClientStandingDTO {
+	tags, _ := client.GetTags()
+	return &ClientStandingDTO{
+		Rank:      standing.Rank,
+		Client:    ToClientResponseDTO(client),
+		Visits:    standing.Visits,
+		Spent:     standing.Spent,
+		Points:    standing.Points,
+		LastVisit: standing.LastVisit,
+		IsVIP:     slices.Contains(tags, domain.VIPTag),
+	}
+}
+
+// SetVIPRulesDTO represents the data for setting the thresholds making clients of a business
+// VIPs; the rules are replaced whole
+type SetVIPRulesDTO struct {
+	BusinessID string           `json:"business_id" validate:"required,uuid"`
+	Enabled    bool             `json:"enabled"`
+	PeriodDays int              `json:"period_days,omitempty" validate:"omitempty,min=1,max=731"` // A year when omitted
+	MinSpend   *decimal.Decimal `json:"min_spend,omitempty"`
+	MinVisits  *int             `json:"min_visits,omitempty" validate:"omitempty,min=1"`
+	MinPoints  *int             `json:"min_points,omitempty" validate:"omitempty,min=1"`
+}
+
+// VIPRulesDTO represents the thresholds making clients of a business VIPs
+type VIPRulesDTO struct {
+	BusinessID string           `json:"business_id"`
+	Enabled    bool             `json:"enabled"`
+	PeriodDays int              `json:"period_days"`
+	MinSpend   *decimal.Decimal `json:"min_spend,omitempty"`
+	MinVisits  *int             `json:"min_visits,omitempty"`
+	MinPoints  *int             `json:"min_points,omitempty"`
+	Tag        string           `json:"tag"` // The client tag VIPs have, for audiences and filters
+}
+
+// ToVIPRulesDTO converts a business's VIP rules to a VIPRulesDTO
+func ToVIPRulesDTO(businessID string, rules domain.VIPRules) *VIPRulesDTO {
+	return &VIPRulesDTO{
+		BusinessID: businessID,
+		Enabled:    rules.Enabled,
+		PeriodDays: rules.PeriodDays,
+		MinSpend:   rules.MinSpend,
+		MinVisits:  rules.MinVisits,
+		MinPoints:  rules.MinPoints,
+		Tag:        domain.VIPTag,
+	}
+}
+
+// VIPRefreshDTO represents the VIP tagging of a business's clients by its rules
+type VIPRefreshDTO struct {
+	Rules      *VIPRulesDTO `json:"rules"`
+	VIPClients int          `json:"vip_clients"` // Clients meeting a threshold
+	Tagged     int64        `json:"tagged"`      // Clients who became VIPs
+	Untagged   int64        `json:"untagged"`    // Clients who no longer are
+}
+
+// VIPRefreshRunDTO represents a refresh of the VIP tags of every business with VIP rules
+type VIPRefreshRunDTO struct {
+	Businesses int   `json:"businesses"`
+	Tagged     int64 `json:"tagged"`
+	Untagged   int64 `json:"untagged"`
+	Failed     int   `json:"failed"`
+}
@@ -0,0 +1,120 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// PortalVisitServiceDTO represents a service delivered during a visit
+type PortalVisitServiceDTO struct {
+	Name     string          `json:"name"`
+	Duration int             `json:"duration"`
+	Price    decimal.Decimal `json:"price"`
+}
+
+// PortalVisitDTO represents a past appointment in the client portal
+type PortalVisitDTO struct {
+	AppointmentID    string                   `json:"appointment_id"`
+	BusinessID       string                   `json:"business_id"`
+	BusinessName     string                   `json:"business_name"`
+	Currency         string                   `json:"currency"`
+	StaffName        string                   `json:"staff_name"`
+	StartTime        time.Time                `json:"start_time"`
+	EndTime          time.Time                `json:"end_time"`
+	Status           domain.AppointmentStatus `json:"status"`
+	Services         []*PortalVisitServiceDTO `json:"services"`
+	AmountPaid       *decimal.Decimal         `json:"amount_paid,omitempty"`
+	PaymentMethod    *string                  `json:"payment_method,omitempty"`
+	LoyaltyPoints    int                      `json:"loyalty_points"`
+	InvoiceAvailable bool                     `json:"invoice_available"`
+}
+
+// InvoiceResponseDTO represents the response data for an invoice
+type InvoiceResponseDTO struct {
+	BaseResponse
+	BusinessID    string               `json:"business_id"`
+	AppointmentID string               `json:"appointment_id"`
+	InvoiceNumber string               `json:"invoice_number"`
+	IssuedAt      time.Time            `json:"issued_at"`
+	Currency      string               `json:"currency"`
+	BusinessName  string               `json:"business_name"`
+	BusinessTaxID *string              `json:"business_tax_id,omitempty"`
+	ClientName    string               `json:"client_name"`
+	LineItems     []domain.InvoiceLine `json:"line_items"`
+	Total         decimal.Decimal      `json:"total"`
+	AmountPaid    decimal.Decimal      `json:"amount_paid"`
+	PaymentMethod *string              `json:"payment_method,omitempty"`
+}
+
+// ToPortalVisitDTO converts a PortalVisit read model to PortalVisitDTO
+func ToPortalVisitDTO(visit *domain.PortalVisit) *PortalVisitDTO {
+	if visit == nil {
+		return nil
+	}
+
+	services := make([]*PortalVisitServiceDTO, len(visit.Services))
+	for i, service := range visit.Services {
+		services[i] = &PortalVisitServiceDTO{
+			Name:     service.Name,
+			Duration: service.Duration,
+			Price:    service.Price,
+		}
+	}
+
+	return &PortalVisitDTO{
+		AppointmentID:    visit.AppointmentID,
+		BusinessID:       visit.BusinessID,
+		BusinessName:     visit.BusinessName,
+		Currency:         visit.Currency,
+		StaffName:        visit.StaffName,
+		StartTime:        visit.StartTime,
+		EndTime:          visit.EndTime,
+		Status:           visit.Status,
+		Services:         services,
+		AmountPaid:       visit.AmountPaid,
+		PaymentMethod:    visit.PaymentMethod,
+		LoyaltyPoints:    visit.LoyaltyPoints,
+		InvoiceAvailable: visit.InvoiceID != nil || visit.CanBeInvoiced(),
+	}
+}
+
+// ToPortalVisitDTOs converts a slice of PortalVisit read models to DTOs
+func ToPortalVisitDTOs(visits []*domain.PortalVisit) []*PortalVisitDTO {
+	result := make([]*PortalVisitDTO, len(visits))
+	for i, visit := range visits {
+		result[i] = ToPortalVisitDTO(visit)
+	}
+	return result
+}
+
+// ToInvoiceResponseDTO converts an Invoice domain model to InvoiceResponseDTO
+func ToInvoiceResponseDTO(invoice *domain.Invoice) *InvoiceResponseDTO {
+	if invoice == nil {
+		return nil
+	}
+
+	lines, _ := invoice.GetLineItems()
+
+	return &InvoiceResponseDTO{
+		BaseResponse: BaseResponse{
+			ID:        invoice.ID,
+			CreatedAt: invoice.CreatedAt,
+			UpdatedAt: invoice.UpdatedAt,
+			Version:   invoice.Version,
+		},
+		BusinessID:    invoice.BusinessID,
+		AppointmentID: invoice.AppointmentID,
+		InvoiceNumber: invoice.InvoiceNumber,
+		IssuedAt:      invoice.IssuedAt,
+		Currency:      invoice.Currency,
+		BusinessName:  invoice.BusinessName,
+		BusinessTaxID: invoice.BusinessTaxID,
+		ClientName:    invoice.ClientName,
+		LineItems:     lines,
+		Total:         invoice.Total,
+		AmountPaid:    invoice.AmountPaid,
+		PaymentMethod: invoice.PaymentMethod,
+	}
+}
@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// ClientTimelineRequestDTO represents the data for reading a page of a client's timeline
+type ClientTimelineRequestDTO struct {
+	ClientID string `json:"client_id" validate:"required,uuid"`
+	After    string `json:"after,omitempty"` // Cursor of the last entry of the previous page
+	First    int    `json:"first,omitempty" validate:"omitempty,min=1,max=100"`
+}
+
+// TimelineEntryDTO represents one thing that happened to a client
+type TimelineEntryDTO struct {
+	ID            string                   `json:"id"`
+	Kind          domain.TimelineEntryKind `json:"kind"`
+	OccurredAt    time.Time                `json:"occurred_at"`
+	AppointmentID *string                  `json:"appointment_id,omitempty"`
+	Status        *string                  `json:"status,omitempty"`
+	Title         *string                  `json:"title,omitempty"`
+	Description   *string                  `json:"description,omitempty"`
+	Channel       *string                  `json:"channel,omitempty"`
+	StartTime     *time.Time               `json:"start_time,omitempty"`
+	Amount        *decimal.Decimal         `json:"amount,omitempty"`
+	Currency      *string                  `json:"currency,omitempty"`
+	Points        *int                     `json:"points,omitempty"`
+	Rating        *int                     `json:"rating,omitempty"`
+}
+
+// ToTimelineEntryDTO converts a timeline entry to a TimelineEntryDTO
+func ToTimelineEntryDTO(entry *domain.TimelineEntry) *TimelineEntryDTO {
+	return &TimelineEntryDTO{
+		ID:            entry.ID,
+		Kind:          entry.Kind,
+		OccurredAt:    entry.OccurredAt,
+		AppointmentID: entry.AppointmentID,
+		Status:        entry.Status,
+		Title:         entry.Title,
+		Description:   entry.Description,
+		Channel:       entry.Channel,
+		StartTime:     entry.StartTime,
+		Amount:        entry.Amount,
+		Currency:      entry.Currency,
+		Points:        entry.Points,
+		Rating:        entry.Rating,
+	}
+}
@@ -0,0 +1,105 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// CreateCommissionPlanDTO represents the data for setting a staff member's commission plan
+type CreateCommissionPlanDTO struct {
+	StaffID          string                             `json:"staff_id" validate:"required,uuid"`
+	EffectiveFrom    time.Time                          `json:"effective_from" validate:"required"`
+	ServiceTiers     []domain.CommissionTier            `json:"service_tiers" validate:"required,min=1"`
+	ProductRate      decimal.Decimal                    `json:"product_rate"`
+	ServiceOverrides []domain.ServiceCommissionOverride `json:"service_overrides,omitempty"`
+	Notes            *string                            `json:"notes,omitempty" validate:"omitempty,max=500"`
+}
+
+// CommissionPlanResponseDTO represents the response data for a staff commission plan
+type CommissionPlanResponseDTO struct {
+	BaseResponse
+	BusinessID       string                             `json:"business_id"`
+	StaffID          string                             `json:"staff_id"`
+	EffectiveFrom    time.Time                          `json:"effective_from"`
+	EffectiveTo      *time.Time                         `json:"effective_to,omitempty"`
+	ServiceTiers     []domain.CommissionTier            `json:"service_tiers"`
+	ProductRate      decimal.Decimal                    `json:"product_rate"`
+	ServiceOverrides []domain.ServiceCommissionOverride `json:"service_overrides"`
+	Notes            *string                            `json:"notes,omitempty"`
+	IsCurrent        bool                               `json:"is_current"`
+}
+
+// CommissionReportDTO represents the commission earned by a staff member over a period
+type CommissionReportDTO struct {
+	StaffID            string                  `json:"staff_id"`
+	PeriodStart        time.Time               `json:"period_start"`
+	PeriodEnd          time.Time               `json:"period_end"`
+	ServiceRevenue     decimal.Decimal         `json:"service_revenue"`
+	TieredRevenue      decimal.Decimal         `json:"tiered_revenue"`
+	OverrideRevenue    decimal.Decimal         `json:"override_revenue"`
+	ProductRevenue     decimal.Decimal         `json:"product_revenue"`
+	Tiers              []domain.TierCommission `json:"tiers"`
+	TieredCommission   decimal.Decimal         `json:"tiered_commission"`
+	OverrideCommission decimal.Decimal         `json:"override_commission"`
+	ProductCommission  decimal.Decimal         `json:"product_commission"`
+	Total              decimal.Decimal         `json:"total"`
+	PlanIDs            []string                `json:"plan_ids"`
+}
+
+// ToCommissionPlanResponseDTO converts a StaffCommissionPlan domain model to CommissionPlanResponseDTO
+func ToCommissionPlanResponseDTO(plan *domain.StaffCommissionPlan) *CommissionPlanResponseDTO {
+	if plan == nil {
+		return nil
+	}
+
+	tiers, _ := plan.GetServiceTiers()
+	overrides, _ := plan.GetServiceOverrides()
+
+	return &CommissionPlanResponseDTO{
+		BaseResponse: BaseResponse{
+			ID:        plan.ID,
+			CreatedAt: plan.CreatedAt,
+			UpdatedAt: plan.UpdatedAt,
+			Version:   plan.Version,
+		},
+		BusinessID:       plan.BusinessID,
+		StaffID:          plan.StaffID,
+		EffectiveFrom:    plan.EffectiveFrom,
+		EffectiveTo:      plan.EffectiveTo,
+		ServiceTiers:     tiers,
+		ProductRate:      plan.ProductRate,
+		ServiceOverrides: overrides,
+		Notes:            plan.Notes,
+		IsCurrent:        plan.IsEffectiveAt(time.Now()),
+	}
+}
+
+// ToCommissionPlanResponseDTOs converts a slice of StaffCommissionPlan domain models to DTOs
+func ToCommissionPlanResponseDTOs(plans []*domain.StaffCommissionPlan) []*CommissionPlanResponseDTO {
+	result := make([]*CommissionPlanResponseDTO, len(plans))
+	for i, plan := range plans {
+		result[i] = ToCommissionPlanResponseDTO(plan)
+	}
+	return result
+}
+
+// ToCommissionReportDTO converts a commission breakdown to CommissionReportDTO
+func ToCommissionReportDTO(staffID string, start, end time.Time, breakdown *domain.CommissionBreakdown, planIDs []string) *CommissionReportDTO {
+	return &CommissionReportDTO{
+		StaffID:            staffID,
+		PeriodStart:        start,
+		PeriodEnd:          end,
+		ServiceRevenue:     breakdown.ServiceRevenue,
+		TieredRevenue:      breakdown.TieredRevenue,
+		OverrideRevenue:    breakdown.OverrideRevenue,
+		ProductRevenue:     breakdown.ProductRevenue,
+		Tiers:              breakdown.Tiers,
+		TieredCommission:   breakdown.TieredCommission,
+		OverrideCommission: breakdown.OverrideCommission,
+		ProductCommission:  breakdown.ProductCommission,
+		Total:              breakdown.Total,
+		PlanIDs:            planIDs,
+	}
+}
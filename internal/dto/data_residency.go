@@ -0,0 +1,42 @@
+package dto
+
+import (
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// SetBusinessDataRegionDTO represents the data for changing the region a business's data must
+// stay in
+type SetBusinessDataRegionDTO struct {
+	BusinessID string            `json:"business_id" validate:"required,uuid"`
+	Region     domain.DataRegion `json:"region" validate:"required,oneof=eu us"`
+}
+
+// DataDestinationDTO represents a destination outside the database and whether a business's
+// data may be sent to it
+type DataDestinationDTO struct {
+	Name    string                     `json:"name"`
+	Kind    domain.DataDestinationKind `json:"kind"`
+	Region  *domain.DataRegion         `json:"region,omitempty"` // Unknown when nil
+	Allowed bool                       `json:"allowed"`
+}
+
+// DataResidencyDTO represents where a business's data may go
+type DataResidencyDTO struct {
+	BusinessID    string                `json:"business_id"`
+	Region        domain.DataRegion     `json:"region"`
+	StorageBucket *string               `json:"storage_bucket,omitempty"` // Where its files are stored, if a bucket permits it
+	Destinations  []*DataDestinationDTO `json:"destinations"`
+}
+
+// ToDataDestinationDTO converts a destination to a DTO for data of a region
+func ToDataDestinationDTO(destination domain.DataDestination, region domain.DataRegion) *DataDestinationDTO {
+	destinationDTO := &DataDestinationDTO{
+		Name:    destination.Name,
+		Kind:    destination.Kind,
+		Allowed: region.Permits(destination.Region),
+	}
+	if destination.Region != "" {
+		destinationDTO.Region = &destination.Region
+	}
+	return destinationDTO
+}
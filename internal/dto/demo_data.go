@@ -0,0 +1,33 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// SetBusinessSandboxDTO represents the request to mark a business as a sandbox or not
+type SetBusinessSandboxDTO struct {
+	BusinessID string `json:"business_id" validate:"required,uuid"`
+	IsSandbox  bool   `json:"is_sandbox"`
+}
+
+// DemoDataResetDTO summarizes the data a sandbox business was reset to
+type DemoDataResetDTO struct {
+	BusinessID      string    `json:"business_id"`
+	ServicesCreated int       `json:"services_created"` // Catalogue services the business did not have yet
+	Clients         int       `json:"clients"`
+	Appointments    int       `json:"appointments"`
+	ResetAt         time.Time `json:"reset_at"`
+}
+
+// ToDemoDataResetDTO summarizes a seeded demo dataset
+func ToDemoDataResetDTO(businessID string, dataset *domain.DemoDataset, resetAt time.Time) *DemoDataResetDTO {
+	return &DemoDataResetDTO{
+		BusinessID:      businessID,
+		ServicesCreated: len(dataset.Services),
+		Clients:         len(dataset.Clients),
+		Appointments:    len(dataset.Appointments),
+		ResetAt:         resetAt,
+	}
+}
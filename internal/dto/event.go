@@ -0,0 +1,119 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// DomainEventFilterDTO represents the criteria for browsing the event log
+type DomainEventFilterDTO struct {
+	BusinessID    *string    `json:"business_id,omitempty" validate:"omitempty,uuid"`
+	AggregateType *string    `json:"aggregate_type,omitempty" validate:"omitempty,max=50"`
+	AggregateID   *string    `json:"aggregate_id,omitempty" validate:"omitempty,uuid"`
+	EventType     *string    `json:"event_type,omitempty" validate:"omitempty,max=100"`
+	StartDate     *time.Time `json:"start_date,omitempty"`
+	EndDate       *time.Time `json:"end_date,omitempty"`
+}
+
+// ReplayEventsDTO represents a request to redeliver events to downstream consumers.
+// Either EventIDs or Filter selects the events; Consumers defaults to all registered consumers.
+type ReplayEventsDTO struct {
+	EventIDs  []string              `json:"event_ids,omitempty" validate:"omitempty,max=500,dive,uuid"`
+	Filter    *DomainEventFilterDTO `json:"filter,omitempty"`
+	Consumers []string              `json:"consumers,omitempty" validate:"omitempty,dive,required"`
+}
+
+// DomainEventResponseDTO represents the response data for a domain event
+type DomainEventResponseDTO struct {
+	BaseResponse
+	BusinessID      *string        `json:"business_id,omitempty"`
+	AggregateType   string         `json:"aggregate_type"`
+	AggregateID     string         `json:"aggregate_id"`
+	EventType       string         `json:"event_type"`
+	Payload         map[string]any `json:"payload"`
+	OccurredAt      time.Time      `json:"occurred_at"`
+	ReplayCount     int            `json:"replay_count"`
+	LastReplayedAt  *time.Time     `json:"last_replayed_at,omitempty"`
+	PublishedAt     *time.Time     `json:"published_at,omitempty"`
+	PublishAttempts int            `json:"publish_attempts"`
+	PublishError    *string        `json:"publish_error,omitempty"`
+	AbandonedAt     *time.Time     `json:"abandoned_at,omitempty"`
+}
+
+// EventDeliveryResultDTO represents the outcome of delivering one event to one consumer
+type EventDeliveryResultDTO struct {
+	EventID  string  `json:"event_id"`
+	Consumer string  `json:"consumer"`
+	Success  bool    `json:"success"`
+	Error    *string `json:"error,omitempty"`
+}
+
+// ReplayResultDTO represents the outcome of an event replay
+type ReplayResultDTO struct {
+	Events     int                       `json:"events"`
+	Succeeded  int                       `json:"succeeded"`
+	Failed     int                       `json:"failed"`
+	Deliveries []*EventDeliveryResultDTO `json:"deliveries"`
+}
+
+// ToDomainEventFilter converts a DomainEventFilterDTO to a domain filter
+func ToDomainEventFilter(filterDTO DomainEventFilterDTO) domain.DomainEventFilter {
+	filter := domain.DomainEventFilter{
+		BusinessID:    filterDTO.BusinessID,
+		AggregateType: filterDTO.AggregateType,
+		AggregateID:   filterDTO.AggregateID,
+		EventType:     filterDTO.EventType,
+	}
+	if filterDTO.StartDate != nil || filterDTO.EndDate != nil {
+		dateRange := &domain.DateRange{End: time.Now()}
+		if filterDTO.StartDate != nil {
+			dateRange.Start = *filterDTO.StartDate
+		}
+		if filterDTO.EndDate != nil {
+			dateRange.End = *filterDTO.EndDate
+		}
+		filter.DateRange = dateRange
+	}
+	return filter
+}
+
+// ToDomainEventResponseDTO converts a DomainEvent domain model to DomainEventResponseDTO
+func ToDomainEventResponseDTO(event *domain.DomainEvent) *DomainEventResponseDTO {
+	if event == nil {
+		return nil
+	}
+
+	payload := map[string]any{}
+	_ = event.GetPayload(&payload)
+
+	return &DomainEventResponseDTO{
+		BaseResponse: BaseResponse{
+			ID:        event.ID,
+			CreatedAt: event.CreatedAt,
+			UpdatedAt: event.UpdatedAt,
+			Version:   event.Version,
+		},
+		BusinessID:      event.BusinessID,
+		AggregateType:   event.AggregateType,
+		AggregateID:     event.AggregateID,
+		EventType:       event.EventType,
+		Payload:         payload,
+		OccurredAt:      event.OccurredAt,
+		ReplayCount:     event.ReplayCount,
+		LastReplayedAt:  event.LastReplayedAt,
+		PublishedAt:     event.PublishedAt,
+		PublishAttempts: event.PublishAttempts,
+		PublishError:    event.PublishError,
+		AbandonedAt:     event.AbandonedAt,
+	}
+}
+
+// ToDomainEventResponseDTOs converts a slice of DomainEvent domain models to DTOs
+func ToDomainEventResponseDTOs(events []*domain.DomainEvent) []*DomainEventResponseDTO {
+	result := make([]*DomainEventResponseDTO, len(events))
+	for i, event := range events {
+		result[i] = ToDomainEventResponseDTO(event)
+	}
+	return result
+}
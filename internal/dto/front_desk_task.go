@@ -0,0 +1,63 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// CompleteFrontDeskTaskDTO represents the request to mark a front desk task done
+type CompleteFrontDeskTaskDTO struct {
+	Outcome *string `json:"outcome,omitempty"`
+	// Confirmed confirms the appointment of a task to call the client on the client's behalf
+	Confirmed bool `json:"confirmed"`
+}
+
+// FrontDeskTaskDTO represents a to-do for the front desk of a business
+type FrontDeskTaskDTO struct {
+	BaseResponse
+	BusinessID    string                     `json:"business_id"`
+	AppointmentID *string                    `json:"appointment_id,omitempty"`
+	ClientID      *string                    `json:"client_id,omitempty"`
+	Kind          domain.FrontDeskTaskKind   `json:"kind"`
+	Status        domain.FrontDeskTaskStatus `json:"status"`
+	Reason        string                     `json:"reason"`
+	DueAt         time.Time                  `json:"due_at"`
+	CompletedAt   *time.Time                 `json:"completed_at,omitempty"`
+	CompletedBy   *string                    `json:"completed_by,omitempty"`
+	Outcome       *string                    `json:"outcome,omitempty"`
+}
+
+// ToFrontDeskTaskDTO converts a FrontDeskTask domain model to a DTO
+func ToFrontDeskTaskDTO(task *domain.FrontDeskTask) *FrontDeskTaskDTO {
+	if task == nil {
+		return nil
+	}
+	return &FrontDeskTaskDTO{
+		BaseResponse: BaseResponse{
+			ID:        task.ID,
+			CreatedAt: task.CreatedAt,
+			UpdatedAt: task.UpdatedAt,
+			Version:   task.Version,
+		},
+		BusinessID:    task.BusinessID,
+		AppointmentID: task.AppointmentID,
+		ClientID:      task.ClientID,
+		Kind:          task.Kind,
+		Status:        task.Status,
+		Reason:        task.Reason,
+		DueAt:         task.DueAt,
+		CompletedAt:   task.CompletedAt,
+		CompletedBy:   task.CompletedBy,
+		Outcome:       task.Outcome,
+	}
+}
+
+// ToFrontDeskTaskDTOs converts FrontDeskTask domain models to DTOs
+func ToFrontDeskTaskDTOs(tasks []*domain.FrontDeskTask) []*FrontDeskTaskDTO {
+	result := make([]*FrontDeskTaskDTO, len(tasks))
+	for i, task := range tasks {
+		result[i] = ToFrontDeskTaskDTO(task)
+	}
+	return result
+}
@@ -0,0 +1,120 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// SellGiftCardDTO represents the data for selling a gift card
+type SellGiftCardDTO struct {
+	BusinessID        string          `json:"business_id" validate:"required,uuid"`
+	Amount            decimal.Decimal `json:"amount"`
+	ExpiresAt         *time.Time      `json:"expires_at,omitempty"`
+	PurchaserClientID *string         `json:"purchaser_client_id,omitempty" validate:"omitempty,uuid"`
+	RecipientName     *string         `json:"recipient_name,omitempty" validate:"omitempty,max=200"`
+	RecipientEmail    *string         `json:"recipient_email,omitempty" validate:"omitempty,email,max=255"`
+	Message           *string         `json:"message,omitempty" validate:"omitempty,max=1000"`
+	PaymentMethod     string          `json:"payment_method" validate:"required,oneof=cash card transfer other"` // How the card itself is paid for
+}
+
+// RedeemGiftCardDTO represents the data for paying for a completed appointment with a gift card
+type RedeemGiftCardDTO struct {
+	AppointmentID  string           `json:"appointment_id" validate:"required,uuid"`
+	Code           string           `json:"code" validate:"required,max=20"`
+	Amount         *decimal.Decimal `json:"amount,omitempty"`                                     // Defaults to as much of the balance due as the card covers
+	PriceCharged   *decimal.Decimal `json:"price_charged,omitempty"`                              // Required unless the completion was recorded already
+	ActualDuration *int             `json:"actual_duration,omitempty" validate:"omitempty,min=1"` // Minutes
+}
+
+// GiftCardTransactionDTO represents a change to a gift card's balance
+type GiftCardTransactionDTO struct {
+	ID            string                         `json:"id"`
+	Kind          domain.GiftCardTransactionKind `json:"kind"`
+	Amount        decimal.Decimal                `json:"amount"`
+	BalanceAfter  decimal.Decimal                `json:"balance_after"`
+	PaymentID     *string                        `json:"payment_id,omitempty"`
+	AppointmentID *string                        `json:"appointment_id,omitempty"`
+	CreatedAt     time.Time                      `json:"created_at"`
+}
+
+// GiftCardResponseDTO represents the response data for a gift card
+type GiftCardResponseDTO struct {
+	BaseResponse
+	BusinessID        string                    `json:"business_id"`
+	Code              string                    `json:"code"`
+	InitialValue      decimal.Decimal           `json:"initial_value"`
+	Balance           decimal.Decimal           `json:"balance"`
+	Currency          string                    `json:"currency"`
+	ExpiresAt         *time.Time                `json:"expires_at,omitempty"`
+	Redeemable        bool                      `json:"redeemable"` // Whether a balance is left and the card has not expired
+	PurchaserClientID *string                   `json:"purchaser_client_id,omitempty"`
+	RecipientName     *string                   `json:"recipient_name,omitempty"`
+	RecipientEmail    *string                   `json:"recipient_email,omitempty"`
+	Message           *string                   `json:"message,omitempty"`
+	SalePaymentMethod string                    `json:"sale_payment_method"`
+	Transactions      []*GiftCardTransactionDTO `json:"transactions,omitempty"`
+}
+
+// GiftCardBalanceDTO represents what is left on a gift card, as checked at the till
+type GiftCardBalanceDTO struct {
+	Code       string          `json:"code"`
+	Balance    decimal.Decimal `json:"balance"`
+	Currency   string          `json:"currency"`
+	ExpiresAt  *time.Time      `json:"expires_at,omitempty"`
+	Redeemable bool            `json:"redeemable"`
+}
+
+// ToGiftCardResponseDTO converts a GiftCard domain model and its transactions to GiftCardResponseDTO.
+// Whether the card is redeemable is judged at now.
+func ToGiftCardResponseDTO(card *domain.GiftCard, transactions []*domain.GiftCardTransaction, now time.Time) *GiftCardResponseDTO {
+	if card == nil {
+		return nil
+	}
+
+	response := &GiftCardResponseDTO{
+		BaseResponse: BaseResponse{
+			ID:        card.ID,
+			CreatedAt: card.CreatedAt,
+			UpdatedAt: card.UpdatedAt,
+			Version:   card.Version,
+		},
+		BusinessID:        card.BusinessID,
+		Code:              card.Code,
+		InitialValue:      card.InitialValue,
+		Balance:           card.Balance,
+		Currency:          card.Currency,
+		ExpiresAt:         card.ExpiresAt,
+		Redeemable:        card.IsRedeemable(now),
+		PurchaserClientID: card.PurchaserClientID,
+		RecipientName:     card.RecipientName,
+		RecipientEmail:    card.RecipientEmail,
+		Message:           card.Message,
+		SalePaymentMethod: card.SalePaymentMethod,
+		Transactions:      make([]*GiftCardTransactionDTO, len(transactions)),
+	}
+	for i, transaction := range transactions {
+		response.Transactions[i] = &GiftCardTransactionDTO{
+			ID:            transaction.ID,
+			Kind:          transaction.Kind,
+			Amount:        transaction.Amount,
+			BalanceAfter:  transaction.BalanceAfter,
+			PaymentID:     transaction.PaymentID,
+			AppointmentID: transaction.AppointmentID,
+			CreatedAt:     transaction.CreatedAt,
+		}
+	}
+	return response
+}
+
+// ToGiftCardBalanceDTO converts a GiftCard domain model to GiftCardBalanceDTO
+func ToGiftCardBalanceDTO(card *domain.GiftCard, now time.Time) *GiftCardBalanceDTO {
+	return &GiftCardBalanceDTO{
+		Code:       card.Code,
+		Balance:    card.Balance,
+		Currency:   card.Currency,
+		ExpiresAt:  card.ExpiresAt,
+		Redeemable: card.IsRedeemable(now),
+	}
+}
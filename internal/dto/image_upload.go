@@ -0,0 +1,80 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// CreateImageUploadDTO represents a request for a URL to upload an image to
+type CreateImageUploadDTO struct {
+	BusinessID  string              `json:"business_id" validate:"required,uuid"`
+	Purpose     domain.ImagePurpose `json:"purpose" validate:"required,oneof=business_logo business_cover staff_photo"`
+	StaffID     *string             `json:"staff_id,omitempty" validate:"omitempty,uuid"` // Whose photo it is; required for staff photos
+	ContentType string              `json:"content_type" validate:"required,oneof=image/jpeg image/png image/gif"`
+	SizeBytes   int64               `json:"size_bytes" validate:"required,min=1,max=10485760"` // The exact size of the file
+}
+
+// ImageUploadResponseDTO represents an image upload and how far it has got
+type ImageUploadResponseDTO struct {
+	BaseResponse
+	BusinessID  string                   `json:"business_id"`
+	Purpose     domain.ImagePurpose      `json:"purpose"`
+	TargetID    string                   `json:"target_id"`
+	ContentType string                   `json:"content_type"`
+	SizeBytes   int64                    `json:"size_bytes"`
+	Status      domain.ImageUploadStatus `json:"status"`
+	URL         *string                  `json:"url,omitempty"`
+	Width       *int                     `json:"width,omitempty"`
+	Height      *int                     `json:"height,omitempty"`
+	Error       *string                  `json:"error,omitempty"`
+	AttachedAt  *time.Time               `json:"attached_at,omitempty"`
+}
+
+// ToImageUploadResponseDTO converts an ImageUpload domain model to ImageUploadResponseDTO
+func ToImageUploadResponseDTO(upload *domain.ImageUpload) *ImageUploadResponseDTO {
+	if upload == nil {
+		return nil
+	}
+
+	return &ImageUploadResponseDTO{
+		BaseResponse: BaseResponse{
+			ID:        upload.ID,
+			CreatedAt: upload.CreatedAt,
+			UpdatedAt: upload.UpdatedAt,
+			Version:   upload.Version,
+		},
+		BusinessID:  upload.BusinessID,
+		Purpose:     upload.Purpose,
+		TargetID:    upload.TargetID,
+		ContentType: upload.ContentType,
+		SizeBytes:   upload.SizeBytes,
+		Status:      upload.Status,
+		URL:         upload.URL,
+		Width:       upload.Width,
+		Height:      upload.Height,
+		Error:       upload.Error,
+		AttachedAt:  upload.AttachedAt,
+	}
+}
+
+// UploadHeaderDTO represents a header an upload must be sent with
+type UploadHeaderDTO struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// ImageUploadTicketDTO represents a presigned URL the file of an image upload is sent to
+type ImageUploadTicketDTO struct {
+	Upload    *ImageUploadResponseDTO `json:"upload"`
+	UploadURL string                  `json:"upload_url"`
+	Method    string                  `json:"method"`
+	Headers   []UploadHeaderDTO       `json:"headers"`    // Signed, so they must be sent as given
+	ExpiresAt time.Time               `json:"expires_at"` // When the URL stops working
+}
+
+// ImageCleanupRunDTO represents a removal of the files of image uploads no longer shown anywhere
+type ImageCleanupRunDTO struct {
+	Removed int `json:"removed"`
+	Failed  int `json:"failed"`
+}
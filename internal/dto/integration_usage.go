@@ -0,0 +1,40 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// IntegrationUsageSourceDTO represents the traffic of a single API key or webhook endpoint on a day
+type IntegrationUsageSourceDTO struct {
+	Channel   domain.IntegrationChannel `json:"channel"`
+	SourceID  string                    `json:"source_id"`
+	Requests  int                       `json:"requests"`
+	Errors    int                       `json:"errors"`
+	ErrorRate float64                   `json:"error_rate"`
+}
+
+// IntegrationUsageDayDTO represents a business's integration traffic on a single day
+type IntegrationUsageDayDTO struct {
+	Date               time.Time                    `json:"date"`
+	APIRequests        int                          `json:"api_requests"`
+	APIErrors          int                          `json:"api_errors"`
+	APIErrorRate       float64                      `json:"api_error_rate"`
+	WebhookDeliveries  int                          `json:"webhook_deliveries"`
+	WebhookFailures    int                          `json:"webhook_failures"`
+	WebhookSuccessRate float64                      `json:"webhook_success_rate"`
+	Sources            []*IntegrationUsageSourceDTO `json:"sources"`
+}
+
+// IntegrationUsageReportDTO represents a business's integration traffic over a date range
+type IntegrationUsageReportDTO struct {
+	BusinessID         string                    `json:"business_id"`
+	StartDate          time.Time                 `json:"start_date"`
+	EndDate            time.Time                 `json:"end_date"`
+	APIRequests        int                       `json:"api_requests"`
+	APIErrorRate       float64                   `json:"api_error_rate"`
+	WebhookDeliveries  int                       `json:"webhook_deliveries"`
+	WebhookSuccessRate float64                   `json:"webhook_success_rate"`
+	Days               []*IntegrationUsageDayDTO `json:"days"`
+}
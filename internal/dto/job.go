@@ -0,0 +1,80 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// JobFilterDTO represents the criteria for browsing background jobs
+type JobFilterDTO struct {
+	Status     *string `json:"status,omitempty" validate:"omitempty,oneof=pending succeeded dead cancelled"`
+	Kind       *string `json:"kind,omitempty" validate:"omitempty,max=100"`
+	BusinessID *string `json:"business_id,omitempty" validate:"omitempty,uuid"`
+}
+
+// JobResponseDTO represents the response data for a background job
+type JobResponseDTO struct {
+	BaseResponse
+	BusinessID  *string        `json:"business_id,omitempty"`
+	Kind        string         `json:"kind"`
+	UniqueKey   *string        `json:"unique_key,omitempty"`
+	Payload     map[string]any `json:"payload"`
+	Status      string         `json:"status"`
+	RunAt       time.Time      `json:"run_at"`
+	Attempts    int            `json:"attempts"`
+	MaxAttempts int            `json:"max_attempts"`
+	LastError   *string        `json:"last_error,omitempty"`
+	CompletedAt *time.Time     `json:"completed_at,omitempty"`
+}
+
+// ToJobFilter converts a JobFilterDTO to a domain filter
+func ToJobFilter(filterDTO JobFilterDTO) domain.JobFilter {
+	filter := domain.JobFilter{
+		Kind:       filterDTO.Kind,
+		BusinessID: filterDTO.BusinessID,
+	}
+	if filterDTO.Status != nil {
+		status := domain.JobStatus(*filterDTO.Status)
+		filter.Status = &status
+	}
+	return filter
+}
+
+// ToJobResponseDTO converts a Job domain model to JobResponseDTO
+func ToJobResponseDTO(job *domain.Job) *JobResponseDTO {
+	if job == nil {
+		return nil
+	}
+
+	payload := map[string]any{}
+	_ = job.DecodePayload(&payload)
+
+	return &JobResponseDTO{
+		BaseResponse: BaseResponse{
+			ID:        job.ID,
+			CreatedAt: job.CreatedAt,
+			UpdatedAt: job.UpdatedAt,
+			Version:   job.Version,
+		},
+		BusinessID:  job.BusinessID,
+		Kind:        job.Kind,
+		UniqueKey:   job.UniqueKey,
+		Payload:     payload,
+		Status:      string(job.Status),
+		RunAt:       job.RunAt,
+		Attempts:    job.Attempts,
+		MaxAttempts: job.MaxAttempts,
+		LastError:   job.LastError,
+		CompletedAt: job.CompletedAt,
+	}
+}
+
+// ToJobResponseDTOs converts a slice of Job domain models to DTOs
+func ToJobResponseDTOs(jobs []*domain.Job) []*JobResponseDTO {
+	result := make([]*JobResponseDTO, len(jobs))
+	for i, job := range jobs {
+		result[i] = ToJobResponseDTO(job)
+	}
+	return result
+}
@@ -0,0 +1,245 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// CreateMembershipPlanDTO represents the data for creating a membership plan
+type CreateMembershipPlanDTO struct {
+	BusinessID  string          `json:"business_id" validate:"required,uuid"`
+	Name        string          `json:"name" validate:"required,max=100"`
+	Description *string         `json:"description,omitempty" validate:"omitempty,max=1000"`
+	Price       decimal.Decimal `json:"price"` // Charged every month, in the business's currency
+	// SessionsPerPeriod makes the plan a package of sessions renewed each month
+	SessionsPerPeriod      *int                `json:"sessions_per_period,omitempty" validate:"omitempty,min=1"`
+	RefundPolicy           domain.RefundPolicy `json:"refund_policy,omitempty" validate:"omitempty,oneof=none refund credit"` // None when not given
+	CancellationFeePercent int                 `json:"cancellation_fee_percent" validate:"min=0,max=100"`
+}
+
+// SubscribeMembershipDTO represents the data for subscribing a client to a membership plan
+type SubscribeMembershipDTO struct {
+	PlanID           string  `json:"plan_id" validate:"required,uuid"`
+	ClientID         string  `json:"client_id" validate:"required,uuid"`
+	PaymentMethodID  string  `json:"payment_method_id" validate:"required,max=255"` // The stored card or mandate charged each period
+	StripeCustomerID *string `json:"stripe_customer_id,omitempty" validate:"omitempty,max=255"`
+}
+
+// UpdateMembershipPaymentMethodDTO represents the data for replacing the card a membership is
+// billed to
+type UpdateMembershipPaymentMethodDTO struct {
+	MembershipID     string  `json:"membership_id" validate:"required,uuid"`
+	PaymentMethodID  string  `json:"payment_method_id" validate:"required,max=255"`
+	StripeCustomerID *string `json:"stripe_customer_id,omitempty" validate:"omitempty,max=255"`
+}
+
+// MembershipPlanResponseDTO represents the response data for a membership plan
+type MembershipPlanResponseDTO struct {
+	BaseResponse
+	BusinessID             string                 `json:"business_id"`
+	Name                   string                 `json:"name"`
+	Description            *string                `json:"description,omitempty"`
+	Price                  decimal.Decimal        `json:"price"`
+	Currency               string                 `json:"currency"`
+	BillingInterval        domain.BillingInterval `json:"billing_interval"`
+	SessionsPerPeriod      *int                   `json:"sessions_per_period,omitempty"`
+	RefundPolicy           domain.RefundPolicy    `json:"refund_policy"`
+	CancellationFeePercent int                    `json:"cancellation_fee_percent"`
+	IsActive               bool                   `json:"is_active"`
+}
+
+// MembershipInvoiceDTO represents the invoice of one period of a membership
+type MembershipInvoiceDTO struct {
+	ID            string                         `json:"id"`
+	PeriodStart   time.Time                      `json:"period_start"`
+	PeriodEnd     time.Time                      `json:"period_end"`
+	Amount        decimal.Decimal                `json:"amount"`
+	Currency      string                         `json:"currency"`
+	Status        domain.MembershipInvoiceStatus `json:"status"`
+	Attempts      int                            `json:"attempts"`
+	FailureReason *string                        `json:"failure_reason,omitempty"`
+	PaidAt        *time.Time                     `json:"paid_at,omitempty"`
+}
+
+// MembershipCreditNoteDTO represents what was given back for the unused part of a paid period
+// when a membership was cancelled
+type MembershipCreditNoteDTO struct {
+	ID                string                `json:"id"`
+	InvoiceID         string                `json:"invoice_id"`
+	Kind              domain.CreditNoteKind `json:"kind"`
+	UnusedShare       decimal.Decimal       `json:"unused_share"`
+	Fee               decimal.Decimal       `json:"fee"`
+	Amount            decimal.Decimal       `json:"amount"`
+	Currency          string                `json:"currency"`
+	SessionsForfeited int                   `json:"sessions_forfeited"`
+	RefundID          *string               `json:"refund_id,omitempty"`
+	GiftCardID        *string               `json:"gift_card_id,omitempty"`
+	CreatedAt         time.Time             `json:"created_at"`
+}
+
+// MembershipCancellationQuoteDTO represents what cancelling a membership now would give back
+type MembershipCancellationQuoteDTO struct {
+	MembershipID      string              `json:"membership_id"`
+	RefundPolicy      domain.RefundPolicy `json:"refund_policy"`
+	PaidAmount        decimal.Decimal     `json:"paid_amount"` // For the current period
+	UnusedShare       decimal.Decimal     `json:"unused_share"`
+	Unused            decimal.Decimal     `json:"unused"`
+	Fee               decimal.Decimal     `json:"fee"`
+	Amount            decimal.Decimal     `json:"amount"` // Refunded or credited
+	Currency          string              `json:"currency"`
+	SessionsRemaining int                 `json:"sessions_remaining"`
+}
+
+// ClientMembershipResponseDTO represents the response data for a client's membership, with its
+// billing status
+type ClientMembershipResponseDTO struct {
+	BaseResponse
+	BusinessID         string                     `json:"business_id"`
+	PlanID             string                     `json:"plan_id"`
+	PlanName           string                     `json:"plan_name"`
+	ClientID           string                     `json:"client_id"`
+	Status             domain.MembershipStatus    `json:"status"`
+	HasAccess          bool                       `json:"has_access"`
+	Price              decimal.Decimal            `json:"price"`
+	Currency           string                     `json:"currency"`
+	CurrentPeriodStart *time.Time                 `json:"current_period_start,omitempty"`
+	CurrentPeriodEnd   *time.Time                 `json:"current_period_end,omitempty"`
+	SessionsPerPeriod  *int                       `json:"sessions_per_period,omitempty"`
+	SessionsRemaining  *int                       `json:"sessions_remaining,omitempty"`
+	NextBillingAt      *time.Time                 `json:"next_billing_at,omitempty"` // Not set once cancelled
+	FailedAttempts     int                        `json:"failed_attempts"`
+	NextRetryAt        *time.Time                 `json:"next_retry_at,omitempty"`
+	GraceUntil         *time.Time                 `json:"grace_until,omitempty"`
+	SuspendedAt        *time.Time                 `json:"suspended_at,omitempty"`
+	CancelledAt        *time.Time                 `json:"cancelled_at,omitempty"`
+	AmountDue          decimal.Decimal            `json:"amount_due"` // Of the open invoice
+	Invoices           []*MembershipInvoiceDTO    `json:"invoices"`
+	CreditNotes        []*MembershipCreditNoteDTO `json:"credit_notes"`
+}
+
+// MembershipBillingRunDTO summarizes a run of membership billing
+type MembershipBillingRunDTO struct {
+	Due       int `json:"due"`
+	Paid      int `json:"paid"`
+	Failed    int `json:"failed"`    // Payments declined, to be retried within the grace period
+	Suspended int `json:"suspended"` // Memberships whose grace period ended unpaid
+	Errors    int `json:"errors"`    // Memberships left for the next run, e.g. because the provider was down
+}
+
+// ToMembershipPlanResponseDTO converts a MembershipPlan domain model to MembershipPlanResponseDTO
+func ToMembershipPlanResponseDTO(plan *domain.MembershipPlan) *MembershipPlanResponseDTO {
+	if plan == nil {
+		return nil
+	}
+
+	return &MembershipPlanResponseDTO{
+		BaseResponse: BaseResponse{
+			ID:        plan.ID,
+			CreatedAt: plan.CreatedAt,
+			UpdatedAt: plan.UpdatedAt,
+			Version:   plan.Version,
+		},
+		BusinessID:             plan.BusinessID,
+		Name:                   plan.Name,
+		Description:            plan.Description,
+		Price:                  plan.Price,
+		Currency:               plan.Currency,
+		BillingInterval:        plan.BillingInterval,
+		SessionsPerPeriod:      plan.SessionsPerPeriod,
+		RefundPolicy:           plan.RefundPolicy,
+		CancellationFeePercent: plan.CancellationFeePercent,
+		IsActive:               plan.IsActive,
+	}
+}
+
+// ToClientMembershipResponseDTO converts a ClientMembership domain model, its plan, its invoices
+// and its credit notes to ClientMembershipResponseDTO. Access is judged at now.
+func ToClientMembershipResponseDTO(membership *domain.ClientMembership, plan *domain.MembershipPlan, invoices []*domain.MembershipInvoice, creditNotes []*domain.MembershipCreditNote, now time.Time) *ClientMembershipResponseDTO {
+	if membership == nil {
+		return nil
+	}
+
+	response := &ClientMembershipResponseDTO{
+		BaseResponse: BaseResponse{
+			ID:        membership.ID,
+			CreatedAt: membership.CreatedAt,
+			UpdatedAt: membership.UpdatedAt,
+			Version:   membership.Version,
+		},
+		BusinessID:         membership.BusinessID,
+		PlanID:             membership.PlanID,
+		ClientID:           membership.ClientID,
+		Status:             membership.Status,
+		HasAccess:          membership.HasAccess(now),
+		Price:              membership.Price,
+		Currency:           membership.Currency,
+		CurrentPeriodStart: membership.CurrentPeriodStart,
+		CurrentPeriodEnd:   membership.CurrentPeriodEnd,
+		SessionsPerPeriod:  membership.SessionsPerPeriod,
+		SessionsRemaining:  membership.SessionsRemaining,
+		FailedAttempts:     membership.FailedAttempts,
+		NextRetryAt:        membership.NextRetryAt,
+		GraceUntil:         membership.GraceUntil,
+		SuspendedAt:        membership.SuspendedAt,
+		CancelledAt:        membership.CancelledAt,
+		AmountDue:          decimal.Zero,
+		Invoices:           make([]*MembershipInvoiceDTO, 0, len(invoices)),
+		CreditNotes:        make([]*MembershipCreditNoteDTO, 0, len(creditNotes)),
+	}
+	if plan != nil {
+		response.PlanName = plan.Name
+	}
+	if membership.Status == domain.MembershipActive {
+		response.NextBillingAt = &membership.NextBillingAt
+	}
+	for _, invoice := range invoices {
+		if invoice.Status == domain.MembershipInvoiceOpen {
+			response.AmountDue = response.AmountDue.Add(invoice.Amount)
+		}
+		response.Invoices = append(response.Invoices, &MembershipInvoiceDTO{
+			ID:            invoice.ID,
+			PeriodStart:   invoice.PeriodStart,
+			PeriodEnd:     invoice.PeriodEnd,
+			Amount:        invoice.Amount,
+			Currency:      invoice.Currency,
+			Status:        invoice.Status,
+			Attempts:      invoice.Attempts,
+			FailureReason: invoice.FailureReason,
+			PaidAt:        invoice.PaidAt,
+		})
+	}
+	for _, creditNote := range creditNotes {
+		response.CreditNotes = append(response.CreditNotes, &MembershipCreditNoteDTO{
+			ID:                creditNote.ID,
+			InvoiceID:         creditNote.InvoiceID,
+			Kind:              creditNote.Kind,
+			UnusedShare:       creditNote.UnusedShare,
+			Fee:               creditNote.Fee,
+			Amount:            creditNote.Amount,
+			Currency:          creditNote.Currency,
+			SessionsForfeited: creditNote.SessionsForfeited,
+			RefundID:          creditNote.RefundID,
+			GiftCardID:        creditNote.GiftCardID,
+			CreatedAt:         creditNote.CreatedAt,
+		})
+	}
+	return response
+}
+
+// ToMembershipCancellationQuoteDTO converts the pro-ration of a membership to
+// MembershipCancellationQuoteDTO
+func ToMembershipCancellationQuoteDTO(membership *domain.ClientMembership, proration domain.ProRation) *MembershipCancellationQuoteDTO {
+	return &MembershipCancellationQuoteDTO{
+		MembershipID:      membership.ID,
+		RefundPolicy:      proration.Policy,
+		PaidAmount:        proration.PaidAmount,
+		UnusedShare:       proration.UnusedShare,
+		Unused:            proration.Unused,
+		Fee:               proration.Fee,
+		Amount:            proration.Amount,
+		Currency:          proration.Currency,
+		SessionsRemaining: proration.SessionsRemaining,
+	}
+}
@@ -0,0 +1,96 @@
+package dto
+
+import (
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// CreateMessageTemplateDTO represents the data for creating a message template
+type CreateMessageTemplateDTO struct {
+	BusinessID string                `json:"business_id" validate:"required,uuid"`
+	Name       string                `json:"name" validate:"required,min=2,max=100"`
+	Channel    domain.MessageChannel `json:"channel" validate:"required,oneof=email sms"`
+	Purpose    *string               `json:"purpose,omitempty" validate:"omitempty,max=50"`
+	Subject    *string               `json:"subject,omitempty" validate:"omitempty,max=200"`
+	Body       string                `json:"body" validate:"required,max=5000"`
+	IsActive   bool                  `json:"is_active"`
+}
+
+// UpdateMessageTemplateDTO represents the data for updating a message template
+type UpdateMessageTemplateDTO struct {
+	Name     *string          `json:"name,omitempty" validate:"omitempty,min=2,max=100"`
+	Purpose  Optional[string] `json:"purpose" validate:"omitempty,max=50"`
+	Subject  Optional[string] `json:"subject" validate:"omitempty,max=200"`
+	Body     *string          `json:"body,omitempty" validate:"omitempty,max=5000"`
+	IsActive *bool            `json:"is_active,omitempty"`
+}
+
+// MessageTemplateContentDTO represents unsaved template content to lint or preview
+type MessageTemplateContentDTO struct {
+	Channel domain.MessageChannel `json:"channel" validate:"required,oneof=email sms"`
+	Subject *string               `json:"subject,omitempty" validate:"omitempty,max=200"`
+	Body    string                `json:"body" validate:"max=5000"`
+}
+
+// PreviewTemplateDTO represents a request to render a saved template or unsaved content.
+// Without a client or appointment, sample values are used.
+type PreviewTemplateDTO struct {
+	BusinessID    string                     `json:"business_id" validate:"required,uuid"`
+	TemplateID    *string                    `json:"template_id,omitempty" validate:"omitempty,uuid"`
+	Content       *MessageTemplateContentDTO `json:"content,omitempty"`
+	ClientID      *string                    `json:"client_id,omitempty" validate:"omitempty,uuid"`
+	AppointmentID *string                    `json:"appointment_id,omitempty" validate:"omitempty,uuid"`
+}
+
+// MessageTemplateResponseDTO represents the response data for a message template
+type MessageTemplateResponseDTO struct {
+	BaseResponse
+	BusinessID string                     `json:"business_id"`
+	Name       string                     `json:"name"`
+	Channel    domain.MessageChannel      `json:"channel"`
+	Purpose    *string                    `json:"purpose,omitempty"`
+	Subject    *string                    `json:"subject,omitempty"`
+	Body       string                     `json:"body"`
+	IsActive   bool                       `json:"is_active"`
+	LintIssues []domain.TemplateLintIssue `json:"lint_issues"`
+}
+
+// TemplatePreviewDTO represents a rendered message template
+type TemplatePreviewDTO struct {
+	Subject    *string                    `json:"subject,omitempty"`
+	Body       string                     `json:"body"`
+	SMS        *domain.SMSInfo            `json:"sms,omitempty"` // SMS templates only
+	LintIssues []domain.TemplateLintIssue `json:"lint_issues"`
+}
+
+// ToMessageTemplateResponseDTO converts a MessageTemplate domain model to MessageTemplateResponseDTO
+func ToMessageTemplateResponseDTO(template *domain.MessageTemplate) *MessageTemplateResponseDTO {
+	if template == nil {
+		return nil
+	}
+
+	return &MessageTemplateResponseDTO{
+		BaseResponse: BaseResponse{
+			ID:        template.ID,
+			CreatedAt: template.CreatedAt,
+			UpdatedAt: template.UpdatedAt,
+			Version:   template.Version,
+		},
+		BusinessID: template.BusinessID,
+		Name:       template.Name,
+		Channel:    template.Channel,
+		Purpose:    template.Purpose,
+		Subject:    template.Subject,
+		Body:       template.Body,
+		IsActive:   template.IsActive,
+		LintIssues: template.Lint(),
+	}
+}
+
+// ToMessageTemplateResponseDTOs converts a slice of MessageTemplate domain models to DTOs
+func ToMessageTemplateResponseDTOs(templates []*domain.MessageTemplate) []*MessageTemplateResponseDTO {
+	result := make([]*MessageTemplateResponseDTO, len(templates))
+	for i, template := range templates {
+		result[i] = ToMessageTemplateResponseDTO(template)
+	}
+	return result
+}
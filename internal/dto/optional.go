@@ -0,0 +1,89 @@
+package dto
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Optional represents a nullable field of a partial update. Unlike a plain pointer it tells
+// "leave unchanged" (not set) apart from "clear" (set to null).
+type Optional[T any] struct {
+	Set   bool // Whether the update touches the field
+	Value *T   // The new value, or nil to clear the field
+}
+
+// Some returns an Optional setting the field to a value
+func Some[T any](value T) Optional[T] {
+	return Optional[T]{Set: true, Value: &value}
+}
+
+// Null returns an Optional clearing the field
+func Null[T any]() Optional[T] {
+	return Optional[T]{Set: true}
+}
+
+// IsNull reports whether the update clears the field
+func (o Optional[T]) IsNull() bool {
+	return o.Set && o.Value == nil
+}
+
+// Apply writes the update to a nullable field, leaving it alone when the update does not touch it
+func (o Optional[T]) Apply(field **T) {
+	if o.Set {
+		*field = o.Value
+	}
+}
+
+// Clear makes the update clear the field
+func (o *Optional[T]) Clear() {
+	o.Set = true
+	o.Value = nil
+}
+
+// MarshalJSON encodes the value, or null when the field is cleared or untouched
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(o.Value)
+}
+
+// UnmarshalJSON marks the field as set; a JSON null clears it
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	o.Value = nil
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return nil
+	}
+	return json.Unmarshal(data, &o.Value)
+}
+
+// validationValue returns what validation rules see: the value, or nil when there is none
+func (o Optional[T]) validationValue() any {
+	if o.Value == nil {
+		return nil
+	}
+	return *o.Value
+}
+
+// RegisterOptionalTypes teaches a validator to apply the rules of Optional fields to their
+// values, so `validate:"omitempty,max=100"` works as it does on pointer fields. Every Optional
+// instantiation used in a DTO must be listed here.
+func RegisterOptionalTypes(v *validator.Validate) {
+	v.RegisterCustomTypeFunc(func(field reflect.Value) any {
+		if optional, ok := field.Interface().(interface{ validationValue() any }); ok {
+			return optional.validationValue()
+		}
+		return nil
+	}, Optional[string]{}, Optional[int]{}, Optional[time.Time]{})
+}
+
+// Change reports whether the update touches the field, and the value it writes: the value, or
+// nil to clear the field. It makes Optional a domain.Changer.
+func (o Optional[T]) Change() (any, bool) {
+	if o.Value == nil {
+		return nil, o.Set
+	}
+	return *o.Value, o.Set
+}
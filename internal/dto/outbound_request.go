@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// OutboundRequestFilterDTO represents the criteria for finding outbound requests
+type OutboundRequestFilterDTO struct {
+	Provider   *string    `json:"provider,omitempty"`
+	FailedOnly bool       `json:"failed_only"`
+	StartDate  *time.Time `json:"start_date,omitempty"`
+	EndDate    *time.Time `json:"end_date,omitempty"`
+	Limit      int        `json:"limit" validate:"omitempty,min=1,max=200"`
+}
+
+// OutboundRequestDTO represents a call made to a third-party provider
+type OutboundRequestDTO struct {
+	ID           string    `json:"id"`
+	Provider     string    `json:"provider"`
+	Method       string    `json:"method"`
+	Endpoint     string    `json:"endpoint"`
+	StatusCode   *int      `json:"status_code,omitempty"`
+	LatencyMs    int64     `json:"latency_ms"`
+	Failed       bool      `json:"failed"`
+	RequestBody  *string   `json:"request_body,omitempty"`
+	ResponseBody *string   `json:"response_body,omitempty"`
+	Error        *string   `json:"error,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// ToOutboundRequestDTO converts an OutboundRequest domain model to OutboundRequestDTO
+func ToOutboundRequestDTO(request *domain.OutboundRequest) *OutboundRequestDTO {
+	if request == nil {
+		return nil
+	}
+
+	return &OutboundRequestDTO{
+		ID:           request.ID,
+		Provider:     request.Provider,
+		Method:       request.Method,
+		Endpoint:     request.Endpoint,
+		StatusCode:   request.StatusCode,
+		LatencyMs:    request.LatencyMs,
+		Failed:       request.Failed(),
+		RequestBody:  request.RequestBody,
+		ResponseBody: request.ResponseBody,
+		Error:        request.Error,
+		CreatedAt:    request.CreatedAt,
+	}
+}
+
+// ToOutboundRequestDTOs converts a slice of OutboundRequest domain models to DTOs
+func ToOutboundRequestDTOs(requests []*domain.OutboundRequest) []*OutboundRequestDTO {
+	result := make([]*OutboundRequestDTO, len(requests))
+	for i, request := range requests {
+		result[i] = ToOutboundRequestDTO(request)
+	}
+	return result
+}
@@ -0,0 +1,91 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// ChargeDepositDTO represents the data for charging the deposit of a booked appointment
+type ChargeDepositDTO struct {
+	AppointmentID   string           `json:"appointment_id" validate:"required,uuid"`
+	PaymentMethodID string           `json:"payment_method_id" validate:"required,max=255"`
+	Amount          *decimal.Decimal `json:"amount,omitempty"` // Defaults to the deposits of the booked services
+}
+
+// CaptureCompletionPaymentDTO represents the data for taking payment for a completed appointment
+type CaptureCompletionPaymentDTO struct {
+	AppointmentID   string           `json:"appointment_id" validate:"required,uuid"`
+	PaymentMethodID string           `json:"payment_method_id" validate:"required,max=255"`
+	PriceCharged    *decimal.Decimal `json:"price_charged,omitempty"`                              // Required unless the completion was recorded already
+	ActualDuration  *int             `json:"actual_duration,omitempty" validate:"omitempty,min=1"` // Minutes
+}
+
+// RefundPaymentDTO represents the data for refunding a payment
+type RefundPaymentDTO struct {
+	PaymentID string           `json:"payment_id" validate:"required,uuid"`
+	Amount    *decimal.Decimal `json:"amount,omitempty"` // Defaults to everything not refunded yet
+}
+
+// ServiceDepositDTO represents the deposit charged at booking for a service
+type ServiceDepositDTO struct {
+	ServiceID       string           `json:"service_id" validate:"required,uuid"`
+	RequiresDeposit bool             `json:"requires_deposit"`
+	DepositAmount   *decimal.Decimal `json:"deposit_amount,omitempty"`
+}
+
+// PaymentResponseDTO represents the response data for a payment
+type PaymentResponseDTO struct {
+	BaseResponse
+	BusinessID          string               `json:"business_id"`
+	AppointmentID       string               `json:"appointment_id"`
+	ServiceCompletionID *string              `json:"service_completion_id,omitempty"`
+	Kind                domain.PaymentKind   `json:"kind"`
+	Status              domain.PaymentStatus `json:"status"`
+	Amount              decimal.Decimal      `json:"amount"`
+	RefundedAmount      decimal.Decimal      `json:"refunded_amount"`
+	Currency            string               `json:"currency"`
+	PaymentIntentID     *string              `json:"payment_intent_id,omitempty"`
+	ClientSecret        *string              `json:"client_secret,omitempty"` // Only while the client has to authenticate the card
+	GiftCardID          *string              `json:"gift_card_id,omitempty"`  // Set for payments redeemed from a gift card
+	FailureReason       *string              `json:"failure_reason,omitempty"`
+	PaidAt              *time.Time           `json:"paid_at,omitempty"`
+}
+
+// ToPaymentResponseDTO converts a Payment domain model to PaymentResponseDTO
+func ToPaymentResponseDTO(payment *domain.Payment) *PaymentResponseDTO {
+	if payment == nil {
+		return nil
+	}
+
+	return &PaymentResponseDTO{
+		BaseResponse: BaseResponse{
+			ID:        payment.ID,
+			CreatedAt: payment.CreatedAt,
+			UpdatedAt: payment.UpdatedAt,
+			Version:   payment.Version,
+		},
+		BusinessID:          payment.BusinessID,
+		AppointmentID:       payment.AppointmentID,
+		ServiceCompletionID: payment.ServiceCompletionID,
+		Kind:                payment.Kind,
+		Status:              payment.Status,
+		Amount:              payment.Amount,
+		RefundedAmount:      payment.RefundedAmount,
+		Currency:            payment.Currency,
+		PaymentIntentID:     payment.PaymentIntentID,
+		GiftCardID:          payment.GiftCardID,
+		FailureReason:       payment.FailureReason,
+		PaidAt:              payment.PaidAt,
+	}
+}
+
+// ToPaymentResponseDTOs converts a slice of Payment domain models to PaymentResponseDTOs
+func ToPaymentResponseDTOs(payments []*domain.Payment) []*PaymentResponseDTO {
+	responses := make([]*PaymentResponseDTO, len(payments))
+	for i, payment := range payments {
+		responses[i] = ToPaymentResponseDTO(payment)
+	}
+	return responses
+}
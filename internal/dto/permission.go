@@ -0,0 +1,100 @@
+package dto
+
+import (
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// PermissionDefinitionDTO represents a permission of the catalog
+type PermissionDefinitionDTO struct {
+	Permission  string `json:"permission"`
+	Resource    string `json:"resource"`
+	Action      string `json:"action"`
+	Description string `json:"description"`
+}
+
+// RolePresetDTO represents the permissions a role comes with
+type RolePresetDTO struct {
+	Role        string   `json:"role"`
+	Permissions []string `json:"permissions"`
+}
+
+// PermissionCatalogDTO represents every permission staff may be given, and the presets of
+// each role
+type PermissionCatalogDTO struct {
+	Permissions []*PermissionDefinitionDTO `json:"permissions"`
+	Presets     []*RolePresetDTO           `json:"presets"`
+}
+
+// UpdateStaffPermissionsDTO represents the data for changing what a member of staff may do.
+// Grants and revokes replace those the member has, as differences from the preset of their role.
+type UpdateStaffPermissionsDTO struct {
+	StaffID string   `json:"staff_id" validate:"required,uuid"`
+	Grants  []string `json:"grants" validate:"omitempty,dive,required,max=100"`
+	Revokes []string `json:"revokes" validate:"omitempty,dive,required,max=100"`
+	DryRun  bool     `json:"dry_run"` // Only work out the effective permissions, without saving them
+}
+
+// StaffPermissionsDTO represents what a member of staff may do in a business
+type StaffPermissionsDTO struct {
+	StaffID    string   `json:"staff_id"`
+	BusinessID string   `json:"business_id"`
+	Role       string   `json:"role"`
+	Grants     []string `json:"grants"`
+	Revokes    []string `json:"revokes"`
+	Effective  []string `json:"effective"`
+	Saved      bool     `json:"saved"`
+}
+
+// ToPermissionCatalogDTO converts the permission catalog and role presets to a DTO
+func ToPermissionCatalogDTO() *PermissionCatalogDTO {
+	catalog := &PermissionCatalogDTO{}
+	for _, definition := range domain.PermissionCatalog() {
+		catalog.Permissions = append(catalog.Permissions, &PermissionDefinitionDTO{
+			Permission:  string(definition.Permission),
+			Resource:    definition.Permission.Resource(),
+			Action:      definition.Permission.Action(),
+			Description: definition.Description,
+		})
+	}
+	for _, role := range []domain.BusinessRole{domain.BusinessRoleOwner, domain.BusinessRoleManager, domain.BusinessRoleEmployee, domain.BusinessRoleAssistant} {
+		catalog.Presets = append(catalog.Presets, &RolePresetDTO{
+			Role:        string(role),
+			Permissions: ToPermissionStrings(domain.RolePermissions(role)),
+		})
+	}
+	return catalog
+}
+
+// ToStaffPermissions converts the grants and revokes of an UpdateStaffPermissionsDTO
+func ToStaffPermissions(updateDTO UpdateStaffPermissionsDTO) domain.StaffPermissions {
+	permissions := domain.StaffPermissions{}
+	for _, grant := range updateDTO.Grants {
+		permissions.Grants = append(permissions.Grants, domain.Permission(grant))
+	}
+	for _, revoke := range updateDTO.Revokes {
+		permissions.Revokes = append(permissions.Revokes, domain.Permission(revoke))
+	}
+	return permissions
+}
+
+// ToStaffPermissionsDTO converts a staff member's permissions to a DTO
+func ToStaffPermissionsDTO(staff *domain.Staff, permissions domain.StaffPermissions, saved bool) *StaffPermissionsDTO {
+	return &StaffPermissionsDTO{
+		StaffID:    staff.ID,
+		BusinessID: staff.BusinessID,
+		Role:       string(staff.Role),
+		Grants:     ToPermissionStrings(permissions.Grants),
+		Revokes:    ToPermissionStrings(permissions.Revokes),
+		Effective:  ToPermissionStrings(permissions.Effective(staff.Role)),
+		Saved:      saved,
+	}
+}
+
+// ToPermissionStrings converts permissions to strings
+func ToPermissionStrings(permissions []domain.Permission) []string {
+	result := make([]string, len(permissions))
+	for i, permission := range permissions {
+		result[i] = string(permission)
+	}
+	return result
+}
@@ -0,0 +1,131 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// BulkPriceChangeDTO represents the data for a bulk price change of services and categories
+type BulkPriceChangeDTO struct {
+	BusinessID     string                     `json:"business_id" validate:"required,uuid"`
+	AdjustmentType domain.PriceAdjustmentType `json:"adjustment_type" validate:"required,oneof=percentage fixed"`
+	Amount         decimal.Decimal            `json:"amount"`
+	ServiceIDs     []string                   `json:"service_ids,omitempty" validate:"omitempty,dive,uuid"`
+	CategoryIDs    []string                   `json:"category_ids,omitempty" validate:"omitempty,dive,uuid"`
+	EffectiveAt    *time.Time                 `json:"effective_at,omitempty"` // Applied immediately when omitted
+	Notes          *string                    `json:"notes,omitempty" validate:"omitempty,max=500"`
+}
+
+// PricePreviewDTO represents a service's price before and after a price change
+type PricePreviewDTO struct {
+	ServiceID   string          `json:"service_id"`
+	ServiceName string          `json:"service_name"`
+	OldPrice    decimal.Decimal `json:"old_price"`
+	NewPrice    decimal.Decimal `json:"new_price"`
+}
+
+// PriceChangeResponseDTO represents the response data for a bulk price change
+type PriceChangeResponseDTO struct {
+	BaseResponse
+	BusinessID     string                     `json:"business_id"`
+	AdjustmentType domain.PriceAdjustmentType `json:"adjustment_type"`
+	Amount         decimal.Decimal            `json:"amount"`
+	ServiceIDs     []string                   `json:"service_ids"`
+	CategoryIDs    []string                   `json:"category_ids"`
+	EffectiveAt    time.Time                  `json:"effective_at"`
+	Status         domain.PriceChangeStatus   `json:"status"`
+	AppliedAt      *time.Time                 `json:"applied_at,omitempty"`
+	FailureReason  *string                    `json:"failure_reason,omitempty"`
+	Notes          *string                    `json:"notes,omitempty"`
+	Preview        []*PricePreviewDTO         `json:"preview,omitempty"` // Set when the change is scheduled
+}
+
+// PriceHistoryDTO represents a change of a service's price
+type PriceHistoryDTO struct {
+	ID            string          `json:"id"`
+	ServiceID     string          `json:"service_id"`
+	PriceChangeID *string         `json:"price_change_id,omitempty"`
+	OldPrice      decimal.Decimal `json:"old_price"`
+	NewPrice      decimal.Decimal `json:"new_price"`
+	ChangedAt     time.Time       `json:"changed_at"`
+	ChangedBy     *string         `json:"changed_by,omitempty"`
+}
+
+// PriceChangeRunDTO summarizes a run of the scheduled price change applier
+type PriceChangeRunDTO struct {
+	Due      int `json:"due"`
+	Applied  int `json:"applied"`
+	Failed   int `json:"failed"`
+	Services int `json:"services"` // Services whose price changed
+}
+
+// ToPricePreviewDTOs converts price previews to DTOs
+func ToPricePreviewDTOs(previews []domain.PricePreview) []*PricePreviewDTO {
+	result := make([]*PricePreviewDTO, len(previews))
+	for i, preview := range previews {
+		result[i] = &PricePreviewDTO{
+			ServiceID:   preview.ServiceID,
+			ServiceName: preview.ServiceName,
+			OldPrice:    preview.OldPrice,
+			NewPrice:    preview.NewPrice,
+		}
+	}
+	return result
+}
+
+// ToPriceChangeResponseDTO converts a PriceChange domain model to PriceChangeResponseDTO
+func ToPriceChangeResponseDTO(change *domain.PriceChange) *PriceChangeResponseDTO {
+	if change == nil {
+		return nil
+	}
+
+	serviceIDs, _ := change.GetServiceIDs()
+	categoryIDs, _ := change.GetCategoryIDs()
+
+	return &PriceChangeResponseDTO{
+		BaseResponse: BaseResponse{
+			ID:        change.ID,
+			CreatedAt: change.CreatedAt,
+			UpdatedAt: change.UpdatedAt,
+			Version:   change.Version,
+		},
+		BusinessID:     change.BusinessID,
+		AdjustmentType: change.AdjustmentType,
+		Amount:         change.Amount,
+		ServiceIDs:     serviceIDs,
+		CategoryIDs:    categoryIDs,
+		EffectiveAt:    change.EffectiveAt,
+		Status:         change.Status,
+		AppliedAt:      change.AppliedAt,
+		FailureReason:  change.FailureReason,
+		Notes:          change.Notes,
+	}
+}
+
+// ToPriceChangeResponseDTOs converts a slice of PriceChange domain models to DTOs
+func ToPriceChangeResponseDTOs(changes []*domain.PriceChange) []*PriceChangeResponseDTO {
+	result := make([]*PriceChangeResponseDTO, len(changes))
+	for i, change := range changes {
+		result[i] = ToPriceChangeResponseDTO(change)
+	}
+	return result
+}
+
+// ToPriceHistoryDTOs converts service price history records to DTOs
+func ToPriceHistoryDTOs(history []*domain.ServicePriceHistory) []*PriceHistoryDTO {
+	result := make([]*PriceHistoryDTO, len(history))
+	for i, entry := range history {
+		result[i] = &PriceHistoryDTO{
+			ID:            entry.ID,
+			ServiceID:     entry.ServiceID,
+			PriceChangeID: entry.PriceChangeID,
+			OldPrice:      entry.OldPrice,
+			NewPrice:      entry.NewPrice,
+			ChangedAt:     entry.ChangedAt,
+			ChangedBy:     entry.ChangedBy,
+		}
+	}
+	return result
+}
@@ -0,0 +1,119 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// PublicServiceDTO represents a service as shown to online bookers
+type PublicServiceDTO struct {
+	ID                   string                   `json:"id"`
+	Name                 string                   `json:"name"`
+	Description          *string                  `json:"description,omitempty"`
+	Duration             int                      `json:"duration"`
+	Price                decimal.Decimal          `json:"price"`
+	RequiresDeposit      bool                     `json:"requires_deposit"`
+	DepositAmount        *decimal.Decimal         `json:"deposit_amount,omitempty"`
+	OnlineBooking        domain.OnlineBookingMode `json:"online_booking"` // Bookable, or call to book
+	ReturningClientsOnly bool                     `json:"returning_clients_only"`
+}
+
+// PublicServiceMenuDTO represents the service menu a business publishes for booking widgets
+// and crawlers
+type PublicServiceMenuDTO struct {
+	BusinessID   string              `json:"business_id"`
+	BusinessName string              `json:"business_name"`
+	Currency     string              `json:"currency"`
+	Services     []*PublicServiceDTO `json:"services"`
+	UpdatedAt    time.Time           `json:"updated_at"` // When the business or a listed service last changed
+}
+
+// PublicSlotsQueryDTO represents a request for the free slots of a service on a day
+type PublicSlotsQueryDTO struct {
+	BusinessID string  `json:"business_id" validate:"required,uuid"`
+	ServiceID  string  `json:"service_id" validate:"required,uuid"`
+	StaffID    *string `json:"staff_id,omitempty" validate:"omitempty,uuid"`
+	PageSlug   *string `json:"page_slug,omitempty"`      // Only the slots of the staff member of this booking page
+	Date       string  `json:"date" validate:"required"` // YYYY-MM-DD in the business's time zone
+}
+
+// PublicSlotDTO represents a time at which a staff member can take the service
+type PublicSlotDTO struct {
+	StaffID   string    `json:"staff_id"`
+	StaffName string    `json:"staff_name"` // First name only
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+}
+
+// PublicBookingRequestDTO represents an online booking made by a client, who is registered with
+// the business if their email address is not known yet. Bookings made from a staff member's
+// booking page name the page instead of the staff member.
+type PublicBookingRequestDTO struct {
+	BusinessID   string    `json:"business_id" validate:"required,uuid"`
+	ServiceID    string    `json:"service_id" validate:"required,uuid"`
+	StaffID      string    `json:"staff_id" validate:"required_without=PageSlug,omitempty,uuid"`
+	PageSlug     *string   `json:"page_slug,omitempty"`
+	StartTime    time.Time `json:"start_time" validate:"required"`
+	FirstName    string    `json:"first_name" validate:"required,max=100"`
+	LastName     string    `json:"last_name" validate:"required,max=100"`
+	Email        string    `json:"email" validate:"required,email,max=255"`
+	Phone        *string   `json:"phone,omitempty" validate:"omitempty,max=20"`
+	Notes        *string   `json:"notes,omitempty" validate:"omitempty,max=2000"`
+	CaptchaToken string    `json:"captcha_token,omitempty"`
+}
+
+// PublicBookingResultDTO represents the outcome of an online booking. When the booking guard
+// refuses the attempt, no appointment is created and the message tells the booker what to do.
+type PublicBookingResultDTO struct {
+	Booked      bool                  `json:"booked"`
+	Outcome     domain.BookingOutcome `json:"outcome"`
+	Message     string                `json:"message,omitempty"` // Safe to show to the booker
+	Appointment *PublicAppointmentDTO `json:"appointment,omitempty"`
+}
+
+// PublicAppointmentDTO represents an appointment requested online, as shown to the booker
+type PublicAppointmentDTO struct {
+	ID        string                   `json:"id"`
+	ServiceID string                   `json:"service_id"`
+	StaffID   string                   `json:"staff_id"`
+	StartTime time.Time                `json:"start_time"`
+	EndTime   time.Time                `json:"end_time"`
+	Status    domain.AppointmentStatus `json:"status"`
+}
+
+// ToPublicServiceDTO converts a Service domain model to PublicServiceDTO
+func ToPublicServiceDTO(service *domain.Service) *PublicServiceDTO {
+	if service == nil {
+		return nil
+	}
+
+	return &PublicServiceDTO{
+		ID:                   service.ID,
+		Name:                 service.Name,
+		Description:          service.Description,
+		Duration:             service.Duration,
+		Price:                service.Price,
+		RequiresDeposit:      service.RequiresDeposit,
+		DepositAmount:        service.DepositAmount,
+		OnlineBooking:        service.BookingMode(),
+		ReturningClientsOnly: service.ReturningClientsOnly,
+	}
+}
+
+// ToPublicAppointmentDTO converts an appointment requested online to PublicAppointmentDTO
+func ToPublicAppointmentDTO(appointment *domain.Appointment, serviceID string) *PublicAppointmentDTO {
+	if appointment == nil {
+		return nil
+	}
+
+	return &PublicAppointmentDTO{
+		ID:        appointment.ID,
+		ServiceID: serviceID,
+		StaffID:   appointment.StaffID,
+		StartTime: appointment.StartTime,
+		EndTime:   appointment.EndTime,
+		Status:    appointment.Status,
+	}
+}
@@ -0,0 +1,113 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// RecordReferralDTO represents the data for recording that a new client was referred by a
+// member of a loyalty program
+type RecordReferralDTO struct {
+	BusinessID       string `json:"business_id" validate:"required,uuid"`
+	Code             string `json:"code" validate:"required,max=20"` // The referrer's code, with or without the dash
+	ReferredClientID string `json:"referred_client_id" validate:"required,uuid"`
+}
+
+// ReferralCodeDTO represents the referral code of a loyalty program member
+type ReferralCodeDTO struct {
+	ProgramID    string `json:"program_id"`
+	ClientID     string `json:"client_id"`
+	MembershipID string `json:"membership_id"`
+	Code         string `json:"code"`
+}
+
+// ReferralResponseDTO represents the response data for a referral
+type ReferralResponseDTO struct {
+	BaseResponse
+	BusinessID           string                `json:"business_id"`
+	ProgramID            string                `json:"program_id"`
+	ReferrerMembershipID string                `json:"referrer_membership_id"`
+	ReferrerClientID     string                `json:"referrer_client_id"`
+	ReferredClientID     string                `json:"referred_client_id"`
+	Code                 string                `json:"code"`
+	Status               domain.ReferralStatus `json:"status"`
+	AppointmentID        *string               `json:"appointment_id,omitempty"`
+	ConvertedAt          *time.Time            `json:"converted_at,omitempty"`
+	BonusPoints          int                   `json:"bonus_points"`
+}
+
+// ReferrerStatsDTO represents how many clients a member referred and how many converted
+type ReferrerStatsDTO struct {
+	MembershipID string `json:"membership_id"`
+	ClientID     string `json:"client_id"`
+	Referrals    int    `json:"referrals"`
+	Converted    int    `json:"converted"`
+	BonusPoints  int    `json:"bonus_points"`
+}
+
+// ReferralStatsDTO represents how well a business's referrals converted over a period
+type ReferralStatsDTO struct {
+	BusinessID           string              `json:"business_id"`
+	PeriodStart          time.Time           `json:"period_start"`
+	PeriodEnd            time.Time           `json:"period_end"`
+	Referrals            int                 `json:"referrals"`
+	Converted            int                 `json:"converted"`
+	Pending              int                 `json:"pending"`
+	BonusPoints          int                 `json:"bonus_points"`
+	ConversionRate       float64             `json:"conversion_rate"`
+	AverageDaysToConvert float64             `json:"average_days_to_convert"`
+	TopReferrers         []*ReferrerStatsDTO `json:"top_referrers"`
+}
+
+// ToReferralResponseDTO converts a Referral domain model to ReferralResponseDTO
+func ToReferralResponseDTO(referral *domain.Referral) *ReferralResponseDTO {
+	if referral == nil {
+		return nil
+	}
+
+	return &ReferralResponseDTO{
+		BaseResponse: BaseResponse{
+			ID:        referral.ID,
+			CreatedAt: referral.CreatedAt,
+			UpdatedAt: referral.UpdatedAt,
+			Version:   referral.Version,
+		},
+		BusinessID:           referral.BusinessID,
+		ProgramID:            referral.ProgramID,
+		ReferrerMembershipID: referral.ReferrerMembershipID,
+		ReferrerClientID:     referral.ReferrerClientID,
+		ReferredClientID:     referral.ReferredClientID,
+		Code:                 referral.Code,
+		Status:               referral.Status,
+		AppointmentID:        referral.AppointmentID,
+		ConvertedAt:          referral.ConvertedAt,
+		BonusPoints:          referral.BonusPoints,
+	}
+}
+
+// ToReferralStatsDTO converts the referral stats of a business over a period to ReferralStatsDTO
+func ToReferralStatsDTO(businessID string, start, end time.Time, stats domain.ReferralStats) *ReferralStatsDTO {
+	response := &ReferralStatsDTO{
+		BusinessID:           businessID,
+		PeriodStart:          start,
+		PeriodEnd:            end,
+		Referrals:            stats.Referrals,
+		Converted:            stats.Converted,
+		Pending:              stats.Pending,
+		BonusPoints:          stats.BonusPoints,
+		ConversionRate:       stats.ConversionRate,
+		AverageDaysToConvert: stats.AverageDaysToConvert,
+		TopReferrers:         make([]*ReferrerStatsDTO, len(stats.TopReferrers)),
+	}
+	for i, referrer := range stats.TopReferrers {
+		response.TopReferrers[i] = &ReferrerStatsDTO{
+			MembershipID: referrer.MembershipID,
+			ClientID:     referrer.ClientID,
+			Referrals:    referrer.Referrals,
+			Converted:    referrer.Converted,
+			BonusPoints:  referrer.BonusPoints,
+		}
+	}
+	return response
+}
@@ -0,0 +1,77 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// CreateReportExportDTO represents a request to export a report of a business
+type CreateReportExportDTO struct {
+	BusinessID string              `json:"business_id" validate:"required,uuid"`
+	Report     domain.ReportKind   `json:"report" validate:"required,oneof=revenue commissions clients appointments"`
+	Format     domain.ReportFormat `json:"format" validate:"required,oneof=csv pdf"`
+	StartDate  *string             `json:"start_date,omitempty"` // YYYY-MM-DD, the first day; required but for the client list
+	EndDate    *string             `json:"end_date,omitempty"`   // YYYY-MM-DD, the last day
+}
+
+// ReportExportResponseDTO represents a report export and how far it has got
+type ReportExportResponseDTO struct {
+	BaseResponse
+	BusinessID  string                    `json:"business_id"`
+	Report      domain.ReportKind         `json:"report"`
+	Format      domain.ReportFormat       `json:"format"`
+	StartDate   *string                   `json:"start_date,omitempty"`
+	EndDate     *string                   `json:"end_date,omitempty"`
+	Status      domain.ReportExportStatus `json:"status"`
+	FileName    string                    `json:"file_name"`
+	ContentType string                    `json:"content_type"`
+	RequestedBy *string                   `json:"requested_by,omitempty"` // The user who asked for it
+	JobID       *string                   `json:"job_id,omitempty"`
+	RowCount    *int                      `json:"row_count,omitempty"`
+	SizeBytes   *int64                    `json:"size_bytes,omitempty"`
+	Error       *string                   `json:"error,omitempty"`
+	CompletedAt *time.Time                `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time                `json:"expires_at,omitempty"`
+}
+
+// ToReportExportResponseDTO converts a ReportExport domain model to ReportExportResponseDTO
+func ToReportExportResponseDTO(export *domain.ReportExport) *ReportExportResponseDTO {
+	if export == nil {
+		return nil
+	}
+
+	return &ReportExportResponseDTO{
+		BaseResponse: BaseResponse{
+			ID:        export.ID,
+			CreatedAt: export.CreatedAt,
+			UpdatedAt: export.UpdatedAt,
+			Version:   export.Version,
+		},
+		BusinessID:  export.BusinessID,
+		Report:      export.Report,
+		Format:      export.Format,
+		StartDate:   export.StartDate,
+		EndDate:     export.EndDate,
+		Status:      export.Status,
+		FileName:    export.FileName,
+		ContentType: export.Format.ContentType(),
+		RequestedBy: export.CreatedBy,
+		JobID:       export.JobID,
+		RowCount:    export.RowCount,
+		SizeBytes:   export.SizeBytes,
+		Error:       export.Error,
+		CompletedAt: export.CompletedAt,
+		ExpiresAt:   export.ExpiresAt,
+	}
+}
+
+// ReportExportDownloadDTO represents a signed URL an export can be downloaded from without
+// signing in
+type ReportExportDownloadDTO struct {
+	ExportID    string    `json:"export_id"`
+	URL         string    `json:"url"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	ExpiresAt   time.Time `json:"expires_at"` // When the URL stops working
+}
@@ -0,0 +1,83 @@
+package dto
+
+import (
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// CreateResourceDTO represents the data for adding a room, chair or piece of equipment
+type CreateResourceDTO struct {
+	BusinessID string              `json:"business_id" validate:"required,uuid"`
+	LocationID *string             `json:"location_id,omitempty" validate:"omitempty,uuid"`
+	Name       string              `json:"name" validate:"required,max=100"`
+	Kind       domain.ResourceKind `json:"kind" validate:"required,oneof=room chair equipment"`
+	Capacity   *int                `json:"capacity,omitempty" validate:"omitempty,min=1"` // One when absent
+	Notes      *string             `json:"notes,omitempty" validate:"omitempty,max=500"`
+}
+
+// UpdateResourceDTO represents the data for changing a resource; absent fields are left as they
+// are. Inactive resources no longer hold up bookings.
+type UpdateResourceDTO struct {
+	LocationID      Optional[string]     `json:"location_id" validate:"omitempty,uuid"`
+	Name            *string              `json:"name,omitempty" validate:"omitempty,max=100"`
+	Kind            *domain.ResourceKind `json:"kind,omitempty" validate:"omitempty,oneof=room chair equipment"`
+	Capacity        *int                 `json:"capacity,omitempty" validate:"omitempty,min=1"`
+	IsActive        *bool                `json:"is_active,omitempty"`
+	Notes           Optional[string]     `json:"notes" validate:"omitempty,max=500"`
+	ExpectedVersion *int                 `json:"expected_version,omitempty" validate:"omitempty,min=1"` // See RescheduleAppointmentDTO
+}
+
+// SetServiceResourcesDTO represents the resources every appointment for a service occupies
+type SetServiceResourcesDTO struct {
+	ServiceID   string   `json:"service_id" validate:"required,uuid"`
+	ResourceIDs []string `json:"resource_ids" validate:"dive,uuid"` // None for services that need no resource
+}
+
+// ResourceResponseDTO represents a room, chair or piece of equipment
+type ResourceResponseDTO struct {
+	BaseResponse
+	BusinessID string              `json:"business_id"`
+	LocationID *string             `json:"location_id,omitempty"`
+	Name       string              `json:"name"`
+	Kind       domain.ResourceKind `json:"kind"`
+	Capacity   int                 `json:"capacity"`
+	IsActive   bool                `json:"is_active"`
+	Notes      *string             `json:"notes,omitempty"`
+}
+
+// ServiceResourcesDTO represents the resources a service needs
+type ServiceResourcesDTO struct {
+	ServiceID string                 `json:"service_id"`
+	Resources []*ResourceResponseDTO `json:"resources"`
+}
+
+// ToResourceResponseDTO converts a Resource domain model to ResourceResponseDTO
+func ToResourceResponseDTO(resource *domain.Resource) *ResourceResponseDTO {
+	if resource == nil {
+		return nil
+	}
+
+	return &ResourceResponseDTO{
+		BaseResponse: BaseResponse{
+			ID:        resource.ID,
+			CreatedAt: resource.CreatedAt,
+			UpdatedAt: resource.UpdatedAt,
+			Version:   resource.Version,
+		},
+		BusinessID: resource.BusinessID,
+		LocationID: resource.LocationID,
+		Name:       resource.Name,
+		Kind:       resource.Kind,
+		Capacity:   resource.Capacity,
+		IsActive:   resource.IsActive,
+		Notes:      resource.Notes,
+	}
+}
+
+// ToResourceResponseDTOs converts resources to ResourceResponseDTOs
+func ToResourceResponseDTOs(resources []*domain.Resource) []*ResourceResponseDTO {
+	result := make([]*ResourceResponseDTO, len(resources))
+	for i, resource := range resources {
+		result[i] = ToResourceResponseDTO(resource)
+	}
+	return result
+}
@@ -0,0 +1,89 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// ReviewFormDTO represents what the review page shows a client
+type ReviewFormDTO struct {
+	BusinessName string    `json:"business_name"`
+	StaffName    string    `json:"staff_name"`
+	ServiceName  string    `json:"service_name"`
+	StartTime    time.Time `json:"start_time"`
+	TimeZone     string    `json:"time_zone"`
+}
+
+// SubmitReviewDTO represents a review a client leaves with a review link
+type SubmitReviewDTO struct {
+	Token   string `json:"token" validate:"required"`
+	Rating  int    `json:"rating" validate:"required,min=1,max=5"`
+	Comment string `json:"comment"`
+}
+
+// ListReviewsDTO represents a page of the reviews of a business. Without the reviews:moderate
+// permission only published reviews can be listed.
+type ListReviewsDTO struct {
+	BusinessID string               `json:"business_id" validate:"required,uuid"`
+	Status     *domain.ReviewStatus `json:"status,omitempty" validate:"omitempty,oneof=pending published rejected flagged"`
+	StaffID    *string              `json:"staff_id,omitempty" validate:"omitempty,uuid"`
+	Page       int                  `json:"page" validate:"min=1"`
+	PageSize   int                  `json:"page_size" validate:"min=1,max=100"`
+}
+
+// ModerateReviewDTO represents a business publishing or rejecting a review
+type ModerateReviewDTO struct {
+	ReviewID string  `json:"review_id" validate:"required,uuid"`
+	Publish  bool    `json:"publish"`
+	Note     *string `json:"note,omitempty" validate:"omitempty,max=500"`
+}
+
+// ReplyToReviewDTO represents the business's public reply to a review; an empty reply removes it
+type ReplyToReviewDTO struct {
+	ReviewID string `json:"review_id" validate:"required,uuid"`
+	Reply    string `json:"reply" validate:"max=2000"`
+}
+
+// ReviewResponseDTO represents a review. Moderation details are only given to staff who may
+// moderate reviews.
+type ReviewResponseDTO struct {
+	BaseResponse
+	BusinessID     string              `json:"business_id"`
+	AppointmentID  string              `json:"appointment_id"`
+	StaffID        string              `json:"staff_id"`
+	ServiceID      string              `json:"service_id"`
+	Rating         int                 `json:"rating"`
+	Comment        *string             `json:"comment,omitempty"`
+	Status         domain.ReviewStatus `json:"status"`
+	Reply          *string             `json:"reply,omitempty"`
+	RepliedAt      *time.Time          `json:"replied_at,omitempty"`
+	ModeratedAt    *time.Time          `json:"moderated_at,omitempty"`
+	ModerationNote *string             `json:"moderation_note,omitempty"`
+}
+
+// ToReviewResponseDTO converts a review to a DTO, with its moderation details when asked for
+func ToReviewResponseDTO(review *domain.Review, moderation bool) *ReviewResponseDTO {
+	result := &ReviewResponseDTO{
+		BaseResponse: BaseResponse{
+			ID:        review.ID,
+			CreatedAt: review.CreatedAt,
+			UpdatedAt: review.UpdatedAt,
+			Version:   review.Version,
+		},
+		BusinessID:    review.BusinessID,
+		AppointmentID: review.AppointmentID,
+		StaffID:       review.StaffID,
+		ServiceID:     review.ServiceID,
+		Rating:        review.Rating,
+		Comment:       review.Comment,
+		Status:        review.Status,
+		Reply:         review.Reply,
+		RepliedAt:     review.RepliedAt,
+	}
+	if moderation {
+		result.ModeratedAt = review.ModeratedAt
+		result.ModerationNote = review.ModerationNote
+	}
+	return result
+}
@@ -0,0 +1,47 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// RunSheetDTO represents a staff member's appointments of a day with their clients' details
+type RunSheetDTO struct {
+	StaffID      string              `json:"staff_id"`
+	Date         time.Time           `json:"date"`
+	Appointments []*RunSheetEntryDTO `json:"appointments"`
+}
+
+// RunSheetEntryDTO represents an appointment on a run sheet
+type RunSheetEntryDTO struct {
+	Appointment *CalendarEntryDTO         `json:"appointment"`
+	Allergies   *string                   `json:"allergies,omitempty"`
+	ClientNotes *string                   `json:"client_notes,omitempty"`
+	Preferences map[string]any            `json:"preferences"`
+	LastRecord  *ServiceRecordResponseDTO `json:"last_record,omitempty"` // Such as the formula used last time
+}
+
+// ToRunSheetDTO converts a run sheet to its DTO. Preferences that cannot be decoded are left out
+// rather than keeping the sheet from printing.
+func ToRunSheetDTO(sheet *domain.RunSheet) *RunSheetDTO {
+	appointments := make([]*RunSheetEntryDTO, len(sheet.Entries))
+	for i, entry := range sheet.Entries {
+		appointment := &RunSheetEntryDTO{
+			Appointment: ToCalendarEntryDTO(entry.Appointment),
+			Preferences: map[string]any{},
+		}
+		if entry.Client != nil {
+			appointment.Allergies = entry.Client.Allergies
+			appointment.ClientNotes = entry.Client.Notes
+			if preferences, err := entry.Client.GetPreferences(); err == nil {
+				appointment.Preferences = preferences
+			}
+		}
+		if entry.LastRecord != nil {
+			appointment.LastRecord = ToServiceRecordResponseDTO(entry.LastRecord)
+		}
+		appointments[i] = appointment
+	}
+	return &RunSheetDTO{StaffID: sheet.StaffID, Date: sheet.Date, Appointments: appointments}
+}
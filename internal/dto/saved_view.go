@@ -0,0 +1,72 @@
+package dto
+
+import (
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// CreateSavedViewDTO represents the data for saving a view of a business's clients or
+// appointments
+type CreateSavedViewDTO struct {
+	BusinessID      string                  `json:"business_id" validate:"required,uuid"`
+	Name            string                  `json:"name" validate:"required,max=100"`
+	Entity          domain.SavedViewEntity  `json:"entity" validate:"required,oneof=clients appointments"`
+	Filters         domain.SavedViewFilters `json:"filters"`
+	SortField       *string                 `json:"sort_field,omitempty" validate:"omitempty,max=50"`
+	SortDescending  bool                    `json:"sort_descending"`
+	SharedWithRoles []domain.BusinessRole   `json:"shared_with_roles,omitempty" validate:"omitempty,max=4,dive,oneof=owner manager employee assistant"`
+}
+
+// UpdateSavedViewDTO represents the data for changing a saved view; absent fields are left
+// as they are
+type UpdateSavedViewDTO struct {
+	Name            *string                  `json:"name,omitempty" validate:"omitempty,max=100"`
+	Filters         *domain.SavedViewFilters `json:"filters,omitempty"` // Replaces every criterion
+	SortField       Optional[string]         `json:"sort_field" validate:"omitempty,max=50"`
+	SortDescending  *bool                    `json:"sort_descending,omitempty"`
+	SharedWithRoles *[]domain.BusinessRole   `json:"shared_with_roles,omitempty" validate:"omitempty,max=4,dive,oneof=owner manager employee assistant"`
+}
+
+// SavedViewResponseDTO represents a saved view
+type SavedViewResponseDTO struct {
+	BaseResponse
+	BusinessID      string                  `json:"business_id"`
+	UserID          string                  `json:"user_id"`
+	Name            string                  `json:"name"`
+	Entity          domain.SavedViewEntity  `json:"entity"`
+	Filters         domain.SavedViewFilters `json:"filters"`
+	SortField       *string                 `json:"sort_field,omitempty"`
+	SortDescending  bool                    `json:"sort_descending"`
+	SharedWithRoles []domain.BusinessRole   `json:"shared_with_roles"`
+	IsOwner         bool                    `json:"is_owner"` // Whether the user asking owns the view
+}
+
+// ToSavedViewResponseDTO converts a SavedView domain model to SavedViewResponseDTO, as seen by
+// a user
+func ToSavedViewResponseDTO(view *domain.SavedView, userID string) *SavedViewResponseDTO {
+	if view == nil {
+		return nil
+	}
+
+	filters, _ := view.GetFilters()
+	roles, _ := view.GetSharedWithRoles()
+	if roles == nil {
+		roles = []domain.BusinessRole{}
+	}
+	return &SavedViewResponseDTO{
+		BaseResponse: BaseResponse{
+			ID:        view.ID,
+			CreatedAt: view.CreatedAt,
+			UpdatedAt: view.UpdatedAt,
+			Version:   view.Version,
+		},
+		BusinessID:      view.BusinessID,
+		UserID:          view.UserID,
+		Name:            view.Name,
+		Entity:          view.Entity,
+		Filters:         filters,
+		SortField:       view.SortField,
+		SortDescending:  view.SortDescending,
+		SharedWithRoles: roles,
+		IsOwner:         view.UserID == userID,
+	}
+}
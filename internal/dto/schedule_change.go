@@ -0,0 +1,123 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// UpdateSchedulingSettingsDTO represents a change to the opening hours, appointment buffer,
+// booking window or deposit window of a business
+type UpdateSchedulingSettingsDTO struct {
+	BusinessID               string           `json:"business_id" validate:"required,uuid"`
+	BusinessHours            Optional[string] `json:"business_hours"` // Cleared, the calendar hours apply every day
+	CalendarStartHour        *int             `json:"calendar_start_hour,omitempty" validate:"omitempty,min=0,max=23"`
+	CalendarEndHour          *int             `json:"calendar_end_hour,omitempty" validate:"omitempty,min=1,max=24"`
+	AppointmentBufferMinutes *int             `json:"appointment_buffer_minutes,omitempty" validate:"omitempty,min=0,max=240"`
+	MinBookingLeadHours      *int             `json:"min_booking_lead_hours,omitempty" validate:"omitempty,min=0,max=168"`
+	MaxBookingHorizonDays    *int             `json:"max_booking_horizon_days,omitempty" validate:"omitempty,min=0,max=730"`
+	DepositWindowHours       *int             `json:"deposit_window_hours,omitempty" validate:"omitempty,min=0,max=168"`
+	DryRun                   bool             `json:"dry_run"` // Only report the impacted appointments
+}
+
+// UpdateServiceBookingWindowDTO represents a change to how far ahead a service can be booked.
+// Cleared limits fall back to the business's.
+type UpdateServiceBookingWindowDTO struct {
+	ServiceID       string        `json:"service_id" validate:"required,uuid"`
+	MinLeadHours    Optional[int] `json:"min_lead_hours" validate:"omitempty,min=0,max=168"`
+	MaxHorizonDays  Optional[int] `json:"max_horizon_days" validate:"omitempty,min=0,max=730"`
+	ExpectedVersion *int          `json:"expected_version,omitempty" validate:"omitempty,min=1"` // See RescheduleAppointmentDTO
+}
+
+// ServiceBookingWindowDTO represents how far ahead a service can be booked
+type ServiceBookingWindowDTO struct {
+	ServiceID               string `json:"service_id"`
+	MinLeadHours            *int   `json:"min_lead_hours,omitempty"`   // Set on the service itself
+	MaxHorizonDays          *int   `json:"max_horizon_days,omitempty"` // Set on the service itself
+	EffectiveMinLeadHours   int    `json:"effective_min_lead_hours"`   // 0 allows booking until the appointment starts
+	EffectiveMaxHorizonDays int    `json:"effective_max_horizon_days"` // 0 for no limit
+}
+
+// ToServiceBookingWindowDTO converts a service and its effective booking window to ServiceBookingWindowDTO
+func ToServiceBookingWindowDTO(service *domain.Service, window domain.BookingWindow) *ServiceBookingWindowDTO {
+	return &ServiceBookingWindowDTO{
+		ServiceID:               service.ID,
+		MinLeadHours:            service.MinAdvanceBooking,
+		MaxHorizonDays:          service.MaxAdvanceBooking,
+		EffectiveMinLeadHours:   window.MinLeadHours,
+		EffectiveMaxHorizonDays: window.MaxHorizonDays,
+	}
+}
+
+// UpdateServiceOnlineBookingDTO represents a change to how a service is offered online and the
+// rules for booking it online. A cleared daily limit lifts it.
+type UpdateServiceOnlineBookingDTO struct {
+	ServiceID            string                    `json:"service_id" validate:"required,uuid"`
+	OnlineBooking        *domain.OnlineBookingMode `json:"online_booking,omitempty"`
+	ReturningClientsOnly *bool                     `json:"returning_clients_only,omitempty"`
+	MaxPerDay            Optional[int]             `json:"max_per_day" validate:"omitempty,min=1,max=100"`
+	ExpectedVersion      *int                      `json:"expected_version,omitempty" validate:"omitempty,min=1"` // See RescheduleAppointmentDTO
+}
+
+// ServiceOnlineBookingDTO represents how a service is offered online
+type ServiceOnlineBookingDTO struct {
+	ServiceID            string                   `json:"service_id"`
+	OnlineBooking        domain.OnlineBookingMode `json:"online_booking"`
+	ReturningClientsOnly bool                     `json:"returning_clients_only"`
+	MaxPerDay            *int                     `json:"max_per_day,omitempty"` // Nil for no limit
+}
+
+// ToServiceOnlineBookingDTO converts a service to ServiceOnlineBookingDTO
+func ToServiceOnlineBookingDTO(service *domain.Service) *ServiceOnlineBookingDTO {
+	return &ServiceOnlineBookingDTO{
+		ServiceID:            service.ID,
+		OnlineBooking:        service.BookingMode(),
+		ReturningClientsOnly: service.ReturningClientsOnly,
+		MaxPerDay:            service.MaxPerDay,
+	}
+}
+
+// UpdateStaffAssignmentDTO represents a staff member leaving a business, at once or on an end date
+type UpdateStaffAssignmentDTO struct {
+	StaffID  string              `json:"staff_id" validate:"required,uuid"`
+	IsActive *bool               `json:"is_active,omitempty"`
+	EndDate  Optional[time.Time] `json:"end_date"`
+	DryRun   bool                `json:"dry_run"` // Only report the impacted appointments
+}
+
+// ImpactedAppointmentDTO represents an upcoming appointment invalidated by a schedule change
+type ImpactedAppointmentDTO struct {
+	AppointmentID string                      `json:"appointment_id"`
+	StaffID       string                      `json:"staff_id"`
+	StartTime     time.Time                   `json:"start_time"`
+	EndTime       time.Time                   `json:"end_time"`
+	Conflicts     []*AvailabilityConflictDTO  `json:"conflicts"`
+	Resolutions   []domain.ScheduleResolution `json:"resolutions"`
+}
+
+// ScheduleChangeResultDTO represents the outcome of a schedule change
+type ScheduleChangeResultDTO struct {
+	Applied              bool                      `json:"applied"` // False for dry runs
+	ImpactedAppointments []*ImpactedAppointmentDTO `json:"impacted_appointments"`
+	NotifiedCount        int                       `json:"notified_count"` // The owner and staff members notified
+}
+
+// ToScheduleChangeResultDTO converts the appointments a change invalidates to ScheduleChangeResultDTO
+func ToScheduleChangeResultDTO(applied bool, impacted []*domain.ImpactedAppointment, notified int) *ScheduleChangeResultDTO {
+	result := &ScheduleChangeResultDTO{
+		Applied:              applied,
+		ImpactedAppointments: make([]*ImpactedAppointmentDTO, len(impacted)),
+		NotifiedCount:        notified,
+	}
+	for i, appointment := range impacted {
+		result.ImpactedAppointments[i] = &ImpactedAppointmentDTO{
+			AppointmentID: appointment.AppointmentID,
+			StaffID:       appointment.StaffID,
+			StartTime:     appointment.StartTime,
+			EndTime:       appointment.EndTime,
+			Conflicts:     ToAvailabilityConflictDTOs(appointment.Conflicts),
+			Resolutions:   appointment.Resolutions,
+		}
+	}
+	return result
+}
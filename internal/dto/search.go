@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// SearchDTO represents the data for searching a business's clients and services
+type SearchDTO struct {
+	BusinessID string                    `json:"business_id" validate:"required,uuid"`
+	Query      string                    `json:"query" validate:"required,max=200"`
+	Kinds      []domain.SearchResultKind `json:"kinds,omitempty" validate:"omitempty,max=2,dive,oneof=client service"` // Every kind the caller may view when empty
+	Limit      int                       `json:"limit,omitempty" validate:"omitempty,min=1,max=50"`
+}
+
+// SearchResultDTO represents a client or service matching a search
+type SearchResultDTO struct {
+	Kind    domain.SearchResultKind `json:"kind"`
+	Rank    float64                 `json:"rank"`
+	Client  *ClientResponseDTO      `json:"client,omitempty"`
+	Service *ServiceResponseDTO     `json:"service,omitempty"`
+}
+
+// Item returns the client or service the result is
+func (r *SearchResultDTO) Item() any {
+	if r.Client != nil {
+		return r.Client
+	}
+	return r.Service
+}
+
+// ToSearchResultDTOs converts search results to SearchResultDTOs
+func ToSearchResultDTOs(results []*domain.SearchResult) []*SearchResultDTO {
+	dtos := make([]*SearchResultDTO, len(results))
+	for i, result := range results {
+		dtos[i] = &SearchResultDTO{
+			Kind:    result.Kind,
+			Rank:    result.Rank,
+			Client:  ToClientResponseDTO(result.Client),
+			Service: ToServiceResponseDTO(result.Service),
+		}
+	}
+	return dtos
+}
@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// ServiceResponseDTO represents the response data for a service
+type ServiceResponseDTO struct {
+	BaseResponse
+	BusinessID      string           `json:"business_id"`
+	CategoryID      *string          `json:"category_id,omitempty"`
+	Name            string           `json:"name"`
+	Description     *string          `json:"description,omitempty"`
+	Duration        int              `json:"duration"`
+	Price           decimal.Decimal  `json:"price"`
+	IsActive        bool             `json:"is_active"`
+	RequiresDeposit bool             `json:"requires_deposit"`
+	DepositAmount   *decimal.Decimal `json:"deposit_amount,omitempty"`
+}
+
+// ToServiceResponseDTO converts a Service domain model to ServiceResponseDTO
+func ToServiceResponseDTO(service *domain.Service) *ServiceResponseDTO {
+	if service == nil {
+		return nil
+	}
+
+	return &ServiceResponseDTO{
+		BaseResponse: BaseResponse{
+			ID:        service.ID,
+			CreatedAt: service.CreatedAt,
+			UpdatedAt: service.UpdatedAt,
+			Version:   service.Version,
+		},
+		BusinessID:      service.BusinessID,
+		CategoryID:      service.CategoryID,
+		Name:            service.Name,
+		Description:     service.Description,
+		Duration:        service.Duration,
+		Price:           service.Price,
+		IsActive:        service.IsActive,
+		RequiresDeposit: service.RequiresDeposit,
+		DepositAmount:   service.DepositAmount,
+	}
+}
@@ -0,0 +1,122 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// BundleItemDTO represents a service of a bundle
+type BundleItemDTO struct {
+	ServiceID                string `json:"service_id" validate:"required,uuid"`
+	ProcessingMinutes        int    `json:"processing_minutes" validate:"min=0,max=480"`
+	DuringPreviousProcessing bool   `json:"during_previous_processing"`
+}
+
+// CreateServiceBundleDTO represents the data for creating a service bundle
+type CreateServiceBundleDTO struct {
+	BusinessID  string          `json:"business_id" validate:"required,uuid"`
+	Name        string          `json:"name" validate:"required,min=2,max=100"`
+	Description *string         `json:"description,omitempty" validate:"omitempty,max=1000"`
+	Price       decimal.Decimal `json:"price"`
+	IsActive    bool            `json:"is_active"`
+	Items       []BundleItemDTO `json:"items" validate:"required,min=2,dive"`
+}
+
+// UpdateServiceBundleDTO represents the data for updating a service bundle
+type UpdateServiceBundleDTO struct {
+	Name        *string          `json:"name,omitempty" validate:"omitempty,min=2,max=100"`
+	Description Optional[string] `json:"description" validate:"omitempty,max=1000"`
+	Price       *decimal.Decimal `json:"price,omitempty"`
+	IsActive    *bool            `json:"is_active,omitempty"`
+	Items       []BundleItemDTO  `json:"items,omitempty" validate:"omitempty,min=2,dive"`
+}
+
+// BookBundleDTO represents the data for booking a service bundle as a single appointment
+type BookBundleDTO struct {
+	BusinessID string    `json:"business_id" validate:"required,uuid"`
+	BundleID   string    `json:"bundle_id" validate:"required,uuid"`
+	ClientID   string    `json:"client_id" validate:"required,uuid"`
+	StaffID    string    `json:"staff_id" validate:"omitempty,uuid"` // Required unless the business is solo
+	StartTime  time.Time `json:"start_time" validate:"required"`
+	Notes      *string   `json:"notes,omitempty" validate:"omitempty,max=2000"`
+}
+
+// BundleStepDTO represents when a service of a bundle is performed
+type BundleStepDTO struct {
+	ServiceID     string          `json:"service_id"`
+	ServiceName   string          `json:"service_name"`
+	OffsetMinutes int             `json:"offset_minutes"`
+	Duration      int             `json:"duration"`
+	Price         decimal.Decimal `json:"price"`
+}
+
+// ServiceBundleResponseDTO represents the response data for a service bundle
+type ServiceBundleResponseDTO struct {
+	BaseResponse
+	BusinessID         string              `json:"business_id"`
+	Name               string              `json:"name"`
+	Description        *string             `json:"description,omitempty"`
+	Price              decimal.Decimal     `json:"price"`
+	IsActive           bool                `json:"is_active"`
+	Items              []domain.BundleItem `json:"items"`
+	Steps              []*BundleStepDTO    `json:"steps"`
+	Duration           int                 `json:"duration"`
+	SequentialDuration int                 `json:"sequential_duration"`
+	RegularPrice       decimal.Decimal     `json:"regular_price"`
+	Savings            decimal.Decimal     `json:"savings"`
+}
+
+// ToBundleItems converts bundle item DTOs to domain bundle items
+func ToBundleItems(items []BundleItemDTO) []domain.BundleItem {
+	result := make([]domain.BundleItem, len(items))
+	for i, item := range items {
+		result[i] = domain.BundleItem{
+			ServiceID:                item.ServiceID,
+			ProcessingMinutes:        item.ProcessingMinutes,
+			DuringPreviousProcessing: item.DuringPreviousProcessing,
+		}
+	}
+	return result
+}
+
+// ToServiceBundleResponseDTO converts a ServiceBundle domain model and its plan to ServiceBundleResponseDTO
+func ToServiceBundleResponseDTO(bundle *domain.ServiceBundle, plan *domain.BundlePlan) *ServiceBundleResponseDTO {
+	if bundle == nil {
+		return nil
+	}
+
+	items, _ := bundle.GetItems()
+	response := &ServiceBundleResponseDTO{
+		BaseResponse: BaseResponse{
+			ID:        bundle.ID,
+			CreatedAt: bundle.CreatedAt,
+			UpdatedAt: bundle.UpdatedAt,
+			Version:   bundle.Version,
+		},
+		BusinessID:  bundle.BusinessID,
+		Name:        bundle.Name,
+		Description: bundle.Description,
+		Price:       bundle.Price,
+		IsActive:    bundle.IsActive,
+		Items:       items,
+		Steps:       []*BundleStepDTO{},
+	}
+	if plan != nil {
+		for _, step := range plan.Steps {
+			response.Steps = append(response.Steps, &BundleStepDTO{
+				ServiceID:     step.ServiceID,
+				ServiceName:   step.ServiceName,
+				OffsetMinutes: step.OffsetMinutes,
+				Duration:      step.Duration,
+				Price:         step.Price,
+			})
+		}
+		response.Duration = plan.Duration
+		response.SequentialDuration = plan.SequentialDuration
+		response.RegularPrice = plan.RegularPrice
+		response.Savings = plan.Savings()
+	}
+	return response
+}
@@ -0,0 +1,171 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// CreateServiceRecordTemplateDTO represents the data for creating a service record template
+type CreateServiceRecordTemplateDTO struct {
+	BusinessID  string                      `json:"business_id" validate:"required,uuid"`
+	CategoryID  *string                     `json:"category_id,omitempty" validate:"omitempty,uuid"`
+	Name        string                      `json:"name" validate:"required,min=2,max=100"`
+	Description *string                     `json:"description,omitempty" validate:"omitempty,max=500"`
+	Fields      []domain.ServiceRecordField `json:"fields" validate:"required,min=1"`
+}
+
+// UpdateServiceRecordTemplateDTO represents the data for updating a service record template
+type UpdateServiceRecordTemplateDTO struct {
+	CategoryID  Optional[string]            `json:"category_id" validate:"omitempty,uuid"`
+	Name        *string                     `json:"name,omitempty" validate:"omitempty,min=2,max=100"`
+	Description Optional[string]            `json:"description" validate:"omitempty,max=500"`
+	Fields      []domain.ServiceRecordField `json:"fields,omitempty" validate:"omitempty,min=1"`
+	IsActive    *bool                       `json:"is_active,omitempty"`
+}
+
+// ServiceRecordTemplateResponseDTO represents the response data for a service record template
+type ServiceRecordTemplateResponseDTO struct {
+	BaseResponse
+	BusinessID  string                      `json:"business_id"`
+	CategoryID  *string                     `json:"category_id,omitempty"`
+	Name        string                      `json:"name"`
+	Description *string                     `json:"description,omitempty"`
+	Fields      []domain.ServiceRecordField `json:"fields"`
+	IsActive    bool                        `json:"is_active"`
+}
+
+// CreateServiceRecordDTO represents the data for recording a service
+type CreateServiceRecordDTO struct {
+	BusinessID          string                `json:"business_id" validate:"required,uuid"`
+	ClientID            string                `json:"client_id" validate:"required,uuid"`
+	AppointmentID       *string               `json:"appointment_id,omitempty" validate:"omitempty,uuid"`
+	ServiceCompletionID *string               `json:"service_completion_id,omitempty" validate:"omitempty,uuid"`
+	ServiceID           *string               `json:"service_id,omitempty" validate:"omitempty,uuid"`
+	StaffID             *string               `json:"staff_id,omitempty" validate:"omitempty,uuid"`
+	TemplateID          *string               `json:"template_id,omitempty" validate:"omitempty,uuid"`
+	RecordedAt          *time.Time            `json:"recorded_at,omitempty"`
+	FieldValues         map[string]any        `json:"field_values,omitempty"`
+	ProductsUsed        []domain.ProductUsage `json:"products_used,omitempty"`
+	ProcessingTime      *int                  `json:"processing_time,omitempty" validate:"omitempty,min=0"`
+	Notes               *string               `json:"notes,omitempty" validate:"omitempty,max=2000"`
+	IsDraft             bool                  `json:"is_draft"`
+}
+
+// UpdateServiceRecordDTO represents the data for updating a service record
+type UpdateServiceRecordDTO struct {
+	StaffID        Optional[string]      `json:"staff_id" validate:"omitempty,uuid"`
+	FieldValues    map[string]any        `json:"field_values,omitempty"`
+	ProductsUsed   []domain.ProductUsage `json:"products_used,omitempty"`
+	ProcessingTime Optional[int]         `json:"processing_time" validate:"omitempty,min=0"`
+	Notes          Optional[string]      `json:"notes" validate:"omitempty,max=2000"`
+	IsDraft        *bool                 `json:"is_draft,omitempty"`
+}
+
+// ServiceRecordResponseDTO represents the response data for a service record
+type ServiceRecordResponseDTO struct {
+	BaseResponse
+	BusinessID          string                `json:"business_id"`
+	ClientID            string                `json:"client_id"`
+	AppointmentID       *string               `json:"appointment_id,omitempty"`
+	ServiceCompletionID *string               `json:"service_completion_id,omitempty"`
+	ServiceID           *string               `json:"service_id,omitempty"`
+	StaffID             *string               `json:"staff_id,omitempty"`
+	TemplateID          *string               `json:"template_id,omitempty"`
+	CopiedFromID        *string               `json:"copied_from_id,omitempty"`
+	RecordedAt          time.Time             `json:"recorded_at"`
+	FieldValues         map[string]any        `json:"field_values"`
+	ProductsUsed        []domain.ProductUsage `json:"products_used"`
+	ProcessingTime      *int                  `json:"processing_time,omitempty"`
+	Notes               *string               `json:"notes,omitempty"`
+	IsDraft             bool                  `json:"is_draft"`
+}
+
+// ServiceRecordSearchDTO represents the criteria for searching service record history
+type ServiceRecordSearchDTO struct {
+	ClientID      *string    `json:"client_id,omitempty" validate:"omitempty,uuid"`
+	ServiceID     *string    `json:"service_id,omitempty" validate:"omitempty,uuid"`
+	StaffID       *string    `json:"staff_id,omitempty" validate:"omitempty,uuid"`
+	Query         string     `json:"query,omitempty" validate:"omitempty,max=100"`
+	StartDate     *time.Time `json:"start_date,omitempty"`
+	EndDate       *time.Time `json:"end_date,omitempty"`
+	IncludeDrafts bool       `json:"include_drafts"`
+}
+
+// ToServiceRecordTemplateResponseDTO converts a ServiceRecordTemplate domain model to ServiceRecordTemplateResponseDTO
+func ToServiceRecordTemplateResponseDTO(template *domain.ServiceRecordTemplate) *ServiceRecordTemplateResponseDTO {
+	if template == nil {
+		return nil
+	}
+
+	fields, _ := template.GetFields()
+	if fields == nil {
+		fields = []domain.ServiceRecordField{}
+	}
+
+	return &ServiceRecordTemplateResponseDTO{
+		BaseResponse: BaseResponse{
+			ID:        template.ID,
+			CreatedAt: template.CreatedAt,
+			UpdatedAt: template.UpdatedAt,
+			Version:   template.Version,
+		},
+		BusinessID:  template.BusinessID,
+		CategoryID:  template.CategoryID,
+		Name:        template.Name,
+		Description: template.Description,
+		Fields:      fields,
+		IsActive:    template.IsActive,
+	}
+}
+
+// ToServiceRecordTemplateResponseDTOs converts a slice of ServiceRecordTemplate domain models to DTOs
+func ToServiceRecordTemplateResponseDTOs(templates []*domain.ServiceRecordTemplate) []*ServiceRecordTemplateResponseDTO {
+	result := make([]*ServiceRecordTemplateResponseDTO, len(templates))
+	for i, template := range templates {
+		result[i] = ToServiceRecordTemplateResponseDTO(template)
+	}
+	return result
+}
+
+// ToServiceRecordResponseDTO converts a ServiceRecord domain model to ServiceRecordResponseDTO
+func ToServiceRecordResponseDTO(record *domain.ServiceRecord) *ServiceRecordResponseDTO {
+	if record == nil {
+		return nil
+	}
+
+	fieldValues, _ := record.GetFieldValues()
+	products, _ := record.GetProductsUsed()
+
+	return &ServiceRecordResponseDTO{
+		BaseResponse: BaseResponse{
+			ID:        record.ID,
+			CreatedAt: record.CreatedAt,
+			UpdatedAt: record.UpdatedAt,
+			Version:   record.Version,
+		},
+		BusinessID:          record.BusinessID,
+		ClientID:            record.ClientID,
+		AppointmentID:       record.AppointmentID,
+		ServiceCompletionID: record.ServiceCompletionID,
+		ServiceID:           record.ServiceID,
+		StaffID:             record.StaffID,
+		TemplateID:          record.TemplateID,
+		CopiedFromID:        record.CopiedFromID,
+		RecordedAt:          record.RecordedAt,
+		FieldValues:         fieldValues,
+		ProductsUsed:        products,
+		ProcessingTime:      record.ProcessingTime,
+		Notes:               record.Notes,
+		IsDraft:             record.IsDraft,
+	}
+}
+
+// ToServiceRecordResponseDTOs converts a slice of ServiceRecord domain models to DTOs
+func ToServiceRecordResponseDTOs(records []*domain.ServiceRecord) []*ServiceRecordResponseDTO {
+	result := make([]*ServiceRecordResponseDTO, len(records))
+	for i, record := range records {
+		result[i] = ToServiceRecordResponseDTO(record)
+	}
+	return result
+}
@@ -0,0 +1,87 @@
+package dto
+
+import "github.com/assimoes/beautix/internal/domain"
+
+// SaveStaffBookingPageDTO represents the creation or update of a staff member's booking page.
+// Fields left out keep their value, or their default when the page is created.
+type SaveStaffBookingPageDTO struct {
+	StaffID       string           `json:"staff_id" validate:"required,uuid"`
+	Slug          *string          `json:"slug,omitempty" validate:"omitempty,min=3,max=100"` // Derived from the staff member's name when the page is created without one
+	Bio           Optional[string] `json:"bio" validate:"omitempty,max=2000"`
+	PortfolioURLs []string         `json:"portfolio_urls,omitempty" validate:"omitempty,dive,url,max=500"`
+	ServiceIDs    []string         `json:"service_ids,omitempty" validate:"omitempty,dive,uuid"` // An empty list offers all of the business's services
+	IsPublished   *bool            `json:"is_published,omitempty"`
+}
+
+// StaffBookingPageResponseDTO represents a staff member's booking page as managed by the business
+type StaffBookingPageResponseDTO struct {
+	BaseResponse
+	BusinessID    string   `json:"business_id"`
+	StaffID       string   `json:"staff_id"`
+	Slug          string   `json:"slug"`
+	Bio           *string  `json:"bio,omitempty"`
+	PortfolioURLs []string `json:"portfolio_urls"`
+	ServiceIDs    []string `json:"service_ids"`
+	IsPublished   bool     `json:"is_published"`
+}
+
+// PublicStaffPageDTO represents a staff member's booking page as shown to online bookers
+type PublicStaffPageDTO struct {
+	BusinessID    string              `json:"business_id"`
+	BusinessName  string              `json:"business_name"`
+	StaffID       string              `json:"staff_id"`
+	StaffName     string              `json:"staff_name"` // First name only
+	Slug          string              `json:"slug"`
+	Bio           *string             `json:"bio,omitempty"`
+	PortfolioURLs []string            `json:"portfolio_urls"`
+	Services      []*PublicServiceDTO `json:"services"`
+}
+
+// ToStaffBookingPageResponseDTO converts a StaffBookingPage domain model to StaffBookingPageResponseDTO
+func ToStaffBookingPageResponseDTO(page *domain.StaffBookingPage) *StaffBookingPageResponseDTO {
+	if page == nil {
+		return nil
+	}
+
+	// Lists are validated when the page is saved
+	portfolio, _ := page.GetPortfolioURLs()
+	serviceIDs, _ := page.GetServiceIDs()
+	return &StaffBookingPageResponseDTO{
+		BaseResponse: BaseResponse{
+			ID:        page.ID,
+			CreatedAt: page.CreatedAt,
+			UpdatedAt: page.UpdatedAt,
+			Version:   page.Version,
+		},
+		BusinessID:    page.BusinessID,
+		StaffID:       page.StaffID,
+		Slug:          page.Slug,
+		Bio:           page.Bio,
+		PortfolioURLs: portfolio,
+		ServiceIDs:    serviceIDs,
+		IsPublished:   page.IsPublished,
+	}
+}
+
+// ToPublicStaffPageDTO converts a published StaffBookingPage to PublicStaffPageDTO
+func ToPublicStaffPageDTO(page *domain.StaffBookingPage, businessName, staffName string, services []*domain.Service) *PublicStaffPageDTO {
+	if page == nil {
+		return nil
+	}
+
+	portfolio, _ := page.GetPortfolioURLs()
+	result := &PublicStaffPageDTO{
+		BusinessID:    page.BusinessID,
+		BusinessName:  businessName,
+		StaffID:       page.StaffID,
+		StaffName:     staffName,
+		Slug:          page.Slug,
+		Bio:           page.Bio,
+		PortfolioURLs: portfolio,
+		Services:      make([]*PublicServiceDTO, len(services)),
+	}
+	for i, service := range services {
+		result.Services[i] = ToPublicServiceDTO(service)
+	}
+	return result
+}
@@ -0,0 +1,83 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// StaffPerformanceQueryDTO represents a request for the performance records of a business
+type StaffPerformanceQueryDTO struct {
+	BusinessID string                   `json:"business_id" validate:"required,uuid"`
+	Period     domain.PerformancePeriod `json:"period" validate:"required,oneof=daily weekly monthly"`
+	StartDate  string                   `json:"start_date" validate:"required"`               // YYYY-MM-DD; periods starting on or after it
+	EndDate    string                   `json:"end_date" validate:"required"`                 // YYYY-MM-DD; periods starting on or before it
+	StaffID    *string                  `json:"staff_id,omitempty" validate:"omitempty,uuid"` // Every staff member when nil
+}
+
+// BackfillStaffPerformanceDTO represents a request to aggregate the performance of a business
+// over past dates
+type BackfillStaffPerformanceDTO struct {
+	BusinessID string `json:"business_id" validate:"required,uuid"`
+	StartDate  string `json:"start_date" validate:"required"` // YYYY-MM-DD, the first day
+	EndDate    string `json:"end_date" validate:"required"`   // YYYY-MM-DD, the last day
+}
+
+// StaffPerformanceResponseDTO represents what a staff member did during a period
+type StaffPerformanceResponseDTO struct {
+	BaseResponse
+	BusinessID            string                   `json:"business_id"`
+	StaffID               string                   `json:"staff_id"`
+	Period                domain.PerformancePeriod `json:"period"`
+	StartDate             time.Time                `json:"start_date"`
+	EndDate               time.Time                `json:"end_date"`
+	TotalAppointments     int                      `json:"total_appointments"`
+	CompletedAppointments int                      `json:"completed_appointments"`
+	CanceledAppointments  int                      `json:"canceled_appointments"`
+	NoShowAppointments    int                      `json:"no_show_appointments"`
+	TotalRevenue          decimal.Decimal          `json:"total_revenue"`
+	AverageRating         *decimal.Decimal         `json:"average_rating,omitempty"`
+	ClientRetentionRate   *decimal.Decimal         `json:"client_retention_rate,omitempty"`
+	NewClients            int                      `json:"new_clients"`
+	ReturnClients         int                      `json:"return_clients"`
+}
+
+// ToStaffPerformanceResponseDTO converts a StaffPerformance domain model to
+// StaffPerformanceResponseDTO
+func ToStaffPerformanceResponseDTO(record *domain.StaffPerformance) *StaffPerformanceResponseDTO {
+	if record == nil {
+		return nil
+	}
+
+	return &StaffPerformanceResponseDTO{
+		BaseResponse: BaseResponse{
+			ID:        record.ID,
+			CreatedAt: record.CreatedAt,
+			UpdatedAt: record.UpdatedAt,
+			Version:   record.Version,
+		},
+		BusinessID:            record.BusinessID,
+		StaffID:               record.StaffID,
+		Period:                record.Period,
+		StartDate:             record.StartDate,
+		EndDate:               record.EndDate,
+		TotalAppointments:     record.TotalAppointments,
+		CompletedAppointments: record.CompletedAppointments,
+		CanceledAppointments:  record.CanceledAppointments,
+		NoShowAppointments:    record.NoShowAppointments,
+		TotalRevenue:          record.TotalRevenue,
+		AverageRating:         record.AverageRating,
+		ClientRetentionRate:   record.ClientRetentionRate,
+		NewClients:            record.NewClients,
+		ReturnClients:         record.ReturnClients,
+	}
+}
+
+// PerformanceRunDTO represents a scheduled aggregation of the staff performance of every
+// business with recent appointments
+type PerformanceRunDTO struct {
+	Businesses int `json:"businesses"`
+	Records    int `json:"records"` // Records written, including those left as they were
+	Failed     int `json:"failed"`
+}
@@ -0,0 +1,145 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// CreateStaffShiftDTO represents the data for adding a shift to a staff member's rota: a weekly
+// shift on a weekday, or a one-off shift on a date
+type CreateStaffShiftDTO struct {
+	StaffID        string                `json:"staff_id" validate:"required,uuid"`
+	Kind           domain.StaffShiftKind `json:"kind" validate:"required,oneof=recurring one_off"`
+	Weekday        *int                  `json:"weekday,omitempty" validate:"omitempty,min=0,max=6"` // Recurring shifts: 0 for Sunday to 6 for Saturday
+	ShiftDate      *string               `json:"shift_date,omitempty"`                               // One-off shifts: YYYY-MM-DD
+	StartTime      string                `json:"start_time" validate:"required"`                     // HH:MM in the business's time zone
+	EndTime        string                `json:"end_time" validate:"required"`                       // HH:MM; 24:00 for midnight
+	EffectiveFrom  *string               `json:"effective_from,omitempty"`                           // Recurring shifts: YYYY-MM-DD
+	EffectiveUntil *string               `json:"effective_until,omitempty"`                          // Recurring shifts: YYYY-MM-DD, inclusive
+	Notes          *string               `json:"notes,omitempty" validate:"omitempty,max=500"`
+}
+
+// UpdateStaffShiftDTO represents the data for changing a shift; absent fields are left as they
+// are. A shift cannot change kind or staff member: delete it and add another.
+type UpdateStaffShiftDTO struct {
+	Weekday         *int             `json:"weekday,omitempty" validate:"omitempty,min=0,max=6"`
+	ShiftDate       *string          `json:"shift_date,omitempty"`
+	StartTime       *string          `json:"start_time,omitempty"`
+	EndTime         *string          `json:"end_time,omitempty"`
+	EffectiveFrom   Optional[string] `json:"effective_from"`
+	EffectiveUntil  Optional[string] `json:"effective_until"`
+	Notes           Optional[string] `json:"notes" validate:"omitempty,max=500"`
+	ExpectedVersion *int             `json:"expected_version,omitempty" validate:"omitempty,min=1"` // See RescheduleAppointmentDTO
+}
+
+// StaffShiftResponseDTO represents a shift of a staff member
+type StaffShiftResponseDTO struct {
+	BaseResponse
+	BusinessID     string                `json:"business_id"`
+	StaffID        string                `json:"staff_id"`
+	Kind           domain.StaffShiftKind `json:"kind"`
+	Weekday        *int                  `json:"weekday,omitempty"`
+	ShiftDate      *string               `json:"shift_date,omitempty"`
+	StartTime      string                `json:"start_time"`
+	EndTime        string                `json:"end_time"`
+	EffectiveFrom  *string               `json:"effective_from,omitempty"`
+	EffectiveUntil *string               `json:"effective_until,omitempty"`
+	Notes          *string               `json:"notes,omitempty"`
+}
+
+// ToStaffShiftResponseDTO converts a StaffShift domain model to StaffShiftResponseDTO
+func ToStaffShiftResponseDTO(shift *domain.StaffShift) *StaffShiftResponseDTO {
+	if shift == nil {
+		return nil
+	}
+
+	return &StaffShiftResponseDTO{
+		BaseResponse: BaseResponse{
+			ID:        shift.ID,
+			CreatedAt: shift.CreatedAt,
+			UpdatedAt: shift.UpdatedAt,
+			Version:   shift.Version,
+		},
+		BusinessID:     shift.BusinessID,
+		StaffID:        shift.StaffID,
+		Kind:           shift.Kind,
+		Weekday:        shift.Weekday,
+		ShiftDate:      formatDate(shift.ShiftDate),
+		StartTime:      shift.StartTime,
+		EndTime:        shift.EndTime,
+		EffectiveFrom:  formatDate(shift.EffectiveFrom),
+		EffectiveUntil: formatDate(shift.EffectiveUntil),
+		Notes:          shift.Notes,
+	}
+}
+
+// formatDate formats a date column as YYYY-MM-DD
+func formatDate(date *time.Time) *string {
+	if date == nil {
+		return nil
+	}
+	formatted := date.Format(time.DateOnly)
+	return &formatted
+}
+
+// RotaQueryDTO represents a request for the week of shifts of a business's staff
+type RotaQueryDTO struct {
+	BusinessID string  `json:"business_id" validate:"required,uuid"`
+	WeekOf     string  `json:"week_of" validate:"required"`                  // YYYY-MM-DD of any day of the week, which starts on Monday
+	StaffID    *string `json:"staff_id,omitempty" validate:"omitempty,uuid"` // Every active staff member when nil
+}
+
+// RotaShiftDTO represents a shift worked on a day of a rota
+type RotaShiftDTO struct {
+	ShiftID   string                `json:"shift_id"`
+	Kind      domain.StaffShiftKind `json:"kind"`
+	Date      string                `json:"date"`
+	StartTime time.Time             `json:"start_time"`
+	EndTime   time.Time             `json:"end_time"`
+	Notes     *string               `json:"notes,omitempty"`
+}
+
+// StaffRotaDTO represents the week of a staff member
+type StaffRotaDTO struct {
+	StaffID string          `json:"staff_id"`
+	OnRota  bool            `json:"on_rota"`
+	Shifts  []*RotaShiftDTO `json:"shifts"`
+	Minutes int             `json:"minutes"`
+}
+
+// RotaDTO represents the week of shifts of a business's staff
+type RotaDTO struct {
+	BusinessID string          `json:"business_id"`
+	WeekStart  string          `json:"week_start"`
+	Days       []string        `json:"days"`
+	Staff      []*StaffRotaDTO `json:"staff"`
+}
+
+// ToRotaDTO converts a Rota to RotaDTO
+func ToRotaDTO(businessID string, rota *domain.Rota) *RotaDTO {
+	result := &RotaDTO{
+		BusinessID: businessID,
+		WeekStart:  rota.WeekStart.Format(time.DateOnly),
+		Days:       make([]string, domain.RotaDays),
+		Staff:      make([]*StaffRotaDTO, len(rota.Staff)),
+	}
+	for i := range result.Days {
+		result.Days[i] = rota.WeekStart.AddDate(0, 0, i).Format(time.DateOnly)
+	}
+	for i, week := range rota.Staff {
+		shifts := make([]*RotaShiftDTO, len(week.Shifts))
+		for j, shift := range week.Shifts {
+			shifts[j] = &RotaShiftDTO{
+				ShiftID:   shift.ShiftID,
+				Kind:      shift.Kind,
+				Date:      shift.Date,
+				StartTime: shift.Start,
+				EndTime:   shift.End,
+				Notes:     shift.Notes,
+			}
+		}
+		result.Staff[i] = &StaffRotaDTO{StaffID: week.StaffID, OnRota: week.OnRota, Shifts: shifts, Minutes: week.Minutes}
+	}
+	return result
+}
@@ -0,0 +1,35 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// EntitlementUsageDTO represents how much of an entitlement a business uses and its plan allows
+type EntitlementUsageDTO struct {
+	Entitlement domain.Entitlement `json:"entitlement"`
+	Used        int                `json:"used"`
+	Limit       *int               `json:"limit,omitempty"` // Unlimited when nil
+}
+
+// BusinessSubscriptionDTO represents the subscription tier of a business and what it uses of it
+type BusinessSubscriptionDTO struct {
+	BusinessID        string                     `json:"business_id"`
+	Tier              domain.SubscriptionTier    `json:"tier"`                      // The tier the business has now
+	SubscribedTier    *domain.SubscriptionTier   `json:"subscribed_tier,omitempty"` // The paid tier subscribed to, if any
+	Status            *domain.SubscriptionStatus `json:"status,omitempty"`
+	CurrentPeriodEnd  *time.Time                 `json:"current_period_end,omitempty"`
+	TrialEndsAt       *time.Time                 `json:"trial_ends_at,omitempty"`
+	CancelAtPeriodEnd bool                       `json:"cancel_at_period_end"`
+	Usage             []*EntitlementUsageDTO     `json:"usage"`
+}
+
+// ChangeSubscriptionPlanDTO represents the data for moving a business to another tier. Paid
+// tiers are charged to the payment method, which is required when the business has none on
+// file yet.
+type ChangeSubscriptionPlanDTO struct {
+	BusinessID      string                  `json:"business_id" validate:"required,uuid"`
+	Tier            domain.SubscriptionTier `json:"tier" validate:"required,oneof=free pro premium"`
+	PaymentMethodID *string                 `json:"payment_method_id,omitempty" validate:"omitempty,max=255"`
+}
@@ -0,0 +1,109 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// ClockDTO represents the caller clocking in or out at a business
+type ClockDTO struct {
+	BusinessID string  `json:"business_id" validate:"required,uuid"`
+	Notes      *string `json:"notes,omitempty" validate:"omitempty,max=500"`
+}
+
+// AdjustTimeEntryDTO represents a manager correcting a time entry, such as a forgotten
+// clock-out; absent fields are left as they are
+type AdjustTimeEntryDTO struct {
+	ClockIn         *time.Time       `json:"clock_in,omitempty"`
+	ClockOut        *time.Time       `json:"clock_out,omitempty"`
+	Notes           Optional[string] `json:"notes" validate:"omitempty,max=500"`
+	ExpectedVersion *int             `json:"expected_version,omitempty" validate:"omitempty,min=1"` // See RescheduleAppointmentDTO
+}
+
+// TimesheetQueryDTO represents a request for the time entries of a pay period
+type TimesheetQueryDTO struct {
+	BusinessID string  `json:"business_id" validate:"required,uuid"`
+	StartDate  string  `json:"start_date" validate:"required"`               // YYYY-MM-DD, the first day of the period
+	EndDate    string  `json:"end_date" validate:"required"`                 // YYYY-MM-DD, the last day of the period
+	StaffID    *string `json:"staff_id,omitempty" validate:"omitempty,uuid"` // Every staff member when nil
+}
+
+// TimeEntryResponseDTO represents a period a staff member was clocked in
+type TimeEntryResponseDTO struct {
+	BaseResponse
+	BusinessID string                 `json:"business_id"`
+	StaffID    string                 `json:"staff_id"`
+	ClockIn    time.Time              `json:"clock_in"`
+	ClockOut   *time.Time             `json:"clock_out,omitempty"`
+	Minutes    int                    `json:"minutes"`
+	Flags      []domain.TimeEntryFlag `json:"flags"`
+	Notes      *string                `json:"notes,omitempty"`
+	AdjustedBy *string                `json:"adjusted_by,omitempty"`
+}
+
+// ToTimeEntryResponseDTO converts a TimeEntry domain model to TimeEntryResponseDTO
+func ToTimeEntryResponseDTO(entry *domain.TimeEntry) *TimeEntryResponseDTO {
+	if entry == nil {
+		return nil
+	}
+
+	flags, _ := entry.GetFlags()
+	return &TimeEntryResponseDTO{
+		BaseResponse: BaseResponse{
+			ID:        entry.ID,
+			CreatedAt: entry.CreatedAt,
+			UpdatedAt: entry.UpdatedAt,
+			Version:   entry.Version,
+		},
+		BusinessID: entry.BusinessID,
+		StaffID:    entry.StaffID,
+		ClockIn:    entry.ClockIn,
+		ClockOut:   entry.ClockOut,
+		Minutes:    int(entry.Duration().Minutes()),
+		Flags:      flags,
+		Notes:      entry.Notes,
+		AdjustedBy: entry.AdjustedBy,
+	}
+}
+
+// TimesheetLineDTO represents the time a staff member worked during a pay period
+type TimesheetLineDTO struct {
+	StaffID   string `json:"staff_id"`
+	StaffName string `json:"staff_name"`
+	Minutes   int    `json:"minutes"`
+	Entries   int    `json:"entries"`
+	Flagged   int    `json:"flagged"`
+	Open      int    `json:"open"`
+}
+
+// TimesheetDTO represents the time the staff of a business worked during a pay period
+type TimesheetDTO struct {
+	BusinessID  string              `json:"business_id"`
+	PeriodStart string              `json:"period_start"`
+	PeriodEnd   string              `json:"period_end"`
+	Lines       []*TimesheetLineDTO `json:"lines"`
+	CSV         string              `json:"csv"` // The lines as a CSV file for payroll software
+}
+
+// ToTimesheetDTO converts a Timesheet to TimesheetDTO
+func ToTimesheetDTO(businessID string, timesheet *domain.Timesheet, csv string) *TimesheetDTO {
+	lines := make([]*TimesheetLineDTO, len(timesheet.Lines))
+	for i, line := range timesheet.Lines {
+		lines[i] = &TimesheetLineDTO{
+			StaffID:   line.StaffID,
+			StaffName: line.StaffName,
+			Minutes:   line.Minutes,
+			Entries:   line.Entries,
+			Flagged:   line.Flagged,
+			Open:      line.Open,
+		}
+	}
+	return &TimesheetDTO{
+		BusinessID:  businessID,
+		PeriodStart: timesheet.Start.Format(time.DateOnly),
+		PeriodEnd:   timesheet.End.AddDate(0, 0, -1).Format(time.DateOnly),
+		Lines:       lines,
+		CSV:         csv,
+	}
+}
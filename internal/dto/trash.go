@@ -0,0 +1,63 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// TrashFilterDTO represents the criteria for browsing the deleted records of a business
+type TrashFilterDTO struct {
+	BusinessID string `json:"business_id" validate:"required,uuid"`
+	EntityType string `json:"entity_type" validate:"required,oneof=client service appointment"`
+}
+
+// RestoreDTO represents the data for restoring a deleted record
+type RestoreDTO struct {
+	BusinessID string `json:"business_id" validate:"required,uuid"`
+	EntityType string `json:"entity_type" validate:"required,oneof=client service appointment"`
+	ID         string `json:"id" validate:"required,uuid"`
+}
+
+// TrashItemDTO represents a deleted record that can still be restored
+type TrashItemDTO struct {
+	ID              string    `json:"id"`
+	BusinessID      string    `json:"business_id"`
+	EntityType      string    `json:"entity_type"`
+	Label           string    `json:"label"` // What the record is, e.g. the client's name
+	DeletedAt       time.Time `json:"deleted_at"`
+	DeletedBy       *string   `json:"deleted_by,omitempty"`
+	RestorableUntil time.Time `json:"restorable_until"`
+}
+
+// ToClientTrashItemDTO converts a deleted client to a TrashItemDTO
+func ToClientTrashItemDTO(client *domain.Client) *TrashItemDTO {
+	return toTrashItemDTO(client.BaseModel, client.BusinessID, domain.TrashEntityClient, client.GetFullName())
+}
+
+// ToServiceTrashItemDTO converts a deleted service to a TrashItemDTO
+func ToServiceTrashItemDTO(service *domain.Service) *TrashItemDTO {
+	return toTrashItemDTO(service.BaseModel, service.BusinessID, domain.TrashEntityService, service.Name)
+}
+
+// ToAppointmentTrashItemDTO converts a deleted appointment to a TrashItemDTO, labelled by its
+// title, or else its start time
+func ToAppointmentTrashItemDTO(appointment *domain.Appointment) *TrashItemDTO {
+	label := appointment.StartTime.UTC().Format(time.RFC3339)
+	if appointment.Title != nil && *appointment.Title != "" {
+		label = *appointment.Title + " (" + label + ")"
+	}
+	return toTrashItemDTO(appointment.BaseModel, appointment.BusinessID, domain.TrashEntityAppointment, label)
+}
+
+func toTrashItemDTO(model domain.BaseModel, businessID string, entityType domain.TrashEntityType, label string) *TrashItemDTO {
+	return &TrashItemDTO{
+		ID:              model.ID,
+		BusinessID:      businessID,
+		EntityType:      string(entityType),
+		Label:           label,
+		DeletedAt:       model.DeletedAt.Time,
+		DeletedBy:       model.DeletedBy,
+		RestorableUntil: domain.RestorableUntil(model.DeletedAt.Time),
+	}
+}
@@ -0,0 +1,26 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// TrialStatusDTO represents where a business is in its free trial, for the banners of the app
+type TrialStatusDTO struct {
+	BusinessID  string                  `json:"business_id"`
+	State       domain.TrialState       `json:"state"`
+	Tier        domain.SubscriptionTier `json:"tier"` // The tier the business has now
+	TrialEndsAt *time.Time              `json:"trial_ends_at,omitempty"`
+	GraceEndsAt *time.Time              `json:"grace_ends_at,omitempty"` // When the business is restricted unless it subscribes
+	DaysLeft    int                     `json:"days_left"`               // Until the trial ends, or the grace period while in it
+	ExpiredAt   *time.Time              `json:"expired_at,omitempty"`
+}
+
+// TrialRunDTO summarizes a run of the trial expiration job
+type TrialRunDTO struct {
+	Due        int `json:"due"`
+	Notified   int `json:"notified"`
+	Restricted int `json:"restricted"`
+	Failed     int `json:"failed"`
+}
@@ -15,10 +15,12 @@ type CreateUserDTO struct {
 
 // UpdateUserDTO represents the data for updating a user
 type UpdateUserDTO struct {
-	FirstName *string `json:"first_name,omitempty" validate:"omitempty,min=2,max=100"`
-	LastName  *string `json:"last_name,omitempty" validate:"omitempty,min=2,max=100"`
-	Phone     *string `json:"phone,omitempty" validate:"omitempty,min=10,max=20"`
-	IsActive  *bool   `json:"is_active,omitempty"`
+	FirstName       *string          `json:"first_name,omitempty" validate:"omitempty,min=2,max=100"`
+	LastName        *string          `json:"last_name,omitempty" validate:"omitempty,min=2,max=100"`
+	Phone           Optional[string] `json:"phone" validate:"omitempty,min=10,max=20"`
+	IsActive        *bool            `json:"is_active,omitempty"`
+	Locale          Optional[string] `json:"locale" validate:"omitempty,oneof=en pt"`                          // Cleared to follow the browser's language
+	ExpectedVersion *int             `json:"expected_version,omitempty" column:"-" validate:"omitempty,min=1"` // See RescheduleAppointmentDTO
 }
 
 // UserResponseDTO represents the response data for a user
@@ -31,6 +33,7 @@ type UserResponseDTO struct {
 	Phone     *string `json:"phone,omitempty"`
 	IsActive  bool    `json:"is_active"`
 	FullName  string  `json:"full_name"`
+	Locale    *string `json:"locale,omitempty"`
 }
 
 // UserWithBusinessesDTO represents a user with their businesses
@@ -77,6 +80,7 @@ func ToUserResponseDTO(user *domain.User) *UserResponseDTO {
 			ID:        user.ID,
 			CreatedAt: user.CreatedAt,
 			UpdatedAt: user.UpdatedAt,
+			Version:   user.Version,
 		},
 		Email:     user.Email,
 		ClerkID:   user.ClerkID,
@@ -85,6 +89,7 @@ func ToUserResponseDTO(user *domain.User) *UserResponseDTO {
 		Phone:     user.Phone,
 		IsActive:  user.IsActive,
 		FullName:  user.GetFullName(),
+		Locale:    user.Locale,
 	}
 }
 
@@ -0,0 +1,73 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// ClerkSessionDTO represents a session of a user as Clerk reports it
+type ClerkSessionDTO struct {
+	ID           string    `json:"id"`
+	UserAgent    string    `json:"user_agent,omitempty"`
+	IPAddress    string    `json:"ip_address,omitempty"`
+	DeviceName   string    `json:"device_name,omitempty"` // e.g. "Chrome on macOS"
+	CreatedAt    time.Time `json:"created_at"`
+	LastActiveAt time.Time `json:"last_active_at"`
+}
+
+// RevokeAllSessionsDTO represents the data for signing out every device of the caller
+type RevokeAllSessionsDTO struct {
+	Reason domain.SessionRevocationReason `json:"reason" validate:"required,oneof=sign_out_all password_change two_factor_change"`
+}
+
+// UserSessionDTO represents a device or API key a user is signed in with
+type UserSessionDTO struct {
+	BaseResponse
+	Kind       domain.SessionKind `json:"kind"`
+	DeviceName *string            `json:"device_name,omitempty"`
+	UserAgent  *string            `json:"user_agent,omitempty"`
+	IPAddress  *string            `json:"ip_address,omitempty"`
+	IssuedAt   time.Time          `json:"issued_at"`
+	LastSeenAt time.Time          `json:"last_seen_at"`
+	RevokedAt  *time.Time         `json:"revoked_at,omitempty"`
+	Current    bool               `json:"current"` // Whether the request was made with the session
+}
+
+// RevokeAllSessionsResultDTO represents the outcome of signing out every device of a user
+type RevokeAllSessionsResultDTO struct {
+	RevokedCount int64     `json:"revoked_count"`
+	RevokedAt    time.Time `json:"revoked_at"`
+}
+
+// ToUserSessionDTO converts a UserSession domain model to UserSessionDTO, flagged as current
+// when the request was made with it
+func ToUserSessionDTO(session *domain.UserSession, current bool) *UserSessionDTO {
+	if session == nil {
+		return nil
+	}
+
+	return &UserSessionDTO{
+		BaseResponse: BaseResponse{
+			ID:        session.ID,
+			CreatedAt: session.CreatedAt,
+			UpdatedAt: session.UpdatedAt,
+			Version:   session.Version,
+		},
+		Kind:       session.Kind,
+		DeviceName: session.DeviceName,
+		UserAgent:  session.UserAgent,
+		IPAddress:  session.IPAddress,
+		IssuedAt:   session.IssuedAt,
+		LastSeenAt: session.LastSeenAt,
+		RevokedAt:  session.RevokedAt,
+		Current:    current,
+	}
+}
+
+// ClerkSessionClaimsDTO represents the caller identified by a verified Clerk session token
+type ClerkSessionClaimsDTO struct {
+	UserID    string    `json:"user_id"`    // The Clerk user
+	SessionID string    `json:"session_id"` // The Clerk session
+	IssuedAt  time.Time `json:"issued_at"`  // When the token was issued; the session is at least this old
+}
@@ -0,0 +1,41 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// WatchdogFindingDTO represents the outcome of a watchdog check
+type WatchdogFindingDTO struct {
+	Check         domain.WatchdogCheck  `json:"check"`
+	Count         int64                 `json:"count"`
+	Remediated    int64                 `json:"remediated"`
+	OldestAt      *time.Time            `json:"oldest_at,omitempty"`
+	Action        domain.WatchdogAction `json:"action"`
+	Error         *string               `json:"error,omitempty"`
+	NeedsOperator bool                  `json:"needs_operator"`
+}
+
+// WatchdogReportDTO represents the outcome of a watchdog run
+type WatchdogReportDTO struct {
+	RanAt    time.Time             `json:"ran_at"`
+	Findings []*WatchdogFindingDTO `json:"findings"`
+}
+
+// ToWatchdogReportDTO converts a watchdog report to its DTO
+func ToWatchdogReportDTO(report *domain.WatchdogReport) *WatchdogReportDTO {
+	findings := make([]*WatchdogFindingDTO, len(report.Findings))
+	for i, finding := range report.Findings {
+		findings[i] = &WatchdogFindingDTO{
+			Check:         finding.Check,
+			Count:         finding.Count,
+			Remediated:    finding.Remediated,
+			OldestAt:      finding.OldestAt,
+			Action:        finding.Action,
+			Error:         finding.Error,
+			NeedsOperator: finding.NeedsOperator(),
+		}
+	}
+	return &WatchdogReportDTO{RanAt: report.RanAt, Findings: findings}
+}
@@ -0,0 +1,96 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// CreateWebhookSubscriptionDTO represents an endpoint a business subscribes to its events
+type CreateWebhookSubscriptionDTO struct {
+	BusinessID  string   `json:"business_id" validate:"required,uuid"`
+	URL         string   `json:"url" validate:"required,url,max=500"`
+	EventTypes  []string `json:"event_types" validate:"required,min=1,max=20,dive,required"`
+	Description *string  `json:"description,omitempty" validate:"omitempty,max=255"`
+}
+
+// UpdateWebhookSubscriptionDTO represents changes to a webhook subscription. Rotating the
+// secret returns the new one, which deliveries are signed with from then on.
+type UpdateWebhookSubscriptionDTO struct {
+	URL          *string  `json:"url,omitempty" validate:"omitempty,url,max=500"`
+	EventTypes   []string `json:"event_types,omitempty" validate:"omitempty,min=1,max=20,dive,required"`
+	Description  *string  `json:"description,omitempty" validate:"omitempty,max=255"`
+	IsActive     *bool    `json:"is_active,omitempty"`
+	RotateSecret bool     `json:"rotate_secret"`
+}
+
+// WebhookSubscriptionResponseDTO represents the response data for a webhook subscription
+type WebhookSubscriptionResponseDTO struct {
+	BaseResponse
+	BusinessID  string   `json:"business_id"`
+	URL         string   `json:"url"`
+	EventTypes  []string `json:"event_types"`
+	Description *string  `json:"description,omitempty"`
+	IsActive    bool     `json:"is_active"`
+	Secret      *string  `json:"secret,omitempty"` // Only when the subscription is created or its secret rotated
+}
+
+// WebhookDeliveryDTO represents an entry of a webhook subscription's delivery log
+type WebhookDeliveryDTO struct {
+	ID            string                       `json:"id"`
+	EventID       string                       `json:"event_id"`
+	EventType     string                       `json:"event_type"`
+	Status        domain.WebhookDeliveryStatus `json:"status"`
+	Attempts      int                          `json:"attempts"`
+	NextAttemptAt *time.Time                   `json:"next_attempt_at,omitempty"` // While pending
+	LastAttemptAt *time.Time                   `json:"last_attempt_at,omitempty"`
+	StatusCode    *int                         `json:"status_code,omitempty"`
+	LastError     *string                      `json:"last_error,omitempty"`
+	DurationMs    *int64                       `json:"duration_ms,omitempty"`
+	DeliveredAt   *time.Time                   `json:"delivered_at,omitempty"`
+	CreatedAt     time.Time                    `json:"created_at"`
+}
+
+// ToWebhookSubscriptionResponseDTO converts a WebhookSubscription domain model to
+// WebhookSubscriptionResponseDTO, without its secret
+func ToWebhookSubscriptionResponseDTO(subscription *domain.WebhookSubscription) *WebhookSubscriptionResponseDTO {
+	if subscription == nil {
+		return nil
+	}
+
+	eventTypes, _ := subscription.GetEventTypes()
+	return &WebhookSubscriptionResponseDTO{
+		BaseResponse: BaseResponse{
+			ID:        subscription.ID,
+			CreatedAt: subscription.CreatedAt,
+			UpdatedAt: subscription.UpdatedAt,
+			Version:   subscription.Version,
+		},
+		BusinessID:  subscription.BusinessID,
+		URL:         subscription.URL,
+		EventTypes:  eventTypes,
+		Description: subscription.Description,
+		IsActive:    subscription.IsActive,
+	}
+}
+
+// ToWebhookDeliveryDTO converts a WebhookDelivery domain model to WebhookDeliveryDTO
+func ToWebhookDeliveryDTO(delivery *domain.WebhookDelivery) *WebhookDeliveryDTO {
+	result := &WebhookDeliveryDTO{
+		ID:            delivery.ID,
+		EventID:       delivery.EventID,
+		EventType:     delivery.EventType,
+		Status:        delivery.Status,
+		Attempts:      delivery.Attempts,
+		LastAttemptAt: delivery.LastAttemptAt,
+		StatusCode:    delivery.StatusCode,
+		LastError:     delivery.LastError,
+		DurationMs:    delivery.DurationMs,
+		DeliveredAt:   delivery.DeliveredAt,
+		CreatedAt:     delivery.CreatedAt,
+	}
+	if delivery.IsPending() {
+		result.NextAttemptAt = &delivery.NextAttemptAt
+	}
+	return result
+}
@@ -2,48 +2,356 @@ package auth
 
 import (
 	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/assimoes/beautix/configs"
+	"github.com/assimoes/beautix/internal/domain"
 	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/resilience"
 )
 
+// ProviderName is the name Clerk's health is reported under
+const ProviderName = "clerk"
+
+// Policy protects calls to Clerk. Token verification sits on the request path, so calls are
+// given a short SLA.
+var Policy = resilience.Policy{
+	Timeout:          5 * time.Second,
+	MaxAttempts:      3,
+	BaseBackoff:      100 * time.Millisecond,
+	MaxBackoff:       time.Second,
+	FailureThreshold: 5,
+	OpenDuration:     30 * time.Second,
+}
+
+// ErrInvalidToken is returned for session tokens that are malformed, not signed by the Clerk
+// instance or expired
+var ErrInvalidToken = fmt.Errorf("invalid session token: %w", domain.ErrUnauthenticated)
+
+// tokenLeeway is how far the clocks of Clerk and the API may drift apart when checking the
+// validity period of a token
+const tokenLeeway = 5 * time.Second
+
+// keyRefreshInterval is how often the signing keys are fetched again at most, so that tokens
+// naming unknown keys cannot make every request call Clerk
+const keyRefreshInterval = time.Minute
+
+// errNotImplemented is returned by the placeholder calls. It is permanent so that it does not
+// open the circuit.
+var errNotImplemented = resilience.Permanent(errors.New("clerk integration not implemented yet"))
+
 // ClerkClient is a placeholder for Clerk integration
 // TODO: Implement proper Clerk integration in next task
 type ClerkClient struct {
-	// placeholder fields
+	secretKey string
+	apiURL    string
+	http      *http.Client
+	guard     *resilience.Guard
+
+	keysMu        sync.Mutex
+	keys          map[string]*rsa.PublicKey // The instance's signing keys by key ID
+	keysFetchedAt time.Time
 }
 
-// NewClerkClient creates a new Clerk client (placeholder)
-func NewClerkClient() *ClerkClient {
-	return &ClerkClient{}
+// NewClerkClient creates a new Clerk client (placeholder) for the configured instance, with calls
+// protected by the guard
+func NewClerkClient(cfg configs.AuthConfig, guard *resilience.Guard) *ClerkClient {
+	return &ClerkClient{
+		secretKey: cfg.ClerkSecretKey,
+		apiURL:    strings.TrimRight(cfg.ClerkAPIURL, "/"),
+		http:      &http.Client{Timeout: Policy.Timeout},
+		guard:     guard,
+	}
+}
+
+// Ping checks that the Clerk Backend API can be reached and accepts the secret key. It bypasses
+// the guard, so that probes neither wait on an open circuit nor open it.
+func (c *ClerkClient) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.apiURL+"/jwks", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.secretKey)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach clerk: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return errors.New("clerk refused the secret key")
+	case resp.StatusCode >= 300:
+		return fmt.Errorf("clerk answered with status %d", resp.StatusCode)
+	}
+	return nil
 }
 
 // VerifyToken verifies a Clerk JWT token (placeholder)
 func (c *ClerkClient) VerifyToken(ctx context.Context, token string) (*dto.ClerkUserDTO, error) {
-	// TODO: Implement actual token verification
-	return nil, fmt.Errorf("clerk integration not implemented yet")
+	err := c.guard.Do(ctx, func(ctx context.Context) error {
+		// TODO: Implement actual token verification
+		return errNotImplemented
+	})
+	return nil, err
+}
+
+// clerkSessionClaims are the claims of a session token Clerk issues. Times are Unix seconds.
+type clerkSessionClaims struct {
+	Subject   string `json:"sub"` // The Clerk user
+	SessionID string `json:"sid"`
+	IssuedAt  int64  `json:"iat"`
+	NotBefore int64  `json:"nbf"`
+	Expires   int64  `json:"exp"`
+}
+
+// VerifySessionToken verifies a session token Clerk issued to a signed-in user, checking its
+// RS256 signature against the instance's signing keys and its validity period
+func (c *ClerkClient) VerifySessionToken(ctx context.Context, token string) (*dto.ClerkSessionClaimsDTO, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidToken
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil || header.Alg != "RS256" {
+		return nil, ErrInvalidToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	key, err := c.signingKey(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	var claims clerkSessionClaims
+	if err := decodeSegment(parts[1], &claims); err != nil || claims.Subject == "" || claims.SessionID == "" {
+		return nil, ErrInvalidToken
+	}
+	now := time.Now()
+	if now.After(time.Unix(claims.Expires, 0).Add(tokenLeeway)) || now.Add(tokenLeeway).Before(time.Unix(claims.NotBefore, 0)) {
+		return nil, ErrInvalidToken
+	}
+	return &dto.ClerkSessionClaimsDTO{
+		UserID:    claims.Subject,
+		SessionID: claims.SessionID,
+		IssuedAt:  time.Unix(claims.IssuedAt, 0).UTC(),
+	}, nil
+}
+
+// decodeSegment decodes a base64url encoded JSON segment of a token
+func decodeSegment(segment string, out any) error {
+	raw, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, out)
+}
+
+// jsonWebKey is a key of the instance's JSON Web Key Set
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// signingKey returns the instance's signing key with the ID, fetching the keys again when it is
+// not known, as after Clerk rotates them
+func (c *ClerkClient) signingKey(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	c.keysMu.Lock()
+	defer c.keysMu.Unlock()
+
+	if key, ok := c.keys[kid]; ok {
+		return key, nil
+	}
+	if c.keys != nil && time.Since(c.keysFetchedAt) < keyRefreshInterval {
+		return nil, ErrInvalidToken
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/jwks", &set); err != nil {
+		return nil, fmt.Errorf("failed to fetch clerk signing keys: %w", err)
+	}
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Kty != "RSA" {
+			continue
+		}
+		n, errN := base64.RawURLEncoding.DecodeString(jwk.N)
+		e, errE := base64.RawURLEncoding.DecodeString(jwk.E)
+		if errN != nil || errE != nil {
+			continue
+		}
+		keys[jwk.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+	c.keys = keys
+	c.keysFetchedAt = time.Now()
+
+	if key, ok := c.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, ErrInvalidToken
 }
 
 // GetUser retrieves a user from Clerk (placeholder)
 func (c *ClerkClient) GetUser(ctx context.Context, userID string) (*dto.ClerkUserDTO, error) {
-	// TODO: Implement actual user retrieval
-	return nil, fmt.Errorf("clerk integration not implemented yet")
+	err := c.guard.Do(ctx, func(ctx context.Context) error {
+		// TODO: Implement actual user retrieval
+		return errNotImplemented
+	})
+	return nil, err
 }
 
 // CreateUser creates a user in Clerk (placeholder)
 func (c *ClerkClient) CreateUser(ctx context.Context, userData dto.ClerkUserDTO) (*dto.ClerkUserDTO, error) {
-	// TODO: Implement actual user creation
-	return nil, fmt.Errorf("clerk integration not implemented yet")
+	err := c.guard.Do(ctx, func(ctx context.Context) error {
+		// TODO: Implement actual user creation
+		return errNotImplemented
+	})
+	return nil, err
 }
 
 // UpdateUser updates a user in Clerk (placeholder)
 func (c *ClerkClient) UpdateUser(ctx context.Context, userID string, userData dto.ClerkUserDTO) (*dto.ClerkUserDTO, error) {
-	// TODO: Implement actual user update
-	return nil, fmt.Errorf("clerk integration not implemented yet")
+	err := c.guard.Do(ctx, func(ctx context.Context) error {
+		// TODO: Implement actual user update
+		return errNotImplemented
+	})
+	return nil, err
 }
 
 // DeleteUser deletes a user from Clerk (placeholder)
 func (c *ClerkClient) DeleteUser(ctx context.Context, userID string) error {
-	// TODO: Implement actual user deletion
-	return fmt.Errorf("clerk integration not implemented yet")
-}
\ No newline at end of file
+	err := c.guard.Do(ctx, func(ctx context.Context) error {
+		// TODO: Implement actual user deletion
+		return errNotImplemented
+	})
+	return err
+}
+
+// clerkSession is a session as the Clerk Backend API returns it. Times are Unix milliseconds.
+type clerkSession struct {
+	ID             string `json:"id"`
+	CreatedAt      int64  `json:"created_at"`
+	LastActiveAt   int64  `json:"last_active_at"`
+	LatestActivity *struct {
+		BrowserName string `json:"browser_name"`
+		DeviceType  string `json:"device_type"`
+		IPAddress   string `json:"ip_address"`
+	} `json:"latest_activity"`
+}
+
+// clerkError is the error body of the Clerk Backend API
+type clerkError struct {
+	Errors []struct {
+		Message     string `json:"message"`
+		LongMessage string `json:"long_message"`
+		Code        string `json:"code"`
+	} `json:"errors"`
+}
+
+// ListSessions lists the active sessions of a Clerk user
+func (c *ClerkClient) ListSessions(ctx context.Context, userID string) ([]*dto.ClerkSessionDTO, error) {
+	query := url.Values{}
+	query.Set("user_id", userID)
+	query.Set("status", "active")
+
+	var sessions []clerkSession
+	if err := c.do(ctx, http.MethodGet, "/sessions?"+query.Encode(), &sessions); err != nil {
+		return nil, err
+	}
+
+	result := make([]*dto.ClerkSessionDTO, len(sessions))
+	for i, session := range sessions {
+		result[i] = &dto.ClerkSessionDTO{
+			ID:           session.ID,
+			CreatedAt:    time.UnixMilli(session.CreatedAt).UTC(),
+			LastActiveAt: time.UnixMilli(session.LastActiveAt).UTC(),
+		}
+		if activity := session.LatestActivity; activity != nil {
+			result[i].IPAddress = activity.IPAddress
+			switch {
+			case activity.BrowserName != "" && activity.DeviceType != "":
+				result[i].DeviceName = activity.BrowserName + " on " + activity.DeviceType
+			case activity.BrowserName != "":
+				result[i].DeviceName = activity.BrowserName
+			default:
+				result[i].DeviceName = activity.DeviceType
+			}
+		}
+	}
+	return result, nil
+}
+
+// RevokeSession signs a Clerk session out
+func (c *ClerkClient) RevokeSession(ctx context.Context, sessionID string) error {
+	var session clerkSession
+	return c.do(ctx, http.MethodPost, "/sessions/"+url.PathEscape(sessionID)+"/revoke", &session)
+}
+
+// do sends a request through the guard and decodes the JSON response into out
+func (c *ClerkClient) do(ctx context.Context, method, path string, out any) error {
+	return c.guard.Do(ctx, func(ctx context.Context) error {
+		return c.send(ctx, method, path, out)
+	})
+}
+
+// send makes a single request. Errors Clerk will repeat on retry are marked permanent.
+func (c *ClerkClient) send(ctx context.Context, method, path string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, method, c.apiURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.secretKey)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("clerk request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	decoder := json.NewDecoder(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode >= 300 {
+		var failure clerkError
+		_ = decoder.Decode(&failure)
+		message := resp.Status
+		if len(failure.Errors) > 0 {
+			message = failure.Errors[0].Message
+			if failure.Errors[0].LongMessage != "" {
+				message = failure.Errors[0].LongMessage
+			}
+		}
+		err := fmt.Errorf("clerk answered with status %d: %s", resp.StatusCode, message)
+		if !resilience.IsRetryableStatus(resp.StatusCode) {
+			return resilience.Permanent(err)
+		}
+		return err
+	}
+	return decoder.Decode(out)
+}
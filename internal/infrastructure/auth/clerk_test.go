@@ -0,0 +1,124 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/infrastructure/resilience"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClerkClient(handler http.HandlerFunc) (*ClerkClient, func()) {
+	server := httptest.NewServer(handler)
+	return &ClerkClient{secretKey: "sk_test_123", apiURL: server.URL, http: server.Client()}, server.Close
+}
+
+func TestClerkClient_ListSessions(t *testing.T) {
+	c, closeServer := newTestClerkClient(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/sessions", r.URL.Path)
+		assert.Equal(t, "user_123", r.URL.Query().Get("user_id"))
+		assert.Equal(t, "active", r.URL.Query().Get("status"))
+		assert.Equal(t, "Bearer sk_test_123", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`[{"id": "sess_1", "created_at": 1700000000000, "last_active_at": 1700000600000,
+			"latest_activity": {"browser_name": "Chrome", "device_type": "Macintosh", "ip_address": "203.0.113.7"}},
+			{"id": "sess_2", "created_at": 1700000000000, "last_active_at": 1700000000000}]`))
+	})
+	defer closeServer()
+
+	sessions, err := c.ListSessions(context.Background(), "user_123")
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	assert.Equal(t, "sess_1", sessions[0].ID)
+	assert.Equal(t, "Chrome on Macintosh", sessions[0].DeviceName)
+	assert.Equal(t, "203.0.113.7", sessions[0].IPAddress)
+	assert.Equal(t, time.UnixMilli(1700000600000).UTC(), sessions[0].LastActiveAt)
+	assert.Empty(t, sessions[1].DeviceName)
+}
+
+func TestClerkClient_RevokeSession(t *testing.T) {
+	c, closeServer := newTestClerkClient(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		if r.URL.Path != "/sessions/sess_1/revoke" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors": [{"message": "not found", "long_message": "No session was found", "code": "resource_not_found"}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id": "sess_1", "status": "revoked"}`))
+	})
+	defer closeServer()
+
+	require.NoError(t, c.RevokeSession(context.Background(), "sess_1"))
+
+	err := c.RevokeSession(context.Background(), "sess_missing")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "No session was found")
+	assert.True(t, resilience.IsPermanent(err), "missing sessions are not retried")
+}
+
+// signToken signs the claims as an RS256 session token with the key ID
+func signToken(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]any) string {
+	t.Helper()
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "kid": kid, "typ": "JWT"})
+	require.NoError(t, err)
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestClerkClient_VerifySessionToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	fetches := 0
+	c, closeServer := newTestClerkClient(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/jwks", r.URL.Path)
+		fetches++
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kid": "ins_1", "kty": "RSA", "alg": "RS256",
+			"n": base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	defer closeServer()
+
+	now := time.Now()
+	claims := map[string]any{"sub": "user_123", "sid": "sess_1", "iat": now.Unix(), "nbf": now.Unix(), "exp": now.Add(time.Minute).Unix()}
+	session, err := c.VerifySessionToken(context.Background(), signToken(t, key, "ins_1", claims))
+	require.NoError(t, err)
+	assert.Equal(t, "user_123", session.UserID)
+	assert.Equal(t, "sess_1", session.SessionID)
+	assert.Equal(t, time.Unix(now.Unix(), 0).UTC(), session.IssuedAt)
+
+	expired := map[string]any{"sub": "user_123", "sid": "sess_1", "iat": now.Add(-time.Hour).Unix(), "exp": now.Add(-time.Minute).Unix()}
+	_, err = c.VerifySessionToken(context.Background(), signToken(t, key, "ins_1", expired))
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	_, err = c.VerifySessionToken(context.Background(), signToken(t, other, "ins_1", claims))
+	assert.ErrorIs(t, err, ErrInvalidToken, "tokens signed by another key are refused")
+
+	_, err = c.VerifySessionToken(context.Background(), signToken(t, other, "ins_2", claims))
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	assert.Equal(t, 1, fetches, "unknown keys are not fetched again right away")
+
+	_, err = c.VerifySessionToken(context.Background(), "not-a-token")
+	assert.ErrorIs(t, err, domain.ErrUnauthenticated)
+}
@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"encoding/base64"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func signedWebhook(payload []byte, at time.Time, secret string) WebhookSignature {
+	key, _ := base64.StdEncoding.DecodeString(secret[len(webhookSecretPrefix):])
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	signature := base64.StdEncoding.EncodeToString(signWebhook(payload, "msg_1", timestamp, key))
+	return WebhookSignature{ID: "msg_1", Timestamp: timestamp, Signature: "v1,b3RoZXI= v1," + signature}
+}
+
+func TestParseWebhook(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	secret := webhookSecretPrefix + base64.StdEncoding.EncodeToString([]byte("endpoint-secret"))
+	other := webhookSecretPrefix + base64.StdEncoding.EncodeToString([]byte("another-secret"))
+	payload := []byte(`{"type": "user.updated", "object": "event",
+		"data": {"id": "user_123", "two_factor_enabled": true, "password_last_updated_at": 1772366400000}}`)
+
+	event, err := ParseWebhook(payload, signedWebhook(payload, now, secret), secret, now)
+	require.NoError(t, err)
+	assert.Equal(t, EventUserUpdated, event.Type)
+
+	user, err := event.User()
+	require.NoError(t, err)
+	assert.Equal(t, "user_123", user.ID)
+	assert.True(t, user.TwoFactorEnabled)
+	assert.Equal(t, now, *user.PasswordUpdatedAt())
+
+	_, err = ParseWebhook(payload, signedWebhook(payload, now, other), secret, now)
+	assert.ErrorIs(t, err, ErrInvalidSignature, "signed with another secret")
+
+	_, err = ParseWebhook(payload, signedWebhook(payload, now.Add(-10*time.Minute), secret), secret, now)
+	assert.ErrorIs(t, err, ErrInvalidSignature, "signed too long ago")
+
+	_, err = ParseWebhook([]byte(`{"type": "user.deleted"}`), signedWebhook(payload, now, secret), secret, now)
+	assert.ErrorIs(t, err, ErrInvalidSignature, "payload changed after signing")
+
+	_, err = ParseWebhook(payload, WebhookSignature{}, secret, now)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	_, err = ParseWebhook(payload, signedWebhook(payload, now, secret), "", now)
+	assert.ErrorIs(t, err, ErrWebhookNotConfigured)
+}
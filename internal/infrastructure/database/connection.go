@@ -3,7 +3,7 @@ package database
 import (
 	"context"
 	"fmt"
-	"time"
+	"strings"
 
 	"github.com/assimoes/beautix/configs"
 	"github.com/rs/zerolog/log"
@@ -35,7 +35,7 @@ func NewConnectionWithConfig(config *configs.Config, gormConfig *gorm.Config) (*
 		}
 	}
 
-	db, err := gorm.Open(postgres.Open(config.Database.URL), gormConfig)
+	db, err := gorm.Open(postgres.Open(dsn(config.Database)), gormConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
@@ -46,13 +46,27 @@ func NewConnectionWithConfig(config *configs.Config, gormConfig *gorm.Config) (*
 	}
 
 	// Set connection pool settings
-	sqlDB.SetMaxIdleConns(10)
-	sqlDB.SetMaxOpenConns(100)
-	sqlDB.SetConnMaxLifetime(time.Hour)
+	sqlDB.SetMaxOpenConns(config.Database.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(config.Database.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(config.Database.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(config.Database.ConnMaxIdleTime)
 
 	return &DB{DB: db}, nil
 }
 
+// dsn returns the URL of the database with the session settings of every connection. The
+// statement timeout is set on the connection, so that it applies whichever query runs on it.
+func dsn(config configs.DatabaseConfig) string {
+	if config.StatementTimeout <= 0 {
+		return config.URL
+	}
+	separator := "?"
+	if strings.Contains(config.URL, "?") {
+		separator = "&"
+	}
+	return fmt.Sprintf("%s%sstatement_timeout=%d", config.URL, separator, config.StatementTimeout.Milliseconds())
+}
+
 // Ping checks if the database connection is alive
 func (db *DB) Ping() error {
 	sqlDB, err := db.DB.DB()
@@ -67,6 +81,39 @@ func (db *DB) Ping() error {
 	return nil
 }
 
+// PingContext checks if the database connection is alive, giving up when the context is done
+func (db *DB) PingContext(ctx context.Context) error {
+	sqlDB, err := db.DB.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get SQL DB: %w", err)
+	}
+
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return nil
+}
+
+// MigrationVersion returns the migration the schema is at, as golang-migrate records it in
+// schema_migrations, and whether that migration failed midway. A database never migrated is at
+// version 0.
+func (db *DB) MigrationVersion(ctx context.Context) (version uint64, dirty bool, err error) {
+	conn := db.WithContext(ctx)
+	if !conn.Migrator().HasTable("schema_migrations") {
+		return 0, false, nil
+	}
+
+	var state struct {
+		Version uint64
+		Dirty   bool
+	}
+	if err := conn.Raw(`SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&state).Error; err != nil {
+		return 0, false, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	return state.Version, state.Dirty, nil
+}
+
 // Close closes the database connection
 func (db *DB) Close() error {
 	sqlDB, err := db.DB.DB()
@@ -167,7 +167,8 @@ func CleanupAllTables(t *testing.T, db *DB) {
 		"waiting_list",
 		"user_connected_accounts",
 		"business_locations",
-		"service_ratings",
+		"reviews",
+		"review_requests",
 		"staff_performance",
 		"resource_bookings",
 		
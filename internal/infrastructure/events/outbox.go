@@ -0,0 +1,40 @@
+// Package events implements a transactional outbox for domain events. Services record events
+// through the Outbox in the transaction of the changes they describe, so that an event exists
+// exactly when its change was committed, and the Relay publishes the recorded events to the
+// subscribers in the background, retrying those that fail.
+package events
+
+import (
+	"context"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// Outbox records domain events to be published by the relay
+type Outbox struct {
+	repo domain.DomainEventRepository
+}
+
+// NewOutbox creates an outbox writing to the event log
+func NewOutbox(repo domain.DomainEventRepository) *Outbox {
+	return &Outbox{repo: repo}
+}
+
+// Record writes the events within the transaction tx, so that they are rolled back with it. A
+// nil tx writes them within the unit of work ctx belongs to, or on their own outside one.
+func (o *Outbox) Record(ctx context.Context, tx *gorm.DB, events ...*domain.DomainEvent) error {
+	var repo domain.BaseRepository[domain.DomainEvent] = o.repo
+	if tx != nil {
+		repo = o.repo.WithTx(tx)
+	}
+	for _, event := range events {
+		if err := event.Validate(); err != nil {
+			return err
+		}
+		if err := repo.Create(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
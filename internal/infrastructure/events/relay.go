@@ -0,0 +1,171 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/infrastructure/lifecycle"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// relayBatchSize bounds the events claimed at once
+	relayBatchSize = 100
+	// relayLease is how long claimed events are held before another relay may claim them,
+	// should this one stop before saving their outcome
+	relayLease = time.Minute
+)
+
+// Subscriber receives the events the relay publishes, e.g. a projection, the loyalty engine or
+// a webhook dispatcher. Handle must be idempotent: an event is published again to every
+// subscriber when any of them fails, and when it is replayed.
+type Subscriber interface {
+	Name() string
+	Handle(ctx context.Context, event *domain.DomainEvent) error
+}
+
+// RelayRun summarizes a run publishing events from the outbox
+type RelayRun struct {
+	Claimed   int
+	Published int
+	Failed    int // To be retried with backoff
+	Abandoned int // Failed too many times, left to be replayed
+}
+
+// Relay publishes the events recorded in the outbox to the subscribers, in the order they
+// occurred. Several relays can run against the same log, each claiming its own events.
+type Relay struct {
+	repo        domain.DomainEventRepository
+	mu          sync.RWMutex
+	subscribers map[string]Subscriber
+	wake        chan struct{}
+}
+
+// NewRelay creates a relay publishing from the event log
+func NewRelay(repo domain.DomainEventRepository) *Relay {
+	return &Relay{
+		repo:        repo,
+		subscribers: make(map[string]Subscriber),
+		wake:        make(chan struct{}, 1),
+	}
+}
+
+// Subscribe adds a subscriber to every event published from now on
+func (r *Relay) Subscribe(subscriber Subscriber) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subscribers[subscriber.Name()] = subscriber
+}
+
+// Subscriber returns the subscriber with the given name
+func (r *Relay) Subscriber(name string) (Subscriber, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	subscriber, ok := r.subscribers[name]
+	return subscriber, ok
+}
+
+// Subscribers returns the names of the subscribers in a stable order
+func (r *Relay) Subscribers() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.subscribers))
+	for name := range r.subscribers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Notify wakes a started relay, so that events just recorded are published without waiting
+// for the next poll
+func (r *Relay) Notify() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run publishes a batch of the unpublished events that are due. An event is published once
+// every subscriber took it; otherwise it is retried with backoff until it is abandoned.
+func (r *Relay) Run(ctx context.Context, now time.Time) (*RelayRun, error) {
+	events, err := r.repo.ClaimUnpublished(ctx, now, relayLease, relayBatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	run := &RelayRun{Claimed: len(events)}
+	for _, event := range events {
+		if err := r.publish(ctx, event); err != nil {
+			event.RecordPublishFailure(err, now)
+			if event.AbandonedAt != nil {
+				run.Abandoned++
+			} else {
+				run.Failed++
+			}
+			log.Warn().Err(err).
+				Str("event_id", event.ID).
+				Str("event_type", event.EventType).
+				Int("attempts", event.PublishAttempts).
+				Msg("Failed to publish event")
+		} else {
+			event.MarkPublished(now)
+			run.Published++
+		}
+		if err := r.repo.Update(ctx, event); err != nil {
+			// The lease runs out and the event is published again
+			log.Error().Err(err).Str("event_id", event.ID).Msg("Failed to record event publication")
+		}
+	}
+	return run, nil
+}
+
+// Start publishes events until the context is done, every interval and whenever notified. The
+// batch in flight is finished with lifecycle.Work(ctx).
+func (r *Relay) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	work := lifecycle.Work(ctx)
+	for {
+		for {
+			run, err := r.Run(work, time.Now())
+			if err != nil {
+				if work.Err() == nil {
+					log.Error().Err(err).Msg("Failed to relay events")
+				}
+				break
+			}
+			// Once stopped, only the batch in flight is finished
+			if run.Claimed < relayBatchSize || ctx.Err() != nil {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-r.wake:
+		}
+	}
+}
+
+// publish hands the event to every subscriber within the event's tenant, joining their errors
+func (r *Relay) publish(ctx context.Context, event *domain.DomainEvent) error {
+	if event.BusinessID != nil {
+		ctx = domain.WithTenant(ctx, domain.TenantContext{BusinessID: *event.BusinessID})
+	}
+	var errs []error
+	for _, name := range r.Subscribers() {
+		subscriber, _ := r.Subscriber(name)
+		if err := subscriber.Handle(ctx, event); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,148 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/repository/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSubscriber records the events it handles, failing each with err until it is cleared
+type recordingSubscriber struct {
+	name    string
+	err     error
+	handled []string
+}
+
+func (s *recordingSubscriber) Name() string { return s.name }
+
+func (s *recordingSubscriber) Handle(ctx context.Context, event *domain.DomainEvent) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.handled = append(s.handled, event.ID)
+	return nil
+}
+
+func recordEvent(t *testing.T, outbox *Outbox, occurredAt time.Time) *domain.DomainEvent {
+	t.Helper()
+	businessID := "business-1"
+	event, err := domain.NewDomainEvent(domain.AggregateAppointment, "appointment-1", domain.EventAppointmentCreated, &businessID, nil)
+	require.NoError(t, err)
+	event.OccurredAt = occurredAt
+	require.NoError(t, outbox.Record(context.Background(), nil, event))
+	return event
+}
+
+func TestRelay_Run(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, time.October, 17, 9, 0, 0, 0, time.UTC)
+	repo := memory.NewDomainEventRepository(memory.NewDB())
+	outbox := NewOutbox(repo)
+	relay := NewRelay(repo)
+	projector := &recordingSubscriber{name: "calendar"}
+	webhooks := &recordingSubscriber{name: "webhooks"}
+	relay.Subscribe(projector)
+	relay.Subscribe(webhooks)
+	assert.Equal(t, []string{"calendar", "webhooks"}, relay.Subscribers())
+
+	first := recordEvent(t, outbox, now.Add(-2*time.Minute))
+	second := recordEvent(t, outbox, now.Add(-time.Minute))
+
+	t.Run("publishes events in the order they occurred", func(t *testing.T) {
+		run, err := relay.Run(ctx, now)
+		require.NoError(t, err)
+		assert.Equal(t, &RelayRun{Claimed: 2, Published: 2}, run)
+		assert.Equal(t, []string{first.ID, second.ID}, projector.handled)
+		assert.Equal(t, []string{first.ID, second.ID}, webhooks.handled)
+
+		events, err := repo.FindByIDs(ctx, []string{first.ID})
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.False(t, events[0].IsUnpublished())
+		assert.Equal(t, 1, events[0].PublishAttempts)
+
+		run, err = relay.Run(ctx, now)
+		require.NoError(t, err)
+		assert.Zero(t, run.Claimed, "published events are not published again")
+	})
+
+	t.Run("retries failed events with backoff", func(t *testing.T) {
+		webhooks.err = errors.New("endpoint returned 503")
+		event := recordEvent(t, outbox, now)
+
+		run, err := relay.Run(ctx, now)
+		require.NoError(t, err)
+		assert.Equal(t, &RelayRun{Claimed: 1, Failed: 1}, run)
+
+		stored, err := repo.FindByIDs(ctx, []string{event.ID})
+		require.NoError(t, err)
+		require.Len(t, stored, 1)
+		assert.True(t, stored[0].IsUnpublished())
+		require.NotNil(t, stored[0].PublishError)
+		assert.Contains(t, *stored[0].PublishError, "webhooks: endpoint returned 503")
+		assert.Equal(t, now.Add(domain.EventPublishRetryDelay(1)), *stored[0].NextPublishAt)
+
+		run, err = relay.Run(ctx, now.Add(5*time.Second))
+		require.NoError(t, err)
+		assert.Zero(t, run.Claimed, "the event waits out its backoff")
+
+		webhooks.err = nil
+		run, err = relay.Run(ctx, now.Add(10*time.Second))
+		require.NoError(t, err)
+		assert.Equal(t, &RelayRun{Claimed: 1, Published: 1}, run)
+		assert.Equal(t, event.ID, webhooks.handled[len(webhooks.handled)-1])
+	})
+
+	t.Run("abandons events that keep failing", func(t *testing.T) {
+		projector.err = errors.New("calendar unavailable")
+		defer func() { projector.err = nil }()
+		event := recordEvent(t, outbox, now)
+
+		at := now
+		for attempt := 1; attempt < domain.MaxEventPublishAttempts; attempt++ {
+			run, err := relay.Run(ctx, at)
+			require.NoError(t, err)
+			assert.Equal(t, &RelayRun{Claimed: 1, Failed: 1}, run)
+			at = at.Add(domain.EventPublishRetryDelay(attempt))
+		}
+		run, err := relay.Run(ctx, at)
+		require.NoError(t, err)
+		assert.Equal(t, &RelayRun{Claimed: 1, Abandoned: 1}, run)
+
+		stored, err := repo.FindByIDs(ctx, []string{event.ID})
+		require.NoError(t, err)
+		require.Len(t, stored, 1)
+		assert.False(t, stored[0].IsUnpublished())
+		assert.NotNil(t, stored[0].AbandonedAt)
+
+		run, err = relay.Run(ctx, at.Add(24*time.Hour))
+		require.NoError(t, err)
+		assert.Zero(t, run.Claimed, "abandoned events are left to be replayed")
+	})
+}
+
+func TestRelay_ClaimLeasesEvents(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, time.October, 17, 9, 0, 0, 0, time.UTC)
+	repo := memory.NewDomainEventRepository(memory.NewDB())
+	event := recordEvent(t, NewOutbox(repo), now)
+
+	claimed, err := repo.ClaimUnpublished(ctx, now, relayLease, relayBatchSize)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	assert.Equal(t, event.ID, claimed[0].ID)
+
+	claimed, err = repo.ClaimUnpublished(ctx, now.Add(30*time.Second), relayLease, relayBatchSize)
+	require.NoError(t, err)
+	assert.Empty(t, claimed, "another relay does not claim leased events")
+
+	claimed, err = repo.ClaimUnpublished(ctx, now.Add(relayLease), relayLease, relayBatchSize)
+	require.NoError(t, err)
+	assert.Len(t, claimed, 1, "events are claimed again once the lease runs out")
+}
@@ -0,0 +1,75 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Database checks that the database answers. The API cannot serve traffic without it.
+func Database(ping func(ctx context.Context) error) Check {
+	return Check{
+		Name:     "database",
+		Critical: true,
+		Run: func(ctx context.Context) (string, error) {
+			return "", ping(ctx)
+		},
+	}
+}
+
+// Migrations checks that the database schema is at the latest migration the API was built with.
+// A schema that is behind or left dirty by a failed migration lacks what the API queries.
+func Migrations(current func(ctx context.Context) (version uint64, dirty bool, err error), latest uint64) Check {
+	return Check{
+		Name:     "migrations",
+		Critical: true,
+		Run: func(ctx context.Context) (string, error) {
+			version, dirty, err := current(ctx)
+			if err != nil {
+				return "", err
+			}
+			detail := fmt.Sprintf("version %d of %d", version, latest)
+			switch {
+			case dirty:
+				return detail, fmt.Errorf("migration %d failed and left the schema dirty", version)
+			case version < latest:
+				return detail, fmt.Errorf("%d pending migrations", latest-version)
+			}
+			return detail, nil
+		},
+	}
+}
+
+// Provider checks that an external provider can be reached. The API keeps serving without it.
+func Provider(name string, ping func(ctx context.Context) error) Check {
+	return Check{
+		Name: name,
+		Run: func(ctx context.Context) (string, error) {
+			return "", ping(ctx)
+		},
+	}
+}
+
+// JobQueue checks that workers keep up with the job queue: the oldest due job has waited at most
+// maxLag. A lagging queue delays reminders and expiries but not requests.
+func JobQueue(oldestDue func(ctx context.Context, now time.Time) (*time.Time, error), maxLag time.Duration) Check {
+	return Check{
+		Name: "job_queue",
+		Run: func(ctx context.Context) (string, error) {
+			now := time.Now()
+			dueAt, err := oldestDue(ctx, now)
+			if err != nil {
+				return "", err
+			}
+			var lag time.Duration
+			if dueAt != nil {
+				lag = now.Sub(*dueAt).Round(time.Second)
+			}
+			detail := fmt.Sprintf("lag %s", lag)
+			if lag > maxLag {
+				return detail, fmt.Errorf("the oldest due job has waited more than %s", maxLag)
+			}
+			return detail, nil
+		},
+	}
+}
@@ -0,0 +1,92 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Statuses of a check and of the whole report
+const (
+	StatusOK          = "ok"
+	StatusDegraded    = "degraded"    // A non-critical dependency fails; the API keeps serving without it
+	StatusUnavailable = "unavailable" // A critical dependency fails; the API cannot serve traffic
+)
+
+// Check is a dependency the API needs to serve traffic. A failing critical check makes the API
+// unready; a failing non-critical one only degrades it.
+type Check struct {
+	Name     string
+	Critical bool
+	// Run checks the dependency, returning what it found, e.g. the lag of a queue
+	Run func(ctx context.Context) (detail string, err error)
+}
+
+// Result is the outcome of a check
+type Result struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	Critical  bool   `json:"critical"`
+	LatencyMS int64  `json:"latency_ms"`
+	Detail    string `json:"detail,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Report is the outcome of every check, with the worst status among them
+type Report struct {
+	Status string   `json:"status"`
+	Checks []Result `json:"checks"`
+}
+
+// Run runs the checks at once, each given at most the timeout, and reports them in order
+func Run(ctx context.Context, timeout time.Duration, checks []Check) Report {
+	report := Report{Status: StatusOK, Checks: make([]Result, len(checks))}
+
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Checks[i] = run(ctx, timeout, check)
+		}()
+	}
+	wg.Wait()
+
+	for _, result := range report.Checks {
+		report.Status = worst(report.Status, result.Status)
+	}
+	return report
+}
+
+// run runs a check within the timeout
+func run(ctx context.Context, timeout time.Duration, check Check) Result {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	detail, err := check.Run(ctx)
+	result := Result{
+		Name:      check.Name,
+		Status:    StatusOK,
+		Critical:  check.Critical,
+		LatencyMS: time.Since(start).Milliseconds(),
+		Detail:    detail,
+	}
+	if err != nil {
+		result.Status = StatusDegraded
+		if check.Critical {
+			result.Status = StatusUnavailable
+		}
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// worst returns the more severe of two statuses
+func worst(a, b string) string {
+	severity := map[string]int{StatusOK: 0, StatusDegraded: 1, StatusUnavailable: 2}
+	if severity[b] > severity[a] {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,108 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func passing(name string, critical bool) Check {
+	return Check{Name: name, Critical: critical, Run: func(ctx context.Context) (string, error) { return "", nil }}
+}
+
+func failing(name string, critical bool) Check {
+	return Check{Name: name, Critical: critical, Run: func(ctx context.Context) (string, error) {
+		return "", errors.New("down")
+	}}
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name     string
+		checks   []Check
+		expected string
+	}{
+		{"all pass", []Check{passing("database", true), passing("clerk", false)}, StatusOK},
+		{"non-critical fails", []Check{passing("database", true), failing("clerk", false)}, StatusDegraded},
+		{"critical fails", []Check{failing("database", true), failing("clerk", false)}, StatusUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := Run(context.Background(), time.Second, tt.checks)
+			assert.Equal(t, tt.expected, report.Status)
+			require.Len(t, report.Checks, len(tt.checks))
+			for i, check := range tt.checks {
+				assert.Equal(t, check.Name, report.Checks[i].Name)
+			}
+		})
+	}
+}
+
+func TestRunTimesOutChecks(t *testing.T) {
+	slow := Check{Name: "clerk", Run: func(ctx context.Context) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	}}
+
+	report := Run(context.Background(), 10*time.Millisecond, []Check{slow})
+
+	assert.Equal(t, StatusDegraded, report.Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks[0].Error)
+}
+
+func TestMigrations(t *testing.T) {
+	tests := []struct {
+		name    string
+		version uint64
+		dirty   bool
+		wantErr string
+	}{
+		{"up to date", 67, false, ""},
+		{"behind", 64, false, "3 pending migrations"},
+		{"dirty", 67, true, "migration 67 failed and left the schema dirty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := Migrations(func(ctx context.Context) (uint64, bool, error) {
+				return tt.version, tt.dirty, nil
+			}, 67)
+
+			detail, err := check.Run(context.Background())
+			assert.Contains(t, detail, "of 67")
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestJobQueue(t *testing.T) {
+	due := func(age time.Duration) func(context.Context, time.Time) (*time.Time, error) {
+		return func(ctx context.Context, now time.Time) (*time.Time, error) {
+			if age == 0 {
+				return nil, nil
+			}
+			dueAt := now.Add(-age)
+			return &dueAt, nil
+		}
+	}
+
+	detail, err := JobQueue(due(0), time.Minute).Run(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "lag 0s", detail)
+
+	_, err = JobQueue(due(30*time.Second), time.Minute).Run(context.Background())
+	assert.NoError(t, err)
+
+	detail, err = JobQueue(due(2*time.Minute), time.Minute).Run(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, "lag 2m0s", detail)
+}
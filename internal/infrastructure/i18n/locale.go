@@ -0,0 +1,69 @@
+package i18n
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Locale is a language messages are written in
+type Locale string
+
+const (
+	English    Locale = "en"
+	Portuguese Locale = "pt"
+)
+
+// Default is the locale of callers whose language is not supported or not known
+const Default = English
+
+// Supported lists the locales messages are translated into
+var Supported = []Locale{English, Portuguese}
+
+// Parse returns the supported locale of a language tag such as "pt-PT" or "en_GB". Regional
+// variants share the messages of their language.
+func Parse(tag string) (Locale, bool) {
+	tag = strings.ToLower(strings.TrimSpace(strings.ReplaceAll(tag, "_", "-")))
+	language, _, _ := strings.Cut(tag, "-")
+	locale := Locale(language)
+	return locale, slices.Contains(Supported, locale)
+}
+
+// Negotiate returns the supported locale the caller prefers most by an Accept-Language header,
+// e.g. "pt-PT,pt;q=0.9,en;q=0.8", or Default when none of them is supported
+func Negotiate(acceptLanguage string) Locale {
+	best, bestWeight := Default, 0.0
+	for _, entry := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(entry, ";")
+		weight := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			weight = parsed
+		}
+		// The first of equally weighted languages is preferred
+		if locale, ok := Parse(tag); ok && weight > bestWeight {
+			best, bestWeight = locale, weight
+		}
+	}
+	return best
+}
+
+type contextKey struct{}
+
+// WithLocale returns a context carrying the locale messages for the caller are written in
+func WithLocale(ctx context.Context, locale Locale) context.Context {
+	return context.WithValue(ctx, contextKey{}, locale)
+}
+
+// FromContext returns the locale messages for the caller are written in, Default when the
+// context carries none
+func FromContext(ctx context.Context) Locale {
+	if locale, ok := ctx.Value(contextKey{}).(Locale); ok {
+		return locale
+	}
+	return Default
+}
@@ -0,0 +1,80 @@
+package i18n
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiate(t *testing.T) {
+	cases := map[string]Locale{
+		"":                           English,
+		"pt-PT,pt;q=0.9,en;q=0.8":    Portuguese,
+		"en-GB,en;q=0.9,pt;q=0.8":    English,
+		"de-DE,de;q=0.9,pt-BR;q=0.5": Portuguese,
+		"fr, en;q=0.2, pt;q=0.7":     Portuguese,
+		"de":                         English,
+		"pt;q=abc, en;q=0.1":         English,
+	}
+	for header, want := range cases {
+		assert.Equal(t, want, Negotiate(header), header)
+	}
+}
+
+func TestFromContext_DefaultsToEnglish(t *testing.T) {
+	assert.Equal(t, English, FromContext(context.Background()))
+	assert.Equal(t, Portuguese, FromContext(WithLocale(context.Background(), Portuguese)))
+}
+
+func TestCatalog_TranslatesEveryMessage(t *testing.T) {
+	for _, locale := range Supported {
+		for key := range catalog[Default] {
+			assert.Contains(t, catalog[locale], key, "%s is missing %s", locale, key)
+		}
+	}
+	assert.Equal(t, "Escolha um de: a, b.", Message(Portuguese, "validation.oneof", map[string]string{"param": "a, b"}))
+	assert.Equal(t, "missing.key", Message(Portuguese, "missing.key", nil))
+}
+
+func TestFieldMessage(t *testing.T) {
+	type input struct {
+		BusinessID string   `validate:"required,uuid"`
+		Name       string   `validate:"max=3"`
+		Capacity   int      `validate:"min=1"`
+		Kind       string   `validate:"oneof=room chair"`
+		Tags       []string `validate:"max=1"`
+	}
+	err := validator.New().Struct(input{Name: "long", Kind: "desk", Tags: []string{"a", "b"}})
+	var fieldErrors validator.ValidationErrors
+	require.ErrorAs(t, err, &fieldErrors)
+
+	messages := map[string]string{}
+	for _, fieldError := range fieldErrors {
+		messages[FieldName(fieldError.Field())] = FieldMessage(Portuguese, fieldError)
+	}
+	assert.Equal(t, map[string]string{
+		"businessId": "Este campo é obrigatório.",
+		"name":       "Não pode ter mais de 3 caracteres.",
+		"capacity":   "Tem de ser pelo menos 1.",
+		"kind":       "Escolha um de: room, chair.",
+		"tags":       "Escolha no máximo 1.",
+	}, messages)
+}
+
+func TestFieldName(t *testing.T) {
+	cases := map[string]string{
+		"ID":              "id",
+		"BusinessID":      "businessId",
+		"CategoryIDs":     "categoryIds",
+		"CoverPhotoURL":   "coverPhotoUrl",
+		"APIErrors":       "apiErrors",
+		"ExpectedVersion": "expectedVersion",
+		"ResourceIDs[0]":  "resourceIds[0]",
+	}
+	for field, want := range cases {
+		assert.Equal(t, want, FieldName(field), field)
+	}
+}
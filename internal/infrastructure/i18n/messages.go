@@ -0,0 +1,168 @@
+package i18n
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// catalog holds the messages of each locale by key. Arguments are written as {name}.
+var catalog = map[Locale]map[string]string{
+	English: {
+		"error.VALIDATION_ERROR":     "Some of the details are not valid.",
+		"error.NOT_FOUND":            "We could not find what you were looking for.",
+		"error.FORBIDDEN":            "You are not allowed to do this.",
+		"error.APPOINTMENT_CONFLICT": "The appointment cannot be booked at this time.",
+		"error.VERSION_CONFLICT":     "Someone else changed this in the meantime. Reload it and try again.",
+		"error.UPGRADE_REQUIRED":     "Your plan does not include this. Upgrade to continue.",
+		"error.UNKNOWN":              "Something went wrong. Please try again.",
+
+		"validation.required":           "This field is required.",
+		"validation.email":              "Enter a valid email address.",
+		"validation.uuid":               "This is not a valid identifier.",
+		"validation.url":                "Enter a valid web address.",
+		"validation.oneof":              "Choose one of: {param}.",
+		"validation.min":                "Must be at least {param}.",
+		"validation.min.text":           "Must be at least {param} characters long.",
+		"validation.min.list":           "Choose at least {param}.",
+		"validation.max":                "Must be at most {param}.",
+		"validation.max.text":           "Must be at most {param} characters long.",
+		"validation.max.list":           "Choose at most {param}.",
+		"validation.len":                "Must be exactly {param} characters long.",
+		"validation.gtfield":            "Must be after {param}.",
+		"validation.nefield":            "Must be different from {param}.",
+		"validation.unique":             "Each value may only be given once.",
+		"validation.datetime":           "Enter a date and time as {param}.",
+		"validation.currency":           "Enter a three-letter currency code, such as EUR.",
+		"validation.hexadecimal":        "Enter a hexadecimal value.",
+		"validation.bcp47_language_tag": "Enter a language code, such as pt or en-GB.",
+		"validation.required_without":   "Required unless {param} is given.",
+		"validation.excluded_with":      "Leave this out when {param} is given.",
+		"validation.invalid":            "This value is not valid.",
+
+		"conflict.overlap":           "Another appointment is booked at this time.",
+		"conflict.outside_hours":     "The business is closed at this time.",
+		"conflict.staff_unavailable": "The staff member is away at this time.",
+		"conflict.outside_shift":     "The staff member is not working at this time.",
+		"conflict.resource_busy":     "A room or piece of equipment it needs is in use at this time.",
+		"conflict.staff_unassigned":  "The staff member no longer works here.",
+	},
+	Portuguese: {
+		"error.VALIDATION_ERROR":     "Alguns dos dados não são válidos.",
+		"error.NOT_FOUND":            "Não encontrámos o que procurava.",
+		"error.FORBIDDEN":            "Não tem permissão para fazer isto.",
+		"error.APPOINTMENT_CONFLICT": "Não é possível fazer a marcação a esta hora.",
+		"error.VERSION_CONFLICT":     "Outra pessoa alterou isto entretanto. Recarregue e tente novamente.",
+		"error.UPGRADE_REQUIRED":     "O seu plano não inclui esta funcionalidade. Mude de plano para continuar.",
+		"error.UNKNOWN":              "Algo correu mal. Tente novamente.",
+
+		"validation.required":           "Este campo é obrigatório.",
+		"validation.email":              "Introduza um endereço de email válido.",
+		"validation.uuid":               "Este identificador não é válido.",
+		"validation.url":                "Introduza um endereço web válido.",
+		"validation.oneof":              "Escolha um de: {param}.",
+		"validation.min":                "Tem de ser pelo menos {param}.",
+		"validation.min.text":           "Tem de ter pelo menos {param} caracteres.",
+		"validation.min.list":           "Escolha pelo menos {param}.",
+		"validation.max":                "Não pode ser superior a {param}.",
+		"validation.max.text":           "Não pode ter mais de {param} caracteres.",
+		"validation.max.list":           "Escolha no máximo {param}.",
+		"validation.len":                "Tem de ter exatamente {param} caracteres.",
+		"validation.gtfield":            "Tem de ser posterior a {param}.",
+		"validation.nefield":            "Tem de ser diferente de {param}.",
+		"validation.unique":             "Cada valor só pode ser indicado uma vez.",
+		"validation.datetime":           "Introduza uma data e hora no formato {param}.",
+		"validation.currency":           "Introduza um código de moeda de três letras, como EUR.",
+		"validation.hexadecimal":        "Introduza um valor hexadecimal.",
+		"validation.bcp47_language_tag": "Introduza um código de idioma, como pt ou en-GB.",
+		"validation.required_without":   "Obrigatório, a menos que indique {param}.",
+		"validation.excluded_with":      "Não indique este campo quando indicar {param}.",
+		"validation.invalid":            "Este valor não é válido.",
+
+		"conflict.overlap":           "Já existe outra marcação a esta hora.",
+		"conflict.outside_hours":     "O negócio está fechado a esta hora.",
+		"conflict.staff_unavailable": "O profissional está ausente a esta hora.",
+		"conflict.outside_shift":     "O profissional não está de serviço a esta hora.",
+		"conflict.resource_busy":     "Uma sala ou equipamento necessário está ocupado a esta hora.",
+		"conflict.staff_unassigned":  "O profissional já não trabalha aqui.",
+	},
+}
+
+// Message returns the message with a key in a locale, with its arguments filled in. Messages
+// missing from a locale are written in English; unknown keys are returned as they are.
+func Message(locale Locale, key string, args map[string]string) string {
+	message, ok := catalog[locale][key]
+	if !ok {
+		message, ok = catalog[Default][key]
+	}
+	if !ok {
+		return key
+	}
+	for name, value := range args {
+		message = strings.ReplaceAll(message, "{"+name+"}", value)
+	}
+	return message
+}
+
+// FieldMessage returns the message for a field that failed a validator rule in a locale. Rules
+// without a message of their own get a generic one.
+func FieldMessage(locale Locale, err validator.FieldError) string {
+	key := "validation." + err.Tag()
+	switch err.Tag() {
+	case "min", "max":
+		// Lengths of text and lists are told apart from amounts
+		switch err.Kind() {
+		case reflect.String:
+			key += ".text"
+		case reflect.Slice, reflect.Map, reflect.Array:
+			key += ".list"
+		}
+	}
+	if _, ok := catalog[Default][key]; !ok {
+		key = "validation.invalid"
+	}
+	param := err.Param()
+	switch err.Tag() {
+	case "oneof":
+		param = strings.Join(strings.Fields(param), ", ")
+	case "gtfield", "nefield", "required_without", "excluded_with":
+		param = FieldName(param)
+	}
+	return Message(locale, key, map[string]string{"param": param})
+}
+
+// FieldName returns the GraphQL name of a DTO field, e.g. "businessId" for "BusinessID" and
+// "categoryIds" for "CategoryIDs"
+func FieldName(field string) string {
+	var name strings.Builder
+	for i := 0; i < len(field); {
+		end := i + 1
+		for end < len(field) && isUpper(field[end]) {
+			end++
+		}
+		if end-i == 1 {
+			for end < len(field) && !isUpper(field[end]) {
+				end++
+			}
+		} else if end < len(field) && isLower(field[end]) {
+			// The last capital of an initialism starts the next word, unless it is made plural
+			if field[end] == 's' && (end+1 == len(field) || !isLower(field[end+1])) {
+				end++
+			} else {
+				end--
+			}
+		}
+		word := strings.ToLower(field[i:end])
+		if name.Len() > 0 {
+			word = strings.ToUpper(word[:1]) + word[1:]
+		}
+		name.WriteString(word)
+		i = end
+	}
+	return name.String()
+}
+
+func isUpper(c byte) bool { return c >= 'A' && c <= 'Z' }
+
+func isLower(c byte) bool { return c >= 'a' && c <= 'z' }
@@ -0,0 +1,87 @@
+// Package jobs runs background work through a queue of jobs stored in the database: subsystems
+// enqueue jobs of a kind, and workers claim them once due and hand them to the handler
+// registered for the kind, retrying failed jobs with backoff until they are dead-lettered.
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// Option adjusts a job as it is enqueued
+type Option func(job *domain.Job)
+
+// At schedules the job to run at a time rather than as soon as possible
+func At(runAt time.Time) Option {
+	return func(job *domain.Job) { job.RunAt = runAt }
+}
+
+// In schedules the job to run once a delay has passed
+func In(delay time.Duration) Option {
+	return func(job *domain.Job) { job.RunAt = job.RunAt.Add(delay) }
+}
+
+// WithKey makes the job unique among the pending jobs of its kind: enqueueing it again while it
+// is pending keeps the job already enqueued, and it can be cancelled by key
+func WithKey(key string) Option {
+	return func(job *domain.Job) { job.UniqueKey = &key }
+}
+
+// ForBusiness runs the job's handler within the business's tenant
+func ForBusiness(businessID string) Option {
+	return func(job *domain.Job) { job.BusinessID = &businessID }
+}
+
+// MaxAttempts sets how many times the job runs before it is dead-lettered
+func MaxAttempts(attempts int) Option {
+	return func(job *domain.Job) { job.MaxAttempts = attempts }
+}
+
+// Queue enqueues jobs for the workers
+type Queue struct {
+	repo   domain.JobRepository
+	now    func() time.Time
+	notify func()
+}
+
+// NewQueue creates a queue storing jobs in the repository. When a runner works the queue in the
+// same process, it is woken whenever a job is enqueued to run right away.
+func NewQueue(repo domain.JobRepository, runner *Runner) *Queue {
+	queue := &Queue{repo: repo, now: time.Now, notify: func() {}}
+	if runner != nil {
+		queue.notify = runner.Notify
+	}
+	return queue
+}
+
+// Enqueue adds a job of a kind with the payload encoded as JSON, due now unless scheduled by
+// the options. A job with a key already pending is returned rather than enqueued again.
+func (q *Queue) Enqueue(ctx context.Context, kind string, payload any, opts ...Option) (*domain.Job, error) {
+	now := q.now()
+	job, err := domain.NewJob(kind, payload, now)
+	if err != nil {
+		return nil, err
+	}
+	for _, opt := range opts {
+		opt(job)
+	}
+	if err := job.Validate(); err != nil {
+		return nil, err
+	}
+
+	stored, created, err := q.repo.Enqueue(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+	if created && !stored.RunAt.After(now) {
+		q.notify()
+	}
+	return stored, nil
+}
+
+// Cancel calls off the pending job of a kind with a key, reporting whether there was one
+func (q *Queue) Cancel(ctx context.Context, kind, key string) (bool, error) {
+	return q.repo.CancelPending(ctx, kind, key, q.now())
+}
@@ -0,0 +1,195 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/infrastructure/lifecycle"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// runnerBatchSize bounds the jobs claimed at once
+	runnerBatchSize = 100
+	// runnerLease is how long claimed jobs are held before another worker may claim them,
+	// should this one stop before saving their outcome
+	runnerLease = 5 * time.Minute
+	// handlerTimeout bounds a job's run, so that it ends before its lease runs out and another
+	// worker runs it too
+	handlerTimeout = 4 * time.Minute
+)
+
+// Handler runs a job of the kind it is registered for. Handlers must be idempotent: a job runs
+// again when its worker stops before saving the outcome. Returning an error retries the job with
+// backoff, unless the error is wrapped with Permanent.
+type Handler func(ctx context.Context, job *domain.Job) error
+
+// permanentError marks a failure that retrying cannot fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps an error so that the job failing with it is dead-lettered right away rather
+// than retried, e.g. when its payload is invalid or what it works on no longer exists
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether a job failing with the error is not to be retried
+func IsPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
+
+// RunnerRun summarizes a run of the jobs that were due
+type RunnerRun struct {
+	Claimed   int
+	Succeeded int
+	Failed    int // To be retried with backoff
+	Dead      int // Failed every attempt, or permanently; left to be retried by an operator
+}
+
+// Runner works the job queue, running each job due with the handler registered for its kind.
+// Several runners can work the same queue, each claiming its own jobs.
+type Runner struct {
+	repo     domain.JobRepository
+	mu       sync.RWMutex
+	handlers map[string]Handler
+	wake     chan struct{}
+}
+
+// NewRunner creates a runner working the jobs in the repository
+func NewRunner(repo domain.JobRepository) *Runner {
+	return &Runner{
+		repo:     repo,
+		handlers: make(map[string]Handler),
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// Register sets the handler running the jobs of a kind
+func (r *Runner) Register(kind string, handler Handler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers[kind] = handler
+}
+
+// Kinds returns the kinds of job the runner has handlers for, in a stable order
+func (r *Runner) Kinds() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	kinds := make([]string, 0, len(r.handlers))
+	for kind := range r.handlers {
+		kinds = append(kinds, kind)
+	}
+	slices.Sort(kinds)
+	return kinds
+}
+
+// Notify wakes a started runner, so that jobs just enqueued run without waiting for the next poll
+func (r *Runner) Notify() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run runs a batch of the jobs that are due. A job that fails is retried with backoff until it
+// has used up its attempts, when it is dead-lettered. Jobs of a kind without a handler are
+// dead-lettered too, as no worker of this build can run them.
+func (r *Runner) Run(ctx context.Context, now time.Time) (*RunnerRun, error) {
+	jobs, err := r.repo.ClaimDue(ctx, now, runnerLease, runnerBatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	run := &RunnerRun{Claimed: len(jobs)}
+	for _, job := range jobs {
+		if err := r.execute(ctx, job); err != nil {
+			job.RecordFailure(err, IsPermanent(err), now)
+			if job.Status == domain.JobStatusDead {
+				run.Dead++
+			} else {
+				run.Failed++
+			}
+			log.Warn().Err(err).
+				Str("job_id", job.ID).
+				Str("kind", job.Kind).
+				Int("attempts", job.Attempts).
+				Str("status", string(job.Status)).
+				Msg("Job failed")
+		} else {
+			job.MarkSucceeded(now)
+			run.Succeeded++
+		}
+		if err := r.repo.Update(ctx, job); err != nil {
+			// The lease runs out and the job runs again
+			log.Error().Err(err).Str("job_id", job.ID).Msg("Failed to record job outcome")
+		}
+	}
+	return run, nil
+}
+
+// Start runs jobs until the context is done, every interval and whenever notified. The batch in
+// flight is finished with lifecycle.Work(ctx).
+func (r *Runner) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	work := lifecycle.Work(ctx)
+	for {
+		for {
+			run, err := r.Run(work, time.Now())
+			if err != nil {
+				if work.Err() == nil {
+					log.Error().Err(err).Msg("Failed to run jobs")
+				}
+				break
+			}
+			// Once stopped, only the batch in flight is finished
+			if run.Claimed < runnerBatchSize || ctx.Err() != nil {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-r.wake:
+		}
+	}
+}
+
+// execute runs the job with its handler within the job's tenant, turning a panic into a failure
+func (r *Runner) execute(ctx context.Context, job *domain.Job) (err error) {
+	r.mu.RLock()
+	handler, ok := r.handlers[job.Kind]
+	r.mu.RUnlock()
+	if !ok {
+		return Permanent(fmt.Errorf("no handler for jobs of kind %q", job.Kind))
+	}
+
+	if job.BusinessID != nil {
+		ctx = domain.WithTenant(ctx, domain.TenantContext{BusinessID: *job.BusinessID})
+	}
+	ctx, cancel := context.WithTimeout(ctx, handlerTimeout)
+	defer cancel()
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("job panicked: %v", recovered)
+		}
+	}()
+	return handler(ctx, job)
+}
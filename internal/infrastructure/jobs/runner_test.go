@@ -0,0 +1,193 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/repository/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type reminderPayload struct {
+	AppointmentID string `json:"appointment_id"`
+}
+
+func newTestQueue(repo domain.JobRepository, runner *Runner, now time.Time) *Queue {
+	queue := NewQueue(repo, runner)
+	queue.now = func() time.Time { return now }
+	return queue
+}
+
+func getJob(t *testing.T, repo domain.JobRepository, id string) *domain.Job {
+	t.Helper()
+	job, err := repo.GetByID(context.Background(), id)
+	require.NoError(t, err)
+	return job
+}
+
+func TestRunner_Run(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, time.October, 17, 9, 0, 0, 0, time.UTC)
+	repo := memory.NewJobRepository(memory.NewDB())
+	runner := NewRunner(repo)
+	queue := newTestQueue(repo, runner, now)
+
+	var handled []string
+	var failWith error
+	runner.Register("appointment.remind", func(ctx context.Context, job *domain.Job) error {
+		if failWith != nil {
+			return failWith
+		}
+		var payload reminderPayload
+		if err := job.DecodePayload(&payload); err != nil {
+			return Permanent(err)
+		}
+		tenant, _ := domain.TenantFromContext(ctx)
+		handled = append(handled, payload.AppointmentID+"@"+tenant.BusinessID)
+		return nil
+	})
+	assert.Equal(t, []string{"appointment.remind"}, runner.Kinds())
+
+	t.Run("runs due jobs within their tenant", func(t *testing.T) {
+		job, err := queue.Enqueue(ctx, "appointment.remind", reminderPayload{AppointmentID: "appointment-1"}, ForBusiness("business-1"))
+		require.NoError(t, err)
+		later, err := queue.Enqueue(ctx, "appointment.remind", reminderPayload{AppointmentID: "appointment-2"}, In(time.Hour))
+		require.NoError(t, err)
+
+		run, err := runner.Run(ctx, now)
+		require.NoError(t, err)
+		assert.Equal(t, &RunnerRun{Claimed: 1, Succeeded: 1}, run)
+		assert.Equal(t, []string{"appointment-1@business-1"}, handled)
+		assert.Equal(t, domain.JobStatusSucceeded, getJob(t, repo, job.ID).Status)
+
+		run, err = runner.Run(ctx, now.Add(time.Hour))
+		require.NoError(t, err)
+		assert.Equal(t, &RunnerRun{Claimed: 1, Succeeded: 1}, run, "scheduled jobs run once due")
+		assert.Equal(t, domain.JobStatusSucceeded, getJob(t, repo, later.ID).Status)
+	})
+
+	t.Run("retries failed jobs with backoff", func(t *testing.T) {
+		failWith = errors.New("provider unavailable")
+		job, err := queue.Enqueue(ctx, "appointment.remind", reminderPayload{AppointmentID: "appointment-3"})
+		require.NoError(t, err)
+
+		run, err := runner.Run(ctx, now)
+		require.NoError(t, err)
+		assert.Equal(t, &RunnerRun{Claimed: 1, Failed: 1}, run)
+		stored := getJob(t, repo, job.ID)
+		assert.Equal(t, domain.JobStatusPending, stored.Status)
+		assert.Equal(t, now.Add(domain.JobRetryDelay(1)), stored.RunAt)
+		require.NotNil(t, stored.LastError)
+		assert.Equal(t, "provider unavailable", *stored.LastError)
+
+		failWith = nil
+		run, err = runner.Run(ctx, now.Add(domain.JobRetryDelay(1)))
+		require.NoError(t, err)
+		assert.Equal(t, &RunnerRun{Claimed: 1, Succeeded: 1}, run)
+		assert.Equal(t, 2, getJob(t, repo, job.ID).Attempts)
+	})
+
+	t.Run("dead-letters jobs that keep failing", func(t *testing.T) {
+		failWith = errors.New("provider unavailable")
+		defer func() { failWith = nil }()
+		job, err := queue.Enqueue(ctx, "appointment.remind", nil, MaxAttempts(2))
+		require.NoError(t, err)
+
+		run, err := runner.Run(ctx, now)
+		require.NoError(t, err)
+		assert.Equal(t, &RunnerRun{Claimed: 1, Failed: 1}, run)
+		run, err = runner.Run(ctx, now.Add(domain.JobRetryDelay(1)))
+		require.NoError(t, err)
+		assert.Equal(t, &RunnerRun{Claimed: 1, Dead: 1}, run)
+		assert.Equal(t, domain.JobStatusDead, getJob(t, repo, job.ID).Status)
+
+		run, err = runner.Run(ctx, now.Add(24*time.Hour))
+		require.NoError(t, err)
+		assert.Zero(t, run.Claimed, "dead jobs are left to be retried by an operator")
+	})
+
+	t.Run("dead-letters permanent failures and unknown kinds right away", func(t *testing.T) {
+		invalid, err := queue.Enqueue(ctx, "appointment.remind", "not an object")
+		require.NoError(t, err)
+		unknown, err := queue.Enqueue(ctx, "report.generate", nil)
+		require.NoError(t, err)
+
+		run, err := runner.Run(ctx, now)
+		require.NoError(t, err)
+		assert.Equal(t, &RunnerRun{Claimed: 2, Dead: 2}, run)
+		assert.Equal(t, 1, getJob(t, repo, invalid.ID).Attempts)
+		assert.Contains(t, *getJob(t, repo, unknown.ID).LastError, `no handler for jobs of kind "report.generate"`)
+	})
+}
+
+func TestRunner_RecoversFromPanics(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, time.October, 17, 9, 0, 0, 0, time.UTC)
+	repo := memory.NewJobRepository(memory.NewDB())
+	runner := NewRunner(repo)
+	runner.Register("campaign.send", func(ctx context.Context, job *domain.Job) error { panic("nil campaign") })
+
+	job, err := newTestQueue(repo, runner, now).Enqueue(ctx, "campaign.send", nil)
+	require.NoError(t, err)
+
+	run, err := runner.Run(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, &RunnerRun{Claimed: 1, Failed: 1}, run)
+	assert.Equal(t, "job panicked: nil campaign", *getJob(t, repo, job.ID).LastError)
+}
+
+func TestQueue_UniqueKeys(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, time.October, 17, 9, 0, 0, 0, time.UTC)
+	repo := memory.NewJobRepository(memory.NewDB())
+	queue := newTestQueue(repo, nil, now)
+
+	first, err := queue.Enqueue(ctx, "deposit.expire", nil, WithKey("appointment-1"), At(now.Add(time.Hour)))
+	require.NoError(t, err)
+	again, err := queue.Enqueue(ctx, "deposit.expire", nil, WithKey("appointment-1"))
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, again.ID, "a pending job with the key is kept")
+	assert.Equal(t, now.Add(time.Hour), again.RunAt)
+
+	other, err := queue.Enqueue(ctx, "deposit.remind", nil, WithKey("appointment-1"))
+	require.NoError(t, err)
+	assert.NotEqual(t, first.ID, other.ID, "keys are unique within a kind")
+
+	cancelled, err := queue.Cancel(ctx, "deposit.expire", "appointment-1")
+	require.NoError(t, err)
+	assert.True(t, cancelled)
+	assert.Equal(t, domain.JobStatusCancelled, getJob(t, repo, first.ID).Status)
+
+	cancelled, err = queue.Cancel(ctx, "deposit.expire", "appointment-1")
+	require.NoError(t, err)
+	assert.False(t, cancelled)
+
+	replacement, err := queue.Enqueue(ctx, "deposit.expire", nil, WithKey("appointment-1"))
+	require.NoError(t, err)
+	assert.NotEqual(t, first.ID, replacement.ID, "a key is free again once its job is no longer pending")
+}
+
+func TestRunner_ClaimLeasesJobs(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, time.October, 17, 9, 0, 0, 0, time.UTC)
+	repo := memory.NewJobRepository(memory.NewDB())
+	job, err := newTestQueue(repo, nil, now).Enqueue(ctx, "campaign.send", nil)
+	require.NoError(t, err)
+
+	claimed, err := repo.ClaimDue(ctx, now, runnerLease, runnerBatchSize)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	assert.Equal(t, job.ID, claimed[0].ID)
+
+	claimed, err = repo.ClaimDue(ctx, now.Add(time.Minute), runnerLease, runnerBatchSize)
+	require.NoError(t, err)
+	assert.Empty(t, claimed, "another worker does not claim leased jobs")
+
+	claimed, err = repo.ClaimDue(ctx, now.Add(runnerLease), runnerLease, runnerBatchSize)
+	require.NoError(t, err)
+	assert.Len(t, claimed, 1, "jobs are claimed again once the lease runs out")
+}
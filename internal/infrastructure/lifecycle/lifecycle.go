@@ -0,0 +1,101 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// workKey is the context key of the context in-flight work runs with
+type workKey struct{}
+
+// Work returns the context a component runs its in-flight work with. Unlike ctx, which is done
+// as soon as the component is asked to stop, it is only done once the component's drain timeout
+// lapses, so work started before shutdown can finish. Outside a Manager it is ctx itself.
+func Work(ctx context.Context) context.Context {
+	if work, ok := ctx.Value(workKey{}).(context.Context); ok {
+		return work
+	}
+	return ctx
+}
+
+// component is a background component run by a Manager
+type component struct {
+	name    string
+	timeout time.Duration
+	stop    context.CancelFunc // Asks the component to stop once its in-flight work is done
+	abort   context.CancelFunc // Cancels its in-flight work
+	done    chan struct{}
+}
+
+// Manager runs the background components of a process and stops them in order on shutdown.
+// Components are stopped in the reverse of the order they were started in, so start the ones
+// consuming what others produce first: e.g. the outbox relay before the jobs recording events.
+type Manager struct {
+	mu         sync.Mutex
+	components []*component
+}
+
+// NewManager creates a manager with no components
+func NewManager() *Manager {
+	return &Manager{}
+}
+
+// Go runs a component until it is stopped. run must return once its context is done, finishing
+// the work in flight with Work(ctx), which is given at most timeout to drain.
+func (m *Manager) Go(name string, timeout time.Duration, run func(ctx context.Context)) {
+	work, abort := context.WithCancel(context.Background())
+	ctx, stop := context.WithCancel(context.WithValue(context.Background(), workKey{}, work))
+	c := &component{name: name, timeout: timeout, stop: stop, abort: abort, done: make(chan struct{})}
+
+	m.mu.Lock()
+	m.components = append(m.components, c)
+	m.mu.Unlock()
+
+	go func() {
+		defer close(c.done)
+		run(ctx)
+	}()
+}
+
+// Shutdown stops the components one at a time, latest started first. Each is given its own
+// timeout, and no more than ctx allows, to finish its in-flight work; one that does not is
+// aborted and left behind, and the next is stopped. It returns the components that were aborted.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.mu.Lock()
+	components := m.components
+	m.components = nil
+	m.mu.Unlock()
+
+	var errs []error
+	for i := len(components) - 1; i >= 0; i-- {
+		if err := components[i].shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// shutdown stops the component and waits for it to drain
+func (c *component) shutdown(ctx context.Context) error {
+	defer c.abort()
+	start := time.Now()
+	c.stop()
+
+	timer := time.NewTimer(c.timeout)
+	defer timer.Stop()
+	select {
+	case <-c.done:
+		log.Info().Str("component", c.name).Dur("took", time.Since(start)).Msg("Background component stopped")
+		return nil
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+
+	log.Warn().Str("component", c.name).Dur("timeout", c.timeout).Msg("Background component aborted before draining")
+	return fmt.Errorf("%s did not drain in time", c.name)
+}
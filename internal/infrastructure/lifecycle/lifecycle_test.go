@@ -0,0 +1,79 @@
+package lifecycle
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShutdownStopsComponentsInReverseOrder(t *testing.T) {
+	manager := NewManager()
+	var mu sync.Mutex
+	var stopped []string
+	for _, name := range []string{"relay", "jobs", "scheduler"} {
+		manager.Go(name, time.Second, func(ctx context.Context) {
+			<-ctx.Done()
+			mu.Lock()
+			stopped = append(stopped, name)
+			mu.Unlock()
+		})
+	}
+
+	err := manager.Shutdown(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"scheduler", "jobs", "relay"}, stopped)
+}
+
+func TestShutdownLetsInFlightWorkFinish(t *testing.T) {
+	manager := NewManager()
+	started := make(chan struct{})
+	var workErr error
+	manager.Go("jobs", time.Second, func(ctx context.Context) {
+		work := Work(ctx)
+		close(started)
+		<-ctx.Done()
+		// The work in flight finishes after the component is asked to stop
+		time.Sleep(10 * time.Millisecond)
+		workErr = work.Err()
+	})
+	<-started
+
+	err := manager.Shutdown(context.Background())
+
+	assert.NoError(t, err)
+	assert.NoError(t, workErr)
+}
+
+func TestShutdownAbortsComponentsPastTheirTimeout(t *testing.T) {
+	manager := NewManager()
+	aborted := make(chan struct{})
+	manager.Go("dispatcher", 10*time.Millisecond, func(ctx context.Context) {
+		<-Work(ctx).Done()
+		close(aborted)
+	})
+	var relayStopped bool
+	manager.Go("relay", time.Second, func(ctx context.Context) {
+		<-ctx.Done()
+		relayStopped = true
+	})
+
+	err := manager.Shutdown(context.Background())
+
+	assert.EqualError(t, err, "dispatcher did not drain in time")
+	assert.True(t, relayStopped)
+	select {
+	case <-aborted:
+	case <-time.After(time.Second):
+		t.Fatal("the work in flight was not cancelled")
+	}
+}
+
+func TestWorkOutsideAManager(t *testing.T) {
+	ctx := context.Background()
+
+	assert.Equal(t, ctx, Work(ctx))
+}
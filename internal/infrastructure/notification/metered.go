@@ -0,0 +1,46 @@
+package notification
+
+import (
+	"context"
+	"errors"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/infrastructure/resilience"
+	"github.com/rs/zerolog/log"
+)
+
+// CreditMeter counts the text messages of a business against the SMS credits of its plan
+type CreditMeter interface {
+	// UseSMSCredit takes a credit, returning an UpgradeRequiredError when none are left
+	UseSMSCredit(ctx context.Context, businessID string) error
+}
+
+// MeteredSender takes an SMS credit from the business of each text message before sending it
+// through another sender. Messages sent on behalf of no business, and emails, are not metered.
+type MeteredSender struct {
+	next  NotificationSender
+	meter CreditMeter
+}
+
+// NewMeteredSender creates a sender that stops a business's text messages once its plan's SMS
+// credits of the month are used up
+func NewMeteredSender(next NotificationSender, meter CreditMeter) *MeteredSender {
+	return &MeteredSender{next: next, meter: meter}
+}
+
+// Send takes a credit and delivers the message. Messages over the limit fail permanently, so
+// they are neither retried nor queued. When credits cannot be counted the message is sent
+// anyway, rather than letting a counting failure stop appointment reminders.
+func (s *MeteredSender) Send(ctx context.Context, message Message) error {
+	if message.Channel == domain.MessageChannelSMS && message.BusinessID != "" {
+		err := s.meter.UseSMSCredit(ctx, message.BusinessID)
+		var upgrade *domain.UpgradeRequiredError
+		if errors.As(err, &upgrade) {
+			return resilience.Permanent(err)
+		}
+		if err != nil {
+			log.Warn().Err(err).Str("business_id", message.BusinessID).Msg("Failed to count SMS credit, sending anyway")
+		}
+	}
+	return s.next.Send(ctx, message)
+}
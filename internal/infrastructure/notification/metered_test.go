@@ -0,0 +1,47 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/infrastructure/resilience"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingMeter allows a number of credits per business
+type countingMeter struct {
+	limit int
+	used  map[string]int
+}
+
+func (m *countingMeter) UseSMSCredit(ctx context.Context, businessID string) error {
+	if m.used[businessID] >= m.limit {
+		return &domain.UpgradeRequiredError{Entitlement: domain.EntitlementSMSCredits, Tier: domain.TierFree, Limit: m.limit, RequiredTier: domain.TierPro}
+	}
+	m.used[businessID]++
+	return nil
+}
+
+func TestMeteredSender_Send(t *testing.T) {
+	provider := &failingSender{}
+	meter := &countingMeter{limit: 1, used: map[string]int{}}
+	sender := NewMeteredSender(provider, meter)
+	ctx := context.Background()
+
+	sms := Message{BusinessID: "business-1", Channel: domain.MessageChannelSMS, Recipient: "+351912345678", Body: "See you tomorrow"}
+	require.NoError(t, sender.Send(ctx, sms))
+
+	err := sender.Send(ctx, sms)
+	var upgrade *domain.UpgradeRequiredError
+	require.True(t, errors.As(err, &upgrade))
+	assert.True(t, resilience.IsPermanent(err), "messages over the limit are not retried")
+
+	email := Message{BusinessID: "business-1", Channel: domain.MessageChannelEmail, Recipient: "ana@example.com", Body: "See you tomorrow"}
+	require.NoError(t, sender.Send(ctx, email), "emails are not metered")
+	require.NoError(t, sender.Send(ctx, Message{Channel: domain.MessageChannelSMS, Recipient: "+351912345678", Body: "Your code is 1234"}),
+		"messages of no business are not metered")
+	assert.Len(t, provider.sent, 3)
+}
@@ -0,0 +1,109 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/infrastructure/resilience"
+	"github.com/rs/zerolog/log"
+)
+
+// queueBatchSize bounds the queued notifications delivered in a single run
+const queueBatchSize = 100
+
+// DeliveryRun summarizes a run delivering queued notifications
+type DeliveryRun struct {
+	Due    int
+	Sent   int
+	Failed int
+}
+
+// QueueingSender sends messages through another sender and queues those that fail because the
+// provider is unavailable, so that they are delivered once it recovers
+type QueueingSender struct {
+	next  NotificationSender
+	queue domain.NotificationQueueRepository
+	now   func() time.Time
+}
+
+// NewQueueingSender creates a sender that queues messages its provider could not take
+func NewQueueingSender(next NotificationSender, queue domain.NotificationQueueRepository) *QueueingSender {
+	return &QueueingSender{next: next, queue: queue, now: time.Now}
+}
+
+// Send delivers the message. When the provider is unavailable the message is queued and Send
+// succeeds; messages the provider rejected are not queued and their error is returned.
+func (s *QueueingSender) Send(ctx context.Context, message Message) error {
+	err := s.next.Send(ctx, message)
+	if err == nil || !isTemporary(err) || ctx.Err() != nil {
+		return err
+	}
+
+	queued := &domain.QueuedNotification{
+		Channel:       message.Channel,
+		Recipient:     message.Recipient,
+		Subject:       message.Subject,
+		Body:          message.Body,
+		NextAttemptAt: s.now().Add(domain.NotificationRetryDelay(1)),
+	}
+	if message.BusinessID != "" {
+		queued.BusinessID = &message.BusinessID
+	}
+	lastError := err.Error()
+	queued.LastError = &lastError
+	if queueErr := s.queue.Create(ctx, queued); queueErr != nil {
+		log.Error().Err(queueErr).Str("channel", string(message.Channel)).Msg("Failed to queue notification")
+		return err
+	}
+	log.Warn().Err(err).Str("channel", string(message.Channel)).Str("queued_id", queued.ID).Msg("Notification provider unavailable, message queued")
+	return nil
+}
+
+// DeliverQueued sends the queued notifications that are due. Failures are rescheduled with
+// backoff and are not queued again.
+func (s *QueueingSender) DeliverQueued(ctx context.Context, now time.Time) (*DeliveryRun, error) {
+	notifications, err := s.queue.FindDue(ctx, now, queueBatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	run := &DeliveryRun{Due: len(notifications)}
+	for _, queued := range notifications {
+		message := Message{
+			Channel:   queued.Channel,
+			Recipient: queued.Recipient,
+			Subject:   queued.Subject,
+			Body:      queued.Body,
+		}
+		if queued.BusinessID != nil {
+			message.BusinessID = *queued.BusinessID
+		}
+
+		err := s.next.Send(ctx, message)
+		switch {
+		case err == nil:
+			queued.MarkSent(now)
+			run.Sent++
+		case errors.Is(err, resilience.ErrCircuitOpen):
+			// The provider is still down and was not called
+			queued.Postpone(now.Add(domain.NotificationRetryDelay(1)))
+		case !isTemporary(err):
+			queued.Abandon(err, now)
+			run.Failed++
+		default:
+			queued.RecordFailure(err, now)
+			run.Failed++
+		}
+		if err := s.queue.Update(ctx, queued); err != nil {
+			log.Error().Err(err).Str("queued_id", queued.ID).Msg("Failed to update queued notification")
+		}
+	}
+	return run, nil
+}
+
+// isTemporary reports whether a send may succeed later
+func isTemporary(err error) bool {
+	return !resilience.IsPermanent(err) && !errors.Is(err, ErrUnsupportedChannel)
+}
@@ -0,0 +1,97 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/infrastructure/resilience"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryQueue keeps queued notifications in memory
+type memoryQueue struct {
+	domain.NotificationQueueRepository
+	notifications []*domain.QueuedNotification
+}
+
+func (q *memoryQueue) Create(ctx context.Context, notification *domain.QueuedNotification) error {
+	notification.ID = fmt.Sprintf("queued-%d", len(q.notifications)+1)
+	q.notifications = append(q.notifications, notification)
+	return nil
+}
+
+func (q *memoryQueue) Update(ctx context.Context, notification *domain.QueuedNotification) error {
+	return nil
+}
+
+func (q *memoryQueue) FindDue(ctx context.Context, now time.Time, limit int) ([]*domain.QueuedNotification, error) {
+	var due []*domain.QueuedNotification
+	for _, notification := range q.notifications {
+		if notification.IsPending() && !notification.NextAttemptAt.After(now) {
+			due = append(due, notification)
+		}
+	}
+	return due, nil
+}
+
+// failingSender fails every send with err until it is cleared
+type failingSender struct {
+	err  error
+	sent []Message
+}
+
+func (s *failingSender) Send(ctx context.Context, message Message) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.sent = append(s.sent, message)
+	return nil
+}
+
+func TestQueueingSender_Send(t *testing.T) {
+	now := time.Date(2026, time.October, 17, 9, 0, 0, 0, time.UTC)
+	provider := &failingSender{err: errors.New("twilio request failed: connection refused")}
+	queue := &memoryQueue{}
+	sender := NewQueueingSender(provider, queue)
+	sender.now = func() time.Time { return now }
+
+	message := Message{BusinessID: "business-1", Channel: domain.MessageChannelSMS, Recipient: "+351912345678", Body: "See you tomorrow"}
+	require.NoError(t, sender.Send(context.Background(), message), "unavailable providers queue the message")
+	require.Len(t, queue.notifications, 1)
+	assert.Equal(t, "business-1", *queue.notifications[0].BusinessID)
+	assert.Equal(t, now.Add(time.Minute), queue.notifications[0].NextAttemptAt)
+
+	provider.err = resilience.Permanent(errors.New("invalid phone number"))
+	assert.Error(t, sender.Send(context.Background(), message), "rejected messages are not queued")
+	assert.Len(t, queue.notifications, 1)
+}
+
+func TestQueueingSender_DeliverQueued(t *testing.T) {
+	now := time.Date(2026, time.October, 17, 9, 0, 0, 0, time.UTC)
+	provider := &failingSender{}
+	queue := &memoryQueue{notifications: []*domain.QueuedNotification{
+		{Channel: domain.MessageChannelEmail, Recipient: "ana@example.com", Body: "Receipt", NextAttemptAt: now},
+		{Channel: domain.MessageChannelSMS, Recipient: "+351912345678", Body: "Reminder", NextAttemptAt: now.Add(time.Hour)},
+	}}
+	sender := NewQueueingSender(provider, queue)
+
+	provider.err = fmt.Errorf("%w: smtp", resilience.ErrCircuitOpen)
+	run, err := sender.DeliverQueued(context.Background(), now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, run.Due)
+	assert.Zero(t, queue.notifications[0].Attempts, "an open circuit does not use up attempts")
+	assert.True(t, queue.notifications[0].NextAttemptAt.After(now))
+
+	provider.err = nil
+	run, err = sender.DeliverQueued(context.Background(), now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 1, run.Sent)
+	assert.NotNil(t, queue.notifications[0].SentAt)
+	require.Len(t, provider.sent, 1)
+	assert.Equal(t, "ana@example.com", provider.sent[0].Recipient)
+}
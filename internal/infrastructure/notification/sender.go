@@ -0,0 +1,206 @@
+// Package notification delivers messages to clients and businesses through email, SMS and
+// WhatsApp providers
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/assimoes/beautix/configs"
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/infrastructure/outbound"
+	"github.com/assimoes/beautix/internal/infrastructure/resilience"
+	"github.com/rs/zerolog/log"
+)
+
+// Provider names accepted in the notification configuration
+const (
+	ProviderLog      = "log"
+	ProviderSMTP     = "smtp"
+	ProviderTwilio   = "twilio"
+	ProviderWhatsApp = "whatsapp"
+)
+
+// providerTimeout bounds a single request to an HTTP provider
+const providerTimeout = 15 * time.Second
+
+// providerPolicies protect the calls to each provider. Providers may have delivered a message
+// whose request timed out, so sends are retried once rather than risk duplicates.
+var providerPolicies = map[string]resilience.Policy{
+	ProviderSMTP: {
+		Timeout:          20 * time.Second,
+		MaxAttempts:      2,
+		BaseBackoff:      time.Second,
+		MaxBackoff:       2 * time.Second,
+		FailureThreshold: 5,
+		OpenDuration:     time.Minute,
+	},
+	ProviderTwilio: {
+		Timeout:          10 * time.Second,
+		MaxAttempts:      2,
+		BaseBackoff:      500 * time.Millisecond,
+		MaxBackoff:       time.Second,
+		FailureThreshold: 5,
+		OpenDuration:     time.Minute,
+	},
+	ProviderWhatsApp: {
+		Timeout:          10 * time.Second,
+		MaxAttempts:      2,
+		BaseBackoff:      500 * time.Millisecond,
+		MaxBackoff:       time.Second,
+		FailureThreshold: 5,
+		OpenDuration:     time.Minute,
+	},
+}
+
+// ErrUnsupportedChannel is returned when no provider delivers messages on a channel
+var ErrUnsupportedChannel = errors.New("no notification provider for channel")
+
+// Message is an email or text message to deliver
+type Message struct {
+	BusinessID string
+	Channel    domain.MessageChannel
+	Recipient  string  // Phone number or email address
+	Subject    *string // Email only
+	Body       string
+}
+
+// NotificationSender delivers messages through a provider
+type NotificationSender interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// Router delivers each message through the sender configured for its channel
+type Router struct {
+	senders map[domain.MessageChannel]NotificationSender
+}
+
+// NewRouter creates a sender that dispatches messages by channel
+func NewRouter(senders map[domain.MessageChannel]NotificationSender) *Router {
+	return &Router{senders: senders}
+}
+
+// Send delivers the message through the sender of its channel
+func (r *Router) Send(ctx context.Context, message Message) error {
+	sender, ok := r.senders[message.Channel]
+	if !ok {
+		return resilience.Permanent(fmt.Errorf("%w: %s", ErrUnsupportedChannel, message.Channel))
+	}
+	return sender.Send(ctx, message)
+}
+
+// NewSender creates the sender selected by the configuration: email goes through SMTP and SMS
+// through Twilio or WhatsApp, with either falling back to logging when no provider is set.
+// Calls to each provider are protected by its guard in the registry and audited by the recorder.
+func NewSender(cfg configs.NotificationConfig, providers *resilience.Registry, recorder *outbound.Recorder) (NotificationSender, error) {
+	var email NotificationSender
+	switch provider := strings.ToLower(cfg.EmailProvider); provider {
+	case "", ProviderLog:
+		email = NewLogSender()
+	case ProviderSMTP:
+		if cfg.SMTP.Host == "" || cfg.SMTP.From == "" {
+			return nil, errors.New("SMTP_HOST and SMTP_FROM are required for the smtp email provider")
+		}
+		email = guarded(NewSMTPSender(cfg.SMTP, recorder), providers, ProviderSMTP)
+	default:
+		return nil, fmt.Errorf("unknown email provider %q", provider)
+	}
+
+	var sms NotificationSender
+	switch provider := strings.ToLower(cfg.SMSProvider); provider {
+	case "", ProviderLog:
+		sms = NewLogSender()
+	case ProviderTwilio:
+		if cfg.Twilio.AccountSID == "" || cfg.Twilio.AuthToken == "" || cfg.Twilio.FromNumber == "" {
+			return nil, errors.New("TWILIO_ACCOUNT_SID, TWILIO_AUTH_TOKEN and TWILIO_FROM_NUMBER are required for the twilio SMS provider")
+		}
+		sms = guarded(NewTwilioSender(cfg.Twilio, recorder.Client(ProviderTwilio, providerTimeout)), providers, ProviderTwilio)
+	case ProviderWhatsApp:
+		if cfg.WhatsApp.PhoneNumberID == "" || cfg.WhatsApp.AccessToken == "" {
+			return nil, errors.New("WHATSAPP_PHONE_NUMBER_ID and WHATSAPP_ACCESS_TOKEN are required for the whatsapp SMS provider")
+		}
+		sms = guarded(NewWhatsAppSender(cfg.WhatsApp, recorder.Client(ProviderWhatsApp, providerTimeout)), providers, ProviderWhatsApp)
+	default:
+		return nil, fmt.Errorf("unknown SMS provider %q", provider)
+	}
+
+	return NewRouter(map[domain.MessageChannel]NotificationSender{
+		domain.MessageChannelEmail: email,
+		domain.MessageChannelSMS:   sms,
+	}), nil
+}
+
+// guardedSender sends messages through a provider's guard
+type guardedSender struct {
+	next  NotificationSender
+	guard *resilience.Guard
+}
+
+// guarded protects a provider's sender with its guard from the registry
+func guarded(sender NotificationSender, providers *resilience.Registry, provider string) NotificationSender {
+	return &guardedSender{next: sender, guard: providers.Guard(provider, providerPolicies[provider])}
+}
+
+// Send delivers the message, retrying temporary failures
+func (s *guardedSender) Send(ctx context.Context, message Message) error {
+	return s.guard.Do(ctx, func(ctx context.Context) error {
+		return s.next.Send(ctx, message)
+	})
+}
+
+// logSender logs messages instead of delivering them
+type logSender struct{}
+
+// NewLogSender creates a sender that only logs messages, for development and environments
+// without a provider configured
+func NewLogSender() NotificationSender {
+	return logSender{}
+}
+
+// Send logs the message
+func (logSender) Send(ctx context.Context, message Message) error {
+	log.Info().
+		Str("business_id", message.BusinessID).
+		Str("channel", string(message.Channel)).
+		Str("recipient", message.Recipient).
+		Str("body", message.Body).
+		Msg("Message not delivered: no provider configured")
+	return nil
+}
+
+// toE164 formats a phone number for providers that require international format. Numbers
+// written with a leading + or 00 keep their country code; others are sent as dialled.
+func toE164(phone string) string {
+	trimmed := strings.TrimSpace(phone)
+	digits := domain.NormalizePhone(trimmed)
+	switch {
+	case strings.HasPrefix(trimmed, "+"):
+		return "+" + digits
+	case strings.HasPrefix(digits, "00"):
+		return "+" + strings.TrimPrefix(digits, "00")
+	default:
+		return digits
+	}
+}
+
+// providerError describes a failed response from an HTTP provider. Responses that will not
+// change on retry, such as an invalid phone number, are marked permanent.
+func providerError(provider string, resp *http.Response, detail string) error {
+	if detail == "" {
+		detail = resp.Status
+	}
+	err := fmt.Errorf("%s rejected the message (status %d): %s", provider, resp.StatusCode, detail)
+	if !resilience.IsRetryableStatus(resp.StatusCode) {
+		return resilience.Permanent(err)
+	}
+	return err
+}
+
+// unsupportedChannel reports a message sent to a provider that cannot deliver its channel
+func unsupportedChannel(provider string, channel domain.MessageChannel) error {
+	return resilience.Permanent(fmt.Errorf("%w: %s cannot send %s", ErrUnsupportedChannel, provider, channel))
+}
@@ -0,0 +1,131 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/assimoes/beautix/configs"
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/infrastructure/resilience"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingSender struct {
+	messages []Message
+}
+
+func (s *recordingSender) Send(ctx context.Context, message Message) error {
+	s.messages = append(s.messages, message)
+	return nil
+}
+
+func TestRouter_Send(t *testing.T) {
+	email := &recordingSender{}
+	router := NewRouter(map[domain.MessageChannel]NotificationSender{domain.MessageChannelEmail: email})
+
+	require.NoError(t, router.Send(context.Background(), Message{Channel: domain.MessageChannelEmail, Recipient: "ana@example.com"}))
+	assert.Len(t, email.messages, 1)
+
+	err := router.Send(context.Background(), Message{Channel: domain.MessageChannelSMS, Recipient: "+351910000000"})
+	assert.ErrorIs(t, err, ErrUnsupportedChannel)
+}
+
+func TestNewSender(t *testing.T) {
+	sender, err := NewSender(configs.NotificationConfig{}, resilience.NewRegistry(), nil)
+	require.NoError(t, err, "providers default to logging")
+	assert.NoError(t, sender.Send(context.Background(), Message{Channel: domain.MessageChannelSMS, Body: "Hi"}))
+
+	_, err = NewSender(configs.NotificationConfig{EmailProvider: "pigeon"}, resilience.NewRegistry(), nil)
+	assert.Error(t, err)
+
+	_, err = NewSender(configs.NotificationConfig{SMSProvider: ProviderTwilio}, resilience.NewRegistry(), nil)
+	assert.Error(t, err, "twilio needs credentials")
+
+	_, err = NewSender(configs.NotificationConfig{
+		EmailProvider: ProviderSMTP,
+		SMTP:          configs.SMTPConfig{Host: "smtp.example.com", Port: "587", From: "no-reply@example.com"},
+		SMSProvider:   ProviderWhatsApp,
+		WhatsApp:      configs.WhatsAppConfig{APIURL: "https://graph.example.com", PhoneNumberID: "123", AccessToken: "token"},
+	}, resilience.NewRegistry(), nil)
+	assert.NoError(t, err)
+}
+
+func TestTwilioSender_Send(t *testing.T) {
+	var form map[string]string
+	var user, password string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/Accounts/AC123/Messages.json", r.URL.Path)
+		user, password, _ = r.BasicAuth()
+		require.NoError(t, r.ParseForm())
+		form = map[string]string{"To": r.PostForm.Get("To"), "From": r.PostForm.Get("From"), "Body": r.PostForm.Get("Body")}
+		if form["To"] == "+000" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"message": "The 'To' number is not a valid phone number."}`))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	sender := &twilioSender{
+		cfg:     configs.TwilioConfig{AccountSID: "AC123", AuthToken: "secret", FromNumber: "+15550001111"},
+		client:  server.Client(),
+		baseURL: server.URL,
+	}
+
+	err := sender.Send(context.Background(), Message{Channel: domain.MessageChannelSMS, Recipient: "+351 910 000 000", Body: "Reply YES"})
+	require.NoError(t, err)
+	assert.Equal(t, "AC123", user)
+	assert.Equal(t, "secret", password)
+	assert.Equal(t, map[string]string{"To": "+351910000000", "From": "+15550001111", "Body": "Reply YES"}, form)
+
+	err = sender.Send(context.Background(), Message{Channel: domain.MessageChannelSMS, Recipient: "+000", Body: "Hi"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not a valid phone number")
+
+	err = sender.Send(context.Background(), Message{Channel: domain.MessageChannelEmail, Recipient: "ana@example.com"})
+	assert.ErrorIs(t, err, ErrUnsupportedChannel)
+}
+
+func TestWhatsAppSender_Send(t *testing.T) {
+	var payload whatsAppTextMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/123/messages", r.URL.Path)
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sender := NewWhatsAppSender(configs.WhatsAppConfig{APIURL: server.URL + "/", PhoneNumberID: "123", AccessToken: "token"}, server.Client())
+
+	err := sender.Send(context.Background(), Message{Channel: domain.MessageChannelSMS, Recipient: "00351 910 000 000", Body: "See you tomorrow"})
+	require.NoError(t, err)
+	assert.Equal(t, "whatsapp", payload.MessagingProduct)
+	assert.Equal(t, "351910000000", payload.To)
+	assert.Equal(t, "See you tomorrow", payload.Text.Body)
+}
+
+func TestBuildEmail(t *testing.T) {
+	subject := "Confirme a sua marcação\r\nBcc: someone@example.com"
+	message := Message{Channel: domain.MessageChannelEmail, Subject: &subject, Body: "Olá Ana, até amanhã!"}
+	from := &mail.Address{Name: "Salão", Address: "no-reply@example.com"}
+	to := &mail.Address{Address: "ana@example.com"}
+
+	data, err := buildEmail(from, to, message, time.Date(2026, time.October, 17, 9, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	headers, body, found := strings.Cut(string(data), "\r\n\r\n")
+	require.True(t, found)
+	assert.NotContains(t, headers, "\r\nBcc:", "a subject cannot inject headers")
+	assert.Contains(t, headers, "To: <ana@example.com>")
+	assert.Contains(t, headers, "Content-Type: text/plain; charset=UTF-8")
+	assert.Contains(t, body, "Ol=C3=A1 Ana")
+}
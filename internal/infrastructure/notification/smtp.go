@@ -0,0 +1,164 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/assimoes/beautix/configs"
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/infrastructure/outbound"
+	"github.com/assimoes/beautix/internal/infrastructure/resilience"
+)
+
+// smtpSender sends email through an SMTP server, upgrading to TLS when the server offers it
+type smtpSender struct {
+	cfg      configs.SMTPConfig
+	recorder *outbound.Recorder
+}
+
+// NewSMTPSender creates a sender that delivers email through an SMTP server, audited by the
+// recorder
+func NewSMTPSender(cfg configs.SMTPConfig, recorder *outbound.Recorder) NotificationSender {
+	return &smtpSender{cfg: cfg, recorder: recorder}
+}
+
+// Send delivers an email and records the exchange with the server. The email itself is
+// personal data, so no body is recorded.
+func (s *smtpSender) Send(ctx context.Context, message Message) error {
+	start := time.Now()
+	err := s.send(ctx, message)
+
+	record := &domain.OutboundRequest{
+		Provider:  ProviderSMTP,
+		Method:    "SEND",
+		Endpoint:  "smtp://" + net.JoinHostPort(s.cfg.Host, s.cfg.Port),
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+	var reply *textproto.Error
+	switch {
+	case err == nil:
+		code := 250
+		record.StatusCode = &code
+	case errors.As(err, &reply):
+		record.StatusCode = &reply.Code
+	}
+	if err != nil {
+		failure := err.Error()
+		record.Error = &failure
+	}
+	s.recorder.Record(ctx, record)
+	return err
+}
+
+// send delivers an email
+func (s *smtpSender) send(ctx context.Context, message Message) error {
+	if message.Channel != domain.MessageChannelEmail {
+		return unsupportedChannel("smtp", message.Channel)
+	}
+	from, err := mail.ParseAddress(s.cfg.From)
+	if err != nil {
+		return resilience.Permanent(fmt.Errorf("invalid SMTP_FROM address: %w", err))
+	}
+	to, err := mail.ParseAddress(message.Recipient)
+	if err != nil {
+		return resilience.Permanent(fmt.Errorf("invalid recipient address: %w", err))
+	}
+	data, err := buildEmail(from, to, message, time.Now())
+	if err != nil {
+		return err
+	}
+
+	dialer := net.Dialer{Timeout: providerTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(s.cfg.Host, s.cfg.Port))
+	if err != nil {
+		return fmt.Errorf("smtp connection failed: %w", err)
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(providerTimeout)
+	}
+	_ = conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp handshake failed: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.cfg.Host}); err != nil {
+			return fmt.Errorf("smtp STARTTLS failed: %w", err)
+		}
+	}
+	if s.cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)); err != nil {
+			return smtpError(fmt.Errorf("smtp authentication failed: %w", err))
+		}
+	}
+	if err := client.Mail(from.Address); err != nil {
+		return smtpError(err)
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return smtpError(err)
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return smtpError(err)
+	}
+	if _, err := writer.Write(data); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return smtpError(err)
+	}
+	return client.Quit()
+}
+
+// smtpError marks permanent SMTP failures (5xx replies such as an unknown mailbox) so they are
+// not retried; 4xx replies are temporary by definition
+func smtpError(err error) error {
+	var reply *textproto.Error
+	if errors.As(err, &reply) && reply.Code >= 500 {
+		return resilience.Permanent(err)
+	}
+	return err
+}
+
+// buildEmail renders a plain-text UTF-8 email
+func buildEmail(from, to *mail.Address, message Message, date time.Time) ([]byte, error) {
+	subject := ""
+	if message.Subject != nil {
+		// Header values cannot span lines
+		subject = strings.Join(strings.Fields(*message.Subject), " ")
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", to.String())
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	body := quotedprintable.NewWriter(&buf)
+	if _, err := body.Write([]byte(message.Body)); err != nil {
+		return nil, err
+	}
+	if err := body.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
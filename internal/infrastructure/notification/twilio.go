@@ -0,0 +1,64 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/assimoes/beautix/configs"
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// twilioAPIURL is the base URL of the Twilio REST API
+const twilioAPIURL = "https://api.twilio.com/2010-04-01"
+
+// twilioSender sends SMS through the Twilio Messages API
+type twilioSender struct {
+	cfg     configs.TwilioConfig
+	client  *http.Client
+	baseURL string
+}
+
+// NewTwilioSender creates a sender that delivers SMS through Twilio
+func NewTwilioSender(cfg configs.TwilioConfig, client *http.Client) NotificationSender {
+	return &twilioSender{cfg: cfg, client: client, baseURL: twilioAPIURL}
+}
+
+// Send delivers an SMS
+func (s *twilioSender) Send(ctx context.Context, message Message) error {
+	if message.Channel != domain.MessageChannelSMS {
+		return unsupportedChannel("twilio", message.Channel)
+	}
+
+	form := url.Values{}
+	form.Set("To", toE164(message.Recipient))
+	form.Set("From", s.cfg.FromNumber)
+	form.Set("Body", message.Body)
+
+	endpoint := s.baseURL + "/Accounts/" + url.PathEscape(s.cfg.AccountSID) + "/Messages.json"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(s.cfg.AccountSID, s.cfg.AuthToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("twilio request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var failure struct {
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&failure)
+		return providerError("twilio", resp, failure.Message)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/assimoes/beautix/configs"
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// whatsAppSender sends text messages through the WhatsApp Business Cloud API. WhatsApp only
+// delivers free-form text within 24 hours of the client's last message, so numbers used for
+// reminders need their messages approved as templates with Meta.
+type whatsAppSender struct {
+	cfg    configs.WhatsAppConfig
+	client *http.Client
+}
+
+// NewWhatsAppSender creates a sender that delivers text messages through WhatsApp
+func NewWhatsAppSender(cfg configs.WhatsAppConfig, client *http.Client) NotificationSender {
+	return &whatsAppSender{cfg: cfg, client: client}
+}
+
+// whatsAppTextMessage is the request body of a WhatsApp text message
+type whatsAppTextMessage struct {
+	MessagingProduct string `json:"messaging_product"`
+	To               string `json:"to"`
+	Type             string `json:"type"`
+	Text             struct {
+		Body string `json:"body"`
+	} `json:"text"`
+}
+
+// Send delivers a text message
+func (s *whatsAppSender) Send(ctx context.Context, message Message) error {
+	if message.Channel != domain.MessageChannelSMS {
+		return unsupportedChannel("whatsapp", message.Channel)
+	}
+
+	payload := whatsAppTextMessage{
+		MessagingProduct: "whatsapp",
+		// The Cloud API takes the number in international format without the +
+		To:   strings.TrimPrefix(toE164(message.Recipient), "+"),
+		Type: "text",
+	}
+	payload.Text.Body = message.Body
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	endpoint := strings.TrimRight(s.cfg.APIURL, "/") + "/" + url.PathEscape(s.cfg.PhoneNumberID) + "/messages"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.cfg.AccessToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("whatsapp request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var failure struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&failure)
+		return providerError("whatsapp", resp, failure.Error.Message)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+// Package outbound audits the calls made to third-party providers, keeping their latency,
+// status and bodies scrubbed of personal data for debugging
+package outbound
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/rs/zerolog/log"
+)
+
+// recordTimeout bounds storing a single outbound request
+const recordTimeout = 2 * time.Second
+
+// Recorder stores the outbound requests made to providers. A nil Recorder records nothing.
+type Recorder struct {
+	repo domain.OutboundRequestRepository
+	now  func() time.Time
+}
+
+// NewRecorder creates a recorder storing outbound requests in the repository
+func NewRecorder(repo domain.OutboundRequestRepository) *Recorder {
+	return &Recorder{repo: repo, now: time.Now}
+}
+
+// Client returns an HTTP client whose requests are recorded under the provider
+func (r *Recorder) Client(provider string, timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: timeout, Transport: r.Transport(provider, http.DefaultTransport)}
+}
+
+// Transport wraps an HTTP transport so that its requests are recorded under the provider
+func (r *Recorder) Transport(provider string, next http.RoundTripper) http.RoundTripper {
+	if r == nil {
+		return next
+	}
+	return &transport{recorder: r, provider: provider, next: next}
+}
+
+// Record stores an outbound request. Failing to store it is logged and does not affect the call.
+func (r *Recorder) Record(ctx context.Context, request *domain.OutboundRequest) {
+	if r == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), recordTimeout)
+	defer cancel()
+	if err := r.repo.Create(ctx, request); err != nil {
+		log.Warn().Err(err).Str("provider", request.Provider).Str("endpoint", request.Endpoint).Msg("Failed to record outbound request")
+	}
+}
+
+// transport records the requests it sends
+type transport struct {
+	recorder *Recorder
+	provider string
+	next     http.RoundTripper
+}
+
+// RoundTrip sends the request and records it along with the response
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var requestBody []byte
+	if req.Body != nil && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			requestBody, _ = io.ReadAll(body)
+			body.Close()
+		}
+	}
+
+	endpoint := *req.URL
+	endpoint.RawQuery = ""
+	endpoint.User = nil
+	record := &domain.OutboundRequest{
+		Provider:    t.provider,
+		Method:      req.Method,
+		Endpoint:    truncate(endpoint.String(), 500),
+		RequestBody: optionalBody(Scrub(req.Header.Get("Content-Type"), requestBody)),
+	}
+
+	start := t.recorder.now()
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		record.LatencyMs = t.recorder.now().Sub(start).Milliseconds()
+		message := err.Error()
+		record.Error = &message
+		t.recorder.Record(req.Context(), record)
+		return nil, err
+	}
+
+	// The body is buffered so that it can be recorded and still read by the caller
+	responseBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	record.LatencyMs = t.recorder.now().Sub(start).Milliseconds()
+	record.StatusCode = &resp.StatusCode
+	record.ResponseBody = optionalBody(Scrub(resp.Header.Get("Content-Type"), responseBody))
+	if err != nil {
+		message := err.Error()
+		record.Error = &message
+		t.recorder.Record(req.Context(), record)
+		return nil, err
+	}
+	t.recorder.Record(req.Context(), record)
+	resp.Body = io.NopCloser(bytes.NewReader(responseBody))
+	return resp, nil
+}
+
+// optionalBody returns nil for an empty body
+func optionalBody(body string) *string {
+	if body == "" {
+		return nil
+	}
+	return &body
+}
+
+// truncate shortens a value to at most n bytes
+func truncate(value string, n int) string {
+	if len(value) <= n {
+		return value
+	}
+	return value[:n]
+}
@@ -0,0 +1,131 @@
+package outbound
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryRepository keeps recorded requests in memory
+type memoryRepository struct {
+	domain.OutboundRequestRepository
+	requests []*domain.OutboundRequest
+}
+
+func (r *memoryRepository) Create(ctx context.Context, request *domain.OutboundRequest) error {
+	r.requests = append(r.requests, request)
+	return nil
+}
+
+func TestScrub_Form(t *testing.T) {
+	form := url.Values{}
+	form.Set("To", "+351912345678")
+	form.Set("Body", "Hi Ana, see you tomorrow")
+	form.Set("metadata[client_email]", "ana@example.com")
+	form.Set("metadata[appointment_id]", "appointment-1")
+	form.Set("description", "Deposit from ana@example.com, call 912 345 678")
+	form.Set("amount", "2500")
+
+	scrubbed := Scrub("application/x-www-form-urlencoded", []byte(form.Encode()))
+	assert.NotContains(t, scrubbed, "912")
+	assert.NotContains(t, scrubbed, "ana@example.com")
+	assert.NotContains(t, scrubbed, "Hi Ana")
+	assert.Contains(t, scrubbed, "metadata[appointment_id]=appointment-1")
+	assert.Contains(t, scrubbed, "amount=2500")
+}
+
+func TestScrub_JSON(t *testing.T) {
+	body := `{"id": "pi_3N1234567890123", "to": "351912345678", "text": {"body": "Hello"}, ` +
+		`"created": "2026-10-17", "billing": {"email": "ana@example.com"}, "note": "card 4242 4242 4242 4242"}`
+
+	scrubbed := Scrub("application/json", []byte(body))
+	assert.Contains(t, scrubbed, "pi_3N1234567890123", "identifiers are kept")
+	assert.Contains(t, scrubbed, "2026-10-17", "dates are kept")
+	assert.NotContains(t, scrubbed, "351912345678")
+	assert.NotContains(t, scrubbed, "Hello")
+	assert.NotContains(t, scrubbed, "ana@example.com")
+	assert.NotContains(t, scrubbed, "4242")
+}
+
+func TestScrub_CamelCaseKeys(t *testing.T) {
+	body := `{"input": {"clientId": "client-1", "firstName": "Ana", "clientEmail": "ana@example.com", "notes": "Allergic to latex"}}`
+
+	scrubbed := Scrub("application/json", []byte(body))
+	assert.Contains(t, scrubbed, "client-1")
+	assert.NotContains(t, scrubbed, "Ana")
+	assert.NotContains(t, scrubbed, "latex")
+	assert.NotContains(t, scrubbed, "ana@example.com")
+}
+
+func TestScrub_Truncates(t *testing.T) {
+	scrubbed := Scrub("text/plain", []byte(strings.Repeat("a", 2*maxBodyLength)))
+	assert.LessOrEqual(t, len(scrubbed), maxBodyLength+len("…"))
+}
+
+func TestScrub_DropsBinaryBodies(t *testing.T) {
+	assert.Empty(t, Scrub("image/jpeg", []byte("\xff\xd8\xff\xe0 photo")))
+	assert.Empty(t, Scrub("application/pdf", []byte("%PDF-1.4")))
+}
+
+func TestRecorder_Transport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"sid": "SM123", "to": "+351912345678"}`))
+	}))
+	defer server.Close()
+
+	repo := &memoryRepository{}
+	client := NewRecorder(repo).Client("twilio", time.Second)
+
+	form := url.Values{"To": {"+351912345678"}, "Body": {"Hello"}}
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/Messages.json?token=secret", strings.NewReader(form.Encode()))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Contains(t, string(body), "+351912345678", "the caller still reads the response")
+
+	require.Len(t, repo.requests, 1)
+	recorded := repo.requests[0]
+	assert.Equal(t, "twilio", recorded.Provider)
+	assert.Equal(t, http.MethodPost, recorded.Method)
+	assert.Equal(t, server.URL+"/Messages.json", recorded.Endpoint, "the query string is dropped")
+	require.NotNil(t, recorded.StatusCode)
+	assert.Equal(t, http.StatusCreated, *recorded.StatusCode)
+	assert.False(t, recorded.Failed())
+	require.NotNil(t, recorded.RequestBody)
+	assert.NotContains(t, *recorded.RequestBody, "912345678")
+	require.NotNil(t, recorded.ResponseBody)
+	assert.NotContains(t, *recorded.ResponseBody, "912345678")
+}
+
+func TestRecorder_TransportError(t *testing.T) {
+	repo := &memoryRepository{}
+	client := NewRecorder(repo).Client("stripe", time.Second)
+
+	_, err := client.Get("http://127.0.0.1:1/payment_intents")
+	require.Error(t, err)
+	require.Len(t, repo.requests, 1)
+	assert.Nil(t, repo.requests[0].StatusCode)
+	assert.NotNil(t, repo.requests[0].Error)
+	assert.True(t, repo.requests[0].Failed())
+}
+
+func TestNilRecorder(t *testing.T) {
+	var recorder *Recorder
+	assert.Equal(t, http.DefaultTransport, recorder.Transport("stripe", http.DefaultTransport))
+	recorder.Record(context.Background(), &domain.OutboundRequest{})
+}
@@ -0,0 +1,202 @@
+package outbound
+
+import (
+	"encoding/json"
+	"mime"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// maxBodyLength bounds the stored length of a scrubbed body
+const maxBodyLength = 4096
+
+// redacted replaces personal data
+const redacted = "[REDACTED]"
+
+// sensitiveKeys are the fields whose values are always redacted, matched on the innermost key in
+// snake case, so "metadata[client_email]" and "clientEmail" both match "client_email"
+var sensitiveKeys = map[string]bool{
+	"to": true, "from": true, "body": true, "text": true, "recipient": true,
+	"name": true, "first_name": true, "last_name": true, "client_name": true,
+	"email": true, "client_email": true, "phone": true, "client_phone": true, "address": true,
+	"client_secret": true, "password": true, "passphrase": true, "token": true, "access_token": true, "secret": true,
+	"card": true, "number": true, "cvc": true, "exp_month": true, "exp_year": true,
+	"notes": true, "date_of_birth": true,
+}
+
+// Patterns of personal data scrubbed from the remaining values. Runs of digits cover both
+// phone and card numbers.
+var (
+	emailPattern  = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	numberPattern = regexp.MustCompile(`\+?\d[\d ()\-.]{7,}\d`)
+)
+
+// minNumberDigits is the fewest digits of a masked number, so that dates and amounts are kept
+const minNumberDigits = 9
+
+// Scrub removes personal data from a request or response body: the values of sensitive fields
+// of JSON and form bodies are redacted, and emails, card numbers and phone numbers are masked
+// anywhere else. Binary bodies, such as images, are not kept. The result is truncated to a few
+// kilobytes.
+func Scrub(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if isBinary(mediaType) {
+		return ""
+	}
+	var scrubbed string
+	switch {
+	case mediaType == "application/x-www-form-urlencoded":
+		scrubbed = scrubForm(string(body))
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") || json.Valid(body):
+		scrubbed = scrubJSON(body)
+	default:
+		scrubbed = scrubText(string(body))
+	}
+
+	if len(scrubbed) > maxBodyLength {
+		scrubbed = scrubbed[:maxBodyLength] + "…"
+	}
+	return scrubbed
+}
+
+// isBinary reports whether bodies of a media type are files rather than text
+func isBinary(mediaType string) bool {
+	for _, prefix := range []string{"image/", "audio/", "video/", "font/"} {
+		if strings.HasPrefix(mediaType, prefix) {
+			return true
+		}
+	}
+	switch mediaType {
+	case "application/octet-stream", "application/pdf", "application/zip", "application/gzip":
+		return true
+	}
+	return false
+}
+
+// scrubJSON redacts the sensitive fields of a JSON document
+func scrubJSON(body []byte) string {
+	var document any
+	if err := json.Unmarshal(body, &document); err != nil {
+		return scrubText(string(body))
+	}
+	scrubbed, err := json.Marshal(scrubValue(document))
+	if err != nil {
+		return redacted
+	}
+	return string(scrubbed)
+}
+
+// scrubValue redacts the sensitive fields of a decoded JSON value
+func scrubValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, field := range v {
+			if isSensitiveKey(key) && field != nil {
+				v[key] = redacted
+				continue
+			}
+			v[key] = scrubValue(field)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = scrubValue(item)
+		}
+		return v
+	case string:
+		return scrubText(v)
+	default:
+		return v
+	}
+}
+
+// scrubForm redacts the sensitive fields of a form-encoded body
+func scrubForm(body string) string {
+	values, err := url.ParseQuery(body)
+	if err != nil {
+		return scrubText(body)
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fields := make([]string, 0, len(keys))
+	for _, key := range keys {
+		for _, value := range values[key] {
+			if isSensitiveKey(key) {
+				value = redacted
+			} else {
+				value = scrubText(value)
+			}
+			fields = append(fields, key+"="+value)
+		}
+	}
+	return strings.Join(fields, "&")
+}
+
+// scrubText masks emails, card numbers and phone numbers
+func scrubText(text string) string {
+	text = emailPattern.ReplaceAllString(text, redacted)
+
+	var scrubbed strings.Builder
+	last := 0
+	for _, match := range numberPattern.FindAllStringIndex(text, -1) {
+		start, end := match[0], match[1]
+		// Digits that are part of an identifier, such as pi_3N1234567890, are kept
+		if start > 0 && isWordByte(text[start-1]) || end < len(text) && isWordByte(text[end]) {
+			continue
+		}
+		digits := 0
+		for _, c := range text[start:end] {
+			if c >= '0' && c <= '9' {
+				digits++
+			}
+		}
+		if digits < minNumberDigits {
+			continue
+		}
+		scrubbed.WriteString(text[last:start])
+		scrubbed.WriteString(redacted)
+		last = end
+	}
+	scrubbed.WriteString(text[last:])
+	return scrubbed.String()
+}
+
+// isWordByte reports whether a byte is a letter, digit or underscore
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// isSensitiveKey reports whether the values of a field are always redacted
+func isSensitiveKey(key string) bool {
+	key = strings.TrimSuffix(key, "]")
+	if i := strings.LastIndex(key, "["); i >= 0 {
+		key = key[i+1:]
+	}
+	return sensitiveKeys[snakeCase(key)]
+}
+
+// snakeCase converts a camel case key such as "clientEmail" to snake case, and lowercases the rest
+func snakeCase(key string) string {
+	var b strings.Builder
+	for i, c := range key {
+		if c >= 'A' && c <= 'Z' {
+			if i > 0 && (key[i-1] >= 'a' && key[i-1] <= 'z' || key[i-1] >= '0' && key[i-1] <= '9') {
+				b.WriteByte('_')
+			}
+			c += 'a' - 'A'
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
@@ -0,0 +1,90 @@
+package probe
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// metricsNamespace prefixes the name of every metric, as the API's own metrics
+const metricsNamespace = "beautix"
+
+// registry holds the metrics of the prober, kept apart from the API's so a sidecar exports only
+// what it measured
+var registry = prometheus.NewRegistry()
+
+var (
+	flowSuccess = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "probe",
+		Name:      "success",
+		Help:      "Whether the last run of a flow succeeded (1) or not (0); skipped flows count as failed",
+	}, []string{"flow"})
+
+	flowDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "probe",
+		Name:      "duration_seconds",
+		Help:      "Latency of the flows that ran, by flow and outcome",
+		Buckets:   []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+	}, []string{"flow", "outcome"})
+
+	flowRuns = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "probe",
+		Name:      "runs_total",
+		Help:      "Runs of the flows by flow and outcome: ok, error or skipped when a flow it depends on failed",
+	}, []string{"flow", "outcome"})
+
+	lastSuccess = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "probe",
+		Name:      "last_success_timestamp_seconds",
+		Help:      "When a flow last succeeded, as a Unix time",
+	}, []string{"flow"})
+
+	cleanupFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "probe",
+		Name:      "cleanup_failures_total",
+		Help:      "Refunds and cancellations of what a run booked that failed, leaving it in the sandbox business",
+	}, []string{"step"})
+)
+
+func init() {
+	registry.MustRegister(flowSuccess, flowDuration, flowRuns, lastSuccess, cleanupFailures)
+	for _, flow := range Flows {
+		flowSuccess.WithLabelValues(flow)
+	}
+}
+
+// MetricsHandler serves the metrics of the prober in the Prometheus exposition format
+func MetricsHandler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// Gatherer returns the metrics of the prober, for pushing them to a Pushgateway
+func Gatherer() prometheus.Gatherer {
+	return registry
+}
+
+// observe records the outcome of a flow run at a time
+func observe(result Result, at time.Time) {
+	switch {
+	case errors.Is(result.Err, ErrSkipped):
+		flowSuccess.WithLabelValues(result.Flow).Set(0)
+		flowRuns.WithLabelValues(result.Flow, "skipped").Inc()
+	case result.Err != nil:
+		flowSuccess.WithLabelValues(result.Flow).Set(0)
+		flowRuns.WithLabelValues(result.Flow, "error").Inc()
+		flowDuration.WithLabelValues(result.Flow, "error").Observe(result.Duration.Seconds())
+	default:
+		flowSuccess.WithLabelValues(result.Flow).Set(1)
+		flowRuns.WithLabelValues(result.Flow, "ok").Inc()
+		flowDuration.WithLabelValues(result.Flow, "ok").Observe(result.Duration.Seconds())
+		lastSuccess.WithLabelValues(result.Flow).Set(float64(at.Unix()))
+	}
+}
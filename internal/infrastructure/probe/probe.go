@@ -0,0 +1,426 @@
+// Package probe exercises the booking funnel of a running API end-to-end, the way a client
+// would: the probe user logs in, availability is looked up, an appointment is booked and its
+// deposit paid. Every run books in a sandbox business and pays with a Stripe test card, then
+// refunds the deposit and cancels the appointment, so probing leaves nothing to clean up by hand.
+// The outcome and latency of each flow are exported as metrics, so outages are alerted on before
+// customers notice them.
+package probe
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/infrastructure/telemetry"
+)
+
+// Flows of the booking funnel, in the order they run
+const (
+	FlowLogin        = "login"        // The probe user's token resolves to the user
+	FlowAvailability = "availability" // A bookable service has a free slot
+	FlowBooking      = "booking"      // The slot is booked online
+	FlowPayment      = "payment"      // The deposit of the booking is charged to a test card
+)
+
+// Flows lists every flow, in the order they run
+var Flows = []string{FlowLogin, FlowAvailability, FlowBooking, FlowPayment}
+
+const (
+	// DefaultPaymentMethodID is a Stripe test card that always succeeds. It exists in test mode
+	// only, so against a live Stripe account the payment flow fails rather than charging a card.
+	DefaultPaymentMethodID = "pm_card_visa"
+	// DefaultDepositAmount is charged, and refunded, by the payment flow
+	DefaultDepositAmount = "1.00"
+	// DefaultLookAheadDays bounds the days searched for a free slot
+	DefaultLookAheadDays = 14
+	// DefaultClientEmail books as the probe client
+	DefaultClientEmail = "synthetic-probe@example.com"
+	// DefaultTimeout bounds a whole run
+	DefaultTimeout = 30 * time.Second
+
+	// userAgent identifies probe requests in the access logs
+	userAgent = "Beautix-Prober/1.0"
+)
+
+// ErrSkipped is the error of a flow not run because a flow it depends on failed
+var ErrSkipped = errors.New("skipped: a flow it depends on failed")
+
+// Config configures a prober
+type Config struct {
+	PrivateURL      string        // The authenticated GraphQL endpoint, e.g. http://localhost:8090/graphql
+	PublicURL       string        // The online booking GraphQL endpoint
+	Token           string        // A session token of the probe user, a manager of the sandbox business
+	BusinessID      string        // The sandbox business booked in
+	ServiceID       string        // The service booked; the first bookable one requiring no history when empty
+	PaymentMethodID string        // The Stripe test PaymentMethod the deposit is charged to
+	DepositAmount   string        // The deposit charged
+	LookAheadDays   int           // The days searched for a free slot, starting tomorrow
+	ClientEmail     string        // The email address of the client the probe books as
+	Timeout         time.Duration // Bounds a whole run
+}
+
+// withDefaults fills in the defaults of the fields left empty
+func (c Config) withDefaults() Config {
+	if c.PaymentMethodID == "" {
+		c.PaymentMethodID = DefaultPaymentMethodID
+	}
+	if c.DepositAmount == "" {
+		c.DepositAmount = DefaultDepositAmount
+	}
+	if c.LookAheadDays <= 0 {
+		c.LookAheadDays = DefaultLookAheadDays
+	}
+	if c.ClientEmail == "" {
+		c.ClientEmail = DefaultClientEmail
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = DefaultTimeout
+	}
+	return c
+}
+
+// Validate checks that the config names the endpoints, the probe user and the sandbox business
+func (c Config) Validate() error {
+	switch {
+	case c.PrivateURL == "" || c.PublicURL == "":
+		return errors.New("the private and public GraphQL endpoints are required")
+	case c.Token == "":
+		return errors.New("a session token of the probe user is required")
+	case c.BusinessID == "":
+		return errors.New("the ID of the sandbox business is required")
+	}
+	return nil
+}
+
+// Result is the outcome of a flow in a run
+type Result struct {
+	Flow     string
+	Duration time.Duration
+	Err      error // ErrSkipped when a flow it depends on failed
+}
+
+// Failed reports whether the flow ran and failed
+func (r Result) Failed() bool {
+	return r.Err != nil && !errors.Is(r.Err, ErrSkipped)
+}
+
+// Prober runs the flows of the booking funnel against an API
+type Prober struct {
+	config Config
+	client *http.Client
+	now    func() time.Time
+}
+
+// New creates a prober sending its requests with the client
+func New(config Config, client *http.Client) (*Prober, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Prober{config: config.withDefaults(), client: client, now: time.Now}, nil
+}
+
+// run carries what the flows of a run found for the flows after them
+type run struct {
+	requestID   string
+	loggedIn    bool
+	serviceID   string
+	slot        *slot
+	appointment string
+	payment     string
+}
+
+// slot is a free slot found by the availability flow
+type slot struct {
+	StaffID   string    `json:"staffId"`
+	StartTime time.Time `json:"startTime"`
+}
+
+// Run runs every flow once, records their outcome in the metrics and returns them in the order
+// of Flows. Whatever was booked or paid is refunded and cancelled before it returns.
+func (p *Prober) Run(ctx context.Context) []Result {
+	ctx, cancel := context.WithTimeout(ctx, p.config.Timeout)
+	defer cancel()
+
+	r := &run{requestID: "probe-" + domain.NewID()}
+	results := []Result{
+		p.flow(ctx, FlowLogin, true, func(ctx context.Context) error { return p.login(ctx, r) }),
+	}
+	availability := p.flow(ctx, FlowAvailability, true, func(ctx context.Context) error { return p.availability(ctx, r) })
+	booking := p.flow(ctx, FlowBooking, availability.Err == nil, func(ctx context.Context) error { return p.booking(ctx, r) })
+	payment := p.flow(ctx, FlowPayment, booking.Err == nil && r.loggedIn, func(ctx context.Context) error { return p.payment(ctx, r) })
+	results = append(results, availability, booking, payment)
+
+	p.cleanUp(ctx, r)
+	for _, result := range results {
+		observe(result, p.now())
+	}
+	return results
+}
+
+// flow times a flow, or skips it when what it depends on failed
+func (p *Prober) flow(ctx context.Context, name string, ready bool, fn func(context.Context) error) Result {
+	if !ready {
+		return Result{Flow: name, Err: ErrSkipped}
+	}
+	start := time.Now()
+	err := fn(ctx)
+	return Result{Flow: name, Duration: time.Since(start), Err: err}
+}
+
+func (p *Prober) login(ctx context.Context, r *run) error {
+	var data struct {
+		CurrentUser *struct {
+			ID string `json:"id"`
+		} `json:"currentUser"`
+	}
+	if err := p.do(ctx, r, p.config.PrivateURL, `query ProbeLogin { currentUser { id } }`, nil, &data); err != nil {
+		return err
+	}
+	if data.CurrentUser == nil || data.CurrentUser.ID == "" {
+		return errors.New("the token did not resolve to a user")
+	}
+	r.loggedIn = true
+	return nil
+}
+
+func (p *Prober) availability(ctx context.Context, r *run) error {
+	r.serviceID = p.config.ServiceID
+	if r.serviceID == "" {
+		var data struct {
+			Services []struct {
+				ID                   string `json:"id"`
+				OnlineBooking        string `json:"onlineBooking"`
+				ReturningClientsOnly bool   `json:"returningClientsOnly"`
+			} `json:"services"`
+		}
+		query := `query ProbeServices($businessId: String!) { services(businessId: $businessId) { id onlineBooking returningClientsOnly } }`
+		if err := p.do(ctx, r, p.config.PublicURL, query, map[string]any{"businessId": p.config.BusinessID}, &data); err != nil {
+			return err
+		}
+		for _, service := range data.Services {
+			if service.OnlineBooking == "BOOKABLE" && !service.ReturningClientsOnly {
+				r.serviceID = service.ID
+				break
+			}
+		}
+		if r.serviceID == "" {
+			return errors.New("the business has no service that can be booked online")
+		}
+	}
+
+	query := `query ProbeSlots($businessId: String!, $serviceId: String!, $date: String!) {
+  availableSlots(businessId: $businessId, serviceId: $serviceId, date: $date) { staffId startTime }
+}`
+	tomorrow := p.now().UTC().AddDate(0, 0, 1)
+	for day := 0; day < p.config.LookAheadDays; day++ {
+		var data struct {
+			AvailableSlots []slot `json:"availableSlots"`
+		}
+		vars := map[string]any{
+			"businessId": p.config.BusinessID,
+			"serviceId":  r.serviceID,
+			"date":       tomorrow.AddDate(0, 0, day).Format(time.DateOnly),
+		}
+		if err := p.do(ctx, r, p.config.PublicURL, query, vars, &data); err != nil {
+			return err
+		}
+		if len(data.AvailableSlots) > 0 {
+			r.slot = &data.AvailableSlots[0]
+			return nil
+		}
+	}
+	return fmt.Errorf("no free slot in the next %d days", p.config.LookAheadDays)
+}
+
+func (p *Prober) booking(ctx context.Context, r *run) error {
+	// Booking writes, so the business is checked to be a sandbox first: a misconfigured prober
+	// must not fill a real calendar with probe appointments
+	if err := p.checkSandbox(ctx, r); err != nil {
+		return err
+	}
+
+	var data struct {
+		RequestBooking struct {
+			Booked      bool    `json:"booked"`
+			Outcome     string  `json:"outcome"`
+			Message     *string `json:"message"`
+			Appointment *struct {
+				ID string `json:"id"`
+			} `json:"appointment"`
+		} `json:"requestBooking"`
+	}
+	query := `mutation ProbeBooking($input: BookingRequestInput!) {
+  requestBooking(input: $input) { booked outcome message appointment { id } }
+}`
+	input := map[string]any{
+		"businessId": p.config.BusinessID,
+		"serviceId":  r.serviceID,
+		"staffId":    r.slot.StaffID,
+		"startTime":  r.slot.StartTime.Format(time.RFC3339),
+		"firstName":  "Synthetic",
+		"lastName":   "Probe",
+		"email":      p.config.ClientEmail,
+		"notes":      "Booked by the synthetic monitoring prober; cancelled automatically",
+	}
+	if err := p.do(ctx, r, p.config.PublicURL, query, map[string]any{"input": input}, &data); err != nil {
+		return err
+	}
+	result := data.RequestBooking
+	if !result.Booked || result.Appointment == nil {
+		message := ""
+		if result.Message != nil {
+			message = ": " + *result.Message
+		}
+		return fmt.Errorf("the booking was refused with %s%s", result.Outcome, message)
+	}
+	r.appointment = result.Appointment.ID
+	return nil
+}
+
+// checkSandbox checks that the business probed is a sandbox
+func (p *Prober) checkSandbox(ctx context.Context, r *run) error {
+	if !r.loggedIn {
+		return errors.New("cannot check the business is a sandbox without logging in")
+	}
+	var data struct {
+		Entities []*struct {
+			IsSandbox bool `json:"isSandbox"`
+		} `json:"_entities"`
+	}
+	query := `query ProbeBusiness($representations: [_Any!]!) {
+  _entities(representations: $representations) { ... on Business { isSandbox } }
+}`
+	representations := []map[string]any{{"__typename": "Business", "id": p.config.BusinessID}}
+	if err := p.do(ctx, r, p.config.PrivateURL, query, map[string]any{"representations": representations}, &data); err != nil {
+		return err
+	}
+	if len(data.Entities) == 0 || data.Entities[0] == nil {
+		return fmt.Errorf("business %s not found", p.config.BusinessID)
+	}
+	if !data.Entities[0].IsSandbox {
+		return fmt.Errorf("business %s is not a sandbox; refusing to book in it", p.config.BusinessID)
+	}
+	return nil
+}
+
+func (p *Prober) payment(ctx context.Context, r *run) error {
+	var data struct {
+		ChargeDeposit *struct {
+			ID            string  `json:"id"`
+			Status        string  `json:"status"`
+			FailureReason *string `json:"failureReason"`
+		} `json:"chargeDeposit"`
+	}
+	query := `mutation ProbePayment($input: ChargeDepositInput!) {
+  chargeDeposit(input: $input) { id status failureReason }
+}`
+	input := map[string]any{
+		"appointmentId":   r.appointment,
+		"paymentMethodId": p.config.PaymentMethodID,
+		"amount":          p.config.DepositAmount,
+	}
+	if err := p.do(ctx, r, p.config.PrivateURL, query, map[string]any{"input": input}, &data); err != nil {
+		return err
+	}
+	payment := data.ChargeDeposit
+	if payment == nil {
+		return errors.New("no payment was returned")
+	}
+	r.payment = payment.ID
+	if payment.Status != "SUCCEEDED" {
+		reason := ""
+		if payment.FailureReason != nil {
+			reason = ": " + *payment.FailureReason
+		}
+		return fmt.Errorf("the payment is %s%s", payment.Status, reason)
+	}
+	return nil
+}
+
+// cleanUp refunds the deposit and cancels the appointment a run made. Failures are logged and
+// counted rather than failing a flow: the sandbox business is reset from time to time anyway.
+func (p *Prober) cleanUp(ctx context.Context, r *run) {
+	if r.payment != "" {
+		query := `mutation ProbeRefund($input: RefundPaymentInput!) { refundPayment(input: $input) { id } }`
+		if err := p.do(ctx, r, p.config.PrivateURL, query, map[string]any{"input": map[string]any{"paymentId": r.payment}}, nil); err != nil {
+			cleanupFailed(r, "refund", err)
+		}
+	}
+	if r.appointment != "" {
+		query := `mutation ProbeCancel($id: String!) {
+  cancelAppointment(id: $id, reason: "Synthetic monitoring probe", waiveNoShowPolicy: true) { id }
+}`
+		if err := p.do(ctx, r, p.config.PrivateURL, query, map[string]any{"id": r.appointment}, nil); err != nil {
+			cleanupFailed(r, "cancel", err)
+		}
+	}
+}
+
+func cleanupFailed(r *run, step string, err error) {
+	cleanupFailures.WithLabelValues(step).Inc()
+	log.Warn().Err(err).Str("request_id", r.requestID).Str("step", step).Msg("Failed to clean up after probing")
+}
+
+// graphQLResponse is the response to a GraphQL request
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// do sends a GraphQL operation, decoding its data into out. The private endpoint is sent the
+// token. Every request of a run carries the same request ID, so a failed run can be traced
+// through the API's logs.
+func (p *Prober) do(ctx context.Context, r *run, endpoint, query string, variables map[string]any, out any) error {
+	body, err := json.Marshal(map[string]any{"query": query, "variables": variables})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(telemetry.RequestIDHeader, r.requestID)
+	if endpoint == p.config.PrivateURL {
+		req.Header.Set("Authorization", "Bearer "+p.config.Token)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %d from %s", resp.StatusCode, endpoint)
+	}
+
+	var response graphQLResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return fmt.Errorf("invalid response from %s: %w", endpoint, err)
+	}
+	if len(response.Errors) > 0 {
+		return errors.New(response.Errors[0].Message)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(response.Data, out)
+}
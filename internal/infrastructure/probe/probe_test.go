@@ -0,0 +1,141 @@
+package probe
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/assimoes/beautix/internal/infrastructure/telemetry"
+)
+
+// fakeAPI answers the operations of the prober by their name
+type fakeAPI struct {
+	mu         sync.Mutex
+	sandbox    bool
+	slots      bool
+	operations []string
+	requestIDs map[string]bool
+	anonymous  []string // Operations sent without the token
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Query string `json:"query"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	name := strings.Fields(body.Query)[1]
+	name = strings.SplitN(name, "(", 2)[0]
+
+	f.mu.Lock()
+	f.operations = append(f.operations, name)
+	f.requestIDs[r.Header.Get(telemetry.RequestIDHeader)] = true
+	if r.Header.Get("Authorization") != "Bearer token" {
+		f.anonymous = append(f.anonymous, name)
+	}
+	f.mu.Unlock()
+
+	data := map[string]any{}
+	switch name {
+	case "ProbeLogin":
+		data["currentUser"] = map[string]any{"id": "user-1"}
+	case "ProbeServices":
+		data["services"] = []map[string]any{
+			{"id": "hidden", "onlineBooking": "HIDDEN", "returningClientsOnly": false},
+			{"id": "service-1", "onlineBooking": "BOOKABLE", "returningClientsOnly": false},
+		}
+	case "ProbeSlots":
+		slots := []map[string]any{}
+		if f.slots {
+			slots = append(slots, map[string]any{"staffId": "staff-1", "startTime": "2026-10-18T10:00:00Z"})
+		}
+		data["availableSlots"] = slots
+	case "ProbeBusiness":
+		data["_entities"] = []map[string]any{{"isSandbox": f.sandbox}}
+	case "ProbeBooking":
+		data["requestBooking"] = map[string]any{"booked": true, "outcome": "ALLOWED", "appointment": map[string]any{"id": "appointment-1"}}
+	case "ProbePayment":
+		data["chargeDeposit"] = map[string]any{"id": "payment-1", "status": "SUCCEEDED"}
+	default:
+		data["ok"] = map[string]any{"id": "x"}
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{"data": data})
+}
+
+func newTestProber(t *testing.T, api *fakeAPI) *Prober {
+	t.Helper()
+	api.requestIDs = map[string]bool{}
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+
+	prober, err := New(Config{
+		PrivateURL: server.URL + "/graphql",
+		PublicURL:  server.URL + "/public/graphql",
+		Token:      "token",
+		BusinessID: "business-1",
+	}, server.Client())
+	require.NoError(t, err)
+	prober.now = func() time.Time { return time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC) }
+	return prober
+}
+
+func TestProber_Run(t *testing.T) {
+	api := &fakeAPI{sandbox: true, slots: true}
+	results := newTestProber(t, api).Run(t.Context())
+
+	require.Len(t, results, len(Flows))
+	for i, result := range results {
+		assert.Equal(t, Flows[i], result.Flow)
+		assert.NoError(t, result.Err, result.Flow)
+	}
+	assert.Equal(t, []string{
+		"ProbeLogin", "ProbeServices", "ProbeSlots", "ProbeBusiness", "ProbeBooking", "ProbePayment", "ProbeRefund", "ProbeCancel",
+	}, api.operations, "the deposit is refunded and the appointment cancelled")
+	assert.ElementsMatch(t, []string{"ProbeServices", "ProbeSlots", "ProbeBooking"}, api.anonymous, "only the private endpoint is sent the token")
+	assert.Len(t, api.requestIDs, 1, "every request of a run carries the same request ID")
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(flowSuccess.WithLabelValues(FlowPayment)))
+	assert.Equal(t, float64(time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC).Unix()), testutil.ToFloat64(lastSuccess.WithLabelValues(FlowBooking)))
+}
+
+func TestProber_RefusesRealBusinesses(t *testing.T) {
+	api := &fakeAPI{sandbox: false, slots: true}
+	results := newTestProber(t, api).Run(t.Context())
+
+	assert.True(t, results[2].Failed())
+	assert.ErrorContains(t, results[2].Err, "not a sandbox")
+	assert.ErrorIs(t, results[3].Err, ErrSkipped)
+	assert.False(t, results[3].Failed())
+	assert.NotContains(t, api.operations, "ProbeBooking")
+	assert.NotContains(t, api.operations, "ProbeCancel", "nothing was booked to clean up")
+	assert.Equal(t, 0.0, testutil.ToFloat64(flowSuccess.WithLabelValues(FlowPayment)))
+}
+
+func TestProber_NoAvailability(t *testing.T) {
+	api := &fakeAPI{sandbox: true}
+	prober := newTestProber(t, api)
+	prober.config.LookAheadDays = 3
+	skipped := testutil.ToFloat64(flowRuns.WithLabelValues(FlowBooking, "skipped"))
+
+	results := prober.Run(t.Context())
+	assert.NoError(t, results[0].Err)
+	assert.ErrorContains(t, results[1].Err, "no free slot in the next 3 days")
+	assert.ErrorIs(t, results[2].Err, ErrSkipped)
+	assert.ErrorIs(t, results[3].Err, ErrSkipped)
+	assert.Equal(t, 3, strings.Count(strings.Join(api.operations, " "), "ProbeSlots"))
+	assert.Equal(t, skipped+1, testutil.ToFloat64(flowRuns.WithLabelValues(FlowBooking, "skipped")))
+}
+
+func TestConfig_Validate(t *testing.T) {
+	_, err := New(Config{PrivateURL: "http://api/graphql", PublicURL: "http://api/public/graphql", Token: "token"}, nil)
+	assert.ErrorContains(t, err, "sandbox business")
+	_, err = New(Config{PrivateURL: "http://api/graphql", PublicURL: "http://api/public/graphql", BusinessID: "b"}, nil)
+	assert.ErrorContains(t, err, "token")
+}
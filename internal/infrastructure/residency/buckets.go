@@ -0,0 +1,48 @@
+package residency
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// Buckets selects the storage bucket a business's files are written to
+type Buckets struct {
+	policy   *Policy
+	byRegion map[domain.DataRegion]string
+}
+
+// NewBuckets registers a storage bucket per region with the policy. Regions without a bucket
+// are skipped.
+func NewBuckets(policy *Policy, byRegion map[domain.DataRegion]string) *Buckets {
+	buckets := &Buckets{policy: policy, byRegion: map[domain.DataRegion]string{}}
+	for region, name := range byRegion {
+		if name == "" {
+			continue
+		}
+		buckets.byRegion[region] = name
+		policy.Register(domain.DataDestination{Name: name, Kind: domain.DataDestinationStorage, Region: region})
+	}
+	return buckets
+}
+
+// Select returns the bucket for data of a region: the bucket in the same region, or otherwise a
+// bucket the region permits. It never falls back to a bucket outside an EU business's region.
+func (b *Buckets) Select(region domain.DataRegion) (string, error) {
+	if name, ok := b.byRegion[region]; ok {
+		return name, b.policy.Check(region, name)
+	}
+
+	regions := make([]domain.DataRegion, 0, len(b.byRegion))
+	for candidate := range b.byRegion {
+		regions = append(regions, candidate)
+	}
+	slices.Sort(regions)
+	for _, candidate := range regions {
+		if region.Permits(candidate) {
+			return b.byRegion[candidate], nil
+		}
+	}
+	return "", fmt.Errorf("%w: no storage bucket is configured for %s data", domain.ErrDataResidency, region)
+}
@@ -0,0 +1,80 @@
+// Package residency keeps each business's data in the region the business requires. Every
+// destination outside the database - storage buckets, the analytics warehouse and third-party
+// providers - is registered with its region, and integrations check a transfer against the
+// policy before sending data. Destinations that were never registered are treated as being in
+// an unknown region, so a new integration cannot receive EU data until it declares where it is.
+package residency
+
+import (
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// Policy holds the known data destinations and the region each is in
+type Policy struct {
+	mu           sync.RWMutex
+	destinations map[string]domain.DataDestination
+}
+
+// NewPolicy creates a policy with the given destinations
+func NewPolicy(destinations ...domain.DataDestination) *Policy {
+	p := &Policy{destinations: map[string]domain.DataDestination{}}
+	for _, destination := range destinations {
+		p.Register(destination)
+	}
+	return p
+}
+
+// Register declares a destination, replacing any earlier one with the same name
+func (p *Policy) Register(destination domain.DataDestination) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.destinations[destination.Name] = destination
+}
+
+// Destination returns a registered destination. Unknown destinations are returned without a
+// region.
+func (p *Policy) Destination(name string) domain.DataDestination {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if destination, ok := p.destinations[name]; ok {
+		return destination
+	}
+	return domain.DataDestination{Name: name}
+}
+
+// Destinations returns every registered destination, by name
+func (p *Policy) Destinations() []domain.DataDestination {
+	p.mu.RLock()
+	destinations := make([]domain.DataDestination, 0, len(p.destinations))
+	for _, destination := range p.destinations {
+		destinations = append(destinations, destination)
+	}
+	p.mu.RUnlock()
+
+	slices.SortFunc(destinations, func(a, b domain.DataDestination) int { return strings.Compare(a.Name, b.Name) })
+	return destinations
+}
+
+// Check returns an ErrDataResidency error when data of the region may not be sent to the named
+// destination
+func (p *Policy) Check(region domain.DataRegion, destination string) error {
+	return region.CheckTransfer(p.Destination(destination))
+}
+
+// Filter returns the items whose region permits sending them to the destination, and the number
+// withheld. It is used when exporting the data of many businesses at once, such as to the
+// analytics warehouse.
+func Filter[T any](p *Policy, destination string, items []T, regionOf func(T) domain.DataRegion) ([]T, int) {
+	target := p.Destination(destination)
+	allowed := make([]T, 0, len(items))
+	for _, item := range items {
+		if regionOf(item).Permits(target.Region) {
+			allowed = append(allowed, item)
+		}
+	}
+	return allowed, len(items) - len(allowed)
+}
@@ -0,0 +1,66 @@
+package residency
+
+import (
+	"testing"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicy_Check(t *testing.T) {
+	policy := NewPolicy(
+		domain.DataDestination{Name: "warehouse-eu", Kind: domain.DataDestinationWarehouse, Region: domain.DataRegionEU},
+		domain.DataDestination{Name: "warehouse-us", Kind: domain.DataDestinationWarehouse, Region: domain.DataRegionUS},
+	)
+
+	assert.NoError(t, policy.Check(domain.DataRegionEU, "warehouse-eu"))
+	assert.ErrorIs(t, policy.Check(domain.DataRegionEU, "warehouse-us"), domain.ErrDataResidency)
+	assert.ErrorIs(t, policy.Check(domain.DataRegionEU, "new-integration"), domain.ErrDataResidency,
+		"destinations that never declared a region do not receive EU data")
+	assert.NoError(t, policy.Check(domain.DataRegionUS, "warehouse-eu"))
+	assert.NoError(t, policy.Check(domain.DataRegionUS, "new-integration"))
+
+	destinations := policy.Destinations()
+	require.Len(t, destinations, 2)
+	assert.Equal(t, "warehouse-eu", destinations[0].Name)
+}
+
+func TestFilter(t *testing.T) {
+	type business struct {
+		id     string
+		region domain.DataRegion
+	}
+	businesses := []business{{"1", domain.DataRegionEU}, {"2", domain.DataRegionUS}, {"3", domain.DataRegionEU}}
+	regionOf := func(b business) domain.DataRegion { return b.region }
+
+	policy := NewPolicy(domain.DataDestination{Name: "warehouse", Kind: domain.DataDestinationWarehouse, Region: domain.DataRegionUS})
+	allowed, withheld := Filter(policy, "warehouse", businesses, regionOf)
+	assert.Equal(t, []business{{"2", domain.DataRegionUS}}, allowed)
+	assert.Equal(t, 2, withheld)
+
+	policy.Register(domain.DataDestination{Name: "warehouse", Kind: domain.DataDestinationWarehouse, Region: domain.DataRegionEU})
+	allowed, withheld = Filter(policy, "warehouse", businesses, regionOf)
+	assert.Len(t, allowed, 3)
+	assert.Zero(t, withheld)
+}
+
+func TestBuckets_Select(t *testing.T) {
+	policy := NewPolicy()
+	buckets := NewBuckets(policy, map[domain.DataRegion]string{
+		domain.DataRegionEU: "beautix-files-eu",
+		domain.DataRegionUS: "",
+	})
+
+	bucket, err := buckets.Select(domain.DataRegionEU)
+	require.NoError(t, err)
+	assert.Equal(t, "beautix-files-eu", bucket)
+
+	bucket, err = buckets.Select(domain.DataRegionUS)
+	require.NoError(t, err)
+	assert.Equal(t, "beautix-files-eu", bucket, "US data may be stored in the EU")
+
+	usOnly := NewBuckets(NewPolicy(), map[domain.DataRegion]string{domain.DataRegionUS: "beautix-files-us"})
+	_, err = usOnly.Select(domain.DataRegionEU)
+	assert.ErrorIs(t, err, domain.ErrDataResidency, "EU data never falls back to a US bucket")
+}
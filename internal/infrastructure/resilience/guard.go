@@ -0,0 +1,250 @@
+// Package resilience protects calls to external providers with timeouts, retries with jitter
+// and circuit breakers, and reports the health of each provider
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// State is the state of a provider's circuit
+type State string
+
+const (
+	StateClosed   State = "closed"    // Calls go through
+	StateOpen     State = "open"      // Calls fail fast until the open duration has passed
+	StateHalfOpen State = "half_open" // A single probe call decides whether to close the circuit again
+)
+
+// ErrCircuitOpen is returned without calling the provider while its circuit is open
+var ErrCircuitOpen = errors.New("circuit open")
+
+// Policy configures how calls to a provider are protected
+type Policy struct {
+	Timeout          time.Duration // The provider's SLA for a single call; slower calls are abandoned and count as failures
+	MaxAttempts      int           // Attempts per call, including the first
+	BaseBackoff      time.Duration // Upper bound of the first retry delay, doubled for each further retry
+	MaxBackoff       time.Duration
+	FailureThreshold int           // Consecutive failures that open the circuit
+	OpenDuration     time.Duration // How long the circuit stays open before a probe call is let through
+}
+
+// DefaultPolicy returns the policy for providers without a specific one
+func DefaultPolicy() Policy {
+	return Policy{
+		Timeout:          10 * time.Second,
+		MaxAttempts:      3,
+		BaseBackoff:      200 * time.Millisecond,
+		MaxBackoff:       2 * time.Second,
+		FailureThreshold: 5,
+		OpenDuration:     30 * time.Second,
+	}
+}
+
+// permanentError marks an error that retrying cannot fix
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent marks an error that retrying cannot fix, such as a rejected request. The provider
+// answered, so permanent errors are not retried and do not count against its circuit.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return permanentError{err: err}
+}
+
+// IsPermanent reports whether an error was marked as permanent
+func IsPermanent(err error) bool {
+	var permanent permanentError
+	return errors.As(err, &permanent)
+}
+
+// IsRetryableStatus reports whether an HTTP status code is worth retrying: timeouts, rate
+// limiting and server errors
+func IsRetryableStatus(code int) bool {
+	return code == 408 || code == 429 || code >= 500
+}
+
+// Guard protects the calls to a single provider. A nil Guard calls through unprotected.
+type Guard struct {
+	name   string
+	policy Policy
+
+	mu            sync.Mutex
+	state         State
+	failures      int
+	openedAt      time.Time
+	probing       bool
+	lastError     string
+	lastFailureAt *time.Time
+	lastSuccessAt *time.Time
+
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewGuard creates a guard for a provider
+func NewGuard(name string, policy Policy) *Guard {
+	return &Guard{name: name, policy: policy, state: StateClosed, now: time.Now, sleep: sleep}
+}
+
+// Do calls fn, retrying failures with jittered exponential backoff. Each attempt is bounded by
+// the policy timeout. While the circuit is open fn is not called and ErrCircuitOpen is returned.
+func (g *Guard) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if g == nil {
+		return fn(ctx)
+	}
+
+	var err error
+	for attempt := 1; attempt <= g.policy.MaxAttempts; attempt++ {
+		if !g.allow() {
+			if err != nil {
+				return err
+			}
+			return fmt.Errorf("%w: %s", ErrCircuitOpen, g.name)
+		}
+
+		attemptCtx, cancel := context.WithTimeout(ctx, g.policy.Timeout)
+		err = fn(attemptCtx)
+		cancel()
+
+		switch {
+		case err == nil || IsPermanent(err):
+			g.recordSuccess()
+			return err
+		case ctx.Err() != nil:
+			// The caller gave up, which says nothing about the provider
+			g.release()
+			return err
+		}
+		g.recordFailure(err)
+
+		if attempt < g.policy.MaxAttempts {
+			if sleepErr := g.sleep(ctx, g.backoff(attempt)); sleepErr != nil {
+				return err
+			}
+		}
+	}
+	return err
+}
+
+// Name returns the name of the provider
+func (g *Guard) Name() string {
+	return g.name
+}
+
+// Health returns the current state of the provider
+func (g *Guard) Health() ProviderHealth {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	health := ProviderHealth{
+		Name:                g.name,
+		State:               g.state,
+		ConsecutiveFailures: g.failures,
+		LastFailureAt:       g.lastFailureAt,
+		LastSuccessAt:       g.lastSuccessAt,
+	}
+	if g.lastError != "" {
+		health.LastError = &g.lastError
+	}
+	if g.state == StateOpen {
+		retryAt := g.openedAt.Add(g.policy.OpenDuration)
+		health.RetryAt = &retryAt
+	}
+	return health
+}
+
+// allow reports whether a call may go through, moving an open circuit to half-open once the
+// open duration has passed
+func (g *Guard) allow() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	switch g.state {
+	case StateOpen:
+		if g.now().Sub(g.openedAt) < g.policy.OpenDuration {
+			return false
+		}
+		g.state = StateHalfOpen
+		g.probing = true
+		return true
+	case StateHalfOpen:
+		if g.probing {
+			return false
+		}
+		g.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// recordSuccess closes the circuit
+func (g *Guard) recordSuccess() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := g.now()
+	g.state = StateClosed
+	g.failures = 0
+	g.probing = false
+	g.lastSuccessAt = &now
+}
+
+// recordFailure counts a failure, opening the circuit at the threshold or when a probe fails
+func (g *Guard) recordFailure(err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := g.now()
+	g.failures++
+	g.lastError = err.Error()
+	g.lastFailureAt = &now
+	if g.state == StateHalfOpen || g.failures >= g.policy.FailureThreshold {
+		g.state = StateOpen
+		g.openedAt = now
+	}
+	g.probing = false
+}
+
+// release lets another probe through after a probe the caller abandoned
+func (g *Guard) release() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.probing = false
+}
+
+// backoff returns a random delay up to the exponential bound for an attempt ("full jitter"),
+// which keeps clients that failed together from retrying together
+func (g *Guard) backoff(attempt int) time.Duration {
+	bound := g.policy.BaseBackoff << (attempt - 1)
+	if bound <= 0 || bound > g.policy.MaxBackoff {
+		bound = g.policy.MaxBackoff
+	}
+	if bound <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int64N(int64(bound) + 1))
+}
+
+// sleep waits for d or until the context is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,120 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errUnavailable = errors.New("provider unavailable")
+
+// newTestGuard creates a guard on a fake clock that does not sleep between retries
+func newTestGuard(policy Policy) (*Guard, *time.Time) {
+	now := time.Date(2026, time.October, 17, 9, 0, 0, 0, time.UTC)
+	guard := NewGuard("test", policy)
+	guard.now = func() time.Time { return now }
+	guard.sleep = func(context.Context, time.Duration) error { return nil }
+	return guard, &now
+}
+
+func TestGuard_Retries(t *testing.T) {
+	guard, _ := newTestGuard(DefaultPolicy())
+
+	calls := 0
+	err := guard.Do(context.Background(), func(context.Context) error {
+		calls++
+		if calls < 3 {
+			return errUnavailable
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, StateClosed, guard.Health().State)
+	assert.Zero(t, guard.Health().ConsecutiveFailures, "a success resets the failures")
+
+	calls = 0
+	err = guard.Do(context.Background(), func(context.Context) error {
+		calls++
+		return Permanent(errors.New("invalid phone number"))
+	})
+	assert.True(t, IsPermanent(err))
+	assert.Equal(t, 1, calls, "permanent errors are not retried")
+	assert.Zero(t, guard.Health().ConsecutiveFailures)
+}
+
+func TestGuard_CircuitBreaker(t *testing.T) {
+	policy := DefaultPolicy()
+	policy.MaxAttempts = 1
+	policy.FailureThreshold = 2
+	guard, now := newTestGuard(policy)
+
+	failing := func(context.Context) error { return errUnavailable }
+	assert.ErrorIs(t, guard.Do(context.Background(), failing), errUnavailable)
+	assert.ErrorIs(t, guard.Do(context.Background(), failing), errUnavailable)
+	health := guard.Health()
+	assert.Equal(t, StateOpen, health.State)
+	require.NotNil(t, health.RetryAt)
+	assert.Equal(t, now.Add(policy.OpenDuration), *health.RetryAt)
+
+	called := false
+	err := guard.Do(context.Background(), func(context.Context) error { called = true; return nil })
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.False(t, called, "an open circuit fails fast")
+
+	// A failed probe opens the circuit again
+	*now = now.Add(policy.OpenDuration)
+	assert.ErrorIs(t, guard.Do(context.Background(), failing), errUnavailable)
+	assert.Equal(t, StateOpen, guard.Health().State)
+
+	// A successful probe closes it
+	*now = now.Add(policy.OpenDuration)
+	require.NoError(t, guard.Do(context.Background(), func(context.Context) error { return nil }))
+	assert.Equal(t, StateClosed, guard.Health().State)
+}
+
+func TestGuard_Timeout(t *testing.T) {
+	policy := DefaultPolicy()
+	policy.Timeout = 10 * time.Millisecond
+	policy.MaxAttempts = 1
+	guard, _ := newTestGuard(policy)
+
+	err := guard.Do(context.Background(), func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, guard.Health().ConsecutiveFailures, "calls slower than the SLA count as failures")
+}
+
+func TestGuard_Backoff(t *testing.T) {
+	guard, _ := newTestGuard(DefaultPolicy())
+	for attempt := 1; attempt <= 6; attempt++ {
+		delay := guard.backoff(attempt)
+		assert.GreaterOrEqual(t, delay, time.Duration(0))
+		assert.LessOrEqual(t, delay, guard.policy.MaxBackoff)
+	}
+}
+
+func TestNilGuard(t *testing.T) {
+	var guard *Guard
+	called := false
+	require.NoError(t, guard.Do(context.Background(), func(context.Context) error { called = true; return nil }))
+	assert.True(t, called)
+}
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+	twilio := registry.Guard("twilio", DefaultPolicy())
+	assert.Same(t, twilio, registry.Guard("twilio", DefaultPolicy()))
+	registry.Guard("stripe", DefaultPolicy())
+
+	health := registry.Health()
+	require.Len(t, health, 2)
+	assert.Equal(t, "stripe", health[0].Name)
+	assert.Equal(t, StateClosed, health[1].State)
+}
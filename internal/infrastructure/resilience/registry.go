@@ -0,0 +1,60 @@
+package resilience
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ProviderHealth is the state of an external provider as seen by its guard
+type ProviderHealth struct {
+	Name                string     `json:"name"`
+	State               State      `json:"state"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	LastError           *string    `json:"last_error,omitempty"`
+	LastFailureAt       *time.Time `json:"last_failure_at,omitempty"`
+	LastSuccessAt       *time.Time `json:"last_success_at,omitempty"`
+	RetryAt             *time.Time `json:"retry_at,omitempty"` // When an open circuit lets a probe call through
+}
+
+// Registry holds the guard of every external provider
+type Registry struct {
+	mu     sync.Mutex
+	guards map[string]*Guard
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{guards: map[string]*Guard{}}
+}
+
+// Guard returns the guard of a provider, creating it with the policy on first use
+func (r *Registry) Guard(name string, policy Policy) *Guard {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if guard, ok := r.guards[name]; ok {
+		return guard
+	}
+	guard := NewGuard(name, policy)
+	r.guards[name] = guard
+	return guard
+}
+
+// Health returns the state of every provider, by name
+func (r *Registry) Health() []ProviderHealth {
+	r.mu.Lock()
+	guards := make([]*Guard, 0, len(r.guards))
+	for _, guard := range r.guards {
+		guards = append(guards, guard)
+	}
+	r.mu.Unlock()
+
+	health := make([]ProviderHealth, len(guards))
+	for i, guard := range guards {
+		health[i] = guard.Health()
+	}
+	slices.SortFunc(health, func(a, b ProviderHealth) int { return strings.Compare(a.Name, b.Name) })
+	return health
+}
@@ -0,0 +1,243 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // Registers the GIF decoder
+	"image/jpeg"
+	"image/png"
+)
+
+// maxImagePixels bounds the size of an image once decoded, so that a small file cannot expand
+// into gigabytes of pixels
+const maxImagePixels = 40_000_000
+
+// jpegQuality is the quality photos are re-encoded at
+const jpegQuality = 85
+
+// ErrInvalidImage is returned for files that are not images of the type they claim to be
+var ErrInvalidImage = errors.New("the file is not a valid image")
+
+// imageFormats maps the content types of the images accepted to the name their decoder registers
+var imageFormats = map[string]string{
+	"image/jpeg": "jpeg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+}
+
+// IsImageContentType reports whether images of a content type are accepted
+func IsImageContentType(contentType string) bool {
+	_, ok := imageFormats[contentType]
+	return ok
+}
+
+// Image is an image re-encoded for serving
+type Image struct {
+	Body        []byte
+	ContentType string
+	Extension   string
+	Width       int
+	Height      int
+}
+
+// ProcessImage decodes an uploaded image, checking it is of the content type it was declared as,
+// turns it upright, scales it down to fit maxDimension pixels on either side and re-encodes it.
+// Re-encoding drops any metadata, such as where a photo was taken. JPEGs stay JPEGs; PNGs and
+// GIFs, which may be transparent, become PNGs, of the first frame for animations.
+func ProcessImage(data []byte, contentType string, maxDimension int) (*Image, error) {
+	format, ok := imageFormats[contentType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s images are not accepted", ErrInvalidImage, contentType)
+	}
+	config, decoded, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+	if decoded != format {
+		return nil, fmt.Errorf("%w: the file is a %s image, not %s", ErrInvalidImage, decoded, contentType)
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxImagePixels {
+		return nil, fmt.Errorf("%w: the image is %dx%d pixels", ErrInvalidImage, config.Width, config.Height)
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrInvalidImage
+	}
+
+	var upright image.Image = src
+	if format == "jpeg" {
+		if orientation := jpegOrientation(data); orientation > 1 && orientation <= 8 {
+			upright = &orientedImage{src: src, orientation: orientation}
+		}
+	}
+	width, height := fit(upright.Bounds().Dx(), upright.Bounds().Dy(), maxDimension)
+	scaled := scale(upright, width, height)
+
+	var buf bytes.Buffer
+	result := &Image{Width: width, Height: height}
+	if format == "jpeg" {
+		err = jpeg.Encode(&buf, scaled, &jpeg.Options{Quality: jpegQuality})
+		result.ContentType, result.Extension = "image/jpeg", "jpg"
+	} else {
+		err = png.Encode(&buf, scaled)
+		result.ContentType, result.Extension = "image/png", "png"
+	}
+	if err != nil {
+		return nil, err
+	}
+	result.Body = buf.Bytes()
+	return result, nil
+}
+
+// fit returns the size of an image scaled down to fit maxDimension on either side, keeping its
+// aspect ratio; images that fit are kept at their size
+func fit(width, height, maxDimension int) (int, int) {
+	if width <= maxDimension && height <= maxDimension {
+		return width, height
+	}
+	if width >= height {
+		return maxDimension, max(1, height*maxDimension/width)
+	}
+	return max(1, width*maxDimension/height), maxDimension
+}
+
+// scale resamples an image to a size no larger than its own, averaging the pixels each one
+// covers. Colours are averaged premultiplied by their alpha, so transparent pixels do not bleed
+// into the edges of opaque ones.
+func scale(src image.Image, width, height int) *image.NRGBA {
+	bounds := src.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + y*bounds.Dy()/height
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*bounds.Dy()/height)
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*bounds.Dx()/width
+			x1 := max(x0+1, bounds.Min.X+(x+1)*bounds.Dx()/width)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := src.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa)
+					n++
+				}
+			}
+			if a == 0 {
+				continue
+			}
+			dst.SetNRGBA(x, y, color.NRGBA{
+				R: uint8(r * 0xff / a),
+				G: uint8(g * 0xff / a),
+				B: uint8(b * 0xff / a),
+				A: uint8(a / n >> 8),
+			})
+		}
+	}
+	return dst
+}
+
+// orientedImage presents an image turned the way its EXIF orientation says it is displayed
+type orientedImage struct {
+	src         image.Image
+	orientation int
+}
+
+func (o *orientedImage) ColorModel() color.Model { return o.src.ColorModel() }
+
+// transposed reports whether the orientation swaps width and height
+func (o *orientedImage) transposed() bool { return o.orientation >= 5 }
+
+func (o *orientedImage) Bounds() image.Rectangle {
+	size := o.src.Bounds().Size()
+	if o.transposed() {
+		return image.Rect(0, 0, size.Y, size.X)
+	}
+	return image.Rect(0, 0, size.X, size.Y)
+}
+
+func (o *orientedImage) At(x, y int) color.Color {
+	bounds := o.src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	var sx, sy int
+	switch o.orientation {
+	case 2: // Mirrored
+		sx, sy = w-1-x, y
+	case 3: // Upside down
+		sx, sy = w-1-x, h-1-y
+	case 4: // Upside down and mirrored
+		sx, sy = x, h-1-y
+	case 5: // Mirrored and turned a quarter anticlockwise
+		sx, sy = y, x
+	case 6: // Turned a quarter anticlockwise
+		sx, sy = y, h-1-x
+	case 7: // Mirrored and turned a quarter clockwise
+		sx, sy = w-1-y, h-1-x
+	case 8: // Turned a quarter clockwise
+		sx, sy = w-1-y, x
+	default:
+		sx, sy = x, y
+	}
+	return o.src.At(bounds.Min.X+sx, bounds.Min.Y+sy)
+}
+
+// jpegOrientation returns the EXIF orientation of a JPEG, from 1 (upright) to 8, or 0 when it has
+// none
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 0
+	}
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 0
+		}
+		marker := data[i+1]
+		if marker == 0xDA || marker == 0xD9 { // The image data starts
+			return 0
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if length < 2 || i+2+length > len(data) {
+			return 0
+		}
+		segment := data[i+4 : i+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return exifOrientation(segment[6:])
+		}
+		i += 2 + length
+	}
+	return 0
+}
+
+// exifOrientation reads the orientation tag from the first directory of EXIF data
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 0
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+	offset := int(order.Uint32(tiff[4:]))
+	if offset < 8 || offset+2 > len(tiff) {
+		return 0
+	}
+	entries := int(order.Uint16(tiff[offset:]))
+	for i := 0; i < entries; i++ {
+		entry := offset + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 0
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 { // Orientation, a SHORT held in the value field
+			return int(order.Uint16(tiff[entry+8:]))
+		}
+	}
+	return 0
+}
@@ -0,0 +1,104 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// encodePNG encodes a picture of the size, red on the left half and transparent on the right
+func encodePNG(t *testing.T, width, height int) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width/2; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: 255, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+// withOrientation inserts an EXIF segment holding an orientation after the start of a JPEG
+func withOrientation(data []byte, orientation uint16) []byte {
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08") // Big endian, first directory right after
+	tiff = binary.BigEndian.AppendUint16(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, 0x0112)
+	tiff = binary.BigEndian.AppendUint16(tiff, 3) // SHORT
+	tiff = binary.BigEndian.AppendUint32(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0) // Value padding and no next directory
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+
+	out := append([]byte{}, data[:2]...)
+	out = append(out, 0xFF, 0xE1)
+	out = binary.BigEndian.AppendUint16(out, uint16(len(segment)+2))
+	out = append(out, segment...)
+	return append(out, data[2:]...)
+}
+
+func TestProcessImage_ScalesDownKeepingTransparency(t *testing.T) {
+	processed, err := ProcessImage(encodePNG(t, 400, 200), "image/png", 100)
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", processed.ContentType)
+	assert.Equal(t, "png", processed.Extension)
+	assert.Equal(t, 100, processed.Width)
+	assert.Equal(t, 50, processed.Height)
+
+	decoded, err := png.Decode(bytes.NewReader(processed.Body))
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 100, 50), decoded.Bounds())
+	assert.Equal(t, color.NRGBA{R: 255, A: 255}, color.NRGBAModel.Convert(decoded.At(10, 10)))
+	assert.Equal(t, uint8(0), color.NRGBAModel.Convert(decoded.At(90, 10)).(color.NRGBA).A)
+}
+
+func TestProcessImage_KeepsSmallImagesAtTheirSize(t *testing.T) {
+	processed, err := ProcessImage(encodePNG(t, 40, 30), "image/png", 100)
+	require.NoError(t, err)
+	assert.Equal(t, 40, processed.Width)
+	assert.Equal(t, 30, processed.Height)
+}
+
+func TestProcessImage_TurnsPhotosUpright(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 60, 20)), nil))
+
+	processed, err := ProcessImage(withOrientation(buf.Bytes(), 6), "image/jpeg", 100)
+	require.NoError(t, err)
+	assert.Equal(t, "image/jpeg", processed.ContentType)
+	assert.Equal(t, 20, processed.Width, "a photo turned a quarter is stored on its side")
+	assert.Equal(t, 60, processed.Height)
+	assert.Equal(t, 0, jpegOrientation(processed.Body), "the metadata is dropped")
+}
+
+func TestProcessImage_RejectsFilesThatAreNotWhatTheyClaim(t *testing.T) {
+	_, err := ProcessImage([]byte("<svg></svg>"), "image/png", 100)
+	assert.ErrorIs(t, err, ErrInvalidImage)
+
+	_, err = ProcessImage(encodePNG(t, 10, 10), "image/jpeg", 100)
+	assert.ErrorIs(t, err, ErrInvalidImage, "a PNG declared as a JPEG is rejected")
+
+	_, err = ProcessImage(encodePNG(t, 10, 10), "image/svg+xml", 100)
+	assert.ErrorIs(t, err, ErrInvalidImage)
+}
+
+func TestProcessImage_RejectsDecompressionBombs(t *testing.T) {
+	// A PNG header claiming 10000x10000 pixels is enough to be refused before decoding
+	header := encodePNG(t, 1, 1)
+	binary.BigEndian.PutUint32(header[16:], 10000)
+	binary.BigEndian.PutUint32(header[20:], 10000)
+	binary.BigEndian.PutUint32(header[29:], crc32.ChecksumIEEE(header[12:29]))
+	_, _, err := image.DecodeConfig(bytes.NewReader(header))
+	require.NoError(t, err)
+
+	_, err = ProcessImage(header, "image/png", 100)
+	assert.ErrorIs(t, err, ErrInvalidImage)
+}
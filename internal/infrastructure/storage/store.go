@@ -0,0 +1,446 @@
+// Package storage keeps files in S3-compatible object storage, such as report exports and
+// uploaded images, and hands out presigned URLs they can be uploaded to and downloaded from
+// without credentials. Requests are signed with AWS Signature Version 4, so any service speaking
+// the S3 API can be used.
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/assimoes/beautix/configs"
+	"github.com/assimoes/beautix/internal/infrastructure/outbound"
+	"github.com/assimoes/beautix/internal/infrastructure/resilience"
+)
+
+// requestTimeout bounds a single request to the storage service
+const requestTimeout = time.Minute
+
+// MaxURLExpiry is the longest a presigned URL can stay valid
+const MaxURLExpiry = 7 * 24 * time.Hour
+
+// ProviderName is the name the storage service's health is reported under
+const ProviderName = "object_storage"
+
+// Policy protects calls to the storage service. Reading, writing and deleting an object are
+// idempotent, so all are retried.
+var Policy = resilience.Policy{
+	Timeout:          2 * time.Minute,
+	MaxAttempts:      3,
+	BaseBackoff:      500 * time.Millisecond,
+	MaxBackoff:       4 * time.Second,
+	FailureThreshold: 5,
+	OpenDuration:     time.Minute,
+}
+
+// ErrNotConfigured is returned by the store when no storage credentials are set
+var ErrNotConfigured = errors.New("object storage is not configured")
+
+// ErrNotFound is returned when reading an object that does not exist
+var ErrNotFound = errors.New("object not found")
+
+// ErrTooLarge is returned when reading an object larger than allowed
+var ErrTooLarge = errors.New("object is too large")
+
+// Error is an error response from the storage service
+type Error struct {
+	StatusCode int
+	Code       string `xml:"Code"`
+	Message    string `xml:"Message"`
+}
+
+// Error describes the failure
+func (e *Error) Error() string {
+	return fmt.Sprintf("object storage rejected the request (status %d): %s %s", e.StatusCode, e.Code, e.Message)
+}
+
+// Object is a file to store
+type Object struct {
+	Bucket      string
+	Key         string
+	ContentType string
+	Body        []byte
+}
+
+// Store keeps files in buckets
+type Store interface {
+	// Put writes an object, replacing any with the same key
+	Put(ctx context.Context, object Object) error
+	// Get reads an object of at most maxBytes
+	Get(ctx context.Context, bucket, key string, maxBytes int64) ([]byte, error)
+	// Delete removes an object; removing one that does not exist succeeds
+	Delete(ctx context.Context, bucket, key string) error
+	// SignedURL returns a URL anyone holding it can download an object from until it expires,
+	// saving it under the file name
+	SignedURL(bucket, key, fileName string, expires time.Duration) (string, error)
+	// SignedUploadURL returns a URL anyone holding it can write an object to with a PUT until it
+	// expires, sending the content type and length given
+	SignedUploadURL(bucket, key, contentType string, size int64, expires time.Duration) (string, error)
+	// ObjectURL returns the unsigned URL of an object, which serves it to anyone when the bucket
+	// allows public reads of it
+	ObjectURL(bucket, key string) (string, error)
+}
+
+// client calls the S3 REST API
+type client struct {
+	cfg           configs.StorageConfig
+	regions       map[string]string // Signing region of each bucket
+	endpoint      *url.URL
+	virtualHosted bool // Buckets are addressed as subdomains of the endpoint rather than path prefixes
+	http          *http.Client
+	guard         *resilience.Guard
+	now           func() time.Time
+}
+
+// NewStore creates a store for the configured credentials and buckets, given with the region
+// each is in, with calls protected by the guard and audited by the recorder. Without credentials
+// every call fails with ErrNotConfigured, so the API runs without exports.
+func NewStore(cfg configs.StorageConfig, regions map[string]string, guard *resilience.Guard, recorder *outbound.Recorder) (Store, error) {
+	if cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return disabledStore{}, nil
+	}
+	c := &client{
+		cfg:     cfg,
+		regions: regions,
+		http:    recorder.Client(ProviderName, requestTimeout),
+		guard:   guard,
+		now:     time.Now,
+	}
+	if cfg.Endpoint != "" {
+		endpoint, err := url.Parse(strings.TrimRight(cfg.Endpoint, "/"))
+		if err != nil || endpoint.Host == "" {
+			return nil, fmt.Errorf("invalid storage endpoint %q", cfg.Endpoint)
+		}
+		c.endpoint = endpoint
+	} else {
+		c.virtualHosted = true
+	}
+	return c, nil
+}
+
+// Put writes an object
+func (c *client) Put(ctx context.Context, object Object) error {
+	return c.guard.Do(ctx, func(ctx context.Context) error {
+		_, err := c.send(ctx, http.MethodPut, object.Bucket, object.Key, object.ContentType, object.Body, 0)
+		return err
+	})
+}
+
+// Get reads an object
+func (c *client) Get(ctx context.Context, bucket, key string, maxBytes int64) ([]byte, error) {
+	var body []byte
+	err := c.guard.Do(ctx, func(ctx context.Context) error {
+		var err error
+		body, err = c.send(ctx, http.MethodGet, bucket, key, "", nil, maxBytes)
+		return err
+	})
+	return body, err
+}
+
+// Delete removes an object
+func (c *client) Delete(ctx context.Context, bucket, key string) error {
+	return c.guard.Do(ctx, func(ctx context.Context) error {
+		_, err := c.send(ctx, http.MethodDelete, bucket, key, "", nil, 0)
+		return err
+	})
+}
+
+// SignedURL presigns a GET of the object, with the file name in the Content-Disposition of the
+// response
+func (c *client) SignedURL(bucket, key, fileName string, expires time.Duration) (string, error) {
+	query := url.Values{}
+	if fileName != "" {
+		query.Set("response-content-disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+	}
+	return c.presign(http.MethodGet, bucket, key, query, nil, expires)
+}
+
+// SignedUploadURL presigns a PUT of the object. The content type and length are signed, so the
+// upload must send them as is, which bounds the size of what can be uploaded.
+func (c *client) SignedUploadURL(bucket, key, contentType string, size int64, expires time.Duration) (string, error) {
+	if contentType == "" || size <= 0 {
+		return "", errors.New("a content type and length are required")
+	}
+	return c.presign(http.MethodPut, bucket, key, url.Values{}, map[string]string{
+		"content-type":   contentType,
+		"content-length": strconv.FormatInt(size, 10),
+	}, expires)
+}
+
+// ObjectURL returns the URL of the object
+func (c *client) ObjectURL(bucket, key string) (string, error) {
+	target, _, err := c.locate(bucket, key)
+	if err != nil {
+		return "", err
+	}
+	return target.String(), nil
+}
+
+// presign signs a request for the object into the query of its URL, along with the headers the
+// request must send
+func (c *client) presign(method, bucket, key string, query url.Values, headers map[string]string, expires time.Duration) (string, error) {
+	if expires <= 0 || expires > MaxURLExpiry {
+		return "", fmt.Errorf("signed URLs must expire within %s", MaxURLExpiry)
+	}
+	target, region, err := c.locate(bucket, key)
+	if err != nil {
+		return "", err
+	}
+
+	signed := map[string]string{"host": target.Host}
+	for name, value := range headers {
+		signed[name] = value
+	}
+	now := c.now().UTC()
+	query.Set("X-Amz-Algorithm", signingAlgorithm)
+	query.Set("X-Amz-Credential", c.cfg.AccessKeyID+"/"+credentialScope(now, region))
+	query.Set("X-Amz-Date", now.Format(amzDateFormat))
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expires.Seconds())))
+	query.Set("X-Amz-SignedHeaders", signedHeaders(signed))
+	target.RawQuery = canonicalQuery(query)
+
+	signature := c.signature(method, target, signed, unsignedPayload, now, region)
+	target.RawQuery += "&X-Amz-Signature=" + signature
+	return target.String(), nil
+}
+
+// send makes a single signed request, returning the body of the response to a GET, which may be
+// at most maxBytes. Errors the service will repeat on retry are marked permanent.
+func (c *client) send(ctx context.Context, method, bucket, key, contentType string, body []byte, maxBytes int64) ([]byte, error) {
+	target, region, err := c.locate(bucket, key)
+	if err != nil {
+		return nil, resilience.Permanent(err)
+	}
+	var reader io.Reader = http.NoBody
+	if len(body) > 0 {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), reader)
+	if err != nil {
+		return nil, resilience.Permanent(err)
+	}
+	// Stored files hold personal data, so their bodies are kept out of the outbound audit
+	req.GetBody = nil
+	req.ContentLength = int64(len(body))
+
+	now := c.now().UTC()
+	payloadHash := sha256.Sum256(body)
+	headers := map[string]string{
+		"host":                 target.Host,
+		"x-amz-content-sha256": hex.EncodeToString(payloadHash[:]),
+		"x-amz-date":           now.Format(amzDateFormat),
+	}
+	if contentType != "" {
+		headers["content-type"] = contentType
+	}
+	signature := c.signature(method, target, headers, headers["x-amz-content-sha256"], now, region)
+	for name, value := range headers {
+		if name != "host" {
+			req.Header.Set(name, value)
+		}
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		signingAlgorithm, c.cfg.AccessKeyID, credentialScope(now, region), signedHeaders(headers), signature))
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("object storage request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 300 && method == http.MethodGet {
+		if resp.ContentLength > maxBytes {
+			return nil, resilience.Permanent(ErrTooLarge)
+		}
+		content, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+		if err != nil {
+			return nil, fmt.Errorf("object storage response failed: %w", err)
+		}
+		if int64(len(content)) > maxBytes {
+			return nil, resilience.Permanent(ErrTooLarge)
+		}
+		return content, nil
+	}
+	if resp.StatusCode < 300 || (method == http.MethodDelete && resp.StatusCode == http.StatusNotFound) {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20))
+		return nil, nil
+	}
+	if method == http.MethodGet && resp.StatusCode == http.StatusNotFound {
+		return nil, resilience.Permanent(ErrNotFound)
+	}
+
+	failure := &Error{StatusCode: resp.StatusCode}
+	_ = xml.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(failure)
+	if failure.Message == "" {
+		failure.Message = resp.Status
+	}
+	if !resilience.IsRetryableStatus(resp.StatusCode) {
+		return nil, resilience.Permanent(failure)
+	}
+	return nil, failure
+}
+
+// locate returns the URL of an object and the region requests for it are signed for
+func (c *client) locate(bucket, key string) (*url.URL, string, error) {
+	region, ok := c.regions[bucket]
+	if !ok || region == "" {
+		return nil, "", fmt.Errorf("no region is configured for storage bucket %q", bucket)
+	}
+	if key == "" {
+		return nil, "", errors.New("an object key is required")
+	}
+
+	var target url.URL
+	if c.virtualHosted {
+		endpoint := c.endpoint
+		if endpoint == nil {
+			endpoint = &url.URL{Scheme: "https", Host: "s3." + region + ".amazonaws.com"}
+		}
+		target = url.URL{Scheme: endpoint.Scheme, Host: bucket + "." + endpoint.Host}
+		target.Path = "/" + key
+		target.RawPath = "/" + uriEncode(key, false)
+	} else {
+		target = url.URL{Scheme: c.endpoint.Scheme, Host: c.endpoint.Host}
+		target.Path = c.endpoint.Path + "/" + bucket + "/" + key
+		target.RawPath = uriEncode(c.endpoint.Path, false) + "/" + uriEncode(bucket, true) + "/" + uriEncode(key, false)
+	}
+	return &target, region, nil
+}
+
+const (
+	signingAlgorithm = "AWS4-HMAC-SHA256"
+	amzDateFormat    = "20060102T150405Z"
+	unsignedPayload  = "UNSIGNED-PAYLOAD"
+)
+
+// signature signs a request for the region with Signature Version 4
+func (c *client) signature(method string, target *url.URL, headers map[string]string, payloadHash string, now time.Time, region string) string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		target.EscapedPath(),
+		target.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders(headers),
+		payloadHash,
+	}, "\n")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		signingAlgorithm,
+		now.Format(amzDateFormat),
+		credentialScope(now, region),
+		hex.EncodeToString(requestHash[:]),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+c.cfg.SecretAccessKey), now.Format("20060102"))
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+// credentialScope returns the scope a signature made at a time for the region is valid in
+func credentialScope(now time.Time, region string) string {
+	return now.Format("20060102") + "/" + region + "/s3/aws4_request"
+}
+
+// signedHeaders lists the names of the signed headers, sorted and separated by semicolons
+func signedHeaders(headers map[string]string) string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ";")
+}
+
+// canonicalQuery encodes a query string the way it is signed: sorted by name, with every
+// character but the unreserved ones percent-encoded
+func canonicalQuery(query url.Values) string {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		for _, value := range query[name] {
+			pairs = append(pairs, uriEncode(name, true)+"="+uriEncode(value, true))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode percent-encodes every byte but the unreserved characters, and slashes unless
+// encodeSlash is set
+func uriEncode(value string, encodeSlash bool) string {
+	var encoded strings.Builder
+	for i := 0; i < len(value); i++ {
+		b := value[i]
+		switch {
+		case 'A' <= b && b <= 'Z', 'a' <= b && b <= 'z', '0' <= b && b <= '9', b == '-', b == '_', b == '.', b == '~':
+			encoded.WriteByte(b)
+		case b == '/' && !encodeSlash:
+			encoded.WriteByte(b)
+		default:
+			fmt.Fprintf(&encoded, "%%%02X", b)
+		}
+	}
+	return encoded.String()
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of data under the key
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// disabledStore rejects every call because no storage credentials are configured
+type disabledStore struct{}
+
+func (disabledStore) Put(context.Context, Object) error {
+	return ErrNotConfigured
+}
+
+func (disabledStore) Get(context.Context, string, string, int64) ([]byte, error) {
+	return nil, ErrNotConfigured
+}
+
+func (disabledStore) Delete(context.Context, string, string) error {
+	return ErrNotConfigured
+}
+
+func (disabledStore) SignedURL(string, string, string, time.Duration) (string, error) {
+	return "", ErrNotConfigured
+}
+
+func (disabledStore) SignedUploadURL(string, string, string, int64, time.Duration) (string, error) {
+	return "", ErrNotConfigured
+}
+
+func (disabledStore) ObjectURL(string, string) (string, error) {
+	return "", ErrNotConfigured
+}
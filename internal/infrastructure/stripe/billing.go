@@ -0,0 +1,236 @@
+package stripe
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// Customer is the Stripe object a business's subscription is billed to
+type Customer struct {
+	ID string `json:"id"`
+}
+
+// CustomerParams describes a customer to create
+type CustomerParams struct {
+	Email           string
+	Name            string
+	PaymentMethodID string // Attached to the customer and charged by default
+	Metadata        map[string]string
+	IdempotencyKey  string
+}
+
+// Subscription is the Stripe object billing a customer for a price every period
+type Subscription struct {
+	ID                string            `json:"id"`
+	CustomerID        string            `json:"customer"`
+	Status            string            `json:"status"`
+	CurrentPeriodEnd  int64             `json:"current_period_end"`
+	TrialEnd          *int64            `json:"trial_end"`
+	CancelAtPeriodEnd bool              `json:"cancel_at_period_end"`
+	Metadata          map[string]string `json:"metadata"`
+	Items             struct {
+		Data []SubscriptionItem `json:"data"`
+	} `json:"items"`
+}
+
+// SubscriptionItem is a price a subscription bills for
+type SubscriptionItem struct {
+	ID    string `json:"id"`
+	Price struct {
+		ID string `json:"id"`
+	} `json:"price"`
+}
+
+// PriceID returns the price the subscription bills for; subscriptions of businesses have a
+// single item
+func (s *Subscription) PriceID() string {
+	if len(s.Items.Data) == 0 {
+		return ""
+	}
+	return s.Items.Data[0].Price.ID
+}
+
+// State returns the state of the subscription at the time Stripe reported it, with the tier
+// its price stands for
+func (s *Subscription) State(tier domain.SubscriptionTier, reportedAt time.Time) domain.SubscriptionState {
+	state := domain.SubscriptionState{
+		StripeSubscriptionID: s.ID,
+		Tier:                 tier,
+		Status:               domain.SubscriptionStatus(s.Status),
+		TrialEndsAt:          unixTime(s.TrialEnd),
+		CancelAtPeriodEnd:    s.CancelAtPeriodEnd,
+		ReportedAt:           reportedAt,
+	}
+	if s.CurrentPeriodEnd > 0 {
+		state.CurrentPeriodEnd = unixTime(&s.CurrentPeriodEnd)
+	}
+	return state
+}
+
+// SubscriptionParams describes a subscription to create
+type SubscriptionParams struct {
+	CustomerID      string
+	PriceID         string
+	PaymentMethodID string
+	Metadata        map[string]string
+	IdempotencyKey  string
+}
+
+// Billing bills the subscriptions of businesses through Stripe Billing. Stripe charges each
+// period, retries failed payments and reports the outcome through webhooks.
+type Billing interface {
+	CreateCustomer(ctx context.Context, params CustomerParams) (*Customer, error)
+	// AttachPaymentMethod attaches a payment method to a customer and makes it the default
+	AttachPaymentMethod(ctx context.Context, customerID, paymentMethodID string) error
+	// CreateSubscription subscribes a customer to a price, charging the first period at once.
+	// A declined card fails the call rather than leaving an incomplete subscription.
+	CreateSubscription(ctx context.Context, params SubscriptionParams) (*Subscription, error)
+	GetSubscription(ctx context.Context, id string) (*Subscription, error)
+	// ChangeSubscriptionPrice moves a subscription to another price, pro-rating the period, and
+	// takes back a cancellation at the end of the period
+	ChangeSubscriptionPrice(ctx context.Context, subscription *Subscription, priceID string) (*Subscription, error)
+	// CancelSubscription cancels a subscription at the end of the paid period
+	CancelSubscription(ctx context.Context, id string) (*Subscription, error)
+}
+
+// NewBilling creates the billing of the configured Stripe account. Without a secret key every
+// call fails with ErrNotConfigured, so the API runs with every business on the free tier.
+func NewBilling(gateway Gateway) Billing {
+	if c, ok := gateway.(*client); ok {
+		return c
+	}
+	return disabledGateway{}
+}
+
+// CreateCustomer creates a customer with a default payment method
+func (c *client) CreateCustomer(ctx context.Context, params CustomerParams) (*Customer, error) {
+	form := url.Values{}
+	form.Set("email", params.Email)
+	form.Set("name", params.Name)
+	if params.PaymentMethodID != "" {
+		form.Set("payment_method", params.PaymentMethodID)
+		form.Set("invoice_settings[default_payment_method]", params.PaymentMethodID)
+	}
+	for key, value := range params.Metadata {
+		form.Set("metadata["+key+"]", value)
+	}
+
+	var customer Customer
+	if err := c.do(ctx, http.MethodPost, "/customers", form, params.IdempotencyKey, &customer); err != nil {
+		return nil, err
+	}
+	return &customer, nil
+}
+
+// AttachPaymentMethod attaches a payment method to a customer and makes it the default
+func (c *client) AttachPaymentMethod(ctx context.Context, customerID, paymentMethodID string) error {
+	form := url.Values{}
+	form.Set("customer", customerID)
+	var attached struct {
+		ID string `json:"id"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/payment_methods/"+url.PathEscape(paymentMethodID)+"/attach", form, "", &attached); err != nil {
+		return err
+	}
+
+	form = url.Values{}
+	form.Set("invoice_settings[default_payment_method]", paymentMethodID)
+	var customer Customer
+	return c.do(ctx, http.MethodPost, "/customers/"+url.PathEscape(customerID), form, "", &customer)
+}
+
+// CreateSubscription subscribes a customer to a price
+func (c *client) CreateSubscription(ctx context.Context, params SubscriptionParams) (*Subscription, error) {
+	form := url.Values{}
+	form.Set("customer", params.CustomerID)
+	form.Set("items[0][price]", params.PriceID)
+	form.Set("payment_behavior", "error_if_incomplete")
+	if params.PaymentMethodID != "" {
+		form.Set("default_payment_method", params.PaymentMethodID)
+	}
+	for key, value := range params.Metadata {
+		form.Set("metadata["+key+"]", value)
+	}
+
+	var subscription Subscription
+	if err := c.do(ctx, http.MethodPost, "/subscriptions", form, params.IdempotencyKey, &subscription); err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+// GetSubscription retrieves a subscription
+func (c *client) GetSubscription(ctx context.Context, id string) (*Subscription, error) {
+	var subscription Subscription
+	if err := c.do(ctx, http.MethodGet, "/subscriptions/"+url.PathEscape(id), nil, "", &subscription); err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+// ChangeSubscriptionPrice moves a subscription to another price
+func (c *client) ChangeSubscriptionPrice(ctx context.Context, subscription *Subscription, priceID string) (*Subscription, error) {
+	form := url.Values{}
+	if len(subscription.Items.Data) > 0 {
+		form.Set("items[0][id]", subscription.Items.Data[0].ID)
+	}
+	form.Set("items[0][price]", priceID)
+	form.Set("proration_behavior", "create_prorations")
+	form.Set("cancel_at_period_end", "false")
+
+	var changed Subscription
+	if err := c.do(ctx, http.MethodPost, "/subscriptions/"+url.PathEscape(subscription.ID), form, "", &changed); err != nil {
+		return nil, err
+	}
+	return &changed, nil
+}
+
+// CancelSubscription cancels a subscription at the end of the paid period
+func (c *client) CancelSubscription(ctx context.Context, id string) (*Subscription, error) {
+	form := url.Values{}
+	form.Set("cancel_at_period_end", strconv.FormatBool(true))
+
+	var subscription Subscription
+	if err := c.do(ctx, http.MethodPost, "/subscriptions/"+url.PathEscape(id), form, "", &subscription); err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (disabledGateway) CreateCustomer(context.Context, CustomerParams) (*Customer, error) {
+	return nil, ErrNotConfigured
+}
+
+func (disabledGateway) AttachPaymentMethod(context.Context, string, string) error {
+	return ErrNotConfigured
+}
+
+func (disabledGateway) CreateSubscription(context.Context, SubscriptionParams) (*Subscription, error) {
+	return nil, ErrNotConfigured
+}
+
+func (disabledGateway) GetSubscription(context.Context, string) (*Subscription, error) {
+	return nil, ErrNotConfigured
+}
+
+func (disabledGateway) ChangeSubscriptionPrice(context.Context, *Subscription, string) (*Subscription, error) {
+	return nil, ErrNotConfigured
+}
+
+func (disabledGateway) CancelSubscription(context.Context, string) (*Subscription, error) {
+	return nil, ErrNotConfigured
+}
+
+// unixTime converts an optional Unix timestamp
+func unixTime(seconds *int64) *time.Time {
+	if seconds == nil {
+		return nil
+	}
+	t := time.Unix(*seconds, 0).UTC()
+	return &t
+}
@@ -0,0 +1,66 @@
+package stripe
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/assimoes/beautix/configs"
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_CreateSubscription(t *testing.T) {
+	c, closeServer := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/subscriptions", r.URL.Path)
+		assert.Equal(t, "subscription-business-1-pro", r.Header.Get("Idempotency-Key"))
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "cus_123", r.PostForm.Get("customer"))
+		assert.Equal(t, "price_pro", r.PostForm.Get("items[0][price]"))
+		assert.Equal(t, "error_if_incomplete", r.PostForm.Get("payment_behavior"))
+		assert.Equal(t, "business-1", r.PostForm.Get("metadata[business_id]"))
+		_, _ = w.Write([]byte(`{"id": "sub_123", "customer": "cus_123", "status": "active", "current_period_end": 1775044800,
+			"items": {"data": [{"id": "si_123", "price": {"id": "price_pro"}}]}}`))
+	})
+	defer closeServer()
+
+	subscription, err := c.CreateSubscription(context.Background(), SubscriptionParams{
+		CustomerID:     "cus_123",
+		PriceID:        "price_pro",
+		Metadata:       map[string]string{"business_id": "business-1"},
+		IdempotencyKey: "subscription-business-1-pro",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "price_pro", subscription.PriceID())
+
+	reportedAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	state := subscription.State(domain.TierPro, reportedAt)
+	assert.Equal(t, domain.SubscriptionActive, state.Status)
+	assert.Equal(t, time.Unix(1775044800, 0).UTC(), *state.CurrentPeriodEnd)
+	assert.Nil(t, state.TrialEndsAt)
+}
+
+func TestClient_ChangeSubscriptionPrice(t *testing.T) {
+	c, closeServer := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/subscriptions/sub_123", r.URL.Path)
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "si_123", r.PostForm.Get("items[0][id]"))
+		assert.Equal(t, "price_premium", r.PostForm.Get("items[0][price]"))
+		assert.Equal(t, "false", r.PostForm.Get("cancel_at_period_end"))
+		_, _ = w.Write([]byte(`{"id": "sub_123", "status": "active", "items": {"data": [{"id": "si_123", "price": {"id": "price_premium"}}]}}`))
+	})
+	defer closeServer()
+
+	current := &Subscription{ID: "sub_123"}
+	current.Items.Data = []SubscriptionItem{{ID: "si_123"}}
+	changed, err := c.ChangeSubscriptionPrice(context.Background(), current, "price_premium")
+	require.NoError(t, err)
+	assert.Equal(t, "price_premium", changed.PriceID())
+}
+
+func TestNewBilling_NotConfigured(t *testing.T) {
+	_, err := NewBilling(NewGateway(configs.StripeConfig{}, nil, nil)).CancelSubscription(context.Background(), "sub_123")
+	assert.ErrorIs(t, err, ErrNotConfigured)
+}
@@ -0,0 +1,279 @@
+// Package stripe takes card payments for appointments through the Stripe API
+package stripe
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/assimoes/beautix/configs"
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/infrastructure/outbound"
+	"github.com/assimoes/beautix/internal/infrastructure/resilience"
+)
+
+// requestTimeout bounds a single request to the Stripe API
+const requestTimeout = 30 * time.Second
+
+// ProviderName is the name Stripe's health is reported under
+const ProviderName = "stripe"
+
+// Policy protects calls to Stripe. Charges and refunds carry idempotency keys, so retrying
+// them never moves money twice.
+var Policy = resilience.Policy{
+	Timeout:          20 * time.Second,
+	MaxAttempts:      3,
+	BaseBackoff:      500 * time.Millisecond,
+	MaxBackoff:       4 * time.Second,
+	FailureThreshold: 5,
+	OpenDuration:     time.Minute,
+}
+
+// PaymentIntent statuses reported by Stripe
+const (
+	StatusRequiresPaymentMethod = "requires_payment_method"
+	StatusRequiresConfirmation  = "requires_confirmation"
+	StatusRequiresAction        = "requires_action"
+	StatusProcessing            = "processing"
+	StatusRequiresCapture       = "requires_capture"
+	StatusCanceled              = "canceled"
+	StatusSucceeded             = "succeeded"
+)
+
+// ErrNotConfigured is returned by the gateway when no Stripe secret key is set
+var ErrNotConfigured = errors.New("stripe is not configured")
+
+// Error is an error response from the Stripe API
+type Error struct {
+	StatusCode int
+	Type       string `json:"type"`
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+}
+
+// Error describes the failure
+func (e *Error) Error() string {
+	return fmt.Sprintf("stripe rejected the request (status %d): %s", e.StatusCode, e.Message)
+}
+
+// IsCardError reports whether the card was declined or could not be charged, as opposed to
+// the request or the account being at fault
+func (e *Error) IsCardError() bool {
+	return e.Type == "card_error"
+}
+
+// PaymentIntent is the Stripe object tracking a card payment
+type PaymentIntent struct {
+	ID               string `json:"id"`
+	Status           string `json:"status"`
+	Amount           int64  `json:"amount"`
+	Currency         string `json:"currency"`
+	ClientSecret     string `json:"client_secret"` // Lets the client app authenticate the card
+	LastPaymentError *Error `json:"last_payment_error"`
+}
+
+// PaymentStatus maps the Stripe status onto the status of a payment
+func (pi *PaymentIntent) PaymentStatus() domain.PaymentStatus {
+	switch pi.Status {
+	case StatusSucceeded:
+		return domain.PaymentStatusSucceeded
+	case StatusRequiresAction, StatusRequiresConfirmation:
+		return domain.PaymentStatusRequiresAction
+	case StatusRequiresPaymentMethod, StatusCanceled:
+		return domain.PaymentStatusFailed
+	default:
+		return domain.PaymentStatusPending
+	}
+}
+
+// FailureMessage returns why the last charge attempt failed, if it did
+func (pi *PaymentIntent) FailureMessage() string {
+	if pi.LastPaymentError != nil && pi.LastPaymentError.Message != "" {
+		return pi.LastPaymentError.Message
+	}
+	if pi.Status == StatusCanceled {
+		return "the payment was canceled"
+	}
+	return "the card could not be charged"
+}
+
+// ChargeParams describes a card charge
+type ChargeParams struct {
+	Amount          int64 // In the smallest currency unit
+	Currency        string
+	PaymentMethodID string // Collected by the client app with Stripe.js or the mobile SDKs
+	CustomerID      string // The customer the payment method is saved to, for stored cards
+	OffSession      bool   // The client is not present to authenticate, as in recurring billing
+	Description     string
+	Metadata        map[string]string
+	IdempotencyKey  string // Retrying with the same key never charges twice
+}
+
+// Refund is the Stripe object for money returned to the card
+type Refund struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Amount int64  `json:"amount"`
+}
+
+// RefundParams describes a refund of a payment
+type RefundParams struct {
+	PaymentIntentID string
+	Amount          int64 // In the smallest currency unit
+	IdempotencyKey  string
+}
+
+// Gateway takes and refunds card payments
+type Gateway interface {
+	// Charge creates and confirms a payment intent, capturing the money at once
+	Charge(ctx context.Context, params ChargeParams) (*PaymentIntent, error)
+	GetPaymentIntent(ctx context.Context, id string) (*PaymentIntent, error)
+	Refund(ctx context.Context, params RefundParams) (*Refund, error)
+}
+
+// client calls the Stripe REST API
+type client struct {
+	cfg    configs.StripeConfig
+	http   *http.Client
+	apiURL string
+	guard  *resilience.Guard
+}
+
+// NewGateway creates a gateway for the configured Stripe account, with calls protected by the
+// guard and audited by the recorder. Without a secret key every call fails with
+// ErrNotConfigured, so the API runs without card payments.
+func NewGateway(cfg configs.StripeConfig, guard *resilience.Guard, recorder *outbound.Recorder) Gateway {
+	if cfg.SecretKey == "" {
+		return disabledGateway{}
+	}
+	return &client{
+		cfg:    cfg,
+		http:   recorder.Client(ProviderName, requestTimeout),
+		apiURL: strings.TrimRight(cfg.APIURL, "/"),
+		guard:  guard,
+	}
+}
+
+// Charge creates and confirms a payment intent
+func (c *client) Charge(ctx context.Context, params ChargeParams) (*PaymentIntent, error) {
+	form := url.Values{}
+	form.Set("amount", strconv.FormatInt(params.Amount, 10))
+	form.Set("currency", strings.ToLower(params.Currency))
+	form.Set("payment_method", params.PaymentMethodID)
+	form.Set("confirm", "true")
+	// Cards are charged in the API call, so payment methods that redirect the client away
+	// cannot be used
+	form.Set("automatic_payment_methods[enabled]", "true")
+	form.Set("automatic_payment_methods[allow_redirects]", "never")
+	if params.CustomerID != "" {
+		form.Set("customer", params.CustomerID)
+	}
+	if params.OffSession {
+		form.Set("off_session", "true")
+	}
+	if params.Description != "" {
+		form.Set("description", params.Description)
+	}
+	for key, value := range params.Metadata {
+		form.Set("metadata["+key+"]", value)
+	}
+
+	var intent PaymentIntent
+	if err := c.do(ctx, http.MethodPost, "/payment_intents", form, params.IdempotencyKey, &intent); err != nil {
+		return nil, err
+	}
+	return &intent, nil
+}
+
+// GetPaymentIntent retrieves a payment intent
+func (c *client) GetPaymentIntent(ctx context.Context, id string) (*PaymentIntent, error) {
+	var intent PaymentIntent
+	if err := c.do(ctx, http.MethodGet, "/payment_intents/"+url.PathEscape(id), nil, "", &intent); err != nil {
+		return nil, err
+	}
+	return &intent, nil
+}
+
+// Refund refunds all or part of a payment intent
+func (c *client) Refund(ctx context.Context, params RefundParams) (*Refund, error) {
+	form := url.Values{}
+	form.Set("payment_intent", params.PaymentIntentID)
+	form.Set("amount", strconv.FormatInt(params.Amount, 10))
+
+	var refund Refund
+	if err := c.do(ctx, http.MethodPost, "/refunds", form, params.IdempotencyKey, &refund); err != nil {
+		return nil, err
+	}
+	return &refund, nil
+}
+
+// do sends a form-encoded request through the guard and decodes the JSON response into out
+func (c *client) do(ctx context.Context, method, path string, form url.Values, idempotencyKey string, out any) error {
+	return c.guard.Do(ctx, func(ctx context.Context) error {
+		return c.send(ctx, method, path, form, idempotencyKey, out)
+	})
+}
+
+// send makes a single request. Errors Stripe will repeat on retry are marked permanent.
+func (c *client) send(ctx context.Context, method, path string, form url.Values, idempotencyKey string, out any) error {
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.apiURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.cfg.SecretKey, "")
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("stripe request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	decoder := json.NewDecoder(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode >= 300 {
+		var failure struct {
+			Error Error `json:"error"`
+		}
+		_ = decoder.Decode(&failure)
+		failure.Error.StatusCode = resp.StatusCode
+		if failure.Error.Message == "" {
+			failure.Error.Message = resp.Status
+		}
+		if !resilience.IsRetryableStatus(resp.StatusCode) {
+			return resilience.Permanent(&failure.Error)
+		}
+		return &failure.Error
+	}
+	return decoder.Decode(out)
+}
+
+// disabledGateway rejects every call because no Stripe account is configured
+type disabledGateway struct{}
+
+func (disabledGateway) Charge(context.Context, ChargeParams) (*PaymentIntent, error) {
+	return nil, ErrNotConfigured
+}
+
+func (disabledGateway) GetPaymentIntent(context.Context, string) (*PaymentIntent, error) {
+	return nil, ErrNotConfigured
+}
+
+func (disabledGateway) Refund(context.Context, RefundParams) (*Refund, error) {
+	return nil, ErrNotConfigured
+}
@@ -0,0 +1,110 @@
+package stripe
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/assimoes/beautix/configs"
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/infrastructure/resilience"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(handler http.HandlerFunc) (*client, func()) {
+	server := httptest.NewServer(handler)
+	return &client{cfg: configs.StripeConfig{SecretKey: "sk_test_123"}, http: server.Client(), apiURL: server.URL}, server.Close
+}
+
+func TestClient_Charge(t *testing.T) {
+	c, closeServer := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/payment_intents", r.URL.Path)
+		assert.Equal(t, "payment-1", r.Header.Get("Idempotency-Key"))
+		user, _, _ := r.BasicAuth()
+		assert.Equal(t, "sk_test_123", user)
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "2500", r.PostForm.Get("amount"))
+		assert.Equal(t, "eur", r.PostForm.Get("currency"))
+		assert.Equal(t, "true", r.PostForm.Get("confirm"))
+		assert.Equal(t, "appointment-1", r.PostForm.Get("metadata[appointment_id]"))
+
+		if r.PostForm.Get("payment_method") == "pm_card_chargeDeclined" {
+			w.WriteHeader(http.StatusPaymentRequired)
+			_, _ = w.Write([]byte(`{"error": {"type": "card_error", "code": "card_declined", "message": "Your card was declined."}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id": "pi_123", "status": "succeeded", "amount": 2500, "currency": "eur", "client_secret": "pi_123_secret"}`))
+	})
+	defer closeServer()
+
+	params := ChargeParams{
+		Amount:          2500,
+		Currency:        "EUR",
+		PaymentMethodID: "pm_card_visa",
+		Metadata:        map[string]string{"appointment_id": "appointment-1"},
+		IdempotencyKey:  "payment-1",
+	}
+	intent, err := c.Charge(context.Background(), params)
+	require.NoError(t, err)
+	assert.Equal(t, "pi_123", intent.ID)
+	assert.Equal(t, domain.PaymentStatusSucceeded, intent.PaymentStatus())
+
+	params.PaymentMethodID = "pm_card_chargeDeclined"
+	_, err = c.Charge(context.Background(), params)
+	var stripeErr *Error
+	require.True(t, errors.As(err, &stripeErr))
+	assert.True(t, stripeErr.IsCardError())
+	assert.True(t, resilience.IsPermanent(err), "declined cards are not retried")
+	assert.Equal(t, http.StatusPaymentRequired, stripeErr.StatusCode)
+	assert.Equal(t, "Your card was declined.", stripeErr.Message)
+}
+
+func TestClient_ChargeOffSession(t *testing.T) {
+	c, closeServer := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "cus_123", r.PostForm.Get("customer"))
+		assert.Equal(t, "true", r.PostForm.Get("off_session"))
+		_, _ = w.Write([]byte(`{"id": "pi_456", "status": "succeeded", "amount": 4000, "currency": "eur"}`))
+	})
+	defer closeServer()
+
+	intent, err := c.Charge(context.Background(), ChargeParams{
+		Amount:          4000,
+		Currency:        "EUR",
+		PaymentMethodID: "pm_card_visa",
+		CustomerID:      "cus_123",
+		OffSession:      true,
+		IdempotencyKey:  "membership-invoice-1-1",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "pi_456", intent.ID)
+}
+
+func TestClient_Refund(t *testing.T) {
+	c, closeServer := newTestClient(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/refunds", r.URL.Path)
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "pi_123", r.PostForm.Get("payment_intent"))
+		assert.Equal(t, "1000", r.PostForm.Get("amount"))
+		_, _ = w.Write([]byte(`{"id": "re_123", "status": "succeeded", "amount": 1000}`))
+	})
+	defer closeServer()
+
+	refund, err := c.Refund(context.Background(), RefundParams{PaymentIntentID: "pi_123", Amount: 1000, IdempotencyKey: "payment-1-refund-10.00"})
+	require.NoError(t, err)
+	assert.Equal(t, "re_123", refund.ID)
+}
+
+func TestPaymentIntent_PaymentStatus(t *testing.T) {
+	assert.Equal(t, domain.PaymentStatusRequiresAction, (&PaymentIntent{Status: StatusRequiresAction}).PaymentStatus())
+	assert.Equal(t, domain.PaymentStatusPending, (&PaymentIntent{Status: StatusProcessing}).PaymentStatus())
+	assert.Equal(t, domain.PaymentStatusFailed, (&PaymentIntent{Status: StatusRequiresPaymentMethod}).PaymentStatus())
+}
+
+func TestNewGateway_NotConfigured(t *testing.T) {
+	_, err := NewGateway(configs.StripeConfig{}, nil, nil).Charge(context.Background(), ChargeParams{})
+	assert.ErrorIs(t, err, ErrNotConfigured)
+}
@@ -0,0 +1,107 @@
+package stripe
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureHeader is the header Stripe signs its webhook events in
+const SignatureHeader = "Stripe-Signature"
+
+// webhookTolerance is how old a signed event may be, which bounds replays of captured events
+const webhookTolerance = 5 * time.Minute
+
+// Subscription events Stripe sends as a subscription changes
+const (
+	EventSubscriptionCreated = "customer.subscription.created"
+	EventSubscriptionUpdated = "customer.subscription.updated"
+	EventSubscriptionDeleted = "customer.subscription.deleted"
+)
+
+// ErrInvalidSignature is returned for webhook events not signed with the endpoint's secret, or
+// signed too long ago
+var ErrInvalidSignature = errors.New("invalid stripe webhook signature")
+
+// Event is a webhook event Stripe posts when something changes on the account
+type Event struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Created int64  `json:"created"`
+	Data    struct {
+		Object json.RawMessage `json:"object"`
+	} `json:"data"`
+}
+
+// CreatedAt returns when the event happened
+func (e *Event) CreatedAt() time.Time {
+	return time.Unix(e.Created, 0).UTC()
+}
+
+// Subscription decodes the subscription a subscription event is about
+func (e *Event) Subscription() (*Subscription, error) {
+	var subscription Subscription
+	if err := json.Unmarshal(e.Data.Object, &subscription); err != nil {
+		return nil, fmt.Errorf("failed to decode the subscription of event %s: %w", e.ID, err)
+	}
+	return &subscription, nil
+}
+
+// ParseWebhook verifies the signature of a webhook event and decodes it. The header holds the
+// time the event was signed and one or more signatures, as in "t=1700000000,v1=5257a8...".
+func ParseWebhook(payload []byte, header, secret string, now time.Time) (*Event, error) {
+	if secret == "" {
+		return nil, ErrNotConfigured
+	}
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	signedAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return nil, ErrInvalidSignature
+	}
+	if age := now.Sub(time.Unix(signedAt, 0)); age > webhookTolerance || age < -webhookTolerance {
+		return nil, ErrInvalidSignature
+	}
+
+	expected := sign(payload, timestamp, secret)
+	verified := false
+	for _, signature := range signatures {
+		if given, err := hex.DecodeString(signature); err == nil && hmac.Equal(given, expected) {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, ErrInvalidSignature
+	}
+
+	var event Event
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("failed to decode stripe webhook event: %w", err)
+	}
+	return &event, nil
+}
+
+// sign computes the signature of a payload signed at a time, as Stripe does
+func sign(payload []byte, timestamp, secret string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
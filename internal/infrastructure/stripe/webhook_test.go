@@ -0,0 +1,49 @@
+package stripe
+
+import (
+	"encoding/hex"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func signedHeader(payload []byte, at time.Time, secret string) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(sign(payload, timestamp, secret))
+}
+
+func TestParseWebhook(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	payload := []byte(`{"id": "evt_1", "type": "customer.subscription.updated", "created": 1772366400,
+		"data": {"object": {"id": "sub_1", "status": "active", "current_period_end": 1775044800,
+		"items": {"data": [{"id": "si_1", "price": {"id": "price_pro"}}]}, "metadata": {"business_id": "business-1"}}}}`)
+
+	event, err := ParseWebhook(payload, signedHeader(payload, now, "whsec_1"), "whsec_1", now)
+	require.NoError(t, err)
+	assert.Equal(t, EventSubscriptionUpdated, event.Type)
+	assert.Equal(t, now, event.CreatedAt())
+
+	subscription, err := event.Subscription()
+	require.NoError(t, err)
+	assert.Equal(t, "sub_1", subscription.ID)
+	assert.Equal(t, "price_pro", subscription.PriceID())
+	assert.Equal(t, "business-1", subscription.Metadata["business_id"])
+
+	_, err = ParseWebhook(payload, signedHeader(payload, now, "whsec_other"), "whsec_1", now)
+	assert.ErrorIs(t, err, ErrInvalidSignature, "signed with another secret")
+
+	_, err = ParseWebhook(payload, signedHeader(payload, now.Add(-10*time.Minute), "whsec_1"), "whsec_1", now)
+	assert.ErrorIs(t, err, ErrInvalidSignature, "signed too long ago")
+
+	_, err = ParseWebhook([]byte(`{"id": "evt_forged"}`), signedHeader(payload, now, "whsec_1"), "whsec_1", now)
+	assert.ErrorIs(t, err, ErrInvalidSignature, "payload changed after signing")
+
+	_, err = ParseWebhook(payload, "", "whsec_1", now)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	_, err = ParseWebhook(payload, signedHeader(payload, now, ""), "", now)
+	assert.ErrorIs(t, err, ErrNotConfigured)
+}
@@ -0,0 +1,104 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// queryStateKey stores the span and start of a query on its statement
+const queryStateKey = "telemetry:query"
+
+// queryState is what the callbacks before a query leave for those after it
+type queryState struct {
+	parent context.Context
+	span   trace.Span
+	start  time.Time
+}
+
+// gormPlugin traces database queries in client spans and counts them
+type gormPlugin struct{}
+
+// NewGORMPlugin returns a GORM plugin tracing and counting the queries made through a database
+func NewGORMPlugin() gorm.Plugin {
+	return gormPlugin{}
+}
+
+// Name returns the name of the plugin
+func (gormPlugin) Name() string { return "telemetry" }
+
+// Initialize registers the callbacks around every kind of query
+func (p gormPlugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	return errors.Join(
+		p.register("create", callbacks.Create().Before("gorm:create"), callbacks.Create().After("gorm:create")),
+		p.register("query", callbacks.Query().Before("gorm:query"), callbacks.Query().After("gorm:query")),
+		p.register("update", callbacks.Update().Before("gorm:update"), callbacks.Update().After("gorm:update")),
+		p.register("delete", callbacks.Delete().Before("gorm:delete"), callbacks.Delete().After("gorm:delete")),
+		p.register("row", callbacks.Row().Before("gorm:row"), callbacks.Row().After("gorm:row")),
+		p.register("raw", callbacks.Raw().Before("gorm:raw"), callbacks.Raw().After("gorm:raw")),
+	)
+}
+
+// callbackRegistrar registers a callback at a position among those of a processor
+type callbackRegistrar interface {
+	Register(name string, fn func(*gorm.DB)) error
+}
+
+// register registers the callbacks of an operation around the query GORM makes for it
+func (p gormPlugin) register(operation string, before, after callbackRegistrar) error {
+	if err := before.Register("telemetry:before_"+operation, p.before(operation)); err != nil {
+		return err
+	}
+	return after.Register("telemetry:after_"+operation, p.after(operation))
+}
+
+// before starts the span of a query as a child of the span in the statement's context
+func (gormPlugin) before(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		parent := db.Statement.Context
+		if parent == nil {
+			parent = context.Background()
+		}
+		ctx, span := Tracer().Start(parent, "db."+operation, trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(attribute.String("db.system", "postgresql"), attribute.String("db.operation", operation)))
+		db.Statement.Context = ctx
+		db.InstanceSet(queryStateKey, &queryState{parent: parent, span: span, start: time.Now()})
+	}
+}
+
+// after ends the span of a query and counts it. Queries finding no record are not failures.
+func (gormPlugin) after(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(queryStateKey)
+		if !ok {
+			return
+		}
+		state := value.(*queryState)
+		db.Statement.Context = state.parent
+
+		table := db.Statement.Table
+		failed := db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound)
+		state.span.SetAttributes(
+			attribute.String("db.sql.table", table),
+			attribute.String("db.statement", db.Statement.SQL.String()), // With placeholders, never values
+			attribute.Int64("db.rows_affected", db.RowsAffected),
+		)
+		if failed {
+			state.span.RecordError(db.Error)
+			state.span.SetStatus(codes.Error, db.Error.Error())
+		}
+		state.span.End()
+
+		if table == "" {
+			table = "unknown"
+		}
+		dbQueries.WithLabelValues(operation, table, outcome(failed)).Inc()
+		dbQueryDuration.WithLabelValues(operation).Observe(time.Since(state.start).Seconds())
+	}
+}
@@ -0,0 +1,57 @@
+package telemetry
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sampleCount returns how many observations a histogram has under the labels
+func sampleCount(t *testing.T, vec *prometheus.HistogramVec, labels ...string) uint64 {
+	t.Helper()
+	var metric dto.Metric
+	require.NoError(t, vec.WithLabelValues(labels...).(prometheus.Histogram).Write(&metric))
+	return metric.GetHistogram().GetSampleCount()
+}
+
+func TestMiddleware_LabelsRequestsByRoute(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /test/items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	})
+	handler := Middleware(mux)
+
+	before := sampleCount(t, httpRequestDuration, "GET", "GET /test/items/{id}", "202")
+	for _, path := range []string{"/test/items/1", "/test/items/2"} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+	}
+	assert.Equal(t, before+2, sampleCount(t, httpRequestDuration, "GET", "GET /test/items/{id}", "202"))
+}
+
+func TestMiddleware_UnmatchedRoute(t *testing.T) {
+	handler := Middleware(http.NewServeMux())
+
+	before := sampleCount(t, httpRequestDuration, "GET", unmatchedRoute, "404")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/test/unknown/123", nil))
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, before+1, sampleCount(t, httpRequestDuration, "GET", unmatchedRoute, "404"))
+}
+
+func TestMetricsHandler(t *testing.T) {
+	ObserveResolver("Query", "testField", 0, false)
+
+	rec := httptest.NewRecorder()
+	MetricsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `beautix_graphql_resolver_duration_seconds_count{field="testField",outcome="ok",type="Query"} 1`)
+}
@@ -0,0 +1,81 @@
+package telemetry
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// metricsNamespace prefixes the name of every metric of the API
+const metricsNamespace = "beautix"
+
+// registry holds the metrics of the API, besides those of the Go runtime and the process
+var registry = prometheus.NewRegistry()
+
+var (
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latency of HTTP requests by method, route and status code",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	resolverDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "graphql_resolver_duration_seconds",
+		Help:      "Duration of GraphQL resolvers by parent type, field and outcome",
+		Buckets:   []float64{.001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"type", "field", "outcome"})
+
+	dbQueries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "db_queries_total",
+		Help:      "Database queries by operation, table and outcome",
+	}, []string{"operation", "table", "outcome"})
+
+	dbQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Duration of database queries by operation",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"operation"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequestDuration,
+		resolverDuration,
+		dbQueries,
+		dbQueryDuration,
+	)
+}
+
+// MetricsHandler serves the metrics in the Prometheus exposition format
+func MetricsHandler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// RegisterDBStats exports the statistics of the connection pool of a database: connections open,
+// in use and idle, and how often and how long queries waited for one
+func RegisterDBStats(db *sql.DB, name string) error {
+	return registry.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+// ObserveResolver records the duration of a GraphQL resolver
+func ObserveResolver(parentType, field string, duration time.Duration, failed bool) {
+	resolverDuration.WithLabelValues(parentType, field, outcome(failed)).Observe(duration.Seconds())
+}
+
+// outcome labels whether an operation failed
+func outcome(failed bool) string {
+	if failed {
+		return "error"
+	}
+	return "ok"
+}
@@ -0,0 +1,54 @@
+package telemetry
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// RequestIDHeader carries the ID of a request, from callers that set their own and back to
+// every caller in the response
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDPattern is what a request ID given by a caller must look like to be kept, so that
+// IDs cannot inject anything into logs
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:\-]{1,128}$`)
+
+// requestIDKey keeps the ID of a request in its context
+type requestIDKey struct{}
+
+// RequestIDMiddleware gives every request an ID, keeping the one the caller sent if it is
+// well-formed, and returns it in the X-Request-ID response header. The request's context carries
+// the ID and a logger adding it to every entry, so errors a client reports can be found in the logs.
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !requestIDPattern.MatchString(id) {
+			id = uuid.NewString()
+		}
+		w.Header().Set(RequestIDHeader, id)
+
+		logger := log.With().Str("request_id", id).Logger()
+		ctx := logger.WithContext(context.WithValue(r.Context(), requestIDKey{}, id))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequestID returns the ID of the request a context belongs to, or an empty string outside requests
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// Logger returns the logger of the request a context belongs to, or the global logger outside
+// requests
+func Logger(ctx context.Context) *zerolog.Logger {
+	if logger := zerolog.Ctx(ctx); logger.GetLevel() != zerolog.Disabled {
+		return logger
+	}
+	return &log.Logger
+}
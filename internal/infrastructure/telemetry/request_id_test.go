@@ -0,0 +1,66 @@
+package telemetry
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestIDMiddleware_KeepsCallerID(t *testing.T) {
+	var seen string
+	handler := RequestIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = RequestID(r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(RequestIDHeader, "client-req.42")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, "client-req.42", seen)
+	assert.Equal(t, "client-req.42", rec.Header().Get(RequestIDHeader))
+}
+
+func TestRequestIDMiddleware_GeneratesID(t *testing.T) {
+	var seen string
+	handler := RequestIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = RequestID(r.Context())
+	}))
+
+	for _, given := range []string{"", "bad id\nwith newline"} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(RequestIDHeader, given)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		require.NotEmpty(t, seen)
+		assert.NotEqual(t, given, seen)
+		assert.Equal(t, seen, rec.Header().Get(RequestIDHeader))
+	}
+}
+
+func TestRequestIDMiddleware_LogsID(t *testing.T) {
+	var out bytes.Buffer
+	previous := log.Logger
+	log.Logger = zerolog.New(&out)
+	defer func() { log.Logger = previous }()
+
+	handler := RequestIDMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Logger(r.Context()).Info().Msg("handled")
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(RequestIDHeader, "req-1")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Contains(t, out.String(), `"request_id":"req-1"`)
+}
+
+func TestLogger_OutsideRequests(t *testing.T) {
+	assert.Same(t, &log.Logger, Logger(t.Context()))
+}
@@ -0,0 +1,57 @@
+// Package telemetry traces requests through the API with OpenTelemetry and keeps Prometheus
+// metrics of their latency, of GraphQL resolvers and of database queries
+package telemetry
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/assimoes/beautix/configs"
+)
+
+// instrumentationName names the tracer the API's spans are started with
+const instrumentationName = "github.com/assimoes/beautix"
+
+// Setup installs the global tracer provider. Spans are exported over OTLP when enabled in the
+// configuration and dropped otherwise, so instrumented code never needs to check. Trace context
+// is propagated with the W3C headers either way. The returned function flushes the spans not yet
+// exported and must be called on shutdown.
+func Setup(ctx context.Context, config configs.TelemetryConfig, version string) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	if !config.OTLPEnabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	var opts []otlptracehttp.Option
+	if config.OTLPEndpoint != "" {
+		opts = append(opts, otlptracehttp.WithEndpointURL(config.OTLPEndpoint))
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", config.ServiceName),
+			attribute.String("service.version", version),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Tracer returns the tracer of the API, from the global tracer provider
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
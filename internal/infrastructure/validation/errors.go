@@ -9,6 +9,7 @@ import (
 type ValidationError struct {
 	Message string `json:"message"`
 	Field   string `json:"field,omitempty"`
+	Cause   error  `json:"-"` // The error the message was taken from, such as the validator's field errors
 }
 
 // Error implements the error interface
@@ -26,6 +27,20 @@ func NewValidationError(message string) *ValidationError {
 	}
 }
 
+// FromError creates a new validation error with the message of an error, keeping the error so
+// the fields it names can be reported to clients
+func FromError(err error) *ValidationError {
+	return &ValidationError{
+		Message: err.Error(),
+		Cause:   err,
+	}
+}
+
+// Unwrap returns the error the message was taken from
+func (e ValidationError) Unwrap() error {
+	return e.Cause
+}
+
 // NewFieldValidationError creates a new validation error for a specific field
 func NewFieldValidationError(field, message string) *ValidationError {
 	return &ValidationError{
@@ -0,0 +1,211 @@
+// Package webhook delivers domain events to the webhook subscriptions of businesses. The
+// Dispatcher subscribes to the outbox relay, turning each event into a delivery per matching
+// subscription, and sends the deliveries as signed JSON requests, retrying failed ones with
+// backoff and logging the outcome of each attempt.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/infrastructure/lifecycle"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+const (
+	// SubscriberName is the name the dispatcher subscribes to the event relay with
+	SubscriberName = "webhooks"
+	// deliveryBatchSize bounds the deliveries attempted in a single run
+	deliveryBatchSize = 100
+	// deliveryTimeout bounds a single delivery request
+	deliveryTimeout = 10 * time.Second
+	// userAgent identifies webhook requests to the receiving endpoints
+	userAgent = "Beautix-Webhooks/1.0"
+)
+
+// DeliveryRun summarizes a run delivering webhooks
+type DeliveryRun struct {
+	Due       int
+	Delivered int
+	Failed    int // To be retried, or failed for good
+}
+
+// Dispatcher delivers events to webhook subscriptions
+type Dispatcher struct {
+	repo   domain.WebhookRepository
+	client *http.Client
+	now    func() time.Time
+	wake   chan struct{}
+}
+
+// NewDispatcher creates a dispatcher sending deliveries with client. A nil client uses one
+// that times out after ten seconds and does not follow redirects.
+func NewDispatcher(repo domain.WebhookRepository, client *http.Client) *Dispatcher {
+	if client == nil {
+		client = &http.Client{
+			Timeout: deliveryTimeout,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+	}
+	return &Dispatcher{repo: repo, client: client, now: time.Now, wake: make(chan struct{}, 1)}
+}
+
+// Name returns the name the dispatcher subscribes to the event relay with
+func (d *Dispatcher) Name() string { return SubscriberName }
+
+// Handle creates a delivery of the event to every active subscription of its business that
+// receives its type. Handling an event again creates no duplicate deliveries.
+func (d *Dispatcher) Handle(ctx context.Context, event *domain.DomainEvent) error {
+	eventType, ok := domain.WebhookEventFor(event)
+	if !ok || event.BusinessID == nil {
+		return nil
+	}
+	subscriptions, err := d.repo.FindByBusiness(ctx, *event.BusinessID)
+	if err != nil {
+		return err
+	}
+
+	var deliveries []*domain.WebhookDelivery
+	for _, subscription := range subscriptions {
+		if !subscription.Receives(eventType) {
+			continue
+		}
+		payload, err := domain.NewWebhookPayload(event, eventType)
+		if err != nil {
+			return err
+		}
+		deliveries = append(deliveries, &domain.WebhookDelivery{
+			BusinessID:     subscription.BusinessID,
+			SubscriptionID: subscription.ID,
+			EventID:        event.ID,
+			EventType:      eventType,
+			Payload:        payload,
+			Status:         domain.WebhookDeliveryPending,
+			NextAttemptAt:  d.now(),
+		})
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+	if err := d.repo.EnqueueDeliveries(ctx, deliveries); err != nil {
+		return err
+	}
+	d.notify()
+	return nil
+}
+
+// DeliverDue attempts the deliveries that are due
+func (d *Dispatcher) DeliverDue(ctx context.Context, now time.Time) (*DeliveryRun, error) {
+	deliveries, err := d.repo.FindDueDeliveries(ctx, now, deliveryBatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	run := &DeliveryRun{Due: len(deliveries)}
+	subscriptions := make(map[string]*domain.WebhookSubscription)
+	for _, delivery := range deliveries {
+		subscription, ok := subscriptions[delivery.SubscriptionID]
+		if !ok {
+			subscription, err = d.repo.GetByID(ctx, delivery.SubscriptionID)
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return run, err
+			}
+			subscriptions[delivery.SubscriptionID] = subscription
+		}
+
+		if subscription == nil || !subscription.IsActive {
+			delivery.Abandon(errors.New("the webhook subscription was deleted or deactivated"))
+			run.Failed++
+		} else if d.deliver(ctx, subscription, delivery, now) {
+			run.Delivered++
+		} else {
+			run.Failed++
+		}
+		if err := d.repo.UpdateDelivery(ctx, delivery); err != nil {
+			log.Error().Err(err).Str("delivery_id", delivery.ID).Msg("Failed to record webhook delivery")
+		}
+	}
+	return run, nil
+}
+
+// Start delivers webhooks until the context is done, every interval and whenever events are
+// enqueued. The batch in flight is finished with lifecycle.Work(ctx).
+func (d *Dispatcher) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	work := lifecycle.Work(ctx)
+	for {
+		for {
+			run, err := d.DeliverDue(work, d.now())
+			if err != nil {
+				if work.Err() == nil {
+					log.Error().Err(err).Msg("Failed to deliver webhooks")
+				}
+				break
+			}
+			// Once stopped, only the batch in flight is finished
+			if run.Due < deliveryBatchSize || ctx.Err() != nil {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+	}
+}
+
+// deliver sends a delivery to its subscription's endpoint and records the outcome, reporting
+// whether the endpoint acknowledged it
+func (d *Dispatcher) deliver(ctx context.Context, subscription *domain.WebhookSubscription, delivery *domain.WebhookDelivery, now time.Time) bool {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		delivery.Abandon(err)
+		return false
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(domain.WebhookEventHeader, delivery.EventType)
+	req.Header.Set(domain.WebhookDeliveryHeader, delivery.ID)
+	req.Header.Set(domain.WebhookSignatureHeader, domain.SignWebhookPayload(subscription.Secret, now, body))
+
+	started := time.Now()
+	resp, err := d.client.Do(req)
+	duration := time.Since(started)
+	if err != nil {
+		delivery.RecordFailure(nil, err, duration, now)
+		return false
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		statusCode := resp.StatusCode
+		delivery.RecordFailure(&statusCode, fmt.Errorf("the endpoint responded with status %d", statusCode), duration, now)
+		return false
+	}
+	delivery.MarkDelivered(resp.StatusCode, duration, now)
+	return true
+}
+
+// notify wakes a started dispatcher, so that new deliveries go out without waiting for the
+// next poll
+func (d *Dispatcher) notify() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
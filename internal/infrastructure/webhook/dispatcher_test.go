@@ -0,0 +1,134 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/repository/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// endpoint is a webhook receiver responding with status and recording the requests it gets
+type endpoint struct {
+	mu       sync.Mutex
+	status   int
+	requests []*http.Request
+	bodies   []string
+}
+
+func (e *endpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	body, _ := io.ReadAll(r.Body)
+	e.requests = append(e.requests, r)
+	e.bodies = append(e.bodies, string(body))
+	w.WriteHeader(e.status)
+}
+
+func setup(t *testing.T, handler http.Handler) (*Dispatcher, domain.WebhookRepository, *domain.WebhookSubscription) {
+	t.Helper()
+	server := httptest.NewTLSServer(handler)
+	t.Cleanup(server.Close)
+
+	repo := memory.NewWebhookRepository(memory.NewDB())
+	subscription := &domain.WebhookSubscription{BusinessID: "business-1", URL: server.URL + "/hooks", Secret: "whsec_test", IsActive: true}
+	require.NoError(t, subscription.SetEventTypes([]string{domain.WebhookEventAppointmentCreated}))
+	require.NoError(t, repo.Create(context.Background(), subscription))
+	return NewDispatcher(repo, server.Client()), repo, subscription
+}
+
+func newEvent(t *testing.T, eventType string) *domain.DomainEvent {
+	t.Helper()
+	businessID := "business-1"
+	event, err := domain.NewDomainEvent(domain.AggregateAppointment, "appointment-1", eventType, &businessID,
+		map[string]any{"appointment_id": "appointment-1"})
+	require.NoError(t, err)
+	event.ID = "event-" + eventType
+	event.OccurredAt = time.Date(2026, time.October, 17, 8, 30, 0, 0, time.UTC)
+	return event
+}
+
+func TestDispatcher_Handle(t *testing.T) {
+	ctx := context.Background()
+	dispatcher, repo, subscription := setup(t, &endpoint{status: http.StatusOK})
+
+	require.NoError(t, dispatcher.Handle(ctx, newEvent(t, domain.EventAppointmentCreated)))
+	require.NoError(t, dispatcher.Handle(ctx, newEvent(t, domain.EventAppointmentCreated)))
+	require.NoError(t, dispatcher.Handle(ctx, newEvent(t, domain.EventAppointmentCancelled)))
+
+	deliveries, total, err := repo.FindDeliveries(ctx, subscription.ID, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total, "events are delivered once, and only when subscribed to")
+	assert.Equal(t, domain.WebhookEventAppointmentCreated, deliveries[0].EventType)
+	assert.True(t, deliveries[0].IsPending())
+}
+
+func TestDispatcher_DeliverDue(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, time.October, 17, 9, 0, 0, 0, time.UTC)
+	receiver := &endpoint{status: http.StatusServiceUnavailable}
+	dispatcher, repo, subscription := setup(t, receiver)
+	dispatcher.now = func() time.Time { return now }
+	require.NoError(t, dispatcher.Handle(ctx, newEvent(t, domain.EventAppointmentCreated)))
+
+	t.Run("retries failed deliveries with backoff", func(t *testing.T) {
+		run, err := dispatcher.DeliverDue(ctx, now)
+		require.NoError(t, err)
+		assert.Equal(t, &DeliveryRun{Due: 1, Failed: 1}, run)
+
+		deliveries, _, err := repo.FindDeliveries(ctx, subscription.ID, 1, 10)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		assert.True(t, deliveries[0].IsPending())
+		assert.Equal(t, http.StatusServiceUnavailable, *deliveries[0].StatusCode)
+		assert.Equal(t, now.Add(domain.WebhookRetryDelay(1)), deliveries[0].NextAttemptAt)
+
+		run, err = dispatcher.DeliverDue(ctx, now.Add(time.Second))
+		require.NoError(t, err)
+		assert.Zero(t, run.Due, "the delivery waits out its backoff")
+	})
+
+	t.Run("sends signed requests", func(t *testing.T) {
+		receiver.status = http.StatusNoContent
+		at := now.Add(domain.WebhookRetryDelay(1))
+		run, err := dispatcher.DeliverDue(ctx, at)
+		require.NoError(t, err)
+		assert.Equal(t, &DeliveryRun{Due: 1, Delivered: 1}, run)
+
+		require.Len(t, receiver.requests, 2)
+		request, body := receiver.requests[1], receiver.bodies[1]
+		assert.Equal(t, receiver.bodies[0], body, "retries send the same body")
+		assert.Equal(t, domain.WebhookEventAppointmentCreated, request.Header.Get(domain.WebhookEventHeader))
+		assert.Equal(t, domain.SignWebhookPayload(subscription.Secret, at, []byte(body)), request.Header.Get(domain.WebhookSignatureHeader))
+		assert.JSONEq(t, `{"id":"event-appointment.created","type":"appointment.created","business_id":"business-1",`+
+			`"occurred_at":"2026-10-17T08:30:00Z","data":{"appointment_id":"appointment-1"}}`, body)
+
+		deliveries, _, err := repo.FindDeliveries(ctx, subscription.ID, 1, 10)
+		require.NoError(t, err)
+		assert.Equal(t, domain.WebhookDeliveryDelivered, deliveries[0].Status)
+		assert.Equal(t, request.Header.Get(domain.WebhookDeliveryHeader), deliveries[0].ID)
+	})
+
+	t.Run("abandons deliveries of deleted subscriptions", func(t *testing.T) {
+		event := newEvent(t, domain.EventAppointmentCreated)
+		event.ID = "event-2"
+		require.NoError(t, dispatcher.Handle(ctx, event))
+		require.NoError(t, repo.Delete(ctx, subscription.ID))
+
+		run, err := dispatcher.DeliverDue(ctx, now)
+		require.NoError(t, err)
+		assert.Equal(t, &DeliveryRun{Due: 1, Failed: 1}, run)
+		assert.Len(t, receiver.requests, 2, "nothing is sent to deleted subscriptions")
+
+		run, err = dispatcher.DeliverDue(ctx, now.Add(24*time.Hour))
+		require.NoError(t, err)
+		assert.Zero(t, run.Due)
+	})
+}
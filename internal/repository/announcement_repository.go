@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// announcementRepositoryImpl implements the AnnouncementRepository interface
+type announcementRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.Announcement]
+}
+
+// NewAnnouncementRepository creates a new announcement repository
+func NewAnnouncementRepository(db *gorm.DB) domain.AnnouncementRepository {
+	return &announcementRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.Announcement]{db: db},
+	}
+}
+
+// FindAll finds every announcement, including scheduled and expired ones, latest first
+func (r *announcementRepositoryImpl) FindAll(ctx context.Context, page, pageSize int) ([]*domain.Announcement, int64, error) {
+	query := conn(ctx, r.db).Model(&domain.Announcement{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var announcements []*domain.Announcement
+	err := query.Order("publish_at DESC, id").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&announcements).Error
+	return announcements, total, err
+}
+
+// FindLive finds the announcements live at a time, most recently published first
+func (r *announcementRepositoryImpl) FindLive(ctx context.Context, now time.Time) ([]*domain.Announcement, error) {
+	var announcements []*domain.Announcement
+	err := conn(ctx, r.db).
+		Where("publish_at <= ? AND (expires_at IS NULL OR expires_at > ?)", now, now).
+		Order("publish_at DESC, id").
+		Find(&announcements).Error
+	return announcements, err
+}
+
+// FindReads finds when a user read the given announcements, by announcement ID
+func (r *announcementRepositoryImpl) FindReads(ctx context.Context, userID string, announcementIDs []string) (map[string]time.Time, error) {
+	reads := make(map[string]time.Time)
+	if len(announcementIDs) == 0 {
+		return reads, nil
+	}
+
+	var rows []*domain.AnnouncementRead
+	err := conn(ctx, r.db).
+		Where("user_id = ? AND announcement_id IN ?", userID, announcementIDs).
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		reads[row.AnnouncementID] = row.ReadAt
+	}
+	return reads, nil
+}
+
+// MarkRead records that a user read announcements, returning how many were unread. Reads
+// already recorded keep their original time.
+func (r *announcementRepositoryImpl) MarkRead(ctx context.Context, userID string, announcementIDs []string, at time.Time) (int64, error) {
+	if len(announcementIDs) == 0 {
+		return 0, nil
+	}
+	reads := make([]*domain.AnnouncementRead, len(announcementIDs))
+	for i, id := range announcementIDs {
+		reads[i] = &domain.AnnouncementRead{AnnouncementID: id, UserID: userID, ReadAt: at}
+	}
+	result := conn(ctx, r.db).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&reads)
+	return result.RowsAffected, result.Error
+}
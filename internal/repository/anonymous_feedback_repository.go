@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// anonymousFeedbackRepositoryImpl implements the AnonymousFeedbackRepository interface
+type anonymousFeedbackRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewAnonymousFeedbackRepository creates a new anonymous feedback repository
+func NewAnonymousFeedbackRepository(db *gorm.DB) domain.AnonymousFeedbackRepository {
+	return &anonymousFeedbackRepositoryImpl{db: db}
+}
+
+// FindTokenByAppointment finds the feedback token issued for an appointment
+func (r *anonymousFeedbackRepositoryImpl) FindTokenByAppointment(ctx context.Context, appointmentID string) (*domain.FeedbackToken, error) {
+	var token domain.FeedbackToken
+	err := conn(ctx, r.db).Where("appointment_id = ?", appointmentID).First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// FindTokenByToken finds a feedback token by the token in its link
+func (r *anonymousFeedbackRepositoryImpl) FindTokenByToken(ctx context.Context, token string) (*domain.FeedbackToken, error) {
+	var feedbackToken domain.FeedbackToken
+	err := conn(ctx, r.db).Where("token = ?", token).First(&feedbackToken).Error
+	if err != nil {
+		return nil, err
+	}
+	return &feedbackToken, nil
+}
+
+// SaveToken creates a token or replaces an expired one
+func (r *anonymousFeedbackRepositoryImpl) SaveToken(ctx context.Context, token *domain.FeedbackToken) error {
+	return conn(ctx, r.db).Save(token).Error
+}
+
+// Submit uses the token and stores the feedback in a single transaction. The token is updated
+// without touching its timestamps, so nothing records when it was used.
+func (r *anonymousFeedbackRepositoryImpl) Submit(ctx context.Context, tokenID string, feedback *domain.AnonymousFeedback) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Table("feedback_tokens").
+			Where("id = ? AND used = FALSE", tokenID).
+			Update("used", true)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrFeedbackTokenUsed
+		}
+		return tx.Create(feedback).Error
+	})
+}
+
+// FindByBusiness finds the feedback sent to a business, most recent day first. Feedback sent
+// on the same day is not ordered by when it arrived.
+func (r *anonymousFeedbackRepositoryImpl) FindByBusiness(ctx context.Context, businessID string, unreadOnly bool, page, pageSize int) ([]*domain.AnonymousFeedback, int64, error) {
+	query := conn(ctx, r.db).Model(&domain.AnonymousFeedback{}).Where("business_id = ?", businessID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var feedback []*domain.AnonymousFeedback
+	err := query.Order("submitted_on DESC, id").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&feedback).Error
+	return feedback, total, err
+}
+
+// MarkRead marks feedback of a business as read, returning how many were unread
+func (r *anonymousFeedbackRepositoryImpl) MarkRead(ctx context.Context, businessID string, ids []string, at time.Time, by *string) (int64, error) {
+	result := conn(ctx, r.db).
+		Model(&domain.AnonymousFeedback{}).
+		Where("business_id = ? AND id IN ? AND read_at IS NULL", businessID, ids).
+		Updates(map[string]any{"read_at": at, "updated_at": at, "updated_by": by})
+	return result.RowsAffected, result.Error
+}
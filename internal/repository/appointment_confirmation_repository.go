@@ -0,0 +1,237 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// contactClientsSQL is the clients table with the phone and email of a minor's guardian in
+// place of their own, since contact with a minor goes through the guardian
+const contactClientsSQL = `(
+	SELECT m.id, m.first_name, m.last_name,
+		CASE WHEN g.id IS NULL THEN m.phone ELSE g.phone END AS phone,
+		CASE WHEN g.id IS NULL THEN m.email ELSE g.email END AS email
+	FROM clients m
+	LEFT JOIN clients g ON g.id = m.guardian_client_id AND g.deleted_at IS NULL
+)`
+
+// confirmationDueSQL selects upcoming, unconfirmed appointments that are within their business's
+// confirmation lead time and have not been sent a request yet. Requests for a minor go to their
+// guardian.
+const confirmationDueSQL = `
+	SELECT a.id AS appointment_id, a.business_id, a.client_id, a.start_time, a.end_time,
+		c.phone AS client_phone, c.email AS client_email
+	FROM appointments a
+	JOIN ` + contactClientsSQL + ` c ON c.id = a.client_id
+	LEFT JOIN business_settings bs ON bs.business_id = a.business_id AND bs.deleted_at IS NULL
+	WHERE a.deleted_at IS NULL
+		AND a.status IN ('scheduled', 'confirmed')
+		AND a.client_confirmed IS NOT TRUE
+		AND (NULLIF(TRIM(c.phone), '') IS NOT NULL OR NULLIF(TRIM(c.email), '') IS NOT NULL)
+		AND COALESCE(bs.confirmation_lead_hours, ?) > 0
+		AND a.start_time > ?
+		AND a.start_time <= CAST(? AS timestamptz) + make_interval(hours => COALESCE(bs.confirmation_lead_hours, ?))
+		AND NOT EXISTS (SELECT 1 FROM appointment_confirmations ac WHERE ac.appointment_id = a.id)
+	ORDER BY a.start_time ASC
+	LIMIT ?`
+
+// escalationDueSQL selects the latest requests of upcoming, unconfirmed appointments that went
+// unacknowledged past their business's cutoff or could not be delivered, and have not been
+// escalated yet
+const escalationDueSQL = `
+	SELECT ac.id AS confirmation_id, ac.attempt, a.id AS appointment_id, a.business_id, a.client_id,
+		a.start_time, a.end_time, c.phone AS client_phone, c.email AS client_email
+	FROM appointment_confirmations ac
+	JOIN appointments a ON a.id = ac.appointment_id
+	JOIN ` + contactClientsSQL + ` c ON c.id = a.client_id
+	LEFT JOIN business_settings bs ON bs.business_id = a.business_id AND bs.deleted_at IS NULL
+	WHERE ac.deleted_at IS NULL
+		AND ac.escalated_at IS NULL
+		AND ac.status IN ('pending', 'failed')
+		AND a.deleted_at IS NULL
+		AND a.status IN ('scheduled', 'confirmed')
+		AND a.client_confirmed IS NOT TRUE
+		AND a.start_time > ?
+		AND COALESCE(bs.confirmation_cutoff_hours, ?) > 0
+		AND (ac.status = 'failed'
+			OR ac.sent_at + make_interval(hours => COALESCE(bs.confirmation_cutoff_hours, ?)) <= ?)
+		AND NOT EXISTS (SELECT 1 FROM appointment_confirmations later
+			WHERE later.appointment_id = ac.appointment_id AND later.attempt > ac.attempt)
+	ORDER BY a.start_time ASC
+	LIMIT ?`
+
+// unconfirmedAppointmentsSQL selects a business's unconfirmed appointments with their latest
+// request, and the contact details to chase them with
+const unconfirmedAppointmentsSQL = `
+	SELECT a.id AS appointment_id, a.staff_id, a.client_id, a.start_time, a.end_time, a.status,
+		c.first_name || ' ' || c.last_name AS client_name, c.phone AS client_phone, c.email AS client_email,
+		u.first_name || ' ' || u.last_name AS staff_name,
+		ac.status AS request_status, ac.channel AS request_channel, ac.sent_at AS request_sent_at
+	FROM appointments a
+	JOIN ` + contactClientsSQL + ` c ON c.id = a.client_id
+	JOIN staff s ON s.id = a.staff_id
+	JOIN users u ON u.id = s.user_id
+	LEFT JOIN LATERAL (
+		SELECT status, channel, sent_at FROM appointment_confirmations
+		WHERE appointment_id = a.id AND deleted_at IS NULL
+		ORDER BY attempt DESC
+		LIMIT 1
+	) ac ON TRUE
+	WHERE a.business_id = ?
+		AND a.deleted_at IS NULL
+		AND a.status IN ('scheduled', 'confirmed')
+		AND a.client_confirmed IS NOT TRUE
+		AND a.start_time >= ? AND a.start_time < ?
+	ORDER BY a.start_time ASC, staff_name ASC`
+
+// appointmentConfirmationRepositoryImpl implements the AppointmentConfirmationRepository interface
+type appointmentConfirmationRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.AppointmentConfirmation]
+}
+
+// NewAppointmentConfirmationRepository creates a new appointment confirmation repository, scoped
+// to the tenant in the context
+func NewAppointmentConfirmationRepository(db *gorm.DB) domain.AppointmentConfirmationRepository {
+	return &appointmentConfirmationRepositoryImpl{
+		BaseRepositoryImpl: newBaseRepository[domain.AppointmentConfirmation](db, WithTenantScope()),
+	}
+}
+
+// FindByToken finds a confirmation request by the token in its link
+func (r *appointmentConfirmationRepositoryImpl) FindByToken(ctx context.Context, token string) (*domain.AppointmentConfirmation, error) {
+	var confirmation domain.AppointmentConfirmation
+	err := r.query(ctx).
+		Where("token = ?", token).
+		First(&confirmation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &confirmation, nil
+}
+
+// FindPendingByPhone finds the most recent pending SMS request sent to a phone number for an
+// upcoming appointment. The phone must be normalized to digits; it matches recipients stored
+// with or without the country code.
+func (r *appointmentConfirmationRepositoryImpl) FindPendingByPhone(ctx context.Context, phone string) (*domain.AppointmentConfirmation, error) {
+	var confirmation domain.AppointmentConfirmation
+	err := r.scopedBy(ctx, conn(ctx, r.db), "appointment_confirmations.business_id").
+		Joins("JOIN appointments a ON a.id = appointment_confirmations.appointment_id AND a.deleted_at IS NULL").
+		Where("appointment_confirmations.status = ? AND appointment_confirmations.channel = ?",
+			domain.ConfirmationStatusPending, domain.MessageChannelSMS).
+		Where("a.start_time > NOW()").
+		Where("length(regexp_replace(appointment_confirmations.recipient, '[^0-9]', '', 'g')) >= 6").
+		Where("? LIKE '%' || regexp_replace(appointment_confirmations.recipient, '[^0-9]', '', 'g')", phone).
+		Order("appointment_confirmations.sent_at DESC").
+		First(&confirmation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &confirmation, nil
+}
+
+// FindDueForRequest finds the appointments that should be sent a confirmation request now
+func (r *appointmentConfirmationRepositoryImpl) FindDueForRequest(ctx context.Context, now time.Time, limit int) ([]*domain.ConfirmationCandidate, error) {
+	if err := r.checkUnscoped(ctx); err != nil {
+		return nil, err
+	}
+	var candidates []*domain.ConfirmationCandidate
+	err := conn(ctx, r.db).
+		Raw(confirmationDueSQL, domain.DefaultConfirmationLeadHours, now, now, domain.DefaultConfirmationLeadHours, limit).
+		Scan(&candidates).Error
+	return candidates, err
+}
+
+// FindUnconfirmed finds a business's unconfirmed appointments starting in a time range
+func (r *appointmentConfirmationRepositoryImpl) FindUnconfirmed(ctx context.Context, businessID string, start, end time.Time) ([]*domain.UnconfirmedAppointment, error) {
+	if err := r.checkBusiness(ctx, businessID); err != nil {
+		return nil, err
+	}
+	var appointments []*domain.UnconfirmedAppointment
+	err := conn(ctx, r.db).
+		Raw(unconfirmedAppointmentsSQL, businessID, start, end).
+		Scan(&appointments).Error
+	return appointments, err
+}
+
+// ConfirmAppointment flags the appointment as confirmed by the client, closes its pending
+// requests and completes the open tasks to call the client about it
+func (r *appointmentConfirmationRepositoryImpl) ConfirmAppointment(ctx context.Context, appointmentID string, source domain.ConfirmationSource, at time.Time) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := r.scoped(ctx, tx.Table("appointments")).
+			Where("id = ? AND deleted_at IS NULL", appointmentID).
+			UpdateColumns(map[string]any{"client_confirmed": true, "updated_at": at})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		err := r.scoped(ctx, tx.Model(&domain.AppointmentConfirmation{})).
+			Where("appointment_id = ? AND status = ?", appointmentID, domain.ConfirmationStatusPending).
+			Updates(map[string]any{
+				"status":          domain.ConfirmationStatusConfirmed,
+				"responded_at":    at,
+				"response_source": source,
+				"updated_at":      at,
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		return r.scoped(ctx, tx.Model(&domain.FrontDeskTask{})).
+			Where("appointment_id = ? AND kind = ? AND status = ?",
+				appointmentID, domain.FrontDeskTaskCallClient, domain.FrontDeskTaskOpen).
+			Updates(map[string]any{
+				"status":       domain.FrontDeskTaskDone,
+				"outcome":      domain.CallClientTaskConfirmedOutcome,
+				"completed_at": at,
+				"updated_at":   at,
+			}).Error
+	})
+}
+
+// FindByAppointment finds the requests of an appointment's chain, first attempt first
+func (r *appointmentConfirmationRepositoryImpl) FindByAppointment(ctx context.Context, appointmentID string) ([]*domain.AppointmentConfirmation, error) {
+	var confirmations []*domain.AppointmentConfirmation
+	err := r.query(ctx).
+		Where("appointment_id = ?", appointmentID).
+		Order("attempt ASC").
+		Find(&confirmations).Error
+	return confirmations, err
+}
+
+// FindDueForEscalation finds the appointments whose latest request should be escalated now
+func (r *appointmentConfirmationRepositoryImpl) FindDueForEscalation(ctx context.Context, now time.Time, limit int) ([]*domain.EscalationCandidate, error) {
+	if err := r.checkUnscoped(ctx); err != nil {
+		return nil, err
+	}
+	var candidates []*domain.EscalationCandidate
+	err := conn(ctx, r.db).
+		Raw(escalationDueSQL, now, domain.DefaultConfirmationCutoffHours, domain.DefaultConfirmationCutoffHours, now, limit).
+		Scan(&candidates).Error
+	return candidates, err
+}
+
+// MarkEscalated records that the next step was taken for a request, unless another run took it
+func (r *appointmentConfirmationRepositoryImpl) MarkEscalated(ctx context.Context, confirmationID string, at time.Time) error {
+	result := r.query(ctx).
+		Model(&domain.AppointmentConfirmation{}).
+		Where("id = ? AND escalated_at IS NULL", confirmationID).
+		Updates(map[string]any{"escalated_at": at, "updated_at": at})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// WithTx returns a new repository instance with the given transaction
+func (r *appointmentConfirmationRepositoryImpl) WithTx(tx *gorm.DB) domain.BaseRepository[domain.AppointmentConfirmation] {
+	return r.withDB(tx)
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// appointmentQuoteRepositoryImpl implements the AppointmentQuoteRepository interface
+type appointmentQuoteRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewAppointmentQuoteRepository creates a new appointment quote repository
+func NewAppointmentQuoteRepository(db *gorm.DB) domain.AppointmentQuoteRepository {
+	return &appointmentQuoteRepositoryImpl{db: db}
+}
+
+// Create stores a quote
+func (r *appointmentQuoteRepositoryImpl) Create(ctx context.Context, quote *domain.AppointmentQuote) error {
+	return conn(ctx, r.db).Create(quote).Error
+}
+
+// FindByToken finds a quote by the token it is booked with
+func (r *appointmentQuoteRepositoryImpl) FindByToken(ctx context.Context, token string) (*domain.AppointmentQuote, error) {
+	var quote domain.AppointmentQuote
+	if err := conn(ctx, r.db).Where("token = ?", token).First(&quote).Error; err != nil {
+		return nil, err
+	}
+	return &quote, nil
+}
+
+// FindPromotions finds the active discount campaigns of the business the client was targeted by
+// and did not unsubscribe from, running at the given time
+func (r *appointmentQuoteRepositoryImpl) FindPromotions(ctx context.Context, businessID, clientID string, at time.Time) ([]*domain.Campaign, error) {
+	var campaigns []*domain.Campaign
+	err := conn(ctx, r.db).
+		Where("business_id = ? AND is_active AND offer_type = ? AND start_date <= ? AND end_date > ?",
+			businessID, domain.OfferTypeDiscount, at, at).
+		Where("id IN (?)", r.db.Model(&domain.CampaignClient{}).Select("campaign_id").
+			Where("client_id = ? AND status <> ?", clientID, domain.CampaignClientUnsubscribed)).
+		Order("start_date, id").
+		Find(&campaigns).Error
+	return campaigns, err
+}
+
+// MarkUsed records the appointment booked with the quote within the booking's transaction
+func (r *appointmentQuoteRepositoryImpl) MarkUsed(ctx context.Context, tx *gorm.DB, quoteID, appointmentID string) error {
+	result := tx.WithContext(ctx).Model(&domain.AppointmentQuote{}).
+		Where("id = ? AND appointment_id IS NULL", quoteID).
+		UpdateColumns(map[string]any{
+			"appointment_id": appointmentID,
+			"updated_at":     time.Now(),
+			"version":        gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrQuoteUsed
+	}
+	return nil
+}
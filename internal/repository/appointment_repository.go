@@ -0,0 +1,445 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// appointmentColumns are the fields of domain.Appointment stored in the appointments table.
+// The model carries a few fields the table does not have, so writes are limited to these.
+var appointmentColumns = []string{
+	"BusinessID", "ClientID", "StaffID", "StartTime", "EndTime", "Status", "Notes", "BundleID", "EstimatedPrice",
+	"CancellationReason", "ClientConfirmed", "CreatedAt", "CreatedBy", "UpdatedAt", "UpdatedBy",
+}
+
+// resourceBusySQL finds the blocking appointments occupying resources between two times, through
+// the resources their services need
+const resourceBusySQL = `
+	SELECT DISTINCT sr.resource_id, a.id AS appointment_id, a.start_time, a.end_time
+	FROM appointments a
+	JOIN appointment_services aps ON aps.appointment_id = a.id AND aps.deleted_at IS NULL
+	JOIN service_resources sr ON sr.service_id = aps.service_id AND sr.deleted_at IS NULL
+	WHERE sr.resource_id IN @resources AND a.status IN @statuses AND a.deleted_at IS NULL
+		AND a.start_time < @end AND a.end_time > @start
+	ORDER BY a.start_time`
+
+// appointmentRepositoryImpl implements the AppointmentRepository interface
+type appointmentRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.Appointment]
+}
+
+// NewAppointmentRepository creates a new appointment repository, scoped to the tenant in the
+// context
+func NewAppointmentRepository(db *gorm.DB) domain.AppointmentRepository {
+	return &appointmentRepositoryImpl{
+		BaseRepositoryImpl: newBaseRepository[domain.Appointment](db, WithTenantScope()),
+	}
+}
+
+// Create creates an appointment, rejecting it with a domain.AppointmentConflictError when the
+// staff member is not available at its time, and records it in the audit log
+func (r *appointmentRepositoryImpl) Create(ctx context.Context, appointment *domain.Appointment) error {
+	if err := r.claim(ctx, appointment); err != nil {
+		return err
+	}
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := r.ensureAvailable(ctx, tx, appointment, nil); err != nil {
+			return err
+		}
+		if err := tx.Select(appointmentColumns).Create(appointment).Error; err != nil {
+			return err
+		}
+		return r.audit(ctx, tx, nil, appointment.ID)
+	})
+}
+
+// CreateWithLines creates an appointment and the services performed during it in a single
+// transaction, subject to the same availability check as Create
+func (r *appointmentRepositoryImpl) CreateWithLines(ctx context.Context, appointment *domain.Appointment, lines []*domain.AppointmentLine) error {
+	if err := r.claim(ctx, appointment); err != nil {
+		return err
+	}
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		serviceIDs := make([]string, len(lines))
+		for i, line := range lines {
+			serviceIDs[i] = line.ServiceID
+		}
+		if err := r.ensureAvailable(ctx, tx, appointment, serviceIDs); err != nil {
+			return err
+		}
+		if err := tx.Select(appointmentColumns).Create(appointment).Error; err != nil {
+			return err
+		}
+		if err := r.audit(ctx, tx, nil, appointment.ID); err != nil {
+			return err
+		}
+		for _, line := range lines {
+			line.AppointmentID = appointment.ID
+		}
+		if len(lines) == 0 {
+			return nil
+		}
+		return tx.Create(&lines).Error
+	})
+}
+
+// Update updates an appointment, recording the fields it changes in the audit log. Appointments
+// that still occupy the staff member's time are checked for conflicts at their new time. The
+// update fails with a domain.ConflictError when the appointment was changed since the version
+// it carries was read.
+func (r *appointmentRepositoryImpl) Update(ctx context.Context, appointment *domain.Appointment) error {
+	if err := r.claim(ctx, appointment); err != nil {
+		return err
+	}
+	if err := r.ensureOwned(ctx, appointment.ID); err != nil {
+		return err
+	}
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var serviceIDs []string
+		err := tx.Model(&domain.AppointmentLine{}).Where("appointment_id = ?", appointment.ID).Pluck("service_id", &serviceIDs).Error
+		if err != nil {
+			return err
+		}
+		if err := r.ensureAvailable(ctx, tx, appointment, serviceIDs); err != nil {
+			return err
+		}
+		before, err := r.stored(tx, appointment.ID)
+		if err != nil {
+			return err
+		}
+		if err := r.save(tx, appointment, appointmentColumns...); err != nil {
+			return err
+		}
+		return r.audit(ctx, tx, before, appointment.ID)
+	})
+}
+
+// audit records a write of an appointment in the audit log, given the appointment as it was
+// stored before, nil when it is new. The appointment is read back after the write, as the model
+// carries fields the table does not store.
+func (r *appointmentRepositoryImpl) audit(ctx context.Context, tx *gorm.DB, before *domain.Appointment, id string) error {
+	entitySchema, audited := r.auditing()
+	if !audited {
+		return nil
+	}
+	after, err := r.stored(tx, id)
+	if err != nil {
+		return err
+	}
+	return r.record(ctx, tx, entitySchema, before, after)
+}
+
+// ensureAvailable serializes bookings of the appointment's staff member, and of the resources
+// its services need, for the rest of the transaction and checks the appointment against their
+// schedules. The locks close the window between checking and writing in which two concurrent
+// bookings could both succeed; resources are locked after the staff member and in order, so two
+// bookings never wait on each other.
+func (r *appointmentRepositoryImpl) ensureAvailable(ctx context.Context, tx *gorm.DB, appointment *domain.Appointment, serviceIDs []string) error {
+	if appointment.Status != "" && !slices.Contains(domain.BlockingAppointmentStatuses(), appointment.Status) {
+		return nil
+	}
+
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", appointment.StaffID).Error; err != nil {
+		return err
+	}
+	if len(serviceIDs) > 0 {
+		var resourceIDs []string
+		err := tx.Model(&domain.ServiceResource{}).Distinct("resource_id").Where("service_id IN ?", serviceIDs).
+			Order("resource_id").Pluck("resource_id", &resourceIDs).Error
+		if err != nil {
+			return err
+		}
+		for _, resourceID := range resourceIDs {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", resourceID).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	request := domain.AvailabilityRequest{
+		BusinessID: appointment.BusinessID,
+		StaffID:    appointment.StaffID,
+		Start:      appointment.StartTime,
+		End:        appointment.EndTime,
+		ServiceIDs: serviceIDs,
+	}
+	if appointment.ID != "" {
+		request.ExcludeAppointmentID = &appointment.ID
+	}
+	conflicts, err := r.checkAvailability(ctx, tx, request)
+	if err != nil {
+		return err
+	}
+	if len(conflicts) > 0 {
+		return &domain.AppointmentConflictError{Conflicts: conflicts}
+	}
+	return nil
+}
+
+// CheckAvailability checks a time slot against the business hours, the appointment buffer,
+// the staff member's other appointments, their availability exceptions and their shifts, and
+// the other appointments using the resources the services need
+func (r *appointmentRepositoryImpl) CheckAvailability(ctx context.Context, request domain.AvailabilityRequest) ([]*domain.AvailabilityConflict, error) {
+	if err := r.checkBusiness(ctx, request.BusinessID); err != nil {
+		return nil, err
+	}
+	return r.checkAvailability(ctx, conn(ctx, r.db), request)
+}
+
+func (r *appointmentRepositoryImpl) checkAvailability(ctx context.Context, db *gorm.DB, request domain.AvailabilityRequest) ([]*domain.AvailabilityConflict, error) {
+	db = db.WithContext(ctx)
+
+	var business domain.Business
+	if err := db.Where("id = ?", request.BusinessID).First(&business).Error; err != nil {
+		return nil, err
+	}
+	var settings *domain.BusinessSettings
+	var found domain.BusinessSettings
+	err := db.Where("business_id = ?", request.BusinessID).First(&found).Error
+	switch {
+	case err == nil:
+		settings = &found
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, err
+	}
+	rules := domain.NewAvailabilityRules(&business, settings)
+
+	var busy []*domain.BusyInterval
+	err = db.Model(&domain.Appointment{}).
+		Select("id AS appointment_id, start_time, end_time").
+		Where("staff_id = ? AND status IN ?", request.StaffID, domain.BlockingAppointmentStatuses()).
+		Where("start_time < ? AND end_time > ?", request.End.Add(rules.Buffer), request.Start.Add(-rules.Buffer)).
+		Order("start_time ASC").
+		Scan(&busy).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var exceptions []*domain.AvailabilityException
+	err = db.Where("staff_id = ? AND exception_type <> ?", request.StaffID, domain.ExceptionCustomHours).
+		Where("is_recurring OR (start_time < ? AND end_time > ?)", request.End, request.Start).
+		Find(&exceptions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var shifts []*domain.StaffShift
+	if err := db.Where("staff_id = ?", request.StaffID).Find(&shifts).Error; err != nil {
+		return nil, err
+	}
+
+	usages, err := r.resourceUsages(db, request)
+	if err != nil {
+		return nil, err
+	}
+
+	conflicts := rules.Check(request, busy, exceptions)
+	if conflict := rules.CheckShifts(request, shifts); conflict != nil {
+		conflicts = append(conflicts, conflict)
+	}
+	return append(conflicts, domain.CheckResources(request, usages, rules.Location)...), nil
+}
+
+// resourceBusyInterval is a busy interval together with the resource it occupies
+type resourceBusyInterval struct {
+	ResourceID string
+	domain.BusyInterval
+}
+
+// resourceUsages finds the active resources the requested services need and the appointments
+// using them during the requested time
+func (r *appointmentRepositoryImpl) resourceUsages(db *gorm.DB, request domain.AvailabilityRequest) ([]*domain.ResourceUsage, error) {
+	if len(request.ServiceIDs) == 0 {
+		return nil, nil
+	}
+	var resources []*domain.Resource
+	err := db.Where("is_active AND id IN (?)",
+		db.Model(&domain.ServiceResource{}).Select("resource_id").Where("service_id IN ?", request.ServiceIDs)).
+		Order("name ASC").
+		Find(&resources).Error
+	if err != nil || len(resources) == 0 {
+		return nil, err
+	}
+
+	usages := make([]*domain.ResourceUsage, len(resources))
+	byResource := make(map[string]*domain.ResourceUsage, len(resources))
+	resourceIDs := make([]string, len(resources))
+	for i, resource := range resources {
+		usages[i] = &domain.ResourceUsage{Resource: resource}
+		byResource[resource.ID] = usages[i]
+		resourceIDs[i] = resource.ID
+	}
+	var busy []*resourceBusyInterval
+	err = db.Raw(resourceBusySQL, map[string]any{
+		"resources": resourceIDs,
+		"statuses":  domain.BlockingAppointmentStatuses(),
+		"start":     request.Start,
+		"end":       request.End,
+	}).Scan(&busy).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, interval := range busy {
+		byResource[interval.ResourceID].Busy = append(byResource[interval.ResourceID].Busy, &interval.BusyInterval)
+	}
+	return usages, nil
+}
+
+// staffBusyInterval is a busy interval together with the staff member it occupies
+type staffBusyInterval struct {
+	StaffID string
+	domain.BusyInterval
+}
+
+// FindSchedules finds the blocking appointments, availability exceptions and shifts of each of
+// the staff members between start and end, in the order of the IDs
+func (r *appointmentRepositoryImpl) FindSchedules(ctx context.Context, staffIDs []string, start, end time.Time) ([]*domain.StaffSchedule, error) {
+	schedules := make([]*domain.StaffSchedule, len(staffIDs))
+	byStaff := make(map[string]*domain.StaffSchedule, len(staffIDs))
+	for i, staffID := range staffIDs {
+		schedules[i] = &domain.StaffSchedule{StaffID: staffID}
+		byStaff[staffID] = schedules[i]
+	}
+	if len(staffIDs) == 0 {
+		return schedules, nil
+	}
+
+	var busy []*staffBusyInterval
+	err := r.query(ctx).Model(&domain.Appointment{}).
+		Select("staff_id, id AS appointment_id, start_time, end_time").
+		Where("staff_id IN ? AND status IN ?", staffIDs, domain.BlockingAppointmentStatuses()).
+		Where("start_time < ? AND end_time > ?", end, start).
+		Order("start_time ASC").
+		Scan(&busy).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, interval := range busy {
+		byStaff[interval.StaffID].Busy = append(byStaff[interval.StaffID].Busy, &interval.BusyInterval)
+	}
+
+	var exceptions []*domain.AvailabilityException
+	err = r.query(ctx).
+		Where("staff_id IN ? AND exception_type <> ?", staffIDs, domain.ExceptionCustomHours).
+		Where("is_recurring OR (start_time < ? AND end_time > ?)", end, start).
+		Find(&exceptions).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, exception := range exceptions {
+		byStaff[exception.StaffID].Exceptions = append(byStaff[exception.StaffID].Exceptions, exception)
+	}
+
+	var shifts []*domain.StaffShift
+	if err := r.query(ctx).Where("staff_id IN ?", staffIDs).Find(&shifts).Error; err != nil {
+		return nil, err
+	}
+	for _, shift := range shifts {
+		byStaff[shift.StaffID].Shifts = append(byStaff[shift.StaffID].Shifts, shift)
+	}
+	return schedules, nil
+}
+
+// FindByBusinessID finds the appointments of a business matching the filters
+func (r *appointmentRepositoryImpl) FindByBusinessID(ctx context.Context, businessID string, filters domain.AppointmentFilters) ([]*domain.Appointment, error) {
+	var appointments []*domain.Appointment
+	query := r.query(ctx).Where("business_id = ?", businessID)
+	if filters.Status != nil {
+		query = query.Where("status = ?", *filters.Status)
+	}
+	if filters.StaffID != nil {
+		query = query.Where("staff_id = ?", *filters.StaffID)
+	}
+	if filters.ServiceID != nil {
+		query = query.Where("EXISTS (SELECT 1 FROM appointment_services aps WHERE aps.appointment_id = appointments.id AND aps.service_id = ? AND aps.deleted_at IS NULL)",
+			*filters.ServiceID)
+	}
+	if filters.DateRange != nil {
+		query = query.Where("start_time >= ? AND start_time < ?", filters.DateRange.Start, filters.DateRange.End)
+	}
+
+	err := query.Order("start_time ASC").Find(&appointments).Error
+	return appointments, err
+}
+
+// FindByClientID finds all appointments of a client, most recent first
+func (r *appointmentRepositoryImpl) FindByClientID(ctx context.Context, clientID string) ([]*domain.Appointment, error) {
+	var appointments []*domain.Appointment
+	err := r.query(ctx).
+		Where("client_id = ?", clientID).
+		Order("start_time DESC").
+		Find(&appointments).Error
+	return appointments, err
+}
+
+// FindByStaffID finds the appointments of a staff member starting in a date range
+func (r *appointmentRepositoryImpl) FindByStaffID(ctx context.Context, staffID string, dateRange domain.DateRange) ([]*domain.Appointment, error) {
+	return r.FindByDateRange(ctx, staffID, dateRange.Start, dateRange.End)
+}
+
+// FindByDateRange finds the appointments of a staff member starting between two times
+func (r *appointmentRepositoryImpl) FindByDateRange(ctx context.Context, staffID string, start, end time.Time) ([]*domain.Appointment, error) {
+	var appointments []*domain.Appointment
+	err := r.query(ctx).
+		Where("staff_id = ? AND start_time >= ? AND start_time < ?", staffID, start, end).
+		Order("start_time ASC").
+		Find(&appointments).Error
+	return appointments, err
+}
+
+// CheckOverlap checks whether a staff member has an appointment overlapping a time range,
+// ignoring the business's buffer
+func (r *appointmentRepositoryImpl) CheckOverlap(ctx context.Context, staffID string, start, end time.Time, excludeID *string) (bool, error) {
+	var count int64
+	query := r.query(ctx).
+		Model(&domain.Appointment{}).
+		Where("staff_id = ? AND status IN ?", staffID, domain.BlockingAppointmentStatuses()).
+		Where("start_time < ? AND end_time > ?", end, start)
+	if excludeID != nil {
+		query = query.Where("id <> ?", *excludeID)
+	}
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
+// GetUpcomingByStaff finds a staff member's next appointments
+func (r *appointmentRepositoryImpl) GetUpcomingByStaff(ctx context.Context, staffID string, limit int) ([]*domain.Appointment, error) {
+	var appointments []*domain.Appointment
+	err := r.query(ctx).
+		Where("staff_id = ? AND start_time > NOW() AND status IN ?", staffID,
+			[]domain.AppointmentStatus{domain.AppointmentStatusScheduled, domain.AppointmentStatusConfirmed}).
+		Order("start_time ASC").
+		Limit(limit).
+		Find(&appointments).Error
+	return appointments, err
+}
+
+// GetDashboardData is not supported: DashboardData has not been defined yet
+func (r *appointmentRepositoryImpl) GetDashboardData(ctx context.Context, businessID string, date time.Time) (*domain.DashboardData, error) {
+	return nil, errors.ErrUnsupported
+}
+
+// GetCalendarView is not supported: calendar views are served by the calendar read model
+func (r *appointmentRepositoryImpl) GetCalendarView(ctx context.Context, businessID string, start, end time.Time) ([]*domain.CalendarAppointment, error) {
+	return nil, errors.ErrUnsupported
+}
+
+// GetByStatus finds the appointments of a business with a status
+func (r *appointmentRepositoryImpl) GetByStatus(ctx context.Context, businessID string, status domain.AppointmentStatus) ([]*domain.Appointment, error) {
+	var appointments []*domain.Appointment
+	err := r.query(ctx).
+		Where("business_id = ? AND status = ?", businessID, status).
+		Order("start_time ASC").
+		Find(&appointments).Error
+	return appointments, err
+}
+
+// WithTx returns a new repository instance with the given transaction
+func (r *appointmentRepositoryImpl) WithTx(tx *gorm.DB) domain.BaseRepository[domain.Appointment] {
+	return &appointmentRepositoryImpl{BaseRepositoryImpl: r.withDB(tx)}
+}
@@ -0,0 +1,109 @@
+//go:build integration
+// +build integration
+
+package repository_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/repository"
+	"github.com/assimoes/beautix/utils/testdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// appointmentFixture stores a business with an owner, a staff member and a client, and returns
+// a cancelled appointment between them, which does not take up the staff member's time
+func appointmentFixture(t *testing.T) (*testdb.TestDB, *domain.Appointment) {
+	t.Helper()
+	db := testdb.NewTestDB(t)
+	fixtures := testdb.NewFixtureBuilder(db.GetDB())
+	owner, err := fixtures.CreateUser()
+	require.NoError(t, err)
+	business, err := fixtures.CreateBusiness(owner.ID)
+	require.NoError(t, err)
+	staff, err := fixtures.CreateStaff(business.ID, owner.ID)
+	require.NoError(t, err)
+	client, err := fixtures.CreateClient(business.ID)
+	require.NoError(t, err)
+
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+	return db, &domain.Appointment{
+		BusinessID: business.ID,
+		ClientID:   client.ID,
+		StaffID:    staff.ID,
+		StartTime:  start,
+		EndTime:    start.Add(time.Hour),
+		Status:     domain.AppointmentStatusCancelled,
+	}
+}
+
+func TestAppointmentRepository_UpdateIsAudited(t *testing.T) {
+	ctx := context.Background()
+	db, appointment := appointmentFixture(t)
+	appointments := repository.NewAppointmentRepository(db.GetDB().DB)
+	require.NoError(t, appointments.Create(ctx, appointment))
+
+	notes := "Bring the colour chart"
+	appointment.Notes = &notes
+	require.NoError(t, appointments.Update(ctx, appointment))
+
+	entityType, action := "appointments", domain.AuditActionUpdate
+	entries, total, err := repository.NewAuditLogRepository(db.GetDB().DB).Search(ctx, domain.AuditLogFilter{
+		BusinessID: appointment.BusinessID,
+		EntityType: &entityType,
+		EntityID:   &appointment.ID,
+		Action:     &action,
+	}, 1, 10)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, total)
+	require.NotNil(t, entries[0].Changes)
+	assert.Contains(t, *entries[0].Changes, "notes")
+}
+
+func TestAppointmentRepository_UpdateRejectsStaleVersions(t *testing.T) {
+	ctx := context.Background()
+	db, appointment := appointmentFixture(t)
+	appointments := repository.NewAppointmentRepository(db.GetDB().DB)
+	require.NoError(t, appointments.Create(ctx, appointment))
+
+	// Two writers read the same version and update it at once
+	writers := make([]*domain.Appointment, 2)
+	for i := range writers {
+		read, err := appointments.GetByID(ctx, appointment.ID)
+		require.NoError(t, err)
+		writers[i] = read
+	}
+	read := writers[0].Version
+	errs := make([]error, len(writers))
+	var wg sync.WaitGroup
+	for i, writer := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			notes := fmt.Sprintf("writer %d", i)
+			writer.Notes = &notes
+			errs[i] = appointments.Update(ctx, writer)
+		}()
+	}
+	wg.Wait()
+
+	var conflict *domain.ConflictError
+	var failed int
+	for _, err := range errs {
+		if err != nil {
+			require.ErrorAs(t, err, &conflict)
+			failed++
+		}
+	}
+	assert.Equal(t, 1, failed, "only one writer updates the version both read")
+
+	stored, err := appointments.GetByID(ctx, appointment.ID)
+	require.NoError(t, err)
+	assert.Equal(t, read+1, stored.Version)
+}
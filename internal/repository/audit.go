@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// unauditedTables are the tables whose writes the audit log leaves out: the log itself, and the
+// operational records the system keeps for its own work rather than changes to a business's data
+var unauditedTables = map[string]bool{
+	"audit_logs":                true,
+	"appointment_confirmations": true,
+	"campaign_clients":          true,
+	"domain_events":             true,
+	"idempotency_keys":          true,
+	"integration_usage_daily":   true,
+	"jobs":                      true,
+	"notification_queue":        true,
+	"outbound_requests":         true,
+}
+
+// unauditedColumns are bookkeeping columns whose changes the audit log does not record
+var unauditedColumns = map[string]bool{
+	"id":         true,
+	"created_at": true,
+	"created_by": true,
+	"updated_at": true,
+	"updated_by": true,
+	"deleted_at": true,
+	"deleted_by": true,
+	"version":    true,
+}
+
+// secretColumnMarkers mark the columns whose values the audit log redacts
+var secretColumnMarkers = []string{"password", "secret", "token", "hash"}
+
+// auditSchemas caches the parsed schemas of the entities
+var auditSchemas sync.Map
+
+// auditing returns the schema of the repository's entity when its writes are recorded in the
+// audit log
+func (r *BaseRepositoryImpl[T]) auditing() (*schema.Schema, bool) {
+	entitySchema, err := r.schema()
+	if err != nil || unauditedTables[entitySchema.Table] {
+		return nil, false
+	}
+	return entitySchema, true
+}
+
+// schema returns the parsed schema of the repository's entity
+func (r *BaseRepositoryImpl[T]) schema() (*schema.Schema, error) {
+	return schema.Parse(new(T), &auditSchemas, r.db.NamingStrategy)
+}
+
+// stored retrieves the entity with an ID as it is stored, or nil when there is none
+func (r *BaseRepositoryImpl[T]) stored(tx *gorm.DB, id string) (*T, error) {
+	if id == "" {
+		return nil, nil
+	}
+	var entity T
+	if err := tx.Where("id = ?", id).First(&entity).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &entity, nil
+}
+
+// record records a write of an entity in the audit log, given the entity as it was stored
+// before and after, nil when there was none. Updates changing no field are not recorded.
+func (r *BaseRepositoryImpl[T]) record(ctx context.Context, tx *gorm.DB, entitySchema *schema.Schema, before, after *T) error {
+	action, entity := domain.AuditActionUpdate, after
+	switch {
+	case before == nil:
+		action = domain.AuditActionCreate
+	case after == nil:
+		action, entity = domain.AuditActionDelete, before
+	}
+
+	var changes map[string]domain.AuditChange
+	if action != domain.AuditActionDelete {
+		var err error
+		if changes, err = auditChanges(ctx, entitySchema, before, after); err != nil {
+			return err
+		}
+		if action == domain.AuditActionUpdate && len(changes) == 0 {
+			return nil
+		}
+	}
+
+	return r.recordEntry(ctx, tx, entitySchema, action, entity, changes)
+}
+
+// recordEntry writes an audit log entry for an action on an entity
+func (r *BaseRepositoryImpl[T]) recordEntry(ctx context.Context, tx *gorm.DB, entitySchema *schema.Schema, action domain.AuditAction, entity *T, changes map[string]domain.AuditChange) error {
+	value := reflect.ValueOf(entity).Elem()
+	entry, err := domain.NewAuditLog(ctx, action, entitySchema.Table, entityID(entity), auditBusinessID(ctx, entitySchema, value), changes, time.Now())
+	if err != nil {
+		return err
+	}
+	return tx.Create(entry).Error
+}
+
+// auditChanges returns the fields that differ between two versions of an entity, keyed by
+// column. For a new entity, before is nil and the fields it sets are returned.
+func auditChanges[T any](ctx context.Context, entitySchema *schema.Schema, before, after *T) (map[string]domain.AuditChange, error) {
+	changes := map[string]domain.AuditChange{}
+	afterValue := reflect.ValueOf(after).Elem()
+	for _, field := range entitySchema.Fields {
+		if field.DBName == "" || unauditedColumns[field.DBName] {
+			continue
+		}
+		value, zero := field.ValueOf(ctx, afterValue)
+		to, err := auditValue(value)
+		if err != nil {
+			return nil, err
+		}
+
+		var from json.RawMessage
+		if before != nil {
+			previous, _ := field.ValueOf(ctx, reflect.ValueOf(before).Elem())
+			if from, err = auditValue(previous); err != nil {
+				return nil, err
+			}
+			if string(from) == string(to) {
+				continue
+			}
+		} else if zero {
+			continue
+		}
+
+		if isSecretColumn(field.DBName) {
+			from, to = redact(from), redact(to)
+		}
+		changes[field.DBName] = domain.AuditChange{From: from, To: to}
+	}
+	return changes, nil
+}
+
+// auditValue encodes a field value for the audit log. Times are compared in UTC, as the
+// database may return them in another zone.
+func auditValue(value any) (json.RawMessage, error) {
+	switch v := value.(type) {
+	case time.Time:
+		value = v.UTC()
+	case *time.Time:
+		if v != nil {
+			utc := v.UTC()
+			value = &utc
+		}
+	}
+	return json.Marshal(value)
+}
+
+// redact replaces a secret value, keeping whether it was set
+func redact(value json.RawMessage) json.RawMessage {
+	if value == nil || string(value) == "null" {
+		return value
+	}
+	return json.RawMessage(domain.AuditRedacted)
+}
+
+// isSecretColumn reports whether a column holds secrets such as password hashes or tokens
+func isSecretColumn(column string) bool {
+	for _, marker := range secretColumnMarkers {
+		if strings.Contains(column, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// auditBusinessID returns the business whose audit log records a change to an entity: the
+// business itself, the one the entity belongs to, or else the tenant the change was made for
+func auditBusinessID(ctx context.Context, entitySchema *schema.Schema, entity reflect.Value) *string {
+	if entitySchema.Table == (domain.Business{}).TableName() {
+		id := entity.FieldByName("ID").String()
+		return &id
+	}
+	if field := entitySchema.LookUpField("business_id"); field != nil {
+		switch businessID, _ := field.ValueOf(ctx, entity); v := businessID.(type) {
+		case string:
+			if v != "" {
+				return &v
+			}
+		case *string:
+			if v != nil && *v != "" {
+				return v
+			}
+		}
+	}
+	if tenant, ok := domain.TenantFromContext(ctx); ok && tenant.BusinessID != "" {
+		return &tenant.BusinessID
+	}
+	return nil
+}
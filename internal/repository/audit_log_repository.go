@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// auditLogRepositoryImpl implements the AuditLogRepository interface
+type auditLogRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.AuditLog]
+}
+
+// NewAuditLogRepository creates a new audit log repository
+func NewAuditLogRepository(db *gorm.DB) domain.AuditLogRepository {
+	return &auditLogRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.AuditLog]{db: db},
+	}
+}
+
+// Search finds the entries matching the filter, most recent first
+func (r *auditLogRepositoryImpl) Search(ctx context.Context, filter domain.AuditLogFilter, page, pageSize int) ([]*domain.AuditLog, int64, error) {
+	var entries []*domain.AuditLog
+	var total int64
+
+	query := conn(ctx, r.db).
+		Model(&domain.AuditLog{}).
+		Where("business_id = ? AND deleted_at IS NULL", filter.BusinessID)
+
+	if filter.EntityType != nil {
+		query = query.Where("entity_type = ?", *filter.EntityType)
+	}
+	if filter.EntityID != nil {
+		query = query.Where("entity_id = ?", *filter.EntityID)
+	}
+	if filter.ActorID != nil {
+		query = query.Where("actor_id = ?", *filter.ActorID)
+	}
+	if filter.Action != nil {
+		query = query.Where("action = ?", *filter.Action)
+	}
+	if filter.StartDate != nil {
+		query = query.Where("occurred_at >= ?", *filter.StartDate)
+	}
+	if filter.EndDate != nil {
+		query = query.Where("occurred_at < ?", *filter.EndDate)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Order("occurred_at DESC, id DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&entries).Error
+
+	return entries, total, err
+}
+
+// WithTx returns a new repository instance with the given transaction
+func (r *auditLogRepositoryImpl) WithTx(tx *gorm.DB) domain.BaseRepository[domain.AuditLog] {
+	return &BaseRepositoryImpl[domain.AuditLog]{db: tx}
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm/schema"
+)
+
+func parseSchema[T any](t *testing.T) *schema.Schema {
+	t.Helper()
+	entitySchema, err := schema.Parse(new(T), &sync.Map{}, schema.NamingStrategy{})
+	require.NoError(t, err)
+	return entitySchema
+}
+
+func encodedChanges(t *testing.T, changes map[string]domain.AuditChange) map[string]any {
+	t.Helper()
+	data, err := json.Marshal(changes)
+	require.NoError(t, err)
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	return decoded
+}
+
+func TestAuditChanges(t *testing.T) {
+	ctx := context.Background()
+	serviceSchema := parseSchema[domain.Service](t)
+	description := "Wash and cut"
+	before := &domain.Service{
+		BaseModel:   domain.BaseModel{ID: "service-1", UpdatedAt: time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)},
+		BusinessID:  "business-1",
+		Name:        "Haircut",
+		Description: &description,
+		Duration:    30,
+		Price:       decimal.RequireFromString("25.00"),
+		IsActive:    true,
+	}
+
+	t.Run("records the fields an update changes", func(t *testing.T) {
+		after := *before
+		after.Name = "Signature haircut"
+		after.Price = decimal.NewFromInt(25) // The same amount, read back with another scale
+		after.Description = nil
+		after.UpdatedAt = before.UpdatedAt.Add(time.Hour)
+
+		changes, err := auditChanges(ctx, serviceSchema, before, &after)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{
+			"name":        map[string]any{"from": "Haircut", "to": "Signature haircut"},
+			"description": map[string]any{"from": "Wash and cut", "to": nil},
+		}, encodedChanges(t, changes), "bookkeeping columns are left out")
+	})
+
+	t.Run("compares times in UTC", func(t *testing.T) {
+		lisbon, err := time.LoadLocation("Europe/Lisbon")
+		require.NoError(t, err)
+		startsAt := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+		stored := &domain.Appointment{StartTime: startsAt}
+		loaded := &domain.Appointment{StartTime: startsAt.In(lisbon)}
+
+		changes, err := auditChanges(ctx, parseSchema[domain.Appointment](t), stored, loaded)
+		require.NoError(t, err)
+		assert.Empty(t, changes)
+	})
+
+	t.Run("records the fields a new entity sets", func(t *testing.T) {
+		changes, err := auditChanges(ctx, serviceSchema, nil, before)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"from": nil, "to": "Haircut"}, encodedChanges(t, changes)["name"])
+		assert.NotContains(t, changes, "id")
+		assert.NotContains(t, changes, "category_id", "unset fields are left out")
+	})
+
+	t.Run("redacts secrets", func(t *testing.T) {
+		type credential struct {
+			ID           string
+			Label        string
+			PasswordHash *string
+		}
+		hash, rehashed := "$2a$10$old", "$2a$10$new"
+		changes, err := auditChanges(ctx, parseSchema[credential](t),
+			&credential{ID: "credential-1", Label: "Front desk", PasswordHash: &hash},
+			&credential{ID: "credential-1", Label: "Front desk", PasswordHash: &rehashed})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{
+			"password_hash": map[string]any{"from": "[redacted]", "to": "[redacted]"},
+		}, encodedChanges(t, changes))
+	})
+}
+
+func TestAuditBusinessID(t *testing.T) {
+	ctx := context.Background()
+
+	business := &domain.Business{BaseModel: domain.BaseModel{ID: "business-1"}}
+	assert.Equal(t, "business-1", *auditBusinessID(ctx, parseSchema[domain.Business](t), reflect.ValueOf(business).Elem()))
+
+	service := &domain.Service{BusinessID: "business-2"}
+	assert.Equal(t, "business-2", *auditBusinessID(ctx, parseSchema[domain.Service](t), reflect.ValueOf(service).Elem()))
+
+	user := &domain.User{}
+	userSchema := parseSchema[domain.User](t)
+	assert.Nil(t, auditBusinessID(ctx, userSchema, reflect.ValueOf(user).Elem()))
+	tenantCtx := domain.WithTenant(ctx, domain.TenantContext{BusinessID: "business-3"})
+	assert.Equal(t, "business-3", *auditBusinessID(tenantCtx, userSchema, reflect.ValueOf(user).Elem()),
+		"changes made for a tenant belong to its log")
+}
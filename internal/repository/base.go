@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/assimoes/beautix/internal/domain"
 	"gorm.io/gorm"
@@ -9,49 +10,170 @@ import (
 
 // BaseRepositoryImpl provides a base implementation for repositories
 type BaseRepositoryImpl[T any] struct {
-	db *gorm.DB
+	db          *gorm.DB
+	tenantField []int // index of the BusinessID field when scoped by tenant
 }
 
 // NewBaseRepository creates a new base repository
-func NewBaseRepository[T any](db *gorm.DB) domain.BaseRepository[T] {
-	return &BaseRepositoryImpl[T]{
+func NewBaseRepository[T any](db *gorm.DB, opts ...BaseRepositoryOption) domain.BaseRepository[T] {
+	return newBaseRepository[T](db, opts...)
+}
+
+// newBaseRepository creates the base of a repository, for repositories embedding it
+func newBaseRepository[T any](db *gorm.DB, opts ...BaseRepositoryOption) *BaseRepositoryImpl[T] {
+	config := &baseRepositoryConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	repo := &BaseRepositoryImpl[T]{
 		db: db,
 	}
+	if config.tenantScoped {
+		repo.tenantField = businessIDField[T]()
+	}
+	return repo
+}
+
+// withDB returns the repository on another connection, such as a transaction, keeping its
+// tenant scope
+func (r *BaseRepositoryImpl[T]) withDB(db *gorm.DB) *BaseRepositoryImpl[T] {
+	return &BaseRepositoryImpl[T]{db: db, tenantField: r.tenantField}
 }
 
-// Create creates a new entity
+// Create creates a new entity, recording it in the audit log
 func (r *BaseRepositoryImpl[T]) Create(ctx context.Context, entity *T) error {
-	return r.db.WithContext(ctx).Create(entity).Error
+	if err := r.claim(ctx, entity); err != nil {
+		return err
+	}
+	entitySchema, audited := r.auditing()
+	if !audited {
+		return conn(ctx, r.db).Create(entity).Error
+	}
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(entity).Error; err != nil {
+			return err
+		}
+		return r.record(ctx, tx, entitySchema, nil, entity)
+	})
 }
 
 // GetByID retrieves an entity by ID
 func (r *BaseRepositoryImpl[T]) GetByID(ctx context.Context, id string) (*T, error) {
 	var entity T
-	err := r.db.WithContext(ctx).Where("id = ?", id).First(&entity).Error
+	err := r.query(ctx).Where("id = ?", id).First(&entity).Error
 	if err != nil {
 		return nil, err
 	}
 	return &entity, nil
 }
 
-// Update updates an existing entity
+// GetByIDs retrieves the entities with the given IDs in one query, skipping missing IDs
+func (r *BaseRepositoryImpl[T]) GetByIDs(ctx context.Context, ids []string) ([]*T, error) {
+	entities := []*T{}
+	if len(ids) == 0 {
+		return entities, nil
+	}
+	err := r.query(ctx).Where("id IN ?", ids).Find(&entities).Error
+	return entities, err
+}
+
+// Update updates an existing entity, recording the fields it changes in the audit log. The
+// update fails with a domain.ConflictError when the entity was changed since the version it
+// carries was read.
 func (r *BaseRepositoryImpl[T]) Update(ctx context.Context, entity *T) error {
-	return r.db.WithContext(ctx).Save(entity).Error
+	if err := r.claim(ctx, entity); err != nil {
+		return err
+	}
+	if err := r.ensureOwned(ctx, entityID(entity)); err != nil {
+		return err
+	}
+	entitySchema, audited := r.auditing()
+	if !audited {
+		return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+			return r.save(tx, entity)
+		})
+	}
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		before, err := r.stored(tx, entityID(entity))
+		if err != nil {
+			return err
+		}
+		if err := r.save(tx, entity); err != nil {
+			return err
+		}
+		return r.record(ctx, tx, entitySchema, before, entity)
+	})
 }
 
-// Delete soft deletes an entity by ID
+// Delete soft deletes an entity by ID, recording it in the audit log
 func (r *BaseRepositoryImpl[T]) Delete(ctx context.Context, id string) error {
+	if err := r.ensureOwned(ctx, id); err != nil {
+		return err
+	}
 	var entity T
-	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&entity).Error
+	entitySchema, audited := r.auditing()
+	if !audited {
+		return conn(ctx, r.db).Where("id = ?", id).Delete(&entity).Error
+	}
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		before, err := r.stored(tx, id)
+		if err != nil || before == nil {
+			return err
+		}
+		if actorID, ok := domain.ActorFromContext(ctx); ok && entitySchema.LookUpField("deleted_by") != nil {
+			if err := tx.Model(new(T)).Where("id = ?", id).UpdateColumn("deleted_by", actorID).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("id = ?", id).Delete(&entity).Error; err != nil {
+			return err
+		}
+		return r.record(ctx, tx, entitySchema, before, nil)
+	})
 }
 
-// List retrieves entities with pagination
+// Restore undoes the soft deletion of an entity, recording it in the audit log
+func (r *BaseRepositoryImpl[T]) Restore(ctx context.Context, id string) error {
+	restore := func(tx *gorm.DB) error {
+		restored := map[string]any{"deleted_at": nil}
+		if entitySchema, err := r.schema(); err == nil && entitySchema.LookUpField("deleted_by") != nil {
+			restored["deleted_by"] = nil
+		}
+		result := r.scoped(ctx, tx).Unscoped().Model(new(T)).
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			Updates(restored)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	}
+	entitySchema, audited := r.auditing()
+	if !audited {
+		return restore(conn(ctx, r.db))
+	}
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := restore(tx); err != nil {
+			return err
+		}
+		after, err := r.stored(tx, id)
+		if err != nil || after == nil {
+			return err
+		}
+		return r.recordEntry(ctx, tx, entitySchema, domain.AuditActionRestore, after, nil)
+	})
+}
+
+// List retrieves entities with pagination, in creation order
 func (r *BaseRepositoryImpl[T]) List(ctx context.Context, page, pageSize int) ([]*T, int64, error) {
 	var entities []*T
 	var total int64
 	
 	// Count total records
-	if err := r.db.WithContext(ctx).Model(new(T)).Count(&total).Error; err != nil {
+	if err := r.query(ctx).Model(new(T)).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 	
@@ -59,7 +181,8 @@ func (r *BaseRepositoryImpl[T]) List(ctx context.Context, page, pageSize int) ([
 	offset := (page - 1) * pageSize
 	
 	// Retrieve paginated results
-	err := r.db.WithContext(ctx).
+	err := r.query(ctx).
+		Order("created_at ASC, id ASC").
 		Offset(offset).
 		Limit(pageSize).
 		Find(&entities).Error
@@ -67,10 +190,35 @@ func (r *BaseRepositoryImpl[T]) List(ctx context.Context, page, pageSize int) ([
 	return entities, total, err
 }
 
+// ListAfter retrieves up to limit entities following the cursor, ordered by creation time and
+// ID. Unlike List it seeks instead of skipping rows, so deep pages cost the same as the first.
+// The boolean reports whether more entities follow.
+func (r *BaseRepositoryImpl[T]) ListAfter(ctx context.Context, cursor *domain.Cursor, limit int) ([]*T, bool, error) {
+	var entities []*T
+	query := r.query(ctx)
+	if cursor != nil {
+		query = query.Where("(created_at, id) > (?, ?)", cursor.CreatedAt, cursor.ID)
+	}
+
+	err := query.
+		Order("created_at ASC, id ASC").
+		Limit(limit + 1).
+		Find(&entities).Error
+	if err != nil {
+		return nil, false, err
+	}
+
+	hasNext := len(entities) > limit
+	if hasNext {
+		entities = entities[:limit]
+	}
+	return entities, hasNext, nil
+}
+
 // FindBy finds entities by criteria
 func (r *BaseRepositoryImpl[T]) FindBy(ctx context.Context, criteria map[string]any) ([]*T, error) {
 	var entities []*T
-	query := r.db.WithContext(ctx)
+	query := r.query(ctx)
 	
 	for key, value := range criteria {
 		query = query.Where(key+" = ?", value)
@@ -80,16 +228,42 @@ func (r *BaseRepositoryImpl[T]) FindBy(ctx context.Context, criteria map[string]
 	return entities, err
 }
 
+// ListDeleted lists the soft-deleted entities matching the criteria that were deleted at or
+// after since, most recently deleted first
+func (r *BaseRepositoryImpl[T]) ListDeleted(ctx context.Context, criteria map[string]any, since time.Time, page, pageSize int) ([]*T, int64, error) {
+	var entities []*T
+	var total int64
+	deleted := func() *gorm.DB {
+		query := r.query(ctx).Unscoped().Model(new(T)).Where("deleted_at IS NOT NULL AND deleted_at >= ?", since)
+		for key, value := range criteria {
+			query = query.Where(key+" = ?", value)
+		}
+		return query
+	}
+	
+	if err := deleted().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	
+	err := deleted().
+		Order("deleted_at DESC, id ASC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&entities).Error
+	
+	return entities, total, err
+}
+
 // ExistsByID checks if an entity exists by ID
 func (r *BaseRepositoryImpl[T]) ExistsByID(ctx context.Context, id string) (bool, error) {
 	var count int64
-	err := r.db.WithContext(ctx).Model(new(T)).Where("id = ?", id).Count(&count).Error
+	err := r.query(ctx).Model(new(T)).Where("id = ?", id).Count(&count).Error
 	return count > 0, err
 }
 
 // WithTx returns a new repository instance with the given transaction
 func (r *BaseRepositoryImpl[T]) WithTx(tx *gorm.DB) domain.BaseRepository[T] {
-	return &BaseRepositoryImpl[T]{db: tx}
+	return r.withDB(tx)
 }
 
 // GetDB returns the database instance
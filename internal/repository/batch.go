@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateBatch inserts the entities in statements of at most BatchSize rows within one
+// transaction, recording each in the audit log. Audited entities only accept ConflictFail, since
+// the rows a skipping or overwriting insert wrote are not known to log them.
+func (r *BaseRepositoryImpl[T]) CreateBatch(ctx context.Context, entities []*T, opts domain.BatchOptions) (int64, error) {
+	if len(entities) == 0 {
+		return 0, nil
+	}
+	for _, entity := range entities {
+		if err := r.claim(ctx, entity); err != nil {
+			return 0, err
+		}
+	}
+	entitySchema, audited := r.auditing()
+	if audited && opts.OnConflict != domain.ConflictFail {
+		return 0, fmt.Errorf("bulk inserts into %s are audited and cannot skip or overwrite conflicting rows", entitySchema.Table)
+	}
+	batchSize := cmp.Or(opts.BatchSize, domain.DefaultBatchSize)
+
+	var written int64
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		query := tx
+		if len(opts.Columns) > 0 {
+			query = query.Select(opts.Columns)
+		}
+		if conflict, ok := onConflict(opts); ok {
+			query = query.Clauses(conflict)
+		}
+		result := query.CreateInBatches(entities, batchSize)
+		if result.Error != nil {
+			return result.Error
+		}
+		written = result.RowsAffected
+		if !audited {
+			return nil
+		}
+
+		now := time.Now()
+		entries := make([]*domain.AuditLog, 0, len(entities))
+		for _, entity := range entities {
+			changes, err := auditChanges(ctx, entitySchema, nil, entity)
+			if err != nil {
+				return err
+			}
+			businessID := auditBusinessID(ctx, entitySchema, reflect.ValueOf(entity).Elem())
+			entry, err := domain.NewAuditLog(ctx, domain.AuditActionCreate, entitySchema.Table, entityID(entity), businessID, changes, now)
+			if err != nil {
+				return err
+			}
+			entries = append(entries, entry)
+		}
+		return tx.CreateInBatches(entries, batchSize).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return written, nil
+}
+
+// UpdateBatch updates the entities within one transaction, checking the version of each and
+// recording the fields they change in the audit log like Update
+func (r *BaseRepositoryImpl[T]) UpdateBatch(ctx context.Context, entities []*T) error {
+	if len(entities) == 0 {
+		return nil
+	}
+	ids := make([]string, len(entities))
+	for i, entity := range entities {
+		if err := r.claim(ctx, entity); err != nil {
+			return err
+		}
+		ids[i] = entityID(entity)
+	}
+	if err := r.ensureAllOwned(ctx, ids); err != nil {
+		return err
+	}
+
+	entitySchema, audited := r.auditing()
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		for _, entity := range entities {
+			if !audited {
+				if err := r.save(tx, entity); err != nil {
+					return err
+				}
+				continue
+			}
+			before, err := r.stored(tx, entityID(entity))
+			if err != nil {
+				return err
+			}
+			if err := r.save(tx, entity); err != nil {
+				return err
+			}
+			if err := r.record(ctx, tx, entitySchema, before, entity); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// onConflict returns the ON CONFLICT clause of a bulk insert, if it has one
+func onConflict(opts domain.BatchOptions) (clause.OnConflict, bool) {
+	conflict := clause.OnConflict{}
+	for _, column := range opts.ConflictColumns {
+		conflict.Columns = append(conflict.Columns, clause.Column{Name: column})
+	}
+	switch opts.OnConflict {
+	case domain.ConflictSkip:
+		conflict.DoNothing = true
+	case domain.ConflictUpdate:
+		if len(opts.UpdateColumns) == 0 {
+			conflict.UpdateAll = true
+		} else {
+			conflict.DoUpdates = clause.AssignmentColumns(opts.UpdateColumns)
+		}
+	default:
+		return conflict, false
+	}
+	return conflict, true
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// blocklistRepositoryImpl implements the BlocklistRepository interface
+type blocklistRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.BlocklistEntry]
+}
+
+// NewBlocklistRepository creates a new booking blocklist repository, scoped to the tenant in the
+// context
+func NewBlocklistRepository(db *gorm.DB) domain.BlocklistRepository {
+	return &blocklistRepositoryImpl{
+		BaseRepositoryImpl: newBaseRepository[domain.BlocklistEntry](db, WithTenantScope()),
+	}
+}
+
+// FindByBusinessID finds all blocklist entries of a business, including expired ones
+func (r *blocklistRepositoryImpl) FindByBusinessID(ctx context.Context, businessID string) ([]*domain.BlocklistEntry, error) {
+	var entries []*domain.BlocklistEntry
+	err := r.query(ctx).
+		Where("business_id = ?", businessID).
+		Order("entry_type ASC, value ASC").
+		Find(&entries).Error
+	return entries, err
+}
+
+// FindMatch finds an active blocklist entry of a business matching any of the keys
+func (r *blocklistRepositoryImpl) FindMatch(ctx context.Context, businessID string, keys []domain.BlocklistKey, at time.Time) (*domain.BlocklistEntry, error) {
+	if len(keys) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	matches := conn(ctx, r.db)
+	for i, key := range keys {
+		if i == 0 {
+			matches = matches.Where("entry_type = ? AND value = ?", key.EntryType, key.Value)
+		} else {
+			matches = matches.Or("entry_type = ? AND value = ?", key.EntryType, key.Value)
+		}
+	}
+
+	var entry domain.BlocklistEntry
+	err := r.query(ctx).
+		Where("business_id = ? AND (expires_at IS NULL OR expires_at > ?)", businessID, at).
+		Where(matches).
+		First(&entry).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// WithTx returns a new repository instance with the given transaction
+func (r *blocklistRepositoryImpl) WithTx(tx *gorm.DB) domain.BaseRepository[domain.BlocklistEntry] {
+	return r.withDB(tx)
+}
+
+// bookingAttemptRepositoryImpl implements the BookingAttemptRepository interface
+type bookingAttemptRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewBookingAttemptRepository creates a new booking attempt repository
+func NewBookingAttemptRepository(db *gorm.DB) domain.BookingAttemptRepository {
+	return &bookingAttemptRepositoryImpl{db: db}
+}
+
+// Record stores a booking attempt
+func (r *bookingAttemptRepositoryImpl) Record(ctx context.Context, attempt *domain.BookingAttemptRecord) error {
+	return conn(ctx, r.db).Create(attempt).Error
+}
+
+// CountByIP counts the booking attempts made from an IP address since a point in time
+func (r *bookingAttemptRepositoryImpl) CountByIP(ctx context.Context, ipAddress string, since time.Time) (int64, error) {
+	var count int64
+	err := conn(ctx, r.db).
+		Model(&domain.BookingAttemptRecord{}).
+		Where("ip_address = ? AND created_at >= ?", ipAddress, since).
+		Count(&count).Error
+	return count, err
+}
+
+// DeleteBefore removes booking attempts older than a point in time
+func (r *bookingAttemptRepositoryImpl) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := conn(ctx, r.db).
+		Where("created_at < ?", before).
+		Delete(&domain.BookingAttemptRecord{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// broadcastTargetsSQL selects the business's upcoming-state appointments overlapping a window,
+// with the contact details used for urgent notices
+const broadcastTargetsSQL = `
+	SELECT a.id AS appointment_id, a.client_id, a.start_time, a.end_time,
+		c.first_name AS client_first_name, c.last_name AS client_last_name,
+		c.phone AS client_phone, c.email AS client_email
+	FROM appointments a
+	JOIN ` + contactClientsSQL + ` c ON c.id = a.client_id
+	WHERE a.business_id = ?
+		AND a.deleted_at IS NULL
+		AND a.status IN ('scheduled', 'confirmed')
+		AND a.start_time < ? AND a.end_time > ?
+	ORDER BY a.start_time ASC`
+
+// broadcastRepositoryImpl implements the BroadcastRepository interface
+type broadcastRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.Broadcast]
+}
+
+// NewBroadcastRepository creates a new broadcast repository, scoped to the tenant in the context
+func NewBroadcastRepository(db *gorm.DB) domain.BroadcastRepository {
+	return &broadcastRepositoryImpl{
+		BaseRepositoryImpl: newBaseRepository[domain.Broadcast](db, WithTenantScope()),
+	}
+}
+
+// FindByBusinessID finds the broadcasts of a business, latest first
+func (r *broadcastRepositoryImpl) FindByBusinessID(ctx context.Context, businessID string) ([]*domain.Broadcast, error) {
+	var broadcasts []*domain.Broadcast
+	err := r.query(ctx).
+		Where("business_id = ?", businessID).
+		Order("created_at DESC").
+		Find(&broadcasts).Error
+	return broadcasts, err
+}
+
+// FindTargets finds the business's scheduled and confirmed appointments overlapping a window
+func (r *broadcastRepositoryImpl) FindTargets(ctx context.Context, businessID string, start, end time.Time) ([]*domain.BroadcastTarget, error) {
+	if err := r.checkBusiness(ctx, businessID); err != nil {
+		return nil, err
+	}
+	var targets []*domain.BroadcastTarget
+	err := conn(ctx, r.db).
+		Raw(broadcastTargetsSQL, businessID, end, start).
+		Scan(&targets).Error
+	return targets, err
+}
+
+// AddRecipients records the recipients of a broadcast and marks their appointments as affected
+func (r *broadcastRepositoryImpl) AddRecipients(ctx context.Context, broadcast *domain.Broadcast, recipients []*domain.BroadcastRecipient) error {
+	if len(recipients) == 0 {
+		return nil
+	}
+	if err := r.claim(ctx, broadcast); err != nil {
+		return err
+	}
+	appointmentIDs := make([]string, len(recipients))
+	for i, recipient := range recipients {
+		appointmentIDs[i] = recipient.AppointmentID
+	}
+
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&recipients).Error; err != nil {
+			return err
+		}
+		return tx.Exec("UPDATE appointments SET broadcast_id = ?, updated_at = ? WHERE id IN ? AND business_id = ?",
+			broadcast.ID, time.Now(), appointmentIDs, broadcast.BusinessID).Error
+	})
+}
+
+// UpdateRecipient saves the delivery or rescheduling outcome of a recipient
+func (r *broadcastRepositoryImpl) UpdateRecipient(ctx context.Context, recipient *domain.BroadcastRecipient) error {
+	if err := r.ensureOwned(ctx, recipient.BroadcastID); err != nil {
+		return err
+	}
+	return conn(ctx, r.db).Save(recipient).Error
+}
+
+// FindRecipients finds the recipients of a broadcast
+func (r *broadcastRepositoryImpl) FindRecipients(ctx context.Context, broadcastID string) ([]*domain.BroadcastRecipient, error) {
+	var recipients []*domain.BroadcastRecipient
+	err := r.scopedThrough(ctx, conn(ctx, r.db), "broadcast_id", &domain.Broadcast{}).
+		Where("broadcast_id = ?", broadcastID).
+		Order("created_at ASC").
+		Find(&recipients).Error
+	return recipients, err
+}
+
+// FindRecipientByToken finds the recipient a rescheduling link was sent to
+func (r *broadcastRepositoryImpl) FindRecipientByToken(ctx context.Context, token string) (*domain.BroadcastRecipient, error) {
+	var recipient domain.BroadcastRecipient
+	err := r.scopedThrough(ctx, conn(ctx, r.db), "broadcast_id", &domain.Broadcast{}).
+		Where("reschedule_token = ?", token).
+		First(&recipient).Error
+	if err != nil {
+		return nil, err
+	}
+	return &recipient, nil
+}
+
+// WithTx returns a new repository instance with the given transaction
+func (r *broadcastRepositoryImpl) WithTx(tx *gorm.DB) domain.BaseRepository[domain.Broadcast] {
+	return r.withDB(tx)
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// businessCloneRepositoryImpl implements the BusinessCloneRepository interface
+type businessCloneRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewBusinessCloneRepository creates a new business clone repository
+func NewBusinessCloneRepository(db *gorm.DB) domain.BusinessCloneRepository {
+	return &businessCloneRepositoryImpl{db: db}
+}
+
+// LoadTemplate loads the configuration of a business, in display order where it has one
+func (r *businessCloneRepositoryImpl) LoadTemplate(ctx context.Context, businessID string) (*domain.BusinessTemplate, error) {
+	db := conn(ctx, r.db)
+	template := &domain.BusinessTemplate{Business: &domain.Business{}}
+	if err := db.Where("id = ?", businessID).First(template.Business).Error; err != nil {
+		return nil, err
+	}
+
+	var settings domain.BusinessSettings
+	err := db.Where("business_id = ?", businessID).First(&settings).Error
+	switch {
+	case err == nil:
+		template.Settings = &settings
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, err
+	}
+
+	ofBusiness := db.Where("business_id = ?", businessID)
+	queries := []struct {
+		dest  any
+		order string
+	}{
+		{&template.Categories, "display_order ASC, name ASC"},
+		{&template.Services, "display_order ASC, name ASC"},
+		{&template.Bundles, "name ASC"},
+		{&template.MessageTemplates, "name ASC"},
+		{&template.LoyaltyPrograms, "created_at ASC"},
+	}
+	for _, query := range queries {
+		if err := ofBusiness.Session(&gorm.Session{}).Order(query.order).Find(query.dest).Error; err != nil {
+			return nil, err
+		}
+	}
+	err = db.Where("business_id = ? AND is_active = ?", businessID, true).Order("created_at ASC").Find(&template.Staff).Error
+	if err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// Create stores the business and its copies in a single transaction. Copies are stored whole so
+// that zero values, such as inactive services, are not replaced by column defaults.
+func (r *businessCloneRepositoryImpl) Create(ctx context.Context, clone *domain.BusinessClone) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Select("*").Create(clone.Business).Error; err != nil {
+			return err
+		}
+		if clone.Settings != nil {
+			if err := tx.Select("*").Create(clone.Settings).Error; err != nil {
+				return err
+			}
+		}
+		copies := []struct {
+			rows  any
+			count int
+		}{
+			{&clone.Categories, len(clone.Categories)},
+			{&clone.Services, len(clone.Services)},
+			{&clone.Bundles, len(clone.Bundles)},
+			{&clone.MessageTemplates, len(clone.MessageTemplates)},
+			{&clone.LoyaltyPrograms, len(clone.LoyaltyPrograms)},
+			{&clone.Staff, len(clone.Staff)},
+			{&clone.Hours, len(clone.Hours)},
+		}
+		for _, copied := range copies {
+			if copied.count == 0 {
+				continue
+			}
+			if err := tx.Select("*").Create(copied.rows).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// businessHoursRepositoryImpl implements the BusinessHoursRepository interface
+type businessHoursRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewBusinessHoursRepository creates a new business hours repository
+func NewBusinessHoursRepository(db *gorm.DB) domain.BusinessHoursRepository {
+	return &businessHoursRepositoryImpl{db: db}
+}
+
+// FindByBusiness finds the hours of a business and of all its locations, the business's first,
+// then by location and weekday
+func (r *businessHoursRepositoryImpl) FindByBusiness(ctx context.Context, businessID string) ([]*domain.BusinessHours, error) {
+	var rows []*domain.BusinessHours
+	err := conn(ctx, r.db).
+		Where("business_id = ?", businessID).
+		Order("location_id NULLS FIRST, weekday").
+		Find(&rows).Error
+	return rows, err
+}
+
+// Replace replaces the hours of a business or one of its locations in a single transaction.
+// Replaced rows are deleted for good, as a day has one row at most.
+func (r *businessHoursRepositoryImpl) Replace(ctx context.Context, businessID string, locationID *string, rows []*domain.BusinessHours) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		replaced := tx.Unscoped().Where("business_id = ?", businessID)
+		if locationID == nil {
+			replaced = replaced.Where("location_id IS NULL")
+		} else {
+			replaced = replaced.Where("location_id = ?", *locationID)
+		}
+		if err := replaced.Delete(&domain.BusinessHours{}).Error; err != nil {
+			return err
+		}
+		if len(rows) > 0 {
+			if err := tx.Create(&rows).Error; err != nil {
+				return err
+			}
+		}
+		if locationID != nil {
+			return nil
+		}
+		return tx.Model(&domain.Business{}).
+			Where("id = ?", businessID).
+			Updates(map[string]any{
+				"business_hours": domain.WeeklyHoursOf(rows).Encode(),
+				"version":        gorm.Expr("version + 1"),
+			}).Error
+	})
+}
@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// businessImportBatchSize bounds the records inserted by a single statement
+const businessImportBatchSize = 500
+
+// importedRecordsInUseSQL counts the appointments using any of the services or staff members an
+// import created
+const importedRecordsInUseSQL = `
+SELECT
+	(SELECT COUNT(*) FROM appointment_services s WHERE s.service_id IN (?) OR s.staff_id IN (?)) +
+	(SELECT COUNT(*) FROM appointments a WHERE a.staff_id IN (?))`
+
+// businessImportRepositoryImpl implements the BusinessImportRepository interface
+type businessImportRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewBusinessImportRepository creates a new business import repository
+func NewBusinessImportRepository(db *gorm.DB) domain.BusinessImportRepository {
+	return &businessImportRepositoryImpl{db: db}
+}
+
+// GetBatch retrieves an import batch by ID
+func (r *businessImportRepositoryImpl) GetBatch(ctx context.Context, id string) (*domain.ImportBatch, error) {
+	var batch domain.ImportBatch
+	if err := conn(ctx, r.db).Where("id = ?", id).First(&batch).Error; err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+// FindBatches finds the import batches of a business, latest first
+func (r *businessImportRepositoryImpl) FindBatches(ctx context.Context, businessID string, limit int) ([]*domain.ImportBatch, error) {
+	var batches []*domain.ImportBatch
+	err := conn(ctx, r.db).
+		Where("business_id = ?", businessID).
+		Order("created_at DESC, id").
+		Limit(limit).
+		Find(&batches).Error
+	return batches, err
+}
+
+// FindServiceNames finds the name of every service of a business
+func (r *businessImportRepositoryImpl) FindServiceNames(ctx context.Context, businessID string) ([]domain.ImportedName, error) {
+	var names []domain.ImportedName
+	err := conn(ctx, r.db).
+		Model(&domain.Service{}).
+		Select("id, name").
+		Where("business_id = ? AND deleted_at IS NULL", businessID).
+		Scan(&names).Error
+	return names, err
+}
+
+// FindCategories finds the service categories of a business
+func (r *businessImportRepositoryImpl) FindCategories(ctx context.Context, businessID string) ([]*domain.ServiceCategory, error) {
+	var categories []*domain.ServiceCategory
+	err := conn(ctx, r.db).
+		Where("business_id = ?", businessID).
+		Order("display_order, name").
+		Find(&categories).Error
+	return categories, err
+}
+
+// FindStaffEmails finds the email of every staff member of a business, by staff ID
+func (r *businessImportRepositoryImpl) FindStaffEmails(ctx context.Context, businessID string) ([]domain.ImportedName, error) {
+	var names []domain.ImportedName
+	err := conn(ctx, r.db).
+		Table("staff s").
+		Select("s.id, u.email AS name").
+		Joins("JOIN users u ON u.id = s.user_id").
+		Where("s.business_id = ? AND s.deleted_at IS NULL", businessID).
+		Scan(&names).Error
+	return names, err
+}
+
+// FindUsersByEmail finds the user accounts with the email addresses, ignoring case
+func (r *businessImportRepositoryImpl) FindUsersByEmail(ctx context.Context, emails []string) ([]*domain.User, error) {
+	if len(emails) == 0 {
+		return nil, nil
+	}
+	lowered := make([]string, len(emails))
+	for i, email := range emails {
+		lowered[i] = strings.ToLower(email)
+	}
+	var users []*domain.User
+	err := conn(ctx, r.db).Where("LOWER(email) IN ?", lowered).Find(&users).Error
+	return users, err
+}
+
+// ImportServices creates the categories, services and batch in a single transaction, so that a
+// failure leaves none of them behind
+func (r *businessImportRepositoryImpl) ImportServices(ctx context.Context, batch *domain.ImportBatch, categories []*domain.ServiceCategory, services []*domain.Service) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if len(categories) > 0 {
+			if err := tx.Omit("Business", "Services").CreateInBatches(categories, businessImportBatchSize).Error; err != nil {
+				return err
+			}
+		}
+		if len(services) > 0 {
+			if err := tx.Omit("Business", "Category").CreateInBatches(services, businessImportBatchSize).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Create(batch).Error
+	})
+}
+
+// ImportStaff creates the user accounts, staff members and batch in a single transaction
+func (r *businessImportRepositoryImpl) ImportStaff(ctx context.Context, batch *domain.ImportBatch, users []*domain.User, staff []*domain.Staff) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if len(users) > 0 {
+			if err := tx.Omit("Businesses", "StaffPositions", "ConnectedAccounts").CreateInBatches(users, businessImportBatchSize).Error; err != nil {
+				return err
+			}
+		}
+		if len(staff) > 0 {
+			if err := tx.Omit("Business", "User").CreateInBatches(staff, businessImportBatchSize).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Create(batch).Error
+	})
+}
+
+// RollBack removes the records a batch created and marks it rolled back in a single
+// transaction. Services, categories and staff are deleted like any other; user accounts are
+// removed for good unless they have since signed in or joined another business.
+func (r *businessImportRepositoryImpl) RollBack(ctx context.Context, batch *domain.ImportBatch) error {
+	records, err := batch.GetRecords()
+	if err != nil {
+		return err
+	}
+
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if len(records.ServiceIDs) > 0 || len(records.StaffIDs) > 0 {
+			var inUse int64
+			serviceIDs, staffIDs := orNone(records.ServiceIDs), orNone(records.StaffIDs)
+			if err := tx.Raw(importedRecordsInUseSQL, serviceIDs, staffIDs, staffIDs).Scan(&inUse).Error; err != nil {
+				return err
+			}
+			if inUse > 0 {
+				return domain.ErrImportBatchInUse
+			}
+		}
+
+		deletions := []struct {
+			model any
+			ids   []string
+		}{
+			{&domain.Service{}, records.ServiceIDs},
+			{&domain.ServiceCategory{}, records.CategoryIDs},
+			{&domain.Staff{}, records.StaffIDs},
+		}
+		for _, deletion := range deletions {
+			if len(deletion.ids) == 0 {
+				continue
+			}
+			if err := tx.Where("id IN ?", deletion.ids).Delete(deletion.model).Error; err != nil {
+				return err
+			}
+		}
+		if len(records.UserIDs) > 0 {
+			err := tx.Unscoped().
+				Where("id IN ? AND clerk_id IS NULL", records.UserIDs).
+				Where("NOT EXISTS (SELECT 1 FROM staff s WHERE s.user_id = users.id AND s.id NOT IN ?)", orNone(records.StaffIDs)).
+				Where("NOT EXISTS (SELECT 1 FROM businesses b WHERE b.user_id = users.id)").
+				Delete(&domain.User{}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Save(batch).Error
+	})
+}
+
+// orNone returns the IDs, or a list matching no row when there are none, for use with IN
+func orNone(ids []string) []string {
+	if len(ids) == 0 {
+		return []string{"00000000-0000-0000-0000-000000000000"}
+	}
+	return ids
+}
@@ -23,7 +23,7 @@ func NewBusinessRepository(db *gorm.DB) domain.BusinessRepository {
 // FindByUserID finds businesses owned by a user
 func (r *businessRepositoryImpl) FindByUserID(ctx context.Context, userID string) ([]*domain.Business, error) {
 	var businesses []*domain.Business
-	err := r.db.WithContext(ctx).
+	err := conn(ctx, r.db).
 		Where("user_id = ? AND deleted_at IS NULL", userID).
 		Find(&businesses).Error
 	return businesses, err
@@ -32,7 +32,7 @@ func (r *businessRepositoryImpl) FindByUserID(ctx context.Context, userID string
 // FindByName finds businesses by name (case-insensitive)
 func (r *businessRepositoryImpl) FindByName(ctx context.Context, name string) ([]*domain.Business, error) {
 	var businesses []*domain.Business
-	err := r.db.WithContext(ctx).
+	err := conn(ctx, r.db).
 		Where("LOWER(name) LIKE ? AND deleted_at IS NULL", "%"+strings.ToLower(name)+"%").
 		Find(&businesses).Error
 	return businesses, err
@@ -41,7 +41,7 @@ func (r *businessRepositoryImpl) FindByName(ctx context.Context, name string) ([
 // ExistsByName checks if a business with the given name exists
 func (r *businessRepositoryImpl) ExistsByName(ctx context.Context, name string) (bool, error) {
 	var count int64
-	err := r.db.WithContext(ctx).
+	err := conn(ctx, r.db).
 		Model(&domain.Business{}).
 		Where("LOWER(name) = ? AND deleted_at IS NULL", strings.ToLower(name)).
 		Count(&count).Error
@@ -54,7 +54,7 @@ func (r *businessRepositoryImpl) FindActiveBusinesses(ctx context.Context, page,
 	var total int64
 	
 	// Count total active businesses
-	if err := r.db.WithContext(ctx).
+	if err := conn(ctx, r.db).
 		Model(&domain.Business{}).
 		Where("is_active = true AND deleted_at IS NULL").
 		Count(&total).Error; err != nil {
@@ -63,7 +63,7 @@ func (r *businessRepositoryImpl) FindActiveBusinesses(ctx context.Context, page,
 	
 	// Get paginated results
 	offset := (page - 1) * pageSize
-	err := r.db.WithContext(ctx).
+	err := conn(ctx, r.db).
 		Where("is_active = true AND deleted_at IS NULL").
 		Offset(offset).
 		Limit(pageSize).
@@ -72,11 +72,45 @@ func (r *businessRepositoryImpl) FindActiveBusinesses(ctx context.Context, page,
 	return businesses, total, err
 }
 
+// Search finds the businesses of every tenant passing a filter, most recently created first
+func (r *businessRepositoryImpl) Search(ctx context.Context, search domain.BusinessSearch, page, pageSize int) ([]*domain.Business, int64, error) {
+	query := conn(ctx, r.db).Model(&domain.Business{}).Where("deleted_at IS NULL")
+	if text := strings.ToLower(strings.TrimSpace(search.Query)); text != "" {
+		pattern := "%" + text + "%"
+		query = query.Where("(LOWER(name) LIKE ? OR LOWER(display_name) LIKE ? OR LOWER(email) LIKE ?)", pattern, pattern, pattern)
+	}
+	if search.Status != nil {
+		switch *search.Status {
+		case domain.BusinessStatusSuspended:
+			query = query.Where("suspended_at IS NOT NULL")
+		case domain.BusinessStatusInactive:
+			query = query.Where("suspended_at IS NULL AND is_active = false")
+		default:
+			query = query.Where("suspended_at IS NULL AND is_active = true")
+		}
+	}
+	if search.Tier != nil {
+		query = query.Where("COALESCE(NULLIF(subscription_tier, ''), ?) = ?", domain.TierFree, *search.Tier)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var businesses []*domain.Business
+	err := query.
+		Order("created_at DESC, id").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&businesses).Error
+	return businesses, total, err
+}
+
 // SearchByLocation finds businesses by city and country
 func (r *businessRepositoryImpl) SearchByLocation(ctx context.Context, city, country string) ([]*domain.Business, error) {
 	var businesses []*domain.Business
 	
-	query := r.db.WithContext(ctx).
+	query := conn(ctx, r.db).
 		Joins("JOIN business_locations bl ON businesses.id = bl.business_id").
 		Where("businesses.is_active = true AND businesses.deleted_at IS NULL AND bl.deleted_at IS NULL")
 	
@@ -96,7 +130,7 @@ func (r *businessRepositoryImpl) SearchByLocation(ctx context.Context, city, cou
 func (r *businessRepositoryImpl) SearchByService(ctx context.Context, serviceName string) ([]*domain.Business, error) {
 	var businesses []*domain.Business
 	
-	err := r.db.WithContext(ctx).
+	err := conn(ctx, r.db).
 		Joins("JOIN services s ON businesses.id = s.business_id").
 		Where("businesses.is_active = true AND businesses.deleted_at IS NULL AND s.deleted_at IS NULL").
 		Where("LOWER(s.name) LIKE ?", "%"+strings.ToLower(serviceName)+"%").
@@ -109,7 +143,7 @@ func (r *businessRepositoryImpl) SearchByService(ctx context.Context, serviceNam
 // GetBusinessWithDetails retrieves a business with all related data
 func (r *businessRepositoryImpl) GetBusinessWithDetails(ctx context.Context, businessID string) (*domain.Business, error) {
 	var business domain.Business
-	err := r.db.WithContext(ctx).
+	err := conn(ctx, r.db).
 		Preload("Locations").
 		Preload("Settings_").
 		Preload("User").
@@ -124,7 +158,7 @@ func (r *businessRepositoryImpl) GetBusinessWithDetails(ctx context.Context, bus
 // GetWithLocations retrieves a business with its locations
 func (r *businessRepositoryImpl) GetWithLocations(ctx context.Context, businessID string) (*domain.Business, error) {
 	var business domain.Business
-	err := r.db.WithContext(ctx).
+	err := conn(ctx, r.db).
 		Preload("Locations", "deleted_at IS NULL").
 		Where("id = ?", businessID).
 		First(&business).Error
@@ -134,6 +168,14 @@ func (r *businessRepositoryImpl) GetWithLocations(ctx context.Context, businessI
 	return &business, nil
 }
 
+// SetDataRegion changes the region a business's data must stay in
+func (r *businessRepositoryImpl) SetDataRegion(ctx context.Context, businessID string, region domain.DataRegion, updatedBy *string) error {
+	return conn(ctx, r.db).
+		Model(&domain.Business{}).
+		Where("id = ?", businessID).
+		Updates(map[string]any{"data_region": region, "updated_by": updatedBy}).Error
+}
+
 // WithTx returns a new repository instance with the given transaction
 func (r *businessRepositoryImpl) WithTx(tx *gorm.DB) domain.BaseRepository[domain.Business] {
 	return &BaseRepositoryImpl[domain.Business]{db: tx}
@@ -144,17 +186,18 @@ type businessLocationRepositoryImpl struct {
 	*BaseRepositoryImpl[domain.BusinessLocation]
 }
 
-// NewBusinessLocationRepository creates a new business location repository
+// NewBusinessLocationRepository creates a new business location repository, scoped to the tenant
+// in the context
 func NewBusinessLocationRepository(db *gorm.DB) domain.BusinessLocationRepository {
 	return &businessLocationRepositoryImpl{
-		BaseRepositoryImpl: &BaseRepositoryImpl[domain.BusinessLocation]{db: db},
+		BaseRepositoryImpl: newBaseRepository[domain.BusinessLocation](db, WithTenantScope()),
 	}
 }
 
 // FindByBusinessID finds all locations for a business
 func (r *businessLocationRepositoryImpl) FindByBusinessID(ctx context.Context, businessID string) ([]*domain.BusinessLocation, error) {
 	var locations []*domain.BusinessLocation
-	err := r.db.WithContext(ctx).
+	err := r.query(ctx).
 		Where("business_id = ? AND deleted_at IS NULL", businessID).
 		Order("is_main DESC, name ASC").
 		Find(&locations).Error
@@ -164,7 +207,7 @@ func (r *businessLocationRepositoryImpl) FindByBusinessID(ctx context.Context, b
 // GetMainLocation retrieves the main location for a business
 func (r *businessLocationRepositoryImpl) GetMainLocation(ctx context.Context, businessID string) (*domain.BusinessLocation, error) {
 	var location domain.BusinessLocation
-	err := r.db.WithContext(ctx).
+	err := r.query(ctx).
 		Where("business_id = ? AND is_main = true AND deleted_at IS NULL", businessID).
 		First(&location).Error
 	if err != nil {
@@ -175,16 +218,16 @@ func (r *businessLocationRepositoryImpl) GetMainLocation(ctx context.Context, bu
 
 // SetMainLocation sets a location as the main location for a business
 func (r *businessLocationRepositoryImpl) SetMainLocation(ctx context.Context, businessID, locationID string) error {
-	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
 		// First, unset all main locations for this business
-		if err := tx.Model(&domain.BusinessLocation{}).
+		if err := r.scoped(ctx, tx).Model(&domain.BusinessLocation{}).
 			Where("business_id = ?", businessID).
 			Update("is_main", false).Error; err != nil {
 			return err
 		}
 		
 		// Then set the specified location as main
-		return tx.Model(&domain.BusinessLocation{}).
+		return r.scoped(ctx, tx).Model(&domain.BusinessLocation{}).
 			Where("id = ? AND business_id = ?", locationID, businessID).
 			Update("is_main", true).Error
 	})
@@ -192,7 +235,7 @@ func (r *businessLocationRepositoryImpl) SetMainLocation(ctx context.Context, bu
 
 // WithTx returns a new repository instance with the given transaction
 func (r *businessLocationRepositoryImpl) WithTx(tx *gorm.DB) domain.BaseRepository[domain.BusinessLocation] {
-	return &BaseRepositoryImpl[domain.BusinessLocation]{db: tx}
+	return r.withDB(tx)
 }
 
 // businessSettingsRepositoryImpl implements the BusinessSettingsRepository interface
@@ -200,17 +243,18 @@ type businessSettingsRepositoryImpl struct {
 	*BaseRepositoryImpl[domain.BusinessSettings]
 }
 
-// NewBusinessSettingsRepository creates a new business settings repository
+// NewBusinessSettingsRepository creates a new business settings repository, scoped to the tenant
+// in the context
 func NewBusinessSettingsRepository(db *gorm.DB) domain.BusinessSettingsRepository {
 	return &businessSettingsRepositoryImpl{
-		BaseRepositoryImpl: &BaseRepositoryImpl[domain.BusinessSettings]{db: db},
+		BaseRepositoryImpl: newBaseRepository[domain.BusinessSettings](db, WithTenantScope()),
 	}
 }
 
 // GetByBusinessID retrieves settings for a business
 func (r *businessSettingsRepositoryImpl) GetByBusinessID(ctx context.Context, businessID string) (*domain.BusinessSettings, error) {
 	var settings domain.BusinessSettings
-	err := r.db.WithContext(ctx).
+	err := r.query(ctx).
 		Where("business_id = ?", businessID).
 		First(&settings).Error
 	if err != nil {
@@ -221,7 +265,7 @@ func (r *businessSettingsRepositoryImpl) GetByBusinessID(ctx context.Context, bu
 
 // UpdateByBusinessID updates settings for a business
 func (r *businessSettingsRepositoryImpl) UpdateByBusinessID(ctx context.Context, businessID string, settings *domain.BusinessSettings) error {
-	return r.db.WithContext(ctx).
+	return r.query(ctx).
 		Model(&domain.BusinessSettings{}).
 		Where("business_id = ?", businessID).
 		Updates(settings).Error
@@ -229,5 +273,5 @@ func (r *businessSettingsRepositoryImpl) UpdateByBusinessID(ctx context.Context,
 
 // WithTx returns a new repository instance with the given transaction
 func (r *businessSettingsRepositoryImpl) WithTx(tx *gorm.DB) domain.BaseRepository[domain.BusinessSettings] {
-	return &BaseRepositoryImpl[domain.BusinessSettings]{db: tx}
+	return r.withDB(tx)
 }
\ No newline at end of file
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// calendarProjectionSQL selects the calendar entries of the appointments matching a scope.
+// This is the five-table join the read model exists to avoid on every calendar load.
+const calendarProjectionSQL = `
+	INSERT INTO calendar_entries (
+		appointment_id, business_id, staff_id, client_id, calendar_date, start_time, end_time, status,
+		client_name, client_phone, staff_name, service_names, total_duration, price, notes, projected_at
+	)
+	SELECT
+		a.id, a.business_id, a.staff_id, a.client_id,
+		(a.start_time AT TIME ZONE COALESCE(b.time_zone, 'UTC'))::date,
+		a.start_time, a.end_time, a.status,
+		c.first_name || ' ' || c.last_name, c.phone,
+		u.first_name || ' ' || u.last_name,
+		COALESCE(svc.names, '[]'::jsonb),
+		COALESCE(svc.duration, (EXTRACT(EPOCH FROM (a.end_time - a.start_time)) / 60)::int),
+		COALESCE(a.actual_price, a.estimated_price),
+		a.notes, NOW()
+	FROM appointments a
+	JOIN businesses b ON b.id = a.business_id
+	JOIN clients c ON c.id = a.client_id
+	JOIN staff s ON s.id = a.staff_id
+	JOIN users u ON u.id = s.user_id
+	LEFT JOIN LATERAL (
+		SELECT jsonb_agg(sv.name ORDER BY aps.created_at) AS names, SUM(aps.duration)::int AS duration
+		FROM appointment_services aps
+		JOIN services sv ON sv.id = aps.service_id
+		WHERE aps.appointment_id = a.id AND aps.deleted_at IS NULL
+	) svc ON TRUE
+	WHERE a.deleted_at IS NULL AND a.%s = ?
+	ON CONFLICT (appointment_id) DO UPDATE SET
+		business_id = EXCLUDED.business_id,
+		staff_id = EXCLUDED.staff_id,
+		client_id = EXCLUDED.client_id,
+		calendar_date = EXCLUDED.calendar_date,
+		start_time = EXCLUDED.start_time,
+		end_time = EXCLUDED.end_time,
+		status = EXCLUDED.status,
+		client_name = EXCLUDED.client_name,
+		client_phone = EXCLUDED.client_phone,
+		staff_name = EXCLUDED.staff_name,
+		service_names = EXCLUDED.service_names,
+		total_duration = EXCLUDED.total_duration,
+		price = EXCLUDED.price,
+		notes = EXCLUDED.notes,
+		projected_at = EXCLUDED.projected_at`
+
+// calendarRepositoryImpl implements the CalendarRepository interface
+type calendarRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewCalendarRepository creates a new calendar read model repository
+func NewCalendarRepository(db *gorm.DB) domain.CalendarRepository {
+	return &calendarRepositoryImpl{db: db}
+}
+
+// FindByDateRange finds the calendar entries of a business between two local dates, inclusive
+func (r *calendarRepositoryImpl) FindByDateRange(ctx context.Context, businessID string, startDate, endDate time.Time, staffID *string) ([]*domain.CalendarEntry, error) {
+	var entries []*domain.CalendarEntry
+
+	query := conn(ctx, r.db).
+		Where("business_id = ? AND calendar_date >= ? AND calendar_date <= ?",
+			businessID, startDate.Format("2006-01-02"), endDate.Format("2006-01-02"))
+	if staffID != nil {
+		query = query.Where("staff_id = ?", *staffID)
+	}
+
+	err := query.
+		Order("calendar_date ASC, staff_id ASC, start_time ASC").
+		Find(&entries).Error
+	return entries, err
+}
+
+// FindByAppointmentID finds the calendar entry of an appointment
+func (r *calendarRepositoryImpl) FindByAppointmentID(ctx context.Context, appointmentID string) (*domain.CalendarEntry, error) {
+	var entry domain.CalendarEntry
+	err := conn(ctx, r.db).
+		Where("appointment_id = ?", appointmentID).
+		First(&entry).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// RefreshAppointment re-projects a single appointment, removing it if it no longer exists
+func (r *calendarRepositoryImpl) RefreshAppointment(ctx context.Context, appointmentID string) error {
+	return r.refresh(ctx, "appointment_id", "id", appointmentID)
+}
+
+// RefreshClient re-projects the appointments of a client, e.g. after the client is renamed
+func (r *calendarRepositoryImpl) RefreshClient(ctx context.Context, clientID string) error {
+	return r.refresh(ctx, "client_id", "client_id", clientID)
+}
+
+// RefreshStaff re-projects the appointments of a staff member
+func (r *calendarRepositoryImpl) RefreshStaff(ctx context.Context, staffID string) error {
+	return r.refresh(ctx, "staff_id", "staff_id", staffID)
+}
+
+// RebuildBusiness re-projects all appointments of a business
+func (r *calendarRepositoryImpl) RebuildBusiness(ctx context.Context, businessID string) error {
+	return r.refresh(ctx, "business_id", "business_id", businessID)
+}
+
+// refresh replaces the entries matching entryColumn with a fresh projection of the
+// appointments matching appointmentColumn. Both column names are fixed by the callers.
+func (r *calendarRepositoryImpl) refresh(ctx context.Context, entryColumn, appointmentColumn, value string) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(fmt.Sprintf("DELETE FROM calendar_entries WHERE %s = ?", entryColumn), value).Error; err != nil {
+			return err
+		}
+		return tx.Exec(fmt.Sprintf(calendarProjectionSQL, appointmentColumn), value).Error
+	})
+}
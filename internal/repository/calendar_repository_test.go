@@ -0,0 +1,74 @@
+//go:build integration
+// +build integration
+
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/repository"
+	"github.com/assimoes/beautix/utils/testdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// projectedAppointment stores the fixture appointment and projects it into the calendar
+func projectedAppointment(t *testing.T) (*testdb.TestDB, *domain.Appointment, domain.CalendarRepository) {
+	t.Helper()
+	ctx := context.Background()
+	db, appointment := appointmentFixture(t)
+	require.NoError(t, repository.NewAppointmentRepository(db.GetDB().DB).Create(ctx, appointment))
+
+	calendar := repository.NewCalendarRepository(db.GetDB().DB)
+	require.NoError(t, calendar.RefreshAppointment(ctx, appointment.ID))
+	return db, appointment, calendar
+}
+
+func TestCalendarRepository_RefreshAppointmentRemovesDeletedAppointments(t *testing.T) {
+	ctx := context.Background()
+	db, appointment, calendar := projectedAppointment(t)
+
+	entry, err := calendar.FindByAppointmentID(ctx, appointment.ID)
+	require.NoError(t, err)
+	assert.Equal(t, appointment.StaffID, entry.StaffID)
+
+	require.NoError(t, repository.NewAppointmentRepository(db.GetDB().DB).Delete(ctx, appointment.ID))
+	require.NoError(t, calendar.RefreshAppointment(ctx, appointment.ID))
+
+	_, err = calendar.FindByAppointmentID(ctx, appointment.ID)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestCalendarRepository_RefreshClientReprojectsRenamedClients(t *testing.T) {
+	ctx := context.Background()
+	db, appointment, calendar := projectedAppointment(t)
+
+	require.NoError(t, db.GetDB().DB.Model(&domain.Client{}).
+		Where("id = ?", appointment.ClientID).
+		Updates(map[string]interface{}{"first_name": "Renamed", "last_name": "Client"}).Error)
+	require.NoError(t, calendar.RefreshClient(ctx, appointment.ClientID))
+
+	entry, err := calendar.FindByAppointmentID(ctx, appointment.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed Client", entry.ClientName)
+}
+
+func TestCalendarRepository_RefreshStaffReprojectsRenamedStaff(t *testing.T) {
+	ctx := context.Background()
+	db, appointment, calendar := projectedAppointment(t)
+
+	// The staff member's name lives on their user
+	var staff domain.Staff
+	require.NoError(t, db.GetDB().DB.First(&staff, "id = ?", appointment.StaffID).Error)
+	require.NoError(t, db.GetDB().DB.Model(&domain.User{}).
+		Where("id = ?", staff.UserID).
+		Updates(map[string]interface{}{"first_name": "Renamed", "last_name": "Stylist"}).Error)
+	require.NoError(t, calendar.RefreshStaff(ctx, appointment.StaffID))
+
+	entry, err := calendar.FindByAppointmentID(ctx, appointment.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed Stylist", entry.StaffName)
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// clientVisitsSQL sums up the completed appointments of each client of a business
+const clientVisitsSQL = `
+	SELECT a.client_id, COUNT(*) AS visits, MAX(a.start_time) AS last_visit,
+		COALESCE(SUM(sc.price_charged), 0) AS spent
+	FROM appointments a
+	LEFT JOIN service_completions sc ON sc.appointment_id = a.id AND sc.deleted_at IS NULL
+	WHERE a.business_id = ? AND a.deleted_at IS NULL AND a.status = 'completed'
+	GROUP BY a.client_id`
+
+// campaignResponsesSQL counts the clients of a campaign who reached each stage in a time range
+const campaignResponsesSQL = `
+	SELECT
+		COUNT(*) FILTER (WHERE cc.created_at >= @from AND cc.created_at < @to) AS targeted,
+		COUNT(*) FILTER (WHERE cc.sent_at >= @from AND cc.sent_at < @to) AS sent,
+		COUNT(*) FILTER (WHERE cc.opened_at >= @from AND cc.opened_at < @to) AS opened,
+		COUNT(*) FILTER (WHERE cc.clicked_at >= @from AND cc.clicked_at < @to) AS clicked,
+		COUNT(*) FILTER (WHERE cc.converted_at >= @from AND cc.converted_at < @to) AS converted,
+		COUNT(*) FILTER (WHERE cc.status = 'unsubscribed') AS unsubscribed
+	FROM campaign_clients cc
+	WHERE cc.campaign_id = @campaign AND cc.deleted_at IS NULL`
+
+// campaignRevenueSQL sums up the visits a campaign's converted clients completed at its
+// business on or after converting, within a time range
+const campaignRevenueSQL = `
+	SELECT COUNT(*) AS visits, COALESCE(SUM(sc.price_charged), 0) AS revenue
+	FROM campaign_clients cc
+	JOIN appointments a ON a.client_id = cc.client_id AND a.business_id = @business
+		AND a.status = 'completed' AND a.deleted_at IS NULL
+	JOIN service_completions sc ON sc.appointment_id = a.id AND sc.deleted_at IS NULL
+	WHERE cc.campaign_id = @campaign AND cc.deleted_at IS NULL AND cc.converted_at IS NOT NULL
+		AND COALESCE(sc.completion_date, a.start_time) >= cc.converted_at
+		AND COALESCE(sc.completion_date, a.start_time) >= @from
+		AND COALESCE(sc.completion_date, a.start_time) < @to`
+
+// campaignClientRepositoryImpl implements the CampaignClientRepository interface
+type campaignClientRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.CampaignClient]
+}
+
+// NewCampaignClientRepository creates a new campaign client repository
+func NewCampaignClientRepository(db *gorm.DB) domain.CampaignClientRepository {
+	return &campaignClientRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.CampaignClient]{db: db},
+	}
+}
+
+// audienceSQL builds the query selecting the IDs of the business's clients in an audience,
+// applying the same criteria as TargetAudience.Matches
+func audienceSQL(businessID string, audience domain.TargetAudience, now time.Time) (string, []any) {
+	conditions := []string{"c.business_id = ?", "c.deleted_at IS NULL", "c.is_active", "c.anonymized_at IS NULL"}
+	args := []any{businessID, businessID}
+	where := func(condition string, values ...any) {
+		conditions = append(conditions, condition)
+		args = append(args, values...)
+	}
+
+	if audience.AcceptsMarketing {
+		where("c.accepts_marketing")
+	}
+	if since := audience.VisitedSince(now); since != nil {
+		where("v.last_visit >= ?", *since)
+	}
+	if since := audience.NotVisitedSince(now); since != nil {
+		where("v.last_visit < ?", *since)
+	}
+	if audience.MinVisits != nil {
+		where("COALESCE(v.visits, 0) >= ?", *audience.MinVisits)
+	}
+	if audience.MaxVisits != nil {
+		where("COALESCE(v.visits, 0) <= ?", *audience.MaxVisits)
+	}
+	if audience.MinSpend != nil {
+		where("COALESCE(v.spent, 0) >= ?", *audience.MinSpend)
+	}
+	if audience.MaxSpend != nil {
+		where("COALESCE(v.spent, 0) <= ?", *audience.MaxSpend)
+	}
+	if audience.BirthdayMonth != nil {
+		where("EXTRACT(MONTH FROM c.date_of_birth) = ?", *audience.BirthdayMonth)
+	}
+	if len(audience.Tags) > 0 {
+		tags := make([]string, len(audience.Tags))
+		for i, tag := range audience.Tags {
+			encoded, _ := json.Marshal([]string{tag})
+			tags[i] = "c.tags @> ?::jsonb"
+			args = append(args, string(encoded))
+		}
+		conditions = append(conditions, "("+strings.Join(tags, " OR ")+")")
+	}
+	if audience.VIPOnly {
+		encoded, _ := json.Marshal([]string{domain.VIPTag})
+		where("c.tags @> ?::jsonb", string(encoded))
+	}
+	if audience.LoyaltyProgramID != nil {
+		membership := []string{"m.client_id = c.id", "m.program_id = ?", "m.is_active", "m.deleted_at IS NULL"}
+		args = append(args, *audience.LoyaltyProgramID)
+		if audience.MinLoyaltyPoints != nil {
+			membership = append(membership, "m.current_points >= ?")
+			args = append(args, *audience.MinLoyaltyPoints)
+		}
+		if audience.LoyaltyTier != nil {
+			membership = append(membership, "m.tier_level = ?")
+			args = append(args, *audience.LoyaltyTier)
+		}
+		conditions = append(conditions,
+			"EXISTS (SELECT 1 FROM client_loyalty_memberships m WHERE "+strings.Join(membership, " AND ")+")")
+	}
+
+	query := `SELECT c.id FROM clients c LEFT JOIN (` + clientVisitsSQL + `) v ON v.client_id = c.id WHERE ` +
+		strings.Join(conditions, " AND ")
+	return query, args
+}
+
+// CountAudience counts the business's clients in the audience at the given time
+func (r *campaignClientRepositoryImpl) CountAudience(ctx context.Context, businessID string, audience domain.TargetAudience, now time.Time) (int64, error) {
+	query, args := audienceSQL(businessID, audience, now)
+	var count int64
+	err := conn(ctx, r.db).
+		Raw("SELECT COUNT(*) FROM ("+query+") audience", args...).
+		Scan(&count).Error
+	return count, err
+}
+
+// TargetClients adds the clients in the campaign's audience at the given time to the
+// campaign, skipping clients targeted already, and returns how many were added
+func (r *campaignClientRepositoryImpl) TargetClients(ctx context.Context, campaign *domain.Campaign, audience domain.TargetAudience, now time.Time, userID *string) (int64, error) {
+	query, args := audienceSQL(campaign.BusinessID, audience, now)
+	result := conn(ctx, r.db).Exec(`
+		INSERT INTO campaign_clients (campaign_id, client_id, status, created_at, created_by)
+		SELECT ?, audience.id, ?, ?, ? FROM (`+query+`) audience
+		ON CONFLICT (campaign_id, client_id) DO NOTHING`,
+		append([]any{campaign.ID, domain.CampaignClientPending, now, userID}, args...)...)
+	return result.RowsAffected, result.Error
+}
+
+// FindByCampaignID finds the clients targeted by a campaign, oldest first
+func (r *campaignClientRepositoryImpl) FindByCampaignID(ctx context.Context, campaignID string) ([]*domain.CampaignClient, error) {
+	var clients []*domain.CampaignClient
+	err := conn(ctx, r.db).
+		Where("campaign_id = ?", campaignID).
+		Order("created_at ASC").
+		Find(&clients).Error
+	return clients, err
+}
+
+// GetCampaignAnalytics aggregates the responses to a campaign between from and to, with the
+// revenue of the visits its converted clients completed
+func (r *campaignClientRepositoryImpl) GetCampaignAnalytics(ctx context.Context, campaign *domain.Campaign, from, to time.Time) (*domain.CampaignAnalytics, error) {
+	args := map[string]any{"campaign": campaign.ID, "business": campaign.BusinessID, "from": from, "to": to}
+	analytics := &domain.CampaignAnalytics{CampaignID: campaign.ID, From: from, To: to}
+	if err := conn(ctx, r.db).Raw(campaignResponsesSQL, args).Scan(analytics).Error; err != nil {
+		return nil, err
+	}
+	if err := conn(ctx, r.db).Raw(campaignRevenueSQL, args).Scan(analytics).Error; err != nil {
+		return nil, err
+	}
+	return analytics, nil
+}
+
+// WithTx returns a new repository instance with the given transaction
+func (r *campaignClientRepositoryImpl) WithTx(tx *gorm.DB) domain.BaseRepository[domain.CampaignClient] {
+	return &BaseRepositoryImpl[domain.CampaignClient]{db: tx}
+}
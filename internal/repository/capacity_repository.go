@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// capacityAlertRepositoryImpl implements the CapacityAlertRepository interface
+type capacityAlertRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewCapacityAlertRepository creates a new capacity alert repository
+func NewCapacityAlertRepository(db *gorm.DB) domain.CapacityAlertRepository {
+	return &capacityAlertRepositoryImpl{db: db}
+}
+
+// MarkNotified records the alert unless an alert for the same date, kind and staff member exists
+func (r *capacityAlertRepositoryImpl) MarkNotified(ctx context.Context, alert *domain.CapacityAlert) (bool, error) {
+	result := conn(ctx, r.db).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(alert)
+	return result.RowsAffected > 0, result.Error
+}
+
+// FindBusinessesWithAppointments lists the businesses with appointments on the calendar between
+// two local dates, inclusive
+func (r *capacityAlertRepositoryImpl) FindBusinessesWithAppointments(ctx context.Context, startDate, endDate time.Time) ([]string, error) {
+	var businessIDs []string
+	err := conn(ctx, r.db).
+		Model(&domain.CalendarEntry{}).
+		Distinct("business_id").
+		Where("calendar_date BETWEEN ? AND ?", startDate.Format("2006-01-02"), endDate.Format("2006-01-02")).
+		Pluck("business_id", &businessIDs).Error
+	return businessIDs, err
+}
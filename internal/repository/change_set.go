@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// UpdateFields writes only the columns the changes touch, zero values included, and returns
+// the updated entity. The audit log records the touched columns whose values changed.
+func (r *BaseRepositoryImpl[T]) UpdateFields(ctx context.Context, id string, changes domain.ChangeSet) (*T, error) {
+	entitySchema, err := r.schema()
+	if err != nil {
+		return nil, err
+	}
+	values, err := changeValues(ctx, entitySchema, changes)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.ensureOwned(ctx, id); err != nil {
+		return nil, err
+	}
+
+	var updated *T
+	err = conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		before, err := r.stored(r.scoped(ctx, tx), id)
+		if err != nil {
+			return err
+		}
+		if before == nil {
+			return gorm.ErrRecordNotFound
+		}
+
+		query := r.scoped(ctx, tx.Model(new(T))).Where("id = ?", id)
+		if entitySchema.LookUpField("version") != nil {
+			values["version"] = gorm.Expr("version + 1")
+			if changes.Version != 0 {
+				query = query.Where("version = ?", changes.Version)
+			}
+		}
+		result := query.Updates(values)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return &domain.ConflictError{EntityType: entitySchema.Table, ID: id, Version: changes.Version}
+		}
+
+		if updated, err = r.stored(tx, id); err != nil {
+			return err
+		}
+		if unauditedTables[entitySchema.Table] {
+			return nil
+		}
+		return r.recordChanges(ctx, tx, entitySchema, before, updated, changes)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// recordChanges records an update made with a change set in the audit log, keeping to the
+// columns the changes touched. Updates changing no value are not recorded.
+func (r *BaseRepositoryImpl[T]) recordChanges(ctx context.Context, tx *gorm.DB, entitySchema *schema.Schema, before, after *T, changes domain.ChangeSet) error {
+	changed, err := auditChanges(ctx, entitySchema, before, after)
+	if err != nil {
+		return err
+	}
+	for column := range changed {
+		if !changes.Has(column) {
+			delete(changed, column)
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	return r.recordEntry(ctx, tx, entitySchema, domain.AuditActionUpdate, after, changed)
+}
+
+// changeValues returns the GORM Updates map of a change set, stamped with the update time and
+// author. Columns the entity does not have, and the bookkeeping columns, cannot be changed.
+func changeValues(ctx context.Context, entitySchema *schema.Schema, changes domain.ChangeSet) (map[string]any, error) {
+	if changes.IsEmpty() {
+		return nil, fmt.Errorf("%w: the update changes no field", domain.ErrValidation)
+	}
+	values := changes.Values()
+	for column := range values {
+		if entitySchema.LookUpField(column) == nil || unauditedColumns[column] {
+			return nil, fmt.Errorf("%w: %s has no changeable column %q", domain.ErrValidation, entitySchema.Table, column)
+		}
+	}
+	if entitySchema.LookUpField("updated_at") != nil {
+		values["updated_at"] = time.Now()
+	}
+	if actorID, ok := domain.ActorFromContext(ctx); ok && entitySchema.LookUpField("updated_by") != nil {
+		values["updated_by"] = actorID
+	}
+	return values, nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// removableAttachmentsSQL finds the attachments whose files can be removed: pending ones abandoned
+// before the cutoff, rejected ones and deleted ones
+const removableAttachmentsSQL = `
+	SELECT a.* FROM client_attachments a
+	WHERE a.deleted_at IS NULL AND (
+		(a.status = 'pending' AND a.upload_expires_at < @cutoff)
+		OR a.status IN ('rejected', 'deleted')
+	)
+	ORDER BY a.created_at ASC, a.id ASC
+	LIMIT @limit`
+
+// clientAttachmentRepositoryImpl implements the ClientAttachmentRepository interface
+type clientAttachmentRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.ClientAttachment]
+}
+
+// NewClientAttachmentRepository creates a new client attachment repository, scoped to the tenant
+// in the context
+func NewClientAttachmentRepository(db *gorm.DB) domain.ClientAttachmentRepository {
+	return &clientAttachmentRepositoryImpl{
+		BaseRepositoryImpl: newBaseRepository[domain.ClientAttachment](db, WithTenantScope()),
+	}
+}
+
+// FindByClient finds the stored attachments of a client, newest first, only those of an
+// appointment when one is given
+func (r *clientAttachmentRepositoryImpl) FindByClient(ctx context.Context, clientID string, appointmentID *string) ([]*domain.ClientAttachment, error) {
+	attachments := []*domain.ClientAttachment{}
+	query := r.query(ctx).
+		Where("client_id = ? AND status = ?", clientID, domain.ClientAttachmentStored)
+	if appointmentID != nil {
+		query = query.Where("appointment_id = ?", *appointmentID)
+	}
+	err := query.Order("created_at DESC, id DESC").Find(&attachments).Error
+	return attachments, err
+}
+
+// FindUsableInMarketing finds the stored photos of a business whose clients agreed to their use
+// in marketing, newest first
+func (r *clientAttachmentRepositoryImpl) FindUsableInMarketing(ctx context.Context, businessID string) ([]*domain.ClientAttachment, error) {
+	attachments := []*domain.ClientAttachment{}
+	err := r.query(ctx).
+		Where("business_id = ? AND status = ? AND kind <> ? AND marketing_consent_at IS NOT NULL",
+			businessID, domain.ClientAttachmentStored, domain.ClientAttachmentDocument).
+		Order("created_at DESC, id DESC").
+		Find(&attachments).Error
+	return attachments, err
+}
+
+// SumUsage totals the bytes of a business's attachments counting against its quota
+func (r *clientAttachmentRepositoryImpl) SumUsage(ctx context.Context, businessID string) (int64, error) {
+	var total int64
+	err := r.query(ctx).Model(&domain.ClientAttachment{}).
+		Where("business_id = ? AND status IN ?", businessID,
+			[]domain.ClientAttachmentStatus{domain.ClientAttachmentPending, domain.ClientAttachmentStored}).
+		Select("COALESCE(SUM(size_bytes), 0)").
+		Scan(&total).Error
+	return total, err
+}
+
+// FindRemovable finds the attachments whose files can be removed, across businesses and oldest
+// first
+func (r *clientAttachmentRepositoryImpl) FindRemovable(ctx context.Context, abandonedBefore time.Time, limit int) ([]*domain.ClientAttachment, error) {
+	if err := r.checkUnscoped(ctx); err != nil {
+		return nil, err
+	}
+	attachments := []*domain.ClientAttachment{}
+	err := conn(ctx, r.db).Raw(removableAttachmentsSQL, map[string]any{
+		"cutoff": abandonedBefore,
+		"limit":  limit,
+	}).Scan(&attachments).Error
+	return attachments, err
+}
+
+// WithTx returns a new repository instance with the given transaction
+func (r *clientAttachmentRepositoryImpl) WithTx(tx *gorm.DB) domain.BaseRepository[domain.ClientAttachment] {
+	return r.withDB(tx)
+}
@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// clientDataRepositoryImpl implements the ClientDataRepository interface
+type clientDataRepositoryImpl struct {
+	db      *gorm.DB
+	clients *BaseRepositoryImpl[domain.Client]
+}
+
+// NewClientDataRepository creates a new client data repository, scoped to the tenant in the
+// context
+func NewClientDataRepository(db *gorm.DB) domain.ClientDataRepository {
+	return &clientDataRepositoryImpl{db: db, clients: newBaseRepository[domain.Client](db, WithTenantScope())}
+}
+
+// FindExport collects the profile, appointments, completions, loyalty memberships and campaign
+// messages of a client. Clients of another business than the tenant's are not found, and the
+// rest of the export hangs off the client.
+func (r *clientDataRepositoryImpl) FindExport(ctx context.Context, clientID string) (*domain.ClientDataExport, error) {
+	db := conn(ctx, r.db)
+	export := &domain.ClientDataExport{
+		Appointments:       []domain.ClientAppointmentExport{},
+		Completions:        []domain.ClientCompletionExport{},
+		LoyaltyMemberships: []domain.ClientLoyaltyMembershipExport{},
+		CampaignMessages:   []domain.ClientCampaignMessageExport{},
+	}
+
+	result := r.clients.query(ctx).Table("clients").
+		Select(`id, business_id, first_name, last_name, email, phone, date_of_birth, address_line1, city,
+			postal_code, country, notes, allergies, health_conditions, referral_source, accepts_marketing,
+			is_active, created_at, anonymized_at`).
+		Where("id = ? AND deleted_at IS NULL", clientID).
+		Limit(1).
+		Scan(&export.Profile)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	err := db.Table("appointments a").
+		Select(`a.id, a.start_time, a.end_time, a.status, a.notes, a.estimated_price, a.actual_price,
+			a.payment_status, a.cancellation_reason,
+			COALESCE(string_agg(s.name, ', ' ORDER BY s.name), '') AS services`).
+		Joins("LEFT JOIN appointment_services aps ON aps.appointment_id = a.id AND aps.deleted_at IS NULL").
+		Joins("LEFT JOIN services s ON s.id = aps.service_id").
+		Where("a.client_id = ? AND a.deleted_at IS NULL", clientID).
+		Group("a.id").
+		Order("a.start_time").
+		Scan(&export.Appointments).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Table("service_completions sc").
+		Select("sc.appointment_id, sc.price_charged, sc.payment_method, sc.completion_date, sc.actual_duration").
+		Joins("JOIN appointments a ON a.id = sc.appointment_id").
+		Where("a.client_id = ? AND sc.deleted_at IS NULL", clientID).
+		Order("sc.completion_date").
+		Scan(&export.Completions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Table("client_loyalty_memberships m").
+		Select(`lp.name AS program_name, m.current_points, m.visits_count, m.total_spent, m.tier_level,
+			m.join_date, m.expiry_date, m.is_active`).
+		Joins("JOIN loyalty_programs lp ON lp.id = m.program_id").
+		Where("m.client_id = ? AND m.deleted_at IS NULL", clientID).
+		Order("m.join_date").
+		Scan(&export.LoyaltyMemberships).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Table("campaign_messages cm").
+		Select("c.name AS campaign_name, cm.message_type, cm.message_content, cm.scheduled_time, cm.sent_time, cm.status").
+		Joins("JOIN campaigns c ON c.id = cm.campaign_id").
+		Where("cm.client_id = ? AND cm.deleted_at IS NULL", clientID).
+		Order("cm.scheduled_time").
+		Scan(&export.CampaignMessages).Error
+	if err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
+// Anonymize removes the personal data of a client. The client row is kept with placeholder
+// names so that appointments, completions, loyalty balances and the business's statistics stay
+// intact; free text that may describe the client is cleared from the rows around it, and their
+// attachments are deleted for the cleanup to remove. Invoices are a legal record and are kept as
+// issued. Clients of another business than the tenant's are not found, and nothing is changed.
+func (r *clientDataRepositoryImpl) Anonymize(ctx context.Context, clientID string, at time.Time, by *string) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := r.clients.scoped(ctx, tx.Table("clients")).Where("id = ?", clientID).Updates(map[string]any{
+			"first_name":            domain.AnonymizedFirstName,
+			"last_name":             domain.AnonymizedLastName,
+			"email":                 nil,
+			"phone":                 nil,
+			"date_of_birth":         nil,
+			"address_line1":         nil,
+			"city":                  nil,
+			"postal_code":           nil,
+			"country":               nil,
+			"notes":                 nil,
+			"allergies":             nil,
+			"health_conditions":     nil,
+			"referral_source":       nil,
+			"user_id":               nil,
+			"guardian_client_id":    nil,
+			"guardian_relationship": nil,
+			"guardian_consent_at":   nil,
+			"accepts_marketing":     false,
+			"is_active":             false,
+			"anonymized_at":         at,
+			"updated_at":            at,
+			"updated_by":            by,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		// Minors the client was the guardian of lose the link, not their own data
+		err := tx.Table("clients").Where("guardian_client_id = ?", clientID).Updates(map[string]any{
+			"guardian_client_id":    nil,
+			"guardian_relationship": nil,
+			"updated_at":            at,
+			"updated_by":            by,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		appointments := tx.Table("appointments").Select("id").Where("client_id = ?", clientID)
+		statements := []*gorm.DB{
+			tx.Table("appointments").Where("client_id = ?", clientID).
+				Updates(map[string]any{"notes": nil, "cancellation_reason": nil}),
+			tx.Table("appointment_services").Where("appointment_id IN (?)", appointments).
+				Update("notes", nil),
+			tx.Exec("DELETE FROM appointment_notes WHERE appointment_id IN (?)", appointments),
+			tx.Table("service_records").Where("client_id = ?", clientID).
+				Updates(map[string]any{"field_values": gorm.Expr("'{}'::jsonb"), "notes": nil}),
+			tx.Table("reviews").Where("client_id = ?", clientID).
+				Update("comment", nil),
+			tx.Table("waiting_list").Where("client_id = ?", clientID).
+				Update("notes", nil),
+			tx.Table("campaign_messages").Where("client_id = ?", clientID).
+				Updates(map[string]any{"message_content": "", "error_message": nil}),
+			tx.Table("appointment_confirmations").Where("client_id = ?", clientID).
+				Update("recipient", ""),
+			tx.Table("calendar_entries").Where("client_id = ?", clientID).
+				Updates(map[string]any{
+					"client_name":  domain.AnonymizedFirstName + " " + domain.AnonymizedLastName,
+					"client_phone": nil,
+					"notes":        nil,
+				}),
+			tx.Table("client_attachments").Where("client_id = ? AND status IN ?", clientID,
+				[]domain.ClientAttachmentStatus{domain.ClientAttachmentPending, domain.ClientAttachmentStored}).
+				Updates(map[string]any{
+					"status":               domain.ClientAttachmentDeleted,
+					"caption":              nil,
+					"marketing_consent_at": nil,
+					"marketing_consent_by": nil,
+					"updated_at":           at,
+					"updated_by":           by,
+				}),
+		}
+		for _, statement := range statements {
+			if statement.Error != nil {
+				return statement.Error
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// clientGuardianRepositoryImpl implements the ClientGuardianRepository interface
+type clientGuardianRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewClientGuardianRepository creates a new client guardian repository
+func NewClientGuardianRepository(db *gorm.DB) domain.ClientGuardianRepository {
+	return &clientGuardianRepositoryImpl{db: db}
+}
+
+// SetGuardian links the client to a guardian, or unlinks it when guardianID is nil
+func (r *clientGuardianRepositoryImpl) SetGuardian(ctx context.Context, clientID string, guardianID, relationship *string, consentAt *time.Time) error {
+	return r.update(ctx, &domain.Client{}, clientID, map[string]any{
+		"guardian_client_id":    guardianID,
+		"guardian_relationship": relationship,
+		"guardian_consent_at":   consentAt,
+		"updated_at":            time.Now(),
+	})
+}
+
+// SetConsent records when the guardian consented to the client being treated
+func (r *clientGuardianRepositoryImpl) SetConsent(ctx context.Context, clientID string, consentAt time.Time) error {
+	return r.update(ctx, &domain.Client{}, clientID, map[string]any{
+		"guardian_consent_at": consentAt,
+		"updated_at":          time.Now(),
+	})
+}
+
+// FindDependents finds the clients a client is the guardian of
+func (r *clientGuardianRepositoryImpl) FindDependents(ctx context.Context, guardianID string) ([]*domain.Client, error) {
+	var clients []*domain.Client
+	err := conn(ctx, r.db).
+		Where("guardian_client_id = ? AND deleted_at IS NULL", guardianID).
+		Order("first_name ASC, last_name ASC").
+		Find(&clients).Error
+	return clients, err
+}
+
+// FindService finds a service by ID
+func (r *clientGuardianRepositoryImpl) FindService(ctx context.Context, serviceID string) (*domain.Service, error) {
+	var service domain.Service
+	if err := conn(ctx, r.db).First(&service, "id = ?", serviceID).Error; err != nil {
+		return nil, err
+	}
+	return &service, nil
+}
+
+// SetServiceMinAge sets the minimum client age of a service, or clears it when minAge is nil
+func (r *clientGuardianRepositoryImpl) SetServiceMinAge(ctx context.Context, serviceID string, minAge *int) error {
+	return r.update(ctx, &domain.Service{}, serviceID, map[string]any{
+		"min_age":    minAge,
+		"updated_at": time.Now(),
+	})
+}
+
+// update updates the columns of a single record, reporting a missing record as not found. The
+// client and service models have fields without columns, so only the given columns are written.
+func (r *clientGuardianRepositoryImpl) update(ctx context.Context, model any, id string, columns map[string]any) error {
+	result := conn(ctx, r.db).
+		Model(model).
+		Where("id = ? AND deleted_at IS NULL", id).
+		Updates(columns)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
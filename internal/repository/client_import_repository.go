@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// clientImportBatchSize bounds the clients inserted by a single statement
+const clientImportBatchSize = 500
+
+// clientInsertColumns are the client fields backed by columns of the clients table
+var clientInsertColumns = []string{
+	"BusinessID", "UserID", "FirstName", "LastName", "Email", "Phone", "DateOfBirth", "Notes", "Allergies",
+	"ReferralSource", "IsActive", "CreatedAt", "CreatedBy", "UpdatedAt", "UpdatedBy",
+}
+
+// clientImportRepositoryImpl implements the ClientImportRepository interface
+type clientImportRepositoryImpl struct {
+	db      *gorm.DB
+	clients *BaseRepositoryImpl[domain.Client]
+}
+
+// NewClientImportRepository creates a new client import repository, scoped to the tenant in the
+// context
+func NewClientImportRepository(db *gorm.DB) domain.ClientImportRepository {
+	return &clientImportRepositoryImpl{db: db, clients: newBaseRepository[domain.Client](db, WithTenantScope())}
+}
+
+// FindContacts finds the email and phone of every client of a business
+func (r *clientImportRepositoryImpl) FindContacts(ctx context.Context, businessID string) ([]domain.ClientContact, error) {
+	var contacts []domain.ClientContact
+	err := r.clients.query(ctx).
+		Model(&domain.Client{}).
+		Select("id, email, phone").
+		Where("business_id = ? AND deleted_at IS NULL", businessID).
+		Scan(&contacts).Error
+	return contacts, err
+}
+
+// CreateClients creates the clients in a single transaction, so that a failure leaves none of
+// them behind, recording each in the audit log
+func (r *clientImportRepositoryImpl) CreateClients(ctx context.Context, clients []*domain.Client) error {
+	_, err := r.clients.CreateBatch(ctx, clients, domain.BatchOptions{
+		BatchSize: clientImportBatchSize,
+		Columns:   clientInsertColumns,
+	})
+	return err
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// clientStandingsSQL sums up the completed appointments and loyalty points earned by each active
+// client of a business in a period
+const clientStandingsSQL = `
+	SELECT c.id AS client_id, COALESCE(v.visits, 0) AS visits, COALESCE(v.spent, 0) AS spent,
+		v.last_visit, COALESCE(p.points, 0) AS points
+	FROM clients c
+	LEFT JOIN (
+		SELECT a.client_id, COUNT(DISTINCT a.id) AS visits, MAX(a.start_time) AS last_visit,
+			COALESCE(SUM(sc.price_charged), 0) AS spent
+		FROM appointments a
+		LEFT JOIN service_completions sc ON sc.appointment_id = a.id AND sc.deleted_at IS NULL
+		WHERE a.business_id = @business AND a.deleted_at IS NULL AND a.status = 'completed'
+			AND a.start_time >= @from AND a.start_time < @to
+		GROUP BY a.client_id
+	) v ON v.client_id = c.id
+	LEFT JOIN (
+		SELECT m.client_id, SUM(t.points) AS points
+		FROM loyalty_transactions t
+		JOIN client_loyalty_memberships m ON m.id = t.membership_id
+		WHERE t.deleted_at IS NULL AND t.transaction_type IN @earned
+			AND t.created_at >= @from AND t.created_at < @to
+		GROUP BY m.client_id
+	) p ON p.client_id = c.id
+	WHERE c.business_id = @business AND c.deleted_at IS NULL AND c.is_active AND c.anonymized_at IS NULL
+		AND (v.client_id IS NOT NULL OR p.client_id IS NOT NULL)`
+
+// clientLeaderboardRepositoryImpl implements the ClientLeaderboardRepository interface
+type clientLeaderboardRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewClientLeaderboardRepository creates a new client leaderboard repository
+func NewClientLeaderboardRepository(db *gorm.DB) domain.ClientLeaderboardRepository {
+	return &clientLeaderboardRepositoryImpl{db: db}
+}
+
+// FindStandings sums up what each active client of the business did in the period, leaving out
+// clients who did nothing
+func (r *clientLeaderboardRepositoryImpl) FindStandings(ctx context.Context, businessID string, period domain.DateRange) ([]*domain.ClientStanding, error) {
+	var standings []*domain.ClientStanding
+	err := conn(ctx, r.db).Raw(clientStandingsSQL, map[string]any{
+		"business": businessID,
+		"from":     period.Start,
+		"to":       period.End,
+		"earned":   []domain.LoyaltyTransactionType{domain.LoyaltyTransactionEarn, domain.LoyaltyTransactionReferral},
+	}).Scan(&standings).Error
+	return standings, err
+}
+
+// FindBusinessesWithVIPRules finds the businesses whose VIP rules are enabled
+func (r *clientLeaderboardRepositoryImpl) FindBusinessesWithVIPRules(ctx context.Context) ([]string, error) {
+	var businessIDs []string
+	err := conn(ctx, r.db).Model(&domain.BusinessSettings{}).
+		Where("vip_rules IS NOT NULL AND (vip_rules->>'enabled')::boolean").
+		Pluck("business_id", &businessIDs).Error
+	return businessIDs, err
+}
+
+// SetVIPClients gives VIPTag to exactly the given clients of the business, taking it from the
+// others, in a single transaction. Clients with MaxClientTags tags already are not tagged.
+func (r *clientLeaderboardRepositoryImpl) SetVIPClients(ctx context.Context, businessID string, clientIDs []string) (int64, int64, error) {
+	tag, _ := json.Marshal([]string{domain.VIPTag})
+	var tagged, untagged int64
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		untag := tx.Model(&domain.Client{}).
+			Where("business_id = ? AND tags @> ?::jsonb", businessID, string(tag))
+		if len(clientIDs) > 0 {
+			untag = untag.Where("id NOT IN ?", clientIDs)
+		}
+		result := untag.UpdateColumns(map[string]any{
+			"tags":       gorm.Expr("tags - ?::text", domain.VIPTag),
+			"updated_at": now,
+			"version":    gorm.Expr("version + 1"),
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		untagged = result.RowsAffected
+
+		if len(clientIDs) == 0 {
+			return nil
+		}
+		result = tx.Model(&domain.Client{}).
+			Where("business_id = ? AND id IN ?", businessID, clientIDs).
+			Where("NOT COALESCE(tags, '[]'::jsonb) @> ?::jsonb", string(tag)).
+			Where("jsonb_array_length(COALESCE(tags, '[]'::jsonb)) < ?", domain.MaxClientTags).
+			UpdateColumns(map[string]any{
+				"tags":       gorm.Expr("COALESCE(tags, '[]'::jsonb) || ?::jsonb", string(tag)),
+				"updated_at": now,
+				"version":    gorm.Expr("version + 1"),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		tagged = result.RowsAffected
+		return nil
+	})
+	return tagged, untagged, err
+}
@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// clientMergeTables are the tables whose client_id is moved to the kept client as they are. The
+// loyalty memberships, campaign records and referrals a client can only have one of are
+// reconciled first.
+var clientMergeTables = []string{
+	"appointments",
+	"appointment_confirmations",
+	"appointment_quotes",
+	"broadcast_recipients",
+	"calendar_entries",
+	"campaign_clients",
+	"campaign_messages",
+	"client_attachments",
+	"client_loyalty_memberships",
+	"client_memberships",
+	"front_desk_tasks",
+	"invoices",
+	"membership_credit_notes",
+	"membership_invoices",
+	"reviews",
+	"service_records",
+	"waiting_list",
+}
+
+// foldLoyaltyMembershipsSQL adds the points, visits and spend of the duplicate's memberships to
+// the kept client's memberships of the same programs
+const foldLoyaltyMembershipsSQL = `
+	UPDATE client_loyalty_memberships s
+	SET current_points = s.current_points + d.current_points, visits_count = s.visits_count + d.visits_count,
+		total_spent = s.total_spent + d.total_spent, join_date = LEAST(s.join_date, d.join_date),
+		updated_at = @at, updated_by = @by
+	FROM client_loyalty_memberships d
+	WHERE s.client_id = @client AND d.client_id = @duplicate AND s.program_id = d.program_id
+		AND s.deleted_at IS NULL AND d.deleted_at IS NULL`
+
+// moveLoyaltyTransactionsSQL moves the points history of the folded memberships along with them
+const moveLoyaltyTransactionsSQL = `
+	UPDATE loyalty_transactions t
+	SET membership_id = s.id
+	FROM client_loyalty_memberships d
+	JOIN client_loyalty_memberships s ON s.program_id = d.program_id AND s.client_id = @client AND s.deleted_at IS NULL
+	WHERE t.membership_id = d.id AND d.client_id = @duplicate AND d.deleted_at IS NULL`
+
+// deleteFoldedMembershipsSQL deletes the duplicate's memberships once folded into the kept
+// client's
+const deleteFoldedMembershipsSQL = `
+	UPDATE client_loyalty_memberships d
+	SET deleted_at = @at, deleted_by = @by
+	WHERE d.client_id = @duplicate AND d.deleted_at IS NULL AND EXISTS (
+		SELECT 1 FROM client_loyalty_memberships s
+		WHERE s.client_id = @client AND s.program_id = d.program_id AND s.deleted_at IS NULL
+	)`
+
+// clientMembershipConflictsSQL counts the plans both clients hold a current membership of
+const clientMembershipConflictsSQL = `
+	SELECT COUNT(*) FROM client_memberships d
+	JOIN client_memberships s ON s.plan_id = d.plan_id AND s.client_id = @client
+		AND s.status <> 'cancelled' AND s.deleted_at IS NULL
+	WHERE d.client_id = @duplicate AND d.status <> 'cancelled' AND d.deleted_at IS NULL`
+
+// clientMergeRepositoryImpl implements the ClientMergeRepository interface
+type clientMergeRepositoryImpl struct {
+	db      *gorm.DB
+	clients *BaseRepositoryImpl[domain.Client]
+}
+
+// NewClientMergeRepository creates a new client merge repository, scoped to the tenant in the
+// context
+func NewClientMergeRepository(db *gorm.DB) domain.ClientMergeRepository {
+	return &clientMergeRepositoryImpl{db: db, clients: newBaseRepository[domain.Client](db, WithTenantScope())}
+}
+
+// FindCandidates finds the clients of a business that are not anonymized
+func (r *clientMergeRepositoryImpl) FindCandidates(ctx context.Context, businessID string) ([]*domain.Client, error) {
+	var clients []*domain.Client
+	err := r.clients.query(ctx).
+		Where("business_id = ? AND anonymized_at IS NULL", businessID).
+		Order("created_at ASC, id ASC").
+		Find(&clients).Error
+	return clients, err
+}
+
+// Merge stores the client, which has absorbed the duplicate, and moves everything held about the
+// duplicate to it in a single transaction, then deletes the duplicate. It returns how many rows
+// were moved.
+func (r *clientMergeRepositoryImpl) Merge(ctx context.Context, client *domain.Client, duplicateID string, at time.Time, by *string) (int64, error) {
+	if err := r.clients.claim(ctx, client); err != nil {
+		return 0, err
+	}
+	if err := r.clients.ensureOwned(ctx, duplicateID); err != nil {
+		return 0, err
+	}
+	var moved int64
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		params := map[string]any{"client": client.ID, "duplicate": duplicateID, "at": at, "by": by}
+
+		result := r.clients.scoped(ctx, tx).Table("clients").Where("id = ? AND deleted_at IS NULL", duplicateID).Updates(map[string]any{
+			"merged_into_id": client.ID,
+			"is_active":      false,
+			"updated_at":     at,
+			"updated_by":     by,
+			"deleted_at":     at,
+			"deleted_by":     by,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		var conflicts int64
+		if err := tx.Raw(clientMembershipConflictsSQL, params).Scan(&conflicts).Error; err != nil {
+			return err
+		}
+		if conflicts > 0 {
+			return domain.ErrClientMembershipConflict
+		}
+
+		statements := []*gorm.DB{
+			tx.Exec(foldLoyaltyMembershipsSQL, params),
+			tx.Exec(moveLoyaltyTransactionsSQL, params),
+			tx.Exec(deleteFoldedMembershipsSQL, params),
+			tx.Exec(`DELETE FROM campaign_clients d WHERE d.client_id = @duplicate AND EXISTS (
+				SELECT 1 FROM campaign_clients s WHERE s.client_id = @client AND s.campaign_id = d.campaign_id)`, params),
+			// A client cannot refer themselves, and is referred to a business once
+			tx.Exec(`DELETE FROM referrals WHERE (referrer_client_id = @duplicate AND referred_client_id = @client)
+				OR (referrer_client_id = @client AND referred_client_id = @duplicate)`, params),
+			tx.Exec(`DELETE FROM referrals d WHERE d.referred_client_id = @duplicate AND EXISTS (
+				SELECT 1 FROM referrals s WHERE s.referred_client_id = @client AND s.business_id = d.business_id)`, params),
+		}
+		for _, statement := range statements {
+			if statement.Error != nil {
+				return statement.Error
+			}
+		}
+
+		moves := []*gorm.DB{
+			tx.Table("referrals").Where("referrer_client_id = ?", duplicateID).Update("referrer_client_id", client.ID),
+			tx.Table("referrals").Where("referred_client_id = ?", duplicateID).Update("referred_client_id", client.ID),
+			tx.Table("gift_cards").Where("purchaser_client_id = ?", duplicateID).Update("purchaser_client_id", client.ID),
+			tx.Table("clients").Where("guardian_client_id = ? AND id <> ?", duplicateID, client.ID).
+				Updates(map[string]any{"guardian_client_id": client.ID, "updated_at": at, "updated_by": by}),
+		}
+		for _, table := range clientMergeTables {
+			query := tx.Table(table).Where("client_id = ?", duplicateID)
+			if table == "client_loyalty_memberships" {
+				// The folded memberships stay with the deleted duplicate
+				query = query.Where("deleted_at IS NULL")
+			}
+			moves = append(moves, query.Update("client_id", client.ID))
+		}
+		for _, move := range moves {
+			if move.Error != nil {
+				return move.Error
+			}
+			moved += move.RowsAffected
+		}
+
+		client.UpdatedAt = at
+		return r.clients.withDB(tx).save(tx, client)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return moved, nil
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// portalVisitSelectSQL selects past visits with their payment, loyalty and invoice details
+const portalVisitSelectSQL = `
+	SELECT
+		a.id AS appointment_id, a.business_id, b.name AS business_name, b.tax_id AS business_tax_id,
+		COALESCE(b.currency, 'EUR') AS currency,
+		a.client_id, c.first_name || ' ' || c.last_name AS client_name,
+		u.first_name || ' ' || u.last_name AS staff_name,
+		a.start_time, a.end_time, a.status,
+		sc.price_charged AS amount_paid, sc.payment_method,
+		COALESCE(lt.points, 0) AS loyalty_points,
+		i.id AS invoice_id
+	FROM appointments a
+	JOIN businesses b ON b.id = a.business_id
+	JOIN clients c ON c.id = a.client_id
+	JOIN staff s ON s.id = a.staff_id
+	JOIN users u ON u.id = s.user_id
+	LEFT JOIN LATERAL (
+		SELECT price_charged, payment_method FROM service_completions
+		WHERE appointment_id = a.id AND deleted_at IS NULL
+		ORDER BY created_at DESC LIMIT 1
+	) sc ON TRUE
+	LEFT JOIN LATERAL (
+		SELECT SUM(points) AS points FROM loyalty_transactions
+		WHERE appointment_id = a.id AND transaction_type = 'earn' AND deleted_at IS NULL
+	) lt ON TRUE
+	LEFT JOIN invoices i ON i.appointment_id = a.id AND i.deleted_at IS NULL
+	WHERE a.client_id IN ? AND a.deleted_at IS NULL`
+
+// clientPortalRepositoryImpl implements the ClientPortalRepository interface
+type clientPortalRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewClientPortalRepository creates a new client portal repository
+func NewClientPortalRepository(db *gorm.DB) domain.ClientPortalRepository {
+	return &clientPortalRepositoryImpl{db: db}
+}
+
+// FindClientIDsByUserID finds the client records linked to a user account, one per business,
+// along with the minors the user is the guardian of
+func (r *clientPortalRepositoryImpl) FindClientIDsByUserID(ctx context.Context, userID string) ([]string, error) {
+	var clientIDs []string
+	err := conn(ctx, r.db).
+		Model(&domain.Client{}).
+		Where("deleted_at IS NULL").
+		Where("user_id = ? OR guardian_client_id IN (SELECT id FROM clients WHERE user_id = ? AND deleted_at IS NULL)", userID, userID).
+		Pluck("id", &clientIDs).Error
+	return clientIDs, err
+}
+
+// FindPastVisits finds the clients' appointments that have already started, most recent first
+func (r *clientPortalRepositoryImpl) FindPastVisits(ctx context.Context, clientIDs []string, page, pageSize int) ([]*domain.PortalVisit, int64, error) {
+	var total int64
+	err := conn(ctx, r.db).
+		Model(&domain.Appointment{}).
+		Where("client_id IN ? AND deleted_at IS NULL AND start_time < NOW()", clientIDs).
+		Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var visits []*domain.PortalVisit
+	err = conn(ctx, r.db).
+		Raw(portalVisitSelectSQL+" AND a.start_time < NOW() ORDER BY a.start_time DESC LIMIT ? OFFSET ?",
+			clientIDs, pageSize, (page-1)*pageSize).
+		Scan(&visits).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := r.attachServices(ctx, visits); err != nil {
+		return nil, 0, err
+	}
+	return visits, total, nil
+}
+
+// FindVisit finds a single appointment of the clients
+func (r *clientPortalRepositoryImpl) FindVisit(ctx context.Context, clientIDs []string, appointmentID string) (*domain.PortalVisit, error) {
+	var visits []*domain.PortalVisit
+	err := conn(ctx, r.db).
+		Raw(portalVisitSelectSQL+" AND a.id = ?", clientIDs, appointmentID).
+		Scan(&visits).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(visits) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	if err := r.attachServices(ctx, visits); err != nil {
+		return nil, err
+	}
+	return visits[0], nil
+}
+
+// attachServices loads the services delivered during each visit
+func (r *clientPortalRepositoryImpl) attachServices(ctx context.Context, visits []*domain.PortalVisit) error {
+	if len(visits) == 0 {
+		return nil
+	}
+
+	byAppointment := make(map[string]*domain.PortalVisit, len(visits))
+	appointmentIDs := make([]string, len(visits))
+	for i, visit := range visits {
+		visit.Services = []domain.PortalVisitService{}
+		byAppointment[visit.AppointmentID] = visit
+		appointmentIDs[i] = visit.AppointmentID
+	}
+
+	var services []domain.PortalVisitService
+	err := conn(ctx, r.db).
+		Raw(`
+			SELECT aps.appointment_id, sv.name, aps.duration, aps.price
+			FROM appointment_services aps
+			JOIN services sv ON sv.id = aps.service_id
+			WHERE aps.appointment_id IN ? AND aps.deleted_at IS NULL
+			ORDER BY aps.created_at ASC`,
+			appointmentIDs,
+		).
+		Scan(&services).Error
+	if err != nil {
+		return err
+	}
+
+	for _, service := range services {
+		visit := byAppointment[service.AppointmentID]
+		visit.Services = append(visit.Services, service)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// timelineSource selects the entries of a client's timeline taken from one table. Every source
+// selects the kind and occurred_at followed by timelineColumns, in order.
+type timelineSource struct {
+	id, occurredAt, from string
+}
+
+// timelineColumns are the columns of a timeline entry following its ID, kind and occurred_at
+const timelineColumns = "appointment_id, status, title, description, channel, start_time, amount, currency, points, rating"
+
+// timelineSources are the tables a client's timeline is assembled from
+var timelineSources = []timelineSource{
+	{
+		id: "a.id", occurredAt: "a.created_at",
+		from: `'appointment', a.created_at, a.id, a.status, NULL, a.notes, NULL, a.start_time, NULL, NULL, NULL::int, NULL::int
+			FROM appointments a
+			WHERE a.client_id = @client AND a.deleted_at IS NULL`,
+	},
+	{
+		id: "sc.id", occurredAt: "COALESCE(sc.completion_date, sc.created_at)",
+		from: `'completion', COALESCE(sc.completion_date, sc.created_at), a.id, sc.payment_method, NULL, NULL, NULL, NULL,
+				sc.price_charged, b.currency, NULL::int, NULL::int
+			FROM service_completions sc
+			JOIN appointments a ON a.id = sc.appointment_id
+			JOIN businesses b ON b.id = a.business_id
+			WHERE a.client_id = @client AND sc.deleted_at IS NULL AND a.deleted_at IS NULL`,
+	},
+	{
+		id: "p.id", occurredAt: "COALESCE(p.paid_at, p.created_at)",
+		from: `'payment', COALESCE(p.paid_at, p.created_at), p.appointment_id, p.status, p.kind, p.failure_reason, NULL, NULL,
+				p.amount, p.currency, NULL::int, NULL::int
+			FROM payments p
+			JOIN appointments a ON a.id = p.appointment_id
+			WHERE a.client_id = @client AND p.deleted_at IS NULL AND a.deleted_at IS NULL`,
+	},
+	{
+		id: "t.id", occurredAt: "t.created_at",
+		from: `'loyalty', t.created_at, t.appointment_id, t.transaction_type, lp.name, t.description, NULL, NULL,
+				NULL, NULL, t.points, NULL::int
+			FROM loyalty_transactions t
+			JOIN client_loyalty_memberships m ON m.id = t.membership_id
+			JOIN loyalty_programs lp ON lp.id = m.program_id
+			WHERE m.client_id = @client AND t.deleted_at IS NULL AND m.deleted_at IS NULL`,
+	},
+	{
+		id: "cm.id", occurredAt: "cm.sent_time",
+		from: `'campaign', cm.sent_time, NULL::uuid, cm.status, c.name, cm.message_content, cm.message_type, NULL,
+				NULL, NULL, NULL::int, NULL::int
+			FROM campaign_messages cm
+			JOIN campaigns c ON c.id = cm.campaign_id
+			WHERE cm.client_id = @client AND cm.sent_time IS NOT NULL AND cm.deleted_at IS NULL`,
+	},
+	{
+		id: "sr.id", occurredAt: "sr.recorded_at",
+		from: `'note', sr.recorded_at, sr.appointment_id, NULL, NULL, sr.notes, NULL, NULL,
+				NULL, NULL, NULL::int, NULL::int
+			FROM service_records sr
+			WHERE sr.client_id = @client AND sr.deleted_at IS NULL`,
+	},
+	{
+		id: "rv.id", occurredAt: "rv.created_at",
+		from: `'review', rv.created_at, rv.appointment_id, rv.status, NULL, rv.comment, NULL, NULL,
+				NULL, NULL, NULL::int, rv.rating
+			FROM reviews rv
+			WHERE rv.client_id = @client AND rv.deleted_at IS NULL`,
+	},
+}
+
+// clientTimelineSQL merges the timeline sources, newest first. Each source is cut down to the
+// page on its own, so only the newest rows following the cursor are read from every table.
+var clientTimelineSQL = func() string {
+	parts := make([]string, len(timelineSources))
+	for i, source := range timelineSources {
+		parts[i] = fmt.Sprintf(`(SELECT %[1]s, %[2]s
+			AND (NOT @paged OR (%[3]s, %[1]s) < (@at, @id::uuid))
+			ORDER BY %[3]s DESC, %[1]s DESC
+			LIMIT @limit)`, source.id, source.from, source.occurredAt)
+	}
+	return fmt.Sprintf(`
+	SELECT * FROM (
+		%s
+	) entries(id, kind, occurred_at, %s)
+	ORDER BY occurred_at DESC, id DESC
+	LIMIT @limit`, strings.Join(parts, "\n\t\tUNION ALL\n\t\t"), timelineColumns)
+}()
+
+// clientTimelineRepositoryImpl implements the ClientTimelineRepository interface
+type clientTimelineRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewClientTimelineRepository creates a new client timeline repository
+func NewClientTimelineRepository(db *gorm.DB) domain.ClientTimelineRepository {
+	return &clientTimelineRepositoryImpl{db: db}
+}
+
+// FindEntries finds up to limit entries of a client's timeline following the cursor, newest
+// first. The boolean reports whether more entries follow.
+func (r *clientTimelineRepositoryImpl) FindEntries(ctx context.Context, clientID string, after *domain.Cursor, limit int) ([]*domain.TimelineEntry, bool, error) {
+	params := map[string]any{
+		"client": clientID,
+		"paged":  after != nil,
+		"at":     time.Time{},
+		"id":     "00000000-0000-0000-0000-000000000000",
+		"limit":  limit + 1,
+	}
+	if after != nil {
+		params["at"] = after.CreatedAt
+		params["id"] = after.ID
+	}
+
+	var entries []*domain.TimelineEntry
+	if err := conn(ctx, r.db).Raw(clientTimelineSQL, params).Scan(&entries).Error; err != nil {
+		return nil, false, err
+	}
+	if len(entries) > limit {
+		return entries[:limit], true, nil
+	}
+	return entries, false, nil
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// staffCommissionPlanRepositoryImpl implements the StaffCommissionPlanRepository interface
+type staffCommissionPlanRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.StaffCommissionPlan]
+}
+
+// NewStaffCommissionPlanRepository creates a new staff commission plan repository, scoped to the
+// tenant in the context
+func NewStaffCommissionPlanRepository(db *gorm.DB) domain.StaffCommissionPlanRepository {
+	return &staffCommissionPlanRepositoryImpl{
+		BaseRepositoryImpl: newBaseRepository[domain.StaffCommissionPlan](db, WithTenantScope()),
+	}
+}
+
+// FindByStaffID finds the commission plan history of a staff member, most recent first
+func (r *staffCommissionPlanRepositoryImpl) FindByStaffID(ctx context.Context, staffID string) ([]*domain.StaffCommissionPlan, error) {
+	var plans []*domain.StaffCommissionPlan
+	err := r.query(ctx).
+		Where("staff_id = ? AND deleted_at IS NULL", staffID).
+		Order("effective_from DESC").
+		Find(&plans).Error
+	return plans, err
+}
+
+// FindEffective finds the commission plan that applies to a staff member at a point in time
+func (r *staffCommissionPlanRepositoryImpl) FindEffective(ctx context.Context, staffID string, at time.Time) (*domain.StaffCommissionPlan, error) {
+	var plan domain.StaffCommissionPlan
+	err := r.query(ctx).
+		Where("staff_id = ? AND effective_from <= ? AND (effective_to IS NULL OR effective_to > ?) AND deleted_at IS NULL", staffID, at, at).
+		Order("effective_from DESC").
+		First(&plan).Error
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// FindInPeriod finds the commission plans that apply at any point within a period
+func (r *staffCommissionPlanRepositoryImpl) FindInPeriod(ctx context.Context, staffID string, start, end time.Time) ([]*domain.StaffCommissionPlan, error) {
+	var plans []*domain.StaffCommissionPlan
+	err := r.query(ctx).
+		Where("staff_id = ? AND effective_from < ? AND (effective_to IS NULL OR effective_to > ?) AND deleted_at IS NULL", staffID, end, start).
+		Order("effective_from ASC").
+		Find(&plans).Error
+	return plans, err
+}
+
+// FindRevenueLines finds the commissionable service and retail revenue attributed to a staff
+// member within a period. Services count once their appointment is completed; retail sales are
+// attributed to the recorded seller, falling back to the appointment's staff member.
+func (r *staffCommissionPlanRepositoryImpl) FindRevenueLines(ctx context.Context, staffID string, start, end time.Time) ([]domain.CommissionRevenueLine, error) {
+	var lines []domain.CommissionRevenueLine
+	if err := r.ensureParentOwned(ctx, &domain.Staff{}, staffID); err != nil {
+		return nil, err
+	}
+
+	err := conn(ctx, r.db).Raw(`
+		SELECT 'service' AS kind, aps.service_id, NULL AS product_id, aps.appointment_id,
+			aps.price AS amount, a.start_time AS occurred_at
+		FROM appointment_services aps
+		JOIN appointments a ON a.id = aps.appointment_id
+		WHERE aps.staff_id = ? AND a.status = 'completed'
+			AND a.start_time >= ? AND a.start_time < ?
+			AND aps.deleted_at IS NULL AND a.deleted_at IS NULL
+		UNION ALL
+		SELECT 'product' AS kind, NULL AS service_id, it.product_id, it.appointment_id,
+			it.quantity * COALESCE(it.unit_price, 0) AS amount, it.created_at AS occurred_at
+		FROM inventory_transactions it
+		LEFT JOIN appointments a ON a.id = it.appointment_id
+		WHERE it.transaction_type = 'sale' AND COALESCE(it.staff_id, a.staff_id) = ?
+			AND it.created_at >= ? AND it.created_at < ?
+			AND it.deleted_at IS NULL
+		ORDER BY occurred_at ASC`,
+		staffID, start, end, staffID, start, end,
+	).Scan(&lines).Error
+
+	return lines, err
+}
+
+// WithTx returns a new repository instance with the given transaction
+func (r *staffCommissionPlanRepositoryImpl) WithTx(tx *gorm.DB) domain.BaseRepository[domain.StaffCommissionPlan] {
+	return r.withDB(tx)
+}
@@ -0,0 +1,365 @@
+// Package contract holds conformance tests for the repository interfaces. Every
+// implementation runs the same suite, so the GORM repositories and their in-memory stand-ins
+// agree on what a miss returns, how pages are cut, what soft delete hides and how lists are
+// ordered.
+package contract
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// BaseRepositorySuite checks an implementation of domain.BaseRepository for one entity type
+type BaseRepositorySuite[T any] struct {
+	// Setup returns an empty repository and a function building the nth entity for it. Built
+	// entities must be valid and distinct, and are not stored yet.
+	Setup func(t *testing.T) (domain.BaseRepository[T], func(n int) *T)
+	// ID returns the ID of an entity
+	ID func(entity *T) string
+	// Label returns a text field that tells entities apart, such as a name
+	Label func(entity *T) string
+	// Relabel changes the text field returned by Label
+	Relabel func(entity *T, label string)
+	// LabelColumn is the column of the text field, used to search with FindBy
+	LabelColumn string
+}
+
+// Run runs the suite
+func (s BaseRepositorySuite[T]) Run(t *testing.T) {
+	t.Run("NotFound", s.testNotFound)
+	t.Run("CreateAndUpdate", s.testCreateAndUpdate)
+	t.Run("GetByIDs", s.testGetByIDs)
+	t.Run("SoftDelete", s.testSoftDelete)
+	t.Run("Trash", s.testTrash)
+	t.Run("OptimisticLocking", s.testOptimisticLocking)
+	t.Run("UpdateFields", s.testUpdateFields)
+	t.Run("Pagination", s.testPagination)
+	t.Run("FindBy", s.testFindBy)
+	t.Run("ListAfter", s.testListAfter)
+	t.Run("Batch", s.testBatch)
+}
+
+func (s BaseRepositorySuite[T]) testNotFound(t *testing.T) {
+	ctx := context.Background()
+	repo, _ := s.Setup(t)
+	missing := uuid.NewString()
+
+	entity, err := repo.GetByID(ctx, missing)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.Nil(t, entity)
+
+	exists, err := repo.ExistsByID(ctx, missing)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	assert.NoError(t, repo.Delete(ctx, missing), "deleting a missing entity is not an error")
+
+	entities, total, err := repo.List(ctx, 1, 10)
+	require.NoError(t, err)
+	assert.Empty(t, entities)
+	assert.Zero(t, total)
+}
+
+func (s BaseRepositorySuite[T]) testCreateAndUpdate(t *testing.T) {
+	ctx := context.Background()
+	repo, build := s.Setup(t)
+
+	entity := build(1)
+	require.NoError(t, repo.Create(ctx, entity))
+	id := s.ID(entity)
+	require.NotEmpty(t, id, "Create assigns an ID")
+
+	stored, err := repo.GetByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, s.Label(entity), s.Label(stored))
+	exists, err := repo.ExistsByID(ctx, id)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	s.Relabel(stored, "relabelled")
+	require.NoError(t, repo.Update(ctx, stored))
+	stored, err = repo.GetByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "relabelled", s.Label(stored))
+}
+
+func (s BaseRepositorySuite[T]) testGetByIDs(t *testing.T) {
+	ctx := context.Background()
+	repo, build := s.Setup(t)
+	first, second, deleted := s.create(t, repo, build(1)), s.create(t, repo, build(2)), s.create(t, repo, build(3))
+	require.NoError(t, repo.Delete(ctx, s.ID(deleted)))
+
+	entities, err := repo.GetByIDs(ctx, []string{s.ID(second), uuid.NewString(), s.ID(deleted), s.ID(first), s.ID(second)})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{s.ID(first), s.ID(second)}, s.ids(entities),
+		"missing and deleted IDs are skipped, and each entity is returned once in no particular order")
+
+	entities, err = repo.GetByIDs(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, entities)
+}
+
+func (s BaseRepositorySuite[T]) testSoftDelete(t *testing.T) {
+	ctx := context.Background()
+	repo, build := s.Setup(t)
+	kept, deleted := s.create(t, repo, build(1)), s.create(t, repo, build(2))
+
+	require.NoError(t, repo.Delete(ctx, s.ID(deleted)))
+
+	_, err := repo.GetByID(ctx, s.ID(deleted))
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	exists, err := repo.ExistsByID(ctx, s.ID(deleted))
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	entities, total, err := repo.List(ctx, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, []string{s.ID(kept)}, s.ids(entities))
+
+	found, err := repo.FindBy(ctx, map[string]any{s.LabelColumn: s.Label(deleted)})
+	require.NoError(t, err)
+	assert.Empty(t, found)
+
+	page, _, err := repo.ListAfter(ctx, nil, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{s.ID(kept)}, s.ids(page))
+}
+
+func (s BaseRepositorySuite[T]) testTrash(t *testing.T) {
+	ctx := context.Background()
+	repo, build := s.Setup(t)
+	kept, first, second := s.create(t, repo, build(1)), s.create(t, repo, build(2)), s.create(t, repo, build(3))
+	require.NoError(t, repo.Delete(ctx, s.ID(first)))
+	require.NoError(t, repo.Delete(ctx, s.ID(second)))
+
+	trash, total, err := repo.ListDeleted(ctx, nil, time.Time{}, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, []string{s.ID(second), s.ID(first)}, s.ids(trash), "most recently deleted first")
+
+	trash, _, err = repo.ListDeleted(ctx, map[string]any{s.LabelColumn: s.Label(first)}, time.Time{}, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{s.ID(first)}, s.ids(trash))
+
+	trash, total, err = repo.ListDeleted(ctx, nil, time.Now().Add(24*time.Hour), 1, 10)
+	require.NoError(t, err)
+	assert.Zero(t, total, "entities deleted before the cut-off are left out")
+	assert.Empty(t, trash)
+
+	require.NoError(t, repo.Restore(ctx, s.ID(first)))
+	restored, err := repo.GetByID(ctx, s.ID(first))
+	require.NoError(t, err)
+	assert.Equal(t, s.Label(first), s.Label(restored))
+
+	assert.ErrorIs(t, repo.Restore(ctx, s.ID(first)), gorm.ErrRecordNotFound, "the entity is no longer deleted")
+	assert.ErrorIs(t, repo.Restore(ctx, s.ID(kept)), gorm.ErrRecordNotFound, "the entity was never deleted")
+	assert.ErrorIs(t, repo.Restore(ctx, uuid.NewString()), gorm.ErrRecordNotFound)
+
+	trash, _, err = repo.ListDeleted(ctx, nil, time.Time{}, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{s.ID(second)}, s.ids(trash))
+}
+
+func (s BaseRepositorySuite[T]) testOptimisticLocking(t *testing.T) {
+	ctx := context.Background()
+	repo, build := s.Setup(t)
+	id := s.ID(s.create(t, repo, build(1)))
+
+	mine, err := repo.GetByID(ctx, id)
+	require.NoError(t, err)
+	theirs, err := repo.GetByID(ctx, id)
+	require.NoError(t, err)
+
+	s.Relabel(mine, "mine")
+	require.NoError(t, repo.Update(ctx, mine))
+	s.Relabel(mine, "mine again")
+	require.NoError(t, repo.Update(ctx, mine), "the entity carries the version its update advanced to")
+
+	s.Relabel(theirs, "theirs")
+	err = repo.Update(ctx, theirs)
+	assert.ErrorIs(t, err, domain.ErrConflict, "the entity was changed since it was read")
+	var conflict *domain.ConflictError
+	require.ErrorAs(t, err, &conflict)
+	assert.Equal(t, id, conflict.ID)
+
+	stored, err := repo.GetByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "mine again", s.Label(stored), "the conflicting update is not written")
+
+	s.Relabel(stored, "theirs")
+	require.NoError(t, repo.Update(ctx, stored), "updating the latest version succeeds")
+}
+
+func (s BaseRepositorySuite[T]) testUpdateFields(t *testing.T) {
+	ctx := context.Background()
+	repo, build := s.Setup(t)
+	id := s.ID(s.create(t, repo, build(1)))
+	read, err := repo.GetByID(ctx, id)
+	require.NoError(t, err)
+
+	var changes domain.ChangeSet
+	require.NoError(t, changes.Capture(read), "the changes take the version of the entity read")
+	changes.Set(s.LabelColumn, "patched")
+	updated, err := repo.UpdateFields(ctx, id, changes)
+	require.NoError(t, err)
+	assert.Equal(t, "patched", s.Label(updated))
+	stored, err := repo.GetByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "patched", s.Label(stored))
+
+	changes.Set(s.LabelColumn, "stale")
+	_, err = repo.UpdateFields(ctx, id, changes)
+	assert.ErrorIs(t, err, domain.ErrConflict, "the entity was changed since the changes' version")
+
+	changes.Version = 0
+	changes.Set(s.LabelColumn, "")
+	updated, err = repo.UpdateFields(ctx, id, changes)
+	require.NoError(t, err)
+	assert.Empty(t, s.Label(updated), "zero values are written")
+
+	_, err = repo.UpdateFields(ctx, uuid.NewString(), changes)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	_, err = repo.UpdateFields(ctx, id, domain.ChangeSet{})
+	assert.ErrorIs(t, err, domain.ErrValidation, "an update must change something")
+	var unknown domain.ChangeSet
+	_, err = repo.UpdateFields(ctx, id, *unknown.Set("no_such_column", 1))
+	assert.ErrorIs(t, err, domain.ErrValidation)
+}
+
+func (s BaseRepositorySuite[T]) testPagination(t *testing.T) {
+	ctx := context.Background()
+	repo, build := s.Setup(t)
+	var created []string
+	for n := 1; n <= 5; n++ {
+		created = append(created, s.ID(s.create(t, repo, build(n))))
+	}
+
+	var listed []string
+	for page := 1; page <= 3; page++ {
+		entities, total, err := repo.List(ctx, page, 2)
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), total, "the total counts every page")
+		assert.Len(t, entities, min(2, 5-(page-1)*2))
+		listed = append(listed, s.ids(entities)...)
+	}
+	assert.Equal(t, created, listed, "pages follow creation order without gaps or repeats")
+
+	entities, total, err := repo.List(ctx, 4, 2)
+	require.NoError(t, err)
+	assert.Empty(t, entities, "pages past the end are empty")
+	assert.Equal(t, int64(5), total)
+}
+
+func (s BaseRepositorySuite[T]) testFindBy(t *testing.T) {
+	ctx := context.Background()
+	repo, build := s.Setup(t)
+	first, second := s.create(t, repo, build(1)), s.create(t, repo, build(2))
+	s.create(t, repo, build(3))
+
+	found, err := repo.FindBy(ctx, map[string]any{s.LabelColumn: s.Label(second)})
+	require.NoError(t, err)
+	assert.Equal(t, []string{s.ID(second)}, s.ids(found))
+
+	found, err = repo.FindBy(ctx, map[string]any{"id": s.ID(first), s.LabelColumn: s.Label(second)})
+	require.NoError(t, err)
+	assert.Empty(t, found, "every criterion must match")
+}
+
+func (s BaseRepositorySuite[T]) testListAfter(t *testing.T) {
+	ctx := context.Background()
+	repo, build := s.Setup(t)
+	var created []string
+	for n := 1; n <= 5; n++ {
+		created = append(created, s.ID(s.create(t, repo, build(n))))
+	}
+
+	var listed []string
+	var cursor *domain.Cursor
+	for range 3 {
+		entities, hasNext, err := repo.ListAfter(ctx, cursor, 2)
+		require.NoError(t, err)
+		listed = append(listed, s.ids(entities)...)
+		if !hasNext {
+			break
+		}
+		next := domain.CursorFor(entityOf(entities[len(entities)-1]))
+		cursor = &next
+	}
+	assert.Equal(t, created, listed, "the cursor walks every entity once, in creation order")
+}
+
+func (s BaseRepositorySuite[T]) testBatch(t *testing.T) {
+	ctx := context.Background()
+	repo, build := s.Setup(t)
+	entities := []*T{build(1), build(2), build(3)}
+
+	created, err := repo.CreateBatch(ctx, entities, domain.BatchOptions{BatchSize: 2})
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, created)
+	stored, err := repo.GetByIDs(ctx, s.ids(entities))
+	require.NoError(t, err)
+	assert.Len(t, stored, 3, "every batch is written")
+
+	_, err = repo.CreateBatch(ctx, []*T{build(4), entities[0]}, domain.BatchOptions{})
+	assert.Error(t, err, "an entity conflicts with a stored one")
+	_, total, err := repo.List(ctx, 1, 10)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, total, "a failing batch writes nothing")
+
+	for i, entity := range stored {
+		s.Relabel(entity, fmt.Sprintf("batch %d", i))
+	}
+	require.NoError(t, repo.UpdateBatch(ctx, stored))
+	for i, entity := range stored {
+		updated, err := repo.GetByID(ctx, s.ID(entity))
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("batch %d", i), s.Label(updated))
+	}
+
+	stale, err := repo.GetByID(ctx, s.ID(stored[0]))
+	require.NoError(t, err)
+	s.Relabel(stored[0], "fresh")
+	require.NoError(t, repo.Update(ctx, stored[0]))
+	s.Relabel(stale, "stale")
+	s.Relabel(stored[1], "unwritten")
+	err = repo.UpdateBatch(ctx, []*T{stored[1], stale})
+	assert.ErrorIs(t, err, domain.ErrConflict)
+	unchanged, err := repo.GetByID(ctx, s.ID(stored[1]))
+	require.NoError(t, err)
+	assert.Equal(t, "batch 1", s.Label(unchanged), "a conflict leaves every entity of the batch unchanged")
+}
+
+// create stores an entity
+func (s BaseRepositorySuite[T]) create(t *testing.T, repo domain.BaseRepository[T], entity *T) *T {
+	t.Helper()
+	require.NoError(t, repo.Create(context.Background(), entity))
+	return entity
+}
+
+// ids returns the IDs of entities
+func (s BaseRepositorySuite[T]) ids(entities []*T) []string {
+	ids := make([]string, len(entities))
+	for i, entity := range entities {
+		ids[i] = s.ID(entity)
+	}
+	return ids
+}
+
+// entityOf returns an entity as a domain.Entity, which suite entities must implement to be
+// paged by cursor
+func entityOf[T any](entity *T) domain.Entity {
+	e, ok := any(entity).(domain.Entity)
+	if !ok {
+		panic(fmt.Sprintf("contract: %T does not implement domain.Entity", entity))
+	}
+	return e
+}
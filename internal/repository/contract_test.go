@@ -0,0 +1,47 @@
+//go:build integration
+// +build integration
+
+package repository_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/repository"
+	"github.com/assimoes/beautix/internal/repository/contract"
+	"github.com/assimoes/beautix/utils/testdb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserRepository_Contract(t *testing.T) {
+	contract.BaseRepositorySuite[domain.User]{
+		Setup: func(t *testing.T) (domain.BaseRepository[domain.User], func(n int) *domain.User) {
+			db := testdb.NewTestDB(t)
+			return repository.NewUserRepository(db.GetDB().DB), func(n int) *domain.User {
+				return &domain.User{Email: fmt.Sprintf("user-%d@example.com", n), FirstName: fmt.Sprintf("User %d", n), LastName: "Test", IsActive: true}
+			}
+		},
+		ID:          func(u *domain.User) string { return u.ID },
+		Label:       func(u *domain.User) string { return u.FirstName },
+		Relabel:     func(u *domain.User, label string) { u.FirstName = label },
+		LabelColumn: "first_name",
+	}.Run(t)
+}
+
+func TestBusinessRepository_Contract(t *testing.T) {
+	contract.BaseRepositorySuite[domain.Business]{
+		Setup: func(t *testing.T) (domain.BaseRepository[domain.Business], func(n int) *domain.Business) {
+			db := testdb.NewTestDB(t)
+			owner, err := testdb.NewFixtureBuilder(db.GetDB()).CreateUser()
+			require.NoError(t, err)
+			return repository.NewBusinessRepository(db.GetDB().DB), func(n int) *domain.Business {
+				return &domain.Business{UserID: owner.ID, Name: fmt.Sprintf("Salon %d", n), Email: fmt.Sprintf("salon-%d@example.com", n), Currency: "EUR", TimeZone: "Europe/Lisbon", IsActive: true}
+			}
+		},
+		ID:          func(b *domain.Business) string { return b.ID },
+		Label:       func(b *domain.Business) string { return b.Name },
+		Relabel:     func(b *domain.Business, label string) { b.Name = label },
+		LabelColumn: "name",
+	}.Run(t)
+}
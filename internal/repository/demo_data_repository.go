@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// demoResetTables are the tables holding the transactional data of a business, in an order
+// that removes referencing rows before the rows they reference. Rows of appointments and clients
+// in tables without a business_id column go with them through their cascading foreign keys.
+var demoResetTables = []string{
+	"payments",
+	"invoices",
+	"invoice_sequences",
+	"membership_credit_notes",
+	"membership_invoices",
+	"client_memberships",
+	"gift_cards",
+	"referrals",
+	"anonymous_feedback",
+	"feedback_tokens",
+	"reviews",
+	"review_requests",
+	"waiting_list",
+	"broadcasts",
+	"appointment_confirmations",
+	"calendar_entries",
+	"capacity_alerts",
+	"booking_attempts",
+	"notification_queue",
+	"service_records",
+	"client_attachments", // Sandbox files are left in storage; only their records go
+	"webhook_deliveries",
+	"domain_events",
+	"appointments",
+	"clients",
+}
+
+// demoServiceColumns are the service fields backed by columns of the services table
+var demoServiceColumns = []string{
+	"ID", "BusinessID", "Name", "Description", "Duration", "Price", "IsActive", "CreatedAt", "CreatedBy",
+	"UpdatedAt", "UpdatedBy",
+}
+
+// demoDataRepositoryImpl implements the DemoDataRepository interface
+type demoDataRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewDemoDataRepository creates a new demo data repository
+func NewDemoDataRepository(db *gorm.DB) domain.DemoDataRepository {
+	return &demoDataRepositoryImpl{db: db}
+}
+
+// Reset removes the transactional data of a business and seeds a dataset in a single transaction
+func (r *demoDataRepositoryImpl) Reset(ctx context.Context, businessID string, dataset *domain.DemoDataset) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		for _, table := range demoResetTables {
+			if err := tx.Exec("DELETE FROM "+table+" WHERE business_id = ?", businessID).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(dataset.Services) > 0 {
+			if err := tx.Select(demoServiceColumns).Create(&dataset.Services).Error; err != nil {
+				return err
+			}
+		}
+		if len(dataset.Clients) > 0 {
+			if err := tx.Select(append([]string{"ID"}, clientInsertColumns...)).Create(&dataset.Clients).Error; err != nil {
+				return err
+			}
+		}
+		if len(dataset.Appointments) > 0 {
+			if err := tx.Select(append([]string{"ID"}, appointmentColumns...)).Create(&dataset.Appointments).Error; err != nil {
+				return err
+			}
+		}
+		if len(dataset.Lines) > 0 {
+			if err := tx.Create(&dataset.Lines).Error; err != nil {
+				return err
+			}
+		}
+		for _, completion := range dataset.Completions {
+			if err := tx.Omit("Appointment").Create(completion).Error; err != nil {
+				return err
+			}
+			err := tx.Table("appointments").
+				Where("id = ?", completion.AppointmentID).
+				Updates(map[string]any{
+					"actual_price":   completion.PriceCharged,
+					"payment_method": completion.PaymentMethod,
+					"payment_status": domain.AppointmentPaymentPaid,
+				}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
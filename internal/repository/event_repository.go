@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// domainEventRepositoryImpl implements the DomainEventRepository interface
+type domainEventRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.DomainEvent]
+}
+
+// NewDomainEventRepository creates a new domain event repository
+func NewDomainEventRepository(db *gorm.DB) domain.DomainEventRepository {
+	return &domainEventRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.DomainEvent]{db: db},
+	}
+}
+
+// Search finds events matching the filter, oldest first so that pages replay in order
+func (r *domainEventRepositoryImpl) Search(ctx context.Context, filter domain.DomainEventFilter, page, pageSize int) ([]*domain.DomainEvent, int64, error) {
+	var events []*domain.DomainEvent
+	var total int64
+
+	query := conn(ctx, r.db).
+		Model(&domain.DomainEvent{}).
+		Where("deleted_at IS NULL")
+
+	if filter.BusinessID != nil {
+		query = query.Where("business_id = ?", *filter.BusinessID)
+	}
+	if filter.AggregateType != nil {
+		query = query.Where("aggregate_type = ?", *filter.AggregateType)
+	}
+	if filter.AggregateID != nil {
+		query = query.Where("aggregate_id = ?", *filter.AggregateID)
+	}
+	if filter.EventType != nil {
+		query = query.Where("event_type = ?", *filter.EventType)
+	}
+	if filter.DateRange != nil {
+		query = query.Where("occurred_at >= ? AND occurred_at < ?", filter.DateRange.Start, filter.DateRange.End)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Order("occurred_at ASC, id ASC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&events).Error
+
+	return events, total, err
+}
+
+// FindByIDs finds events by ID in the order they occurred
+func (r *domainEventRepositoryImpl) FindByIDs(ctx context.Context, ids []string) ([]*domain.DomainEvent, error) {
+	var events []*domain.DomainEvent
+	err := conn(ctx, r.db).
+		Where("id IN ? AND deleted_at IS NULL", ids).
+		Order("occurred_at ASC, id ASC").
+		Find(&events).Error
+	return events, err
+}
+
+// MarkReplayed records that the given events were replayed
+func (r *domainEventRepositoryImpl) MarkReplayed(ctx context.Context, ids []string, at time.Time) error {
+	return conn(ctx, r.db).
+		Model(&domain.DomainEvent{}).
+		Where("id IN ?", ids).
+		Updates(map[string]any{
+			"replay_count":     gorm.Expr("replay_count + 1"),
+			"last_replayed_at": at,
+		}).Error
+}
+
+// ClaimUnpublished leases the unpublished events that are due, skipping the rows other relays
+// hold locked while they claim theirs
+func (r *domainEventRepositoryImpl) ClaimUnpublished(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.DomainEvent, error) {
+	var events []*domain.DomainEvent
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("published_at IS NULL AND abandoned_at IS NULL AND deleted_at IS NULL").
+			Where("next_publish_at IS NULL OR next_publish_at <= ?", now).
+			Order("occurred_at ASC, id ASC").
+			Limit(limit).
+			Find(&events).Error
+		if err != nil || len(events) == 0 {
+			return err
+		}
+
+		leasedUntil := now.Add(lease)
+		ids := make([]string, 0, len(events))
+		for _, event := range events {
+			ids = append(ids, event.ID)
+			event.NextPublishAt = &leasedUntil
+		}
+		return tx.Model(&domain.DomainEvent{}).Where("id IN ?", ids).Update("next_publish_at", leasedUntil).Error
+	})
+	return events, err
+}
+
+// WithTx returns a new repository instance with the given transaction
+func (r *domainEventRepositoryImpl) WithTx(tx *gorm.DB) domain.BaseRepository[domain.DomainEvent] {
+	return &BaseRepositoryImpl[domain.DomainEvent]{db: tx}
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// frontDeskTaskRepositoryImpl implements the FrontDeskTaskRepository interface
+type frontDeskTaskRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.FrontDeskTask]
+}
+
+// NewFrontDeskTaskRepository creates a new front desk task repository, scoped to the tenant in
+// the context
+func NewFrontDeskTaskRepository(db *gorm.DB) domain.FrontDeskTaskRepository {
+	return &frontDeskTaskRepositoryImpl{
+		BaseRepositoryImpl: newBaseRepository[domain.FrontDeskTask](db, WithTenantScope()),
+	}
+}
+
+// FindByBusiness finds the tasks of a business, soonest due first
+func (r *frontDeskTaskRepositoryImpl) FindByBusiness(ctx context.Context, businessID string, status *domain.FrontDeskTaskStatus) ([]*domain.FrontDeskTask, error) {
+	query := r.query(ctx).Where("business_id = ?", businessID)
+	if status != nil {
+		query = query.Where("status = ?", *status)
+	}
+	var tasks []*domain.FrontDeskTask
+	err := query.Order("due_at ASC, created_at ASC").Find(&tasks).Error
+	return tasks, err
+}
+
+// FindByAppointment finds the tasks raised about an appointment, oldest first
+func (r *frontDeskTaskRepositoryImpl) FindByAppointment(ctx context.Context, appointmentID string) ([]*domain.FrontDeskTask, error) {
+	var tasks []*domain.FrontDeskTask
+	err := r.query(ctx).
+		Where("appointment_id = ?", appointmentID).
+		Order("created_at ASC").
+		Find(&tasks).Error
+	return tasks, err
+}
+
+// WithTx returns a new repository instance with the given transaction
+func (r *frontDeskTaskRepositoryImpl) WithTx(tx *gorm.DB) domain.BaseRepository[domain.FrontDeskTask] {
+	return r.withDB(tx)
+}
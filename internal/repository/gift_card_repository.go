@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// giftCardRepositoryImpl implements the GiftCardRepository interface
+type giftCardRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.GiftCard]
+}
+
+// NewGiftCardRepository creates a new gift card repository, scoped to the tenant in the context
+func NewGiftCardRepository(db *gorm.DB) domain.GiftCardRepository {
+	return &giftCardRepositoryImpl{
+		BaseRepositoryImpl: newBaseRepository[domain.GiftCard](db, WithTenantScope()),
+	}
+}
+
+// FindByCode finds a gift card of a business by its code
+func (r *giftCardRepositoryImpl) FindByCode(ctx context.Context, businessID, code string) (*domain.GiftCard, error) {
+	var card domain.GiftCard
+	err := r.query(ctx).
+		Where("business_id = ? AND code = ?", businessID, code).
+		First(&card).Error
+	if err != nil {
+		return nil, err
+	}
+	return &card, nil
+}
+
+// FindTransactions finds the balance changes of a gift card, oldest first
+func (r *giftCardRepositoryImpl) FindTransactions(ctx context.Context, giftCardID string) ([]*domain.GiftCardTransaction, error) {
+	var transactions []*domain.GiftCardTransaction
+	err := r.query(ctx).
+		Where("gift_card_id = ?", giftCardID).
+		Order("created_at ASC").
+		Find(&transactions).Error
+	return transactions, err
+}
+
+// Issue creates a card together with the transaction recording its sale
+func (r *giftCardRepositoryImpl) Issue(ctx context.Context, card *domain.GiftCard) error {
+	if err := r.claim(ctx, card); err != nil {
+		return err
+	}
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(card).Error; err != nil {
+			return err
+		}
+		return tx.Create(&domain.GiftCardTransaction{
+			BaseModel:    domain.BaseModel{CreatedBy: card.CreatedBy},
+			BusinessID:   card.BusinessID,
+			GiftCardID:   card.ID,
+			Kind:         domain.GiftCardTransactionIssue,
+			Amount:       card.InitialValue,
+			BalanceAfter: card.Balance,
+		}).Error
+	})
+}
+
+// Redeem takes a payment's amount off a card, guarded so that concurrent redemptions cannot
+// take the balance below zero, and records the payment and the transaction
+func (r *giftCardRepositoryImpl) Redeem(ctx context.Context, giftCardID string, payment *domain.Payment) error {
+	if err := r.checkBusiness(ctx, payment.BusinessID); err != nil {
+		return err
+	}
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := r.scoped(ctx, tx).Model(&domain.GiftCard{}).
+			Where("id = ? AND balance >= ?", giftCardID, payment.Amount).
+			Updates(map[string]any{
+				"balance":    gorm.Expr("balance - ?", payment.Amount),
+				"updated_at": time.Now(),
+				"updated_by": payment.CreatedBy,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrGiftCardBalanceChanged
+		}
+
+		payment.GiftCardID = &giftCardID
+		if err := tx.Create(payment).Error; err != nil {
+			return err
+		}
+		return r.recordTransaction(tx, payment, domain.GiftCardTransactionRedemption, payment.Amount, payment.CreatedBy)
+	})
+}
+
+// Refund puts part of a gift card payment back onto the card and saves the payment
+func (r *giftCardRepositoryImpl) Refund(ctx context.Context, payment *domain.Payment, amount decimal.Decimal) error {
+	if err := r.checkBusiness(ctx, payment.BusinessID); err != nil {
+		return err
+	}
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		err := r.scoped(ctx, tx).Model(&domain.GiftCard{}).
+			Where("id = ?", *payment.GiftCardID).
+			Updates(map[string]any{
+				"balance":    gorm.Expr("balance + ?", amount),
+				"updated_at": time.Now(),
+				"updated_by": payment.UpdatedBy,
+			}).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Save(payment).Error; err != nil {
+			return err
+		}
+		return r.recordTransaction(tx, payment, domain.GiftCardTransactionRefund, amount, payment.UpdatedBy)
+	})
+}
+
+// recordTransaction records a change of a card's balance made by a payment, reading back the
+// balance it left
+func (r *giftCardRepositoryImpl) recordTransaction(tx *gorm.DB, payment *domain.Payment, kind domain.GiftCardTransactionKind, amount decimal.Decimal, by *string) error {
+	var balance decimal.Decimal
+	err := tx.Model(&domain.GiftCard{}).
+		Select("balance").
+		Where("id = ?", *payment.GiftCardID).
+		Scan(&balance).Error
+	if err != nil {
+		return err
+	}
+	return tx.Create(&domain.GiftCardTransaction{
+		BaseModel:     domain.BaseModel{CreatedBy: by},
+		BusinessID:    payment.BusinessID,
+		GiftCardID:    *payment.GiftCardID,
+		Kind:          kind,
+		Amount:        amount,
+		BalanceAfter:  balance,
+		PaymentID:     &payment.ID,
+		AppointmentID: &payment.AppointmentID,
+	}).Error
+}
+
+// WithTx returns a new repository instance with the given transaction
+func (r *giftCardRepositoryImpl) WithTx(tx *gorm.DB) domain.BaseRepository[domain.GiftCard] {
+	return r.withDB(tx)
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// idGenerator assigns the IDs of the records GORM creates
+type idGenerator struct{}
+
+// NewIDGenerator returns a GORM plugin giving new records time-ordered UUIDv7 IDs from
+// domain.NewID rather than the random UUIDv4s of the columns' gen_random_uuid() default. IDs
+// set by the caller are kept.
+func NewIDGenerator() gorm.Plugin {
+	return idGenerator{}
+}
+
+// Name returns the name of the plugin
+func (idGenerator) Name() string { return "id_generator" }
+
+// Initialize registers the generator before the create hooks, so they see the IDs
+func (g idGenerator) Initialize(db *gorm.DB) error {
+	return db.Callback().Create().Before("gorm:before_create").Register("id:assign", g.beforeCreate)
+}
+
+// beforeCreate assigns IDs to the records being created that have none
+func (idGenerator) beforeCreate(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return
+	}
+	assignIDs(db.Statement.Context, db.Statement.Schema, db.Statement.ReflectValue)
+}
+
+// assignIDs gives the entities of a schema whose primary key the database generates an ID where
+// they have none
+func assignIDs(ctx context.Context, entitySchema *schema.Schema, value reflect.Value) {
+	field := entitySchema.PrioritizedPrimaryField
+	if field == nil || field.FieldType.Kind() != reflect.String || !generatedUUID(field) {
+		return
+	}
+	for _, entity := range loadedEntities(value) {
+		if _, zero := field.ValueOf(ctx, entity); zero && entity.CanAddr() {
+			// The value is a string field, so setting it cannot fail
+			_ = field.Set(ctx, entity, domain.NewID())
+		}
+	}
+}
+
+// generatedUUID reports whether a column defaults to a generated UUID
+func generatedUUID(field *schema.Field) bool {
+	return field.HasDefaultValue && strings.Contains(field.DefaultValue, "uuid")
+}
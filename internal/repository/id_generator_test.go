@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssignIDs(t *testing.T) {
+	ctx := context.Background()
+	clientSchema := parseSchema[domain.Client](t)
+
+	client := &domain.Client{}
+	assignIDs(ctx, clientSchema, reflect.ValueOf(client))
+	parsed, err := uuid.Parse(client.ID)
+	require.NoError(t, err)
+	assert.Equal(t, uuid.Version(7), parsed.Version())
+
+	legacy := uuid.NewString()
+	clients := []*domain.Client{{BaseModel: domain.BaseModel{ID: legacy}}, {}}
+	assignIDs(ctx, clientSchema, reflect.ValueOf(&clients))
+	assert.Equal(t, legacy, clients[0].ID, "IDs set by the caller are kept")
+	assert.NotEmpty(t, clients[1].ID)
+}
+
+func TestAssignIDs_KeysWithoutGeneratedDefault(t *testing.T) {
+	entry := &domain.CalendarEntry{}
+	assignIDs(context.Background(), parseSchema[domain.CalendarEntry](t), reflect.ValueOf(entry))
+	assert.Empty(t, entry.AppointmentID, "keys the database does not generate are left alone")
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// idempotencyKeyRepositoryImpl implements the IdempotencyKeyRepository interface
+type idempotencyKeyRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.IdempotencyKey]
+}
+
+// NewIdempotencyKeyRepository creates a new idempotency key repository
+func NewIdempotencyKeyRepository(db *gorm.DB) domain.IdempotencyKeyRepository {
+	return &idempotencyKeyRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.IdempotencyKey]{db: db},
+	}
+}
+
+// Claim stores a pending key, first removing a replaceable key stored for the same user and key.
+// Concurrent claims race on the unique index, so only one of them stores its key.
+func (r *idempotencyKeyRepositoryImpl) Claim(ctx context.Context, key *domain.IdempotencyKey, now time.Time) (*domain.IdempotencyKey, bool, error) {
+	var stored *domain.IdempotencyKey
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().
+			Where("scope = ? AND key = ?", key.Scope, key.Key).
+			Where("expires_at <= ? OR (status = ? AND created_at <= ?)",
+				now, domain.IdempotencyPending, now.Add(-domain.IdempotencyKeyLockTimeout)).
+			Delete(&domain.IdempotencyKey{}).Error
+		if err != nil {
+			return err
+		}
+
+		result := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "scope"}, {Name: "key"}},
+			DoNothing: true,
+		}).Create(key)
+		if result.Error != nil || result.RowsAffected > 0 {
+			return result.Error
+		}
+
+		var found domain.IdempotencyKey
+		if err := tx.Where("scope = ? AND key = ?", key.Scope, key.Key).First(&found).Error; err != nil {
+			return err
+		}
+		stored = &found
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if stored != nil {
+		return stored, false, nil
+	}
+	return key, true, nil
+}
+
+// Complete records the response to the request of a pending key
+func (r *idempotencyKeyRepositoryImpl) Complete(ctx context.Context, id string, response string) error {
+	result := conn(ctx, r.db).Model(&domain.IdempotencyKey{}).
+		Where("id = ? AND status = ?", id, domain.IdempotencyPending).
+		UpdateColumns(map[string]any{
+			"status":     domain.IdempotencyCompleted,
+			"response":   response,
+			"updated_at": time.Now(),
+			"version":    gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("the idempotency key is no longer pending")
+	}
+	return nil
+}
+
+// Release permanently deletes a pending key
+func (r *idempotencyKeyRepositoryImpl) Release(ctx context.Context, id string) error {
+	return conn(ctx, r.db).Unscoped().
+		Where("id = ? AND status = ?", id, domain.IdempotencyPending).
+		Delete(&domain.IdempotencyKey{}).Error
+}
+
+// DeleteExpired permanently deletes the keys that expired before a time
+func (r *idempotencyKeyRepositoryImpl) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	result := conn(ctx, r.db).Unscoped().Where("expires_at < ?", before).Delete(&domain.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
+
+// WithTx returns a new repository instance with the given transaction
+func (r *idempotencyKeyRepositoryImpl) WithTx(tx *gorm.DB) domain.BaseRepository[domain.IdempotencyKey] {
+	return &idempotencyKeyRepositoryImpl{BaseRepositoryImpl: &BaseRepositoryImpl[domain.IdempotencyKey]{db: tx}}
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// unreferencedUploadsSQL finds the uploads whose files can be removed: pending ones abandoned
+// before the cutoff, rejected ones, and attached ones whose URL no business or staff member shows.
+// Any business counts, not only the target, since cloned businesses share their source's images.
+const unreferencedUploadsSQL = `
+	SELECT u.* FROM image_uploads u
+	WHERE u.deleted_at IS NULL AND (
+		(u.status = 'pending' AND u.upload_expires_at < @cutoff)
+		OR u.status = 'rejected'
+		OR (u.status = 'attached' AND NOT EXISTS (
+			SELECT 1 FROM businesses b
+			WHERE b.deleted_at IS NULL AND (b.logo_url = u.url OR b.cover_photo_url = u.url)
+			UNION ALL
+			SELECT 1 FROM staff s
+			WHERE s.deleted_at IS NULL AND s.profile_image_url = u.url
+		))
+	)
+	ORDER BY u.created_at ASC, u.id ASC
+	LIMIT @limit`
+
+// imageUploadRepositoryImpl implements the ImageUploadRepository interface
+type imageUploadRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.ImageUpload]
+}
+
+// NewImageUploadRepository creates a new image upload repository, scoped to the tenant in the
+// context
+func NewImageUploadRepository(db *gorm.DB) domain.ImageUploadRepository {
+	return &imageUploadRepositoryImpl{
+		BaseRepositoryImpl: newBaseRepository[domain.ImageUpload](db, WithTenantScope()),
+	}
+}
+
+// FindUnreferenced finds the uploads whose files can be removed, across businesses and oldest
+// first
+func (r *imageUploadRepositoryImpl) FindUnreferenced(ctx context.Context, abandonedBefore time.Time, limit int) ([]*domain.ImageUpload, error) {
+	if err := r.checkUnscoped(ctx); err != nil {
+		return nil, err
+	}
+	uploads := []*domain.ImageUpload{}
+	err := conn(ctx, r.db).Raw(unreferencedUploadsSQL, map[string]any{
+		"cutoff": abandonedBefore,
+		"limit":  limit,
+	}).Scan(&uploads).Error
+	return uploads, err
+}
+
+// WithTx returns a new repository instance with the given transaction
+func (r *imageUploadRepositoryImpl) WithTx(tx *gorm.DB) domain.BaseRepository[domain.ImageUpload] {
+	return r.withDB(tx)
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// integrationUsageRepositoryImpl implements the IntegrationUsageRepository interface
+type integrationUsageRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.IntegrationUsageDaily]
+}
+
+// NewIntegrationUsageRepository creates a new integration usage repository, scoped to the tenant
+// in the context
+func NewIntegrationUsageRepository(db *gorm.DB) domain.IntegrationUsageRepository {
+	return &integrationUsageRepositoryImpl{
+		BaseRepositoryImpl: newBaseRepository[domain.IntegrationUsageDaily](db, WithTenantScope()),
+	}
+}
+
+// Increment atomically adds a request, and optionally an error, to the day's counters
+func (r *integrationUsageRepositoryImpl) Increment(ctx context.Context, businessID string, day time.Time, channel domain.IntegrationChannel, sourceID string, failed bool) error {
+	if err := r.checkBusiness(ctx, businessID); err != nil {
+		return err
+	}
+	errorCount := 0
+	if failed {
+		errorCount = 1
+	}
+
+	return conn(ctx, r.db).Exec(`
+		INSERT INTO integration_usage_daily (business_id, usage_date, channel, source_id, request_count, error_count, updated_at)
+		VALUES (?, ?, ?, ?, 1, ?, NOW())
+		ON CONFLICT (business_id, usage_date, channel, source_id) DO UPDATE SET
+			request_count = integration_usage_daily.request_count + 1,
+			error_count = integration_usage_daily.error_count + EXCLUDED.error_count,
+			updated_at = NOW()`,
+		businessID, day.Format("2006-01-02"), channel, sourceID, errorCount,
+	).Error
+}
+
+// FindByBusiness finds the usage counters of a business between two dates, inclusive
+func (r *integrationUsageRepositoryImpl) FindByBusiness(ctx context.Context, businessID string, start, end time.Time) ([]*domain.IntegrationUsageDaily, error) {
+	var usage []*domain.IntegrationUsageDaily
+	err := r.query(ctx).
+		Where("business_id = ? AND usage_date >= ? AND usage_date <= ? AND deleted_at IS NULL",
+			businessID, start.Format("2006-01-02"), end.Format("2006-01-02")).
+		Order("usage_date ASC, channel ASC, source_id ASC").
+		Find(&usage).Error
+	return usage, err
+}
+
+// WithTx returns a new repository instance with the given transaction
+func (r *integrationUsageRepositoryImpl) WithTx(tx *gorm.DB) domain.BaseRepository[domain.IntegrationUsageDaily] {
+	return r.withDB(tx)
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// invoiceRepositoryImpl implements the InvoiceRepository interface
+type invoiceRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.Invoice]
+}
+
+// NewInvoiceRepository creates a new invoice repository, scoped to the tenant in the context
+func NewInvoiceRepository(db *gorm.DB) domain.InvoiceRepository {
+	return &invoiceRepositoryImpl{
+		BaseRepositoryImpl: newBaseRepository[domain.Invoice](db, WithTenantScope()),
+	}
+}
+
+// FindByAppointmentID finds the invoice issued for an appointment
+func (r *invoiceRepositoryImpl) FindByAppointmentID(ctx context.Context, appointmentID string) (*domain.Invoice, error) {
+	var invoice domain.Invoice
+	err := r.query(ctx).
+		Where("appointment_id = ? AND deleted_at IS NULL", appointmentID).
+		First(&invoice).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invoice, nil
+}
+
+// Issue assigns the business's next invoice number and creates the invoice atomically
+func (r *invoiceRepositoryImpl) Issue(ctx context.Context, invoice *domain.Invoice) error {
+	if err := r.claim(ctx, invoice); err != nil {
+		return err
+	}
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var sequence int
+		err := tx.Raw(`
+			INSERT INTO invoice_sequences (business_id, last_number) VALUES (?, 1)
+			ON CONFLICT (business_id) DO UPDATE SET last_number = invoice_sequences.last_number + 1
+			RETURNING last_number`,
+			invoice.BusinessID,
+		).Scan(&sequence).Error
+		if err != nil {
+			return err
+		}
+
+		invoice.InvoiceNumber = domain.FormatInvoiceNumber(invoice.IssuedAt, sequence)
+		return tx.Create(invoice).Error
+	})
+}
+
+// WithTx returns a new repository instance with the given transaction
+func (r *invoiceRepositoryImpl) WithTx(tx *gorm.DB) domain.BaseRepository[domain.Invoice] {
+	return r.withDB(tx)
+}
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// jobRepositoryImpl implements the JobRepository interface
+type jobRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.Job]
+}
+
+// NewJobRepository creates a new job repository
+func NewJobRepository(db *gorm.DB) domain.JobRepository {
+	return &jobRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.Job]{db: db},
+	}
+}
+
+// Enqueue creates a job unless a pending job of the same kind has its unique key, in which case
+// that job is returned instead. The partial unique index on pending keys settles races between
+// callers enqueueing the same job.
+func (r *jobRepositoryImpl) Enqueue(ctx context.Context, job *domain.Job) (*domain.Job, bool, error) {
+	if job.UniqueKey == nil {
+		if err := conn(ctx, r.db).Create(job).Error; err != nil {
+			return nil, false, err
+		}
+		return job, true, nil
+	}
+
+	result := conn(ctx, r.db).
+		Clauses(clause.OnConflict{
+			Columns:     []clause.Column{{Name: "kind"}, {Name: "unique_key"}},
+			TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "status = 'pending' AND unique_key IS NOT NULL AND deleted_at IS NULL"}}},
+			DoNothing:   true,
+		}).
+		Create(job)
+	if result.Error != nil {
+		return nil, false, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return job, true, nil
+	}
+
+	var existing domain.Job
+	err := conn(ctx, r.db).
+		Where("kind = ? AND unique_key = ? AND status = ? AND deleted_at IS NULL", job.Kind, *job.UniqueKey, domain.JobStatusPending).
+		First(&existing).Error
+	if err != nil {
+		return nil, false, err
+	}
+	return &existing, false, nil
+}
+
+// ClaimDue leases the pending jobs that are due, skipping the rows other workers hold locked
+// while they claim theirs
+func (r *jobRepositoryImpl) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.Job, error) {
+	var jobs []*domain.Job
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND run_at <= ? AND deleted_at IS NULL", domain.JobStatusPending, now).
+			Order("run_at ASC, id ASC").
+			Limit(limit).
+			Find(&jobs).Error
+		if err != nil || len(jobs) == 0 {
+			return err
+		}
+
+		leasedUntil := now.Add(lease)
+		ids := make([]string, 0, len(jobs))
+		for _, job := range jobs {
+			ids = append(ids, job.ID)
+			job.RunAt = leasedUntil
+		}
+		return tx.Model(&domain.Job{}).Where("id IN ?", ids).Update("run_at", leasedUntil).Error
+	})
+	return jobs, err
+}
+
+// CancelPending cancels the pending job of a kind with a unique key
+func (r *jobRepositoryImpl) CancelPending(ctx context.Context, kind, uniqueKey string, now time.Time) (bool, error) {
+	result := conn(ctx, r.db).
+		Model(&domain.Job{}).
+		Where("kind = ? AND unique_key = ? AND status = ? AND deleted_at IS NULL", kind, uniqueKey, domain.JobStatusPending).
+		Updates(map[string]any{
+			"status":       domain.JobStatusCancelled,
+			"completed_at": now,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// OldestDue returns when the oldest pending job that is due was due. Leased jobs are due again
+// only once their lease runs out.
+func (r *jobRepositoryImpl) OldestDue(ctx context.Context, now time.Time) (*time.Time, error) {
+	var dueAt *time.Time
+	err := conn(ctx, r.db).
+		Model(&domain.Job{}).
+		Where("status = ? AND run_at <= ? AND deleted_at IS NULL", domain.JobStatusPending, now).
+		Select("MIN(run_at)").
+		Scan(&dueAt).Error
+	return dueAt, err
+}
+
+// Search finds the jobs matching the filter, most recently due first
+func (r *jobRepositoryImpl) Search(ctx context.Context, filter domain.JobFilter, page, pageSize int) ([]*domain.Job, int64, error) {
+	var jobs []*domain.Job
+	var total int64
+
+	query := conn(ctx, r.db).
+		Model(&domain.Job{}).
+		Where("deleted_at IS NULL")
+
+	if filter.Status != nil {
+		query = query.Where("status = ?", *filter.Status)
+	}
+	if filter.Kind != nil {
+		query = query.Where("kind = ?", *filter.Kind)
+	}
+	if filter.BusinessID != nil {
+		query = query.Where("business_id = ?", *filter.BusinessID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Order("run_at DESC, id DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&jobs).Error
+
+	return jobs, total, err
+}
+
+// WithTx returns a new repository instance with the given transaction
+func (r *jobRepositoryImpl) WithTx(tx *gorm.DB) domain.BaseRepository[domain.Job] {
+	return &BaseRepositoryImpl[domain.Job]{db: tx}
+}
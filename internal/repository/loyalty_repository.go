@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// loyaltyRepositoryImpl implements the LoyaltyRepository interface
+type loyaltyRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.LoyaltyProgram]
+}
+
+// NewLoyaltyRepository creates a new loyalty repository, scoped to the tenant in the context
+func NewLoyaltyRepository(db *gorm.DB) domain.LoyaltyRepository {
+	return &loyaltyRepositoryImpl{
+		BaseRepositoryImpl: newBaseRepository[domain.LoyaltyProgram](db, WithTenantScope()),
+	}
+}
+
+// FindRunningPrograms finds the business's programs awarding points at the given time
+func (r *loyaltyRepositoryImpl) FindRunningPrograms(ctx context.Context, businessID string, at time.Time) ([]*domain.LoyaltyProgram, error) {
+	var programs []*domain.LoyaltyProgram
+	err := r.query(ctx).
+		Where("business_id = ? AND is_active", businessID).
+		Where("start_date IS NULL OR start_date <= ?", at).
+		Where("end_date IS NULL OR end_date > ?", at).
+		Order("created_at ASC").
+		Find(&programs).Error
+	return programs, err
+}
+
+// FindMembership finds a client's membership of a program
+func (r *loyaltyRepositoryImpl) FindMembership(ctx context.Context, programID, clientID string) (*domain.ClientLoyaltyMembership, error) {
+	var membership domain.ClientLoyaltyMembership
+	err := r.scopedThrough(ctx, conn(ctx, r.db), "program_id", &domain.LoyaltyProgram{}).
+		Where("program_id = ? AND client_id = ?", programID, clientID).
+		First(&membership).Error
+	if err != nil {
+		return nil, err
+	}
+	return &membership, nil
+}
+
+// HasEarned reports whether the membership already earned points for the appointment
+func (r *loyaltyRepositoryImpl) HasEarned(ctx context.Context, membershipID, appointmentID string) (bool, error) {
+	var count int64
+	err := r.scopedThrough(ctx, conn(ctx, r.db), "appointment_id", &domain.Appointment{}).
+		Model(&domain.LoyaltyTransaction{}).
+		Where("membership_id = ? AND appointment_id = ? AND transaction_type = ?", membershipID, appointmentID, domain.LoyaltyTransactionEarn).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// FindAppointmentLines finds the services performed during an appointment
+func (r *loyaltyRepositoryImpl) FindAppointmentLines(ctx context.Context, appointmentID string) ([]*domain.AppointmentLine, error) {
+	var lines []*domain.AppointmentLine
+	err := r.scopedThrough(ctx, conn(ctx, r.db), "appointment_id", &domain.Appointment{}).
+		Where("appointment_id = ?", appointmentID).
+		Order("created_at ASC").
+		Find(&lines).Error
+	return lines, err
+}
+
+// RecordEarning saves the membership, creating it on the client's first earning visit,
+// together with the transaction recording the points
+func (r *loyaltyRepositoryImpl) RecordEarning(ctx context.Context, membership *domain.ClientLoyaltyMembership, transaction *domain.LoyaltyTransaction) error {
+	if err := r.ensureParentOwned(ctx, &domain.LoyaltyProgram{}, membership.ProgramID); err != nil {
+		return err
+	}
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(membership).Error; err != nil {
+			return err
+		}
+		transaction.MembershipID = membership.ID
+		return tx.Create(transaction).Error
+	})
+}
+
+// SaveMembership saves a membership, creating it when it is new
+func (r *loyaltyRepositoryImpl) SaveMembership(ctx context.Context, membership *domain.ClientLoyaltyMembership) error {
+	if err := r.ensureParentOwned(ctx, &domain.LoyaltyProgram{}, membership.ProgramID); err != nil {
+		return err
+	}
+	return conn(ctx, r.db).Save(membership).Error
+}
+
+// FindMembershipByReferralCode finds the membership a referral code was given to
+func (r *loyaltyRepositoryImpl) FindMembershipByReferralCode(ctx context.Context, code string) (*domain.ClientLoyaltyMembership, error) {
+	var membership domain.ClientLoyaltyMembership
+	err := r.scopedThrough(ctx, conn(ctx, r.db), "program_id", &domain.LoyaltyProgram{}).
+		Where("referral_code = ?", code).
+		First(&membership).Error
+	if err != nil {
+		return nil, err
+	}
+	return &membership, nil
+}
+
+// FindReferral finds how a client was referred to the business
+func (r *loyaltyRepositoryImpl) FindReferral(ctx context.Context, businessID, referredClientID string) (*domain.Referral, error) {
+	var referral domain.Referral
+	err := r.query(ctx).
+		Where("business_id = ? AND referred_client_id = ?", businessID, referredClientID).
+		First(&referral).Error
+	if err != nil {
+		return nil, err
+	}
+	return &referral, nil
+}
+
+// CreateReferral creates a referral
+func (r *loyaltyRepositoryImpl) CreateReferral(ctx context.Context, referral *domain.Referral) error {
+	if err := r.checkBusiness(ctx, referral.BusinessID); err != nil {
+		return err
+	}
+	return conn(ctx, r.db).Create(referral).Error
+}
+
+// RecordReferralBonus saves a converted referral together with the referrer's membership and
+// the transaction awarding the bonus, when one was awarded
+func (r *loyaltyRepositoryImpl) RecordReferralBonus(ctx context.Context, referral *domain.Referral, membership *domain.ClientLoyaltyMembership, transaction *domain.LoyaltyTransaction) error {
+	if err := r.checkBusiness(ctx, referral.BusinessID); err != nil {
+		return err
+	}
+	if membership != nil {
+		if err := r.ensureParentOwned(ctx, &domain.LoyaltyProgram{}, membership.ProgramID); err != nil {
+			return err
+		}
+	}
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(referral).Error; err != nil {
+			return err
+		}
+		if transaction == nil {
+			return nil
+		}
+		if err := tx.Save(membership).Error; err != nil {
+			return err
+		}
+		transaction.MembershipID = membership.ID
+		return tx.Create(transaction).Error
+	})
+}
+
+// FindReferrals finds the business's referrals made between from and to, oldest first
+func (r *loyaltyRepositoryImpl) FindReferrals(ctx context.Context, businessID string, from, to time.Time) ([]*domain.Referral, error) {
+	var referrals []*domain.Referral
+	err := r.query(ctx).
+		Where("business_id = ? AND created_at >= ? AND created_at < ?", businessID, from, to).
+		Order("created_at ASC").
+		Find(&referrals).Error
+	return referrals, err
+}
+
+// WithTx returns a new repository instance with the given transaction
+func (r *loyaltyRepositoryImpl) WithTx(tx *gorm.DB) domain.BaseRepository[domain.LoyaltyProgram] {
+	return r.withDB(tx)
+}
@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// membershipRepositoryImpl implements the MembershipRepository interface
+type membershipRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.ClientMembership]
+}
+
+// NewMembershipRepository creates a new membership repository, scoped to the tenant in the context
+func NewMembershipRepository(db *gorm.DB) domain.MembershipRepository {
+	return &membershipRepositoryImpl{
+		BaseRepositoryImpl: newBaseRepository[domain.ClientMembership](db, WithTenantScope()),
+	}
+}
+
+// FindByClientID finds the client's memberships, oldest first
+func (r *membershipRepositoryImpl) FindByClientID(ctx context.Context, clientID string) ([]*domain.ClientMembership, error) {
+	var memberships []*domain.ClientMembership
+	err := r.query(ctx).
+		Where("client_id = ? AND deleted_at IS NULL", clientID).
+		Order("created_at ASC").
+		Find(&memberships).Error
+	return memberships, err
+}
+
+// FindCurrent finds the client's membership of the plan that has not been cancelled
+func (r *membershipRepositoryImpl) FindCurrent(ctx context.Context, clientID, planID string) (*domain.ClientMembership, error) {
+	var membership domain.ClientMembership
+	err := r.query(ctx).
+		Where("client_id = ? AND plan_id = ? AND status <> ? AND deleted_at IS NULL", clientID, planID, domain.MembershipCancelled).
+		First(&membership).Error
+	if err != nil {
+		return nil, err
+	}
+	return &membership, nil
+}
+
+// FindDue finds the active memberships whose next period is due, and the past due memberships
+// whose retry is due or whose grace period has ended
+func (r *membershipRepositoryImpl) FindDue(ctx context.Context, now time.Time) ([]*domain.ClientMembership, error) {
+	if err := r.checkUnscoped(ctx); err != nil {
+		return nil, err
+	}
+	var memberships []*domain.ClientMembership
+	err := conn(ctx, r.db).
+		Where("deleted_at IS NULL").
+		Where("(status = ? AND next_billing_at <= ?) OR (status = ? AND (next_retry_at <= ? OR grace_until <= ?))",
+			domain.MembershipActive, now, domain.MembershipPastDue, now, now).
+		Order("next_billing_at ASC").
+		Find(&memberships).Error
+	return memberships, err
+}
+
+// FindOpenInvoice finds the membership's invoice awaiting payment
+func (r *membershipRepositoryImpl) FindOpenInvoice(ctx context.Context, membershipID string) (*domain.MembershipInvoice, error) {
+	var invoice domain.MembershipInvoice
+	err := r.query(ctx).
+		Where("membership_id = ? AND status = ? AND deleted_at IS NULL", membershipID, domain.MembershipInvoiceOpen).
+		Order("period_start ASC").
+		First(&invoice).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invoice, nil
+}
+
+// FindInvoices finds the membership's invoices, most recent period first
+func (r *membershipRepositoryImpl) FindInvoices(ctx context.Context, membershipID string) ([]*domain.MembershipInvoice, error) {
+	var invoices []*domain.MembershipInvoice
+	err := r.query(ctx).
+		Where("membership_id = ? AND deleted_at IS NULL", membershipID).
+		Order("period_start DESC").
+		Find(&invoices).Error
+	return invoices, err
+}
+
+// SaveBilling saves a membership together with its invoice in a single transaction
+func (r *membershipRepositoryImpl) SaveBilling(ctx context.Context, membership *domain.ClientMembership, invoice *domain.MembershipInvoice) error {
+	if err := r.claim(ctx, membership); err != nil {
+		return err
+	}
+	if invoice != nil {
+		if err := r.checkBusiness(ctx, invoice.BusinessID); err != nil {
+			return err
+		}
+	}
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(membership).Error; err != nil {
+			return err
+		}
+		if invoice == nil {
+			return nil
+		}
+		invoice.MembershipID = membership.ID
+		return tx.Save(invoice).Error
+	})
+}
+
+// UseSession takes a session off the membership's current period, guarded so that concurrent
+// bookings cannot take it below zero
+func (r *membershipRepositoryImpl) UseSession(ctx context.Context, membershipID string, by *string) error {
+	result := r.query(ctx).Model(&domain.ClientMembership{}).
+		Where("id = ? AND sessions_remaining IS NOT NULL AND sessions_remaining > 0", membershipID).
+		Updates(map[string]any{
+			"sessions_remaining": gorm.Expr("sessions_remaining - 1"),
+			"updated_at":         time.Now(),
+			"updated_by":         by,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNoSessionsLeft
+	}
+	return nil
+}
+
+// Cancel saves a cancelled membership together with its credit note and the gift card holding
+// the credit in a single transaction
+func (r *membershipRepositoryImpl) Cancel(ctx context.Context, membership *domain.ClientMembership, creditNote *domain.MembershipCreditNote, giftCard *domain.GiftCard) error {
+	if err := r.claim(ctx, membership); err != nil {
+		return err
+	}
+	if giftCard != nil {
+		if err := r.checkBusiness(ctx, giftCard.BusinessID); err != nil {
+			return err
+		}
+	}
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(membership).Error; err != nil {
+			return err
+		}
+		if giftCard != nil {
+			if err := tx.Create(giftCard).Error; err != nil {
+				return err
+			}
+			if err := tx.Create(&domain.GiftCardTransaction{
+				BaseModel:    domain.BaseModel{CreatedBy: giftCard.CreatedBy},
+				BusinessID:   giftCard.BusinessID,
+				GiftCardID:   giftCard.ID,
+				Kind:         domain.GiftCardTransactionIssue,
+				Amount:       giftCard.InitialValue,
+				BalanceAfter: giftCard.Balance,
+			}).Error; err != nil {
+				return err
+			}
+			creditNote.GiftCardID = &giftCard.ID
+		}
+		if creditNote == nil {
+			return nil
+		}
+		creditNote.MembershipID = membership.ID
+		return tx.Create(creditNote).Error
+	})
+}
+
+// FindCreditNotes finds the membership's credit notes, oldest first
+func (r *membershipRepositoryImpl) FindCreditNotes(ctx context.Context, membershipID string) ([]*domain.MembershipCreditNote, error) {
+	var creditNotes []*domain.MembershipCreditNote
+	err := r.query(ctx).
+		Where("membership_id = ? AND deleted_at IS NULL", membershipID).
+		Order("created_at ASC").
+		Find(&creditNotes).Error
+	return creditNotes, err
+}
+
+// WithTx returns a new repository instance with the given transaction
+func (r *membershipRepositoryImpl) WithTx(tx *gorm.DB) domain.BaseRepository[domain.ClientMembership] {
+	return r.withDB(tx)
+}
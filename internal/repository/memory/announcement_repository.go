@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// announcementRepositoryImpl implements the AnnouncementRepository interface
+type announcementRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.Announcement]
+}
+
+// NewAnnouncementRepository creates a new announcement repository
+func NewAnnouncementRepository(db *DB) domain.AnnouncementRepository {
+	return &announcementRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.Announcement]{db: db},
+	}
+}
+
+// FindAll finds every announcement, including scheduled and expired ones, latest first
+func (r *announcementRepositoryImpl) FindAll(ctx context.Context, page, pageSize int) ([]*domain.Announcement, int64, error) {
+	defer r.db.lock()()
+	announcements := r.table().where(nil)
+	sortByPublication(announcements)
+	return paginate(announcements, page, pageSize), int64(len(announcements)), nil
+}
+
+// FindLive finds the announcements live at a time, most recently published first
+func (r *announcementRepositoryImpl) FindLive(ctx context.Context, now time.Time) ([]*domain.Announcement, error) {
+	defer r.db.lock()()
+	announcements := r.table().where(func(a *domain.Announcement) bool { return a.IsLive(now) })
+	sortByPublication(announcements)
+	return announcements, nil
+}
+
+// FindReads finds when a user read the given announcements, by announcement ID
+func (r *announcementRepositoryImpl) FindReads(ctx context.Context, userID string, announcementIDs []string) (map[string]time.Time, error) {
+	defer r.db.lock()()
+	reads := make(map[string]time.Time)
+	for _, read := range r.reads(userID, announcementIDs) {
+		reads[read.AnnouncementID] = read.ReadAt
+	}
+	return reads, nil
+}
+
+// MarkRead records that a user read announcements, returning how many were unread. Reads
+// already recorded keep their original time.
+func (r *announcementRepositoryImpl) MarkRead(ctx context.Context, userID string, announcementIDs []string, at time.Time) (int64, error) {
+	defer r.db.lock()()
+	var marked int64
+	for _, id := range announcementIDs {
+		if len(r.reads(userID, []string{id})) > 0 {
+			continue
+		}
+		if err := tableOf[domain.AnnouncementRead](r.db).insert(&domain.AnnouncementRead{AnnouncementID: id, UserID: userID, ReadAt: at}); err != nil {
+			return marked, err
+		}
+		marked++
+	}
+	return marked, nil
+}
+
+// reads returns the reads a user recorded for the given announcements. The caller must hold
+// the lock.
+func (r *announcementRepositoryImpl) reads(userID string, announcementIDs []string) []*domain.AnnouncementRead {
+	return tableOf[domain.AnnouncementRead](r.db).where(func(read *domain.AnnouncementRead) bool {
+		return read.UserID == userID && slices.Contains(announcementIDs, read.AnnouncementID)
+	})
+}
+
+// sortByPublication orders announcements by when they were published, latest first
+func sortByPublication(announcements []*domain.Announcement) {
+	slices.SortFunc(announcements, func(a, b *domain.Announcement) int {
+		return cmp.Or(b.PublishAt.Compare(a.PublishAt), cmp.Compare(a.ID, b.ID))
+	})
+}
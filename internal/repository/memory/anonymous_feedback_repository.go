@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// anonymousFeedbackRepositoryImpl implements the AnonymousFeedbackRepository interface
+type anonymousFeedbackRepositoryImpl struct {
+	db *DB
+}
+
+// NewAnonymousFeedbackRepository creates a new anonymous feedback repository
+func NewAnonymousFeedbackRepository(db *DB) domain.AnonymousFeedbackRepository {
+	return &anonymousFeedbackRepositoryImpl{db: db}
+}
+
+// FindTokenByAppointment finds the feedback token issued for an appointment
+func (r *anonymousFeedbackRepositoryImpl) FindTokenByAppointment(ctx context.Context, appointmentID string) (*domain.FeedbackToken, error) {
+	defer r.db.lock()()
+	return tableOf[domain.FeedbackToken](r.db).first(func(t *domain.FeedbackToken) bool { return t.AppointmentID == appointmentID })
+}
+
+// FindTokenByToken finds a feedback token by the token in its link
+func (r *anonymousFeedbackRepositoryImpl) FindTokenByToken(ctx context.Context, token string) (*domain.FeedbackToken, error) {
+	defer r.db.lock()()
+	return tableOf[domain.FeedbackToken](r.db).first(func(t *domain.FeedbackToken) bool { return t.Token == token })
+}
+
+// SaveToken creates a token or replaces an expired one
+func (r *anonymousFeedbackRepositoryImpl) SaveToken(ctx context.Context, token *domain.FeedbackToken) error {
+	defer r.db.lock()()
+	return tableOf[domain.FeedbackToken](r.db).save(token)
+}
+
+// Submit uses the token and stores the feedback at once. The token is updated without
+// touching its timestamps, so nothing records when it was used.
+func (r *anonymousFeedbackRepositoryImpl) Submit(ctx context.Context, tokenID string, feedback *domain.AnonymousFeedback) error {
+	defer r.db.lock()()
+	used := tableOf[domain.FeedbackToken](r.db).updateWhere(func(t *domain.FeedbackToken) bool {
+		return t.ID == tokenID && !t.Used
+	}, func(t *domain.FeedbackToken) {
+		t.Used = true
+	})
+	if used == 0 {
+		return domain.ErrFeedbackTokenUsed
+	}
+	return tableOf[domain.AnonymousFeedback](r.db).insert(feedback)
+}
+
+// FindByBusiness finds the feedback sent to a business, most recent day first. Feedback sent
+// on the same day is not ordered by when it arrived.
+func (r *anonymousFeedbackRepositoryImpl) FindByBusiness(ctx context.Context, businessID string, unreadOnly bool, page, pageSize int) ([]*domain.AnonymousFeedback, int64, error) {
+	defer r.db.lock()()
+	feedback := tableOf[domain.AnonymousFeedback](r.db).where(func(f *domain.AnonymousFeedback) bool {
+		return f.BusinessID == businessID && (!unreadOnly || f.ReadAt == nil)
+	})
+	slices.SortStableFunc(feedback, func(a, b *domain.AnonymousFeedback) int {
+		if order := b.SubmittedOn.Compare(a.SubmittedOn); order != 0 {
+			return order
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return paginate(feedback, page, pageSize), int64(len(feedback)), nil
+}
+
+// MarkRead marks feedback of a business as read, returning how many were unread
+func (r *anonymousFeedbackRepositoryImpl) MarkRead(ctx context.Context, businessID string, ids []string, at time.Time, by *string) (int64, error) {
+	defer r.db.lock()()
+	return tableOf[domain.AnonymousFeedback](r.db).updateWhere(func(f *domain.AnonymousFeedback) bool {
+		return f.BusinessID == businessID && slices.Contains(ids, f.ID) && f.ReadAt == nil
+	}, func(f *domain.AnonymousFeedback) {
+		f.ReadAt = &at
+		f.UpdatedAt = &at
+		f.UpdatedBy = by
+	}), nil
+}
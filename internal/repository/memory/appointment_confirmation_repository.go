@@ -0,0 +1,259 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// appointmentConfirmationRepositoryImpl implements the AppointmentConfirmationRepository interface
+type appointmentConfirmationRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.AppointmentConfirmation]
+}
+
+// NewAppointmentConfirmationRepository creates a new appointment confirmation repository, scoped
+// to the tenant in the context
+func NewAppointmentConfirmationRepository(db *DB) domain.AppointmentConfirmationRepository {
+	return &appointmentConfirmationRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.AppointmentConfirmation]{db: db, tenantScoped: true},
+	}
+}
+
+// FindByToken finds a confirmation request by the token in its link
+func (r *appointmentConfirmationRepositoryImpl) FindByToken(ctx context.Context, token string) (*domain.AppointmentConfirmation, error) {
+	defer r.db.lock()()
+	return r.table().first(func(c *domain.AppointmentConfirmation) bool { return c.Token == token })
+}
+
+// FindPendingByPhone finds the most recent pending SMS request sent to a phone number for an
+// upcoming appointment. The phone must be normalized to digits; it matches recipients stored
+// with or without the country code.
+func (r *appointmentConfirmationRepositoryImpl) FindPendingByPhone(ctx context.Context, phone string) (*domain.AppointmentConfirmation, error) {
+	defer r.db.lock()()
+	now := r.db.now()
+	appointments := tableOf[domain.Appointment](r.db)
+	confirmations := r.table().where(func(c *domain.AppointmentConfirmation) bool {
+		recipient := domain.NormalizePhone(c.Recipient)
+		if c.Status != domain.ConfirmationStatusPending || c.Channel != domain.MessageChannelSMS ||
+			len(recipient) < 6 || !strings.HasSuffix(phone, recipient) {
+			return false
+		}
+		appointment, err := appointments.get(c.AppointmentID)
+		return err == nil && appointment.StartTime.After(now)
+	})
+	if len(confirmations) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	slices.SortStableFunc(confirmations, func(a, b *domain.AppointmentConfirmation) int {
+		return b.SentAt.Compare(a.SentAt)
+	})
+	return confirmations[0], nil
+}
+
+// FindDueForRequest finds the appointments that should be sent a confirmation request now
+func (r *appointmentConfirmationRepositoryImpl) FindDueForRequest(ctx context.Context, now time.Time, limit int) ([]*domain.ConfirmationCandidate, error) {
+	defer r.db.lock()()
+	candidates := []*domain.ConfirmationCandidate{}
+	appointments := tableOf[domain.Appointment](r.db).where(func(a *domain.Appointment) bool {
+		return isPendingConfirmation(a) && a.StartTime.After(now)
+	})
+	sortByStart(appointments)
+	for _, a := range appointments {
+		client, ok := clientContact(r.db, a.ClientID)
+		if !ok || (blank(client.Phone) && blank(&client.Email)) {
+			continue
+		}
+		leadHours := domain.DefaultConfirmationLeadHours
+		if settings := businessSettings(r.db, a.BusinessID); settings != nil {
+			leadHours = settings.ConfirmationLeadHours
+		}
+		if leadHours <= 0 || a.StartTime.After(now.Add(time.Duration(leadHours)*time.Hour)) {
+			continue
+		}
+		requested := r.table().countUnscoped(func(c *domain.AppointmentConfirmation) bool { return c.AppointmentID == a.ID })
+		if requested > 0 {
+			continue
+		}
+
+		candidates = append(candidates, &domain.ConfirmationCandidate{
+			AppointmentID: a.ID,
+			BusinessID:    a.BusinessID,
+			ClientID:      a.ClientID,
+			StartTime:     a.StartTime,
+			EndTime:       a.EndTime,
+			ClientPhone:   client.Phone,
+			ClientEmail:   stringPtr(client.Email),
+		})
+		if len(candidates) == limit {
+			break
+		}
+	}
+	return candidates, nil
+}
+
+// FindUnconfirmed finds a business's unconfirmed appointments starting in a time range
+func (r *appointmentConfirmationRepositoryImpl) FindUnconfirmed(ctx context.Context, businessID string, start, end time.Time) ([]*domain.UnconfirmedAppointment, error) {
+	defer r.db.lock()()
+	unconfirmed := []*domain.UnconfirmedAppointment{}
+	appointments := tableOf[domain.Appointment](r.db).where(func(a *domain.Appointment) bool {
+		return a.BusinessID == businessID && isPendingConfirmation(a) && inRange(a.StartTime, start, end)
+	})
+	for _, a := range appointments {
+		client, ok := clientContact(r.db, a.ClientID)
+		if !ok {
+			continue
+		}
+		user, ok := staffUser(r.db, a.StaffID)
+		if !ok {
+			continue
+		}
+
+		appointment := &domain.UnconfirmedAppointment{
+			AppointmentID: a.ID,
+			StaffID:       a.StaffID,
+			ClientID:      a.ClientID,
+			StartTime:     a.StartTime,
+			EndTime:       a.EndTime,
+			Status:        a.Status,
+			ClientName:    client.FirstName + " " + client.LastName,
+			ClientPhone:   client.Phone,
+			ClientEmail:   stringPtr(client.Email),
+			StaffName:     user.FirstName + " " + user.LastName,
+		}
+		if request := r.latest(a.ID); request != nil {
+			appointment.RequestStatus = &request.Status
+			appointment.RequestChannel = &request.Channel
+			appointment.RequestSentAt = &request.SentAt
+		}
+		unconfirmed = append(unconfirmed, appointment)
+	}
+
+	slices.SortStableFunc(unconfirmed, func(a, b *domain.UnconfirmedAppointment) int {
+		if order := a.StartTime.Compare(b.StartTime); order != 0 {
+			return order
+		}
+		return strings.Compare(a.StaffName, b.StaffName)
+	})
+	return unconfirmed, nil
+}
+
+// ConfirmAppointment flags the appointment as confirmed by the client, closes its pending
+// requests and completes the open tasks to call the client about it
+func (r *appointmentConfirmationRepositoryImpl) ConfirmAppointment(ctx context.Context, appointmentID string, source domain.ConfirmationSource, at time.Time) error {
+	defer r.db.lock()()
+	found := tableOf[domain.Appointment](r.db).update(appointmentID, func(a *domain.Appointment) {
+		a.ClientConfirmed = true
+		a.UpdatedAt = at
+	})
+	if !found {
+		return gorm.ErrRecordNotFound
+	}
+
+	r.table().updateWhere(func(c *domain.AppointmentConfirmation) bool {
+		return c.AppointmentID == appointmentID && c.Status == domain.ConfirmationStatusPending
+	}, func(c *domain.AppointmentConfirmation) {
+		c.Status = domain.ConfirmationStatusConfirmed
+		c.RespondedAt = &at
+		c.ResponseSource = &source
+		c.UpdatedAt = at
+	})
+
+	outcome := domain.CallClientTaskConfirmedOutcome
+	tableOf[domain.FrontDeskTask](r.db).updateWhere(func(t *domain.FrontDeskTask) bool {
+		return t.AppointmentID != nil && *t.AppointmentID == appointmentID &&
+			t.Kind == domain.FrontDeskTaskCallClient && t.IsOpen()
+	}, func(t *domain.FrontDeskTask) {
+		t.Complete(&outcome, nil, at)
+		t.UpdatedAt = at
+	})
+	return nil
+}
+
+// FindByAppointment finds the requests of an appointment's chain, first attempt first
+func (r *appointmentConfirmationRepositoryImpl) FindByAppointment(ctx context.Context, appointmentID string) ([]*domain.AppointmentConfirmation, error) {
+	defer r.db.lock()()
+	confirmations := r.table().where(func(c *domain.AppointmentConfirmation) bool { return c.AppointmentID == appointmentID })
+	slices.SortStableFunc(confirmations, func(a, b *domain.AppointmentConfirmation) int { return a.Attempt - b.Attempt })
+	return confirmations, nil
+}
+
+// FindDueForEscalation finds the appointments whose latest request should be escalated now
+func (r *appointmentConfirmationRepositoryImpl) FindDueForEscalation(ctx context.Context, now time.Time, limit int) ([]*domain.EscalationCandidate, error) {
+	defer r.db.lock()()
+	candidates := []*domain.EscalationCandidate{}
+	appointments := tableOf[domain.Appointment](r.db).where(func(a *domain.Appointment) bool {
+		return isPendingConfirmation(a) && a.StartTime.After(now)
+	})
+	sortByStart(appointments)
+	for _, a := range appointments {
+		request := r.latest(a.ID)
+		if request == nil {
+			continue
+		}
+		cutoffHours := domain.DefaultConfirmationCutoffHours
+		if settings := businessSettings(r.db, a.BusinessID); settings != nil {
+			cutoffHours = settings.ConfirmationCutoffHours
+		}
+		if !request.IsDueForEscalation(now, cutoffHours) {
+			continue
+		}
+		client, ok := clientContact(r.db, a.ClientID)
+		if !ok {
+			continue
+		}
+
+		candidates = append(candidates, &domain.EscalationCandidate{
+			ConfirmationCandidate: domain.ConfirmationCandidate{
+				AppointmentID: a.ID,
+				BusinessID:    a.BusinessID,
+				ClientID:      a.ClientID,
+				StartTime:     a.StartTime,
+				EndTime:       a.EndTime,
+				ClientPhone:   client.Phone,
+				ClientEmail:   stringPtr(client.Email),
+			},
+			ConfirmationID: request.ID,
+			Attempt:        request.Attempt,
+		})
+		if len(candidates) == limit {
+			break
+		}
+	}
+	return candidates, nil
+}
+
+// MarkEscalated records that the next step was taken for a request, unless another run took it
+func (r *appointmentConfirmationRepositoryImpl) MarkEscalated(ctx context.Context, confirmationID string, at time.Time) error {
+	defer r.db.lock()()
+	escalated := r.table().updateWhere(func(c *domain.AppointmentConfirmation) bool {
+		return c.ID == confirmationID && c.EscalatedAt == nil
+	}, func(c *domain.AppointmentConfirmation) {
+		c.EscalatedAt = &at
+		c.UpdatedAt = at
+	})
+	if escalated == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// latest returns the last request of an appointment's chain, or nil when none was sent
+func (r *appointmentConfirmationRepositoryImpl) latest(appointmentID string) *domain.AppointmentConfirmation {
+	var latest *domain.AppointmentConfirmation
+	for _, c := range r.table().where(func(c *domain.AppointmentConfirmation) bool { return c.AppointmentID == appointmentID }) {
+		if latest == nil || c.Attempt > latest.Attempt {
+			latest = c
+		}
+	}
+	return latest
+}
+
+// isPendingConfirmation reports whether an appointment is still going ahead without the
+// client having confirmed it
+func isPendingConfirmation(a *domain.Appointment) bool {
+	return (a.Status == domain.AppointmentStatusScheduled || a.Status == domain.AppointmentStatusConfirmed) && !a.ClientConfirmed
+}
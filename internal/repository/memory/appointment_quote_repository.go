@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// appointmentQuoteRepositoryImpl implements the AppointmentQuoteRepository interface
+type appointmentQuoteRepositoryImpl struct {
+	db *DB
+}
+
+// NewAppointmentQuoteRepository creates a new appointment quote repository
+func NewAppointmentQuoteRepository(db *DB) domain.AppointmentQuoteRepository {
+	return &appointmentQuoteRepositoryImpl{db: db}
+}
+
+// Create stores a quote
+func (r *appointmentQuoteRepositoryImpl) Create(ctx context.Context, quote *domain.AppointmentQuote) error {
+	defer r.db.lock()()
+	return tableOf[domain.AppointmentQuote](r.db).insert(quote)
+}
+
+// FindByToken finds a quote by the token it is booked with
+func (r *appointmentQuoteRepositoryImpl) FindByToken(ctx context.Context, token string) (*domain.AppointmentQuote, error) {
+	defer r.db.lock()()
+	return tableOf[domain.AppointmentQuote](r.db).first(func(q *domain.AppointmentQuote) bool { return q.Token == token })
+}
+
+// FindPromotions finds the active discount campaigns of the business the client was targeted by
+// and did not unsubscribe from, running at the given time
+func (r *appointmentQuoteRepositoryImpl) FindPromotions(ctx context.Context, businessID, clientID string, at time.Time) ([]*domain.Campaign, error) {
+	defer r.db.lock()()
+	targeted := map[string]bool{}
+	for _, target := range tableOf[domain.CampaignClient](r.db).where(func(c *domain.CampaignClient) bool {
+		return c.ClientID == clientID && c.Status != domain.CampaignClientUnsubscribed
+	}) {
+		targeted[target.CampaignID] = true
+	}
+	campaigns := tableOf[domain.Campaign](r.db).where(func(c *domain.Campaign) bool {
+		return c.BusinessID == businessID && c.IsActive && c.OfferType == domain.OfferTypeDiscount && targeted[c.ID] &&
+			!c.StartDate.After(at) && c.EndDate.After(at)
+	})
+	slices.SortFunc(campaigns, func(a, b *domain.Campaign) int {
+		if c := a.StartDate.Compare(b.StartDate); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return campaigns, nil
+}
+
+// MarkUsed records the appointment booked with the quote. The memory store has no
+// transactions, so tx is ignored.
+func (r *appointmentQuoteRepositoryImpl) MarkUsed(ctx context.Context, tx *gorm.DB, quoteID, appointmentID string) error {
+	defer r.db.lock()()
+	now := r.db.now()
+	used := tableOf[domain.AppointmentQuote](r.db).updateWhere(func(q *domain.AppointmentQuote) bool {
+		return q.ID == quoteID && q.AppointmentID == nil
+	}, func(q *domain.AppointmentQuote) {
+		q.AppointmentID = &appointmentID
+		q.UpdatedAt = now
+		q.Version++
+	})
+	if used == 0 {
+		return domain.ErrQuoteUsed
+	}
+	return nil
+}
@@ -0,0 +1,311 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// appointmentRepositoryImpl implements the AppointmentRepository interface
+type appointmentRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.Appointment]
+}
+
+// NewAppointmentRepository creates a new appointment repository, scoped to the tenant in the
+// context
+func NewAppointmentRepository(db *DB) domain.AppointmentRepository {
+	return &appointmentRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.Appointment]{db: db, tenantScoped: true},
+	}
+}
+
+// Create creates an appointment, rejecting it with a domain.AppointmentConflictError when the
+// staff member is not available at its time
+func (r *appointmentRepositoryImpl) Create(ctx context.Context, appointment *domain.Appointment) error {
+	defer r.db.lock()()
+	if err := r.claim(ctx, appointment); err != nil {
+		return err
+	}
+	if err := r.ensureAvailable(appointment, nil); err != nil {
+		return err
+	}
+	return r.table().insert(appointment)
+}
+
+// CreateWithLines creates an appointment and the services performed during it, subject to the
+// same availability check as Create
+func (r *appointmentRepositoryImpl) CreateWithLines(ctx context.Context, appointment *domain.Appointment, lines []*domain.AppointmentLine) error {
+	defer r.db.lock()()
+	if err := r.claim(ctx, appointment); err != nil {
+		return err
+	}
+	serviceIDs := make([]string, len(lines))
+	for i, line := range lines {
+		serviceIDs[i] = line.ServiceID
+	}
+	if err := r.ensureAvailable(appointment, serviceIDs); err != nil {
+		return err
+	}
+	if err := r.table().insert(appointment); err != nil {
+		return err
+	}
+	for _, line := range lines {
+		line.AppointmentID = appointment.ID
+		if err := tableOf[domain.AppointmentLine](r.db).insert(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Update updates an appointment. Appointments that still occupy the staff member's time are
+// checked for conflicts at their new time. The update fails with a domain.ConflictError when
+// the appointment was changed since the version it carries was read.
+func (r *appointmentRepositoryImpl) Update(ctx context.Context, appointment *domain.Appointment) error {
+	defer r.db.lock()()
+	version, err := r.checkUpdate(ctx, appointment)
+	if err != nil {
+		return err
+	}
+	if err := r.ensureAvailable(appointment, r.serviceIDs(appointment.ID)); err != nil {
+		return err
+	}
+	return r.saveVersion(appointment, version)
+}
+
+// ensureAvailable checks the appointment against the staff member's schedule and the other
+// appointments using the resources its services need
+func (r *appointmentRepositoryImpl) ensureAvailable(appointment *domain.Appointment, serviceIDs []string) error {
+	if appointment.Status != "" && !slices.Contains(domain.BlockingAppointmentStatuses(), appointment.Status) {
+		return nil
+	}
+
+	request := domain.AvailabilityRequest{
+		BusinessID: appointment.BusinessID,
+		StaffID:    appointment.StaffID,
+		Start:      appointment.StartTime,
+		End:        appointment.EndTime,
+		ServiceIDs: serviceIDs,
+	}
+	if appointment.ID != "" {
+		request.ExcludeAppointmentID = &appointment.ID
+	}
+	conflicts, err := r.checkAvailability(request)
+	if err != nil {
+		return err
+	}
+	if len(conflicts) > 0 {
+		return &domain.AppointmentConflictError{Conflicts: conflicts}
+	}
+	return nil
+}
+
+// CheckAvailability checks a time slot against the business hours, the appointment buffer,
+// the staff member's other appointments and their availability exceptions, and the other
+// appointments using the resources the services need
+func (r *appointmentRepositoryImpl) CheckAvailability(ctx context.Context, request domain.AvailabilityRequest) ([]*domain.AvailabilityConflict, error) {
+	defer r.db.lock()()
+	return r.checkAvailability(request)
+}
+
+func (r *appointmentRepositoryImpl) checkAvailability(request domain.AvailabilityRequest) ([]*domain.AvailabilityConflict, error) {
+	business, err := tableOf[domain.Business](r.db).get(request.BusinessID)
+	if err != nil {
+		return nil, err
+	}
+	rules := domain.NewAvailabilityRules(business, businessSettings(r.db, request.BusinessID))
+
+	start, end := request.Start.Add(-rules.Buffer), request.End.Add(rules.Buffer)
+	appointments := r.table().where(func(a *domain.Appointment) bool {
+		return a.StaffID == request.StaffID && isBlocking(a.Status) && a.StartTime.Before(end) && a.EndTime.After(start)
+	})
+	sortByStart(appointments)
+	busy := make([]*domain.BusyInterval, len(appointments))
+	for i, a := range appointments {
+		busy[i] = &domain.BusyInterval{AppointmentID: a.ID, StartTime: a.StartTime, EndTime: a.EndTime}
+	}
+
+	exceptions := tableOf[domain.AvailabilityException](r.db).where(func(e *domain.AvailabilityException) bool {
+		return e.StaffID == request.StaffID && e.ExceptionType != domain.ExceptionCustomHours &&
+			(e.IsRecurring || (e.StartTime.Before(request.End) && e.EndTime.After(request.Start)))
+	})
+	shifts := tableOf[domain.StaffShift](r.db).where(func(s *domain.StaffShift) bool { return s.StaffID == request.StaffID })
+
+	conflicts := rules.Check(request, busy, exceptions)
+	if conflict := rules.CheckShifts(request, shifts); conflict != nil {
+		conflicts = append(conflicts, conflict)
+	}
+	return append(conflicts, domain.CheckResources(request, r.resourceUsages(request), rules.Location)...), nil
+}
+
+// resourceUsages finds the active resources the requested services need and the appointments
+// using them during the requested time
+func (r *appointmentRepositoryImpl) resourceUsages(request domain.AvailabilityRequest) []*domain.ResourceUsage {
+	if len(request.ServiceIDs) == 0 {
+		return nil
+	}
+	required := resourcesOf(r.db, request.ServiceIDs)
+	usages := make([]*domain.ResourceUsage, 0, len(required))
+	for _, resource := range required {
+		usage := &domain.ResourceUsage{Resource: resource}
+		appointments := r.table().where(func(a *domain.Appointment) bool {
+			return isBlocking(a.Status) && a.StartTime.Before(request.End) && a.EndTime.After(request.Start) &&
+				slices.ContainsFunc(resourcesOf(r.db, r.serviceIDs(a.ID)), func(used *domain.Resource) bool { return used.ID == resource.ID })
+		})
+		sortByStart(appointments)
+		for _, a := range appointments {
+			usage.Busy = append(usage.Busy, &domain.BusyInterval{AppointmentID: a.ID, StartTime: a.StartTime, EndTime: a.EndTime})
+		}
+		usages = append(usages, usage)
+	}
+	return usages
+}
+
+// serviceIDs returns the services performed during an appointment
+func (r *appointmentRepositoryImpl) serviceIDs(appointmentID string) []string {
+	var serviceIDs []string
+	for _, line := range tableOf[domain.AppointmentLine](r.db).where(func(l *domain.AppointmentLine) bool { return l.AppointmentID == appointmentID }) {
+		serviceIDs = append(serviceIDs, line.ServiceID)
+	}
+	return serviceIDs
+}
+
+// FindSchedules finds the blocking appointments, availability exceptions and shifts of each of
+// the staff members between start and end, in the order of the IDs
+func (r *appointmentRepositoryImpl) FindSchedules(ctx context.Context, staffIDs []string, start, end time.Time) ([]*domain.StaffSchedule, error) {
+	defer r.db.lock()()
+	schedules := make([]*domain.StaffSchedule, len(staffIDs))
+	for i, staffID := range staffIDs {
+		appointments := r.table().where(func(a *domain.Appointment) bool {
+			return a.StaffID == staffID && isBlocking(a.Status) && a.StartTime.Before(end) && a.EndTime.After(start)
+		})
+		sortByStart(appointments)
+		busy := make([]*domain.BusyInterval, len(appointments))
+		for j, a := range appointments {
+			busy[j] = &domain.BusyInterval{AppointmentID: a.ID, StartTime: a.StartTime, EndTime: a.EndTime}
+		}
+
+		exceptions := tableOf[domain.AvailabilityException](r.db).where(func(e *domain.AvailabilityException) bool {
+			return e.StaffID == staffID && e.ExceptionType != domain.ExceptionCustomHours &&
+				(e.IsRecurring || (e.StartTime.Before(end) && e.EndTime.After(start)))
+		})
+		shifts := tableOf[domain.StaffShift](r.db).where(func(s *domain.StaffShift) bool { return s.StaffID == staffID })
+		schedules[i] = &domain.StaffSchedule{StaffID: staffID, Busy: busy, Exceptions: exceptions, Shifts: shifts}
+	}
+	return schedules, nil
+}
+
+// FindByBusinessID finds the appointments of a business matching the filters
+func (r *appointmentRepositoryImpl) FindByBusinessID(ctx context.Context, businessID string, filters domain.AppointmentFilters) ([]*domain.Appointment, error) {
+	defer r.db.lock()()
+	lines := tableOf[domain.AppointmentLine](r.db)
+	appointments := r.table().where(func(a *domain.Appointment) bool {
+		switch {
+		case a.BusinessID != businessID,
+			filters.Status != nil && a.Status != *filters.Status,
+			filters.StaffID != nil && a.StaffID != *filters.StaffID,
+			filters.DateRange != nil && !inRange(a.StartTime, filters.DateRange.Start, filters.DateRange.End):
+			return false
+		case filters.ServiceID != nil:
+			return lines.count(func(l *domain.AppointmentLine) bool {
+				return l.AppointmentID == a.ID && l.ServiceID == *filters.ServiceID
+			}) > 0
+		}
+		return true
+	})
+	sortByStart(appointments)
+	return appointments, nil
+}
+
+// FindByClientID finds all appointments of a client, most recent first
+func (r *appointmentRepositoryImpl) FindByClientID(ctx context.Context, clientID string) ([]*domain.Appointment, error) {
+	defer r.db.lock()()
+	appointments := r.table().where(func(a *domain.Appointment) bool { return a.ClientID == clientID })
+	sortByStart(appointments)
+	slices.Reverse(appointments)
+	return appointments, nil
+}
+
+// FindByStaffID finds the appointments of a staff member starting in a date range
+func (r *appointmentRepositoryImpl) FindByStaffID(ctx context.Context, staffID string, dateRange domain.DateRange) ([]*domain.Appointment, error) {
+	return r.FindByDateRange(ctx, staffID, dateRange.Start, dateRange.End)
+}
+
+// FindByDateRange finds the appointments of a staff member starting between two times
+func (r *appointmentRepositoryImpl) FindByDateRange(ctx context.Context, staffID string, start, end time.Time) ([]*domain.Appointment, error) {
+	defer r.db.lock()()
+	appointments := r.table().where(func(a *domain.Appointment) bool {
+		return a.StaffID == staffID && inRange(a.StartTime, start, end)
+	})
+	sortByStart(appointments)
+	return appointments, nil
+}
+
+// CheckOverlap checks whether a staff member has an appointment overlapping a time range,
+// ignoring the business's buffer
+func (r *appointmentRepositoryImpl) CheckOverlap(ctx context.Context, staffID string, start, end time.Time, excludeID *string) (bool, error) {
+	defer r.db.lock()()
+	count := r.table().count(func(a *domain.Appointment) bool {
+		return a.StaffID == staffID && isBlocking(a.Status) && a.StartTime.Before(end) && a.EndTime.After(start) &&
+			(excludeID == nil || a.ID != *excludeID)
+	})
+	return count > 0, nil
+}
+
+// GetUpcomingByStaff finds a staff member's next appointments
+func (r *appointmentRepositoryImpl) GetUpcomingByStaff(ctx context.Context, staffID string, limit int) ([]*domain.Appointment, error) {
+	defer r.db.lock()()
+	now := r.db.now()
+	appointments := r.table().where(func(a *domain.Appointment) bool {
+		return a.StaffID == staffID && a.StartTime.After(now) &&
+			(a.Status == domain.AppointmentStatusScheduled || a.Status == domain.AppointmentStatusConfirmed)
+	})
+	sortByStart(appointments)
+	return appointments[:min(limit, len(appointments))], nil
+}
+
+// GetDashboardData is not supported: DashboardData has not been defined yet
+func (r *appointmentRepositoryImpl) GetDashboardData(ctx context.Context, businessID string, date time.Time) (*domain.DashboardData, error) {
+	return nil, errors.ErrUnsupported
+}
+
+// GetCalendarView is not supported: calendar views are served by the calendar read model
+func (r *appointmentRepositoryImpl) GetCalendarView(ctx context.Context, businessID string, start, end time.Time) ([]*domain.CalendarAppointment, error) {
+	return nil, errors.ErrUnsupported
+}
+
+// GetByStatus finds the appointments of a business with a status
+func (r *appointmentRepositoryImpl) GetByStatus(ctx context.Context, businessID string, status domain.AppointmentStatus) ([]*domain.Appointment, error) {
+	defer r.db.lock()()
+	appointments := r.table().where(func(a *domain.Appointment) bool {
+		return a.BusinessID == businessID && a.Status == status
+	})
+	sortByStart(appointments)
+	return appointments, nil
+}
+
+// WithTx returns the repository itself, keeping the availability check on writes
+func (r *appointmentRepositoryImpl) WithTx(tx *gorm.DB) domain.BaseRepository[domain.Appointment] {
+	return r
+}
+
+// isBlocking reports whether an appointment with the status occupies the staff member's time
+func isBlocking(status domain.AppointmentStatus) bool {
+	return slices.Contains(domain.BlockingAppointmentStatuses(), status)
+}
+
+// inRange reports whether a time falls in the half-open range [start, end)
+func inRange(t, start, end time.Time) bool {
+	return !t.Before(start) && t.Before(end)
+}
+
+// sortByStart orders appointments by start time, keeping creation order for ties
+func sortByStart(appointments []*domain.Appointment) {
+	slices.SortStableFunc(appointments, func(a, b *domain.Appointment) int {
+		return a.StartTime.Compare(b.StartTime)
+	})
+}
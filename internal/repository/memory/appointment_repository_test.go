@@ -0,0 +1,184 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestAppointmentRepository_RejectsConflicts(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	business := &domain.Business{UserID: "owner-1", Name: "Salon"}
+	require.NoError(t, Insert(db, business))
+	repo := NewAppointmentRepository(db)
+
+	start := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	first := &domain.Appointment{
+		BusinessID: business.ID,
+		StaffID:    "staff-1",
+		ClientID:   "client-1",
+		StartTime:  start,
+		EndTime:    start.Add(time.Hour),
+		Status:     domain.AppointmentStatusScheduled,
+	}
+	require.NoError(t, repo.Create(ctx, first))
+
+	overlapping := *first
+	overlapping.ID = ""
+	overlapping.StartTime = start.Add(30 * time.Minute)
+	overlapping.EndTime = start.Add(90 * time.Minute)
+	var conflict *domain.AppointmentConflictError
+	require.ErrorAs(t, repo.Create(ctx, &overlapping), &conflict)
+	assert.Equal(t, domain.ConflictOverlap, conflict.Conflicts[0].Reason)
+
+	overlapping.StaffID = "staff-2"
+	assert.NoError(t, repo.Create(ctx, &overlapping), "other staff members are free")
+
+	first.Status = domain.AppointmentStatusCancelled
+	require.NoError(t, repo.Update(ctx, first))
+	overlapping.ID = ""
+	overlapping.StaffID = "staff-1"
+	assert.NoError(t, repo.Create(ctx, &overlapping), "cancelled appointments free the slot")
+
+	appointments, err := repo.FindByDateRange(ctx, "staff-1", start, start.Add(24*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, appointments, 2)
+	assert.Equal(t, first.ID, appointments[0].ID)
+}
+
+func TestAppointmentRepository_RejectsBookingsOutsideShifts(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	business := &domain.Business{UserID: "owner-1", Name: "Salon"}
+	require.NoError(t, Insert(db, business))
+	repo := NewAppointmentRepository(db)
+
+	// 2026-03-02 is a Monday
+	monday := int(time.Monday)
+	require.NoError(t, Insert(db, &domain.StaffShift{
+		BusinessID: business.ID,
+		StaffID:    "staff-1",
+		Kind:       domain.StaffShiftRecurring,
+		Weekday:    &monday,
+		StartTime:  "13:00",
+		EndTime:    "17:00",
+	}))
+	start := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	appointment := &domain.Appointment{
+		BusinessID: business.ID,
+		StaffID:    "staff-1",
+		ClientID:   "client-1",
+		StartTime:  start,
+		EndTime:    start.Add(time.Hour),
+		Status:     domain.AppointmentStatusScheduled,
+	}
+	var conflict *domain.AppointmentConflictError
+	require.ErrorAs(t, repo.Create(ctx, appointment), &conflict)
+	assert.Equal(t, domain.ConflictOutsideShift, conflict.Conflicts[0].Reason)
+
+	appointment.StaffID = "staff-2"
+	assert.NoError(t, repo.Create(ctx, appointment), "staff members not on a rota work whenever the business is open")
+
+	appointment.ID = ""
+	appointment.StaffID = "staff-1"
+	appointment.StartTime = start.Add(4 * time.Hour)
+	appointment.EndTime = start.Add(5 * time.Hour)
+	assert.NoError(t, repo.Create(ctx, appointment))
+
+	schedules, err := repo.FindSchedules(ctx, []string{"staff-1"}, start, start.Add(24*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, schedules, 1)
+	assert.Len(t, schedules[0].Shifts, 1)
+}
+
+func TestAppointmentRepository_UpdateRejectsStaleVersions(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	business := &domain.Business{UserID: "owner-1", Name: "Salon"}
+	require.NoError(t, Insert(db, business))
+	repo := NewAppointmentRepository(db)
+
+	start := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	appointment := &domain.Appointment{
+		BusinessID: business.ID,
+		StaffID:    "staff-1",
+		ClientID:   "client-1",
+		StartTime:  start,
+		EndTime:    start.Add(time.Hour),
+		Status:     domain.AppointmentStatusScheduled,
+	}
+	require.NoError(t, repo.Create(ctx, appointment))
+
+	// Two writers read the same version and update it at once
+	writers := make([]*domain.Appointment, 2)
+	for i := range writers {
+		read, err := repo.GetByID(ctx, appointment.ID)
+		require.NoError(t, err)
+		writers[i] = read
+	}
+	errs := make([]error, len(writers))
+	var wg sync.WaitGroup
+	for i, writer := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			notes := fmt.Sprintf("writer %d", i)
+			writer.Notes = &notes
+			errs[i] = repo.Update(ctx, writer)
+		}()
+	}
+	wg.Wait()
+
+	var conflict *domain.ConflictError
+	var failed int
+	for _, err := range errs {
+		if err != nil {
+			require.ErrorAs(t, err, &conflict)
+			failed++
+		}
+	}
+	assert.Equal(t, 1, failed, "only one writer updates the version both read")
+
+	stored, err := repo.GetByID(ctx, appointment.ID)
+	require.NoError(t, err)
+	assert.Equal(t, appointment.Version+1, stored.Version)
+}
+
+func TestAppointmentRepository_ScopedToTenant(t *testing.T) {
+	db := NewDB()
+	business := &domain.Business{UserID: "owner-1", Name: "Salon"}
+	require.NoError(t, Insert(db, business))
+	other := &domain.Business{UserID: "owner-2", Name: "Spa"}
+	require.NoError(t, Insert(db, other))
+	repo := NewAppointmentRepository(db)
+	ctx := domain.WithTenant(context.Background(), domain.TenantContext{BusinessID: business.ID})
+	otherCtx := domain.WithTenant(context.Background(), domain.TenantContext{BusinessID: other.ID})
+
+	start := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	appointment := &domain.Appointment{
+		StaffID:   "staff-1",
+		ClientID:  "client-1",
+		StartTime: start,
+		EndTime:   start.Add(time.Hour),
+		Status:    domain.AppointmentStatusScheduled,
+	}
+	require.NoError(t, repo.Create(ctx, appointment))
+	assert.Equal(t, business.ID, appointment.BusinessID, "new appointments are the tenant's")
+
+	_, err := repo.GetByID(otherCtx, appointment.ID)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound, "other tenants do not see the appointment")
+	assert.ErrorIs(t, repo.Update(otherCtx, appointment), domain.ErrCrossTenant)
+
+	foreign := *appointment
+	foreign.ID = ""
+	foreign.StaffID = "staff-2"
+	assert.ErrorIs(t, repo.CreateWithLines(otherCtx, &foreign, nil), domain.ErrCrossTenant)
+}
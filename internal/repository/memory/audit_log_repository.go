@@ -0,0 +1,43 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// auditLogRepositoryImpl implements the AuditLogRepository interface
+type auditLogRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.AuditLog]
+}
+
+// NewAuditLogRepository creates a new audit log repository
+func NewAuditLogRepository(db *DB) domain.AuditLogRepository {
+	return &auditLogRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.AuditLog]{db: db},
+	}
+}
+
+// Search finds the entries matching the filter, most recent first
+func (r *auditLogRepositoryImpl) Search(ctx context.Context, filter domain.AuditLogFilter, page, pageSize int) ([]*domain.AuditLog, int64, error) {
+	defer r.db.lock()()
+	entries := r.table().where(func(l *domain.AuditLog) bool {
+		switch {
+		case l.BusinessID == nil || *l.BusinessID != filter.BusinessID,
+			filter.EntityType != nil && l.EntityType != *filter.EntityType,
+			filter.EntityID != nil && l.EntityID != *filter.EntityID,
+			filter.ActorID != nil && (l.ActorID == nil || *l.ActorID != *filter.ActorID),
+			filter.Action != nil && l.Action != *filter.Action,
+			filter.StartDate != nil && l.OccurredAt.Before(*filter.StartDate),
+			filter.EndDate != nil && !l.OccurredAt.Before(*filter.EndDate):
+			return false
+		}
+		return true
+	})
+	slices.SortFunc(entries, func(a, b *domain.AuditLog) int {
+		return cmp.Or(b.OccurredAt.Compare(a.OccurredAt), cmp.Compare(b.ID, a.ID))
+	})
+	return paginate(entries, page, pageSize), int64(len(entries)), nil
+}
@@ -0,0 +1,293 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// BaseRepositoryImpl provides a base implementation for in-memory repositories
+type BaseRepositoryImpl[T any] struct {
+	db           *DB
+	tenantScoped bool
+}
+
+// BaseRepositoryOption configures optional behaviour of a base repository
+type BaseRepositoryOption func(*baseRepositoryConfig)
+
+type baseRepositoryConfig struct {
+	tenantScoped bool
+}
+
+// WithTenantScope scopes the repository to the tenant carried in the context, like its GORM
+// counterpart. The entity must have a business_id column.
+func WithTenantScope() BaseRepositoryOption {
+	return func(c *baseRepositoryConfig) {
+		c.tenantScoped = true
+	}
+}
+
+// NewBaseRepository creates a new base repository
+func NewBaseRepository[T any](db *DB, opts ...BaseRepositoryOption) domain.BaseRepository[T] {
+	config := &baseRepositoryConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+	if config.tenantScoped {
+		defer db.lock()()
+		if _, ok := tableOf[T](db).meta.columns["business_id"]; !ok {
+			panic(fmt.Sprintf("memory: %s has no BusinessID to scope by tenant", reflect.TypeFor[T]().Name()))
+		}
+	}
+	return &BaseRepositoryImpl[T]{db: db, tenantScoped: config.tenantScoped}
+}
+
+// table returns the repository's table. The caller must hold the lock.
+func (r *BaseRepositoryImpl[T]) table() *table[T] {
+	return tableOf[T](r.db)
+}
+
+// Create creates a new entity
+func (r *BaseRepositoryImpl[T]) Create(ctx context.Context, entity *T) error {
+	defer r.db.lock()()
+	if err := r.claim(ctx, entity); err != nil {
+		return err
+	}
+	return r.table().insert(entity)
+}
+
+// GetByID retrieves an entity by ID
+func (r *BaseRepositoryImpl[T]) GetByID(ctx context.Context, id string) (*T, error) {
+	defer r.db.lock()()
+	return r.get(ctx, id)
+}
+
+// GetByIDs retrieves the entities with the given IDs, skipping missing IDs
+func (r *BaseRepositoryImpl[T]) GetByIDs(ctx context.Context, ids []string) ([]*T, error) {
+	defer r.db.lock()()
+	entities := []*T{}
+	seen := map[string]bool{}
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if entity, err := r.get(ctx, id); err == nil {
+			entities = append(entities, entity)
+		}
+	}
+	return entities, nil
+}
+
+// Update updates an existing entity, failing with a domain.ConflictError when it was changed
+// since the version it carries was read
+func (r *BaseRepositoryImpl[T]) Update(ctx context.Context, entity *T) error {
+	defer r.db.lock()()
+	version, err := r.checkUpdate(ctx, entity)
+	if err != nil {
+		return err
+	}
+	return r.saveVersion(entity, version)
+}
+
+// checkUpdate checks that an entity can be updated, returning the version it is saved with, or
+// 0 when it has none. The caller must hold the lock.
+func (r *BaseRepositoryImpl[T]) checkUpdate(ctx context.Context, entity *T) (int, error) {
+	if err := r.claim(ctx, entity); err != nil {
+		return 0, err
+	}
+	t := r.table()
+	v := reflect.ValueOf(entity).Elem()
+	id := t.meta.id(v)
+	if err := r.ensureOwned(ctx, id); err != nil {
+		return 0, err
+	}
+	stored, err := t.get(id)
+	if err != nil || t.meta.versionField == nil {
+		return 0, nil
+	}
+	current, expected := t.meta.version(reflect.ValueOf(stored).Elem()), t.meta.version(v)
+	if expected != 0 && expected != current {
+		return 0, &domain.ConflictError{EntityType: t.meta.table, ID: id, Version: expected}
+	}
+	return current + 1, nil
+}
+
+// saveVersion saves an entity checked by checkUpdate. The caller must hold the lock.
+func (r *BaseRepositoryImpl[T]) saveVersion(entity *T, version int) error {
+	t := r.table()
+	if version > 0 {
+		t.meta.setVersion(reflect.ValueOf(entity).Elem(), version)
+	}
+	return t.save(entity)
+}
+
+// Delete soft deletes an entity by ID
+func (r *BaseRepositoryImpl[T]) Delete(ctx context.Context, id string) error {
+	defer r.db.lock()()
+	if err := r.ensureOwned(ctx, id); err != nil {
+		return err
+	}
+	r.table().delete(id)
+	return nil
+}
+
+// List retrieves entities with pagination, in creation order
+func (r *BaseRepositoryImpl[T]) List(ctx context.Context, page, pageSize int) ([]*T, int64, error) {
+	defer r.db.lock()()
+	entities := r.table().where(r.scope(ctx))
+	return paginate(entities, page, pageSize), int64(len(entities)), nil
+}
+
+// ListAfter retrieves up to limit entities following the cursor, ordered by creation time and
+// ID. The boolean reports whether more entities follow.
+func (r *BaseRepositoryImpl[T]) ListAfter(ctx context.Context, cursor *domain.Cursor, limit int) ([]*T, bool, error) {
+	defer r.db.lock()()
+	t := r.table()
+	inScope := r.scope(ctx)
+	entities := t.where(func(row *T) bool {
+		if inScope != nil && !inScope(row) {
+			return false
+		}
+		if cursor == nil {
+			return true
+		}
+		v := reflect.ValueOf(row).Elem()
+		createdAt := t.meta.createdAt(v)
+		return createdAt.After(cursor.CreatedAt) || (createdAt.Equal(cursor.CreatedAt) && t.meta.id(v) > cursor.ID)
+	})
+
+	hasNext := len(entities) > limit
+	if hasNext {
+		entities = entities[:limit]
+	}
+	return entities, hasNext, nil
+}
+
+// FindBy finds entities whose columns equal the criteria
+func (r *BaseRepositoryImpl[T]) FindBy(ctx context.Context, criteria map[string]any) ([]*T, error) {
+	defer r.db.lock()()
+	t := r.table()
+	inScope := r.scope(ctx)
+	return t.where(func(row *T) bool {
+		return (inScope == nil || inScope(row)) && t.meta.matches(reflect.ValueOf(row).Elem(), criteria)
+	}), nil
+}
+
+// ExistsByID checks if an entity exists by ID
+func (r *BaseRepositoryImpl[T]) ExistsByID(ctx context.Context, id string) (bool, error) {
+	defer r.db.lock()()
+	_, err := r.get(ctx, id)
+	return err == nil, nil
+}
+
+// ListDeleted lists the soft-deleted entities matching the criteria that were deleted at or
+// after since, most recently deleted first
+func (r *BaseRepositoryImpl[T]) ListDeleted(ctx context.Context, criteria map[string]any, since time.Time, page, pageSize int) ([]*T, int64, error) {
+	defer r.db.lock()()
+	t := r.table()
+	inScope := r.scope(ctx)
+	entities := t.trash(func(row *T) bool {
+		v := reflect.ValueOf(row).Elem()
+		return (inScope == nil || inScope(row)) && !t.meta.deletedAt(v).Before(since) && t.meta.matches(v, criteria)
+	})
+	return paginate(entities, page, pageSize), int64(len(entities)), nil
+}
+
+// Restore undoes the soft deletion of an entity
+func (r *BaseRepositoryImpl[T]) Restore(ctx context.Context, id string) error {
+	defer r.db.lock()()
+	t := r.table()
+	inScope := r.scope(ctx)
+	if row, ok := t.rows[id]; !ok || (inScope != nil && !inScope(row)) || !t.restore(id) {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// WithTx returns the repository itself: every call is already atomic, and there is no
+// transaction to roll back
+func (r *BaseRepositoryImpl[T]) WithTx(tx *gorm.DB) domain.BaseRepository[T] {
+	return r
+}
+
+// GetDB returns nil, as there is no database connection
+func (r *BaseRepositoryImpl[T]) GetDB() *gorm.DB {
+	return nil
+}
+
+// get returns a stored entity, treating rows of other tenants as missing
+func (r *BaseRepositoryImpl[T]) get(ctx context.Context, id string) (*T, error) {
+	entity, err := r.table().get(id)
+	if err != nil {
+		return nil, err
+	}
+	if inScope := r.scope(ctx); inScope != nil && !inScope(entity) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return entity, nil
+}
+
+// scope returns the filter of the tenant's rows, or nil when the call is not scoped
+func (r *BaseRepositoryImpl[T]) scope(ctx context.Context) func(*T) bool {
+	tenant, ok := r.tenant(ctx)
+	if !ok {
+		return nil
+	}
+	return func(row *T) bool { return tenant.Owns(r.businessID(row).String()) }
+}
+
+// tenant returns the tenant the call is scoped to
+func (r *BaseRepositoryImpl[T]) tenant(ctx context.Context) (domain.TenantContext, bool) {
+	if !r.tenantScoped {
+		return domain.TenantContext{}, false
+	}
+	return domain.TenantFromContext(ctx)
+}
+
+// businessID returns the BusinessID field of an entity
+func (r *BaseRepositoryImpl[T]) businessID(entity *T) reflect.Value {
+	return reflect.ValueOf(entity).Elem().FieldByIndex(r.table().meta.columns["business_id"])
+}
+
+// claim checks that an entity about to be written belongs to the tenant, assigning new
+// entities without a business to it
+func (r *BaseRepositoryImpl[T]) claim(ctx context.Context, entity *T) error {
+	tenant, ok := r.tenant(ctx)
+	if !ok {
+		return nil
+	}
+	businessID := r.businessID(entity)
+	if businessID.String() == "" {
+		businessID.SetString(tenant.BusinessID)
+	}
+	if !tenant.Owns(businessID.String()) {
+		return domain.ErrCrossTenant
+	}
+	return nil
+}
+
+// ensureOwned checks that a stored row, if there is one, belongs to the tenant
+func (r *BaseRepositoryImpl[T]) ensureOwned(ctx context.Context, id string) error {
+	inScope := r.scope(ctx)
+	if inScope == nil {
+		return nil
+	}
+	if row, err := r.table().get(id); err == nil && !inScope(row) {
+		return domain.ErrCrossTenant
+	}
+	return nil
+}
+
+// paginate returns a page of entities, numbering pages from 1
+func paginate[T any](entities []*T, page, pageSize int) []*T {
+	offset := max((page-1)*pageSize, 0)
+	if offset >= len(entities) {
+		return []*T{}
+	}
+	return entities[offset:min(offset+pageSize, len(entities))]
+}
@@ -0,0 +1,161 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestBaseRepository_CRUD(t *testing.T) {
+	ctx := context.Background()
+	repo := NewBaseRepository[domain.ServiceCategory](NewDB())
+
+	category := &domain.ServiceCategory{BusinessID: "business-1", Name: "Hair"}
+	require.NoError(t, repo.Create(ctx, category))
+	require.NotEmpty(t, category.ID)
+	assert.False(t, category.CreatedAt.IsZero())
+
+	found, err := repo.GetByID(ctx, category.ID)
+	require.NoError(t, err)
+	found.Name = "Changed without saving"
+	stored, err := repo.GetByID(ctx, category.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Hair", stored.Name, "rows are copied out of the table")
+
+	stored.Name = "Nails"
+	require.NoError(t, repo.Update(ctx, stored))
+	stored, err = repo.GetByID(ctx, category.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Nails", stored.Name)
+
+	require.NoError(t, repo.Delete(ctx, category.ID))
+	_, err = repo.GetByID(ctx, category.ID)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	exists, err := repo.ExistsByID(ctx, category.ID)
+	require.NoError(t, err)
+	assert.False(t, exists)
+	assert.NoError(t, repo.Delete(ctx, "missing"))
+}
+
+func TestBaseRepository_ListAndFindBy(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	clock := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	db.SetClock(func() time.Time {
+		clock = clock.Add(time.Minute)
+		return clock
+	})
+	repo := NewBaseRepository[domain.ServiceCategory](db)
+
+	for _, name := range []string{"A", "B", "C", "D", "E"} {
+		require.NoError(t, repo.Create(ctx, &domain.ServiceCategory{BusinessID: "business-1", Name: name, IsActive: name != "C"}))
+	}
+	categories, err := repo.FindBy(ctx, map[string]any{"name": "B"})
+	require.NoError(t, err)
+	require.Len(t, categories, 1)
+	require.NoError(t, repo.Delete(ctx, categories[0].ID))
+
+	page, total, err := repo.List(ctx, 2, 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), total, "soft-deleted rows are not listed")
+	require.Len(t, page, 2)
+	assert.Equal(t, "D", page[0].Name)
+	assert.Equal(t, "E", page[1].Name)
+
+	page, _, err = repo.List(ctx, 3, 2)
+	require.NoError(t, err)
+	assert.Empty(t, page)
+
+	inactive, err := repo.FindBy(ctx, map[string]any{"business_id": "business-1", "is_active": false})
+	require.NoError(t, err)
+	require.Len(t, inactive, 1)
+	assert.Equal(t, "C", inactive[0].Name)
+
+	first, hasNext, err := repo.ListAfter(ctx, nil, 3)
+	require.NoError(t, err)
+	assert.True(t, hasNext)
+	require.Len(t, first, 3)
+	rest, hasNext, err := repo.ListAfter(ctx, &domain.Cursor{CreatedAt: first[2].CreatedAt, ID: first[2].ID}, 3)
+	require.NoError(t, err)
+	assert.False(t, hasNext)
+	require.Len(t, rest, 1)
+	assert.Equal(t, "E", rest[0].Name)
+}
+
+func TestBaseRepository_TenantScope(t *testing.T) {
+	db := NewDB()
+	repo := NewBaseRepository[domain.ServiceCategory](db, WithTenantScope())
+	salon := domain.WithTenant(context.Background(), domain.TenantContext{BusinessID: "business-1"})
+	other := domain.WithTenant(context.Background(), domain.TenantContext{BusinessID: "business-2"})
+
+	own := &domain.ServiceCategory{Name: "Hair"}
+	require.NoError(t, repo.Create(salon, own))
+	assert.Equal(t, "business-1", own.BusinessID, "new entities are assigned to the tenant")
+	foreign := &domain.ServiceCategory{BusinessID: "business-2", Name: "Nails"}
+	assert.ErrorIs(t, repo.Create(salon, foreign), domain.ErrCrossTenant)
+	require.NoError(t, repo.Create(other, foreign))
+
+	_, err := repo.GetByID(salon, foreign.ID)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	categories, total, err := repo.List(salon, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, own.ID, categories[0].ID)
+	categories, err = repo.FindBy(salon, map[string]any{"name": "Nails"})
+	require.NoError(t, err)
+	assert.Empty(t, categories)
+
+	hijacked := *foreign
+	hijacked.BusinessID = "business-1"
+	assert.ErrorIs(t, repo.Update(salon, &hijacked), domain.ErrCrossTenant)
+	assert.ErrorIs(t, repo.Delete(salon, foreign.ID), domain.ErrCrossTenant)
+
+	categories, total, err = repo.List(context.Background(), 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total, "calls without a tenant are not scoped")
+	assert.Equal(t, "business-2", categories[1].BusinessID)
+}
+
+func TestBaseRepository_CreateBatchConflicts(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	repo := NewBaseRepository[domain.CampaignClient](db)
+	target := func(clientID string, status domain.CampaignClientStatus) *domain.CampaignClient {
+		return &domain.CampaignClient{CampaignID: "campaign-1", ClientID: clientID, Status: status}
+	}
+	byClient := domain.BatchOptions{ConflictColumns: []string{"campaign_id", "client_id"}}
+
+	created, err := repo.CreateBatch(ctx, []*domain.CampaignClient{target("client-1", domain.CampaignClientPending)}, byClient)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, created)
+
+	_, err = repo.CreateBatch(ctx, []*domain.CampaignClient{
+		target("client-2", domain.CampaignClientPending), target("client-1", domain.CampaignClientSent),
+	}, byClient)
+	assert.ErrorIs(t, err, gorm.ErrDuplicatedKey)
+	assert.Len(t, All[domain.CampaignClient](db), 1, "a failing batch writes nothing")
+
+	skip := byClient
+	skip.OnConflict = domain.ConflictSkip
+	created, err = repo.CreateBatch(ctx, []*domain.CampaignClient{
+		target("client-2", domain.CampaignClientPending), target("client-1", domain.CampaignClientSent), target("client-2", domain.CampaignClientSent),
+	}, skip)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, created, "conflicting rows, stored or earlier in the batch, are skipped")
+
+	update := byClient
+	update.OnConflict = domain.ConflictUpdate
+	update.UpdateColumns = []string{"status"}
+	created, err = repo.CreateBatch(ctx, []*domain.CampaignClient{target("client-1", domain.CampaignClientSent)}, update)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, created)
+	stored, err := repo.FindBy(ctx, map[string]any{"client_id": "client-1"})
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	assert.Equal(t, domain.CampaignClientSent, stored[0].Status, "the conflicting row is overwritten")
+}
@@ -0,0 +1,139 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// CreateBatch inserts the entities all at once, so that a failure leaves none of them behind.
+// Conflicts are found on the conflict columns of every stored row, soft-deleted ones included,
+// as a unique index would find them. Entities are stored whole, whatever the columns to write.
+func (r *BaseRepositoryImpl[T]) CreateBatch(ctx context.Context, entities []*T, opts domain.BatchOptions) (int64, error) {
+	defer r.db.lock()()
+	for _, entity := range entities {
+		if err := r.claim(ctx, entity); err != nil {
+			return 0, err
+		}
+	}
+	t := r.table()
+	columns := opts.ConflictColumns
+	if len(columns) == 0 {
+		columns = []string{"id"}
+	}
+
+	// Conflicts are found before anything is written, against the stored rows and the entities
+	// ahead in the batch
+	keys := map[string]string{}
+	for id, row := range t.rows {
+		if key, ok := t.meta.conflictKey(reflect.ValueOf(row).Elem(), columns); ok {
+			keys[key] = id
+		}
+	}
+	conflicts := make([]string, len(entities))
+	for i, entity := range entities {
+		key, ok := t.meta.conflictKey(reflect.ValueOf(entity).Elem(), columns)
+		if !ok {
+			continue
+		}
+		id, exists := keys[key]
+		switch {
+		case !exists:
+			keys[key] = ""
+		case opts.OnConflict == domain.ConflictFail, opts.OnConflict == domain.ConflictUpdate && id == "":
+			// Like Postgres, an insert cannot overwrite a row it inserted itself
+			return 0, gorm.ErrDuplicatedKey
+		default:
+			conflicts[i] = cmp.Or(id, "batch")
+		}
+	}
+
+	var written int64
+	var inserted []string
+	for i, entity := range entities {
+		switch {
+		case conflicts[i] == "":
+			if err := t.insert(entity); err != nil {
+				for _, id := range inserted {
+					delete(t.rows, id)
+				}
+				return 0, err
+			}
+			inserted = append(inserted, t.meta.id(reflect.ValueOf(entity).Elem()))
+		case opts.OnConflict == domain.ConflictSkip:
+			continue
+		default:
+			t.meta.overwrite(reflect.ValueOf(t.rows[conflicts[i]]).Elem(), reflect.ValueOf(entity).Elem(), opts.UpdateColumns)
+			t.meta.touch(reflect.ValueOf(t.rows[conflicts[i]]).Elem(), t.db.now(), false)
+		}
+		written++
+	}
+	return written, nil
+}
+
+// UpdateBatch updates the entities all at once. Every entity is checked before any is written,
+// so that a conflict leaves all of them unchanged.
+func (r *BaseRepositoryImpl[T]) UpdateBatch(ctx context.Context, entities []*T) error {
+	defer r.db.lock()()
+	versions := make([]int, len(entities))
+	for i, entity := range entities {
+		version, err := r.checkUpdate(ctx, entity)
+		if err != nil {
+			return err
+		}
+		versions[i] = version
+	}
+	for i, entity := range entities {
+		if err := r.saveVersion(entity, versions[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// conflictKey returns the values of the columns a unique index covers, or false when one is
+// null or an empty ID, which conflicts with nothing
+func (m *entityMeta) conflictKey(v reflect.Value, columns []string) (string, bool) {
+	values := make([]string, len(columns))
+	for i, column := range columns {
+		index, ok := m.columns[column]
+		if !ok {
+			panic(fmt.Sprintf("memory: %s has no column %s", m.table, column))
+		}
+		field := v.FieldByIndex(index)
+		for field.Kind() == reflect.Pointer {
+			if field.IsNil() {
+				return "", false
+			}
+			field = field.Elem()
+		}
+		if column == "id" && field.String() == "" {
+			return "", false
+		}
+		values[i] = fmt.Sprint(field.Interface())
+	}
+	return strings.Join(values, "\x00"), true
+}
+
+// overwrite copies the columns of src over those of dst, or every column but the ID and the
+// creation fields when none are given, as an upsert does
+func (m *entityMeta) overwrite(dst, src reflect.Value, columns []string) {
+	if len(columns) == 0 {
+		for column := range m.columns {
+			if !slices.Contains([]string{"id", "created_at", "created_by"}, column) {
+				columns = append(columns, column)
+			}
+		}
+	}
+	for _, column := range columns {
+		if index, ok := m.columns[column]; ok {
+			dst.FieldByIndex(index).Set(src.FieldByIndex(index))
+		}
+	}
+}
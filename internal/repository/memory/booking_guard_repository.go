@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// blocklistRepositoryImpl implements the BlocklistRepository interface
+type blocklistRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.BlocklistEntry]
+}
+
+// NewBlocklistRepository creates a new booking blocklist repository, scoped to the tenant in the
+// context
+func NewBlocklistRepository(db *DB) domain.BlocklistRepository {
+	return &blocklistRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.BlocklistEntry]{db: db, tenantScoped: true},
+	}
+}
+
+// FindByBusinessID finds all blocklist entries of a business, including expired ones
+func (r *blocklistRepositoryImpl) FindByBusinessID(ctx context.Context, businessID string) ([]*domain.BlocklistEntry, error) {
+	defer r.db.lock()()
+	entries := r.table().where(func(e *domain.BlocklistEntry) bool { return e.BusinessID == businessID })
+	slices.SortStableFunc(entries, func(a, b *domain.BlocklistEntry) int {
+		return cmp.Or(strings.Compare(string(a.EntryType), string(b.EntryType)), strings.Compare(a.Value, b.Value))
+	})
+	return entries, nil
+}
+
+// FindMatch finds an active blocklist entry of a business matching any of the keys
+func (r *blocklistRepositoryImpl) FindMatch(ctx context.Context, businessID string, keys []domain.BlocklistKey, at time.Time) (*domain.BlocklistEntry, error) {
+	defer r.db.lock()()
+	if len(keys) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return r.table().first(func(e *domain.BlocklistEntry) bool {
+		if e.BusinessID != businessID || (e.ExpiresAt != nil && !e.ExpiresAt.After(at)) {
+			return false
+		}
+		return slices.Contains(keys, domain.BlocklistKey{EntryType: e.EntryType, Value: e.Value})
+	})
+}
+
+// bookingAttemptRepositoryImpl implements the BookingAttemptRepository interface
+type bookingAttemptRepositoryImpl struct {
+	db *DB
+}
+
+// NewBookingAttemptRepository creates a new booking attempt repository
+func NewBookingAttemptRepository(db *DB) domain.BookingAttemptRepository {
+	return &bookingAttemptRepositoryImpl{db: db}
+}
+
+// Record stores a booking attempt
+func (r *bookingAttemptRepositoryImpl) Record(ctx context.Context, attempt *domain.BookingAttemptRecord) error {
+	defer r.db.lock()()
+	return tableOf[domain.BookingAttemptRecord](r.db).insert(attempt)
+}
+
+// CountByIP counts the booking attempts made from an IP address since a point in time
+func (r *bookingAttemptRepositoryImpl) CountByIP(ctx context.Context, ipAddress string, since time.Time) (int64, error) {
+	defer r.db.lock()()
+	return tableOf[domain.BookingAttemptRecord](r.db).count(func(a *domain.BookingAttemptRecord) bool {
+		return a.IPAddress == ipAddress && !a.CreatedAt.Before(since)
+	}), nil
+}
+
+// DeleteBefore removes booking attempts older than a point in time
+func (r *bookingAttemptRepositoryImpl) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	defer r.db.lock()()
+	return tableOf[domain.BookingAttemptRecord](r.db).deleteWhere(func(a *domain.BookingAttemptRecord) bool {
+		return a.CreatedAt.Before(before)
+	}), nil
+}
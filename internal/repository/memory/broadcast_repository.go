@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// broadcastRepositoryImpl implements the BroadcastRepository interface
+type broadcastRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.Broadcast]
+}
+
+// NewBroadcastRepository creates a new broadcast repository, scoped to the tenant in the context
+func NewBroadcastRepository(db *DB) domain.BroadcastRepository {
+	return &broadcastRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.Broadcast]{db: db, tenantScoped: true},
+	}
+}
+
+// FindByBusinessID finds the broadcasts of a business, latest first
+func (r *broadcastRepositoryImpl) FindByBusinessID(ctx context.Context, businessID string) ([]*domain.Broadcast, error) {
+	defer r.db.lock()()
+	broadcasts := r.table().where(func(b *domain.Broadcast) bool { return b.BusinessID == businessID })
+	slices.Reverse(broadcasts)
+	return broadcasts, nil
+}
+
+// FindTargets finds the business's scheduled and confirmed appointments overlapping a window
+func (r *broadcastRepositoryImpl) FindTargets(ctx context.Context, businessID string, start, end time.Time) ([]*domain.BroadcastTarget, error) {
+	defer r.db.lock()()
+	appointments := tableOf[domain.Appointment](r.db).where(func(a *domain.Appointment) bool {
+		return a.BusinessID == businessID &&
+			(a.Status == domain.AppointmentStatusScheduled || a.Status == domain.AppointmentStatusConfirmed) &&
+			a.StartTime.Before(end) && a.EndTime.After(start)
+	})
+	sortByStart(appointments)
+
+	targets := []*domain.BroadcastTarget{}
+	for _, a := range appointments {
+		client, ok := clientContact(r.db, a.ClientID)
+		if !ok {
+			continue
+		}
+		targets = append(targets, &domain.BroadcastTarget{
+			AppointmentID:   a.ID,
+			ClientID:        a.ClientID,
+			StartTime:       a.StartTime,
+			EndTime:         a.EndTime,
+			ClientFirstName: client.FirstName,
+			ClientLastName:  client.LastName,
+			ClientPhone:     client.Phone,
+			ClientEmail:     stringPtr(client.Email),
+		})
+	}
+	return targets, nil
+}
+
+// AddRecipients records the recipients of a broadcast and marks their appointments as affected
+func (r *broadcastRepositoryImpl) AddRecipients(ctx context.Context, broadcast *domain.Broadcast, recipients []*domain.BroadcastRecipient) error {
+	defer r.db.lock()()
+	for _, recipient := range recipients {
+		if err := tableOf[domain.BroadcastRecipient](r.db).insert(recipient); err != nil {
+			return err
+		}
+	}
+
+	now := r.db.now()
+	for _, recipient := range recipients {
+		tableOf[domain.Appointment](r.db).update(recipient.AppointmentID, func(a *domain.Appointment) {
+			a.BroadcastID = &broadcast.ID
+			a.UpdatedAt = now
+		})
+	}
+	return nil
+}
+
+// UpdateRecipient saves the delivery or rescheduling outcome of a recipient
+func (r *broadcastRepositoryImpl) UpdateRecipient(ctx context.Context, recipient *domain.BroadcastRecipient) error {
+	defer r.db.lock()()
+	return tableOf[domain.BroadcastRecipient](r.db).save(recipient)
+}
+
+// FindRecipients finds the recipients of a broadcast
+func (r *broadcastRepositoryImpl) FindRecipients(ctx context.Context, broadcastID string) ([]*domain.BroadcastRecipient, error) {
+	defer r.db.lock()()
+	return tableOf[domain.BroadcastRecipient](r.db).where(func(recipient *domain.BroadcastRecipient) bool {
+		return recipient.BroadcastID == broadcastID
+	}), nil
+}
+
+// FindRecipientByToken finds the recipient a rescheduling link was sent to
+func (r *broadcastRepositoryImpl) FindRecipientByToken(ctx context.Context, token string) (*domain.BroadcastRecipient, error) {
+	defer r.db.lock()()
+	return tableOf[domain.BroadcastRecipient](r.db).first(func(recipient *domain.BroadcastRecipient) bool {
+		return recipient.RescheduleToken != nil && *recipient.RescheduleToken == token
+	})
+}
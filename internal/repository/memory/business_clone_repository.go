@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// businessCloneRepositoryImpl implements the BusinessCloneRepository interface
+type businessCloneRepositoryImpl struct {
+	db *DB
+}
+
+// NewBusinessCloneRepository creates a new business clone repository
+func NewBusinessCloneRepository(db *DB) domain.BusinessCloneRepository {
+	return &businessCloneRepositoryImpl{db: db}
+}
+
+// LoadTemplate loads the configuration of a business, in display order where it has one
+func (r *businessCloneRepositoryImpl) LoadTemplate(ctx context.Context, businessID string) (*domain.BusinessTemplate, error) {
+	defer r.db.lock()()
+	business, err := tableOf[domain.Business](r.db).get(businessID)
+	if err != nil {
+		return nil, err
+	}
+	template := &domain.BusinessTemplate{Business: business}
+	if settings, err := tableOf[domain.BusinessSettings](r.db).first(func(s *domain.BusinessSettings) bool {
+		return s.BusinessID == businessID
+	}); err == nil {
+		template.Settings = settings
+	}
+
+	template.Categories = tableOf[domain.ServiceCategory](r.db).where(func(c *domain.ServiceCategory) bool { return c.BusinessID == businessID })
+	slices.SortStableFunc(template.Categories, func(a, b *domain.ServiceCategory) int {
+		return cmp.Or(cmp.Compare(a.DisplayOrder, b.DisplayOrder), cmp.Compare(a.Name, b.Name))
+	})
+	template.Services = tableOf[domain.Service](r.db).where(func(s *domain.Service) bool { return s.BusinessID == businessID })
+	slices.SortStableFunc(template.Services, func(a, b *domain.Service) int {
+		return cmp.Or(cmp.Compare(a.DisplayOrder, b.DisplayOrder), cmp.Compare(a.Name, b.Name))
+	})
+	template.Bundles = tableOf[domain.ServiceBundle](r.db).where(func(b *domain.ServiceBundle) bool { return b.BusinessID == businessID })
+	slices.SortStableFunc(template.Bundles, func(a, b *domain.ServiceBundle) int { return cmp.Compare(a.Name, b.Name) })
+	template.MessageTemplates = tableOf[domain.MessageTemplate](r.db).where(func(t *domain.MessageTemplate) bool { return t.BusinessID == businessID })
+	slices.SortStableFunc(template.MessageTemplates, func(a, b *domain.MessageTemplate) int { return cmp.Compare(a.Name, b.Name) })
+	template.LoyaltyPrograms = tableOf[domain.LoyaltyProgram](r.db).where(func(p *domain.LoyaltyProgram) bool { return p.BusinessID == businessID })
+	template.Staff = tableOf[domain.Staff](r.db).where(func(s *domain.Staff) bool { return s.BusinessID == businessID && s.IsActive })
+	return template, nil
+}
+
+// Create stores the business and its copies all at once, so that a failure leaves none of
+// them behind
+func (r *businessCloneRepositoryImpl) Create(ctx context.Context, clone *domain.BusinessClone) error {
+	defer r.db.lock()()
+	var undos []func()
+	undoAll := func() {
+		for i := len(undos) - 1; i >= 0; i-- {
+			undos[i]()
+		}
+	}
+	settings := []*domain.BusinessSettings{}
+	if clone.Settings != nil {
+		settings = append(settings, clone.Settings)
+	}
+	inserts := []func() (func(), error){
+		func() (func(), error) {
+			return insertAll(tableOf[domain.Business](r.db), []*domain.Business{clone.Business})
+		},
+		func() (func(), error) { return insertAll(tableOf[domain.BusinessSettings](r.db), settings) },
+		func() (func(), error) { return insertAll(tableOf[domain.ServiceCategory](r.db), clone.Categories) },
+		func() (func(), error) { return insertAll(tableOf[domain.Service](r.db), clone.Services) },
+		func() (func(), error) { return insertAll(tableOf[domain.ServiceBundle](r.db), clone.Bundles) },
+		func() (func(), error) {
+			return insertAll(tableOf[domain.MessageTemplate](r.db), clone.MessageTemplates)
+		},
+		func() (func(), error) { return insertAll(tableOf[domain.LoyaltyProgram](r.db), clone.LoyaltyPrograms) },
+		func() (func(), error) { return insertAll(tableOf[domain.Staff](r.db), clone.Staff) },
+		func() (func(), error) { return insertAll(tableOf[domain.BusinessHours](r.db), clone.Hours) },
+	}
+	for _, insert := range inserts {
+		undo, err := insert()
+		if err != nil {
+			undoAll()
+			return err
+		}
+		undos = append(undos, undo)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// businessHoursRepositoryImpl implements the BusinessHoursRepository interface
+type businessHoursRepositoryImpl struct {
+	db *DB
+}
+
+// NewBusinessHoursRepository creates a new business hours repository
+func NewBusinessHoursRepository(db *DB) domain.BusinessHoursRepository {
+	return &businessHoursRepositoryImpl{db: db}
+}
+
+// FindByBusiness finds the hours of a business and of all its locations, the business's first,
+// then by location and weekday
+func (r *businessHoursRepositoryImpl) FindByBusiness(ctx context.Context, businessID string) ([]*domain.BusinessHours, error) {
+	defer r.db.lock()()
+	rows := tableOf[domain.BusinessHours](r.db).where(func(h *domain.BusinessHours) bool { return h.BusinessID == businessID })
+	slices.SortFunc(rows, func(a, b *domain.BusinessHours) int {
+		switch {
+		case a.LocationID == nil && b.LocationID != nil:
+			return -1
+		case a.LocationID != nil && b.LocationID == nil:
+			return 1
+		case a.LocationID != nil && *a.LocationID != *b.LocationID:
+			return cmp.Compare(*a.LocationID, *b.LocationID)
+		}
+		return cmp.Compare(a.Weekday, b.Weekday)
+	})
+	return rows, nil
+}
+
+// Replace replaces the hours of a business or one of its locations. Replaced rows are removed
+// for good, as a day has one row at most.
+func (r *businessHoursRepositoryImpl) Replace(ctx context.Context, businessID string, locationID *string, rows []*domain.BusinessHours) error {
+	defer r.db.lock()()
+	hours := tableOf[domain.BusinessHours](r.db)
+	hours.purgeWhere(func(h *domain.BusinessHours) bool {
+		if h.BusinessID != businessID {
+			return false
+		}
+		if locationID == nil {
+			return h.LocationID == nil
+		}
+		return h.LocationID != nil && *h.LocationID == *locationID
+	})
+	for _, row := range rows {
+		if err := hours.insert(row); err != nil {
+			return err
+		}
+	}
+	if locationID != nil {
+		return nil
+	}
+	now := r.db.now()
+	tableOf[domain.Business](r.db).update(businessID, func(b *domain.Business) {
+		b.BusinessHours = domain.WeeklyHoursOf(rows).Encode()
+		b.Version++
+		b.UpdatedAt = now
+	})
+	return nil
+}
@@ -0,0 +1,52 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBusinessHoursRepository_ReplaceRejectsBookingsOutsideHours(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	business := &domain.Business{UserID: "owner-1", Name: "Salon", TimeZone: "UTC"}
+	require.NoError(t, Insert(db, business))
+	repo := NewBusinessHoursRepository(db)
+	appointments := NewAppointmentRepository(db)
+
+	downtown := "location-1"
+	require.NoError(t, repo.Replace(ctx, business.ID, &downtown,
+		domain.WeeklyHours{"monday": {IsOpen: true, OpenTime: "07:00", CloseTime: "22:00"}}.Rows(business.ID, &downtown)))
+	require.NoError(t, repo.Replace(ctx, business.ID, nil,
+		domain.WeeklyHours{"monday": {IsOpen: true, OpenTime: "10:00", CloseTime: "14:00"}}.Rows(business.ID, nil)))
+
+	rows, err := repo.FindByBusiness(ctx, business.ID)
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Nil(t, rows[0].LocationID, "the business's hours come first")
+
+	// Monday 2 March 2026
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	request := domain.AvailabilityRequest{BusinessID: business.ID, StaffID: "staff-1", Start: start, End: start.Add(time.Hour)}
+	conflicts, err := appointments.CheckAvailability(ctx, request)
+	require.NoError(t, err)
+	require.Len(t, conflicts, 1)
+	assert.Equal(t, domain.ConflictOutsideHours, conflicts[0].Reason)
+
+	request.Start, request.End = start.Add(time.Hour), start.Add(2*time.Hour)
+	conflicts, err = appointments.CheckAvailability(ctx, request)
+	require.NoError(t, err)
+	assert.Empty(t, conflicts)
+
+	require.NoError(t, repo.Replace(ctx, business.ID, nil, nil))
+	rows, err = repo.FindByBusiness(ctx, business.ID)
+	require.NoError(t, err)
+	require.Len(t, rows, 1, "the location keeps its own hours")
+	stored, err := NewBaseRepository[domain.Business](db).GetByID(ctx, business.ID)
+	require.NoError(t, err)
+	assert.Nil(t, stored.BusinessHours, "the calendar hours apply again")
+}
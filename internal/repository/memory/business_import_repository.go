@@ -0,0 +1,182 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// businessImportRepositoryImpl implements the BusinessImportRepository interface
+type businessImportRepositoryImpl struct {
+	db *DB
+}
+
+// NewBusinessImportRepository creates a new business import repository
+func NewBusinessImportRepository(db *DB) domain.BusinessImportRepository {
+	return &businessImportRepositoryImpl{db: db}
+}
+
+// GetBatch retrieves an import batch by ID
+func (r *businessImportRepositoryImpl) GetBatch(ctx context.Context, id string) (*domain.ImportBatch, error) {
+	defer r.db.lock()()
+	return tableOf[domain.ImportBatch](r.db).get(id)
+}
+
+// FindBatches finds the import batches of a business, latest first
+func (r *businessImportRepositoryImpl) FindBatches(ctx context.Context, businessID string, limit int) ([]*domain.ImportBatch, error) {
+	defer r.db.lock()()
+	batches := tableOf[domain.ImportBatch](r.db).where(func(b *domain.ImportBatch) bool { return b.BusinessID == businessID })
+	slices.SortStableFunc(batches, func(a, b *domain.ImportBatch) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+	if len(batches) > limit {
+		batches = batches[:limit]
+	}
+	return batches, nil
+}
+
+// FindServiceNames finds the name of every service of a business
+func (r *businessImportRepositoryImpl) FindServiceNames(ctx context.Context, businessID string) ([]domain.ImportedName, error) {
+	defer r.db.lock()()
+	var names []domain.ImportedName
+	for _, service := range tableOf[domain.Service](r.db).where(func(s *domain.Service) bool { return s.BusinessID == businessID }) {
+		names = append(names, domain.ImportedName{ID: service.ID, Name: service.Name})
+	}
+	return names, nil
+}
+
+// FindCategories finds the service categories of a business
+func (r *businessImportRepositoryImpl) FindCategories(ctx context.Context, businessID string) ([]*domain.ServiceCategory, error) {
+	defer r.db.lock()()
+	categories := tableOf[domain.ServiceCategory](r.db).where(func(c *domain.ServiceCategory) bool { return c.BusinessID == businessID })
+	slices.SortStableFunc(categories, func(a, b *domain.ServiceCategory) int {
+		return cmp.Or(cmp.Compare(a.DisplayOrder, b.DisplayOrder), cmp.Compare(a.Name, b.Name))
+	})
+	return categories, nil
+}
+
+// FindStaffEmails finds the email of every staff member of a business, by staff ID
+func (r *businessImportRepositoryImpl) FindStaffEmails(ctx context.Context, businessID string) ([]domain.ImportedName, error) {
+	defer r.db.lock()()
+	users := tableOf[domain.User](r.db)
+	var names []domain.ImportedName
+	for _, staff := range tableOf[domain.Staff](r.db).where(func(s *domain.Staff) bool { return s.BusinessID == businessID }) {
+		if user, err := users.get(staff.UserID); err == nil {
+			names = append(names, domain.ImportedName{ID: staff.ID, Name: user.Email})
+		}
+	}
+	return names, nil
+}
+
+// FindUsersByEmail finds the user accounts with the email addresses, ignoring case
+func (r *businessImportRepositoryImpl) FindUsersByEmail(ctx context.Context, emails []string) ([]*domain.User, error) {
+	defer r.db.lock()()
+	return tableOf[domain.User](r.db).where(func(u *domain.User) bool {
+		return slices.ContainsFunc(emails, func(email string) bool { return strings.EqualFold(email, u.Email) })
+	}), nil
+}
+
+// ImportServices creates the categories, services and batch all at once, so that a failure
+// leaves none of them behind
+func (r *businessImportRepositoryImpl) ImportServices(ctx context.Context, batch *domain.ImportBatch, categories []*domain.ServiceCategory, services []*domain.Service) error {
+	defer r.db.lock()()
+	undoCategories, err := insertAll(tableOf[domain.ServiceCategory](r.db), categories)
+	if err != nil {
+		return err
+	}
+	undoServices, err := insertAll(tableOf[domain.Service](r.db), services)
+	if err != nil {
+		undoCategories()
+		return err
+	}
+	if err := tableOf[domain.ImportBatch](r.db).insert(batch); err != nil {
+		undoServices()
+		undoCategories()
+		return err
+	}
+	return nil
+}
+
+// ImportStaff creates the user accounts, staff members and batch all at once
+func (r *businessImportRepositoryImpl) ImportStaff(ctx context.Context, batch *domain.ImportBatch, users []*domain.User, staff []*domain.Staff) error {
+	defer r.db.lock()()
+	undoUsers, err := insertAll(tableOf[domain.User](r.db), users)
+	if err != nil {
+		return err
+	}
+	undoStaff, err := insertAll(tableOf[domain.Staff](r.db), staff)
+	if err != nil {
+		undoUsers()
+		return err
+	}
+	if err := tableOf[domain.ImportBatch](r.db).insert(batch); err != nil {
+		undoStaff()
+		undoUsers()
+		return err
+	}
+	return nil
+}
+
+// RollBack removes the records a batch created and saves the batch, marked rolled back, all at
+// once. User accounts are removed for good unless they have since signed in or joined another
+// business.
+func (r *businessImportRepositoryImpl) RollBack(ctx context.Context, batch *domain.ImportBatch) error {
+	defer r.db.lock()()
+	records, err := batch.GetRecords()
+	if err != nil {
+		return err
+	}
+
+	inUse := tableOf[domain.AppointmentLine](r.db).count(func(l *domain.AppointmentLine) bool {
+		return slices.Contains(records.ServiceIDs, l.ServiceID) || slices.Contains(records.StaffIDs, l.StaffID)
+	}) + tableOf[domain.Appointment](r.db).count(func(a *domain.Appointment) bool {
+		return slices.Contains(records.StaffIDs, a.StaffID)
+	})
+	if inUse > 0 {
+		return domain.ErrImportBatchInUse
+	}
+
+	tableOf[domain.Service](r.db).deleteWhere(func(s *domain.Service) bool { return slices.Contains(records.ServiceIDs, s.ID) })
+	tableOf[domain.ServiceCategory](r.db).deleteWhere(func(c *domain.ServiceCategory) bool { return slices.Contains(records.CategoryIDs, c.ID) })
+	staff := tableOf[domain.Staff](r.db)
+	staff.deleteWhere(func(s *domain.Staff) bool { return slices.Contains(records.StaffIDs, s.ID) })
+	businesses := tableOf[domain.Business](r.db)
+	var removedUserIDs []string
+	for _, user := range tableOf[domain.User](r.db).where(func(u *domain.User) bool {
+		return slices.Contains(records.UserIDs, u.ID) && u.ClerkID == nil
+	}) {
+		otherPositions := staff.countUnscoped(func(s *domain.Staff) bool {
+			return s.UserID == user.ID && !slices.Contains(records.StaffIDs, s.ID)
+		})
+		if otherPositions == 0 && businesses.count(func(b *domain.Business) bool { return b.UserID == user.ID }) == 0 {
+			removedUserIDs = append(removedUserIDs, user.ID)
+		}
+	}
+	// Staff rows go with their user accounts, as the foreign key cascades
+	tableOf[domain.User](r.db).purgeWhere(func(u *domain.User) bool { return slices.Contains(removedUserIDs, u.ID) })
+	staff.purgeWhere(func(s *domain.Staff) bool { return slices.Contains(removedUserIDs, s.UserID) })
+	return tableOf[domain.ImportBatch](r.db).save(batch)
+}
+
+// insertAll inserts the entities, removing those inserted when one fails. It returns a function
+// removing every entity inserted, to undo the insert when a later step fails.
+func insertAll[T any](table *table[T], entities []*T) (func(), error) {
+	var inserted []string
+	undo := func() {
+		for _, id := range inserted {
+			delete(table.rows, id)
+		}
+	}
+	for _, entity := range entities {
+		if err := table.insert(entity); err != nil {
+			undo()
+			return nil, err
+		}
+		inserted = append(inserted, table.meta.id(reflect.ValueOf(entity).Elem()))
+	}
+	return undo, nil
+}
@@ -0,0 +1,220 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// businessRepositoryImpl implements the BusinessRepository interface
+type businessRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.Business]
+}
+
+// NewBusinessRepository creates a new business repository
+func NewBusinessRepository(db *DB) domain.BusinessRepository {
+	return &businessRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.Business]{db: db},
+	}
+}
+
+// FindByUserID finds businesses owned by a user
+func (r *businessRepositoryImpl) FindByUserID(ctx context.Context, userID string) ([]*domain.Business, error) {
+	defer r.db.lock()()
+	return r.table().where(func(b *domain.Business) bool { return b.UserID == userID }), nil
+}
+
+// FindByName finds businesses by name (case-insensitive)
+func (r *businessRepositoryImpl) FindByName(ctx context.Context, name string) ([]*domain.Business, error) {
+	defer r.db.lock()()
+	return r.table().where(func(b *domain.Business) bool {
+		return strings.Contains(strings.ToLower(b.Name), strings.ToLower(name))
+	}), nil
+}
+
+// ExistsByName checks if a business with the given name exists
+func (r *businessRepositoryImpl) ExistsByName(ctx context.Context, name string) (bool, error) {
+	defer r.db.lock()()
+	count := r.table().count(func(b *domain.Business) bool { return strings.EqualFold(b.Name, name) })
+	return count > 0, nil
+}
+
+// FindActiveBusinesses finds active businesses with pagination
+func (r *businessRepositoryImpl) FindActiveBusinesses(ctx context.Context, page, pageSize int) ([]*domain.Business, int64, error) {
+	defer r.db.lock()()
+	businesses := r.table().where(func(b *domain.Business) bool { return b.IsActive })
+	return paginate(businesses, page, pageSize), int64(len(businesses)), nil
+}
+
+// Search finds the businesses of every tenant passing a filter, most recently created first
+func (r *businessRepositoryImpl) Search(ctx context.Context, search domain.BusinessSearch, page, pageSize int) ([]*domain.Business, int64, error) {
+	defer r.db.lock()()
+	businesses := r.table().where(search.Matches)
+	slices.SortStableFunc(businesses, func(a, b *domain.Business) int { return b.CreatedAt.Compare(a.CreatedAt) })
+	return paginate(businesses, page, pageSize), int64(len(businesses)), nil
+}
+
+// SearchByLocation finds businesses by city and country
+func (r *businessRepositoryImpl) SearchByLocation(ctx context.Context, city, country string) ([]*domain.Business, error) {
+	defer r.db.lock()()
+	locations := tableOf[domain.BusinessLocation](r.db)
+	return r.table().where(func(b *domain.Business) bool {
+		return b.IsActive && locations.count(func(l *domain.BusinessLocation) bool {
+			return l.BusinessID == b.ID &&
+				(city == "" || (l.City != nil && strings.EqualFold(*l.City, city))) &&
+				(country == "" || strings.EqualFold(l.Country, country))
+		}) > 0
+	}), nil
+}
+
+// SearchByService finds businesses that offer a specific service
+func (r *businessRepositoryImpl) SearchByService(ctx context.Context, serviceName string) ([]*domain.Business, error) {
+	defer r.db.lock()()
+	services := tableOf[domain.Service](r.db)
+	return r.table().where(func(b *domain.Business) bool {
+		return b.IsActive && services.count(func(s *domain.Service) bool {
+			return s.BusinessID == b.ID && strings.Contains(strings.ToLower(s.Name), strings.ToLower(serviceName))
+		}) > 0
+	}), nil
+}
+
+// GetBusinessWithDetails retrieves a business with all related data
+func (r *businessRepositoryImpl) GetBusinessWithDetails(ctx context.Context, businessID string) (*domain.Business, error) {
+	defer r.db.lock()()
+	business, err := r.table().get(businessID)
+	if err != nil {
+		return nil, err
+	}
+	business.Locations = r.locations(businessID)
+	business.Settings_ = businessSettings(r.db, businessID)
+	if user, err := tableOf[domain.User](r.db).get(business.UserID); err == nil {
+		business.User = *user
+	}
+	return business, nil
+}
+
+// GetWithLocations retrieves a business with its locations
+func (r *businessRepositoryImpl) GetWithLocations(ctx context.Context, businessID string) (*domain.Business, error) {
+	defer r.db.lock()()
+	business, err := r.table().get(businessID)
+	if err != nil {
+		return nil, err
+	}
+	business.Locations = r.locations(businessID)
+	return business, nil
+}
+
+// locations returns the locations of a business
+func (r *businessRepositoryImpl) locations(businessID string) []domain.BusinessLocation {
+	var locations []domain.BusinessLocation
+	for _, location := range tableOf[domain.BusinessLocation](r.db).where(func(l *domain.BusinessLocation) bool {
+		return l.BusinessID == businessID
+	}) {
+		locations = append(locations, *location)
+	}
+	return locations
+}
+
+// SetDataRegion changes the region a business's data must stay in
+func (r *businessRepositoryImpl) SetDataRegion(ctx context.Context, businessID string, region domain.DataRegion, updatedBy *string) error {
+	defer r.db.lock()()
+	now := r.db.now()
+	r.table().update(businessID, func(b *domain.Business) {
+		b.DataRegion = region
+		b.UpdatedBy = updatedBy
+		b.UpdatedAt = now
+	})
+	return nil
+}
+
+// businessLocationRepositoryImpl implements the BusinessLocationRepository interface
+type businessLocationRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.BusinessLocation]
+}
+
+// NewBusinessLocationRepository creates a new business location repository, scoped to the tenant
+// in the context
+func NewBusinessLocationRepository(db *DB) domain.BusinessLocationRepository {
+	return &businessLocationRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.BusinessLocation]{db: db, tenantScoped: true},
+	}
+}
+
+// FindByBusinessID finds all locations for a business, the main location first
+func (r *businessLocationRepositoryImpl) FindByBusinessID(ctx context.Context, businessID string) ([]*domain.BusinessLocation, error) {
+	defer r.db.lock()()
+	locations := r.table().where(func(l *domain.BusinessLocation) bool { return l.BusinessID == businessID })
+	slices.SortStableFunc(locations, func(a, b *domain.BusinessLocation) int {
+		if a.IsMain != b.IsMain {
+			if a.IsMain {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return locations, nil
+}
+
+// GetMainLocation retrieves the main location for a business
+func (r *businessLocationRepositoryImpl) GetMainLocation(ctx context.Context, businessID string) (*domain.BusinessLocation, error) {
+	defer r.db.lock()()
+	return r.table().first(func(l *domain.BusinessLocation) bool { return l.BusinessID == businessID && l.IsMain })
+}
+
+// SetMainLocation sets a location as the main location for a business
+func (r *businessLocationRepositoryImpl) SetMainLocation(ctx context.Context, businessID, locationID string) error {
+	defer r.db.lock()()
+	now := r.db.now()
+	r.table().updateWhere(func(l *domain.BusinessLocation) bool { return l.BusinessID == businessID }, func(l *domain.BusinessLocation) {
+		l.IsMain = l.ID == locationID
+		l.UpdatedAt = now
+	})
+	return nil
+}
+
+// businessSettingsRepositoryImpl implements the BusinessSettingsRepository interface
+type businessSettingsRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.BusinessSettings]
+}
+
+// NewBusinessSettingsRepository creates a new business settings repository, scoped to the tenant
+// in the context
+func NewBusinessSettingsRepository(db *DB) domain.BusinessSettingsRepository {
+	return &businessSettingsRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.BusinessSettings]{db: db, tenantScoped: true},
+	}
+}
+
+// Create stores the settings of a business, giving the settings left zero their column defaults
+func (r *businessSettingsRepositoryImpl) Create(ctx context.Context, settings *domain.BusinessSettings) error {
+	defer r.db.lock()()
+	if err := r.claim(ctx, settings); err != nil {
+		return err
+	}
+	t := r.table()
+	t.meta.applyDefaults(reflect.ValueOf(settings).Elem())
+	return t.insert(settings)
+}
+
+// GetByBusinessID retrieves settings for a business
+func (r *businessSettingsRepositoryImpl) GetByBusinessID(ctx context.Context, businessID string) (*domain.BusinessSettings, error) {
+	defer r.db.lock()()
+	return r.table().first(func(s *domain.BusinessSettings) bool { return s.BusinessID == businessID })
+}
+
+// UpdateByBusinessID updates the non-zero settings of a business
+func (r *businessSettingsRepositoryImpl) UpdateByBusinessID(ctx context.Context, businessID string, settings *domain.BusinessSettings) error {
+	defer r.db.lock()()
+	t := r.table()
+	now := r.db.now()
+	t.updateWhere(func(s *domain.BusinessSettings) bool { return s.BusinessID == businessID }, func(s *domain.BusinessSettings) {
+		t.meta.assignNonZero(reflect.ValueOf(s).Elem(), reflect.ValueOf(settings).Elem())
+		s.UpdatedAt = now
+	})
+	return nil
+}
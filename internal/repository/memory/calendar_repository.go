@@ -0,0 +1,152 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// calendarRepositoryImpl implements the CalendarRepository interface
+type calendarRepositoryImpl struct {
+	db *DB
+}
+
+// NewCalendarRepository creates a new calendar read model repository
+func NewCalendarRepository(db *DB) domain.CalendarRepository {
+	return &calendarRepositoryImpl{db: db}
+}
+
+// FindByDateRange finds the calendar entries of a business between two local dates, inclusive
+func (r *calendarRepositoryImpl) FindByDateRange(ctx context.Context, businessID string, startDate, endDate time.Time, staffID *string) ([]*domain.CalendarEntry, error) {
+	defer r.db.lock()()
+	start, end := startDate.Format(time.DateOnly), endDate.Format(time.DateOnly)
+	entries := tableOf[domain.CalendarEntry](r.db).where(func(e *domain.CalendarEntry) bool {
+		date := e.CalendarDate.Format(time.DateOnly)
+		return e.BusinessID == businessID && date >= start && date <= end && (staffID == nil || e.StaffID == *staffID)
+	})
+	slices.SortStableFunc(entries, func(a, b *domain.CalendarEntry) int {
+		return cmp.Or(a.CalendarDate.Compare(b.CalendarDate), cmp.Compare(a.StaffID, b.StaffID), a.StartTime.Compare(b.StartTime))
+	})
+	return entries, nil
+}
+
+// FindByAppointmentID finds the calendar entry of an appointment
+func (r *calendarRepositoryImpl) FindByAppointmentID(ctx context.Context, appointmentID string) (*domain.CalendarEntry, error) {
+	defer r.db.lock()()
+	return tableOf[domain.CalendarEntry](r.db).first(func(e *domain.CalendarEntry) bool { return e.AppointmentID == appointmentID })
+}
+
+// RefreshAppointment re-projects a single appointment, removing it if it no longer exists
+func (r *calendarRepositoryImpl) RefreshAppointment(ctx context.Context, appointmentID string) error {
+	return r.refresh(
+		func(e *domain.CalendarEntry) bool { return e.AppointmentID == appointmentID },
+		func(a *domain.Appointment) bool { return a.ID == appointmentID })
+}
+
+// RefreshClient re-projects the appointments of a client, e.g. after the client is renamed
+func (r *calendarRepositoryImpl) RefreshClient(ctx context.Context, clientID string) error {
+	return r.refresh(
+		func(e *domain.CalendarEntry) bool { return e.ClientID == clientID },
+		func(a *domain.Appointment) bool { return a.ClientID == clientID })
+}
+
+// RefreshStaff re-projects the appointments of a staff member
+func (r *calendarRepositoryImpl) RefreshStaff(ctx context.Context, staffID string) error {
+	return r.refresh(
+		func(e *domain.CalendarEntry) bool { return e.StaffID == staffID },
+		func(a *domain.Appointment) bool { return a.StaffID == staffID })
+}
+
+// RebuildBusiness re-projects all appointments of a business
+func (r *calendarRepositoryImpl) RebuildBusiness(ctx context.Context, businessID string) error {
+	return r.refresh(
+		func(e *domain.CalendarEntry) bool { return e.BusinessID == businessID },
+		func(a *domain.Appointment) bool { return a.BusinessID == businessID })
+}
+
+// refresh replaces the entries matching a filter with a fresh projection of the appointments
+// matching another
+func (r *calendarRepositoryImpl) refresh(entries func(*domain.CalendarEntry) bool, appointments func(*domain.Appointment) bool) error {
+	defer r.db.lock()()
+	calendar := tableOf[domain.CalendarEntry](r.db)
+	calendar.purgeWhere(entries)
+
+	now := r.db.now()
+	for _, a := range tableOf[domain.Appointment](r.db).where(appointments) {
+		entry, ok := r.project(a, now)
+		if !ok {
+			continue
+		}
+		calendar.purgeWhere(func(e *domain.CalendarEntry) bool { return e.AppointmentID == a.ID })
+		if err := calendar.insert(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// project builds the calendar entry of an appointment. It returns false when the business,
+// client, staff member or their user account is missing, as the SQL join would.
+func (r *calendarRepositoryImpl) project(a *domain.Appointment, now time.Time) (*domain.CalendarEntry, bool) {
+	business, err := tableOf[domain.Business](r.db).get(a.BusinessID)
+	if err != nil {
+		return nil, false
+	}
+	client, err := tableOf[domain.Client](r.db).get(a.ClientID)
+	if err != nil {
+		return nil, false
+	}
+	user, ok := staffUser(r.db, a.StaffID)
+	if !ok {
+		return nil, false
+	}
+
+	location := time.UTC
+	if business.TimeZone != "" {
+		if loaded, err := time.LoadLocation(business.TimeZone); err == nil {
+			location = loaded
+		}
+	}
+	year, month, day := a.StartTime.In(location).Date()
+
+	names := []string{}
+	duration := 0
+	services := tableOf[domain.Service](r.db)
+	for _, line := range tableOf[domain.AppointmentLine](r.db).where(func(l *domain.AppointmentLine) bool {
+		return l.AppointmentID == a.ID
+	}) {
+		service, err := services.get(line.ServiceID)
+		if err != nil {
+			continue
+		}
+		names = append(names, service.Name)
+		duration += line.Duration
+	}
+	if len(names) == 0 {
+		duration = int(a.EndTime.Sub(a.StartTime).Minutes())
+	}
+	encoded, _ := json.Marshal(names)
+
+	return &domain.CalendarEntry{
+		AppointmentID: a.ID,
+		BusinessID:    a.BusinessID,
+		StaffID:       a.StaffID,
+		ClientID:      a.ClientID,
+		CalendarDate:  time.Date(year, month, day, 0, 0, 0, 0, time.UTC),
+		StartTime:     a.StartTime,
+		EndTime:       a.EndTime,
+		Status:        a.Status,
+		ClientName:    client.FirstName + " " + client.LastName,
+		ClientPhone:   client.Phone,
+		StaffName:     user.FirstName + " " + user.LastName,
+		ServiceNames:  stringPtr(string(encoded)),
+		TotalDuration: duration,
+		Price:         a.EstimatedPrice,
+		Notes:         a.Notes,
+		ProjectedAt:   now,
+	}, true
+}
@@ -0,0 +1,130 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// campaignClientRepositoryImpl implements the CampaignClientRepository interface
+type campaignClientRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.CampaignClient]
+}
+
+// NewCampaignClientRepository creates a new campaign client repository
+func NewCampaignClientRepository(db *DB) domain.CampaignClientRepository {
+	return &campaignClientRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.CampaignClient]{db: db},
+	}
+}
+
+// audience finds the business's clients in an audience. The caller must hold the lock.
+func (r *campaignClientRepositoryImpl) audience(businessID string, audience domain.TargetAudience, now time.Time) []*domain.Client {
+	completions := tableOf[domain.ServiceCompletion](r.db)
+	facts := map[string]*domain.AudienceFacts{}
+	for _, appointment := range tableOf[domain.Appointment](r.db).where(func(a *domain.Appointment) bool {
+		return a.BusinessID == businessID && a.Status == domain.AppointmentStatusCompleted
+	}) {
+		client, ok := facts[appointment.ClientID]
+		if !ok {
+			client = &domain.AudienceFacts{}
+			facts[appointment.ClientID] = client
+		}
+		client.Visits++
+		if client.LastVisit == nil || appointment.StartTime.After(*client.LastVisit) {
+			client.LastVisit = &appointment.StartTime
+		}
+		for _, completion := range completions.where(func(c *domain.ServiceCompletion) bool { return c.AppointmentID == appointment.ID }) {
+			client.Spent = client.Spent.Add(completion.PriceCharged)
+		}
+	}
+
+	memberships := tableOf[domain.ClientLoyaltyMembership](r.db)
+	return tableOf[domain.Client](r.db).where(func(c *domain.Client) bool {
+		if c.BusinessID != businessID {
+			return false
+		}
+		client := domain.AudienceFacts{}
+		if found, ok := facts[c.ID]; ok {
+			client = *found
+		}
+		client.Client = c
+		client.Tags, _ = c.GetTags()
+		client.Memberships = memberships.where(func(m *domain.ClientLoyaltyMembership) bool { return m.ClientID == c.ID })
+		return audience.Matches(client, now)
+	})
+}
+
+// CountAudience counts the business's clients in the audience at the given time
+func (r *campaignClientRepositoryImpl) CountAudience(ctx context.Context, businessID string, audience domain.TargetAudience, now time.Time) (int64, error) {
+	defer r.db.lock()()
+	return int64(len(r.audience(businessID, audience, now))), nil
+}
+
+// TargetClients adds the clients in the campaign's audience at the given time to the
+// campaign, skipping clients targeted already, and returns how many were added
+func (r *campaignClientRepositoryImpl) TargetClients(ctx context.Context, campaign *domain.Campaign, audience domain.TargetAudience, now time.Time, userID *string) (int64, error) {
+	unlock := r.db.lock()
+	clients := r.audience(campaign.BusinessID, audience, now)
+	unlock()
+
+	targets := make([]*domain.CampaignClient, len(clients))
+	for i, client := range clients {
+		targets[i] = &domain.CampaignClient{CampaignID: campaign.ID, ClientID: client.ID, Status: domain.CampaignClientPending}
+		targets[i].SetAuditFields(userID)
+	}
+	return r.CreateBatch(ctx, targets, domain.BatchOptions{
+		OnConflict:      domain.ConflictSkip,
+		ConflictColumns: []string{"campaign_id", "client_id"},
+	})
+}
+
+// FindByCampaignID finds the clients targeted by a campaign, oldest first
+func (r *campaignClientRepositoryImpl) FindByCampaignID(ctx context.Context, campaignID string) ([]*domain.CampaignClient, error) {
+	defer r.db.lock()()
+	return r.table().where(func(c *domain.CampaignClient) bool { return c.CampaignID == campaignID }), nil
+}
+
+// GetCampaignAnalytics aggregates the responses to a campaign between from and to, with the
+// revenue of the visits its converted clients completed
+func (r *campaignClientRepositoryImpl) GetCampaignAnalytics(ctx context.Context, campaign *domain.Campaign, from, to time.Time) (*domain.CampaignAnalytics, error) {
+	defer r.db.lock()()
+	within := func(at *time.Time) bool { return at != nil && !at.Before(from) && at.Before(to) }
+	analytics := &domain.CampaignAnalytics{CampaignID: campaign.ID, From: from, To: to}
+	completions := tableOf[domain.ServiceCompletion](r.db)
+	for _, client := range r.table().where(func(c *domain.CampaignClient) bool { return c.CampaignID == campaign.ID }) {
+		count := func(reached bool) int64 {
+			if reached {
+				return 1
+			}
+			return 0
+		}
+		analytics.Targeted += count(within(&client.CreatedAt))
+		analytics.Sent += count(within(client.SentAt))
+		analytics.Opened += count(within(client.OpenedAt))
+		analytics.Clicked += count(within(client.ClickedAt))
+		analytics.Converted += count(within(client.ConvertedAt))
+		analytics.Unsubscribed += count(client.Status == domain.CampaignClientUnsubscribed)
+		if client.ConvertedAt == nil {
+			continue
+		}
+
+		for _, appointment := range tableOf[domain.Appointment](r.db).where(func(a *domain.Appointment) bool {
+			return a.ClientID == client.ClientID && a.BusinessID == campaign.BusinessID && a.Status == domain.AppointmentStatusCompleted
+		}) {
+			for _, completion := range completions.where(func(c *domain.ServiceCompletion) bool { return c.AppointmentID == appointment.ID }) {
+				completedAt := appointment.StartTime
+				if completion.CompletionDate != nil {
+					completedAt = *completion.CompletionDate
+				}
+				if completedAt.Before(*client.ConvertedAt) || !within(&completedAt) {
+					continue
+				}
+				analytics.Visits++
+				analytics.Revenue = analytics.Revenue.Add(completion.PriceCharged)
+			}
+		}
+	}
+	return analytics, nil
+}
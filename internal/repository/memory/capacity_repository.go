@@ -0,0 +1,51 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// capacityAlertRepositoryImpl implements the CapacityAlertRepository interface
+type capacityAlertRepositoryImpl struct {
+	db *DB
+}
+
+// NewCapacityAlertRepository creates a new capacity alert repository
+func NewCapacityAlertRepository(db *DB) domain.CapacityAlertRepository {
+	return &capacityAlertRepositoryImpl{db: db}
+}
+
+// MarkNotified records the alert unless an alert for the same date, kind and staff member exists
+func (r *capacityAlertRepositoryImpl) MarkNotified(ctx context.Context, alert *domain.CapacityAlert) (bool, error) {
+	defer r.db.lock()()
+	alerts := tableOf[domain.CapacityAlert](r.db)
+	date := alert.AlertDate.Format(time.DateOnly)
+	notified := alerts.count(func(a *domain.CapacityAlert) bool {
+		return a.BusinessID == alert.BusinessID && a.AlertDate.Format(time.DateOnly) == date && a.Kind == alert.Kind &&
+			((a.StaffID == nil && alert.StaffID == nil) || (a.StaffID != nil && alert.StaffID != nil && *a.StaffID == *alert.StaffID))
+	})
+	if notified > 0 {
+		return false, nil
+	}
+	return true, alerts.insert(alert)
+}
+
+// FindBusinessesWithAppointments lists the businesses with appointments on the calendar between
+// two local dates, inclusive
+func (r *capacityAlertRepositoryImpl) FindBusinessesWithAppointments(ctx context.Context, startDate, endDate time.Time) ([]string, error) {
+	defer r.db.lock()()
+	start, end := startDate.Format(time.DateOnly), endDate.Format(time.DateOnly)
+	businessIDs := []string{}
+	for _, entry := range tableOf[domain.CalendarEntry](r.db).where(func(e *domain.CalendarEntry) bool {
+		date := e.CalendarDate.Format(time.DateOnly)
+		return date >= start && date <= end
+	}) {
+		if !slices.Contains(businessIDs, entry.BusinessID) {
+			businessIDs = append(businessIDs, entry.BusinessID)
+		}
+	}
+	return businessIDs, nil
+}
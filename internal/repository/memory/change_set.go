@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// bookkeepingColumns are the columns a change set cannot write
+var bookkeepingColumns = map[string]bool{
+	"id": true, "created_at": true, "created_by": true, "updated_at": true, "updated_by": true,
+	"deleted_at": true, "deleted_by": true, "version": true,
+}
+
+// UpdateFields writes only the columns the changes touch, zero values included, and returns
+// the updated entity
+func (r *BaseRepositoryImpl[T]) UpdateFields(ctx context.Context, id string, changes domain.ChangeSet) (*T, error) {
+	defer r.db.lock()()
+	t := r.table()
+	if changes.IsEmpty() {
+		return nil, fmt.Errorf("%w: the update changes no field", domain.ErrValidation)
+	}
+	for _, column := range changes.Columns() {
+		if _, ok := t.meta.columns[column]; !ok || bookkeepingColumns[column] {
+			return nil, fmt.Errorf("%w: %s has no changeable column %q", domain.ErrValidation, t.meta.table, column)
+		}
+	}
+	if err := r.ensureOwned(ctx, id); err != nil {
+		return nil, err
+	}
+	if _, err := r.get(ctx, id); err != nil {
+		return nil, err
+	}
+
+	updated := *t.rows[id]
+	v := reflect.ValueOf(&updated).Elem()
+	current := t.meta.version(v)
+	if changes.Version != 0 && changes.Version != current {
+		return nil, &domain.ConflictError{EntityType: t.meta.table, ID: id, Version: changes.Version}
+	}
+	for column, value := range changes.Values() {
+		if err := assignColumn(v.FieldByIndex(t.meta.columns[column]), value); err != nil {
+			return nil, fmt.Errorf("%w: %s.%s: %v", domain.ErrValidation, t.meta.table, column, err)
+		}
+	}
+	if index, ok := t.meta.columns["updated_by"]; ok {
+		if actorID, ok := domain.ActorFromContext(ctx); ok {
+			_ = assignColumn(v.FieldByIndex(index), actorID)
+		}
+	}
+	t.meta.setVersion(v, current+1)
+	t.meta.touch(v, r.db.now(), false)
+
+	t.rows[id] = &updated
+	result := updated
+	return &result, nil
+}
+
+// assignColumn writes a value to a field the way the database would store it: nil clears the
+// field, and values are taken by or wrapped in pointers to match the field
+func assignColumn(field reflect.Value, value any) error {
+	if value == nil {
+		field.SetZero()
+		return nil
+	}
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Pointer && field.Kind() != reflect.Pointer {
+		if v.IsNil() {
+			field.SetZero()
+			return nil
+		}
+		v = v.Elem()
+	}
+	if field.Kind() == reflect.Pointer && v.Type() != field.Type() {
+		if !v.Type().ConvertibleTo(field.Type().Elem()) {
+			return fmt.Errorf("cannot store %s in %s", v.Type(), field.Type())
+		}
+		ptr := reflect.New(field.Type().Elem())
+		ptr.Elem().Set(v.Convert(field.Type().Elem()))
+		field.Set(ptr)
+		return nil
+	}
+	if !v.Type().ConvertibleTo(field.Type()) {
+		return fmt.Errorf("cannot store %s in %s", v.Type(), field.Type())
+	}
+	field.Set(v.Convert(field.Type()))
+	return nil
+}
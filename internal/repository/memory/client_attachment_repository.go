@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// clientAttachmentRepositoryImpl implements the ClientAttachmentRepository interface
+type clientAttachmentRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.ClientAttachment]
+}
+
+// NewClientAttachmentRepository creates a new client attachment repository, scoped to the tenant
+// in the context
+func NewClientAttachmentRepository(db *DB) domain.ClientAttachmentRepository {
+	return &clientAttachmentRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.ClientAttachment]{db: db, tenantScoped: true},
+	}
+}
+
+// FindByClient finds the stored attachments of a client, newest first, only those of an
+// appointment when one is given
+func (r *clientAttachmentRepositoryImpl) FindByClient(ctx context.Context, clientID string, appointmentID *string) ([]*domain.ClientAttachment, error) {
+	defer r.db.lock()()
+	inScope := r.scope(ctx)
+	attachments := r.table().where(func(a *domain.ClientAttachment) bool {
+		return a.ClientID == clientID && a.Status == domain.ClientAttachmentStored &&
+			(appointmentID == nil || (a.AppointmentID != nil && *a.AppointmentID == *appointmentID)) &&
+			(inScope == nil || inScope(a))
+	})
+	return newestAttachmentsFirst(attachments), nil
+}
+
+// FindUsableInMarketing finds the stored photos of a business whose clients agreed to their use
+// in marketing, newest first
+func (r *clientAttachmentRepositoryImpl) FindUsableInMarketing(ctx context.Context, businessID string) ([]*domain.ClientAttachment, error) {
+	defer r.db.lock()()
+	inScope := r.scope(ctx)
+	attachments := r.table().where(func(a *domain.ClientAttachment) bool {
+		return a.BusinessID == businessID && a.UsableInMarketing() && (inScope == nil || inScope(a))
+	})
+	return newestAttachmentsFirst(attachments), nil
+}
+
+// SumUsage totals the bytes of a business's attachments counting against its quota
+func (r *clientAttachmentRepositoryImpl) SumUsage(ctx context.Context, businessID string) (int64, error) {
+	defer r.db.lock()()
+	inScope := r.scope(ctx)
+	var total int64
+	for _, a := range r.table().where(func(a *domain.ClientAttachment) bool {
+		return a.BusinessID == businessID && (inScope == nil || inScope(a)) &&
+			(a.Status == domain.ClientAttachmentPending || a.Status == domain.ClientAttachmentStored)
+	}) {
+		total += a.SizeBytes
+	}
+	return total, nil
+}
+
+// FindRemovable finds the attachments whose files can be removed, across businesses and oldest
+// first
+func (r *clientAttachmentRepositoryImpl) FindRemovable(ctx context.Context, abandonedBefore time.Time, limit int) ([]*domain.ClientAttachment, error) {
+	defer r.db.lock()()
+	attachments := r.table().where(func(a *domain.ClientAttachment) bool {
+		switch a.Status {
+		case domain.ClientAttachmentPending:
+			return a.UploadExpiresAt.Before(abandonedBefore)
+		case domain.ClientAttachmentRejected, domain.ClientAttachmentDeleted:
+			return true
+		}
+		return false
+	})
+	slices.SortStableFunc(attachments, func(a, b *domain.ClientAttachment) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+	return attachments[:min(limit, len(attachments))], nil
+}
+
+// newestAttachmentsFirst orders attachments as the database does, newest first
+func newestAttachmentsFirst(attachments []*domain.ClientAttachment) []*domain.ClientAttachment {
+	slices.SortStableFunc(attachments, func(a, b *domain.ClientAttachment) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+	})
+	return attachments
+}
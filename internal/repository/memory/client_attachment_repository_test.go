@@ -0,0 +1,114 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestClientAttachmentRepository_FindsAndTotalsAttachments(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	repo := NewClientAttachmentRepository(db)
+
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	appointmentID := "appointment-1"
+	attachment := func(clientID string, kind domain.ClientAttachmentKind, status domain.ClientAttachmentStatus, size int64) *domain.ClientAttachment {
+		return &domain.ClientAttachment{BusinessID: "business-1", ClientID: clientID, Kind: kind, Status: status,
+			SizeBytes: size, UploadExpiresAt: now}
+	}
+	before := attachment("client-1", domain.ClientAttachmentBeforePhoto, domain.ClientAttachmentStored, 100)
+	before.AppointmentID = &appointmentID
+	before.CreatedAt = now.Add(-time.Hour)
+	after := attachment("client-1", domain.ClientAttachmentAfterPhoto, domain.ClientAttachmentStored, 200)
+	after.AppointmentID = &appointmentID
+	after.CreatedAt = now
+	after.MarketingConsentAt = &now
+	form := attachment("client-1", domain.ClientAttachmentDocument, domain.ClientAttachmentStored, 400)
+	uploading := attachment("client-1", domain.ClientAttachmentPhoto, domain.ClientAttachmentPending, 800)
+	deleted := attachment("client-1", domain.ClientAttachmentPhoto, domain.ClientAttachmentDeleted, 1600)
+	other := attachment("client-2", domain.ClientAttachmentPhoto, domain.ClientAttachmentStored, 3200)
+	other.BusinessID = "business-2"
+	other.MarketingConsentAt = &now
+	require.NoError(t, Insert(db, before, after, form, uploading, deleted, other))
+
+	ofAppointment, err := repo.FindByClient(ctx, "client-1", &appointmentID)
+	require.NoError(t, err)
+	require.Len(t, ofAppointment, 2)
+	assert.Equal(t, after.ID, ofAppointment[0].ID, "newest first")
+	assert.Equal(t, before.ID, ofAppointment[1].ID)
+
+	all, err := repo.FindByClient(ctx, "client-1", nil)
+	require.NoError(t, err)
+	assert.Len(t, all, 3, "only stored attachments are listed")
+
+	marketing, err := repo.FindUsableInMarketing(ctx, "business-1")
+	require.NoError(t, err)
+	require.Len(t, marketing, 1)
+	assert.Equal(t, after.ID, marketing[0].ID)
+
+	used, err := repo.SumUsage(ctx, "business-1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(100+200+400+800), used, "stored and pending attachments count, deleted ones do not")
+}
+
+func TestClientAttachmentRepository_FindRemovable(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	repo := NewClientAttachmentRepository(db)
+
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	attachment := func(status domain.ClientAttachmentStatus, expires time.Time) *domain.ClientAttachment {
+		return &domain.ClientAttachment{BusinessID: "business-1", ClientID: "client-1", Kind: domain.ClientAttachmentPhoto,
+			Status: status, UploadExpiresAt: expires}
+	}
+	abandoned := attachment(domain.ClientAttachmentPending, now.Add(-2*time.Hour))
+	inProgress := attachment(domain.ClientAttachmentPending, now.Add(time.Hour))
+	rejected := attachment(domain.ClientAttachmentRejected, now)
+	stored := attachment(domain.ClientAttachmentStored, now)
+	deleted := attachment(domain.ClientAttachmentDeleted, now)
+	removed := attachment(domain.ClientAttachmentRemoved, now)
+	require.NoError(t, Insert(db, abandoned, inProgress, rejected, stored, deleted, removed))
+
+	attachments, err := repo.FindRemovable(ctx, now.Add(-time.Hour), 10)
+	require.NoError(t, err)
+	ids := []string{}
+	for _, a := range attachments {
+		ids = append(ids, a.ID)
+	}
+	assert.ElementsMatch(t, []string{abandoned.ID, rejected.ID, deleted.ID}, ids)
+}
+
+func TestClientDataRepository_AnonymizeDeletesAttachments(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	repo := NewClientDataRepository(db)
+
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	client := &domain.Client{BusinessID: "business-1", FirstName: "Ana", LastName: "Silva"}
+	require.NoError(t, Insert(db, client))
+	caption := "Balayage"
+	photo := &domain.ClientAttachment{BusinessID: "business-1", ClientID: client.ID, Kind: domain.ClientAttachmentAfterPhoto,
+		Status: domain.ClientAttachmentStored, Caption: &caption, MarketingConsentAt: &now}
+	removed := &domain.ClientAttachment{BusinessID: "business-1", ClientID: client.ID, Kind: domain.ClientAttachmentPhoto,
+		Status: domain.ClientAttachmentRemoved}
+	require.NoError(t, Insert(db, photo, removed))
+
+	otherTenant := domain.WithTenant(ctx, domain.TenantContext{BusinessID: "business-2"})
+	assert.ErrorIs(t, repo.Anonymize(otherTenant, client.ID, now, nil), gorm.ErrRecordNotFound, "other businesses' clients are not found")
+	require.NoError(t, repo.Anonymize(ctx, client.ID, now, nil))
+	attachments := NewClientAttachmentRepository(db)
+	photo, err := attachments.GetByID(ctx, photo.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.ClientAttachmentDeleted, photo.Status)
+	assert.Nil(t, photo.Caption)
+	assert.Nil(t, photo.MarketingConsentAt)
+	removed, err = attachments.GetByID(ctx, removed.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.ClientAttachmentRemoved, removed.Status)
+}
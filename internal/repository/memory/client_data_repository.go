@@ -0,0 +1,229 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// clientDataRepositoryImpl implements the ClientDataRepository interface. Campaign messages and
+// the other tables without a domain model are not held in memory, so they are exported empty
+// and left alone on anonymization.
+type clientDataRepositoryImpl struct {
+	db      *DB
+	clients *BaseRepositoryImpl[domain.Client]
+}
+
+// NewClientDataRepository creates a new client data repository, scoped to the tenant in the
+// context
+func NewClientDataRepository(db *DB) domain.ClientDataRepository {
+	return &clientDataRepositoryImpl{db: db, clients: &BaseRepositoryImpl[domain.Client]{db: db, tenantScoped: true}}
+}
+
+// FindExport collects the profile, appointments, completions and loyalty memberships of a client
+func (r *clientDataRepositoryImpl) FindExport(ctx context.Context, clientID string) (*domain.ClientDataExport, error) {
+	defer r.db.lock()()
+	client, err := r.clients.get(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &domain.ClientDataExport{
+		Profile: domain.ClientProfileExport{
+			ID:             client.ID,
+			BusinessID:     client.BusinessID,
+			FirstName:      client.FirstName,
+			LastName:       client.LastName,
+			Phone:          client.Phone,
+			DateOfBirth:    client.DateOfBirth,
+			Notes:          client.Notes,
+			Allergies:      client.Allergies,
+			ReferralSource: client.ReferralSource,
+			IsActive:       client.IsActive,
+			CreatedAt:      client.CreatedAt,
+			AnonymizedAt:   client.AnonymizedAt,
+		},
+		Appointments:       []domain.ClientAppointmentExport{},
+		Completions:        []domain.ClientCompletionExport{},
+		LoyaltyMemberships: []domain.ClientLoyaltyMembershipExport{},
+		CampaignMessages:   []domain.ClientCampaignMessageExport{},
+	}
+	if client.Email != "" {
+		export.Profile.Email = &client.Email
+	}
+
+	appointments := tableOf[domain.Appointment](r.db).where(func(a *domain.Appointment) bool { return a.ClientID == clientID })
+	sortByStart(appointments)
+	for _, a := range appointments {
+		appointment := domain.ClientAppointmentExport{
+			ID:                 a.ID,
+			StartTime:          a.StartTime,
+			EndTime:            a.EndTime,
+			Status:             string(a.Status),
+			Services:           r.serviceNames(a.ID),
+			Notes:              a.Notes,
+			EstimatedPrice:     a.EstimatedPrice,
+			CancellationReason: a.CancellationReason,
+		}
+		if payment, ok := tableOf[appointmentPayment](r.db).rows[a.ID]; ok {
+			appointment.ActualPrice = payment.ActualPrice
+			if payment.PaymentStatus != nil {
+				appointment.PaymentStatus = stringPtr(string(*payment.PaymentStatus))
+			}
+		}
+		export.Appointments = append(export.Appointments, appointment)
+	}
+
+	appointmentTable := tableOf[domain.Appointment](r.db)
+	completions := tableOf[domain.ServiceCompletion](r.db).where(func(c *domain.ServiceCompletion) bool {
+		appointment, ok := appointmentTable.rows[c.AppointmentID]
+		return ok && appointment.ClientID == clientID
+	})
+	slices.SortStableFunc(completions, func(a, b *domain.ServiceCompletion) int {
+		return compareOptionalTime(a.CompletionDate, b.CompletionDate)
+	})
+	for _, c := range completions {
+		export.Completions = append(export.Completions, domain.ClientCompletionExport{
+			AppointmentID:  c.AppointmentID,
+			PriceCharged:   c.PriceCharged,
+			PaymentMethod:  c.PaymentMethod,
+			CompletionDate: c.CompletionDate,
+			ActualDuration: c.ActualDuration,
+		})
+	}
+
+	programs := tableOf[domain.LoyaltyProgram](r.db)
+	memberships := tableOf[domain.ClientLoyaltyMembership](r.db).where(func(m *domain.ClientLoyaltyMembership) bool {
+		return m.ClientID == clientID
+	})
+	slices.SortStableFunc(memberships, func(a, b *domain.ClientLoyaltyMembership) int { return a.JoinDate.Compare(b.JoinDate) })
+	for _, m := range memberships {
+		program, ok := programs.rows[m.ProgramID]
+		if !ok {
+			continue
+		}
+		export.LoyaltyMemberships = append(export.LoyaltyMemberships, domain.ClientLoyaltyMembershipExport{
+			ProgramName:   program.Name,
+			CurrentPoints: m.CurrentPoints,
+			VisitsCount:   m.VisitsCount,
+			TotalSpent:    m.TotalSpent,
+			TierLevel:     m.TierLevel,
+			JoinDate:      m.JoinDate,
+			ExpiryDate:    m.ExpiryDate,
+			IsActive:      m.IsActive,
+		})
+	}
+	return export, nil
+}
+
+// serviceNames returns the names of an appointment's services, sorted and comma-separated
+func (r *clientDataRepositoryImpl) serviceNames(appointmentID string) string {
+	services := tableOf[domain.Service](r.db)
+	var names []string
+	for _, line := range tableOf[domain.AppointmentLine](r.db).where(func(l *domain.AppointmentLine) bool {
+		return l.AppointmentID == appointmentID
+	}) {
+		if service, ok := services.rows[line.ServiceID]; ok {
+			names = append(names, service.Name)
+		}
+	}
+	slices.Sort(names)
+	return strings.Join(names, ", ")
+}
+
+// Anonymize removes the personal data of a client. The client row is kept with placeholder
+// names so that appointments and completions stay intact; free text that may describe the
+// client is cleared from the rows around it, and their attachments are deleted.
+func (r *clientDataRepositoryImpl) Anonymize(ctx context.Context, clientID string, at time.Time, by *string) error {
+	defer r.db.lock()()
+	clients := tableOf[domain.Client](r.db)
+	row, err := r.clients.get(ctx, clientID)
+	if err != nil {
+		return err
+	}
+	row.FirstName = domain.AnonymizedFirstName
+	row.LastName = domain.AnonymizedLastName
+	row.Email = ""
+	row.Phone = nil
+	row.DateOfBirth = nil
+	row.Notes = nil
+	row.Allergies = nil
+	row.ReferralSource = nil
+	row.UserID = nil
+	row.GuardianClientID = nil
+	row.GuardianRelationship = nil
+	row.GuardianConsentAt = nil
+	row.IsActive = false
+	row.AnonymizedAt = &at
+	row.UpdatedAt = at
+	row.UpdatedBy = by
+
+	// Minors the client was the guardian of lose the link, not their own data
+	for _, row := range clients.rows {
+		if row.GuardianClientID != nil && *row.GuardianClientID == clientID {
+			row.GuardianClientID = nil
+			row.GuardianRelationship = nil
+			row.UpdatedAt = at
+			row.UpdatedBy = by
+		}
+	}
+
+	appointmentIDs := map[string]bool{}
+	for _, row := range tableOf[domain.Appointment](r.db).rows {
+		if row.ClientID == clientID {
+			appointmentIDs[row.ID] = true
+			row.Notes = nil
+			row.CancellationReason = nil
+		}
+	}
+	for _, row := range tableOf[domain.AppointmentLine](r.db).rows {
+		if appointmentIDs[row.AppointmentID] {
+			row.Notes = nil
+		}
+	}
+	for _, row := range tableOf[domain.ServiceRecord](r.db).rows {
+		if row.ClientID == clientID {
+			row.FieldValues = stringPtr("{}")
+			row.Notes = nil
+		}
+	}
+	for _, row := range tableOf[domain.AppointmentConfirmation](r.db).rows {
+		if row.ClientID == clientID {
+			row.Recipient = ""
+		}
+	}
+	for _, row := range tableOf[domain.CalendarEntry](r.db).rows {
+		if row.ClientID == clientID {
+			row.ClientName = domain.AnonymizedFirstName + " " + domain.AnonymizedLastName
+			row.ClientPhone = nil
+			row.Notes = nil
+		}
+	}
+	for _, row := range tableOf[domain.ClientAttachment](r.db).rows {
+		if row.ClientID == clientID && (row.Status == domain.ClientAttachmentPending || row.Status == domain.ClientAttachmentStored) {
+			row.Status = domain.ClientAttachmentDeleted
+			row.Caption = nil
+			row.MarketingConsentAt = nil
+			row.MarketingConsentBy = nil
+			row.UpdatedAt = at
+			row.UpdatedBy = by
+		}
+	}
+	return nil
+}
+
+// compareOptionalTime orders optional times with missing ones last, as PostgreSQL sorts NULLs
+func compareOptionalTime(a, b *time.Time) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+	return a.Compare(*b)
+}
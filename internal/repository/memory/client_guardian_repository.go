@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// clientGuardianRepositoryImpl implements the ClientGuardianRepository interface
+type clientGuardianRepositoryImpl struct {
+	db *DB
+}
+
+// NewClientGuardianRepository creates a new client guardian repository
+func NewClientGuardianRepository(db *DB) domain.ClientGuardianRepository {
+	return &clientGuardianRepositoryImpl{db: db}
+}
+
+// SetGuardian links the client to a guardian, or unlinks it when guardianID is nil
+func (r *clientGuardianRepositoryImpl) SetGuardian(ctx context.Context, clientID string, guardianID, relationship *string, consentAt *time.Time) error {
+	defer r.db.lock()()
+	now := r.db.now()
+	return found(tableOf[domain.Client](r.db).update(clientID, func(c *domain.Client) {
+		c.GuardianClientID = guardianID
+		c.GuardianRelationship = relationship
+		c.GuardianConsentAt = consentAt
+		c.UpdatedAt = now
+	}))
+}
+
+// SetConsent records when the guardian consented to the client being treated
+func (r *clientGuardianRepositoryImpl) SetConsent(ctx context.Context, clientID string, consentAt time.Time) error {
+	defer r.db.lock()()
+	now := r.db.now()
+	return found(tableOf[domain.Client](r.db).update(clientID, func(c *domain.Client) {
+		c.GuardianConsentAt = &consentAt
+		c.UpdatedAt = now
+	}))
+}
+
+// FindDependents finds the clients a client is the guardian of
+func (r *clientGuardianRepositoryImpl) FindDependents(ctx context.Context, guardianID string) ([]*domain.Client, error) {
+	defer r.db.lock()()
+	clients := tableOf[domain.Client](r.db).where(func(c *domain.Client) bool {
+		return c.GuardianClientID != nil && *c.GuardianClientID == guardianID
+	})
+	slices.SortStableFunc(clients, func(a, b *domain.Client) int {
+		return cmp.Or(cmp.Compare(a.FirstName, b.FirstName), cmp.Compare(a.LastName, b.LastName))
+	})
+	return clients, nil
+}
+
+// FindService finds a service by ID
+func (r *clientGuardianRepositoryImpl) FindService(ctx context.Context, serviceID string) (*domain.Service, error) {
+	defer r.db.lock()()
+	return tableOf[domain.Service](r.db).get(serviceID)
+}
+
+// SetServiceMinAge sets the minimum client age of a service, or clears it when minAge is nil
+func (r *clientGuardianRepositoryImpl) SetServiceMinAge(ctx context.Context, serviceID string, minAge *int) error {
+	defer r.db.lock()()
+	now := r.db.now()
+	return found(tableOf[domain.Service](r.db).update(serviceID, func(s *domain.Service) {
+		s.MinAge = minAge
+		s.UpdatedAt = now
+	}))
+}
+
+// found reports a missing record as not found
+func found(ok bool) error {
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
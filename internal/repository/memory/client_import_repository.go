@@ -0,0 +1,33 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// clientImportRepositoryImpl implements the ClientImportRepository interface
+type clientImportRepositoryImpl struct {
+	db *DB
+}
+
+// NewClientImportRepository creates a new client import repository
+func NewClientImportRepository(db *DB) domain.ClientImportRepository {
+	return &clientImportRepositoryImpl{db: db}
+}
+
+// FindContacts finds the email and phone of every client of a business
+func (r *clientImportRepositoryImpl) FindContacts(ctx context.Context, businessID string) ([]domain.ClientContact, error) {
+	defer r.db.lock()()
+	var contacts []domain.ClientContact
+	for _, client := range tableOf[domain.Client](r.db).where(func(c *domain.Client) bool { return c.BusinessID == businessID }) {
+		contacts = append(contacts, domain.ClientContact{ID: client.ID, Email: &client.Email, Phone: client.Phone})
+	}
+	return contacts, nil
+}
+
+// CreateClients creates the clients all at once, so that a failure leaves none of them behind
+func (r *clientImportRepositoryImpl) CreateClients(ctx context.Context, clients []*domain.Client) error {
+	_, err := NewBaseRepository[domain.Client](r.db).CreateBatch(ctx, clients, domain.BatchOptions{})
+	return err
+}
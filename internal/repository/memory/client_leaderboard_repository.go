@@ -0,0 +1,117 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// clientLeaderboardRepositoryImpl implements the ClientLeaderboardRepository interface
+type clientLeaderboardRepositoryImpl struct {
+	db *DB
+}
+
+// NewClientLeaderboardRepository creates a new client leaderboard repository
+func NewClientLeaderboardRepository(db *DB) domain.ClientLeaderboardRepository {
+	return &clientLeaderboardRepositoryImpl{db: db}
+}
+
+// FindStandings sums up what each active client of the business did in the period, leaving out
+// clients who did nothing
+func (r *clientLeaderboardRepositoryImpl) FindStandings(ctx context.Context, businessID string, period domain.DateRange) ([]*domain.ClientStanding, error) {
+	defer r.db.lock()()
+	clients := map[string]bool{}
+	for _, client := range tableOf[domain.Client](r.db).where(func(c *domain.Client) bool {
+		return c.BusinessID == businessID && c.IsActive && c.AnonymizedAt == nil
+	}) {
+		clients[client.ID] = true
+	}
+
+	standings := map[string]*domain.ClientStanding{}
+	standing := func(clientID string) *domain.ClientStanding {
+		if _, ok := standings[clientID]; !ok {
+			standings[clientID] = &domain.ClientStanding{ClientID: clientID}
+		}
+		return standings[clientID]
+	}
+	completions := tableOf[domain.ServiceCompletion](r.db)
+	for _, appointment := range tableOf[domain.Appointment](r.db).where(func(a *domain.Appointment) bool {
+		return a.BusinessID == businessID && a.Status == domain.AppointmentStatusCompleted && clients[a.ClientID] &&
+			!a.StartTime.Before(period.Start) && a.StartTime.Before(period.End)
+	}) {
+		client := standing(appointment.ClientID)
+		client.Visits++
+		if client.LastVisit == nil || appointment.StartTime.After(*client.LastVisit) {
+			client.LastVisit = &appointment.StartTime
+		}
+		for _, completion := range completions.where(func(c *domain.ServiceCompletion) bool { return c.AppointmentID == appointment.ID }) {
+			client.Spent = client.Spent.Add(completion.PriceCharged)
+		}
+	}
+
+	memberships := map[string]string{}
+	for _, membership := range tableOf[domain.ClientLoyaltyMembership](r.db).where(func(m *domain.ClientLoyaltyMembership) bool {
+		return clients[m.ClientID]
+	}) {
+		memberships[membership.ID] = membership.ClientID
+	}
+	earned := []domain.LoyaltyTransactionType{domain.LoyaltyTransactionEarn, domain.LoyaltyTransactionReferral}
+	for _, transaction := range tableOf[domain.LoyaltyTransaction](r.db).where(func(t *domain.LoyaltyTransaction) bool {
+		return memberships[t.MembershipID] != "" && slices.Contains(earned, t.TransactionType) &&
+			!t.CreatedAt.Before(period.Start) && t.CreatedAt.Before(period.End)
+	}) {
+		standing(memberships[transaction.MembershipID]).Points += transaction.Points
+	}
+
+	result := make([]*domain.ClientStanding, 0, len(standings))
+	for _, standing := range standings {
+		result = append(result, standing)
+	}
+	return result, nil
+}
+
+// FindBusinessesWithVIPRules finds the businesses whose VIP rules are enabled
+func (r *clientLeaderboardRepositoryImpl) FindBusinessesWithVIPRules(ctx context.Context) ([]string, error) {
+	defer r.db.lock()()
+	var businessIDs []string
+	for _, settings := range tableOf[domain.BusinessSettings](r.db).where(nil) {
+		if rules, err := settings.GetVIPRules(); err == nil && rules.Enabled {
+			businessIDs = append(businessIDs, settings.BusinessID)
+		}
+	}
+	return businessIDs, nil
+}
+
+// SetVIPClients gives VIPTag to exactly the given clients of the business, taking it from the
+// others. Clients with MaxClientTags tags already are not tagged.
+func (r *clientLeaderboardRepositoryImpl) SetVIPClients(ctx context.Context, businessID string, clientIDs []string) (int64, int64, error) {
+	defer r.db.lock()()
+	now := r.db.now()
+	var tagged, untagged int64
+	clients := tableOf[domain.Client](r.db)
+	for _, client := range clients.where(func(c *domain.Client) bool { return c.BusinessID == businessID }) {
+		tags, err := client.GetTags()
+		if err != nil {
+			return tagged, untagged, err
+		}
+		vip, has := slices.Contains(clientIDs, client.ID), slices.Contains(tags, domain.VIPTag)
+		switch {
+		case vip && !has && len(tags) < domain.MaxClientTags:
+			tags = append(tags, domain.VIPTag)
+			tagged++
+		case !vip && has:
+			tags = slices.DeleteFunc(tags, func(tag string) bool { return tag == domain.VIPTag })
+			untagged++
+		default:
+			continue
+		}
+		if err := client.SetTags(tags); err != nil {
+			return tagged, untagged, err
+		}
+		client.UpdatedAt = now
+		client.Version++
+		clients.update(client.ID, func(c *domain.Client) { *c = *client })
+	}
+	return tagged, untagged, nil
+}
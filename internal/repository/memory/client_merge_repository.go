@@ -0,0 +1,144 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// clientMergeRepositoryImpl implements the ClientMergeRepository interface. Campaign messages
+// and the waiting list have no domain model and are not held in memory, so they are left alone.
+type clientMergeRepositoryImpl struct {
+	db *DB
+}
+
+// NewClientMergeRepository creates a new client merge repository
+func NewClientMergeRepository(db *DB) domain.ClientMergeRepository {
+	return &clientMergeRepositoryImpl{db: db}
+}
+
+// FindCandidates finds the clients of a business that are not anonymized
+func (r *clientMergeRepositoryImpl) FindCandidates(ctx context.Context, businessID string) ([]*domain.Client, error) {
+	defer r.db.lock()()
+	return tableOf[domain.Client](r.db).where(func(c *domain.Client) bool {
+		return c.BusinessID == businessID && c.AnonymizedAt == nil
+	}), nil
+}
+
+// Merge stores the client, which has absorbed the duplicate, and moves everything held about the
+// duplicate to it, then deletes the duplicate. It returns how many rows were moved.
+func (r *clientMergeRepositoryImpl) Merge(ctx context.Context, client *domain.Client, duplicateID string, at time.Time, by *string) (int64, error) {
+	defer r.db.lock()()
+	clients := tableOf[domain.Client](r.db)
+	if _, err := clients.get(duplicateID); err != nil {
+		return 0, err
+	}
+	stored, err := clients.get(client.ID)
+	if err != nil {
+		return 0, err
+	}
+	if client.Version != 0 && client.Version != stored.Version {
+		return 0, &domain.ConflictError{EntityType: "clients", ID: client.ID, Version: client.Version}
+	}
+	memberships := tableOf[domain.ClientMembership](r.db)
+	current := func(m *domain.ClientMembership) bool { return m.Status != domain.MembershipCancelled }
+	for _, membership := range memberships.where(func(m *domain.ClientMembership) bool { return m.ClientID == duplicateID && current(m) }) {
+		if memberships.count(func(m *domain.ClientMembership) bool {
+			return m.ClientID == client.ID && m.PlanID == membership.PlanID && current(m)
+		}) > 0 {
+			return 0, domain.ErrClientMembershipConflict
+		}
+	}
+
+	// Loyalty memberships of a program both clients belong to are folded into the kept one
+	loyalty := tableOf[domain.ClientLoyaltyMembership](r.db)
+	for _, folded := range loyalty.where(func(m *domain.ClientLoyaltyMembership) bool { return m.ClientID == duplicateID }) {
+		kept, err := loyalty.first(func(m *domain.ClientLoyaltyMembership) bool {
+			return m.ClientID == client.ID && m.ProgramID == folded.ProgramID
+		})
+		if err != nil {
+			continue
+		}
+		loyalty.update(kept.ID, func(m *domain.ClientLoyaltyMembership) {
+			m.CurrentPoints += folded.CurrentPoints
+			m.VisitsCount += folded.VisitsCount
+			m.TotalSpent = m.TotalSpent.Add(folded.TotalSpent)
+			if folded.JoinDate.Before(m.JoinDate) {
+				m.JoinDate = folded.JoinDate
+			}
+			m.UpdatedAt, m.UpdatedBy = at, by
+		})
+		tableOf[domain.LoyaltyTransaction](r.db).updateWhere(func(t *domain.LoyaltyTransaction) bool { return t.MembershipID == folded.ID },
+			func(t *domain.LoyaltyTransaction) { t.MembershipID = kept.ID })
+		loyalty.update(folded.ID, func(m *domain.ClientLoyaltyMembership) {
+			m.DeletedAt, m.DeletedBy = gorm.DeletedAt{Time: at, Valid: true}, by
+		})
+	}
+
+	campaigns := tableOf[domain.CampaignClient](r.db)
+	campaigns.purgeWhere(func(d *domain.CampaignClient) bool {
+		return d.ClientID == duplicateID && campaigns.count(func(s *domain.CampaignClient) bool {
+			return s.ClientID == client.ID && s.CampaignID == d.CampaignID
+		}) > 0
+	})
+	referrals := tableOf[domain.Referral](r.db)
+	referrals.purgeWhere(func(d *domain.Referral) bool {
+		return (d.ReferrerClientID == duplicateID && d.ReferredClientID == client.ID) ||
+			(d.ReferrerClientID == client.ID && d.ReferredClientID == duplicateID)
+	})
+	referrals.purgeWhere(func(d *domain.Referral) bool {
+		return d.ReferredClientID == duplicateID && referrals.count(func(s *domain.Referral) bool {
+			return s.ReferredClientID == client.ID && s.BusinessID == d.BusinessID
+		}) > 0
+	})
+
+	moved := moveClientRows(r.db, duplicateID, client.ID, func(a *domain.Appointment) *string { return &a.ClientID }) +
+		moveClientRows(r.db, duplicateID, client.ID, func(c *domain.AppointmentConfirmation) *string { return &c.ClientID }) +
+		moveClientRows(r.db, duplicateID, client.ID, func(q *domain.AppointmentQuote) *string { return &q.ClientID }) +
+		moveClientRows(r.db, duplicateID, client.ID, func(b *domain.BroadcastRecipient) *string { return &b.ClientID }) +
+		moveClientRows(r.db, duplicateID, client.ID, func(c *domain.CalendarEntry) *string { return &c.ClientID }) +
+		moveClientRows(r.db, duplicateID, client.ID, func(c *domain.CampaignClient) *string { return &c.ClientID }) +
+		moveClientRows(r.db, duplicateID, client.ID, func(a *domain.ClientAttachment) *string { return &a.ClientID }) +
+		moveClientRows(r.db, duplicateID, client.ID, func(m *domain.ClientLoyaltyMembership) *string { return &m.ClientID }) +
+		moveClientRows(r.db, duplicateID, client.ID, func(m *domain.ClientMembership) *string { return &m.ClientID }) +
+		moveClientRows(r.db, duplicateID, client.ID, func(i *domain.Invoice) *string { return &i.ClientID }) +
+		moveClientRows(r.db, duplicateID, client.ID, func(n *domain.MembershipCreditNote) *string { return &n.ClientID }) +
+		moveClientRows(r.db, duplicateID, client.ID, func(i *domain.MembershipInvoice) *string { return &i.ClientID }) +
+		moveClientRows(r.db, duplicateID, client.ID, func(rv *domain.Review) *string { return &rv.ClientID }) +
+		moveClientRows(r.db, duplicateID, client.ID, func(sr *domain.ServiceRecord) *string { return &sr.ClientID }) +
+		moveClientRows(r.db, duplicateID, client.ID, func(rf *domain.Referral) *string { return &rf.ReferrerClientID }) +
+		moveClientRows(r.db, duplicateID, client.ID, func(rf *domain.Referral) *string { return &rf.ReferredClientID }) +
+		moveOptionalClientRows(r.db, duplicateID, client.ID, func(t *domain.FrontDeskTask) **string { return &t.ClientID }) +
+		moveOptionalClientRows(r.db, duplicateID, client.ID, func(g *domain.GiftCard) **string { return &g.PurchaserClientID })
+	moved += clients.updateWhere(func(c *domain.Client) bool {
+		return c.GuardianClientID != nil && *c.GuardianClientID == duplicateID && c.ID != client.ID
+	}, func(c *domain.Client) {
+		c.GuardianClientID = &client.ID
+		c.UpdatedAt, c.UpdatedBy = at, by
+	})
+
+	clients.update(duplicateID, func(c *domain.Client) {
+		c.MergedIntoID = &client.ID
+		c.IsActive = false
+		c.UpdatedAt, c.UpdatedBy = at, by
+		c.DeletedAt, c.DeletedBy = gorm.DeletedAt{Time: at, Valid: true}, by
+	})
+	client.Version = stored.Version + 1
+	client.UpdatedAt = at
+	return moved, clients.save(client)
+}
+
+// moveClientRows points the rows of a table held by the duplicate at the kept client
+func moveClientRows[T any](db *DB, duplicateID, clientID string, clientOf func(row *T) *string) int64 {
+	return tableOf[T](db).updateWhere(func(row *T) bool { return *clientOf(row) == duplicateID },
+		func(row *T) { *clientOf(row) = clientID })
+}
+
+// moveOptionalClientRows points the rows of a table held by the duplicate at the kept client,
+// for tables whose client is optional
+func moveOptionalClientRows[T any](db *DB, duplicateID, clientID string, clientOf func(row *T) **string) int64 {
+	return tableOf[T](db).updateWhere(func(row *T) bool { return *clientOf(row) != nil && **clientOf(row) == duplicateID },
+		func(row *T) { *clientOf(row) = &clientID })
+}
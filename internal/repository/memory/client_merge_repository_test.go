@@ -0,0 +1,104 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestClientMergeRepository_MovesEverythingToTheKeptClient(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	repo := NewClientMergeRepository(db)
+	clients := NewBaseRepository[domain.Client](db)
+
+	day := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	business := &domain.Business{Name: "Studio"}
+	require.NoError(t, Insert(db, business))
+	client := &domain.Client{BusinessID: business.ID, FirstName: "Rita", IsActive: true}
+	duplicate := &domain.Client{BusinessID: business.ID, FirstName: "Rita", IsActive: true}
+	require.NoError(t, Insert(db, client, duplicate))
+	minor := &domain.Client{BusinessID: business.ID, FirstName: "Maria", GuardianClientID: &duplicate.ID}
+	require.NoError(t, Insert(db, minor))
+	appointment := &domain.Appointment{BusinessID: business.ID, ClientID: duplicate.ID}
+	require.NoError(t, Insert(db, appointment))
+
+	program := &domain.LoyaltyProgram{BusinessID: business.ID, Name: "Stamps"}
+	other := &domain.LoyaltyProgram{BusinessID: business.ID, Name: "Referrals"}
+	require.NoError(t, Insert(db, program, other))
+	kept := &domain.ClientLoyaltyMembership{ProgramID: program.ID, ClientID: client.ID, CurrentPoints: 10, VisitsCount: 1,
+		TotalSpent: decimal.NewFromInt(30), JoinDate: day}
+	folded := &domain.ClientLoyaltyMembership{ProgramID: program.ID, ClientID: duplicate.ID, CurrentPoints: 25, VisitsCount: 2,
+		TotalSpent: decimal.NewFromInt(50), JoinDate: day.AddDate(0, -1, 0)}
+	moved := &domain.ClientLoyaltyMembership{ProgramID: other.ID, ClientID: duplicate.ID, JoinDate: day}
+	require.NoError(t, Insert(db, kept, folded, moved))
+	transaction := &domain.LoyaltyTransaction{MembershipID: folded.ID, TransactionType: domain.LoyaltyTransactionEarn, Points: 25}
+	require.NoError(t, Insert(db, transaction))
+
+	campaign := &domain.Campaign{BusinessID: business.ID, Name: "Spring"}
+	require.NoError(t, Insert(db, campaign))
+	require.NoError(t, Insert(db, &domain.CampaignClient{CampaignID: campaign.ID, ClientID: client.ID},
+		&domain.CampaignClient{CampaignID: campaign.ID, ClientID: duplicate.ID}))
+
+	stored, err := clients.GetByID(ctx, client.ID)
+	require.NoError(t, err)
+	stored.Absorb(duplicate)
+	count, err := repo.Merge(ctx, stored, duplicate.ID, day, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count, "the appointment, the loyalty membership of the other program and the guardian link")
+
+	_, err = clients.GetByID(ctx, duplicate.ID)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound, "the duplicate is deleted")
+	appointments, err := NewBaseRepository[domain.Appointment](db).FindBy(ctx, map[string]any{"client_id": client.ID})
+	require.NoError(t, err)
+	assert.Len(t, appointments, 1)
+	children, err := clients.FindBy(ctx, map[string]any{"guardian_client_id": client.ID})
+	require.NoError(t, err)
+	assert.Len(t, children, 1)
+
+	memberships, err := NewBaseRepository[domain.ClientLoyaltyMembership](db).FindBy(ctx, map[string]any{"client_id": client.ID})
+	require.NoError(t, err)
+	require.Len(t, memberships, 2)
+	for _, membership := range memberships {
+		if membership.ID == kept.ID {
+			assert.Equal(t, 35, membership.CurrentPoints)
+			assert.Equal(t, 3, membership.VisitsCount)
+			assert.True(t, decimal.NewFromInt(80).Equal(membership.TotalSpent))
+			assert.Equal(t, folded.JoinDate, membership.JoinDate)
+		}
+	}
+	transactions, err := NewBaseRepository[domain.LoyaltyTransaction](db).FindBy(ctx, map[string]any{"membership_id": kept.ID})
+	require.NoError(t, err)
+	assert.Len(t, transactions, 1, "the points history follows the folded membership")
+	recipients, err := NewBaseRepository[domain.CampaignClient](db).FindBy(ctx, map[string]any{"campaign_id": campaign.ID})
+	require.NoError(t, err)
+	assert.Len(t, recipients, 1, "a client is a campaign recipient once")
+}
+
+func TestClientMergeRepository_RefusesClientsSharingAMembershipPlan(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	repo := NewClientMergeRepository(db)
+
+	business := &domain.Business{Name: "Studio"}
+	require.NoError(t, Insert(db, business))
+	client := &domain.Client{BusinessID: business.ID, FirstName: "Rita"}
+	duplicate := &domain.Client{BusinessID: business.ID, FirstName: "Rita"}
+	require.NoError(t, Insert(db, client, duplicate))
+	plan := &domain.MembershipPlan{BusinessID: business.ID, Name: "Monthly blow-dry"}
+	require.NoError(t, Insert(db, plan))
+	require.NoError(t, Insert(db,
+		&domain.ClientMembership{BusinessID: business.ID, PlanID: plan.ID, ClientID: client.ID, Status: domain.MembershipActive},
+		&domain.ClientMembership{BusinessID: business.ID, PlanID: plan.ID, ClientID: duplicate.ID, Status: domain.MembershipPastDue}))
+
+	_, err := repo.Merge(ctx, client, duplicate.ID, time.Now(), nil)
+	assert.ErrorIs(t, err, domain.ErrClientMembershipConflict)
+	_, err = NewBaseRepository[domain.Client](db).GetByID(ctx, duplicate.ID)
+	assert.NoError(t, err, "nothing is changed")
+}
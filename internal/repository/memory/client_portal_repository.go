@@ -0,0 +1,143 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// clientPortalRepositoryImpl implements the ClientPortalRepository interface
+type clientPortalRepositoryImpl struct {
+	db *DB
+}
+
+// NewClientPortalRepository creates a new client portal repository
+func NewClientPortalRepository(db *DB) domain.ClientPortalRepository {
+	return &clientPortalRepositoryImpl{db: db}
+}
+
+// FindClientIDsByUserID finds the client records linked to a user account, one per business,
+// along with the minors the user is the guardian of
+func (r *clientPortalRepositoryImpl) FindClientIDsByUserID(ctx context.Context, userID string) ([]string, error) {
+	defer r.db.lock()()
+	clients := tableOf[domain.Client](r.db)
+	linked := func(c *domain.Client) bool { return c.UserID != nil && *c.UserID == userID }
+
+	clientIDs := []string{}
+	for _, client := range clients.where(func(c *domain.Client) bool {
+		if linked(c) {
+			return true
+		}
+		if c.GuardianClientID == nil {
+			return false
+		}
+		guardian, err := clients.get(*c.GuardianClientID)
+		return err == nil && linked(guardian)
+	}) {
+		clientIDs = append(clientIDs, client.ID)
+	}
+	return clientIDs, nil
+}
+
+// FindPastVisits finds the clients' appointments that have already started, most recent first
+func (r *clientPortalRepositoryImpl) FindPastVisits(ctx context.Context, clientIDs []string, page, pageSize int) ([]*domain.PortalVisit, int64, error) {
+	defer r.db.lock()()
+	now := r.db.now()
+	appointments := tableOf[domain.Appointment](r.db).where(func(a *domain.Appointment) bool {
+		return slices.Contains(clientIDs, a.ClientID) && a.StartTime.Before(now)
+	})
+	sortByStart(appointments)
+	slices.Reverse(appointments)
+
+	visits := []*domain.PortalVisit{}
+	for _, a := range paginate(appointments, page, pageSize) {
+		if visit, ok := r.visit(a); ok {
+			visits = append(visits, visit)
+		}
+	}
+	return visits, int64(len(appointments)), nil
+}
+
+// FindVisit finds a single appointment of the clients
+func (r *clientPortalRepositoryImpl) FindVisit(ctx context.Context, clientIDs []string, appointmentID string) (*domain.PortalVisit, error) {
+	defer r.db.lock()()
+	appointment, err := tableOf[domain.Appointment](r.db).get(appointmentID)
+	if err != nil || !slices.Contains(clientIDs, appointment.ClientID) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	visit, ok := r.visit(appointment)
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return visit, nil
+}
+
+// visit builds the portal view of an appointment with its payment, invoice and services. It
+// returns false when the business, client, staff member or their user account is missing, as
+// the SQL join would.
+func (r *clientPortalRepositoryImpl) visit(a *domain.Appointment) (*domain.PortalVisit, bool) {
+	business, err := tableOf[domain.Business](r.db).get(a.BusinessID)
+	if err != nil {
+		return nil, false
+	}
+	client, err := tableOf[domain.Client](r.db).get(a.ClientID)
+	if err != nil {
+		return nil, false
+	}
+	user, ok := staffUser(r.db, a.StaffID)
+	if !ok {
+		return nil, false
+	}
+
+	visit := &domain.PortalVisit{
+		AppointmentID: a.ID,
+		BusinessID:    a.BusinessID,
+		BusinessName:  business.Name,
+		BusinessTaxID: business.TaxID,
+		Currency:      business.Currency,
+		ClientID:      a.ClientID,
+		ClientName:    client.FirstName + " " + client.LastName,
+		StaffName:     user.FirstName + " " + user.LastName,
+		StartTime:     a.StartTime,
+		EndTime:       a.EndTime,
+		Status:        a.Status,
+		Services:      []domain.PortalVisitService{},
+	}
+	if visit.Currency == "" {
+		visit.Currency = "EUR"
+	}
+
+	completions := tableOf[domain.ServiceCompletion](r.db).where(func(c *domain.ServiceCompletion) bool {
+		return c.AppointmentID == a.ID
+	})
+	if len(completions) > 0 {
+		latest := completions[len(completions)-1]
+		visit.AmountPaid = &latest.PriceCharged
+		visit.PaymentMethod = &latest.PaymentMethod
+	}
+	for _, transaction := range tableOf[domain.LoyaltyTransaction](r.db).where(func(t *domain.LoyaltyTransaction) bool {
+		return t.AppointmentID != nil && *t.AppointmentID == a.ID && t.TransactionType == domain.LoyaltyTransactionEarn
+	}) {
+		visit.LoyaltyPoints += transaction.Points
+	}
+	if invoice, err := tableOf[domain.Invoice](r.db).first(func(i *domain.Invoice) bool { return i.AppointmentID == a.ID }); err == nil {
+		visit.InvoiceID = &invoice.ID
+	}
+
+	services := tableOf[domain.Service](r.db)
+	for _, line := range tableOf[domain.AppointmentLine](r.db).where(func(l *domain.AppointmentLine) bool {
+		return l.AppointmentID == a.ID
+	}) {
+		if service, err := services.get(line.ServiceID); err == nil {
+			visit.Services = append(visit.Services, domain.PortalVisitService{
+				AppointmentID: a.ID,
+				Name:          service.Name,
+				Duration:      line.Duration,
+				Price:         line.Price,
+			})
+		}
+	}
+	return visit, true
+}
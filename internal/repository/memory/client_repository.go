@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// clientRepositoryImpl implements the ClientRepository interface
+type clientRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.Client]
+}
+
+// NewClientRepository creates a new client repository
+func NewClientRepository(db *DB) domain.ClientRepository {
+	return &clientRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.Client]{db: db},
+	}
+}
+
+// FindByBusinessID finds all clients of a business
+func (r *clientRepositoryImpl) FindByBusinessID(ctx context.Context, businessID string) ([]*domain.Client, error) {
+	defer r.db.lock()()
+	return r.table().where(func(c *domain.Client) bool { return c.BusinessID == businessID }), nil
+}
+
+// FindByEmail finds a client by email address
+func (r *clientRepositoryImpl) FindByEmail(ctx context.Context, email string) (*domain.Client, error) {
+	defer r.db.lock()()
+	return r.table().first(func(c *domain.Client) bool { return strings.EqualFold(c.Email, email) })
+}
+
+// FindByUserID finds the client records linked to a user account
+func (r *clientRepositoryImpl) FindByUserID(ctx context.Context, userID string) ([]*domain.Client, error) {
+	defer r.db.lock()()
+	return r.table().where(func(c *domain.Client) bool { return c.UserID != nil && *c.UserID == userID }), nil
+}
+
+// FindByBusinessAndEmail finds a business's client by email address
+func (r *clientRepositoryImpl) FindByBusinessAndEmail(ctx context.Context, businessID, email string) (*domain.Client, error) {
+	defer r.db.lock()()
+	return r.table().first(func(c *domain.Client) bool {
+		return c.BusinessID == businessID && strings.EqualFold(c.Email, email)
+	})
+}
+
+// ExistsByEmailAndBusiness checks whether the business has a client with the email address
+func (r *clientRepositoryImpl) ExistsByEmailAndBusiness(ctx context.Context, email, businessID string) (bool, error) {
+	defer r.db.lock()()
+	count := r.table().count(func(c *domain.Client) bool {
+		return c.BusinessID == businessID && strings.EqualFold(c.Email, email)
+	})
+	return count > 0, nil
+}
+
+// UpdateVisitStats records a visit and the amount spent on it
+func (r *clientRepositoryImpl) UpdateVisitStats(ctx context.Context, clientID string, visitTime time.Time, amount decimal.Decimal) error {
+	defer r.db.lock()()
+	now := r.db.now()
+	return found(r.table().update(clientID, func(c *domain.Client) {
+		c.UpdateVisitStats(visitTime, amount)
+		c.UpdatedAt = now
+	}))
+}
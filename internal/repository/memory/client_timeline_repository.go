@@ -0,0 +1,139 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// clientTimelineRepositoryImpl implements the ClientTimelineRepository interface. Campaign
+// messages have no domain model and are not held in memory, so they never show in the timeline.
+type clientTimelineRepositoryImpl struct {
+	db *DB
+}
+
+// NewClientTimelineRepository creates a new client timeline repository
+func NewClientTimelineRepository(db *DB) domain.ClientTimelineRepository {
+	return &clientTimelineRepositoryImpl{db: db}
+}
+
+// FindEntries finds up to limit entries of a client's timeline following the cursor, newest
+// first. The boolean reports whether more entries follow.
+func (r *clientTimelineRepositoryImpl) FindEntries(ctx context.Context, clientID string, after *domain.Cursor, limit int) ([]*domain.TimelineEntry, bool, error) {
+	defer r.db.lock()()
+	var entries []*domain.TimelineEntry
+
+	appointments := map[string]*domain.Appointment{}
+	for _, appointment := range tableOf[domain.Appointment](r.db).where(func(a *domain.Appointment) bool { return a.ClientID == clientID }) {
+		appointments[appointment.ID] = appointment
+		entries = append(entries, &domain.TimelineEntry{
+			ID:            appointment.ID,
+			Kind:          domain.TimelineAppointment,
+			OccurredAt:    appointment.CreatedAt,
+			AppointmentID: &appointment.ID,
+			Status:        stringPtr(string(appointment.Status)),
+			Description:   appointment.Notes,
+			StartTime:     &appointment.StartTime,
+		})
+	}
+
+	for _, completion := range tableOf[domain.ServiceCompletion](r.db).where(func(c *domain.ServiceCompletion) bool {
+		return appointments[c.AppointmentID] != nil
+	}) {
+		entry := &domain.TimelineEntry{
+			ID:            completion.ID,
+			Kind:          domain.TimelineCompletion,
+			OccurredAt:    completion.CreatedAt,
+			AppointmentID: &completion.AppointmentID,
+			Status:        &completion.PaymentMethod,
+			Amount:        &completion.PriceCharged,
+		}
+		if completion.CompletionDate != nil {
+			entry.OccurredAt = *completion.CompletionDate
+		}
+		if business, err := tableOf[domain.Business](r.db).get(appointments[completion.AppointmentID].BusinessID); err == nil {
+			entry.Currency = &business.Currency
+		}
+		entries = append(entries, entry)
+	}
+
+	for _, payment := range tableOf[domain.Payment](r.db).where(func(p *domain.Payment) bool {
+		return appointments[p.AppointmentID] != nil
+	}) {
+		entry := &domain.TimelineEntry{
+			ID:            payment.ID,
+			Kind:          domain.TimelinePayment,
+			OccurredAt:    payment.CreatedAt,
+			AppointmentID: &payment.AppointmentID,
+			Status:        stringPtr(string(payment.Status)),
+			Title:         stringPtr(string(payment.Kind)),
+			Description:   payment.FailureReason,
+			Amount:        &payment.Amount,
+			Currency:      &payment.Currency,
+		}
+		if payment.PaidAt != nil {
+			entry.OccurredAt = *payment.PaidAt
+		}
+		entries = append(entries, entry)
+	}
+
+	programs := map[string]string{}
+	for _, membership := range tableOf[domain.ClientLoyaltyMembership](r.db).where(func(m *domain.ClientLoyaltyMembership) bool {
+		return m.ClientID == clientID
+	}) {
+		programs[membership.ID] = ""
+		if program, err := tableOf[domain.LoyaltyProgram](r.db).get(membership.ProgramID); err == nil {
+			programs[membership.ID] = program.Name
+		}
+	}
+	for _, transaction := range tableOf[domain.LoyaltyTransaction](r.db).where(func(t *domain.LoyaltyTransaction) bool {
+		_, ok := programs[t.MembershipID]
+		return ok
+	}) {
+		entries = append(entries, &domain.TimelineEntry{
+			ID:            transaction.ID,
+			Kind:          domain.TimelineLoyalty,
+			OccurredAt:    transaction.CreatedAt,
+			AppointmentID: transaction.AppointmentID,
+			Status:        stringPtr(string(transaction.TransactionType)),
+			Title:         stringPtr(programs[transaction.MembershipID]),
+			Description:   transaction.Description,
+			Points:        &transaction.Points,
+		})
+	}
+
+	for _, record := range tableOf[domain.ServiceRecord](r.db).where(func(sr *domain.ServiceRecord) bool { return sr.ClientID == clientID }) {
+		entries = append(entries, &domain.TimelineEntry{
+			ID:            record.ID,
+			Kind:          domain.TimelineNote,
+			OccurredAt:    record.RecordedAt,
+			AppointmentID: record.AppointmentID,
+			Description:   record.Notes,
+		})
+	}
+
+	for _, review := range tableOf[domain.Review](r.db).where(func(rv *domain.Review) bool { return rv.ClientID == clientID }) {
+		entries = append(entries, &domain.TimelineEntry{
+			ID:            review.ID,
+			Kind:          domain.TimelineReview,
+			OccurredAt:    review.CreatedAt,
+			AppointmentID: &review.AppointmentID,
+			Status:        stringPtr(string(review.Status)),
+			Description:   review.Comment,
+			Rating:        &review.Rating,
+		})
+	}
+
+	if after != nil {
+		entries = slices.DeleteFunc(entries, func(e *domain.TimelineEntry) bool { return !e.Before(*after) })
+	}
+	slices.SortFunc(entries, func(a, b *domain.TimelineEntry) int {
+		return cmp.Or(b.OccurredAt.Compare(a.OccurredAt), cmp.Compare(b.ID, a.ID))
+	})
+	if len(entries) > limit {
+		return entries[:limit], true, nil
+	}
+	return entries, false, nil
+}
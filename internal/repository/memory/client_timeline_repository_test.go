@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientTimelineRepository_MergesAndPagesNewestFirst(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	repo := NewClientTimelineRepository(db)
+
+	day := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	at := func(hours int) domain.BaseModel {
+		return domain.BaseModel{CreatedAt: day.Add(time.Duration(hours) * time.Hour)}
+	}
+	business := &domain.Business{Name: "Studio", Currency: "EUR"}
+	require.NoError(t, Insert(db, business))
+	client := &domain.Client{BusinessID: business.ID, FirstName: "Rita"}
+	other := &domain.Client{BusinessID: business.ID, FirstName: "Joana"}
+	require.NoError(t, Insert(db, client, other))
+	visit := &domain.Appointment{BaseModel: at(0), BusinessID: business.ID, ClientID: client.ID, StartTime: day.Add(48 * time.Hour),
+		Status: domain.AppointmentStatusCompleted}
+	require.NoError(t, Insert(db, visit, &domain.Appointment{BusinessID: business.ID, ClientID: other.ID}))
+	completedAt := day.Add(49 * time.Hour)
+	require.NoError(t, Insert(db, &domain.ServiceCompletion{BaseModel: at(1), AppointmentID: visit.ID, PriceCharged: decimal.NewFromInt(40),
+		PaymentMethod: "cash", CompletionDate: &completedAt}))
+	require.NoError(t, Insert(db, &domain.Payment{BaseModel: at(2), BusinessID: business.ID, AppointmentID: visit.ID,
+		Kind: domain.PaymentKindDeposit, Status: domain.PaymentStatusSucceeded, Amount: decimal.NewFromInt(10), Currency: "EUR"}))
+	program := &domain.LoyaltyProgram{BusinessID: business.ID, Name: "Stamps"}
+	require.NoError(t, Insert(db, program))
+	membership := &domain.ClientLoyaltyMembership{ProgramID: program.ID, ClientID: client.ID}
+	require.NoError(t, Insert(db, membership))
+	require.NoError(t, Insert(db, &domain.LoyaltyTransaction{BaseModel: at(50), MembershipID: membership.ID, AppointmentID: &visit.ID,
+		TransactionType: domain.LoyaltyTransactionEarn, Points: 40}))
+	notes := "Colour 6.1"
+	require.NoError(t, Insert(db, &domain.ServiceRecord{BusinessID: business.ID, ClientID: client.ID, AppointmentID: &visit.ID,
+		RecordedAt: day.Add(51 * time.Hour), Notes: &notes}))
+	require.NoError(t, Insert(db, &domain.Review{BaseModel: at(52), BusinessID: business.ID, AppointmentID: visit.ID, ClientID: client.ID,
+		Rating: 5, Status: domain.ReviewPublished}))
+
+	entries, more, err := repo.FindEntries(ctx, client.ID, nil, 4)
+	require.NoError(t, err)
+	assert.True(t, more)
+	kinds := []domain.TimelineEntryKind{}
+	for _, entry := range entries {
+		kinds = append(kinds, entry.Kind)
+	}
+	assert.Equal(t, []domain.TimelineEntryKind{domain.TimelineReview, domain.TimelineNote, domain.TimelineLoyalty, domain.TimelineCompletion}, kinds)
+	assert.Equal(t, 5, *entries[0].Rating)
+	assert.Equal(t, "Stamps", *entries[2].Title)
+	assert.Equal(t, 40, *entries[2].Points)
+	assert.Equal(t, completedAt, entries[3].OccurredAt, "completions occur when the appointment was completed")
+	assert.Equal(t, "EUR", *entries[3].Currency)
+
+	cursor := entries[3].Cursor()
+	entries, more, err = repo.FindEntries(ctx, client.ID, &cursor, 4)
+	require.NoError(t, err)
+	assert.False(t, more)
+	require.Len(t, entries, 2)
+	assert.Equal(t, domain.TimelinePayment, entries[0].Kind)
+	assert.Equal(t, domain.TimelineAppointment, entries[1].Kind)
+	assert.Equal(t, visit.StartTime, *entries[1].StartTime)
+}
@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// staffCommissionPlanRepositoryImpl implements the StaffCommissionPlanRepository interface.
+// Inventory transactions are not held in memory, so only service revenue is found.
+type staffCommissionPlanRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.StaffCommissionPlan]
+}
+
+// NewStaffCommissionPlanRepository creates a new staff commission plan repository, scoped to the
+// tenant in the context
+func NewStaffCommissionPlanRepository(db *DB) domain.StaffCommissionPlanRepository {
+	return &staffCommissionPlanRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.StaffCommissionPlan]{db: db, tenantScoped: true},
+	}
+}
+
+// FindByStaffID finds the commission plan history of a staff member, most recent first
+func (r *staffCommissionPlanRepositoryImpl) FindByStaffID(ctx context.Context, staffID string) ([]*domain.StaffCommissionPlan, error) {
+	defer r.db.lock()()
+	plans := r.table().where(func(p *domain.StaffCommissionPlan) bool { return p.StaffID == staffID })
+	sortByEffectiveFrom(plans)
+	slices.Reverse(plans)
+	return plans, nil
+}
+
+// FindEffective finds the commission plan that applies to a staff member at a point in time
+func (r *staffCommissionPlanRepositoryImpl) FindEffective(ctx context.Context, staffID string, at time.Time) (*domain.StaffCommissionPlan, error) {
+	defer r.db.lock()()
+	plans := r.table().where(func(p *domain.StaffCommissionPlan) bool {
+		return p.StaffID == staffID && !p.EffectiveFrom.After(at) && (p.EffectiveTo == nil || p.EffectiveTo.After(at))
+	})
+	if len(plans) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	sortByEffectiveFrom(plans)
+	return plans[len(plans)-1], nil
+}
+
+// FindInPeriod finds the commission plans that apply at any point within a period
+func (r *staffCommissionPlanRepositoryImpl) FindInPeriod(ctx context.Context, staffID string, start, end time.Time) ([]*domain.StaffCommissionPlan, error) {
+	defer r.db.lock()()
+	plans := r.table().where(func(p *domain.StaffCommissionPlan) bool {
+		return p.StaffID == staffID && p.EffectiveFrom.Before(end) && (p.EffectiveTo == nil || p.EffectiveTo.After(start))
+	})
+	sortByEffectiveFrom(plans)
+	return plans, nil
+}
+
+// FindRevenueLines finds the commissionable service revenue attributed to a staff member within
+// a period. Services count once their appointment is completed.
+func (r *staffCommissionPlanRepositoryImpl) FindRevenueLines(ctx context.Context, staffID string, start, end time.Time) ([]domain.CommissionRevenueLine, error) {
+	defer r.db.lock()()
+	appointments := tableOf[domain.Appointment](r.db)
+	var lines []domain.CommissionRevenueLine
+	for _, line := range tableOf[domain.AppointmentLine](r.db).where(func(l *domain.AppointmentLine) bool {
+		return l.StaffID == staffID
+	}) {
+		appointment, err := appointments.get(line.AppointmentID)
+		if err != nil || appointment.Status != domain.AppointmentStatusCompleted || !inRange(appointment.StartTime, start, end) {
+			continue
+		}
+		lines = append(lines, domain.CommissionRevenueLine{
+			Kind:          domain.CommissionLineService,
+			ServiceID:     stringPtr(line.ServiceID),
+			AppointmentID: stringPtr(line.AppointmentID),
+			Amount:        line.Price,
+			OccurredAt:    appointment.StartTime,
+		})
+	}
+	slices.SortStableFunc(lines, func(a, b domain.CommissionRevenueLine) int { return a.OccurredAt.Compare(b.OccurredAt) })
+	return lines, nil
+}
+
+// sortByEffectiveFrom orders commission plans by when they took effect
+func sortByEffectiveFrom(plans []*domain.StaffCommissionPlan) {
+	slices.SortStableFunc(plans, func(a, b *domain.StaffCommissionPlan) int {
+		return a.EffectiveFrom.Compare(b.EffectiveFrom)
+	})
+}
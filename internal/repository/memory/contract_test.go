@@ -0,0 +1,53 @@
+package memory_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/repository/contract"
+	"github.com/assimoes/beautix/internal/repository/memory"
+	"github.com/google/uuid"
+)
+
+// newDB returns a database whose clock ticks on every read, so entities are created in a
+// known order
+func newDB() *memory.DB {
+	db := memory.NewDB()
+	clock := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	db.SetClock(func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	})
+	return db
+}
+
+func TestUserRepository_Contract(t *testing.T) {
+	contract.BaseRepositorySuite[domain.User]{
+		Setup: func(t *testing.T) (domain.BaseRepository[domain.User], func(n int) *domain.User) {
+			return memory.NewUserRepository(newDB()), func(n int) *domain.User {
+				return &domain.User{Email: fmt.Sprintf("user-%d@example.com", n), FirstName: fmt.Sprintf("User %d", n), LastName: "Test", IsActive: true}
+			}
+		},
+		ID:          func(u *domain.User) string { return u.ID },
+		Label:       func(u *domain.User) string { return u.FirstName },
+		Relabel:     func(u *domain.User, label string) { u.FirstName = label },
+		LabelColumn: "first_name",
+	}.Run(t)
+}
+
+func TestBusinessRepository_Contract(t *testing.T) {
+	contract.BaseRepositorySuite[domain.Business]{
+		Setup: func(t *testing.T) (domain.BaseRepository[domain.Business], func(n int) *domain.Business) {
+			ownerID := uuid.NewString()
+			return memory.NewBusinessRepository(newDB()), func(n int) *domain.Business {
+				return &domain.Business{UserID: ownerID, Name: fmt.Sprintf("Salon %d", n), Email: fmt.Sprintf("salon-%d@example.com", n), Currency: "EUR", TimeZone: "Europe/Lisbon", IsActive: true}
+			}
+		},
+		ID:          func(b *domain.Business) string { return b.ID },
+		Label:       func(b *domain.Business) string { return b.Name },
+		Relabel:     func(b *domain.Business, label string) { b.Name = label },
+		LabelColumn: "name",
+	}.Run(t)
+}
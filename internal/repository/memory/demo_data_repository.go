@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// demoDataRepositoryImpl implements the DemoDataRepository interface. Only the tables held in
+// memory are reset.
+type demoDataRepositoryImpl struct {
+	db *DB
+}
+
+// NewDemoDataRepository creates a new demo data repository
+func NewDemoDataRepository(db *DB) domain.DemoDataRepository {
+	return &demoDataRepositoryImpl{db: db}
+}
+
+// Reset removes the transactional data of a business and seeds a dataset in its place
+func (r *demoDataRepositoryImpl) Reset(ctx context.Context, businessID string, dataset *domain.DemoDataset) error {
+	defer r.db.lock()()
+	appointmentIDs := map[string]bool{}
+	for _, row := range tableOf[domain.Appointment](r.db).rows {
+		if row.BusinessID == businessID {
+			appointmentIDs[row.ID] = true
+		}
+	}
+	ofAppointment := func(appointmentID string) bool { return appointmentIDs[appointmentID] }
+
+	tableOf[domain.AppointmentLine](r.db).purgeWhere(func(l *domain.AppointmentLine) bool { return ofAppointment(l.AppointmentID) })
+	tableOf[domain.ServiceCompletion](r.db).purgeWhere(func(c *domain.ServiceCompletion) bool { return ofAppointment(c.AppointmentID) })
+	tableOf[appointmentPayment](r.db).purgeWhere(func(p *appointmentPayment) bool { return ofAppointment(p.ID) })
+	tableOf[domain.Payment](r.db).purgeWhere(func(p *domain.Payment) bool { return p.BusinessID == businessID })
+	tableOf[domain.DomainEvent](r.db).purgeWhere(func(e *domain.DomainEvent) bool {
+		return e.BusinessID != nil && *e.BusinessID == businessID
+	})
+	tableOf[domain.WebhookDelivery](r.db).purgeWhere(func(d *domain.WebhookDelivery) bool { return d.BusinessID == businessID })
+	tableOf[domain.ClientAttachment](r.db).purgeWhere(func(a *domain.ClientAttachment) bool { return a.BusinessID == businessID })
+	tableOf[domain.Review](r.db).purgeWhere(func(v *domain.Review) bool { return v.BusinessID == businessID })
+	tableOf[domain.ReviewRequest](r.db).purgeWhere(func(v *domain.ReviewRequest) bool { return v.BusinessID == businessID })
+	tableOf[domain.Appointment](r.db).purgeWhere(func(a *domain.Appointment) bool { return a.BusinessID == businessID })
+	tableOf[domain.Client](r.db).purgeWhere(func(c *domain.Client) bool { return c.BusinessID == businessID })
+
+	for _, service := range dataset.Services {
+		if err := tableOf[domain.Service](r.db).insert(service); err != nil {
+			return err
+		}
+	}
+	for _, client := range dataset.Clients {
+		if err := tableOf[domain.Client](r.db).insert(client); err != nil {
+			return err
+		}
+	}
+	for _, appointment := range dataset.Appointments {
+		if err := tableOf[domain.Appointment](r.db).insert(appointment); err != nil {
+			return err
+		}
+	}
+	for _, line := range dataset.Lines {
+		if err := tableOf[domain.AppointmentLine](r.db).insert(line); err != nil {
+			return err
+		}
+	}
+	for _, completion := range dataset.Completions {
+		if err := tableOf[domain.ServiceCompletion](r.db).insert(completion); err != nil {
+			return err
+		}
+		payment := paymentOf(r.db, completion.AppointmentID)
+		status := domain.AppointmentPaymentPaid
+		price := completion.PriceCharged
+		payment.PaymentStatus = &status
+		payment.ActualPrice = &price
+		payment.PaymentMethod = &completion.PaymentMethod
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// domainEventRepositoryImpl implements the DomainEventRepository interface
+type domainEventRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.DomainEvent]
+}
+
+// NewDomainEventRepository creates a new domain event repository
+func NewDomainEventRepository(db *DB) domain.DomainEventRepository {
+	return &domainEventRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.DomainEvent]{db: db},
+	}
+}
+
+// Search finds events matching the filter, oldest first so that pages replay in order
+func (r *domainEventRepositoryImpl) Search(ctx context.Context, filter domain.DomainEventFilter, page, pageSize int) ([]*domain.DomainEvent, int64, error) {
+	defer r.db.lock()()
+	events := r.table().where(func(e *domain.DomainEvent) bool {
+		switch {
+		case filter.BusinessID != nil && (e.BusinessID == nil || *e.BusinessID != *filter.BusinessID),
+			filter.AggregateType != nil && e.AggregateType != *filter.AggregateType,
+			filter.AggregateID != nil && e.AggregateID != *filter.AggregateID,
+			filter.EventType != nil && e.EventType != *filter.EventType,
+			filter.DateRange != nil && !inRange(e.OccurredAt, filter.DateRange.Start, filter.DateRange.End):
+			return false
+		}
+		return true
+	})
+	sortByOccurrence(events)
+	return paginate(events, page, pageSize), int64(len(events)), nil
+}
+
+// FindByIDs finds events by ID in the order they occurred
+func (r *domainEventRepositoryImpl) FindByIDs(ctx context.Context, ids []string) ([]*domain.DomainEvent, error) {
+	defer r.db.lock()()
+	events := r.table().where(func(e *domain.DomainEvent) bool { return slices.Contains(ids, e.ID) })
+	sortByOccurrence(events)
+	return events, nil
+}
+
+// MarkReplayed records that the given events were replayed
+func (r *domainEventRepositoryImpl) MarkReplayed(ctx context.Context, ids []string, at time.Time) error {
+	defer r.db.lock()()
+	r.table().updateWhere(func(e *domain.DomainEvent) bool { return slices.Contains(ids, e.ID) }, func(e *domain.DomainEvent) {
+		e.ReplayCount++
+		e.LastReplayedAt = &at
+	})
+	return nil
+}
+
+// ClaimUnpublished leases the unpublished events that are due
+func (r *domainEventRepositoryImpl) ClaimUnpublished(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.DomainEvent, error) {
+	defer r.db.lock()()
+	events := r.table().where(func(e *domain.DomainEvent) bool {
+		return e.IsUnpublished() && (e.NextPublishAt == nil || !e.NextPublishAt.After(now))
+	})
+	sortByOccurrence(events)
+	if len(events) > limit {
+		events = events[:limit]
+	}
+
+	leasedUntil := now.Add(lease)
+	for _, event := range events {
+		event.NextPublishAt = &leasedUntil
+		r.table().update(event.ID, func(e *domain.DomainEvent) { e.NextPublishAt = &leasedUntil })
+	}
+	return events, nil
+}
+
+// sortByOccurrence orders events by when they occurred and by ID
+func sortByOccurrence(events []*domain.DomainEvent) {
+	slices.SortFunc(events, func(a, b *domain.DomainEvent) int {
+		return cmp.Or(a.OccurredAt.Compare(b.OccurredAt), cmp.Compare(a.ID, b.ID))
+	})
+}
@@ -0,0 +1,42 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// frontDeskTaskRepositoryImpl implements the FrontDeskTaskRepository interface
+type frontDeskTaskRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.FrontDeskTask]
+}
+
+// NewFrontDeskTaskRepository creates a new front desk task repository, scoped to the tenant in
+// the context
+func NewFrontDeskTaskRepository(db *DB) domain.FrontDeskTaskRepository {
+	return &frontDeskTaskRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.FrontDeskTask]{db: db, tenantScoped: true},
+	}
+}
+
+// FindByBusiness finds the tasks of a business, soonest due first
+func (r *frontDeskTaskRepositoryImpl) FindByBusiness(ctx context.Context, businessID string, status *domain.FrontDeskTaskStatus) ([]*domain.FrontDeskTask, error) {
+	defer r.db.lock()()
+	tasks := r.table().where(func(t *domain.FrontDeskTask) bool {
+		return t.BusinessID == businessID && (status == nil || t.Status == *status)
+	})
+	slices.SortStableFunc(tasks, func(a, b *domain.FrontDeskTask) int {
+		return cmp.Or(a.DueAt.Compare(b.DueAt), a.CreatedAt.Compare(b.CreatedAt))
+	})
+	return tasks, nil
+}
+
+// FindByAppointment finds the tasks raised about an appointment, oldest first
+func (r *frontDeskTaskRepositoryImpl) FindByAppointment(ctx context.Context, appointmentID string) ([]*domain.FrontDeskTask, error) {
+	defer r.db.lock()()
+	return r.table().where(func(t *domain.FrontDeskTask) bool {
+		return t.AppointmentID != nil && *t.AppointmentID == appointmentID
+	}), nil
+}
@@ -0,0 +1,102 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// giftCardRepositoryImpl implements the GiftCardRepository interface
+type giftCardRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.GiftCard]
+}
+
+// NewGiftCardRepository creates a new gift card repository, scoped to the tenant in the context
+func NewGiftCardRepository(db *DB) domain.GiftCardRepository {
+	return &giftCardRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.GiftCard]{db: db, tenantScoped: true},
+	}
+}
+
+// FindByCode finds a gift card of a business by its code
+func (r *giftCardRepositoryImpl) FindByCode(ctx context.Context, businessID, code string) (*domain.GiftCard, error) {
+	defer r.db.lock()()
+	return r.table().first(func(g *domain.GiftCard) bool { return g.BusinessID == businessID && g.Code == code })
+}
+
+// FindTransactions finds the balance changes of a gift card, oldest first
+func (r *giftCardRepositoryImpl) FindTransactions(ctx context.Context, giftCardID string) ([]*domain.GiftCardTransaction, error) {
+	defer r.db.lock()()
+	return tableOf[domain.GiftCardTransaction](r.db).where(func(t *domain.GiftCardTransaction) bool { return t.GiftCardID == giftCardID }), nil
+}
+
+// Issue creates a card together with the transaction recording its sale
+func (r *giftCardRepositoryImpl) Issue(ctx context.Context, card *domain.GiftCard) error {
+	defer r.db.lock()()
+	if err := r.table().insert(card); err != nil {
+		return err
+	}
+	return tableOf[domain.GiftCardTransaction](r.db).insert(&domain.GiftCardTransaction{
+		BaseModel:    domain.BaseModel{CreatedBy: card.CreatedBy},
+		BusinessID:   card.BusinessID,
+		GiftCardID:   card.ID,
+		Kind:         domain.GiftCardTransactionIssue,
+		Amount:       card.InitialValue,
+		BalanceAfter: card.Balance,
+	})
+}
+
+// Redeem takes a payment's amount off a card and records the payment and the transaction
+func (r *giftCardRepositoryImpl) Redeem(ctx context.Context, giftCardID string, payment *domain.Payment) error {
+	defer r.db.lock()()
+	var balance decimal.Decimal
+	redeemed := r.table().updateWhere(func(g *domain.GiftCard) bool {
+		return g.ID == giftCardID && g.Balance.GreaterThanOrEqual(payment.Amount)
+	}, func(g *domain.GiftCard) {
+		g.Balance = g.Balance.Sub(payment.Amount)
+		g.UpdatedAt = r.db.now()
+		g.UpdatedBy = payment.CreatedBy
+		balance = g.Balance
+	})
+	if redeemed == 0 {
+		return domain.ErrGiftCardBalanceChanged
+	}
+
+	payment.GiftCardID = &giftCardID
+	if err := tableOf[domain.Payment](r.db).insert(payment); err != nil {
+		return err
+	}
+	return r.recordTransaction(payment, domain.GiftCardTransactionRedemption, payment.Amount, balance, payment.CreatedBy)
+}
+
+// Refund puts part of a gift card payment back onto the card and saves the payment
+func (r *giftCardRepositoryImpl) Refund(ctx context.Context, payment *domain.Payment, amount decimal.Decimal) error {
+	defer r.db.lock()()
+	var balance decimal.Decimal
+	r.table().update(*payment.GiftCardID, func(g *domain.GiftCard) {
+		g.Balance = g.Balance.Add(amount)
+		g.UpdatedAt = r.db.now()
+		g.UpdatedBy = payment.UpdatedBy
+		balance = g.Balance
+	})
+	if err := tableOf[domain.Payment](r.db).save(payment); err != nil {
+		return err
+	}
+	return r.recordTransaction(payment, domain.GiftCardTransactionRefund, amount, balance, payment.UpdatedBy)
+}
+
+// recordTransaction records a change of a card's balance made by a payment. The caller must
+// hold the lock.
+func (r *giftCardRepositoryImpl) recordTransaction(payment *domain.Payment, kind domain.GiftCardTransactionKind, amount, balance decimal.Decimal, by *string) error {
+	return tableOf[domain.GiftCardTransaction](r.db).insert(&domain.GiftCardTransaction{
+		BaseModel:     domain.BaseModel{CreatedBy: by},
+		BusinessID:    payment.BusinessID,
+		GiftCardID:    *payment.GiftCardID,
+		Kind:          kind,
+		Amount:        amount,
+		BalanceAfter:  balance,
+		PaymentID:     &payment.ID,
+		AppointmentID: &payment.AppointmentID,
+	})
+}
@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// idempotencyKeyRepositoryImpl implements the IdempotencyKeyRepository interface
+type idempotencyKeyRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.IdempotencyKey]
+}
+
+// NewIdempotencyKeyRepository creates a new idempotency key repository
+func NewIdempotencyKeyRepository(db *DB) domain.IdempotencyKeyRepository {
+	return &idempotencyKeyRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.IdempotencyKey]{db: db},
+	}
+}
+
+// Claim stores a pending key, first removing a replaceable key stored for the same scope and key
+func (r *idempotencyKeyRepositoryImpl) Claim(ctx context.Context, key *domain.IdempotencyKey, now time.Time) (*domain.IdempotencyKey, bool, error) {
+	defer r.db.lock()()
+	t := r.table()
+	sameKey := func(k *domain.IdempotencyKey) bool { return k.Scope == key.Scope && k.Key == key.Key }
+	t.purgeWhere(func(k *domain.IdempotencyKey) bool { return sameKey(k) && k.Replaceable(now) })
+
+	stored, err := t.first(sameKey)
+	if err == nil {
+		return stored, false, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, err
+	}
+	if err := t.insert(key); err != nil {
+		return nil, false, err
+	}
+	return key, true, nil
+}
+
+// Complete records the response to the request of a pending key
+func (r *idempotencyKeyRepositoryImpl) Complete(ctx context.Context, id string, response string) error {
+	defer r.db.lock()()
+	now := r.db.now()
+	completed := r.table().updateWhere(func(k *domain.IdempotencyKey) bool {
+		return k.ID == id && k.Status == domain.IdempotencyPending
+	}, func(k *domain.IdempotencyKey) {
+		k.Status = domain.IdempotencyCompleted
+		k.Response = &response
+		k.UpdatedAt = now
+		k.Version++
+	})
+	if completed == 0 {
+		return errors.New("the idempotency key is no longer pending")
+	}
+	return nil
+}
+
+// Release permanently deletes a pending key
+func (r *idempotencyKeyRepositoryImpl) Release(ctx context.Context, id string) error {
+	defer r.db.lock()()
+	r.table().purgeWhere(func(k *domain.IdempotencyKey) bool {
+		return k.ID == id && k.Status == domain.IdempotencyPending
+	})
+	return nil
+}
+
+// DeleteExpired permanently deletes the keys that expired before a time
+func (r *idempotencyKeyRepositoryImpl) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	defer r.db.lock()()
+	return r.table().purgeWhere(func(k *domain.IdempotencyKey) bool { return k.ExpiresAt.Before(before) }), nil
+}
+
+// WithTx returns the repository itself
+func (r *idempotencyKeyRepositoryImpl) WithTx(tx *gorm.DB) domain.BaseRepository[domain.IdempotencyKey] {
+	return r
+}
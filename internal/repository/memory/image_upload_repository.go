@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// imageUploadRepositoryImpl implements the ImageUploadRepository interface
+type imageUploadRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.ImageUpload]
+}
+
+// NewImageUploadRepository creates a new image upload repository, scoped to the tenant in the
+// context
+func NewImageUploadRepository(db *DB) domain.ImageUploadRepository {
+	return &imageUploadRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.ImageUpload]{db: db, tenantScoped: true},
+	}
+}
+
+// FindUnreferenced finds the uploads whose files can be removed, across businesses and oldest
+// first
+func (r *imageUploadRepositoryImpl) FindUnreferenced(ctx context.Context, abandonedBefore time.Time, limit int) ([]*domain.ImageUpload, error) {
+	defer r.db.lock()()
+	uploads := r.table().where(func(u *domain.ImageUpload) bool {
+		switch u.Status {
+		case domain.ImageUploadPending:
+			return u.UploadExpiresAt.Before(abandonedBefore)
+		case domain.ImageUploadRejected:
+			return true
+		case domain.ImageUploadAttached:
+			return u.URL == nil || !imageShown(r.db, *u.URL)
+		}
+		return false
+	})
+	slices.SortStableFunc(uploads, func(a, b *domain.ImageUpload) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+	return uploads[:min(limit, len(uploads))], nil
+}
+
+// imageShown reports whether any business or staff member shows the image at a URL, since
+// cloned businesses share their source's images
+func imageShown(db *DB, url string) bool {
+	shows := func(shown *string) bool { return shown != nil && *shown == url }
+	if _, err := tableOf[domain.Business](db).first(func(b *domain.Business) bool {
+		return shows(b.LogoURL) || shows(b.CoverPhotoURL)
+	}); err == nil {
+		return true
+	}
+	_, err := tableOf[domain.Staff](db).first(func(s *domain.Staff) bool { return shows(s.ProfileImageURL) })
+	return err == nil
+}
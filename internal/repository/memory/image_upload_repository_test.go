@@ -0,0 +1,47 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImageUploadRepository_FindUnreferenced(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	repo := NewImageUploadRepository(db)
+
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	logo, photo, replaced := "https://cdn.example.com/logo.png", "https://cdn.example.com/photo.jpg", "https://cdn.example.com/old.png"
+	require.NoError(t, Insert(db, &domain.Business{Name: "Clone", LogoURL: &logo}))
+	require.NoError(t, Insert(db, &domain.Staff{BusinessID: "business-1", ProfileImageURL: &photo}))
+
+	upload := func(status domain.ImageUploadStatus, expires time.Time, url *string) *domain.ImageUpload {
+		return &domain.ImageUpload{BusinessID: "business-1", Purpose: domain.ImagePurposeBusinessLogo, TargetID: "business-1",
+			Status: status, UploadExpiresAt: expires, URL: url}
+	}
+	abandoned := upload(domain.ImageUploadPending, now.Add(-2*time.Hour), nil)
+	inProgress := upload(domain.ImageUploadPending, now.Add(time.Hour), nil)
+	rejected := upload(domain.ImageUploadRejected, now, nil)
+	sharedLogo := upload(domain.ImageUploadAttached, now, &logo)
+	staffPhoto := upload(domain.ImageUploadAttached, now, &photo)
+	unshown := upload(domain.ImageUploadAttached, now, &replaced)
+	removed := upload(domain.ImageUploadRemoved, now, &replaced)
+	require.NoError(t, Insert(db, abandoned, inProgress, rejected, sharedLogo, staffPhoto, unshown, removed))
+
+	uploads, err := repo.FindUnreferenced(ctx, now.Add(-time.Hour), 10)
+	require.NoError(t, err)
+	ids := []string{}
+	for _, u := range uploads {
+		ids = append(ids, u.ID)
+	}
+	assert.ElementsMatch(t, []string{abandoned.ID, rejected.ID, unshown.ID}, ids)
+
+	uploads, err = repo.FindUnreferenced(ctx, now.Add(-time.Hour), 1)
+	require.NoError(t, err)
+	assert.Len(t, uploads, 1)
+}
@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// integrationUsageRepositoryImpl implements the IntegrationUsageRepository interface
+type integrationUsageRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.IntegrationUsageDaily]
+}
+
+// NewIntegrationUsageRepository creates a new integration usage repository, scoped to the tenant
+// in the context
+func NewIntegrationUsageRepository(db *DB) domain.IntegrationUsageRepository {
+	return &integrationUsageRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.IntegrationUsageDaily]{db: db, tenantScoped: true},
+	}
+}
+
+// Increment atomically adds a request, and optionally an error, to the day's counters
+func (r *integrationUsageRepositoryImpl) Increment(ctx context.Context, businessID string, day time.Time, channel domain.IntegrationChannel, sourceID string, failed bool) error {
+	defer r.db.lock()()
+	date := day.Format(time.DateOnly)
+	now := r.db.now()
+	errorCount := 0
+	if failed {
+		errorCount = 1
+	}
+
+	updated := r.table().updateWhere(func(u *domain.IntegrationUsageDaily) bool {
+		return u.BusinessID == businessID && u.UsageDate.Format(time.DateOnly) == date && u.Channel == channel && u.SourceID == sourceID
+	}, func(u *domain.IntegrationUsageDaily) {
+		u.RequestCount++
+		u.ErrorCount += errorCount
+		u.UpdatedAt = now
+	})
+	if updated > 0 {
+		return nil
+	}
+
+	year, month, dayOfMonth := day.Date()
+	return r.table().insert(&domain.IntegrationUsageDaily{
+		BusinessID:   businessID,
+		UsageDate:    time.Date(year, month, dayOfMonth, 0, 0, 0, 0, time.UTC),
+		Channel:      channel,
+		SourceID:     sourceID,
+		RequestCount: 1,
+		ErrorCount:   errorCount,
+	})
+}
+
+// FindByBusiness finds the usage counters of a business between two dates, inclusive
+func (r *integrationUsageRepositoryImpl) FindByBusiness(ctx context.Context, businessID string, start, end time.Time) ([]*domain.IntegrationUsageDaily, error) {
+	defer r.db.lock()()
+	startDate, endDate := start.Format(time.DateOnly), end.Format(time.DateOnly)
+	usage := r.table().where(func(u *domain.IntegrationUsageDaily) bool {
+		date := u.UsageDate.Format(time.DateOnly)
+		return u.BusinessID == businessID && date >= startDate && date <= endDate
+	})
+	slices.SortStableFunc(usage, func(a, b *domain.IntegrationUsageDaily) int {
+		return cmp.Or(a.UsageDate.Compare(b.UsageDate), cmp.Compare(a.Channel, b.Channel), cmp.Compare(a.SourceID, b.SourceID))
+	})
+	return usage, nil
+}
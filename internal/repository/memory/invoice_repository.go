@@ -0,0 +1,46 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// invoiceSequence is the last invoice number issued by a business, keyed by the business ID
+type invoiceSequence struct {
+	ID         string
+	LastNumber int
+}
+
+// invoiceRepositoryImpl implements the InvoiceRepository interface
+type invoiceRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.Invoice]
+}
+
+// NewInvoiceRepository creates a new invoice repository, scoped to the tenant in the context
+func NewInvoiceRepository(db *DB) domain.InvoiceRepository {
+	return &invoiceRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.Invoice]{db: db, tenantScoped: true},
+	}
+}
+
+// FindByAppointmentID finds the invoice issued for an appointment
+func (r *invoiceRepositoryImpl) FindByAppointmentID(ctx context.Context, appointmentID string) (*domain.Invoice, error) {
+	defer r.db.lock()()
+	return r.table().first(func(i *domain.Invoice) bool { return i.AppointmentID == appointmentID })
+}
+
+// Issue assigns the business's next invoice number and creates the invoice atomically
+func (r *invoiceRepositoryImpl) Issue(ctx context.Context, invoice *domain.Invoice) error {
+	defer r.db.lock()()
+	sequences := tableOf[invoiceSequence](r.db)
+	sequence, ok := sequences.rows[invoice.BusinessID]
+	if !ok {
+		sequence = &invoiceSequence{ID: invoice.BusinessID}
+		sequences.rows[invoice.BusinessID] = sequence
+	}
+	sequence.LastNumber++
+
+	invoice.InvoiceNumber = domain.FormatInvoiceNumber(invoice.IssuedAt, sequence.LastNumber)
+	return r.table().insert(invoice)
+}
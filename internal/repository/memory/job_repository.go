@@ -0,0 +1,106 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// jobRepositoryImpl implements the JobRepository interface
+type jobRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.Job]
+}
+
+// NewJobRepository creates a new job repository
+func NewJobRepository(db *DB) domain.JobRepository {
+	return &jobRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.Job]{db: db},
+	}
+}
+
+// Enqueue creates a job unless a pending job of the same kind has its unique key, in which case
+// that job is returned instead
+func (r *jobRepositoryImpl) Enqueue(ctx context.Context, job *domain.Job) (*domain.Job, bool, error) {
+	defer r.db.lock()()
+	if job.UniqueKey != nil {
+		existing, err := r.table().first(func(j *domain.Job) bool {
+			return j.Kind == job.Kind && j.Status == domain.JobStatusPending && j.UniqueKey != nil && *j.UniqueKey == *job.UniqueKey
+		})
+		if err == nil {
+			return existing, false, nil
+		}
+	}
+	if err := r.table().insert(job); err != nil {
+		return nil, false, err
+	}
+	return job, true, nil
+}
+
+// ClaimDue leases the pending jobs that are due
+func (r *jobRepositoryImpl) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*domain.Job, error) {
+	defer r.db.lock()()
+	jobs := r.table().where(func(j *domain.Job) bool {
+		return j.Status == domain.JobStatusPending && !j.RunAt.After(now)
+	})
+	slices.SortFunc(jobs, func(a, b *domain.Job) int {
+		return cmp.Or(a.RunAt.Compare(b.RunAt), cmp.Compare(a.ID, b.ID))
+	})
+	if len(jobs) > limit {
+		jobs = jobs[:limit]
+	}
+
+	leasedUntil := now.Add(lease)
+	for _, job := range jobs {
+		job.RunAt = leasedUntil
+		r.table().update(job.ID, func(j *domain.Job) { j.RunAt = leasedUntil })
+	}
+	return jobs, nil
+}
+
+// CancelPending cancels the pending job of a kind with a unique key
+func (r *jobRepositoryImpl) CancelPending(ctx context.Context, kind, uniqueKey string, now time.Time) (bool, error) {
+	defer r.db.lock()()
+	cancelled := r.table().updateWhere(func(j *domain.Job) bool {
+		return j.Kind == kind && j.Status == domain.JobStatusPending && j.UniqueKey != nil && *j.UniqueKey == uniqueKey
+	}, func(j *domain.Job) {
+		j.Status = domain.JobStatusCancelled
+		j.CompletedAt = &now
+	})
+	return cancelled > 0, nil
+}
+
+// OldestDue returns when the oldest pending job that is due was due
+func (r *jobRepositoryImpl) OldestDue(ctx context.Context, now time.Time) (*time.Time, error) {
+	defer r.db.lock()()
+	var dueAt *time.Time
+	for _, job := range r.table().where(func(j *domain.Job) bool {
+		return j.Status == domain.JobStatusPending && !j.RunAt.After(now)
+	}) {
+		if dueAt == nil || job.RunAt.Before(*dueAt) {
+			runAt := job.RunAt
+			dueAt = &runAt
+		}
+	}
+	return dueAt, nil
+}
+
+// Search finds the jobs matching the filter, most recently due first
+func (r *jobRepositoryImpl) Search(ctx context.Context, filter domain.JobFilter, page, pageSize int) ([]*domain.Job, int64, error) {
+	defer r.db.lock()()
+	jobs := r.table().where(func(j *domain.Job) bool {
+		switch {
+		case filter.Status != nil && j.Status != *filter.Status,
+			filter.Kind != nil && j.Kind != *filter.Kind,
+			filter.BusinessID != nil && (j.BusinessID == nil || *j.BusinessID != *filter.BusinessID):
+			return false
+		}
+		return true
+	})
+	slices.SortFunc(jobs, func(a, b *domain.Job) int {
+		return cmp.Or(b.RunAt.Compare(a.RunAt), cmp.Compare(b.ID, a.ID))
+	})
+	return paginate(jobs, page, pageSize), int64(len(jobs)), nil
+}
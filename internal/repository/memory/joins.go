@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// The helpers below stand in for the joins of the SQL queries. The caller must hold the lock.
+
+// clientContact returns the client with the phone and email of a minor's guardian in place of
+// their own, since contact with a minor goes through the guardian
+func clientContact(db *DB, clientID string) (*domain.Client, bool) {
+	clients := tableOf[domain.Client](db)
+	client, err := clients.get(clientID)
+	if err != nil {
+		return nil, false
+	}
+	if client.GuardianClientID != nil {
+		if guardian, err := clients.get(*client.GuardianClientID); err == nil {
+			client.Phone = guardian.Phone
+			client.Email = guardian.Email
+		}
+	}
+	return client, true
+}
+
+// clientName returns the full name of a client
+func clientName(db *DB, clientID string) string {
+	client, err := tableOf[domain.Client](db).get(clientID)
+	if err != nil {
+		return ""
+	}
+	return client.FirstName + " " + client.LastName
+}
+
+// staffUser returns the user account of a staff member
+func staffUser(db *DB, staffID string) (*domain.User, bool) {
+	staff, err := tableOf[domain.Staff](db).get(staffID)
+	if err != nil {
+		return nil, false
+	}
+	user, err := tableOf[domain.User](db).get(staff.UserID)
+	if err != nil {
+		return nil, false
+	}
+	return user, true
+}
+
+// staffName returns the full name of a staff member
+func staffName(db *DB, staffID string) string {
+	user, ok := staffUser(db, staffID)
+	if !ok {
+		return ""
+	}
+	return user.FirstName + " " + user.LastName
+}
+
+// appointmentServiceNames returns the names of an appointment's services, sorted and comma
+// separated
+func appointmentServiceNames(db *DB, appointmentID string) string {
+	services := tableOf[domain.Service](db)
+	var names []string
+	for _, line := range tableOf[domain.AppointmentLine](db).where(func(l *domain.AppointmentLine) bool { return l.AppointmentID == appointmentID }) {
+		if service, err := services.get(line.ServiceID); err == nil {
+			names = append(names, service.Name)
+		}
+	}
+	slices.Sort(names)
+	return strings.Join(names, ", ")
+}
+
+// businessSettings returns the settings of a business, or nil when it has none
+func businessSettings(db *DB, businessID string) *domain.BusinessSettings {
+	settings, err := tableOf[domain.BusinessSettings](db).first(func(s *domain.BusinessSettings) bool {
+		return s.BusinessID == businessID
+	})
+	if err != nil {
+		return nil
+	}
+	return settings
+}
+
+// blank reports whether an optional text is missing or only whitespace
+func blank(s *string) bool {
+	return s == nil || strings.TrimSpace(*s) == ""
+}
+
+// stringPtr returns a pointer to a copy of the string
+func stringPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,113 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// loyaltyRepositoryImpl implements the LoyaltyRepository interface
+type loyaltyRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.LoyaltyProgram]
+}
+
+// NewLoyaltyRepository creates a new loyalty repository, scoped to the tenant in the context
+func NewLoyaltyRepository(db *DB) domain.LoyaltyRepository {
+	return &loyaltyRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.LoyaltyProgram]{db: db, tenantScoped: true},
+	}
+}
+
+// FindRunningPrograms finds the business's programs awarding points at the given time
+func (r *loyaltyRepositoryImpl) FindRunningPrograms(ctx context.Context, businessID string, at time.Time) ([]*domain.LoyaltyProgram, error) {
+	defer r.db.lock()()
+	return r.table().where(func(p *domain.LoyaltyProgram) bool { return p.BusinessID == businessID && p.IsRunningAt(at) }), nil
+}
+
+// FindMembership finds a client's membership of a program
+func (r *loyaltyRepositoryImpl) FindMembership(ctx context.Context, programID, clientID string) (*domain.ClientLoyaltyMembership, error) {
+	defer r.db.lock()()
+	return tableOf[domain.ClientLoyaltyMembership](r.db).first(func(m *domain.ClientLoyaltyMembership) bool {
+		return m.ProgramID == programID && m.ClientID == clientID
+	})
+}
+
+// HasEarned reports whether the membership already earned points for the appointment
+func (r *loyaltyRepositoryImpl) HasEarned(ctx context.Context, membershipID, appointmentID string) (bool, error) {
+	defer r.db.lock()()
+	return tableOf[domain.LoyaltyTransaction](r.db).count(func(t *domain.LoyaltyTransaction) bool {
+		return t.MembershipID == membershipID && t.AppointmentID != nil && *t.AppointmentID == appointmentID &&
+			t.TransactionType == domain.LoyaltyTransactionEarn
+	}) > 0, nil
+}
+
+// FindAppointmentLines finds the services performed during an appointment
+func (r *loyaltyRepositoryImpl) FindAppointmentLines(ctx context.Context, appointmentID string) ([]*domain.AppointmentLine, error) {
+	defer r.db.lock()()
+	return tableOf[domain.AppointmentLine](r.db).where(func(l *domain.AppointmentLine) bool { return l.AppointmentID == appointmentID }), nil
+}
+
+// RecordEarning saves the membership, creating it on the client's first earning visit,
+// together with the transaction recording the points
+func (r *loyaltyRepositoryImpl) RecordEarning(ctx context.Context, membership *domain.ClientLoyaltyMembership, transaction *domain.LoyaltyTransaction) error {
+	defer r.db.lock()()
+	if err := tableOf[domain.ClientLoyaltyMembership](r.db).save(membership); err != nil {
+		return err
+	}
+	transaction.MembershipID = membership.ID
+	return tableOf[domain.LoyaltyTransaction](r.db).insert(transaction)
+}
+
+// SaveMembership saves a membership, creating it when it is new
+func (r *loyaltyRepositoryImpl) SaveMembership(ctx context.Context, membership *domain.ClientLoyaltyMembership) error {
+	defer r.db.lock()()
+	return tableOf[domain.ClientLoyaltyMembership](r.db).save(membership)
+}
+
+// FindMembershipByReferralCode finds the membership a referral code was given to
+func (r *loyaltyRepositoryImpl) FindMembershipByReferralCode(ctx context.Context, code string) (*domain.ClientLoyaltyMembership, error) {
+	defer r.db.lock()()
+	return tableOf[domain.ClientLoyaltyMembership](r.db).first(func(m *domain.ClientLoyaltyMembership) bool {
+		return m.ReferralCode != nil && *m.ReferralCode == code
+	})
+}
+
+// FindReferral finds how a client was referred to the business
+func (r *loyaltyRepositoryImpl) FindReferral(ctx context.Context, businessID, referredClientID string) (*domain.Referral, error) {
+	defer r.db.lock()()
+	return tableOf[domain.Referral](r.db).first(func(ref *domain.Referral) bool {
+		return ref.BusinessID == businessID && ref.ReferredClientID == referredClientID
+	})
+}
+
+// CreateReferral creates a referral
+func (r *loyaltyRepositoryImpl) CreateReferral(ctx context.Context, referral *domain.Referral) error {
+	defer r.db.lock()()
+	return tableOf[domain.Referral](r.db).insert(referral)
+}
+
+// RecordReferralBonus saves a converted referral together with the referrer's membership and
+// the transaction awarding the bonus, when one was awarded
+func (r *loyaltyRepositoryImpl) RecordReferralBonus(ctx context.Context, referral *domain.Referral, membership *domain.ClientLoyaltyMembership, transaction *domain.LoyaltyTransaction) error {
+	defer r.db.lock()()
+	if err := tableOf[domain.Referral](r.db).save(referral); err != nil {
+		return err
+	}
+	if transaction == nil {
+		return nil
+	}
+	if err := tableOf[domain.ClientLoyaltyMembership](r.db).save(membership); err != nil {
+		return err
+	}
+	transaction.MembershipID = membership.ID
+	return tableOf[domain.LoyaltyTransaction](r.db).insert(transaction)
+}
+
+// FindReferrals finds the business's referrals made between from and to, oldest first
+func (r *loyaltyRepositoryImpl) FindReferrals(ctx context.Context, businessID string, from, to time.Time) ([]*domain.Referral, error) {
+	defer r.db.lock()()
+	return tableOf[domain.Referral](r.db).where(func(ref *domain.Referral) bool {
+		return ref.BusinessID == businessID && !ref.CreatedAt.Before(from) && ref.CreatedAt.Before(to)
+	}), nil
+}
@@ -0,0 +1,127 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// membershipRepositoryImpl implements the MembershipRepository interface
+type membershipRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.ClientMembership]
+}
+
+// NewMembershipRepository creates a new membership repository, scoped to the tenant in the context
+func NewMembershipRepository(db *DB) domain.MembershipRepository {
+	return &membershipRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.ClientMembership]{db: db, tenantScoped: true},
+	}
+}
+
+// FindByClientID finds the client's memberships, oldest first
+func (r *membershipRepositoryImpl) FindByClientID(ctx context.Context, clientID string) ([]*domain.ClientMembership, error) {
+	defer r.db.lock()()
+	return r.table().where(func(m *domain.ClientMembership) bool { return m.ClientID == clientID }), nil
+}
+
+// FindCurrent finds the client's membership of the plan that has not been cancelled
+func (r *membershipRepositoryImpl) FindCurrent(ctx context.Context, clientID, planID string) (*domain.ClientMembership, error) {
+	defer r.db.lock()()
+	return r.table().first(func(m *domain.ClientMembership) bool {
+		return m.ClientID == clientID && m.PlanID == planID && m.Status != domain.MembershipCancelled
+	})
+}
+
+// FindDue finds the memberships a billing run at the given time has to act on
+func (r *membershipRepositoryImpl) FindDue(ctx context.Context, now time.Time) ([]*domain.ClientMembership, error) {
+	defer r.db.lock()()
+	memberships := r.table().where(func(m *domain.ClientMembership) bool {
+		return m.BillingAction(now) != domain.MembershipBillingNone
+	})
+	slices.SortStableFunc(memberships, func(a, b *domain.ClientMembership) int { return a.NextBillingAt.Compare(b.NextBillingAt) })
+	return memberships, nil
+}
+
+// FindOpenInvoice finds the membership's invoice awaiting payment
+func (r *membershipRepositoryImpl) FindOpenInvoice(ctx context.Context, membershipID string) (*domain.MembershipInvoice, error) {
+	defer r.db.lock()()
+	return tableOf[domain.MembershipInvoice](r.db).first(func(i *domain.MembershipInvoice) bool {
+		return i.MembershipID == membershipID && i.Status == domain.MembershipInvoiceOpen
+	})
+}
+
+// FindInvoices finds the membership's invoices, most recent period first
+func (r *membershipRepositoryImpl) FindInvoices(ctx context.Context, membershipID string) ([]*domain.MembershipInvoice, error) {
+	defer r.db.lock()()
+	invoices := tableOf[domain.MembershipInvoice](r.db).where(func(i *domain.MembershipInvoice) bool { return i.MembershipID == membershipID })
+	slices.SortStableFunc(invoices, func(a, b *domain.MembershipInvoice) int { return b.PeriodStart.Compare(a.PeriodStart) })
+	return invoices, nil
+}
+
+// SaveBilling saves a membership together with its invoice
+func (r *membershipRepositoryImpl) SaveBilling(ctx context.Context, membership *domain.ClientMembership, invoice *domain.MembershipInvoice) error {
+	defer r.db.lock()()
+	if err := r.table().save(membership); err != nil {
+		return err
+	}
+	if invoice == nil {
+		return nil
+	}
+	invoice.MembershipID = membership.ID
+	return tableOf[domain.MembershipInvoice](r.db).save(invoice)
+}
+
+// UseSession takes a session off the membership's current period
+func (r *membershipRepositoryImpl) UseSession(ctx context.Context, membershipID string, by *string) error {
+	defer r.db.lock()()
+	used := r.table().updateWhere(func(m *domain.ClientMembership) bool {
+		return m.ID == membershipID && m.SessionsRemaining != nil && *m.SessionsRemaining > 0
+	}, func(m *domain.ClientMembership) {
+		remaining := *m.SessionsRemaining - 1
+		m.SessionsRemaining = &remaining
+		m.UpdatedAt = r.db.now()
+		m.UpdatedBy = by
+	})
+	if used == 0 {
+		return domain.ErrNoSessionsLeft
+	}
+	return nil
+}
+
+// Cancel saves a cancelled membership together with its credit note and the gift card holding
+// the credit
+func (r *membershipRepositoryImpl) Cancel(ctx context.Context, membership *domain.ClientMembership, creditNote *domain.MembershipCreditNote, giftCard *domain.GiftCard) error {
+	defer r.db.lock()()
+	if err := r.table().save(membership); err != nil {
+		return err
+	}
+	if giftCard != nil {
+		if err := tableOf[domain.GiftCard](r.db).insert(giftCard); err != nil {
+			return err
+		}
+		if err := tableOf[domain.GiftCardTransaction](r.db).insert(&domain.GiftCardTransaction{
+			BaseModel:    domain.BaseModel{CreatedBy: giftCard.CreatedBy},
+			BusinessID:   giftCard.BusinessID,
+			GiftCardID:   giftCard.ID,
+			Kind:         domain.GiftCardTransactionIssue,
+			Amount:       giftCard.InitialValue,
+			BalanceAfter: giftCard.Balance,
+		}); err != nil {
+			return err
+		}
+		creditNote.GiftCardID = &giftCard.ID
+	}
+	if creditNote == nil {
+		return nil
+	}
+	creditNote.MembershipID = membership.ID
+	return tableOf[domain.MembershipCreditNote](r.db).insert(creditNote)
+}
+
+// FindCreditNotes finds the membership's credit notes, oldest first
+func (r *membershipRepositoryImpl) FindCreditNotes(ctx context.Context, membershipID string) ([]*domain.MembershipCreditNote, error) {
+	defer r.db.lock()()
+	return tableOf[domain.MembershipCreditNote](r.db).where(func(c *domain.MembershipCreditNote) bool { return c.MembershipID == membershipID }), nil
+}
@@ -0,0 +1,45 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// messageTemplateRepositoryImpl implements the MessageTemplateRepository interface
+type messageTemplateRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.MessageTemplate]
+}
+
+// NewMessageTemplateRepository creates a new message template repository, scoped to the tenant in
+// the context
+func NewMessageTemplateRepository(db *DB) domain.MessageTemplateRepository {
+	return &messageTemplateRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.MessageTemplate]{db: db, tenantScoped: true},
+	}
+}
+
+// FindByBusinessID finds all message templates of a business
+func (r *messageTemplateRepositoryImpl) FindByBusinessID(ctx context.Context, businessID string) ([]*domain.MessageTemplate, error) {
+	defer r.db.lock()()
+	templates := r.table().where(func(t *domain.MessageTemplate) bool { return t.BusinessID == businessID })
+	slices.SortStableFunc(templates, func(a, b *domain.MessageTemplate) int {
+		return cmp.Or(cmp.Compare(a.Channel, b.Channel), cmp.Compare(a.Name, b.Name))
+	})
+	return templates, nil
+}
+
+// FindActiveByPurpose finds the most recently updated active template of a business for a channel and purpose
+func (r *messageTemplateRepositoryImpl) FindActiveByPurpose(ctx context.Context, businessID string, channel domain.MessageChannel, purpose string) (*domain.MessageTemplate, error) {
+	defer r.db.lock()()
+	templates := r.table().where(func(t *domain.MessageTemplate) bool {
+		return t.BusinessID == businessID && t.Channel == channel && t.Purpose != nil && *t.Purpose == purpose && t.IsActive
+	})
+	if len(templates) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return slices.MaxFunc(templates, func(a, b *domain.MessageTemplate) int { return a.UpdatedAt.Compare(b.UpdatedAt) }), nil
+}
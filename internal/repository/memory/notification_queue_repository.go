@@ -0,0 +1,39 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// notificationQueueRepositoryImpl implements the NotificationQueueRepository interface
+type notificationQueueRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.QueuedNotification]
+}
+
+// NewNotificationQueueRepository creates a new notification queue repository
+func NewNotificationQueueRepository(db *DB) domain.NotificationQueueRepository {
+	return &notificationQueueRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.QueuedNotification]{db: db},
+	}
+}
+
+// FindDue finds the pending notifications whose next attempt is due, oldest first
+func (r *notificationQueueRepositoryImpl) FindDue(ctx context.Context, now time.Time, limit int) ([]*domain.QueuedNotification, error) {
+	defer r.db.lock()()
+	notifications := r.table().where(func(n *domain.QueuedNotification) bool {
+		return n.SentAt == nil && n.AbandonedAt == nil && !n.NextAttemptAt.After(now)
+	})
+	slices.SortStableFunc(notifications, func(a, b *domain.QueuedNotification) int {
+		return a.NextAttemptAt.Compare(b.NextAttemptAt)
+	})
+	return notifications[:min(limit, len(notifications))], nil
+}
+
+// WithTx returns the repository itself
+func (r *notificationQueueRepositoryImpl) WithTx(tx *gorm.DB) domain.BaseRepository[domain.QueuedNotification] {
+	return r
+}
@@ -0,0 +1,50 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// outboundRequestRepositoryImpl implements the OutboundRequestRepository interface
+type outboundRequestRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.OutboundRequest]
+}
+
+// NewOutboundRequestRepository creates a new outbound request repository
+func NewOutboundRequestRepository(db *DB) domain.OutboundRequestRepository {
+	return &outboundRequestRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.OutboundRequest]{db: db},
+	}
+}
+
+// FindByFilter finds the outbound requests matching the filter, most recent first
+func (r *outboundRequestRepositoryImpl) FindByFilter(ctx context.Context, filter domain.OutboundRequestFilter) ([]*domain.OutboundRequest, error) {
+	defer r.db.lock()()
+	requests := r.table().where(func(o *domain.OutboundRequest) bool {
+		switch {
+		case filter.Provider != nil && o.Provider != *filter.Provider,
+			filter.FailedOnly && !o.Failed(),
+			filter.StartDate != nil && o.CreatedAt.Before(*filter.StartDate),
+			filter.EndDate != nil && !o.CreatedAt.Before(*filter.EndDate):
+			return false
+		}
+		return true
+	})
+	slices.Reverse(requests)
+	return requests[:min(filter.Limit, len(requests))], nil
+}
+
+// DeleteBefore permanently deletes the outbound requests made before a time
+func (r *outboundRequestRepositoryImpl) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	defer r.db.lock()()
+	return r.table().purgeWhere(func(o *domain.OutboundRequest) bool { return o.CreatedAt.Before(before) }), nil
+}
+
+// WithTx returns the repository itself
+func (r *outboundRequestRepositoryImpl) WithTx(tx *gorm.DB) domain.BaseRepository[domain.OutboundRequest] {
+	return r
+}
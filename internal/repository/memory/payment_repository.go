@@ -0,0 +1,129 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// appointmentPayment holds the payment columns of the appointments table, which the
+// appointment model does not carry. It is keyed by the appointment ID.
+type appointmentPayment struct {
+	ID            string
+	PaymentStatus *domain.AppointmentPaymentStatus
+	ActualPrice   *decimal.Decimal
+	PaymentMethod *string
+}
+
+// paymentOf returns the payment columns of an appointment, creating them on first use
+func paymentOf(db *DB, appointmentID string) *appointmentPayment {
+	payments := tableOf[appointmentPayment](db)
+	payment, ok := payments.rows[appointmentID]
+	if !ok {
+		payment = &appointmentPayment{ID: appointmentID}
+		payments.rows[appointmentID] = payment
+	}
+	return payment
+}
+
+// paymentRepositoryImpl implements the PaymentRepository interface
+type paymentRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.Payment]
+}
+
+// NewPaymentRepository creates a new payment repository, scoped to the tenant in the context
+func NewPaymentRepository(db *DB) domain.PaymentRepository {
+	return &paymentRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.Payment]{db: db, tenantScoped: true},
+	}
+}
+
+// Create creates a payment, failing with domain.ErrDepositAlreadyCharged for a deposit of an
+// appointment that already has one paid or in progress
+func (r *paymentRepositoryImpl) Create(ctx context.Context, payment *domain.Payment) error {
+	if payment.Kind == domain.PaymentKindDeposit {
+		unlock := r.db.lock()
+		charged := r.table().count(func(p *domain.Payment) bool {
+			return p.AppointmentID == payment.AppointmentID && p.Kind == domain.PaymentKindDeposit && (p.IsPaid() || p.IsOpen())
+		})
+		unlock()
+		if charged > 0 {
+			return domain.ErrDepositAlreadyCharged
+		}
+	}
+	return r.BaseRepositoryImpl.Create(ctx, payment)
+}
+
+// GetByIDForUpdate retrieves a payment. The store has no row locks to take, so concurrent
+// units of work are not kept apart as they are in the database.
+func (r *paymentRepositoryImpl) GetByIDForUpdate(ctx context.Context, id string) (*domain.Payment, error) {
+	return r.GetByID(ctx, id)
+}
+
+// FindByAppointmentID finds the payments of an appointment, oldest first
+func (r *paymentRepositoryImpl) FindByAppointmentID(ctx context.Context, appointmentID string) ([]*domain.Payment, error) {
+	defer r.db.lock()()
+	return r.table().where(func(p *domain.Payment) bool { return p.AppointmentID == appointmentID }), nil
+}
+
+// FindDepositDue sums the deposits of the services booked for an appointment
+func (r *paymentRepositoryImpl) FindDepositDue(ctx context.Context, appointmentID string) (decimal.Decimal, error) {
+	defer r.db.lock()()
+	services := tableOf[domain.Service](r.db)
+	total := decimal.Zero
+	for _, line := range tableOf[domain.AppointmentLine](r.db).where(func(l *domain.AppointmentLine) bool {
+		return l.AppointmentID == appointmentID
+	}) {
+		service, ok := services.rows[line.ServiceID]
+		if ok && service.RequiresDeposit && service.DepositAmount != nil {
+			total = total.Add(*service.DepositAmount)
+		}
+	}
+	return total, nil
+}
+
+// SetAppointmentPaymentStatus sets the payment status of an appointment
+func (r *paymentRepositoryImpl) SetAppointmentPaymentStatus(ctx context.Context, appointmentID string, status domain.AppointmentPaymentStatus) error {
+	defer r.db.lock()()
+	now := r.db.now()
+	if _, ok := tableOf[domain.Appointment](r.db).rows[appointmentID]; !ok {
+		return nil
+	}
+	paymentOf(r.db, appointmentID).PaymentStatus = &status
+	tableOf[domain.Appointment](r.db).rows[appointmentID].UpdatedAt = now
+	return nil
+}
+
+// SetServiceDeposit sets the deposit charged at booking for a service
+func (r *paymentRepositoryImpl) SetServiceDeposit(ctx context.Context, serviceID string, requiresDeposit bool, amount *decimal.Decimal) error {
+	defer r.db.lock()()
+	now := r.db.now()
+	return found(tableOf[domain.Service](r.db).update(serviceID, func(s *domain.Service) {
+		s.RequiresDeposit = requiresDeposit
+		s.DepositAmount = amount
+		s.UpdatedAt = now
+	}))
+}
+
+// RecordCompletion creates the completion of an appointment paid by card or gift card and marks the
+// appointment completed at the charged price
+func (r *paymentRepositoryImpl) RecordCompletion(ctx context.Context, completion *domain.ServiceCompletion) error {
+	defer r.db.lock()()
+	if err := tableOf[domain.ServiceCompletion](r.db).insert(completion); err != nil {
+		return err
+	}
+
+	appointment, ok := tableOf[domain.Appointment](r.db).rows[completion.AppointmentID]
+	if !ok {
+		return nil
+	}
+	appointment.Status = domain.AppointmentStatusCompleted
+	appointment.UpdatedAt = r.db.now()
+	appointment.UpdatedBy = completion.CreatedBy
+	price, method := completion.PriceCharged, completion.PaymentMethod
+	payment := paymentOf(r.db, completion.AppointmentID)
+	payment.ActualPrice = &price
+	payment.PaymentMethod = &method
+	return nil
+}
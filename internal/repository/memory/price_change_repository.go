@@ -0,0 +1,131 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// priceChangeRepositoryImpl implements the PriceChangeRepository interface
+type priceChangeRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.PriceChange]
+}
+
+// NewPriceChangeRepository creates a new price change repository, scoped to the tenant in the
+// context
+func NewPriceChangeRepository(db *DB) domain.PriceChangeRepository {
+	return &priceChangeRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.PriceChange]{db: db, tenantScoped: true},
+	}
+}
+
+// FindByBusinessID finds all price changes of a business, latest effective date first
+func (r *priceChangeRepositoryImpl) FindByBusinessID(ctx context.Context, businessID string) ([]*domain.PriceChange, error) {
+	defer r.db.lock()()
+	changes := r.table().where(func(c *domain.PriceChange) bool { return c.BusinessID == businessID })
+	slices.SortStableFunc(changes, func(a, b *domain.PriceChange) int { return b.EffectiveAt.Compare(a.EffectiveAt) })
+	return changes, nil
+}
+
+// FindTargetServices finds the business's services that are selected directly or through their category
+func (r *priceChangeRepositoryImpl) FindTargetServices(ctx context.Context, businessID string, serviceIDs, categoryIDs []string) ([]*domain.Service, error) {
+	defer r.db.lock()()
+	return findTargetServices(r.db, businessID, serviceIDs, categoryIDs), nil
+}
+
+// findTargetServices finds the selected services of a business by name. The caller must hold
+// the lock.
+func findTargetServices(db *DB, businessID string, serviceIDs, categoryIDs []string) []*domain.Service {
+	services := tableOf[domain.Service](db).where(func(s *domain.Service) bool {
+		return s.BusinessID == businessID &&
+			(slices.Contains(serviceIDs, s.ID) || (s.CategoryID != nil && slices.Contains(categoryIDs, *s.CategoryID)))
+	})
+	slices.SortStableFunc(services, func(a, b *domain.Service) int { return cmp.Compare(a.Name, b.Name) })
+	return services
+}
+
+// FindDue finds the scheduled price changes whose effective date has passed
+func (r *priceChangeRepositoryImpl) FindDue(ctx context.Context, now time.Time) ([]*domain.PriceChange, error) {
+	defer r.db.lock()()
+	changes := r.table().where(func(c *domain.PriceChange) bool {
+		return c.Status == domain.PriceChangeScheduled && !c.EffectiveAt.After(now)
+	})
+	slices.SortStableFunc(changes, func(a, b *domain.PriceChange) int { return a.EffectiveAt.Compare(b.EffectiveAt) })
+	return changes, nil
+}
+
+// Apply adjusts the current prices of the change's services, records their history and marks
+// the change applied, all at once
+func (r *priceChangeRepositoryImpl) Apply(ctx context.Context, change *domain.PriceChange, appliedAt time.Time) ([]*domain.ServicePriceHistory, error) {
+	defer r.db.lock()()
+	current, err := r.table().get(change.ID)
+	if err != nil {
+		return nil, err
+	}
+	if current.Status != domain.PriceChangeScheduled {
+		*change = *current
+		return nil, nil
+	}
+
+	serviceIDs, err := current.GetServiceIDs()
+	if err != nil {
+		return nil, err
+	}
+	categoryIDs, err := current.GetCategoryIDs()
+	if err != nil {
+		return nil, err
+	}
+	previews, err := domain.PreviewPriceChange(findTargetServices(r.db, current.BusinessID, serviceIDs, categoryIDs), current.Adjustment())
+	if err != nil {
+		return nil, err
+	}
+
+	var history []*domain.ServicePriceHistory
+	for _, preview := range previews {
+		tableOf[domain.Service](r.db).update(preview.ServiceID, func(s *domain.Service) {
+			s.Price = preview.NewPrice
+			s.UpdatedAt = appliedAt
+			s.UpdatedBy = current.UpdatedBy
+		})
+		entry := &domain.ServicePriceHistory{
+			BusinessID:    current.BusinessID,
+			ServiceID:     preview.ServiceID,
+			PriceChangeID: &current.ID,
+			OldPrice:      preview.OldPrice,
+			NewPrice:      preview.NewPrice,
+			ChangedAt:     appliedAt,
+			ChangedBy:     current.UpdatedBy,
+		}
+		if err := tableOf[domain.ServicePriceHistory](r.db).insert(entry); err != nil {
+			return nil, err
+		}
+		history = append(history, entry)
+	}
+
+	current.Status = domain.PriceChangeApplied
+	current.AppliedAt = &appliedAt
+	if err := r.table().save(current); err != nil {
+		return nil, err
+	}
+	*change = *current
+	return history, nil
+}
+
+// FindHistoryByServiceID finds the price history of a service, most recent first
+func (r *priceChangeRepositoryImpl) FindHistoryByServiceID(ctx context.Context, serviceID string) ([]*domain.ServicePriceHistory, error) {
+	defer r.db.lock()()
+	history := tableOf[domain.ServicePriceHistory](r.db).where(func(h *domain.ServicePriceHistory) bool {
+		return h.ServiceID == serviceID
+	})
+	slices.SortStableFunc(history, func(a, b *domain.ServicePriceHistory) int { return b.ChangedAt.Compare(a.ChangedAt) })
+	return history, nil
+}
+
+// WithTx returns the repository itself
+func (r *priceChangeRepositoryImpl) WithTx(tx *gorm.DB) domain.BaseRepository[domain.PriceChange] {
+	return r
+}
@@ -0,0 +1,103 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// reportExportRepositoryImpl implements the ReportExportRepository interface
+type reportExportRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.ReportExport]
+}
+
+// NewReportExportRepository creates a new report export repository, scoped to the tenant in
+// the context
+func NewReportExportRepository(db *DB) domain.ReportExportRepository {
+	return &reportExportRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.ReportExport]{db: db, tenantScoped: true},
+	}
+}
+
+// FindByBusinessID finds the most recent exports of a business, newest first
+func (r *reportExportRepositoryImpl) FindByBusinessID(ctx context.Context, businessID string, limit int) ([]*domain.ReportExport, error) {
+	defer r.db.lock()()
+	inScope := r.scope(ctx)
+	exports := r.table().where(func(e *domain.ReportExport) bool {
+		return e.BusinessID == businessID && (inScope == nil || inScope(e))
+	})
+	slices.SortStableFunc(exports, func(a, b *domain.ReportExport) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+	})
+	return exports[:min(limit, len(exports))], nil
+}
+
+// FindRevenueRows finds the services completed on appointments of a business starting in the
+// range, ordered by completion
+func (r *reportExportRepositoryImpl) FindRevenueRows(ctx context.Context, businessID string, start, end time.Time) ([]*domain.RevenueReportRow, error) {
+	defer r.db.lock()()
+	appointments := tableOf[domain.Appointment](r.db)
+	rows := []*domain.RevenueReportRow{}
+	for _, completion := range tableOf[domain.ServiceCompletion](r.db).where(nil) {
+		appointment, err := appointments.get(completion.AppointmentID)
+		if err != nil || appointment.BusinessID != businessID || appointment.StartTime.Before(start) || !appointment.StartTime.Before(end) {
+			continue
+		}
+		completedAt := appointment.EndTime
+		if completion.CompletionDate != nil {
+			completedAt = *completion.CompletionDate
+		}
+		rows = append(rows, &domain.RevenueReportRow{
+			AppointmentID: appointment.ID,
+			CompletedAt:   completedAt,
+			ClientName:    clientName(r.db, appointment.ClientID),
+			StaffName:     staffName(r.db, appointment.StaffID),
+			Services:      appointmentServiceNames(r.db, appointment.ID),
+			PaymentMethod: completion.PaymentMethod,
+			Amount:        completion.PriceCharged,
+		})
+	}
+	slices.SortStableFunc(rows, func(a, b *domain.RevenueReportRow) int {
+		return cmp.Or(a.CompletedAt.Compare(b.CompletedAt), cmp.Compare(a.AppointmentID, b.AppointmentID))
+	})
+	return rows, nil
+}
+
+// FindAppointmentRows finds the appointments of a business starting in the range, ordered by
+// start time
+func (r *reportExportRepositoryImpl) FindAppointmentRows(ctx context.Context, businessID string, start, end time.Time) ([]*domain.AppointmentReportRow, error) {
+	defer r.db.lock()()
+	appointments := tableOf[domain.Appointment](r.db).where(func(a *domain.Appointment) bool {
+		return a.BusinessID == businessID && !a.StartTime.Before(start) && a.StartTime.Before(end)
+	})
+	slices.SortStableFunc(appointments, func(a, b *domain.Appointment) int {
+		return cmp.Or(a.StartTime.Compare(b.StartTime), cmp.Compare(a.ID, b.ID))
+	})
+	rows := make([]*domain.AppointmentReportRow, len(appointments))
+	for i, appointment := range appointments {
+		rows[i] = &domain.AppointmentReportRow{
+			AppointmentID: appointment.ID,
+			StartTime:     appointment.StartTime,
+			EndTime:       appointment.EndTime,
+			Status:        appointment.Status,
+			ClientName:    clientName(r.db, appointment.ClientID),
+			StaffName:     staffName(r.db, appointment.StaffID),
+			Services:      appointmentServiceNames(r.db, appointment.ID),
+			TotalPrice:    appointment.TotalPrice,
+		}
+	}
+	return rows, nil
+}
+
+// FindClients finds the clients of a business, ordered by last and first name
+func (r *reportExportRepositoryImpl) FindClients(ctx context.Context, businessID string) ([]*domain.Client, error) {
+	defer r.db.lock()()
+	clients := tableOf[domain.Client](r.db).where(func(c *domain.Client) bool { return c.BusinessID == businessID })
+	slices.SortStableFunc(clients, func(a, b *domain.Client) int {
+		return cmp.Or(cmp.Compare(a.LastName, b.LastName), cmp.Compare(a.FirstName, b.FirstName), cmp.Compare(a.ID, b.ID))
+	})
+	return clients, nil
+}
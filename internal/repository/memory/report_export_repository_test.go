@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportExportRepository_FindsRowsWithNames(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	repo := NewReportExportRepository(db)
+
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	user := &domain.User{FirstName: "Sam", LastName: "Reed", Email: "sam@example.com"}
+	require.NoError(t, Insert(db, user))
+	staff := &domain.Staff{BusinessID: "business-1", UserID: user.ID}
+	client := &domain.Client{BusinessID: "business-1", FirstName: "Ana", LastName: "Silva"}
+	cut := &domain.Service{BusinessID: "business-1", Name: "Cut"}
+	colour := &domain.Service{BusinessID: "business-1", Name: "Colour"}
+	require.NoError(t, Insert(db, staff))
+	require.NoError(t, Insert(db, client))
+	require.NoError(t, Insert(db, cut, colour))
+
+	visit := &domain.Appointment{BusinessID: "business-1", StaffID: staff.ID, ClientID: client.ID,
+		StartTime: day.Add(10 * time.Hour), EndTime: day.Add(11 * time.Hour), Status: domain.AppointmentStatusCompleted,
+		TotalPrice: decimal.NewFromInt(80)}
+	later := &domain.Appointment{BusinessID: "business-1", StaffID: staff.ID, ClientID: client.ID,
+		StartTime: day.AddDate(0, 0, 1).Add(10 * time.Hour), Status: domain.AppointmentStatusScheduled}
+	other := &domain.Appointment{BusinessID: "business-2", StaffID: "staff-2", ClientID: "client-2",
+		StartTime: day.Add(12 * time.Hour), Status: domain.AppointmentStatusScheduled}
+	require.NoError(t, Insert(db, visit, later, other))
+	require.NoError(t, Insert(db,
+		&domain.AppointmentLine{AppointmentID: visit.ID, ServiceID: cut.ID, StaffID: staff.ID},
+		&domain.AppointmentLine{AppointmentID: visit.ID, ServiceID: colour.ID, StaffID: staff.ID}))
+	require.NoError(t, Insert(db, &domain.ServiceCompletion{AppointmentID: visit.ID, PriceCharged: decimal.NewFromInt(80), PaymentMethod: "card"}))
+
+	revenue, err := repo.FindRevenueRows(ctx, "business-1", day, day.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	require.Len(t, revenue, 1)
+	assert.Equal(t, "Ana Silva", revenue[0].ClientName)
+	assert.Equal(t, "Sam Reed", revenue[0].StaffName)
+	assert.Equal(t, "Colour, Cut", revenue[0].Services)
+	assert.Equal(t, visit.EndTime, revenue[0].CompletedAt)
+	assert.True(t, decimal.NewFromInt(80).Equal(revenue[0].Amount))
+
+	appointments, err := repo.FindAppointmentRows(ctx, "business-1", day, day.AddDate(0, 0, 2))
+	require.NoError(t, err)
+	require.Len(t, appointments, 2)
+	assert.Equal(t, visit.ID, appointments[0].AppointmentID)
+	assert.Equal(t, later.ID, appointments[1].AppointmentID)
+	assert.Equal(t, "", appointments[1].Services)
+}
+
+func TestReportExportRepository_FindByBusinessIDIsNewestFirst(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	repo := NewReportExportRepository(db)
+
+	for range 3 {
+		require.NoError(t, repo.Create(ctx, &domain.ReportExport{BusinessID: "business-1", Report: domain.ReportClients,
+			Format: domain.ReportFormatCSV, Status: domain.ReportExportPending, FileName: "clients.csv"}))
+	}
+	require.NoError(t, repo.Create(ctx, &domain.ReportExport{BusinessID: "business-2", Report: domain.ReportClients,
+		Format: domain.ReportFormatCSV, Status: domain.ReportExportPending, FileName: "clients.csv"}))
+
+	exports, err := repo.FindByBusinessID(ctx, "business-1", 2)
+	require.NoError(t, err)
+	require.Len(t, exports, 2)
+	assert.False(t, exports[0].CreatedAt.Before(exports[1].CreatedAt))
+	for _, export := range exports {
+		assert.Equal(t, "business-1", export.BusinessID)
+	}
+}
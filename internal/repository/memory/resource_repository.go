@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// resourceRepositoryImpl implements the ResourceRepository interface
+type resourceRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.Resource]
+}
+
+// NewResourceRepository creates a new resource repository, scoped to the tenant in the context
+func NewResourceRepository(db *DB) domain.ResourceRepository {
+	return &resourceRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.Resource]{db: db, tenantScoped: true},
+	}
+}
+
+// FindByBusiness finds the resources of a business by name, only those at a location when one
+// is given
+func (r *resourceRepositoryImpl) FindByBusiness(ctx context.Context, businessID string, locationID *string) ([]*domain.Resource, error) {
+	defer r.db.lock()()
+	inScope := r.scope(ctx)
+	resources := r.table().where(func(res *domain.Resource) bool {
+		return res.BusinessID == businessID && (locationID == nil || (res.LocationID != nil && *res.LocationID == *locationID)) &&
+			(inScope == nil || inScope(res))
+	})
+	sortResources(resources)
+	return resources, nil
+}
+
+// FindByServices finds the active resources the services need, by name
+func (r *resourceRepositoryImpl) FindByServices(ctx context.Context, serviceIDs []string) ([]*domain.Resource, error) {
+	defer r.db.lock()()
+	inScope := r.scope(ctx)
+	return slices.DeleteFunc(resourcesOf(r.db, serviceIDs), func(res *domain.Resource) bool {
+		return inScope != nil && !inScope(res)
+	}), nil
+}
+
+// SetServiceResources replaces the resources a service needs. Replaced rows are removed for
+// good, as a service needs a resource once at most.
+func (r *resourceRepositoryImpl) SetServiceResources(ctx context.Context, serviceID string, rows []*domain.ServiceResource) error {
+	defer r.db.lock()()
+	requirements := tableOf[domain.ServiceResource](r.db)
+	requirements.purgeWhere(func(sr *domain.ServiceResource) bool { return sr.ServiceID == serviceID })
+	for _, row := range rows {
+		if err := requirements.insert(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resourcesOf returns the active resources the services need, by name
+func resourcesOf(db *DB, serviceIDs []string) []*domain.Resource {
+	if len(serviceIDs) == 0 {
+		return nil
+	}
+	requirements := tableOf[domain.ServiceResource](db).where(func(sr *domain.ServiceResource) bool {
+		return slices.Contains(serviceIDs, sr.ServiceID)
+	})
+	resources := tableOf[domain.Resource](db).where(func(res *domain.Resource) bool {
+		return res.IsActive && slices.ContainsFunc(requirements, func(sr *domain.ServiceResource) bool { return sr.ResourceID == res.ID })
+	})
+	sortResources(resources)
+	return resources
+}
+
+// sortResources sorts resources by name, then ID
+func sortResources(resources []*domain.Resource) {
+	slices.SortFunc(resources, func(a, b *domain.Resource) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.ID, b.ID))
+	})
+}
@@ -0,0 +1,51 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppointmentRepository_RejectsOverlappingResourceBookings(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	business := &domain.Business{UserID: "owner-1", Name: "Salon"}
+	require.NoError(t, Insert(db, business))
+	room := &domain.Resource{BusinessID: business.ID, Name: "Laser room", Kind: domain.ResourceRoom, Capacity: 1, IsActive: true}
+	require.NoError(t, Insert(db, room))
+	resources := NewResourceRepository(db)
+	require.NoError(t, resources.SetServiceResources(ctx, "service-laser",
+		[]*domain.ServiceResource{{BusinessID: business.ID, ServiceID: "service-laser", ResourceID: room.ID}}))
+	repo := NewAppointmentRepository(db)
+
+	start := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	book := func(staffID string, from time.Time, serviceID string) error {
+		appointment := &domain.Appointment{BusinessID: business.ID, StaffID: staffID, ClientID: "client-1",
+			StartTime: from, EndTime: from.Add(time.Hour), Status: domain.AppointmentStatusScheduled}
+		return repo.CreateWithLines(ctx, appointment, []*domain.AppointmentLine{{ServiceID: serviceID, StaffID: staffID, Duration: 60}})
+	}
+	require.NoError(t, book("staff-1", start, "service-laser"))
+
+	var conflict *domain.AppointmentConflictError
+	require.ErrorAs(t, book("staff-2", start.Add(30*time.Minute), "service-laser"), &conflict)
+	require.Len(t, conflict.Conflicts, 1)
+	assert.Equal(t, domain.ConflictResourceBusy, conflict.Conflicts[0].Reason)
+	assert.Equal(t, room.ID, *conflict.Conflicts[0].ResourceID)
+
+	assert.NoError(t, book("staff-2", start.Add(30*time.Minute), "service-haircut"), "services without the room are unaffected")
+	assert.NoError(t, book("staff-3", start.Add(time.Hour), "service-laser"), "the room is free once the first appointment ends")
+
+	conflicts, err := repo.CheckAvailability(ctx, domain.AvailabilityRequest{BusinessID: business.ID, StaffID: "staff-4",
+		Start: start, End: start.Add(time.Hour), ServiceIDs: []string{"service-laser"}})
+	require.NoError(t, err)
+	require.Len(t, conflicts, 1)
+
+	found, err := resources.FindByServices(ctx, []string{"service-laser", "service-haircut"})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, room.ID, found[0].ID)
+}
@@ -0,0 +1,143 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// reviewRepositoryImpl implements the ReviewRepository interface
+type reviewRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.Review]
+}
+
+// NewReviewRepository creates a new review repository, scoped to the tenant in the context
+func NewReviewRepository(db *DB) domain.ReviewRepository {
+	return &reviewRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.Review]{db: db, tenantScoped: true},
+	}
+}
+
+// FindByBusiness finds the reviews of a business, newest first
+func (r *reviewRepositoryImpl) FindByBusiness(ctx context.Context, businessID string, filter domain.ReviewFilter, page, pageSize int) ([]*domain.Review, int64, error) {
+	defer r.db.lock()()
+	inScope := r.scope(ctx)
+	reviews := r.table().where(func(v *domain.Review) bool {
+		return v.BusinessID == businessID && (inScope == nil || inScope(v)) &&
+			(filter.Status == nil || v.Status == *filter.Status) &&
+			(filter.StaffID == nil || v.StaffID == *filter.StaffID)
+	})
+	slices.SortStableFunc(reviews, func(a, b *domain.Review) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(b.ID, a.ID))
+	})
+	return paginate(reviews, page, pageSize), int64(len(reviews)), nil
+}
+
+// FindReviewable finds what a review of an appointment is about
+func (r *reviewRepositoryImpl) FindReviewable(ctx context.Context, appointmentID string) (*domain.ReviewableAppointment, error) {
+	defer r.db.lock()()
+	appointment, err := tableOf[domain.Appointment](r.db).get(appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	business, err := tableOf[domain.Business](r.db).get(appointment.BusinessID)
+	if err != nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	client, ok := clientContact(r.db, appointment.ClientID)
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	reviewable := &domain.ReviewableAppointment{
+		AppointmentID:    appointment.ID,
+		BusinessID:       appointment.BusinessID,
+		BusinessName:     business.GetDisplayName(),
+		TimeZone:         business.TimeZone,
+		ClientID:         appointment.ClientID,
+		ClientFirstName:  client.FirstName,
+		ClientEmail:      client.Email,
+		ClientAnonymized: client.AnonymizedAt != nil,
+		StaffID:          appointment.StaffID,
+		StaffName:        staffName(r.db, appointment.StaffID),
+		Status:           appointment.Status,
+		StartTime:        appointment.StartTime,
+		Reviewed:         r.table().count(func(v *domain.Review) bool { return v.AppointmentID == appointment.ID }) > 0,
+	}
+	lines := tableOf[domain.AppointmentLine](r.db)
+	appointmentLines := lines.where(func(l *domain.AppointmentLine) bool { return l.AppointmentID == appointment.ID })
+	slices.SortStableFunc(appointmentLines, lines.compareCreated)
+	if len(appointmentLines) > 0 {
+		reviewable.ServiceID = appointmentLines[0].ServiceID
+		if service, err := tableOf[domain.Service](r.db).get(reviewable.ServiceID); err == nil {
+			reviewable.ServiceName = service.Name
+		}
+	}
+	return reviewable, nil
+}
+
+// FindRequestByAppointment finds the review request sent for an appointment
+func (r *reviewRepositoryImpl) FindRequestByAppointment(ctx context.Context, appointmentID string) (*domain.ReviewRequest, error) {
+	defer r.db.lock()()
+	return tableOf[domain.ReviewRequest](r.db).first(func(q *domain.ReviewRequest) bool { return q.AppointmentID == appointmentID })
+}
+
+// FindRequestByToken finds a review request by the token in its link
+func (r *reviewRepositoryImpl) FindRequestByToken(ctx context.Context, token string) (*domain.ReviewRequest, error) {
+	defer r.db.lock()()
+	return tableOf[domain.ReviewRequest](r.db).first(func(q *domain.ReviewRequest) bool { return q.Token == token })
+}
+
+// SaveRequest creates a request or updates an existing one
+func (r *reviewRepositoryImpl) SaveRequest(ctx context.Context, request *domain.ReviewRequest) error {
+	defer r.db.lock()()
+	return tableOf[domain.ReviewRequest](r.db).save(request)
+}
+
+// Submit uses the request and stores the review at once
+func (r *reviewRepositoryImpl) Submit(ctx context.Context, requestID string, review *domain.Review, at time.Time) error {
+	defer r.db.lock()()
+	used := tableOf[domain.ReviewRequest](r.db).updateWhere(func(q *domain.ReviewRequest) bool {
+		return q.ID == requestID && q.ReviewedAt == nil
+	}, func(q *domain.ReviewRequest) {
+		q.ReviewedAt = &at
+		q.UpdatedAt = at
+	})
+	if used == 0 {
+		return domain.ErrReviewRequestUsed
+	}
+	return r.table().insert(review)
+}
+
+// RefreshRatings works out the ratings of a staff member and their business again from their
+// published reviews
+func (r *reviewRepositoryImpl) RefreshRatings(ctx context.Context, businessID, staffID string) error {
+	defer r.db.lock()()
+	staffAverage, staffCount := r.rating(func(v *domain.Review) bool { return v.StaffID == staffID })
+	tableOf[domain.Staff](r.db).update(staffID, func(s *domain.Staff) {
+		s.AverageRating, s.ReviewCount = staffAverage, staffCount
+	})
+	businessAverage, businessCount := r.rating(func(v *domain.Review) bool { return v.BusinessID == businessID })
+	tableOf[domain.Business](r.db).update(businessID, func(b *domain.Business) {
+		b.AverageRating, b.ReviewCount = businessAverage, businessCount
+	})
+	return nil
+}
+
+// rating averages the published reviews matching a filter, rounded to two decimals
+func (r *reviewRepositoryImpl) rating(match func(v *domain.Review) bool) (*decimal.Decimal, int) {
+	reviews := r.table().where(func(v *domain.Review) bool { return match(v) && v.Status == domain.ReviewPublished })
+	if len(reviews) == 0 {
+		return nil, 0
+	}
+	total := 0
+	for _, review := range reviews {
+		total += review.Rating
+	}
+	average := decimal.NewFromInt(int64(total)).DivRound(decimal.NewFromInt(int64(len(reviews))), 2)
+	return &average, len(reviews)
+}
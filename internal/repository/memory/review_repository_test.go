@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReviewRepository_SubmitsReviewsAndRefreshesRatings(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	repo := NewReviewRepository(db)
+
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	business := &domain.Business{Name: "Studio", Email: "studio@example.com", TimeZone: "Europe/Lisbon"}
+	require.NoError(t, Insert(db, business))
+	user := &domain.User{FirstName: "Ana", LastName: "Silva"}
+	require.NoError(t, Insert(db, user))
+	staff := &domain.Staff{BusinessID: business.ID, UserID: user.ID}
+	client := &domain.Client{BusinessID: business.ID, FirstName: "Rita", LastName: "Costa", Email: "rita@example.com"}
+	service := &domain.Service{BusinessID: business.ID, Name: "Haircut"}
+	require.NoError(t, Insert(db, staff))
+	require.NoError(t, Insert(db, client))
+	require.NoError(t, Insert(db, service))
+	visit := &domain.Appointment{BusinessID: business.ID, StaffID: staff.ID, ClientID: client.ID, StartTime: now,
+		Status: domain.AppointmentStatusCompleted}
+	require.NoError(t, Insert(db, visit))
+	require.NoError(t, Insert(db, &domain.AppointmentLine{AppointmentID: visit.ID, ServiceID: service.ID, StaffID: staff.ID}))
+
+	reviewable, err := repo.FindReviewable(ctx, visit.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Studio", reviewable.BusinessName)
+	assert.Equal(t, "Rita", reviewable.ClientFirstName)
+	assert.Equal(t, "rita@example.com", reviewable.ClientEmail)
+	assert.Equal(t, "Ana Silva", reviewable.StaffName)
+	assert.Equal(t, "Haircut", reviewable.ServiceName)
+	assert.True(t, reviewable.CanBeReviewed())
+
+	request := &domain.ReviewRequest{BusinessID: business.ID, AppointmentID: visit.ID, Token: "token-1", ExpiresAt: now.Add(domain.ReviewRequestValidity)}
+	require.NoError(t, repo.SaveRequest(ctx, request))
+	review, err := domain.NewReview(reviewable, 4, "", nil)
+	require.NoError(t, err)
+	require.NoError(t, repo.Submit(ctx, request.ID, review, now))
+	assert.ErrorIs(t, repo.Submit(ctx, request.ID, review, now), domain.ErrReviewRequestUsed)
+
+	stored, err := repo.FindRequestByToken(ctx, "token-1")
+	require.NoError(t, err)
+	assert.Equal(t, now, *stored.ReviewedAt)
+	reviewable, err = repo.FindReviewable(ctx, visit.ID)
+	require.NoError(t, err)
+	assert.False(t, reviewable.CanBeReviewed(), "an appointment is reviewed once")
+
+	comment := "Great"
+	require.NoError(t, Insert(db, &domain.Review{BusinessID: business.ID, AppointmentID: "appointment-2", ClientID: client.ID,
+		StaffID: staff.ID, ServiceID: service.ID, Rating: 5, Status: domain.ReviewPublished},
+		&domain.Review{BusinessID: business.ID, AppointmentID: "appointment-3", ClientID: client.ID,
+			StaffID: staff.ID, ServiceID: service.ID, Rating: 1, Comment: &comment, Status: domain.ReviewPending}))
+	require.NoError(t, repo.RefreshRatings(ctx, business.ID, staff.ID))
+
+	ratedStaff, err := NewStaffRepository(db).GetByID(ctx, staff.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "4.5", ratedStaff.AverageRating.String(), "pending reviews are not counted")
+	assert.Equal(t, 2, ratedStaff.ReviewCount)
+	ratedBusiness, err := NewBusinessRepository(db).GetByID(ctx, business.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "4.5", ratedBusiness.AverageRating.String())
+	assert.Equal(t, 2, ratedBusiness.ReviewCount)
+
+	pending := domain.ReviewPending
+	reviews, total, err := repo.FindByBusiness(ctx, business.ID, domain.ReviewFilter{Status: &pending}, 1, 20)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, 1, reviews[0].Rating)
+}
@@ -0,0 +1,51 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// savedViewRepositoryImpl implements the SavedViewRepository interface
+type savedViewRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.SavedView]
+}
+
+// NewSavedViewRepository creates a new saved view repository, scoped to the tenant in the context
+func NewSavedViewRepository(db *DB) domain.SavedViewRepository {
+	return &savedViewRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.SavedView]{db: db, tenantScoped: true},
+	}
+}
+
+// FindVisible finds the views of a business a user with a role can use, optionally of one list
+// only, ordered by name
+func (r *savedViewRepositoryImpl) FindVisible(ctx context.Context, businessID, userID string, role domain.BusinessRole, entity *domain.SavedViewEntity) ([]*domain.SavedView, error) {
+	defer r.db.lock()()
+	views := r.table().where(func(v *domain.SavedView) bool {
+		return v.BusinessID == businessID && (entity == nil || v.Entity == *entity) && v.VisibleTo(userID, role)
+	})
+	slices.SortStableFunc(views, func(a, b *domain.SavedView) int {
+		return cmp.Or(cmp.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)), cmp.Compare(a.ID, b.ID))
+	})
+	return views, nil
+}
+
+// FindByOwnerAndName finds a user's view of a list by name, ignoring case
+func (r *savedViewRepositoryImpl) FindByOwnerAndName(ctx context.Context, businessID, userID string, entity domain.SavedViewEntity, name string) (*domain.SavedView, error) {
+	defer r.db.lock()()
+	return r.table().first(func(v *domain.SavedView) bool {
+		return v.BusinessID == businessID && v.UserID == userID && v.Entity == entity && strings.EqualFold(v.Name, name)
+	})
+}
+
+// CountByOwner counts a user's views of a list
+func (r *savedViewRepositoryImpl) CountByOwner(ctx context.Context, businessID, userID string, entity domain.SavedViewEntity) (int64, error) {
+	defer r.db.lock()()
+	return r.table().count(func(v *domain.SavedView) bool {
+		return v.BusinessID == businessID && v.UserID == userID && v.Entity == entity
+	}), nil
+}
@@ -0,0 +1,43 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// searchRepositoryImpl implements the SearchRepository interface
+type searchRepositoryImpl struct {
+	db *DB
+}
+
+// NewSearchRepository creates a new search repository
+func NewSearchRepository(db *DB) domain.SearchRepository {
+	return &searchRepositoryImpl{db: db}
+}
+
+// Search matches clients and services as domain.RankClient and domain.RankService do
+func (r *searchRepositoryImpl) Search(ctx context.Context, query *domain.SearchQuery) ([]*domain.SearchResult, error) {
+	defer r.db.lock()()
+	var results []*domain.SearchResult
+	if query.Includes(domain.SearchResultClient) {
+		for _, client := range tableOf[domain.Client](r.db).where(func(c *domain.Client) bool { return c.BusinessID == query.BusinessID }) {
+			if rank, ok := domain.RankClient(client, query); ok {
+				results = append(results, &domain.SearchResult{Kind: domain.SearchResultClient, Client: client, Rank: rank})
+			}
+		}
+	}
+	if query.Includes(domain.SearchResultService) {
+		for _, service := range tableOf[domain.Service](r.db).where(func(s *domain.Service) bool { return s.BusinessID == query.BusinessID }) {
+			if rank, ok := domain.RankService(service, query); ok {
+				results = append(results, &domain.SearchResult{Kind: domain.SearchResultService, Service: service, Rank: rank})
+			}
+		}
+	}
+
+	domain.SortSearchResults(results)
+	if len(results) > query.Limit {
+		results = results[:query.Limit]
+	}
+	return results, nil
+}
@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// serviceBundleRepositoryImpl implements the ServiceBundleRepository interface
+type serviceBundleRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.ServiceBundle]
+}
+
+// NewServiceBundleRepository creates a new service bundle repository, scoped to the tenant in the
+// context
+func NewServiceBundleRepository(db *DB) domain.ServiceBundleRepository {
+	return &serviceBundleRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.ServiceBundle]{db: db, tenantScoped: true},
+	}
+}
+
+// FindByBusinessID finds the bundles of a business, optionally only the bookable ones
+func (r *serviceBundleRepositoryImpl) FindByBusinessID(ctx context.Context, businessID string, activeOnly bool) ([]*domain.ServiceBundle, error) {
+	defer r.db.lock()()
+	bundles := r.table().where(func(b *domain.ServiceBundle) bool {
+		return b.BusinessID == businessID && (!activeOnly || b.IsActive)
+	})
+	slices.SortStableFunc(bundles, func(a, b *domain.ServiceBundle) int { return cmp.Compare(a.Name, b.Name) })
+	return bundles, nil
+}
+
+// ExistsByNameAndBusiness checks whether the business has another bundle with the name
+func (r *serviceBundleRepositoryImpl) ExistsByNameAndBusiness(ctx context.Context, name, businessID string, excludeID *string) (bool, error) {
+	defer r.db.lock()()
+	count := r.table().count(func(b *domain.ServiceBundle) bool {
+		return b.BusinessID == businessID && strings.EqualFold(b.Name, name) && (excludeID == nil || b.ID != *excludeID)
+	})
+	return count > 0, nil
+}
+
+// FindServices finds the business's services with the given IDs, keyed by ID
+func (r *serviceBundleRepositoryImpl) FindServices(ctx context.Context, businessID string, serviceIDs []string) (map[string]*domain.Service, error) {
+	defer r.db.lock()()
+	services := map[string]*domain.Service{}
+	found := tableOf[domain.Service](r.db).where(func(s *domain.Service) bool {
+		return s.BusinessID == businessID && slices.Contains(serviceIDs, s.ID)
+	})
+	for _, service := range found {
+		services[service.ID] = service
+	}
+	return services, nil
+}
@@ -0,0 +1,30 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// serviceCompletionRepositoryImpl implements the ServiceCompletionRepository interface
+type serviceCompletionRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.ServiceCompletion]
+}
+
+// NewServiceCompletionRepository creates a new service completion repository
+func NewServiceCompletionRepository(db *DB) domain.ServiceCompletionRepository {
+	return &serviceCompletionRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.ServiceCompletion]{db: db},
+	}
+}
+
+// FindByAppointmentID finds the completion record for an appointment
+func (r *serviceCompletionRepositoryImpl) FindByAppointmentID(ctx context.Context, appointmentID string) (*domain.ServiceCompletion, error) {
+	defer r.db.lock()()
+	completions := r.table().where(func(c *domain.ServiceCompletion) bool { return c.AppointmentID == appointmentID })
+	if len(completions) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return completions[len(completions)-1], nil
+}
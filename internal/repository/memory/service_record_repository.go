@@ -0,0 +1,139 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// serviceRecordTemplateRepositoryImpl implements the ServiceRecordTemplateRepository interface
+type serviceRecordTemplateRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.ServiceRecordTemplate]
+}
+
+// NewServiceRecordTemplateRepository creates a new service record template repository, scoped to
+// the tenant in the context
+func NewServiceRecordTemplateRepository(db *DB) domain.ServiceRecordTemplateRepository {
+	return &serviceRecordTemplateRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.ServiceRecordTemplate]{db: db, tenantScoped: true},
+	}
+}
+
+// FindByBusinessID finds all templates for a business
+func (r *serviceRecordTemplateRepositoryImpl) FindByBusinessID(ctx context.Context, businessID string) ([]*domain.ServiceRecordTemplate, error) {
+	defer r.db.lock()()
+	templates := r.table().where(func(t *domain.ServiceRecordTemplate) bool { return t.BusinessID == businessID })
+	slices.SortStableFunc(templates, func(a, b *domain.ServiceRecordTemplate) int { return cmp.Compare(a.Name, b.Name) })
+	return templates, nil
+}
+
+// FindActiveByCategory finds the active template for a service category
+func (r *serviceRecordTemplateRepositoryImpl) FindActiveByCategory(ctx context.Context, businessID, categoryID string) (*domain.ServiceRecordTemplate, error) {
+	defer r.db.lock()()
+	templates := r.table().where(func(t *domain.ServiceRecordTemplate) bool {
+		return t.BusinessID == businessID && t.CategoryID != nil && *t.CategoryID == categoryID && t.IsActive
+	})
+	if len(templates) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	slices.SortStableFunc(templates, func(a, b *domain.ServiceRecordTemplate) int { return b.UpdatedAt.Compare(a.UpdatedAt) })
+	return templates[0], nil
+}
+
+// serviceRecordRepositoryImpl implements the ServiceRecordRepository interface
+type serviceRecordRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.ServiceRecord]
+}
+
+// NewServiceRecordRepository creates a new service record repository, scoped to the tenant in the
+// context
+func NewServiceRecordRepository(db *DB) domain.ServiceRecordRepository {
+	return &serviceRecordRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.ServiceRecord]{db: db, tenantScoped: true},
+	}
+}
+
+// FindByClientID finds a client's service records, most recent first
+func (r *serviceRecordRepositoryImpl) FindByClientID(ctx context.Context, clientID string, page, pageSize int) ([]*domain.ServiceRecord, int64, error) {
+	defer r.db.lock()()
+	records := r.table().where(func(s *domain.ServiceRecord) bool { return s.ClientID == clientID })
+	sortByRecorded(records)
+	slices.Reverse(records)
+	return paginate(records, page, pageSize), int64(len(records)), nil
+}
+
+// FindByAppointmentID finds the service records captured for an appointment
+func (r *serviceRecordRepositoryImpl) FindByAppointmentID(ctx context.Context, appointmentID string) ([]*domain.ServiceRecord, error) {
+	defer r.db.lock()()
+	records := r.table().where(func(s *domain.ServiceRecord) bool {
+		return s.AppointmentID != nil && *s.AppointmentID == appointmentID
+	})
+	sortByRecorded(records)
+	return records, nil
+}
+
+// FindLatestByClient finds the client's most recent non-draft record, optionally for a specific service
+func (r *serviceRecordRepositoryImpl) FindLatestByClient(ctx context.Context, clientID string, serviceID *string) (*domain.ServiceRecord, error) {
+	defer r.db.lock()()
+	records := r.table().where(func(s *domain.ServiceRecord) bool {
+		return s.ClientID == clientID && !s.IsDraft &&
+			(serviceID == nil || (s.ServiceID != nil && *s.ServiceID == *serviceID))
+	})
+	if len(records) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	sortByRecorded(records)
+	return records[len(records)-1], nil
+}
+
+// FindLatestByClients finds the latest record of each of the clients that has one
+func (r *serviceRecordRepositoryImpl) FindLatestByClients(ctx context.Context, clientIDs []string) ([]*domain.ServiceRecord, error) {
+	defer r.db.lock()()
+	records := r.table().where(func(s *domain.ServiceRecord) bool {
+		return slices.Contains(clientIDs, s.ClientID) && !s.IsDraft
+	})
+	sortByRecorded(records)
+	latest := map[string]*domain.ServiceRecord{}
+	for _, record := range records {
+		latest[record.ClientID] = record
+	}
+	result := make([]*domain.ServiceRecord, 0, len(latest))
+	for _, record := range records {
+		if latest[record.ClientID] == record {
+			result = append(result, record)
+		}
+	}
+	return result, nil
+}
+
+// Search searches a business's service record history. The text query matches the notes,
+// field values and products used without regard to case.
+func (r *serviceRecordRepositoryImpl) Search(ctx context.Context, businessID string, criteria domain.ServiceRecordSearch, page, pageSize int) ([]*domain.ServiceRecord, int64, error) {
+	defer r.db.lock()()
+	query := strings.ToLower(criteria.Query)
+	contains := func(s *string) bool { return s != nil && strings.Contains(strings.ToLower(*s), query) }
+	records := r.table().where(func(s *domain.ServiceRecord) bool {
+		switch {
+		case s.BusinessID != businessID,
+			criteria.ClientID != nil && s.ClientID != *criteria.ClientID,
+			criteria.ServiceID != nil && (s.ServiceID == nil || *s.ServiceID != *criteria.ServiceID),
+			criteria.StaffID != nil && (s.StaffID == nil || *s.StaffID != *criteria.StaffID),
+			criteria.DateRange != nil && !inRange(s.RecordedAt, criteria.DateRange.Start, criteria.DateRange.End),
+			!criteria.IncludeDrafts && s.IsDraft:
+			return false
+		}
+		return query == "" || contains(s.Notes) || contains(s.FieldValues) || contains(s.ProductsUsed)
+	})
+	sortByRecorded(records)
+	slices.Reverse(records)
+	return paginate(records, page, pageSize), int64(len(records)), nil
+}
+
+// sortByRecorded orders service records by the time they were recorded
+func sortByRecorded(records []*domain.ServiceRecord) {
+	slices.SortStableFunc(records, func(a, b *domain.ServiceRecord) int { return a.RecordedAt.Compare(b.RecordedAt) })
+}
@@ -0,0 +1,139 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/shopspring/decimal"
+)
+
+// serviceRepositoryImpl implements the ServiceRepository interface
+type serviceRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.Service]
+}
+
+// NewServiceRepository creates a new service repository
+func NewServiceRepository(db *DB) domain.ServiceRepository {
+	return &serviceRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.Service]{db: db},
+	}
+}
+
+// FindByBusinessID finds all services of a business in display order
+func (r *serviceRepositoryImpl) FindByBusinessID(ctx context.Context, businessID string) ([]*domain.Service, error) {
+	defer r.db.lock()()
+	return sortServices(r.table().where(func(s *domain.Service) bool { return s.BusinessID == businessID })), nil
+}
+
+// FindByCategory finds the services of a category in display order
+func (r *serviceRepositoryImpl) FindByCategory(ctx context.Context, businessID, categoryID string) ([]*domain.Service, error) {
+	defer r.db.lock()()
+	return sortServices(r.table().where(func(s *domain.Service) bool {
+		return s.BusinessID == businessID && s.CategoryID != nil && *s.CategoryID == categoryID
+	})), nil
+}
+
+// FindActiveByBusiness finds the active services of a business in display order
+func (r *serviceRepositoryImpl) FindActiveByBusiness(ctx context.Context, businessID string) ([]*domain.Service, error) {
+	defer r.db.lock()()
+	return sortServices(r.table().where(func(s *domain.Service) bool { return s.BusinessID == businessID && s.IsActive })), nil
+}
+
+// UpdatePricing updates the price of a service
+func (r *serviceRepositoryImpl) UpdatePricing(ctx context.Context, serviceID string, price decimal.Decimal) error {
+	defer r.db.lock()()
+	now := r.db.now()
+	return found(r.table().update(serviceID, func(s *domain.Service) {
+		s.Price = price
+		s.UpdatedAt = now
+	}))
+}
+
+// ReorderServices sets the display order of the business's services
+func (r *serviceRepositoryImpl) ReorderServices(ctx context.Context, businessID string, serviceOrders []domain.ServiceOrder) error {
+	defer r.db.lock()()
+	now := r.db.now()
+	for _, order := range serviceOrders {
+		r.table().updateWhere(func(s *domain.Service) bool {
+			return s.ID == order.ServiceID && s.BusinessID == businessID
+		}, func(s *domain.Service) {
+			s.DisplayOrder = order.DisplayOrder
+			s.UpdatedAt = now
+		})
+	}
+	return nil
+}
+
+// ExistsByNameAndBusiness checks whether the business has a service with the name
+func (r *serviceRepositoryImpl) ExistsByNameAndBusiness(ctx context.Context, name, businessID string) (bool, error) {
+	defer r.db.lock()()
+	count := r.table().count(func(s *domain.Service) bool {
+		return s.BusinessID == businessID && strings.EqualFold(s.Name, name)
+	})
+	return count > 0, nil
+}
+
+// sortServices orders services by display order, then name
+func sortServices(services []*domain.Service) []*domain.Service {
+	slices.SortStableFunc(services, func(a, b *domain.Service) int {
+		return cmp.Or(cmp.Compare(a.DisplayOrder, b.DisplayOrder), cmp.Compare(a.Name, b.Name))
+	})
+	return services
+}
+
+// serviceCategoryRepositoryImpl implements the ServiceCategoryRepository interface
+type serviceCategoryRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.ServiceCategory]
+}
+
+// NewServiceCategoryRepository creates a new service category repository
+func NewServiceCategoryRepository(db *DB) domain.ServiceCategoryRepository {
+	return &serviceCategoryRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.ServiceCategory]{db: db},
+	}
+}
+
+// FindByBusinessID finds all categories of a business by name
+func (r *serviceCategoryRepositoryImpl) FindByBusinessID(ctx context.Context, businessID string) ([]*domain.ServiceCategory, error) {
+	defer r.db.lock()()
+	categories := r.table().where(func(c *domain.ServiceCategory) bool { return c.BusinessID == businessID })
+	slices.SortStableFunc(categories, func(a, b *domain.ServiceCategory) int { return cmp.Compare(a.Name, b.Name) })
+	return categories, nil
+}
+
+// GetByDisplayOrder finds all categories of a business in display order
+func (r *serviceCategoryRepositoryImpl) GetByDisplayOrder(ctx context.Context, businessID string) ([]*domain.ServiceCategory, error) {
+	defer r.db.lock()()
+	categories := r.table().where(func(c *domain.ServiceCategory) bool { return c.BusinessID == businessID })
+	slices.SortStableFunc(categories, func(a, b *domain.ServiceCategory) int {
+		return cmp.Or(cmp.Compare(a.DisplayOrder, b.DisplayOrder), cmp.Compare(a.Name, b.Name))
+	})
+	return categories, nil
+}
+
+// ExistsByNameAndBusiness checks whether the business has a category with the name
+func (r *serviceCategoryRepositoryImpl) ExistsByNameAndBusiness(ctx context.Context, name, businessID string) (bool, error) {
+	defer r.db.lock()()
+	count := r.table().count(func(c *domain.ServiceCategory) bool {
+		return c.BusinessID == businessID && strings.EqualFold(c.Name, name)
+	})
+	return count > 0, nil
+}
+
+// ReorderCategories sets the display order of the business's categories
+func (r *serviceCategoryRepositoryImpl) ReorderCategories(ctx context.Context, businessID string, categoryOrders []domain.CategoryOrder) error {
+	defer r.db.lock()()
+	now := r.db.now()
+	for _, order := range categoryOrders {
+		r.table().updateWhere(func(c *domain.ServiceCategory) bool {
+			return c.ID == order.CategoryID && c.BusinessID == businessID
+		}, func(c *domain.ServiceCategory) {
+			c.DisplayOrder = order.DisplayOrder
+			c.UpdatedAt = now
+		})
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// staffBookingPageRepositoryImpl implements the StaffBookingPageRepository interface
+type staffBookingPageRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.StaffBookingPage]
+}
+
+// NewStaffBookingPageRepository creates a new staff booking page repository, scoped to the tenant
+// in the context
+func NewStaffBookingPageRepository(db *DB) domain.StaffBookingPageRepository {
+	return &staffBookingPageRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.StaffBookingPage]{db: db, tenantScoped: true},
+	}
+}
+
+// FindBySlug finds a booking page by its slug
+func (r *staffBookingPageRepositoryImpl) FindBySlug(ctx context.Context, slug string) (*domain.StaffBookingPage, error) {
+	defer r.db.lock()()
+	return r.table().first(func(p *domain.StaffBookingPage) bool { return p.Slug == slug })
+}
+
+// FindByStaffID finds the booking page of a staff member
+func (r *staffBookingPageRepositoryImpl) FindByStaffID(ctx context.Context, staffID string) (*domain.StaffBookingPage, error) {
+	defer r.db.lock()()
+	return r.table().first(func(p *domain.StaffBookingPage) bool { return p.StaffID == staffID })
+}
+
+// SlugExists checks whether another page has the slug. Deleted pages keep their slug, which
+// the unique index still covers.
+func (r *staffBookingPageRepositoryImpl) SlugExists(ctx context.Context, slug string, excludeID *string) (bool, error) {
+	defer r.db.lock()()
+	count := r.table().countUnscoped(func(p *domain.StaffBookingPage) bool {
+		return p.Slug == slug && (excludeID == nil || p.ID != *excludeID)
+	})
+	return count > 0, nil
+}
@@ -0,0 +1,132 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// staffPerformanceRepositoryImpl implements the StaffPerformanceRepository interface
+type staffPerformanceRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.StaffPerformance]
+}
+
+// NewStaffPerformanceRepository creates a new staff performance repository, scoped to the
+// tenant in the context
+func NewStaffPerformanceRepository(db *DB) domain.StaffPerformanceRepository {
+	return &staffPerformanceRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.StaffPerformance]{db: db, tenantScoped: true},
+	}
+}
+
+// FindBusinessesWithAppointments finds the businesses with appointments starting in the range
+func (r *staffPerformanceRepositoryImpl) FindBusinessesWithAppointments(ctx context.Context, start, end time.Time) ([]string, error) {
+	defer r.db.lock()()
+	businessIDs := []string{}
+	for _, appointment := range tableOf[domain.Appointment](r.db).where(func(a *domain.Appointment) bool {
+		return !a.StartTime.Before(start) && a.StartTime.Before(end)
+	}) {
+		if !slices.Contains(businessIDs, appointment.BusinessID) {
+			businessIDs = append(businessIDs, appointment.BusinessID)
+		}
+	}
+	return businessIDs, nil
+}
+
+// FindPerformanceAppointments finds the appointments of a business starting in the range, with
+// what was charged for them, their rating and the first visit of their client
+func (r *staffPerformanceRepositoryImpl) FindPerformanceAppointments(ctx context.Context, businessID string, start, end time.Time) ([]*domain.PerformanceAppointment, error) {
+	defer r.db.lock()()
+	firstVisits := map[string]time.Time{}
+	for _, visit := range tableOf[domain.Appointment](r.db).where(func(a *domain.Appointment) bool {
+		return a.BusinessID == businessID && a.Status == domain.AppointmentStatusCompleted
+	}) {
+		if first, ok := firstVisits[visit.ClientID]; !ok || visit.StartTime.Before(first) {
+			firstVisits[visit.ClientID] = visit.StartTime
+		}
+	}
+
+	appointments := tableOf[domain.Appointment](r.db).where(func(a *domain.Appointment) bool {
+		return a.BusinessID == businessID && !a.StartTime.Before(start) && a.StartTime.Before(end)
+	})
+	slices.SortStableFunc(appointments, func(a, b *domain.Appointment) int {
+		return cmp.Or(a.StartTime.Compare(b.StartTime), cmp.Compare(a.ID, b.ID))
+	})
+	completions := tableOf[domain.ServiceCompletion](r.db)
+	reviews := tableOf[domain.Review](r.db)
+	result := make([]*domain.PerformanceAppointment, len(appointments))
+	for i, appointment := range appointments {
+		found := &domain.PerformanceAppointment{
+			StaffID:   appointment.StaffID,
+			ClientID:  appointment.ClientID,
+			Status:    appointment.Status,
+			StartTime: appointment.StartTime,
+		}
+		for _, completion := range completions.where(func(c *domain.ServiceCompletion) bool { return c.AppointmentID == appointment.ID }) {
+			found.Revenue = found.Revenue.Add(completion.PriceCharged)
+		}
+		if review, err := reviews.first(func(s *domain.Review) bool {
+			return s.AppointmentID == appointment.ID && s.Status == domain.ReviewPublished
+		}); err == nil {
+			found.Rating = &review.Rating
+		}
+		if first, ok := firstVisits[appointment.ClientID]; ok {
+			found.FirstVisit = &first
+		}
+		result[i] = found
+	}
+	return result, nil
+}
+
+// ReplacePeriod stores the records of a period of a business. Records of the same staff member
+// and period start are updated in place, restoring them if deleted, and those of staff members
+// left out are removed for good.
+func (r *staffPerformanceRepositoryImpl) ReplacePeriod(ctx context.Context, businessID string, period domain.PerformancePeriod, start time.Time, records []*domain.StaffPerformance) error {
+	defer r.db.lock()()
+	table := r.table()
+	inPeriod := func(p *domain.StaffPerformance) bool {
+		return p.BusinessID == businessID && p.Period == period && p.StartDate.Equal(start)
+	}
+	table.purgeWhere(func(p *domain.StaffPerformance) bool {
+		return inPeriod(p) && !slices.ContainsFunc(records, func(record *domain.StaffPerformance) bool { return record.StaffID == p.StaffID })
+	})
+
+	for _, record := range records {
+		var existing *domain.StaffPerformance
+		for _, row := range table.rows {
+			if inPeriod(row) && row.StaffID == record.StaffID {
+				existing = row
+			}
+		}
+		if existing == nil {
+			if err := table.insert(record); err != nil {
+				return err
+			}
+			continue
+		}
+		record.ID, record.CreatedAt, record.CreatedBy = existing.ID, existing.CreatedAt, existing.CreatedBy
+		record.Version = existing.Version + 1
+		if err := table.save(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FindInRange finds the records of a business for periods of the kind starting in the range,
+// ordered by period start and staff ID
+func (r *staffPerformanceRepositoryImpl) FindInRange(ctx context.Context, businessID string, period domain.PerformancePeriod, staffID *string, start, end time.Time) ([]*domain.StaffPerformance, error) {
+	defer r.db.lock()()
+	inScope := r.scope(ctx)
+	records := r.table().where(func(p *domain.StaffPerformance) bool {
+		return p.BusinessID == businessID && p.Period == period && (staffID == nil || p.StaffID == *staffID) &&
+			!p.StartDate.Before(start) && p.StartDate.Before(end) && (inScope == nil || inScope(p))
+	})
+	slices.SortStableFunc(records, func(a, b *domain.StaffPerformance) int {
+		return cmp.Or(a.StartDate.Compare(b.StartDate), cmp.Compare(a.StaffID, b.StaffID))
+	})
+	return records, nil
+}
@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaffPerformanceRepository_FindsAppointmentsWithRevenueAndRatings(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	repo := NewStaffPerformanceRepository(db)
+
+	day := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	earlier := &domain.Appointment{BusinessID: "business-1", StaffID: "staff-1", ClientID: "client-1",
+		StartTime: day.AddDate(0, -1, 0), Status: domain.AppointmentStatusCompleted}
+	visit := &domain.Appointment{BusinessID: "business-1", StaffID: "staff-1", ClientID: "client-1",
+		StartTime: day.Add(10 * time.Hour), Status: domain.AppointmentStatusCompleted}
+	other := &domain.Appointment{BusinessID: "business-2", StaffID: "staff-2", ClientID: "client-2",
+		StartTime: day.Add(11 * time.Hour), Status: domain.AppointmentStatusScheduled}
+	require.NoError(t, Insert(db, earlier, visit, other))
+	require.NoError(t, Insert(db, &domain.ServiceCompletion{AppointmentID: visit.ID, PriceCharged: decimal.NewFromInt(45), PaymentMethod: "card"}))
+	require.NoError(t, Insert(db, &domain.Review{BusinessID: "business-1", AppointmentID: visit.ID, ClientID: "client-1", StaffID: "staff-1",
+		ServiceID: "service-1", Rating: 4, Status: domain.ReviewPublished}))
+
+	businessIDs, err := repo.FindBusinessesWithAppointments(ctx, day, day.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"business-1", "business-2"}, businessIDs)
+
+	appointments, err := repo.FindPerformanceAppointments(ctx, "business-1", day, day.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	require.Len(t, appointments, 1)
+	assert.True(t, decimal.NewFromInt(45).Equal(appointments[0].Revenue))
+	require.NotNil(t, appointments[0].Rating)
+	assert.Equal(t, 4, *appointments[0].Rating)
+	require.NotNil(t, appointments[0].FirstVisit)
+	assert.Equal(t, earlier.StartTime, *appointments[0].FirstVisit)
+}
+
+func TestStaffPerformanceRepository_ReplacePeriodIsIdempotent(t *testing.T) {
+	ctx := context.Background()
+	repo := NewStaffPerformanceRepository(NewDB())
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	record := func(staffID string, total int) *domain.StaffPerformance {
+		return &domain.StaffPerformance{BusinessID: "business-1", StaffID: staffID, Period: domain.PerformanceDaily,
+			StartDate: start, EndDate: start.AddDate(0, 0, 1), TotalAppointments: total}
+	}
+	find := func() []*domain.StaffPerformance {
+		records, err := repo.FindInRange(ctx, "business-1", domain.PerformanceDaily, nil, start, start.AddDate(0, 0, 1))
+		require.NoError(t, err)
+		return records
+	}
+
+	require.NoError(t, repo.ReplacePeriod(ctx, "business-1", domain.PerformanceDaily, start, []*domain.StaffPerformance{record("staff-1", 2), record("staff-2", 1)}))
+	first := find()
+	require.Len(t, first, 2)
+
+	require.NoError(t, repo.ReplacePeriod(ctx, "business-1", domain.PerformanceDaily, start, []*domain.StaffPerformance{record("staff-1", 3)}))
+	second := find()
+	require.Len(t, second, 1, "records of staff members left out are removed")
+	assert.Equal(t, first[0].ID, second[0].ID, "records are updated in place")
+	assert.Equal(t, 3, second[0].TotalAppointments)
+
+	require.NoError(t, repo.ReplacePeriod(ctx, "business-1", domain.PerformanceDaily, start, nil))
+	assert.Empty(t, find())
+}
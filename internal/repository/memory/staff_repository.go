@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// staffRepositoryImpl implements the StaffRepository interface
+type staffRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.Staff]
+}
+
+// NewStaffRepository creates a new staff repository, scoped to the tenant in the context
+func NewStaffRepository(db *DB) domain.StaffRepository {
+	return &staffRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.Staff]{db: db, tenantScoped: true},
+	}
+}
+
+// FindByBusinessID finds all staff members for a business
+func (r *staffRepositoryImpl) FindByBusinessID(ctx context.Context, businessID string) ([]*domain.Staff, error) {
+	defer r.db.lock()()
+	return r.table().where(func(s *domain.Staff) bool { return s.BusinessID == businessID }), nil
+}
+
+// FindByUserID finds all staff positions for a user
+func (r *staffRepositoryImpl) FindByUserID(ctx context.Context, userID string) ([]*domain.Staff, error) {
+	defer r.db.lock()()
+	return r.table().where(func(s *domain.Staff) bool { return s.UserID == userID }), nil
+}
+
+// FindByBusinessAndUser finds a specific staff record
+func (r *staffRepositoryImpl) FindByBusinessAndUser(ctx context.Context, businessID, userID string) (*domain.Staff, error) {
+	defer r.db.lock()()
+	return r.table().first(func(s *domain.Staff) bool { return s.BusinessID == businessID && s.UserID == userID })
+}
+
+// FindActiveByBusinessID finds all active staff members for a business
+func (r *staffRepositoryImpl) FindActiveByBusinessID(ctx context.Context, businessID string) ([]*domain.Staff, error) {
+	defer r.db.lock()()
+	return r.table().where(func(s *domain.Staff) bool { return s.BusinessID == businessID && s.IsActive }), nil
+}
+
+// FindByRole finds staff members by role in a business
+func (r *staffRepositoryImpl) FindByRole(ctx context.Context, businessID string, role domain.BusinessRole) ([]*domain.Staff, error) {
+	defer r.db.lock()()
+	return r.table().where(func(s *domain.Staff) bool { return s.BusinessID == businessID && s.Role == role }), nil
+}
+
+// UpdateRole updates the role of a staff member
+func (r *staffRepositoryImpl) UpdateRole(ctx context.Context, businessID, userID string, role domain.BusinessRole) error {
+	defer r.db.lock()()
+	now := r.db.now()
+	r.table().updateWhere(func(s *domain.Staff) bool {
+		return s.BusinessID == businessID && s.UserID == userID
+	}, func(s *domain.Staff) {
+		s.Role = role
+		s.UpdatedAt = now
+	})
+	return nil
+}
+
+// DeactivateStaff deactivates a staff member
+func (r *staffRepositoryImpl) DeactivateStaff(ctx context.Context, businessID, userID string) error {
+	defer r.db.lock()()
+	now := r.db.now()
+	r.table().updateWhere(func(s *domain.Staff) bool {
+		return s.BusinessID == businessID && s.UserID == userID
+	}, func(s *domain.Staff) {
+		s.IsActive = false
+		s.UpdatedAt = now
+	})
+	return nil
+}
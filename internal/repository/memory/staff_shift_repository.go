@@ -0,0 +1,30 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// staffShiftRepositoryImpl implements the StaffShiftRepository interface
+type staffShiftRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.StaffShift]
+}
+
+// NewStaffShiftRepository creates a new staff shift repository, scoped to the tenant in the
+// context
+func NewStaffShiftRepository(db *DB) domain.StaffShiftRepository {
+	return &staffShiftRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.StaffShift]{db: db, tenantScoped: true},
+	}
+}
+
+// FindByStaffIDs finds the shifts of the staff members, in the order they were created
+func (r *staffShiftRepositoryImpl) FindByStaffIDs(ctx context.Context, staffIDs []string) ([]*domain.StaffShift, error) {
+	defer r.db.lock()()
+	inScope := r.scope(ctx)
+	return r.table().where(func(s *domain.StaffShift) bool {
+		return slices.Contains(staffIDs, s.StaffID) && (inScope == nil || inScope(s))
+	}), nil
+}
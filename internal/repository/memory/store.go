@@ -0,0 +1,527 @@
+// Package memory implements the repository interfaces with in-memory maps, so services can be
+// unit tested without a database and without mocks that expect every call. The repositories
+// follow the semantics of their GORM counterparts: reads skip soft-deleted rows, misses
+// return gorm.ErrRecordNotFound, and lists are filtered, ordered and paginated the same way.
+//
+// Repositories created from the same DB share its tables, so queries that join other tables
+// in SQL see the rows written through the other repositories.
+package memory
+
+import (
+	"cmp"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// DB holds the tables of the in-memory repositories. Every repository call holds its lock, so
+// calls are atomic the way a database transaction would make them.
+type DB struct {
+	mu     sync.Mutex
+	tables map[reflect.Type]any
+	now    func() time.Time
+}
+
+// NewDB creates an empty database
+func NewDB() *DB {
+	return &DB{tables: map[reflect.Type]any{}, now: time.Now}
+}
+
+// SetClock replaces the clock used for timestamps and for queries relative to the current
+// time, such as upcoming appointments
+func (db *DB) SetClock(now func() time.Time) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.now = now
+}
+
+// lock locks the database, returning the function that unlocks it
+func (db *DB) lock() func() {
+	db.mu.Lock()
+	return db.mu.Unlock
+}
+
+// Insert stores entities directly, bypassing repository checks, so tests can seed tables
+// that have no repository of their own
+func Insert[T any](db *DB, entities ...*T) error {
+	defer db.lock()()
+	for _, entity := range entities {
+		if err := tableOf[T](db).insert(entity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// All returns every stored entity of a type that has not been soft deleted, in creation order
+func All[T any](db *DB) []*T {
+	defer db.lock()()
+	return tableOf[T](db).where(nil)
+}
+
+// tableOf returns the table storing entities of type T, creating it on first use. The caller
+// must hold the lock.
+func tableOf[T any](db *DB) *table[T] {
+	key := reflect.TypeFor[T]()
+	if t, ok := db.tables[key]; ok {
+		return t.(*table[T])
+	}
+	t := &table[T]{db: db, rows: map[string]*T{}, meta: metaFor(key)}
+	db.tables[key] = t
+	return t
+}
+
+// table stores the entities of one type by ID. Rows are copied in and out, so callers cannot
+// change stored rows without writing them back.
+type table[T any] struct {
+	db   *DB
+	rows map[string]*T
+	meta *entityMeta
+}
+
+// insert stores a new entity, filling in its ID and timestamps like GORM does
+func (t *table[T]) insert(entity *T) error {
+	v := reflect.ValueOf(entity).Elem()
+	id := t.meta.id(v)
+	if id == "" {
+		// Entities without an ID column are keyed by a generated ID all the same
+		id = domain.NewID()
+		t.meta.setID(v, id)
+	}
+	if _, exists := t.rows[id]; exists {
+		return gorm.ErrDuplicatedKey
+	}
+
+	now := t.db.now()
+	t.meta.touch(v, now, true)
+	row := *entity
+	t.rows[id] = &row
+	return nil
+}
+
+// save stores an entity, inserting it when it has no ID or is not stored yet, like GORM's Save
+func (t *table[T]) save(entity *T) error {
+	v := reflect.ValueOf(entity).Elem()
+	id := t.meta.id(v)
+	if _, exists := t.rows[id]; id == "" || !exists {
+		return t.insert(entity)
+	}
+
+	t.meta.touch(v, t.db.now(), false)
+	row := *entity
+	t.rows[id] = &row
+	return nil
+}
+
+// get returns a copy of the entity with the ID
+func (t *table[T]) get(id string) (*T, error) {
+	row, ok := t.rows[id]
+	if !ok || t.meta.deleted(reflect.ValueOf(row).Elem()) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	entity := *row
+	return &entity, nil
+}
+
+// update changes the stored entity with the ID in place, reporting whether it was found
+func (t *table[T]) update(id string, change func(row *T)) bool {
+	row, ok := t.rows[id]
+	if !ok || t.meta.deleted(reflect.ValueOf(row).Elem()) {
+		return false
+	}
+	change(row)
+	return true
+}
+
+// updateWhere changes every stored entity matching the filter in place, returning how many
+// were changed
+func (t *table[T]) updateWhere(match func(row *T) bool, change func(row *T)) int64 {
+	var count int64
+	for _, row := range t.rows {
+		if t.meta.deleted(reflect.ValueOf(row).Elem()) || !match(row) {
+			continue
+		}
+		change(row)
+		count++
+	}
+	return count
+}
+
+// delete soft deletes the entity with the ID, reporting whether it was found. Entities
+// without a DeletedAt field are removed for good, as GORM does.
+func (t *table[T]) delete(id string) bool {
+	if t.meta.deletedAtField == nil {
+		_, ok := t.rows[id]
+		delete(t.rows, id)
+		return ok
+	}
+	now := t.db.now()
+	return t.update(id, func(row *T) {
+		t.meta.setDeleted(reflect.ValueOf(row).Elem(), now)
+	})
+}
+
+// deleteWhere soft deletes every entity matching the filter, returning how many were deleted.
+// Entities without a DeletedAt field are removed for good, as GORM does.
+func (t *table[T]) deleteWhere(match func(row *T) bool) int64 {
+	if t.meta.deletedAtField == nil {
+		return t.purgeWhere(match)
+	}
+	now := t.db.now()
+	return t.updateWhere(match, func(row *T) {
+		t.meta.setDeleted(reflect.ValueOf(row).Elem(), now)
+	})
+}
+
+// purgeWhere removes every entity matching the filter for good, including soft-deleted ones,
+// returning how many were removed
+func (t *table[T]) purgeWhere(match func(row *T) bool) int64 {
+	var count int64
+	for id, row := range t.rows {
+		if match(row) {
+			delete(t.rows, id)
+			count++
+		}
+	}
+	return count
+}
+
+// restore undoes the soft deletion of the entity with the ID, reporting whether a deleted
+// entity was found
+func (t *table[T]) restore(id string) bool {
+	row, ok := t.rows[id]
+	if !ok || !t.meta.deleted(reflect.ValueOf(row).Elem()) {
+		return false
+	}
+	v := reflect.ValueOf(row).Elem()
+	v.FieldByIndex(t.meta.deletedAtField).Set(reflect.ValueOf(gorm.DeletedAt{}))
+	if index, ok := t.meta.columns["deleted_by"]; ok {
+		field := v.FieldByIndex(index)
+		field.Set(reflect.Zero(field.Type()))
+	}
+	t.meta.touch(v, t.db.now(), false)
+	return true
+}
+
+// trash returns copies of the soft-deleted entities matching the filter, most recently
+// deleted first
+func (t *table[T]) trash(match func(row *T) bool) []*T {
+	entities := []*T{}
+	for _, row := range t.rows {
+		if !t.meta.deleted(reflect.ValueOf(row).Elem()) || (match != nil && !match(row)) {
+			continue
+		}
+		entity := *row
+		entities = append(entities, &entity)
+	}
+	slices.SortFunc(entities, func(a, b *T) int {
+		va, vb := reflect.ValueOf(a).Elem(), reflect.ValueOf(b).Elem()
+		return cmp.Or(t.meta.deletedAt(vb).Compare(t.meta.deletedAt(va)), cmp.Compare(t.meta.id(va), t.meta.id(vb)))
+	})
+	return entities
+}
+
+// where returns copies of the entities matching the filter, in creation order. A nil filter
+// matches every entity.
+func (t *table[T]) where(match func(row *T) bool) []*T {
+	entities := []*T{}
+	for _, row := range t.rows {
+		if t.meta.deleted(reflect.ValueOf(row).Elem()) || (match != nil && !match(row)) {
+			continue
+		}
+		entity := *row
+		entities = append(entities, &entity)
+	}
+	slices.SortFunc(entities, t.compareCreated)
+	return entities
+}
+
+// first returns the first entity matching the filter in creation order
+func (t *table[T]) first(match func(row *T) bool) (*T, error) {
+	entities := t.where(match)
+	if len(entities) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return entities[0], nil
+}
+
+// count counts the entities matching the filter
+func (t *table[T]) count(match func(row *T) bool) int64 {
+	var count int64
+	for _, row := range t.rows {
+		if !t.meta.deleted(reflect.ValueOf(row).Elem()) && (match == nil || match(row)) {
+			count++
+		}
+	}
+	return count
+}
+
+// countUnscoped counts the entities matching the filter, including soft-deleted ones, for
+// queries that do not exclude them
+func (t *table[T]) countUnscoped(match func(row *T) bool) int64 {
+	var count int64
+	for _, row := range t.rows {
+		if match(row) {
+			count++
+		}
+	}
+	return count
+}
+
+// compareCreated orders entities by creation time and ID
+func (t *table[T]) compareCreated(a, b *T) int {
+	va, vb := reflect.ValueOf(a).Elem(), reflect.ValueOf(b).Elem()
+	return cmp.Or(t.meta.createdAt(va).Compare(t.meta.createdAt(vb)), cmp.Compare(t.meta.id(va), t.meta.id(vb)))
+}
+
+// assignNonZero copies the non-zero fields of src onto dst, except the ID, the way GORM's
+// Updates writes a struct
+func (m *entityMeta) assignNonZero(dst, src reflect.Value) {
+	for _, index := range m.columns {
+		if slices.Equal(index, m.idField) {
+			continue
+		}
+		if field := src.FieldByIndex(index); !field.IsZero() {
+			dst.FieldByIndex(index).Set(field)
+		}
+	}
+}
+
+// entityMeta locates the fields GORM manages on an entity type
+type entityMeta struct {
+	idField        []int
+	createdAtField []int
+	updatedAtField []int
+	deletedAtField []int
+	versionField   []int
+	columns        map[string][]int
+	defaults       []columnDefault
+	table          string
+}
+
+// columnDefault is the value a column takes when an entity is created with the field zero
+type columnDefault struct {
+	index []int
+	value reflect.Value
+}
+
+var (
+	timeType      = reflect.TypeFor[time.Time]()
+	deletedAtType = reflect.TypeFor[gorm.DeletedAt]()
+	naming        = schema.NamingStrategy{}
+	metas         sync.Map
+)
+
+// metaFor returns the metadata of an entity type
+func metaFor(typ reflect.Type) *entityMeta {
+	if meta, ok := metas.Load(typ); ok {
+		return meta.(*entityMeta)
+	}
+	meta := &entityMeta{columns: map[string][]int{}, table: naming.TableName(typ.Name())}
+	if tabler, ok := reflect.New(typ).Interface().(schema.Tabler); ok {
+		meta.table = tabler.TableName()
+	}
+	meta.scan(typ, nil)
+	metas.Store(typ, meta)
+	return meta
+}
+
+// scan records the columns of a struct type, descending into embedded structs
+func (m *entityMeta) scan(typ reflect.Type, parent []int) {
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		index := append(slices.Clone(parent), i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			m.scan(field.Type, index)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		settings := schema.ParseTagSetting(field.Tag.Get("gorm"), ";")
+		column := settings["COLUMN"]
+		if column == "" {
+			column = naming.ColumnName("", field.Name)
+		}
+		m.columns[column] = index
+		if value, ok := parseDefault(field.Type, settings["DEFAULT"]); ok {
+			m.defaults = append(m.defaults, columnDefault{index: index, value: value})
+		}
+
+		switch {
+		case field.Name == "ID" && field.Type.Kind() == reflect.String:
+			m.idField = index
+		case field.Name == "CreatedAt" && field.Type == timeType:
+			m.createdAtField = index
+		case field.Name == "UpdatedAt" && field.Type == timeType:
+			m.updatedAtField = index
+		case field.Name == "DeletedAt" && field.Type == deletedAtType:
+			m.deletedAtField = index
+		case field.Name == "Version" && field.Type.Kind() == reflect.Int:
+			m.versionField = index
+		}
+	}
+}
+
+// parseDefault parses the default tag of a field the way GORM does. Defaults computed by the
+// database, such as gen_random_uuid(), are left out.
+func parseDefault(typ reflect.Type, tag string) (reflect.Value, bool) {
+	if tag == "" || strings.EqualFold(tag, "null") || (strings.Contains(tag, "(") && strings.Contains(tag, ")")) {
+		return reflect.Value{}, false
+	}
+	value := reflect.New(typ).Elem()
+	switch typ.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(tag)
+		if err != nil {
+			return reflect.Value{}, false
+		}
+		value.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(tag, 10, 64)
+		if err != nil {
+			return reflect.Value{}, false
+		}
+		value.SetInt(i)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(tag, 64)
+		if err != nil {
+			return reflect.Value{}, false
+		}
+		value.SetFloat(f)
+	case reflect.String:
+		value.SetString(strings.Trim(tag, "'"))
+	default:
+		return reflect.Value{}, false
+	}
+	return value, true
+}
+
+// applyDefaults sets the zero fields of an entity being created to their column defaults, for
+// repositories whose callers leave the defaults to the database
+func (m *entityMeta) applyDefaults(v reflect.Value) {
+	for _, d := range m.defaults {
+		if field := v.FieldByIndex(d.index); field.IsZero() {
+			field.Set(d.value)
+		}
+	}
+}
+
+func (m *entityMeta) id(v reflect.Value) string {
+	if m.idField == nil {
+		return ""
+	}
+	return v.FieldByIndex(m.idField).String()
+}
+
+func (m *entityMeta) setID(v reflect.Value, id string) {
+	if m.idField != nil {
+		v.FieldByIndex(m.idField).SetString(id)
+	}
+}
+
+func (m *entityMeta) createdAt(v reflect.Value) time.Time {
+	if m.createdAtField == nil {
+		return time.Time{}
+	}
+	return v.FieldByIndex(m.createdAtField).Interface().(time.Time)
+}
+
+func (m *entityMeta) version(v reflect.Value) int {
+	if m.versionField == nil {
+		return 0
+	}
+	return int(v.FieldByIndex(m.versionField).Int())
+}
+
+func (m *entityMeta) setVersion(v reflect.Value, version int) {
+	if m.versionField != nil {
+		v.FieldByIndex(m.versionField).SetInt(int64(version))
+	}
+}
+
+// touch sets the update time, and on creation the creation time and the first version when
+// they are not set
+func (m *entityMeta) touch(v reflect.Value, now time.Time, creating bool) {
+	if creating && m.createdAtField != nil && m.createdAt(v).IsZero() {
+		v.FieldByIndex(m.createdAtField).Set(reflect.ValueOf(now))
+	}
+	if creating && m.version(v) == 0 {
+		m.setVersion(v, 1)
+	}
+	if m.updatedAtField != nil {
+		updatedAt := v.FieldByIndex(m.updatedAtField)
+		if !creating || updatedAt.Interface().(time.Time).IsZero() {
+			updatedAt.Set(reflect.ValueOf(now))
+		}
+	}
+}
+
+func (m *entityMeta) deleted(v reflect.Value) bool {
+	return m.deletedAtField != nil && v.FieldByIndex(m.deletedAtField).Interface().(gorm.DeletedAt).Valid
+}
+
+func (m *entityMeta) deletedAt(v reflect.Value) time.Time {
+	if m.deletedAtField == nil {
+		return time.Time{}
+	}
+	return v.FieldByIndex(m.deletedAtField).Interface().(gorm.DeletedAt).Time
+}
+
+func (m *entityMeta) setDeleted(v reflect.Value, at time.Time) {
+	if m.deletedAtField != nil {
+		v.FieldByIndex(m.deletedAtField).Set(reflect.ValueOf(gorm.DeletedAt{Time: at, Valid: true}))
+	}
+}
+
+// matches reports whether the entity's columns equal the criteria, the way FindBy compares
+// them in SQL. Unknown columns match nothing.
+func (m *entityMeta) matches(v reflect.Value, criteria map[string]any) bool {
+	for column, want := range criteria {
+		index, ok := m.columns[column]
+		if !ok || !equalValue(v.FieldByIndex(index), want) {
+			return false
+		}
+	}
+	return true
+}
+
+// equalValue compares a field with a query value, dereferencing pointers and converting
+// between named types such as statuses and plain strings
+func equalValue(field reflect.Value, want any) bool {
+	for field.Kind() == reflect.Pointer {
+		if field.IsNil() {
+			return false
+		}
+		field = field.Elem()
+	}
+	value := reflect.ValueOf(want)
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return false
+		}
+		value = value.Elem()
+	}
+	if !value.IsValid() || !value.Type().ConvertibleTo(field.Type()) {
+		return false
+	}
+	value = value.Convert(field.Type())
+
+	// Times and decimals compare by value rather than by representation
+	if equal := field.MethodByName("Equal"); equal.IsValid() && equal.Type().NumIn() == 1 &&
+		equal.Type().In(0) == field.Type() && equal.Type().NumOut() == 1 && equal.Type().Out(0).Kind() == reflect.Bool {
+		return equal.Call([]reflect.Value{value})[0].Bool()
+	}
+	if !field.Comparable() {
+		return reflect.DeepEqual(field.Interface(), value.Interface())
+	}
+	return field.Equal(value)
+}
@@ -0,0 +1,113 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// subscriptionRepositoryImpl implements the SubscriptionRepository interface
+type subscriptionRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.BusinessSubscription]
+}
+
+// NewSubscriptionRepository creates a new subscription repository, scoped to the tenant in the
+// context
+func NewSubscriptionRepository(db *DB) domain.SubscriptionRepository {
+	return &subscriptionRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.BusinessSubscription]{db: db, tenantScoped: true},
+	}
+}
+
+// FindByBusiness finds the subscription of a business
+func (r *subscriptionRepositoryImpl) FindByBusiness(ctx context.Context, businessID string) (*domain.BusinessSubscription, error) {
+	defer r.db.lock()()
+	return r.table().first(func(s *domain.BusinessSubscription) bool { return s.BusinessID == businessID })
+}
+
+// FindByStripeSubscription finds the subscription mirroring a Stripe subscription
+func (r *subscriptionRepositoryImpl) FindByStripeSubscription(ctx context.Context, stripeSubscriptionID string) (*domain.BusinessSubscription, error) {
+	defer r.db.lock()()
+	return r.table().first(func(s *domain.BusinessSubscription) bool {
+		return s.StripeSubscriptionID != nil && *s.StripeSubscriptionID == stripeSubscriptionID
+	})
+}
+
+// CountUsage counts how much of an entitlement a business uses at a time
+func (r *subscriptionRepositoryImpl) CountUsage(ctx context.Context, businessID string, entitlement domain.Entitlement, now time.Time) (int, error) {
+	defer r.db.lock()()
+	switch entitlement {
+	case domain.EntitlementStaff:
+		return int(tableOf[domain.Staff](r.db).count(func(s *domain.Staff) bool { return s.BusinessID == businessID && s.IsActive })), nil
+	case domain.EntitlementLocations:
+		return int(tableOf[domain.BusinessLocation](r.db).count(func(l *domain.BusinessLocation) bool {
+			return l.BusinessID == businessID && l.IsActive
+		})), nil
+	case domain.EntitlementCampaigns:
+		return int(tableOf[domain.Campaign](r.db).count(func(c *domain.Campaign) bool {
+			return c.BusinessID == businessID && c.IsActive && !c.EndDate.Before(now)
+		})), nil
+	case domain.EntitlementSMSCredits:
+		usage, err := r.smsUsage(businessID, now)
+		if err != nil {
+			return 0, nil
+		}
+		return usage.Used, nil
+	}
+	return 0, fmt.Errorf("unknown entitlement %q", entitlement)
+}
+
+// UseSMSCredit takes a credit off the business's SMS credits of the month, reporting false
+// when the limit has been reached
+func (r *subscriptionRepositoryImpl) UseSMSCredit(ctx context.Context, businessID string, now time.Time, limit int) (bool, error) {
+	defer r.db.lock()()
+	usage, err := r.smsUsage(businessID, now)
+	if err != nil {
+		if limit == 0 {
+			return false, nil
+		}
+		return true, tableOf[domain.SMSUsageMonthly](r.db).insert(&domain.SMSUsageMonthly{
+			BusinessID: businessID,
+			Month:      domain.UsageMonth(now),
+			Used:       1,
+		})
+	}
+	if limit != domain.Unlimited && usage.Used >= limit {
+		return false, nil
+	}
+	tableOf[domain.SMSUsageMonthly](r.db).update(usage.ID, func(u *domain.SMSUsageMonthly) {
+		u.Used++
+		u.UpdatedAt = r.db.now()
+	})
+	return true, nil
+}
+
+// Sync saves the subscription and gives the business its effective tier and trial end
+func (r *subscriptionRepositoryImpl) Sync(ctx context.Context, subscription *domain.BusinessSubscription) error {
+	defer r.db.lock()()
+	if stored, err := r.table().get(subscription.ID); err == nil {
+		if subscription.Version != 0 && subscription.Version != stored.Version {
+			return &domain.ConflictError{EntityType: "business_subscriptions", ID: subscription.ID, Version: subscription.Version}
+		}
+		subscription.Version = stored.Version + 1
+	}
+	if err := r.table().save(subscription); err != nil {
+		return err
+	}
+	tableOf[domain.Business](r.db).update(subscription.BusinessID, func(b *domain.Business) {
+		b.SubscriptionTier = string(subscription.EffectiveTier())
+		b.TrialEndsAt = subscription.TrialEndsAt
+		b.UpdatedAt = r.db.now()
+	})
+	return nil
+}
+
+// smsUsage finds the business's SMS usage of the month
+func (r *subscriptionRepositoryImpl) smsUsage(businessID string, now time.Time) (*domain.SMSUsageMonthly, error) {
+	month := domain.UsageMonth(now)
+	return tableOf[domain.SMSUsageMonthly](r.db).first(func(u *domain.SMSUsageMonthly) bool {
+		return u.BusinessID == businessID && u.Month.Equal(month)
+	})
+}
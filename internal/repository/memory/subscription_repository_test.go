@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscriptionRepository_UseSMSCredit(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	repo := NewSubscriptionRepository(db)
+
+	business := &domain.Business{Name: "Studio", SubscriptionTier: "free"}
+	require.NoError(t, Insert(db, business))
+	march := time.Date(2026, 3, 31, 23, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 2; i++ {
+		used, err := repo.UseSMSCredit(ctx, business.ID, march, 2)
+		require.NoError(t, err)
+		assert.True(t, used)
+	}
+	used, err := repo.UseSMSCredit(ctx, business.ID, march, 2)
+	require.NoError(t, err)
+	assert.False(t, used, "the month's credits are used up")
+
+	count, err := repo.CountUsage(ctx, business.ID, domain.EntitlementSMSCredits, march)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	used, err = repo.UseSMSCredit(ctx, business.ID, march.Add(2*time.Hour), 2)
+	require.NoError(t, err)
+	assert.True(t, used, "credits start again in April")
+
+	used, err = repo.UseSMSCredit(ctx, business.ID, march, domain.Unlimited)
+	require.NoError(t, err)
+	assert.True(t, used)
+}
+
+func TestSubscriptionRepository_SyncUpdatesTheBusinessTier(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	repo := NewSubscriptionRepository(db)
+	businesses := NewBaseRepository[domain.Business](db)
+
+	business := &domain.Business{Name: "Studio", SubscriptionTier: "free"}
+	require.NoError(t, Insert(db, business))
+	trialEnd := time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)
+	subscriptionID := "sub_1"
+	subscription := &domain.BusinessSubscription{BusinessID: business.ID, Tier: domain.TierPro, Status: domain.SubscriptionTrialing,
+		StripeCustomerID: "cus_1", StripeSubscriptionID: &subscriptionID, TrialEndsAt: &trialEnd}
+	require.NoError(t, repo.Sync(ctx, subscription))
+
+	stored, err := businesses.GetByID(ctx, business.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.TierPro, stored.Tier())
+	assert.Equal(t, trialEnd, *stored.TrialEndsAt)
+
+	found, err := repo.FindByStripeSubscription(ctx, subscriptionID)
+	require.NoError(t, err)
+	found.Status = domain.SubscriptionUnpaid
+	require.NoError(t, repo.Sync(ctx, found))
+	stored, err = businesses.GetByID(ctx, business.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.TierFree, stored.Tier(), "unpaid subscriptions fall back to the free tier")
+
+	subscription.Status = domain.SubscriptionActive
+	assert.Error(t, repo.Sync(ctx, subscription), "outdated versions are refused")
+}
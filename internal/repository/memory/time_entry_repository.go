@@ -0,0 +1,43 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// timeEntryRepositoryImpl implements the TimeEntryRepository interface
+type timeEntryRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.TimeEntry]
+}
+
+// NewTimeEntryRepository creates a new time entry repository, scoped to the tenant in the
+// context
+func NewTimeEntryRepository(db *DB) domain.TimeEntryRepository {
+	return &timeEntryRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.TimeEntry]{db: db, tenantScoped: true},
+	}
+}
+
+// FindOpenByStaffID finds the entry the staff member is clocked in on
+func (r *timeEntryRepositoryImpl) FindOpenByStaffID(ctx context.Context, staffID string) (*domain.TimeEntry, error) {
+	defer r.db.lock()()
+	inScope := r.scope(ctx)
+	return r.table().first(func(e *domain.TimeEntry) bool {
+		return e.StaffID == staffID && e.IsOpen() && (inScope == nil || inScope(e))
+	})
+}
+
+// FindInRange finds the entries of a business overlapping the range, ordered by clock-in time
+func (r *timeEntryRepositoryImpl) FindInRange(ctx context.Context, businessID string, staffID *string, start, end time.Time) ([]*domain.TimeEntry, error) {
+	defer r.db.lock()()
+	inScope := r.scope(ctx)
+	entries := r.table().where(func(e *domain.TimeEntry) bool {
+		return e.BusinessID == businessID && (staffID == nil || e.StaffID == *staffID) &&
+			e.ClockIn.Before(end) && (e.ClockOut == nil || e.ClockOut.After(start)) && (inScope == nil || inScope(e))
+	})
+	slices.SortStableFunc(entries, func(a, b *domain.TimeEntry) int { return a.ClockIn.Compare(b.ClockIn) })
+	return entries, nil
+}
@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// trialRepositoryImpl implements the TrialRepository interface
+type trialRepositoryImpl struct {
+	db *DB
+}
+
+// NewTrialRepository creates a new trial repository
+func NewTrialRepository(db *DB) domain.TrialRepository {
+	return &trialRepositoryImpl{db: db}
+}
+
+// FindEnding finds the businesses on an in-app trial whose trial ends before a time or has
+// ended without their being restricted yet
+func (r *trialRepositoryImpl) FindEnding(ctx context.Context, before time.Time) ([]*domain.Business, error) {
+	defer r.db.lock()()
+	businesses := tableOf[domain.Business](r.db).where(func(b *domain.Business) bool {
+		return b.TrialEndsAt != nil && b.TrialEndsAt.Before(before) && b.TrialExpiredAt == nil &&
+			b.SubscriptionTier != string(domain.TierFree) && !r.billedByStripe(b.ID)
+	})
+	slices.SortFunc(businesses, func(a, b *domain.Business) int { return a.TrialEndsAt.Compare(*b.TrialEndsAt) })
+	return businesses, nil
+}
+
+// RecordNotice records a notice unless it was already recorded for the trial
+func (r *trialRepositoryImpl) RecordNotice(ctx context.Context, notice *domain.TrialNotice) (bool, error) {
+	defer r.db.lock()()
+	notices := tableOf[domain.TrialNotice](r.db)
+	if notices.count(func(n *domain.TrialNotice) bool {
+		return n.BusinessID == notice.BusinessID && n.Kind == notice.Kind && n.TrialEndsAt.Equal(notice.TrialEndsAt)
+	}) > 0 {
+		return false, nil
+	}
+	return true, notices.insert(notice)
+}
+
+// Restrict moves a business whose trial and grace period are over to the free tier
+func (r *trialRepositoryImpl) Restrict(ctx context.Context, businessID string, trialEndsAt, now time.Time) (bool, error) {
+	defer r.db.lock()()
+	restricted := tableOf[domain.Business](r.db).updateWhere(func(b *domain.Business) bool {
+		return b.ID == businessID && b.TrialEndsAt != nil && b.TrialEndsAt.Equal(trialEndsAt) && b.TrialExpiredAt == nil &&
+			!r.billedByStripe(b.ID)
+	}, func(b *domain.Business) {
+		b.SubscriptionTier = string(domain.TierFree)
+		b.TrialExpiredAt = &now
+		b.UpdatedAt = now
+	})
+	return restricted > 0, nil
+}
+
+// billedByStripe reports whether the business's Stripe subscription gives it its tier
+func (r *trialRepositoryImpl) billedByStripe(businessID string) bool {
+	return tableOf[domain.BusinessSubscription](r.db).count(func(s *domain.BusinessSubscription) bool {
+		return s.BusinessID == businessID && s.Status.GrantsTier()
+	}) > 0
+}
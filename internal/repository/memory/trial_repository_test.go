@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrialRepository_FindEndingAndRestrict(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	repo := NewTrialRepository(db)
+	businesses := NewBaseRepository[domain.Business](db)
+
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	ending, later, stripeTrial := now.AddDate(0, 0, -8), now.AddDate(0, 0, 30), now.AddDate(0, 0, 2)
+	expired := &domain.Business{Name: "Expired", SubscriptionTier: "pro", TrialEndsAt: &ending}
+	notYet := &domain.Business{Name: "Not yet", SubscriptionTier: "pro", TrialEndsAt: &later}
+	subscribed := &domain.Business{Name: "Subscribed", SubscriptionTier: "pro", TrialEndsAt: &stripeTrial}
+	free := &domain.Business{Name: "Free", SubscriptionTier: "free", TrialEndsAt: &ending}
+	require.NoError(t, Insert(db, expired, notYet, subscribed, free))
+	require.NoError(t, Insert(db, &domain.BusinessSubscription{BusinessID: subscribed.ID, Tier: domain.TierPro, Status: domain.SubscriptionTrialing}))
+
+	found, err := repo.FindEnding(ctx, now.Add(domain.TrialNoticeHorizon))
+	require.NoError(t, err)
+	require.Len(t, found, 1, "Stripe ends the trials it bills and free businesses have nothing to lose")
+	assert.Equal(t, expired.ID, found[0].ID)
+
+	restricted, err := repo.Restrict(ctx, expired.ID, now, now)
+	require.NoError(t, err)
+	assert.False(t, restricted, "trials extended since are left alone")
+
+	restricted, err = repo.Restrict(ctx, expired.ID, ending, now)
+	require.NoError(t, err)
+	assert.True(t, restricted)
+	stored, err := businesses.GetByID(ctx, expired.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.TierFree, stored.Tier())
+	assert.Equal(t, domain.TrialExpired, stored.TrialState(now))
+
+	found, err = repo.FindEnding(ctx, now.Add(domain.TrialNoticeHorizon))
+	require.NoError(t, err)
+	assert.Empty(t, found)
+}
+
+func TestTrialRepository_RecordNotice(t *testing.T) {
+	ctx := context.Background()
+	repo := NewTrialRepository(NewDB())
+	trialEndsAt := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	recorded, err := repo.RecordNotice(ctx, &domain.TrialNotice{BusinessID: "business-1", Kind: domain.TrialNoticeOneDay, TrialEndsAt: trialEndsAt})
+	require.NoError(t, err)
+	assert.True(t, recorded)
+	recorded, err = repo.RecordNotice(ctx, &domain.TrialNotice{BusinessID: "business-1", Kind: domain.TrialNoticeOneDay, TrialEndsAt: trialEndsAt})
+	require.NoError(t, err)
+	assert.False(t, recorded)
+	recorded, err = repo.RecordNotice(ctx, &domain.TrialNotice{BusinessID: "business-1", Kind: domain.TrialNoticeOneDay, TrialEndsAt: trialEndsAt.AddDate(0, 1, 0)})
+	require.NoError(t, err)
+	assert.True(t, recorded, "extended trials are noticed again")
+}
@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// unitOfWork runs units of work against the tables of a DB
+type unitOfWork struct {
+	db *DB
+}
+
+// NewUnitOfWork creates a unit of work over the tables of db
+func NewUnitOfWork(db *DB) domain.UnitOfWork {
+	return &unitOfWork{db: db}
+}
+
+// Do runs fn, putting the tables back the way they were when it returns an error or panics.
+// Unlike a database transaction it does not hide the changes of fn from concurrent calls.
+func (u *unitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	rollback := u.db.snapshot()
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			rollback()
+			panic(recovered)
+		}
+		if err != nil {
+			rollback()
+		}
+	}()
+	return fn(ctx)
+}
+
+// snapshottable is a table whose rows can be put back the way they were
+type snapshottable interface {
+	snapshot() func()
+}
+
+// snapshot copies the rows of every table, returning the function that puts them back and
+// drops the tables created since
+func (db *DB) snapshot() func() {
+	defer db.lock()()
+	tables := make(map[reflect.Type]any, len(db.tables))
+	restores := make([]func(), 0, len(db.tables))
+	for key, t := range db.tables {
+		tables[key] = t
+		restores = append(restores, t.(snapshottable).snapshot())
+	}
+	return func() {
+		defer db.lock()()
+		db.tables = tables
+		for _, restore := range restores {
+			restore()
+		}
+	}
+}
+
+// snapshot copies the rows of the table, returning the function that puts them back
+func (t *table[T]) snapshot() func() {
+	rows := make(map[string]*T, len(t.rows))
+	for id, row := range t.rows {
+		copied := *row
+		rows[id] = &copied
+	}
+	return func() {
+		t.rows = rows
+	}
+}
@@ -0,0 +1,104 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitOfWork_CommitsWhenFnSucceeds(t *testing.T) {
+	db := NewDB()
+	businesses := NewBusinessRepository(db)
+	settings := NewBusinessSettingsRepository(db)
+
+	err := NewUnitOfWork(db).Do(context.Background(), func(ctx context.Context) error {
+		business := &domain.Business{UserID: "user-1", Name: "Salon", Email: "salon@example.com"}
+		if err := businesses.Create(ctx, business); err != nil {
+			return err
+		}
+		return settings.Create(ctx, domain.NewBusinessSettings(business))
+	})
+
+	require.NoError(t, err)
+	assert.Len(t, All[domain.Business](db), 1)
+	stored := All[domain.BusinessSettings](db)
+	require.Len(t, stored, 1)
+	assert.Equal(t, 24, stored[0].ConfirmationLeadHours, "settings left zero take their column defaults")
+	assert.True(t, stored[0].AllowOnlineBooking)
+	assert.Equal(t, "EUR", stored[0].Currency)
+}
+
+func TestUnitOfWork_RollsBackWhenFnFails(t *testing.T) {
+	ctx := context.Background()
+	db := NewDB()
+	businesses := NewBusinessRepository(db)
+	existing := &domain.Business{UserID: "user-1", Name: "Salon", Email: "salon@example.com"}
+	require.NoError(t, businesses.Create(ctx, existing))
+	failure := errors.New("owner could not be added")
+
+	err := NewUnitOfWork(db).Do(ctx, func(ctx context.Context) error {
+		existing.Name = "Renamed"
+		if err := businesses.Update(ctx, existing); err != nil {
+			return err
+		}
+		business := &domain.Business{UserID: "user-2", Name: "Spa", Email: "spa@example.com"}
+		if err := businesses.Create(ctx, business); err != nil {
+			return err
+		}
+		if err := NewBusinessSettingsRepository(db).Create(ctx, domain.NewBusinessSettings(business)); err != nil {
+			return err
+		}
+		return failure
+	})
+
+	assert.ErrorIs(t, err, failure)
+	stored := All[domain.Business](db)
+	require.Len(t, stored, 1)
+	assert.Equal(t, "Salon", stored[0].Name)
+	assert.Empty(t, All[domain.BusinessSettings](db))
+}
+
+func TestUnitOfWork_RollsBackWhenFnPanics(t *testing.T) {
+	db := NewDB()
+	businesses := NewBusinessRepository(db)
+
+	assert.Panics(t, func() {
+		_ = NewUnitOfWork(db).Do(context.Background(), func(ctx context.Context) error {
+			business := &domain.Business{UserID: "user-1", Name: "Salon", Email: "salon@example.com"}
+			if err := businesses.Create(ctx, business); err != nil {
+				return err
+			}
+			panic("unexpected")
+		})
+	})
+	assert.Empty(t, All[domain.Business](db))
+}
+
+func TestUnitOfWork_NestedFailureOnlyRollsBackItsOwnChanges(t *testing.T) {
+	db := NewDB()
+	businesses := NewBusinessRepository(db)
+	unitOfWork := NewUnitOfWork(db)
+
+	err := unitOfWork.Do(context.Background(), func(ctx context.Context) error {
+		if err := businesses.Create(ctx, &domain.Business{UserID: "user-1", Name: "Salon", Email: "salon@example.com"}); err != nil {
+			return err
+		}
+		nested := unitOfWork.Do(ctx, func(ctx context.Context) error {
+			if err := businesses.Create(ctx, &domain.Business{UserID: "user-2", Name: "Spa", Email: "spa@example.com"}); err != nil {
+				return err
+			}
+			return errors.New("spa rejected")
+		})
+		assert.Error(t, nested)
+		return nil
+	})
+
+	require.NoError(t, err)
+	stored := All[domain.Business](db)
+	require.Len(t, stored, 1)
+	assert.Equal(t, "Salon", stored[0].Name)
+}
@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"context"
+	"strings"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// userRepositoryImpl implements the UserRepository interface
+type userRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.User]
+}
+
+// NewUserRepository creates a new user repository
+func NewUserRepository(db *DB) domain.UserRepository {
+	return &userRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.User]{db: db},
+	}
+}
+
+// FindByEmail finds a user by email address
+func (r *userRepositoryImpl) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
+	defer r.db.lock()()
+	email = strings.ToLower(email)
+	return r.table().first(func(u *domain.User) bool { return u.Email == email })
+}
+
+// FindByClerkID finds a user by Clerk ID
+func (r *userRepositoryImpl) FindByClerkID(ctx context.Context, clerkID string) (*domain.User, error) {
+	defer r.db.lock()()
+	return r.table().first(func(u *domain.User) bool { return u.ClerkID != nil && *u.ClerkID == clerkID })
+}
+
+// UpdateClerkID updates the Clerk ID for a user
+func (r *userRepositoryImpl) UpdateClerkID(ctx context.Context, userID, clerkID string) error {
+	defer r.db.lock()()
+	now := r.db.now()
+	r.table().update(userID, func(u *domain.User) {
+		u.ClerkID = &clerkID
+		u.UpdatedAt = now
+	})
+	return nil
+}
+
+// GetWithBusinesses retrieves a user with their businesses loaded
+func (r *userRepositoryImpl) GetWithBusinesses(ctx context.Context, userID string) (*domain.User, error) {
+	defer r.db.lock()()
+	user, err := r.table().get(userID)
+	if err != nil {
+		return nil, err
+	}
+	user.Businesses = nil
+	for _, business := range tableOf[domain.Business](r.db).where(func(b *domain.Business) bool { return b.UserID == userID }) {
+		user.Businesses = append(user.Businesses, *business)
+	}
+	return user, nil
+}
+
+// SearchUsers searches for users by name or email
+func (r *userRepositoryImpl) SearchUsers(ctx context.Context, query string, limit int) ([]*domain.User, error) {
+	defer r.db.lock()()
+	query = strings.ToLower(query)
+	users := r.table().where(func(u *domain.User) bool {
+		return strings.Contains(strings.ToLower(u.FirstName), query) ||
+			strings.Contains(strings.ToLower(u.LastName), query) ||
+			strings.Contains(strings.ToLower(u.Email), query)
+	})
+	return users[:min(limit, len(users))], nil
+}
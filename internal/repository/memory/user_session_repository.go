@@ -0,0 +1,56 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// userSessionRepositoryImpl implements the UserSessionRepository interface
+type userSessionRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.UserSession]
+}
+
+// NewUserSessionRepository creates a new user session repository
+func NewUserSessionRepository(db *DB) domain.UserSessionRepository {
+	return &userSessionRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.UserSession]{db: db},
+	}
+}
+
+// FindByExternalID finds a session by the ID Clerk or the API key gave it
+func (r *userSessionRepositoryImpl) FindByExternalID(ctx context.Context, kind domain.SessionKind, externalID string) (*domain.UserSession, error) {
+	defer r.db.lock()()
+	return r.table().first(func(s *domain.UserSession) bool {
+		return s.Kind == kind && s.ExternalID == externalID
+	})
+}
+
+// FindByUser finds the sessions of a user, most recently seen first, optionally with those
+// signed out
+func (r *userSessionRepositoryImpl) FindByUser(ctx context.Context, userID string, includeRevoked bool) ([]*domain.UserSession, error) {
+	defer r.db.lock()()
+	sessions := r.table().where(func(s *domain.UserSession) bool {
+		return s.UserID == userID && (includeRevoked || s.RevokedAt == nil)
+	})
+	slices.SortStableFunc(sessions, func(a, b *domain.UserSession) int {
+		return cmp.Or(b.LastSeenAt.Compare(a.LastSeenAt), cmp.Compare(a.ID, b.ID))
+	})
+	return sessions, nil
+}
+
+// RevokeAllByUser signs out every session of a user, returning how many were signed in
+func (r *userSessionRepositoryImpl) RevokeAllByUser(ctx context.Context, userID string, reason domain.SessionRevocationReason, at time.Time) (int64, error) {
+	defer r.db.lock()()
+	return r.table().updateWhere(func(s *domain.UserSession) bool {
+		return s.UserID == userID && s.RevokedAt == nil
+	}, func(s *domain.UserSession) {
+		s.RevokedAt = &at
+		s.RevokedReason = &reason
+		s.UpdatedAt = at
+		s.UpdatedBy = &userID
+	}), nil
+}
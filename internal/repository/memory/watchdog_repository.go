@@ -0,0 +1,136 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// watchdogRepositoryImpl implements the WatchdogRepository interface
+type watchdogRepositoryImpl struct {
+	db *DB
+}
+
+// NewWatchdogRepository creates a new watchdog repository
+func NewWatchdogRepository(db *DB) domain.WatchdogRepository {
+	return &watchdogRepositoryImpl{db: db}
+}
+
+// RequeueEventLeases makes the unpublished events leased past the cutoff due at now
+func (r *watchdogRepositoryImpl) RequeueEventLeases(ctx context.Context, cutoff, now time.Time) (int64, error) {
+	defer r.db.lock()()
+	return tableOf[domain.DomainEvent](r.db).updateWhere(func(e *domain.DomainEvent) bool {
+		return e.IsUnpublished() && e.NextPublishAt != nil && e.NextPublishAt.After(cutoff)
+	}, func(e *domain.DomainEvent) {
+		e.NextPublishAt = &now
+	}), nil
+}
+
+// FindEventBacklog counts the unpublished events that were due before a time
+func (r *watchdogRepositoryImpl) FindEventBacklog(ctx context.Context, dueBefore time.Time) (domain.StuckItems, error) {
+	defer r.db.lock()()
+	var due []time.Time
+	for _, e := range tableOf[domain.DomainEvent](r.db).where((*domain.DomainEvent).IsUnpublished) {
+		at := e.OccurredAt
+		if e.NextPublishAt != nil {
+			at = *e.NextPublishAt
+		}
+		if at.Before(dueBefore) {
+			due = append(due, at)
+		}
+	}
+	return stuckItems(due), nil
+}
+
+// RequeueNotifications makes the pending notifications scheduled past the cutoff due at now
+func (r *watchdogRepositoryImpl) RequeueNotifications(ctx context.Context, cutoff, now time.Time) (int64, error) {
+	defer r.db.lock()()
+	return tableOf[domain.QueuedNotification](r.db).updateWhere(func(n *domain.QueuedNotification) bool {
+		return n.SentAt == nil && n.AbandonedAt == nil && n.NextAttemptAt.After(cutoff)
+	}, func(n *domain.QueuedNotification) {
+		n.NextAttemptAt = now
+	}), nil
+}
+
+// FindNotificationBacklog counts the pending notifications that were due before a time
+func (r *watchdogRepositoryImpl) FindNotificationBacklog(ctx context.Context, dueBefore time.Time) (domain.StuckItems, error) {
+	defer r.db.lock()()
+	var due []time.Time
+	for _, n := range tableOf[domain.QueuedNotification](r.db).where(func(n *domain.QueuedNotification) bool {
+		return n.SentAt == nil && n.AbandonedAt == nil && n.NextAttemptAt.Before(dueBefore)
+	}) {
+		due = append(due, n.NextAttemptAt)
+	}
+	return stuckItems(due), nil
+}
+
+// RequeueWebhookDeliveries makes the pending deliveries scheduled past the cutoff due at now
+func (r *watchdogRepositoryImpl) RequeueWebhookDeliveries(ctx context.Context, cutoff, now time.Time) (int64, error) {
+	defer r.db.lock()()
+	return tableOf[domain.WebhookDelivery](r.db).updateWhere(func(d *domain.WebhookDelivery) bool {
+		return d.IsPending() && d.NextAttemptAt.After(cutoff)
+	}, func(d *domain.WebhookDelivery) {
+		d.NextAttemptAt = now
+	}), nil
+}
+
+// FindWebhookBacklog counts the pending deliveries that were due before a time
+func (r *watchdogRepositoryImpl) FindWebhookBacklog(ctx context.Context, dueBefore time.Time) (domain.StuckItems, error) {
+	defer r.db.lock()()
+	var due []time.Time
+	for _, d := range tableOf[domain.WebhookDelivery](r.db).where(func(d *domain.WebhookDelivery) bool {
+		return d.IsPending() && d.NextAttemptAt.Before(dueBefore)
+	}) {
+		due = append(due, d.NextAttemptAt)
+	}
+	return stuckItems(due), nil
+}
+
+// FindStalledBroadcasts counts the broadcasts created before a time that never finished sending
+func (r *watchdogRepositoryImpl) FindStalledBroadcasts(ctx context.Context, createdBefore time.Time) (domain.StuckItems, error) {
+	defer r.db.lock()()
+	var created []time.Time
+	for _, b := range tableOf[domain.Broadcast](r.db).where(func(b *domain.Broadcast) bool {
+		return b.SentAt == nil && b.CreatedAt.Before(createdBefore)
+	}) {
+		created = append(created, b.CreatedAt)
+	}
+	return stuckItems(created), nil
+}
+
+// FindOpenPayments counts the pending payments, or those awaiting authentication, created before a time
+func (r *watchdogRepositoryImpl) FindOpenPayments(ctx context.Context, createdBefore time.Time) (domain.StuckItems, error) {
+	defer r.db.lock()()
+	var created []time.Time
+	for _, p := range tableOf[domain.Payment](r.db).where(func(p *domain.Payment) bool {
+		return p.IsOpen() && p.CreatedAt.Before(createdBefore)
+	}) {
+		created = append(created, p.CreatedAt)
+	}
+	return stuckItems(created), nil
+}
+
+// FindUnansweredRequests finds the appointments still pending acceptance that started before a
+// time, oldest first
+func (r *watchdogRepositoryImpl) FindUnansweredRequests(ctx context.Context, startedBefore time.Time, limit int) ([]*domain.Appointment, error) {
+	defer r.db.lock()()
+	appointments := tableOf[domain.Appointment](r.db).where(func(a *domain.Appointment) bool {
+		return a.Status == domain.AppointmentStatusPending && a.StartTime.Before(startedBefore)
+	})
+	slices.SortStableFunc(appointments, func(a, b *domain.Appointment) int {
+		return a.StartTime.Compare(b.StartTime)
+	})
+	return appointments[:min(limit, len(appointments))], nil
+}
+
+// stuckItems counts stuck items from when each was due or created
+func stuckItems(times []time.Time) domain.StuckItems {
+	items := domain.StuckItems{Count: int64(len(times))}
+	if len(times) > 0 {
+		oldest := slices.MinFunc(times, time.Time.Compare)
+		items.OldestAt = &oldest
+	}
+	return items
+}
@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// webhookRepositoryImpl implements the WebhookRepository interface
+type webhookRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.WebhookSubscription]
+}
+
+// NewWebhookRepository creates a new webhook repository, scoped to the tenant in the context
+func NewWebhookRepository(db *DB) domain.WebhookRepository {
+	return &webhookRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.WebhookSubscription]{db: db, tenantScoped: true},
+	}
+}
+
+// FindByBusiness finds the webhook subscriptions of a business, in creation order
+func (r *webhookRepositoryImpl) FindByBusiness(ctx context.Context, businessID string) ([]*domain.WebhookSubscription, error) {
+	defer r.db.lock()()
+	return r.table().where(func(s *domain.WebhookSubscription) bool { return s.BusinessID == businessID }), nil
+}
+
+// EnqueueDeliveries creates deliveries, skipping those already created for the same
+// subscription and event
+func (r *webhookRepositoryImpl) EnqueueDeliveries(ctx context.Context, deliveries []*domain.WebhookDelivery) error {
+	defer r.db.lock()()
+	table := tableOf[domain.WebhookDelivery](r.db)
+	for _, delivery := range deliveries {
+		exists := len(table.where(func(d *domain.WebhookDelivery) bool {
+			return d.SubscriptionID == delivery.SubscriptionID && d.EventID == delivery.EventID
+		})) > 0
+		if exists {
+			continue
+		}
+		if err := table.insert(delivery); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FindDueDeliveries finds the pending deliveries whose next attempt is due, oldest first
+func (r *webhookRepositoryImpl) FindDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*domain.WebhookDelivery, error) {
+	defer r.db.lock()()
+	deliveries := tableOf[domain.WebhookDelivery](r.db).where(func(d *domain.WebhookDelivery) bool {
+		return d.IsPending() && !d.NextAttemptAt.After(now)
+	})
+	slices.SortStableFunc(deliveries, func(a, b *domain.WebhookDelivery) int {
+		return cmp.Or(a.NextAttemptAt.Compare(b.NextAttemptAt), cmp.Compare(a.ID, b.ID))
+	})
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
+
+// UpdateDelivery saves the outcome of a delivery attempt
+func (r *webhookRepositoryImpl) UpdateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	defer r.db.lock()()
+	return tableOf[domain.WebhookDelivery](r.db).save(delivery)
+}
+
+// FindDeliveries finds the deliveries of a subscription, latest first
+func (r *webhookRepositoryImpl) FindDeliveries(ctx context.Context, subscriptionID string, page, pageSize int) ([]*domain.WebhookDelivery, int64, error) {
+	defer r.db.lock()()
+	deliveries := tableOf[domain.WebhookDelivery](r.db).where(func(d *domain.WebhookDelivery) bool {
+		return d.SubscriptionID == subscriptionID
+	})
+	slices.Reverse(deliveries)
+	return paginate(deliveries, page, pageSize), int64(len(deliveries)), nil
+}
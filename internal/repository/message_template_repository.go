@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// messageTemplateRepositoryImpl implements the MessageTemplateRepository interface
+type messageTemplateRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.MessageTemplate]
+}
+
+// NewMessageTemplateRepository creates a new message template repository, scoped to the tenant in
+// the context
+func NewMessageTemplateRepository(db *gorm.DB) domain.MessageTemplateRepository {
+	return &messageTemplateRepositoryImpl{
+		BaseRepositoryImpl: newBaseRepository[domain.MessageTemplate](db, WithTenantScope()),
+	}
+}
+
+// FindByBusinessID finds all message templates of a business
+func (r *messageTemplateRepositoryImpl) FindByBusinessID(ctx context.Context, businessID string) ([]*domain.MessageTemplate, error) {
+	var templates []*domain.MessageTemplate
+	err := r.query(ctx).
+		Where("business_id = ? AND deleted_at IS NULL", businessID).
+		Order("channel ASC, name ASC").
+		Find(&templates).Error
+	return templates, err
+}
+
+// FindActiveByPurpose finds the most recently updated active template of a business for a channel and purpose
+func (r *messageTemplateRepositoryImpl) FindActiveByPurpose(ctx context.Context, businessID string, channel domain.MessageChannel, purpose string) (*domain.MessageTemplate, error) {
+	var template domain.MessageTemplate
+	err := r.query(ctx).
+		Where("business_id = ? AND channel = ? AND purpose = ? AND is_active = TRUE AND deleted_at IS NULL", businessID, channel, purpose).
+		Order("updated_at DESC").
+		First(&template).Error
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// WithTx returns a new repository instance with the given transaction
+func (r *messageTemplateRepositoryImpl) WithTx(tx *gorm.DB) domain.BaseRepository[domain.MessageTemplate] {
+	return r.withDB(tx)
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// moneyMigrationRepositoryImpl implements the MoneyMigrationRepository interface
+type moneyMigrationRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewMoneyMigrationRepository creates a new money migration repository
+func NewMoneyMigrationRepository(db *gorm.DB) domain.MoneyMigrationRepository {
+	return &moneyMigrationRepositoryImpl{db: db}
+}
+
+// HasMinorUnits reports whether the minor units column of a monetary column exists
+func (r *moneyMigrationRepositoryImpl) HasMinorUnits(ctx context.Context, column domain.MoneyColumn) (bool, error) {
+	var count int64
+	err := conn(ctx, r.db).Raw(`
+		SELECT COUNT(*) FROM information_schema.columns
+		WHERE table_schema = 'public' AND table_name = ? AND column_name = ?`,
+		column.Table, column.MinorColumn(),
+	).Scan(&count).Error
+	return count > 0, err
+}
+
+// Backfill converts a batch of the amounts written before the minor units column existed.
+// Rows are locked one batch at a time, skipping rows being written, so the tables stay usable.
+// Soft-deleted rows are converted too.
+func (r *moneyMigrationRepositoryImpl) Backfill(ctx context.Context, column domain.MoneyColumn, batchSize int) (int64, error) {
+	if batchSize <= 0 {
+		return 0, fmt.Errorf("%w: the batch size must be positive", domain.ErrValidation)
+	}
+	table, amount, minor := quoted(column.Table), quoted(column.Column), quoted(column.MinorColumn())
+	result := conn(ctx, r.db).Exec(`
+		UPDATE public.`+table+` SET `+minor+` = round(`+amount+` * 100)
+		WHERE id IN (
+			SELECT id FROM public.`+table+`
+			WHERE `+minor+` IS NULL AND `+amount+` IS NOT NULL
+			LIMIT ? FOR UPDATE SKIP LOCKED
+		)`, batchSize)
+	return result.RowsAffected, result.Error
+}
+
+// Verify counts the rows of a monetary column whose minor units are missing or wrong, and
+// compares the totals of both columns
+func (r *moneyMigrationRepositoryImpl) Verify(ctx context.Context, column domain.MoneyColumn) (*domain.MoneyColumnReport, error) {
+	table, amount, minor := quoted(column.Table), quoted(column.Column), quoted(column.MinorColumn())
+	var row struct {
+		Rows       int64
+		Missing    int64
+		Mismatched int64
+		Fractional int64
+		Sum        decimal.Decimal
+		SumMinor   int64
+	}
+	err := conn(ctx, r.db).Raw(`
+		SELECT
+			COUNT(*) AS rows,
+			COUNT(*) FILTER (WHERE ` + amount + ` IS NOT NULL AND ` + minor + ` IS NULL) AS missing,
+			COUNT(*) FILTER (WHERE ` + minor + ` <> round(` + amount + ` * 100)) AS mismatched,
+			COUNT(*) FILTER (WHERE ` + amount + ` * 100 <> trunc(` + amount + ` * 100)) AS fractional,
+			COALESCE(SUM(` + amount + `), 0) AS sum,
+			COALESCE(SUM(` + minor + `), 0) AS sum_minor
+		FROM public.` + table).Scan(&row).Error
+	if err != nil {
+		return nil, err
+	}
+	return &domain.MoneyColumnReport{
+		Column:     column,
+		Rows:       row.Rows,
+		Missing:    row.Missing,
+		Mismatched: row.Mismatched,
+		Fractional: row.Fractional,
+		Sum:        row.Sum,
+		SumMinor:   row.SumMinor,
+	}, nil
+}
+
+// quoted quotes an identifier for SQL
+func quoted(identifier string) string {
+	return `"` + identifier + `"`
+}
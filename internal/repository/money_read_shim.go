@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"reflect"
+	"slices"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/rs/zerolog/log"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// moneyReadShim reads the monetary amounts of the entities a query loads from their minor units
+// columns during the dual read phase of the money migration
+type moneyReadShim struct{}
+
+// NewMoneyReadShim returns a GORM plugin reading monetary amounts from their minor units, so the
+// integer columns are relied on before the decimal ones are dropped. Amounts not converted yet
+// are kept as read, and amounts whose minor units disagree are logged.
+func NewMoneyReadShim() gorm.Plugin {
+	return moneyReadShim{}
+}
+
+// Name returns the name of the plugin
+func (moneyReadShim) Name() string { return "money_read_shim" }
+
+// Initialize registers the shim after the queries loading entities
+func (s moneyReadShim) Initialize(db *gorm.DB) error {
+	return db.Callback().Query().After("gorm:query").Register("money:read_minor_units", s.afterQuery)
+}
+
+// afterQuery replaces the amounts of the entities loaded with those of their minor units
+func (moneyReadShim) afterQuery(db *gorm.DB) {
+	statement := db.Statement
+	if db.Error != nil || statement.Schema == nil || db.RowsAffected == 0 {
+		return
+	}
+	idField := statement.Schema.LookUpField("id")
+	if idField == nil {
+		return
+	}
+	fields := map[string]*schema.Field{}
+	for _, column := range domain.MoneyColumnsOf(statement.Schema.Table) {
+		if field := statement.Schema.LookUpField(column.Column); field != nil && selected(statement, column.Column) {
+			fields[column.MinorColumn()] = field
+		}
+	}
+	if len(fields) == 0 {
+		return
+	}
+
+	entities := map[string][]reflect.Value{}
+	for _, entity := range loadedEntities(statement.ReflectValue) {
+		if id, zero := idField.ValueOf(statement.Context, entity); !zero {
+			if id, ok := id.(string); ok {
+				entities[id] = append(entities[id], entity)
+			}
+		}
+	}
+	if len(entities) == 0 {
+		return
+	}
+
+	columns := []string{"id"}
+	for column := range fields {
+		columns = append(columns, column)
+	}
+	var rows []map[string]any
+	err := db.Session(&gorm.Session{NewDB: true}).Table(statement.Schema.Table).
+		Select(columns).Where("id IN ?", keys(entities)).Find(&rows).Error
+	if err != nil {
+		// The decimal amounts were read already, so the query still succeeds
+		log.Warn().Err(err).Str("table", statement.Schema.Table).Msg("Failed to read minor units")
+		return
+	}
+
+	for _, row := range rows {
+		id, _ := row["id"].(string)
+		for column, field := range fields {
+			units, ok := row[column].(int64)
+			if !ok {
+				continue
+			}
+			amount := domain.FromStoredUnits(units)
+			for _, entity := range entities[id] {
+				setAmount(statement, field, entity, amount, id)
+			}
+		}
+	}
+}
+
+// setAmount sets an amount read from minor units on an entity, logging when it disagrees with
+// the decimal amount read
+func setAmount(statement *gorm.Statement, field *schema.Field, entity reflect.Value, amount decimal.Decimal, id string) {
+	value := field.ReflectValueOf(statement.Context, entity)
+	if value.Kind() == reflect.Pointer {
+		if !value.IsNil() && !value.Interface().(*decimal.Decimal).Equal(amount) {
+			logMismatch(statement, field, id, *value.Interface().(*decimal.Decimal), amount)
+		}
+		value.Set(reflect.ValueOf(&amount))
+		return
+	}
+	if read, ok := value.Interface().(decimal.Decimal); ok {
+		if !read.Equal(amount) {
+			logMismatch(statement, field, id, read, amount)
+		}
+		value.Set(reflect.ValueOf(amount))
+	}
+}
+
+// logMismatch logs an amount whose minor units disagree with its decimal column
+func logMismatch(statement *gorm.Statement, field *schema.Field, id string, read, amount decimal.Decimal) {
+	log.Warn().
+		Str("table", statement.Schema.Table).
+		Str("column", field.DBName).
+		Str("id", id).
+		Str("decimal", read.String()).
+		Str("minor_units", amount.String()).
+		Msg("Monetary amount disagrees with its minor units")
+}
+
+// selected reports whether a query loaded a column, which it does unless it chose others
+func selected(statement *gorm.Statement, column string) bool {
+	return len(statement.Selects) == 0 || slices.Contains(statement.Selects, column) || slices.Contains(statement.Selects, "*")
+}
+
+// loadedEntities returns the structs a query loaded into, whether one or a slice of them
+func loadedEntities(value reflect.Value) []reflect.Value {
+	value = reflect.Indirect(value)
+	switch value.Kind() {
+	case reflect.Struct:
+		return []reflect.Value{value}
+	case reflect.Slice, reflect.Array:
+		entities := make([]reflect.Value, 0, value.Len())
+		for i := range value.Len() {
+			if entity := reflect.Indirect(value.Index(i)); entity.Kind() == reflect.Struct {
+				entities = append(entities, entity)
+			}
+		}
+		return entities
+	}
+	return nil
+}
+
+// keys returns the keys of a map
+func keys[V any](m map[string]V) []string {
+	result := make([]string, 0, len(m))
+	for key := range m {
+		result = append(result, key)
+	}
+	return result
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// notificationQueueRepositoryImpl implements the NotificationQueueRepository interface
+type notificationQueueRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.QueuedNotification]
+}
+
+// NewNotificationQueueRepository creates a new notification queue repository
+func NewNotificationQueueRepository(db *gorm.DB) domain.NotificationQueueRepository {
+	return &notificationQueueRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.QueuedNotification]{db: db},
+	}
+}
+
+// FindDue finds the pending notifications whose next attempt is due, oldest first
+func (r *notificationQueueRepositoryImpl) FindDue(ctx context.Context, now time.Time, limit int) ([]*domain.QueuedNotification, error) {
+	var notifications []*domain.QueuedNotification
+	err := conn(ctx, r.db).
+		Where("sent_at IS NULL AND abandoned_at IS NULL AND next_attempt_at <= ?", now).
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		Find(&notifications).Error
+	return notifications, err
+}
+
+// WithTx returns a new repository instance with the given transaction
+func (r *notificationQueueRepositoryImpl) WithTx(tx *gorm.DB) domain.BaseRepository[domain.QueuedNotification] {
+	return &notificationQueueRepositoryImpl{BaseRepositoryImpl: &BaseRepositoryImpl[domain.QueuedNotification]{db: tx}}
+}
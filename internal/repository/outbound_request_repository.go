@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// outboundRequestRepositoryImpl implements the OutboundRequestRepository interface
+type outboundRequestRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.OutboundRequest]
+}
+
+// NewOutboundRequestRepository creates a new outbound request repository
+func NewOutboundRequestRepository(db *gorm.DB) domain.OutboundRequestRepository {
+	return &outboundRequestRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.OutboundRequest]{db: db},
+	}
+}
+
+// FindByFilter finds the outbound requests matching the filter, most recent first
+func (r *outboundRequestRepositoryImpl) FindByFilter(ctx context.Context, filter domain.OutboundRequestFilter) ([]*domain.OutboundRequest, error) {
+	query := conn(ctx, r.db)
+	if filter.Provider != nil {
+		query = query.Where("provider = ?", *filter.Provider)
+	}
+	if filter.FailedOnly {
+		query = query.Where("error IS NOT NULL OR status_code IS NULL OR status_code >= 400")
+	}
+	if filter.StartDate != nil {
+		query = query.Where("created_at >= ?", *filter.StartDate)
+	}
+	if filter.EndDate != nil {
+		query = query.Where("created_at < ?", *filter.EndDate)
+	}
+
+	var requests []*domain.OutboundRequest
+	err := query.Order("created_at DESC").Limit(filter.Limit).Find(&requests).Error
+	return requests, err
+}
+
+// DeleteBefore permanently deletes the outbound requests made before a time
+func (r *outboundRequestRepositoryImpl) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := conn(ctx, r.db).Unscoped().Where("created_at < ?", before).Delete(&domain.OutboundRequest{})
+	return result.RowsAffected, result.Error
+}
+
+// WithTx returns a new repository instance with the given transaction
+func (r *outboundRequestRepositoryImpl) WithTx(tx *gorm.DB) domain.BaseRepository[domain.OutboundRequest] {
+	return &outboundRequestRepositoryImpl{BaseRepositoryImpl: &BaseRepositoryImpl[domain.OutboundRequest]{db: tx}}
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// depositDueSQL sums the deposits of the services booked for an appointment
+const depositDueSQL = `
+	SELECT COALESCE(SUM(s.deposit_amount), 0)
+	FROM appointment_services l
+	JOIN services s ON s.id = l.service_id
+	WHERE l.appointment_id = ?
+		AND l.deleted_at IS NULL
+		AND s.requires_deposit`
+
+// openDepositIndex is the unique index allowing one deposit paid or in progress per appointment
+const openDepositIndex = "uq_payments_open_deposit"
+
+// paymentRepositoryImpl implements the PaymentRepository interface
+type paymentRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.Payment]
+}
+
+// NewPaymentRepository creates a new payment repository, scoped to the tenant in the context
+func NewPaymentRepository(db *gorm.DB) domain.PaymentRepository {
+	return &paymentRepositoryImpl{
+		BaseRepositoryImpl: newBaseRepository[domain.Payment](db, WithTenantScope()),
+	}
+}
+
+// Create creates a payment, failing with domain.ErrDepositAlreadyCharged for a deposit of an
+// appointment that already has one paid or in progress
+func (r *paymentRepositoryImpl) Create(ctx context.Context, payment *domain.Payment) error {
+	err := r.BaseRepositoryImpl.Create(ctx, payment)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == openDepositIndex {
+		return domain.ErrDepositAlreadyCharged
+	}
+	return err
+}
+
+// GetByIDForUpdate retrieves a payment and locks it until the unit of work the call runs in
+// ends. Outside a unit of work the lock is released as soon as the payment is read.
+func (r *paymentRepositoryImpl) GetByIDForUpdate(ctx context.Context, id string) (*domain.Payment, error) {
+	var payment domain.Payment
+	err := r.query(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&payment).Error
+	if err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// FindByAppointmentID finds the payments of an appointment, oldest first
+func (r *paymentRepositoryImpl) FindByAppointmentID(ctx context.Context, appointmentID string) ([]*domain.Payment, error) {
+	var payments []*domain.Payment
+	err := r.query(ctx).
+		Where("appointment_id = ?", appointmentID).
+		Order("created_at ASC").
+		Find(&payments).Error
+	return payments, err
+}
+
+// FindDepositDue sums the deposits of the services booked for an appointment
+func (r *paymentRepositoryImpl) FindDepositDue(ctx context.Context, appointmentID string) (decimal.Decimal, error) {
+	var total decimal.Decimal
+	if err := r.ensureParentOwned(ctx, &domain.Appointment{}, appointmentID); err != nil {
+		return total, err
+	}
+	err := conn(ctx, r.db).Raw(depositDueSQL, appointmentID).Scan(&total).Error
+	return total, err
+}
+
+// SetAppointmentPaymentStatus sets the payment status of an appointment
+func (r *paymentRepositoryImpl) SetAppointmentPaymentStatus(ctx context.Context, appointmentID string, status domain.AppointmentPaymentStatus) error {
+	return r.query(ctx).
+		Table("appointments").
+		Where("id = ?", appointmentID).
+		Updates(map[string]any{"payment_status": status, "updated_at": time.Now()}).Error
+}
+
+// SetServiceDeposit sets the deposit charged at booking for a service. The service model has
+// fields without columns, so only the deposit columns are written.
+func (r *paymentRepositoryImpl) SetServiceDeposit(ctx context.Context, serviceID string, requiresDeposit bool, amount *decimal.Decimal) error {
+	result := r.query(ctx).
+		Model(&domain.Service{}).
+		Where("id = ? AND deleted_at IS NULL", serviceID).
+		Updates(map[string]any{
+			"requires_deposit": requiresDeposit,
+			"deposit_amount":   amount,
+			"updated_at":       time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// RecordCompletion creates the completion of an appointment paid by card or gift card and marks the
+// appointment completed at the charged price in a single transaction
+func (r *paymentRepositoryImpl) RecordCompletion(ctx context.Context, completion *domain.ServiceCompletion) error {
+	if err := r.ensureParentOwned(ctx, &domain.Appointment{}, completion.AppointmentID); err != nil {
+		return err
+	}
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Appointment").Create(completion).Error; err != nil {
+			return err
+		}
+		return r.scoped(ctx, tx).Table("appointments").
+			Where("id = ?", completion.AppointmentID).
+			Updates(map[string]any{
+				"status":         domain.AppointmentStatusCompleted,
+				"actual_price":   completion.PriceCharged,
+				"payment_method": completion.PaymentMethod,
+				"updated_at":     time.Now(),
+				"updated_by":     completion.CreatedBy,
+			}).Error
+	})
+}
+
+// WithTx returns a new repository instance with the given transaction
+func (r *paymentRepositoryImpl) WithTx(tx *gorm.DB) domain.BaseRepository[domain.Payment] {
+	return r.withDB(tx)
+}
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// priceChangeRepositoryImpl implements the PriceChangeRepository interface
+type priceChangeRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.PriceChange]
+}
+
+// NewPriceChangeRepository creates a new price change repository, scoped to the tenant in the
+// context
+func NewPriceChangeRepository(db *gorm.DB) domain.PriceChangeRepository {
+	return &priceChangeRepositoryImpl{
+		BaseRepositoryImpl: newBaseRepository[domain.PriceChange](db, WithTenantScope()),
+	}
+}
+
+// FindByBusinessID finds all price changes of a business, latest effective date first
+func (r *priceChangeRepositoryImpl) FindByBusinessID(ctx context.Context, businessID string) ([]*domain.PriceChange, error) {
+	var changes []*domain.PriceChange
+	err := r.query(ctx).
+		Where("business_id = ?", businessID).
+		Order("effective_at DESC").
+		Find(&changes).Error
+	return changes, err
+}
+
+// FindTargetServices finds the business's services that are selected directly or through their category
+func (r *priceChangeRepositoryImpl) FindTargetServices(ctx context.Context, businessID string, serviceIDs, categoryIDs []string) ([]*domain.Service, error) {
+	return findTargetServices(r.query(ctx), businessID, serviceIDs, categoryIDs)
+}
+
+func findTargetServices(db *gorm.DB, businessID string, serviceIDs, categoryIDs []string) ([]*domain.Service, error) {
+	var services []*domain.Service
+	query := db.Where("business_id = ?", businessID)
+	switch {
+	case len(serviceIDs) > 0 && len(categoryIDs) > 0:
+		query = query.Where("id IN ? OR category_id IN ?", serviceIDs, categoryIDs)
+	case len(serviceIDs) > 0:
+		query = query.Where("id IN ?", serviceIDs)
+	case len(categoryIDs) > 0:
+		query = query.Where("category_id IN ?", categoryIDs)
+	default:
+		return services, nil
+	}
+	err := query.Order("name ASC").Find(&services).Error
+	return services, err
+}
+
+// FindDue finds the scheduled price changes whose effective date has passed
+func (r *priceChangeRepositoryImpl) FindDue(ctx context.Context, now time.Time) ([]*domain.PriceChange, error) {
+	if err := r.checkUnscoped(ctx); err != nil {
+		return nil, err
+	}
+	var changes []*domain.PriceChange
+	err := conn(ctx, r.db).
+		Where("status = ? AND effective_at <= ?", domain.PriceChangeScheduled, now).
+		Order("effective_at ASC").
+		Find(&changes).Error
+	return changes, err
+}
+
+// Apply adjusts the current prices of the change's services, records their history and marks
+// the change applied in a single transaction. The change row is locked so that concurrent
+// workers apply it once.
+func (r *priceChangeRepositoryImpl) Apply(ctx context.Context, change *domain.PriceChange, appliedAt time.Time) ([]*domain.ServicePriceHistory, error) {
+	var history []*domain.ServicePriceHistory
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var current domain.PriceChange
+		if err := r.scoped(ctx, tx).Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", change.ID).First(&current).Error; err != nil {
+			return err
+		}
+		if current.Status != domain.PriceChangeScheduled {
+			*change = current
+			return nil
+		}
+
+		serviceIDs, err := current.GetServiceIDs()
+		if err != nil {
+			return err
+		}
+		categoryIDs, err := current.GetCategoryIDs()
+		if err != nil {
+			return err
+		}
+		services, err := findTargetServices(tx, current.BusinessID, serviceIDs, categoryIDs)
+		if err != nil {
+			return err
+		}
+		previews, err := domain.PreviewPriceChange(services, current.Adjustment())
+		if err != nil {
+			return err
+		}
+
+		for _, preview := range previews {
+			err := tx.Model(&domain.Service{}).
+				Where("id = ?", preview.ServiceID).
+				Updates(map[string]any{"price": preview.NewPrice, "updated_at": appliedAt, "updated_by": current.UpdatedBy}).Error
+			if err != nil {
+				return err
+			}
+			history = append(history, &domain.ServicePriceHistory{
+				BusinessID:    current.BusinessID,
+				ServiceID:     preview.ServiceID,
+				PriceChangeID: &current.ID,
+				OldPrice:      preview.OldPrice,
+				NewPrice:      preview.NewPrice,
+				ChangedAt:     appliedAt,
+				ChangedBy:     current.UpdatedBy,
+			})
+		}
+		if len(history) > 0 {
+			if err := tx.Create(&history).Error; err != nil {
+				return err
+			}
+		}
+
+		current.Status = domain.PriceChangeApplied
+		current.AppliedAt = &appliedAt
+		if err := tx.Save(&current).Error; err != nil {
+			return err
+		}
+		*change = current
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// FindHistoryByServiceID finds the price history of a service, most recent first
+func (r *priceChangeRepositoryImpl) FindHistoryByServiceID(ctx context.Context, serviceID string) ([]*domain.ServicePriceHistory, error) {
+	var history []*domain.ServicePriceHistory
+	err := r.query(ctx).
+		Where("service_id = ?", serviceID).
+		Order("changed_at DESC").
+		Find(&history).Error
+	return history, err
+}
+
+// WithTx returns a new repository instance with the given transaction
+func (r *priceChangeRepositoryImpl) WithTx(tx *gorm.DB) domain.BaseRepository[domain.PriceChange] {
+	return &priceChangeRepositoryImpl{BaseRepositoryImpl: r.withDB(tx)}
+}
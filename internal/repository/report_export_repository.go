@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// reportServicesSQL lists the names of an appointment's services, comma separated
+const reportServicesSQL = `COALESCE((
+		SELECT STRING_AGG(s.name, ', ' ORDER BY s.name) FROM appointment_services aps
+		JOIN services s ON s.id = aps.service_id
+		WHERE aps.appointment_id = a.id AND aps.deleted_at IS NULL
+	), '')`
+
+// revenueRowsSQL finds the completed services of the appointments of a business starting in a
+// range, with the names of those involved
+const revenueRowsSQL = `
+	SELECT a.id AS appointment_id, COALESCE(sc.completion_date, a.end_time) AS completed_at,
+		CONCAT_WS(' ', c.first_name, c.last_name) AS client_name,
+		CONCAT_WS(' ', u.first_name, u.last_name) AS staff_name,
+		` + reportServicesSQL + ` AS services,
+		sc.payment_method, sc.price_charged AS amount
+	FROM service_completions sc
+	JOIN appointments a ON a.id = sc.appointment_id AND a.deleted_at IS NULL
+	JOIN clients c ON c.id = a.client_id
+	JOIN staff st ON st.id = a.staff_id
+	JOIN users u ON u.id = st.user_id
+	WHERE a.business_id = @business AND sc.deleted_at IS NULL
+		AND a.start_time >= @start AND a.start_time < @end
+	ORDER BY completed_at ASC, a.id ASC`
+
+// appointmentRowsSQL finds the appointments of a business starting in a range, with the names of
+// those involved
+const appointmentRowsSQL = `
+	SELECT a.id AS appointment_id, a.start_time, a.end_time, a.status,
+		CONCAT_WS(' ', c.first_name, c.last_name) AS client_name,
+		CONCAT_WS(' ', u.first_name, u.last_name) AS staff_name,
+		` + reportServicesSQL + ` AS services,
+		a.total_price
+	FROM appointments a
+	JOIN clients c ON c.id = a.client_id
+	JOIN staff st ON st.id = a.staff_id
+	JOIN users u ON u.id = st.user_id
+	WHERE a.business_id = @business AND a.deleted_at IS NULL
+		AND a.start_time >= @start AND a.start_time < @end
+	ORDER BY a.start_time ASC, a.id ASC`
+
+// reportExportRepositoryImpl implements the ReportExportRepository interface
+type reportExportRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.ReportExport]
+}
+
+// NewReportExportRepository creates a new report export repository, scoped to the tenant in
+// the context
+func NewReportExportRepository(db *gorm.DB) domain.ReportExportRepository {
+	return &reportExportRepositoryImpl{
+		BaseRepositoryImpl: newBaseRepository[domain.ReportExport](db, WithTenantScope()),
+	}
+}
+
+// FindByBusinessID finds the most recent exports of a business, newest first
+func (r *reportExportRepositoryImpl) FindByBusinessID(ctx context.Context, businessID string, limit int) ([]*domain.ReportExport, error) {
+	exports := []*domain.ReportExport{}
+	err := r.query(ctx).
+		Where("business_id = ?", businessID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&exports).Error
+	return exports, err
+}
+
+// FindRevenueRows finds the services completed on appointments of a business starting in the
+// range, ordered by completion
+func (r *reportExportRepositoryImpl) FindRevenueRows(ctx context.Context, businessID string, start, end time.Time) ([]*domain.RevenueReportRow, error) {
+	if err := r.checkBusiness(ctx, businessID); err != nil {
+		return nil, err
+	}
+	rows := []*domain.RevenueReportRow{}
+	err := conn(ctx, r.db).Raw(revenueRowsSQL, map[string]any{
+		"business": businessID,
+		"start":    start,
+		"end":      end,
+	}).Scan(&rows).Error
+	return rows, err
+}
+
+// FindAppointmentRows finds the appointments of a business starting in the range, ordered by
+// start time
+func (r *reportExportRepositoryImpl) FindAppointmentRows(ctx context.Context, businessID string, start, end time.Time) ([]*domain.AppointmentReportRow, error) {
+	if err := r.checkBusiness(ctx, businessID); err != nil {
+		return nil, err
+	}
+	rows := []*domain.AppointmentReportRow{}
+	err := conn(ctx, r.db).Raw(appointmentRowsSQL, map[string]any{
+		"business": businessID,
+		"start":    start,
+		"end":      end,
+	}).Scan(&rows).Error
+	return rows, err
+}
+
+// FindClients finds the clients of a business, ordered by last and first name
+func (r *reportExportRepositoryImpl) FindClients(ctx context.Context, businessID string) ([]*domain.Client, error) {
+	clients := []*domain.Client{}
+	err := r.query(ctx).
+		Where("business_id = ?", businessID).
+		Order("last_name ASC, first_name ASC, id ASC").
+		Find(&clients).Error
+	return clients, err
+}
+
+// WithTx returns a new repository instance with the given transaction
+func (r *reportExportRepositoryImpl) WithTx(tx *gorm.DB) domain.BaseRepository[domain.ReportExport] {
+	return r.withDB(tx)
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// resourceRepositoryImpl implements the ResourceRepository interface
+type resourceRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.Resource]
+}
+
+// NewResourceRepository creates a new resource repository, scoped to the tenant in the context
+func NewResourceRepository(db *gorm.DB) domain.ResourceRepository {
+	return &resourceRepositoryImpl{
+		BaseRepositoryImpl: newBaseRepository[domain.Resource](db, WithTenantScope()),
+	}
+}
+
+// FindByBusiness finds the resources of a business by name, only those at a location when one
+// is given
+func (r *resourceRepositoryImpl) FindByBusiness(ctx context.Context, businessID string, locationID *string) ([]*domain.Resource, error) {
+	resources := []*domain.Resource{}
+	query := r.query(ctx).Where("business_id = ?", businessID)
+	if locationID != nil {
+		query = query.Where("location_id = ?", *locationID)
+	}
+	err := query.Order("name ASC, id ASC").Find(&resources).Error
+	return resources, err
+}
+
+// FindByServices finds the active resources the services need, by name
+func (r *resourceRepositoryImpl) FindByServices(ctx context.Context, serviceIDs []string) ([]*domain.Resource, error) {
+	resources := []*domain.Resource{}
+	if len(serviceIDs) == 0 {
+		return resources, nil
+	}
+	err := r.query(ctx).
+		Where("is_active AND id IN (?)",
+			r.query(ctx).Model(&domain.ServiceResource{}).Select("resource_id").Where("service_id IN ?", serviceIDs)).
+		Order("name ASC, id ASC").
+		Find(&resources).Error
+	return resources, err
+}
+
+// SetServiceResources replaces the resources a service needs in a single transaction. Replaced
+// rows are deleted for good, as a service needs a resource once at most.
+func (r *resourceRepositoryImpl) SetServiceResources(ctx context.Context, serviceID string, rows []*domain.ServiceResource) error {
+	if err := r.ensureParentOwned(ctx, &domain.Service{}, serviceID); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := r.checkBusiness(ctx, row.BusinessID); err != nil {
+			return err
+		}
+	}
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := r.scoped(ctx, tx).Unscoped().Where("service_id = ?", serviceID).Delete(&domain.ServiceResource{}).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+// WithTx returns a new repository instance with the given transaction
+func (r *resourceRepositoryImpl) WithTx(tx *gorm.DB) domain.BaseRepository[domain.Resource] {
+	return r.withDB(tx)
+}
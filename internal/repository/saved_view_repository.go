@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// savedViewRepositoryImpl implements the SavedViewRepository interface
+type savedViewRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.SavedView]
+}
+
+// NewSavedViewRepository creates a new saved view repository, scoped to the tenant in the context
+func NewSavedViewRepository(db *gorm.DB) domain.SavedViewRepository {
+	return &savedViewRepositoryImpl{
+		BaseRepositoryImpl: newBaseRepository[domain.SavedView](db, WithTenantScope()),
+	}
+}
+
+// FindVisible finds the views of a business a user with a role can use, optionally of one list
+// only, ordered by name
+func (r *savedViewRepositoryImpl) FindVisible(ctx context.Context, businessID, userID string, role domain.BusinessRole, entity *domain.SavedViewEntity) ([]*domain.SavedView, error) {
+	sharedWith, _ := json.Marshal([]domain.BusinessRole{role})
+	query := r.query(ctx).
+		Where("business_id = ? AND (user_id = ? OR shared_with_roles @> ?::jsonb)", businessID, userID, string(sharedWith))
+	if entity != nil {
+		query = query.Where("entity = ?", *entity)
+	}
+
+	var views []*domain.SavedView
+	err := query.Order("LOWER(name), id").Find(&views).Error
+	return views, err
+}
+
+// FindByOwnerAndName finds a user's view of a list by name, ignoring case
+func (r *savedViewRepositoryImpl) FindByOwnerAndName(ctx context.Context, businessID, userID string, entity domain.SavedViewEntity, name string) (*domain.SavedView, error) {
+	var view domain.SavedView
+	err := r.query(ctx).
+		Where("business_id = ? AND user_id = ? AND entity = ? AND LOWER(name) = ?", businessID, userID, entity, strings.ToLower(name)).
+		First(&view).Error
+	if err != nil {
+		return nil, err
+	}
+	return &view, nil
+}
+
+// CountByOwner counts a user's views of a list
+func (r *savedViewRepositoryImpl) CountByOwner(ctx context.Context, businessID, userID string, entity domain.SavedViewEntity) (int64, error) {
+	var count int64
+	err := r.query(ctx).Model(&domain.SavedView{}).
+		Where("business_id = ? AND user_id = ? AND entity = ?", businessID, userID, entity).
+		Count(&count).Error
+	return count, err
+}
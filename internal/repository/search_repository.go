@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// searchNameExpression is the client name as the trigram index on clients has it
+const searchNameExpression = "public.search_normalize(clients.first_name || ' ' || clients.last_name)"
+
+// searchRepositoryImpl implements the SearchRepository interface
+type searchRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewSearchRepository creates a new search repository
+func NewSearchRepository(db *gorm.DB) domain.SearchRepository {
+	return &searchRepositoryImpl{db: db}
+}
+
+// clientHit is a client found by a search and its rank
+type clientHit struct {
+	domain.Client `gorm:"embedded"`
+	SearchRank    float64 `gorm:"column:search_rank"`
+}
+
+// serviceHit is a service found by a search and its rank
+type serviceHit struct {
+	domain.Service `gorm:"embedded"`
+	SearchRank     float64 `gorm:"column:search_rank"`
+}
+
+// Search matches the search vectors of clients and services against the query's terms as word
+// prefixes, ranking with ts_rank. Client names also match by trigram similarity, so that a typo
+// still finds the client, and phone numbers match the query's digits anywhere.
+func (r *searchRepositoryImpl) Search(ctx context.Context, query *domain.SearchQuery) ([]*domain.SearchResult, error) {
+	tsQuery := prefixTSQuery(query.Terms)
+	text := strings.Join(query.Terms, " ")
+	var results []*domain.SearchResult
+
+	if query.Includes(domain.SearchResultClient) {
+		conditions := "clients.search_vector @@ search.query OR ? <% " + searchNameExpression
+		args := []any{text}
+		rank := "ts_rank(clients.search_vector, search.query) + word_similarity(?, " + searchNameExpression + ")"
+		rankArgs := []any{text}
+		if query.Digits != "" {
+			conditions += " OR regexp_replace(coalesce(clients.phone, ''), '[^0-9]', '', 'g') LIKE ?"
+			args = append(args, "%"+query.Digits+"%")
+			rank += " + CASE WHEN regexp_replace(coalesce(clients.phone, ''), '[^0-9]', '', 'g') LIKE ? THEN 1 ELSE 0 END"
+			rankArgs = append(rankArgs, "%"+query.Digits+"%")
+		}
+
+		var hits []clientHit
+		err := conn(ctx, r.db).Raw(`SELECT clients.*, `+rank+` AS search_rank
+			FROM clients, to_tsquery('simple', ?) AS search(query)
+			WHERE clients.business_id = ? AND clients.deleted_at IS NULL AND clients.anonymized_at IS NULL
+			AND (`+conditions+`)
+			ORDER BY search_rank DESC, clients.last_name, clients.first_name
+			LIMIT ?`, append(append(append(rankArgs, tsQuery, query.BusinessID), args...), query.Limit)...).
+			Scan(&hits).Error
+		if err != nil {
+			return nil, err
+		}
+		for i := range hits {
+			results = append(results, &domain.SearchResult{Kind: domain.SearchResultClient, Client: &hits[i].Client, Rank: hits[i].SearchRank})
+		}
+	}
+
+	if query.Includes(domain.SearchResultService) {
+		var hits []serviceHit
+		err := conn(ctx, r.db).Raw(`SELECT services.*, ts_rank(services.search_vector, search.query) AS search_rank
+			FROM services, to_tsquery('simple', ?) AS search(query)
+			WHERE services.business_id = ? AND services.deleted_at IS NULL AND services.search_vector @@ search.query
+			ORDER BY search_rank DESC, services.name
+			LIMIT ?`, tsQuery, query.BusinessID, query.Limit).
+			Scan(&hits).Error
+		if err != nil {
+			return nil, err
+		}
+		for i := range hits {
+			results = append(results, &domain.SearchResult{Kind: domain.SearchResultService, Service: &hits[i].Service, Rank: hits[i].SearchRank})
+		}
+	}
+
+	domain.SortSearchResults(results)
+	if len(results) > query.Limit {
+		results = results[:query.Limit]
+	}
+	return results, nil
+}
+
+// prefixTSQuery returns a tsquery matching documents with words starting with every term. Terms
+// are letters and digits only, so they need no quoting.
+func prefixTSQuery(terms []string) string {
+	prefixes := make([]string, len(terms))
+	for i, term := range terms {
+		prefixes[i] = term + ":*"
+	}
+	return strings.Join(prefixes, " & ")
+}
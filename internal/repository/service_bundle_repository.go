@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// serviceBundleRepositoryImpl implements the ServiceBundleRepository interface
+type serviceBundleRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.ServiceBundle]
+}
+
+// NewServiceBundleRepository creates a new service bundle repository, scoped to the tenant in the
+// context
+func NewServiceBundleRepository(db *gorm.DB) domain.ServiceBundleRepository {
+	return &serviceBundleRepositoryImpl{
+		BaseRepositoryImpl: newBaseRepository[domain.ServiceBundle](db, WithTenantScope()),
+	}
+}
+
+// FindByBusinessID finds the bundles of a business, optionally only the bookable ones
+func (r *serviceBundleRepositoryImpl) FindByBusinessID(ctx context.Context, businessID string, activeOnly bool) ([]*domain.ServiceBundle, error) {
+	var bundles []*domain.ServiceBundle
+	query := r.query(ctx).Where("business_id = ?", businessID)
+	if activeOnly {
+		query = query.Where("is_active = TRUE")
+	}
+	err := query.Order("name ASC").Find(&bundles).Error
+	return bundles, err
+}
+
+// ExistsByNameAndBusiness checks whether the business has another bundle with the name
+func (r *serviceBundleRepositoryImpl) ExistsByNameAndBusiness(ctx context.Context, name, businessID string, excludeID *string) (bool, error) {
+	var count int64
+	query := r.query(ctx).
+		Model(&domain.ServiceBundle{}).
+		Where("business_id = ? AND LOWER(name) = LOWER(?)", businessID, name)
+	if excludeID != nil {
+		query = query.Where("id <> ?", *excludeID)
+	}
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
+// FindServices finds the business's services with the given IDs, keyed by ID
+func (r *serviceBundleRepositoryImpl) FindServices(ctx context.Context, businessID string, serviceIDs []string) (map[string]*domain.Service, error) {
+	services := map[string]*domain.Service{}
+	if len(serviceIDs) == 0 {
+		return services, nil
+	}
+
+	var found []*domain.Service
+	err := r.query(ctx).
+		Where("business_id = ? AND id IN ?", businessID, serviceIDs).
+		Find(&found).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, service := range found {
+		services[service.ID] = service
+	}
+	return services, nil
+}
+
+// WithTx returns a new repository instance with the given transaction
+func (r *serviceBundleRepositoryImpl) WithTx(tx *gorm.DB) domain.BaseRepository[domain.ServiceBundle] {
+	return &serviceBundleRepositoryImpl{BaseRepositoryImpl: r.withDB(tx)}
+}
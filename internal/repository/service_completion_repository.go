@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// serviceCompletionRepositoryImpl implements the ServiceCompletionRepository interface
+type serviceCompletionRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.ServiceCompletion]
+}
+
+// NewServiceCompletionRepository creates a new service completion repository
+func NewServiceCompletionRepository(db *gorm.DB) domain.ServiceCompletionRepository {
+	return &serviceCompletionRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.ServiceCompletion]{db: db},
+	}
+}
+
+// FindByAppointmentID finds the completion record for an appointment
+func (r *serviceCompletionRepositoryImpl) FindByAppointmentID(ctx context.Context, appointmentID string) (*domain.ServiceCompletion, error) {
+	var completion domain.ServiceCompletion
+	err := conn(ctx, r.db).
+		Where("appointment_id = ? AND deleted_at IS NULL", appointmentID).
+		Order("created_at DESC").
+		First(&completion).Error
+	if err != nil {
+		return nil, err
+	}
+	return &completion, nil
+}
+
+// WithTx returns a new repository instance with the given transaction
+func (r *serviceCompletionRepositoryImpl) WithTx(tx *gorm.DB) domain.BaseRepository[domain.ServiceCompletion] {
+	return &BaseRepositoryImpl[domain.ServiceCompletion]{db: tx}
+}
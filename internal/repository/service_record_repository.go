@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// serviceRecordTemplateRepositoryImpl implements the ServiceRecordTemplateRepository interface
+type serviceRecordTemplateRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.ServiceRecordTemplate]
+}
+
+// NewServiceRecordTemplateRepository creates a new service record template repository, scoped to
+// the tenant in the context
+func NewServiceRecordTemplateRepository(db *gorm.DB) domain.ServiceRecordTemplateRepository {
+	return &serviceRecordTemplateRepositoryImpl{
+		BaseRepositoryImpl: newBaseRepository[domain.ServiceRecordTemplate](db, WithTenantScope()),
+	}
+}
+
+// FindByBusinessID finds all templates for a business
+func (r *serviceRecordTemplateRepositoryImpl) FindByBusinessID(ctx context.Context, businessID string) ([]*domain.ServiceRecordTemplate, error) {
+	var templates []*domain.ServiceRecordTemplate
+	err := r.query(ctx).
+		Where("business_id = ? AND deleted_at IS NULL", businessID).
+		Order("name ASC").
+		Find(&templates).Error
+	return templates, err
+}
+
+// FindActiveByCategory finds the active template for a service category
+func (r *serviceRecordTemplateRepositoryImpl) FindActiveByCategory(ctx context.Context, businessID, categoryID string) (*domain.ServiceRecordTemplate, error) {
+	var template domain.ServiceRecordTemplate
+	err := r.query(ctx).
+		Where("business_id = ? AND category_id = ? AND is_active = true AND deleted_at IS NULL", businessID, categoryID).
+		Order("updated_at DESC").
+		First(&template).Error
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// WithTx returns a new repository instance with the given transaction
+func (r *serviceRecordTemplateRepositoryImpl) WithTx(tx *gorm.DB) domain.BaseRepository[domain.ServiceRecordTemplate] {
+	return r.withDB(tx)
+}
+
+// serviceRecordRepositoryImpl implements the ServiceRecordRepository interface
+type serviceRecordRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.ServiceRecord]
+}
+
+// NewServiceRecordRepository creates a new service record repository, scoped to the tenant in the
+// context
+func NewServiceRecordRepository(db *gorm.DB) domain.ServiceRecordRepository {
+	return &serviceRecordRepositoryImpl{
+		BaseRepositoryImpl: newBaseRepository[domain.ServiceRecord](db, WithTenantScope()),
+	}
+}
+
+// FindByClientID finds a client's service records, most recent first
+func (r *serviceRecordRepositoryImpl) FindByClientID(ctx context.Context, clientID string, page, pageSize int) ([]*domain.ServiceRecord, int64, error) {
+	var records []*domain.ServiceRecord
+	var total int64
+
+	query := r.query(ctx).
+		Model(&domain.ServiceRecord{}).
+		Where("client_id = ? AND deleted_at IS NULL", clientID)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Order("recorded_at DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&records).Error
+
+	return records, total, err
+}
+
+// FindByAppointmentID finds the service records captured for an appointment
+func (r *serviceRecordRepositoryImpl) FindByAppointmentID(ctx context.Context, appointmentID string) ([]*domain.ServiceRecord, error) {
+	var records []*domain.ServiceRecord
+	err := r.query(ctx).
+		Where("appointment_id = ? AND deleted_at IS NULL", appointmentID).
+		Order("recorded_at ASC").
+		Find(&records).Error
+	return records, err
+}
+
+// FindLatestByClient finds the client's most recent non-draft record, optionally for a specific service
+func (r *serviceRecordRepositoryImpl) FindLatestByClient(ctx context.Context, clientID string, serviceID *string) (*domain.ServiceRecord, error) {
+	var record domain.ServiceRecord
+	query := r.query(ctx).
+		Where("client_id = ? AND is_draft = false AND deleted_at IS NULL", clientID)
+
+	if serviceID != nil {
+		query = query.Where("service_id = ?", *serviceID)
+	}
+
+	err := query.Order("recorded_at DESC").First(&record).Error
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// FindLatestByClients finds the latest record of each of the clients that has one
+func (r *serviceRecordRepositoryImpl) FindLatestByClients(ctx context.Context, clientIDs []string) ([]*domain.ServiceRecord, error) {
+	var records []*domain.ServiceRecord
+	if len(clientIDs) == 0 {
+		return records, nil
+	}
+	err := r.query(ctx).
+		Select("DISTINCT ON (client_id) *").
+		Where("client_id IN ? AND is_draft = false AND deleted_at IS NULL", clientIDs).
+		Order("client_id, recorded_at DESC").
+		Find(&records).Error
+	return records, err
+}
+
+// Search searches a business's service record history
+func (r *serviceRecordRepositoryImpl) Search(ctx context.Context, businessID string, criteria domain.ServiceRecordSearch, page, pageSize int) ([]*domain.ServiceRecord, int64, error) {
+	var records []*domain.ServiceRecord
+	var total int64
+
+	query := r.query(ctx).
+		Model(&domain.ServiceRecord{}).
+		Where("business_id = ? AND deleted_at IS NULL", businessID)
+
+	if criteria.ClientID != nil {
+		query = query.Where("client_id = ?", *criteria.ClientID)
+	}
+	if criteria.ServiceID != nil {
+		query = query.Where("service_id = ?", *criteria.ServiceID)
+	}
+	if criteria.StaffID != nil {
+		query = query.Where("staff_id = ?", *criteria.StaffID)
+	}
+	if criteria.DateRange != nil {
+		query = query.Where("recorded_at >= ? AND recorded_at < ?", criteria.DateRange.Start, criteria.DateRange.End)
+	}
+	if !criteria.IncludeDrafts {
+		query = query.Where("is_draft = false")
+	}
+	if criteria.Query != "" {
+		searchPattern := "%" + criteria.Query + "%"
+		query = query.Where(
+			"(notes ILIKE ? OR field_values::text ILIKE ? OR products_used::text ILIKE ?)",
+			searchPattern, searchPattern, searchPattern,
+		)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Order("recorded_at DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&records).Error
+
+	return records, total, err
+}
+
+// WithTx returns a new repository instance with the given transaction
+func (r *serviceRecordRepositoryImpl) WithTx(tx *gorm.DB) domain.BaseRepository[domain.ServiceRecord] {
+	return r.withDB(tx)
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// staffBookingPageRepositoryImpl implements the StaffBookingPageRepository interface
+type staffBookingPageRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.StaffBookingPage]
+}
+
+// NewStaffBookingPageRepository creates a new staff booking page repository, scoped to the tenant
+// in the context
+func NewStaffBookingPageRepository(db *gorm.DB) domain.StaffBookingPageRepository {
+	return &staffBookingPageRepositoryImpl{
+		BaseRepositoryImpl: newBaseRepository[domain.StaffBookingPage](db, WithTenantScope()),
+	}
+}
+
+// FindBySlug finds a booking page by its slug
+func (r *staffBookingPageRepositoryImpl) FindBySlug(ctx context.Context, slug string) (*domain.StaffBookingPage, error) {
+	var page domain.StaffBookingPage
+	if err := r.query(ctx).Where("slug = ?", slug).First(&page).Error; err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// FindByStaffID finds the booking page of a staff member
+func (r *staffBookingPageRepositoryImpl) FindByStaffID(ctx context.Context, staffID string) (*domain.StaffBookingPage, error) {
+	var page domain.StaffBookingPage
+	if err := r.query(ctx).Where("staff_id = ?", staffID).First(&page).Error; err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// SlugExists checks whether another page has the slug. Deleted pages keep their slug, which
+// the unique index still covers. Slugs are unique across businesses, so every business's pages
+// are checked.
+func (r *staffBookingPageRepositoryImpl) SlugExists(ctx context.Context, slug string, excludeID *string) (bool, error) {
+	var count int64
+	query := conn(ctx, r.db).
+		Unscoped().
+		Model(&domain.StaffBookingPage{}).
+		Where("slug = ?", slug)
+	if excludeID != nil {
+		query = query.Where("id <> ?", *excludeID)
+	}
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
+// WithTx returns a new repository instance with the given transaction
+func (r *staffBookingPageRepositoryImpl) WithTx(tx *gorm.DB) domain.BaseRepository[domain.StaffBookingPage] {
+	return r.withDB(tx)
+}
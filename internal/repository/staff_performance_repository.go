@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// performanceAppointmentsSQL finds the appointments of a business starting in a range, with what
+// was charged on their completion, their published rating and the first completed visit of their client
+const performanceAppointmentsSQL = `
+	SELECT a.staff_id, a.client_id, a.status, a.start_time,
+		COALESCE((
+			SELECT SUM(sc.price_charged) FROM service_completions sc
+			WHERE sc.appointment_id = a.id AND sc.deleted_at IS NULL
+		), 0) AS revenue,
+		r.rating,
+		(
+			SELECT MIN(f.start_time) FROM appointments f
+			WHERE f.business_id = a.business_id AND f.client_id = a.client_id
+				AND f.status = 'completed' AND f.deleted_at IS NULL
+		) AS first_visit
+	FROM appointments a
+	LEFT JOIN reviews r ON r.appointment_id = a.id AND r.status = 'published' AND r.deleted_at IS NULL
+	WHERE a.business_id = @business AND a.deleted_at IS NULL
+		AND a.start_time >= @start AND a.start_time < @end
+	ORDER BY a.start_time ASC, a.id ASC`
+
+// performanceColumns are the columns an aggregation overwrites when a record already exists
+var performanceColumns = []string{
+	"end_date", "total_appointments", "completed_appointments", "canceled_appointments", "no_show_appointments",
+	"total_revenue", "average_rating", "client_retention_rate", "new_clients", "return_clients",
+	"updated_at", "updated_by", "deleted_at", "deleted_by",
+}
+
+// staffPerformanceRepositoryImpl implements the StaffPerformanceRepository interface
+type staffPerformanceRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.StaffPerformance]
+}
+
+// NewStaffPerformanceRepository creates a new staff performance repository, scoped to the
+// tenant in the context
+func NewStaffPerformanceRepository(db *gorm.DB) domain.StaffPerformanceRepository {
+	return &staffPerformanceRepositoryImpl{
+		BaseRepositoryImpl: newBaseRepository[domain.StaffPerformance](db, WithTenantScope()),
+	}
+}
+
+// FindBusinessesWithAppointments finds the businesses with appointments starting in the range
+func (r *staffPerformanceRepositoryImpl) FindBusinessesWithAppointments(ctx context.Context, start, end time.Time) ([]string, error) {
+	if err := r.checkUnscoped(ctx); err != nil {
+		return nil, err
+	}
+	var businessIDs []string
+	err := conn(ctx, r.db).
+		Model(&domain.Appointment{}).
+		Distinct("business_id").
+		Where("start_time >= ? AND start_time < ?", start, end).
+		Pluck("business_id", &businessIDs).Error
+	return businessIDs, err
+}
+
+// FindPerformanceAppointments finds the appointments of a business starting in the range, with
+// what was charged for them, their rating and the first visit of their client
+func (r *staffPerformanceRepositoryImpl) FindPerformanceAppointments(ctx context.Context, businessID string, start, end time.Time) ([]*domain.PerformanceAppointment, error) {
+	if err := r.checkBusiness(ctx, businessID); err != nil {
+		return nil, err
+	}
+	appointments := []*domain.PerformanceAppointment{}
+	err := conn(ctx, r.db).Raw(performanceAppointmentsSQL, map[string]any{
+		"business": businessID,
+		"start":    start,
+		"end":      end,
+	}).Scan(&appointments).Error
+	return appointments, err
+}
+
+// ReplacePeriod stores the records of a period of a business in a single transaction. Records
+// of the same staff member and period start are updated in place, restoring them if deleted, and
+// those of staff members left out are removed for good.
+func (r *staffPerformanceRepositoryImpl) ReplacePeriod(ctx context.Context, businessID string, period domain.PerformancePeriod, start time.Time, records []*domain.StaffPerformance) error {
+	if err := r.checkBusiness(ctx, businessID); err != nil {
+		return err
+	}
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		staffIDs := make([]string, len(records))
+		for i, record := range records {
+			staffIDs[i] = record.StaffID
+		}
+		stale := tx.Unscoped().Where("business_id = ? AND period = ? AND start_date = ?", businessID, period, start)
+		if len(staffIDs) > 0 {
+			stale = stale.Where("staff_id NOT IN ?", staffIDs)
+		}
+		if err := stale.Delete(&domain.StaffPerformance{}).Error; err != nil {
+			return err
+		}
+		if len(records) == 0 {
+			return nil
+		}
+
+		return tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "staff_id"}, {Name: "period"}, {Name: "start_date"}},
+			DoUpdates: append(clause.AssignmentColumns(performanceColumns),
+				clause.Assignment{Column: clause.Column{Name: "version"}, Value: gorm.Expr("staff_performance.version + 1")}),
+		}).Create(&records).Error
+	})
+}
+
+// FindInRange finds the records of a business for periods of the kind starting in the range,
+// ordered by period start and staff ID
+func (r *staffPerformanceRepositoryImpl) FindInRange(ctx context.Context, businessID string, period domain.PerformancePeriod, staffID *string, start, end time.Time) ([]*domain.StaffPerformance, error) {
+	records := []*domain.StaffPerformance{}
+	query := r.query(ctx).
+		Where("business_id = ? AND period = ? AND start_date >= ? AND start_date < ?", businessID, period, start, end)
+	if staffID != nil {
+		query = query.Where("staff_id = ?", *staffID)
+	}
+	err := query.Order("start_date ASC, staff_id ASC").Find(&records).Error
+	return records, err
+}
+
+// WithTx returns a new repository instance with the given transaction
+func (r *staffPerformanceRepositoryImpl) WithTx(tx *gorm.DB) domain.BaseRepository[domain.StaffPerformance] {
+	return r.withDB(tx)
+}
@@ -12,26 +12,27 @@ type staffRepositoryImpl struct {
 	*BaseRepositoryImpl[domain.Staff]
 }
 
-// NewStaffRepository creates a new staff repository
+// NewStaffRepository creates a new staff repository, scoped to the tenant in the context
 func NewStaffRepository(db *gorm.DB) domain.StaffRepository {
 	return &staffRepositoryImpl{
-		BaseRepositoryImpl: &BaseRepositoryImpl[domain.Staff]{db: db},
+		BaseRepositoryImpl: newBaseRepository[domain.Staff](db, WithTenantScope()),
 	}
 }
 
 // FindByBusinessID finds all staff members for a business
 func (r *staffRepositoryImpl) FindByBusinessID(ctx context.Context, businessID string) ([]*domain.Staff, error) {
 	var staff []*domain.Staff
-	err := r.db.WithContext(ctx).
+	err := r.query(ctx).
 		Where("business_id = ? AND deleted_at IS NULL", businessID).
 		Find(&staff).Error
 	return staff, err
 }
 
-// FindByUserID finds all staff positions for a user
+// FindByUserID finds all staff positions for a user. The positions are the user's own, so they
+// are found in every business rather than the tenant's only
 func (r *staffRepositoryImpl) FindByUserID(ctx context.Context, userID string) ([]*domain.Staff, error) {
 	var staff []*domain.Staff
-	err := r.db.WithContext(ctx).
+	err := conn(ctx, r.db).
 		Where("user_id = ? AND deleted_at IS NULL", userID).
 		Find(&staff).Error
 	return staff, err
@@ -40,7 +41,7 @@ func (r *staffRepositoryImpl) FindByUserID(ctx context.Context, userID string) (
 // FindByBusinessAndUser finds a specific staff record
 func (r *staffRepositoryImpl) FindByBusinessAndUser(ctx context.Context, businessID, userID string) (*domain.Staff, error) {
 	var staff domain.Staff
-	err := r.db.WithContext(ctx).
+	err := r.query(ctx).
 		Where("business_id = ? AND user_id = ? AND deleted_at IS NULL", businessID, userID).
 		First(&staff).Error
 	if err != nil {
@@ -52,7 +53,7 @@ func (r *staffRepositoryImpl) FindByBusinessAndUser(ctx context.Context, busines
 // FindActiveByBusinessID finds all active staff members for a business
 func (r *staffRepositoryImpl) FindActiveByBusinessID(ctx context.Context, businessID string) ([]*domain.Staff, error) {
 	var staff []*domain.Staff
-	err := r.db.WithContext(ctx).
+	err := r.query(ctx).
 		Where("business_id = ? AND is_active = true AND deleted_at IS NULL", businessID).
 		Find(&staff).Error
 	return staff, err
@@ -61,7 +62,7 @@ func (r *staffRepositoryImpl) FindActiveByBusinessID(ctx context.Context, busine
 // FindByRole finds staff members by role in a business
 func (r *staffRepositoryImpl) FindByRole(ctx context.Context, businessID string, role domain.BusinessRole) ([]*domain.Staff, error) {
 	var staff []*domain.Staff
-	err := r.db.WithContext(ctx).
+	err := r.query(ctx).
 		Where("business_id = ? AND role = ? AND deleted_at IS NULL", businessID, role).
 		Find(&staff).Error
 	return staff, err
@@ -69,7 +70,7 @@ func (r *staffRepositoryImpl) FindByRole(ctx context.Context, businessID string,
 
 // UpdateRole updates the role of a staff member
 func (r *staffRepositoryImpl) UpdateRole(ctx context.Context, businessID, userID string, role domain.BusinessRole) error {
-	return r.db.WithContext(ctx).
+	return r.query(ctx).
 		Model(&domain.Staff{}).
 		Where("business_id = ? AND user_id = ?", businessID, userID).
 		Update("role", role).Error
@@ -77,7 +78,7 @@ func (r *staffRepositoryImpl) UpdateRole(ctx context.Context, businessID, userID
 
 // DeactivateStaff deactivates a staff member
 func (r *staffRepositoryImpl) DeactivateStaff(ctx context.Context, businessID, userID string) error {
-	return r.db.WithContext(ctx).
+	return r.query(ctx).
 		Model(&domain.Staff{}).
 		Where("business_id = ? AND user_id = ?", businessID, userID).
 		Update("is_active", false).Error
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// staffShiftRepositoryImpl implements the StaffShiftRepository interface
+type staffShiftRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.StaffShift]
+}
+
+// NewStaffShiftRepository creates a new staff shift repository, scoped to the tenant in the
+// context
+func NewStaffShiftRepository(db *gorm.DB) domain.StaffShiftRepository {
+	return &staffShiftRepositoryImpl{
+		BaseRepositoryImpl: newBaseRepository[domain.StaffShift](db, WithTenantScope()),
+	}
+}
+
+// FindByStaffIDs finds the shifts of the staff members, in the order they were created
+func (r *staffShiftRepositoryImpl) FindByStaffIDs(ctx context.Context, staffIDs []string) ([]*domain.StaffShift, error) {
+	shifts := []*domain.StaffShift{}
+	if len(staffIDs) == 0 {
+		return shifts, nil
+	}
+	err := r.query(ctx).Where("staff_id IN ?", staffIDs).Order("created_at ASC, id ASC").Find(&shifts).Error
+	return shifts, err
+}
+
+// WithTx returns a new repository instance with the given transaction
+func (r *staffShiftRepositoryImpl) WithTx(tx *gorm.DB) domain.BaseRepository[domain.StaffShift] {
+	return r.withDB(tx)
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// useSMSCreditSQL counts a text message against the month's SMS credits, unless the limit has
+// been reached
+const useSMSCreditSQL = `
+	INSERT INTO sms_usage_monthly (business_id, month, used, updated_at)
+	VALUES (@business, @month, 1, NOW())
+	ON CONFLICT (business_id, month) DO UPDATE SET used = sms_usage_monthly.used + 1, updated_at = NOW()
+	WHERE @limit = -1 OR sms_usage_monthly.used < @limit`
+
+// subscriptionRepositoryImpl implements the SubscriptionRepository interface
+type subscriptionRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.BusinessSubscription]
+}
+
+// NewSubscriptionRepository creates a new subscription repository, scoped to the tenant in the
+// context
+func NewSubscriptionRepository(db *gorm.DB) domain.SubscriptionRepository {
+	return &subscriptionRepositoryImpl{
+		BaseRepositoryImpl: newBaseRepository[domain.BusinessSubscription](db, WithTenantScope()),
+	}
+}
+
+// FindByBusiness finds the subscription of a business
+func (r *subscriptionRepositoryImpl) FindByBusiness(ctx context.Context, businessID string) (*domain.BusinessSubscription, error) {
+	var subscription domain.BusinessSubscription
+	err := r.query(ctx).
+		Where("business_id = ? AND deleted_at IS NULL", businessID).
+		First(&subscription).Error
+	if err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+// FindByStripeSubscription finds the subscription mirroring a Stripe subscription
+func (r *subscriptionRepositoryImpl) FindByStripeSubscription(ctx context.Context, stripeSubscriptionID string) (*domain.BusinessSubscription, error) {
+	var subscription domain.BusinessSubscription
+	err := r.query(ctx).
+		Where("stripe_subscription_id = ? AND deleted_at IS NULL", stripeSubscriptionID).
+		First(&subscription).Error
+	if err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+// CountUsage counts how much of an entitlement a business uses at a time
+func (r *subscriptionRepositoryImpl) CountUsage(ctx context.Context, businessID string, entitlement domain.Entitlement, now time.Time) (int, error) {
+	if err := r.checkBusiness(ctx, businessID); err != nil {
+		return 0, err
+	}
+	db := conn(ctx, r.db)
+	var count int64
+	var err error
+	switch entitlement {
+	case domain.EntitlementStaff:
+		err = db.Table("staff").Where("business_id = ? AND is_active AND deleted_at IS NULL", businessID).Count(&count).Error
+	case domain.EntitlementLocations:
+		err = db.Table("business_locations").Where("business_id = ? AND is_active AND deleted_at IS NULL", businessID).Count(&count).Error
+	case domain.EntitlementCampaigns:
+		err = db.Table("campaigns").
+			Where("business_id = ? AND is_active AND end_date >= ? AND deleted_at IS NULL", businessID, now).
+			Count(&count).Error
+	case domain.EntitlementSMSCredits:
+		var used []int64
+		err = db.Table("sms_usage_monthly").
+			Where("business_id = ? AND month = ? AND deleted_at IS NULL", businessID, domain.UsageMonth(now).Format(time.DateOnly)).
+			Pluck("used", &used).Error
+		if len(used) > 0 {
+			count = used[0]
+		}
+	default:
+		return 0, fmt.Errorf("unknown entitlement %q", entitlement)
+	}
+	return int(count), err
+}
+
+// UseSMSCredit takes a credit off the business's SMS credits of the month, reporting false
+// when the limit has been reached
+func (r *subscriptionRepositoryImpl) UseSMSCredit(ctx context.Context, businessID string, now time.Time, limit int) (bool, error) {
+	if limit == 0 {
+		return false, nil
+	}
+	if err := r.checkBusiness(ctx, businessID); err != nil {
+		return false, err
+	}
+	result := conn(ctx, r.db).Exec(useSMSCreditSQL, map[string]any{
+		"business": businessID,
+		"month":    domain.UsageMonth(now).Format(time.DateOnly),
+		"limit":    limit,
+	})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Sync saves the subscription and gives the business its effective tier and trial end, in a
+// single transaction
+func (r *subscriptionRepositoryImpl) Sync(ctx context.Context, subscription *domain.BusinessSubscription) error {
+	if err := r.claim(ctx, subscription); err != nil {
+		return err
+	}
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := r.withDB(tx).save(tx, subscription); err != nil {
+			return err
+		}
+		return tx.Table("businesses").Where("id = ? AND deleted_at IS NULL", subscription.BusinessID).Updates(map[string]any{
+			"subscription_tier": subscription.EffectiveTier(),
+			"trial_ends_at":     subscription.TrialEndsAt,
+			"updated_at":        time.Now(),
+		}).Error
+	})
+}
+
+// WithTx returns a new repository instance with the given transaction
+func (r *subscriptionRepositoryImpl) WithTx(tx *gorm.DB) domain.BaseRepository[domain.BusinessSubscription] {
+	return r.withDB(tx)
+}
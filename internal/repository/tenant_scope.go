@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// BaseRepositoryOption configures optional behaviour of a base repository
+type BaseRepositoryOption func(*baseRepositoryConfig)
+
+type baseRepositoryConfig struct {
+	tenantScoped bool
+}
+
+// WithTenantScope scopes the repository to the tenant carried in the context. Reads only see
+// rows of the tenant's business, so other businesses' rows look missing, and writes of rows
+// belonging to another business fail with domain.ErrCrossTenant. Signed-in users acting for no
+// business see no rows and cannot write any. Calls without a tenant, such as background work,
+// are not scoped. The entity must have a BusinessID field.
+func WithTenantScope() BaseRepositoryOption {
+	return func(c *baseRepositoryConfig) {
+		c.tenantScoped = true
+	}
+}
+
+// businessIDField returns the index of the entity's BusinessID field
+func businessIDField[T any]() []int {
+	entityType := reflect.TypeFor[T]()
+	field, ok := entityType.FieldByName("BusinessID")
+	if !ok || field.Type.Kind() != reflect.String {
+		panic(fmt.Sprintf("repository: %s has no BusinessID to scope by tenant", entityType.Name()))
+	}
+	return field.Index
+}
+
+// query starts a query, filtered to the tenant's business when the repository is scoped
+func (r *BaseRepositoryImpl[T]) query(ctx context.Context) *gorm.DB {
+	return r.scoped(ctx, conn(ctx, r.db))
+}
+
+// scoped restricts a query, such as one in a transaction, to the tenant's rows
+func (r *BaseRepositoryImpl[T]) scoped(ctx context.Context, query *gorm.DB) *gorm.DB {
+	return r.scopedBy(ctx, query, "business_id")
+}
+
+// scopedBy restricts a query to the tenant's rows by a business column, qualified for queries
+// joining other tables
+func (r *BaseRepositoryImpl[T]) scopedBy(ctx context.Context, query *gorm.DB, column string) *gorm.DB {
+	tenant, ok := r.tenant(ctx)
+	switch {
+	case !ok:
+		return query
+	case tenant.BusinessID == "":
+		return query.Where("FALSE")
+	}
+	return query.Where(column+" = ?", tenant.BusinessID)
+}
+
+// scopedThrough restricts a query on rows without a business of their own to those whose column
+// references a row of the parent model belonging to the tenant
+func (r *BaseRepositoryImpl[T]) scopedThrough(ctx context.Context, query *gorm.DB, column string, parent any) *gorm.DB {
+	if _, ok := r.tenant(ctx); !ok {
+		return query
+	}
+	return query.Where(column+" IN (?)", r.scoped(ctx, conn(ctx, r.db).Model(parent)).Select("id"))
+}
+
+// checkBusiness checks that a business named by a query belongs to the tenant, for queries that
+// cannot be scoped with a condition, such as raw SQL
+func (r *BaseRepositoryImpl[T]) checkBusiness(ctx context.Context, businessID string) error {
+	if tenant, ok := r.tenant(ctx); ok && !tenant.Owns(businessID) {
+		return domain.ErrCrossTenant
+	}
+	return nil
+}
+
+// checkUnscoped refuses calls acting for a tenant, for the queries of background work that
+// span every business
+func (r *BaseRepositoryImpl[T]) checkUnscoped(ctx context.Context) error {
+	if _, ok := r.tenant(ctx); ok {
+		return domain.ErrCrossTenant
+	}
+	return nil
+}
+
+// tenant returns the tenant the call is scoped to
+func (r *BaseRepositoryImpl[T]) tenant(ctx context.Context) (domain.TenantContext, bool) {
+	if r.tenantField == nil {
+		return domain.TenantContext{}, false
+	}
+	return domain.TenantFromContext(ctx)
+}
+
+// claim checks that an entity about to be written belongs to the tenant, assigning new
+// entities without a business to it
+func (r *BaseRepositoryImpl[T]) claim(ctx context.Context, entity *T) error {
+	tenant, ok := r.tenant(ctx)
+	if !ok {
+		return nil
+	}
+	businessID := reflect.ValueOf(entity).Elem().FieldByIndex(r.tenantField)
+	if businessID.String() == "" {
+		businessID.SetString(tenant.BusinessID)
+	}
+	if !tenant.Owns(businessID.String()) {
+		return domain.ErrCrossTenant
+	}
+	return nil
+}
+
+// ensureOwned checks that a stored row, if there is one, belongs to the tenant
+func (r *BaseRepositoryImpl[T]) ensureOwned(ctx context.Context, id string) error {
+	tenant, ok := r.tenant(ctx)
+	if !ok {
+		return nil
+	}
+	var owners []string
+	err := conn(ctx, r.db).Model(new(T)).Where("id = ?", id).Pluck("business_id", &owners).Error
+	if err != nil {
+		return err
+	}
+	for _, owner := range owners {
+		if !tenant.Owns(owner) {
+			return domain.ErrCrossTenant
+		}
+	}
+	return nil
+}
+
+// ensureParentOwned checks that a row of another model, such as the parent of rows without a
+// business of their own, belongs to the tenant. Missing rows count as another tenant's.
+func (r *BaseRepositoryImpl[T]) ensureParentOwned(ctx context.Context, parent any, id string) error {
+	if _, ok := r.tenant(ctx); !ok {
+		return nil
+	}
+	var count int64
+	if err := r.query(ctx).Model(parent).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return domain.ErrCrossTenant
+	}
+	return nil
+}
+
+// ensureAllOwned checks that the stored rows with the IDs, if there are any, belong to the tenant
+func (r *BaseRepositoryImpl[T]) ensureAllOwned(ctx context.Context, ids []string) error {
+	tenant, ok := r.tenant(ctx)
+	if !ok {
+		return nil
+	}
+	var owners []string
+	err := conn(ctx, r.db).Model(new(T)).Where("id IN ?", ids).Distinct().Pluck("business_id", &owners).Error
+	if err != nil {
+		return err
+	}
+	for _, owner := range owners {
+		if !tenant.Owns(owner) {
+			return domain.ErrCrossTenant
+		}
+	}
+	return nil
+}
+
+// entityID returns the ID of an entity
+func entityID[T any](entity *T) string {
+	return reflect.ValueOf(entity).Elem().FieldByName("ID").String()
+}
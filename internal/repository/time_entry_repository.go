@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// timeEntryRepositoryImpl implements the TimeEntryRepository interface
+type timeEntryRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.TimeEntry]
+}
+
+// NewTimeEntryRepository creates a new time entry repository, scoped to the tenant in the
+// context
+func NewTimeEntryRepository(db *gorm.DB) domain.TimeEntryRepository {
+	return &timeEntryRepositoryImpl{
+		BaseRepositoryImpl: newBaseRepository[domain.TimeEntry](db, WithTenantScope()),
+	}
+}
+
+// FindOpenByStaffID finds the entry the staff member is clocked in on
+func (r *timeEntryRepositoryImpl) FindOpenByStaffID(ctx context.Context, staffID string) (*domain.TimeEntry, error) {
+	var entry domain.TimeEntry
+	if err := r.query(ctx).Where("staff_id = ? AND clock_out IS NULL", staffID).First(&entry).Error; err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// FindInRange finds the entries of a business overlapping the range, ordered by clock-in time
+func (r *timeEntryRepositoryImpl) FindInRange(ctx context.Context, businessID string, staffID *string, start, end time.Time) ([]*domain.TimeEntry, error) {
+	entries := []*domain.TimeEntry{}
+	query := r.query(ctx).
+		Where("business_id = ? AND clock_in < ? AND (clock_out IS NULL OR clock_out > ?)", businessID, end, start)
+	if staffID != nil {
+		query = query.Where("staff_id = ?", *staffID)
+	}
+	err := query.Order("clock_in ASC, id ASC").Find(&entries).Error
+	return entries, err
+}
+
+// WithTx returns a new repository instance with the given transaction
+func (r *timeEntryRepositoryImpl) WithTx(tx *gorm.DB) domain.BaseRepository[domain.TimeEntry] {
+	return r.withDB(tx)
+}
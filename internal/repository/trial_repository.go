@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// notBilledByStripeSQL excludes businesses whose Stripe subscription gives them their tier;
+// Stripe ends their trials by charging them
+const notBilledByStripeSQL = `NOT EXISTS (
+	SELECT 1 FROM business_subscriptions s
+	WHERE s.business_id = businesses.id AND s.status IN ('trialing', 'active', 'past_due') AND s.deleted_at IS NULL)`
+
+// trialRepositoryImpl implements the TrialRepository interface
+type trialRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewTrialRepository creates a new trial repository
+func NewTrialRepository(db *gorm.DB) domain.TrialRepository {
+	return &trialRepositoryImpl{db: db}
+}
+
+// FindEnding finds the businesses on an in-app trial whose trial ends before a time or has
+// ended without their being restricted yet
+func (r *trialRepositoryImpl) FindEnding(ctx context.Context, before time.Time) ([]*domain.Business, error) {
+	var businesses []*domain.Business
+	err := conn(ctx, r.db).
+		Where("trial_ends_at IS NOT NULL AND trial_ends_at < ? AND trial_expired_at IS NULL", before).
+		Where("subscription_tier <> ? AND deleted_at IS NULL", domain.TierFree).
+		Where(notBilledByStripeSQL).
+		Order("trial_ends_at").
+		Find(&businesses).Error
+	return businesses, err
+}
+
+// RecordNotice records a notice unless it was already recorded for the trial
+func (r *trialRepositoryImpl) RecordNotice(ctx context.Context, notice *domain.TrialNotice) (bool, error) {
+	result := conn(ctx, r.db).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(notice)
+	return result.RowsAffected > 0, result.Error
+}
+
+// Restrict moves a business whose trial and grace period are over to the free tier
+func (r *trialRepositoryImpl) Restrict(ctx context.Context, businessID string, trialEndsAt, now time.Time) (bool, error) {
+	result := conn(ctx, r.db).
+		Model(&domain.Business{}).
+		Where("id = ? AND trial_ends_at = ? AND trial_expired_at IS NULL AND deleted_at IS NULL", businessID, trialEndsAt).
+		Where(notBilledByStripeSQL).
+		Updates(map[string]any{
+			"subscription_tier": domain.TierFree,
+			"trial_expired_at":  now,
+			"updated_at":        now,
+		})
+	return result.RowsAffected > 0, result.Error
+}
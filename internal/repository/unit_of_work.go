@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// txKey is the context key of the transaction of the unit of work a call belongs to
+type txKey struct{}
+
+// unitOfWork runs units of work in database transactions
+type unitOfWork struct {
+	db *gorm.DB
+}
+
+// NewUnitOfWork creates a unit of work running in transactions of db
+func NewUnitOfWork(db *gorm.DB) domain.UnitOfWork {
+	return &unitOfWork{db: db}
+}
+
+// Do runs fn in a transaction, bound to the context fn is given. Within another unit of work
+// the transaction is a savepoint of the outer one.
+func (u *unitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return conn(ctx, u.db).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// conn returns the connection a repository call runs on: db when the repository was bound to a
+// transaction with WithTx, the transaction of the unit of work ctx belongs to otherwise, and db
+// outside a unit of work
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if _, bound := db.Statement.ConnPool.(gorm.TxCommitter); !bound {
+		if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+			db = tx
+		}
+	}
+	return db.WithContext(ctx)
+}
@@ -0,0 +1,67 @@
+//go:build integration
+// +build integration
+
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/repository"
+	"github.com/assimoes/beautix/utils/testdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnitOfWork_RollsBackEveryRepository(t *testing.T) {
+	ctx := context.Background()
+	db := testdb.NewTestDB(t)
+	owner, err := testdb.NewFixtureBuilder(db.GetDB()).CreateUser()
+	require.NoError(t, err)
+	businesses := repository.NewBusinessRepository(db.GetDB().DB)
+	settings := repository.NewBusinessSettingsRepository(db.GetDB().DB)
+	failure := errors.New("owner could not be added")
+
+	var business *domain.Business
+	err = repository.NewUnitOfWork(db.GetDB().DB).Do(ctx, func(ctx context.Context) error {
+		business = &domain.Business{UserID: owner.ID, Name: "Salon", Email: "salon@example.com", Currency: "EUR", TimeZone: "Europe/Lisbon", IsActive: true}
+		if err := businesses.Create(ctx, business); err != nil {
+			return err
+		}
+		if err := settings.Create(ctx, domain.NewBusinessSettings(business)); err != nil {
+			return err
+		}
+		return failure
+	})
+
+	assert.ErrorIs(t, err, failure)
+	exists, err := businesses.ExistsByID(ctx, business.ID)
+	require.NoError(t, err)
+	assert.False(t, exists)
+	_, err = settings.GetByBusinessID(ctx, business.ID)
+	assert.Error(t, err)
+}
+
+func TestUnitOfWork_CommitsEveryRepository(t *testing.T) {
+	ctx := context.Background()
+	db := testdb.NewTestDB(t)
+	owner, err := testdb.NewFixtureBuilder(db.GetDB()).CreateUser()
+	require.NoError(t, err)
+	businesses := repository.NewBusinessRepository(db.GetDB().DB)
+	settings := repository.NewBusinessSettingsRepository(db.GetDB().DB)
+
+	business := &domain.Business{UserID: owner.ID, Name: "Salon", Email: "salon@example.com", Currency: "EUR", TimeZone: "Europe/Lisbon", IsActive: true}
+	err = repository.NewUnitOfWork(db.GetDB().DB).Do(ctx, func(ctx context.Context) error {
+		if err := businesses.Create(ctx, business); err != nil {
+			return err
+		}
+		return settings.Create(ctx, domain.NewBusinessSettings(business))
+	})
+
+	require.NoError(t, err)
+	stored, err := settings.GetByBusinessID(ctx, business.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 24, stored.ConfirmationLeadHours)
+}
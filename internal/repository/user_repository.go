@@ -23,7 +23,7 @@ func NewUserRepository(db *gorm.DB) domain.UserRepository {
 // FindByEmail finds a user by email address
 func (r *userRepositoryImpl) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
 	var user domain.User
-	err := r.db.WithContext(ctx).
+	err := conn(ctx, r.db).
 		Where("email = ?", strings.ToLower(email)).
 		First(&user).Error
 	if err != nil {
@@ -35,7 +35,7 @@ func (r *userRepositoryImpl) FindByEmail(ctx context.Context, email string) (*do
 // FindByClerkID finds a user by Clerk ID
 func (r *userRepositoryImpl) FindByClerkID(ctx context.Context, clerkID string) (*domain.User, error) {
 	var user domain.User
-	err := r.db.WithContext(ctx).
+	err := conn(ctx, r.db).
 		Where("clerk_id = ?", clerkID).
 		First(&user).Error
 	if err != nil {
@@ -46,7 +46,7 @@ func (r *userRepositoryImpl) FindByClerkID(ctx context.Context, clerkID string)
 
 // UpdateClerkID updates the Clerk ID for a user
 func (r *userRepositoryImpl) UpdateClerkID(ctx context.Context, userID, clerkID string) error {
-	return r.db.WithContext(ctx).
+	return conn(ctx, r.db).
 		Model(&domain.User{}).
 		Where("id = ?", userID).
 		Update("clerk_id", clerkID).Error
@@ -56,7 +56,7 @@ func (r *userRepositoryImpl) UpdateClerkID(ctx context.Context, userID, clerkID
 // GetWithBusinesses retrieves a user with their businesses preloaded
 func (r *userRepositoryImpl) GetWithBusinesses(ctx context.Context, userID string) (*domain.User, error) {
 	var user domain.User
-	err := r.db.WithContext(ctx).
+	err := conn(ctx, r.db).
 		Preload("Businesses").
 		Where("id = ?", userID).
 		First(&user).Error
@@ -72,7 +72,7 @@ func (r *userRepositoryImpl) SearchUsers(ctx context.Context, query string, limi
 	
 	searchTerm := "%" + strings.ToLower(query) + "%"
 	
-	err := r.db.WithContext(ctx).
+	err := conn(ctx, r.db).
 		Where("(LOWER(first_name) LIKE ? OR LOWER(last_name) LIKE ? OR LOWER(email) LIKE ?) AND deleted_at IS NULL",
 			searchTerm, searchTerm, searchTerm).
 		Limit(limit).
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// userSessionRepositoryImpl implements the UserSessionRepository interface
+type userSessionRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.UserSession]
+}
+
+// NewUserSessionRepository creates a new user session repository
+func NewUserSessionRepository(db *gorm.DB) domain.UserSessionRepository {
+	return &userSessionRepositoryImpl{
+		BaseRepositoryImpl: &BaseRepositoryImpl[domain.UserSession]{db: db},
+	}
+}
+
+// FindByExternalID finds a session by the ID Clerk or the API key gave it
+func (r *userSessionRepositoryImpl) FindByExternalID(ctx context.Context, kind domain.SessionKind, externalID string) (*domain.UserSession, error) {
+	var session domain.UserSession
+	err := conn(ctx, r.db).
+		Where("kind = ? AND external_id = ?", kind, externalID).
+		First(&session).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// FindByUser finds the sessions of a user, most recently seen first, optionally with those
+// signed out
+func (r *userSessionRepositoryImpl) FindByUser(ctx context.Context, userID string, includeRevoked bool) ([]*domain.UserSession, error) {
+	query := conn(ctx, r.db).Where("user_id = ?", userID)
+	if !includeRevoked {
+		query = query.Where("revoked_at IS NULL")
+	}
+
+	var sessions []*domain.UserSession
+	err := query.Order("last_seen_at DESC, id").Find(&sessions).Error
+	return sessions, err
+}
+
+// RevokeAllByUser signs out every session of a user, returning how many were signed in
+func (r *userSessionRepositoryImpl) RevokeAllByUser(ctx context.Context, userID string, reason domain.SessionRevocationReason, at time.Time) (int64, error) {
+	result := conn(ctx, r.db).
+		Model(&domain.UserSession{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Updates(map[string]any{"revoked_at": at, "revoked_reason": reason, "updated_at": at, "updated_by": userID})
+	return result.RowsAffected, result.Error
+}
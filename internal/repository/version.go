@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"reflect"
+	"slices"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// save writes an entity unless it was changed since the version it carries was read, in
+// which case a domain.ConflictError is returned. A version of zero stands for a write that
+// does not know the version, which overwrites the stored entity whatever its version. The
+// entity's version is advanced by the write; entities that are not stored yet are created.
+// Columns limits the write to those fields, for models carrying fields their table lacks.
+func (r *BaseRepositoryImpl[T]) save(tx *gorm.DB, entity *T, columns ...string) error {
+	write := func() *gorm.DB {
+		if len(columns) > 0 {
+			return tx.Select(columns)
+		}
+		return tx
+	}
+	version := reflect.ValueOf(entity).Elem().FieldByName("Version")
+	if !version.IsValid() || version.Kind() != reflect.Int {
+		return write().Save(entity).Error
+	}
+	id := entityID(entity)
+
+	expected := version.Int()
+	if expected == 0 {
+		var current []int64
+		if err := tx.Model(new(T)).Where("id = ?", id).Pluck("version", &current).Error; err != nil {
+			return err
+		}
+		if len(current) == 0 {
+			return write().Save(entity).Error
+		}
+		expected = current[0]
+	}
+
+	version.SetInt(expected + 1)
+	updated := []string{"*"}
+	if len(columns) > 0 {
+		updated = append(slices.Clone(columns), "Version")
+	}
+	result := tx.Model(entity).Where("version = ?", expected).Select(updated).Updates(entity)
+	if result.Error == nil && result.RowsAffected == 1 {
+		return nil
+	}
+	version.SetInt(expected)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	var stored int64
+	if err := tx.Model(new(T)).Where("id = ?", id).Count(&stored).Error; err != nil {
+		return err
+	}
+	if stored == 0 {
+		return write().Save(entity).Error
+	}
+	entitySchema, err := r.schema()
+	if err != nil {
+		return err
+	}
+	return &domain.ConflictError{EntityType: entitySchema.Table, ID: id, Version: int(expected)}
+}
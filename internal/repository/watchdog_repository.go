@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// watchdogRepositoryImpl implements the WatchdogRepository interface
+type watchdogRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewWatchdogRepository creates a new watchdog repository
+func NewWatchdogRepository(db *gorm.DB) domain.WatchdogRepository {
+	return &watchdogRepositoryImpl{db: db}
+}
+
+// unpublishedEventsSQL selects the events the relay still has to publish
+const unpublishedEventsSQL = "published_at IS NULL AND abandoned_at IS NULL AND deleted_at IS NULL"
+
+// RequeueEventLeases makes the unpublished events leased past the cutoff due at now
+func (r *watchdogRepositoryImpl) RequeueEventLeases(ctx context.Context, cutoff, now time.Time) (int64, error) {
+	result := conn(ctx, r.db).Model(&domain.DomainEvent{}).
+		Where(unpublishedEventsSQL+" AND next_publish_at > ?", cutoff).
+		Update("next_publish_at", now)
+	return result.RowsAffected, result.Error
+}
+
+// FindEventBacklog counts the unpublished events that were due before a time
+func (r *watchdogRepositoryImpl) FindEventBacklog(ctx context.Context, dueBefore time.Time) (domain.StuckItems, error) {
+	return r.stuck(ctx, &domain.DomainEvent{}, "COALESCE(next_publish_at, occurred_at)",
+		unpublishedEventsSQL+" AND COALESCE(next_publish_at, occurred_at) < ?", dueBefore)
+}
+
+// RequeueNotifications makes the pending notifications scheduled past the cutoff due at now
+func (r *watchdogRepositoryImpl) RequeueNotifications(ctx context.Context, cutoff, now time.Time) (int64, error) {
+	result := conn(ctx, r.db).Model(&domain.QueuedNotification{}).
+		Where("sent_at IS NULL AND abandoned_at IS NULL AND next_attempt_at > ?", cutoff).
+		Update("next_attempt_at", now)
+	return result.RowsAffected, result.Error
+}
+
+// FindNotificationBacklog counts the pending notifications that were due before a time
+func (r *watchdogRepositoryImpl) FindNotificationBacklog(ctx context.Context, dueBefore time.Time) (domain.StuckItems, error) {
+	return r.stuck(ctx, &domain.QueuedNotification{}, "next_attempt_at",
+		"sent_at IS NULL AND abandoned_at IS NULL AND next_attempt_at < ?", dueBefore)
+}
+
+// RequeueWebhookDeliveries makes the pending deliveries scheduled past the cutoff due at now
+func (r *watchdogRepositoryImpl) RequeueWebhookDeliveries(ctx context.Context, cutoff, now time.Time) (int64, error) {
+	result := conn(ctx, r.db).Model(&domain.WebhookDelivery{}).
+		Where("status = ? AND next_attempt_at > ?", domain.WebhookDeliveryPending, cutoff).
+		Update("next_attempt_at", now)
+	return result.RowsAffected, result.Error
+}
+
+// FindWebhookBacklog counts the pending deliveries that were due before a time
+func (r *watchdogRepositoryImpl) FindWebhookBacklog(ctx context.Context, dueBefore time.Time) (domain.StuckItems, error) {
+	return r.stuck(ctx, &domain.WebhookDelivery{}, "next_attempt_at",
+		"status = ? AND next_attempt_at < ?", domain.WebhookDeliveryPending, dueBefore)
+}
+
+// FindStalledBroadcasts counts the broadcasts created before a time that never finished sending
+func (r *watchdogRepositoryImpl) FindStalledBroadcasts(ctx context.Context, createdBefore time.Time) (domain.StuckItems, error) {
+	return r.stuck(ctx, &domain.Broadcast{}, "created_at", "sent_at IS NULL AND created_at < ?", createdBefore)
+}
+
+// FindOpenPayments counts the pending payments, or those awaiting authentication, created before a time
+func (r *watchdogRepositoryImpl) FindOpenPayments(ctx context.Context, createdBefore time.Time) (domain.StuckItems, error) {
+	return r.stuck(ctx, &domain.Payment{}, "created_at", "status IN ? AND created_at < ?",
+		[]domain.PaymentStatus{domain.PaymentStatusPending, domain.PaymentStatusRequiresAction}, createdBefore)
+}
+
+// FindUnansweredRequests finds the appointments still pending acceptance that started before a
+// time, oldest first
+func (r *watchdogRepositoryImpl) FindUnansweredRequests(ctx context.Context, startedBefore time.Time, limit int) ([]*domain.Appointment, error) {
+	var appointments []*domain.Appointment
+	err := conn(ctx, r.db).
+		Where("status = ? AND start_time < ?", domain.AppointmentStatusPending, startedBefore).
+		Order("start_time ASC").
+		Limit(limit).
+		Find(&appointments).Error
+	return appointments, err
+}
+
+// stuck counts the rows of a model matching a condition, with the oldest value of a column
+func (r *watchdogRepositoryImpl) stuck(ctx context.Context, model any, column, condition string, args ...any) (domain.StuckItems, error) {
+	var row struct {
+		Count    int64
+		OldestAt *time.Time
+	}
+	err := conn(ctx, r.db).Model(model).
+		Select("COUNT(*) AS count, MIN("+column+") AS oldest_at").
+		Where(condition, args...).
+		Scan(&row).Error
+	return domain.StuckItems{Count: row.Count, OldestAt: row.OldestAt}, err
+}
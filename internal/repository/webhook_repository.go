@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// webhookRepositoryImpl implements the WebhookRepository interface
+type webhookRepositoryImpl struct {
+	*BaseRepositoryImpl[domain.WebhookSubscription]
+}
+
+// NewWebhookRepository creates a new webhook repository, scoped to the tenant in the context
+func NewWebhookRepository(db *gorm.DB) domain.WebhookRepository {
+	return &webhookRepositoryImpl{
+		BaseRepositoryImpl: newBaseRepository[domain.WebhookSubscription](db, WithTenantScope()),
+	}
+}
+
+// FindByBusiness finds the webhook subscriptions of a business, in creation order
+func (r *webhookRepositoryImpl) FindByBusiness(ctx context.Context, businessID string) ([]*domain.WebhookSubscription, error) {
+	var subscriptions []*domain.WebhookSubscription
+	err := r.query(ctx).
+		Where("business_id = ?", businessID).
+		Order("created_at ASC, id ASC").
+		Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+// EnqueueDeliveries creates deliveries, skipping those already created for the same
+// subscription and event
+func (r *webhookRepositoryImpl) EnqueueDeliveries(ctx context.Context, deliveries []*domain.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	for _, delivery := range deliveries {
+		if err := r.checkBusiness(ctx, delivery.BusinessID); err != nil {
+			return err
+		}
+	}
+	return conn(ctx, r.db).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&deliveries).Error
+}
+
+// FindDueDeliveries finds the pending deliveries whose next attempt is due, oldest first
+func (r *webhookRepositoryImpl) FindDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*domain.WebhookDelivery, error) {
+	if err := r.checkUnscoped(ctx); err != nil {
+		return nil, err
+	}
+	var deliveries []*domain.WebhookDelivery
+	err := conn(ctx, r.db).
+		Where("status = ? AND next_attempt_at <= ?", domain.WebhookDeliveryPending, now).
+		Order("next_attempt_at ASC, id ASC").
+		Limit(limit).
+		Find(&deliveries).Error
+	return deliveries, err
+}
+
+// UpdateDelivery saves the outcome of a delivery attempt
+func (r *webhookRepositoryImpl) UpdateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	if err := r.checkBusiness(ctx, delivery.BusinessID); err != nil {
+		return err
+	}
+	return conn(ctx, r.db).Save(delivery).Error
+}
+
+// FindDeliveries finds the deliveries of a subscription, latest first
+func (r *webhookRepositoryImpl) FindDeliveries(ctx context.Context, subscriptionID string, page, pageSize int) ([]*domain.WebhookDelivery, int64, error) {
+	query := r.query(ctx).Model(&domain.WebhookDelivery{}).Where("subscription_id = ?", subscriptionID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var deliveries []*domain.WebhookDelivery
+	err := query.Order("created_at DESC, id").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&deliveries).Error
+	return deliveries, total, err
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// AdminService defines the service interface for the back office platform operators look after
+// the businesses of every tenant in. Only platform admins may use it; business owners and staff
+// are refused whatever their role in their business.
+type AdminService interface {
+	SearchBusinesses(ctx context.Context, searchDTO dto.AdminBusinessSearchDTO, page, pageSize int) ([]*dto.AdminBusinessDTO, int64, error)
+	GetBusiness(ctx context.Context, id string) (*dto.AdminBusinessDTO, error)
+	SuspendBusiness(ctx context.Context, suspendDTO dto.SuspendBusinessDTO) (*dto.AdminBusinessDTO, error)
+	ReactivateBusiness(ctx context.Context, id string) (*dto.AdminBusinessDTO, error)
+	CheckBusiness(ctx context.Context) error
+}
+
+// adminServiceImpl implements the AdminService interface
+type adminServiceImpl struct {
+	businessRepo domain.BusinessRepository
+	validator    *validator.Validate
+	now          func() time.Time
+}
+
+// NewAdminService creates a new admin service
+func NewAdminService(businessRepo domain.BusinessRepository, validator *validator.Validate) AdminService {
+	return &adminServiceImpl{
+		businessRepo: businessRepo,
+		validator:    validator,
+		now:          time.Now,
+	}
+}
+
+// SearchBusinesses finds the businesses of every tenant by name, display name or email, their
+// status and their tier, most recently created first
+func (s *adminServiceImpl) SearchBusinesses(ctx context.Context, searchDTO dto.AdminBusinessSearchDTO, page, pageSize int) ([]*dto.AdminBusinessDTO, int64, error) {
+	if !IsPlatformAdmin(ctx) {
+		return nil, 0, NewForbiddenError("search businesses")
+	}
+	if err := s.validator.Struct(searchDTO); err != nil {
+		return nil, 0, validation.FromError(err)
+	}
+
+	pagination := ValidatePagination(&dto.PaginationRequest{Page: page, PageSize: pageSize})
+	search := domain.BusinessSearch{Query: searchDTO.Query, Status: searchDTO.Status, Tier: searchDTO.Tier}
+	businesses, total, err := s.businessRepo.Search(ctx, search, pagination.Page, pagination.PageSize)
+	if err != nil {
+		return nil, 0, NewServiceError("failed to search businesses", err)
+	}
+	now := s.now()
+	result := make([]*dto.AdminBusinessDTO, len(businesses))
+	for i, business := range businesses {
+		result[i] = dto.ToAdminBusinessDTO(business, now)
+	}
+	return result, total, nil
+}
+
+// GetBusiness returns any business, whichever tenant it is
+func (s *adminServiceImpl) GetBusiness(ctx context.Context, id string) (*dto.AdminBusinessDTO, error) {
+	if !IsPlatformAdmin(ctx) {
+		return nil, NewForbiddenError("view businesses")
+	}
+	business, err := getBusiness(ctx, s.businessRepo, id)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToAdminBusinessDTO(business, s.now()), nil
+}
+
+// SuspendBusiness stops a business from being used by its staff or booked by its clients until
+// it is reactivated. Its data is kept as it is.
+func (s *adminServiceImpl) SuspendBusiness(ctx context.Context, suspendDTO dto.SuspendBusinessDTO) (*dto.AdminBusinessDTO, error) {
+	if !IsPlatformAdmin(ctx) {
+		return nil, NewForbiddenError("suspend businesses")
+	}
+	if err := s.validator.Struct(suspendDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	business, err := getBusiness(ctx, s.businessRepo, suspendDTO.BusinessID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	if err := business.Suspend(suspendDTO.Reason, now); err != nil {
+		return nil, toValidationError(err)
+	}
+	business.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.businessRepo.Update(ctx, business); err != nil {
+		return nil, NewServiceError("failed to suspend business", err)
+	}
+	log.Info().Str("business_id", business.ID).Str("reason", *business.SuspensionReason).Msg("Business suspended")
+	return dto.ToAdminBusinessDTO(business, now), nil
+}
+
+// ReactivateBusiness lifts the suspension of a business
+func (s *adminServiceImpl) ReactivateBusiness(ctx context.Context, id string) (*dto.AdminBusinessDTO, error) {
+	if !IsPlatformAdmin(ctx) {
+		return nil, NewForbiddenError("reactivate businesses")
+	}
+	business, err := getBusiness(ctx, s.businessRepo, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := business.Reactivate(); err != nil {
+		return nil, toValidationError(err)
+	}
+	business.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.businessRepo.Update(ctx, business); err != nil {
+		return nil, NewServiceError("failed to reactivate business", err)
+	}
+	log.Info().Str("business_id", business.ID).Msg("Business reactivated")
+	return dto.ToAdminBusinessDTO(business, s.now()), nil
+}
+
+// CheckBusiness checks the business a request is made for, returning domain.ErrBusinessSuspended
+// when platform operators suspended it. Platform admins and requests made for no business are
+// let through.
+func (s *adminServiceImpl) CheckBusiness(ctx context.Context) error {
+	businessID := GetBusinessIDFromContext(ctx)
+	if businessID == nil || IsPlatformAdmin(ctx) {
+		return nil
+	}
+	business, err := s.businessRepo.GetByID(ctx, *businessID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return NewServiceError("failed to retrieve business", err)
+	}
+	if business.IsSuspended() {
+		return domain.ErrBusinessSuspended
+	}
+	return nil
+}
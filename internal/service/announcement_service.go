@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/i18n"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// AnnouncementService defines the service interface for the product updates the platform
+// posts to the in-app feed of businesses
+type AnnouncementService interface {
+	CreateAnnouncement(ctx context.Context, createDTO dto.CreateAnnouncementDTO) (*dto.AnnouncementResponseDTO, error)
+	DeleteAnnouncement(ctx context.Context, id string) error
+	ListAnnouncements(ctx context.Context, page, pageSize int) ([]*dto.AnnouncementResponseDTO, int64, error)
+	GetAnnouncementFeed(ctx context.Context, businessID, locale string) (*dto.AnnouncementFeedDTO, error)
+	MarkAnnouncementsRead(ctx context.Context, markDTO dto.MarkAnnouncementsReadDTO) (int64, error)
+}
+
+// announcementServiceImpl implements the AnnouncementService interface
+type announcementServiceImpl struct {
+	announcementRepo domain.AnnouncementRepository
+	businessRepo     domain.BusinessRepository
+	staffRepo        domain.StaffRepository
+	validator        *validator.Validate
+}
+
+// NewAnnouncementService creates a new announcement service
+func NewAnnouncementService(
+	announcementRepo domain.AnnouncementRepository,
+	businessRepo domain.BusinessRepository,
+	staffRepo domain.StaffRepository,
+	validator *validator.Validate,
+) AnnouncementService {
+	return &announcementServiceImpl{
+		announcementRepo: announcementRepo,
+		businessRepo:     businessRepo,
+		staffRepo:        staffRepo,
+		validator:        validator,
+	}
+}
+
+// CreateAnnouncement posts an announcement, shown in the targeted feeds from its publish time
+// (platform admin only)
+func (s *announcementServiceImpl) CreateAnnouncement(ctx context.Context, createDTO dto.CreateAnnouncementDTO) (*dto.AnnouncementResponseDTO, error) {
+	if !IsPlatformAdmin(ctx) {
+		return nil, NewForbiddenError("post announcements")
+	}
+	if err := s.validator.Struct(createDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+
+	announcement := &domain.Announcement{
+		Title:     createDTO.Title,
+		Body:      createDTO.Body,
+		Link:      createDTO.Link,
+		PublishAt: time.Now(),
+		ExpiresAt: createDTO.ExpiresAt,
+	}
+	if createDTO.PublishAt != nil {
+		announcement.PublishAt = *createDTO.PublishAt
+	}
+	if err := announcement.SetPlans(createDTO.Plans); err != nil {
+		return nil, NewServiceError("failed to encode plans", err)
+	}
+	if err := announcement.SetLocales(createDTO.Locales); err != nil {
+		return nil, NewServiceError("failed to encode locales", err)
+	}
+	if err := announcement.Validate(); err != nil {
+		return nil, toValidationError(err)
+	}
+
+	announcement.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.announcementRepo.Create(ctx, announcement); err != nil {
+		return nil, NewServiceError("failed to create announcement", err)
+	}
+	return dto.ToAnnouncementResponseDTO(announcement), nil
+}
+
+// DeleteAnnouncement retracts an announcement from every feed (platform admin only)
+func (s *announcementServiceImpl) DeleteAnnouncement(ctx context.Context, id string) error {
+	if !IsPlatformAdmin(ctx) {
+		return NewForbiddenError("delete announcements")
+	}
+	if _, err := s.announcementRepo.GetByID(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewNotFoundError("announcement", "id", id)
+		}
+		return NewServiceError("failed to retrieve announcement", err)
+	}
+	if err := s.announcementRepo.Delete(ctx, id); err != nil {
+		return NewServiceError("failed to delete announcement", err)
+	}
+	return nil
+}
+
+// ListAnnouncements lists every announcement, including scheduled and expired ones, latest
+// first (platform admin only)
+func (s *announcementServiceImpl) ListAnnouncements(ctx context.Context, page, pageSize int) ([]*dto.AnnouncementResponseDTO, int64, error) {
+	if !IsPlatformAdmin(ctx) {
+		return nil, 0, NewForbiddenError("list announcements")
+	}
+
+	announcements, total, err := s.announcementRepo.FindAll(ctx, page, pageSize)
+	if err != nil {
+		return nil, 0, NewServiceError("failed to retrieve announcements", err)
+	}
+	result := make([]*dto.AnnouncementResponseDTO, len(announcements))
+	for i, announcement := range announcements {
+		result[i] = dto.ToAnnouncementResponseDTO(announcement)
+	}
+	return result, total, nil
+}
+
+// GetAnnouncementFeed returns the live announcements targeting a business's plan and the
+// caller's locale, with what the caller has read. Any staff member of the business may read it.
+func (s *announcementServiceImpl) GetAnnouncementFeed(ctx context.Context, businessID, locale string) (*dto.AnnouncementFeedDTO, error) {
+	userID := GetUserIDFromContext(ctx)
+	if userID == nil {
+		return nil, NewForbiddenError("read announcements")
+	}
+	if _, err := s.staffRepo.FindByBusinessAndUser(ctx, businessID, *userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewForbiddenError("read announcements")
+		}
+		return nil, NewServiceError("failed to retrieve staff member", err)
+	}
+	business, err := getBusiness(ctx, s.businessRepo, businessID)
+	if err != nil {
+		return nil, err
+	}
+	if locale == "" {
+		locale = string(i18n.FromContext(ctx))
+	}
+
+	live, err := s.announcementRepo.FindLive(ctx, time.Now())
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve announcements", err)
+	}
+	announcements := make([]*domain.Announcement, 0, len(live))
+	ids := make([]string, 0, len(live))
+	for _, announcement := range live {
+		if announcement.Targets(business.SubscriptionTier, locale) {
+			announcements = append(announcements, announcement)
+			ids = append(ids, announcement.ID)
+		}
+	}
+
+	reads, err := s.announcementRepo.FindReads(ctx, *userID, ids)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve announcement reads", err)
+	}
+	feed := &dto.AnnouncementFeedDTO{Announcements: make([]*dto.AnnouncementFeedItemDTO, len(announcements))}
+	for i, announcement := range announcements {
+		var readAt *time.Time
+		if at, ok := reads[announcement.ID]; ok {
+			readAt = &at
+		} else {
+			feed.UnreadCount++
+		}
+		feed.Announcements[i] = dto.ToAnnouncementFeedItemDTO(announcement, readAt)
+	}
+	return feed, nil
+}
+
+// MarkAnnouncementsRead records that the caller read live announcements, returning how many
+// were unread. Announcements that are not live are ignored.
+func (s *announcementServiceImpl) MarkAnnouncementsRead(ctx context.Context, markDTO dto.MarkAnnouncementsReadDTO) (int64, error) {
+	userID := GetUserIDFromContext(ctx)
+	if userID == nil {
+		return 0, NewForbiddenError("read announcements")
+	}
+	if err := s.validator.Struct(markDTO); err != nil {
+		return 0, validation.FromError(err)
+	}
+
+	now := time.Now()
+	live, err := s.announcementRepo.FindLive(ctx, now)
+	if err != nil {
+		return 0, NewServiceError("failed to retrieve announcements", err)
+	}
+	ids := make([]string, 0, len(markDTO.AnnouncementIDs))
+	for _, announcement := range live {
+		if slices.Contains(markDTO.AnnouncementIDs, announcement.ID) {
+			ids = append(ids, announcement.ID)
+		}
+	}
+
+	marked, err := s.announcementRepo.MarkRead(ctx, *userID, ids, now)
+	if err != nil {
+		return 0, NewServiceError("failed to mark announcements as read", err)
+	}
+	return marked, nil
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// FeedbackLinkPath is the path of the link clients open to send anonymous feedback
+const FeedbackLinkPath = "/feedback/"
+
+// AnonymousFeedbackService defines the service interface for the anonymous feedback clients
+// send to a business, separate from public reviews
+type AnonymousFeedbackService interface {
+	CreateFeedbackLink(ctx context.Context, appointmentID string) (*dto.FeedbackLinkDTO, error)
+	GetFeedbackForm(ctx context.Context, token string) (*dto.FeedbackFormDTO, error)
+	SubmitFeedback(ctx context.Context, submitDTO dto.SubmitAnonymousFeedbackDTO) error
+	ListAnonymousFeedback(ctx context.Context, businessID string, unreadOnly bool, page, pageSize int) ([]*dto.AnonymousFeedbackDTO, int64, error)
+	MarkAnonymousFeedbackRead(ctx context.Context, markDTO dto.MarkAnonymousFeedbackReadDTO) (int64, error)
+}
+
+// anonymousFeedbackServiceImpl implements the AnonymousFeedbackService interface
+type anonymousFeedbackServiceImpl struct {
+	feedbackRepo    domain.AnonymousFeedbackRepository
+	appointmentRepo domain.BaseRepository[domain.Appointment]
+	businessRepo    domain.BusinessRepository
+	staffRepo       domain.StaffRepository
+	validator       *validator.Validate
+	publicURL       string
+}
+
+// NewAnonymousFeedbackService creates a new anonymous feedback service. Feedback links point to
+// publicURL, the externally reachable base URL of the API.
+func NewAnonymousFeedbackService(
+	feedbackRepo domain.AnonymousFeedbackRepository,
+	appointmentRepo domain.BaseRepository[domain.Appointment],
+	businessRepo domain.BusinessRepository,
+	staffRepo domain.StaffRepository,
+	validator *validator.Validate,
+	publicURL string,
+) AnonymousFeedbackService {
+	return &anonymousFeedbackServiceImpl{
+		feedbackRepo:    feedbackRepo,
+		appointmentRepo: appointmentRepo,
+		businessRepo:    businessRepo,
+		staffRepo:       staffRepo,
+		validator:       validator,
+		publicURL:       publicURL,
+	}
+}
+
+// CreateFeedbackLink returns the link the client of a completed appointment can send anonymous
+// feedback with. Each appointment has a single link; asking again returns the same one, and an
+// expired link that was never used is replaced.
+func (s *anonymousFeedbackServiceImpl) CreateFeedbackLink(ctx context.Context, appointmentID string) (*dto.FeedbackLinkDTO, error) {
+	appointment, err := s.appointmentRepo.GetByID(ctx, appointmentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("appointment", "id", appointmentID)
+		}
+		return nil, NewServiceError("failed to retrieve appointment", err)
+	}
+	if appointment.Status != domain.AppointmentStatusCompleted {
+		return nil, validation.NewValidationError("feedback can only be requested for completed appointments")
+	}
+
+	now := time.Now()
+	token, err := s.feedbackRepo.FindTokenByAppointment(ctx, appointmentID)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		token = &domain.FeedbackToken{BusinessID: appointment.BusinessID, AppointmentID: appointment.ID}
+	case err != nil:
+		return nil, NewServiceError("failed to retrieve feedback link", err)
+	case token.Used:
+		return nil, validation.NewValidationError(domain.ErrFeedbackTokenUsed.Error())
+	case !token.IsExpired(now):
+		return s.toFeedbackLinkDTO(token), nil
+	}
+
+	value, err := domain.NewConfirmationToken()
+	if err != nil {
+		return nil, NewServiceError("failed to create feedback link", err)
+	}
+	token.Token = value
+	token.ExpiresAt = now.Add(domain.FeedbackTokenValidity)
+	token.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.feedbackRepo.SaveToken(ctx, token); err != nil {
+		return nil, NewServiceError("failed to create feedback link", err)
+	}
+	return s.toFeedbackLinkDTO(token), nil
+}
+
+// GetFeedbackForm retrieves what the feedback page shows for a link, failing when the link can
+// no longer be used
+func (s *anonymousFeedbackServiceImpl) GetFeedbackForm(ctx context.Context, token string) (*dto.FeedbackFormDTO, error) {
+	feedbackToken, err := s.loadToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	business, err := s.businessRepo.GetByID(ctx, feedbackToken.BusinessID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve business", err)
+	}
+	return &dto.FeedbackFormDTO{BusinessName: business.GetDisplayName()}, nil
+}
+
+// SubmitFeedback stores the feedback sent with a link, using up the link. Nothing identifying the
+// client or the appointment is stored with the feedback.
+func (s *anonymousFeedbackServiceImpl) SubmitFeedback(ctx context.Context, submitDTO dto.SubmitAnonymousFeedbackDTO) error {
+	if err := s.validator.Struct(submitDTO); err != nil {
+		return validation.FromError(err)
+	}
+	feedbackToken, err := s.loadToken(ctx, submitDTO.Token)
+	if err != nil {
+		return err
+	}
+
+	feedback, err := domain.NewAnonymousFeedback(feedbackToken.BusinessID, submitDTO.Rating, submitDTO.Message, time.Now())
+	if err != nil {
+		return toValidationError(err)
+	}
+	if err := s.feedbackRepo.Submit(ctx, feedbackToken.ID, feedback); err != nil {
+		if errors.Is(err, domain.ErrFeedbackTokenUsed) {
+			return validation.FromError(err)
+		}
+		return NewServiceError("failed to send feedback", err)
+	}
+	return nil
+}
+
+// ListAnonymousFeedback lists the feedback sent to a business, most recent day first. Only the
+// owner of the business may read it.
+func (s *anonymousFeedbackServiceImpl) ListAnonymousFeedback(ctx context.Context, businessID string, unreadOnly bool, page, pageSize int) ([]*dto.AnonymousFeedbackDTO, int64, error) {
+	if err := s.ensureOwner(ctx, businessID); err != nil {
+		return nil, 0, err
+	}
+
+	feedback, total, err := s.feedbackRepo.FindByBusiness(ctx, businessID, unreadOnly, page, pageSize)
+	if err != nil {
+		return nil, 0, NewServiceError("failed to retrieve anonymous feedback", err)
+	}
+	result := make([]*dto.AnonymousFeedbackDTO, len(feedback))
+	for i, item := range feedback {
+		result[i] = dto.ToAnonymousFeedbackDTO(item)
+	}
+	return result, total, nil
+}
+
+// MarkAnonymousFeedbackRead marks feedback as read by the owner, returning how many were unread
+func (s *anonymousFeedbackServiceImpl) MarkAnonymousFeedbackRead(ctx context.Context, markDTO dto.MarkAnonymousFeedbackReadDTO) (int64, error) {
+	if err := s.validator.Struct(markDTO); err != nil {
+		return 0, validation.FromError(err)
+	}
+	if err := s.ensureOwner(ctx, markDTO.BusinessID); err != nil {
+		return 0, err
+	}
+
+	marked, err := s.feedbackRepo.MarkRead(ctx, markDTO.BusinessID, markDTO.FeedbackIDs, time.Now(), GetUserIDFromContext(ctx))
+	if err != nil {
+		return 0, NewServiceError("failed to mark anonymous feedback as read", err)
+	}
+	return marked, nil
+}
+
+// loadToken loads a feedback token that can still be used
+func (s *anonymousFeedbackServiceImpl) loadToken(ctx context.Context, token string) (*domain.FeedbackToken, error) {
+	if token == "" {
+		return nil, validation.NewValidationError("token is required")
+	}
+	feedbackToken, err := s.feedbackRepo.FindTokenByToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("feedback link", "token", token)
+		}
+		return nil, NewServiceError("failed to retrieve feedback link", err)
+	}
+	if err := feedbackToken.CheckUsable(time.Now()); err != nil {
+		return nil, validation.FromError(err)
+	}
+	return feedbackToken, nil
+}
+
+// ensureOwner checks that the caller owns the business. Anonymous feedback is often about
+// staff, so managers and employees cannot read it.
+func (s *anonymousFeedbackServiceImpl) ensureOwner(ctx context.Context, businessID string) error {
+	userID := GetUserIDFromContext(ctx)
+	if userID == nil {
+		return NewForbiddenError("read anonymous feedback")
+	}
+	staff, err := s.staffRepo.FindByBusinessAndUser(ctx, businessID, *userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewForbiddenError("read anonymous feedback")
+		}
+		return NewServiceError("failed to retrieve staff member", err)
+	}
+	if !staff.IsOwner() {
+		return NewForbiddenError("read anonymous feedback")
+	}
+	return nil
+}
+
+// toFeedbackLinkDTO converts a token to the link the client opens
+func (s *anonymousFeedbackServiceImpl) toFeedbackLinkDTO(token *domain.FeedbackToken) *dto.FeedbackLinkDTO {
+	return &dto.FeedbackLinkDTO{
+		AppointmentID: token.AppointmentID,
+		URL:           s.publicURL + FeedbackLinkPath + token.Token,
+		ExpiresAt:     token.ExpiresAt,
+	}
+}
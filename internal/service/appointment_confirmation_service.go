@@ -0,0 +1,522 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/notification"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+const (
+	// confirmationBatchSize limits how many requests a single run sends
+	confirmationBatchSize = 200
+	// escalationBatchSize limits how many requests a single run escalates
+	escalationBatchSize = 200
+	// maxTaskOutcomeLength bounds the outcome recorded on a front desk task
+	maxTaskOutcomeLength = 1000
+	// maxUnconfirmedHours limits how far ahead the unconfirmed appointments dashboard looks
+	maxUnconfirmedHours = 14 * 24
+	// ConfirmationLinkPath is the path of the link clients open to confirm an appointment
+	ConfirmationLinkPath = "/confirm/"
+)
+
+// AppointmentConfirmationService defines the service interface for appointment confirmation requests
+type AppointmentConfirmationService interface {
+	SendDueRequests(ctx context.Context, now time.Time) (*dto.ConfirmationRunDTO, error)
+	GetConfirmationRequest(ctx context.Context, token string) (*dto.ConfirmationResultDTO, error)
+	ConfirmByToken(ctx context.Context, token string) (*dto.ConfirmationResultDTO, error)
+	HandleSMSReply(ctx context.Context, from, body string) (bool, error)
+	ConfirmAppointment(ctx context.Context, appointmentID string) error
+	GetUnconfirmedAppointments(ctx context.Context, businessID string, hours int) ([]*dto.UnconfirmedAppointmentDTO, error)
+	EscalateUnacknowledged(ctx context.Context, now time.Time) (*dto.EscalationRunDTO, error)
+	GetReminderChain(ctx context.Context, appointmentID string) (*dto.ReminderChainDTO, error)
+	ListFrontDeskTasks(ctx context.Context, businessID string, status *domain.FrontDeskTaskStatus) ([]*dto.FrontDeskTaskDTO, error)
+	CompleteFrontDeskTask(ctx context.Context, id string, completeDTO dto.CompleteFrontDeskTaskDTO) (*dto.FrontDeskTaskDTO, error)
+}
+
+// appointmentConfirmationServiceImpl implements the AppointmentConfirmationService interface
+type appointmentConfirmationServiceImpl struct {
+	confirmationRepo domain.AppointmentConfirmationRepository
+	taskRepo         domain.FrontDeskTaskRepository
+	appointmentRepo  domain.BaseRepository[domain.Appointment]
+	businessRepo     domain.BusinessRepository
+	clientRepo       domain.BaseRepository[domain.Client]
+	calendarRepo     domain.CalendarRepository
+	templateRepo     domain.MessageTemplateRepository
+	eventService     EventService
+	sender           notification.NotificationSender
+	publicURL        string
+}
+
+// NewAppointmentConfirmationService creates a new appointment confirmation service. Confirmation
+// links point to publicURL, the externally reachable base URL of the API.
+func NewAppointmentConfirmationService(
+	confirmationRepo domain.AppointmentConfirmationRepository,
+	taskRepo domain.FrontDeskTaskRepository,
+	appointmentRepo domain.BaseRepository[domain.Appointment],
+	businessRepo domain.BusinessRepository,
+	clientRepo domain.BaseRepository[domain.Client],
+	calendarRepo domain.CalendarRepository,
+	templateRepo domain.MessageTemplateRepository,
+	eventService EventService,
+	sender notification.NotificationSender,
+	publicURL string,
+) AppointmentConfirmationService {
+	return &appointmentConfirmationServiceImpl{
+		confirmationRepo: confirmationRepo,
+		taskRepo:         taskRepo,
+		appointmentRepo:  appointmentRepo,
+		businessRepo:     businessRepo,
+		clientRepo:       clientRepo,
+		calendarRepo:     calendarRepo,
+		templateRepo:     templateRepo,
+		eventService:     eventService,
+		sender:           sender,
+		publicURL:        strings.TrimSuffix(publicURL, "/"),
+	}
+}
+
+// SendDueRequests sends a confirmation request for every upcoming appointment that has entered
+// its business's confirmation lead time. Each appointment is only ever sent one request.
+func (s *appointmentConfirmationServiceImpl) SendDueRequests(ctx context.Context, now time.Time) (*dto.ConfirmationRunDTO, error) {
+	candidates, err := s.confirmationRepo.FindDueForRequest(ctx, now, confirmationBatchSize)
+	if err != nil {
+		return nil, NewServiceError("failed to find appointments due a confirmation request", err)
+	}
+
+	run := &dto.ConfirmationRunDTO{Due: len(candidates)}
+	for _, candidate := range candidates {
+		if err := s.sendRequest(ctx, candidate, now); err != nil {
+			log.Warn().Err(err).
+				Str("appointment_id", candidate.AppointmentID).
+				Msg("Failed to send confirmation request")
+			run.Failed++
+			continue
+		}
+		run.Sent++
+	}
+	return run, nil
+}
+
+// sendRequest records and sends the confirmation request of an appointment. The request is
+// recorded first so that the appointment is not picked up again, even if delivery fails.
+func (s *appointmentConfirmationServiceImpl) sendRequest(ctx context.Context, candidate *domain.ConfirmationCandidate, now time.Time) error {
+	token, err := domain.NewConfirmationToken()
+	if err != nil {
+		return err
+	}
+
+	confirmation := &domain.AppointmentConfirmation{
+		AppointmentID: candidate.AppointmentID,
+		BusinessID:    candidate.BusinessID,
+		ClientID:      candidate.ClientID,
+		Attempt:       1,
+		Token:         token,
+		Status:        domain.ConfirmationStatusPending,
+		SentAt:        now,
+	}
+	channel, recipient, ok := candidate.Channel()
+	if !ok {
+		// The phone number on file is unusable; record the failure so the appointment shows
+		// up on the dashboard as needing a call rather than being retried every run
+		confirmation.Channel = domain.MessageChannelSMS
+		if candidate.ClientPhone != nil {
+			confirmation.Recipient = *candidate.ClientPhone
+		}
+		confirmation.Status = domain.ConfirmationStatusFailed
+		if err := s.confirmationRepo.Create(ctx, confirmation); err != nil {
+			return err
+		}
+		return errors.New("client has no usable phone number or email address")
+	}
+	return s.send(ctx, candidate, confirmation, channel, recipient, now)
+}
+
+// send records a request and hands it to the provider of its channel, recording whether the
+// provider accepted it
+func (s *appointmentConfirmationServiceImpl) send(ctx context.Context, candidate *domain.ConfirmationCandidate, confirmation *domain.AppointmentConfirmation, channel domain.MessageChannel, recipient string, now time.Time) error {
+	confirmation.Channel = channel
+	confirmation.Recipient = recipient
+
+	message, err := s.renderRequest(ctx, candidate, channel, confirmation.Token)
+	if err != nil {
+		return err
+	}
+	message.Recipient = recipient
+
+	if err := s.confirmationRepo.Create(ctx, confirmation); err != nil {
+		return err
+	}
+	sendErr := s.sender.Send(ctx, *message)
+	confirmation.RecordDelivery(sendErr, now)
+	if err := s.confirmationRepo.Update(ctx, confirmation); err != nil {
+		log.Error().Err(err).Str("appointment_id", candidate.AppointmentID).Msg("Failed to record confirmation delivery")
+	}
+	return sendErr
+}
+
+// EscalateUnacknowledged takes the next step for every upcoming appointment whose latest
+// confirmation request went unacknowledged past its business's cutoff, or could not be
+// delivered: the request is sent again through a channel not tried yet, or once every channel
+// was tried, the front desk is given a task to call the client.
+func (s *appointmentConfirmationServiceImpl) EscalateUnacknowledged(ctx context.Context, now time.Time) (*dto.EscalationRunDTO, error) {
+	candidates, err := s.confirmationRepo.FindDueForEscalation(ctx, now, escalationBatchSize)
+	if err != nil {
+		return nil, NewServiceError("failed to find confirmation requests due escalation", err)
+	}
+
+	run := &dto.EscalationRunDTO{Due: len(candidates)}
+	for _, candidate := range candidates {
+		resent, err := s.escalate(ctx, candidate, now)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			// Escalated by another run in the meantime
+		case err != nil:
+			log.Warn().Err(err).
+				Str("appointment_id", candidate.AppointmentID).
+				Msg("Failed to escalate confirmation request")
+			run.Failed++
+		case resent:
+			run.Resent++
+		default:
+			run.Tasks++
+		}
+	}
+	return run, nil
+}
+
+// escalate takes the next step of an appointment's reminder chain, reporting whether the
+// request was sent again rather than handed to the front desk. The latest request is marked
+// escalated first, so that the step is only ever taken once.
+func (s *appointmentConfirmationServiceImpl) escalate(ctx context.Context, candidate *domain.EscalationCandidate, now time.Time) (bool, error) {
+	chain, err := s.confirmationRepo.FindByAppointment(ctx, candidate.AppointmentID)
+	if err != nil {
+		return false, err
+	}
+	tried := make([]domain.MessageChannel, len(chain))
+	for i, confirmation := range chain {
+		tried[i] = confirmation.Channel
+	}
+	if err := s.confirmationRepo.MarkEscalated(ctx, candidate.ConfirmationID, now); err != nil {
+		return false, err
+	}
+
+	if channel, recipient, ok := candidate.NextChannel(tried); ok {
+		token, err := domain.NewConfirmationToken()
+		if err != nil {
+			return false, err
+		}
+		confirmation := &domain.AppointmentConfirmation{
+			AppointmentID: candidate.AppointmentID,
+			BusinessID:    candidate.BusinessID,
+			ClientID:      candidate.ClientID,
+			Attempt:       candidate.Attempt + 1,
+			Token:         token,
+			Status:        domain.ConfirmationStatusPending,
+			SentAt:        now,
+		}
+		return true, s.send(ctx, &candidate.ConfirmationCandidate, confirmation, channel, recipient, now)
+	}
+
+	task := &domain.FrontDeskTask{
+		BusinessID:    candidate.BusinessID,
+		AppointmentID: &candidate.AppointmentID,
+		ClientID:      &candidate.ClientID,
+		Kind:          domain.FrontDeskTaskCallClient,
+		Status:        domain.FrontDeskTaskOpen,
+		Reason:        fmt.Sprintf("The client did not confirm the appointment after %d reminder(s)", len(chain)),
+		DueAt:         now,
+	}
+	return false, s.taskRepo.Create(ctx, task)
+}
+
+// GetReminderChain retrieves the confirmation requests sent about an appointment, with when each
+// was delivered, acknowledged or escalated, and the front desk tasks they led to
+func (s *appointmentConfirmationServiceImpl) GetReminderChain(ctx context.Context, appointmentID string) (*dto.ReminderChainDTO, error) {
+	if appointmentID == "" {
+		return nil, validation.NewValidationError("appointment_id is required")
+	}
+
+	appointment, err := s.appointmentRepo.GetByID(ctx, appointmentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("appointment", "id", appointmentID)
+		}
+		return nil, NewServiceError("failed to retrieve appointment", err)
+	}
+	confirmations, err := s.confirmationRepo.FindByAppointment(ctx, appointmentID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve confirmation requests", err)
+	}
+	tasks, err := s.taskRepo.FindByAppointment(ctx, appointmentID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve front desk tasks", err)
+	}
+
+	return dto.ToReminderChainDTO(appointment, confirmations, tasks), nil
+}
+
+// ListFrontDeskTasks retrieves the tasks of a business's front desk, soonest due first
+func (s *appointmentConfirmationServiceImpl) ListFrontDeskTasks(ctx context.Context, businessID string, status *domain.FrontDeskTaskStatus) ([]*dto.FrontDeskTaskDTO, error) {
+	if businessID == "" {
+		return nil, validation.NewValidationError("business_id is required")
+	}
+
+	tasks, err := s.taskRepo.FindByBusiness(ctx, businessID, status)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve front desk tasks", err)
+	}
+	return dto.ToFrontDeskTaskDTOs(tasks), nil
+}
+
+// CompleteFrontDeskTask marks a front desk task done. Completing a task to call a client as
+// confirmed confirms the appointment on the client's behalf.
+func (s *appointmentConfirmationServiceImpl) CompleteFrontDeskTask(ctx context.Context, id string, completeDTO dto.CompleteFrontDeskTaskDTO) (*dto.FrontDeskTaskDTO, error) {
+	if completeDTO.Outcome != nil && len(*completeDTO.Outcome) > maxTaskOutcomeLength {
+		return nil, validation.NewValidationError("outcome must be at most 1000 characters")
+	}
+
+	task, err := s.taskRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("front desk task", "id", id)
+		}
+		return nil, NewServiceError("failed to retrieve front desk task", err)
+	}
+	if !task.IsOpen() {
+		return nil, validation.NewValidationError("the task is already done")
+	}
+
+	if completeDTO.Confirmed {
+		if task.Kind != domain.FrontDeskTaskCallClient || task.AppointmentID == nil {
+			return nil, validation.NewValidationError("only tasks to call a client about an appointment can confirm it")
+		}
+		if err := s.ConfirmAppointment(ctx, *task.AppointmentID); err != nil {
+			return nil, err
+		}
+	}
+
+	userID := GetUserIDFromContext(ctx)
+	task.Complete(completeDTO.Outcome, userID, time.Now())
+	task.SetAuditFields(userID)
+	if err := s.taskRepo.Update(ctx, task); err != nil {
+		return nil, NewServiceError("failed to complete front desk task", err)
+	}
+	return dto.ToFrontDeskTaskDTO(task), nil
+}
+
+// renderRequest renders the business's active confirmation template for the channel, or the
+// default message when it has none
+func (s *appointmentConfirmationServiceImpl) renderRequest(ctx context.Context, candidate *domain.ConfirmationCandidate, channel domain.MessageChannel, token string) (*notification.Message, error) {
+	business, err := s.businessRepo.GetByID(ctx, candidate.BusinessID)
+	if err != nil {
+		return nil, err
+	}
+	client, err := s.clientRepo.GetByID(ctx, candidate.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	appointment, err := s.calendarRepo.FindByAppointmentID(ctx, candidate.AppointmentID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		// Not projected yet; render without services and staff
+		appointment = &domain.CalendarEntry{StartTime: candidate.StartTime, EndTime: candidate.EndTime}
+	}
+
+	var subject *string
+	body := domain.DefaultConfirmationSMS
+	if channel == domain.MessageChannelEmail {
+		defaultSubject := domain.DefaultConfirmationEmailSubject
+		subject = &defaultSubject
+		body = domain.DefaultConfirmationEmail
+	}
+	template, err := s.templateRepo.FindActiveByPurpose(ctx, candidate.BusinessID, channel, domain.ConfirmationPurpose)
+	switch {
+	case err == nil:
+		subject = template.Subject
+		body = template.Body
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, err
+	}
+
+	values := domain.TemplateRenderContext{
+		Business:         business,
+		Client:           client,
+		Appointment:      appointment,
+		ConfirmationLink: s.publicURL + ConfirmationLinkPath + token,
+	}.Values()
+
+	message := &notification.Message{
+		BusinessID: candidate.BusinessID,
+		Channel:    channel,
+		Body:       domain.RenderTemplateText(body, values),
+	}
+	if channel == domain.MessageChannelEmail && subject != nil {
+		rendered := domain.RenderTemplateText(*subject, values)
+		message.Subject = &rendered
+	}
+	return message, nil
+}
+
+// GetConfirmationRequest retrieves the appointment of the request a client opened the link of,
+// without confirming it; link scanners in email clients open links too
+func (s *appointmentConfirmationServiceImpl) GetConfirmationRequest(ctx context.Context, token string) (*dto.ConfirmationResultDTO, error) {
+	result, _, err := s.loadRequest(ctx, token)
+	return result, err
+}
+
+// ConfirmByToken confirms the appointment of the request a client opened the link of.
+// Confirming again is not an error.
+func (s *appointmentConfirmationServiceImpl) ConfirmByToken(ctx context.Context, token string) (*dto.ConfirmationResultDTO, error) {
+	result, appointment, err := s.loadRequest(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if result.AlreadyConfirmed {
+		return result, nil
+	}
+	if !appointment.CanBeCancelled() {
+		return nil, validation.NewValidationError("the appointment can no longer be confirmed")
+	}
+
+	if err := s.confirm(ctx, appointment.ID, appointment.BusinessID, domain.ConfirmationSourceLink); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// loadRequest loads the appointment and business of a confirmation request
+func (s *appointmentConfirmationServiceImpl) loadRequest(ctx context.Context, token string) (*dto.ConfirmationResultDTO, *domain.Appointment, error) {
+	if token == "" {
+		return nil, nil, validation.NewValidationError("token is required")
+	}
+
+	confirmation, err := s.confirmationRepo.FindByToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, NewNotFoundError("confirmation request", "token", token)
+		}
+		return nil, nil, NewServiceError("failed to retrieve confirmation request", err)
+	}
+
+	appointment, err := s.appointmentRepo.GetByID(ctx, confirmation.AppointmentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, NewNotFoundError("appointment", "id", confirmation.AppointmentID)
+		}
+		return nil, nil, NewServiceError("failed to retrieve appointment", err)
+	}
+	business, err := s.businessRepo.GetByID(ctx, confirmation.BusinessID)
+	if err != nil {
+		return nil, nil, NewServiceError("failed to retrieve business", err)
+	}
+
+	result := &dto.ConfirmationResultDTO{
+		AppointmentID:    appointment.ID,
+		BusinessName:     business.GetDisplayName(),
+		StartTime:        appointment.StartTime,
+		AlreadyConfirmed: appointment.ClientConfirmed,
+	}
+	if location, err := time.LoadLocation(business.TimeZone); err == nil {
+		result.StartTime = appointment.StartTime.In(location)
+	}
+	return result, appointment, nil
+}
+
+// HandleSMSReply processes an inbound SMS. A confirmation keyword from a number with a pending
+// request confirms that appointment; anything else is ignored. It reports whether an
+// appointment was confirmed.
+func (s *appointmentConfirmationServiceImpl) HandleSMSReply(ctx context.Context, from, body string) (bool, error) {
+	if !domain.IsConfirmationReply(body) {
+		return false, nil
+	}
+	phone := domain.NormalizePhone(from)
+	if len(phone) < 6 {
+		return false, nil
+	}
+
+	confirmation, err := s.confirmationRepo.FindPendingByPhone(ctx, phone)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, NewServiceError("failed to retrieve confirmation request", err)
+	}
+
+	if err := s.confirm(ctx, confirmation.AppointmentID, confirmation.BusinessID, domain.ConfirmationSourceSMS); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ConfirmAppointment marks an appointment as confirmed by staff on the client's behalf
+func (s *appointmentConfirmationServiceImpl) ConfirmAppointment(ctx context.Context, appointmentID string) error {
+	if appointmentID == "" {
+		return validation.NewValidationError("appointment_id is required")
+	}
+
+	appointment, err := s.appointmentRepo.GetByID(ctx, appointmentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewNotFoundError("appointment", "id", appointmentID)
+		}
+		return NewServiceError("failed to retrieve appointment", err)
+	}
+	if appointment.ClientConfirmed {
+		return nil
+	}
+	if !appointment.CanBeCancelled() {
+		return validation.NewValidationError("only scheduled or confirmed appointments can be confirmed")
+	}
+
+	return s.confirm(ctx, appointment.ID, appointment.BusinessID, domain.ConfirmationSourceStaff)
+}
+
+// GetUnconfirmedAppointments retrieves the appointments starting in the next hours that the
+// clients have not confirmed, so that staff can chase them
+func (s *appointmentConfirmationServiceImpl) GetUnconfirmedAppointments(ctx context.Context, businessID string, hours int) ([]*dto.UnconfirmedAppointmentDTO, error) {
+	if businessID == "" {
+		return nil, validation.NewValidationError("business_id is required")
+	}
+	if hours < 1 || hours > maxUnconfirmedHours {
+		return nil, validation.NewValidationError("hours must be between 1 and 336")
+	}
+
+	now := time.Now()
+	appointments, err := s.confirmationRepo.FindUnconfirmed(ctx, businessID, now, now.Add(time.Duration(hours)*time.Hour))
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve unconfirmed appointments", err)
+	}
+
+	return dto.ToUnconfirmedAppointmentDTOs(appointments), nil
+}
+
+// confirm flags the appointment as confirmed and publishes the change
+func (s *appointmentConfirmationServiceImpl) confirm(ctx context.Context, appointmentID, businessID string, source domain.ConfirmationSource) error {
+	if err := s.confirmationRepo.ConfirmAppointment(ctx, appointmentID, source, time.Now()); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewNotFoundError("appointment", "id", appointmentID)
+		}
+		return NewServiceError("failed to confirm appointment", err)
+	}
+
+	event, err := domain.NewDomainEvent(domain.AggregateAppointment, appointmentID, domain.EventAppointmentUpdated, &businessID,
+		map[string]any{"client_confirmed": true, "confirmation_source": source})
+	if err == nil {
+		err = s.eventService.Publish(ctx, event)
+	}
+	if err != nil {
+		log.Error().Err(err).Str("appointment_id", appointmentID).Msg("Failed to publish appointment confirmation")
+	}
+	return nil
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// AppointmentQuoteService defines the service interface for pricing appointments before they
+// are booked
+type AppointmentQuoteService interface {
+	QuoteAppointment(ctx context.Context, quoteDTO dto.QuoteAppointmentDTO) (*dto.AppointmentQuoteDTO, error)
+}
+
+// appointmentQuoteServiceImpl implements the AppointmentQuoteService interface
+type appointmentQuoteServiceImpl struct {
+	quoteRepo       domain.AppointmentQuoteRepository
+	serviceRepo     domain.BaseRepository[domain.Service]
+	priceChangeRepo domain.PriceChangeRepository
+	loyaltyRepo     domain.LoyaltyRepository
+	clientRepo      domain.BaseRepository[domain.Client]
+	businessRepo    domain.BusinessRepository
+	staffRepo       domain.StaffRepository
+	validator       *validator.Validate
+}
+
+// NewAppointmentQuoteService creates a new appointment quote service
+func NewAppointmentQuoteService(
+	quoteRepo domain.AppointmentQuoteRepository,
+	serviceRepo domain.BaseRepository[domain.Service],
+	priceChangeRepo domain.PriceChangeRepository,
+	loyaltyRepo domain.LoyaltyRepository,
+	clientRepo domain.BaseRepository[domain.Client],
+	businessRepo domain.BusinessRepository,
+	staffRepo domain.StaffRepository,
+	validator *validator.Validate,
+) AppointmentQuoteService {
+	return &appointmentQuoteServiceImpl{
+		quoteRepo:       quoteRepo,
+		serviceRepo:     serviceRepo,
+		priceChangeRepo: priceChangeRepo,
+		loyaltyRepo:     loyaltyRepo,
+		clientRepo:      clientRepo,
+		businessRepo:    businessRepo,
+		staffRepo:       staffRepo,
+		validator:       validator,
+	}
+}
+
+// QuoteAppointment prices an appointment without booking it: the services and add-ons at the
+// prices they will have by then, the promotion the client gets, the deposit due and the loyalty
+// rewards the client could redeem. The quote comes with a token that books the appointment at
+// the quoted total within domain.QuoteValidity. Only staff who may book appointments can
+// request quotes.
+func (s *appointmentQuoteServiceImpl) QuoteAppointment(ctx context.Context, quoteDTO dto.QuoteAppointmentDTO) (*dto.AppointmentQuoteDTO, error) {
+	if err := s.validator.Struct(quoteDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	if err := s.requireBooking(ctx, quoteDTO.BusinessID, "quote appointments"); err != nil {
+		return nil, err
+	}
+	staffID, err := s.bookingStaff(ctx, quoteDTO.BusinessID, quoteDTO.StaffID)
+	if err != nil {
+		return nil, err
+	}
+	client, err := s.clientRepo.GetByID(ctx, quoteDTO.ClientID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, NewServiceError("failed to retrieve client", err)
+	}
+	if client == nil || client.BusinessID != quoteDTO.BusinessID {
+		return nil, NewNotFoundError("client", "id", quoteDTO.ClientID)
+	}
+
+	services, err := s.getServices(ctx, quoteDTO.BusinessID, quoteDTO.ServiceIDs)
+	if err != nil {
+		return nil, err
+	}
+	addOns, err := s.getServices(ctx, quoteDTO.BusinessID, quoteDTO.AddOnServiceIDs)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.CheckBookingAge(append(services, addOns...), quoteDTO.StartTime); err != nil {
+		return nil, toValidationError(err)
+	}
+
+	request := domain.QuoteRequest{Services: services, AddOns: addOns, StartTime: quoteDTO.StartTime}
+	if request.PriceChanges, err = s.pendingPriceChanges(ctx, quoteDTO.BusinessID, quoteDTO.StartTime); err != nil {
+		return nil, err
+	}
+	if request.Promotions, err = s.quoteRepo.FindPromotions(ctx, quoteDTO.BusinessID, client.ID, quoteDTO.StartTime); err != nil {
+		return nil, NewServiceError("failed to retrieve promotions", err)
+	}
+	if request.Programs, err = s.loyaltyRepo.FindRunningPrograms(ctx, quoteDTO.BusinessID, quoteDTO.StartTime); err != nil {
+		return nil, NewServiceError("failed to retrieve loyalty programs", err)
+	}
+	request.Memberships = make(map[string]*domain.ClientLoyaltyMembership, len(request.Programs))
+	for _, program := range request.Programs {
+		membership, err := s.loyaltyRepo.FindMembership(ctx, program.ID, client.ID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue
+			}
+			return nil, NewServiceError("failed to retrieve loyalty membership", err)
+		}
+		request.Memberships[program.ID] = membership
+	}
+
+	breakdown, err := domain.PriceQuote(request)
+	if err != nil {
+		return nil, toValidationError(err)
+	}
+	quote, err := domain.NewAppointmentQuote(quoteDTO.BusinessID, client.ID, staffID, quoteDTO.StartTime, breakdown, time.Now())
+	if err != nil {
+		return nil, NewServiceError("failed to issue quote", err)
+	}
+	quote.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.quoteRepo.Create(ctx, quote); err != nil {
+		return nil, NewServiceError("failed to save quote", err)
+	}
+	return dto.ToAppointmentQuoteDTO(quote, breakdown), nil
+}
+
+// getServices retrieves the bookable services of the business with the given IDs, in order
+func (s *appointmentQuoteServiceImpl) getServices(ctx context.Context, businessID string, ids []string) ([]*domain.Service, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	found, err := s.serviceRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve services", err)
+	}
+	byID := make(map[string]*domain.Service, len(found))
+	for _, service := range found {
+		byID[service.ID] = service
+	}
+	services := make([]*domain.Service, len(ids))
+	for i, id := range ids {
+		service, ok := byID[id]
+		if !ok || service.BusinessID != businessID {
+			return nil, NewNotFoundError("service", "id", id)
+		}
+		if !service.IsActive {
+			return nil, validation.NewValidationError(fmt.Sprintf("service %q is not available for booking", service.Name))
+		}
+		services[i] = service
+	}
+	return services, nil
+}
+
+// pendingPriceChanges finds the business's scheduled price changes taking effect by the given
+// time, with the services they target
+func (s *appointmentQuoteServiceImpl) pendingPriceChanges(ctx context.Context, businessID string, at time.Time) ([]domain.PendingPriceChange, error) {
+	changes, err := s.priceChangeRepo.FindByBusinessID(ctx, businessID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve price changes", err)
+	}
+	var pending []domain.PendingPriceChange
+	for _, change := range changes {
+		if change.Status != domain.PriceChangeScheduled || change.EffectiveAt.After(at) {
+			continue
+		}
+		serviceIDs, err := change.GetServiceIDs()
+		if err != nil {
+			return nil, NewServiceError("failed to read price change", err)
+		}
+		categoryIDs, err := change.GetCategoryIDs()
+		if err != nil {
+			return nil, NewServiceError("failed to read price change", err)
+		}
+		targets, err := s.priceChangeRepo.FindTargetServices(ctx, businessID, serviceIDs, categoryIDs)
+		if err != nil {
+			return nil, NewServiceError("failed to retrieve price change services", err)
+		}
+		ids := make([]string, len(targets))
+		for i, target := range targets {
+			ids[i] = target.ID
+		}
+		pending = append(pending, domain.PendingPriceChange{Change: change, ServiceIDs: ids})
+	}
+	return pending, nil
+}
+
+// bookingStaff returns the staff member the appointment would be booked with, checking that
+// they work at the business
+func (s *appointmentQuoteServiceImpl) bookingStaff(ctx context.Context, businessID, staffID string) (string, error) {
+	staffID, err := resolveBookingStaff(ctx, s.businessRepo, s.staffRepo, businessID, staffID)
+	if err != nil {
+		return "", err
+	}
+	staff, err := s.staffRepo.GetByID(ctx, staffID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", NewServiceError("failed to retrieve staff member", err)
+	}
+	if staff == nil || staff.BusinessID != businessID {
+		return "", NewNotFoundError("staff", "id", staffID)
+	}
+	if !staff.IsActive {
+		return "", validation.NewValidationError("the staff member is not active")
+	}
+	return staffID, nil
+}
+
+// requireBooking checks that the caller may book appointments at the business
+func (s *appointmentQuoteServiceImpl) requireBooking(ctx context.Context, businessID, action string) error {
+	userID := GetUserIDFromContext(ctx)
+	if userID == nil {
+		return NewForbiddenError(action)
+	}
+	staff, err := s.staffRepo.FindByBusinessAndUser(ctx, businessID, *userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewForbiddenError(action)
+		}
+		return NewServiceError("failed to retrieve staff member", err)
+	}
+	if !staff.Can(domain.PermissionAppointmentsCreate) {
+		return NewForbiddenError(action)
+	}
+	return nil
+}
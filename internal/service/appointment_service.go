@@ -0,0 +1,519 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// AppointmentService defines the service interface for booking appointments
+type AppointmentService interface {
+	CheckAvailability(ctx context.Context, checkDTO dto.CheckAvailabilityDTO) (*dto.AvailabilityResultDTO, error)
+	CreateAppointment(ctx context.Context, createDTO dto.CreateAppointmentDTO) (*dto.AppointmentResponseDTO, error)
+	RescheduleAppointment(ctx context.Context, id string, rescheduleDTO dto.RescheduleAppointmentDTO) (*dto.AppointmentResponseDTO, error)
+	AcceptAppointment(ctx context.Context, id string) (*dto.AppointmentResponseDTO, error)
+	CancelAppointment(ctx context.Context, id string, cancelDTO dto.CancelAppointmentDTO) (*dto.AppointmentResponseDTO, error)
+	MarkNoShow(ctx context.Context, id string) (*dto.AppointmentResponseDTO, error)
+	BookBundle(ctx context.Context, bookDTO dto.BookBundleDTO) (*dto.AppointmentResponseDTO, error)
+}
+
+// appointmentServiceImpl implements the AppointmentService interface
+type appointmentServiceImpl struct {
+	appointmentRepo domain.AppointmentRepository
+	businessRepo    domain.BusinessRepository
+	staffRepo       domain.StaffRepository
+	clientRepo      domain.BaseRepository[domain.Client]
+	bundleRepo      domain.ServiceBundleRepository
+	quoteRepo       domain.AppointmentQuoteRepository
+	settingsRepo    domain.BusinessSettingsRepository
+	eventService    EventService
+	validator       *validator.Validate
+}
+
+// NewAppointmentService creates a new appointment service
+func NewAppointmentService(
+	appointmentRepo domain.AppointmentRepository,
+	businessRepo domain.BusinessRepository,
+	staffRepo domain.StaffRepository,
+	clientRepo domain.BaseRepository[domain.Client],
+	bundleRepo domain.ServiceBundleRepository,
+	quoteRepo domain.AppointmentQuoteRepository,
+	settingsRepo domain.BusinessSettingsRepository,
+	eventService EventService,
+	validator *validator.Validate,
+) AppointmentService {
+	return &appointmentServiceImpl{
+		appointmentRepo: appointmentRepo,
+		businessRepo:    businessRepo,
+		staffRepo:       staffRepo,
+		clientRepo:      clientRepo,
+		bundleRepo:      bundleRepo,
+		quoteRepo:       quoteRepo,
+		settingsRepo:    settingsRepo,
+		eventService:    eventService,
+		validator:       validator,
+	}
+}
+
+// CheckAvailability reports whether a staff member can take an appointment at a time, and if
+// not, every reason why. Solo businesses need not name the staff member.
+func (s *appointmentServiceImpl) CheckAvailability(ctx context.Context, checkDTO dto.CheckAvailabilityDTO) (*dto.AvailabilityResultDTO, error) {
+	if err := s.validator.Struct(checkDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	staffID, err := s.bookingStaff(ctx, checkDTO.BusinessID, checkDTO.StaffID)
+	if err != nil {
+		return nil, err
+	}
+
+	conflicts, err := s.appointmentRepo.CheckAvailability(ctx, domain.AvailabilityRequest{
+		BusinessID:           checkDTO.BusinessID,
+		StaffID:              staffID,
+		Start:                checkDTO.StartTime,
+		End:                  checkDTO.EndTime,
+		ExcludeAppointmentID: checkDTO.ExcludeAppointmentID,
+		ServiceIDs:           checkDTO.ServiceIDs,
+	})
+	if err != nil {
+		return nil, NewServiceError("failed to check availability", err)
+	}
+	return dto.ToAvailabilityResultDTO(conflicts), nil
+}
+
+// CreateAppointment books an appointment, rejecting it when the staff member is not available
+// or the client is a minor without a consenting guardian. Solo businesses book with their sole
+// staff member when none is named. Booking with a quote's token records the quoted services at
+// their quoted prices, as long as the booking matches the quote and it has not expired.
+func (s *appointmentServiceImpl) CreateAppointment(ctx context.Context, createDTO dto.CreateAppointmentDTO) (*dto.AppointmentResponseDTO, error) {
+	if err := s.validator.Struct(createDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	staffID, err := s.bookingStaff(ctx, createDTO.BusinessID, createDTO.StaffID)
+	if err != nil {
+		return nil, err
+	}
+	client, err := s.getClientOfBusiness(ctx, createDTO.BusinessID, createDTO.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.CheckBookingAge(nil, createDTO.StartTime); err != nil {
+		return nil, toValidationError(err)
+	}
+
+	appointment := &domain.Appointment{
+		BusinessID: createDTO.BusinessID,
+		ClientID:   createDTO.ClientID,
+		StaffID:    staffID,
+		StartTime:  createDTO.StartTime,
+		EndTime:    createDTO.EndTime,
+		Status:     domain.AppointmentStatusScheduled,
+		Notes:      createDTO.Notes,
+	}
+	if err := appointment.Validate(); err != nil {
+		return nil, toValidationError(err)
+	}
+
+	userID := GetUserIDFromContext(ctx)
+	appointment.SetAuditFields(userID)
+	var quote *domain.AppointmentQuote
+	var lines []*domain.AppointmentLine
+	if createDTO.QuoteToken != nil {
+		if quote, lines, err = s.getQuote(ctx, *createDTO.QuoteToken, appointment); err != nil {
+			return nil, err
+		}
+		appointment.EstimatedPrice = &quote.Total
+		for _, line := range lines {
+			line.SetAuditFields(userID)
+		}
+	}
+	err = s.transact(ctx, func(repo domain.AppointmentRepository, tx *gorm.DB) error {
+		if quote == nil {
+			if err := repo.Create(ctx, appointment); err != nil {
+				return err
+			}
+		} else {
+			if err := repo.CreateWithLines(ctx, appointment, lines); err != nil {
+				return err
+			}
+			if err := s.quoteRepo.MarkUsed(ctx, tx, quote.ID, appointment.ID); err != nil {
+				return err
+			}
+		}
+		return s.record(ctx, tx, appointment, domain.EventAppointmentCreated)
+	})
+	if errors.Is(err, domain.ErrQuoteUsed) {
+		return nil, validation.FromError(err)
+	}
+	if err != nil {
+		return nil, toAppointmentError("failed to create appointment", err)
+	}
+
+	return dto.ToAppointmentResponseDTO(appointment), nil
+}
+
+// RescheduleAppointment moves an appointment to another time, and optionally another staff member
+func (s *appointmentServiceImpl) RescheduleAppointment(ctx context.Context, id string, rescheduleDTO dto.RescheduleAppointmentDTO) (*dto.AppointmentResponseDTO, error) {
+	if err := s.validator.Struct(rescheduleDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+
+	appointment, err := s.appointmentRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("appointment", "id", id)
+		}
+		return nil, NewServiceError("failed to retrieve appointment", err)
+	}
+	if err := appointment.CheckVersion(appointment.TableName(), rescheduleDTO.ExpectedVersion); err != nil {
+		return nil, err
+	}
+	if !appointment.CanBeCancelled() {
+		return nil, validation.NewValidationError("only scheduled or confirmed appointments can be rescheduled")
+	}
+
+	if rescheduleDTO.StaffID != nil && *rescheduleDTO.StaffID != appointment.StaffID {
+		if err := s.ensureStaffOfBusiness(ctx, appointment.BusinessID, *rescheduleDTO.StaffID); err != nil {
+			return nil, err
+		}
+		appointment.StaffID = *rescheduleDTO.StaffID
+	}
+	appointment.StartTime = rescheduleDTO.StartTime
+	appointment.EndTime = rescheduleDTO.EndTime
+	// The client confirmed the old time, not the new one
+	appointment.ClientConfirmed = false
+
+	appointment.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.update(ctx, appointment, domain.EventAppointmentUpdated); err != nil {
+		return nil, toAppointmentError("failed to reschedule appointment", err)
+	}
+
+	return dto.ToAppointmentResponseDTO(appointment), nil
+}
+
+// AcceptAppointment accepts an appointment a client requested online
+func (s *appointmentServiceImpl) AcceptAppointment(ctx context.Context, id string) (*dto.AppointmentResponseDTO, error) {
+	appointment, err := s.appointmentRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("appointment", "id", id)
+		}
+		return nil, NewServiceError("failed to retrieve appointment", err)
+	}
+	if err := appointment.Accept(); err != nil {
+		return nil, toValidationError(err)
+	}
+
+	appointment.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.update(ctx, appointment, domain.EventAppointmentUpdated); err != nil {
+		return nil, toAppointmentError("failed to accept appointment", err)
+	}
+
+	return dto.ToAppointmentResponseDTO(appointment), nil
+}
+
+// CancelAppointment cancels an appointment that has not taken place yet. Cancelling with less
+// notice than the business's cancellation policy asks for counts as a no-show: the no-show fee
+// is charged and the client's no-show counter goes up, which may flag them as needing a deposit.
+// Owners and managers can waive the policy.
+func (s *appointmentServiceImpl) CancelAppointment(ctx context.Context, id string, cancelDTO dto.CancelAppointmentDTO) (*dto.AppointmentResponseDTO, error) {
+	if err := s.validator.Struct(cancelDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+
+	appointment, err := s.appointmentRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("appointment", "id", id)
+		}
+		return nil, NewServiceError("failed to retrieve appointment", err)
+	}
+	if err := appointment.CheckVersion(appointment.TableName(), cancelDTO.ExpectedVersion); err != nil {
+		return nil, err
+	}
+	if !appointment.CanBeCancelled() {
+		return nil, validation.NewValidationError("only pending, scheduled or confirmed appointments can be cancelled")
+	}
+	if cancelDTO.WaiveNoShowPolicy {
+		if err := requireManager(ctx, s.staffRepo, appointment.BusinessID, "waive the cancellation policy"); err != nil {
+			return nil, err
+		}
+	}
+	policy, err := s.cancellationPolicy(ctx, appointment.BusinessID)
+	if err != nil {
+		return nil, err
+	}
+
+	late := !cancelDTO.WaiveNoShowPolicy && policy.IsLateCancellation(appointment, time.Now())
+	if late {
+		appointment.MarkLateCancellation(cancelDTO.Reason, policy)
+	} else {
+		appointment.MarkCancelled(cancelDTO.Reason)
+	}
+	appointment.SetAuditFields(GetUserIDFromContext(ctx))
+	err = s.transact(ctx, func(repo domain.AppointmentRepository, tx *gorm.DB) error {
+		if err := repo.Update(ctx, appointment); err != nil {
+			return err
+		}
+		if late {
+			if err := s.recordNoShow(ctx, tx, appointment, policy); err != nil {
+				return err
+			}
+		}
+		return s.record(ctx, tx, appointment, domain.EventAppointmentCancelled)
+	})
+	if err != nil {
+		return nil, toAppointmentError("failed to cancel appointment", err)
+	}
+
+	return dto.ToAppointmentResponseDTO(appointment), nil
+}
+
+// MarkNoShow records that the client of an appointment that has started did not turn up. The
+// business's no-show fee is charged and the client's no-show counter goes up, flagging them as
+// needing a deposit to book once they reach the business's limit.
+func (s *appointmentServiceImpl) MarkNoShow(ctx context.Context, id string) (*dto.AppointmentResponseDTO, error) {
+	appointment, err := s.appointmentRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("appointment", "id", id)
+		}
+		return nil, NewServiceError("failed to retrieve appointment", err)
+	}
+	policy, err := s.cancellationPolicy(ctx, appointment.BusinessID)
+	if err != nil {
+		return nil, err
+	}
+	if err := appointment.MarkNoShow(policy, time.Now()); err != nil {
+		return nil, toValidationError(err)
+	}
+
+	appointment.SetAuditFields(GetUserIDFromContext(ctx))
+	err = s.transact(ctx, func(repo domain.AppointmentRepository, tx *gorm.DB) error {
+		if err := repo.Update(ctx, appointment); err != nil {
+			return err
+		}
+		if err := s.recordNoShow(ctx, tx, appointment, policy); err != nil {
+			return err
+		}
+		return s.record(ctx, tx, appointment, domain.EventAppointmentUpdated)
+	})
+	if err != nil {
+		return nil, toAppointmentError("failed to mark appointment as a no-show", err)
+	}
+
+	return dto.ToAppointmentResponseDTO(appointment), nil
+}
+
+// BookBundle books the services of a bundle as a single appointment. The appointment lasts the
+// bundle's optimized duration and each service is recorded with its share of the bundle price.
+// Clients too young for an age-restricted service of the bundle are turned away.
+func (s *appointmentServiceImpl) BookBundle(ctx context.Context, bookDTO dto.BookBundleDTO) (*dto.AppointmentResponseDTO, error) {
+	if err := s.validator.Struct(bookDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+
+	bundle, err := s.bundleRepo.GetByID(ctx, bookDTO.BundleID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, NewServiceError("failed to retrieve service bundle", err)
+	}
+	if bundle == nil || bundle.BusinessID != bookDTO.BusinessID {
+		return nil, NewNotFoundError("service bundle", "id", bookDTO.BundleID)
+	}
+	if !bundle.IsActive {
+		return nil, validation.NewValidationError("the bundle is not available for booking")
+	}
+	services, err := s.bundleRepo.FindServices(ctx, bundle.BusinessID, bundle.ServiceIDs())
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve bundle services", err)
+	}
+	plan, err := bundle.Plan(services)
+	if err != nil {
+		return nil, toValidationError(err)
+	}
+
+	staffID, err := s.bookingStaff(ctx, bookDTO.BusinessID, bookDTO.StaffID)
+	if err != nil {
+		return nil, err
+	}
+	client, err := s.getClientOfBusiness(ctx, bookDTO.BusinessID, bookDTO.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	bookedServices := make([]*domain.Service, 0, len(services))
+	for _, serviceID := range bundle.ServiceIDs() {
+		bookedServices = append(bookedServices, services[serviceID])
+	}
+	if err := client.CheckBookingAge(bookedServices, bookDTO.StartTime); err != nil {
+		return nil, toValidationError(err)
+	}
+
+	appointment := &domain.Appointment{
+		BusinessID:     bookDTO.BusinessID,
+		ClientID:       bookDTO.ClientID,
+		StaffID:        staffID,
+		StartTime:      bookDTO.StartTime,
+		EndTime:        bookDTO.StartTime.Add(time.Duration(plan.Duration) * time.Minute),
+		Status:         domain.AppointmentStatusScheduled,
+		Notes:          bookDTO.Notes,
+		BundleID:       &bundle.ID,
+		EstimatedPrice: &plan.Price,
+	}
+	if err := appointment.Validate(); err != nil {
+		return nil, toValidationError(err)
+	}
+
+	userID := GetUserIDFromContext(ctx)
+	appointment.SetAuditFields(userID)
+	lines := make([]*domain.AppointmentLine, len(plan.Steps))
+	for i, step := range plan.Steps {
+		lines[i] = &domain.AppointmentLine{
+			ServiceID: step.ServiceID,
+			StaffID:   staffID,
+			Duration:  step.Duration,
+			Price:     step.Price,
+		}
+		lines[i].SetAuditFields(userID)
+	}
+	err = s.transact(ctx, func(repo domain.AppointmentRepository, tx *gorm.DB) error {
+		if err := repo.CreateWithLines(ctx, appointment, lines); err != nil {
+			return err
+		}
+		return s.record(ctx, tx, appointment, domain.EventAppointmentCreated)
+	})
+	if err != nil {
+		return nil, toAppointmentError("failed to book service bundle", err)
+	}
+
+	return dto.ToAppointmentResponseDTO(appointment), nil
+}
+
+// getQuote retrieves the quote an appointment is booked with, checking that it can be used for
+// the appointment, and the services it quoted
+func (s *appointmentServiceImpl) getQuote(ctx context.Context, token string, appointment *domain.Appointment) (*domain.AppointmentQuote, []*domain.AppointmentLine, error) {
+	quote, err := s.quoteRepo.FindByToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, NewNotFoundError("quote", "token", token)
+		}
+		return nil, nil, NewServiceError("failed to retrieve quote", err)
+	}
+	if err := quote.CheckBooking(appointment, time.Now()); err != nil {
+		return nil, nil, validation.FromError(err)
+	}
+	lines, err := quote.AppointmentLines()
+	if err != nil {
+		return nil, nil, NewServiceError("failed to read quote", err)
+	}
+	return quote, lines, nil
+}
+
+// cancellationPolicy returns the cancellation policy of a business
+func (s *appointmentServiceImpl) cancellationPolicy(ctx context.Context, businessID string) (domain.CancellationPolicy, error) {
+	settings, err := s.settingsRepo.GetByBusinessID(ctx, businessID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.CancellationPolicy{}, NewServiceError("failed to retrieve business settings", err)
+	}
+	return settings.GetCancellationPolicy(), nil
+}
+
+// recordNoShow counts a no-show or late cancellation against the appointment's client within
+// the transaction of the change
+func (s *appointmentServiceImpl) recordNoShow(ctx context.Context, tx *gorm.DB, appointment *domain.Appointment, policy domain.CancellationPolicy) error {
+	clients := s.clientRepo.WithTx(tx)
+	client, err := clients.GetByID(ctx, appointment.ClientID)
+	if err != nil {
+		return err
+	}
+	if client.RecordNoShow(policy) {
+		log.Info().Str("business_id", appointment.BusinessID).Str("client_id", client.ID).Int("no_shows", client.NoShowCount).
+			Msg("Client flagged as needing a deposit after repeated no-shows")
+	}
+	client.SetAuditFields(GetUserIDFromContext(ctx))
+	return clients.Update(ctx, client)
+}
+
+// getClientOfBusiness retrieves a client, checking that it belongs to the business
+func (s *appointmentServiceImpl) getClientOfBusiness(ctx context.Context, businessID, clientID string) (*domain.Client, error) {
+	client, err := s.clientRepo.GetByID(ctx, clientID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, NewServiceError("failed to retrieve client", err)
+	}
+	if client == nil || client.BusinessID != businessID {
+		return nil, NewNotFoundError("client", "id", clientID)
+	}
+	return client, nil
+}
+
+// bookingStaff returns the staff member a booking is made with, checking that they work at
+// the business
+func (s *appointmentServiceImpl) bookingStaff(ctx context.Context, businessID, staffID string) (string, error) {
+	staffID, err := resolveBookingStaff(ctx, s.businessRepo, s.staffRepo, businessID, staffID)
+	if err != nil {
+		return "", err
+	}
+	return staffID, s.ensureStaffOfBusiness(ctx, businessID, staffID)
+}
+
+// ensureStaffOfBusiness checks that the staff member works at the business
+func (s *appointmentServiceImpl) ensureStaffOfBusiness(ctx context.Context, businessID, staffID string) error {
+	staff, err := s.staffRepo.GetByID(ctx, staffID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return NewServiceError("failed to retrieve staff member", err)
+	}
+	if staff == nil || staff.BusinessID != businessID {
+		return NewNotFoundError("staff", "id", staffID)
+	}
+	if !staff.IsActive {
+		return validation.NewValidationError("the staff member is not active")
+	}
+	return nil
+}
+
+// update saves an appointment together with the event recording the change
+func (s *appointmentServiceImpl) update(ctx context.Context, appointment *domain.Appointment, eventType string) error {
+	return s.transact(ctx, func(repo domain.AppointmentRepository, tx *gorm.DB) error {
+		if err := repo.Update(ctx, appointment); err != nil {
+			return err
+		}
+		return s.record(ctx, tx, appointment, eventType)
+	})
+}
+
+// transact runs fn in a transaction, with the appointment repository bound to it
+func (s *appointmentServiceImpl) transact(ctx context.Context, fn func(repo domain.AppointmentRepository, tx *gorm.DB) error) error {
+	return s.appointmentRepo.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		repo, ok := s.appointmentRepo.WithTx(tx).(domain.AppointmentRepository)
+		if !ok {
+			return errors.New("the appointment repository cannot join transactions")
+		}
+		return fn(repo, tx)
+	})
+}
+
+// record records an appointment event in the outbox within the transaction of the change, so
+// that read models pick up the change once it is committed
+func (s *appointmentServiceImpl) record(ctx context.Context, tx *gorm.DB, appointment *domain.Appointment, eventType string) error {
+	event, err := domain.NewDomainEvent(domain.AggregateAppointment, appointment.ID, eventType, &appointment.BusinessID,
+		map[string]any{"staff_id": appointment.StaffID, "client_id": appointment.ClientID, "status": appointment.Status,
+			"start_time": appointment.StartTime, "end_time": appointment.EndTime, "cancellation_reason": appointment.CancellationReason})
+	if err != nil {
+		return err
+	}
+	return s.eventService.Record(ctx, tx, event)
+}
+
+// toAppointmentError turns a schedule conflict into a validation error listing its reasons
+func toAppointmentError(message string, err error) error {
+	var conflict *domain.AppointmentConflictError
+	if errors.As(err, &conflict) {
+		return validation.FromError(conflict)
+	}
+	return NewServiceError(message, err)
+}
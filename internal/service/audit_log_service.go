@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// AuditLogService defines the service interface for the record of who changed what in a business
+type AuditLogService interface {
+	ListAuditLog(ctx context.Context, filterDTO dto.AuditLogFilterDTO, page, pageSize int) ([]*dto.AuditLogResponseDTO, int64, error)
+}
+
+// auditLogServiceImpl implements the AuditLogService interface
+type auditLogServiceImpl struct {
+	auditLogRepo domain.AuditLogRepository
+	staffRepo    domain.StaffRepository
+	validator    *validator.Validate
+}
+
+// NewAuditLogService creates a new audit log service
+func NewAuditLogService(auditLogRepo domain.AuditLogRepository, staffRepo domain.StaffRepository, validator *validator.Validate) AuditLogService {
+	return &auditLogServiceImpl{
+		auditLogRepo: auditLogRepo,
+		staffRepo:    staffRepo,
+		validator:    validator,
+	}
+}
+
+// ListAuditLog browses the changes made to a business's records, most recent first. The log
+// shows what every member of staff did, so only the owner may read it.
+func (s *auditLogServiceImpl) ListAuditLog(ctx context.Context, filterDTO dto.AuditLogFilterDTO, page, pageSize int) ([]*dto.AuditLogResponseDTO, int64, error) {
+	if err := s.validator.Struct(filterDTO); err != nil {
+		return nil, 0, validation.FromError(err)
+	}
+	if filterDTO.StartDate != nil && filterDTO.EndDate != nil && !filterDTO.EndDate.After(*filterDTO.StartDate) {
+		return nil, 0, validation.NewValidationError("end_date must be after start_date")
+	}
+	if err := s.ensureOwner(ctx, filterDTO.BusinessID); err != nil {
+		return nil, 0, err
+	}
+
+	pagination := ValidatePagination(&dto.PaginationRequest{Page: page, PageSize: pageSize})
+	entries, total, err := s.auditLogRepo.Search(ctx, dto.ToAuditLogFilter(filterDTO), pagination.Page, pagination.PageSize)
+	if err != nil {
+		return nil, 0, NewServiceError("failed to search the audit log", err)
+	}
+
+	return dto.ToAuditLogResponseDTOs(entries), total, nil
+}
+
+// ensureOwner checks that the caller owns the business
+func (s *auditLogServiceImpl) ensureOwner(ctx context.Context, businessID string) error {
+	userID := GetUserIDFromContext(ctx)
+	if userID == nil {
+		return NewForbiddenError("read the audit log")
+	}
+	staff, err := s.staffRepo.FindByBusinessAndUser(ctx, businessID, *userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewForbiddenError("read the audit log")
+		}
+		return NewServiceError("failed to retrieve staff member", err)
+	}
+	if !staff.IsOwner() {
+		return NewForbiddenError("read the audit log")
+	}
+	return nil
+}
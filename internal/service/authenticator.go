@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"gorm.io/gorm"
+)
+
+// SessionTokenVerifier verifies the session tokens users sign in with, returning an error
+// wrapping domain.ErrUnauthenticated for tokens that cannot be trusted
+type SessionTokenVerifier interface {
+	VerifySessionToken(ctx context.Context, token string) (*dto.ClerkSessionClaimsDTO, error)
+}
+
+// Credentials are what a request identifies its caller with
+type Credentials struct {
+	Token      string // The bearer session token; empty for anonymous requests
+	BusinessID string // The business the caller chose to act for, among those they work at
+	UserAgent  string
+}
+
+// Authenticator establishes who a request is made by and the business it acts for
+type Authenticator interface {
+	Authenticate(ctx context.Context, credentials Credentials) (context.Context, error)
+}
+
+// authenticatorImpl implements the Authenticator interface
+type authenticatorImpl struct {
+	verifier       SessionTokenVerifier
+	userRepo       domain.UserRepository
+	staffRepo      domain.StaffRepository
+	platformAdmins map[string]bool
+}
+
+// NewAuthenticator creates a new authenticator. Platform admins are named by their Clerk user
+// IDs.
+func NewAuthenticator(
+	verifier SessionTokenVerifier,
+	userRepo domain.UserRepository,
+	staffRepo domain.StaffRepository,
+	platformAdmins []string,
+) Authenticator {
+	admins := make(map[string]bool, len(platformAdmins))
+	for _, clerkID := range platformAdmins {
+		admins[clerkID] = true
+	}
+	return &authenticatorImpl{
+		verifier:       verifier,
+		userRepo:       userRepo,
+		staffRepo:      staffRepo,
+		platformAdmins: admins,
+	}
+}
+
+// Authenticate verifies the caller's session token and scopes the context to them: the session
+// for the revocation check, the user, and as the tenant the business they act for. That is the
+// business chosen in the credentials, or the only one they work at. Anonymous callers, users
+// who have not registered and users working at several businesses who chose none act for no
+// business, so scoped data stays out of their reach. Invalid tokens are refused with
+// domain.ErrUnauthenticated, and a business the user does not work at with
+// domain.ErrCrossTenant.
+func (a *authenticatorImpl) Authenticate(ctx context.Context, credentials Credentials) (context.Context, error) {
+	if credentials.Token == "" {
+		return domain.WithTenant(ctx, domain.TenantContext{}), nil
+	}
+	claims, err := a.verifier.VerifySessionToken(ctx, credentials.Token)
+	if err != nil {
+		if errors.Is(err, domain.ErrUnauthenticated) {
+			return ctx, err
+		}
+		return ctx, fmt.Errorf("failed to verify session token: %w", err)
+	}
+	ctx = SetSessionContext(ctx, CallerSession{
+		Kind:       domain.SessionKindClerk,
+		ExternalID: claims.SessionID,
+		IssuedAt:   claims.IssuedAt,
+		UserAgent:  credentials.UserAgent,
+	})
+	ctx = context.WithValue(ctx, ClerkUserKey, claims.UserID)
+
+	user, err := a.userRepo.FindByClerkID(ctx, claims.UserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.WithTenant(ctx, domain.TenantContext{}), nil
+	}
+	if err != nil {
+		return ctx, fmt.Errorf("failed to retrieve user: %w", err)
+	}
+	if a.platformAdmins[claims.UserID] {
+		return SetUserContext(ctx, user.ID, PlatformAdminRole, nil), nil
+	}
+
+	positions, err := a.staffRepo.FindByUserID(ctx, user.ID)
+	if err != nil {
+		return ctx, fmt.Errorf("failed to retrieve staff positions: %w", err)
+	}
+	var active []*domain.Staff
+	for _, position := range positions {
+		if position.IsActive {
+			active = append(active, position)
+		}
+	}
+	switch {
+	case credentials.BusinessID != "":
+		for _, position := range active {
+			if position.BusinessID == credentials.BusinessID {
+				return SetUserContext(ctx, user.ID, string(position.Role), &position.BusinessID), nil
+			}
+		}
+		return ctx, domain.ErrCrossTenant
+	case len(active) == 1:
+		return SetUserContext(ctx, user.ID, string(active[0].Role), &active[0].BusinessID), nil
+	}
+	return SetUserContext(ctx, user.ID, "", nil), nil
+}
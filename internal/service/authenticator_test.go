@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/repository/memory"
+)
+
+// tokenVerifierFunc adapts a function to a SessionTokenVerifier
+type tokenVerifierFunc func(ctx context.Context, token string) (*dto.ClerkSessionClaimsDTO, error)
+
+func (f tokenVerifierFunc) VerifySessionToken(ctx context.Context, token string) (*dto.ClerkSessionClaimsDTO, error) {
+	return f(ctx, token)
+}
+
+func TestAuthenticator_Authenticate(t *testing.T) {
+	ctx := context.Background()
+	db := memory.NewDB()
+	users := memory.NewUserRepository(db)
+	staff := memory.NewStaffRepository(db)
+
+	newUser := func(clerkID string) *domain.User {
+		user := &domain.User{ClerkID: &clerkID, Email: clerkID + "@example.com", FirstName: "Ana", LastName: "Silva"}
+		require.NoError(t, users.Create(ctx, user))
+		return user
+	}
+	owner, stylist, admin := newUser("user_owner"), newUser("user_stylist"), newUser("user_admin")
+	require.NoError(t, staff.Create(ctx, &domain.Staff{BusinessID: "business-1", UserID: owner.ID, Role: domain.BusinessRoleOwner, IsActive: true}))
+	require.NoError(t, staff.Create(ctx, &domain.Staff{BusinessID: "business-1", UserID: stylist.ID, Role: domain.BusinessRoleEmployee, IsActive: true}))
+	require.NoError(t, staff.Create(ctx, &domain.Staff{BusinessID: "business-2", UserID: stylist.ID, Role: domain.BusinessRoleEmployee, IsActive: true}))
+
+	issuedAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	authenticator := NewAuthenticator(tokenVerifierFunc(func(ctx context.Context, token string) (*dto.ClerkSessionClaimsDTO, error) {
+		if token == "forged" {
+			return nil, domain.ErrUnauthenticated
+		}
+		return &dto.ClerkSessionClaimsDTO{UserID: token, SessionID: "sess_" + token, IssuedAt: issuedAt}, nil
+	}), users, staff, []string{"user_admin"})
+
+	anonymous, err := authenticator.Authenticate(ctx, Credentials{})
+	require.NoError(t, err)
+	tenant, ok := domain.TenantFromContext(anonymous)
+	assert.True(t, ok && tenant.BusinessID == "", "anonymous callers act for no business")
+
+	_, err = authenticator.Authenticate(ctx, Credentials{Token: "forged"})
+	assert.ErrorIs(t, err, domain.ErrUnauthenticated)
+
+	signedIn, err := authenticator.Authenticate(ctx, Credentials{Token: "user_owner", UserAgent: "Safari"})
+	require.NoError(t, err)
+	assert.Equal(t, owner.ID, *GetUserIDFromContext(signedIn))
+	assert.Equal(t, "business-1", *GetBusinessIDFromContext(signedIn), "users working at one business act for it")
+	assert.Equal(t, &CallerSession{Kind: domain.SessionKindClerk, ExternalID: "sess_user_owner", IssuedAt: issuedAt, UserAgent: "Safari"},
+		GetSessionFromContext(signedIn))
+
+	undecided, err := authenticator.Authenticate(ctx, Credentials{Token: "user_stylist"})
+	require.NoError(t, err)
+	tenant, ok = domain.TenantFromContext(undecided)
+	assert.True(t, ok && tenant.BusinessID == "", "users working at several businesses choose one")
+
+	chosen, err := authenticator.Authenticate(ctx, Credentials{Token: "user_stylist", BusinessID: "business-2"})
+	require.NoError(t, err)
+	tenant, _ = domain.TenantFromContext(chosen)
+	assert.Equal(t, "business-2", tenant.BusinessID)
+
+	_, err = authenticator.Authenticate(ctx, Credentials{Token: "user_owner", BusinessID: "business-2"})
+	assert.ErrorIs(t, err, domain.ErrCrossTenant)
+
+	platform, err := authenticator.Authenticate(ctx, Credentials{Token: "user_admin"})
+	require.NoError(t, err)
+	assert.True(t, IsPlatformAdmin(platform))
+	assert.Equal(t, admin.ID, *GetUserIDFromContext(platform))
+	_, ok = domain.TenantFromContext(platform)
+	assert.False(t, ok, "platform admins are not scoped to a business")
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// AvailabilityService defines the service interface for computing bookable time slots
+type AvailabilityService interface {
+	GetAvailableSlots(ctx context.Context, query dto.AvailableSlotsQueryDTO) ([]*dto.AvailableSlotDTO, error)
+	CheckBookingWindow(ctx context.Context, service *domain.Service, start time.Time) error
+}
+
+// availabilityServiceImpl implements the AvailabilityService interface
+type availabilityServiceImpl struct {
+	businessRepo         domain.BusinessRepository
+	businessSettingsRepo domain.BusinessSettingsRepository
+	serviceRepo          domain.BaseRepository[domain.Service]
+	staffRepo            domain.StaffRepository
+	appointmentRepo      domain.AppointmentRepository
+	validator            *validator.Validate
+}
+
+// NewAvailabilityService creates a new availability service
+func NewAvailabilityService(
+	businessRepo domain.BusinessRepository,
+	businessSettingsRepo domain.BusinessSettingsRepository,
+	serviceRepo domain.BaseRepository[domain.Service],
+	staffRepo domain.StaffRepository,
+	appointmentRepo domain.AppointmentRepository,
+	validator *validator.Validate,
+) AvailabilityService {
+	return &availabilityServiceImpl{
+		businessRepo:         businessRepo,
+		businessSettingsRepo: businessSettingsRepo,
+		serviceRepo:          serviceRepo,
+		staffRepo:            staffRepo,
+		appointmentRepo:      appointmentRepo,
+		validator:            validator,
+	}
+}
+
+// GetAvailableSlots returns the times between two local dates, inclusive, at which a staff
+// member, or any active staff member when none is given, can take the service. A slot is free
+// when it falls within the business's working hours, clears the staff member's appointments by
+// the business's buffer, is not blocked by time off, and lies within the service's booking
+// window. Slots are ordered by start time.
+func (s *availabilityServiceImpl) GetAvailableSlots(ctx context.Context, query dto.AvailableSlotsQueryDTO) ([]*dto.AvailableSlotDTO, error) {
+	if err := s.validator.Struct(query); err != nil {
+		return nil, validation.FromError(err)
+	}
+	ctx, err := actFor(ctx, query.BusinessID, "view availability")
+	if err != nil {
+		return nil, err
+	}
+
+	business, err := s.businessRepo.GetByID(ctx, query.BusinessID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, NewServiceError("failed to retrieve business", err)
+	}
+	if business == nil || !business.IsActive {
+		return nil, NewNotFoundError("business", "id", query.BusinessID)
+	}
+	service, err := s.serviceRepo.GetByID(ctx, query.ServiceID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, NewServiceError("failed to retrieve service", err)
+	}
+	if service == nil || service.BusinessID != query.BusinessID || !service.IsActive {
+		return nil, NewNotFoundError("service", "id", query.ServiceID)
+	}
+	settings, err := s.getSettings(ctx, business.ID)
+	if err != nil {
+		return nil, err
+	}
+	rules := domain.NewAvailabilityRules(business, settings)
+	window := domain.NewBookingWindow(settings, service)
+
+	start, err := domain.ParseBookingDate(query.StartDate, rules.Location)
+	if err != nil {
+		return nil, toValidationError(err)
+	}
+	last, err := domain.ParseBookingDate(query.EndDate, rules.Location)
+	if err != nil {
+		return nil, toValidationError(err)
+	}
+	if last.Before(start) {
+		return nil, validation.NewValidationError("end_date must not be before start_date")
+	}
+	end := last.AddDate(0, 0, 1)
+	if end.After(start.AddDate(0, 0, domain.MaxSlotSearchDays)) {
+		return nil, validation.NewValidationError(fmt.Sprintf("date range cannot exceed %d days", domain.MaxSlotSearchDays))
+	}
+
+	staffIDs, err := s.staffIDs(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(staffIDs) == 0 {
+		return []*dto.AvailableSlotDTO{}, nil
+	}
+
+	// Appointments just outside the range still push their buffer into it
+	schedules, err := s.appointmentRepo.FindSchedules(ctx, staffIDs, start.Add(-rules.Buffer), end.Add(rules.Buffer))
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve staff schedules", err)
+	}
+
+	now := time.Now()
+	search := domain.SlotSearch{
+		Start:     start,
+		End:       end,
+		Duration:  time.Duration(service.Duration) * time.Minute,
+		Interval:  domain.SlotInterval,
+		NotBefore: window.Earliest(now),
+		NotAfter:  window.Latest(now),
+	}
+	slots := []*dto.AvailableSlotDTO{}
+	for _, schedule := range schedules {
+		for _, slot := range rules.FreeSlots(schedule, search) {
+			slots = append(slots, &dto.AvailableSlotDTO{
+				StaffID:   schedule.StaffID,
+				StartTime: slot,
+				EndTime:   slot.Add(search.Duration),
+			})
+		}
+	}
+	// Slots at the same time stay in the order of the staff members
+	slices.SortStableFunc(slots, func(a, b *dto.AvailableSlotDTO) int {
+		return a.StartTime.Compare(b.StartTime)
+	})
+	return slots, nil
+}
+
+// CheckBookingWindow checks that the service may be booked now for a time, given how far ahead
+// the service and its business take bookings. The error explains the limit to the client.
+func (s *availabilityServiceImpl) CheckBookingWindow(ctx context.Context, service *domain.Service, start time.Time) error {
+	settings, err := s.getSettings(ctx, service.BusinessID)
+	if err != nil {
+		return err
+	}
+	if err := domain.NewBookingWindow(settings, service).Check(start, time.Now()); err != nil {
+		return toValidationError(err)
+	}
+	return nil
+}
+
+// getSettings retrieves the settings of a business, or nil when it has none
+func (s *availabilityServiceImpl) getSettings(ctx context.Context, businessID string) (*domain.BusinessSettings, error) {
+	settings, err := s.businessSettingsRepo.GetByBusinessID(ctx, businessID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, NewServiceError("failed to retrieve business settings", err)
+	}
+	return settings, nil
+}
+
+// staffIDs returns the staff member of the query when it names an active one of the business,
+// or else all active staff members of the business
+func (s *availabilityServiceImpl) staffIDs(ctx context.Context, query dto.AvailableSlotsQueryDTO) ([]string, error) {
+	if query.StaffID != nil {
+		staff, err := s.staffRepo.GetByID(ctx, *query.StaffID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewServiceError("failed to retrieve staff member", err)
+		}
+		if staff == nil || staff.BusinessID != query.BusinessID || !staff.IsActive {
+			return nil, NewNotFoundError("staff", "id", *query.StaffID)
+		}
+		return []string{staff.ID}, nil
+	}
+
+	staff, err := s.staffRepo.FindActiveByBusinessID(ctx, query.BusinessID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve staff", err)
+	}
+	ids := make([]string, len(staff))
+	for i, member := range staff {
+		ids[i] = member.ID
+	}
+	return ids, nil
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/assimoes/beautix/internal/domain"
 	"github.com/assimoes/beautix/internal/dto"
@@ -15,6 +16,8 @@ const (
 	UserRoleKey   ContextKey = "user_role"
 	BusinessIDKey ContextKey = "business_id"
 	ClerkUserKey  ContextKey = "clerk_user"
+	ClientIPKey   ContextKey = "client_ip"
+	SessionKey    ContextKey = "session"
 )
 
 // GetUserIDFromContext extracts user ID from context
@@ -33,6 +36,14 @@ func GetUserRoleFromContext(ctx context.Context) string {
 	return ""
 }
 
+// PlatformAdminRole is the context role of platform operators, as opposed to business staff
+const PlatformAdminRole = "platform_admin"
+
+// IsPlatformAdmin returns true if the context belongs to a platform operator
+func IsPlatformAdmin(ctx context.Context) bool {
+	return GetUserRoleFromContext(ctx) == PlatformAdminRole
+}
+
 // GetBusinessIDFromContext extracts business ID from context
 func GetBusinessIDFromContext(ctx context.Context) *string {
 	if businessID, ok := ctx.Value(BusinessIDKey).(string); ok && businessID != "" {
@@ -46,16 +57,67 @@ func GetClerkUserFromContext(ctx context.Context) any {
 	return ctx.Value(ClerkUserKey)
 }
 
-// SetUserContext creates a new context with user information
+// GetClientIPFromContext extracts the IP address of the caller from context
+func GetClientIPFromContext(ctx context.Context) string {
+	if ip, ok := ctx.Value(ClientIPKey).(string); ok {
+		return ip
+	}
+	return ""
+}
+
+// CallerSession identifies the session a request is made with, as established by the
+// authentication middleware
+type CallerSession struct {
+	Kind       domain.SessionKind
+	ExternalID string    // The Clerk session ID, or the API key's ID
+	IssuedAt   time.Time // When the session was signed in
+	UserAgent  string
+}
+
+// SetSessionContext creates a new context with the session the request is made with
+func SetSessionContext(ctx context.Context, session CallerSession) context.Context {
+	return context.WithValue(ctx, SessionKey, session)
+}
+
+// GetSessionFromContext extracts the session the request is made with from context
+func GetSessionFromContext(ctx context.Context) *CallerSession {
+	if session, ok := ctx.Value(SessionKey).(CallerSession); ok && session.ExternalID != "" {
+		return &session
+	}
+	return nil
+}
+
+// SetUserContext creates a new context with user information. Business users act for their
+// business as the tenant, and users without a business for none, so that they cannot reach any
+// business's data; platform admins are not scoped to one. The user is recorded in the audit
+// log as the author of the changes made with the context.
 func SetUserContext(ctx context.Context, userID, role string, businessID *string) context.Context {
 	ctx = context.WithValue(ctx, UserIDKey, userID)
+	ctx = domain.WithActor(ctx, userID)
 	ctx = context.WithValue(ctx, UserRoleKey, role)
+	tenant := domain.TenantContext{}
 	if businessID != nil {
 		ctx = context.WithValue(ctx, BusinessIDKey, *businessID)
+		tenant.BusinessID = *businessID
+	}
+	if role != PlatformAdminRole {
+		ctx = domain.WithTenant(ctx, tenant)
 	}
 	return ctx
 }
 
+// actFor scopes a context to the business a request acts for. Business users signed in with
+// SetUserContext act for their business alone, so a business taken from the input, or from the
+// data it names, is refused when it is another one, and users without a business are refused
+// any. Callers without a tenant, such as platform admins and background work, act for the
+// business given once they are authorized for it.
+func actFor(ctx context.Context, businessID, action string) (context.Context, error) {
+	if tenant, ok := domain.TenantFromContext(ctx); ok && !tenant.Owns(businessID) {
+		return ctx, NewForbiddenError(action)
+	}
+	return domain.WithTenant(ctx, domain.TenantContext{BusinessID: businessID}), nil
+}
+
 // ValidatePagination validates and sets default pagination values
 func ValidatePagination(pagination *dto.PaginationRequest) *dto.PaginationRequest {
 	if pagination == nil {
@@ -123,24 +185,35 @@ func (s *BaseServiceImpl[T, CreateDTO, UpdateDTO, ResponseDTO]) GetByID(ctx cont
 	return s.responseConverter(entity), nil
 }
 
-// Update updates an existing entity
+// Update applies a partial update to an entity, writing only the fields the update touches.
+// Fields set to their zero value or cleared are written too, so they can be emptied on purpose.
 func (s *BaseServiceImpl[T, CreateDTO, UpdateDTO, ResponseDTO]) Update(ctx context.Context, id string, updateDTO UpdateDTO) (*ResponseDTO, error) {
 	entity, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 	
+	changes, err := domain.NewChangeSet(updateDTO)
+	if err != nil {
+		return nil, err
+	}
 	err = s.updateConverter(entity, updateDTO)
 	if err != nil {
 		return nil, err
 	}
+	if changes.IsEmpty() {
+		return s.responseConverter(entity), nil
+	}
+	if err := changes.Capture(entity); err != nil {
+		return nil, err
+	}
 	
-	err = s.repo.Update(ctx, entity)
+	updated, err := s.repo.UpdateFields(ctx, id, changes)
 	if err != nil {
 		return nil, err
 	}
 	
-	return s.responseConverter(entity), nil
+	return s.responseConverter(updated), nil
 }
 
 // Delete deletes an entity by ID
@@ -161,4 +234,51 @@ func (s *BaseServiceImpl[T, CreateDTO, UpdateDTO, ResponseDTO]) List(ctx context
 	}
 	
 	return results, total, nil
-}
\ No newline at end of file
+}
+
+// ListAfter retrieves the page of up to first entities following the after cursor. An empty
+// cursor starts from the beginning of the list.
+func (s *BaseServiceImpl[T, CreateDTO, UpdateDTO, ResponseDTO]) ListAfter(ctx context.Context, after string, first int) (*domain.Connection[ResponseDTO], error) {
+	if first < 1 {
+		first = 20
+	} else if first > 100 {
+		first = 100
+	}
+
+	var cursor *domain.Cursor
+	if after != "" {
+		decoded, err := domain.DecodeCursor(after)
+		if err != nil {
+			return nil, toValidationError(err)
+		}
+		cursor = decoded
+	}
+
+	entities, hasNext, err := s.repo.ListAfter(ctx, cursor, first)
+	if err != nil {
+		return nil, err
+	}
+
+	connection := &domain.Connection[ResponseDTO]{
+		Edges: make([]*domain.Edge[ResponseDTO], 0, len(entities)),
+		PageInfo: domain.PageInfo{
+			HasNextPage:     hasNext,
+			HasPreviousPage: cursor != nil,
+		},
+	}
+	for _, entity := range entities {
+		entityCursor := ""
+		if e, ok := any(entity).(domain.Entity); ok {
+			entityCursor = domain.CursorFor(e).Encode()
+		}
+		connection.Edges = append(connection.Edges, &domain.Edge[ResponseDTO]{
+			Cursor: entityCursor,
+			Node:   s.responseConverter(entity),
+		})
+	}
+	if len(connection.Edges) > 0 {
+		connection.PageInfo.StartCursor = &connection.Edges[0].Cursor
+		connection.PageInfo.EndCursor = &connection.Edges[len(connection.Edges)-1].Cursor
+	}
+	return connection, nil
+}
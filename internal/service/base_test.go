@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+func TestActFor(t *testing.T) {
+	businessID := "business-1"
+	signedIn := SetUserContext(context.Background(), "user-1", "owner", &businessID)
+
+	ctx, err := actFor(signedIn, businessID, "manage resources")
+	require.NoError(t, err)
+	tenant, _ := domain.TenantFromContext(ctx)
+	assert.Equal(t, businessID, tenant.BusinessID)
+
+	_, err = actFor(signedIn, "business-2", "manage resources")
+	var forbidden ForbiddenError
+	assert.ErrorAs(t, err, &forbidden, "users act for the business they signed in for alone")
+
+	admin := SetUserContext(context.Background(), "admin-1", PlatformAdminRole, &businessID)
+	ctx, err = actFor(admin, "business-2", "manage resources")
+	require.NoError(t, err)
+	tenant, _ = domain.TenantFromContext(ctx)
+	assert.Equal(t, "business-2", tenant.BusinessID)
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// bookingAttemptRetention is how long booking attempts are kept for velocity checks
+const bookingAttemptRetention = 24 * time.Hour
+
+// Messages shown to bookers whose attempt was refused. The blocklist message deliberately
+// does not reveal that the booker is blocklisted.
+const (
+	bookingBlockedMessage     = "Online booking is not available. Please contact the business to book."
+	bookingCaptchaMessage     = "Please complete the CAPTCHA to continue."
+	bookingRateLimitedMessage = "Too many booking attempts. Please try again later."
+)
+
+// CaptchaVerifier checks a CAPTCHA solution submitted by a booker
+type CaptchaVerifier interface {
+	Verify(ctx context.Context, token, ipAddress string) (bool, error)
+}
+
+// BookingGuardService defines the service interface for protecting online booking from abuse
+type BookingGuardService interface {
+	AddBlocklistEntry(ctx context.Context, createDTO dto.CreateBlocklistEntryDTO) (*dto.BlocklistEntryResponseDTO, error)
+	ListBlocklist(ctx context.Context, businessID string) ([]*dto.BlocklistEntryResponseDTO, error)
+	RemoveBlocklistEntry(ctx context.Context, id string) error
+	CheckBooking(ctx context.Context, attempt domain.BookingAttempt, captchaToken string) (*dto.BookingDecisionDTO, error)
+	PruneAttempts(ctx context.Context, now time.Time) (int64, error)
+}
+
+// bookingGuardServiceImpl implements the BookingGuardService interface
+type bookingGuardServiceImpl struct {
+	blocklistRepo domain.BlocklistRepository
+	attemptRepo   domain.BookingAttemptRepository
+	clientRepo    domain.BaseRepository[domain.Client]
+	captcha       CaptchaVerifier
+	policy        domain.VelocityPolicy
+	validator     *validator.Validate
+}
+
+// NewBookingGuardService creates a new booking guard service. Without a CAPTCHA verifier,
+// attempts that require a CAPTCHA are refused.
+func NewBookingGuardService(
+	blocklistRepo domain.BlocklistRepository,
+	attemptRepo domain.BookingAttemptRepository,
+	clientRepo domain.BaseRepository[domain.Client],
+	captcha CaptchaVerifier,
+	validator *validator.Validate,
+) BookingGuardService {
+	return &bookingGuardServiceImpl{
+		blocklistRepo: blocklistRepo,
+		attemptRepo:   attemptRepo,
+		clientRepo:    clientRepo,
+		captcha:       captcha,
+		policy:        domain.DefaultVelocityPolicy,
+		validator:     validator,
+	}
+}
+
+// AddBlocklistEntry blocks a phone number, email address or client from booking online
+func (s *bookingGuardServiceImpl) AddBlocklistEntry(ctx context.Context, createDTO dto.CreateBlocklistEntryDTO) (*dto.BlocklistEntryResponseDTO, error) {
+	if err := s.validator.Struct(createDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	if createDTO.ExpiresAt != nil && !createDTO.ExpiresAt.After(time.Now()) {
+		return nil, validation.NewValidationError("expires_at must be in the future")
+	}
+
+	entry := &domain.BlocklistEntry{
+		BusinessID: createDTO.BusinessID,
+		EntryType:  createDTO.EntryType,
+		Value:      createDTO.Value,
+		Reason:     createDTO.Reason,
+		ExpiresAt:  createDTO.ExpiresAt,
+	}
+	if err := entry.Validate(); err != nil {
+		return nil, toValidationError(err)
+	}
+
+	if entry.EntryType == domain.BlocklistClient {
+		client, err := s.clientRepo.GetByID(ctx, entry.Value)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewServiceError("failed to retrieve client", err)
+		}
+		if client == nil || client.BusinessID != entry.BusinessID {
+			return nil, NewNotFoundError("client", "id", entry.Value)
+		}
+	}
+
+	_, err := s.blocklistRepo.FindMatch(ctx, entry.BusinessID,
+		[]domain.BlocklistKey{{EntryType: entry.EntryType, Value: entry.Value}}, time.Now())
+	if err == nil {
+		return nil, validation.NewValidationError("this " + string(entry.EntryType) + " is already blocklisted")
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, NewServiceError("failed to check blocklist", err)
+	}
+
+	entry.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.blocklistRepo.Create(ctx, entry); err != nil {
+		return nil, NewServiceError("failed to create blocklist entry", err)
+	}
+
+	return dto.ToBlocklistEntryResponseDTO(entry), nil
+}
+
+// ListBlocklist retrieves a business's blocklist, including expired entries
+func (s *bookingGuardServiceImpl) ListBlocklist(ctx context.Context, businessID string) ([]*dto.BlocklistEntryResponseDTO, error) {
+	if businessID == "" {
+		return nil, validation.NewValidationError("business_id is required")
+	}
+
+	entries, err := s.blocklistRepo.FindByBusinessID(ctx, businessID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve blocklist", err)
+	}
+
+	return dto.ToBlocklistEntryResponseDTOs(entries), nil
+}
+
+// RemoveBlocklistEntry removes a blocklist entry
+func (s *bookingGuardServiceImpl) RemoveBlocklistEntry(ctx context.Context, id string) error {
+	if _, err := s.blocklistRepo.GetByID(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewNotFoundError("blocklist entry", "id", id)
+		}
+		return NewServiceError("failed to retrieve blocklist entry", err)
+	}
+
+	if err := s.blocklistRepo.Delete(ctx, id); err != nil {
+		return NewServiceError("failed to delete blocklist entry", err)
+	}
+	return nil
+}
+
+// CheckBooking decides whether an online booking attempt may proceed. IP addresses that made
+// too many attempts across all businesses must solve a CAPTCHA, and are refused above the block
+// threshold; bookers on the business's blocklist are refused. Every attempt is recorded, so
+// refused attempts count towards the velocity limits too.
+func (s *bookingGuardServiceImpl) CheckBooking(ctx context.Context, attempt domain.BookingAttempt, captchaToken string) (*dto.BookingDecisionDTO, error) {
+	if attempt.BusinessID == "" {
+		return nil, validation.NewValidationError("business_id is required")
+	}
+	if attempt.IPAddress == "" {
+		return nil, validation.NewValidationError("ip_address is required")
+	}
+
+	now := time.Now()
+	previous, err := s.attemptRepo.CountByIP(ctx, attempt.IPAddress, now.Add(-s.policy.Window))
+	if err != nil {
+		return nil, NewServiceError("failed to check booking velocity", err)
+	}
+
+	outcome := s.policy.Evaluate(int(previous))
+	if outcome == domain.BookingCaptchaRequired && captchaToken != "" && s.captcha != nil {
+		solved, err := s.captcha.Verify(ctx, captchaToken, attempt.IPAddress)
+		if err != nil {
+			return nil, NewServiceError("failed to verify CAPTCHA", err)
+		}
+		if solved {
+			outcome = domain.BookingAllowed
+		}
+	}
+
+	if outcome == domain.BookingAllowed {
+		_, err := s.blocklistRepo.FindMatch(ctx, attempt.BusinessID, attempt.BlocklistKeys(), now)
+		switch {
+		case err == nil:
+			outcome = domain.BookingBlocklisted
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, NewServiceError("failed to check blocklist", err)
+		}
+	}
+
+	record := &domain.BookingAttemptRecord{
+		IPAddress:  attempt.IPAddress,
+		BusinessID: attempt.BusinessID,
+		Outcome:    outcome,
+		CreatedAt:  now,
+	}
+	if err := s.attemptRepo.Record(ctx, record); err != nil {
+		log.Warn().Err(err).Str("ip_address", attempt.IPAddress).Msg("Failed to record booking attempt")
+	}
+
+	decision := &dto.BookingDecisionDTO{Allowed: outcome == domain.BookingAllowed, Outcome: outcome}
+	switch outcome {
+	case domain.BookingBlocklisted:
+		decision.Message = bookingBlockedMessage
+	case domain.BookingCaptchaRequired:
+		decision.Message = bookingCaptchaMessage
+	case domain.BookingRateLimited:
+		decision.Message = bookingRateLimitedMessage
+	}
+	return decision, nil
+}
+
+// PruneAttempts removes booking attempts that no longer count towards velocity checks
+func (s *bookingGuardServiceImpl) PruneAttempts(ctx context.Context, now time.Time) (int64, error) {
+	removed, err := s.attemptRepo.DeleteBefore(ctx, now.Add(-bookingAttemptRetention))
+	if err != nil {
+		return 0, NewServiceError("failed to prune booking attempts", err)
+	}
+	return removed, nil
+}
@@ -0,0 +1,355 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/notification"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+const (
+	// RescheduleLinkPath is the path of the link clients open to move a disrupted appointment
+	RescheduleLinkPath = "/reschedule/"
+	// defaultBroadcastSubject is the email subject of broadcasts without one
+	defaultBroadcastSubject = "Important notice from {{business.name}}"
+)
+
+// broadcastChannels is the order a notice is delivered in; SMS first as it is read soonest
+var broadcastChannels = []domain.MessageChannel{domain.MessageChannelSMS, domain.MessageChannelEmail}
+
+// BroadcastService defines the service interface for emergency broadcasts
+type BroadcastService interface {
+	PreviewBroadcast(ctx context.Context, businessID string, start, end time.Time) (*dto.BroadcastPreviewDTO, error)
+	SendBroadcast(ctx context.Context, sendDTO dto.SendBroadcastDTO) (*dto.BroadcastResponseDTO, error)
+	GetBroadcast(ctx context.Context, id string) (*dto.BroadcastResponseDTO, error)
+	ListBroadcasts(ctx context.Context, businessID string) ([]*dto.BroadcastResponseDTO, error)
+	GetRescheduleRequest(ctx context.Context, token string) (*dto.RescheduleRequestDTO, error)
+	RescheduleByToken(ctx context.Context, token string, startTime time.Time) (*dto.RescheduleRequestDTO, error)
+}
+
+// broadcastServiceImpl implements the BroadcastService interface
+type broadcastServiceImpl struct {
+	broadcastRepo      domain.BroadcastRepository
+	appointmentRepo    domain.BaseRepository[domain.Appointment]
+	businessRepo       domain.BusinessRepository
+	calendarRepo       domain.CalendarRepository
+	appointmentService AppointmentService
+	sender             notification.NotificationSender
+	validator          *validator.Validate
+	publicURL          string
+}
+
+// NewBroadcastService creates a new broadcast service. Rescheduling links point to publicURL,
+// the externally reachable base URL of the API.
+func NewBroadcastService(
+	broadcastRepo domain.BroadcastRepository,
+	appointmentRepo domain.BaseRepository[domain.Appointment],
+	businessRepo domain.BusinessRepository,
+	calendarRepo domain.CalendarRepository,
+	appointmentService AppointmentService,
+	sender notification.NotificationSender,
+	validator *validator.Validate,
+	publicURL string,
+) BroadcastService {
+	return &broadcastServiceImpl{
+		broadcastRepo:      broadcastRepo,
+		appointmentRepo:    appointmentRepo,
+		businessRepo:       businessRepo,
+		calendarRepo:       calendarRepo,
+		appointmentService: appointmentService,
+		sender:             sender,
+		validator:          validator,
+		publicURL:          strings.TrimSuffix(publicURL, "/"),
+	}
+}
+
+// PreviewBroadcast counts the appointments in a window and how many of their clients can be reached
+func (s *broadcastServiceImpl) PreviewBroadcast(ctx context.Context, businessID string, start, end time.Time) (*dto.BroadcastPreviewDTO, error) {
+	if businessID == "" {
+		return nil, validation.NewValidationError("business_id is required")
+	}
+	window := &domain.Broadcast{BusinessID: businessID, Message: "-", WindowStart: start, WindowEnd: end}
+	if err := window.Validate(); err != nil {
+		return nil, toValidationError(err)
+	}
+
+	targets, err := s.broadcastRepo.FindTargets(ctx, businessID, start, end)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve affected appointments", err)
+	}
+
+	preview := &dto.BroadcastPreviewDTO{AppointmentCount: len(targets)}
+	for _, target := range targets {
+		if len(target.Channels()) > 0 {
+			preview.ReachableCount++
+		} else {
+			preview.UnreachableCount++
+		}
+	}
+	return preview, nil
+}
+
+// SendBroadcast messages every client with an appointment in the window on all the channels
+// they can be reached on, and marks their appointments as affected. Delivery failures are
+// recorded per recipient rather than failing the broadcast.
+func (s *broadcastServiceImpl) SendBroadcast(ctx context.Context, sendDTO dto.SendBroadcastDTO) (*dto.BroadcastResponseDTO, error) {
+	if err := s.validator.Struct(sendDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+
+	business, err := s.businessRepo.GetByID(ctx, sendDTO.BusinessID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("business", "id", sendDTO.BusinessID)
+		}
+		return nil, NewServiceError("failed to retrieve business", err)
+	}
+
+	broadcast := &domain.Broadcast{
+		BusinessID:      sendDTO.BusinessID,
+		Subject:         sendDTO.Subject,
+		Message:         sendDTO.Message,
+		WindowStart:     sendDTO.WindowStart,
+		WindowEnd:       sendDTO.WindowEnd,
+		OfferReschedule: sendDTO.OfferReschedule,
+	}
+	if err := broadcast.Validate(); err != nil {
+		return nil, toValidationError(err)
+	}
+
+	targets, err := s.broadcastRepo.FindTargets(ctx, broadcast.BusinessID, broadcast.WindowStart, broadcast.WindowEnd)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve affected appointments", err)
+	}
+
+	userID := GetUserIDFromContext(ctx)
+	broadcast.SetAuditFields(userID)
+	broadcast.AppointmentCount = len(targets)
+	if err := s.broadcastRepo.Create(ctx, broadcast); err != nil {
+		return nil, NewServiceError("failed to create broadcast", err)
+	}
+
+	recipients := make([]*domain.BroadcastRecipient, len(targets))
+	for i, target := range targets {
+		recipients[i] = &domain.BroadcastRecipient{
+			BroadcastID:   broadcast.ID,
+			AppointmentID: target.AppointmentID,
+			ClientID:      target.ClientID,
+			Status:        domain.BroadcastRecipientPending,
+		}
+		if broadcast.OfferReschedule {
+			token, err := domain.NewConfirmationToken()
+			if err != nil {
+				return nil, NewServiceError("failed to create rescheduling link", err)
+			}
+			recipients[i].RescheduleToken = &token
+		}
+		recipients[i].SetAuditFields(userID)
+	}
+	// Recipients are stored before sending so that rescheduling links work as soon as they arrive
+	if err := s.broadcastRepo.AddRecipients(ctx, broadcast, recipients); err != nil {
+		return nil, NewServiceError("failed to record broadcast recipients", err)
+	}
+
+	for i, target := range targets {
+		s.deliver(ctx, broadcast, business, target, recipients[i])
+		if recipients[i].Status == domain.BroadcastRecipientNotified {
+			broadcast.NotifiedCount++
+		} else {
+			broadcast.FailedCount++
+		}
+	}
+
+	now := time.Now()
+	broadcast.SentAt = &now
+	if err := s.broadcastRepo.Update(ctx, broadcast); err != nil {
+		return nil, NewServiceError("failed to update broadcast", err)
+	}
+
+	return dto.ToBroadcastResponseDTO(broadcast, recipients), nil
+}
+
+// deliver sends the notice to one client on every channel they can be reached on and records
+// the outcome on the recipient
+func (s *broadcastServiceImpl) deliver(ctx context.Context, broadcast *domain.Broadcast, business *domain.Business, target *domain.BroadcastTarget, recipient *domain.BroadcastRecipient) {
+	appointment, err := s.calendarRepo.FindByAppointmentID(ctx, target.AppointmentID)
+	if err != nil {
+		// Not projected yet; render without services and staff
+		appointment = &domain.CalendarEntry{StartTime: target.StartTime, EndTime: target.EndTime}
+	}
+	values := domain.TemplateRenderContext{
+		Business:    business,
+		Client:      &domain.Client{FirstName: target.ClientFirstName, LastName: target.ClientLastName},
+		Appointment: appointment,
+	}.Values()
+
+	link := ""
+	if recipient.RescheduleToken != nil {
+		link = s.publicURL + RescheduleLinkPath + *recipient.RescheduleToken
+	}
+	body := broadcast.Render(values, link)
+	subject := defaultBroadcastSubject
+	if broadcast.Subject != nil && strings.TrimSpace(*broadcast.Subject) != "" {
+		subject = *broadcast.Subject
+	}
+	subject = domain.RenderTemplateText(subject, values)
+
+	contacts := target.Channels()
+	var delivered []domain.MessageChannel
+	var failures []string
+	for _, channel := range broadcastChannels {
+		address, ok := contacts[channel]
+		if !ok {
+			continue
+		}
+		message := notification.Message{
+			BusinessID: broadcast.BusinessID,
+			Channel:    channel,
+			Recipient:  address,
+			Body:       body,
+		}
+		if channel == domain.MessageChannelEmail {
+			message.Subject = &subject
+		}
+		if err := s.sender.Send(ctx, message); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", channel, err))
+			continue
+		}
+		delivered = append(delivered, channel)
+	}
+
+	recipient.RecordDelivery(delivered, failures)
+	if err := s.broadcastRepo.UpdateRecipient(ctx, recipient); err != nil {
+		log.Error().Err(err).Str("appointment_id", recipient.AppointmentID).Msg("Failed to record broadcast delivery")
+	}
+}
+
+// GetBroadcast retrieves a broadcast with the outcome for each affected appointment
+func (s *broadcastServiceImpl) GetBroadcast(ctx context.Context, id string) (*dto.BroadcastResponseDTO, error) {
+	broadcast, err := s.broadcastRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("broadcast", "id", id)
+		}
+		return nil, NewServiceError("failed to retrieve broadcast", err)
+	}
+	recipients, err := s.broadcastRepo.FindRecipients(ctx, broadcast.ID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve broadcast recipients", err)
+	}
+	return dto.ToBroadcastResponseDTO(broadcast, recipients), nil
+}
+
+// ListBroadcasts retrieves the broadcasts of a business, latest first
+func (s *broadcastServiceImpl) ListBroadcasts(ctx context.Context, businessID string) ([]*dto.BroadcastResponseDTO, error) {
+	if businessID == "" {
+		return nil, validation.NewValidationError("business_id is required")
+	}
+	broadcasts, err := s.broadcastRepo.FindByBusinessID(ctx, businessID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve broadcasts", err)
+	}
+
+	result := make([]*dto.BroadcastResponseDTO, len(broadcasts))
+	for i, broadcast := range broadcasts {
+		result[i] = dto.ToBroadcastResponseDTO(broadcast, nil)
+	}
+	return result, nil
+}
+
+// GetRescheduleRequest retrieves the appointment behind a rescheduling link
+func (s *broadcastServiceImpl) GetRescheduleRequest(ctx context.Context, token string) (*dto.RescheduleRequestDTO, error) {
+	result, _, _, err := s.loadRescheduleRequest(ctx, token)
+	return result, err
+}
+
+// RescheduleByToken moves the appointment behind a rescheduling link to a new start time,
+// keeping its length and staff member. The new time must be outside the disrupted window and
+// free in the staff member's schedule; an appointment can be moved once through its link.
+func (s *broadcastServiceImpl) RescheduleByToken(ctx context.Context, token string, startTime time.Time) (*dto.RescheduleRequestDTO, error) {
+	result, recipient, appointment, err := s.loadRescheduleRequest(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if result.Rescheduled {
+		return nil, validation.NewValidationError("the appointment has already been rescheduled")
+	}
+	if !startTime.After(time.Now()) {
+		return nil, validation.NewValidationError("the new time must be in the future")
+	}
+
+	broadcast, err := s.broadcastRepo.GetByID(ctx, recipient.BroadcastID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve broadcast", err)
+	}
+	endTime := startTime.Add(appointment.EndTime.Sub(appointment.StartTime))
+	if startTime.Before(broadcast.WindowEnd) && endTime.After(broadcast.WindowStart) {
+		return nil, validation.NewValidationError("the new time falls in the period affected by the disruption")
+	}
+
+	if _, err := s.appointmentService.RescheduleAppointment(ctx, appointment.ID, dto.RescheduleAppointmentDTO{
+		StartTime: startTime,
+		EndTime:   endTime,
+	}); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	recipient.RescheduledAt = &now
+	if err := s.broadcastRepo.UpdateRecipient(ctx, recipient); err != nil {
+		return nil, NewServiceError("failed to record rescheduling", err)
+	}
+
+	result.StartTime = startTime.In(result.StartTime.Location())
+	result.Rescheduled = true
+	return result, nil
+}
+
+// loadRescheduleRequest loads the recipient, appointment and business of a rescheduling link
+func (s *broadcastServiceImpl) loadRescheduleRequest(ctx context.Context, token string) (*dto.RescheduleRequestDTO, *domain.BroadcastRecipient, *domain.Appointment, error) {
+	if token == "" {
+		return nil, nil, nil, validation.NewValidationError("token is required")
+	}
+
+	recipient, err := s.broadcastRepo.FindRecipientByToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, nil, NewNotFoundError("rescheduling link", "token", token)
+		}
+		return nil, nil, nil, NewServiceError("failed to retrieve rescheduling link", err)
+	}
+	appointment, err := s.appointmentRepo.GetByID(ctx, recipient.AppointmentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, nil, NewNotFoundError("appointment", "id", recipient.AppointmentID)
+		}
+		return nil, nil, nil, NewServiceError("failed to retrieve appointment", err)
+	}
+	business, err := s.businessRepo.GetByID(ctx, appointment.BusinessID)
+	if err != nil {
+		return nil, nil, nil, NewServiceError("failed to retrieve business", err)
+	}
+
+	location, err := time.LoadLocation(business.TimeZone)
+	if err != nil {
+		location = time.UTC
+	}
+	result := &dto.RescheduleRequestDTO{
+		AppointmentID: appointment.ID,
+		BusinessName:  business.GetDisplayName(),
+		TimeZone:      location.String(),
+		StartTime:     appointment.StartTime.In(location),
+		Duration:      int(appointment.EndTime.Sub(appointment.StartTime).Minutes()),
+		Rescheduled:   recipient.RescheduledAt != nil,
+	}
+	return result, recipient, appointment, nil
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// BusinessHoursService defines the service interface for the opening hours of businesses and
+// their locations
+type BusinessHoursService interface {
+	GetBusinessHours(ctx context.Context, businessID string) ([]*dto.OpeningHoursDTO, error)
+	SetBusinessHours(ctx context.Context, setDTO dto.SetBusinessHoursDTO) (*dto.OpeningHoursDTO, error)
+	ClearBusinessHours(ctx context.Context, businessID string, locationID *string) (*dto.OpeningHoursDTO, error)
+}
+
+// businessHoursServiceImpl implements the BusinessHoursService interface
+type businessHoursServiceImpl struct {
+	hoursRepo    domain.BusinessHoursRepository
+	businessRepo domain.BusinessRepository
+	locationRepo domain.BusinessLocationRepository
+	staffRepo    domain.StaffRepository
+	validator    *validator.Validate
+}
+
+// NewBusinessHoursService creates a new business hours service
+func NewBusinessHoursService(
+	hoursRepo domain.BusinessHoursRepository,
+	businessRepo domain.BusinessRepository,
+	locationRepo domain.BusinessLocationRepository,
+	staffRepo domain.StaffRepository,
+	validator *validator.Validate,
+) BusinessHoursService {
+	return &businessHoursServiceImpl{
+		hoursRepo:    hoursRepo,
+		businessRepo: businessRepo,
+		locationRepo: locationRepo,
+		staffRepo:    staffRepo,
+		validator:    validator,
+	}
+}
+
+// GetBusinessHours returns the opening hours of a business, then those of each of its locations,
+// the main location first. Locations without hours of their own keep the business's.
+func (s *businessHoursServiceImpl) GetBusinessHours(ctx context.Context, businessID string) ([]*dto.OpeningHoursDTO, error) {
+	if err := requireStaff(ctx, s.staffRepo, businessID, "view the opening hours of this business"); err != nil {
+		return nil, err
+	}
+	ctx, err := actFor(ctx, businessID, "view the opening hours of this business")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := getBusiness(ctx, s.businessRepo, businessID); err != nil {
+		return nil, err
+	}
+	rows, err := s.hoursRepo.FindByBusiness(ctx, businessID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve opening hours", err)
+	}
+	locations, err := s.locationRepo.FindByBusinessID(ctx, businessID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve locations", err)
+	}
+
+	hours := []*dto.OpeningHoursDTO{toOpeningHoursDTO(businessID, nil, rows)}
+	for _, location := range locations {
+		hours = append(hours, toOpeningHoursDTO(businessID, location, rows))
+	}
+	return hours, nil
+}
+
+// SetBusinessHours replaces the opening hours of a business or of one of its locations. From
+// then on the business's hours are the ones bookings are checked against; use
+// updateSchedulingSettings to list the appointments a change would invalidate first. Only
+// owners and managers can set opening hours.
+func (s *businessHoursServiceImpl) SetBusinessHours(ctx context.Context, setDTO dto.SetBusinessHoursDTO) (*dto.OpeningHoursDTO, error) {
+	if err := s.validator.Struct(setDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	hours := setDTO.Hours.WeeklyHours()
+	if err := hours.Validate(); err != nil {
+		return nil, toValidationError(err)
+	}
+	return s.replace(ctx, setDTO.BusinessID, setDTO.LocationID, hours)
+}
+
+// ClearBusinessHours removes the opening hours of a location, which then keeps the business's,
+// or of the business, whose calendar hours then apply every day
+func (s *businessHoursServiceImpl) ClearBusinessHours(ctx context.Context, businessID string, locationID *string) (*dto.OpeningHoursDTO, error) {
+	return s.replace(ctx, businessID, locationID, nil)
+}
+
+// replace checks who is asking and that the location is the business's, then replaces its hours
+func (s *businessHoursServiceImpl) replace(ctx context.Context, businessID string, locationID *string, hours domain.WeeklyHours) (*dto.OpeningHoursDTO, error) {
+	if err := requireManager(ctx, s.staffRepo, businessID, "change the opening hours of this business"); err != nil {
+		return nil, err
+	}
+	ctx, err := actFor(ctx, businessID, "change the opening hours of this business")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := getBusiness(ctx, s.businessRepo, businessID); err != nil {
+		return nil, err
+	}
+	var location *domain.BusinessLocation
+	if locationID != nil {
+		found, err := s.locationRepo.GetByID(ctx, *locationID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewServiceError("failed to retrieve location", err)
+		}
+		if found == nil || found.BusinessID != businessID {
+			return nil, NewNotFoundError("location", "id", *locationID)
+		}
+		location = found
+	}
+
+	rows := hours.Rows(businessID, locationID)
+	userID := GetUserIDFromContext(ctx)
+	for _, row := range rows {
+		row.SetAuditFields(userID)
+	}
+	if err := s.hoursRepo.Replace(ctx, businessID, locationID, rows); err != nil {
+		return nil, NewServiceError("failed to save opening hours", err)
+	}
+
+	all, err := s.hoursRepo.FindByBusiness(ctx, businessID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve opening hours", err)
+	}
+	return toOpeningHoursDTO(businessID, location, all), nil
+}
+
+// toOpeningHoursDTO converts the hours of a business, or of one of its locations, to a DTO. The
+// rows are those of the business and all its locations.
+func toOpeningHoursDTO(businessID string, location *domain.BusinessLocation, rows []*domain.BusinessHours) *dto.OpeningHoursDTO {
+	opening := &dto.OpeningHoursDTO{BusinessID: businessID}
+	var locationID *string
+	if location != nil {
+		locationID = &location.ID
+		opening.LocationID = &location.ID
+		opening.LocationName = &location.Name
+		opening.Inherited = !hasOwnHours(rows, location.ID)
+	}
+	hours := domain.HoursAt(rows, locationID)
+	opening.Configured = hours != nil
+	opening.Hours = dto.ToBusinessHoursDTO(hours)
+	return opening
+}
+
+// hasOwnHours reports whether a location has opening hours of its own
+func hasOwnHours(rows []*domain.BusinessHours, locationID string) bool {
+	for _, row := range rows {
+		if row.LocationID != nil && *row.LocationID == locationID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// importBatchListLimit bounds the import batches listed for a business
+const importBatchListLimit = 50
+
+// BusinessImportService defines the service interface for setting up the service menu and staff
+// roster of a business from spreadsheets
+type BusinessImportService interface {
+	ImportServices(ctx context.Context, importDTO dto.BusinessImportDTO) (*dto.BusinessImportResultDTO, error)
+	ImportStaff(ctx context.Context, importDTO dto.BusinessImportDTO) (*dto.BusinessImportResultDTO, error)
+	ListImportBatches(ctx context.Context, businessID string) ([]*dto.ImportBatchDTO, error)
+	RollBackImportBatch(ctx context.Context, batchID string) (*dto.ImportBatchDTO, error)
+}
+
+// businessImportServiceImpl implements the BusinessImportService interface
+type businessImportServiceImpl struct {
+	importRepo         domain.BusinessImportRepository
+	staffRepo          domain.StaffRepository
+	entitlementService EntitlementService
+	validator          *validator.Validate
+}
+
+// NewBusinessImportService creates a new business import service
+func NewBusinessImportService(
+	importRepo domain.BusinessImportRepository,
+	staffRepo domain.StaffRepository,
+	entitlementService EntitlementService,
+	validator *validator.Validate,
+) BusinessImportService {
+	return &businessImportServiceImpl{
+		importRepo:         importRepo,
+		staffRepo:          staffRepo,
+		entitlementService: entitlementService,
+		validator:          validator,
+	}
+}
+
+// ImportServices imports service categories and services from a CSV file. Services whose name
+// matches an existing service or an earlier row are skipped as duplicates, and categories are
+// created for the names not matching an existing one. As with client imports, nothing is
+// created while any row is invalid or on a dry run. The records created are kept as a batch
+// that can be rolled back.
+func (s *businessImportServiceImpl) ImportServices(ctx context.Context, importDTO dto.BusinessImportDTO) (*dto.BusinessImportResultDTO, error) {
+	if err := s.checkImport(ctx, importDTO, "import services"); err != nil {
+		return nil, err
+	}
+
+	rows, err := domain.ParseServiceCSV(strings.NewReader(importDTO.CSV), importDTO.BusinessID)
+	if err != nil {
+		return nil, toValidationError(err)
+	}
+	existing, err := s.importRepo.FindServiceNames(ctx, importDTO.BusinessID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve existing services", err)
+	}
+	domain.MarkServiceImportDuplicates(rows, existing)
+
+	ready, invalid := 0, false
+	for _, row := range rows {
+		switch row.Status {
+		case domain.ImportRowInvalid:
+			invalid = true
+		case domain.ImportRowReady:
+			ready++
+		}
+	}
+	if importDTO.DryRun || invalid || ready == 0 {
+		return dto.ToServiceImportResultDTO(importDTO.BusinessID, importDTO.DryRun, nil, rows), nil
+	}
+
+	existingCategories, err := s.importRepo.FindCategories(ctx, importDTO.BusinessID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve service categories", err)
+	}
+	userID := GetUserIDFromContext(ctx)
+	categories, services, records := domain.PlanServiceImport(rows, existingCategories, userID)
+
+	batch, err := newImportBatch(importDTO.BusinessID, domain.ImportKindServices, len(rows), len(services), records, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.importRepo.ImportServices(ctx, batch, categories, services); err != nil {
+		return nil, NewServiceError("failed to import services", err)
+	}
+	for _, row := range rows {
+		if row.Status == domain.ImportRowReady {
+			row.Status = domain.ImportRowImported
+		}
+	}
+	return dto.ToServiceImportResultDTO(importDTO.BusinessID, false, batch, rows), nil
+}
+
+// ImportStaff imports staff members from a CSV file. Rows whose email matches an existing staff
+// member or an earlier row are skipped as duplicates. Staff members are linked to the user
+// account with their email, or to a new account they claim when they first sign in. Imports
+// are all or nothing and recorded as a batch, as for services. Imports taking the business over
+// the staff its plan allows are refused, dry runs included.
+func (s *businessImportServiceImpl) ImportStaff(ctx context.Context, importDTO dto.BusinessImportDTO) (*dto.BusinessImportResultDTO, error) {
+	if err := s.checkImport(ctx, importDTO, "import staff"); err != nil {
+		return nil, err
+	}
+
+	rows, err := domain.ParseStaffCSV(strings.NewReader(importDTO.CSV), importDTO.BusinessID)
+	if err != nil {
+		return nil, toValidationError(err)
+	}
+	existing, err := s.importRepo.FindStaffEmails(ctx, importDTO.BusinessID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve existing staff", err)
+	}
+	domain.MarkStaffImportDuplicates(rows, existing)
+
+	var emails []string
+	invalid := false
+	for _, row := range rows {
+		switch row.Status {
+		case domain.ImportRowInvalid:
+			invalid = true
+		case domain.ImportRowReady:
+			emails = append(emails, row.User.Email)
+		}
+	}
+	if !invalid && len(emails) > 0 {
+		if err := s.entitlementService.Require(ctx, importDTO.BusinessID, domain.EntitlementStaff, len(emails)); err != nil {
+			return nil, err
+		}
+	}
+	if importDTO.DryRun || invalid || len(emails) == 0 {
+		return dto.ToStaffImportResultDTO(importDTO.BusinessID, importDTO.DryRun, nil, rows), nil
+	}
+
+	existingUsers, err := s.importRepo.FindUsersByEmail(ctx, emails)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve user accounts", err)
+	}
+	userID := GetUserIDFromContext(ctx)
+	users, staff, records := domain.PlanStaffImport(rows, existingUsers, userID)
+
+	batch, err := newImportBatch(importDTO.BusinessID, domain.ImportKindStaff, len(rows), len(staff), records, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.importRepo.ImportStaff(ctx, batch, users, staff); err != nil {
+		return nil, NewServiceError("failed to import staff", err)
+	}
+	for _, row := range rows {
+		if row.Status == domain.ImportRowReady {
+			row.Status = domain.ImportRowImported
+		}
+	}
+	return dto.ToStaffImportResultDTO(importDTO.BusinessID, false, batch, rows), nil
+}
+
+// ListImportBatches lists the latest import batches of a business (managers only)
+func (s *businessImportServiceImpl) ListImportBatches(ctx context.Context, businessID string) ([]*dto.ImportBatchDTO, error) {
+	if err := requireManager(ctx, s.staffRepo, businessID, "view imports"); err != nil {
+		return nil, err
+	}
+
+	batches, err := s.importRepo.FindBatches(ctx, businessID, importBatchListLimit)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve import batches", err)
+	}
+	result := make([]*dto.ImportBatchDTO, len(batches))
+	for i, batch := range batches {
+		result[i] = dto.ToImportBatchDTO(batch)
+	}
+	return result, nil
+}
+
+// RollBackImportBatch removes everything an import batch created (managers only). A batch
+// cannot be rolled back once appointments were booked with its services or staff.
+func (s *businessImportServiceImpl) RollBackImportBatch(ctx context.Context, batchID string) (*dto.ImportBatchDTO, error) {
+	batch, err := s.importRepo.GetBatch(ctx, batchID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("import batch", "id", batchID)
+		}
+		return nil, NewServiceError("failed to retrieve import batch", err)
+	}
+	if err := requireManager(ctx, s.staffRepo, batch.BusinessID, "roll back imports"); err != nil {
+		return nil, err
+	}
+	if !batch.CanRollBack() {
+		return nil, validation.NewValidationError("the import has already been rolled back")
+	}
+
+	userID := GetUserIDFromContext(ctx)
+	batch.MarkRolledBack(userID, time.Now())
+	batch.SetAuditFields(userID)
+	if err := s.importRepo.RollBack(ctx, batch); err != nil {
+		if errors.Is(err, domain.ErrImportBatchInUse) {
+			return nil, validation.NewValidationError("the import cannot be rolled back: appointments have been booked with its records")
+		}
+		return nil, NewServiceError("failed to roll back import", err)
+	}
+	return dto.ToImportBatchDTO(batch), nil
+}
+
+// checkImport validates an import request and checks that the user asking manages the business
+func (s *businessImportServiceImpl) checkImport(ctx context.Context, importDTO dto.BusinessImportDTO, action string) error {
+	if err := s.validator.Struct(importDTO); err != nil {
+		return validation.FromError(err)
+	}
+	if len(importDTO.CSV) > dto.MaxClientImportSize {
+		return validation.NewValidationError("the file must be at most 5 MB")
+	}
+	return requireManager(ctx, s.staffRepo, importDTO.BusinessID, action)
+}
+
+// newImportBatch creates the batch recording an import
+func newImportBatch(businessID string, kind domain.ImportKind, totalRows, imported int, records domain.ImportRecords, by *string) (*domain.ImportBatch, error) {
+	batch := &domain.ImportBatch{
+		BusinessID: businessID,
+		Kind:       kind,
+		Status:     domain.ImportBatchImported,
+		TotalRows:  totalRows,
+		Imported:   imported,
+	}
+	if err := batch.SetRecords(records); err != nil {
+		return nil, NewServiceError("failed to encode import records", err)
+	}
+	batch.SetAuditFields(by)
+	return batch, nil
+}
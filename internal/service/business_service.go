@@ -0,0 +1,336 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// BusinessService defines the service interface for setting up businesses and the way they
+// are run
+type BusinessService interface {
+	CreateBusiness(ctx context.Context, createDTO dto.CreateBusinessDTO) (*dto.BusinessResponseDTO, error)
+	SetBusinessMode(ctx context.Context, modeDTO dto.SetBusinessModeDTO) (*dto.BusinessResponseDTO, error)
+	SetBusinessTemplate(ctx context.Context, templateDTO dto.SetBusinessTemplateDTO) (*dto.BusinessResponseDTO, error)
+	CloneBusiness(ctx context.Context, cloneDTO dto.CloneBusinessDTO) (*dto.BusinessCloneDTO, error)
+}
+
+// businessServiceImpl implements the BusinessService interface
+type businessServiceImpl struct {
+	businessRepo domain.BusinessRepository
+	settingsRepo domain.BusinessSettingsRepository
+	staffRepo    domain.StaffRepository
+	cloneRepo    domain.BusinessCloneRepository
+	unitOfWork   domain.UnitOfWork
+	validator    *validator.Validate
+}
+
+// NewBusinessService creates a new business service
+func NewBusinessService(
+	businessRepo domain.BusinessRepository,
+	settingsRepo domain.BusinessSettingsRepository,
+	staffRepo domain.StaffRepository,
+	cloneRepo domain.BusinessCloneRepository,
+	unitOfWork domain.UnitOfWork,
+	validator *validator.Validate,
+) BusinessService {
+	return &businessServiceImpl{
+		businessRepo: businessRepo,
+		settingsRepo: settingsRepo,
+		staffRepo:    staffRepo,
+		cloneRepo:    cloneRepo,
+		unitOfWork:   unitOfWork,
+		validator:    validator,
+	}
+}
+
+// CreateBusiness creates a business owned by the current user, together with its default
+// settings and the owner's staff record so that they can take bookings straight away, all in
+// one unit of work
+func (s *businessServiceImpl) CreateBusiness(ctx context.Context, createDTO dto.CreateBusinessDTO) (*dto.BusinessResponseDTO, error) {
+	userID := GetUserIDFromContext(ctx)
+	if userID == nil {
+		return nil, NewForbiddenError("create a business")
+	}
+	if err := s.validator.Struct(createDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+
+	business := &domain.Business{
+		UserID:       *userID,
+		Name:         strings.TrimSpace(createDTO.Name),
+		DisplayName:  createDTO.DisplayName,
+		BusinessType: createDTO.BusinessType,
+		TaxID:        createDTO.TaxID,
+		Email:        strings.ToLower(createDTO.Email),
+		Website:      createDTO.Website,
+		Currency:     createDTO.Currency,
+		TimeZone:     createDTO.TimeZone,
+		IsActive:     true,
+		Mode:         createDTO.Mode,
+	}
+	if business.Mode == "" {
+		business.Mode = domain.BusinessModeTeam
+	}
+	if err := business.Validate(); err != nil {
+		return nil, toValidationError(err)
+	}
+
+	now := time.Now()
+	owner := &domain.Staff{
+		UserID:    *userID,
+		Role:      domain.BusinessRoleOwner,
+		IsActive:  true,
+		StartDate: &now,
+	}
+	business.SetAuditFields(userID)
+	owner.SetAuditFields(userID)
+	err := s.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		if err := s.businessRepo.Create(ctx, business); err != nil {
+			return err
+		}
+		tenantCtx := domain.WithTenant(ctx, domain.TenantContext{BusinessID: business.ID})
+		settings := domain.NewBusinessSettings(business)
+		settings.SetAuditFields(userID)
+		if err := s.settingsRepo.Create(tenantCtx, settings); err != nil {
+			return err
+		}
+		owner.BusinessID = business.ID
+		return s.staffRepo.Create(tenantCtx, owner)
+	})
+	if err != nil {
+		return nil, NewServiceError("failed to create business", err)
+	}
+
+	return dto.ToBusinessResponseDTO(business), nil
+}
+
+// SetBusinessMode changes the way a business is run, and with it the capabilities it has.
+// Only the owner may change it, and a business can only go solo once the owner is its only
+// active staff member.
+func (s *businessServiceImpl) SetBusinessMode(ctx context.Context, modeDTO dto.SetBusinessModeDTO) (*dto.BusinessResponseDTO, error) {
+	if err := s.validator.Struct(modeDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	ctx, err := actFor(ctx, modeDTO.BusinessID, "change the business mode")
+	if err != nil {
+		return nil, err
+	}
+
+	business, err := getBusiness(ctx, s.businessRepo, modeDTO.BusinessID)
+	if err != nil {
+		return nil, err
+	}
+	userID := GetUserIDFromContext(ctx)
+	if userID == nil || !business.IsOwner(*userID) {
+		return nil, NewForbiddenError("change the mode of the business")
+	}
+	if business.GetMode() == modeDTO.Mode {
+		return dto.ToBusinessResponseDTO(business), nil
+	}
+
+	activeStaff, err := s.staffRepo.FindActiveByBusinessID(ctx, business.ID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve staff", err)
+	}
+	if err := business.CheckModeChange(modeDTO.Mode, activeStaff); err != nil {
+		return nil, toValidationError(err)
+	}
+
+	business.Mode = modeDTO.Mode
+	business.SetAuditFields(userID)
+	if err := s.businessRepo.Update(ctx, business); err != nil {
+		return nil, NewServiceError("failed to update business mode", err)
+	}
+	return dto.ToBusinessResponseDTO(business), nil
+}
+
+// SetBusinessTemplate marks a business as a template to clone new businesses from, or back as
+// a business clients can book (owner only)
+func (s *businessServiceImpl) SetBusinessTemplate(ctx context.Context, templateDTO dto.SetBusinessTemplateDTO) (*dto.BusinessResponseDTO, error) {
+	if err := s.validator.Struct(templateDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	ctx, err := actFor(ctx, templateDTO.BusinessID, "mark business templates")
+	if err != nil {
+		return nil, err
+	}
+
+	business, err := getBusiness(ctx, s.businessRepo, templateDTO.BusinessID)
+	if err != nil {
+		return nil, err
+	}
+	userID := GetUserIDFromContext(ctx)
+	if userID == nil || !business.IsOwner(*userID) {
+		return nil, NewForbiddenError("mark template businesses")
+	}
+	if business.IsTemplate == templateDTO.IsTemplate {
+		return dto.ToBusinessResponseDTO(business), nil
+	}
+
+	business.IsTemplate = templateDTO.IsTemplate
+	business.SetAuditFields(userID)
+	if err := s.businessRepo.Update(ctx, business); err != nil {
+		return nil, NewServiceError("failed to update business", err)
+	}
+	return dto.ToBusinessResponseDTO(business), nil
+}
+
+// CloneBusiness creates a business owned by the current user from the configuration of another
+// - its service menu and categories, bundles, settings, message templates and loyalty programs,
+// and optionally its staff roles - in one transaction. Clients, appointments and payments stay
+// behind. The caller must manage the business cloned, which need not be marked a template. The
+// clone starts on the free tier, so staff roles are only copied while its plan allows them.
+func (s *businessServiceImpl) CloneBusiness(ctx context.Context, cloneDTO dto.CloneBusinessDTO) (*dto.BusinessCloneDTO, error) {
+	userID := GetUserIDFromContext(ctx)
+	if userID == nil {
+		return nil, NewForbiddenError("clone a business")
+	}
+	if err := s.validator.Struct(cloneDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	templateCtx := domain.WithTenant(ctx, domain.TenantContext{BusinessID: cloneDTO.TemplateBusinessID})
+	if err := requireManager(templateCtx, s.staffRepo, cloneDTO.TemplateBusinessID, "clone the business"); err != nil {
+		return nil, err
+	}
+
+	template, err := s.cloneRepo.LoadTemplate(templateCtx, cloneDTO.TemplateBusinessID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("business", "id", cloneDTO.TemplateBusinessID)
+		}
+		return nil, NewServiceError("failed to load the business to clone", err)
+	}
+	options := domain.BusinessCloneOptions{
+		Name:           cloneDTO.Name,
+		DisplayName:    cloneDTO.DisplayName,
+		CopyStaffRoles: cloneDTO.CopyStaffRoles,
+	}
+	if cloneDTO.Email != nil {
+		options.Email = *cloneDTO.Email
+	}
+	if cloneDTO.TimeZone != nil {
+		options.TimeZone = *cloneDTO.TimeZone
+	}
+	clone, err := domain.NewBusinessClone(template, options, *userID, time.Now(), userID)
+	if err != nil {
+		return nil, toValidationError(err)
+	}
+	if err := clone.Business.CheckEntitlement(domain.EntitlementStaff, 0, len(clone.Staff)); err != nil {
+		return nil, err
+	}
+
+	if err := s.cloneRepo.Create(domain.WithTenant(ctx, domain.TenantContext{BusinessID: clone.Business.ID}), clone); err != nil {
+		return nil, NewServiceError("failed to clone business", err)
+	}
+	return dto.ToBusinessCloneDTO(template.Business.ID, clone), nil
+}
+
+// getBusiness retrieves a business, mapping a missing record to a not found error
+func getBusiness(ctx context.Context, businessRepo domain.BusinessRepository, businessID string) (*domain.Business, error) {
+	business, err := businessRepo.GetByID(ctx, businessID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("business", "id", businessID)
+		}
+		return nil, NewServiceError("failed to retrieve business", err)
+	}
+	return business, nil
+}
+
+// requireCapability checks that a business has a capability, refusing the action otherwise
+func requireCapability(ctx context.Context, businessRepo domain.BusinessRepository, businessID string, capability domain.Capability, action string) error {
+	business, err := getBusiness(ctx, businessRepo, businessID)
+	if err != nil {
+		return err
+	}
+	if !business.Can(capability) {
+		return NewForbiddenError(action + " in a solo business")
+	}
+	return nil
+}
+
+// resolveBookingStaff returns the staff member a booking is made with. Businesses with staff
+// calendars must name one; the others take every booking with their sole staff member.
+func resolveBookingStaff(ctx context.Context, businessRepo domain.BusinessRepository, staffRepo domain.StaffRepository, businessID, staffID string) (string, error) {
+	if staffID != "" {
+		return staffID, nil
+	}
+	business, err := getBusiness(ctx, businessRepo, businessID)
+	if err != nil {
+		return "", err
+	}
+	if business.Can(domain.CapabilityStaffCalendars) {
+		return "", validation.NewValidationError("staff_id is required")
+	}
+	activeStaff, err := staffRepo.FindActiveByBusinessID(ctx, businessID)
+	if err != nil {
+		return "", NewServiceError("failed to retrieve staff", err)
+	}
+	staff, err := domain.SoleStaff(activeStaff)
+	if err != nil {
+		return "", toValidationError(err)
+	}
+	return staff.ID, nil
+}
+
+// requireStaff checks that the caller works at the business, refusing the action otherwise
+func requireStaff(ctx context.Context, staffRepo domain.StaffRepository, businessID, action string) error {
+	userID := GetUserIDFromContext(ctx)
+	if userID == nil {
+		return NewForbiddenError(action)
+	}
+	if _, err := staffRepo.FindByBusinessAndUser(ctx, businessID, *userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewForbiddenError(action)
+		}
+		return NewServiceError("failed to retrieve staff member", err)
+	}
+	return nil
+}
+
+// requireManager checks that the caller is an owner or manager of the business, refusing the
+// action otherwise
+func requireManager(ctx context.Context, staffRepo domain.StaffRepository, businessID, action string) error {
+	userID := GetUserIDFromContext(ctx)
+	if userID == nil {
+		return NewForbiddenError(action)
+	}
+	staff, err := staffRepo.FindByBusinessAndUser(ctx, businessID, *userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewForbiddenError(action)
+		}
+		return NewServiceError("failed to retrieve staff member", err)
+	}
+	if !staff.CanManage() {
+		return NewForbiddenError(action)
+	}
+	return nil
+}
+
+// requireOwner checks that the caller is an owner of the business, refusing the action otherwise
+func requireOwner(ctx context.Context, staffRepo domain.StaffRepository, businessID, action string) error {
+	userID := GetUserIDFromContext(ctx)
+	if userID == nil {
+		return NewForbiddenError(action)
+	}
+	staff, err := staffRepo.FindByBusinessAndUser(ctx, businessID, *userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewForbiddenError(action)
+		}
+		return NewServiceError("failed to retrieve staff member", err)
+	}
+	if !staff.IsOwner() {
+		return NewForbiddenError(action)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+)
+
+// maxCalendarDays limits the date range of a calendar query to a month view
+const maxCalendarDays = 42
+
+// CalendarService defines the service interface for calendar views
+type CalendarService interface {
+	GetCalendar(ctx context.Context, businessID string, startDate, endDate time.Time, staffID *string) ([]*dto.CalendarEntryDTO, error)
+	RebuildProjection(ctx context.Context, businessID string) error
+}
+
+// calendarServiceImpl implements the CalendarService interface
+type calendarServiceImpl struct {
+	calendarRepo domain.CalendarRepository
+	staffRepo    domain.StaffRepository
+}
+
+// NewCalendarService creates a new calendar service
+func NewCalendarService(calendarRepo domain.CalendarRepository, staffRepo domain.StaffRepository) CalendarService {
+	return &calendarServiceImpl{
+		calendarRepo: calendarRepo,
+		staffRepo:    staffRepo,
+	}
+}
+
+// GetCalendar retrieves the appointments of a business between two local dates, inclusive. Only
+// staff of the business can see its calendar.
+func (s *calendarServiceImpl) GetCalendar(ctx context.Context, businessID string, startDate, endDate time.Time, staffID *string) ([]*dto.CalendarEntryDTO, error) {
+	if businessID == "" {
+		return nil, validation.NewValidationError("business_id is required")
+	}
+	if endDate.Before(startDate) {
+		return nil, validation.NewValidationError("end_date must not be before start_date")
+	}
+	if endDate.Sub(startDate) >= maxCalendarDays*24*time.Hour {
+		return nil, validation.NewValidationError("date range cannot exceed 42 days")
+	}
+	if err := requireStaff(ctx, s.staffRepo, businessID, "view the calendar"); err != nil {
+		return nil, err
+	}
+	ctx, err := actFor(ctx, businessID, "view the calendar")
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.calendarRepo.FindByDateRange(ctx, businessID, startDate, endDate, staffID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve calendar", err)
+	}
+
+	return dto.ToCalendarEntryDTOs(entries), nil
+}
+
+// RebuildProjection re-projects all appointments of a business from the source tables,
+// e.g. after the projection logic changed or for appointments written outside the API
+func (s *calendarServiceImpl) RebuildProjection(ctx context.Context, businessID string) error {
+	if !IsPlatformAdmin(ctx) {
+		return NewForbiddenError("rebuild the calendar projection")
+	}
+	if businessID == "" {
+		return validation.NewValidationError("business_id is required")
+	}
+
+	if err := s.calendarRepo.RebuildBusiness(ctx, businessID); err != nil {
+		return NewServiceError("failed to rebuild calendar projection", err)
+	}
+	return nil
+}
+
+// calendarProjector keeps the calendar read model up to date from domain events
+type calendarProjector struct {
+	calendarRepo domain.CalendarRepository
+}
+
+// NewCalendarProjector creates the event consumer that maintains the calendar read model
+func NewCalendarProjector(calendarRepo domain.CalendarRepository) EventConsumer {
+	return &calendarProjector{calendarRepo: calendarRepo}
+}
+
+// Name returns the consumer name used when replaying events
+func (p *calendarProjector) Name() string { return "calendar_projection" }
+
+// Handle re-projects the appointments affected by an event. Entries are always rebuilt
+// from the source tables rather than from the payload, so handling is idempotent.
+func (p *calendarProjector) Handle(ctx context.Context, event *domain.DomainEvent) error {
+	switch event.AggregateType {
+	case domain.AggregateAppointment:
+		return p.calendarRepo.RefreshAppointment(ctx, event.AggregateID)
+	case domain.AggregateClient:
+		if event.EventType == domain.EventClientUpdated {
+			return p.calendarRepo.RefreshClient(ctx, event.AggregateID)
+		}
+	case domain.AggregateStaff:
+		if event.EventType == domain.EventStaffUpdated {
+			return p.calendarRepo.RefreshStaff(ctx, event.AggregateID)
+		}
+	}
+	return nil
+}
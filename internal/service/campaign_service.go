@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// CampaignService defines the service interface for marketing campaigns and the clients they
+// target
+type CampaignService interface {
+	CreateCampaign(ctx context.Context, createDTO dto.CreateCampaignDTO) (*dto.CampaignResponseDTO, error)
+	GetCampaign(ctx context.Context, id string) (*dto.CampaignResponseDTO, error)
+	PreviewAudience(ctx context.Context, previewDTO dto.PreviewAudienceDTO) (*dto.AudiencePreviewDTO, error)
+	TargetClients(ctx context.Context, campaignID string) (*dto.CampaignTargetingDTO, error)
+	GetCampaignAnalytics(ctx context.Context, campaignID string, start, end *time.Time) (*dto.CampaignAnalyticsDTO, error)
+}
+
+// campaignServiceImpl implements the CampaignService interface
+type campaignServiceImpl struct {
+	campaignRepo       domain.BaseRepository[domain.Campaign]
+	campaignClientRepo domain.CampaignClientRepository
+	staffRepo          domain.StaffRepository
+	entitlementService EntitlementService
+	validator          *validator.Validate
+}
+
+// NewCampaignService creates a new campaign service
+func NewCampaignService(
+	campaignRepo domain.BaseRepository[domain.Campaign],
+	campaignClientRepo domain.CampaignClientRepository,
+	staffRepo domain.StaffRepository,
+	entitlementService EntitlementService,
+	validator *validator.Validate,
+) CampaignService {
+	return &campaignServiceImpl{
+		campaignRepo:       campaignRepo,
+		campaignClientRepo: campaignClientRepo,
+		staffRepo:          staffRepo,
+		entitlementService: entitlementService,
+		validator:          validator,
+	}
+}
+
+// CreateCampaign creates a campaign with the audience it targets. Clients are only added to it
+// by TargetClients.
+func (s *campaignServiceImpl) CreateCampaign(ctx context.Context, createDTO dto.CreateCampaignDTO) (*dto.CampaignResponseDTO, error) {
+	if err := s.validator.Struct(createDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	ctx, err := actFor(ctx, createDTO.BusinessID, "manage campaigns")
+	if err != nil {
+		return nil, err
+	}
+	if err := requireManager(ctx, s.staffRepo, createDTO.BusinessID, "manage campaigns"); err != nil {
+		return nil, err
+	}
+
+	audience, err := normalizeAudience(createDTO.TargetAudience)
+	if err != nil {
+		return nil, err
+	}
+	offerDetails := createDTO.OfferDetails
+	if offerDetails == nil {
+		offerDetails = map[string]any{}
+	}
+	encodedOffer, err := json.Marshal(offerDetails)
+	if err != nil {
+		return nil, validation.NewValidationError("offer_details must be a JSON object")
+	}
+
+	campaign := &domain.Campaign{
+		BusinessID:      createDTO.BusinessID,
+		Name:            strings.TrimSpace(createDTO.Name),
+		Description:     createDTO.Description,
+		CampaignType:    createDTO.CampaignType,
+		OfferType:       createDTO.OfferType,
+		OfferDetails:    string(encodedOffer),
+		StartDate:       createDTO.StartDate,
+		EndDate:         createDTO.EndDate,
+		IsActive:        true,
+		MessageTemplate: createDTO.MessageTemplate,
+	}
+	if err := campaign.SetTargetAudience(audience); err != nil {
+		return nil, NewServiceError("failed to create campaign", err)
+	}
+	if err := campaign.Validate(); err != nil {
+		return nil, toValidationError(err)
+	}
+	if err := s.entitlementService.Require(ctx, createDTO.BusinessID, domain.EntitlementCampaigns, 1); err != nil {
+		return nil, err
+	}
+
+	campaign.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.campaignRepo.Create(ctx, campaign); err != nil {
+		return nil, NewServiceError("failed to create campaign", err)
+	}
+	return dto.ToCampaignResponseDTO(campaign, 0), nil
+}
+
+// GetCampaign retrieves a campaign with the number of clients it targets
+func (s *campaignServiceImpl) GetCampaign(ctx context.Context, id string) (*dto.CampaignResponseDTO, error) {
+	campaign, err := s.getCampaign(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	targeted, err := s.campaignClientRepo.FindByCampaignID(ctx, campaign.ID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve campaign clients", err)
+	}
+	return dto.ToCampaignResponseDTO(campaign, len(targeted)), nil
+}
+
+// PreviewAudience counts the clients an audience would target now, without targeting them
+func (s *campaignServiceImpl) PreviewAudience(ctx context.Context, previewDTO dto.PreviewAudienceDTO) (*dto.AudiencePreviewDTO, error) {
+	if err := s.validator.Struct(previewDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	ctx, err := actFor(ctx, previewDTO.BusinessID, "manage campaigns")
+	if err != nil {
+		return nil, err
+	}
+	if err := requireManager(ctx, s.staffRepo, previewDTO.BusinessID, "manage campaigns"); err != nil {
+		return nil, err
+	}
+
+	audience, err := normalizeAudience(previewDTO.TargetAudience)
+	if err != nil {
+		return nil, err
+	}
+	count, err := s.campaignClientRepo.CountAudience(ctx, previewDTO.BusinessID, audience, time.Now())
+	if err != nil {
+		return nil, NewServiceError("failed to count audience", err)
+	}
+	return &dto.AudiencePreviewDTO{BusinessID: previewDTO.BusinessID, Clients: count}, nil
+}
+
+// TargetClients adds the clients in a campaign's audience to it. Clients are judged against the
+// audience as they are now; running it again adds clients who have since joined the audience
+// and leaves those already targeted alone.
+func (s *campaignServiceImpl) TargetClients(ctx context.Context, campaignID string) (*dto.CampaignTargetingDTO, error) {
+	campaign, err := s.getCampaign(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	ctx, err = actFor(ctx, campaign.BusinessID, "manage campaigns")
+	if err != nil {
+		return nil, err
+	}
+	if err := requireManager(ctx, s.staffRepo, campaign.BusinessID, "manage campaigns"); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if !campaign.IsActive || !now.Before(campaign.EndDate) {
+		return nil, validation.NewValidationError("the campaign has ended")
+	}
+
+	audience, err := campaign.GetTargetAudience()
+	if err != nil {
+		return nil, toValidationError(err)
+	}
+	added, err := s.campaignClientRepo.TargetClients(ctx, campaign, audience, now, GetUserIDFromContext(ctx))
+	if err != nil {
+		return nil, NewServiceError("failed to target clients", err)
+	}
+	targeted, err := s.campaignClientRepo.FindByCampaignID(ctx, campaign.ID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve campaign clients", err)
+	}
+	return &dto.CampaignTargetingDTO{CampaignID: campaign.ID, Added: added, Targeted: len(targeted)}, nil
+}
+
+// GetCampaignAnalytics aggregates how the clients targeted by a campaign responded to it,
+// from start, or since the campaign was created, to end, or now
+func (s *campaignServiceImpl) GetCampaignAnalytics(ctx context.Context, campaignID string, start, end *time.Time) (*dto.CampaignAnalyticsDTO, error) {
+	campaign, err := s.getCampaign(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	ctx, err = actFor(ctx, campaign.BusinessID, "manage campaigns")
+	if err != nil {
+		return nil, err
+	}
+	if err := requireManager(ctx, s.staffRepo, campaign.BusinessID, "manage campaigns"); err != nil {
+		return nil, err
+	}
+
+	from, to, err := campaign.AnalyticsRange(start, end, time.Now())
+	if err != nil {
+		return nil, toValidationError(err)
+	}
+	analytics, err := s.campaignClientRepo.GetCampaignAnalytics(ctx, campaign, from, to)
+	if err != nil {
+		return nil, NewServiceError("failed to aggregate campaign analytics", err)
+	}
+	return dto.ToCampaignAnalyticsDTO(analytics), nil
+}
+
+// getCampaign retrieves a campaign, mapping a missing record to a not found error
+func (s *campaignServiceImpl) getCampaign(ctx context.Context, id string) (*domain.Campaign, error) {
+	campaign, err := s.campaignRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("campaign", "id", id)
+		}
+		return nil, NewServiceError("failed to retrieve campaign", err)
+	}
+	return campaign, nil
+}
+
+// normalizeAudience validates an audience, bringing its tags to the form clients' tags are
+// stored in
+func normalizeAudience(audience domain.TargetAudience) (domain.TargetAudience, error) {
+	tags, err := domain.NormalizeClientTags(audience.Tags)
+	if err != nil {
+		return audience, toValidationError(err)
+	}
+	audience.Tags = tags
+	if err := audience.Validate(); err != nil {
+		return audience, toValidationError(err)
+	}
+	return audience, nil
+}
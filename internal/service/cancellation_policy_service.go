@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// CancellationPolicyService defines the service interface for how businesses treat late
+// cancellations and no-shows
+type CancellationPolicyService interface {
+	GetCancellationPolicy(ctx context.Context, businessID string) (*dto.CancellationPolicyDTO, error)
+	SetCancellationPolicy(ctx context.Context, policyDTO dto.SetCancellationPolicyDTO) (*dto.CancellationPolicyDTO, error)
+	ClearClientNoShows(ctx context.Context, clientID string) (*dto.ClientResponseDTO, error)
+}
+
+// cancellationPolicyServiceImpl implements the CancellationPolicyService interface
+type cancellationPolicyServiceImpl struct {
+	settingsRepo domain.BusinessSettingsRepository
+	clientRepo   domain.BaseRepository[domain.Client]
+	staffRepo    domain.StaffRepository
+	validator    *validator.Validate
+}
+
+// NewCancellationPolicyService creates a new cancellation policy service
+func NewCancellationPolicyService(
+	settingsRepo domain.BusinessSettingsRepository,
+	clientRepo domain.BaseRepository[domain.Client],
+	staffRepo domain.StaffRepository,
+	validator *validator.Validate,
+) CancellationPolicyService {
+	return &cancellationPolicyServiceImpl{
+		settingsRepo: settingsRepo,
+		clientRepo:   clientRepo,
+		staffRepo:    staffRepo,
+		validator:    validator,
+	}
+}
+
+// GetCancellationPolicy returns how a business treats late cancellations and no-shows. Any
+// staff member of the business can see it.
+func (s *cancellationPolicyServiceImpl) GetCancellationPolicy(ctx context.Context, businessID string) (*dto.CancellationPolicyDTO, error) {
+	if businessID == "" {
+		return nil, validation.NewValidationError("business_id is required")
+	}
+	if err := requireStaff(ctx, s.staffRepo, businessID, "view the cancellation policy"); err != nil {
+		return nil, err
+	}
+	settings, err := s.getSettings(ctx, businessID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToCancellationPolicyDTO(businessID, settings.GetCancellationPolicy()), nil
+}
+
+// SetCancellationPolicy replaces the cancellation policy of a business. It applies to
+// cancellations and no-shows from then on; clients already flagged stay flagged. Only owners
+// and managers can set it.
+func (s *cancellationPolicyServiceImpl) SetCancellationPolicy(ctx context.Context, policyDTO dto.SetCancellationPolicyDTO) (*dto.CancellationPolicyDTO, error) {
+	if err := s.validator.Struct(policyDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	if err := requireManager(ctx, s.staffRepo, policyDTO.BusinessID, "set the cancellation policy"); err != nil {
+		return nil, err
+	}
+
+	policy := domain.CancellationPolicy{
+		NoticeHours: policyDTO.NoticeHours,
+		NoShowFee:   policyDTO.NoShowFee,
+		MaxNoShows:  policyDTO.MaxNoShows,
+	}
+	settings, err := s.getSettings(ctx, policyDTO.BusinessID)
+	if err != nil {
+		return nil, err
+	}
+	updated := domain.BusinessSettings{BusinessID: policyDTO.BusinessID, CalendarStartHour: 9, CalendarEndHour: 18,
+		DepositWindowHours: domain.DefaultDepositWindowHours}
+	if settings != nil {
+		updated = *settings
+	}
+	if err := updated.SetCancellationPolicy(policy); err != nil {
+		return nil, toValidationError(err)
+	}
+	updated.SetAuditFields(GetUserIDFromContext(ctx))
+	if settings == nil {
+		err = s.settingsRepo.Create(ctx, &updated)
+	} else {
+		err = s.settingsRepo.Update(ctx, &updated)
+	}
+	if err != nil {
+		return nil, NewServiceError("failed to update business settings", err)
+	}
+	return dto.ToCancellationPolicyDTO(policyDTO.BusinessID, policy), nil
+}
+
+// ClearClientNoShows forgives a client's no-shows, resetting their counter and lifting the
+// deposit requirement. Only owners and managers can clear them.
+func (s *cancellationPolicyServiceImpl) ClearClientNoShows(ctx context.Context, clientID string) (*dto.ClientResponseDTO, error) {
+	if clientID == "" {
+		return nil, validation.NewValidationError("client_id is required")
+	}
+	client, err := s.clientRepo.GetByID(ctx, clientID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("client", "id", clientID)
+		}
+		return nil, NewServiceError("failed to retrieve client", err)
+	}
+	if err := requireManager(ctx, s.staffRepo, client.BusinessID, "clear client no-shows"); err != nil {
+		return nil, err
+	}
+
+	client.ClearNoShows()
+	client.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.clientRepo.Update(ctx, client); err != nil {
+		return nil, NewServiceError("failed to update client", err)
+	}
+	return dto.ToClientResponseDTO(client), nil
+}
+
+// getSettings retrieves the settings of a business, nil when it has none yet
+func (s *cancellationPolicyServiceImpl) getSettings(ctx context.Context, businessID string) (*domain.BusinessSettings, error) {
+	settings, err := s.settingsRepo.GetByBusinessID(ctx, businessID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, NewServiceError("failed to retrieve business settings", err)
+	}
+	return settings, nil
+}
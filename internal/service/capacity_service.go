@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/notification"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// capacityAlertDays is how many days ahead, starting today, businesses are notified of warnings
+const capacityAlertDays = 3
+
+// CapacityService defines the service interface for soft capacity warnings
+type CapacityService interface {
+	GetCapacityWarnings(ctx context.Context, businessID string, startDate, endDate time.Time) ([]*dto.CapacityWarningDTO, error)
+	NotifyUpcomingWarnings(ctx context.Context, now time.Time) (*dto.CapacityNotificationRunDTO, error)
+}
+
+// capacityServiceImpl implements the CapacityService interface
+type capacityServiceImpl struct {
+	calendarRepo domain.CalendarRepository
+	settingsRepo domain.BusinessSettingsRepository
+	staffRepo    domain.StaffRepository
+	businessRepo domain.BusinessRepository
+	alertRepo    domain.CapacityAlertRepository
+	sender       notification.NotificationSender
+}
+
+// NewCapacityService creates a new capacity service
+func NewCapacityService(
+	calendarRepo domain.CalendarRepository,
+	settingsRepo domain.BusinessSettingsRepository,
+	staffRepo domain.StaffRepository,
+	businessRepo domain.BusinessRepository,
+	alertRepo domain.CapacityAlertRepository,
+	sender notification.NotificationSender,
+) CapacityService {
+	return &capacityServiceImpl{
+		calendarRepo: calendarRepo,
+		settingsRepo: settingsRepo,
+		staffRepo:    staffRepo,
+		businessRepo: businessRepo,
+		alertRepo:    alertRepo,
+		sender:       sender,
+	}
+}
+
+// GetCapacityWarnings retrieves the capacity warnings of a business between two local dates, inclusive
+func (s *capacityServiceImpl) GetCapacityWarnings(ctx context.Context, businessID string, startDate, endDate time.Time) ([]*dto.CapacityWarningDTO, error) {
+	if businessID == "" {
+		return nil, validation.NewValidationError("business_id is required")
+	}
+	if endDate.Before(startDate) {
+		return nil, validation.NewValidationError("end_date must not be before start_date")
+	}
+	if endDate.Sub(startDate) >= maxCalendarDays*24*time.Hour {
+		return nil, validation.NewValidationError("date range cannot exceed 42 days")
+	}
+
+	warnings, err := s.evaluate(ctx, businessID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToCapacityWarningDTOs(warnings), nil
+}
+
+// evaluate computes the warnings of each day in a date range from the calendar read model
+func (s *capacityServiceImpl) evaluate(ctx context.Context, businessID string, startDate, endDate time.Time) ([]*domain.CapacityWarning, error) {
+	settings, err := s.settingsRepo.GetByBusinessID(ctx, businessID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewServiceError("failed to retrieve business settings", err)
+		}
+		settings = nil
+	}
+	policy := domain.CapacityPolicyFor(settings)
+
+	openMinutes := 9 * 60 // Default calendar hours are 9:00 to 18:00
+	if settings != nil {
+		openMinutes = (settings.CalendarEndHour - settings.CalendarStartHour) * 60
+	}
+	staff, err := s.staffRepo.FindActiveByBusinessID(ctx, businessID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve staff", err)
+	}
+
+	entries, err := s.calendarRepo.FindByDateRange(ctx, businessID, startDate, endDate, nil)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve calendar", err)
+	}
+
+	// Entries are ordered by date, so days are evaluated in order
+	var days []string
+	byDay := map[string][]*domain.CalendarEntry{}
+	for _, entry := range entries {
+		day := entry.CalendarDate.Format("2006-01-02")
+		if _, ok := byDay[day]; !ok {
+			days = append(days, day)
+		}
+		byDay[day] = append(byDay[day], entry)
+	}
+
+	var warnings []*domain.CapacityWarning
+	for _, day := range days {
+		// Inactive staff may still have appointments left on the calendar
+		working := map[string]bool{}
+		for _, entry := range byDay[day] {
+			working[entry.StaffID] = true
+		}
+		staffCount := max(len(staff), len(working))
+
+		date := byDay[day][0].CalendarDate
+		warnings = append(warnings, policy.EvaluateDay(date, byDay[day], staffCount*openMinutes)...)
+	}
+	return warnings, nil
+}
+
+// NotifyUpcomingWarnings emails each business the capacity warnings for the next few days that
+// it has not been notified of yet
+func (s *capacityServiceImpl) NotifyUpcomingWarnings(ctx context.Context, now time.Time) (*dto.CapacityNotificationRunDTO, error) {
+	// Widen the range by a day on each side so every business's local dates are covered
+	businessIDs, err := s.alertRepo.FindBusinessesWithAppointments(ctx, now.AddDate(0, 0, -1), now.AddDate(0, 0, capacityAlertDays))
+	if err != nil {
+		return nil, NewServiceError("failed to find businesses with upcoming appointments", err)
+	}
+
+	run := &dto.CapacityNotificationRunDTO{}
+	for _, businessID := range businessIDs {
+		notified, err := s.notifyBusiness(ctx, businessID, now)
+		if err != nil {
+			log.Warn().Err(err).Str("business_id", businessID).Msg("Failed to notify capacity warnings")
+			continue
+		}
+		if notified > 0 {
+			run.Businesses++
+			run.Warnings += notified
+		}
+	}
+	return run, nil
+}
+
+// notifyBusiness sends one business a summary of its new warnings and returns how many there were
+func (s *capacityServiceImpl) notifyBusiness(ctx context.Context, businessID string, now time.Time) (int, error) {
+	business, err := s.businessRepo.GetByID(ctx, businessID)
+	if err != nil {
+		return 0, err
+	}
+	location, err := time.LoadLocation(business.TimeZone)
+	if err != nil {
+		location = time.UTC
+	}
+	local := now.In(location)
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+
+	warnings, err := s.evaluate(ctx, businessID, today, today.AddDate(0, 0, capacityAlertDays-1))
+	if err != nil {
+		return 0, err
+	}
+
+	var lines []string
+	for _, warning := range warnings {
+		isNew, err := s.alertRepo.MarkNotified(ctx, &domain.CapacityAlert{
+			BusinessID: businessID,
+			AlertDate:  warning.Date,
+			Kind:       warning.Kind,
+			StaffID:    warning.StaffID,
+			NotifiedAt: now,
+		})
+		if err != nil {
+			return 0, err
+		}
+		if isNew {
+			lines = append(lines, describeCapacityWarning(warning, location))
+		}
+	}
+	if len(lines) == 0 {
+		return 0, nil
+	}
+
+	subject := "Busy days ahead at " + business.GetDisplayName()
+	err = s.sender.Send(ctx, notification.Message{
+		BusinessID: businessID,
+		Channel:    domain.MessageChannelEmail,
+		Recipient:  business.Email,
+		Subject:    &subject,
+		Body:       "Your schedule is getting full:\n\n- " + strings.Join(lines, "\n- "),
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(lines), nil
+}
+
+// describeCapacityWarning renders a warning as a line of the notification
+func describeCapacityWarning(warning *domain.CapacityWarning, location *time.Location) string {
+	day := warning.Date.Format("Monday 2 January")
+	switch warning.Kind {
+	case domain.CapacityWarningDayBooked:
+		return fmt.Sprintf("%s is %d%% booked", day, warning.BookedPercent)
+	case domain.CapacityWarningNoBreak:
+		line := fmt.Sprintf("%s: %s works %s to %s without a break", day, *warning.StaffName,
+			warning.Start.In(location).Format("15:04"), warning.End.In(location).Format("15:04"))
+		if warning.SuggestedBreak != nil {
+			line += fmt.Sprintf("; consider blocking %d minutes at %s", warning.SuggestedBreak.Minutes,
+				warning.SuggestedBreak.Start.In(location).Format("15:04"))
+		}
+		return line
+	}
+	return day
+}
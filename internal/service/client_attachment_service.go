@@ -0,0 +1,479 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/residency"
+	"github.com/assimoes/beautix/internal/infrastructure/storage"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// attachmentCleanupBatch bounds the attachments a cleanup run removes, leaving the rest to the
+// next run
+const attachmentCleanupBatch = 500
+
+// ClientAttachmentService defines the service interface for the photos and documents attached to
+// clients and their appointments. Files are sent straight to object storage through presigned
+// URLs and checked when the upload is completed; they are only ever served through signed URLs.
+type ClientAttachmentService interface {
+	CreateClientAttachment(ctx context.Context, createDTO dto.CreateClientAttachmentDTO) (*dto.ClientAttachmentTicketDTO, error)
+	CompleteClientAttachment(ctx context.Context, id string) (*dto.ClientAttachmentResponseDTO, error)
+	GetClientAttachments(ctx context.Context, clientID string, appointmentID *string) ([]*dto.ClientAttachmentResponseDTO, error)
+	SetClientAttachmentConsent(ctx context.Context, consentDTO dto.SetClientAttachmentConsentDTO) (*dto.ClientAttachmentResponseDTO, error)
+	DeleteClientAttachment(ctx context.Context, id string) error
+	GetMarketingPhotos(ctx context.Context, businessID string) ([]*dto.ClientAttachmentResponseDTO, error)
+	GetAttachmentUsage(ctx context.Context, businessID string) (*dto.AttachmentUsageDTO, error)
+	SetAttachmentQuota(ctx context.Context, quotaDTO dto.SetAttachmentQuotaDTO) (*dto.AttachmentUsageDTO, error)
+	CleanupClientAttachments(ctx context.Context, now time.Time) (*dto.ClientAttachmentCleanupRunDTO, error)
+}
+
+// clientAttachmentServiceImpl implements the ClientAttachmentService interface
+type clientAttachmentServiceImpl struct {
+	attachmentRepo  domain.ClientAttachmentRepository
+	clientRepo      domain.BaseRepository[domain.Client]
+	appointmentRepo domain.AppointmentRepository
+	businessRepo    domain.BusinessRepository
+	staffRepo       domain.StaffRepository
+	store           storage.Store
+	buckets         *residency.Buckets
+	urlExpiry       time.Duration
+	validator       *validator.Validate
+}
+
+// NewClientAttachmentService creates a new client attachment service. Upload and download URLs
+// stay valid for urlExpiry.
+func NewClientAttachmentService(
+	attachmentRepo domain.ClientAttachmentRepository,
+	clientRepo domain.BaseRepository[domain.Client],
+	appointmentRepo domain.AppointmentRepository,
+	businessRepo domain.BusinessRepository,
+	staffRepo domain.StaffRepository,
+	store storage.Store,
+	buckets *residency.Buckets,
+	urlExpiry time.Duration,
+	validator *validator.Validate,
+) ClientAttachmentService {
+	return &clientAttachmentServiceImpl{
+		attachmentRepo:  attachmentRepo,
+		clientRepo:      clientRepo,
+		appointmentRepo: appointmentRepo,
+		businessRepo:    businessRepo,
+		staffRepo:       staffRepo,
+		store:           store,
+		buckets:         buckets,
+		urlExpiry:       urlExpiry,
+		validator:       validator,
+	}
+}
+
+// CreateClientAttachment records an attachment and signs the URL its file is sent to, in the
+// bucket of the business's data region. The declared size counts against the business's quota
+// from now on.
+func (s *clientAttachmentServiceImpl) CreateClientAttachment(ctx context.Context, createDTO dto.CreateClientAttachmentDTO) (*dto.ClientAttachmentTicketDTO, error) {
+	if err := s.validator.Struct(createDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	if err := s.requirePermission(ctx, createDTO.BusinessID, domain.PermissionClientsEdit, "attach files to clients"); err != nil {
+		return nil, err
+	}
+	if err := s.checkClient(ctx, createDTO.BusinessID, createDTO.ClientID, createDTO.AppointmentID); err != nil {
+		return nil, err
+	}
+	business, err := getBusiness(ctx, s.businessRepo, createDTO.BusinessID)
+	if err != nil {
+		return nil, err
+	}
+	used, err := s.attachmentRepo.SumUsage(ctx, business.ID)
+	if err != nil {
+		return nil, NewServiceError("failed to total attachment storage", err)
+	}
+	if quota := business.AttachmentQuotaBytes(); used+createDTO.SizeBytes > quota {
+		return nil, validation.NewValidationError(fmt.Sprintf(
+			"the business has used %d of its %d MB of storage for attachments", used>>20, quota>>20))
+	}
+	region := business.DataRegion
+	if region == "" {
+		region = domain.DefaultDataRegion
+	}
+	bucket, err := s.buckets.Select(region)
+	if err != nil {
+		return nil, NewServiceError("no storage is available for the business's data region", err)
+	}
+
+	now := time.Now().UTC()
+	attachment := &domain.ClientAttachment{
+		BaseModel:       domain.BaseModel{ID: domain.NewID(), CreatedBy: GetUserIDFromContext(ctx)},
+		BusinessID:      createDTO.BusinessID,
+		ClientID:        createDTO.ClientID,
+		AppointmentID:   createDTO.AppointmentID,
+		Kind:            createDTO.Kind,
+		FileName:        path.Base(strings.ReplaceAll(createDTO.FileName, `\`, "/")),
+		Caption:         createDTO.Caption,
+		ContentType:     createDTO.ContentType,
+		SizeBytes:       createDTO.SizeBytes,
+		Status:          domain.ClientAttachmentPending,
+		Bucket:          bucket,
+		UploadExpiresAt: now.Add(s.urlExpiry).Truncate(time.Second),
+	}
+	attachment.UploadKey = attachment.UploadKeyFor()
+	if err := attachment.Validate(); err != nil {
+		return nil, toValidationError(err)
+	}
+
+	uploadURL, err := s.store.SignedUploadURL(bucket, attachment.UploadKey, attachment.ContentType, attachment.SizeBytes, s.urlExpiry)
+	if err != nil {
+		return nil, NewServiceError("failed to sign upload URL", err)
+	}
+	if err := s.attachmentRepo.Create(ctx, attachment); err != nil {
+		return nil, NewServiceError("failed to create client attachment", err)
+	}
+	return &dto.ClientAttachmentTicketDTO{
+		Attachment: dto.ToClientAttachmentResponseDTO(attachment),
+		UploadURL:  uploadURL,
+		Method:     http.MethodPut,
+		Headers: []dto.UploadHeaderDTO{
+			{Name: "Content-Type", Value: attachment.ContentType},
+			{Name: "Content-Length", Value: strconv.FormatInt(attachment.SizeBytes, 10)},
+		},
+		ExpiresAt: attachment.UploadExpiresAt,
+	}, nil
+}
+
+// CompleteClientAttachment checks an uploaded file and keeps it. Images are turned upright, scaled
+// down and stripped of their metadata, which may include where they were taken; files that are
+// not what they were declared as are rejected.
+func (s *clientAttachmentServiceImpl) CompleteClientAttachment(ctx context.Context, id string) (*dto.ClientAttachmentResponseDTO, error) {
+	attachment, err := s.getAttachment(ctx, id, domain.PermissionClientsEdit, "attach files to clients")
+	if err != nil {
+		return nil, err
+	}
+	if attachment.Status == domain.ClientAttachmentStored {
+		return s.withURL(attachment)
+	}
+	now := time.Now().UTC()
+	if !attachment.CanComplete(now) {
+		if attachment.Status == domain.ClientAttachmentRejected && attachment.Error != nil {
+			return nil, validation.NewValidationError("the upload was rejected: " + *attachment.Error)
+		}
+		return nil, validation.NewValidationError("the upload has expired; request a new one")
+	}
+
+	data, err := s.store.Get(ctx, attachment.Bucket, attachment.UploadKey, domain.MaxClientAttachmentBytes)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrNotFound):
+			return nil, validation.NewValidationError("the file has not been uploaded yet")
+		case errors.Is(err, storage.ErrTooLarge):
+			return nil, s.reject(ctx, attachment, "the file is larger than allowed")
+		}
+		return nil, NewServiceError("failed to read uploaded file", err)
+	}
+	object := storage.Object{Bucket: attachment.Bucket, ContentType: attachment.ContentType, Body: data}
+	extension := "pdf"
+	var width, height *int
+	if attachment.ContentType == "application/pdf" {
+		if !bytes.HasPrefix(data, []byte("%PDF-")) {
+			return nil, s.reject(ctx, attachment, "the file is not a PDF")
+		}
+	} else {
+		processed, err := storage.ProcessImage(data, attachment.ContentType, domain.ClientPhotoMaxDimension)
+		if err != nil {
+			if errors.Is(err, storage.ErrInvalidImage) {
+				return nil, s.reject(ctx, attachment, err.Error())
+			}
+			return nil, NewServiceError("failed to process image", err)
+		}
+		object.ContentType, object.Body, extension = processed.ContentType, processed.Body, processed.Extension
+		width, height = &processed.Width, &processed.Height
+	}
+
+	object.Key = attachment.ObjectKeyFor(extension)
+	if err := s.store.Put(ctx, object); err != nil {
+		return nil, NewServiceError("failed to store attachment", err)
+	}
+	attachment.Store(object.Key, object.ContentType, int64(len(object.Body)), width, height, now)
+	if err := s.attachmentRepo.Update(ctx, attachment); err != nil {
+		return nil, NewServiceError("failed to update client attachment", err)
+	}
+	if err := s.store.Delete(ctx, attachment.Bucket, attachment.UploadKey); err != nil {
+		log.Warn().Err(err).Str("attachment_id", attachment.ID).Msg("Failed to remove uploaded file")
+	}
+	return s.withURL(attachment)
+}
+
+// GetClientAttachments lists the stored attachments of a client, newest first, only those of an
+// appointment when one is given
+func (s *clientAttachmentServiceImpl) GetClientAttachments(ctx context.Context, clientID string, appointmentID *string) ([]*dto.ClientAttachmentResponseDTO, error) {
+	client, err := s.clientRepo.GetByID(ctx, clientID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("client", "id", clientID)
+		}
+		return nil, NewServiceError("failed to retrieve client", err)
+	}
+	if err := s.requirePermission(ctx, client.BusinessID, domain.PermissionClientsView, "view client attachments"); err != nil {
+		return nil, err
+	}
+	attachments, err := s.attachmentRepo.FindByClient(ctx, clientID, appointmentID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve client attachments", err)
+	}
+	return s.withURLs(attachments)
+}
+
+// SetClientAttachmentConsent records a client agreeing to the use of a photo in marketing, or
+// withdrawing their agreement, which takes it out of the marketing photos at once
+func (s *clientAttachmentServiceImpl) SetClientAttachmentConsent(ctx context.Context, consentDTO dto.SetClientAttachmentConsentDTO) (*dto.ClientAttachmentResponseDTO, error) {
+	if err := s.validator.Struct(consentDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	attachment, err := s.getAttachment(ctx, consentDTO.AttachmentID, domain.PermissionClientsEdit, "record consent for client photos")
+	if err != nil {
+		return nil, err
+	}
+	userID := GetUserIDFromContext(ctx)
+	if err := attachment.SetMarketingConsent(consentDTO.Consented, userID, time.Now().UTC()); err != nil {
+		return nil, toValidationError(err)
+	}
+	attachment.SetAuditFields(userID)
+	if err := s.attachmentRepo.Update(ctx, attachment); err != nil {
+		return nil, NewServiceError("failed to update client attachment", err)
+	}
+	return s.withURL(attachment)
+}
+
+// DeleteClientAttachment deletes an attachment, which stops counting against the quota at once.
+// Its files are removed by the next cleanup.
+func (s *clientAttachmentServiceImpl) DeleteClientAttachment(ctx context.Context, id string) error {
+	attachment, err := s.getAttachment(ctx, id, domain.PermissionClientsEdit, "delete client attachments")
+	if err != nil {
+		return err
+	}
+	if err := attachment.Delete(); err != nil {
+		return toValidationError(err)
+	}
+	attachment.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.attachmentRepo.Update(ctx, attachment); err != nil {
+		return NewServiceError("failed to delete client attachment", err)
+	}
+	return nil
+}
+
+// GetMarketingPhotos lists the photos of a business its clients agreed to the use of in
+// marketing, newest first
+func (s *clientAttachmentServiceImpl) GetMarketingPhotos(ctx context.Context, businessID string) ([]*dto.ClientAttachmentResponseDTO, error) {
+	if err := s.requirePermission(ctx, businessID, domain.PermissionCampaignsView, "view marketing photos"); err != nil {
+		return nil, err
+	}
+	attachments, err := s.attachmentRepo.FindUsableInMarketing(ctx, businessID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve marketing photos", err)
+	}
+	return s.withURLs(attachments)
+}
+
+// GetAttachmentUsage returns the storage a business uses for client attachments, and its quota
+func (s *clientAttachmentServiceImpl) GetAttachmentUsage(ctx context.Context, businessID string) (*dto.AttachmentUsageDTO, error) {
+	if !IsPlatformAdmin(ctx) {
+		if err := s.requirePermission(ctx, businessID, domain.PermissionClientsEdit, "view attachment storage"); err != nil {
+			return nil, err
+		}
+	}
+	business, err := getBusiness(ctx, s.businessRepo, businessID)
+	if err != nil {
+		return nil, err
+	}
+	return s.usage(ctx, business)
+}
+
+// SetAttachmentQuota changes the storage a business may use for client attachments, or restores
+// the default (platform admin only). Lowering it below what is used blocks new attachments
+// without removing any.
+func (s *clientAttachmentServiceImpl) SetAttachmentQuota(ctx context.Context, quotaDTO dto.SetAttachmentQuotaDTO) (*dto.AttachmentUsageDTO, error) {
+	if !IsPlatformAdmin(ctx) {
+		return nil, NewForbiddenError("set attachment quotas")
+	}
+	if err := s.validator.Struct(quotaDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	business, err := getBusiness(ctx, s.businessRepo, quotaDTO.BusinessID)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes domain.ChangeSet
+	changes.Set("attachment_quota_mb", quotaDTO.QuotaMB)
+	if _, err := s.businessRepo.UpdateFields(ctx, business.ID, changes); err != nil {
+		return nil, NewServiceError("failed to set attachment quota", err)
+	}
+	business.AttachmentQuotaMB = quotaDTO.QuotaMB
+	return s.usage(ctx, business)
+}
+
+// CleanupClientAttachments removes the files of deleted, rejected and abandoned attachments. An
+// attachment that fails is logged and retried on the next run.
+func (s *clientAttachmentServiceImpl) CleanupClientAttachments(ctx context.Context, now time.Time) (*dto.ClientAttachmentCleanupRunDTO, error) {
+	attachments, err := s.attachmentRepo.FindRemovable(ctx, now.Add(-domain.ImageUploadGracePeriod), attachmentCleanupBatch)
+	if err != nil {
+		return nil, NewServiceError("failed to find removable client attachments", err)
+	}
+
+	run := &dto.ClientAttachmentCleanupRunDTO{}
+	for _, attachment := range attachments {
+		attachmentCtx := domain.WithTenant(ctx, domain.TenantContext{BusinessID: attachment.BusinessID})
+		if err := s.remove(attachmentCtx, attachment, now); err != nil {
+			log.Warn().Err(err).Str("business_id", attachment.BusinessID).Str("attachment_id", attachment.ID).
+				Msg("Failed to remove client attachment")
+			run.Failed++
+			continue
+		}
+		run.Removed++
+	}
+	return run, nil
+}
+
+// remove deletes the files of an attachment from storage
+func (s *clientAttachmentServiceImpl) remove(ctx context.Context, attachment *domain.ClientAttachment, now time.Time) error {
+	if err := s.store.Delete(ctx, attachment.Bucket, attachment.UploadKey); err != nil {
+		return err
+	}
+	if attachment.ObjectKey != nil {
+		if err := s.store.Delete(ctx, attachment.Bucket, *attachment.ObjectKey); err != nil {
+			return err
+		}
+	}
+	attachment.MarkRemoved(now)
+	return s.attachmentRepo.Update(ctx, attachment)
+}
+
+// reject records an attachment as rejected, returning the validation error to report
+func (s *clientAttachmentServiceImpl) reject(ctx context.Context, attachment *domain.ClientAttachment, message string) error {
+	attachment.Reject(message)
+	if err := s.attachmentRepo.Update(ctx, attachment); err != nil {
+		return NewServiceError("failed to update client attachment", err)
+	}
+	return validation.NewValidationError("the upload was rejected: " + message)
+}
+
+// usage totals the storage a business uses for client attachments
+func (s *clientAttachmentServiceImpl) usage(ctx context.Context, business *domain.Business) (*dto.AttachmentUsageDTO, error) {
+	used, err := s.attachmentRepo.SumUsage(ctx, business.ID)
+	if err != nil {
+		return nil, NewServiceError("failed to total attachment storage", err)
+	}
+	return &dto.AttachmentUsageDTO{BusinessID: business.ID, UsedBytes: used, QuotaBytes: business.AttachmentQuotaBytes()}, nil
+}
+
+// withURLs converts attachments to DTOs with signed URLs to view them
+func (s *clientAttachmentServiceImpl) withURLs(attachments []*domain.ClientAttachment) ([]*dto.ClientAttachmentResponseDTO, error) {
+	result := make([]*dto.ClientAttachmentResponseDTO, 0, len(attachments))
+	for _, attachment := range attachments {
+		attachmentDTO, err := s.withURL(attachment)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, attachmentDTO)
+	}
+	return result, nil
+}
+
+// withURL converts an attachment to a DTO, with a signed URL to view it once stored. The file is
+// saved under the name it was uploaded with, given the extension of the format it is kept in.
+func (s *clientAttachmentServiceImpl) withURL(attachment *domain.ClientAttachment) (*dto.ClientAttachmentResponseDTO, error) {
+	attachmentDTO := dto.ToClientAttachmentResponseDTO(attachment)
+	if attachment.Status != domain.ClientAttachmentStored || attachment.ObjectKey == nil {
+		return attachmentDTO, nil
+	}
+	fileName := strings.TrimSuffix(attachment.FileName, path.Ext(attachment.FileName)) + path.Ext(*attachment.ObjectKey)
+	url, err := s.store.SignedURL(attachment.Bucket, *attachment.ObjectKey, fileName, s.urlExpiry)
+	if err != nil {
+		return nil, NewServiceError("failed to sign attachment URL", err)
+	}
+	expiresAt := time.Now().UTC().Add(s.urlExpiry).Truncate(time.Second)
+	attachmentDTO.URL = &url
+	attachmentDTO.URLExpiresAt = &expiresAt
+	return attachmentDTO, nil
+}
+
+// checkClient checks that a client, and the appointment when one is given, can have files
+// attached at a business
+func (s *clientAttachmentServiceImpl) checkClient(ctx context.Context, businessID, clientID string, appointmentID *string) error {
+	client, err := s.clientRepo.GetByID(ctx, clientID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewNotFoundError("client", "id", clientID)
+		}
+		return NewServiceError("failed to retrieve client", err)
+	}
+	if client.BusinessID != businessID {
+		return NewNotFoundError("client", "id", clientID)
+	}
+	if client.AnonymizedAt != nil {
+		return validation.NewValidationError("files cannot be attached to an anonymized client")
+	}
+	if appointmentID == nil {
+		return nil
+	}
+	appointment, err := s.appointmentRepo.GetByID(ctx, *appointmentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewNotFoundError("appointment", "id", *appointmentID)
+		}
+		return NewServiceError("failed to retrieve appointment", err)
+	}
+	if appointment.BusinessID != businessID {
+		return NewNotFoundError("appointment", "id", *appointmentID)
+	}
+	if appointment.ClientID != clientID {
+		return validation.NewValidationError("the appointment is not the client's")
+	}
+	return nil
+}
+
+// getAttachment retrieves an attachment the caller has a permission for
+func (s *clientAttachmentServiceImpl) getAttachment(ctx context.Context, id string, permission domain.Permission, action string) (*domain.ClientAttachment, error) {
+	attachment, err := s.attachmentRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("client attachment", "id", id)
+		}
+		return nil, NewServiceError("failed to retrieve client attachment", err)
+	}
+	if err := s.requirePermission(ctx, attachment.BusinessID, permission, action); err != nil {
+		return nil, err
+	}
+	return attachment, nil
+}
+
+// requirePermission checks that the caller is active staff of the business with a permission,
+// refusing the action otherwise
+func (s *clientAttachmentServiceImpl) requirePermission(ctx context.Context, businessID string, permission domain.Permission, action string) error {
+	userID := GetUserIDFromContext(ctx)
+	if userID == nil {
+		return NewForbiddenError(action)
+	}
+	staff, err := s.staffRepo.FindByBusinessAndUser(ctx, businessID, *userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewForbiddenError(action)
+		}
+		return NewServiceError("failed to retrieve staff member", err)
+	}
+	if !staff.IsActive || !staff.Can(permission) {
+		return NewForbiddenError(action)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// ClientGuardianService defines the service interface for minor clients, their guardians and
+// age-restricted services
+type ClientGuardianService interface {
+	LinkGuardian(ctx context.Context, linkDTO dto.LinkGuardianDTO) (*dto.MinorClientDTO, error)
+	UnlinkGuardian(ctx context.Context, clientID string) (*dto.MinorClientDTO, error)
+	RecordGuardianConsent(ctx context.Context, clientID string) (*dto.MinorClientDTO, error)
+	ListDependents(ctx context.Context, guardianClientID string) ([]*dto.MinorClientDTO, error)
+	SetServiceMinAge(ctx context.Context, serviceID string, minAge *int) (*dto.ServiceAgeRestrictionDTO, error)
+}
+
+// clientGuardianServiceImpl implements the ClientGuardianService interface
+type clientGuardianServiceImpl struct {
+	guardianRepo domain.ClientGuardianRepository
+	clientRepo   domain.BaseRepository[domain.Client]
+	validator    *validator.Validate
+}
+
+// NewClientGuardianService creates a new client guardian service
+func NewClientGuardianService(
+	guardianRepo domain.ClientGuardianRepository,
+	clientRepo domain.BaseRepository[domain.Client],
+	validator *validator.Validate,
+) ClientGuardianService {
+	return &clientGuardianServiceImpl{
+		guardianRepo: guardianRepo,
+		clientRepo:   clientRepo,
+		validator:    validator,
+	}
+}
+
+// LinkGuardian links a minor client to a guardian, replacing any previous guardian. Consent
+// given to a previous guardian does not carry over.
+func (s *clientGuardianServiceImpl) LinkGuardian(ctx context.Context, linkDTO dto.LinkGuardianDTO) (*dto.MinorClientDTO, error) {
+	if err := s.validator.Struct(linkDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+
+	client, err := s.getClient(ctx, linkDTO.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	guardian, err := s.getClient(ctx, linkDTO.GuardianClientID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if err := client.ValidateGuardian(guardian, now); err != nil {
+		return nil, toValidationError(err)
+	}
+
+	var consentAt *time.Time
+	if linkDTO.ConsentGiven {
+		consentAt = &now
+	}
+	if err := s.guardianRepo.SetGuardian(ctx, client.ID, &guardian.ID, linkDTO.Relationship, consentAt); err != nil {
+		return nil, NewServiceError("failed to link guardian", err)
+	}
+
+	client.GuardianClientID = &guardian.ID
+	client.GuardianRelationship = linkDTO.Relationship
+	client.GuardianConsentAt = consentAt
+	return dto.ToMinorClientDTO(client, now), nil
+}
+
+// UnlinkGuardian removes the guardian of a client, along with their consent
+func (s *clientGuardianServiceImpl) UnlinkGuardian(ctx context.Context, clientID string) (*dto.MinorClientDTO, error) {
+	client, err := s.getClient(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if !client.HasGuardian() {
+		return nil, validation.NewValidationError("the client has no guardian")
+	}
+
+	if err := s.guardianRepo.SetGuardian(ctx, client.ID, nil, nil, nil); err != nil {
+		return nil, NewServiceError("failed to unlink guardian", err)
+	}
+
+	client.GuardianClientID = nil
+	client.GuardianRelationship = nil
+	client.GuardianConsentAt = nil
+	return dto.ToMinorClientDTO(client, time.Now()), nil
+}
+
+// RecordGuardianConsent records that the guardian consented to the client being treated
+func (s *clientGuardianServiceImpl) RecordGuardianConsent(ctx context.Context, clientID string) (*dto.MinorClientDTO, error) {
+	client, err := s.getClient(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if !client.HasGuardian() {
+		return nil, validation.NewValidationError("link a guardian before recording consent")
+	}
+
+	now := time.Now()
+	if err := s.guardianRepo.SetConsent(ctx, client.ID, now); err != nil {
+		return nil, NewServiceError("failed to record guardian consent", err)
+	}
+
+	client.GuardianConsentAt = &now
+	return dto.ToMinorClientDTO(client, now), nil
+}
+
+// ListDependents lists the clients a client is the guardian of
+func (s *clientGuardianServiceImpl) ListDependents(ctx context.Context, guardianClientID string) ([]*dto.MinorClientDTO, error) {
+	if _, err := s.getClient(ctx, guardianClientID); err != nil {
+		return nil, err
+	}
+
+	dependents, err := s.guardianRepo.FindDependents(ctx, guardianClientID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve dependents", err)
+	}
+
+	now := time.Now()
+	result := make([]*dto.MinorClientDTO, len(dependents))
+	for i, dependent := range dependents {
+		result[i] = dto.ToMinorClientDTO(dependent, now)
+	}
+	return result, nil
+}
+
+// SetServiceMinAge sets the minimum client age of a service, or lifts the restriction when
+// minAge is nil
+func (s *clientGuardianServiceImpl) SetServiceMinAge(ctx context.Context, serviceID string, minAge *int) (*dto.ServiceAgeRestrictionDTO, error) {
+	if minAge != nil && (*minAge < 1 || *minAge > 99) {
+		return nil, validation.NewValidationError("min_age must be between 1 and 99")
+	}
+
+	service, err := s.guardianRepo.FindService(ctx, serviceID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("service", "id", serviceID)
+		}
+		return nil, NewServiceError("failed to retrieve service", err)
+	}
+
+	if err := s.guardianRepo.SetServiceMinAge(ctx, service.ID, minAge); err != nil {
+		return nil, NewServiceError("failed to update service age restriction", err)
+	}
+
+	service.MinAge = minAge
+	return dto.ToServiceAgeRestrictionDTO(service), nil
+}
+
+// getClient retrieves a client, mapping a missing record to a not found error
+func (s *clientGuardianServiceImpl) getClient(ctx context.Context, id string) (*domain.Client, error) {
+	client, err := s.clientRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("client", "id", id)
+		}
+		return nil, NewServiceError("failed to retrieve client", err)
+	}
+	return client, nil
+}
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// ClientLeaderboardService defines the service interface for ranking the clients of a business
+// and identifying its VIPs
+type ClientLeaderboardService interface {
+	GetLeaderboard(ctx context.Context, requestDTO dto.ClientLeaderboardRequestDTO) (*dto.ClientLeaderboardDTO, error)
+	GetVIPRules(ctx context.Context, businessID string) (*dto.VIPRulesDTO, error)
+	SetVIPRules(ctx context.Context, rulesDTO dto.SetVIPRulesDTO) (*dto.VIPRefreshDTO, error)
+	RefreshVIPClients(ctx context.Context, businessID string) (*dto.VIPRefreshDTO, error)
+	// RefreshAllVIPClients brings the VIP tags of every business with VIP rules up to date
+	RefreshAllVIPClients(ctx context.Context, now time.Time) (*dto.VIPRefreshRunDTO, error)
+}
+
+// clientLeaderboardServiceImpl implements the ClientLeaderboardService interface
+type clientLeaderboardServiceImpl struct {
+	leaderboardRepo domain.ClientLeaderboardRepository
+	clientRepo      domain.BaseRepository[domain.Client]
+	settingsRepo    domain.BusinessSettingsRepository
+	staffRepo       domain.StaffRepository
+	validator       *validator.Validate
+}
+
+// NewClientLeaderboardService creates a new client leaderboard service
+func NewClientLeaderboardService(
+	leaderboardRepo domain.ClientLeaderboardRepository,
+	clientRepo domain.BaseRepository[domain.Client],
+	settingsRepo domain.BusinessSettingsRepository,
+	staffRepo domain.StaffRepository,
+	validator *validator.Validate,
+) ClientLeaderboardService {
+	return &clientLeaderboardServiceImpl{
+		leaderboardRepo: leaderboardRepo,
+		clientRepo:      clientRepo,
+		settingsRepo:    settingsRepo,
+		staffRepo:       staffRepo,
+		validator:       validator,
+	}
+}
+
+// GetLeaderboard ranks the clients of a business by spend, visits or loyalty points over a
+// period, the last year unless given. Only staff who may view reports can see it.
+func (s *clientLeaderboardServiceImpl) GetLeaderboard(ctx context.Context, requestDTO dto.ClientLeaderboardRequestDTO) (*dto.ClientLeaderboardDTO, error) {
+	if err := s.validator.Struct(requestDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	if err := s.requireReports(ctx, requestDTO.BusinessID, "view the client leaderboard"); err != nil {
+		return nil, err
+	}
+
+	to := time.Now()
+	if requestDTO.To != nil {
+		to = *requestDTO.To
+	}
+	from := to.AddDate(-1, 0, 0)
+	if requestDTO.From != nil {
+		from = *requestDTO.From
+	}
+	period, err := domain.LeaderboardPeriod(from, to)
+	if err != nil {
+		return nil, toValidationError(err)
+	}
+	limit := requestDTO.Limit
+	if limit == 0 {
+		limit = domain.DefaultLeaderboardLimit
+	}
+
+	standings, err := s.leaderboardRepo.FindStandings(ctx, requestDTO.BusinessID, *period)
+	if err != nil {
+		return nil, NewServiceError("failed to rank clients", err)
+	}
+	ranked := domain.RankStandings(standings, requestDTO.Metric)
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+
+	ids := make([]string, len(ranked))
+	for i, standing := range ranked {
+		ids[i] = standing.ClientID
+	}
+	clients, err := s.clientRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve clients", err)
+	}
+	byID := make(map[string]*domain.Client, len(clients))
+	for _, client := range clients {
+		byID[client.ID] = client
+	}
+
+	leaderboard := &dto.ClientLeaderboardDTO{
+		BusinessID: requestDTO.BusinessID,
+		Metric:     requestDTO.Metric,
+		From:       period.Start,
+		To:         period.End,
+		Standings:  make([]*dto.ClientStandingDTO, 0, len(ranked)),
+	}
+	for _, standing := range ranked {
+		// Clients removed since the standings were summed up are left out
+		if client, ok := byID[standing.ClientID]; ok {
+			leaderboard.Standings = append(leaderboard.Standings, dto.ToClientStandingDTO(standing, client))
+		}
+	}
+	return leaderboard, nil
+}
+
+// GetVIPRules returns the thresholds making clients of a business VIPs
+func (s *clientLeaderboardServiceImpl) GetVIPRules(ctx context.Context, businessID string) (*dto.VIPRulesDTO, error) {
+	if businessID == "" {
+		return nil, validation.NewValidationError("business_id is required")
+	}
+	if err := s.requireReports(ctx, businessID, "view VIP rules"); err != nil {
+		return nil, err
+	}
+	_, rules, err := s.getRules(ctx, businessID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToVIPRulesDTO(businessID, rules), nil
+}
+
+// SetVIPRules replaces the thresholds making clients of a business VIPs and, when the rules are
+// enabled, tags the clients meeting them right away. Disabling the rules leaves the tags as
+// they are. Only owners and managers can set them.
+func (s *clientLeaderboardServiceImpl) SetVIPRules(ctx context.Context, rulesDTO dto.SetVIPRulesDTO) (*dto.VIPRefreshDTO, error) {
+	if err := s.validator.Struct(rulesDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	if err := requireManager(ctx, s.staffRepo, rulesDTO.BusinessID, "set VIP rules"); err != nil {
+		return nil, err
+	}
+
+	rules := domain.VIPRules{
+		Enabled:    rulesDTO.Enabled,
+		PeriodDays: rulesDTO.PeriodDays,
+		MinSpend:   rulesDTO.MinSpend,
+		MinVisits:  rulesDTO.MinVisits,
+		MinPoints:  rulesDTO.MinPoints,
+	}
+	if rules.PeriodDays == 0 {
+		rules.PeriodDays = domain.DefaultVIPPeriodDays
+	}
+	settings, _, err := s.getRules(ctx, rulesDTO.BusinessID)
+	if err != nil {
+		return nil, err
+	}
+	updated := domain.BusinessSettings{BusinessID: rulesDTO.BusinessID, CalendarStartHour: 9, CalendarEndHour: 18,
+		DepositWindowHours: domain.DefaultDepositWindowHours}
+	if settings != nil {
+		updated = *settings
+	}
+	if err := updated.SetVIPRules(rules); err != nil {
+		return nil, toValidationError(err)
+	}
+	updated.SetAuditFields(GetUserIDFromContext(ctx))
+	if settings == nil {
+		err = s.settingsRepo.Create(ctx, &updated)
+	} else {
+		err = s.settingsRepo.Update(ctx, &updated)
+	}
+	if err != nil {
+		return nil, NewServiceError("failed to update business settings", err)
+	}
+
+	if !rules.Enabled {
+		return &dto.VIPRefreshDTO{Rules: dto.ToVIPRulesDTO(rulesDTO.BusinessID, rules)}, nil
+	}
+	return s.refresh(ctx, rulesDTO.BusinessID, rules, time.Now())
+}
+
+// RefreshVIPClients tags the clients of a business meeting its VIP rules now, rather than at
+// the next periodic refresh. Only owners and managers can refresh them.
+func (s *clientLeaderboardServiceImpl) RefreshVIPClients(ctx context.Context, businessID string) (*dto.VIPRefreshDTO, error) {
+	if businessID == "" {
+		return nil, validation.NewValidationError("business_id is required")
+	}
+	if err := requireManager(ctx, s.staffRepo, businessID, "refresh VIP clients"); err != nil {
+		return nil, err
+	}
+	_, rules, err := s.getRules(ctx, businessID)
+	if err != nil {
+		return nil, err
+	}
+	if !rules.Enabled {
+		return nil, validation.NewValidationError("the business has no VIP rules enabled")
+	}
+	return s.refresh(ctx, businessID, rules, time.Now())
+}
+
+// RefreshAllVIPClients brings the VIP tags of every business with VIP rules up to date, as
+// clients cross thresholds and older visits leave the rules' period. A business that fails is
+// logged and skipped.
+func (s *clientLeaderboardServiceImpl) RefreshAllVIPClients(ctx context.Context, now time.Time) (*dto.VIPRefreshRunDTO, error) {
+	businessIDs, err := s.leaderboardRepo.FindBusinessesWithVIPRules(ctx)
+	if err != nil {
+		return nil, NewServiceError("failed to find businesses with VIP rules", err)
+	}
+
+	run := &dto.VIPRefreshRunDTO{}
+	for _, businessID := range businessIDs {
+		businessCtx := domain.WithTenant(ctx, domain.TenantContext{BusinessID: businessID})
+		_, rules, err := s.getRules(businessCtx, businessID)
+		if err == nil {
+			var refresh *dto.VIPRefreshDTO
+			if refresh, err = s.refresh(businessCtx, businessID, rules, now); err == nil {
+				run.Businesses++
+				run.Tagged += refresh.Tagged
+				run.Untagged += refresh.Untagged
+				continue
+			}
+		}
+		log.Warn().Err(err).Str("business_id", businessID).Msg("Failed to refresh VIP clients")
+		run.Failed++
+	}
+	return run, nil
+}
+
+// refresh tags the clients of a business meeting its VIP rules at the given time, untagging
+// the others
+func (s *clientLeaderboardServiceImpl) refresh(ctx context.Context, businessID string, rules domain.VIPRules, now time.Time) (*dto.VIPRefreshDTO, error) {
+	standings, err := s.leaderboardRepo.FindStandings(ctx, businessID, rules.Period(now))
+	if err != nil {
+		return nil, NewServiceError("failed to rank clients", err)
+	}
+	var vips []string
+	for _, standing := range standings {
+		if rules.Qualifies(standing) {
+			vips = append(vips, standing.ClientID)
+		}
+	}
+	tagged, untagged, err := s.leaderboardRepo.SetVIPClients(ctx, businessID, vips)
+	if err != nil {
+		return nil, NewServiceError("failed to tag VIP clients", err)
+	}
+	return &dto.VIPRefreshDTO{
+		Rules:      dto.ToVIPRulesDTO(businessID, rules),
+		VIPClients: len(vips),
+		Tagged:     tagged,
+		Untagged:   untagged,
+	}, nil
+}
+
+// getRules retrieves the settings of a business, nil when it has none, and its VIP rules
+func (s *clientLeaderboardServiceImpl) getRules(ctx context.Context, businessID string) (*domain.BusinessSettings, domain.VIPRules, error) {
+	settings, err := s.settingsRepo.GetByBusinessID(ctx, businessID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.VIPRules{}, NewServiceError("failed to retrieve business settings", err)
+		}
+		settings = nil
+	}
+	rules, err := settings.GetVIPRules()
+	if err != nil {
+		return nil, domain.VIPRules{}, NewServiceError("failed to read VIP rules", err)
+	}
+	return settings, rules, nil
+}
+
+// requireReports checks that the caller may view the reports of the business
+func (s *clientLeaderboardServiceImpl) requireReports(ctx context.Context, businessID, action string) error {
+	userID := GetUserIDFromContext(ctx)
+	if userID == nil {
+		return NewForbiddenError(action)
+	}
+	staff, err := s.staffRepo.FindByBusinessAndUser(ctx, businessID, *userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewForbiddenError(action)
+		}
+		return NewServiceError("failed to retrieve staff member", err)
+	}
+	if !staff.Can(domain.PermissionReportsView) {
+		return NewForbiddenError(action)
+	}
+	return nil
+}
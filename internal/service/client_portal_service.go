@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"gorm.io/gorm"
+)
+
+// ClientPortalService defines the service interface for the client-facing portal. All
+// operations act on the client records of the signed-in user and never take a client ID.
+type ClientPortalService interface {
+	GetMyVisits(ctx context.Context, page, pageSize int) ([]*dto.PortalVisitDTO, int64, error)
+	GetMyVisit(ctx context.Context, appointmentID string) (*dto.PortalVisitDTO, error)
+	GetMyInvoice(ctx context.Context, appointmentID string) (*dto.InvoiceResponseDTO, error)
+}
+
+// clientPortalServiceImpl implements the ClientPortalService interface
+type clientPortalServiceImpl struct {
+	portalRepo  domain.ClientPortalRepository
+	invoiceRepo domain.InvoiceRepository
+}
+
+// NewClientPortalService creates a new client portal service
+func NewClientPortalService(portalRepo domain.ClientPortalRepository, invoiceRepo domain.InvoiceRepository) ClientPortalService {
+	return &clientPortalServiceImpl{
+		portalRepo:  portalRepo,
+		invoiceRepo: invoiceRepo,
+	}
+}
+
+// GetMyVisits retrieves the signed-in client's past appointments, most recent first
+func (s *clientPortalServiceImpl) GetMyVisits(ctx context.Context, page, pageSize int) ([]*dto.PortalVisitDTO, int64, error) {
+	clientIDs, err := s.currentClientIDs(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(clientIDs) == 0 {
+		return []*dto.PortalVisitDTO{}, 0, nil
+	}
+
+	pagination := ValidatePagination(&dto.PaginationRequest{Page: page, PageSize: pageSize})
+	visits, total, err := s.portalRepo.FindPastVisits(ctx, clientIDs, pagination.Page, pagination.PageSize)
+	if err != nil {
+		return nil, 0, NewServiceError("failed to retrieve booking history", err)
+	}
+
+	return dto.ToPortalVisitDTOs(visits), total, nil
+}
+
+// GetMyVisit retrieves one of the signed-in client's appointments
+func (s *clientPortalServiceImpl) GetMyVisit(ctx context.Context, appointmentID string) (*dto.PortalVisitDTO, error) {
+	visit, err := s.findVisit(ctx, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToPortalVisitDTO(visit), nil
+}
+
+// GetMyInvoice retrieves the invoice of one of the signed-in client's appointments,
+// issuing it on first request once the appointment is completed and paid
+func (s *clientPortalServiceImpl) GetMyInvoice(ctx context.Context, appointmentID string) (*dto.InvoiceResponseDTO, error) {
+	visit, err := s.findVisit(ctx, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if visit.InvoiceID != nil {
+		invoice, err := s.invoiceRepo.GetByID(ctx, *visit.InvoiceID)
+		if err != nil {
+			return nil, NewServiceError("failed to retrieve invoice", err)
+		}
+		return dto.ToInvoiceResponseDTO(invoice), nil
+	}
+
+	if !visit.CanBeInvoiced() {
+		return nil, validation.NewValidationError("invoices are only available for completed, paid appointments")
+	}
+
+	invoice := &domain.Invoice{
+		BusinessID:    visit.BusinessID,
+		ClientID:      visit.ClientID,
+		AppointmentID: visit.AppointmentID,
+		IssuedAt:      time.Now(),
+		Currency:      visit.Currency,
+		BusinessName:  visit.BusinessName,
+		BusinessTaxID: visit.BusinessTaxID,
+		ClientName:    visit.ClientName,
+		AmountPaid:    *visit.AmountPaid,
+		PaymentMethod: visit.PaymentMethod,
+	}
+	if err := invoice.SetLineItems(visit.InvoiceLines()); err != nil {
+		return nil, NewServiceError("failed to encode invoice lines", err)
+	}
+	invoice.SetAuditFields(GetUserIDFromContext(ctx))
+
+	if err := s.invoiceRepo.Issue(ctx, invoice); err != nil {
+		// A concurrent request may have issued the invoice first
+		existing, findErr := s.invoiceRepo.FindByAppointmentID(ctx, appointmentID)
+		if findErr != nil {
+			return nil, NewServiceError("failed to issue invoice", err)
+		}
+		invoice = existing
+	}
+
+	return dto.ToInvoiceResponseDTO(invoice), nil
+}
+
+// findVisit retrieves an appointment, reporting appointments of other clients as not found
+func (s *clientPortalServiceImpl) findVisit(ctx context.Context, appointmentID string) (*domain.PortalVisit, error) {
+	if appointmentID == "" {
+		return nil, validation.NewValidationError("appointment_id is required")
+	}
+
+	clientIDs, err := s.currentClientIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(clientIDs) == 0 {
+		return nil, NewNotFoundError("appointment", "id", appointmentID)
+	}
+
+	visit, err := s.portalRepo.FindVisit(ctx, clientIDs, appointmentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("appointment", "id", appointmentID)
+		}
+		return nil, NewServiceError("failed to retrieve appointment", err)
+	}
+	return visit, nil
+}
+
+// currentClientIDs resolves the client records of the signed-in user
+func (s *clientPortalServiceImpl) currentClientIDs(ctx context.Context) ([]string, error) {
+	userID := GetUserIDFromContext(ctx)
+	if userID == nil {
+		return nil, NewForbiddenError("view booking history without signing in")
+	}
+
+	clientIDs, err := s.portalRepo.FindClientIDsByUserID(ctx, *userID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve client records", err)
+	}
+	return clientIDs, nil
+}
@@ -0,0 +1,425 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// ClientService defines the service interface for managing the clients of a business
+type ClientService interface {
+	ImportClients(ctx context.Context, importDTO dto.ImportClientsDTO) (*dto.ClientImportResultDTO, error)
+	ExportClientData(ctx context.Context, exportDTO dto.ExportClientDataDTO) (*dto.ClientDataExportDTO, error)
+	AnonymizeClient(ctx context.Context, clientID string) (*dto.ClientAnonymizationDTO, error)
+	SetClientTags(ctx context.Context, tagsDTO dto.SetClientTagsDTO) (*dto.ClientResponseDTO, error)
+	FindDuplicateClients(ctx context.Context, businessID string) ([]*dto.ClientDuplicateDTO, error)
+	MergeClients(ctx context.Context, mergeDTO dto.MergeClientsDTO) (*dto.ClientMergeDTO, error)
+}
+
+// clientServiceImpl implements the ClientService interface
+type clientServiceImpl struct {
+	importRepo   domain.ClientImportRepository
+	dataRepo     domain.ClientDataRepository
+	mergeRepo    domain.ClientMergeRepository
+	clientRepo   domain.BaseRepository[domain.Client]
+	businessRepo domain.BusinessRepository
+	staffRepo    domain.StaffRepository
+	auditLogRepo domain.AuditLogRepository
+	eventService EventService
+	validator    *validator.Validate
+}
+
+// NewClientService creates a new client service
+func NewClientService(
+	importRepo domain.ClientImportRepository,
+	dataRepo domain.ClientDataRepository,
+	mergeRepo domain.ClientMergeRepository,
+	clientRepo domain.BaseRepository[domain.Client],
+	businessRepo domain.BusinessRepository,
+	staffRepo domain.StaffRepository,
+	auditLogRepo domain.AuditLogRepository,
+	eventService EventService,
+	validator *validator.Validate,
+) ClientService {
+	return &clientServiceImpl{
+		importRepo:   importRepo,
+		dataRepo:     dataRepo,
+		mergeRepo:    mergeRepo,
+		clientRepo:   clientRepo,
+		businessRepo: businessRepo,
+		staffRepo:    staffRepo,
+		auditLogRepo: auditLogRepo,
+		eventService: eventService,
+		validator:    validator,
+	}
+}
+
+// ImportClients imports clients from a CSV file. Rows whose email or phone matches an existing
+// client or an earlier row are skipped as duplicates. The import is all or nothing: while any
+// row is invalid no client is created, so the file can be fixed and imported again. A dry run
+// reports what would be imported without creating anything.
+func (s *clientServiceImpl) ImportClients(ctx context.Context, importDTO dto.ImportClientsDTO) (*dto.ClientImportResultDTO, error) {
+	if err := s.validator.Struct(importDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	if len(importDTO.CSV) > dto.MaxClientImportSize {
+		return nil, validation.NewValidationError("the file must be at most 5 MB")
+	}
+
+	if _, err := s.businessRepo.GetByID(ctx, importDTO.BusinessID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("business", "id", importDTO.BusinessID)
+		}
+		return nil, NewServiceError("failed to retrieve business", err)
+	}
+
+	rows, err := domain.ParseClientCSV(strings.NewReader(importDTO.CSV), importDTO.BusinessID)
+	if err != nil {
+		return nil, toValidationError(err)
+	}
+	existing, err := s.importRepo.FindContacts(ctx, importDTO.BusinessID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve existing clients", err)
+	}
+	domain.MarkClientImportDuplicates(rows, existing)
+
+	var clients []*domain.Client
+	invalid := false
+	for _, row := range rows {
+		switch row.Status {
+		case domain.ClientImportInvalid:
+			invalid = true
+		case domain.ClientImportReady:
+			clients = append(clients, &row.Client)
+		}
+	}
+	if importDTO.DryRun || invalid || len(clients) == 0 {
+		return dto.ToClientImportResultDTO(importDTO.BusinessID, importDTO.DryRun, rows), nil
+	}
+
+	userID := GetUserIDFromContext(ctx)
+	for _, client := range clients {
+		client.SetAuditFields(userID)
+	}
+	if err := s.importRepo.CreateClients(ctx, clients); err != nil {
+		return nil, NewServiceError("failed to import clients", err)
+	}
+	for _, client := range clients {
+		publishClientCreated(ctx, s.eventService, client)
+	}
+	for _, row := range rows {
+		if row.Status == domain.ClientImportReady {
+			row.Status = domain.ClientImportImported
+		}
+	}
+	return dto.ToClientImportResultDTO(importDTO.BusinessID, false, rows), nil
+}
+
+// ExportClientData exports everything held about a client, as a single JSON document or a ZIP
+// archive with a JSON file per kind of data, to answer a data subject access request. Given a
+// public key or passphrase of the business, the export is encrypted for it, so copies left in
+// storage are unreadable to anyone else. Every export is recorded in the audit log, with the
+// fingerprint of the key it was encrypted for.
+func (s *clientServiceImpl) ExportClientData(ctx context.Context, exportDTO dto.ExportClientDataDTO) (*dto.ClientDataExportDTO, error) {
+	if err := s.validator.Struct(exportDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	format := exportDTO.Format
+	if format == "" {
+		format = domain.ClientDataExportJSON
+	}
+	var encrypter *domain.ExportEncrypter
+	var err error
+	switch {
+	case exportDTO.PublicKey != "":
+		encrypter, err = domain.NewPublicKeyEncrypter(exportDTO.PublicKey)
+	case exportDTO.Passphrase != "":
+		encrypter, err = domain.NewPassphraseEncrypter(exportDTO.Passphrase)
+	}
+	if err != nil {
+		return nil, toValidationError(err)
+	}
+	client, err := s.getClient(ctx, exportDTO.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.requirePermission(ctx, client.BusinessID, domain.PermissionClientsExport, "export client data"); err != nil {
+		return nil, err
+	}
+	if ctx, err = actFor(ctx, client.BusinessID, "export client data"); err != nil {
+		return nil, err
+	}
+
+	export, err := s.dataRepo.FindExport(ctx, exportDTO.ClientID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("client", "id", exportDTO.ClientID)
+		}
+		return nil, NewServiceError("failed to retrieve client data", err)
+	}
+	export.ExportedAt = time.Now().UTC()
+
+	var buf bytes.Buffer
+	contentType := "application/json"
+	if format == domain.ClientDataExportZIP {
+		contentType = "application/zip"
+		err = export.WriteZip(&buf)
+	} else {
+		err = export.WriteJSON(&buf)
+	}
+	if err != nil {
+		return nil, NewServiceError("failed to write client data export", err)
+	}
+
+	result := &dto.ClientDataExportDTO{
+		ClientID:    exportDTO.ClientID,
+		Format:      format,
+		FileName:    fmt.Sprintf("client-%s-%s.%s", exportDTO.ClientID, export.ExportedAt.Format("20060102"), format),
+		ContentType: contentType,
+		GeneratedAt: export.ExportedAt,
+		Encryption:  domain.ExportEncryptionNone,
+	}
+	data := buf.Bytes()
+	if encrypter != nil {
+		if data, err = encrypter.Encrypt(data); err != nil {
+			return nil, NewServiceError("failed to encrypt client data export", err)
+		}
+		result.FileName += domain.EncryptedExportExtension
+		result.ContentType = "application/octet-stream"
+		result.Encryption = encrypter.Method()
+		if encrypter.Fingerprint != "" {
+			result.KeyFingerprint = &encrypter.Fingerprint
+		}
+	}
+	result.Data = base64.StdEncoding.EncodeToString(data)
+
+	if err := s.recordExport(ctx, export, result); err != nil {
+		return nil, NewServiceError("failed to record client data export", err)
+	}
+	return result, nil
+}
+
+// recordExport records an export of a client's data in the audit log, with its format and how
+// it was encrypted
+func (s *clientServiceImpl) recordExport(ctx context.Context, export *domain.ClientDataExport, result *dto.ClientDataExportDTO) error {
+	details := map[string]any{"format": result.Format, "encryption": result.Encryption}
+	if result.KeyFingerprint != nil {
+		details["key_fingerprint"] = *result.KeyFingerprint
+	}
+	changes := make(map[string]domain.AuditChange, len(details))
+	for field, value := range details {
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		changes[field] = domain.AuditChange{To: encoded}
+	}
+	entry, err := domain.NewAuditLog(ctx, domain.AuditActionExport, "clients", export.Profile.ID, &export.Profile.BusinessID, changes, export.ExportedAt)
+	if err != nil {
+		return err
+	}
+	return s.auditLogRepo.Create(ctx, entry)
+}
+
+// AnonymizeClient removes the personal data of a client on an erasure request. The client's
+// appointments, completions and loyalty balances are kept, so the business's statistics do not
+// change; their photos and documents are deleted. Only staff allowed to delete clients can
+// anonymize them, and anonymization cannot be undone.
+func (s *clientServiceImpl) AnonymizeClient(ctx context.Context, clientID string) (*dto.ClientAnonymizationDTO, error) {
+	client, err := s.getClient(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.requirePermission(ctx, client.BusinessID, domain.PermissionClientsDelete, "anonymize clients"); err != nil {
+		return nil, err
+	}
+	if ctx, err = actFor(ctx, client.BusinessID, "anonymize clients"); err != nil {
+		return nil, err
+	}
+	if client.AnonymizedAt != nil {
+		return nil, validation.NewValidationError("the client has already been anonymized")
+	}
+
+	now := time.Now().UTC()
+	if err := s.dataRepo.Anonymize(ctx, clientID, now, GetUserIDFromContext(ctx)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("client", "id", clientID)
+		}
+		return nil, NewServiceError("failed to anonymize client", err)
+	}
+	return &dto.ClientAnonymizationDTO{ClientID: clientID, AnonymizedAt: now}, nil
+}
+
+// SetClientTags replaces the tags of a client, which campaigns can target. Tags are stored
+// lower-cased, without repeats.
+func (s *clientServiceImpl) SetClientTags(ctx context.Context, tagsDTO dto.SetClientTagsDTO) (*dto.ClientResponseDTO, error) {
+	if err := s.validator.Struct(tagsDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	client, err := s.clientRepo.GetByID(ctx, tagsDTO.ClientID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("client", "id", tagsDTO.ClientID)
+		}
+		return nil, NewServiceError("failed to retrieve client", err)
+	}
+	if err := client.CheckVersion(client.TableName(), tagsDTO.ExpectedVersion); err != nil {
+		return nil, err
+	}
+
+	tags, err := domain.NormalizeClientTags(tagsDTO.Tags)
+	if err != nil {
+		return nil, toValidationError(err)
+	}
+	if err := client.SetTags(tags); err != nil {
+		return nil, NewServiceError("failed to set client tags", err)
+	}
+	client.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.clientRepo.Update(ctx, client); err != nil {
+		return nil, NewServiceError("failed to set client tags", err)
+	}
+	return dto.ToClientResponseDTO(client), nil
+}
+
+// FindDuplicateClients finds the clients of a business that are probably the same person, as
+// they share an email, phone or name, strongest matches first. Only owners can see them, as
+// only owners can merge them.
+func (s *clientServiceImpl) FindDuplicateClients(ctx context.Context, businessID string) ([]*dto.ClientDuplicateDTO, error) {
+	if err := requireOwner(ctx, s.staffRepo, businessID, "find duplicate clients"); err != nil {
+		return nil, err
+	}
+	clients, err := s.mergeRepo.FindCandidates(ctx, businessID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve clients", err)
+	}
+
+	duplicates := domain.FindDuplicateClients(clients)
+	result := make([]*dto.ClientDuplicateDTO, len(duplicates))
+	for i, duplicate := range duplicates {
+		result[i] = &dto.ClientDuplicateDTO{
+			Client:    dto.ToClientResponseDTO(duplicate.Client),
+			Duplicate: dto.ToClientResponseDTO(duplicate.Duplicate),
+			Reasons:   duplicate.Reasons,
+		}
+	}
+	return result, nil
+}
+
+// MergeClients merges a duplicate into the client kept. The kept client takes the details it is
+// missing from the duplicate, and everything held about the duplicate moves to it in a single
+// transaction; the duplicate is then deleted. Only owners can merge clients, and a merge cannot
+// be undone.
+func (s *clientServiceImpl) MergeClients(ctx context.Context, mergeDTO dto.MergeClientsDTO) (*dto.ClientMergeDTO, error) {
+	if err := s.validator.Struct(mergeDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	client, err := s.getClient(ctx, mergeDTO.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	duplicate, err := s.getClient(ctx, mergeDTO.DuplicateID)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireOwner(ctx, s.staffRepo, client.BusinessID, "merge clients"); err != nil {
+		return nil, err
+	}
+	if err := client.CheckMerge(duplicate); err != nil {
+		return nil, toValidationError(err)
+	}
+
+	client.Absorb(duplicate)
+	userID := GetUserIDFromContext(ctx)
+	client.SetAuditFields(userID)
+	now := time.Now().UTC()
+	moved, err := s.mergeRepo.Merge(ctx, client, duplicate.ID, now, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrClientMembershipConflict):
+			return nil, validation.FromError(err)
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return nil, NewNotFoundError("client", "id", duplicate.ID)
+		}
+		return nil, NewServiceError("failed to merge clients", err)
+	}
+	publishClientMerged(ctx, s.eventService, client, duplicate.ID)
+
+	return &dto.ClientMergeDTO{
+		Client:         dto.ToClientResponseDTO(client),
+		MergedClientID: duplicate.ID,
+		RecordsMoved:   moved,
+		MergedAt:       now,
+	}, nil
+}
+
+// getClient retrieves a client by ID
+func (s *clientServiceImpl) getClient(ctx context.Context, id string) (*domain.Client, error) {
+	client, err := s.clientRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("client", "id", id)
+		}
+		return nil, NewServiceError("failed to retrieve client", err)
+	}
+	return client, nil
+}
+
+// requirePermission checks that the caller is active staff of the business with a permission,
+// refusing the action otherwise
+func (s *clientServiceImpl) requirePermission(ctx context.Context, businessID string, permission domain.Permission, action string) error {
+	userID := GetUserIDFromContext(ctx)
+	if userID == nil {
+		return NewForbiddenError(action)
+	}
+	staff, err := s.staffRepo.FindByBusinessAndUser(ctx, businessID, *userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewForbiddenError(action)
+		}
+		return NewServiceError("failed to retrieve staff member", err)
+	}
+	if !staff.IsActive || !staff.Can(permission) {
+		return NewForbiddenError(action)
+	}
+	return nil
+}
+
+// publishClientCreated records the creation of a client so that integrations pick it up. The
+// client is already created, so a failure is logged rather than returned.
+func publishClientCreated(ctx context.Context, eventService EventService, client *domain.Client) {
+	event, err := domain.NewDomainEvent(domain.AggregateClient, client.ID, domain.EventClientCreated, &client.BusinessID,
+		map[string]any{"client_id": client.ID, "first_name": client.FirstName, "last_name": client.LastName,
+			"email": client.Email, "phone": client.Phone})
+	if err == nil {
+		err = eventService.Publish(ctx, event)
+	}
+	if err != nil {
+		log.Error().Err(err).Str("client_id", client.ID).Msg("Failed to publish client event")
+	}
+}
+
+// publishClientMerged records the update of a client a duplicate was merged into, so that the
+// calendar and integrations pick up the appointments it took over. The merge is already stored,
+// so a failure is logged rather than returned.
+func publishClientMerged(ctx context.Context, eventService EventService, client *domain.Client, duplicateID string) {
+	event, err := domain.NewDomainEvent(domain.AggregateClient, client.ID, domain.EventClientUpdated, &client.BusinessID,
+		map[string]any{"client_id": client.ID, "merged_client_id": duplicateID})
+	if err == nil {
+		err = eventService.Publish(ctx, event)
+	}
+	if err != nil {
+		log.Error().Err(err).Str("client_id", client.ID).Msg("Failed to publish client event")
+	}
+}
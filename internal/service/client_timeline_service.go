@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ClientTimelineService defines the service interface for the activity feed of a client
+type ClientTimelineService interface {
+	GetClientTimeline(ctx context.Context, requestDTO dto.ClientTimelineRequestDTO) (*domain.Connection[dto.TimelineEntryDTO], error)
+}
+
+// clientTimelineServiceImpl implements the ClientTimelineService interface
+type clientTimelineServiceImpl struct {
+	timelineRepo domain.ClientTimelineRepository
+	clientRepo   domain.BaseRepository[domain.Client]
+	staffRepo    domain.StaffRepository
+	validator    *validator.Validate
+}
+
+// NewClientTimelineService creates a new client timeline service
+func NewClientTimelineService(
+	timelineRepo domain.ClientTimelineRepository,
+	clientRepo domain.BaseRepository[domain.Client],
+	staffRepo domain.StaffRepository,
+	validator *validator.Validate,
+) ClientTimelineService {
+	return &clientTimelineServiceImpl{
+		timelineRepo: timelineRepo,
+		clientRepo:   clientRepo,
+		staffRepo:    staffRepo,
+		validator:    validator,
+	}
+}
+
+// GetClientTimeline retrieves a page of a client's timeline, newest first: their bookings,
+// completed visits, payments, loyalty points, campaign messages, service records and reviews.
+// Only staff who may view clients can see it.
+func (s *clientTimelineServiceImpl) GetClientTimeline(ctx context.Context, requestDTO dto.ClientTimelineRequestDTO) (*domain.Connection[dto.TimelineEntryDTO], error) {
+	if err := s.validator.Struct(requestDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	first := requestDTO.First
+	if first == 0 {
+		first = 20
+	}
+	var cursor *domain.Cursor
+	if requestDTO.After != "" {
+		decoded, err := domain.DecodeCursor(requestDTO.After)
+		if err != nil {
+			return nil, toValidationError(err)
+		}
+		if _, err := uuid.Parse(decoded.ID); err != nil {
+			return nil, validation.NewValidationError("invalid cursor")
+		}
+		cursor = decoded
+	}
+
+	client, err := s.clientRepo.GetByID(ctx, requestDTO.ClientID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("client", "id", requestDTO.ClientID)
+		}
+		return nil, NewServiceError("failed to retrieve client", err)
+	}
+	if err := s.requireClientsView(ctx, client.BusinessID); err != nil {
+		return nil, err
+	}
+
+	entries, hasNext, err := s.timelineRepo.FindEntries(ctx, client.ID, cursor, first)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve client timeline", err)
+	}
+	connection := &domain.Connection[dto.TimelineEntryDTO]{
+		Edges: make([]*domain.Edge[dto.TimelineEntryDTO], 0, len(entries)),
+		PageInfo: domain.PageInfo{
+			HasNextPage:     hasNext,
+			HasPreviousPage: cursor != nil,
+		},
+	}
+	for _, entry := range entries {
+		connection.Edges = append(connection.Edges, &domain.Edge[dto.TimelineEntryDTO]{
+			Cursor: entry.Cursor().Encode(),
+			Node:   dto.ToTimelineEntryDTO(entry),
+		})
+	}
+	if len(connection.Edges) > 0 {
+		connection.PageInfo.StartCursor = &connection.Edges[0].Cursor
+		connection.PageInfo.EndCursor = &connection.Edges[len(connection.Edges)-1].Cursor
+	}
+	return connection, nil
+}
+
+// requireClientsView checks that the caller is an active member of the business's staff who may
+// view clients
+func (s *clientTimelineServiceImpl) requireClientsView(ctx context.Context, businessID string) error {
+	const action = "view the client timeline"
+	userID := GetUserIDFromContext(ctx)
+	if userID == nil {
+		return NewForbiddenError(action)
+	}
+	staff, err := s.staffRepo.FindByBusinessAndUser(ctx, businessID, *userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewForbiddenError(action)
+		}
+		return NewServiceError("failed to retrieve staff member", err)
+	}
+	if !staff.IsActive || !staff.Can(domain.PermissionClientsView) {
+		return NewForbiddenError(action)
+	}
+	return nil
+}
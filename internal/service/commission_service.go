@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// CommissionService defines the service interface for staff commission operations
+type CommissionService interface {
+	SetCommissionPlan(ctx context.Context, createDTO dto.CreateCommissionPlanDTO) (*dto.CommissionPlanResponseDTO, error)
+	GetCurrentPlan(ctx context.Context, staffID string) (*dto.CommissionPlanResponseDTO, error)
+	GetPlanHistory(ctx context.Context, staffID string) ([]*dto.CommissionPlanResponseDTO, error)
+	CalculateCommission(ctx context.Context, staffID string, start, end time.Time) (*dto.CommissionReportDTO, error)
+}
+
+// commissionServiceImpl implements the CommissionService interface
+type commissionServiceImpl struct {
+	planRepo     domain.StaffCommissionPlanRepository
+	staffRepo    domain.StaffRepository
+	businessRepo domain.BusinessRepository
+	unitOfWork   domain.UnitOfWork
+	validator    *validator.Validate
+}
+
+// NewCommissionService creates a new commission service
+func NewCommissionService(planRepo domain.StaffCommissionPlanRepository, staffRepo domain.StaffRepository, businessRepo domain.BusinessRepository, unitOfWork domain.UnitOfWork, validator *validator.Validate) CommissionService {
+	return &commissionServiceImpl{
+		planRepo:     planRepo,
+		staffRepo:    staffRepo,
+		businessRepo: businessRepo,
+		unitOfWork:   unitOfWork,
+		validator:    validator,
+	}
+}
+
+// SetCommissionPlan adds a new commission plan for a staff member. The plan in effect on the
+// new plan's start date is closed so that the history stays contiguous; plans can only be
+// appended, never inserted before an existing plan. Only the owner and managers allowed to see
+// staff pay can set plans, and solo businesses pay no commissions.
+func (s *commissionServiceImpl) SetCommissionPlan(ctx context.Context, createDTO dto.CreateCommissionPlanDTO) (*dto.CommissionPlanResponseDTO, error) {
+	if err := s.validator.Struct(createDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+
+	ctx, staff, err := s.staffForPay(ctx, createDTO.StaffID, false, "set commission plans")
+	if err != nil {
+		return nil, err
+	}
+	if err := requireCapability(ctx, s.businessRepo, staff.BusinessID, domain.CapabilityStaffManagement, "set commission plans"); err != nil {
+		return nil, err
+	}
+
+	plan := &domain.StaffCommissionPlan{
+		BusinessID:    staff.BusinessID,
+		StaffID:       staff.ID,
+		EffectiveFrom: createDTO.EffectiveFrom,
+		ProductRate:   createDTO.ProductRate,
+		Notes:         createDTO.Notes,
+	}
+	if err := plan.SetServiceTiers(createDTO.ServiceTiers); err != nil {
+		return nil, NewServiceError("failed to encode service tiers", err)
+	}
+	if err := plan.SetServiceOverrides(createDTO.ServiceOverrides); err != nil {
+		return nil, NewServiceError("failed to encode service overrides", err)
+	}
+	if err := plan.Validate(); err != nil {
+		return nil, toValidationError(err)
+	}
+
+	history, err := s.planRepo.FindByStaffID(ctx, staff.ID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve commission plan history", err)
+	}
+
+	var previous *domain.StaffCommissionPlan
+	for _, existing := range history {
+		if !existing.EffectiveFrom.Before(plan.EffectiveFrom) {
+			return nil, validation.NewValidationError("a commission plan already starts on or after effective_from")
+		}
+		if existing.IsEffectiveAt(plan.EffectiveFrom) {
+			previous = existing
+		}
+	}
+
+	userID := GetUserIDFromContext(ctx)
+	err = s.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		if previous != nil {
+			previous.EffectiveTo = &plan.EffectiveFrom
+			previous.SetAuditFields(userID)
+			if err := s.planRepo.Update(ctx, previous); err != nil {
+				return err
+			}
+		}
+		plan.SetAuditFields(userID)
+		return s.planRepo.Create(ctx, plan)
+	})
+	if err != nil {
+		return nil, NewServiceError("failed to set commission plan", err)
+	}
+
+	return dto.ToCommissionPlanResponseDTO(plan), nil
+}
+
+// GetCurrentPlan retrieves the commission plan currently in effect for a staff member. Only the
+// owner, managers allowed to see staff pay and the staff member themselves can see it.
+func (s *commissionServiceImpl) GetCurrentPlan(ctx context.Context, staffID string) (*dto.CommissionPlanResponseDTO, error) {
+	ctx, _, err := s.staffForPay(ctx, staffID, true, "view commission plans")
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := s.planRepo.FindEffective(ctx, staffID, time.Now())
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("commission plan", "staff_id", staffID)
+		}
+		return nil, NewServiceError("failed to retrieve commission plan", err)
+	}
+
+	return dto.ToCommissionPlanResponseDTO(plan), nil
+}
+
+// GetPlanHistory retrieves all commission plans of a staff member, most recent first. Only the
+// owner, managers allowed to see staff pay and the staff member themselves can see them.
+func (s *commissionServiceImpl) GetPlanHistory(ctx context.Context, staffID string) ([]*dto.CommissionPlanResponseDTO, error) {
+	ctx, _, err := s.staffForPay(ctx, staffID, true, "view commission plans")
+	if err != nil {
+		return nil, err
+	}
+
+	plans, err := s.planRepo.FindByStaffID(ctx, staffID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve commission plan history", err)
+	}
+
+	return dto.ToCommissionPlanResponseDTOs(plans), nil
+}
+
+// CalculateCommission calculates the commission earned by a staff member over a period.
+// Each sale is paid under the plan in effect when it occurred; when the plan changes mid-period,
+// tier thresholds apply separately to the revenue under each plan. Only the owner, managers
+// allowed to see staff pay and the staff member themselves can see it.
+func (s *commissionServiceImpl) CalculateCommission(ctx context.Context, staffID string, start, end time.Time) (*dto.CommissionReportDTO, error) {
+	if !end.After(start) {
+		return nil, validation.NewValidationError("end must be after start")
+	}
+	ctx, _, err := s.staffForPay(ctx, staffID, true, "view commissions")
+	if err != nil {
+		return nil, err
+	}
+
+	plans, err := s.planRepo.FindInPeriod(ctx, staffID, start, end)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve commission plans", err)
+	}
+	if len(plans) == 0 {
+		return nil, NewNotFoundError("commission plan", "staff_id", staffID)
+	}
+
+	lines, err := s.planRepo.FindRevenueLines(ctx, staffID, start, end)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve commissionable revenue", err)
+	}
+
+	linesByPlan := make([][]domain.CommissionRevenueLine, len(plans))
+	for _, line := range lines {
+		for i, plan := range plans {
+			if plan.IsEffectiveAt(line.OccurredAt) {
+				linesByPlan[i] = append(linesByPlan[i], line)
+				break
+			}
+		}
+	}
+
+	total := &domain.CommissionBreakdown{Tiers: []domain.TierCommission{}}
+	planIDs := make([]string, len(plans))
+	for i, plan := range plans {
+		planIDs[i] = plan.ID
+		breakdown, err := plan.Calculate(linesByPlan[i])
+		if err != nil {
+			return nil, toValidationError(err)
+		}
+		total.Add(breakdown)
+	}
+
+	return dto.ToCommissionReportDTO(staffID, start, end, total, planIDs), nil
+}
+
+// staffForPay retrieves the staff member whose pay the caller acts on, scoping the context to
+// their business. The owner and managers with the staff:pay permission may act on anyone's pay,
+// and staff members on their own where self is allowed.
+func (s *commissionServiceImpl) staffForPay(ctx context.Context, staffID string, self bool, action string) (context.Context, *domain.Staff, error) {
+	if staffID == "" {
+		return ctx, nil, validation.NewValidationError("staff_id is required")
+	}
+	staff, err := s.staffRepo.GetByID(ctx, staffID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ctx, nil, NewNotFoundError("staff", "id", staffID)
+		}
+		return ctx, nil, NewServiceError("failed to retrieve staff", err)
+	}
+
+	userID := GetUserIDFromContext(ctx)
+	if userID == nil {
+		return ctx, nil, NewForbiddenError(action)
+	}
+	caller, err := s.staffRepo.FindByBusinessAndUser(ctx, staff.BusinessID, *userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ctx, nil, NewForbiddenError(action)
+		}
+		return ctx, nil, NewServiceError("failed to retrieve staff member", err)
+	}
+	switch {
+	case !caller.IsActive:
+		return ctx, nil, NewForbiddenError(action)
+	case caller.Role == domain.BusinessRoleOwner,
+		caller.Role == domain.BusinessRoleManager && caller.Can(domain.PermissionStaffPay),
+		self && caller.ID == staff.ID:
+	default:
+		return ctx, nil, NewForbiddenError(action)
+	}
+
+	ctx, err = actFor(ctx, staff.BusinessID, action)
+	if err != nil {
+		return ctx, nil, err
+	}
+	return ctx, staff, nil
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/repository/memory"
+)
+
+func TestCommissionService_GetPlanHistory(t *testing.T) {
+	db := memory.NewDB()
+	business := &domain.Business{UserID: "owner-1", Name: "Salon", Currency: "EUR"}
+	require.NoError(t, memory.Insert(db, business))
+	revoked, err := domain.StaffPermissions{Revokes: []domain.Permission{domain.PermissionStaffPay}}.Encode()
+	require.NoError(t, err)
+	owner := &domain.Staff{BusinessID: business.ID, UserID: "user-owner", Role: domain.BusinessRoleOwner, IsActive: true}
+	manager := &domain.Staff{BusinessID: business.ID, UserID: "user-manager", Role: domain.BusinessRoleManager, IsActive: true}
+	frontDesk := &domain.Staff{BusinessID: business.ID, UserID: "user-front-desk", Role: domain.BusinessRoleManager, IsActive: true,
+		Permissions: revoked}
+	stylist := &domain.Staff{BusinessID: business.ID, UserID: "user-stylist", Role: domain.BusinessRoleEmployee, IsActive: true}
+	colleague := &domain.Staff{BusinessID: business.ID, UserID: "user-colleague", Role: domain.BusinessRoleEmployee, IsActive: true}
+	require.NoError(t, memory.Insert(db, owner, manager, frontDesk, stylist, colleague))
+
+	commissions := NewCommissionService(memory.NewStaffCommissionPlanRepository(db), memory.NewStaffRepository(db),
+		memory.NewBusinessRepository(db), nil, validator.New())
+	as := func(staff *domain.Staff) context.Context {
+		return SetUserContext(context.Background(), staff.UserID, string(staff.Role), &business.ID)
+	}
+
+	for _, allowed := range []*domain.Staff{owner, manager, stylist} {
+		_, err := commissions.GetPlanHistory(as(allowed), stylist.ID)
+		assert.NoError(t, err, "%s can see the stylist's pay", allowed.UserID)
+	}
+
+	var forbidden ForbiddenError
+	for _, refused := range []*domain.Staff{frontDesk, colleague} {
+		_, err := commissions.GetPlanHistory(as(refused), stylist.ID)
+		assert.ErrorAs(t, err, &forbidden, "%s cannot see the stylist's pay", refused.UserID)
+	}
+}
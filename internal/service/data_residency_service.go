@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/residency"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// DataResidencyService defines the service interface for the region each business's data must
+// stay in
+type DataResidencyService interface {
+	GetBusinessDataResidency(ctx context.Context, businessID string) (*dto.DataResidencyDTO, error)
+	SetBusinessDataRegion(ctx context.Context, regionDTO dto.SetBusinessDataRegionDTO) (*dto.DataResidencyDTO, error)
+}
+
+// dataResidencyServiceImpl implements the DataResidencyService interface
+type dataResidencyServiceImpl struct {
+	businessRepo domain.BusinessRepository
+	policy       *residency.Policy
+	buckets      *residency.Buckets
+	validator    *validator.Validate
+}
+
+// NewDataResidencyService creates a new data residency service
+func NewDataResidencyService(
+	businessRepo domain.BusinessRepository,
+	policy *residency.Policy,
+	buckets *residency.Buckets,
+	validator *validator.Validate,
+) DataResidencyService {
+	return &dataResidencyServiceImpl{
+		businessRepo: businessRepo,
+		policy:       policy,
+		buckets:      buckets,
+		validator:    validator,
+	}
+}
+
+// GetBusinessDataResidency returns the region of a business and the destinations its data may
+// and may not be sent to
+func (s *dataResidencyServiceImpl) GetBusinessDataResidency(ctx context.Context, businessID string) (*dto.DataResidencyDTO, error) {
+	business, err := s.getBusiness(ctx, businessID)
+	if err != nil {
+		return nil, err
+	}
+	return s.toDataResidencyDTO(business.ID, business.DataRegion), nil
+}
+
+// SetBusinessDataRegion changes the region a business's data must stay in. Only platform
+// operators may change it, since data already sent to destinations in the old region is not
+// moved.
+func (s *dataResidencyServiceImpl) SetBusinessDataRegion(ctx context.Context, regionDTO dto.SetBusinessDataRegionDTO) (*dto.DataResidencyDTO, error) {
+	if !IsPlatformAdmin(ctx) {
+		return nil, NewForbiddenError("change the data region of a business")
+	}
+	if err := s.validator.Struct(regionDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+
+	business, err := s.getBusiness(ctx, regionDTO.BusinessID)
+	if err != nil {
+		return nil, err
+	}
+	if business.DataRegion != regionDTO.Region {
+		if err := s.businessRepo.SetDataRegion(ctx, business.ID, regionDTO.Region, GetUserIDFromContext(ctx)); err != nil {
+			return nil, NewServiceError("failed to update data region", err)
+		}
+	}
+	return s.toDataResidencyDTO(business.ID, regionDTO.Region), nil
+}
+
+// getBusiness retrieves a business, mapping a missing record to a not found error
+func (s *dataResidencyServiceImpl) getBusiness(ctx context.Context, businessID string) (*domain.Business, error) {
+	business, err := s.businessRepo.GetByID(ctx, businessID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("business", "id", businessID)
+		}
+		return nil, NewServiceError("failed to retrieve business", err)
+	}
+	if business.DataRegion == "" {
+		business.DataRegion = domain.DefaultDataRegion
+	}
+	return business, nil
+}
+
+// toDataResidencyDTO describes where data of a region may go
+func (s *dataResidencyServiceImpl) toDataResidencyDTO(businessID string, region domain.DataRegion) *dto.DataResidencyDTO {
+	residencyDTO := &dto.DataResidencyDTO{
+		BusinessID:   businessID,
+		Region:       region,
+		Destinations: []*dto.DataDestinationDTO{},
+	}
+	if bucket, err := s.buckets.Select(region); err == nil {
+		residencyDTO.StorageBucket = &bucket
+	}
+	for _, destination := range s.policy.Destinations() {
+		residencyDTO.Destinations = append(residencyDTO.Destinations, dto.ToDataDestinationDTO(destination, region))
+	}
+	return residencyDTO
+}
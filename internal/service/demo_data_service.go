@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+)
+
+// DemoDataService defines the service interface for the sandbox businesses used by sales demos
+// and automated end-to-end test environments
+type DemoDataService interface {
+	SetBusinessSandbox(ctx context.Context, sandboxDTO dto.SetBusinessSandboxDTO) (*dto.BusinessResponseDTO, error)
+	ResetDemoData(ctx context.Context, businessID string) (*dto.DemoDataResetDTO, error)
+}
+
+// demoDataServiceImpl implements the DemoDataService interface
+type demoDataServiceImpl struct {
+	demoDataRepo domain.DemoDataRepository
+	businessRepo domain.BusinessRepository
+	staffRepo    domain.StaffRepository
+	serviceRepo  domain.BaseRepository[domain.Service]
+	validator    *validator.Validate
+}
+
+// NewDemoDataService creates a new demo data service
+func NewDemoDataService(
+	demoDataRepo domain.DemoDataRepository,
+	businessRepo domain.BusinessRepository,
+	staffRepo domain.StaffRepository,
+	serviceRepo domain.BaseRepository[domain.Service],
+	validator *validator.Validate,
+) DemoDataService {
+	return &demoDataServiceImpl{
+		demoDataRepo: demoDataRepo,
+		businessRepo: businessRepo,
+		staffRepo:    staffRepo,
+		serviceRepo:  serviceRepo,
+		validator:    validator,
+	}
+}
+
+// SetBusinessSandbox marks a business as a sandbox whose data can be reset, or back as a real
+// business (platform admin only)
+func (s *demoDataServiceImpl) SetBusinessSandbox(ctx context.Context, sandboxDTO dto.SetBusinessSandboxDTO) (*dto.BusinessResponseDTO, error) {
+	if !IsPlatformAdmin(ctx) {
+		return nil, NewForbiddenError("mark sandbox businesses")
+	}
+	if err := s.validator.Struct(sandboxDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	ctx, err := actFor(ctx, sandboxDTO.BusinessID, "mark sandbox businesses")
+	if err != nil {
+		return nil, err
+	}
+
+	business, err := getBusiness(ctx, s.businessRepo, sandboxDTO.BusinessID)
+	if err != nil {
+		return nil, err
+	}
+	if business.IsSandbox == sandboxDTO.IsSandbox {
+		return dto.ToBusinessResponseDTO(business), nil
+	}
+
+	business.IsSandbox = sandboxDTO.IsSandbox
+	business.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.businessRepo.Update(ctx, business); err != nil {
+		return nil, NewServiceError("failed to update business", err)
+	}
+	return dto.ToBusinessResponseDTO(business), nil
+}
+
+// ResetDemoData wipes the transactional data of a sandbox business and reseeds the standard
+// demo dataset in one transaction (owners, managers and platform admins only)
+func (s *demoDataServiceImpl) ResetDemoData(ctx context.Context, businessID string) (*dto.DemoDataResetDTO, error) {
+	if !IsPlatformAdmin(ctx) {
+		if err := requireManager(ctx, s.staffRepo, businessID, "reset demo data"); err != nil {
+			return nil, err
+		}
+	}
+	ctx, err := actFor(ctx, businessID, "reset demo data")
+	if err != nil {
+		return nil, err
+	}
+
+	business, err := getBusiness(ctx, s.businessRepo, businessID)
+	if err != nil {
+		return nil, err
+	}
+	staff, err := s.staffRepo.FindActiveByBusinessID(ctx, businessID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve staff", err)
+	}
+	services, err := s.serviceRepo.FindBy(ctx, map[string]any{"business_id": businessID})
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve services", err)
+	}
+
+	now := time.Now()
+	dataset, err := domain.NewDemoDataset(business, staff, services, now, GetUserIDFromContext(ctx))
+	if err != nil {
+		return nil, toValidationError(err)
+	}
+
+	if err := s.demoDataRepo.Reset(ctx, businessID, dataset); err != nil {
+		return nil, NewServiceError("failed to reset demo data", err)
+	}
+	return dto.ToDemoDataResetDTO(businessID, dataset, now), nil
+}
@@ -0,0 +1,300 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/jobs"
+	"github.com/assimoes/beautix/internal/infrastructure/notification"
+	"github.com/rs/zerolog/log"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// depositExpiredReason is the cancellation reason of appointments released for an unpaid deposit
+const depositExpiredReason = "Released: the deposit was not paid in time"
+
+// errDepositInProgress defers releasing an appointment while its deposit may still go through
+var errDepositInProgress = errors.New("a deposit payment for the appointment is still in progress")
+
+// DepositFollowUp follows up the deposits of new bookings through the job queue: clients are
+// reminded to pay, and appointments still unpaid at the end of the business's deposit window
+// are cancelled to release the slot, telling both the client and the business
+type DepositFollowUp interface {
+	EventConsumer
+	RegisterJobs(runner *jobs.Runner)
+}
+
+// depositFollowUpImpl implements the DepositFollowUp interface
+type depositFollowUpImpl struct {
+	appointmentRepo      domain.BaseRepository[domain.Appointment]
+	businessRepo         domain.BusinessRepository
+	businessSettingsRepo domain.BusinessSettingsRepository
+	clientRepo           domain.BaseRepository[domain.Client]
+	paymentRepo          domain.PaymentRepository
+	appointmentService   AppointmentService
+	queue                *jobs.Queue
+	sender               notification.NotificationSender
+}
+
+// NewDepositFollowUp creates the event consumer scheduling deposit reminders and releases for
+// new bookings, along with the job handlers carrying them out
+func NewDepositFollowUp(
+	appointmentRepo domain.BaseRepository[domain.Appointment],
+	businessRepo domain.BusinessRepository,
+	businessSettingsRepo domain.BusinessSettingsRepository,
+	clientRepo domain.BaseRepository[domain.Client],
+	paymentRepo domain.PaymentRepository,
+	appointmentService AppointmentService,
+	queue *jobs.Queue,
+	sender notification.NotificationSender,
+) DepositFollowUp {
+	return &depositFollowUpImpl{
+		appointmentRepo:      appointmentRepo,
+		businessRepo:         businessRepo,
+		businessSettingsRepo: businessSettingsRepo,
+		clientRepo:           clientRepo,
+		paymentRepo:          paymentRepo,
+		appointmentService:   appointmentService,
+		queue:                queue,
+		sender:               sender,
+	}
+}
+
+// Name returns the consumer name used when replaying events
+func (f *depositFollowUpImpl) Name() string { return "deposit_follow_up" }
+
+// Handle schedules the reminders and release of a new booking whose services require a
+// deposit, and calls them off when the appointment is cancelled. The jobs are keyed by
+// appointment, so handling an event again does not schedule them twice.
+func (f *depositFollowUpImpl) Handle(ctx context.Context, event *domain.DomainEvent) error {
+	if event.AggregateType != domain.AggregateAppointment {
+		return nil
+	}
+	switch event.EventType {
+	case domain.EventAppointmentCreated:
+		return f.schedule(ctx, event)
+	case domain.EventAppointmentCancelled:
+		return f.callOff(ctx, event.AggregateID)
+	}
+	return nil
+}
+
+// RegisterJobs registers the handlers of the deposit jobs with a runner
+func (f *depositFollowUpImpl) RegisterJobs(runner *jobs.Runner) {
+	runner.Register(domain.JobDepositReminder, f.remind)
+	runner.Register(domain.JobDepositExpiry, f.expire)
+}
+
+// schedule enqueues the reminders and release of a booking awaiting its deposit
+func (f *depositFollowUpImpl) schedule(ctx context.Context, event *domain.DomainEvent) error {
+	appointment, err := f.appointmentRepo.GetByID(ctx, event.AggregateID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to retrieve appointment: %w", err)
+	}
+	if !appointment.CanBeCancelled() {
+		return nil
+	}
+	settings, err := f.businessSettingsRepo.GetByBusinessID(ctx, appointment.BusinessID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("failed to retrieve business settings: %w", err)
+	}
+	due, err := depositDue(ctx, f.paymentRepo, f.clientRepo, settings, appointment)
+	if err != nil {
+		return fmt.Errorf("failed to work out the deposit: %w", err)
+	}
+	if !due.IsPositive() {
+		return nil
+	}
+	deadline, ok := domain.NewDepositDeadline(event.OccurredAt, appointment.StartTime, domain.DepositWindowHours(settings))
+	if !ok {
+		return nil
+	}
+
+	for _, reminder := range deadline.Reminders {
+		payload := domain.DepositJobPayload{AppointmentID: appointment.ID, Stage: reminder.Stage, ExpiresAt: &deadline.ExpiresAt}
+		_, err := f.queue.Enqueue(ctx, domain.JobDepositReminder, payload,
+			jobs.WithKey(depositReminderKey(appointment.ID, reminder.Stage)), jobs.At(reminder.At), jobs.ForBusiness(appointment.BusinessID))
+		if err != nil {
+			return fmt.Errorf("failed to schedule deposit reminder: %w", err)
+		}
+	}
+	_, err = f.queue.Enqueue(ctx, domain.JobDepositExpiry, domain.DepositJobPayload{AppointmentID: appointment.ID},
+		jobs.WithKey(appointment.ID), jobs.At(deadline.ExpiresAt), jobs.ForBusiness(appointment.BusinessID))
+	if err != nil {
+		return fmt.Errorf("failed to schedule deposit release: %w", err)
+	}
+	return nil
+}
+
+// depositDue works out the deposit a booking needs: the deposits of its services, raised to the
+// no-show fee for clients the business flagged for no-shows
+func depositDue(ctx context.Context, paymentRepo domain.PaymentRepository, clientRepo domain.BaseRepository[domain.Client],
+	settings *domain.BusinessSettings, appointment *domain.Appointment) (decimal.Decimal, error) {
+	due, err := paymentRepo.FindDepositDue(ctx, appointment.ID)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	client, err := clientRepo.GetByID(ctx, appointment.ClientID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return decimal.Zero, err
+	}
+	return settings.GetCancellationPolicy().DepositDue(due, client), nil
+}
+
+// callOff cancels the pending deposit jobs of a cancelled appointment
+func (f *depositFollowUpImpl) callOff(ctx context.Context, appointmentID string) error {
+	for stage := 1; stage <= domain.DepositReminderStages(); stage++ {
+		if _, err := f.queue.Cancel(ctx, domain.JobDepositReminder, depositReminderKey(appointmentID, stage)); err != nil {
+			return fmt.Errorf("failed to cancel deposit reminder: %w", err)
+		}
+	}
+	if _, err := f.queue.Cancel(ctx, domain.JobDepositExpiry, appointmentID); err != nil {
+		return fmt.Errorf("failed to cancel deposit release: %w", err)
+	}
+	return nil
+}
+
+// remind reminds the client to pay the deposit of an appointment still awaiting it, unless a
+// payment is under way
+func (f *depositFollowUpImpl) remind(ctx context.Context, job *domain.Job) error {
+	var payload domain.DepositJobPayload
+	if err := job.DecodePayload(&payload); err != nil {
+		return jobs.Permanent(err)
+	}
+	appointment, payments, err := f.awaitingDeposit(ctx, payload.AppointmentID)
+	if err != nil || appointment == nil || domain.DepositInProgress(payments) {
+		return err
+	}
+
+	due, err := f.paymentRepo.FindDepositDue(ctx, appointment.ID)
+	if err != nil {
+		return fmt.Errorf("failed to work out the deposit: %w", err)
+	}
+	business, client, err := f.parties(ctx, appointment)
+	if err != nil {
+		return err
+	}
+	location := domain.NewAvailabilityRules(business, nil).Location
+
+	subject := "Deposit due for your appointment at " + business.GetDisplayName()
+	if payload.Stage == domain.DepositReminderStages() {
+		subject = "Final notice: " + subject
+	}
+	body := fmt.Sprintf("Hi %s,\n\nYour appointment at %s on %s needs a deposit of %s %s.",
+		client.FirstName, business.GetDisplayName(), appointment.StartTime.In(location).Format("Monday 2 January 15:04"),
+		due.StringFixed(2), business.Currency)
+	if payload.ExpiresAt != nil {
+		body += fmt.Sprintf(" Please pay it by %s, or the appointment will be cancelled to free the slot.",
+			payload.ExpiresAt.In(location).Format("Monday 2 January 15:04"))
+	}
+	return f.sender.Send(ctx, notification.Message{
+		BusinessID: business.ID,
+		Channel:    domain.MessageChannelEmail,
+		Recipient:  client.Email,
+		Subject:    &subject,
+		Body:       body,
+	})
+}
+
+// expire cancels an appointment whose deposit was not paid in time, releasing its slot, and
+// tells the client and the business. A deposit payment still in progress defers the release.
+func (f *depositFollowUpImpl) expire(ctx context.Context, job *domain.Job) error {
+	var payload domain.DepositJobPayload
+	if err := job.DecodePayload(&payload); err != nil {
+		return jobs.Permanent(err)
+	}
+	appointment, payments, err := f.awaitingDeposit(ctx, payload.AppointmentID)
+	if err != nil || appointment == nil {
+		return err
+	}
+	if domain.DepositInProgress(payments) {
+		return errDepositInProgress
+	}
+
+	if _, err := f.appointmentService.CancelAppointment(ctx, appointment.ID, dto.CancelAppointmentDTO{Reason: depositExpiredReason}); err != nil {
+		return err
+	}
+	// The slot is released; a failed notification only leaves the parties to find out otherwise
+	business, client, err := f.parties(ctx, appointment)
+	if err != nil {
+		log.Error().Err(err).Str("appointment_id", appointment.ID).Msg("Failed to notify release of unpaid appointment")
+		return nil
+	}
+	location := domain.NewAvailabilityRules(business, nil).Location
+	when := appointment.StartTime.In(location).Format("Monday 2 January 15:04")
+
+	clientSubject := "Your appointment at " + business.GetDisplayName() + " was cancelled"
+	businessSubject := "Appointment released for an unpaid deposit"
+	messages := []notification.Message{
+		{
+			BusinessID: business.ID,
+			Channel:    domain.MessageChannelEmail,
+			Recipient:  client.Email,
+			Subject:    &clientSubject,
+			Body: fmt.Sprintf("Hi %s,\n\nYour appointment at %s on %s was cancelled because its deposit was not paid in time. "+
+				"You are welcome to book again.", client.FirstName, business.GetDisplayName(), when),
+		},
+		{
+			BusinessID: business.ID,
+			Channel:    domain.MessageChannelEmail,
+			Recipient:  business.Email,
+			Subject:    &businessSubject,
+			Body: fmt.Sprintf("The appointment of %s on %s was cancelled because its deposit was not paid in time. The slot is free again.",
+				client.GetFullName(), when),
+		},
+	}
+	for _, message := range messages {
+		if err := f.sender.Send(ctx, message); err != nil {
+			log.Error().Err(err).Str("appointment_id", appointment.ID).Msg("Failed to notify release of unpaid appointment")
+		}
+	}
+	return nil
+}
+
+// awaitingDeposit retrieves an appointment still booked without its deposit paid, along with
+// its payments, or nil when it was paid, cancelled, has taken place or no longer exists
+func (f *depositFollowUpImpl) awaitingDeposit(ctx context.Context, appointmentID string) (*domain.Appointment, []*domain.Payment, error) {
+	appointment, err := f.appointmentRepo.GetByID(ctx, appointmentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("failed to retrieve appointment: %w", err)
+	}
+	if !appointment.CanBeCancelled() {
+		return nil, nil, nil
+	}
+	payments, err := f.paymentRepo.FindByAppointmentID(ctx, appointment.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to retrieve payments: %w", err)
+	}
+	if domain.DepositPaid(payments) {
+		return nil, nil, nil
+	}
+	return appointment, payments, nil
+}
+
+// parties retrieves the business and the client of an appointment
+func (f *depositFollowUpImpl) parties(ctx context.Context, appointment *domain.Appointment) (*domain.Business, *domain.Client, error) {
+	business, err := f.businessRepo.GetByID(ctx, appointment.BusinessID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to retrieve business: %w", err)
+	}
+	client, err := f.clientRepo.GetByID(ctx, appointment.ClientID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to retrieve client: %w", err)
+	}
+	return business, client, nil
+}
+
+// depositReminderKey returns the job key of a stage of an appointment's deposit reminders
+func depositReminderKey(appointmentID string, stage int) string {
+	return fmt.Sprintf("%s:%d", appointmentID, stage)
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// EntitlementService guards what a business may add against the limits of its subscription
+// plan. The services creating staff, campaigns and locations call it before adding them, and
+// SMS credits are taken as text messages are sent.
+type EntitlementService interface {
+	// Require checks that the business can add some of an entitlement, returning a
+	// *domain.UpgradeRequiredError when its plan does not allow it
+	Require(ctx context.Context, businessID string, entitlement domain.Entitlement, adding int) error
+	// UseSMSCredit takes one of the business's SMS credits of the month, returning a
+	// *domain.UpgradeRequiredError when none are left
+	UseSMSCredit(ctx context.Context, businessID string) error
+	// Usage counts how much of each entitlement the business uses
+	Usage(ctx context.Context, businessID string) (map[domain.Entitlement]int, error)
+}
+
+// entitlementServiceImpl implements the EntitlementService interface
+type entitlementServiceImpl struct {
+	subscriptionRepo domain.SubscriptionRepository
+	businessRepo     domain.BusinessRepository
+	now              func() time.Time
+}
+
+// NewEntitlementService creates a new entitlement service
+func NewEntitlementService(subscriptionRepo domain.SubscriptionRepository, businessRepo domain.BusinessRepository) EntitlementService {
+	return &entitlementServiceImpl{
+		subscriptionRepo: subscriptionRepo,
+		businessRepo:     businessRepo,
+		now:              time.Now,
+	}
+}
+
+// Require checks that the business can add some of an entitlement
+func (s *entitlementServiceImpl) Require(ctx context.Context, businessID string, entitlement domain.Entitlement, adding int) error {
+	business, err := getBusiness(ctx, s.businessRepo, businessID)
+	if err != nil {
+		return err
+	}
+	if domain.PlanFor(business.Tier()).Limit(entitlement) == domain.Unlimited {
+		return nil
+	}
+	used, err := s.subscriptionRepo.CountUsage(ctx, businessID, entitlement, s.now())
+	if err != nil {
+		return NewServiceError("failed to count usage", err)
+	}
+	return business.CheckEntitlement(entitlement, used, adding)
+}
+
+// UseSMSCredit takes one of the business's SMS credits of the month
+func (s *entitlementServiceImpl) UseSMSCredit(ctx context.Context, businessID string) error {
+	business, err := getBusiness(ctx, s.businessRepo, businessID)
+	if err != nil {
+		return err
+	}
+	plan := domain.PlanFor(business.Tier())
+	limit := plan.Limit(domain.EntitlementSMSCredits)
+	used, err := s.subscriptionRepo.UseSMSCredit(ctx, businessID, s.now(), limit)
+	if err != nil {
+		return NewServiceError("failed to count SMS credit", err)
+	}
+	if !used {
+		return &domain.UpgradeRequiredError{
+			Entitlement:  domain.EntitlementSMSCredits,
+			Tier:         plan.Tier,
+			Limit:        limit,
+			RequiredTier: domain.UpgradeFor(domain.EntitlementSMSCredits, limit, 1),
+		}
+	}
+	return nil
+}
+
+// Usage counts how much of each entitlement the business uses
+func (s *entitlementServiceImpl) Usage(ctx context.Context, businessID string) (map[domain.Entitlement]int, error) {
+	now := s.now()
+	usage := make(map[domain.Entitlement]int, len(domain.Entitlements))
+	for _, entitlement := range domain.Entitlements {
+		used, err := s.subscriptionRepo.CountUsage(ctx, businessID, entitlement, now)
+		if err != nil {
+			return nil, NewServiceError("failed to count usage", err)
+		}
+		usage[entitlement] = used
+	}
+	return usage, nil
+}
@@ -50,4 +50,20 @@ func NewNotFoundError(entityType, field, value string) error {
 		Field:      field,
 		Value:      value,
 	}
-}
\ No newline at end of file
+}
+// ForbiddenError represents an operation the current user is not allowed to perform
+type ForbiddenError struct {
+	Action string
+}
+
+// Error implements the error interface
+func (e ForbiddenError) Error() string {
+	return fmt.Sprintf("not allowed to %s", e.Action)
+}
+
+// NewForbiddenError creates a new forbidden error
+func NewForbiddenError(action string) error {
+	return ForbiddenError{
+		Action: action,
+	}
+}
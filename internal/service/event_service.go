@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/events"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// maxReplayEvents limits the number of events replayed in a single request
+const maxReplayEvents = 500
+
+// EventConsumer receives domain events, e.g. a projection, webhook dispatcher or warehouse export.
+// Handle must be idempotent: events are redelivered when publishing them fails and when they
+// are replayed.
+type EventConsumer interface {
+	Name() string
+	Handle(ctx context.Context, event *domain.DomainEvent) error
+}
+
+// EventService defines the service interface for the domain event log, which doubles as the
+// outbox events are published from
+type EventService interface {
+	RegisterConsumer(consumer EventConsumer)
+	Publish(ctx context.Context, event *domain.DomainEvent) error
+	Record(ctx context.Context, tx *gorm.DB, event *domain.DomainEvent) error
+	GetEvent(ctx context.Context, id string) (*dto.DomainEventResponseDTO, error)
+	ListEvents(ctx context.Context, filterDTO dto.DomainEventFilterDTO, page, pageSize int) ([]*dto.DomainEventResponseDTO, int64, error)
+	ListConsumers(ctx context.Context) ([]string, error)
+	ReplayEvents(ctx context.Context, replayDTO dto.ReplayEventsDTO) (*dto.ReplayResultDTO, error)
+}
+
+// eventServiceImpl implements the EventService interface
+type eventServiceImpl struct {
+	eventRepo domain.DomainEventRepository
+	outbox    *events.Outbox
+	relay     *events.Relay
+	validator *validator.Validate
+}
+
+// NewEventService creates a new event service recording events in the outbox, from which the
+// relay publishes them to the registered consumers
+func NewEventService(eventRepo domain.DomainEventRepository, outbox *events.Outbox, relay *events.Relay, validator *validator.Validate) EventService {
+	return &eventServiceImpl{
+		eventRepo: eventRepo,
+		outbox:    outbox,
+		relay:     relay,
+		validator: validator,
+	}
+}
+
+// RegisterConsumer subscribes a consumer to all published events. Consumers are
+// registered at startup, before any event is published.
+func (s *eventServiceImpl) RegisterConsumer(consumer EventConsumer) {
+	s.relay.Subscribe(consumer)
+}
+
+// Publish appends an event to the log on its own, for changes not made in a transaction, and
+// wakes the relay to publish it to every consumer
+func (s *eventServiceImpl) Publish(ctx context.Context, event *domain.DomainEvent) error {
+	if err := s.Record(ctx, nil, event); err != nil {
+		return err
+	}
+	s.relay.Notify()
+	return nil
+}
+
+// Record appends an event to the log within the transaction of the change it describes. The
+// relay publishes it to every consumer once the transaction commits; delivery failures are
+// retried and never roll back the change.
+func (s *eventServiceImpl) Record(ctx context.Context, tx *gorm.DB, event *domain.DomainEvent) error {
+	if err := event.Validate(); err != nil {
+		return toValidationError(err)
+	}
+
+	event.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.outbox.Record(ctx, tx, event); err != nil {
+		return NewServiceError("failed to append event", err)
+	}
+	return nil
+}
+
+// GetEvent retrieves a single event from the log
+func (s *eventServiceImpl) GetEvent(ctx context.Context, id string) (*dto.DomainEventResponseDTO, error) {
+	if !IsPlatformAdmin(ctx) {
+		return nil, NewForbiddenError("inspect the event log")
+	}
+
+	event, err := s.eventRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("event", "id", id)
+		}
+		return nil, NewServiceError("failed to retrieve event", err)
+	}
+
+	return dto.ToDomainEventResponseDTO(event), nil
+}
+
+// ListEvents browses the event log, oldest first
+func (s *eventServiceImpl) ListEvents(ctx context.Context, filterDTO dto.DomainEventFilterDTO, page, pageSize int) ([]*dto.DomainEventResponseDTO, int64, error) {
+	if !IsPlatformAdmin(ctx) {
+		return nil, 0, NewForbiddenError("inspect the event log")
+	}
+	if err := s.validator.Struct(filterDTO); err != nil {
+		return nil, 0, validation.FromError(err)
+	}
+
+	pagination := ValidatePagination(&dto.PaginationRequest{Page: page, PageSize: pageSize})
+	events, total, err := s.eventRepo.Search(ctx, dto.ToDomainEventFilter(filterDTO), pagination.Page, pagination.PageSize)
+	if err != nil {
+		return nil, 0, NewServiceError("failed to search events", err)
+	}
+
+	return dto.ToDomainEventResponseDTOs(events), total, nil
+}
+
+// ListConsumers returns the names of the registered consumers
+func (s *eventServiceImpl) ListConsumers(ctx context.Context) ([]string, error) {
+	if !IsPlatformAdmin(ctx) {
+		return nil, NewForbiddenError("inspect the event log")
+	}
+	return s.relay.Subscribers(), nil
+}
+
+// ReplayEvents redelivers the selected events, in the order they occurred, to the selected consumers
+func (s *eventServiceImpl) ReplayEvents(ctx context.Context, replayDTO dto.ReplayEventsDTO) (*dto.ReplayResultDTO, error) {
+	if !IsPlatformAdmin(ctx) {
+		return nil, NewForbiddenError("replay events")
+	}
+	if err := s.validator.Struct(replayDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	if (len(replayDTO.EventIDs) == 0) == (replayDTO.Filter == nil) {
+		return nil, validation.NewValidationError("either event_ids or filter is required")
+	}
+
+	consumers := make([]EventConsumer, 0, len(replayDTO.Consumers))
+	names := replayDTO.Consumers
+	if len(names) == 0 {
+		names = s.relay.Subscribers()
+	}
+	for _, name := range names {
+		consumer, ok := s.relay.Subscriber(name)
+		if !ok {
+			return nil, NewNotFoundError("event consumer", "name", name)
+		}
+		consumers = append(consumers, consumer)
+	}
+
+	events, err := s.selectReplayEvents(ctx, replayDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.ReplayResultDTO{
+		Events:     len(events),
+		Deliveries: []*dto.EventDeliveryResultDTO{},
+	}
+	replayedIDs := make([]string, 0, len(events))
+	for _, event := range events {
+		for _, consumer := range consumers {
+			delivery := &dto.EventDeliveryResultDTO{EventID: event.ID, Consumer: consumer.Name(), Success: true}
+			if err := consumer.Handle(ctx, event); err != nil {
+				message := err.Error()
+				delivery.Success = false
+				delivery.Error = &message
+				result.Failed++
+			} else {
+				result.Succeeded++
+			}
+			result.Deliveries = append(result.Deliveries, delivery)
+		}
+		replayedIDs = append(replayedIDs, event.ID)
+	}
+
+	if len(replayedIDs) > 0 {
+		if err := s.eventRepo.MarkReplayed(ctx, replayedIDs, time.Now()); err != nil {
+			return nil, NewServiceError("failed to record replay", err)
+		}
+	}
+
+	return result, nil
+}
+
+// selectReplayEvents loads the events selected by ID or filter
+func (s *eventServiceImpl) selectReplayEvents(ctx context.Context, replayDTO dto.ReplayEventsDTO) ([]*domain.DomainEvent, error) {
+	if len(replayDTO.EventIDs) > 0 {
+		events, err := s.eventRepo.FindByIDs(ctx, replayDTO.EventIDs)
+		if err != nil {
+			return nil, NewServiceError("failed to retrieve events", err)
+		}
+		if len(events) != len(replayDTO.EventIDs) {
+			return nil, validation.NewValidationError("one or more events do not exist")
+		}
+		return events, nil
+	}
+
+	events, total, err := s.eventRepo.Search(ctx, dto.ToDomainEventFilter(*replayDTO.Filter), 1, maxReplayEvents)
+	if err != nil {
+		return nil, NewServiceError("failed to search events", err)
+	}
+	if total > maxReplayEvents {
+		return nil, validation.NewValidationError("filter matches more than 500 events, narrow it down")
+	}
+	return events, nil
+}
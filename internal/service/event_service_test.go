@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/events"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/assimoes/beautix/internal/repository/memory"
+)
+
+// appointmentStatuses projects appointment events into the status of each appointment. Changes
+// to an appointment it has not seen created are out of order and refused.
+type appointmentStatuses struct {
+	statuses map[string]string
+	handled  []string
+}
+
+func (p *appointmentStatuses) Name() string { return "appointment-statuses" }
+
+func (p *appointmentStatuses) Handle(ctx context.Context, event *domain.DomainEvent) error {
+	p.handled = append(p.handled, event.ID)
+	if event.EventType == domain.EventAppointmentCreated {
+		p.statuses[event.AggregateID] = "created"
+		return nil
+	}
+	if _, ok := p.statuses[event.AggregateID]; !ok {
+		return fmt.Errorf("%s for unknown appointment %s", event.EventType, event.AggregateID)
+	}
+	p.statuses[event.AggregateID] = event.EventType
+	return nil
+}
+
+// replayFixture returns an event service with the projection subscribed and the events of an
+// appointment's life recorded in it, oldest first
+func replayFixture(t *testing.T) (EventService, *appointmentStatuses, domain.DomainEventRepository, []*domain.DomainEvent) {
+	t.Helper()
+	eventRepo := memory.NewDomainEventRepository(memory.NewDB())
+	s := NewEventService(eventRepo, events.NewOutbox(eventRepo), events.NewRelay(eventRepo), validator.New())
+	projection := &appointmentStatuses{statuses: map[string]string{}}
+	s.RegisterConsumer(projection)
+
+	appointmentID := "6f1c2a4e-8a55-4c3e-9d2b-1f0a7c9e2b11"
+	start := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	recorded := make([]*domain.DomainEvent, 0, 3)
+	for i, eventType := range []string{domain.EventAppointmentCreated, domain.EventAppointmentUpdated, domain.EventAppointmentCancelled} {
+		event, err := domain.NewDomainEvent(domain.AggregateAppointment, appointmentID, eventType, nil, nil)
+		require.NoError(t, err)
+		event.OccurredAt = start.Add(time.Duration(i) * time.Minute)
+		require.NoError(t, s.Record(context.Background(), nil, event))
+		recorded = append(recorded, event)
+	}
+	return s, projection, eventRepo, recorded
+}
+
+func TestEventService_ReplayEvents(t *testing.T) {
+	s, projection, eventRepo, recorded := replayFixture(t)
+	admin := SetUserContext(context.Background(), "admin-1", PlatformAdminRole, nil)
+
+	// Selected out of order, the events are replayed in the order they occurred
+	result, err := s.ReplayEvents(admin, dto.ReplayEventsDTO{EventIDs: []string{recorded[2].ID, recorded[0].ID, recorded[1].ID}})
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Events)
+	assert.Equal(t, 3, result.Succeeded)
+	assert.Zero(t, result.Failed)
+	assert.Equal(t, []string{recorded[0].ID, recorded[1].ID, recorded[2].ID}, projection.handled)
+	assert.Equal(t, map[string]string{recorded[0].AggregateID: domain.EventAppointmentCancelled}, projection.statuses)
+
+	replayed, err := eventRepo.GetByID(context.Background(), recorded[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, replayed.ReplayCount)
+	assert.NotNil(t, replayed.LastReplayedAt)
+}
+
+func TestEventService_ReplayEventsReportsFailedDeliveries(t *testing.T) {
+	s, projection, _, recorded := replayFixture(t)
+	admin := SetUserContext(context.Background(), "admin-1", PlatformAdminRole, nil)
+
+	// The cancellation alone reaches the projection before the appointment's creation
+	result, err := s.ReplayEvents(admin, dto.ReplayEventsDTO{EventIDs: []string{recorded[2].ID}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Failed)
+	require.Len(t, result.Deliveries, 1)
+	assert.False(t, result.Deliveries[0].Success)
+	require.NotNil(t, result.Deliveries[0].Error)
+	assert.Contains(t, *result.Deliveries[0].Error, "unknown appointment")
+	assert.Empty(t, projection.statuses)
+}
+
+func TestEventService_ReplayEventsRejectsUnknownSelections(t *testing.T) {
+	s, projection, eventRepo, recorded := replayFixture(t)
+	admin := SetUserContext(context.Background(), "admin-1", PlatformAdminRole, nil)
+
+	var invalid *validation.ValidationError
+	_, err := s.ReplayEvents(admin, dto.ReplayEventsDTO{EventIDs: []string{recorded[0].ID, "0b7e9c1d-2f4a-4b6e-8c3d-5a9f1e7b2c40"}})
+	assert.ErrorAs(t, err, &invalid, "unknown events are refused")
+
+	var notFound NotFoundError
+	_, err = s.ReplayEvents(admin, dto.ReplayEventsDTO{EventIDs: []string{recorded[0].ID}, Consumers: []string{"warehouse"}})
+	assert.ErrorAs(t, err, &notFound, "unknown consumers are refused")
+
+	var forbidden ForbiddenError
+	owner := SetUserContext(context.Background(), "owner-1", "owner", nil)
+	_, err = s.ReplayEvents(owner, dto.ReplayEventsDTO{EventIDs: []string{recorded[0].ID}})
+	assert.ErrorAs(t, err, &forbidden, "only platform admins replay events")
+
+	assert.Empty(t, projection.handled, "nothing is delivered when the selection is refused")
+	event, err := eventRepo.GetByID(context.Background(), recorded[0].ID)
+	require.NoError(t, err)
+	assert.Zero(t, event.ReplayCount)
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// GiftCardService defines the service interface for selling gift cards and checking their
+// balance. Cards are redeemed through the payment service, as payments of appointments.
+type GiftCardService interface {
+	SellGiftCard(ctx context.Context, sellDTO dto.SellGiftCardDTO) (*dto.GiftCardResponseDTO, error)
+	GetGiftCard(ctx context.Context, id string) (*dto.GiftCardResponseDTO, error)
+	CheckGiftCardBalance(ctx context.Context, businessID, code string) (*dto.GiftCardBalanceDTO, error)
+}
+
+// giftCardServiceImpl implements the GiftCardService interface
+type giftCardServiceImpl struct {
+	giftCardRepo domain.GiftCardRepository
+	businessRepo domain.BusinessRepository
+	clientRepo   domain.BaseRepository[domain.Client]
+	staffRepo    domain.StaffRepository
+	validator    *validator.Validate
+}
+
+// NewGiftCardService creates a new gift card service
+func NewGiftCardService(
+	giftCardRepo domain.GiftCardRepository,
+	businessRepo domain.BusinessRepository,
+	clientRepo domain.BaseRepository[domain.Client],
+	staffRepo domain.StaffRepository,
+	validator *validator.Validate,
+) GiftCardService {
+	return &giftCardServiceImpl{
+		giftCardRepo: giftCardRepo,
+		businessRepo: businessRepo,
+		clientRepo:   clientRepo,
+		staffRepo:    staffRepo,
+		validator:    validator,
+	}
+}
+
+// SellGiftCard issues a gift card worth the amount paid for it, in the business's currency,
+// with a new random code. Only the business's staff can sell its gift cards.
+func (s *giftCardServiceImpl) SellGiftCard(ctx context.Context, sellDTO dto.SellGiftCardDTO) (*dto.GiftCardResponseDTO, error) {
+	if err := s.validator.Struct(sellDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	now := time.Now()
+	if sellDTO.ExpiresAt != nil && !sellDTO.ExpiresAt.After(now) {
+		return nil, validation.NewValidationError("expires_at must be in the future")
+	}
+	if err := requireStaff(ctx, s.staffRepo, sellDTO.BusinessID, "sell gift cards"); err != nil {
+		return nil, err
+	}
+	ctx, err := actFor(ctx, sellDTO.BusinessID, "sell gift cards")
+	if err != nil {
+		return nil, err
+	}
+
+	business, err := s.businessRepo.GetByID(ctx, sellDTO.BusinessID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("business", "id", sellDTO.BusinessID)
+		}
+		return nil, NewServiceError("failed to retrieve business", err)
+	}
+	if sellDTO.PurchaserClientID != nil {
+		client, err := s.clientRepo.GetByID(ctx, *sellDTO.PurchaserClientID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewServiceError("failed to retrieve client", err)
+		}
+		if client == nil || client.BusinessID != business.ID {
+			return nil, NewNotFoundError("client", "id", *sellDTO.PurchaserClientID)
+		}
+	}
+
+	code, err := domain.NewGiftCardCode()
+	if err != nil {
+		return nil, NewServiceError("failed to issue gift card", err)
+	}
+	card := &domain.GiftCard{
+		BusinessID:        business.ID,
+		Code:              code,
+		InitialValue:      sellDTO.Amount,
+		Balance:           sellDTO.Amount,
+		Currency:          business.Currency,
+		ExpiresAt:         sellDTO.ExpiresAt,
+		PurchaserClientID: sellDTO.PurchaserClientID,
+		RecipientName:     sellDTO.RecipientName,
+		RecipientEmail:    sellDTO.RecipientEmail,
+		Message:           sellDTO.Message,
+		SalePaymentMethod: sellDTO.PaymentMethod,
+	}
+	if err := card.Validate(); err != nil {
+		return nil, toValidationError(err)
+	}
+
+	card.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.giftCardRepo.Issue(ctx, card); err != nil {
+		return nil, NewServiceError("failed to issue gift card", err)
+	}
+	transactions, err := s.giftCardRepo.FindTransactions(ctx, card.ID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve gift card transactions", err)
+	}
+	return dto.ToGiftCardResponseDTO(card, transactions, now), nil
+}
+
+// GetGiftCard retrieves a gift card with its balance changes, oldest first
+func (s *giftCardServiceImpl) GetGiftCard(ctx context.Context, id string) (*dto.GiftCardResponseDTO, error) {
+	card, err := s.giftCardRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("gift card", "id", id)
+		}
+		return nil, NewServiceError("failed to retrieve gift card", err)
+	}
+	transactions, err := s.giftCardRepo.FindTransactions(ctx, card.ID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve gift card transactions", err)
+	}
+	return dto.ToGiftCardResponseDTO(card, transactions, time.Now()), nil
+}
+
+// CheckGiftCardBalance looks up a gift card of a business by the code the client presents
+func (s *giftCardServiceImpl) CheckGiftCardBalance(ctx context.Context, businessID, code string) (*dto.GiftCardBalanceDTO, error) {
+	if businessID == "" || code == "" {
+		return nil, validation.NewValidationError("business_id and code are required")
+	}
+	card, err := findGiftCard(ctx, s.giftCardRepo, businessID, code)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToGiftCardBalanceDTO(card, time.Now()), nil
+}
+
+// findGiftCard finds a gift card of a business by a code as typed, reporting a missing one as
+// not found
+func findGiftCard(ctx context.Context, giftCardRepo domain.GiftCardRepository, businessID, code string) (*domain.GiftCard, error) {
+	card, err := giftCardRepo.FindByCode(ctx, businessID, domain.NormalizeGiftCardCode(code))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("gift card", "code", code)
+		}
+		return nil, NewServiceError("failed to retrieve gift card", err)
+	}
+	return card, nil
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/repository/memory"
+)
+
+func TestGiftCardService_SellGiftCard(t *testing.T) {
+	db := memory.NewDB()
+	business := &domain.Business{UserID: "owner-1", Name: "Salon", Currency: "EUR"}
+	other := &domain.Business{UserID: "owner-2", Name: "Spa", Currency: "EUR"}
+	require.NoError(t, memory.Insert(db, business, other))
+	require.NoError(t, memory.Insert(db, &domain.Staff{BusinessID: business.ID, UserID: "user-1", Role: domain.BusinessRoleEmployee, IsActive: true}))
+	giftCards := NewGiftCardService(memory.NewGiftCardRepository(db), memory.NewBusinessRepository(db),
+		memory.NewBaseRepository[domain.Client](db, memory.WithTenantScope()), memory.NewStaffRepository(db), validator.New())
+	sell := func(businessID string) dto.SellGiftCardDTO {
+		return dto.SellGiftCardDTO{BusinessID: businessID, Amount: decimal.NewFromInt(50), PaymentMethod: "card"}
+	}
+
+	staffCtx := SetUserContext(context.Background(), "user-1", "employee", &business.ID)
+	card, err := giftCards.SellGiftCard(staffCtx, sell(business.ID))
+	require.NoError(t, err)
+	assert.Equal(t, business.ID, card.BusinessID)
+	assert.True(t, decimal.NewFromInt(50).Equal(card.Balance))
+
+	var forbidden ForbiddenError
+	_, err = giftCards.SellGiftCard(staffCtx, sell(other.ID))
+	assert.ErrorAs(t, err, &forbidden, "staff cannot sell another business's cards")
+
+	outsider := SetUserContext(context.Background(), "user-2", "employee", nil)
+	_, err = giftCards.SellGiftCard(outsider, sell(business.ID))
+	assert.ErrorAs(t, err, &forbidden, "only the business's staff can sell its cards")
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+)
+
+// IdempotencyService defines the service interface for the idempotency keys clients send with
+// mutations they may retry, so that a retry gets the first response rather than running the
+// mutation again
+type IdempotencyService interface {
+	// ClaimIdempotencyKey claims a key for a request. It returns a completed key to replay when
+	// the request was already answered, and a pending key for the caller to complete or release
+	// otherwise.
+	ClaimIdempotencyKey(ctx context.Context, key, requestHash string) (*domain.IdempotencyKey, error)
+	CompleteIdempotencyKey(ctx context.Context, claim *domain.IdempotencyKey, response []byte) error
+	ReleaseIdempotencyKey(ctx context.Context, claim *domain.IdempotencyKey) error
+	PruneIdempotencyKeys(ctx context.Context, now time.Time) (int64, error)
+}
+
+// idempotencyServiceImpl implements the IdempotencyService interface
+type idempotencyServiceImpl struct {
+	keyRepo domain.IdempotencyKeyRepository
+}
+
+// NewIdempotencyService creates a new idempotency service
+func NewIdempotencyService(keyRepo domain.IdempotencyKeyRepository) IdempotencyService {
+	return &idempotencyServiceImpl{keyRepo: keyRepo}
+}
+
+// ClaimIdempotencyKey claims a key for the request of the signed-in user, or of an anonymous
+// caller's IP address, as on the public booking API. A key already stored for them is returned
+// when it was sent with the same request and completed, and refused with
+// domain.ErrIdempotencyKeyReused or domain.ErrIdempotencyKeyInProgress otherwise.
+func (s *idempotencyServiceImpl) ClaimIdempotencyKey(ctx context.Context, key, requestHash string) (*domain.IdempotencyKey, error) {
+	userID := GetUserIDFromContext(ctx)
+	var scope string
+	switch ip := GetClientIPFromContext(ctx); {
+	case userID != nil:
+		scope = domain.UserIdempotencyScope(*userID)
+	case ip != "":
+		scope = domain.ClientIdempotencyScope(ip)
+	default:
+		return nil, NewForbiddenError("send idempotency keys")
+	}
+	if key == "" || len(key) > domain.MaxIdempotencyKeyLength {
+		return nil, validation.NewValidationError(fmt.Sprintf("the idempotency key must have 1 to %d characters", domain.MaxIdempotencyKeyLength))
+	}
+
+	now := time.Now()
+	claim, claimed, err := s.keyRepo.Claim(ctx, domain.NewIdempotencyKey(scope, userID, key, requestHash, now), now)
+	if err != nil {
+		return nil, NewServiceError("failed to claim idempotency key", err)
+	}
+	if !claimed {
+		if err := claim.CheckRetry(requestHash); err != nil {
+			return nil, err
+		}
+	}
+	return claim, nil
+}
+
+// CompleteIdempotencyKey records the response to the request of a claimed key, to be replayed to
+// its retries
+func (s *idempotencyServiceImpl) CompleteIdempotencyKey(ctx context.Context, claim *domain.IdempotencyKey, response []byte) error {
+	if err := s.keyRepo.Complete(ctx, claim.ID, string(response)); err != nil {
+		return NewServiceError("failed to record idempotent response", err)
+	}
+	return nil
+}
+
+// ReleaseIdempotencyKey gives up a claimed key without recording a response, so that a retry runs
+// the request again
+func (s *idempotencyServiceImpl) ReleaseIdempotencyKey(ctx context.Context, claim *domain.IdempotencyKey) error {
+	if err := s.keyRepo.Release(ctx, claim.ID); err != nil {
+		return NewServiceError("failed to release idempotency key", err)
+	}
+	return nil
+}
+
+// PruneIdempotencyKeys deletes the keys whose responses are no longer replayed
+func (s *idempotencyServiceImpl) PruneIdempotencyKeys(ctx context.Context, now time.Time) (int64, error) {
+	removed, err := s.keyRepo.DeleteExpired(ctx, now)
+	if err != nil {
+		return 0, NewServiceError("failed to prune idempotency keys", err)
+	}
+	return removed, nil
+}
@@ -0,0 +1,303 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/residency"
+	"github.com/assimoes/beautix/internal/infrastructure/storage"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// imageCleanupBatch bounds the uploads a cleanup run removes, leaving the rest to the next run
+const imageCleanupBatch = 500
+
+// ImageUploadService defines the service interface for uploading images: business logos and cover
+// photos, and staff profile pictures. Files are sent straight to object storage through presigned
+// URLs, then validated, scaled down and set on their target when the upload is completed.
+type ImageUploadService interface {
+	CreateImageUpload(ctx context.Context, createDTO dto.CreateImageUploadDTO) (*dto.ImageUploadTicketDTO, error)
+	CompleteImageUpload(ctx context.Context, id string) (*dto.ImageUploadResponseDTO, error)
+	CleanupImageUploads(ctx context.Context, now time.Time) (*dto.ImageCleanupRunDTO, error)
+}
+
+// imageUploadServiceImpl implements the ImageUploadService interface
+type imageUploadServiceImpl struct {
+	uploadRepo   domain.ImageUploadRepository
+	businessRepo domain.BusinessRepository
+	staffRepo    domain.StaffRepository
+	store        storage.Store
+	buckets      *residency.Buckets
+	urlExpiry    time.Duration
+	validator    *validator.Validate
+}
+
+// NewImageUploadService creates a new image upload service. Upload URLs stay valid for urlExpiry.
+func NewImageUploadService(
+	uploadRepo domain.ImageUploadRepository,
+	businessRepo domain.BusinessRepository,
+	staffRepo domain.StaffRepository,
+	store storage.Store,
+	buckets *residency.Buckets,
+	urlExpiry time.Duration,
+	validator *validator.Validate,
+) ImageUploadService {
+	return &imageUploadServiceImpl{
+		uploadRepo:   uploadRepo,
+		businessRepo: businessRepo,
+		staffRepo:    staffRepo,
+		store:        store,
+		buckets:      buckets,
+		urlExpiry:    urlExpiry,
+		validator:    validator,
+	}
+}
+
+// CreateImageUpload records an image upload and signs the URL its file is sent to, in the bucket
+// of the business's data region. Business images are uploaded by owners and managers; staff
+// photos also by the staff member pictured.
+func (s *imageUploadServiceImpl) CreateImageUpload(ctx context.Context, createDTO dto.CreateImageUploadDTO) (*dto.ImageUploadTicketDTO, error) {
+	if err := s.validator.Struct(createDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	targetID := createDTO.BusinessID
+	if createDTO.Purpose == domain.ImagePurposeStaffPhoto {
+		if createDTO.StaffID == nil {
+			return nil, validation.NewValidationError("staff_id is required for staff photos")
+		}
+		targetID = *createDTO.StaffID
+	} else if createDTO.StaffID != nil {
+		return nil, validation.NewValidationError("staff_id is only given for staff photos")
+	}
+	if err := s.authorize(ctx, createDTO.BusinessID, createDTO.Purpose, targetID); err != nil {
+		return nil, err
+	}
+	business, err := getBusiness(ctx, s.businessRepo, createDTO.BusinessID)
+	if err != nil {
+		return nil, err
+	}
+	region := business.DataRegion
+	if region == "" {
+		region = domain.DefaultDataRegion
+	}
+	bucket, err := s.buckets.Select(region)
+	if err != nil {
+		return nil, NewServiceError("no storage is available for the business's data region", err)
+	}
+
+	now := time.Now().UTC()
+	upload := &domain.ImageUpload{
+		BaseModel:       domain.BaseModel{ID: domain.NewID(), CreatedBy: GetUserIDFromContext(ctx)},
+		BusinessID:      createDTO.BusinessID,
+		Purpose:         createDTO.Purpose,
+		TargetID:        targetID,
+		ContentType:     createDTO.ContentType,
+		SizeBytes:       createDTO.SizeBytes,
+		Status:          domain.ImageUploadPending,
+		Bucket:          bucket,
+		UploadExpiresAt: now.Add(s.urlExpiry).Truncate(time.Second),
+	}
+	upload.UploadKey = upload.UploadKeyFor()
+	if err := upload.Validate(); err != nil {
+		return nil, toValidationError(err)
+	}
+
+	uploadURL, err := s.store.SignedUploadURL(bucket, upload.UploadKey, upload.ContentType, upload.SizeBytes, s.urlExpiry)
+	if err != nil {
+		return nil, NewServiceError("failed to sign upload URL", err)
+	}
+	if err := s.uploadRepo.Create(ctx, upload); err != nil {
+		return nil, NewServiceError("failed to create image upload", err)
+	}
+	return &dto.ImageUploadTicketDTO{
+		Upload:    dto.ToImageUploadResponseDTO(upload),
+		UploadURL: uploadURL,
+		Method:    http.MethodPut,
+		Headers: []dto.UploadHeaderDTO{
+			{Name: "Content-Type", Value: upload.ContentType},
+			{Name: "Content-Length", Value: strconv.FormatInt(upload.SizeBytes, 10)},
+		},
+		ExpiresAt: upload.UploadExpiresAt,
+	}, nil
+}
+
+// CompleteImageUpload processes an uploaded image and sets it on its target. Files that are not
+// valid images of the declared type are rejected. The image the target showed before is left to
+// the cleanup.
+func (s *imageUploadServiceImpl) CompleteImageUpload(ctx context.Context, id string) (*dto.ImageUploadResponseDTO, error) {
+	upload, err := s.uploadRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("image upload", "id", id)
+		}
+		return nil, NewServiceError("failed to retrieve image upload", err)
+	}
+	if err := s.authorize(ctx, upload.BusinessID, upload.Purpose, upload.TargetID); err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	if upload.Status == domain.ImageUploadAttached {
+		return dto.ToImageUploadResponseDTO(upload), nil
+	}
+	if !upload.CanComplete(now) {
+		if upload.Status == domain.ImageUploadRejected && upload.Error != nil {
+			return nil, validation.NewValidationError("the upload was rejected: " + *upload.Error)
+		}
+		return nil, validation.NewValidationError("the upload has expired; request a new one")
+	}
+
+	data, err := s.store.Get(ctx, upload.Bucket, upload.UploadKey, domain.MaxImageUploadBytes)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrNotFound):
+			return nil, validation.NewValidationError("the file has not been uploaded yet")
+		case errors.Is(err, storage.ErrTooLarge):
+			return nil, s.reject(ctx, upload, "the file is larger than allowed")
+		}
+		return nil, NewServiceError("failed to read uploaded file", err)
+	}
+	processed, err := storage.ProcessImage(data, upload.ContentType, upload.Purpose.MaxDimension())
+	if err != nil {
+		if errors.Is(err, storage.ErrInvalidImage) {
+			return nil, s.reject(ctx, upload, err.Error())
+		}
+		return nil, NewServiceError("failed to process image", err)
+	}
+
+	key := upload.ObjectKeyFor(processed.Extension)
+	if err := s.store.Put(ctx, storage.Object{Bucket: upload.Bucket, Key: key, ContentType: processed.ContentType, Body: processed.Body}); err != nil {
+		return nil, NewServiceError("failed to store image", err)
+	}
+	url, err := s.store.ObjectURL(upload.Bucket, key)
+	if err != nil {
+		return nil, NewServiceError("failed to locate image", err)
+	}
+	// The target is updated before the upload is attached, so that the cleanup never sees an
+	// attached upload its target has yet to show
+	if err := s.setOnTarget(ctx, upload, url); err != nil {
+		return nil, err
+	}
+	upload.Attach(key, url, processed.Width, processed.Height, now)
+	if err := s.uploadRepo.Update(ctx, upload); err != nil {
+		return nil, NewServiceError("failed to update image upload", err)
+	}
+	if err := s.store.Delete(ctx, upload.Bucket, upload.UploadKey); err != nil {
+		log.Warn().Err(err).Str("upload_id", upload.ID).Msg("Failed to remove uploaded file")
+	}
+	return dto.ToImageUploadResponseDTO(upload), nil
+}
+
+// CleanupImageUploads removes the files of uploads no longer shown anywhere: abandoned, rejected
+// or replaced on their target. An upload that fails is logged and retried on the next run.
+func (s *imageUploadServiceImpl) CleanupImageUploads(ctx context.Context, now time.Time) (*dto.ImageCleanupRunDTO, error) {
+	uploads, err := s.uploadRepo.FindUnreferenced(ctx, now.Add(-domain.ImageUploadGracePeriod), imageCleanupBatch)
+	if err != nil {
+		return nil, NewServiceError("failed to find unreferenced image uploads", err)
+	}
+
+	run := &dto.ImageCleanupRunDTO{}
+	for _, upload := range uploads {
+		uploadCtx := domain.WithTenant(ctx, domain.TenantContext{BusinessID: upload.BusinessID})
+		if err := s.remove(uploadCtx, upload, now); err != nil {
+			log.Warn().Err(err).Str("business_id", upload.BusinessID).Str("upload_id", upload.ID).Msg("Failed to remove image upload")
+			run.Failed++
+			continue
+		}
+		run.Removed++
+	}
+	return run, nil
+}
+
+// remove deletes the files of an upload from storage
+func (s *imageUploadServiceImpl) remove(ctx context.Context, upload *domain.ImageUpload, now time.Time) error {
+	if err := s.store.Delete(ctx, upload.Bucket, upload.UploadKey); err != nil {
+		return err
+	}
+	if upload.ObjectKey != nil {
+		if err := s.store.Delete(ctx, upload.Bucket, *upload.ObjectKey); err != nil {
+			return err
+		}
+	}
+	upload.MarkRemoved(now)
+	return s.uploadRepo.Update(ctx, upload)
+}
+
+// reject records an upload as rejected, returning the validation error to report
+func (s *imageUploadServiceImpl) reject(ctx context.Context, upload *domain.ImageUpload, message string) error {
+	upload.Reject(message)
+	if err := s.uploadRepo.Update(ctx, upload); err != nil {
+		return NewServiceError("failed to update image upload", err)
+	}
+	return validation.NewValidationError("the upload was rejected: " + message)
+}
+
+// setOnTarget sets the URL of an upload's image on the business or staff member it is for
+func (s *imageUploadServiceImpl) setOnTarget(ctx context.Context, upload *domain.ImageUpload, url string) error {
+	var changes domain.ChangeSet
+	changes.Set(upload.Purpose.Column(), url)
+	var err error
+	if upload.Purpose == domain.ImagePurposeStaffPhoto {
+		_, err = s.staffRepo.UpdateFields(ctx, upload.TargetID, changes)
+	} else {
+		_, err = s.businessRepo.UpdateFields(ctx, upload.TargetID, changes)
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewNotFoundError(string(upload.Purpose)+" target", "id", upload.TargetID)
+		}
+		return NewServiceError("failed to set image", err)
+	}
+	return nil
+}
+
+// authorize checks that the caller may change an image of a business: owners and managers may
+// change any, and staff members their own photo
+func (s *imageUploadServiceImpl) authorize(ctx context.Context, businessID string, purpose domain.ImagePurpose, targetID string) error {
+	const action = "change the images of the business"
+	userID := GetUserIDFromContext(ctx)
+	if userID == nil {
+		return NewForbiddenError(action)
+	}
+	caller, err := s.staffRepo.FindByBusinessAndUser(ctx, businessID, *userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewForbiddenError(action)
+		}
+		return NewServiceError("failed to retrieve staff member", err)
+	}
+	if !caller.IsActive {
+		return NewForbiddenError(action)
+	}
+
+	if purpose != domain.ImagePurposeStaffPhoto {
+		if !caller.CanManage() {
+			return NewForbiddenError(action)
+		}
+		return nil
+	}
+	if caller.ID == targetID {
+		return nil
+	}
+	if !caller.CanManage() {
+		return NewForbiddenError("change the photo of another staff member")
+	}
+	target, err := s.staffRepo.GetByID(ctx, targetID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewNotFoundError("staff", "id", targetID)
+		}
+		return NewServiceError("failed to retrieve staff member", err)
+	}
+	if target.BusinessID != businessID {
+		return NewNotFoundError("staff", "id", targetID)
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+)
+
+// maxIntegrationUsageDays limits the date range of a usage report
+const maxIntegrationUsageDays = 90
+
+// IntegrationUsageService defines the service interface for integration usage analytics
+type IntegrationUsageService interface {
+	RecordAPIRequest(ctx context.Context, businessID, sourceID string, failed bool) error
+	RecordWebhookDelivery(ctx context.Context, businessID, endpointID string, delivered bool) error
+	GetUsage(ctx context.Context, businessID string, startDate, endDate time.Time) (*dto.IntegrationUsageReportDTO, error)
+}
+
+// integrationUsageServiceImpl implements the IntegrationUsageService interface
+type integrationUsageServiceImpl struct {
+	usageRepo domain.IntegrationUsageRepository
+	staffRepo domain.StaffRepository
+	now       func() time.Time
+}
+
+// NewIntegrationUsageService creates a new integration usage service
+func NewIntegrationUsageService(usageRepo domain.IntegrationUsageRepository, staffRepo domain.StaffRepository) IntegrationUsageService {
+	return &integrationUsageServiceImpl{
+		usageRepo: usageRepo,
+		staffRepo: staffRepo,
+		now:       time.Now,
+	}
+}
+
+// RecordAPIRequest counts an API request made on behalf of a business
+func (s *integrationUsageServiceImpl) RecordAPIRequest(ctx context.Context, businessID, sourceID string, failed bool) error {
+	if businessID == "" {
+		return validation.NewValidationError("business_id is required")
+	}
+
+	if err := s.usageRepo.Increment(ctx, businessID, s.now().UTC(), domain.IntegrationChannelAPI, sourceID, failed); err != nil {
+		return NewServiceError("failed to record API request", err)
+	}
+	return nil
+}
+
+// RecordWebhookDelivery counts a webhook delivery attempt to a business's endpoint
+func (s *integrationUsageServiceImpl) RecordWebhookDelivery(ctx context.Context, businessID, endpointID string, delivered bool) error {
+	if businessID == "" {
+		return validation.NewValidationError("business_id is required")
+	}
+
+	if err := s.usageRepo.Increment(ctx, businessID, s.now().UTC(), domain.IntegrationChannelWebhook, endpointID, !delivered); err != nil {
+		return NewServiceError("failed to record webhook delivery", err)
+	}
+	return nil
+}
+
+// GetUsage returns a business's daily integration traffic between two dates, inclusive.
+// Days are UTC calendar days; days without traffic are included with zero counts. Only the
+// business's owners and platform admins can see it.
+func (s *integrationUsageServiceImpl) GetUsage(ctx context.Context, businessID string, startDate, endDate time.Time) (*dto.IntegrationUsageReportDTO, error) {
+	if businessID == "" {
+		return nil, validation.NewValidationError("business_id is required")
+	}
+	if !IsPlatformAdmin(ctx) {
+		if err := requireOwner(ctx, s.staffRepo, businessID, "view integration usage"); err != nil {
+			return nil, err
+		}
+	}
+	ctx, err := actFor(ctx, businessID, "view integration usage")
+	if err != nil {
+		return nil, err
+	}
+
+	start := truncateToDay(startDate)
+	end := truncateToDay(endDate)
+	if end.Before(start) {
+		return nil, validation.NewValidationError("end_date must not be before start_date")
+	}
+	if end.Sub(start) >= maxIntegrationUsageDays*24*time.Hour {
+		return nil, validation.NewValidationError("date range cannot exceed 90 days")
+	}
+
+	usage, err := s.usageRepo.FindByBusiness(ctx, businessID, start, end)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve integration usage", err)
+	}
+
+	days := make(map[string]*dto.IntegrationUsageDayDTO)
+	report := &dto.IntegrationUsageReportDTO{
+		BusinessID: businessID,
+		StartDate:  start,
+		EndDate:    end,
+		Days:       []*dto.IntegrationUsageDayDTO{},
+	}
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		entry := &dto.IntegrationUsageDayDTO{Date: day, Sources: []*dto.IntegrationUsageSourceDTO{}}
+		days[day.Format("2006-01-02")] = entry
+		report.Days = append(report.Days, entry)
+	}
+
+	apiErrors, webhookFailures := 0, 0
+	for _, row := range usage {
+		entry, ok := days[row.UsageDate.Format("2006-01-02")]
+		if !ok {
+			continue
+		}
+
+		entry.Sources = append(entry.Sources, &dto.IntegrationUsageSourceDTO{
+			Channel:   row.Channel,
+			SourceID:  row.SourceID,
+			Requests:  row.RequestCount,
+			Errors:    row.ErrorCount,
+			ErrorRate: row.ErrorRate(),
+		})
+
+		switch row.Channel {
+		case domain.IntegrationChannelAPI:
+			entry.APIRequests += row.RequestCount
+			entry.APIErrors += row.ErrorCount
+			report.APIRequests += row.RequestCount
+			apiErrors += row.ErrorCount
+		case domain.IntegrationChannelWebhook:
+			entry.WebhookDeliveries += row.RequestCount
+			entry.WebhookFailures += row.ErrorCount
+			report.WebhookDeliveries += row.RequestCount
+			webhookFailures += row.ErrorCount
+		}
+	}
+
+	for _, entry := range report.Days {
+		entry.APIErrorRate = ratio(entry.APIErrors, entry.APIRequests)
+		entry.WebhookSuccessRate = successRatio(entry.WebhookFailures, entry.WebhookDeliveries)
+	}
+	report.APIErrorRate = ratio(apiErrors, report.APIRequests)
+	report.WebhookSuccessRate = successRatio(webhookFailures, report.WebhookDeliveries)
+
+	return report, nil
+}
+
+// truncateToDay returns the start of the UTC day containing t
+func truncateToDay(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// ratio returns part/total, or 0 when there is no traffic
+func ratio(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total)
+}
+
+// successRatio returns the share of successful attempts, or 1 when there were no attempts
+func successRatio(failures, total int) float64 {
+	if total == 0 {
+		return 1
+	}
+	return 1 - ratio(failures, total)
+}
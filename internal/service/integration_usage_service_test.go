@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/assimoes/beautix/internal/repository/memory"
+)
+
+// usageFixture stores a business owned by owner-1 with employee-1 on its staff, and returns a
+// usage service recording traffic on 2 March 2026
+func usageFixture(t *testing.T) (*integrationUsageServiceImpl, *domain.Business) {
+	t.Helper()
+	db := memory.NewDB()
+	business := &domain.Business{UserID: "owner-1", Name: "Salon"}
+	require.NoError(t, memory.Insert(db, business))
+	require.NoError(t, memory.Insert(db,
+		&domain.Staff{BusinessID: business.ID, UserID: "owner-1", Role: domain.BusinessRoleOwner, IsActive: true},
+		&domain.Staff{BusinessID: business.ID, UserID: "employee-1", Role: domain.BusinessRoleEmployee, IsActive: true},
+	))
+	usage := NewIntegrationUsageService(memory.NewIntegrationUsageRepository(db), memory.NewStaffRepository(db)).(*integrationUsageServiceImpl)
+	usage.now = func() time.Time { return time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC) }
+	return usage, business
+}
+
+func TestIntegrationUsageService_GetUsage(t *testing.T) {
+	usage, business := usageFixture(t)
+	ctx := context.Background()
+	require.NoError(t, usage.RecordAPIRequest(ctx, business.ID, "key-1", false))
+	require.NoError(t, usage.RecordAPIRequest(ctx, business.ID, "key-1", true))
+	require.NoError(t, usage.RecordWebhookDelivery(ctx, business.ID, "endpoint-1", true))
+
+	owner := SetUserContext(ctx, "owner-1", "owner", &business.ID)
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	report, err := usage.GetUsage(owner, business.ID, start, start.AddDate(0, 0, 2))
+	require.NoError(t, err)
+	require.Len(t, report.Days, 3)
+	assert.Equal(t, 2, report.APIRequests)
+	assert.Equal(t, 0.5, report.APIErrorRate)
+	assert.Equal(t, 1, report.WebhookDeliveries)
+	assert.Equal(t, 2, report.Days[1].APIRequests)
+	assert.Zero(t, report.Days[0].APIRequests)
+
+	admin := SetUserContext(ctx, "admin-1", PlatformAdminRole, nil)
+	_, err = usage.GetUsage(admin, business.ID, start, start)
+	assert.NoError(t, err, "platform admins see any business's usage")
+}
+
+func TestIntegrationUsageService_GetUsageRequiresOwner(t *testing.T) {
+	usage, business := usageFixture(t)
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	var forbidden ForbiddenError
+	for name, ctx := range map[string]context.Context{
+		"employee":   SetUserContext(context.Background(), "employee-1", "employee", &business.ID),
+		"outsider":   SetUserContext(context.Background(), "user-2", "owner", nil),
+		"signed out": context.Background(),
+	} {
+		_, err := usage.GetUsage(ctx, business.ID, start, start)
+		assert.ErrorAs(t, err, &forbidden, name)
+	}
+}
+
+func TestIntegrationUsageService_GetUsageEmptyRange(t *testing.T) {
+	usage, business := usageFixture(t)
+	owner := SetUserContext(context.Background(), "owner-1", "owner", &business.ID)
+	start := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+
+	report, err := usage.GetUsage(owner, business.ID, start, start.AddDate(0, 0, 6))
+	require.NoError(t, err)
+	require.Len(t, report.Days, 7, "days without traffic are reported with zero counts")
+	assert.Zero(t, report.APIRequests)
+	assert.Zero(t, report.APIErrorRate)
+	assert.Equal(t, 1.0, report.WebhookSuccessRate)
+	for _, day := range report.Days {
+		assert.Empty(t, day.Sources)
+	}
+
+	_, err = usage.GetUsage(owner, business.ID, start, start.AddDate(0, 0, -1))
+	var invalid *validation.ValidationError
+	assert.ErrorAs(t, err, &invalid, "the range must not end before it starts")
+}
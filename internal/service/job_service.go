@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/jobs"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// JobService defines the service interface for operating the background job queue
+type JobService interface {
+	ListJobs(ctx context.Context, filterDTO dto.JobFilterDTO, page, pageSize int) ([]*dto.JobResponseDTO, int64, error)
+	RetryJob(ctx context.Context, id string) (*dto.JobResponseDTO, error)
+}
+
+// jobServiceImpl implements the JobService interface
+type jobServiceImpl struct {
+	jobRepo   domain.JobRepository
+	runner    *jobs.Runner
+	validator *validator.Validate
+}
+
+// NewJobService creates a new job service
+func NewJobService(jobRepo domain.JobRepository, runner *jobs.Runner, validator *validator.Validate) JobService {
+	return &jobServiceImpl{
+		jobRepo:   jobRepo,
+		runner:    runner,
+		validator: validator,
+	}
+}
+
+// ListJobs browses the job queue, most recently due first. Jobs concern every business, so only
+// platform operators may see them.
+func (s *jobServiceImpl) ListJobs(ctx context.Context, filterDTO dto.JobFilterDTO, page, pageSize int) ([]*dto.JobResponseDTO, int64, error) {
+	if !IsPlatformAdmin(ctx) {
+		return nil, 0, NewForbiddenError("inspect background jobs")
+	}
+	if err := s.validator.Struct(filterDTO); err != nil {
+		return nil, 0, validation.FromError(err)
+	}
+
+	pagination := ValidatePagination(&dto.PaginationRequest{Page: page, PageSize: pageSize})
+	jobList, total, err := s.jobRepo.Search(ctx, dto.ToJobFilter(filterDTO), pagination.Page, pagination.PageSize)
+	if err != nil {
+		return nil, 0, NewServiceError("failed to search jobs", err)
+	}
+
+	return dto.ToJobResponseDTOs(jobList), total, nil
+}
+
+// RetryJob puts a dead-lettered job back in the queue with a fresh set of attempts, e.g. once
+// what made it fail has been fixed
+func (s *jobServiceImpl) RetryJob(ctx context.Context, id string) (*dto.JobResponseDTO, error) {
+	if !IsPlatformAdmin(ctx) {
+		return nil, NewForbiddenError("retry background jobs")
+	}
+
+	job, err := s.jobRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("job", "id", id)
+		}
+		return nil, NewServiceError("failed to retrieve job", err)
+	}
+	if err := job.Retry(time.Now()); err != nil {
+		return nil, toValidationError(err)
+	}
+	if err := s.jobRepo.Update(ctx, job); err != nil {
+		return nil, NewServiceError("failed to retry job", err)
+	}
+	s.runner.Notify()
+
+	return dto.ToJobResponseDTO(job), nil
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"gorm.io/gorm"
+)
+
+// loyaltyEngine awards loyalty points for completed appointments
+type loyaltyEngine struct {
+	loyaltyRepo     domain.LoyaltyRepository
+	appointmentRepo domain.BaseRepository[domain.Appointment]
+	completionRepo  domain.ServiceCompletionRepository
+}
+
+// NewLoyaltyEngine creates the event consumer that awards loyalty points when appointments are
+// completed
+func NewLoyaltyEngine(
+	loyaltyRepo domain.LoyaltyRepository,
+	appointmentRepo domain.BaseRepository[domain.Appointment],
+	completionRepo domain.ServiceCompletionRepository,
+) EventConsumer {
+	return &loyaltyEngine{
+		loyaltyRepo:     loyaltyRepo,
+		appointmentRepo: appointmentRepo,
+		completionRepo:  completionRepo,
+	}
+}
+
+// Name returns the consumer name used when replaying events
+func (e *loyaltyEngine) Name() string { return "loyalty_engine" }
+
+// Handle awards the points a completed appointment earns in each of the business's running
+// programs, joining the client to programs on their first earning visit, and awards the
+// referral bonus when a referred client completes their first visit. Points are only awarded
+// once per appointment and membership, and bonuses once per referral, so handling is idempotent.
+func (e *loyaltyEngine) Handle(ctx context.Context, event *domain.DomainEvent) error {
+	if event.AggregateType != domain.AggregateAppointment || event.EventType != domain.EventAppointmentCompleted {
+		return nil
+	}
+
+	appointment, err := e.appointmentRepo.GetByID(ctx, event.AggregateID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve appointment: %w", err)
+	}
+	completion, err := e.completionRepo.FindByAppointmentID(ctx, appointment.ID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve completion: %w", err)
+	}
+	at := event.OccurredAt
+	if completion.CompletionDate != nil {
+		at = *completion.CompletionDate
+	}
+
+	var errs []error
+	if err := e.convertReferral(ctx, appointment, at); err != nil {
+		errs = append(errs, fmt.Errorf("referral: %w", err))
+	}
+
+	programs, err := e.loyaltyRepo.FindRunningPrograms(ctx, appointment.BusinessID, at)
+	if err != nil || len(programs) == 0 {
+		return errors.Join(append(errs, err)...)
+	}
+	lines, err := e.loyaltyRepo.FindAppointmentLines(ctx, appointment.ID)
+	if err != nil {
+		return errors.Join(append(errs, fmt.Errorf("failed to retrieve appointment services: %w", err))...)
+	}
+	visit := domain.LoyaltyVisit{PriceCharged: completion.PriceCharged, Lines: lines}
+
+	for _, program := range programs {
+		if err := e.accrue(ctx, program, appointment, visit, at); err != nil {
+			errs = append(errs, fmt.Errorf("program %s: %w", program.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// accrue awards the points a visit earns in a program
+func (e *loyaltyEngine) accrue(ctx context.Context, program *domain.LoyaltyProgram, appointment *domain.Appointment, visit domain.LoyaltyVisit, at time.Time) error {
+	rules, err := program.GetRules()
+	if err != nil {
+		return err
+	}
+	points, spend := rules.Earned(visit)
+	if points == 0 {
+		return nil
+	}
+
+	membership, err := e.loyaltyRepo.FindMembership(ctx, program.ID, appointment.ClientID)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		membership = &domain.ClientLoyaltyMembership{
+			ProgramID: program.ID,
+			ClientID:  appointment.ClientID,
+			JoinDate:  at,
+			IsActive:  true,
+		}
+	case err != nil:
+		return err
+	case !membership.IsActiveAt(at):
+		return nil
+	default:
+		earned, err := e.loyaltyRepo.HasEarned(ctx, membership.ID, appointment.ID)
+		if err != nil || earned {
+			return err
+		}
+	}
+
+	if err := membership.Earn(rules, points, spend, at); err != nil {
+		return err
+	}
+	description := fmt.Sprintf("Visit on %s", appointment.StartTime.Format("2006-01-02"))
+	transaction := &domain.LoyaltyTransaction{
+		AppointmentID:   &appointment.ID,
+		TransactionType: domain.LoyaltyTransactionEarn,
+		Points:          points,
+		Description:     &description,
+	}
+	userID := GetUserIDFromContext(ctx)
+	membership.SetAuditFields(userID)
+	transaction.SetAuditFields(userID)
+	return e.loyaltyRepo.RecordEarning(ctx, membership, transaction)
+}
+
+// convertReferral converts the referral of a client completing their first visit, awarding the
+// referrer the bonus of the program they referred the client to while it is running
+func (e *loyaltyEngine) convertReferral(ctx context.Context, appointment *domain.Appointment, at time.Time) error {
+	referral, err := e.loyaltyRepo.FindReferral(ctx, appointment.BusinessID, appointment.ClientID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil || referral.Status == domain.ReferralConverted {
+		return err
+	}
+
+	program, err := e.loyaltyRepo.GetByID(ctx, referral.ProgramID)
+	if err != nil {
+		return err
+	}
+	rules, err := program.GetRules()
+	if err != nil {
+		return err
+	}
+	membership, err := e.loyaltyRepo.FindMembership(ctx, referral.ProgramID, referral.ReferrerClientID)
+	if err != nil {
+		return err
+	}
+	points := 0
+	if program.IsRunningAt(at) {
+		if points, err = membership.AwardReferralBonus(rules, at); err != nil {
+			return err
+		}
+	}
+	if err := referral.Convert(appointment.ID, points, at); err != nil {
+		return err
+	}
+
+	userID := GetUserIDFromContext(ctx)
+	referral.SetAuditFields(userID)
+	var transaction *domain.LoyaltyTransaction
+	if points > 0 {
+		description := "Referral bonus"
+		transaction = &domain.LoyaltyTransaction{
+			AppointmentID:   &appointment.ID,
+			TransactionType: domain.LoyaltyTransactionReferral,
+			Points:          points,
+			Description:     &description,
+		}
+		membership.SetAuditFields(userID)
+		transaction.SetAuditFields(userID)
+	}
+	return e.loyaltyRepo.RecordReferralBonus(ctx, referral, membership, transaction)
+}
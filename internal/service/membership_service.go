@@ -0,0 +1,562 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/stripe"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// MembershipService defines the service interface for recurring memberships and session
+// packages, billed monthly to the client's stored card with dunning when payments fail
+type MembershipService interface {
+	CreatePlan(ctx context.Context, createDTO dto.CreateMembershipPlanDTO) (*dto.MembershipPlanResponseDTO, error)
+	Subscribe(ctx context.Context, subscribeDTO dto.SubscribeMembershipDTO) (*dto.ClientMembershipResponseDTO, error)
+	UpdatePaymentMethod(ctx context.Context, updateDTO dto.UpdateMembershipPaymentMethodDTO) (*dto.ClientMembershipResponseDTO, error)
+	CancelMembership(ctx context.Context, id string) (*dto.ClientMembershipResponseDTO, error)
+	GetCancellationQuote(ctx context.Context, id string) (*dto.MembershipCancellationQuoteDTO, error)
+	UseSession(ctx context.Context, id string) (*dto.ClientMembershipResponseDTO, error)
+	GetClientMemberships(ctx context.Context, clientID string) ([]*dto.ClientMembershipResponseDTO, error)
+	RunBilling(ctx context.Context, now time.Time) (*dto.MembershipBillingRunDTO, error)
+}
+
+// membershipServiceImpl implements the MembershipService interface
+type membershipServiceImpl struct {
+	planRepo       domain.BaseRepository[domain.MembershipPlan]
+	membershipRepo domain.MembershipRepository
+	clientRepo     domain.BaseRepository[domain.Client]
+	businessRepo   domain.BusinessRepository
+	staffRepo      domain.StaffRepository
+	gateway        stripe.Gateway
+	validator      *validator.Validate
+}
+
+// NewMembershipService creates a new membership service
+func NewMembershipService(
+	planRepo domain.BaseRepository[domain.MembershipPlan],
+	membershipRepo domain.MembershipRepository,
+	clientRepo domain.BaseRepository[domain.Client],
+	businessRepo domain.BusinessRepository,
+	staffRepo domain.StaffRepository,
+	gateway stripe.Gateway,
+	validator *validator.Validate,
+) MembershipService {
+	return &membershipServiceImpl{
+		planRepo:       planRepo,
+		membershipRepo: membershipRepo,
+		clientRepo:     clientRepo,
+		businessRepo:   businessRepo,
+		staffRepo:      staffRepo,
+		gateway:        gateway,
+		validator:      validator,
+	}
+}
+
+// CreatePlan creates a monthly membership plan priced in the business's currency
+func (s *membershipServiceImpl) CreatePlan(ctx context.Context, createDTO dto.CreateMembershipPlanDTO) (*dto.MembershipPlanResponseDTO, error) {
+	if err := s.validator.Struct(createDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	ctx, err := actFor(ctx, createDTO.BusinessID, "manage memberships")
+	if err != nil {
+		return nil, err
+	}
+	if err := requireManager(ctx, s.staffRepo, createDTO.BusinessID, "manage memberships"); err != nil {
+		return nil, err
+	}
+	business, err := getBusiness(ctx, s.businessRepo, createDTO.BusinessID)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &domain.MembershipPlan{
+		BusinessID:             createDTO.BusinessID,
+		Name:                   strings.TrimSpace(createDTO.Name),
+		Description:            createDTO.Description,
+		Price:                  createDTO.Price,
+		Currency:               business.Currency,
+		BillingInterval:        domain.BillingMonthly,
+		SessionsPerPeriod:      createDTO.SessionsPerPeriod,
+		RefundPolicy:           domain.RefundPolicyNone,
+		CancellationFeePercent: createDTO.CancellationFeePercent,
+		IsActive:               true,
+	}
+	if createDTO.RefundPolicy != "" {
+		plan.RefundPolicy = createDTO.RefundPolicy
+	}
+	if err := plan.Validate(); err != nil {
+		return nil, toValidationError(err)
+	}
+
+	plan.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.planRepo.Create(ctx, plan); err != nil {
+		return nil, NewServiceError("failed to create membership plan", err)
+	}
+	return dto.ToMembershipPlanResponseDTO(plan), nil
+}
+
+// Subscribe subscribes a client to a plan and bills the first month at once. A declined card
+// leaves the membership past due, retried like any other failed payment.
+func (s *membershipServiceImpl) Subscribe(ctx context.Context, subscribeDTO dto.SubscribeMembershipDTO) (*dto.ClientMembershipResponseDTO, error) {
+	if err := s.validator.Struct(subscribeDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	plan, err := s.getPlan(ctx, subscribeDTO.PlanID)
+	if err != nil {
+		return nil, err
+	}
+	ctx, err = actFor(ctx, plan.BusinessID, "manage memberships")
+	if err != nil {
+		return nil, err
+	}
+	if err := requireManager(ctx, s.staffRepo, plan.BusinessID, "manage memberships"); err != nil {
+		return nil, err
+	}
+	if _, err := s.getClient(ctx, plan.BusinessID, subscribeDTO.ClientID); err != nil {
+		return nil, err
+	}
+
+	_, err = s.membershipRepo.FindCurrent(ctx, subscribeDTO.ClientID, plan.ID)
+	switch {
+	case err == nil:
+		return nil, validation.NewValidationError("the client is already a member of the plan")
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, NewServiceError("failed to retrieve membership", err)
+	}
+
+	now := time.Now()
+	membership, err := domain.NewClientMembership(plan, subscribeDTO.ClientID, subscribeDTO.PaymentMethodID, subscribeDTO.StripeCustomerID, now)
+	if err != nil {
+		return nil, toValidationError(err)
+	}
+	membership.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.membershipRepo.Create(ctx, membership); err != nil {
+		return nil, NewServiceError("failed to create membership", err)
+	}
+
+	if _, err := s.bill(ctx, membership, now); err != nil {
+		// The membership is due, so the next billing run charges it
+		log.Warn().Err(err).Str("membership_id", membership.ID).Msg("Failed to bill new membership")
+	}
+	return s.toResponse(ctx, membership, plan, now)
+}
+
+// UpdatePaymentMethod replaces the card a membership is billed to. Past due memberships are
+// retried with the new card at once, and suspended ones restart with a new period.
+func (s *membershipServiceImpl) UpdatePaymentMethod(ctx context.Context, updateDTO dto.UpdateMembershipPaymentMethodDTO) (*dto.ClientMembershipResponseDTO, error) {
+	if err := s.validator.Struct(updateDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	membership, err := s.getMembership(ctx, updateDTO.MembershipID)
+	if err != nil {
+		return nil, err
+	}
+	ctx, err = actFor(ctx, membership.BusinessID, "manage memberships")
+	if err != nil {
+		return nil, err
+	}
+	if err := requireManager(ctx, s.staffRepo, membership.BusinessID, "manage memberships"); err != nil {
+		return nil, err
+	}
+	if membership.Status == domain.MembershipCancelled {
+		return nil, validation.NewValidationError("the membership is cancelled")
+	}
+
+	now := time.Now()
+	membership.PaymentMethodID = updateDTO.PaymentMethodID
+	if updateDTO.StripeCustomerID != nil {
+		membership.StripeCustomerID = updateDTO.StripeCustomerID
+	}
+	switch membership.Status {
+	case domain.MembershipSuspended:
+		if err := membership.Reactivate(now); err != nil {
+			return nil, toValidationError(err)
+		}
+	case domain.MembershipPastDue:
+		membership.NextRetryAt = &now
+	}
+	membership.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.membershipRepo.Update(ctx, membership); err != nil {
+		return nil, NewServiceError("failed to update membership", err)
+	}
+
+	if membership.BillingAction(now) != domain.MembershipBillingNone {
+		if _, err := s.bill(ctx, membership, now); err != nil {
+			log.Warn().Err(err).Str("membership_id", membership.ID).Msg("Failed to bill membership")
+		}
+	}
+	return s.toResponse(ctx, membership, nil, now)
+}
+
+// CancelMembership stops billing a membership. Under the plan's refund policy the unused part
+// of the paid period, less the cancellation fee, is refunded to the card or issued as store
+// credit on a gift card, and the period and its sessions end at once. Otherwise the client
+// keeps access until the end of the paid period.
+func (s *membershipServiceImpl) CancelMembership(ctx context.Context, id string) (*dto.ClientMembershipResponseDTO, error) {
+	membership, err := s.getMembership(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	ctx, err = actFor(ctx, membership.BusinessID, "manage memberships")
+	if err != nil {
+		return nil, err
+	}
+	if err := requireManager(ctx, s.staffRepo, membership.BusinessID, "manage memberships"); err != nil {
+		return nil, err
+	}
+	plan, err := s.getPlan(ctx, membership.PlanID)
+	if err != nil {
+		return nil, err
+	}
+	invoice, err := s.paidInvoice(ctx, membership)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	proration := membership.ProRate(plan, invoice, now)
+	if err := membership.Cancel(now); err != nil {
+		return nil, toValidationError(err)
+	}
+	userID := GetUserIDFromContext(ctx)
+	membership.SetAuditFields(userID)
+	if !proration.Policy.GivesBack() || !proration.Amount.IsPositive() {
+		if err := s.membershipRepo.Update(ctx, membership); err != nil {
+			return nil, NewServiceError("failed to cancel membership", err)
+		}
+		return s.toResponse(ctx, membership, plan, now)
+	}
+
+	membership.ForfeitPeriod(now)
+	creditNote := &domain.MembershipCreditNote{
+		BusinessID:        membership.BusinessID,
+		InvoiceID:         invoice.ID,
+		ClientID:          membership.ClientID,
+		Kind:              domain.CreditNoteCredit,
+		UnusedShare:       proration.UnusedShare,
+		Fee:               proration.Fee,
+		Amount:            proration.Amount,
+		Currency:          proration.Currency,
+		SessionsForfeited: proration.SessionsRemaining,
+	}
+	creditNote.SetAuditFields(userID)
+
+	var giftCard *domain.GiftCard
+	if proration.Policy == domain.RefundPolicyRefund && invoice.PaymentIntentID != nil {
+		// Refunded before anything is saved, keyed by the invoice, so that a cancellation
+		// retried after a failed save cannot refund twice
+		refund, err := s.gateway.Refund(ctx, stripe.RefundParams{
+			PaymentIntentID: *invoice.PaymentIntentID,
+			Amount:          domain.ToMinorUnits(proration.Amount, proration.Currency),
+			IdempotencyKey:  fmt.Sprintf("membership-invoice-%s-cancellation", invoice.ID),
+		})
+		if err != nil {
+			return nil, toPaymentError("failed to refund membership", err)
+		}
+		creditNote.Kind = domain.CreditNoteRefund
+		creditNote.RefundID = &refund.ID
+	} else {
+		// Store credit, which also covers a period paid without a payment intent on record
+		code, err := domain.NewGiftCardCode()
+		if err != nil {
+			return nil, NewServiceError("failed to issue store credit", err)
+		}
+		giftCard = &domain.GiftCard{
+			BusinessID:        membership.BusinessID,
+			Code:              code,
+			InitialValue:      proration.Amount,
+			Balance:           proration.Amount,
+			Currency:          proration.Currency,
+			PurchaserClientID: &membership.ClientID,
+			SalePaymentMethod: domain.PaymentMethodCreditNote,
+		}
+		giftCard.SetAuditFields(userID)
+	}
+
+	if err := s.membershipRepo.Cancel(ctx, membership, creditNote, giftCard); err != nil {
+		return nil, NewServiceError("failed to cancel membership", err)
+	}
+	return s.toResponse(ctx, membership, plan, now)
+}
+
+// GetCancellationQuote works out what cancelling a membership now would give back under its
+// plan's refund policy, without cancelling it
+func (s *membershipServiceImpl) GetCancellationQuote(ctx context.Context, id string) (*dto.MembershipCancellationQuoteDTO, error) {
+	membership, err := s.getMembership(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	ctx, err = actFor(ctx, membership.BusinessID, "manage memberships")
+	if err != nil {
+		return nil, err
+	}
+	if err := requireManager(ctx, s.staffRepo, membership.BusinessID, "manage memberships"); err != nil {
+		return nil, err
+	}
+	if membership.Status == domain.MembershipCancelled {
+		return nil, validation.NewValidationError("the membership is already cancelled")
+	}
+	plan, err := s.getPlan(ctx, membership.PlanID)
+	if err != nil {
+		return nil, err
+	}
+	invoice, err := s.paidInvoice(ctx, membership)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToMembershipCancellationQuoteDTO(membership, membership.ProRate(plan, invoice, time.Now())), nil
+}
+
+// UseSession takes a session off a package membership's current period, for instance when the
+// client checks in. Memberships without sessions have unlimited access and are left untouched.
+func (s *membershipServiceImpl) UseSession(ctx context.Context, id string) (*dto.ClientMembershipResponseDTO, error) {
+	membership, err := s.getMembership(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	ctx, err = actFor(ctx, membership.BusinessID, "manage memberships")
+	if err != nil {
+		return nil, err
+	}
+	if err := requireManager(ctx, s.staffRepo, membership.BusinessID, "manage memberships"); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := membership.CanUseSession(now); err != nil {
+		return nil, validation.FromError(err)
+	}
+	if membership.SessionsRemaining != nil {
+		if err := s.membershipRepo.UseSession(ctx, membership.ID, GetUserIDFromContext(ctx)); err != nil {
+			if errors.Is(err, domain.ErrNoSessionsLeft) {
+				return nil, validation.FromError(err)
+			}
+			return nil, NewServiceError("failed to use membership session", err)
+		}
+		if membership, err = s.getMembership(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	return s.toResponse(ctx, membership, nil, now)
+}
+
+// GetClientMemberships retrieves a client's memberships with their billing status and invoices
+func (s *membershipServiceImpl) GetClientMemberships(ctx context.Context, clientID string) ([]*dto.ClientMembershipResponseDTO, error) {
+	client, err := s.getClient(ctx, "", clientID)
+	if err != nil {
+		return nil, err
+	}
+	ctx, err = actFor(ctx, client.BusinessID, "view memberships")
+	if err != nil {
+		return nil, err
+	}
+
+	memberships, err := s.membershipRepo.FindByClientID(ctx, client.ID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve memberships", err)
+	}
+	now := time.Now()
+	responses := make([]*dto.ClientMembershipResponseDTO, 0, len(memberships))
+	for _, membership := range memberships {
+		response, err := s.toResponse(ctx, membership, nil, now)
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, response)
+	}
+	return responses, nil
+}
+
+// RunBilling bills every membership whose next period is due, retries failed payments whose
+// retry is due and suspends memberships whose grace period ended unpaid. Memberships that
+// could not be billed, for instance because the payment provider was down, are left for the
+// next run.
+func (s *membershipServiceImpl) RunBilling(ctx context.Context, now time.Time) (*dto.MembershipBillingRunDTO, error) {
+	memberships, err := s.membershipRepo.FindDue(ctx, now)
+	if err != nil {
+		return nil, NewServiceError("failed to find due memberships", err)
+	}
+
+	run := &dto.MembershipBillingRunDTO{Due: len(memberships)}
+	for _, membership := range memberships {
+		tenantCtx := domain.WithTenant(ctx, domain.TenantContext{BusinessID: membership.BusinessID})
+		status, err := s.bill(tenantCtx, membership, now)
+		if err != nil {
+			log.Warn().Err(err).Str("membership_id", membership.ID).Msg("Failed to bill membership")
+			run.Errors++
+			continue
+		}
+		switch status {
+		case domain.MembershipActive:
+			run.Paid++
+		case domain.MembershipPastDue:
+			run.Failed++
+		case domain.MembershipSuspended:
+			run.Suspended++
+		}
+	}
+	return run, nil
+}
+
+// bill does what the membership's billing action at the given time calls for, returning the
+// membership's status afterwards
+func (s *membershipServiceImpl) bill(ctx context.Context, membership *domain.ClientMembership, now time.Time) (domain.MembershipStatus, error) {
+	action := membership.BillingAction(now)
+	if action == domain.MembershipBillingNone {
+		return membership.Status, nil
+	}
+
+	invoice, err := s.membershipRepo.FindOpenInvoice(ctx, membership.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", fmt.Errorf("failed to retrieve open invoice: %w", err)
+	}
+
+	if action == domain.MembershipBillingSuspend {
+		membership.Suspend(invoice, now)
+		if err := s.membershipRepo.SaveBilling(ctx, membership, invoice); err != nil {
+			return "", fmt.Errorf("failed to suspend membership: %w", err)
+		}
+		return membership.Status, nil
+	}
+
+	if invoice == nil {
+		// The invoice is saved before charging so its ID can key the payment, and an
+		// interrupted run charges the same invoice again
+		invoice = membership.NextInvoice()
+		if err := s.membershipRepo.SaveBilling(ctx, membership, invoice); err != nil {
+			return "", fmt.Errorf("failed to create invoice: %w", err)
+		}
+	}
+	return s.collect(ctx, membership, invoice, now)
+}
+
+// collect charges an open invoice to the membership's stored card, recording the payment or
+// the failure. Errors other than a declined card leave both untouched, to be retried.
+func (s *membershipServiceImpl) collect(ctx context.Context, membership *domain.ClientMembership, invoice *domain.MembershipInvoice, now time.Time) (domain.MembershipStatus, error) {
+	params := stripe.ChargeParams{
+		Amount:          domain.ToMinorUnits(invoice.Amount, invoice.Currency),
+		Currency:        invoice.Currency,
+		PaymentMethodID: membership.PaymentMethodID,
+		OffSession:      true,
+		Description: fmt.Sprintf("Membership from %s to %s",
+			invoice.PeriodStart.Format("2006-01-02"), invoice.PeriodEnd.Format("2006-01-02")),
+		Metadata: map[string]string{
+			"membership_id":         membership.ID,
+			"membership_invoice_id": invoice.ID,
+			"business_id":           membership.BusinessID,
+		},
+		IdempotencyKey: invoice.IdempotencyKey(),
+	}
+	if membership.StripeCustomerID != nil {
+		params.CustomerID = *membership.StripeCustomerID
+	}
+
+	intent, err := s.gateway.Charge(ctx, params)
+	var stripeErr *stripe.Error
+	switch {
+	case err == nil && intent.PaymentStatus() == domain.PaymentStatusSucceeded:
+		membership.RecordPayment(invoice, intent.ID, now)
+	case err == nil && intent.PaymentStatus() == domain.PaymentStatusPending:
+		return "", fmt.Errorf("payment %s is still processing", intent.ID)
+	case err == nil:
+		// The client is not there to authenticate the card, so an intent needing action has
+		// failed as much as a declined one
+		invoice.PaymentIntentID = &intent.ID
+		membership.RecordFailure(invoice, intent.FailureMessage(), now)
+	case errors.As(err, &stripeErr) && stripeErr.IsCardError():
+		membership.RecordFailure(invoice, stripeErr.Message, now)
+	default:
+		return "", fmt.Errorf("failed to charge card: %w", err)
+	}
+
+	if err := s.membershipRepo.SaveBilling(ctx, membership, invoice); err != nil {
+		return "", fmt.Errorf("failed to record payment: %w", err)
+	}
+	return membership.Status, nil
+}
+
+// paidInvoice finds the paid invoice of the membership's current period, or nil when the
+// period is not paid
+func (s *membershipServiceImpl) paidInvoice(ctx context.Context, membership *domain.ClientMembership) (*domain.MembershipInvoice, error) {
+	if membership.CurrentPeriodEnd == nil {
+		return nil, nil
+	}
+	invoices, err := s.membershipRepo.FindInvoices(ctx, membership.ID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve membership invoices", err)
+	}
+	for _, invoice := range invoices {
+		if invoice.Status == domain.MembershipInvoicePaid && invoice.PeriodEnd.Equal(*membership.CurrentPeriodEnd) {
+			return invoice, nil
+		}
+	}
+	return nil, nil
+}
+
+// toResponse builds the response of a membership with its plan, retrieving the plan when not
+// given, its invoices and its credit notes
+func (s *membershipServiceImpl) toResponse(ctx context.Context, membership *domain.ClientMembership, plan *domain.MembershipPlan, now time.Time) (*dto.ClientMembershipResponseDTO, error) {
+	if plan == nil {
+		var err error
+		if plan, err = s.getPlan(ctx, membership.PlanID); err != nil {
+			return nil, err
+		}
+	}
+	invoices, err := s.membershipRepo.FindInvoices(ctx, membership.ID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve membership invoices", err)
+	}
+	creditNotes, err := s.membershipRepo.FindCreditNotes(ctx, membership.ID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve membership credit notes", err)
+	}
+	return dto.ToClientMembershipResponseDTO(membership, plan, invoices, creditNotes, now), nil
+}
+
+// getPlan retrieves a membership plan, mapping a missing record to a not found error
+func (s *membershipServiceImpl) getPlan(ctx context.Context, id string) (*domain.MembershipPlan, error) {
+	plan, err := s.planRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("membership plan", "id", id)
+		}
+		return nil, NewServiceError("failed to retrieve membership plan", err)
+	}
+	return plan, nil
+}
+
+// getMembership retrieves a membership, mapping a missing record to a not found error
+func (s *membershipServiceImpl) getMembership(ctx context.Context, id string) (*domain.ClientMembership, error) {
+	membership, err := s.membershipRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("membership", "id", id)
+		}
+		return nil, NewServiceError("failed to retrieve membership", err)
+	}
+	return membership, nil
+}
+
+// getClient retrieves a client, checking that it belongs to the business when one is given
+func (s *membershipServiceImpl) getClient(ctx context.Context, businessID, clientID string) (*domain.Client, error) {
+	client, err := s.clientRepo.GetByID(ctx, clientID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("client", "id", clientID)
+		}
+		return nil, NewServiceError("failed to retrieve client", err)
+	}
+	if businessID != "" && client.BusinessID != businessID {
+		return nil, NewNotFoundError("client", "id", clientID)
+	}
+	return client, nil
+}
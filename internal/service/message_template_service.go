@@ -0,0 +1,286 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// MessageTemplateService defines the service interface for email and SMS templates
+type MessageTemplateService interface {
+	CreateTemplate(ctx context.Context, createDTO dto.CreateMessageTemplateDTO) (*dto.MessageTemplateResponseDTO, error)
+	UpdateTemplate(ctx context.Context, id string, updateDTO dto.UpdateMessageTemplateDTO) (*dto.MessageTemplateResponseDTO, error)
+	GetTemplate(ctx context.Context, id string) (*dto.MessageTemplateResponseDTO, error)
+	ListTemplates(ctx context.Context, businessID string) ([]*dto.MessageTemplateResponseDTO, error)
+	DeleteTemplate(ctx context.Context, id string) error
+	LintTemplate(ctx context.Context, content dto.MessageTemplateContentDTO) ([]domain.TemplateLintIssue, error)
+	PreviewTemplate(ctx context.Context, previewDTO dto.PreviewTemplateDTO) (*dto.TemplatePreviewDTO, error)
+}
+
+// messageTemplateServiceImpl implements the MessageTemplateService interface
+type messageTemplateServiceImpl struct {
+	templateRepo domain.MessageTemplateRepository
+	businessRepo domain.BusinessRepository
+	clientRepo   domain.BaseRepository[domain.Client]
+	calendarRepo domain.CalendarRepository
+	validator    *validator.Validate
+}
+
+// NewMessageTemplateService creates a new message template service
+func NewMessageTemplateService(
+	templateRepo domain.MessageTemplateRepository,
+	businessRepo domain.BusinessRepository,
+	clientRepo domain.BaseRepository[domain.Client],
+	calendarRepo domain.CalendarRepository,
+	validator *validator.Validate,
+) MessageTemplateService {
+	return &messageTemplateServiceImpl{
+		templateRepo: templateRepo,
+		businessRepo: businessRepo,
+		clientRepo:   clientRepo,
+		calendarRepo: calendarRepo,
+		validator:    validator,
+	}
+}
+
+// CreateTemplate creates a message template. Templates with lint errors can be saved as drafts but not activated.
+func (s *messageTemplateServiceImpl) CreateTemplate(ctx context.Context, createDTO dto.CreateMessageTemplateDTO) (*dto.MessageTemplateResponseDTO, error) {
+	if err := s.validator.Struct(createDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+
+	template := &domain.MessageTemplate{
+		BusinessID: createDTO.BusinessID,
+		Name:       strings.TrimSpace(createDTO.Name),
+		Channel:    createDTO.Channel,
+		Purpose:    createDTO.Purpose,
+		Subject:    createDTO.Subject,
+		Body:       createDTO.Body,
+		IsActive:   createDTO.IsActive,
+	}
+	if err := s.validateTemplate(template); err != nil {
+		return nil, err
+	}
+
+	template.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.templateRepo.Create(ctx, template); err != nil {
+		return nil, NewServiceError("failed to create message template", err)
+	}
+
+	return dto.ToMessageTemplateResponseDTO(template), nil
+}
+
+// UpdateTemplate updates a message template
+func (s *messageTemplateServiceImpl) UpdateTemplate(ctx context.Context, id string, updateDTO dto.UpdateMessageTemplateDTO) (*dto.MessageTemplateResponseDTO, error) {
+	if err := s.validator.Struct(updateDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+
+	template, err := s.getTemplate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if updateDTO.Name != nil {
+		template.Name = strings.TrimSpace(*updateDTO.Name)
+	}
+	updateDTO.Purpose.Apply(&template.Purpose)
+	updateDTO.Subject.Apply(&template.Subject)
+	if updateDTO.Body != nil {
+		template.Body = *updateDTO.Body
+	}
+	if updateDTO.IsActive != nil {
+		template.IsActive = *updateDTO.IsActive
+	}
+	if err := s.validateTemplate(template); err != nil {
+		return nil, err
+	}
+
+	template.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.templateRepo.Update(ctx, template); err != nil {
+		return nil, NewServiceError("failed to update message template", err)
+	}
+
+	return dto.ToMessageTemplateResponseDTO(template), nil
+}
+
+// GetTemplate retrieves a message template by ID
+func (s *messageTemplateServiceImpl) GetTemplate(ctx context.Context, id string) (*dto.MessageTemplateResponseDTO, error) {
+	template, err := s.getTemplate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToMessageTemplateResponseDTO(template), nil
+}
+
+// ListTemplates retrieves all message templates of a business
+func (s *messageTemplateServiceImpl) ListTemplates(ctx context.Context, businessID string) ([]*dto.MessageTemplateResponseDTO, error) {
+	if businessID == "" {
+		return nil, validation.NewValidationError("business_id is required")
+	}
+
+	templates, err := s.templateRepo.FindByBusinessID(ctx, businessID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve message templates", err)
+	}
+
+	return dto.ToMessageTemplateResponseDTOs(templates), nil
+}
+
+// DeleteTemplate deletes a message template
+func (s *messageTemplateServiceImpl) DeleteTemplate(ctx context.Context, id string) error {
+	if _, err := s.getTemplate(ctx, id); err != nil {
+		return err
+	}
+	if err := s.templateRepo.Delete(ctx, id); err != nil {
+		return NewServiceError("failed to delete message template", err)
+	}
+	return nil
+}
+
+// LintTemplate checks unsaved template content
+func (s *messageTemplateServiceImpl) LintTemplate(ctx context.Context, content dto.MessageTemplateContentDTO) ([]domain.TemplateLintIssue, error) {
+	if err := s.validator.Struct(content); err != nil {
+		return nil, validation.FromError(err)
+	}
+
+	template := &domain.MessageTemplate{Channel: content.Channel, Subject: content.Subject, Body: content.Body}
+	return template.Lint(), nil
+}
+
+// PreviewTemplate renders a saved template or unsaved content against a client and/or
+// appointment of the business, falling back to sample values for anything not provided
+func (s *messageTemplateServiceImpl) PreviewTemplate(ctx context.Context, previewDTO dto.PreviewTemplateDTO) (*dto.TemplatePreviewDTO, error) {
+	if err := s.validator.Struct(previewDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	if (previewDTO.TemplateID == nil) == (previewDTO.Content == nil) {
+		return nil, validation.NewValidationError("either template_id or content is required")
+	}
+
+	var template *domain.MessageTemplate
+	if previewDTO.TemplateID != nil {
+		saved, err := s.getTemplate(ctx, *previewDTO.TemplateID)
+		if err != nil {
+			return nil, err
+		}
+		if saved.BusinessID != previewDTO.BusinessID {
+			return nil, NewNotFoundError("message template", "id", *previewDTO.TemplateID)
+		}
+		template = saved
+	} else {
+		if err := s.validator.Struct(previewDTO.Content); err != nil {
+			return nil, validation.FromError(err)
+		}
+		template = &domain.MessageTemplate{
+			BusinessID: previewDTO.BusinessID,
+			Channel:    previewDTO.Content.Channel,
+			Subject:    previewDTO.Content.Subject,
+			Body:       previewDTO.Content.Body,
+		}
+	}
+
+	renderContext, err := s.loadRenderContext(ctx, previewDTO)
+	if err != nil {
+		return nil, err
+	}
+	values := renderContext.Values()
+
+	preview := &dto.TemplatePreviewDTO{
+		Body:       domain.RenderTemplateText(template.Body, values),
+		LintIssues: template.Lint(),
+	}
+	if template.Channel == domain.MessageChannelEmail && template.Subject != nil {
+		subject := domain.RenderTemplateText(*template.Subject, values)
+		preview.Subject = &subject
+	}
+	if template.Channel == domain.MessageChannelSMS {
+		info := domain.AnalyzeSMS(preview.Body)
+		preview.SMS = &info
+	}
+
+	return preview, nil
+}
+
+// loadRenderContext loads the business, client and appointment to render a preview against
+func (s *messageTemplateServiceImpl) loadRenderContext(ctx context.Context, previewDTO dto.PreviewTemplateDTO) (domain.TemplateRenderContext, error) {
+	renderContext := domain.TemplateRenderContext{}
+
+	business, err := s.businessRepo.GetByID(ctx, previewDTO.BusinessID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return renderContext, NewNotFoundError("business", "id", previewDTO.BusinessID)
+		}
+		return renderContext, NewServiceError("failed to retrieve business", err)
+	}
+	renderContext.Business = business
+
+	clientID := previewDTO.ClientID
+	if previewDTO.AppointmentID != nil {
+		appointment, err := s.calendarRepo.FindByAppointmentID(ctx, *previewDTO.AppointmentID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return renderContext, NewServiceError("failed to retrieve appointment", err)
+		}
+		if appointment == nil || appointment.BusinessID != business.ID {
+			return renderContext, NewNotFoundError("appointment", "id", *previewDTO.AppointmentID)
+		}
+		renderContext.Appointment = appointment
+		if clientID == nil {
+			clientID = &appointment.ClientID
+		}
+	}
+
+	if clientID != nil {
+		client, err := s.clientRepo.GetByID(ctx, *clientID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return renderContext, NewServiceError("failed to retrieve client", err)
+		}
+		if client == nil || client.BusinessID != business.ID {
+			return renderContext, NewNotFoundError("client", "id", *clientID)
+		}
+		renderContext.Client = client
+	}
+
+	return renderContext, nil
+}
+
+// validateTemplate validates a template and rejects activating one with lint errors
+func (s *messageTemplateServiceImpl) validateTemplate(template *domain.MessageTemplate) error {
+	if err := template.Validate(); err != nil {
+		return toValidationError(err)
+	}
+	if !template.IsActive {
+		return nil
+	}
+
+	issues := template.Lint()
+	if !domain.HasLintErrors(issues) {
+		return nil
+	}
+
+	errs := validation.ValidationErrors{}
+	for _, issue := range issues {
+		if issue.Severity == domain.TemplateLintError {
+			errs.AddField(issue.Code, issue.Message)
+		}
+	}
+	return errs
+}
+
+// getTemplate retrieves a message template, mapping missing records to a not found error
+func (s *messageTemplateServiceImpl) getTemplate(ctx context.Context, id string) (*domain.MessageTemplate, error) {
+	template, err := s.templateRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("message template", "id", id)
+		}
+		return nil, NewServiceError("failed to retrieve message template", err)
+	}
+	return template, nil
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/rs/zerolog/log"
+)
+
+// MoneyMigrationService defines the service interface for moving monetary amounts from decimal
+// columns to integer minor units
+type MoneyMigrationService interface {
+	Backfill(ctx context.Context, batchSize int) (*domain.MoneyMigrationReport, error)
+	Verify(ctx context.Context) (*domain.MoneyMigrationReport, error)
+}
+
+// moneyMigrationServiceImpl implements the MoneyMigrationService interface
+type moneyMigrationServiceImpl struct {
+	repo domain.MoneyMigrationRepository
+}
+
+// NewMoneyMigrationService creates a new money migration service
+func NewMoneyMigrationService(repo domain.MoneyMigrationRepository) MoneyMigrationService {
+	return &moneyMigrationServiceImpl{repo: repo}
+}
+
+// Backfill converts the amounts written before the minor units columns existed, a batch at a
+// time so the API keeps running, then verifies every column. It can be stopped and run again.
+func (s *moneyMigrationServiceImpl) Backfill(ctx context.Context, batchSize int) (*domain.MoneyMigrationReport, error) {
+	for _, column := range domain.MoneyColumns {
+		migrated, err := s.repo.HasMinorUnits(ctx, column)
+		if err != nil {
+			return nil, NewServiceError("failed to look up minor units", err)
+		}
+		if !migrated {
+			continue
+		}
+
+		var converted int64
+		for {
+			count, err := s.repo.Backfill(ctx, column, batchSize)
+			if err != nil {
+				return nil, NewServiceError("failed to backfill "+column.String(), err)
+			}
+			converted += count
+			if count == 0 {
+				break
+			}
+		}
+		log.Info().Str("column", column.String()).Int64("converted", converted).Msg("Backfilled minor units")
+	}
+	return s.Verify(ctx)
+}
+
+// Verify reports the rows of every monetary column whose minor units are missing or disagree
+// with their amount. Columns the database does not have are left out.
+func (s *moneyMigrationServiceImpl) Verify(ctx context.Context) (*domain.MoneyMigrationReport, error) {
+	report := &domain.MoneyMigrationReport{CheckedAt: time.Now()}
+	for _, column := range domain.MoneyColumns {
+		migrated, err := s.repo.HasMinorUnits(ctx, column)
+		if err != nil {
+			return nil, NewServiceError("failed to look up minor units", err)
+		}
+		if !migrated {
+			log.Warn().Str("column", column.String()).Msg("Monetary column has no minor units")
+			continue
+		}
+		columnReport, err := s.repo.Verify(ctx, column)
+		if err != nil {
+			return nil, NewServiceError("failed to verify "+column.String(), err)
+		}
+		report.Columns = append(report.Columns, columnReport)
+	}
+	return report, nil
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+)
+
+// defaultOutboundRequestLimit is the number of outbound requests returned when no limit is given
+const defaultOutboundRequestLimit = 50
+
+// OutboundRequestService defines the service interface for the audit of calls made to
+// third-party providers
+type OutboundRequestService interface {
+	ListOutboundRequests(ctx context.Context, filterDTO dto.OutboundRequestFilterDTO) ([]*dto.OutboundRequestDTO, error)
+	PruneOutboundRequests(ctx context.Context, now time.Time) (int64, error)
+}
+
+// outboundRequestServiceImpl implements the OutboundRequestService interface
+type outboundRequestServiceImpl struct {
+	outboundRequestRepo domain.OutboundRequestRepository
+	validator           *validator.Validate
+}
+
+// NewOutboundRequestService creates a new outbound request service
+func NewOutboundRequestService(outboundRequestRepo domain.OutboundRequestRepository, validator *validator.Validate) OutboundRequestService {
+	return &outboundRequestServiceImpl{
+		outboundRequestRepo: outboundRequestRepo,
+		validator:           validator,
+	}
+}
+
+// ListOutboundRequests lists the recorded calls to providers, most recent first. The calls
+// concern every business, so only platform operators may see them.
+func (s *outboundRequestServiceImpl) ListOutboundRequests(ctx context.Context, filterDTO dto.OutboundRequestFilterDTO) ([]*dto.OutboundRequestDTO, error) {
+	if !IsPlatformAdmin(ctx) {
+		return nil, NewForbiddenError("view outbound requests")
+	}
+	if err := s.validator.Struct(filterDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	if filterDTO.StartDate != nil && filterDTO.EndDate != nil && !filterDTO.EndDate.After(*filterDTO.StartDate) {
+		return nil, validation.NewValidationError("end_date must be after start_date")
+	}
+
+	filter := domain.OutboundRequestFilter{
+		Provider:   filterDTO.Provider,
+		FailedOnly: filterDTO.FailedOnly,
+		StartDate:  filterDTO.StartDate,
+		EndDate:    filterDTO.EndDate,
+		Limit:      filterDTO.Limit,
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultOutboundRequestLimit
+	}
+	requests, err := s.outboundRequestRepo.FindByFilter(ctx, filter)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve outbound requests", err)
+	}
+
+	return dto.ToOutboundRequestDTOs(requests), nil
+}
+
+// PruneOutboundRequests deletes the outbound requests older than their retention period
+func (s *outboundRequestServiceImpl) PruneOutboundRequests(ctx context.Context, now time.Time) (int64, error) {
+	removed, err := s.outboundRequestRepo.DeleteBefore(ctx, now.Add(-domain.OutboundRequestRetention))
+	if err != nil {
+		return 0, NewServiceError("failed to prune outbound requests", err)
+	}
+	return removed, nil
+}
@@ -0,0 +1,610 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/resilience"
+	"github.com/assimoes/beautix/internal/infrastructure/stripe"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// PaymentService defines the service interface for card and gift card payments of appointments
+type PaymentService interface {
+	ChargeDeposit(ctx context.Context, chargeDTO dto.ChargeDepositDTO) (*dto.PaymentResponseDTO, error)
+	CaptureCompletionPayment(ctx context.Context, captureDTO dto.CaptureCompletionPaymentDTO) (*dto.PaymentResponseDTO, error)
+	RedeemGiftCard(ctx context.Context, redeemDTO dto.RedeemGiftCardDTO) (*dto.PaymentResponseDTO, error)
+	RefundPayment(ctx context.Context, refundDTO dto.RefundPaymentDTO) (*dto.PaymentResponseDTO, error)
+	RefreshPayment(ctx context.Context, id string) (*dto.PaymentResponseDTO, error)
+	ListAppointmentPayments(ctx context.Context, appointmentID string) ([]*dto.PaymentResponseDTO, error)
+	SetServiceDeposit(ctx context.Context, depositDTO dto.ServiceDepositDTO) (*dto.ServiceDepositDTO, error)
+}
+
+// paymentServiceImpl implements the PaymentService interface
+type paymentServiceImpl struct {
+	paymentRepo     domain.PaymentRepository
+	appointmentRepo domain.BaseRepository[domain.Appointment]
+	completionRepo  domain.ServiceCompletionRepository
+	businessRepo    domain.BusinessRepository
+	giftCardRepo    domain.GiftCardRepository
+	settingsRepo    domain.BusinessSettingsRepository
+	clientRepo      domain.BaseRepository[domain.Client]
+	staffRepo       domain.StaffRepository
+	unitOfWork      domain.UnitOfWork
+	eventService    EventService
+	gateway         stripe.Gateway
+	validator       *validator.Validate
+}
+
+// NewPaymentService creates a new payment service
+func NewPaymentService(
+	paymentRepo domain.PaymentRepository,
+	appointmentRepo domain.BaseRepository[domain.Appointment],
+	completionRepo domain.ServiceCompletionRepository,
+	businessRepo domain.BusinessRepository,
+	giftCardRepo domain.GiftCardRepository,
+	settingsRepo domain.BusinessSettingsRepository,
+	clientRepo domain.BaseRepository[domain.Client],
+	staffRepo domain.StaffRepository,
+	unitOfWork domain.UnitOfWork,
+	eventService EventService,
+	gateway stripe.Gateway,
+	validator *validator.Validate,
+) PaymentService {
+	return &paymentServiceImpl{
+		paymentRepo:     paymentRepo,
+		appointmentRepo: appointmentRepo,
+		completionRepo:  completionRepo,
+		businessRepo:    businessRepo,
+		giftCardRepo:    giftCardRepo,
+		settingsRepo:    settingsRepo,
+		clientRepo:      clientRepo,
+		staffRepo:       staffRepo,
+		unitOfWork:      unitOfWork,
+		eventService:    eventService,
+		gateway:         gateway,
+		validator:       validator,
+	}
+}
+
+// ChargeDeposit charges the deposit of a booked appointment to a card. Without an amount the
+// deposits of the booked services are charged, or at least the no-show fee when the client was
+// flagged for no-shows.
+func (s *paymentServiceImpl) ChargeDeposit(ctx context.Context, chargeDTO dto.ChargeDepositDTO) (*dto.PaymentResponseDTO, error) {
+	if err := s.validator.Struct(chargeDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+
+	appointment, err := s.getAppointment(ctx, chargeDTO.AppointmentID)
+	if err != nil {
+		return nil, err
+	}
+	ctx, err = s.authorize(ctx, appointment.BusinessID, domain.PermissionPaymentsTake, "charge deposits")
+	if err != nil {
+		return nil, err
+	}
+	if !appointment.CanBeCancelled() {
+		return nil, validation.NewValidationError("deposits can only be charged for scheduled or confirmed appointments")
+	}
+
+	payments, err := s.paymentRepo.FindByAppointmentID(ctx, appointment.ID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve payments", err)
+	}
+	for _, payment := range payments {
+		if payment.Kind == domain.PaymentKindDeposit && (payment.IsPaid() || payment.IsOpen()) {
+			return nil, validation.NewValidationError("a deposit has already been charged for the appointment")
+		}
+	}
+
+	amount := chargeDTO.Amount
+	if amount == nil {
+		settings, err := s.settingsRepo.GetByBusinessID(ctx, appointment.BusinessID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewServiceError("failed to retrieve business settings", err)
+		}
+		due, err := depositDue(ctx, s.paymentRepo, s.clientRepo, settings, appointment)
+		if err != nil {
+			return nil, NewServiceError("failed to work out the deposit", err)
+		}
+		if !due.IsPositive() {
+			return nil, validation.NewValidationError("none of the booked services requires a deposit; give an amount")
+		}
+		amount = &due
+	}
+
+	payment := &domain.Payment{
+		BusinessID:    appointment.BusinessID,
+		AppointmentID: appointment.ID,
+		Kind:          domain.PaymentKindDeposit,
+		Amount:        *amount,
+	}
+	return s.charge(ctx, appointment, payment, chargeDTO.PaymentMethodID)
+}
+
+// CaptureCompletionPayment takes payment for a completed appointment: the charged price less
+// what the deposits already covered. The appointment's completion is recorded first if it was
+// not already, so the service is on record even when the card is declined.
+func (s *paymentServiceImpl) CaptureCompletionPayment(ctx context.Context, captureDTO dto.CaptureCompletionPaymentDTO) (*dto.PaymentResponseDTO, error) {
+	if err := s.validator.Struct(captureDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+
+	appointment, err := s.getAppointment(ctx, captureDTO.AppointmentID)
+	if err != nil {
+		return nil, err
+	}
+	ctx, err = s.authorize(ctx, appointment.BusinessID, domain.PermissionPaymentsTake, "take payments")
+	if err != nil {
+		return nil, err
+	}
+	completion, err := s.getOrRecordCompletion(ctx, appointment, captureDTO.PriceCharged, captureDTO.ActualDuration, domain.PaymentMethodCard)
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err := s.paymentRepo.FindByAppointmentID(ctx, appointment.ID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve payments", err)
+	}
+	for _, payment := range payments {
+		if payment.IsOpen() {
+			return nil, validation.NewValidationError("a payment for the appointment is still in progress")
+		}
+	}
+	balance := domain.BalanceDue(completion.PriceCharged, payments)
+	if !balance.IsPositive() {
+		return nil, validation.NewValidationError("the appointment is already paid in full")
+	}
+
+	payment := &domain.Payment{
+		BusinessID:          appointment.BusinessID,
+		AppointmentID:       appointment.ID,
+		ServiceCompletionID: &completion.ID,
+		Kind:                domain.PaymentKindBalance,
+		Amount:              balance,
+	}
+	return s.charge(ctx, appointment, payment, captureDTO.PaymentMethodID)
+}
+
+// RedeemGiftCard pays for a completed appointment, in full or in part, from the balance of a
+// gift card of the business. The rest of the bill can then be paid by card or with another
+// gift card. As with card payments, the completion is recorded first if it was not already.
+func (s *paymentServiceImpl) RedeemGiftCard(ctx context.Context, redeemDTO dto.RedeemGiftCardDTO) (*dto.PaymentResponseDTO, error) {
+	if err := s.validator.Struct(redeemDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+
+	appointment, err := s.getAppointment(ctx, redeemDTO.AppointmentID)
+	if err != nil {
+		return nil, err
+	}
+	ctx, err = s.authorize(ctx, appointment.BusinessID, domain.PermissionPaymentsTake, "take payments")
+	if err != nil {
+		return nil, err
+	}
+	card, err := findGiftCard(ctx, s.giftCardRepo, appointment.BusinessID, redeemDTO.Code)
+	if err != nil {
+		return nil, err
+	}
+	business, err := s.businessRepo.GetByID(ctx, appointment.BusinessID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve business", err)
+	}
+	if card.Currency != business.Currency {
+		return nil, validation.NewValidationError("the gift card is in " + card.Currency + " but the business charges in " + business.Currency)
+	}
+	completion, err := s.getOrRecordCompletion(ctx, appointment, redeemDTO.PriceCharged, redeemDTO.ActualDuration, domain.PaymentMethodGiftCard)
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err := s.paymentRepo.FindByAppointmentID(ctx, appointment.ID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve payments", err)
+	}
+	for _, payment := range payments {
+		if payment.IsOpen() {
+			return nil, validation.NewValidationError("a payment for the appointment is still in progress")
+		}
+	}
+	balance := domain.BalanceDue(completion.PriceCharged, payments)
+	if !balance.IsPositive() {
+		return nil, validation.NewValidationError("the appointment is already paid in full")
+	}
+	now := time.Now()
+	amount, err := card.RedemptionAmount(balance, redeemDTO.Amount, now)
+	if err != nil {
+		return nil, toValidationError(err)
+	}
+
+	payment := &domain.Payment{
+		BusinessID:          appointment.BusinessID,
+		AppointmentID:       appointment.ID,
+		ServiceCompletionID: &completion.ID,
+		Kind:                domain.PaymentKindBalance,
+		Status:              domain.PaymentStatusSucceeded,
+		Amount:              amount,
+		Currency:            card.Currency,
+		PaidAt:              &now,
+	}
+	if err := payment.Validate(); err != nil {
+		return nil, toValidationError(err)
+	}
+	payment.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.giftCardRepo.Redeem(ctx, card.ID, payment); err != nil {
+		if errors.Is(err, domain.ErrGiftCardBalanceChanged) {
+			return nil, validation.FromError(err)
+		}
+		return nil, NewServiceError("failed to redeem gift card", err)
+	}
+	s.syncAppointmentPaymentStatus(ctx, appointment.ID)
+	return dto.ToPaymentResponseDTO(payment), nil
+}
+
+// RefundPayment refunds all or part of a payment to the card it was taken from, or back onto
+// the gift card it was redeemed from
+func (s *paymentServiceImpl) RefundPayment(ctx context.Context, refundDTO dto.RefundPaymentDTO) (*dto.PaymentResponseDTO, error) {
+	if err := s.validator.Struct(refundDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+
+	payment, err := s.getPayment(ctx, refundDTO.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+	ctx, err = s.authorize(ctx, payment.BusinessID, domain.PermissionPaymentsRefund, "refund payments")
+	if err != nil {
+		return nil, err
+	}
+
+	// The payment stays locked from reading what is left to refund until the refund is
+	// recorded, so concurrent refunds take turns and cannot both refund the same balance
+	err = s.unitOfWork.Do(ctx, func(ctx context.Context) error {
+		payment, err = s.paymentRepo.GetByIDForUpdate(ctx, refundDTO.PaymentID)
+		if err != nil {
+			return NewServiceError("failed to retrieve payment", err)
+		}
+		amount := payment.RefundableAmount()
+		if refundDTO.Amount != nil {
+			amount = *refundDTO.Amount
+		}
+		if err := payment.RecordRefund(amount); err != nil {
+			return toValidationError(err)
+		}
+
+		payment.SetAuditFields(GetUserIDFromContext(ctx))
+		if payment.GiftCardID != nil {
+			if err := s.giftCardRepo.Refund(ctx, payment, amount); err != nil {
+				return NewServiceError("failed to refund gift card", err)
+			}
+			return nil
+		}
+		if err := s.paymentRepo.Update(ctx, payment); err != nil {
+			return NewServiceError("failed to record refund", err)
+		}
+
+		// Recording the refund moved the payment to a new version, which keys the refund: a
+		// retried request reaches Stripe with the same key until a refund is recorded, while
+		// the next refund of the payment gets a key of its own. A failed refund rolls the
+		// record back.
+		_, err := s.gateway.Refund(ctx, stripe.RefundParams{
+			PaymentIntentID: *payment.PaymentIntentID,
+			Amount:          domain.ToMinorUnits(amount, payment.Currency),
+			IdempotencyKey:  fmt.Sprintf("%s-refund-%d", payment.ID, payment.Version),
+		})
+		if err != nil {
+			return toPaymentError("failed to refund payment", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.syncAppointmentPaymentStatus(ctx, payment.AppointmentID)
+	return dto.ToPaymentResponseDTO(payment), nil
+}
+
+// RefreshPayment updates a payment that was waiting on the client or the card network with its
+// current state at Stripe
+func (s *paymentServiceImpl) RefreshPayment(ctx context.Context, id string) (*dto.PaymentResponseDTO, error) {
+	payment, err := s.getPayment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	ctx, err = s.authorize(ctx, payment.BusinessID, domain.PermissionPaymentsView, "view payments")
+	if err != nil {
+		return nil, err
+	}
+	if !payment.IsOpen() || payment.PaymentIntentID == nil {
+		return dto.ToPaymentResponseDTO(payment), nil
+	}
+
+	intent, err := s.gateway.GetPaymentIntent(ctx, *payment.PaymentIntentID)
+	if err != nil {
+		return nil, toPaymentError("failed to retrieve payment from stripe", err)
+	}
+	applyPaymentIntent(payment, intent)
+
+	payment.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.paymentRepo.Update(ctx, payment); err != nil {
+		return nil, NewServiceError("failed to update payment", err)
+	}
+	s.syncAppointmentPaymentStatus(ctx, payment.AppointmentID)
+	return toPaymentResponse(payment, intent), nil
+}
+
+// ListAppointmentPayments lists the payments of an appointment, oldest first
+func (s *paymentServiceImpl) ListAppointmentPayments(ctx context.Context, appointmentID string) ([]*dto.PaymentResponseDTO, error) {
+	appointment, err := s.getAppointment(ctx, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	ctx, err = s.authorize(ctx, appointment.BusinessID, domain.PermissionPaymentsView, "view payments")
+	if err != nil {
+		return nil, err
+	}
+
+	payments, err := s.paymentRepo.FindByAppointmentID(ctx, appointmentID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve payments", err)
+	}
+	return dto.ToPaymentResponseDTOs(payments), nil
+}
+
+// SetServiceDeposit sets the deposit charged at booking for a service
+func (s *paymentServiceImpl) SetServiceDeposit(ctx context.Context, depositDTO dto.ServiceDepositDTO) (*dto.ServiceDepositDTO, error) {
+	if err := s.validator.Struct(depositDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	if depositDTO.DepositAmount != nil && depositDTO.DepositAmount.IsNegative() {
+		return nil, validation.NewValidationError("the deposit cannot be negative")
+	}
+	if depositDTO.RequiresDeposit && (depositDTO.DepositAmount == nil || !depositDTO.DepositAmount.IsPositive()) {
+		return nil, validation.NewValidationError("a deposit amount is required when the service requires a deposit")
+	}
+
+	if err := s.paymentRepo.SetServiceDeposit(ctx, depositDTO.ServiceID, depositDTO.RequiresDeposit, depositDTO.DepositAmount); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("service", "id", depositDTO.ServiceID)
+		}
+		return nil, NewServiceError("failed to set service deposit", err)
+	}
+	return &depositDTO, nil
+}
+
+// charge records a payment and charges it to the card. The payment ID is the idempotency key,
+// so Stripe never takes the same payment twice.
+func (s *paymentServiceImpl) charge(ctx context.Context, appointment *domain.Appointment, payment *domain.Payment, paymentMethodID string) (*dto.PaymentResponseDTO, error) {
+	business, err := s.businessRepo.GetByID(ctx, appointment.BusinessID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve business", err)
+	}
+	payment.Currency = business.Currency
+	payment.Status = domain.PaymentStatusPending
+	if err := payment.Validate(); err != nil {
+		return nil, toValidationError(err)
+	}
+
+	payment.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.paymentRepo.Create(ctx, payment); err != nil {
+		if errors.Is(err, domain.ErrDepositAlreadyCharged) {
+			return nil, validation.FromError(err)
+		}
+		return nil, NewServiceError("failed to create payment", err)
+	}
+
+	intent, chargeErr := s.gateway.Charge(ctx, stripe.ChargeParams{
+		Amount:          domain.ToMinorUnits(payment.Amount, payment.Currency),
+		Currency:        payment.Currency,
+		PaymentMethodID: paymentMethodID,
+		Description:     fmt.Sprintf("%s %s for appointment on %s", business.GetDisplayName(), payment.Kind, appointment.StartTime.Format("2006-01-02")),
+		Metadata: map[string]string{
+			"payment_id":     payment.ID,
+			"appointment_id": appointment.ID,
+			"business_id":    appointment.BusinessID,
+		},
+		IdempotencyKey: payment.ID,
+	})
+	if chargeErr != nil {
+		payment.MarkFailed(chargeErr.Error())
+		var stripeErr *stripe.Error
+		if errors.As(chargeErr, &stripeErr) {
+			payment.FailureReason = &stripeErr.Message
+		}
+	} else {
+		applyPaymentIntent(payment, intent)
+	}
+
+	if err := s.paymentRepo.Update(ctx, payment); err != nil {
+		return nil, NewServiceError("failed to update payment", err)
+	}
+	if chargeErr != nil {
+		return nil, toPaymentError("failed to charge card", chargeErr)
+	}
+	s.syncAppointmentPaymentStatus(ctx, appointment.ID)
+	return toPaymentResponse(payment, intent), nil
+}
+
+// getOrRecordCompletion finds the completion of an appointment, or records one paid with the
+// given payment method at the given price and marks the appointment completed
+func (s *paymentServiceImpl) getOrRecordCompletion(ctx context.Context, appointment *domain.Appointment, priceCharged *decimal.Decimal, actualDuration *int, paymentMethod string) (*domain.ServiceCompletion, error) {
+	completion, err := s.completionRepo.FindByAppointmentID(ctx, appointment.ID)
+	if err == nil {
+		return completion, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, NewServiceError("failed to retrieve service completion", err)
+	}
+
+	if !appointment.CanBeCompleted() {
+		return nil, validation.NewValidationError("only confirmed or in-progress appointments can be completed")
+	}
+	if priceCharged == nil {
+		return nil, validation.NewValidationError("price_charged is required to complete the appointment")
+	}
+	now := time.Now()
+	completion = &domain.ServiceCompletion{
+		AppointmentID:     appointment.ID,
+		PriceCharged:      *priceCharged,
+		PaymentMethod:     paymentMethod,
+		ProviderConfirmed: true,
+		CompletionDate:    &now,
+		ActualDuration:    actualDuration,
+	}
+	if err := completion.Validate(); err != nil {
+		return nil, validation.NewValidationError("invalid price_charged or actual_duration")
+	}
+
+	completion.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.paymentRepo.RecordCompletion(ctx, completion); err != nil {
+		return nil, NewServiceError("failed to complete appointment", err)
+	}
+
+	appointment.MarkCompleted()
+	s.publish(ctx, appointment, completion)
+	return completion, nil
+}
+
+// syncAppointmentPaymentStatus updates the payment status of an appointment from its payments.
+// The payments are already recorded, so a failure here is logged rather than returned.
+func (s *paymentServiceImpl) syncAppointmentPaymentStatus(ctx context.Context, appointmentID string) {
+	err := func() error {
+		payments, err := s.paymentRepo.FindByAppointmentID(ctx, appointmentID)
+		if err != nil {
+			return err
+		}
+		price, err := s.appointmentPrice(ctx, appointmentID)
+		if err != nil {
+			return err
+		}
+		return s.paymentRepo.SetAppointmentPaymentStatus(ctx, appointmentID, domain.SummarizePayments(price, payments))
+	}()
+	if err != nil {
+		log.Error().Err(err).Str("appointment_id", appointmentID).Msg("Failed to update appointment payment status")
+	}
+}
+
+// appointmentPrice returns the charged price of a completed appointment, or else its estimate
+func (s *paymentServiceImpl) appointmentPrice(ctx context.Context, appointmentID string) (decimal.Decimal, error) {
+	completion, err := s.completionRepo.FindByAppointmentID(ctx, appointmentID)
+	if err == nil {
+		return completion.PriceCharged, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return decimal.Zero, err
+	}
+
+	appointment, err := s.appointmentRepo.GetByID(ctx, appointmentID)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if appointment.EstimatedPrice != nil {
+		return *appointment.EstimatedPrice, nil
+	}
+	return decimal.Zero, nil
+}
+
+// getAppointment retrieves an appointment, reporting a missing one as not found
+func (s *paymentServiceImpl) getAppointment(ctx context.Context, id string) (*domain.Appointment, error) {
+	appointment, err := s.appointmentRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("appointment", "id", id)
+		}
+		return nil, NewServiceError("failed to retrieve appointment", err)
+	}
+	return appointment, nil
+}
+
+// authorize requires the user to be active staff of the business with the permission, and
+// returns a context acting for the business
+func (s *paymentServiceImpl) authorize(ctx context.Context, businessID string, permission domain.Permission, action string) (context.Context, error) {
+	userID := GetUserIDFromContext(ctx)
+	if userID == nil {
+		return ctx, NewForbiddenError(action)
+	}
+	staff, err := s.staffRepo.FindByBusinessAndUser(ctx, businessID, *userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ctx, NewForbiddenError(action)
+		}
+		return ctx, NewServiceError("failed to retrieve staff member", err)
+	}
+	if !staff.IsActive || !staff.Can(permission) {
+		return ctx, NewForbiddenError(action)
+	}
+	return actFor(ctx, businessID, action)
+}
+
+// getPayment retrieves a payment, reporting a missing one as not found
+func (s *paymentServiceImpl) getPayment(ctx context.Context, id string) (*domain.Payment, error) {
+	payment, err := s.paymentRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("payment", "id", id)
+		}
+		return nil, NewServiceError("failed to retrieve payment", err)
+	}
+	return payment, nil
+}
+
+// publish records that the appointment was completed so that read models and the loyalty
+// engine pick up the change
+func (s *paymentServiceImpl) publish(ctx context.Context, appointment *domain.Appointment, completion *domain.ServiceCompletion) {
+	event, err := domain.NewDomainEvent(domain.AggregateAppointment, appointment.ID, domain.EventAppointmentCompleted, &appointment.BusinessID,
+		map[string]any{"staff_id": appointment.StaffID, "client_id": appointment.ClientID, "start_time": appointment.StartTime,
+			"end_time": appointment.EndTime, "completion_id": completion.ID, "price_charged": completion.PriceCharged})
+	if err == nil {
+		err = s.eventService.Publish(ctx, event)
+	}
+	if err != nil {
+		log.Error().Err(err).Str("appointment_id", appointment.ID).Msg("Failed to publish appointment event")
+	}
+}
+
+// applyPaymentIntent copies the state of a Stripe payment intent onto a payment
+func applyPaymentIntent(payment *domain.Payment, intent *stripe.PaymentIntent) {
+	payment.PaymentIntentID = &intent.ID
+	switch payment.Status = intent.PaymentStatus(); payment.Status {
+	case domain.PaymentStatusSucceeded:
+		now := time.Now()
+		payment.PaidAt = &now
+		payment.FailureReason = nil
+	case domain.PaymentStatusFailed:
+		payment.MarkFailed(intent.FailureMessage())
+	}
+}
+
+// toPaymentResponse converts a payment, handing out the client secret only while the client
+// has to authenticate the card
+func toPaymentResponse(payment *domain.Payment, intent *stripe.PaymentIntent) *dto.PaymentResponseDTO {
+	response := dto.ToPaymentResponseDTO(payment)
+	if payment.Status == domain.PaymentStatusRequiresAction && intent != nil && intent.ClientSecret != "" {
+		response.ClientSecret = &intent.ClientSecret
+	}
+	return response
+}
+
+// toPaymentError turns declined cards and a missing Stripe configuration into validation errors
+func toPaymentError(message string, err error) error {
+	if errors.Is(err, stripe.ErrNotConfigured) {
+		return validation.NewValidationError("card payments are not configured")
+	}
+	if errors.Is(err, resilience.ErrCircuitOpen) {
+		return validation.NewValidationError("card payments are temporarily unavailable, please try again later")
+	}
+	var stripeErr *stripe.Error
+	if errors.As(err, &stripeErr) && stripeErr.IsCardError() {
+		return validation.NewValidationError("the card was declined: " + stripeErr.Message)
+	}
+	return NewServiceError(message, err)
+}
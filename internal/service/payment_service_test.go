@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/stripe"
+	"github.com/assimoes/beautix/internal/repository/memory"
+)
+
+// fakeGateway records the refunds asked of Stripe, failing them while refundErr is set
+type fakeGateway struct {
+	stripe.Gateway
+	refunds   []stripe.RefundParams
+	refundErr error
+}
+
+func (g *fakeGateway) Refund(ctx context.Context, params stripe.RefundParams) (*stripe.Refund, error) {
+	g.refunds = append(g.refunds, params)
+	if g.refundErr != nil {
+		return nil, g.refundErr
+	}
+	return &stripe.Refund{ID: "re_1", Status: "succeeded", Amount: params.Amount}, nil
+}
+
+func TestPaymentService_RefundPayment(t *testing.T) {
+	db := memory.NewDB()
+	business := &domain.Business{UserID: "owner-1", Name: "Salon", Currency: "EUR"}
+	require.NoError(t, memory.Insert(db, business))
+	owner := &domain.Staff{BusinessID: business.ID, UserID: "user-owner", Role: domain.BusinessRoleOwner, IsActive: true}
+	stylist := &domain.Staff{BusinessID: business.ID, UserID: "user-stylist", Role: domain.BusinessRoleEmployee, IsActive: true}
+	require.NoError(t, memory.Insert(db, owner, stylist))
+	start := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	appointment := &domain.Appointment{BusinessID: business.ID, ClientID: "client-1", StaffID: stylist.ID,
+		StartTime: start, EndTime: start.Add(time.Hour), Status: domain.AppointmentStatusCompleted}
+	require.NoError(t, memory.Insert(db, appointment))
+	intentID := "pi_1"
+	payment := &domain.Payment{BusinessID: business.ID, AppointmentID: appointment.ID, Kind: domain.PaymentKindBalance,
+		Status: domain.PaymentStatusSucceeded, Amount: decimal.NewFromInt(60), Currency: "EUR", PaymentIntentID: &intentID}
+	require.NoError(t, memory.Insert(db, payment))
+
+	gateway := &fakeGateway{}
+	payments := NewPaymentService(memory.NewPaymentRepository(db), memory.NewAppointmentRepository(db),
+		memory.NewServiceCompletionRepository(db), memory.NewBusinessRepository(db), memory.NewGiftCardRepository(db),
+		memory.NewBusinessSettingsRepository(db), memory.NewBaseRepository[domain.Client](db, memory.WithTenantScope()),
+		memory.NewStaffRepository(db), memory.NewUnitOfWork(db), nil, gateway, validator.New())
+	as := func(staff *domain.Staff) context.Context {
+		return SetUserContext(context.Background(), staff.UserID, string(staff.Role), &business.ID)
+	}
+	ten := decimal.NewFromInt(10)
+	refund := dto.RefundPaymentDTO{PaymentID: payment.ID, Amount: &ten}
+
+	var forbidden ForbiddenError
+	_, err := payments.RefundPayment(as(stylist), refund)
+	assert.ErrorAs(t, err, &forbidden, "employees cannot refund payments")
+	_, err = payments.RefundPayment(SetUserContext(context.Background(), "user-outsider", "employee", &business.ID), refund)
+	assert.ErrorAs(t, err, &forbidden, "only the business's staff can refund its payments")
+	assert.Empty(t, gateway.refunds)
+
+	gateway.refundErr = errors.New("connection reset")
+	_, err = payments.RefundPayment(as(owner), refund)
+	require.Error(t, err)
+	stored, err := memory.NewPaymentRepository(db).GetByID(as(owner), payment.ID)
+	require.NoError(t, err)
+	assert.True(t, stored.RefundedAmount.IsZero(), "a refund Stripe did not take is rolled back")
+
+	gateway.refundErr = nil
+	refunded, err := payments.RefundPayment(as(owner), refund)
+	require.NoError(t, err)
+	assert.True(t, ten.Equal(refunded.RefundedAmount))
+	require.Len(t, gateway.refunds, 2)
+	assert.Equal(t, gateway.refunds[0].IdempotencyKey, gateway.refunds[1].IdempotencyKey, "the retry reuses the key of the failed refund")
+
+	_, err = payments.RefundPayment(as(owner), refund)
+	require.NoError(t, err)
+	require.Len(t, gateway.refunds, 3)
+	assert.NotEqual(t, gateway.refunds[1].IdempotencyKey, gateway.refunds[2].IdempotencyKey, "a second refund of the same amount is another refund")
+}
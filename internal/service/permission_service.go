@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// PermissionService defines the service interface for what members of staff may do in a business
+type PermissionService interface {
+	GetPermissionCatalog(ctx context.Context) *dto.PermissionCatalogDTO
+	GetStaffPermissions(ctx context.Context, staffID string) (*dto.StaffPermissionsDTO, error)
+	UpdateStaffPermissions(ctx context.Context, updateDTO dto.UpdateStaffPermissionsDTO) (*dto.StaffPermissionsDTO, error)
+}
+
+// permissionServiceImpl implements the PermissionService interface
+type permissionServiceImpl struct {
+	staffRepo domain.StaffRepository
+	validator *validator.Validate
+}
+
+// NewPermissionService creates a new permission service
+func NewPermissionService(staffRepo domain.StaffRepository, validator *validator.Validate) PermissionService {
+	return &permissionServiceImpl{
+		staffRepo: staffRepo,
+		validator: validator,
+	}
+}
+
+// GetPermissionCatalog lists every permission staff may be given, and what each role comes with
+func (s *permissionServiceImpl) GetPermissionCatalog(ctx context.Context) *dto.PermissionCatalogDTO {
+	return dto.ToPermissionCatalogDTO()
+}
+
+// GetStaffPermissions returns what a member of staff may do. Staff may see their own
+// permissions; those of others are for staff who may change them.
+func (s *permissionServiceImpl) GetStaffPermissions(ctx context.Context, staffID string) (*dto.StaffPermissionsDTO, error) {
+	staff, err := s.getStaff(ctx, staffID)
+	if err != nil {
+		return nil, err
+	}
+	caller, err := s.caller(ctx, staff.BusinessID, "view staff permissions")
+	if err != nil {
+		return nil, err
+	}
+	if caller.ID != staff.ID && !caller.Can(domain.PermissionStaffPermissions) {
+		return nil, NewForbiddenError("view staff permissions")
+	}
+
+	permissions, err := domain.ParseStaffPermissions(staff.Permissions)
+	if err != nil {
+		return nil, NewServiceError("failed to read staff permissions", err)
+	}
+	return dto.ToStaffPermissionsDTO(staff, permissions.Normalize(staff.Role), true), nil
+}
+
+// UpdateStaffPermissions replaces the permissions a member of staff is given or denied beyond
+// the preset of their role. Staff may not change their own permissions, owners always have
+// every permission, and only owners may give permissions they do not hold themselves. A dry
+// run validates the change and returns the resulting permissions without saving them.
+func (s *permissionServiceImpl) UpdateStaffPermissions(ctx context.Context, updateDTO dto.UpdateStaffPermissionsDTO) (*dto.StaffPermissionsDTO, error) {
+	if err := s.validator.Struct(updateDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	permissions := dto.ToStaffPermissions(updateDTO)
+	if err := permissions.Validate(); err != nil {
+		return nil, toValidationError(err)
+	}
+
+	staff, err := s.getStaff(ctx, updateDTO.StaffID)
+	if err != nil {
+		return nil, err
+	}
+	ctx, err = actFor(ctx, staff.BusinessID, "change staff permissions")
+	if err != nil {
+		return nil, err
+	}
+	caller, err := s.caller(ctx, staff.BusinessID, "change staff permissions")
+	if err != nil {
+		return nil, err
+	}
+	if !caller.Can(domain.PermissionStaffPermissions) {
+		return nil, NewForbiddenError("change staff permissions")
+	}
+	if caller.ID == staff.ID {
+		return nil, validation.NewValidationError("staff cannot change their own permissions")
+	}
+	if staff.IsOwner() {
+		return nil, validation.NewValidationError("the owner always has every permission")
+	}
+
+	permissions = permissions.Normalize(staff.Role)
+	if !caller.IsOwner() {
+		held := caller.EffectivePermissions()
+		for _, permission := range permissions.Effective(staff.Role) {
+			if !slices.Contains(held, permission) {
+				return nil, validation.NewValidationError(fmt.Sprintf("cannot give permission %q, which you do not have", permission))
+			}
+		}
+	}
+	if updateDTO.DryRun {
+		return dto.ToStaffPermissionsDTO(staff, permissions, false), nil
+	}
+
+	if staff.Permissions, err = permissions.Encode(); err != nil {
+		return nil, NewServiceError("failed to encode staff permissions", err)
+	}
+	if err := s.staffRepo.Update(ctx, staff); err != nil {
+		return nil, NewServiceError("failed to update staff permissions", err)
+	}
+	return dto.ToStaffPermissionsDTO(staff, permissions, true), nil
+}
+
+// getStaff retrieves a member of staff by ID
+func (s *permissionServiceImpl) getStaff(ctx context.Context, staffID string) (*domain.Staff, error) {
+	staff, err := s.staffRepo.GetByID(ctx, staffID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("staff", "id", staffID)
+		}
+		return nil, NewServiceError("failed to retrieve staff member", err)
+	}
+	return staff, nil
+}
+
+// caller retrieves the caller's active membership of the business's staff
+func (s *permissionServiceImpl) caller(ctx context.Context, businessID, action string) (*domain.Staff, error) {
+	userID := GetUserIDFromContext(ctx)
+	if userID == nil {
+		return nil, NewForbiddenError(action)
+	}
+	staff, err := s.staffRepo.FindByBusinessAndUser(ctx, businessID, *userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewForbiddenError(action)
+		}
+		return nil, NewServiceError("failed to retrieve staff member", err)
+	}
+	if !staff.IsActive {
+		return nil, NewForbiddenError(action)
+	}
+	return staff, nil
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// PriceChangeService defines the service interface for bulk price updates
+type PriceChangeService interface {
+	PreviewPriceChange(ctx context.Context, changeDTO dto.BulkPriceChangeDTO) ([]*dto.PricePreviewDTO, error)
+	SchedulePriceChange(ctx context.Context, changeDTO dto.BulkPriceChangeDTO) (*dto.PriceChangeResponseDTO, error)
+	CancelPriceChange(ctx context.Context, id string) (*dto.PriceChangeResponseDTO, error)
+	ListPriceChanges(ctx context.Context, businessID string) ([]*dto.PriceChangeResponseDTO, error)
+	GetPriceHistory(ctx context.Context, serviceID string) ([]*dto.PriceHistoryDTO, error)
+	ApplyDueChanges(ctx context.Context, now time.Time) (*dto.PriceChangeRunDTO, error)
+}
+
+// priceChangeServiceImpl implements the PriceChangeService interface
+type priceChangeServiceImpl struct {
+	priceChangeRepo domain.PriceChangeRepository
+	validator       *validator.Validate
+}
+
+// NewPriceChangeService creates a new price change service
+func NewPriceChangeService(priceChangeRepo domain.PriceChangeRepository, validator *validator.Validate) PriceChangeService {
+	return &priceChangeServiceImpl{
+		priceChangeRepo: priceChangeRepo,
+		validator:       validator,
+	}
+}
+
+// PreviewPriceChange computes the prices the selected services would have after the change
+func (s *priceChangeServiceImpl) PreviewPriceChange(ctx context.Context, changeDTO dto.BulkPriceChangeDTO) ([]*dto.PricePreviewDTO, error) {
+	change, err := s.buildChange(changeDTO, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	previews, err := s.preview(ctx, change)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToPricePreviewDTOs(previews), nil
+}
+
+// SchedulePriceChange saves a price change to be applied on its effective date. Changes without
+// an effective date are applied immediately.
+func (s *priceChangeServiceImpl) SchedulePriceChange(ctx context.Context, changeDTO dto.BulkPriceChangeDTO) (*dto.PriceChangeResponseDTO, error) {
+	now := time.Now()
+	change, err := s.buildChange(changeDTO, now)
+	if err != nil {
+		return nil, err
+	}
+	previews, err := s.preview(ctx, change)
+	if err != nil {
+		return nil, err
+	}
+
+	change.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.priceChangeRepo.Create(ctx, change); err != nil {
+		return nil, NewServiceError("failed to schedule price change", err)
+	}
+
+	if change.IsDue(now) {
+		if _, err := s.apply(ctx, change, now); err != nil {
+			return nil, err
+		}
+	}
+
+	response := dto.ToPriceChangeResponseDTO(change)
+	response.Preview = dto.ToPricePreviewDTOs(previews)
+	return response, nil
+}
+
+// CancelPriceChange cancels a price change that has not been applied yet
+func (s *priceChangeServiceImpl) CancelPriceChange(ctx context.Context, id string) (*dto.PriceChangeResponseDTO, error) {
+	change, err := s.priceChangeRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("price change", "id", id)
+		}
+		return nil, NewServiceError("failed to retrieve price change", err)
+	}
+	if change.Status != domain.PriceChangeScheduled {
+		return nil, validation.NewValidationError("only scheduled price changes can be cancelled")
+	}
+
+	change.Status = domain.PriceChangeCancelled
+	change.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.priceChangeRepo.Update(ctx, change); err != nil {
+		return nil, NewServiceError("failed to cancel price change", err)
+	}
+	return dto.ToPriceChangeResponseDTO(change), nil
+}
+
+// ListPriceChanges retrieves the price changes of a business, latest effective date first
+func (s *priceChangeServiceImpl) ListPriceChanges(ctx context.Context, businessID string) ([]*dto.PriceChangeResponseDTO, error) {
+	if businessID == "" {
+		return nil, validation.NewValidationError("business_id is required")
+	}
+	changes, err := s.priceChangeRepo.FindByBusinessID(ctx, businessID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve price changes", err)
+	}
+	return dto.ToPriceChangeResponseDTOs(changes), nil
+}
+
+// GetPriceHistory retrieves the price changes of a service, most recent first
+func (s *priceChangeServiceImpl) GetPriceHistory(ctx context.Context, serviceID string) ([]*dto.PriceHistoryDTO, error) {
+	if serviceID == "" {
+		return nil, validation.NewValidationError("service_id is required")
+	}
+	history, err := s.priceChangeRepo.FindHistoryByServiceID(ctx, serviceID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve price history", err)
+	}
+	return dto.ToPriceHistoryDTOs(history), nil
+}
+
+// ApplyDueChanges applies every scheduled price change whose effective date has passed
+func (s *priceChangeServiceImpl) ApplyDueChanges(ctx context.Context, now time.Time) (*dto.PriceChangeRunDTO, error) {
+	changes, err := s.priceChangeRepo.FindDue(ctx, now)
+	if err != nil {
+		return nil, NewServiceError("failed to find due price changes", err)
+	}
+
+	run := &dto.PriceChangeRunDTO{Due: len(changes)}
+	for _, change := range changes {
+		applied, err := s.apply(ctx, change, now)
+		if err != nil {
+			log.Warn().Err(err).Str("price_change_id", change.ID).Msg("Failed to apply price change")
+			run.Failed++
+			continue
+		}
+		run.Applied++
+		run.Services += applied
+	}
+	return run, nil
+}
+
+// apply applies a price change and returns how many prices changed. A change that would make a
+// price negative, because prices were edited after it was scheduled, is marked failed.
+func (s *priceChangeServiceImpl) apply(ctx context.Context, change *domain.PriceChange, now time.Time) (int, error) {
+	history, err := s.priceChangeRepo.Apply(ctx, change, now)
+	if err == nil {
+		return len(history), nil
+	}
+	if !errors.Is(err, domain.ErrValidation) {
+		return 0, NewServiceError("failed to apply price change", err)
+	}
+
+	reason := err.Error()
+	change.Status = domain.PriceChangeFailed
+	change.FailureReason = &reason
+	if updateErr := s.priceChangeRepo.Update(ctx, change); updateErr != nil {
+		return 0, NewServiceError("failed to mark price change failed", updateErr)
+	}
+	return 0, toValidationError(err)
+}
+
+// buildChange validates the request and converts it to a price change
+func (s *priceChangeServiceImpl) buildChange(changeDTO dto.BulkPriceChangeDTO, now time.Time) (*domain.PriceChange, error) {
+	if err := s.validator.Struct(changeDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+
+	change := &domain.PriceChange{
+		BusinessID:     changeDTO.BusinessID,
+		AdjustmentType: changeDTO.AdjustmentType,
+		Amount:         changeDTO.Amount,
+		EffectiveAt:    now,
+		Status:         domain.PriceChangeScheduled,
+		Notes:          changeDTO.Notes,
+	}
+	if changeDTO.EffectiveAt != nil {
+		if changeDTO.EffectiveAt.Before(now) {
+			return nil, validation.NewValidationError("effective_at must not be in the past")
+		}
+		change.EffectiveAt = *changeDTO.EffectiveAt
+	}
+	if err := change.SetTargets(changeDTO.ServiceIDs, changeDTO.CategoryIDs); err != nil {
+		return nil, NewServiceError("failed to encode price change targets", err)
+	}
+	if err := change.Validate(); err != nil {
+		return nil, toValidationError(err)
+	}
+	return change, nil
+}
+
+// preview computes the new prices of the services a change currently selects
+func (s *priceChangeServiceImpl) preview(ctx context.Context, change *domain.PriceChange) ([]domain.PricePreview, error) {
+	serviceIDs, _ := change.GetServiceIDs()
+	categoryIDs, _ := change.GetCategoryIDs()
+	services, err := s.priceChangeRepo.FindTargetServices(ctx, change.BusinessID, serviceIDs, categoryIDs)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve services", err)
+	}
+	if len(services) == 0 {
+		return nil, validation.NewValidationError("no services match the selection")
+	}
+
+	previews, err := domain.PreviewPriceChange(services, change.Adjustment())
+	if err != nil {
+		return nil, toValidationError(err)
+	}
+	return previews, nil
+}
@@ -0,0 +1,508 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// slotTakenMessage is shown to bookers whose slot was taken while they were filling in the form
+const slotTakenMessage = "the selected time is no longer available, please choose another"
+
+// PublicBookingService defines the service interface for the unauthenticated online booking
+// surface, where clients book a business's services without an account
+type PublicBookingService interface {
+	ListServices(ctx context.Context, businessID string) ([]*dto.PublicServiceDTO, error)
+	GetServiceMenu(ctx context.Context, businessID string) (*dto.PublicServiceMenuDTO, error)
+	GetAvailableSlots(ctx context.Context, query dto.PublicSlotsQueryDTO) ([]*dto.PublicSlotDTO, error)
+	RequestBooking(ctx context.Context, request dto.PublicBookingRequestDTO) (*dto.PublicBookingResultDTO, error)
+	GetStaffPage(ctx context.Context, slug string) (*dto.PublicStaffPageDTO, error)
+}
+
+// publicBookingServiceImpl implements the PublicBookingService interface
+type publicBookingServiceImpl struct {
+	businessRepo        domain.BusinessRepository
+	serviceRepo         domain.BaseRepository[domain.Service]
+	staffRepo           domain.StaffRepository
+	userRepo            domain.BaseRepository[domain.User]
+	clientRepo          domain.BaseRepository[domain.Client]
+	appointmentRepo     domain.AppointmentRepository
+	staffPageRepo       domain.StaffBookingPageRepository
+	availabilityService AvailabilityService
+	bookingGuard        BookingGuardService
+	eventService        EventService
+	validator           *validator.Validate
+}
+
+// NewPublicBookingService creates a new public booking service
+func NewPublicBookingService(
+	businessRepo domain.BusinessRepository,
+	serviceRepo domain.BaseRepository[domain.Service],
+	staffRepo domain.StaffRepository,
+	userRepo domain.BaseRepository[domain.User],
+	clientRepo domain.BaseRepository[domain.Client],
+	appointmentRepo domain.AppointmentRepository,
+	staffPageRepo domain.StaffBookingPageRepository,
+	availabilityService AvailabilityService,
+	bookingGuard BookingGuardService,
+	eventService EventService,
+	validator *validator.Validate,
+) PublicBookingService {
+	return &publicBookingServiceImpl{
+		businessRepo:        businessRepo,
+		serviceRepo:         serviceRepo,
+		staffRepo:           staffRepo,
+		userRepo:            userRepo,
+		clientRepo:          clientRepo,
+		appointmentRepo:     appointmentRepo,
+		staffPageRepo:       staffPageRepo,
+		availabilityService: availabilityService,
+		bookingGuard:        bookingGuard,
+		eventService:        eventService,
+		validator:           validator,
+	}
+}
+
+// ListServices lists the active services of a business open to online booking, including those
+// listed for clients to call and book
+func (s *publicBookingServiceImpl) ListServices(ctx context.Context, businessID string) ([]*dto.PublicServiceDTO, error) {
+	ctx = domain.WithTenant(ctx, domain.TenantContext{BusinessID: businessID})
+	if _, err := s.getBusiness(ctx, businessID); err != nil {
+		return nil, err
+	}
+
+	services, err := s.activeServices(ctx, businessID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*dto.PublicServiceDTO, len(services))
+	for i, service := range services {
+		result[i] = dto.ToPublicServiceDTO(service)
+	}
+	return result, nil
+}
+
+// GetServiceMenu returns the services of a business listed online, as ListServices does, with
+// the business's name and when the menu last changed
+func (s *publicBookingServiceImpl) GetServiceMenu(ctx context.Context, businessID string) (*dto.PublicServiceMenuDTO, error) {
+	ctx = domain.WithTenant(ctx, domain.TenantContext{BusinessID: businessID})
+	business, err := s.getBusiness(ctx, businessID)
+	if err != nil {
+		return nil, err
+	}
+	services, err := s.activeServices(ctx, businessID)
+	if err != nil {
+		return nil, err
+	}
+
+	menu := &dto.PublicServiceMenuDTO{
+		BusinessID:   business.ID,
+		BusinessName: business.Name,
+		Currency:     business.Currency,
+		Services:     make([]*dto.PublicServiceDTO, len(services)),
+		UpdatedAt:    business.UpdatedAt,
+	}
+	for i, service := range services {
+		menu.Services[i] = dto.ToPublicServiceDTO(service)
+		if service.UpdatedAt.After(menu.UpdatedAt) {
+			menu.UpdatedAt = service.UpdatedAt
+		}
+	}
+	return menu, nil
+}
+
+// GetAvailableSlots returns the times on a day at which a staff member, or any active staff
+// member when none is given, can take the service, as computed by the availability service.
+// Slots asked for from a booking page are those of the page's staff member.
+func (s *publicBookingServiceImpl) GetAvailableSlots(ctx context.Context, query dto.PublicSlotsQueryDTO) ([]*dto.PublicSlotDTO, error) {
+	if err := s.validator.Struct(query); err != nil {
+		return nil, validation.FromError(err)
+	}
+	ctx = domain.WithTenant(ctx, domain.TenantContext{BusinessID: query.BusinessID})
+	if query.PageSlug != nil {
+		page, err := s.getPageOffering(ctx, query.BusinessID, *query.PageSlug, query.ServiceID)
+		if err != nil {
+			return nil, err
+		}
+		if query.StaffID != nil && *query.StaffID != page.StaffID {
+			return nil, validation.NewValidationError("staff_id does not match the booking page")
+		}
+		query.StaffID = &page.StaffID
+	}
+	business, err := s.getBusiness(ctx, query.BusinessID)
+	if err != nil {
+		return nil, err
+	}
+	service, err := s.getService(ctx, query.ServiceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := service.CheckOnlineBooking(); err != nil {
+		return nil, toValidationError(err)
+	}
+	if service.MaxPerDay != nil {
+		day, err := domain.ParseBookingDate(query.Date, domain.NewAvailabilityRules(business, nil).Location)
+		if err != nil {
+			return nil, toValidationError(err)
+		}
+		booked, err := s.dailyBookings(ctx, service, day, day.Location())
+		if err != nil {
+			return nil, err
+		}
+		if service.CheckDailyLimit(booked) != nil {
+			// The service is fully booked on the day
+			return []*dto.PublicSlotDTO{}, nil
+		}
+	}
+
+	available, err := s.availabilityService.GetAvailableSlots(ctx, dto.AvailableSlotsQueryDTO{
+		BusinessID: query.BusinessID,
+		ServiceID:  query.ServiceID,
+		StaffID:    query.StaffID,
+		StartDate:  query.Date,
+		EndDate:    query.Date,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var staffIDs []string
+	for _, slot := range available {
+		if !slices.Contains(staffIDs, slot.StaffID) {
+			staffIDs = append(staffIDs, slot.StaffID)
+		}
+	}
+	staff, err := s.staffRepo.GetByIDs(ctx, staffIDs)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve staff", err)
+	}
+	names, err := s.staffFirstNames(ctx, staff)
+	if err != nil {
+		return nil, err
+	}
+
+	slots := make([]*dto.PublicSlotDTO, len(available))
+	for i, slot := range available {
+		slots[i] = &dto.PublicSlotDTO{
+			StaffID:   slot.StaffID,
+			StaffName: names[slot.StaffID],
+			StartTime: slot.StartTime,
+			EndTime:   slot.EndTime,
+		}
+	}
+	return slots, nil
+}
+
+// RequestBooking books a service for a client as a pending appointment, which the business
+// then accepts. The attempt goes through the booking guard first; refused attempts create
+// nothing. Clients are matched by email address, and registered with the business when it
+// does not know them yet. Bookings made from a booking page go to the page's staff member.
+// The service's online booking rules apply: it must be bookable online, may be limited to
+// returning clients, and may take a limited number of bookings a day.
+func (s *publicBookingServiceImpl) RequestBooking(ctx context.Context, request dto.PublicBookingRequestDTO) (*dto.PublicBookingResultDTO, error) {
+	if err := s.validator.Struct(request); err != nil {
+		return nil, validation.FromError(err)
+	}
+	ctx = domain.WithTenant(ctx, domain.TenantContext{BusinessID: request.BusinessID})
+
+	business, err := s.getBusiness(ctx, request.BusinessID)
+	if err != nil {
+		return nil, err
+	}
+	if request.PageSlug != nil {
+		page, err := s.getPageOffering(ctx, request.BusinessID, *request.PageSlug, request.ServiceID)
+		if err != nil {
+			return nil, err
+		}
+		if request.StaffID != "" && request.StaffID != page.StaffID {
+			return nil, validation.NewValidationError("staff_id does not match the booking page")
+		}
+		request.StaffID = page.StaffID
+	}
+	service, err := s.getService(ctx, request.ServiceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := service.CheckOnlineBooking(); err != nil {
+		return nil, toValidationError(err)
+	}
+	if _, err := s.getStaff(ctx, request.BusinessID, request.StaffID); err != nil {
+		return nil, err
+	}
+	if err := s.availabilityService.CheckBookingWindow(ctx, service, request.StartTime); err != nil {
+		return nil, err
+	}
+	booked, err := s.dailyBookings(ctx, service, request.StartTime, domain.NewAvailabilityRules(business, nil).Location)
+	if err != nil {
+		return nil, err
+	}
+	if err := service.CheckDailyLimit(booked); err != nil {
+		return nil, toValidationError(err)
+	}
+
+	email := domain.NormalizeEmail(request.Email)
+	client, err := s.findClient(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkClientHistory(ctx, service, client); err != nil {
+		return nil, err
+	}
+
+	attempt := domain.BookingAttempt{
+		BusinessID: request.BusinessID,
+		Phone:      request.Phone,
+		Email:      &email,
+		IPAddress:  GetClientIPFromContext(ctx),
+	}
+	if client != nil {
+		attempt.ClientID = &client.ID
+	}
+	decision, err := s.bookingGuard.CheckBooking(ctx, attempt, request.CaptchaToken)
+	if err != nil {
+		return nil, err
+	}
+	if !decision.Allowed {
+		return &dto.PublicBookingResultDTO{Outcome: decision.Outcome, Message: decision.Message}, nil
+	}
+
+	if client == nil {
+		client = domain.NewOnlineBookingClient(request.BusinessID, request.FirstName, request.LastName, email, request.Phone)
+		if err := s.clientRepo.Create(ctx, client); err != nil {
+			return nil, NewServiceError("failed to register client", err)
+		}
+		publishClientCreated(ctx, s.eventService, client)
+	}
+	if err := client.CheckBookingAge([]*domain.Service{service}, request.StartTime); err != nil {
+		return nil, toValidationError(err)
+	}
+
+	appointment := &domain.Appointment{
+		BusinessID:     request.BusinessID,
+		ClientID:       client.ID,
+		StaffID:        request.StaffID,
+		StartTime:      request.StartTime,
+		EndTime:        request.StartTime.Add(time.Duration(service.Duration) * time.Minute),
+		Status:         domain.AppointmentStatusPending,
+		Notes:          request.Notes,
+		EstimatedPrice: &service.Price,
+	}
+	if err := appointment.Validate(); err != nil {
+		return nil, toValidationError(err)
+	}
+	line := &domain.AppointmentLine{
+		ServiceID: service.ID,
+		StaffID:   request.StaffID,
+		Duration:  service.Duration,
+		Price:     service.Price,
+	}
+	if err := s.appointmentRepo.CreateWithLines(ctx, appointment, []*domain.AppointmentLine{line}); err != nil {
+		if errors.Is(err, domain.ErrAppointmentConflict) {
+			return nil, validation.NewValidationError(slotTakenMessage)
+		}
+		return nil, NewServiceError("failed to create appointment", err)
+	}
+
+	s.publish(ctx, appointment)
+	return &dto.PublicBookingResultDTO{
+		Booked:      true,
+		Outcome:     decision.Outcome,
+		Appointment: dto.ToPublicAppointmentDTO(appointment, service.ID),
+	}, nil
+}
+
+// GetStaffPage returns a published staff booking page with the services that can be booked
+// from it
+func (s *publicBookingServiceImpl) GetStaffPage(ctx context.Context, slug string) (*dto.PublicStaffPageDTO, error) {
+	page, err := s.getPage(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	ctx = domain.WithTenant(ctx, domain.TenantContext{BusinessID: page.BusinessID})
+
+	business, err := s.getBusiness(ctx, page.BusinessID)
+	if err != nil {
+		return nil, err
+	}
+	staff, err := s.getStaff(ctx, page.BusinessID, page.StaffID)
+	if err != nil {
+		return nil, err
+	}
+	names, err := s.staffFirstNames(ctx, []*domain.Staff{staff})
+	if err != nil {
+		return nil, err
+	}
+	services, err := s.activeServices(ctx, page.BusinessID)
+	if err != nil {
+		return nil, err
+	}
+	services = slices.DeleteFunc(services, func(service *domain.Service) bool { return !page.Offers(service.ID) })
+
+	return dto.ToPublicStaffPageDTO(page, business.Name, names[staff.ID], services), nil
+}
+
+// getPage retrieves a published staff booking page
+func (s *publicBookingServiceImpl) getPage(ctx context.Context, slug string) (*domain.StaffBookingPage, error) {
+	page, err := s.staffPageRepo.FindBySlug(ctx, slug)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, NewServiceError("failed to retrieve booking page", err)
+	}
+	if page == nil || !page.IsPublished {
+		return nil, NewNotFoundError("booking page", "slug", slug)
+	}
+	return page, nil
+}
+
+// getPageOffering retrieves a published booking page of the business from which the service
+// can be booked
+func (s *publicBookingServiceImpl) getPageOffering(ctx context.Context, businessID, slug, serviceID string) (*domain.StaffBookingPage, error) {
+	page, err := s.getPage(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if page.BusinessID != businessID {
+		return nil, NewNotFoundError("booking page", "slug", slug)
+	}
+	if !page.Offers(serviceID) {
+		return nil, NewNotFoundError("service", "id", serviceID)
+	}
+	return page, nil
+}
+
+// activeServices returns the active services of a business listed online, in their display order
+func (s *publicBookingServiceImpl) activeServices(ctx context.Context, businessID string) ([]*domain.Service, error) {
+	services, err := s.serviceRepo.FindBy(ctx, map[string]any{"business_id": businessID, "is_active": true})
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve services", err)
+	}
+	services = slices.DeleteFunc(services, func(service *domain.Service) bool { return !service.IsListedOnline() })
+	slices.SortStableFunc(services, func(a, b *domain.Service) int {
+		return cmp.Or(cmp.Compare(a.DisplayOrder, b.DisplayOrder), cmp.Compare(a.Name, b.Name))
+	})
+	return services, nil
+}
+
+// getBusiness retrieves a business that takes online bookings
+func (s *publicBookingServiceImpl) getBusiness(ctx context.Context, businessID string) (*domain.Business, error) {
+	business, err := s.businessRepo.GetByID(ctx, businessID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, NewServiceError("failed to retrieve business", err)
+	}
+	if business == nil || !business.IsActive || business.IsTemplate {
+		return nil, NewNotFoundError("business", "id", businessID)
+	}
+	return business, nil
+}
+
+// getService retrieves an active service of the tenant business listed online
+func (s *publicBookingServiceImpl) getService(ctx context.Context, serviceID string) (*domain.Service, error) {
+	service, err := s.serviceRepo.GetByID(ctx, serviceID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, NewServiceError("failed to retrieve service", err)
+	}
+	if service == nil || !service.IsActive || !service.IsListedOnline() {
+		return nil, NewNotFoundError("service", "id", serviceID)
+	}
+	return service, nil
+}
+
+// dailyBookings returns the appointments booked for a service on the local day of a time, or
+// nil when the service takes any number of bookings a day
+func (s *publicBookingServiceImpl) dailyBookings(ctx context.Context, service *domain.Service, at time.Time, location *time.Location) ([]*domain.Appointment, error) {
+	if service.MaxPerDay == nil {
+		return nil, nil
+	}
+	day := domain.LocalDay(at, location)
+	booked, err := s.appointmentRepo.FindByBusinessID(ctx, service.BusinessID, domain.AppointmentFilters{ServiceID: &service.ID, DateRange: &day})
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve appointments", err)
+	}
+	return booked, nil
+}
+
+// checkClientHistory checks that a client, nil when new to the business, may book the service
+func (s *publicBookingServiceImpl) checkClientHistory(ctx context.Context, service *domain.Service, client *domain.Client) error {
+	if !service.ReturningClientsOnly {
+		return nil
+	}
+	var appointments []*domain.Appointment
+	if client != nil {
+		var err error
+		if appointments, err = s.appointmentRepo.FindByClientID(ctx, client.ID); err != nil {
+			return NewServiceError("failed to retrieve appointments", err)
+		}
+	}
+	if err := service.CheckClientHistory(appointments); err != nil {
+		return toValidationError(err)
+	}
+	return nil
+}
+
+// getStaff retrieves an active staff member of the business
+func (s *publicBookingServiceImpl) getStaff(ctx context.Context, businessID, staffID string) (*domain.Staff, error) {
+	staff, err := s.staffRepo.GetByID(ctx, staffID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, NewServiceError("failed to retrieve staff member", err)
+	}
+	if staff == nil || staff.BusinessID != businessID || !staff.IsActive {
+		return nil, NewNotFoundError("staff", "id", staffID)
+	}
+	return staff, nil
+}
+
+// findClient finds the tenant business's client with an email address, or nil when there is none
+func (s *publicBookingServiceImpl) findClient(ctx context.Context, email string) (*domain.Client, error) {
+	clients, err := s.clientRepo.FindBy(ctx, map[string]any{"email": email})
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve client", err)
+	}
+	if len(clients) == 0 {
+		return nil, nil
+	}
+	return clients[0], nil
+}
+
+// staffFirstNames returns the first names of the staff members, keyed by staff ID. Bookers only
+// see first names.
+func (s *publicBookingServiceImpl) staffFirstNames(ctx context.Context, staff []*domain.Staff) (map[string]string, error) {
+	userIDs := make([]string, len(staff))
+	for i, member := range staff {
+		userIDs[i] = member.UserID
+	}
+	users, err := s.userRepo.GetByIDs(ctx, userIDs)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve staff names", err)
+	}
+
+	firstNames := make(map[string]string, len(users))
+	for _, user := range users {
+		firstNames[user.ID] = user.FirstName
+	}
+	names := make(map[string]string, len(staff))
+	for _, member := range staff {
+		names[member.ID] = firstNames[member.UserID]
+	}
+	return names, nil
+}
+
+// publish records the creation of the appointment so that read models pick it up
+func (s *publicBookingServiceImpl) publish(ctx context.Context, appointment *domain.Appointment) {
+	event, err := domain.NewDomainEvent(domain.AggregateAppointment, appointment.ID, domain.EventAppointmentCreated, &appointment.BusinessID,
+		map[string]any{"staff_id": appointment.StaffID, "start_time": appointment.StartTime, "end_time": appointment.EndTime})
+	if err == nil {
+		err = s.eventService.Publish(ctx, event)
+	}
+	if err != nil {
+		log.Error().Err(err).Str("appointment_id", appointment.ID).Msg("Failed to publish appointment event")
+	}
+}
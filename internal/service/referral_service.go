@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// topReferrersLimit is the number of referrers listed in the referral stats
+const topReferrersLimit = 10
+
+// ReferralService defines the service interface for loyalty program referrals. Referrers are
+// awarded their bonus by the loyalty engine, when the referred client completes a first visit.
+type ReferralService interface {
+	IssueReferralCode(ctx context.Context, programID, clientID string) (*dto.ReferralCodeDTO, error)
+	RecordReferral(ctx context.Context, recordDTO dto.RecordReferralDTO) (*dto.ReferralResponseDTO, error)
+	GetReferralStats(ctx context.Context, businessID string, start, end time.Time) (*dto.ReferralStatsDTO, error)
+}
+
+// referralServiceImpl implements the ReferralService interface
+type referralServiceImpl struct {
+	loyaltyRepo     domain.LoyaltyRepository
+	clientRepo      domain.BaseRepository[domain.Client]
+	appointmentRepo domain.AppointmentRepository
+	validator       *validator.Validate
+}
+
+// NewReferralService creates a new referral service
+func NewReferralService(
+	loyaltyRepo domain.LoyaltyRepository,
+	clientRepo domain.BaseRepository[domain.Client],
+	appointmentRepo domain.AppointmentRepository,
+	validator *validator.Validate,
+) ReferralService {
+	return &referralServiceImpl{
+		loyaltyRepo:     loyaltyRepo,
+		clientRepo:      clientRepo,
+		appointmentRepo: appointmentRepo,
+		validator:       validator,
+	}
+}
+
+// IssueReferralCode returns the code a client refers friends to a program with, joining the
+// client to the program and giving them a code the first time it is asked for
+func (s *referralServiceImpl) IssueReferralCode(ctx context.Context, programID, clientID string) (*dto.ReferralCodeDTO, error) {
+	if programID == "" || clientID == "" {
+		return nil, validation.NewValidationError("program_id and client_id are required")
+	}
+	program, err := s.loyaltyRepo.GetByID(ctx, programID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("loyalty program", "id", programID)
+		}
+		return nil, NewServiceError("failed to retrieve loyalty program", err)
+	}
+	ctx, err = actFor(ctx, program.BusinessID, "view referral codes")
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkClient(ctx, program.BusinessID, clientID); err != nil {
+		return nil, err
+	}
+
+	membership, err := s.loyaltyRepo.FindMembership(ctx, program.ID, clientID)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		membership = &domain.ClientLoyaltyMembership{
+			ProgramID: program.ID,
+			ClientID:  clientID,
+			JoinDate:  time.Now(),
+			IsActive:  true,
+		}
+	case err != nil:
+		return nil, NewServiceError("failed to retrieve loyalty membership", err)
+	}
+
+	if membership.ReferralCode == nil {
+		code, err := domain.NewReferralCode()
+		if err != nil {
+			return nil, NewServiceError("failed to issue referral code", err)
+		}
+		membership.ReferralCode = &code
+		membership.SetAuditFields(GetUserIDFromContext(ctx))
+		if err := s.loyaltyRepo.SaveMembership(ctx, membership); err != nil {
+			return nil, NewServiceError("failed to issue referral code", err)
+		}
+	}
+
+	return &dto.ReferralCodeDTO{
+		ProgramID:    program.ID,
+		ClientID:     clientID,
+		MembershipID: membership.ID,
+		Code:         *membership.ReferralCode,
+	}, nil
+}
+
+// RecordReferral records that a new client was referred with a member's code. Only clients who
+// have not completed a visit yet can be referred, and only once per business.
+func (s *referralServiceImpl) RecordReferral(ctx context.Context, recordDTO dto.RecordReferralDTO) (*dto.ReferralResponseDTO, error) {
+	if err := s.validator.Struct(recordDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	ctx, err := actFor(ctx, recordDTO.BusinessID, "record referrals")
+	if err != nil {
+		return nil, err
+	}
+
+	code := domain.NormalizeReferralCode(recordDTO.Code)
+	referrer, err := s.loyaltyRepo.FindMembershipByReferralCode(ctx, code)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, NewServiceError("failed to retrieve loyalty membership", err)
+	}
+	var program *domain.LoyaltyProgram
+	if referrer != nil {
+		program, err = s.loyaltyRepo.GetByID(ctx, referrer.ProgramID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewServiceError("failed to retrieve loyalty program", err)
+		}
+	}
+	if program == nil || program.BusinessID != recordDTO.BusinessID {
+		return nil, NewNotFoundError("referral code", "code", code)
+	}
+	if err := s.checkClient(ctx, recordDTO.BusinessID, recordDTO.ReferredClientID); err != nil {
+		return nil, err
+	}
+
+	_, err = s.loyaltyRepo.FindReferral(ctx, recordDTO.BusinessID, recordDTO.ReferredClientID)
+	switch {
+	case err == nil:
+		return nil, validation.NewValidationError("the client was already referred")
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, NewServiceError("failed to retrieve referral", err)
+	}
+	appointments, err := s.appointmentRepo.FindByClientID(ctx, recordDTO.ReferredClientID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve appointments", err)
+	}
+	if slices.ContainsFunc(appointments, func(a *domain.Appointment) bool { return a.Status == domain.AppointmentStatusCompleted }) {
+		return nil, validation.NewValidationError("only clients who have not completed a visit yet can be referred")
+	}
+
+	referral, err := domain.NewReferral(program, referrer, recordDTO.ReferredClientID)
+	if err != nil {
+		return nil, toValidationError(err)
+	}
+	referral.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.loyaltyRepo.CreateReferral(ctx, referral); err != nil {
+		return nil, NewServiceError("failed to record referral", err)
+	}
+	return dto.ToReferralResponseDTO(referral), nil
+}
+
+// GetReferralStats reports how the business's referrals made over a period converted
+func (s *referralServiceImpl) GetReferralStats(ctx context.Context, businessID string, start, end time.Time) (*dto.ReferralStatsDTO, error) {
+	if businessID == "" {
+		return nil, validation.NewValidationError("business_id is required")
+	}
+	if !end.After(start) {
+		return nil, validation.NewValidationError("end must be after start")
+	}
+	ctx, err := actFor(ctx, businessID, "view referral statistics")
+	if err != nil {
+		return nil, err
+	}
+
+	referrals, err := s.loyaltyRepo.FindReferrals(ctx, businessID, start, end)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve referrals", err)
+	}
+	return dto.ToReferralStatsDTO(businessID, start, end, domain.SummarizeReferrals(referrals, topReferrersLimit)), nil
+}
+
+// checkClient checks that the client belongs to the business
+func (s *referralServiceImpl) checkClient(ctx context.Context, businessID, clientID string) error {
+	client, err := s.clientRepo.GetByID(ctx, clientID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return NewServiceError("failed to retrieve client", err)
+	}
+	if client == nil || client.BusinessID != businessID {
+		return NewNotFoundError("client", "id", clientID)
+	}
+	return nil
+}
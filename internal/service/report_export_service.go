@@ -0,0 +1,475 @@
+package service
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/jobs"
+	"github.com/assimoes/beautix/internal/infrastructure/residency"
+	"github.com/assimoes/beautix/internal/infrastructure/storage"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+const (
+	// reportExportAttempts is how many times an export job runs before the export is failed
+	reportExportAttempts     = 3
+	defaultReportExportLimit = 20
+	maxReportExportLimit     = 100
+)
+
+// ReportExportService defines the service interface for exporting reports - revenue,
+// commissions, the client list and appointments - to CSV or PDF files. Exports are rendered by
+// background jobs into the storage bucket of the business's data region, and downloaded through
+// short-lived signed URLs.
+type ReportExportService interface {
+	ExportReport(ctx context.Context, createDTO dto.CreateReportExportDTO) (*dto.ReportExportResponseDTO, error)
+	GetReportExport(ctx context.Context, id string) (*dto.ReportExportResponseDTO, error)
+	ListReportExports(ctx context.Context, businessID string, limit int) ([]*dto.ReportExportResponseDTO, error)
+	GetDownloadURL(ctx context.Context, id string) (*dto.ReportExportDownloadDTO, error)
+	RegisterJobs(runner *jobs.Runner)
+}
+
+// reportExportServiceImpl implements the ReportExportService interface
+type reportExportServiceImpl struct {
+	exportRepo        domain.ReportExportRepository
+	staffRepo         domain.StaffRepository
+	userRepo          domain.UserRepository
+	businessRepo      domain.BusinessRepository
+	commissionService CommissionService
+	store             storage.Store
+	buckets           *residency.Buckets
+	queue             *jobs.Queue
+	urlExpiry         time.Duration
+	validator         *validator.Validate
+}
+
+// NewReportExportService creates a new report export service, along with the handlers of the
+// jobs rendering exports and removing them once expired. Signed download URLs stay valid for
+// urlExpiry.
+func NewReportExportService(
+	exportRepo domain.ReportExportRepository,
+	staffRepo domain.StaffRepository,
+	userRepo domain.UserRepository,
+	businessRepo domain.BusinessRepository,
+	commissionService CommissionService,
+	store storage.Store,
+	buckets *residency.Buckets,
+	queue *jobs.Queue,
+	urlExpiry time.Duration,
+	validator *validator.Validate,
+) ReportExportService {
+	return &reportExportServiceImpl{
+		exportRepo:        exportRepo,
+		staffRepo:         staffRepo,
+		userRepo:          userRepo,
+		businessRepo:      businessRepo,
+		commissionService: commissionService,
+		store:             store,
+		buckets:           buckets,
+		queue:             queue,
+		urlExpiry:         urlExpiry,
+		validator:         validator,
+	}
+}
+
+// RegisterJobs registers the handlers of the export jobs with a runner
+func (s *reportExportServiceImpl) RegisterJobs(runner *jobs.Runner) {
+	runner.Register(domain.JobReportExport, s.render)
+	runner.Register(domain.JobReportExportExpire, s.expire)
+}
+
+// ExportReport records an export of a report and enqueues the job rendering it. Exporting the
+// client list takes the clients:export permission; other reports reports:view.
+func (s *reportExportServiceImpl) ExportReport(ctx context.Context, createDTO dto.CreateReportExportDTO) (*dto.ReportExportResponseDTO, error) {
+	if err := s.validator.Struct(createDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	if err := s.requirePermission(ctx, createDTO.BusinessID, createDTO.Report.Permission(), "export the "+string(createDTO.Report)+" report"); err != nil {
+		return nil, err
+	}
+	business, err := getBusiness(ctx, s.businessRepo, createDTO.BusinessID)
+	if err != nil {
+		return nil, err
+	}
+	location := domain.NewAvailabilityRules(business, nil).Location
+	now := time.Now().UTC()
+
+	export := &domain.ReportExport{
+		BaseModel:  domain.BaseModel{CreatedBy: GetUserIDFromContext(ctx)},
+		BusinessID: createDTO.BusinessID,
+		Report:     createDTO.Report,
+		Format:     createDTO.Format,
+		Status:     domain.ReportExportPending,
+	}
+	if createDTO.Report.IsDated() {
+		if createDTO.StartDate == nil || createDTO.EndDate == nil {
+			return nil, validation.NewValidationError(fmt.Sprintf("the %s report needs a start_date and an end_date", createDTO.Report))
+		}
+		first, last, err := reportDates(*createDTO.StartDate, *createDTO.EndDate, location)
+		if err != nil {
+			return nil, err
+		}
+		startDate, endDate := first.Format(time.DateOnly), last.Format(time.DateOnly)
+		export.StartDate, export.EndDate = &startDate, &endDate
+	}
+	export.FileName = domain.ReportFileName(export.Report, export.Format, export.StartDate, export.EndDate, now.In(location))
+	if err := export.Validate(); err != nil {
+		return nil, toValidationError(err)
+	}
+
+	if err := s.exportRepo.Create(ctx, export); err != nil {
+		return nil, NewServiceError("failed to create report export", err)
+	}
+	job, err := s.queue.Enqueue(ctx, domain.JobReportExport, domain.ReportExportPayload{ExportID: export.ID},
+		jobs.WithKey(export.ID), jobs.ForBusiness(export.BusinessID), jobs.MaxAttempts(reportExportAttempts))
+	if err != nil {
+		return nil, NewServiceError("failed to enqueue report export", err)
+	}
+	export.JobID = &job.ID
+	if err := s.exportRepo.Update(ctx, export); err != nil {
+		return nil, NewServiceError("failed to update report export", err)
+	}
+	return dto.ToReportExportResponseDTO(export), nil
+}
+
+// GetReportExport returns an export, to those who may export its report
+func (s *reportExportServiceImpl) GetReportExport(ctx context.Context, id string) (*dto.ReportExportResponseDTO, error) {
+	export, err := s.getExport(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToReportExportResponseDTO(export), nil
+}
+
+// ListReportExports returns the most recent exports of a business, newest first, leaving out
+// those of reports the caller may not export. Platform admins see them all.
+func (s *reportExportServiceImpl) ListReportExports(ctx context.Context, businessID string, limit int) ([]*dto.ReportExportResponseDTO, error) {
+	var staff *domain.Staff
+	if !IsPlatformAdmin(ctx) {
+		var err error
+		if staff, err = s.callerStaff(ctx, businessID, "view report exports"); err != nil {
+			return nil, err
+		}
+	}
+	if limit <= 0 {
+		limit = defaultReportExportLimit
+	}
+	exports, err := s.exportRepo.FindByBusinessID(ctx, businessID, min(limit, maxReportExportLimit))
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve report exports", err)
+	}
+	result := []*dto.ReportExportResponseDTO{}
+	for _, export := range exports {
+		if staff == nil || staff.Can(export.Report.Permission()) {
+			result = append(result, dto.ToReportExportResponseDTO(export))
+		}
+	}
+	return result, nil
+}
+
+// GetDownloadURL signs a URL an export's file can be downloaded from without signing in. The
+// URL stays valid for the configured expiry, or until the export expires if sooner.
+func (s *reportExportServiceImpl) GetDownloadURL(ctx context.Context, id string) (*dto.ReportExportDownloadDTO, error) {
+	export, err := s.getExport(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	if !export.IsDownloadable(now) {
+		switch export.Status {
+		case domain.ReportExportPending:
+			return nil, validation.NewValidationError("the export is still being generated")
+		case domain.ReportExportFailed:
+			return nil, validation.NewValidationError("the export failed and cannot be downloaded")
+		default:
+			return nil, validation.NewValidationError("the export has expired")
+		}
+	}
+
+	expiry := min(s.urlExpiry, export.ExpiresAt.Sub(now))
+	url, err := s.store.SignedURL(*export.Bucket, *export.ObjectKey, export.FileName, expiry)
+	if err != nil {
+		return nil, NewServiceError("failed to sign download URL", err)
+	}
+	return &dto.ReportExportDownloadDTO{
+		ExportID:    export.ID,
+		URL:         url,
+		FileName:    export.FileName,
+		ContentType: export.Format.ContentType(),
+		ExpiresAt:   now.Add(expiry).Truncate(time.Second),
+	}, nil
+}
+
+// render renders the export of a job's payload and stores it in the bucket of the business's
+// data region, then schedules its removal. Once the job runs out of attempts, or fails in a way
+// retrying cannot fix, the export is marked failed.
+func (s *reportExportServiceImpl) render(ctx context.Context, job *domain.Job) error {
+	var payload domain.ReportExportPayload
+	if err := job.DecodePayload(&payload); err != nil {
+		return jobs.Permanent(err)
+	}
+	export, err := s.exportRepo.GetByID(ctx, payload.ExportID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return jobs.Permanent(err)
+		}
+		return err
+	}
+	if export.Status != domain.ReportExportPending {
+		return nil
+	}
+
+	if err := s.renderExport(ctx, export); err != nil {
+		permanent := jobs.IsPermanent(err) || errors.Is(err, storage.ErrNotConfigured) || errors.Is(err, domain.ErrDataResidency)
+		if !permanent && job.Attempts+1 < job.MaxAttempts {
+			return err
+		}
+		export.MarkFailed(err.Error(), time.Now().UTC())
+		if updateErr := s.exportRepo.Update(ctx, export); updateErr != nil {
+			return errors.Join(err, updateErr)
+		}
+		return jobs.Permanent(err)
+	}
+
+	log.Info().Str("business_id", export.BusinessID).Str("export_id", export.ID).Str("report", string(export.Report)).
+		Int("rows", *export.RowCount).Msg("Exported report")
+	return nil
+}
+
+// renderExport renders and stores an export, marking it ready
+func (s *reportExportServiceImpl) renderExport(ctx context.Context, export *domain.ReportExport) error {
+	business, err := s.businessRepo.GetByID(ctx, export.BusinessID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return jobs.Permanent(err)
+		}
+		return err
+	}
+	region := business.DataRegion
+	if region == "" {
+		region = domain.DefaultDataRegion
+	}
+	bucket, err := s.buckets.Select(region)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	table, err := s.table(ctx, export, domain.NewAvailabilityRules(business, nil).Location, now)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := table.Write(&buf, export.Format, now); err != nil {
+		return jobs.Permanent(err)
+	}
+
+	key := export.ObjectKeyFor()
+	if err := s.store.Put(ctx, storage.Object{Bucket: bucket, Key: key, ContentType: export.Format.ContentType(), Body: buf.Bytes()}); err != nil {
+		return err
+	}
+	export.MarkReady(bucket, key, len(table.Rows), int64(buf.Len()), now)
+	if err := s.exportRepo.Update(ctx, export); err != nil {
+		return err
+	}
+	// A failure to schedule the removal is logged rather than retried, which would render the
+	// export again; the storage lifecycle rules are the backstop
+	if _, err := s.queue.Enqueue(ctx, domain.JobReportExportExpire, domain.ReportExportPayload{ExportID: export.ID},
+		jobs.WithKey(export.ID), jobs.ForBusiness(export.BusinessID), jobs.At(*export.ExpiresAt)); err != nil {
+		log.Warn().Err(err).Str("export_id", export.ID).Msg("Failed to schedule report export removal")
+	}
+	return nil
+}
+
+// table runs the query of an export's report
+func (s *reportExportServiceImpl) table(ctx context.Context, export *domain.ReportExport, location *time.Location, now time.Time) (*domain.ReportTable, error) {
+	if export.Report == domain.ReportClients {
+		clients, err := s.exportRepo.FindClients(ctx, export.BusinessID)
+		if err != nil {
+			return nil, err
+		}
+		return domain.NewClientReport(now, location, clients), nil
+	}
+
+	if export.StartDate == nil || export.EndDate == nil {
+		return nil, jobs.Permanent(fmt.Errorf("the %s report needs a start and an end date", export.Report))
+	}
+	first, last, err := reportDates(*export.StartDate, *export.EndDate, location)
+	if err != nil {
+		return nil, jobs.Permanent(err)
+	}
+	start, end := first, last.AddDate(0, 0, 1)
+	switch export.Report {
+	case domain.ReportRevenue:
+		rows, err := s.exportRepo.FindRevenueRows(ctx, export.BusinessID, start, end)
+		if err != nil {
+			return nil, err
+		}
+		return domain.NewRevenueReport(*export.StartDate, *export.EndDate, location, rows), nil
+	case domain.ReportAppointments:
+		rows, err := s.exportRepo.FindAppointmentRows(ctx, export.BusinessID, start, end)
+		if err != nil {
+			return nil, err
+		}
+		return domain.NewAppointmentReport(*export.StartDate, *export.EndDate, location, rows), nil
+	case domain.ReportCommissions:
+		rows, err := s.commissionRows(ctx, export.BusinessID, start, end)
+		if err != nil {
+			return nil, err
+		}
+		return domain.NewCommissionReport(*export.StartDate, *export.EndDate, rows), nil
+	default:
+		return nil, jobs.Permanent(fmt.Errorf("unknown report %q", export.Report))
+	}
+}
+
+// commissionRows calculates the commission of each staff member of a business with a commission
+// plan over the range, ordered by name
+func (s *reportExportServiceImpl) commissionRows(ctx context.Context, businessID string, start, end time.Time) ([]*domain.CommissionReportRow, error) {
+	members, err := s.staffRepo.FindByBusinessID(ctx, businessID)
+	if err != nil {
+		return nil, err
+	}
+	userIDs := make([]string, len(members))
+	for i, member := range members {
+		userIDs[i] = member.UserID
+	}
+	users, err := s.userRepo.GetByIDs(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	names := map[string]string{}
+	for _, user := range users {
+		names[user.ID] = user.GetFullName()
+	}
+
+	rows := []*domain.CommissionReportRow{}
+	for _, member := range members {
+		report, err := s.commissionService.CalculateCommission(ctx, member.ID, start, end)
+		if err != nil {
+			if errors.As(err, &NotFoundError{}) {
+				continue
+			}
+			return nil, err
+		}
+		rows = append(rows, &domain.CommissionReportRow{
+			StaffID:        member.ID,
+			StaffName:      names[member.UserID],
+			ServiceRevenue: report.ServiceRevenue,
+			ProductRevenue: report.ProductRevenue,
+			Commission:     report.Total,
+		})
+	}
+	slices.SortStableFunc(rows, func(a, b *domain.CommissionReportRow) int {
+		return cmp.Or(cmp.Compare(a.StaffName, b.StaffName), cmp.Compare(a.StaffID, b.StaffID))
+	})
+	return rows, nil
+}
+
+// expire removes the file of an expired export from storage
+func (s *reportExportServiceImpl) expire(ctx context.Context, job *domain.Job) error {
+	var payload domain.ReportExportPayload
+	if err := job.DecodePayload(&payload); err != nil {
+		return jobs.Permanent(err)
+	}
+	export, err := s.exportRepo.GetByID(ctx, payload.ExportID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return jobs.Permanent(err)
+		}
+		return err
+	}
+	if export.Status != domain.ReportExportReady || export.Bucket == nil || export.ObjectKey == nil {
+		return nil
+	}
+
+	if err := s.store.Delete(ctx, *export.Bucket, *export.ObjectKey); err != nil {
+		if errors.Is(err, storage.ErrNotConfigured) {
+			return jobs.Permanent(err)
+		}
+		return err
+	}
+	export.MarkExpired()
+	return s.exportRepo.Update(ctx, export)
+}
+
+// getExport returns an export, refusing callers who may not export its report
+func (s *reportExportServiceImpl) getExport(ctx context.Context, id string) (*domain.ReportExport, error) {
+	export, err := s.exportRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("report export", "id", id)
+		}
+		return nil, NewServiceError("failed to retrieve report export", err)
+	}
+	if err := s.requirePermission(ctx, export.BusinessID, export.Report.Permission(), "view the export"); err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
+// requirePermission checks that the caller is active staff of the business with a permission,
+// refusing the action otherwise. Platform admins export the data of any business.
+func (s *reportExportServiceImpl) requirePermission(ctx context.Context, businessID string, permission domain.Permission, action string) error {
+	if IsPlatformAdmin(ctx) {
+		return nil
+	}
+	staff, err := s.callerStaff(ctx, businessID, action)
+	if err != nil {
+		return err
+	}
+	if !staff.Can(permission) {
+		return NewForbiddenError(action)
+	}
+	return nil
+}
+
+// callerStaff returns the caller's active staff record at the business, refusing the action
+// when they have none
+func (s *reportExportServiceImpl) callerStaff(ctx context.Context, businessID, action string) (*domain.Staff, error) {
+	userID := GetUserIDFromContext(ctx)
+	if userID == nil {
+		return nil, NewForbiddenError(action)
+	}
+	staff, err := s.staffRepo.FindByBusinessAndUser(ctx, businessID, *userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewForbiddenError(action)
+		}
+		return nil, NewServiceError("failed to retrieve staff member", err)
+	}
+	if !staff.IsActive {
+		return nil, NewForbiddenError(action)
+	}
+	return staff, nil
+}
+
+// reportDates parses the inclusive range of local dates of a report, returning the start of the
+// first and of the last
+func reportDates(startDate, endDate string, location *time.Location) (time.Time, time.Time, error) {
+	first, err := domain.ParseBookingDate(startDate, location)
+	if err != nil {
+		return time.Time{}, time.Time{}, toValidationError(err)
+	}
+	last, err := domain.ParseBookingDate(endDate, location)
+	if err != nil {
+		return time.Time{}, time.Time{}, toValidationError(err)
+	}
+	if last.Before(first) {
+		return time.Time{}, time.Time{}, validation.NewValidationError("end_date must not be before start_date")
+	}
+	if last.After(first.AddDate(0, 0, domain.MaxReportRangeDays-1)) {
+		return time.Time{}, time.Time{}, validation.NewValidationError(fmt.Sprintf("a report cannot cover more than %d days", domain.MaxReportRangeDays))
+	}
+	return first, last, nil
+}
@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// ResourceService defines the service interface for the rooms, chairs and equipment that
+// appointments occupy
+type ResourceService interface {
+	CreateResource(ctx context.Context, createDTO dto.CreateResourceDTO) (*dto.ResourceResponseDTO, error)
+	UpdateResource(ctx context.Context, id string, updateDTO dto.UpdateResourceDTO) (*dto.ResourceResponseDTO, error)
+	DeleteResource(ctx context.Context, id string) error
+	GetResources(ctx context.Context, businessID string, locationID *string) ([]*dto.ResourceResponseDTO, error)
+	GetServiceResources(ctx context.Context, serviceID string) (*dto.ServiceResourcesDTO, error)
+	SetServiceResources(ctx context.Context, setDTO dto.SetServiceResourcesDTO) (*dto.ServiceResourcesDTO, error)
+}
+
+// resourceServiceImpl implements the ResourceService interface
+type resourceServiceImpl struct {
+	resourceRepo domain.ResourceRepository
+	locationRepo domain.BusinessLocationRepository
+	serviceRepo  domain.BaseRepository[domain.Service]
+	staffRepo    domain.StaffRepository
+	validator    *validator.Validate
+}
+
+// NewResourceService creates a new resource service
+func NewResourceService(
+	resourceRepo domain.ResourceRepository,
+	locationRepo domain.BusinessLocationRepository,
+	serviceRepo domain.BaseRepository[domain.Service],
+	staffRepo domain.StaffRepository,
+	validator *validator.Validate,
+) ResourceService {
+	return &resourceServiceImpl{
+		resourceRepo: resourceRepo,
+		locationRepo: locationRepo,
+		serviceRepo:  serviceRepo,
+		staffRepo:    staffRepo,
+		validator:    validator,
+	}
+}
+
+// CreateResource adds a room, chair or piece of equipment to a business. It holds up bookings
+// once services are set to need it. Only owners and managers can add resources.
+func (s *resourceServiceImpl) CreateResource(ctx context.Context, createDTO dto.CreateResourceDTO) (*dto.ResourceResponseDTO, error) {
+	if err := s.validator.Struct(createDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	if err := requireManager(ctx, s.staffRepo, createDTO.BusinessID, "manage resources"); err != nil {
+		return nil, err
+	}
+	ctx, err := actFor(ctx, createDTO.BusinessID, "manage resources")
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureLocation(ctx, createDTO.BusinessID, createDTO.LocationID); err != nil {
+		return nil, err
+	}
+
+	resource := &domain.Resource{
+		BusinessID: createDTO.BusinessID,
+		LocationID: createDTO.LocationID,
+		Name:       strings.TrimSpace(createDTO.Name),
+		Kind:       createDTO.Kind,
+		Capacity:   1,
+		IsActive:   true,
+		Notes:      createDTO.Notes,
+	}
+	if createDTO.Capacity != nil {
+		resource.Capacity = *createDTO.Capacity
+	}
+	if err := resource.Validate(); err != nil {
+		return nil, toValidationError(err)
+	}
+
+	resource.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.resourceRepo.Create(ctx, resource); err != nil {
+		return nil, NewServiceError("failed to create resource", err)
+	}
+	return dto.ToResourceResponseDTO(resource), nil
+}
+
+// UpdateResource changes a resource. Appointments already booked are kept even if the resource
+// can no longer hold them all. Only owners and managers can change resources.
+func (s *resourceServiceImpl) UpdateResource(ctx context.Context, id string, updateDTO dto.UpdateResourceDTO) (*dto.ResourceResponseDTO, error) {
+	if err := s.validator.Struct(updateDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	resource, err := s.getResource(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireManager(ctx, s.staffRepo, resource.BusinessID, "manage resources"); err != nil {
+		return nil, err
+	}
+	if err := resource.CheckVersion(resource.TableName(), updateDTO.ExpectedVersion); err != nil {
+		return nil, err
+	}
+	ctx, err = actFor(ctx, resource.BusinessID, "manage resources")
+	if err != nil {
+		return nil, err
+	}
+
+	updateDTO.LocationID.Apply(&resource.LocationID)
+	if err := s.ensureLocation(ctx, resource.BusinessID, resource.LocationID); err != nil {
+		return nil, err
+	}
+	if updateDTO.Name != nil {
+		resource.Name = strings.TrimSpace(*updateDTO.Name)
+	}
+	if updateDTO.Kind != nil {
+		resource.Kind = *updateDTO.Kind
+	}
+	if updateDTO.Capacity != nil {
+		resource.Capacity = *updateDTO.Capacity
+	}
+	if updateDTO.IsActive != nil {
+		resource.IsActive = *updateDTO.IsActive
+	}
+	updateDTO.Notes.Apply(&resource.Notes)
+	if err := resource.Validate(); err != nil {
+		return nil, toValidationError(err)
+	}
+
+	resource.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.resourceRepo.Update(ctx, resource); err != nil {
+		return nil, NewServiceError("failed to update resource", err)
+	}
+	return dto.ToResourceResponseDTO(resource), nil
+}
+
+// DeleteResource removes a resource, which then no longer holds up bookings of the services that
+// needed it. Only owners and managers can remove resources.
+func (s *resourceServiceImpl) DeleteResource(ctx context.Context, id string) error {
+	resource, err := s.getResource(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := requireManager(ctx, s.staffRepo, resource.BusinessID, "manage resources"); err != nil {
+		return err
+	}
+	if err := s.resourceRepo.Delete(ctx, id); err != nil {
+		return NewServiceError("failed to delete resource", err)
+	}
+	return nil
+}
+
+// GetResources returns the resources of a business by name, only those at a location when one
+// is given. Any staff member of the business can see them.
+func (s *resourceServiceImpl) GetResources(ctx context.Context, businessID string, locationID *string) ([]*dto.ResourceResponseDTO, error) {
+	if err := requireStaff(ctx, s.staffRepo, businessID, "view resources"); err != nil {
+		return nil, err
+	}
+	ctx, err := actFor(ctx, businessID, "view resources")
+	if err != nil {
+		return nil, err
+	}
+	resources, err := s.resourceRepo.FindByBusiness(ctx, businessID, locationID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve resources", err)
+	}
+	return dto.ToResourceResponseDTOs(resources), nil
+}
+
+// GetServiceResources returns the active resources a service needs. Any staff member of the
+// business can see them.
+func (s *resourceServiceImpl) GetServiceResources(ctx context.Context, serviceID string) (*dto.ServiceResourcesDTO, error) {
+	service, err := s.getService(ctx, serviceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireStaff(ctx, s.staffRepo, service.BusinessID, "view resources"); err != nil {
+		return nil, err
+	}
+	ctx, err = actFor(ctx, service.BusinessID, "view resources")
+	if err != nil {
+		return nil, err
+	}
+	return s.serviceResources(ctx, service.ID)
+}
+
+// SetServiceResources replaces the resources every appointment for a service occupies. Bookings
+// from then on are rejected while any of them is fully in use; appointments already booked are
+// kept. Only owners and managers can set the resources of services.
+func (s *resourceServiceImpl) SetServiceResources(ctx context.Context, setDTO dto.SetServiceResourcesDTO) (*dto.ServiceResourcesDTO, error) {
+	if err := s.validator.Struct(setDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	service, err := s.getService(ctx, setDTO.ServiceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireManager(ctx, s.staffRepo, service.BusinessID, "manage resources"); err != nil {
+		return nil, err
+	}
+	ctx, err = actFor(ctx, service.BusinessID, "manage resources")
+	if err != nil {
+		return nil, err
+	}
+
+	resourceIDs := slices.Compact(slices.Sorted(slices.Values(setDTO.ResourceIDs)))
+	rows := make([]*domain.ServiceResource, len(resourceIDs))
+	userID := GetUserIDFromContext(ctx)
+	for i, resourceID := range resourceIDs {
+		resource, err := s.getResource(ctx, resourceID)
+		if err != nil {
+			return nil, err
+		}
+		if resource.BusinessID != service.BusinessID {
+			return nil, NewNotFoundError("resource", "id", resourceID)
+		}
+		rows[i] = &domain.ServiceResource{BusinessID: service.BusinessID, ServiceID: service.ID, ResourceID: resource.ID}
+		rows[i].SetAuditFields(userID)
+	}
+	if err := s.resourceRepo.SetServiceResources(ctx, service.ID, rows); err != nil {
+		return nil, NewServiceError("failed to set service resources", err)
+	}
+	return s.serviceResources(ctx, service.ID)
+}
+
+// serviceResources returns the active resources a service needs
+func (s *resourceServiceImpl) serviceResources(ctx context.Context, serviceID string) (*dto.ServiceResourcesDTO, error) {
+	resources, err := s.resourceRepo.FindByServices(ctx, []string{serviceID})
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve service resources", err)
+	}
+	return &dto.ServiceResourcesDTO{ServiceID: serviceID, Resources: dto.ToResourceResponseDTOs(resources)}, nil
+}
+
+// ensureLocation checks that a location, when there is one, is the business's
+func (s *resourceServiceImpl) ensureLocation(ctx context.Context, businessID string, locationID *string) error {
+	if locationID == nil {
+		return nil
+	}
+	location, err := s.locationRepo.GetByID(ctx, *locationID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return NewServiceError("failed to retrieve location", err)
+	}
+	if location == nil || location.BusinessID != businessID {
+		return NewNotFoundError("location", "id", *locationID)
+	}
+	return nil
+}
+
+// getResource retrieves a resource, failing with a not found error when there is none
+func (s *resourceServiceImpl) getResource(ctx context.Context, id string) (*domain.Resource, error) {
+	if id == "" {
+		return nil, validation.NewValidationError("id is required")
+	}
+	resource, err := s.resourceRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("resource", "id", id)
+		}
+		return nil, NewServiceError("failed to retrieve resource", err)
+	}
+	return resource, nil
+}
+
+// getService retrieves a service, failing with a not found error when there is none
+func (s *resourceServiceImpl) getService(ctx context.Context, id string) (*domain.Service, error) {
+	service, err := s.serviceRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("service", "id", id)
+		}
+		return nil, NewServiceError("failed to retrieve service", err)
+	}
+	return service, nil
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/infrastructure/jobs"
+	"github.com/assimoes/beautix/internal/infrastructure/notification"
+	"gorm.io/gorm"
+)
+
+// ReviewFollowUp asks clients for a review of their completed appointments through the job
+// queue, a while after their visit as set by the business
+type ReviewFollowUp interface {
+	EventConsumer
+	RegisterJobs(runner *jobs.Runner)
+}
+
+// reviewFollowUpImpl implements the ReviewFollowUp interface
+type reviewFollowUpImpl struct {
+	reviewRepo           domain.ReviewRepository
+	businessSettingsRepo domain.BusinessSettingsRepository
+	queue                *jobs.Queue
+	sender               notification.NotificationSender
+	publicURL            string
+}
+
+// NewReviewFollowUp creates the event consumer scheduling review requests for completed
+// appointments, along with the job handler sending them. Review links point to publicURL, the
+// externally reachable base URL of the API.
+func NewReviewFollowUp(
+	reviewRepo domain.ReviewRepository,
+	businessSettingsRepo domain.BusinessSettingsRepository,
+	queue *jobs.Queue,
+	sender notification.NotificationSender,
+	publicURL string,
+) ReviewFollowUp {
+	return &reviewFollowUpImpl{
+		reviewRepo:           reviewRepo,
+		businessSettingsRepo: businessSettingsRepo,
+		queue:                queue,
+		sender:               sender,
+		publicURL:            publicURL,
+	}
+}
+
+// Name returns the consumer name used when replaying events
+func (f *reviewFollowUpImpl) Name() string { return "review_follow_up" }
+
+// Handle schedules the review request of a completed appointment. The job is keyed by
+// appointment, so handling an event again does not schedule it twice.
+func (f *reviewFollowUpImpl) Handle(ctx context.Context, event *domain.DomainEvent) error {
+	if event.AggregateType != domain.AggregateAppointment || event.EventType != domain.EventAppointmentCompleted {
+		return nil
+	}
+	appointment, err := f.reviewable(ctx, event.AggregateID)
+	if err != nil || appointment == nil {
+		return err
+	}
+	settings, err := f.businessSettingsRepo.GetByBusinessID(ctx, appointment.BusinessID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("failed to retrieve business settings: %w", err)
+	}
+	delay, ok := domain.ReviewRequestDelay(settings)
+	if !ok {
+		return nil
+	}
+
+	_, err = f.queue.Enqueue(ctx, domain.JobReviewRequest, domain.ReviewRequestJobPayload{AppointmentID: appointment.AppointmentID},
+		jobs.WithKey(appointment.AppointmentID), jobs.At(event.OccurredAt.Add(delay)), jobs.ForBusiness(appointment.BusinessID))
+	if err != nil {
+		return fmt.Errorf("failed to schedule review request: %w", err)
+	}
+	return nil
+}
+
+// RegisterJobs registers the handlers of the review request and reply notification jobs with a
+// runner
+func (f *reviewFollowUpImpl) RegisterJobs(runner *jobs.Runner) {
+	runner.Register(domain.JobReviewRequest, f.request)
+	runner.Register(domain.JobReviewReply, f.reply)
+}
+
+// request emails the client of a completed appointment a link to review it, unless they were
+// already sent one or left a review
+func (f *reviewFollowUpImpl) request(ctx context.Context, job *domain.Job) error {
+	var payload domain.ReviewRequestJobPayload
+	if err := job.DecodePayload(&payload); err != nil {
+		return jobs.Permanent(err)
+	}
+	appointment, err := f.reviewable(ctx, payload.AppointmentID)
+	if err != nil || appointment == nil || appointment.ClientEmail == "" {
+		return err
+	}
+
+	now := time.Now()
+	request, err := f.reviewRepo.FindRequestByAppointment(ctx, appointment.AppointmentID)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		token, err := domain.NewConfirmationToken()
+		if err != nil {
+			return fmt.Errorf("failed to create review link: %w", err)
+		}
+		request = &domain.ReviewRequest{
+			BusinessID:    appointment.BusinessID,
+			AppointmentID: appointment.AppointmentID,
+			Token:         token,
+			ExpiresAt:     now.Add(domain.ReviewRequestValidity),
+		}
+	case err != nil:
+		return fmt.Errorf("failed to retrieve review request: %w", err)
+	case request.SentAt != nil:
+		return nil
+	}
+	// The link is stored before it is sent, so a retry sends the same one
+	if err := f.reviewRepo.SaveRequest(ctx, request); err != nil {
+		return fmt.Errorf("failed to save review request: %w", err)
+	}
+
+	location, err := time.LoadLocation(appointment.TimeZone)
+	if err != nil {
+		location = time.UTC
+	}
+	subject := "How was your visit to " + appointment.BusinessName + "?"
+	body := fmt.Sprintf("Hi %s,\n\nThank you for visiting %s on %s. We would love to hear how your %s with %s went. "+
+		"It only takes a moment:\n\n%s\n\nThe link works for %d days.",
+		appointment.ClientFirstName, appointment.BusinessName, appointment.StartTime.In(location).Format("Monday 2 January"),
+		appointment.ServiceName, appointment.StaffName, f.publicURL+ReviewLinkPath+request.Token,
+		int(domain.ReviewRequestValidity.Hours()/24))
+	err = f.sender.Send(ctx, notification.Message{
+		BusinessID: appointment.BusinessID,
+		Channel:    domain.MessageChannelEmail,
+		Recipient:  appointment.ClientEmail,
+		Subject:    &subject,
+		Body:       body,
+	})
+	if err != nil {
+		return err
+	}
+	request.SentAt = &now
+	if err := f.reviewRepo.SaveRequest(ctx, request); err != nil {
+		return fmt.Errorf("failed to save review request: %w", err)
+	}
+	return nil
+}
+
+// reply emails the client who left a review the business's reply to it, unless the reply was
+// removed or held back since it was queued
+func (f *reviewFollowUpImpl) reply(ctx context.Context, job *domain.Job) error {
+	var payload domain.ReviewReplyJobPayload
+	if err := job.DecodePayload(&payload); err != nil {
+		return jobs.Permanent(err)
+	}
+	review, err := f.reviewRepo.GetByID(ctx, payload.ReviewID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to retrieve review: %w", err)
+	}
+	if !review.IsReplyShown() {
+		return nil
+	}
+	appointment, err := f.reviewRepo.FindReviewable(ctx, review.AppointmentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to retrieve appointment: %w", err)
+	}
+	if appointment.ClientAnonymized || appointment.ClientEmail == "" {
+		return nil
+	}
+
+	subject := appointment.BusinessName + " replied to your review"
+	body := fmt.Sprintf("Hi %s,\n\nThank you for reviewing your visit to %s. They replied:\n\n%s",
+		appointment.ClientFirstName, appointment.BusinessName, *review.Reply)
+	return f.sender.Send(ctx, notification.Message{
+		BusinessID: appointment.BusinessID,
+		Channel:    domain.MessageChannelEmail,
+		Recipient:  appointment.ClientEmail,
+		Subject:    &subject,
+		Body:       body,
+	})
+}
+
+// reviewable retrieves an appointment its client can be asked to review, or nil when it is not
+// completed, was already reviewed, its client was anonymized or it no longer exists
+func (f *reviewFollowUpImpl) reviewable(ctx context.Context, appointmentID string) (*domain.ReviewableAppointment, error) {
+	appointment, err := f.reviewRepo.FindReviewable(ctx, appointmentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to retrieve appointment: %w", err)
+	}
+	if !appointment.CanBeReviewed() {
+		return nil, nil
+	}
+	return appointment, nil
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/jobs"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// ReviewLinkPath is the path of the link clients open to review a completed appointment
+const ReviewLinkPath = "/review/"
+
+// ReviewService defines the service interface for the reviews clients leave of their completed
+// appointments, and their moderation by the business
+type ReviewService interface {
+	GetReviewForm(ctx context.Context, token string) (*dto.ReviewFormDTO, error)
+	SubmitReview(ctx context.Context, submitDTO dto.SubmitReviewDTO) error
+	ListReviews(ctx context.Context, listDTO dto.ListReviewsDTO) ([]*dto.ReviewResponseDTO, int64, error)
+	ModerateReview(ctx context.Context, moderateDTO dto.ModerateReviewDTO) (*dto.ReviewResponseDTO, error)
+	ReplyToReview(ctx context.Context, replyDTO dto.ReplyToReviewDTO) (*dto.ReviewResponseDTO, error)
+}
+
+// reviewServiceImpl implements the ReviewService interface
+type reviewServiceImpl struct {
+	reviewRepo domain.ReviewRepository
+	staffRepo  domain.StaffRepository
+	filter     domain.ContentFilter
+	queue      *jobs.Queue
+	validator  *validator.Validate
+}
+
+// NewReviewService creates a new review service. Reviews and replies the filter flags are held
+// back for the business to look at; a nil filter flags nothing. Clients are told of replies to
+// their reviews through the job queue.
+func NewReviewService(
+	reviewRepo domain.ReviewRepository,
+	staffRepo domain.StaffRepository,
+	filter domain.ContentFilter,
+	queue *jobs.Queue,
+	validator *validator.Validate,
+) ReviewService {
+	return &reviewServiceImpl{
+		reviewRepo: reviewRepo,
+		staffRepo:  staffRepo,
+		filter:     filter,
+		queue:      queue,
+		validator:  validator,
+	}
+}
+
+// GetReviewForm retrieves what the review page shows for a link, failing when the link can no
+// longer be used
+func (s *reviewServiceImpl) GetReviewForm(ctx context.Context, token string) (*dto.ReviewFormDTO, error) {
+	_, appointment, err := s.loadRequest(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.ReviewFormDTO{
+		BusinessName: appointment.BusinessName,
+		StaffName:    appointment.StaffName,
+		ServiceName:  appointment.ServiceName,
+		StartTime:    appointment.StartTime,
+		TimeZone:     appointment.TimeZone,
+	}, nil
+}
+
+// SubmitReview stores the review left with a link, using up the link. Reviews without a comment
+// are published, and counted in the ratings, at once; the others wait for moderation, flagged
+// when the content filter flags their comment.
+func (s *reviewServiceImpl) SubmitReview(ctx context.Context, submitDTO dto.SubmitReviewDTO) error {
+	if err := s.validator.Struct(submitDTO); err != nil {
+		return validation.FromError(err)
+	}
+	request, appointment, err := s.loadRequest(ctx, submitDTO.Token)
+	if err != nil {
+		return err
+	}
+
+	review, err := domain.NewReview(appointment, submitDTO.Rating, submitDTO.Comment, s.filter)
+	if err != nil {
+		return toValidationError(err)
+	}
+	if err := s.reviewRepo.Submit(ctx, request.ID, review, time.Now()); err != nil {
+		if errors.Is(err, domain.ErrReviewRequestUsed) {
+			return validation.FromError(err)
+		}
+		return NewServiceError("failed to leave review", err)
+	}
+	if review.Status == domain.ReviewPublished {
+		s.refreshRatings(ctx, review)
+	}
+	return nil
+}
+
+// ListReviews lists the reviews of a business, newest first. Published reviews are public; the
+// others, and the moderation details, are only listed to staff who may moderate reviews.
+func (s *reviewServiceImpl) ListReviews(ctx context.Context, listDTO dto.ListReviewsDTO) ([]*dto.ReviewResponseDTO, int64, error) {
+	if err := s.validator.Struct(listDTO); err != nil {
+		return nil, 0, validation.FromError(err)
+	}
+	moderator, err := s.canModerate(ctx, listDTO.BusinessID)
+	if err != nil {
+		return nil, 0, err
+	}
+	filter := domain.ReviewFilter{Status: listDTO.Status, StaffID: listDTO.StaffID}
+	if !moderator {
+		if filter.Status != nil && *filter.Status != domain.ReviewPublished {
+			return nil, 0, NewForbiddenError("list unpublished reviews")
+		}
+		published := domain.ReviewPublished
+		filter.Status = &published
+	}
+
+	reviews, total, err := s.reviewRepo.FindByBusiness(ctx, listDTO.BusinessID, filter, listDTO.Page, listDTO.PageSize)
+	if err != nil {
+		return nil, 0, NewServiceError("failed to retrieve reviews", err)
+	}
+	result := make([]*dto.ReviewResponseDTO, len(reviews))
+	for i, review := range reviews {
+		result[i] = dto.ToReviewResponseDTO(review, moderator)
+	}
+	return result, total, nil
+}
+
+// ModerateReview publishes or rejects a review, updating the ratings it counts in. Publishing a
+// review with a reply held back lets the client know of the reply.
+func (s *reviewServiceImpl) ModerateReview(ctx context.Context, moderateDTO dto.ModerateReviewDTO) (*dto.ReviewResponseDTO, error) {
+	if err := s.validator.Struct(moderateDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	review, err := s.getModeratedReview(ctx, moderateDTO.ReviewID, "moderate reviews")
+	if err != nil {
+		return nil, err
+	}
+
+	wasPublished := review.Status == domain.ReviewPublished
+	userID := GetUserIDFromContext(ctx)
+	if err := review.Moderate(moderateDTO.Publish, moderateDTO.Note, userID, time.Now()); err != nil {
+		return nil, toValidationError(err)
+	}
+	review.SetAuditFields(userID)
+	if err := s.reviewRepo.Update(ctx, review); err != nil {
+		return nil, NewServiceError("failed to update review", err)
+	}
+	if wasPublished != (review.Status == domain.ReviewPublished) {
+		s.refreshRatings(ctx, review)
+	}
+	if !wasPublished && review.IsReplyShown() {
+		s.notifyReply(ctx, review)
+	}
+	return dto.ToReviewResponseDTO(review, true), nil
+}
+
+// ReplyToReview sets or removes the business's public reply to a review, letting the client know
+// once it is shown. A reply the content filter flags holds the review back until it is
+// published again.
+func (s *reviewServiceImpl) ReplyToReview(ctx context.Context, replyDTO dto.ReplyToReviewDTO) (*dto.ReviewResponseDTO, error) {
+	if err := s.validator.Struct(replyDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	review, err := s.getModeratedReview(ctx, replyDTO.ReviewID, "reply to reviews")
+	if err != nil {
+		return nil, err
+	}
+
+	wasPublished := review.Status == domain.ReviewPublished
+	userID := GetUserIDFromContext(ctx)
+	if err := review.SetReply(replyDTO.Reply, userID, time.Now(), s.filter); err != nil {
+		return nil, toValidationError(err)
+	}
+	review.SetAuditFields(userID)
+	if err := s.reviewRepo.Update(ctx, review); err != nil {
+		return nil, NewServiceError("failed to update review", err)
+	}
+	if wasPublished != (review.Status == domain.ReviewPublished) {
+		s.refreshRatings(ctx, review)
+	}
+	if review.IsReplyShown() {
+		s.notifyReply(ctx, review)
+	}
+	return dto.ToReviewResponseDTO(review, true), nil
+}
+
+// loadRequest loads a review request that can still be used, along with the appointment it is
+// about
+func (s *reviewServiceImpl) loadRequest(ctx context.Context, token string) (*domain.ReviewRequest, *domain.ReviewableAppointment, error) {
+	if token == "" {
+		return nil, nil, validation.NewValidationError("token is required")
+	}
+	request, err := s.reviewRepo.FindRequestByToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, NewNotFoundError("review link", "token", token)
+		}
+		return nil, nil, NewServiceError("failed to retrieve review link", err)
+	}
+	if err := request.CheckUsable(time.Now()); err != nil {
+		return nil, nil, validation.FromError(err)
+	}
+	appointment, err := s.reviewRepo.FindReviewable(ctx, request.AppointmentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, NewNotFoundError("review link", "token", token)
+		}
+		return nil, nil, NewServiceError("failed to retrieve appointment", err)
+	}
+	if appointment.Reviewed {
+		return nil, nil, validation.NewValidationError(domain.ErrReviewRequestUsed.Error())
+	}
+	return request, appointment, nil
+}
+
+// getModeratedReview retrieves a review the caller may moderate
+func (s *reviewServiceImpl) getModeratedReview(ctx context.Context, id, action string) (*domain.Review, error) {
+	review, err := s.reviewRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("review", "id", id)
+		}
+		return nil, NewServiceError("failed to retrieve review", err)
+	}
+	moderator, err := s.canModerate(ctx, review.BusinessID)
+	if err != nil {
+		return nil, err
+	}
+	if !moderator {
+		return nil, NewForbiddenError(action)
+	}
+	return review, nil
+}
+
+// canModerate reports whether the caller is an active member of the business's staff who may
+// moderate reviews
+func (s *reviewServiceImpl) canModerate(ctx context.Context, businessID string) (bool, error) {
+	userID := GetUserIDFromContext(ctx)
+	if userID == nil {
+		return false, nil
+	}
+	staff, err := s.staffRepo.FindByBusinessAndUser(ctx, businessID, *userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, NewServiceError("failed to retrieve staff member", err)
+	}
+	return staff.IsActive && staff.Can(domain.PermissionReviewsModerate), nil
+}
+
+// notifyReply queues the notification telling the client of the business's reply to their
+// review. The job is keyed by review, so replies edited before it runs are sent once, as they
+// last stood. The reply is already stored, so a failure is only logged.
+func (s *reviewServiceImpl) notifyReply(ctx context.Context, review *domain.Review) {
+	_, err := s.queue.Enqueue(ctx, domain.JobReviewReply, domain.ReviewReplyJobPayload{ReviewID: review.ID},
+		jobs.WithKey(review.ID), jobs.ForBusiness(review.BusinessID))
+	if err != nil {
+		log.Error().Err(err).Str("review_id", review.ID).Msg("Failed to queue review reply notification")
+	}
+}
+
+// refreshRatings updates the ratings a review counts in. The review is already stored, so a
+// failure is only logged; the next change to the ratings corrects them.
+func (s *reviewServiceImpl) refreshRatings(ctx context.Context, review *domain.Review) {
+	if err := s.reviewRepo.RefreshRatings(ctx, review.BusinessID, review.StaffID); err != nil {
+		log.Error().Err(err).Str("review_id", review.ID).Msg("Failed to refresh ratings")
+	}
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/jobs"
+	"github.com/assimoes/beautix/internal/infrastructure/notification"
+	"github.com/assimoes/beautix/internal/repository/memory"
+)
+
+// recordingSender records the messages it is asked to send
+type recordingSender struct {
+	sent []notification.Message
+}
+
+func (s *recordingSender) Send(ctx context.Context, message notification.Message) error {
+	s.sent = append(s.sent, message)
+	return nil
+}
+
+func TestReviewService_FlagsAndNotifiesReplies(t *testing.T) {
+	db := memory.NewDB()
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	business := &domain.Business{UserID: "owner-1", Name: "Studio", TimeZone: "Europe/Lisbon"}
+	require.NoError(t, memory.Insert(db, business))
+	user := &domain.User{FirstName: "Ana", LastName: "Silva"}
+	require.NoError(t, memory.Insert(db, user))
+	owner := &domain.Staff{BusinessID: business.ID, UserID: user.ID, Role: domain.BusinessRoleOwner, IsActive: true}
+	client := &domain.Client{BusinessID: business.ID, FirstName: "Rita", LastName: "Costa", Email: "rita@example.com"}
+	haircut := &domain.Service{BusinessID: business.ID, Name: "Haircut"}
+	require.NoError(t, memory.Insert(db, owner))
+	require.NoError(t, memory.Insert(db, client))
+	require.NoError(t, memory.Insert(db, haircut))
+	visit := func() string {
+		appointment := &domain.Appointment{BusinessID: business.ID, StaffID: owner.ID, ClientID: client.ID, StartTime: now,
+			Status: domain.AppointmentStatusCompleted}
+		require.NoError(t, memory.Insert(db, appointment))
+		require.NoError(t, memory.Insert(db, &domain.AppointmentLine{AppointmentID: appointment.ID, ServiceID: haircut.ID, StaffID: owner.ID}))
+		token := "token-" + appointment.ID
+		require.NoError(t, memory.Insert(db, &domain.ReviewRequest{BusinessID: business.ID, AppointmentID: appointment.ID,
+			Token: token, ExpiresAt: time.Now().Add(domain.ReviewRequestValidity)}))
+		return token
+	}
+
+	reviewRepo := memory.NewReviewRepository(db)
+	jobRepo := memory.NewJobRepository(db)
+	queue := jobs.NewQueue(jobRepo, nil)
+	runner := jobs.NewRunner(jobRepo)
+	sender := &recordingSender{}
+	NewReviewFollowUp(reviewRepo, memory.NewBusinessSettingsRepository(db), queue, sender, "https://beautix.example").RegisterJobs(runner)
+	reviews := NewReviewService(reviewRepo, memory.NewStaffRepository(db), domain.NewWordFilter([]string{"scam", "idiot"}), queue, validator.New())
+	ctx := context.Background()
+	ownerCtx := SetUserContext(ctx, user.ID, string(owner.Role), &business.ID)
+	status := func(status domain.ReviewStatus) []*dto.ReviewResponseDTO {
+		listed, _, err := reviews.ListReviews(ownerCtx, dto.ListReviewsDTO{BusinessID: business.ID, Status: &status, Page: 1, PageSize: 20})
+		require.NoError(t, err)
+		return listed
+	}
+	runJobs := func() {
+		_, err := runner.Run(ctx, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, reviews.SubmitReview(ctx, dto.SubmitReviewDTO{Token: visit(), Rating: 1, Comment: "A scam"}))
+	require.Len(t, status(domain.ReviewFlagged), 1, "comments the filter flags are held back")
+	require.NoError(t, reviews.SubmitReview(ctx, dto.SubmitReviewDTO{Token: visit(), Rating: 5}))
+	published := status(domain.ReviewPublished)
+	require.Len(t, published, 1)
+
+	replied, err := reviews.ReplyToReview(ownerCtx, dto.ReplyToReviewDTO{ReviewID: published[0].ID, Reply: "Thank you, Rita!"})
+	require.NoError(t, err)
+	assert.Equal(t, domain.ReviewPublished, replied.Status)
+	runJobs()
+	require.Len(t, sender.sent, 1, "the client is told of the reply")
+	assert.Equal(t, "rita@example.com", sender.sent[0].Recipient)
+	assert.Equal(t, "Studio replied to your review", *sender.sent[0].Subject)
+	assert.Contains(t, sender.sent[0].Body, "Thank you, Rita!")
+
+	held, err := reviews.ReplyToReview(ownerCtx, dto.ReplyToReviewDTO{ReviewID: published[0].ID, Reply: "Do not call us an idiot"})
+	require.NoError(t, err)
+	assert.Equal(t, domain.ReviewFlagged, held.Status, "replies the filter flags hold the review back")
+	runJobs()
+	assert.Len(t, sender.sent, 1, "replies held back are not sent")
+
+	_, err = reviews.ModerateReview(ownerCtx, dto.ModerateReviewDTO{ReviewID: published[0].ID, Publish: true})
+	require.NoError(t, err)
+	runJobs()
+	require.Len(t, sender.sent, 2, "publishing the review sends the reply held back")
+	assert.Contains(t, sender.sent[1].Body, "Do not call us an idiot")
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"gorm.io/gorm"
+)
+
+// RunSheetService defines the service interface for the daily run sheets of staff members
+type RunSheetService interface {
+	GetDailyRunSheet(ctx context.Context, staffID, date string) (*dto.RunSheetDTO, error)
+}
+
+// runSheetServiceImpl implements the RunSheetService interface
+type runSheetServiceImpl struct {
+	staffRepo         domain.StaffRepository
+	calendarRepo      domain.CalendarRepository
+	clientRepo        domain.BaseRepository[domain.Client]
+	serviceRecordRepo domain.ServiceRecordRepository
+}
+
+// NewRunSheetService creates a new run sheet service
+func NewRunSheetService(
+	staffRepo domain.StaffRepository,
+	calendarRepo domain.CalendarRepository,
+	clientRepo domain.BaseRepository[domain.Client],
+	serviceRecordRepo domain.ServiceRecordRepository,
+) RunSheetService {
+	return &runSheetServiceImpl{
+		staffRepo:         staffRepo,
+		calendarRepo:      calendarRepo,
+		clientRepo:        clientRepo,
+		serviceRecordRepo: serviceRecordRepo,
+	}
+}
+
+// GetDailyRunSheet retrieves a staff member's appointments of a local date in chronological
+// order, each with the client's preferences, allergies, notes and latest service record, for
+// printing or display at the station (the staff member, or owners and managers)
+func (s *runSheetServiceImpl) GetDailyRunSheet(ctx context.Context, staffID, date string) (*dto.RunSheetDTO, error) {
+	day, err := domain.ParseBookingDate(date, time.UTC)
+	if err != nil {
+		return nil, toValidationError(err)
+	}
+	staff, err := s.authorize(ctx, staffID)
+	if err != nil {
+		return nil, err
+	}
+
+	appointments, err := s.calendarRepo.FindByDateRange(ctx, staff.BusinessID, day, day, &staff.ID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve appointments", err)
+	}
+	clientIDs := domain.RunSheetClientIDs(appointments)
+	clients, err := s.clientRepo.GetByIDs(ctx, clientIDs)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve clients", err)
+	}
+	records, err := s.serviceRecordRepo.FindLatestByClients(ctx, clientIDs)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve service records", err)
+	}
+
+	return dto.ToRunSheetDTO(domain.NewRunSheet(staff.ID, day, appointments, clients, records)), nil
+}
+
+// authorize retrieves the staff member whose run sheet is requested, checking that the caller
+// is the staff member, an owner or manager of their business, or a platform admin
+func (s *runSheetServiceImpl) authorize(ctx context.Context, staffID string) (*domain.Staff, error) {
+	if staffID == "" {
+		return nil, validation.NewValidationError("staff_id is required")
+	}
+	userID := GetUserIDFromContext(ctx)
+	if userID == nil && !IsPlatformAdmin(ctx) {
+		return nil, NewForbiddenError("view run sheets")
+	}
+	staff, err := s.staffRepo.GetByID(ctx, staffID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("staff", "id", staffID)
+		}
+		return nil, NewServiceError("failed to retrieve staff member", err)
+	}
+	if IsPlatformAdmin(ctx) || staff.UserID == *userID {
+		return staff, nil
+	}
+	if err := requireManager(ctx, s.staffRepo, staff.BusinessID, "view the run sheets of other staff members"); err != nil {
+		return nil, err
+	}
+	return staff, nil
+}
@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// SavedViewService defines the service interface for the named filters and sorts users save
+// for the client and appointment lists of a business
+type SavedViewService interface {
+	CreateSavedView(ctx context.Context, createDTO dto.CreateSavedViewDTO) (*dto.SavedViewResponseDTO, error)
+	UpdateSavedView(ctx context.Context, id string, updateDTO dto.UpdateSavedViewDTO) (*dto.SavedViewResponseDTO, error)
+	DeleteSavedView(ctx context.Context, id string) error
+	GetSavedView(ctx context.Context, id string) (*dto.SavedViewResponseDTO, error)
+	ListSavedViews(ctx context.Context, businessID string, entity *domain.SavedViewEntity) ([]*dto.SavedViewResponseDTO, error)
+}
+
+// savedViewServiceImpl implements the SavedViewService interface
+type savedViewServiceImpl struct {
+	savedViewRepo domain.SavedViewRepository
+	staffRepo     domain.StaffRepository
+	validator     *validator.Validate
+}
+
+// NewSavedViewService creates a new saved view service
+func NewSavedViewService(
+	savedViewRepo domain.SavedViewRepository,
+	staffRepo domain.StaffRepository,
+	validator *validator.Validate,
+) SavedViewService {
+	return &savedViewServiceImpl{
+		savedViewRepo: savedViewRepo,
+		staffRepo:     staffRepo,
+		validator:     validator,
+	}
+}
+
+// CreateSavedView saves a view for the user asking, who must be on the business's staff. Only
+// managers can share a view with the staff of some roles.
+func (s *savedViewServiceImpl) CreateSavedView(ctx context.Context, createDTO dto.CreateSavedViewDTO) (*dto.SavedViewResponseDTO, error) {
+	if err := s.validator.Struct(createDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	staff, err := s.viewer(ctx, createDTO.BusinessID, "save views")
+	if err != nil {
+		return nil, err
+	}
+	if len(createDTO.SharedWithRoles) > 0 && !staff.CanManage() {
+		return nil, NewForbiddenError("share views")
+	}
+
+	count, err := s.savedViewRepo.CountByOwner(ctx, staff.BusinessID, staff.UserID, createDTO.Entity)
+	if err != nil {
+		return nil, NewServiceError("failed to count saved views", err)
+	}
+	if count >= domain.MaxSavedViewsPerUser {
+		return nil, validation.NewValidationError(fmt.Sprintf("a user cannot save more than %d views of %s", domain.MaxSavedViewsPerUser, createDTO.Entity))
+	}
+
+	view := &domain.SavedView{
+		BusinessID:     staff.BusinessID,
+		UserID:         staff.UserID,
+		Name:           strings.TrimSpace(createDTO.Name),
+		Entity:         createDTO.Entity,
+		SortField:      createDTO.SortField,
+		SortDescending: createDTO.SortDescending,
+	}
+	if err := view.SetFilters(createDTO.Filters); err != nil {
+		return nil, NewServiceError("failed to encode filters", err)
+	}
+	if err := view.SetSharedWithRoles(createDTO.SharedWithRoles); err != nil {
+		return nil, NewServiceError("failed to encode shared roles", err)
+	}
+	if err := view.Validate(); err != nil {
+		return nil, toValidationError(err)
+	}
+	if err := s.requireUniqueName(ctx, view); err != nil {
+		return nil, err
+	}
+
+	view.SetAuditFields(&staff.UserID)
+	if err := s.savedViewRepo.Create(ctx, view); err != nil {
+		return nil, NewServiceError("failed to create saved view", err)
+	}
+	return dto.ToSavedViewResponseDTO(view, staff.UserID), nil
+}
+
+// UpdateSavedView changes a view (owner only)
+func (s *savedViewServiceImpl) UpdateSavedView(ctx context.Context, id string, updateDTO dto.UpdateSavedViewDTO) (*dto.SavedViewResponseDTO, error) {
+	if err := s.validator.Struct(updateDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	view, staff, err := s.ownedView(ctx, id, "change saved views")
+	if err != nil {
+		return nil, err
+	}
+
+	if updateDTO.Name != nil {
+		view.Name = strings.TrimSpace(*updateDTO.Name)
+	}
+	if updateDTO.Filters != nil {
+		if err := view.SetFilters(*updateDTO.Filters); err != nil {
+			return nil, NewServiceError("failed to encode filters", err)
+		}
+	}
+	updateDTO.SortField.Apply(&view.SortField)
+	if updateDTO.SortDescending != nil {
+		view.SortDescending = *updateDTO.SortDescending
+	}
+	if updateDTO.SharedWithRoles != nil {
+		if len(*updateDTO.SharedWithRoles) > 0 && !staff.CanManage() {
+			return nil, NewForbiddenError("share views")
+		}
+		if err := view.SetSharedWithRoles(*updateDTO.SharedWithRoles); err != nil {
+			return nil, NewServiceError("failed to encode shared roles", err)
+		}
+	}
+	if err := view.Validate(); err != nil {
+		return nil, toValidationError(err)
+	}
+	if updateDTO.Name != nil {
+		if err := s.requireUniqueName(ctx, view); err != nil {
+			return nil, err
+		}
+	}
+
+	view.SetAuditFields(&staff.UserID)
+	if err := s.savedViewRepo.Update(ctx, view); err != nil {
+		return nil, NewServiceError("failed to update saved view", err)
+	}
+	return dto.ToSavedViewResponseDTO(view, staff.UserID), nil
+}
+
+// DeleteSavedView deletes a view (owner only)
+func (s *savedViewServiceImpl) DeleteSavedView(ctx context.Context, id string) error {
+	view, _, err := s.ownedView(ctx, id, "delete saved views")
+	if err != nil {
+		return err
+	}
+	if err := s.savedViewRepo.Delete(ctx, view.ID); err != nil {
+		return NewServiceError("failed to delete saved view", err)
+	}
+	return nil
+}
+
+// GetSavedView retrieves a view the user asking can use
+func (s *savedViewServiceImpl) GetSavedView(ctx context.Context, id string) (*dto.SavedViewResponseDTO, error) {
+	view, err := s.getView(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	staff, err := s.viewer(ctx, view.BusinessID, "use saved views")
+	if err != nil {
+		return nil, err
+	}
+	if !view.VisibleTo(staff.UserID, staff.Role) {
+		return nil, NewNotFoundError("saved view", "id", id)
+	}
+	return dto.ToSavedViewResponseDTO(view, staff.UserID), nil
+}
+
+// ListSavedViews lists the views of a business the user asking can use: their own and those
+// shared with their role, ordered by name
+func (s *savedViewServiceImpl) ListSavedViews(ctx context.Context, businessID string, entity *domain.SavedViewEntity) ([]*dto.SavedViewResponseDTO, error) {
+	staff, err := s.viewer(ctx, businessID, "use saved views")
+	if err != nil {
+		return nil, err
+	}
+
+	views, err := s.savedViewRepo.FindVisible(ctx, businessID, staff.UserID, staff.Role, entity)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve saved views", err)
+	}
+	result := make([]*dto.SavedViewResponseDTO, len(views))
+	for i, view := range views {
+		result[i] = dto.ToSavedViewResponseDTO(view, staff.UserID)
+	}
+	return result, nil
+}
+
+// viewer returns the staff record of the user asking in a business
+func (s *savedViewServiceImpl) viewer(ctx context.Context, businessID, action string) (*domain.Staff, error) {
+	userID := GetUserIDFromContext(ctx)
+	if userID == nil {
+		return nil, NewForbiddenError(action)
+	}
+	staff, err := s.staffRepo.FindByBusinessAndUser(ctx, businessID, *userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewForbiddenError(action)
+		}
+		return nil, NewServiceError("failed to retrieve staff member", err)
+	}
+	return staff, nil
+}
+
+// getView retrieves a view by ID
+func (s *savedViewServiceImpl) getView(ctx context.Context, id string) (*domain.SavedView, error) {
+	view, err := s.savedViewRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("saved view", "id", id)
+		}
+		return nil, NewServiceError("failed to retrieve saved view", err)
+	}
+	return view, nil
+}
+
+// ownedView retrieves a view owned by the user asking, with their staff record
+func (s *savedViewServiceImpl) ownedView(ctx context.Context, id, action string) (*domain.SavedView, *domain.Staff, error) {
+	view, err := s.getView(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	staff, err := s.viewer(ctx, view.BusinessID, action)
+	if err != nil {
+		return nil, nil, err
+	}
+	if view.UserID != staff.UserID {
+		if view.VisibleTo(staff.UserID, staff.Role) {
+			return nil, nil, NewForbiddenError(action)
+		}
+		return nil, nil, NewNotFoundError("saved view", "id", id)
+	}
+	return view, staff, nil
+}
+
+// requireUniqueName checks that the owner has no other view of the list with the same name
+func (s *savedViewServiceImpl) requireUniqueName(ctx context.Context, view *domain.SavedView) error {
+	existing, err := s.savedViewRepo.FindByOwnerAndName(ctx, view.BusinessID, view.UserID, view.Entity, view.Name)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return NewServiceError("failed to check saved view name", err)
+	}
+	if existing != nil && existing.ID != view.ID {
+		return validation.NewValidationError(fmt.Sprintf("a view of %s named %q already exists", view.Entity, view.Name))
+	}
+	return nil
+}
@@ -0,0 +1,416 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/notification"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// scheduleImpactHorizon is how far ahead appointments are checked against a schedule change
+const scheduleImpactHorizon = 365 * 24 * time.Hour
+
+// ScheduleChangeService defines the service interface for changes to when a business and its
+// staff work, which may invalidate appointments already booked
+type ScheduleChangeService interface {
+	UpdateSchedulingSettings(ctx context.Context, updateDTO dto.UpdateSchedulingSettingsDTO) (*dto.ScheduleChangeResultDTO, error)
+	UpdateStaffAssignment(ctx context.Context, updateDTO dto.UpdateStaffAssignmentDTO) (*dto.ScheduleChangeResultDTO, error)
+	UpdateServiceBookingWindow(ctx context.Context, updateDTO dto.UpdateServiceBookingWindowDTO) (*dto.ServiceBookingWindowDTO, error)
+	UpdateServiceOnlineBooking(ctx context.Context, updateDTO dto.UpdateServiceOnlineBookingDTO) (*dto.ServiceOnlineBookingDTO, error)
+}
+
+// scheduleChangeServiceImpl implements the ScheduleChangeService interface
+type scheduleChangeServiceImpl struct {
+	businessRepo         domain.BusinessRepository
+	businessSettingsRepo domain.BusinessSettingsRepository
+	businessHoursRepo    domain.BusinessHoursRepository
+	serviceRepo          domain.BaseRepository[domain.Service]
+	staffRepo            domain.StaffRepository
+	userRepo             domain.BaseRepository[domain.User]
+	appointmentRepo      domain.AppointmentRepository
+	sender               notification.NotificationSender
+	validator            *validator.Validate
+}
+
+// NewScheduleChangeService creates a new schedule change service
+func NewScheduleChangeService(
+	businessRepo domain.BusinessRepository,
+	businessSettingsRepo domain.BusinessSettingsRepository,
+	businessHoursRepo domain.BusinessHoursRepository,
+	serviceRepo domain.BaseRepository[domain.Service],
+	staffRepo domain.StaffRepository,
+	userRepo domain.BaseRepository[domain.User],
+	appointmentRepo domain.AppointmentRepository,
+	sender notification.NotificationSender,
+	validator *validator.Validate,
+) ScheduleChangeService {
+	return &scheduleChangeServiceImpl{
+		businessRepo:         businessRepo,
+		businessSettingsRepo: businessSettingsRepo,
+		businessHoursRepo:    businessHoursRepo,
+		serviceRepo:          serviceRepo,
+		staffRepo:            staffRepo,
+		userRepo:             userRepo,
+		appointmentRepo:      appointmentRepo,
+		sender:               sender,
+		validator:            validator,
+	}
+}
+
+// UpdateSchedulingSettings changes the opening hours, appointment buffer, booking window or
+// deposit window of a business and lists the upcoming appointments the change invalidates; the
+// windows only apply to new bookings, so they never invalidate any. Unless it is a dry run, the change is
+// saved and the owner and the affected staff members are told which appointments to resolve
+// and how; the appointments themselves are left for them to reschedule, reassign or cancel.
+func (s *scheduleChangeServiceImpl) UpdateSchedulingSettings(ctx context.Context, updateDTO dto.UpdateSchedulingSettingsDTO) (*dto.ScheduleChangeResultDTO, error) {
+	if err := s.validator.Struct(updateDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	ctx, err := actFor(ctx, updateDTO.BusinessID, "change the business schedule")
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureManager(ctx, updateDTO.BusinessID); err != nil {
+		return nil, err
+	}
+
+	business, err := s.businessRepo.GetByID(ctx, updateDTO.BusinessID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("business", "id", updateDTO.BusinessID)
+		}
+		return nil, NewServiceError("failed to retrieve business", err)
+	}
+	settings, err := s.businessSettingsRepo.GetByBusinessID(ctx, business.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, NewServiceError("failed to retrieve business settings", err)
+	}
+	current := domain.NewAvailabilityRules(business, settings)
+
+	updatedBusiness := *business
+	updateDTO.BusinessHours.Apply(&updatedBusiness.BusinessHours)
+	hours, err := domain.ParseWeeklyHours(updatedBusiness.BusinessHours)
+	if err == nil {
+		err = hours.Validate()
+	}
+	if err != nil {
+		return nil, toValidationError(err)
+	}
+	updatedSettings := domain.BusinessSettings{BusinessID: business.ID, CalendarStartHour: 9, CalendarEndHour: 18,
+		DepositWindowHours: domain.DefaultDepositWindowHours}
+	if settings != nil {
+		updatedSettings = *settings
+	}
+	if updateDTO.CalendarStartHour != nil {
+		updatedSettings.CalendarStartHour = *updateDTO.CalendarStartHour
+	}
+	if updateDTO.CalendarEndHour != nil {
+		updatedSettings.CalendarEndHour = *updateDTO.CalendarEndHour
+	}
+	if updateDTO.AppointmentBufferMinutes != nil {
+		updatedSettings.AppointmentBufferMinutes = *updateDTO.AppointmentBufferMinutes
+	}
+	if updateDTO.MinBookingLeadHours != nil {
+		updatedSettings.MinBookingLeadHours = *updateDTO.MinBookingLeadHours
+	}
+	if updateDTO.MaxBookingHorizonDays != nil {
+		updatedSettings.MaxBookingHorizonDays = *updateDTO.MaxBookingHorizonDays
+	}
+	if updateDTO.DepositWindowHours != nil {
+		updatedSettings.DepositWindowHours = *updateDTO.DepositWindowHours
+	}
+	if updatedSettings.CalendarStartHour >= updatedSettings.CalendarEndHour {
+		return nil, validation.NewValidationError("calendar_end_hour must be after calendar_start_hour")
+	}
+	proposed := domain.NewAvailabilityRules(&updatedBusiness, &updatedSettings)
+
+	staff, err := s.staffRepo.FindActiveByBusinessID(ctx, business.ID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve staff", err)
+	}
+	staffIDs := make([]string, len(staff))
+	for i, member := range staff {
+		staffIDs[i] = member.ID
+	}
+	// Appointments under way still push their buffer into the upcoming ones
+	now := time.Now()
+	schedules, err := s.appointmentRepo.FindSchedules(ctx, staffIDs, now.Add(-max(current.Buffer, proposed.Buffer)), now.Add(scheduleImpactHorizon))
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve staff schedules", err)
+	}
+	impacted := domain.ScheduleImpact(current, proposed, schedules, now)
+	if updateDTO.DryRun {
+		return dto.ToScheduleChangeResultDTO(false, impacted, 0), nil
+	}
+
+	userID := GetUserIDFromContext(ctx)
+	if updateDTO.BusinessHours.Set {
+		rows := hours.Rows(business.ID, nil)
+		for _, row := range rows {
+			row.SetAuditFields(userID)
+		}
+		if err := s.businessHoursRepo.Replace(ctx, business.ID, nil, rows); err != nil {
+			return nil, NewServiceError("failed to update business hours", err)
+		}
+	}
+	updatedSettings.SetAuditFields(userID)
+	if settings == nil {
+		err = s.businessSettingsRepo.Create(ctx, &updatedSettings)
+	} else {
+		err = s.businessSettingsRepo.Update(ctx, &updatedSettings)
+	}
+	if err != nil {
+		return nil, NewServiceError("failed to update business settings", err)
+	}
+
+	notified := s.notify(ctx, &updatedBusiness, staff, impacted, proposed.Location)
+	return dto.ToScheduleChangeResultDTO(true, impacted, notified), nil
+}
+
+// UpdateStaffAssignment deactivates a staff member or sets the date they leave the business, and
+// lists their appointments from then on, which someone else must take or which must be
+// cancelled. Unless it is a dry run, the change is saved and the owner and the staff member
+// are notified.
+func (s *scheduleChangeServiceImpl) UpdateStaffAssignment(ctx context.Context, updateDTO dto.UpdateStaffAssignmentDTO) (*dto.ScheduleChangeResultDTO, error) {
+	if err := s.validator.Struct(updateDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+
+	staff, err := s.staffRepo.GetByID(ctx, updateDTO.StaffID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("staff", "id", updateDTO.StaffID)
+		}
+		return nil, NewServiceError("failed to retrieve staff member", err)
+	}
+	ctx, err = actFor(ctx, staff.BusinessID, "change the business schedule")
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureManager(ctx, staff.BusinessID); err != nil {
+		return nil, err
+	}
+	business, err := s.businessRepo.GetByID(ctx, staff.BusinessID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve business", err)
+	}
+
+	updated := *staff
+	if updateDTO.IsActive != nil {
+		updated.IsActive = *updateDTO.IsActive
+	}
+	updateDTO.EndDate.Apply(&updated.EndDate)
+	if updated.EndDate != nil && updated.StartDate != nil && updated.EndDate.Before(*updated.StartDate) {
+		return nil, validation.NewValidationError("end_date must not be before start_date")
+	}
+
+	now := time.Now()
+	var impacted []*domain.ImpactedAppointment
+	if from, leaving := leavingFrom(&updated, now); leaving {
+		schedules, err := s.appointmentRepo.FindSchedules(ctx, []string{staff.ID}, from, from.Add(scheduleImpactHorizon))
+		if err != nil {
+			return nil, NewServiceError("failed to retrieve staff schedule", err)
+		}
+		impacted = domain.UnassignedImpact(schedules[0], from)
+	}
+	if updateDTO.DryRun {
+		return dto.ToScheduleChangeResultDTO(false, impacted, 0), nil
+	}
+
+	updated.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.staffRepo.Update(ctx, &updated); err != nil {
+		return nil, NewServiceError("failed to update staff member", err)
+	}
+
+	rules := domain.NewAvailabilityRules(business, nil)
+	notified := s.notify(ctx, business, []*domain.Staff{&updated}, impacted, rules.Location)
+	return dto.ToScheduleChangeResultDTO(true, impacted, notified), nil
+}
+
+// leavingFrom returns when a staff member stops taking appointments: now if they are inactive,
+// or their end date, and whether they are leaving at all
+func leavingFrom(staff *domain.Staff, now time.Time) (time.Time, bool) {
+	switch {
+	case !staff.IsActive:
+		return now, true
+	case staff.EndDate != nil && staff.EndDate.After(now):
+		return *staff.EndDate, true
+	case staff.EndDate != nil:
+		return now, true
+	}
+	return time.Time{}, false
+}
+
+// notify sends the owner the list of impacted appointments and each affected staff member
+// their own, with the ways to resolve them, and returns how many notifications were sent.
+// A change is already saved when it is notified, so delivery failures are only logged.
+func (s *scheduleChangeServiceImpl) notify(ctx context.Context, business *domain.Business, staff []*domain.Staff, impacted []*domain.ImpactedAppointment, location *time.Location) int {
+	if len(impacted) == 0 {
+		return 0
+	}
+	subject := "Appointments to review at " + business.GetDisplayName()
+	intro := fmt.Sprintf("A schedule change at %s affects %d upcoming appointment(s):", business.GetDisplayName(), len(impacted))
+	sent := 0
+	send := func(recipient string, appointments []*domain.ImpactedAppointment) {
+		lines := make([]string, len(appointments))
+		for i, appointment := range appointments {
+			lines[i] = describeImpactedAppointment(appointment, location)
+		}
+		err := s.sender.Send(ctx, notification.Message{
+			BusinessID: business.ID,
+			Channel:    domain.MessageChannelEmail,
+			Recipient:  recipient,
+			Subject:    &subject,
+			Body:       intro + "\n\n- " + strings.Join(lines, "\n- "),
+		})
+		if err != nil {
+			log.Error().Err(err).Str("business_id", business.ID).Msg("Failed to send schedule change notification")
+			return
+		}
+		sent++
+	}
+	send(business.Email, impacted)
+
+	byStaff := map[string][]*domain.ImpactedAppointment{}
+	for _, appointment := range impacted {
+		byStaff[appointment.StaffID] = append(byStaff[appointment.StaffID], appointment)
+	}
+	var userIDs []string
+	for _, member := range staff {
+		if len(byStaff[member.ID]) > 0 && !member.IsOwner() {
+			userIDs = append(userIDs, member.UserID)
+		}
+	}
+	if len(userIDs) == 0 {
+		return sent
+	}
+	users, err := s.userRepo.GetByIDs(ctx, userIDs)
+	if err != nil {
+		log.Error().Err(err).Str("business_id", business.ID).Msg("Failed to retrieve staff to notify of schedule change")
+		return sent
+	}
+	emails := make(map[string]string, len(users))
+	for _, user := range users {
+		emails[user.ID] = user.Email
+	}
+	for _, member := range staff {
+		if email := emails[member.UserID]; email != "" && len(byStaff[member.ID]) > 0 {
+			send(email, byStaff[member.ID])
+		}
+	}
+	return sent
+}
+
+// describeImpactedAppointment renders an impacted appointment as a line of the notification
+func describeImpactedAppointment(appointment *domain.ImpactedAppointment, location *time.Location) string {
+	reasons := make([]string, len(appointment.Conflicts))
+	for i, conflict := range appointment.Conflicts {
+		reasons[i] = conflict.Message
+	}
+	resolutions := make([]string, len(appointment.Resolutions))
+	for i, resolution := range appointment.Resolutions {
+		resolutions[i] = string(resolution)
+	}
+	return fmt.Sprintf("%s to %s: %s (you can %s)",
+		appointment.StartTime.In(location).Format("Monday 2 January 15:04"), appointment.EndTime.In(location).Format("15:04"),
+		strings.Join(reasons, "; "), strings.Join(resolutions, ", "))
+}
+
+// UpdateServiceBookingWindow changes how far ahead a service can be booked, overriding the
+// business's lead time and horizon. Like the business's, the limits only apply to new bookings.
+func (s *scheduleChangeServiceImpl) UpdateServiceBookingWindow(ctx context.Context, updateDTO dto.UpdateServiceBookingWindowDTO) (*dto.ServiceBookingWindowDTO, error) {
+	if err := s.validator.Struct(updateDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+
+	service, err := s.serviceRepo.GetByID(ctx, updateDTO.ServiceID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("service", "id", updateDTO.ServiceID)
+		}
+		return nil, NewServiceError("failed to retrieve service", err)
+	}
+	ctx, err = actFor(ctx, service.BusinessID, "change the business schedule")
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureManager(ctx, service.BusinessID); err != nil {
+		return nil, err
+	}
+	if err := service.CheckVersion(service.TableName(), updateDTO.ExpectedVersion); err != nil {
+		return nil, err
+	}
+
+	updateDTO.MinLeadHours.Apply(&service.MinAdvanceBooking)
+	updateDTO.MaxHorizonDays.Apply(&service.MaxAdvanceBooking)
+	if err := service.Validate(); err != nil {
+		return nil, toValidationError(err)
+	}
+	service.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.serviceRepo.Update(ctx, service); err != nil {
+		return nil, NewServiceError("failed to update service", err)
+	}
+
+	settings, err := s.businessSettingsRepo.GetByBusinessID(ctx, service.BusinessID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, NewServiceError("failed to retrieve business settings", err)
+	}
+	return dto.ToServiceBookingWindowDTO(service, domain.NewBookingWindow(settings, service)), nil
+}
+
+// UpdateServiceOnlineBooking changes whether a service can be booked online, is listed for
+// clients to call and book, or is not offered online, along with the rules for booking it
+// online. The rules only apply to online bookings; staff can still book the service for anyone.
+func (s *scheduleChangeServiceImpl) UpdateServiceOnlineBooking(ctx context.Context, updateDTO dto.UpdateServiceOnlineBookingDTO) (*dto.ServiceOnlineBookingDTO, error) {
+	if err := s.validator.Struct(updateDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+
+	service, err := s.serviceRepo.GetByID(ctx, updateDTO.ServiceID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("service", "id", updateDTO.ServiceID)
+		}
+		return nil, NewServiceError("failed to retrieve service", err)
+	}
+	ctx, err = actFor(ctx, service.BusinessID, "change the business schedule")
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureManager(ctx, service.BusinessID); err != nil {
+		return nil, err
+	}
+	if err := service.CheckVersion(service.TableName(), updateDTO.ExpectedVersion); err != nil {
+		return nil, err
+	}
+
+	if updateDTO.OnlineBooking != nil {
+		service.OnlineBooking = *updateDTO.OnlineBooking
+	}
+	if updateDTO.ReturningClientsOnly != nil {
+		service.ReturningClientsOnly = *updateDTO.ReturningClientsOnly
+	}
+	updateDTO.MaxPerDay.Apply(&service.MaxPerDay)
+	if err := service.Validate(); err != nil {
+		return nil, toValidationError(err)
+	}
+	service.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.serviceRepo.Update(ctx, service); err != nil {
+		return nil, NewServiceError("failed to update service", err)
+	}
+	return dto.ToServiceOnlineBookingDTO(service), nil
+}
+
+// ensureManager checks that the caller is an owner or manager of the business
+func (s *scheduleChangeServiceImpl) ensureManager(ctx context.Context, businessID string) error {
+	return requireManager(ctx, s.staffRepo, businessID, "change the business schedule")
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// SearchService defines the service interface for searching a business's clients and services
+type SearchService interface {
+	Search(ctx context.Context, searchDTO dto.SearchDTO) ([]*dto.SearchResultDTO, error)
+}
+
+// searchServiceImpl implements the SearchService interface
+type searchServiceImpl struct {
+	searchRepo domain.SearchRepository
+	staffRepo  domain.StaffRepository
+	validator  *validator.Validate
+}
+
+// NewSearchService creates a new search service
+func NewSearchService(
+	searchRepo domain.SearchRepository,
+	staffRepo domain.StaffRepository,
+	validator *validator.Validate,
+) SearchService {
+	return &searchServiceImpl{
+		searchRepo: searchRepo,
+		staffRepo:  staffRepo,
+		validator:  validator,
+	}
+}
+
+// searchPermissions are the permissions needed to find each kind of record
+var searchPermissions = map[domain.SearchResultKind]domain.Permission{
+	domain.SearchResultClient:  domain.PermissionClientsView,
+	domain.SearchResultService: domain.PermissionServicesView,
+}
+
+// Search finds the clients and services of a business matching a query, best match first. The
+// caller must be on the business's staff, and only finds the kinds of record they may view.
+func (s *searchServiceImpl) Search(ctx context.Context, searchDTO dto.SearchDTO) ([]*dto.SearchResultDTO, error) {
+	if err := s.validator.Struct(searchDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+
+	userID := GetUserIDFromContext(ctx)
+	if userID == nil {
+		return nil, NewForbiddenError("search")
+	}
+	staff, err := s.staffRepo.FindByBusinessAndUser(ctx, searchDTO.BusinessID, *userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewForbiddenError("search")
+		}
+		return nil, NewServiceError("failed to retrieve staff member", err)
+	}
+
+	requested := searchDTO.Kinds
+	if len(requested) == 0 {
+		requested = domain.SearchResultKinds
+	}
+	var kinds []domain.SearchResultKind
+	for _, kind := range requested {
+		if staff.Can(searchPermissions[kind]) {
+			kinds = append(kinds, kind)
+		} else if len(searchDTO.Kinds) > 0 {
+			return nil, NewForbiddenError("search " + string(kind) + "s")
+		}
+	}
+	if len(kinds) == 0 {
+		return nil, NewForbiddenError("search")
+	}
+
+	query, err := domain.NewSearchQuery(searchDTO.BusinessID, searchDTO.Query, kinds, searchDTO.Limit)
+	if err != nil {
+		return nil, toValidationError(err)
+	}
+	results, err := s.searchRepo.Search(ctx, query)
+	if err != nil {
+		return nil, NewServiceError("failed to search", err)
+	}
+
+	return dto.ToSearchResultDTOs(results), nil
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// ServiceBundleService defines the service interface for service bundles
+type ServiceBundleService interface {
+	CreateBundle(ctx context.Context, createDTO dto.CreateServiceBundleDTO) (*dto.ServiceBundleResponseDTO, error)
+	UpdateBundle(ctx context.Context, id string, updateDTO dto.UpdateServiceBundleDTO) (*dto.ServiceBundleResponseDTO, error)
+	GetBundle(ctx context.Context, id string) (*dto.ServiceBundleResponseDTO, error)
+	ListBundles(ctx context.Context, businessID string, activeOnly bool) ([]*dto.ServiceBundleResponseDTO, error)
+	DeleteBundle(ctx context.Context, id string) error
+}
+
+// serviceBundleServiceImpl implements the ServiceBundleService interface
+type serviceBundleServiceImpl struct {
+	bundleRepo domain.ServiceBundleRepository
+	validator  *validator.Validate
+}
+
+// NewServiceBundleService creates a new service bundle service
+func NewServiceBundleService(bundleRepo domain.ServiceBundleRepository, validator *validator.Validate) ServiceBundleService {
+	return &serviceBundleServiceImpl{
+		bundleRepo: bundleRepo,
+		validator:  validator,
+	}
+}
+
+// CreateBundle creates a service bundle from services of the business
+func (s *serviceBundleServiceImpl) CreateBundle(ctx context.Context, createDTO dto.CreateServiceBundleDTO) (*dto.ServiceBundleResponseDTO, error) {
+	if err := s.validator.Struct(createDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+
+	bundle := &domain.ServiceBundle{
+		BusinessID:  createDTO.BusinessID,
+		Name:        strings.TrimSpace(createDTO.Name),
+		Description: createDTO.Description,
+		Price:       createDTO.Price,
+		IsActive:    createDTO.IsActive,
+	}
+	if err := bundle.SetItems(dto.ToBundleItems(createDTO.Items)); err != nil {
+		return nil, NewServiceError("failed to encode bundle items", err)
+	}
+	plan, err := s.validateBundle(ctx, bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.bundleRepo.Create(ctx, bundle); err != nil {
+		return nil, NewServiceError("failed to create service bundle", err)
+	}
+
+	return dto.ToServiceBundleResponseDTO(bundle, plan), nil
+}
+
+// UpdateBundle updates a service bundle
+func (s *serviceBundleServiceImpl) UpdateBundle(ctx context.Context, id string, updateDTO dto.UpdateServiceBundleDTO) (*dto.ServiceBundleResponseDTO, error) {
+	if err := s.validator.Struct(updateDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+
+	bundle, err := s.getBundle(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if updateDTO.Name != nil {
+		bundle.Name = strings.TrimSpace(*updateDTO.Name)
+	}
+	updateDTO.Description.Apply(&bundle.Description)
+	if updateDTO.Price != nil {
+		bundle.Price = *updateDTO.Price
+	}
+	if updateDTO.IsActive != nil {
+		bundle.IsActive = *updateDTO.IsActive
+	}
+	if updateDTO.Items != nil {
+		if err := bundle.SetItems(dto.ToBundleItems(updateDTO.Items)); err != nil {
+			return nil, NewServiceError("failed to encode bundle items", err)
+		}
+	}
+	plan, err := s.validateBundle(ctx, bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.bundleRepo.Update(ctx, bundle); err != nil {
+		return nil, NewServiceError("failed to update service bundle", err)
+	}
+
+	return dto.ToServiceBundleResponseDTO(bundle, plan), nil
+}
+
+// GetBundle retrieves a service bundle with its schedule and pricing
+func (s *serviceBundleServiceImpl) GetBundle(ctx context.Context, id string) (*dto.ServiceBundleResponseDTO, error) {
+	bundle, err := s.getBundle(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := planBundle(ctx, s.bundleRepo, bundle)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToServiceBundleResponseDTO(bundle, plan), nil
+}
+
+// ListBundles retrieves the bundles of a business. Bundles whose services can no longer be
+// scheduled, e.g. because one was deleted, are listed without a schedule.
+func (s *serviceBundleServiceImpl) ListBundles(ctx context.Context, businessID string, activeOnly bool) ([]*dto.ServiceBundleResponseDTO, error) {
+	if businessID == "" {
+		return nil, validation.NewValidationError("business_id is required")
+	}
+	bundles, err := s.bundleRepo.FindByBusinessID(ctx, businessID, activeOnly)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve service bundles", err)
+	}
+
+	result := make([]*dto.ServiceBundleResponseDTO, 0, len(bundles))
+	for _, bundle := range bundles {
+		plan, err := planBundle(ctx, s.bundleRepo, bundle)
+		if err != nil {
+			log.Warn().Err(err).Str("bundle_id", bundle.ID).Msg("Failed to plan service bundle")
+			plan = nil
+		}
+		result = append(result, dto.ToServiceBundleResponseDTO(bundle, plan))
+	}
+	return result, nil
+}
+
+// DeleteBundle deletes a service bundle. Appointments booked from it keep their services.
+func (s *serviceBundleServiceImpl) DeleteBundle(ctx context.Context, id string) error {
+	if _, err := s.getBundle(ctx, id); err != nil {
+		return err
+	}
+	if err := s.bundleRepo.Delete(ctx, id); err != nil {
+		return NewServiceError("failed to delete service bundle", err)
+	}
+	return nil
+}
+
+// getBundle retrieves a service bundle, mapping a missing record to a not found error
+func (s *serviceBundleServiceImpl) getBundle(ctx context.Context, id string) (*domain.ServiceBundle, error) {
+	bundle, err := s.bundleRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("service bundle", "id", id)
+		}
+		return nil, NewServiceError("failed to retrieve service bundle", err)
+	}
+	return bundle, nil
+}
+
+// validateBundle checks the bundle, the uniqueness of its name and that all its services can
+// be scheduled, returning its plan
+func (s *serviceBundleServiceImpl) validateBundle(ctx context.Context, bundle *domain.ServiceBundle) (*domain.BundlePlan, error) {
+	if err := bundle.Validate(); err != nil {
+		return nil, toValidationError(err)
+	}
+
+	var excludeID *string
+	if bundle.ID != "" {
+		excludeID = &bundle.ID
+	}
+	exists, err := s.bundleRepo.ExistsByNameAndBusiness(ctx, bundle.Name, bundle.BusinessID, excludeID)
+	if err != nil {
+		return nil, NewServiceError("failed to check bundle name", err)
+	}
+	if exists {
+		return nil, validation.NewValidationError("a bundle with this name already exists")
+	}
+
+	return planBundle(ctx, s.bundleRepo, bundle)
+}
+
+// planBundle loads a bundle's services and schedules them
+func planBundle(ctx context.Context, bundleRepo domain.ServiceBundleRepository, bundle *domain.ServiceBundle) (*domain.BundlePlan, error) {
+	services, err := bundleRepo.FindServices(ctx, bundle.BusinessID, bundle.ServiceIDs())
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve bundle services", err)
+	}
+	plan, err := bundle.Plan(services)
+	if err != nil {
+		return nil, toValidationError(err)
+	}
+	return plan, nil
+}
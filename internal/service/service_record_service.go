@@ -0,0 +1,572 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// ServiceRecordService defines the service interface for client service history operations
+type ServiceRecordService interface {
+	domain.BaseService[dto.CreateServiceRecordDTO, dto.UpdateServiceRecordDTO, dto.ServiceRecordResponseDTO]
+	CreateTemplate(ctx context.Context, createDTO dto.CreateServiceRecordTemplateDTO) (*dto.ServiceRecordTemplateResponseDTO, error)
+	UpdateTemplate(ctx context.Context, id string, updateDTO dto.UpdateServiceRecordTemplateDTO) (*dto.ServiceRecordTemplateResponseDTO, error)
+	DeleteTemplate(ctx context.Context, id string) error
+	GetTemplate(ctx context.Context, id string) (*dto.ServiceRecordTemplateResponseDTO, error)
+	ListTemplates(ctx context.Context, businessID string) ([]*dto.ServiceRecordTemplateResponseDTO, error)
+	GetClientHistory(ctx context.Context, clientID string, page, pageSize int) ([]*dto.ServiceRecordResponseDTO, int64, error)
+	GetByAppointment(ctx context.Context, appointmentID string) ([]*dto.ServiceRecordResponseDTO, error)
+	GetLatestForClient(ctx context.Context, clientID string, serviceID *string) (*dto.ServiceRecordResponseDTO, error)
+	SearchHistory(ctx context.Context, businessID string, search dto.ServiceRecordSearchDTO, page, pageSize int) ([]*dto.ServiceRecordResponseDTO, int64, error)
+	CopyForward(ctx context.Context, recordID, appointmentID string) (*dto.ServiceRecordResponseDTO, error)
+}
+
+// serviceRecordServiceImpl implements the ServiceRecordService interface
+type serviceRecordServiceImpl struct {
+	*BaseServiceImpl[domain.ServiceRecord, dto.CreateServiceRecordDTO, dto.UpdateServiceRecordDTO, dto.ServiceRecordResponseDTO]
+	recordRepo      domain.ServiceRecordRepository
+	templateRepo    domain.ServiceRecordTemplateRepository
+	completionRepo  domain.ServiceCompletionRepository
+	serviceRepo     domain.BaseRepository[domain.Service]
+	appointmentRepo domain.BaseRepository[domain.Appointment]
+	clientRepo      domain.BaseRepository[domain.Client]
+	staffRepo       domain.StaffRepository
+	validator       *validator.Validate
+}
+
+// NewServiceRecordService creates a new service record service
+func NewServiceRecordService(
+	recordRepo domain.ServiceRecordRepository,
+	templateRepo domain.ServiceRecordTemplateRepository,
+	completionRepo domain.ServiceCompletionRepository,
+	serviceRepo domain.BaseRepository[domain.Service],
+	appointmentRepo domain.BaseRepository[domain.Appointment],
+	clientRepo domain.BaseRepository[domain.Client],
+	staffRepo domain.StaffRepository,
+	validator *validator.Validate,
+) ServiceRecordService {
+	return &serviceRecordServiceImpl{
+		BaseServiceImpl: NewBaseService(
+			recordRepo,
+			func(createDTO dto.CreateServiceRecordDTO) (*domain.ServiceRecord, error) {
+				record := &domain.ServiceRecord{
+					BusinessID:          createDTO.BusinessID,
+					ClientID:            createDTO.ClientID,
+					AppointmentID:       createDTO.AppointmentID,
+					ServiceCompletionID: createDTO.ServiceCompletionID,
+					ServiceID:           createDTO.ServiceID,
+					StaffID:             createDTO.StaffID,
+					TemplateID:          createDTO.TemplateID,
+					RecordedAt:          time.Now(),
+					ProcessingTime:      createDTO.ProcessingTime,
+					Notes:               createDTO.Notes,
+					IsDraft:             createDTO.IsDraft,
+				}
+				if createDTO.RecordedAt != nil {
+					record.RecordedAt = *createDTO.RecordedAt
+				}
+				if err := record.SetFieldValues(createDTO.FieldValues); err != nil {
+					return nil, err
+				}
+				if err := record.SetProductsUsed(createDTO.ProductsUsed); err != nil {
+					return nil, err
+				}
+				return record, record.Validate()
+			},
+			func(entity *domain.ServiceRecord, updateDTO dto.UpdateServiceRecordDTO) error {
+				updateDTO.StaffID.Apply(&entity.StaffID)
+				if updateDTO.FieldValues != nil {
+					if err := entity.SetFieldValues(updateDTO.FieldValues); err != nil {
+						return err
+					}
+				}
+				if updateDTO.ProductsUsed != nil {
+					if err := entity.SetProductsUsed(updateDTO.ProductsUsed); err != nil {
+						return err
+					}
+				}
+				updateDTO.ProcessingTime.Apply(&entity.ProcessingTime)
+				updateDTO.Notes.Apply(&entity.Notes)
+				if updateDTO.IsDraft != nil {
+					entity.IsDraft = *updateDTO.IsDraft
+				}
+				return entity.Validate()
+			},
+			func(entity *domain.ServiceRecord) *dto.ServiceRecordResponseDTO {
+				return dto.ToServiceRecordResponseDTO(entity)
+			},
+		),
+		recordRepo:      recordRepo,
+		templateRepo:    templateRepo,
+		completionRepo:  completionRepo,
+		serviceRepo:     serviceRepo,
+		appointmentRepo: appointmentRepo,
+		clientRepo:      clientRepo,
+		staffRepo:       staffRepo,
+		validator:       validator,
+	}
+}
+
+// Create records a service for a client, attaching it to the appointment's completion and
+// validating the recorded values against the template for the service's category
+func (s *serviceRecordServiceImpl) Create(ctx context.Context, createDTO dto.CreateServiceRecordDTO) (*dto.ServiceRecordResponseDTO, error) {
+	if err := s.validator.Struct(createDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+
+	record, err := s.createConverter(createDTO)
+	if err != nil {
+		return nil, toValidationError(err)
+	}
+
+	if err := s.attachCompletion(ctx, record); err != nil {
+		return nil, err
+	}
+
+	template, err := s.resolveTemplate(ctx, record)
+	if err != nil {
+		return nil, err
+	}
+	if template != nil {
+		record.TemplateID = &template.ID
+		if err := record.ValidateAgainstTemplate(template); err != nil {
+			return nil, toValidationError(err)
+		}
+	}
+
+	record.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.recordRepo.Create(ctx, record); err != nil {
+		return nil, NewServiceError("failed to create service record", err)
+	}
+
+	return dto.ToServiceRecordResponseDTO(record), nil
+}
+
+// GetByID retrieves a service record by ID
+func (s *serviceRecordServiceImpl) GetByID(ctx context.Context, id string) (*dto.ServiceRecordResponseDTO, error) {
+	record, err := s.getRecord(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToServiceRecordResponseDTO(record), nil
+}
+
+// Update updates a service record and re-validates it against its template
+func (s *serviceRecordServiceImpl) Update(ctx context.Context, id string, updateDTO dto.UpdateServiceRecordDTO) (*dto.ServiceRecordResponseDTO, error) {
+	if err := s.validator.Struct(updateDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+
+	record, err := s.getRecord(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := domain.NewChangeSet(updateDTO)
+	if err != nil {
+		return nil, NewServiceError("failed to read service record changes", err)
+	}
+	if err := s.updateConverter(record, updateDTO); err != nil {
+		return nil, toValidationError(err)
+	}
+
+	if record.TemplateID != nil {
+		template, err := s.templateRepo.GetByID(ctx, *record.TemplateID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewServiceError("failed to retrieve service record template", err)
+		}
+		if template != nil {
+			if err := record.ValidateAgainstTemplate(template); err != nil {
+				return nil, toValidationError(err)
+			}
+		}
+	}
+
+	if changes.IsEmpty() {
+		return dto.ToServiceRecordResponseDTO(record), nil
+	}
+	if err := changes.Capture(record); err != nil {
+		return nil, NewServiceError("failed to read service record changes", err)
+	}
+	updated, err := s.recordRepo.UpdateFields(ctx, id, changes)
+	if err != nil {
+		return nil, NewServiceError("failed to update service record", err)
+	}
+
+	return dto.ToServiceRecordResponseDTO(updated), nil
+}
+
+// CreateTemplate creates a service record template
+func (s *serviceRecordServiceImpl) CreateTemplate(ctx context.Context, createDTO dto.CreateServiceRecordTemplateDTO) (*dto.ServiceRecordTemplateResponseDTO, error) {
+	if err := s.validator.Struct(createDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+
+	template := &domain.ServiceRecordTemplate{
+		BusinessID:  createDTO.BusinessID,
+		CategoryID:  createDTO.CategoryID,
+		Name:        strings.TrimSpace(createDTO.Name),
+		Description: createDTO.Description,
+		IsActive:    true,
+	}
+	if err := template.SetFields(createDTO.Fields); err != nil {
+		return nil, NewServiceError("failed to encode template fields", err)
+	}
+	if err := template.Validate(); err != nil {
+		return nil, toValidationError(err)
+	}
+
+	template.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.templateRepo.Create(ctx, template); err != nil {
+		return nil, NewServiceError("failed to create service record template", err)
+	}
+
+	return dto.ToServiceRecordTemplateResponseDTO(template), nil
+}
+
+// UpdateTemplate updates a service record template. Existing records keep the values
+// they were captured with; the new definition applies to records created or edited afterwards.
+func (s *serviceRecordServiceImpl) UpdateTemplate(ctx context.Context, id string, updateDTO dto.UpdateServiceRecordTemplateDTO) (*dto.ServiceRecordTemplateResponseDTO, error) {
+	if err := s.validator.Struct(updateDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+
+	template, err := s.getTemplate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updateDTO.CategoryID.Apply(&template.CategoryID)
+	if updateDTO.Name != nil {
+		template.Name = strings.TrimSpace(*updateDTO.Name)
+	}
+	updateDTO.Description.Apply(&template.Description)
+	if updateDTO.Fields != nil {
+		if err := template.SetFields(updateDTO.Fields); err != nil {
+			return nil, NewServiceError("failed to encode template fields", err)
+		}
+	}
+	if updateDTO.IsActive != nil {
+		template.IsActive = *updateDTO.IsActive
+	}
+	if err := template.Validate(); err != nil {
+		return nil, toValidationError(err)
+	}
+
+	template.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.templateRepo.Update(ctx, template); err != nil {
+		return nil, NewServiceError("failed to update service record template", err)
+	}
+
+	return dto.ToServiceRecordTemplateResponseDTO(template), nil
+}
+
+// DeleteTemplate deletes a service record template
+func (s *serviceRecordServiceImpl) DeleteTemplate(ctx context.Context, id string) error {
+	if _, err := s.getTemplate(ctx, id); err != nil {
+		return err
+	}
+	if err := s.templateRepo.Delete(ctx, id); err != nil {
+		return NewServiceError("failed to delete service record template", err)
+	}
+	return nil
+}
+
+// GetTemplate retrieves a service record template by ID
+func (s *serviceRecordServiceImpl) GetTemplate(ctx context.Context, id string) (*dto.ServiceRecordTemplateResponseDTO, error) {
+	template, err := s.getTemplate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToServiceRecordTemplateResponseDTO(template), nil
+}
+
+// ListTemplates lists the service record templates for a business
+func (s *serviceRecordServiceImpl) ListTemplates(ctx context.Context, businessID string) ([]*dto.ServiceRecordTemplateResponseDTO, error) {
+	if businessID == "" {
+		return nil, validation.NewValidationError("business_id is required")
+	}
+
+	templates, err := s.templateRepo.FindByBusinessID(ctx, businessID)
+	if err != nil {
+		return nil, NewServiceError("failed to list service record templates", err)
+	}
+
+	return dto.ToServiceRecordTemplateResponseDTOs(templates), nil
+}
+
+// GetClientHistory retrieves a client's service records, most recent first. Only staff of the
+// client's business can see them.
+func (s *serviceRecordServiceImpl) GetClientHistory(ctx context.Context, clientID string, page, pageSize int) ([]*dto.ServiceRecordResponseDTO, int64, error) {
+	ctx, err := s.actForClient(ctx, clientID, "view client service history")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	pagination := ValidatePagination(&dto.PaginationRequest{Page: page, PageSize: pageSize})
+	records, total, err := s.recordRepo.FindByClientID(ctx, clientID, pagination.Page, pagination.PageSize)
+	if err != nil {
+		return nil, 0, NewServiceError("failed to retrieve client service history", err)
+	}
+
+	return dto.ToServiceRecordResponseDTOs(records), total, nil
+}
+
+// GetByAppointment retrieves the service records captured for an appointment. Only staff of the
+// appointment's business can see them.
+func (s *serviceRecordServiceImpl) GetByAppointment(ctx context.Context, appointmentID string) ([]*dto.ServiceRecordResponseDTO, error) {
+	if appointmentID == "" {
+		return nil, validation.NewValidationError("appointment_id is required")
+	}
+	appointment, err := s.appointmentRepo.GetByID(ctx, appointmentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("appointment", "id", appointmentID)
+		}
+		return nil, NewServiceError("failed to retrieve appointment", err)
+	}
+	if err := requireStaff(ctx, s.staffRepo, appointment.BusinessID, "view appointment service records"); err != nil {
+		return nil, err
+	}
+	if ctx, err = actFor(ctx, appointment.BusinessID, "view appointment service records"); err != nil {
+		return nil, err
+	}
+
+	records, err := s.recordRepo.FindByAppointmentID(ctx, appointmentID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve appointment service records", err)
+	}
+
+	return dto.ToServiceRecordResponseDTOs(records), nil
+}
+
+// GetLatestForClient retrieves the client's most recent record, optionally for a specific
+// service. Only staff of the client's business can see it.
+func (s *serviceRecordServiceImpl) GetLatestForClient(ctx context.Context, clientID string, serviceID *string) (*dto.ServiceRecordResponseDTO, error) {
+	ctx, err := s.actForClient(ctx, clientID, "view client service history")
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := s.recordRepo.FindLatestByClient(ctx, clientID, serviceID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("service record", "client_id", clientID)
+		}
+		return nil, NewServiceError("failed to retrieve latest service record", err)
+	}
+
+	return dto.ToServiceRecordResponseDTO(record), nil
+}
+
+// SearchHistory searches a business's service record history by client, service, staff,
+// date range and free text across notes, recorded values and products used
+func (s *serviceRecordServiceImpl) SearchHistory(ctx context.Context, businessID string, search dto.ServiceRecordSearchDTO, page, pageSize int) ([]*dto.ServiceRecordResponseDTO, int64, error) {
+	if businessID == "" {
+		return nil, 0, validation.NewValidationError("business_id is required")
+	}
+	if err := s.validator.Struct(search); err != nil {
+		return nil, 0, validation.FromError(err)
+	}
+
+	criteria := domain.ServiceRecordSearch{
+		ClientID:      search.ClientID,
+		ServiceID:     search.ServiceID,
+		StaffID:       search.StaffID,
+		Query:         strings.TrimSpace(search.Query),
+		IncludeDrafts: search.IncludeDrafts,
+	}
+	if search.StartDate != nil || search.EndDate != nil {
+		dateRange := &domain.DateRange{Start: time.Time{}, End: time.Now().AddDate(100, 0, 0)}
+		if search.StartDate != nil {
+			dateRange.Start = *search.StartDate
+		}
+		if search.EndDate != nil {
+			dateRange.End = *search.EndDate
+		}
+		if !dateRange.End.After(dateRange.Start) {
+			return nil, 0, validation.NewValidationError("end_date must be after start_date")
+		}
+		criteria.DateRange = dateRange
+	}
+
+	pagination := ValidatePagination(&dto.PaginationRequest{Page: page, PageSize: pageSize})
+	records, total, err := s.recordRepo.Search(ctx, businessID, criteria, pagination.Page, pagination.PageSize)
+	if err != nil {
+		return nil, 0, NewServiceError("failed to search service records", err)
+	}
+
+	return dto.ToServiceRecordResponseDTOs(records), total, nil
+}
+
+// CopyForward copies a service record onto a follow-up appointment as a draft, so the
+// previous formula can be adjusted during the next visit instead of re-entered
+func (s *serviceRecordServiceImpl) CopyForward(ctx context.Context, recordID, appointmentID string) (*dto.ServiceRecordResponseDTO, error) {
+	if recordID == "" {
+		return nil, validation.NewValidationError("record_id is required")
+	}
+	if appointmentID == "" {
+		return nil, validation.NewValidationError("appointment_id is required")
+	}
+
+	source, err := s.getRecord(ctx, recordID)
+	if err != nil {
+		return nil, err
+	}
+
+	appointment, err := s.appointmentRepo.GetByID(ctx, appointmentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("appointment", "id", appointmentID)
+		}
+		return nil, NewServiceError("failed to retrieve appointment", err)
+	}
+	if appointment.ClientID != source.ClientID || appointment.BusinessID != source.BusinessID {
+		return nil, validation.NewValidationError("appointment belongs to a different client")
+	}
+
+	record := source.CopyForward(appointmentID)
+	record.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.recordRepo.Create(ctx, record); err != nil {
+		return nil, NewServiceError("failed to copy service record forward", err)
+	}
+
+	return dto.ToServiceRecordResponseDTO(record), nil
+}
+
+// attachCompletion links the record to the appointment's completion, or checks that an
+// explicitly provided completion belongs to the record's appointment
+func (s *serviceRecordServiceImpl) attachCompletion(ctx context.Context, record *domain.ServiceRecord) error {
+	if record.ServiceCompletionID != nil {
+		completion, err := s.completionRepo.GetByID(ctx, *record.ServiceCompletionID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return NewNotFoundError("service completion", "id", *record.ServiceCompletionID)
+			}
+			return NewServiceError("failed to retrieve service completion", err)
+		}
+		if record.AppointmentID == nil {
+			record.AppointmentID = &completion.AppointmentID
+		} else if *record.AppointmentID != completion.AppointmentID {
+			return validation.NewValidationError("service completion does not belong to the appointment")
+		}
+		return nil
+	}
+
+	if record.AppointmentID == nil {
+		return nil
+	}
+
+	completion, err := s.completionRepo.FindByAppointmentID(ctx, *record.AppointmentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return NewServiceError("failed to retrieve service completion", err)
+	}
+	record.ServiceCompletionID = &completion.ID
+	return nil
+}
+
+// resolveTemplate returns the explicitly requested template, or the active template for
+// the category of the recorded service. Records without a template are free-form.
+func (s *serviceRecordServiceImpl) resolveTemplate(ctx context.Context, record *domain.ServiceRecord) (*domain.ServiceRecordTemplate, error) {
+	if record.TemplateID != nil {
+		template, err := s.getTemplate(ctx, *record.TemplateID)
+		if err != nil {
+			return nil, err
+		}
+		if template.BusinessID != record.BusinessID {
+			return nil, validation.NewValidationError("template belongs to a different business")
+		}
+		return template, nil
+	}
+
+	if record.ServiceID == nil {
+		return nil, nil
+	}
+
+	svc, err := s.serviceRepo.GetByID(ctx, *record.ServiceID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("service", "id", *record.ServiceID)
+		}
+		return nil, NewServiceError("failed to retrieve service", err)
+	}
+	if svc.CategoryID == nil {
+		return nil, nil
+	}
+
+	template, err := s.templateRepo.FindActiveByCategory(ctx, record.BusinessID, *svc.CategoryID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, NewServiceError("failed to retrieve service record template", err)
+	}
+	return template, nil
+}
+
+// actForClient checks that the caller works at the business of a client, scoping the context to
+// that business
+func (s *serviceRecordServiceImpl) actForClient(ctx context.Context, clientID, action string) (context.Context, error) {
+	if clientID == "" {
+		return ctx, validation.NewValidationError("client_id is required")
+	}
+	client, err := s.clientRepo.GetByID(ctx, clientID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ctx, NewNotFoundError("client", "id", clientID)
+		}
+		return ctx, NewServiceError("failed to retrieve client", err)
+	}
+	if err := requireStaff(ctx, s.staffRepo, client.BusinessID, action); err != nil {
+		return ctx, err
+	}
+	return actFor(ctx, client.BusinessID, action)
+}
+
+func (s *serviceRecordServiceImpl) getRecord(ctx context.Context, id string) (*domain.ServiceRecord, error) {
+	if id == "" {
+		return nil, validation.NewValidationError("id is required")
+	}
+
+	record, err := s.recordRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("service record", "id", id)
+		}
+		return nil, NewServiceError("failed to retrieve service record", err)
+	}
+	return record, nil
+}
+
+func (s *serviceRecordServiceImpl) getTemplate(ctx context.Context, id string) (*domain.ServiceRecordTemplate, error) {
+	if id == "" {
+		return nil, validation.NewValidationError("template_id is required")
+	}
+
+	template, err := s.templateRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("service record template", "id", id)
+		}
+		return nil, NewServiceError("failed to retrieve service record template", err)
+	}
+	return template, nil
+}
+
+// toValidationError converts domain validation failures into service validation errors
+func toValidationError(err error) error {
+	if errors.Is(err, domain.ErrValidation) {
+		return validation.FromError(err)
+	}
+	return err
+}
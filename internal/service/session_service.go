@@ -0,0 +1,381 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/auth"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// SessionProvider is the identity provider users sign in through, which keeps sessions of its own
+type SessionProvider interface {
+	ListSessions(ctx context.Context, userID string) ([]*dto.ClerkSessionDTO, error)
+	RevokeSession(ctx context.Context, sessionID string) error
+}
+
+// SessionService defines the service interface for the devices and API keys users are signed in
+// with
+type SessionService interface {
+	ListMySessions(ctx context.Context) ([]*dto.UserSessionDTO, error)
+	RevokeSession(ctx context.Context, id string) (*dto.UserSessionDTO, error)
+	RevokeAllSessions(ctx context.Context, revokeDTO dto.RevokeAllSessionsDTO) (*dto.RevokeAllSessionsResultDTO, error)
+	CheckSession(ctx context.Context) error
+	// HandleClerkEvent signs a user's other sessions out when Clerk reports their password or
+	// two-factor authentication changed, verifying the event's signature first
+	HandleClerkEvent(ctx context.Context, payload []byte, signature auth.WebhookSignature) error
+}
+
+// sessionServiceImpl implements the SessionService interface
+type sessionServiceImpl struct {
+	sessionRepo   domain.UserSessionRepository
+	userRepo      domain.UserRepository
+	provider      SessionProvider
+	webhookSecret string
+	validator     *validator.Validate
+	now           func() time.Time
+}
+
+// NewSessionService creates a new session service, verifying Clerk's webhook events with the
+// endpoint's signing secret
+func NewSessionService(
+	sessionRepo domain.UserSessionRepository,
+	userRepo domain.UserRepository,
+	provider SessionProvider,
+	webhookSecret string,
+	validator *validator.Validate,
+) SessionService {
+	return &sessionServiceImpl{
+		sessionRepo:   sessionRepo,
+		userRepo:      userRepo,
+		provider:      provider,
+		webhookSecret: webhookSecret,
+		validator:     validator,
+		now:           time.Now,
+	}
+}
+
+// ListMySessions lists the devices and API keys the caller is signed in with, most recently used
+// first. Sessions Clerk knows of that have not made a request yet are recorded first.
+func (s *sessionServiceImpl) ListMySessions(ctx context.Context) ([]*dto.UserSessionDTO, error) {
+	user, err := s.caller(ctx, "list sessions")
+	if err != nil {
+		return nil, err
+	}
+	s.syncProviderSessions(ctx, user)
+
+	sessions, err := s.sessionRepo.FindByUser(ctx, user.ID, false)
+	if err != nil {
+		return nil, NewServiceError("failed to list sessions", err)
+	}
+	current := GetSessionFromContext(ctx)
+	result := make([]*dto.UserSessionDTO, 0, len(sessions))
+	for _, session := range sessions {
+		if session.IsRevoked(user.SessionsRevokedAt) {
+			continue
+		}
+		isCurrent := current != nil && current.Kind == session.Kind && current.ExternalID == session.ExternalID
+		result = append(result, dto.ToUserSessionDTO(session, isCurrent))
+	}
+	return result, nil
+}
+
+// RevokeSession signs out one of the caller's sessions. It is refused from then on even if
+// Clerk cannot be told.
+func (s *sessionServiceImpl) RevokeSession(ctx context.Context, id string) (*dto.UserSessionDTO, error) {
+	user, err := s.caller(ctx, "revoke sessions")
+	if err != nil {
+		return nil, err
+	}
+	session, err := s.sessionRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("session", "id", id)
+		}
+		return nil, NewServiceError("failed to retrieve session", err)
+	}
+	if session.UserID != user.ID {
+		return nil, NewNotFoundError("session", "id", id)
+	}
+
+	if err := session.Revoke(domain.SessionRevokedByUser, time.Now()); err != nil {
+		return nil, toValidationError(err)
+	}
+	session.SetAuditFields(&user.ID)
+	if err := s.sessionRepo.Update(ctx, session); err != nil {
+		return nil, NewServiceError("failed to revoke session", err)
+	}
+	if session.Kind == domain.SessionKindClerk {
+		s.revokeAtProvider(ctx, session.ExternalID)
+	}
+	return dto.ToUserSessionDTO(session, false), nil
+}
+
+// RevokeAllSessions signs out every device and API key of the caller, including the one asking,
+// as is done when the password or two-factor authentication changes. Sessions signed in before
+// now are refused, including those not recorded yet.
+func (s *sessionServiceImpl) RevokeAllSessions(ctx context.Context, revokeDTO dto.RevokeAllSessionsDTO) (*dto.RevokeAllSessionsResultDTO, error) {
+	if err := s.validator.Struct(revokeDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	user, err := s.caller(ctx, "revoke sessions")
+	if err != nil {
+		return nil, err
+	}
+	signedIn, err := s.sessionRepo.FindByUser(ctx, user.ID, false)
+	if err != nil {
+		return nil, NewServiceError("failed to list sessions", err)
+	}
+
+	now := time.Now()
+	user.SessionsRevokedAt = &now
+	user.SetAuditFields(&user.ID)
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return nil, NewServiceError("failed to update user", err)
+	}
+	count, err := s.sessionRepo.RevokeAllByUser(ctx, user.ID, revokeDTO.Reason, now)
+	if err != nil {
+		return nil, NewServiceError("failed to revoke sessions", err)
+	}
+	for _, session := range signedIn {
+		if session.Kind == domain.SessionKindClerk {
+			s.revokeAtProvider(ctx, session.ExternalID)
+		}
+	}
+	return &dto.RevokeAllSessionsResultDTO{RevokedCount: count, RevokedAt: now}, nil
+}
+
+// CheckSession checks the session a request is made with against the revocation list, returning
+// domain.ErrSessionRevoked when it was signed out. Sessions are recorded when first seen, and
+// their last use as requests come in. Anonymous requests are left to the resolvers, while users
+// without a session are refused as revoked.
+func (s *sessionServiceImpl) CheckSession(ctx context.Context) error {
+	userID := GetUserIDFromContext(ctx)
+	if userID == nil {
+		return nil
+	}
+	current := GetSessionFromContext(ctx)
+	if current == nil {
+		return domain.ErrSessionRevoked
+	}
+	user, err := s.userRepo.GetByID(ctx, *userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrSessionRevoked
+		}
+		return NewServiceError("failed to retrieve user", err)
+	}
+
+	now := time.Now()
+	session, err := s.sessionRepo.FindByExternalID(ctx, current.Kind, current.ExternalID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		session = &domain.UserSession{
+			UserID:     user.ID,
+			Kind:       current.Kind,
+			ExternalID: current.ExternalID,
+			UserAgent:  optionalString(current.UserAgent),
+			IPAddress:  optionalString(GetClientIPFromContext(ctx)),
+			IssuedAt:   current.IssuedAt,
+			LastSeenAt: now,
+		}
+		if err := session.Validate(); err != nil {
+			return toValidationError(err)
+		}
+		if err := session.CheckActive(user.SessionsRevokedAt); err != nil {
+			return err
+		}
+		session.SetAuditFields(&user.ID)
+		if err := s.sessionRepo.Create(ctx, session); err != nil {
+			return NewServiceError("failed to record session", err)
+		}
+		return nil
+	}
+	if err != nil {
+		return NewServiceError("failed to retrieve session", err)
+	}
+
+	if session.UserID != user.ID {
+		return domain.ErrSessionRevoked
+	}
+	if err := session.CheckActive(user.SessionsRevokedAt); err != nil {
+		return err
+	}
+	if session.Seen(now) {
+		session.IPAddress = optionalString(GetClientIPFromContext(ctx))
+		// Concurrent requests record the same use, so losing the race is fine
+		if err := s.sessionRepo.Update(ctx, session); err != nil && !errors.Is(err, domain.ErrConflict) {
+			return NewServiceError("failed to record session use", err)
+		}
+	}
+	return nil
+}
+
+// HandleClerkEvent signs a user's other sessions out when Clerk reports their password or
+// two-factor authentication changed, keeping the one most recently active, which the change was
+// made from. Settings reported for a user for the first time are only recorded. Events about
+// anything else, or users who have not registered, are acknowledged and ignored.
+func (s *sessionServiceImpl) HandleClerkEvent(ctx context.Context, payload []byte, signature auth.WebhookSignature) error {
+	event, err := auth.ParseWebhook(payload, signature, s.webhookSecret, s.now())
+	if err != nil {
+		return err
+	}
+	if event.Type != auth.EventUserUpdated {
+		return nil
+	}
+	clerkUser, err := event.User()
+	if err != nil {
+		return validation.FromError(err)
+	}
+	user, err := s.userRepo.FindByClerkID(ctx, clerkUser.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return NewServiceError("failed to retrieve user", err)
+	}
+
+	passwordChangedAt := clerkUser.PasswordUpdatedAt()
+	known := user.TwoFactorEnabled != nil
+	var reason domain.SessionRevocationReason
+	switch {
+	case known && passwordChangedAt != nil && (user.PasswordChangedAt == nil || passwordChangedAt.After(*user.PasswordChangedAt)):
+		reason = domain.SessionRevokedPasswordChange
+	case known && *user.TwoFactorEnabled != clerkUser.TwoFactorEnabled:
+		reason = domain.SessionRevokedTwoFactorChange
+	case known:
+		return nil
+	}
+	// Sessions are signed out before the settings are saved, so that a retry of an event that
+	// failed half way signs out the rest
+	if reason != "" {
+		if err := s.revokeOtherSessions(ctx, user, reason); err != nil {
+			return err
+		}
+	}
+
+	user.PasswordChangedAt = passwordChangedAt
+	user.TwoFactorEnabled = &clerkUser.TwoFactorEnabled
+	user.SetAuditFields(&user.ID)
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return NewServiceError("failed to update user", err)
+	}
+	return nil
+}
+
+// revokeOtherSessions signs out every session of a user but the one most recently active at
+// Clerk, or recorded as such when Clerk cannot be reached
+func (s *sessionServiceImpl) revokeOtherSessions(ctx context.Context, user *domain.User, reason domain.SessionRevocationReason) error {
+	listed := s.syncProviderSessions(ctx, user)
+	sessions, err := s.sessionRepo.FindByUser(ctx, user.ID, false)
+	if err != nil {
+		return NewServiceError("failed to list sessions", err)
+	}
+
+	var keep string
+	var lastActiveAt time.Time
+	for _, clerkSession := range listed {
+		if keep == "" || clerkSession.LastActiveAt.After(lastActiveAt) {
+			keep, lastActiveAt = clerkSession.ID, clerkSession.LastActiveAt
+		}
+	}
+	if keep == "" {
+		// Sessions are listed most recently seen first
+		for _, session := range sessions {
+			if session.Kind == domain.SessionKindClerk {
+				keep = session.ExternalID
+				break
+			}
+		}
+	}
+
+	now := s.now()
+	for _, session := range sessions {
+		if session.Kind == domain.SessionKindClerk && session.ExternalID == keep {
+			continue
+		}
+		if err := session.Revoke(reason, now); err != nil {
+			return toValidationError(err)
+		}
+		session.SetAuditFields(&user.ID)
+		if err := s.sessionRepo.Update(ctx, session); err != nil {
+			return NewServiceError("failed to revoke session", err)
+		}
+		if session.Kind == domain.SessionKindClerk {
+			s.revokeAtProvider(ctx, session.ExternalID)
+		}
+	}
+	return nil
+}
+
+// caller retrieves the user asking
+func (s *sessionServiceImpl) caller(ctx context.Context, action string) (*domain.User, error) {
+	userID := GetUserIDFromContext(ctx)
+	if userID == nil {
+		return nil, NewForbiddenError(action)
+	}
+	user, err := s.userRepo.GetByID(ctx, *userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewForbiddenError(action)
+		}
+		return nil, NewServiceError("failed to retrieve user", err)
+	}
+	return user, nil
+}
+
+// syncProviderSessions records the Clerk sessions of a user that have not made a request yet,
+// returning those Clerk listed. Listing falls back to the recorded sessions when Clerk cannot be
+// reached, in which case none are returned.
+func (s *sessionServiceImpl) syncProviderSessions(ctx context.Context, user *domain.User) []*dto.ClerkSessionDTO {
+	if user.ClerkID == nil {
+		return nil
+	}
+	sessions, err := s.provider.ListSessions(ctx, *user.ClerkID)
+	if err != nil {
+		log.Warn().Err(err).Str("user_id", user.ID).Msg("Failed to list Clerk sessions")
+		return nil
+	}
+	for _, clerkSession := range sessions {
+		_, err := s.sessionRepo.FindByExternalID(ctx, domain.SessionKindClerk, clerkSession.ID)
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		session := &domain.UserSession{
+			UserID:     user.ID,
+			Kind:       domain.SessionKindClerk,
+			ExternalID: clerkSession.ID,
+			DeviceName: optionalString(clerkSession.DeviceName),
+			UserAgent:  optionalString(clerkSession.UserAgent),
+			IPAddress:  optionalString(clerkSession.IPAddress),
+			IssuedAt:   clerkSession.CreatedAt,
+			LastSeenAt: clerkSession.LastActiveAt,
+		}
+		session.SetAuditFields(&user.ID)
+		if err := s.sessionRepo.Create(ctx, session); err != nil {
+			log.Warn().Err(err).Str("session_id", clerkSession.ID).Msg("Failed to record Clerk session")
+		}
+	}
+	return sessions
+}
+
+// revokeAtProvider signs a session out at Clerk too. The revocation list refuses the session
+// anyway, so failures are only logged.
+func (s *sessionServiceImpl) revokeAtProvider(ctx context.Context, sessionID string) {
+	if err := s.provider.RevokeSession(ctx, sessionID); err != nil {
+		log.Warn().Err(err).Str("session_id", sessionID).Msg("Failed to revoke Clerk session")
+	}
+}
+
+// optionalString returns nil for an empty string
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
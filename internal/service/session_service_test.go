@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/auth"
+	"github.com/assimoes/beautix/internal/repository/memory"
+)
+
+// fakeSessionProvider lists fixed Clerk sessions and records those revoked
+type fakeSessionProvider struct {
+	sessions []*dto.ClerkSessionDTO
+	revoked  []string
+}
+
+func (p *fakeSessionProvider) ListSessions(ctx context.Context, userID string) ([]*dto.ClerkSessionDTO, error) {
+	return p.sessions, nil
+}
+
+func (p *fakeSessionProvider) RevokeSession(ctx context.Context, sessionID string) error {
+	p.revoked = append(p.revoked, sessionID)
+	return nil
+}
+
+// signClerkEvent signs a webhook payload as Clerk does, with the key of a "whsec_" secret
+func signClerkEvent(payload []byte, key string, at time.Time) auth.WebhookSignature {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte("msg_1." + timestamp + "."))
+	mac.Write(payload)
+	return auth.WebhookSignature{ID: "msg_1", Timestamp: timestamp, Signature: "v1," + base64.StdEncoding.EncodeToString(mac.Sum(nil))}
+}
+
+func TestSessionService_CheckSession(t *testing.T) {
+	db := memory.NewDB()
+	sessions := NewSessionService(memory.NewUserSessionRepository(db), memory.NewUserRepository(db), &fakeSessionProvider{}, "", validator.New())
+
+	assert.NoError(t, sessions.CheckSession(context.Background()), "anonymous requests are left to the resolvers")
+
+	withoutSession := SetUserContext(context.Background(), "user-1", "employee", nil)
+	assert.ErrorIs(t, sessions.CheckSession(withoutSession), domain.ErrSessionRevoked)
+}
+
+func TestSessionService_HandleClerkEvent(t *testing.T) {
+	ctx := context.Background()
+	db := memory.NewDB()
+	users := memory.NewUserRepository(db)
+	sessionRepo := memory.NewUserSessionRepository(db)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	clerkID := "user_123"
+	user := &domain.User{ClerkID: &clerkID, Email: "ana@example.com", FirstName: "Ana", LastName: "Silva"}
+	require.NoError(t, users.Create(ctx, user))
+	old := &domain.UserSession{UserID: user.ID, Kind: domain.SessionKindClerk, ExternalID: "sess_old",
+		IssuedAt: now.Add(-48 * time.Hour), LastSeenAt: now.Add(-24 * time.Hour)}
+	require.NoError(t, sessionRepo.Create(ctx, old))
+
+	// The user changed their settings from the session most recently active at Clerk, which
+	// has not made a request yet
+	provider := &fakeSessionProvider{sessions: []*dto.ClerkSessionDTO{
+		{ID: "sess_old", CreatedAt: now.Add(-48 * time.Hour), LastActiveAt: now.Add(-24 * time.Hour)},
+		{ID: "sess_current", CreatedAt: now.Add(-time.Hour), LastActiveAt: now.Add(-time.Minute)},
+		{ID: "sess_phone", CreatedAt: now.Add(-2 * time.Hour), LastActiveAt: now.Add(-time.Hour)},
+	}}
+	key := "endpoint-secret"
+	secret := "whsec_" + base64.StdEncoding.EncodeToString([]byte(key))
+	sessions := NewSessionService(sessionRepo, users, provider, secret, validator.New()).(*sessionServiceImpl)
+	sessions.now = func() time.Time { return now }
+
+	handle := func(payload string) error {
+		return sessions.HandleClerkEvent(ctx, []byte(payload), signClerkEvent([]byte(payload), key, now))
+	}
+	active := func() []string {
+		signedIn, err := sessionRepo.FindByUser(ctx, user.ID, false)
+		require.NoError(t, err)
+		ids := make([]string, 0, len(signedIn))
+		for _, session := range signedIn {
+			ids = append(ids, session.ExternalID)
+		}
+		return ids
+	}
+
+	require.NoError(t, handle(`{"type": "user.updated", "data": {"id": "user_123", "two_factor_enabled": false,
+		"password_last_updated_at": 1772280000000}}`))
+	stored, err := users.GetByID(ctx, user.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.TwoFactorEnabled)
+	assert.False(t, *stored.TwoFactorEnabled)
+	assert.Equal(t, time.UnixMilli(1772280000000).UTC(), *stored.PasswordChangedAt)
+	assert.Equal(t, []string{"sess_old"}, active(), "settings reported for the first time are only recorded")
+	assert.Empty(t, provider.revoked)
+
+	require.NoError(t, handle(`{"type": "user.updated", "data": {"id": "user_123", "two_factor_enabled": false,
+		"password_last_updated_at": 1772366340000}}`))
+	assert.Equal(t, []string{"sess_current"}, active(), "the password change signs the other sessions out")
+	assert.ElementsMatch(t, []string{"sess_old", "sess_phone"}, provider.revoked)
+	signedOut, err := sessionRepo.FindByExternalID(ctx, domain.SessionKindClerk, "sess_phone")
+	require.NoError(t, err)
+	assert.Equal(t, domain.SessionRevokedPasswordChange, *signedOut.RevokedReason)
+
+	require.NoError(t, handle(`{"type": "user.updated", "data": {"id": "user_123", "two_factor_enabled": false,
+		"password_last_updated_at": 1772366340000}}`))
+	assert.Len(t, provider.revoked, 2, "updates leaving the settings alone sign nobody out")
+
+	provider.sessions = append(provider.sessions, &dto.ClerkSessionDTO{ID: "sess_tablet", CreatedAt: now, LastActiveAt: now.Add(-30 * time.Minute)})
+	require.NoError(t, handle(`{"type": "user.updated", "data": {"id": "user_123", "two_factor_enabled": true,
+		"password_last_updated_at": 1772366340000}}`))
+	assert.Equal(t, []string{"sess_current"}, active(), "turning two-factor authentication on signs the other sessions out")
+	signedOut, err = sessionRepo.FindByExternalID(ctx, domain.SessionKindClerk, "sess_tablet")
+	require.NoError(t, err)
+	assert.Equal(t, domain.SessionRevokedTwoFactorChange, *signedOut.RevokedReason)
+
+	assert.NoError(t, handle(`{"type": "user.updated", "data": {"id": "user_unregistered", "two_factor_enabled": true}}`))
+	assert.NoError(t, handle(`{"type": "session.created", "data": {"id": "sess_other"}}`))
+
+	payload := []byte(`{"type": "user.updated", "data": {"id": "user_123", "two_factor_enabled": false}}`)
+	err = sessions.HandleClerkEvent(ctx, payload, signClerkEvent(payload, "another-secret", now))
+	assert.ErrorIs(t, err, auth.ErrInvalidSignature)
+}
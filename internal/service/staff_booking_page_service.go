@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// maxSlugSuffix limits the numbers tried when a derived slug is already taken
+const maxSlugSuffix = 100
+
+// StaffBookingPageService defines the service interface for managing staff members' personal
+// booking pages
+type StaffBookingPageService interface {
+	SaveStaffBookingPage(ctx context.Context, saveDTO dto.SaveStaffBookingPageDTO) (*dto.StaffBookingPageResponseDTO, error)
+	GetStaffBookingPage(ctx context.Context, staffID string) (*dto.StaffBookingPageResponseDTO, error)
+}
+
+// staffBookingPageServiceImpl implements the StaffBookingPageService interface
+type staffBookingPageServiceImpl struct {
+	staffPageRepo domain.StaffBookingPageRepository
+	staffRepo     domain.StaffRepository
+	userRepo      domain.BaseRepository[domain.User]
+	serviceRepo   domain.BaseRepository[domain.Service]
+	validator     *validator.Validate
+}
+
+// NewStaffBookingPageService creates a new staff booking page service
+func NewStaffBookingPageService(
+	staffPageRepo domain.StaffBookingPageRepository,
+	staffRepo domain.StaffRepository,
+	userRepo domain.BaseRepository[domain.User],
+	serviceRepo domain.BaseRepository[domain.Service],
+	validator *validator.Validate,
+) StaffBookingPageService {
+	return &staffBookingPageServiceImpl{
+		staffPageRepo: staffPageRepo,
+		staffRepo:     staffRepo,
+		userRepo:      userRepo,
+		serviceRepo:   serviceRepo,
+		validator:     validator,
+	}
+}
+
+// SaveStaffBookingPage creates or updates a staff member's booking page. Staff members manage
+// their own page; owners and managers manage the pages of everyone at the business. New pages
+// without a slug get one derived from the staff member's name.
+func (s *staffBookingPageServiceImpl) SaveStaffBookingPage(ctx context.Context, saveDTO dto.SaveStaffBookingPageDTO) (*dto.StaffBookingPageResponseDTO, error) {
+	if err := s.validator.Struct(saveDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	staff, err := s.authorize(ctx, saveDTO.StaffID)
+	if err != nil {
+		return nil, err
+	}
+	ctx, err = actFor(ctx, staff.BusinessID, "manage booking pages")
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := s.staffPageRepo.FindByStaffID(ctx, staff.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, NewServiceError("failed to retrieve booking page", err)
+	}
+	if page == nil {
+		page = &domain.StaffBookingPage{BusinessID: staff.BusinessID, StaffID: staff.ID}
+		if saveDTO.Slug == nil {
+			if page.Slug, err = s.deriveSlug(ctx, staff); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if saveDTO.Slug != nil && *saveDTO.Slug != page.Slug {
+		var excludeID *string
+		if page.ID != "" {
+			excludeID = &page.ID
+		}
+		taken, err := s.staffPageRepo.SlugExists(ctx, *saveDTO.Slug, excludeID)
+		if err != nil {
+			return nil, NewServiceError("failed to check slug", err)
+		}
+		if taken {
+			return nil, validation.NewValidationError("the slug is already taken")
+		}
+		page.Slug = *saveDTO.Slug
+	}
+	saveDTO.Bio.Apply(&page.Bio)
+	if saveDTO.PortfolioURLs != nil {
+		if err := page.SetPortfolioURLs(saveDTO.PortfolioURLs); err != nil {
+			return nil, NewServiceError("failed to encode portfolio", err)
+		}
+	}
+	if saveDTO.ServiceIDs != nil {
+		if err := s.checkServices(ctx, staff.BusinessID, saveDTO.ServiceIDs); err != nil {
+			return nil, err
+		}
+		if err := page.SetServiceIDs(saveDTO.ServiceIDs); err != nil {
+			return nil, NewServiceError("failed to encode services", err)
+		}
+	}
+	if saveDTO.IsPublished != nil {
+		page.IsPublished = *saveDTO.IsPublished
+	}
+	if err := page.Validate(); err != nil {
+		return nil, toValidationError(err)
+	}
+
+	creating := page.ID == ""
+	page.SetAuditFields(GetUserIDFromContext(ctx))
+	if creating {
+		err = s.staffPageRepo.Create(ctx, page)
+	} else {
+		err = s.staffPageRepo.Update(ctx, page)
+	}
+	if err != nil {
+		return nil, NewServiceError("failed to save booking page", err)
+	}
+	return dto.ToStaffBookingPageResponseDTO(page), nil
+}
+
+// GetStaffBookingPage retrieves a staff member's booking page
+func (s *staffBookingPageServiceImpl) GetStaffBookingPage(ctx context.Context, staffID string) (*dto.StaffBookingPageResponseDTO, error) {
+	if _, err := s.authorize(ctx, staffID); err != nil {
+		return nil, err
+	}
+
+	page, err := s.staffPageRepo.FindByStaffID(ctx, staffID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("booking page", "staff_id", staffID)
+		}
+		return nil, NewServiceError("failed to retrieve booking page", err)
+	}
+	return dto.ToStaffBookingPageResponseDTO(page), nil
+}
+
+// authorize retrieves the staff member whose page is managed, checking that the caller is the
+// staff member or an owner or manager of their business
+func (s *staffBookingPageServiceImpl) authorize(ctx context.Context, staffID string) (*domain.Staff, error) {
+	userID := GetUserIDFromContext(ctx)
+	if userID == nil {
+		return nil, NewForbiddenError("manage booking pages")
+	}
+	staff, err := s.staffRepo.GetByID(ctx, staffID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("staff", "id", staffID)
+		}
+		return nil, NewServiceError("failed to retrieve staff member", err)
+	}
+	if staff.UserID == *userID {
+		return staff, nil
+	}
+
+	caller, err := s.staffRepo.FindByBusinessAndUser(ctx, staff.BusinessID, *userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, NewServiceError("failed to retrieve staff member", err)
+	}
+	if caller == nil || !caller.CanManage() {
+		return nil, NewForbiddenError("manage the booking pages of other staff members")
+	}
+	return staff, nil
+}
+
+// deriveSlug derives an unused slug from the staff member's name, numbering it when the name
+// is already taken, e.g. rita-sousa-2
+func (s *staffBookingPageServiceImpl) deriveSlug(ctx context.Context, staff *domain.Staff) (string, error) {
+	user, err := s.userRepo.GetByID(ctx, staff.UserID)
+	if err != nil {
+		return "", NewServiceError("failed to retrieve staff name", err)
+	}
+	base := domain.StaffPageSlug(user.FirstName, user.LastName)
+	switch {
+	case base == "":
+		base = "staff"
+	case len(base) < 3:
+		base = "staff-" + base
+	}
+
+	slug := base
+	for suffix := 2; suffix <= maxSlugSuffix; suffix++ {
+		taken, err := s.staffPageRepo.SlugExists(ctx, slug, nil)
+		if err != nil {
+			return "", NewServiceError("failed to check slug", err)
+		}
+		if !taken {
+			return slug, nil
+		}
+		slug = fmt.Sprintf("%s-%d", base, suffix)
+	}
+	return "", validation.NewValidationError("no slug could be derived from the staff member's name, please choose one")
+}
+
+// checkServices checks that the services offered on a page are active services of the business
+func (s *staffBookingPageServiceImpl) checkServices(ctx context.Context, businessID string, serviceIDs []string) error {
+	services, err := s.serviceRepo.GetByIDs(ctx, serviceIDs)
+	if err != nil {
+		return NewServiceError("failed to retrieve services", err)
+	}
+	active := map[string]bool{}
+	for _, service := range services {
+		active[service.ID] = service.BusinessID == businessID && service.IsActive
+	}
+	for _, id := range serviceIDs {
+		if !active[id] {
+			return NewNotFoundError("service", "id", id)
+		}
+	}
+	return nil
+}
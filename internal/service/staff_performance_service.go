@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/jobs"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// StaffPerformanceService defines the service interface for the daily, weekly and monthly
+// performance records of staff members, aggregated from their appointments, what was charged
+// for them and their clients' ratings
+type StaffPerformanceService interface {
+	GetStaffPerformance(ctx context.Context, query dto.StaffPerformanceQueryDTO) ([]*dto.StaffPerformanceResponseDTO, error)
+	BackfillStaffPerformance(ctx context.Context, backfillDTO dto.BackfillStaffPerformanceDTO) (*dto.JobResponseDTO, error)
+	// AggregateRecent recomputes the records of the periods overlapping the last
+	// domain.PerformanceRefreshDays days of every business with appointments in them
+	AggregateRecent(ctx context.Context, now time.Time) (*dto.PerformanceRunDTO, error)
+	RegisterJobs(runner *jobs.Runner)
+}
+
+// staffPerformanceServiceImpl implements the StaffPerformanceService interface
+type staffPerformanceServiceImpl struct {
+	performanceRepo domain.StaffPerformanceRepository
+	staffRepo       domain.StaffRepository
+	businessRepo    domain.BusinessRepository
+	queue           *jobs.Queue
+	validator       *validator.Validate
+}
+
+// NewStaffPerformanceService creates a new staff performance service, along with the handler of
+// the backfill jobs it enqueues
+func NewStaffPerformanceService(
+	performanceRepo domain.StaffPerformanceRepository,
+	staffRepo domain.StaffRepository,
+	businessRepo domain.BusinessRepository,
+	queue *jobs.Queue,
+	validator *validator.Validate,
+) StaffPerformanceService {
+	return &staffPerformanceServiceImpl{
+		performanceRepo: performanceRepo,
+		staffRepo:       staffRepo,
+		businessRepo:    businessRepo,
+		queue:           queue,
+		validator:       validator,
+	}
+}
+
+// RegisterJobs registers the handler of the backfill jobs with a runner
+func (s *staffPerformanceServiceImpl) RegisterJobs(runner *jobs.Runner) {
+	runner.Register(domain.JobPerformanceBackfill, s.backfill)
+}
+
+// GetStaffPerformance returns the records of the periods of a kind starting between two dates,
+// ordered by period start and staff ID. Staff who may view reports see every staff member's;
+// other staff only their own.
+func (s *staffPerformanceServiceImpl) GetStaffPerformance(ctx context.Context, query dto.StaffPerformanceQueryDTO) ([]*dto.StaffPerformanceResponseDTO, error) {
+	if err := s.validator.Struct(query); err != nil {
+		return nil, validation.FromError(err)
+	}
+	staff, err := s.callerStaff(ctx, query.BusinessID, "view staff performance")
+	if err != nil {
+		return nil, err
+	}
+	if !staff.Can(domain.PermissionReportsView) {
+		if query.StaffID != nil && *query.StaffID != staff.ID {
+			return nil, NewForbiddenError("view the performance of other staff")
+		}
+		query.StaffID = &staff.ID
+	}
+	location, err := s.location(ctx, query.BusinessID)
+	if err != nil {
+		return nil, err
+	}
+	first, last, err := performanceDates(query.StartDate, query.EndDate, location)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := s.performanceRepo.FindInRange(ctx, query.BusinessID, query.Period, query.StaffID, first, last.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve staff performance", err)
+	}
+	result := make([]*dto.StaffPerformanceResponseDTO, len(records))
+	for i, record := range records {
+		result[i] = dto.ToStaffPerformanceResponseDTO(record)
+	}
+	return result, nil
+}
+
+// BackfillStaffPerformance enqueues a job aggregating the performance of a business over past
+// dates, such as those before the aggregation ran. Only owners and managers can backfill, and
+// a backfill of the same dates already pending is returned rather than enqueued again.
+func (s *staffPerformanceServiceImpl) BackfillStaffPerformance(ctx context.Context, backfillDTO dto.BackfillStaffPerformanceDTO) (*dto.JobResponseDTO, error) {
+	if err := s.validator.Struct(backfillDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	if err := requireManager(ctx, s.staffRepo, backfillDTO.BusinessID, "backfill staff performance"); err != nil {
+		return nil, err
+	}
+	location, err := s.location(ctx, backfillDTO.BusinessID)
+	if err != nil {
+		return nil, err
+	}
+	first, last, err := performanceDates(backfillDTO.StartDate, backfillDTO.EndDate, location)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := domain.PerformanceBackfillPayload{StartDate: first.Format(time.DateOnly), EndDate: last.Format(time.DateOnly)}
+	job, err := s.queue.Enqueue(ctx, domain.JobPerformanceBackfill, payload,
+		jobs.WithKey(backfillDTO.BusinessID+":"+payload.StartDate+":"+payload.EndDate), jobs.ForBusiness(backfillDTO.BusinessID))
+	if err != nil {
+		return nil, NewServiceError("failed to enqueue staff performance backfill", err)
+	}
+	return dto.ToJobResponseDTO(job), nil
+}
+
+// AggregateRecent recomputes the records of the periods overlapping the last
+// domain.PerformanceRefreshDays days of every business with appointments in them, so
+// appointments completed or rated late are counted. A business that fails is logged and skipped.
+func (s *staffPerformanceServiceImpl) AggregateRecent(ctx context.Context, now time.Time) (*dto.PerformanceRunDTO, error) {
+	// Widen the range by a day on each side so every business's local dates are covered
+	businessIDs, err := s.performanceRepo.FindBusinessesWithAppointments(ctx,
+		now.AddDate(0, 0, -domain.PerformanceRefreshDays-1), now.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, NewServiceError("failed to find businesses with recent appointments", err)
+	}
+
+	run := &dto.PerformanceRunDTO{}
+	for _, businessID := range businessIDs {
+		businessCtx := domain.WithTenant(ctx, domain.TenantContext{BusinessID: businessID})
+		location, err := s.location(businessCtx, businessID)
+		if err == nil {
+			var written int
+			if written, err = s.aggregate(businessCtx, businessID, location, now.AddDate(0, 0, -domain.PerformanceRefreshDays), now); err == nil {
+				run.Businesses++
+				run.Records += written
+				continue
+			}
+		}
+		log.Warn().Err(err).Str("business_id", businessID).Msg("Failed to aggregate staff performance")
+		run.Failed++
+	}
+	return run, nil
+}
+
+// backfill aggregates the performance of the business of a job over the dates of its payload
+func (s *staffPerformanceServiceImpl) backfill(ctx context.Context, job *domain.Job) error {
+	var payload domain.PerformanceBackfillPayload
+	if err := job.DecodePayload(&payload); err != nil {
+		return jobs.Permanent(err)
+	}
+	if job.BusinessID == nil {
+		return jobs.Permanent(errors.New("a staff performance backfill needs a business"))
+	}
+	location, err := s.location(ctx, *job.BusinessID)
+	if err != nil {
+		if errors.As(err, &NotFoundError{}) {
+			return jobs.Permanent(err)
+		}
+		return err
+	}
+	first, last, err := performanceDates(payload.StartDate, payload.EndDate, location)
+	if err != nil {
+		return jobs.Permanent(err)
+	}
+
+	written, err := s.aggregate(ctx, *job.BusinessID, location, first, last)
+	if err != nil {
+		return err
+	}
+	log.Info().Str("business_id", *job.BusinessID).Str("start_date", payload.StartDate).Str("end_date", payload.EndDate).
+		Int("records", written).Msg("Backfilled staff performance")
+	return nil
+}
+
+// aggregate recomputes and stores the records of every period of each kind overlapping the local
+// dates from first to last, returning how many records were written. Periods are aggregated
+// whole, so a month is recomputed from all of its appointments even when only its last day is
+// in range.
+func (s *staffPerformanceServiceImpl) aggregate(ctx context.Context, businessID string, location *time.Location, first, last time.Time) (int, error) {
+	fetchStart, fetchEnd := first, last
+	for _, period := range domain.PerformancePeriods {
+		start, _ := period.Bounds(first, location)
+		_, end := period.Bounds(last, location)
+		if start.Before(fetchStart) {
+			fetchStart = start
+		}
+		if end.After(fetchEnd) {
+			fetchEnd = end
+		}
+	}
+	appointments, err := s.performanceRepo.FindPerformanceAppointments(ctx, businessID, fetchStart, fetchEnd)
+	if err != nil {
+		return 0, NewServiceError("failed to retrieve appointments", err)
+	}
+
+	written := 0
+	for _, period := range domain.PerformancePeriods {
+		byStart := map[int64][]*domain.StaffPerformance{}
+		for _, record := range domain.AggregatePerformance(businessID, period, location, appointments) {
+			byStart[record.StartDate.Unix()] = append(byStart[record.StartDate.Unix()], record)
+		}
+		// Periods without appointments are stored empty too, removing records left from before
+		start, _ := period.Bounds(first, location)
+		_, end := period.Bounds(last, location)
+		for start.Before(end) {
+			records := byStart[start.Unix()]
+			if err := s.performanceRepo.ReplacePeriod(ctx, businessID, period, start, records); err != nil {
+				return written, NewServiceError("failed to store staff performance", err)
+			}
+			written += len(records)
+			_, start = period.Bounds(start, location)
+		}
+	}
+	return written, nil
+}
+
+// callerStaff returns the caller's active staff record at the business, refusing the action
+// when they have none
+func (s *staffPerformanceServiceImpl) callerStaff(ctx context.Context, businessID, action string) (*domain.Staff, error) {
+	userID := GetUserIDFromContext(ctx)
+	if userID == nil {
+		return nil, NewForbiddenError(action)
+	}
+	staff, err := s.staffRepo.FindByBusinessAndUser(ctx, businessID, *userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewForbiddenError(action)
+		}
+		return nil, NewServiceError("failed to retrieve staff member", err)
+	}
+	if !staff.IsActive {
+		return nil, NewForbiddenError(action)
+	}
+	return staff, nil
+}
+
+// location returns the time zone of a business, whose local dates the periods follow
+func (s *staffPerformanceServiceImpl) location(ctx context.Context, businessID string) (*time.Location, error) {
+	business, err := getBusiness(ctx, s.businessRepo, businessID)
+	if err != nil {
+		return nil, err
+	}
+	return domain.NewAvailabilityRules(business, nil).Location, nil
+}
+
+// performanceDates parses an inclusive range of local dates, returning the start of the first
+// and of the last
+func performanceDates(startDate, endDate string, location *time.Location) (time.Time, time.Time, error) {
+	first, err := domain.ParseBookingDate(startDate, location)
+	if err != nil {
+		return time.Time{}, time.Time{}, toValidationError(err)
+	}
+	last, err := domain.ParseBookingDate(endDate, location)
+	if err != nil {
+		return time.Time{}, time.Time{}, toValidationError(err)
+	}
+	if last.Before(first) {
+		return time.Time{}, time.Time{}, validation.NewValidationError("end_date must not be before start_date")
+	}
+	if last.After(first.AddDate(0, 0, domain.MaxPerformanceBackfillDays-1)) {
+		return time.Time{}, time.Time{}, validation.NewValidationError(fmt.Sprintf("the range cannot exceed %d days", domain.MaxPerformanceBackfillDays))
+	}
+	return first, last, nil
+}
@@ -0,0 +1,301 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// StaffShiftService defines the service interface for the shifts staff members work and the
+// weekly rota built from them
+type StaffShiftService interface {
+	CreateStaffShift(ctx context.Context, shiftDTO dto.CreateStaffShiftDTO) (*dto.StaffShiftResponseDTO, error)
+	UpdateStaffShift(ctx context.Context, id string, shiftDTO dto.UpdateStaffShiftDTO) (*dto.StaffShiftResponseDTO, error)
+	DeleteStaffShift(ctx context.Context, id string) error
+	GetStaffShifts(ctx context.Context, staffID string) ([]*dto.StaffShiftResponseDTO, error)
+	GetRota(ctx context.Context, query dto.RotaQueryDTO) (*dto.RotaDTO, error)
+}
+
+// staffShiftServiceImpl implements the StaffShiftService interface
+type staffShiftServiceImpl struct {
+	shiftRepo    domain.StaffShiftRepository
+	staffRepo    domain.StaffRepository
+	businessRepo domain.BusinessRepository
+	validator    *validator.Validate
+}
+
+// NewStaffShiftService creates a new staff shift service
+func NewStaffShiftService(
+	shiftRepo domain.StaffShiftRepository,
+	staffRepo domain.StaffRepository,
+	businessRepo domain.BusinessRepository,
+	validator *validator.Validate,
+) StaffShiftService {
+	return &staffShiftServiceImpl{
+		shiftRepo:    shiftRepo,
+		staffRepo:    staffRepo,
+		businessRepo: businessRepo,
+		validator:    validator,
+	}
+}
+
+// CreateStaffShift adds a shift to a staff member's rota. From then on the staff member can only
+// be booked during their shifts. Only owners and managers can add shifts.
+func (s *staffShiftServiceImpl) CreateStaffShift(ctx context.Context, shiftDTO dto.CreateStaffShiftDTO) (*dto.StaffShiftResponseDTO, error) {
+	if err := s.validator.Struct(shiftDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	staff, err := s.getStaff(ctx, shiftDTO.StaffID)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireManager(ctx, s.staffRepo, staff.BusinessID, "schedule staff shifts"); err != nil {
+		return nil, err
+	}
+
+	shift := &domain.StaffShift{
+		BusinessID: staff.BusinessID,
+		StaffID:    staff.ID,
+		Kind:       shiftDTO.Kind,
+		Weekday:    shiftDTO.Weekday,
+		StartTime:  shiftDTO.StartTime,
+		EndTime:    shiftDTO.EndTime,
+		Notes:      shiftDTO.Notes,
+	}
+	for _, date := range []struct {
+		value *string
+		field **time.Time
+	}{
+		{shiftDTO.ShiftDate, &shift.ShiftDate},
+		{shiftDTO.EffectiveFrom, &shift.EffectiveFrom},
+		{shiftDTO.EffectiveUntil, &shift.EffectiveUntil},
+	} {
+		if *date.field, err = parseShiftDate(date.value); err != nil {
+			return nil, err
+		}
+	}
+	if err := shift.Validate(); err != nil {
+		return nil, toValidationError(err)
+	}
+
+	shift.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.shiftRepo.Create(ctx, shift); err != nil {
+		return nil, NewServiceError("failed to create staff shift", err)
+	}
+	return dto.ToStaffShiftResponseDTO(shift), nil
+}
+
+// UpdateStaffShift changes the times, day or effective dates of a shift. Appointments already
+// booked are kept even if they now fall outside the staff member's shifts. Only owners and
+// managers can change shifts.
+func (s *staffShiftServiceImpl) UpdateStaffShift(ctx context.Context, id string, shiftDTO dto.UpdateStaffShiftDTO) (*dto.StaffShiftResponseDTO, error) {
+	if err := s.validator.Struct(shiftDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	shift, err := s.getShift(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireManager(ctx, s.staffRepo, shift.BusinessID, "schedule staff shifts"); err != nil {
+		return nil, err
+	}
+	if err := shift.CheckVersion(shift.TableName(), shiftDTO.ExpectedVersion); err != nil {
+		return nil, err
+	}
+
+	if shiftDTO.Weekday != nil {
+		shift.Weekday = shiftDTO.Weekday
+	}
+	if shiftDTO.ShiftDate != nil {
+		if shift.ShiftDate, err = parseShiftDate(shiftDTO.ShiftDate); err != nil {
+			return nil, err
+		}
+	}
+	if shiftDTO.StartTime != nil {
+		shift.StartTime = *shiftDTO.StartTime
+	}
+	if shiftDTO.EndTime != nil {
+		shift.EndTime = *shiftDTO.EndTime
+	}
+	for _, date := range []struct {
+		update dto.Optional[string]
+		field  **time.Time
+	}{
+		{shiftDTO.EffectiveFrom, &shift.EffectiveFrom},
+		{shiftDTO.EffectiveUntil, &shift.EffectiveUntil},
+	} {
+		if !date.update.Set {
+			continue
+		}
+		if *date.field, err = parseShiftDate(date.update.Value); err != nil {
+			return nil, err
+		}
+	}
+	shiftDTO.Notes.Apply(&shift.Notes)
+	if err := shift.Validate(); err != nil {
+		return nil, toValidationError(err)
+	}
+
+	shift.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.shiftRepo.Update(ctx, shift); err != nil {
+		return nil, NewServiceError("failed to update staff shift", err)
+	}
+	return dto.ToStaffShiftResponseDTO(shift), nil
+}
+
+// DeleteStaffShift removes a shift from a staff member's rota. A staff member left without
+// shifts is no longer on a rota and can be booked whenever the business is open. Only owners
+// and managers can remove shifts.
+func (s *staffShiftServiceImpl) DeleteStaffShift(ctx context.Context, id string) error {
+	shift, err := s.getShift(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := requireManager(ctx, s.staffRepo, shift.BusinessID, "schedule staff shifts"); err != nil {
+		return err
+	}
+	if err := s.shiftRepo.Delete(ctx, id); err != nil {
+		return NewServiceError("failed to delete staff shift", err)
+	}
+	return nil
+}
+
+// GetStaffShifts returns the shifts of a staff member, weekly ones first. Any staff member of
+// the business can see them.
+func (s *staffShiftServiceImpl) GetStaffShifts(ctx context.Context, staffID string) ([]*dto.StaffShiftResponseDTO, error) {
+	if staffID == "" {
+		return nil, validation.NewValidationError("staff_id is required")
+	}
+	staff, err := s.getStaff(ctx, staffID)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireStaff(ctx, s.staffRepo, staff.BusinessID, "view staff shifts"); err != nil {
+		return nil, err
+	}
+	shifts, err := s.shiftRepo.FindByStaffIDs(ctx, []string{staffID})
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve staff shifts", err)
+	}
+	slices.SortStableFunc(shifts, func(a, b *domain.StaffShift) int {
+		if a.Kind == b.Kind {
+			return 0
+		}
+		if a.Kind == domain.StaffShiftRecurring {
+			return -1
+		}
+		return 1
+	})
+
+	result := make([]*dto.StaffShiftResponseDTO, len(shifts))
+	for i, shift := range shifts {
+		result[i] = dto.ToStaffShiftResponseDTO(shift)
+	}
+	return result, nil
+}
+
+// GetRota returns the shifts worked during the week, Monday to Sunday, of a business's active
+// staff or of one staff member. Any staff member of the business can see the rota.
+func (s *staffShiftServiceImpl) GetRota(ctx context.Context, query dto.RotaQueryDTO) (*dto.RotaDTO, error) {
+	if err := s.validator.Struct(query); err != nil {
+		return nil, validation.FromError(err)
+	}
+	if err := requireStaff(ctx, s.staffRepo, query.BusinessID, "view the staff rota"); err != nil {
+		return nil, err
+	}
+	location, err := s.location(ctx, query.BusinessID)
+	if err != nil {
+		return nil, err
+	}
+	weekOf, err := domain.ParseBookingDate(query.WeekOf, location)
+	if err != nil {
+		return nil, toValidationError(err)
+	}
+
+	var staffIDs []string
+	if query.StaffID != nil {
+		staff, err := s.getStaff(ctx, *query.StaffID)
+		if err != nil {
+			return nil, err
+		}
+		if staff.BusinessID != query.BusinessID {
+			return nil, NewNotFoundError("staff", "id", *query.StaffID)
+		}
+		staffIDs = []string{staff.ID}
+	} else {
+		staff, err := s.staffRepo.FindActiveByBusinessID(ctx, query.BusinessID)
+		if err != nil {
+			return nil, NewServiceError("failed to retrieve staff", err)
+		}
+		for _, member := range staff {
+			staffIDs = append(staffIDs, member.ID)
+		}
+	}
+
+	var shifts []*domain.StaffShift
+	if len(staffIDs) > 0 {
+		if shifts, err = s.shiftRepo.FindByStaffIDs(ctx, staffIDs); err != nil {
+			return nil, NewServiceError("failed to retrieve staff shifts", err)
+		}
+	}
+	return dto.ToRotaDTO(query.BusinessID, domain.NewRota(weekOf, location, staffIDs, shifts)), nil
+}
+
+// getShift retrieves a shift, failing with a not found error when there is none
+func (s *staffShiftServiceImpl) getShift(ctx context.Context, id string) (*domain.StaffShift, error) {
+	if id == "" {
+		return nil, validation.NewValidationError("id is required")
+	}
+	shift, err := s.shiftRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("staff shift", "id", id)
+		}
+		return nil, NewServiceError("failed to retrieve staff shift", err)
+	}
+	return shift, nil
+}
+
+// getStaff retrieves a staff member, failing with a not found error when there is none
+func (s *staffShiftServiceImpl) getStaff(ctx context.Context, id string) (*domain.Staff, error) {
+	staff, err := s.staffRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("staff", "id", id)
+		}
+		return nil, NewServiceError("failed to retrieve staff", err)
+	}
+	return staff, nil
+}
+
+// location returns the time zone of a business, in which shifts are worked
+func (s *staffShiftServiceImpl) location(ctx context.Context, businessID string) (*time.Location, error) {
+	business, err := s.businessRepo.GetByID(ctx, businessID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("business", "id", businessID)
+		}
+		return nil, NewServiceError("failed to retrieve business", err)
+	}
+	return domain.NewAvailabilityRules(business, nil).Location, nil
+}
+
+// parseShiftDate parses an optional YYYY-MM-DD date of a shift, nil when there is none. Dates
+// are kept as UTC midnight, as date columns are read back.
+func parseShiftDate(date *string) (*time.Time, error) {
+	if date == nil {
+		return nil, nil
+	}
+	day, err := domain.ParseBookingDate(*date, time.UTC)
+	if err != nil {
+		return nil, toValidationError(err)
+	}
+	return &day, nil
+}
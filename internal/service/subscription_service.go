@@ -0,0 +1,310 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/resilience"
+	"github.com/assimoes/beautix/internal/infrastructure/stripe"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// SubscriptionService defines the service interface for the subscription tiers of businesses,
+// billed through Stripe Billing
+type SubscriptionService interface {
+	GetSubscription(ctx context.Context, businessID string) (*dto.BusinessSubscriptionDTO, error)
+	ChangeSubscriptionPlan(ctx context.Context, changeDTO dto.ChangeSubscriptionPlanDTO) (*dto.BusinessSubscriptionDTO, error)
+	// HandleStripeEvent mirrors a subscription event Stripe posted to the webhook, verifying its
+	// signature first
+	HandleStripeEvent(ctx context.Context, payload []byte, signature string) error
+}
+
+// subscriptionServiceImpl implements the SubscriptionService interface
+type subscriptionServiceImpl struct {
+	subscriptionRepo   domain.SubscriptionRepository
+	businessRepo       domain.BusinessRepository
+	staffRepo          domain.StaffRepository
+	entitlementService EntitlementService
+	billing            stripe.Billing
+	prices             map[domain.SubscriptionTier]string // The Stripe price of each paid tier
+	webhookSecret      string
+	validator          *validator.Validate
+	now                func() time.Time
+}
+
+// NewSubscriptionService creates a new subscription service, billing each paid tier at its
+// Stripe price and verifying webhook events with the endpoint's signing secret
+func NewSubscriptionService(
+	subscriptionRepo domain.SubscriptionRepository,
+	businessRepo domain.BusinessRepository,
+	staffRepo domain.StaffRepository,
+	entitlementService EntitlementService,
+	billing stripe.Billing,
+	prices map[domain.SubscriptionTier]string,
+	webhookSecret string,
+	validator *validator.Validate,
+) SubscriptionService {
+	return &subscriptionServiceImpl{
+		subscriptionRepo:   subscriptionRepo,
+		businessRepo:       businessRepo,
+		staffRepo:          staffRepo,
+		entitlementService: entitlementService,
+		billing:            billing,
+		prices:             prices,
+		webhookSecret:      webhookSecret,
+		validator:          validator,
+		now:                time.Now,
+	}
+}
+
+// GetSubscription returns the tier of a business, its subscription and what it uses of its
+// plan, to its managers and to platform admins
+func (s *subscriptionServiceImpl) GetSubscription(ctx context.Context, businessID string) (*dto.BusinessSubscriptionDTO, error) {
+	if !IsPlatformAdmin(ctx) {
+		if err := requireManager(ctx, s.staffRepo, businessID, "view the subscription of this business"); err != nil {
+			return nil, err
+		}
+	}
+	business, err := getBusiness(ctx, s.businessRepo, businessID)
+	if err != nil {
+		return nil, err
+	}
+	subscription, err := s.findSubscription(ctx, businessID)
+	if err != nil {
+		return nil, err
+	}
+	return s.toSubscriptionDTO(ctx, business.ID, business.Tier(), subscription)
+}
+
+// ChangeSubscriptionPlan moves a business to another tier. Moving to a paid tier subscribes the
+// business, or changes the price of its subscription with the period pro-rated; moving to the
+// free tier cancels the subscription at the end of the paid period. A business cannot move to a
+// tier whose plan allows less than it uses.
+func (s *subscriptionServiceImpl) ChangeSubscriptionPlan(ctx context.Context, changeDTO dto.ChangeSubscriptionPlanDTO) (*dto.BusinessSubscriptionDTO, error) {
+	if err := s.validator.Struct(changeDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	if changeDTO.Tier.IsPaid() && s.prices[changeDTO.Tier] == "" {
+		return nil, validation.NewValidationError(fmt.Sprintf("the %s tier is not available", changeDTO.Tier))
+	}
+	if err := requireOwner(ctx, s.staffRepo, changeDTO.BusinessID, "change the subscription of this business"); err != nil {
+		return nil, err
+	}
+	business, err := getBusiness(ctx, s.businessRepo, changeDTO.BusinessID)
+	if err != nil {
+		return nil, err
+	}
+	subscription, err := s.findSubscription(ctx, business.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if slices.Index(domain.SubscriptionTiers, changeDTO.Tier) < slices.Index(domain.SubscriptionTiers, business.Tier()) {
+		usage, err := s.entitlementService.Usage(ctx, business.ID)
+		if err != nil {
+			return nil, err
+		}
+		if err := domain.CheckDowngrade(changeDTO.Tier, usage); err != nil {
+			return nil, toValidationError(err)
+		}
+	}
+
+	var billed *stripe.Subscription
+	subscribed := subscription != nil && subscription.StripeSubscriptionID != nil && subscription.Status.GrantsTier()
+	switch {
+	case !changeDTO.Tier.IsPaid() && !subscribed:
+		return s.toSubscriptionDTO(ctx, business.ID, business.Tier(), subscription)
+	case !changeDTO.Tier.IsPaid():
+		billed, err = s.billing.CancelSubscription(ctx, *subscription.StripeSubscriptionID)
+	case subscribed:
+		billed, err = s.changePrice(ctx, *subscription.StripeSubscriptionID, changeDTO.Tier)
+	default:
+		subscription, billed, err = s.subscribe(ctx, business, subscription, changeDTO)
+	}
+	if err != nil {
+		return nil, toBillingError("failed to change subscription", err)
+	}
+
+	if changeDTO.Tier.IsPaid() {
+		subscription.Tier = changeDTO.Tier
+	}
+	subscription.Sync(billed.State(changeDTO.Tier, s.now()))
+	if err := s.subscriptionRepo.Sync(ctx, subscription); err != nil {
+		return nil, NewServiceError("failed to save subscription", err)
+	}
+	log.Info().Str("business_id", business.ID).Str("tier", string(changeDTO.Tier)).Str("status", string(subscription.Status)).
+		Msg("Subscription plan changed")
+	return s.toSubscriptionDTO(ctx, business.ID, subscription.EffectiveTier(), subscription)
+}
+
+// HandleStripeEvent mirrors a subscription event Stripe posted to the webhook. Events about
+// anything else are acknowledged and ignored.
+func (s *subscriptionServiceImpl) HandleStripeEvent(ctx context.Context, payload []byte, signature string) error {
+	event, err := stripe.ParseWebhook(payload, signature, s.webhookSecret, s.now())
+	if err != nil {
+		return err
+	}
+	switch event.Type {
+	case stripe.EventSubscriptionCreated, stripe.EventSubscriptionUpdated, stripe.EventSubscriptionDeleted:
+	default:
+		return nil
+	}
+	billed, err := event.Subscription()
+	if err != nil {
+		return validation.FromError(err)
+	}
+	tier := s.tierOf(billed.PriceID())
+
+	subscription, err := s.subscriptionRepo.FindByStripeSubscription(ctx, billed.ID)
+	if errors.Is(err, gorm.ErrRecordNotFound) && billed.Metadata["business_id"] != "" {
+		// Subscriptions made outside the API, or whose creation was not saved, are found by the
+		// business they were made for
+		subscription, err = s.findSubscription(ctx, billed.Metadata["business_id"])
+		if err == nil && subscription == nil {
+			if !tier.IsPaid() {
+				log.Warn().Str("event_id", event.ID).Str("price_id", billed.PriceID()).Msg("Stripe subscription with an unknown price ignored")
+				return nil
+			}
+			subscription = &domain.BusinessSubscription{BusinessID: billed.Metadata["business_id"], Tier: tier, StripeCustomerID: billed.CustomerID}
+		}
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Warn().Str("event_id", event.ID).Str("subscription_id", billed.ID).Msg("Stripe subscription of no business ignored")
+		return nil
+	}
+	if err != nil {
+		return NewServiceError("failed to retrieve subscription", err)
+	}
+
+	if !subscription.Sync(billed.State(tier, event.CreatedAt())) {
+		return nil
+	}
+	if err := s.subscriptionRepo.Sync(ctx, subscription); err != nil {
+		return NewServiceError("failed to save subscription", err)
+	}
+	log.Info().Str("event_id", event.ID).Str("business_id", subscription.BusinessID).Str("status", string(subscription.Status)).
+		Msg("Subscription synced from Stripe")
+	return nil
+}
+
+// subscribe subscribes a business to a paid tier, reusing the Stripe customer of an earlier
+// subscription
+func (s *subscriptionServiceImpl) subscribe(ctx context.Context, business *domain.Business, subscription *domain.BusinessSubscription, changeDTO dto.ChangeSubscriptionPlanDTO) (*domain.BusinessSubscription, *stripe.Subscription, error) {
+	var paymentMethodID string
+	if changeDTO.PaymentMethodID != nil {
+		paymentMethodID = *changeDTO.PaymentMethodID
+	}
+	metadata := map[string]string{"business_id": business.ID}
+	if subscription == nil {
+		if paymentMethodID == "" {
+			return nil, nil, validation.NewValidationError("payment_method_id is required to subscribe to a paid tier")
+		}
+		customer, err := s.billing.CreateCustomer(ctx, stripe.CustomerParams{
+			Email:           business.Email,
+			Name:            business.Name,
+			PaymentMethodID: paymentMethodID,
+			Metadata:        metadata,
+			IdempotencyKey:  "customer-" + business.ID,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		subscription = &domain.BusinessSubscription{BusinessID: business.ID, StripeCustomerID: customer.ID}
+	} else if paymentMethodID != "" {
+		if err := s.billing.AttachPaymentMethod(ctx, subscription.StripeCustomerID, paymentMethodID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	billed, err := s.billing.CreateSubscription(ctx, stripe.SubscriptionParams{
+		CustomerID:      subscription.StripeCustomerID,
+		PriceID:         s.prices[changeDTO.Tier],
+		PaymentMethodID: paymentMethodID,
+		Metadata:        metadata,
+		IdempotencyKey:  fmt.Sprintf("subscription-%s-%s-%d", business.ID, changeDTO.Tier, subscription.Version),
+	})
+	return subscription, billed, err
+}
+
+// changePrice moves a subscription to the price of another paid tier
+func (s *subscriptionServiceImpl) changePrice(ctx context.Context, stripeSubscriptionID string, tier domain.SubscriptionTier) (*stripe.Subscription, error) {
+	billed, err := s.billing.GetSubscription(ctx, stripeSubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	return s.billing.ChangeSubscriptionPrice(ctx, billed, s.prices[tier])
+}
+
+// findSubscription finds the subscription of a business, or nil when it never subscribed
+func (s *subscriptionServiceImpl) findSubscription(ctx context.Context, businessID string) (*domain.BusinessSubscription, error) {
+	subscription, err := s.subscriptionRepo.FindByBusiness(ctx, businessID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve subscription", err)
+	}
+	return subscription, nil
+}
+
+// tierOf returns the paid tier billed at a Stripe price, or an empty tier for unknown prices
+func (s *subscriptionServiceImpl) tierOf(priceID string) domain.SubscriptionTier {
+	for tier, price := range s.prices {
+		if price != "" && price == priceID {
+			return tier
+		}
+	}
+	return ""
+}
+
+// toSubscriptionDTO describes the tier of a business, its subscription and its usage
+func (s *subscriptionServiceImpl) toSubscriptionDTO(ctx context.Context, businessID string, tier domain.SubscriptionTier, subscription *domain.BusinessSubscription) (*dto.BusinessSubscriptionDTO, error) {
+	usage, err := s.entitlementService.Usage(ctx, businessID)
+	if err != nil {
+		return nil, err
+	}
+	subscriptionDTO := &dto.BusinessSubscriptionDTO{BusinessID: businessID, Tier: tier, Usage: []*dto.EntitlementUsageDTO{}}
+	if subscription != nil {
+		subscriptionDTO.SubscribedTier = &subscription.Tier
+		subscriptionDTO.Status = &subscription.Status
+		subscriptionDTO.CurrentPeriodEnd = subscription.CurrentPeriodEnd
+		subscriptionDTO.TrialEndsAt = subscription.TrialEndsAt
+		subscriptionDTO.CancelAtPeriodEnd = subscription.CancelAtPeriodEnd
+	}
+	plan := domain.PlanFor(tier)
+	for _, entitlement := range domain.Entitlements {
+		usageDTO := &dto.EntitlementUsageDTO{Entitlement: entitlement, Used: usage[entitlement]}
+		if limit := plan.Limit(entitlement); limit != domain.Unlimited {
+			usageDTO.Limit = &limit
+		}
+		subscriptionDTO.Usage = append(subscriptionDTO.Usage, usageDTO)
+	}
+	return subscriptionDTO, nil
+}
+
+// toBillingError maps a failure to bill a subscription to an error for the client
+func toBillingError(message string, err error) error {
+	var validationErr *validation.ValidationError
+	if errors.As(err, &validationErr) {
+		return err
+	}
+	if errors.Is(err, stripe.ErrNotConfigured) {
+		return validation.NewValidationError("paid subscriptions are not available")
+	}
+	if errors.Is(err, resilience.ErrCircuitOpen) {
+		return validation.NewValidationError("billing is temporarily unavailable, please try again later")
+	}
+	var stripeErr *stripe.Error
+	if errors.As(err, &stripeErr) && stripeErr.IsCardError() {
+		return validation.NewValidationError("the card was declined: " + stripeErr.Message)
+	}
+	return NewServiceError(message, err)
+}
@@ -0,0 +1,380 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// TimeClockService defines the service interface for staff clocking in and out, and the
+// timesheets of pay periods built from their time entries
+type TimeClockService interface {
+	ClockIn(ctx context.Context, clockDTO dto.ClockDTO) (*dto.TimeEntryResponseDTO, error)
+	ClockOut(ctx context.Context, clockDTO dto.ClockDTO) (*dto.TimeEntryResponseDTO, error)
+	GetCurrentTimeEntry(ctx context.Context, businessID string) (*dto.TimeEntryResponseDTO, error)
+	AdjustTimeEntry(ctx context.Context, id string, adjustDTO dto.AdjustTimeEntryDTO) (*dto.TimeEntryResponseDTO, error)
+	GetTimeEntries(ctx context.Context, query dto.TimesheetQueryDTO) ([]*dto.TimeEntryResponseDTO, error)
+	GetTimesheet(ctx context.Context, query dto.TimesheetQueryDTO) (*dto.TimesheetDTO, error)
+}
+
+// timeClockServiceImpl implements the TimeClockService interface
+type timeClockServiceImpl struct {
+	entryRepo    domain.TimeEntryRepository
+	shiftRepo    domain.StaffShiftRepository
+	staffRepo    domain.StaffRepository
+	businessRepo domain.BusinessRepository
+	userRepo     domain.BaseRepository[domain.User]
+	validator    *validator.Validate
+	now          func() time.Time
+}
+
+// NewTimeClockService creates a new time clock service
+func NewTimeClockService(
+	entryRepo domain.TimeEntryRepository,
+	shiftRepo domain.StaffShiftRepository,
+	staffRepo domain.StaffRepository,
+	businessRepo domain.BusinessRepository,
+	userRepo domain.BaseRepository[domain.User],
+	validator *validator.Validate,
+) TimeClockService {
+	return &timeClockServiceImpl{
+		entryRepo:    entryRepo,
+		shiftRepo:    shiftRepo,
+		staffRepo:    staffRepo,
+		businessRepo: businessRepo,
+		userRepo:     userRepo,
+		validator:    validator,
+		now:          time.Now,
+	}
+}
+
+// ClockIn starts a time entry for the caller at the business. The entry is flagged when it
+// does not start within one of the caller's shifts, give or take domain.TimeEntryGrace.
+func (s *timeClockServiceImpl) ClockIn(ctx context.Context, clockDTO dto.ClockDTO) (*dto.TimeEntryResponseDTO, error) {
+	if err := s.validator.Struct(clockDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	staff, err := s.callerStaff(ctx, clockDTO.BusinessID, "clock in")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.entryRepo.FindOpenByStaffID(ctx, staff.ID); err == nil {
+		return nil, validation.NewValidationError("already clocked in; clock out first")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, NewServiceError("failed to retrieve time entry", err)
+	}
+
+	entry := &domain.TimeEntry{
+		BusinessID: staff.BusinessID,
+		StaffID:    staff.ID,
+		ClockIn:    s.now().UTC().Truncate(time.Second),
+		Notes:      clockDTO.Notes,
+	}
+	if err := s.review(ctx, entry); err != nil {
+		return nil, err
+	}
+	entry.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.entryRepo.Create(ctx, entry); err != nil {
+		return nil, NewServiceError("failed to create time entry", err)
+	}
+	return dto.ToTimeEntryResponseDTO(entry), nil
+}
+
+// ClockOut ends the time entry the caller is clocked in on at the business, flagging it when
+// it ran past the caller's shifts or for longer than domain.MaxTimeEntryDuration
+func (s *timeClockServiceImpl) ClockOut(ctx context.Context, clockDTO dto.ClockDTO) (*dto.TimeEntryResponseDTO, error) {
+	if err := s.validator.Struct(clockDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	staff, err := s.callerStaff(ctx, clockDTO.BusinessID, "clock out")
+	if err != nil {
+		return nil, err
+	}
+	entry, err := s.entryRepo.FindOpenByStaffID(ctx, staff.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, validation.NewValidationError("not clocked in")
+		}
+		return nil, NewServiceError("failed to retrieve time entry", err)
+	}
+
+	if err := entry.Close(s.now().UTC().Truncate(time.Second)); err != nil {
+		return nil, toValidationError(err)
+	}
+	if clockDTO.Notes != nil {
+		entry.Notes = clockDTO.Notes
+	}
+	if err := s.review(ctx, entry); err != nil {
+		return nil, err
+	}
+	entry.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.entryRepo.Update(ctx, entry); err != nil {
+		return nil, NewServiceError("failed to update time entry", err)
+	}
+	return dto.ToTimeEntryResponseDTO(entry), nil
+}
+
+// GetCurrentTimeEntry returns the entry the caller is clocked in on at the business, nil when
+// they are clocked out
+func (s *timeClockServiceImpl) GetCurrentTimeEntry(ctx context.Context, businessID string) (*dto.TimeEntryResponseDTO, error) {
+	if businessID == "" {
+		return nil, validation.NewValidationError("business_id is required")
+	}
+	staff, err := s.callerStaff(ctx, businessID, "view time entries")
+	if err != nil {
+		return nil, err
+	}
+	entry, err := s.entryRepo.FindOpenByStaffID(ctx, staff.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, NewServiceError("failed to retrieve time entry", err)
+	}
+	return dto.ToTimeEntryResponseDTO(entry), nil
+}
+
+// AdjustTimeEntry corrects the times or notes of an entry, such as a forgotten clock-out, and
+// flags it again. Only owners and managers can adjust entries, and the entry records who did.
+func (s *timeClockServiceImpl) AdjustTimeEntry(ctx context.Context, id string, adjustDTO dto.AdjustTimeEntryDTO) (*dto.TimeEntryResponseDTO, error) {
+	if err := s.validator.Struct(adjustDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	entry, err := s.entryRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("time entry", "id", id)
+		}
+		return nil, NewServiceError("failed to retrieve time entry", err)
+	}
+	if err := requireManager(ctx, s.staffRepo, entry.BusinessID, "adjust time entries"); err != nil {
+		return nil, err
+	}
+	if err := entry.CheckVersion(entry.TableName(), adjustDTO.ExpectedVersion); err != nil {
+		return nil, err
+	}
+
+	if adjustDTO.ClockIn != nil || adjustDTO.ClockOut != nil {
+		if adjustDTO.ClockIn != nil {
+			entry.ClockIn = adjustDTO.ClockIn.UTC()
+		}
+		if adjustDTO.ClockOut != nil {
+			clockOut := adjustDTO.ClockOut.UTC()
+			entry.ClockOut = &clockOut
+		}
+		if entry.ClockIn.After(s.now()) || (entry.ClockOut != nil && entry.ClockOut.After(s.now())) {
+			return nil, validation.NewValidationError("time entries cannot be in the future")
+		}
+		entry.AdjustedBy = GetUserIDFromContext(ctx)
+	}
+	adjustDTO.Notes.Apply(&entry.Notes)
+	if err := entry.Validate(); err != nil {
+		return nil, toValidationError(err)
+	}
+	if err := s.review(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	entry.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.entryRepo.Update(ctx, entry); err != nil {
+		return nil, NewServiceError("failed to update time entry", err)
+	}
+	return dto.ToTimeEntryResponseDTO(entry), nil
+}
+
+// GetTimeEntries returns the entries overlapping a period, ordered by clock-in time. Owners
+// and managers see every staff member's; other staff only their own.
+func (s *timeClockServiceImpl) GetTimeEntries(ctx context.Context, query dto.TimesheetQueryDTO) ([]*dto.TimeEntryResponseDTO, error) {
+	if err := s.validator.Struct(query); err != nil {
+		return nil, validation.FromError(err)
+	}
+	staff, err := s.callerStaff(ctx, query.BusinessID, "view time entries")
+	if err != nil {
+		return nil, err
+	}
+	if !staff.CanManage() {
+		if query.StaffID != nil && *query.StaffID != staff.ID {
+			return nil, NewForbiddenError("view the time entries of other staff")
+		}
+		query.StaffID = &staff.ID
+	}
+	start, end, err := s.period(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.entryRepo.FindInRange(ctx, query.BusinessID, query.StaffID, start, end)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve time entries", err)
+	}
+	result := make([]*dto.TimeEntryResponseDTO, len(entries))
+	for i, entry := range entries {
+		result[i] = dto.ToTimeEntryResponseDTO(entry)
+	}
+	return result, nil
+}
+
+// GetTimesheet totals the time each staff member of the business worked during a pay period,
+// with the CSV file payroll software imports. Staff who left during the period are included
+// when they clocked in. Only owners and managers can see timesheets.
+func (s *timeClockServiceImpl) GetTimesheet(ctx context.Context, query dto.TimesheetQueryDTO) (*dto.TimesheetDTO, error) {
+	if err := s.validator.Struct(query); err != nil {
+		return nil, validation.FromError(err)
+	}
+	if err := requireManager(ctx, s.staffRepo, query.BusinessID, "view timesheets"); err != nil {
+		return nil, err
+	}
+	start, end, err := s.period(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.entryRepo.FindInRange(ctx, query.BusinessID, query.StaffID, start, end)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve time entries", err)
+	}
+	var members []*domain.Staff
+	if query.StaffID != nil {
+		staff, err := s.staffRepo.GetByID(ctx, *query.StaffID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewServiceError("failed to retrieve staff", err)
+		}
+		if staff == nil || staff.BusinessID != query.BusinessID {
+			return nil, NewNotFoundError("staff", "id", *query.StaffID)
+		}
+		members = []*domain.Staff{staff}
+	} else if members, err = s.staffRepo.FindActiveByBusinessID(ctx, query.BusinessID); err != nil {
+		return nil, NewServiceError("failed to retrieve staff", err)
+	}
+
+	var staffIDs []string
+	for _, member := range members {
+		staffIDs = append(staffIDs, member.ID)
+	}
+	for _, entry := range entries {
+		if !slices.Contains(staffIDs, entry.StaffID) {
+			staffIDs = append(staffIDs, entry.StaffID)
+		}
+	}
+	names, err := s.staffNames(ctx, staffIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	timesheet := domain.NewTimesheet(start, end, staffIDs, names, entries)
+	var csv strings.Builder
+	if err := timesheet.WriteCSV(&csv); err != nil {
+		return nil, NewServiceError("failed to write timesheet", err)
+	}
+	return dto.ToTimesheetDTO(query.BusinessID, timesheet, csv.String()), nil
+}
+
+// callerStaff returns the caller's active staff record at the business, refusing the action
+// when they have none
+func (s *timeClockServiceImpl) callerStaff(ctx context.Context, businessID, action string) (*domain.Staff, error) {
+	userID := GetUserIDFromContext(ctx)
+	if userID == nil {
+		return nil, NewForbiddenError(action)
+	}
+	staff, err := s.staffRepo.FindByBusinessAndUser(ctx, businessID, *userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewForbiddenError(action)
+		}
+		return nil, NewServiceError("failed to retrieve staff member", err)
+	}
+	if !staff.IsActive {
+		return nil, NewForbiddenError(action)
+	}
+	return staff, nil
+}
+
+// review flags an entry against the shifts of its staff member
+func (s *timeClockServiceImpl) review(ctx context.Context, entry *domain.TimeEntry) error {
+	location, err := s.location(ctx, entry.BusinessID)
+	if err != nil {
+		return err
+	}
+	shifts, err := s.shiftRepo.FindByStaffIDs(ctx, []string{entry.StaffID})
+	if err != nil {
+		return NewServiceError("failed to retrieve staff shifts", err)
+	}
+	if err := entry.Review(shifts, location); err != nil {
+		return NewServiceError("failed to flag time entry", err)
+	}
+	return nil
+}
+
+// period parses the days of a pay period in the business's time zone, returning the start of
+// its first day and of the day after its last
+func (s *timeClockServiceImpl) period(ctx context.Context, query dto.TimesheetQueryDTO) (time.Time, time.Time, error) {
+	location, err := s.location(ctx, query.BusinessID)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	start, err := domain.ParseBookingDate(query.StartDate, location)
+	if err != nil {
+		return time.Time{}, time.Time{}, toValidationError(err)
+	}
+	last, err := domain.ParseBookingDate(query.EndDate, location)
+	if err != nil {
+		return time.Time{}, time.Time{}, toValidationError(err)
+	}
+	if last.Before(start) {
+		return time.Time{}, time.Time{}, validation.NewValidationError("end_date must not be before start_date")
+	}
+	end := last.AddDate(0, 0, 1)
+	if end.After(start.AddDate(0, 0, domain.MaxTimesheetDays)) {
+		return time.Time{}, time.Time{}, validation.NewValidationError(fmt.Sprintf("a pay period cannot exceed %d days", domain.MaxTimesheetDays))
+	}
+	return start, end, nil
+}
+
+// location returns the time zone of a business, in which shifts and pay periods are kept
+func (s *timeClockServiceImpl) location(ctx context.Context, businessID string) (*time.Location, error) {
+	business, err := s.businessRepo.GetByID(ctx, businessID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("business", "id", businessID)
+		}
+		return nil, NewServiceError("failed to retrieve business", err)
+	}
+	return domain.NewAvailabilityRules(business, nil).Location, nil
+}
+
+// staffNames returns the full names of staff members by their ID
+func (s *timeClockServiceImpl) staffNames(ctx context.Context, staffIDs []string) (map[string]string, error) {
+	names := map[string]string{}
+	if len(staffIDs) == 0 {
+		return names, nil
+	}
+	members, err := s.staffRepo.GetByIDs(ctx, staffIDs)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve staff", err)
+	}
+	userIDs := make([]string, len(members))
+	for i, member := range members {
+		userIDs[i] = member.UserID
+	}
+	users, err := s.userRepo.GetByIDs(ctx, userIDs)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve users", err)
+	}
+	byUser := map[string]string{}
+	for _, user := range users {
+		byUser[user.ID] = user.GetFullName()
+	}
+	for _, member := range members {
+		names[member.ID] = byUser[member.UserID]
+	}
+	return names, nil
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// TrashService defines the service interface for recovering the deleted records of a business
+type TrashService interface {
+	ListTrash(ctx context.Context, filterDTO dto.TrashFilterDTO, page, pageSize int) ([]*dto.TrashItemDTO, int64, error)
+	Restore(ctx context.Context, restoreDTO dto.RestoreDTO) (*dto.TrashItemDTO, error)
+}
+
+// trashServiceImpl implements the TrashService interface
+type trashServiceImpl struct {
+	clientRepo      domain.BaseRepository[domain.Client]
+	serviceRepo     domain.BaseRepository[domain.Service]
+	appointmentRepo domain.AppointmentRepository
+	staffRepo       domain.StaffRepository
+	validator       *validator.Validate
+}
+
+// NewTrashService creates a new trash service
+func NewTrashService(
+	clientRepo domain.BaseRepository[domain.Client],
+	serviceRepo domain.BaseRepository[domain.Service],
+	appointmentRepo domain.AppointmentRepository,
+	staffRepo domain.StaffRepository,
+	validator *validator.Validate,
+) TrashService {
+	return &trashServiceImpl{
+		clientRepo:      clientRepo,
+		serviceRepo:     serviceRepo,
+		appointmentRepo: appointmentRepo,
+		staffRepo:       staffRepo,
+		validator:       validator,
+	}
+}
+
+// ListTrash lists the clients, services or appointments of a business deleted within the
+// retention window, most recently deleted first. Only the owner may browse the trash.
+func (s *trashServiceImpl) ListTrash(ctx context.Context, filterDTO dto.TrashFilterDTO, page, pageSize int) ([]*dto.TrashItemDTO, int64, error) {
+	if err := s.validator.Struct(filterDTO); err != nil {
+		return nil, 0, validation.FromError(err)
+	}
+	if err := s.ensureOwner(ctx, filterDTO.BusinessID); err != nil {
+		return nil, 0, err
+	}
+
+	pagination := ValidatePagination(&dto.PaginationRequest{Page: page, PageSize: pageSize})
+	criteria := map[string]any{"business_id": filterDTO.BusinessID}
+	items, total, err := s.deleted(ctx, domain.TrashEntityType(filterDTO.EntityType), criteria, pagination.Page, pagination.PageSize)
+	if err != nil {
+		return nil, 0, NewServiceError("failed to list deleted records", err)
+	}
+	return items, total, nil
+}
+
+// Restore puts a deleted client, service or appointment of a business back, as long as it is
+// still in the trash. Appointments need their client in place, and upcoming ones a staff
+// member free at their time. Only the owner may restore records.
+func (s *trashServiceImpl) Restore(ctx context.Context, restoreDTO dto.RestoreDTO) (*dto.TrashItemDTO, error) {
+	if err := s.validator.Struct(restoreDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	if err := s.ensureOwner(ctx, restoreDTO.BusinessID); err != nil {
+		return nil, err
+	}
+	ctx, err := actFor(ctx, restoreDTO.BusinessID, "restore deleted records")
+	if err != nil {
+		return nil, err
+	}
+
+	entityType := domain.TrashEntityType(restoreDTO.EntityType)
+	criteria := map[string]any{"business_id": restoreDTO.BusinessID, "id": restoreDTO.ID}
+	items, _, err := s.deleted(ctx, entityType, criteria, 1, 1)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve deleted record", err)
+	}
+	if len(items) == 0 {
+		return nil, NewNotFoundError(string(entityType), "id", restoreDTO.ID)
+	}
+
+	var restore func(ctx context.Context, id string) error
+	switch entityType {
+	case domain.TrashEntityClient:
+		restore = s.clientRepo.Restore
+	case domain.TrashEntityService:
+		restore = s.serviceRepo.Restore
+	case domain.TrashEntityAppointment:
+		if err := s.checkAppointmentRestore(ctx, restoreDTO.ID); err != nil {
+			return nil, err
+		}
+		restore = s.appointmentRepo.Restore
+	}
+	if err := restore(ctx, restoreDTO.ID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError(string(entityType), "id", restoreDTO.ID)
+		}
+		return nil, NewServiceError(fmt.Sprintf("failed to restore %s", entityType), err)
+	}
+	return items[0], nil
+}
+
+// deleted lists the deleted records of a type matching the criteria that are still in the trash
+func (s *trashServiceImpl) deleted(ctx context.Context, entityType domain.TrashEntityType, criteria map[string]any, page, pageSize int) ([]*dto.TrashItemDTO, int64, error) {
+	since := domain.TrashCutoff(time.Now())
+	switch entityType {
+	case domain.TrashEntityClient:
+		clients, total, err := s.clientRepo.ListDeleted(ctx, criteria, since, page, pageSize)
+		return convertTrash(clients, dto.ToClientTrashItemDTO), total, err
+	case domain.TrashEntityService:
+		services, total, err := s.serviceRepo.ListDeleted(ctx, criteria, since, page, pageSize)
+		return convertTrash(services, dto.ToServiceTrashItemDTO), total, err
+	case domain.TrashEntityAppointment:
+		appointments, total, err := s.appointmentRepo.ListDeleted(ctx, criteria, since, page, pageSize)
+		return convertTrash(appointments, dto.ToAppointmentTrashItemDTO), total, err
+	}
+	return nil, 0, fmt.Errorf("%w: %s records cannot be restored", domain.ErrValidation, entityType)
+}
+
+// checkAppointmentRestore checks that a deleted appointment can be put back in the book
+func (s *trashServiceImpl) checkAppointmentRestore(ctx context.Context, id string) error {
+	appointments, _, err := s.appointmentRepo.ListDeleted(ctx, map[string]any{"id": id}, time.Time{}, 1, 1)
+	if err != nil {
+		return NewServiceError("failed to retrieve deleted appointment", err)
+	}
+	if len(appointments) == 0 {
+		return NewNotFoundError(string(domain.TrashEntityAppointment), "id", id)
+	}
+	appointment := appointments[0]
+
+	client, err := s.clientRepo.GetByID(ctx, appointment.ClientID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewServiceError("failed to retrieve client", err)
+		}
+		client = nil
+	}
+	overlapping, err := s.appointmentRepo.CheckOverlap(ctx, appointment.StaffID, appointment.StartTime, appointment.EndTime, &appointment.ID)
+	if err != nil {
+		return NewServiceError("failed to check staff availability", err)
+	}
+	return toValidationError(appointment.CheckRestore(client, overlapping, time.Now()))
+}
+
+// ensureOwner checks that the caller owns the business
+func (s *trashServiceImpl) ensureOwner(ctx context.Context, businessID string) error {
+	userID := GetUserIDFromContext(ctx)
+	if userID == nil {
+		return NewForbiddenError("restore deleted records")
+	}
+	staff, err := s.staffRepo.FindByBusinessAndUser(ctx, businessID, *userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return NewForbiddenError("restore deleted records")
+		}
+		return NewServiceError("failed to retrieve staff member", err)
+	}
+	if !staff.IsOwner() {
+		return NewForbiddenError("restore deleted records")
+	}
+	return nil
+}
+
+// convertTrash converts deleted entities to trash items
+func convertTrash[T any](entities []*T, convert func(*T) *dto.TrashItemDTO) []*dto.TrashItemDTO {
+	items := make([]*dto.TrashItemDTO, len(entities))
+	for i, entity := range entities {
+		items[i] = convert(entity)
+	}
+	return items
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/notification"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// TrialService defines the service interface for the free trials of paid tiers
+type TrialService interface {
+	GetTrialStatus(ctx context.Context, businessID string) (*dto.TrialStatusDTO, error)
+	// ProcessTrials emails the owners of trials ending a week and a day before they end and when
+	// they do, and restricts the businesses whose grace period has passed to the free tier
+	ProcessTrials(ctx context.Context, now time.Time) (*dto.TrialRunDTO, error)
+}
+
+// trialServiceImpl implements the TrialService interface
+type trialServiceImpl struct {
+	trialRepo        domain.TrialRepository
+	businessRepo     domain.BusinessRepository
+	subscriptionRepo domain.SubscriptionRepository
+	staffRepo        domain.StaffRepository
+	sender           notification.NotificationSender
+}
+
+// NewTrialService creates a new trial service
+func NewTrialService(
+	trialRepo domain.TrialRepository,
+	businessRepo domain.BusinessRepository,
+	subscriptionRepo domain.SubscriptionRepository,
+	staffRepo domain.StaffRepository,
+	sender notification.NotificationSender,
+) TrialService {
+	return &trialServiceImpl{
+		trialRepo:        trialRepo,
+		businessRepo:     businessRepo,
+		subscriptionRepo: subscriptionRepo,
+		staffRepo:        staffRepo,
+		sender:           sender,
+	}
+}
+
+// GetTrialStatus returns where a business is in its trial. Businesses trialing a Stripe
+// subscription are charged when the trial ends, so they have no grace period.
+func (s *trialServiceImpl) GetTrialStatus(ctx context.Context, businessID string) (*dto.TrialStatusDTO, error) {
+	if err := requireStaff(ctx, s.staffRepo, businessID, "view the trial of this business"); err != nil {
+		return nil, err
+	}
+	business, err := getBusiness(ctx, s.businessRepo, businessID)
+	if err != nil {
+		return nil, err
+	}
+	subscription, err := s.subscriptionRepo.FindByBusiness(ctx, businessID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, NewServiceError("failed to retrieve subscription", err)
+	}
+
+	now := time.Now()
+	status := &dto.TrialStatusDTO{BusinessID: business.ID, Tier: business.Tier(), State: domain.TrialNone}
+	if subscription != nil && subscription.Status.GrantsTier() {
+		if subscription.Status == domain.SubscriptionTrialing && business.TrialEndsAt != nil {
+			status.State = domain.TrialActive
+			status.TrialEndsAt = business.TrialEndsAt
+			status.DaysLeft = domain.DaysLeft(now, *business.TrialEndsAt)
+		}
+		return status, nil
+	}
+
+	status.State = business.TrialState(now)
+	if status.State == domain.TrialNone {
+		return status, nil
+	}
+	status.TrialEndsAt = business.TrialEndsAt
+	status.GraceEndsAt = business.GraceEndsAt()
+	status.ExpiredAt = business.TrialExpiredAt
+	switch status.State {
+	case domain.TrialActive:
+		status.DaysLeft = domain.DaysLeft(now, *status.TrialEndsAt)
+	case domain.TrialGrace:
+		status.DaysLeft = domain.DaysLeft(now, *status.GraceEndsAt)
+	}
+	return status, nil
+}
+
+// ProcessTrials sends the notices due and restricts the businesses whose grace period has
+// passed. Each notice is recorded before it is sent, so it is sent at most once.
+func (s *trialServiceImpl) ProcessTrials(ctx context.Context, now time.Time) (*dto.TrialRunDTO, error) {
+	businesses, err := s.trialRepo.FindEnding(ctx, now.Add(domain.TrialNoticeHorizon))
+	if err != nil {
+		return nil, NewServiceError("failed to find ending trials", err)
+	}
+
+	run := &dto.TrialRunDTO{Due: len(businesses)}
+	for _, business := range businesses {
+		tenantCtx := domain.WithTenant(ctx, domain.TenantContext{BusinessID: business.ID})
+		if business.TrialState(now) == domain.TrialExpired {
+			restricted, err := s.trialRepo.Restrict(tenantCtx, business.ID, *business.TrialEndsAt, now)
+			if err != nil {
+				log.Warn().Err(err).Str("business_id", business.ID).Msg("Failed to restrict business after its trial")
+				run.Failed++
+				continue
+			}
+			if restricted {
+				log.Info().Str("business_id", business.ID).Msg("Trial expired, business restricted to the free tier")
+				run.Restricted++
+			}
+			continue
+		}
+
+		notified, err := s.notify(tenantCtx, business, now)
+		if err != nil {
+			log.Warn().Err(err).Str("business_id", business.ID).Msg("Failed to send trial notice")
+			run.Failed++
+			continue
+		}
+		if notified {
+			run.Notified++
+		}
+	}
+	return run, nil
+}
+
+// notify sends the owner of a business the trial notice due, unless it was sent already
+func (s *trialServiceImpl) notify(ctx context.Context, business *domain.Business, now time.Time) (bool, error) {
+	kind, ok := business.DueTrialNotice(now)
+	if !ok {
+		return false, nil
+	}
+	recorded, err := s.trialRepo.RecordNotice(ctx, &domain.TrialNotice{
+		BusinessID:  business.ID,
+		Kind:        kind,
+		TrialEndsAt: *business.TrialEndsAt,
+		SentAt:      now,
+	})
+	if err != nil || !recorded {
+		return false, err
+	}
+
+	subject, body := trialNoticeMessage(business, kind, now)
+	err = s.sender.Send(ctx, notification.Message{
+		BusinessID: business.ID,
+		Channel:    domain.MessageChannelEmail,
+		Recipient:  business.Email,
+		Subject:    &subject,
+		Body:       body,
+	})
+	return err == nil, err
+}
+
+// trialNoticeMessage renders the email of a trial notice, with dates in the business's time zone
+func trialNoticeMessage(business *domain.Business, kind domain.TrialNoticeKind, now time.Time) (string, string) {
+	location, err := time.LoadLocation(business.TimeZone)
+	if err != nil {
+		location = time.UTC
+	}
+	tier := business.Tier()
+	trialEndsAt := business.TrialEndsAt.In(location)
+	free := domain.PlanFor(domain.TierFree)
+	restrictions := fmt.Sprintf("the free plan, which allows %d staff member, %d location, no campaigns and %d text messages a month",
+		free.Limit(domain.EntitlementStaff), free.Limit(domain.EntitlementLocations), free.Limit(domain.EntitlementSMSCredits))
+
+	switch kind {
+	case domain.TrialNoticeEnded:
+		graceEndsAt := business.GraceEndsAt().In(location)
+		return fmt.Sprintf("Your %s trial at %s has ended", tier, business.GetDisplayName()),
+			fmt.Sprintf("Your free trial of the %s plan has ended. You keep the %s plan until %s; after that, %s moves to %s.\n\n"+
+				"Subscribe before then to keep everything you use.",
+				tier, tier, graceEndsAt.Format("Monday 2 January"), business.GetDisplayName(), restrictions)
+	case domain.TrialNoticeOneDay:
+		return fmt.Sprintf("Your %s trial at %s ends tomorrow", tier, business.GetDisplayName()),
+			fmt.Sprintf("Your free trial of the %s plan ends on %s at %s. Subscribe to keep it; otherwise, %d days later, %s moves to %s.",
+				tier, trialEndsAt.Format("Monday 2 January"), trialEndsAt.Format("15:04"), int(domain.TrialGracePeriod.Hours()/24),
+				business.GetDisplayName(), restrictions)
+	default:
+		return fmt.Sprintf("Your %s trial at %s ends in %d days", tier, business.GetDisplayName(), domain.DaysLeft(now, *business.TrialEndsAt)),
+			fmt.Sprintf("Your free trial of the %s plan ends on %s. Subscribe before then to keep everything you use.",
+				tier, trialEndsAt.Format("Monday 2 January"))
+	}
+}
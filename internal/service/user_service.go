@@ -22,6 +22,7 @@ type UserService interface {
 	SearchUsers(ctx context.Context, query string, limit int) ([]*dto.UserResponseDTO, error)
 	ActivateUser(ctx context.Context, userID string) error
 	DeactivateUser(ctx context.Context, userID string) error
+	PreferredLocale(ctx context.Context) (string, error)
 }
 
 // userServiceImpl implements the UserService interface
@@ -50,15 +51,17 @@ func NewUserService(userRepo domain.UserRepository, businessRepo domain.Business
 				return user, user.Validate()
 			},
 			func(entity *domain.User, updateDTO dto.UpdateUserDTO) error {
+				if err := entity.CheckVersion(entity.TableName(), updateDTO.ExpectedVersion); err != nil {
+					return err
+				}
 				if updateDTO.FirstName != nil {
 					entity.FirstName = strings.TrimSpace(*updateDTO.FirstName)
 				}
 				if updateDTO.LastName != nil {
 					entity.LastName = strings.TrimSpace(*updateDTO.LastName)
 				}
-				if updateDTO.Phone != nil {
-					entity.Phone = updateDTO.Phone
-				}
+				updateDTO.Phone.Apply(&entity.Phone)
+				updateDTO.Locale.Apply(&entity.Locale)
 				if updateDTO.IsActive != nil {
 					entity.IsActive = *updateDTO.IsActive
 				}
@@ -79,7 +82,7 @@ func NewUserService(userRepo domain.UserRepository, businessRepo domain.Business
 func (s *userServiceImpl) Create(ctx context.Context, createDTO dto.CreateUserDTO) (*dto.UserResponseDTO, error) {
 	// Validate the input
 	if err := s.validator.Struct(createDTO); err != nil {
-		return nil, validation.NewValidationError(err.Error())
+		return nil, validation.FromError(err)
 	}
 
 	// Create the user first using the base service
@@ -275,4 +278,24 @@ func (s *userServiceImpl) DeactivateUser(ctx context.Context, userID string) err
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// PreferredLocale returns the locale the signed-in user chose to read the app in, or an empty
+// string when they chose none or nobody is signed in
+func (s *userServiceImpl) PreferredLocale(ctx context.Context) (string, error) {
+	userID := GetUserIDFromContext(ctx)
+	if userID == nil {
+		return "", nil
+	}
+	user, err := s.userRepo.GetByID(ctx, *userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", nil
+		}
+		return "", NewServiceError("failed to retrieve user", err)
+	}
+	if user.Locale == nil {
+		return "", nil
+	}
+	return *user.Locale, nil
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/repository/memory"
+)
+
+func TestUserService_UpdateChecksExpectedVersion(t *testing.T) {
+	ctx := context.Background()
+	db := memory.NewDB()
+	users := NewUserService(memory.NewUserRepository(db), memory.NewBusinessRepository(db), memory.NewStaffRepository(db), validator.New())
+	user, err := users.Create(ctx, dto.CreateUserDTO{Email: "ana@example.com", FirstName: "Ana", LastName: "Silva"})
+	require.NoError(t, err)
+
+	name := "Anabela"
+	updated, err := users.Update(ctx, user.ID, dto.UpdateUserDTO{FirstName: &name, ExpectedVersion: &user.Version})
+	require.NoError(t, err)
+	assert.Equal(t, name, updated.FirstName)
+	assert.Equal(t, user.Version+1, updated.Version)
+
+	// A second change based on the version the first one replaced
+	surname := "Sousa"
+	_, err = users.Update(ctx, user.ID, dto.UpdateUserDTO{LastName: &surname, ExpectedVersion: &user.Version})
+	var conflict *domain.ConflictError
+	require.ErrorAs(t, err, &conflict)
+}
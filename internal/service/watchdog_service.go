@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/notification"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// unansweredRequestBatchSize limits how many booking requests a single run expires
+	unansweredRequestBatchSize = 200
+	// watchdogAlertCooldown is how long operators are not alerted again about the same check
+	watchdogAlertCooldown = time.Hour
+	// unansweredRequestReason is the cancellation reason of booking requests the watchdog expires
+	unansweredRequestReason = "Expired: the booking request was not accepted before its start time"
+)
+
+// WatchdogService defines the service interface for the watchdog looking for stuck background work
+type WatchdogService interface {
+	Run(ctx context.Context, now time.Time) (*domain.WatchdogReport, error)
+	RunWatchdog(ctx context.Context) (*dto.WatchdogReportDTO, error)
+}
+
+// watchdogServiceImpl implements the WatchdogService interface
+type watchdogServiceImpl struct {
+	watchdogRepo       domain.WatchdogRepository
+	appointmentService AppointmentService
+	sender             notification.NotificationSender
+	operatorEmail      string
+	thresholds         domain.WatchdogThresholds
+
+	mu        sync.Mutex
+	alertedAt map[domain.WatchdogCheck]time.Time
+}
+
+// NewWatchdogService creates a new watchdog service. Alerts are emailed to operatorEmail, or only
+// logged when it is empty; sender should deliver directly rather than through the notification
+// queue, which may be what is stuck.
+func NewWatchdogService(
+	watchdogRepo domain.WatchdogRepository,
+	appointmentService AppointmentService,
+	sender notification.NotificationSender,
+	operatorEmail string,
+	thresholds domain.WatchdogThresholds,
+) WatchdogService {
+	return &watchdogServiceImpl{
+		watchdogRepo:       watchdogRepo,
+		appointmentService: appointmentService,
+		sender:             sender,
+		operatorEmail:      operatorEmail,
+		thresholds:         thresholds,
+		alertedAt:          map[domain.WatchdogCheck]time.Time{},
+	}
+}
+
+// Run runs every check, requeues leases no worker will ever release, expires booking requests
+// that were never answered, and alerts operators about what is left. Operators are alerted about
+// a check at most once an hour.
+func (s *watchdogServiceImpl) Run(ctx context.Context, now time.Time) (*domain.WatchdogReport, error) {
+	report := s.check(ctx, now)
+	if err := s.alert(ctx, report, now); err != nil {
+		return report, NewServiceError("failed to alert operators", err)
+	}
+	return report, nil
+}
+
+// RunWatchdog runs every check and remediates what it safely can, without alerting operators
+// (platform admin only)
+func (s *watchdogServiceImpl) RunWatchdog(ctx context.Context) (*dto.WatchdogReportDTO, error) {
+	if !IsPlatformAdmin(ctx) {
+		return nil, NewForbiddenError("run the watchdog")
+	}
+	return dto.ToWatchdogReportDTO(s.check(ctx, time.Now())), nil
+}
+
+// check runs every check. A failed check is reported in its finding rather than stopping the run.
+func (s *watchdogServiceImpl) check(ctx context.Context, now time.Time) *domain.WatchdogReport {
+	return &domain.WatchdogReport{
+		RanAt: now,
+		Findings: []*domain.WatchdogFinding{
+			requeued(domain.WatchdogEventLeases)(s.watchdogRepo.RequeueEventLeases(ctx, domain.EventLeaseCutoff(now), now)),
+			overdue(domain.WatchdogEventBacklog)(s.watchdogRepo.FindEventBacklog(ctx, now.Add(-s.thresholds.EventBacklog))),
+			requeued(domain.WatchdogNotificationLeases)(s.watchdogRepo.RequeueNotifications(ctx, domain.NotificationLeaseCutoff(now), now)),
+			overdue(domain.WatchdogNotificationBacklog)(s.watchdogRepo.FindNotificationBacklog(ctx, now.Add(-s.thresholds.NotificationBacklog))),
+			requeued(domain.WatchdogWebhookLeases)(s.watchdogRepo.RequeueWebhookDeliveries(ctx, domain.WebhookLeaseCutoff(now), now)),
+			overdue(domain.WatchdogWebhookBacklog)(s.watchdogRepo.FindWebhookBacklog(ctx, now.Add(-s.thresholds.WebhookBacklog))),
+			overdue(domain.WatchdogStalledBroadcasts)(s.watchdogRepo.FindStalledBroadcasts(ctx, now.Add(-s.thresholds.StalledBroadcast))),
+			overdue(domain.WatchdogOpenPayments)(s.watchdogRepo.FindOpenPayments(ctx, now.Add(-s.thresholds.OpenPayment))),
+			s.expireUnansweredRequests(ctx, now),
+		},
+	}
+}
+
+// requeued returns a function reporting the leases a check requeued
+func requeued(check domain.WatchdogCheck) func(int64, error) *domain.WatchdogFinding {
+	return func(count int64, err error) *domain.WatchdogFinding {
+		finding := &domain.WatchdogFinding{Check: check, Count: count, Remediated: count, Action: domain.WatchdogActionNone}
+		if err != nil {
+			finding.Error = failure(err)
+		} else if count > 0 {
+			finding.Action = domain.WatchdogActionRequeued
+		}
+		return finding
+	}
+}
+
+// overdue returns a function reporting the stuck items a check found, which are left to operators
+func overdue(check domain.WatchdogCheck) func(domain.StuckItems, error) *domain.WatchdogFinding {
+	return func(items domain.StuckItems, err error) *domain.WatchdogFinding {
+		finding := &domain.WatchdogFinding{Check: check, Count: items.Count, OldestAt: items.OldestAt, Action: domain.WatchdogActionNone}
+		if err != nil {
+			finding.Error = failure(err)
+		} else if items.Count > 0 {
+			finding.Action = domain.WatchdogActionAlerted
+		}
+		return finding
+	}
+}
+
+// expireUnansweredRequests cancels the online booking requests whose start time passed before the
+// business accepted them, releasing their slot. Cancelling publishes the usual events, so clients
+// and webhooks hear of it like any other cancellation.
+func (s *watchdogServiceImpl) expireUnansweredRequests(ctx context.Context, now time.Time) *domain.WatchdogFinding {
+	finding := &domain.WatchdogFinding{Check: domain.WatchdogUnansweredRequests, Action: domain.WatchdogActionNone}
+	appointments, err := s.watchdogRepo.FindUnansweredRequests(ctx, now, unansweredRequestBatchSize)
+	if err != nil {
+		finding.Error = failure(err)
+		return finding
+	}
+
+	finding.Count = int64(len(appointments))
+	for _, appointment := range appointments {
+		if finding.OldestAt == nil {
+			finding.OldestAt = &appointment.StartTime
+		}
+		tenantCtx := domain.WithTenant(ctx, domain.TenantContext{BusinessID: appointment.BusinessID})
+		if _, err := s.appointmentService.CancelAppointment(tenantCtx, appointment.ID, dto.CancelAppointmentDTO{Reason: unansweredRequestReason}); err != nil {
+			log.Warn().Err(err).Str("appointment_id", appointment.ID).Msg("Failed to expire booking request")
+			if finding.Error == nil {
+				finding.Error = failure(err)
+			}
+			continue
+		}
+		finding.Remediated++
+	}
+	if finding.Count > 0 {
+		finding.Action = domain.WatchdogActionExpired
+		if finding.NeedsOperator() {
+			finding.Action = domain.WatchdogActionAlerted
+		}
+	}
+	return finding
+}
+
+// failure returns the message of an error for a finding
+func failure(err error) *string {
+	message := err.Error()
+	return &message
+}
+
+// alert emails operators the findings they have to look into, leaving out the checks they were
+// alerted about within the cooldown
+func (s *watchdogServiceImpl) alert(ctx context.Context, report *domain.WatchdogReport, now time.Time) error {
+	s.mu.Lock()
+	var findings []*domain.WatchdogFinding
+	for _, finding := range report.NeedingOperator() {
+		if alertedAt, ok := s.alertedAt[finding.Check]; ok && now.Sub(alertedAt) < watchdogAlertCooldown {
+			continue
+		}
+		s.alertedAt[finding.Check] = now
+		findings = append(findings, finding)
+	}
+	s.mu.Unlock()
+	if len(findings) == 0 {
+		return nil
+	}
+
+	body := watchdogAlertBody(findings)
+	if s.operatorEmail == "" {
+		log.Warn().Str("findings", body).Msg("Watchdog found stuck work needing an operator")
+		return nil
+	}
+	subject := fmt.Sprintf("[Beautix watchdog] %d check(s) need attention", len(findings))
+	return s.sender.Send(ctx, notification.Message{
+		Channel:   domain.MessageChannelEmail,
+		Recipient: s.operatorEmail,
+		Subject:   &subject,
+		Body:      body,
+	})
+}
+
+// watchdogAlertBody describes findings for operators, one per line
+func watchdogAlertBody(findings []*domain.WatchdogFinding) string {
+	var b strings.Builder
+	for _, finding := range findings {
+		fmt.Fprintf(&b, "%s: %d stuck", finding.Check, finding.Count)
+		if finding.Remediated > 0 {
+			fmt.Fprintf(&b, ", %d fixed", finding.Remediated)
+		}
+		if finding.OldestAt != nil {
+			fmt.Fprintf(&b, ", oldest since %s", finding.OldestAt.UTC().Format(time.RFC3339))
+		}
+		if finding.Error != nil {
+			fmt.Fprintf(&b, " (error: %s)", *finding.Error)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
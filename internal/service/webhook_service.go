@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// WebhookService defines the service interface for the webhook subscriptions third-party
+// integrations receive a business's events through
+type WebhookService interface {
+	CreateWebhookSubscription(ctx context.Context, createDTO dto.CreateWebhookSubscriptionDTO) (*dto.WebhookSubscriptionResponseDTO, error)
+	UpdateWebhookSubscription(ctx context.Context, id string, updateDTO dto.UpdateWebhookSubscriptionDTO) (*dto.WebhookSubscriptionResponseDTO, error)
+	DeleteWebhookSubscription(ctx context.Context, id string) error
+	ListWebhookSubscriptions(ctx context.Context, businessID string) ([]*dto.WebhookSubscriptionResponseDTO, error)
+	ListWebhookDeliveries(ctx context.Context, subscriptionID string, page, pageSize int) ([]*dto.WebhookDeliveryDTO, int64, error)
+}
+
+// webhookServiceImpl implements the WebhookService interface
+type webhookServiceImpl struct {
+	webhookRepo domain.WebhookRepository
+	staffRepo   domain.StaffRepository
+	validator   *validator.Validate
+}
+
+// NewWebhookService creates a new webhook service
+func NewWebhookService(webhookRepo domain.WebhookRepository, staffRepo domain.StaffRepository, validator *validator.Validate) WebhookService {
+	return &webhookServiceImpl{
+		webhookRepo: webhookRepo,
+		staffRepo:   staffRepo,
+		validator:   validator,
+	}
+}
+
+// CreateWebhookSubscription subscribes an endpoint to events of a business. The response
+// carries the signing secret, which is not shown again.
+func (s *webhookServiceImpl) CreateWebhookSubscription(ctx context.Context, createDTO dto.CreateWebhookSubscriptionDTO) (*dto.WebhookSubscriptionResponseDTO, error) {
+	if err := s.validator.Struct(createDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	if err := requireManager(ctx, s.staffRepo, createDTO.BusinessID, "manage webhooks"); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.webhookRepo.FindByBusiness(ctx, createDTO.BusinessID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve webhook subscriptions", err)
+	}
+	if len(existing) >= domain.MaxWebhookSubscriptions {
+		return nil, validation.NewValidationError("a business can have at most 10 webhook subscriptions")
+	}
+
+	secret, err := domain.NewWebhookSecret()
+	if err != nil {
+		return nil, NewServiceError("failed to create webhook subscription", err)
+	}
+	subscription := &domain.WebhookSubscription{
+		BusinessID:  createDTO.BusinessID,
+		URL:         createDTO.URL,
+		Secret:      secret,
+		Description: createDTO.Description,
+		IsActive:    true,
+	}
+	if err := subscription.SetEventTypes(createDTO.EventTypes); err != nil {
+		return nil, NewServiceError("failed to encode event types", err)
+	}
+	if err := subscription.Validate(); err != nil {
+		return nil, toValidationError(err)
+	}
+
+	subscription.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.webhookRepo.Create(ctx, subscription); err != nil {
+		return nil, NewServiceError("failed to create webhook subscription", err)
+	}
+
+	result := dto.ToWebhookSubscriptionResponseDTO(subscription)
+	result.Secret = &secret
+	return result, nil
+}
+
+// UpdateWebhookSubscription changes a webhook subscription, returning the new secret when it
+// is rotated
+func (s *webhookServiceImpl) UpdateWebhookSubscription(ctx context.Context, id string, updateDTO dto.UpdateWebhookSubscriptionDTO) (*dto.WebhookSubscriptionResponseDTO, error) {
+	if err := s.validator.Struct(updateDTO); err != nil {
+		return nil, validation.FromError(err)
+	}
+	subscription, err := s.getSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if updateDTO.URL != nil {
+		subscription.URL = *updateDTO.URL
+	}
+	if updateDTO.EventTypes != nil {
+		if err := subscription.SetEventTypes(updateDTO.EventTypes); err != nil {
+			return nil, NewServiceError("failed to encode event types", err)
+		}
+	}
+	if updateDTO.Description != nil {
+		subscription.Description = updateDTO.Description
+	}
+	if updateDTO.IsActive != nil {
+		subscription.IsActive = *updateDTO.IsActive
+	}
+	if updateDTO.RotateSecret {
+		secret, err := domain.NewWebhookSecret()
+		if err != nil {
+			return nil, NewServiceError("failed to rotate webhook secret", err)
+		}
+		subscription.Secret = secret
+	}
+	if err := subscription.Validate(); err != nil {
+		return nil, toValidationError(err)
+	}
+
+	subscription.SetAuditFields(GetUserIDFromContext(ctx))
+	if err := s.webhookRepo.Update(ctx, subscription); err != nil {
+		return nil, NewServiceError("failed to update webhook subscription", err)
+	}
+
+	result := dto.ToWebhookSubscriptionResponseDTO(subscription)
+	if updateDTO.RotateSecret {
+		result.Secret = &subscription.Secret
+	}
+	return result, nil
+}
+
+// DeleteWebhookSubscription deletes a webhook subscription. Its pending deliveries fail
+// instead of being sent.
+func (s *webhookServiceImpl) DeleteWebhookSubscription(ctx context.Context, id string) error {
+	if _, err := s.getSubscription(ctx, id); err != nil {
+		return err
+	}
+	if err := s.webhookRepo.Delete(ctx, id); err != nil {
+		return NewServiceError("failed to delete webhook subscription", err)
+	}
+	return nil
+}
+
+// ListWebhookSubscriptions lists the webhook subscriptions of a business
+func (s *webhookServiceImpl) ListWebhookSubscriptions(ctx context.Context, businessID string) ([]*dto.WebhookSubscriptionResponseDTO, error) {
+	if err := requireManager(ctx, s.staffRepo, businessID, "manage webhooks"); err != nil {
+		return nil, err
+	}
+
+	subscriptions, err := s.webhookRepo.FindByBusiness(ctx, businessID)
+	if err != nil {
+		return nil, NewServiceError("failed to retrieve webhook subscriptions", err)
+	}
+	result := make([]*dto.WebhookSubscriptionResponseDTO, len(subscriptions))
+	for i, subscription := range subscriptions {
+		result[i] = dto.ToWebhookSubscriptionResponseDTO(subscription)
+	}
+	return result, nil
+}
+
+// ListWebhookDeliveries lists the delivery log of a webhook subscription, latest first
+func (s *webhookServiceImpl) ListWebhookDeliveries(ctx context.Context, subscriptionID string, page, pageSize int) ([]*dto.WebhookDeliveryDTO, int64, error) {
+	if _, err := s.getSubscription(ctx, subscriptionID); err != nil {
+		return nil, 0, err
+	}
+
+	deliveries, total, err := s.webhookRepo.FindDeliveries(ctx, subscriptionID, page, pageSize)
+	if err != nil {
+		return nil, 0, NewServiceError("failed to retrieve webhook deliveries", err)
+	}
+	result := make([]*dto.WebhookDeliveryDTO, len(deliveries))
+	for i, delivery := range deliveries {
+		result[i] = dto.ToWebhookDeliveryDTO(delivery)
+	}
+	return result, total, nil
+}
+
+// getSubscription retrieves a webhook subscription the caller manages
+func (s *webhookServiceImpl) getSubscription(ctx context.Context, id string) (*domain.WebhookSubscription, error) {
+	subscription, err := s.webhookRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewNotFoundError("webhook subscription", "id", id)
+		}
+		return nil, NewServiceError("failed to retrieve webhook subscription", err)
+	}
+	if err := requireManager(ctx, s.staffRepo, subscription.BusinessID, "manage webhooks"); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
@@ -0,0 +1,10 @@
+-- Rollback migration for structured service records
+
+DROP TABLE IF EXISTS public.service_records;
+DROP TABLE IF EXISTS public.service_record_templates;
+
+DROP INDEX IF EXISTS idx_services_category_id;
+
+ALTER TABLE public.services
+    DROP CONSTRAINT IF EXISTS fk_services_category,
+    DROP COLUMN IF EXISTS category_id;
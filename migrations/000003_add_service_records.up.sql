@@ -0,0 +1,95 @@
+-- Migration to add structured service records (e.g. colour formulas) to client history
+-- Records attach to service completions and capture templated fields per service category
+
+-- ========================================
+-- Link services to service categories
+-- ========================================
+
+-- Templates are resolved through the service category, which the services table
+-- only tracked as free text until now
+ALTER TABLE public.services
+    ADD COLUMN IF NOT EXISTS category_id UUID,
+    ADD CONSTRAINT fk_services_category FOREIGN KEY (category_id) REFERENCES public.service_categories(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_services_category_id ON public.services(category_id);
+
+-- ========================================
+-- Service record templates
+-- ========================================
+
+CREATE TABLE public.service_record_templates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID NOT NULL,
+    category_id UUID,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    fields JSONB NOT NULL DEFAULT '[]', -- [{key, label, type, required, unit, options}]
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_service_record_templates_business FOREIGN KEY (business_id) REFERENCES public.businesses(id) ON DELETE CASCADE,
+    CONSTRAINT fk_service_record_templates_category FOREIGN KEY (category_id) REFERENCES public.service_categories(id) ON DELETE SET NULL,
+    CONSTRAINT fk_service_record_templates_created_by FOREIGN KEY (created_by) REFERENCES public.users(id),
+    CONSTRAINT fk_service_record_templates_updated_by FOREIGN KEY (updated_by) REFERENCES public.users(id),
+    CONSTRAINT fk_service_record_templates_deleted_by FOREIGN KEY (deleted_by) REFERENCES public.users(id)
+);
+
+COMMENT ON TABLE public.service_record_templates IS 'Field definitions for structured service records, scoped to a service category';
+
+CREATE INDEX idx_service_record_templates_business_id ON public.service_record_templates(business_id);
+CREATE INDEX idx_service_record_templates_category_id ON public.service_record_templates(category_id);
+CREATE INDEX idx_service_record_templates_deleted_at ON public.service_record_templates(deleted_at) WHERE deleted_at IS NULL;
+
+-- ========================================
+-- Service records
+-- ========================================
+
+CREATE TABLE public.service_records (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID NOT NULL,
+    client_id UUID NOT NULL,
+    appointment_id UUID,
+    service_completion_id UUID,
+    service_id UUID,
+    staff_id UUID,
+    template_id UUID,
+    copied_from_id UUID,
+    recorded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    field_values JSONB NOT NULL DEFAULT '{}',
+    products_used JSONB NOT NULL DEFAULT '[]', -- [{product_id, name, brand, shade, amount, unit}]
+    processing_time INTEGER, -- in minutes
+    notes TEXT,
+    is_draft BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_service_records_business FOREIGN KEY (business_id) REFERENCES public.businesses(id) ON DELETE CASCADE,
+    CONSTRAINT fk_service_records_client FOREIGN KEY (client_id) REFERENCES public.clients(id) ON DELETE CASCADE,
+    CONSTRAINT fk_service_records_appointment FOREIGN KEY (appointment_id) REFERENCES public.appointments(id) ON DELETE SET NULL,
+    CONSTRAINT fk_service_records_completion FOREIGN KEY (service_completion_id) REFERENCES public.service_completions(id) ON DELETE SET NULL,
+    CONSTRAINT fk_service_records_service FOREIGN KEY (service_id) REFERENCES public.services(id) ON DELETE SET NULL,
+    CONSTRAINT fk_service_records_staff FOREIGN KEY (staff_id) REFERENCES public.staff(id) ON DELETE SET NULL,
+    CONSTRAINT fk_service_records_template FOREIGN KEY (template_id) REFERENCES public.service_record_templates(id) ON DELETE SET NULL,
+    CONSTRAINT fk_service_records_copied_from FOREIGN KEY (copied_from_id) REFERENCES public.service_records(id) ON DELETE SET NULL,
+    CONSTRAINT fk_service_records_created_by FOREIGN KEY (created_by) REFERENCES public.users(id),
+    CONSTRAINT fk_service_records_updated_by FOREIGN KEY (updated_by) REFERENCES public.users(id),
+    CONSTRAINT fk_service_records_deleted_by FOREIGN KEY (deleted_by) REFERENCES public.users(id),
+    CONSTRAINT chk_service_records_processing_time CHECK (processing_time IS NULL OR processing_time >= 0)
+);
+
+COMMENT ON TABLE public.service_records IS 'Structured per-visit service records such as colour formulas and products used';
+
+CREATE INDEX idx_service_records_client_recorded_at ON public.service_records(client_id, recorded_at DESC);
+CREATE INDEX idx_service_records_business_id ON public.service_records(business_id);
+CREATE INDEX idx_service_records_appointment_id ON public.service_records(appointment_id);
+CREATE INDEX idx_service_records_service_completion_id ON public.service_records(service_completion_id);
+CREATE INDEX idx_service_records_field_values ON public.service_records USING GIN (field_values);
+CREATE INDEX idx_service_records_products_used ON public.service_records USING GIN (products_used);
+CREATE INDEX idx_service_records_deleted_at ON public.service_records(deleted_at) WHERE deleted_at IS NULL;
@@ -0,0 +1,9 @@
+-- Rollback migration for staff commission plans
+
+DROP INDEX IF EXISTS idx_inventory_transactions_staff_id;
+
+ALTER TABLE public.inventory_transactions
+    DROP CONSTRAINT IF EXISTS fk_inventory_transactions_staff,
+    DROP COLUMN IF EXISTS staff_id;
+
+DROP TABLE IF EXISTS public.staff_commission_plans;
@@ -0,0 +1,47 @@
+-- Migration to add tiered staff commission plans with effective-date history
+
+-- ========================================
+-- Staff commission plans
+-- ========================================
+
+CREATE TABLE public.staff_commission_plans (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID NOT NULL,
+    staff_id UUID NOT NULL,
+    effective_from TIMESTAMP WITH TIME ZONE NOT NULL,
+    effective_to TIMESTAMP WITH TIME ZONE, -- NULL for the current plan
+    service_tiers JSONB NOT NULL DEFAULT '[]', -- [{threshold, rate}] ordered by threshold
+    product_rate DECIMAL(5,2) NOT NULL DEFAULT 0, -- percentage of retail product sales
+    service_overrides JSONB NOT NULL DEFAULT '[]', -- [{service_id, rate}]
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_staff_commission_plans_business FOREIGN KEY (business_id) REFERENCES public.businesses(id) ON DELETE CASCADE,
+    CONSTRAINT fk_staff_commission_plans_staff FOREIGN KEY (staff_id) REFERENCES public.staff(id) ON DELETE CASCADE,
+    CONSTRAINT fk_staff_commission_plans_created_by FOREIGN KEY (created_by) REFERENCES public.users(id),
+    CONSTRAINT fk_staff_commission_plans_updated_by FOREIGN KEY (updated_by) REFERENCES public.users(id),
+    CONSTRAINT fk_staff_commission_plans_deleted_by FOREIGN KEY (deleted_by) REFERENCES public.users(id),
+    CONSTRAINT chk_staff_commission_plans_period CHECK (effective_to IS NULL OR effective_to > effective_from),
+    CONSTRAINT chk_staff_commission_plans_product_rate CHECK (product_rate >= 0 AND product_rate <= 100)
+);
+
+COMMENT ON TABLE public.staff_commission_plans IS 'Per-staff commission configuration with tiered service rates, product rates and service overrides';
+
+CREATE INDEX idx_staff_commission_plans_staff_effective ON public.staff_commission_plans(staff_id, effective_from DESC);
+CREATE INDEX idx_staff_commission_plans_business_id ON public.staff_commission_plans(business_id);
+CREATE INDEX idx_staff_commission_plans_deleted_at ON public.staff_commission_plans(deleted_at) WHERE deleted_at IS NULL;
+
+-- ========================================
+-- Attribute retail sales to staff
+-- ========================================
+
+-- Retail sales outside an appointment need an explicit seller for product commission
+ALTER TABLE public.inventory_transactions
+    ADD COLUMN IF NOT EXISTS staff_id UUID,
+    ADD CONSTRAINT fk_inventory_transactions_staff FOREIGN KEY (staff_id) REFERENCES public.staff(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_inventory_transactions_staff_id ON public.inventory_transactions(staff_id);
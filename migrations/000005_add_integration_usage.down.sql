@@ -0,0 +1,3 @@
+-- Rollback migration for integration usage counters
+
+DROP TABLE IF EXISTS public.integration_usage_daily;
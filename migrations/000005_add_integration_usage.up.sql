@@ -0,0 +1,24 @@
+-- Migration to add daily integration usage counters for API and webhook traffic
+
+CREATE TABLE public.integration_usage_daily (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID NOT NULL,
+    usage_date DATE NOT NULL,
+    channel VARCHAR(20) NOT NULL CHECK (channel IN ('api', 'webhook')),
+    source_id VARCHAR(100) NOT NULL DEFAULT '', -- API key or webhook endpoint, '' when unattributed
+    request_count INTEGER NOT NULL DEFAULT 0,
+    error_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_integration_usage_daily_business FOREIGN KEY (business_id) REFERENCES public.businesses(id) ON DELETE CASCADE,
+    CONSTRAINT uq_integration_usage_daily UNIQUE (business_id, usage_date, channel, source_id),
+    CONSTRAINT chk_integration_usage_daily_counts CHECK (error_count >= 0 AND error_count <= request_count)
+);
+
+COMMENT ON TABLE public.integration_usage_daily IS 'Per-day API request and webhook delivery counters for integrators';
+
+CREATE INDEX idx_integration_usage_daily_business_date ON public.integration_usage_daily(business_id, usage_date);
@@ -0,0 +1,3 @@
+-- Rollback migration for the domain event log
+
+DROP TABLE IF EXISTS public.domain_events;
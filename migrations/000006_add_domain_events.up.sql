@@ -0,0 +1,26 @@
+-- Migration to add the append-only domain event log
+
+CREATE TABLE public.domain_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID,
+    aggregate_type VARCHAR(50) NOT NULL,
+    aggregate_id UUID NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}',
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    replay_count INTEGER NOT NULL DEFAULT 0,
+    last_replayed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_domain_events_business FOREIGN KEY (business_id) REFERENCES public.businesses(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE public.domain_events IS 'Append-only log of domain events, replayable to downstream consumers';
+
+CREATE INDEX idx_domain_events_aggregate ON public.domain_events(aggregate_type, aggregate_id, occurred_at);
+CREATE INDEX idx_domain_events_business_occurred ON public.domain_events(business_id, occurred_at);
+CREATE INDEX idx_domain_events_type_occurred ON public.domain_events(event_type, occurred_at);
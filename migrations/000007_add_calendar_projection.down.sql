@@ -0,0 +1,3 @@
+-- Rollback migration for the calendar read model
+
+DROP TABLE IF EXISTS public.calendar_entries;
@@ -0,0 +1,27 @@
+-- Migration to add the denormalized calendar read model, maintained from appointment events
+
+CREATE TABLE public.calendar_entries (
+    appointment_id UUID PRIMARY KEY,
+    business_id UUID NOT NULL,
+    staff_id UUID NOT NULL,
+    client_id UUID NOT NULL,
+    calendar_date DATE NOT NULL, -- Local date in the business time zone
+    start_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    client_name VARCHAR(201) NOT NULL,
+    client_phone VARCHAR(50),
+    staff_name VARCHAR(201) NOT NULL,
+    service_names JSONB NOT NULL DEFAULT '[]',
+    total_duration INTEGER NOT NULL DEFAULT 0, -- in minutes
+    price DECIMAL(10,2),
+    notes TEXT,
+    projected_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_calendar_entries_appointment FOREIGN KEY (appointment_id) REFERENCES public.appointments(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE public.calendar_entries IS 'Read model for calendar views; rebuilt from appointments, never written by the API directly';
+
+CREATE INDEX idx_calendar_entries_business_date ON public.calendar_entries(business_id, calendar_date, staff_id, start_time);
+CREATE INDEX idx_calendar_entries_client_id ON public.calendar_entries(client_id);
+CREATE INDEX idx_calendar_entries_staff_id ON public.calendar_entries(staff_id);
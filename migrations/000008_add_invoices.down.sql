@@ -0,0 +1,4 @@
+-- Rollback migration for invoices
+
+DROP TABLE IF EXISTS public.invoices;
+DROP TABLE IF EXISTS public.invoice_sequences;
@@ -0,0 +1,42 @@
+-- Migration to add invoices issued for completed appointments
+
+CREATE TABLE public.invoice_sequences (
+    business_id UUID PRIMARY KEY,
+    last_number INTEGER NOT NULL DEFAULT 0,
+    CONSTRAINT fk_invoice_sequences_business FOREIGN KEY (business_id) REFERENCES public.businesses(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE public.invoice_sequences IS 'Last invoice number issued per business, for gapless numbering';
+
+CREATE TABLE public.invoices (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID NOT NULL,
+    client_id UUID NOT NULL,
+    appointment_id UUID NOT NULL,
+    invoice_number VARCHAR(20) NOT NULL,
+    issued_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    currency VARCHAR(3) NOT NULL DEFAULT 'EUR',
+    business_name VARCHAR(100) NOT NULL,
+    business_tax_id VARCHAR(50),
+    client_name VARCHAR(201) NOT NULL,
+    line_items JSONB NOT NULL DEFAULT '[]',
+    total DECIMAL(10,2) NOT NULL,
+    amount_paid DECIMAL(10,2) NOT NULL,
+    payment_method VARCHAR(20),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_invoices_business FOREIGN KEY (business_id) REFERENCES public.businesses(id) ON DELETE CASCADE,
+    CONSTRAINT fk_invoices_client FOREIGN KEY (client_id) REFERENCES public.clients(id),
+    CONSTRAINT fk_invoices_appointment FOREIGN KEY (appointment_id) REFERENCES public.appointments(id),
+    CONSTRAINT uq_invoices_appointment UNIQUE (appointment_id),
+    CONSTRAINT uq_invoices_business_number UNIQUE (business_id, invoice_number)
+);
+
+COMMENT ON TABLE public.invoices IS 'Immutable invoices for completed appointments, with business and client details captured at issue time';
+
+CREATE INDEX idx_invoices_client_id ON public.invoices(client_id);
+CREATE INDEX idx_invoices_business_issued ON public.invoices(business_id, issued_at);
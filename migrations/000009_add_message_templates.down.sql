@@ -0,0 +1,3 @@
+-- Rollback migration for message templates
+
+DROP TABLE IF EXISTS public.message_templates;
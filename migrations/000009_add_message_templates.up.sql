@@ -0,0 +1,24 @@
+-- Migration to add email and SMS message templates
+
+CREATE TABLE public.message_templates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    channel VARCHAR(20) NOT NULL CHECK (channel IN ('email', 'sms')),
+    purpose VARCHAR(50), -- e.g. 'reminder', 'confirmation', 'campaign'
+    subject VARCHAR(200),
+    body TEXT NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_message_templates_business FOREIGN KEY (business_id) REFERENCES public.businesses(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE public.message_templates IS 'Reusable email and SMS messages with {{variable}} placeholders';
+
+CREATE INDEX idx_message_templates_business_id ON public.message_templates(business_id);
+CREATE INDEX idx_message_templates_purpose ON public.message_templates(business_id, channel, purpose) WHERE is_active = TRUE;
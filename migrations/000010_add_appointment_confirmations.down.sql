@@ -0,0 +1,5 @@
+-- Rollback migration for appointment confirmation requests
+
+DROP INDEX IF EXISTS public.idx_appointments_unconfirmed;
+DROP TABLE IF EXISTS public.appointment_confirmations;
+ALTER TABLE public.business_settings DROP COLUMN IF EXISTS confirmation_lead_hours;
@@ -0,0 +1,37 @@
+-- Migration to add appointment confirmation requests
+
+ALTER TABLE public.business_settings
+    ADD COLUMN confirmation_lead_hours INTEGER NOT NULL DEFAULT 24
+    CHECK (confirmation_lead_hours >= 0 AND confirmation_lead_hours <= 168);
+
+COMMENT ON COLUMN public.business_settings.confirmation_lead_hours IS 'How many hours before an appointment clients are asked to confirm it; 0 disables confirmation requests';
+
+CREATE TABLE public.appointment_confirmations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    appointment_id UUID NOT NULL,
+    business_id UUID NOT NULL,
+    client_id UUID NOT NULL,
+    channel VARCHAR(20) NOT NULL CHECK (channel IN ('email', 'sms')),
+    recipient VARCHAR(255) NOT NULL,
+    token VARCHAR(64) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'confirmed', 'failed')),
+    sent_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    responded_at TIMESTAMP WITH TIME ZONE,
+    response_source VARCHAR(20) CHECK (response_source IN ('link', 'sms', 'staff')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_appointment_confirmations_appointment FOREIGN KEY (appointment_id) REFERENCES public.appointments(id) ON DELETE CASCADE,
+    CONSTRAINT fk_appointment_confirmations_business FOREIGN KEY (business_id) REFERENCES public.businesses(id) ON DELETE CASCADE,
+    CONSTRAINT fk_appointment_confirmations_client FOREIGN KEY (client_id) REFERENCES public.clients(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE public.appointment_confirmations IS 'Confirmation requests sent to clients ahead of their appointments, one per appointment';
+
+CREATE UNIQUE INDEX idx_appointment_confirmations_appointment_id ON public.appointment_confirmations(appointment_id);
+CREATE UNIQUE INDEX idx_appointment_confirmations_token ON public.appointment_confirmations(token);
+CREATE INDEX idx_appointment_confirmations_pending ON public.appointment_confirmations(channel, sent_at) WHERE status = 'pending';
+CREATE INDEX idx_appointments_unconfirmed ON public.appointments(business_id, start_time) WHERE client_confirmed IS NOT TRUE AND deleted_at IS NULL;
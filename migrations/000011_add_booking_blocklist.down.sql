@@ -0,0 +1,4 @@
+-- Rollback migration for booking blocklists
+
+DROP TABLE IF EXISTS public.booking_attempts;
+DROP TABLE IF EXISTS public.booking_blocklist_entries;
@@ -0,0 +1,34 @@
+-- Migration to add booking blocklists and public booking attempt tracking
+
+CREATE TABLE public.booking_blocklist_entries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID NOT NULL,
+    entry_type VARCHAR(20) NOT NULL CHECK (entry_type IN ('phone', 'email', 'client')),
+    value VARCHAR(255) NOT NULL, -- Normalized: digits for phones, lower case for emails, the client ID for clients
+    reason TEXT,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_booking_blocklist_entries_business FOREIGN KEY (business_id) REFERENCES public.businesses(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE public.booking_blocklist_entries IS 'Phones, emails and clients that may not book online with a business';
+
+CREATE UNIQUE INDEX idx_booking_blocklist_entries_value ON public.booking_blocklist_entries(business_id, entry_type, value) WHERE deleted_at IS NULL;
+
+CREATE TABLE public.booking_attempts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    ip_address VARCHAR(45) NOT NULL,
+    business_id UUID NOT NULL,
+    outcome VARCHAR(20) NOT NULL CHECK (outcome IN ('allowed', 'blocklisted', 'captcha_required', 'rate_limited')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE public.booking_attempts IS 'Public booking attempts, used for per-IP velocity checks';
+
+CREATE INDEX idx_booking_attempts_ip_created_at ON public.booking_attempts(ip_address, created_at);
+CREATE INDEX idx_booking_attempts_created_at ON public.booking_attempts(created_at);
@@ -0,0 +1,7 @@
+-- Rollback migration for capacity warnings
+
+DROP TABLE IF EXISTS public.capacity_alerts;
+ALTER TABLE public.business_settings
+    DROP COLUMN IF EXISTS min_break_minutes,
+    DROP COLUMN IF EXISTS max_continuous_work_minutes,
+    DROP COLUMN IF EXISTS capacity_warning_percent;
@@ -0,0 +1,30 @@
+-- Migration to add soft capacity warnings for dense schedules
+
+ALTER TABLE public.business_settings
+    ADD COLUMN capacity_warning_percent INTEGER NOT NULL DEFAULT 90
+        CHECK (capacity_warning_percent >= 0 AND capacity_warning_percent <= 100),
+    ADD COLUMN max_continuous_work_minutes INTEGER NOT NULL DEFAULT 240
+        CHECK (max_continuous_work_minutes >= 0 AND max_continuous_work_minutes <= 720),
+    ADD COLUMN min_break_minutes INTEGER NOT NULL DEFAULT 15
+        CHECK (min_break_minutes >= 5 AND min_break_minutes <= 120);
+
+COMMENT ON COLUMN public.business_settings.capacity_warning_percent IS 'Warn when a day is booked at or above this percentage of staff capacity; 0 disables the warning';
+COMMENT ON COLUMN public.business_settings.max_continuous_work_minutes IS 'Warn when a staff member works longer than this without a break; 0 disables the warning';
+COMMENT ON COLUMN public.business_settings.min_break_minutes IS 'Shortest gap between appointments that counts as a break';
+
+CREATE TABLE public.capacity_alerts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID NOT NULL,
+    alert_date DATE NOT NULL,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('day_booked', 'no_break')),
+    staff_id UUID,
+    notified_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_capacity_alerts_business FOREIGN KEY (business_id) REFERENCES public.businesses(id) ON DELETE CASCADE,
+    CONSTRAINT fk_capacity_alerts_staff FOREIGN KEY (staff_id) REFERENCES public.staff(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE public.capacity_alerts IS 'Capacity warnings already notified to businesses, so each is notified once';
+
+CREATE UNIQUE INDEX idx_capacity_alerts_unique ON public.capacity_alerts(
+    business_id, alert_date, kind, COALESCE(staff_id, '00000000-0000-0000-0000-000000000000'::uuid)
+);
@@ -0,0 +1,4 @@
+-- Rollback migration for bulk price changes
+
+DROP TABLE IF EXISTS public.service_price_history;
+DROP TABLE IF EXISTS public.service_price_changes;
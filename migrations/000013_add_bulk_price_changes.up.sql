@@ -0,0 +1,45 @@
+-- Migration to add scheduled bulk price changes and service price history
+
+CREATE TABLE public.service_price_changes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID NOT NULL,
+    adjustment_type VARCHAR(20) NOT NULL CHECK (adjustment_type IN ('percentage', 'fixed')),
+    amount DECIMAL(10,2) NOT NULL,
+    service_ids JSONB NOT NULL DEFAULT '[]',
+    category_ids JSONB NOT NULL DEFAULT '[]',
+    effective_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'scheduled' CHECK (status IN ('scheduled', 'applied', 'cancelled', 'failed')),
+    applied_at TIMESTAMP WITH TIME ZONE,
+    failure_reason TEXT,
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_service_price_changes_business FOREIGN KEY (business_id) REFERENCES public.businesses(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE public.service_price_changes IS 'Percentage or fixed price adjustments of selected services and categories, applied on their effective date';
+
+CREATE INDEX idx_service_price_changes_business_id ON public.service_price_changes(business_id);
+CREATE INDEX idx_service_price_changes_due ON public.service_price_changes(effective_at) WHERE status = 'scheduled';
+
+CREATE TABLE public.service_price_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID NOT NULL,
+    service_id UUID NOT NULL,
+    price_change_id UUID,
+    old_price DECIMAL(10,2) NOT NULL,
+    new_price DECIMAL(10,2) NOT NULL,
+    changed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    changed_by UUID,
+    CONSTRAINT fk_service_price_history_business FOREIGN KEY (business_id) REFERENCES public.businesses(id) ON DELETE CASCADE,
+    CONSTRAINT fk_service_price_history_service FOREIGN KEY (service_id) REFERENCES public.services(id) ON DELETE CASCADE,
+    CONSTRAINT fk_service_price_history_change FOREIGN KEY (price_change_id) REFERENCES public.service_price_changes(id) ON DELETE SET NULL
+);
+
+COMMENT ON TABLE public.service_price_history IS 'Every change of a service price, with the bulk change that made it';
+
+CREATE INDEX idx_service_price_history_service ON public.service_price_history(service_id, changed_at);
@@ -0,0 +1,6 @@
+-- Rollback migration for service bundles
+
+ALTER TABLE public.appointments
+    DROP CONSTRAINT IF EXISTS fk_appointments_bundle,
+    DROP COLUMN IF EXISTS bundle_id;
+DROP TABLE IF EXISTS public.service_bundles;
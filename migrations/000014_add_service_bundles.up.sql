@@ -0,0 +1,29 @@
+-- Migration to add service bundles booked as a single selection
+
+CREATE TABLE public.service_bundles (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    price DECIMAL(10,2) NOT NULL CHECK (price >= 0),
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    items JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_service_bundles_business FOREIGN KEY (business_id) REFERENCES public.businesses(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE public.service_bundles IS 'Services booked together at a combined price, with processing time that later services may overlap';
+COMMENT ON COLUMN public.service_bundles.items IS 'Ordered JSON array of {service_id, processing_minutes, during_previous_processing}';
+
+CREATE INDEX idx_service_bundles_business_id ON public.service_bundles(business_id);
+CREATE UNIQUE INDEX idx_service_bundles_business_name ON public.service_bundles(business_id, LOWER(name)) WHERE deleted_at IS NULL;
+
+-- Record which bundle an appointment was booked from
+ALTER TABLE public.appointments
+    ADD COLUMN bundle_id UUID,
+    ADD CONSTRAINT fk_appointments_bundle FOREIGN KEY (bundle_id) REFERENCES public.service_bundles(id) ON DELETE SET NULL;
@@ -0,0 +1,12 @@
+-- Rollback migration for client guardians
+
+ALTER TABLE public.services
+    DROP COLUMN IF EXISTS min_age;
+
+DROP INDEX IF EXISTS idx_clients_guardian;
+ALTER TABLE public.clients
+    DROP CONSTRAINT IF EXISTS chk_clients_guardian_not_self,
+    DROP CONSTRAINT IF EXISTS fk_clients_guardian,
+    DROP COLUMN IF EXISTS guardian_consent_at,
+    DROP COLUMN IF EXISTS guardian_relationship,
+    DROP COLUMN IF EXISTS guardian_client_id;
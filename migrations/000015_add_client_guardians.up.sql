@@ -0,0 +1,18 @@
+-- Migration to add guardian links for minor clients and minimum ages for services
+
+ALTER TABLE public.clients
+    ADD COLUMN guardian_client_id UUID,
+    ADD COLUMN guardian_relationship VARCHAR(50),
+    ADD COLUMN guardian_consent_at TIMESTAMP WITH TIME ZONE,
+    ADD CONSTRAINT fk_clients_guardian FOREIGN KEY (guardian_client_id) REFERENCES public.clients(id) ON DELETE SET NULL,
+    ADD CONSTRAINT chk_clients_guardian_not_self CHECK (guardian_client_id IS NULL OR guardian_client_id <> id);
+
+CREATE INDEX idx_clients_guardian ON public.clients(guardian_client_id) WHERE guardian_client_id IS NOT NULL;
+
+COMMENT ON COLUMN public.clients.guardian_client_id IS 'Client record of the guardian of a minor; contact and consent go through the guardian';
+COMMENT ON COLUMN public.clients.guardian_consent_at IS 'When the guardian consented to the minor being treated';
+
+ALTER TABLE public.services
+    ADD COLUMN min_age INTEGER CHECK (min_age IS NULL OR min_age BETWEEN 1 AND 99);
+
+COMMENT ON COLUMN public.services.min_age IS 'Minimum age of the client on the day of the appointment; NULL when the service is not age-restricted';
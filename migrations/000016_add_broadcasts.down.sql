@@ -0,0 +1,7 @@
+-- Rollback migration for emergency broadcasts
+
+ALTER TABLE public.appointments
+    DROP CONSTRAINT IF EXISTS fk_appointments_broadcast,
+    DROP COLUMN IF EXISTS broadcast_id;
+DROP TABLE IF EXISTS public.broadcast_recipients;
+DROP TABLE IF EXISTS public.broadcasts;
@@ -0,0 +1,60 @@
+-- Migration to add emergency broadcasts to clients with appointments in a time window
+
+CREATE TABLE public.broadcasts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID NOT NULL,
+    subject VARCHAR(200),
+    message TEXT NOT NULL,
+    window_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    window_end TIMESTAMP WITH TIME ZONE NOT NULL,
+    offer_reschedule BOOLEAN NOT NULL DEFAULT TRUE,
+    appointment_count INTEGER NOT NULL DEFAULT 0,
+    notified_count INTEGER NOT NULL DEFAULT 0,
+    failed_count INTEGER NOT NULL DEFAULT 0,
+    sent_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_broadcasts_business FOREIGN KEY (business_id) REFERENCES public.businesses(id) ON DELETE CASCADE,
+    CONSTRAINT chk_broadcasts_window CHECK (window_end > window_start)
+);
+
+CREATE INDEX idx_broadcasts_business ON public.broadcasts(business_id, created_at DESC);
+
+COMMENT ON TABLE public.broadcasts IS 'Urgent notices, e.g. a closure, sent to every client with an appointment in a time window';
+
+CREATE TABLE public.broadcast_recipients (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    broadcast_id UUID NOT NULL,
+    appointment_id UUID NOT NULL,
+    client_id UUID NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'notified', 'failed', 'unreachable')),
+    channels JSONB NOT NULL DEFAULT '[]',
+    error TEXT,
+    reschedule_token VARCHAR(64),
+    rescheduled_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_broadcast_recipients_broadcast FOREIGN KEY (broadcast_id) REFERENCES public.broadcasts(id) ON DELETE CASCADE,
+    CONSTRAINT fk_broadcast_recipients_appointment FOREIGN KEY (appointment_id) REFERENCES public.appointments(id) ON DELETE CASCADE,
+    CONSTRAINT fk_broadcast_recipients_client FOREIGN KEY (client_id) REFERENCES public.clients(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_broadcast_recipients_broadcast ON public.broadcast_recipients(broadcast_id);
+CREATE UNIQUE INDEX idx_broadcast_recipients_token ON public.broadcast_recipients(reschedule_token) WHERE reschedule_token IS NOT NULL;
+
+COMMENT ON COLUMN public.broadcast_recipients.channels IS 'JSON array of the channels the notice was delivered on';
+COMMENT ON COLUMN public.broadcast_recipients.reschedule_token IS 'Token of the link the client opens to pick a new time';
+
+ALTER TABLE public.appointments
+    ADD COLUMN broadcast_id UUID,
+    ADD CONSTRAINT fk_appointments_broadcast FOREIGN KEY (broadcast_id) REFERENCES public.broadcasts(id) ON DELETE SET NULL;
+
+COMMENT ON COLUMN public.appointments.broadcast_id IS 'The latest emergency broadcast about a disruption affecting the appointment';
@@ -0,0 +1,7 @@
+-- Rollback migration for card payments
+
+DROP TABLE IF EXISTS public.payments;
+
+ALTER TABLE public.services
+    DROP COLUMN IF EXISTS deposit_amount,
+    DROP COLUMN IF EXISTS requires_deposit;
@@ -0,0 +1,37 @@
+-- Migration to add card payments taken through Stripe for deposits and completed appointments
+
+ALTER TABLE public.services
+    ADD COLUMN requires_deposit BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN deposit_amount DECIMAL(10,2) CHECK (deposit_amount IS NULL OR deposit_amount >= 0);
+
+COMMENT ON COLUMN public.services.deposit_amount IS 'Deposit charged at booking when requires_deposit is set';
+
+CREATE TABLE public.payments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID NOT NULL,
+    appointment_id UUID NOT NULL,
+    service_completion_id UUID,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('deposit', 'balance')),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'requires_action', 'succeeded', 'failed', 'partially_refunded', 'refunded')),
+    amount DECIMAL(10,2) NOT NULL CHECK (amount > 0),
+    refunded_amount DECIMAL(10,2) NOT NULL DEFAULT 0 CHECK (refunded_amount >= 0 AND refunded_amount <= amount),
+    currency VARCHAR(3) NOT NULL,
+    payment_intent_id VARCHAR(255),
+    failure_reason TEXT,
+    paid_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_payments_business FOREIGN KEY (business_id) REFERENCES public.businesses(id) ON DELETE CASCADE,
+    CONSTRAINT fk_payments_appointment FOREIGN KEY (appointment_id) REFERENCES public.appointments(id) ON DELETE CASCADE,
+    CONSTRAINT fk_payments_service_completion FOREIGN KEY (service_completion_id) REFERENCES public.service_completions(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_payments_appointment ON public.payments(appointment_id);
+CREATE UNIQUE INDEX uq_payments_payment_intent ON public.payments(payment_intent_id) WHERE payment_intent_id IS NOT NULL;
+
+COMMENT ON TABLE public.payments IS 'Card payments for appointments; the Stripe PaymentIntent is the source of truth for the money';
+COMMENT ON COLUMN public.payments.payment_intent_id IS 'ID of the Stripe PaymentIntent; NULL until Stripe has accepted the charge request';
@@ -0,0 +1,3 @@
+-- Rollback migration for the notification queue
+
+DROP TABLE IF EXISTS public.notification_queue;
@@ -0,0 +1,26 @@
+-- Migration to add the queue of notifications waiting for their provider to recover
+
+CREATE TABLE public.notification_queue (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID,
+    channel VARCHAR(20) NOT NULL CHECK (channel IN ('sms', 'email')),
+    recipient VARCHAR(255) NOT NULL,
+    subject VARCHAR(255),
+    body TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_error TEXT,
+    sent_at TIMESTAMP WITH TIME ZONE,
+    abandoned_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_notification_queue_business FOREIGN KEY (business_id) REFERENCES public.businesses(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE public.notification_queue IS 'Messages that could not be delivered because their provider was unavailable, retried with backoff';
+
+CREATE INDEX idx_notification_queue_due ON public.notification_queue(next_attempt_at) WHERE sent_at IS NULL AND abandoned_at IS NULL;
@@ -0,0 +1,3 @@
+-- Rollback migration for the outbound request audit
+
+DROP TABLE IF EXISTS public.outbound_requests;
@@ -0,0 +1,24 @@
+-- Migration to add the audit of calls made to third-party providers
+
+CREATE TABLE public.outbound_requests (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    provider VARCHAR(50) NOT NULL,
+    method VARCHAR(10) NOT NULL,
+    endpoint VARCHAR(500) NOT NULL,
+    status_code INTEGER,
+    latency_ms BIGINT NOT NULL,
+    request_body TEXT,
+    response_body TEXT,
+    error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID
+);
+
+COMMENT ON TABLE public.outbound_requests IS 'Calls made to third-party providers with bodies scrubbed of personal data, kept for a week for debugging';
+
+CREATE INDEX idx_outbound_requests_created_at ON public.outbound_requests(created_at);
+CREATE INDEX idx_outbound_requests_provider ON public.outbound_requests(provider, created_at);
@@ -0,0 +1,4 @@
+-- Rollback migration for client anonymization
+
+ALTER TABLE public.clients
+    DROP COLUMN IF EXISTS anonymized_at;
@@ -0,0 +1,6 @@
+-- Migration to add the anonymization of clients' personal data
+
+ALTER TABLE public.clients
+    ADD COLUMN anonymized_at TIMESTAMP WITH TIME ZONE;
+
+COMMENT ON COLUMN public.clients.anonymized_at IS 'When the personal data of the client was removed on request; the row is kept for the business statistics';
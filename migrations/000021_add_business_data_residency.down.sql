@@ -0,0 +1,7 @@
+-- Rollback migration for business data residency
+
+DROP INDEX IF EXISTS idx_businesses_data_region;
+
+ALTER TABLE public.businesses
+    DROP CONSTRAINT IF EXISTS chk_businesses_data_region,
+    DROP COLUMN IF EXISTS data_region;
@@ -0,0 +1,9 @@
+-- Migration to add the data residency region of businesses
+
+ALTER TABLE public.businesses
+    ADD COLUMN data_region VARCHAR(10) NOT NULL DEFAULT 'eu',
+    ADD CONSTRAINT chk_businesses_data_region CHECK (data_region IN ('eu', 'us'));
+
+CREATE INDEX idx_businesses_data_region ON public.businesses(data_region);
+
+COMMENT ON COLUMN public.businesses.data_region IS 'Region the business''s data must stay in; EU data is never sent to destinations outside the EU';
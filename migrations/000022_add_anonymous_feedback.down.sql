@@ -0,0 +1,4 @@
+-- Rollback migration for anonymous client feedback
+
+DROP TABLE IF EXISTS public.anonymous_feedback;
+DROP TABLE IF EXISTS public.feedback_tokens;
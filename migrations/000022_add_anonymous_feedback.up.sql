@@ -0,0 +1,47 @@
+-- Migration to add anonymous client feedback, sent with a single-use token per completed appointment
+
+CREATE TABLE public.feedback_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID NOT NULL,
+    appointment_id UUID NOT NULL,
+    token VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used BOOLEAN NOT NULL DEFAULT FALSE, -- When it was used is deliberately not recorded
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_feedback_tokens_business FOREIGN KEY (business_id) REFERENCES public.businesses(id),
+    CONSTRAINT fk_feedback_tokens_appointment FOREIGN KEY (appointment_id) REFERENCES public.appointments(id) ON DELETE CASCADE,
+    CONSTRAINT fk_feedback_tokens_created_by FOREIGN KEY (created_by) REFERENCES public.users(id),
+    CONSTRAINT fk_feedback_tokens_updated_by FOREIGN KEY (updated_by) REFERENCES public.users(id),
+    CONSTRAINT fk_feedback_tokens_deleted_by FOREIGN KEY (deleted_by) REFERENCES public.users(id)
+);
+
+CREATE UNIQUE INDEX idx_feedback_tokens_appointment ON public.feedback_tokens(appointment_id);
+CREATE UNIQUE INDEX idx_feedback_tokens_token ON public.feedback_tokens(token);
+CREATE INDEX idx_feedback_tokens_business ON public.feedback_tokens(business_id);
+
+-- Feedback is linked to the business only. It has no client, appointment, token or creation
+-- time, so it cannot be traced back to the visit it is about.
+CREATE TABLE public.anonymous_feedback (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID NOT NULL,
+    rating INTEGER CHECK (rating BETWEEN 1 AND 5),
+    message TEXT NOT NULL,
+    submitted_on DATE NOT NULL,
+    read_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_anonymous_feedback_business FOREIGN KEY (business_id) REFERENCES public.businesses(id),
+    CONSTRAINT fk_anonymous_feedback_updated_by FOREIGN KEY (updated_by) REFERENCES public.users(id),
+    CONSTRAINT fk_anonymous_feedback_deleted_by FOREIGN KEY (deleted_by) REFERENCES public.users(id)
+);
+
+CREATE INDEX idx_anonymous_feedback_business ON public.anonymous_feedback(business_id, submitted_on DESC) WHERE deleted_at IS NULL;
+
+COMMENT ON TABLE public.anonymous_feedback IS 'Feedback clients sent without identifying themselves, visible to the business owner only';
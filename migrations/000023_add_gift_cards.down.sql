@@ -0,0 +1,7 @@
+-- Rollback migration for gift cards
+
+DROP TABLE IF EXISTS public.gift_card_transactions;
+
+ALTER TABLE public.payments DROP COLUMN IF EXISTS gift_card_id;
+
+DROP TABLE IF EXISTS public.gift_cards;
@@ -0,0 +1,66 @@
+-- Migration to add gift cards sold by businesses and redeemed, in one go or in parts, against appointments
+
+CREATE TABLE public.gift_cards (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID NOT NULL,
+    code VARCHAR(20) NOT NULL,
+    initial_value DECIMAL(10,2) NOT NULL CHECK (initial_value > 0),
+    balance DECIMAL(10,2) NOT NULL CHECK (balance >= 0 AND balance <= initial_value),
+    currency VARCHAR(3) NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    purchaser_client_id UUID,
+    recipient_name VARCHAR(200),
+    recipient_email VARCHAR(255),
+    message TEXT,
+    sale_payment_method VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_gift_cards_business FOREIGN KEY (business_id) REFERENCES public.businesses(id) ON DELETE CASCADE,
+    CONSTRAINT fk_gift_cards_purchaser FOREIGN KEY (purchaser_client_id) REFERENCES public.clients(id) ON DELETE SET NULL,
+    CONSTRAINT fk_gift_cards_created_by FOREIGN KEY (created_by) REFERENCES public.users(id),
+    CONSTRAINT fk_gift_cards_updated_by FOREIGN KEY (updated_by) REFERENCES public.users(id),
+    CONSTRAINT fk_gift_cards_deleted_by FOREIGN KEY (deleted_by) REFERENCES public.users(id)
+);
+
+CREATE UNIQUE INDEX uq_gift_cards_code ON public.gift_cards(code);
+CREATE INDEX idx_gift_cards_business ON public.gift_cards(business_id);
+
+COMMENT ON COLUMN public.gift_cards.code IS 'Random code grouped in fours, e.g. ABCD-EFGH-JKLM, which the client presents to redeem the card';
+COMMENT ON COLUMN public.gift_cards.sale_payment_method IS 'How the card itself was paid for: cash, card, transfer or other';
+
+-- Payments redeemed from a gift card have no Stripe PaymentIntent
+ALTER TABLE public.payments
+    ADD COLUMN gift_card_id UUID,
+    ADD CONSTRAINT fk_payments_gift_card FOREIGN KEY (gift_card_id) REFERENCES public.gift_cards(id);
+
+CREATE INDEX idx_payments_gift_card ON public.payments(gift_card_id) WHERE gift_card_id IS NOT NULL;
+
+CREATE TABLE public.gift_card_transactions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID NOT NULL,
+    gift_card_id UUID NOT NULL,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('issue', 'redemption', 'refund')),
+    amount DECIMAL(10,2) NOT NULL CHECK (amount > 0),
+    balance_after DECIMAL(10,2) NOT NULL CHECK (balance_after >= 0),
+    payment_id UUID,
+    appointment_id UUID,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_gift_card_transactions_business FOREIGN KEY (business_id) REFERENCES public.businesses(id) ON DELETE CASCADE,
+    CONSTRAINT fk_gift_card_transactions_gift_card FOREIGN KEY (gift_card_id) REFERENCES public.gift_cards(id) ON DELETE CASCADE,
+    CONSTRAINT fk_gift_card_transactions_payment FOREIGN KEY (payment_id) REFERENCES public.payments(id) ON DELETE SET NULL,
+    CONSTRAINT fk_gift_card_transactions_appointment FOREIGN KEY (appointment_id) REFERENCES public.appointments(id) ON DELETE SET NULL,
+    CONSTRAINT fk_gift_card_transactions_created_by FOREIGN KEY (created_by) REFERENCES public.users(id)
+);
+
+CREATE INDEX idx_gift_card_transactions_gift_card ON public.gift_card_transactions(gift_card_id, created_at);
+
+COMMENT ON TABLE public.gift_card_transactions IS 'Ledger of gift card balance changes: the sale, each redemption and each refund back onto the card';
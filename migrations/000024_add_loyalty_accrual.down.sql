@@ -0,0 +1,6 @@
+-- Rollback migration for loyalty point accrual
+
+DROP INDEX IF EXISTS public.idx_loyalty_transactions_earned_once;
+
+ALTER TABLE public.loyalty_transactions ALTER COLUMN created_by SET NOT NULL;
+ALTER TABLE public.client_loyalty_memberships ALTER COLUMN created_by SET NOT NULL;
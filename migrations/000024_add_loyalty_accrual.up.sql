@@ -0,0 +1,10 @@
+-- Migration for awarding loyalty points when appointments are completed
+
+-- Points are awarded by the loyalty engine, which may run without a user, e.g. on replay
+ALTER TABLE public.client_loyalty_memberships ALTER COLUMN created_by DROP NOT NULL;
+ALTER TABLE public.loyalty_transactions ALTER COLUMN created_by DROP NOT NULL;
+
+-- A membership earns points for an appointment at most once, however often the completion
+-- event is delivered
+CREATE UNIQUE INDEX idx_loyalty_transactions_earned_once ON public.loyalty_transactions(membership_id, appointment_id)
+    WHERE transaction_type = 'earn' AND deleted_at IS NULL;
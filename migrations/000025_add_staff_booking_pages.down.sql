@@ -0,0 +1,3 @@
+-- Rollback migration for staff booking pages
+
+DROP TABLE IF EXISTS public.staff_booking_pages;
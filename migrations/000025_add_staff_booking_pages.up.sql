@@ -0,0 +1,29 @@
+-- Migration to add staff members' personal booking pages, shared as links so that clients book
+-- with them directly
+
+CREATE TABLE public.staff_booking_pages (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID NOT NULL,
+    staff_id UUID NOT NULL,
+    slug VARCHAR(100) NOT NULL,
+    bio TEXT,
+    portfolio_urls JSONB DEFAULT '[]',
+    service_ids JSONB DEFAULT '[]', -- All of the business's services when empty
+    is_published BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_staff_booking_pages_business FOREIGN KEY (business_id) REFERENCES public.businesses(id),
+    CONSTRAINT fk_staff_booking_pages_staff FOREIGN KEY (staff_id) REFERENCES public.staff(id) ON DELETE CASCADE,
+    CONSTRAINT fk_staff_booking_pages_created_by FOREIGN KEY (created_by) REFERENCES public.users(id),
+    CONSTRAINT fk_staff_booking_pages_updated_by FOREIGN KEY (updated_by) REFERENCES public.users(id),
+    CONSTRAINT fk_staff_booking_pages_deleted_by FOREIGN KEY (deleted_by) REFERENCES public.users(id)
+);
+
+-- Slugs stay reserved after a page is deleted, so that old links never book someone else
+CREATE UNIQUE INDEX idx_staff_booking_pages_slug ON public.staff_booking_pages(slug);
+CREATE UNIQUE INDEX idx_staff_booking_pages_staff ON public.staff_booking_pages(staff_id);
+CREATE INDEX idx_staff_booking_pages_business ON public.staff_booking_pages(business_id);
@@ -0,0 +1,5 @@
+-- Rollback migration for business modes
+
+ALTER TABLE public.businesses
+    DROP CONSTRAINT IF EXISTS chk_businesses_mode,
+    DROP COLUMN IF EXISTS mode;
@@ -0,0 +1,6 @@
+-- Migration to add the way a business is run. Solo businesses are run by a professional on
+-- their own, without staff management or per-staff calendars.
+
+ALTER TABLE public.businesses
+    ADD COLUMN mode VARCHAR(10) NOT NULL DEFAULT 'team',
+    ADD CONSTRAINT chk_businesses_mode CHECK (mode IN ('team', 'solo'));
@@ -0,0 +1,9 @@
+-- Rollback migration for booking windows
+
+ALTER TABLE public.services
+    DROP COLUMN IF EXISTS max_advance_booking,
+    DROP COLUMN IF EXISTS min_advance_booking;
+
+ALTER TABLE public.business_settings
+    DROP COLUMN IF EXISTS max_booking_horizon_days,
+    DROP COLUMN IF EXISTS min_booking_lead_hours;
@@ -0,0 +1,17 @@
+-- Migration to add how far ahead clients can book, per business and optionally per service
+
+ALTER TABLE public.business_settings
+    ADD COLUMN min_booking_lead_hours INTEGER NOT NULL DEFAULT 0
+        CHECK (min_booking_lead_hours >= 0 AND min_booking_lead_hours <= 168),
+    ADD COLUMN max_booking_horizon_days INTEGER NOT NULL DEFAULT 0
+        CHECK (max_booking_horizon_days >= 0 AND max_booking_horizon_days <= 730);
+
+COMMENT ON COLUMN public.business_settings.min_booking_lead_hours IS 'Shortest notice in hours clients must give when booking; 0 allows booking until the appointment starts';
+COMMENT ON COLUMN public.business_settings.max_booking_horizon_days IS 'How many days ahead clients can book at most; 0 for no limit';
+
+ALTER TABLE public.services
+    ADD COLUMN min_advance_booking INTEGER CHECK (min_advance_booking IS NULL OR min_advance_booking BETWEEN 0 AND 168),
+    ADD COLUMN max_advance_booking INTEGER CHECK (max_advance_booking IS NULL OR max_advance_booking BETWEEN 0 AND 730);
+
+COMMENT ON COLUMN public.services.min_advance_booking IS 'Lead time in hours overriding the business''s; NULL to use the business''s';
+COMMENT ON COLUMN public.services.max_advance_booking IS 'Booking horizon in days overriding the business''s; NULL to use the business''s';
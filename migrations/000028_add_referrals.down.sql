@@ -0,0 +1,5 @@
+-- Rollback migration for loyalty program referrals
+
+DROP TABLE IF EXISTS public.referrals;
+DROP INDEX IF EXISTS public.idx_client_loyalty_memberships_referral_code;
+ALTER TABLE public.client_loyalty_memberships DROP COLUMN IF EXISTS referral_code;
@@ -0,0 +1,39 @@
+-- Migration to add referrals to loyalty programs: members share a referral code, and are
+-- awarded the program's referral bonus once a client they referred completes a first visit
+
+ALTER TABLE public.client_loyalty_memberships ADD COLUMN referral_code VARCHAR(20);
+CREATE UNIQUE INDEX idx_client_loyalty_memberships_referral_code ON public.client_loyalty_memberships(referral_code);
+
+CREATE TABLE public.referrals (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID NOT NULL,
+    program_id UUID NOT NULL,
+    referrer_membership_id UUID NOT NULL,
+    referrer_client_id UUID NOT NULL,
+    referred_client_id UUID NOT NULL,
+    code VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- 'pending', 'converted'
+    appointment_id UUID, -- The referred client's first completed visit
+    converted_at TIMESTAMP WITH TIME ZONE,
+    bonus_points INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_referrals_business FOREIGN KEY (business_id) REFERENCES public.businesses(id),
+    CONSTRAINT fk_referrals_program FOREIGN KEY (program_id) REFERENCES public.loyalty_programs(id) ON DELETE CASCADE,
+    CONSTRAINT fk_referrals_referrer_membership FOREIGN KEY (referrer_membership_id) REFERENCES public.client_loyalty_memberships(id) ON DELETE CASCADE,
+    CONSTRAINT fk_referrals_referrer_client FOREIGN KEY (referrer_client_id) REFERENCES public.clients(id) ON DELETE CASCADE,
+    CONSTRAINT fk_referrals_referred_client FOREIGN KEY (referred_client_id) REFERENCES public.clients(id) ON DELETE CASCADE,
+    CONSTRAINT fk_referrals_appointment FOREIGN KEY (appointment_id) REFERENCES public.appointments(id) ON DELETE SET NULL,
+    CONSTRAINT fk_referrals_created_by FOREIGN KEY (created_by) REFERENCES public.users(id),
+    CONSTRAINT fk_referrals_updated_by FOREIGN KEY (updated_by) REFERENCES public.users(id),
+    CONSTRAINT fk_referrals_deleted_by FOREIGN KEY (deleted_by) REFERENCES public.users(id)
+);
+
+-- A client is referred to a business once
+CREATE UNIQUE INDEX idx_referrals_referred_client ON public.referrals(business_id, referred_client_id);
+CREATE INDEX idx_referrals_business_created ON public.referrals(business_id, created_at);
+CREATE INDEX idx_referrals_referrer_membership ON public.referrals(referrer_membership_id);
@@ -0,0 +1,5 @@
+-- Rollback migration for client tags
+
+DROP INDEX IF EXISTS public.idx_appointments_business_client_completed;
+DROP INDEX IF EXISTS public.idx_clients_tags;
+ALTER TABLE public.clients DROP COLUMN IF EXISTS tags;
@@ -0,0 +1,13 @@
+-- Migration to add client tags, which campaigns target together with the visit, spend,
+-- birthday and loyalty criteria of their audience
+
+ALTER TABLE public.clients
+    ADD COLUMN tags JSONB NOT NULL DEFAULT '[]';
+
+COMMENT ON COLUMN public.clients.tags IS 'Lower-case tags of the client, e.g. ["vip", "bridal"]';
+
+-- Audience queries match tags with the containment operator
+CREATE INDEX idx_clients_tags ON public.clients USING GIN (tags);
+-- Audience queries sum up each client's completed appointments
+CREATE INDEX idx_appointments_business_client_completed ON public.appointments(business_id, client_id)
+    WHERE status = 'completed' AND deleted_at IS NULL;
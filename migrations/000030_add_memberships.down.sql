@@ -0,0 +1,5 @@
+-- Rollback migration for recurring memberships
+
+DROP TABLE IF EXISTS public.membership_invoices;
+DROP TABLE IF EXISTS public.client_memberships;
+DROP TABLE IF EXISTS public.membership_plans;
@@ -0,0 +1,99 @@
+-- Migration to add recurring memberships: plans billed monthly to the client's stored card,
+-- with an invoice per period and dunning of failed payments
+
+CREATE TABLE public.membership_plans (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    price DECIMAL(10, 2) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    billing_interval VARCHAR(20) NOT NULL DEFAULT 'monthly',
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_membership_plans_business FOREIGN KEY (business_id) REFERENCES public.businesses(id),
+    CONSTRAINT fk_membership_plans_created_by FOREIGN KEY (created_by) REFERENCES public.users(id),
+    CONSTRAINT fk_membership_plans_updated_by FOREIGN KEY (updated_by) REFERENCES public.users(id),
+    CONSTRAINT fk_membership_plans_deleted_by FOREIGN KEY (deleted_by) REFERENCES public.users(id)
+);
+
+CREATE INDEX idx_membership_plans_business ON public.membership_plans(business_id);
+
+CREATE TABLE public.client_memberships (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID NOT NULL,
+    plan_id UUID NOT NULL,
+    client_id UUID NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'active', -- 'active', 'past_due', 'suspended', 'cancelled'
+    price DECIMAL(10, 2) NOT NULL, -- Fixed when the client joined
+    currency VARCHAR(3) NOT NULL,
+    stripe_customer_id VARCHAR(255),
+    payment_method_id VARCHAR(255) NOT NULL, -- The stored card or mandate charged each period
+    billing_day INTEGER NOT NULL,
+    current_period_start TIMESTAMP WITH TIME ZONE,
+    current_period_end TIMESTAMP WITH TIME ZONE,
+    next_billing_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    failed_attempts INTEGER NOT NULL DEFAULT 0,
+    next_retry_at TIMESTAMP WITH TIME ZONE,
+    grace_until TIMESTAMP WITH TIME ZONE,
+    suspended_at TIMESTAMP WITH TIME ZONE,
+    cancelled_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_client_memberships_business FOREIGN KEY (business_id) REFERENCES public.businesses(id),
+    CONSTRAINT fk_client_memberships_plan FOREIGN KEY (plan_id) REFERENCES public.membership_plans(id),
+    CONSTRAINT fk_client_memberships_client FOREIGN KEY (client_id) REFERENCES public.clients(id) ON DELETE CASCADE,
+    CONSTRAINT fk_client_memberships_created_by FOREIGN KEY (created_by) REFERENCES public.users(id),
+    CONSTRAINT fk_client_memberships_updated_by FOREIGN KEY (updated_by) REFERENCES public.users(id),
+    CONSTRAINT fk_client_memberships_deleted_by FOREIGN KEY (deleted_by) REFERENCES public.users(id)
+);
+
+CREATE INDEX idx_client_memberships_client ON public.client_memberships(client_id);
+CREATE INDEX idx_client_memberships_business ON public.client_memberships(business_id);
+-- A client has one membership of a plan that is not cancelled
+CREATE UNIQUE INDEX idx_client_memberships_current ON public.client_memberships(client_id, plan_id)
+    WHERE status <> 'cancelled' AND deleted_at IS NULL;
+-- The billing run looks up due and past due memberships
+CREATE INDEX idx_client_memberships_next_billing ON public.client_memberships(next_billing_at) WHERE status = 'active';
+CREATE INDEX idx_client_memberships_dunning ON public.client_memberships(next_retry_at, grace_until) WHERE status = 'past_due';
+
+CREATE TABLE public.membership_invoices (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID NOT NULL,
+    membership_id UUID NOT NULL,
+    client_id UUID NOT NULL,
+    period_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    period_end TIMESTAMP WITH TIME ZONE NOT NULL,
+    amount DECIMAL(10, 2) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open', -- 'open', 'paid', 'uncollectible'
+    attempts INTEGER NOT NULL DEFAULT 0,
+    payment_intent_id VARCHAR(255),
+    failure_reason TEXT,
+    paid_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_membership_invoices_business FOREIGN KEY (business_id) REFERENCES public.businesses(id),
+    CONSTRAINT fk_membership_invoices_membership FOREIGN KEY (membership_id) REFERENCES public.client_memberships(id) ON DELETE CASCADE,
+    CONSTRAINT fk_membership_invoices_client FOREIGN KEY (client_id) REFERENCES public.clients(id) ON DELETE CASCADE,
+    CONSTRAINT fk_membership_invoices_created_by FOREIGN KEY (created_by) REFERENCES public.users(id),
+    CONSTRAINT fk_membership_invoices_updated_by FOREIGN KEY (updated_by) REFERENCES public.users(id),
+    CONSTRAINT fk_membership_invoices_deleted_by FOREIGN KEY (deleted_by) REFERENCES public.users(id)
+);
+
+-- A period is invoiced once, so an interrupted billing run cannot bill it twice
+CREATE UNIQUE INDEX idx_membership_invoices_period ON public.membership_invoices(membership_id, period_start);
+CREATE INDEX idx_membership_invoices_business ON public.membership_invoices(business_id);
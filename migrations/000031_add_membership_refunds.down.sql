@@ -0,0 +1,15 @@
+-- Rollback migration for session packages and pro-rated membership cancellation
+
+DROP TABLE IF EXISTS public.membership_credit_notes;
+
+ALTER TABLE public.client_memberships
+    DROP CONSTRAINT IF EXISTS chk_client_memberships_sessions_remaining,
+    DROP COLUMN IF EXISTS sessions_remaining,
+    DROP COLUMN IF EXISTS sessions_per_period;
+
+ALTER TABLE public.membership_plans
+    DROP CONSTRAINT IF EXISTS chk_membership_plans_cancellation_fee_percent,
+    DROP CONSTRAINT IF EXISTS chk_membership_plans_sessions_per_period,
+    DROP COLUMN IF EXISTS cancellation_fee_percent,
+    DROP COLUMN IF EXISTS refund_policy,
+    DROP COLUMN IF EXISTS sessions_per_period;
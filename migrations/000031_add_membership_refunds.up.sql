@@ -0,0 +1,50 @@
+-- Migration to add session packages and pro-rated cancellation to memberships: plans can
+-- include a number of sessions each period, and their refund policy decides whether the
+-- unused part of the paid period is refunded or credited when a membership is cancelled
+
+ALTER TABLE public.membership_plans
+    ADD COLUMN sessions_per_period INTEGER,
+    ADD COLUMN refund_policy VARCHAR(20) NOT NULL DEFAULT 'none', -- 'none', 'refund', 'credit'
+    ADD COLUMN cancellation_fee_percent INTEGER NOT NULL DEFAULT 0,
+    ADD CONSTRAINT chk_membership_plans_sessions_per_period CHECK (sessions_per_period IS NULL OR sessions_per_period > 0),
+    ADD CONSTRAINT chk_membership_plans_cancellation_fee_percent CHECK (cancellation_fee_percent BETWEEN 0 AND 100);
+
+ALTER TABLE public.client_memberships
+    ADD COLUMN sessions_per_period INTEGER,
+    ADD COLUMN sessions_remaining INTEGER,
+    ADD CONSTRAINT chk_client_memberships_sessions_remaining CHECK (sessions_remaining IS NULL OR sessions_remaining >= 0);
+
+CREATE TABLE public.membership_credit_notes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID NOT NULL,
+    membership_id UUID NOT NULL,
+    invoice_id UUID NOT NULL, -- The paid invoice credited
+    client_id UUID NOT NULL,
+    kind VARCHAR(20) NOT NULL, -- 'refund', 'credit'
+    unused_share DECIMAL(5, 4) NOT NULL,
+    fee DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    amount DECIMAL(10, 2) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    sessions_forfeited INTEGER NOT NULL DEFAULT 0,
+    refund_id VARCHAR(255),
+    gift_card_id UUID, -- Holding the store credit
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_membership_credit_notes_business FOREIGN KEY (business_id) REFERENCES public.businesses(id),
+    CONSTRAINT fk_membership_credit_notes_membership FOREIGN KEY (membership_id) REFERENCES public.client_memberships(id) ON DELETE CASCADE,
+    CONSTRAINT fk_membership_credit_notes_invoice FOREIGN KEY (invoice_id) REFERENCES public.membership_invoices(id) ON DELETE CASCADE,
+    CONSTRAINT fk_membership_credit_notes_client FOREIGN KEY (client_id) REFERENCES public.clients(id) ON DELETE CASCADE,
+    CONSTRAINT fk_membership_credit_notes_gift_card FOREIGN KEY (gift_card_id) REFERENCES public.gift_cards(id),
+    CONSTRAINT fk_membership_credit_notes_created_by FOREIGN KEY (created_by) REFERENCES public.users(id),
+    CONSTRAINT fk_membership_credit_notes_updated_by FOREIGN KEY (updated_by) REFERENCES public.users(id),
+    CONSTRAINT fk_membership_credit_notes_deleted_by FOREIGN KEY (deleted_by) REFERENCES public.users(id)
+);
+
+CREATE INDEX idx_membership_credit_notes_business ON public.membership_credit_notes(business_id);
+CREATE INDEX idx_membership_credit_notes_membership ON public.membership_credit_notes(membership_id);
+-- A paid period is refunded or credited at most once
+CREATE UNIQUE INDEX idx_membership_credit_notes_invoice ON public.membership_credit_notes(invoice_id);
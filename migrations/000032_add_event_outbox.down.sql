@@ -0,0 +1,9 @@
+-- Rollback migration for the event outbox
+
+DROP INDEX IF EXISTS public.idx_domain_events_unpublished;
+ALTER TABLE public.domain_events
+    DROP COLUMN IF EXISTS abandoned_at,
+    DROP COLUMN IF EXISTS publish_error,
+    DROP COLUMN IF EXISTS next_publish_at,
+    DROP COLUMN IF EXISTS publish_attempts,
+    DROP COLUMN IF EXISTS published_at;
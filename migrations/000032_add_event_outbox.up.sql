@@ -0,0 +1,20 @@
+-- Migration to turn the domain event log into a transactional outbox: services record events in
+-- the transaction of the change, and a relay publishes them to subscribers once committed
+
+ALTER TABLE public.domain_events
+    ADD COLUMN published_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN publish_attempts INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN next_publish_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN publish_error TEXT,
+    ADD COLUMN abandoned_at TIMESTAMP WITH TIME ZONE;
+
+COMMENT ON COLUMN public.domain_events.published_at IS 'When every subscriber took the event';
+COMMENT ON COLUMN public.domain_events.next_publish_at IS 'Earliest next publish attempt; also leases the event to a relay';
+COMMENT ON COLUMN public.domain_events.abandoned_at IS 'When the relay gave up publishing the event';
+
+-- Events recorded so far were delivered synchronously when they were published
+UPDATE public.domain_events SET published_at = occurred_at, publish_attempts = 1;
+
+-- The relay claims pending events oldest first
+CREATE INDEX idx_domain_events_unpublished ON public.domain_events(occurred_at)
+    WHERE published_at IS NULL AND abandoned_at IS NULL;
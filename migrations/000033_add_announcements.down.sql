@@ -0,0 +1,4 @@
+-- Rollback migration for announcements
+
+DROP TABLE IF EXISTS public.announcement_reads;
+DROP TABLE IF EXISTS public.announcements;
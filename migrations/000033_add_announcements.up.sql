@@ -0,0 +1,37 @@
+-- Migration to add announcements: product updates the platform posts to the in-app feed of
+-- businesses, targeted by subscription tier and locale, with reads tracked per user
+
+CREATE TABLE public.announcements (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    title VARCHAR(200) NOT NULL,
+    body TEXT NOT NULL, -- Markdown
+    link VARCHAR(500),
+    plans JSONB NOT NULL DEFAULT '[]', -- Subscription tiers targeted; empty targets every tier
+    locales JSONB NOT NULL DEFAULT '[]', -- Locales targeted; empty targets every locale
+    publish_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT chk_announcements_expires_at CHECK (expires_at IS NULL OR expires_at > publish_at)
+);
+
+COMMENT ON TABLE public.announcements IS 'Product updates shown in the in-app feed of businesses';
+
+CREATE INDEX idx_announcements_publish_at ON public.announcements(publish_at) WHERE deleted_at IS NULL;
+
+CREATE TABLE public.announcement_reads (
+    announcement_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    read_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (announcement_id, user_id),
+    CONSTRAINT fk_announcement_reads_announcement FOREIGN KEY (announcement_id) REFERENCES public.announcements(id) ON DELETE CASCADE,
+    CONSTRAINT fk_announcement_reads_user FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE public.announcement_reads IS 'When each user read each announcement';
+
+CREATE INDEX idx_announcement_reads_user ON public.announcement_reads(user_id);
@@ -0,0 +1,4 @@
+-- Rollback migration for webhooks
+
+DROP TABLE IF EXISTS public.webhook_deliveries;
+DROP TABLE IF EXISTS public.webhook_subscriptions;
@@ -0,0 +1,56 @@
+-- Migration to add webhooks: endpoints of third-party integrations subscribed to events of a
+-- business, and the log of signed deliveries sent to them with retries
+
+CREATE TABLE public.webhook_subscriptions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID NOT NULL,
+    url VARCHAR(500) NOT NULL,
+    secret VARCHAR(100) NOT NULL, -- Signs deliveries with HMAC-SHA256
+    event_types JSONB NOT NULL DEFAULT '[]',
+    description VARCHAR(255),
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_webhook_subscriptions_business FOREIGN KEY (business_id) REFERENCES public.businesses(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE public.webhook_subscriptions IS 'Endpoints receiving events of a business as signed JSON requests';
+
+CREATE INDEX idx_webhook_subscriptions_business_id ON public.webhook_subscriptions(business_id) WHERE deleted_at IS NULL;
+
+CREATE TABLE public.webhook_deliveries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID NOT NULL,
+    subscription_id UUID NOT NULL,
+    event_id UUID NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    payload TEXT NOT NULL, -- Fixed when the delivery is created, so retries send the same body
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_attempt_at TIMESTAMP WITH TIME ZONE,
+    status_code INTEGER,
+    last_error TEXT,
+    duration_ms BIGINT,
+    delivered_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_webhook_deliveries_business FOREIGN KEY (business_id) REFERENCES public.businesses(id) ON DELETE CASCADE,
+    CONSTRAINT fk_webhook_deliveries_subscription FOREIGN KEY (subscription_id) REFERENCES public.webhook_subscriptions(id) ON DELETE CASCADE,
+    CONSTRAINT chk_webhook_deliveries_status CHECK (status IN ('pending', 'delivered', 'failed'))
+);
+
+COMMENT ON TABLE public.webhook_deliveries IS 'Deliveries of events to webhook subscriptions, logging the last attempt';
+
+-- An event published again is not delivered twice to the same subscription
+CREATE UNIQUE INDEX idx_webhook_deliveries_subscription_event ON public.webhook_deliveries(subscription_id, event_id);
+CREATE INDEX idx_webhook_deliveries_business_id ON public.webhook_deliveries(business_id);
+CREATE INDEX idx_webhook_deliveries_due ON public.webhook_deliveries(next_attempt_at) WHERE status = 'pending';
@@ -0,0 +1,3 @@
+-- Rollback migration for sandbox businesses
+
+ALTER TABLE public.businesses DROP COLUMN IF EXISTS is_sandbox;
@@ -0,0 +1,7 @@
+-- Migration to add sandbox businesses: demo tenants used by sales demos and automated end-to-end
+-- test environments, whose transactional data can be reset to the standard demo dataset
+
+ALTER TABLE public.businesses
+    ADD COLUMN is_sandbox BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN public.businesses.is_sandbox IS 'Whether the business is a demo tenant whose data can be reset';
@@ -0,0 +1,18 @@
+-- Rollback migration for confirmation request escalation
+
+DROP TABLE IF EXISTS public.front_desk_tasks;
+
+DROP INDEX IF EXISTS public.idx_appointment_confirmations_unescalated;
+DROP INDEX IF EXISTS public.idx_appointment_confirmations_attempt;
+DELETE FROM public.appointment_confirmations WHERE attempt > 1;
+CREATE UNIQUE INDEX idx_appointment_confirmations_appointment_id ON public.appointment_confirmations(appointment_id);
+
+ALTER TABLE public.appointment_confirmations
+    DROP COLUMN IF EXISTS escalated_at,
+    DROP COLUMN IF EXISTS delivery_error,
+    DROP COLUMN IF EXISTS delivered_at,
+    DROP COLUMN IF EXISTS attempt;
+
+COMMENT ON TABLE public.appointment_confirmations IS 'Confirmation requests sent to clients ahead of their appointments, one per appointment';
+
+ALTER TABLE public.business_settings DROP COLUMN IF EXISTS confirmation_cutoff_hours;
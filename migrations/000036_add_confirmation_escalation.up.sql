@@ -0,0 +1,52 @@
+-- Migration to escalate unacknowledged confirmation requests: each appointment now has a chain
+-- of requests, one per attempt, and clients who acknowledge none of them are handed to the front
+-- desk as a task to call them
+
+ALTER TABLE public.business_settings
+    ADD COLUMN confirmation_cutoff_hours INTEGER NOT NULL DEFAULT 4
+    CHECK (confirmation_cutoff_hours >= 0 AND confirmation_cutoff_hours <= 72);
+
+COMMENT ON COLUMN public.business_settings.confirmation_cutoff_hours IS 'How many hours clients have to acknowledge a confirmation request before it is escalated; 0 disables escalation';
+
+ALTER TABLE public.appointment_confirmations
+    ADD COLUMN attempt INTEGER NOT NULL DEFAULT 1 CHECK (attempt >= 1),
+    ADD COLUMN delivered_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN delivery_error TEXT,
+    ADD COLUMN escalated_at TIMESTAMP WITH TIME ZONE;
+
+COMMENT ON COLUMN public.appointment_confirmations.attempt IS 'The position of the request in the chain of its appointment, starting at 1';
+COMMENT ON COLUMN public.appointment_confirmations.delivered_at IS 'When the messaging provider accepted the request';
+COMMENT ON COLUMN public.appointment_confirmations.escalated_at IS 'When the request was escalated for going unacknowledged';
+COMMENT ON TABLE public.appointment_confirmations IS 'Confirmation requests sent to clients ahead of their appointments, one per attempt';
+
+DROP INDEX IF EXISTS public.idx_appointment_confirmations_appointment_id;
+CREATE UNIQUE INDEX idx_appointment_confirmations_attempt ON public.appointment_confirmations(appointment_id, attempt);
+CREATE INDEX idx_appointment_confirmations_unescalated ON public.appointment_confirmations(sent_at) WHERE escalated_at IS NULL AND status <> 'confirmed';
+
+CREATE TABLE public.front_desk_tasks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID NOT NULL,
+    appointment_id UUID,
+    client_id UUID,
+    kind VARCHAR(30) NOT NULL CHECK (kind IN ('call_client')),
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'done')),
+    reason TEXT NOT NULL,
+    due_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    completed_by UUID,
+    outcome TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_front_desk_tasks_business FOREIGN KEY (business_id) REFERENCES public.businesses(id) ON DELETE CASCADE,
+    CONSTRAINT fk_front_desk_tasks_appointment FOREIGN KEY (appointment_id) REFERENCES public.appointments(id) ON DELETE CASCADE,
+    CONSTRAINT fk_front_desk_tasks_client FOREIGN KEY (client_id) REFERENCES public.clients(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE public.front_desk_tasks IS 'To-dos for the front desk of a business, e.g. calling clients who did not confirm an appointment';
+
+CREATE INDEX idx_front_desk_tasks_business ON public.front_desk_tasks(business_id, status, due_at) WHERE deleted_at IS NULL;
+CREATE INDEX idx_front_desk_tasks_appointment ON public.front_desk_tasks(appointment_id);
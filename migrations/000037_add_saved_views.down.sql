@@ -0,0 +1,3 @@
+-- Rollback migration for saved views
+
+DROP TABLE IF EXISTS public.saved_views;
@@ -0,0 +1,28 @@
+-- Migration to add saved views: named filters and sorts of the client and appointment lists that
+-- users save server-side, so that they follow them across devices
+
+CREATE TABLE public.saved_views (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    entity VARCHAR(20) NOT NULL CHECK (entity IN ('clients', 'appointments')),
+    filters JSONB NOT NULL DEFAULT '{}',
+    sort_field VARCHAR(50),
+    sort_descending BOOLEAN NOT NULL DEFAULT FALSE,
+    shared_with_roles JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_saved_views_business FOREIGN KEY (business_id) REFERENCES public.businesses(id) ON DELETE CASCADE,
+    CONSTRAINT fk_saved_views_user FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE public.saved_views IS 'Named filters and sorts of the client and appointment lists saved by users';
+COMMENT ON COLUMN public.saved_views.shared_with_roles IS 'The business roles whose staff can use the view besides its owner; empty when private';
+
+CREATE UNIQUE INDEX idx_saved_views_owner_name ON public.saved_views(business_id, user_id, entity, LOWER(name)) WHERE deleted_at IS NULL;
+CREATE INDEX idx_saved_views_shared ON public.saved_views USING GIN (shared_with_roles) WHERE deleted_at IS NULL;
@@ -0,0 +1,3 @@
+-- Rollback migration for import batches
+
+DROP TABLE IF EXISTS public.import_batches;
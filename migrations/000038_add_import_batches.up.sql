@@ -0,0 +1,27 @@
+-- Migration to add import batches: the service menus and staff rosters imported from CSV files,
+-- with the records each import created so that it can be rolled back
+
+CREATE TABLE public.import_batches (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID NOT NULL,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('services', 'staff')),
+    status VARCHAR(20) NOT NULL DEFAULT 'imported' CHECK (status IN ('imported', 'rolled_back')),
+    total_rows INTEGER NOT NULL,
+    imported INTEGER NOT NULL,
+    records JSONB NOT NULL DEFAULT '{}',
+    rolled_back_at TIMESTAMP WITH TIME ZONE,
+    rolled_back_by UUID,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_import_batches_business FOREIGN KEY (business_id) REFERENCES public.businesses(id) ON DELETE CASCADE,
+    CONSTRAINT fk_import_batches_rolled_back_by FOREIGN KEY (rolled_back_by) REFERENCES public.users(id) ON DELETE SET NULL
+);
+
+COMMENT ON TABLE public.import_batches IS 'Imports of services and staff from CSV files, which can be rolled back';
+COMMENT ON COLUMN public.import_batches.records IS 'The IDs of the categories, services, staff members and user accounts the import created';
+
+CREATE INDEX idx_import_batches_business ON public.import_batches(business_id, created_at DESC) WHERE deleted_at IS NULL;
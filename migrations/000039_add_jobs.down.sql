@@ -0,0 +1,3 @@
+-- Rollback migration for the background job queue
+
+DROP TABLE IF EXISTS public.jobs;
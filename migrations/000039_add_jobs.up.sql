@@ -0,0 +1,32 @@
+-- Migration to add the background job queue: work run by workers once due, retried with backoff
+-- and dead-lettered once it has failed every attempt
+
+CREATE TABLE public.jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID,
+    kind VARCHAR(100) NOT NULL,
+    unique_key VARCHAR(200),
+    payload JSONB NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'succeeded', 'dead', 'cancelled')),
+    run_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL DEFAULT 8 CHECK (max_attempts > 0),
+    last_error TEXT,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_jobs_business FOREIGN KEY (business_id) REFERENCES public.businesses(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE public.jobs IS 'Background jobs, claimed by workers once due';
+COMMENT ON COLUMN public.jobs.run_at IS 'When the job is due; claiming a job pushes it forward to lease the job to the worker';
+COMMENT ON COLUMN public.jobs.unique_key IS 'At most one pending job of a kind has a key, so that enqueueing it again is a no-op';
+
+CREATE INDEX idx_jobs_due ON public.jobs(run_at) WHERE status = 'pending' AND deleted_at IS NULL;
+CREATE INDEX idx_jobs_kind_status ON public.jobs(kind, status) WHERE deleted_at IS NULL;
+CREATE INDEX idx_jobs_business ON public.jobs(business_id) WHERE business_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_jobs_pending_key ON public.jobs(kind, unique_key) WHERE status = 'pending' AND unique_key IS NOT NULL AND deleted_at IS NULL;
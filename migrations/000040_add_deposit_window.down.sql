@@ -0,0 +1,4 @@
+-- Rollback migration for deposit windows
+
+ALTER TABLE public.business_settings
+    DROP COLUMN IF EXISTS deposit_window_hours;
@@ -0,0 +1,7 @@
+-- Migration to add how long clients have to pay a deposit before their appointment is released
+
+ALTER TABLE public.business_settings
+    ADD COLUMN deposit_window_hours INTEGER NOT NULL DEFAULT 24
+        CHECK (deposit_window_hours >= 0 AND deposit_window_hours <= 168);
+
+COMMENT ON COLUMN public.business_settings.deposit_window_hours IS 'Hours after booking within which a required deposit must be paid before the appointment is cancelled; 0 keeps unpaid appointments booked';
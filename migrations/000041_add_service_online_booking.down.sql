@@ -0,0 +1,6 @@
+-- Rollback migration for per-service online booking rules
+
+ALTER TABLE public.services
+    DROP COLUMN IF EXISTS max_per_day,
+    DROP COLUMN IF EXISTS returning_clients_only,
+    DROP COLUMN IF EXISTS online_booking;
@@ -0,0 +1,11 @@
+-- Migration to add how each service is offered online, and the rules for booking it online
+
+ALTER TABLE public.services
+    ADD COLUMN online_booking VARCHAR(20) NOT NULL DEFAULT 'bookable'
+        CHECK (online_booking IN ('bookable', 'call_to_book', 'hidden')),
+    ADD COLUMN returning_clients_only BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN max_per_day INTEGER CHECK (max_per_day IS NULL OR max_per_day BETWEEN 1 AND 100);
+
+COMMENT ON COLUMN public.services.online_booking IS 'bookable, call_to_book to list the service without taking online bookings, or hidden to leave it out of online booking';
+COMMENT ON COLUMN public.services.returning_clients_only IS 'Only clients with a completed appointment, such as a consultation, can book the service online';
+COMMENT ON COLUMN public.services.max_per_day IS 'Most bookings of the service a day across staff before online booking closes for the day; NULL for no limit';
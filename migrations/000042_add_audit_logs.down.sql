@@ -0,0 +1,3 @@
+-- Rollback migration for the audit log
+
+DROP TABLE IF EXISTS public.audit_logs;
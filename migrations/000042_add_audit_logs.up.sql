@@ -0,0 +1,30 @@
+-- Migration to add the audit log: who created, changed or deleted which record, and the fields
+-- they changed
+
+CREATE TABLE public.audit_logs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID,
+    entity_type VARCHAR(100) NOT NULL,
+    entity_id VARCHAR(100) NOT NULL,
+    action VARCHAR(20) NOT NULL CHECK (action IN ('create', 'update', 'delete')),
+    changes JSONB,
+    actor_id UUID,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_audit_logs_business FOREIGN KEY (business_id) REFERENCES public.businesses(id) ON DELETE CASCADE,
+    CONSTRAINT fk_audit_logs_actor FOREIGN KEY (actor_id) REFERENCES public.users(id) ON DELETE SET NULL
+);
+
+COMMENT ON TABLE public.audit_logs IS 'Append-only record of the writes made through the repositories, written in the transaction of each write';
+COMMENT ON COLUMN public.audit_logs.entity_type IS 'The table of the record changed, e.g. appointments';
+COMMENT ON COLUMN public.audit_logs.changes IS 'Changed columns mapped to their values before and after, secrets redacted; NULL for deletions';
+COMMENT ON COLUMN public.audit_logs.actor_id IS 'The user who made the change; NULL for changes made by the system';
+
+CREATE INDEX idx_audit_logs_business ON public.audit_logs(business_id, occurred_at DESC) WHERE deleted_at IS NULL;
+CREATE INDEX idx_audit_logs_entity ON public.audit_logs(entity_type, entity_id);
+CREATE INDEX idx_audit_logs_actor ON public.audit_logs(actor_id) WHERE actor_id IS NOT NULL;
@@ -0,0 +1,9 @@
+-- Rollback migration for restoring soft-deleted records
+
+DROP INDEX IF EXISTS public.idx_appointments_trash;
+DROP INDEX IF EXISTS public.idx_services_trash;
+DROP INDEX IF EXISTS public.idx_clients_trash;
+
+DELETE FROM public.audit_logs WHERE action = 'restore';
+ALTER TABLE public.audit_logs DROP CONSTRAINT IF EXISTS audit_logs_action_check;
+ALTER TABLE public.audit_logs ADD CONSTRAINT audit_logs_action_check CHECK (action IN ('create', 'update', 'delete'));
@@ -0,0 +1,9 @@
+-- Migration to support restoring soft-deleted records: restorations are recorded in the audit
+-- log, and the trash of clients, services and appointments is listed by business
+
+ALTER TABLE public.audit_logs DROP CONSTRAINT IF EXISTS audit_logs_action_check;
+ALTER TABLE public.audit_logs ADD CONSTRAINT audit_logs_action_check CHECK (action IN ('create', 'update', 'delete', 'restore'));
+
+CREATE INDEX idx_clients_trash ON public.clients(business_id, deleted_at DESC) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_services_trash ON public.services(business_id, deleted_at DESC) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_appointments_trash ON public.appointments(business_id, deleted_at DESC) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,13 @@
+-- Rollback migration for the entity versions used for optimistic locking
+
+DO $$
+DECLARE
+    entity_table TEXT;
+BEGIN
+    FOR entity_table IN
+        SELECT table_name FROM information_schema.columns
+        WHERE table_schema = 'public' AND column_name = 'deleted_at'
+    LOOP
+        EXECUTE format('ALTER TABLE public.%I DROP COLUMN IF EXISTS version', entity_table);
+    END LOOP;
+END $$;
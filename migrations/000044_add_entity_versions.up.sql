@@ -0,0 +1,14 @@
+-- Migration to add the version column every entity carries for optimistic locking: updates
+-- require the version they read and advance it, so concurrent updates cannot overwrite each other
+
+DO $$
+DECLARE
+    entity_table TEXT;
+BEGIN
+    FOR entity_table IN
+        SELECT table_name FROM information_schema.columns
+        WHERE table_schema = 'public' AND column_name = 'deleted_at'
+    LOOP
+        EXECUTE format('ALTER TABLE public.%I ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1', entity_table);
+    END LOOP;
+END $$;
@@ -0,0 +1,4 @@
+-- Rollback migration for user sessions
+
+ALTER TABLE public.users DROP COLUMN IF EXISTS sessions_revoked_at;
+DROP TABLE IF EXISTS public.user_sessions;
@@ -0,0 +1,33 @@
+-- Migration to add user sessions: the devices and API keys users are signed in with, and the
+-- revocation list checked on every request so that signed out sessions cannot be used again
+
+CREATE TABLE public.user_sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('clerk', 'api_key')),
+    external_id VARCHAR(255) NOT NULL,
+    device_name VARCHAR(255),
+    user_agent VARCHAR(500),
+    ip_address VARCHAR(45),
+    issued_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    revoked_reason VARCHAR(30) CHECK (revoked_reason IN ('user', 'sign_out_all', 'password_change', 'two_factor_change')),
+    version INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_user_sessions_user FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE public.user_sessions IS 'Devices and API keys users are signed in with; revoked sessions are refused';
+COMMENT ON COLUMN public.user_sessions.external_id IS 'The Clerk session ID, or the ID of the API key';
+
+CREATE UNIQUE INDEX idx_user_sessions_external ON public.user_sessions(kind, external_id) WHERE deleted_at IS NULL;
+CREATE INDEX idx_user_sessions_user ON public.user_sessions(user_id, last_seen_at DESC) WHERE deleted_at IS NULL;
+
+ALTER TABLE public.users ADD COLUMN sessions_revoked_at TIMESTAMP WITH TIME ZONE;
+COMMENT ON COLUMN public.users.sessions_revoked_at IS 'When the user signed out every device; sessions issued before then are refused';
@@ -0,0 +1,54 @@
+-- Rollback migration for money minor units
+
+DO $$
+DECLARE
+    money_table TEXT;
+    minor_column TEXT;
+BEGIN
+    FOR money_table IN
+        SELECT event_object_table FROM information_schema.triggers
+        WHERE trigger_schema = 'public' AND trigger_name = 'sync_money_minor_units'
+        GROUP BY event_object_table
+    LOOP
+        EXECUTE format('DROP TRIGGER IF EXISTS sync_money_minor_units ON public.%I', money_table);
+    END LOOP;
+
+    FOR money_table, minor_column IN
+        SELECT c.table_name, c.column_name
+        FROM (VALUES
+            ('appointments', 'estimated_price'),
+            ('appointments', 'total_price'),
+            ('appointments', 'deposit_paid'),
+            ('appointment_services', 'price'),
+            ('calendar_entries', 'price'),
+            ('campaign_clients', 'conversion_value'),
+            ('clients', 'total_spent'),
+            ('client_loyalty_memberships', 'total_spent'),
+            ('client_memberships', 'price'),
+            ('gift_cards', 'initial_value'),
+            ('gift_cards', 'balance'),
+            ('gift_card_transactions', 'amount'),
+            ('gift_card_transactions', 'balance_after'),
+            ('invoices', 'total'),
+            ('invoices', 'amount_paid'),
+            ('membership_credit_notes', 'fee'),
+            ('membership_credit_notes', 'amount'),
+            ('membership_invoices', 'amount'),
+            ('membership_plans', 'price'),
+            ('payments', 'amount'),
+            ('payments', 'refunded_amount'),
+            ('service_bundles', 'price'),
+            ('service_completions', 'price_charged'),
+            ('service_price_history', 'old_price'),
+            ('service_price_history', 'new_price'),
+            ('services', 'price'),
+            ('services', 'deposit_amount')
+        ) AS m(table_name, column_name)
+        JOIN information_schema.columns c
+            ON c.table_schema = 'public' AND c.table_name = m.table_name AND c.column_name = m.column_name || '_minor'
+    LOOP
+        EXECUTE format('ALTER TABLE public.%I DROP COLUMN IF EXISTS %I', money_table, minor_column);
+    END LOOP;
+END $$;
+
+DROP FUNCTION IF EXISTS public.sync_money_minor_units();
@@ -0,0 +1,74 @@
+-- Migration to add integer minor unit twins of the monetary columns, the first step of moving
+-- money off decimals without downtime. The new columns are nullable so adding them does not
+-- rewrite the tables; a trigger fills them on every write, and rows written before are filled
+-- in batches by "api money-migrate backfill".
+
+CREATE OR REPLACE FUNCTION public.sync_money_minor_units()
+RETURNS TRIGGER AS $$
+DECLARE
+    money_column TEXT;
+    minor_units JSONB := '{}'::JSONB;
+BEGIN
+    -- The trigger arguments name the monetary columns of the table
+    FOREACH money_column IN ARRAY TG_ARGV LOOP
+        minor_units := minor_units || jsonb_build_object(
+            money_column || '_minor',
+            round((to_jsonb(NEW) ->> money_column)::NUMERIC * 100)::BIGINT
+        );
+    END LOOP;
+    NEW := jsonb_populate_record(NEW, minor_units);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DO $$
+DECLARE
+    money_table TEXT;
+    money_columns TEXT[];
+    money_column TEXT;
+BEGIN
+    FOR money_table, money_columns IN
+        SELECT c.table_name, array_agg(c.column_name::TEXT ORDER BY c.column_name)
+        FROM (VALUES
+            ('appointments', 'estimated_price'),
+            ('appointments', 'total_price'),
+            ('appointments', 'deposit_paid'),
+            ('appointment_services', 'price'),
+            ('calendar_entries', 'price'),
+            ('campaign_clients', 'conversion_value'),
+            ('clients', 'total_spent'),
+            ('client_loyalty_memberships', 'total_spent'),
+            ('client_memberships', 'price'),
+            ('gift_cards', 'initial_value'),
+            ('gift_cards', 'balance'),
+            ('gift_card_transactions', 'amount'),
+            ('gift_card_transactions', 'balance_after'),
+            ('invoices', 'total'),
+            ('invoices', 'amount_paid'),
+            ('membership_credit_notes', 'fee'),
+            ('membership_credit_notes', 'amount'),
+            ('membership_invoices', 'amount'),
+            ('membership_plans', 'price'),
+            ('payments', 'amount'),
+            ('payments', 'refunded_amount'),
+            ('service_bundles', 'price'),
+            ('service_completions', 'price_charged'),
+            ('service_price_history', 'old_price'),
+            ('service_price_history', 'new_price'),
+            ('services', 'price'),
+            ('services', 'deposit_amount')
+        ) AS m(table_name, column_name)
+        JOIN information_schema.columns c
+            ON c.table_schema = 'public' AND c.table_name = m.table_name AND c.column_name = m.column_name
+        GROUP BY c.table_name
+    LOOP
+        FOREACH money_column IN ARRAY money_columns LOOP
+            EXECUTE format('ALTER TABLE public.%I ADD COLUMN IF NOT EXISTS %I BIGINT', money_table, money_column || '_minor');
+        END LOOP;
+        EXECUTE format(
+            'CREATE TRIGGER sync_money_minor_units BEFORE INSERT OR UPDATE ON public.%I FOR EACH ROW EXECUTE FUNCTION public.sync_money_minor_units(%s)',
+            money_table,
+            (SELECT string_agg(quote_literal(name), ', ') FROM unnest(money_columns) AS name)
+        );
+    END LOOP;
+END $$;
@@ -0,0 +1,3 @@
+-- Rollback migration for business templates
+
+ALTER TABLE public.businesses DROP COLUMN IF EXISTS is_template;
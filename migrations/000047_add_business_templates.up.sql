@@ -0,0 +1,7 @@
+-- Migration to add template businesses: businesses kept to clone new locations from, with their
+-- service menu, settings and message templates, which clients cannot book
+
+ALTER TABLE public.businesses
+    ADD COLUMN is_template BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN public.businesses.is_template IS 'Whether the business is a template new businesses are cloned from';
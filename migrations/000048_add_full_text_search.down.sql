@@ -0,0 +1,13 @@
+-- Rollback migration for full-text search of clients and services
+
+DROP INDEX IF EXISTS public.idx_services_search_vector;
+DROP INDEX IF EXISTS public.idx_clients_search_phone;
+DROP INDEX IF EXISTS public.idx_clients_search_name;
+DROP INDEX IF EXISTS public.idx_clients_search_vector;
+
+ALTER TABLE public.services DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE public.clients DROP COLUMN IF EXISTS search_vector;
+
+DROP FUNCTION IF EXISTS public.search_normalize(TEXT);
+
+-- The extensions are left installed, as other objects may have come to depend on them
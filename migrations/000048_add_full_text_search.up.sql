@@ -0,0 +1,36 @@
+-- Migration to add full-text search of clients and services: search vectors kept up to date by
+-- the database, trigram matching of client names to tolerate typos, and accent-insensitive
+-- matching so that Conceição is found by conceicao
+
+CREATE EXTENSION IF NOT EXISTS unaccent SCHEMA public;
+CREATE EXTENSION IF NOT EXISTS pg_trgm SCHEMA public;
+
+-- unaccent is only stable, as its dictionary could change, so generated columns and indexes
+-- need an immutable wrapper naming the dictionary
+CREATE OR REPLACE FUNCTION public.search_normalize(value TEXT) RETURNS TEXT
+    LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT
+AS $$ SELECT lower(public.unaccent('public.unaccent'::regdictionary, value)) $$;
+
+-- The simple configuration neither stems nor drops stop words, which suits names in any language
+ALTER TABLE public.clients
+    ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', public.search_normalize(coalesce(first_name, '') || ' ' || coalesce(last_name, ''))), 'A') ||
+        setweight(to_tsvector('simple', regexp_replace(public.search_normalize(coalesce(email, '')), '[^[:alnum:]]+', ' ', 'g')), 'B') ||
+        setweight(to_tsvector('simple', public.search_normalize(coalesce(notes, ''))), 'C')
+    ) STORED;
+
+ALTER TABLE public.services
+    ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple', public.search_normalize(coalesce(name, ''))), 'A') ||
+        setweight(to_tsvector('simple', public.search_normalize(coalesce(description, ''))), 'C')
+    ) STORED;
+
+CREATE INDEX idx_clients_search_vector ON public.clients USING GIN (search_vector);
+CREATE INDEX idx_clients_search_name ON public.clients
+    USING GIN (public.search_normalize(first_name || ' ' || last_name) public.gin_trgm_ops);
+CREATE INDEX idx_clients_search_phone ON public.clients
+    USING GIN (regexp_replace(coalesce(phone, ''), '[^0-9]', '', 'g') public.gin_trgm_ops);
+CREATE INDEX idx_services_search_vector ON public.services USING GIN (search_vector);
+
+COMMENT ON COLUMN public.clients.search_vector IS 'Name, email and notes for full-text search, without accents';
+COMMENT ON COLUMN public.services.search_vector IS 'Name and description for full-text search, without accents';
@@ -0,0 +1,3 @@
+-- Rollback migration for VIP rules
+
+ALTER TABLE public.business_settings DROP COLUMN IF EXISTS vip_rules;
@@ -0,0 +1,7 @@
+-- Migration to add VIP rules: the spend, visit and loyalty point thresholds over which clients of
+-- a business are tagged as VIPs
+
+ALTER TABLE public.business_settings
+    ADD COLUMN vip_rules JSONB;
+
+COMMENT ON COLUMN public.business_settings.vip_rules IS 'VIP thresholds as JSON; clients are not tagged as VIPs when null or disabled';
@@ -0,0 +1,3 @@
+-- Rollback migration for appointment quotes
+
+DROP TABLE IF EXISTS public.appointment_quotes;
@@ -0,0 +1,41 @@
+-- Migration to add appointment quotes: priced appointments that have not been booked. Booking
+-- with a quote's token before it expires honors the quoted total.
+
+CREATE TABLE public.appointment_quotes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID NOT NULL,
+    client_id UUID NOT NULL,
+    staff_id UUID NOT NULL,
+    start_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    token VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    total DECIMAL(10,2) NOT NULL,
+    total_minor BIGINT,
+    deposit_due DECIMAL(10,2) NOT NULL,
+    deposit_due_minor BIGINT,
+    breakdown JSONB NOT NULL,
+    appointment_id UUID, -- The appointment booked with the quote
+    version INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_appointment_quotes_business FOREIGN KEY (business_id) REFERENCES public.businesses(id),
+    CONSTRAINT fk_appointment_quotes_client FOREIGN KEY (client_id) REFERENCES public.clients(id) ON DELETE CASCADE,
+    CONSTRAINT fk_appointment_quotes_staff FOREIGN KEY (staff_id) REFERENCES public.staff(id),
+    CONSTRAINT fk_appointment_quotes_appointment FOREIGN KEY (appointment_id) REFERENCES public.appointments(id) ON DELETE SET NULL,
+    CONSTRAINT fk_appointment_quotes_created_by FOREIGN KEY (created_by) REFERENCES public.users(id),
+    CONSTRAINT fk_appointment_quotes_updated_by FOREIGN KEY (updated_by) REFERENCES public.users(id),
+    CONSTRAINT fk_appointment_quotes_deleted_by FOREIGN KEY (deleted_by) REFERENCES public.users(id)
+);
+
+CREATE UNIQUE INDEX idx_appointment_quotes_token ON public.appointment_quotes(token);
+CREATE INDEX idx_appointment_quotes_business ON public.appointment_quotes(business_id);
+CREATE INDEX idx_appointment_quotes_client ON public.appointment_quotes(client_id);
+
+-- The quoted amounts have minor unit twins like every monetary column (see 000046)
+CREATE TRIGGER sync_money_minor_units BEFORE INSERT OR UPDATE ON public.appointment_quotes
+    FOR EACH ROW EXECUTE FUNCTION public.sync_money_minor_units('total', 'deposit_due');
@@ -0,0 +1,21 @@
+-- Rollback migration for cancellation policies
+
+DROP TRIGGER IF EXISTS sync_money_minor_units ON public.appointments;
+CREATE TRIGGER sync_money_minor_units BEFORE INSERT OR UPDATE ON public.appointments
+    FOR EACH ROW EXECUTE FUNCTION public.sync_money_minor_units('estimated_price', 'total_price', 'deposit_paid');
+
+DROP TRIGGER IF EXISTS sync_money_minor_units ON public.business_settings;
+
+ALTER TABLE public.clients
+    DROP COLUMN IF EXISTS deposit_required,
+    DROP COLUMN IF EXISTS no_show_count;
+
+ALTER TABLE public.appointments
+    DROP COLUMN IF EXISTS no_show_fee_minor,
+    DROP COLUMN IF EXISTS no_show_fee;
+
+ALTER TABLE public.business_settings
+    DROP COLUMN IF EXISTS max_no_shows,
+    DROP COLUMN IF EXISTS no_show_fee_minor,
+    DROP COLUMN IF EXISTS no_show_fee,
+    DROP COLUMN IF EXISTS cancellation_notice_hours;
@@ -0,0 +1,24 @@
+-- Migration to add per-business cancellation policies: the notice clients must give, the fee
+-- for no-shows and late cancellations, and how many no-shows flag a client as needing a deposit
+
+ALTER TABLE public.business_settings
+    ADD COLUMN cancellation_notice_hours INTEGER NOT NULL DEFAULT 0 CHECK (cancellation_notice_hours BETWEEN 0 AND 168),
+    ADD COLUMN no_show_fee DECIMAL(10,2) CHECK (no_show_fee > 0),
+    ADD COLUMN no_show_fee_minor BIGINT,
+    ADD COLUMN max_no_shows INTEGER NOT NULL DEFAULT 0 CHECK (max_no_shows BETWEEN 0 AND 20);
+
+ALTER TABLE public.appointments
+    ADD COLUMN no_show_fee DECIMAL(10,2),
+    ADD COLUMN no_show_fee_minor BIGINT;
+
+ALTER TABLE public.clients
+    ADD COLUMN no_show_count INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN deposit_required BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- The fees have minor unit twins like every monetary column (see 000046)
+CREATE TRIGGER sync_money_minor_units BEFORE INSERT OR UPDATE ON public.business_settings
+    FOR EACH ROW EXECUTE FUNCTION public.sync_money_minor_units('no_show_fee');
+
+DROP TRIGGER IF EXISTS sync_money_minor_units ON public.appointments;
+CREATE TRIGGER sync_money_minor_units BEFORE INSERT OR UPDATE ON public.appointments
+    FOR EACH ROW EXECUTE FUNCTION public.sync_money_minor_units('estimated_price', 'total_price', 'deposit_paid', 'no_show_fee');
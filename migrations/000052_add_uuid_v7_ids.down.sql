@@ -0,0 +1,17 @@
+-- Rollback migration for UUIDv7 IDs; IDs generated meanwhile stay valid UUIDs
+
+ALTER TABLE public.appointments ALTER COLUMN id SET DEFAULT gen_random_uuid();
+ALTER TABLE public.appointment_services ALTER COLUMN id SET DEFAULT gen_random_uuid();
+ALTER TABLE public.payments ALTER COLUMN id SET DEFAULT gen_random_uuid();
+ALTER TABLE public.gift_card_transactions ALTER COLUMN id SET DEFAULT gen_random_uuid();
+ALTER TABLE public.loyalty_transactions ALTER COLUMN id SET DEFAULT gen_random_uuid();
+ALTER TABLE public.inventory_transactions ALTER COLUMN id SET DEFAULT gen_random_uuid();
+ALTER TABLE public.campaign_messages ALTER COLUMN id SET DEFAULT gen_random_uuid();
+ALTER TABLE public.notification_queue ALTER COLUMN id SET DEFAULT gen_random_uuid();
+ALTER TABLE public.broadcast_recipients ALTER COLUMN id SET DEFAULT gen_random_uuid();
+ALTER TABLE public.webhook_deliveries ALTER COLUMN id SET DEFAULT gen_random_uuid();
+ALTER TABLE public.domain_events ALTER COLUMN id SET DEFAULT gen_random_uuid();
+ALTER TABLE public.audit_logs ALTER COLUMN id SET DEFAULT gen_random_uuid();
+ALTER TABLE public.jobs ALTER COLUMN id SET DEFAULT gen_random_uuid();
+
+DROP FUNCTION IF EXISTS public.uuid_generate_v7();
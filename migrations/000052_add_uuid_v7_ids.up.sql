@@ -0,0 +1,31 @@
+-- Migration to generate time-ordered UUIDv7 IDs on the tables appended to the most, so rows
+-- inserted together sit together in their primary key index. The application generates the
+-- IDs itself; the defaults cover rows inserted in SQL. Existing UUIDv4 IDs are left as they are.
+
+-- A UUIDv7: the milliseconds since the epoch in the first 48 bits and version 7 in place of the
+-- version 4 of a random UUID, whose random bits and variant are kept
+CREATE OR REPLACE FUNCTION public.uuid_generate_v7() RETURNS UUID
+    LANGUAGE sql VOLATILE PARALLEL SAFE
+AS $$
+    SELECT encode(
+        set_bit(set_bit(
+            overlay(uuid_send(gen_random_uuid())
+                PLACING substring(int8send(floor(extract(epoch FROM clock_timestamp()) * 1000)::BIGINT) FROM 3)
+                FROM 1 FOR 6),
+            52, 1), 53, 1),
+        'hex')::UUID
+$$;
+
+ALTER TABLE public.appointments ALTER COLUMN id SET DEFAULT public.uuid_generate_v7();
+ALTER TABLE public.appointment_services ALTER COLUMN id SET DEFAULT public.uuid_generate_v7();
+ALTER TABLE public.payments ALTER COLUMN id SET DEFAULT public.uuid_generate_v7();
+ALTER TABLE public.gift_card_transactions ALTER COLUMN id SET DEFAULT public.uuid_generate_v7();
+ALTER TABLE public.loyalty_transactions ALTER COLUMN id SET DEFAULT public.uuid_generate_v7();
+ALTER TABLE public.inventory_transactions ALTER COLUMN id SET DEFAULT public.uuid_generate_v7();
+ALTER TABLE public.campaign_messages ALTER COLUMN id SET DEFAULT public.uuid_generate_v7();
+ALTER TABLE public.notification_queue ALTER COLUMN id SET DEFAULT public.uuid_generate_v7();
+ALTER TABLE public.broadcast_recipients ALTER COLUMN id SET DEFAULT public.uuid_generate_v7();
+ALTER TABLE public.webhook_deliveries ALTER COLUMN id SET DEFAULT public.uuid_generate_v7();
+ALTER TABLE public.domain_events ALTER COLUMN id SET DEFAULT public.uuid_generate_v7();
+ALTER TABLE public.audit_logs ALTER COLUMN id SET DEFAULT public.uuid_generate_v7();
+ALTER TABLE public.jobs ALTER COLUMN id SET DEFAULT public.uuid_generate_v7();
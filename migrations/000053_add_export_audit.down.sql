@@ -0,0 +1,5 @@
+-- Rollback migration for recording data exports in the audit log
+
+DELETE FROM public.audit_logs WHERE action = 'export';
+ALTER TABLE public.audit_logs DROP CONSTRAINT IF EXISTS audit_logs_action_check;
+ALTER TABLE public.audit_logs ADD CONSTRAINT audit_logs_action_check CHECK (action IN ('create', 'update', 'delete', 'restore'));
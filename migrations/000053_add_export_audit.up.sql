@@ -0,0 +1,5 @@
+-- Migration to record data exports in the audit log, with how each export was encrypted and
+-- the fingerprint of the key it was encrypted for
+
+ALTER TABLE public.audit_logs DROP CONSTRAINT IF EXISTS audit_logs_action_check;
+ALTER TABLE public.audit_logs ADD CONSTRAINT audit_logs_action_check CHECK (action IN ('create', 'update', 'delete', 'restore', 'export'));
@@ -0,0 +1,3 @@
+-- Rollback migration for staff shifts
+
+DROP TABLE IF EXISTS public.staff_shifts;
@@ -0,0 +1,37 @@
+-- Migration to add staff shifts: the weekly pattern staff members work and one-off shifts on top
+-- of it. Staff members with shifts can only be booked during them.
+
+CREATE TABLE public.staff_shifts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID NOT NULL,
+    staff_id UUID NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    weekday SMALLINT, -- Recurring shifts: 0 for Sunday to 6 for Saturday
+    shift_date DATE, -- One-off shifts
+    start_time VARCHAR(5) NOT NULL, -- HH:MM in the business's time zone
+    end_time VARCHAR(5) NOT NULL, -- HH:MM; 24:00 for midnight
+    effective_from DATE,
+    effective_until DATE,
+    notes TEXT,
+    version INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_staff_shifts_business FOREIGN KEY (business_id) REFERENCES public.businesses(id),
+    CONSTRAINT fk_staff_shifts_staff FOREIGN KEY (staff_id) REFERENCES public.staff(id) ON DELETE CASCADE,
+    CONSTRAINT fk_staff_shifts_created_by FOREIGN KEY (created_by) REFERENCES public.users(id),
+    CONSTRAINT fk_staff_shifts_updated_by FOREIGN KEY (updated_by) REFERENCES public.users(id),
+    CONSTRAINT fk_staff_shifts_deleted_by FOREIGN KEY (deleted_by) REFERENCES public.users(id),
+    CONSTRAINT chk_staff_shifts_kind CHECK (
+        (kind = 'recurring' AND weekday BETWEEN 0 AND 6 AND shift_date IS NULL) OR
+        (kind = 'one_off' AND shift_date IS NOT NULL AND weekday IS NULL AND effective_from IS NULL AND effective_until IS NULL)
+    ),
+    CONSTRAINT chk_staff_shifts_times CHECK (start_time ~ '^([01][0-9]|2[0-3]):[0-5][0-9]$' AND end_time ~ '^(([01][0-9]|2[0-3]):[0-5][0-9]|24:00)$'),
+    CONSTRAINT chk_staff_shifts_effective CHECK (effective_until IS NULL OR effective_from IS NULL OR effective_until >= effective_from)
+);
+
+CREATE INDEX idx_staff_shifts_business ON public.staff_shifts(business_id);
+CREATE INDEX idx_staff_shifts_staff ON public.staff_shifts(staff_id) WHERE deleted_at IS NULL;
@@ -0,0 +1,3 @@
+-- Rollback migration for time entries
+
+DROP TABLE IF EXISTS public.time_entries;
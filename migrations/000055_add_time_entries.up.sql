@@ -0,0 +1,33 @@
+-- Migration to add time entries: staff members clocking in and out, flagged against their
+-- shifts and totalled per pay period for payroll.
+
+CREATE TABLE public.time_entries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID NOT NULL,
+    staff_id UUID NOT NULL,
+    clock_in TIMESTAMP WITH TIME ZONE NOT NULL,
+    clock_out TIMESTAMP WITH TIME ZONE, -- Still clocked in when NULL
+    flags JSONB NOT NULL DEFAULT '[]', -- outside_shift, no_shift, overlong
+    notes TEXT,
+    adjusted_by UUID, -- The manager who last corrected the times
+    version INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_time_entries_business FOREIGN KEY (business_id) REFERENCES public.businesses(id),
+    CONSTRAINT fk_time_entries_staff FOREIGN KEY (staff_id) REFERENCES public.staff(id) ON DELETE CASCADE,
+    CONSTRAINT fk_time_entries_adjusted_by FOREIGN KEY (adjusted_by) REFERENCES public.users(id),
+    CONSTRAINT fk_time_entries_created_by FOREIGN KEY (created_by) REFERENCES public.users(id),
+    CONSTRAINT fk_time_entries_updated_by FOREIGN KEY (updated_by) REFERENCES public.users(id),
+    CONSTRAINT fk_time_entries_deleted_by FOREIGN KEY (deleted_by) REFERENCES public.users(id),
+    CONSTRAINT chk_time_entries_clock_out CHECK (clock_out IS NULL OR clock_out > clock_in)
+);
+
+CREATE INDEX idx_time_entries_business_clock_in ON public.time_entries(business_id, clock_in);
+CREATE INDEX idx_time_entries_staff_clock_in ON public.time_entries(staff_id, clock_in);
+
+-- A staff member is clocked in on at most one entry at a time
+CREATE UNIQUE INDEX idx_time_entries_open ON public.time_entries(staff_id) WHERE clock_out IS NULL AND deleted_at IS NULL;
@@ -0,0 +1,6 @@
+-- Rollback migration for staff performance aggregation
+
+DROP INDEX IF EXISTS public.idx_staff_performance_business_period;
+
+DELETE FROM public.staff_performance WHERE created_by IS NULL;
+ALTER TABLE public.staff_performance ALTER COLUMN created_by SET NOT NULL;
@@ -0,0 +1,7 @@
+-- Migration for aggregating staff performance from appointments, completions and ratings
+
+-- Records are written by the aggregation job, which runs without a user
+ALTER TABLE public.staff_performance ALTER COLUMN created_by DROP NOT NULL;
+
+-- Periods are listed for a business by their start
+CREATE INDEX idx_staff_performance_business_period ON public.staff_performance(business_id, period, start_date);
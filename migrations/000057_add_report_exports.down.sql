@@ -0,0 +1,3 @@
+-- Rollback migration for report exports
+
+DROP TABLE IF EXISTS public.report_exports;
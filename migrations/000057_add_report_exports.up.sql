@@ -0,0 +1,38 @@
+-- Migration to add report exports: reports rendered to CSV or PDF by a background job, kept in
+-- object storage and downloaded through signed URLs until they expire
+
+CREATE TABLE public.report_exports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID NOT NULL,
+    report VARCHAR(20) NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    start_date VARCHAR(10), -- YYYY-MM-DD in the business's time zone; NULL for the client list
+    end_date VARCHAR(10),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    file_name VARCHAR(255) NOT NULL,
+    job_id UUID,
+    bucket VARCHAR(255),
+    object_key VARCHAR(500),
+    row_count INTEGER,
+    size_bytes BIGINT,
+    error TEXT,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE, -- The file is removed from storage then
+    version INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_report_exports_business FOREIGN KEY (business_id) REFERENCES public.businesses(id),
+    CONSTRAINT fk_report_exports_job FOREIGN KEY (job_id) REFERENCES public.jobs(id) ON DELETE SET NULL,
+    CONSTRAINT fk_report_exports_created_by FOREIGN KEY (created_by) REFERENCES public.users(id),
+    CONSTRAINT fk_report_exports_updated_by FOREIGN KEY (updated_by) REFERENCES public.users(id),
+    CONSTRAINT fk_report_exports_deleted_by FOREIGN KEY (deleted_by) REFERENCES public.users(id),
+    CONSTRAINT chk_report_exports_report CHECK (report IN ('revenue', 'commissions', 'clients', 'appointments')),
+    CONSTRAINT chk_report_exports_format CHECK (format IN ('csv', 'pdf')),
+    CONSTRAINT chk_report_exports_status CHECK (status IN ('pending', 'ready', 'failed', 'expired'))
+);
+
+CREATE INDEX idx_report_exports_business_created ON public.report_exports(business_id, created_at DESC);
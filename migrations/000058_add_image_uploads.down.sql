@@ -0,0 +1,3 @@
+-- Rollback migration for image uploads
+
+DROP TABLE IF EXISTS public.image_uploads;
@@ -0,0 +1,41 @@
+-- Migration to add image uploads: logos, cover photos and staff photos uploaded straight to
+-- object storage through presigned URLs, then validated, scaled down and served publicly. Files
+-- no longer shown anywhere are removed by a periodic cleanup.
+
+CREATE TABLE public.image_uploads (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID NOT NULL,
+    purpose VARCHAR(20) NOT NULL,
+    target_id UUID NOT NULL, -- The business or staff member shown the image
+    content_type VARCHAR(50) NOT NULL, -- As declared for the upload
+    size_bytes BIGINT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    bucket VARCHAR(255) NOT NULL,
+    upload_key VARCHAR(500) NOT NULL,
+    upload_expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    object_key VARCHAR(500),
+    url VARCHAR(255), -- Set on the target; the image is removed once the target no longer shows it
+    width INTEGER,
+    height INTEGER,
+    error TEXT,
+    attached_at TIMESTAMP WITH TIME ZONE,
+    removed_at TIMESTAMP WITH TIME ZONE,
+    version INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_image_uploads_business FOREIGN KEY (business_id) REFERENCES public.businesses(id),
+    CONSTRAINT fk_image_uploads_created_by FOREIGN KEY (created_by) REFERENCES public.users(id),
+    CONSTRAINT fk_image_uploads_updated_by FOREIGN KEY (updated_by) REFERENCES public.users(id),
+    CONSTRAINT fk_image_uploads_deleted_by FOREIGN KEY (deleted_by) REFERENCES public.users(id),
+    CONSTRAINT chk_image_uploads_purpose CHECK (purpose IN ('business_logo', 'business_cover', 'staff_photo')),
+    CONSTRAINT chk_image_uploads_status CHECK (status IN ('pending', 'attached', 'rejected', 'removed')),
+    CONSTRAINT chk_image_uploads_size CHECK (size_bytes > 0)
+);
+
+-- The cleanup only looks at uploads whose files are still in storage
+CREATE INDEX idx_image_uploads_cleanup ON public.image_uploads(created_at)
+    WHERE status <> 'removed' AND deleted_at IS NULL;
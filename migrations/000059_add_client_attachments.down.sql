@@ -0,0 +1,5 @@
+-- Rollback migration for client attachments
+
+DROP TABLE IF EXISTS public.client_attachments;
+ALTER TABLE public.businesses DROP CONSTRAINT IF EXISTS chk_businesses_attachment_quota;
+ALTER TABLE public.businesses DROP COLUMN IF EXISTS attachment_quota_mb;
@@ -0,0 +1,58 @@
+-- Migration to add client attachments: before/after photos and documents attached to a client or
+-- one of their appointments, uploaded straight to object storage and served through signed URLs.
+-- Attachments count against a storage quota per business, and photos may only be used in
+-- marketing once the client's consent is recorded.
+
+ALTER TABLE public.businesses ADD COLUMN attachment_quota_mb INTEGER; -- The default quota when NULL
+ALTER TABLE public.businesses ADD CONSTRAINT chk_businesses_attachment_quota CHECK (attachment_quota_mb >= 0);
+
+CREATE TABLE public.client_attachments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID NOT NULL,
+    client_id UUID NOT NULL,
+    appointment_id UUID,
+    kind VARCHAR(20) NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    caption VARCHAR(500),
+    content_type VARCHAR(50) NOT NULL,
+    size_bytes BIGINT NOT NULL, -- As declared until stored, then as kept
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    bucket VARCHAR(255) NOT NULL,
+    upload_key VARCHAR(500) NOT NULL,
+    upload_expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    object_key VARCHAR(500),
+    width INTEGER,
+    height INTEGER,
+    error TEXT,
+    stored_at TIMESTAMP WITH TIME ZONE,
+    marketing_consent_at TIMESTAMP WITH TIME ZONE, -- When the client agreed to the photo's use in marketing
+    marketing_consent_by UUID,
+    removed_at TIMESTAMP WITH TIME ZONE,
+    version INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_client_attachments_business FOREIGN KEY (business_id) REFERENCES public.businesses(id),
+    CONSTRAINT fk_client_attachments_client FOREIGN KEY (client_id) REFERENCES public.clients(id),
+    CONSTRAINT fk_client_attachments_appointment FOREIGN KEY (appointment_id) REFERENCES public.appointments(id),
+    CONSTRAINT fk_client_attachments_consent_by FOREIGN KEY (marketing_consent_by) REFERENCES public.users(id),
+    CONSTRAINT fk_client_attachments_created_by FOREIGN KEY (created_by) REFERENCES public.users(id),
+    CONSTRAINT fk_client_attachments_updated_by FOREIGN KEY (updated_by) REFERENCES public.users(id),
+    CONSTRAINT fk_client_attachments_deleted_by FOREIGN KEY (deleted_by) REFERENCES public.users(id),
+    CONSTRAINT chk_client_attachments_kind CHECK (kind IN ('before_photo', 'after_photo', 'photo', 'document')),
+    CONSTRAINT chk_client_attachments_status CHECK (status IN ('pending', 'stored', 'rejected', 'deleted', 'removed')),
+    CONSTRAINT chk_client_attachments_size CHECK (size_bytes > 0),
+    CONSTRAINT chk_client_attachments_consent CHECK (marketing_consent_at IS NULL OR (kind <> 'document' AND status = 'stored'))
+);
+
+CREATE INDEX idx_client_attachments_client ON public.client_attachments(client_id, created_at)
+    WHERE status = 'stored' AND deleted_at IS NULL;
+CREATE INDEX idx_client_attachments_marketing ON public.client_attachments(business_id, created_at)
+    WHERE marketing_consent_at IS NOT NULL AND status = 'stored' AND deleted_at IS NULL;
+
+-- The cleanup only looks at attachments whose files are no longer kept
+CREATE INDEX idx_client_attachments_cleanup ON public.client_attachments(created_at)
+    WHERE status IN ('pending', 'rejected', 'deleted') AND deleted_at IS NULL;
@@ -0,0 +1,51 @@
+-- Rollback migration for reviews
+
+ALTER TABLE public.business_settings DROP CONSTRAINT IF EXISTS chk_business_settings_review_request_delay;
+ALTER TABLE public.business_settings DROP COLUMN IF EXISTS review_request_delay_hours;
+ALTER TABLE public.businesses DROP COLUMN IF EXISTS review_count;
+ALTER TABLE public.businesses DROP COLUMN IF EXISTS average_rating;
+ALTER TABLE public.staff DROP COLUMN IF EXISTS review_count;
+ALTER TABLE public.staff DROP COLUMN IF EXISTS average_rating;
+
+DROP TABLE IF EXISTS public.review_requests;
+
+DROP INDEX IF EXISTS public.idx_reviews_published_staff;
+DROP INDEX IF EXISTS public.idx_reviews_business;
+ALTER TABLE public.reviews
+    DROP CONSTRAINT IF EXISTS chk_reviews_status,
+    DROP CONSTRAINT IF EXISTS fk_reviews_replied_by,
+    DROP CONSTRAINT IF EXISTS fk_reviews_moderated_by,
+    DROP CONSTRAINT IF EXISTS fk_reviews_business,
+    DROP COLUMN IF EXISTS replied_by,
+    DROP COLUMN IF EXISTS replied_at,
+    DROP COLUMN IF EXISTS reply,
+    DROP COLUMN IF EXISTS moderation_note,
+    DROP COLUMN IF EXISTS moderated_by,
+    DROP COLUMN IF EXISTS moderated_at;
+
+-- Pending reviews were never published
+ALTER TABLE public.reviews ADD COLUMN is_published BOOLEAN NOT NULL DEFAULT TRUE;
+UPDATE public.reviews SET is_published = (status = 'published');
+ALTER TABLE public.reviews DROP COLUMN status;
+ALTER TABLE public.reviews DROP COLUMN business_id;
+
+-- Ratings left through a link have no author to restore
+DELETE FROM public.reviews WHERE created_by IS NULL;
+ALTER TABLE public.reviews ALTER COLUMN created_by SET NOT NULL;
+
+CREATE INDEX idx_service_ratings_rating ON public.reviews(rating);
+ALTER INDEX public.idx_reviews_service_id RENAME TO idx_service_ratings_service_id;
+ALTER INDEX public.idx_reviews_staff_id RENAME TO idx_service_ratings_staff_id;
+ALTER INDEX public.idx_reviews_client_id RENAME TO idx_service_ratings_client_id;
+ALTER INDEX public.idx_reviews_appointment_id RENAME TO idx_service_ratings_appointment_id;
+ALTER TABLE public.reviews RENAME CONSTRAINT chk_reviews_rating TO chk_service_rating_range;
+ALTER TABLE public.reviews RENAME CONSTRAINT uq_reviews_appointment TO uq_rating_appointment;
+ALTER TABLE public.reviews RENAME CONSTRAINT fk_reviews_deleted_by TO fk_service_ratings_deleted_by;
+ALTER TABLE public.reviews RENAME CONSTRAINT fk_reviews_updated_by TO fk_service_ratings_updated_by;
+ALTER TABLE public.reviews RENAME CONSTRAINT fk_reviews_created_by TO fk_service_ratings_created_by;
+ALTER TABLE public.reviews RENAME CONSTRAINT fk_reviews_service TO fk_service_ratings_service;
+ALTER TABLE public.reviews RENAME CONSTRAINT fk_reviews_staff TO fk_service_ratings_staff;
+ALTER TABLE public.reviews RENAME CONSTRAINT fk_reviews_client TO fk_service_ratings_client;
+ALTER TABLE public.reviews RENAME CONSTRAINT fk_reviews_appointment TO fk_service_ratings_appointment;
+ALTER TABLE public.reviews RENAME COLUMN comment TO feedback;
+ALTER TABLE public.reviews RENAME TO service_ratings;
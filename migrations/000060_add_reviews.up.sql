@@ -0,0 +1,101 @@
+-- Migration to turn service ratings into reviews: clients rate a completed appointment from 1 to
+-- 5 stars through a link sent after their visit, optionally with a comment the business
+-- moderates and may reply to. Published reviews are averaged into the ratings of the staff
+-- member and the business.
+
+ALTER TABLE public.service_ratings RENAME TO reviews;
+ALTER TABLE public.reviews RENAME COLUMN feedback TO comment;
+ALTER TABLE public.reviews RENAME CONSTRAINT fk_service_ratings_appointment TO fk_reviews_appointment;
+ALTER TABLE public.reviews RENAME CONSTRAINT fk_service_ratings_client TO fk_reviews_client;
+ALTER TABLE public.reviews RENAME CONSTRAINT fk_service_ratings_staff TO fk_reviews_staff;
+ALTER TABLE public.reviews RENAME CONSTRAINT fk_service_ratings_service TO fk_reviews_service;
+ALTER TABLE public.reviews RENAME CONSTRAINT fk_service_ratings_created_by TO fk_reviews_created_by;
+ALTER TABLE public.reviews RENAME CONSTRAINT fk_service_ratings_updated_by TO fk_reviews_updated_by;
+ALTER TABLE public.reviews RENAME CONSTRAINT fk_service_ratings_deleted_by TO fk_reviews_deleted_by;
+ALTER TABLE public.reviews RENAME CONSTRAINT uq_rating_appointment TO uq_reviews_appointment;
+ALTER TABLE public.reviews RENAME CONSTRAINT chk_service_rating_range TO chk_reviews_rating;
+ALTER INDEX public.idx_service_ratings_appointment_id RENAME TO idx_reviews_appointment_id;
+ALTER INDEX public.idx_service_ratings_client_id RENAME TO idx_reviews_client_id;
+ALTER INDEX public.idx_service_ratings_staff_id RENAME TO idx_reviews_staff_id;
+ALTER INDEX public.idx_service_ratings_service_id RENAME TO idx_reviews_service_id;
+DROP INDEX IF EXISTS public.idx_service_ratings_rating;
+
+-- Clients leave reviews through their link, without an account
+ALTER TABLE public.reviews ALTER COLUMN created_by DROP NOT NULL;
+
+ALTER TABLE public.reviews ADD COLUMN business_id UUID;
+UPDATE public.reviews r SET business_id = a.business_id FROM public.appointments a WHERE a.id = r.appointment_id;
+ALTER TABLE public.reviews ALTER COLUMN business_id SET NOT NULL;
+
+-- Ratings recorded so far were published as given
+ALTER TABLE public.reviews ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'pending';
+UPDATE public.reviews SET status = CASE WHEN is_published THEN 'published' ELSE 'rejected' END;
+ALTER TABLE public.reviews DROP COLUMN is_published;
+
+ALTER TABLE public.reviews
+    ADD COLUMN moderated_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN moderated_by UUID,
+    ADD COLUMN moderation_note VARCHAR(500), -- Why the review was rejected; never shown to the public
+    ADD COLUMN reply TEXT,
+    ADD COLUMN replied_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN replied_by UUID,
+    ADD CONSTRAINT fk_reviews_business FOREIGN KEY (business_id) REFERENCES public.businesses(id),
+    ADD CONSTRAINT fk_reviews_moderated_by FOREIGN KEY (moderated_by) REFERENCES public.users(id),
+    ADD CONSTRAINT fk_reviews_replied_by FOREIGN KEY (replied_by) REFERENCES public.users(id),
+    ADD CONSTRAINT chk_reviews_status CHECK (status IN ('pending', 'published', 'rejected'));
+
+COMMENT ON TABLE public.reviews IS 'Client reviews of completed appointments, moderated by the business';
+
+CREATE INDEX idx_reviews_business ON public.reviews(business_id, created_at) WHERE deleted_at IS NULL;
+-- Ratings are worked out from published reviews alone
+CREATE INDEX idx_reviews_published_staff ON public.reviews(staff_id)
+    WHERE status = 'published' AND deleted_at IS NULL;
+
+CREATE TABLE public.review_requests (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID NOT NULL,
+    appointment_id UUID NOT NULL,
+    token VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    sent_at TIMESTAMP WITH TIME ZONE,
+    reviewed_at TIMESTAMP WITH TIME ZONE, -- When the link was used
+    version INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_review_requests_business FOREIGN KEY (business_id) REFERENCES public.businesses(id),
+    CONSTRAINT fk_review_requests_appointment FOREIGN KEY (appointment_id) REFERENCES public.appointments(id) ON DELETE CASCADE,
+    CONSTRAINT fk_review_requests_created_by FOREIGN KEY (created_by) REFERENCES public.users(id),
+    CONSTRAINT fk_review_requests_updated_by FOREIGN KEY (updated_by) REFERENCES public.users(id),
+    CONSTRAINT fk_review_requests_deleted_by FOREIGN KEY (deleted_by) REFERENCES public.users(id),
+    CONSTRAINT uq_review_requests_appointment UNIQUE (appointment_id),
+    CONSTRAINT uq_review_requests_token UNIQUE (token)
+);
+
+ALTER TABLE public.staff
+    ADD COLUMN average_rating DECIMAL(3,2), -- Of their published reviews; NULL without any
+    ADD COLUMN review_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE public.businesses
+    ADD COLUMN average_rating DECIMAL(3,2), -- Of its published reviews; NULL without any
+    ADD COLUMN review_count INTEGER NOT NULL DEFAULT 0;
+
+UPDATE public.staff s SET average_rating = r.average, review_count = r.count
+FROM (
+    SELECT staff_id, ROUND(AVG(rating), 2) AS average, COUNT(*) AS count FROM public.reviews
+    WHERE status = 'published' AND deleted_at IS NULL GROUP BY staff_id
+) r
+WHERE r.staff_id = s.id;
+UPDATE public.businesses b SET average_rating = r.average, review_count = r.count
+FROM (
+    SELECT business_id, ROUND(AVG(rating), 2) AS average, COUNT(*) AS count FROM public.reviews
+    WHERE status = 'published' AND deleted_at IS NULL GROUP BY business_id
+) r
+WHERE r.business_id = b.id;
+
+-- 0 disables review requests
+ALTER TABLE public.business_settings ADD COLUMN review_request_delay_hours INTEGER NOT NULL DEFAULT 2;
+ALTER TABLE public.business_settings ADD CONSTRAINT chk_business_settings_review_request_delay
+    CHECK (review_request_delay_hours BETWEEN 0 AND 168);
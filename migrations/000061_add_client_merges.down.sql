@@ -0,0 +1,7 @@
+-- Rollback migration for client merges
+
+DROP INDEX IF EXISTS idx_clients_merged_into;
+ALTER TABLE public.clients
+    DROP CONSTRAINT IF EXISTS chk_clients_merged_into_not_self,
+    DROP CONSTRAINT IF EXISTS fk_clients_merged_into,
+    DROP COLUMN IF EXISTS merged_into_id;
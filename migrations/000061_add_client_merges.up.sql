@@ -0,0 +1,11 @@
+-- Migration to record the client a duplicate was merged into
+
+ALTER TABLE public.clients
+    ADD COLUMN merged_into_id UUID,
+    ADD CONSTRAINT fk_clients_merged_into FOREIGN KEY (merged_into_id) REFERENCES public.clients(id),
+    ADD CONSTRAINT chk_clients_merged_into_not_self CHECK (merged_into_id IS NULL OR merged_into_id <> id);
+
+CREATE INDEX idx_clients_merged_into ON public.clients(merged_into_id) WHERE merged_into_id IS NOT NULL;
+
+COMMENT ON COLUMN public.clients.merged_into_id IS 'Client a duplicate was merged into; set on the deleted duplicate, whose records all moved there';
+
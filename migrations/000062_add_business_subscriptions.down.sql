@@ -0,0 +1,5 @@
+-- Rollback migration for business subscriptions
+
+DROP TABLE IF EXISTS public.sms_usage_monthly;
+DROP TABLE IF EXISTS public.business_subscriptions;
+ALTER TABLE public.businesses DROP CONSTRAINT IF EXISTS chk_businesses_subscription_tier;
@@ -0,0 +1,58 @@
+-- Migration to enforce subscription tiers: businesses subscribe to a paid tier through Stripe
+-- Billing, mirrored from Stripe's webhooks, and the text messages they send are counted against
+-- the SMS credits of their plan each month
+
+-- Tiers no longer sold are taken as the free tier
+UPDATE public.businesses SET subscription_tier = 'free' WHERE subscription_tier NOT IN ('free', 'pro', 'premium');
+ALTER TABLE public.businesses
+    ADD CONSTRAINT chk_businesses_subscription_tier CHECK (subscription_tier IN ('free', 'pro', 'premium'));
+
+CREATE TABLE public.business_subscriptions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID NOT NULL,
+    tier VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    stripe_customer_id VARCHAR(255) NOT NULL,
+    stripe_subscription_id VARCHAR(255),
+    current_period_end TIMESTAMP WITH TIME ZONE,
+    trial_ends_at TIMESTAMP WITH TIME ZONE,
+    cancel_at_period_end BOOLEAN NOT NULL DEFAULT FALSE,
+    synced_at TIMESTAMP WITH TIME ZONE, -- When Stripe reported the state mirrored; older webhooks are ignored
+    version INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_business_subscriptions_business FOREIGN KEY (business_id) REFERENCES public.businesses(id) ON DELETE CASCADE,
+    CONSTRAINT fk_business_subscriptions_created_by FOREIGN KEY (created_by) REFERENCES public.users(id),
+    CONSTRAINT fk_business_subscriptions_updated_by FOREIGN KEY (updated_by) REFERENCES public.users(id),
+    CONSTRAINT fk_business_subscriptions_deleted_by FOREIGN KEY (deleted_by) REFERENCES public.users(id),
+    CONSTRAINT uq_business_subscriptions_business UNIQUE (business_id),
+    CONSTRAINT uq_business_subscriptions_stripe_subscription UNIQUE (stripe_subscription_id),
+    CONSTRAINT chk_business_subscriptions_tier CHECK (tier IN ('pro', 'premium')),
+    CONSTRAINT chk_business_subscriptions_status CHECK (status IN ('incomplete', 'incomplete_expired', 'trialing',
+        'active', 'past_due', 'unpaid', 'canceled', 'paused'))
+);
+
+COMMENT ON TABLE public.business_subscriptions IS 'Paid subscriptions of businesses, billed through and mirrored from Stripe';
+
+CREATE TABLE public.sms_usage_monthly (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID NOT NULL,
+    month DATE NOT NULL, -- The first day of the month, in UTC
+    used INTEGER NOT NULL DEFAULT 0,
+    version INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_sms_usage_monthly_business FOREIGN KEY (business_id) REFERENCES public.businesses(id) ON DELETE CASCADE,
+    CONSTRAINT uq_sms_usage_monthly UNIQUE (business_id, month),
+    CONSTRAINT chk_sms_usage_monthly_used CHECK (used >= 0)
+);
+
+COMMENT ON TABLE public.sms_usage_monthly IS 'Text messages sent by each business per month, counted against the SMS credits of its plan';
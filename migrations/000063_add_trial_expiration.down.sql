@@ -0,0 +1,5 @@
+-- Rollback migration for trial expiration
+
+DROP TABLE IF EXISTS public.trial_notices;
+DROP INDEX IF EXISTS public.idx_businesses_trial_ends_at;
+ALTER TABLE public.businesses DROP COLUMN IF EXISTS trial_expired_at;
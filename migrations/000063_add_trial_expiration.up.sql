@@ -0,0 +1,31 @@
+-- Migration to end the in-app trials of businesses: owners are emailed a week and a day before
+-- their trial ends and when it does, and businesses that have not subscribed by the end of the
+-- grace period are restricted to the free tier
+
+ALTER TABLE public.businesses ADD COLUMN trial_expired_at TIMESTAMP WITH TIME ZONE;
+
+COMMENT ON COLUMN public.businesses.trial_expired_at IS 'When the business was restricted to the free tier after its trial and grace period';
+
+-- Trials the expiration job has yet to process
+CREATE INDEX idx_businesses_trial_ends_at ON public.businesses(trial_ends_at)
+    WHERE trial_ends_at IS NOT NULL AND trial_expired_at IS NULL AND deleted_at IS NULL;
+
+CREATE TABLE public.trial_notices (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    trial_ends_at TIMESTAMP WITH TIME ZONE NOT NULL, -- The trial the notice is about, so extended trials are noticed again
+    sent_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    version INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_trial_notices_business FOREIGN KEY (business_id) REFERENCES public.businesses(id) ON DELETE CASCADE,
+    CONSTRAINT uq_trial_notices UNIQUE (business_id, kind, trial_ends_at),
+    CONSTRAINT chk_trial_notices_kind CHECK (kind IN ('seven_days', 'one_day', 'ended'))
+);
+
+COMMENT ON TABLE public.trial_notices IS 'Emails sent to owners about their trial ending, each sent once per trial';
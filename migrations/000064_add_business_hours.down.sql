@@ -0,0 +1,4 @@
+-- Rollback migration for business hours; businesses.business_hours still holds the hours of
+-- each business
+
+DROP TABLE IF EXISTS public.business_hours;
@@ -0,0 +1,42 @@
+-- Migration to store the opening hours of businesses and of each of their locations as rows,
+-- one per day of the week. The hours of the whole business are still mirrored in
+-- businesses.business_hours, which bookings are checked against.
+
+CREATE TABLE public.business_hours (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID NOT NULL,
+    location_id UUID, -- The whole business when null
+    weekday SMALLINT NOT NULL,
+    is_open BOOLEAN NOT NULL DEFAULT FALSE,
+    open_time VARCHAR(5),
+    close_time VARCHAR(5),
+    version INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_business_hours_business FOREIGN KEY (business_id) REFERENCES public.businesses(id) ON DELETE CASCADE,
+    CONSTRAINT fk_business_hours_location FOREIGN KEY (location_id) REFERENCES public.business_locations(id) ON DELETE CASCADE,
+    CONSTRAINT chk_business_hours_weekday CHECK (weekday BETWEEN 0 AND 6),
+    CONSTRAINT chk_business_hours_open CHECK (NOT is_open OR (open_time IS NOT NULL AND close_time IS NOT NULL))
+);
+
+-- One row per day, for the business and for each location
+CREATE UNIQUE INDEX uq_business_hours_business ON public.business_hours(business_id, weekday) WHERE location_id IS NULL;
+CREATE UNIQUE INDEX uq_business_hours_location ON public.business_hours(location_id, weekday) WHERE location_id IS NOT NULL;
+
+COMMENT ON TABLE public.business_hours IS 'Opening hours of businesses and their locations, a day without a row being closed';
+COMMENT ON COLUMN public.business_hours.weekday IS 'Day of the week, from 0 for Sunday';
+
+-- Copy the hours businesses already have
+INSERT INTO public.business_hours (business_id, weekday, is_open, open_time, close_time)
+SELECT b.id, d.weekday,
+       COALESCE((b.business_hours -> d.name ->> 'is_open')::BOOLEAN, FALSE),
+       CASE WHEN (b.business_hours -> d.name ->> 'is_open')::BOOLEAN THEN b.business_hours -> d.name ->> 'open_time' END,
+       CASE WHEN (b.business_hours -> d.name ->> 'is_open')::BOOLEAN THEN b.business_hours -> d.name ->> 'close_time' END
+FROM public.businesses b
+CROSS JOIN (VALUES (0, 'sunday'), (1, 'monday'), (2, 'tuesday'), (3, 'wednesday'),
+                   (4, 'thursday'), (5, 'friday'), (6, 'saturday')) AS d(weekday, name)
+WHERE jsonb_typeof(b.business_hours -> d.name) = 'object';
@@ -0,0 +1,4 @@
+-- Rollback migration for resources
+
+DROP TABLE IF EXISTS public.service_resources;
+DROP TABLE IF EXISTS public.resources;
@@ -0,0 +1,51 @@
+-- Migration to book the rooms, chairs and equipment services need: appointments for services
+-- needing a resource cannot overlap more of its other appointments than its capacity allows
+
+CREATE TABLE public.resources (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID NOT NULL,
+    location_id UUID,
+    name VARCHAR(100) NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    capacity INTEGER NOT NULL DEFAULT 1,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    notes TEXT,
+    version INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_resources_business FOREIGN KEY (business_id) REFERENCES public.businesses(id) ON DELETE CASCADE,
+    CONSTRAINT fk_resources_location FOREIGN KEY (location_id) REFERENCES public.business_locations(id) ON DELETE SET NULL,
+    CONSTRAINT chk_resources_kind CHECK (kind IN ('room', 'chair', 'equipment')),
+    CONSTRAINT chk_resources_capacity CHECK (capacity >= 1)
+);
+
+CREATE INDEX idx_resources_business_id ON public.resources(business_id) WHERE deleted_at IS NULL;
+
+COMMENT ON TABLE public.resources IS 'Rooms, chairs and equipment that appointments occupy';
+COMMENT ON COLUMN public.resources.capacity IS 'How many appointments the resource can hold at once';
+
+CREATE TABLE public.service_resources (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    business_id UUID NOT NULL,
+    service_id UUID NOT NULL,
+    resource_id UUID NOT NULL,
+    version INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_service_resources_business FOREIGN KEY (business_id) REFERENCES public.businesses(id) ON DELETE CASCADE,
+    CONSTRAINT fk_service_resources_service FOREIGN KEY (service_id) REFERENCES public.services(id) ON DELETE CASCADE,
+    CONSTRAINT fk_service_resources_resource FOREIGN KEY (resource_id) REFERENCES public.resources(id) ON DELETE CASCADE,
+    CONSTRAINT uq_service_resources UNIQUE (service_id, resource_id)
+);
+
+CREATE INDEX idx_service_resources_resource_id ON public.service_resources(resource_id);
+
+COMMENT ON TABLE public.service_resources IS 'The resources every appointment for a service occupies';
@@ -0,0 +1,3 @@
+-- Rollback migration for user locales
+
+ALTER TABLE public.users DROP COLUMN IF EXISTS locale;
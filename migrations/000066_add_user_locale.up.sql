@@ -0,0 +1,6 @@
+-- Migration to add the locale users chose to read the app in, which error messages are written
+-- in rather than the one their browser prefers
+
+ALTER TABLE public.users ADD COLUMN locale VARCHAR(10) CHECK (locale IN ('en', 'pt'));
+
+COMMENT ON COLUMN public.users.locale IS 'The locale the user reads the app in; the Accept-Language header decides when null';
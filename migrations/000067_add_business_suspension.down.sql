@@ -0,0 +1,5 @@
+-- Rollback migration for business suspension
+
+DROP INDEX IF EXISTS idx_businesses_suspended_at;
+ALTER TABLE public.businesses DROP COLUMN IF EXISTS suspension_reason;
+ALTER TABLE public.businesses DROP COLUMN IF EXISTS suspended_at;
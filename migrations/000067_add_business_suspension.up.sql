@@ -0,0 +1,10 @@
+-- Migration to let platform operators suspend businesses, which stops them from being used or
+-- booked until they are reactivated
+
+ALTER TABLE public.businesses ADD COLUMN suspended_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE public.businesses ADD COLUMN suspension_reason VARCHAR(500);
+
+CREATE INDEX idx_businesses_suspended_at ON public.businesses(suspended_at) WHERE suspended_at IS NOT NULL;
+
+COMMENT ON COLUMN public.businesses.suspended_at IS 'When platform operators suspended the business; null when it is not suspended';
+COMMENT ON COLUMN public.businesses.suspension_reason IS 'Why platform operators suspended the business';
@@ -0,0 +1,3 @@
+-- Rollback migration for idempotency keys
+
+DROP TABLE IF EXISTS public.idempotency_keys;
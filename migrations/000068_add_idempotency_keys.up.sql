@@ -0,0 +1,27 @@
+-- Migration to add idempotency keys: the first response to a mutation sent with an
+-- Idempotency-Key header, replayed to its retries so that they do not run it again
+
+CREATE TABLE public.idempotency_keys (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL,
+    key VARCHAR(255) NOT NULL,
+    request_hash VARCHAR(64) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'completed')),
+    response TEXT,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    version INTEGER NOT NULL DEFAULT 1,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_by UUID,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    updated_by UUID,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by UUID,
+    CONSTRAINT fk_idempotency_keys_user FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE
+);
+
+COMMENT ON TABLE public.idempotency_keys IS 'First responses to mutations sent with an Idempotency-Key header, replayed to retries for a day';
+COMMENT ON COLUMN public.idempotency_keys.request_hash IS 'SHA-256 of the operation and its variables; the key cannot be reused for another request';
+COMMENT ON COLUMN public.idempotency_keys.response IS 'The GraphQL response recorded once the request completed';
+
+CREATE UNIQUE INDEX idx_idempotency_keys_user_key ON public.idempotency_keys(user_id, key);
+CREATE INDEX idx_idempotency_keys_expires_at ON public.idempotency_keys(expires_at);
@@ -0,0 +1,4 @@
+-- Rollback migration for user security settings
+
+ALTER TABLE public.users DROP COLUMN IF EXISTS two_factor_enabled;
+ALTER TABLE public.users DROP COLUMN IF EXISTS password_changed_at;
@@ -0,0 +1,8 @@
+-- Migration to record the password and two-factor settings Clerk reports for users, so that a
+-- change to either signs their other devices out
+
+ALTER TABLE public.users ADD COLUMN password_changed_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE public.users ADD COLUMN two_factor_enabled BOOLEAN;
+
+COMMENT ON COLUMN public.users.password_changed_at IS 'When Clerk last reported the password changed; NULL until first reported';
+COMMENT ON COLUMN public.users.two_factor_enabled IS 'Whether Clerk last reported two-factor authentication on; NULL until first reported';
@@ -0,0 +1,3 @@
+-- Rollback migration for the open deposit index
+
+DROP INDEX IF EXISTS public.uq_payments_open_deposit;
@@ -0,0 +1,6 @@
+-- Migration to allow one deposit paid or in progress per appointment, so that two charges of the
+-- same deposit racing each other cannot both reach Stripe. Deposits that failed or were refunded
+-- in full may be charged again.
+
+CREATE UNIQUE INDEX uq_payments_open_deposit ON public.payments(appointment_id)
+    WHERE kind = 'deposit' AND status IN ('pending', 'requires_action', 'succeeded', 'partially_refunded') AND deleted_at IS NULL;
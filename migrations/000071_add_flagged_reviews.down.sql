@@ -0,0 +1,5 @@
+-- Rollback migration for flagged reviews
+
+UPDATE public.reviews SET status = 'pending' WHERE status = 'flagged';
+ALTER TABLE public.reviews DROP CONSTRAINT chk_reviews_status;
+ALTER TABLE public.reviews ADD CONSTRAINT chk_reviews_status CHECK (status IN ('pending', 'published', 'rejected'));
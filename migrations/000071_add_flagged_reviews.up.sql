@@ -0,0 +1,5 @@
+-- Migration to let the content filter hold reviews, and reviews with a reply, back as flagged
+-- until the business publishes or rejects them
+
+ALTER TABLE public.reviews DROP CONSTRAINT chk_reviews_status;
+ALTER TABLE public.reviews ADD CONSTRAINT chk_reviews_status CHECK (status IN ('pending', 'published', 'rejected', 'flagged'));
@@ -0,0 +1,7 @@
+-- Rollback migration for scoped idempotency keys
+
+DELETE FROM public.idempotency_keys WHERE user_id IS NULL;
+DROP INDEX IF EXISTS public.idx_idempotency_keys_scope_key;
+CREATE UNIQUE INDEX idx_idempotency_keys_user_key ON public.idempotency_keys(user_id, key);
+ALTER TABLE public.idempotency_keys ALTER COLUMN user_id SET NOT NULL;
+ALTER TABLE public.idempotency_keys DROP COLUMN IF EXISTS scope;
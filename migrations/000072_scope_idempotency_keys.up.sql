@@ -0,0 +1,12 @@
+-- Migration to scope idempotency keys to the user who sent them or, for anonymous callers of the
+-- public booking API, to their IP address
+
+ALTER TABLE public.idempotency_keys ADD COLUMN scope VARCHAR(100);
+UPDATE public.idempotency_keys SET scope = 'user:' || user_id;
+ALTER TABLE public.idempotency_keys ALTER COLUMN scope SET NOT NULL;
+ALTER TABLE public.idempotency_keys ALTER COLUMN user_id DROP NOT NULL;
+
+DROP INDEX IF EXISTS public.idx_idempotency_keys_user_key;
+CREATE UNIQUE INDEX idx_idempotency_keys_scope_key ON public.idempotency_keys(scope, key);
+
+COMMENT ON COLUMN public.idempotency_keys.scope IS 'Whose keys the key is unique among: user:<user ID>, or ip:<address> for anonymous callers';
@@ -0,0 +1,35 @@
+// Package migrations embeds the SQL migrations, which golang-migrate applies, so that the API
+// knows the schema version it was built for.
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+// files holds the up migrations
+//
+//go:embed *.up.sql
+var files embed.FS
+
+// Latest returns the version of the newest up migration
+func Latest() (uint64, error) {
+	names, err := fs.Glob(files, "*.up.sql")
+	if err != nil {
+		return 0, err
+	}
+
+	var latest uint64
+	for _, name := range names {
+		prefix, _, _ := strings.Cut(name, "_")
+		version, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid migration file name %s", name)
+		}
+		latest = max(latest, version)
+	}
+	return latest, nil
+}
@@ -0,0 +1,91 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/service"
+)
+
+// adminNamespace is the source of the fields of the admin namespaces, which carry no data
+type adminNamespace struct{}
+
+// Admin Namespace Resolvers
+func (r *Resolver) resolveAdmin(p graphql.ResolveParams) (any, error) {
+	// Refused here as well as by the services, so business staff learn nothing of the back office
+	if !service.IsPlatformAdmin(p.Context) {
+		return nil, service.NewForbiddenError("use the back office")
+	}
+	return adminNamespace{}, nil
+}
+
+// Admin Query Resolvers
+func (r *Resolver) resolveAdminBusinesses(p graphql.ResolveParams) (any, error) {
+	searchDTO := dto.AdminBusinessSearchDTO{}
+	if query, ok := p.Args["query"].(string); ok {
+		searchDTO.Query = query
+	}
+	if status, ok := p.Args["status"].(domain.BusinessStatus); ok {
+		searchDTO.Status = &status
+	}
+	if tier, ok := p.Args["tier"].(domain.SubscriptionTier); ok {
+		searchDTO.Tier = &tier
+	}
+	page, pageSize := pageFromArgs(p.Args, 20)
+
+	businesses, total, err := r.adminService.SearchBusinesses(p.Context, searchDTO, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{"businesses": businesses, "total": total}, nil
+}
+
+func (r *Resolver) resolveAdminBusiness(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+
+	business, err := r.adminService.GetBusiness(p.Context, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return business, nil
+}
+
+// Admin Mutation Resolvers
+func (r *Resolver) resolveSuspendBusiness(p graphql.ResolveParams) (any, error) {
+	suspendDTO := dto.SuspendBusinessDTO{}
+	if businessID, ok := p.Args["businessId"].(string); ok {
+		suspendDTO.BusinessID = businessID
+	}
+	if reason, ok := p.Args["reason"].(string); ok {
+		suspendDTO.Reason = reason
+	}
+
+	business, err := r.adminService.SuspendBusiness(p.Context, suspendDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return business, nil
+}
+
+func (r *Resolver) resolveReactivateBusiness(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+
+	business, err := r.adminService.ReactivateBusiness(p.Context, businessID)
+	if err != nil {
+		return nil, err
+	}
+
+	return business, nil
+}
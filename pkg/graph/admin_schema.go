@@ -0,0 +1,219 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// BusinessStatusEnum represents the GraphQL enum for whether a business can be used
+var BusinessStatusEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "BusinessStatus",
+	Description: "Whether a business can be used and booked",
+	Values: graphql.EnumValueConfigMap{
+		"ACTIVE": &graphql.EnumValueConfig{
+			Value:       domain.BusinessStatusActive,
+			Description: "Can be used and booked",
+		},
+		"SUSPENDED": &graphql.EnumValueConfig{
+			Value:       domain.BusinessStatusSuspended,
+			Description: "Suspended by platform operators; neither its staff nor its clients can use it",
+		},
+		"INACTIVE": &graphql.EnumValueConfig{
+			Value:       domain.BusinessStatusInactive,
+			Description: "Deactivated without being suspended; it cannot be booked",
+		},
+	},
+})
+
+// AdminBusinessType represents the GraphQL AdminBusiness type
+var AdminBusinessType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "AdminBusiness",
+	Description: "A business of any tenant, as platform operators see it",
+	Fields: withBaseFields("business", graphql.Fields{
+		"ownerId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the user who owns the business",
+		},
+		"name": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The name of the business",
+		},
+		"displayName": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The name shown to clients, when it differs from the name",
+		},
+		"email": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The email address of the business",
+		},
+		"status": &graphql.Field{
+			Type:        graphql.NewNonNull(BusinessStatusEnum),
+			Description: "Whether the business can be used",
+		},
+		"tier": &graphql.Field{
+			Type:        graphql.NewNonNull(SubscriptionTierEnum),
+			Description: "The tier the business has now",
+		},
+		"trialState": &graphql.Field{
+			Type:        graphql.NewNonNull(TrialStateEnum),
+			Description: "Where the business is in its free trial",
+		},
+		"trialEndsAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the free trial ends",
+		},
+		"suspendedAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the business was suspended; null when it is not",
+		},
+		"suspensionReason": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Why the business was suspended",
+		},
+		"dataRegion": &graphql.Field{
+			Type:        graphql.NewNonNull(DataRegionEnum),
+			Description: "The region the business's data must stay in",
+		},
+		"mode": &graphql.Field{
+			Type:        graphql.NewNonNull(BusinessModeEnum),
+			Description: "Whether the business is run by a team or a single professional",
+		},
+		"isSandbox": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether the business is a demo whose data can be reset",
+		},
+		"isTemplate": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether the business is kept to clone new businesses from",
+		},
+	}),
+})
+
+// AdminBusinessSearchResultType represents the GraphQL AdminBusinessSearchResult type
+var AdminBusinessSearchResultType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "AdminBusinessSearchResult",
+	Description: "A page of the businesses passing a search, with how many pass it",
+	Fields: graphql.Fields{
+		"businesses": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(AdminBusinessType))),
+			Description: "The businesses of the page, most recently created first",
+		},
+		"total": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "How many businesses pass the search",
+		},
+	},
+})
+
+// adminQueryType returns the AdminQuery namespace, whose fields take the platform admin role.
+// Fields shared with business staff are reused, and let platform admins act for any business.
+func adminQueryType(resolver *Resolver) *graphql.Object {
+	searchArgs := paginationArgs("businesses", 20)
+	searchArgs["query"] = &graphql.ArgumentConfig{
+		Type:        graphql.String,
+		Description: "Text to find in the name, display name or email of the businesses",
+	}
+	searchArgs["status"] = &graphql.ArgumentConfig{
+		Type:        BusinessStatusEnum,
+		Description: "Only businesses with this status",
+	}
+	searchArgs["tier"] = &graphql.ArgumentConfig{
+		Type:        SubscriptionTierEnum,
+		Description: "Only businesses on this tier",
+	}
+
+	subscription := *subscriptionQueryFields(resolver)["businessSubscription"]
+	subscription.Description = "Get the subscription of a business, its billing status and what it uses of its plan"
+	exports := *reportExportQueryFields(resolver)["reportExports"]
+	exports.Description = "Get the most recent report exports of a business, newest first"
+
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name:        "AdminQuery",
+		Description: "The back office of platform operators, across the businesses of every tenant",
+		Fields: graphql.Fields{
+			"businesses": &graphql.Field{
+				Type:        graphql.NewNonNull(AdminBusinessSearchResultType),
+				Description: "Search the businesses of every tenant, most recently created first",
+				Args:        searchArgs,
+				Resolve:     resolver.resolveAdminBusinesses,
+			},
+			"business": &graphql.Field{
+				Type:        AdminBusinessType,
+				Description: "Get a business of any tenant by ID",
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{
+						Type:        graphql.NewNonNull(graphql.String),
+						Description: "The ID of the business",
+					},
+				},
+				Resolve: resolver.resolveAdminBusiness,
+			},
+			"businessSubscription": &subscription,
+			"reportExports":        &exports,
+		},
+	})
+}
+
+// adminMutationType returns the AdminMutation namespace, whose fields take the platform admin role
+func adminMutationType(resolver *Resolver) *graphql.Object {
+	export := *reportExportMutationFields(resolver)["exportReport"]
+	export.Description = "Export a report of a business to a file in the background, e.g. when its owner asks support for their data"
+
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name:        "AdminMutation",
+		Description: "The back office of platform operators, across the businesses of every tenant",
+		Fields: graphql.Fields{
+			"suspendBusiness": &graphql.Field{
+				Type: AdminBusinessType,
+				Description: "Suspend a business: its staff are refused and its clients cannot book it until it is " +
+					"reactivated. Its data is kept.",
+				Args: graphql.FieldConfigArgument{
+					"businessId": &graphql.ArgumentConfig{
+						Type:        graphql.NewNonNull(graphql.String),
+						Description: "The ID of the business",
+					},
+					"reason": &graphql.ArgumentConfig{
+						Type:        graphql.NewNonNull(graphql.String),
+						Description: "Why the business is suspended, at most 500 characters",
+					},
+				},
+				Resolve: resolver.resolveSuspendBusiness,
+			},
+			"reactivateBusiness": &graphql.Field{
+				Type:        AdminBusinessType,
+				Description: "Lift the suspension of a business",
+				Args: graphql.FieldConfigArgument{
+					"businessId": &graphql.ArgumentConfig{
+						Type:        graphql.NewNonNull(graphql.String),
+						Description: "The ID of the business",
+					},
+				},
+				Resolve: resolver.resolveReactivateBusiness,
+			},
+			"exportReport": &export,
+		},
+	})
+}
+
+// adminQueryFields returns the admin query namespace
+func adminQueryFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"admin": &graphql.Field{
+			Type:        graphql.NewNonNull(adminQueryType(resolver)),
+			Description: "The back office of platform operators (platform admin only)",
+			Resolve:     resolver.resolveAdmin,
+		},
+	}
+}
+
+// adminMutationFields returns the admin mutation namespace
+func adminMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"admin": &graphql.Field{
+			Type:        graphql.NewNonNull(adminMutationType(resolver)),
+			Description: "The back office of platform operators (platform admin only)",
+			Resolve:     resolver.resolveAdmin,
+		},
+	}
+}
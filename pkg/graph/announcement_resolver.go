@@ -0,0 +1,78 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Announcement Query Resolvers
+func (r *Resolver) resolveAnnouncementFeed(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+	locale, _ := p.Args["locale"].(string)
+
+	feed, err := r.announcementService.GetAnnouncementFeed(p.Context, businessID, locale)
+	if err != nil {
+		return nil, err
+	}
+
+	return feed, nil
+}
+
+func (r *Resolver) resolveAnnouncements(p graphql.ResolveParams) (any, error) {
+	page, pageSize := pageFromArgs(p.Args, 20)
+
+	announcements, _, err := r.announcementService.ListAnnouncements(p.Context, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return announcements, nil
+}
+
+// Announcement Mutation Resolvers
+func (r *Resolver) resolveCreateAnnouncement(p graphql.ResolveParams) (any, error) {
+	createDTO := dto.CreateAnnouncementDTO{}
+	if err := decodeInput(p.Args, &createDTO); err != nil {
+		return nil, err
+	}
+
+	announcement, err := r.announcementService.CreateAnnouncement(p.Context, createDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return announcement, nil
+}
+
+func (r *Resolver) resolveDeleteAnnouncement(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+
+	if err := r.announcementService.DeleteAnnouncement(p.Context, id); err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		"success": true,
+		"message": "Announcement deleted successfully",
+	}, nil
+}
+
+func (r *Resolver) resolveMarkAnnouncementsRead(p graphql.ResolveParams) (any, error) {
+	markDTO := dto.MarkAnnouncementsReadDTO{AnnouncementIDs: stringList(p.Args["announcementIds"])}
+
+	marked, err := r.announcementService.MarkAnnouncementsRead(p.Context, markDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return int(marked), nil
+}
@@ -0,0 +1,191 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+)
+
+// AnnouncementType represents the GraphQL Announcement type
+var AnnouncementType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Announcement",
+	Description: "A product update the platform posts to the in-app feed of businesses",
+	Fields: withBaseFields("announcement", graphql.Fields{
+		"title": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The title of the announcement",
+		},
+		"body": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The announcement in Markdown",
+		},
+		"link": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Where to read more",
+		},
+		"plans": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+			Description: "The subscription tiers targeted; empty targets every tier",
+		},
+		"locales": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+			Description: "The locales targeted; empty targets every locale",
+		},
+		"publishAt": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the announcement appears in feeds",
+		},
+		"expiresAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the announcement leaves feeds",
+		},
+	}),
+})
+
+// AnnouncementFeedItemType represents the GraphQL AnnouncementFeedItem type
+var AnnouncementFeedItemType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "AnnouncementFeedItem",
+	Description: "An announcement in a user's feed",
+	Fields: graphql.Fields{
+		"id": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the announcement",
+		},
+		"title": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The title of the announcement",
+		},
+		"body": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The announcement in Markdown",
+		},
+		"link": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Where to read more",
+		},
+		"publishAt": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the announcement was published",
+		},
+		"readAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the user read the announcement",
+		},
+	},
+})
+
+// AnnouncementFeedType represents the GraphQL AnnouncementFeed type
+var AnnouncementFeedType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "AnnouncementFeed",
+	Description: "The announcements shown to a user of a business",
+	Fields: graphql.Fields{
+		"announcements": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(AnnouncementFeedItemType))),
+			Description: "The live announcements, most recently published first",
+		},
+		"unreadCount": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "How many of the announcements the user has not read, for the inbox badge",
+		},
+	},
+})
+
+// CreateAnnouncementInput represents the input for posting an announcement
+var CreateAnnouncementInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "CreateAnnouncementInput",
+	Description: "Input for posting a product update to the in-app feed of businesses",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"title": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The title, at most 200 characters",
+		},
+		"body": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The announcement in Markdown",
+		},
+		"link": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "Where to read more",
+		},
+		"plans": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+			Description: "The subscription tiers to target, e.g. [\"pro\"]; omit to target every tier",
+		},
+		"locales": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+			Description: "The locales to target, e.g. [\"pt\", \"en-GB\"]; a language targets all its regions. Omit to target every locale",
+		},
+		"publishAt": &graphql.InputObjectFieldConfig{
+			Type:        graphql.DateTime,
+			Description: "When the announcement appears in feeds; defaults to now",
+		},
+		"expiresAt": &graphql.InputObjectFieldConfig{
+			Type:        graphql.DateTime,
+			Description: "When the announcement leaves feeds; by default it stays",
+		},
+	},
+})
+
+// announcementQueryFields returns the announcement queries
+func announcementQueryFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"announcementFeed": &graphql.Field{
+			Type:        AnnouncementFeedType,
+			Description: "Get the announcements for the caller's in-app inbox, targeted by the business's plan and the caller's locale",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business the caller works at",
+				},
+				"locale": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: "The locale the caller reads the app in, e.g. pt-PT; defaults to the locale of the request",
+				},
+			},
+			Resolve: resolver.resolveAnnouncementFeed,
+		},
+		"announcements": &graphql.Field{
+			Type:        graphql.NewList(AnnouncementType),
+			Description: "List every announcement, including scheduled and expired ones, latest first (platform admins only)",
+			Args:        paginationArgs("announcements", 20),
+			Resolve:     resolver.resolveAnnouncements,
+		},
+	}
+}
+
+// announcementMutationFields returns the announcement mutations
+func announcementMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"createAnnouncement": &graphql.Field{
+			Type:        AnnouncementType,
+			Description: "Post a product update to the in-app feed of businesses (platform admins only)",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(CreateAnnouncementInput),
+					Description: "The announcement",
+				},
+			},
+			Resolve: resolver.resolveCreateAnnouncement,
+		},
+		"deleteAnnouncement": &graphql.Field{
+			Type:        DeleteResultType,
+			Description: "Retract an announcement from every feed (platform admins only)",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the announcement",
+				},
+			},
+			Resolve: resolver.resolveDeleteAnnouncement,
+		},
+		"markAnnouncementsRead": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "Mark announcements as read by the caller, returning how many were unread",
+			Args: graphql.FieldConfigArgument{
+				"announcementIds": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+					Description: "The IDs of the announcements, at most 100",
+				},
+			},
+			Resolve: resolver.resolveMarkAnnouncementsRead,
+		},
+	}
+}
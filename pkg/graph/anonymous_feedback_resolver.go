@@ -0,0 +1,55 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Anonymous Feedback Query Resolvers
+func (r *Resolver) resolveAnonymousFeedback(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+	unreadOnly, _ := p.Args["unreadOnly"].(bool)
+	page, pageSize := pageFromArgs(p.Args, 20)
+
+	feedback, _, err := r.anonymousFeedbackService.ListAnonymousFeedback(p.Context, businessID, unreadOnly, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return feedback, nil
+}
+
+// Anonymous Feedback Mutation Resolvers
+func (r *Resolver) resolveCreateFeedbackLink(p graphql.ResolveParams) (any, error) {
+	appointmentID, ok := p.Args["appointmentId"].(string)
+	if !ok {
+		return nil, errors.New("appointmentId is required")
+	}
+
+	link, err := r.anonymousFeedbackService.CreateFeedbackLink(p.Context, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return link, nil
+}
+
+func (r *Resolver) resolveMarkAnonymousFeedbackRead(p graphql.ResolveParams) (any, error) {
+	markDTO := dto.MarkAnonymousFeedbackReadDTO{FeedbackIDs: stringList(p.Args["feedbackIds"])}
+	if businessID, ok := p.Args["businessId"].(string); ok {
+		markDTO.BusinessID = businessID
+	}
+
+	marked, err := r.anonymousFeedbackService.MarkAnonymousFeedbackRead(p.Context, markDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return int(marked), nil
+}
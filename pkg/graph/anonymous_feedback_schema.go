@@ -0,0 +1,109 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+)
+
+// FeedbackLinkType represents the GraphQL FeedbackLink type
+var FeedbackLinkType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "FeedbackLink",
+	Description: "The link a client opens to send anonymous feedback about a completed appointment",
+	Fields: graphql.Fields{
+		"appointmentId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the appointment",
+		},
+		"url": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The link to send to the client",
+		},
+		"expiresAt": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the link stops working",
+		},
+	},
+})
+
+// AnonymousFeedbackType represents the GraphQL AnonymousFeedback type
+var AnonymousFeedbackType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "AnonymousFeedback",
+	Description: "Feedback a client sent without identifying themselves",
+	Fields: graphql.Fields{
+		"id": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The unique identifier of the feedback",
+		},
+		"rating": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "The rating from 1 to 5 stars, when given",
+		},
+		"message": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The feedback",
+		},
+		"submittedOn": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "The day the feedback was sent; the time is not recorded",
+		},
+		"readAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the owner marked the feedback as read",
+		},
+	},
+})
+
+// anonymousFeedbackQueryFields returns the anonymous feedback queries
+func anonymousFeedbackQueryFields(resolver *Resolver) graphql.Fields {
+	args := paginationArgs("feedback", 20)
+	args["businessId"] = &graphql.ArgumentConfig{
+		Type:        graphql.NewNonNull(graphql.String),
+		Description: "The ID of the business",
+	}
+	args["unreadOnly"] = &graphql.ArgumentConfig{
+		Type:         graphql.Boolean,
+		Description:  "Only return feedback that has not been marked as read",
+		DefaultValue: false,
+	}
+
+	return graphql.Fields{
+		"anonymousFeedback": &graphql.Field{
+			Type:        graphql.NewList(AnonymousFeedbackType),
+			Description: "Get the anonymous feedback sent to a business, most recent day first (business owner only)",
+			Args:        args,
+			Resolve:     resolver.resolveAnonymousFeedback,
+		},
+	}
+}
+
+// anonymousFeedbackMutationFields returns the anonymous feedback mutations
+func anonymousFeedbackMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"createFeedbackLink": &graphql.Field{
+			Type: FeedbackLinkType,
+			Description: "Get the link the client of a completed appointment can send anonymous feedback with. " +
+				"Each appointment has a single link that can be used once.",
+			Args: graphql.FieldConfigArgument{
+				"appointmentId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the completed appointment",
+				},
+			},
+			Resolve: resolver.resolveCreateFeedbackLink,
+		},
+		"markAnonymousFeedbackRead": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "Mark anonymous feedback as read (business owner only), returning how many were unread",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+				"feedbackIds": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+					Description: "The IDs of the feedback, at most 100",
+				},
+			},
+			Resolve: resolver.resolveMarkAnonymousFeedbackRead,
+		},
+	}
+}
@@ -0,0 +1,76 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Appointment Confirmation Query Resolvers
+func (r *Resolver) resolveUnconfirmedAppointments(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+	hours, _ := p.Args["hours"].(int)
+
+	appointments, err := r.appointmentConfirmationService.GetUnconfirmedAppointments(p.Context, businessID, hours)
+	if err != nil {
+		return nil, err
+	}
+
+	return appointments, nil
+}
+
+func (r *Resolver) resolveAppointmentReminderChain(p graphql.ResolveParams) (any, error) {
+	appointmentID, ok := p.Args["appointmentId"].(string)
+	if !ok {
+		return nil, errors.New("appointmentId is required")
+	}
+
+	return r.appointmentConfirmationService.GetReminderChain(p.Context, appointmentID)
+}
+
+func (r *Resolver) resolveFrontDeskTasks(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+	var status *domain.FrontDeskTaskStatus
+	if value, ok := p.Args["status"].(domain.FrontDeskTaskStatus); ok {
+		status = &value
+	}
+
+	return r.appointmentConfirmationService.ListFrontDeskTasks(p.Context, businessID, status)
+}
+
+// Appointment Confirmation Mutation Resolvers
+func (r *Resolver) resolveConfirmAppointment(p graphql.ResolveParams) (any, error) {
+	appointmentID, ok := p.Args["appointmentId"].(string)
+	if !ok {
+		return nil, errors.New("appointmentId is required")
+	}
+
+	if err := r.appointmentConfirmationService.ConfirmAppointment(p.Context, appointmentID); err != nil {
+		return nil, err
+	}
+
+	return true, nil
+}
+
+func (r *Resolver) resolveCompleteFrontDeskTask(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+	completeDTO := dto.CompleteFrontDeskTaskDTO{}
+	if outcome, ok := p.Args["outcome"].(string); ok {
+		completeDTO.Outcome = &outcome
+	}
+	completeDTO.Confirmed, _ = p.Args["confirmed"].(bool)
+
+	return r.appointmentConfirmationService.CompleteFrontDeskTask(p.Context, id, completeDTO)
+}
@@ -0,0 +1,316 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// ConfirmationStatusEnum represents the GraphQL enum for confirmation request statuses
+var ConfirmationStatusEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "ConfirmationStatus",
+	Description: "The state of a confirmation request sent to a client",
+	Values: graphql.EnumValueConfigMap{
+		"PENDING": &graphql.EnumValueConfig{
+			Value:       domain.ConfirmationStatusPending,
+			Description: "Sent, waiting for the client to confirm",
+		},
+		"CONFIRMED": &graphql.EnumValueConfig{
+			Value:       domain.ConfirmationStatusConfirmed,
+			Description: "The client confirmed",
+		},
+		"FAILED": &graphql.EnumValueConfig{
+			Value:       domain.ConfirmationStatusFailed,
+			Description: "The request could not be delivered",
+		},
+	},
+})
+
+// FrontDeskTaskKindEnum represents the GraphQL enum for front desk task kinds
+var FrontDeskTaskKindEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "FrontDeskTaskKind",
+	Description: "What the front desk is asked to do",
+	Values: graphql.EnumValueConfigMap{
+		"CALL_CLIENT": &graphql.EnumValueConfig{
+			Value:       domain.FrontDeskTaskCallClient,
+			Description: "Call a client who did not acknowledge the reminders of an appointment",
+		},
+	},
+})
+
+// FrontDeskTaskStatusEnum represents the GraphQL enum for front desk task statuses
+var FrontDeskTaskStatusEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "FrontDeskTaskStatus",
+	Description: "The state of a front desk task",
+	Values: graphql.EnumValueConfigMap{
+		"OPEN": &graphql.EnumValueConfig{
+			Value:       domain.FrontDeskTaskOpen,
+			Description: "Still to be done",
+		},
+		"DONE": &graphql.EnumValueConfig{
+			Value:       domain.FrontDeskTaskDone,
+			Description: "Done",
+		},
+	},
+})
+
+// FrontDeskTaskType represents the GraphQL FrontDeskTask type
+var FrontDeskTaskType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "FrontDeskTask",
+	Description: "A to-do for the front desk of a business",
+	Fields: withBaseFields("task", graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"appointmentId": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The ID of the appointment the task is about",
+		},
+		"clientId": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The ID of the client the task is about",
+		},
+		"kind": &graphql.Field{
+			Type:        graphql.NewNonNull(FrontDeskTaskKindEnum),
+			Description: "What the front desk is asked to do",
+		},
+		"status": &graphql.Field{
+			Type:        graphql.NewNonNull(FrontDeskTaskStatusEnum),
+			Description: "The state of the task",
+		},
+		"reason": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "Why the task was created",
+		},
+		"dueAt": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the task is due",
+		},
+		"completedAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the task was done",
+		},
+		"completedBy": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The ID of the user who did the task",
+		},
+		"outcome": &graphql.Field{
+			Type:        graphql.String,
+			Description: "What came of the task, e.g. a note on the call",
+		},
+	}),
+})
+
+// ReminderAttemptType represents the GraphQL ReminderAttempt type
+var ReminderAttemptType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ReminderAttempt",
+	Description: "A confirmation request sent to a client about an appointment",
+	Fields: graphql.Fields{
+		"attempt": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The position of the request in the chain, starting at 1",
+		},
+		"channel": &graphql.Field{
+			Type:        graphql.NewNonNull(MessageChannelEnum),
+			Description: "How the request was sent",
+		},
+		"recipient": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The phone number or email address the request was sent to",
+		},
+		"status": &graphql.Field{
+			Type:        graphql.NewNonNull(ConfirmationStatusEnum),
+			Description: "The state of the request",
+		},
+		"sentAt": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the request was sent",
+		},
+		"deliveredAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the messaging provider accepted the request",
+		},
+		"deliveryError": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Why the request could not be delivered",
+		},
+		"respondedAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the appointment was confirmed",
+		},
+		"escalatedAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the request was escalated for going unacknowledged",
+		},
+	},
+})
+
+// ReminderChainType represents the GraphQL ReminderChain type
+var ReminderChainType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ReminderChain",
+	Description: "The confirmation requests sent about an appointment and what they were escalated to",
+	Fields: graphql.Fields{
+		"appointmentId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the appointment",
+		},
+		"acknowledged": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether the appointment was confirmed",
+		},
+		"attempts": &graphql.Field{
+			Type:        graphql.NewList(ReminderAttemptType),
+			Description: "The requests sent, first to last",
+		},
+		"tasks": &graphql.Field{
+			Type:        graphql.NewList(FrontDeskTaskType),
+			Description: "The front desk tasks the requests were escalated to",
+		},
+	},
+})
+
+// UnconfirmedAppointmentType represents the GraphQL UnconfirmedAppointment type
+var UnconfirmedAppointmentType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "UnconfirmedAppointment",
+	Description: "An upcoming appointment the client has not confirmed yet",
+	Fields: graphql.Fields{
+		"appointmentId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the appointment",
+		},
+		"staffId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the staff member",
+		},
+		"clientId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the client",
+		},
+		"startTime": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the appointment starts",
+		},
+		"endTime": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the appointment ends",
+		},
+		"status": &graphql.Field{
+			Type:        graphql.NewNonNull(AppointmentStatusEnum),
+			Description: "The status of the appointment",
+		},
+		"clientName": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The client's full name",
+		},
+		"clientPhone": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The client's phone number",
+		},
+		"clientEmail": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The client's email address",
+		},
+		"staffName": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The staff member's full name",
+		},
+		"requestStatus": &graphql.Field{
+			Type:        ConfirmationStatusEnum,
+			Description: "The state of the confirmation request, null if none has been sent yet",
+		},
+		"requestChannel": &graphql.Field{
+			Type:        MessageChannelEnum,
+			Description: "How the confirmation request was sent",
+		},
+		"requestSentAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the confirmation request was sent",
+		},
+	},
+})
+
+// appointmentConfirmationQueryFields returns the appointment confirmation queries
+func appointmentConfirmationQueryFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"unconfirmedAppointments": &graphql.Field{
+			Type:        graphql.NewList(UnconfirmedAppointmentType),
+			Description: "Get the upcoming appointments clients have not confirmed, soonest first",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+				"hours": &graphql.ArgumentConfig{
+					Type:         graphql.Int,
+					Description:  "How many hours ahead to look (at most 336)",
+					DefaultValue: 48,
+				},
+			},
+			Resolve: resolver.resolveUnconfirmedAppointments,
+		},
+		"appointmentReminderChain": &graphql.Field{
+			Type:        ReminderChainType,
+			Description: "Get the confirmation requests sent about an appointment, with their delivery, response and escalation",
+			Args: graphql.FieldConfigArgument{
+				"appointmentId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the appointment",
+				},
+			},
+			Resolve: resolver.resolveAppointmentReminderChain,
+		},
+		"frontDeskTasks": &graphql.Field{
+			Type:        graphql.NewList(FrontDeskTaskType),
+			Description: "Get the tasks of a business's front desk, soonest due first",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+				"status": &graphql.ArgumentConfig{
+					Type:        FrontDeskTaskStatusEnum,
+					Description: "Only tasks in this state",
+				},
+			},
+			Resolve: resolver.resolveFrontDeskTasks,
+		},
+	}
+}
+
+// appointmentConfirmationMutationFields returns the appointment confirmation mutations
+func appointmentConfirmationMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"confirmAppointment": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Mark an appointment as confirmed on the client's behalf, e.g. after calling them",
+			Args: graphql.FieldConfigArgument{
+				"appointmentId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the appointment",
+				},
+			},
+			Resolve: resolver.resolveConfirmAppointment,
+		},
+		"completeFrontDeskTask": &graphql.Field{
+			Type:        FrontDeskTaskType,
+			Description: "Mark a front desk task done",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the task",
+				},
+				"outcome": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: "What came of the task, e.g. a note on the call",
+				},
+				"confirmed": &graphql.ArgumentConfig{
+					Type:         graphql.Boolean,
+					Description:  "Whether the client confirmed the appointment of a task to call them",
+					DefaultValue: false,
+				},
+			},
+			Resolve: resolver.resolveCompleteFrontDeskTask,
+		},
+	}
+}
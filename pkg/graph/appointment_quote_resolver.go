@@ -0,0 +1,22 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Appointment Quote Mutation Resolvers
+func (r *Resolver) resolveQuoteAppointment(p graphql.ResolveParams) (any, error) {
+	quoteDTO := dto.QuoteAppointmentDTO{}
+	if err := decodeInput(p.Args, &quoteDTO); err != nil {
+		return nil, err
+	}
+
+	quote, err := r.appointmentQuoteService.QuoteAppointment(p.Context, quoteDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return quote, nil
+}
@@ -0,0 +1,238 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+)
+
+// QuoteAdjustmentType represents the GraphQL QuoteAdjustment type
+var QuoteAdjustmentType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "QuoteAdjustment",
+	Description: "A scheduled price change taking effect by the time of a quoted appointment",
+	Fields: graphql.Fields{
+		"priceChangeId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the price change",
+		},
+		"adjustmentType": &graphql.Field{
+			Type:        graphql.NewNonNull(PriceAdjustmentTypeEnum),
+			Description: "How the price change modifies prices",
+		},
+		"amount": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The percentage or fixed amount of the price change",
+		},
+		"effect": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "What the price change adds to the price; negative for a reduction",
+		},
+	},
+})
+
+// QuoteLineType represents the GraphQL QuoteLine type
+var QuoteLineType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "QuoteLine",
+	Description: "A quoted service or add-on",
+	Fields: graphql.Fields{
+		"serviceId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the service",
+		},
+		"name": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The name of the service",
+		},
+		"addOn": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether the service was quoted as an add-on",
+		},
+		"duration": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The duration in minutes, preparation and cleanup included",
+		},
+		"basePrice": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The current price of the service",
+		},
+		"adjustments": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(QuoteAdjustmentType))),
+			Description: "The scheduled price changes applied, in the order they take effect",
+		},
+		"price": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The price after the adjustments",
+		},
+		"depositAmount": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The deposit the service requires",
+		},
+	},
+})
+
+// QuotePromotionType represents the GraphQL QuotePromotion type
+var QuotePromotionType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "QuotePromotion",
+	Description: "A discount campaign applied to a quote",
+	Fields: graphql.Fields{
+		"campaignId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the campaign",
+		},
+		"name": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The name of the campaign",
+		},
+		"discount": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The amount taken off",
+		},
+	},
+})
+
+// QuoteRedemptionType represents the GraphQL QuoteRedemption type
+var QuoteRedemptionType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "QuoteRedemption",
+	Description: "A loyalty reward the client could redeem on the booking; not included in the quoted total",
+	Fields: graphql.Fields{
+		"programId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the loyalty program",
+		},
+		"programName": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The name of the loyalty program",
+		},
+		"pointsRequired": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The points the reward costs",
+		},
+		"pointsAvailable": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The points the client has",
+		},
+		"discount": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The amount the reward would take off the total",
+		},
+		"eligible": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether the client has enough points",
+		},
+	},
+})
+
+// AppointmentQuoteType represents the GraphQL AppointmentQuote type
+var AppointmentQuoteType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "AppointmentQuote",
+	Description: "The price of an appointment that has not been booked",
+	Fields: graphql.Fields{
+		"token": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "Pass as quoteToken to createAppointment to book at the quoted total",
+		},
+		"expiresAt": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the quote stops being honored",
+		},
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"clientId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the client",
+		},
+		"staffId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the staff member",
+		},
+		"startTime": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the appointment would start",
+		},
+		"endTime": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the appointment would end, after every service and add-on",
+		},
+		"lines": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(QuoteLineType))),
+			Description: "The services, then the add-ons",
+		},
+		"basePrice": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The current prices of the services",
+		},
+		"adjustments": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "What scheduled price changes add; negative for a reduction",
+		},
+		"subtotal": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The price before the promotion",
+		},
+		"promotion": &graphql.Field{
+			Type:        QuotePromotionType,
+			Description: "The promotion taking the most off, as promotions do not stack",
+		},
+		"total": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The price of the appointment",
+		},
+		"depositDue": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The deposit due at booking",
+		},
+		"redemptions": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(QuoteRedemptionType))),
+			Description: "The loyalty rewards the client could redeem",
+		},
+	},
+})
+
+// QuoteAppointmentInput represents the GraphQL input for pricing an appointment
+var QuoteAppointmentInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "QuoteAppointmentInput",
+	Description: "Input for pricing an appointment before booking it",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"businessId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"clientId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the client",
+		},
+		"staffId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The ID of the staff member; required unless the business is solo",
+		},
+		"startTime": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the appointment would start",
+		},
+		"serviceIds": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+			Description: "The services, in the order they are performed",
+		},
+		"addOnServiceIds": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+			Description: "The services added on, performed after the others",
+		},
+	},
+})
+
+// appointmentQuoteMutationFields returns the appointment quote mutations
+func appointmentQuoteMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"quoteAppointment": &graphql.Field{
+			Type:        AppointmentQuoteType,
+			Description: "Price an appointment without booking it, issuing a token that books it at the quoted total for 30 minutes (staff who may book appointments)",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(QuoteAppointmentInput),
+					Description: "The appointment to price",
+				},
+			},
+			Resolve: resolver.resolveQuoteAppointment,
+		},
+	}
+}
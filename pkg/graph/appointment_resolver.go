@@ -0,0 +1,122 @@
+package graph
+
+import (
+	"errors"
+	"time"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Appointment Query Resolvers
+func (r *Resolver) resolveCheckAvailability(p graphql.ResolveParams) (any, error) {
+	checkDTO := dto.CheckAvailabilityDTO{
+		ExcludeAppointmentID: optionalString(p.Args, "excludeAppointmentId"),
+		ServiceIDs:           stringList(p.Args["serviceIds"]),
+	}
+	if businessID, ok := p.Args["businessId"].(string); ok {
+		checkDTO.BusinessID = businessID
+	}
+	if staffID, ok := p.Args["staffId"].(string); ok {
+		checkDTO.StaffID = staffID
+	}
+	if startTime, ok := p.Args["startTime"].(time.Time); ok {
+		checkDTO.StartTime = startTime
+	}
+	if endTime, ok := p.Args["endTime"].(time.Time); ok {
+		checkDTO.EndTime = endTime
+	}
+
+	result, err := r.appointmentService.CheckAvailability(p.Context, checkDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Appointment Mutation Resolvers
+func (r *Resolver) resolveCreateAppointment(p graphql.ResolveParams) (any, error) {
+	createDTO := dto.CreateAppointmentDTO{}
+	if err := decodeInput(p.Args, &createDTO); err != nil {
+		return nil, err
+	}
+
+	appointment, err := r.appointmentService.CreateAppointment(p.Context, createDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return appointment, nil
+}
+
+func (r *Resolver) resolveRescheduleAppointment(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+	rescheduleDTO := dto.RescheduleAppointmentDTO{}
+	if err := decodeInput(p.Args, &rescheduleDTO); err != nil {
+		return nil, err
+	}
+
+	appointment, err := r.appointmentService.RescheduleAppointment(p.Context, id, rescheduleDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return appointment, nil
+}
+
+func (r *Resolver) resolveAcceptAppointment(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+
+	appointment, err := r.appointmentService.AcceptAppointment(p.Context, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return appointment, nil
+}
+
+func (r *Resolver) resolveCancelAppointment(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+	cancelDTO := dto.CancelAppointmentDTO{}
+	if reason, ok := p.Args["reason"].(string); ok {
+		cancelDTO.Reason = reason
+	}
+	if version, ok := p.Args["expectedVersion"].(int); ok {
+		cancelDTO.ExpectedVersion = &version
+	}
+	if waive, ok := p.Args["waiveNoShowPolicy"].(bool); ok {
+		cancelDTO.WaiveNoShowPolicy = waive
+	}
+
+	appointment, err := r.appointmentService.CancelAppointment(p.Context, id, cancelDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return appointment, nil
+}
+
+func (r *Resolver) resolveMarkNoShow(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+
+	appointment, err := r.appointmentService.MarkNoShow(p.Context, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return appointment, nil
+}
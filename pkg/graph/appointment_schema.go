@@ -0,0 +1,325 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// ConflictReasonEnum represents the GraphQL enum for availability conflict reasons
+var ConflictReasonEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "ConflictReason",
+	Description: "Why a staff member cannot take an appointment at a given time",
+	Values: graphql.EnumValueConfigMap{
+		"OVERLAP": &graphql.EnumValueConfig{
+			Value:       domain.ConflictOverlap,
+			Description: "Another appointment, including the business's buffer, overlaps",
+		},
+		"OUTSIDE_HOURS": &graphql.EnumValueConfig{
+			Value:       domain.ConflictOutsideHours,
+			Description: "The business is closed",
+		},
+		"STAFF_UNAVAILABLE": &graphql.EnumValueConfig{
+			Value:       domain.ConflictStaffUnavailable,
+			Description: "The staff member has time off",
+		},
+		"RESOURCE_BUSY": &graphql.EnumValueConfig{
+			Value:       domain.ConflictResourceBusy,
+			Description: "A room, chair or piece of equipment the services need is fully in use",
+		},
+		"STAFF_UNASSIGNED": &graphql.EnumValueConfig{
+			Value:       domain.ConflictStaffUnassigned,
+			Description: "The staff member no longer works for the business",
+		},
+		"OUTSIDE_SHIFT": &graphql.EnumValueConfig{
+			Value:       domain.ConflictOutsideShift,
+			Description: "The staff member is on a rota and not on shift",
+		},
+	},
+})
+
+// AppointmentType represents the GraphQL Appointment type
+var AppointmentType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Appointment",
+	Description: "An appointment of a client with a staff member",
+	Fields: withBaseFields("appointment", graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"clientId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the client",
+		},
+		"staffId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the staff member",
+		},
+		"startTime": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the appointment starts",
+		},
+		"endTime": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the appointment ends",
+		},
+		"status": &graphql.Field{
+			Type:        graphql.NewNonNull(AppointmentStatusEnum),
+			Description: "The status of the appointment",
+		},
+		"notes": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Notes about the appointment",
+		},
+		"bundleId": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The service bundle the appointment was booked from",
+		},
+		"estimatedPrice": &graphql.Field{
+			Type:        DecimalScalar,
+			Description: "The expected price of the appointment",
+		},
+		"broadcastId": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The emergency broadcast that notified the client of a disruption affecting the appointment",
+		},
+		"clientConfirmed": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether the client confirmed they are coming",
+		},
+		"noShowFee": &graphql.Field{
+			Type:        DecimalScalar,
+			Description: "The fee charged for a no-show or late cancellation",
+		},
+		"client":   clientRelation(func(a *dto.AppointmentResponseDTO) string { return a.ClientID }),
+		"staff":    staffRelation(func(a *dto.AppointmentResponseDTO) string { return a.StaffID }),
+		"business": businessRelation(func(a *dto.AppointmentResponseDTO) string { return a.BusinessID }),
+	}),
+})
+
+// AvailabilityConflictType represents the GraphQL AvailabilityConflict type
+var AvailabilityConflictType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "AvailabilityConflict",
+	Description: "One reason a time slot is not available",
+	Fields: graphql.Fields{
+		"reason": &graphql.Field{
+			Type:        graphql.NewNonNull(ConflictReasonEnum),
+			Description: "Why the slot is not available",
+		},
+		"message": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "A description of the conflict",
+		},
+		"appointmentId": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The overlapping appointment",
+		},
+		"exceptionId": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The staff member's availability exception",
+		},
+		"resourceId": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The room, chair or piece of equipment in use",
+		},
+		"start": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the overlapping appointment starts",
+		},
+		"end": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the overlapping appointment ends",
+		},
+	},
+})
+
+// AvailabilityResultType represents the GraphQL AvailabilityResult type
+var AvailabilityResultType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "AvailabilityResult",
+	Description: "Whether a staff member can take an appointment at a time",
+	Fields: graphql.Fields{
+		"available": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether the slot can be booked",
+		},
+		"conflicts": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(AvailabilityConflictType))),
+			Description: "Every reason the slot cannot be booked",
+		},
+	},
+})
+
+// CreateAppointmentInput represents the GraphQL input for booking an appointment
+var CreateAppointmentInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "CreateAppointmentInput",
+	Description: "Input for booking an appointment",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"businessId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"clientId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the client",
+		},
+		"staffId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The ID of the staff member; required unless the business is solo",
+		},
+		"startTime": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the appointment starts",
+		},
+		"endTime": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the appointment ends",
+		},
+		"notes": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "Notes about the appointment",
+		},
+		"quoteToken": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The token of a quote for the appointment, booking it at the quoted total; the booking must match the quote",
+		},
+	},
+})
+
+// RescheduleAppointmentInput represents the GraphQL input for rescheduling an appointment
+var RescheduleAppointmentInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "RescheduleAppointmentInput",
+	Description: "Input for moving an appointment to another time or staff member",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"startTime": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "The new start time",
+		},
+		"endTime": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "The new end time",
+		},
+		"staffId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The new staff member; omit to keep the current one",
+		},
+		"expectedVersion": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Int,
+			Description: "The version of the appointment the change is based on; fails with VERSION_CONFLICT when someone else changed it since",
+		},
+	},
+})
+
+// appointmentQueryFields returns the appointment queries
+func appointmentQueryFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"checkAvailability": &graphql.Field{
+			Type:        AvailabilityResultType,
+			Description: "Check whether a staff member can take an appointment at a time",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+				"staffId": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: "The ID of the staff member; required unless the business is solo",
+				},
+				"startTime": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.DateTime),
+					Description: "When the appointment would start",
+				},
+				"endTime": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.DateTime),
+					Description: "When the appointment would end",
+				},
+				"excludeAppointmentId": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: "An appointment being rescheduled, which does not conflict with itself",
+				},
+				"serviceIds": &graphql.ArgumentConfig{
+					Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+					Description: "The services to perform, whose rooms, chairs and equipment must be free too",
+				},
+			},
+			Resolve: resolver.resolveCheckAvailability,
+		},
+	}
+}
+
+// appointmentMutationFields returns the appointment mutations
+func appointmentMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"createAppointment": &graphql.Field{
+			Type:        AppointmentType,
+			Description: "Book an appointment; fails when the staff member is not available",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(CreateAppointmentInput),
+					Description: "The appointment data",
+				},
+			},
+			Resolve: resolver.resolveCreateAppointment,
+		},
+		"rescheduleAppointment": &graphql.Field{
+			Type:        AppointmentType,
+			Description: "Move an appointment to another time; fails when the staff member is not available",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the appointment",
+				},
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(RescheduleAppointmentInput),
+					Description: "The new time",
+				},
+			},
+			Resolve: resolver.resolveRescheduleAppointment,
+		},
+		"acceptAppointment": &graphql.Field{
+			Type:        AppointmentType,
+			Description: "Accept an appointment a client requested online",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the pending appointment",
+				},
+			},
+			Resolve: resolver.resolveAcceptAppointment,
+		},
+		"cancelAppointment": &graphql.Field{
+			Type:        AppointmentType,
+			Description: "Cancel an appointment that has not taken place yet; with less notice than the business's cancellation policy asks for, it counts as a no-show",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the pending, scheduled or confirmed appointment",
+				},
+				"reason": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: "Why the appointment was cancelled",
+				},
+				"expectedVersion": &graphql.ArgumentConfig{
+					Type:        graphql.Int,
+					Description: "The version of the appointment the cancellation is based on; fails with VERSION_CONFLICT when someone else changed it since",
+				},
+				"waiveNoShowPolicy": &graphql.ArgumentConfig{
+					Type:        graphql.Boolean,
+					Description: "Cancel without counting a late cancellation as a no-show (owners and managers)",
+				},
+			},
+			Resolve: resolver.resolveCancelAppointment,
+		},
+		"markNoShow": &graphql.Field{
+			Type:        AppointmentType,
+			Description: "Record that the client of an appointment that has started did not turn up, charging the business's no-show fee",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the scheduled or confirmed appointment",
+				},
+			},
+			Resolve: resolver.resolveMarkNoShow,
+		},
+	}
+}
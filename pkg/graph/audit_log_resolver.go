@@ -0,0 +1,23 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Audit Log Query Resolvers
+func (r *Resolver) resolveAuditLog(p graphql.ResolveParams) (any, error) {
+	var filter dto.AuditLogFilterDTO
+	if err := decodeValue(p.Args["filter"], &filter, "filter"); err != nil {
+		return nil, err
+	}
+	page, pageSize := pageFromArgs(p.Args, 50)
+
+	entries, _, err := r.auditLogService.ListAuditLog(p.Context, filter, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,127 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+)
+
+// AuditActionEnum represents the GraphQL AuditAction enum
+var AuditActionEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "AuditAction",
+	Description: "The kind of change an audit log entry records",
+	Values: graphql.EnumValueConfigMap{
+		"CREATE": &graphql.EnumValueConfig{
+			Value:       "create",
+			Description: "The record was created",
+		},
+		"UPDATE": &graphql.EnumValueConfig{
+			Value:       "update",
+			Description: "Fields of the record were changed",
+		},
+		"DELETE": &graphql.EnumValueConfig{
+			Value:       "delete",
+			Description: "The record was deleted",
+		},
+		"RESTORE": &graphql.EnumValueConfig{
+			Value:       "restore",
+			Description: "The deleted record was restored",
+		},
+		"EXPORT": &graphql.EnumValueConfig{
+			Value:       "export",
+			Description: "The record's data was exported; the entry tells how the export was encrypted",
+		},
+	},
+})
+
+// AuditLogEntryType represents the GraphQL AuditLogEntry type
+var AuditLogEntryType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "AuditLogEntry",
+	Description: "A change made to a record of a business",
+	Fields: graphql.Fields{
+		"id": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the entry",
+		},
+		"businessId": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The ID of the business the record belongs to",
+		},
+		"entityType": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The kind of record changed, e.g. appointments",
+		},
+		"entityId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the record changed",
+		},
+		"action": &graphql.Field{
+			Type:        graphql.NewNonNull(AuditActionEnum),
+			Description: "Whether the record was created, changed, deleted or restored",
+		},
+		"changes": &graphql.Field{
+			Type:        graphql.NewNonNull(JSONScalar),
+			Description: "The fields changed, each with its value before (from) and after (to); secrets are redacted",
+		},
+		"actorId": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The ID of the user who made the change; null for changes made by the system",
+		},
+		"occurredAt": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the change was made",
+		},
+	},
+})
+
+// AuditLogFilterInput represents the GraphQL input for browsing the audit log
+var AuditLogFilterInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "AuditLogFilterInput",
+	Description: "Criteria for browsing the audit log of a business",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"businessId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"entityType": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "Only changes to this kind of record, e.g. appointments",
+		},
+		"entityId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "Only changes to this record",
+		},
+		"actorId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "Only changes made by this user",
+		},
+		"action": &graphql.InputObjectFieldConfig{
+			Type:        AuditActionEnum,
+			Description: "Only changes of this kind",
+		},
+		"startDate": &graphql.InputObjectFieldConfig{
+			Type:        graphql.DateTime,
+			Description: "Only changes made from this time",
+		},
+		"endDate": &graphql.InputObjectFieldConfig{
+			Type:        graphql.DateTime,
+			Description: "Only changes made before this time",
+		},
+	},
+})
+
+// auditLogQueryFields returns the audit log queries
+func auditLogQueryFields(resolver *Resolver) graphql.Fields {
+	listArgs := paginationArgs("entries", 50)
+	listArgs["filter"] = &graphql.ArgumentConfig{
+		Type:        graphql.NewNonNull(AuditLogFilterInput),
+		Description: "The filter criteria",
+	}
+
+	return graphql.Fields{
+		"auditLog": &graphql.Field{
+			Type:        graphql.NewList(AuditLogEntryType),
+			Description: "Browse who changed what in a business, most recent first (business owners only)",
+			Args:        listArgs,
+			Resolve:     resolver.resolveAuditLog,
+		},
+	}
+}
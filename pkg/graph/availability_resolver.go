@@ -0,0 +1,42 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Availability Query Resolvers
+func (r *Resolver) resolveAvailableSlots(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+	serviceID, ok := p.Args["serviceId"].(string)
+	if !ok {
+		return nil, errors.New("serviceId is required")
+	}
+	startDate, ok := p.Args["startDate"].(string)
+	if !ok {
+		return nil, errors.New("startDate is required")
+	}
+	endDate, ok := p.Args["endDate"].(string)
+	if !ok {
+		return nil, errors.New("endDate is required")
+	}
+
+	slots, err := r.availabilityService.GetAvailableSlots(p.Context, dto.AvailableSlotsQueryDTO{
+		BusinessID: businessID,
+		ServiceID:  serviceID,
+		StaffID:    optionalString(p.Args, "staffId"),
+		StartDate:  startDate,
+		EndDate:    endDate,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return slots, nil
+}
@@ -0,0 +1,62 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// AvailableSlotType represents the GraphQL AvailableSlot type
+var AvailableSlotType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "AvailableSlot",
+	Description: "A time at which a staff member can take a service",
+	Fields: graphql.Fields{
+		"staffId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the staff member",
+		},
+		"staff": staffRelation(func(s *dto.AvailableSlotDTO) string { return s.StaffID }),
+		"startTime": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the appointment would start",
+		},
+		"endTime": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the appointment would end",
+		},
+	},
+})
+
+// availabilityQueryFields returns the available slot queries
+func availabilityQueryFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"availableSlots": &graphql.Field{
+			Type: graphql.NewList(AvailableSlotType),
+			Description: "Get the times at which a service can be booked, taking into account the business's working hours " +
+				"and buffer, staff time off, and existing appointments",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+				"serviceId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the service",
+				},
+				"staffId": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: "The ID of the staff member (any active staff member when omitted)",
+				},
+				"startDate": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The first date to search, as YYYY-MM-DD in the business's time zone",
+				},
+				"endDate": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The last date to search, as YYYY-MM-DD in the business's time zone (at most 31 days after startDate)",
+				},
+			},
+			Resolve: resolver.resolveAvailableSlots,
+		},
+	}
+}
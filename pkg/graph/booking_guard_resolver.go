@@ -0,0 +1,55 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Booking Guard Query Resolvers
+func (r *Resolver) resolveBookingBlocklist(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+
+	entries, err := r.bookingGuardService.ListBlocklist(p.Context, businessID)
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// Booking Guard Mutation Resolvers
+func (r *Resolver) resolveAddBlocklistEntry(p graphql.ResolveParams) (any, error) {
+	createDTO := dto.CreateBlocklistEntryDTO{}
+	if err := decodeInput(p.Args, &createDTO); err != nil {
+		return nil, err
+	}
+
+	entry, err := r.bookingGuardService.AddBlocklistEntry(p.Context, createDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+func (r *Resolver) resolveRemoveBlocklistEntry(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+
+	if err := r.bookingGuardService.RemoveBlocklistEntry(p.Context, id); err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		"success": true,
+		"message": "Blocklist entry removed successfully",
+	}, nil
+}
@@ -0,0 +1,132 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// BlocklistEntryTypeEnum represents the GraphQL enum for blocklist entry types
+var BlocklistEntryTypeEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "BlocklistEntryType",
+	Description: "What a blocklist entry matches",
+	Values: graphql.EnumValueConfigMap{
+		"PHONE": &graphql.EnumValueConfig{
+			Value:       domain.BlocklistPhone,
+			Description: "A phone number, matched on its digits",
+		},
+		"EMAIL": &graphql.EnumValueConfig{
+			Value:       domain.BlocklistEmail,
+			Description: "An email address, matched case-insensitively",
+		},
+		"CLIENT": &graphql.EnumValueConfig{
+			Value:       domain.BlocklistClient,
+			Description: "A client of the business, by ID",
+		},
+	},
+})
+
+// BlocklistEntryType represents the GraphQL BlocklistEntry type
+var BlocklistEntryType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "BlocklistEntry",
+	Description: "A phone number, email address or client that may not book online",
+	Fields: withBaseFields("blocklist entry", graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"entryType": &graphql.Field{
+			Type:        graphql.NewNonNull(BlocklistEntryTypeEnum),
+			Description: "What the entry matches",
+		},
+		"value": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The normalized phone digits, lower-cased email or client ID",
+		},
+		"reason": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Why the entry was added, for staff only",
+		},
+		"expiresAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the entry stops blocking bookings, null if never",
+		},
+		"isActive": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether the entry currently blocks bookings",
+		},
+	}),
+})
+
+// CreateBlocklistEntryInput represents the GraphQL input for adding a blocklist entry
+var CreateBlocklistEntryInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "CreateBlocklistEntryInput",
+	Description: "Input for blocking a phone number, email address or client from booking online",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"businessId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"entryType": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(BlocklistEntryTypeEnum),
+			Description: "What the entry matches",
+		},
+		"value": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The phone number, email address or client ID",
+		},
+		"reason": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "Why the entry is added, for staff only",
+		},
+		"expiresAt": &graphql.InputObjectFieldConfig{
+			Type:        graphql.DateTime,
+			Description: "When the entry stops blocking bookings; omit to block indefinitely",
+		},
+	},
+})
+
+// bookingGuardQueryFields returns the booking blocklist queries
+func bookingGuardQueryFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"bookingBlocklist": &graphql.Field{
+			Type:        graphql.NewList(BlocklistEntryType),
+			Description: "Get the phone numbers, email addresses and clients blocked from booking online with a business",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+			},
+			Resolve: resolver.resolveBookingBlocklist,
+		},
+	}
+}
+
+// bookingGuardMutationFields returns the booking blocklist mutations
+func bookingGuardMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"addBlocklistEntry": &graphql.Field{
+			Type:        BlocklistEntryType,
+			Description: "Block a phone number, email address or client from booking online",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(CreateBlocklistEntryInput),
+					Description: "The entry data",
+				},
+			},
+			Resolve: resolver.resolveAddBlocklistEntry,
+		},
+		"removeBlocklistEntry": &graphql.Field{
+			Type:        DeleteResultType,
+			Description: "Remove a blocklist entry",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the entry",
+				},
+			},
+			Resolve: resolver.resolveRemoveBlocklistEntry,
+		},
+	}
+}
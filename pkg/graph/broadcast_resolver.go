@@ -0,0 +1,76 @@
+package graph
+
+import (
+	"errors"
+	"time"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Broadcast Query Resolvers
+func (r *Resolver) resolvePreviewBroadcast(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+	windowStart, ok := p.Args["windowStart"].(time.Time)
+	if !ok {
+		return nil, errors.New("windowStart is required")
+	}
+	windowEnd, ok := p.Args["windowEnd"].(time.Time)
+	if !ok {
+		return nil, errors.New("windowEnd is required")
+	}
+
+	preview, err := r.broadcastService.PreviewBroadcast(p.Context, businessID, windowStart, windowEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	return preview, nil
+}
+
+func (r *Resolver) resolveBroadcast(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+
+	broadcast, err := r.broadcastService.GetBroadcast(p.Context, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return broadcast, nil
+}
+
+func (r *Resolver) resolveBroadcasts(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+
+	broadcasts, err := r.broadcastService.ListBroadcasts(p.Context, businessID)
+	if err != nil {
+		return nil, err
+	}
+
+	return broadcasts, nil
+}
+
+// Broadcast Mutation Resolvers
+func (r *Resolver) resolveSendBroadcast(p graphql.ResolveParams) (any, error) {
+	sendDTO := dto.SendBroadcastDTO{OfferReschedule: true}
+	if err := decodeInput(p.Args, &sendDTO); err != nil {
+		return nil, err
+	}
+
+	broadcast, err := r.broadcastService.SendBroadcast(p.Context, sendDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return broadcast, nil
+}
@@ -0,0 +1,236 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// BroadcastRecipientStatusEnum represents the GraphQL enum for broadcast recipient statuses
+var BroadcastRecipientStatusEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "BroadcastRecipientStatus",
+	Description: "Whether a client was reached by a broadcast",
+	Values: graphql.EnumValueConfigMap{
+		"PENDING": &graphql.EnumValueConfig{
+			Value:       domain.BroadcastRecipientPending,
+			Description: "The notice is being sent",
+		},
+		"NOTIFIED": &graphql.EnumValueConfig{
+			Value:       domain.BroadcastRecipientNotified,
+			Description: "Delivered on at least one channel",
+		},
+		"FAILED": &graphql.EnumValueConfig{
+			Value:       domain.BroadcastRecipientFailed,
+			Description: "Every delivery attempt failed",
+		},
+		"UNREACHABLE": &graphql.EnumValueConfig{
+			Value:       domain.BroadcastRecipientUnreachable,
+			Description: "The client has no phone number or email address",
+		},
+	},
+})
+
+// BroadcastPreviewType represents the GraphQL BroadcastPreview type
+var BroadcastPreviewType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "BroadcastPreview",
+	Description: "Who a broadcast would reach",
+	Fields: graphql.Fields{
+		"appointmentCount": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The number of appointments in the window",
+		},
+		"reachableCount": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The number of appointments whose client has a phone number or email address",
+		},
+		"unreachableCount": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The number of appointments whose client has to be called",
+		},
+	},
+})
+
+// BroadcastRecipientType represents the GraphQL BroadcastRecipient type
+var BroadcastRecipientType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "BroadcastRecipient",
+	Description: "An appointment affected by a broadcast",
+	Fields: graphql.Fields{
+		"appointmentId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the appointment",
+		},
+		"clientId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the client",
+		},
+		"status": &graphql.Field{
+			Type:        graphql.NewNonNull(BroadcastRecipientStatusEnum),
+			Description: "Whether the client was reached",
+		},
+		"channels": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(MessageChannelEnum))),
+			Description: "The channels the notice was delivered on",
+		},
+		"error": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Why a delivery failed",
+		},
+		"rescheduledAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the client moved the appointment through their rescheduling link",
+		},
+	},
+})
+
+// BroadcastType represents the GraphQL Broadcast type
+var BroadcastType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Broadcast",
+	Description: "An urgent notice sent to every client with an appointment in a time window",
+	Fields: withBaseFields("broadcast", graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"subject": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The email subject",
+		},
+		"message": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The notice, which may use message template variables",
+		},
+		"windowStart": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "The start of the affected period",
+		},
+		"windowEnd": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "The end of the affected period",
+		},
+		"offerReschedule": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether clients were sent a link to pick a new time",
+		},
+		"appointmentCount": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The number of affected appointments",
+		},
+		"notifiedCount": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The number of appointments whose client was reached",
+		},
+		"failedCount": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The number of appointments whose client could not be reached",
+		},
+		"rescheduledCount": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The number of appointments clients have moved; only counted with recipients",
+		},
+		"sentAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the notice finished sending",
+		},
+		"recipients": &graphql.Field{
+			Type:        graphql.NewList(graphql.NewNonNull(BroadcastRecipientType)),
+			Description: "The affected appointments; only returned for a single broadcast",
+		},
+	}),
+})
+
+// SendBroadcastInput represents the GraphQL input for sending a broadcast
+var SendBroadcastInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "SendBroadcastInput",
+	Description: "Input for sending an urgent notice to the clients with appointments in a time window",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"businessId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"subject": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The email subject; defaults to an important notice from the business",
+		},
+		"message": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The notice; may use message template variables and {{reschedule.link}}",
+		},
+		"windowStart": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "The start of the affected period",
+		},
+		"windowEnd": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "The end of the affected period",
+		},
+		"offerReschedule": &graphql.InputObjectFieldConfig{
+			Type:         graphql.Boolean,
+			Description:  "Send each client a link to pick a new time",
+			DefaultValue: true,
+		},
+	},
+})
+
+// broadcastQueryFields returns the broadcast queries
+func broadcastQueryFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"previewBroadcast": &graphql.Field{
+			Type:        BroadcastPreviewType,
+			Description: "Count the appointments a broadcast for a time window would reach",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+				"windowStart": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.DateTime),
+					Description: "The start of the affected period",
+				},
+				"windowEnd": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.DateTime),
+					Description: "The end of the affected period",
+				},
+			},
+			Resolve: resolver.resolvePreviewBroadcast,
+		},
+		"broadcast": &graphql.Field{
+			Type:        BroadcastType,
+			Description: "Get a broadcast with its affected appointments",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the broadcast",
+				},
+			},
+			Resolve: resolver.resolveBroadcast,
+		},
+		"broadcasts": &graphql.Field{
+			Type:        graphql.NewList(BroadcastType),
+			Description: "Get the broadcasts of a business, latest first",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+			},
+			Resolve: resolver.resolveBroadcasts,
+		},
+	}
+}
+
+// broadcastMutationFields returns the broadcast mutations
+func broadcastMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"sendBroadcast": &graphql.Field{
+			Type:        BroadcastType,
+			Description: "Message every client with an appointment in a time window on all their channels and mark the appointments as affected",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(SendBroadcastInput),
+					Description: "The notice",
+				},
+			},
+			Resolve: resolver.resolveSendBroadcast,
+		},
+	}
+}
@@ -0,0 +1,57 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Business Hours Query Resolvers
+func (r *Resolver) resolveBusinessHours(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+
+	hours, err := r.businessHoursService.GetBusinessHours(p.Context, businessID)
+	if err != nil {
+		return nil, err
+	}
+
+	return hours, nil
+}
+
+// Business Hours Mutation Resolvers
+func (r *Resolver) resolveSetBusinessHours(p graphql.ResolveParams) (any, error) {
+	var setDTO dto.SetBusinessHoursDTO
+	if err := decodeInput(p.Args, &setDTO); err != nil {
+		return nil, err
+	}
+
+	hours, err := r.businessHoursService.SetBusinessHours(p.Context, setDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return hours, nil
+}
+
+func (r *Resolver) resolveClearBusinessHours(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+	var locationID *string
+	if id, ok := p.Args["locationId"].(string); ok {
+		locationID = &id
+	}
+
+	hours, err := r.businessHoursService.ClearBusinessHours(p.Context, businessID, locationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return hours, nil
+}
@@ -0,0 +1,172 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+)
+
+// weekdays are the days of the week, as named by the fields of the opening hours types
+var weekdays = []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"}
+
+// DayHoursType represents the GraphQL DayHours type
+var DayHoursType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "DayHours",
+	Description: "The opening hours of one day of the week",
+	Fields: graphql.Fields{
+		"isOpen": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether the business is open on the day",
+		},
+		"openTime": &graphql.Field{
+			Type:        graphql.String,
+			Description: "When it opens, as HH:MM",
+		},
+		"closeTime": &graphql.Field{
+			Type:        graphql.String,
+			Description: "When it closes, as HH:MM, or 24:00 for midnight",
+		},
+	},
+})
+
+// WeeklyHoursType represents the GraphQL WeeklyHours type
+var WeeklyHoursType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "WeeklyHours",
+	Description: "The opening hours of each day of the week; days without any are closed",
+	Fields: func() graphql.Fields {
+		fields := graphql.Fields{}
+		for _, day := range weekdays {
+			fields[day] = &graphql.Field{Type: DayHoursType, Description: "The opening hours on " + day}
+		}
+		return fields
+	}(),
+})
+
+// OpeningHoursType represents the GraphQL OpeningHours type
+var OpeningHoursType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "OpeningHours",
+	Description: "The opening hours of a business or of one of its locations",
+	Fields: graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"locationId": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The ID of the location; null for the hours of the whole business",
+		},
+		"locationName": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The name of the location",
+		},
+		"hours": &graphql.Field{
+			Type:        graphql.NewNonNull(WeeklyHoursType),
+			Description: "The opening hours in effect",
+		},
+		"inherited": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether the location has no hours of its own and keeps the business's",
+		},
+		"configured": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether any hours are set; without them, the business is open during its calendar hours every day",
+		},
+	},
+})
+
+// DayHoursInput represents the GraphQL input for the opening hours of one day of the week
+var DayHoursInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "DayHoursInput",
+	Description: "Input for the opening hours of one day of the week",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"isOpen": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether the business is open on the day",
+		},
+		"openTime": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "When it opens, as HH:MM; required on open days",
+		},
+		"closeTime": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "When it closes, as HH:MM, or 24:00 for midnight; required on open days",
+		},
+	},
+})
+
+// SetBusinessHoursInput represents the GraphQL input for replacing opening hours
+var SetBusinessHoursInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "SetBusinessHoursInput",
+	Description: "Input for replacing the opening hours of a business or of one of its locations; days left out are closed",
+	Fields: func() graphql.InputObjectConfigFieldMap {
+		fields := graphql.InputObjectConfigFieldMap{
+			"businessId": &graphql.InputObjectFieldConfig{
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "The ID of the business",
+			},
+			"locationId": &graphql.InputObjectFieldConfig{
+				Type:        graphql.String,
+				Description: "The ID of the location; leave out for the hours of the whole business",
+			},
+		}
+		hours := graphql.InputObjectConfigFieldMap{}
+		for _, day := range weekdays {
+			hours[day] = &graphql.InputObjectFieldConfig{Type: DayHoursInput, Description: "The opening hours on " + day}
+		}
+		fields["hours"] = &graphql.InputObjectFieldConfig{
+			Type: graphql.NewNonNull(graphql.NewInputObject(graphql.InputObjectConfig{
+				Name:        "WeeklyHoursInput",
+				Description: "Input for the opening hours of each day of the week",
+				Fields:      hours,
+			})),
+			Description: "The opening hours of each day",
+		}
+		return fields
+	}(),
+})
+
+// businessHoursQueryFields returns the opening hours queries
+func businessHoursQueryFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"businessHours": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(OpeningHoursType))),
+			Description: "Get the opening hours of a business, then those of each of its locations (staff of the business only)",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+			},
+			Resolve: resolver.resolveBusinessHours,
+		},
+	}
+}
+
+// businessHoursMutationFields returns the opening hours mutations
+func businessHoursMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"setBusinessHours": &graphql.Field{
+			Type:        graphql.NewNonNull(OpeningHoursType),
+			Description: "Replace the opening hours of a business or of one of its locations (owners and managers only). Bookings outside the business's hours are rejected; use updateSchedulingSettings with dryRun to list the appointments a change would invalidate.",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type: graphql.NewNonNull(SetBusinessHoursInput),
+				},
+			},
+			Resolve: resolver.resolveSetBusinessHours,
+		},
+		"clearBusinessHours": &graphql.Field{
+			Type:        graphql.NewNonNull(OpeningHoursType),
+			Description: "Remove the opening hours of a location, which then keeps the business's, or of the business, which is then open during its calendar hours every day (owners and managers only)",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+				"locationId": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: "The ID of the location; leave out for the hours of the whole business",
+				},
+			},
+			Resolve: resolver.resolveClearBusinessHours,
+		},
+	}
+}
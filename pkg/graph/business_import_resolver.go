@@ -0,0 +1,67 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Business Import Query Resolvers
+func (r *Resolver) resolveImportBatches(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+
+	batches, err := r.businessImportService.ListImportBatches(p.Context, businessID)
+	if err != nil {
+		return nil, err
+	}
+
+	return batches, nil
+}
+
+// Business Import Mutation Resolvers
+func (r *Resolver) resolveImportServices(p graphql.ResolveParams) (any, error) {
+	importDTO := dto.BusinessImportDTO{}
+	if err := decodeInput(p.Args, &importDTO); err != nil {
+		return nil, err
+	}
+
+	result, err := r.businessImportService.ImportServices(p.Context, importDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *Resolver) resolveImportStaff(p graphql.ResolveParams) (any, error) {
+	importDTO := dto.BusinessImportDTO{}
+	if err := decodeInput(p.Args, &importDTO); err != nil {
+		return nil, err
+	}
+
+	result, err := r.businessImportService.ImportStaff(p.Context, importDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *Resolver) resolveRollBackImportBatch(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+
+	batch, err := r.businessImportService.RollBackImportBatch(p.Context, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return batch, nil
+}
@@ -0,0 +1,282 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// ImportRowStatusEnum represents the GraphQL enum for the outcome of a row of a service or staff import
+var ImportRowStatusEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "ImportRowStatus",
+	Description: "The outcome of a row of a service or staff import",
+	Values: graphql.EnumValueConfigMap{
+		"READY": &graphql.EnumValueConfig{
+			Value:       domain.ImportRowReady,
+			Description: "Valid, but not imported because of a dry run or an invalid row elsewhere in the file",
+		},
+		"IMPORTED": &graphql.EnumValueConfig{
+			Value:       domain.ImportRowImported,
+			Description: "Created",
+		},
+		"DUPLICATE": &graphql.EnumValueConfig{
+			Value:       domain.ImportRowDuplicate,
+			Description: "Skipped because it matches an existing record or an earlier row",
+		},
+		"INVALID": &graphql.EnumValueConfig{
+			Value:       domain.ImportRowInvalid,
+			Description: "Rejected; see the errors",
+		},
+	},
+})
+
+// ImportKindEnum represents the GraphQL enum for what an import created
+var ImportKindEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "ImportKind",
+	Description: "What an import created",
+	Values: graphql.EnumValueConfigMap{
+		"SERVICES": &graphql.EnumValueConfig{
+			Value:       domain.ImportKindServices,
+			Description: "Service categories and services",
+		},
+		"STAFF": &graphql.EnumValueConfig{
+			Value:       domain.ImportKindStaff,
+			Description: "Staff members",
+		},
+	},
+})
+
+// ImportBatchStatusEnum represents the GraphQL enum for the state of an import batch
+var ImportBatchStatusEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "ImportBatchStatus",
+	Description: "The state of an import batch",
+	Values: graphql.EnumValueConfigMap{
+		"IMPORTED": &graphql.EnumValueConfig{
+			Value:       domain.ImportBatchImported,
+			Description: "The records are in place",
+		},
+		"ROLLED_BACK": &graphql.EnumValueConfig{
+			Value:       domain.ImportBatchRolledBack,
+			Description: "The records were removed",
+		},
+	},
+})
+
+// BusinessImportRowType represents the GraphQL BusinessImportRow type
+var BusinessImportRowType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "BusinessImportRow",
+	Description: "The outcome of a row of a service or staff import",
+	Fields: graphql.Fields{
+		"line": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The line of the file, counting the header as line 1",
+		},
+		"status": &graphql.Field{
+			Type:        graphql.NewNonNull(ImportRowStatusEnum),
+			Description: "The outcome of the row",
+		},
+		"name": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The service's name or the staff member's full name",
+		},
+		"detail": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The service's category or the staff member's email address",
+		},
+		"errors": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+			Description: "Why the row is invalid",
+		},
+		"recordId": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The ID of the created service or staff member",
+		},
+		"duplicateOfId": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The existing service or staff member the row duplicates",
+		},
+		"duplicateOfLine": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "The earlier line of the file the row duplicates",
+		},
+	},
+})
+
+// BusinessImportResultType represents the GraphQL BusinessImportResult type
+var BusinessImportResultType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "BusinessImportResult",
+	Description: "The outcome of a service or staff import, row by row",
+	Fields: graphql.Fields{
+		"batchId": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The import batch recording the records created, to roll them back",
+		},
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"kind": &graphql.Field{
+			Type:        graphql.NewNonNull(ImportKindEnum),
+			Description: "What was imported",
+		},
+		"dryRun": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether the import only validated the file",
+		},
+		"totalRows": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The number of rows in the file",
+		},
+		"imported": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The number of rows imported",
+		},
+		"ready": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The number of valid rows that were not imported",
+		},
+		"duplicates": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The number of rows skipped as duplicates",
+		},
+		"invalid": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The number of invalid rows",
+		},
+		"rows": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(BusinessImportRowType))),
+			Description: "The outcome of every row",
+		},
+	},
+})
+
+// ImportBatchType represents the GraphQL ImportBatch type
+var ImportBatchType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ImportBatch",
+	Description: "An import of services or staff into a business, which can be rolled back",
+	Fields: withBaseFields("import batch", graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"kind": &graphql.Field{
+			Type:        graphql.NewNonNull(ImportKindEnum),
+			Description: "What was imported",
+		},
+		"status": &graphql.Field{
+			Type:        graphql.NewNonNull(ImportBatchStatusEnum),
+			Description: "Whether the import was rolled back",
+		},
+		"totalRows": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The number of rows in the file",
+		},
+		"imported": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The number of rows imported",
+		},
+		"createdBy": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The user who imported the file",
+		},
+		"rolledBackAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the import was rolled back",
+		},
+		"rolledBackBy": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The user who rolled the import back",
+		},
+	}),
+})
+
+// importBusinessDataInput creates the GraphQL input for importing a CSV file of services or staff
+func importBusinessDataInput(name, noun, columns string) *graphql.InputObject {
+	return graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:        name,
+		Description: "Input for importing " + noun + " from a CSV file",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"businessId": &graphql.InputObjectFieldConfig{
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "The ID of the business",
+			},
+			"csv": &graphql.InputObjectFieldConfig{
+				Type: graphql.NewNonNull(graphql.String),
+				Description: "The content of the CSV file, at most 5 MB, with a header row. Columns are matched by name: " +
+					columns + ". Commas, semicolons and tabs are accepted as separators.",
+			},
+			"dryRun": &graphql.InputObjectFieldConfig{
+				Type:         graphql.Boolean,
+				Description:  "Validate the file and report what would be imported without creating any " + noun,
+				DefaultValue: false,
+			},
+		},
+	})
+}
+
+// ImportServicesInput represents the GraphQL input for importing the service menu
+var ImportServicesInput = importBusinessDataInput("ImportServicesInput", "services",
+	"category, name, description, duration (minutes, \"1h30\" or \"1:30\"), price, preparation time and cleanup time")
+
+// ImportStaffInput represents the GraphQL input for importing the staff roster
+var ImportStaffInput = importBusinessDataInput("ImportStaffInput", "staff",
+	"first name, last name (or full name), email, phone, role (manager, employee or assistant) and start date")
+
+// businessImportQueryFields returns the business import queries
+func businessImportQueryFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"importBatches": &graphql.Field{
+			Type:        graphql.NewList(ImportBatchType),
+			Description: "Get the latest service and staff imports of a business, latest first (managers only)",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+			},
+			Resolve: resolver.resolveImportBatches,
+		},
+	}
+}
+
+// businessImportMutationFields returns the business import mutations
+func businessImportMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"importServices": &graphql.Field{
+			Type: BusinessImportResultType,
+			Description: "Import service categories and services from a CSV file, skipping services whose name exists " +
+				"(managers only). Nothing is imported while any row is invalid.",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(ImportServicesInput),
+					Description: "The file to import",
+				},
+			},
+			Resolve: resolver.resolveImportServices,
+		},
+		"importStaff": &graphql.Field{
+			Type: BusinessImportResultType,
+			Description: "Import staff members from a CSV file, skipping emails already on the staff (managers only). " +
+				"Nothing is imported while any row is invalid.",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(ImportStaffInput),
+					Description: "The file to import",
+				},
+			},
+			Resolve: resolver.resolveImportStaff,
+		},
+		"rollBackImportBatch": &graphql.Field{
+			Type: ImportBatchType,
+			Description: "Remove everything an import created (managers only). Imports whose services or staff have " +
+				"appointments cannot be rolled back.",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the import batch",
+				},
+			},
+			Resolve: resolver.resolveRollBackImportBatch,
+		},
+	}
+}
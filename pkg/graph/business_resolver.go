@@ -0,0 +1,71 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Business Mutation Resolvers
+func (r *Resolver) resolveCreateBusiness(p graphql.ResolveParams) (any, error) {
+	createDTO := dto.CreateBusinessDTO{}
+	if err := decodeInput(p.Args, &createDTO); err != nil {
+		return nil, err
+	}
+
+	business, err := r.businessService.CreateBusiness(p.Context, createDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return business, nil
+}
+
+func (r *Resolver) resolveSetBusinessMode(p graphql.ResolveParams) (any, error) {
+	modeDTO := dto.SetBusinessModeDTO{}
+	if businessID, ok := p.Args["businessId"].(string); ok {
+		modeDTO.BusinessID = businessID
+	}
+	if mode, ok := p.Args["mode"].(domain.BusinessMode); ok {
+		modeDTO.Mode = mode
+	}
+
+	business, err := r.businessService.SetBusinessMode(p.Context, modeDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return business, nil
+}
+
+func (r *Resolver) resolveSetBusinessTemplate(p graphql.ResolveParams) (any, error) {
+	templateDTO := dto.SetBusinessTemplateDTO{}
+	if businessID, ok := p.Args["businessId"].(string); ok {
+		templateDTO.BusinessID = businessID
+	}
+	if isTemplate, ok := p.Args["isTemplate"].(bool); ok {
+		templateDTO.IsTemplate = isTemplate
+	}
+
+	business, err := r.businessService.SetBusinessTemplate(p.Context, templateDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return business, nil
+}
+
+func (r *Resolver) resolveCloneBusiness(p graphql.ResolveParams) (any, error) {
+	cloneDTO := dto.CloneBusinessDTO{}
+	if err := decodeInput(p.Args, &cloneDTO); err != nil {
+		return nil, err
+	}
+
+	clone, err := r.businessService.CloneBusiness(p.Context, cloneDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return clone, nil
+}
@@ -0,0 +1,174 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// BusinessModeEnum represents the GraphQL enum for the way a business is run
+var BusinessModeEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "BusinessMode",
+	Description: "The way a business is run",
+	Values: graphql.EnumValueConfigMap{
+		"TEAM": &graphql.EnumValueConfig{
+			Value:       domain.BusinessModeTeam,
+			Description: "A salon with a team of staff members",
+		},
+		"SOLO": &graphql.EnumValueConfig{
+			Value:       domain.BusinessModeSolo,
+			Description: "A professional working on their own, with a single calendar and no staff management",
+		},
+	},
+})
+
+// CapabilityEnum represents the GraphQL enum for the parts of the app only some businesses use
+var CapabilityEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "Capability",
+	Description: "A part of the app only some businesses use",
+	Values: graphql.EnumValueConfigMap{
+		"STAFF_MANAGEMENT": &graphql.EnumValueConfig{
+			Value:       domain.CapabilityStaffManagement,
+			Description: "Managing other staff members, their roles and their pay",
+		},
+		"STAFF_CALENDARS": &graphql.EnumValueConfig{
+			Value:       domain.CapabilityStaffCalendars,
+			Description: "A calendar per staff member, with each booking assigned to one",
+		},
+	},
+})
+
+// CloneBusinessInput represents the GraphQL input for creating a business from a template
+var CloneBusinessInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "CloneBusinessInput",
+	Description: "Input for creating a business from the configuration of another",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"templateBusinessId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business to clone, which the caller must manage",
+		},
+		"name": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The name of the new business",
+		},
+		"displayName": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The display name of the new business",
+		},
+		"email": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The email address of the new business; the template's when omitted",
+		},
+		"timeZone": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The time zone of the new business; the template's when omitted",
+		},
+		"copyStaffRoles": &graphql.InputObjectFieldConfig{
+			Type:         graphql.Boolean,
+			DefaultValue: false,
+			Description:  "Give the template's active staff the same roles and permissions in the new business",
+		},
+	},
+})
+
+// BusinessCloneType represents the GraphQL BusinessClone type
+var BusinessCloneType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "BusinessClone",
+	Description: "A business created from a template and what it copied; clients and appointments are never copied",
+	Fields: graphql.Fields{
+		"business": &graphql.Field{
+			Type:        graphql.NewNonNull(BusinessType),
+			Description: "The new business, owned by the caller",
+		},
+		"templateBusinessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business cloned",
+		},
+		"categories": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "How many service categories were copied",
+		},
+		"services": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "How many services were copied",
+		},
+		"bundles": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "How many service bundles were copied",
+		},
+		"messageTemplates": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "How many message templates were copied",
+		},
+		"loyaltyPrograms": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "How many loyalty programs were copied",
+		},
+		"staffRoles": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "How many staff members were given their role in the new business, besides the owner",
+		},
+		"settingsCopied": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether the template's settings were copied; the new business has the defaults otherwise",
+		},
+	},
+})
+
+// businessMutationFields returns the business mutations
+func businessMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"createBusiness": &graphql.Field{
+			Type:        BusinessType,
+			Description: "Create a business owned by the current user, who becomes its first staff member",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(CreateBusinessInput),
+					Description: "The business",
+				},
+			},
+			Resolve: resolver.resolveCreateBusiness,
+		},
+		"setBusinessMode": &graphql.Field{
+			Type:        BusinessType,
+			Description: "Change the way a business is run (owner only); a business can only go solo once the owner is its only active staff member",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+				"mode": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(BusinessModeEnum),
+					Description: "The new mode",
+				},
+			},
+			Resolve: resolver.resolveSetBusinessMode,
+		},
+		"setBusinessTemplate": &graphql.Field{
+			Type:        BusinessType,
+			Description: "Mark a business as a template to clone new businesses from, which clients cannot book, or back (owner only)",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+				"isTemplate": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.Boolean),
+					Description: "Whether the business is a template",
+				},
+			},
+			Resolve: resolver.resolveSetBusinessTemplate,
+		},
+		"cloneBusiness": &graphql.Field{
+			Type:        BusinessCloneType,
+			Description: "Create a business owned by the current user from the service menu, settings, message templates, loyalty programs and optionally staff roles of another, all at once",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(CloneBusinessInput),
+					Description: "The business to clone and what the new one does not take from it",
+				},
+			},
+			Resolve: resolver.resolveCloneBusiness,
+		},
+	}
+}
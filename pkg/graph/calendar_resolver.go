@@ -0,0 +1,45 @@
+package graph
+
+import (
+	"errors"
+	"time"
+
+	"github.com/graphql-go/graphql"
+)
+
+// Calendar Query Resolvers
+func (r *Resolver) resolveCalendar(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+	startDate, ok := p.Args["startDate"].(time.Time)
+	if !ok {
+		return nil, errors.New("startDate is required")
+	}
+	endDate, ok := p.Args["endDate"].(time.Time)
+	if !ok {
+		return nil, errors.New("endDate is required")
+	}
+
+	entries, err := r.calendarService.GetCalendar(p.Context, businessID, startDate, endDate, optionalString(p.Args, "staffId"))
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// Calendar Mutation Resolvers
+func (r *Resolver) resolveRebuildCalendarProjection(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+
+	if err := r.calendarService.RebuildProjection(p.Context, businessID); err != nil {
+		return nil, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,115 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+)
+
+// CalendarEntryType represents the GraphQL CalendarEntry type
+var CalendarEntryType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "CalendarEntry",
+	Description: "An appointment as shown on the calendar",
+	Fields: graphql.Fields{
+		"appointmentId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the appointment",
+		},
+		"staffId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the staff member",
+		},
+		"clientId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the client",
+		},
+		"calendarDate": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "The local date of the appointment in the business time zone",
+		},
+		"startTime": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the appointment starts",
+		},
+		"endTime": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the appointment ends",
+		},
+		"status": &graphql.Field{
+			Type:        graphql.NewNonNull(AppointmentStatusEnum),
+			Description: "The status of the appointment",
+		},
+		"clientName": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The client's full name",
+		},
+		"clientPhone": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The client's phone number",
+		},
+		"staffName": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The staff member's full name",
+		},
+		"serviceNames": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+			Description: "The names of the booked services",
+		},
+		"totalDuration": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The total duration in minutes",
+		},
+		"price": &graphql.Field{
+			Type:        DecimalScalar,
+			Description: "The appointment price",
+		},
+		"notes": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Notes about the appointment",
+		},
+	},
+})
+
+// calendarQueryFields returns the calendar queries
+func calendarQueryFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"calendar": &graphql.Field{
+			Type:        graphql.NewList(CalendarEntryType),
+			Description: "Get a business's appointments for a calendar view, ordered by day, staff member and time",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+				"startDate": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.DateTime),
+					Description: "The first local date to include",
+				},
+				"endDate": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.DateTime),
+					Description: "The last local date to include (at most 42 days after startDate)",
+				},
+				"staffId": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: "Only appointments of this staff member",
+				},
+			},
+			Resolve: resolver.resolveCalendar,
+		},
+	}
+}
+
+// calendarMutationFields returns the calendar mutations
+func calendarMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"rebuildCalendarProjection": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Rebuild a business's calendar read model from its appointments (platform admins only)",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+			},
+			Resolve: resolver.resolveRebuildCalendarProjection,
+		},
+	}
+}
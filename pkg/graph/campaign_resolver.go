@@ -0,0 +1,81 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Campaign Query Resolvers
+func (r *Resolver) resolveCampaign(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+
+	campaign, err := r.campaignService.GetCampaign(p.Context, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return campaign, nil
+}
+
+func (r *Resolver) resolvePreviewAudience(p graphql.ResolveParams) (any, error) {
+	previewDTO := dto.PreviewAudienceDTO{}
+	if err := decodeInput(p.Args, &previewDTO); err != nil {
+		return nil, err
+	}
+
+	preview, err := r.campaignService.PreviewAudience(p.Context, previewDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return preview, nil
+}
+
+func (r *Resolver) resolveCampaignAnalytics(p graphql.ResolveParams) (any, error) {
+	campaignID, ok := p.Args["campaignId"].(string)
+	if !ok {
+		return nil, errors.New("campaignId is required")
+	}
+
+	analytics, err := r.campaignService.GetCampaignAnalytics(p.Context, campaignID, optionalTime(p.Args, "start"), optionalTime(p.Args, "end"))
+	if err != nil {
+		return nil, err
+	}
+
+	return analytics, nil
+}
+
+// Campaign Mutation Resolvers
+func (r *Resolver) resolveCreateCampaign(p graphql.ResolveParams) (any, error) {
+	createDTO := dto.CreateCampaignDTO{}
+	if err := decodeInput(p.Args, &createDTO); err != nil {
+		return nil, err
+	}
+
+	campaign, err := r.campaignService.CreateCampaign(p.Context, createDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return campaign, nil
+}
+
+func (r *Resolver) resolveTargetCampaignClients(p graphql.ResolveParams) (any, error) {
+	campaignID, ok := p.Args["campaignId"].(string)
+	if !ok {
+		return nil, errors.New("campaignId is required")
+	}
+
+	targeting, err := r.campaignService.TargetClients(p.Context, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	return targeting, nil
+}
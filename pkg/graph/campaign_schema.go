@@ -0,0 +1,447 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// CampaignTypeEnum represents the GraphQL enum for campaign types
+var CampaignTypeEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "CampaignType",
+	Description: "What a marketing campaign is for",
+	Values: graphql.EnumValueConfigMap{
+		"PROMOTION": &graphql.EnumValueConfig{
+			Value:       domain.CampaignPromotion,
+			Description: "Promotes services or offers",
+		},
+		"SEASONAL": &graphql.EnumValueConfig{
+			Value:       domain.CampaignSeasonal,
+			Description: "Runs for a season or holiday",
+		},
+		"REACTIVATION": &graphql.EnumValueConfig{
+			Value:       domain.CampaignReactivation,
+			Description: "Brings back clients who stopped visiting",
+		},
+		"BIRTHDAY": &graphql.EnumValueConfig{
+			Value:       domain.CampaignBirthday,
+			Description: "Celebrates clients' birthdays",
+		},
+	},
+})
+
+// TargetAudienceType represents the GraphQL TargetAudience type
+var TargetAudienceType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "TargetAudience",
+	Description: "The criteria selecting the clients a campaign targets; every criterion set must hold",
+	Fields: graphql.Fields{
+		"visitedWithinDays": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "Clients whose last visit was at most this many days ago",
+		},
+		"notVisitedForDays": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "Clients whose last visit was more than this many days ago",
+		},
+		"minVisits": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "The fewest completed visits",
+		},
+		"maxVisits": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "The most completed visits",
+		},
+		"minSpend": &graphql.Field{
+			Type:        DecimalScalar,
+			Description: "The least spent on completed visits",
+		},
+		"maxSpend": &graphql.Field{
+			Type:        DecimalScalar,
+			Description: "The most spent on completed visits",
+		},
+		"birthdayMonth": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "The month of the clients' birthday, 1 for January",
+		},
+		"tags": &graphql.Field{
+			Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+			Description: "Clients with any of the tags",
+		},
+		"loyaltyProgramId": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Members of the loyalty program",
+		},
+		"minLoyaltyPoints": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "The fewest points of the loyalty program members",
+		},
+		"loyaltyTier": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The tier of the loyalty program members",
+		},
+		"acceptsMarketing": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether only clients who accepted marketing messages are targeted",
+		},
+		"vipOnly": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether only VIP clients are targeted",
+		},
+	},
+})
+
+// CampaignType represents the GraphQL Campaign type
+var CampaignType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Campaign",
+	Description: "A marketing campaign of a business",
+	Fields: withBaseFields("campaign", graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"name": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The name of the campaign",
+		},
+		"description": &graphql.Field{
+			Type:        graphql.String,
+			Description: "What the campaign is about",
+		},
+		"campaignType": &graphql.Field{
+			Type:        graphql.NewNonNull(CampaignTypeEnum),
+			Description: "What the campaign is for",
+		},
+		"targetAudience": &graphql.Field{
+			Type:        graphql.NewNonNull(TargetAudienceType),
+			Description: "The criteria selecting the clients the campaign targets",
+		},
+		"offerType": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The kind of offer: discount, free_service, bundle or gift",
+		},
+		"offerDetails": &graphql.Field{
+			Type:        graphql.NewNonNull(JSONScalar),
+			Description: "The details of the offer",
+		},
+		"startDate": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the campaign starts",
+		},
+		"endDate": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the campaign ends",
+		},
+		"isActive": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether the campaign is active",
+		},
+		"messageTemplate": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The message sent to the targeted clients",
+		},
+		"targeted": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "How many clients the campaign targets so far",
+		},
+	}),
+})
+
+// AudiencePreviewType represents the GraphQL AudiencePreview type
+var AudiencePreviewType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "AudiencePreview",
+	Description: "How many clients an audience targets",
+	Fields: graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"clients": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The clients in the audience now",
+		},
+	},
+})
+
+// CampaignTargetingType represents the GraphQL CampaignTargeting type
+var CampaignTargetingType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "CampaignTargeting",
+	Description: "The outcome of adding the clients in a campaign's audience to it",
+	Fields: graphql.Fields{
+		"campaignId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the campaign",
+		},
+		"added": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The clients added this time",
+		},
+		"targeted": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The clients the campaign targets in total",
+		},
+	},
+})
+
+// CampaignAnalyticsType represents the GraphQL CampaignAnalytics type
+var CampaignAnalyticsType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "CampaignAnalytics",
+	Description: "How the clients targeted by a campaign responded to it within a period",
+	Fields: graphql.Fields{
+		"campaignId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the campaign",
+		},
+		"start": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "The start of the period",
+		},
+		"end": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "The end of the period",
+		},
+		"targeted": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "Clients added to the campaign in the period",
+		},
+		"sent": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "Clients sent the campaign in the period",
+		},
+		"opened": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "Clients who opened the campaign in the period",
+		},
+		"clicked": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "Clients who clicked through in the period",
+		},
+		"converted": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "Clients who converted in the period",
+		},
+		"unsubscribed": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "Clients who unsubscribed, whenever they did",
+		},
+		"openRate": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Float),
+			Description: "The share of sent messages that were opened, between 0 and 1",
+		},
+		"clickRate": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Float),
+			Description: "The share of sent messages that were clicked, between 0 and 1",
+		},
+		"conversionRate": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Float),
+			Description: "The share of sent messages that converted, between 0 and 1",
+		},
+		"visits": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "Visits converted clients completed in the period, on or after converting",
+		},
+		"revenue": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The amount charged for those visits",
+		},
+	},
+})
+
+// TargetAudienceInput represents the GraphQL input for a campaign's audience
+var TargetAudienceInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "TargetAudienceInput",
+	Description: "The criteria selecting the clients a campaign targets; every criterion set must hold",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"visitedWithinDays": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Int,
+			Description: "Clients whose last visit was at most this many days ago",
+		},
+		"notVisitedForDays": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Int,
+			Description: "Clients whose last visit was more than this many days ago; clients who never visited are left out",
+		},
+		"minVisits": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Int,
+			Description: "The fewest completed visits",
+		},
+		"maxVisits": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Int,
+			Description: "The most completed visits",
+		},
+		"minSpend": &graphql.InputObjectFieldConfig{
+			Type:        DecimalScalar,
+			Description: "The least spent on completed visits",
+		},
+		"maxSpend": &graphql.InputObjectFieldConfig{
+			Type:        DecimalScalar,
+			Description: "The most spent on completed visits",
+		},
+		"birthdayMonth": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Int,
+			Description: "The month of the clients' birthday, 1 for January",
+		},
+		"tags": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+			Description: "Clients with any of the tags",
+		},
+		"loyaltyProgramId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "Members of the loyalty program",
+		},
+		"minLoyaltyPoints": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Int,
+			Description: "The fewest points of the loyalty program members; requires loyaltyProgramId",
+		},
+		"loyaltyTier": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The tier of the loyalty program members; requires loyaltyProgramId",
+		},
+		"acceptsMarketing": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Boolean,
+			Description: "Only target clients who accepted marketing messages",
+		},
+		"vipOnly": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Boolean,
+			Description: "Only target VIP clients, as tagged by the business's VIP rules",
+		},
+	},
+})
+
+// CreateCampaignInput represents the GraphQL input for creating a campaign
+var CreateCampaignInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "CreateCampaignInput",
+	Description: "Input for creating a marketing campaign",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"businessId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"name": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The name of the campaign",
+		},
+		"description": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "What the campaign is about",
+		},
+		"campaignType": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(CampaignTypeEnum),
+			Description: "What the campaign is for",
+		},
+		"targetAudience": &graphql.InputObjectFieldConfig{
+			Type:        TargetAudienceInput,
+			Description: "The clients the campaign targets; every active client when omitted",
+		},
+		"offerType": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The kind of offer: discount, free_service, bundle or gift",
+		},
+		"offerDetails": &graphql.InputObjectFieldConfig{
+			Type:        JSONScalar,
+			Description: "The details of the offer",
+		},
+		"startDate": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the campaign starts",
+		},
+		"endDate": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the campaign ends",
+		},
+		"messageTemplate": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The message sent to the targeted clients",
+		},
+	},
+})
+
+// PreviewAudienceInput represents the GraphQL input for previewing an audience
+var PreviewAudienceInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "PreviewAudienceInput",
+	Description: "Input for counting the clients an audience would target",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"businessId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"targetAudience": &graphql.InputObjectFieldConfig{
+			Type:        TargetAudienceInput,
+			Description: "The audience; every active client when omitted",
+		},
+	},
+})
+
+// campaignQueryFields returns the campaign queries
+func campaignQueryFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"campaign": &graphql.Field{
+			Type:        CampaignType,
+			Description: "Get a campaign by ID",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the campaign",
+				},
+			},
+			Resolve: resolver.resolveCampaign,
+		},
+		"previewAudience": &graphql.Field{
+			Type:        AudiencePreviewType,
+			Description: "Count the clients an audience would target now",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(PreviewAudienceInput),
+					Description: "The audience",
+				},
+			},
+			Resolve: resolver.resolvePreviewAudience,
+		},
+		"campaignAnalytics": &graphql.Field{
+			Type:        CampaignAnalyticsType,
+			Description: "Get how the clients targeted by a campaign responded to it within a period",
+			Args: graphql.FieldConfigArgument{
+				"campaignId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the campaign",
+				},
+				"start": &graphql.ArgumentConfig{
+					Type:        graphql.DateTime,
+					Description: "The start of the period; defaults to when the campaign was created",
+				},
+				"end": &graphql.ArgumentConfig{
+					Type:        graphql.DateTime,
+					Description: "The end of the period; defaults to now",
+				},
+			},
+			Resolve: resolver.resolveCampaignAnalytics,
+		},
+	}
+}
+
+// campaignMutationFields returns the campaign mutations
+func campaignMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"createCampaign": &graphql.Field{
+			Type:        CampaignType,
+			Description: "Create a marketing campaign",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(CreateCampaignInput),
+					Description: "The campaign",
+				},
+			},
+			Resolve: resolver.resolveCreateCampaign,
+		},
+		"targetCampaignClients": &graphql.Field{
+			Type: CampaignTargetingType,
+			Description: "Add the clients in a campaign's audience to it. Running it again adds clients who have since " +
+				"joined the audience.",
+			Args: graphql.FieldConfigArgument{
+				"campaignId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the campaign",
+				},
+			},
+			Resolve: resolver.resolveTargetCampaignClients,
+		},
+	}
+}
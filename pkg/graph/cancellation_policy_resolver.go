@@ -0,0 +1,53 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Cancellation Policy Query Resolvers
+func (r *Resolver) resolveCancellationPolicy(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+
+	policy, err := r.cancellationPolicyService.GetCancellationPolicy(p.Context, businessID)
+	if err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// Cancellation Policy Mutation Resolvers
+func (r *Resolver) resolveSetCancellationPolicy(p graphql.ResolveParams) (any, error) {
+	policyDTO := dto.SetCancellationPolicyDTO{}
+	if err := decodeInput(p.Args, &policyDTO); err != nil {
+		return nil, err
+	}
+
+	policy, err := r.cancellationPolicyService.SetCancellationPolicy(p.Context, policyDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+func (r *Resolver) resolveClearClientNoShows(p graphql.ResolveParams) (any, error) {
+	clientID, ok := p.Args["clientId"].(string)
+	if !ok {
+		return nil, errors.New("clientId is required")
+	}
+
+	client, err := r.cancellationPolicyService.ClearClientNoShows(p.Context, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
@@ -0,0 +1,98 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+)
+
+// CancellationPolicyType represents the GraphQL CancellationPolicy type
+var CancellationPolicyType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "CancellationPolicy",
+	Description: "How a business treats late cancellations and no-shows",
+	Fields: graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"noticeHours": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The notice a cancellation needs not to count as a no-show; 0 allows cancelling until the appointment starts",
+		},
+		"noShowFee": &graphql.Field{
+			Type:        DecimalScalar,
+			Description: "The fee charged for a no-show or late cancellation, and the least deposit flagged clients pay",
+		},
+		"maxNoShows": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The no-shows after which a client must pay a deposit to book; 0 never flags clients",
+		},
+	},
+})
+
+// SetCancellationPolicyInput represents the GraphQL input for setting a cancellation policy
+var SetCancellationPolicyInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "SetCancellationPolicyInput",
+	Description: "Input for replacing the cancellation policy of a business",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"businessId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"noticeHours": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The notice a cancellation needs not to count as a no-show, up to a week; 0 allows cancelling until the appointment starts",
+		},
+		"noShowFee": &graphql.InputObjectFieldConfig{
+			Type:        DecimalScalar,
+			Description: "The fee charged for a no-show or late cancellation; none when omitted",
+		},
+		"maxNoShows": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The no-shows after which a client must pay a deposit to book, up to 20; 0 never flags clients",
+		},
+	},
+})
+
+// cancellationPolicyQueryFields returns the cancellation policy queries
+func cancellationPolicyQueryFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"cancellationPolicy": &graphql.Field{
+			Type:        CancellationPolicyType,
+			Description: "Get how a business treats late cancellations and no-shows (staff of the business)",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+			},
+			Resolve: resolver.resolveCancellationPolicy,
+		},
+	}
+}
+
+// cancellationPolicyMutationFields returns the cancellation policy mutations
+func cancellationPolicyMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"setCancellationPolicy": &graphql.Field{
+			Type:        CancellationPolicyType,
+			Description: "Replace how a business treats late cancellations and no-shows (owners and managers)",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(SetCancellationPolicyInput),
+					Description: "The new policy",
+				},
+			},
+			Resolve: resolver.resolveSetCancellationPolicy,
+		},
+		"clearClientNoShows": &graphql.Field{
+			Type:        ClientType,
+			Description: "Forgive a client's no-shows, lifting the deposit requirement (owners and managers)",
+			Args: graphql.FieldConfigArgument{
+				"clientId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the client",
+				},
+			},
+			Resolve: resolver.resolveClearClientNoShows,
+		},
+	}
+}
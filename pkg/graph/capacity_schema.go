@@ -0,0 +1,116 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// CapacityWarningKindEnum represents the GraphQL enum for capacity warning kinds
+var CapacityWarningKindEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "CapacityWarningKind",
+	Description: "Why a schedule is flagged as too dense",
+	Values: graphql.EnumValueConfigMap{
+		"DAY_BOOKED": &graphql.EnumValueConfig{
+			Value:       domain.CapacityWarningDayBooked,
+			Description: "The day is booked above the business's capacity threshold",
+		},
+		"NO_BREAK": &graphql.EnumValueConfig{
+			Value:       domain.CapacityWarningNoBreak,
+			Description: "A staff member works longer than allowed without a break",
+		},
+	},
+})
+
+// BreakSuggestionType represents the GraphQL BreakSuggestion type
+var BreakSuggestionType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "BreakSuggestion",
+	Description: "Where to block time for a break in a staff member's day",
+	Fields: graphql.Fields{
+		"staffId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the staff member",
+		},
+		"start": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the break should start",
+		},
+		"minutes": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "How long the break should be",
+		},
+	},
+})
+
+// CapacityWarningType represents the GraphQL CapacityWarning type
+var CapacityWarningType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "CapacityWarning",
+	Description: "A day or staff schedule exceeding the business's capacity thresholds. Warnings never block bookings.",
+	Fields: graphql.Fields{
+		"kind": &graphql.Field{
+			Type:        graphql.NewNonNull(CapacityWarningKindEnum),
+			Description: "Why the schedule is flagged",
+		},
+		"date": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "The local date of the warning",
+		},
+		"bookedMinutes": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "Minutes booked on the day, or worked without a break",
+		},
+		"capacityMinutes": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "Bookable minutes of all staff on the day (day warnings only)",
+		},
+		"bookedPercent": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "Percentage of the day's capacity booked (day warnings only)",
+		},
+		"staffId": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The staff member working without a break (break warnings only)",
+		},
+		"staffName": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The name of the staff member",
+		},
+		"start": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the stretch without a break starts",
+		},
+		"end": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the stretch without a break ends",
+		},
+		"suggestedBreak": &graphql.Field{
+			Type:        BreakSuggestionType,
+			Description: "Where a break would bring the stretch within the limit",
+		},
+	},
+})
+
+// capacityQueryFields returns the capacity warning queries
+func capacityQueryFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"capacityWarnings": &graphql.Field{
+			Type:        graphql.NewList(CapacityWarningType),
+			Description: "Get the days and staff schedules of a business that exceed its capacity thresholds",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+				"startDate": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.DateTime),
+					Description: "The first local date to include",
+				},
+				"endDate": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.DateTime),
+					Description: "The last local date to include (at most 42 days after startDate)",
+				},
+			},
+			Resolve: resolver.resolveCapacityWarnings,
+		},
+	}
+}
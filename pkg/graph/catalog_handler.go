@@ -0,0 +1,108 @@
+package graph
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/assimoes/beautix/internal/service"
+)
+
+// PublicServiceMenuPath is the path prefix of the public service menus, followed by the
+// business ID
+const PublicServiceMenuPath = "/public/services/"
+
+// serviceMenuMaxAge is how long caches may serve a service menu before checking it again
+const serviceMenuMaxAge = "300"
+
+// ServiceMenuHandler serves the service menu of the business in the URL path as JSON for booking
+// widgets and crawlers. Responses carry an ETag and Last-Modified so unchanged menus are answered
+// with 304 Not Modified. It must be registered under PublicServiceMenuPath.
+func ServiceMenuHandler(bookingService service.PublicBookingService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "Only GET and HEAD requests are allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		businessID := strings.Trim(r.URL.Path[len(PublicServiceMenuPath):], "/")
+		menu, err := bookingService.GetServiceMenu(r.Context(), businessID)
+		if err != nil {
+			var notFound service.NotFoundError
+			var invalid *validation.ValidationError
+			switch {
+			case errors.As(err, &notFound):
+				http.Error(w, "Business not found", http.StatusNotFound)
+			case errors.As(err, &invalid):
+				http.Error(w, invalid.Error(), http.StatusBadRequest)
+			default:
+				http.Error(w, "Failed to retrieve services", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		body, err := json.Marshal(menu)
+		if err != nil {
+			http.Error(w, "Failed to render services", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Cache-Control", "public, max-age="+serviceMenuMaxAge)
+		if writeNotModified(w, r, contentETag(body), menu.UpdatedAt) {
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodHead {
+			return
+		}
+		_, _ = w.Write(body)
+	}
+}
+
+// contentETag returns a strong entity tag for a response body
+func contentETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// writeNotModified sets the ETag and Last-Modified of a response and answers a conditional GET
+// or HEAD whose copy is still current with 304 Not Modified, reporting whether it did.
+// If-None-Match takes precedence over If-Modified-Since, as RFC 9110 requires.
+func writeNotModified(w http.ResponseWriter, r *http.Request, etag string, modified time.Time) bool {
+	w.Header().Set("ETag", etag)
+	if !modified.IsZero() {
+		w.Header().Set("Last-Modified", modified.UTC().Format(http.TimeFormat))
+	}
+
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		if !etagMatches(match, etag) {
+			return false
+		}
+	} else {
+		since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+		// HTTP dates have whole seconds only
+		if err != nil || modified.IsZero() || modified.Truncate(time.Second).After(since) {
+			return false
+		}
+	}
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// etagMatches reports whether an If-None-Match list names an entity tag, comparing weakly
+func etagMatches(list, etag string) bool {
+	for _, candidate := range strings.Split(list, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,166 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Client Attachment Query Resolvers
+func (r *Resolver) resolveClientAttachments(p graphql.ResolveParams) (any, error) {
+	clientID, ok := p.Args["clientId"].(string)
+	if !ok {
+		return nil, errors.New("clientId is required")
+	}
+
+	attachments, err := r.clientAttachmentService.GetClientAttachments(p.Context, clientID, optionalString(p.Args, "appointmentId"))
+	if err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+func (r *Resolver) resolveMarketingPhotos(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+
+	photos, err := r.clientAttachmentService.GetMarketingPhotos(p.Context, businessID)
+	if err != nil {
+		return nil, err
+	}
+
+	return photos, nil
+}
+
+func (r *Resolver) resolveAttachmentUsage(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+
+	usage, err := r.clientAttachmentService.GetAttachmentUsage(p.Context, businessID)
+	if err != nil {
+		return nil, err
+	}
+
+	return usage, nil
+}
+
+// Client Attachment Mutation Resolvers
+func (r *Resolver) resolveCreateClientAttachment(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+	clientID, ok := p.Args["clientId"].(string)
+	if !ok {
+		return nil, errors.New("clientId is required")
+	}
+	kind, ok := p.Args["kind"].(domain.ClientAttachmentKind)
+	if !ok {
+		return nil, errors.New("kind is required")
+	}
+	fileName, ok := p.Args["fileName"].(string)
+	if !ok {
+		return nil, errors.New("fileName is required")
+	}
+	contentType, ok := p.Args["contentType"].(string)
+	if !ok {
+		return nil, errors.New("contentType is required")
+	}
+	sizeBytes, ok := p.Args["sizeBytes"].(int)
+	if !ok {
+		return nil, errors.New("sizeBytes is required")
+	}
+
+	ticket, err := r.clientAttachmentService.CreateClientAttachment(p.Context, dto.CreateClientAttachmentDTO{
+		BusinessID:    businessID,
+		ClientID:      clientID,
+		AppointmentID: optionalString(p.Args, "appointmentId"),
+		Kind:          kind,
+		FileName:      fileName,
+		Caption:       optionalString(p.Args, "caption"),
+		ContentType:   contentType,
+		SizeBytes:     int64(sizeBytes),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ticket, nil
+}
+
+func (r *Resolver) resolveCompleteClientAttachment(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+
+	attachment, err := r.clientAttachmentService.CompleteClientAttachment(p.Context, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+func (r *Resolver) resolveSetClientAttachmentConsent(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+	consented, ok := p.Args["consented"].(bool)
+	if !ok {
+		return nil, errors.New("consented is required")
+	}
+
+	attachment, err := r.clientAttachmentService.SetClientAttachmentConsent(p.Context, dto.SetClientAttachmentConsentDTO{
+		AttachmentID: id,
+		Consented:    consented,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+func (r *Resolver) resolveDeleteClientAttachment(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+
+	if err := r.clientAttachmentService.DeleteClientAttachment(p.Context, id); err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		"success": true,
+		"message": "Client attachment deleted successfully",
+	}, nil
+}
+
+func (r *Resolver) resolveSetAttachmentQuota(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+	quotaDTO := dto.SetAttachmentQuotaDTO{BusinessID: businessID}
+	if quota, ok := p.Args["quotaMb"].(int); ok {
+		quotaDTO.QuotaMB = &quota
+	}
+
+	usage, err := r.clientAttachmentService.SetAttachmentQuota(p.Context, quotaDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return usage, nil
+}
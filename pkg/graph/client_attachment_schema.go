@@ -0,0 +1,323 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// ClientAttachmentKindEnum represents the GraphQL enum for what a file attached to a client is
+var ClientAttachmentKindEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "ClientAttachmentKind",
+	Description: "What a file attached to a client is",
+	Values: graphql.EnumValueConfigMap{
+		"BEFORE_PHOTO": &graphql.EnumValueConfig{
+			Value:       domain.ClientAttachmentBeforePhoto,
+			Description: "A photo taken before a treatment",
+		},
+		"AFTER_PHOTO": &graphql.EnumValueConfig{
+			Value:       domain.ClientAttachmentAfterPhoto,
+			Description: "A photo taken after a treatment",
+		},
+		"PHOTO": &graphql.EnumValueConfig{
+			Value:       domain.ClientAttachmentPhoto,
+			Description: "Any other photo of the client",
+		},
+		"DOCUMENT": &graphql.EnumValueConfig{
+			Value:       domain.ClientAttachmentDocument,
+			Description: "A signed form, a prescription or the like; an image or a PDF",
+		},
+	},
+})
+
+// ClientAttachmentStatusEnum represents the GraphQL enum for the state of a client attachment
+var ClientAttachmentStatusEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "ClientAttachmentStatus",
+	Description: "How far a client attachment has got",
+	Values: graphql.EnumValueConfigMap{
+		"PENDING": &graphql.EnumValueConfig{
+			Value:       domain.ClientAttachmentPending,
+			Description: "Waiting for the file to be uploaded and the upload completed",
+		},
+		"STORED": &graphql.EnumValueConfig{
+			Value:       domain.ClientAttachmentStored,
+			Description: "Checked and kept",
+		},
+		"REJECTED": &graphql.EnumValueConfig{
+			Value:       domain.ClientAttachmentRejected,
+			Description: "The file was not what it was declared as; see the error",
+		},
+		"DELETED": &graphql.EnumValueConfig{
+			Value:       domain.ClientAttachmentDeleted,
+			Description: "Deleted; the file is removed within the hour",
+		},
+		"REMOVED": &graphql.EnumValueConfig{
+			Value:       domain.ClientAttachmentRemoved,
+			Description: "Removed from storage",
+		},
+	},
+})
+
+// ClientAttachmentType represents the GraphQL ClientAttachment type
+var ClientAttachmentType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ClientAttachment",
+	Description: "A photo or document attached to a client, and optionally to one of their appointments",
+	Fields: withBaseFields("attachment", graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"clientId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the client",
+		},
+		"appointmentId": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The ID of the appointment the file is about",
+		},
+		"kind": &graphql.Field{
+			Type:        graphql.NewNonNull(ClientAttachmentKindEnum),
+			Description: "What the file is",
+		},
+		"fileName": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The name the file was uploaded with",
+		},
+		"caption": &graphql.Field{
+			Type:        graphql.String,
+			Description: "A note on the file",
+		},
+		"contentType": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The media type of the file",
+		},
+		"sizeBytes": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Float),
+			Description: "The size of the file in bytes, as declared until stored",
+		},
+		"status": &graphql.Field{
+			Type:        graphql.NewNonNull(ClientAttachmentStatusEnum),
+			Description: "How far the attachment has got",
+		},
+		"width": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "The width of an image in pixels",
+		},
+		"height": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "The height of an image in pixels",
+		},
+		"error": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Why the file was rejected",
+		},
+		"storedAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the file was checked and kept",
+		},
+		"marketingConsentAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the client agreed to the photo's use in marketing",
+		},
+		"usableInMarketing": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether the photo may be used in marketing",
+		},
+		"url": &graphql.Field{
+			Type:        graphql.String,
+			Description: "A signed URL to view the file, once stored",
+		},
+		"urlExpiresAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the URL stops working",
+		},
+	}),
+})
+
+// ClientAttachmentTicketType represents the GraphQL ClientAttachmentTicket type
+var ClientAttachmentTicketType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ClientAttachmentTicket",
+	Description: "A presigned URL to send the file of a client attachment to, before completing the upload",
+	Fields: graphql.Fields{
+		"attachment": &graphql.Field{
+			Type:        graphql.NewNonNull(ClientAttachmentType),
+			Description: "The attachment",
+		},
+		"uploadUrl": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The URL to send the file to",
+		},
+		"method": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The HTTP method to send the file with",
+		},
+		"headers": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(UploadHeaderType))),
+			Description: "The headers to send the file with, exactly as given",
+		},
+		"expiresAt": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the URL stops working",
+		},
+	},
+})
+
+// AttachmentUsageType represents the GraphQL AttachmentUsage type
+var AttachmentUsageType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "AttachmentUsage",
+	Description: "The storage a business uses for client attachments",
+	Fields: graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"usedBytes": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Float),
+			Description: "The bytes used by stored attachments and those being uploaded",
+		},
+		"quotaBytes": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Float),
+			Description: "The bytes the business may use",
+		},
+	},
+})
+
+// clientAttachmentQueryFields returns the client attachment queries
+func clientAttachmentQueryFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"clientAttachments": &graphql.Field{
+			Type:        graphql.NewList(ClientAttachmentType),
+			Description: "Get the photos and documents attached to a client, newest first",
+			Args: graphql.FieldConfigArgument{
+				"clientId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the client",
+				},
+				"appointmentId": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: "Only those of this appointment",
+				},
+			},
+			Resolve: resolver.resolveClientAttachments,
+		},
+		"marketingPhotos": &graphql.Field{
+			Type:        graphql.NewList(ClientAttachmentType),
+			Description: "Get the client photos of a business that clients agreed to the use of in marketing, newest first",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+			},
+			Resolve: resolver.resolveMarketingPhotos,
+		},
+		"attachmentUsage": &graphql.Field{
+			Type:        AttachmentUsageType,
+			Description: "Get the storage a business uses for client attachments, and its quota",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+			},
+			Resolve: resolver.resolveAttachmentUsage,
+		},
+	}
+}
+
+// clientAttachmentMutationFields returns the client attachment mutations
+func clientAttachmentMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"createClientAttachment": &graphql.Field{
+			Type:        ClientAttachmentTicketType,
+			Description: "Get a URL to upload a client's photo or document to: a JPEG, PNG or GIF, or a PDF for documents, of at most 20 MB",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+				"clientId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the client",
+				},
+				"appointmentId": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: "The ID of the client's appointment the file is about",
+				},
+				"kind": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(ClientAttachmentKindEnum),
+					Description: "What the file is",
+				},
+				"fileName": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The name of the file",
+				},
+				"caption": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: "A note on the file",
+				},
+				"contentType": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The media type of the file: image/jpeg, image/png, image/gif or application/pdf",
+				},
+				"sizeBytes": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.Int),
+					Description: "The exact size of the file in bytes",
+				},
+			},
+			Resolve: resolver.resolveCreateClientAttachment,
+		},
+		"completeClientAttachment": &graphql.Field{
+			Type:        ClientAttachmentType,
+			Description: "Check an uploaded file and keep it: images are turned upright, scaled down and stripped of metadata",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the attachment",
+				},
+			},
+			Resolve: resolver.resolveCompleteClientAttachment,
+		},
+		"setClientAttachmentConsent": &graphql.Field{
+			Type:        ClientAttachmentType,
+			Description: "Record the client agreeing to the use of a photo in marketing, or withdrawing their agreement",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the photo",
+				},
+				"consented": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.Boolean),
+					Description: "Whether the client agrees",
+				},
+			},
+			Resolve: resolver.resolveSetClientAttachmentConsent,
+		},
+		"deleteClientAttachment": &graphql.Field{
+			Type:        DeleteResultType,
+			Description: "Delete a client attachment, freeing its storage",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the attachment",
+				},
+			},
+			Resolve: resolver.resolveDeleteClientAttachment,
+		},
+		"setAttachmentQuota": &graphql.Field{
+			Type:        AttachmentUsageType,
+			Description: "Set the storage a business may use for client attachments (platform admins only)",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+				"quotaMb": &graphql.ArgumentConfig{
+					Type:        graphql.Int,
+					Description: "The quota in megabytes; omit to restore the default",
+				},
+			},
+			Resolve: resolver.resolveSetAttachmentQuota,
+		},
+	}
+}
@@ -0,0 +1,85 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Client Guardian Query Resolvers
+func (r *Resolver) resolveClientDependents(p graphql.ResolveParams) (any, error) {
+	guardianClientID, ok := p.Args["guardianClientId"].(string)
+	if !ok {
+		return nil, errors.New("guardianClientId is required")
+	}
+
+	dependents, err := r.clientGuardianService.ListDependents(p.Context, guardianClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	return dependents, nil
+}
+
+// Client Guardian Mutation Resolvers
+func (r *Resolver) resolveLinkClientGuardian(p graphql.ResolveParams) (any, error) {
+	linkDTO := dto.LinkGuardianDTO{}
+	if err := decodeInput(p.Args, &linkDTO); err != nil {
+		return nil, err
+	}
+
+	client, err := r.clientGuardianService.LinkGuardian(p.Context, linkDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func (r *Resolver) resolveUnlinkClientGuardian(p graphql.ResolveParams) (any, error) {
+	clientID, ok := p.Args["clientId"].(string)
+	if !ok {
+		return nil, errors.New("clientId is required")
+	}
+
+	client, err := r.clientGuardianService.UnlinkGuardian(p.Context, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func (r *Resolver) resolveRecordGuardianConsent(p graphql.ResolveParams) (any, error) {
+	clientID, ok := p.Args["clientId"].(string)
+	if !ok {
+		return nil, errors.New("clientId is required")
+	}
+
+	client, err := r.clientGuardianService.RecordGuardianConsent(p.Context, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func (r *Resolver) resolveSetServiceMinAge(p graphql.ResolveParams) (any, error) {
+	serviceID, ok := p.Args["serviceId"].(string)
+	if !ok {
+		return nil, errors.New("serviceId is required")
+	}
+	var minAge *int
+	if value, ok := p.Args["minAge"].(int); ok {
+		minAge = &value
+	}
+
+	restriction, err := r.clientGuardianService.SetServiceMinAge(p.Context, serviceID, minAge)
+	if err != nil {
+		return nil, err
+	}
+
+	return restriction, nil
+}
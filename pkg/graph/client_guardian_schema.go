@@ -0,0 +1,165 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+)
+
+// MinorClientType represents the GraphQL MinorClient type
+var MinorClientType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "MinorClient",
+	Description: "A client with their age and guardian; contact and consent for a minor go through the guardian",
+	Fields: graphql.Fields{
+		"clientId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the client",
+		},
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"fullName": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The full name of the client",
+		},
+		"dateOfBirth": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "The client's date of birth",
+		},
+		"age": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "The client's age in years; null when the date of birth is unknown",
+		},
+		"isMinor": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether the client is known to be under 18",
+		},
+		"guardianClientId": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The client record of the guardian",
+		},
+		"guardianRelationship": &graphql.Field{
+			Type:        graphql.String,
+			Description: "How the guardian is related to the client, e.g. parent",
+		},
+		"guardianConsentAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the guardian consented to the client being treated",
+		},
+	},
+})
+
+// ServiceAgeRestrictionType represents the GraphQL ServiceAgeRestriction type
+var ServiceAgeRestrictionType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ServiceAgeRestriction",
+	Description: "The minimum client age of a service",
+	Fields: graphql.Fields{
+		"serviceId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the service",
+		},
+		"name": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The name of the service",
+		},
+		"minAge": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "The minimum age on the day of the appointment; null when the service is not age-restricted",
+		},
+	},
+})
+
+// LinkClientGuardianInput represents the GraphQL input for linking a minor to a guardian
+var LinkClientGuardianInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "LinkClientGuardianInput",
+	Description: "Input for linking a minor client to a guardian",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"clientId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the minor client",
+		},
+		"guardianClientId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the guardian's client record",
+		},
+		"relationship": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "How the guardian is related to the client, e.g. parent",
+		},
+		"consentGiven": &graphql.InputObjectFieldConfig{
+			Type:         graphql.Boolean,
+			Description:  "Whether the guardian has consented to the client being treated",
+			DefaultValue: false,
+		},
+	},
+})
+
+// clientGuardianQueryFields returns the minor client and guardian queries
+func clientGuardianQueryFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"clientDependents": &graphql.Field{
+			Type:        graphql.NewList(MinorClientType),
+			Description: "Get the clients a client is the guardian of",
+			Args: graphql.FieldConfigArgument{
+				"guardianClientId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the guardian's client record",
+				},
+			},
+			Resolve: resolver.resolveClientDependents,
+		},
+	}
+}
+
+// clientGuardianMutationFields returns the minor client and guardian mutations
+func clientGuardianMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"linkClientGuardian": &graphql.Field{
+			Type:        MinorClientType,
+			Description: "Link a minor client to a guardian, who then receives their messages",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(LinkClientGuardianInput),
+					Description: "The guardian data",
+				},
+			},
+			Resolve: resolver.resolveLinkClientGuardian,
+		},
+		"unlinkClientGuardian": &graphql.Field{
+			Type:        MinorClientType,
+			Description: "Remove the guardian of a client, along with their consent",
+			Args: graphql.FieldConfigArgument{
+				"clientId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the client",
+				},
+			},
+			Resolve: resolver.resolveUnlinkClientGuardian,
+		},
+		"recordGuardianConsent": &graphql.Field{
+			Type:        MinorClientType,
+			Description: "Record that the guardian of a client consented to the client being treated",
+			Args: graphql.FieldConfigArgument{
+				"clientId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the client",
+				},
+			},
+			Resolve: resolver.resolveRecordGuardianConsent,
+		},
+		"setServiceMinAge": &graphql.Field{
+			Type:        ServiceAgeRestrictionType,
+			Description: "Set the minimum client age of a service",
+			Args: graphql.FieldConfigArgument{
+				"serviceId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the service",
+				},
+				"minAge": &graphql.ArgumentConfig{
+					Type:        graphql.Int,
+					Description: "The minimum age in years; omit to lift the restriction",
+				},
+			},
+			Resolve: resolver.resolveSetServiceMinAge,
+		},
+	}
+}
@@ -0,0 +1,77 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Client Leaderboard Query Resolvers
+func (r *Resolver) resolveClientLeaderboard(p graphql.ResolveParams) (any, error) {
+	requestDTO := dto.ClientLeaderboardRequestDTO{
+		From: optionalTime(p.Args, "from"),
+		To:   optionalTime(p.Args, "to"),
+	}
+	if businessID, ok := p.Args["businessId"].(string); ok {
+		requestDTO.BusinessID = businessID
+	}
+	if metric, ok := p.Args["metric"].(domain.LeaderboardMetric); ok {
+		requestDTO.Metric = metric
+	}
+	if limit, ok := p.Args["limit"].(int); ok {
+		requestDTO.Limit = limit
+	}
+
+	leaderboard, err := r.clientLeaderboardService.GetLeaderboard(p.Context, requestDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return leaderboard, nil
+}
+
+func (r *Resolver) resolveVIPRules(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+
+	rules, err := r.clientLeaderboardService.GetVIPRules(p.Context, businessID)
+	if err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// VIP Mutation Resolvers
+func (r *Resolver) resolveSetVIPRules(p graphql.ResolveParams) (any, error) {
+	rulesDTO := dto.SetVIPRulesDTO{}
+	if err := decodeInput(p.Args, &rulesDTO); err != nil {
+		return nil, err
+	}
+
+	refresh, err := r.clientLeaderboardService.SetVIPRules(p.Context, rulesDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return refresh, nil
+}
+
+func (r *Resolver) resolveRefreshVIPClients(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+
+	refresh, err := r.clientLeaderboardService.RefreshVIPClients(p.Context, businessID)
+	if err != nil {
+		return nil, err
+	}
+
+	return refresh, nil
+}
@@ -0,0 +1,256 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// LeaderboardMetricEnum represents the GraphQL enum for what clients are ranked by
+var LeaderboardMetricEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "LeaderboardMetric",
+	Description: "What clients are ranked by",
+	Values: graphql.EnumValueConfigMap{
+		"SPEND": &graphql.EnumValueConfig{
+			Value:       domain.LeaderboardSpend,
+			Description: "The amount spent on completed appointments",
+		},
+		"VISITS": &graphql.EnumValueConfig{
+			Value:       domain.LeaderboardVisits,
+			Description: "The number of completed appointments",
+		},
+		"POINTS": &graphql.EnumValueConfig{
+			Value:       domain.LeaderboardPoints,
+			Description: "The loyalty points earned across programs, referral bonuses included",
+		},
+	},
+})
+
+// ClientStandingType represents the GraphQL ClientStanding type
+var ClientStandingType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ClientStanding",
+	Description: "A client's place on a leaderboard and what they did over its period",
+	Fields: graphql.Fields{
+		"rank": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "1 for the top client; clients tied share a rank",
+		},
+		"client": &graphql.Field{
+			Type:        graphql.NewNonNull(ClientType),
+			Description: "The client",
+		},
+		"visits": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The completed appointments over the period",
+		},
+		"spent": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The amount spent on completed appointments over the period",
+		},
+		"points": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The loyalty points earned over the period",
+		},
+		"lastVisit": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "The latest completed appointment of the period",
+		},
+		"isVip": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether the client is currently tagged as a VIP",
+		},
+	},
+})
+
+// ClientLeaderboardType represents the GraphQL ClientLeaderboard type
+var ClientLeaderboardType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ClientLeaderboard",
+	Description: "The top clients of a business over a period",
+	Fields: graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"metric": &graphql.Field{
+			Type:        graphql.NewNonNull(LeaderboardMetricEnum),
+			Description: "What the clients are ranked by",
+		},
+		"from": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "The start of the period",
+		},
+		"to": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "The end of the period, excluded",
+		},
+		"standings": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(ClientStandingType))),
+			Description: "The clients with anything on the metric, best first",
+		},
+	},
+})
+
+// VIPRulesType represents the GraphQL VIPRules type
+var VIPRulesType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "VIPRules",
+	Description: "The thresholds making clients of a business VIPs; a client meeting any of them over the period is one",
+	Fields: graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"enabled": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether clients are tagged as VIPs by the rules; tags are left as they are while disabled",
+		},
+		"periodDays": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The trailing days the thresholds apply to",
+		},
+		"minSpend": &graphql.Field{
+			Type:        DecimalScalar,
+			Description: "The amount spent on completed appointments making a client a VIP",
+		},
+		"minVisits": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "The completed appointments making a client a VIP",
+		},
+		"minPoints": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "The loyalty points earned making a client a VIP",
+		},
+		"tag": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The tag VIP clients have; target them in a campaign with vipOnly",
+		},
+	},
+})
+
+// VIPRefreshType represents the GraphQL VIPRefresh type
+var VIPRefreshType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "VIPRefresh",
+	Description: "The VIP tagging of a business's clients by its rules",
+	Fields: graphql.Fields{
+		"rules": &graphql.Field{
+			Type:        graphql.NewNonNull(VIPRulesType),
+			Description: "The rules applied",
+		},
+		"vipClients": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "How many clients meet a threshold",
+		},
+		"tagged": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "How many clients became VIPs",
+		},
+		"untagged": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "How many clients are no longer VIPs",
+		},
+	},
+})
+
+// SetVIPRulesInput represents the GraphQL input for setting VIP rules
+var SetVIPRulesInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "SetVIPRulesInput",
+	Description: "Input for replacing the VIP rules of a business",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"businessId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"enabled": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether to tag clients as VIPs by the rules; enabling them tags clients right away",
+		},
+		"periodDays": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Int,
+			Description: "The trailing days the thresholds apply to, a year when omitted",
+		},
+		"minSpend": &graphql.InputObjectFieldConfig{
+			Type:        DecimalScalar,
+			Description: "The amount spent on completed appointments making a client a VIP",
+		},
+		"minVisits": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Int,
+			Description: "The completed appointments making a client a VIP",
+		},
+		"minPoints": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Int,
+			Description: "The loyalty points earned making a client a VIP",
+		},
+	},
+})
+
+// clientLeaderboardQueryFields returns the client leaderboard queries
+func clientLeaderboardQueryFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"clientLeaderboard": &graphql.Field{
+			Type:        ClientLeaderboardType,
+			Description: "Rank the clients of a business by spend, visits or loyalty points over a period (staff who may view reports)",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+				"metric": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(LeaderboardMetricEnum),
+					Description: "What to rank clients by",
+				},
+				"from": &graphql.ArgumentConfig{
+					Type:        graphql.DateTime,
+					Description: "The start of the period, a year before its end when omitted",
+				},
+				"to": &graphql.ArgumentConfig{
+					Type:        graphql.DateTime,
+					Description: "The end of the period, excluded; now when omitted",
+				},
+				"limit": &graphql.ArgumentConfig{
+					Type:         graphql.Int,
+					DefaultValue: domain.DefaultLeaderboardLimit,
+					Description:  "The number of clients to rank, up to 100",
+				},
+			},
+			Resolve: resolver.resolveClientLeaderboard,
+		},
+		"vipRules": &graphql.Field{
+			Type:        VIPRulesType,
+			Description: "Get the thresholds making clients of a business VIPs (staff who may view reports)",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+			},
+			Resolve: resolver.resolveVIPRules,
+		},
+	}
+}
+
+// clientLeaderboardMutationFields returns the VIP mutations
+func clientLeaderboardMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"setVipRules": &graphql.Field{
+			Type:        VIPRefreshType,
+			Description: "Replace the thresholds making clients of a business VIPs, tagging them right away when enabled (owners and managers)",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(SetVIPRulesInput),
+					Description: "The new rules",
+				},
+			},
+			Resolve: resolver.resolveSetVIPRules,
+		},
+		"refreshVipClients": &graphql.Field{
+			Type:        VIPRefreshType,
+			Description: "Tag the clients meeting a business's VIP rules now rather than at the next hourly refresh (owners and managers)",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+			},
+			Resolve: resolver.resolveRefreshVIPClients,
+		},
+	}
+}
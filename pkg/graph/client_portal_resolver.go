@@ -0,0 +1,47 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+)
+
+// Client Portal Query Resolvers
+func (r *Resolver) resolveMyVisits(p graphql.ResolveParams) (any, error) {
+	page, pageSize := pageFromArgs(p.Args, 20)
+
+	visits, total, err := r.clientPortalService.GetMyVisits(p.Context, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{"visits": visits, "total": total}, nil
+}
+
+func (r *Resolver) resolveMyVisit(p graphql.ResolveParams) (any, error) {
+	appointmentID, ok := p.Args["appointmentId"].(string)
+	if !ok {
+		return nil, errors.New("appointmentId is required")
+	}
+
+	visit, err := r.clientPortalService.GetMyVisit(p.Context, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return visit, nil
+}
+
+func (r *Resolver) resolveMyInvoice(p graphql.ResolveParams) (any, error) {
+	appointmentID, ok := p.Args["appointmentId"].(string)
+	if !ok {
+		return nil, errors.New("appointmentId is required")
+	}
+
+	invoice, err := r.clientPortalService.GetMyInvoice(p.Context, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return invoice, nil
+}
@@ -0,0 +1,212 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// PortalVisitServiceType represents the GraphQL type for a service delivered during a visit
+var PortalVisitServiceType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "PortalVisitService",
+	Description: "A service delivered during a visit",
+	Fields: graphql.Fields{
+		"name": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The name of the service",
+		},
+		"duration": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The duration in minutes",
+		},
+		"price": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The listed price",
+		},
+	},
+})
+
+// PortalVisitType represents the GraphQL type for a past appointment in the client portal
+var PortalVisitType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "PortalVisit",
+	Description: "A past appointment of the signed-in client",
+	Fields: graphql.Fields{
+		"appointmentId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the appointment",
+		},
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"businessName": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The name of the business",
+		},
+		"currency": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The currency of the amounts",
+		},
+		"staffName": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The staff member who served the client",
+		},
+		"startTime": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the appointment started",
+		},
+		"endTime": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the appointment ended",
+		},
+		"status": &graphql.Field{
+			Type:        graphql.NewNonNull(AppointmentStatusEnum),
+			Description: "The status of the appointment",
+		},
+		"services": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(PortalVisitServiceType))),
+			Description: "The services delivered",
+		},
+		"amountPaid": &graphql.Field{
+			Type:        DecimalScalar,
+			Description: "The amount charged, once the service is completed",
+		},
+		"paymentMethod": &graphql.Field{
+			Type:        graphql.String,
+			Description: "How the client paid",
+		},
+		"loyaltyPoints": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The loyalty points earned during the visit",
+		},
+		"invoiceAvailable": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether an invoice can be downloaded",
+		},
+	},
+})
+
+// PortalVisitPageType represents the GraphQL type for a page of the signed-in client's visits
+var PortalVisitPageType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "PortalVisitPage",
+	Description: "A page of the signed-in client's past appointments, with how many there are",
+	Fields: graphql.Fields{
+		"visits": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(PortalVisitType))),
+			Description: "The visits of the page, most recent first",
+		},
+		"total": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "How many past appointments the client has",
+		},
+	},
+})
+
+// InvoiceLineType represents the GraphQL type for a billed item
+var InvoiceLineType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "InvoiceLine",
+	Description: "A billed item of an invoice",
+	Fields: graphql.Fields{
+		"description": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "What was billed",
+		},
+		"amount": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The billed amount",
+		},
+	},
+})
+
+// InvoiceType represents the GraphQL Invoice type
+var InvoiceType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Invoice",
+	Description: "An invoice for a completed appointment",
+	Fields: withBaseFields("invoice", graphql.Fields{
+		"appointmentId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the invoiced appointment",
+		},
+		"invoiceNumber": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The business's sequential invoice number",
+		},
+		"issuedAt": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the invoice was issued",
+		},
+		"currency": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The currency of the amounts",
+		},
+		"businessName": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The name of the issuing business",
+		},
+		"businessTaxId": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The tax ID of the issuing business",
+		},
+		"clientName": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The name of the invoiced client",
+		},
+		"lineItems": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(InvoiceLineType))),
+			Description: "The billed items",
+		},
+		"total": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The invoice total",
+		},
+		"amountPaid": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The amount paid",
+		},
+		"paymentMethod": &graphql.Field{
+			Type:        graphql.String,
+			Description: "How the client paid",
+		},
+		"downloadUrl": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The path to download a printable copy of the invoice",
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				if invoice, ok := p.Source.(*dto.InvoiceResponseDTO); ok {
+					return InvoiceDownloadPath + invoice.AppointmentID, nil
+				}
+				return nil, nil
+			},
+		},
+	}),
+})
+
+// clientPortalQueryFields returns the client portal queries
+func clientPortalQueryFields(resolver *Resolver) graphql.Fields {
+	appointmentArgs := graphql.FieldConfigArgument{
+		"appointmentId": &graphql.ArgumentConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the appointment",
+		},
+	}
+
+	return graphql.Fields{
+		"myVisits": &graphql.Field{
+			Type:        graphql.NewNonNull(PortalVisitPageType),
+			Description: "Get a page of the signed-in client's past appointments, most recent first",
+			Args:        paginationArgs("visits", 20),
+			Resolve:     resolver.resolveMyVisits,
+		},
+		"myVisit": &graphql.Field{
+			Type:        PortalVisitType,
+			Description: "Get one of the signed-in client's appointments",
+			Args:        appointmentArgs,
+			Resolve:     resolver.resolveMyVisit,
+		},
+		"myInvoice": &graphql.Field{
+			Type:        InvoiceType,
+			Description: "Get the invoice of one of the signed-in client's completed appointments",
+			Args:        appointmentArgs,
+			Resolve:     resolver.resolveMyInvoice,
+		},
+	}
+}
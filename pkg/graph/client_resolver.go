@@ -0,0 +1,115 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Client Query Resolvers
+func (r *Resolver) resolveClientDuplicates(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+
+	duplicates, err := r.clientService.FindDuplicateClients(p.Context, businessID)
+	if err != nil {
+		return nil, err
+	}
+
+	return duplicates, nil
+}
+
+// Client Mutation Resolvers
+func (r *Resolver) resolveImportClients(p graphql.ResolveParams) (any, error) {
+	importDTO := dto.ImportClientsDTO{}
+	if err := decodeInput(p.Args, &importDTO); err != nil {
+		return nil, err
+	}
+
+	result, err := r.clientService.ImportClients(p.Context, importDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *Resolver) resolveExportClientData(p graphql.ResolveParams) (any, error) {
+	clientID, ok := p.Args["clientId"].(string)
+	if !ok {
+		return nil, errors.New("clientId is required")
+	}
+
+	exportDTO := dto.ExportClientDataDTO{ClientID: clientID}
+	if format, ok := p.Args["format"].(domain.ClientDataExportFormat); ok {
+		exportDTO.Format = format
+	}
+	if publicKey, ok := p.Args["publicKey"].(string); ok {
+		exportDTO.PublicKey = publicKey
+	}
+	if passphrase, ok := p.Args["passphrase"].(string); ok {
+		exportDTO.Passphrase = passphrase
+	}
+
+	export, err := r.clientService.ExportClientData(p.Context, exportDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return export, nil
+}
+
+func (r *Resolver) resolveAnonymizeClient(p graphql.ResolveParams) (any, error) {
+	clientID, ok := p.Args["clientId"].(string)
+	if !ok {
+		return nil, errors.New("clientId is required")
+	}
+
+	result, err := r.clientService.AnonymizeClient(p.Context, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *Resolver) resolveSetClientTags(p graphql.ResolveParams) (any, error) {
+	clientID, ok := p.Args["clientId"].(string)
+	if !ok {
+		return nil, errors.New("clientId is required")
+	}
+	tagsDTO := dto.SetClientTagsDTO{ClientID: clientID, Tags: stringList(p.Args["tags"])}
+	if version, ok := p.Args["expectedVersion"].(int); ok {
+		tagsDTO.ExpectedVersion = &version
+	}
+
+	client, err := r.clientService.SetClientTags(p.Context, tagsDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func (r *Resolver) resolveMergeClients(p graphql.ResolveParams) (any, error) {
+	clientID, ok := p.Args["clientId"].(string)
+	if !ok {
+		return nil, errors.New("clientId is required")
+	}
+	duplicateID, ok := p.Args["duplicateId"].(string)
+	if !ok {
+		return nil, errors.New("duplicateId is required")
+	}
+
+	merge, err := r.clientService.MergeClients(p.Context, dto.MergeClientsDTO{ClientID: clientID, DuplicateID: duplicateID})
+	if err != nil {
+		return nil, err
+	}
+
+	return merge, nil
+}
@@ -0,0 +1,405 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// ClientImportStatusEnum represents the GraphQL enum for the outcome of an imported row
+var ClientImportStatusEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "ClientImportStatus",
+	Description: "The outcome of a row of a client import",
+	Values: graphql.EnumValueConfigMap{
+		"READY": &graphql.EnumValueConfig{
+			Value:       domain.ClientImportReady,
+			Description: "Valid, but not imported because of a dry run or an invalid row elsewhere in the file",
+		},
+		"IMPORTED": &graphql.EnumValueConfig{
+			Value:       domain.ClientImportImported,
+			Description: "Created as a new client",
+		},
+		"DUPLICATE": &graphql.EnumValueConfig{
+			Value:       domain.ClientImportDuplicate,
+			Description: "Skipped because its email or phone belongs to an existing client or an earlier row",
+		},
+		"INVALID": &graphql.EnumValueConfig{
+			Value:       domain.ClientImportInvalid,
+			Description: "Rejected; see the errors",
+		},
+	},
+})
+
+// ClientImportRowType represents the GraphQL ClientImportRow type
+var ClientImportRowType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ClientImportRow",
+	Description: "The outcome of a row of a client import",
+	Fields: graphql.Fields{
+		"line": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The line of the file, counting the header as line 1",
+		},
+		"status": &graphql.Field{
+			Type:        graphql.NewNonNull(ClientImportStatusEnum),
+			Description: "The outcome of the row",
+		},
+		"firstName": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The client's first name",
+		},
+		"lastName": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The client's last name",
+		},
+		"email": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The client's email address",
+		},
+		"phone": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The client's phone number",
+		},
+		"errors": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+			Description: "Why the row is invalid",
+		},
+		"clientId": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The ID of the created client",
+		},
+		"duplicateOfClientId": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The existing client the row duplicates",
+		},
+		"duplicateOfLine": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "The earlier line of the file the row duplicates",
+		},
+	},
+})
+
+// ClientImportResultType represents the GraphQL ClientImportResult type
+var ClientImportResultType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ClientImportResult",
+	Description: "The outcome of a client import, row by row",
+	Fields: graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"dryRun": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether the import only validated the file",
+		},
+		"totalRows": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The number of clients in the file",
+		},
+		"imported": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The number of clients created",
+		},
+		"ready": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The number of valid clients that were not created",
+		},
+		"duplicates": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The number of rows skipped as duplicates",
+		},
+		"invalid": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The number of invalid rows",
+		},
+		"rows": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(ClientImportRowType))),
+			Description: "The outcome of every row",
+		},
+	},
+})
+
+// ImportClientsInput represents the GraphQL input for importing clients
+var ImportClientsInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "ImportClientsInput",
+	Description: "Input for importing clients from a CSV file",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"businessId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"csv": &graphql.InputObjectFieldConfig{
+			Type: graphql.NewNonNull(graphql.String),
+			Description: "The content of the CSV file, at most 5 MB, with a header row. Columns are matched by name: " +
+				"first name, last name (or full name), email, phone, date of birth, notes, allergies and referral source. " +
+				"Commas, semicolons and tabs are accepted as separators.",
+		},
+		"dryRun": &graphql.InputObjectFieldConfig{
+			Type:         graphql.Boolean,
+			Description:  "Validate the file and report what would be imported without creating any client",
+			DefaultValue: false,
+		},
+	},
+})
+
+// ClientDataExportFormatEnum represents the GraphQL enum for the file format of a client data export
+var ClientDataExportFormatEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "ClientDataExportFormat",
+	Description: "The file format of a client data export",
+	Values: graphql.EnumValueConfigMap{
+		"JSON": &graphql.EnumValueConfig{
+			Value:       domain.ClientDataExportJSON,
+			Description: "A single JSON document",
+		},
+		"ZIP": &graphql.EnumValueConfig{
+			Value:       domain.ClientDataExportZIP,
+			Description: "A ZIP archive with a JSON file per kind of data",
+		},
+	},
+})
+
+// ExportEncryptionEnum represents the GraphQL enum for how an export is encrypted
+var ExportEncryptionEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "ExportEncryption",
+	Description: "How an export is encrypted",
+	Values: graphql.EnumValueConfigMap{
+		"NONE": &graphql.EnumValueConfig{
+			Value:       domain.ExportEncryptionNone,
+			Description: "The export is not encrypted",
+		},
+		"PUBLIC_KEY": &graphql.EnumValueConfig{
+			Value:       domain.ExportEncryptionPublicKey,
+			Description: "Encrypted for the holder of the private key of an RSA key",
+		},
+		"PASSPHRASE": &graphql.EnumValueConfig{
+			Value:       domain.ExportEncryptionPassphrase,
+			Description: "Encrypted with a passphrase",
+		},
+	},
+})
+
+// ClientDataExportType represents the GraphQL ClientDataExport type
+var ClientDataExportType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ClientDataExport",
+	Description: "Everything held about a client, as a file for a data subject access request",
+	Fields: graphql.Fields{
+		"clientId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the client",
+		},
+		"format": &graphql.Field{
+			Type:        graphql.NewNonNull(ClientDataExportFormatEnum),
+			Description: "The file format",
+		},
+		"fileName": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "A suggested name for the file",
+		},
+		"contentType": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The media type of the file",
+		},
+		"data": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The file, base64-encoded",
+		},
+		"generatedAt": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the export was generated",
+		},
+		"encryption": &graphql.Field{
+			Type:        graphql.NewNonNull(ExportEncryptionEnum),
+			Description: "How the file is encrypted; encrypted files are in the BTXE format, opened with the key or passphrase",
+		},
+		"keyFingerprint": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The SHA-256 fingerprint of the public key the file is encrypted for, as recorded in the audit log",
+		},
+	},
+})
+
+// ClientAnonymizationType represents the GraphQL ClientAnonymization type
+var ClientAnonymizationType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ClientAnonymization",
+	Description: "The outcome of anonymizing a client",
+	Fields: graphql.Fields{
+		"clientId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the client",
+		},
+		"anonymizedAt": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the client's personal data was removed",
+		},
+	},
+})
+
+// DuplicateReasonEnum represents the GraphQL enum for why two clients are probably the same person
+var DuplicateReasonEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "DuplicateReason",
+	Description: "Why two clients are probably the same person",
+	Values: graphql.EnumValueConfigMap{
+		"EMAIL": &graphql.EnumValueConfig{
+			Value:       domain.DuplicateEmail,
+			Description: "The same email address, ignoring case",
+		},
+		"PHONE": &graphql.EnumValueConfig{
+			Value:       domain.DuplicatePhone,
+			Description: "The same phone number, ignoring punctuation",
+		},
+		"NAME": &graphql.EnumValueConfig{
+			Value:       domain.DuplicateName,
+			Description: "The same name, ignoring case, accents, punctuation and word order",
+		},
+	},
+})
+
+// ClientDuplicateType represents the GraphQL ClientDuplicate type
+var ClientDuplicateType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ClientDuplicate",
+	Description: "Two clients of a business that are probably the same person",
+	Fields: graphql.Fields{
+		"client": &graphql.Field{
+			Type:        graphql.NewNonNull(ClientType),
+			Description: "The older of the two clients, usually the one to keep",
+		},
+		"duplicate": &graphql.Field{
+			Type:        graphql.NewNonNull(ClientType),
+			Description: "The newer of the two clients",
+		},
+		"reasons": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(DuplicateReasonEnum))),
+			Description: "What the two clients share",
+		},
+	},
+})
+
+// ClientMergeType represents the GraphQL ClientMerge type
+var ClientMergeType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ClientMerge",
+	Description: "The outcome of merging a duplicate into a client",
+	Fields: graphql.Fields{
+		"client": &graphql.Field{
+			Type:        graphql.NewNonNull(ClientType),
+			Description: "The client kept, with the details taken from the duplicate",
+		},
+		"mergedClientId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the duplicate, which was deleted",
+		},
+		"recordsMoved": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The number of appointments, memberships, campaign records and other records moved to the client",
+		},
+		"mergedAt": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the clients were merged",
+		},
+	},
+})
+
+// clientQueryFields returns the client queries
+func clientQueryFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"clientDuplicates": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(ClientDuplicateType))),
+			Description: "Find the clients of a business that are probably the same person, strongest matches first",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+			},
+			Resolve: resolver.resolveClientDuplicates,
+		},
+	}
+}
+
+// clientMutationFields returns the client mutations
+func clientMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"importClients": &graphql.Field{
+			Type: ClientImportResultType,
+			Description: "Import clients from a CSV file, skipping duplicates by email or phone. " +
+				"Nothing is imported while any row is invalid.",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(ImportClientsInput),
+					Description: "The file to import",
+				},
+			},
+			Resolve: resolver.resolveImportClients,
+		},
+		"exportClientData": &graphql.Field{
+			Type: ClientDataExportType,
+			Description: "Export the profile, appointments, completions, loyalty memberships and campaign messages " +
+				"of a client",
+			Args: graphql.FieldConfigArgument{
+				"clientId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the client",
+				},
+				"format": &graphql.ArgumentConfig{
+					Type:         ClientDataExportFormatEnum,
+					Description:  "The file format",
+					DefaultValue: domain.ClientDataExportJSON,
+				},
+				"publicKey": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: "A PEM encoded RSA public key of at least 2048 bits to encrypt the file for",
+				},
+				"passphrase": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: "A passphrase of at least 12 characters to encrypt the file with, instead of a public key",
+				},
+			},
+			Resolve: resolver.resolveExportClientData,
+		},
+		"anonymizeClient": &graphql.Field{
+			Type: ClientAnonymizationType,
+			Description: "Remove the personal data of a client, keeping their appointments, completions and loyalty " +
+				"balances for the business's statistics. This cannot be undone.",
+			Args: graphql.FieldConfigArgument{
+				"clientId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the client",
+				},
+			},
+			Resolve: resolver.resolveAnonymizeClient,
+		},
+		"setClientTags": &graphql.Field{
+			Type:        ClientType,
+			Description: "Replace the tags of a client, which campaigns can target",
+			Args: graphql.FieldConfigArgument{
+				"clientId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the client",
+				},
+				"tags": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+					Description: "The tags; they are lower-cased and repeats dropped",
+				},
+				"expectedVersion": &graphql.ArgumentConfig{
+					Type:        graphql.Int,
+					Description: "The version of the client the change is based on; fails with VERSION_CONFLICT when someone else changed it since",
+				},
+			},
+			Resolve: resolver.resolveSetClientTags,
+		},
+		"mergeClients": &graphql.Field{
+			Type: ClientMergeType,
+			Description: "Merge a duplicate into a client, moving its appointments, completions, loyalty memberships, " +
+				"campaign records and everything else held about it, then deleting it. This cannot be undone.",
+			Args: graphql.FieldConfigArgument{
+				"clientId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the client to keep",
+				},
+				"duplicateId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the duplicate to merge into it",
+				},
+			},
+			Resolve: resolver.resolveMergeClients,
+		},
+	}
+}
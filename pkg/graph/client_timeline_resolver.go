@@ -0,0 +1,29 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Client Timeline Query Resolvers
+func (r *Resolver) resolveClientTimeline(p graphql.ResolveParams) (any, error) {
+	clientID, ok := p.Args["clientId"].(string)
+	if !ok {
+		return nil, errors.New("clientId is required")
+	}
+	after, first := cursorFromArgs(p.Args, 20)
+
+	timeline, err := r.clientTimelineService.GetClientTimeline(p.Context, dto.ClientTimelineRequestDTO{
+		ClientID: clientID,
+		After:    after,
+		First:    first,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return timeline, nil
+}
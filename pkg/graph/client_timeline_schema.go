@@ -0,0 +1,103 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// TimelineEntryKindEnum represents the GraphQL enum for what happened in a client's timeline
+var TimelineEntryKindEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "TimelineEntryKind",
+	Description: "What happened in a client's timeline",
+	Values: graphql.EnumValueConfigMap{
+		"APPOINTMENT": &graphql.EnumValueConfig{Value: domain.TimelineAppointment, Description: "An appointment was booked"},
+		"COMPLETION":  &graphql.EnumValueConfig{Value: domain.TimelineCompletion, Description: "An appointment was completed"},
+		"PAYMENT":     &graphql.EnumValueConfig{Value: domain.TimelinePayment, Description: "A card payment was taken"},
+		"LOYALTY":     &graphql.EnumValueConfig{Value: domain.TimelineLoyalty, Description: "Loyalty points were earned, redeemed, adjusted or expired"},
+		"CAMPAIGN":    &graphql.EnumValueConfig{Value: domain.TimelineCampaign, Description: "A campaign message was sent"},
+		"NOTE":        &graphql.EnumValueConfig{Value: domain.TimelineNote, Description: "A service record was written up"},
+		"REVIEW":      &graphql.EnumValueConfig{Value: domain.TimelineReview, Description: "A review was left"},
+	},
+})
+
+// TimelineEntryType represents the GraphQL TimelineEntry type
+var TimelineEntryType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "TimelineEntry",
+	Description: "One thing that happened to a client; only the fields that apply to its kind are set",
+	Fields: graphql.Fields{
+		"id": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the appointment, completion, payment, loyalty transaction, campaign message, service record or review the entry is taken from",
+		},
+		"kind": &graphql.Field{
+			Type:        graphql.NewNonNull(TimelineEntryKindEnum),
+			Description: "What happened",
+		},
+		"occurredAt": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When it happened",
+		},
+		"appointmentId": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The ID of the appointment it is about",
+		},
+		"status": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The appointment, payment, campaign message or review status, the loyalty transaction type or the completion's payment method",
+		},
+		"title": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The campaign or loyalty program name, or the payment kind",
+		},
+		"description": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The appointment or service record notes, loyalty description, campaign message or review comment",
+		},
+		"channel": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The channel a campaign message was sent through",
+		},
+		"startTime": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the booked appointment starts",
+		},
+		"amount": &graphql.Field{
+			Type:        DecimalScalar,
+			Description: "The price charged or the payment amount",
+		},
+		"currency": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The currency of the amount",
+		},
+		"points": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "The loyalty points earned, or taken away when negative",
+		},
+		"rating": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "The review rating, 1 to 5 stars",
+		},
+	},
+})
+
+// TimelineEntryConnectionType represents a page of a client's timeline
+var TimelineEntryConnectionType = connectionType(TimelineEntryType)
+
+// clientTimelineQueryFields returns the client timeline queries
+func clientTimelineQueryFields(resolver *Resolver) graphql.Fields {
+	args := connectionArgs("entries", 20)
+	args["clientId"] = &graphql.ArgumentConfig{
+		Type:        graphql.NewNonNull(graphql.String),
+		Description: "The ID of the client",
+	}
+
+	return graphql.Fields{
+		"clientTimeline": &graphql.Field{
+			Type:        graphql.NewNonNull(TimelineEntryConnectionType),
+			Description: "Get what happened to a client, newest first: bookings, visits, payments, loyalty points, campaign messages, service records and reviews",
+			Args:        args,
+			Resolve:     resolver.resolveClientTimeline,
+		},
+	}
+}
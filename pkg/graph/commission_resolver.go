@@ -0,0 +1,76 @@
+package graph
+
+import (
+	"errors"
+	"time"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Commission Query Resolvers
+func (r *Resolver) resolveCurrentCommissionPlan(p graphql.ResolveParams) (any, error) {
+	staffID, ok := p.Args["staffId"].(string)
+	if !ok {
+		return nil, errors.New("staffId is required")
+	}
+
+	plan, err := r.commissionService.GetCurrentPlan(p.Context, staffID)
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+func (r *Resolver) resolveCommissionPlanHistory(p graphql.ResolveParams) (any, error) {
+	staffID, ok := p.Args["staffId"].(string)
+	if !ok {
+		return nil, errors.New("staffId is required")
+	}
+
+	plans, err := r.commissionService.GetPlanHistory(p.Context, staffID)
+	if err != nil {
+		return nil, err
+	}
+
+	return plans, nil
+}
+
+func (r *Resolver) resolveStaffCommission(p graphql.ResolveParams) (any, error) {
+	staffID, ok := p.Args["staffId"].(string)
+	if !ok {
+		return nil, errors.New("staffId is required")
+	}
+	start, ok := p.Args["start"].(time.Time)
+	if !ok {
+		return nil, errors.New("start is required")
+	}
+	end, ok := p.Args["end"].(time.Time)
+	if !ok {
+		return nil, errors.New("end is required")
+	}
+
+	report, err := r.commissionService.CalculateCommission(p.Context, staffID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// Commission Mutation Resolvers
+func (r *Resolver) resolveSetCommissionPlan(p graphql.ResolveParams) (any, error) {
+	createDTO := dto.CreateCommissionPlanDTO{}
+	if err := decodeInput(p.Args, &createDTO); err != nil {
+		return nil, err
+	}
+
+	plan, err := r.commissionService.SetCommissionPlan(p.Context, createDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
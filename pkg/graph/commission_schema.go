@@ -0,0 +1,293 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+)
+
+// CommissionTierType represents the GraphQL type for a commission tier
+var CommissionTierType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "CommissionTier",
+	Description: "A commission rate applied to service revenue above a threshold",
+	Fields: graphql.Fields{
+		"threshold": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The revenue above which the rate applies",
+		},
+		"rate": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The commission percentage",
+		},
+	},
+})
+
+// ServiceCommissionOverrideType represents the GraphQL type for a service-specific commission rate
+var ServiceCommissionOverrideType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ServiceCommissionOverride",
+	Description: "A commission rate that replaces the tiered rate for a service",
+	Fields: graphql.Fields{
+		"serviceId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the service",
+		},
+		"rate": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The commission percentage",
+		},
+	},
+})
+
+// CommissionPlanType represents the GraphQL CommissionPlan type
+var CommissionPlanType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "CommissionPlan",
+	Description: "A staff member's commission configuration over a period",
+	Fields: withBaseFields("commission plan", graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"staffId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the staff member",
+		},
+		"effectiveFrom": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the plan takes effect",
+		},
+		"effectiveTo": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the plan was superseded",
+		},
+		"serviceTiers": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(CommissionTierType))),
+			Description: "The tiered service commission rates",
+		},
+		"productRate": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The commission percentage on retail product sales",
+		},
+		"serviceOverrides": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(ServiceCommissionOverrideType))),
+			Description: "The service-specific commission rates",
+		},
+		"notes": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Notes about the plan",
+		},
+		"isCurrent": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether the plan is currently in effect",
+		},
+	}),
+})
+
+// TierCommissionType represents the GraphQL type for the commission earned within a tier
+var TierCommissionType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "TierCommission",
+	Description: "The commission earned within a single tier",
+	Fields: graphql.Fields{
+		"threshold": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The threshold of the tier",
+		},
+		"rate": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The commission percentage of the tier",
+		},
+		"revenue": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The revenue paid at this tier",
+		},
+		"commission": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The commission earned at this tier",
+		},
+	},
+})
+
+// CommissionReportType represents the GraphQL type for a commission report
+var CommissionReportType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "CommissionReport",
+	Description: "The commission earned by a staff member over a period",
+	Fields: graphql.Fields{
+		"staffId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the staff member",
+		},
+		"periodStart": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "The start of the period",
+		},
+		"periodEnd": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "The end of the period",
+		},
+		"serviceRevenue": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "Total service revenue",
+		},
+		"tieredRevenue": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "Service revenue paid on the tiered rates",
+		},
+		"overrideRevenue": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "Service revenue paid on service-specific rates",
+		},
+		"productRevenue": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "Retail product revenue",
+		},
+		"tiers": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(TierCommissionType))),
+			Description: "The commission earned per tier",
+		},
+		"tieredCommission": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "Commission from tiered service revenue",
+		},
+		"overrideCommission": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "Commission from service-specific rates",
+		},
+		"productCommission": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "Commission from retail product sales",
+		},
+		"total": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "Total commission earned",
+		},
+		"planIds": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+			Description: "The IDs of the plans applied",
+		},
+	},
+})
+
+// CommissionTierInput represents the GraphQL input for a commission tier
+var CommissionTierInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "CommissionTierInput",
+	Description: "Input for a commission tier",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"threshold": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The revenue above which the rate applies; the first tier starts at 0",
+		},
+		"rate": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The commission percentage",
+		},
+	},
+})
+
+// ServiceCommissionOverrideInput represents the GraphQL input for a service-specific commission rate
+var ServiceCommissionOverrideInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "ServiceCommissionOverrideInput",
+	Description: "Input for a service-specific commission rate",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"serviceId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the service",
+		},
+		"rate": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The commission percentage",
+		},
+	},
+})
+
+// SetCommissionPlanInput represents the GraphQL input for setting a commission plan
+var SetCommissionPlanInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "SetCommissionPlanInput",
+	Description: "Input for setting a staff member's commission plan",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"staffId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the staff member",
+		},
+		"effectiveFrom": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the plan takes effect",
+		},
+		"serviceTiers": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(CommissionTierInput))),
+			Description: "The tiered service commission rates",
+		},
+		"productRate": &graphql.InputObjectFieldConfig{
+			Type:        DecimalScalar,
+			Description: "The commission percentage on retail product sales",
+		},
+		"serviceOverrides": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewList(graphql.NewNonNull(ServiceCommissionOverrideInput)),
+			Description: "The service-specific commission rates",
+		},
+		"notes": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "Notes about the plan",
+		},
+	},
+})
+
+// commissionQueryFields returns the staff commission queries
+func commissionQueryFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"currentCommissionPlan": &graphql.Field{
+			Type:        CommissionPlanType,
+			Description: "Get the commission plan currently in effect for a staff member",
+			Args: graphql.FieldConfigArgument{
+				"staffId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the staff member",
+				},
+			},
+			Resolve: resolver.resolveCurrentCommissionPlan,
+		},
+		"commissionPlanHistory": &graphql.Field{
+			Type:        graphql.NewList(CommissionPlanType),
+			Description: "Get the commission plan history of a staff member",
+			Args: graphql.FieldConfigArgument{
+				"staffId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the staff member",
+				},
+			},
+			Resolve: resolver.resolveCommissionPlanHistory,
+		},
+		"staffCommission": &graphql.Field{
+			Type:        CommissionReportType,
+			Description: "Calculate the commission earned by a staff member over a period",
+			Args: graphql.FieldConfigArgument{
+				"staffId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the staff member",
+				},
+				"start": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.DateTime),
+					Description: "The start of the period",
+				},
+				"end": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.DateTime),
+					Description: "The end of the period (exclusive)",
+				},
+			},
+			Resolve: resolver.resolveStaffCommission,
+		},
+	}
+}
+
+// commissionMutationFields returns the staff commission mutations
+func commissionMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"setCommissionPlan": &graphql.Field{
+			Type:        CommissionPlanType,
+			Description: "Set a new commission plan for a staff member, closing the current one",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(SetCommissionPlanInput),
+					Description: "The commission plan data",
+				},
+			},
+			Resolve: resolver.resolveSetCommissionPlan,
+		},
+	}
+}
@@ -0,0 +1,113 @@
+package graph
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/assimoes/beautix/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+// SMSReplyPath is the path the SMS provider posts inbound messages to
+const SMSReplyPath = "/webhooks/sms/reply"
+
+// confirmationPageData is rendered by confirmationTemplate
+type confirmationPageData struct {
+	*dto.ConfirmationResultDTO
+	Confirmed bool
+}
+
+// confirmationTemplate renders the page a client lands on from a confirmation link
+var confirmationTemplate = template.Must(template.New("confirmation").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+    <title>{{.BusinessName}}</title>
+    <style>
+        body { font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; margin: 40px auto; max-width: 480px; padding: 0 16px; color: #333; text-align: center; }
+        button { font-size: 18px; padding: 12px 32px; border: none; border-radius: 6px; background: #333; color: #fff; cursor: pointer; }
+    </style>
+</head>
+<body>
+    <h1>{{.BusinessName}}</h1>
+    <p>Your appointment on <strong>{{.StartTime.Format "Monday, 2 January"}}</strong> at <strong>{{.StartTime.Format "15:04"}}</strong></p>
+    {{if .Confirmed}}
+    <p>is confirmed. See you soon!</p>
+    {{else}}
+    <form method="POST">
+        <button type="submit">Confirm appointment</button>
+    </form>
+    {{end}}
+</body>
+</html>
+`))
+
+// ConfirmationHandler serves the page a client opens from a confirmation request. Opening the
+// page shows the appointment; submitting it confirms the appointment. It must be registered
+// under service.ConfirmationLinkPath.
+func ConfirmationHandler(confirmationService service.AppointmentConfirmationService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Path[len(service.ConfirmationLinkPath):]
+
+		var result *dto.ConfirmationResultDTO
+		var err error
+		switch r.Method {
+		case http.MethodGet:
+			result, err = confirmationService.GetConfirmationRequest(r.Context(), token)
+		case http.MethodPost:
+			result, err = confirmationService.ConfirmByToken(r.Context(), token)
+		default:
+			http.Error(w, "Only GET and POST requests are allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if err != nil {
+			var notFound service.NotFoundError
+			var invalid *validation.ValidationError
+			switch {
+			case errors.As(err, &notFound):
+				http.Error(w, "This confirmation link is not valid", http.StatusNotFound)
+			case errors.As(err, &invalid):
+				http.Error(w, invalid.Error(), http.StatusConflict)
+			default:
+				http.Error(w, "Failed to confirm appointment", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		data := confirmationPageData{
+			ConfirmationResultDTO: result,
+			Confirmed:             result.AlreadyConfirmed || r.Method == http.MethodPost,
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := confirmationTemplate.Execute(w, data); err != nil {
+			http.Error(w, "Failed to render page", http.StatusInternalServerError)
+		}
+	}
+}
+
+// SMSReplyHandler receives inbound SMS from the provider as a form post with From and Body
+// fields, and confirms the sender's appointment when the message is a confirmation keyword.
+// Other messages are acknowledged and ignored.
+func SMSReplyHandler(confirmationService service.AppointmentConfirmationService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Only POST requests are allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Invalid form body", http.StatusBadRequest)
+			return
+		}
+
+		if _, err := confirmationService.HandleSMSReply(r.Context(), r.PostForm.Get("From"), r.PostForm.Get("Body")); err != nil {
+			log.Error().Err(err).Msg("Failed to handle SMS reply")
+			http.Error(w, "Failed to handle message", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,43 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Data Residency Query Resolvers
+func (r *Resolver) resolveBusinessDataResidency(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+
+	residency, err := r.dataResidencyService.GetBusinessDataResidency(p.Context, businessID)
+	if err != nil {
+		return nil, err
+	}
+
+	return residency, nil
+}
+
+// Data Residency Mutation Resolvers
+func (r *Resolver) resolveSetBusinessDataRegion(p graphql.ResolveParams) (any, error) {
+	regionDTO := dto.SetBusinessDataRegionDTO{}
+	if businessID, ok := p.Args["businessId"].(string); ok {
+		regionDTO.BusinessID = businessID
+	}
+	if region, ok := p.Args["region"].(domain.DataRegion); ok {
+		regionDTO.Region = region
+	}
+
+	residency, err := r.dataResidencyService.SetBusinessDataRegion(p.Context, regionDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return residency, nil
+}
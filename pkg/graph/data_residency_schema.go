@@ -0,0 +1,130 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// DataRegionEnum represents the GraphQL enum for the region a business's data must stay in
+var DataRegionEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "DataRegion",
+	Description: "The region a business's data must stay in",
+	Values: graphql.EnumValueConfigMap{
+		"EU": &graphql.EnumValueConfig{
+			Value:       domain.DataRegionEU,
+			Description: "Data stays in the European Union",
+		},
+		"US": &graphql.EnumValueConfig{
+			Value:       domain.DataRegionUS,
+			Description: "Data is hosted in the United States and may flow to any region",
+		},
+	},
+})
+
+// DataDestinationKindEnum represents the GraphQL enum for what a data destination is used for
+var DataDestinationKindEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "DataDestinationKind",
+	Description: "What a data destination is used for",
+	Values: graphql.EnumValueConfigMap{
+		"STORAGE": &graphql.EnumValueConfig{
+			Value:       domain.DataDestinationStorage,
+			Description: "Object storage for files such as photos and exports",
+		},
+		"WAREHOUSE": &graphql.EnumValueConfig{
+			Value:       domain.DataDestinationWarehouse,
+			Description: "Analytics warehouse business data is exported to",
+		},
+		"PROVIDER": &graphql.EnumValueConfig{
+			Value:       domain.DataDestinationProvider,
+			Description: "Third-party API called with business data",
+		},
+	},
+})
+
+// DataDestinationType represents the GraphQL DataDestination type
+var DataDestinationType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "DataDestination",
+	Description: "A destination outside the database and whether a business's data may be sent to it",
+	Fields: graphql.Fields{
+		"name": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The name of the destination",
+		},
+		"kind": &graphql.Field{
+			Type:        graphql.NewNonNull(DataDestinationKindEnum),
+			Description: "What the destination is used for",
+		},
+		"region": &graphql.Field{
+			Type:        DataRegionEnum,
+			Description: "The region the destination is in, when known",
+		},
+		"allowed": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether the business's data may be sent to the destination",
+		},
+	},
+})
+
+// DataResidencyType represents the GraphQL DataResidency type
+var DataResidencyType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "DataResidency",
+	Description: "Where a business's data may go",
+	Fields: graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"region": &graphql.Field{
+			Type:        graphql.NewNonNull(DataRegionEnum),
+			Description: "The region the business's data must stay in",
+		},
+		"storageBucket": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The bucket the business's files are stored in; null when no bucket in a permitted region is configured",
+		},
+		"destinations": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(DataDestinationType))),
+			Description: "Every known destination",
+		},
+	},
+})
+
+// dataResidencyQueryFields returns the data residency queries
+func dataResidencyQueryFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"businessDataResidency": &graphql.Field{
+			Type:        DataResidencyType,
+			Description: "Get the region a business's data must stay in and the destinations it may be sent to",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+			},
+			Resolve: resolver.resolveBusinessDataResidency,
+		},
+	}
+}
+
+// dataResidencyMutationFields returns the data residency mutations
+func dataResidencyMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"setBusinessDataRegion": &graphql.Field{
+			Type: DataResidencyType,
+			Description: "Change the region a business's data must stay in (platform administrators only). " +
+				"Data already sent to destinations is not moved.",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+				"region": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(DataRegionEnum),
+					Description: "The new region",
+				},
+			},
+			Resolve: resolver.resolveSetBusinessDataRegion,
+		},
+	}
+}
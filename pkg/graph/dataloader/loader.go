@@ -0,0 +1,79 @@
+// Package dataloader batches the lookups of related entities made while resolving one GraphQL
+// request. Resolvers ask a loader for an entity and get back a thunk; graphql-go runs the
+// thunks of a level only once every sibling field has been resolved, so the first thunk fetches
+// the IDs requested by all of them in a single query. Results are cached for the rest of the
+// request.
+package dataloader
+
+import (
+	"context"
+	"sync"
+)
+
+// BatchFunc fetches the entities with the given IDs. Missing IDs are left out of the result.
+type BatchFunc[T any] func(ctx context.Context, ids []string) ([]*T, error)
+
+// Loader batches and caches lookups of one entity type by ID. A loader belongs to a single
+// request.
+type Loader[T any] struct {
+	fetch   BatchFunc[T]
+	idOf    func(*T) string
+	mu      sync.Mutex
+	pending []string
+	results map[string]*result[T]
+}
+
+// result is the outcome of looking up one ID
+type result[T any] struct {
+	entity  *T
+	err     error
+	fetched bool
+}
+
+// NewLoader creates a loader fetching entities with fetch and matching them to the requested
+// IDs with idOf
+func NewLoader[T any](fetch BatchFunc[T], idOf func(*T) string) *Loader[T] {
+	return &Loader[T]{fetch: fetch, idOf: idOf, results: map[string]*result[T]{}}
+}
+
+// Load queues the ID for the next batch and returns a thunk yielding its entity, or nil when
+// there is none. Calling the thunk fetches every queued ID that has not been fetched yet.
+func (l *Loader[T]) Load(ctx context.Context, id string) func() (*T, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	res, ok := l.results[id]
+	if !ok {
+		res = &result[T]{}
+		l.results[id] = res
+		l.pending = append(l.pending, id)
+	}
+
+	return func() (*T, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if !res.fetched {
+			l.dispatch(ctx)
+		}
+		return res.entity, res.err
+	}
+}
+
+// dispatch fetches the queued IDs. The caller must hold the lock.
+func (l *Loader[T]) dispatch(ctx context.Context) {
+	ids := l.pending
+	l.pending = nil
+	entities, err := l.fetch(ctx, ids)
+
+	found := make(map[string]*T, len(entities))
+	for _, entity := range entities {
+		found[l.idOf(entity)] = entity
+	}
+	for _, id := range ids {
+		res := l.results[id]
+		res.entity, res.err, res.fetched = found[id], err, true
+		if err != nil {
+			// Let a later request retry instead of caching the failure
+			delete(l.results, id)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package dataloader
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type item struct {
+	ID string
+}
+
+func TestLoader_BatchesAndCaches(t *testing.T) {
+	ctx := context.Background()
+	var batches [][]string
+	loader := NewLoader(func(ctx context.Context, ids []string) ([]*item, error) {
+		batches = append(batches, ids)
+		var items []*item
+		for _, id := range ids {
+			if id != "missing" {
+				items = append(items, &item{ID: id})
+			}
+		}
+		return items, nil
+	}, func(i *item) string { return i.ID })
+
+	first := loader.Load(ctx, "a")
+	second := loader.Load(ctx, "b")
+	repeated := loader.Load(ctx, "a")
+	missing := loader.Load(ctx, "missing")
+
+	a, err := first()
+	require.NoError(t, err)
+	assert.Equal(t, "a", a.ID)
+	b, err := second()
+	require.NoError(t, err)
+	assert.Equal(t, "b", b.ID)
+	again, err := repeated()
+	require.NoError(t, err)
+	assert.Same(t, a, again)
+	none, err := missing()
+	require.NoError(t, err)
+	assert.Nil(t, none)
+	assert.Equal(t, [][]string{{"a", "b", "missing"}}, batches, "every queued ID is fetched in one batch")
+
+	cached, err := loader.Load(ctx, "b")()
+	require.NoError(t, err)
+	assert.Same(t, b, cached)
+	_, err = loader.Load(ctx, "c")()
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"a", "b", "missing"}, {"c"}}, batches, "only IDs not fetched yet are queued")
+}
+
+func TestLoader_DoesNotCacheFailures(t *testing.T) {
+	ctx := context.Background()
+	failing := true
+	loader := NewLoader(func(ctx context.Context, ids []string) ([]*item, error) {
+		if failing {
+			return nil, errors.New("database unavailable")
+		}
+		return []*item{{ID: ids[0]}}, nil
+	}, func(i *item) string { return i.ID })
+
+	_, err := loader.Load(ctx, "a")()
+	assert.EqualError(t, err, "database unavailable")
+
+	failing = false
+	a, err := loader.Load(ctx, "a")()
+	require.NoError(t, err)
+	assert.Equal(t, "a", a.ID)
+}
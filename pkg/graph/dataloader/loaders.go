@@ -0,0 +1,60 @@
+package dataloader
+
+import (
+	"context"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// Sources are the repositories the loaders fetch from
+type Sources struct {
+	Users        domain.BaseRepository[domain.User]
+	Clients      domain.BaseRepository[domain.Client]
+	Staff        domain.BaseRepository[domain.Staff]
+	Businesses   domain.BaseRepository[domain.Business]
+	Services     domain.BaseRepository[domain.Service]
+	Appointments domain.BaseRepository[domain.Appointment]
+}
+
+// Loaders holds the loaders of one request
+type Loaders struct {
+	Users        *Loader[domain.User]
+	Clients      *Loader[domain.Client]
+	Staff        *Loader[domain.Staff]
+	Businesses   *Loader[domain.Business]
+	Services     *Loader[domain.Service]
+	Appointments *Loader[domain.Appointment]
+}
+
+// New creates a fresh set of loaders for a request
+func New(sources Sources) *Loaders {
+	return &Loaders{
+		Users:        newLoader(sources.Users),
+		Clients:      newLoader(sources.Clients),
+		Staff:        newLoader(sources.Staff),
+		Businesses:   newLoader(sources.Businesses),
+		Services:     newLoader(sources.Services),
+		Appointments: newLoader(sources.Appointments),
+	}
+}
+
+// newLoader creates a loader fetching from a repository
+func newLoader[T any, PT interface {
+	*T
+	GetID() string
+}](repo domain.BaseRepository[T]) *Loader[T] {
+	return NewLoader(repo.GetByIDs, func(entity *T) string { return PT(entity).GetID() })
+}
+
+type loadersContextKey struct{}
+
+// WithLoaders returns a context carrying the loaders of a request
+func WithLoaders(ctx context.Context, loaders *Loaders) context.Context {
+	return context.WithValue(ctx, loadersContextKey{}, loaders)
+}
+
+// For returns the loaders of the request, or nil when the context has none
+func For(ctx context.Context) *Loaders {
+	loaders, _ := ctx.Value(loadersContextKey{}).(*Loaders)
+	return loaders
+}
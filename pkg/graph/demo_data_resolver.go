@@ -0,0 +1,41 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Demo Data Mutation Resolvers
+func (r *Resolver) resolveSetBusinessSandbox(p graphql.ResolveParams) (any, error) {
+	sandboxDTO := dto.SetBusinessSandboxDTO{}
+	if businessID, ok := p.Args["businessId"].(string); ok {
+		sandboxDTO.BusinessID = businessID
+	}
+	if isSandbox, ok := p.Args["isSandbox"].(bool); ok {
+		sandboxDTO.IsSandbox = isSandbox
+	}
+
+	business, err := r.demoDataService.SetBusinessSandbox(p.Context, sandboxDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return business, nil
+}
+
+func (r *Resolver) resolveResetDemoData(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+
+	reset, err := r.demoDataService.ResetDemoData(p.Context, businessID)
+	if err != nil {
+		return nil, err
+	}
+
+	return reset, nil
+}
@@ -0,0 +1,67 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+)
+
+// DemoDataResetType represents the GraphQL DemoDataReset type
+var DemoDataResetType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "DemoDataReset",
+	Description: "The standard demo dataset a sandbox business was reset to",
+	Fields: graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"servicesCreated": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "How many demo catalogue services the business did not have yet and were created",
+		},
+		"clients": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "How many clients were seeded",
+		},
+		"appointments": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "How many appointments were seeded, past and upcoming",
+		},
+		"resetAt": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the data was reset; seeded appointments are placed around this time",
+		},
+	},
+})
+
+// demoDataMutationFields returns the sandbox business mutations
+func demoDataMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"setBusinessSandbox": &graphql.Field{
+			Type:        BusinessType,
+			Description: "Mark a business as a sandbox whose data can be reset, or back as a real business (platform admin only)",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+				"isSandbox": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.Boolean),
+					Description: "Whether the business is a sandbox",
+				},
+			},
+			Resolve: resolver.resolveSetBusinessSandbox,
+		},
+		"resetDemoData": &graphql.Field{
+			Type: DemoDataResetType,
+			Description: "Wipe the clients, appointments, payments, invoices, messages and events of a sandbox business and " +
+				"reseed the standard demo dataset in one transaction; services, staff and settings are kept " +
+				"(owners, managers and platform admins only)",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the sandbox business",
+				},
+			},
+			Resolve: resolver.resolveResetDemoData,
+		},
+	}
+}
@@ -0,0 +1,270 @@
+//go:build integration
+// +build integration
+
+package graph_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/repository"
+	"github.com/assimoes/beautix/internal/service"
+	"github.com/assimoes/beautix/pkg/graph"
+	"github.com/assimoes/beautix/pkg/graph/dataloader"
+	"github.com/assimoes/beautix/utils/testdb"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// e2eStack is the full resolver stack wired to the test database as in cmd/api
+type e2eStack struct {
+	handler  http.HandlerFunc
+	fixtures *testdb.FixtureBuilder
+	queries  map[string]*atomic.Int64
+}
+
+// caller is the authenticated user a request is made as
+type caller struct {
+	userID     string
+	role       string
+	businessID string
+}
+
+func newE2EStack(t *testing.T) *e2eStack {
+	db := testdb.NewTestDB(t)
+	gormDB := db.GetDB().DB
+	validate := validator.New()
+	dto.RegisterOptionalTypes(validate)
+
+	userRepo := repository.NewUserRepository(gormDB)
+	businessRepo := repository.NewBusinessRepository(gormDB)
+	staffRepo := repository.NewStaffRepository(gormDB)
+	clientRepo := repository.NewBaseRepository[domain.Client](gormDB, repository.WithTenantScope())
+	serviceRepo := repository.NewBaseRepository[domain.Service](gormDB, repository.WithTenantScope())
+	appointmentRepo := repository.NewAppointmentRepository(gormDB)
+	serviceRecordRepo := repository.NewServiceRecordRepository(gormDB)
+
+	userService := service.NewUserService(userRepo, businessRepo, staffRepo, validate)
+	authService := service.NewAuthService(userRepo, nil, gormDB)
+	serviceRecordService := service.NewServiceRecordService(serviceRecordRepo, repository.NewServiceRecordTemplateRepository(gormDB),
+		repository.NewServiceCompletionRepository(gormDB), serviceRepo, appointmentRepo, clientRepo, staffRepo, validate)
+	anonymousFeedbackService := service.NewAnonymousFeedbackService(repository.NewAnonymousFeedbackRepository(gormDB),
+		appointmentRepo, businessRepo, staffRepo, validate, "https://beautix.test")
+
+	resolver := graph.NewResolver(userService, authService,
+		graph.WithServiceRecordService(serviceRecordService),
+		graph.WithAnonymousFeedbackService(anonymousFeedbackService),
+	)
+	schema, err := graph.CreateSchema(resolver)
+	require.NoError(t, err)
+
+	// Count the queries per table to observe the batching of the dataloaders
+	queries := map[string]*atomic.Int64{}
+	for _, table := range []string{"clients", "staff", "services"} {
+		queries[table] = &atomic.Int64{}
+	}
+	err = gormDB.Callback().Query().After("gorm:query").Register("e2e:count_queries", func(tx *gorm.DB) {
+		if counter, ok := queries[tx.Statement.Table]; ok {
+			counter.Add(1)
+		}
+	})
+	require.NoError(t, err)
+
+	return &e2eStack{
+		handler: graph.Handler(schema, graph.WithLoaders(dataloader.Sources{
+			Users:        userRepo,
+			Clients:      clientRepo,
+			Staff:        staffRepo,
+			Businesses:   businessRepo,
+			Services:     serviceRepo,
+			Appointments: appointmentRepo,
+		})),
+		fixtures: testdb.NewFixtureBuilder(db.GetDB()),
+		queries:  queries,
+	}
+}
+
+// execute posts a query to the GraphQL endpoint as the caller, standing in for the
+// authentication middleware by putting the caller in the request context
+func (s *e2eStack) execute(t *testing.T, as *caller, query string, variables map[string]any) graph.GraphQLResponse {
+	body, err := json.Marshal(graph.GraphQLRequest{Query: query, Variables: variables})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	if as != nil {
+		ctx = service.SetUserContext(ctx, as.userID, as.role, &as.businessID)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body)).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	s.handler(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response graph.GraphQLResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	return response
+}
+
+// salon is a business seeded with an owner and an employee
+type salon struct {
+	business *domain.Business
+	owner    *domain.Staff
+	employee *domain.Staff
+}
+
+func seedSalon(t *testing.T, fixtures *testdb.FixtureBuilder) *salon {
+	ownerUser, err := fixtures.CreateUser()
+	require.NoError(t, err)
+	employeeUser, err := fixtures.CreateUser()
+	require.NoError(t, err)
+
+	business, err := fixtures.CreateBusiness(ownerUser.ID)
+	require.NoError(t, err)
+	owner, err := fixtures.CreateStaff(business.ID, ownerUser.ID, func(s *domain.Staff) {
+		s.Role = domain.BusinessRoleOwner
+	})
+	require.NoError(t, err)
+	employee, err := fixtures.CreateStaff(business.ID, employeeUser.ID)
+	require.NoError(t, err)
+
+	return &salon{business: business, owner: owner, employee: employee}
+}
+
+func (s *salon) as(staff *domain.Staff) *caller {
+	return &caller{userID: staff.UserID, role: string(staff.Role), businessID: s.business.ID}
+}
+
+func TestEndToEnd_Authorization(t *testing.T) {
+	stack := newE2EStack(t)
+	salon := seedSalon(t, stack.fixtures)
+
+	query := `
+		query($businessId: String!) {
+			anonymousFeedback(businessId: $businessId) {
+				id
+				rating
+			}
+		}
+	`
+	variables := map[string]any{"businessId": salon.business.ID}
+
+	t.Run("owner can read the inbox", func(t *testing.T) {
+		response := stack.execute(t, salon.as(salon.owner), query, variables)
+		require.Empty(t, response.Errors)
+		assert.Equal(t, map[string]any{"anonymousFeedback": []any{}}, response.Data)
+	})
+
+	t.Run("employee is forbidden", func(t *testing.T) {
+		response := stack.execute(t, salon.as(salon.employee), query, variables)
+		require.Len(t, response.Errors, 1)
+		assert.Equal(t, "not allowed to read anonymous feedback", response.Errors[0].Message)
+	})
+
+	t.Run("anonymous caller is forbidden", func(t *testing.T) {
+		response := stack.execute(t, nil, query, variables)
+		require.Len(t, response.Errors, 1)
+		assert.Equal(t, "not allowed to read anonymous feedback", response.Errors[0].Message)
+	})
+
+	t.Run("owner of another business is forbidden", func(t *testing.T) {
+		other := seedSalon(t, stack.fixtures)
+		response := stack.execute(t, other.as(other.owner), query, variables)
+		require.Len(t, response.Errors, 1)
+		assert.Equal(t, "not allowed to read anonymous feedback", response.Errors[0].Message)
+	})
+}
+
+func TestEndToEnd_RelationsAreBatched(t *testing.T) {
+	stack := newE2EStack(t)
+	salon := seedSalon(t, stack.fixtures)
+
+	haircut, err := stack.fixtures.CreateService(salon.business.ID, func(s *domain.Service) { s.Name = "Haircut" })
+	require.NoError(t, err)
+	colour, err := stack.fixtures.CreateService(salon.business.ID, func(s *domain.Service) { s.Name = "Colour" })
+	require.NoError(t, err)
+
+	clients := map[string]string{}
+	for _, name := range []string{"Ana", "Rita", "Sofia"} {
+		client, err := stack.fixtures.CreateClient(salon.business.ID, func(c *domain.Client) { c.FirstName = name })
+		require.NoError(t, err)
+		clients[client.ID] = name
+		for _, svc := range []*domain.Service{haircut, colour} {
+			_, err := stack.fixtures.CreateServiceRecord(salon.business.ID, client.ID, func(r *domain.ServiceRecord) {
+				r.ServiceID = &svc.ID
+				r.StaffID = &salon.employee.ID
+			})
+			require.NoError(t, err)
+		}
+	}
+	for _, counter := range stack.queries {
+		counter.Store(0)
+	}
+
+	response := stack.execute(t, salon.as(salon.owner), `
+		query($businessId: String!) {
+			searchServiceRecords(businessId: $businessId) {
+				clientId
+				client { id firstName }
+				service { name }
+				staff { id role }
+			}
+		}
+	`, map[string]any{"businessId": salon.business.ID})
+	require.Empty(t, response.Errors)
+
+	records := response.Data.(map[string]any)["searchServiceRecords"].([]any)
+	require.Len(t, records, 6)
+	for _, r := range records {
+		record := r.(map[string]any)
+		client := record["client"].(map[string]any)
+		assert.Equal(t, record["clientId"], client["id"])
+		assert.Equal(t, clients[client["id"].(string)], client["firstName"])
+		assert.Contains(t, []any{"Haircut", "Colour"}, record["service"].(map[string]any)["name"])
+		assert.Equal(t, salon.employee.ID, record["staff"].(map[string]any)["id"])
+		assert.Equal(t, string(domain.BusinessRoleEmployee), record["staff"].(map[string]any)["role"])
+	}
+
+	// One query per relation, however many records reference it
+	assert.Equal(t, int64(1), stack.queries["clients"].Load())
+	assert.Equal(t, int64(1), stack.queries["services"].Load())
+	assert.Equal(t, int64(1), stack.queries["staff"].Load())
+}
+
+func TestEndToEnd_TenantScopedRelations(t *testing.T) {
+	stack := newE2EStack(t)
+	salon := seedSalon(t, stack.fixtures)
+	other := seedSalon(t, stack.fixtures)
+
+	// A record pointing at another business's client must not leak that client
+	foreign, err := stack.fixtures.CreateClient(other.business.ID)
+	require.NoError(t, err)
+	_, err = stack.fixtures.CreateServiceRecord(salon.business.ID, foreign.ID)
+	require.NoError(t, err)
+
+	query := `
+		query($clientId: String!) {
+			clientServiceHistory(clientId: $clientId) {
+				clientId
+				client { id firstName }
+			}
+		}
+	`
+	variables := map[string]any{"clientId": foreign.ID}
+
+	response := stack.execute(t, salon.as(salon.owner), query, variables)
+	require.Len(t, response.Errors, 1)
+	assert.Contains(t, response.Errors[0].Message, "not found")
+
+	// The client's own business does not see the other business's record either
+	response = stack.execute(t, other.as(other.owner), query, variables)
+	require.Empty(t, response.Errors)
+	assert.Empty(t, response.Data.(map[string]any)["clientServiceHistory"])
+}
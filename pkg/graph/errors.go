@@ -0,0 +1,125 @@
+package graph
+
+import (
+	"errors"
+	"maps"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/graphql-go/graphql/gqlerrors"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/infrastructure/i18n"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/assimoes/beautix/internal/service"
+)
+
+// ErrorCodeVersionConflict is the code of errors reporting an update to an outdated version
+// of an entity, which clients resolve by reloading the entity and trying again
+const ErrorCodeVersionConflict = "VERSION_CONFLICT"
+
+// ErrorCodeUpgradeRequired is the code of errors reporting that the business has used up an
+// entitlement of its subscription plan, which clients answer by offering the upgrade
+const ErrorCodeUpgradeRequired = "UPGRADE_REQUIRED"
+
+// ErrorCodeAppointmentConflict is the code of errors reporting that an appointment cannot be
+// booked at its time, with the reasons in the conflicts extension
+const ErrorCodeAppointmentConflict = "APPOINTMENT_CONFLICT"
+
+// ErrorCodeValidation is the code of errors reporting invalid input, with the fields that
+// failed in the fields extension when they are known
+const ErrorCodeValidation = "VALIDATION_ERROR"
+
+// ErrorCodeNotFound is the code of errors reporting that an entity does not exist
+const ErrorCodeNotFound = "NOT_FOUND"
+
+// ErrorCodeForbidden is the code of errors reporting that the caller may not do what they asked
+const ErrorCodeForbidden = "FORBIDDEN"
+
+// errorExtensions returns the extensions of an error returned to the client, which tell
+// clients the kind of error by its code. Resolver errors are looked into for the errors of
+// known kinds they wrap, and carry a localizedMessage in the caller's locale that clients can
+// show as it is.
+func errorExtensions(err gqlerrors.FormattedError, locale i18n.Locale) map[string]any {
+	extensions := maps.Clone(err.Extensions)
+	cause := err.OriginalError()
+	var located *gqlerrors.Error
+	if errors.As(cause, &located) {
+		cause = located.OriginalError
+	}
+	if cause == nil {
+		// Errors in the query itself are for the developers of the client
+		return extensions
+	}
+	if extensions == nil {
+		extensions = map[string]any{}
+	}
+
+	var conflict *domain.ConflictError
+	var upgrade *domain.UpgradeRequiredError
+	var appointmentConflict *domain.AppointmentConflictError
+	var invalid *validation.ValidationError
+	var notFound service.NotFoundError
+	var forbidden service.ForbiddenError
+	switch {
+	case errors.As(cause, &conflict):
+		extensions["code"] = ErrorCodeVersionConflict
+		extensions["entityId"] = conflict.ID
+		extensions["expectedVersion"] = conflict.Version
+	case errors.As(cause, &upgrade):
+		extensions["code"] = ErrorCodeUpgradeRequired
+		extensions["entitlement"] = upgrade.Entitlement
+		extensions["tier"] = upgrade.Tier
+		extensions["limit"] = upgrade.Limit
+		if upgrade.RequiredTier != "" {
+			extensions["requiredTier"] = upgrade.RequiredTier
+		}
+	case errors.As(cause, &appointmentConflict):
+		extensions["code"] = ErrorCodeAppointmentConflict
+		conflicts := make([]map[string]any, len(appointmentConflict.Conflicts))
+		for i, c := range appointmentConflict.Conflicts {
+			conflicts[i] = map[string]any{
+				"reason":  strings.ToUpper(string(c.Reason)),
+				"message": i18n.Message(locale, "conflict."+string(c.Reason), nil),
+			}
+		}
+		extensions["conflicts"] = conflicts
+	case errors.As(cause, &invalid), errors.Is(cause, domain.ErrValidation):
+		extensions["code"] = ErrorCodeValidation
+		var fieldErrors validator.ValidationErrors
+		if errors.As(cause, &fieldErrors) {
+			fields := make([]map[string]any, len(fieldErrors))
+			for i, fieldError := range fieldErrors {
+				fields[i] = map[string]any{
+					"field":   fieldPath(fieldError),
+					"rule":    fieldError.Tag(),
+					"message": i18n.FieldMessage(locale, fieldError),
+				}
+			}
+			extensions["fields"] = fields
+		}
+	case errors.As(cause, &notFound):
+		extensions["code"] = ErrorCodeNotFound
+		extensions["entity"] = notFound.EntityType
+	case errors.As(cause, &forbidden):
+		extensions["code"] = ErrorCodeForbidden
+	}
+
+	key := "error.UNKNOWN"
+	if code, ok := extensions["code"].(string); ok {
+		key = "error." + code
+	}
+	extensions["localizedMessage"] = i18n.Message(locale, key, nil)
+	return extensions
+}
+
+// fieldPath returns the path of the input field that failed a validator rule by its GraphQL
+// names, e.g. "hours.monday.openTime"
+func fieldPath(err validator.FieldError) string {
+	// The namespace starts with the name of the DTO, which is not part of the input
+	segments := strings.Split(err.StructNamespace(), ".")[1:]
+	for i, segment := range segments {
+		segments[i] = i18n.FieldName(segment)
+	}
+	return strings.Join(segments, ".")
+}
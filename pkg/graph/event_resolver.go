@@ -0,0 +1,63 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Event Log Query Resolvers
+func (r *Resolver) resolveDomainEvent(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+
+	event, err := r.eventService.GetEvent(p.Context, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+func (r *Resolver) resolveDomainEvents(p graphql.ResolveParams) (any, error) {
+	var filter dto.DomainEventFilterDTO
+	if err := decodeValue(p.Args["filter"], &filter, "filter"); err != nil {
+		return nil, err
+	}
+	page, pageSize := pageFromArgs(p.Args, 50)
+
+	events, _, err := r.eventService.ListEvents(p.Context, filter, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (r *Resolver) resolveEventConsumers(p graphql.ResolveParams) (any, error) {
+	consumers, err := r.eventService.ListConsumers(p.Context)
+	if err != nil {
+		return nil, err
+	}
+
+	return consumers, nil
+}
+
+// Event Log Mutation Resolvers
+func (r *Resolver) resolveReplayEvents(p graphql.ResolveParams) (any, error) {
+	replayDTO := dto.ReplayEventsDTO{}
+	if err := decodeInput(p.Args, &replayDTO); err != nil {
+		return nil, err
+	}
+
+	result, err := r.eventService.ReplayEvents(p.Context, replayDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
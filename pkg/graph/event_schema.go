@@ -0,0 +1,212 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+)
+
+// DomainEventType represents the GraphQL DomainEvent type
+var DomainEventType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "DomainEvent",
+	Description: "An entry in the domain event log",
+	Fields: withBaseFields("event", graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The ID of the business the event belongs to",
+		},
+		"aggregateType": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The kind of entity the event is about, e.g. appointment",
+		},
+		"aggregateId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the entity the event is about",
+		},
+		"eventType": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "What happened, e.g. appointment.cancelled",
+		},
+		"payload": &graphql.Field{
+			Type:        graphql.NewNonNull(JSONScalar),
+			Description: "The event data",
+		},
+		"occurredAt": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the event happened",
+		},
+		"replayCount": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "How many times the event has been replayed",
+		},
+		"lastReplayedAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the event was last replayed",
+		},
+		"publishedAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the outbox relay published the event to every subscriber",
+		},
+		"publishAttempts": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "How many times the outbox relay tried to publish the event",
+		},
+		"publishError": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Why the last publish attempt failed",
+		},
+		"abandonedAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the outbox relay gave up publishing the event",
+		},
+	}),
+})
+
+// EventDeliveryResultType represents the GraphQL type for the outcome of delivering an event to a consumer
+var EventDeliveryResultType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "EventDeliveryResult",
+	Description: "The outcome of delivering one event to one consumer",
+	Fields: graphql.Fields{
+		"eventId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the event",
+		},
+		"consumer": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The name of the consumer",
+		},
+		"success": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether the consumer handled the event",
+		},
+		"error": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The error returned by the consumer",
+		},
+	},
+})
+
+// ReplayResultType represents the GraphQL type for the outcome of an event replay
+var ReplayResultType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ReplayResult",
+	Description: "The outcome of replaying events to downstream consumers",
+	Fields: graphql.Fields{
+		"events": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The number of events replayed",
+		},
+		"succeeded": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The number of successful deliveries",
+		},
+		"failed": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The number of failed deliveries",
+		},
+		"deliveries": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(EventDeliveryResultType))),
+			Description: "The outcome per event and consumer",
+		},
+	},
+})
+
+// DomainEventFilterInput represents the GraphQL input for browsing the event log
+var DomainEventFilterInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "DomainEventFilterInput",
+	Description: "Criteria for browsing the event log",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"businessId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "Only events of this business",
+		},
+		"aggregateType": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "Only events about this kind of entity",
+		},
+		"aggregateId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "Only events about this entity",
+		},
+		"eventType": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "Only events of this type",
+		},
+		"startDate": &graphql.InputObjectFieldConfig{
+			Type:        graphql.DateTime,
+			Description: "Only events on or after this time",
+		},
+		"endDate": &graphql.InputObjectFieldConfig{
+			Type:        graphql.DateTime,
+			Description: "Only events before this time",
+		},
+	},
+})
+
+// ReplayEventsInput represents the GraphQL input for replaying events
+var ReplayEventsInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "ReplayEventsInput",
+	Description: "The events to replay and the consumers to deliver them to",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"eventIds": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+			Description: "The events to replay",
+		},
+		"filter": &graphql.InputObjectFieldConfig{
+			Type:        DomainEventFilterInput,
+			Description: "Replay all events matching the filter instead (at most 500)",
+		},
+		"consumers": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+			Description: "The consumers to deliver to, all registered consumers by default",
+		},
+	},
+})
+
+// eventQueryFields returns the event log admin queries
+func eventQueryFields(resolver *Resolver) graphql.Fields {
+	listArgs := paginationArgs("events", 50)
+	listArgs["filter"] = &graphql.ArgumentConfig{
+		Type:        DomainEventFilterInput,
+		Description: "The filter criteria",
+	}
+
+	return graphql.Fields{
+		"domainEvent": &graphql.Field{
+			Type:        DomainEventType,
+			Description: "Get an event from the event log (platform admins only)",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the event",
+				},
+			},
+			Resolve: resolver.resolveDomainEvent,
+		},
+		"domainEvents": &graphql.Field{
+			Type:        graphql.NewList(DomainEventType),
+			Description: "Browse the event log, oldest first (platform admins only)",
+			Args:        listArgs,
+			Resolve:     resolver.resolveDomainEvents,
+		},
+		"eventConsumers": &graphql.Field{
+			Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+			Description: "List the consumers events can be replayed to (platform admins only)",
+			Resolve:     resolver.resolveEventConsumers,
+		},
+	}
+}
+
+// eventMutationFields returns the event log admin mutations
+func eventMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"replayEvents": &graphql.Field{
+			Type:        ReplayResultType,
+			Description: "Redeliver events to downstream consumers, e.g. after fixing a bug (platform admins only)",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(ReplayEventsInput),
+					Description: "The events and consumers",
+				},
+			},
+			Resolve: resolver.resolveReplayEvents,
+		},
+	}
+}
@@ -0,0 +1,75 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/pkg/graph/dataloader"
+)
+
+// Federation Query Resolvers
+func (r *Resolver) resolveService(p graphql.ResolveParams) (any, error) {
+	return map[string]any{"sdl": PrintSubgraphSDL(p.Info.Schema)}, nil
+}
+
+func (r *Resolver) resolveEntities(p graphql.ResolveParams) (any, error) {
+	representations, ok := p.Args["representations"].([]any)
+	if !ok {
+		return nil, errors.New("representations is required")
+	}
+	loaders := dataloader.For(p.Context)
+	if loaders == nil {
+		return nil, errLoadersMissing
+	}
+
+	// Every entity is queued before any is fetched, so each type is fetched in one batch
+	loads := make([]func() (any, error), len(representations))
+	for i, representation := range representations {
+		fields, _ := representation.(map[string]any)
+		typename, _ := fields["__typename"].(string)
+		id, _ := fields["id"].(string)
+		if id == "" {
+			return nil, fmt.Errorf("representation %d has no id", i)
+		}
+
+		switch typename {
+		case BusinessType.Name():
+			loads[i] = loadEntity(p.Context, loaders.Businesses, id, dto.ToBusinessResponseDTO)
+		case ClientType.Name():
+			loads[i] = loadEntity(p.Context, loaders.Clients, id, dto.ToClientResponseDTO)
+		case AppointmentType.Name():
+			loads[i] = loadEntity(p.Context, loaders.Appointments, id, dto.ToAppointmentResponseDTO)
+		default:
+			return nil, fmt.Errorf("%q is not an entity of this subgraph", typename)
+		}
+	}
+
+	return func() (any, error) {
+		entities := make([]any, len(loads))
+		for i, load := range loads {
+			entity, err := load()
+			if err != nil {
+				return nil, err
+			}
+			entities[i] = entity
+		}
+		return entities, nil
+	}, nil
+}
+
+// loadEntity queues an entity on the request's loader and returns a thunk yielding its
+// response DTO, or nil when there is none
+func loadEntity[T, R any](ctx context.Context, loader *dataloader.Loader[T], id string, convert func(*T) *R) func() (any, error) {
+	load := loader.Load(ctx, id)
+	return func() (any, error) {
+		entity, err := load()
+		if err != nil || entity == nil {
+			return nil, err
+		}
+		return convert(entity), nil
+	}
+}
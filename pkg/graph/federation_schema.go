@@ -0,0 +1,95 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// federationSchemaExtension links the Apollo Federation directives the subgraph SDL uses
+const federationSchemaExtension = `extend schema @link(url: "https://specs.apollo.dev/federation/v2.3", import: ["@key"])`
+
+// federationEntities are the types other subgraphs can reference and extend, each keyed by
+// its ID
+var federationEntities = map[string]bool{
+	"Business":    true,
+	"Client":      true,
+	"Appointment": true,
+}
+
+// federationNames are the types and root fields federation adds to the schema, which the
+// subgraph SDL leaves out as the router defines them itself
+var federationNames = map[string]bool{
+	"_Any":      true,
+	"_Entity":   true,
+	"_Service":  true,
+	"_service":  true,
+	"_entities": true,
+}
+
+// AnyScalar represents the entity representations the router sends to resolve entities
+var AnyScalar = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "_Any",
+	Description: "An entity representation: its __typename and key fields",
+	Serialize: func(value any) any {
+		return value
+	},
+	ParseValue: func(value any) any {
+		return value
+	},
+	ParseLiteral: parseJSONLiteral,
+})
+
+// FederationServiceType represents the GraphQL _Service type of Apollo Federation
+var FederationServiceType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "_Service",
+	Description: "The subgraph, as seen by an Apollo Federation router",
+	Fields: graphql.Fields{
+		"sdl": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The subgraph schema, with the federation directives of its entities",
+		},
+	},
+})
+
+// EntityUnion represents the GraphQL _Entity union of Apollo Federation
+var EntityUnion = graphql.NewUnion(graphql.UnionConfig{
+	Name:        "_Entity",
+	Description: "The types other subgraphs can reference by key",
+	Types:       []*graphql.Object{BusinessType, ClientType, AppointmentType},
+	ResolveType: func(p graphql.ResolveTypeParams) *graphql.Object {
+		switch p.Value.(type) {
+		case *dto.BusinessResponseDTO:
+			return BusinessType
+		case *dto.ClientResponseDTO:
+			return ClientType
+		case *dto.AppointmentResponseDTO:
+			return AppointmentType
+		default:
+			return nil
+		}
+	},
+})
+
+// federationQueryFields returns the queries an Apollo Federation router uses to compose the
+// schema and to resolve entities referenced by other subgraphs
+func federationQueryFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"_service": &graphql.Field{
+			Type:        graphql.NewNonNull(FederationServiceType),
+			Description: "The subgraph schema, for composition by an Apollo Federation router",
+			Resolve:     resolver.resolveService,
+		},
+		"_entities": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(EntityUnion)),
+			Description: "Resolve entities referenced by other subgraphs; unknown entities resolve to null",
+			Args: graphql.FieldConfigArgument{
+				"representations": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(AnyScalar))),
+					Description: "The entities, each as its __typename and id",
+				},
+			},
+			Resolve: resolver.resolveEntities,
+		},
+	}
+}
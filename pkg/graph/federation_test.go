@@ -0,0 +1,99 @@
+package graph
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/repository/memory"
+	"github.com/assimoes/beautix/pkg/graph/dataloader"
+)
+
+func TestFederation_ServiceSDL(t *testing.T) {
+	schema, err := CreateSchema(NewResolver(nil, nil))
+	require.NoError(t, err)
+
+	result := graphql.Do(graphql.Params{Schema: schema, RequestString: `{ _service { sdl } }`})
+	require.Empty(t, result.Errors)
+	sdl := result.Data.(map[string]any)["_service"].(map[string]any)["sdl"].(string)
+
+	header, types, found := strings.Cut(sdl, "\n\n")
+	require.True(t, found)
+	assert.Equal(t, federationSchemaExtension, header)
+	assert.Contains(t, types, `type Business @key(fields: "id") {`)
+	assert.Contains(t, types, `type Client @key(fields: "id") {`)
+	assert.Contains(t, types, `type Appointment @key(fields: "id") {`)
+	assert.Contains(t, types, "scalar Decimal")
+	assert.NotContains(t, types, "_entities", "the router defines the federation fields itself")
+	assert.NotContains(t, types, "__Schema")
+
+	_, err = parser.Parse(parser.ParseParams{Source: types})
+	assert.NoError(t, err, "the SDL parses")
+}
+
+func TestFederation_ResolveEntities(t *testing.T) {
+	db := memory.NewDB()
+	business := &domain.Business{Name: "Salon", Email: "salon@example.com"}
+	require.NoError(t, memory.Insert(db, business))
+	client := &domain.Client{BusinessID: business.ID, FirstName: "Rita", Email: "rita@example.com"}
+	require.NoError(t, memory.Insert(db, client))
+	start := time.Date(2024, 5, 6, 10, 0, 0, 0, time.UTC)
+	appointment := &domain.Appointment{BusinessID: business.ID, ClientID: client.ID, StartTime: start, EndTime: start.Add(time.Hour),
+		Status: domain.AppointmentStatusScheduled}
+	require.NoError(t, memory.Insert(db, appointment))
+
+	businessRepo := &countingRepository[domain.Business]{BaseRepository: memory.NewBaseRepository[domain.Business](db)}
+	loaders := dataloader.New(dataloader.Sources{
+		Users:        memory.NewBaseRepository[domain.User](db),
+		Clients:      memory.NewBaseRepository[domain.Client](db),
+		Staff:        memory.NewBaseRepository[domain.Staff](db),
+		Businesses:   businessRepo,
+		Services:     memory.NewBaseRepository[domain.Service](db),
+		Appointments: memory.NewBaseRepository[domain.Appointment](db),
+	})
+	schema, err := CreateSchema(NewResolver(nil, nil))
+	require.NoError(t, err)
+
+	result := graphql.Do(graphql.Params{
+		Schema: schema,
+		RequestString: `query ($representations: [_Any!]!) {
+			_entities(representations: $representations) {
+				__typename
+				... on Business { name }
+				... on Client { firstName business { name } }
+				... on Appointment { id }
+			}
+		}`,
+		VariableValues: map[string]any{"representations": []any{
+			map[string]any{"__typename": "Client", "id": client.ID},
+			map[string]any{"__typename": "Business", "id": business.ID},
+			map[string]any{"__typename": "Appointment", "id": appointment.ID},
+			map[string]any{"__typename": "Business", "id": "missing"},
+		}},
+		Context: dataloader.WithLoaders(context.Background(), loaders),
+	})
+	require.Empty(t, result.Errors)
+
+	entities := result.Data.(map[string]any)["_entities"].([]any)
+	require.Len(t, entities, 4)
+	assert.Equal(t, map[string]any{"__typename": "Client", "firstName": "Rita", "business": map[string]any{"name": "Salon"}}, entities[0])
+	assert.Equal(t, map[string]any{"__typename": "Business", "name": "Salon"}, entities[1])
+	assert.Equal(t, map[string]any{"__typename": "Appointment", "id": appointment.ID}, entities[2])
+	assert.Nil(t, entities[3], "unknown entities resolve to null")
+	assert.Len(t, businessRepo.batches, 1, "the businesses are fetched in one batch")
+
+	result = graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ _entities(representations: [{__typename: "Staff", id: "1"}]) { __typename } }`,
+		Context:       dataloader.WithLoaders(context.Background(), loaders),
+	})
+	require.Len(t, result.Errors, 1)
+	assert.Contains(t, result.Errors[0].Message, "not an entity")
+}
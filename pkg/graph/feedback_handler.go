@@ -0,0 +1,101 @@
+package graph
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+	"strconv"
+
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/assimoes/beautix/internal/service"
+)
+
+// feedbackPageData is rendered by feedbackTemplate
+type feedbackPageData struct {
+	*dto.FeedbackFormDTO
+	Sent bool
+}
+
+// feedbackTemplate renders the page a client lands on from a feedback link
+var feedbackTemplate = template.Must(template.New("feedback").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+    <title>{{.BusinessName}}</title>
+    <style>
+        body { font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; margin: 40px auto; max-width: 480px; padding: 0 16px; color: #333; text-align: center; }
+        select, textarea { font-size: 16px; padding: 8px; margin-bottom: 16px; width: 100%; box-sizing: border-box; }
+        button { font-size: 18px; padding: 12px 32px; border: none; border-radius: 6px; background: #333; color: #fff; cursor: pointer; }
+    </style>
+</head>
+<body>
+    <h1>{{.BusinessName}}</h1>
+    {{if .Sent}}
+    <p>Thank you for your feedback!</p>
+    {{else}}
+    <p>Tell us how your visit went. Your feedback is anonymous: only the owner reads it, and it is not linked to your name or appointment.</p>
+    <form method="POST">
+        <select name="rating">
+            <option value="">No rating</option>
+            <option value="5">5 - Excellent</option>
+            <option value="4">4 - Good</option>
+            <option value="3">3 - Okay</option>
+            <option value="2">2 - Poor</option>
+            <option value="1">1 - Very poor</option>
+        </select><br>
+        <textarea name="message" rows="6" maxlength="2000" required></textarea><br>
+        <button type="submit">Send feedback</button>
+    </form>
+    {{end}}
+</body>
+</html>
+`))
+
+// FeedbackHandler serves the page a client opens from an anonymous feedback link. Opening the
+// page shows the form; submitting a message and an optional rating sends the feedback. It must
+// be registered under service.FeedbackLinkPath.
+func FeedbackHandler(feedbackService service.AnonymousFeedbackService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Path[len(service.FeedbackLinkPath):]
+
+		var form *dto.FeedbackFormDTO
+		var err error
+		switch r.Method {
+		case http.MethodGet:
+			form, err = feedbackService.GetFeedbackForm(r.Context(), token)
+		case http.MethodPost:
+			form, err = feedbackService.GetFeedbackForm(r.Context(), token)
+			if err == nil {
+				submitDTO := dto.SubmitAnonymousFeedbackDTO{Token: token, Message: r.PostFormValue("message")}
+				if rating, parseErr := strconv.Atoi(r.PostFormValue("rating")); parseErr == nil {
+					submitDTO.Rating = &rating
+				}
+				err = feedbackService.SubmitFeedback(r.Context(), submitDTO)
+			}
+		default:
+			http.Error(w, "Only GET and POST requests are allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if err != nil {
+			var notFound service.NotFoundError
+			var invalid *validation.ValidationError
+			switch {
+			case errors.As(err, &notFound):
+				http.Error(w, "This feedback link is not valid", http.StatusNotFound)
+			case errors.As(err, &invalid):
+				http.Error(w, invalid.Error(), http.StatusConflict)
+			default:
+				http.Error(w, "Failed to send feedback", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		data := feedbackPageData{FeedbackFormDTO: form, Sent: r.Method == http.MethodPost}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := feedbackTemplate.Execute(w, data); err != nil {
+			http.Error(w, "Failed to render page", http.StatusInternalServerError)
+		}
+	}
+}
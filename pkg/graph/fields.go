@@ -0,0 +1,225 @@
+package graph
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// baseResponseSource is implemented by response DTOs embedding dto.BaseResponse
+type baseResponseSource interface {
+	GetBaseResponse() dto.BaseResponse
+}
+
+// withBaseFields adds the id, createdAt, updatedAt and version fields shared by all response DTOs
+func withBaseFields(entity string, fields graphql.Fields) graphql.Fields {
+	fields["id"] = &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.String),
+		Description: "The unique identifier of the " + entity,
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			if source, ok := p.Source.(baseResponseSource); ok {
+				return source.GetBaseResponse().ID, nil
+			}
+			return nil, nil
+		},
+	}
+	fields["createdAt"] = &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.DateTime),
+		Description: "When the " + entity + " was created",
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			if source, ok := p.Source.(baseResponseSource); ok {
+				return source.GetBaseResponse().CreatedAt, nil
+			}
+			return nil, nil
+		},
+	}
+	fields["updatedAt"] = &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.DateTime),
+		Description: "When the " + entity + " was last updated",
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			if source, ok := p.Source.(baseResponseSource); ok {
+				return source.GetBaseResponse().UpdatedAt, nil
+			}
+			return nil, nil
+		},
+	}
+	fields["version"] = &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.Int),
+		Description: "The version of the " + entity + ", sent back with updates so that changes made by someone else in the meantime are not overwritten",
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			if source, ok := p.Source.(baseResponseSource); ok {
+				return source.GetBaseResponse().Version, nil
+			}
+			return nil, nil
+		},
+	}
+	return fields
+}
+
+// paginationArgs returns the limit/offset arguments used by list queries
+func paginationArgs(entity string, defaultLimit int) graphql.FieldConfigArgument {
+	return graphql.FieldConfigArgument{
+		"limit": &graphql.ArgumentConfig{
+			Type:         graphql.Int,
+			Description:  "Maximum number of " + entity + " to return",
+			DefaultValue: defaultLimit,
+		},
+		"offset": &graphql.ArgumentConfig{
+			Type:         graphql.Int,
+			Description:  "Number of " + entity + " to skip",
+			DefaultValue: 0,
+		},
+	}
+}
+
+// pageFromArgs converts limit/offset arguments into page-based pagination
+func pageFromArgs(args map[string]any, defaultLimit int) (page, pageSize int) {
+	page = 1
+	pageSize = defaultLimit
+
+	if limit, ok := args["limit"].(int); ok && limit > 0 {
+		pageSize = limit
+	}
+	if offset, ok := args["offset"].(int); ok && offset >= 0 {
+		page = (offset / pageSize) + 1
+	}
+	return page, pageSize
+}
+
+// optionalString returns a pointer to a string argument, or nil when absent
+func optionalString(args map[string]any, key string) *string {
+	if value, ok := args[key].(string); ok {
+		return &value
+	}
+	return nil
+}
+
+// optionalTime returns a pointer to a DateTime argument, or nil when absent
+func optionalTime(args map[string]any, key string) *time.Time {
+	if value, ok := args[key].(time.Time); ok {
+		return &value
+	}
+	return nil
+}
+
+// clearField builds the clear field of an update input, naming the nullable fields the update
+// sets to null. GraphQL drops null input values, so clearing a field is asked for by name.
+func clearField(inputName string, fields ...string) *graphql.InputObjectFieldConfig {
+	values := graphql.EnumValueConfigMap{}
+	for _, field := range fields {
+		values[enumValueName(field)] = &graphql.EnumValueConfig{Value: field}
+	}
+	return &graphql.InputObjectFieldConfig{
+		Type: graphql.NewList(graphql.NewNonNull(graphql.NewEnum(graphql.EnumConfig{
+			Name:        inputName + "ClearableField",
+			Description: "A field of " + inputName + " that can be set to null",
+			Values:      values,
+		}))),
+		Description: "Fields to set to null",
+	}
+}
+
+// enumValueName converts a camelCase field name to the SCREAMING_SNAKE_CASE of enum values
+func enumValueName(field string) string {
+	var name strings.Builder
+	for i, r := range field {
+		if unicode.IsUpper(r) && i > 0 {
+			name.WriteByte('_')
+		}
+		name.WriteRune(unicode.ToUpper(r))
+	}
+	return name.String()
+}
+
+// stringList converts a list argument into a slice of strings
+func stringList(value any) []string {
+	items, _ := value.([]any)
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			values = append(values, s)
+		}
+	}
+	return values
+}
+
+// PageInfoType represents the GraphQL PageInfo type of cursor-paginated connections
+var PageInfoType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "PageInfo",
+	Description: "Where a page sits in a cursor-paginated list",
+	Fields: graphql.Fields{
+		"hasNextPage": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether more items follow this page",
+		},
+		"hasPreviousPage": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether items precede this page",
+		},
+		"startCursor": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Cursor of the first item of the page",
+		},
+		"endCursor": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Cursor of the last item of the page; pass it as after to fetch the next page",
+		},
+	},
+})
+
+// connectionType builds the Relay-style connection and edge types for a node type
+func connectionType(nodeType *graphql.Object) *graphql.Object {
+	edgeType := graphql.NewObject(graphql.ObjectConfig{
+		Name: nodeType.Name() + "Edge",
+		Fields: graphql.Fields{
+			"cursor": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "Cursor pointing at this item",
+			},
+			"node": &graphql.Field{
+				Type:        graphql.NewNonNull(nodeType),
+				Description: "The item",
+			},
+		},
+	})
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: nodeType.Name() + "Connection",
+		Fields: graphql.Fields{
+			"edges": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(edgeType))),
+			},
+			"pageInfo": &graphql.Field{
+				Type: graphql.NewNonNull(PageInfoType),
+			},
+		},
+	})
+}
+
+// connectionArgs returns the first/after arguments used by connection queries
+func connectionArgs(entity string, defaultFirst int) graphql.FieldConfigArgument {
+	return graphql.FieldConfigArgument{
+		"first": &graphql.ArgumentConfig{
+			Type:         graphql.Int,
+			Description:  "Maximum number of " + entity + " to return (at most 100)",
+			DefaultValue: defaultFirst,
+		},
+		"after": &graphql.ArgumentConfig{
+			Type:        graphql.String,
+			Description: "Return the " + entity + " following this cursor",
+		},
+	}
+}
+
+// cursorFromArgs extracts the first/after arguments of a connection query
+func cursorFromArgs(args map[string]any, defaultFirst int) (after string, first int) {
+	first = defaultFirst
+	if value, ok := args["first"].(int); ok && value > 0 {
+		first = value
+	}
+	after, _ = args["after"].(string)
+	return after, first
+}
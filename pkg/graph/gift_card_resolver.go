@@ -0,0 +1,57 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Gift Card Query Resolvers
+func (r *Resolver) resolveGiftCard(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+
+	card, err := r.giftCardService.GetGiftCard(p.Context, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return card, nil
+}
+
+func (r *Resolver) resolveGiftCardBalance(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+	code, ok := p.Args["code"].(string)
+	if !ok {
+		return nil, errors.New("code is required")
+	}
+
+	balance, err := r.giftCardService.CheckGiftCardBalance(p.Context, businessID, code)
+	if err != nil {
+		return nil, err
+	}
+
+	return balance, nil
+}
+
+// Gift Card Mutation Resolvers
+func (r *Resolver) resolveSellGiftCard(p graphql.ResolveParams) (any, error) {
+	sellDTO := dto.SellGiftCardDTO{}
+	if err := decodeInput(p.Args, &sellDTO); err != nil {
+		return nil, err
+	}
+
+	card, err := r.giftCardService.SellGiftCard(p.Context, sellDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return card, nil
+}
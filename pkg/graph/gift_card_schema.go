@@ -0,0 +1,248 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// GiftCardTransactionKindEnum represents the GraphQL enum for gift card balance changes
+var GiftCardTransactionKindEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "GiftCardTransactionKind",
+	Description: "How a transaction changed a gift card's balance",
+	Values: graphql.EnumValueConfigMap{
+		"ISSUE": &graphql.EnumValueConfig{
+			Value:       domain.GiftCardTransactionIssue,
+			Description: "The card was sold with its initial value",
+		},
+		"REDEMPTION": &graphql.EnumValueConfig{
+			Value:       domain.GiftCardTransactionRedemption,
+			Description: "Part of the balance paid for an appointment",
+		},
+		"REFUND": &graphql.EnumValueConfig{
+			Value:       domain.GiftCardTransactionRefund,
+			Description: "A redemption was refunded back onto the card",
+		},
+	},
+})
+
+// GiftCardTransactionType represents the GraphQL GiftCardTransaction type
+var GiftCardTransactionType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "GiftCardTransaction",
+	Description: "A change to a gift card's balance",
+	Fields: graphql.Fields{
+		"id": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The unique identifier of the transaction",
+		},
+		"kind": &graphql.Field{
+			Type:        graphql.NewNonNull(GiftCardTransactionKindEnum),
+			Description: "How the balance changed",
+		},
+		"amount": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The amount added to or taken off the card",
+		},
+		"balanceAfter": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The balance left after the transaction",
+		},
+		"paymentId": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The ID of the payment the card was redeemed for",
+		},
+		"appointmentId": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The ID of the appointment the card paid for",
+		},
+		"createdAt": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the transaction happened",
+		},
+	},
+})
+
+// GiftCardType represents the GraphQL GiftCard type
+var GiftCardType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "GiftCard",
+	Description: "A prepaid voucher sold by a business and redeemed against its appointments",
+	Fields: withBaseFields("gift card", graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"code": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The code the client presents to redeem the card",
+		},
+		"initialValue": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The value the card was sold for",
+		},
+		"balance": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The value left on the card",
+		},
+		"currency": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The currency code",
+		},
+		"expiresAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the card stops being redeemable",
+		},
+		"redeemable": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether a balance is left and the card has not expired",
+		},
+		"purchaserClientId": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The ID of the client who bought the card",
+		},
+		"purchaser": clientRelation(func(g *dto.GiftCardResponseDTO) string {
+			if g.PurchaserClientID == nil {
+				return ""
+			}
+			return *g.PurchaserClientID
+		}),
+		"recipientName": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Who the card is for",
+		},
+		"recipientEmail": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The email address of who the card is for",
+		},
+		"message": &graphql.Field{
+			Type:        graphql.String,
+			Description: "A message to the recipient",
+		},
+		"salePaymentMethod": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "How the card itself was paid for",
+		},
+		"transactions": &graphql.Field{
+			Type:        graphql.NewList(GiftCardTransactionType),
+			Description: "The changes to the balance, oldest first",
+		},
+	}),
+})
+
+// GiftCardBalanceType represents the GraphQL GiftCardBalance type
+var GiftCardBalanceType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "GiftCardBalance",
+	Description: "What is left on a gift card",
+	Fields: graphql.Fields{
+		"code": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The code of the card",
+		},
+		"balance": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The value left on the card",
+		},
+		"currency": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The currency code",
+		},
+		"expiresAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the card stops being redeemable",
+		},
+		"redeemable": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether a balance is left and the card has not expired",
+		},
+	},
+})
+
+// SellGiftCardInput represents the GraphQL input for selling a gift card
+var SellGiftCardInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "SellGiftCardInput",
+	Description: "Input for selling a gift card",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"businessId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"amount": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The value of the card, in the business's currency",
+		},
+		"expiresAt": &graphql.InputObjectFieldConfig{
+			Type:        graphql.DateTime,
+			Description: "When the card stops being redeemable; cards without one do not expire",
+		},
+		"purchaserClientId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The ID of the client buying the card",
+		},
+		"recipientName": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "Who the card is for",
+		},
+		"recipientEmail": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The email address of who the card is for",
+		},
+		"message": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "A message to the recipient",
+		},
+		"paymentMethod": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "How the card is paid for: cash, card, transfer or other",
+		},
+	},
+})
+
+// giftCardQueryFields returns the gift card queries
+func giftCardQueryFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"giftCard": &graphql.Field{
+			Type:        GiftCardType,
+			Description: "Get a gift card by ID with its balance changes",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the gift card",
+				},
+			},
+			Resolve: resolver.resolveGiftCard,
+		},
+		"giftCardBalance": &graphql.Field{
+			Type:        GiftCardBalanceType,
+			Description: "Check what is left on a gift card by the code the client presents",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+				"code": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The code of the gift card, with or without dashes",
+				},
+			},
+			Resolve: resolver.resolveGiftCardBalance,
+		},
+	}
+}
+
+// giftCardMutationFields returns the gift card mutations. Cards are redeemed with redeemGiftCard,
+// among the payment mutations.
+func giftCardMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"sellGiftCard": &graphql.Field{
+			Type:        GiftCardType,
+			Description: "Sell a gift card, issuing it with a new code",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(SellGiftCardInput),
+					Description: "The gift card",
+				},
+			},
+			Resolve: resolver.resolveSellGiftCard,
+		},
+	}
+}
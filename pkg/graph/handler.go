@@ -1,10 +1,26 @@
 package graph
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/infrastructure/i18n"
+	"github.com/assimoes/beautix/internal/infrastructure/outbound"
+	"github.com/assimoes/beautix/internal/infrastructure/telemetry"
+	"github.com/assimoes/beautix/internal/service"
+	"github.com/assimoes/beautix/pkg/graph/dataloader"
 )
 
 // GraphQLRequest represents a GraphQL request
@@ -24,15 +40,167 @@ type GraphQLResponse struct {
 type GraphQLError struct {
 	Message string `json:"message"`
 	Path    []interface{} `json:"path,omitempty"`
+	Extensions map[string]any `json:"extensions,omitempty"` // Carries the code of known kinds of errors
+}
+
+// RequestObserver is notified after each GraphQL operation has executed
+type RequestObserver func(ctx context.Context, operationName string, failed bool)
+
+// BusinessIDHeader names the business a signed-in user working at several businesses acts for
+const BusinessIDHeader = "X-Business-ID"
+
+// Authenticator establishes who a request is made by and the business it acts for
+type Authenticator interface {
+	Authenticate(ctx context.Context, credentials service.Credentials) (context.Context, error)
+}
+
+// SessionChecker checks the session a request is made with against the revocation list
+type SessionChecker interface {
+	CheckSession(ctx context.Context) error
+}
+
+// BusinessChecker checks that the business a request is made for may be used
+type BusinessChecker interface {
+	CheckBusiness(ctx context.Context) error
+}
+
+// LocalePreferences looks up the locale a signed-in caller chose to read the app in
+type LocalePreferences interface {
+	PreferredLocale(ctx context.Context) (string, error)
+}
+
+// IdempotencyStore claims the idempotency keys mutations are sent with and records their
+// responses
+type IdempotencyStore interface {
+	ClaimIdempotencyKey(ctx context.Context, key, requestHash string) (*domain.IdempotencyKey, error)
+	CompleteIdempotencyKey(ctx context.Context, claim *domain.IdempotencyKey, response []byte) error
+	ReleaseIdempotencyKey(ctx context.Context, claim *domain.IdempotencyKey) error
+}
+
+// HandlerOption configures optional behaviour of the GraphQL handler
+type HandlerOption func(*handlerConfig)
+
+type handlerConfig struct {
+	observers []RequestObserver
+	loaders   *dataloader.Sources
+	limiter   *RateLimiter
+	auth      Authenticator
+	sessions  SessionChecker
+	business  BusinessChecker
+	locales   LocalePreferences
+	keys      IdempotencyStore
+	telemetry bool
+	private   bool
+	logging   bool
+}
+
+// WithRequestObserver registers an observer that is called after each executed operation
+func WithRequestObserver(observer RequestObserver) HandlerOption {
+	return func(c *handlerConfig) {
+		c.observers = append(c.observers, observer)
+	}
+}
+
+// WithRateLimit refuses requests beyond the limiter's limit for the caller's IP address with
+// 429 Too Many Requests
+func WithRateLimit(limiter *RateLimiter) HandlerOption {
+	return func(c *handlerConfig) {
+		c.limiter = limiter
+	}
+}
+
+// WithAuthentication identifies callers by the session token in the Authorization header and the
+// business they act for by the X-Business-ID header. Invalid tokens are refused with 401
+// Unauthorized, and businesses the caller does not work at with 403 Forbidden.
+func WithAuthentication(authenticator Authenticator) HandlerOption {
+	return func(c *handlerConfig) {
+		c.auth = authenticator
+	}
+}
+
+// WithSessionCheck refuses requests made with a revoked session with 401 Unauthorized
+func WithSessionCheck(checker SessionChecker) HandlerOption {
+	return func(c *handlerConfig) {
+		c.sessions = checker
+	}
+}
+
+// WithSuspensionCheck refuses requests made for a business platform operators suspended with
+// 403 Forbidden
+func WithSuspensionCheck(checker BusinessChecker) HandlerOption {
+	return func(c *handlerConfig) {
+		c.business = checker
+	}
+}
+
+// WithLocalePreferences writes messages in the locale signed-in callers chose, rather than the
+// one their Accept-Language header prefers
+func WithLocalePreferences(preferences LocalePreferences) HandlerOption {
+	return func(c *handlerConfig) {
+		c.locales = preferences
+	}
+}
+
+// WithIdempotency replays the first response to the mutations that honour idempotency keys when
+// they are retried with the same Idempotency-Key header, rather than running them again. A key
+// sent with a different request is refused with 422 Unprocessable Entity, and a retry while
+// the first attempt is running with 409 Conflict. Responses failing with an unexpected error
+// are not recorded, so a retry runs the mutation again.
+func WithIdempotency(store IdempotencyStore) HandlerOption {
+	return func(c *handlerConfig) {
+		c.keys = store
+	}
+}
+
+// WithoutIntrospection refuses queries for the schema, including the federation _service
+// field, so the schema is not given away to anyone who can reach the endpoint. Frontends and
+// routers then build from the SDL written by cmd/schemadoc.
+func WithoutIntrospection() HandlerOption {
+	return func(c *handlerConfig) {
+		c.private = true
+	}
+}
+
+// WithTelemetry traces the execution of operations and their resolvers, and records how long
+// resolvers take
+func WithTelemetry() HandlerOption {
+	return func(c *handlerConfig) {
+		c.telemetry = true
+	}
+}
+
+// WithRequestLogging logs every executed operation with its name, its variables stripped of
+// personal data, how long it took and how many errors it returned
+func WithRequestLogging() HandlerOption {
+	return func(c *handlerConfig) {
+		c.logging = true
+	}
+}
+
+// WithLoaders gives every request its own loaders fetching from the sources, so related
+// entities are fetched in batches
+func WithLoaders(sources dataloader.Sources) HandlerOption {
+	return func(c *handlerConfig) {
+		c.loaders = &sources
+	}
 }
 
 // Handler creates an HTTP handler for GraphQL requests
-func Handler(schema graphql.Schema) http.HandlerFunc {
+func Handler(schema graphql.Schema, opts ...HandlerOption) http.HandlerFunc {
+	config := &handlerConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+	if config.telemetry {
+		schema.AddExtensions(telemetryExtension{})
+	}
+
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Set CORS headers
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, "+telemetry.RequestIDHeader+", "+IdempotencyKeyHeader+", "+BusinessIDHeader)
+		w.Header().Set("Access-Control-Expose-Headers", telemetry.RequestIDHeader+", "+IdempotentReplayedHeader)
 
 		// Handle preflight OPTIONS request
 		if r.Method == http.MethodOptions {
@@ -46,6 +214,14 @@ func Handler(schema graphql.Schema) http.HandlerFunc {
 			return
 		}
 
+		if config.limiter != nil {
+			if allowed, retryAfter := config.limiter.Allow(clientIP(r)); !allowed {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+				http.Error(w, "Too many requests", http.StatusTooManyRequests)
+				return
+			}
+		}
+
 		// Parse the request body
 		var req GraphQLRequest
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -54,13 +230,99 @@ func Handler(schema graphql.Schema) http.HandlerFunc {
 		}
 
 		// Execute the GraphQL query
-		result := graphql.Do(graphql.Params{
-			Schema:         schema,
-			RequestString:  req.Query,
-			VariableValues: req.Variables,
-			OperationName:  req.OperationName,
-			Context:        r.Context(),
-		})
+		start := time.Now()
+		ctx := context.WithValue(r.Context(), service.ClientIPKey, clientIP(r))
+		if config.auth != nil {
+			var err error
+			ctx, err = config.auth.Authenticate(ctx, service.Credentials{
+				Token:      bearerToken(r),
+				BusinessID: r.Header.Get(BusinessIDHeader),
+				UserAgent:  r.UserAgent(),
+			})
+			switch {
+			case errors.Is(err, domain.ErrUnauthenticated):
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			case errors.Is(err, domain.ErrCrossTenant):
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			case err != nil:
+				telemetry.Logger(ctx).Error().Err(err).Msg("Failed to authenticate request")
+				http.Error(w, "Failed to authenticate request", http.StatusInternalServerError)
+				return
+			}
+		}
+		if config.sessions != nil {
+			if err := config.sessions.CheckSession(ctx); err != nil {
+				if errors.Is(err, domain.ErrSessionRevoked) {
+					http.Error(w, "Session revoked", http.StatusUnauthorized)
+					return
+				}
+				telemetry.Logger(ctx).Error().Err(err).Msg("Failed to check session")
+				http.Error(w, "Failed to check session", http.StatusInternalServerError)
+				return
+			}
+		}
+		if config.business != nil {
+			if err := config.business.CheckBusiness(ctx); err != nil {
+				if errors.Is(err, domain.ErrBusinessSuspended) {
+					http.Error(w, "Business suspended", http.StatusForbidden)
+					return
+				}
+				telemetry.Logger(ctx).Error().Err(err).Msg("Failed to check business")
+				http.Error(w, "Failed to check business", http.StatusInternalServerError)
+				return
+			}
+		}
+		locale := i18n.Negotiate(r.Header.Get("Accept-Language"))
+		if config.locales != nil {
+			preferred, err := config.locales.PreferredLocale(ctx)
+			if err != nil {
+				telemetry.Logger(ctx).Warn().Err(err).Msg("Failed to look up preferred locale")
+			} else if parsed, ok := i18n.Parse(preferred); ok {
+				locale = parsed
+			}
+		}
+		ctx = i18n.WithLocale(ctx, locale)
+		if config.loaders != nil {
+			ctx = dataloader.WithLoaders(ctx, dataloader.New(*config.loaders))
+		}
+		var claim *domain.IdempotencyKey
+		if key := r.Header.Get(IdempotencyKeyHeader); config.keys != nil && key != "" && idempotent(req) {
+			var err error
+			if claim, err = config.keys.ClaimIdempotencyKey(ctx, key, requestHash(req)); err != nil {
+				writeIdempotencyError(ctx, w, err)
+				return
+			}
+			if claim.Status == domain.IdempotencyCompleted {
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set(IdempotentReplayedHeader, "true")
+				_, _ = io.WriteString(w, *claim.Response)
+				return
+			}
+		}
+		var result *graphql.Result
+		if config.private && introspects(req.Query) {
+			result = &graphql.Result{Errors: []gqlerrors.FormattedError{{
+				Message:    "GraphQL introspection is disabled",
+				Extensions: map[string]any{"code": ErrorCodeIntrospectionDisabled},
+			}}}
+		} else {
+			result = graphql.Do(graphql.Params{
+				Schema:         schema,
+				RequestString:  req.Query,
+				VariableValues: req.Variables,
+				OperationName:  req.OperationName,
+				Context:        ctx,
+			})
+		}
+
+		for _, observer := range config.observers {
+			observer(ctx, req.OperationName, len(result.Errors) > 0)
+		}
+		if config.logging {
+			logOperation(ctx, req, len(result.Errors), time.Since(start))
+		}
 
 		// Convert GraphQL errors to our error format
 		var errors []GraphQLError
@@ -70,6 +332,7 @@ func Handler(schema graphql.Schema) http.HandlerFunc {
 				errors[i] = GraphQLError{
 					Message: err.Message,
 					Path:    err.Path,
+					Extensions: errorExtensions(err, locale),
 				}
 			}
 		}
@@ -79,12 +342,75 @@ func Handler(schema graphql.Schema) http.HandlerFunc {
 			Data:   result.Data,
 			Errors: errors,
 		}
-
-		// Set content type and encode the response
-		w.Header().Set("Content-Type", "application/json")
-		if err := json.NewEncoder(w).Encode(response); err != nil {
+		body, err := json.Marshal(response)
+		if err != nil {
+			if claim != nil {
+				_ = config.keys.ReleaseIdempotencyKey(ctx, claim)
+			}
 			http.Error(w, "Failed to encode response", http.StatusInternalServerError)
 			return
 		}
+		if claim != nil {
+			if retryable(errors) {
+				err = config.keys.ReleaseIdempotencyKey(ctx, claim)
+			} else {
+				err = config.keys.CompleteIdempotencyKey(ctx, claim, body)
+			}
+			if err != nil {
+				telemetry.Logger(ctx).Error().Err(err).Msg("Failed to record idempotent response")
+			}
+		}
+
+		// Set content type and write the response
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Language", string(locale))
+		_, _ = w.Write(append(body, '\n'))
+	}
+}
+
+// clientIP returns the IP address of the caller, taking the first X-Forwarded-For entry set
+// by the load balancer into account
+func clientIP(r *http.Request) string {
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		if ip := strings.TrimSpace(strings.Split(forwarded, ",")[0]); ip != "" {
+			return ip
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// bearerToken returns the token of a Bearer Authorization header, or an empty string
+func bearerToken(r *http.Request) string {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// logOperation logs an executed operation. Variables are logged with personal data redacted,
+// like the bodies of calls to providers.
+func logOperation(ctx context.Context, req GraphQLRequest, errorCount int, duration time.Duration) {
+	operationName := req.OperationName
+	if operationName == "" {
+		operationName = "anonymous"
+	}
+	event := telemetry.Logger(ctx).Info()
+	if errorCount > 0 {
+		event = telemetry.Logger(ctx).Warn()
+	}
+	if len(req.Variables) > 0 {
+		if variables, err := json.Marshal(req.Variables); err == nil {
+			// Scrubbed bodies may be truncated, so they are logged as text rather than JSON
+			event = event.Str("variables", outbound.Scrub("application/json", variables))
+		}
 	}
-}
\ No newline at end of file
+	event.Str("operation", operationName).
+		Dur("duration", duration).
+		Int("errors", errorCount).
+		Msg("GraphQL operation")
+}
@@ -0,0 +1,335 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/graphql-go/graphql"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/infrastructure/telemetry"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/assimoes/beautix/internal/service"
+)
+
+func TestHandlerWithRequestLogging(t *testing.T) {
+	var out bytes.Buffer
+	previous := log.Logger
+	log.Logger = zerolog.New(&out)
+	defer func() { log.Logger = previous }()
+
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"greet": &graphql.Field{
+					Type: graphql.String,
+					Args: graphql.FieldConfigArgument{
+						"clientEmail": &graphql.ArgumentConfig{Type: graphql.String},
+					},
+					Resolve: func(p graphql.ResolveParams) (any, error) { return "hello", nil },
+				},
+			},
+		}),
+	})
+	require.NoError(t, err)
+	handler := telemetry.RequestIDMiddleware(Handler(schema, WithRequestLogging()))
+
+	body := `{"query": "query Greet($email: String) { greet(clientEmail: $email) }", "operationName": "Greet", ` +
+		`"variables": {"email": "ana@example.com"}}`
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+	req.Header.Set(telemetry.RequestIDHeader, "req-7")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "req-7", rec.Header().Get(telemetry.RequestIDHeader))
+	logged := out.String()
+	assert.Contains(t, logged, `"request_id":"req-7"`)
+	assert.Contains(t, logged, `"operation":"Greet"`)
+	assert.Contains(t, logged, `"errors":0`)
+	assert.NotContains(t, logged, "ana@example.com")
+}
+
+func TestHandlerReportsVersionConflicts(t *testing.T) {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"stale": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						conflict := &domain.ConflictError{EntityType: "appointments", ID: "appointment-1", Version: 3}
+						return nil, service.NewServiceError("failed to reschedule appointment", conflict)
+					},
+				},
+				"limited": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return nil, &domain.UpgradeRequiredError{Entitlement: domain.EntitlementCampaigns, Tier: domain.TierFree, RequiredTier: domain.TierPro}
+					},
+				},
+				"broken": &graphql.Field{
+					Type:    graphql.String,
+					Resolve: func(p graphql.ResolveParams) (any, error) { return nil, errors.New("boom") },
+				},
+			},
+		}),
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"query": "{ stale limited broken }"}`))
+	rec := httptest.NewRecorder()
+	Handler(schema).ServeHTTP(rec, req)
+
+	var response GraphQLResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Len(t, response.Errors, 3)
+	codes := map[string]any{}
+	for _, e := range response.Errors {
+		codes[e.Path[0].(string)] = e.Extensions["code"]
+	}
+	assert.Equal(t, ErrorCodeVersionConflict, codes["stale"])
+	assert.Equal(t, ErrorCodeUpgradeRequired, codes["limited"])
+	assert.Nil(t, codes["broken"], "other errors carry no code")
+}
+
+func TestHandlerWithoutIntrospection(t *testing.T) {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"greeting": &graphql.Field{
+					Type:    graphql.String,
+					Resolve: func(p graphql.ResolveParams) (any, error) { return "hello", nil },
+				},
+			},
+		}),
+	})
+	require.NoError(t, err)
+	handler := Handler(schema, WithoutIntrospection())
+
+	queries := map[string]bool{
+		`{ greeting __typename }`:                                                    false,
+		`{ __schema { types { name } } }`:                                            true,
+		`{ greeting __type(name: "Query") { name } }`:                                true,
+		`{ ...Schema } fragment Schema on Query { __schema { queryType { name } } }`: true,
+		`{ _service { sdl } }`:                                                       true,
+	}
+	for query, refused := range queries {
+		body, err := json.Marshal(GraphQLRequest{Query: query})
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		var response GraphQLResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		if refused {
+			require.Len(t, response.Errors, 1, query)
+			assert.Equal(t, ErrorCodeIntrospectionDisabled, response.Errors[0].Extensions["code"], query)
+			assert.Nil(t, response.Data, query)
+		} else {
+			assert.Empty(t, response.Errors, query)
+			assert.Equal(t, map[string]any{"greeting": "hello", "__typename": "Query"}, response.Data)
+		}
+	}
+}
+
+// localePreferencesFunc adapts a function to LocalePreferences
+type localePreferencesFunc func(ctx context.Context) (string, error)
+
+func (f localePreferencesFunc) PreferredLocale(ctx context.Context) (string, error) { return f(ctx) }
+
+func TestHandlerLocalizesErrors(t *testing.T) {
+	type input struct {
+		BusinessID string `validate:"required,uuid"`
+		Name       string `validate:"max=3"`
+	}
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"invalid": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return nil, validation.FromError(validator.New().Struct(input{Name: "long"}))
+					},
+				},
+				"missing": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return nil, service.NewNotFoundError("client", "id", "client-1")
+					},
+				},
+			},
+		}),
+	})
+	require.NoError(t, err)
+
+	query := func(handler http.Handler, acceptLanguage string) map[string]map[string]any {
+		req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"query": "{ invalid missing }"}`))
+		req.Header.Set("Accept-Language", acceptLanguage)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		var response GraphQLResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		extensions := map[string]map[string]any{}
+		for _, e := range response.Errors {
+			extensions[e.Path[0].(string)] = e.Extensions
+		}
+		return extensions
+	}
+
+	portuguese := query(Handler(schema), "pt-PT,pt;q=0.9,en;q=0.8")
+	assert.Equal(t, ErrorCodeValidation, portuguese["invalid"]["code"])
+	assert.Equal(t, "Alguns dos dados não são válidos.", portuguese["invalid"]["localizedMessage"])
+	assert.Equal(t, []any{
+		map[string]any{"field": "businessId", "rule": "required", "message": "Este campo é obrigatório."},
+		map[string]any{"field": "name", "rule": "max", "message": "Não pode ter mais de 3 caracteres."},
+	}, portuguese["invalid"]["fields"])
+	assert.Equal(t, ErrorCodeNotFound, portuguese["missing"]["code"])
+	assert.Equal(t, "client", portuguese["missing"]["entity"])
+
+	preferred := Handler(schema, WithLocalePreferences(localePreferencesFunc(func(ctx context.Context) (string, error) {
+		return "en", nil
+	})))
+	english := query(preferred, "pt-PT")
+	assert.Equal(t, "Some of the details are not valid.", english["invalid"]["localizedMessage"], "the user's choice wins over the browser's")
+}
+
+// sessionCheckerFunc adapts a function to a SessionChecker
+type sessionCheckerFunc func(ctx context.Context) error
+
+func (f sessionCheckerFunc) CheckSession(ctx context.Context) error { return f(ctx) }
+
+func TestHandlerRefusesRevokedSessions(t *testing.T) {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"me": &graphql.Field{
+					Type:    graphql.String,
+					Resolve: func(p graphql.ResolveParams) (any, error) { return "someone", nil },
+				},
+			},
+		}),
+	})
+	require.NoError(t, err)
+
+	revoked := map[string]bool{"sess_old": true}
+	handler := Handler(schema, WithSessionCheck(sessionCheckerFunc(func(ctx context.Context) error {
+		if session := service.GetSessionFromContext(ctx); session != nil && revoked[session.ExternalID] {
+			return domain.ErrSessionRevoked
+		}
+		return nil
+	})))
+
+	for sessionID, status := range map[string]int{"sess_old": http.StatusUnauthorized, "sess_new": http.StatusOK} {
+		ctx := service.SetSessionContext(context.Background(), service.CallerSession{Kind: domain.SessionKindClerk, ExternalID: sessionID})
+		req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"query": "{ me }"}`)).WithContext(ctx)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, status, rec.Code, sessionID)
+	}
+}
+
+// authenticatorFunc adapts a function to an Authenticator
+type authenticatorFunc func(ctx context.Context, credentials service.Credentials) (context.Context, error)
+
+func (f authenticatorFunc) Authenticate(ctx context.Context, credentials service.Credentials) (context.Context, error) {
+	return f(ctx, credentials)
+}
+
+func TestHandlerAuthenticatesCallers(t *testing.T) {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"business": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						tenant, _ := domain.TenantFromContext(p.Context)
+						return tenant.BusinessID, nil
+					},
+				},
+			},
+		}),
+	})
+	require.NoError(t, err)
+
+	handler := Handler(schema, WithAuthentication(authenticatorFunc(func(ctx context.Context, credentials service.Credentials) (context.Context, error) {
+		switch {
+		case credentials.Token != "valid":
+			return ctx, domain.ErrUnauthenticated
+		case credentials.BusinessID == "business-2":
+			return ctx, domain.ErrCrossTenant
+		}
+		return domain.WithTenant(ctx, domain.TenantContext{BusinessID: credentials.BusinessID}), nil
+	})))
+
+	for _, tc := range []struct {
+		authorization, business string
+		status                  int
+	}{
+		{"Bearer valid", "business-1", http.StatusOK},
+		{"Bearer forged", "business-1", http.StatusUnauthorized},
+		{"Bearer valid", "business-2", http.StatusForbidden},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"query": "{ business }"}`))
+		req.Header.Set("Authorization", tc.authorization)
+		req.Header.Set(BusinessIDHeader, tc.business)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		require.Equal(t, tc.status, rec.Code, tc.authorization+" for "+tc.business)
+		if tc.status == http.StatusOK {
+			assert.JSONEq(t, `{"data": {"business": "business-1"}}`, rec.Body.String())
+		}
+	}
+}
+
+// businessCheckerFunc adapts a function to a BusinessChecker
+type businessCheckerFunc func(ctx context.Context) error
+
+func (f businessCheckerFunc) CheckBusiness(ctx context.Context) error { return f(ctx) }
+
+func TestHandlerRefusesSuspendedBusinesses(t *testing.T) {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"me": &graphql.Field{
+					Type:    graphql.String,
+					Resolve: func(p graphql.ResolveParams) (any, error) { return "someone", nil },
+				},
+			},
+		}),
+	})
+	require.NoError(t, err)
+
+	handler := Handler(schema, WithSuspensionCheck(businessCheckerFunc(func(ctx context.Context) error {
+		if businessID := service.GetBusinessIDFromContext(ctx); businessID != nil && *businessID == "business-suspended" {
+			return domain.ErrBusinessSuspended
+		}
+		return nil
+	})))
+
+	for businessID, status := range map[string]int{"business-suspended": http.StatusForbidden, "business-active": http.StatusOK} {
+		ctx := service.SetUserContext(context.Background(), "user-1", "owner", &businessID)
+		req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"query": "{ me }"}`)).WithContext(ctx)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, status, rec.Code, businessID)
+	}
+}
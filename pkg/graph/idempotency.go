@@ -0,0 +1,107 @@
+package graph
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/infrastructure/telemetry"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/assimoes/beautix/internal/service"
+)
+
+const (
+	// IdempotencyKeyHeader carries the key clients send with mutations they may retry
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader marks a response replayed to a retry rather than produced by it
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// idempotentMutations are the mutations a retry would repeat with effects clients notice, such
+// as a second appointment, a second charge or a subscription changed twice, and which therefore
+// honour idempotency keys. Mutations added with such effects belong here too.
+var idempotentMutations = map[string]bool{
+	"createAppointment":        true,
+	"requestBooking":           true,
+	"bookServiceBundle":        true,
+	"importClients":            true,
+	"chargeDeposit":            true,
+	"captureCompletionPayment": true,
+	"sellGiftCard":             true,
+	"redeemGiftCard":           true,
+	"refundPayment":            true,
+	"subscribeMembership":      true,
+	"cancelMembership":         true,
+	"changeSubscriptionPlan":   true,
+}
+
+// idempotent reports whether the operation a request runs is a mutation honouring idempotency
+// keys. Requests that do not parse are left to execution to report.
+func idempotent(req GraphQLRequest) bool {
+	document, err := parser.Parse(parser.ParseParams{Source: req.Query})
+	if err != nil {
+		return false
+	}
+	for _, definition := range document.Definitions {
+		operation, ok := definition.(*ast.OperationDefinition)
+		if !ok || operation.Operation != ast.OperationTypeMutation {
+			continue
+		}
+		if req.OperationName != "" && (operation.Name == nil || operation.Name.Value != req.OperationName) {
+			continue
+		}
+		for _, selection := range operation.SelectionSet.Selections {
+			if field, ok := selection.(*ast.Field); ok && field.Name != nil && idempotentMutations[field.Name.Value] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// requestHash identifies a request by its operation and variables, so that a key sent again
+// with another request is told apart from a retry
+func requestHash(req GraphQLRequest) string {
+	// Map keys are marshalled in order, so equal variables hash the same
+	encoded, _ := json.Marshal(req)
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:])
+}
+
+// retryable reports whether a response failed in a way a retry may not, such as a database
+// outage, rather than with an error the same request would get again. Known errors carry a code.
+func retryable(errs []GraphQLError) bool {
+	for _, err := range errs {
+		if _, ok := err.Extensions["code"]; !ok {
+			return true
+		}
+	}
+	return false
+}
+
+// writeIdempotencyError answers a request whose idempotency key could not be claimed
+func writeIdempotencyError(ctx context.Context, w http.ResponseWriter, err error) {
+	var invalid *validation.ValidationError
+	var forbidden service.ForbiddenError
+	switch {
+	case errors.Is(err, domain.ErrIdempotencyKeyReused):
+		http.Error(w, "Idempotency key reused for a different request", http.StatusUnprocessableEntity)
+	case errors.Is(err, domain.ErrIdempotencyKeyInProgress):
+		w.Header().Set("Retry-After", "1")
+		http.Error(w, "A request with this idempotency key is in progress", http.StatusConflict)
+	case errors.As(err, &invalid):
+		http.Error(w, invalid.Message, http.StatusBadRequest)
+	case errors.As(err, &forbidden):
+		http.Error(w, "Idempotency keys require signing in", http.StatusUnauthorized)
+	default:
+		telemetry.Logger(ctx).Error().Err(err).Msg("Failed to claim idempotency key")
+		http.Error(w, "Failed to claim idempotency key", http.StatusInternalServerError)
+	}
+}
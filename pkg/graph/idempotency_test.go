@@ -0,0 +1,205 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/assimoes/beautix/internal/repository/memory"
+	"github.com/assimoes/beautix/internal/service"
+)
+
+// idempotencySchema has createAppointment and sellGiftCard mutations counting their runs,
+// failing unexpectedly for the "broken" client
+func idempotencySchema(t *testing.T, runs *int) graphql.Schema {
+	t.Helper()
+	counted := func(prefix string) *graphql.Field {
+		return &graphql.Field{
+			Type: graphql.String,
+			Args: graphql.FieldConfigArgument{"client": &graphql.ArgumentConfig{Type: graphql.String}},
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				*runs++
+				if p.Args["client"] == "broken" {
+					return nil, errors.New("database unavailable")
+				}
+				return fmt.Sprintf("%s-%d", prefix, *runs), nil
+			},
+		}
+	}
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"me": &graphql.Field{Type: graphql.String, Resolve: func(p graphql.ResolveParams) (any, error) { return "someone", nil }},
+			},
+		}),
+		Mutation: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Mutation",
+			Fields: graphql.Fields{
+				"createAppointment": counted("appointment"),
+				"sellGiftCard":      counted("gift-card"),
+			},
+		}),
+	})
+	require.NoError(t, err)
+	return schema
+}
+
+// sendMutation posts createAppointment for a client with an idempotency key, as user-1
+func sendMutation(handler http.Handler, key, client string) *httptest.ResponseRecorder {
+	return postMutation(handler, key, "createAppointment", client)
+}
+
+// postMutation posts a mutation field for a client with an idempotency key, as user-1
+func postMutation(handler http.Handler, key, field, client string) *httptest.ResponseRecorder {
+	body := fmt.Sprintf(`{"query": "mutation Run($client: String) { %s(client: $client) }", "operationName": "Run", "variables": {"client": %q}}`, field, client)
+	ctx := service.SetUserContext(context.Background(), "user-1", "owner", nil)
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body)).WithContext(ctx)
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandlerReplaysRetriedMutations(t *testing.T) {
+	runs := 0
+	store := service.NewIdempotencyService(memory.NewIdempotencyKeyRepository(memory.NewDB()))
+	handler := Handler(idempotencySchema(t, &runs), WithIdempotency(store))
+
+	first := sendMutation(handler, "key-1", "ana")
+	retry := sendMutation(handler, "key-1", "ana")
+
+	require.Equal(t, http.StatusOK, first.Code)
+	require.Equal(t, http.StatusOK, retry.Code)
+	assert.Equal(t, 1, runs, "the retry does not run the mutation again")
+	assert.JSONEq(t, first.Body.String(), retry.Body.String())
+	assert.Empty(t, first.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, "true", retry.Header().Get(IdempotentReplayedHeader))
+
+	sendMutation(handler, "", "ana")
+	sendMutation(handler, "key-2", "ana")
+	assert.Equal(t, 3, runs, "requests without the key or with another key run")
+}
+
+func TestHandlerReplaysRetriedGiftCardSales(t *testing.T) {
+	runs := 0
+	store := service.NewIdempotencyService(memory.NewIdempotencyKeyRepository(memory.NewDB()))
+	handler := Handler(idempotencySchema(t, &runs), WithIdempotency(store))
+
+	first := postMutation(handler, "key-1", "sellGiftCard", "ana")
+	retry := postMutation(handler, "key-1", "sellGiftCard", "ana")
+
+	require.Equal(t, http.StatusOK, first.Code)
+	require.Equal(t, http.StatusOK, retry.Code)
+	assert.Equal(t, 1, runs, "the retry does not sell a second gift card")
+	assert.JSONEq(t, first.Body.String(), retry.Body.String())
+	assert.Equal(t, "true", retry.Header().Get(IdempotentReplayedHeader))
+}
+
+func TestHandlerRefusesKeysReusedForAnotherRequest(t *testing.T) {
+	runs := 0
+	store := service.NewIdempotencyService(memory.NewIdempotencyKeyRepository(memory.NewDB()))
+	handler := Handler(idempotencySchema(t, &runs), WithIdempotency(store))
+
+	sendMutation(handler, "key-1", "ana")
+	rec := sendMutation(handler, "key-1", "bea")
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, 1, runs)
+}
+
+func TestHandlerRunsMutationsAgainAfterUnexpectedErrors(t *testing.T) {
+	runs := 0
+	store := service.NewIdempotencyService(memory.NewIdempotencyKeyRepository(memory.NewDB()))
+	handler := Handler(idempotencySchema(t, &runs), WithIdempotency(store))
+
+	sendMutation(handler, "key-1", "broken")
+	rec := sendMutation(handler, "key-1", "broken")
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, 2, runs, "the failed response was not recorded")
+}
+
+func TestHandlerScopesAnonymousKeysToTheCallersAddress(t *testing.T) {
+	runs := 0
+	store := service.NewIdempotencyService(memory.NewIdempotencyKeyRepository(memory.NewDB()))
+	handler := Handler(idempotencySchema(t, &runs), WithIdempotency(store))
+	sendAnonymously := func(ip string) *httptest.ResponseRecorder {
+		body := `{"query": "mutation { createAppointment(client: \"ana\") }"}`
+		req := httptest.NewRequest(http.MethodPost, "/graphql/public", strings.NewReader(body))
+		req.RemoteAddr = ip + ":50000"
+		req.Header.Set(IdempotencyKeyHeader, "key-1")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := sendAnonymously("203.0.113.7")
+	retry := sendAnonymously("203.0.113.7")
+	require.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, "true", retry.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, 1, runs, "the retry does not book again")
+
+	other := sendAnonymously("198.51.100.4")
+	assert.Empty(t, other.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, 2, runs, "callers at other addresses do not share keys")
+}
+
+func TestIdempotent(t *testing.T) {
+	tests := map[string]struct {
+		req  GraphQLRequest
+		want bool
+	}{
+		"covered mutation": {
+			req:  GraphQLRequest{Query: `mutation { createAppointment(input: {}) { id } }`},
+			want: true,
+		},
+		"gift card sale": {
+			req:  GraphQLRequest{Query: `mutation { sellGiftCard(input: {}) { id } }`},
+			want: true,
+		},
+		"public booking": {
+			req:  GraphQLRequest{Query: `mutation { requestBooking(input: {}) { id } }`},
+			want: true,
+		},
+		"membership change": {
+			req:  GraphQLRequest{Query: `mutation { changeSubscriptionPlan(input: {}) { id } }`},
+			want: true,
+		},
+		"other mutation": {
+			req:  GraphQLRequest{Query: `mutation { setClientTags(input: {}) { id } }`},
+			want: false,
+		},
+		"query": {
+			req:  GraphQLRequest{Query: `{ createAppointment }`},
+			want: false,
+		},
+		"named operation": {
+			req: GraphQLRequest{
+				Query:         `mutation Tag { setClientTags(input: {}) { id } } mutation Refund { refundPayment(input: {}) { id } }`,
+				OperationName: "Refund",
+			},
+			want: true,
+		},
+		"unparsable": {
+			req:  GraphQLRequest{Query: `mutation {`},
+			want: false,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, idempotent(tt.req))
+		})
+	}
+}
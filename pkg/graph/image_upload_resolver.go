@@ -0,0 +1,57 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Image Upload Mutation Resolvers
+func (r *Resolver) resolveCreateImageUpload(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+	purpose, ok := p.Args["purpose"].(domain.ImagePurpose)
+	if !ok {
+		return nil, errors.New("purpose is required")
+	}
+	contentType, ok := p.Args["contentType"].(string)
+	if !ok {
+		return nil, errors.New("contentType is required")
+	}
+	sizeBytes, ok := p.Args["sizeBytes"].(int)
+	if !ok {
+		return nil, errors.New("sizeBytes is required")
+	}
+
+	ticket, err := r.imageUploadService.CreateImageUpload(p.Context, dto.CreateImageUploadDTO{
+		BusinessID:  businessID,
+		Purpose:     purpose,
+		StaffID:     optionalString(p.Args, "staffId"),
+		ContentType: contentType,
+		SizeBytes:   int64(sizeBytes),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ticket, nil
+}
+
+func (r *Resolver) resolveCompleteImageUpload(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+
+	upload, err := r.imageUploadService.CompleteImageUpload(p.Context, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return upload, nil
+}
@@ -0,0 +1,191 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// ImagePurposeEnum represents the GraphQL enum for what an uploaded image is shown as
+var ImagePurposeEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "ImagePurpose",
+	Description: "What an uploaded image is shown as",
+	Values: graphql.EnumValueConfigMap{
+		"BUSINESS_LOGO": &graphql.EnumValueConfig{
+			Value:       domain.ImagePurposeBusinessLogo,
+			Description: "The business's logo, served at up to 512 pixels",
+		},
+		"BUSINESS_COVER": &graphql.EnumValueConfig{
+			Value:       domain.ImagePurposeBusinessCover,
+			Description: "The banner of the business's pages, served at up to 1920 pixels",
+		},
+		"STAFF_PHOTO": &graphql.EnumValueConfig{
+			Value:       domain.ImagePurposeStaffPhoto,
+			Description: "A staff member's profile picture, served at up to 800 pixels",
+		},
+	},
+})
+
+// ImageUploadStatusEnum represents the GraphQL enum for the state of an image upload
+var ImageUploadStatusEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "ImageUploadStatus",
+	Description: "How far an image upload has got",
+	Values: graphql.EnumValueConfigMap{
+		"PENDING": &graphql.EnumValueConfig{
+			Value:       domain.ImageUploadPending,
+			Description: "Waiting for the file to be uploaded and the upload completed",
+		},
+		"ATTACHED": &graphql.EnumValueConfig{
+			Value:       domain.ImageUploadAttached,
+			Description: "Processed and shown",
+		},
+		"REJECTED": &graphql.EnumValueConfig{
+			Value:       domain.ImageUploadRejected,
+			Description: "The file was not a valid image; see the error",
+		},
+		"REMOVED": &graphql.EnumValueConfig{
+			Value:       domain.ImageUploadRemoved,
+			Description: "No longer shown, and removed from storage",
+		},
+	},
+})
+
+// ImageUploadType represents the GraphQL ImageUpload type
+var ImageUploadType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ImageUpload",
+	Description: "An image uploaded to be shown as a business's logo or cover photo, or a staff member's profile picture",
+	Fields: withBaseFields("upload", graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"purpose": &graphql.Field{
+			Type:        graphql.NewNonNull(ImagePurposeEnum),
+			Description: "What the image is shown as",
+		},
+		"targetId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business or staff member shown the image",
+		},
+		"contentType": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The media type of the file, as declared for the upload",
+		},
+		"sizeBytes": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Float),
+			Description: "The size of the file in bytes, as declared for the upload",
+		},
+		"status": &graphql.Field{
+			Type:        graphql.NewNonNull(ImageUploadStatusEnum),
+			Description: "How far the upload has got",
+		},
+		"url": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Where the processed image is served from, once attached",
+		},
+		"width": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "The width of the processed image in pixels",
+		},
+		"height": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "The height of the processed image in pixels",
+		},
+		"error": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Why the file was rejected",
+		},
+		"attachedAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the image was set on its target",
+		},
+	}),
+})
+
+// UploadHeaderType represents the GraphQL UploadHeader type
+var UploadHeaderType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "UploadHeader",
+	Description: "A header a file upload must be sent with",
+	Fields: graphql.Fields{
+		"name": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The name of the header",
+		},
+		"value": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The value of the header",
+		},
+	},
+})
+
+// ImageUploadTicketType represents the GraphQL ImageUploadTicket type
+var ImageUploadTicketType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ImageUploadTicket",
+	Description: "A presigned URL to send the file of an image upload to, before completing the upload",
+	Fields: graphql.Fields{
+		"upload": &graphql.Field{
+			Type:        graphql.NewNonNull(ImageUploadType),
+			Description: "The upload",
+		},
+		"uploadUrl": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The URL to send the file to",
+		},
+		"method": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The HTTP method to send the file with",
+		},
+		"headers": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(UploadHeaderType))),
+			Description: "The headers to send the file with, exactly as given",
+		},
+		"expiresAt": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the URL stops working",
+		},
+	},
+})
+
+// imageUploadMutationFields returns the image upload mutations
+func imageUploadMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"createImageUpload": &graphql.Field{
+			Type:        ImageUploadTicketType,
+			Description: "Get a URL to upload an image to: a JPEG, PNG or GIF of at most 10 MB (owners and managers; staff members for their own photo)",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+				"purpose": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(ImagePurposeEnum),
+					Description: "What the image will be shown as",
+				},
+				"staffId": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: "The staff member pictured; required for staff photos",
+				},
+				"contentType": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The media type of the file: image/jpeg, image/png or image/gif",
+				},
+				"sizeBytes": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.Int),
+					Description: "The exact size of the file in bytes",
+				},
+			},
+			Resolve: resolver.resolveCreateImageUpload,
+		},
+		"completeImageUpload": &graphql.Field{
+			Type:        ImageUploadType,
+			Description: "Process an uploaded image and show it: it is checked, turned upright, scaled down and stripped of metadata",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the upload",
+				},
+			},
+			Resolve: resolver.resolveCompleteImageUpload,
+		},
+	}
+}
@@ -0,0 +1,206 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+)
+
+// clearKey is the input field listing the fields an update sets to null, see clearField
+const clearKey = "clear"
+
+// nullable is implemented by dto.Optional, whose fields tell "leave unchanged" from "clear"
+type nullable interface {
+	Clear()
+}
+
+// decodeInput decodes the input object argument of a mutation into the DTO target points to.
+// See decodeValue for how fields are matched and coerced.
+func decodeInput(args map[string]any, target any) error {
+	input, ok := args["input"].(map[string]any)
+	if !ok {
+		return errors.New("input is required")
+	}
+	return decodeValue(input, target, "input")
+}
+
+// decodeValue decodes a GraphQL argument value into the value target points to. Input object
+// fields fill the struct fields whose Go name or JSON tag matches ignoring case and underscores,
+// so businessId fills BusinessID; fields of embedded structs are matched as if they were the
+// outer struct's own. Absent and null fields leave the target untouched, which keeps defaults
+// set before decoding and leaves pointers nil for partial updates. dto.Optional fields are
+// marked as set when present, and cleared when named in the input's clear list. Lists and
+// nested input objects decode recursively. Values that cannot be coerced to the field's type
+// are reported as validation errors naming the offending field.
+func decodeValue(value any, target any, path string) error {
+	destination := reflect.ValueOf(target)
+	if destination.Kind() != reflect.Pointer || destination.IsNil() {
+		return fmt.Errorf("cannot decode %s into %T", path, target)
+	}
+	return decodeInto(reflect.ValueOf(value), destination.Elem(), path)
+}
+
+// decodeInto assigns a decoded value to a settable destination
+func decodeInto(value, destination reflect.Value, path string) error {
+	if !value.IsValid() {
+		return nil
+	}
+	if value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+
+	// Custom scalars and enums arrive as their Go values, e.g. decimal.Decimal or domain enums
+	if value.Type().AssignableTo(destination.Type()) {
+		destination.Set(value)
+		return nil
+	}
+
+	if _, ok := destination.Addr().Interface().(nullable); ok {
+		if err := decodeInto(value, destination.FieldByName("Value"), path); err != nil {
+			return err
+		}
+		destination.FieldByName("Set").SetBool(true)
+		return nil
+	}
+
+	switch destination.Kind() {
+	case reflect.Pointer:
+		element := reflect.New(destination.Type().Elem())
+		if err := decodeInto(value, element.Elem(), path); err != nil {
+			return err
+		}
+		destination.Set(element)
+		return nil
+	case reflect.Struct:
+		input, ok := value.Interface().(map[string]any)
+		if !ok {
+			return invalidInput(path, "an object")
+		}
+		return decodeStruct(input, destination, path)
+	case reflect.Slice:
+		if value.Kind() != reflect.Slice {
+			return invalidInput(path, "a list")
+		}
+		items := reflect.MakeSlice(destination.Type(), value.Len(), value.Len())
+		for i := range value.Len() {
+			if err := decodeInto(value.Index(i), items.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		destination.Set(items)
+		return nil
+	}
+
+	// Named types such as domain enums convert from values of the same kind, and integers
+	// widen to floats the way GraphQL coerces Int literals into Float arguments
+	if value.CanConvert(destination.Type()) && sameKind(value.Kind(), destination.Kind()) {
+		converted := value.Convert(destination.Type())
+		if value.CanFloat() && !converted.CanFloat() && float64(converted.Int()) != value.Float() {
+			return invalidInput(path, "a whole number")
+		}
+		destination.Set(converted)
+		return nil
+	}
+	return invalidInput(path, describeKind(destination.Type()))
+}
+
+// decodeStruct fills the fields of a struct from an input object
+func decodeStruct(input map[string]any, destination reflect.Value, path string) error {
+	fields := inputFields(destination)
+	for key, value := range input {
+		field, ok := fields[normalizeInputName(key)]
+		if !ok {
+			continue
+		}
+		if err := decodeInto(reflect.ValueOf(value), field, path+"."+key); err != nil {
+			return err
+		}
+	}
+
+	cleared, _ := input[clearKey].([]any)
+	for _, item := range cleared {
+		key, _ := item.(string)
+		field, ok := fields[normalizeInputName(key)]
+		if !ok || key == clearKey {
+			return validation.NewFieldValidationError(path+"."+clearKey, fmt.Sprintf("unknown field %q", key))
+		}
+		target, ok := field.Addr().Interface().(nullable)
+		if !ok {
+			return validation.NewFieldValidationError(path+"."+clearKey, fmt.Sprintf("%s cannot be cleared", key))
+		}
+		if _, ok := input[key]; ok {
+			return validation.NewFieldValidationError(path+"."+key, "cannot be both set and cleared")
+		}
+		target.Clear()
+	}
+	return nil
+}
+
+// inputFields indexes the settable fields of a struct, including those of embedded structs,
+// by their normalized Go and JSON names
+func inputFields(destination reflect.Value) map[string]reflect.Value {
+	fields := map[string]reflect.Value{}
+	structType := destination.Type()
+	for i := range structType.NumField() {
+		field := structType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			for name, embedded := range inputFields(destination.Field(i)) {
+				if _, ok := fields[name]; !ok {
+					fields[name] = embedded
+				}
+			}
+			continue
+		}
+		fields[normalizeInputName(field.Name)] = destination.Field(i)
+		if name, _, _ := strings.Cut(field.Tag.Get("json"), ","); name != "" && name != "-" {
+			fields[normalizeInputName(name)] = destination.Field(i)
+		}
+	}
+	return fields
+}
+
+// normalizeInputName folds the GraphQL, Go, and JSON spellings of a field name together
+func normalizeInputName(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
+
+// sameKind reports whether a value of one kind may be converted to another without changing
+// its meaning, i.e. between numbers or between strings
+func sameKind(from, to reflect.Kind) bool {
+	return (isNumberKind(from) && isNumberKind(to)) || from == to
+}
+
+// isNumberKind reports whether a kind is an integer or floating-point number
+func isNumberKind(kind reflect.Kind) bool {
+	return (kind >= reflect.Int && kind <= reflect.Uint64) || kind == reflect.Float32 || kind == reflect.Float64
+}
+
+// describeKind names the expected type of a field in validation messages
+func describeKind(t reflect.Type) string {
+	switch {
+	case t.Kind() == reflect.String:
+		return "a string"
+	case t.Kind() == reflect.Bool:
+		return "a boolean"
+	case isNumberKind(t.Kind()):
+		return "a number"
+	case t.Kind() == reflect.Map:
+		return "an object"
+	default:
+		return "a " + t.Name()
+	}
+}
+
+// invalidInput reports an input field whose value has the wrong type
+func invalidInput(path, expected string) error {
+	return validation.NewFieldValidationError(path, "must be "+expected)
+}
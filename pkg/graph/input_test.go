@@ -0,0 +1,128 @@
+package graph
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+)
+
+func TestDecodeInput(t *testing.T) {
+	t.Run("fields match their camelCase names", func(t *testing.T) {
+		start := time.Date(2024, 6, 3, 9, 0, 0, 0, time.UTC)
+		var createDTO dto.CreateAppointmentDTO
+		err := decodeInput(map[string]any{"input": map[string]any{
+			"businessId": "business-1",
+			"clientId":   "client-1",
+			"staffId":    "staff-1",
+			"startTime":  start,
+			"notes":      "First visit",
+		}}, &createDTO)
+		require.NoError(t, err)
+
+		assert.Equal(t, "business-1", createDTO.BusinessID)
+		assert.Equal(t, "staff-1", createDTO.StaffID)
+		assert.True(t, createDTO.StartTime.Equal(start))
+		require.NotNil(t, createDTO.Notes)
+		assert.Equal(t, "First visit", *createDTO.Notes)
+	})
+
+	t.Run("absent and null fields are left untouched", func(t *testing.T) {
+		updateDTO := dto.UpdateUserDTO{}
+		err := decodeInput(map[string]any{"input": map[string]any{"firstName": "Ana", "phone": nil}}, &updateDTO)
+		require.NoError(t, err)
+
+		require.NotNil(t, updateDTO.FirstName)
+		assert.Equal(t, "Ana", *updateDTO.FirstName)
+		assert.Nil(t, updateDTO.LastName)
+		assert.False(t, updateDTO.Phone.Set)
+		assert.Nil(t, updateDTO.IsActive)
+
+		sendDTO := dto.SendBroadcastDTO{OfferReschedule: true}
+		require.NoError(t, decodeInput(map[string]any{"input": map[string]any{"message": "Closed today"}}, &sendDTO))
+		assert.True(t, sendDTO.OfferReschedule)
+	})
+
+	t.Run("optional fields are set or cleared", func(t *testing.T) {
+		var updateDTO dto.UpdateServiceRecordDTO
+		err := decodeInput(map[string]any{"input": map[string]any{
+			"processingTime": 25,
+			"clear":          []any{"staffId", "notes"},
+		}}, &updateDTO)
+		require.NoError(t, err)
+
+		assert.Equal(t, dto.Some(25), updateDTO.ProcessingTime)
+		assert.True(t, updateDTO.StaffID.IsNull())
+		assert.True(t, updateDTO.Notes.IsNull())
+
+		err = decodeInput(map[string]any{"input": map[string]any{
+			"notes": "Sensitive scalp",
+			"clear": []any{"notes"},
+		}}, &updateDTO)
+		var invalid *validation.ValidationError
+		require.True(t, errors.As(err, &invalid))
+		assert.Equal(t, "input.notes", invalid.Field)
+
+		err = decodeInput(map[string]any{"input": map[string]any{"clear": []any{"isDraft"}}}, &updateDTO)
+		require.True(t, errors.As(err, &invalid))
+		assert.Equal(t, "isDraft cannot be cleared", invalid.Message)
+	})
+
+	t.Run("nested lists, enums, and scalars", func(t *testing.T) {
+		var createDTO dto.CreateServiceRecordTemplateDTO
+		err := decodeInput(map[string]any{"input": map[string]any{
+			"businessId": "business-1",
+			"name":       "Colour",
+			"fields": []any{
+				map[string]any{"key": "developer", "label": "Developer", "type": domain.ServiceRecordFieldType("select"), "options": []any{"20 vol", "30 vol"}},
+			},
+		}}, &createDTO)
+		require.NoError(t, err)
+		require.Len(t, createDTO.Fields, 1)
+		assert.Equal(t, domain.ServiceRecordFieldType("select"), createDTO.Fields[0].Type)
+		assert.Equal(t, []string{"20 vol", "30 vol"}, createDTO.Fields[0].Options)
+
+		var planDTO dto.CreateCommissionPlanDTO
+		err = decodeInput(map[string]any{"input": map[string]any{
+			"serviceTiers": []any{map[string]any{"threshold": decimal.NewFromInt(1000), "rate": decimal.NewFromInt(40)}},
+		}}, &planDTO)
+		require.NoError(t, err)
+		require.Len(t, planDTO.ServiceTiers, 1)
+		assert.True(t, planDTO.ServiceTiers[0].Rate.Equal(decimal.NewFromInt(40)))
+	})
+
+	t.Run("integers widen to floats", func(t *testing.T) {
+		var recordDTO dto.UpdateServiceRecordDTO
+		err := decodeInput(map[string]any{"input": map[string]any{
+			"productsUsed": []any{map[string]any{"name": "Developer", "amount": 30}},
+		}}, &recordDTO)
+		require.NoError(t, err)
+		require.Len(t, recordDTO.ProductsUsed, 1)
+		require.NotNil(t, recordDTO.ProductsUsed[0].Amount)
+		assert.Equal(t, 30.0, *recordDTO.ProductsUsed[0].Amount)
+	})
+
+	t.Run("wrong types are validation errors naming the field", func(t *testing.T) {
+		var recordDTO dto.UpdateServiceRecordDTO
+		err := decodeInput(map[string]any{"input": map[string]any{
+			"productsUsed": []any{map[string]any{"name": 42}},
+		}}, &recordDTO)
+
+		var invalid *validation.ValidationError
+		require.True(t, errors.As(err, &invalid))
+		assert.Equal(t, "input.productsUsed[0].name", invalid.Field)
+		assert.Equal(t, "must be a string", invalid.Message)
+	})
+
+	t.Run("input is required", func(t *testing.T) {
+		var createDTO dto.CreateAppointmentDTO
+		assert.EqualError(t, decodeInput(map[string]any{}, &createDTO), "input is required")
+	})
+}
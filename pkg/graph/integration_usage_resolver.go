@@ -0,0 +1,31 @@
+package graph
+
+import (
+	"errors"
+	"time"
+
+	"github.com/graphql-go/graphql"
+)
+
+// Integration Usage Query Resolvers
+func (r *Resolver) resolveIntegrationUsage(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+	startDate, ok := p.Args["startDate"].(time.Time)
+	if !ok {
+		return nil, errors.New("startDate is required")
+	}
+	endDate, ok := p.Args["endDate"].(time.Time)
+	if !ok {
+		return nil, errors.New("endDate is required")
+	}
+
+	usage, err := r.integrationUsageService.GetUsage(p.Context, businessID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	return usage, nil
+}
@@ -0,0 +1,156 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// IntegrationChannelEnum represents the kinds of integration traffic
+var IntegrationChannelEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "IntegrationChannel",
+	Description: "The kind of integration traffic",
+	Values: graphql.EnumValueConfigMap{
+		"API": &graphql.EnumValueConfig{
+			Value:       domain.IntegrationChannelAPI,
+			Description: "Requests made to the API",
+		},
+		"WEBHOOK": &graphql.EnumValueConfig{
+			Value:       domain.IntegrationChannelWebhook,
+			Description: "Webhook deliveries to the business's endpoints",
+		},
+	},
+})
+
+// IntegrationUsageSourceType represents the GraphQL type for the traffic of a single API key or webhook endpoint
+var IntegrationUsageSourceType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "IntegrationUsageSource",
+	Description: "The traffic of a single API key or webhook endpoint on a day",
+	Fields: graphql.Fields{
+		"channel": &graphql.Field{
+			Type:        graphql.NewNonNull(IntegrationChannelEnum),
+			Description: "The kind of traffic",
+		},
+		"sourceId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The API key or webhook endpoint, empty when unknown",
+		},
+		"requests": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The number of requests or delivery attempts",
+		},
+		"errors": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The number of failed requests or delivery attempts",
+		},
+		"errorRate": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Float),
+			Description: "The share of failures, between 0 and 1",
+		},
+	},
+})
+
+// IntegrationUsageDayType represents the GraphQL type for a day of integration traffic
+var IntegrationUsageDayType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "IntegrationUsageDay",
+	Description: "A business's integration traffic on a single UTC day",
+	Fields: graphql.Fields{
+		"date": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "The start of the day",
+		},
+		"apiRequests": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The number of API requests",
+		},
+		"apiErrors": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The number of API requests that returned errors",
+		},
+		"apiErrorRate": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Float),
+			Description: "The share of API requests that returned errors, between 0 and 1",
+		},
+		"webhookDeliveries": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The number of webhook delivery attempts",
+		},
+		"webhookFailures": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The number of failed webhook delivery attempts",
+		},
+		"webhookSuccessRate": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Float),
+			Description: "The share of successful webhook deliveries, between 0 and 1",
+		},
+		"sources": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(IntegrationUsageSourceType))),
+			Description: "The traffic broken down by API key and webhook endpoint",
+		},
+	},
+})
+
+// IntegrationUsageType represents the GraphQL type for a business's integration traffic over a date range
+var IntegrationUsageType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "IntegrationUsage",
+	Description: "A business's API and webhook traffic over a date range",
+	Fields: graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"startDate": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "The first day of the report",
+		},
+		"endDate": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "The last day of the report",
+		},
+		"apiRequests": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The total number of API requests",
+		},
+		"apiErrorRate": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Float),
+			Description: "The share of API requests that returned errors, between 0 and 1",
+		},
+		"webhookDeliveries": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The total number of webhook delivery attempts",
+		},
+		"webhookSuccessRate": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Float),
+			Description: "The share of successful webhook deliveries, between 0 and 1",
+		},
+		"days": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(IntegrationUsageDayType))),
+			Description: "The traffic per day, including days without traffic",
+		},
+	},
+})
+
+// integrationUsageQueryFields returns the integration usage queries
+func integrationUsageQueryFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"integrationUsage": &graphql.Field{
+			Type:        IntegrationUsageType,
+			Description: "Get a business's API request and webhook delivery statistics per day",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+				"startDate": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.DateTime),
+					Description: "The first day of the report",
+				},
+				"endDate": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.DateTime),
+					Description: "The last day of the report (inclusive, at most 90 days after startDate)",
+				},
+			},
+			Resolve: resolver.resolveIntegrationUsage,
+		},
+	}
+}
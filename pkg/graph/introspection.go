@@ -0,0 +1,40 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/kinds"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/visitor"
+)
+
+// ErrorCodeIntrospectionDisabled is the code of the error refusing queries for the schema when
+// introspection is turned off
+const ErrorCodeIntrospectionDisabled = "INTROSPECTION_DISABLED"
+
+// schemaFields are the fields that describe the schema rather than the data. The federation
+// _service field is among them as it returns the whole schema too.
+var schemaFields = map[string]bool{"__schema": true, "__type": true, "_service": true}
+
+// introspects reports whether a query asks for the schema anywhere, including in fragments.
+// Queries that do not parse are left to execution to report.
+func introspects(query string) bool {
+	document, err := parser.Parse(parser.ParseParams{Source: query})
+	if err != nil {
+		return false
+	}
+	found := false
+	visitor.Visit(document, &visitor.VisitorOptions{
+		KindFuncMap: map[string]visitor.NamedVisitFuncs{
+			kinds.Field: {
+				Kind: func(p visitor.VisitFuncParams) (string, any) {
+					if field, ok := p.Node.(*ast.Field); ok && field.Name != nil && schemaFields[field.Name.Value] {
+						found = true
+						return visitor.ActionBreak, nil
+					}
+					return visitor.ActionNoChange, nil
+				},
+			},
+		},
+	}, nil)
+	return found
+}
@@ -0,0 +1,85 @@
+package graph
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/assimoes/beautix/internal/service"
+)
+
+// InvoiceDownloadPath is the path prefix of printable invoice downloads
+const InvoiceDownloadPath = "/portal/invoices/"
+
+// invoiceTemplate renders a printable invoice
+var invoiceTemplate = template.Must(template.New("invoice").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <title>Invoice {{.InvoiceNumber}}</title>
+    <style>
+        body { font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; margin: 40px; color: #333; }
+        table { width: 100%; border-collapse: collapse; margin-top: 24px; }
+        th, td { padding: 8px; border-bottom: 1px solid #ddd; text-align: left; }
+        td.amount, th.amount { text-align: right; }
+        tfoot td { font-weight: bold; }
+    </style>
+</head>
+<body>
+    <h1>Invoice {{.InvoiceNumber}}</h1>
+    <p>
+        <strong>{{.BusinessName}}</strong><br>
+        {{if .BusinessTaxID}}Tax ID: {{.BusinessTaxID}}<br>{{end}}
+        Issued: {{.IssuedAt.Format "2006-01-02"}}
+    </p>
+    <p>Billed to: {{.ClientName}}</p>
+    <table>
+        <thead><tr><th>Description</th><th class="amount">Amount ({{.Currency}})</th></tr></thead>
+        <tbody>
+        {{range .LineItems}}<tr><td>{{.Description}}</td><td class="amount">{{.Amount.StringFixed 2}}</td></tr>
+        {{end}}</tbody>
+        <tfoot>
+            <tr><td>Total</td><td class="amount">{{.Total.StringFixed 2}}</td></tr>
+            <tr><td>Paid{{if .PaymentMethod}} ({{.PaymentMethod}}){{end}}</td><td class="amount">{{.AmountPaid.StringFixed 2}}</td></tr>
+        </tfoot>
+    </table>
+</body>
+</html>
+`))
+
+// InvoiceDownloadHandler serves a printable copy of the signed-in client's invoice for the
+// appointment in the URL path. It must be registered under InvoiceDownloadPath.
+func InvoiceDownloadHandler(portalService service.ClientPortalService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Only GET requests are allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		appointmentID := r.URL.Path[len(InvoiceDownloadPath):]
+		invoice, err := portalService.GetMyInvoice(r.Context(), appointmentID)
+		if err != nil {
+			var notFound service.NotFoundError
+			var forbidden service.ForbiddenError
+			var invalid *validation.ValidationError
+			switch {
+			case errors.As(err, &notFound):
+				http.Error(w, "Invoice not found", http.StatusNotFound)
+			case errors.As(err, &forbidden):
+				http.Error(w, "Sign in to download invoices", http.StatusUnauthorized)
+			case errors.As(err, &invalid):
+				http.Error(w, invalid.Error(), http.StatusConflict)
+			default:
+				http.Error(w, "Failed to retrieve invoice", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="invoice-`+invoice.InvoiceNumber+`.html"`)
+		if err := invoiceTemplate.Execute(w, invoice); err != nil {
+			http.Error(w, "Failed to render invoice", http.StatusInternalServerError)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Background Job Query Resolvers
+func (r *Resolver) resolveJobs(p graphql.ResolveParams) (any, error) {
+	var filter dto.JobFilterDTO
+	if err := decodeValue(p.Args["filter"], &filter, "filter"); err != nil {
+		return nil, err
+	}
+	page, pageSize := pageFromArgs(p.Args, 50)
+
+	jobs, _, err := r.jobService.ListJobs(p.Context, filter, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// Background Job Mutation Resolvers
+func (r *Resolver) resolveRetryJob(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+
+	job, err := r.jobService.RetryJob(p.Context, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
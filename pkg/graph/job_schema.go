@@ -0,0 +1,132 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+)
+
+// JobStatusEnum represents the GraphQL JobStatus enum
+var JobStatusEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "JobStatus",
+	Description: "The state of a background job",
+	Values: graphql.EnumValueConfigMap{
+		"PENDING": &graphql.EnumValueConfig{
+			Value:       "pending",
+			Description: "Waiting to run, or to be retried",
+		},
+		"SUCCEEDED": &graphql.EnumValueConfig{
+			Value:       "succeeded",
+			Description: "Ran to completion",
+		},
+		"DEAD": &graphql.EnumValueConfig{
+			Value:       "dead",
+			Description: "Failed every attempt, or permanently; can be retried",
+		},
+		"CANCELLED": &graphql.EnumValueConfig{
+			Value:       "cancelled",
+			Description: "Called off before it ran",
+		},
+	},
+})
+
+// JobType represents the GraphQL Job type
+var JobType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Job",
+	Description: "A unit of background work in the job queue",
+	Fields: withBaseFields("job", graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The ID of the business the job runs for",
+		},
+		"kind": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "What the job does, e.g. deposit.expire",
+		},
+		"uniqueKey": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The key keeping the job unique among the pending jobs of its kind",
+		},
+		"payload": &graphql.Field{
+			Type:        graphql.NewNonNull(JSONScalar),
+			Description: "The job data",
+		},
+		"status": &graphql.Field{
+			Type:        graphql.NewNonNull(JobStatusEnum),
+			Description: "The state of the job",
+		},
+		"runAt": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the job is due, or until when a worker holds it",
+		},
+		"attempts": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "How many times the job has run",
+		},
+		"maxAttempts": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "How many times the job runs before it is dead-lettered",
+		},
+		"lastError": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Why the last attempt failed",
+		},
+		"completedAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the job succeeded, died or was cancelled",
+		},
+	}),
+})
+
+// JobFilterInput represents the GraphQL input for browsing background jobs
+var JobFilterInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "JobFilterInput",
+	Description: "Criteria for browsing background jobs",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"status": &graphql.InputObjectFieldConfig{
+			Type:        JobStatusEnum,
+			Description: "Only jobs in this state",
+		},
+		"kind": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "Only jobs of this kind",
+		},
+		"businessId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "Only jobs of this business",
+		},
+	},
+})
+
+// jobQueryFields returns the background job admin queries
+func jobQueryFields(resolver *Resolver) graphql.Fields {
+	listArgs := paginationArgs("jobs", 50)
+	listArgs["filter"] = &graphql.ArgumentConfig{
+		Type:        JobFilterInput,
+		Description: "The filter criteria",
+	}
+
+	return graphql.Fields{
+		"jobs": &graphql.Field{
+			Type:        graphql.NewList(JobType),
+			Description: "Browse the background job queue, most recently due first (platform admins only)",
+			Args:        listArgs,
+			Resolve:     resolver.resolveJobs,
+		},
+	}
+}
+
+// jobMutationFields returns the background job admin mutations
+func jobMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"retryJob": &graphql.Field{
+			Type:        JobType,
+			Description: "Put a dead-lettered job back in the queue with a fresh set of attempts (platform admins only)",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the job",
+				},
+			},
+			Resolve: resolver.resolveRetryJob,
+		},
+	}
+}
@@ -0,0 +1,109 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Membership Query Resolvers
+func (r *Resolver) resolveClientMemberships(p graphql.ResolveParams) (any, error) {
+	clientID, ok := p.Args["clientId"].(string)
+	if !ok {
+		return nil, errors.New("clientId is required")
+	}
+
+	memberships, err := r.membershipService.GetClientMemberships(p.Context, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	return memberships, nil
+}
+
+func (r *Resolver) resolveMembershipCancellationQuote(p graphql.ResolveParams) (any, error) {
+	membershipID, ok := p.Args["membershipId"].(string)
+	if !ok {
+		return nil, errors.New("membershipId is required")
+	}
+
+	quote, err := r.membershipService.GetCancellationQuote(p.Context, membershipID)
+	if err != nil {
+		return nil, err
+	}
+
+	return quote, nil
+}
+
+// Membership Mutation Resolvers
+func (r *Resolver) resolveCreateMembershipPlan(p graphql.ResolveParams) (any, error) {
+	createDTO := dto.CreateMembershipPlanDTO{}
+	if err := decodeInput(p.Args, &createDTO); err != nil {
+		return nil, err
+	}
+
+	plan, err := r.membershipService.CreatePlan(p.Context, createDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+func (r *Resolver) resolveSubscribeMembership(p graphql.ResolveParams) (any, error) {
+	subscribeDTO := dto.SubscribeMembershipDTO{}
+	if err := decodeInput(p.Args, &subscribeDTO); err != nil {
+		return nil, err
+	}
+
+	membership, err := r.membershipService.Subscribe(p.Context, subscribeDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return membership, nil
+}
+
+func (r *Resolver) resolveUpdateMembershipPaymentMethod(p graphql.ResolveParams) (any, error) {
+	updateDTO := dto.UpdateMembershipPaymentMethodDTO{}
+	if err := decodeInput(p.Args, &updateDTO); err != nil {
+		return nil, err
+	}
+
+	membership, err := r.membershipService.UpdatePaymentMethod(p.Context, updateDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return membership, nil
+}
+
+func (r *Resolver) resolveCancelMembership(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+
+	membership, err := r.membershipService.CancelMembership(p.Context, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return membership, nil
+}
+
+func (r *Resolver) resolveUseMembershipSession(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+
+	membership, err := r.membershipService.UseSession(p.Context, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return membership, nil
+}
@@ -0,0 +1,537 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// MembershipStatusEnum represents the GraphQL enum for membership billing statuses
+var MembershipStatusEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "MembershipStatus",
+	Description: "The billing status of a client's membership",
+	Values: graphql.EnumValueConfigMap{
+		"ACTIVE": &graphql.EnumValueConfig{
+			Value:       domain.MembershipActive,
+			Description: "Paid up",
+		},
+		"PAST_DUE": &graphql.EnumValueConfig{
+			Value:       domain.MembershipPastDue,
+			Description: "A payment failed and is being retried; the client keeps access during the grace period",
+		},
+		"SUSPENDED": &graphql.EnumValueConfig{
+			Value:       domain.MembershipSuspended,
+			Description: "The grace period ended unpaid; updating the card restarts the membership",
+		},
+		"CANCELLED": &graphql.EnumValueConfig{
+			Value:       domain.MembershipCancelled,
+			Description: "No longer billed; access lasts to the end of the paid period",
+		},
+	},
+})
+
+// MembershipInvoiceStatusEnum represents the GraphQL enum for membership invoice statuses
+var MembershipInvoiceStatusEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "MembershipInvoiceStatus",
+	Description: "The status of a membership invoice",
+	Values: graphql.EnumValueConfigMap{
+		"OPEN": &graphql.EnumValueConfig{
+			Value:       domain.MembershipInvoiceOpen,
+			Description: "Awaiting payment",
+		},
+		"PAID": &graphql.EnumValueConfig{
+			Value:       domain.MembershipInvoicePaid,
+			Description: "Paid",
+		},
+		"UNCOLLECTIBLE": &graphql.EnumValueConfig{
+			Value:       domain.MembershipInvoiceUncollectible,
+			Description: "Given up on when the membership was suspended",
+		},
+	},
+})
+
+// RefundPolicyEnum represents the GraphQL enum for membership refund policies
+var RefundPolicyEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "RefundPolicy",
+	Description: "What a client gets back for the unused part of the paid period when their membership is cancelled",
+	Values: graphql.EnumValueConfigMap{
+		"NONE": &graphql.EnumValueConfig{
+			Value:       domain.RefundPolicyNone,
+			Description: "Nothing; access lasts to the end of the paid period instead",
+		},
+		"REFUND": &graphql.EnumValueConfig{
+			Value:       domain.RefundPolicyRefund,
+			Description: "Refunded to the card the period was paid with",
+		},
+		"CREDIT": &graphql.EnumValueConfig{
+			Value:       domain.RefundPolicyCredit,
+			Description: "Issued as store credit on a gift card",
+		},
+	},
+})
+
+// CreditNoteKindEnum represents the GraphQL enum for how membership credit notes were settled
+var CreditNoteKindEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "CreditNoteKind",
+	Description: "How a membership credit note was settled",
+	Values: graphql.EnumValueConfigMap{
+		"REFUND": &graphql.EnumValueConfig{
+			Value:       domain.CreditNoteRefund,
+			Description: "Refunded to the card",
+		},
+		"CREDIT": &graphql.EnumValueConfig{
+			Value:       domain.CreditNoteCredit,
+			Description: "Issued as store credit on a gift card",
+		},
+	},
+})
+
+// MembershipPlanType represents the GraphQL MembershipPlan type
+var MembershipPlanType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "MembershipPlan",
+	Description: "A recurring membership sold by a business, billed monthly",
+	Fields: withBaseFields("membership plan", graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"name": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The name of the plan",
+		},
+		"description": &graphql.Field{
+			Type:        graphql.String,
+			Description: "What the plan includes",
+		},
+		"price": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The monthly price",
+		},
+		"currency": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The currency of the price",
+		},
+		"sessionsPerPeriod": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "The sessions included each month, for packages; unlimited when not set",
+		},
+		"refundPolicy": &graphql.Field{
+			Type:        graphql.NewNonNull(RefundPolicyEnum),
+			Description: "What a client gets back for the unused part of the paid period on cancelling",
+		},
+		"cancellationFeePercent": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The percentage kept from the unused part refunded or credited",
+		},
+		"isActive": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether clients can join the plan",
+		},
+	}),
+})
+
+// MembershipInvoiceType represents the GraphQL MembershipInvoice type
+var MembershipInvoiceType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "MembershipInvoice",
+	Description: "The invoice of one period of a membership",
+	Fields: graphql.Fields{
+		"id": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the invoice",
+		},
+		"periodStart": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the billed period starts",
+		},
+		"periodEnd": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the billed period ends",
+		},
+		"amount": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The amount billed",
+		},
+		"currency": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The currency of the amount",
+		},
+		"status": &graphql.Field{
+			Type:        graphql.NewNonNull(MembershipInvoiceStatusEnum),
+			Description: "The status of the invoice",
+		},
+		"attempts": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "How many times payment was attempted",
+		},
+		"failureReason": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Why the last payment attempt failed",
+		},
+		"paidAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the invoice was paid",
+		},
+	},
+})
+
+// MembershipCreditNoteType represents the GraphQL MembershipCreditNote type
+var MembershipCreditNoteType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "MembershipCreditNote",
+	Description: "What was given back for the unused part of a paid period when a membership was cancelled",
+	Fields: graphql.Fields{
+		"id": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the credit note",
+		},
+		"invoiceId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the paid invoice credited",
+		},
+		"kind": &graphql.Field{
+			Type:        graphql.NewNonNull(CreditNoteKindEnum),
+			Description: "How the credit note was settled",
+		},
+		"unusedShare": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The unused share of the period, between 0 and 1",
+		},
+		"fee": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The cancellation fee kept",
+		},
+		"amount": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The amount refunded or credited",
+		},
+		"currency": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The currency of the amounts",
+		},
+		"sessionsForfeited": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The sessions left in the period when it ended",
+		},
+		"refundId": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The Stripe refund, for refunds",
+		},
+		"giftCardId": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The gift card holding the store credit, for credits",
+		},
+		"createdAt": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the membership was cancelled",
+		},
+	},
+})
+
+// MembershipCancellationQuoteType represents the GraphQL MembershipCancellationQuote type
+var MembershipCancellationQuoteType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "MembershipCancellationQuote",
+	Description: "What cancelling a membership now would give back under its plan's refund policy",
+	Fields: graphql.Fields{
+		"membershipId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the membership",
+		},
+		"refundPolicy": &graphql.Field{
+			Type:        graphql.NewNonNull(RefundPolicyEnum),
+			Description: "The plan's refund policy",
+		},
+		"paidAmount": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The amount paid for the current period",
+		},
+		"unusedShare": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The unused share of the period, by sessions for packages and by time otherwise",
+		},
+		"unused": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The value of the unused part",
+		},
+		"fee": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The cancellation fee that would be kept",
+		},
+		"amount": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The amount that would be refunded or credited",
+		},
+		"currency": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The currency of the amounts",
+		},
+		"sessionsRemaining": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The sessions left in the period",
+		},
+	},
+})
+
+// ClientMembershipType represents the GraphQL ClientMembership type
+var ClientMembershipType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ClientMembership",
+	Description: "A client's membership of a plan, with its billing status",
+	Fields: withBaseFields("membership", graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"planId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the plan",
+		},
+		"planName": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The name of the plan",
+		},
+		"clientId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the client",
+		},
+		"status": &graphql.Field{
+			Type:        graphql.NewNonNull(MembershipStatusEnum),
+			Description: "The billing status",
+		},
+		"hasAccess": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether the client can use the membership now",
+		},
+		"price": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The monthly price, fixed when the client joined",
+		},
+		"currency": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The currency of the price",
+		},
+		"currentPeriodStart": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the last paid period started",
+		},
+		"currentPeriodEnd": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the last paid period ends",
+		},
+		"sessionsPerPeriod": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "The sessions included each month, for packages",
+		},
+		"sessionsRemaining": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "The sessions left in the current period, for packages",
+		},
+		"nextBillingAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the next period is billed, while the membership is active",
+		},
+		"failedAttempts": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "Failed payments of the open invoice",
+		},
+		"nextRetryAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the failed payment is retried",
+		},
+		"graceUntil": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the membership is suspended unless paid",
+		},
+		"suspendedAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the membership was suspended",
+		},
+		"cancelledAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the membership was cancelled",
+		},
+		"amountDue": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The amount of the open invoice",
+		},
+		"invoices": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(MembershipInvoiceType))),
+			Description: "The invoices, most recent period first",
+		},
+		"creditNotes": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(MembershipCreditNoteType))),
+			Description: "What was refunded or credited on cancelling",
+		},
+		"client": clientRelation(func(m *dto.ClientMembershipResponseDTO) string { return m.ClientID }),
+	}),
+})
+
+// CreateMembershipPlanInput represents the GraphQL input for creating a membership plan
+var CreateMembershipPlanInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "CreateMembershipPlanInput",
+	Description: "Input for creating a monthly membership plan",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"businessId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"name": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The name of the plan",
+		},
+		"description": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "What the plan includes",
+		},
+		"price": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The monthly price, in the business's currency",
+		},
+		"sessionsPerPeriod": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Int,
+			Description: "The sessions included each month, making the plan a package",
+		},
+		"refundPolicy": &graphql.InputObjectFieldConfig{
+			Type:        RefundPolicyEnum,
+			Description: "What a client gets back for the unused part of the paid period on cancelling; NONE by default",
+		},
+		"cancellationFeePercent": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Int,
+			Description: "The percentage kept from the unused part refunded or credited",
+		},
+	},
+})
+
+// SubscribeMembershipInput represents the GraphQL input for subscribing a client to a plan
+var SubscribeMembershipInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "SubscribeMembershipInput",
+	Description: "Input for subscribing a client to a membership plan",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"planId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the plan",
+		},
+		"clientId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the client",
+		},
+		"paymentMethodId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The Stripe payment method saved for off-session charges",
+		},
+		"stripeCustomerId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The Stripe customer the payment method is saved to",
+		},
+	},
+})
+
+// UpdateMembershipPaymentMethodInput represents the GraphQL input for replacing a membership's card
+var UpdateMembershipPaymentMethodInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "UpdateMembershipPaymentMethodInput",
+	Description: "Input for replacing the card a membership is billed to",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"membershipId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the membership",
+		},
+		"paymentMethodId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The Stripe payment method saved for off-session charges",
+		},
+		"stripeCustomerId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The Stripe customer the payment method is saved to",
+		},
+	},
+})
+
+// membershipQueryFields returns the membership queries
+func membershipQueryFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"clientMemberships": &graphql.Field{
+			Type:        graphql.NewList(ClientMembershipType),
+			Description: "Get a client's memberships with their billing status",
+			Args: graphql.FieldConfigArgument{
+				"clientId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the client",
+				},
+			},
+			Resolve: resolver.resolveClientMemberships,
+		},
+		"membershipCancellationQuote": &graphql.Field{
+			Type:        MembershipCancellationQuoteType,
+			Description: "Work out what cancelling a membership now would refund or credit",
+			Args: graphql.FieldConfigArgument{
+				"membershipId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the membership",
+				},
+			},
+			Resolve: resolver.resolveMembershipCancellationQuote,
+		},
+	}
+}
+
+// membershipMutationFields returns the membership mutations
+func membershipMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"createMembershipPlan": &graphql.Field{
+			Type:        MembershipPlanType,
+			Description: "Create a monthly membership plan",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(CreateMembershipPlanInput),
+					Description: "The plan",
+				},
+			},
+			Resolve: resolver.resolveCreateMembershipPlan,
+		},
+		"subscribeMembership": &graphql.Field{
+			Type: ClientMembershipType,
+			Description: "Subscribe a client to a membership plan, billing the first month at once. A declined card " +
+				"leaves the membership past due.",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(SubscribeMembershipInput),
+					Description: "The subscription",
+				},
+			},
+			Resolve: resolver.resolveSubscribeMembership,
+		},
+		"updateMembershipPaymentMethod": &graphql.Field{
+			Type: ClientMembershipType,
+			Description: "Replace the card a membership is billed to. Past due memberships are retried at once and " +
+				"suspended ones restart.",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(UpdateMembershipPaymentMethodInput),
+					Description: "The new card",
+				},
+			},
+			Resolve: resolver.resolveUpdateMembershipPaymentMethod,
+		},
+		"cancelMembership": &graphql.Field{
+			Type: ClientMembershipType,
+			Description: "Stop billing a membership. Under the plan's refund policy the unused part of the paid period " +
+				"is refunded or credited and the period ends; otherwise the client keeps access until it ends.",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the membership",
+				},
+			},
+			Resolve: resolver.resolveCancelMembership,
+		},
+		"useMembershipSession": &graphql.Field{
+			Type:        ClientMembershipType,
+			Description: "Take a session off a package membership's current period",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the membership",
+				},
+			},
+			Resolve: resolver.resolveUseMembershipSession,
+		},
+	}
+}
@@ -0,0 +1,134 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Message Template Query Resolvers
+func (r *Resolver) resolveMessageTemplate(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+
+	template, err := r.messageTemplateService.GetTemplate(p.Context, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+func (r *Resolver) resolveMessageTemplates(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+
+	templates, err := r.messageTemplateService.ListTemplates(p.Context, businessID)
+	if err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+func (r *Resolver) resolveTemplateVariables(p graphql.ResolveParams) (any, error) {
+	return domain.TemplateVariables, nil
+}
+
+func (r *Resolver) resolveLintTemplate(p graphql.ResolveParams) (any, error) {
+	if p.Args["content"] == nil {
+		return nil, errors.New("content is required")
+	}
+	var content dto.MessageTemplateContentDTO
+	if err := decodeValue(p.Args["content"], &content, "content"); err != nil {
+		return nil, err
+	}
+
+	issues, err := r.messageTemplateService.LintTemplate(p.Context, content)
+	if err != nil {
+		return nil, err
+	}
+
+	return issues, nil
+}
+
+func (r *Resolver) resolvePreviewTemplate(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+
+	previewDTO := dto.PreviewTemplateDTO{
+		BusinessID:    businessID,
+		TemplateID:    optionalString(p.Args, "templateId"),
+		ClientID:      optionalString(p.Args, "clientId"),
+		AppointmentID: optionalString(p.Args, "appointmentId"),
+	}
+	if err := decodeValue(p.Args["content"], &previewDTO.Content, "content"); err != nil {
+		return nil, err
+	}
+
+	preview, err := r.messageTemplateService.PreviewTemplate(p.Context, previewDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return preview, nil
+}
+
+// Message Template Mutation Resolvers
+func (r *Resolver) resolveCreateMessageTemplate(p graphql.ResolveParams) (any, error) {
+	createDTO := dto.CreateMessageTemplateDTO{}
+	if err := decodeInput(p.Args, &createDTO); err != nil {
+		return nil, err
+	}
+
+	template, err := r.messageTemplateService.CreateTemplate(p.Context, createDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+func (r *Resolver) resolveUpdateMessageTemplate(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+
+	updateDTO := dto.UpdateMessageTemplateDTO{}
+	if err := decodeInput(p.Args, &updateDTO); err != nil {
+		return nil, err
+	}
+
+	template, err := r.messageTemplateService.UpdateTemplate(p.Context, id, updateDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+func (r *Resolver) resolveDeleteMessageTemplate(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+
+	if err := r.messageTemplateService.DeleteTemplate(p.Context, id); err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		"success": true,
+		"message": "Message template deleted successfully",
+	}, nil
+}
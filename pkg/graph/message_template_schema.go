@@ -0,0 +1,363 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// MessageChannelEnum represents the GraphQL enum for message channels
+var MessageChannelEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "MessageChannel",
+	Description: "How a message is delivered to a client",
+	Values: graphql.EnumValueConfigMap{
+		"EMAIL": &graphql.EnumValueConfig{
+			Value:       domain.MessageChannelEmail,
+			Description: "Email",
+		},
+		"SMS": &graphql.EnumValueConfig{
+			Value:       domain.MessageChannelSMS,
+			Description: "Text message",
+		},
+	},
+})
+
+// TemplateLintSeverityEnum represents the GraphQL enum for template lint severities
+var TemplateLintSeverityEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "TemplateLintSeverity",
+	Description: "How serious a template lint issue is",
+	Values: graphql.EnumValueConfigMap{
+		"ERROR": &graphql.EnumValueConfig{
+			Value:       domain.TemplateLintError,
+			Description: "Prevents the template from being saved as active",
+		},
+		"WARNING": &graphql.EnumValueConfig{
+			Value:       domain.TemplateLintWarning,
+			Description: "Worth reviewing, but allowed",
+		},
+	},
+})
+
+// TemplateLintIssueType represents the GraphQL type for a template lint issue
+var TemplateLintIssueType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "TemplateLintIssue",
+	Description: "A problem found in a message template",
+	Fields: graphql.Fields{
+		"severity": &graphql.Field{
+			Type:        graphql.NewNonNull(TemplateLintSeverityEnum),
+			Description: "How serious the issue is",
+		},
+		"code": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "A stable code identifying the kind of issue, e.g. unknown_variable",
+		},
+		"message": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "A human readable description of the issue",
+		},
+	},
+})
+
+// SMSInfoType represents the GraphQL type for SMS length information
+var SMSInfoType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "SMSInfo",
+	Description: "How a text message is split into segments",
+	Fields: graphql.Fields{
+		"encoding": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The encoding the message is sent with, GSM-7 or UCS-2",
+		},
+		"characters": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The length of the message in encoding units",
+		},
+		"segments": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The number of segments the message is billed as",
+		},
+	},
+})
+
+// TemplateVariableType represents the GraphQL type for a template variable
+var TemplateVariableType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "TemplateVariable",
+	Description: "A placeholder that can be used in message templates as {{name}}",
+	Fields: graphql.Fields{
+		"name": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The name of the variable",
+		},
+		"description": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "What the variable is replaced with",
+		},
+		"sample": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The value used when previewing without a client or appointment",
+		},
+	},
+})
+
+// MessageTemplateType represents the GraphQL MessageTemplate type
+var MessageTemplateType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "MessageTemplate",
+	Description: "A reusable email or SMS message sent to clients",
+	Fields: withBaseFields("message template", graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"name": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The name of the template",
+		},
+		"channel": &graphql.Field{
+			Type:        graphql.NewNonNull(MessageChannelEnum),
+			Description: "How the message is delivered",
+		},
+		"purpose": &graphql.Field{
+			Type:        graphql.String,
+			Description: "What the template is used for, e.g. reminder or confirmation",
+		},
+		"subject": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The email subject",
+		},
+		"body": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The message body with {{variable}} placeholders",
+		},
+		"isActive": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether the template is used for outgoing messages",
+		},
+		"lintIssues": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(TemplateLintIssueType))),
+			Description: "Problems found in the template",
+		},
+	}),
+})
+
+// TemplatePreviewType represents the GraphQL type for a rendered template
+var TemplatePreviewType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "TemplatePreview",
+	Description: "A message template rendered for a client or appointment",
+	Fields: graphql.Fields{
+		"subject": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The rendered email subject",
+		},
+		"body": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The rendered message body",
+		},
+		"sms": &graphql.Field{
+			Type:        SMSInfoType,
+			Description: "The length of the rendered text message, for SMS templates",
+		},
+		"lintIssues": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(TemplateLintIssueType))),
+			Description: "Problems found in the template",
+		},
+	},
+})
+
+// CreateMessageTemplateInput represents the GraphQL input for creating a message template
+var CreateMessageTemplateInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "CreateMessageTemplateInput",
+	Description: "Input for creating a message template",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"businessId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"name": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The name of the template",
+		},
+		"channel": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(MessageChannelEnum),
+			Description: "How the message is delivered",
+		},
+		"purpose": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "What the template is used for",
+		},
+		"subject": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The email subject",
+		},
+		"body": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The message body with {{variable}} placeholders",
+		},
+		"isActive": &graphql.InputObjectFieldConfig{
+			Type:         graphql.Boolean,
+			Description:  "Whether the template is used for outgoing messages; rejected if the template has lint errors",
+			DefaultValue: false,
+		},
+	},
+})
+
+// UpdateMessageTemplateInput represents the GraphQL input for updating a message template
+var UpdateMessageTemplateInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "UpdateMessageTemplateInput",
+	Description: "Input for updating a message template",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"name": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The name of the template",
+		},
+		"purpose": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "What the template is used for",
+		},
+		"subject": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The email subject",
+		},
+		"body": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The message body with {{variable}} placeholders",
+		},
+		"isActive": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Boolean,
+			Description: "Whether the template is used for outgoing messages; rejected if the template has lint errors",
+		},
+		"clear": clearField("UpdateMessageTemplateInput", "purpose", "subject"),
+	},
+})
+
+// MessageTemplateContentInput represents the GraphQL input for unsaved template content
+var MessageTemplateContentInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "MessageTemplateContentInput",
+	Description: "Unsaved template content to lint or preview",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"channel": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(MessageChannelEnum),
+			Description: "How the message is delivered",
+		},
+		"subject": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The email subject",
+		},
+		"body": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The message body with {{variable}} placeholders",
+		},
+	},
+})
+
+// messageTemplateQueryFields returns the message template queries
+func messageTemplateQueryFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"messageTemplate": &graphql.Field{
+			Type:        MessageTemplateType,
+			Description: "Get a message template by ID",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the template",
+				},
+			},
+			Resolve: resolver.resolveMessageTemplate,
+		},
+		"messageTemplates": &graphql.Field{
+			Type:        graphql.NewList(MessageTemplateType),
+			Description: "Get a business's message templates",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+			},
+			Resolve: resolver.resolveMessageTemplates,
+		},
+		"templateVariables": &graphql.Field{
+			Type:        graphql.NewList(graphql.NewNonNull(TemplateVariableType)),
+			Description: "List the variables available to message templates",
+			Resolve:     resolver.resolveTemplateVariables,
+		},
+		"lintTemplate": &graphql.Field{
+			Type:        graphql.NewList(graphql.NewNonNull(TemplateLintIssueType)),
+			Description: "Check unsaved template content for unknown variables and channel length limits",
+			Args: graphql.FieldConfigArgument{
+				"content": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(MessageTemplateContentInput),
+					Description: "The template content",
+				},
+			},
+			Resolve: resolver.resolveLintTemplate,
+		},
+		"previewTemplate": &graphql.Field{
+			Type:        TemplatePreviewType,
+			Description: "Render a saved template or unsaved content for a client or appointment, using sample values for anything not given",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+				"templateId": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: "The ID of a saved template; either templateId or content is required",
+				},
+				"content": &graphql.ArgumentConfig{
+					Type:        MessageTemplateContentInput,
+					Description: "Unsaved template content; either templateId or content is required",
+				},
+				"clientId": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: "The ID of the client to render for",
+				},
+				"appointmentId": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: "The ID of the appointment to render for; also sets the client when clientId is omitted",
+				},
+			},
+			Resolve: resolver.resolvePreviewTemplate,
+		},
+	}
+}
+
+// messageTemplateMutationFields returns the message template mutations
+func messageTemplateMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"createMessageTemplate": &graphql.Field{
+			Type:        MessageTemplateType,
+			Description: "Create a message template",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(CreateMessageTemplateInput),
+					Description: "The template data",
+				},
+			},
+			Resolve: resolver.resolveCreateMessageTemplate,
+		},
+		"updateMessageTemplate": &graphql.Field{
+			Type:        MessageTemplateType,
+			Description: "Update a message template",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the template",
+				},
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(UpdateMessageTemplateInput),
+					Description: "The fields to update",
+				},
+			},
+			Resolve: resolver.resolveUpdateMessageTemplate,
+		},
+		"deleteMessageTemplate": &graphql.Field{
+			Type:        DeleteResultType,
+			Description: "Delete a message template",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the template",
+				},
+			},
+			Resolve: resolver.resolveDeleteMessageTemplate,
+		},
+	}
+}
@@ -0,0 +1,25 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Outbound Request Query Resolvers
+func (r *Resolver) resolveOutboundRequests(p graphql.ResolveParams) (any, error) {
+	filterDTO := dto.OutboundRequestFilterDTO{}
+	if limit, ok := p.Args["limit"].(int); ok {
+		filterDTO.Limit = limit
+	}
+	if err := decodeValue(p.Args["filter"], &filterDTO, "filter"); err != nil {
+		return nil, err
+	}
+
+	requests, err := r.outboundRequestService.ListOutboundRequests(p.Context, filterDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return requests, nil
+}
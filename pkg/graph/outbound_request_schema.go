@@ -0,0 +1,104 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+)
+
+// OutboundRequestType represents the GraphQL OutboundRequest type
+var OutboundRequestType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "OutboundRequest",
+	Description: "A call made to a third-party provider, with bodies scrubbed of personal data; kept for a week",
+	Fields: graphql.Fields{
+		"id": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the request",
+		},
+		"provider": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The provider called, e.g. stripe or twilio",
+		},
+		"method": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The HTTP method, or SEND for email",
+		},
+		"endpoint": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The URL called, without its query string",
+		},
+		"statusCode": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "The response status; null when no response was received",
+		},
+		"latencyMs": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "How long the call took in milliseconds",
+		},
+		"failed": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether the call failed or was answered with an error status",
+		},
+		"requestBody": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The scrubbed request body",
+		},
+		"responseBody": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The scrubbed response body",
+		},
+		"error": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Why no response was received",
+		},
+		"createdAt": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the call was made",
+		},
+	},
+})
+
+// OutboundRequestFilterInput represents the GraphQL input for browsing outbound requests
+var OutboundRequestFilterInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "OutboundRequestFilterInput",
+	Description: "Criteria for browsing the calls made to providers",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"provider": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "Only calls to this provider",
+		},
+		"failedOnly": &graphql.InputObjectFieldConfig{
+			Type:         graphql.Boolean,
+			Description:  "Only failed calls",
+			DefaultValue: false,
+		},
+		"startDate": &graphql.InputObjectFieldConfig{
+			Type:        graphql.DateTime,
+			Description: "Only calls on or after this time",
+		},
+		"endDate": &graphql.InputObjectFieldConfig{
+			Type:        graphql.DateTime,
+			Description: "Only calls before this time",
+		},
+	},
+})
+
+// outboundRequestQueryFields returns the outbound request audit queries
+func outboundRequestQueryFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"outboundRequests": &graphql.Field{
+			Type:        graphql.NewList(OutboundRequestType),
+			Description: "Browse the calls made to third-party providers, most recent first (platform admins only)",
+			Args: graphql.FieldConfigArgument{
+				"filter": &graphql.ArgumentConfig{
+					Type:        OutboundRequestFilterInput,
+					Description: "The filter criteria",
+				},
+				"limit": &graphql.ArgumentConfig{
+					Type:         graphql.Int,
+					Description:  "The maximum number of calls to return, at most 200",
+					DefaultValue: 50,
+				},
+			},
+			Resolve: resolver.resolveOutboundRequests,
+		},
+	}
+}
@@ -0,0 +1,109 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Payment Query Resolvers
+func (r *Resolver) resolveAppointmentPayments(p graphql.ResolveParams) (any, error) {
+	appointmentID, ok := p.Args["appointmentId"].(string)
+	if !ok {
+		return nil, errors.New("appointmentId is required")
+	}
+
+	payments, err := r.paymentService.ListAppointmentPayments(p.Context, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return payments, nil
+}
+
+// Payment Mutation Resolvers
+func (r *Resolver) resolveChargeDeposit(p graphql.ResolveParams) (any, error) {
+	chargeDTO := dto.ChargeDepositDTO{}
+	if err := decodeInput(p.Args, &chargeDTO); err != nil {
+		return nil, err
+	}
+
+	payment, err := r.paymentService.ChargeDeposit(p.Context, chargeDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+func (r *Resolver) resolveCaptureCompletionPayment(p graphql.ResolveParams) (any, error) {
+	captureDTO := dto.CaptureCompletionPaymentDTO{}
+	if err := decodeInput(p.Args, &captureDTO); err != nil {
+		return nil, err
+	}
+
+	payment, err := r.paymentService.CaptureCompletionPayment(p.Context, captureDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+func (r *Resolver) resolveRedeemGiftCard(p graphql.ResolveParams) (any, error) {
+	redeemDTO := dto.RedeemGiftCardDTO{}
+	if err := decodeInput(p.Args, &redeemDTO); err != nil {
+		return nil, err
+	}
+
+	payment, err := r.paymentService.RedeemGiftCard(p.Context, redeemDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+func (r *Resolver) resolveRefundPayment(p graphql.ResolveParams) (any, error) {
+	refundDTO := dto.RefundPaymentDTO{}
+	if err := decodeInput(p.Args, &refundDTO); err != nil {
+		return nil, err
+	}
+
+	payment, err := r.paymentService.RefundPayment(p.Context, refundDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+func (r *Resolver) resolveRefreshPayment(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+
+	payment, err := r.paymentService.RefreshPayment(p.Context, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+func (r *Resolver) resolveSetServiceDeposit(p graphql.ResolveParams) (any, error) {
+	depositDTO := dto.ServiceDepositDTO{}
+	if err := decodeInput(p.Args, &depositDTO); err != nil {
+		return nil, err
+	}
+
+	deposit, err := r.paymentService.SetServiceDeposit(p.Context, depositDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return deposit, nil
+}
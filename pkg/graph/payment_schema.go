@@ -0,0 +1,332 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// PaymentKindEnum represents the GraphQL enum for what a payment is for
+var PaymentKindEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "PaymentKind",
+	Description: "What a card payment is for",
+	Values: graphql.EnumValueConfigMap{
+		"DEPOSIT": &graphql.EnumValueConfig{
+			Value:       domain.PaymentKindDeposit,
+			Description: "Taken at booking",
+		},
+		"BALANCE": &graphql.EnumValueConfig{
+			Value:       domain.PaymentKindBalance,
+			Description: "Taken at completion, less the deposits",
+		},
+	},
+})
+
+// PaymentStatusEnum represents the GraphQL enum for payment statuses
+var PaymentStatusEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "PaymentStatus",
+	Description: "The status of a card payment",
+	Values: graphql.EnumValueConfigMap{
+		"PENDING": &graphql.EnumValueConfig{
+			Value:       domain.PaymentStatusPending,
+			Description: "The card network has not confirmed the payment yet",
+		},
+		"REQUIRES_ACTION": &graphql.EnumValueConfig{
+			Value:       domain.PaymentStatusRequiresAction,
+			Description: "The client has to authenticate the card with the client secret",
+		},
+		"SUCCEEDED": &graphql.EnumValueConfig{
+			Value:       domain.PaymentStatusSucceeded,
+			Description: "The money was taken",
+		},
+		"FAILED": &graphql.EnumValueConfig{
+			Value:       domain.PaymentStatusFailed,
+			Description: "The card was declined or the payment was abandoned",
+		},
+		"PARTIALLY_REFUNDED": &graphql.EnumValueConfig{
+			Value:       domain.PaymentStatusPartiallyRefunded,
+			Description: "Some of the money was refunded",
+		},
+		"REFUNDED": &graphql.EnumValueConfig{
+			Value:       domain.PaymentStatusRefunded,
+			Description: "All of the money was refunded",
+		},
+	},
+})
+
+// PaymentType represents the GraphQL Payment type
+var PaymentType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Payment",
+	Description: "A payment for an appointment, taken by card through Stripe or redeemed from a gift card",
+	Fields: withBaseFields("payment", graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"appointmentId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the appointment",
+		},
+		"serviceCompletionId": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The ID of the service completion a balance payment is for",
+		},
+		"kind": &graphql.Field{
+			Type:        graphql.NewNonNull(PaymentKindEnum),
+			Description: "What the payment is for",
+		},
+		"status": &graphql.Field{
+			Type:        graphql.NewNonNull(PaymentStatusEnum),
+			Description: "The status of the payment",
+		},
+		"amount": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The amount charged",
+		},
+		"refundedAmount": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The amount refunded so far",
+		},
+		"currency": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The currency code",
+		},
+		"paymentIntentId": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The ID of the Stripe PaymentIntent",
+		},
+		"giftCardId": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The ID of the gift card the payment was redeemed from",
+		},
+		"clientSecret": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Passed to Stripe.js to authenticate the card; only set while the status is REQUIRES_ACTION",
+		},
+		"failureReason": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Why the payment failed",
+		},
+		"paidAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the money was taken",
+		},
+	}),
+})
+
+// ServiceDepositType represents the GraphQL ServiceDeposit type
+var ServiceDepositType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ServiceDeposit",
+	Description: "The deposit charged at booking for a service",
+	Fields: graphql.Fields{
+		"serviceId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the service",
+		},
+		"requiresDeposit": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether bookings of the service take a deposit",
+		},
+		"depositAmount": &graphql.Field{
+			Type:        DecimalScalar,
+			Description: "The deposit",
+		},
+	},
+})
+
+// ChargeDepositInput represents the GraphQL input for charging a deposit
+var ChargeDepositInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "ChargeDepositInput",
+	Description: "Input for charging the deposit of a booked appointment to a card",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"appointmentId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the appointment",
+		},
+		"paymentMethodId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The Stripe PaymentMethod collected by the client app",
+		},
+		"amount": &graphql.InputObjectFieldConfig{
+			Type:        DecimalScalar,
+			Description: "The deposit; defaults to the deposits of the booked services",
+		},
+	},
+})
+
+// CaptureCompletionPaymentInput represents the GraphQL input for taking payment at completion
+var CaptureCompletionPaymentInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "CaptureCompletionPaymentInput",
+	Description: "Input for completing an appointment and charging what the deposits did not cover",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"appointmentId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the appointment",
+		},
+		"paymentMethodId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The Stripe PaymentMethod collected by the client app",
+		},
+		"priceCharged": &graphql.InputObjectFieldConfig{
+			Type:        DecimalScalar,
+			Description: "The full price of the appointment; required unless its completion was recorded already",
+		},
+		"actualDuration": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Int,
+			Description: "How long the appointment took in minutes",
+		},
+	},
+})
+
+// RedeemGiftCardInput represents the GraphQL input for paying with a gift card
+var RedeemGiftCardInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "RedeemGiftCardInput",
+	Description: "Input for paying for a completed appointment, in full or in part, with a gift card",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"appointmentId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the appointment",
+		},
+		"code": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The code of the gift card, with or without dashes",
+		},
+		"amount": &graphql.InputObjectFieldConfig{
+			Type:        DecimalScalar,
+			Description: "The amount to take off the card; defaults to as much of the balance due as the card covers",
+		},
+		"priceCharged": &graphql.InputObjectFieldConfig{
+			Type:        DecimalScalar,
+			Description: "The full price of the appointment; required unless its completion was recorded already",
+		},
+		"actualDuration": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Int,
+			Description: "How long the appointment took in minutes",
+		},
+	},
+})
+
+// RefundPaymentInput represents the GraphQL input for refunding a payment
+var RefundPaymentInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "RefundPaymentInput",
+	Description: "Input for refunding a payment to the card it was taken from, or back onto the gift card it was redeemed from",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"paymentId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the payment",
+		},
+		"amount": &graphql.InputObjectFieldConfig{
+			Type:        DecimalScalar,
+			Description: "The amount to refund; defaults to everything not refunded yet",
+		},
+	},
+})
+
+// SetServiceDepositInput represents the GraphQL input for setting the deposit of a service
+var SetServiceDepositInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "SetServiceDepositInput",
+	Description: "Input for setting the deposit charged at booking for a service",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"serviceId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the service",
+		},
+		"requiresDeposit": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether bookings of the service take a deposit",
+		},
+		"depositAmount": &graphql.InputObjectFieldConfig{
+			Type:        DecimalScalar,
+			Description: "The deposit; required when a deposit is taken",
+		},
+	},
+})
+
+// paymentQueryFields returns the payment queries
+func paymentQueryFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"appointmentPayments": &graphql.Field{
+			Type:        graphql.NewList(PaymentType),
+			Description: "Get the card payments of an appointment, oldest first",
+			Args: graphql.FieldConfigArgument{
+				"appointmentId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the appointment",
+				},
+			},
+			Resolve: resolver.resolveAppointmentPayments,
+		},
+	}
+}
+
+// paymentMutationFields returns the payment mutations
+func paymentMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"chargeDeposit": &graphql.Field{
+			Type:        PaymentType,
+			Description: "Charge the deposit of a booked appointment to a card",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(ChargeDepositInput),
+					Description: "The deposit",
+				},
+			},
+			Resolve: resolver.resolveChargeDeposit,
+		},
+		"captureCompletionPayment": &graphql.Field{
+			Type:        PaymentType,
+			Description: "Complete an appointment and charge the price less the deposits to a card",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(CaptureCompletionPaymentInput),
+					Description: "The completion payment",
+				},
+			},
+			Resolve: resolver.resolveCaptureCompletionPayment,
+		},
+		"redeemGiftCard": &graphql.Field{
+			Type:        PaymentType,
+			Description: "Complete an appointment if needed and pay for it, in full or in part, from a gift card's balance",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(RedeemGiftCardInput),
+					Description: "The redemption",
+				},
+			},
+			Resolve: resolver.resolveRedeemGiftCard,
+		},
+		"refundPayment": &graphql.Field{
+			Type:        PaymentType,
+			Description: "Refund all or part of a payment",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(RefundPaymentInput),
+					Description: "The refund",
+				},
+			},
+			Resolve: resolver.resolveRefundPayment,
+		},
+		"refreshPayment": &graphql.Field{
+			Type:        PaymentType,
+			Description: "Update a pending payment with its current state at Stripe, e.g. after the client authenticated the card",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the payment",
+				},
+			},
+			Resolve: resolver.resolveRefreshPayment,
+		},
+		"setServiceDeposit": &graphql.Field{
+			Type:        ServiceDepositType,
+			Description: "Set the deposit charged at booking for a service",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(SetServiceDepositInput),
+					Description: "The deposit",
+				},
+			},
+			Resolve: resolver.resolveSetServiceDeposit,
+		},
+	}
+}
@@ -0,0 +1,43 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Permission Query Resolvers
+func (r *Resolver) resolvePermissionCatalog(p graphql.ResolveParams) (any, error) {
+	return r.permissionService.GetPermissionCatalog(p.Context), nil
+}
+
+func (r *Resolver) resolveStaffPermissions(p graphql.ResolveParams) (any, error) {
+	staffID, ok := p.Args["staffId"].(string)
+	if !ok {
+		return nil, errors.New("staffId is required")
+	}
+
+	permissions, err := r.permissionService.GetStaffPermissions(p.Context, staffID)
+	if err != nil {
+		return nil, err
+	}
+
+	return permissions, nil
+}
+
+// Permission Mutation Resolvers
+func (r *Resolver) resolveUpdateStaffPermissions(p graphql.ResolveParams) (any, error) {
+	var updateDTO dto.UpdateStaffPermissionsDTO
+	if err := decodeInput(p.Args, &updateDTO); err != nil {
+		return nil, err
+	}
+
+	permissions, err := r.permissionService.UpdateStaffPermissions(p.Context, updateDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return permissions, nil
+}
@@ -0,0 +1,161 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+)
+
+// PermissionDefinitionType represents the GraphQL PermissionDefinition type
+var PermissionDefinitionType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "PermissionDefinition",
+	Description: "Something a member of staff may be allowed to do in a business",
+	Fields: graphql.Fields{
+		"permission": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The permission, as resource:action, e.g. appointments:cancel",
+		},
+		"resource": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The resource the permission is about, e.g. appointments",
+		},
+		"action": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "What the permission allows doing to the resource, e.g. cancel",
+		},
+		"description": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "What the permission allows, for people",
+		},
+	},
+})
+
+// RolePresetType represents the GraphQL RolePreset type
+var RolePresetType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "RolePreset",
+	Description: "The permissions a role comes with",
+	Fields: graphql.Fields{
+		"role": &graphql.Field{
+			Type:        graphql.NewNonNull(BusinessRoleEnum),
+			Description: "The role",
+		},
+		"permissions": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+			Description: "The permissions staff with the role have unless changed",
+		},
+	},
+})
+
+// PermissionCatalogType represents the GraphQL PermissionCatalog type
+var PermissionCatalogType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "PermissionCatalog",
+	Description: "Every permission staff may be given, and what each role comes with",
+	Fields: graphql.Fields{
+		"permissions": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(PermissionDefinitionType))),
+			Description: "The permissions, grouped by resource",
+		},
+		"presets": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(RolePresetType))),
+			Description: "The permissions of each role",
+		},
+	},
+})
+
+// StaffPermissionsType represents the GraphQL StaffPermissions type
+var StaffPermissionsType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "StaffPermissions",
+	Description: "What a member of staff may do in a business",
+	Fields: graphql.Fields{
+		"staffId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the staff member",
+		},
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"role": &graphql.Field{
+			Type:        graphql.NewNonNull(BusinessRoleEnum),
+			Description: "The role of the staff member, whose preset the permissions start from",
+		},
+		"grants": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+			Description: "Permissions given beyond the role's",
+		},
+		"revokes": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+			Description: "Permissions of the role taken away",
+		},
+		"effective": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+			Description: "Everything the staff member may do",
+		},
+		"saved": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "False when the permissions are only a preview of a dry run",
+		},
+	},
+})
+
+// UpdateStaffPermissionsInput represents the GraphQL input for changing staff permissions
+var UpdateStaffPermissionsInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "UpdateStaffPermissionsInput",
+	Description: "The permissions a member of staff is given or denied beyond the preset of their role, replacing the current ones",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"staffId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the staff member",
+		},
+		"grants": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+			Description: "Permissions to give beyond the role's",
+		},
+		"revokes": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+			Description: "Permissions of the role to take away",
+		},
+		"dryRun": &graphql.InputObjectFieldConfig{
+			Type:         graphql.Boolean,
+			DefaultValue: false,
+			Description:  "Only validate the change and return the resulting permissions, without saving them",
+		},
+	},
+})
+
+// permissionQueryFields returns the permission queries
+func permissionQueryFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"permissionCatalog": &graphql.Field{
+			Type:        graphql.NewNonNull(PermissionCatalogType),
+			Description: "List every permission staff may be given, and what each role comes with",
+			Resolve:     resolver.resolvePermissionCatalog,
+		},
+		"staffPermissions": &graphql.Field{
+			Type:        StaffPermissionsType,
+			Description: "Get what a member of staff may do (the member themselves, or staff who may change permissions)",
+			Args: graphql.FieldConfigArgument{
+				"staffId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the staff member",
+				},
+			},
+			Resolve: resolver.resolveStaffPermissions,
+		},
+	}
+}
+
+// permissionMutationFields returns the permission mutations
+func permissionMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"updateStaffPermissions": &graphql.Field{
+			Type:        StaffPermissionsType,
+			Description: "Change what a member of staff may do (staff with the staff:permissions permission)",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(UpdateStaffPermissionsInput),
+					Description: "The permissions to give and take away",
+				},
+			},
+			Resolve: resolver.resolveUpdateStaffPermissions,
+		},
+	}
+}
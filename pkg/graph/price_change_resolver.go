@@ -0,0 +1,88 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Price Change Query Resolvers
+func (r *Resolver) resolvePreviewPriceChange(p graphql.ResolveParams) (any, error) {
+	changeDTO, err := bulkPriceChangeFromArgs(p.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	previews, err := r.priceChangeService.PreviewPriceChange(p.Context, changeDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return previews, nil
+}
+
+func (r *Resolver) resolvePriceChanges(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+
+	changes, err := r.priceChangeService.ListPriceChanges(p.Context, businessID)
+	if err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+func (r *Resolver) resolveServicePriceHistory(p graphql.ResolveParams) (any, error) {
+	serviceID, ok := p.Args["serviceId"].(string)
+	if !ok {
+		return nil, errors.New("serviceId is required")
+	}
+
+	history, err := r.priceChangeService.GetPriceHistory(p.Context, serviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// Price Change Mutation Resolvers
+func (r *Resolver) resolveSchedulePriceChange(p graphql.ResolveParams) (any, error) {
+	changeDTO, err := bulkPriceChangeFromArgs(p.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	change, err := r.priceChangeService.SchedulePriceChange(p.Context, changeDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return change, nil
+}
+
+func (r *Resolver) resolveCancelPriceChange(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+
+	change, err := r.priceChangeService.CancelPriceChange(p.Context, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return change, nil
+}
+
+// bulkPriceChangeFromArgs converts the BulkPriceChangeInput argument to a DTO
+func bulkPriceChangeFromArgs(args map[string]any) (dto.BulkPriceChangeDTO, error) {
+	var changeDTO dto.BulkPriceChangeDTO
+	err := decodeInput(args, &changeDTO)
+	return changeDTO, err
+}
@@ -0,0 +1,262 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// PriceAdjustmentTypeEnum represents the GraphQL enum for price adjustment types
+var PriceAdjustmentTypeEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "PriceAdjustmentType",
+	Description: "How a bulk price change modifies prices",
+	Values: graphql.EnumValueConfigMap{
+		"PERCENTAGE": &graphql.EnumValueConfig{
+			Value:       domain.PriceAdjustmentPercentage,
+			Description: "The amount is a percentage of the current price",
+		},
+		"FIXED": &graphql.EnumValueConfig{
+			Value:       domain.PriceAdjustmentFixed,
+			Description: "The amount is added to the current price",
+		},
+	},
+})
+
+// PriceChangeStatusEnum represents the GraphQL enum for price change statuses
+var PriceChangeStatusEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "PriceChangeStatus",
+	Description: "The lifecycle of a bulk price change",
+	Values: graphql.EnumValueConfigMap{
+		"SCHEDULED": &graphql.EnumValueConfig{
+			Value:       domain.PriceChangeScheduled,
+			Description: "Waiting for its effective date",
+		},
+		"APPLIED": &graphql.EnumValueConfig{
+			Value:       domain.PriceChangeApplied,
+			Description: "The new prices are in effect",
+		},
+		"CANCELLED": &graphql.EnumValueConfig{
+			Value:       domain.PriceChangeCancelled,
+			Description: "Cancelled before its effective date",
+		},
+		"FAILED": &graphql.EnumValueConfig{
+			Value:       domain.PriceChangeFailed,
+			Description: "Not applied because a price would have become negative",
+		},
+	},
+})
+
+// PricePreviewType represents the GraphQL PricePreview type
+var PricePreviewType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "PricePreview",
+	Description: "A service's price before and after a price change",
+	Fields: graphql.Fields{
+		"serviceId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the service",
+		},
+		"serviceName": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The name of the service",
+		},
+		"oldPrice": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The current price",
+		},
+		"newPrice": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The price after the change",
+		},
+	},
+})
+
+// PriceChangeType represents the GraphQL PriceChange type
+var PriceChangeType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "PriceChange",
+	Description: "A bulk price change of selected services and categories",
+	Fields: withBaseFields("price change", graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"adjustmentType": &graphql.Field{
+			Type:        graphql.NewNonNull(PriceAdjustmentTypeEnum),
+			Description: "How prices are modified",
+		},
+		"amount": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The percentage or amount of the change; negative values lower prices",
+		},
+		"serviceIds": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+			Description: "The selected services",
+		},
+		"categoryIds": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+			Description: "The selected categories, whose services are all changed",
+		},
+		"effectiveAt": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the new prices take effect",
+		},
+		"status": &graphql.Field{
+			Type:        graphql.NewNonNull(PriceChangeStatusEnum),
+			Description: "The status of the change",
+		},
+		"appliedAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the change was applied",
+		},
+		"failureReason": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Why the change could not be applied",
+		},
+		"notes": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Notes about the change",
+		},
+		"preview": &graphql.Field{
+			Type:        graphql.NewList(graphql.NewNonNull(PricePreviewType)),
+			Description: "The prices before and after the change, when it was scheduled",
+		},
+	}),
+})
+
+// PriceHistoryType represents the GraphQL PriceHistory type
+var PriceHistoryType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "PriceHistory",
+	Description: "A change of a service's price",
+	Fields: graphql.Fields{
+		"id": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The unique identifier of the record",
+		},
+		"serviceId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the service",
+		},
+		"priceChangeId": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The bulk price change that set the price",
+		},
+		"oldPrice": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The price before the change",
+		},
+		"newPrice": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The price after the change",
+		},
+		"changedAt": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the price changed",
+		},
+		"changedBy": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The user who made the change",
+		},
+	},
+})
+
+// BulkPriceChangeInput represents the GraphQL input for a bulk price change
+var BulkPriceChangeInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "BulkPriceChangeInput",
+	Description: "Input for changing the prices of selected services and categories",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"businessId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"adjustmentType": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(PriceAdjustmentTypeEnum),
+			Description: "How prices are modified",
+		},
+		"amount": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The percentage or amount of the change; negative values lower prices",
+		},
+		"serviceIds": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+			Description: "The services to change",
+		},
+		"categoryIds": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+			Description: "The categories whose services to change",
+		},
+		"effectiveAt": &graphql.InputObjectFieldConfig{
+			Type:        graphql.DateTime,
+			Description: "When the new prices take effect; immediately when omitted",
+		},
+		"notes": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "Notes about the change",
+		},
+	},
+})
+
+// priceChangeQueryFields returns the bulk price change queries
+func priceChangeQueryFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"previewPriceChange": &graphql.Field{
+			Type:        graphql.NewList(PricePreviewType),
+			Description: "Preview the prices of the selected services after a bulk price change",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(BulkPriceChangeInput),
+					Description: "The price change",
+				},
+			},
+			Resolve: resolver.resolvePreviewPriceChange,
+		},
+		"priceChanges": &graphql.Field{
+			Type:        graphql.NewList(PriceChangeType),
+			Description: "Get the bulk price changes of a business, latest effective date first",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+			},
+			Resolve: resolver.resolvePriceChanges,
+		},
+		"servicePriceHistory": &graphql.Field{
+			Type:        graphql.NewList(PriceHistoryType),
+			Description: "Get the price changes of a service, most recent first",
+			Args: graphql.FieldConfigArgument{
+				"serviceId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the service",
+				},
+			},
+			Resolve: resolver.resolveServicePriceHistory,
+		},
+	}
+}
+
+// priceChangeMutationFields returns the bulk price change mutations
+func priceChangeMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"schedulePriceChange": &graphql.Field{
+			Type:        PriceChangeType,
+			Description: "Change the prices of selected services and categories, now or on a future date",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(BulkPriceChangeInput),
+					Description: "The price change",
+				},
+			},
+			Resolve: resolver.resolveSchedulePriceChange,
+		},
+		"cancelPriceChange": &graphql.Field{
+			Type:        PriceChangeType,
+			Description: "Cancel a price change that has not been applied yet",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the price change",
+				},
+			},
+			Resolve: resolver.resolveCancelPriceChange,
+		},
+	}
+}
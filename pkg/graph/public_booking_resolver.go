@@ -0,0 +1,87 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/service"
+)
+
+// PublicResolver resolves the operations of the public booking schema
+type PublicResolver struct {
+	bookingService service.PublicBookingService
+}
+
+// NewPublicResolver creates a new public booking resolver
+func NewPublicResolver(bookingService service.PublicBookingService) *PublicResolver {
+	return &PublicResolver{bookingService: bookingService}
+}
+
+// Public Booking Query Resolvers
+func (r *PublicResolver) resolveServices(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+
+	services, err := r.bookingService.ListServices(p.Context, businessID)
+	if err != nil {
+		return nil, err
+	}
+
+	return services, nil
+}
+
+func (r *PublicResolver) resolveAvailableSlots(p graphql.ResolveParams) (any, error) {
+	query := dto.PublicSlotsQueryDTO{
+		StaffID:  optionalString(p.Args, "staffId"),
+		PageSlug: optionalString(p.Args, "pageSlug"),
+	}
+	if businessID, ok := p.Args["businessId"].(string); ok {
+		query.BusinessID = businessID
+	}
+	if serviceID, ok := p.Args["serviceId"].(string); ok {
+		query.ServiceID = serviceID
+	}
+	if date, ok := p.Args["date"].(string); ok {
+		query.Date = date
+	}
+
+	slots, err := r.bookingService.GetAvailableSlots(p.Context, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return slots, nil
+}
+
+func (r *PublicResolver) resolveStaffPage(p graphql.ResolveParams) (any, error) {
+	slug, ok := p.Args["slug"].(string)
+	if !ok {
+		return nil, errors.New("slug is required")
+	}
+
+	page, err := r.bookingService.GetStaffPage(p.Context, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// Public Booking Mutation Resolvers
+func (r *PublicResolver) resolveRequestBooking(p graphql.ResolveParams) (any, error) {
+	request := dto.PublicBookingRequestDTO{}
+	if err := decodeInput(p.Args, &request); err != nil {
+		return nil, err
+	}
+
+	result, err := r.bookingService.RequestBooking(p.Context, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,218 @@
+package graph
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// mockPublicBookingService records the requests it receives
+type mockPublicBookingService struct {
+	slotsQuery dto.PublicSlotsQueryDTO
+	request    dto.PublicBookingRequestDTO
+}
+
+func (m *mockPublicBookingService) ListServices(ctx context.Context, businessID string) ([]*dto.PublicServiceDTO, error) {
+	return []*dto.PublicServiceDTO{{ID: "service-1", Name: "Haircut", Duration: 30, Price: decimal.NewFromInt(25)}}, nil
+}
+
+func (m *mockPublicBookingService) GetServiceMenu(ctx context.Context, businessID string) (*dto.PublicServiceMenuDTO, error) {
+	services, _ := m.ListServices(ctx, businessID)
+	return &dto.PublicServiceMenuDTO{
+		BusinessID:   businessID,
+		BusinessName: "Salão Rita",
+		Currency:     "EUR",
+		Services:     services,
+		UpdatedAt:    time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC),
+	}, nil
+}
+
+func (m *mockPublicBookingService) GetAvailableSlots(ctx context.Context, query dto.PublicSlotsQueryDTO) ([]*dto.PublicSlotDTO, error) {
+	m.slotsQuery = query
+	start := time.Date(2024, 6, 3, 9, 0, 0, 0, time.UTC)
+	return []*dto.PublicSlotDTO{{StaffID: "staff-1", StaffName: "Rita", StartTime: start, EndTime: start.Add(30 * time.Minute)}}, nil
+}
+
+func (m *mockPublicBookingService) GetStaffPage(ctx context.Context, slug string) (*dto.PublicStaffPageDTO, error) {
+	return &dto.PublicStaffPageDTO{
+		BusinessID:    "business-1",
+		BusinessName:  "Salão Rita",
+		StaffID:       "staff-1",
+		StaffName:     "Rita",
+		Slug:          slug,
+		PortfolioURLs: []string{"https://example.com/rita.jpg"},
+		Services:      []*dto.PublicServiceDTO{{ID: "service-1", Name: "Haircut", Duration: 30, Price: decimal.NewFromInt(25)}},
+	}, nil
+}
+
+func (m *mockPublicBookingService) RequestBooking(ctx context.Context, request dto.PublicBookingRequestDTO) (*dto.PublicBookingResultDTO, error) {
+	m.request = request
+	return &dto.PublicBookingResultDTO{
+		Booked:  true,
+		Outcome: domain.BookingAllowed,
+		Appointment: &dto.PublicAppointmentDTO{
+			ID:        "appointment-1",
+			ServiceID: request.ServiceID,
+			StaffID:   request.StaffID,
+			StartTime: request.StartTime,
+			EndTime:   request.StartTime.Add(30 * time.Minute),
+			Status:    domain.AppointmentStatusPending,
+		},
+	}, nil
+}
+
+func TestPublicSchema(t *testing.T) {
+	bookingService := &mockPublicBookingService{}
+	schema, err := CreatePublicSchema(NewPublicResolver(bookingService))
+	require.NoError(t, err)
+
+	t.Run("available slots", func(t *testing.T) {
+		result := graphql.Do(graphql.Params{
+			Schema: schema,
+			RequestString: `{
+				availableSlots(businessId: "business-1", serviceId: "service-1", date: "2024-06-03") {
+					staffId
+					staffName
+					startTime
+				}
+			}`,
+		})
+		require.Empty(t, result.Errors)
+		assert.Equal(t, "2024-06-03", bookingService.slotsQuery.Date)
+		assert.Nil(t, bookingService.slotsQuery.StaffID)
+
+		slots := result.Data.(map[string]any)["availableSlots"].([]any)
+		require.Len(t, slots, 1)
+		assert.Equal(t, "Rita", slots[0].(map[string]any)["staffName"])
+	})
+
+	t.Run("staff page", func(t *testing.T) {
+		result := graphql.Do(graphql.Params{
+			Schema: schema,
+			RequestString: `{
+				staffPage(slug: "rita-sousa") {
+					staffName
+					slug
+					portfolioUrls
+					services { id name }
+				}
+			}`,
+		})
+		require.Empty(t, result.Errors)
+
+		page := result.Data.(map[string]any)["staffPage"].(map[string]any)
+		assert.Equal(t, "rita-sousa", page["slug"])
+		assert.Equal(t, "Rita", page["staffName"])
+		assert.Len(t, page["services"], 1)
+	})
+
+	t.Run("request booking from a staff page", func(t *testing.T) {
+		result := graphql.Do(graphql.Params{
+			Schema: schema,
+			RequestString: `mutation {
+				requestBooking(input: {
+					businessId: "business-1", serviceId: "service-1", pageSlug: "rita-sousa",
+					startTime: "2024-06-03T09:00:00Z", firstName: "Ana", lastName: "Silva",
+					email: "ana@example.com"
+				}) { booked }
+			}`,
+		})
+		require.Empty(t, result.Errors)
+		require.NotNil(t, bookingService.request.PageSlug)
+		assert.Equal(t, "rita-sousa", *bookingService.request.PageSlug)
+		assert.Empty(t, bookingService.request.StaffID)
+	})
+
+	t.Run("request booking", func(t *testing.T) {
+		result := graphql.Do(graphql.Params{
+			Schema: schema,
+			RequestString: `mutation {
+				requestBooking(input: {
+					businessId: "business-1", serviceId: "service-1", staffId: "staff-1",
+					startTime: "2024-06-03T09:00:00Z", firstName: "Ana", lastName: "Silva",
+					email: "ana@example.com", phone: "+351912345678"
+				}) {
+					booked
+					outcome
+					appointment { id status }
+				}
+			}`,
+		})
+		require.Empty(t, result.Errors)
+		assert.Equal(t, "ana@example.com", bookingService.request.Email)
+		require.NotNil(t, bookingService.request.Phone)
+		assert.Equal(t, "+351912345678", *bookingService.request.Phone)
+		assert.True(t, bookingService.request.StartTime.Equal(time.Date(2024, 6, 3, 9, 0, 0, 0, time.UTC)))
+
+		booking := result.Data.(map[string]any)["requestBooking"].(map[string]any)
+		assert.Equal(t, true, booking["booked"])
+		assert.Equal(t, "ALLOWED", booking["outcome"])
+		assert.Equal(t, "PENDING", booking["appointment"].(map[string]any)["status"])
+	})
+
+	t.Run("internal operations are not exposed", func(t *testing.T) {
+		result := graphql.Do(graphql.Params{
+			Schema:        schema,
+			RequestString: `{ user(id: "user-1") { id } }`,
+		})
+		require.Len(t, result.Errors, 1)
+		assert.Contains(t, result.Errors[0].Message, `Cannot query field "user"`)
+	})
+}
+
+func TestServiceMenuHandler(t *testing.T) {
+	handler := ServiceMenuHandler(&mockPublicBookingService{})
+	get := func(header, value string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, PublicServiceMenuPath+"business-1", nil)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	first := get("", "")
+	require.Equal(t, http.StatusOK, first.Code)
+	etag := first.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+	assert.Equal(t, "Sat, 01 Jun 2024 12:00:00 GMT", first.Header().Get("Last-Modified"))
+	assert.Contains(t, first.Body.String(), `"business_id":"business-1"`)
+
+	t.Run("unchanged etag", func(t *testing.T) {
+		rec := get("If-None-Match", `"other", W/`+etag)
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+		assert.Empty(t, rec.Body.String())
+		assert.Equal(t, etag, rec.Header().Get("ETag"))
+	})
+
+	t.Run("changed etag wins over date", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, PublicServiceMenuPath+"business-1", nil)
+		req.Header.Set("If-None-Match", `"stale"`)
+		req.Header.Set("If-Modified-Since", "Sat, 01 Jun 2024 12:00:00 GMT")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("not modified since", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotModified, get("If-Modified-Since", "Sat, 01 Jun 2024 12:00:00 GMT").Code)
+		assert.Equal(t, http.StatusOK, get("If-Modified-Since", "Sat, 01 Jun 2024 11:59:59 GMT").Code)
+	})
+
+	t.Run("only reads", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, PublicServiceMenuPath+"business-1", nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+}
@@ -0,0 +1,351 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// PublicGraphQLPath is the path of the public booking GraphQL endpoint
+const PublicGraphQLPath = "/public/graphql"
+
+// BookingOutcomeEnum represents the GraphQL enum for the outcomes of online booking attempts
+var BookingOutcomeEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "BookingOutcome",
+	Description: "Whether an online booking attempt may proceed",
+	Values: graphql.EnumValueConfigMap{
+		"ALLOWED": &graphql.EnumValueConfig{
+			Value:       domain.BookingAllowed,
+			Description: "The booking may proceed",
+		},
+		"BLOCKLISTED": &graphql.EnumValueConfig{
+			Value:       domain.BookingBlocklisted,
+			Description: "The business does not take online bookings from the booker",
+		},
+		"CAPTCHA_REQUIRED": &graphql.EnumValueConfig{
+			Value:       domain.BookingCaptchaRequired,
+			Description: "The booker must solve a CAPTCHA and try again",
+		},
+		"RATE_LIMITED": &graphql.EnumValueConfig{
+			Value:       domain.BookingRateLimited,
+			Description: "Too many attempts were made from the booker's address",
+		},
+	},
+})
+
+// OnlineBookingModeEnum represents the GraphQL enum for how services are offered online
+var OnlineBookingModeEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "OnlineBookingMode",
+	Description: "How a service is offered to online bookers",
+	Values: graphql.EnumValueConfigMap{
+		"BOOKABLE": &graphql.EnumValueConfig{
+			Value:       domain.OnlineBookingBookable,
+			Description: "Listed and bookable online",
+		},
+		"CALL_TO_BOOK": &graphql.EnumValueConfig{
+			Value:       domain.OnlineBookingCallToBook,
+			Description: "Listed, but booked by calling the business",
+		},
+		"HIDDEN": &graphql.EnumValueConfig{
+			Value:       domain.OnlineBookingHidden,
+			Description: "Not offered online",
+		},
+	},
+})
+
+// BookableServiceType represents the GraphQL BookableService type
+var BookableServiceType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "BookableService",
+	Description: "A service that can be booked online",
+	Fields: graphql.Fields{
+		"id": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the service",
+		},
+		"name": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The name of the service",
+		},
+		"description": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The description of the service",
+		},
+		"duration": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "How long the service takes, in minutes",
+		},
+		"price": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The price of the service",
+		},
+		"requiresDeposit": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether a deposit must be paid to book the service",
+		},
+		"depositAmount": &graphql.Field{
+			Type:        DecimalScalar,
+			Description: "The deposit to pay, when one is required",
+		},
+		"onlineBooking": &graphql.Field{
+			Type:        graphql.NewNonNull(OnlineBookingModeEnum),
+			Description: "Whether the service can be booked online, or by calling the business",
+		},
+		"returningClientsOnly": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether only clients who have had an appointment, such as a consultation, can book the service online",
+		},
+	},
+})
+
+// BookingSlotType represents the GraphQL BookingSlot type
+var BookingSlotType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "BookingSlot",
+	Description: "A time at which a staff member can take a service",
+	Fields: graphql.Fields{
+		"staffId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the staff member",
+		},
+		"staffName": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The first name of the staff member",
+		},
+		"startTime": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the appointment would start",
+		},
+		"endTime": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the appointment would end",
+		},
+	},
+})
+
+// BookedAppointmentType represents the GraphQL BookedAppointment type
+var BookedAppointmentType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "BookedAppointment",
+	Description: "An appointment requested online, pending acceptance by the business",
+	Fields: graphql.Fields{
+		"id": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the appointment",
+		},
+		"serviceId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the booked service",
+		},
+		"staffId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the staff member",
+		},
+		"startTime": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the appointment starts",
+		},
+		"endTime": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the appointment ends",
+		},
+		"status": &graphql.Field{
+			Type:        graphql.NewNonNull(AppointmentStatusEnum),
+			Description: "The status of the appointment",
+		},
+	},
+})
+
+// BookingResultType represents the GraphQL BookingResult type
+var BookingResultType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "BookingResult",
+	Description: "The outcome of an online booking",
+	Fields: graphql.Fields{
+		"booked": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether the appointment was requested",
+		},
+		"outcome": &graphql.Field{
+			Type:        graphql.NewNonNull(BookingOutcomeEnum),
+			Description: "Whether the attempt was allowed to proceed",
+		},
+		"message": &graphql.Field{
+			Type:        graphql.String,
+			Description: "What the booker must do when the attempt was refused",
+		},
+		"appointment": &graphql.Field{
+			Type:        BookedAppointmentType,
+			Description: "The requested appointment, when booked",
+		},
+	},
+})
+
+// StaffPageType represents the GraphQL StaffPage type
+var StaffPageType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "StaffPage",
+	Description: "A staff member's personal booking page",
+	Fields: graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"businessName": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The name of the business",
+		},
+		"staffId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the staff member",
+		},
+		"staffName": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The first name of the staff member",
+		},
+		"slug": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The slug identifying the page in its link",
+		},
+		"bio": &graphql.Field{
+			Type:        graphql.String,
+			Description: "What the staff member tells clients about themselves",
+		},
+		"portfolioUrls": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+			Description: "The images of the staff member's work",
+		},
+		"services": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(BookableServiceType))),
+			Description: "The services that can be booked with the staff member",
+		},
+	},
+})
+
+// BookingRequestInput represents the GraphQL input for booking online
+var BookingRequestInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "BookingRequestInput",
+	Description: "An online booking, with the details of the client making it",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"businessId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"serviceId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the service to book",
+		},
+		"staffId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The ID of the staff member of the chosen slot; required unless booking from a staff page",
+		},
+		"pageSlug": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The slug of the staff page the booking is made from, which books its staff member",
+		},
+		"startTime": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "The start time of the chosen slot",
+		},
+		"firstName": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The client's first name",
+		},
+		"lastName": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The client's last name",
+		},
+		"email": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The client's email address, which identifies returning clients",
+		},
+		"phone": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The client's phone number",
+		},
+		"notes": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "Notes for the business",
+		},
+		"captchaToken": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The CAPTCHA solution, when a previous attempt required one",
+		},
+	},
+})
+
+// CreatePublicSchema creates the GraphQL schema of the public booking API. It is separate
+// from the main schema so that none of the internal operations are reachable without
+// authentication.
+func CreatePublicSchema(resolver *PublicResolver) (graphql.Schema, error) {
+	rootQuery := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"services": &graphql.Field{
+				Type:        graphql.NewList(BookableServiceType),
+				Description: "Get the services of a business that can be booked online",
+				Args: graphql.FieldConfigArgument{
+					"businessId": &graphql.ArgumentConfig{
+						Type:        graphql.NewNonNull(graphql.String),
+						Description: "The ID of the business",
+					},
+				},
+				Resolve: resolver.resolveServices,
+			},
+			"availableSlots": &graphql.Field{
+				Type:        graphql.NewList(BookingSlotType),
+				Description: "Get the times on a day at which a service can be booked",
+				Args: graphql.FieldConfigArgument{
+					"businessId": &graphql.ArgumentConfig{
+						Type:        graphql.NewNonNull(graphql.String),
+						Description: "The ID of the business",
+					},
+					"serviceId": &graphql.ArgumentConfig{
+						Type:        graphql.NewNonNull(graphql.String),
+						Description: "The ID of the service",
+					},
+					"staffId": &graphql.ArgumentConfig{
+						Type:        graphql.String,
+						Description: "Only return the slots of this staff member",
+					},
+					"pageSlug": &graphql.ArgumentConfig{
+						Type:        graphql.String,
+						Description: "Only return the slots of the staff member of this staff page",
+					},
+					"date": &graphql.ArgumentConfig{
+						Type:        graphql.NewNonNull(graphql.String),
+						Description: "The day, as YYYY-MM-DD in the business's time zone",
+					},
+				},
+				Resolve: resolver.resolveAvailableSlots,
+			},
+			"staffPage": &graphql.Field{
+				Type:        StaffPageType,
+				Description: "Get a staff member's booking page by the slug in its link",
+				Args: graphql.FieldConfigArgument{
+					"slug": &graphql.ArgumentConfig{
+						Type:        graphql.NewNonNull(graphql.String),
+						Description: "The slug of the page",
+					},
+				},
+				Resolve: resolver.resolveStaffPage,
+			},
+		},
+	})
+	rootMutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"requestBooking": &graphql.Field{
+				Type:        graphql.NewNonNull(BookingResultType),
+				Description: "Request an appointment, registering the client with the business if they are new",
+				Args: graphql.FieldConfigArgument{
+					"input": &graphql.ArgumentConfig{
+						Type:        graphql.NewNonNull(BookingRequestInput),
+						Description: "The booking",
+					},
+				},
+				Resolve: resolver.resolveRequestBooking,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query:    rootQuery,
+		Mutation: rootMutation,
+	})
+}
@@ -0,0 +1,62 @@
+package graph
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimiter limits how many requests each client IP address can make in a fixed window. It
+// keeps its counts in memory, so every API instance enforces the limit on its own.
+type RateLimiter struct {
+	limit  int
+	window time.Duration
+	now    func() time.Time
+
+	mu      sync.Mutex
+	windows map[string]*rateWindow
+}
+
+// rateWindow counts the requests of an IP address since the window started
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// NewRateLimiter creates a rate limiter allowing limit requests per IP address in each window
+func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
+	return &RateLimiter{
+		limit:   limit,
+		window:  window,
+		now:     time.Now,
+		windows: map[string]*rateWindow{},
+	}
+}
+
+// Allow counts a request from the IP address and reports whether it is within the limit. When
+// it is not, it also returns how long until the address may make requests again.
+func (l *RateLimiter) Allow(ip string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.prune(now)
+	w, ok := l.windows[ip]
+	if !ok {
+		w = &rateWindow{start: now}
+		l.windows[ip] = w
+	}
+	if w.count >= l.limit {
+		return false, w.start.Add(l.window).Sub(now)
+	}
+	w.count++
+	return true, 0
+}
+
+// prune forgets the windows that have ended. The caller must hold the lock.
+func (l *RateLimiter) prune(now time.Time) {
+	for ip, w := range l.windows {
+		if !now.Before(w.start.Add(l.window)) {
+			delete(l.windows, ip)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package graph
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Date(2024, 6, 3, 12, 0, 0, 0, time.UTC)
+	limiter := NewRateLimiter(2, time.Minute)
+	limiter.now = func() time.Time { return now }
+
+	allowed, _ := limiter.Allow("10.0.0.1")
+	assert.True(t, allowed)
+	allowed, _ = limiter.Allow("10.0.0.1")
+	assert.True(t, allowed)
+
+	now = now.Add(20 * time.Second)
+	allowed, retryAfter := limiter.Allow("10.0.0.1")
+	assert.False(t, allowed)
+	assert.Equal(t, 40*time.Second, retryAfter)
+
+	allowed, _ = limiter.Allow("10.0.0.2")
+	assert.True(t, allowed, "each address has its own limit")
+
+	now = now.Add(40 * time.Second)
+	allowed, _ = limiter.Allow("10.0.0.1")
+	assert.True(t, allowed, "a new window starts once the previous one ends")
+}
+
+func TestHandlerWithRateLimit(t *testing.T) {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"ping": &graphql.Field{
+					Type:    graphql.String,
+					Resolve: func(p graphql.ResolveParams) (any, error) { return "pong", nil },
+				},
+			},
+		}),
+	})
+	require.NoError(t, err)
+	handler := Handler(schema, WithRateLimit(NewRateLimiter(1, time.Minute)))
+
+	post := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, PublicGraphQLPath, strings.NewReader(`{"query": "{ ping }"}`))
+		req.RemoteAddr = "10.0.0.1:1234"
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+
+	rec := post()
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "pong")
+
+	rec = post()
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "60", rec.Header().Get("Retry-After"))
+}
@@ -0,0 +1,63 @@
+package graph
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/assimoes/beautix/internal/infrastructure/health"
+	"github.com/assimoes/beautix/internal/infrastructure/resilience"
+)
+
+// Readiness statuses reported by the readiness endpoint
+const (
+	ReadinessOK          = health.StatusOK
+	ReadinessDegraded    = health.StatusDegraded    // A non-critical dependency fails or a provider circuit is open; the API keeps serving
+	ReadinessUnavailable = health.StatusUnavailable // A critical dependency, such as the database, fails
+)
+
+// readinessTimeout bounds each dependency check, so probes answer within their own timeout
+const readinessTimeout = 2 * time.Second
+
+// readinessResponse is the body of the readiness endpoint
+type readinessResponse struct {
+	health.Report
+	Providers []resilience.ProviderHealth `json:"providers"`
+}
+
+// LivenessHandler reports that the process is up and serving HTTP. It checks no dependency, so
+// an outage of one does not get the API restarted.
+func LivenessHandler(version string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(map[string]string{"status": ReadinessOK, "version": version})
+	}
+}
+
+// ReadinessHandler reports whether the API can serve traffic, with the outcome of every check and
+// the health of every external provider. Only a failing critical check makes the API unready; a
+// failing non-critical check or an open provider circuit is reported as degraded, since the API
+// keeps serving without that dependency.
+func ReadinessHandler(checks []health.Check, providers *resilience.Registry) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		response := readinessResponse{
+			Report:    health.Run(r.Context(), readinessTimeout, checks),
+			Providers: providers.Health(),
+		}
+		for _, provider := range response.Providers {
+			if provider.State != resilience.StateClosed && response.Status == ReadinessOK {
+				response.Status = ReadinessDegraded
+			}
+		}
+
+		status := http.StatusOK
+		if response.Status == ReadinessUnavailable {
+			status = http.StatusServiceUnavailable
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(response)
+	}
+}
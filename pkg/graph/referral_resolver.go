@@ -0,0 +1,66 @@
+package graph
+
+import (
+	"errors"
+	"time"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Referral Query Resolvers
+func (r *Resolver) resolveReferralStats(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+	start, ok := p.Args["start"].(time.Time)
+	if !ok {
+		return nil, errors.New("start is required")
+	}
+	end, ok := p.Args["end"].(time.Time)
+	if !ok {
+		return nil, errors.New("end is required")
+	}
+
+	stats, err := r.referralService.GetReferralStats(p.Context, businessID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// Referral Mutation Resolvers
+func (r *Resolver) resolveIssueReferralCode(p graphql.ResolveParams) (any, error) {
+	programID, ok := p.Args["programId"].(string)
+	if !ok {
+		return nil, errors.New("programId is required")
+	}
+	clientID, ok := p.Args["clientId"].(string)
+	if !ok {
+		return nil, errors.New("clientId is required")
+	}
+
+	code, err := r.referralService.IssueReferralCode(p.Context, programID, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	return code, nil
+}
+
+func (r *Resolver) resolveRecordReferral(p graphql.ResolveParams) (any, error) {
+	recordDTO := dto.RecordReferralDTO{}
+	if err := decodeInput(p.Args, &recordDTO); err != nil {
+		return nil, err
+	}
+
+	referral, err := r.referralService.RecordReferral(p.Context, recordDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return referral, nil
+}
@@ -0,0 +1,252 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// ReferralStatusEnum represents the GraphQL enum for referral statuses
+var ReferralStatusEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "ReferralStatus",
+	Description: "Whether a referred client has completed a visit yet",
+	Values: graphql.EnumValueConfigMap{
+		"PENDING": &graphql.EnumValueConfig{
+			Value:       domain.ReferralPending,
+			Description: "The referred client has not completed a visit yet",
+		},
+		"CONVERTED": &graphql.EnumValueConfig{
+			Value:       domain.ReferralConverted,
+			Description: "The referred client completed their first visit",
+		},
+	},
+})
+
+// ReferralCodeType represents the GraphQL ReferralCode type
+var ReferralCodeType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ReferralCode",
+	Description: "The code a loyalty program member refers friends with",
+	Fields: graphql.Fields{
+		"programId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the loyalty program",
+		},
+		"clientId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the member",
+		},
+		"membershipId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the membership the code was given to",
+		},
+		"code": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The referral code",
+		},
+	},
+})
+
+// ReferralType represents the GraphQL Referral type
+var ReferralType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Referral",
+	Description: "A new client brought to the business by a loyalty program member",
+	Fields: withBaseFields("referral", graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"programId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the loyalty program the referral bonus comes from",
+		},
+		"referrerMembershipId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the referrer's membership",
+		},
+		"referrerClientId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the client who made the referral",
+		},
+		"referrer": clientRelation(func(r *dto.ReferralResponseDTO) string { return r.ReferrerClientID }),
+		"referredClientId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the client who was referred",
+		},
+		"referred": clientRelation(func(r *dto.ReferralResponseDTO) string { return r.ReferredClientID }),
+		"code": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The referral code used",
+		},
+		"status": &graphql.Field{
+			Type:        graphql.NewNonNull(ReferralStatusEnum),
+			Description: "Whether the referred client has completed a visit yet",
+		},
+		"appointmentId": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The ID of the referred client's first completed appointment",
+		},
+		"convertedAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the referred client completed their first visit",
+		},
+		"bonusPoints": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The points the referrer was awarded",
+		},
+	}),
+})
+
+// ReferrerStatsType represents the GraphQL ReferrerStats type
+var ReferrerStatsType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ReferrerStats",
+	Description: "How many clients a member referred and how many of them converted",
+	Fields: graphql.Fields{
+		"membershipId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the referrer's membership",
+		},
+		"clientId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the referrer",
+		},
+		"client": clientRelation(func(r *dto.ReferrerStatsDTO) string { return r.ClientID }),
+		"referrals": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The clients referred",
+		},
+		"converted": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The referred clients who completed a visit",
+		},
+		"bonusPoints": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The referral bonus points awarded",
+		},
+	},
+})
+
+// ReferralStatsType represents the GraphQL ReferralStats type
+var ReferralStatsType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ReferralStats",
+	Description: "How the referrals a business received over a period converted",
+	Fields: graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"periodStart": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "The start of the period",
+		},
+		"periodEnd": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "The end of the period",
+		},
+		"referrals": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The referrals made in the period",
+		},
+		"converted": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The referred clients who completed a visit",
+		},
+		"pending": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The referred clients yet to complete a visit",
+		},
+		"bonusPoints": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The referral bonus points awarded",
+		},
+		"conversionRate": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Float),
+			Description: "The share of referrals that converted, between 0 and 1",
+		},
+		"averageDaysToConvert": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Float),
+			Description: "The average days from the referral to the first completed visit",
+		},
+		"topReferrers": &graphql.Field{
+			Type:        graphql.NewList(ReferrerStatsType),
+			Description: "The members who brought the most converted clients",
+		},
+	},
+})
+
+// RecordReferralInput represents the GraphQL input for recording a referral
+var RecordReferralInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "RecordReferralInput",
+	Description: "Input for recording that a new client was referred by a loyalty program member",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"businessId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"code": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The referrer's code, with or without the dash",
+		},
+		"referredClientId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the new client",
+		},
+	},
+})
+
+// referralQueryFields returns the referral queries
+func referralQueryFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"referralStats": &graphql.Field{
+			Type:        ReferralStatsType,
+			Description: "Get how the referrals a business received over a period converted",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+				"start": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.DateTime),
+					Description: "The start of the period",
+				},
+				"end": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.DateTime),
+					Description: "The end of the period",
+				},
+			},
+			Resolve: resolver.resolveReferralStats,
+		},
+	}
+}
+
+// referralMutationFields returns the referral mutations
+func referralMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"issueReferralCode": &graphql.Field{
+			Type:        ReferralCodeType,
+			Description: "Get a client's referral code for a loyalty program, issuing one the first time",
+			Args: graphql.FieldConfigArgument{
+				"programId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the loyalty program",
+				},
+				"clientId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the client",
+				},
+			},
+			Resolve: resolver.resolveIssueReferralCode,
+		},
+		"recordReferral": &graphql.Field{
+			Type:        ReferralType,
+			Description: "Record that a new client was referred with a member's code",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(RecordReferralInput),
+					Description: "The referral",
+				},
+			},
+			Resolve: resolver.resolveRecordReferral,
+		},
+	}
+}
@@ -0,0 +1,52 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/pkg/graph/dataloader"
+)
+
+// errLoadersMissing is returned when a related entity is requested outside graph.Handler,
+// which attaches the loaders to each request
+var errLoadersMissing = errors.New("related entities cannot be loaded for this request")
+
+// relationField builds a field resolving the entity a source DTO refers to by ID. The lookup
+// goes through the request's loader, so the entities referred to by every item of a list are
+// fetched in one batch. Missing entities, including those of another tenant, resolve to null.
+func relationField[S, T, R any](
+	typ graphql.Output,
+	description string,
+	loaderOf func(*dataloader.Loaders) *dataloader.Loader[T],
+	idOf func(S) string,
+	convert func(*T) *R,
+) *graphql.Field {
+	return &graphql.Field{
+		Type:        typ,
+		Description: description,
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			source, ok := p.Source.(S)
+			if !ok {
+				return nil, nil
+			}
+			id := idOf(source)
+			if id == "" {
+				return nil, nil
+			}
+			loaders := dataloader.For(p.Context)
+			if loaders == nil {
+				return nil, errLoadersMissing
+			}
+
+			load := loaderOf(loaders).Load(p.Context, id)
+			return func() (any, error) {
+				entity, err := load()
+				if err != nil || entity == nil {
+					return nil, err
+				}
+				return convert(entity), nil
+			}, nil
+		},
+	}
+}
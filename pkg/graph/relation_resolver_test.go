@@ -0,0 +1,100 @@
+package graph
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/repository/memory"
+	"github.com/assimoes/beautix/pkg/graph/dataloader"
+)
+
+// countingRepository records the batches fetched through a repository
+type countingRepository[T any] struct {
+	domain.BaseRepository[T]
+	batches [][]string
+}
+
+func (r *countingRepository[T]) GetByIDs(ctx context.Context, ids []string) ([]*T, error) {
+	r.batches = append(r.batches, ids)
+	return r.BaseRepository.GetByIDs(ctx, ids)
+}
+
+func TestRelationFields_BatchLookups(t *testing.T) {
+	db := memory.NewDB()
+	business := &domain.Business{Name: "Salon", Email: "salon@example.com"}
+	require.NoError(t, memory.Insert(db, business))
+	user := &domain.User{Email: "ana@example.com", FirstName: "Ana", LastName: "Silva"}
+	require.NoError(t, memory.Insert(db, user))
+	staff := &domain.Staff{BusinessID: business.ID, UserID: user.ID, Role: domain.BusinessRoleEmployee}
+	require.NoError(t, memory.Insert(db, staff))
+	clients := []*domain.Client{
+		{BusinessID: business.ID, FirstName: "Rita", Email: "rita@example.com"},
+		{BusinessID: business.ID, FirstName: "Joana", Email: "joana@example.com"},
+	}
+	require.NoError(t, memory.Insert(db, clients...))
+
+	var appointments []*dto.AppointmentResponseDTO
+	for _, client := range []*domain.Client{clients[0], clients[1], clients[0]} {
+		appointments = append(appointments, &dto.AppointmentResponseDTO{BusinessID: business.ID, ClientID: client.ID, StaffID: staff.ID})
+	}
+	appointments = append(appointments, &dto.AppointmentResponseDTO{BusinessID: business.ID, ClientID: "deleted", StaffID: staff.ID})
+
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"appointments": &graphql.Field{
+					Type:    graphql.NewList(AppointmentType),
+					Resolve: func(p graphql.ResolveParams) (any, error) { return appointments, nil },
+				},
+			},
+		}),
+	})
+	require.NoError(t, err)
+
+	clientRepo := &countingRepository[domain.Client]{BaseRepository: memory.NewBaseRepository[domain.Client](db)}
+	businessRepo := &countingRepository[domain.Business]{BaseRepository: memory.NewBaseRepository[domain.Business](db)}
+	loaders := dataloader.New(dataloader.Sources{
+		Users:        memory.NewBaseRepository[domain.User](db),
+		Clients:      clientRepo,
+		Staff:        memory.NewBaseRepository[domain.Staff](db),
+		Businesses:   businessRepo,
+		Services:     memory.NewBaseRepository[domain.Service](db),
+		Appointments: memory.NewBaseRepository[domain.Appointment](db),
+	})
+
+	result := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ appointments { client { firstName business { name } } staff { role user { firstName } } business { name } } }`,
+		Context:       dataloader.WithLoaders(context.Background(), loaders),
+	})
+	require.Empty(t, result.Errors)
+
+	rows := result.Data.(map[string]any)["appointments"].([]any)
+	require.Len(t, rows, 4)
+	first := rows[0].(map[string]any)
+	assert.Equal(t, "Rita", first["client"].(map[string]any)["firstName"])
+	assert.Equal(t, "Salon", first["client"].(map[string]any)["business"].(map[string]any)["name"])
+	assert.Equal(t, "employee", first["staff"].(map[string]any)["role"])
+	assert.Equal(t, "Ana", first["staff"].(map[string]any)["user"].(map[string]any)["firstName"])
+	assert.Equal(t, "Joana", rows[1].(map[string]any)["client"].(map[string]any)["firstName"])
+	assert.Nil(t, rows[3].(map[string]any)["client"], "missing clients resolve to null")
+
+	assert.Equal(t, [][]string{{clients[0].ID, clients[1].ID, "deleted"}}, clientRepo.batches)
+	assert.Len(t, businessRepo.batches, 1, "the business is fetched once for the appointments and their clients")
+}
+
+func TestRelationFields_RequireLoaders(t *testing.T) {
+	field := clientRelation(func(a *dto.AppointmentResponseDTO) string { return a.ClientID })
+	_, err := field.Resolve(graphql.ResolveParams{
+		Source:  &dto.AppointmentResponseDTO{ClientID: "client-1"},
+		Context: context.Background(),
+	})
+	assert.ErrorIs(t, err, errLoadersMissing)
+}
@@ -0,0 +1,186 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/pkg/graph/dataloader"
+)
+
+// ClientType represents the GraphQL Client type
+var ClientType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Client",
+	Description: "A client of a business",
+	Fields: withBaseFields("client", graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"firstName": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The first name of the client",
+		},
+		"lastName": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The last name of the client",
+		},
+		"email": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The email address of the client",
+		},
+		"phone": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The phone number of the client",
+		},
+		"isActive": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether the client is active",
+		},
+		"lastVisit": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the client last visited",
+		},
+		"totalVisits": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "How many times the client has visited",
+		},
+		"totalSpent": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "How much the client has spent in total",
+		},
+		"tags": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+			Description: "The lower-case tags of the client, which campaigns can target",
+		},
+		"noShowCount": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The no-shows and late cancellations counted against the client",
+		},
+		"depositRequired": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether the client reached the business's no-show limit and must pay a deposit to book",
+		},
+		"business": businessRelation(func(c *dto.ClientResponseDTO) string { return c.BusinessID }),
+	}),
+})
+
+// StaffType represents the GraphQL Staff type
+var StaffType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Staff",
+	Description: "A user working at a business",
+	Fields: withBaseFields("staff member", graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"userId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the staff member's user account",
+		},
+		"role": &graphql.Field{
+			Type:        graphql.NewNonNull(BusinessRoleEnum),
+			Description: "The role of the staff member in the business",
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				if staff, ok := p.Source.(*dto.StaffResponseDTO); ok {
+					return string(staff.Role), nil
+				}
+				return nil, nil
+			},
+		},
+		"isActive": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether the staff member is active",
+		},
+		"profileImageUrl": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The URL of the staff member's profile picture",
+		},
+		"averageRating": &graphql.Field{
+			Type:        DecimalScalar,
+			Description: "The average of the staff member's published reviews, 1 to 5; null without any",
+		},
+		"reviewCount": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The number of the staff member's published reviews",
+		},
+		"user": relationField(UserType, "The staff member's user account",
+			func(l *dataloader.Loaders) *dataloader.Loader[domain.User] { return l.Users },
+			func(s *dto.StaffResponseDTO) string { return s.UserID },
+			dto.ToUserResponseDTO),
+		"business": businessRelation(func(s *dto.StaffResponseDTO) string { return s.BusinessID }),
+	}),
+})
+
+// ServiceType represents the GraphQL Service type
+var ServiceType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Service",
+	Description: "A service offered by a business",
+	Fields: withBaseFields("service", graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"categoryId": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The ID of the service category",
+		},
+		"name": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The name of the service",
+		},
+		"description": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The description of the service",
+		},
+		"duration": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The duration of the service in minutes",
+		},
+		"price": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The current price of the service",
+		},
+		"isActive": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether the service can be booked",
+		},
+		"requiresDeposit": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether booking the service requires a deposit",
+		},
+		"depositAmount": &graphql.Field{
+			Type:        DecimalScalar,
+			Description: "The deposit required to book the service",
+		},
+		"business": businessRelation(func(s *dto.ServiceResponseDTO) string { return s.BusinessID }),
+	}),
+})
+
+// businessRelation builds the field resolving the business of a response DTO
+func businessRelation[S any](idOf func(S) string) *graphql.Field {
+	return relationField(BusinessType, "The business",
+		func(l *dataloader.Loaders) *dataloader.Loader[domain.Business] { return l.Businesses },
+		idOf, dto.ToBusinessResponseDTO)
+}
+
+// clientRelation builds the field resolving the client of a response DTO
+func clientRelation[S any](idOf func(S) string) *graphql.Field {
+	return relationField(ClientType, "The client",
+		func(l *dataloader.Loaders) *dataloader.Loader[domain.Client] { return l.Clients },
+		idOf, dto.ToClientResponseDTO)
+}
+
+// staffRelation builds the field resolving the staff member of a response DTO
+func staffRelation[S any](idOf func(S) string) *graphql.Field {
+	return relationField(StaffType, "The staff member",
+		func(l *dataloader.Loaders) *dataloader.Loader[domain.Staff] { return l.Staff },
+		idOf, dto.ToStaffResponseDTO)
+}
+
+// serviceRelation builds the field resolving the service of a response DTO
+func serviceRelation[S any](idOf func(S) string) *graphql.Field {
+	return relationField(ServiceType, "The service",
+		func(l *dataloader.Loaders) *dataloader.Loader[domain.Service] { return l.Services },
+		idOf, dto.ToServiceResponseDTO)
+}
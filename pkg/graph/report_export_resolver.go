@@ -0,0 +1,83 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Report Export Query Resolvers
+func (r *Resolver) resolveReportExport(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+
+	export, err := r.reportExportService.GetReportExport(p.Context, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return export, nil
+}
+
+func (r *Resolver) resolveReportExports(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+	limit, _ := p.Args["limit"].(int)
+
+	exports, err := r.reportExportService.ListReportExports(p.Context, businessID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return exports, nil
+}
+
+func (r *Resolver) resolveReportExportDownload(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+
+	download, err := r.reportExportService.GetDownloadURL(p.Context, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return download, nil
+}
+
+// Report Export Mutation Resolvers
+func (r *Resolver) resolveExportReport(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+	report, ok := p.Args["report"].(domain.ReportKind)
+	if !ok {
+		return nil, errors.New("report is required")
+	}
+	format, ok := p.Args["format"].(domain.ReportFormat)
+	if !ok {
+		return nil, errors.New("format is required")
+	}
+
+	export, err := r.reportExportService.ExportReport(p.Context, dto.CreateReportExportDTO{
+		BusinessID: businessID,
+		Report:     report,
+		Format:     format,
+		StartDate:  optionalString(p.Args, "startDate"),
+		EndDate:    optionalString(p.Args, "endDate"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return export, nil
+}
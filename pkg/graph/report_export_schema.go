@@ -0,0 +1,243 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// ReportKindEnum represents the GraphQL enum for the reports that can be exported
+var ReportKindEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "ReportKind",
+	Description: "A report that can be exported to a file",
+	Values: graphql.EnumValueConfigMap{
+		"REVENUE": &graphql.EnumValueConfig{
+			Value:       domain.ReportRevenue,
+			Description: "What was charged for each completed service",
+		},
+		"COMMISSIONS": &graphql.EnumValueConfig{
+			Value:       domain.ReportCommissions,
+			Description: "The commission each staff member with a commission plan earned",
+		},
+		"CLIENTS": &graphql.EnumValueConfig{
+			Value:       domain.ReportClients,
+			Description: "The client list, which is not limited to a range of dates",
+		},
+		"APPOINTMENTS": &graphql.EnumValueConfig{
+			Value:       domain.ReportAppointments,
+			Description: "The appointments starting in the range",
+		},
+	},
+})
+
+// ReportFormatEnum represents the GraphQL enum for the file formats of report exports
+var ReportFormatEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "ReportFormat",
+	Description: "The file format of a report export",
+	Values: graphql.EnumValueConfigMap{
+		"CSV": &graphql.EnumValueConfig{
+			Value:       domain.ReportFormatCSV,
+			Description: "Comma separated values, for spreadsheets",
+		},
+		"PDF": &graphql.EnumValueConfig{
+			Value:       domain.ReportFormatPDF,
+			Description: "A printable table",
+		},
+	},
+})
+
+// ReportExportStatusEnum represents the GraphQL enum for the state of a report export
+var ReportExportStatusEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "ReportExportStatus",
+	Description: "How far a report export has got",
+	Values: graphql.EnumValueConfigMap{
+		"PENDING": &graphql.EnumValueConfig{
+			Value:       domain.ReportExportPending,
+			Description: "Waiting to be generated",
+		},
+		"READY": &graphql.EnumValueConfig{
+			Value:       domain.ReportExportReady,
+			Description: "Generated and downloadable until it expires",
+		},
+		"FAILED": &graphql.EnumValueConfig{
+			Value:       domain.ReportExportFailed,
+			Description: "Could not be generated; see the error",
+		},
+		"EXPIRED": &graphql.EnumValueConfig{
+			Value:       domain.ReportExportExpired,
+			Description: "Removed from storage",
+		},
+	},
+})
+
+// ReportExportType represents the GraphQL ReportExport type
+var ReportExportType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ReportExport",
+	Description: "A report exported to a file in the background",
+	Fields: withBaseFields("export", graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"report": &graphql.Field{
+			Type:        graphql.NewNonNull(ReportKindEnum),
+			Description: "The report exported",
+		},
+		"format": &graphql.Field{
+			Type:        graphql.NewNonNull(ReportFormatEnum),
+			Description: "The file format",
+		},
+		"startDate": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The first day of the report, as YYYY-MM-DD; null for the client list",
+		},
+		"endDate": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The last day of the report, as YYYY-MM-DD; null for the client list",
+		},
+		"status": &graphql.Field{
+			Type:        graphql.NewNonNull(ReportExportStatusEnum),
+			Description: "How far the export has got",
+		},
+		"fileName": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The name the file downloads as",
+		},
+		"contentType": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The media type of the file",
+		},
+		"requestedBy": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The ID of the user who asked for the export",
+		},
+		"jobId": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The ID of the job generating the export",
+		},
+		"rowCount": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "The rows of the report, once generated",
+		},
+		"sizeBytes": &graphql.Field{
+			Type:        graphql.Float,
+			Description: "The size of the file in bytes, once generated",
+		},
+		"error": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Why the export failed",
+		},
+		"completedAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the export was generated or failed",
+		},
+		"expiresAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the file is removed from storage",
+		},
+	}),
+})
+
+// ReportExportDownloadType represents the GraphQL ReportExportDownload type
+var ReportExportDownloadType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ReportExportDownload",
+	Description: "A signed URL a report export can be downloaded from without signing in",
+	Fields: graphql.Fields{
+		"exportId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the export",
+		},
+		"url": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The URL; anyone holding it can download the file until it expires",
+		},
+		"fileName": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The name the file downloads as",
+		},
+		"contentType": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The media type of the file",
+		},
+		"expiresAt": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the URL stops working",
+		},
+	},
+})
+
+// reportExportQueryFields returns the report export queries
+func reportExportQueryFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"reportExport": &graphql.Field{
+			Type:        ReportExportType,
+			Description: "Get a report export by ID",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the export",
+				},
+			},
+			Resolve: resolver.resolveReportExport,
+		},
+		"reportExports": &graphql.Field{
+			Type:        graphql.NewList(ReportExportType),
+			Description: "Get the most recent report exports of a business, newest first, of the reports the caller may export",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+				"limit": &graphql.ArgumentConfig{
+					Type:        graphql.Int,
+					Description: "The most exports to return; 20 by default, at most 100",
+				},
+			},
+			Resolve: resolver.resolveReportExports,
+		},
+		"reportExportDownload": &graphql.Field{
+			Type:        ReportExportDownloadType,
+			Description: "Sign a short-lived URL to download a ready report export from",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the export",
+				},
+			},
+			Resolve: resolver.resolveReportExportDownload,
+		},
+	}
+}
+
+// reportExportMutationFields returns the report export mutations
+func reportExportMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"exportReport": &graphql.Field{
+			Type:        ReportExportType,
+			Description: "Export a report to a file in the background (staff who may view reports; the client list takes the client export permission)",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+				"report": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(ReportKindEnum),
+					Description: "The report to export",
+				},
+				"format": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(ReportFormatEnum),
+					Description: "The file format",
+				},
+				"startDate": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: "The first day, as YYYY-MM-DD; required but for the client list",
+				},
+				"endDate": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: "The last day, as YYYY-MM-DD; at most a year after the first",
+				},
+			},
+			Resolve: resolver.resolveExportReport,
+		},
+	}
+}
@@ -0,0 +1,105 @@
+package graph
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+	"time"
+
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/assimoes/beautix/internal/service"
+)
+
+// rescheduleTimeLayout is the format of the datetime-local input on the rescheduling page
+const rescheduleTimeLayout = "2006-01-02T15:04"
+
+// rescheduleTemplate renders the page a client lands on from a rescheduling link
+var rescheduleTemplate = template.Must(template.New("reschedule").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+    <title>{{.BusinessName}}</title>
+    <style>
+        body { font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; margin: 40px auto; max-width: 480px; padding: 0 16px; color: #333; text-align: center; }
+        input { font-size: 18px; padding: 8px; margin-bottom: 16px; }
+        button { font-size: 18px; padding: 12px 32px; border: none; border-radius: 6px; background: #333; color: #fff; cursor: pointer; }
+    </style>
+</head>
+<body>
+    <h1>{{.BusinessName}}</h1>
+    {{if .Rescheduled}}
+    <p>Your appointment is now on <strong>{{.StartTime.Format "Monday, 2 January"}}</strong> at <strong>{{.StartTime.Format "15:04"}}</strong>. See you then!</p>
+    {{else}}
+    <p>Your appointment on <strong>{{.StartTime.Format "Monday, 2 January"}}</strong> at <strong>{{.StartTime.Format "15:04"}}</strong> is affected. Pick a new time:</p>
+    <form method="POST">
+        <input type="datetime-local" name="start_time" required><br>
+        <button type="submit">Reschedule appointment</button>
+    </form>
+    {{end}}
+</body>
+</html>
+`))
+
+// RescheduleHandler serves the page a client opens from the rescheduling link of an emergency
+// broadcast. Opening the page shows the appointment; submitting a start_time in the business
+// time zone moves it. It must be registered under service.RescheduleLinkPath.
+func RescheduleHandler(broadcastService service.BroadcastService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Path[len(service.RescheduleLinkPath):]
+
+		var result *dto.RescheduleRequestDTO
+		var err error
+		switch r.Method {
+		case http.MethodGet:
+			result, err = broadcastService.GetRescheduleRequest(r.Context(), token)
+		case http.MethodPost:
+			result, err = broadcastService.GetRescheduleRequest(r.Context(), token)
+			if err == nil {
+				var startTime time.Time
+				startTime, err = parseRescheduleTime(r, result.TimeZone)
+				if err == nil {
+					result, err = broadcastService.RescheduleByToken(r.Context(), token, startTime)
+				}
+			}
+		default:
+			http.Error(w, "Only GET and POST requests are allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if err != nil {
+			var notFound service.NotFoundError
+			var invalid *validation.ValidationError
+			switch {
+			case errors.As(err, &notFound):
+				http.Error(w, "This rescheduling link is not valid", http.StatusNotFound)
+			case errors.As(err, &invalid):
+				http.Error(w, invalid.Error(), http.StatusConflict)
+			default:
+				http.Error(w, "Failed to reschedule appointment", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := rescheduleTemplate.Execute(w, result); err != nil {
+			http.Error(w, "Failed to render page", http.StatusInternalServerError)
+		}
+	}
+}
+
+// parseRescheduleTime reads the start_time form field in the business time zone
+func parseRescheduleTime(r *http.Request, timeZone string) (time.Time, error) {
+	if err := r.ParseForm(); err != nil {
+		return time.Time{}, validation.NewValidationError("invalid form body")
+	}
+	location, err := time.LoadLocation(timeZone)
+	if err != nil {
+		location = time.UTC
+	}
+	startTime, err := time.ParseInLocation(rescheduleTimeLayout, r.PostForm.Get("start_time"), location)
+	if err != nil {
+		return time.Time{}, validation.NewValidationError("pick a date and time")
+	}
+	return startTime, nil
+}
@@ -11,16 +11,479 @@ import (
 
 // Resolver contains the GraphQL resolvers
 type Resolver struct {
-	userService service.UserService
-	authService service.AuthService
+	userService                    service.UserService
+	authService                    service.AuthService
+	serviceRecordService           service.ServiceRecordService
+	commissionService              service.CommissionService
+	integrationUsageService        service.IntegrationUsageService
+	eventService                   service.EventService
+	calendarService                service.CalendarService
+	clientPortalService            service.ClientPortalService
+	messageTemplateService         service.MessageTemplateService
+	appointmentConfirmationService service.AppointmentConfirmationService
+	bookingGuardService            service.BookingGuardService
+	capacityService                service.CapacityService
+	appointmentService             service.AppointmentService
+	priceChangeService             service.PriceChangeService
+	serviceBundleService           service.ServiceBundleService
+	clientGuardianService          service.ClientGuardianService
+	clientService                  service.ClientService
+	outboundRequestService         service.OutboundRequestService
+	broadcastService               service.BroadcastService
+	paymentService                 service.PaymentService
+	dataResidencyService           service.DataResidencyService
+	anonymousFeedbackService       service.AnonymousFeedbackService
+	availabilityService            service.AvailabilityService
+	scheduleChangeService          service.ScheduleChangeService
+	giftCardService                service.GiftCardService
+	staffBookingPageService        service.StaffBookingPageService
+	businessService                service.BusinessService
+	referralService                service.ReferralService
+	campaignService                service.CampaignService
+	membershipService              service.MembershipService
+	announcementService            service.AnnouncementService
+	webhookService                 service.WebhookService
+	demoDataService                service.DemoDataService
+	savedViewService               service.SavedViewService
+	businessImportService          service.BusinessImportService
+	watchdogService                service.WatchdogService
+	runSheetService                service.RunSheetService
+	jobService                     service.JobService
+	auditLogService                service.AuditLogService
+	permissionService              service.PermissionService
+	trashService                   service.TrashService
+	sessionService                 service.SessionService
+	searchService                  service.SearchService
+	clientLeaderboardService       service.ClientLeaderboardService
+	appointmentQuoteService        service.AppointmentQuoteService
+	cancellationPolicyService      service.CancellationPolicyService
+	staffShiftService              service.StaffShiftService
+	timeClockService               service.TimeClockService
+	staffPerformanceService        service.StaffPerformanceService
+	reportExportService            service.ReportExportService
+	imageUploadService             service.ImageUploadService
+	clientAttachmentService        service.ClientAttachmentService
+	reviewService                  service.ReviewService
+	clientTimelineService          service.ClientTimelineService
+	subscriptionService            service.SubscriptionService
+	trialService                   service.TrialService
+	businessHoursService           service.BusinessHoursService
+	resourceService                service.ResourceService
+	adminService                   service.AdminService
+}
+
+// ResolverOption configures optional services on the resolver
+type ResolverOption func(*Resolver)
+
+// WithServiceRecordService sets the service used by the client service history resolvers
+func WithServiceRecordService(serviceRecordService service.ServiceRecordService) ResolverOption {
+	return func(r *Resolver) {
+		r.serviceRecordService = serviceRecordService
+	}
+}
+
+// WithCommissionService sets the service used by the staff commission resolvers
+func WithCommissionService(commissionService service.CommissionService) ResolverOption {
+	return func(r *Resolver) {
+		r.commissionService = commissionService
+	}
+}
+
+// WithIntegrationUsageService sets the service used by the integration usage resolvers
+func WithIntegrationUsageService(integrationUsageService service.IntegrationUsageService) ResolverOption {
+	return func(r *Resolver) {
+		r.integrationUsageService = integrationUsageService
+	}
+}
+
+// WithEventService sets the service used by the event log admin resolvers
+func WithEventService(eventService service.EventService) ResolverOption {
+	return func(r *Resolver) {
+		r.eventService = eventService
+	}
+}
+
+// WithCalendarService sets the service used by the calendar resolvers
+func WithCalendarService(calendarService service.CalendarService) ResolverOption {
+	return func(r *Resolver) {
+		r.calendarService = calendarService
+	}
+}
+
+// WithClientPortalService sets the service used by the client portal resolvers
+func WithClientPortalService(clientPortalService service.ClientPortalService) ResolverOption {
+	return func(r *Resolver) {
+		r.clientPortalService = clientPortalService
+	}
+}
+
+// WithMessageTemplateService sets the service used by the message template resolvers
+func WithMessageTemplateService(messageTemplateService service.MessageTemplateService) ResolverOption {
+	return func(r *Resolver) {
+		r.messageTemplateService = messageTemplateService
+	}
+}
+
+// WithAppointmentConfirmationService sets the service used by the appointment confirmation resolvers
+func WithAppointmentConfirmationService(appointmentConfirmationService service.AppointmentConfirmationService) ResolverOption {
+	return func(r *Resolver) {
+		r.appointmentConfirmationService = appointmentConfirmationService
+	}
+}
+
+// WithBookingGuardService sets the service used by the booking blocklist resolvers
+func WithBookingGuardService(bookingGuardService service.BookingGuardService) ResolverOption {
+	return func(r *Resolver) {
+		r.bookingGuardService = bookingGuardService
+	}
+}
+
+// WithCapacityService sets the service used by the capacity warning resolvers
+func WithCapacityService(capacityService service.CapacityService) ResolverOption {
+	return func(r *Resolver) {
+		r.capacityService = capacityService
+	}
+}
+
+// WithAppointmentService sets the service used by the appointment booking resolvers
+func WithAppointmentService(appointmentService service.AppointmentService) ResolverOption {
+	return func(r *Resolver) {
+		r.appointmentService = appointmentService
+	}
+}
+
+// WithPriceChangeService sets the service used by the bulk price change resolvers
+func WithPriceChangeService(priceChangeService service.PriceChangeService) ResolverOption {
+	return func(r *Resolver) {
+		r.priceChangeService = priceChangeService
+	}
+}
+
+// WithServiceBundleService sets the service used by the service bundle resolvers
+func WithServiceBundleService(serviceBundleService service.ServiceBundleService) ResolverOption {
+	return func(r *Resolver) {
+		r.serviceBundleService = serviceBundleService
+	}
+}
+
+// WithClientGuardianService sets the service used by the minor client and guardian resolvers
+func WithClientGuardianService(clientGuardianService service.ClientGuardianService) ResolverOption {
+	return func(r *Resolver) {
+		r.clientGuardianService = clientGuardianService
+	}
+}
+
+// WithBroadcastService sets the service used by the emergency broadcast resolvers
+func WithBroadcastService(broadcastService service.BroadcastService) ResolverOption {
+	return func(r *Resolver) {
+		r.broadcastService = broadcastService
+	}
+}
+
+// WithPaymentService sets the service used by the card payment resolvers
+func WithPaymentService(paymentService service.PaymentService) ResolverOption {
+	return func(r *Resolver) {
+		r.paymentService = paymentService
+	}
+}
+
+// WithClientService sets the service used by the client resolvers
+func WithClientService(clientService service.ClientService) ResolverOption {
+	return func(r *Resolver) {
+		r.clientService = clientService
+	}
+}
+
+// WithOutboundRequestService sets the service used by the outbound request audit resolvers
+func WithOutboundRequestService(outboundRequestService service.OutboundRequestService) ResolverOption {
+	return func(r *Resolver) {
+		r.outboundRequestService = outboundRequestService
+	}
+}
+
+// WithDataResidencyService sets the service used by the data residency resolvers
+func WithDataResidencyService(dataResidencyService service.DataResidencyService) ResolverOption {
+	return func(r *Resolver) {
+		r.dataResidencyService = dataResidencyService
+	}
+}
+
+// WithAnonymousFeedbackService sets the service used by the anonymous feedback resolvers
+func WithAnonymousFeedbackService(anonymousFeedbackService service.AnonymousFeedbackService) ResolverOption {
+	return func(r *Resolver) {
+		r.anonymousFeedbackService = anonymousFeedbackService
+	}
+}
+
+// WithAvailabilityService sets the service used by the available slot resolvers
+func WithAvailabilityService(availabilityService service.AvailabilityService) ResolverOption {
+	return func(r *Resolver) {
+		r.availabilityService = availabilityService
+	}
+}
+
+// WithScheduleChangeService sets the service used by the schedule change resolvers
+func WithScheduleChangeService(scheduleChangeService service.ScheduleChangeService) ResolverOption {
+	return func(r *Resolver) {
+		r.scheduleChangeService = scheduleChangeService
+	}
+}
+
+// WithGiftCardService sets the service used by the gift card resolvers
+func WithGiftCardService(giftCardService service.GiftCardService) ResolverOption {
+	return func(r *Resolver) {
+		r.giftCardService = giftCardService
+	}
+}
+
+// WithStaffBookingPageService sets the service used by the staff booking page resolvers
+func WithStaffBookingPageService(staffBookingPageService service.StaffBookingPageService) ResolverOption {
+	return func(r *Resolver) {
+		r.staffBookingPageService = staffBookingPageService
+	}
+}
+
+// WithBusinessService sets the service used by the business resolvers
+func WithBusinessService(businessService service.BusinessService) ResolverOption {
+	return func(r *Resolver) {
+		r.businessService = businessService
+	}
+}
+
+// WithReferralService sets the service used by the referral resolvers
+func WithReferralService(referralService service.ReferralService) ResolverOption {
+	return func(r *Resolver) {
+		r.referralService = referralService
+	}
+}
+
+// WithCampaignService sets the service used by the campaign resolvers
+func WithCampaignService(campaignService service.CampaignService) ResolverOption {
+	return func(r *Resolver) {
+		r.campaignService = campaignService
+	}
+}
+
+// WithMembershipService sets the service used by the membership resolvers
+func WithMembershipService(membershipService service.MembershipService) ResolverOption {
+	return func(r *Resolver) {
+		r.membershipService = membershipService
+	}
+}
+
+// WithAnnouncementService sets the service used by the announcement resolvers
+func WithAnnouncementService(announcementService service.AnnouncementService) ResolverOption {
+	return func(r *Resolver) {
+		r.announcementService = announcementService
+	}
+}
+
+// WithWebhookService sets the service used by the webhook resolvers
+func WithWebhookService(webhookService service.WebhookService) ResolverOption {
+	return func(r *Resolver) {
+		r.webhookService = webhookService
+	}
+}
+
+// WithDemoDataService sets the service used by the sandbox business resolvers
+func WithDemoDataService(demoDataService service.DemoDataService) ResolverOption {
+	return func(r *Resolver) {
+		r.demoDataService = demoDataService
+	}
+}
+
+// WithSavedViewService sets the service used by the saved view resolvers
+func WithSavedViewService(savedViewService service.SavedViewService) ResolverOption {
+	return func(r *Resolver) {
+		r.savedViewService = savedViewService
+	}
+}
+
+// WithBusinessImportService sets the service used by the service and staff import resolvers
+func WithBusinessImportService(businessImportService service.BusinessImportService) ResolverOption {
+	return func(r *Resolver) {
+		r.businessImportService = businessImportService
+	}
+}
+
+// WithWatchdogService sets the service used by the watchdog resolvers
+func WithWatchdogService(watchdogService service.WatchdogService) ResolverOption {
+	return func(r *Resolver) {
+		r.watchdogService = watchdogService
+	}
+}
+
+// WithRunSheetService sets the service used by the run sheet resolvers
+func WithRunSheetService(runSheetService service.RunSheetService) ResolverOption {
+	return func(r *Resolver) {
+		r.runSheetService = runSheetService
+	}
+}
+
+// WithJobService sets the service used by the background job resolvers
+func WithJobService(jobService service.JobService) ResolverOption {
+	return func(r *Resolver) {
+		r.jobService = jobService
+	}
+}
+
+// WithAuditLogService sets the service used by the audit log resolvers
+func WithAuditLogService(auditLogService service.AuditLogService) ResolverOption {
+	return func(r *Resolver) {
+		r.auditLogService = auditLogService
+	}
+}
+
+// WithPermissionService sets the service used by the staff permission resolvers
+func WithPermissionService(permissionService service.PermissionService) ResolverOption {
+	return func(r *Resolver) {
+		r.permissionService = permissionService
+	}
+}
+
+// WithTrashService sets the service used by the trash resolvers
+func WithTrashService(trashService service.TrashService) ResolverOption {
+	return func(r *Resolver) {
+		r.trashService = trashService
+	}
+}
+
+// WithSessionService sets the service used by the session resolvers
+func WithSessionService(sessionService service.SessionService) ResolverOption {
+	return func(r *Resolver) {
+		r.sessionService = sessionService
+	}
+}
+
+// WithSearchService sets the service used by the search resolvers
+func WithSearchService(searchService service.SearchService) ResolverOption {
+	return func(r *Resolver) {
+		r.searchService = searchService
+	}
+}
+
+// WithClientLeaderboardService sets the service used by the client leaderboard and VIP resolvers
+func WithClientLeaderboardService(clientLeaderboardService service.ClientLeaderboardService) ResolverOption {
+	return func(r *Resolver) {
+		r.clientLeaderboardService = clientLeaderboardService
+	}
+}
+
+// WithAppointmentQuoteService sets the service used by the appointment quote resolvers
+func WithAppointmentQuoteService(appointmentQuoteService service.AppointmentQuoteService) ResolverOption {
+	return func(r *Resolver) {
+		r.appointmentQuoteService = appointmentQuoteService
+	}
+}
+
+// WithCancellationPolicyService sets the service used by the cancellation policy resolvers
+func WithCancellationPolicyService(cancellationPolicyService service.CancellationPolicyService) ResolverOption {
+	return func(r *Resolver) {
+		r.cancellationPolicyService = cancellationPolicyService
+	}
+}
+
+// WithStaffShiftService sets the service used by the staff shift and rota resolvers
+func WithStaffShiftService(staffShiftService service.StaffShiftService) ResolverOption {
+	return func(r *Resolver) {
+		r.staffShiftService = staffShiftService
+	}
+}
+
+// WithTimeClockService sets the service used by the time clock and timesheet resolvers
+func WithTimeClockService(timeClockService service.TimeClockService) ResolverOption {
+	return func(r *Resolver) {
+		r.timeClockService = timeClockService
+	}
+}
+
+// WithStaffPerformanceService sets the service used by the staff performance resolvers
+func WithStaffPerformanceService(staffPerformanceService service.StaffPerformanceService) ResolverOption {
+	return func(r *Resolver) {
+		r.staffPerformanceService = staffPerformanceService
+	}
+}
+
+// WithReportExportService sets the service used by the report export resolvers
+func WithReportExportService(reportExportService service.ReportExportService) ResolverOption {
+	return func(r *Resolver) {
+		r.reportExportService = reportExportService
+	}
+}
+
+// WithImageUploadService sets the service used by the image upload resolvers
+func WithImageUploadService(imageUploadService service.ImageUploadService) ResolverOption {
+	return func(r *Resolver) {
+		r.imageUploadService = imageUploadService
+	}
+}
+
+// WithClientAttachmentService sets the service used by the client attachment resolvers
+func WithClientAttachmentService(clientAttachmentService service.ClientAttachmentService) ResolverOption {
+	return func(r *Resolver) {
+		r.clientAttachmentService = clientAttachmentService
+	}
+}
+
+// WithReviewService sets the service used by the review resolvers
+func WithReviewService(reviewService service.ReviewService) ResolverOption {
+	return func(r *Resolver) {
+		r.reviewService = reviewService
+	}
+}
+
+// WithClientTimelineService sets the service used by the client timeline resolvers
+func WithClientTimelineService(clientTimelineService service.ClientTimelineService) ResolverOption {
+	return func(r *Resolver) {
+		r.clientTimelineService = clientTimelineService
+	}
+}
+
+// WithSubscriptionService sets the service used by the subscription resolvers
+func WithSubscriptionService(subscriptionService service.SubscriptionService) ResolverOption {
+	return func(r *Resolver) {
+		r.subscriptionService = subscriptionService
+	}
+}
+
+// WithTrialService sets the service used by the trial resolvers
+func WithTrialService(trialService service.TrialService) ResolverOption {
+	return func(r *Resolver) {
+		r.trialService = trialService
+	}
+}
+
+// WithBusinessHoursService sets the service used by the opening hours resolvers
+func WithBusinessHoursService(businessHoursService service.BusinessHoursService) ResolverOption {
+	return func(r *Resolver) {
+		r.businessHoursService = businessHoursService
+	}
+}
+
+// WithResourceService sets the service used by the resource resolvers
+func WithResourceService(resourceService service.ResourceService) ResolverOption {
+	return func(r *Resolver) {
+		r.resourceService = resourceService
+	}
+}
+
+// WithAdminService sets the service used by the back office resolvers
+func WithAdminService(adminService service.AdminService) ResolverOption {
+	return func(r *Resolver) {
+		r.adminService = adminService
+	}
 }
 
 // NewResolver creates a new GraphQL resolver
-func NewResolver(userService service.UserService, authService service.AuthService) *Resolver {
-	return &Resolver{
+func NewResolver(userService service.UserService, authService service.AuthService, opts ...ResolverOption) *Resolver {
+	r := &Resolver{
 		userService: userService,
 		authService: authService,
 	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
 // User Query Resolvers
@@ -59,6 +522,11 @@ func (r *Resolver) resolveUsers(p graphql.ResolveParams) (any, error) {
 	return users, nil
 }
 
+func (r *Resolver) resolveUsersConnection(p graphql.ResolveParams) (any, error) {
+	after, first := cursorFromArgs(p.Args, 20)
+	return r.userService.ListAfter(p.Context, after, first)
+}
+
 func (r *Resolver) resolveUserByEmail(p graphql.ResolveParams) (any, error) {
 	email, ok := p.Args["email"].(string)
 	if !ok {
@@ -109,27 +577,9 @@ func (r *Resolver) resolveCurrentUser(p graphql.ResolveParams) (any, error) {
 
 // User Mutation Resolvers
 func (r *Resolver) resolveCreateUser(p graphql.ResolveParams) (any, error) {
-	input, ok := p.Args["input"].(map[string]any)
-	if !ok {
-		return nil, errors.New("input is required")
-	}
-
 	createDTO := dto.CreateUserDTO{}
-
-	if email, ok := input["email"].(string); ok {
-		createDTO.Email = email
-	}
-	if clerkId, ok := input["clerkId"].(string); ok {
-		createDTO.ClerkID = &clerkId
-	}
-	if firstName, ok := input["firstName"].(string); ok {
-		createDTO.FirstName = firstName
-	}
-	if lastName, ok := input["lastName"].(string); ok {
-		createDTO.LastName = lastName
-	}
-	if phone, ok := input["phone"].(string); ok {
-		createDTO.Phone = &phone
+	if err := decodeInput(p.Args, &createDTO); err != nil {
+		return nil, err
 	}
 
 	user, err := r.userService.Create(p.Context, createDTO)
@@ -146,24 +596,9 @@ func (r *Resolver) resolveUpdateUser(p graphql.ResolveParams) (any, error) {
 		return nil, errors.New("id is required")
 	}
 
-	input, ok := p.Args["input"].(map[string]any)
-	if !ok {
-		return nil, errors.New("input is required")
-	}
-
 	updateDTO := dto.UpdateUserDTO{}
-
-	if firstName, ok := input["firstName"].(string); ok {
-		updateDTO.FirstName = &firstName
-	}
-	if lastName, ok := input["lastName"].(string); ok {
-		updateDTO.LastName = &lastName
-	}
-	if phone, ok := input["phone"].(string); ok {
-		updateDTO.Phone = &phone
-	}
-	if isActive, ok := input["isActive"].(bool); ok {
-		updateDTO.IsActive = &isActive
+	if err := decodeInput(p.Args, &updateDTO); err != nil {
+		return nil, err
 	}
 
 	user, err := r.userService.Update(p.Context, id, updateDTO)
@@ -189,4 +624,4 @@ func (r *Resolver) resolveDeleteUser(p graphql.ResolveParams) (any, error) {
 		"success": true,
 		"message": "User deleted successfully",
 	}, nil
-}
\ No newline at end of file
+}
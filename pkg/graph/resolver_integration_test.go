@@ -6,6 +6,7 @@ package graph_test
 import (
 	"testing"
 
+	"github.com/assimoes/beautix/internal/dto"
 	"github.com/assimoes/beautix/internal/repository"
 	"github.com/assimoes/beautix/internal/service"
 	"github.com/assimoes/beautix/pkg/graph"
@@ -28,6 +29,7 @@ func TestUserQueriesIntegration(t *testing.T) {
 
 	// Create validator
 	validate := validator.New()
+	dto.RegisterOptionalTypes(validate)
 
 	// Create repositories
 	userRepo := repository.NewUserRepository(gormDB)
@@ -201,6 +203,7 @@ func TestUserMutationsIntegration(t *testing.T) {
 
 	// Create validator
 	validate := validator.New()
+	dto.RegisterOptionalTypes(validate)
 
 	// Create repositories
 	userRepo := repository.NewUserRepository(gormDB)
@@ -354,6 +357,7 @@ func TestErrorHandlingIntegration(t *testing.T) {
 
 	// Create validator
 	validate := validator.New()
+	dto.RegisterOptionalTypes(validate)
 
 	// Create repositories
 	userRepo := repository.NewUserRepository(gormDB)
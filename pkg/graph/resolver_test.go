@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/assimoes/beautix/internal/domain"
 	"github.com/assimoes/beautix/internal/dto"
 	"github.com/assimoes/beautix/internal/service"
 )
@@ -58,9 +59,7 @@ func (m *mockUserService) Update(ctx context.Context, id string, updateDTO dto.U
 	if updateDTO.LastName != nil {
 		user.LastName = *updateDTO.LastName
 	}
-	if updateDTO.Phone != nil {
-		user.Phone = updateDTO.Phone
-	}
+	updateDTO.Phone.Apply(&user.Phone)
 	if updateDTO.IsActive != nil {
 		user.IsActive = *updateDTO.IsActive
 	}
@@ -85,6 +84,14 @@ func (m *mockUserService) List(ctx context.Context, page, pageSize int) ([]*dto.
 	return users, int64(len(users)), nil
 }
 
+func (m *mockUserService) ListAfter(ctx context.Context, after string, first int) (*domain.Connection[dto.UserResponseDTO], error) {
+	connection := &domain.Connection[dto.UserResponseDTO]{}
+	for _, user := range m.users {
+		connection.Edges = append(connection.Edges, &domain.Edge[dto.UserResponseDTO]{Cursor: user.ID, Node: user})
+	}
+	return connection, nil
+}
+
 func (m *mockUserService) GetByEmail(ctx context.Context, email string) (*dto.UserResponseDTO, error) {
 	for _, user := range m.users {
 		if user.Email == email {
@@ -148,6 +155,10 @@ func (m *mockUserService) DeactivateUser(ctx context.Context, userID string) err
 	return service.NewNotFoundError("user", "id", userID)
 }
 
+func (m *mockUserService) PreferredLocale(ctx context.Context) (string, error) {
+	return "", nil
+}
+
 type mockAuthService struct{}
 
 func (m *mockAuthService) RegisterWithClerk(ctx context.Context, clerkUserData dto.ClerkUserDTO) (*dto.UserResponseDTO, error) {
@@ -297,6 +308,48 @@ func TestGraphQLUserQueries(t *testing.T) {
 		assert.Equal(t, "test-user-1", user["id"])
 	})
 
+	t.Run("Query users connection", func(t *testing.T) {
+		query := `
+			query {
+				usersConnection(first: 10) {
+					edges {
+						cursor
+						node {
+							id
+							email
+						}
+					}
+					pageInfo {
+						hasNextPage
+						endCursor
+					}
+				}
+			}
+		`
+
+		result := graphql.Do(graphql.Params{
+			Schema:        schema,
+			RequestString: query,
+			Context:       context.Background(),
+		})
+
+		require.Empty(t, result.Errors)
+
+		data, ok := result.Data.(map[string]interface{})
+		require.True(t, ok)
+
+		connection, ok := data["usersConnection"].(map[string]interface{})
+		require.True(t, ok)
+		edges, ok := connection["edges"].([]interface{})
+		require.True(t, ok)
+		require.Len(t, edges, 1)
+
+		edge := edges[0].(map[string]interface{})
+		assert.Equal(t, "test-user-1", edge["cursor"])
+		assert.Equal(t, "test-user-1", edge["node"].(map[string]interface{})["id"])
+		assert.Equal(t, false, connection["pageInfo"].(map[string]interface{})["hasNextPage"])
+	})
+
 	t.Run("Search users", func(t *testing.T) {
 		query := `
 			query {
@@ -423,6 +476,50 @@ func TestGraphQLUserMutations(t *testing.T) {
 		assert.Equal(t, "Updated User", user["fullName"])
 	})
 
+	t.Run("Clear user phone", func(t *testing.T) {
+		createResult := graphql.Do(graphql.Params{
+			Schema: schema,
+			RequestString: `
+				mutation {
+					createUser(input: {
+						email: "clear@example.com"
+						firstName: "Clear"
+						lastName: "Test"
+						phone: "+351912345678"
+					}) {
+						id
+						phone
+					}
+				}
+			`,
+			Context: context.Background(),
+		})
+		require.Empty(t, createResult.Errors)
+		created := createResult.Data.(map[string]interface{})["createUser"].(map[string]interface{})
+		require.Equal(t, "+351912345678", created["phone"])
+
+		result := graphql.Do(graphql.Params{
+			Schema: schema,
+			RequestString: `
+				mutation {
+					updateUser(id: "` + created["id"].(string) + `", input: {
+						firstName: "Cleared"
+						clear: [PHONE]
+					}) {
+						firstName
+						phone
+					}
+				}
+			`,
+			Context: context.Background(),
+		})
+		require.Empty(t, result.Errors)
+
+		user := result.Data.(map[string]interface{})["updateUser"].(map[string]interface{})
+		assert.Equal(t, "Cleared", user["firstName"])
+		assert.Nil(t, user["phone"])
+	})
+
 	t.Run("Delete user", func(t *testing.T) {
 		// First create a user
 		createMutation := `
@@ -0,0 +1,101 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Resource Query Resolvers
+func (r *Resolver) resolveResources(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+
+	resources, err := r.resourceService.GetResources(p.Context, businessID, optionalString(p.Args, "locationId"))
+	if err != nil {
+		return nil, err
+	}
+
+	return resources, nil
+}
+
+func (r *Resolver) resolveServiceResources(p graphql.ResolveParams) (any, error) {
+	serviceID, ok := p.Args["serviceId"].(string)
+	if !ok {
+		return nil, errors.New("serviceId is required")
+	}
+
+	resources, err := r.resourceService.GetServiceResources(p.Context, serviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	return resources, nil
+}
+
+// Resource Mutation Resolvers
+func (r *Resolver) resolveCreateResource(p graphql.ResolveParams) (any, error) {
+	createDTO := dto.CreateResourceDTO{}
+	if err := decodeInput(p.Args, &createDTO); err != nil {
+		return nil, err
+	}
+
+	resource, err := r.resourceService.CreateResource(p.Context, createDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return resource, nil
+}
+
+func (r *Resolver) resolveUpdateResource(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+	updateDTO := dto.UpdateResourceDTO{}
+	if err := decodeInput(p.Args, &updateDTO); err != nil {
+		return nil, err
+	}
+
+	resource, err := r.resourceService.UpdateResource(p.Context, id, updateDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return resource, nil
+}
+
+func (r *Resolver) resolveDeleteResource(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+
+	if err := r.resourceService.DeleteResource(p.Context, id); err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		"success": true,
+		"message": "Resource deleted successfully",
+	}, nil
+}
+
+func (r *Resolver) resolveSetServiceResources(p graphql.ResolveParams) (any, error) {
+	setDTO := dto.SetServiceResourcesDTO{}
+	if err := decodeInput(p.Args, &setDTO); err != nil {
+		return nil, err
+	}
+
+	resources, err := r.resourceService.SetServiceResources(p.Context, setDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return resources, nil
+}
@@ -0,0 +1,250 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// ResourceKindEnum represents the GraphQL enum for what a bookable resource is
+var ResourceKindEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "ResourceKind",
+	Description: "What a bookable resource is",
+	Values: graphql.EnumValueConfigMap{
+		"ROOM": &graphql.EnumValueConfig{
+			Value:       domain.ResourceRoom,
+			Description: "A treatment room",
+		},
+		"CHAIR": &graphql.EnumValueConfig{
+			Value:       domain.ResourceChair,
+			Description: "A chair or station",
+		},
+		"EQUIPMENT": &graphql.EnumValueConfig{
+			Value:       domain.ResourceEquipment,
+			Description: "A machine, such as a laser",
+		},
+	},
+})
+
+// ResourceType represents the GraphQL Resource type
+var ResourceType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Resource",
+	Description: "A room, chair or piece of equipment that appointments for the services needing it occupy",
+	Fields: withBaseFields("resource", graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"locationId": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The ID of the location the resource is at",
+		},
+		"name": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The name of the resource",
+		},
+		"kind": &graphql.Field{
+			Type:        graphql.NewNonNull(ResourceKindEnum),
+			Description: "What the resource is",
+		},
+		"capacity": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "How many appointments the resource can hold at once",
+		},
+		"isActive": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether the resource holds up bookings; inactive resources are ignored",
+		},
+		"notes": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Notes on the resource",
+		},
+	}),
+})
+
+// ServiceResourcesType represents the GraphQL ServiceResources type
+var ServiceResourcesType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ServiceResources",
+	Description: "The resources every appointment for a service occupies",
+	Fields: graphql.Fields{
+		"serviceId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the service",
+		},
+		"resources": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(ResourceType))),
+			Description: "The active resources the service needs, by name",
+		},
+	},
+})
+
+// CreateResourceInput represents the GraphQL input for adding a resource
+var CreateResourceInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "CreateResourceInput",
+	Description: "Input for adding a room, chair or piece of equipment",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"businessId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"locationId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The ID of the location the resource is at",
+		},
+		"name": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The name of the resource",
+		},
+		"kind": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(ResourceKindEnum),
+			Description: "What the resource is",
+		},
+		"capacity": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Int,
+			Description: "How many appointments the resource can hold at once; 1 when left out",
+		},
+		"notes": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "Notes on the resource",
+		},
+	},
+})
+
+// UpdateResourceInput represents the GraphQL input for changing a resource
+var UpdateResourceInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "UpdateResourceInput",
+	Description: "Input for changing a resource; fields left out are unchanged",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"locationId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The ID of the location the resource is at",
+		},
+		"name": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The name of the resource",
+		},
+		"kind": &graphql.InputObjectFieldConfig{
+			Type:        ResourceKindEnum,
+			Description: "What the resource is",
+		},
+		"capacity": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Int,
+			Description: "How many appointments the resource can hold at once",
+		},
+		"isActive": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Boolean,
+			Description: "Whether the resource holds up bookings",
+		},
+		"notes": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "Notes on the resource",
+		},
+		"expectedVersion": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Int,
+			Description: "The version of the resource the change is based on; fails with VERSION_CONFLICT when someone else changed it since",
+		},
+		"clear": clearField("UpdateResourceInput", "locationId", "notes"),
+	},
+})
+
+// SetServiceResourcesInput represents the GraphQL input for the resources a service needs
+var SetServiceResourcesInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "SetServiceResourcesInput",
+	Description: "Input for replacing the resources every appointment for a service occupies",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"serviceId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the service",
+		},
+		"resourceIds": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+			Description: "The IDs of the resources; empty for services that need none",
+		},
+	},
+})
+
+// resourceQueryFields returns the resource queries
+func resourceQueryFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"resources": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(ResourceType))),
+			Description: "Get the rooms, chairs and equipment of a business by name (staff of the business only)",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+				"locationId": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: "Only the resources at this location",
+				},
+			},
+			Resolve: resolver.resolveResources,
+		},
+		"serviceResources": &graphql.Field{
+			Type:        ServiceResourcesType,
+			Description: "Get the resources every appointment for a service occupies (staff of the business only)",
+			Args: graphql.FieldConfigArgument{
+				"serviceId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the service",
+				},
+			},
+			Resolve: resolver.resolveServiceResources,
+		},
+	}
+}
+
+// resourceMutationFields returns the resource mutations
+func resourceMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"createResource": &graphql.Field{
+			Type:        ResourceType,
+			Description: "Add a room, chair or piece of equipment (owners and managers)",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(CreateResourceInput),
+					Description: "The resource to add",
+				},
+			},
+			Resolve: resolver.resolveCreateResource,
+		},
+		"updateResource": &graphql.Field{
+			Type:        ResourceType,
+			Description: "Change a resource; appointments already booked are kept (owners and managers)",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the resource",
+				},
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(UpdateResourceInput),
+					Description: "The changes to the resource",
+				},
+			},
+			Resolve: resolver.resolveUpdateResource,
+		},
+		"deleteResource": &graphql.Field{
+			Type:        DeleteResultType,
+			Description: "Remove a resource, which then no longer holds up bookings (owners and managers)",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the resource",
+				},
+			},
+			Resolve: resolver.resolveDeleteResource,
+		},
+		"setServiceResources": &graphql.Field{
+			Type:        ServiceResourcesType,
+			Description: "Replace the resources every appointment for a service occupies; bookings are then rejected while any of them is fully in use (owners and managers)",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(SetServiceResourcesInput),
+					Description: "The service and its resources",
+				},
+			},
+			Resolve: resolver.resolveSetServiceResources,
+		},
+	}
+}
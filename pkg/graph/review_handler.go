@@ -0,0 +1,105 @@
+package graph
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/assimoes/beautix/internal/dto"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/assimoes/beautix/internal/service"
+)
+
+// reviewPageData is rendered by reviewTemplate
+type reviewPageData struct {
+	*dto.ReviewFormDTO
+	When string
+	Sent bool
+}
+
+// reviewTemplate renders the page a client lands on from a review link
+var reviewTemplate = template.Must(template.New("review").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+    <title>{{.BusinessName}}</title>
+    <style>
+        body { font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; margin: 40px auto; max-width: 480px; padding: 0 16px; color: #333; text-align: center; }
+        select, textarea { font-size: 16px; padding: 8px; margin-bottom: 16px; width: 100%; box-sizing: border-box; }
+        button { font-size: 18px; padding: 12px 32px; border: none; border-radius: 6px; background: #333; color: #fff; cursor: pointer; }
+    </style>
+</head>
+<body>
+    <h1>{{.BusinessName}}</h1>
+    {{if .Sent}}
+    <p>Thank you for your review!</p>
+    {{else}}
+    <p>How was your {{.ServiceName}} with {{.StaffName}} on {{.When}}?</p>
+    <form method="POST">
+        <select name="rating" required>
+            <option value="">Choose a rating</option>
+            <option value="5">5 - Excellent</option>
+            <option value="4">4 - Good</option>
+            <option value="3">3 - Okay</option>
+            <option value="2">2 - Poor</option>
+            <option value="1">1 - Very poor</option>
+        </select><br>
+        <textarea name="comment" rows="6" maxlength="2000" placeholder="Tell others about your visit (optional)"></textarea><br>
+        <button type="submit">Leave review</button>
+    </form>
+    {{end}}
+</body>
+</html>
+`))
+
+// ReviewHandler serves the page a client opens from a review link. Opening the page shows the
+// form; submitting a rating and an optional comment leaves the review. It must be registered
+// under service.ReviewLinkPath.
+func ReviewHandler(reviewService service.ReviewService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := r.URL.Path[len(service.ReviewLinkPath):]
+
+		var form *dto.ReviewFormDTO
+		var err error
+		switch r.Method {
+		case http.MethodGet:
+			form, err = reviewService.GetReviewForm(r.Context(), token)
+		case http.MethodPost:
+			form, err = reviewService.GetReviewForm(r.Context(), token)
+			if err == nil {
+				submitDTO := dto.SubmitReviewDTO{Token: token, Comment: r.PostFormValue("comment")}
+				submitDTO.Rating, _ = strconv.Atoi(r.PostFormValue("rating"))
+				err = reviewService.SubmitReview(r.Context(), submitDTO)
+			}
+		default:
+			http.Error(w, "Only GET and POST requests are allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if err != nil {
+			var notFound service.NotFoundError
+			var invalid *validation.ValidationError
+			switch {
+			case errors.As(err, &notFound):
+				http.Error(w, "This review link is not valid", http.StatusNotFound)
+			case errors.As(err, &invalid):
+				http.Error(w, invalid.Error(), http.StatusConflict)
+			default:
+				http.Error(w, "Failed to leave review", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		location, err := time.LoadLocation(form.TimeZone)
+		if err != nil {
+			location = time.UTC
+		}
+		data := reviewPageData{ReviewFormDTO: form, When: form.StartTime.In(location).Format("Monday 2 January"), Sent: r.Method == http.MethodPost}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := reviewTemplate.Execute(w, data); err != nil {
+			http.Error(w, "Failed to render page", http.StatusInternalServerError)
+		}
+	}
+}
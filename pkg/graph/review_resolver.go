@@ -0,0 +1,74 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Review Query Resolvers
+func (r *Resolver) resolveReviews(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+	page, pageSize := pageFromArgs(p.Args, 20)
+
+	listDTO := dto.ListReviewsDTO{
+		BusinessID: businessID,
+		StaffID:    optionalString(p.Args, "staffId"),
+		Page:       page,
+		PageSize:   pageSize,
+	}
+	if status, ok := p.Args["status"].(domain.ReviewStatus); ok {
+		listDTO.Status = &status
+	}
+
+	reviews, _, err := r.reviewService.ListReviews(p.Context, listDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return reviews, nil
+}
+
+// Review Mutation Resolvers
+func (r *Resolver) resolveModerateReview(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+	publish, ok := p.Args["publish"].(bool)
+	if !ok {
+		return nil, errors.New("publish is required")
+	}
+
+	review, err := r.reviewService.ModerateReview(p.Context, dto.ModerateReviewDTO{
+		ReviewID: id,
+		Publish:  publish,
+		Note:     optionalString(p.Args, "note"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return review, nil
+}
+
+func (r *Resolver) resolveReplyToReview(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+	reply, _ := p.Args["reply"].(string)
+
+	review, err := r.reviewService.ReplyToReview(p.Context, dto.ReplyToReviewDTO{ReviewID: id, Reply: reply})
+	if err != nil {
+		return nil, err
+	}
+
+	return review, nil
+}
@@ -0,0 +1,149 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// ReviewStatusEnum represents the GraphQL enum for where a review is in moderation
+var ReviewStatusEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "ReviewStatus",
+	Description: "Where a review is in moderation",
+	Values: graphql.EnumValueConfigMap{
+		"PENDING": &graphql.EnumValueConfig{
+			Value:       domain.ReviewPending,
+			Description: "Has a comment the business is yet to publish or reject",
+		},
+		"PUBLISHED": &graphql.EnumValueConfig{
+			Value:       domain.ReviewPublished,
+			Description: "Shown publicly, and counted in the ratings",
+		},
+		"REJECTED": &graphql.EnumValueConfig{
+			Value:       domain.ReviewRejected,
+			Description: "Hidden by the business, and not counted in the ratings",
+		},
+		"FLAGGED": &graphql.EnumValueConfig{
+			Value:       domain.ReviewFlagged,
+			Description: "Held back by the content filter, with its reply, until the business publishes or rejects it",
+		},
+	},
+})
+
+// ReviewType represents the GraphQL Review type
+var ReviewType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Review",
+	Description: "A client's review of a completed appointment",
+	Fields: withBaseFields("review", graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"appointmentId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the reviewed appointment",
+		},
+		"staffId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the staff member who carried out the appointment",
+		},
+		"serviceId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the first service of the appointment",
+		},
+		"rating": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The rating, 1 to 5 stars",
+		},
+		"comment": &graphql.Field{
+			Type:        graphql.String,
+			Description: "What the client wrote",
+		},
+		"status": &graphql.Field{
+			Type:        graphql.NewNonNull(ReviewStatusEnum),
+			Description: "Where the review is in moderation",
+		},
+		"reply": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The business's public reply",
+		},
+		"repliedAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the business replied",
+		},
+		"moderatedAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the review was last published or rejected; only given to staff who may moderate reviews",
+		},
+		"moderationNote": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Why the review was rejected; only given to staff who may moderate reviews",
+		},
+	}),
+})
+
+// reviewQueryFields returns the review queries
+func reviewQueryFields(resolver *Resolver) graphql.Fields {
+	args := paginationArgs("reviews", 20)
+	args["businessId"] = &graphql.ArgumentConfig{
+		Type:        graphql.NewNonNull(graphql.String),
+		Description: "The ID of the business",
+	}
+	args["status"] = &graphql.ArgumentConfig{
+		Type:        ReviewStatusEnum,
+		Description: "Only reviews in this state; only published reviews are listed to those who may not moderate them",
+	}
+	args["staffId"] = &graphql.ArgumentConfig{
+		Type:        graphql.String,
+		Description: "Only reviews of this staff member's appointments",
+	}
+
+	return graphql.Fields{
+		"reviews": &graphql.Field{
+			Type:        graphql.NewList(ReviewType),
+			Description: "Get the reviews of a business, newest first",
+			Args:        args,
+			Resolve:     resolver.resolveReviews,
+		},
+	}
+}
+
+// reviewMutationFields returns the review mutations
+func reviewMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"moderateReview": &graphql.Field{
+			Type:        ReviewType,
+			Description: "Publish or reject a review, updating the ratings of the staff member and the business",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the review",
+				},
+				"publish": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.Boolean),
+					Description: "Whether to publish the review; false rejects it",
+				},
+				"note": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: "Why the review was rejected, for the business's own records",
+				},
+			},
+			Resolve: resolver.resolveModerateReview,
+		},
+		"replyToReview": &graphql.Field{
+			Type:        ReviewType,
+			Description: "Set the business's public reply to a review, or remove it with an empty reply",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the review",
+				},
+				"reply": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The reply, of at most 2000 characters",
+				},
+			},
+			Resolve: resolver.resolveReplyToReview,
+		},
+	}
+}
@@ -0,0 +1,26 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+)
+
+// Run Sheet Query Resolvers
+func (r *Resolver) resolveDailyRunSheet(p graphql.ResolveParams) (any, error) {
+	staffID, ok := p.Args["staffId"].(string)
+	if !ok {
+		return nil, errors.New("staffId is required")
+	}
+	date, ok := p.Args["date"].(string)
+	if !ok {
+		return nil, errors.New("date is required")
+	}
+
+	sheet, err := r.runSheetService.GetDailyRunSheet(p.Context, staffID, date)
+	if err != nil {
+		return nil, err
+	}
+
+	return sheet, nil
+}
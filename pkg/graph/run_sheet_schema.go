@@ -0,0 +1,75 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+)
+
+// RunSheetEntryType represents the GraphQL RunSheetEntry type
+var RunSheetEntryType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "RunSheetEntry",
+	Description: "An appointment on a run sheet, with what the staff member needs to know about the client",
+	Fields: graphql.Fields{
+		"appointment": &graphql.Field{
+			Type:        graphql.NewNonNull(CalendarEntryType),
+			Description: "The appointment, with its client, services and notes",
+		},
+		"allergies": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The client's allergies",
+		},
+		"clientNotes": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The notes kept about the client",
+		},
+		"preferences": &graphql.Field{
+			Type:        graphql.NewNonNull(JSONScalar),
+			Description: "The client's recorded preferences",
+		},
+		"lastRecord": &graphql.Field{
+			Type:        ServiceRecordType,
+			Description: "The client's latest service record, such as the formula used last time",
+		},
+	},
+})
+
+// RunSheetType represents the GraphQL RunSheet type
+var RunSheetType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "RunSheet",
+	Description: "A staff member's appointments of a day, laid out for printing or a tablet at the station",
+	Fields: graphql.Fields{
+		"staffId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the staff member",
+		},
+		"date": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "The local date of the sheet",
+		},
+		"appointments": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(RunSheetEntryType))),
+			Description: "The appointments in chronological order; cancelled and rescheduled ones are left off",
+		},
+	},
+})
+
+// runSheetQueryFields returns the run sheet queries
+func runSheetQueryFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"dailyRunSheet": &graphql.Field{
+			Type: RunSheetType,
+			Description: "A staff member's appointments of a day with each client's preferences, allergies, notes and " +
+				"latest formula in one payload (the staff member, or owners and managers)",
+			Args: graphql.FieldConfigArgument{
+				"staffId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the staff member",
+				},
+				"date": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The date, as YYYY-MM-DD in the business's time zone",
+				},
+			},
+			Resolve: resolver.resolveDailyRunSheet,
+		},
+	}
+}
@@ -0,0 +1,92 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Saved View Query Resolvers
+func (r *Resolver) resolveSavedViews(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+	var entity *domain.SavedViewEntity
+	if value, ok := p.Args["entity"].(domain.SavedViewEntity); ok {
+		entity = &value
+	}
+
+	views, err := r.savedViewService.ListSavedViews(p.Context, businessID, entity)
+	if err != nil {
+		return nil, err
+	}
+
+	return views, nil
+}
+
+func (r *Resolver) resolveSavedView(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+
+	view, err := r.savedViewService.GetSavedView(p.Context, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return view, nil
+}
+
+// Saved View Mutation Resolvers
+func (r *Resolver) resolveCreateSavedView(p graphql.ResolveParams) (any, error) {
+	createDTO := dto.CreateSavedViewDTO{}
+	if err := decodeInput(p.Args, &createDTO); err != nil {
+		return nil, err
+	}
+
+	view, err := r.savedViewService.CreateSavedView(p.Context, createDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return view, nil
+}
+
+func (r *Resolver) resolveUpdateSavedView(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+	updateDTO := dto.UpdateSavedViewDTO{}
+	if err := decodeInput(p.Args, &updateDTO); err != nil {
+		return nil, err
+	}
+
+	view, err := r.savedViewService.UpdateSavedView(p.Context, id, updateDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return view, nil
+}
+
+func (r *Resolver) resolveDeleteSavedView(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+
+	if err := r.savedViewService.DeleteSavedView(p.Context, id); err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		"success": true,
+		"message": "Saved view deleted successfully",
+	}, nil
+}
@@ -0,0 +1,299 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// SavedViewEntityEnum represents the GraphQL enum for the lists saved views filter
+var SavedViewEntityEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "SavedViewEntity",
+	Description: "The list a saved view filters",
+	Values: graphql.EnumValueConfigMap{
+		"CLIENTS": &graphql.EnumValueConfig{
+			Value:       domain.SavedViewClients,
+			Description: "The clients of the business",
+		},
+		"APPOINTMENTS": &graphql.EnumValueConfig{
+			Value:       domain.SavedViewAppointments,
+			Description: "The appointments of the business",
+		},
+	},
+})
+
+// SavedViewFiltersType represents the GraphQL SavedViewFilters type
+var SavedViewFiltersType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "SavedViewFilters",
+	Description: "The criteria of a saved view; every criterion set must hold",
+	Fields: graphql.Fields{
+		"search": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Matched against names, email addresses and phone numbers",
+		},
+		"audience": &graphql.Field{
+			Type:        TargetAudienceType,
+			Description: "The criteria selecting clients, as for campaigns (client views only)",
+		},
+		"statuses": &graphql.Field{
+			Type:        graphql.NewList(graphql.NewNonNull(AppointmentStatusEnum)),
+			Description: "Appointments in any of the statuses",
+		},
+		"staffIds": &graphql.Field{
+			Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+			Description: "Appointments with any of the staff members",
+		},
+		"myAppointments": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether only the appointments of the staff member using the view are shown",
+		},
+		"confirmed": &graphql.Field{
+			Type:        graphql.Boolean,
+			Description: "Whether the client confirmed the appointments",
+		},
+		"fromDay": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "The first day of the appointments, in days from today (1 for tomorrow)",
+		},
+		"toDay": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "The last day of the appointments, in days from today",
+		},
+	},
+})
+
+// SavedViewType represents the GraphQL SavedView type
+var SavedViewType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "SavedView",
+	Description: "A named filter and sort of the clients or appointments of a business, saved by a user",
+	Fields: withBaseFields("saved view", graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"userId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the user who owns the view",
+		},
+		"name": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The name of the view",
+		},
+		"entity": &graphql.Field{
+			Type:        graphql.NewNonNull(SavedViewEntityEnum),
+			Description: "The list the view filters",
+		},
+		"filters": &graphql.Field{
+			Type:        graphql.NewNonNull(SavedViewFiltersType),
+			Description: "The criteria of the view",
+		},
+		"sortField": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The field the list is sorted by",
+		},
+		"sortDescending": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether the list is sorted in descending order",
+		},
+		"sharedWithRoles": &graphql.Field{
+			Type:        graphql.NewList(graphql.NewNonNull(BusinessRoleEnum)),
+			Description: "The roles whose staff can use the view; empty when it is private",
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				view, ok := p.Source.(*dto.SavedViewResponseDTO)
+				if !ok {
+					return nil, nil
+				}
+				roles := make([]string, len(view.SharedWithRoles))
+				for i, role := range view.SharedWithRoles {
+					roles[i] = string(role)
+				}
+				return roles, nil
+			},
+		},
+		"isOwner": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether the caller owns the view and can change it",
+		},
+	}),
+})
+
+// SavedViewFiltersInput represents the GraphQL input for the criteria of a saved view
+var SavedViewFiltersInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "SavedViewFiltersInput",
+	Description: "The criteria of a saved view; every criterion set must hold",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"search": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "Matched against names, email addresses and phone numbers",
+		},
+		"audience": &graphql.InputObjectFieldConfig{
+			Type:        TargetAudienceInput,
+			Description: "The criteria selecting clients, as for campaigns (client views only)",
+		},
+		"statuses": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewList(graphql.NewNonNull(AppointmentStatusEnum)),
+			Description: "Appointments in any of the statuses (appointment views only)",
+		},
+		"staffIds": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+			Description: "Appointments with any of the staff members (appointment views only)",
+		},
+		"myAppointments": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Boolean,
+			Description: "Only the appointments of the staff member using the view (appointment views only)",
+		},
+		"confirmed": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Boolean,
+			Description: "Whether the client confirmed the appointments (appointment views only)",
+		},
+		"fromDay": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Int,
+			Description: "The first day of the appointments, in days from today (1 for tomorrow)",
+		},
+		"toDay": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Int,
+			Description: "The last day of the appointments, in days from today",
+		},
+	},
+})
+
+// CreateSavedViewInput represents the GraphQL input for saving a view
+var CreateSavedViewInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "CreateSavedViewInput",
+	Description: "Input for saving a view of the clients or appointments of a business",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"businessId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"name": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The name of the view, unique among the caller's views of the list",
+		},
+		"entity": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(SavedViewEntityEnum),
+			Description: "The list the view filters",
+		},
+		"filters": &graphql.InputObjectFieldConfig{
+			Type:        SavedViewFiltersInput,
+			Description: "The criteria of the view",
+		},
+		"sortField": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The field to sort by: name, created_at, last_visit, total_visits or total_spent for clients; start_time, created_at or status for appointments",
+		},
+		"sortDescending": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Boolean,
+			Description: "Whether to sort in descending order",
+		},
+		"sharedWithRoles": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewList(graphql.NewNonNull(BusinessRoleEnum)),
+			Description: "The roles whose staff can use the view (managers only)",
+		},
+	},
+})
+
+// UpdateSavedViewInput represents the GraphQL input for changing a saved view
+var UpdateSavedViewInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "UpdateSavedViewInput",
+	Description: "Input for changing a saved view; absent fields are left as they are",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"name": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The name of the view",
+		},
+		"filters": &graphql.InputObjectFieldConfig{
+			Type:        SavedViewFiltersInput,
+			Description: "The criteria of the view, replacing every criterion",
+		},
+		"sortField": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The field to sort by",
+		},
+		"sortDescending": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Boolean,
+			Description: "Whether to sort in descending order",
+		},
+		"sharedWithRoles": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewList(graphql.NewNonNull(BusinessRoleEnum)),
+			Description: "The roles whose staff can use the view (managers only); an empty list makes it private",
+		},
+		"clear": clearField("UpdateSavedViewInput", "sortField"),
+	},
+})
+
+// savedViewQueryFields returns the saved view queries
+func savedViewQueryFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"savedViews": &graphql.Field{
+			Type:        graphql.NewList(SavedViewType),
+			Description: "Get the saved views of a business the caller can use: their own and those shared with their role, ordered by name",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+				"entity": &graphql.ArgumentConfig{
+					Type:        SavedViewEntityEnum,
+					Description: "Only views of this list",
+				},
+			},
+			Resolve: resolver.resolveSavedViews,
+		},
+		"savedView": &graphql.Field{
+			Type:        SavedViewType,
+			Description: "Get a saved view by ID",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the saved view",
+				},
+			},
+			Resolve: resolver.resolveSavedView,
+		},
+	}
+}
+
+// savedViewMutationFields returns the saved view mutations
+func savedViewMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"createSavedView": &graphql.Field{
+			Type:        SavedViewType,
+			Description: "Save a view of the clients or appointments of a business for the caller",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(CreateSavedViewInput),
+					Description: "The view to save",
+				},
+			},
+			Resolve: resolver.resolveCreateSavedView,
+		},
+		"updateSavedView": &graphql.Field{
+			Type:        SavedViewType,
+			Description: "Change a saved view (owner only)",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the saved view",
+				},
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(UpdateSavedViewInput),
+					Description: "The changes to the view",
+				},
+			},
+			Resolve: resolver.resolveUpdateSavedView,
+		},
+		"deleteSavedView": &graphql.Field{
+			Type:        DeleteResultType,
+			Description: "Delete a saved view (owner only)",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the saved view",
+				},
+			},
+			Resolve: resolver.resolveDeleteSavedView,
+		},
+	}
+}
@@ -0,0 +1,107 @@
+package graph
+
+import (
+	"strconv"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/shopspring/decimal"
+)
+
+// DecimalScalar represents exact decimal amounts such as prices and rates, serialized as strings
+var DecimalScalar = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "Decimal",
+	Description: "An exact decimal number, serialized as a string to preserve precision",
+	Serialize: func(value any) any {
+		switch v := value.(type) {
+		case decimal.Decimal:
+			return v.String()
+		case *decimal.Decimal:
+			if v == nil {
+				return nil
+			}
+			return v.String()
+		default:
+			return nil
+		}
+	},
+	ParseValue: func(value any) any {
+		switch v := value.(type) {
+		case string:
+			if d, err := decimal.NewFromString(v); err == nil {
+				return d
+			}
+		case float64:
+			return decimal.NewFromFloat(v)
+		case int:
+			return decimal.NewFromInt(int64(v))
+		}
+		return nil
+	},
+	ParseLiteral: func(valueAST ast.Value) any {
+		switch v := valueAST.(type) {
+		case *ast.StringValue:
+			if d, err := decimal.NewFromString(v.Value); err == nil {
+				return d
+			}
+		case *ast.IntValue:
+			if d, err := decimal.NewFromString(v.Value); err == nil {
+				return d
+			}
+		case *ast.FloatValue:
+			if d, err := decimal.NewFromString(v.Value); err == nil {
+				return d
+			}
+		}
+		return nil
+	},
+})
+
+// JSONScalar represents arbitrary JSON values such as templated service record fields
+var JSONScalar = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "JSON",
+	Description: "An arbitrary JSON value",
+	Serialize: func(value any) any {
+		return value
+	},
+	ParseValue: func(value any) any {
+		return value
+	},
+	ParseLiteral: parseJSONLiteral,
+})
+
+// parseJSONLiteral converts an inline GraphQL literal into its Go value
+func parseJSONLiteral(valueAST ast.Value) any {
+	switch value := valueAST.(type) {
+	case *ast.StringValue:
+		return value.Value
+	case *ast.BooleanValue:
+		return value.Value
+	case *ast.IntValue:
+		if number, err := strconv.ParseFloat(value.Value, 64); err == nil {
+			return number
+		}
+		return nil
+	case *ast.FloatValue:
+		if number, err := strconv.ParseFloat(value.Value, 64); err == nil {
+			return number
+		}
+		return nil
+	case *ast.EnumValue:
+		return value.Value
+	case *ast.ListValue:
+		list := make([]any, len(value.Values))
+		for i, item := range value.Values {
+			list[i] = parseJSONLiteral(item)
+		}
+		return list
+	case *ast.ObjectValue:
+		object := make(map[string]any, len(value.Fields))
+		for _, field := range value.Fields {
+			object[field.Name.Value] = parseJSONLiteral(field.Value)
+		}
+		return object
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,64 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Schedule Change Mutation Resolvers
+func (r *Resolver) resolveUpdateSchedulingSettings(p graphql.ResolveParams) (any, error) {
+	var updateDTO dto.UpdateSchedulingSettingsDTO
+	if err := decodeInput(p.Args, &updateDTO); err != nil {
+		return nil, err
+	}
+
+	result, err := r.scheduleChangeService.UpdateSchedulingSettings(p.Context, updateDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *Resolver) resolveUpdateStaffAssignment(p graphql.ResolveParams) (any, error) {
+	var updateDTO dto.UpdateStaffAssignmentDTO
+	if err := decodeInput(p.Args, &updateDTO); err != nil {
+		return nil, err
+	}
+
+	result, err := r.scheduleChangeService.UpdateStaffAssignment(p.Context, updateDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *Resolver) resolveUpdateServiceBookingWindow(p graphql.ResolveParams) (any, error) {
+	var updateDTO dto.UpdateServiceBookingWindowDTO
+	if err := decodeInput(p.Args, &updateDTO); err != nil {
+		return nil, err
+	}
+
+	window, err := r.scheduleChangeService.UpdateServiceBookingWindow(p.Context, updateDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return window, nil
+}
+
+func (r *Resolver) resolveUpdateServiceOnlineBooking(p graphql.ResolveParams) (any, error) {
+	var updateDTO dto.UpdateServiceOnlineBookingDTO
+	if err := decodeInput(p.Args, &updateDTO); err != nil {
+		return nil, err
+	}
+
+	result, err := r.scheduleChangeService.UpdateServiceOnlineBooking(p.Context, updateDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,311 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// ScheduleResolutionEnum represents the GraphQL enum for ways to resolve invalidated appointments
+var ScheduleResolutionEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "ScheduleResolution",
+	Description: "A way to resolve an appointment invalidated by a schedule change",
+	Values: graphql.EnumValueConfigMap{
+		"RESCHEDULE": &graphql.EnumValueConfig{
+			Value:       domain.ResolutionReschedule,
+			Description: "Move the appointment to a time that works under the new schedule",
+		},
+		"REASSIGN": &graphql.EnumValueConfig{
+			Value:       domain.ResolutionReassign,
+			Description: "Give the appointment to another staff member",
+		},
+		"CANCEL": &graphql.EnumValueConfig{
+			Value:       domain.ResolutionCancel,
+			Description: "Cancel the appointment and let the client know",
+		},
+	},
+})
+
+// ImpactedAppointmentType represents the GraphQL ImpactedAppointment type
+var ImpactedAppointmentType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ImpactedAppointment",
+	Description: "An upcoming appointment invalidated by a schedule change",
+	Fields: graphql.Fields{
+		"appointmentId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the appointment",
+		},
+		"staffId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the staff member taking the appointment",
+		},
+		"staff": staffRelation(func(a *dto.ImpactedAppointmentDTO) string { return a.StaffID }),
+		"startTime": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the appointment starts",
+		},
+		"endTime": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the appointment ends",
+		},
+		"conflicts": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(AvailabilityConflictType))),
+			Description: "Why the appointment no longer fits the schedule",
+		},
+		"resolutions": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(ScheduleResolutionEnum))),
+			Description: "The ways the appointment can be resolved",
+		},
+	},
+})
+
+// ScheduleChangeResultType represents the GraphQL ScheduleChangeResult type
+var ScheduleChangeResultType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ScheduleChangeResult",
+	Description: "The upcoming appointments a schedule change invalidates",
+	Fields: graphql.Fields{
+		"applied": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether the change was saved; false for dry runs",
+		},
+		"impactedAppointments": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(ImpactedAppointmentType))),
+			Description: "The appointments to reschedule, reassign or cancel",
+		},
+		"notifiedCount": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "How many notifications were sent to the owner and the affected staff members",
+		},
+	},
+})
+
+// UpdateSchedulingSettingsInput represents the GraphQL input for changing when a business takes appointments
+var UpdateSchedulingSettingsInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "UpdateSchedulingSettingsInput",
+	Description: "Input for changing the opening hours, appointment buffer, booking window or deposit window of a business",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"businessId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"businessHours": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The weekly opening hours as JSON, keyed by lowercase day name",
+		},
+		"calendarStartHour": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Int,
+			Description: "The hour the business opens on days without opening hours",
+		},
+		"calendarEndHour": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Int,
+			Description: "The hour the business closes on days without opening hours",
+		},
+		"appointmentBufferMinutes": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Int,
+			Description: "The minimum gap between a staff member's appointments",
+		},
+		"minBookingLeadHours": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Int,
+			Description: "The shortest notice, in hours, clients must give when booking; 0 allows booking until the appointment starts",
+		},
+		"maxBookingHorizonDays": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Int,
+			Description: "How many days ahead clients can book at most; 0 for no limit",
+		},
+		"depositWindowHours": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Int,
+			Description: "How many hours clients have to pay a required deposit before their appointment is cancelled; 0 keeps it booked",
+		},
+		"dryRun": &graphql.InputObjectFieldConfig{
+			Type:         graphql.Boolean,
+			Description:  "Only report the impacted appointments without saving the change",
+			DefaultValue: false,
+		},
+		"clear": clearField("UpdateSchedulingSettingsInput", "businessHours"),
+	},
+})
+
+// ServiceBookingWindowType represents the GraphQL ServiceBookingWindow type
+var ServiceBookingWindowType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ServiceBookingWindow",
+	Description: "How far ahead a service can be booked",
+	Fields: graphql.Fields{
+		"serviceId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the service",
+		},
+		"minLeadHours": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "The lead time set on the service; null when it uses the business's",
+		},
+		"maxHorizonDays": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "The horizon set on the service; null when it uses the business's",
+		},
+		"effectiveMinLeadHours": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The shortest notice, in hours, clients must give; 0 allows booking until the appointment starts",
+		},
+		"effectiveMaxHorizonDays": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "How many days ahead clients can book at most; 0 for no limit",
+		},
+	},
+})
+
+// UpdateServiceBookingWindowInput represents the GraphQL input for changing how far ahead a service can be booked
+var UpdateServiceBookingWindowInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "UpdateServiceBookingWindowInput",
+	Description: "Input for overriding the business's lead time and horizon for a service; cleared limits use the business's again",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"serviceId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the service",
+		},
+		"minLeadHours": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Int,
+			Description: "The shortest notice, in hours, clients must give; 0 allows booking until the appointment starts",
+		},
+		"maxHorizonDays": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Int,
+			Description: "How many days ahead clients can book at most; 0 for no limit",
+		},
+		"expectedVersion": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Int,
+			Description: "The version of the service the change is based on; fails with VERSION_CONFLICT when someone else changed it since",
+		},
+		"clear": clearField("UpdateServiceBookingWindowInput", "minLeadHours", "maxHorizonDays"),
+	},
+})
+
+// ServiceOnlineBookingType represents the GraphQL ServiceOnlineBooking type
+var ServiceOnlineBookingType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ServiceOnlineBooking",
+	Description: "How a service is offered online and the rules for booking it online",
+	Fields: graphql.Fields{
+		"serviceId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the service",
+		},
+		"onlineBooking": &graphql.Field{
+			Type:        graphql.NewNonNull(OnlineBookingModeEnum),
+			Description: "Whether the service is bookable online, listed for clients to call and book, or hidden",
+		},
+		"returningClientsOnly": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether new clients need a completed appointment, such as a consultation, before booking online",
+		},
+		"maxPerDay": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "How many bookings of the service a day, across staff, online booking takes; null for no limit",
+		},
+	},
+})
+
+// UpdateServiceOnlineBookingInput represents the GraphQL input for changing how a service is offered online
+var UpdateServiceOnlineBookingInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "UpdateServiceOnlineBookingInput",
+	Description: "Input for changing how a service is offered online and the rules for booking it online",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"serviceId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the service",
+		},
+		"onlineBooking": &graphql.InputObjectFieldConfig{
+			Type:        OnlineBookingModeEnum,
+			Description: "Whether the service is bookable online, listed for clients to call and book, or hidden",
+		},
+		"returningClientsOnly": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Boolean,
+			Description: "Whether new clients need a completed appointment, such as a consultation, before booking online",
+		},
+		"maxPerDay": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Int,
+			Description: "How many bookings of the service a day, across staff, online booking takes",
+		},
+		"expectedVersion": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Int,
+			Description: "The version of the service the change is based on; fails with VERSION_CONFLICT when someone else changed it since",
+		},
+		"clear": clearField("UpdateServiceOnlineBookingInput", "maxPerDay"),
+	},
+})
+
+// UpdateStaffAssignmentInput represents the GraphQL input for a staff member leaving a business
+var UpdateStaffAssignmentInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "UpdateStaffAssignmentInput",
+	Description: "Input for deactivating a staff member or setting the date they leave the business",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"staffId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the staff member",
+		},
+		"isActive": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Boolean,
+			Description: "Whether the staff member takes appointments",
+		},
+		"endDate": &graphql.InputObjectFieldConfig{
+			Type:        graphql.DateTime,
+			Description: "When the staff member leaves the business",
+		},
+		"dryRun": &graphql.InputObjectFieldConfig{
+			Type:         graphql.Boolean,
+			Description:  "Only report the impacted appointments without saving the change",
+			DefaultValue: false,
+		},
+		"clear": clearField("UpdateStaffAssignmentInput", "endDate"),
+	},
+})
+
+// scheduleChangeMutationFields returns the schedule change mutations
+func scheduleChangeMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"updateSchedulingSettings": &graphql.Field{
+			Type: ScheduleChangeResultType,
+			Description: "Change the opening hours or appointment buffer of a business, listing the upcoming appointments " +
+				"it invalidates and notifying the owner and the affected staff members",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(UpdateSchedulingSettingsInput),
+					Description: "The new scheduling settings",
+				},
+			},
+			Resolve: resolver.resolveUpdateSchedulingSettings,
+		},
+		"updateStaffAssignment": &graphql.Field{
+			Type: ScheduleChangeResultType,
+			Description: "Deactivate a staff member or set the date they leave, listing the upcoming appointments " +
+				"someone else must take and notifying the owner and the staff member",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(UpdateStaffAssignmentInput),
+					Description: "The staff member's new assignment",
+				},
+			},
+			Resolve: resolver.resolveUpdateStaffAssignment,
+		},
+		"updateServiceBookingWindow": &graphql.Field{
+			Type:        ServiceBookingWindowType,
+			Description: "Change how far ahead a service can be booked, overriding the business's lead time and horizon",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(UpdateServiceBookingWindowInput),
+					Description: "The service's booking window",
+				},
+			},
+			Resolve: resolver.resolveUpdateServiceBookingWindow,
+		},
+		"updateServiceOnlineBooking": &graphql.Field{
+			Type:        ServiceOnlineBookingType,
+			Description: "Change whether a service can be booked online and the rules for booking it online",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(UpdateServiceOnlineBookingInput),
+					Description: "How the service is offered online",
+				},
+			},
+			Resolve: resolver.resolveUpdateServiceOnlineBooking,
+		},
+	}
+}
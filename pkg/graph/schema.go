@@ -6,127 +6,226 @@ import (
 
 // CreateSchema creates the GraphQL schema
 func CreateSchema(resolver *Resolver) (graphql.Schema, error) {
-	// Define the root query type
-	rootQuery := graphql.NewObject(graphql.ObjectConfig{
-		Name: "Query",
-		Fields: graphql.Fields{
-			// User queries
-			"user": &graphql.Field{
-				Type:        UserType,
-				Description: "Get a user by ID",
-				Args: graphql.FieldConfigArgument{
-					"id": &graphql.ArgumentConfig{
-						Type:        graphql.NewNonNull(graphql.String),
-						Description: "The ID of the user to retrieve",
-					},
+	queryFields := graphql.Fields{
+		// User queries
+		"user": &graphql.Field{
+			Type:        UserType,
+			Description: "Get a user by ID",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the user to retrieve",
 				},
-				Resolve: resolver.resolveUser,
 			},
-			"users": &graphql.Field{
-				Type:        graphql.NewList(UserType),
-				Description: "Get a list of users with pagination",
-				Args: graphql.FieldConfigArgument{
-					"limit": &graphql.ArgumentConfig{
-						Type:         graphql.Int,
-						Description:  "Maximum number of users to return",
-						DefaultValue: 10,
-					},
-					"offset": &graphql.ArgumentConfig{
-						Type:         graphql.Int,
-						Description:  "Number of users to skip",
-						DefaultValue: 0,
-					},
+			Resolve: resolver.resolveUser,
+		},
+		"users": &graphql.Field{
+			Type:        graphql.NewList(UserType),
+			Description: "Get a list of users with pagination",
+			Args: graphql.FieldConfigArgument{
+				"limit": &graphql.ArgumentConfig{
+					Type:         graphql.Int,
+					Description:  "Maximum number of users to return",
+					DefaultValue: 10,
 				},
-				Resolve: resolver.resolveUsers,
-			},
-			"userByEmail": &graphql.Field{
-				Type:        UserType,
-				Description: "Get a user by email address",
-				Args: graphql.FieldConfigArgument{
-					"email": &graphql.ArgumentConfig{
-						Type:        graphql.NewNonNull(graphql.String),
-						Description: "The email address of the user",
-					},
+				"offset": &graphql.ArgumentConfig{
+					Type:         graphql.Int,
+					Description:  "Number of users to skip",
+					DefaultValue: 0,
 				},
-				Resolve: resolver.resolveUserByEmail,
 			},
-			"searchUsers": &graphql.Field{
-				Type:        graphql.NewList(UserType),
-				Description: "Search users by name or email",
-				Args: graphql.FieldConfigArgument{
-					"query": &graphql.ArgumentConfig{
-						Type:        graphql.NewNonNull(graphql.String),
-						Description: "The search query",
-					},
-					"limit": &graphql.ArgumentConfig{
-						Type:         graphql.Int,
-						Description:  "Maximum number of users to return",
-						DefaultValue: 50,
-					},
+			Resolve: resolver.resolveUsers,
+		},
+		"usersConnection": &graphql.Field{
+			Type:        graphql.NewNonNull(UserConnectionType),
+			Description: "Get a cursor-paginated list of users",
+			Args:        connectionArgs("users", 20),
+			Resolve:     resolver.resolveUsersConnection,
+		},
+		"userByEmail": &graphql.Field{
+			Type:        UserType,
+			Description: "Get a user by email address",
+			Args: graphql.FieldConfigArgument{
+				"email": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The email address of the user",
 				},
-				Resolve: resolver.resolveSearchUsers,
 			},
-			"currentUser": &graphql.Field{
-				Type:        UserType,
-				Description: "Get the currently authenticated user",
-				Resolve:     resolver.resolveCurrentUser,
+			Resolve: resolver.resolveUserByEmail,
+		},
+		"searchUsers": &graphql.Field{
+			Type:        graphql.NewList(UserType),
+			Description: "Search users by name or email",
+			Args: graphql.FieldConfigArgument{
+				"query": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The search query",
+				},
+				"limit": &graphql.ArgumentConfig{
+					Type:         graphql.Int,
+					Description:  "Maximum number of users to return",
+					DefaultValue: 50,
+				},
 			},
+			Resolve: resolver.resolveSearchUsers,
 		},
-	})
+		"currentUser": &graphql.Field{
+			Type:        UserType,
+			Description: "Get the currently authenticated user",
+			Resolve:     resolver.resolveCurrentUser,
+		},
+	}
 
-	// Define the root mutation type
-	rootMutation := graphql.NewObject(graphql.ObjectConfig{
-		Name: "Mutation",
-		Fields: graphql.Fields{
-			// User mutations
-			"createUser": &graphql.Field{
-				Type:        UserType,
-				Description: "Create a new user",
-				Args: graphql.FieldConfigArgument{
-					"input": &graphql.ArgumentConfig{
-						Type:        graphql.NewNonNull(CreateUserInput),
-						Description: "The user data",
-					},
+	mutationFields := graphql.Fields{
+		// User mutations
+		"createUser": &graphql.Field{
+			Type:        UserType,
+			Description: "Create a new user",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(CreateUserInput),
+					Description: "The user data",
 				},
-				Resolve: resolver.resolveCreateUser,
 			},
-			"updateUser": &graphql.Field{
-				Type:        UserType,
-				Description: "Update an existing user",
-				Args: graphql.FieldConfigArgument{
-					"id": &graphql.ArgumentConfig{
-						Type:        graphql.NewNonNull(graphql.String),
-						Description: "The ID of the user to update",
-					},
-					"input": &graphql.ArgumentConfig{
-						Type:        graphql.NewNonNull(UpdateUserInput),
-						Description: "The updated user data",
-					},
+			Resolve: resolver.resolveCreateUser,
+		},
+		"updateUser": &graphql.Field{
+			Type:        UserType,
+			Description: "Update an existing user",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the user to update",
+				},
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(UpdateUserInput),
+					Description: "The updated user data",
 				},
-				Resolve: resolver.resolveUpdateUser,
 			},
-			"deleteUser": &graphql.Field{
-				Type: graphql.NewObject(graphql.ObjectConfig{
-					Name: "DeleteResult",
-					Fields: graphql.Fields{
-						"success": &graphql.Field{
-							Type: graphql.NewNonNull(graphql.Boolean),
-						},
-						"message": &graphql.Field{
-							Type: graphql.String,
-						},
-					},
-				}),
-				Description: "Delete a user",
-				Args: graphql.FieldConfigArgument{
-					"id": &graphql.ArgumentConfig{
-						Type:        graphql.NewNonNull(graphql.String),
-						Description: "The ID of the user to delete",
-					},
+			Resolve: resolver.resolveUpdateUser,
+		},
+		"deleteUser": &graphql.Field{
+			Type:        DeleteResultType,
+			Description: "Delete a user",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the user to delete",
 				},
-				Resolve: resolver.resolveDeleteUser,
 			},
+			Resolve: resolver.resolveDeleteUser,
 		},
+	}
+
+	// Module fields
+	mergeFields(queryFields, serviceRecordQueryFields(resolver))
+	mergeFields(mutationFields, serviceRecordMutationFields(resolver))
+	mergeFields(queryFields, commissionQueryFields(resolver))
+	mergeFields(mutationFields, commissionMutationFields(resolver))
+	mergeFields(queryFields, integrationUsageQueryFields(resolver))
+	mergeFields(queryFields, eventQueryFields(resolver))
+	mergeFields(mutationFields, eventMutationFields(resolver))
+	mergeFields(queryFields, calendarQueryFields(resolver))
+	mergeFields(mutationFields, calendarMutationFields(resolver))
+	mergeFields(queryFields, clientPortalQueryFields(resolver))
+	mergeFields(queryFields, messageTemplateQueryFields(resolver))
+	mergeFields(mutationFields, messageTemplateMutationFields(resolver))
+	mergeFields(queryFields, appointmentConfirmationQueryFields(resolver))
+	mergeFields(mutationFields, appointmentConfirmationMutationFields(resolver))
+	mergeFields(queryFields, bookingGuardQueryFields(resolver))
+	mergeFields(mutationFields, bookingGuardMutationFields(resolver))
+	mergeFields(queryFields, capacityQueryFields(resolver))
+	mergeFields(queryFields, appointmentQueryFields(resolver))
+	mergeFields(mutationFields, appointmentMutationFields(resolver))
+	mergeFields(queryFields, priceChangeQueryFields(resolver))
+	mergeFields(mutationFields, priceChangeMutationFields(resolver))
+	mergeFields(queryFields, serviceBundleQueryFields(resolver))
+	mergeFields(mutationFields, serviceBundleMutationFields(resolver))
+	mergeFields(queryFields, clientGuardianQueryFields(resolver))
+	mergeFields(mutationFields, clientGuardianMutationFields(resolver))
+	mergeFields(queryFields, broadcastQueryFields(resolver))
+	mergeFields(mutationFields, broadcastMutationFields(resolver))
+	mergeFields(queryFields, paymentQueryFields(resolver))
+	mergeFields(mutationFields, paymentMutationFields(resolver))
+	mergeFields(queryFields, clientQueryFields(resolver))
+	mergeFields(mutationFields, clientMutationFields(resolver))
+	mergeFields(queryFields, outboundRequestQueryFields(resolver))
+	mergeFields(queryFields, dataResidencyQueryFields(resolver))
+	mergeFields(mutationFields, dataResidencyMutationFields(resolver))
+	mergeFields(queryFields, anonymousFeedbackQueryFields(resolver))
+	mergeFields(mutationFields, anonymousFeedbackMutationFields(resolver))
+	mergeFields(queryFields, availabilityQueryFields(resolver))
+	mergeFields(mutationFields, scheduleChangeMutationFields(resolver))
+	mergeFields(queryFields, giftCardQueryFields(resolver))
+	mergeFields(mutationFields, giftCardMutationFields(resolver))
+	mergeFields(queryFields, staffBookingPageQueryFields(resolver))
+	mergeFields(mutationFields, staffBookingPageMutationFields(resolver))
+	mergeFields(mutationFields, businessMutationFields(resolver))
+	mergeFields(queryFields, referralQueryFields(resolver))
+	mergeFields(mutationFields, referralMutationFields(resolver))
+	mergeFields(queryFields, campaignQueryFields(resolver))
+	mergeFields(mutationFields, campaignMutationFields(resolver))
+	mergeFields(queryFields, membershipQueryFields(resolver))
+	mergeFields(mutationFields, membershipMutationFields(resolver))
+	mergeFields(queryFields, announcementQueryFields(resolver))
+	mergeFields(mutationFields, announcementMutationFields(resolver))
+	mergeFields(queryFields, webhookQueryFields(resolver))
+	mergeFields(mutationFields, webhookMutationFields(resolver))
+	mergeFields(mutationFields, demoDataMutationFields(resolver))
+	mergeFields(queryFields, savedViewQueryFields(resolver))
+	mergeFields(mutationFields, savedViewMutationFields(resolver))
+	mergeFields(queryFields, businessImportQueryFields(resolver))
+	mergeFields(mutationFields, businessImportMutationFields(resolver))
+	mergeFields(mutationFields, watchdogMutationFields(resolver))
+	mergeFields(queryFields, runSheetQueryFields(resolver))
+	mergeFields(queryFields, jobQueryFields(resolver))
+	mergeFields(mutationFields, jobMutationFields(resolver))
+	mergeFields(queryFields, auditLogQueryFields(resolver))
+	mergeFields(queryFields, permissionQueryFields(resolver))
+	mergeFields(mutationFields, permissionMutationFields(resolver))
+	mergeFields(queryFields, trashQueryFields(resolver))
+	mergeFields(mutationFields, trashMutationFields(resolver))
+	mergeFields(queryFields, sessionQueryFields(resolver))
+	mergeFields(mutationFields, sessionMutationFields(resolver))
+	mergeFields(queryFields, searchQueryFields(resolver))
+	mergeFields(queryFields, clientLeaderboardQueryFields(resolver))
+	mergeFields(mutationFields, clientLeaderboardMutationFields(resolver))
+	mergeFields(mutationFields, appointmentQuoteMutationFields(resolver))
+	mergeFields(queryFields, cancellationPolicyQueryFields(resolver))
+	mergeFields(mutationFields, cancellationPolicyMutationFields(resolver))
+	mergeFields(queryFields, staffShiftQueryFields(resolver))
+	mergeFields(mutationFields, staffShiftMutationFields(resolver))
+	mergeFields(queryFields, timeClockQueryFields(resolver))
+	mergeFields(mutationFields, timeClockMutationFields(resolver))
+	mergeFields(queryFields, staffPerformanceQueryFields(resolver))
+	mergeFields(mutationFields, staffPerformanceMutationFields(resolver))
+	mergeFields(queryFields, reportExportQueryFields(resolver))
+	mergeFields(mutationFields, reportExportMutationFields(resolver))
+	mergeFields(mutationFields, imageUploadMutationFields(resolver))
+	mergeFields(queryFields, clientAttachmentQueryFields(resolver))
+	mergeFields(mutationFields, clientAttachmentMutationFields(resolver))
+	mergeFields(queryFields, reviewQueryFields(resolver))
+	mergeFields(mutationFields, reviewMutationFields(resolver))
+	mergeFields(queryFields, clientTimelineQueryFields(resolver))
+	mergeFields(queryFields, subscriptionQueryFields(resolver))
+	mergeFields(mutationFields, subscriptionMutationFields(resolver))
+	mergeFields(queryFields, trialQueryFields(resolver))
+	mergeFields(queryFields, businessHoursQueryFields(resolver))
+	mergeFields(mutationFields, businessHoursMutationFields(resolver))
+	mergeFields(queryFields, resourceQueryFields(resolver))
+	mergeFields(mutationFields, resourceMutationFields(resolver))
+	mergeFields(queryFields, adminQueryFields(resolver))
+	mergeFields(mutationFields, adminMutationFields(resolver))
+	mergeFields(queryFields, federationQueryFields(resolver))
+
+	// Define the root query and mutation types
+	rootQuery := graphql.NewObject(graphql.ObjectConfig{
+		Name:   "Query",
+		Fields: queryFields,
+	})
+	rootMutation := graphql.NewObject(graphql.ObjectConfig{
+		Name:   "Mutation",
+		Fields: mutationFields,
 	})
 
 	// Create the schema
@@ -140,4 +239,11 @@ func CreateSchema(resolver *Resolver) (graphql.Schema, error) {
 	}
 
 	return schema, nil
-}
\ No newline at end of file
+}
+
+// mergeFields adds the fields of a module to a root type's field map
+func mergeFields(target, fields graphql.Fields) {
+	for name, field := range fields {
+		target[name] = field
+	}
+}
@@ -0,0 +1,295 @@
+package schemadoc
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+)
+
+// Format is the file format of the documentation
+type Format string
+
+const (
+	FormatMarkdown Format = "markdown"
+	FormatHTML     Format = "html"
+)
+
+// kindLabels are how the kinds of types are named in the documentation
+var kindLabels = map[string]string{
+	KindScalar:      "scalar",
+	KindObject:      "object",
+	KindInterface:   "interface",
+	KindUnion:       "union",
+	KindEnum:        "enum",
+	KindInputObject: "input",
+}
+
+// Write writes the documentation of a schema in the given format
+func Write(w io.Writer, schema *Schema, format Format, title string) error {
+	switch format {
+	case FormatMarkdown:
+		return WriteMarkdown(w, schema, title)
+	case FormatHTML:
+		return WriteHTML(w, schema, title)
+	default:
+		return fmt.Errorf("unknown format %q, expected markdown or html", format)
+	}
+}
+
+// anchor returns the ID of the section documenting a query, mutation or type. Sections are
+// prefixed by what they document, as a query and a type may share a name.
+func anchor(section, name string) string {
+	return section + "-" + strings.ToLower(name)
+}
+
+// documented reports whether a type has a section of its own to link to
+func documented(schema *Schema, name string) bool {
+	return name != "" && !builtinScalars[name] && schema.Type(name) != nil
+}
+
+// deprecationNote describes why a field or enum value is deprecated
+func deprecationNote(deprecated bool, reason string) string {
+	if !deprecated {
+		return ""
+	}
+	if reason == "" {
+		return "Deprecated."
+	}
+	return "Deprecated: " + reason
+}
+
+// WriteMarkdown writes the documentation of a schema as a Markdown page
+func WriteMarkdown(w io.Writer, schema *Schema, title string) error {
+	md := &markdownWriter{schema: schema, w: w}
+	md.printf("# %s\n\nGenerated from the schema by introspection; do not edit.\n\n", title)
+	md.printf("- [Queries](#queries)\n- [Mutations](#mutations)\n- [Types](#types)\n")
+
+	for _, root := range []struct {
+		heading, section string
+		fields           []*Field
+	}{
+		{"Queries", "query", schema.Queries()},
+		{"Mutations", "mutation", schema.Mutations()},
+	} {
+		md.printf("\n## %s\n", root.heading)
+		for _, field := range root.fields {
+			md.printf("\n<a id=\"%s\"></a>\n\n### %s\n\n", anchor(root.section, field.Name), field.Name)
+			md.paragraph(field.Description)
+			md.paragraph(deprecationNote(field.IsDeprecated, field.DeprecationReason))
+			md.printf("**Returns:** %s\n\n", md.typeLink(field.Type))
+			md.inputTable("Argument", field.Args)
+		}
+	}
+
+	md.printf("\n## Types\n")
+	for _, t := range schema.DocumentedTypes() {
+		md.printf("\n<a id=\"%s\"></a>\n\n### %s (%s)\n\n", anchor("type", t.Name), t.Name, kindLabels[t.Kind])
+		md.paragraph(t.Description)
+		if len(t.Interfaces) > 0 {
+			md.printf("**Implements:** %s\n\n", md.typeLinks(t.Interfaces))
+		}
+		if len(t.PossibleTypes) > 0 {
+			md.printf("**Possible types:** %s\n\n", md.typeLinks(t.PossibleTypes))
+		}
+		if len(t.Fields) > 0 {
+			md.printf("| Field | Type | Description |\n| --- | --- | --- |\n")
+			for _, field := range sortedFields(t.Fields) {
+				name := "`" + field.Name + "`"
+				if len(field.Args) > 0 {
+					args := make([]string, len(field.Args))
+					for i, arg := range field.Args {
+						args[i] = arg.Name + ": " + arg.Type.String()
+					}
+					name = "`" + field.Name + "(" + strings.Join(args, ", ") + ")`"
+				}
+				md.row(name, md.typeLink(field.Type), describe(field.Description, field.IsDeprecated, field.DeprecationReason))
+			}
+			md.printf("\n")
+		}
+		md.inputTable("Field", t.InputFields)
+		if len(t.EnumValues) > 0 {
+			md.printf("| Value | Description |\n| --- | --- |\n")
+			for _, value := range t.EnumValues {
+				md.row("`"+value.Name+"`", describe(value.Description, value.IsDeprecated, value.DeprecationReason))
+			}
+			md.printf("\n")
+		}
+	}
+	return md.err
+}
+
+// markdownWriter writes Markdown, keeping the first error
+type markdownWriter struct {
+	schema *Schema
+	w      io.Writer
+	err    error
+}
+
+func (md *markdownWriter) printf(format string, args ...any) {
+	if md.err == nil {
+		_, md.err = fmt.Fprintf(md.w, format, args...)
+	}
+}
+
+// paragraph prints a paragraph, nothing when empty
+func (md *markdownWriter) paragraph(text string) {
+	if text != "" {
+		md.printf("%s\n\n", text)
+	}
+}
+
+// inputTable prints the arguments of a field or the fields of an input object
+func (md *markdownWriter) inputTable(heading string, values []*InputValue) {
+	if len(values) == 0 {
+		return
+	}
+	md.printf("| %s | Type | Default | Description |\n| --- | --- | --- | --- |\n", heading)
+	for _, value := range values {
+		defaultValue := ""
+		if value.DefaultValue != nil {
+			defaultValue = "`" + *value.DefaultValue + "`"
+		}
+		md.row("`"+value.Name+"`", md.typeLink(value.Type), defaultValue, value.Description)
+	}
+	md.printf("\n")
+}
+
+// row prints a table row, escaping its cells
+func (md *markdownWriter) row(cells ...string) {
+	escaped := make([]string, len(cells))
+	for i, text := range cells {
+		escaped[i] = strings.ReplaceAll(strings.ReplaceAll(text, "|", `\|`), "\n", " ")
+	}
+	md.printf("| %s |\n", strings.Join(escaped, " | "))
+}
+
+// typeLink prints a type reference, linking to the section of the type when it has one
+func (md *markdownWriter) typeLink(ref *TypeRef) string {
+	code := "`" + ref.String() + "`"
+	if name := ref.Named(); documented(md.schema, name) {
+		return "[" + code + "](#" + anchor("type", name) + ")"
+	}
+	return code
+}
+
+func (md *markdownWriter) typeLinks(refs []*TypeRef) string {
+	links := make([]string, len(refs))
+	for i, ref := range refs {
+		links[i] = md.typeLink(ref)
+	}
+	return strings.Join(links, ", ")
+}
+
+// describe appends why a field or enum value is deprecated to its description
+func describe(description string, deprecated bool, reason string) string {
+	return strings.TrimSpace(description + " " + deprecationNote(deprecated, reason))
+}
+
+// WriteHTML writes the documentation of a schema as a self-contained HTML page, with a sidebar
+// listing every query, mutation and type
+func WriteHTML(w io.Writer, schema *Schema, title string) error {
+	page := template.Must(template.New("schema").Funcs(template.FuncMap{
+		"anchor":      anchor,
+		"kind":        func(kind string) string { return kindLabels[kind] },
+		"deprecation": deprecationNote,
+		"sorted":      sortedFields,
+		"typeLink": func(ref *TypeRef) template.HTML {
+			code := "<code>" + template.HTMLEscapeString(ref.String()) + "</code>"
+			if name := ref.Named(); documented(schema, name) {
+				return template.HTML(`<a href="#` + anchor("type", name) + `">` + code + "</a>")
+			}
+			return template.HTML(code)
+		},
+	}).Parse(htmlTemplate))
+	return page.Execute(w, map[string]any{
+		"Title":     title,
+		"Queries":   rootEntries("query", schema.Queries()),
+		"Mutations": rootEntries("mutation", schema.Mutations()),
+		"Types":     schema.DocumentedTypes(),
+	})
+}
+
+// rootEntry is a root query or mutation field, with the section documenting it
+type rootEntry struct {
+	Section string
+	Field   *Field
+}
+
+func rootEntries(section string, fields []*Field) []rootEntry {
+	entries := make([]rootEntry, len(fields))
+	for i, field := range fields {
+		entries[i] = rootEntry{Section: section, Field: field}
+	}
+	return entries
+}
+
+const htmlTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { margin: 0; font: 15px/1.5 -apple-system, BlinkMacSystemFont, "Segoe UI", sans-serif; color: #1f2328; }
+nav { position: fixed; top: 0; bottom: 0; width: 260px; overflow-y: auto; padding: 16px; background: #f6f8fa; border-right: 1px solid #d0d7de; box-sizing: border-box; }
+nav h2 { font-size: 13px; text-transform: uppercase; color: #59636e; margin: 16px 0 4px; }
+nav a { display: block; color: #0969da; text-decoration: none; font-size: 13px; }
+main { margin-left: 260px; padding: 24px 40px; max-width: 960px; }
+section { border-top: 1px solid #d0d7de; padding-top: 8px; margin-top: 24px; }
+table { border-collapse: collapse; width: 100%; margin: 8px 0; }
+th, td { border: 1px solid #d0d7de; padding: 4px 8px; text-align: left; vertical-align: top; }
+code { font: 13px ui-monospace, SFMono-Regular, Menlo, monospace; }
+.kind { color: #59636e; font-weight: normal; font-size: 14px; }
+.deprecated { color: #9a6700; }
+</style>
+</head>
+<body>
+<nav>
+<strong>{{.Title}}</strong>
+<h2>Queries</h2>
+{{range .Queries}}<a href="#{{anchor .Section .Field.Name}}">{{.Field.Name}}</a>
+{{end}}<h2>Mutations</h2>
+{{range .Mutations}}<a href="#{{anchor .Section .Field.Name}}">{{.Field.Name}}</a>
+{{end}}<h2>Types</h2>
+{{range .Types}}<a href="#{{anchor "type" .Name}}">{{.Name}}</a>
+{{end}}</nav>
+<main>
+<h1>{{.Title}}</h1>
+<p>Generated from the schema by introspection; do not edit.</p>
+{{define "args"}}{{if .}}<table>
+<tr><th>Argument</th><th>Type</th><th>Default</th><th>Description</th></tr>
+{{range .}}<tr><td><code>{{.Name}}</code></td><td>{{typeLink .Type}}</td><td>{{with .DefaultValue}}<code>{{.}}</code>{{end}}</td><td>{{.Description}}</td></tr>
+{{end}}</table>
+{{end}}{{end}}{{define "root"}}<section id="{{anchor .Section .Field.Name}}">
+<h3>{{.Field.Name}}</h3>
+{{with .Field.Description}}<p>{{.}}</p>{{end}}
+{{with deprecation .Field.IsDeprecated .Field.DeprecationReason}}<p class="deprecated">{{.}}</p>{{end}}
+<p><strong>Returns:</strong> {{typeLink .Field.Type}}</p>
+{{template "args" .Field.Args}}</section>
+{{end}}<h2 id="queries">Queries</h2>
+{{range .Queries}}{{template "root" .}}{{end}}
+<h2 id="mutations">Mutations</h2>
+{{range .Mutations}}{{template "root" .}}{{end}}
+<h2 id="types">Types</h2>
+{{range .Types}}<section id="{{anchor "type" .Name}}">
+<h3>{{.Name}} <span class="kind">{{kind .Kind}}</span></h3>
+{{with .Description}}<p>{{.}}</p>{{end}}
+{{with .Interfaces}}<p><strong>Implements:</strong> {{range $i, $ref := .}}{{if $i}}, {{end}}{{typeLink $ref}}{{end}}</p>{{end}}
+{{with .PossibleTypes}}<p><strong>Possible types:</strong> {{range $i, $ref := .}}{{if $i}}, {{end}}{{typeLink $ref}}{{end}}</p>{{end}}
+{{with .Fields}}<table>
+<tr><th>Field</th><th>Type</th><th>Description</th></tr>
+{{range sorted .}}<tr><td><code>{{.Name}}</code>{{template "args" .Args}}</td><td>{{typeLink .Type}}</td><td>{{.Description}}{{with deprecation .IsDeprecated .DeprecationReason}} <span class="deprecated">{{.}}</span>{{end}}</td></tr>
+{{end}}</table>
+{{end}}{{with .InputFields}}<table>
+<tr><th>Field</th><th>Type</th><th>Default</th><th>Description</th></tr>
+{{range .}}<tr><td><code>{{.Name}}</code></td><td>{{typeLink .Type}}</td><td>{{with .DefaultValue}}<code>{{.}}</code>{{end}}</td><td>{{.Description}}</td></tr>
+{{end}}</table>
+{{end}}{{with .EnumValues}}<table>
+<tr><th>Value</th><th>Description</th></tr>
+{{range .}}<tr><td><code>{{.Name}}</code></td><td>{{.Description}}{{with deprecation .IsDeprecated .DeprecationReason}} <span class="deprecated">{{.}}</span>{{end}}</td></tr>
+{{end}}</table>
+{{end}}</section>
+{{end}}</main>
+</body>
+</html>
+`
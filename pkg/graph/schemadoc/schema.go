@@ -0,0 +1,290 @@
+// Package schemadoc generates developer documentation of a GraphQL schema. The schema is read
+// through the standard introspection query, either from a schema built in-process or from a
+// running server, so the documentation shows exactly what clients see: every query, mutation
+// and type with the descriptions its fields and arguments were registered with.
+package schemadoc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+)
+
+// IntrospectionQuery reads the whole schema, with type references nested deep enough for
+// non-null lists of non-null types
+const IntrospectionQuery = `query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    subscriptionType { name }
+    types { ...FullType }
+  }
+}
+
+fragment FullType on __Type {
+  kind
+  name
+  description
+  fields(includeDeprecated: true) {
+    name
+    description
+    args { ...InputValue }
+    type { ...TypeRef }
+    isDeprecated
+    deprecationReason
+  }
+  inputFields { ...InputValue }
+  interfaces { ...TypeRef }
+  enumValues(includeDeprecated: true) {
+    name
+    description
+    isDeprecated
+    deprecationReason
+  }
+  possibleTypes { ...TypeRef }
+}
+
+fragment InputValue on __InputValue {
+  name
+  description
+  type { ...TypeRef }
+  defaultValue
+}
+
+fragment TypeRef on __Type {
+  kind
+  name
+  ofType { kind name ofType { kind name ofType { kind name ofType { kind name } } } }
+}`
+
+// Kinds of types, as introspection names them
+const (
+	KindScalar      = "SCALAR"
+	KindObject      = "OBJECT"
+	KindInterface   = "INTERFACE"
+	KindUnion       = "UNION"
+	KindEnum        = "ENUM"
+	KindInputObject = "INPUT_OBJECT"
+	KindList        = "LIST"
+	KindNonNull     = "NON_NULL"
+)
+
+// builtinScalars are defined by every GraphQL implementation and left out of the documentation
+var builtinScalars = map[string]bool{"String": true, "Int": true, "Float": true, "Boolean": true, "ID": true}
+
+// Schema is the result of the introspection query
+type Schema struct {
+	QueryType        *NamedRef `json:"queryType"`
+	MutationType     *NamedRef `json:"mutationType"`
+	SubscriptionType *NamedRef `json:"subscriptionType"`
+	Types            []*Type   `json:"types"`
+}
+
+// NamedRef names a root type
+type NamedRef struct {
+	Name string `json:"name"`
+}
+
+// Type is a type of the schema
+type Type struct {
+	Kind          string        `json:"kind"`
+	Name          string        `json:"name"`
+	Description   string        `json:"description"`
+	Fields        []*Field      `json:"fields"`
+	InputFields   []*InputValue `json:"inputFields"`
+	Interfaces    []*TypeRef    `json:"interfaces"`
+	EnumValues    []*EnumValue  `json:"enumValues"`
+	PossibleTypes []*TypeRef    `json:"possibleTypes"`
+}
+
+// Field is a field of an object or interface, or a root query or mutation
+type Field struct {
+	Name              string        `json:"name"`
+	Description       string        `json:"description"`
+	Args              []*InputValue `json:"args"`
+	Type              *TypeRef      `json:"type"`
+	IsDeprecated      bool          `json:"isDeprecated"`
+	DeprecationReason string        `json:"deprecationReason"`
+}
+
+// InputValue is an argument or a field of an input object
+type InputValue struct {
+	Name         string   `json:"name"`
+	Description  string   `json:"description"`
+	Type         *TypeRef `json:"type"`
+	DefaultValue *string  `json:"defaultValue"`
+}
+
+// EnumValue is a value of an enum
+type EnumValue struct {
+	Name              string `json:"name"`
+	Description       string `json:"description"`
+	IsDeprecated      bool   `json:"isDeprecated"`
+	DeprecationReason string `json:"deprecationReason"`
+}
+
+// TypeRef refers to a type, possibly wrapped in lists and non-nulls
+type TypeRef struct {
+	Kind   string   `json:"kind"`
+	Name   string   `json:"name"`
+	OfType *TypeRef `json:"ofType"`
+}
+
+// String prints the reference the way GraphQL does, e.g. "[Client!]!"
+func (r *TypeRef) String() string {
+	switch {
+	case r == nil:
+		return ""
+	case r.Kind == KindNonNull:
+		return r.OfType.String() + "!"
+	case r.Kind == KindList:
+		return "[" + r.OfType.String() + "]"
+	default:
+		return r.Name
+	}
+}
+
+// Named returns the name of the type referred to, unwrapped from lists and non-nulls
+func (r *TypeRef) Named() string {
+	for r != nil && r.OfType != nil {
+		r = r.OfType
+	}
+	if r == nil {
+		return ""
+	}
+	return r.Name
+}
+
+// Introspect reads a schema built in-process
+func Introspect(schema graphql.Schema) (*Schema, error) {
+	result := graphql.Do(graphql.Params{Schema: schema, RequestString: IntrospectionQuery, Context: context.Background()})
+	if len(result.Errors) > 0 {
+		return nil, fmt.Errorf("introspection failed: %s", result.Errors[0].Message)
+	}
+	data, err := json.Marshal(result.Data)
+	if err != nil {
+		return nil, err
+	}
+	return Decode(data)
+}
+
+// Fetch reads the schema of a running server, authenticating with a bearer token when given
+func Fetch(ctx context.Context, client *http.Client, endpoint, token string) (*Schema, error) {
+	body, err := json.Marshal(map[string]string{"query": IntrospectionQuery, "operationName": "IntrospectionQuery"})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("introspection failed with status %d", resp.StatusCode)
+	}
+
+	var response struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("invalid introspection response: %w", err)
+	}
+	if len(response.Errors) > 0 {
+		return nil, fmt.Errorf("introspection failed: %s", response.Errors[0].Message)
+	}
+	return Decode(response.Data)
+}
+
+// Decode decodes the data of an introspection query response
+func Decode(data []byte) (*Schema, error) {
+	var result struct {
+		Schema *Schema `json:"__schema"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("invalid introspection result: %w", err)
+	}
+	if result.Schema == nil {
+		return nil, errors.New("invalid introspection result: no __schema")
+	}
+	return result.Schema, nil
+}
+
+// Type returns the named type, nil when the schema has none
+func (s *Schema) Type(name string) *Type {
+	for _, t := range s.Types {
+		if t.Name == name {
+			return t
+		}
+	}
+	return nil
+}
+
+// Queries returns the root query fields, sorted by name
+func (s *Schema) Queries() []*Field {
+	return s.rootFields(s.QueryType)
+}
+
+// Mutations returns the root mutation fields, sorted by name
+func (s *Schema) Mutations() []*Field {
+	return s.rootFields(s.MutationType)
+}
+
+func (s *Schema) rootFields(root *NamedRef) []*Field {
+	if root == nil {
+		return nil
+	}
+	t := s.Type(root.Name)
+	if t == nil {
+		return nil
+	}
+	return sortedFields(t.Fields)
+}
+
+// DocumentedTypes returns the types worth documenting, sorted by name: everything but the root
+// types, the introspection types and the built-in scalars
+func (s *Schema) DocumentedTypes() []*Type {
+	roots := map[string]bool{}
+	for _, root := range []*NamedRef{s.QueryType, s.MutationType, s.SubscriptionType} {
+		if root != nil {
+			roots[root.Name] = true
+		}
+	}
+	var types []*Type
+	for _, t := range s.Types {
+		if !roots[t.Name] && !strings.HasPrefix(t.Name, "__") && !builtinScalars[t.Name] {
+			types = append(types, t)
+		}
+	}
+	slices.SortFunc(types, func(a, b *Type) int { return strings.Compare(a.Name, b.Name) })
+	return types
+}
+
+// sortedFields returns fields sorted by name, so the documentation is stable
+func sortedFields(fields []*Field) []*Field {
+	sorted := slices.Clone(fields)
+	slices.SortFunc(sorted, func(a, b *Field) int { return strings.Compare(a.Name, b.Name) })
+	return sorted
+}
@@ -0,0 +1,134 @@
+package schemadoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSchema(t *testing.T) graphql.Schema {
+	t.Helper()
+	status := graphql.NewEnum(graphql.EnumConfig{
+		Name:        "Status",
+		Description: "Whether a client is active",
+		Values: graphql.EnumValueConfigMap{
+			"ACTIVE":   {Value: "active", Description: "The client books"},
+			"ARCHIVED": {Value: "archived", DeprecationReason: "Use deletedAt"},
+		},
+	})
+	client := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Client",
+		Description: "A client of a business",
+		Fields: graphql.Fields{
+			"id":     {Type: graphql.NewNonNull(graphql.String), Description: "The ID of the client"},
+			"status": {Type: status, Description: "Whether the client is active"},
+			"phone":  {Type: graphql.String, DeprecationReason: "Use contact"},
+		},
+	})
+	filter := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name:        "ClientFilter",
+		Description: "Narrows the clients listed",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"search": {Type: graphql.String, Description: "Text the name contains"},
+		},
+	})
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{Name: "Query", Fields: graphql.Fields{
+			"clients": {
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(client))),
+				Description: "List the clients of a business",
+				Args: graphql.FieldConfigArgument{
+					"filter": {Type: filter, Description: "Narrows the clients"},
+					"limit":  {Type: graphql.Int, DefaultValue: 20, Description: "The most clients returned"},
+				},
+			},
+		}}),
+		Mutation: graphql.NewObject(graphql.ObjectConfig{Name: "Mutation", Fields: graphql.Fields{
+			"archiveClient": {Type: client, Description: "Archive a client", Args: graphql.FieldConfigArgument{
+				"id": {Type: graphql.NewNonNull(graphql.String)},
+			}},
+		}}),
+	})
+	require.NoError(t, err)
+	return schema
+}
+
+func TestIntrospect(t *testing.T) {
+	schema, err := Introspect(testSchema(t))
+	require.NoError(t, err)
+
+	queries := schema.Queries()
+	require.Len(t, queries, 1)
+	assert.Equal(t, "List the clients of a business", queries[0].Description)
+	assert.Equal(t, "[Client!]!", queries[0].Type.String())
+	assert.Equal(t, "Client", queries[0].Type.Named())
+	require.Len(t, schema.Mutations(), 1)
+
+	var names []string
+	for _, typ := range schema.DocumentedTypes() {
+		names = append(names, typ.Name)
+	}
+	assert.Equal(t, []string{"Client", "ClientFilter", "Status"}, names, "root, introspection and built-in types are left out")
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	schema, err := Introspect(testSchema(t))
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, Write(&out, schema, FormatMarkdown, "Test API"))
+	doc := out.String()
+	assert.Contains(t, doc, "# Test API")
+	assert.Contains(t, doc, `<a id="query-clients"></a>`)
+	assert.Contains(t, doc, "List the clients of a business")
+	assert.Contains(t, doc, "[`[Client!]!`](#type-client)")
+	assert.Contains(t, doc, "| `limit` | `Int` | `20` | The most clients returned |")
+	assert.Contains(t, doc, `<a id="mutation-archiveclient"></a>`)
+	assert.Contains(t, doc, "Narrows the clients listed")
+	assert.Contains(t, doc, "Deprecated: Use contact")
+	assert.Contains(t, doc, "Deprecated: Use deletedAt")
+}
+
+func TestWriteHTML(t *testing.T) {
+	schema, err := Introspect(testSchema(t))
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, Write(&out, schema, FormatHTML, "Test <API>"))
+	doc := out.String()
+	assert.Contains(t, doc, "Test &lt;API&gt;", "the title is escaped")
+	assert.Contains(t, doc, `id="query-clients"`)
+	assert.Contains(t, doc, `href="#type-client"`)
+	assert.Contains(t, doc, "A client of a business")
+
+	assert.Error(t, Write(&out, schema, Format("pdf"), "Test API"))
+}
+
+func TestFetch(t *testing.T) {
+	schema := testSchema(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var body struct {
+			Query string `json:"query"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		_ = json.NewEncoder(w).Encode(graphql.Do(graphql.Params{Schema: schema, RequestString: body.Query, Context: r.Context()}))
+	}))
+	defer server.Close()
+
+	fetched, err := Fetch(t.Context(), server.Client(), server.URL, "secret")
+	require.NoError(t, err)
+	assert.NotNil(t, fetched.Type("ClientFilter"))
+
+	_, err = Fetch(t.Context(), server.Client(), server.URL, "")
+	assert.ErrorContains(t, err, "status 401")
+}
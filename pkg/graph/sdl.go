@@ -0,0 +1,193 @@
+package graph
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+)
+
+// builtinScalars are the scalars every GraphQL implementation defines, left out of printed SDL
+var builtinScalars = map[string]bool{"String": true, "Int": true, "Float": true, "Boolean": true, "ID": true}
+
+// sdlOptions tailor how printSDL prints a schema
+type sdlOptions struct {
+	header         string                 // Printed before the types, e.g. a schema extension
+	skip           func(name string) bool // Leaves out the named types and root fields
+	typeDirectives func(name string) string
+}
+
+// PrintSDL prints a schema in the schema definition language, e.g. for the code generators of
+// frontends
+func PrintSDL(schema graphql.Schema) string {
+	return printSDL(schema, sdlOptions{})
+}
+
+// PrintSubgraphSDL prints the private schema as the Apollo Federation subgraph it is, as the
+// _service query returns it, for routers composing from a file
+func PrintSubgraphSDL(schema graphql.Schema) string {
+	return printSDL(schema, sdlOptions{
+		header: federationSchemaExtension,
+		skip:   func(name string) bool { return federationNames[name] },
+		typeDirectives: func(name string) string {
+			if federationEntities[name] {
+				return `@key(fields: "id")`
+			}
+			return ""
+		},
+	})
+}
+
+// printSDL prints a schema in the schema definition language, types sorted by name and fields
+// sorted by name so that the output is stable. Introspection types and built-in scalars are
+// left out.
+func printSDL(schema graphql.Schema, options sdlOptions) string {
+	skip := func(name string) bool {
+		return strings.HasPrefix(name, "__") || (options.skip != nil && options.skip(name))
+	}
+
+	var names []string
+	for name := range schema.TypeMap() {
+		if !skip(name) && !builtinScalars[name] {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	var b strings.Builder
+	if options.header != "" {
+		b.WriteString(options.header)
+		b.WriteString("\n\n")
+	}
+	for i, name := range names {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		directives := ""
+		if options.typeDirectives != nil {
+			if d := options.typeDirectives(name); d != "" {
+				directives = " " + d
+			}
+		}
+
+		switch t := schema.TypeMap()[name].(type) {
+		case *graphql.Scalar:
+			printDescription(&b, t.Description(), "")
+			fmt.Fprintf(&b, "scalar %s%s\n", name, directives)
+		case *graphql.Enum:
+			printDescription(&b, t.Description(), "")
+			fmt.Fprintf(&b, "enum %s%s {\n", name, directives)
+			for _, value := range t.Values() {
+				printDescription(&b, value.Description, "  ")
+				fmt.Fprintf(&b, "  %s%s\n", value.Name, deprecation(value.DeprecationReason))
+			}
+			b.WriteString("}\n")
+		case *graphql.Union:
+			printDescription(&b, t.Description(), "")
+			members := make([]string, 0, len(t.Types()))
+			for _, member := range t.Types() {
+				members = append(members, member.Name())
+			}
+			fmt.Fprintf(&b, "union %s%s = %s\n", name, directives, strings.Join(members, " | "))
+		case *graphql.InputObject:
+			printDescription(&b, t.Description(), "")
+			fmt.Fprintf(&b, "input %s%s {\n", name, directives)
+			fields := t.Fields()
+			for _, fieldName := range sortedKeys(fields) {
+				field := fields[fieldName]
+				printDescription(&b, field.Description(), "  ")
+				fmt.Fprintf(&b, "  %s: %s%s\n", fieldName, field.Type, defaultValue(field.DefaultValue, field.Type))
+			}
+			b.WriteString("}\n")
+		case *graphql.Interface:
+			printDescription(&b, t.Description(), "")
+			fmt.Fprintf(&b, "interface %s%s {\n", name, directives)
+			printFields(&b, t.Fields(), skip)
+			b.WriteString("}\n")
+		case *graphql.Object:
+			printDescription(&b, t.Description(), "")
+			implements := ""
+			if interfaces := t.Interfaces(); len(interfaces) > 0 {
+				interfaceNames := make([]string, 0, len(interfaces))
+				for _, iface := range interfaces {
+					interfaceNames = append(interfaceNames, iface.Name())
+				}
+				implements = " implements " + strings.Join(interfaceNames, " & ")
+			}
+			fmt.Fprintf(&b, "type %s%s%s {\n", name, implements, directives)
+			printFields(&b, t.Fields(), skip)
+			b.WriteString("}\n")
+		}
+	}
+	return b.String()
+}
+
+// printFields prints the fields of an object or interface with their arguments
+func printFields(b *strings.Builder, fields graphql.FieldDefinitionMap, skip func(name string) bool) {
+	for _, name := range sortedKeys(fields) {
+		if skip(name) {
+			continue
+		}
+		field := fields[name]
+		printDescription(b, field.Description, "  ")
+		args := ""
+		if len(field.Args) > 0 {
+			parts := make([]string, 0, len(field.Args))
+			for _, arg := range field.Args {
+				parts = append(parts, fmt.Sprintf("%s: %s%s", arg.Name(), arg.Type, defaultValue(arg.DefaultValue, arg.Type)))
+			}
+			args = "(" + strings.Join(parts, ", ") + ")"
+		}
+		fmt.Fprintf(b, "  %s%s: %s%s\n", name, args, field.Type, deprecation(field.DeprecationReason))
+	}
+}
+
+// printDescription prints a description as a block string on the lines before what it describes
+func printDescription(b *strings.Builder, description, indent string) {
+	if description == "" {
+		return
+	}
+	description = strings.ReplaceAll(description, `"""`, `\"""`)
+	fmt.Fprintf(b, "%s\"\"\"%s\"\"\"\n", indent, description)
+}
+
+// deprecation prints the deprecated directive of a field or enum value
+func deprecation(reason string) string {
+	if reason == "" {
+		return ""
+	}
+	return " @deprecated(reason: " + strconv.Quote(reason) + ")"
+}
+
+// defaultValue prints the default value of an argument or input field
+func defaultValue(value any, typ graphql.Input) string {
+	if value == nil {
+		return ""
+	}
+	if nonNull, ok := typ.(*graphql.NonNull); ok {
+		typ, _ = nonNull.OfType.(graphql.Input)
+	}
+	if enum, ok := typ.(*graphql.Enum); ok {
+		for _, enumValue := range enum.Values() {
+			if fmt.Sprint(enumValue.Value) == fmt.Sprint(value) {
+				return " = " + enumValue.Name
+			}
+		}
+	}
+	if s, ok := value.(string); ok {
+		return " = " + strconv.Quote(s)
+	}
+	return fmt.Sprintf(" = %v", value)
+}
+
+// sortedKeys returns the keys of a map in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,36 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Search Query Resolvers
+func (r *Resolver) resolveSearch(p graphql.ResolveParams) (any, error) {
+	searchDTO := dto.SearchDTO{}
+	if businessID, ok := p.Args["businessId"].(string); ok {
+		searchDTO.BusinessID = businessID
+	}
+	if query, ok := p.Args["query"].(string); ok {
+		searchDTO.Query = query
+	}
+	if kinds, ok := p.Args["kinds"].([]any); ok {
+		for _, kind := range kinds {
+			if kind, ok := kind.(domain.SearchResultKind); ok {
+				searchDTO.Kinds = append(searchDTO.Kinds, kind)
+			}
+		}
+	}
+	if limit, ok := p.Args["limit"].(int); ok {
+		searchDTO.Limit = limit
+	}
+
+	results, err := r.searchService.Search(p.Context, searchDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
@@ -0,0 +1,97 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// SearchResultKindEnum represents the GraphQL enum for the kinds of record a search finds
+var SearchResultKindEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "SearchResultKind",
+	Description: "The kind of record a search result is",
+	Values: graphql.EnumValueConfigMap{
+		"CLIENT": &graphql.EnumValueConfig{
+			Value:       domain.SearchResultClient,
+			Description: "A client, matched by name, email, phone number or notes",
+		},
+		"SERVICE": &graphql.EnumValueConfig{
+			Value:       domain.SearchResultService,
+			Description: "A service, matched by name or description",
+		},
+	},
+})
+
+// SearchItemUnion represents the GraphQL SearchItem union
+var SearchItemUnion = graphql.NewUnion(graphql.UnionConfig{
+	Name:        "SearchItem",
+	Description: "A record a search finds",
+	Types:       []*graphql.Object{ClientType, ServiceType},
+	ResolveType: func(p graphql.ResolveTypeParams) *graphql.Object {
+		switch p.Value.(type) {
+		case *dto.ClientResponseDTO:
+			return ClientType
+		case *dto.ServiceResponseDTO:
+			return ServiceType
+		default:
+			return nil
+		}
+	},
+})
+
+// SearchResultType represents the GraphQL SearchResult type
+var SearchResultType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "SearchResult",
+	Description: "A client or service matching a search",
+	Fields: graphql.Fields{
+		"kind": &graphql.Field{
+			Type:        graphql.NewNonNull(SearchResultKindEnum),
+			Description: "The kind of record found",
+		},
+		"rank": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Float),
+			Description: "How well the record matches, higher first; only comparable within one search",
+		},
+		"item": &graphql.Field{
+			Type:        graphql.NewNonNull(SearchItemUnion),
+			Description: "The record found",
+			Resolve: func(p graphql.ResolveParams) (any, error) {
+				if result, ok := p.Source.(*dto.SearchResultDTO); ok {
+					return result.Item(), nil
+				}
+				return nil, nil
+			},
+		},
+	},
+})
+
+// searchQueryFields returns the search queries
+func searchQueryFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"search": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(SearchResultType))),
+			Description: "Search the clients and services of a business, best match first. Every word must start a word of the record, ignoring case and accents; client names also match with typos, and digits match phone numbers.",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+				"query": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The words to search for",
+				},
+				"kinds": &graphql.ArgumentConfig{
+					Type:        graphql.NewList(graphql.NewNonNull(SearchResultKindEnum)),
+					Description: "The kinds of record to search; every kind the caller may view when omitted",
+				},
+				"limit": &graphql.ArgumentConfig{
+					Type:         graphql.Int,
+					DefaultValue: domain.DefaultSearchLimit,
+					Description:  "The maximum number of results, up to 50",
+				},
+			},
+			Resolve: resolver.resolveSearch,
+		},
+	}
+}
@@ -0,0 +1,102 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Service Bundle Query Resolvers
+func (r *Resolver) resolveServiceBundle(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+
+	bundle, err := r.serviceBundleService.GetBundle(p.Context, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return bundle, nil
+}
+
+func (r *Resolver) resolveServiceBundles(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+	activeOnly, _ := p.Args["activeOnly"].(bool)
+
+	bundles, err := r.serviceBundleService.ListBundles(p.Context, businessID, activeOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	return bundles, nil
+}
+
+// Service Bundle Mutation Resolvers
+func (r *Resolver) resolveCreateServiceBundle(p graphql.ResolveParams) (any, error) {
+	createDTO := dto.CreateServiceBundleDTO{IsActive: true}
+	if err := decodeInput(p.Args, &createDTO); err != nil {
+		return nil, err
+	}
+
+	bundle, err := r.serviceBundleService.CreateBundle(p.Context, createDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return bundle, nil
+}
+
+func (r *Resolver) resolveUpdateServiceBundle(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+	updateDTO := dto.UpdateServiceBundleDTO{}
+	if err := decodeInput(p.Args, &updateDTO); err != nil {
+		return nil, err
+	}
+
+	bundle, err := r.serviceBundleService.UpdateBundle(p.Context, id, updateDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return bundle, nil
+}
+
+func (r *Resolver) resolveDeleteServiceBundle(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+
+	if err := r.serviceBundleService.DeleteBundle(p.Context, id); err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		"success": true,
+		"message": "Service bundle deleted successfully",
+	}, nil
+}
+
+func (r *Resolver) resolveBookServiceBundle(p graphql.ResolveParams) (any, error) {
+	bookDTO := dto.BookBundleDTO{}
+	if err := decodeInput(p.Args, &bookDTO); err != nil {
+		return nil, err
+	}
+
+	appointment, err := r.appointmentService.BookBundle(p.Context, bookDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return appointment, nil
+}
@@ -0,0 +1,308 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+)
+
+// BundleItemType represents the GraphQL BundleItem type
+var BundleItemType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "BundleItem",
+	Description: "A service of a bundle, in the order it is performed",
+	Fields: graphql.Fields{
+		"serviceId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the service",
+		},
+		"processingMinutes": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "Trailing minutes of the service during which the client waits, e.g. while colour develops",
+		},
+		"duringPreviousProcessing": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether the service starts as soon as the previous one starts processing",
+		},
+	},
+})
+
+// BundleStepType represents the GraphQL BundleStep type
+var BundleStepType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "BundleStep",
+	Description: "When a service of a bundle is performed, relative to the start of the booking",
+	Fields: graphql.Fields{
+		"serviceId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the service",
+		},
+		"serviceName": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The name of the service",
+		},
+		"offsetMinutes": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "Minutes after the start of the booking at which the service starts",
+		},
+		"duration": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The duration of the service in minutes",
+		},
+		"price": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The service's share of the bundle price",
+		},
+	},
+})
+
+// ServiceBundleType represents the GraphQL ServiceBundle type
+var ServiceBundleType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ServiceBundle",
+	Description: "Services booked together as a single selection at a combined price",
+	Fields: withBaseFields("service bundle", graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"name": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The name of the bundle",
+		},
+		"description": &graphql.Field{
+			Type:        graphql.String,
+			Description: "A description of the bundle",
+		},
+		"price": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The combined price of the bundle",
+		},
+		"isActive": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether the bundle can be booked",
+		},
+		"items": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(BundleItemType))),
+			Description: "The services of the bundle",
+		},
+		"steps": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(BundleStepType))),
+			Description: "The schedule of the services; empty when a service of the bundle no longer exists",
+		},
+		"duration": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "Minutes the bundle takes, with services overlapping processing time",
+		},
+		"sequentialDuration": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "Minutes the services would take one after another",
+		},
+		"regularPrice": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The sum of the service prices",
+		},
+		"savings": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "How much less the bundle costs than its services booked separately",
+		},
+	}),
+})
+
+// BundleItemInput represents the GraphQL input for a service of a bundle
+var BundleItemInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "BundleItemInput",
+	Description: "Input for a service of a bundle",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"serviceId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the service",
+		},
+		"processingMinutes": &graphql.InputObjectFieldConfig{
+			Type:         graphql.Int,
+			Description:  "Trailing minutes of the service during which the client waits",
+			DefaultValue: 0,
+		},
+		"duringPreviousProcessing": &graphql.InputObjectFieldConfig{
+			Type:         graphql.Boolean,
+			Description:  "Start the service as soon as the previous one starts processing",
+			DefaultValue: false,
+		},
+	},
+})
+
+// CreateServiceBundleInput represents the GraphQL input for creating a service bundle
+var CreateServiceBundleInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "CreateServiceBundleInput",
+	Description: "Input for creating a service bundle",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"businessId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"name": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The name of the bundle",
+		},
+		"description": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "A description of the bundle",
+		},
+		"price": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "The combined price of the bundle",
+		},
+		"isActive": &graphql.InputObjectFieldConfig{
+			Type:         graphql.Boolean,
+			Description:  "Whether the bundle can be booked",
+			DefaultValue: true,
+		},
+		"items": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(BundleItemInput))),
+			Description: "The services of the bundle, in the order they are performed",
+		},
+	},
+})
+
+// UpdateServiceBundleInput represents the GraphQL input for updating a service bundle
+var UpdateServiceBundleInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "UpdateServiceBundleInput",
+	Description: "Input for updating a service bundle",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"name": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The name of the bundle",
+		},
+		"description": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "A description of the bundle",
+		},
+		"price": &graphql.InputObjectFieldConfig{
+			Type:        DecimalScalar,
+			Description: "The combined price of the bundle",
+		},
+		"isActive": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Boolean,
+			Description: "Whether the bundle can be booked",
+		},
+		"items": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewList(graphql.NewNonNull(BundleItemInput)),
+			Description: "The services of the bundle, in the order they are performed",
+		},
+		"clear": clearField("UpdateServiceBundleInput", "description"),
+	},
+})
+
+// BookServiceBundleInput represents the GraphQL input for booking a service bundle
+var BookServiceBundleInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "BookServiceBundleInput",
+	Description: "Input for booking a service bundle as a single appointment",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"businessId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"bundleId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the bundle",
+		},
+		"clientId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the client",
+		},
+		"staffId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The ID of the staff member; required unless the business is solo",
+		},
+		"startTime": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the appointment starts; it ends after the bundle's duration",
+		},
+		"notes": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "Notes about the appointment",
+		},
+	},
+})
+
+// serviceBundleQueryFields returns the service bundle queries
+func serviceBundleQueryFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"serviceBundle": &graphql.Field{
+			Type:        ServiceBundleType,
+			Description: "Get a service bundle by ID",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the bundle",
+				},
+			},
+			Resolve: resolver.resolveServiceBundle,
+		},
+		"serviceBundles": &graphql.Field{
+			Type:        graphql.NewList(ServiceBundleType),
+			Description: "Get the service bundles of a business",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+				"activeOnly": &graphql.ArgumentConfig{
+					Type:         graphql.Boolean,
+					Description:  "Only return bundles that can be booked",
+					DefaultValue: false,
+				},
+			},
+			Resolve: resolver.resolveServiceBundles,
+		},
+	}
+}
+
+// serviceBundleMutationFields returns the service bundle mutations
+func serviceBundleMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"createServiceBundle": &graphql.Field{
+			Type:        ServiceBundleType,
+			Description: "Create a service bundle",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(CreateServiceBundleInput),
+					Description: "The bundle data",
+				},
+			},
+			Resolve: resolver.resolveCreateServiceBundle,
+		},
+		"updateServiceBundle": &graphql.Field{
+			Type:        ServiceBundleType,
+			Description: "Update a service bundle",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the bundle",
+				},
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(UpdateServiceBundleInput),
+					Description: "The fields to update",
+				},
+			},
+			Resolve: resolver.resolveUpdateServiceBundle,
+		},
+		"deleteServiceBundle": &graphql.Field{
+			Type:        DeleteResultType,
+			Description: "Delete a service bundle",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the bundle",
+				},
+			},
+			Resolve: resolver.resolveDeleteServiceBundle,
+		},
+		"bookServiceBundle": &graphql.Field{
+			Type:        AppointmentType,
+			Description: "Book the services of a bundle as a single appointment; fails when the staff member is not available",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(BookServiceBundleInput),
+					Description: "The booking data",
+				},
+			},
+			Resolve: resolver.resolveBookServiceBundle,
+		},
+	}
+}
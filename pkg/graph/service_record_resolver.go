@@ -0,0 +1,236 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Service Record Query Resolvers
+func (r *Resolver) resolveServiceRecord(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+
+	record, err := r.serviceRecordService.GetByID(p.Context, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+func (r *Resolver) resolveClientServiceHistory(p graphql.ResolveParams) (any, error) {
+	clientID, ok := p.Args["clientId"].(string)
+	if !ok {
+		return nil, errors.New("clientId is required")
+	}
+
+	page, pageSize := pageFromArgs(p.Args, 20)
+
+	records, _, err := r.serviceRecordService.GetClientHistory(p.Context, clientID, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+func (r *Resolver) resolveAppointmentServiceRecords(p graphql.ResolveParams) (any, error) {
+	appointmentID, ok := p.Args["appointmentId"].(string)
+	if !ok {
+		return nil, errors.New("appointmentId is required")
+	}
+
+	records, err := r.serviceRecordService.GetByAppointment(p.Context, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+func (r *Resolver) resolveLatestServiceRecord(p graphql.ResolveParams) (any, error) {
+	clientID, ok := p.Args["clientId"].(string)
+	if !ok {
+		return nil, errors.New("clientId is required")
+	}
+
+	record, err := r.serviceRecordService.GetLatestForClient(p.Context, clientID, optionalString(p.Args, "serviceId"))
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+func (r *Resolver) resolveSearchServiceRecords(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+
+	search := dto.ServiceRecordSearchDTO{}
+	if err := decodeValue(p.Args["filter"], &search, "filter"); err != nil {
+		return nil, err
+	}
+
+	page, pageSize := pageFromArgs(p.Args, 20)
+
+	records, _, err := r.serviceRecordService.SearchHistory(p.Context, businessID, search, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+func (r *Resolver) resolveServiceRecordTemplate(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+
+	template, err := r.serviceRecordService.GetTemplate(p.Context, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+func (r *Resolver) resolveServiceRecordTemplates(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+
+	templates, err := r.serviceRecordService.ListTemplates(p.Context, businessID)
+	if err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+// Service Record Mutation Resolvers
+func (r *Resolver) resolveCreateServiceRecordTemplate(p graphql.ResolveParams) (any, error) {
+	createDTO := dto.CreateServiceRecordTemplateDTO{}
+	if err := decodeInput(p.Args, &createDTO); err != nil {
+		return nil, err
+	}
+
+	template, err := r.serviceRecordService.CreateTemplate(p.Context, createDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+func (r *Resolver) resolveUpdateServiceRecordTemplate(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+
+	updateDTO := dto.UpdateServiceRecordTemplateDTO{}
+	if err := decodeInput(p.Args, &updateDTO); err != nil {
+		return nil, err
+	}
+
+	template, err := r.serviceRecordService.UpdateTemplate(p.Context, id, updateDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+func (r *Resolver) resolveDeleteServiceRecordTemplate(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+
+	err := r.serviceRecordService.DeleteTemplate(p.Context, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		"success": true,
+		"message": "Service record template deleted successfully",
+	}, nil
+}
+
+func (r *Resolver) resolveCreateServiceRecord(p graphql.ResolveParams) (any, error) {
+	createDTO := dto.CreateServiceRecordDTO{}
+	if err := decodeInput(p.Args, &createDTO); err != nil {
+		return nil, err
+	}
+
+	record, err := r.serviceRecordService.Create(p.Context, createDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+func (r *Resolver) resolveUpdateServiceRecord(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+
+	updateDTO := dto.UpdateServiceRecordDTO{}
+	if err := decodeInput(p.Args, &updateDTO); err != nil {
+		return nil, err
+	}
+
+	record, err := r.serviceRecordService.Update(p.Context, id, updateDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+func (r *Resolver) resolveDeleteServiceRecord(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+
+	err := r.serviceRecordService.Delete(p.Context, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		"success": true,
+		"message": "Service record deleted successfully",
+	}, nil
+}
+
+func (r *Resolver) resolveCopyServiceRecordForward(p graphql.ResolveParams) (any, error) {
+	recordID, ok := p.Args["recordId"].(string)
+	if !ok {
+		return nil, errors.New("recordId is required")
+	}
+	appointmentID, ok := p.Args["appointmentId"].(string)
+	if !ok {
+		return nil, errors.New("appointmentId is required")
+	}
+
+	record, err := r.serviceRecordService.CopyForward(p.Context, recordID, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
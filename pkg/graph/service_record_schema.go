@@ -0,0 +1,646 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// ServiceRecordFieldTypeEnum represents the GraphQL enum for service record template field types
+var ServiceRecordFieldTypeEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "ServiceRecordFieldType",
+	Description: "The kind of value a service record template field captures",
+	Values: graphql.EnumValueConfigMap{
+		"text": &graphql.EnumValueConfig{
+			Value:       domain.ServiceRecordFieldText,
+			Description: "Free text",
+		},
+		"number": &graphql.EnumValueConfig{
+			Value:       domain.ServiceRecordFieldNumber,
+			Description: "A numeric value, optionally with a unit",
+		},
+		"select": &graphql.EnumValueConfig{
+			Value:       domain.ServiceRecordFieldSelect,
+			Description: "One of a fixed set of options",
+		},
+		"duration": &graphql.EnumValueConfig{
+			Value:       domain.ServiceRecordFieldDuration,
+			Description: "A duration in minutes",
+		},
+	},
+})
+
+// ServiceRecordFieldDefinitionType represents the GraphQL type for a template field definition
+var ServiceRecordFieldDefinitionType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ServiceRecordFieldDefinition",
+	Description: "A field captured by a service record template",
+	Fields: graphql.Fields{
+		"key": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The key the value is stored under",
+		},
+		"label": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The label shown to staff",
+		},
+		"type": &graphql.Field{
+			Type:        graphql.NewNonNull(ServiceRecordFieldTypeEnum),
+			Description: "The kind of value the field captures",
+		},
+		"required": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether the field must be filled in on completed records",
+		},
+		"unit": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The unit of the value, e.g. vol, g or ml",
+		},
+		"options": &graphql.Field{
+			Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+			Description: "The allowed values for select fields",
+		},
+	},
+})
+
+// ServiceRecordTemplateType represents the GraphQL ServiceRecordTemplate type
+var ServiceRecordTemplateType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ServiceRecordTemplate",
+	Description: "The structured fields recorded for services in a category",
+	Fields: withBaseFields("template", graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"categoryId": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The ID of the service category the template applies to",
+		},
+		"name": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The name of the template",
+		},
+		"description": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The description of the template",
+		},
+		"fields": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(ServiceRecordFieldDefinitionType))),
+			Description: "The fields captured by the template",
+		},
+		"isActive": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether the template is used for new records",
+		},
+	}),
+})
+
+// ProductUsageType represents the GraphQL type for a product used during a service
+var ProductUsageType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ProductUsage",
+	Description: "A product used during a service",
+	Fields: graphql.Fields{
+		"productId": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The ID of the product, when tracked in inventory",
+		},
+		"name": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The name of the product",
+		},
+		"brand": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The brand of the product",
+		},
+		"shade": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The shade or variant used",
+		},
+		"amount": &graphql.Field{
+			Type:        graphql.Float,
+			Description: "The amount used",
+		},
+		"unit": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The unit of the amount",
+		},
+	},
+})
+
+// ServiceRecordType represents the GraphQL ServiceRecord type
+var ServiceRecordType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ServiceRecord",
+	Description: "A structured record of a service performed for a client, such as a colour formula",
+	Fields: withBaseFields("service record", graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"clientId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the client",
+		},
+		"appointmentId": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The ID of the appointment",
+		},
+		"serviceCompletionId": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The ID of the service completion the record is attached to",
+		},
+		"serviceId": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The ID of the service performed",
+		},
+		"staffId": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The ID of the staff member who performed the service",
+		},
+		"templateId": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The ID of the template the values follow",
+		},
+		"copiedFromId": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The ID of the record this one was copied forward from",
+		},
+		"recordedAt": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the service was recorded",
+		},
+		"fieldValues": &graphql.Field{
+			Type:        graphql.NewNonNull(JSONScalar),
+			Description: "The recorded values keyed by template field",
+		},
+		"productsUsed": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(ProductUsageType))),
+			Description: "The products used during the service",
+		},
+		"processingTime": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "The processing time in minutes",
+		},
+		"notes": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Free-form notes",
+		},
+		"isDraft": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether the record is a draft awaiting the visit",
+		},
+		"client": clientRelation(func(r *dto.ServiceRecordResponseDTO) string { return r.ClientID }),
+		"service": serviceRelation(func(r *dto.ServiceRecordResponseDTO) string {
+			if r.ServiceID == nil {
+				return ""
+			}
+			return *r.ServiceID
+		}),
+		"staff": staffRelation(func(r *dto.ServiceRecordResponseDTO) string {
+			if r.StaffID == nil {
+				return ""
+			}
+			return *r.StaffID
+		}),
+	}),
+})
+
+// ServiceRecordFieldInput represents the GraphQL input for a template field definition
+var ServiceRecordFieldInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "ServiceRecordFieldInput",
+	Description: "Input for a service record template field",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"key": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The key the value is stored under",
+		},
+		"label": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The label shown to staff",
+		},
+		"type": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(ServiceRecordFieldTypeEnum),
+			Description: "The kind of value the field captures",
+		},
+		"required": &graphql.InputObjectFieldConfig{
+			Type:         graphql.Boolean,
+			Description:  "Whether the field must be filled in on completed records",
+			DefaultValue: false,
+		},
+		"unit": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The unit of the value",
+		},
+		"options": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+			Description: "The allowed values for select fields",
+		},
+	},
+})
+
+// CreateServiceRecordTemplateInput represents the GraphQL input for creating a template
+var CreateServiceRecordTemplateInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "CreateServiceRecordTemplateInput",
+	Description: "Input for creating a service record template",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"businessId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"categoryId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The ID of the service category the template applies to",
+		},
+		"name": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The name of the template",
+		},
+		"description": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The description of the template",
+		},
+		"fields": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(ServiceRecordFieldInput))),
+			Description: "The fields captured by the template",
+		},
+	},
+})
+
+// UpdateServiceRecordTemplateInput represents the GraphQL input for updating a template
+var UpdateServiceRecordTemplateInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "UpdateServiceRecordTemplateInput",
+	Description: "Input for updating a service record template",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"categoryId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The ID of the service category the template applies to",
+		},
+		"name": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The name of the template",
+		},
+		"description": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The description of the template",
+		},
+		"fields": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewList(graphql.NewNonNull(ServiceRecordFieldInput)),
+			Description: "The fields captured by the template",
+		},
+		"isActive": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Boolean,
+			Description: "Whether the template is used for new records",
+		},
+		"clear": clearField("UpdateServiceRecordTemplateInput", "categoryId", "description"),
+	},
+})
+
+// ProductUsageInput represents the GraphQL input for a product used during a service
+var ProductUsageInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "ProductUsageInput",
+	Description: "Input for a product used during a service",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"productId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The ID of the product, when tracked in inventory",
+		},
+		"name": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The name of the product",
+		},
+		"brand": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The brand of the product",
+		},
+		"shade": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The shade or variant used",
+		},
+		"amount": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Float,
+			Description: "The amount used",
+		},
+		"unit": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The unit of the amount",
+		},
+	},
+})
+
+// CreateServiceRecordInput represents the GraphQL input for recording a service
+var CreateServiceRecordInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "CreateServiceRecordInput",
+	Description: "Input for recording a service performed for a client",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"businessId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"clientId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the client",
+		},
+		"appointmentId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The ID of the appointment",
+		},
+		"serviceCompletionId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The ID of the service completion; defaults to the appointment's completion",
+		},
+		"serviceId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The ID of the service performed",
+		},
+		"staffId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The ID of the staff member who performed the service",
+		},
+		"templateId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The ID of the template; defaults to the template for the service's category",
+		},
+		"recordedAt": &graphql.InputObjectFieldConfig{
+			Type:        graphql.DateTime,
+			Description: "When the service was recorded; defaults to now",
+		},
+		"fieldValues": &graphql.InputObjectFieldConfig{
+			Type:        JSONScalar,
+			Description: "The recorded values keyed by template field",
+		},
+		"productsUsed": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewList(graphql.NewNonNull(ProductUsageInput)),
+			Description: "The products used during the service",
+		},
+		"processingTime": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Int,
+			Description: "The processing time in minutes",
+		},
+		"notes": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "Free-form notes",
+		},
+		"isDraft": &graphql.InputObjectFieldConfig{
+			Type:         graphql.Boolean,
+			Description:  "Whether the record is a draft awaiting the visit",
+			DefaultValue: false,
+		},
+	},
+})
+
+// UpdateServiceRecordInput represents the GraphQL input for updating a service record
+var UpdateServiceRecordInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "UpdateServiceRecordInput",
+	Description: "Input for updating a service record",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"staffId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The ID of the staff member who performed the service",
+		},
+		"fieldValues": &graphql.InputObjectFieldConfig{
+			Type:        JSONScalar,
+			Description: "The recorded values keyed by template field",
+		},
+		"productsUsed": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewList(graphql.NewNonNull(ProductUsageInput)),
+			Description: "The products used during the service",
+		},
+		"processingTime": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Int,
+			Description: "The processing time in minutes",
+		},
+		"notes": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "Free-form notes",
+		},
+		"isDraft": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Boolean,
+			Description: "Whether the record is a draft awaiting the visit",
+		},
+		"clear": clearField("UpdateServiceRecordInput", "staffId", "processingTime", "notes"),
+	},
+})
+
+// ServiceRecordSearchInput represents the GraphQL input for searching service record history
+var ServiceRecordSearchInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "ServiceRecordSearchInput",
+	Description: "Criteria for searching service record history",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"clientId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "Only records for this client",
+		},
+		"serviceId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "Only records for this service",
+		},
+		"staffId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "Only records by this staff member",
+		},
+		"query": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "Text to match in notes, recorded values and products used",
+		},
+		"startDate": &graphql.InputObjectFieldConfig{
+			Type:        graphql.DateTime,
+			Description: "Only records on or after this time",
+		},
+		"endDate": &graphql.InputObjectFieldConfig{
+			Type:        graphql.DateTime,
+			Description: "Only records before this time",
+		},
+		"includeDrafts": &graphql.InputObjectFieldConfig{
+			Type:         graphql.Boolean,
+			Description:  "Whether to include draft records",
+			DefaultValue: false,
+		},
+	},
+})
+
+// serviceRecordQueryFields returns the client service history queries
+func serviceRecordQueryFields(resolver *Resolver) graphql.Fields {
+	historyArgs := paginationArgs("records", 20)
+	historyArgs["clientId"] = &graphql.ArgumentConfig{
+		Type:        graphql.NewNonNull(graphql.String),
+		Description: "The ID of the client",
+	}
+
+	searchArgs := paginationArgs("records", 20)
+	searchArgs["businessId"] = &graphql.ArgumentConfig{
+		Type:        graphql.NewNonNull(graphql.String),
+		Description: "The ID of the business",
+	}
+	searchArgs["filter"] = &graphql.ArgumentConfig{
+		Type:        ServiceRecordSearchInput,
+		Description: "The search criteria",
+	}
+
+	return graphql.Fields{
+		"serviceRecord": &graphql.Field{
+			Type:        ServiceRecordType,
+			Description: "Get a service record by ID",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the service record",
+				},
+			},
+			Resolve: resolver.resolveServiceRecord,
+		},
+		"clientServiceHistory": &graphql.Field{
+			Type:        graphql.NewList(ServiceRecordType),
+			Description: "Get a client's service records, most recent first",
+			Args:        historyArgs,
+			Resolve:     resolver.resolveClientServiceHistory,
+		},
+		"appointmentServiceRecords": &graphql.Field{
+			Type:        graphql.NewList(ServiceRecordType),
+			Description: "Get the service records captured for an appointment",
+			Args: graphql.FieldConfigArgument{
+				"appointmentId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the appointment",
+				},
+			},
+			Resolve: resolver.resolveAppointmentServiceRecords,
+		},
+		"latestServiceRecord": &graphql.Field{
+			Type:        ServiceRecordType,
+			Description: "Get a client's most recent completed service record",
+			Args: graphql.FieldConfigArgument{
+				"clientId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the client",
+				},
+				"serviceId": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: "Only consider records for this service",
+				},
+			},
+			Resolve: resolver.resolveLatestServiceRecord,
+		},
+		"searchServiceRecords": &graphql.Field{
+			Type:        graphql.NewList(ServiceRecordType),
+			Description: "Search a business's service record history",
+			Args:        searchArgs,
+			Resolve:     resolver.resolveSearchServiceRecords,
+		},
+		"serviceRecordTemplate": &graphql.Field{
+			Type:        ServiceRecordTemplateType,
+			Description: "Get a service record template by ID",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the template",
+				},
+			},
+			Resolve: resolver.resolveServiceRecordTemplate,
+		},
+		"serviceRecordTemplates": &graphql.Field{
+			Type:        graphql.NewList(ServiceRecordTemplateType),
+			Description: "Get the service record templates of a business",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+			},
+			Resolve: resolver.resolveServiceRecordTemplates,
+		},
+	}
+}
+
+// serviceRecordMutationFields returns the client service history mutations
+func serviceRecordMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"createServiceRecordTemplate": &graphql.Field{
+			Type:        ServiceRecordTemplateType,
+			Description: "Create a service record template",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(CreateServiceRecordTemplateInput),
+					Description: "The template data",
+				},
+			},
+			Resolve: resolver.resolveCreateServiceRecordTemplate,
+		},
+		"updateServiceRecordTemplate": &graphql.Field{
+			Type:        ServiceRecordTemplateType,
+			Description: "Update a service record template",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the template to update",
+				},
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(UpdateServiceRecordTemplateInput),
+					Description: "The updated template data",
+				},
+			},
+			Resolve: resolver.resolveUpdateServiceRecordTemplate,
+		},
+		"deleteServiceRecordTemplate": &graphql.Field{
+			Type:        DeleteResultType,
+			Description: "Delete a service record template",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the template to delete",
+				},
+			},
+			Resolve: resolver.resolveDeleteServiceRecordTemplate,
+		},
+		"createServiceRecord": &graphql.Field{
+			Type:        ServiceRecordType,
+			Description: "Record a service performed for a client",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(CreateServiceRecordInput),
+					Description: "The service record data",
+				},
+			},
+			Resolve: resolver.resolveCreateServiceRecord,
+		},
+		"updateServiceRecord": &graphql.Field{
+			Type:        ServiceRecordType,
+			Description: "Update a service record",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the service record to update",
+				},
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(UpdateServiceRecordInput),
+					Description: "The updated service record data",
+				},
+			},
+			Resolve: resolver.resolveUpdateServiceRecord,
+		},
+		"deleteServiceRecord": &graphql.Field{
+			Type:        DeleteResultType,
+			Description: "Delete a service record",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the service record to delete",
+				},
+			},
+			Resolve: resolver.resolveDeleteServiceRecord,
+		},
+		"copyServiceRecordForward": &graphql.Field{
+			Type:        ServiceRecordType,
+			Description: "Copy a service record onto a follow-up appointment as a draft",
+			Args: graphql.FieldConfigArgument{
+				"recordId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the service record to copy",
+				},
+				"appointmentId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the follow-up appointment",
+				},
+			},
+			Resolve: resolver.resolveCopyServiceRecordForward,
+		},
+	}
+}
@@ -0,0 +1,52 @@
+package graph
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/assimoes/beautix/internal/infrastructure/auth"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/assimoes/beautix/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+// ClerkWebhookPath is the path Clerk posts its webhook events to
+const ClerkWebhookPath = "/webhooks/clerk"
+
+// maxClerkEventBytes bounds the body of a webhook event; Clerk's user events are far smaller
+const maxClerkEventBytes = 1 << 16
+
+// ClerkWebhookHandler receives the webhook events Clerk posts as users change, signing their
+// other sessions out when their password or two-factor authentication changes. Events that fail
+// to be handled are answered with an error, so that Clerk retries them; events it cannot verify
+// are refused.
+func ClerkWebhookHandler(sessionService service.SessionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Only POST requests are allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		payload, err := io.ReadAll(io.LimitReader(r.Body, maxClerkEventBytes))
+		if err != nil {
+			http.Error(w, "Failed to read event", http.StatusBadRequest)
+			return
+		}
+
+		err = sessionService.HandleClerkEvent(r.Context(), payload, auth.WebhookSignature{
+			ID:        r.Header.Get(auth.WebhookIDHeader),
+			Timestamp: r.Header.Get(auth.WebhookTimestampHeader),
+			Signature: r.Header.Get(auth.WebhookSignatureHeader),
+		})
+		var validationErr *validation.ValidationError
+		switch {
+		case errors.Is(err, auth.ErrInvalidSignature), errors.Is(err, auth.ErrWebhookNotConfigured), errors.As(err, &validationErr):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case err != nil:
+			log.Error().Err(err).Msg("Failed to handle Clerk event")
+			http.Error(w, "Failed to handle event", http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}
+}
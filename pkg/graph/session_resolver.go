@@ -0,0 +1,49 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Session Query Resolvers
+func (r *Resolver) resolveMySessions(p graphql.ResolveParams) (any, error) {
+	sessions, err := r.sessionService.ListMySessions(p.Context)
+	if err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// Session Mutation Resolvers
+func (r *Resolver) resolveRevokeSession(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+
+	session, err := r.sessionService.RevokeSession(p.Context, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (r *Resolver) resolveRevokeAllSessions(p graphql.ResolveParams) (any, error) {
+	reason, ok := p.Args["reason"].(domain.SessionRevocationReason)
+	if !ok {
+		return nil, errors.New("reason is required")
+	}
+
+	result, err := r.sessionService.RevokeAllSessions(p.Context, dto.RevokeAllSessionsDTO{Reason: reason})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,138 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// SessionKindEnum represents the GraphQL enum for how sessions authenticate
+var SessionKindEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "SessionKind",
+	Description: "How a session authenticates its requests",
+	Values: graphql.EnumValueConfigMap{
+		"CLERK": &graphql.EnumValueConfig{
+			Value:       domain.SessionKindClerk,
+			Description: "A browser or app signed in through Clerk",
+		},
+		"API_KEY": &graphql.EnumValueConfig{
+			Value:       domain.SessionKindAPIKey,
+			Description: "An API key issued to the user",
+		},
+	},
+})
+
+// SessionRevocationReasonEnum represents the GraphQL enum for why every session is signed out
+var SessionRevocationReasonEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "SessionRevocationReason",
+	Description: "Why every device of a user is signed out",
+	Values: graphql.EnumValueConfigMap{
+		"SIGN_OUT_ALL": &graphql.EnumValueConfig{
+			Value:       domain.SessionRevokedSignOutAll,
+			Description: "The user chose to sign out everywhere",
+		},
+		"PASSWORD_CHANGE": &graphql.EnumValueConfig{
+			Value:       domain.SessionRevokedPasswordChange,
+			Description: "The password was changed",
+		},
+		"TWO_FACTOR_CHANGE": &graphql.EnumValueConfig{
+			Value:       domain.SessionRevokedTwoFactorChange,
+			Description: "Two-factor authentication was set up or removed",
+		},
+	},
+})
+
+// UserSessionType represents the GraphQL UserSession type
+var UserSessionType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "UserSession",
+	Description: "A device or API key a user is signed in with",
+	Fields: withBaseFields("session", graphql.Fields{
+		"kind": &graphql.Field{
+			Type:        graphql.NewNonNull(SessionKindEnum),
+			Description: "How the session authenticates",
+		},
+		"deviceName": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The device, e.g. Chrome on macOS",
+		},
+		"userAgent": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The user agent of the device",
+		},
+		"ipAddress": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The IP address the session was last used from",
+		},
+		"issuedAt": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the session was signed in",
+		},
+		"lastSeenAt": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the session was last used",
+		},
+		"revokedAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the session was signed out",
+		},
+		"current": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether this is the session the request was made with",
+		},
+	}),
+})
+
+// RevokeAllSessionsResultType represents the GraphQL result of signing out every device
+var RevokeAllSessionsResultType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "RevokeAllSessionsResult",
+	Description: "The outcome of signing out every device of a user",
+	Fields: graphql.Fields{
+		"revokedCount": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "How many recorded sessions were signed out",
+		},
+		"revokedAt": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "Sessions signed in before then are refused",
+		},
+	},
+})
+
+// sessionQueryFields returns the session queries
+func sessionQueryFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"mySessions": &graphql.Field{
+			Type:        graphql.NewList(UserSessionType),
+			Description: "List the devices and API keys the caller is signed in with, most recently used first",
+			Resolve:     resolver.resolveMySessions,
+		},
+	}
+}
+
+// sessionMutationFields returns the session mutations
+func sessionMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"revokeSession": &graphql.Field{
+			Type:        UserSessionType,
+			Description: "Sign out one of the caller's devices or API keys",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the session",
+				},
+			},
+			Resolve: resolver.resolveRevokeSession,
+		},
+		"revokeAllSessions": &graphql.Field{
+			Type:        RevokeAllSessionsResultType,
+			Description: "Sign out every device and API key of the caller, including the one asking; to be called when the password or two-factor authentication changes",
+			Args: graphql.FieldConfigArgument{
+				"reason": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(SessionRevocationReasonEnum),
+					Description: "Why every device is signed out",
+				},
+			},
+			Resolve: resolver.resolveRevokeAllSessions,
+		},
+	}
+}
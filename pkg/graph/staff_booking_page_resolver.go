@@ -0,0 +1,39 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Staff Booking Page Query Resolvers
+func (r *Resolver) resolveStaffBookingPage(p graphql.ResolveParams) (any, error) {
+	staffID, ok := p.Args["staffId"].(string)
+	if !ok {
+		return nil, errors.New("staffId is required")
+	}
+
+	page, err := r.staffBookingPageService.GetStaffBookingPage(p.Context, staffID)
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// Staff Booking Page Mutation Resolvers
+func (r *Resolver) resolveSaveStaffBookingPage(p graphql.ResolveParams) (any, error) {
+	saveDTO := dto.SaveStaffBookingPageDTO{}
+	if err := decodeInput(p.Args, &saveDTO); err != nil {
+		return nil, err
+	}
+
+	page, err := r.staffBookingPageService.SaveStaffBookingPage(p.Context, saveDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
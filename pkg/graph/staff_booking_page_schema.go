@@ -0,0 +1,111 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// StaffBookingPageType represents the GraphQL StaffBookingPage type
+var StaffBookingPageType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "StaffBookingPage",
+	Description: "A staff member's personal booking page, shared so that clients book with them directly",
+	Fields: withBaseFields("booking page", graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"staffId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the staff member",
+		},
+		"staff": staffRelation(func(p *dto.StaffBookingPageResponseDTO) string { return p.StaffID }),
+		"slug": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The slug identifying the page in its link",
+		},
+		"bio": &graphql.Field{
+			Type:        graphql.String,
+			Description: "What the staff member tells clients about themselves",
+		},
+		"portfolioUrls": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+			Description: "The images of the staff member's work",
+		},
+		"serviceIds": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+			Description: "The IDs of the services that can be booked from the page; all of the business's when empty",
+		},
+		"isPublished": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether the page can be visited",
+		},
+	}),
+})
+
+// SaveStaffBookingPageInput represents the GraphQL input for creating or updating a booking page
+var SaveStaffBookingPageInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "SaveStaffBookingPageInput",
+	Description: "Input for creating or updating a staff member's booking page; fields left out keep their value",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"staffId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the staff member",
+		},
+		"slug": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The slug of the page's link; derived from the staff member's name when a page is created without one",
+		},
+		"bio": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "What the staff member tells clients about themselves",
+		},
+		"portfolioUrls": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+			Description: "The images of the staff member's work",
+		},
+		"serviceIds": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+			Description: "The IDs of the services the staff member performs; an empty list offers all of the business's services",
+		},
+		"isPublished": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Boolean,
+			Description: "Whether the page can be visited",
+		},
+		"clear": clearField("SaveStaffBookingPageInput", "bio"),
+	},
+})
+
+// staffBookingPageQueryFields returns the staff booking page queries
+func staffBookingPageQueryFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"staffBookingPage": &graphql.Field{
+			Type:        StaffBookingPageType,
+			Description: "Get a staff member's booking page",
+			Args: graphql.FieldConfigArgument{
+				"staffId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the staff member",
+				},
+			},
+			Resolve: resolver.resolveStaffBookingPage,
+		},
+	}
+}
+
+// staffBookingPageMutationFields returns the staff booking page mutations
+func staffBookingPageMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"saveStaffBookingPage": &graphql.Field{
+			Type:        StaffBookingPageType,
+			Description: "Create or update a staff member's booking page",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(SaveStaffBookingPageInput),
+					Description: "The booking page",
+				},
+			},
+			Resolve: resolver.resolveSaveStaffBookingPage,
+		},
+	}
+}
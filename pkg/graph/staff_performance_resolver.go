@@ -0,0 +1,70 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Staff Performance Query Resolvers
+func (r *Resolver) resolveStaffPerformance(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+	period, ok := p.Args["period"].(domain.PerformancePeriod)
+	if !ok {
+		return nil, errors.New("period is required")
+	}
+	startDate, ok := p.Args["startDate"].(string)
+	if !ok {
+		return nil, errors.New("startDate is required")
+	}
+	endDate, ok := p.Args["endDate"].(string)
+	if !ok {
+		return nil, errors.New("endDate is required")
+	}
+
+	records, err := r.staffPerformanceService.GetStaffPerformance(p.Context, dto.StaffPerformanceQueryDTO{
+		BusinessID: businessID,
+		Period:     period,
+		StartDate:  startDate,
+		EndDate:    endDate,
+		StaffID:    optionalString(p.Args, "staffId"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// Staff Performance Mutation Resolvers
+func (r *Resolver) resolveBackfillStaffPerformance(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+	startDate, ok := p.Args["startDate"].(string)
+	if !ok {
+		return nil, errors.New("startDate is required")
+	}
+	endDate, ok := p.Args["endDate"].(string)
+	if !ok {
+		return nil, errors.New("endDate is required")
+	}
+
+	job, err := r.staffPerformanceService.BackfillStaffPerformance(p.Context, dto.BackfillStaffPerformanceDTO{
+		BusinessID: businessID,
+		StartDate:  startDate,
+		EndDate:    endDate,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
@@ -0,0 +1,151 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// PerformancePeriodEnum represents the GraphQL enum for the periods of staff performance
+var PerformancePeriodEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "PerformancePeriod",
+	Description: "The length of the periods staff performance is aggregated over",
+	Values: graphql.EnumValueConfigMap{
+		"DAILY": &graphql.EnumValueConfig{
+			Value:       domain.PerformanceDaily,
+			Description: "A day in the business's time zone",
+		},
+		"WEEKLY": &graphql.EnumValueConfig{
+			Value:       domain.PerformanceWeekly,
+			Description: "A week, Monday to Sunday",
+		},
+		"MONTHLY": &graphql.EnumValueConfig{
+			Value:       domain.PerformanceMonthly,
+			Description: "A calendar month",
+		},
+	},
+})
+
+// StaffPerformanceType represents the GraphQL StaffPerformance type
+var StaffPerformanceType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "StaffPerformance",
+	Description: "What a staff member did during a period, aggregated from their appointments",
+	Fields: withBaseFields("performance record", graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"staffId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the staff member",
+		},
+		"staff": staffRelation(func(p *dto.StaffPerformanceResponseDTO) string { return p.StaffID }),
+		"period": &graphql.Field{
+			Type:        graphql.NewNonNull(PerformancePeriodEnum),
+			Description: "The length of the period",
+		},
+		"startDate": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "The start of the period",
+		},
+		"endDate": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "The start of the next period",
+		},
+		"totalAppointments": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The appointments starting in the period, rescheduled ones aside",
+		},
+		"completedAppointments": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The appointments completed",
+		},
+		"canceledAppointments": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The appointments cancelled",
+		},
+		"noShowAppointments": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The appointments the client did not show up for",
+		},
+		"totalRevenue": &graphql.Field{
+			Type:        graphql.NewNonNull(DecimalScalar),
+			Description: "What was charged for the completed appointments",
+		},
+		"averageRating": &graphql.Field{
+			Type:        DecimalScalar,
+			Description: "The average of the clients' ratings, 1 to 5; null when no appointment was rated",
+		},
+		"clientRetentionRate": &graphql.Field{
+			Type:        DecimalScalar,
+			Description: "The percentage of the clients served who had been to the business before; null when none were served",
+		},
+		"newClients": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The clients served on their first visit to the business",
+		},
+		"returnClients": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The clients served who had been to the business before",
+		},
+	}),
+})
+
+// staffPerformanceQueryFields returns the staff performance queries
+func staffPerformanceQueryFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"staffPerformance": &graphql.Field{
+			Type:        graphql.NewList(StaffPerformanceType),
+			Description: "Get the performance records of the periods starting between two dates, by period start (staff who may view reports see everyone's, other staff their own)",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+				"period": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(PerformancePeriodEnum),
+					Description: "The length of the periods",
+				},
+				"startDate": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The first day periods may start on, as YYYY-MM-DD",
+				},
+				"endDate": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The last day periods may start on, as YYYY-MM-DD",
+				},
+				"staffId": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: "Only this staff member",
+				},
+			},
+			Resolve: resolver.resolveStaffPerformance,
+		},
+	}
+}
+
+// staffPerformanceMutationFields returns the staff performance mutations
+func staffPerformanceMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"backfillStaffPerformance": &graphql.Field{
+			Type:        JobType,
+			Description: "Aggregate the staff performance of past dates in the background, such as those before the aggregation ran (owners and managers)",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+				"startDate": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The first day, as YYYY-MM-DD",
+				},
+				"endDate": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The last day, as YYYY-MM-DD; at most two years after the first",
+				},
+			},
+			Resolve: resolver.resolveBackfillStaffPerformance,
+		},
+	}
+}
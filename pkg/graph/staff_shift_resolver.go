@@ -0,0 +1,95 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Staff Shift Query Resolvers
+func (r *Resolver) resolveStaffShifts(p graphql.ResolveParams) (any, error) {
+	staffID, ok := p.Args["staffId"].(string)
+	if !ok {
+		return nil, errors.New("staffId is required")
+	}
+
+	shifts, err := r.staffShiftService.GetStaffShifts(p.Context, staffID)
+	if err != nil {
+		return nil, err
+	}
+
+	return shifts, nil
+}
+
+func (r *Resolver) resolveStaffRota(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+	weekOf, ok := p.Args["weekOf"].(string)
+	if !ok {
+		return nil, errors.New("weekOf is required")
+	}
+
+	rota, err := r.staffShiftService.GetRota(p.Context, dto.RotaQueryDTO{
+		BusinessID: businessID,
+		WeekOf:     weekOf,
+		StaffID:    optionalString(p.Args, "staffId"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rota, nil
+}
+
+// Staff Shift Mutation Resolvers
+func (r *Resolver) resolveCreateStaffShift(p graphql.ResolveParams) (any, error) {
+	createDTO := dto.CreateStaffShiftDTO{}
+	if err := decodeInput(p.Args, &createDTO); err != nil {
+		return nil, err
+	}
+
+	shift, err := r.staffShiftService.CreateStaffShift(p.Context, createDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return shift, nil
+}
+
+func (r *Resolver) resolveUpdateStaffShift(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+	updateDTO := dto.UpdateStaffShiftDTO{}
+	if err := decodeInput(p.Args, &updateDTO); err != nil {
+		return nil, err
+	}
+
+	shift, err := r.staffShiftService.UpdateStaffShift(p.Context, id, updateDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return shift, nil
+}
+
+func (r *Resolver) resolveDeleteStaffShift(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+
+	if err := r.staffShiftService.DeleteStaffShift(p.Context, id); err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		"success": true,
+		"message": "Staff shift deleted successfully",
+	}, nil
+}
@@ -0,0 +1,335 @@
+package graph
+
+import (
+	"time"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// WeekdayEnum represents the GraphQL enum for days of the week
+var WeekdayEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "Weekday",
+	Description: "A day of the week",
+	Values: graphql.EnumValueConfigMap{
+		"MONDAY":    &graphql.EnumValueConfig{Value: int(time.Monday)},
+		"TUESDAY":   &graphql.EnumValueConfig{Value: int(time.Tuesday)},
+		"WEDNESDAY": &graphql.EnumValueConfig{Value: int(time.Wednesday)},
+		"THURSDAY":  &graphql.EnumValueConfig{Value: int(time.Thursday)},
+		"FRIDAY":    &graphql.EnumValueConfig{Value: int(time.Friday)},
+		"SATURDAY":  &graphql.EnumValueConfig{Value: int(time.Saturday)},
+		"SUNDAY":    &graphql.EnumValueConfig{Value: int(time.Sunday)},
+	},
+})
+
+// StaffShiftKindEnum represents the GraphQL enum for how shifts repeat
+var StaffShiftKindEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "StaffShiftKind",
+	Description: "How a shift repeats",
+	Values: graphql.EnumValueConfigMap{
+		"RECURRING": &graphql.EnumValueConfig{
+			Value:       domain.StaffShiftRecurring,
+			Description: "Every week on a weekday",
+		},
+		"ONE_OFF": &graphql.EnumValueConfig{
+			Value:       domain.StaffShiftOneOff,
+			Description: "On a single date, on top of the weekly pattern",
+		},
+	},
+})
+
+// StaffShiftType represents the GraphQL StaffShift type
+var StaffShiftType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "StaffShift",
+	Description: "A period a staff member works. Staff members with shifts can only be booked during them.",
+	Fields: withBaseFields("staff shift", graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"staffId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the staff member",
+		},
+		"staff": staffRelation(func(s *dto.StaffShiftResponseDTO) string { return s.StaffID }),
+		"kind": &graphql.Field{
+			Type:        graphql.NewNonNull(StaffShiftKindEnum),
+			Description: "How the shift repeats",
+		},
+		"weekday": &graphql.Field{
+			Type:        WeekdayEnum,
+			Description: "The day of the week of a recurring shift",
+		},
+		"shiftDate": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The date of a one-off shift, as YYYY-MM-DD",
+		},
+		"startTime": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "When the shift starts, as HH:MM in the business's time zone",
+		},
+		"endTime": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "When the shift ends, as HH:MM in the business's time zone; 24:00 for midnight",
+		},
+		"effectiveFrom": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The first date a recurring shift is worked, as YYYY-MM-DD; no limit when null",
+		},
+		"effectiveUntil": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The last date a recurring shift is worked, as YYYY-MM-DD; no limit when null",
+		},
+		"notes": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Notes on the shift",
+		},
+	}),
+})
+
+// RotaShiftType represents the GraphQL RotaShift type
+var RotaShiftType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "RotaShift",
+	Description: "A shift worked on a day of a rota",
+	Fields: graphql.Fields{
+		"shiftId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the shift, to change or delete it",
+		},
+		"kind": &graphql.Field{
+			Type:        graphql.NewNonNull(StaffShiftKindEnum),
+			Description: "How the shift repeats",
+		},
+		"date": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The day the shift is worked, as YYYY-MM-DD",
+		},
+		"startTime": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the shift starts",
+		},
+		"endTime": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the shift ends",
+		},
+		"notes": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Notes on the shift",
+		},
+	},
+})
+
+// StaffRotaType represents the GraphQL StaffRota type
+var StaffRotaType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "StaffRota",
+	Description: "The week of a staff member",
+	Fields: graphql.Fields{
+		"staffId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the staff member",
+		},
+		"staff": staffRelation(func(s *dto.StaffRotaDTO) string { return s.StaffID }),
+		"onRota": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether the staff member has shifts; those who do not can be booked whenever the business is open",
+		},
+		"shifts": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(RotaShiftType))),
+			Description: "The shifts worked during the week, in order",
+		},
+		"minutes": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The time on shift during the week, in minutes, overlapping shifts counted once",
+		},
+	},
+})
+
+// RotaType represents the GraphQL Rota type
+var RotaType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Rota",
+	Description: "The week of shifts of the staff of a business, Monday to Sunday",
+	Fields: graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"weekStart": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The Monday starting the week, as YYYY-MM-DD",
+		},
+		"days": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+			Description: "The days of the week, Monday to Sunday, as YYYY-MM-DD",
+		},
+		"staff": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(StaffRotaType))),
+			Description: "The week of each staff member",
+		},
+	},
+})
+
+// CreateStaffShiftInput represents the GraphQL input for adding a shift
+var CreateStaffShiftInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "CreateStaffShiftInput",
+	Description: "Input for adding a shift to a staff member's rota",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"staffId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the staff member",
+		},
+		"kind": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(StaffShiftKindEnum),
+			Description: "How the shift repeats",
+		},
+		"weekday": &graphql.InputObjectFieldConfig{
+			Type:        WeekdayEnum,
+			Description: "The day of the week of a recurring shift",
+		},
+		"shiftDate": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The date of a one-off shift, as YYYY-MM-DD",
+		},
+		"startTime": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "When the shift starts, as HH:MM in the business's time zone",
+		},
+		"endTime": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "When the shift ends, as HH:MM in the business's time zone; 24:00 for midnight",
+		},
+		"effectiveFrom": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The first date a recurring shift is worked, as YYYY-MM-DD",
+		},
+		"effectiveUntil": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The last date a recurring shift is worked, as YYYY-MM-DD",
+		},
+		"notes": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "Notes on the shift",
+		},
+	},
+})
+
+// UpdateStaffShiftInput represents the GraphQL input for changing a shift
+var UpdateStaffShiftInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "UpdateStaffShiftInput",
+	Description: "Input for changing a shift; absent fields are left as they are",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"weekday": &graphql.InputObjectFieldConfig{
+			Type:        WeekdayEnum,
+			Description: "The day of the week of a recurring shift",
+		},
+		"shiftDate": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The date of a one-off shift, as YYYY-MM-DD",
+		},
+		"startTime": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "When the shift starts, as HH:MM",
+		},
+		"endTime": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "When the shift ends, as HH:MM; 24:00 for midnight",
+		},
+		"effectiveFrom": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The first date a recurring shift is worked, as YYYY-MM-DD",
+		},
+		"effectiveUntil": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The last date a recurring shift is worked, as YYYY-MM-DD",
+		},
+		"notes": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "Notes on the shift",
+		},
+		"expectedVersion": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Int,
+			Description: "The version of the shift the change is based on; fails with VERSION_CONFLICT when someone else changed it since",
+		},
+		"clear": clearField("UpdateStaffShiftInput", "effectiveFrom", "effectiveUntil", "notes"),
+	},
+})
+
+// staffShiftQueryFields returns the staff shift queries
+func staffShiftQueryFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"staffShifts": &graphql.Field{
+			Type:        graphql.NewList(StaffShiftType),
+			Description: "Get the shifts of a staff member, weekly ones first (staff of the business)",
+			Args: graphql.FieldConfigArgument{
+				"staffId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the staff member",
+				},
+			},
+			Resolve: resolver.resolveStaffShifts,
+		},
+		"staffRota": &graphql.Field{
+			Type:        RotaType,
+			Description: "Get the shifts worked during a week, Monday to Sunday, by the staff of a business (staff of the business)",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+				"weekOf": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "Any day of the week, as YYYY-MM-DD",
+				},
+				"staffId": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: "Only this staff member; every active staff member when omitted",
+				},
+			},
+			Resolve: resolver.resolveStaffRota,
+		},
+	}
+}
+
+// staffShiftMutationFields returns the staff shift mutations
+func staffShiftMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"createStaffShift": &graphql.Field{
+			Type:        StaffShiftType,
+			Description: "Add a shift to a staff member's rota (owners and managers)",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(CreateStaffShiftInput),
+					Description: "The shift to add",
+				},
+			},
+			Resolve: resolver.resolveCreateStaffShift,
+		},
+		"updateStaffShift": &graphql.Field{
+			Type:        StaffShiftType,
+			Description: "Change a shift; appointments already booked are kept (owners and managers)",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the shift",
+				},
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(UpdateStaffShiftInput),
+					Description: "The changes to the shift",
+				},
+			},
+			Resolve: resolver.resolveUpdateStaffShift,
+		},
+		"deleteStaffShift": &graphql.Field{
+			Type:        DeleteResultType,
+			Description: "Remove a shift from a staff member's rota (owners and managers)",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the shift",
+				},
+			},
+			Resolve: resolver.resolveDeleteStaffShift,
+		},
+	}
+}
@@ -0,0 +1,47 @@
+package graph
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/assimoes/beautix/internal/infrastructure/stripe"
+	"github.com/assimoes/beautix/internal/infrastructure/validation"
+	"github.com/assimoes/beautix/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+// StripeWebhookPath is the path Stripe posts its webhook events to
+const StripeWebhookPath = "/webhooks/stripe"
+
+// maxStripeEventBytes bounds the body of a webhook event; Stripe's events are far smaller
+const maxStripeEventBytes = 1 << 16
+
+// StripeWebhookHandler receives the webhook events Stripe posts as business subscriptions
+// change. Events that fail to be handled are answered with an error, so that Stripe retries
+// them; events it cannot verify are refused.
+func StripeWebhookHandler(subscriptionService service.SubscriptionService) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Only POST requests are allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		payload, err := io.ReadAll(io.LimitReader(r.Body, maxStripeEventBytes))
+		if err != nil {
+			http.Error(w, "Failed to read event", http.StatusBadRequest)
+			return
+		}
+
+		err = subscriptionService.HandleStripeEvent(r.Context(), payload, r.Header.Get(stripe.SignatureHeader))
+		var validationErr *validation.ValidationError
+		switch {
+		case errors.Is(err, stripe.ErrInvalidSignature), errors.Is(err, stripe.ErrNotConfigured), errors.As(err, &validationErr):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case err != nil:
+			log.Error().Err(err).Msg("Failed to handle Stripe event")
+			http.Error(w, "Failed to handle event", http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}
+}
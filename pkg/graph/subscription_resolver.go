@@ -0,0 +1,46 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Subscription Query Resolvers
+func (r *Resolver) resolveBusinessSubscription(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+
+	subscription, err := r.subscriptionService.GetSubscription(p.Context, businessID)
+	if err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+// Subscription Mutation Resolvers
+func (r *Resolver) resolveChangeSubscriptionPlan(p graphql.ResolveParams) (any, error) {
+	changeDTO := dto.ChangeSubscriptionPlanDTO{}
+	if businessID, ok := p.Args["businessId"].(string); ok {
+		changeDTO.BusinessID = businessID
+	}
+	if tier, ok := p.Args["tier"].(domain.SubscriptionTier); ok {
+		changeDTO.Tier = tier
+	}
+	if paymentMethodID, ok := p.Args["paymentMethodId"].(string); ok {
+		changeDTO.PaymentMethodID = &paymentMethodID
+	}
+
+	subscription, err := r.subscriptionService.ChangeSubscriptionPlan(p.Context, changeDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
@@ -0,0 +1,195 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// SubscriptionTierEnum represents the GraphQL enum for the plan a business pays for
+var SubscriptionTierEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "SubscriptionTier",
+	Description: "The plan a business pays for, which decides how much of the app it can use",
+	Values: graphql.EnumValueConfigMap{
+		"FREE": &graphql.EnumValueConfig{
+			Value:       domain.TierFree,
+			Description: "One staff member and location, no campaigns and 25 text messages a month",
+		},
+		"PRO": &graphql.EnumValueConfig{
+			Value:       domain.TierPro,
+			Description: "Five staff members, two locations, five campaigns and 500 text messages a month",
+		},
+		"PREMIUM": &graphql.EnumValueConfig{
+			Value:       domain.TierPremium,
+			Description: "Unlimited staff, locations and campaigns and 2000 text messages a month",
+		},
+	},
+})
+
+// SubscriptionStatusEnum represents the GraphQL enum for the billing status of a subscription
+var SubscriptionStatusEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "SubscriptionStatus",
+	Description: "The billing status of a business's subscription, as Stripe reports it",
+	Values: graphql.EnumValueConfigMap{
+		"INCOMPLETE": &graphql.EnumValueConfig{
+			Value:       domain.SubscriptionIncomplete,
+			Description: "The first payment has not gone through yet",
+		},
+		"INCOMPLETE_EXPIRED": &graphql.EnumValueConfig{
+			Value:       domain.SubscriptionIncompleteExpired,
+			Description: "The first payment never went through",
+		},
+		"TRIALING": &graphql.EnumValueConfig{
+			Value:       domain.SubscriptionTrialing,
+			Description: "In a free trial of the tier",
+		},
+		"ACTIVE": &graphql.EnumValueConfig{
+			Value:       domain.SubscriptionActive,
+			Description: "Paid up",
+		},
+		"PAST_DUE": &graphql.EnumValueConfig{
+			Value:       domain.SubscriptionPastDue,
+			Description: "A payment failed and is being retried; the business keeps its tier meanwhile",
+		},
+		"UNPAID": &graphql.EnumValueConfig{
+			Value:       domain.SubscriptionUnpaid,
+			Description: "Payment retries gave up; the business is on the free tier",
+		},
+		"CANCELED": &graphql.EnumValueConfig{
+			Value:       domain.SubscriptionCanceled,
+			Description: "Canceled; the business is on the free tier",
+		},
+		"PAUSED": &graphql.EnumValueConfig{
+			Value:       domain.SubscriptionPaused,
+			Description: "Paused; the business is on the free tier",
+		},
+	},
+})
+
+// EntitlementEnum represents the GraphQL enum for what a plan allows a limited amount of
+var EntitlementEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "Entitlement",
+	Description: "Something a subscription plan allows a business a limited amount of",
+	Values: graphql.EnumValueConfigMap{
+		"STAFF": &graphql.EnumValueConfig{
+			Value:       domain.EntitlementStaff,
+			Description: "Active staff members, the owner included",
+		},
+		"LOCATIONS": &graphql.EnumValueConfig{
+			Value:       domain.EntitlementLocations,
+			Description: "Active locations",
+		},
+		"CAMPAIGNS": &graphql.EnumValueConfig{
+			Value:       domain.EntitlementCampaigns,
+			Description: "Active campaigns that have not ended",
+		},
+		"SMS_CREDITS": &graphql.EnumValueConfig{
+			Value:       domain.EntitlementSMSCredits,
+			Description: "Text messages sent this calendar month, in UTC",
+		},
+	},
+})
+
+// EntitlementUsageType represents the GraphQL EntitlementUsage type
+var EntitlementUsageType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "EntitlementUsage",
+	Description: "How much of an entitlement a business uses and its plan allows",
+	Fields: graphql.Fields{
+		"entitlement": &graphql.Field{
+			Type:        graphql.NewNonNull(EntitlementEnum),
+			Description: "The entitlement",
+		},
+		"used": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "How much the business uses",
+		},
+		"limit": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "How much the plan allows; null when unlimited",
+		},
+	},
+})
+
+// BusinessSubscriptionType represents the GraphQL BusinessSubscription type
+var BusinessSubscriptionType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "BusinessSubscription",
+	Description: "The subscription tier of a business and what it uses of its plan",
+	Fields: graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"tier": &graphql.Field{
+			Type:        graphql.NewNonNull(SubscriptionTierEnum),
+			Description: "The tier the business has now",
+		},
+		"subscribedTier": &graphql.Field{
+			Type:        SubscriptionTierEnum,
+			Description: "The paid tier subscribed to; null when the business never subscribed",
+		},
+		"status": &graphql.Field{
+			Type:        SubscriptionStatusEnum,
+			Description: "The billing status of the subscription",
+		},
+		"currentPeriodEnd": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the paid period ends and the next is charged",
+		},
+		"trialEndsAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the free trial ends",
+		},
+		"cancelAtPeriodEnd": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether the subscription ends, and the business moves to the free tier, at the end of the paid period",
+		},
+		"usage": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(EntitlementUsageType))),
+			Description: "What the business uses of each entitlement",
+		},
+	},
+})
+
+// subscriptionQueryFields returns the subscription queries
+func subscriptionQueryFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"businessSubscription": &graphql.Field{
+			Type:        BusinessSubscriptionType,
+			Description: "Get the subscription tier of a business and what it uses of its plan (managers only)",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+			},
+			Resolve: resolver.resolveBusinessSubscription,
+		},
+	}
+}
+
+// subscriptionMutationFields returns the subscription mutations
+func subscriptionMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"changeSubscriptionPlan": &graphql.Field{
+			Type: BusinessSubscriptionType,
+			Description: "Move a business to another tier (owners only). Paid tiers are charged at once, pro-rated when " +
+				"changing between them; moving to the free tier cancels the subscription at the end of the paid period. " +
+				"A business cannot move to a tier allowing less than it uses.",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+				"tier": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(SubscriptionTierEnum),
+					Description: "The tier to move to",
+				},
+				"paymentMethodId": &graphql.ArgumentConfig{
+					Type:        graphql.String,
+					Description: "A Stripe payment method to charge, required when the business has none on file",
+				},
+			},
+			Resolve: resolver.resolveChangeSubscriptionPlan,
+		},
+	}
+}
@@ -0,0 +1,103 @@
+package graph
+
+import (
+	"context"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/assimoes/beautix/internal/infrastructure/telemetry"
+)
+
+// telemetryExtension traces the execution of GraphQL operations and the resolvers doing real
+// work: those of the root fields, and resolvers fetching objects. Fields merely read off their
+// parent, or computed into a scalar, would only add noise.
+type telemetryExtension struct{}
+
+// operationNameKey keeps the name of the operation executed for its span
+type operationNameKey struct{}
+
+// Init keeps the name of the operation for its span, which starts with the execution
+func (telemetryExtension) Init(ctx context.Context, params *graphql.Params) context.Context {
+	return context.WithValue(ctx, operationNameKey{}, params.OperationName)
+}
+
+// Name returns the name of the extension
+func (telemetryExtension) Name() string { return "telemetry" }
+
+// ParseDidStart does nothing; parsing is part of the request's span
+func (telemetryExtension) ParseDidStart(ctx context.Context) (context.Context, graphql.ParseFinishFunc) {
+	return ctx, func(error) {}
+}
+
+// ValidationDidStart does nothing; validation is part of the request's span
+func (telemetryExtension) ValidationDidStart(ctx context.Context) (context.Context, graphql.ValidationFinishFunc) {
+	return ctx, func([]gqlerrors.FormattedError) {}
+}
+
+// ExecutionDidStart starts the span of the operation, which the resolvers' spans are children of
+func (telemetryExtension) ExecutionDidStart(ctx context.Context) (context.Context, graphql.ExecutionFinishFunc) {
+	operationName, _ := ctx.Value(operationNameKey{}).(string)
+	ctx, span := telemetry.Tracer().Start(ctx, "graphql.execute", trace.WithAttributes(
+		attribute.String("graphql.operation.name", operationName),
+	))
+	return ctx, func(result *graphql.Result) {
+		if result != nil && result.HasErrors() {
+			span.SetStatus(codes.Error, result.Errors[0].Message)
+		}
+		span.End()
+	}
+}
+
+// ResolveFieldDidStart starts the span of a resolver and times it. The context is returned
+// unchanged: graphql-go keeps it for the fields resolved next, which would otherwise become
+// children of this one.
+func (telemetryExtension) ResolveFieldDidStart(ctx context.Context, info *graphql.ResolveInfo) (context.Context, graphql.ResolveFieldFinishFunc) {
+	if !tracedField(info) {
+		return ctx, func(any, error) {}
+	}
+	parentType := info.ParentType.Name()
+	start := time.Now()
+	_, span := telemetry.Tracer().Start(ctx, "graphql.resolve "+parentType+"."+info.FieldName, trace.WithAttributes(
+		attribute.String("graphql.type", parentType),
+		attribute.String("graphql.field", info.FieldName),
+	))
+	return ctx, func(_ any, err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+		telemetry.ObserveResolver(parentType, info.FieldName, time.Since(start), err != nil)
+	}
+}
+
+// HasResult reports that the extension adds nothing to responses
+func (telemetryExtension) HasResult() bool { return false }
+
+// GetResult returns nothing; the extension adds nothing to responses
+func (telemetryExtension) GetResult(context.Context) any { return nil }
+
+// tracedField reports whether a field is a root field or has a resolver returning objects
+func tracedField(info *graphql.ResolveInfo) bool {
+	if info.Path == nil || info.Path.Prev == nil {
+		return true
+	}
+	parent, ok := info.ParentType.(*graphql.Object)
+	if !ok {
+		return false
+	}
+	field, ok := parent.Fields()[info.FieldName]
+	if !ok || field.Resolve == nil {
+		return false
+	}
+	switch graphql.GetNamed(info.ReturnType).(type) {
+	case *graphql.Scalar, *graphql.Enum:
+		return false
+	}
+	return true
+}
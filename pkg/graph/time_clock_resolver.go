@@ -0,0 +1,121 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// payPeriodFromArgs reads the pay period arguments of time entry queries
+func payPeriodFromArgs(args map[string]any) (dto.TimesheetQueryDTO, error) {
+	businessID, ok := args["businessId"].(string)
+	if !ok {
+		return dto.TimesheetQueryDTO{}, errors.New("businessId is required")
+	}
+	startDate, ok := args["startDate"].(string)
+	if !ok {
+		return dto.TimesheetQueryDTO{}, errors.New("startDate is required")
+	}
+	endDate, ok := args["endDate"].(string)
+	if !ok {
+		return dto.TimesheetQueryDTO{}, errors.New("endDate is required")
+	}
+	return dto.TimesheetQueryDTO{
+		BusinessID: businessID,
+		StartDate:  startDate,
+		EndDate:    endDate,
+		StaffID:    optionalString(args, "staffId"),
+	}, nil
+}
+
+// Time Clock Query Resolvers
+func (r *Resolver) resolveCurrentTimeEntry(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+
+	entry, err := r.timeClockService.GetCurrentTimeEntry(p.Context, businessID)
+	if err != nil || entry == nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+func (r *Resolver) resolveTimeEntries(p graphql.ResolveParams) (any, error) {
+	query, err := payPeriodFromArgs(p.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := r.timeClockService.GetTimeEntries(p.Context, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (r *Resolver) resolveTimesheet(p graphql.ResolveParams) (any, error) {
+	query, err := payPeriodFromArgs(p.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	timesheet, err := r.timeClockService.GetTimesheet(p.Context, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return timesheet, nil
+}
+
+// Time Clock Mutation Resolvers
+func (r *Resolver) resolveClockIn(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+
+	entry, err := r.timeClockService.ClockIn(p.Context, dto.ClockDTO{BusinessID: businessID, Notes: optionalString(p.Args, "notes")})
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+func (r *Resolver) resolveClockOut(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+
+	entry, err := r.timeClockService.ClockOut(p.Context, dto.ClockDTO{BusinessID: businessID, Notes: optionalString(p.Args, "notes")})
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+func (r *Resolver) resolveAdjustTimeEntry(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+	adjustDTO := dto.AdjustTimeEntryDTO{}
+	if err := decodeInput(p.Args, &adjustDTO); err != nil {
+		return nil, err
+	}
+
+	entry, err := r.timeClockService.AdjustTimeEntry(p.Context, id, adjustDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
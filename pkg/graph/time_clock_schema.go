@@ -0,0 +1,252 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// TimeEntryFlagEnum represents the GraphQL enum for the flags of time entries
+var TimeEntryFlagEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "TimeEntryFlag",
+	Description: "Why a time entry should be looked at before it is paid",
+	Values: graphql.EnumValueConfigMap{
+		"OUTSIDE_SHIFT": &graphql.EnumValueConfig{
+			Value:       domain.TimeEntryOutsideShift,
+			Description: "Clocked in before or out after the staff member's shifts",
+		},
+		"NO_SHIFT": &graphql.EnumValueConfig{
+			Value:       domain.TimeEntryNoShift,
+			Description: "Clocked in on a day the staff member has no shift",
+		},
+		"OVERLONG": &graphql.EnumValueConfig{
+			Value:       domain.TimeEntryOverlong,
+			Description: "Unusually long, usually a forgotten clock-out",
+		},
+	},
+})
+
+// TimeEntryType represents the GraphQL TimeEntry type
+var TimeEntryType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "TimeEntry",
+	Description: "A period a staff member was clocked in",
+	Fields: withBaseFields("time entry", graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"staffId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the staff member",
+		},
+		"staff": staffRelation(func(e *dto.TimeEntryResponseDTO) string { return e.StaffID }),
+		"clockIn": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the staff member clocked in",
+		},
+		"clockOut": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the staff member clocked out; null while they are clocked in",
+		},
+		"minutes": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The time clocked in, 0 while the entry is open",
+		},
+		"flags": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(TimeEntryFlagEnum))),
+			Description: "Why the entry should be looked at before it is paid; empty when it matches the staff member's shifts",
+		},
+		"notes": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Notes on the entry",
+		},
+		"adjustedBy": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The ID of the user who last corrected the times",
+		},
+	}),
+})
+
+// TimesheetLineType represents the GraphQL TimesheetLine type
+var TimesheetLineType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "TimesheetLine",
+	Description: "The time a staff member worked during a pay period",
+	Fields: graphql.Fields{
+		"staffId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the staff member",
+		},
+		"staffName": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The full name of the staff member",
+		},
+		"minutes": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The time worked within the period by the closed entries",
+		},
+		"entries": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The entries overlapping the period",
+		},
+		"flagged": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The entries that should be looked at before they are paid",
+		},
+		"open": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The entries still clocked in, not counted in minutes",
+		},
+	},
+})
+
+// TimesheetType represents the GraphQL Timesheet type
+var TimesheetType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "Timesheet",
+	Description: "The time the staff of a business worked during a pay period",
+	Fields: graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"periodStart": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The first day of the period, as YYYY-MM-DD",
+		},
+		"periodEnd": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The last day of the period, as YYYY-MM-DD",
+		},
+		"lines": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(TimesheetLineType))),
+			Description: "The time worked by each staff member",
+		},
+		"csv": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The lines as a CSV file for payroll software, with hours in decimal",
+		},
+	},
+})
+
+// AdjustTimeEntryInput represents the GraphQL input for correcting a time entry
+var AdjustTimeEntryInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "AdjustTimeEntryInput",
+	Description: "Input for correcting a time entry; absent fields are left as they are",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"clockIn": &graphql.InputObjectFieldConfig{
+			Type:        graphql.DateTime,
+			Description: "When the staff member clocked in",
+		},
+		"clockOut": &graphql.InputObjectFieldConfig{
+			Type:        graphql.DateTime,
+			Description: "When the staff member clocked out, closing an entry they forgot to",
+		},
+		"notes": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "Notes on the entry",
+		},
+		"expectedVersion": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Int,
+			Description: "The version of the entry the change is based on; fails with VERSION_CONFLICT when someone else changed it since",
+		},
+		"clear": clearField("AdjustTimeEntryInput", "notes"),
+	},
+})
+
+// payPeriodArgs returns the arguments selecting the time entries of a pay period
+func payPeriodArgs() graphql.FieldConfigArgument {
+	return graphql.FieldConfigArgument{
+		"businessId": &graphql.ArgumentConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"startDate": &graphql.ArgumentConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The first day of the period, as YYYY-MM-DD",
+		},
+		"endDate": &graphql.ArgumentConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The last day of the period, as YYYY-MM-DD; at most 31 days after the first",
+		},
+		"staffId": &graphql.ArgumentConfig{
+			Type:        graphql.String,
+			Description: "Only this staff member",
+		},
+	}
+}
+
+// clockArgs returns the arguments of clocking in or out
+func clockArgs() graphql.FieldConfigArgument {
+	return graphql.FieldConfigArgument{
+		"businessId": &graphql.ArgumentConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"notes": &graphql.ArgumentConfig{
+			Type:        graphql.String,
+			Description: "Notes on the entry",
+		},
+	}
+}
+
+// timeClockQueryFields returns the time clock queries
+func timeClockQueryFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"currentTimeEntry": &graphql.Field{
+			Type:        TimeEntryType,
+			Description: "Get the entry the caller is clocked in on at a business; null when clocked out",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+			},
+			Resolve: resolver.resolveCurrentTimeEntry,
+		},
+		"timeEntries": &graphql.Field{
+			Type:        graphql.NewList(TimeEntryType),
+			Description: "Get the time entries overlapping a period, by clock-in time (owners and managers see everyone's, other staff their own)",
+			Args:        payPeriodArgs(),
+			Resolve:     resolver.resolveTimeEntries,
+		},
+		"timesheet": &graphql.Field{
+			Type:        TimesheetType,
+			Description: "Get the time each staff member worked during a pay period, with a CSV file for payroll (owners and managers)",
+			Args:        payPeriodArgs(),
+			Resolve:     resolver.resolveTimesheet,
+		},
+	}
+}
+
+// timeClockMutationFields returns the time clock mutations
+func timeClockMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"clockIn": &graphql.Field{
+			Type:        TimeEntryType,
+			Description: "Clock the caller in at a business",
+			Args:        clockArgs(),
+			Resolve:     resolver.resolveClockIn,
+		},
+		"clockOut": &graphql.Field{
+			Type:        TimeEntryType,
+			Description: "Clock the caller out at a business",
+			Args:        clockArgs(),
+			Resolve:     resolver.resolveClockOut,
+		},
+		"adjustTimeEntry": &graphql.Field{
+			Type:        TimeEntryType,
+			Description: "Correct the times or notes of a time entry (owners and managers)",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the time entry",
+				},
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(AdjustTimeEntryInput),
+					Description: "The corrections",
+				},
+			},
+			Resolve: resolver.resolveAdjustTimeEntry,
+		},
+	}
+}
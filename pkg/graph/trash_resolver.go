@@ -0,0 +1,45 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Trash Query Resolvers
+func (r *Resolver) resolveTrash(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+	entityType, ok := p.Args["entityType"].(string)
+	if !ok {
+		return nil, errors.New("entityType is required")
+	}
+	filter := dto.TrashFilterDTO{BusinessID: businessID, EntityType: entityType}
+	page, pageSize := pageFromArgs(p.Args, 20)
+
+	items, _, err := r.trashService.ListTrash(p.Context, filter, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// Trash Mutation Resolvers
+func (r *Resolver) resolveRestoreDeleted(p graphql.ResolveParams) (any, error) {
+	var restoreDTO dto.RestoreDTO
+	if err := decodeInput(p.Args, &restoreDTO); err != nil {
+		return nil, err
+	}
+
+	restored, err := r.trashService.Restore(p.Context, restoreDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return restored, nil
+}
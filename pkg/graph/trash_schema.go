@@ -0,0 +1,120 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+)
+
+// TrashEntityTypeEnum represents the GraphQL TrashEntityType enum
+var TrashEntityTypeEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "TrashEntityType",
+	Description: "A kind of record that can be restored after being deleted",
+	Values: graphql.EnumValueConfigMap{
+		"CLIENT": &graphql.EnumValueConfig{
+			Value:       "client",
+			Description: "A client",
+		},
+		"SERVICE": &graphql.EnumValueConfig{
+			Value:       "service",
+			Description: "A service of the menu",
+		},
+		"APPOINTMENT": &graphql.EnumValueConfig{
+			Value:       "appointment",
+			Description: "An appointment",
+		},
+	},
+})
+
+// TrashItemType represents the GraphQL TrashItem type
+var TrashItemType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "TrashItem",
+	Description: "A deleted record that can still be restored",
+	Fields: graphql.Fields{
+		"id": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the record",
+		},
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"entityType": &graphql.Field{
+			Type:        graphql.NewNonNull(TrashEntityTypeEnum),
+			Description: "The kind of record",
+		},
+		"label": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "What the record is, e.g. the client's name",
+		},
+		"deletedAt": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the record was deleted",
+		},
+		"deletedBy": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The ID of the user who deleted the record, when known",
+		},
+		"restorableUntil": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the record leaves the trash and can no longer be restored",
+		},
+	},
+})
+
+// RestoreInput represents the GraphQL input for restoring a deleted record
+var RestoreInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "RestoreInput",
+	Description: "The deleted record to restore",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"businessId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"entityType": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(TrashEntityTypeEnum),
+			Description: "The kind of record",
+		},
+		"id": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the record",
+		},
+	},
+})
+
+// trashQueryFields returns the trash queries
+func trashQueryFields(resolver *Resolver) graphql.Fields {
+	listArgs := paginationArgs("records", 20)
+	listArgs["businessId"] = &graphql.ArgumentConfig{
+		Type:        graphql.NewNonNull(graphql.String),
+		Description: "The ID of the business",
+	}
+	listArgs["entityType"] = &graphql.ArgumentConfig{
+		Type:        graphql.NewNonNull(TrashEntityTypeEnum),
+		Description: "The kind of records to list",
+	}
+
+	return graphql.Fields{
+		"trash": &graphql.Field{
+			Type:        graphql.NewList(TrashItemType),
+			Description: "List the records of a kind deleted in the last 30 days, most recently deleted first (business owners only)",
+			Args:        listArgs,
+			Resolve:     resolver.resolveTrash,
+		},
+	}
+}
+
+// trashMutationFields returns the trash mutations
+func trashMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"restoreDeleted": &graphql.Field{
+			Type:        TrashItemType,
+			Description: "Restore a client, service or appointment deleted in the last 30 days (business owners only)",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(RestoreInput),
+					Description: "The record to restore",
+				},
+			},
+			Resolve: resolver.resolveRestoreDeleted,
+		},
+	}
+}
@@ -0,0 +1,22 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+)
+
+// Trial Query Resolvers
+func (r *Resolver) resolveBusinessTrialStatus(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+
+	status, err := r.trialService.GetTrialStatus(p.Context, businessID)
+	if err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
@@ -0,0 +1,84 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// TrialStateEnum represents the GraphQL enum for where a business is in its free trial
+var TrialStateEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "TrialState",
+	Description: "Where a business is in a free trial of a paid tier",
+	Values: graphql.EnumValueConfigMap{
+		"NONE": &graphql.EnumValueConfig{
+			Value:       domain.TrialNone,
+			Description: "Not on a trial, or subscribed after it",
+		},
+		"ACTIVE": &graphql.EnumValueConfig{
+			Value:       domain.TrialActive,
+			Description: "The trial has not ended",
+		},
+		"GRACE": &graphql.EnumValueConfig{
+			Value:       domain.TrialGrace,
+			Description: "The trial ended; the business keeps its tier until the grace period ends",
+		},
+		"EXPIRED": &graphql.EnumValueConfig{
+			Value:       domain.TrialExpired,
+			Description: "The grace period passed; the business is restricted to the free tier until it subscribes",
+		},
+	},
+})
+
+// TrialStatusType represents the GraphQL TrialStatus type
+var TrialStatusType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "TrialStatus",
+	Description: "Where a business is in its free trial, for the banners of the app",
+	Fields: graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"state": &graphql.Field{
+			Type:        graphql.NewNonNull(TrialStateEnum),
+			Description: "Where the business is in its trial",
+		},
+		"tier": &graphql.Field{
+			Type:        graphql.NewNonNull(SubscriptionTierEnum),
+			Description: "The tier the business has now",
+		},
+		"trialEndsAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the trial ends or ended",
+		},
+		"graceEndsAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the business is restricted to the free tier unless it subscribes",
+		},
+		"daysLeft": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "Whole days left of the trial, or of the grace period while in it",
+		},
+		"expiredAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the business was restricted",
+		},
+	},
+})
+
+// trialQueryFields returns the trial queries
+func trialQueryFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"businessTrialStatus": &graphql.Field{
+			Type:        TrialStatusType,
+			Description: "Get where a business is in its free trial (staff of the business only)",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+			},
+			Resolve: resolver.resolveBusinessTrialStatus,
+		},
+	}
+}
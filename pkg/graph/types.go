@@ -3,6 +3,7 @@ package graph
 import (
 	"github.com/graphql-go/graphql"
 	
+	"github.com/assimoes/beautix/internal/domain"
 	"github.com/assimoes/beautix/internal/dto"
 )
 
@@ -91,6 +92,16 @@ var UserType = graphql.NewObject(graphql.ObjectConfig{
 				return nil, nil
 			},
 		},
+		"locale": &graphql.Field{
+			Type:        graphql.String,
+			Description: "The locale the user reads the app in, en or pt; null follows the browser's language",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				if user, ok := p.Source.(*dto.UserResponseDTO); ok {
+					return user.Locale, nil
+				}
+				return nil, nil
+			},
+		},
 		"createdAt": &graphql.Field{
 			Type:        graphql.NewNonNull(graphql.DateTime),
 			Description: "When the user was created",
@@ -114,6 +125,9 @@ var UserType = graphql.NewObject(graphql.ObjectConfig{
 	},
 })
 
+// UserConnectionType represents a cursor-paginated page of users
+var UserConnectionType = connectionType(UserType)
+
 // BusinessRoleEnum represents the GraphQL BusinessRole enum for staff roles within businesses
 var BusinessRoleEnum = graphql.NewEnum(graphql.EnumConfig{
 	Name:        "BusinessRole",
@@ -191,6 +205,34 @@ var BusinessType = graphql.NewObject(graphql.ObjectConfig{
 			Type:        graphql.NewNonNull(graphql.String),
 			Description: "The timezone of the business",
 		},
+		"dataRegion": &graphql.Field{
+			Type:        graphql.NewNonNull(DataRegionEnum),
+			Description: "The region the business's data must stay in",
+		},
+		"mode": &graphql.Field{
+			Type:        graphql.NewNonNull(BusinessModeEnum),
+			Description: "The way the business is run",
+		},
+		"capabilities": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(CapabilityEnum))),
+			Description: "The parts of the app the business uses; clients hide the others",
+		},
+		"isSandbox": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether the business is a demo tenant whose data can be reset",
+		},
+		"isTemplate": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether the business is a template new businesses are cloned from, which clients cannot book",
+		},
+		"averageRating": &graphql.Field{
+			Type:        DecimalScalar,
+			Description: "The average of the business's published reviews, 1 to 5; null without any",
+		},
+		"reviewCount": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The number of the business's published reviews",
+		},
 		"isActive": &graphql.Field{
 			Type:        graphql.NewNonNull(graphql.Boolean),
 			Description: "Whether the business is active",
@@ -263,6 +305,15 @@ var UpdateUserInput = graphql.NewInputObject(graphql.InputObjectConfig{
 			Type:        graphql.Boolean,
 			Description: "Whether the user is active",
 		},
+		"locale": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The locale the user reads the app in, en or pt; error messages are written in it",
+		},
+		"expectedVersion": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Int,
+			Description: "The version of the user the change is based on; fails with VERSION_CONFLICT when someone else changed it since",
+		},
+		"clear": clearField("UpdateUserInput", "phone", "locale"),
 	},
 })
 
@@ -299,6 +350,10 @@ var CreateBusinessInput = graphql.NewInputObject(graphql.InputObjectConfig{
 			Type:        graphql.NewNonNull(graphql.String),
 			Description: "The timezone of the business",
 		},
+		"mode": &graphql.InputObjectFieldConfig{
+			Type:        BusinessModeEnum,
+			Description: "The way the business is run; TEAM when omitted",
+		},
 	},
 })
 
@@ -348,4 +403,57 @@ var UpdateBusinessInput = graphql.NewInputObject(graphql.InputObjectConfig{
 			Description: "Whether the business is active",
 		},
 	},
-})
\ No newline at end of file
+})
+// DeleteResultType represents the GraphQL result of a delete mutation
+var DeleteResultType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "DeleteResult",
+	Description: "The result of a delete operation",
+	Fields: graphql.Fields{
+		"success": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.Boolean),
+		},
+		"message": &graphql.Field{
+			Type: graphql.String,
+		},
+	},
+})
+
+// AppointmentStatusEnum represents the GraphQL AppointmentStatus enum
+var AppointmentStatusEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "AppointmentStatus",
+	Description: "The status of an appointment",
+	Values: graphql.EnumValueConfigMap{
+		"PENDING": &graphql.EnumValueConfig{
+			Value:       domain.AppointmentStatusPending,
+			Description: "Requested online, awaiting acceptance by the business",
+		},
+		"SCHEDULED": &graphql.EnumValueConfig{
+			Value:       domain.AppointmentStatusScheduled,
+			Description: "Booked but not yet confirmed",
+		},
+		"CONFIRMED": &graphql.EnumValueConfig{
+			Value:       domain.AppointmentStatusConfirmed,
+			Description: "Confirmed by the client",
+		},
+		"IN_PROGRESS": &graphql.EnumValueConfig{
+			Value:       domain.AppointmentStatusInProgress,
+			Description: "The client is being served",
+		},
+		"COMPLETED": &graphql.EnumValueConfig{
+			Value:       domain.AppointmentStatusCompleted,
+			Description: "The service was delivered",
+		},
+		"CANCELLED": &graphql.EnumValueConfig{
+			Value:       domain.AppointmentStatusCancelled,
+			Description: "Cancelled by the client or the business",
+		},
+		"NO_SHOW": &graphql.EnumValueConfig{
+			Value:       domain.AppointmentStatusNoShow,
+			Description: "The client did not show up",
+		},
+		"RESCHEDULED": &graphql.EnumValueConfig{
+			Value:       domain.AppointmentStatusRescheduled,
+			Description: "Moved to another time",
+		},
+	},
+})
@@ -0,0 +1,15 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+)
+
+// Watchdog Mutation Resolvers
+func (r *Resolver) resolveRunWatchdog(p graphql.ResolveParams) (any, error) {
+	report, err := r.watchdogService.RunWatchdog(p.Context)
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
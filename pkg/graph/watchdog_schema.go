@@ -0,0 +1,140 @@
+package graph
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// WatchdogCheckEnum represents the GraphQL enum for the kinds of background work the watchdog checks
+var WatchdogCheckEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "WatchdogCheck",
+	Description: "A kind of background work the watchdog looks for stuck items of",
+	Values: graphql.EnumValueConfigMap{
+		"EVENT_LEASES": &graphql.EnumValueConfig{
+			Value:       domain.WatchdogEventLeases,
+			Description: "Outbox events leased further ahead than a relay ever leases them; requeued",
+		},
+		"EVENT_BACKLOG": &graphql.EnumValueConfig{
+			Value:       domain.WatchdogEventBacklog,
+			Description: "Outbox events overdue for publishing",
+		},
+		"NOTIFICATION_LEASES": &graphql.EnumValueConfig{
+			Value:       domain.WatchdogNotificationLeases,
+			Description: "Queued notifications scheduled further ahead than any retry; requeued",
+		},
+		"NOTIFICATION_BACKLOG": &graphql.EnumValueConfig{
+			Value:       domain.WatchdogNotificationBacklog,
+			Description: "Queued notifications overdue for sending",
+		},
+		"WEBHOOK_LEASES": &graphql.EnumValueConfig{
+			Value:       domain.WatchdogWebhookLeases,
+			Description: "Webhook deliveries scheduled further ahead than any retry; requeued",
+		},
+		"WEBHOOK_BACKLOG": &graphql.EnumValueConfig{
+			Value:       domain.WatchdogWebhookBacklog,
+			Description: "Webhook deliveries overdue",
+		},
+		"STALLED_BROADCASTS": &graphql.EnumValueConfig{
+			Value:       domain.WatchdogStalledBroadcasts,
+			Description: "Broadcasts that stopped sending part way",
+		},
+		"OPEN_PAYMENTS": &graphql.EnumValueConfig{
+			Value:       domain.WatchdogOpenPayments,
+			Description: "Card payments neither taken nor failed",
+		},
+		"UNANSWERED_REQUESTS": &graphql.EnumValueConfig{
+			Value:       domain.WatchdogUnansweredRequests,
+			Description: "Online booking requests still holding their slot once it started; expired",
+		},
+	},
+})
+
+// WatchdogActionEnum represents the GraphQL enum for what the watchdog did about stuck items
+var WatchdogActionEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "WatchdogAction",
+	Description: "What the watchdog did about the stuck items a check found",
+	Values: graphql.EnumValueConfigMap{
+		"NONE": &graphql.EnumValueConfig{
+			Value:       domain.WatchdogActionNone,
+			Description: "Nothing was stuck",
+		},
+		"REQUEUED": &graphql.EnumValueConfig{
+			Value:       domain.WatchdogActionRequeued,
+			Description: "Made due again, for their worker to pick up",
+		},
+		"EXPIRED": &graphql.EnumValueConfig{
+			Value:       domain.WatchdogActionExpired,
+			Description: "Cancelled, releasing what they held",
+		},
+		"ALERTED": &graphql.EnumValueConfig{
+			Value:       domain.WatchdogActionAlerted,
+			Description: "Left for an operator",
+		},
+	},
+})
+
+// WatchdogFindingType represents the GraphQL WatchdogFinding type
+var WatchdogFindingType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "WatchdogFinding",
+	Description: "The outcome of a watchdog check",
+	Fields: graphql.Fields{
+		"check": &graphql.Field{
+			Type:        graphql.NewNonNull(WatchdogCheckEnum),
+			Description: "The check",
+		},
+		"count": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "How many stuck items were found",
+		},
+		"remediated": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "How many of them the watchdog fixed",
+		},
+		"oldestAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the oldest of them was due or created",
+		},
+		"action": &graphql.Field{
+			Type:        graphql.NewNonNull(WatchdogActionEnum),
+			Description: "What the watchdog did about them",
+		},
+		"error": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Why the check or its remediation failed",
+		},
+		"needsOperator": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether an operator has to look into the finding",
+		},
+	},
+})
+
+// WatchdogReportType represents the GraphQL WatchdogReport type
+var WatchdogReportType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "WatchdogReport",
+	Description: "The outcome of a watchdog run",
+	Fields: graphql.Fields{
+		"ranAt": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the watchdog ran",
+		},
+		"findings": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(WatchdogFindingType))),
+			Description: "The outcome of every check",
+		},
+	},
+})
+
+// watchdogMutationFields returns the watchdog mutations
+func watchdogMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"runWatchdog": &graphql.Field{
+			Type: WatchdogReportType,
+			Description: "Look for stuck background work now: requeue leases no worker will release, expire booking requests " +
+				"never accepted before their start, and report the rest; the watchdog also runs every five minutes and " +
+				"alerts operators (platform admin only)",
+			Resolve: resolver.resolveRunWatchdog,
+		},
+	}
+}
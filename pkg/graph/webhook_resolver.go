@@ -0,0 +1,89 @@
+package graph
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/dto"
+)
+
+// Webhook Query Resolvers
+func (r *Resolver) resolveWebhookSubscriptions(p graphql.ResolveParams) (any, error) {
+	businessID, ok := p.Args["businessId"].(string)
+	if !ok {
+		return nil, errors.New("businessId is required")
+	}
+
+	subscriptions, err := r.webhookService.ListWebhookSubscriptions(p.Context, businessID)
+	if err != nil {
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+func (r *Resolver) resolveWebhookDeliveries(p graphql.ResolveParams) (any, error) {
+	subscriptionID, ok := p.Args["subscriptionId"].(string)
+	if !ok {
+		return nil, errors.New("subscriptionId is required")
+	}
+	page, pageSize := pageFromArgs(p.Args, 50)
+
+	deliveries, _, err := r.webhookService.ListWebhookDeliveries(p.Context, subscriptionID, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// Webhook Mutation Resolvers
+func (r *Resolver) resolveCreateWebhookSubscription(p graphql.ResolveParams) (any, error) {
+	createDTO := dto.CreateWebhookSubscriptionDTO{}
+	if err := decodeInput(p.Args, &createDTO); err != nil {
+		return nil, err
+	}
+
+	subscription, err := r.webhookService.CreateWebhookSubscription(p.Context, createDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+func (r *Resolver) resolveUpdateWebhookSubscription(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+
+	updateDTO := dto.UpdateWebhookSubscriptionDTO{}
+	if err := decodeInput(p.Args, &updateDTO); err != nil {
+		return nil, err
+	}
+
+	subscription, err := r.webhookService.UpdateWebhookSubscription(p.Context, id, updateDTO)
+	if err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+func (r *Resolver) resolveDeleteWebhookSubscription(p graphql.ResolveParams) (any, error) {
+	id, ok := p.Args["id"].(string)
+	if !ok {
+		return nil, errors.New("id is required")
+	}
+
+	if err := r.webhookService.DeleteWebhookSubscription(p.Context, id); err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		"success": true,
+		"message": "Webhook subscription deleted successfully",
+	}, nil
+}
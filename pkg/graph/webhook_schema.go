@@ -0,0 +1,246 @@
+package graph
+
+import (
+	"strings"
+
+	"github.com/graphql-go/graphql"
+
+	"github.com/assimoes/beautix/internal/domain"
+)
+
+// webhookEventTypesDescription lists the event types a subscription can receive
+var webhookEventTypesDescription = "The event types to deliver: " + strings.Join(domain.WebhookEventTypes, ", ")
+
+// WebhookDeliveryStatusEnum represents the GraphQL enum for webhook delivery states
+var WebhookDeliveryStatusEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "WebhookDeliveryStatus",
+	Description: "The state of a webhook delivery",
+	Values: graphql.EnumValueConfigMap{
+		"PENDING": &graphql.EnumValueConfig{
+			Value:       domain.WebhookDeliveryPending,
+			Description: "Waiting for its first attempt or a retry",
+		},
+		"DELIVERED": &graphql.EnumValueConfig{
+			Value:       domain.WebhookDeliveryDelivered,
+			Description: "Acknowledged by the endpoint with a 2xx response",
+		},
+		"FAILED": &graphql.EnumValueConfig{
+			Value:       domain.WebhookDeliveryFailed,
+			Description: "Every attempt failed, or the subscription was deleted or deactivated",
+		},
+	},
+})
+
+// WebhookSubscriptionType represents the GraphQL WebhookSubscription type
+var WebhookSubscriptionType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "WebhookSubscription",
+	Description: "An endpoint of a third-party integration receiving events of a business as signed JSON requests",
+	Fields: withBaseFields("webhook subscription", graphql.Fields{
+		"businessId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"url": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The https URL events are posted to",
+		},
+		"eventTypes": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+			Description: "The event types delivered",
+		},
+		"description": &graphql.Field{
+			Type:        graphql.String,
+			Description: "What the endpoint is for",
+		},
+		"isActive": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Description: "Whether events are delivered",
+		},
+		"secret": &graphql.Field{
+			Type: graphql.String,
+			Description: "The secret deliveries are signed with, returned only when the subscription is created or its secret " +
+				"rotated. The " + domain.WebhookSignatureHeader + " header holds t=<unix time>,v1=<hex HMAC-SHA256 of \"<unix time>.<body>\">",
+		},
+	}),
+})
+
+// WebhookDeliveryType represents the GraphQL WebhookDelivery type
+var WebhookDeliveryType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "WebhookDelivery",
+	Description: "An entry of a webhook subscription's delivery log",
+	Fields: graphql.Fields{
+		"id": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the delivery, sent in the " + domain.WebhookDeliveryHeader + " header",
+		},
+		"eventId": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the event, the id of the payload",
+		},
+		"eventType": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The event type delivered",
+		},
+		"status": &graphql.Field{
+			Type:        graphql.NewNonNull(WebhookDeliveryStatusEnum),
+			Description: "The state of the delivery",
+		},
+		"attempts": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "How many times the delivery was attempted",
+		},
+		"nextAttemptAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the delivery is attempted next, while pending",
+		},
+		"lastAttemptAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the delivery was last attempted",
+		},
+		"statusCode": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "The status of the endpoint's last response",
+		},
+		"lastError": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Why the last attempt failed",
+		},
+		"durationMs": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "How long the last attempt took in milliseconds",
+		},
+		"deliveredAt": &graphql.Field{
+			Type:        graphql.DateTime,
+			Description: "When the endpoint acknowledged the delivery",
+		},
+		"createdAt": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.DateTime),
+			Description: "When the event was queued for delivery",
+		},
+	},
+})
+
+// CreateWebhookSubscriptionInput represents the input for subscribing an endpoint to events
+var CreateWebhookSubscriptionInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "CreateWebhookSubscriptionInput",
+	Description: "Input for subscribing an endpoint to events of a business",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"businessId": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The ID of the business",
+		},
+		"url": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The https URL to post events to",
+		},
+		"eventTypes": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+			Description: webhookEventTypesDescription,
+		},
+		"description": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "What the endpoint is for",
+		},
+	},
+})
+
+// UpdateWebhookSubscriptionInput represents the input for changing a webhook subscription
+var UpdateWebhookSubscriptionInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "UpdateWebhookSubscriptionInput",
+	Description: "Input for changing a webhook subscription; omitted fields are left unchanged",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"url": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The https URL to post events to",
+		},
+		"eventTypes": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+			Description: webhookEventTypesDescription,
+		},
+		"description": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "What the endpoint is for",
+		},
+		"isActive": &graphql.InputObjectFieldConfig{
+			Type:        graphql.Boolean,
+			Description: "Whether events are delivered",
+		},
+		"rotateSecret": &graphql.InputObjectFieldConfig{
+			Type:         graphql.Boolean,
+			Description:  "Replace the signing secret, returning the new one",
+			DefaultValue: false,
+		},
+	},
+})
+
+// webhookQueryFields returns the webhook queries
+func webhookQueryFields(resolver *Resolver) graphql.Fields {
+	deliveryArgs := paginationArgs("deliveries", 50)
+	deliveryArgs["subscriptionId"] = &graphql.ArgumentConfig{
+		Type:        graphql.NewNonNull(graphql.String),
+		Description: "The ID of the webhook subscription",
+	}
+
+	return graphql.Fields{
+		"webhookSubscriptions": &graphql.Field{
+			Type:        graphql.NewList(WebhookSubscriptionType),
+			Description: "List the webhook subscriptions of a business (owners and managers only)",
+			Args: graphql.FieldConfigArgument{
+				"businessId": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the business",
+				},
+			},
+			Resolve: resolver.resolveWebhookSubscriptions,
+		},
+		"webhookDeliveries": &graphql.Field{
+			Type:        graphql.NewList(WebhookDeliveryType),
+			Description: "Get the delivery log of a webhook subscription, latest first (owners and managers only)",
+			Args:        deliveryArgs,
+			Resolve:     resolver.resolveWebhookDeliveries,
+		},
+	}
+}
+
+// webhookMutationFields returns the webhook mutations
+func webhookMutationFields(resolver *Resolver) graphql.Fields {
+	return graphql.Fields{
+		"createWebhookSubscription": &graphql.Field{
+			Type:        WebhookSubscriptionType,
+			Description: "Subscribe an endpoint to events of a business; the response carries the signing secret (owners and managers only)",
+			Args: graphql.FieldConfigArgument{
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(CreateWebhookSubscriptionInput),
+					Description: "The endpoint and event types",
+				},
+			},
+			Resolve: resolver.resolveCreateWebhookSubscription,
+		},
+		"updateWebhookSubscription": &graphql.Field{
+			Type:        WebhookSubscriptionType,
+			Description: "Change a webhook subscription or rotate its secret (owners and managers only)",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the webhook subscription",
+				},
+				"input": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(UpdateWebhookSubscriptionInput),
+					Description: "The changes",
+				},
+			},
+			Resolve: resolver.resolveUpdateWebhookSubscription,
+		},
+		"deleteWebhookSubscription": &graphql.Field{
+			Type:        DeleteResultType,
+			Description: "Delete a webhook subscription; its pending deliveries are not sent (owners and managers only)",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{
+					Type:        graphql.NewNonNull(graphql.String),
+					Description: "The ID of the webhook subscription",
+				},
+			},
+			Resolve: resolver.resolveDeleteWebhookSubscription,
+		},
+	}
+}
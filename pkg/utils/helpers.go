@@ -66,9 +66,9 @@ func IsValidUUID(u string) bool {
 	return err == nil
 }
 
-// GenerateUUID generates a new UUID string
+// GenerateUUID generates a new time-ordered UUIDv7 string
 func GenerateUUID() string {
-	return uuid.New().String()
+	return uuid.Must(uuid.NewV7()).String()
 }
 
 // IsValidEmail checks if a string is a valid email address
@@ -7,6 +7,7 @@ import (
 
 	"github.com/graphql-go/graphql"
 
+	{{if .GenerateService}}"github.com/assimoes/beautix/internal/domain"{{end}}
 	"github.com/assimoes/beautix/internal/dto"
 	{{if .GenerateService}}"github.com/assimoes/beautix/internal/service"{{end}}
 )
@@ -333,6 +334,14 @@ func (m *mock{{.EntityName}}Service) List(ctx context.Context, page, pageSize in
 		{{.EntityNameLower}}s = append({{.EntityNameLower}}s, {{.EntityNameLower}})
 	}
 	return {{.EntityNameLower}}s, int64(len({{.EntityNameLower}}s)), nil
+}
+
+func (m *mock{{.EntityName}}Service) ListAfter(ctx context.Context, after string, first int) (*domain.Connection[dto.{{.EntityName}}ResponseDTO], error) {
+	connection := &domain.Connection[dto.{{.EntityName}}ResponseDTO]{}
+	for _, {{.EntityNameLower}} := range m.{{.EntityNameLower}}s {
+		connection.Edges = append(connection.Edges, &domain.Edge[dto.{{.EntityName}}ResponseDTO]{Cursor: {{.EntityNameLower}}.ID, Node: {{.EntityNameLower}}})
+	}
+	return connection, nil
 }{{end}}
 
 type mockAuth{{.EntityName}}Service struct{}
@@ -592,6 +601,11 @@ func (m *Mock{{.EntityName}}Repository) List(ctx context.Context, page, pageSize
 	return args.Get(0).([]*domain.{{.EntityName}}), args.Get(1).(int64), args.Error(2)
 }
 
+func (m *Mock{{.EntityName}}Repository) ListAfter(ctx context.Context, cursor *domain.Cursor, limit int) ([]*domain.{{.EntityName}}, bool, error) {
+	args := m.Called(ctx, cursor, limit)
+	return args.Get(0).([]*domain.{{.EntityName}}), args.Bool(1), args.Error(2)
+}
+
 // TODO: Add mock methods for custom repository methods
 // Example:
 // func (m *Mock{{.EntityName}}Repository) FindByName(ctx context.Context, name string) (*domain.{{.EntityName}}, error) {
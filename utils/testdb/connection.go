@@ -8,6 +8,7 @@ import (
 
 	"github.com/assimoes/beautix/configs"
 	"github.com/assimoes/beautix/internal/infrastructure/database"
+	"github.com/assimoes/beautix/internal/repository"
 	"github.com/rs/zerolog/log"
 	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
@@ -51,6 +52,7 @@ func NewTestDB(t *testing.T) *TestDB {
 		Logger: logger.Default.LogMode(logger.Silent),
 	})
 	require.NoError(t, err, "Failed to connect to test database")
+	require.NoError(t, db.DB.Use(repository.NewIDGenerator()), "Failed to register the ID generator")
 
 	testDB := &TestDB{
 		DB:     db,
@@ -82,6 +84,10 @@ func NewTestDB(t *testing.T) *TestDB {
 	err = SetupTestSchema(testDB.DB)
 	require.NoError(t, err, "Failed to setup test schema")
 
+	// Bring the schema up to the latest migration
+	err = Migrate(testDB.DB)
+	require.NoError(t, err, "Failed to migrate test database")
+
 	return testDB
 }
 
@@ -2,10 +2,12 @@ package testdb
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/assimoes/beautix/internal/domain"
 	"github.com/assimoes/beautix/internal/infrastructure/database"
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 )
 
 // FixtureBuilder helps create test data
@@ -89,6 +91,70 @@ func (fb *FixtureBuilder) CreateStaff(businessID, userID string, overrides ...fu
 	return staff, nil
 }
 
+// CreateClient creates a test client of a business
+func (fb *FixtureBuilder) CreateClient(businessID string, overrides ...func(*domain.Client)) (*domain.Client, error) {
+	client := &domain.Client{
+		BusinessID: businessID,
+		FirstName:  "Test",
+		LastName:   "Client",
+		Email:      fmt.Sprintf("client-%s@example.com", uuid.New().String()[:8]),
+		IsActive:   true,
+	}
+
+	// Apply overrides
+	for _, override := range overrides {
+		override(client)
+	}
+
+	if err := fb.db.Create(client).Error; err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+
+	return client, nil
+}
+
+// CreateService creates a test service offered by a business
+func (fb *FixtureBuilder) CreateService(businessID string, overrides ...func(*domain.Service)) (*domain.Service, error) {
+	service := &domain.Service{
+		BusinessID: businessID,
+		Name:       fmt.Sprintf("Test Service %s", uuid.New().String()[:8]),
+		Duration:   30,
+		Price:      decimal.NewFromInt(25),
+		IsActive:   true,
+	}
+
+	// Apply overrides
+	for _, override := range overrides {
+		override(service)
+	}
+
+	if err := fb.db.Create(service).Error; err != nil {
+		return nil, fmt.Errorf("failed to create service: %w", err)
+	}
+
+	return service, nil
+}
+
+// CreateServiceRecord creates a completed service record of a client
+func (fb *FixtureBuilder) CreateServiceRecord(businessID, clientID string, overrides ...func(*domain.ServiceRecord)) (*domain.ServiceRecord, error) {
+	record := &domain.ServiceRecord{
+		BusinessID: businessID,
+		ClientID:   clientID,
+		RecordedAt: time.Now(),
+	}
+
+	// Apply overrides
+	for _, override := range overrides {
+		override(record)
+	}
+
+	if err := fb.db.Create(record).Error; err != nil {
+		return nil, fmt.Errorf("failed to create service record: %w", err)
+	}
+
+	return record, nil
+}
+
 // UserBuilder provides a fluent interface for creating users
 type UserBuilder struct {
 	user *domain.User
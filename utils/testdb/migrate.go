@@ -0,0 +1,107 @@
+package testdb
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/assimoes/beautix/internal/infrastructure/database"
+	"gorm.io/gorm"
+)
+
+// migration is an up migration file of the migrations directory
+type migration struct {
+	version uint64
+	path    string
+}
+
+// Migrate applies the pending up migrations to the test database, in version order. It keeps
+// its state in the same schema_migrations table as golang-migrate, so a database migrated with
+// `make migrate-up` is picked up where it was left, and it refuses to run on a dirty database.
+func Migrate(db *database.DB) error {
+	if err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (version bigint NOT NULL PRIMARY KEY, dirty boolean NOT NULL)`).Error; err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	var state struct {
+		Version uint64
+		Dirty   bool
+	}
+	err := db.Raw(`SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&state).Error
+	if err != nil {
+		return fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	if state.Dirty {
+		return fmt.Errorf("test database is dirty at version %d, fix it with migrate force", state.Version)
+	}
+
+	migrations, err := upMigrations()
+	if err != nil {
+		return err
+	}
+	for _, m := range migrations {
+		if m.version <= state.Version {
+			continue
+		}
+		if err := applyMigration(db, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyMigration runs a migration and records its version in one transaction
+func applyMigration(db *database.DB, m migration) error {
+	sql, err := os.ReadFile(m.path)
+	if err != nil {
+		return fmt.Errorf("failed to read migration %d: %w", m.version, err)
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(string(sql)).Error; err != nil {
+			return fmt.Errorf("failed to apply migration %s: %w", filepath.Base(m.path), err)
+		}
+		if err := tx.Exec(`DELETE FROM schema_migrations`).Error; err != nil {
+			return err
+		}
+		return tx.Exec(`INSERT INTO schema_migrations (version, dirty) VALUES (?, false)`, m.version).Error
+	})
+}
+
+// upMigrations lists the up migrations in version order
+func upMigrations() ([]migration, error) {
+	dir, err := migrationsDir()
+	if err != nil {
+		return nil, err
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.up.sql"))
+	if err != nil {
+		return nil, err
+	}
+
+	migrations := make([]migration, 0, len(paths))
+	for _, path := range paths {
+		prefix, _, _ := strings.Cut(filepath.Base(path), "_")
+		version, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration file name %s", filepath.Base(path))
+		}
+		migrations = append(migrations, migration{version: version, path: path})
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].version < migrations[j].version })
+	return migrations, nil
+}
+
+// migrationsDir locates the migrations directory at the root of the module
+func migrationsDir() (string, error) {
+	_, file, _, ok := runtime.Caller(0)
+	if !ok {
+		return "", errors.New("failed to locate the migrations directory")
+	}
+	return filepath.Join(filepath.Dir(file), "..", "..", "migrations"), nil
+}